@@ -0,0 +1,175 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package handshake negotiates the protocol capabilities of supervisor and ws-proxy. Whenever ws-proxy connects
+// to a supervisor it has not talked to before, it posts its capabilities to Path. Supervisor answers with its own
+// capabilities and both sides compute the same agreement using Negotiate. A feature is only used once both sides
+// announce it, which lets either of them roll out features without upgrading the other in lockstep.
+package handshake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	// Path is the well-known path supervisor serves the handshake on
+	Path = "/_supervisor/.well-known/handshake"
+
+	// Version is the protocol version this package speaks
+	Version = 1
+
+	// maxMessageSize limits the size of handshake messages we read
+	maxMessageSize = 64 * 1024
+)
+
+// ErrIncompatible is returned if two peers have no protocol version in common
+var ErrIncompatible = errors.New("no common protocol version")
+
+// Capabilities is what a peer supports
+type Capabilities struct {
+	// Version is the highest protocol version the peer speaks
+	Version int `json:"version"`
+	// MinVersion is the lowest protocol version the peer still speaks
+	MinVersion int `json:"minVersion,omitempty"`
+	// Compression are the content encodings the peer can handle on the supervisor API, in order of preference
+	Compression []string `json:"compression,omitempty"`
+	// KeepaliveInterval is the longest time the peer keeps idle connections open without a keepalive. Zero means forever.
+	KeepaliveInterval util.Duration `json:"keepaliveInterval,omitempty"`
+	// Tunnel is true if the peer can tunnel TCP connections to the workspace
+	Tunnel bool `json:"tunnel,omitempty"`
+}
+
+// Agreement is what two peers agreed on
+type Agreement struct {
+	// Version is the protocol version both peers speak
+	Version int `json:"version"`
+	// Compression is the content encoding of supervisor API responses, or empty if they are not compressed
+	Compression string `json:"compression,omitempty"`
+	// KeepaliveInterval is how often peers must send keepalives on idle connections. Zero means never.
+	KeepaliveInterval util.Duration `json:"keepaliveInterval,omitempty"`
+	// Tunnel is true if ws-proxy may tunnel TCP connections through supervisor
+	Tunnel bool `json:"tunnel,omitempty"`
+}
+
+// Baseline is the agreement with peers which predate the handshake: protocol version 0 without any capabilities
+var Baseline = Agreement{}
+
+// Response is supervisor's answer to a handshake
+type Response struct {
+	Capabilities Capabilities `json:"capabilities"`
+	Agreement    Agreement    `json:"agreement"`
+}
+
+// Negotiate computes the agreement between supervisor and ws-proxy. Supervisor's preference decides
+// the compression, as it is the one which compresses.
+func Negotiate(supervisor, proxy Capabilities) (Agreement, error) {
+	var res Agreement
+
+	res.Version = supervisor.Version
+	if proxy.Version < res.Version {
+		res.Version = proxy.Version
+	}
+	if res.Version < supervisor.MinVersion || res.Version < proxy.MinVersion {
+		return Baseline, fmt.Errorf("%w: supervisor speaks %d to %d, ws-proxy %d to %d", ErrIncompatible,
+			supervisor.MinVersion, supervisor.Version, proxy.MinVersion, proxy.Version)
+	}
+
+	for _, enc := range supervisor.Compression {
+		if contains(proxy.Compression, enc) {
+			res.Compression = enc
+			break
+		}
+	}
+
+	res.KeepaliveInterval = supervisor.KeepaliveInterval
+	if res.KeepaliveInterval == 0 || (proxy.KeepaliveInterval != 0 && proxy.KeepaliveInterval < res.KeepaliveInterval) {
+		res.KeepaliveInterval = proxy.KeepaliveInterval
+	}
+
+	res.Tunnel = supervisor.Tunnel && proxy.Tunnel
+	return res, nil
+}
+
+func contains(s []string, e string) bool {
+	for _, v := range s {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Do performs the handshake with the supervisor at baseURL, e.g. http://10.0.0.12:22999.
+// If supervisor predates the handshake, Do returns the Baseline agreement.
+func Do(ctx context.Context, client *http.Client, baseURL string, proxy Capabilities) (Agreement, error) {
+	body, err := json.Marshal(proxy)
+	if err != nil {
+		return Baseline, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+Path, bytes.NewReader(body))
+	if err != nil {
+		return Baseline, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Baseline, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxMessageSize))
+		return Baseline, nil
+	default:
+		return Baseline, fmt.Errorf("handshake failed with status %d", resp.StatusCode)
+	}
+
+	var res Response
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxMessageSize)).Decode(&res)
+	if err != nil {
+		return Baseline, fmt.Errorf("cannot decode handshake response: %w", err)
+	}
+	// we don't take supervisor's word for the agreement - both sides computing it the same way is the point
+	return Negotiate(res.Capabilities, proxy)
+}
+
+// Handler serves the supervisor side of the handshake. Agree is called with every agreement supervisor makes.
+func Handler(supervisor Capabilities, agree func(Agreement)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "handshakes must be POSTed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var proxy Capabilities
+		err := json.NewDecoder(io.LimitReader(req.Body, maxMessageSize)).Decode(&proxy)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("cannot decode capabilities: %v", err), http.StatusBadRequest)
+			return
+		}
+		agreement, err := Negotiate(supervisor, proxy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if agree != nil {
+			agree(agreement)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Response{Capabilities: supervisor, Agreement: agreement})
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package handshake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Supervisor Capabilities
+		Proxy      Capabilities
+		Expected   Agreement
+		Err        error
+	}{
+		{Name: "nothing", Expected: Baseline},
+		{
+			Name:       "lower version wins",
+			Supervisor: Capabilities{Version: 3},
+			Proxy:      Capabilities{Version: 2, MinVersion: 1},
+			Expected:   Agreement{Version: 2},
+		},
+		{
+			Name:       "no common version",
+			Supervisor: Capabilities{Version: 3, MinVersion: 3},
+			Proxy:      Capabilities{Version: 2},
+			Err:        ErrIncompatible,
+		},
+		{
+			Name:       "supervisor prefers compression",
+			Supervisor: Capabilities{Version: 1, Compression: []string{"br", "gzip"}},
+			Proxy:      Capabilities{Version: 1, Compression: []string{"gzip", "br"}},
+			Expected:   Agreement{Version: 1, Compression: "br"},
+		},
+		{
+			Name:       "no common compression",
+			Supervisor: Capabilities{Version: 1, Compression: []string{"br"}},
+			Proxy:      Capabilities{Version: 1, Compression: []string{"gzip"}},
+			Expected:   Agreement{Version: 1},
+		},
+		{
+			Name:       "shorter keepalive wins",
+			Supervisor: Capabilities{Version: 1, KeepaliveInterval: util.Duration(30 * time.Second)},
+			Proxy:      Capabilities{Version: 1, KeepaliveInterval: util.Duration(20 * time.Second)},
+			Expected:   Agreement{Version: 1, KeepaliveInterval: util.Duration(20 * time.Second)},
+		},
+		{
+			Name:       "one-sided keepalive",
+			Supervisor: Capabilities{Version: 1},
+			Proxy:      Capabilities{Version: 1, KeepaliveInterval: util.Duration(20 * time.Second)},
+			Expected:   Agreement{Version: 1, KeepaliveInterval: util.Duration(20 * time.Second)},
+		},
+		{
+			Name:       "one-sided tunnel",
+			Supervisor: Capabilities{Version: 1, Tunnel: true},
+			Proxy:      Capabilities{Version: 1},
+			Expected:   Agreement{Version: 1},
+		},
+		{
+			Name:       "tunnel",
+			Supervisor: Capabilities{Version: 1, Tunnel: true},
+			Proxy:      Capabilities{Version: 1, Tunnel: true},
+			Expected:   Agreement{Version: 1, Tunnel: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := Negotiate(test.Supervisor, test.Proxy)
+			if !errors.Is(err, test.Err) {
+				t.Fatalf("unexpected error: want %v, got %v", test.Err, err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected agreement (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	var (
+		supervisor = Capabilities{Version: 1, Compression: []string{"gzip"}, KeepaliveInterval: util.Duration(30 * time.Second)}
+		proxy      = Capabilities{Version: 1, Compression: []string{"br", "gzip"}, KeepaliveInterval: util.Duration(time.Minute)}
+		expected   = Agreement{Version: 1, Compression: "gzip", KeepaliveInterval: util.Duration(30 * time.Second)}
+	)
+
+	tests := []struct {
+		Name     string
+		Handler  http.Handler
+		Expected Agreement
+		Error    bool
+	}{
+		{Name: "supervisor with handshake", Expected: expected},
+		{Name: "supervisor without handshake", Handler: http.NewServeMux(), Expected: Baseline},
+		{
+			Name: "broken supervisor",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}),
+			Error: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var agreed *Agreement
+			handler := test.Handler
+			if handler == nil {
+				mux := http.NewServeMux()
+				mux.Handle(Path, Handler(supervisor, func(a Agreement) { agreed = &a }))
+				handler = mux
+			}
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			act, err := Do(context.Background(), srv.Client(), srv.URL, proxy)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected agreement (-want +got):\n%s", diff)
+			}
+			if agreed != nil && *agreed != act {
+				t.Errorf("supervisor agreed on %+v, ws-proxy on %+v", *agreed, act)
+			}
+		})
+	}
+}
+
+func TestHandlerRejectsGet(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler(Capabilities{Version: 1}, nil).ServeHTTP(rec, httptest.NewRequest("GET", Path, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected status %d", rec.Code)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package imageref
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RewriteRule maps image references to a different location, e.g. a registry mirror
+type RewriteRule struct {
+	// Pattern is a regular expression which must match the complete, normalised image reference,
+	// e.g. docker.io/library/alpine:latest. Use capture groups to carry over parts of the reference.
+	Pattern string `json:"pattern"`
+	// Replacement is the rewritten image reference. It can refer to capture groups of the pattern, e.g. ${1}.
+	Replacement string `json:"replacement"`
+}
+
+// Rewriter rewrites image references using a list of rules. The first matching rule wins.
+// A nil rewriter leaves all references untouched.
+type Rewriter struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewRewriter compiles the rewrite rules
+func NewRewriter(rules []RewriteRule) (*Rewriter, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	res := &Rewriter{rules: make([]compiledRule, 0, len(rules))}
+	for i, r := range rules {
+		if r.Replacement == "" {
+			return nil, fmt.Errorf("rewrite rule %d: replacement is required", i)
+		}
+		pattern, err := regexp.Compile("^(?:" + r.Pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("rewrite rule %d: invalid pattern: %w", i, err)
+		}
+		res.rules = append(res.rules, compiledRule{pattern: pattern, replacement: r.Replacement})
+	}
+	return res, nil
+}
+
+// Rewrite applies the first matching rule to the reference. If no rule matches, the reference is returned unchanged.
+func (r *Rewriter) Rewrite(ref string) string {
+	if r == nil || ref == "" {
+		return ref
+	}
+
+	normalized := Normalize(ref)
+	for _, rule := range r.rules {
+		match := rule.pattern.FindStringSubmatchIndex(normalized)
+		if match == nil {
+			continue
+		}
+		return string(rule.pattern.ExpandString(nil, rule.replacement, normalized, match))
+	}
+	return ref
+}
+
+// Normalize turns a familiar image reference into its fully qualified form the way Docker does,
+// e.g. alpine becomes docker.io/library/alpine:latest.
+func Normalize(ref string) string {
+	name, suffix := ref, ""
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name, suffix = name[:i], name[i:]
+	}
+	if i := strings.LastIndexByte(name, ':'); i > strings.LastIndexByte(name, '/') {
+		name, suffix = name[:i], name[i:]+suffix
+	}
+	if suffix == "" {
+		suffix = ":latest"
+	}
+
+	i := strings.IndexByte(name, '/')
+	if i < 0 {
+		return "docker.io/library/" + name + suffix
+	}
+	if domain := name[:i]; !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		return "docker.io/" + name + suffix
+	}
+	return name + suffix
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package imageref
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		Ref      string
+		Expected string
+	}{
+		{"alpine", "docker.io/library/alpine:latest"},
+		{"gitpod/workspace-full:latest", "docker.io/gitpod/workspace-full:latest"},
+		{"eu.gcr.io/gitpod-core-dev/build/ide/code:commit-abc", "eu.gcr.io/gitpod-core-dev/build/ide/code:commit-abc"},
+		{"localhost:5000/foo", "localhost:5000/foo:latest"},
+		{"registry.local:5000/foo@sha256:1234", "registry.local:5000/foo@sha256:1234"},
+		{"alpine:3.13@sha256:1234", "docker.io/library/alpine:3.13@sha256:1234"},
+	}
+	for _, test := range tests {
+		t.Run(test.Ref, func(t *testing.T) {
+			if act := Normalize(test.Ref); act != test.Expected {
+				t.Errorf("expected %s, got %s", test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	rewriter, err := NewRewriter([]RewriteRule{
+		{Pattern: `docker\.io/(.*)`, Replacement: "mirror.internal/dockerhub/${1}"},
+		{Pattern: `eu\.gcr\.io/gitpod-core-dev/build/(.*)`, Replacement: "mirror.internal/gitpod/${1}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Ref      string
+		Expected string
+	}{
+		{"alpine", "mirror.internal/dockerhub/library/alpine:latest"},
+		{"gitpod/workspace-full", "mirror.internal/dockerhub/gitpod/workspace-full:latest"},
+		{"eu.gcr.io/gitpod-core-dev/build/ide/code:commit-abc", "mirror.internal/gitpod/ide/code:commit-abc"},
+		{"quay.io/foo/bar:1.0", "quay.io/foo/bar:1.0"},
+		{"mirror.internal/dockerhub/library/alpine:latest", "mirror.internal/dockerhub/library/alpine:latest"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Ref, func(t *testing.T) {
+			if act := rewriter.Rewrite(test.Ref); act != test.Expected {
+				t.Errorf("expected %s, got %s", test.Expected, act)
+			}
+		})
+	}
+
+	var noop *Rewriter
+	if act := noop.Rewrite("alpine"); act != "alpine" {
+		t.Errorf("nil rewriter changed the reference to %s", act)
+	}
+
+	_, err = NewRewriter([]RewriteRule{{Pattern: "(", Replacement: "foo"}})
+	if err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+}
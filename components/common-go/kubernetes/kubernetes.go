@@ -52,8 +52,17 @@ const (
 	// CPULimitAnnotation enforces a strict CPU limit on a workspace by virtue of ws-daemon
 	CPULimitAnnotation = "gitpod/cpuLimit"
 
+	// GPUAnnotation requests GPUs for a workspace from ws-daemon, either a number of whole GPUs (e.g. "1") or a MIG slice (e.g. "mig:1g.5gb")
+	GPUAnnotation = "gitpod.io/gpu"
+
 	// RequiredNodeServicesAnnotation lists all Gitpod services required on the node
 	RequiredNodeServicesAnnotation = "gitpod.io/requiredNodeServices"
+
+	// PreviewDNSHostnameAnnotation contains the hostname under which a workspace is reachable on a custom preview domain
+	PreviewDNSHostnameAnnotation = "gitpod.io/previewDnsHostname"
+
+	// PreviewDNSReadyAnnotation marks a workspace as ready to have its preview DNS record created
+	PreviewDNSReadyAnnotation = "gitpod.io/previewDnsReady"
 )
 
 // WorkspaceSupervisorEndpoint produces the supervisor endpoint of a workspace.
@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package namegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WorkspaceIDExpr matches the IDs of all workspaces we can route to: v4 UUIDs, the generated IDs of the default
+// scheme (e.g. amaranth-smelt-9ba20cc1), and the generated IDs of custom schemes (e.g. acme-amaranth-smelt-9ba20cc1
+// or acme-x8c2fd4k). The expression has neither anchors nor capture groups so that it can be embedded, e.g. in the
+// host patterns of ws-proxy.
+const WorkspaceIDExpr = `[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|[0-9a-z]{2,16}-[0-9a-z]{2,16}-[0-9a-z]{8}|[a-z][0-9a-z]{1,15}(?:-[a-z]{2,16}){0,2}-[0-9a-z]{4,16}`
+
+// MaxWorkspaceIDLength is the maximum length of a workspace ID. Workspace IDs are part of DNS labels, which must not
+// exceed 63 characters, and the longest label prefix ws-proxy accepts is "extensions-65535-".
+const MaxWorkspaceIDLength = 63 - len("extensions-65535-")
+
+const (
+	// IDFormatWords produces IDs like amaranth-smelt-9ba20cc1
+	IDFormatWords IDFormat = "words"
+	// IDFormatRandom produces IDs like acme-x8c2fd4k. It requires a prefix.
+	IDFormatRandom IDFormat = "random"
+
+	defaultSuffixLength = 8
+	// minAlphabetSize keeps the random suffix from becoming guessable or colliding all the time
+	minAlphabetSize = 10
+	// maxCollisionRetries is the number of times we generate a new ID when the previous one collided
+	maxCollisionRetries = 10
+)
+
+// IDFormat determines the structure of generated workspace IDs
+type IDFormat string
+
+var (
+	workspaceIDPattern  = regexp.MustCompile("^(?:" + WorkspaceIDExpr + ")$")
+	idPrefixPattern     = regexp.MustCompile(`^[a-z][0-9a-z]{1,15}$`)
+	idAlphabetPattern   = regexp.MustCompile(`^[0-9a-z]+$`)
+	reservedIDPrefixes  = []string{"webview", "browser", "extensions", "blobserve"}
+	errWorkspaceIDEmpty = fmt.Errorf("workspace ID must not be empty")
+)
+
+// IDScheme configures how an installation names its workspaces, e.g. to make workspace IDs recognizable in
+// support requests and logs. The zero value is the default scheme.
+type IDScheme struct {
+	// Format is the structure of the IDs. Defaults to words.
+	Format IDFormat `json:"format,omitempty"`
+	// SuffixLength is the number of random characters at the end of the ID. Defaults to 8.
+	SuffixLength int `json:"suffixLength,omitempty"`
+	// Alphabet is the set of characters the random suffix is made of. Defaults to lowercase letters and digits.
+	Alphabet string `json:"alphabet,omitempty"`
+	// Prefix is prepended to all IDs, e.g. the name of the installation
+	Prefix string `json:"prefix,omitempty"`
+	// ProjectPrefixes are vanity prefixes per project, e.g. {"gitpod-io/gitpod": "gp"}. They take precedence over Prefix.
+	ProjectPrefixes map[string]string `json:"projectPrefixes,omitempty"`
+}
+
+// Validate validates the scheme to catch issues during startup and not at runtime. Beyond checking its settings,
+// Validate makes sure that all IDs the scheme can produce are URL-safe and parse as workspace IDs in ws-proxy.
+func (s *IDScheme) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	switch s.Format {
+	case "", IDFormatWords:
+	case IDFormatRandom:
+		if s.Prefix == "" {
+			return fmt.Errorf("format %s requires a prefix", IDFormatRandom)
+		}
+	default:
+		return fmt.Errorf("unknown format %q", s.Format)
+	}
+
+	if s.SuffixLength != 0 && (s.SuffixLength < 4 || s.SuffixLength > 16) {
+		return fmt.Errorf("suffixLength must be between 4 and 16")
+	}
+	if s.Alphabet != "" {
+		if !idAlphabetPattern.MatchString(s.Alphabet) {
+			return fmt.Errorf("alphabet must consist of lowercase letters and digits only")
+		}
+		if n := len(uniqueChars(s.Alphabet)); n < minAlphabetSize {
+			return fmt.Errorf("alphabet must contain at least %d different characters, not %d", minAlphabetSize, n)
+		}
+	}
+
+	prefixes := map[string]string{"prefix": s.Prefix}
+	for project, p := range s.ProjectPrefixes {
+		if p == "" {
+			return fmt.Errorf("projectPrefixes[%s] must not be empty", project)
+		}
+		prefixes[fmt.Sprintf("projectPrefixes[%s]", project)] = p
+	}
+	for field, p := range prefixes {
+		if p == "" {
+			continue
+		}
+		if !idPrefixPattern.MatchString(p) {
+			return fmt.Errorf("%s: %q must start with a letter and consist of 2-16 lowercase letters and digits", field, p)
+		}
+		for _, r := range reservedIDPrefixes {
+			if p == r {
+				return fmt.Errorf("%s: %q is reserved", field, p)
+			}
+		}
+	}
+
+	for _, p := range prefixes {
+		// the longest ID the scheme can produce uses the longest words we have
+		id := s.assemble(p, longest(colors), longest(animals), strings.Repeat("z", s.suffixLength()))
+		if err := ValidateWorkspaceID(id); err != nil {
+			return fmt.Errorf("scheme produces invalid IDs: %w", err)
+		}
+	}
+	return nil
+}
+
+// Generate produces a new workspace ID for a project, which may be empty. If exists is not nil, Generate
+// makes sure the ID does not collide with an existing workspace.
+func (s *IDScheme) Generate(project string, exists func(id string) (bool, error)) (string, error) {
+	for i := 0; i < maxCollisionRetries; i++ {
+		id, err := s.generate(project)
+		if err != nil {
+			return "", err
+		}
+		if exists == nil {
+			return id, nil
+		}
+		collides, err := exists(id)
+		if err != nil {
+			return "", fmt.Errorf("cannot check workspace ID %s for collisions: %w", id, err)
+		}
+		if !collides {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("cannot generate a unique workspace ID after %d attempts - the scheme produces too few IDs", maxCollisionRetries)
+}
+
+func (s *IDScheme) generate(project string) (string, error) {
+	var (
+		prefix = s.prefix(project)
+		color  string
+		animal string
+		err    error
+	)
+	if s.format() == IDFormatWords {
+		color, err = chooseRandomly(colors, 1)
+		if err != nil {
+			return "", err
+		}
+		animal, err = chooseRandomly(animals, 1)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	alphabet := characters
+	if s != nil && s.Alphabet != "" {
+		alphabet = uniqueChars(s.Alphabet)
+	}
+	suffix, err := chooseRandomly(alphabet, s.suffixLength())
+	if err != nil {
+		return "", err
+	}
+	return s.assemble(prefix, color, animal, suffix), nil
+}
+
+func (s *IDScheme) assemble(prefix, color, animal, suffix string) string {
+	segs := make([]string, 0, 4)
+	if prefix != "" {
+		segs = append(segs, prefix)
+	}
+	if s.format() == IDFormatWords {
+		segs = append(segs, color, animal)
+	}
+	return strings.Join(append(segs, suffix), "-")
+}
+
+func (s *IDScheme) format() IDFormat {
+	if s == nil || s.Format == "" {
+		return IDFormatWords
+	}
+	return s.Format
+}
+
+func (s *IDScheme) suffixLength() int {
+	if s == nil || s.SuffixLength == 0 {
+		return defaultSuffixLength
+	}
+	return s.SuffixLength
+}
+
+func (s *IDScheme) prefix(project string) string {
+	if s == nil {
+		return ""
+	}
+	if p, ok := s.ProjectPrefixes[project]; ok && project != "" {
+		return p
+	}
+	return s.Prefix
+}
+
+// ValidateWorkspaceID makes sure a workspace ID is URL-safe, i.e. can be part of a workspace's host name,
+// and is recognised as workspace ID by ws-proxy.
+func ValidateWorkspaceID(id string) error {
+	if id == "" {
+		return errWorkspaceIDEmpty
+	}
+	if len(id) > MaxWorkspaceIDLength {
+		return fmt.Errorf("workspace ID %s is longer than %d characters", id, MaxWorkspaceIDLength)
+	}
+	if !workspaceIDPattern.MatchString(id) {
+		return fmt.Errorf("workspace ID %s does not match %s", id, workspaceIDPattern.String())
+	}
+	for _, r := range reservedIDPrefixes {
+		if strings.HasPrefix(id, r+"-") {
+			return fmt.Errorf("workspace ID %s starts with the reserved prefix %s", id, r)
+		}
+	}
+	return nil
+}
+
+func uniqueChars(s string) []string {
+	var (
+		res  []string
+		seen = make(map[rune]struct{}, len(s))
+	)
+	for _, c := range s {
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		res = append(res, string(c))
+	}
+	return res
+}
+
+func longest(options []string) string {
+	var res string
+	for _, o := range options {
+		if len(o) > len(res) {
+			res = o
+		}
+	}
+	return res
+}
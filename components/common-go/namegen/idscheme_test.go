@@ -0,0 +1,128 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package namegen_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gitpod-io/gitpod/common-go/namegen"
+)
+
+func TestIDSchemeValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Scheme *namegen.IDScheme
+		Valid  bool
+	}{
+		{Name: "nil", Valid: true},
+		{Name: "default", Scheme: &namegen.IDScheme{}, Valid: true},
+		{Name: "vanity prefixes", Scheme: &namegen.IDScheme{Prefix: "acme", ProjectPrefixes: map[string]string{"gitpod-io/gitpod": "gp"}}, Valid: true},
+		{Name: "random", Scheme: &namegen.IDScheme{Format: namegen.IDFormatRandom, Prefix: "acme", SuffixLength: 12, Alphabet: "0123456789abcdef"}, Valid: true},
+		{Name: "random without prefix", Scheme: &namegen.IDScheme{Format: namegen.IDFormatRandom}},
+		{Name: "unknown format", Scheme: &namegen.IDScheme{Format: "emoji"}},
+		{Name: "short suffix", Scheme: &namegen.IDScheme{SuffixLength: 3}},
+		{Name: "uppercase alphabet", Scheme: &namegen.IDScheme{Alphabet: "ABCDEFGHIJKLMNOP"}},
+		{Name: "small alphabet", Scheme: &namegen.IDScheme{Alphabet: "ababababab01"}},
+		{Name: "prefix with dash", Scheme: &namegen.IDScheme{Prefix: "ac-me"}},
+		{Name: "prefix starting with digit", Scheme: &namegen.IDScheme{Prefix: "8080"}},
+		{Name: "reserved prefix", Scheme: &namegen.IDScheme{ProjectPrefixes: map[string]string{"gitpod-io/gitpod": "webview"}}},
+		{Name: "empty project prefix", Scheme: &namegen.IDScheme{ProjectPrefixes: map[string]string{"gitpod-io/gitpod": ""}}},
+		{Name: "IDs too long for a DNS label", Scheme: &namegen.IDScheme{Prefix: "acmecorporations", SuffixLength: 16}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Scheme.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestIDSchemeGenerate(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Scheme  *namegen.IDScheme
+		Project string
+		Pattern string
+	}{
+		{Name: "default", Scheme: &namegen.IDScheme{}, Pattern: `^[a-z]+-[a-z]+-[a-z0-9]{8}$`},
+		{Name: "installation prefix", Scheme: &namegen.IDScheme{Prefix: "acme"}, Project: "acme/website", Pattern: `^acme-[a-z]+-[a-z]+-[a-z0-9]{8}$`},
+		{Name: "project prefix", Scheme: &namegen.IDScheme{Prefix: "acme", ProjectPrefixes: map[string]string{"gitpod-io/gitpod": "gp"}}, Project: "gitpod-io/gitpod", Pattern: `^gp-[a-z]+-[a-z]+-[a-z0-9]{8}$`},
+		{Name: "random", Scheme: &namegen.IDScheme{Format: namegen.IDFormatRandom, Prefix: "acme", SuffixLength: 12, Alphabet: "0123456789abcdef"}, Pattern: `^acme-[0-9a-f]{12}$`},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pattern := regexp.MustCompile(test.Pattern)
+			for i := 0; i < 1000; i++ {
+				id, err := test.Scheme.Generate(test.Project, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !pattern.MatchString(id) {
+					t.Fatalf("ID %s does not match %s", id, test.Pattern)
+				}
+				if err := namegen.ValidateWorkspaceID(id); err != nil {
+					t.Fatalf("generated invalid ID: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestIDSchemeGenerateCollisions(t *testing.T) {
+	scheme := &namegen.IDScheme{}
+	var attempts int
+	id, err := scheme.Generate("", func(id string) (bool, error) {
+		attempts++
+		return attempts < 3, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || id == "" {
+		t.Errorf("expected an ID after 3 attempts, got %q after %d", id, attempts)
+	}
+
+	_, err = scheme.Generate("", func(id string) (bool, error) { return true, nil })
+	if err == nil {
+		t.Error("expected an error if all IDs collide")
+	}
+}
+
+func TestValidateWorkspaceID(t *testing.T) {
+	tests := []struct {
+		ID    string
+		Valid bool
+	}{
+		{ID: "amaranth-smelt-9ba20cc1", Valid: true},
+		{ID: "a7dab5e2-8c53-4a2b-8a07-4a4c9a9bbc97", Valid: true},
+		{ID: "gp-amaranth-smelt-9ba20cc1", Valid: true},
+		{ID: "acme-x8c2fd4k", Valid: true},
+		{ID: ""},
+		{ID: "foobar"},
+		{ID: "Amaranth-Smelt-9ba20cc1"},
+		{ID: "amaranth_smelt_9ba20cc1"},
+		{ID: "webview-amaranth-smelt-9ba20cc1"},
+		{ID: "8080-x8c2fd4k"},
+		{ID: "acme-" + strings.Repeat("a", 16) + "-" + strings.Repeat("b", 16) + "-" + strings.Repeat("c", 16)},
+	}
+	for _, test := range tests {
+		t.Run(test.ID, func(t *testing.T) {
+			err := namegen.ValidateWorkspaceID(test.ID)
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
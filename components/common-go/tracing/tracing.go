@@ -24,6 +24,7 @@ import (
 
 type tracingOptions struct {
 	prometheusReporter *PromReporter
+	configModifier     []func(cfg *jaegercfg.Configuration)
 }
 
 // Option configures the tracing
@@ -36,6 +37,38 @@ func WithPrometheusReporter(p *PromReporter) Option {
 	}
 }
 
+// WithSampler overrides the sampler configured in the environment.
+// See https://www.jaegertracing.io/docs/latest/sampling/ for valid types and params.
+func WithSampler(samplerType string, param float64) Option {
+	return func(o *tracingOptions) {
+		o.configModifier = append(o.configModifier, func(cfg *jaegercfg.Configuration) {
+			if cfg.Sampler == nil {
+				cfg.Sampler = &jaegercfg.SamplerConfig{}
+			}
+			cfg.Sampler.Type = samplerType
+			cfg.Sampler.Param = param
+		})
+	}
+}
+
+// WithCollectorEndpoint makes the tracer send spans to the given collector (HTTP) endpoint,
+// or to the given agent (UDP host:port) address, rather than what's configured in the environment.
+func WithCollectorEndpoint(collectorEndpoint, agentHostPort string) Option {
+	return func(o *tracingOptions) {
+		o.configModifier = append(o.configModifier, func(cfg *jaegercfg.Configuration) {
+			if cfg.Reporter == nil {
+				cfg.Reporter = &jaegercfg.ReporterConfig{}
+			}
+			if collectorEndpoint != "" {
+				cfg.Reporter.CollectorEndpoint = collectorEndpoint
+			}
+			if agentHostPort != "" {
+				cfg.Reporter.LocalAgentHostPort = agentHostPort
+			}
+		})
+	}
+}
+
 // Init initializes tracing for this application
 func Init(serviceName string, opts ...Option) io.Closer {
 	cfg, err := jaegercfg.FromEnv()
@@ -44,17 +77,20 @@ func Init(serviceName string, opts ...Option) io.Closer {
 		return nil
 	}
 
+	var options tracingOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	for _, mod := range options.configModifier {
+		mod(cfg)
+	}
+
 	reporter, err := cfg.Reporter.NewReporter(serviceName, nil, nil)
 	if err != nil {
 		log.WithError(err).Debug("cannot initialize Jaeger tracer from env")
 		return nil
 	}
 
-	var options tracingOptions
-	for _, opt := range opts {
-		opt(&options)
-	}
-
 	if options.prometheusReporter != nil {
 		promrep := options.prometheusReporter
 		err = promrep.RegisterMetrics()
@@ -135,6 +171,19 @@ func FromTraceID(traceID string) opentracing.SpanContext {
 	return spanCtx
 }
 
+// TraceIDOf returns the ID of the trace span belongs to as Jaeger displays it, e.g. to link metrics to traces
+// using exemplars. If the trace is not sampled, i.e. there is nothing to link to, we return an empty string.
+func TraceIDOf(span opentracing.Span) string {
+	if span == nil {
+		return ""
+	}
+	sc, ok := span.Context().(jaeger.SpanContext)
+	if !ok || !sc.IsSampled() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
 // LogEvent logs an event in the trace. This is similar to the (now deprecated) span.LogEvent
 func LogEvent(span opentracing.Span, name string) {
 	span.LogFields(tracelog.String("event", name))
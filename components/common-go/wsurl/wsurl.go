@@ -0,0 +1,385 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package wsurl composes and parses the hosts and URLs workspaces are served from. Workspace hosts look like
+// [<foreign prefix>-][<port>-]<workspace ID><host suffix>, e.g. 3000-amaranth-smelt-9ba20cc1.ws.gitpod.io.
+// ws-manager, ws-proxy and friends must agree on this format, hence they all use this package.
+package wsurl
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gitpod-io/gitpod/common-go/namegen"
+)
+
+const (
+	// BlobserveHostPrefix is the label of the host blobserve serves IDE assets from, e.g. blobserve.ws.gitpod.io
+	BlobserveHostPrefix = "blobserve"
+
+	// maxLabelLength is the maximum length of a DNS label
+	maxLabelLength = 63
+	// maxPort is the highest port number a workspace port host can name
+	maxPort = 65535
+	// maxPortDigits is the number of digits of maxPort
+	maxPortDigits = 5
+)
+
+// DefaultForeignPrefixes are the prefixes of the hosts we serve foreign content, e.g. webviews, from
+var DefaultForeignPrefixes = []string{"webview", "browser", "extensions"}
+
+// Host is a parsed workspace host
+type Host struct {
+	// WorkspaceID is the ID of the workspace the host belongs to
+	WorkspaceID string
+	// Port is the workspace port the host serves, or zero if it serves the workspace itself
+	Port int
+	// ForeignPrefix is the prefix of a foreign content host without the trailing dash, e.g. webview,
+	// or empty if the host serves trusted content
+	ForeignPrefix string
+}
+
+// Scheme describes the workspace hosts of an installation
+type Scheme struct {
+	// HostSuffix is the suffix of all workspace hosts including the leading dot, e.g. ".ws.gitpod.io"
+	HostSuffix string
+	// ForeignHostSuffix is the suffix of foreign content hosts. If it differs from HostSuffix, workspace hosts
+	// do not serve foreign content. Defaults to HostSuffix.
+	ForeignHostSuffix string
+	// ForeignPrefixes are the prefixes of foreign content hosts. Defaults to DefaultForeignPrefixes.
+	ForeignPrefixes []string
+}
+
+// Validate makes sure that the scheme produces valid host names
+func (s Scheme) Validate() error {
+	if err := validateHostSuffix(s.HostSuffix); err != nil {
+		return fmt.Errorf("host suffix: %w", err)
+	}
+	if s.ForeignHostSuffix != "" {
+		if err := validateHostSuffix(s.ForeignHostSuffix); err != nil {
+			return fmt.Errorf("foreign host suffix: %w", err)
+		}
+	}
+	for _, p := range s.ForeignPrefixes {
+		if p == "" || strings.Contains(p, "-") || validateLabel(p) != nil {
+			return fmt.Errorf("invalid foreign prefix %q: must be a DNS label without dashes", p)
+		}
+		if p == BlobserveHostPrefix {
+			return fmt.Errorf("foreign prefix %q is reserved", p)
+		}
+	}
+	return nil
+}
+
+func validateHostSuffix(suffix string) error {
+	if suffix == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if !strings.HasPrefix(suffix, ".") {
+		return fmt.Errorf("%q must start with a dot", suffix)
+	}
+	return ValidateHostname(strings.TrimPrefix(suffix, "."))
+}
+
+func (s Scheme) foreignHostSuffix() string {
+	if s.ForeignHostSuffix != "" {
+		return s.ForeignHostSuffix
+	}
+	return s.HostSuffix
+}
+
+func (s Scheme) foreignPrefixes() []string {
+	if len(s.ForeignPrefixes) > 0 {
+		return s.ForeignPrefixes
+	}
+	return DefaultForeignPrefixes
+}
+
+func (s Scheme) isForeignPrefix(prefix string) bool {
+	for _, p := range s.foreignPrefixes() {
+		if p == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// Compose produces the host name of a workspace host
+func (s Scheme) Compose(h Host) (string, error) {
+	if err := namegen.ValidateWorkspaceID(h.WorkspaceID); err != nil {
+		return "", err
+	}
+	if h.Port < 0 || h.Port > maxPort {
+		return "", fmt.Errorf("port %d is out of range", h.Port)
+	}
+
+	var (
+		label  = h.WorkspaceID
+		suffix = s.HostSuffix
+	)
+	if h.Port != 0 {
+		label = strconv.Itoa(h.Port) + "-" + label
+	}
+	if h.ForeignPrefix != "" {
+		if !s.isForeignPrefix(h.ForeignPrefix) {
+			return "", fmt.Errorf("unknown foreign prefix %q", h.ForeignPrefix)
+		}
+		label = h.ForeignPrefix + "-" + label
+		suffix = s.foreignHostSuffix()
+	}
+	if err := validateLabel(label); err != nil {
+		return "", err
+	}
+	return label + suffix, nil
+}
+
+// WorkspaceHost produces the host a workspace is served from
+func (s Scheme) WorkspaceHost(workspaceID string) (string, error) {
+	return s.Compose(Host{WorkspaceID: workspaceID})
+}
+
+// PortHost produces the host a workspace port is served from
+func (s Scheme) PortHost(workspaceID string, port int) (string, error) {
+	if port == 0 {
+		return "", fmt.Errorf("port must not be zero")
+	}
+	return s.Compose(Host{WorkspaceID: workspaceID, Port: port})
+}
+
+// BlobserveHost produces the host blobserve serves IDE assets from
+func (s Scheme) BlobserveHost() string {
+	return BlobserveHostPrefix + s.HostSuffix
+}
+
+// IsBlobserveHost returns true if host is the blobserve host. host may contain a port.
+func (s Scheme) IsBlobserveHost(host string) bool {
+	return normalizeHost(host) == s.BlobserveHost()
+}
+
+// ParseHost parses a workspace host. host may contain a port, e.g. from a Host header.
+//
+// Labels which start with a number followed by a dash are port hosts as long as the remainder is a valid
+// workspace ID, e.g. 3000-ab-9ba20cc1 names port 3000 of workspace ab-9ba20cc1, not workspace 3000-ab-9ba20cc1.
+func (s Scheme) ParseHost(host string) (*Host, error) {
+	host = normalizeHost(host)
+
+	var (
+		label   string
+		foreign bool
+		ok      bool
+	)
+	if fs := s.foreignHostSuffix(); fs != s.HostSuffix {
+		if label, ok = cutSuffix(host, fs); ok {
+			foreign = true
+		}
+	}
+	if !ok {
+		label, ok = cutSuffix(host, s.HostSuffix)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%q is not a workspace host", host)
+	}
+	if err := validateLabel(label); err != nil {
+		return nil, fmt.Errorf("%q is not a workspace host: %w", host, err)
+	}
+
+	var res Host
+	if idx := strings.Index(label, "-"); idx > 0 && s.isForeignPrefix(label[:idx]) {
+		res.ForeignPrefix = label[:idx]
+		label = label[idx+1:]
+	}
+	if foreign && res.ForeignPrefix == "" {
+		return nil, fmt.Errorf("%q is a foreign content host without prefix", host)
+	}
+	if !foreign && res.ForeignPrefix != "" && s.foreignHostSuffix() != s.HostSuffix {
+		return nil, fmt.Errorf("%q is a workspace host with foreign content prefix", host)
+	}
+
+	if port, id, ok := cutPort(label); ok {
+		res.Port = port
+		res.WorkspaceID = id
+		return &res, nil
+	}
+	if err := namegen.ValidateWorkspaceID(label); err != nil {
+		return nil, fmt.Errorf("%q is not a workspace host: %w", host, err)
+	}
+	res.WorkspaceID = label
+	return &res, nil
+}
+
+// cutPort splits a label into port and workspace ID, if it's the label of a port host
+func cutPort(label string) (port int, workspaceID string, ok bool) {
+	// this runs for every request ws-proxy serves, hence we check the digits ourselves instead of producing parse errors
+	idx := strings.Index(label, "-")
+	if idx <= 0 || idx > maxPortDigits || label[0] == '0' {
+		return 0, "", false
+	}
+	for _, c := range label[:idx] {
+		if c < '0' || c > '9' {
+			return 0, "", false
+		}
+	}
+	port, err := strconv.Atoi(label[:idx])
+	if err != nil || port > maxPort {
+		return 0, "", false
+	}
+	workspaceID = label[idx+1:]
+	if namegen.ValidateWorkspaceID(workspaceID) != nil {
+		return 0, "", false
+	}
+	return port, workspaceID, true
+}
+
+// IsForeignOrigin returns true if origin, e.g. the value of an Origin header, is that of a foreign content host
+func (s Scheme) IsForeignOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	h, err := s.ParseHost(u.Host)
+	if err != nil {
+		return false
+	}
+	return h.ForeignPrefix != ""
+}
+
+// IsSubdomainOrigin returns true if the host of origin, e.g. the value of an Origin header, is hostname or one of its subdomains.
+// origin may lack the scheme.
+func IsSubdomainOrigin(origin, hostname string) bool {
+	if !strings.Contains(origin, "://") {
+		origin = "//" + origin
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := normalizeHost(u.Host)
+	hostname = strings.ToLower(hostname)
+	return host == hostname || strings.HasSuffix(host, "."+hostname)
+}
+
+// ValidateHostname makes sure hostname is a valid DNS name, e.g. so that it can be part of a URL or a certificate
+func ValidateHostname(hostname string) error {
+	if hostname == "" {
+		return fmt.Errorf("host name must not be empty")
+	}
+	if len(hostname) > 253 {
+		return fmt.Errorf("host name %q is longer than 253 characters", hostname)
+	}
+	for _, l := range strings.Split(hostname, ".") {
+		if err := validateLabel(l); err != nil {
+			return fmt.Errorf("host name %q: %w", hostname, err)
+		}
+	}
+	return nil
+}
+
+func validateLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("empty DNS label")
+	}
+	if len(label) > maxLabelLength {
+		return fmt.Errorf("DNS label %q is longer than %d characters", label, maxLabelLength)
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return fmt.Errorf("DNS label %q must not start or end with a dash", label)
+	}
+	for _, c := range label {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return fmt.Errorf("DNS label %q contains %q", label, c)
+		}
+	}
+	return nil
+}
+
+// normalizeHost lowercases a host and removes its port, if any
+func normalizeHost(host string) string {
+	if strings.Contains(host, ":") {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+	return strings.ToLower(host)
+}
+
+func cutSuffix(s, suffix string) (string, bool) {
+	if !strings.HasSuffix(s, suffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(s, suffix), true
+}
+
+// TemplateContext are the fields available in workspace URL templates
+type TemplateContext struct {
+	// ID is the ID of the workspace instance
+	ID string
+	// Prefix is the service prefix of the workspace, usually the workspace ID
+	Prefix string
+	// Host is the host of the installation, e.g. gitpod.io
+	Host string
+	// WorkspacePort is the workspace port, only available in port URL templates
+	WorkspacePort string
+	// IngressPort is the public port the workspace port is served on, only available in port URL templates
+	IngressPort string
+}
+
+// RenderTemplate renders a workspace URL or host name template
+func RenderTemplate(tpl string, ctx TemplateContext) (string, error) {
+	t, err := template.New("url").Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse template: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, ctx)
+	if err != nil {
+		return "", fmt.Errorf("cannot render template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// ValidateURLTemplate makes sure a workspace URL template renders URLs whose host, if any, is a valid host name
+// for all workspaces
+func ValidateURLTemplate(tpl string) error {
+	res, err := RenderTemplate(tpl, ExampleTemplateContext("gitpod.io"))
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(res)
+	if err != nil {
+		return fmt.Errorf("not a valid URL: %w", err)
+	}
+	if u.Host == "" || net.ParseIP(u.Hostname()) != nil {
+		return nil
+	}
+	return ValidateHostname(strings.ToLower(u.Hostname()))
+}
+
+// ValidateHostnameTemplate makes sure a workspace host name template renders valid host names for all workspaces
+func ValidateHostnameTemplate(tpl string) error {
+	res, err := RenderTemplate(tpl, ExampleTemplateContext("gitpod.io"))
+	if err != nil {
+		return err
+	}
+	return ValidateHostname(res)
+}
+
+// ExampleTemplateContext is the context to validate templates with. It holds the longest workspace ID and port
+// we produce, so that templates which work with it work with all workspaces.
+func ExampleTemplateContext(host string) TemplateContext {
+	// the longest ID a custom ID scheme can produce, e.g. acme-<word>-<suffix>
+	id := "a" + strings.Repeat("b", 15) + "-" + strings.Repeat("c", 16) + "-" + strings.Repeat("d", namegen.MaxWorkspaceIDLength-34)
+	return TemplateContext{
+		ID:            id,
+		Prefix:        id,
+		Host:          host,
+		WorkspacePort: strconv.Itoa(maxPort),
+		IngressPort:   strconv.Itoa(maxPort),
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package wsurl_test
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/common-go/namegen"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
+)
+
+var (
+	testScheme = wsurl.Scheme{HostSuffix: ".ws.gitpod.io"}
+
+	testForeignScheme = wsurl.Scheme{
+		HostSuffix:        ".ws.gitpod.io",
+		ForeignHostSuffix: ".ws.gitpod-foreign.io",
+	}
+
+	longestID = "a" + strings.Repeat("b", 15) + "-" + strings.Repeat("c", 16) + "-" + strings.Repeat("d", 12)
+)
+
+func TestParseHost(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Scheme      wsurl.Scheme
+		Host        string
+		Expectation *wsurl.Host
+	}{
+		{Name: "workspace", Host: "amaranth-smelt-9ba20cc1.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1"}},
+		{Name: "uuid", Host: "a7dab5e2-8c53-4a2b-8a07-4a4c9a9bbc97.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "a7dab5e2-8c53-4a2b-8a07-4a4c9a9bbc97"}},
+		{Name: "with port", Host: "amaranth-smelt-9ba20cc1.ws.gitpod.io:443", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1"}},
+		{Name: "upper case", Host: "Amaranth-Smelt-9ba20cc1.WS.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1"}},
+		{Name: "port host", Host: "3000-amaranth-smelt-9ba20cc1.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", Port: 3000}},
+		{Name: "highest port", Host: "65535-amaranth-smelt-9ba20cc1.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", Port: 65535}},
+		{Name: "custom scheme", Host: "acme-x8c2fd4k.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "acme-x8c2fd4k"}},
+		{Name: "custom scheme port host", Host: "8080-acme-x8c2fd4k.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "acme-x8c2fd4k", Port: 8080}},
+		{Name: "longest ID", Host: "extensions-65535-" + longestID + ".ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: longestID, Port: 65535, ForeignPrefix: "extensions"}},
+		{Name: "foreign", Host: "webview-amaranth-smelt-9ba20cc1.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", ForeignPrefix: "webview"}},
+		{Name: "foreign port host", Host: "browser-3000-amaranth-smelt-9ba20cc1.ws.gitpod.io", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", Port: 3000, ForeignPrefix: "browser"}},
+		{Name: "foreign suffix", Scheme: testForeignScheme, Host: "webview-amaranth-smelt-9ba20cc1.ws.gitpod-foreign.io", Expectation: &wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", ForeignPrefix: "webview"}},
+		{Name: "foreign suffix without prefix", Scheme: testForeignScheme, Host: "amaranth-smelt-9ba20cc1.ws.gitpod-foreign.io"},
+		{Name: "foreign prefix on workspace suffix", Scheme: testForeignScheme, Host: "webview-amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "nested subdomain", Host: "foo.amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "trailing host", Host: "amaranth-smelt-9ba20cc1.ws.gitpod.io.evil.com"},
+		{Name: "suffix is not a pattern", Host: "amaranth-smelt-9ba20cc1.wsXgitpodXio"},
+		{Name: "no label", Host: ".ws.gitpod.io"},
+		{Name: "blobserve", Host: "blobserve.ws.gitpod.io"},
+		{Name: "invalid ID", Host: "foobar.ws.gitpod.io"},
+		{Name: "leading zero port", Host: "03000-amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "port out of range", Host: "65536-amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "zero port", Host: "0-amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "label too long", Host: "extensions-65535-" + longestID + "x.ws.gitpod.io"},
+		{Name: "underscore", Host: "amaranth_smelt_9ba20cc1.ws.gitpod.io"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			s := test.Scheme
+			if s.HostSuffix == "" {
+				s = testScheme
+			}
+			act, err := s.ParseHost(test.Host)
+			if test.Expectation == nil {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", act)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected ParseHost (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Scheme      wsurl.Scheme
+		Host        wsurl.Host
+		Expectation string
+	}{
+		{Name: "workspace", Host: wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1"}, Expectation: "amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "port host", Host: wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", Port: 3000}, Expectation: "3000-amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "foreign", Host: wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", ForeignPrefix: "webview"}, Expectation: "webview-amaranth-smelt-9ba20cc1.ws.gitpod.io"},
+		{Name: "foreign suffix", Scheme: testForeignScheme, Host: wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", ForeignPrefix: "webview"}, Expectation: "webview-amaranth-smelt-9ba20cc1.ws.gitpod-foreign.io"},
+		{Name: "longest ID", Host: wsurl.Host{WorkspaceID: longestID, Port: 65535, ForeignPrefix: "extensions"}, Expectation: "extensions-65535-" + longestID + ".ws.gitpod.io"},
+		{Name: "invalid ID", Host: wsurl.Host{WorkspaceID: "foobar"}},
+		{Name: "ID with dot", Host: wsurl.Host{WorkspaceID: "amaranth-smelt.9ba20cc1"}},
+		{Name: "negative port", Host: wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", Port: -1}},
+		{Name: "port out of range", Host: wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", Port: 65536}},
+		{Name: "unknown foreign prefix", Host: wsurl.Host{WorkspaceID: "amaranth-smelt-9ba20cc1", ForeignPrefix: "evil"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			s := test.Scheme
+			if s.HostSuffix == "" {
+				s = testScheme
+			}
+			act, err := s.Compose(test.Host)
+			if test.Expectation == "" {
+				if err == nil {
+					t.Errorf("expected an error, got %q", act)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if act != test.Expectation {
+				t.Errorf("unexpected Compose: want %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestSchemeValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Scheme wsurl.Scheme
+		Valid  bool
+	}{
+		{Name: "default", Scheme: testScheme, Valid: true},
+		{Name: "foreign suffix", Scheme: testForeignScheme, Valid: true},
+		{Name: "empty", Scheme: wsurl.Scheme{}},
+		{Name: "no leading dot", Scheme: wsurl.Scheme{HostSuffix: "ws.gitpod.io"}},
+		{Name: "pattern", Scheme: wsurl.Scheme{HostSuffix: ".ws.gitpod.*"}},
+		{Name: "dashed foreign prefix", Scheme: wsurl.Scheme{HostSuffix: ".ws.gitpod.io", ForeignPrefixes: []string{"web-view"}}},
+		{Name: "reserved foreign prefix", Scheme: wsurl.Scheme{HostSuffix: ".ws.gitpod.io", ForeignPrefixes: []string{"blobserve"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Scheme.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestIsSubdomainOrigin(t *testing.T) {
+	tests := []struct {
+		Origin      string
+		Expectation bool
+	}{
+		{Origin: "https://gitpod.io", Expectation: true},
+		{Origin: "https://gitpod.io:443", Expectation: true},
+		{Origin: "https://amaranth-smelt-9ba20cc1.ws.gitpod.io", Expectation: true},
+		{Origin: "gitpod.io", Expectation: true},
+		{Origin: "https://evilgitpod.io"},
+		{Origin: "https://gitpod.io.evil.com"},
+		{Origin: "https://gitpodXio"},
+		{Origin: "null"},
+		{Origin: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Origin, func(t *testing.T) {
+			if act := wsurl.IsSubdomainOrigin(test.Origin, "gitpod.io"); act != test.Expectation {
+				t.Errorf("unexpected IsSubdomainOrigin: want %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestValidateURLTemplate(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Template string
+		Valid    bool
+	}{
+		{Name: "workspace URL", Template: "https://{{ .Prefix }}.ws.{{ .Host }}", Valid: true},
+		{Name: "port URL", Template: "https://{{ .WorkspacePort }}-{{ .Prefix }}.ws.{{ .Host }}", Valid: true},
+		{Name: "IP address", Template: "http://10.0.0.1:{{ .IngressPort }}", Valid: true},
+		{Name: "unknown field", Template: "https://{{ .Port }}-{{ .Prefix }}.ws.{{ .Host }}"},
+		{Name: "label too long", Template: "https://{{ .ID }}-{{ .Prefix }}.ws.{{ .Host }}"},
+		{Name: "invalid character", Template: "https://{{ .Prefix }}_ws.{{ .Host }}"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := wsurl.ValidateURLTemplate(test.Template)
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestExampleTemplateContext(t *testing.T) {
+	ctx := wsurl.ExampleTemplateContext("gitpod.io")
+	if err := namegen.ValidateWorkspaceID(ctx.ID); err != nil {
+		t.Errorf("example ID is invalid: %v", err)
+	}
+	if len(ctx.ID) != namegen.MaxWorkspaceIDLength {
+		t.Errorf("example ID is %d characters long, expected %d", len(ctx.ID), namegen.MaxWorkspaceIDLength)
+	}
+}
+
+// randomHost produces workspace hosts with valid IDs, ports and foreign prefixes
+type randomHost wsurl.Host
+
+func (randomHost) Generate(r *rand.Rand, size int) reflect.Value {
+	s := &namegen.IDScheme{
+		Prefix: "acme",
+		Format: []namegen.IDFormat{namegen.IDFormatWords, namegen.IDFormatRandom}[r.Intn(2)],
+	}
+	id, err := s.Generate("", func(string) (bool, error) { return false, nil })
+	if err != nil {
+		panic(err)
+	}
+	if r.Intn(4) == 0 {
+		id, _ = namegen.GenerateWorkspaceID()
+	}
+
+	var h randomHost
+	h.WorkspaceID = id
+	if r.Intn(2) == 0 {
+		h.Port = 1 + r.Intn(65535)
+	}
+	if r.Intn(2) == 0 {
+		h.ForeignPrefix = wsurl.DefaultForeignPrefixes[r.Intn(len(wsurl.DefaultForeignPrefixes))]
+	}
+	return reflect.ValueOf(h)
+}
+
+func TestComposeParseRoundTrip(t *testing.T) {
+	for _, s := range []wsurl.Scheme{testScheme, testForeignScheme} {
+		err := quick.Check(func(h randomHost) bool {
+			host, err := s.Compose(wsurl.Host(h))
+			if err != nil {
+				t.Logf("cannot compose %+v: %v", h, err)
+				return false
+			}
+			act, err := s.ParseHost(host)
+			if err != nil {
+				t.Logf("cannot parse %s: %v", host, err)
+				return false
+			}
+			return *act == wsurl.Host(h)
+		}, &quick.Config{MaxCount: 1000})
+		if err != nil {
+			t.Errorf("%s: %v", s.HostSuffix, err)
+		}
+	}
+}
+
+// TestParseHostArbitrary makes sure that whatever ParseHost accepts composes back to the same host
+func TestParseHostArbitrary(t *testing.T) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789-.:_AZ"
+	randomLabel := func(r *rand.Rand) string {
+		b := make([]byte, r.Intn(70))
+		for i := range b {
+			b[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	err := quick.Check(func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		host := randomLabel(r)
+		if r.Intn(2) == 0 {
+			host = strings.Join([]string{"webview", "3000", "amaranth", "smelt", "9ba20cc1", host}[r.Intn(5):], "-")
+		}
+		host += testScheme.HostSuffix
+
+		h, err := testScheme.ParseHost(host)
+		if err != nil {
+			return true
+		}
+		act, err := testScheme.Compose(*h)
+		if err != nil {
+			t.Logf("cannot compose %+v parsed from %s: %v", h, host, err)
+			return false
+		}
+		if act != strings.ToLower(host) {
+			t.Logf("%s parsed to %+v, which composes to %s", host, h, act)
+			return false
+		}
+		return true
+	}, &quick.Config{MaxCount: 10000})
+	if err != nil {
+		t.Error(err)
+	}
+}
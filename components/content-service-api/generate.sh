@@ -5,7 +5,7 @@ protoc -I. --go_out=plugins=grpc:. *.proto
 mv github.com/gitpod-io/gitpod/content-service/api/* go && rm -rf github.com
 
 cd typescript
-rm src/initializer_*.ts src/initializer_*.js src/blobs_*.ts src/blobs_*.js
+rm src/initializer_*.ts src/initializer_*.js src/blobs_*.ts src/blobs_*.js src/snapshots_*.ts src/snapshots_*.js src/prebuilds_*.ts src/prebuilds_*.js src/streams_*.ts src/streams_*.js
 export PATH=$(yarn bin):$PATH
 protoc --plugin=protoc-gen-grpc=`which grpc_tools_node_protoc_plugin` --js_out=import_style=commonjs,binary:src --grpc_out=src -I.. ../*.proto
 protoc --plugin=protoc-gen-ts=`which protoc-gen-ts` --ts_out=src -I /usr/lib/protoc/include -I .. ../*.proto
@@ -93,6 +93,38 @@ func (GitAuthMethod) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_fb6f168f5b28a3e9, []int{1}
 }
 
+// LayerConflictPolicy decides what happens with a file of a layer that exists already
+type LayerConflictPolicy int32
+
+const (
+	// OVERWRITE replaces the existing file with the one of the layer
+	LayerConflictPolicy_OVERWRITE LayerConflictPolicy = 0
+	// KEEP_EXISTING keeps the existing file
+	LayerConflictPolicy_KEEP_EXISTING LayerConflictPolicy = 1
+	// FAIL fails the initialization
+	LayerConflictPolicy_FAIL LayerConflictPolicy = 2
+)
+
+var LayerConflictPolicy_name = map[int32]string{
+	0: "OVERWRITE",
+	1: "KEEP_EXISTING",
+	2: "FAIL",
+}
+
+var LayerConflictPolicy_value = map[string]int32{
+	"OVERWRITE":     0,
+	"KEEP_EXISTING": 1,
+	"FAIL":          2,
+}
+
+func (x LayerConflictPolicy) String() string {
+	return proto.EnumName(LayerConflictPolicy_name, int32(x))
+}
+
+func (LayerConflictPolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_fb6f168f5b28a3e9, []int{2}
+}
+
 // WorkspaceInitializer specifies how a workspace is to be initialized
 type WorkspaceInitializer struct {
 	// Types that are valid to be assigned to Spec:
@@ -100,6 +132,8 @@ type WorkspaceInitializer struct {
 	//	*WorkspaceInitializer_Git
 	//	*WorkspaceInitializer_Snapshot
 	//	*WorkspaceInitializer_Prebuild
+	//	*WorkspaceInitializer_Composite
+	//	*WorkspaceInitializer_Archive
 	Spec                 isWorkspaceInitializer_Spec `protobuf_oneof:"spec"`
 	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
 	XXX_unrecognized     []byte                      `json:"-"`
@@ -151,6 +185,14 @@ type WorkspaceInitializer_Prebuild struct {
 	Prebuild *PrebuildInitializer `protobuf:"bytes,4,opt,name=prebuild,proto3,oneof"`
 }
 
+type WorkspaceInitializer_Composite struct {
+	Composite *CompositeInitializer `protobuf:"bytes,5,opt,name=composite,proto3,oneof"`
+}
+
+type WorkspaceInitializer_Archive struct {
+	Archive *ArchiveInitializer `protobuf:"bytes,6,opt,name=archive,proto3,oneof"`
+}
+
 func (*WorkspaceInitializer_Empty) isWorkspaceInitializer_Spec() {}
 
 func (*WorkspaceInitializer_Git) isWorkspaceInitializer_Spec() {}
@@ -159,6 +201,10 @@ func (*WorkspaceInitializer_Snapshot) isWorkspaceInitializer_Spec() {}
 
 func (*WorkspaceInitializer_Prebuild) isWorkspaceInitializer_Spec() {}
 
+func (*WorkspaceInitializer_Composite) isWorkspaceInitializer_Spec() {}
+
+func (*WorkspaceInitializer_Archive) isWorkspaceInitializer_Spec() {}
+
 func (m *WorkspaceInitializer) GetSpec() isWorkspaceInitializer_Spec {
 	if m != nil {
 		return m.Spec
@@ -194,6 +240,20 @@ func (m *WorkspaceInitializer) GetPrebuild() *PrebuildInitializer {
 	return nil
 }
 
+func (m *WorkspaceInitializer) GetComposite() *CompositeInitializer {
+	if x, ok := m.GetSpec().(*WorkspaceInitializer_Composite); ok {
+		return x.Composite
+	}
+	return nil
+}
+
+func (m *WorkspaceInitializer) GetArchive() *ArchiveInitializer {
+	if x, ok := m.GetSpec().(*WorkspaceInitializer_Archive); ok {
+		return x.Archive
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*WorkspaceInitializer) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -201,6 +261,8 @@ func (*WorkspaceInitializer) XXX_OneofWrappers() []interface{} {
 		(*WorkspaceInitializer_Git)(nil),
 		(*WorkspaceInitializer_Snapshot)(nil),
 		(*WorkspaceInitializer_Prebuild)(nil),
+		(*WorkspaceInitializer_Composite)(nil),
+		(*WorkspaceInitializer_Archive)(nil),
 	}
 }
 
@@ -486,6 +548,155 @@ func (m *PrebuildInitializer) GetGit() *GitInitializer {
 	return nil
 }
 
+// A composite initializer layers the content of several initializers in one workspace, e.g. a Git clone,
+// a dependency archive on top and a user-specific snapshot on top of that. Layers are initialized in order.
+type CompositeInitializer struct {
+	Layers               []*CompositeInitializerLayer `protobuf:"bytes,1,rep,name=layers,proto3" json:"layers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *CompositeInitializer) Reset()         { *m = CompositeInitializer{} }
+func (m *CompositeInitializer) String() string { return proto.CompactTextString(m) }
+func (*CompositeInitializer) ProtoMessage()    {}
+func (*CompositeInitializer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fb6f168f5b28a3e9, []int{6}
+}
+
+func (m *CompositeInitializer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompositeInitializer.Unmarshal(m, b)
+}
+func (m *CompositeInitializer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompositeInitializer.Marshal(b, m, deterministic)
+}
+func (m *CompositeInitializer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompositeInitializer.Merge(m, src)
+}
+func (m *CompositeInitializer) XXX_Size() int {
+	return xxx_messageInfo_CompositeInitializer.Size(m)
+}
+func (m *CompositeInitializer) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompositeInitializer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompositeInitializer proto.InternalMessageInfo
+
+func (m *CompositeInitializer) GetLayers() []*CompositeInitializerLayer {
+	if m != nil {
+		return m.Layers
+	}
+	return nil
+}
+
+type CompositeInitializerLayer struct {
+	// initializer produces the content of this layer
+	Initializer *WorkspaceInitializer `protobuf:"bytes,1,opt,name=initializer,proto3" json:"initializer,omitempty"`
+	// target_location is a path relative to the workspace root in which the content of this layer is placed
+	TargetLocation string `protobuf:"bytes,2,opt,name=target_location,json=targetLocation,proto3" json:"target_location,omitempty"`
+	// conflict_policy decides what happens with files this layer shares with the layers before it
+	ConflictPolicy LayerConflictPolicy `protobuf:"varint,3,opt,name=conflict_policy,json=conflictPolicy,proto3,enum=contentservice.LayerConflictPolicy" json:"conflict_policy,omitempty"`
+	// optional layers can fail without failing the workspace initialization
+	Optional             bool     `protobuf:"varint,4,opt,name=optional,proto3" json:"optional,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompositeInitializerLayer) Reset()         { *m = CompositeInitializerLayer{} }
+func (m *CompositeInitializerLayer) String() string { return proto.CompactTextString(m) }
+func (*CompositeInitializerLayer) ProtoMessage()    {}
+func (*CompositeInitializerLayer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fb6f168f5b28a3e9, []int{7}
+}
+
+func (m *CompositeInitializerLayer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompositeInitializerLayer.Unmarshal(m, b)
+}
+func (m *CompositeInitializerLayer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompositeInitializerLayer.Marshal(b, m, deterministic)
+}
+func (m *CompositeInitializerLayer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompositeInitializerLayer.Merge(m, src)
+}
+func (m *CompositeInitializerLayer) XXX_Size() int {
+	return xxx_messageInfo_CompositeInitializerLayer.Size(m)
+}
+func (m *CompositeInitializerLayer) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompositeInitializerLayer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompositeInitializerLayer proto.InternalMessageInfo
+
+func (m *CompositeInitializerLayer) GetInitializer() *WorkspaceInitializer {
+	if m != nil {
+		return m.Initializer
+	}
+	return nil
+}
+
+func (m *CompositeInitializerLayer) GetTargetLocation() string {
+	if m != nil {
+		return m.TargetLocation
+	}
+	return ""
+}
+
+func (m *CompositeInitializerLayer) GetConflictPolicy() LayerConflictPolicy {
+	if m != nil {
+		return m.ConflictPolicy
+	}
+	return LayerConflictPolicy_OVERWRITE
+}
+
+func (m *CompositeInitializerLayer) GetOptional() bool {
+	if m != nil {
+		return m.Optional
+	}
+	return false
+}
+
+// An archive initializer downloads and extracts a tar archive, e.g. a cache of dependencies
+type ArchiveInitializer struct {
+	// url is where we download the (possibly gzip compressed) archive from using a GET request
+	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchiveInitializer) Reset()         { *m = ArchiveInitializer{} }
+func (m *ArchiveInitializer) String() string { return proto.CompactTextString(m) }
+func (*ArchiveInitializer) ProtoMessage()    {}
+func (*ArchiveInitializer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fb6f168f5b28a3e9, []int{8}
+}
+
+func (m *ArchiveInitializer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchiveInitializer.Unmarshal(m, b)
+}
+func (m *ArchiveInitializer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchiveInitializer.Marshal(b, m, deterministic)
+}
+func (m *ArchiveInitializer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveInitializer.Merge(m, src)
+}
+func (m *ArchiveInitializer) XXX_Size() int {
+	return xxx_messageInfo_ArchiveInitializer.Size(m)
+}
+func (m *ArchiveInitializer) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveInitializer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchiveInitializer proto.InternalMessageInfo
+
+func (m *ArchiveInitializer) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
 // GitStatus describes the current Git working copy status, akin to a combination of "git status" and "git branch"
 type GitStatus struct {
 	// branch is branch we're currently on
@@ -513,7 +724,7 @@ func (m *GitStatus) Reset()         { *m = GitStatus{} }
 func (m *GitStatus) String() string { return proto.CompactTextString(m) }
 func (*GitStatus) ProtoMessage()    {}
 func (*GitStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fb6f168f5b28a3e9, []int{6}
+	return fileDescriptor_fb6f168f5b28a3e9, []int{9}
 }
 
 func (m *GitStatus) XXX_Unmarshal(b []byte) error {
@@ -593,6 +804,7 @@ func (m *GitStatus) GetTotalUnpushedCommits() int64 {
 func init() {
 	proto.RegisterEnum("contentservice.CloneTargetMode", CloneTargetMode_name, CloneTargetMode_value)
 	proto.RegisterEnum("contentservice.GitAuthMethod", GitAuthMethod_name, GitAuthMethod_value)
+	proto.RegisterEnum("contentservice.LayerConflictPolicy", LayerConflictPolicy_name, LayerConflictPolicy_value)
 	proto.RegisterType((*WorkspaceInitializer)(nil), "contentservice.WorkspaceInitializer")
 	proto.RegisterType((*EmptyInitializer)(nil), "contentservice.EmptyInitializer")
 	proto.RegisterType((*GitInitializer)(nil), "contentservice.GitInitializer")
@@ -600,6 +812,9 @@ func init() {
 	proto.RegisterMapType((map[string]string)(nil), "contentservice.GitConfig.CustomConfigEntry")
 	proto.RegisterType((*SnapshotInitializer)(nil), "contentservice.SnapshotInitializer")
 	proto.RegisterType((*PrebuildInitializer)(nil), "contentservice.PrebuildInitializer")
+	proto.RegisterType((*CompositeInitializer)(nil), "contentservice.CompositeInitializer")
+	proto.RegisterType((*CompositeInitializerLayer)(nil), "contentservice.CompositeInitializerLayer")
+	proto.RegisterType((*ArchiveInitializer)(nil), "contentservice.ArchiveInitializer")
 	proto.RegisterType((*GitStatus)(nil), "contentservice.GitStatus")
 }
 
@@ -608,55 +823,68 @@ func init() {
 }
 
 var fileDescriptor_fb6f168f5b28a3e9 = []byte{
-	// 799 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xcd, 0x6e, 0xdb, 0x46,
-	0x10, 0xb6, 0x28, 0x5b, 0xb6, 0x46, 0xb6, 0x4c, 0xad, 0xdd, 0x40, 0x49, 0x91, 0xc6, 0x50, 0x0e,
-	0x75, 0x13, 0x44, 0xae, 0xd5, 0x1e, 0x82, 0x5e, 0x12, 0x59, 0x55, 0x6b, 0x03, 0x56, 0x64, 0xd0,
-	0x32, 0x0a, 0xe4, 0x42, 0xac, 0x57, 0x1b, 0x71, 0x21, 0x92, 0x4b, 0xec, 0x0e, 0x53, 0xb8, 0x4f,
-	0xd0, 0x73, 0x1f, 0xa1, 0x8f, 0xd2, 0x97, 0xe9, 0x6b, 0x14, 0x5c, 0xae, 0x7e, 0x48, 0xab, 0x40,
-	0x7b, 0xdb, 0xf9, 0xe6, 0xfb, 0x3e, 0xcd, 0x8c, 0x66, 0x24, 0x68, 0x89, 0x58, 0xa0, 0xa0, 0xa1,
-	0xf8, 0x8d, 0xab, 0x6e, 0xa2, 0x24, 0x4a, 0xd2, 0x64, 0x32, 0x46, 0x1e, 0xa3, 0xe6, 0xea, 0xb3,
-	0x60, 0xbc, 0xf3, 0x87, 0x03, 0xc7, 0xbf, 0x48, 0x35, 0xd7, 0x09, 0x65, 0xfc, 0x6a, 0x45, 0x27,
-	0x6f, 0x61, 0x87, 0x47, 0x09, 0x3e, 0xb4, 0x2b, 0x27, 0x95, 0xd3, 0x46, 0xef, 0xa4, 0x5b, 0x14,
-	0x76, 0x87, 0x59, 0x72, 0x4d, 0x70, 0xb9, 0xe5, 0xe5, 0x02, 0xd2, 0x83, 0xea, 0x4c, 0x60, 0xdb,
-	0x31, 0xba, 0xaf, 0xca, 0xba, 0x9f, 0x05, 0x16, 0x55, 0x19, 0x99, 0xf4, 0x61, 0x4f, 0xc7, 0x34,
-	0xd1, 0x81, 0xc4, 0x76, 0xd5, 0x08, 0x5f, 0x96, 0x85, 0xb7, 0x36, 0x5f, 0x54, 0x2f, 0x65, 0x99,
-	0x45, 0xa2, 0xf8, 0x7d, 0x2a, 0xc2, 0x69, 0x7b, 0x7b, 0xb3, 0xc5, 0x8d, 0xcd, 0x97, 0x2c, 0x16,
-	0xb2, 0x8b, 0x1a, 0x6c, 0xeb, 0x84, 0xb3, 0x0e, 0x01, 0xb7, 0xdc, 0x5e, 0xe7, 0x4f, 0x07, 0x9a,
-	0xc5, 0xda, 0xc9, 0x73, 0x00, 0xc5, 0x23, 0x89, 0xdc, 0x4f, 0x95, 0x30, 0x73, 0xaa, 0x7b, 0xf5,
-	0x1c, 0xb9, 0x53, 0x82, 0x74, 0xe1, 0x28, 0x4d, 0x34, 0x2a, 0x4e, 0x23, 0xdf, 0x5b, 0xf1, 0x1c,
-	0xc3, 0x6b, 0x2d, 0x52, 0xde, 0x92, 0xff, 0x1e, 0x1a, 0x48, 0xd5, 0x8c, 0xa3, 0x1f, 0xc9, 0x29,
-	0x37, 0x63, 0x68, 0xf6, 0x5e, 0x94, 0x7b, 0x18, 0x84, 0x32, 0xe6, 0x13, 0xc3, 0x1b, 0xc9, 0x29,
-	0xf7, 0x00, 0x97, 0x6f, 0xf2, 0x02, 0x1a, 0x2c, 0x4b, 0xfb, 0x48, 0x67, 0x1c, 0xcd, 0x14, 0xea,
-	0x1e, 0xb0, 0x5c, 0x31, 0xe3, 0x48, 0x5e, 0x43, 0x8b, 0x05, 0x9c, 0xcd, 0x65, 0x8a, 0x7e, 0x28,
-	0x19, 0x45, 0x21, 0xe3, 0xf6, 0x8e, 0xa1, 0xb9, 0x8b, 0xc4, 0xb5, 0xc5, 0xc9, 0x39, 0xd4, 0x98,
-	0x8c, 0x3f, 0x89, 0x59, 0xbb, 0x66, 0xc6, 0xf9, 0x74, 0xc3, 0x57, 0x39, 0x30, 0x04, 0xcf, 0x12,
-	0x3b, 0x7f, 0x39, 0x50, 0x5f, 0xa2, 0xe4, 0x06, 0x0e, 0x58, 0xaa, 0x51, 0x46, 0xbe, 0xf5, 0xa9,
-	0x9c, 0x54, 0x4f, 0x1b, 0xbd, 0xd7, 0xff, 0xea, 0xd3, 0x1d, 0x18, 0x7a, 0x1e, 0x0c, 0x63, 0x54,
-	0x0f, 0xde, 0x3e, 0x5b, 0x83, 0xc8, 0x10, 0x9a, 0x34, 0xc5, 0x80, 0xc7, 0x28, 0x6c, 0xf1, 0x8e,
-	0x99, 0xd2, 0xf3, 0x0d, 0x96, 0xfd, 0x14, 0x83, 0x11, 0xc7, 0x40, 0x4e, 0xbd, 0x92, 0x88, 0x7c,
-	0x09, 0xf5, 0x0c, 0xf1, 0x53, 0xcd, 0x95, 0x99, 0x73, 0xdd, 0xdb, 0xcb, 0x80, 0x3b, 0xcd, 0x15,
-	0x79, 0x09, 0x07, 0x26, 0x99, 0x50, 0xad, 0x7f, 0x95, 0x6a, 0x6a, 0xc7, 0xb8, 0x9f, 0x81, 0x37,
-	0x16, 0x23, 0x4f, 0xc1, 0x08, 0x7c, 0x89, 0xda, 0xce, 0x6f, 0x37, 0x8b, 0xc7, 0xa8, 0x9f, 0xbd,
-	0x83, 0xd6, 0xa3, 0x36, 0x88, 0x0b, 0xd5, 0x39, 0x7f, 0xb0, 0x3b, 0x92, 0x3d, 0xc9, 0x31, 0xec,
-	0x7c, 0xa6, 0x61, 0xca, 0xed, 0x3e, 0xe4, 0xc1, 0x0f, 0xce, 0xdb, 0x4a, 0xe7, 0x1c, 0x8e, 0x36,
-	0xec, 0x3a, 0x79, 0xb6, 0x76, 0x22, 0xb9, 0xcf, 0x32, 0xee, 0xfc, 0x5e, 0x81, 0xa3, 0x0d, 0xcb,
-	0x4d, 0xde, 0xad, 0xdd, 0x44, 0xe5, 0x3f, 0x9f, 0xd5, 0xea, 0x22, 0xc8, 0xb7, 0xff, 0xe3, 0x96,
-	0xcd, 0x25, 0x77, 0xfe, 0xce, 0x57, 0xe0, 0x16, 0x29, 0xa6, 0x9a, 0x3c, 0x81, 0xda, 0xbd, 0xa2,
-	0x31, 0x0b, 0x6c, 0xc9, 0x36, 0xca, 0x86, 0x1c, 0x52, 0xe4, 0x1a, 0x7d, 0x26, 0xa3, 0xc8, 0x7e,
-	0x42, 0xdd, 0xdb, 0xcf, 0xc1, 0x81, 0xc1, 0xc8, 0x37, 0xe0, 0xa6, 0x71, 0x9e, 0xe7, 0x53, 0xff,
-	0x93, 0x08, 0xb9, 0x6e, 0x57, 0x4f, 0xaa, 0xa7, 0x75, 0xef, 0x70, 0x85, 0xff, 0x94, 0xc1, 0xe4,
-	0x7b, 0x78, 0x82, 0x12, 0x69, 0xe8, 0x3f, 0x12, 0x64, 0xbb, 0x5b, 0xf5, 0x8e, 0x4d, 0xf6, 0xae,
-	0xa4, 0xfa, 0x1a, 0x0e, 0xd3, 0x18, 0x15, 0x65, 0xf3, 0x25, 0x7d, 0xdb, 0xf8, 0x37, 0x97, 0x70,
-	0x4e, 0xec, 0xc1, 0x17, 0x0b, 0xfb, 0x22, 0x7d, 0xd7, 0xb8, 0x1f, 0x59, 0xf7, 0x82, 0xc6, 0x54,
-	0x9f, 0xa4, 0x3a, 0xe0, 0x53, 0xdb, 0x64, 0xb6, 0x2a, 0xb6, 0xfa, 0x1c, 0xcf, 0xfb, 0x2c, 0x54,
-	0x5f, 0x12, 0xec, 0x15, 0xaa, 0x2f, 0xa8, 0x5e, 0x7d, 0x84, 0xc3, 0xd2, 0x8f, 0x01, 0x39, 0x84,
-	0x86, 0x37, 0x1c, 0x8d, 0x27, 0x43, 0xff, 0x72, 0xd8, 0xff, 0xd1, 0xdd, 0x22, 0x2d, 0x38, 0xb0,
-	0xc0, 0x60, 0x3c, 0x1a, 0x5d, 0x4d, 0xdc, 0xca, 0x1a, 0x74, 0xe1, 0xf5, 0x3f, 0x0c, 0x2e, 0x5d,
-	0x87, 0xb8, 0xb0, 0x7f, 0x3d, 0x1e, 0xf4, 0xaf, 0x17, 0x48, 0xf5, 0xd5, 0x7b, 0x38, 0x28, 0x9c,
-	0x10, 0x69, 0xc0, 0xee, 0x87, 0xb1, 0xdf, 0xbf, 0x9b, 0x5c, 0xba, 0x5b, 0xa4, 0x09, 0x70, 0xd1,
-	0xbf, 0xbd, 0x1a, 0xe4, 0x71, 0x85, 0x10, 0x68, 0xae, 0x62, 0x7f, 0x3c, 0xb9, 0x75, 0x9d, 0x8b,
-	0xf3, 0x8f, 0x67, 0x33, 0x81, 0x41, 0x7a, 0xdf, 0x65, 0x32, 0xca, 0x9e, 0x89, 0x9c, 0xbe, 0x11,
-	0xd2, 0xbe, 0xce, 0xec, 0x26, 0xbd, 0xb1, 0xab, 0x74, 0x46, 0x13, 0x71, 0x5f, 0x33, 0x7f, 0x51,
-	0xdf, 0xfd, 0x13, 0x00, 0x00, 0xff, 0xff, 0x0d, 0x8d, 0xa9, 0x11, 0xb7, 0x06, 0x00, 0x00,
+	// 998 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xd1, 0x6e, 0xdb, 0x36,
+	0x14, 0xad, 0xec, 0xc4, 0x89, 0xae, 0x13, 0x5b, 0x61, 0xb2, 0xc2, 0xe9, 0xd0, 0x35, 0x50, 0x87,
+	0x35, 0x4d, 0x51, 0x67, 0xc9, 0xf6, 0x50, 0xec, 0x61, 0xad, 0xe2, 0x2a, 0x8d, 0x31, 0x27, 0x0e,
+	0x18, 0x67, 0xdd, 0xfa, 0x22, 0x28, 0x34, 0x6b, 0x13, 0x91, 0x45, 0x81, 0xa2, 0x32, 0x64, 0x5f,
+	0xb0, 0x4f, 0xd8, 0xf3, 0x3e, 0x65, 0x3f, 0xb3, 0x3f, 0xd8, 0xf3, 0x20, 0x8a, 0x96, 0x2d, 0xd9,
+	0x05, 0xba, 0x37, 0xde, 0x73, 0xcf, 0x39, 0xa0, 0x2e, 0xef, 0xbd, 0x10, 0x6c, 0xb1, 0x90, 0x49,
+	0xe6, 0x07, 0xec, 0x77, 0x2a, 0xda, 0x91, 0xe0, 0x92, 0xa3, 0x06, 0xe1, 0xa1, 0xa4, 0xa1, 0x8c,
+	0xa9, 0xb8, 0x63, 0x84, 0xda, 0x7f, 0x56, 0x61, 0xe7, 0x3d, 0x17, 0xb7, 0x71, 0xe4, 0x13, 0xda,
+	0x9d, 0xd1, 0xd1, 0x2b, 0x58, 0xa5, 0x93, 0x48, 0xde, 0xb7, 0x8c, 0x3d, 0x63, 0xbf, 0x7e, 0xbc,
+	0xd7, 0x2e, 0x0a, 0xdb, 0x6e, 0x9a, 0x9c, 0x13, 0x9c, 0x3d, 0xc0, 0x99, 0x00, 0x1d, 0x43, 0x75,
+	0xc4, 0x64, 0xab, 0xa2, 0x74, 0x5f, 0x95, 0x75, 0xef, 0x98, 0x2c, 0xaa, 0x52, 0x32, 0x72, 0x60,
+	0x3d, 0x0e, 0xfd, 0x28, 0x1e, 0x73, 0xd9, 0xaa, 0x2a, 0xe1, 0xd3, 0xb2, 0xf0, 0x4a, 0xe7, 0x8b,
+	0xea, 0x5c, 0x96, 0x5a, 0x44, 0x82, 0xde, 0x24, 0x2c, 0x18, 0xb6, 0x56, 0x96, 0x5b, 0x5c, 0xea,
+	0x7c, 0xc9, 0x62, 0x2a, 0x43, 0x6f, 0xc1, 0x24, 0x7c, 0x12, 0xf1, 0x98, 0x49, 0xda, 0x5a, 0x55,
+	0x1e, 0x5f, 0x97, 0x3d, 0x3a, 0x53, 0x42, 0xd1, 0x64, 0x26, 0x44, 0x3f, 0xc2, 0x9a, 0x2f, 0xc8,
+	0x98, 0xdd, 0xd1, 0x56, 0x4d, 0x79, 0xd8, 0x65, 0x0f, 0x27, 0x4b, 0x17, 0x1d, 0xa6, 0xa2, 0x93,
+	0x1a, 0xac, 0xc4, 0x11, 0x25, 0x36, 0x02, 0xab, 0x5c, 0x64, 0xfb, 0xaf, 0x0a, 0x34, 0x8a, 0x15,
+	0x44, 0x8f, 0x01, 0x04, 0x9d, 0x70, 0x49, 0xbd, 0x44, 0x30, 0xf5, 0x5a, 0x26, 0x36, 0x33, 0xe4,
+	0x5a, 0x30, 0xd4, 0x86, 0xed, 0x24, 0x8a, 0xa5, 0xa0, 0xfe, 0xc4, 0xc3, 0x33, 0x5e, 0x45, 0xf1,
+	0xb6, 0xa6, 0x29, 0x9c, 0xf3, 0xdf, 0x40, 0x5d, 0xfa, 0x62, 0x44, 0xa5, 0x37, 0xe1, 0x43, 0xaa,
+	0x1e, 0xa3, 0x71, 0xfc, 0x64, 0xa1, 0x0a, 0x01, 0x0f, 0xe9, 0x40, 0xf1, 0xce, 0xf9, 0x90, 0x62,
+	0x90, 0xf9, 0x19, 0x3d, 0x81, 0x3a, 0x49, 0xd3, 0x9e, 0xf4, 0x47, 0x54, 0xaa, 0xb7, 0x30, 0x31,
+	0x90, 0x4c, 0x31, 0xa2, 0x12, 0xbd, 0x80, 0x2d, 0x32, 0xa6, 0xe4, 0x96, 0x27, 0xd2, 0x0b, 0x38,
+	0xf1, 0x25, 0xe3, 0xa1, 0x2a, 0xb7, 0x89, 0xad, 0x69, 0xa2, 0xa7, 0x71, 0x74, 0x04, 0x35, 0xc2,
+	0xc3, 0x8f, 0x6c, 0xa4, 0x8b, 0xb9, 0xbb, 0xa4, 0xa1, 0x3a, 0x8a, 0x80, 0x35, 0xd1, 0xfe, 0xbb,
+	0x02, 0x66, 0x8e, 0xa2, 0x4b, 0xd8, 0x24, 0x49, 0x2c, 0xf9, 0xc4, 0xd3, 0x3e, 0xc6, 0x5e, 0x75,
+	0xbf, 0x7e, 0xfc, 0xe2, 0x93, 0x3e, 0xed, 0x8e, 0xa2, 0x67, 0x81, 0x1b, 0x4a, 0x71, 0x8f, 0x37,
+	0xc8, 0x1c, 0x84, 0x5c, 0x68, 0xf8, 0x89, 0x1c, 0xd3, 0x50, 0x32, 0x7d, 0xf9, 0x8a, 0xaa, 0xd2,
+	0xe3, 0x25, 0x96, 0x4e, 0x22, 0xc7, 0xe7, 0x54, 0x8e, 0xf9, 0x10, 0x97, 0x44, 0xe8, 0x4b, 0x30,
+	0x53, 0xc4, 0x4b, 0x62, 0x2a, 0x54, 0x9d, 0x4d, 0xbc, 0x9e, 0x02, 0xd7, 0x31, 0x15, 0xe8, 0x29,
+	0x6c, 0xaa, 0x64, 0xe4, 0xc7, 0xf1, 0x6f, 0x5c, 0x0c, 0x75, 0x19, 0x37, 0x52, 0xf0, 0x52, 0x63,
+	0x68, 0x17, 0x94, 0xc0, 0xe3, 0x32, 0xd6, 0xf5, 0x5b, 0x4b, 0xe3, 0xbe, 0x8c, 0x1f, 0xbd, 0x86,
+	0xad, 0x85, 0xcf, 0x40, 0x16, 0x54, 0x6f, 0xe9, 0xbd, 0xee, 0x91, 0xf4, 0x88, 0x76, 0x60, 0xf5,
+	0xce, 0x0f, 0x12, 0xaa, 0xfb, 0x21, 0x0b, 0x7e, 0xa8, 0xbc, 0x32, 0xec, 0x23, 0xd8, 0x5e, 0x32,
+	0x71, 0xe8, 0xd1, 0xdc, 0xa0, 0x66, 0x3e, 0x79, 0x6c, 0xff, 0x61, 0xc0, 0xf6, 0x92, 0x11, 0x43,
+	0xaf, 0xe7, 0x26, 0xd3, 0xf8, 0xec, 0xe1, 0x9e, 0x9b, 0xcb, 0x6f, 0xff, 0xc7, 0x46, 0x51, 0xfb,
+	0xc4, 0xfe, 0x15, 0x76, 0x96, 0x0d, 0x2a, 0x72, 0xa0, 0x16, 0xf8, 0xf7, 0x54, 0xc4, 0xba, 0x0b,
+	0x9e, 0x7f, 0xce, 0x78, 0xf7, 0x52, 0x05, 0xd6, 0x42, 0xfb, 0x5f, 0x03, 0x76, 0x3f, 0xc9, 0x42,
+	0xa7, 0x50, 0x9f, 0x5b, 0xba, 0x2d, 0x63, 0xf9, 0x12, 0x59, 0xb6, 0x71, 0xf1, 0xbc, 0x10, 0x3d,
+	0x83, 0xa6, 0x1e, 0xc3, 0x7c, 0x42, 0xb2, 0x27, 0x6a, 0x64, 0x70, 0x3e, 0x1f, 0x3d, 0x68, 0xa6,
+	0x7d, 0x1d, 0x30, 0x22, 0xbd, 0x88, 0x07, 0x8c, 0xdc, 0xeb, 0x99, 0x5d, 0xa8, 0xb1, 0xba, 0x60,
+	0x47, 0x73, 0x2f, 0x15, 0x15, 0x37, 0x48, 0x21, 0x4e, 0x9f, 0x97, 0x47, 0xa9, 0xaf, 0x1f, 0xa8,
+	0x8e, 0x5b, 0xc7, 0x79, 0x6c, 0x7f, 0x03, 0x68, 0x71, 0x71, 0xa5, 0x3d, 0x95, 0x88, 0x60, 0xda,
+	0x53, 0x89, 0x08, 0xec, 0x7f, 0xb2, 0xf1, 0xbb, 0x92, 0xbe, 0x4c, 0x62, 0xf4, 0x10, 0x6a, 0x37,
+	0xc2, 0x0f, 0xc9, 0x58, 0x53, 0x74, 0x94, 0x36, 0x78, 0xe0, 0x4b, 0x1a, 0x4b, 0x8f, 0xf0, 0xc9,
+	0x44, 0xbf, 0xae, 0x89, 0x37, 0x32, 0xb0, 0xa3, 0x30, 0xf4, 0x1c, 0xac, 0x24, 0xcc, 0xf2, 0x74,
+	0xe8, 0x7d, 0x64, 0x01, 0x8d, 0x5b, 0xd5, 0xbd, 0xea, 0xbe, 0x89, 0x9b, 0x33, 0xfc, 0x34, 0x85,
+	0xd1, 0xf7, 0xf0, 0x50, 0x72, 0xe9, 0x07, 0xde, 0x82, 0x20, 0xdd, 0x1b, 0x55, 0xbc, 0xa3, 0xb2,
+	0xd7, 0x25, 0xd5, 0x33, 0x68, 0x26, 0xa1, 0x14, 0x3e, 0xb9, 0xcd, 0xe9, 0x2b, 0xca, 0xbf, 0x91,
+	0xc3, 0x19, 0xf1, 0x18, 0xbe, 0x98, 0xda, 0x17, 0xe9, 0x6b, 0xca, 0x7d, 0x5b, 0xbb, 0x17, 0x34,
+	0xea, 0xf6, 0x51, 0x12, 0x8f, 0xe9, 0x50, 0x7f, 0x64, 0x3a, 0xa6, 0xfa, 0xf6, 0x19, 0x9e, 0x7d,
+	0x67, 0xe1, 0xf6, 0x25, 0xc1, 0x7a, 0xe1, 0xf6, 0x05, 0xd5, 0xc1, 0x07, 0x68, 0x96, 0x16, 0x31,
+	0x6a, 0x42, 0x1d, 0xbb, 0xe7, 0xfd, 0x81, 0xeb, 0x9d, 0xb9, 0xce, 0x5b, 0xeb, 0x01, 0xda, 0x82,
+	0x4d, 0x0d, 0x74, 0xfa, 0xe7, 0xe7, 0xdd, 0x81, 0x65, 0xcc, 0x41, 0x27, 0xd8, 0xb9, 0xe8, 0x9c,
+	0x59, 0x15, 0x64, 0xc1, 0x46, 0xaf, 0xdf, 0x71, 0x7a, 0x53, 0xa4, 0x7a, 0xf0, 0x06, 0x36, 0x0b,
+	0xeb, 0x0b, 0xd5, 0x61, 0xed, 0xa2, 0xef, 0x39, 0xd7, 0x83, 0x33, 0xeb, 0x01, 0x6a, 0x00, 0x9c,
+	0x38, 0x57, 0xdd, 0x4e, 0x16, 0x1b, 0x08, 0x41, 0x63, 0x16, 0x7b, 0xfd, 0xc1, 0x95, 0x55, 0x39,
+	0x70, 0x60, 0x7b, 0x49, 0xcb, 0xa1, 0x4d, 0x30, 0xfb, 0x3f, 0xbb, 0xf8, 0x3d, 0xee, 0x0e, 0xdc,
+	0xec, 0x7e, 0x3f, 0xb9, 0xee, 0xa5, 0xe7, 0xfe, 0xd2, 0xbd, 0x1a, 0x74, 0x2f, 0xde, 0x59, 0x06,
+	0x5a, 0x87, 0x95, 0x53, 0xa7, 0xdb, 0xb3, 0x2a, 0x27, 0x47, 0x1f, 0x0e, 0x47, 0x4c, 0x8e, 0x93,
+	0x9b, 0x36, 0xe1, 0x93, 0xf4, 0x18, 0xf1, 0xe1, 0x4b, 0xc6, 0xf5, 0xe9, 0x50, 0x37, 0xf8, 0x4b,
+	0xdd, 0xe1, 0x87, 0x7e, 0xc4, 0x6e, 0x6a, 0xea, 0x3f, 0xe7, 0xbb, 0xff, 0x06, 0x00, 0xd0, 0xfd,
+	0x12, 0x43, 0xfc, 0x08, 0x00, 0x00,
 }
@@ -0,0 +1,660 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: prebuilds.proto
+
+package api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type RegisterPrebuildRequest struct {
+	// owner_id is the user whose bucket holds the prebuilds of the project
+	OwnerId   string `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	ProjectId string `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// branch is the branch the prebuild was built from
+	Branch string `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	// snapshot_name is the fully qualified name of the prebuild's snapshot, as returned by TakeSnapshot
+	SnapshotName         string   `protobuf:"bytes,4,opt,name=snapshot_name,json=snapshotName,proto3" json:"snapshot_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterPrebuildRequest) Reset()         { *m = RegisterPrebuildRequest{} }
+func (m *RegisterPrebuildRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterPrebuildRequest) ProtoMessage()    {}
+func (*RegisterPrebuildRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{0}
+}
+
+func (m *RegisterPrebuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterPrebuildRequest.Unmarshal(m, b)
+}
+func (m *RegisterPrebuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterPrebuildRequest.Marshal(b, m, deterministic)
+}
+func (m *RegisterPrebuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterPrebuildRequest.Merge(m, src)
+}
+func (m *RegisterPrebuildRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterPrebuildRequest.Size(m)
+}
+func (m *RegisterPrebuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterPrebuildRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterPrebuildRequest proto.InternalMessageInfo
+
+func (m *RegisterPrebuildRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *RegisterPrebuildRequest) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+func (m *RegisterPrebuildRequest) GetBranch() string {
+	if m != nil {
+		return m.Branch
+	}
+	return ""
+}
+
+func (m *RegisterPrebuildRequest) GetSnapshotName() string {
+	if m != nil {
+		return m.SnapshotName
+	}
+	return ""
+}
+
+type RegisterPrebuildResponse struct {
+	Usage *PrebuildStorageUsage `protobuf:"bytes,1,opt,name=usage,proto3" json:"usage,omitempty"`
+	// evicted_snapshots are the snapshots we deleted to bring the project back under its quota
+	EvictedSnapshots     []string `protobuf:"bytes,2,rep,name=evicted_snapshots,json=evictedSnapshots,proto3" json:"evicted_snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterPrebuildResponse) Reset()         { *m = RegisterPrebuildResponse{} }
+func (m *RegisterPrebuildResponse) String() string { return proto.CompactTextString(m) }
+func (*RegisterPrebuildResponse) ProtoMessage()    {}
+func (*RegisterPrebuildResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{1}
+}
+
+func (m *RegisterPrebuildResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterPrebuildResponse.Unmarshal(m, b)
+}
+func (m *RegisterPrebuildResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterPrebuildResponse.Marshal(b, m, deterministic)
+}
+func (m *RegisterPrebuildResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterPrebuildResponse.Merge(m, src)
+}
+func (m *RegisterPrebuildResponse) XXX_Size() int {
+	return xxx_messageInfo_RegisterPrebuildResponse.Size(m)
+}
+func (m *RegisterPrebuildResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterPrebuildResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterPrebuildResponse proto.InternalMessageInfo
+
+func (m *RegisterPrebuildResponse) GetUsage() *PrebuildStorageUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+func (m *RegisterPrebuildResponse) GetEvictedSnapshots() []string {
+	if m != nil {
+		return m.EvictedSnapshots
+	}
+	return nil
+}
+
+type TouchBranchRequest struct {
+	OwnerId              string   `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	ProjectId            string   `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Branch               string   `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TouchBranchRequest) Reset()         { *m = TouchBranchRequest{} }
+func (m *TouchBranchRequest) String() string { return proto.CompactTextString(m) }
+func (*TouchBranchRequest) ProtoMessage()    {}
+func (*TouchBranchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{2}
+}
+
+func (m *TouchBranchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TouchBranchRequest.Unmarshal(m, b)
+}
+func (m *TouchBranchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TouchBranchRequest.Marshal(b, m, deterministic)
+}
+func (m *TouchBranchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TouchBranchRequest.Merge(m, src)
+}
+func (m *TouchBranchRequest) XXX_Size() int {
+	return xxx_messageInfo_TouchBranchRequest.Size(m)
+}
+func (m *TouchBranchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TouchBranchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TouchBranchRequest proto.InternalMessageInfo
+
+func (m *TouchBranchRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *TouchBranchRequest) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+func (m *TouchBranchRequest) GetBranch() string {
+	if m != nil {
+		return m.Branch
+	}
+	return ""
+}
+
+type TouchBranchResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TouchBranchResponse) Reset()         { *m = TouchBranchResponse{} }
+func (m *TouchBranchResponse) String() string { return proto.CompactTextString(m) }
+func (*TouchBranchResponse) ProtoMessage()    {}
+func (*TouchBranchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{3}
+}
+
+func (m *TouchBranchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TouchBranchResponse.Unmarshal(m, b)
+}
+func (m *TouchBranchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TouchBranchResponse.Marshal(b, m, deterministic)
+}
+func (m *TouchBranchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TouchBranchResponse.Merge(m, src)
+}
+func (m *TouchBranchResponse) XXX_Size() int {
+	return xxx_messageInfo_TouchBranchResponse.Size(m)
+}
+func (m *TouchBranchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TouchBranchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TouchBranchResponse proto.InternalMessageInfo
+
+type GetPrebuildStorageUsageRequest struct {
+	OwnerId              string   `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	ProjectId            string   `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPrebuildStorageUsageRequest) Reset()         { *m = GetPrebuildStorageUsageRequest{} }
+func (m *GetPrebuildStorageUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPrebuildStorageUsageRequest) ProtoMessage()    {}
+func (*GetPrebuildStorageUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{4}
+}
+
+func (m *GetPrebuildStorageUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPrebuildStorageUsageRequest.Unmarshal(m, b)
+}
+func (m *GetPrebuildStorageUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPrebuildStorageUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPrebuildStorageUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPrebuildStorageUsageRequest.Merge(m, src)
+}
+func (m *GetPrebuildStorageUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPrebuildStorageUsageRequest.Size(m)
+}
+func (m *GetPrebuildStorageUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPrebuildStorageUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPrebuildStorageUsageRequest proto.InternalMessageInfo
+
+func (m *GetPrebuildStorageUsageRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *GetPrebuildStorageUsageRequest) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+type GetPrebuildStorageUsageResponse struct {
+	Usage                *PrebuildStorageUsage `protobuf:"bytes,1,opt,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *GetPrebuildStorageUsageResponse) Reset()         { *m = GetPrebuildStorageUsageResponse{} }
+func (m *GetPrebuildStorageUsageResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPrebuildStorageUsageResponse) ProtoMessage()    {}
+func (*GetPrebuildStorageUsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{5}
+}
+
+func (m *GetPrebuildStorageUsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPrebuildStorageUsageResponse.Unmarshal(m, b)
+}
+func (m *GetPrebuildStorageUsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPrebuildStorageUsageResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPrebuildStorageUsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPrebuildStorageUsageResponse.Merge(m, src)
+}
+func (m *GetPrebuildStorageUsageResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPrebuildStorageUsageResponse.Size(m)
+}
+func (m *GetPrebuildStorageUsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPrebuildStorageUsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPrebuildStorageUsageResponse proto.InternalMessageInfo
+
+func (m *GetPrebuildStorageUsageResponse) GetUsage() *PrebuildStorageUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+type PrebuildStorageUsage struct {
+	ProjectId string `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// quota_bytes is the storage the project's prebuilds may use. Zero means the storage is not limited.
+	QuotaBytes int64 `protobuf:"varint,2,opt,name=quota_bytes,json=quotaBytes,proto3" json:"quota_bytes,omitempty"`
+	// used_bytes is the storage the project's prebuild archives currently use
+	UsedBytes            int64                 `protobuf:"varint,3,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	Branches             []*BranchStorageUsage `protobuf:"bytes,4,rep,name=branches,proto3" json:"branches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *PrebuildStorageUsage) Reset()         { *m = PrebuildStorageUsage{} }
+func (m *PrebuildStorageUsage) String() string { return proto.CompactTextString(m) }
+func (*PrebuildStorageUsage) ProtoMessage()    {}
+func (*PrebuildStorageUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{6}
+}
+
+func (m *PrebuildStorageUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrebuildStorageUsage.Unmarshal(m, b)
+}
+func (m *PrebuildStorageUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrebuildStorageUsage.Marshal(b, m, deterministic)
+}
+func (m *PrebuildStorageUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrebuildStorageUsage.Merge(m, src)
+}
+func (m *PrebuildStorageUsage) XXX_Size() int {
+	return xxx_messageInfo_PrebuildStorageUsage.Size(m)
+}
+func (m *PrebuildStorageUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrebuildStorageUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrebuildStorageUsage proto.InternalMessageInfo
+
+func (m *PrebuildStorageUsage) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
+func (m *PrebuildStorageUsage) GetQuotaBytes() int64 {
+	if m != nil {
+		return m.QuotaBytes
+	}
+	return 0
+}
+
+func (m *PrebuildStorageUsage) GetUsedBytes() int64 {
+	if m != nil {
+		return m.UsedBytes
+	}
+	return 0
+}
+
+func (m *PrebuildStorageUsage) GetBranches() []*BranchStorageUsage {
+	if m != nil {
+		return m.Branches
+	}
+	return nil
+}
+
+type BranchStorageUsage struct {
+	Branch       string               `protobuf:"bytes,1,opt,name=branch,proto3" json:"branch,omitempty"`
+	UsedBytes    int64                `protobuf:"varint,2,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	LastActivity *timestamp.Timestamp `protobuf:"bytes,3,opt,name=last_activity,json=lastActivity,proto3" json:"last_activity,omitempty"`
+	// snapshots are the prebuild snapshots we keep for the branch, oldest first
+	Snapshots            []string `protobuf:"bytes,4,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BranchStorageUsage) Reset()         { *m = BranchStorageUsage{} }
+func (m *BranchStorageUsage) String() string { return proto.CompactTextString(m) }
+func (*BranchStorageUsage) ProtoMessage()    {}
+func (*BranchStorageUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e53c4d6b88b4b969, []int{7}
+}
+
+func (m *BranchStorageUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BranchStorageUsage.Unmarshal(m, b)
+}
+func (m *BranchStorageUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BranchStorageUsage.Marshal(b, m, deterministic)
+}
+func (m *BranchStorageUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BranchStorageUsage.Merge(m, src)
+}
+func (m *BranchStorageUsage) XXX_Size() int {
+	return xxx_messageInfo_BranchStorageUsage.Size(m)
+}
+func (m *BranchStorageUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_BranchStorageUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BranchStorageUsage proto.InternalMessageInfo
+
+func (m *BranchStorageUsage) GetBranch() string {
+	if m != nil {
+		return m.Branch
+	}
+	return ""
+}
+
+func (m *BranchStorageUsage) GetUsedBytes() int64 {
+	if m != nil {
+		return m.UsedBytes
+	}
+	return 0
+}
+
+func (m *BranchStorageUsage) GetLastActivity() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastActivity
+	}
+	return nil
+}
+
+func (m *BranchStorageUsage) GetSnapshots() []string {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RegisterPrebuildRequest)(nil), "contentservice.RegisterPrebuildRequest")
+	proto.RegisterType((*RegisterPrebuildResponse)(nil), "contentservice.RegisterPrebuildResponse")
+	proto.RegisterType((*TouchBranchRequest)(nil), "contentservice.TouchBranchRequest")
+	proto.RegisterType((*TouchBranchResponse)(nil), "contentservice.TouchBranchResponse")
+	proto.RegisterType((*GetPrebuildStorageUsageRequest)(nil), "contentservice.GetPrebuildStorageUsageRequest")
+	proto.RegisterType((*GetPrebuildStorageUsageResponse)(nil), "contentservice.GetPrebuildStorageUsageResponse")
+	proto.RegisterType((*PrebuildStorageUsage)(nil), "contentservice.PrebuildStorageUsage")
+	proto.RegisterType((*BranchStorageUsage)(nil), "contentservice.BranchStorageUsage")
+}
+
+func init() {
+	proto.RegisterFile("prebuilds.proto", fileDescriptor_e53c4d6b88b4b969)
+}
+
+var fileDescriptor_e53c4d6b88b4b969 = []byte{
+	// 534 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xc5, 0x49, 0x29, 0xcd, 0xa4, 0x85, 0xb2, 0x40, 0x6b, 0x2c, 0x20, 0x91, 0x8b, 0x44, 0x24,
+	0x54, 0x5b, 0x84, 0x1b, 0x07, 0x10, 0xb9, 0xa0, 0x5e, 0x10, 0x72, 0x8a, 0x84, 0x2a, 0x21, 0x6b,
+	0x6d, 0x4f, 0x9d, 0x45, 0xb1, 0xd7, 0xf5, 0xae, 0x83, 0x2a, 0x71, 0xe3, 0x07, 0xf8, 0x0e, 0xee,
+	0x7c, 0x0c, 0x7f, 0x83, 0xbc, 0x5e, 0xb7, 0xb5, 0xd3, 0x14, 0xa4, 0x8a, 0xdb, 0xee, 0xbc, 0x99,
+	0xd9, 0x37, 0x6f, 0xe7, 0xc1, 0x9d, 0x2c, 0xc7, 0xa0, 0x60, 0xf3, 0x48, 0x38, 0x59, 0xce, 0x25,
+	0x27, 0xb7, 0x43, 0x9e, 0x4a, 0x4c, 0xa5, 0xc0, 0x7c, 0xc1, 0x42, 0xb4, 0x06, 0x31, 0xe7, 0xf1,
+	0x1c, 0x5d, 0x85, 0x06, 0xc5, 0xb1, 0x2b, 0x59, 0x82, 0x42, 0xd2, 0x24, 0xab, 0x0a, 0xec, 0x1f,
+	0x06, 0xec, 0x7a, 0x18, 0x33, 0x21, 0x31, 0xff, 0xa0, 0x9b, 0x79, 0x78, 0x52, 0xa0, 0x90, 0xe4,
+	0x21, 0x6c, 0xf0, 0xaf, 0x29, 0xe6, 0x3e, 0x8b, 0x4c, 0x63, 0x68, 0x8c, 0x7a, 0xde, 0x2d, 0x75,
+	0x3f, 0x88, 0xc8, 0x63, 0x80, 0x2c, 0xe7, 0x5f, 0x30, 0x94, 0x25, 0xd8, 0x51, 0x60, 0x4f, 0x47,
+	0x0e, 0x22, 0xb2, 0x03, 0xeb, 0x41, 0x4e, 0xd3, 0x70, 0x66, 0x76, 0x15, 0xa4, 0x6f, 0x64, 0x0f,
+	0xb6, 0x44, 0x4a, 0x33, 0x31, 0xe3, 0xd2, 0x4f, 0x69, 0x82, 0xe6, 0x9a, 0x82, 0x37, 0xeb, 0xe0,
+	0x7b, 0x9a, 0xa0, 0xfd, 0xdd, 0x00, 0x73, 0x99, 0x92, 0xc8, 0x78, 0x2a, 0x90, 0xbc, 0x82, 0x9b,
+	0x85, 0xa0, 0x31, 0x2a, 0x42, 0xfd, 0xf1, 0x53, 0xa7, 0x39, 0xb0, 0x53, 0x17, 0x4c, 0x25, 0xcf,
+	0x69, 0x8c, 0x1f, 0xcb, 0x5c, 0xaf, 0x2a, 0x21, 0xcf, 0xe1, 0x2e, 0x2e, 0x58, 0x28, 0x31, 0xf2,
+	0xeb, 0x07, 0x85, 0xd9, 0x19, 0x76, 0x47, 0x3d, 0x6f, 0x5b, 0x03, 0xd3, 0x3a, 0x6e, 0x1f, 0x03,
+	0x39, 0xe4, 0x45, 0x38, 0x9b, 0x28, 0xe6, 0xff, 0x4d, 0x12, 0xfb, 0x01, 0xdc, 0x6b, 0xbc, 0x53,
+	0xcd, 0x69, 0x1f, 0xc1, 0x93, 0x77, 0x28, 0x2f, 0x9d, 0xe6, 0xba, 0x54, 0xec, 0xcf, 0x30, 0x58,
+	0xd9, 0xfb, 0xfa, 0x32, 0xdb, 0xbf, 0x0c, 0xb8, 0x7f, 0x19, 0xde, 0xa2, 0x65, 0xb4, 0x15, 0x1a,
+	0x40, 0xff, 0xa4, 0xe0, 0x92, 0xfa, 0xc1, 0xa9, 0x44, 0xa1, 0x68, 0x77, 0x3d, 0x50, 0xa1, 0x49,
+	0x19, 0x29, 0xeb, 0x0b, 0x81, 0x91, 0xc6, 0xbb, 0x0a, 0xef, 0x95, 0x91, 0x0a, 0x7e, 0x0d, 0x1b,
+	0x95, 0xa6, 0x28, 0xcc, 0xb5, 0x61, 0x77, 0xd4, 0x1f, 0xdb, 0x6d, 0xda, 0x95, 0xc8, 0x0d, 0xd2,
+	0x67, 0x35, 0xf6, 0x4f, 0x03, 0xc8, 0x72, 0xc2, 0x85, 0x8f, 0x33, 0x1a, 0xbb, 0xdc, 0x64, 0xd3,
+	0x69, 0xb3, 0x79, 0x03, 0x5b, 0x73, 0x2a, 0xa4, 0x4f, 0x43, 0xc9, 0x16, 0x4c, 0x9e, 0x2a, 0xbe,
+	0xfd, 0xb1, 0xe5, 0x54, 0x8e, 0x74, 0x6a, 0x47, 0x3a, 0x87, 0xb5, 0x23, 0xbd, 0xcd, 0xb2, 0xe0,
+	0xad, 0xce, 0x27, 0x8f, 0xa0, 0x77, 0xbe, 0xa5, 0x6b, 0x6a, 0x4b, 0xcf, 0x03, 0xe3, 0xdf, 0x1d,
+	0xd8, 0x69, 0x89, 0x3c, 0xad, 0x86, 0x24, 0x31, 0x6c, 0xb7, 0xed, 0x43, 0x9e, 0xb5, 0x95, 0x58,
+	0xe1, 0x79, 0x6b, 0xf4, 0xf7, 0x44, 0xbd, 0xa1, 0x37, 0xc8, 0x27, 0xe8, 0x5f, 0x58, 0x5d, 0xb2,
+	0xa4, 0xf6, 0xb2, 0x7f, 0xac, 0xbd, 0x2b, 0x73, 0xce, 0x3a, 0x7f, 0x83, 0xdd, 0x15, 0x1b, 0x4a,
+	0x9c, 0x76, 0x87, 0xab, 0x6d, 0x62, 0xb9, 0xff, 0x9c, 0x5f, 0xbf, 0x3e, 0x79, 0x71, 0xe4, 0xc6,
+	0x4c, 0xce, 0x8a, 0xc0, 0x09, 0x79, 0x52, 0x1e, 0x33, 0x1e, 0xed, 0x33, 0xae, 0x4f, 0xae, 0xee,
+	0xb7, 0xaf, 0x1b, 0xba, 0x34, 0x63, 0xc1, 0xba, 0xfa, 0xce, 0x97, 0x7f, 0x06, 0x00, 0xb4, 0x25,
+	0x29, 0xad, 0x91, 0x05, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PrebuildStorageServiceClient is the client API for PrebuildStorageService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PrebuildStorageServiceClient interface {
+	// RegisterPrebuild accounts a prebuild snapshot against the storage quota of its project. If the project
+	// exceeds its quota, we evict the archives of the branches which have seen the least activity first.
+	RegisterPrebuild(ctx context.Context, in *RegisterPrebuildRequest, opts ...grpc.CallOption) (*RegisterPrebuildResponse, error)
+	// TouchBranch records activity on a branch, e.g. a push or a workspace starting from one of its prebuilds.
+	// Archives of active branches are evicted last.
+	TouchBranch(ctx context.Context, in *TouchBranchRequest, opts ...grpc.CallOption) (*TouchBranchResponse, error)
+	// GetPrebuildStorageUsage reports how much storage the prebuilds of a project use
+	GetPrebuildStorageUsage(ctx context.Context, in *GetPrebuildStorageUsageRequest, opts ...grpc.CallOption) (*GetPrebuildStorageUsageResponse, error)
+}
+
+type prebuildStorageServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPrebuildStorageServiceClient(cc grpc.ClientConnInterface) PrebuildStorageServiceClient {
+	return &prebuildStorageServiceClient{cc}
+}
+
+func (c *prebuildStorageServiceClient) RegisterPrebuild(ctx context.Context, in *RegisterPrebuildRequest, opts ...grpc.CallOption) (*RegisterPrebuildResponse, error) {
+	out := new(RegisterPrebuildResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.PrebuildStorageService/RegisterPrebuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prebuildStorageServiceClient) TouchBranch(ctx context.Context, in *TouchBranchRequest, opts ...grpc.CallOption) (*TouchBranchResponse, error) {
+	out := new(TouchBranchResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.PrebuildStorageService/TouchBranch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prebuildStorageServiceClient) GetPrebuildStorageUsage(ctx context.Context, in *GetPrebuildStorageUsageRequest, opts ...grpc.CallOption) (*GetPrebuildStorageUsageResponse, error) {
+	out := new(GetPrebuildStorageUsageResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.PrebuildStorageService/GetPrebuildStorageUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrebuildStorageServiceServer is the server API for PrebuildStorageService service.
+type PrebuildStorageServiceServer interface {
+	// RegisterPrebuild accounts a prebuild snapshot against the storage quota of its project. If the project
+	// exceeds its quota, we evict the archives of the branches which have seen the least activity first.
+	RegisterPrebuild(context.Context, *RegisterPrebuildRequest) (*RegisterPrebuildResponse, error)
+	// TouchBranch records activity on a branch, e.g. a push or a workspace starting from one of its prebuilds.
+	// Archives of active branches are evicted last.
+	TouchBranch(context.Context, *TouchBranchRequest) (*TouchBranchResponse, error)
+	// GetPrebuildStorageUsage reports how much storage the prebuilds of a project use
+	GetPrebuildStorageUsage(context.Context, *GetPrebuildStorageUsageRequest) (*GetPrebuildStorageUsageResponse, error)
+}
+
+// UnimplementedPrebuildStorageServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPrebuildStorageServiceServer struct {
+}
+
+func (*UnimplementedPrebuildStorageServiceServer) RegisterPrebuild(ctx context.Context, req *RegisterPrebuildRequest) (*RegisterPrebuildResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterPrebuild not implemented")
+}
+func (*UnimplementedPrebuildStorageServiceServer) TouchBranch(ctx context.Context, req *TouchBranchRequest) (*TouchBranchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TouchBranch not implemented")
+}
+func (*UnimplementedPrebuildStorageServiceServer) GetPrebuildStorageUsage(ctx context.Context, req *GetPrebuildStorageUsageRequest) (*GetPrebuildStorageUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrebuildStorageUsage not implemented")
+}
+
+func RegisterPrebuildStorageServiceServer(s *grpc.Server, srv PrebuildStorageServiceServer) {
+	s.RegisterService(&_PrebuildStorageService_serviceDesc, srv)
+}
+
+func _PrebuildStorageService_RegisterPrebuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterPrebuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrebuildStorageServiceServer).RegisterPrebuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.PrebuildStorageService/RegisterPrebuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrebuildStorageServiceServer).RegisterPrebuild(ctx, req.(*RegisterPrebuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrebuildStorageService_TouchBranch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TouchBranchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrebuildStorageServiceServer).TouchBranch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.PrebuildStorageService/TouchBranch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrebuildStorageServiceServer).TouchBranch(ctx, req.(*TouchBranchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrebuildStorageService_GetPrebuildStorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrebuildStorageUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrebuildStorageServiceServer).GetPrebuildStorageUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.PrebuildStorageService/GetPrebuildStorageUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrebuildStorageServiceServer).GetPrebuildStorageUsage(ctx, req.(*GetPrebuildStorageUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PrebuildStorageService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "contentservice.PrebuildStorageService",
+	HandlerType: (*PrebuildStorageServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterPrebuild",
+			Handler:    _PrebuildStorageService_RegisterPrebuild_Handler,
+		},
+		{
+			MethodName: "TouchBranch",
+			Handler:    _PrebuildStorageService_TouchBranch_Handler,
+		},
+		{
+			MethodName: "GetPrebuildStorageUsage",
+			Handler:    _PrebuildStorageService_GetPrebuildStorageUsage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "prebuilds.proto",
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package api
+
+import (
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// SnapshotBundleManifestVersionV1 is the version of the snapshot bundle manifest format
+const SnapshotBundleManifestVersionV1 = 1
+
+// SnapshotBundleManifest describes a snapshot exported from a Gitpod installation.
+// It's the signed part of a SnapshotBundle.
+type SnapshotBundleManifest struct {
+	Version int `json:"version"`
+
+	// SnapshotName is the fully qualified name of the snapshot in the exporting installation
+	SnapshotName string `json:"snapshotName"`
+
+	// ExpiresAt is the time after which the object URLs are no longer valid
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Objects lists all storage objects which make up the snapshot. The first object is the snapshot itself.
+	// If the snapshot is a WorkspaceContentManifest the remaining objects are its layers.
+	Objects []SnapshotBundleObject `json:"objects"`
+}
+
+// SnapshotBundleObject describes a single storage object of an exported snapshot
+type SnapshotBundleObject struct {
+	// Bucket and Object locate the object in the exporting installation
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+
+	// URL is a signed URL the object can be downloaded from
+	URL string `json:"url"`
+
+	ContentType string        `json:"contentType,omitempty"`
+	Size        int64         `json:"size"`
+	Digest      digest.Digest `json:"digest"`
+}
@@ -0,0 +1,639 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: snapshots.proto
+
+package api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// SnapshotBundle is a portable description of a snapshot
+type SnapshotBundle struct {
+	// manifest is the JSON serialized SnapshotBundleManifest
+	Manifest []byte `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	// key_id identifies the key the manifest was signed with
+	KeyId string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// signature is the ed25519 signature of the manifest
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotBundle) Reset()         { *m = SnapshotBundle{} }
+func (m *SnapshotBundle) String() string { return proto.CompactTextString(m) }
+func (*SnapshotBundle) ProtoMessage()    {}
+func (*SnapshotBundle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{0}
+}
+
+func (m *SnapshotBundle) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotBundle.Unmarshal(m, b)
+}
+func (m *SnapshotBundle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotBundle.Marshal(b, m, deterministic)
+}
+func (m *SnapshotBundle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotBundle.Merge(m, src)
+}
+func (m *SnapshotBundle) XXX_Size() int {
+	return xxx_messageInfo_SnapshotBundle.Size(m)
+}
+func (m *SnapshotBundle) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotBundle.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotBundle proto.InternalMessageInfo
+
+func (m *SnapshotBundle) GetManifest() []byte {
+	if m != nil {
+		return m.Manifest
+	}
+	return nil
+}
+
+func (m *SnapshotBundle) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *SnapshotBundle) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ExportSnapshotRequest struct {
+	OwnerId string `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// snapshot_name is the fully qualified name of the snapshot, as returned by TakeSnapshot
+	SnapshotName         string   `protobuf:"bytes,2,opt,name=snapshot_name,json=snapshotName,proto3" json:"snapshot_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportSnapshotRequest) Reset()         { *m = ExportSnapshotRequest{} }
+func (m *ExportSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportSnapshotRequest) ProtoMessage()    {}
+func (*ExportSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{1}
+}
+
+func (m *ExportSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportSnapshotRequest.Unmarshal(m, b)
+}
+func (m *ExportSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportSnapshotRequest.Merge(m, src)
+}
+func (m *ExportSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportSnapshotRequest.Size(m)
+}
+func (m *ExportSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportSnapshotRequest proto.InternalMessageInfo
+
+func (m *ExportSnapshotRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *ExportSnapshotRequest) GetSnapshotName() string {
+	if m != nil {
+		return m.SnapshotName
+	}
+	return ""
+}
+
+type ExportSnapshotResponse struct {
+	Bundle               *SnapshotBundle `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ExportSnapshotResponse) Reset()         { *m = ExportSnapshotResponse{} }
+func (m *ExportSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportSnapshotResponse) ProtoMessage()    {}
+func (*ExportSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{2}
+}
+
+func (m *ExportSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportSnapshotResponse.Unmarshal(m, b)
+}
+func (m *ExportSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportSnapshotResponse.Merge(m, src)
+}
+func (m *ExportSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportSnapshotResponse.Size(m)
+}
+func (m *ExportSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportSnapshotResponse proto.InternalMessageInfo
+
+func (m *ExportSnapshotResponse) GetBundle() *SnapshotBundle {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type ImportSnapshotRequest struct {
+	// owner_id is the user who owns the imported snapshot in this installation
+	OwnerId string `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// workspace_id is the workspace the imported snapshot is stored for
+	WorkspaceId          string          `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	Bundle               *SnapshotBundle `protobuf:"bytes,3,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ImportSnapshotRequest) Reset()         { *m = ImportSnapshotRequest{} }
+func (m *ImportSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportSnapshotRequest) ProtoMessage()    {}
+func (*ImportSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{3}
+}
+
+func (m *ImportSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportSnapshotRequest.Unmarshal(m, b)
+}
+func (m *ImportSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportSnapshotRequest.Merge(m, src)
+}
+func (m *ImportSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportSnapshotRequest.Size(m)
+}
+func (m *ImportSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportSnapshotRequest proto.InternalMessageInfo
+
+func (m *ImportSnapshotRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *ImportSnapshotRequest) GetWorkspaceId() string {
+	if m != nil {
+		return m.WorkspaceId
+	}
+	return ""
+}
+
+func (m *ImportSnapshotRequest) GetBundle() *SnapshotBundle {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type ImportSnapshotResponse struct {
+	// snapshot_name is the fully qualified name of the imported snapshot in this installation
+	SnapshotName         string   `protobuf:"bytes,1,opt,name=snapshot_name,json=snapshotName,proto3" json:"snapshot_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportSnapshotResponse) Reset()         { *m = ImportSnapshotResponse{} }
+func (m *ImportSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportSnapshotResponse) ProtoMessage()    {}
+func (*ImportSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{4}
+}
+
+func (m *ImportSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportSnapshotResponse.Unmarshal(m, b)
+}
+func (m *ImportSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *ImportSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportSnapshotResponse.Merge(m, src)
+}
+func (m *ImportSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportSnapshotResponse.Size(m)
+}
+func (m *ImportSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportSnapshotResponse proto.InternalMessageInfo
+
+func (m *ImportSnapshotResponse) GetSnapshotName() string {
+	if m != nil {
+		return m.SnapshotName
+	}
+	return ""
+}
+
+type PrestageSnapshotRequest struct {
+	OwnerId string `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// snapshot_name is the fully qualified name of the snapshot, as returned by TakeSnapshot
+	SnapshotName string `protobuf:"bytes,2,opt,name=snapshot_name,json=snapshotName,proto3" json:"snapshot_name,omitempty"`
+	// nodes are the hosts whose ws-daemon we stage the snapshot on
+	Nodes                []string `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrestageSnapshotRequest) Reset()         { *m = PrestageSnapshotRequest{} }
+func (m *PrestageSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*PrestageSnapshotRequest) ProtoMessage()    {}
+func (*PrestageSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{5}
+}
+
+func (m *PrestageSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrestageSnapshotRequest.Unmarshal(m, b)
+}
+func (m *PrestageSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrestageSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *PrestageSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrestageSnapshotRequest.Merge(m, src)
+}
+func (m *PrestageSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_PrestageSnapshotRequest.Size(m)
+}
+func (m *PrestageSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrestageSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrestageSnapshotRequest proto.InternalMessageInfo
+
+func (m *PrestageSnapshotRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *PrestageSnapshotRequest) GetSnapshotName() string {
+	if m != nil {
+		return m.SnapshotName
+	}
+	return ""
+}
+
+func (m *PrestageSnapshotRequest) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type PrestageSnapshotResponse struct {
+	// results has one entry per requested node, in the order of the request
+	Results              []*PrestageSnapshotResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *PrestageSnapshotResponse) Reset()         { *m = PrestageSnapshotResponse{} }
+func (m *PrestageSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*PrestageSnapshotResponse) ProtoMessage()    {}
+func (*PrestageSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{6}
+}
+
+func (m *PrestageSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrestageSnapshotResponse.Unmarshal(m, b)
+}
+func (m *PrestageSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrestageSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *PrestageSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrestageSnapshotResponse.Merge(m, src)
+}
+func (m *PrestageSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_PrestageSnapshotResponse.Size(m)
+}
+func (m *PrestageSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrestageSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrestageSnapshotResponse proto.InternalMessageInfo
+
+func (m *PrestageSnapshotResponse) GetResults() []*PrestageSnapshotResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type PrestageSnapshotResult struct {
+	Node string `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	// already_staged is true if the node had the snapshot staged before
+	AlreadyStaged bool `protobuf:"varint,2,opt,name=already_staged,json=alreadyStaged,proto3" json:"already_staged,omitempty"`
+	// error describes why staging failed on this node. Empty if staging succeeded.
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrestageSnapshotResult) Reset()         { *m = PrestageSnapshotResult{} }
+func (m *PrestageSnapshotResult) String() string { return proto.CompactTextString(m) }
+func (*PrestageSnapshotResult) ProtoMessage()    {}
+func (*PrestageSnapshotResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60008e53df516755, []int{7}
+}
+
+func (m *PrestageSnapshotResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrestageSnapshotResult.Unmarshal(m, b)
+}
+func (m *PrestageSnapshotResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrestageSnapshotResult.Marshal(b, m, deterministic)
+}
+func (m *PrestageSnapshotResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrestageSnapshotResult.Merge(m, src)
+}
+func (m *PrestageSnapshotResult) XXX_Size() int {
+	return xxx_messageInfo_PrestageSnapshotResult.Size(m)
+}
+func (m *PrestageSnapshotResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrestageSnapshotResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrestageSnapshotResult proto.InternalMessageInfo
+
+func (m *PrestageSnapshotResult) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *PrestageSnapshotResult) GetAlreadyStaged() bool {
+	if m != nil {
+		return m.AlreadyStaged
+	}
+	return false
+}
+
+func (m *PrestageSnapshotResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*SnapshotBundle)(nil), "contentservice.SnapshotBundle")
+	proto.RegisterType((*ExportSnapshotRequest)(nil), "contentservice.ExportSnapshotRequest")
+	proto.RegisterType((*ExportSnapshotResponse)(nil), "contentservice.ExportSnapshotResponse")
+	proto.RegisterType((*ImportSnapshotRequest)(nil), "contentservice.ImportSnapshotRequest")
+	proto.RegisterType((*ImportSnapshotResponse)(nil), "contentservice.ImportSnapshotResponse")
+	proto.RegisterType((*PrestageSnapshotRequest)(nil), "contentservice.PrestageSnapshotRequest")
+	proto.RegisterType((*PrestageSnapshotResponse)(nil), "contentservice.PrestageSnapshotResponse")
+	proto.RegisterType((*PrestageSnapshotResult)(nil), "contentservice.PrestageSnapshotResult")
+}
+
+func init() {
+	proto.RegisterFile("snapshots.proto", fileDescriptor_60008e53df516755)
+}
+
+var fileDescriptor_60008e53df516755 = []byte{
+	// 467 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x5f, 0x6b, 0x13, 0x41,
+	0x10, 0xef, 0xf5, 0x6c, 0x9a, 0x4c, 0xd2, 0x54, 0x16, 0x13, 0xcf, 0x22, 0x12, 0x4f, 0x5a, 0xf3,
+	0xd2, 0x04, 0x23, 0xf8, 0x26, 0x48, 0xc1, 0x87, 0x7b, 0x91, 0x72, 0x79, 0x10, 0x44, 0x08, 0x9b,
+	0xdc, 0x98, 0x2e, 0xc9, 0xee, 0x9e, 0xbb, 0x7b, 0xd6, 0x7c, 0x08, 0xbf, 0x93, 0x1f, 0x4d, 0x6e,
+	0x6f, 0xaf, 0x35, 0xd7, 0xc3, 0x58, 0xe8, 0xdb, 0xcd, 0x30, 0xf3, 0xfb, 0xb7, 0xc3, 0xc1, 0xb1,
+	0x16, 0x34, 0xd5, 0x57, 0xd2, 0xe8, 0x51, 0xaa, 0xa4, 0x91, 0xa4, 0xbb, 0x90, 0xc2, 0xa0, 0x30,
+	0x1a, 0xd5, 0x0f, 0xb6, 0xc0, 0x90, 0x42, 0x77, 0xea, 0x46, 0x2e, 0x32, 0x91, 0xac, 0x91, 0x9c,
+	0x40, 0x93, 0x53, 0xc1, 0xbe, 0xa1, 0x36, 0x81, 0x37, 0xf0, 0x86, 0x9d, 0xf8, 0xa6, 0x26, 0x3d,
+	0x68, 0xac, 0x70, 0x33, 0x63, 0x49, 0xb0, 0x3f, 0xf0, 0x86, 0xad, 0xf8, 0x60, 0x85, 0x9b, 0x28,
+	0x21, 0xcf, 0xa1, 0xa5, 0xd9, 0x52, 0x50, 0x93, 0x29, 0x0c, 0x7c, 0xbb, 0x73, 0xdb, 0x08, 0x3f,
+	0x43, 0xef, 0xe3, 0xcf, 0x54, 0x2a, 0x53, 0x12, 0xc5, 0xf8, 0x3d, 0xcb, 0xd1, 0x9e, 0x41, 0x53,
+	0x5e, 0x0b, 0x54, 0x39, 0x9e, 0x67, 0xf1, 0x0e, 0x6d, 0x1d, 0x25, 0xe4, 0x15, 0x1c, 0x95, 0xca,
+	0x67, 0x82, 0x72, 0x74, 0x7c, 0x9d, 0xb2, 0xf9, 0x89, 0x72, 0x0c, 0x2f, 0xa1, 0x5f, 0x05, 0xd6,
+	0xa9, 0x14, 0x1a, 0xc9, 0x3b, 0x68, 0xcc, 0xad, 0x1b, 0x8b, 0xdb, 0x9e, 0xbc, 0x18, 0x6d, 0xdb,
+	0x1e, 0x6d, 0x7b, 0x8e, 0xdd, 0x74, 0xf8, 0xcb, 0x83, 0x5e, 0xc4, 0xef, 0xa9, 0xf5, 0x25, 0x74,
+	0xae, 0xa5, 0x5a, 0xe9, 0x94, 0x2e, 0xf0, 0x36, 0x9a, 0xf6, 0x4d, 0x2f, 0x4a, 0xfe, 0xd2, 0xe3,
+	0xdf, 0x4b, 0xcf, 0x7b, 0xe8, 0x47, 0xbc, 0xd6, 0xe1, 0x9d, 0x80, 0xbc, 0x9a, 0x80, 0x24, 0x3c,
+	0xbd, 0x54, 0xa8, 0x0d, 0x5d, 0xe2, 0x03, 0x67, 0x4f, 0x9e, 0xc0, 0x81, 0x90, 0x09, 0xea, 0xc0,
+	0x1f, 0xf8, 0xf9, 0x21, 0xd8, 0x22, 0xfc, 0x0a, 0xc1, 0x5d, 0x42, 0xa7, 0xf8, 0x03, 0x1c, 0x2a,
+	0xd4, 0xd9, 0xda, 0xe8, 0xc0, 0x1b, 0xf8, 0xc3, 0xf6, 0xe4, 0xac, 0x1a, 0x42, 0xcd, 0x6a, 0xb6,
+	0x36, 0x71, 0xb9, 0x16, 0x32, 0xe8, 0xd7, 0x8f, 0x10, 0x02, 0x8f, 0x72, 0x01, 0xce, 0x89, 0xfd,
+	0x26, 0xa7, 0xd0, 0xa5, 0x6b, 0x85, 0x34, 0xd9, 0xcc, 0xec, 0x4a, 0xf1, 0x30, 0xcd, 0xf8, 0xc8,
+	0x75, 0xa7, 0xb6, 0x99, 0x1b, 0x41, 0xa5, 0xa4, 0xb2, 0x2f, 0xd3, 0x8a, 0x8b, 0x62, 0xf2, 0x7b,
+	0x1f, 0x8e, 0x4b, 0x8e, 0x69, 0x21, 0x8f, 0x50, 0xe8, 0x6e, 0x9f, 0x1b, 0x39, 0xad, 0x3a, 0xa8,
+	0xbd, 0xf3, 0x93, 0xb3, 0x5d, 0x63, 0x45, 0x42, 0xe1, 0x5e, 0x4e, 0x11, 0xf1, 0x7f, 0x53, 0x44,
+	0xfc, 0xbf, 0x28, 0xea, 0xcf, 0x26, 0xdc, 0x23, 0x4b, 0x78, 0x5c, 0x0d, 0x91, 0xbc, 0xde, 0xfd,
+	0x12, 0x05, 0xcd, 0x70, 0xf7, 0x60, 0x49, 0x74, 0xf1, 0xe6, 0xcb, 0x78, 0xc9, 0xcc, 0x55, 0x36,
+	0x1f, 0x2d, 0x24, 0xcf, 0x3f, 0x53, 0x99, 0x9c, 0x33, 0xe9, 0xbe, 0xc6, 0x0e, 0xe8, 0xdc, 0x21,
+	0x8d, 0x69, 0xca, 0xe6, 0x0d, 0xfb, 0x8f, 0x7a, 0xfb, 0x67, 0x00, 0x04, 0x71, 0x37, 0x9b, 0xb6,
+	0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SnapshotServiceClient is the client API for SnapshotService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SnapshotServiceClient interface {
+	// ExportSnapshot produces a signed bundle which another Gitpod installation can import the snapshot from.
+	// The object URLs contained in the bundle are only valid for a limited time.
+	ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*ExportSnapshotResponse, error)
+	// ImportSnapshot copies the snapshot described by a bundle into this installation's storage.
+	// Only bundles signed by a trusted key are imported.
+	ImportSnapshot(ctx context.Context, in *ImportSnapshotRequest, opts ...grpc.CallOption) (*ImportSnapshotResponse, error)
+	// PrestageSnapshot pushes a snapshot, e.g. of a prebuild, onto the content caches of a set of nodes ahead of
+	// the workspace starts we expect. Workspaces starting on those nodes need not download the snapshot.
+	PrestageSnapshot(ctx context.Context, in *PrestageSnapshotRequest, opts ...grpc.CallOption) (*PrestageSnapshotResponse, error)
+}
+
+type snapshotServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSnapshotServiceClient(cc grpc.ClientConnInterface) SnapshotServiceClient {
+	return &snapshotServiceClient{cc}
+}
+
+func (c *snapshotServiceClient) ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*ExportSnapshotResponse, error) {
+	out := new(ExportSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.SnapshotService/ExportSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snapshotServiceClient) ImportSnapshot(ctx context.Context, in *ImportSnapshotRequest, opts ...grpc.CallOption) (*ImportSnapshotResponse, error) {
+	out := new(ImportSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.SnapshotService/ImportSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snapshotServiceClient) PrestageSnapshot(ctx context.Context, in *PrestageSnapshotRequest, opts ...grpc.CallOption) (*PrestageSnapshotResponse, error) {
+	out := new(PrestageSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.SnapshotService/PrestageSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SnapshotServiceServer is the server API for SnapshotService service.
+type SnapshotServiceServer interface {
+	// ExportSnapshot produces a signed bundle which another Gitpod installation can import the snapshot from.
+	// The object URLs contained in the bundle are only valid for a limited time.
+	ExportSnapshot(context.Context, *ExportSnapshotRequest) (*ExportSnapshotResponse, error)
+	// ImportSnapshot copies the snapshot described by a bundle into this installation's storage.
+	// Only bundles signed by a trusted key are imported.
+	ImportSnapshot(context.Context, *ImportSnapshotRequest) (*ImportSnapshotResponse, error)
+	// PrestageSnapshot pushes a snapshot, e.g. of a prebuild, onto the content caches of a set of nodes ahead of
+	// the workspace starts we expect. Workspaces starting on those nodes need not download the snapshot.
+	PrestageSnapshot(context.Context, *PrestageSnapshotRequest) (*PrestageSnapshotResponse, error)
+}
+
+// UnimplementedSnapshotServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedSnapshotServiceServer struct {
+}
+
+func (*UnimplementedSnapshotServiceServer) ExportSnapshot(ctx context.Context, req *ExportSnapshotRequest) (*ExportSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSnapshot not implemented")
+}
+func (*UnimplementedSnapshotServiceServer) ImportSnapshot(ctx context.Context, req *ImportSnapshotRequest) (*ImportSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportSnapshot not implemented")
+}
+func (*UnimplementedSnapshotServiceServer) PrestageSnapshot(ctx context.Context, req *PrestageSnapshotRequest) (*PrestageSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrestageSnapshot not implemented")
+}
+
+func RegisterSnapshotServiceServer(s *grpc.Server, srv SnapshotServiceServer) {
+	s.RegisterService(&_SnapshotService_serviceDesc, srv)
+}
+
+func _SnapshotService_ExportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnapshotServiceServer).ExportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.SnapshotService/ExportSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnapshotServiceServer).ExportSnapshot(ctx, req.(*ExportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SnapshotService_ImportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnapshotServiceServer).ImportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.SnapshotService/ImportSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnapshotServiceServer).ImportSnapshot(ctx, req.(*ImportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SnapshotService_PrestageSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrestageSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnapshotServiceServer).PrestageSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.SnapshotService/PrestageSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnapshotServiceServer).PrestageSnapshot(ctx, req.(*PrestageSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SnapshotService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "contentservice.SnapshotService",
+	HandlerType: (*SnapshotServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExportSnapshot",
+			Handler:    _SnapshotService_ExportSnapshot_Handler,
+		},
+		{
+			MethodName: "ImportSnapshot",
+			Handler:    _SnapshotService_ImportSnapshot_Handler,
+		},
+		{
+			MethodName: "PrestageSnapshot",
+			Handler:    _SnapshotService_PrestageSnapshot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "snapshots.proto",
+}
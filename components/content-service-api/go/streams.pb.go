@@ -0,0 +1,582 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: streams.proto
+
+package api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type AppendBlobRequest struct {
+	OwnerId string `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// offset is the size of the blob before this append, i.e. the offset data starts at
+	Offset               int64    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data                 []byte   `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppendBlobRequest) Reset()         { *m = AppendBlobRequest{} }
+func (m *AppendBlobRequest) String() string { return proto.CompactTextString(m) }
+func (*AppendBlobRequest) ProtoMessage()    {}
+func (*AppendBlobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6bbf8af0ec331d6, []int{0}
+}
+
+func (m *AppendBlobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendBlobRequest.Unmarshal(m, b)
+}
+func (m *AppendBlobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendBlobRequest.Marshal(b, m, deterministic)
+}
+func (m *AppendBlobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendBlobRequest.Merge(m, src)
+}
+func (m *AppendBlobRequest) XXX_Size() int {
+	return xxx_messageInfo_AppendBlobRequest.Size(m)
+}
+func (m *AppendBlobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendBlobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendBlobRequest proto.InternalMessageInfo
+
+func (m *AppendBlobRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *AppendBlobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AppendBlobRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *AppendBlobRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type AppendBlobResponse struct {
+	// size is the size of the blob after the append
+	Size                 int64    `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppendBlobResponse) Reset()         { *m = AppendBlobResponse{} }
+func (m *AppendBlobResponse) String() string { return proto.CompactTextString(m) }
+func (*AppendBlobResponse) ProtoMessage()    {}
+func (*AppendBlobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6bbf8af0ec331d6, []int{1}
+}
+
+func (m *AppendBlobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendBlobResponse.Unmarshal(m, b)
+}
+func (m *AppendBlobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendBlobResponse.Marshal(b, m, deterministic)
+}
+func (m *AppendBlobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendBlobResponse.Merge(m, src)
+}
+func (m *AppendBlobResponse) XXX_Size() int {
+	return xxx_messageInfo_AppendBlobResponse.Size(m)
+}
+func (m *AppendBlobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendBlobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendBlobResponse proto.InternalMessageInfo
+
+func (m *AppendBlobResponse) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type FinalizeBlobRequest struct {
+	OwnerId string `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// size is the size the writer expects the blob to have. Finalize fails if appends are missing.
+	Size int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// content_type is the content type of the finalized blob
+	ContentType          string   `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FinalizeBlobRequest) Reset()         { *m = FinalizeBlobRequest{} }
+func (m *FinalizeBlobRequest) String() string { return proto.CompactTextString(m) }
+func (*FinalizeBlobRequest) ProtoMessage()    {}
+func (*FinalizeBlobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6bbf8af0ec331d6, []int{2}
+}
+
+func (m *FinalizeBlobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FinalizeBlobRequest.Unmarshal(m, b)
+}
+func (m *FinalizeBlobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FinalizeBlobRequest.Marshal(b, m, deterministic)
+}
+func (m *FinalizeBlobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FinalizeBlobRequest.Merge(m, src)
+}
+func (m *FinalizeBlobRequest) XXX_Size() int {
+	return xxx_messageInfo_FinalizeBlobRequest.Size(m)
+}
+func (m *FinalizeBlobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FinalizeBlobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FinalizeBlobRequest proto.InternalMessageInfo
+
+func (m *FinalizeBlobRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *FinalizeBlobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FinalizeBlobRequest) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *FinalizeBlobRequest) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+type FinalizeBlobResponse struct {
+	Size                 int64    `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FinalizeBlobResponse) Reset()         { *m = FinalizeBlobResponse{} }
+func (m *FinalizeBlobResponse) String() string { return proto.CompactTextString(m) }
+func (*FinalizeBlobResponse) ProtoMessage()    {}
+func (*FinalizeBlobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6bbf8af0ec331d6, []int{3}
+}
+
+func (m *FinalizeBlobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FinalizeBlobResponse.Unmarshal(m, b)
+}
+func (m *FinalizeBlobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FinalizeBlobResponse.Marshal(b, m, deterministic)
+}
+func (m *FinalizeBlobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FinalizeBlobResponse.Merge(m, src)
+}
+func (m *FinalizeBlobResponse) XXX_Size() int {
+	return xxx_messageInfo_FinalizeBlobResponse.Size(m)
+}
+func (m *FinalizeBlobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FinalizeBlobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FinalizeBlobResponse proto.InternalMessageInfo
+
+func (m *FinalizeBlobResponse) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type TailBlobRequest struct {
+	OwnerId string `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// offset is where to start reading, e.g. to resume after a dropped connection
+	Offset               int64    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailBlobRequest) Reset()         { *m = TailBlobRequest{} }
+func (m *TailBlobRequest) String() string { return proto.CompactTextString(m) }
+func (*TailBlobRequest) ProtoMessage()    {}
+func (*TailBlobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6bbf8af0ec331d6, []int{4}
+}
+
+func (m *TailBlobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailBlobRequest.Unmarshal(m, b)
+}
+func (m *TailBlobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailBlobRequest.Marshal(b, m, deterministic)
+}
+func (m *TailBlobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailBlobRequest.Merge(m, src)
+}
+func (m *TailBlobRequest) XXX_Size() int {
+	return xxx_messageInfo_TailBlobRequest.Size(m)
+}
+func (m *TailBlobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailBlobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailBlobRequest proto.InternalMessageInfo
+
+func (m *TailBlobRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *TailBlobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TailBlobRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type TailBlobResponse struct {
+	// offset is the offset of data in the blob
+	Offset int64  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data   []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// finalized is set on the last message of the stream once the blob is complete. offset is its size then.
+	Finalized            bool     `protobuf:"varint,3,opt,name=finalized,proto3" json:"finalized,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailBlobResponse) Reset()         { *m = TailBlobResponse{} }
+func (m *TailBlobResponse) String() string { return proto.CompactTextString(m) }
+func (*TailBlobResponse) ProtoMessage()    {}
+func (*TailBlobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6bbf8af0ec331d6, []int{5}
+}
+
+func (m *TailBlobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailBlobResponse.Unmarshal(m, b)
+}
+func (m *TailBlobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailBlobResponse.Marshal(b, m, deterministic)
+}
+func (m *TailBlobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailBlobResponse.Merge(m, src)
+}
+func (m *TailBlobResponse) XXX_Size() int {
+	return xxx_messageInfo_TailBlobResponse.Size(m)
+}
+func (m *TailBlobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailBlobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailBlobResponse proto.InternalMessageInfo
+
+func (m *TailBlobResponse) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *TailBlobResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *TailBlobResponse) GetFinalized() bool {
+	if m != nil {
+		return m.Finalized
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*AppendBlobRequest)(nil), "contentservice.AppendBlobRequest")
+	proto.RegisterType((*AppendBlobResponse)(nil), "contentservice.AppendBlobResponse")
+	proto.RegisterType((*FinalizeBlobRequest)(nil), "contentservice.FinalizeBlobRequest")
+	proto.RegisterType((*FinalizeBlobResponse)(nil), "contentservice.FinalizeBlobResponse")
+	proto.RegisterType((*TailBlobRequest)(nil), "contentservice.TailBlobRequest")
+	proto.RegisterType((*TailBlobResponse)(nil), "contentservice.TailBlobResponse")
+}
+
+func init() {
+	proto.RegisterFile("streams.proto", fileDescriptor_c6bbf8af0ec331d6)
+}
+
+var fileDescriptor_c6bbf8af0ec331d6 = []byte{
+	// 365 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0xdd, 0x4e, 0xf2, 0x40,
+	0x10, 0xfd, 0x4a, 0x09, 0x1f, 0x8c, 0xf8, 0xc3, 0x6a, 0x4c, 0x25, 0x26, 0x96, 0xea, 0x45, 0x63,
+	0x42, 0xf1, 0xe7, 0x09, 0xe4, 0xc2, 0xc4, 0x0b, 0x2f, 0x2c, 0x24, 0x1a, 0x63, 0x42, 0x0a, 0x1d,
+	0x74, 0x13, 0xd8, 0x5d, 0xbb, 0x8b, 0x06, 0x7c, 0x07, 0x9f, 0xd9, 0x74, 0x59, 0xac, 0x20, 0x72,
+	0xa3, 0x77, 0x67, 0x26, 0xa7, 0x67, 0x4e, 0xcf, 0xcc, 0xc2, 0xba, 0x54, 0x09, 0x46, 0x43, 0x19,
+	0x88, 0x84, 0x2b, 0x4e, 0x36, 0x7a, 0x9c, 0x29, 0x64, 0x4a, 0x62, 0xf2, 0x42, 0x7b, 0xe8, 0x31,
+	0xa8, 0x5c, 0x08, 0x81, 0x2c, 0x6e, 0x0e, 0x78, 0x37, 0xc4, 0xe7, 0x11, 0x4a, 0x45, 0xf6, 0xa0,
+	0xc8, 0x5f, 0x19, 0x26, 0x1d, 0x1a, 0x3b, 0x96, 0x6b, 0xf9, 0xa5, 0xf0, 0xbf, 0xae, 0xaf, 0x62,
+	0x42, 0x20, 0xcf, 0xa2, 0x21, 0x3a, 0x39, 0xdd, 0xd6, 0x98, 0xec, 0x42, 0x81, 0xf7, 0xfb, 0x12,
+	0x95, 0x63, 0xbb, 0x96, 0x6f, 0x87, 0xa6, 0x4a, 0xb9, 0x71, 0xa4, 0x22, 0x27, 0xef, 0x5a, 0x7e,
+	0x39, 0xd4, 0xd8, 0xf3, 0x81, 0x7c, 0x9d, 0x27, 0x05, 0x67, 0x12, 0x53, 0xa6, 0xa4, 0x13, 0xd4,
+	0xc3, 0xec, 0x50, 0x63, 0xef, 0x0d, 0xb6, 0x2f, 0x29, 0x8b, 0x06, 0x74, 0x82, 0xbf, 0xf0, 0x36,
+	0x53, 0xb6, 0x33, 0x65, 0x52, 0x83, 0xb2, 0x49, 0xa1, 0xa3, 0xc6, 0x02, 0xb5, 0xbf, 0x52, 0xb8,
+	0x66, 0x7a, 0xed, 0xb1, 0x40, 0xef, 0x18, 0x76, 0xe6, 0x87, 0xaf, 0x30, 0x7a, 0x07, 0x9b, 0xed,
+	0x88, 0x0e, 0xfe, 0x3e, 0x40, 0xef, 0x01, 0xb6, 0x32, 0x65, 0xe3, 0x20, 0xe3, 0x5a, 0x4b, 0xc3,
+	0xce, 0x65, 0x61, 0x93, 0x7d, 0x28, 0xf5, 0xcd, 0x5f, 0xc4, 0x5a, 0xba, 0x18, 0x66, 0x8d, 0xb3,
+	0xf7, 0x1c, 0x54, 0x52, 0xe9, 0x96, 0x3e, 0x90, 0xd6, 0xf4, 0x20, 0xc8, 0x0d, 0x14, 0xa6, 0x0b,
+	0x22, 0xb5, 0x60, 0xfe, 0x56, 0x82, 0x6f, 0x87, 0x52, 0xf5, 0x56, 0x51, 0xa6, 0x86, 0xbd, 0x7f,
+	0xe4, 0x16, 0x8a, 0xb3, 0x30, 0xc9, 0xe1, 0xe2, 0x17, 0x4b, 0x76, 0x5c, 0x3d, 0x5a, 0x4d, 0xfa,
+	0x14, 0xbe, 0x86, 0x7c, 0x9a, 0x0f, 0x39, 0x58, 0xe4, 0x2f, 0xec, 0xa3, 0xea, 0xfe, 0x4c, 0x98,
+	0x89, 0x9d, 0x58, 0xcd, 0xd3, 0xfb, 0xc6, 0x23, 0x55, 0x4f, 0xa3, 0x6e, 0xd0, 0xe3, 0xc3, 0x14,
+	0x0a, 0x1e, 0xd7, 0x29, 0x37, 0xa8, 0x61, 0x24, 0xea, 0x46, 0xa3, 0x11, 0x09, 0xda, 0x2d, 0xe8,
+	0x57, 0x75, 0xfe, 0x31, 0x00, 0x09, 0x5a, 0x86, 0x87, 0x66, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// BlobStreamServiceClient is the client API for BlobStreamService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BlobStreamServiceClient interface {
+	// Append adds data to the end of a blob which is still being written, e.g. the log of a running prebuild.
+	// Writers append sequentially and name the offset they append at, so that retrying an append is safe.
+	Append(ctx context.Context, in *AppendBlobRequest, opts ...grpc.CallOption) (*AppendBlobResponse, error)
+	// Finalize completes a blob. Once finalized, the blob can no longer be appended to and is available
+	// like any other blob through BlobService.DownloadUrl.
+	Finalize(ctx context.Context, in *FinalizeBlobRequest, opts ...grpc.CallOption) (*FinalizeBlobResponse, error)
+	// Tail streams the content of a blob from an offset, including everything appended while tailing, until the
+	// blob is finalized. Tailing a blob which does not exist yet waits for its first append.
+	Tail(ctx context.Context, in *TailBlobRequest, opts ...grpc.CallOption) (BlobStreamService_TailClient, error)
+}
+
+type blobStreamServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlobStreamServiceClient(cc grpc.ClientConnInterface) BlobStreamServiceClient {
+	return &blobStreamServiceClient{cc}
+}
+
+func (c *blobStreamServiceClient) Append(ctx context.Context, in *AppendBlobRequest, opts ...grpc.CallOption) (*AppendBlobResponse, error) {
+	out := new(AppendBlobResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.BlobStreamService/Append", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobStreamServiceClient) Finalize(ctx context.Context, in *FinalizeBlobRequest, opts ...grpc.CallOption) (*FinalizeBlobResponse, error) {
+	out := new(FinalizeBlobResponse)
+	err := c.cc.Invoke(ctx, "/contentservice.BlobStreamService/Finalize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobStreamServiceClient) Tail(ctx context.Context, in *TailBlobRequest, opts ...grpc.CallOption) (BlobStreamService_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlobStreamService_serviceDesc.Streams[0], "/contentservice.BlobStreamService/Tail", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobStreamServiceTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlobStreamService_TailClient interface {
+	Recv() (*TailBlobResponse, error)
+	grpc.ClientStream
+}
+
+type blobStreamServiceTailClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobStreamServiceTailClient) Recv() (*TailBlobResponse, error) {
+	m := new(TailBlobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlobStreamServiceServer is the server API for BlobStreamService service.
+type BlobStreamServiceServer interface {
+	// Append adds data to the end of a blob which is still being written, e.g. the log of a running prebuild.
+	// Writers append sequentially and name the offset they append at, so that retrying an append is safe.
+	Append(context.Context, *AppendBlobRequest) (*AppendBlobResponse, error)
+	// Finalize completes a blob. Once finalized, the blob can no longer be appended to and is available
+	// like any other blob through BlobService.DownloadUrl.
+	Finalize(context.Context, *FinalizeBlobRequest) (*FinalizeBlobResponse, error)
+	// Tail streams the content of a blob from an offset, including everything appended while tailing, until the
+	// blob is finalized. Tailing a blob which does not exist yet waits for its first append.
+	Tail(*TailBlobRequest, BlobStreamService_TailServer) error
+}
+
+// UnimplementedBlobStreamServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedBlobStreamServiceServer struct {
+}
+
+func (*UnimplementedBlobStreamServiceServer) Append(ctx context.Context, req *AppendBlobRequest) (*AppendBlobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
+}
+func (*UnimplementedBlobStreamServiceServer) Finalize(ctx context.Context, req *FinalizeBlobRequest) (*FinalizeBlobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Finalize not implemented")
+}
+func (*UnimplementedBlobStreamServiceServer) Tail(req *TailBlobRequest, srv BlobStreamService_TailServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tail not implemented")
+}
+
+func RegisterBlobStreamServiceServer(s *grpc.Server, srv BlobStreamServiceServer) {
+	s.RegisterService(&_BlobStreamService_serviceDesc, srv)
+}
+
+func _BlobStreamService_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobStreamServiceServer).Append(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.BlobStreamService/Append",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobStreamServiceServer).Append(ctx, req.(*AppendBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobStreamService_Finalize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizeBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobStreamServiceServer).Finalize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contentservice.BlobStreamService/Finalize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobStreamServiceServer).Finalize(ctx, req.(*FinalizeBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobStreamService_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailBlobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlobStreamServiceServer).Tail(m, &blobStreamServiceTailServer{stream})
+}
+
+type BlobStreamService_TailServer interface {
+	Send(*TailBlobResponse) error
+	grpc.ServerStream
+}
+
+type blobStreamServiceTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobStreamServiceTailServer) Send(m *TailBlobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BlobStreamService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "contentservice.BlobStreamService",
+	HandlerType: (*BlobStreamServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Append",
+			Handler:    _BlobStreamService_Append_Handler,
+		},
+		{
+			MethodName: "Finalize",
+			Handler:    _BlobStreamService_Finalize_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _BlobStreamService_Tail_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "streams.proto",
+}
@@ -38,6 +38,18 @@ export class WorkspaceInitializer extends jspb.Message {
     setPrebuild(value?: PrebuildInitializer): WorkspaceInitializer;
 
 
+    hasComposite(): boolean;
+    clearComposite(): void;
+    getComposite(): CompositeInitializer | undefined;
+    setComposite(value?: CompositeInitializer): WorkspaceInitializer;
+
+
+    hasArchive(): boolean;
+    clearArchive(): void;
+    getArchive(): ArchiveInitializer | undefined;
+    setArchive(value?: ArchiveInitializer): WorkspaceInitializer;
+
+
     getSpecCase(): WorkspaceInitializer.SpecCase;
 
     serializeBinary(): Uint8Array;
@@ -56,6 +68,8 @@ export namespace WorkspaceInitializer {
         git?: GitInitializer.AsObject,
         snapshot?: SnapshotInitializer.AsObject,
         prebuild?: PrebuildInitializer.AsObject,
+        composite?: CompositeInitializer.AsObject,
+        archive?: ArchiveInitializer.AsObject,
     }
 
     export enum SpecCase {
@@ -69,6 +83,10 @@ export namespace WorkspaceInitializer {
 
     PREBUILD = 4,
 
+    COMPOSITE = 5,
+
+    ARCHIVE = 6,
+
     }
 
 }
@@ -225,6 +243,86 @@ export namespace PrebuildInitializer {
     }
 }
 
+export class CompositeInitializer extends jspb.Message { 
+    clearLayersList(): void;
+    getLayersList(): Array<CompositeInitializerLayer>;
+    setLayersList(value: Array<CompositeInitializerLayer>): CompositeInitializer;
+    addLayers(value?: CompositeInitializerLayer, index?: number): CompositeInitializerLayer;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CompositeInitializer.AsObject;
+    static toObject(includeInstance: boolean, msg: CompositeInitializer): CompositeInitializer.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CompositeInitializer, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CompositeInitializer;
+    static deserializeBinaryFromReader(message: CompositeInitializer, reader: jspb.BinaryReader): CompositeInitializer;
+}
+
+export namespace CompositeInitializer {
+    export type AsObject = {
+        layersList: Array<CompositeInitializerLayer.AsObject>,
+    }
+}
+
+export class CompositeInitializerLayer extends jspb.Message { 
+
+    hasInitializer(): boolean;
+    clearInitializer(): void;
+    getInitializer(): WorkspaceInitializer | undefined;
+    setInitializer(value?: WorkspaceInitializer): CompositeInitializerLayer;
+
+    getTargetLocation(): string;
+    setTargetLocation(value: string): CompositeInitializerLayer;
+
+    getConflictPolicy(): LayerConflictPolicy;
+    setConflictPolicy(value: LayerConflictPolicy): CompositeInitializerLayer;
+
+    getOptional(): boolean;
+    setOptional(value: boolean): CompositeInitializerLayer;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CompositeInitializerLayer.AsObject;
+    static toObject(includeInstance: boolean, msg: CompositeInitializerLayer): CompositeInitializerLayer.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CompositeInitializerLayer, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CompositeInitializerLayer;
+    static deserializeBinaryFromReader(message: CompositeInitializerLayer, reader: jspb.BinaryReader): CompositeInitializerLayer;
+}
+
+export namespace CompositeInitializerLayer {
+    export type AsObject = {
+        initializer?: WorkspaceInitializer.AsObject,
+        targetLocation: string,
+        conflictPolicy: LayerConflictPolicy,
+        optional: boolean,
+    }
+}
+
+export class ArchiveInitializer extends jspb.Message { 
+    getUrl(): string;
+    setUrl(value: string): ArchiveInitializer;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ArchiveInitializer.AsObject;
+    static toObject(includeInstance: boolean, msg: ArchiveInitializer): ArchiveInitializer.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ArchiveInitializer, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ArchiveInitializer;
+    static deserializeBinaryFromReader(message: ArchiveInitializer, reader: jspb.BinaryReader): ArchiveInitializer;
+}
+
+export namespace ArchiveInitializer {
+    export type AsObject = {
+        url: string,
+    }
+}
+
 export class GitStatus extends jspb.Message { 
     getBranch(): string;
     setBranch(value: string): GitStatus;
@@ -292,3 +390,9 @@ export enum GitAuthMethod {
     BASIC_AUTH = 1,
     BASIC_AUTH_OTS = 2,
 }
+
+export enum LayerConflictPolicy {
+    OVERWRITE = 0,
+    KEEP_EXISTING = 1,
+    FAIL = 2,
+}
@@ -17,12 +17,16 @@ var jspb = require('google-protobuf');
 var goog = jspb;
 var global = Function('return this')();
 
+goog.exportSymbol('proto.contentservice.ArchiveInitializer', null, global);
 goog.exportSymbol('proto.contentservice.CloneTargetMode', null, global);
+goog.exportSymbol('proto.contentservice.CompositeInitializer', null, global);
+goog.exportSymbol('proto.contentservice.CompositeInitializerLayer', null, global);
 goog.exportSymbol('proto.contentservice.EmptyInitializer', null, global);
 goog.exportSymbol('proto.contentservice.GitAuthMethod', null, global);
 goog.exportSymbol('proto.contentservice.GitConfig', null, global);
 goog.exportSymbol('proto.contentservice.GitInitializer', null, global);
 goog.exportSymbol('proto.contentservice.GitStatus', null, global);
+goog.exportSymbol('proto.contentservice.LayerConflictPolicy', null, global);
 goog.exportSymbol('proto.contentservice.PrebuildInitializer', null, global);
 goog.exportSymbol('proto.contentservice.SnapshotInitializer', null, global);
 goog.exportSymbol('proto.contentservice.WorkspaceInitializer', null, global);
@@ -152,6 +156,69 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.contentservice.PrebuildInitializer.displayName = 'proto.contentservice.PrebuildInitializer';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.CompositeInitializer = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.contentservice.CompositeInitializer.repeatedFields_, null);
+};
+goog.inherits(proto.contentservice.CompositeInitializer, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.contentservice.CompositeInitializer.displayName = 'proto.contentservice.CompositeInitializer';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.CompositeInitializerLayer = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.contentservice.CompositeInitializerLayer, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.contentservice.CompositeInitializerLayer.displayName = 'proto.contentservice.CompositeInitializerLayer';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.ArchiveInitializer = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.contentservice.ArchiveInitializer, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.contentservice.ArchiveInitializer.displayName = 'proto.contentservice.ArchiveInitializer';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -182,7 +249,7 @@ if (goog.DEBUG && !COMPILED) {
  * @private {!Array<!Array<number>>}
  * @const
  */
-proto.contentservice.WorkspaceInitializer.oneofGroups_ = [[1,2,3,4]];
+proto.contentservice.WorkspaceInitializer.oneofGroups_ = [[1,2,3,4,5,6]];
 
 /**
  * @enum {number}
@@ -192,7 +259,9 @@ proto.contentservice.WorkspaceInitializer.SpecCase = {
   EMPTY: 1,
   GIT: 2,
   SNAPSHOT: 3,
-  PREBUILD: 4
+  PREBUILD: 4,
+  COMPOSITE: 5,
+  ARCHIVE: 6
 };
 
 /**
@@ -234,7 +303,9 @@ proto.contentservice.WorkspaceInitializer.toObject = function(includeInstance, m
     empty: (f = msg.getEmpty()) && proto.contentservice.EmptyInitializer.toObject(includeInstance, f),
     git: (f = msg.getGit()) && proto.contentservice.GitInitializer.toObject(includeInstance, f),
     snapshot: (f = msg.getSnapshot()) && proto.contentservice.SnapshotInitializer.toObject(includeInstance, f),
-    prebuild: (f = msg.getPrebuild()) && proto.contentservice.PrebuildInitializer.toObject(includeInstance, f)
+    prebuild: (f = msg.getPrebuild()) && proto.contentservice.PrebuildInitializer.toObject(includeInstance, f),
+    composite: (f = msg.getComposite()) && proto.contentservice.CompositeInitializer.toObject(includeInstance, f),
+    archive: (f = msg.getArchive()) && proto.contentservice.ArchiveInitializer.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -291,6 +362,16 @@ proto.contentservice.WorkspaceInitializer.deserializeBinaryFromReader = function
       reader.readMessage(value,proto.contentservice.PrebuildInitializer.deserializeBinaryFromReader);
       msg.setPrebuild(value);
       break;
+    case 5:
+      var value = new proto.contentservice.CompositeInitializer;
+      reader.readMessage(value,proto.contentservice.CompositeInitializer.deserializeBinaryFromReader);
+      msg.setComposite(value);
+      break;
+    case 6:
+      var value = new proto.contentservice.ArchiveInitializer;
+      reader.readMessage(value,proto.contentservice.ArchiveInitializer.deserializeBinaryFromReader);
+      msg.setArchive(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -352,6 +433,22 @@ proto.contentservice.WorkspaceInitializer.serializeBinaryToWriter = function(mes
       proto.contentservice.PrebuildInitializer.serializeBinaryToWriter
     );
   }
+  f = message.getComposite();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      proto.contentservice.CompositeInitializer.serializeBinaryToWriter
+    );
+  }
+  f = message.getArchive();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      proto.contentservice.ArchiveInitializer.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -487,6 +584,72 @@ proto.contentservice.WorkspaceInitializer.prototype.hasPrebuild = function() {
 };
 
 
+/**
+ * optional CompositeInitializer composite = 5;
+ * @return {?proto.contentservice.CompositeInitializer}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.getComposite = function() {
+  return /** @type{?proto.contentservice.CompositeInitializer} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.CompositeInitializer, 5));
+};
+
+
+/** @param {?proto.contentservice.CompositeInitializer|undefined} value */
+proto.contentservice.WorkspaceInitializer.prototype.setComposite = function(value) {
+  jspb.Message.setOneofWrapperField(this, 5, proto.contentservice.WorkspaceInitializer.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.contentservice.WorkspaceInitializer.prototype.clearComposite = function() {
+  this.setComposite(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.hasComposite = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * optional ArchiveInitializer archive = 6;
+ * @return {?proto.contentservice.ArchiveInitializer}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.getArchive = function() {
+  return /** @type{?proto.contentservice.ArchiveInitializer} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.ArchiveInitializer, 6));
+};
+
+
+/** @param {?proto.contentservice.ArchiveInitializer|undefined} value */
+proto.contentservice.WorkspaceInitializer.prototype.setArchive = function(value) {
+  jspb.Message.setOneofWrapperField(this, 6, proto.contentservice.WorkspaceInitializer.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.contentservice.WorkspaceInitializer.prototype.clearArchive = function() {
+  this.setArchive(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.WorkspaceInitializer.prototype.hasArchive = function() {
+  return jspb.Message.getField(this, 6) != null;
+};
+
+
 
 
 
@@ -1422,6 +1585,513 @@ proto.contentservice.PrebuildInitializer.prototype.hasGit = function() {
 
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.contentservice.CompositeInitializer.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.contentservice.CompositeInitializer.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.CompositeInitializer.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.contentservice.CompositeInitializer} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.CompositeInitializer.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    layersList: jspb.Message.toObjectList(msg.getLayersList(),
+    proto.contentservice.CompositeInitializerLayer.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.CompositeInitializer}
+ */
+proto.contentservice.CompositeInitializer.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.contentservice.CompositeInitializer;
+  return proto.contentservice.CompositeInitializer.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.CompositeInitializer} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.CompositeInitializer}
+ */
+proto.contentservice.CompositeInitializer.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.contentservice.CompositeInitializerLayer;
+      reader.readMessage(value,proto.contentservice.CompositeInitializerLayer.deserializeBinaryFromReader);
+      msg.addLayers(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.CompositeInitializer.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.contentservice.CompositeInitializer.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.CompositeInitializer} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.CompositeInitializer.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getLayersList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.contentservice.CompositeInitializerLayer.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated CompositeInitializerLayer layers = 1;
+ * @return {!Array<!proto.contentservice.CompositeInitializerLayer>}
+ */
+proto.contentservice.CompositeInitializer.prototype.getLayersList = function() {
+  return /** @type{!Array<!proto.contentservice.CompositeInitializerLayer>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.contentservice.CompositeInitializerLayer, 1));
+};
+
+
+/** @param {!Array<!proto.contentservice.CompositeInitializerLayer>} value */
+proto.contentservice.CompositeInitializer.prototype.setLayersList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.contentservice.CompositeInitializerLayer=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.contentservice.CompositeInitializerLayer}
+ */
+proto.contentservice.CompositeInitializer.prototype.addLayers = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.contentservice.CompositeInitializerLayer, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.contentservice.CompositeInitializer.prototype.clearLayersList = function() {
+  this.setLayersList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.CompositeInitializerLayer.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.contentservice.CompositeInitializerLayer} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.CompositeInitializerLayer.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    initializer: (f = msg.getInitializer()) && proto.contentservice.WorkspaceInitializer.toObject(includeInstance, f),
+    targetLocation: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    conflictPolicy: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    optional: jspb.Message.getFieldWithDefault(msg, 4, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.CompositeInitializerLayer}
+ */
+proto.contentservice.CompositeInitializerLayer.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.contentservice.CompositeInitializerLayer;
+  return proto.contentservice.CompositeInitializerLayer.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.CompositeInitializerLayer} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.CompositeInitializerLayer}
+ */
+proto.contentservice.CompositeInitializerLayer.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.contentservice.WorkspaceInitializer;
+      reader.readMessage(value,proto.contentservice.WorkspaceInitializer.deserializeBinaryFromReader);
+      msg.setInitializer(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTargetLocation(value);
+      break;
+    case 3:
+      var value = /** @type {!proto.contentservice.LayerConflictPolicy} */ (reader.readEnum());
+      msg.setConflictPolicy(value);
+      break;
+    case 4:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setOptional(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.contentservice.CompositeInitializerLayer.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.CompositeInitializerLayer} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.CompositeInitializerLayer.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getInitializer();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.contentservice.WorkspaceInitializer.serializeBinaryToWriter
+    );
+  }
+  f = message.getTargetLocation();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getConflictPolicy();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
+  f = message.getOptional();
+  if (f) {
+    writer.writeBool(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional WorkspaceInitializer initializer = 1;
+ * @return {?proto.contentservice.WorkspaceInitializer}
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.getInitializer = function() {
+  return /** @type{?proto.contentservice.WorkspaceInitializer} */ (
+    jspb.Message.getWrapperField(this, proto.contentservice.WorkspaceInitializer, 1));
+};
+
+
+/** @param {?proto.contentservice.WorkspaceInitializer|undefined} value */
+proto.contentservice.CompositeInitializerLayer.prototype.setInitializer = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.clearInitializer = function() {
+  this.setInitializer(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.hasInitializer = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional string target_location = 2;
+ * @return {string}
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.getTargetLocation = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.contentservice.CompositeInitializerLayer.prototype.setTargetLocation = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional LayerConflictPolicy conflict_policy = 3;
+ * @return {!proto.contentservice.LayerConflictPolicy}
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.getConflictPolicy = function() {
+  return /** @type {!proto.contentservice.LayerConflictPolicy} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {!proto.contentservice.LayerConflictPolicy} value */
+proto.contentservice.CompositeInitializerLayer.prototype.setConflictPolicy = function(value) {
+  jspb.Message.setProto3EnumField(this, 3, value);
+};
+
+
+/**
+ * optional bool optional = 4;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.contentservice.CompositeInitializerLayer.prototype.getOptional = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 4, false));
+};
+
+
+/** @param {boolean} value */
+proto.contentservice.CompositeInitializerLayer.prototype.setOptional = function(value) {
+  jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.contentservice.ArchiveInitializer.prototype.toObject = function(opt_includeInstance) {
+  return proto.contentservice.ArchiveInitializer.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.contentservice.ArchiveInitializer} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.ArchiveInitializer.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.ArchiveInitializer}
+ */
+proto.contentservice.ArchiveInitializer.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.contentservice.ArchiveInitializer;
+  return proto.contentservice.ArchiveInitializer.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.ArchiveInitializer} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.ArchiveInitializer}
+ */
+proto.contentservice.ArchiveInitializer.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.ArchiveInitializer.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.contentservice.ArchiveInitializer.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.ArchiveInitializer} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.ArchiveInitializer.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string url = 1;
+ * @return {string}
+ */
+proto.contentservice.ArchiveInitializer.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.contentservice.ArchiveInitializer.prototype.setUrl = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
 /**
  * List of repeated fields within this message type.
  * @private {!Array<number>}
@@ -1812,4 +2482,13 @@ proto.contentservice.GitAuthMethod = {
   BASIC_AUTH_OTS: 2
 };
 
+/**
+ * @enum {number}
+ */
+proto.contentservice.LayerConflictPolicy = {
+  OVERWRITE: 0,
+  KEEP_EXISTING: 1,
+  FAIL: 2
+};
+
 goog.object.extend(exports, proto.contentservice);
@@ -18,6 +18,7 @@ import (
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/content-service/pkg/service"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 )
 
@@ -85,7 +86,10 @@ type config struct {
 	PProf struct {
 		Addr string `json:"address"`
 	} `json:"pprof"`
-	Storage storage.Config `json:"storage"`
+	Storage   storage.Config              `json:"storage"`
+	Snapshots service.SnapshotConfig      `json:"snapshots"`
+	Prebuilds service.PrebuildQuotaConfig `json:"prebuilds"`
+	Streams   service.StreamConfig        `json:"streams"`
 }
 
 type tlsConfig struct {
@@ -66,6 +66,21 @@ var runCmd = &cobra.Command{
 		}
 
 		server := grpc.NewServer(grpcOpts...)
+		snapshotService, err := service.NewSnapshotService(cfg.Snapshots, cfg.Storage)
+		if err != nil {
+			log.WithError(err).Fatalf("cannot create snapshot service")
+		}
+		api.RegisterSnapshotServiceServer(server, snapshotService)
+		prebuildService, err := service.NewPrebuildStorageService(cfg.Prebuilds, cfg.Storage)
+		if err != nil {
+			log.WithError(err).Fatalf("cannot create prebuild storage service")
+		}
+		api.RegisterPrebuildStorageServiceServer(server, prebuildService)
+		streamService, err := service.NewBlobStreamService(cfg.Streams, cfg.Storage)
+		if err != nil {
+			log.WithError(err).Fatalf("cannot create blob stream service")
+		}
+		api.RegisterBlobStreamServiceServer(server, streamService)
 		service, err := service.NewContentService(cfg.Storage)
 		if err != nil {
 			log.WithError(err).Fatalf("cannot create content service")
@@ -0,0 +1,327 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package compression compresses workspace content with dictionaries trained per repository. Generic compression
+// does poorly on many small, similar files because every file starts from scratch. A dictionary of what the files
+// of a repository have in common primes the compressor for each of them.
+//
+// Compressed content is a sequence of independently deflated frames, each primed with the dictionary. Frames
+// which deflate cannot make smaller are stored raw. The
+// dictionary itself is part of the header, so that the content can be decompressed without access to the
+// dictionary store, e.g. after it was exported to another installation.
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MaxDictionarySize is the size of deflate's window. Dictionaries larger than that are pointless.
+	MaxDictionarySize = 32 * 1024
+
+	// frameSize is the amount of uncompressed content per frame. Every frame starts with the dictionary as history.
+	frameSize = 64 * 1024
+	// maxFrameSize limits the compressed size of a frame we're willing to read
+	maxFrameSize = 2 * frameSize
+
+	// frameRaw flags frames which are stored as they are, because deflate did not make them any smaller
+	frameRaw = 1
+
+	formatVersion = 1
+)
+
+// magic starts all content compressed with a dictionary
+var magic = []byte("GPDZ")
+
+// Method describes how content was compressed
+type Method string
+
+const (
+	// MethodNone means the content is not compressed
+	MethodNone Method = "none"
+	// MethodGzip means the content is gzip compressed
+	MethodGzip Method = "gzip"
+	// MethodDictionary means the content is compressed with a dictionary
+	MethodDictionary Method = "dictionary"
+)
+
+// Writer compresses content using a dictionary
+type Writer struct {
+	w    io.Writer
+	fw   *flate.Writer
+	dict []byte
+
+	buf    []byte
+	frame  bytes.Buffer
+	check  bytes.Buffer
+	closed bool
+}
+
+// NewWriter starts compressed content with the dictionary on w. The dictionary must not exceed MaxDictionarySize.
+func NewWriter(w io.Writer, dict []byte) (*Writer, error) {
+	if len(dict) > MaxDictionarySize {
+		return nil, xerrors.Errorf("dictionary exceeds %d bytes", MaxDictionarySize)
+	}
+
+	res := &Writer{
+		w:    w,
+		dict: dict,
+		buf:  make([]byte, 0, frameSize),
+	}
+	fw, err := flate.NewWriterDict(&res.frame, flate.BestCompression, dict)
+	if err != nil {
+		return nil, err
+	}
+	res.fw = fw
+
+	hdr := make([]byte, 0, len(magic)+1+binary.MaxVarintLen64+len(dict))
+	hdr = append(hdr, magic...)
+	hdr = append(hdr, formatVersion)
+	hdr = appendUvarint(hdr, uint64(len(dict)))
+	hdr = append(hdr, dict...)
+	_, err = w.Write(hdr)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Write compresses p
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.closed {
+		return 0, xerrors.Errorf("writer is closed")
+	}
+	for len(p) > 0 {
+		c := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		p = p[c:]
+		n += c
+
+		if len(w.buf) == cap(w.buf) {
+			err = w.flushFrame()
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (w *Writer) flushFrame() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	defer func() { w.buf = w.buf[:0] }()
+
+	compressed, err := w.compressFrame()
+	if err != nil {
+		return err
+	}
+	if compressed == nil {
+		_, err = w.w.Write(appendUvarint(nil, uint64(len(w.buf))<<1|frameRaw))
+		if err != nil {
+			return err
+		}
+		_, err = w.w.Write(w.buf)
+		return err
+	}
+
+	_, err = w.w.Write(appendUvarint(nil, uint64(len(compressed))<<1))
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(compressed)
+	return err
+}
+
+// compressFrame deflates the buffer and returns nil if the frame is better stored raw.
+//
+// Deflate with a preset dictionary is known to emit the dictionary as part of stored blocks, which happens
+// for content that does not compress. We verify each frame instead of relying on the exact conditions
+// under which that happens.
+func (w *Writer) compressFrame() ([]byte, error) {
+	w.frame.Reset()
+	// Reset restores the dictionary as history
+	w.fw.Reset(&w.frame)
+	_, err := w.fw.Write(w.buf)
+	if err != nil {
+		return nil, err
+	}
+	err = w.fw.Close()
+	if err != nil {
+		return nil, err
+	}
+	if w.frame.Len() >= len(w.buf) {
+		return nil, nil
+	}
+
+	w.check.Reset()
+	fr := flate.NewReaderDict(bytes.NewReader(w.frame.Bytes()), w.dict)
+	_, err = io.Copy(&w.check, io.LimitReader(fr, int64(len(w.buf))+1))
+	fr.Close()
+	if err != nil || !bytes.Equal(w.check.Bytes(), w.buf) {
+		return nil, nil
+	}
+	return w.frame.Bytes(), nil
+}
+
+// Close compresses what's left and marks the end of the content. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	err := w.flushFrame()
+	if err != nil {
+		return err
+	}
+	w.closed = true
+
+	// an empty frame marks the end, so that we notice truncated content
+	_, err = w.w.Write(appendUvarint(nil, 0))
+	return err
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// Decompress detects how the content of r was compressed and decompresses it.
+// Content that is not compressed is passed through.
+func Decompress(r io.Reader) (io.Reader, Method, error) {
+	br := bufio.NewReader(r)
+	hdr, err := br.Peek(len(magic))
+	if err == io.EOF {
+		return br, MethodNone, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case bytes.Equal(hdr, magic):
+		dr, err := newReader(br)
+		return dr, MethodDictionary, err
+	case hdr[0] == 0x1f && hdr[1] == 0x8b:
+		gr, err := gzip.NewReader(br)
+		return gr, MethodGzip, err
+	default:
+		return br, MethodNone, nil
+	}
+}
+
+// reader decompresses content compressed by Writer
+type reader struct {
+	r    *bufio.Reader
+	dict []byte
+
+	frame     io.Reader
+	remainder *io.LimitedReader
+	done      bool
+}
+
+func newReader(r *bufio.Reader) (*reader, error) {
+	hdr := make([]byte, len(magic)+1)
+	_, err := io.ReadFull(r, hdr)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read header: %w", err)
+	}
+	if v := hdr[len(magic)]; v != formatVersion {
+		return nil, xerrors.Errorf("unsupported format version %d", v)
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read dictionary size: %w", err)
+	}
+	if n > MaxDictionarySize {
+		return nil, xerrors.Errorf("dictionary exceeds %d bytes", MaxDictionarySize)
+	}
+	dict := make([]byte, n)
+	_, err = io.ReadFull(r, dict)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read dictionary: %w", err)
+	}
+	return &reader{r: r, dict: dict}, nil
+}
+
+func (r *reader) Read(p []byte) (n int, err error) {
+	for {
+		if r.done {
+			return 0, io.EOF
+		}
+		if r.frame == nil {
+			err = r.nextFrame()
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		n, err = r.frame.Read(p)
+		if err == io.EOF {
+			if c, ok := r.frame.(io.Closer); ok {
+				c.Close()
+			}
+			r.frame = nil
+			// the next frame starts where this one ends, not where flate stopped reading
+			_, err = io.Copy(io.Discard, r.remainder)
+			if err != nil {
+				return n, xerrors.Errorf("cannot skip to next frame: %w", err)
+			}
+			if n == 0 {
+				continue
+			}
+		}
+		if err != nil {
+			err = xerrors.Errorf("cannot decompress frame: %w", err)
+		}
+		return n, err
+	}
+}
+
+func (r *reader) nextFrame() error {
+	hdr, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot read frame size: %w", err)
+	}
+	if hdr == 0 {
+		r.done = true
+		return nil
+	}
+	size := hdr >> 1
+	if size == 0 || size > maxFrameSize {
+		return xerrors.Errorf("invalid frame size %d", size)
+	}
+	r.remainder = &io.LimitedReader{R: r.r, N: int64(size)}
+	if hdr&frameRaw != 0 {
+		r.frame = &rawFrame{r.remainder}
+	} else {
+		r.frame = flate.NewReaderDict(r.remainder, r.dict)
+	}
+	return nil
+}
+
+// rawFrame reads a frame stored as it is and fails if the content ends before the frame does
+type rawFrame struct {
+	r *io.LimitedReader
+}
+
+func (f *rawFrame) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF && f.r.N > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
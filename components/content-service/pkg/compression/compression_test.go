@@ -0,0 +1,209 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sourceFiles produces files which have a license header and imports in common, but little else
+func sourceFiles(n int) [][]byte {
+	rnd := rand.New(rand.NewSource(42))
+	res := make([][]byte, n)
+	for i := range res {
+		var b bytes.Buffer
+		b.WriteString("// Copyright (c) 2021 Gitpod GmbH. All rights reserved.\n")
+		b.WriteString("// Licensed under the GNU Affero General Public License (AGPL).\n")
+		b.WriteString("// See License-AGPL.txt in the project root for license information.\n\n")
+		fmt.Fprintf(&b, "package pkg%d\n\nimport (\n\t\"context\"\n\t\"fmt\"\n\n\t\"golang.org/x/xerrors\"\n)\n\n", i)
+		for j := 0; j < 5; j++ {
+			fmt.Fprintf(&b, "func f%d_%x(ctx context.Context) error {\n\treturn xerrors.Errorf(\"%x\")\n}\n\n", j, rnd.Int63(), rnd.Int63())
+		}
+		res[i] = b.Bytes()
+	}
+	return res
+}
+
+func TestRoundTrip(t *testing.T) {
+	random := make([]byte, 3*frameSize+17)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	tests := []struct {
+		Name    string
+		Dict    []byte
+		Content []byte
+	}{
+		{Name: "empty"},
+		{Name: "no dictionary", Content: bytes.Join(sourceFiles(100), nil)},
+		{Name: "dictionary", Dict: Train(sourceFiles(50), 4096), Content: bytes.Join(sourceFiles(100), nil)},
+		{Name: "incompressible", Dict: []byte("foobar"), Content: random},
+		{Name: "exactly one frame", Dict: []byte("foobar"), Content: bytes.Repeat([]byte("a"), frameSize)},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var compressed bytes.Buffer
+			w, err := NewWriter(&compressed, test.Dict)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// write in odd chunks to cross frame boundaries
+			for c := test.Content; len(c) > 0; {
+				n := 1000
+				if n > len(c) {
+					n = len(c)
+				}
+				_, err = w.Write(c[:n])
+				if err != nil {
+					t.Fatal(err)
+				}
+				c = c[n:]
+			}
+			err = w.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, method, err := Decompress(&compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method != MethodDictionary {
+				t.Errorf("unexpected method %s", method)
+			}
+			act, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(act, test.Content) {
+				t.Errorf("content differs after round trip: %d bytes in, %d bytes out", len(test.Content), len(act))
+			}
+		})
+	}
+}
+
+func TestDecompressTruncated(t *testing.T) {
+	var compressed bytes.Buffer
+	w, _ := NewWriter(&compressed, []byte("foobar"))
+	_, _ = w.Write(bytes.Repeat([]byte("foobar"), 3*frameSize))
+	_ = w.Close()
+
+	truncated := compressed.Bytes()[:compressed.Len()-1]
+	r, _, err := Decompress(bytes.NewReader(truncated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(r)
+	if err == nil {
+		t.Error("expected truncated content to fail")
+	}
+}
+
+func TestDecompressOtherMethods(t *testing.T) {
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, _ = gw.Write([]byte("hello world"))
+	_ = gw.Close()
+
+	tests := []struct {
+		Name    string
+		Input   []byte
+		Method  Method
+		Content string
+	}{
+		{Name: "gzip", Input: gz.Bytes(), Method: MethodGzip, Content: "hello world"},
+		{Name: "uncompressed", Input: []byte("hello world"), Method: MethodNone, Content: "hello world"},
+		{Name: "short", Input: []byte("he"), Method: MethodNone, Content: "he"},
+		{Name: "empty", Method: MethodNone},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r, method, err := Decompress(bytes.NewReader(test.Input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method != test.Method {
+				t.Errorf("unexpected method %s", method)
+			}
+			act, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(act) != test.Content {
+				t.Errorf("unexpected content %q", act)
+			}
+		})
+	}
+}
+
+func TestTrain(t *testing.T) {
+	samples := sourceFiles(20)
+	dict := Train(samples, 512)
+	if len(dict) > 512 {
+		t.Errorf("dictionary exceeds its size: %d bytes", len(dict))
+	}
+	if !strings.HasSuffix(string(dict), "// See License-AGPL.txt in the project root for license information.\n") &&
+		!strings.Contains(string(dict), "Licensed under the GNU Affero General Public License (AGPL).") {
+		t.Errorf("expected the license header in the dictionary, got %q", dict)
+	}
+	if strings.Contains(string(dict), "package pkg1\n") {
+		t.Errorf("lines of a single sample must not be in the dictionary")
+	}
+
+	if dict := Train([][]byte{[]byte("nothing in common\n"), []byte("with each other\n")}, 512); len(dict) != 0 {
+		t.Errorf("expected an empty dictionary, got %q", dict)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	samples := sourceFiles(200)
+	gain, err := Evaluate(samples, Train(samples, MaxDictionarySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gain <= 0 {
+		t.Errorf("expected a dictionary of similar files to beat gzip, got a gain of %f", gain)
+	}
+}
+
+func TestSample(t *testing.T) {
+	root := t.TempDir()
+	files := map[string][]byte{
+		"main.go":            []byte("package main\n"),
+		"pkg/lib.go":         []byte("package pkg\n"),
+		".git/config":        []byte("[core]\n"),
+		"image.png":          {0x89, 'P', 'N', 'G', 0, 0, 0},
+		"large/generated.go": bytes.Repeat([]byte("a"), maxSampleFileSize+1),
+	}
+	for fn, content := range files {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(root, fn)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(root, fn), content, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	samples, err := Sample(root, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var act []string
+	for _, s := range samples {
+		act = append(act, string(s))
+	}
+	if len(act) != 2 || !strings.Contains(strings.Join(act, ""), "package main") || !strings.Contains(strings.Join(act, ""), "package pkg") {
+		t.Errorf("expected the two source files only, got %q", act)
+	}
+}
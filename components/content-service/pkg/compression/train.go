@@ -0,0 +1,228 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// minLineLength is the length below which lines are not worth a place in the dictionary
+	minLineLength = 8
+	// maxLineLength is the length above which we truncate lines, so that a few long lines cannot fill the dictionary
+	maxLineLength = 256
+	// maxSampleFileSize is the size above which files are not sampled. Large files do fine with generic compression.
+	maxSampleFileSize = 64 * 1024
+	// maxSampleFiles limits the number of files we sample
+	maxSampleFiles = 2000
+)
+
+// Train builds a dictionary of at most size bytes from samples of the files of a repository. The dictionary
+// consists of the lines which most samples have in common, e.g. license headers or imports. Lines that
+// save the most are placed at the end of the dictionary, where they're cheapest to refer to.
+func Train(samples [][]byte, size int) []byte {
+	if size > MaxDictionarySize {
+		size = MaxDictionarySize
+	}
+
+	type candidate struct {
+		Line  string
+		Count int
+	}
+	var (
+		counts = make(map[string]*candidate)
+		seen   = make(map[string]struct{})
+	)
+	for _, sample := range samples {
+		for k := range seen {
+			delete(seen, k)
+		}
+		for _, line := range bytes.SplitAfter(sample, []byte("\n")) {
+			if len(line) < minLineLength {
+				continue
+			}
+			if len(line) > maxLineLength {
+				line = line[:maxLineLength]
+			}
+			l := string(line)
+			if _, ok := seen[l]; ok {
+				// a line repeated within a file compresses well anyway
+				continue
+			}
+			seen[l] = struct{}{}
+
+			c, ok := counts[l]
+			if !ok {
+				c = &candidate{Line: l}
+				counts[l] = c
+			}
+			c.Count++
+		}
+	}
+
+	cs := make([]*candidate, 0, len(counts))
+	for _, c := range counts {
+		if c.Count < 2 {
+			continue
+		}
+		cs = append(cs, c)
+	}
+	score := func(c *candidate) int { return (c.Count - 1) * len(c.Line) }
+	sort.Slice(cs, func(i, j int) bool {
+		si, sj := score(cs[i]), score(cs[j])
+		if si != sj {
+			return si > sj
+		}
+		return cs[i].Line < cs[j].Line
+	})
+
+	var (
+		selected []*candidate
+		total    int
+	)
+	for _, c := range cs {
+		if total+len(c.Line) > size {
+			continue
+		}
+		selected = append(selected, c)
+		total += len(c.Line)
+	}
+
+	dict := make([]byte, 0, total)
+	for i := len(selected) - 1; i >= 0; i-- {
+		dict = append(dict, selected[i].Line...)
+	}
+	return dict
+}
+
+// Evaluate compresses the samples with the dictionary and with gzip, and returns how much smaller the content
+// compressed with the dictionary is, e.g. 0.2 if it is 20% smaller. Dictionaries which make the content
+// larger have a negative gain.
+func Evaluate(samples [][]byte, dict []byte) (gain float64, err error) {
+	var (
+		withDict countingWriter
+		withGzip countingWriter
+	)
+	dw, err := NewWriter(&withDict, dict)
+	if err != nil {
+		return 0, err
+	}
+	gw, err := gzip.NewWriterLevel(&withGzip, gzip.DefaultCompression)
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range samples {
+		_, err = dw.Write(s)
+		if err != nil {
+			return 0, err
+		}
+		_, err = gw.Write(s)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = dw.Close()
+	if err != nil {
+		return 0, err
+	}
+	err = gw.Close()
+	if err != nil {
+		return 0, err
+	}
+	if withGzip.N == 0 {
+		return 0, nil
+	}
+	return 1 - float64(withDict.N)/float64(withGzip.N), nil
+}
+
+type countingWriter struct {
+	N int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.N += int64(len(p))
+	return len(p), nil
+}
+
+// Sample reads up to budget bytes of randomly chosen small text files below root, skipping the .git directory
+func Sample(root string, budget int64) (samples [][]byte, err error) {
+	var (
+		candidates []string
+		n          int
+	)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// unreadable parts of the workspace don't keep us from sampling the rest
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		// reservoir sampling keeps every file equally likely, no matter where in the tree it is
+		n++
+		if len(candidates) < maxSampleFiles {
+			candidates = append(candidates, path)
+		} else if i := rand.Intn(n); i < maxSampleFiles {
+			candidates[i] = path
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot sample %s: %w", root, err)
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+
+	var total int64
+	for _, fn := range candidates {
+		if total >= budget {
+			break
+		}
+		content, err := readSample(fn)
+		if err != nil || content == nil {
+			continue
+		}
+		samples = append(samples, content)
+		total += int64(len(content))
+	}
+	return samples, nil
+}
+
+// readSample reads a file unless it is large or binary
+func readSample(fn string) ([]byte, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(io.LimitReader(f, maxSampleFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxSampleFileSize {
+		return nil, nil
+	}
+	head := content
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+	return content, nil
+}
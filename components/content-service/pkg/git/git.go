@@ -172,6 +172,10 @@ func (c *Client) GitWithOutput(ctx context.Context, subcommand string, args ...s
 	fullArgs = append(fullArgs, args...)
 
 	env = append(env, fmt.Sprintf("PATH=%s", os.Getenv("PATH")))
+	if subcommand == "status" {
+		// status must not write to the repository, e.g. because we back up the workspace at the same time
+		env = append(env, "GIT_OPTIONAL_LOCKS=0")
+	}
 	if os.Getenv("http_proxy") != "" {
 		env = append(env, fmt.Sprintf("http_proxy=%s", os.Getenv("http_proxy")))
 	}
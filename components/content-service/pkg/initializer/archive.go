@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+)
+
+// ArchiveInitializer downloads a tar archive and extracts it into the workspace
+type ArchiveInitializer struct {
+	Location string
+	URL      string
+}
+
+// Run downloads and extracts the archive
+func (a *ArchiveInitializer) Run(ctx context.Context, mappings []archive.IDMapping) (src csapi.WorkspaceInitSource, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ArchiveInitializer")
+	defer tracing.FinishSpan(span, &err)
+
+	src = csapi.WorkspaceInitFromOther
+
+	req, err := http.NewRequestWithContext(ctx, "GET", a.URL, nil)
+	if err != nil {
+		return src, xerrors.Errorf("archive initializer: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return src, xerrors.Errorf("archive initializer: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return src, xerrors.Errorf("archive initializer: non-OK download response: %s", resp.Status)
+	}
+
+	body, err := decompressArchive(resp.Body)
+	if err != nil {
+		return src, xerrors.Errorf("archive initializer: %w", err)
+	}
+
+	err = os.MkdirAll(a.Location, 0755)
+	if err != nil {
+		return src, xerrors.Errorf("archive initializer: %w", err)
+	}
+	err = archive.ExtractTarbal(ctx, body, a.Location, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
+	if err != nil {
+		return src, xerrors.Errorf("archive initializer: %w", err)
+	}
+	return src, nil
+}
+
+// decompressArchive decompresses gzip compressed archives and passes all others through
+func decompressArchive(in io.Reader) (io.Reader, error) {
+	r := bufio.NewReader(in)
+	magic, err := r.Peek(2)
+	if err == io.EOF {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if magic[0] != 0x1f || magic[1] != 0x8b {
+		return r, nil
+	}
+	return gzip.NewReader(r)
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+// compositeStagingDir is where we initialize layers which cannot be initialized in place, relative to the workspace.
+// Being on the same file system as the workspace, we can move the content of such layers instead of copying it.
+const compositeStagingDir = ".gitpod/composite-layers"
+
+// CompositeLayer is a layer of a composite initializer
+type CompositeLayer struct {
+	// Initializer produces the initializer of this layer for the location it's initialized in
+	Initializer    func(location string) (Initializer, error)
+	TargetLocation string
+	ConflictPolicy csapi.LayerConflictPolicy
+	Optional       bool
+}
+
+// CompositeInitializer layers the content of several initializers in one workspace. A layer whose target location
+// is empty is initialized in place. All others are initialized in a staging directory and merged with the content
+// of the layers before them according to their conflict policy.
+type CompositeInitializer struct {
+	Location string
+	Layers   []CompositeLayer
+}
+
+// newCompositeInitializer creates a composite initializer based on the request.
+// Returns gRPC errors.
+func newCompositeInitializer(ctx context.Context, loc string, rs storage.DirectDownloader, req *csapi.CompositeInitializer) (*CompositeInitializer, error) {
+	if req == nil || len(req.Layers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "composite initializer has no layers")
+	}
+
+	res := &CompositeInitializer{Location: loc}
+	for i, l := range req.Layers {
+		if l.Initializer == nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("composite initializer layer %d has no initializer", i))
+		}
+		target := filepath.Clean(l.TargetLocation)
+		if filepath.IsAbs(target) || target == ".." || strings.HasPrefix(target, "../") {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("composite initializer layer %d: target location must be within the workspace", i))
+		}
+		// catch invalid layers before we start initializing the workspace
+		_, err := NewFromRequest(ctx, loc, rs, l.Initializer)
+		if err != nil {
+			return nil, err
+		}
+
+		spec := l.Initializer
+		res.Layers = append(res.Layers, CompositeLayer{
+			Initializer: func(location string) (Initializer, error) {
+				return NewFromRequest(ctx, location, rs, spec)
+			},
+			TargetLocation: target,
+			ConflictPolicy: l.ConflictPolicy,
+			Optional:       l.Optional,
+		})
+	}
+	return res, nil
+}
+
+// Run initializes the layers in order. The first layer determines the source of the workspace content.
+func (c *CompositeInitializer) Run(ctx context.Context, mappings []archive.IDMapping) (src csapi.WorkspaceInitSource, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "CompositeInitializer")
+	span.SetTag("layers", len(c.Layers))
+	defer tracing.FinishSpan(span, &err)
+
+	src = csapi.WorkspaceInitFromOther
+	defer os.RemoveAll(filepath.Join(c.Location, compositeStagingDir))
+
+	for i, l := range c.Layers {
+		lsrc, err := c.runLayer(ctx, i, l, mappings)
+		if err != nil && l.Optional {
+			log.WithError(err).WithField("location", c.Location).WithField("layer", i).Warn("optional layer of composite initializer failed - continuing without it")
+			continue
+		}
+		if err != nil {
+			return src, xerrors.Errorf("composite initializer: layer %d: %w", i, err)
+		}
+		if i == 0 {
+			src = lsrc
+		}
+	}
+	return src, nil
+}
+
+func (c *CompositeInitializer) runLayer(ctx context.Context, idx int, l CompositeLayer, mappings []archive.IDMapping) (src csapi.WorkspaceInitSource, err error) {
+	dst := filepath.Join(c.Location, l.TargetLocation)
+	empty, err := isEmptyDir(dst)
+	if err != nil {
+		return src, err
+	}
+	if empty {
+		// there's nothing to conflict with
+		ilr, err := l.Initializer(dst)
+		if err != nil {
+			return src, err
+		}
+		src, err = ilr.Run(ctx, mappings)
+		if err != nil {
+			// don't leave the remains of a failed layer behind
+			if cerr := removeContent(dst); cerr != nil {
+				log.WithError(cerr).WithField("location", dst).Warn("cannot remove content of failed layer")
+			}
+			return src, err
+		}
+		return src, nil
+	}
+
+	stage := filepath.Join(c.Location, compositeStagingDir, strconv.Itoa(idx))
+	err = os.MkdirAll(stage, 0755)
+	if err != nil {
+		return src, xerrors.Errorf("cannot create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stage)
+
+	ilr, err := l.Initializer(stage)
+	if err != nil {
+		return src, err
+	}
+	src, err = ilr.Run(ctx, mappings)
+	if err != nil {
+		return src, err
+	}
+
+	if l.ConflictPolicy == csapi.LayerConflictPolicy_FAIL {
+		// find conflicts before we move anything so that a failing layer leaves the workspace untouched
+		err = mergeLayer(stage, dst, l.ConflictPolicy, true)
+		if err != nil {
+			return src, err
+		}
+	}
+	err = mergeLayer(stage, dst, l.ConflictPolicy, false)
+	if err != nil {
+		return src, err
+	}
+	return src, nil
+}
+
+// mergeLayer moves the content of src to dst. Directories which exist in both are merged, all other files which
+// exist in both are conflicts resolved by policy. With dryRun mergeLayer only looks for conflicts.
+func mergeLayer(src, dst string, policy csapi.LayerConflictPolicy, dryRun bool) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		var (
+			s = filepath.Join(src, e.Name())
+			d = filepath.Join(dst, e.Name())
+		)
+		dstat, err := os.Lstat(d)
+		if os.IsNotExist(err) {
+			if dryRun {
+				continue
+			}
+			err = os.Rename(s, d)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if e.IsDir() && dstat.IsDir() {
+			err = mergeLayer(s, d, policy, dryRun)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch policy {
+		case csapi.LayerConflictPolicy_KEEP_EXISTING:
+			continue
+		case csapi.LayerConflictPolicy_FAIL:
+			return xerrors.Errorf("%s exists already", d)
+		}
+		if dryRun {
+			continue
+		}
+		err = os.RemoveAll(d)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(s, d)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeContent removes everything in dir, including hidden files
+func removeContent(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		err = os.RemoveAll(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isEmptyDir returns true if dir does not exist or is an empty directory
+func isEmptyDir(dir string) (bool, error) {
+	f, err := os.Open(dir)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	_, err = f.Readdirnames(1)
+	if err == io.EOF {
+		return true, nil
+	}
+	return false, err
+}
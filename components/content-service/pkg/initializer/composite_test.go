@@ -0,0 +1,233 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package initializer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+)
+
+// fileInitializer writes files to its location
+type fileInitializer struct {
+	Location string
+	Files    map[string]string
+	Source   csapi.WorkspaceInitSource
+	Err      error
+}
+
+func (f *fileInitializer) Run(ctx context.Context, mappings []archive.IDMapping) (csapi.WorkspaceInitSource, error) {
+	for name, content := range f.Files {
+		fn := filepath.Join(f.Location, name)
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			return "", err
+		}
+		err = os.WriteFile(fn, []byte(content), 0644)
+		if err != nil {
+			return "", err
+		}
+	}
+	return f.Source, f.Err
+}
+
+func fileLayer(files map[string]string, mod func(*fileInitializer)) func(string) (Initializer, error) {
+	return func(location string) (Initializer, error) {
+		res := &fileInitializer{Location: location, Files: files, Source: csapi.WorkspaceInitFromOther}
+		if mod != nil {
+			mod(res)
+		}
+		return res, nil
+	}
+}
+
+func TestCompositeInitializer(t *testing.T) {
+	base := map[string]string{
+		"README.md":         "base",
+		"src/main.go":       "package main",
+		".gitignore":        "node_modules",
+		"package.json":      "{}",
+		"node_modules/.ref": "base",
+	}
+	deps := map[string]string{
+		"node_modules/left-pad/index.js": "module.exports = {}",
+		"node_modules/.ref":              "deps",
+	}
+
+	tests := []struct {
+		Name          string
+		Layers        []CompositeLayer
+		ExpectedFiles map[string]string
+		ExpectedSrc   csapi.WorkspaceInitSource
+		ExpectError   bool
+	}{
+		{
+			Name: "overwrite",
+			Layers: []CompositeLayer{
+				{Initializer: fileLayer(base, func(f *fileInitializer) { f.Source = csapi.WorkspaceInitFromPrebuild })},
+				{Initializer: fileLayer(deps, nil)},
+			},
+			ExpectedFiles: map[string]string{
+				"README.md":                      "base",
+				"src/main.go":                    "package main",
+				".gitignore":                     "node_modules",
+				"package.json":                   "{}",
+				"node_modules/.ref":              "deps",
+				"node_modules/left-pad/index.js": "module.exports = {}",
+			},
+			ExpectedSrc: csapi.WorkspaceInitFromPrebuild,
+		},
+		{
+			Name: "keep existing",
+			Layers: []CompositeLayer{
+				{Initializer: fileLayer(base, nil)},
+				{Initializer: fileLayer(deps, nil), ConflictPolicy: csapi.LayerConflictPolicy_KEEP_EXISTING},
+			},
+			ExpectedFiles: map[string]string{
+				"README.md":                      "base",
+				"src/main.go":                    "package main",
+				".gitignore":                     "node_modules",
+				"package.json":                   "{}",
+				"node_modules/.ref":              "base",
+				"node_modules/left-pad/index.js": "module.exports = {}",
+			},
+			ExpectedSrc: csapi.WorkspaceInitFromOther,
+		},
+		{
+			Name: "conflict fails",
+			Layers: []CompositeLayer{
+				{Initializer: fileLayer(base, nil)},
+				{Initializer: fileLayer(deps, nil), ConflictPolicy: csapi.LayerConflictPolicy_FAIL},
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "conflict fails optional layer",
+			Layers: []CompositeLayer{
+				{Initializer: fileLayer(base, nil)},
+				{Initializer: fileLayer(deps, nil), ConflictPolicy: csapi.LayerConflictPolicy_FAIL, Optional: true},
+			},
+			// the workspace must not contain any of the layer's files
+			ExpectedFiles: base,
+			ExpectedSrc:   csapi.WorkspaceInitFromOther,
+		},
+		{
+			Name: "failing optional layer",
+			Layers: []CompositeLayer{
+				{Initializer: fileLayer(map[string]string{"README.md": "base"}, nil)},
+				{Initializer: fileLayer(map[string]string{"cache/a": "a"}, func(f *fileInitializer) { f.Err = xerrors.Errorf("download failed") }), TargetLocation: "cache", Optional: true},
+			},
+			ExpectedFiles: map[string]string{"README.md": "base"},
+			ExpectedSrc:   csapi.WorkspaceInitFromOther,
+		},
+		{
+			Name: "failing layer",
+			Layers: []CompositeLayer{
+				{Initializer: fileLayer(base, nil)},
+				{Initializer: fileLayer(deps, func(f *fileInitializer) { f.Err = xerrors.Errorf("download failed") })},
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "target location",
+			Layers: []CompositeLayer{
+				{Initializer: fileLayer(map[string]string{"README.md": "base"}, nil)},
+				{Initializer: fileLayer(map[string]string{"index.js": "left-pad"}, nil), TargetLocation: "node_modules/left-pad"},
+			},
+			ExpectedFiles: map[string]string{
+				"README.md":                      "base",
+				"node_modules/left-pad/index.js": "left-pad",
+			},
+			ExpectedSrc: csapi.WorkspaceInitFromOther,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			loc := t.TempDir()
+			ini := &CompositeInitializer{Location: loc, Layers: test.Layers}
+			src, err := ini.Run(context.Background(), nil)
+			if test.ExpectError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if src != test.ExpectedSrc {
+				t.Errorf("unexpected init source: want %s, got %s", test.ExpectedSrc, src)
+			}
+
+			files := make(map[string]string)
+			err = filepath.Walk(loc, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				rel, _ := filepath.Rel(loc, path)
+				files[rel] = string(content)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.ExpectedFiles, files); diff != "" {
+				t.Errorf("unexpected workspace content (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewCompositeInitializer(t *testing.T) {
+	empty := &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Empty{Empty: &csapi.EmptyInitializer{}}}
+	tests := []struct {
+		Name   string
+		Layers []*csapi.CompositeInitializerLayer
+		Valid  bool
+	}{
+		{Name: "no layers"},
+		{Name: "missing initializer", Layers: []*csapi.CompositeInitializerLayer{{}}},
+		{Name: "absolute target location", Layers: []*csapi.CompositeInitializerLayer{{Initializer: empty, TargetLocation: "/etc"}}},
+		{Name: "target location outside the workspace", Layers: []*csapi.CompositeInitializerLayer{{Initializer: empty, TargetLocation: "foo/../../bar"}}},
+		{
+			Name:   "invalid layer",
+			Layers: []*csapi.CompositeInitializerLayer{{Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Git{Git: &csapi.GitInitializer{}}}}},
+		},
+		{
+			Name: "valid",
+			Layers: []*csapi.CompositeInitializerLayer{
+				{Initializer: empty},
+				{Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Archive{Archive: &csapi.ArchiveInitializer{Url: "https://example.com/deps.tar.gz"}}}, TargetLocation: "node_modules"},
+			},
+			Valid: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Composite{Composite: &csapi.CompositeInitializer{Layers: test.Layers}}}
+			_, err := NewFromRequest(context.Background(), t.TempDir(), nil, req)
+			if test.Valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -98,6 +98,17 @@ func NewFromRequest(ctx context.Context, loc string, rs storage.DirectDownloader
 		}
 	} else if ir, ok := spec.(*csapi.WorkspaceInitializer_Snapshot); ok {
 		initializer, err = newSnapshotInitializer(loc, rs, ir.Snapshot)
+	} else if ir, ok := spec.(*csapi.WorkspaceInitializer_Composite); ok {
+		initializer, err = newCompositeInitializer(ctx, loc, rs, ir.Composite)
+		if err != nil {
+			return nil, err
+		}
+	} else if ir, ok := spec.(*csapi.WorkspaceInitializer_Archive); ok {
+		if ir.Archive == nil || ir.Archive.Url == "" {
+			return nil, status.Error(codes.InvalidArgument, "missing archive initializer URL")
+		}
+
+		initializer = &ArchiveInitializer{Location: loc, URL: ir.Archive.Url}
 	} else {
 		initializer = &EmptyInitializer{}
 	}
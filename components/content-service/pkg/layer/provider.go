@@ -190,6 +190,10 @@ func (s *Provider) GetContentLayer(ctx context.Context, owner, workspaceID strin
 			return
 		}
 	}
+	if cis := initializer.GetComposite(); cis != nil {
+		span.LogKV("initializer", "composite")
+		return s.getCompositeContentLayer(ctx, initializer)
+	}
 	if gis := initializer.GetGit(); gis != nil {
 		span.LogKV("initializer", "Git")
 
@@ -250,6 +254,63 @@ func (s *Provider) getSnapshotContentLayer(ctx context.Context, sp *csapi.Snapsh
 	return l, manifest, nil
 }
 
+// getCompositeContentLayer runs a composite initializer in the workspace. Snapshots are downloaded from presigned URLs,
+// hence composite initializers cannot restore full workspace snapshots which consist of several layers.
+func (s *Provider) getCompositeContentLayer(ctx context.Context, initializer *csapi.WorkspaceInitializer) (l []Layer, manifest *csapi.WorkspaceContentManifest, err error) {
+	span, ctx := tracing.FromContext(ctx, "getCompositeContentLayer")
+	defer tracing.FinishSpan(span, &err)
+
+	urls := make(map[string]string)
+	for _, snapshot := range snapshotsOf(initializer) {
+		segs := strings.Split(snapshot, "@")
+		if len(segs) != 2 {
+			return nil, nil, xerrors.Errorf("invalid snapshot FQN: %s", snapshot)
+		}
+		obj, bkt := segs[0], segs[1]
+
+		mf, info, err := s.downloadContentManifest(ctx, bkt, obj)
+		if err == storage.ErrNotFound {
+			return nil, nil, xerrors.Errorf("invalid snapshot: %w", err)
+		}
+		if err != nil && err != errUnsupportedContentType {
+			return nil, nil, err
+		}
+		if mf != nil {
+			return nil, nil, xerrors.Errorf("snapshot %s is a full workspace snapshot, which composite initializers do not support", snapshot)
+		}
+		urls[snapshot] = info.URL
+	}
+
+	cdesc, err := executor.Prepare(initializer, urls)
+	if err != nil {
+		return nil, nil, err
+	}
+	layer, err := contentDescriptorToLayer(cdesc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []Layer{*layer}, nil, nil
+}
+
+// snapshotsOf returns the names of all snapshots an initializer restores
+func snapshotsOf(initializer *csapi.WorkspaceInitializer) []string {
+	var res []string
+	if sp := initializer.GetSnapshot(); sp != nil {
+		res = append(res, sp.Snapshot)
+	}
+	if pb := initializer.GetPrebuild(); pb != nil && pb.Prebuild != nil {
+		res = append(res, pb.Prebuild.Snapshot)
+	}
+	if c := initializer.GetComposite(); c != nil {
+		for _, l := range c.Layers {
+			if l.Initializer != nil {
+				res = append(res, snapshotsOf(l.Initializer)...)
+			}
+		}
+	}
+	return res
+}
+
 func (s *Provider) getPrebuildContentLayer(ctx context.Context, pb *csapi.PrebuildInitializer) (l []Layer, manifest *csapi.WorkspaceContentManifest, err error) {
 	span, ctx := tracing.FromContext(ctx, "getPrebuildContentLayer")
 	defer tracing.FinishSpan(span, &err)
@@ -240,6 +240,10 @@ func (s *testStorage) DeleteObject(ctx context.Context, bucket string, query *st
 	return nil
 }
 
+func (s *testStorage) SetStorageClass(ctx context.Context, bucket, prefix, class string) (size int64, err error) {
+	return 0, nil
+}
+
 type roundTripFunc func(req *http.Request) *http.Response
 
 // RoundTrip .
@@ -0,0 +1,313 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package qos provides a prioritized job queue for content up- and downloads.
+// Jobs of the interactive class (e.g. restores users wait for) are admitted before
+// jobs of the background class (e.g. prebuild snapshots), so that a storm of prebuilds
+// cannot starve workspace starts.
+package qos
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"golang.org/x/xerrors"
+)
+
+// Class is the quality of service class of a content job
+type Class string
+
+const (
+	// ClassInteractive is for jobs a user is waiting for, e.g. restoring a workspace's content
+	ClassInteractive Class = "interactive"
+
+	// ClassBackground is for jobs nobody is waiting for, e.g. uploading prebuild snapshots
+	ClassBackground Class = "background"
+)
+
+// Config configures the job queue
+type Config struct {
+	// MaxConcurrency limits the number of jobs running at the same time across all classes.
+	// Zero means no limit.
+	MaxConcurrency int `json:"maxConcurrency"`
+
+	// Classes configures the individual QoS classes. Classes which are not configured
+	// use the defaults of DefaultClasses.
+	Classes map[Class]ClassConfig `json:"classes,omitempty"`
+}
+
+// ClassConfig configures a QoS class
+type ClassConfig struct {
+	// Priority determines the order in which waiting jobs are admitted. Jobs of classes with a higher priority
+	// are admitted first.
+	Priority int `json:"priority"`
+
+	// Concurrency limits the number of jobs of this class running at the same time. Zero means no limit.
+	Concurrency int `json:"concurrency"`
+
+	// RateLimit limits the number of jobs of this class started per second. Zero means no limit.
+	RateLimit float64 `json:"rateLimit,omitempty"`
+
+	// Burst is the number of jobs which can start at once despite the rate limit. Defaults to one.
+	Burst int `json:"burst,omitempty"`
+}
+
+// DefaultClasses are the QoS classes used if the configuration does not set them
+var DefaultClasses = map[Class]ClassConfig{
+	ClassInteractive: {Priority: 100},
+	ClassBackground:  {Priority: 0},
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxConcurrency < 0 {
+		return xerrors.Errorf("maxConcurrency must not be negative")
+	}
+	for cls, cc := range c.Classes {
+		if _, ok := DefaultClasses[cls]; !ok {
+			return xerrors.Errorf("unknown QoS class %q", cls)
+		}
+		if cc.Concurrency < 0 || cc.RateLimit < 0 || cc.Burst < 0 {
+			return xerrors.Errorf("QoS class %s: concurrency, rateLimit and burst must not be negative", cls)
+		}
+	}
+	return nil
+}
+
+// Queue admits content jobs according to their QoS class
+type Queue struct {
+	maxConcurrency int
+	classes        map[Class]*class
+	// order lists the classes by descending priority
+	order []Class
+
+	mu      sync.Mutex
+	running int
+
+	metrics struct {
+		waiting  *prometheus.GaugeVec
+		running  *prometheus.GaugeVec
+		waitTime *prometheus.HistogramVec
+		jobs     *prometheus.CounterVec
+	}
+}
+
+type class struct {
+	ClassConfig
+
+	limiter *rate.Limiter
+	running int
+	waiting []*waiter
+}
+
+type waiter struct {
+	admitted chan struct{}
+}
+
+// NewQueue creates a new job queue. A nil configuration produces a queue which uses the default classes
+// and imposes no limits.
+func NewQueue(cfg *Config) (*Queue, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Queue{
+		maxConcurrency: cfg.MaxConcurrency,
+		classes:        make(map[Class]*class, len(DefaultClasses)),
+	}
+	for cls, cc := range DefaultClasses {
+		if c, ok := cfg.Classes[cls]; ok {
+			cc = c
+		}
+		c := &class{ClassConfig: cc}
+		if cc.RateLimit > 0 {
+			burst := cc.Burst
+			if burst == 0 {
+				burst = 1
+			}
+			c.limiter = rate.NewLimiter(rate.Limit(cc.RateLimit), burst)
+		}
+		q.classes[cls] = c
+		q.order = append(q.order, cls)
+	}
+	sort.Slice(q.order, func(i, j int) bool {
+		pi, pj := q.classes[q.order[i]].Priority, q.classes[q.order[j]].Priority
+		if pi != pj {
+			return pi > pj
+		}
+		return q.order[i] < q.order[j]
+	})
+
+	q.metrics.waiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "content_jobs_waiting",
+		Help: "Content jobs waiting to be admitted by QoS class",
+	}, []string{"class"})
+	q.metrics.running = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "content_jobs_running",
+		Help: "Content jobs currently running by QoS class",
+	}, []string{"class"})
+	q.metrics.waitTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "content_job_wait_seconds",
+		Help:    "Time content jobs spent waiting for admission by QoS class",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"class"})
+	q.metrics.jobs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "content_jobs_total",
+		Help: "Content jobs by QoS class and outcome",
+	}, []string{"class", "outcome"})
+
+	return q, nil
+}
+
+// RegisterMetrics registers the queue depth metrics
+func (q *Queue) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{
+		q.metrics.waiting,
+		q.metrics.running,
+		q.metrics.waitTime,
+		q.metrics.jobs,
+	} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Do runs a job once the queue admits it. Do returns the error of the job, or the context's error
+// if the context is done before the job was admitted.
+func (q *Queue) Do(ctx context.Context, cls Class, job func(ctx context.Context) error) (err error) {
+	defer func() {
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+		}
+		q.metrics.jobs.WithLabelValues(string(cls), outcome).Inc()
+	}()
+
+	release, err := q.acquire(ctx, cls)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return job(ctx)
+}
+
+func (q *Queue) acquire(ctx context.Context, cls Class) (release func(), err error) {
+	c, ok := q.classes[cls]
+	if !ok {
+		return nil, xerrors.Errorf("unknown QoS class %q", cls)
+	}
+
+	start := time.Now()
+	if c.limiter != nil {
+		err = c.limiter.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w := &waiter{admitted: make(chan struct{})}
+	q.mu.Lock()
+	c.waiting = append(c.waiting, w)
+	q.metrics.waiting.WithLabelValues(string(cls)).Inc()
+	q.admit()
+	q.mu.Unlock()
+
+	select {
+	case <-w.admitted:
+	case <-ctx.Done():
+		q.mu.Lock()
+		select {
+		case <-w.admitted:
+			// we were admitted while the context finished - give the slot back
+			q.releaseLocked(cls)
+		default:
+			q.dequeueLocked(cls, w)
+		}
+		q.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	q.metrics.waitTime.WithLabelValues(string(cls)).Observe(time.Since(start).Seconds())
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			q.releaseLocked(cls)
+			q.mu.Unlock()
+		})
+	}, nil
+}
+
+// admit starts waiting jobs in order of their class priority as long as there are free slots.
+// Jobs of lower priority classes only start if no job of a higher priority class is waiting for a free slot.
+// Callers must hold q.mu.
+func (q *Queue) admit() {
+	for _, cls := range q.order {
+		c := q.classes[cls]
+		for len(c.waiting) > 0 {
+			if q.maxConcurrency > 0 && q.running >= q.maxConcurrency {
+				return
+			}
+			if c.Concurrency > 0 && c.running >= c.Concurrency {
+				break
+			}
+
+			w := c.waiting[0]
+			c.waiting = c.waiting[1:]
+			c.running++
+			q.running++
+			q.metrics.waiting.WithLabelValues(string(cls)).Dec()
+			q.metrics.running.WithLabelValues(string(cls)).Inc()
+			close(w.admitted)
+		}
+	}
+}
+
+// releaseLocked frees the slot of a job of the class. Callers must hold q.mu.
+func (q *Queue) releaseLocked(cls Class) {
+	q.classes[cls].running--
+	q.running--
+	q.metrics.running.WithLabelValues(string(cls)).Dec()
+	q.admit()
+}
+
+// dequeueLocked removes a waiting job. Callers must hold q.mu.
+func (q *Queue) dequeueLocked(cls Class, w *waiter) {
+	c := q.classes[cls]
+	for i, o := range c.waiting {
+		if o != w {
+			continue
+		}
+		c.waiting = append(c.waiting[:i], c.waiting[i+1:]...)
+		q.metrics.waiting.WithLabelValues(string(cls)).Dec()
+		return
+	}
+}
+
+// Waiting returns the number of jobs of a class waiting for admission
+func (q *Queue) Waiting(cls Class) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	c, ok := q.classes[cls]
+	if !ok {
+		return 0
+	}
+	return len(c.waiting)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package qos
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+)
+
+func TestInteractiveJobsGoFirst(t *testing.T) {
+	q, err := NewQueue(&Config{MaxConcurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		block = make(chan struct{})
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		order []Class
+	)
+	run := func(cls Class) {
+		defer wg.Done()
+		err := q.Do(context.Background(), cls, func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, cls)
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// occupy the only slot so that all other jobs have to wait
+	started := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = q.Do(context.Background(), ClassBackground, func(ctx context.Context) error {
+			close(started)
+			<-block
+			return nil
+		})
+	}()
+	<-started
+
+	wg.Add(2)
+	go run(ClassBackground)
+	go run(ClassBackground)
+	waitFor(t, func() bool { return q.Waiting(ClassBackground) == 2 })
+	wg.Add(1)
+	go run(ClassInteractive)
+	waitFor(t, func() bool { return q.Waiting(ClassInteractive) == 1 })
+
+	close(block)
+	wg.Wait()
+
+	if diff := cmp.Diff([]Class{ClassInteractive, ClassBackground, ClassBackground}, order); diff != "" {
+		t.Errorf("unexpected admission order (-want +got):\n%s", diff)
+	}
+}
+
+func TestClassConcurrency(t *testing.T) {
+	q, err := NewQueue(&Config{Classes: map[Class]ClassConfig{
+		ClassBackground: {Concurrency: 1},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = q.Do(context.Background(), ClassBackground, func(ctx context.Context) error {
+			close(started)
+			<-block
+			return nil
+		})
+	}()
+	<-started
+	defer close(block)
+
+	// the background class is at its limit, but interactive jobs are not affected by that
+	err = q.Do(context.Background(), ClassInteractive, func(ctx context.Context) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = q.Do(ctx, ClassBackground, func(ctx context.Context) error {
+		t.Error("background job ran despite the concurrency limit")
+		return nil
+	})
+	if !xerrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if n := q.Waiting(ClassBackground); n != 0 {
+		t.Errorf("canceled job is still waiting: %d", n)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	q, err := NewQueue(&Config{Classes: map[Class]ClassConfig{
+		ClassBackground: {RateLimit: 0.001, Burst: 1},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noop := func(ctx context.Context) error { return nil }
+	err = q.Do(context.Background(), ClassBackground, noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = q.Do(ctx, ClassBackground, noop)
+	if err == nil {
+		t.Fatal("expected the rate limit to hold back the second job")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *Config
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "defaults", Config: &Config{}},
+		{Name: "valid", Config: &Config{MaxConcurrency: 4, Classes: map[Class]ClassConfig{ClassBackground: {Concurrency: 2, RateLimit: 1}}}},
+		{Name: "negative max", Config: &Config{MaxConcurrency: -1}, Error: true},
+		{Name: "unknown class", Config: &Config{Classes: map[Class]ClassConfig{"urgent": {}}}, Error: true},
+		{Name: "negative concurrency", Config: &Config{Classes: map[Class]ClassConfig{ClassInteractive: {Concurrency: -1}}}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition was not met in time")
+}
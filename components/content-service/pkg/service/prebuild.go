@@ -0,0 +1,400 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+const (
+	// maxPrebuildIndexSize limits how much we read when downloading the prebuild index of a project
+	maxPrebuildIndexSize = 4 << 20
+
+	prebuildIndexContentType = "application/json"
+)
+
+// PrebuildQuotaConfig configures how much storage the prebuilds of a project may use
+type PrebuildQuotaConfig struct {
+	// DefaultQuota is the number of bytes the prebuild archives of a project may use. If zero, prebuild storage is not limited.
+	DefaultQuota int64 `json:"defaultQuota,omitempty"`
+
+	// Projects overrides the quota of individual projects, keyed by project ID
+	Projects map[string]int64 `json:"projects,omitempty"`
+}
+
+// Quota returns the number of bytes the prebuilds of a project may use
+func (c PrebuildQuotaConfig) Quota(projectID string) int64 {
+	if q, ok := c.Projects[projectID]; ok {
+		return q
+	}
+	return c.DefaultQuota
+}
+
+// PrebuildStorageService implements PrebuildStorageServiceServer
+type PrebuildStorageService struct {
+	cfg PrebuildQuotaConfig
+	s   storage.PresignedAccess
+
+	// mu serialises the read-modify-write cycles of the project indices
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewPrebuildStorageService creates a new prebuild storage service
+func NewPrebuildStorageService(cfg PrebuildQuotaConfig, storageCfg storage.Config) (*PrebuildStorageService, error) {
+	s, err := storage.NewPresignedAccess(&storageCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newPrebuildStorageService(cfg, s), nil
+}
+
+func newPrebuildStorageService(cfg PrebuildQuotaConfig, s storage.PresignedAccess) *PrebuildStorageService {
+	return &PrebuildStorageService{
+		cfg: cfg,
+		s:   s,
+		now: time.Now,
+	}
+}
+
+// prebuildIndex tracks the prebuild archives of a project. We keep it in the bucket next to the archives.
+type prebuildIndex struct {
+	Branches map[string]*prebuildBranch `json:"branches"`
+}
+
+type prebuildBranch struct {
+	LastActivity time.Time         `json:"lastActivity"`
+	Archives     []prebuildArchive `json:"archives"`
+}
+
+type prebuildArchive struct {
+	SnapshotName string    `json:"snapshot"`
+	Objects      []string  `json:"objects"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func prebuildIndexObject(projectID string) string {
+	return fmt.Sprintf("prebuilds/%s/index.json", projectID)
+}
+
+// RegisterPrebuild accounts a prebuild snapshot against the storage quota of its project
+func (cs *PrebuildStorageService) RegisterPrebuild(ctx context.Context, req *api.RegisterPrebuildRequest) (resp *api.RegisterPrebuildResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RegisterPrebuild")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("project", req.ProjectId)
+	span.SetTag("snapshot", req.SnapshotName)
+	defer tracing.FinishSpan(span, &err)
+
+	if err := validatePrebuildProject(req.ProjectId, req.Branch); err != nil {
+		return nil, err
+	}
+	bkt, obj, err := storage.ParseSnapshotName(req.SnapshotName)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if bkt != cs.s.Bucket(req.OwnerId) {
+		return nil, status.Error(codes.PermissionDenied, "snapshot does not belong to this user")
+	}
+
+	archive, err := cs.describeArchive(ctx, bkt, obj)
+	if err != nil {
+		return nil, err
+	}
+	archive.SnapshotName = req.SnapshotName
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	idx, err := cs.loadIndex(ctx, bkt, req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	now := cs.now()
+	archive.CreatedAt = now
+	idx.forget(req.SnapshotName)
+	branch, ok := idx.Branches[req.Branch]
+	if !ok {
+		branch = &prebuildBranch{}
+		idx.Branches[req.Branch] = branch
+	}
+	branch.LastActivity = now
+	branch.Archives = append(branch.Archives, *archive)
+
+	quota := cs.cfg.Quota(req.ProjectId)
+	evicted := cs.evict(ctx, bkt, idx, quota, req.SnapshotName)
+
+	err = cs.saveIndex(ctx, bkt, req.ProjectId, idx)
+	if err != nil {
+		return nil, err
+	}
+	if len(evicted) > 0 {
+		log.WithField("project", req.ProjectId).WithField("evicted", evicted).Info("evicted prebuilds to stay within the project's storage quota")
+	}
+
+	return &api.RegisterPrebuildResponse{
+		Usage:            idx.usage(req.ProjectId, quota),
+		EvictedSnapshots: evicted,
+	}, nil
+}
+
+// TouchBranch records activity on a branch
+func (cs *PrebuildStorageService) TouchBranch(ctx context.Context, req *api.TouchBranchRequest) (resp *api.TouchBranchResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "TouchBranch")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("project", req.ProjectId)
+	defer tracing.FinishSpan(span, &err)
+
+	if err := validatePrebuildProject(req.ProjectId, req.Branch); err != nil {
+		return nil, err
+	}
+	bkt := cs.s.Bucket(req.OwnerId)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	idx, err := cs.loadIndex(ctx, bkt, req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	branch, ok := idx.Branches[req.Branch]
+	if !ok {
+		// branches without prebuilds have nothing we could evict
+		return &api.TouchBranchResponse{}, nil
+	}
+	branch.LastActivity = cs.now()
+
+	err = cs.saveIndex(ctx, bkt, req.ProjectId, idx)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TouchBranchResponse{}, nil
+}
+
+// GetPrebuildStorageUsage reports how much storage the prebuilds of a project use
+func (cs *PrebuildStorageService) GetPrebuildStorageUsage(ctx context.Context, req *api.GetPrebuildStorageUsageRequest) (resp *api.GetPrebuildStorageUsageResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetPrebuildStorageUsage")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("project", req.ProjectId)
+	defer tracing.FinishSpan(span, &err)
+
+	if err := validatePrebuildProject(req.ProjectId, "-"); err != nil {
+		return nil, err
+	}
+
+	idx, err := cs.loadIndex(ctx, cs.s.Bucket(req.OwnerId), req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	return &api.GetPrebuildStorageUsageResponse{
+		Usage: idx.usage(req.ProjectId, cs.cfg.Quota(req.ProjectId)),
+	}, nil
+}
+
+func validatePrebuildProject(projectID, branch string) error {
+	if projectID == "" || strings.ContainsAny(projectID, "/@") {
+		return status.Error(codes.InvalidArgument, "invalid project ID")
+	}
+	if branch == "" {
+		return status.Error(codes.InvalidArgument, "branch is missing")
+	}
+	return nil
+}
+
+// describeArchive finds the objects which make up a prebuild snapshot and their size. Full workspace
+// backups consist of their content manifest and the layers it references.
+func (cs *PrebuildStorageService) describeArchive(ctx context.Context, bkt, obj string) (*prebuildArchive, error) {
+	info, err := cs.s.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
+	if err == storage.ErrNotFound {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("%s@%s not found", obj, bkt))
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+
+	res := &prebuildArchive{
+		Objects: []string{obj},
+		Size:    info.Size,
+	}
+	if info.Meta.ContentType != api.ContentTypeManifest {
+		return res, nil
+	}
+
+	content, err := fetchObject(ctx, info.URL, maxWorkspaceContentManifestSize)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	var mf api.WorkspaceContentManifest
+	err = json.Unmarshal(content, &mf)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("cannot parse workspace content manifest: %v", err))
+	}
+	for _, l := range mf.Layers {
+		if l.Bucket != bkt {
+			return nil, status.Error(codes.PermissionDenied, "snapshot layer does not belong to this user")
+		}
+		res.Objects = append(res.Objects, l.Object)
+		res.Size += l.Size
+	}
+	return res, nil
+}
+
+func (cs *PrebuildStorageService) loadIndex(ctx context.Context, bkt, projectID string) (*prebuildIndex, error) {
+	idx := &prebuildIndex{Branches: make(map[string]*prebuildBranch)}
+
+	info, err := cs.s.SignDownload(ctx, bkt, prebuildIndexObject(projectID), &storage.SignedURLOptions{})
+	if err == storage.ErrNotFound {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	content, err := fetchObject(ctx, info.URL, maxPrebuildIndexSize)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	err = json.Unmarshal(content, idx)
+	if err != nil {
+		return nil, status.Error(codes.DataLoss, fmt.Sprintf("cannot parse prebuild index: %v", err))
+	}
+	if idx.Branches == nil {
+		idx.Branches = make(map[string]*prebuildBranch)
+	}
+	return idx, nil
+}
+
+func (cs *PrebuildStorageService) saveIndex(ctx context.Context, bkt, projectID string, idx *prebuildIndex) error {
+	content, err := json.Marshal(idx)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	err = putObject(ctx, cs.s, bkt, prebuildIndexObject(projectID), prebuildIndexContentType, bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return status.Error(codes.Unavailable, xerrors.Errorf("cannot store prebuild index: %w", err).Error())
+	}
+	return nil
+}
+
+// evict deletes prebuild archives until the project is within its quota. We evict the archives of the branches
+// with the least recent activity first, and the oldest archives of a branch before its newer ones.
+// The archive named keep is never evicted, even if it alone exceeds the quota.
+func (cs *PrebuildStorageService) evict(ctx context.Context, bkt string, idx *prebuildIndex, quota int64, keep string) (evicted []string) {
+	if quota <= 0 {
+		return nil
+	}
+
+	type candidate struct {
+		Branch       string
+		LastActivity time.Time
+		Archive      prebuildArchive
+	}
+	var (
+		candidates []candidate
+		used       int64
+	)
+	for name, b := range idx.Branches {
+		for _, a := range b.Archives {
+			used += a.Size
+			if a.SnapshotName == keep {
+				continue
+			}
+			candidates = append(candidates, candidate{Branch: name, LastActivity: b.LastActivity, Archive: a})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if !ci.LastActivity.Equal(cj.LastActivity) {
+			return ci.LastActivity.Before(cj.LastActivity)
+		}
+		if ci.Branch != cj.Branch {
+			return ci.Branch < cj.Branch
+		}
+		return ci.Archive.CreatedAt.Before(cj.Archive.CreatedAt)
+	})
+
+	for _, c := range candidates {
+		if used <= quota {
+			break
+		}
+
+		err := cs.deleteArchive(ctx, bkt, c.Archive)
+		if err != nil {
+			// we keep the archive in the index and try again next time
+			log.WithError(err).WithField("snapshot", c.Archive.SnapshotName).Warn("cannot evict prebuild")
+			continue
+		}
+		idx.forget(c.Archive.SnapshotName)
+		used -= c.Archive.Size
+		evicted = append(evicted, c.Archive.SnapshotName)
+	}
+	return evicted
+}
+
+func (cs *PrebuildStorageService) deleteArchive(ctx context.Context, bkt string, a prebuildArchive) error {
+	// Delete the root object last so that a partially deleted archive is still in the index
+	for i := len(a.Objects) - 1; i >= 0; i-- {
+		err := cs.s.DeleteObject(ctx, bkt, &storage.DeleteObjectQuery{Name: a.Objects[i]})
+		if err != nil && err != storage.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// forget removes a snapshot from the index, and the branch with it if it has no archives left
+func (idx *prebuildIndex) forget(snapshotName string) {
+	for name, b := range idx.Branches {
+		for i, a := range b.Archives {
+			if a.SnapshotName != snapshotName {
+				continue
+			}
+			b.Archives = append(b.Archives[:i], b.Archives[i+1:]...)
+			if len(b.Archives) == 0 {
+				delete(idx.Branches, name)
+			}
+			return
+		}
+	}
+}
+
+func (idx *prebuildIndex) usage(projectID string, quota int64) *api.PrebuildStorageUsage {
+	res := &api.PrebuildStorageUsage{
+		ProjectId:  projectID,
+		QuotaBytes: quota,
+	}
+	for name, b := range idx.Branches {
+		lastActivity, _ := ptypes.TimestampProto(b.LastActivity)
+		bu := &api.BranchStorageUsage{
+			Branch:       name,
+			LastActivity: lastActivity,
+		}
+		for _, a := range b.Archives {
+			bu.UsedBytes += a.Size
+			bu.Snapshots = append(bu.Snapshots, a.SnapshotName)
+		}
+		res.UsedBytes += bu.UsedBytes
+		res.Branches = append(res.Branches, bu)
+	}
+	sort.Slice(res.Branches, func(i, j int) bool { return res.Branches[i].Branch < res.Branches[j].Branch })
+	return res
+}
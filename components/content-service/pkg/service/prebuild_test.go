@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/content-service/api"
+)
+
+func TestPrebuildStorageQuota(t *testing.T) {
+	ctx := context.Background()
+	st := newMemStorage(t, "prebuilds")
+	bkt := st.Bucket("owner")
+	cs := newPrebuildStorageService(PrebuildQuotaConfig{DefaultQuota: 100, Projects: map[string]int64{"huge": 1000}}, st)
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	cs.now = func() time.Time { return now }
+
+	register := func(branch, obj string, size int) *api.RegisterPrebuildResponse {
+		t.Helper()
+		now = now.Add(time.Minute)
+		st.Put(bkt, obj, memObject{Content: strings.Repeat("x", size)})
+		resp, err := cs.RegisterPrebuild(ctx, &api.RegisterPrebuildRequest{
+			OwnerId:      "owner",
+			ProjectId:    "project",
+			Branch:       branch,
+			SnapshotName: fmt.Sprintf("%s@%s", obj, bkt),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	register("main", "workspaces/a/snapshot-1.tar", 30)
+	register("stale", "workspaces/b/snapshot-1.tar", 30)
+	register("main", "workspaces/c/snapshot-1.tar", 30)
+
+	// main is active again, so the stale branch's archive goes first
+	now = now.Add(time.Minute)
+	_, err := cs.TouchBranch(ctx, &api.TouchBranchRequest{OwnerId: "owner", ProjectId: "project", Branch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := register("feature", "workspaces/d/snapshot-1.tar", 30)
+	if diff := cmp.Diff([]string{"workspaces/b/snapshot-1.tar@" + bkt}, resp.EvictedSnapshots); diff != "" {
+		t.Errorf("unexpected evictions (-want +got):\n%s", diff)
+	}
+	if _, ok := st.Get(bkt, "workspaces/b/snapshot-1.tar"); ok {
+		t.Error("evicted archive is still in storage")
+	}
+	if resp.Usage.UsedBytes != 90 || resp.Usage.QuotaBytes != 100 {
+		t.Errorf("unexpected usage: %d of %d bytes", resp.Usage.UsedBytes, resp.Usage.QuotaBytes)
+	}
+
+	// within a branch the oldest archive goes first
+	resp = register("feature", "workspaces/e/snapshot-1.tar", 30)
+	if diff := cmp.Diff([]string{"workspaces/a/snapshot-1.tar@" + bkt}, resp.EvictedSnapshots); diff != "" {
+		t.Errorf("unexpected evictions (-want +got):\n%s", diff)
+	}
+
+	// the usage survives in storage and is reported per branch
+	usage, err := newPrebuildStorageService(cs.cfg, st).GetPrebuildStorageUsage(ctx, &api.GetPrebuildStorageUsageRequest{OwnerId: "owner", ProjectId: "project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var branches []string
+	for _, b := range usage.Usage.Branches {
+		branches = append(branches, fmt.Sprintf("%s:%d:%v", b.Branch, b.UsedBytes, b.Snapshots))
+	}
+	expected := []string{
+		"feature:60:[workspaces/d/snapshot-1.tar@" + bkt + " workspaces/e/snapshot-1.tar@" + bkt + "]",
+		"main:30:[workspaces/c/snapshot-1.tar@" + bkt + "]",
+	}
+	if diff := cmp.Diff(expected, branches); diff != "" {
+		t.Errorf("unexpected branch usage (-want +got):\n%s", diff)
+	}
+
+	// an archive larger than the quota is kept, but everything else goes
+	resp = register("main", "workspaces/f/snapshot-1.tar", 150)
+	if len(resp.EvictedSnapshots) != 3 {
+		t.Errorf("expected all other archives to be evicted, got %v", resp.EvictedSnapshots)
+	}
+	if resp.Usage.UsedBytes != 150 {
+		t.Errorf("unexpected usage: %d bytes", resp.Usage.UsedBytes)
+	}
+}
+
+func TestPrebuildStorageManifest(t *testing.T) {
+	ctx := context.Background()
+	st := newMemStorage(t, "prebuilds")
+	bkt := st.Bucket("owner")
+	cs := newPrebuildStorageService(PrebuildQuotaConfig{DefaultQuota: 100}, st)
+
+	st.Put(bkt, "workspaces/a/wsfull-1.tar", memObject{Content: strings.Repeat("x", 80)})
+	mf, err := json.Marshal(api.WorkspaceContentManifest{
+		Type: api.TypeFullWorkspaceContentV1,
+		Layers: []api.WorkspaceContentLayer{
+			{Descriptor: ociv1.Descriptor{Size: 80}, Bucket: bkt, Object: "workspaces/a/wsfull-1.tar"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Put(bkt, "workspaces/a/snapshot-1.mf.json", memObject{Content: string(mf), ContentType: api.ContentTypeManifest})
+
+	resp, err := cs.RegisterPrebuild(ctx, &api.RegisterPrebuildRequest{OwnerId: "owner", ProjectId: "project", Branch: "main", SnapshotName: "workspaces/a/snapshot-1.mf.json@" + bkt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := int64(len(mf) + 80); resp.Usage.UsedBytes != expected {
+		t.Errorf("expected the layers to count towards the usage: got %d, expected %d", resp.Usage.UsedBytes, expected)
+	}
+
+	st.Put(bkt, "workspaces/b/snapshot-1.tar", memObject{Content: "small"})
+	resp, err = cs.RegisterPrebuild(ctx, &api.RegisterPrebuildRequest{OwnerId: "owner", ProjectId: "project", Branch: "other", SnapshotName: "workspaces/b/snapshot-1.tar@" + bkt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.EvictedSnapshots) != 1 {
+		t.Fatalf("expected the full backup to be evicted, got %v", resp.EvictedSnapshots)
+	}
+	for _, obj := range []string{"workspaces/a/snapshot-1.mf.json", "workspaces/a/wsfull-1.tar"} {
+		if _, ok := st.Get(bkt, obj); ok {
+			t.Errorf("%s is still in storage", obj)
+		}
+	}
+}
+
+func TestPrebuildStorageInvalidRequests(t *testing.T) {
+	ctx := context.Background()
+	st := newMemStorage(t, "prebuilds")
+	cs := newPrebuildStorageService(PrebuildQuotaConfig{}, st)
+	st.Put(st.Bucket("owner"), "workspaces/a/snapshot-1.tar", memObject{Content: "content"})
+
+	tests := []struct {
+		Name string
+		Req  *api.RegisterPrebuildRequest
+		Code codes.Code
+	}{
+		{"invalid project", &api.RegisterPrebuildRequest{OwnerId: "owner", ProjectId: "../x", Branch: "main", SnapshotName: "workspaces/a/snapshot-1.tar@" + st.Bucket("owner")}, codes.InvalidArgument},
+		{"missing branch", &api.RegisterPrebuildRequest{OwnerId: "owner", ProjectId: "project", SnapshotName: "workspaces/a/snapshot-1.tar@" + st.Bucket("owner")}, codes.InvalidArgument},
+		{"foreign snapshot", &api.RegisterPrebuildRequest{OwnerId: "other", ProjectId: "project", Branch: "main", SnapshotName: "workspaces/a/snapshot-1.tar@" + st.Bucket("owner")}, codes.PermissionDenied},
+		{"missing snapshot", &api.RegisterPrebuildRequest{OwnerId: "owner", ProjectId: "project", Branch: "main", SnapshotName: "workspaces/b/snapshot-1.tar@" + st.Bucket("owner")}, codes.NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := cs.RegisterPrebuild(ctx, test.Req)
+			if status.Code(err) != test.Code {
+				t.Errorf("expected %v, got %v", test.Code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	wsdaemon "github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+const (
+	// maxPrestageConcurrency limits the number of nodes we stage a snapshot on at the same time
+	maxPrestageConcurrency = 8
+
+	// defaultPrestageTimeout is the time we allow a node to download a snapshot
+	defaultPrestageTimeout = 10 * time.Minute
+)
+
+// PrestageConfig configures how we reach the ws-daemons we pre-stage snapshots on
+type PrestageConfig struct {
+	// Port is the port on which ws-daemon listens on the nodes. If zero, snapshots cannot be pre-staged.
+	Port int `json:"port,omitempty"`
+
+	// TLS is the certificate/key config to connect to ws-daemon
+	TLS struct {
+		Authority   string `json:"ca"`
+		Certificate string `json:"crt"`
+		PrivateKey  string `json:"key"`
+	} `json:"tls"`
+
+	// Timeout is the time we allow a node to download a snapshot. Defaults to 10 minutes.
+	Timeout util.Duration `json:"timeout,omitempty"`
+}
+
+// daemonDialer connects to the ws-daemon on a node
+type daemonDialer func(ctx context.Context, node string) (client wsdaemon.WorkspaceContentServiceClient, close func(), err error)
+
+func newDaemonDialer(cfg PrestageConfig) (daemonDialer, error) {
+	opts := []grpc.DialOption{
+		grpc.WithUnaryInterceptor(grpc_opentracing.UnaryClientInterceptor(grpc_opentracing.WithTracer(opentracing.GlobalTracer()))),
+		grpc.WithBlock(),
+	}
+	if cfg.TLS.Authority != "" || cfg.TLS.Certificate != "" && cfg.TLS.PrivateKey != "" {
+		rootCA, err := os.ReadFile(cfg.TLS.Authority)
+		if err != nil {
+			return nil, xerrors.Errorf("could not read ca certificate: %w", err)
+		}
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(rootCA); !ok {
+			return nil, xerrors.Errorf("failed to append ca certs")
+		}
+		certificate, err := tls.LoadX509KeyPair(cfg.TLS.Certificate, cfg.TLS.PrivateKey)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot load ws-daemon certs: %w", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			ServerName:   "wsdaemon",
+			Certificates: []tls.Certificate{certificate},
+			RootCAs:      certPool,
+		})))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	return func(ctx context.Context, node string) (wsdaemon.WorkspaceContentServiceClient, func(), error) {
+		conn, err := grpc.DialContext(ctx, fmt.Sprintf("%s:%d", node, cfg.Port), opts...)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("cannot connect to ws-daemon: %w", err)
+		}
+		return wsdaemon.NewWorkspaceContentServiceClient(conn), func() { conn.Close() }, nil
+	}, nil
+}
+
+// PrestageSnapshot pushes a snapshot onto the content caches of a set of nodes ahead of the workspace starts we expect
+func (cs *SnapshotService) PrestageSnapshot(ctx context.Context, req *api.PrestageSnapshotRequest) (resp *api.PrestageSnapshotResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "PrestageSnapshot")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("snapshot", req.SnapshotName)
+	defer tracing.FinishSpan(span, &err)
+
+	if cs.dialDaemon == nil {
+		return nil, status.Error(codes.FailedPrecondition, "snapshot pre-staging is not configured")
+	}
+	if len(req.Nodes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no nodes to stage the snapshot on")
+	}
+	bkt, obj, err := storage.ParseSnapshotName(req.SnapshotName)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if bkt != cs.s.Bucket(req.OwnerId) {
+		return nil, status.Error(codes.PermissionDenied, "snapshot does not belong to this user")
+	}
+
+	info, err := cs.s.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
+	if err == storage.ErrNotFound {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("%s not found", req.SnapshotName))
+	}
+	if err != nil {
+		log.WithError(err).WithField("bucket", bkt).WithField("object", obj).Error("cannot sign snapshot download")
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	if info.Meta.ContentType == api.ContentTypeManifest {
+		// full workspace backups are restored by the registry-facade, not ws-daemon
+		return nil, status.Error(codes.FailedPrecondition, "full workspace backups cannot be pre-staged")
+	}
+
+	timeout := time.Duration(cs.cfg.Prestage.Timeout)
+	if timeout == 0 {
+		timeout = defaultPrestageTimeout
+	}
+	var (
+		results = make([]*api.PrestageSnapshotResult, len(req.Nodes))
+		sema    = make(chan struct{}, maxPrestageConcurrency)
+		wg      sync.WaitGroup
+	)
+	for i, node := range req.Nodes {
+		wg.Add(1)
+		go func(i int, node string) {
+			defer wg.Done()
+			sema <- struct{}{}
+			defer func() { <-sema }()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			res := &api.PrestageSnapshotResult{Node: node}
+			results[i] = res
+
+			stagingResp, err := cs.stageOnNode(ctx, node, &wsdaemon.StageSnapshotRequest{
+				Snapshot: req.SnapshotName,
+				Url:      info.URL,
+				Size:     info.Size,
+			})
+			if err != nil {
+				log.WithError(err).WithField("node", node).WithField("snapshot", req.SnapshotName).Warn("cannot pre-stage snapshot")
+				res.Error = err.Error()
+				return
+			}
+			res.AlreadyStaged = stagingResp.AlreadyStaged
+		}(i, node)
+	}
+	wg.Wait()
+
+	return &api.PrestageSnapshotResponse{Results: results}, nil
+}
+
+func (cs *SnapshotService) stageOnNode(ctx context.Context, node string, req *wsdaemon.StageSnapshotRequest) (*wsdaemon.StageSnapshotResponse, error) {
+	client, close, err := cs.dialDaemon(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	defer close()
+
+	return client.StageSnapshot(ctx, req)
+}
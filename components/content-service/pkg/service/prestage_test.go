@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/content-service/api"
+	wsdaemon "github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+// fakeDaemon stages snapshots by remembering their name
+type fakeDaemon struct {
+	wsdaemon.WorkspaceContentServiceClient
+
+	mu     sync.Mutex
+	staged map[string]*wsdaemon.StageSnapshotRequest
+}
+
+func (d *fakeDaemon) StageSnapshot(ctx context.Context, req *wsdaemon.StageSnapshotRequest, opts ...grpc.CallOption) (*wsdaemon.StageSnapshotResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, already := d.staged[req.Snapshot]
+	d.staged[req.Snapshot] = req
+	return &wsdaemon.StageSnapshotResponse{AlreadyStaged: already, Size: req.Size}, nil
+}
+
+func TestPrestageSnapshot(t *testing.T) {
+	store := newMemStorage(t, "gitpod-user")
+	store.Put("gitpod-user-owner", "workspaces/ws1/snapshot.tar", memObject{Content: "workspace content"})
+	store.Put("gitpod-user-owner", "workspaces/ws1/snapshot.mf.json", memObject{Content: "{}", ContentType: api.ContentTypeManifest})
+
+	daemons := map[string]*fakeDaemon{
+		"node-1": {staged: map[string]*wsdaemon.StageSnapshotRequest{"workspaces/ws1/snapshot.tar@gitpod-user-owner": {}}},
+		"node-2": {staged: make(map[string]*wsdaemon.StageSnapshotRequest)},
+	}
+	svc, err := newSnapshotService(SnapshotConfig{}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = svc.PrestageSnapshot(context.Background(), &api.PrestageSnapshotRequest{OwnerId: "owner", SnapshotName: "workspaces/ws1/snapshot.tar@gitpod-user-owner", Nodes: []string{"node-1"}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected pre-staging without configuration to fail, got %v", err)
+	}
+
+	svc.dialDaemon = func(ctx context.Context, node string) (wsdaemon.WorkspaceContentServiceClient, func(), error) {
+		d, ok := daemons[node]
+		if !ok {
+			return nil, nil, xerrors.Errorf("cannot connect to ws-daemon")
+		}
+		return d, func() {}, nil
+	}
+
+	tests := []struct {
+		Name        string
+		Req         *api.PrestageSnapshotRequest
+		Code        codes.Code
+		Expectation *api.PrestageSnapshotResponse
+	}{
+		{
+			Name: "stage",
+			Req:  &api.PrestageSnapshotRequest{OwnerId: "owner", SnapshotName: "workspaces/ws1/snapshot.tar@gitpod-user-owner", Nodes: []string{"node-1", "node-2", "node-3"}},
+			Expectation: &api.PrestageSnapshotResponse{Results: []*api.PrestageSnapshotResult{
+				{Node: "node-1", AlreadyStaged: true},
+				{Node: "node-2"},
+				{Node: "node-3", Error: "cannot connect to ws-daemon"},
+			}},
+		},
+		{
+			Name: "no nodes",
+			Req:  &api.PrestageSnapshotRequest{OwnerId: "owner", SnapshotName: "workspaces/ws1/snapshot.tar@gitpod-user-owner"},
+			Code: codes.InvalidArgument,
+		},
+		{
+			Name: "foreign snapshot",
+			Req:  &api.PrestageSnapshotRequest{OwnerId: "someone-else", SnapshotName: "workspaces/ws1/snapshot.tar@gitpod-user-owner", Nodes: []string{"node-1"}},
+			Code: codes.PermissionDenied,
+		},
+		{
+			Name: "unknown snapshot",
+			Req:  &api.PrestageSnapshotRequest{OwnerId: "owner", SnapshotName: "workspaces/ws1/other.tar@gitpod-user-owner", Nodes: []string{"node-1"}},
+			Code: codes.NotFound,
+		},
+		{
+			Name: "full workspace backup",
+			Req:  &api.PrestageSnapshotRequest{OwnerId: "owner", SnapshotName: "workspaces/ws1/snapshot.mf.json@gitpod-user-owner", Nodes: []string{"node-1"}},
+			Code: codes.FailedPrecondition,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := svc.PrestageSnapshot(context.Background(), test.Req)
+			if status.Code(err) != test.Code {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expectation, resp, cmpopts.IgnoreUnexported(api.PrestageSnapshotResponse{}, api.PrestageSnapshotResult{})); diff != "" {
+				t.Errorf("unexpected response (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	staged := daemons["node-2"].staged["workspaces/ws1/snapshot.tar@gitpod-user-owner"]
+	if staged == nil || staged.Size != int64(len("workspace content")) || staged.Url == "" {
+		t.Errorf("unexpected stage request: %v", staged)
+	}
+}
@@ -0,0 +1,469 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+const (
+	// snapshotBundleValidity is how long an exported bundle can be imported. This is bound by the
+	// lifetime of the signed URLs our storage implementations produce.
+	snapshotBundleValidity = 30 * time.Minute
+
+	// maxWorkspaceContentManifestSize limits how much we read when downloading a workspace content manifest
+	maxWorkspaceContentManifestSize = 1 << 20
+)
+
+// SnapshotConfig configures the export and import of snapshots between Gitpod installations
+type SnapshotConfig struct {
+	// SigningKey is the path to a PEM encoded (PKCS #8) ed25519 private key exported bundles are signed with.
+	// If empty, snapshots cannot be exported.
+	SigningKey string `json:"signingKey,omitempty"`
+
+	// KeyID identifies the signing key to importing installations
+	KeyID string `json:"keyID,omitempty"`
+
+	// TrustedKeys maps key IDs to paths of PEM encoded (PKIX) ed25519 public keys.
+	// We import only bundles signed by one of those keys.
+	TrustedKeys map[string]string `json:"trustedKeys,omitempty"`
+
+	// Prestage configures the pre-staging of snapshots onto nodes
+	Prestage PrestageConfig `json:"prestage,omitempty"`
+}
+
+// SnapshotService implements SnapshotServiceServer
+type SnapshotService struct {
+	cfg SnapshotConfig
+	s   storage.PresignedAccess
+
+	signingKey  ed25519.PrivateKey
+	trustedKeys map[string]ed25519.PublicKey
+	dialDaemon  daemonDialer
+}
+
+// NewSnapshotService creates a new snapshot service
+func NewSnapshotService(cfg SnapshotConfig, storageCfg storage.Config) (res *SnapshotService, err error) {
+	s, err := storage.NewPresignedAccess(&storageCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newSnapshotService(cfg, s)
+}
+
+func newSnapshotService(cfg SnapshotConfig, s storage.PresignedAccess) (res *SnapshotService, err error) {
+	res = &SnapshotService{
+		cfg:         cfg,
+		s:           s,
+		trustedKeys: make(map[string]ed25519.PublicKey, len(cfg.TrustedKeys)),
+	}
+
+	if cfg.SigningKey != "" {
+		if cfg.KeyID == "" {
+			return nil, xerrors.Errorf("snapshot signing key needs a key ID")
+		}
+		block, err := readPEM(cfg.SigningKey)
+		if err != nil {
+			return nil, err
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse snapshot signing key: %w", err)
+		}
+		pk, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, xerrors.Errorf("snapshot signing key is not an ed25519 key")
+		}
+		res.signingKey = pk
+	}
+
+	for id, fn := range cfg.TrustedKeys {
+		block, err := readPEM(fn)
+		if err != nil {
+			return nil, err
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse trusted key %s: %w", id, err)
+		}
+		pk, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, xerrors.Errorf("trusted key %s is not an ed25519 key", id)
+		}
+		res.trustedKeys[id] = pk
+	}
+
+	if cfg.Prestage.Port != 0 {
+		res.dialDaemon, err = newDaemonDialer(cfg.Prestage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func readPEM(fn string) (*pem.Block, error) {
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read key: %w", err)
+	}
+	block, _ := pem.Decode(fc)
+	if block == nil {
+		return nil, xerrors.Errorf("%s is not PEM encoded", fn)
+	}
+	return block, nil
+}
+
+// ExportSnapshot produces a signed bundle which another Gitpod installation can import the snapshot from
+func (cs *SnapshotService) ExportSnapshot(ctx context.Context, req *api.ExportSnapshotRequest) (resp *api.ExportSnapshotResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExportSnapshot")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("snapshot", req.SnapshotName)
+	defer tracing.FinishSpan(span, &err)
+
+	if cs.signingKey == nil {
+		return nil, status.Error(codes.FailedPrecondition, "snapshot export is not configured")
+	}
+
+	bkt, obj, err := storage.ParseSnapshotName(req.SnapshotName)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if bkt != cs.s.Bucket(req.OwnerId) {
+		return nil, status.Error(codes.PermissionDenied, "snapshot does not belong to this user")
+	}
+
+	mf := api.SnapshotBundleManifest{
+		Version:      api.SnapshotBundleManifestVersionV1,
+		SnapshotName: req.SnapshotName,
+		ExpiresAt:    time.Now().Add(snapshotBundleValidity).UTC(),
+	}
+
+	root, content, err := cs.exportObject(ctx, bkt, obj, "")
+	if err != nil {
+		return nil, err
+	}
+	mf.Objects = append(mf.Objects, *root)
+
+	if root.ContentType == api.ContentTypeManifest {
+		var wsmf api.WorkspaceContentManifest
+		err = json.Unmarshal(content, &wsmf)
+		if err != nil {
+			return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("cannot parse workspace content manifest: %v", err))
+		}
+		for _, l := range wsmf.Layers {
+			if l.Bucket != bkt {
+				return nil, status.Error(codes.PermissionDenied, "snapshot layer does not belong to this user")
+			}
+			layer, _, err := cs.exportObject(ctx, l.Bucket, l.Object, l.Digest)
+			if err != nil {
+				return nil, err
+			}
+			mf.Objects = append(mf.Objects, *layer)
+		}
+	}
+
+	manifest, err := json.Marshal(mf)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &api.ExportSnapshotResponse{
+		Bundle: &api.SnapshotBundle{
+			Manifest:  manifest,
+			KeyId:     cs.cfg.KeyID,
+			Signature: ed25519.Sign(cs.signingKey, manifest),
+		},
+	}, nil
+}
+
+// exportObject describes a single storage object for a bundle. If we know neither the digest of the object,
+// nor does the storage, we download the object to compute it. Workspace content manifests are always downloaded
+// and their content returned.
+func (cs *SnapshotService) exportObject(ctx context.Context, bkt, obj string, dgst digest.Digest) (res *api.SnapshotBundleObject, content []byte, err error) {
+	info, err := cs.s.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
+	if err == storage.ErrNotFound {
+		return nil, nil, status.Error(codes.NotFound, fmt.Sprintf("%s@%s not found", obj, bkt))
+	}
+	if err != nil {
+		log.WithError(err).WithField("bucket", bkt).WithField("object", obj).Error("cannot sign snapshot download")
+		return nil, nil, status.Error(codes.Unknown, err.Error())
+	}
+
+	res = &api.SnapshotBundleObject{
+		Bucket:      bkt,
+		Object:      obj,
+		URL:         info.URL,
+		ContentType: info.Meta.ContentType,
+		Size:        info.Size,
+		Digest:      dgst,
+	}
+	if res.Digest == "" {
+		res.Digest = digest.Digest(info.Meta.Digest)
+	}
+
+	if res.ContentType == api.ContentTypeManifest {
+		content, err = fetchObject(ctx, res.URL, maxWorkspaceContentManifestSize)
+		if err != nil {
+			return nil, nil, status.Error(codes.Unavailable, err.Error())
+		}
+		res.Digest = digest.FromBytes(content)
+		return res, content, nil
+	}
+
+	if res.Digest.Validate() != nil {
+		dl, err := openObject(ctx, res.URL)
+		if err != nil {
+			return nil, nil, status.Error(codes.Unavailable, err.Error())
+		}
+		defer dl.Close()
+
+		res.Digest, err = digest.FromReader(dl)
+		if err != nil {
+			return nil, nil, status.Error(codes.Unavailable, fmt.Sprintf("cannot compute digest of %s: %v", obj, err))
+		}
+	}
+
+	return res, nil, nil
+}
+
+// ImportSnapshot copies the snapshot described by a bundle into this installation's storage
+func (cs *SnapshotService) ImportSnapshot(ctx context.Context, req *api.ImportSnapshotRequest) (resp *api.ImportSnapshotResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ImportSnapshot")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("workspace", req.WorkspaceId)
+	defer tracing.FinishSpan(span, &err)
+
+	if req.WorkspaceId == "" || strings.ContainsAny(req.WorkspaceId, "/@") {
+		return nil, status.Error(codes.InvalidArgument, "invalid workspace ID")
+	}
+	mf, err := cs.verifyBundle(req.Bundle)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("snapshot", mf.SnapshotName)
+
+	err = cs.s.EnsureExists(ctx, req.OwnerId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	bkt := cs.s.Bucket(req.OwnerId)
+
+	root := mf.Objects[0]
+	rootObj := importedObjectName(req.WorkspaceId, root.Object)
+	if root.ContentType != api.ContentTypeManifest {
+		err = cs.importObject(ctx, root, bkt, rootObj)
+		if err != nil {
+			return nil, err
+		}
+		return &api.ImportSnapshotResponse{SnapshotName: fmt.Sprintf("%s@%s", rootObj, bkt)}, nil
+	}
+
+	// Full workspace backups reference their layers by bucket and object name. We import the layers first
+	// and point the manifest to their new location.
+	content, err := fetchObject(ctx, root.URL, maxWorkspaceContentManifestSize)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if err := verifyDigest(root.Digest, content); err != nil {
+		return nil, status.Error(codes.DataLoss, err.Error())
+	}
+	var wsmf api.WorkspaceContentManifest
+	err = json.Unmarshal(content, &wsmf)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("cannot parse workspace content manifest: %v", err))
+	}
+
+	layers := make(map[string]api.SnapshotBundleObject, len(mf.Objects)-1)
+	for _, obj := range mf.Objects[1:] {
+		layers[obj.Object+"@"+obj.Bucket] = obj
+	}
+	for i, l := range wsmf.Layers {
+		src, ok := layers[l.Object+"@"+l.Bucket]
+		if !ok || src.Digest != l.Digest {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("layer %s is not part of the bundle", l.Digest))
+		}
+
+		obj := importedObjectName(req.WorkspaceId, l.Object)
+		err = cs.importObject(ctx, src, bkt, obj)
+		if err != nil {
+			return nil, err
+		}
+		wsmf.Layers[i].Bucket = bkt
+		wsmf.Layers[i].Object = obj
+	}
+
+	content, err = json.Marshal(wsmf)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = cs.uploadObject(ctx, bkt, rootObj, root.ContentType, bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &api.ImportSnapshotResponse{SnapshotName: fmt.Sprintf("%s@%s", rootObj, bkt)}, nil
+}
+
+// verifyBundle checks the signature of a bundle and returns its manifest if the bundle is valid
+func (cs *SnapshotService) verifyBundle(bundle *api.SnapshotBundle) (*api.SnapshotBundleManifest, error) {
+	if bundle == nil {
+		return nil, status.Error(codes.InvalidArgument, "bundle is missing")
+	}
+	key, ok := cs.trustedKeys[bundle.KeyId]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("bundle is signed by unknown key %q", bundle.KeyId))
+	}
+	if !ed25519.Verify(key, bundle.Manifest, bundle.Signature) {
+		return nil, status.Error(codes.PermissionDenied, "invalid bundle signature")
+	}
+
+	var mf api.SnapshotBundleManifest
+	err := json.Unmarshal(bundle.Manifest, &mf)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("cannot parse bundle manifest: %v", err))
+	}
+	if mf.Version != api.SnapshotBundleManifestVersionV1 {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("unsupported bundle version %d", mf.Version))
+	}
+	if time.Now().After(mf.ExpiresAt) {
+		return nil, status.Error(codes.FailedPrecondition, "bundle has expired")
+	}
+	if len(mf.Objects) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "bundle contains no objects")
+	}
+	for _, obj := range mf.Objects {
+		if err := obj.Digest.Validate(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid digest for %s: %v", obj.Object, err))
+		}
+	}
+
+	return &mf, nil
+}
+
+// importObject streams an object from the exporting installation into our storage and verifies its digest
+func (cs *SnapshotService) importObject(ctx context.Context, src api.SnapshotBundleObject, bkt, obj string) (err error) {
+	dl, err := openObject(ctx, src.URL)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer dl.Close()
+
+	verifier := src.Digest.Verifier()
+	err = cs.uploadObject(ctx, bkt, obj, src.ContentType, io.TeeReader(dl, verifier), src.Size)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if !verifier.Verified() {
+		derr := cs.s.DeleteObject(ctx, bkt, &storage.DeleteObjectQuery{Name: obj})
+		if derr != nil {
+			log.WithError(derr).WithField("bucket", bkt).WithField("object", obj).Warn("cannot delete corrupted snapshot object")
+		}
+		return status.Error(codes.DataLoss, fmt.Sprintf("%s does not match its digest", src.Object))
+	}
+
+	return nil
+}
+
+func (cs *SnapshotService) uploadObject(ctx context.Context, bkt, obj, contentType string, body io.Reader, size int64) error {
+	return putObject(ctx, cs.s, bkt, obj, contentType, body, size)
+}
+
+// putObject uploads an object through a presigned URL
+func putObject(ctx context.Context, s storage.PresignedAccess, bkt, obj, contentType string, body io.Reader, size int64) error {
+	info, err := s.SignUpload(ctx, bkt, obj, &storage.SignedURLOptions{ContentType: contentType})
+	if err != nil {
+		return xerrors.Errorf("cannot sign upload of %s: %w", obj, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, info.URL, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("cannot upload %s: %w", obj, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return xerrors.Errorf("cannot upload %s: %s", obj, resp.Status)
+	}
+	return nil
+}
+
+func openObject(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download object: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, xerrors.Errorf("cannot download object: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func fetchObject(ctx context.Context, url string, maxSize int64) ([]byte, error) {
+	dl, err := openObject(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer dl.Close()
+
+	content, err := io.ReadAll(io.LimitReader(dl, maxSize+1))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download object: %w", err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, xerrors.Errorf("object is larger than %d bytes", maxSize)
+	}
+	return content, nil
+}
+
+func verifyDigest(dgst digest.Digest, content []byte) error {
+	verifier := dgst.Verifier()
+	_, _ = verifier.Write(content)
+	if !verifier.Verified() {
+		return xerrors.Errorf("content does not match digest %s", dgst)
+	}
+	return nil
+}
+
+// importedObjectName places an imported object amongst the workspace's content
+func importedObjectName(workspaceID, obj string) string {
+	return fmt.Sprintf("workspaces/%s/%s", workspaceID, path.Base(obj))
+}
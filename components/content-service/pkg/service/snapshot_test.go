@@ -0,0 +1,263 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	digest "github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+type memObject struct {
+	Content     string
+	ContentType string
+}
+
+// memStorage is a presigned storage which keeps its objects in memory and serves them via HTTP
+type memStorage struct {
+	storage.PresignedNoopStorage
+
+	mu      sync.Mutex
+	prefix  string
+	objects map[string]memObject
+	srv     *httptest.Server
+}
+
+func newMemStorage(t *testing.T, prefix string) *memStorage {
+	s := &memStorage{prefix: prefix, objects: make(map[string]memObject)}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		switch r.Method {
+		case http.MethodGet:
+			s.mu.Lock()
+			obj, ok := s.objects[key]
+			s.mu.Unlock()
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			http.ServeContent(w, r, key, time.Time{}, strings.NewReader(obj.Content))
+		case http.MethodPut:
+			// uploads may be streamed from downloads of the same storage, hence we must not lock while reading
+			content, _ := io.ReadAll(r.Body)
+			s.mu.Lock()
+			s.objects[key] = memObject{Content: string(content), ContentType: r.Header.Get("Content-Type")}
+			s.mu.Unlock()
+		}
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *memStorage) Bucket(ownerID string) string { return s.prefix + "-" + ownerID }
+
+func (s *memStorage) BlobObject(name string) (string, error) { return "blobs/" + name, nil }
+
+func (s *memStorage) Put(bkt, obj string, o memObject) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[bkt+"/"+obj] = o
+}
+
+func (s *memStorage) Get(bkt, obj string) (memObject, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[bkt+"/"+obj]
+	return o, ok
+}
+
+func (s *memStorage) SignDownload(ctx context.Context, bkt, obj string, options *storage.SignedURLOptions) (*storage.DownloadInfo, error) {
+	o, ok := s.Get(bkt, obj)
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &storage.DownloadInfo{
+		Meta: storage.ObjectMeta{ContentType: o.ContentType},
+		URL:  fmt.Sprintf("%s/%s/%s", s.srv.URL, bkt, obj),
+		Size: int64(len(o.Content)),
+	}, nil
+}
+
+func (s *memStorage) SignUpload(ctx context.Context, bkt, obj string, options *storage.SignedURLOptions) (*storage.UploadInfo, error) {
+	return &storage.UploadInfo{URL: fmt.Sprintf("%s/%s/%s", s.srv.URL, bkt, obj)}, nil
+}
+
+func (s *memStorage) DeleteObject(ctx context.Context, bkt string, query *storage.DeleteObjectQuery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[bkt+"/"+query.Name]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(s.objects, bkt+"/"+query.Name)
+	return nil
+}
+
+func TestSnapshotExportImport(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeKey := func(tpe string, key interface{}) string {
+		var der []byte
+		var err error
+		if tpe == "PRIVATE KEY" {
+			der, err = x509.MarshalPKCS8PrivateKey(key)
+		} else {
+			der, err = x509.MarshalPKIXPublicKey(key)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		fn := filepath.Join(t.TempDir(), "key.pem")
+		err = os.WriteFile(fn, pem.EncodeToMemory(&pem.Block{Type: tpe, Bytes: der}), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fn
+	}
+
+	staging := newMemStorage(t, "staging")
+	exporter, err := newSnapshotService(SnapshotConfig{SigningKey: writeKey("PRIVATE KEY", priv), KeyID: "staging"}, staging)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod := newMemStorage(t, "prod")
+	importer, err := newSnapshotService(SnapshotConfig{TrustedKeys: map[string]string{"staging": writeKey("PUBLIC KEY", pub)}}, prod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const layerContent = "layer content"
+	srcBkt := staging.Bucket("alice")
+	staging.Put(srcBkt, "workspaces/src/snapshot-1.tar", memObject{Content: "backup content"})
+	staging.Put(srcBkt, "workspaces/src/wsfull-1.tar", memObject{Content: layerContent})
+	srcManifest, _ := json.Marshal(api.WorkspaceContentManifest{
+		Type: api.TypeFullWorkspaceContentV1,
+		Layers: []api.WorkspaceContentLayer{
+			{
+				Descriptor: ociv1.Descriptor{Digest: digest.FromString(layerContent), Size: int64(len(layerContent))},
+				Bucket:     srcBkt,
+				Object:     "workspaces/src/wsfull-1.tar",
+				DiffID:     digest.FromString(layerContent),
+			},
+		},
+	})
+	staging.Put(srcBkt, "workspaces/src/snapshot-2.mf.json", memObject{Content: string(srcManifest), ContentType: api.ContentTypeManifest})
+
+	ctx := context.Background()
+	t.Run("tar backup", func(t *testing.T) {
+		exp, err := exporter.ExportSnapshot(ctx, &api.ExportSnapshotRequest{OwnerId: "alice", SnapshotName: "workspaces/src/snapshot-1.tar@" + srcBkt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		imp, err := importer.ImportSnapshot(ctx, &api.ImportSnapshotRequest{OwnerId: "bob", WorkspaceId: "dst", Bundle: exp.Bundle})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if imp.SnapshotName != "workspaces/dst/snapshot-1.tar@prod-bob" {
+			t.Errorf("unexpected snapshot name %s", imp.SnapshotName)
+		}
+		if o, _ := prod.Get("prod-bob", "workspaces/dst/snapshot-1.tar"); o.Content != "backup content" {
+			t.Errorf("unexpected content %q", o.Content)
+		}
+	})
+
+	t.Run("full workspace backup", func(t *testing.T) {
+		exp, err := exporter.ExportSnapshot(ctx, &api.ExportSnapshotRequest{OwnerId: "alice", SnapshotName: "workspaces/src/snapshot-2.mf.json@" + srcBkt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		imp, err := importer.ImportSnapshot(ctx, &api.ImportSnapshotRequest{OwnerId: "bob", WorkspaceId: "dst", Bundle: exp.Bundle})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if imp.SnapshotName != "workspaces/dst/snapshot-2.mf.json@prod-bob" {
+			t.Errorf("unexpected snapshot name %s", imp.SnapshotName)
+		}
+
+		mf, _ := prod.Get("prod-bob", "workspaces/dst/snapshot-2.mf.json")
+		if mf.ContentType != api.ContentTypeManifest {
+			t.Errorf("unexpected manifest content type %q", mf.ContentType)
+		}
+		var act api.WorkspaceContentManifest
+		err = json.Unmarshal([]byte(mf.Content), &act)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"prod-bob", "workspaces/dst/wsfull-1.tar"}, []string{act.Layers[0].Bucket, act.Layers[0].Object}); diff != "" {
+			t.Errorf("layer reference was not rewritten (-want +got):\n%s", diff)
+		}
+		if o, _ := prod.Get("prod-bob", "workspaces/dst/wsfull-1.tar"); o.Content != layerContent {
+			t.Errorf("unexpected layer content %q", o.Content)
+		}
+	})
+
+	t.Run("foreign snapshot", func(t *testing.T) {
+		_, err := exporter.ExportSnapshot(ctx, &api.ExportSnapshotRequest{OwnerId: "mallory", SnapshotName: "workspaces/src/snapshot-1.tar@" + srcBkt})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("tampered bundle", func(t *testing.T) {
+		exp, err := exporter.ExportSnapshot(ctx, &api.ExportSnapshotRequest{OwnerId: "alice", SnapshotName: "workspaces/src/snapshot-1.tar@" + srcBkt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bundle := exp.Bundle
+		bundle.Manifest = []byte(strings.ReplaceAll(string(bundle.Manifest), "snapshot-1.tar", "snapshot-x.tar"))
+		_, err = importer.ImportSnapshot(ctx, &api.ImportSnapshotRequest{OwnerId: "bob", WorkspaceId: "dst", Bundle: bundle})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		bundle.Signature = ed25519.Sign(otherPriv, bundle.Manifest)
+		_, err = importer.ImportSnapshot(ctx, &api.ImportSnapshotRequest{OwnerId: "bob", WorkspaceId: "dst", Bundle: bundle})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("corrupted object", func(t *testing.T) {
+		staging.Put(srcBkt, "workspaces/src/snapshot-3.tar", memObject{Content: "original"})
+		exp, err := exporter.ExportSnapshot(ctx, &api.ExportSnapshotRequest{OwnerId: "alice", SnapshotName: "workspaces/src/snapshot-3.tar@" + srcBkt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		staging.Put(srcBkt, "workspaces/src/snapshot-3.tar", memObject{Content: "modified"})
+
+		_, err = importer.ImportSnapshot(ctx, &api.ImportSnapshotRequest{OwnerId: "bob", WorkspaceId: "dst", Bundle: exp.Bundle})
+		if status.Code(err) != codes.DataLoss {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
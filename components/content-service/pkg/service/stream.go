@@ -0,0 +1,500 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+const (
+	// maxStreamChunkSize limits how much a single append may add. It stays well below gRPC's default message size.
+	maxStreamChunkSize = 2 << 20
+
+	// maxStreamChunks limits the number of appends until a blob is finalized
+	maxStreamChunks = 10000
+
+	// maxStreamIndexSize limits how much we read when downloading the index of a blob stream
+	maxStreamIndexSize = 4 << 20
+
+	streamIndexContentType = "application/json"
+	streamChunkContentType = "application/octet-stream"
+
+	defaultStreamPollInterval = 1 * time.Second
+
+	// streamLocks is the number of locks appends and finalizations of the same blob serialise on
+	streamLocks = 64
+)
+
+// StreamConfig configures the blobs which are still being written
+type StreamConfig struct {
+	// PollInterval is how often tailers check for appended data. Defaults to one second.
+	PollInterval util.Duration `json:"pollInterval,omitempty"`
+}
+
+// BlobStreamService implements BlobStreamServiceServer
+type BlobStreamService struct {
+	cfg   StreamConfig
+	quota int64
+	s     storage.PresignedAccess
+
+	// locks serialise the read-modify-write cycles of the stream indices
+	locks [streamLocks]sync.Mutex
+}
+
+// NewBlobStreamService creates a new blob stream service
+func NewBlobStreamService(cfg StreamConfig, storageCfg storage.Config) (*BlobStreamService, error) {
+	s, err := storage.NewPresignedAccess(&storageCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newBlobStreamService(cfg, storageCfg.BlobQuota, s), nil
+}
+
+func newBlobStreamService(cfg StreamConfig, quota int64, s storage.PresignedAccess) *BlobStreamService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = util.Duration(defaultStreamPollInterval)
+	}
+	return &BlobStreamService{
+		cfg:   cfg,
+		quota: quota,
+		s:     s,
+	}
+}
+
+// streamIndex tracks the chunks of a blob which is still being written. We keep it in the bucket next to the chunks,
+// and keep it once the blob is finalized so that late tailers know the blob is complete.
+type streamIndex struct {
+	Chunks    []streamChunk `json:"chunks,omitempty"`
+	Size      int64         `json:"size"`
+	Finalized bool          `json:"finalized,omitempty"`
+}
+
+type streamChunk struct {
+	Offset int64 `json:"offset"`
+	Size   int64 `json:"size"`
+}
+
+func streamIndexObject(blobName string) string {
+	return blobName + ".stream/index.json"
+}
+
+func streamChunkObject(blobName string, offset int64) string {
+	return fmt.Sprintf("%s.stream/%020d", blobName, offset)
+}
+
+func (cs *BlobStreamService) lock(bkt, blobName string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = io.WriteString(h, bkt+"/"+blobName)
+	return &cs.locks[h.Sum32()%streamLocks]
+}
+
+// Append adds data to the end of a blob which is still being written
+func (cs *BlobStreamService) Append(ctx context.Context, req *api.AppendBlobRequest) (resp *api.AppendBlobResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Append")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("name", req.Name)
+	span.SetTag("offset", req.Offset)
+	defer tracing.FinishSpan(span, &err)
+
+	if len(req.Data) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "data is missing")
+	}
+	if len(req.Data) > maxStreamChunkSize {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot append more than %d bytes at once", maxStreamChunkSize)
+	}
+	blobName, err := cs.s.BlobObject(req.Name)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	bkt := cs.s.Bucket(req.OwnerId)
+
+	mu := cs.lock(bkt, blobName)
+	mu.Lock()
+	defer mu.Unlock()
+
+	idx, err := cs.loadIndex(ctx, bkt, blobName)
+	if err != nil {
+		return nil, err
+	}
+	if idx.Finalized {
+		return nil, status.Error(codes.FailedPrecondition, "blob is finalized")
+	}
+	if req.Offset != idx.Size {
+		retried, err := cs.isRetry(ctx, bkt, blobName, idx, req)
+		if err != nil {
+			return nil, err
+		}
+		if retried {
+			return &api.AppendBlobResponse{Size: idx.Size}, nil
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "blob has %d bytes, cannot append at %d", idx.Size, req.Offset)
+	}
+	if len(idx.Chunks) >= maxStreamChunks {
+		return nil, status.Errorf(codes.ResourceExhausted, "blob has %d appends already", maxStreamChunks)
+	}
+	if len(idx.Chunks) == 0 {
+		_, err = cs.s.SignDownload(ctx, bkt, blobName, &storage.SignedURLOptions{})
+		if err == nil {
+			return nil, status.Error(codes.AlreadyExists, "blob was uploaded already")
+		}
+		if err != storage.ErrNotFound {
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+
+		// We check the quota once per blob rather than on every append, which would list the bucket every time.
+		err = cs.checkQuota(ctx, req.OwnerId, bkt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = putObject(ctx, cs.s, bkt, streamChunkObject(blobName, req.Offset), streamChunkContentType, bytes.NewReader(req.Data), int64(len(req.Data)))
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	idx.Chunks = append(idx.Chunks, streamChunk{Offset: req.Offset, Size: int64(len(req.Data))})
+	idx.Size += int64(len(req.Data))
+	err = cs.saveIndex(ctx, bkt, blobName, idx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AppendBlobResponse{Size: idx.Size}, nil
+}
+
+// isRetry determines if an append is the retry of one which succeeded, i.e. if the blob has the same chunk already
+func (cs *BlobStreamService) isRetry(ctx context.Context, bkt, blobName string, idx *streamIndex, req *api.AppendBlobRequest) (bool, error) {
+	for _, c := range idx.Chunks {
+		if c.Offset != req.Offset || c.Size != int64(len(req.Data)) {
+			continue
+		}
+
+		info, err := cs.s.SignDownload(ctx, bkt, streamChunkObject(blobName, c.Offset), &storage.SignedURLOptions{})
+		if err != nil {
+			return false, status.Error(codes.Unknown, err.Error())
+		}
+		data, err := fetchObject(ctx, info.URL, maxStreamChunkSize)
+		if err != nil {
+			return false, status.Error(codes.Unavailable, err.Error())
+		}
+		return bytes.Equal(data, req.Data), nil
+	}
+	return false, nil
+}
+
+func (cs *BlobStreamService) checkQuota(ctx context.Context, ownerID, bkt string) error {
+	err := cs.s.EnsureExists(ctx, ownerID)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if cs.quota <= 0 {
+		return nil
+	}
+	size, err := cs.s.DiskUsage(ctx, bkt, "blobs")
+	if err != nil {
+		return status.Error(codes.Unknown, err.Error())
+	}
+	if size >= cs.quota {
+		return status.Error(codes.ResourceExhausted, "quota exceeded")
+	}
+	return nil
+}
+
+// Finalize completes a blob. We concatenate its chunks into a regular blob and delete them.
+func (cs *BlobStreamService) Finalize(ctx context.Context, req *api.FinalizeBlobRequest) (resp *api.FinalizeBlobResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Finalize")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("name", req.Name)
+	defer tracing.FinishSpan(span, &err)
+
+	blobName, err := cs.s.BlobObject(req.Name)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	bkt := cs.s.Bucket(req.OwnerId)
+
+	mu := cs.lock(bkt, blobName)
+	mu.Lock()
+	defer mu.Unlock()
+
+	idx, err := cs.loadIndex(ctx, bkt, blobName)
+	if err != nil {
+		return nil, err
+	}
+	if len(idx.Chunks) == 0 && !idx.Finalized {
+		// Finalizing a blob nobody appended to creates an empty blob, but must not overwrite one which was uploaded in one go
+		info, err := cs.s.SignDownload(ctx, bkt, blobName, &storage.SignedURLOptions{})
+		if err == nil {
+			idx = &streamIndex{Size: info.Size, Finalized: true}
+		} else if err != storage.ErrNotFound {
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+	}
+	if idx.Size != req.Size {
+		return nil, status.Errorf(codes.FailedPrecondition, "blob has %d bytes, expected %d", idx.Size, req.Size)
+	}
+	if idx.Finalized {
+		return &api.FinalizeBlobResponse{Size: idx.Size}, nil
+	}
+	if len(idx.Chunks) == 0 {
+		err = cs.s.EnsureExists(ctx, req.OwnerId)
+		if err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(cs.copyChunks(ctx, pw, bkt, blobName, idx.Chunks))
+	}()
+	err = putObject(ctx, cs.s, bkt, blobName, req.ContentType, pr, idx.Size)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, xerrors.Errorf("cannot finalize blob: %w", err).Error())
+	}
+
+	chunks := idx.Chunks
+	idx.Chunks = nil
+	idx.Finalized = true
+	err = cs.saveIndex(ctx, bkt, blobName, idx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range chunks {
+		err := cs.s.DeleteObject(ctx, bkt, &storage.DeleteObjectQuery{Name: streamChunkObject(blobName, c.Offset)})
+		if err != nil && err != storage.ErrNotFound {
+			log.WithError(err).WithField("bucket", bkt).WithField("blob", blobName).Warn("cannot delete chunk of finalized blob")
+		}
+	}
+
+	return &api.FinalizeBlobResponse{Size: idx.Size}, nil
+}
+
+func (cs *BlobStreamService) copyChunks(ctx context.Context, dst io.Writer, bkt, blobName string, chunks []streamChunk) error {
+	for _, c := range chunks {
+		info, err := cs.s.SignDownload(ctx, bkt, streamChunkObject(blobName, c.Offset), &storage.SignedURLOptions{})
+		if err != nil {
+			return xerrors.Errorf("cannot find chunk at %d: %w", c.Offset, err)
+		}
+		dl, err := openObject(ctx, info.URL)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(dst, dl)
+		dl.Close()
+		if err != nil {
+			return err
+		}
+		if n != c.Size {
+			return xerrors.Errorf("chunk at %d has %d bytes, expected %d", c.Offset, n, c.Size)
+		}
+	}
+	return nil
+}
+
+// Tail streams the content of a blob until it is finalized
+func (cs *BlobStreamService) Tail(req *api.TailBlobRequest, srv api.BlobStreamService_TailServer) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(srv.Context(), "Tail")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("name", req.Name)
+	defer tracing.FinishSpan(span, &err)
+
+	if req.Offset < 0 {
+		return status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+	blobName, err := cs.s.BlobObject(req.Name)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	bkt := cs.s.Bucket(req.OwnerId)
+
+	offset := req.Offset
+	for {
+		idx, err := cs.loadIndex(ctx, bkt, blobName)
+		if err != nil {
+			return err
+		}
+		if len(idx.Chunks) == 0 && !idx.Finalized {
+			// blobs which were uploaded in one go have no index, but we can tail them all the same
+			info, err := cs.s.SignDownload(ctx, bkt, blobName, &storage.SignedURLOptions{})
+			if err == nil {
+				idx = &streamIndex{Size: info.Size, Finalized: true}
+			} else if err != storage.ErrNotFound {
+				return status.Error(codes.Unknown, err.Error())
+			}
+		}
+		if offset > idx.Size && idx.Finalized {
+			return status.Errorf(codes.OutOfRange, "blob has %d bytes only", idx.Size)
+		}
+
+		if idx.Finalized {
+			if offset < idx.Size {
+				err = cs.sendFinalized(ctx, srv, bkt, blobName, offset)
+				if err != nil {
+					return err
+				}
+			}
+			return srv.Send(&api.TailBlobResponse{Offset: idx.Size, Finalized: true})
+		}
+
+		offset, err = cs.sendChunks(ctx, srv, bkt, blobName, idx.Chunks, offset)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(cs.cfg.PollInterval)):
+		}
+	}
+}
+
+// sendChunks sends all chunks past offset and returns the offset it got to. Chunks which are gone
+// belong to a blob which was finalized in the meantime - the next round reads them from the blob.
+func (cs *BlobStreamService) sendChunks(ctx context.Context, srv api.BlobStreamService_TailServer, bkt, blobName string, chunks []streamChunk, offset int64) (int64, error) {
+	for _, c := range chunks {
+		if c.Offset+c.Size <= offset {
+			continue
+		}
+
+		info, err := cs.s.SignDownload(ctx, bkt, streamChunkObject(blobName, c.Offset), &storage.SignedURLOptions{})
+		if err == storage.ErrNotFound {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, status.Error(codes.Unknown, err.Error())
+		}
+		data, err := fetchObject(ctx, info.URL, maxStreamChunkSize)
+		if err != nil {
+			return offset, status.Error(codes.Unavailable, err.Error())
+		}
+		if int64(len(data)) != c.Size {
+			return offset, status.Errorf(codes.DataLoss, "chunk at %d has %d bytes, expected %d", c.Offset, len(data), c.Size)
+		}
+
+		err = srv.Send(&api.TailBlobResponse{Offset: offset, Data: data[offset-c.Offset:]})
+		if err != nil {
+			return offset, err
+		}
+		offset = c.Offset + c.Size
+	}
+	return offset, nil
+}
+
+func (cs *BlobStreamService) sendFinalized(ctx context.Context, srv api.BlobStreamService_TailServer, bkt, blobName string, offset int64) error {
+	info, err := cs.s.SignDownload(ctx, bkt, blobName, &storage.SignedURLOptions{})
+	if err == storage.ErrNotFound {
+		return status.Error(codes.DataLoss, "finalized blob is gone")
+	}
+	if err != nil {
+		return status.Error(codes.Unknown, err.Error())
+	}
+	dl, err := openObjectAt(ctx, info.URL, offset)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer dl.Close()
+
+	buf := make([]byte, maxStreamChunkSize)
+	for {
+		n, err := io.ReadFull(dl, buf)
+		if n > 0 {
+			serr := srv.Send(&api.TailBlobResponse{Offset: offset, Data: buf[:n]})
+			if serr != nil {
+				return serr
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	}
+}
+
+// openObjectAt downloads an object starting at offset
+func openObjectAt(ctx context.Context, url string, offset int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download object: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusOK:
+		// the server ignored the range
+		_, err = io.CopyN(io.Discard, resp.Body, offset)
+		if err != nil {
+			resp.Body.Close()
+			return nil, xerrors.Errorf("cannot download object: %w", err)
+		}
+		return resp.Body, nil
+	default:
+		resp.Body.Close()
+		return nil, xerrors.Errorf("cannot download object: %s", resp.Status)
+	}
+}
+
+func (cs *BlobStreamService) loadIndex(ctx context.Context, bkt, blobName string) (*streamIndex, error) {
+	idx := &streamIndex{}
+
+	info, err := cs.s.SignDownload(ctx, bkt, streamIndexObject(blobName), &storage.SignedURLOptions{})
+	if err == storage.ErrNotFound {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	content, err := fetchObject(ctx, info.URL, maxStreamIndexSize)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	err = json.Unmarshal(content, idx)
+	if err != nil {
+		return nil, status.Error(codes.DataLoss, fmt.Sprintf("cannot parse blob stream index: %v", err))
+	}
+	return idx, nil
+}
+
+func (cs *BlobStreamService) saveIndex(ctx context.Context, bkt, blobName string, idx *streamIndex) error {
+	content, err := json.Marshal(idx)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	err = putObject(ctx, cs.s, bkt, streamIndexObject(blobName), streamIndexContentType, bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return status.Error(codes.Unavailable, xerrors.Errorf("cannot store blob stream index: %w", err).Error())
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/api"
+)
+
+func TestBlobStreamAppendFinalize(t *testing.T) {
+	ctx := context.Background()
+	st := newMemStorage(t, "blobs")
+	bkt := st.Bucket("owner")
+	cs := newBlobStreamService(StreamConfig{}, 0, st)
+
+	append := func(offset int64, data string) (*api.AppendBlobResponse, error) {
+		return cs.Append(ctx, &api.AppendBlobRequest{OwnerId: "owner", Name: "prebuilds/log.txt", Offset: offset, Data: []byte(data)})
+	}
+	expectCode := func(err error, code codes.Code) {
+		t.Helper()
+		if status.Code(err) != code {
+			t.Errorf("expected %v, got %v", code, err)
+		}
+	}
+
+	for _, chunk := range []struct {
+		Offset int64
+		Data   string
+	}{{0, "hello "}, {6, "live "}, {11, "world"}} {
+		resp, err := append(chunk.Offset, chunk.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := chunk.Offset + int64(len(chunk.Data)); resp.Size != exp {
+			t.Errorf("size = %d, expected %d", resp.Size, exp)
+		}
+	}
+
+	// retrying an append which succeeded does not append twice
+	resp, err := append(6, "live ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Size != 16 {
+		t.Errorf("size = %d after retrying an append, expected 16", resp.Size)
+	}
+	_, err = append(6, "lost ")
+	expectCode(err, codes.FailedPrecondition)
+	_, err = append(20, "gap")
+	expectCode(err, codes.FailedPrecondition)
+	_, err = append(16, strings.Repeat("x", maxStreamChunkSize+1))
+	expectCode(err, codes.InvalidArgument)
+
+	// the blob is not available for download until it is finalized
+	if _, ok := st.Get(bkt, "blobs/prebuilds/log.txt"); ok {
+		t.Error("blob was available before it was finalized")
+	}
+	_, err = cs.Finalize(ctx, &api.FinalizeBlobRequest{OwnerId: "owner", Name: "prebuilds/log.txt", Size: 20})
+	expectCode(err, codes.FailedPrecondition)
+	fin, err := cs.Finalize(ctx, &api.FinalizeBlobRequest{OwnerId: "owner", Name: "prebuilds/log.txt", Size: 16, ContentType: "text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fin.Size != 16 {
+		t.Errorf("finalized size = %d, expected 16", fin.Size)
+	}
+	blob, ok := st.Get(bkt, "blobs/prebuilds/log.txt")
+	if !ok {
+		t.Fatal("finalized blob is missing")
+	}
+	if blob.Content != "hello live world" || blob.ContentType != "text/plain" {
+		t.Errorf("unexpected finalized blob: %+v", blob)
+	}
+	if _, ok := st.Get(bkt, streamChunkObject("blobs/prebuilds/log.txt", 6)); ok {
+		t.Error("chunks were not deleted after finalization")
+	}
+
+	// finalizing is idempotent, appending to a finalized blob is not possible
+	_, err = cs.Finalize(ctx, &api.FinalizeBlobRequest{OwnerId: "owner", Name: "prebuilds/log.txt", Size: 16})
+	if err != nil {
+		t.Errorf("finalizing again failed: %v", err)
+	}
+	_, err = append(16, "more")
+	expectCode(err, codes.FailedPrecondition)
+
+	// blobs which were uploaded in one go cannot be appended to or overwritten
+	st.Put(bkt, "blobs/uploaded.txt", memObject{Content: "uploaded"})
+	_, err = cs.Append(ctx, &api.AppendBlobRequest{OwnerId: "owner", Name: "uploaded.txt", Data: []byte("x")})
+	expectCode(err, codes.AlreadyExists)
+	_, err = cs.Finalize(ctx, &api.FinalizeBlobRequest{OwnerId: "owner", Name: "uploaded.txt"})
+	expectCode(err, codes.FailedPrecondition)
+	if blob, _ := st.Get(bkt, "blobs/uploaded.txt"); blob.Content != "uploaded" {
+		t.Errorf("uploaded blob was overwritten with %q", blob.Content)
+	}
+}
+
+type tailServer struct {
+	grpc.ServerStream
+
+	ctx  context.Context
+	msgs chan *api.TailBlobResponse
+}
+
+func (s *tailServer) Context() context.Context { return s.ctx }
+
+func (s *tailServer) Send(resp *api.TailBlobResponse) error {
+	s.msgs <- resp
+	return nil
+}
+
+func TestBlobStreamTail(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	st := newMemStorage(t, "blobs")
+	cs := newBlobStreamService(StreamConfig{PollInterval: util.Duration(5 * time.Millisecond)}, 0, st)
+
+	tail := func(name string, offset int64) (content string, err error) {
+		srv := &tailServer{ctx: ctx, msgs: make(chan *api.TailBlobResponse)}
+		errc := make(chan error, 1)
+		go func() {
+			errc <- cs.Tail(&api.TailBlobRequest{OwnerId: "owner", Name: name, Offset: offset}, srv)
+		}()
+
+		var (
+			res  strings.Builder
+			next = offset
+		)
+		for {
+			select {
+			case msg := <-srv.msgs:
+				if msg.Offset != next {
+					t.Errorf("got data at %d, expected %d", msg.Offset, next)
+				}
+				if msg.Finalized {
+					return res.String(), <-errc
+				}
+				res.Write(msg.Data)
+				next += int64(len(msg.Data))
+			case err := <-errc:
+				return res.String(), err
+			}
+		}
+	}
+
+	// tailers which start before the first append wait for it and follow the blob until it is finalized
+	done := make(chan struct{})
+	var (
+		content string
+		err     error
+	)
+	go func() {
+		defer close(done)
+		content, err = tail("prebuilds/log.txt", 3)
+	}()
+	var offset int64
+	for _, chunk := range []string{"hello ", "live ", "world"} {
+		time.Sleep(10 * time.Millisecond)
+		_, aerr := cs.Append(ctx, &api.AppendBlobRequest{OwnerId: "owner", Name: "prebuilds/log.txt", Offset: offset, Data: []byte(chunk)})
+		if aerr != nil {
+			t.Fatal(aerr)
+		}
+		offset += int64(len(chunk))
+	}
+	_, ferr := cs.Finalize(ctx, &api.FinalizeBlobRequest{OwnerId: "owner", Name: "prebuilds/log.txt", Size: offset})
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	<-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "lo live world" {
+		t.Errorf("tailed %q, expected %q", content, "lo live world")
+	}
+
+	// late tailers read the finalized blob
+	content, err = tail("prebuilds/log.txt", 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "live world" {
+		t.Errorf("tailed %q from the finalized blob, expected %q", content, "live world")
+	}
+	_, err = tail("prebuilds/log.txt", 17)
+	if status.Code(err) != codes.OutOfRange {
+		t.Errorf("expected tailing past the end of a finalized blob to fail, got %v", err)
+	}
+
+	// blobs which were uploaded in one go are finalized from the start
+	st.Put(st.Bucket("owner"), "blobs/uploaded.txt", memObject{Content: "uploaded"})
+	content, err = tail("uploaded.txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "uploaded" {
+		t.Errorf("tailed %q, expected %q", content, "uploaded")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	dictionaryPrefix = "compression-dictionaries/"
+
+	// maxDictionarySize mirrors compression.maxDictionarySize, which we cannot import without an import cycle
+	maxDictionarySize = 32 * 1024
+)
+
+// DictionaryStore keeps the compression dictionary of each repository in the remote storage of a dedicated owner.
+// An empty dictionary records that a repository was trained, but its dictionary did not pay off.
+type DictionaryStore struct {
+	Storage PresignedAccess
+	Owner   string
+	Client  *http.Client
+}
+
+// DictionaryObject returns the name of the object which holds the dictionary of a repository
+func DictionaryObject(repository string) string {
+	repository = strings.TrimSuffix(strings.TrimSuffix(repository, "/"), ".git")
+	return fmt.Sprintf("%s%x", dictionaryPrefix, sha256.Sum256([]byte(repository)))
+}
+
+func (s *DictionaryStore) client() *http.Client {
+	if s.Client == nil {
+		return http.DefaultClient
+	}
+	return s.Client
+}
+
+// Get downloads the dictionary of a repository. found is false if the repository was never trained.
+func (s *DictionaryStore) Get(ctx context.Context, repository string) (dict []byte, found bool, err error) {
+	info, err := s.Storage.SignDownload(ctx, s.Storage.Bucket(s.Owner), DictionaryObject(repository), &SignedURLOptions{})
+	if err == ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, xerrors.Errorf("cannot sign dictionary download: %w", err)
+	}
+	if info.Size > maxDictionarySize {
+		return nil, false, xerrors.Errorf("dictionary exceeds %d bytes", maxDictionarySize)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", info.URL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, false, xerrors.Errorf("cannot download dictionary: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, xerrors.Errorf("cannot download dictionary: %s", resp.Status)
+	}
+	dict, err = io.ReadAll(io.LimitReader(resp.Body, maxDictionarySize+1))
+	if err != nil {
+		return nil, false, xerrors.Errorf("cannot download dictionary: %w", err)
+	}
+	if len(dict) > maxDictionarySize {
+		return nil, false, xerrors.Errorf("dictionary exceeds %d bytes", maxDictionarySize)
+	}
+	return dict, true, nil
+}
+
+// Put uploads the dictionary of a repository
+func (s *DictionaryStore) Put(ctx context.Context, repository string, dict []byte) error {
+	if len(dict) > maxDictionarySize {
+		return xerrors.Errorf("dictionary exceeds %d bytes", maxDictionarySize)
+	}
+
+	err := s.Storage.EnsureExists(ctx, s.Owner)
+	if err != nil {
+		return xerrors.Errorf("cannot create dictionary bucket: %w", err)
+	}
+	info, err := s.Storage.SignUpload(ctx, s.Storage.Bucket(s.Owner), DictionaryObject(repository), &SignedURLOptions{
+		ContentType: "application/octet-stream",
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot sign dictionary upload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", info.URL, bytes.NewReader(dict))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return xerrors.Errorf("cannot upload dictionary: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("cannot upload dictionary: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakePresignedStorage struct {
+	PresignedNoopStorage
+	URL string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *fakePresignedStorage) Bucket(owner string) string { return "gitpod-" + owner }
+
+func (s *fakePresignedStorage) SignDownload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (*DownloadInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.objects[bucket+"/"+obj]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &DownloadInfo{URL: s.URL + "/" + bucket + "/" + obj, Size: int64(len(content))}, nil
+}
+
+func (s *fakePresignedStorage) SignUpload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (*UploadInfo, error) {
+	return &UploadInfo{URL: s.URL + "/" + bucket + "/" + obj}, nil
+}
+
+func (s *fakePresignedStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case "PUT":
+		content, _ := io.ReadAll(r.Body)
+		s.objects[key] = content
+	case "GET":
+		_, _ = w.Write(s.objects[key])
+	}
+}
+
+func TestDictionaryStore(t *testing.T) {
+	fake := &fakePresignedStorage{objects: make(map[string][]byte)}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	fake.URL = srv.URL
+	store := &DictionaryStore{Storage: fake, Owner: "dictionaries"}
+
+	_, found, err := store.Get(context.Background(), "https://github.com/gitpod-io/gitpod.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("expected no dictionary before training")
+	}
+
+	err = store.Put(context.Background(), "https://github.com/gitpod-io/gitpod.git", []byte("foobar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the same repository without .git suffix
+	dict, found, err := store.Get(context.Background(), "https://github.com/gitpod-io/gitpod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || string(dict) != "foobar" {
+		t.Errorf("unexpected dictionary %q (found: %v)", dict, found)
+	}
+
+	// a dictionary which did not pay off is remembered too
+	err = store.Put(context.Background(), "https://github.com/gitpod-io/website", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict, found, err = store.Get(context.Background(), "https://github.com/gitpod-io/website")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || len(dict) != 0 {
+		t.Errorf("expected an empty dictionary, got %q (found: %v)", dict, found)
+	}
+}
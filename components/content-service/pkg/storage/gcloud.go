@@ -736,6 +736,46 @@ type PresignedGCPStorage struct {
 	accessID   string
 }
 
+// SetStorageClass rewrites all objects with the given prefix to a storage class, keeping their metadata
+func (p *PresignedGCPStorage) SetStorageClass(ctx context.Context, bucket, prefix, class string) (size int64, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gcloud.SetStorageClass")
+	defer tracing.FinishSpan(span, &err)
+
+	client, err := newGCPClient(ctx, p.config)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	b := client.Bucket(bucket)
+	it := b.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, xerrors.Errorf("cannot list objects of %s/%s: %w", bucket, prefix, err)
+		}
+		size += attrs.Size
+		if attrs.StorageClass == class {
+			continue
+		}
+
+		obj := b.Object(attrs.Name)
+		copier := obj.CopierFrom(obj)
+		copier.ContentType = attrs.ContentType
+		copier.Metadata = attrs.Metadata
+		copier.StorageClass = class
+		_, err = copier.Run(ctx)
+		if err != nil {
+			return 0, xerrors.Errorf("cannot move %s/%s to storage class %s: %w", bucket, attrs.Name, class, err)
+		}
+	}
+	return size, nil
+}
+
 // Bucket provides the bucket name for a particular user
 func (p *PresignedGCPStorage) Bucket(owner string) string {
 	return gcpBucketName(p.stage, owner)
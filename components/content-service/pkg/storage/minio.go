@@ -395,6 +395,44 @@ func (s *presignedMinIOStorage) DeleteObject(ctx context.Context, bucket string,
 	return err
 }
 
+// SetStorageClass copies all objects with the given prefix onto themselves with a new storage class, keeping their metadata
+func (s *presignedMinIOStorage) SetStorageClass(ctx context.Context, bucket, prefix, class string) (size int64, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "minio.SetStorageClass")
+	defer tracing.FinishSpan(span, &err)
+
+	for object := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return 0, xerrors.Errorf("cannot list objects of %s/%s: %w", bucket, prefix, object.Err)
+		}
+		size += object.Size
+		if object.StorageClass == class {
+			continue
+		}
+
+		info, err := s.client.StatObject(ctx, bucket, object.Key, minio.StatObjectOptions{})
+		if err != nil {
+			return 0, xerrors.Errorf("cannot stat %s/%s: %w", bucket, object.Key, err)
+		}
+		meta := make(map[string]string, len(info.UserMetadata)+2)
+		for k, v := range info.UserMetadata {
+			meta[k] = v
+		}
+		meta["Content-Type"] = info.ContentType
+		meta["X-Amz-Storage-Class"] = class
+		_, err = s.client.CopyObject(ctx,
+			minio.CopyDestOptions{Bucket: bucket, Object: object.Key, UserMetadata: meta, ReplaceMetadata: true},
+			minio.CopySrcOptions{Bucket: bucket, Object: object.Key},
+		)
+		if err != nil {
+			return 0, xerrors.Errorf("cannot move %s/%s to storage class %s: %w", bucket, object.Key, class, err)
+		}
+	}
+	return size, nil
+}
+
 func annotationToAmzMetaHeader(annotation string) string {
 	return http.CanonicalHeaderKey(fmt.Sprintf("X-Amz-Meta-%s", annotation))
 }
@@ -86,6 +86,11 @@ func (s *PresignedNoopStorage) DeleteObject(ctx context.Context, bucket string,
 	return nil
 }
 
+// SetStorageClass does nothing
+func (s *PresignedNoopStorage) SetStorageClass(ctx context.Context, bucket, prefix, class string) (size int64, err error) {
+	return 0, nil
+}
+
 // Bucket returns an empty string
 func (*PresignedNoopStorage) Bucket(string) string {
 	return ""
@@ -15,6 +15,7 @@ import (
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/compression"
 )
 
 const (
@@ -69,6 +70,15 @@ type PresignedAccess interface {
 
 	// DeleteObject deletes objects in the given bucket specified by the given query
 	DeleteObject(ctx context.Context, bucket string, query *DeleteObjectQuery) error
+
+	// SetStorageClass moves all objects with the given prefix to a storage class, e.g. to archive them in cold storage.
+	// It returns the total size of the objects.
+	SetStorageClass(ctx context.Context, bucket, prefix, class string) (size int64, err error)
+}
+
+// WorkspacePrefix is the prefix of all objects of a workspace in its owner's bucket, e.g. its backups
+func WorkspacePrefix(workspaceID string) string {
+	return fmt.Sprintf("workspaces/%s/", workspaceID)
 }
 
 // ObjectMeta describtes the metadata of a remote object
@@ -205,6 +215,9 @@ const (
 
 	// ObjectAnnotationOCIContentType is the OCI media type of the object
 	ObjectAnnotationOCIContentType = "gitpod-oci-contentType"
+
+	// ObjectAnnotationCompression is the compression method of the object, see compression.Method
+	ObjectAnnotationCompression = "gitpod-compression"
 )
 
 // Config configures the remote storage we use
@@ -319,7 +332,11 @@ func NewPresignedAccess(c *Config) (PresignedAccess, error) {
 }
 
 func extractTarbal(ctx context.Context, dest string, src io.Reader, mappings []archive.IDMapping) error {
-	err := archive.ExtractTarbal(ctx, src, dest, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
+	src, _, err := compression.Decompress(src)
+	if err != nil {
+		return xerrors.Errorf("cannot decompress %s: %w", dest, err)
+	}
+	err = archive.ExtractTarbal(ctx, src, dest, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
 	if err != nil {
 		return xerrors.Errorf("tar %s: %s", dest, err.Error())
 	}
@@ -13,6 +13,16 @@ import (
 	"fmt"
 )
 
+// Buildpacks Builds the image from your application using Cloud Native Buildpacks instead of a docker file. The image is rebuilt for every commit.
+type Buildpacks struct {
+
+	// The builder image that provides the buildpacks. Defaults to `paketobuildpacks/builder:base`.
+	Builder string `yaml:"builder,omitempty"`
+
+	// Environment variables to configure the buildpacks with, e.g. `BP_JVM_VERSION: 17`.
+	Env map[string]string `yaml:"env,omitempty"`
+}
+
 // Env Environment variables to set.
 type Env struct {
 }
@@ -42,6 +52,9 @@ type GitpodConfig struct {
 	// The Docker image to run your workspace in.
 	Image interface{} `yaml:"image,omitempty"`
 
+	// Runs an OpenTelemetry collector in the workspace which forwards the traces and logs of your applications to a backend.
+	Otel *Otel `yaml:"otel,omitempty"`
+
 	// List of exposed ports.
 	Ports []*PortsItems `yaml:"ports,omitempty"`
 
@@ -58,11 +71,37 @@ type GitpodConfig struct {
 // Image_object The Docker image to run your workspace in.
 type Image_object struct {
 
-	// Relative path to the context path (optional). Should only be set if you need to copy files into the image.
+	// Builds the image from your application using Cloud Native Buildpacks instead of a docker file. The image is rebuilt for every commit.
+	Buildpacks *Buildpacks `yaml:"buildpacks,omitempty"`
+
+	// Relative path to the context path (optional). For docker files, should only be set if you need to copy files into the image. For buildpacks, the application to build. For Nix, the directory that contains flake.nix.
 	Context string `yaml:"context,omitempty"`
 
 	// Relative path to a docker file.
-	File string `yaml:"file"`
+	File string `yaml:"file,omitempty"`
+
+	// Builds the image from a Nix flake instead of a docker file. The image is rebuilt when flake.nix or flake.lock change.
+	Nix *Nix `yaml:"nix,omitempty"`
+}
+
+// Nix Builds the image from a Nix flake instead of a docker file. The image is rebuilt when flake.nix or flake.lock change.
+type Nix struct {
+
+	// The package of the flake that provides the tools of the workspace, e.g. `gitpod` for `packages.x86_64-linux.gitpod`. Defaults to `default`.
+	Attribute string `yaml:"attribute,omitempty"`
+}
+
+// Otel Runs an OpenTelemetry collector in the workspace which forwards the traces and logs of your applications to a backend.
+type Otel struct {
+
+	// OTLP/HTTP endpoint of the backend to forward to, e.g. `https://api.honeycomb.io`. Signals are sent to `/v1/traces` and `/v1/logs` below it. Environment variables like `${OTEL_BACKEND}` are expanded.
+	Endpoint string `yaml:"endpoint"`
+
+	// Headers to send to the backend, e.g. `x-honeycomb-team: ${HONEYCOMB_API_KEY}`. Environment variables are expanded, so that you can keep secrets in your Gitpod environment variables.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Signals to forward. Defaults to traces and logs.
+	Signals []string `yaml:"signals,omitempty"`
 }
 
 // PortsItems
@@ -141,6 +180,61 @@ type Vscode struct {
 	Extensions []string `yaml:"extensions,omitempty"`
 }
 
+func (strct *Buildpacks) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	buf.WriteString("{")
+	comma := false
+	// Marshal the "builder" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"builder\": ")
+	if tmp, err := json.Marshal(strct.Builder); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "env" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"env\": ")
+	if tmp, err := json.Marshal(strct.Env); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+
+	buf.WriteString("}")
+	rv := buf.Bytes()
+	return rv, nil
+}
+
+func (strct *Buildpacks) UnmarshalJSON(b []byte) error {
+	var jsonMap map[string]json.RawMessage
+	if err := json.Unmarshal(b, &jsonMap); err != nil {
+		return err
+	}
+	// parse all the defined properties
+	for k, v := range jsonMap {
+		switch k {
+		case "builder":
+			if err := json.Unmarshal([]byte(v), &strct.Builder); err != nil {
+				return err
+			}
+		case "env":
+			if err := json.Unmarshal([]byte(v), &strct.Env); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("additional property not allowed: \"" + k + "\"")
+		}
+	}
+	return nil
+}
+
 func (strct *Github) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0))
 	buf.WriteString("{")
@@ -240,6 +334,17 @@ func (strct *GitpodConfig) MarshalJSON() ([]byte, error) {
 		buf.Write(tmp)
 	}
 	comma = true
+	// Marshal the "otel" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"otel\": ")
+	if tmp, err := json.Marshal(strct.Otel); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 	// Marshal the "ports" field
 	if comma {
 		buf.WriteString(",")
@@ -318,6 +423,10 @@ func (strct *GitpodConfig) UnmarshalJSON(b []byte) error {
 			if err := json.Unmarshal([]byte(v), &strct.Image); err != nil {
 				return err
 			}
+		case "otel":
+			if err := json.Unmarshal([]byte(v), &strct.Otel); err != nil {
+				return err
+			}
 		case "ports":
 			if err := json.Unmarshal([]byte(v), &strct.Ports); err != nil {
 				return err
@@ -345,6 +454,17 @@ func (strct *Image_object) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(make([]byte, 0))
 	buf.WriteString("{")
 	comma := false
+	// Marshal the "buildpacks" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"buildpacks\": ")
+	if tmp, err := json.Marshal(strct.Buildpacks); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 	// Marshal the "context" field
 	if comma {
 		buf.WriteString(",")
@@ -356,8 +476,6 @@ func (strct *Image_object) MarshalJSON() ([]byte, error) {
 		buf.Write(tmp)
 	}
 	comma = true
-	// "File" field is required
-	// only required object types supported for marshal checking (for now)
 	// Marshal the "file" field
 	if comma {
 		buf.WriteString(",")
@@ -369,6 +487,17 @@ func (strct *Image_object) MarshalJSON() ([]byte, error) {
 		buf.Write(tmp)
 	}
 	comma = true
+	// Marshal the "nix" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"nix\": ")
+	if tmp, err := json.Marshal(strct.Nix); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
 
 	buf.WriteString("}")
 	rv := buf.Bytes()
@@ -376,7 +505,6 @@ func (strct *Image_object) MarshalJSON() ([]byte, error) {
 }
 
 func (strct *Image_object) UnmarshalJSON(b []byte) error {
-	fileReceived := false
 	var jsonMap map[string]json.RawMessage
 	if err := json.Unmarshal(b, &jsonMap); err != nil {
 		return err
@@ -384,6 +512,10 @@ func (strct *Image_object) UnmarshalJSON(b []byte) error {
 	// parse all the defined properties
 	for k, v := range jsonMap {
 		switch k {
+		case "buildpacks":
+			if err := json.Unmarshal([]byte(v), &strct.Buildpacks); err != nil {
+				return err
+			}
 		case "context":
 			if err := json.Unmarshal([]byte(v), &strct.Context); err != nil {
 				return err
@@ -392,14 +524,131 @@ func (strct *Image_object) UnmarshalJSON(b []byte) error {
 			if err := json.Unmarshal([]byte(v), &strct.File); err != nil {
 				return err
 			}
-			fileReceived = true
+		case "nix":
+			if err := json.Unmarshal([]byte(v), &strct.Nix); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("additional property not allowed: \"" + k + "\"")
+		}
+	}
+	return nil
+}
+
+func (strct *Nix) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	buf.WriteString("{")
+	comma := false
+	// Marshal the "attribute" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"attribute\": ")
+	if tmp, err := json.Marshal(strct.Attribute); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+
+	buf.WriteString("}")
+	rv := buf.Bytes()
+	return rv, nil
+}
+
+func (strct *Nix) UnmarshalJSON(b []byte) error {
+	var jsonMap map[string]json.RawMessage
+	if err := json.Unmarshal(b, &jsonMap); err != nil {
+		return err
+	}
+	// parse all the defined properties
+	for k, v := range jsonMap {
+		switch k {
+		case "attribute":
+			if err := json.Unmarshal([]byte(v), &strct.Attribute); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("additional property not allowed: \"" + k + "\"")
+		}
+	}
+	return nil
+}
+
+func (strct *Otel) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	buf.WriteString("{")
+	comma := false
+	// "Endpoint" field is required
+	// only required object types supported for marshal checking (for now)
+	// Marshal the "endpoint" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"endpoint\": ")
+	if tmp, err := json.Marshal(strct.Endpoint); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "headers" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"headers\": ")
+	if tmp, err := json.Marshal(strct.Headers); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+	// Marshal the "signals" field
+	if comma {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\"signals\": ")
+	if tmp, err := json.Marshal(strct.Signals); err != nil {
+		return nil, err
+	} else {
+		buf.Write(tmp)
+	}
+	comma = true
+
+	buf.WriteString("}")
+	rv := buf.Bytes()
+	return rv, nil
+}
+
+func (strct *Otel) UnmarshalJSON(b []byte) error {
+	endpointReceived := false
+	var jsonMap map[string]json.RawMessage
+	if err := json.Unmarshal(b, &jsonMap); err != nil {
+		return err
+	}
+	// parse all the defined properties
+	for k, v := range jsonMap {
+		switch k {
+		case "endpoint":
+			if err := json.Unmarshal([]byte(v), &strct.Endpoint); err != nil {
+				return err
+			}
+			endpointReceived = true
+		case "headers":
+			if err := json.Unmarshal([]byte(v), &strct.Headers); err != nil {
+				return err
+			}
+		case "signals":
+			if err := json.Unmarshal([]byte(v), &strct.Signals); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("additional property not allowed: \"" + k + "\"")
 		}
 	}
-	// check if file (a required property) was received
-	if !fileReceived {
-		return errors.New("\"file\" is required but was not present")
+	// check if endpoint (a required property) was received
+	if !endpointReceived {
+		return errors.New("\"endpoint\" is required but was not present")
 	}
 	return nil
 }
@@ -214,6 +214,8 @@ var errNotConnected = errors.New("not connected to Gitpod server")
 type ConnectToServerOpts struct {
 	Context context.Context
 	Token   string
+	// Cookie authenticates the connection using a session cookie rather than a token
+	Cookie string
 }
 
 // ConnectToServer establishes a new websocket connection to the server
@@ -228,7 +230,7 @@ func ConnectToServer(endpoint string, opts ConnectToServerOpts) (*APIoverJSONRPC
 	}
 
 	var protocol string
-	if epURL.Scheme == "wss:" {
+	if epURL.Scheme == "wss" {
 		protocol = "https"
 	} else {
 		protocol = "http"
@@ -240,6 +242,9 @@ func ConnectToServer(endpoint string, opts ConnectToServerOpts) (*APIoverJSONRPC
 	if opts.Token != "" {
 		reqHeader.Set("Authorization", "Bearer "+opts.Token)
 	}
+	if opts.Cookie != "" {
+		reqHeader.Set("Cookie", opts.Cookie)
+	}
 	ws := NewReconnectingWebsocket(endpoint, reqHeader)
 	go ws.Dial()
 
@@ -527,6 +527,7 @@ export interface WorkspaceConfig {
     gitConfig?: { [config: string]: string };
     github?: GithubAppConfig;
     vscode?: VSCodeConfig;
+    otel?: OTelConfig;
     
     /**
      * Where the config object originates from.
@@ -671,13 +672,28 @@ export interface TaskConfig {
     name?: string;
     before?: string;
     init?: string;
+    inputs?: string[];
     prebuild?: string;
     command?: string;
     env?: { [env: string]: string };
+    secrets?: TaskSecretConfig[];
     openIn?: 'bottom' | 'main' | 'left' | 'right';
     openMode?: 'split-top' | 'split-left' | 'split-right' | 'split-bottom' | 'tab-before' | 'tab-after';
 }
 
+export interface OTelConfig {
+    endpoint: string;
+    headers?: { [header: string]: string };
+    signals?: ('traces' | 'logs')[];
+}
+
+export interface TaskSecretConfig {
+    provider?: string;
+    name: string;
+    env?: string;
+    file?: string;
+}
+
 export namespace TaskConfig {
     export function is(config: any): config is TaskConfig {
         return config
@@ -704,7 +720,7 @@ export namespace WorkspaceImageBuild {
     }
 }
 
-export type ImageConfig = ImageConfigString | ImageConfigFile;
+export type ImageConfig = ImageConfigString | ImageConfigFile | ImageConfigBuildpacks | ImageConfigNix;
 export type ImageConfigString = string;
 export namespace ImageConfigString {
     export function is(config: ImageConfig | undefined): config is ImageConfigString {
@@ -724,6 +740,36 @@ export namespace ImageConfigFile {
             && 'file' in config;
     }
 }
+export interface ImageConfigBuildpacks {
+    buildpacks: {
+        // Builder image which provides the buildpacks
+        builder?: string,
+        // Environment the buildpacks run with
+        env?: { [name: string]: string }
+    },
+    // Path to the application relative to repository root
+    context?: string
+}
+export namespace ImageConfigBuildpacks {
+    export function is(config: ImageConfig | undefined): config is ImageConfigBuildpacks {
+        return typeof config === 'object'
+            && 'buildpacks' in config;
+    }
+}
+export interface ImageConfigNix {
+    nix: {
+        // Package of the flake which provides the workspace tools
+        attribute?: string
+    },
+    // Path to the directory containing flake.nix relative to repository root
+    context?: string
+}
+export namespace ImageConfigNix {
+    export function is(config: ImageConfig | undefined): config is ImageConfigNix {
+        return typeof config === 'object'
+            && 'nix' in config;
+    }
+}
 export interface ExternalImageConfigFile extends ImageConfigFile {
     externalSource: Commit;
 }
@@ -64,6 +64,8 @@ type BuildSource struct {
 	// Types that are valid to be assigned to From:
 	//	*BuildSource_Ref
 	//	*BuildSource_File
+	//	*BuildSource_Buildpacks
+	//	*BuildSource_Nix
 	From                 isBuildSource_From `protobuf_oneof:"from"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
@@ -107,10 +109,22 @@ type BuildSource_File struct {
 	File *BuildSourceDockerfile `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
 }
 
+type BuildSource_Buildpacks struct {
+	Buildpacks *BuildSourceBuildpacks `protobuf:"bytes,3,opt,name=buildpacks,proto3,oneof"`
+}
+
+type BuildSource_Nix struct {
+	Nix *BuildSourceNix `protobuf:"bytes,4,opt,name=nix,proto3,oneof"`
+}
+
 func (*BuildSource_Ref) isBuildSource_From() {}
 
 func (*BuildSource_File) isBuildSource_From() {}
 
+func (*BuildSource_Buildpacks) isBuildSource_From() {}
+
+func (*BuildSource_Nix) isBuildSource_From() {}
+
 func (m *BuildSource) GetFrom() isBuildSource_From {
 	if m != nil {
 		return m.From
@@ -132,11 +146,27 @@ func (m *BuildSource) GetFile() *BuildSourceDockerfile {
 	return nil
 }
 
+func (m *BuildSource) GetBuildpacks() *BuildSourceBuildpacks {
+	if x, ok := m.GetFrom().(*BuildSource_Buildpacks); ok {
+		return x.Buildpacks
+	}
+	return nil
+}
+
+func (m *BuildSource) GetNix() *BuildSourceNix {
+	if x, ok := m.GetFrom().(*BuildSource_Nix); ok {
+		return x.Nix
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*BuildSource) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*BuildSource_Ref)(nil),
 		(*BuildSource_File)(nil),
+		(*BuildSource_Buildpacks)(nil),
+		(*BuildSource_Nix)(nil),
 	}
 }
 
@@ -242,6 +272,140 @@ func (m *BuildSourceDockerfile) GetContextPath() string {
 	return ""
 }
 
+// BuildSourceBuildpacks builds the base image from the application source using Cloud Native Buildpacks
+type BuildSourceBuildpacks struct {
+	Source *api.WorkspaceInitializer `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// path of the application relative to the checkout
+	ContextPath string `protobuf:"bytes,2,opt,name=context_path,json=contextPath,proto3" json:"context_path,omitempty"`
+	// builder image which provides the buildpacks, e.g. paketobuildpacks/builder:base
+	Builder string `protobuf:"bytes,3,opt,name=builder,proto3" json:"builder,omitempty"`
+	// environment the buildpacks run with, e.g. BP_JVM_VERSION
+	Env                  map[string]string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BuildSourceBuildpacks) Reset()         { *m = BuildSourceBuildpacks{} }
+func (m *BuildSourceBuildpacks) String() string { return proto.CompactTextString(m) }
+func (*BuildSourceBuildpacks) ProtoMessage()    {}
+func (*BuildSourceBuildpacks) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a464e6dbb36703b9, []int{3}
+}
+
+func (m *BuildSourceBuildpacks) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildSourceBuildpacks.Unmarshal(m, b)
+}
+func (m *BuildSourceBuildpacks) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildSourceBuildpacks.Marshal(b, m, deterministic)
+}
+func (m *BuildSourceBuildpacks) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildSourceBuildpacks.Merge(m, src)
+}
+func (m *BuildSourceBuildpacks) XXX_Size() int {
+	return xxx_messageInfo_BuildSourceBuildpacks.Size(m)
+}
+func (m *BuildSourceBuildpacks) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildSourceBuildpacks.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildSourceBuildpacks proto.InternalMessageInfo
+
+func (m *BuildSourceBuildpacks) GetSource() *api.WorkspaceInitializer {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (m *BuildSourceBuildpacks) GetContextPath() string {
+	if m != nil {
+		return m.ContextPath
+	}
+	return ""
+}
+
+func (m *BuildSourceBuildpacks) GetBuilder() string {
+	if m != nil {
+		return m.Builder
+	}
+	return ""
+}
+
+func (m *BuildSourceBuildpacks) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+// BuildSourceNix builds the base image from a Nix flake
+type BuildSourceNix struct {
+	Source *api.WorkspaceInitializer `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// path of the directory containing flake.nix relative to the checkout
+	FlakePath string `protobuf:"bytes,2,opt,name=flake_path,json=flakePath,proto3" json:"flake_path,omitempty"`
+	// flake output which provides the workspace tools, e.g. "default" for packages.<system>.default
+	Attribute string `protobuf:"bytes,3,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	// identifies the content of flake.nix and flake.lock
+	FlakeVersion         string   `protobuf:"bytes,4,opt,name=flake_version,json=flakeVersion,proto3" json:"flake_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildSourceNix) Reset()         { *m = BuildSourceNix{} }
+func (m *BuildSourceNix) String() string { return proto.CompactTextString(m) }
+func (*BuildSourceNix) ProtoMessage()    {}
+func (*BuildSourceNix) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a464e6dbb36703b9, []int{4}
+}
+
+func (m *BuildSourceNix) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildSourceNix.Unmarshal(m, b)
+}
+func (m *BuildSourceNix) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildSourceNix.Marshal(b, m, deterministic)
+}
+func (m *BuildSourceNix) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildSourceNix.Merge(m, src)
+}
+func (m *BuildSourceNix) XXX_Size() int {
+	return xxx_messageInfo_BuildSourceNix.Size(m)
+}
+func (m *BuildSourceNix) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildSourceNix.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildSourceNix proto.InternalMessageInfo
+
+func (m *BuildSourceNix) GetSource() *api.WorkspaceInitializer {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (m *BuildSourceNix) GetFlakePath() string {
+	if m != nil {
+		return m.FlakePath
+	}
+	return ""
+}
+
+func (m *BuildSourceNix) GetAttribute() string {
+	if m != nil {
+		return m.Attribute
+	}
+	return ""
+}
+
+func (m *BuildSourceNix) GetFlakeVersion() string {
+	if m != nil {
+		return m.FlakeVersion
+	}
+	return ""
+}
+
 type ResolveBaseImageRequest struct {
 	Ref                  string             `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
 	Auth                 *BuildRegistryAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
@@ -254,7 +418,7 @@ func (m *ResolveBaseImageRequest) Reset()         { *m = ResolveBaseImageRequest
 func (m *ResolveBaseImageRequest) String() string { return proto.CompactTextString(m) }
 func (*ResolveBaseImageRequest) ProtoMessage()    {}
 func (*ResolveBaseImageRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{3}
+	return fileDescriptor_a464e6dbb36703b9, []int{5}
 }
 
 func (m *ResolveBaseImageRequest) XXX_Unmarshal(b []byte) error {
@@ -300,7 +464,7 @@ func (m *ResolveBaseImageResponse) Reset()         { *m = ResolveBaseImageRespon
 func (m *ResolveBaseImageResponse) String() string { return proto.CompactTextString(m) }
 func (*ResolveBaseImageResponse) ProtoMessage()    {}
 func (*ResolveBaseImageResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{4}
+	return fileDescriptor_a464e6dbb36703b9, []int{6}
 }
 
 func (m *ResolveBaseImageResponse) XXX_Unmarshal(b []byte) error {
@@ -340,7 +504,7 @@ func (m *ResolveWorkspaceImageRequest) Reset()         { *m = ResolveWorkspaceIm
 func (m *ResolveWorkspaceImageRequest) String() string { return proto.CompactTextString(m) }
 func (*ResolveWorkspaceImageRequest) ProtoMessage()    {}
 func (*ResolveWorkspaceImageRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{5}
+	return fileDescriptor_a464e6dbb36703b9, []int{7}
 }
 
 func (m *ResolveWorkspaceImageRequest) XXX_Unmarshal(b []byte) error {
@@ -388,7 +552,7 @@ func (m *ResolveWorkspaceImageResponse) Reset()         { *m = ResolveWorkspaceI
 func (m *ResolveWorkspaceImageResponse) String() string { return proto.CompactTextString(m) }
 func (*ResolveWorkspaceImageResponse) ProtoMessage()    {}
 func (*ResolveWorkspaceImageResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{6}
+	return fileDescriptor_a464e6dbb36703b9, []int{8}
 }
 
 func (m *ResolveWorkspaceImageResponse) XXX_Unmarshal(b []byte) error {
@@ -431,8 +595,10 @@ func (m *ResolveWorkspaceImageResponse) GetStatus() BuildStatus {
 }
 
 type BuildRequest struct {
-	Source               *BuildSource       `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
-	Auth                 *BuildRegistryAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	Source *BuildSource       `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Auth   *BuildRegistryAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+	// debug, if set, makes a failing Dockerfile build start a temporary workspace from the last layer that built successfully
+	Debug                *BuildDebugOptions `protobuf:"bytes,3,opt,name=debug,proto3" json:"debug,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
 	XXX_sizecache        int32              `json:"-"`
@@ -442,7 +608,7 @@ func (m *BuildRequest) Reset()         { *m = BuildRequest{} }
 func (m *BuildRequest) String() string { return proto.CompactTextString(m) }
 func (*BuildRequest) ProtoMessage()    {}
 func (*BuildRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{7}
+	return fileDescriptor_a464e6dbb36703b9, []int{9}
 }
 
 func (m *BuildRequest) XXX_Unmarshal(b []byte) error {
@@ -477,6 +643,53 @@ func (m *BuildRequest) GetAuth() *BuildRegistryAuth {
 	return nil
 }
 
+func (m *BuildRequest) GetDebug() *BuildDebugOptions {
+	if m != nil {
+		return m.Debug
+	}
+	return nil
+}
+
+type BuildDebugOptions struct {
+	// owner is the user who gets access to the debug workspace
+	Owner                string   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildDebugOptions) Reset()         { *m = BuildDebugOptions{} }
+func (m *BuildDebugOptions) String() string { return proto.CompactTextString(m) }
+func (*BuildDebugOptions) ProtoMessage()    {}
+func (*BuildDebugOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a464e6dbb36703b9, []int{10}
+}
+
+func (m *BuildDebugOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildDebugOptions.Unmarshal(m, b)
+}
+func (m *BuildDebugOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildDebugOptions.Marshal(b, m, deterministic)
+}
+func (m *BuildDebugOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildDebugOptions.Merge(m, src)
+}
+func (m *BuildDebugOptions) XXX_Size() int {
+	return xxx_messageInfo_BuildDebugOptions.Size(m)
+}
+func (m *BuildDebugOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildDebugOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildDebugOptions proto.InternalMessageInfo
+
+func (m *BuildDebugOptions) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
 type BuildRegistryAuth struct {
 	// Types that are valid to be assigned to Mode:
 	//	*BuildRegistryAuth_Total
@@ -491,7 +704,7 @@ func (m *BuildRegistryAuth) Reset()         { *m = BuildRegistryAuth{} }
 func (m *BuildRegistryAuth) String() string { return proto.CompactTextString(m) }
 func (*BuildRegistryAuth) ProtoMessage()    {}
 func (*BuildRegistryAuth) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{8}
+	return fileDescriptor_a464e6dbb36703b9, []int{11}
 }
 
 func (m *BuildRegistryAuth) XXX_Unmarshal(b []byte) error {
@@ -568,7 +781,7 @@ func (m *BuildRegistryAuthTotal) Reset()         { *m = BuildRegistryAuthTotal{}
 func (m *BuildRegistryAuthTotal) String() string { return proto.CompactTextString(m) }
 func (*BuildRegistryAuthTotal) ProtoMessage()    {}
 func (*BuildRegistryAuthTotal) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{9}
+	return fileDescriptor_a464e6dbb36703b9, []int{12}
 }
 
 func (m *BuildRegistryAuthTotal) XXX_Unmarshal(b []byte) error {
@@ -609,7 +822,7 @@ func (m *BuildRegistryAuthSelective) Reset()         { *m = BuildRegistryAuthSel
 func (m *BuildRegistryAuthSelective) String() string { return proto.CompactTextString(m) }
 func (*BuildRegistryAuthSelective) ProtoMessage()    {}
 func (*BuildRegistryAuthSelective) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{10}
+	return fileDescriptor_a464e6dbb36703b9, []int{13}
 }
 
 func (m *BuildRegistryAuthSelective) XXX_Unmarshal(b []byte) error {
@@ -652,20 +865,22 @@ func (m *BuildRegistryAuthSelective) GetAnyOf() []string {
 }
 
 type BuildResponse struct {
-	Ref                  string      `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
-	BaseRef              string      `protobuf:"bytes,4,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`
-	Status               BuildStatus `protobuf:"varint,2,opt,name=status,proto3,enum=builder.BuildStatus" json:"status,omitempty"`
-	Message              string      `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Ref     string      `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	BaseRef string      `protobuf:"bytes,4,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`
+	Status  BuildStatus `protobuf:"varint,2,opt,name=status,proto3,enum=builder.BuildStatus" json:"status,omitempty"`
+	Message string      `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// debug describes the debug workspace of a failed debug build
+	Debug                *BuildDebugInfo `protobuf:"bytes,5,opt,name=debug,proto3" json:"debug,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *BuildResponse) Reset()         { *m = BuildResponse{} }
 func (m *BuildResponse) String() string { return proto.CompactTextString(m) }
 func (*BuildResponse) ProtoMessage()    {}
 func (*BuildResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{11}
+	return fileDescriptor_a464e6dbb36703b9, []int{14}
 }
 
 func (m *BuildResponse) XXX_Unmarshal(b []byte) error {
@@ -714,6 +929,71 @@ func (m *BuildResponse) GetMessage() string {
 	return ""
 }
 
+func (m *BuildResponse) GetDebug() *BuildDebugInfo {
+	if m != nil {
+		return m.Debug
+	}
+	return nil
+}
+
+type BuildDebugInfo struct {
+	// ref is the workspace image built from the last successful layer of the failed build
+	Ref string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// instance_id is the ID of the debug workspace instance
+	InstanceId string `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// url is the URL of the debug workspace
+	Url                  string   `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BuildDebugInfo) Reset()         { *m = BuildDebugInfo{} }
+func (m *BuildDebugInfo) String() string { return proto.CompactTextString(m) }
+func (*BuildDebugInfo) ProtoMessage()    {}
+func (*BuildDebugInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a464e6dbb36703b9, []int{15}
+}
+
+func (m *BuildDebugInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BuildDebugInfo.Unmarshal(m, b)
+}
+func (m *BuildDebugInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BuildDebugInfo.Marshal(b, m, deterministic)
+}
+func (m *BuildDebugInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildDebugInfo.Merge(m, src)
+}
+func (m *BuildDebugInfo) XXX_Size() int {
+	return xxx_messageInfo_BuildDebugInfo.Size(m)
+}
+func (m *BuildDebugInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildDebugInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildDebugInfo proto.InternalMessageInfo
+
+func (m *BuildDebugInfo) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *BuildDebugInfo) GetInstanceId() string {
+	if m != nil {
+		return m.InstanceId
+	}
+	return ""
+}
+
+func (m *BuildDebugInfo) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
 type LogsRequest struct {
 	BuildRef             string   `protobuf:"bytes,1,opt,name=build_ref,json=buildRef,proto3" json:"build_ref,omitempty"`
 	Censored             bool     `protobuf:"varint,2,opt,name=censored,proto3" json:"censored,omitempty"`
@@ -726,7 +1006,7 @@ func (m *LogsRequest) Reset()         { *m = LogsRequest{} }
 func (m *LogsRequest) String() string { return proto.CompactTextString(m) }
 func (*LogsRequest) ProtoMessage()    {}
 func (*LogsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{12}
+	return fileDescriptor_a464e6dbb36703b9, []int{16}
 }
 
 func (m *LogsRequest) XXX_Unmarshal(b []byte) error {
@@ -772,7 +1052,7 @@ func (m *LogsResponse) Reset()         { *m = LogsResponse{} }
 func (m *LogsResponse) String() string { return proto.CompactTextString(m) }
 func (*LogsResponse) ProtoMessage()    {}
 func (*LogsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{13}
+	return fileDescriptor_a464e6dbb36703b9, []int{17}
 }
 
 func (m *LogsResponse) XXX_Unmarshal(b []byte) error {
@@ -810,7 +1090,7 @@ func (m *ListBuildsRequest) Reset()         { *m = ListBuildsRequest{} }
 func (m *ListBuildsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListBuildsRequest) ProtoMessage()    {}
 func (*ListBuildsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{14}
+	return fileDescriptor_a464e6dbb36703b9, []int{18}
 }
 
 func (m *ListBuildsRequest) XXX_Unmarshal(b []byte) error {
@@ -842,7 +1122,7 @@ func (m *ListBuildsResponse) Reset()         { *m = ListBuildsResponse{} }
 func (m *ListBuildsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListBuildsResponse) ProtoMessage()    {}
 func (*ListBuildsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{15}
+	return fileDescriptor_a464e6dbb36703b9, []int{19}
 }
 
 func (m *ListBuildsResponse) XXX_Unmarshal(b []byte) error {
@@ -883,7 +1163,7 @@ func (m *BuildInfo) Reset()         { *m = BuildInfo{} }
 func (m *BuildInfo) String() string { return proto.CompactTextString(m) }
 func (*BuildInfo) ProtoMessage()    {}
 func (*BuildInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a464e6dbb36703b9, []int{16}
+	return fileDescriptor_a464e6dbb36703b9, []int{20}
 }
 
 func (m *BuildInfo) XXX_Unmarshal(b []byte) error {
@@ -930,15 +1210,20 @@ func init() {
 	proto.RegisterType((*BuildSource)(nil), "builder.BuildSource")
 	proto.RegisterType((*BuildSourceReference)(nil), "builder.BuildSourceReference")
 	proto.RegisterType((*BuildSourceDockerfile)(nil), "builder.BuildSourceDockerfile")
+	proto.RegisterType((*BuildSourceBuildpacks)(nil), "builder.BuildSourceBuildpacks")
+	proto.RegisterMapType((map[string]string)(nil), "builder.BuildSourceBuildpacks.EnvEntry")
+	proto.RegisterType((*BuildSourceNix)(nil), "builder.BuildSourceNix")
 	proto.RegisterType((*ResolveBaseImageRequest)(nil), "builder.ResolveBaseImageRequest")
 	proto.RegisterType((*ResolveBaseImageResponse)(nil), "builder.ResolveBaseImageResponse")
 	proto.RegisterType((*ResolveWorkspaceImageRequest)(nil), "builder.ResolveWorkspaceImageRequest")
 	proto.RegisterType((*ResolveWorkspaceImageResponse)(nil), "builder.ResolveWorkspaceImageResponse")
 	proto.RegisterType((*BuildRequest)(nil), "builder.BuildRequest")
+	proto.RegisterType((*BuildDebugOptions)(nil), "builder.BuildDebugOptions")
 	proto.RegisterType((*BuildRegistryAuth)(nil), "builder.BuildRegistryAuth")
 	proto.RegisterType((*BuildRegistryAuthTotal)(nil), "builder.BuildRegistryAuthTotal")
 	proto.RegisterType((*BuildRegistryAuthSelective)(nil), "builder.BuildRegistryAuthSelective")
 	proto.RegisterType((*BuildResponse)(nil), "builder.BuildResponse")
+	proto.RegisterType((*BuildDebugInfo)(nil), "builder.BuildDebugInfo")
 	proto.RegisterType((*LogsRequest)(nil), "builder.LogsRequest")
 	proto.RegisterType((*LogsResponse)(nil), "builder.LogsResponse")
 	proto.RegisterType((*ListBuildsRequest)(nil), "builder.ListBuildsRequest")
@@ -951,62 +1236,77 @@ func init() {
 }
 
 var fileDescriptor_a464e6dbb36703b9 = []byte{
-	// 868 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xdf, 0x6f, 0x1b, 0x45,
-	0x10, 0xf6, 0xc5, 0x8e, 0x7f, 0x8c, 0xdd, 0xe2, 0x2c, 0x75, 0x31, 0x0e, 0x81, 0xf4, 0x4a, 0xc1,
-	0xaa, 0x6a, 0x1b, 0x02, 0xa8, 0x12, 0xe2, 0x81, 0x18, 0x04, 0x89, 0xa8, 0x04, 0xda, 0x22, 0x2a,
-	0xe0, 0xc1, 0x5a, 0x9f, 0xe7, 0xec, 0x25, 0xe7, 0xdd, 0xe3, 0x76, 0x2f, 0x21, 0x15, 0xe2, 0x09,
-	0x89, 0x67, 0x1e, 0xf9, 0xaf, 0xf8, 0x93, 0xd0, 0xed, 0xed, 0xf9, 0x5c, 0xfb, 0x0c, 0x44, 0x48,
-	0xbc, 0x79, 0x67, 0xbe, 0xf9, 0xe6, 0x9b, 0xd9, 0xd9, 0xf1, 0x41, 0x9b, 0x2f, 0xe7, 0xd3, 0x98,
-	0x07, 0x33, 0x8c, 0x86, 0x61, 0x24, 0xb5, 0x24, 0x35, 0x7b, 0xec, 0x3d, 0xf0, 0xa4, 0xd0, 0x28,
-	0xf4, 0x40, 0x61, 0x74, 0xc9, 0x3d, 0x1c, 0xb0, 0x90, 0x8f, 0xb8, 0xe0, 0x9a, 0xb3, 0x80, 0x3f,
-	0xcf, 0xf0, 0xee, 0x2f, 0xd0, 0x1c, 0x27, 0x11, 0x4f, 0x65, 0x1c, 0x79, 0x48, 0xde, 0x85, 0x72,
-	0x84, 0x7e, 0xd7, 0x39, 0x76, 0xfa, 0xcd, 0x93, 0xa3, 0x61, 0xc6, 0xbd, 0x06, 0xa1, 0xe8, 0x63,
-	0x84, 0xc2, 0xc3, 0xb3, 0x12, 0x4d, 0xb0, 0xe4, 0x7d, 0xa8, 0xf8, 0x3c, 0xc0, 0xee, 0x9e, 0x89,
-	0x79, 0xbd, 0x28, 0xe6, 0x53, 0xe9, 0x5d, 0x60, 0x94, 0xa0, 0xce, 0x4a, 0xd4, 0xa0, 0xc7, 0x55,
-	0xa8, 0xf8, 0x91, 0x5c, 0xba, 0x7d, 0xb8, 0x53, 0x44, 0x4e, 0xda, 0xb9, 0x90, 0x86, 0xc9, 0xe3,
-	0xfe, 0xe9, 0x40, 0xa7, 0x90, 0x93, 0x7c, 0x04, 0x55, 0x65, 0x6c, 0x56, 0xf7, 0x9b, 0x43, 0x5b,
-	0xbb, 0x2d, 0x7d, 0xf8, 0x4c, 0x46, 0x17, 0x2a, 0x64, 0x1e, 0x9e, 0xe7, 0xf5, 0x53, 0x1b, 0x43,
-	0x06, 0x40, 0x66, 0x2b, 0xae, 0xc9, 0x25, 0x46, 0x8a, 0x4b, 0x61, 0xaa, 0x69, 0xd0, 0x83, 0xdc,
-	0xf3, 0x4d, 0xea, 0x20, 0x6f, 0xc3, 0x4b, 0x6b, 0xf0, 0x90, 0xe9, 0x45, 0xb7, 0x6c, 0xb0, 0xb7,
-	0x73, 0xf3, 0x57, 0x4c, 0x2f, 0xc8, 0x3d, 0x68, 0x19, 0x19, 0x3f, 0xe9, 0x14, 0x55, 0x31, 0xa8,
-	0xa6, 0xb5, 0x25, 0x10, 0xf7, 0x7b, 0x78, 0x85, 0xa2, 0x92, 0xc1, 0x25, 0x8e, 0x99, 0xc2, 0xf3,
-	0x25, 0x9b, 0x23, 0xc5, 0x1f, 0x63, 0x54, 0x7a, 0xbb, 0x7e, 0x32, 0x84, 0x0a, 0x8b, 0xf5, 0xc2,
-	0xf6, 0xb9, 0xf7, 0x62, 0x9f, 0x29, 0xce, 0xb9, 0xd2, 0xd1, 0xf5, 0x69, 0xac, 0x17, 0xd4, 0xe0,
-	0xdc, 0x47, 0xd0, 0xdd, 0x26, 0x57, 0xa1, 0x14, 0xaa, 0xa8, 0xbb, 0x3f, 0xc3, 0x6b, 0x16, 0x9d,
-	0x37, 0x6b, 0x5d, 0xcf, 0xa3, 0x8d, 0x1e, 0xdf, 0x29, 0x9c, 0x8d, 0xac, 0xa7, 0x37, 0xd5, 0xfa,
-	0x1c, 0x8e, 0x76, 0x64, 0xdf, 0x25, 0x98, 0xbc, 0x0a, 0xf5, 0x29, 0x53, 0x38, 0x49, 0xcc, 0xe9,
-	0x05, 0xd4, 0x92, 0x33, 0x45, 0xdf, 0x68, 0xd5, 0x4c, 0xc7, 0xca, 0xe4, 0xbf, 0xbd, 0xa5, 0xd5,
-	0xf8, 0xa8, 0xc5, 0xb8, 0x01, 0xb4, 0xac, 0xac, 0xff, 0xa3, 0xd2, 0x3f, 0x1c, 0x38, 0xd8, 0xf2,
-	0x91, 0xc7, 0xb0, 0xaf, 0xa5, 0x66, 0x81, 0x4d, 0xf9, 0xc6, 0x6e, 0x9a, 0xaf, 0x13, 0xd8, 0x59,
-	0x89, 0xa6, 0x78, 0xf2, 0x09, 0x34, 0x14, 0x06, 0xe8, 0x69, 0x7e, 0x99, 0xbd, 0xc0, 0xfb, 0xbb,
-	0x83, 0x9f, 0x66, 0xd0, 0xb3, 0x12, 0xcd, 0xe3, 0x92, 0xb7, 0xb8, 0x94, 0x33, 0x74, 0x3f, 0x80,
-	0xbb, 0xc5, 0xf9, 0xc8, 0x21, 0x34, 0x58, 0x10, 0xc8, 0xab, 0x09, 0x0b, 0x52, 0x8d, 0x75, 0x5a,
-	0x37, 0x86, 0xd3, 0x20, 0x70, 0x7f, 0x73, 0xa0, 0xb7, 0x3b, 0x15, 0xb9, 0x0f, 0xb7, 0xd2, 0xd8,
-	0xe4, 0x7a, 0x22, 0x0c, 0x6d, 0x7c, 0xcb, 0x18, 0xc7, 0xa9, 0x2d, 0x79, 0x84, 0x29, 0xe8, 0x2a,
-	0xbb, 0xff, 0x04, 0xb9, 0x67, 0x90, 0x07, 0xc6, 0xf3, 0x6c, 0xcd, 0x41, 0x3a, 0x50, 0x65, 0xe2,
-	0x7a, 0x22, 0x93, 0xab, 0x2f, 0xf7, 0x1b, 0x74, 0x9f, 0x89, 0xeb, 0x2f, 0x7d, 0xf7, 0x57, 0x07,
-	0x6e, 0x59, 0x25, 0xff, 0x6a, 0x6e, 0x2a, 0xff, 0x61, 0x6e, 0x48, 0x17, 0x6a, 0x4b, 0x54, 0x8a,
-	0xcd, 0x31, 0x9b, 0x3f, 0x7b, 0x74, 0x3f, 0x83, 0xe6, 0x13, 0x39, 0x57, 0xd9, 0x40, 0x1d, 0x42,
-	0xc3, 0xf0, 0x4c, 0x72, 0x25, 0xf5, 0x69, 0xaa, 0xd2, 0x27, 0x3d, 0xa8, 0x7b, 0x28, 0x94, 0x8c,
-	0x70, 0x66, 0xcb, 0x5d, 0x9d, 0xdd, 0x3e, 0xb4, 0x52, 0x1e, 0x5b, 0x4c, 0x17, 0x6a, 0x76, 0xb1,
-	0x19, 0x9a, 0x16, 0xcd, 0x8e, 0xee, 0xcb, 0x70, 0xf0, 0x84, 0x2b, 0x6d, 0x64, 0x66, 0x79, 0xdd,
-	0x8f, 0x81, 0xac, 0x1b, 0x2d, 0xc9, 0x43, 0xa8, 0x9a, 0xe4, 0xaa, 0xeb, 0x1c, 0x97, 0xfb, 0xcd,
-	0x13, 0xf2, 0x62, 0x91, 0xe7, 0xc2, 0x97, 0xd4, 0x22, 0xdc, 0x1f, 0xa0, 0xb1, 0x32, 0x16, 0xb4,
-	0xf2, 0x66, 0xfd, 0x3a, 0x02, 0x50, 0x9a, 0x45, 0x1a, 0x67, 0x13, 0xa6, 0x4d, 0xcb, 0xca, 0xb4,
-	0x61, 0x2d, 0xa7, 0xfa, 0xe1, 0x17, 0xd9, 0x1f, 0x51, 0x8a, 0x6e, 0x42, 0x2d, 0x16, 0x17, 0x42,
-	0x5e, 0x89, 0x76, 0x29, 0x39, 0x44, 0xb1, 0x10, 0x5c, 0xcc, 0xdb, 0x0e, 0x69, 0x43, 0x6b, 0x26,
-	0x05, 0x4e, 0x54, 0xec, 0x79, 0xa8, 0x54, 0x7b, 0x6f, 0x65, 0xf1, 0x19, 0x0f, 0xe2, 0x08, 0xdb,
-	0xe5, 0x93, 0xdf, 0xcb, 0xd0, 0x32, 0x0b, 0x64, 0x9c, 0x0a, 0x22, 0xdf, 0x42, 0x7b, 0x73, 0x19,
-	0x92, 0xe3, 0x95, 0xdc, 0x1d, 0x4b, 0xb8, 0x77, 0xef, 0x6f, 0x10, 0x69, 0x3b, 0xdd, 0x12, 0x59,
-	0x40, 0xa7, 0x70, 0x77, 0x91, 0x07, 0x9b, 0xd1, 0x85, 0x9b, 0xb5, 0xf7, 0xd6, 0x3f, 0xc1, 0x56,
-	0x99, 0x3e, 0x84, 0x7d, 0x53, 0x0f, 0xe9, 0x6c, 0x3e, 0xf1, 0x94, 0xe9, 0xee, 0xa6, 0x39, 0x8b,
-	0x7c, 0xc7, 0x21, 0x8f, 0xa1, 0x92, 0xcc, 0x12, 0xc9, 0xef, 0x68, 0x6d, 0x44, 0x7b, 0x9d, 0x0d,
-	0xeb, 0x5a, 0xe0, 0xe7, 0x00, 0xf9, 0x14, 0x91, 0x7c, 0xc1, 0x6d, 0xcd, 0x5b, 0xef, 0xb0, 0xd0,
-	0x97, 0x51, 0x8d, 0x47, 0xdf, 0x0d, 0xe6, 0x5c, 0x2f, 0xe2, 0xe9, 0xd0, 0x93, 0xcb, 0xd1, 0x9c,
-	0xeb, 0x50, 0xce, 0x06, 0x5c, 0xda, 0x5f, 0x23, 0x9e, 0x14, 0x3b, 0xb0, 0x0c, 0x23, 0x16, 0xf2,
-	0x69, 0xd5, 0x7c, 0xa1, 0xbc, 0xf7, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0x26, 0x99, 0x8f, 0x63,
-	0xe5, 0x08, 0x00, 0x00,
+	// 1114 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xdd, 0x72, 0x1b, 0x35,
+	0x14, 0xf6, 0xda, 0x4e, 0x62, 0x1f, 0xbb, 0xc1, 0x11, 0x49, 0x6b, 0x9c, 0x86, 0xa6, 0x5b, 0x4a,
+	0x43, 0xa9, 0x9d, 0x12, 0x7e, 0x0a, 0x1d, 0x2e, 0x1a, 0xd3, 0x42, 0x32, 0x74, 0x28, 0xb3, 0x05,
+	0x3a, 0xc0, 0x85, 0x47, 0x5e, 0xcb, 0x8e, 0xf0, 0x5a, 0x5a, 0x56, 0x5a, 0x27, 0xee, 0x70, 0xcb,
+	0x70, 0xcd, 0x25, 0x37, 0x3c, 0x03, 0x33, 0xbc, 0x08, 0x2f, 0xc2, 0x3b, 0x30, 0xd2, 0x4a, 0x5e,
+	0xc7, 0x5e, 0x17, 0x3a, 0x99, 0xe1, 0xce, 0x3a, 0xe7, 0xfb, 0xce, 0xcf, 0xa7, 0xb3, 0x47, 0x86,
+	0x1a, 0x1d, 0x0d, 0xba, 0x31, 0x0d, 0x7a, 0x24, 0x6a, 0x85, 0x11, 0x97, 0x1c, 0xad, 0x99, 0x63,
+	0xe3, 0xa6, 0xcf, 0x99, 0x24, 0x4c, 0x36, 0x05, 0x89, 0xc6, 0xd4, 0x27, 0x4d, 0x1c, 0xd2, 0x7d,
+	0xca, 0xa8, 0xa4, 0x38, 0xa0, 0xcf, 0x2d, 0xde, 0xfd, 0xdb, 0x81, 0x4a, 0x5b, 0x51, 0x9e, 0xf2,
+	0x38, 0xf2, 0x09, 0x7a, 0x07, 0x0a, 0x11, 0xe9, 0xd7, 0x9d, 0x5d, 0x67, 0xaf, 0x72, 0xb0, 0xd3,
+	0xb2, 0xc1, 0x67, 0x20, 0x1e, 0xe9, 0x93, 0x88, 0x30, 0x9f, 0x1c, 0xe5, 0x3c, 0x85, 0x45, 0xef,
+	0x41, 0xb1, 0x4f, 0x03, 0x52, 0xcf, 0x6b, 0xce, 0xeb, 0x59, 0x9c, 0x87, 0xdc, 0x1f, 0x92, 0x48,
+	0xa1, 0x8e, 0x72, 0x9e, 0x46, 0xa3, 0x07, 0x00, 0x1a, 0x18, 0x62, 0x7f, 0x28, 0xea, 0x85, 0xe5,
+	0xdc, 0xf6, 0x14, 0x75, 0x94, 0xf3, 0x66, 0x38, 0xe8, 0x6d, 0x28, 0x30, 0x7a, 0x56, 0x2f, 0x6a,
+	0xea, 0x95, 0x2c, 0xea, 0x17, 0xf4, 0x4c, 0x15, 0xc9, 0xe8, 0x59, 0x7b, 0x15, 0x8a, 0xfd, 0x88,
+	0x8f, 0xdc, 0x3d, 0xd8, 0xcc, 0xea, 0x05, 0xd5, 0xd2, 0xbe, 0xcb, 0xba, 0x2d, 0xf7, 0x2f, 0x07,
+	0xb6, 0x32, 0x5b, 0x40, 0x1f, 0xc3, 0xaa, 0xd0, 0x36, 0x23, 0xd3, 0x1b, 0x2d, 0xa3, 0xb5, 0x91,
+	0xba, 0xf5, 0x8c, 0x47, 0x43, 0x11, 0x62, 0x9f, 0x1c, 0xa7, 0x7a, 0x7b, 0x86, 0x83, 0x9a, 0x80,
+	0x7a, 0xd3, 0x58, 0x9d, 0x31, 0x89, 0x04, 0xe5, 0x4c, 0x8b, 0x57, 0xf6, 0x36, 0x52, 0xcf, 0x37,
+	0x89, 0x03, 0xdd, 0x82, 0x57, 0x66, 0xe0, 0x21, 0x96, 0x27, 0x5a, 0xac, 0xb2, 0xb7, 0x9e, 0x9a,
+	0xbf, 0xc4, 0xf2, 0x04, 0x5d, 0x87, 0xaa, 0x2e, 0xe3, 0x4c, 0x26, 0xa8, 0xa2, 0x46, 0x55, 0x8c,
+	0x4d, 0x41, 0xdc, 0x9f, 0xf3, 0xe7, 0x5a, 0x4a, 0x95, 0xbd, 0x60, 0x4b, 0xf3, 0xa9, 0xf3, 0x0b,
+	0xa9, 0x51, 0x1d, 0xec, 0x64, 0x9a, 0xf2, 0xed, 0x11, 0x7d, 0x04, 0x05, 0xc2, 0xc6, 0xf5, 0xe2,
+	0x6e, 0x61, 0xaf, 0x72, 0x70, 0xeb, 0xc5, 0x13, 0xd0, 0x7a, 0xc4, 0xc6, 0x8f, 0x98, 0x8c, 0x26,
+	0x9e, 0xe2, 0x34, 0x3e, 0x80, 0x92, 0x35, 0xa8, 0x0b, 0x1c, 0x92, 0x89, 0xbd, 0xc0, 0x21, 0x99,
+	0xa0, 0x4d, 0x58, 0x19, 0xe3, 0x20, 0x26, 0xa6, 0x9c, 0xe4, 0x70, 0x3f, 0xff, 0xa1, 0xe3, 0xfe,
+	0xe1, 0xc0, 0xfa, 0xf9, 0x31, 0xb9, 0xa0, 0x00, 0x3b, 0x00, 0xfd, 0x00, 0x0f, 0xc9, 0x6c, 0xfb,
+	0x65, 0x6d, 0xd1, 0xcd, 0x5f, 0x85, 0x32, 0x96, 0x32, 0xa2, 0xdd, 0x58, 0x12, 0xd3, 0x7e, 0x6a,
+	0x40, 0x37, 0xe0, 0x52, 0x42, 0xb6, 0xb3, 0x90, 0xdc, 0x5c, 0x55, 0x1b, 0xcd, 0x18, 0xb8, 0xdf,
+	0xc3, 0x15, 0x8f, 0x08, 0x1e, 0x8c, 0x49, 0x1b, 0x0b, 0x72, 0x3c, 0xc2, 0x03, 0xe2, 0x91, 0x1f,
+	0x63, 0x22, 0xe4, 0xe2, 0xe8, 0xa2, 0x16, 0x14, 0x71, 0x6c, 0x0a, 0xa9, 0x1c, 0x34, 0xce, 0x6b,
+	0xea, 0x91, 0x01, 0x15, 0x32, 0x9a, 0x1c, 0xc6, 0xf2, 0xc4, 0xd3, 0x38, 0xf7, 0x0e, 0xd4, 0x17,
+	0x83, 0x8b, 0x90, 0x33, 0x91, 0xf5, 0x61, 0xfc, 0x04, 0x57, 0x0d, 0x3a, 0xd5, 0x64, 0xb6, 0x9e,
+	0x3b, 0x73, 0x52, 0x6e, 0x66, 0x6e, 0x11, 0x2b, 0xdd, 0xcb, 0xd6, 0xfa, 0x1c, 0x76, 0x96, 0x64,
+	0x5f, 0x56, 0x30, 0x7a, 0x0d, 0x4a, 0x5d, 0x2c, 0x48, 0x47, 0x99, 0xed, 0xf0, 0x61, 0xa1, 0x3e,
+	0x7e, 0x5d, 0xab, 0xc4, 0x32, 0x16, 0x3a, 0xff, 0xfa, 0x42, 0xad, 0xda, 0xe7, 0x19, 0x8c, 0xfb,
+	0xbb, 0x03, 0x55, 0x53, 0xd7, 0xff, 0xd0, 0x2a, 0xba, 0x0b, 0x2b, 0x3d, 0xd2, 0x8d, 0x07, 0xf5,
+	0x42, 0x16, 0xe1, 0xa1, 0x72, 0x3d, 0x09, 0x25, 0xe5, 0x4c, 0x78, 0x09, 0xd0, 0x7d, 0x0b, 0x36,
+	0x16, 0x7c, 0xea, 0x3b, 0xe0, 0xa7, 0x8c, 0x44, 0x46, 0x92, 0xe4, 0xe0, 0xfe, 0xe6, 0xc0, 0xc6,
+	0x42, 0x62, 0x74, 0x0f, 0x56, 0x24, 0x97, 0x38, 0x30, 0xfd, 0x5c, 0x5b, 0x5e, 0xe3, 0x57, 0x0a,
+	0x76, 0x94, 0xf3, 0x12, 0x3c, 0xfa, 0x04, 0xca, 0x82, 0x04, 0xc4, 0x97, 0x74, 0x6c, 0x5f, 0x82,
+	0x1b, 0xcb, 0xc9, 0x4f, 0x2d, 0xf4, 0x28, 0xe7, 0xa5, 0x3c, 0xb5, 0xa4, 0x47, 0xbc, 0x47, 0xdc,
+	0xf7, 0xe1, 0x72, 0x76, 0x3e, 0xb4, 0x0d, 0x65, 0x1c, 0x04, 0xfc, 0xb4, 0x83, 0x83, 0xa4, 0xc6,
+	0x92, 0x57, 0xd2, 0x86, 0xc3, 0x20, 0x70, 0x7f, 0x71, 0xa0, 0xb1, 0x3c, 0x95, 0xfa, 0xce, 0x12,
+	0xae, 0xba, 0xfc, 0x88, 0x84, 0x86, 0x5f, 0xd5, 0xc6, 0x76, 0x62, 0x53, 0xdb, 0x39, 0x01, 0x9d,
+	0xda, 0xe9, 0x52, 0xc8, 0xbc, 0x46, 0x6e, 0x68, 0xcf, 0xb3, 0x19, 0x07, 0xda, 0x82, 0x55, 0xcc,
+	0x26, 0x1d, 0xae, 0x06, 0xab, 0xa0, 0xc4, 0xc5, 0x6c, 0xf2, 0xa4, 0xef, 0xfe, 0xe9, 0xc0, 0x25,
+	0x53, 0xc9, 0x7f, 0x9a, 0xca, 0xe2, 0x05, 0xa6, 0x52, 0xad, 0xd6, 0x11, 0x11, 0x02, 0x0f, 0xec,
+	0x6e, 0xb1, 0x47, 0xd4, 0xb4, 0x03, 0xb4, 0x92, 0xf5, 0x46, 0xea, 0x21, 0x39, 0x66, 0x7d, 0x6e,
+	0xa7, 0xe7, 0x6b, 0x58, 0x3f, 0xef, 0xc8, 0xa8, 0xfa, 0x1a, 0x54, 0x28, 0x13, 0x12, 0x33, 0x9f,
+	0x74, 0x68, 0xcf, 0xac, 0x3a, 0xb0, 0xa6, 0xe3, 0x9e, 0xa2, 0xc4, 0x51, 0x60, 0x2a, 0x51, 0x3f,
+	0xdd, 0x4f, 0xa1, 0xf2, 0x98, 0x0f, 0x84, 0xfd, 0x66, 0xb6, 0xa1, 0xac, 0xcb, 0xe8, 0xa4, 0x91,
+	0x4b, 0xdd, 0x44, 0xab, 0x3e, 0x6a, 0x40, 0xc9, 0x27, 0x4c, 0xf0, 0x88, 0xf4, 0x8c, 0xe8, 0xd3,
+	0xb3, 0xbb, 0x07, 0xd5, 0x24, 0x8e, 0x91, 0xb4, 0x0e, 0x6b, 0x66, 0x47, 0xeb, 0x30, 0x55, 0xcf,
+	0x1e, 0xdd, 0x57, 0x61, 0xe3, 0x31, 0x15, 0x52, 0x37, 0x63, 0xf3, 0xba, 0x0f, 0x00, 0xcd, 0x1a,
+	0x4d, 0x90, 0xdb, 0xb0, 0xaa, 0x93, 0x8b, 0xba, 0xa3, 0x1f, 0x20, 0x74, 0x5e, 0x23, 0x2d, 0x8f,
+	0x41, 0xb8, 0x3f, 0x40, 0x79, 0x6a, 0xcc, 0x90, 0xe6, 0xe5, 0x6e, 0x6d, 0x07, 0x40, 0x48, 0x1c,
+	0x49, 0xd2, 0xeb, 0x60, 0xa9, 0xe5, 0x2a, 0x78, 0x65, 0x63, 0x39, 0x94, 0xb7, 0x3f, 0xb7, 0x7f,
+	0xcb, 0x12, 0x74, 0x05, 0xd6, 0x62, 0x36, 0x64, 0xfc, 0x94, 0xd5, 0x72, 0xea, 0x10, 0xc5, 0x8c,
+	0x51, 0x36, 0xa8, 0x39, 0xa8, 0x06, 0xd5, 0x1e, 0x67, 0xa4, 0x23, 0x62, 0xdf, 0x27, 0x42, 0xd4,
+	0xf2, 0x53, 0x4b, 0x1f, 0xd3, 0x20, 0x8e, 0x48, 0xad, 0x70, 0xf0, 0x6b, 0x01, 0xaa, 0x7a, 0x49,
+	0xb6, 0xcd, 0x9b, 0xfb, 0x2d, 0xd4, 0xe6, 0x17, 0x3e, 0xda, 0x9d, 0x96, 0xbb, 0xe4, 0xa1, 0x69,
+	0x5c, 0x7f, 0x01, 0x22, 0x91, 0xd3, 0xcd, 0xa1, 0x13, 0xd8, 0xca, 0xdc, 0xcf, 0xe8, 0xe6, 0x3c,
+	0x3b, 0xf3, 0xf5, 0x68, 0xbc, 0xf9, 0x6f, 0xb0, 0x69, 0xa6, 0xfb, 0xb0, 0xa2, 0xfb, 0x41, 0x5b,
+	0xf3, 0x8b, 0x26, 0x89, 0x74, 0x79, 0xde, 0x6c, 0x99, 0x77, 0x1d, 0x74, 0x0f, 0x8a, 0x6a, 0x96,
+	0x50, 0x7a, 0x47, 0x33, 0x23, 0xda, 0xd8, 0x9a, 0xb3, 0xce, 0x10, 0x3f, 0x03, 0x48, 0xa7, 0x08,
+	0xa5, 0x2b, 0x79, 0x61, 0xde, 0x1a, 0xdb, 0x99, 0x3e, 0x1b, 0xaa, 0xbd, 0xff, 0x5d, 0x73, 0x40,
+	0xe5, 0x49, 0xdc, 0x6d, 0xf9, 0x7c, 0xb4, 0x3f, 0xa0, 0x32, 0xe4, 0xbd, 0x26, 0xe5, 0xe6, 0xd7,
+	0x3e, 0x55, 0xcd, 0x36, 0x4d, 0x84, 0x7d, 0x1c, 0xd2, 0xee, 0xaa, 0xfe, 0xc3, 0xfe, 0xee, 0x3f,
+	0x03, 0x00, 0xf0, 0xe4, 0xae, 0x4a, 0xf4, 0x0b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
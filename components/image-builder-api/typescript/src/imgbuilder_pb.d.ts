@@ -26,6 +26,18 @@ export class BuildSource extends jspb.Message {
     setFile(value?: BuildSourceDockerfile): void;
 
 
+    hasBuildpacks(): boolean;
+    clearBuildpacks(): void;
+    getBuildpacks(): BuildSourceBuildpacks | undefined;
+    setBuildpacks(value?: BuildSourceBuildpacks): void;
+
+
+    hasNix(): boolean;
+    clearNix(): void;
+    getNix(): BuildSourceNix | undefined;
+    setNix(value?: BuildSourceNix): void;
+
+
     getFromCase(): BuildSource.FromCase;
 
     serializeBinary(): Uint8Array;
@@ -42,6 +54,8 @@ export namespace BuildSource {
     export type AsObject = {
         ref?: BuildSourceReference.AsObject,
         file?: BuildSourceDockerfile.AsObject,
+        buildpacks?: BuildSourceBuildpacks.AsObject,
+        nix?: BuildSourceNix.AsObject,
     }
 
     export enum FromCase {
@@ -51,6 +65,10 @@ export namespace BuildSource {
 
     FILE = 2,
 
+    BUILDPACKS = 3,
+
+    NIX = 4,
+
     }
 
 }
@@ -112,6 +130,80 @@ export namespace BuildSourceDockerfile {
     }
 }
 
+export class BuildSourceBuildpacks extends jspb.Message { 
+
+    hasSource(): boolean;
+    clearSource(): void;
+    getSource(): content_service_api_initializer_pb.WorkspaceInitializer | undefined;
+    setSource(value?: content_service_api_initializer_pb.WorkspaceInitializer): void;
+
+    getContextPath(): string;
+    setContextPath(value: string): void;
+
+    getBuilder(): string;
+    setBuilder(value: string): void;
+
+
+    getEnvMap(): jspb.Map<string, string>;
+    clearEnvMap(): void;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BuildSourceBuildpacks.AsObject;
+    static toObject(includeInstance: boolean, msg: BuildSourceBuildpacks): BuildSourceBuildpacks.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BuildSourceBuildpacks, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BuildSourceBuildpacks;
+    static deserializeBinaryFromReader(message: BuildSourceBuildpacks, reader: jspb.BinaryReader): BuildSourceBuildpacks;
+}
+
+export namespace BuildSourceBuildpacks {
+    export type AsObject = {
+        source?: content_service_api_initializer_pb.WorkspaceInitializer.AsObject,
+        contextPath: string,
+        builder: string,
+
+        envMap: Array<[string, string]>,
+    }
+}
+
+export class BuildSourceNix extends jspb.Message { 
+
+    hasSource(): boolean;
+    clearSource(): void;
+    getSource(): content_service_api_initializer_pb.WorkspaceInitializer | undefined;
+    setSource(value?: content_service_api_initializer_pb.WorkspaceInitializer): void;
+
+    getFlakePath(): string;
+    setFlakePath(value: string): void;
+
+    getAttribute(): string;
+    setAttribute(value: string): void;
+
+    getFlakeVersion(): string;
+    setFlakeVersion(value: string): void;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BuildSourceNix.AsObject;
+    static toObject(includeInstance: boolean, msg: BuildSourceNix): BuildSourceNix.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BuildSourceNix, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BuildSourceNix;
+    static deserializeBinaryFromReader(message: BuildSourceNix, reader: jspb.BinaryReader): BuildSourceNix;
+}
+
+export namespace BuildSourceNix {
+    export type AsObject = {
+        source?: content_service_api_initializer_pb.WorkspaceInitializer.AsObject,
+        flakePath: string,
+        attribute: string,
+        flakeVersion: string,
+    }
+}
+
 export class ResolveBaseImageRequest extends jspb.Message { 
     getRef(): string;
     setRef(value: string): void;
@@ -235,6 +327,12 @@ export class BuildRequest extends jspb.Message {
     setAuth(value?: BuildRegistryAuth): void;
 
 
+    hasDebug(): boolean;
+    clearDebug(): void;
+    getDebug(): BuildDebugOptions | undefined;
+    setDebug(value?: BuildDebugOptions): void;
+
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): BuildRequest.AsObject;
     static toObject(includeInstance: boolean, msg: BuildRequest): BuildRequest.AsObject;
@@ -249,6 +347,28 @@ export namespace BuildRequest {
     export type AsObject = {
         source?: BuildSource.AsObject,
         auth?: BuildRegistryAuth.AsObject,
+        debug?: BuildDebugOptions.AsObject,
+    }
+}
+
+export class BuildDebugOptions extends jspb.Message { 
+    getOwner(): string;
+    setOwner(value: string): void;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BuildDebugOptions.AsObject;
+    static toObject(includeInstance: boolean, msg: BuildDebugOptions): BuildDebugOptions.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BuildDebugOptions, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BuildDebugOptions;
+    static deserializeBinaryFromReader(message: BuildDebugOptions, reader: jspb.BinaryReader): BuildDebugOptions;
+}
+
+export namespace BuildDebugOptions {
+    export type AsObject = {
+        owner: string,
     }
 }
 
@@ -361,6 +481,12 @@ export class BuildResponse extends jspb.Message {
     setMessage(value: string): void;
 
 
+    hasDebug(): boolean;
+    clearDebug(): void;
+    getDebug(): BuildDebugInfo | undefined;
+    setDebug(value?: BuildDebugInfo): void;
+
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): BuildResponse.AsObject;
     static toObject(includeInstance: boolean, msg: BuildResponse): BuildResponse.AsObject;
@@ -377,6 +503,36 @@ export namespace BuildResponse {
         baseRef: string,
         status: BuildStatus,
         message: string,
+        debug?: BuildDebugInfo.AsObject,
+    }
+}
+
+export class BuildDebugInfo extends jspb.Message { 
+    getRef(): string;
+    setRef(value: string): void;
+
+    getInstanceId(): string;
+    setInstanceId(value: string): void;
+
+    getUrl(): string;
+    setUrl(value: string): void;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BuildDebugInfo.AsObject;
+    static toObject(includeInstance: boolean, msg: BuildDebugInfo): BuildDebugInfo.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BuildDebugInfo, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BuildDebugInfo;
+    static deserializeBinaryFromReader(message: BuildDebugInfo, reader: jspb.BinaryReader): BuildDebugInfo;
+}
+
+export namespace BuildDebugInfo {
+    export type AsObject = {
+        ref: string,
+        instanceId: string,
+        url: string,
     }
 }
 
@@ -19,6 +19,8 @@ var global = Function('return this')();
 
 var content$service$api_initializer_pb = require('@gitpod/content-service/lib');
 goog.object.extend(proto, content$service$api_initializer_pb);
+goog.exportSymbol('proto.builder.BuildDebugInfo', null, global);
+goog.exportSymbol('proto.builder.BuildDebugOptions', null, global);
 goog.exportSymbol('proto.builder.BuildInfo', null, global);
 goog.exportSymbol('proto.builder.BuildRegistryAuth', null, global);
 goog.exportSymbol('proto.builder.BuildRegistryAuthSelective', null, global);
@@ -26,7 +28,9 @@ goog.exportSymbol('proto.builder.BuildRegistryAuthTotal', null, global);
 goog.exportSymbol('proto.builder.BuildRequest', null, global);
 goog.exportSymbol('proto.builder.BuildResponse', null, global);
 goog.exportSymbol('proto.builder.BuildSource', null, global);
+goog.exportSymbol('proto.builder.BuildSourceBuildpacks', null, global);
 goog.exportSymbol('proto.builder.BuildSourceDockerfile', null, global);
+goog.exportSymbol('proto.builder.BuildSourceNix', null, global);
 goog.exportSymbol('proto.builder.BuildSourceReference', null, global);
 goog.exportSymbol('proto.builder.BuildStatus', null, global);
 goog.exportSymbol('proto.builder.ListBuildsRequest', null, global);
@@ -100,6 +104,48 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.builder.BuildSourceDockerfile.displayName = 'proto.builder.BuildSourceDockerfile';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.builder.BuildSourceBuildpacks = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.builder.BuildSourceBuildpacks, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.builder.BuildSourceBuildpacks.displayName = 'proto.builder.BuildSourceBuildpacks';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.builder.BuildSourceNix = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.builder.BuildSourceNix, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.builder.BuildSourceNix.displayName = 'proto.builder.BuildSourceNix';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -205,6 +251,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.builder.BuildRequest.displayName = 'proto.builder.BuildRequest';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.builder.BuildDebugOptions = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.builder.BuildDebugOptions, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.builder.BuildDebugOptions.displayName = 'proto.builder.BuildDebugOptions';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -289,6 +356,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.builder.BuildResponse.displayName = 'proto.builder.BuildResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.builder.BuildDebugInfo = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.builder.BuildDebugInfo, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.builder.BuildDebugInfo.displayName = 'proto.builder.BuildDebugInfo';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -403,7 +491,7 @@ if (goog.DEBUG && !COMPILED) {
  * @private {!Array<!Array<number>>}
  * @const
  */
-proto.builder.BuildSource.oneofGroups_ = [[1,2]];
+proto.builder.BuildSource.oneofGroups_ = [[1,2,3,4]];
 
 /**
  * @enum {number}
@@ -411,7 +499,9 @@ proto.builder.BuildSource.oneofGroups_ = [[1,2]];
 proto.builder.BuildSource.FromCase = {
   FROM_NOT_SET: 0,
   REF: 1,
-  FILE: 2
+  FILE: 2,
+  BUILDPACKS: 3,
+  NIX: 4
 };
 
 /**
@@ -451,7 +541,9 @@ proto.builder.BuildSource.prototype.toObject = function(opt_includeInstance) {
 proto.builder.BuildSource.toObject = function(includeInstance, msg) {
   var f, obj = {
     ref: (f = msg.getRef()) && proto.builder.BuildSourceReference.toObject(includeInstance, f),
-    file: (f = msg.getFile()) && proto.builder.BuildSourceDockerfile.toObject(includeInstance, f)
+    file: (f = msg.getFile()) && proto.builder.BuildSourceDockerfile.toObject(includeInstance, f),
+    buildpacks: (f = msg.getBuildpacks()) && proto.builder.BuildSourceBuildpacks.toObject(includeInstance, f),
+    nix: (f = msg.getNix()) && proto.builder.BuildSourceNix.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -498,6 +590,16 @@ proto.builder.BuildSource.deserializeBinaryFromReader = function(msg, reader) {
       reader.readMessage(value,proto.builder.BuildSourceDockerfile.deserializeBinaryFromReader);
       msg.setFile(value);
       break;
+    case 3:
+      var value = new proto.builder.BuildSourceBuildpacks;
+      reader.readMessage(value,proto.builder.BuildSourceBuildpacks.deserializeBinaryFromReader);
+      msg.setBuildpacks(value);
+      break;
+    case 4:
+      var value = new proto.builder.BuildSourceNix;
+      reader.readMessage(value,proto.builder.BuildSourceNix.deserializeBinaryFromReader);
+      msg.setNix(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -543,6 +645,22 @@ proto.builder.BuildSource.serializeBinaryToWriter = function(message, writer) {
       proto.builder.BuildSourceDockerfile.serializeBinaryToWriter
     );
   }
+  f = message.getBuildpacks();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.builder.BuildSourceBuildpacks.serializeBinaryToWriter
+    );
+  }
+  f = message.getNix();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.builder.BuildSourceNix.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -612,6 +730,72 @@ proto.builder.BuildSource.prototype.hasFile = function() {
 };
 
 
+/**
+ * optional BuildSourceBuildpacks buildpacks = 3;
+ * @return {?proto.builder.BuildSourceBuildpacks}
+ */
+proto.builder.BuildSource.prototype.getBuildpacks = function() {
+  return /** @type{?proto.builder.BuildSourceBuildpacks} */ (
+    jspb.Message.getWrapperField(this, proto.builder.BuildSourceBuildpacks, 3));
+};
+
+
+/** @param {?proto.builder.BuildSourceBuildpacks|undefined} value */
+proto.builder.BuildSource.prototype.setBuildpacks = function(value) {
+  jspb.Message.setOneofWrapperField(this, 3, proto.builder.BuildSource.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildSource.prototype.clearBuildpacks = function() {
+  this.setBuildpacks(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildSource.prototype.hasBuildpacks = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional BuildSourceNix nix = 4;
+ * @return {?proto.builder.BuildSourceNix}
+ */
+proto.builder.BuildSource.prototype.getNix = function() {
+  return /** @type{?proto.builder.BuildSourceNix} */ (
+    jspb.Message.getWrapperField(this, proto.builder.BuildSourceNix, 4));
+};
+
+
+/** @param {?proto.builder.BuildSourceNix|undefined} value */
+proto.builder.BuildSource.prototype.setNix = function(value) {
+  jspb.Message.setOneofWrapperField(this, 4, proto.builder.BuildSource.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildSource.prototype.clearNix = function() {
+  this.setNix(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildSource.prototype.hasNix = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
 
 
 
@@ -977,8 +1161,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.builder.ResolveBaseImageRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.builder.ResolveBaseImageRequest.toObject(opt_includeInstance, this);
+proto.builder.BuildSourceBuildpacks.prototype.toObject = function(opt_includeInstance) {
+  return proto.builder.BuildSourceBuildpacks.toObject(opt_includeInstance, this);
 };
 
 
@@ -987,14 +1171,16 @@ proto.builder.ResolveBaseImageRequest.prototype.toObject = function(opt_includeI
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.builder.ResolveBaseImageRequest} msg The msg instance to transform.
+ * @param {!proto.builder.BuildSourceBuildpacks} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.builder.ResolveBaseImageRequest.toObject = function(includeInstance, msg) {
+proto.builder.BuildSourceBuildpacks.toObject = function(includeInstance, msg) {
   var f, obj = {
-    ref: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    auth: (f = msg.getAuth()) && proto.builder.BuildRegistryAuth.toObject(includeInstance, f)
+    source: (f = msg.getSource()) && content$service$api_initializer_pb.WorkspaceInitializer.toObject(includeInstance, f),
+    contextPath: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    builder: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    envMap: (f = msg.getEnvMap()) ? f.toObject(includeInstance, undefined) : []
   };
 
   if (includeInstance) {
@@ -1008,23 +1194,23 @@ proto.builder.ResolveBaseImageRequest.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.builder.ResolveBaseImageRequest}
+ * @return {!proto.builder.BuildSourceBuildpacks}
  */
-proto.builder.ResolveBaseImageRequest.deserializeBinary = function(bytes) {
+proto.builder.BuildSourceBuildpacks.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.builder.ResolveBaseImageRequest;
-  return proto.builder.ResolveBaseImageRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.builder.BuildSourceBuildpacks;
+  return proto.builder.BuildSourceBuildpacks.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.builder.ResolveBaseImageRequest} msg The message object to deserialize into.
+ * @param {!proto.builder.BuildSourceBuildpacks} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.builder.ResolveBaseImageRequest}
+ * @return {!proto.builder.BuildSourceBuildpacks}
  */
-proto.builder.ResolveBaseImageRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.builder.BuildSourceBuildpacks.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1032,13 +1218,23 @@ proto.builder.ResolveBaseImageRequest.deserializeBinaryFromReader = function(msg
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setRef(value);
+      var value = new content$service$api_initializer_pb.WorkspaceInitializer;
+      reader.readMessage(value,content$service$api_initializer_pb.WorkspaceInitializer.deserializeBinaryFromReader);
+      msg.setSource(value);
       break;
     case 2:
-      var value = new proto.builder.BuildRegistryAuth;
-      reader.readMessage(value,proto.builder.BuildRegistryAuth.deserializeBinaryFromReader);
-      msg.setAuth(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setContextPath(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBuilder(value);
+      break;
+    case 4:
+      var value = msg.getEnvMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
       break;
     default:
       reader.skipField();
@@ -1053,9 +1249,9 @@ proto.builder.ResolveBaseImageRequest.deserializeBinaryFromReader = function(msg
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.builder.ResolveBaseImageRequest.prototype.serializeBinary = function() {
+proto.builder.BuildSourceBuildpacks.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.builder.ResolveBaseImageRequest.serializeBinaryToWriter(this, writer);
+  proto.builder.BuildSourceBuildpacks.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1063,75 +1259,122 @@ proto.builder.ResolveBaseImageRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.builder.ResolveBaseImageRequest} message
+ * @param {!proto.builder.BuildSourceBuildpacks} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.builder.ResolveBaseImageRequest.serializeBinaryToWriter = function(message, writer) {
+proto.builder.BuildSourceBuildpacks.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getRef();
+  f = message.getSource();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      content$service$api_initializer_pb.WorkspaceInitializer.serializeBinaryToWriter
+    );
+  }
+  f = message.getContextPath();
   if (f.length > 0) {
     writer.writeString(
-      1,
+      2,
       f
     );
   }
-  f = message.getAuth();
-  if (f != null) {
-    writer.writeMessage(
-      2,
-      f,
-      proto.builder.BuildRegistryAuth.serializeBinaryToWriter
+  f = message.getBuilder();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
     );
   }
+  f = message.getEnvMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(4, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
 };
 
 
 /**
- * optional string ref = 1;
+ * optional contentservice.WorkspaceInitializer source = 1;
+ * @return {?proto.contentservice.WorkspaceInitializer}
+ */
+proto.builder.BuildSourceBuildpacks.prototype.getSource = function() {
+  return /** @type{?proto.contentservice.WorkspaceInitializer} */ (
+    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.WorkspaceInitializer, 1));
+};
+
+
+/** @param {?proto.contentservice.WorkspaceInitializer|undefined} value */
+proto.builder.BuildSourceBuildpacks.prototype.setSource = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildSourceBuildpacks.prototype.clearSource = function() {
+  this.setSource(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildSourceBuildpacks.prototype.hasSource = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional string context_path = 2;
  * @return {string}
  */
-proto.builder.ResolveBaseImageRequest.prototype.getRef = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.builder.BuildSourceBuildpacks.prototype.getContextPath = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /** @param {string} value */
-proto.builder.ResolveBaseImageRequest.prototype.setRef = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+proto.builder.BuildSourceBuildpacks.prototype.setContextPath = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional BuildRegistryAuth auth = 2;
- * @return {?proto.builder.BuildRegistryAuth}
+ * optional string builder = 3;
+ * @return {string}
  */
-proto.builder.ResolveBaseImageRequest.prototype.getAuth = function() {
-  return /** @type{?proto.builder.BuildRegistryAuth} */ (
-    jspb.Message.getWrapperField(this, proto.builder.BuildRegistryAuth, 2));
+proto.builder.BuildSourceBuildpacks.prototype.getBuilder = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
-/** @param {?proto.builder.BuildRegistryAuth|undefined} value */
-proto.builder.ResolveBaseImageRequest.prototype.setAuth = function(value) {
-  jspb.Message.setWrapperField(this, 2, value);
+/** @param {string} value */
+proto.builder.BuildSourceBuildpacks.prototype.setBuilder = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
+ * map<string, string> env = 4;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
  */
-proto.builder.ResolveBaseImageRequest.prototype.clearAuth = function() {
-  this.setAuth(undefined);
+proto.builder.BuildSourceBuildpacks.prototype.getEnvMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 4, opt_noLazyCreate,
+      null));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Clears values from the map. The map will be non-null.
  */
-proto.builder.ResolveBaseImageRequest.prototype.hasAuth = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.builder.BuildSourceBuildpacks.prototype.clearEnvMap = function() {
+  this.getEnvMap().clear();
 };
 
 
@@ -1149,8 +1392,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.builder.ResolveBaseImageResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.builder.ResolveBaseImageResponse.toObject(opt_includeInstance, this);
+proto.builder.BuildSourceNix.prototype.toObject = function(opt_includeInstance) {
+  return proto.builder.BuildSourceNix.toObject(opt_includeInstance, this);
 };
 
 
@@ -1159,13 +1402,16 @@ proto.builder.ResolveBaseImageResponse.prototype.toObject = function(opt_include
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.builder.ResolveBaseImageResponse} msg The msg instance to transform.
+ * @param {!proto.builder.BuildSourceNix} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.builder.ResolveBaseImageResponse.toObject = function(includeInstance, msg) {
+proto.builder.BuildSourceNix.toObject = function(includeInstance, msg) {
   var f, obj = {
-    ref: jspb.Message.getFieldWithDefault(msg, 1, "")
+    source: (f = msg.getSource()) && content$service$api_initializer_pb.WorkspaceInitializer.toObject(includeInstance, f),
+    flakePath: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    attribute: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    flakeVersion: jspb.Message.getFieldWithDefault(msg, 4, "")
   };
 
   if (includeInstance) {
@@ -1179,23 +1425,23 @@ proto.builder.ResolveBaseImageResponse.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.builder.ResolveBaseImageResponse}
+ * @return {!proto.builder.BuildSourceNix}
  */
-proto.builder.ResolveBaseImageResponse.deserializeBinary = function(bytes) {
+proto.builder.BuildSourceNix.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.builder.ResolveBaseImageResponse;
-  return proto.builder.ResolveBaseImageResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.builder.BuildSourceNix;
+  return proto.builder.BuildSourceNix.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.builder.ResolveBaseImageResponse} msg The message object to deserialize into.
+ * @param {!proto.builder.BuildSourceNix} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.builder.ResolveBaseImageResponse}
+ * @return {!proto.builder.BuildSourceNix}
  */
-proto.builder.ResolveBaseImageResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.builder.BuildSourceNix.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1203,8 +1449,403 @@ proto.builder.ResolveBaseImageResponse.deserializeBinaryFromReader = function(ms
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setRef(value);
+      var value = new content$service$api_initializer_pb.WorkspaceInitializer;
+      reader.readMessage(value,content$service$api_initializer_pb.WorkspaceInitializer.deserializeBinaryFromReader);
+      msg.setSource(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setFlakePath(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAttribute(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setFlakeVersion(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.builder.BuildSourceNix.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.builder.BuildSourceNix.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.builder.BuildSourceNix} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.builder.BuildSourceNix.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSource();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      content$service$api_initializer_pb.WorkspaceInitializer.serializeBinaryToWriter
+    );
+  }
+  f = message.getFlakePath();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getAttribute();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getFlakeVersion();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional contentservice.WorkspaceInitializer source = 1;
+ * @return {?proto.contentservice.WorkspaceInitializer}
+ */
+proto.builder.BuildSourceNix.prototype.getSource = function() {
+  return /** @type{?proto.contentservice.WorkspaceInitializer} */ (
+    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.WorkspaceInitializer, 1));
+};
+
+
+/** @param {?proto.contentservice.WorkspaceInitializer|undefined} value */
+proto.builder.BuildSourceNix.prototype.setSource = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildSourceNix.prototype.clearSource = function() {
+  this.setSource(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildSourceNix.prototype.hasSource = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional string flake_path = 2;
+ * @return {string}
+ */
+proto.builder.BuildSourceNix.prototype.getFlakePath = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.builder.BuildSourceNix.prototype.setFlakePath = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string attribute = 3;
+ * @return {string}
+ */
+proto.builder.BuildSourceNix.prototype.getAttribute = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.builder.BuildSourceNix.prototype.setAttribute = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string flake_version = 4;
+ * @return {string}
+ */
+proto.builder.BuildSourceNix.prototype.getFlakeVersion = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.builder.BuildSourceNix.prototype.setFlakeVersion = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.builder.ResolveBaseImageRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.builder.ResolveBaseImageRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.builder.ResolveBaseImageRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.builder.ResolveBaseImageRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    ref: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    auth: (f = msg.getAuth()) && proto.builder.BuildRegistryAuth.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.builder.ResolveBaseImageRequest}
+ */
+proto.builder.ResolveBaseImageRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.builder.ResolveBaseImageRequest;
+  return proto.builder.ResolveBaseImageRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.builder.ResolveBaseImageRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.builder.ResolveBaseImageRequest}
+ */
+proto.builder.ResolveBaseImageRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRef(value);
+      break;
+    case 2:
+      var value = new proto.builder.BuildRegistryAuth;
+      reader.readMessage(value,proto.builder.BuildRegistryAuth.deserializeBinaryFromReader);
+      msg.setAuth(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.builder.ResolveBaseImageRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.builder.ResolveBaseImageRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.builder.ResolveBaseImageRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.builder.ResolveBaseImageRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getRef();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getAuth();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.builder.BuildRegistryAuth.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string ref = 1;
+ * @return {string}
+ */
+proto.builder.ResolveBaseImageRequest.prototype.getRef = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.builder.ResolveBaseImageRequest.prototype.setRef = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional BuildRegistryAuth auth = 2;
+ * @return {?proto.builder.BuildRegistryAuth}
+ */
+proto.builder.ResolveBaseImageRequest.prototype.getAuth = function() {
+  return /** @type{?proto.builder.BuildRegistryAuth} */ (
+    jspb.Message.getWrapperField(this, proto.builder.BuildRegistryAuth, 2));
+};
+
+
+/** @param {?proto.builder.BuildRegistryAuth|undefined} value */
+proto.builder.ResolveBaseImageRequest.prototype.setAuth = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.ResolveBaseImageRequest.prototype.clearAuth = function() {
+  this.setAuth(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.ResolveBaseImageRequest.prototype.hasAuth = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.builder.ResolveBaseImageResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.builder.ResolveBaseImageResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.builder.ResolveBaseImageResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.builder.ResolveBaseImageResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    ref: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.builder.ResolveBaseImageResponse}
+ */
+proto.builder.ResolveBaseImageResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.builder.ResolveBaseImageResponse;
+  return proto.builder.ResolveBaseImageResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.builder.ResolveBaseImageResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.builder.ResolveBaseImageResponse}
+ */
+proto.builder.ResolveBaseImageResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRef(value);
       break;
     default:
       reader.skipField();
@@ -1662,7 +2303,8 @@ proto.builder.BuildRequest.prototype.toObject = function(opt_includeInstance) {
 proto.builder.BuildRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     source: (f = msg.getSource()) && proto.builder.BuildSource.toObject(includeInstance, f),
-    auth: (f = msg.getAuth()) && proto.builder.BuildRegistryAuth.toObject(includeInstance, f)
+    auth: (f = msg.getAuth()) && proto.builder.BuildRegistryAuth.toObject(includeInstance, f),
+    debug: (f = msg.getDebug()) && proto.builder.BuildDebugOptions.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -1709,6 +2351,11 @@ proto.builder.BuildRequest.deserializeBinaryFromReader = function(msg, reader) {
       reader.readMessage(value,proto.builder.BuildRegistryAuth.deserializeBinaryFromReader);
       msg.setAuth(value);
       break;
+    case 3:
+      var value = new proto.builder.BuildDebugOptions;
+      reader.readMessage(value,proto.builder.BuildDebugOptions.deserializeBinaryFromReader);
+      msg.setDebug(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1754,72 +2401,238 @@ proto.builder.BuildRequest.serializeBinaryToWriter = function(message, writer) {
       proto.builder.BuildRegistryAuth.serializeBinaryToWriter
     );
   }
+  f = message.getDebug();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.builder.BuildDebugOptions.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional BuildSource source = 1;
+ * @return {?proto.builder.BuildSource}
+ */
+proto.builder.BuildRequest.prototype.getSource = function() {
+  return /** @type{?proto.builder.BuildSource} */ (
+    jspb.Message.getWrapperField(this, proto.builder.BuildSource, 1));
+};
+
+
+/** @param {?proto.builder.BuildSource|undefined} value */
+proto.builder.BuildRequest.prototype.setSource = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildRequest.prototype.clearSource = function() {
+  this.setSource(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildRequest.prototype.hasSource = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional BuildRegistryAuth auth = 2;
+ * @return {?proto.builder.BuildRegistryAuth}
+ */
+proto.builder.BuildRequest.prototype.getAuth = function() {
+  return /** @type{?proto.builder.BuildRegistryAuth} */ (
+    jspb.Message.getWrapperField(this, proto.builder.BuildRegistryAuth, 2));
+};
+
+
+/** @param {?proto.builder.BuildRegistryAuth|undefined} value */
+proto.builder.BuildRequest.prototype.setAuth = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildRequest.prototype.clearAuth = function() {
+  this.setAuth(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildRequest.prototype.hasAuth = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional BuildDebugOptions debug = 3;
+ * @return {?proto.builder.BuildDebugOptions}
+ */
+proto.builder.BuildRequest.prototype.getDebug = function() {
+  return /** @type{?proto.builder.BuildDebugOptions} */ (
+    jspb.Message.getWrapperField(this, proto.builder.BuildDebugOptions, 3));
+};
+
+
+/** @param {?proto.builder.BuildDebugOptions|undefined} value */
+proto.builder.BuildRequest.prototype.setDebug = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildRequest.prototype.clearDebug = function() {
+  this.setDebug(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildRequest.prototype.hasDebug = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.builder.BuildDebugOptions.prototype.toObject = function(opt_includeInstance) {
+  return proto.builder.BuildDebugOptions.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.builder.BuildDebugOptions} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.builder.BuildDebugOptions.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    owner: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * optional BuildSource source = 1;
- * @return {?proto.builder.BuildSource}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.builder.BuildDebugOptions}
  */
-proto.builder.BuildRequest.prototype.getSource = function() {
-  return /** @type{?proto.builder.BuildSource} */ (
-    jspb.Message.getWrapperField(this, proto.builder.BuildSource, 1));
-};
-
-
-/** @param {?proto.builder.BuildSource|undefined} value */
-proto.builder.BuildRequest.prototype.setSource = function(value) {
-  jspb.Message.setWrapperField(this, 1, value);
+proto.builder.BuildDebugOptions.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.builder.BuildDebugOptions;
+  return proto.builder.BuildDebugOptions.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Clears the message field making it undefined.
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.builder.BuildDebugOptions} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.builder.BuildDebugOptions}
  */
-proto.builder.BuildRequest.prototype.clearSource = function() {
-  this.setSource(undefined);
+proto.builder.BuildDebugOptions.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.builder.BuildRequest.prototype.hasSource = function() {
-  return jspb.Message.getField(this, 1) != null;
+proto.builder.BuildDebugOptions.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.builder.BuildDebugOptions.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * optional BuildRegistryAuth auth = 2;
- * @return {?proto.builder.BuildRegistryAuth}
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.builder.BuildDebugOptions} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.builder.BuildRequest.prototype.getAuth = function() {
-  return /** @type{?proto.builder.BuildRegistryAuth} */ (
-    jspb.Message.getWrapperField(this, proto.builder.BuildRegistryAuth, 2));
-};
-
-
-/** @param {?proto.builder.BuildRegistryAuth|undefined} value */
-proto.builder.BuildRequest.prototype.setAuth = function(value) {
-  jspb.Message.setWrapperField(this, 2, value);
+proto.builder.BuildDebugOptions.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
 };
 
 
 /**
- * Clears the message field making it undefined.
+ * optional string owner = 1;
+ * @return {string}
  */
-proto.builder.BuildRequest.prototype.clearAuth = function() {
-  this.setAuth(undefined);
+proto.builder.BuildDebugOptions.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.builder.BuildRequest.prototype.hasAuth = function() {
-  return jspb.Message.getField(this, 2) != null;
+/** @param {string} value */
+proto.builder.BuildDebugOptions.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
@@ -2408,7 +3221,8 @@ proto.builder.BuildResponse.toObject = function(includeInstance, msg) {
     ref: jspb.Message.getFieldWithDefault(msg, 1, ""),
     baseRef: jspb.Message.getFieldWithDefault(msg, 4, ""),
     status: jspb.Message.getFieldWithDefault(msg, 2, 0),
-    message: jspb.Message.getFieldWithDefault(msg, 3, "")
+    message: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    debug: (f = msg.getDebug()) && proto.builder.BuildDebugInfo.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -2461,6 +3275,11 @@ proto.builder.BuildResponse.deserializeBinaryFromReader = function(msg, reader)
       var value = /** @type {string} */ (reader.readString());
       msg.setMessage(value);
       break;
+    case 5:
+      var value = new proto.builder.BuildDebugInfo;
+      reader.readMessage(value,proto.builder.BuildDebugInfo.deserializeBinaryFromReader);
+      msg.setDebug(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -2518,6 +3337,14 @@ proto.builder.BuildResponse.serializeBinaryToWriter = function(message, writer)
       f
     );
   }
+  f = message.getDebug();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      proto.builder.BuildDebugInfo.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -2581,6 +3408,218 @@ proto.builder.BuildResponse.prototype.setMessage = function(value) {
 };
 
 
+/**
+ * optional BuildDebugInfo debug = 5;
+ * @return {?proto.builder.BuildDebugInfo}
+ */
+proto.builder.BuildResponse.prototype.getDebug = function() {
+  return /** @type{?proto.builder.BuildDebugInfo} */ (
+    jspb.Message.getWrapperField(this, proto.builder.BuildDebugInfo, 5));
+};
+
+
+/** @param {?proto.builder.BuildDebugInfo|undefined} value */
+proto.builder.BuildResponse.prototype.setDebug = function(value) {
+  jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.builder.BuildResponse.prototype.clearDebug = function() {
+  this.setDebug(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.builder.BuildResponse.prototype.hasDebug = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.builder.BuildDebugInfo.prototype.toObject = function(opt_includeInstance) {
+  return proto.builder.BuildDebugInfo.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.builder.BuildDebugInfo} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.builder.BuildDebugInfo.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    ref: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    instanceId: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    url: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.builder.BuildDebugInfo}
+ */
+proto.builder.BuildDebugInfo.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.builder.BuildDebugInfo;
+  return proto.builder.BuildDebugInfo.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.builder.BuildDebugInfo} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.builder.BuildDebugInfo}
+ */
+proto.builder.BuildDebugInfo.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRef(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setInstanceId(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.builder.BuildDebugInfo.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.builder.BuildDebugInfo.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.builder.BuildDebugInfo} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.builder.BuildDebugInfo.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getRef();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getInstanceId();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string ref = 1;
+ * @return {string}
+ */
+proto.builder.BuildDebugInfo.prototype.getRef = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.builder.BuildDebugInfo.prototype.setRef = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string instance_id = 2;
+ * @return {string}
+ */
+proto.builder.BuildDebugInfo.prototype.getInstanceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.builder.BuildDebugInfo.prototype.setInstanceId = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string url = 3;
+ * @return {string}
+ */
+proto.builder.BuildDebugInfo.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.builder.BuildDebugInfo.prototype.setUrl = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
 
 
 
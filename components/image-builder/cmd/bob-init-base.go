@@ -7,20 +7,17 @@ package cmd
 import (
 	"context"
 	"encoding/base64"
-	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 
-	securejoin "github.com/cyphar/filepath-securejoin"
 	proto "github.com/golang/protobuf/proto"
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/content-service/pkg/initializer"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 	"github.com/gitpod-io/gitpod/image-builder/api"
+	"github.com/gitpod-io/gitpod/image-builder/pkg/builder"
 )
 
 // bobInitBase represents the init command
@@ -38,17 +35,21 @@ var bobInitBase = &cobra.Command{
 			log.Fatalf("cannot decode initializer: %v", err)
 		}
 
-		var src api.BuildSourceDockerfile
+		var src api.BuildSource
 		err = proto.Unmarshal(rawinit, &src)
 		if err != nil {
 			log.Fatalf("cannot unmarshal initializer: %v", err)
 		}
+		strategy, err := builder.NewBaseImageStrategy(&src)
+		if err != nil {
+			log.Fatalf("cannot build base image: %v", err)
+		}
 
 		initwd := filepath.Join(wd, "init")
 
 		ctx := context.Background()
 		rms := &storage.DirectNoopStorage{}
-		ilr, err := initializer.NewFromRequest(ctx, initwd, rms, src.Source)
+		ilr, err := initializer.NewFromRequest(ctx, initwd, rms, strategy.Source())
 		if err != nil {
 			log.Fatalf("cannot create initializer: %v", err)
 		}
@@ -68,40 +69,9 @@ var bobInitBase = &cobra.Command{
 			log.WithError(err).Fatal("init failed")
 		}
 
-		initctx, err := securejoin.SecureJoin(initwd, src.ContextPath)
-		if err != nil {
-			log.WithError(err).Fatal("init failed")
-		}
-		if stat, err := os.Stat(initctx); os.IsNotExist(err) {
-			log.Fatalf("Context directory \"%s\" does not exist", src.ContextPath)
-		} else if err != nil {
-			log.Fatalf("Context directory error: %v", err)
-		} else if !stat.IsDir() {
-			log.Fatalf("Context path \"%s\" is not a directory", src.ContextPath)
-		}
-
-		initdf, err := securejoin.SecureJoin(initwd, src.DockerfilePath)
+		err = strategy.PrepareContext(wd)
 		if err != nil {
-			log.WithError(err).Fatal("init failed")
-		}
-		if stat, err := os.Stat(initdf); os.IsNotExist(err) {
-			log.Fatalf("Dockerfile \"%s\" does not exist", src.DockerfilePath)
-		} else if err != nil {
-			log.Fatalf("Dockerfile error: %v", err)
-		} else if stat.IsDir() {
-			log.Fatalf("Dockerfile \"%s\" is a directory", src.DockerfilePath)
-		}
-
-		var cmds [][]string
-		cmds = append(cmds, []string{"sh", "-c", fmt.Sprintf("cp -Rf %s/. .", initctx)})
-		cmds = append(cmds, []string{"mv", "-f", initdf, "Dockerfile"})
-
-		for _, cmd := range cmds {
-			c := exec.Command(cmd[0], cmd[1:]...)
-			c.Dir = ctxwd
-			c.Stdout = log.Log.WriterLevel(logrus.InfoLevel)
-			c.Stderr = log.Log.WriterLevel(logrus.ErrorLevel)
-			c.Run()
+			log.Fatal(err)
 		}
 	},
 }
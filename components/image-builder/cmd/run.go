@@ -28,6 +28,7 @@ import (
 	"github.com/gitpod-io/gitpod/image-builder/api"
 	"github.com/gitpod-io/gitpod/image-builder/pkg/builder"
 	"github.com/gitpod-io/gitpod/image-builder/pkg/resolve"
+	wsmanapi "github.com/gitpod-io/gitpod/ws-manager/api"
 )
 
 // runCmd represents the run command
@@ -80,6 +81,14 @@ var runCmd = &cobra.Command{
 			go resolver.StartCaching(ctx, interval)
 			service.Resolver = resolver
 		}
+		if cfg.Builder.DebugWorkspace != nil {
+			conn, err := grpc.Dial(cfg.Builder.DebugWorkspace.WsManagerAddr, grpc.WithInsecure())
+			if err != nil {
+				log.WithError(err).Fatal("cannot connect to ws-manager")
+			}
+			defer conn.Close()
+			service.WorkspaceManager = wsmanapi.NewWorkspaceManagerClient(conn)
+		}
 
 		err = service.Start(ctx)
 		if err != nil {
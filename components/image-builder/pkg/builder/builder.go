@@ -34,6 +34,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	"github.com/gitpod-io/gitpod/image-builder/api"
 	"github.com/gitpod-io/gitpod/image-builder/pkg/resolve"
+	wsmanapi "github.com/gitpod-io/gitpod/ws-manager/api"
 )
 
 const (
@@ -70,6 +71,9 @@ type DockerBuilder struct {
 	Auth     RegistryAuthenticator
 	Resolver resolve.DockerRefResolver
 
+	// WorkspaceManager starts the debug workspaces of failed debug builds. If nil, debug builds are unavailable.
+	WorkspaceManager wsmanapi.WorkspaceManagerClient
+
 	builderref  string
 	gplayerHash string
 
@@ -192,6 +196,14 @@ func (b *DockerBuilder) Build(req *api.BuildRequest, resp api.ImageBuilder_Build
 	if b.builderref == "" {
 		return status.Error(codes.FailedPrecondition, "no selfbuild available - this image-builder is really broken (missing Start() call)")
 	}
+	if req.Debug != nil {
+		if b.WorkspaceManager == nil || b.Config.DebugWorkspace == nil {
+			return status.Error(codes.FailedPrecondition, "debug builds are not enabled")
+		}
+		if req.Debug.Owner == "" {
+			return status.Error(codes.InvalidArgument, "debug builds need an owner")
+		}
+	}
 
 	// resolve build request authentication
 	reqauth := b.resolveRequestAuth(req.Auth)
@@ -279,7 +291,10 @@ func (b *DockerBuilder) Build(req *api.BuildRequest, resp api.ImageBuilder_Build
 	b.mu.Unlock()
 
 	// make sure that when we're done we're cleaning things up and tell our client
-	var baserefAbsolute string
+	var (
+		baserefAbsolute string
+		debugInfo       *api.BuildDebugInfo
+	)
 	defer func(baseref *string, perr *error) {
 		err := *perr
 		var (
@@ -309,6 +324,7 @@ func (b *DockerBuilder) Build(req *api.BuildRequest, resp api.ImageBuilder_Build
 			Ref:     wsrefstr,
 			BaseRef: *baseref,
 			Message: msg,
+			Debug:   debugInfo,
 		})
 		if err != nil {
 			if status.Code(err) == codes.Unavailable {
@@ -352,7 +368,7 @@ func (b *DockerBuilder) Build(req *api.BuildRequest, resp api.ImageBuilder_Build
 	// which requires registry access. Thus, if the request auth prohibits access to that registry, resolving will fail.
 	//
 	// The latter case (base image is stored in the base image cache registry) can only happen if the build source
-	// is a Dockerfile. In this case the getBaseImage ref works no matter the authentication, but we need to elevate the
+	// is something we build ourselves, e.g. a Dockerfile. In this case the getBaseImage ref works no matter the authentication, but we need to elevate the
 	// auth to allow checking for its existence.
 
 	// Resolving the base image will fail if the user is trying to use an image they have no permission to use
@@ -380,13 +396,18 @@ func (b *DockerBuilder) Build(req *api.BuildRequest, resp api.ImageBuilder_Build
 			return status.Errorf(codes.InvalidArgument, "cannot pull base image: %v", err)
 		}
 	} else {
-		if req.Source.GetFile() == nil {
+		strategy, err := NewBaseImageStrategy(req.Source)
+		if err != nil {
 			return status.Errorf(codes.NotFound, "base image does not exist: %v", baseref)
 		}
 
-		basesrc := req.Source.GetFile()
-		err = b.buildBaseImage(ctx, thisBuild, basesrc, baseref, reqauth)
+		err = b.buildBaseImage(ctx, thisBuild, req.Source, strategy, baseref, reqauth)
 		if err != nil {
+			// Clients which listen in on an ongoing build don't get a debug workspace - only the one who started the build does.
+			// Debug workspaces let users iterate on a Dockerfile, which the other build strategies don't have.
+			if basesrc := req.Source.GetFile(); req.Debug != nil && basesrc != nil {
+				debugInfo = b.startDebugWorkspace(ctx, thisBuild, basesrc, req.Debug, reqauth)
+			}
 			return err
 		}
 	}
@@ -453,7 +474,7 @@ func (b *DockerBuilder) createBuildVolume(ctx context.Context, buildID string) (
 	return buildVolName, nil
 }
 
-func (b *DockerBuilder) buildBaseImage(ctx context.Context, bld *build, src *api.BuildSourceDockerfile, ref string, allowedAuth allowedAuthFor) (err error) {
+func (b *DockerBuilder) buildBaseImage(ctx context.Context, bld *build, src *api.BuildSource, strategy BaseImageStrategy, ref string, allowedAuth allowedAuthFor) (err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "buildBaseImage")
 	defer tracing.FinishSpan(span, &err)
 
@@ -499,6 +520,10 @@ func (b *DockerBuilder) buildBaseImage(ctx context.Context, bld *build, src *api
 	}
 	r, w := io.Pipe()
 	ctxsrvChan, buildChan := make(chan error), make(chan error)
+	layers := &layerTracker{Writer: bld}
+	defer func() {
+		bld.lastBaseLayer = layers.Last()
+	}()
 	go func() {
 		ctxsrvChan <- b.serveContext(ctx, bld, bld.buildVolume, "/workspace/context", w)
 		log.WithField("buildRef", bld.Ref).Debug("base image context sent")
@@ -510,6 +535,7 @@ func (b *DockerBuilder) buildBaseImage(ctx context.Context, bld *build, src *api
 			Tags:        []string{ref},
 			PullParent:  true,
 			Dockerfile:  "Dockerfile",
+			BuildArgs:   strategy.BuildArgs(b.Config),
 			AuthConfigs: buildauth,
 			Labels: map[string]string{
 				LabelTemporary: "true",
@@ -520,7 +546,7 @@ func (b *DockerBuilder) buildBaseImage(ctx context.Context, bld *build, src *api
 			return
 		}
 
-		err = jsonmessage.DisplayJSONMessagesStream(resp.Body, bld, 0, bld.isTTY, nil)
+		err = jsonmessage.DisplayJSONMessagesStream(resp.Body, layers, 0, bld.isTTY, nil)
 		resp.Body.Close()
 		buildChan <- err
 
@@ -874,6 +900,8 @@ type build struct {
 
 	buildVolume string
 	isTTY       bool
+	// lastBaseLayer is the image of the last successful step of the base image build
+	lastBaseLayer string
 
 	phase    buildPhase
 	listener listenerSet
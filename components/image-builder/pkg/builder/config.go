@@ -44,6 +44,16 @@ type Configuration struct {
 	// SelfBuildBaseImage points to an image that is used as base image.
 	// Needs to be an alpine image that has `git bash openssh-client lz4 coreutils` installed.
 	SelfBuildBaseImage string `json:"selfBuildBaseImage,omitempty"`
+
+	// NixImage is the image we run Nix flake builds in. Defaults to DefaultNixImage.
+	NixImage string `json:"nixImage,omitempty"`
+
+	// NixBaseImage is the image we add the environment built from a Nix flake to. Defaults to DefaultNixBaseImage.
+	NixBaseImage string `json:"nixBaseImage,omitempty"`
+
+	// DebugWorkspace configures the temporary workspaces we start when a debug build fails.
+	// If nil, debug builds are unavailable.
+	DebugWorkspace *DebugWorkspaceConfig `json:"debugWorkspace,omitempty"`
 }
 
 // Validate validates the configuration
@@ -60,6 +70,25 @@ func (c *Configuration) Validate() error {
 	if len(c.WorkspaceImageRepository) > 255 {
 		return xerrors.Errorf("WorkspaceImageRepository must not be longer than 255 characters")
 	}
+	if c.DebugWorkspace != nil {
+		if err := c.DebugWorkspace.Validate(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
+
+func (c *Configuration) nixImage() string {
+	if c.NixImage == "" {
+		return DefaultNixImage
+	}
+	return c.NixImage
+}
+
+func (c *Configuration) nixBaseImage() string {
+	if c.NixBaseImage == "" {
+		return DefaultNixBaseImage
+	}
+	return c.NixBaseImage
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package builder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/image-builder/api"
+	wsmanapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// debugDockerfileEnvVar tells the debug workspace which Dockerfile failed to build
+	debugDockerfileEnvVar = "GITPOD_DEBUG_BUILD_DOCKERFILE"
+
+	// debugWorkspaceLocation is used if we cannot tell where the build context is checked out to
+	debugWorkspaceLocation = "debug"
+)
+
+// DebugWorkspaceConfig configures the temporary workspaces we start when a debug build fails
+type DebugWorkspaceConfig struct {
+	// WsManagerAddr is the address of the ws-manager which starts the debug workspaces
+	WsManagerAddr string `json:"wsManagerAddr"`
+
+	// IDEImage is the IDE image debug workspaces run
+	IDEImage string `json:"ideImage"`
+
+	// Timeout is the inactivity timeout of debug workspaces, e.g. 30m. If empty, ws-manager's default timeout applies.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Validate validates the debug workspace configuration
+func (c *DebugWorkspaceConfig) Validate() error {
+	if c.WsManagerAddr == "" {
+		return xerrors.Errorf("DebugWorkspace.WsManagerAddr is mandatory")
+	}
+	if c.IDEImage == "" {
+		return xerrors.Errorf("DebugWorkspace.IDEImage is mandatory")
+	}
+	if c.Timeout != "" {
+		if _, err := time.ParseDuration(c.Timeout); err != nil {
+			return xerrors.Errorf("DebugWorkspace.Timeout is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// stepDonePattern matches the line the Docker builder prints when a build step has produced an image
+var stepDonePattern = regexp.MustCompile(`^ ---> ([0-9a-f]{12,64})\s*$`)
+
+// layerTracker forwards the output of a Docker build and remembers the image of the last step which succeeded
+type layerTracker struct {
+	io.Writer
+
+	mu   sync.Mutex
+	line []byte
+	last string
+}
+
+func (t *layerTracker) Write(p []byte) (n int, err error) {
+	t.mu.Lock()
+	t.line = append(t.line, p...)
+	for {
+		idx := bytes.IndexByte(t.line, '\n')
+		if idx < 0 {
+			break
+		}
+		if m := stepDonePattern.FindSubmatch(t.line[:idx]); m != nil {
+			t.last = string(m[1])
+		}
+		t.line = t.line[idx+1:]
+	}
+	t.mu.Unlock()
+
+	return t.Writer.Write(p)
+}
+
+// Last returns the image of the last successful build step, or an empty string if no step succeeded
+func (t *layerTracker) Last() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+// startDebugWorkspace turns the last successful layer of a failed base image build into a workspace image
+// and starts a temporary workspace from it, so that the user can iterate on the failing Dockerfile step.
+// Debug workspaces are best effort: if we cannot start one, we report why in the build log and return nil.
+func (b *DockerBuilder) startDebugWorkspace(ctx context.Context, bld *build, src *api.BuildSourceDockerfile, opts *api.BuildDebugOptions, allowedAuth allowedAuthFor) *api.BuildDebugInfo {
+	info, err := b.doStartDebugWorkspace(ctx, bld, src, opts, allowedAuth)
+	if err != nil {
+		log.WithError(err).WithField("buildRef", bld.Ref).Warn("cannot start debug workspace")
+		fmt.Fprintf(bld, "\ncannot start debug workspace: %v\n", err)
+		return nil
+	}
+
+	log.WithField("buildRef", bld.Ref).WithField("instanceId", info.InstanceId).WithField("debugRef", info.Ref).Info("started debug workspace")
+	fmt.Fprintf(bld, "\nstarted debug workspace from the last successful build step: %s\n", info.Url)
+	return info
+}
+
+func (b *DockerBuilder) doStartDebugWorkspace(ctx context.Context, bld *build, src *api.BuildSourceDockerfile, opts *api.BuildDebugOptions, allowedAuth allowedAuthFor) (info *api.BuildDebugInfo, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "startDebugWorkspace")
+	defer tracing.FinishSpan(span, &err)
+
+	if bld.lastBaseLayer == "" {
+		return nil, xerrors.Errorf("no build step succeeded")
+	}
+
+	// The build failed in the middle of the Dockerfile, hence we add the Gitpod layer to whatever the last successful step produced.
+	// We never push the intermediate base image - only the resulting workspace image needs to leave this node.
+	baseref := fmt.Sprintf("%s:debug-%s", b.Config.BaseImageRepository, bld.ID)
+	err = b.Docker.ImageTag(ctx, bld.lastBaseLayer, baseref)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot tag last successful layer: %w", err)
+	}
+	debugref := fmt.Sprintf("%s:debug-%s", b.Config.WorkspaceImageRepository, bld.ID)
+	err = b.buildWorkspaceImage(ctx, bld, baseref, []string{debugref}, allowedAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	location := debugWorkspaceLocation
+	if loc := src.Source.GetGit().GetCheckoutLocation(); loc != "" {
+		location = loc
+	}
+	instanceID := uuid.New().String()
+	resp, err := b.WorkspaceManager.StartWorkspace(ctx, &wsmanapi.StartWorkspaceRequest{
+		Id:            instanceID,
+		ServicePrefix: instanceID,
+		Metadata: &wsmanapi.WorkspaceMetadata{
+			Owner:  opts.Owner,
+			MetaId: "debug-" + bld.ID[:12],
+		},
+		Type: wsmanapi.WorkspaceType_REGULAR,
+		Spec: &wsmanapi.StartWorkspaceSpec{
+			WorkspaceImage:    debugref,
+			IdeImage:          b.Config.DebugWorkspace.IDEImage,
+			Initializer:       debugInitializer(src),
+			CheckoutLocation:  location,
+			WorkspaceLocation: location,
+			Timeout:           b.Config.DebugWorkspace.Timeout,
+			Admission:         wsmanapi.AdmissionLevel_ADMIT_OWNER_ONLY,
+			Envvars: []*wsmanapi.EnvironmentVariable{
+				{Name: debugDockerfileEnvVar, Value: src.DockerfilePath},
+			},
+		},
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot start workspace: %w", err)
+	}
+
+	return &api.BuildDebugInfo{
+		Ref:        debugref,
+		InstanceId: instanceID,
+		Url:        resp.Url,
+	}, nil
+}
+
+// debugInitializer produces the content of a debug workspace: the build context the Dockerfile came from
+func debugInitializer(src *api.BuildSourceDockerfile) *csapi.WorkspaceInitializer {
+	if src.Source == nil {
+		return &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Empty{Empty: &csapi.EmptyInitializer{}}}
+	}
+	return src.Source
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package builder
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLayerTracker(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Output      []string
+		Expectation string
+	}{
+		{
+			Name:   "no step succeeded",
+			Output: []string{"Step 1/2 : FROM doesnotexist\n", "pull access denied for doesnotexist\n"},
+		},
+		{
+			Name: "failing step",
+			Output: []string{
+				"Step 1/3 : FROM alpine:3.9\n",
+				" ---> 5cb3aa00f899\n",
+				"Step 2/3 : RUN apk add git\n",
+				" ---> Running in 8d3b3e2c9d5e\n",
+				"Removing intermediate container 8d3b3e2c9d5e\n",
+				" ---> 0e6e8f2a5c12\n",
+				"Step 3/3 : RUN exit 1\n",
+				" ---> Running in 1f2e3d4c5b6a\n",
+			},
+			Expectation: "0e6e8f2a5c12",
+		},
+		{
+			Name:        "split writes",
+			Output:      []string{"Step 1/2 : FROM alpine:3.9\n ---", "> 5cb3aa00", "f899\n", "Step 2/2 : RUN exit 1\n"},
+			Expectation: "5cb3aa00f899",
+		},
+		{
+			Name:   "cached step",
+			Output: []string{"Step 2/3 : RUN apk add git\n", " ---> Using cache\n", " ---> 0e6e8f2a5c12\n"},
+			// a cached step produced an image just as well
+			Expectation: "0e6e8f2a5c12",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			tracker := &layerTracker{Writer: &out}
+			var expectedOut string
+			for _, o := range test.Output {
+				_, err := tracker.Write([]byte(o))
+				if err != nil {
+					t.Fatal(err)
+				}
+				expectedOut += o
+			}
+
+			if act := tracker.Last(); act != test.Expectation {
+				t.Errorf("unexpected last layer: %q, expected %q", act, test.Expectation)
+			}
+			if out.String() != expectedOut {
+				t.Errorf("tracker did not forward the build output: %q", out.String())
+			}
+		})
+	}
+}
@@ -219,21 +219,14 @@ func (b *DockerBuilder) getBaseImageRef(ctx context.Context, bs *api.BuildSource
 	case *api.BuildSource_Ref:
 		return b.getAbsoluteImageRef(ctx, src.Ref.Ref, allowedAuth)
 
-	case *api.BuildSource_File:
-		manifest := map[string]string{
-			"DockerfilePath":    src.File.DockerfilePath,
-			"DockerfileVersion": src.File.DockerfileVersion,
-			"ContextPath":       src.File.ContextPath,
+	case *api.BuildSource_File, *api.BuildSource_Buildpacks, *api.BuildSource_Nix:
+		strategy, err := NewBaseImageStrategy(bs)
+		if err != nil {
+			return "", err
 		}
-		// workspace starter will only ever send us Git sources. Should that ever change, we'll need to add
-		// manifest support for the other initializer types.
-		if src.File.Source.GetGit() != nil {
-			fsrc := src.File.Source.GetGit()
-			manifest["Source"] = "git"
-			manifest["CloneTarget"] = fsrc.CloneTaget
-			manifest["RemoteURI"] = fsrc.RemoteUri
-		} else {
-			return "", xerrors.Errorf("unsupported context initializer")
+		manifest, err := strategy.Manifest(b.Config)
+		if err != nil {
+			return "", err
 		}
 		// Go maps do NOT maintain their order - we must sort the keys to maintain a stable order
 		var keys []string
@@ -0,0 +1,383 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package builder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/image-builder/api"
+)
+
+const (
+	// DefaultBuildpacksBuilder is the builder we use if .gitpod.yml does not name one
+	DefaultBuildpacksBuilder = "paketobuildpacks/builder:base"
+	// DefaultNixImage is the image we run Nix builds in
+	DefaultNixImage = "nixos/nix:2.11.1"
+	// DefaultNixBaseImage is the image we add the Nix environment to
+	DefaultNixBaseImage = "gitpod/workspace-base:latest"
+	// DefaultNixAttribute is the package of a flake we build if .gitpod.yml does not name one
+	DefaultNixAttribute = "default"
+)
+
+// buildpacksDockerfile runs the buildpacks lifecycle of the builder image. Unlike a regular buildpacks build we keep the
+// builder image rather than moving the layers to a run image, because workspaces need the build tools, too.
+// We place the application in /app because the workspace content is mounted at /workspace.
+const buildpacksDockerfile = `ARG BUILDER
+FROM ${BUILDER}
+USER root
+COPY platform /platform
+COPY app /app
+COPY buildpacks-env.sh /etc/profile.d/buildpacks-env.sh
+RUN mkdir -p /layers \
+ && chown -R "${CNB_USER_ID}:${CNB_GROUP_ID}" /app /layers
+USER ${CNB_USER_ID}:${CNB_GROUP_ID}
+RUN /cnb/lifecycle/detector -app /app -layers /layers -platform /platform \
+ && /cnb/lifecycle/builder -app /app -layers /layers -platform /platform
+USER root
+`
+
+// buildpacksEnvScript makes the layers the buildpacks contributed available in workspace shells,
+// see https://github.com/buildpacks/spec/blob/main/buildpack.md#provided-by-the-buildpacks
+const buildpacksEnvScript = `# applies the environment of the buildpack layers
+for layer in /layers/*/*/; do
+    [ -d "${layer}bin" ] && PATH="${layer}bin:${PATH}"
+    [ -d "${layer}lib" ] && LD_LIBRARY_PATH="${layer}lib${LD_LIBRARY_PATH:+:${LD_LIBRARY_PATH}}"
+    for envdir in "${layer}env" "${layer}env.build" "${layer}env.launch"; do
+        [ -d "${envdir}" ] || continue
+        for file in "${envdir}"/*; do
+            [ -f "${file}" ] || continue
+            name="$(basename "${file}")"
+            var="${name%.*}"
+            value="$(cat "${file}")"
+            delim="$(cat "${envdir}/${var}.delim" 2>/dev/null)"
+            eval "current=\"\${${var}}\""
+            case "${name}" in
+                *.delim) continue ;;
+                *.default) [ -n "${current}" ] && continue ;;
+                *.prepend) [ -n "${current}" ] && value="${value}${delim}${current}" ;;
+                *.append) [ -n "${current}" ] && value="${current}${delim}${value}" ;;
+            esac
+            export "${var}=${value}"
+        done
+    done
+    for script in "${layer}profile.d"/*; do
+        [ -f "${script}" ] && . "${script}"
+    done
+done
+export PATH LD_LIBRARY_PATH
+unset layer envdir file name var value delim current script
+`
+
+// nixDockerfile builds a package of a flake and copies its closure into the base image
+const nixDockerfile = `ARG NIX_IMAGE
+ARG BASE_IMAGE
+FROM ${NIX_IMAGE} AS nix
+ARG ATTRIBUTE
+COPY flake /flake
+RUN nix --extra-experimental-features "nix-command flakes" build "path:/flake#${ATTRIBUTE}" --out-link /result \
+ && mkdir -p /out/nix/store \
+ && cp -a $(nix-store --query --requisites /result) /out/nix/store/ \
+ && ln -s "$(readlink /result)" /out/nix/env
+
+FROM ${BASE_IMAGE}
+COPY --from=nix /out/ /
+ENV PATH=/nix/env/bin:${PATH}
+`
+
+var envVarNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// BaseImageStrategy builds base images from a kind of build source. We turn all sources into a
+// build context with a Dockerfile, so that all of them share the same build, push and caching.
+type BaseImageStrategy interface {
+	// Source produces the content the base image is built from
+	Source() *csapi.WorkspaceInitializer
+
+	// Manifest lists everything the base image depends on. Builds with the same manifest share their base image.
+	Manifest(cfg *Configuration) (map[string]string, error)
+
+	// PrepareContext assembles the build context and Dockerfile in <wd>/context from the source in <wd>/init.
+	// It runs as part of bob rather than in the image-builder itself.
+	PrepareContext(wd string) error
+
+	// BuildArgs are the build arguments the Dockerfile expects
+	BuildArgs(cfg *Configuration) map[string]*string
+}
+
+// NewBaseImageStrategy returns the strategy for building a base image from a source
+func NewBaseImageStrategy(src *api.BuildSource) (BaseImageStrategy, error) {
+	switch s := src.GetFrom().(type) {
+	case *api.BuildSource_File:
+		return &dockerfileStrategy{src: s.File}, nil
+	case *api.BuildSource_Buildpacks:
+		for k := range s.Buildpacks.Env {
+			if !envVarNameRegexp.MatchString(k) {
+				return nil, xerrors.Errorf("invalid buildpacks environment variable name: %q", k)
+			}
+		}
+		return &buildpacksStrategy{src: s.Buildpacks}, nil
+	case *api.BuildSource_Nix:
+		return &nixStrategy{src: s.Nix}, nil
+	default:
+		return nil, xerrors.Errorf("cannot build base image from this source")
+	}
+}
+
+// gitSourceManifest describes the source of a build. Workspace starter will only ever send us Git sources.
+// Should that ever change, we'll need to add manifest support for the other initializer types.
+func gitSourceManifest(manifest map[string]string, src *csapi.WorkspaceInitializer, includeRevision bool) error {
+	git := src.GetGit()
+	if git == nil {
+		return xerrors.Errorf("unsupported context initializer")
+	}
+	manifest["Source"] = "git"
+	manifest["RemoteURI"] = git.RemoteUri
+	if includeRevision {
+		manifest["CloneTarget"] = git.CloneTaget
+	}
+	return nil
+}
+
+type dockerfileStrategy struct {
+	src *api.BuildSourceDockerfile
+}
+
+func (s *dockerfileStrategy) Source() *csapi.WorkspaceInitializer {
+	return s.src.Source
+}
+
+func (s *dockerfileStrategy) Manifest(cfg *Configuration) (map[string]string, error) {
+	manifest := map[string]string{
+		"DockerfilePath":    s.src.DockerfilePath,
+		"DockerfileVersion": s.src.DockerfileVersion,
+		"ContextPath":       s.src.ContextPath,
+	}
+	err := gitSourceManifest(manifest, s.src.Source, true)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (s *dockerfileStrategy) PrepareContext(wd string) error {
+	initwd, ctxwd := filepath.Join(wd, "init"), filepath.Join(wd, "context")
+	initctx, err := contextDir(initwd, s.src.ContextPath, "Context")
+	if err != nil {
+		return err
+	}
+
+	initdf, err := securejoin.SecureJoin(initwd, s.src.DockerfilePath)
+	if err != nil {
+		return err
+	}
+	if stat, err := os.Stat(initdf); os.IsNotExist(err) {
+		return xerrors.Errorf("Dockerfile \"%s\" does not exist", s.src.DockerfilePath)
+	} else if err != nil {
+		return xerrors.Errorf("Dockerfile error: %v", err)
+	} else if stat.IsDir() {
+		return xerrors.Errorf("Dockerfile \"%s\" is a directory", s.src.DockerfilePath)
+	}
+
+	return runIn(ctxwd, [][]string{
+		{"sh", "-c", fmt.Sprintf("cp -Rf %s/. .", initctx)},
+		{"mv", "-f", initdf, "Dockerfile"},
+	})
+}
+
+func (s *dockerfileStrategy) BuildArgs(cfg *Configuration) map[string]*string {
+	return nil
+}
+
+type buildpacksStrategy struct {
+	src *api.BuildSourceBuildpacks
+}
+
+func (s *buildpacksStrategy) Source() *csapi.WorkspaceInitializer {
+	return s.src.Source
+}
+
+func (s *buildpacksStrategy) builder() string {
+	if s.src.Builder == "" {
+		return DefaultBuildpacksBuilder
+	}
+	return s.src.Builder
+}
+
+// Manifest includes the revision of the source: buildpacks derive the image from the whole application
+// and we cannot tell which of its files they looked at.
+func (s *buildpacksStrategy) Manifest(cfg *Configuration) (map[string]string, error) {
+	env := make([]string, 0, len(s.src.Env))
+	for k, v := range s.src.Env {
+		env = append(env, fmt.Sprintf("%s=%q", k, v))
+	}
+	sort.Strings(env)
+
+	manifest := map[string]string{
+		"Strategy":    "buildpacks",
+		"ContextPath": s.src.ContextPath,
+		"Builder":     s.builder(),
+		"Env":         strings.Join(env, " "),
+	}
+	err := gitSourceManifest(manifest, s.src.Source, true)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (s *buildpacksStrategy) PrepareContext(wd string) error {
+	initwd, ctxwd := filepath.Join(wd, "init"), filepath.Join(wd, "context")
+	initctx, err := contextDir(initwd, s.src.ContextPath, "Context")
+	if err != nil {
+		return err
+	}
+
+	// buildpacks read their configuration from the platform directory, one file per environment variable
+	envdir := filepath.Join(ctxwd, "platform", "env")
+	err = os.MkdirAll(envdir, 0755)
+	if err != nil {
+		return err
+	}
+	for k, v := range s.src.Env {
+		if !envVarNameRegexp.MatchString(k) {
+			return xerrors.Errorf("invalid buildpacks environment variable name: %q", k)
+		}
+		err = os.WriteFile(filepath.Join(envdir, k), []byte(v), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.WriteFile(filepath.Join(ctxwd, "buildpacks-env.sh"), []byte(buildpacksEnvScript), 0644)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(ctxwd, "Dockerfile"), []byte(buildpacksDockerfile), 0644)
+	if err != nil {
+		return err
+	}
+
+	return runIn(ctxwd, [][]string{
+		{"mkdir", "-p", "app"},
+		{"sh", "-c", fmt.Sprintf("cp -Rf %s/. app", initctx)},
+	})
+}
+
+func (s *buildpacksStrategy) BuildArgs(cfg *Configuration) map[string]*string {
+	builder := s.builder()
+	return map[string]*string{
+		"BUILDER": &builder,
+	}
+}
+
+type nixStrategy struct {
+	src *api.BuildSourceNix
+}
+
+func (s *nixStrategy) Source() *csapi.WorkspaceInitializer {
+	return s.src.Source
+}
+
+func (s *nixStrategy) attribute() string {
+	if s.src.Attribute == "" {
+		return DefaultNixAttribute
+	}
+	return s.src.Attribute
+}
+
+// Manifest does not include the revision of the source: flake.lock pins everything the flake depends on,
+// so that the image only changes if the flake does.
+func (s *nixStrategy) Manifest(cfg *Configuration) (map[string]string, error) {
+	if s.src.FlakeVersion == "" {
+		return nil, xerrors.Errorf("flake version is mandatory")
+	}
+	manifest := map[string]string{
+		"Strategy":     "nix",
+		"FlakePath":    s.src.FlakePath,
+		"FlakeVersion": s.src.FlakeVersion,
+		"Attribute":    s.attribute(),
+		"NixImage":     cfg.nixImage(),
+		"BaseImage":    cfg.nixBaseImage(),
+	}
+	err := gitSourceManifest(manifest, s.src.Source, false)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (s *nixStrategy) PrepareContext(wd string) error {
+	initwd, ctxwd := filepath.Join(wd, "init"), filepath.Join(wd, "context")
+	initflake, err := contextDir(initwd, s.src.FlakePath, "Flake")
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(initflake, "flake.nix")); os.IsNotExist(err) {
+		return xerrors.Errorf("Flake directory \"%s\" does not contain a flake.nix", s.src.FlakePath)
+	}
+
+	err = os.WriteFile(filepath.Join(ctxwd, "Dockerfile"), []byte(nixDockerfile), 0644)
+	if err != nil {
+		return err
+	}
+
+	return runIn(ctxwd, [][]string{
+		{"mkdir", "-p", "flake"},
+		{"sh", "-c", fmt.Sprintf("cp -Rf %s/. flake", initflake)},
+	})
+}
+
+func (s *nixStrategy) BuildArgs(cfg *Configuration) map[string]*string {
+	var (
+		nixImage  = cfg.nixImage()
+		baseImage = cfg.nixBaseImage()
+		attribute = s.attribute()
+	)
+	return map[string]*string{
+		"NIX_IMAGE":  &nixImage,
+		"BASE_IMAGE": &baseImage,
+		"ATTRIBUTE":  &attribute,
+	}
+}
+
+// contextDir resolves a directory of the source without leaving it
+func contextDir(initwd, path, name string) (string, error) {
+	res, err := securejoin.SecureJoin(initwd, path)
+	if err != nil {
+		return "", err
+	}
+	if stat, err := os.Stat(res); os.IsNotExist(err) {
+		return "", xerrors.Errorf("%s directory \"%s\" does not exist", name, path)
+	} else if err != nil {
+		return "", xerrors.Errorf("%s directory error: %v", name, err)
+	} else if !stat.IsDir() {
+		return "", xerrors.Errorf("%s path \"%s\" is not a directory", name, path)
+	}
+	return res, nil
+}
+
+func runIn(dir string, cmds [][]string) error {
+	for _, cmd := range cmds {
+		c := exec.Command(cmd[0], cmd[1:]...)
+		c.Dir = dir
+		c.Stdout = log.Log.WriterLevel(logrus.InfoLevel)
+		c.Stderr = log.Log.WriterLevel(logrus.ErrorLevel)
+		err := c.Run()
+		if err != nil {
+			return xerrors.Errorf("%s failed: %w", strings.Join(cmd, " "), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/image-builder/api"
+)
+
+func gitSource(cloneTarget string) *csapi.WorkspaceInitializer {
+	return &csapi.WorkspaceInitializer{
+		Spec: &csapi.WorkspaceInitializer_Git{
+			Git: &csapi.GitInitializer{
+				RemoteUri:  "https://github.com/gitpod-io/gitpod.git",
+				CloneTaget: cloneTarget,
+			},
+		},
+	}
+}
+
+func TestGetBaseImageRef(t *testing.T) {
+	b := &DockerBuilder{Config: &Configuration{BaseImageRepository: "registry/base", ImageBuildSalt: "salt"}}
+	ref := func(src *api.BuildSource) string {
+		res, err := b.getBaseImageRef(context.Background(), src, allowedAuthForNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	dockerfile := func(cloneTarget string) *api.BuildSource {
+		return &api.BuildSource{From: &api.BuildSource_File{File: &api.BuildSourceDockerfile{
+			Source:            gitSource(cloneTarget),
+			DockerfilePath:    ".gitpod.Dockerfile",
+			DockerfileVersion: "abc",
+			ContextPath:       ".",
+		}}}
+	}
+	buildpacks := func(cloneTarget string, env map[string]string) *api.BuildSource {
+		return &api.BuildSource{From: &api.BuildSource_Buildpacks{Buildpacks: &api.BuildSourceBuildpacks{
+			Source: gitSource(cloneTarget),
+			Env:    env,
+		}}}
+	}
+	nix := func(cloneTarget, version string) *api.BuildSource {
+		return &api.BuildSource{From: &api.BuildSource_Nix{Nix: &api.BuildSourceNix{
+			Source:       gitSource(cloneTarget),
+			FlakeVersion: version,
+		}}}
+	}
+
+	// Dockerfile builds must keep their refs, lest we rebuild all images we have cached
+	if r := ref(dockerfile("main")); r != "registry/base:cdae1fdf6ae985037ef2cc6245099b5271cbc966a12a1048a1099f8ed7bc2d84" {
+		t.Errorf("Dockerfile base image ref changed: %s", r)
+	}
+
+	if ref(buildpacks("main", nil)) == ref(buildpacks("feature", nil)) {
+		t.Error("buildpacks builds of different revisions share their base image")
+	}
+	if ref(buildpacks("main", map[string]string{"BP_JVM_VERSION": "11"})) == ref(buildpacks("main", map[string]string{"BP_JVM_VERSION": "17"})) {
+		t.Error("buildpacks builds with different environments share their base image")
+	}
+	if ref(buildpacks("main", nil)) == ref(dockerfile("main")) {
+		t.Error("buildpacks and Dockerfile builds share their base image")
+	}
+
+	if ref(nix("main", "abc")) != ref(nix("feature", "abc")) {
+		t.Error("Nix builds of the same flake do not share their base image")
+	}
+	if ref(nix("main", "abc")) == ref(nix("main", "def")) {
+		t.Error("Nix builds of different flakes share their base image")
+	}
+	nixref := ref(nix("main", "abc"))
+	b.Config.NixBaseImage = "gitpod/workspace-full"
+	if ref(nix("main", "abc")) == nixref {
+		t.Error("Nix builds on different base images share their base image")
+	}
+
+	_, err := b.getBaseImageRef(context.Background(), nix("main", ""), allowedAuthForNone)
+	if err == nil {
+		t.Error("expected Nix builds without flake version to fail")
+	}
+	_, err = b.getBaseImageRef(context.Background(), buildpacks("main", map[string]string{"FOO\nRUN": "bar"}), allowedAuthForNone)
+	if err == nil {
+		t.Error("expected buildpacks builds with invalid environment variable names to fail")
+	}
+}
+
+func TestPrepareContext(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Source      *api.BuildSource
+		Files       map[string]string
+		Expectation map[string]string
+		Error       bool
+	}{
+		{
+			Name: "dockerfile",
+			Source: &api.BuildSource{From: &api.BuildSource_File{File: &api.BuildSourceDockerfile{
+				DockerfilePath: ".gitpod/Dockerfile",
+				ContextPath:    "app",
+			}}},
+			Files: map[string]string{".gitpod/Dockerfile": "FROM alpine", "app/main.go": "package main"},
+			Expectation: map[string]string{
+				"Dockerfile": "FROM alpine",
+				"main.go":    "package main",
+			},
+		},
+		{
+			Name: "dockerfile outside the checkout",
+			Source: &api.BuildSource{From: &api.BuildSource_File{File: &api.BuildSourceDockerfile{
+				DockerfilePath: "../../Dockerfile",
+			}}},
+			Error: true,
+		},
+		{
+			Name: "buildpacks",
+			Source: &api.BuildSource{From: &api.BuildSource_Buildpacks{Buildpacks: &api.BuildSourceBuildpacks{
+				ContextPath: "app",
+				Env:         map[string]string{"BP_JVM_VERSION": "17"},
+			}}},
+			Files: map[string]string{"README.md": "# Hello", "app/pom.xml": "<project/>"},
+			Expectation: map[string]string{
+				"Dockerfile":                  buildpacksDockerfile,
+				"buildpacks-env.sh":           buildpacksEnvScript,
+				"platform/env/BP_JVM_VERSION": "17",
+				"app/pom.xml":                 "<project/>",
+			},
+		},
+		{
+			Name: "nix",
+			Source: &api.BuildSource{From: &api.BuildSource_Nix{Nix: &api.BuildSourceNix{
+				FlakePath: "nix",
+			}}},
+			Files: map[string]string{"README.md": "# Hello", "nix/flake.nix": "{}", "nix/flake.lock": "{}"},
+			Expectation: map[string]string{
+				"Dockerfile":       nixDockerfile,
+				"flake/flake.nix":  "{}",
+				"flake/flake.lock": "{}",
+			},
+		},
+		{
+			Name: "nix without flake",
+			Source: &api.BuildSource{From: &api.BuildSource_Nix{Nix: &api.BuildSourceNix{
+				FlakePath: ".",
+			}}},
+			Files: map[string]string{"README.md": "# Hello"},
+			Error: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			wd := t.TempDir()
+			for fn, content := range test.Files {
+				fn = filepath.Join(wd, "init", fn)
+				if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(fn, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.MkdirAll(filepath.Join(wd, "context"), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			strategy, err := NewBaseImageStrategy(test.Source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = strategy.PrepareContext(wd)
+			if test.Error {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			act := make(map[string]string)
+			ctxwd := filepath.Join(wd, "context")
+			err = filepath.Walk(ctxwd, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				act[strings.TrimPrefix(path, ctxwd+"/")] = string(content)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected build context (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
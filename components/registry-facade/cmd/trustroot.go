@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/registry-facade/pkg/registry"
+)
+
+// trustRootCmd produces a trust root for static layers
+var trustRootCmd = &cobra.Command{
+	Use:   "trust-root <layer.tar.gz|image-ref>...",
+	Short: "Produces a trust root listing the layers of the given layer files and images",
+	Long: `Produces a trust root listing the layers of the given layer files and images, for use with staticLayerTrust.
+Arguments which exist on disk are treated as gzipped layer files, all others as image references.
+
+To sign the trust root, sign the file with an ed25519 key and store the base64 encoded signature next to it, e.g.
+  openssl pkeyutl -sign -inkey key.pem -rawin -in trust-root.json | base64 -w0 > trust-root.json.sig`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		var layers []registry.TrustedLayer
+		for _, ref := range args {
+			var (
+				src registry.LayerSource
+				err error
+			)
+			if _, serr := os.Stat(ref); serr == nil {
+				src, err = registry.NewFileLayerSource(ctx, ref)
+			} else {
+				src, err = registry.NewStaticSourceFromImage(ctx, docker.NewResolver(docker.ResolverOptions{}), ref)
+			}
+			if err != nil {
+				return xerrors.Errorf("cannot source layer from %s: %w", ref, err)
+			}
+
+			ls, err := src.GetLayer(ctx, nil)
+			if err != nil {
+				return xerrors.Errorf("cannot get layer of %s: %w", ref, err)
+			}
+			for _, l := range ls {
+				layers = append(layers, registry.TrustedLayer{
+					Name:   ref,
+					Digest: l.Descriptor.Digest,
+					DiffID: l.DiffID,
+				})
+			}
+		}
+
+		root, err := registry.NewTrustRoot(layers)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(root)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trustRootCmd)
+}
@@ -104,6 +104,25 @@ func (s FileLayerSource) GetBlob(ctx context.Context, spec *api.ImageSpec, dgst
 	return src.Descriptor.MediaType, "", f, nil
 }
 
+// VerifyContent re-hashes the file backing a layer and checks that it still matches the layer's digest
+func (s FileLayerSource) VerifyContent(ctx context.Context, dgst digest.Digest) error {
+	_, _, rc, err := s.GetBlob(ctx, nil, dgst)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	verifier := dgst.Verifier()
+	_, err = io.Copy(verifier, rc)
+	if err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		return xerrors.Errorf("%w: %s", ErrTamperedLayer, dgst)
+	}
+	return nil
+}
+
 // NewFileLayerSource produces a static layer source where each file is expected to be a gzipped layer
 func NewFileLayerSource(ctx context.Context, file ...string) (FileLayerSource, error) {
 	var res FileLayerSource
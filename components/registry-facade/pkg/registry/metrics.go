@@ -48,6 +48,7 @@ type metrics struct {
 	ManifestHist          prometheus.Histogram
 	BlobCounter           prometheus.Counter
 	BlobDownloadSpeedHist prometheus.Histogram
+	LayerVerifications    *prometheus.CounterVec
 }
 
 func newMetrics(reg prometheus.Registerer, upstream bool) (*metrics, error) {
@@ -75,16 +76,25 @@ func newMetrics(reg prometheus.Registerer, upstream bool) (*metrics, error) {
 		Help:    "blob download speed in bytes per second",
 		Buckets: prometheus.ExponentialBuckets(1024*1024, 2, 10),
 	})
+	layerVerifications := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "layer_verifications_total",
+		Help: "number of verifications of injected layers against the trust root by source and result (trusted, untrusted or tampered)",
+	}, []string{"source", "result"})
 	if upstream {
 		err = reg.Register(blobDownloadSpeedHist)
 		if err != nil {
 			return nil, err
 		}
+		err = reg.Register(layerVerifications)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &metrics{
 		ManifestHist:          manifestHist,
 		BlobCounter:           blobCounter,
 		BlobDownloadSpeedHist: blobDownloadSpeedHist,
+		LayerVerifications:    layerVerifications,
 	}, nil
 }
@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gitpod-io/gitpod/common-go/imageref"
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/registry-facade/api"
 	"github.com/gitpod-io/gitpod/registry-facade/pkg/handover"
@@ -63,6 +64,12 @@ type Config struct {
 		Enabled bool   `json:"enabled"`
 		Sockets string `json:"sockets"`
 	} `json:"handover"`
+	// ImageRewrite maps the static layer images to different locations, e.g. registry mirrors in air-gapped installations.
+	// Workspace and IDE images are rewritten by ws-manager as part of the image spec.
+	ImageRewrite []imageref.RewriteRule `json:"imageRewrite,omitempty"`
+	// StaticLayerTrust verifies the static and IDE layers against a trust root every time we compose a manifest,
+	// and refuses to inject layers which aren't listed or whose content was tampered with.
+	StaticLayerTrust *TrustConfig `json:"staticLayerTrust,omitempty"`
 }
 
 // ResolverProvider provides new resolver
@@ -101,6 +108,26 @@ func NewRegistry(cfg Config, newResolver ResolverProvider, reg prometheus.Regist
 		return nil, err
 	}
 
+	imageRewriter, err := imageref.NewRewriter(cfg.ImageRewrite)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid image rewrite rules: %w", err)
+	}
+
+	var trust *TrustRoot
+	if cfg.StaticLayerTrust != nil {
+		trust, err = LoadTrustRoot(*cfg.StaticLayerTrust)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot load static layer trust root: %w", err)
+		}
+		log.WithField("layers", len(trust.Layers)).WithField("auditOnly", cfg.StaticLayerTrust.AuditOnly).Info("verifying static layers against trust root")
+	}
+	trusted := func(name string, src LayerSource) LayerSource {
+		if trust == nil {
+			return src
+		}
+		return NewTrustedLayerSource(name, src, trust, cfg.StaticLayerTrust.AuditOnly, metrics.LayerVerifications)
+	}
+
 	var layerSources []LayerSource
 
 	ideRefSource := func(s *api.ImageSpec) (ref string, err error) {
@@ -110,7 +137,7 @@ func NewRegistry(cfg Config, newResolver ResolverProvider, reg prometheus.Regist
 	if err != nil {
 		return nil, err
 	}
-	layerSources = append(layerSources, ideLayerSource)
+	layerSources = append(layerSources, trusted("ide", ideLayerSource))
 
 	log.Info("preparing static layer")
 	for _, sl := range cfg.StaticLayer {
@@ -120,13 +147,14 @@ func NewRegistry(cfg Config, newResolver ResolverProvider, reg prometheus.Regist
 			if err != nil {
 				return nil, fmt.Errorf("cannot source layer from %s: %w", sl.Ref, err)
 			}
-			layerSources = append(layerSources, src)
+			layerSources = append(layerSources, trusted(sl.Ref, src))
 		case "image":
-			src, err := NewStaticSourceFromImage(ctx, newResolver(), sl.Ref)
+			ref := imageRewriter.Rewrite(sl.Ref)
+			src, err := NewStaticSourceFromImage(ctx, newResolver(), ref)
 			if err != nil {
-				return nil, fmt.Errorf("cannot source layer from %s: %w", sl.Ref, err)
+				return nil, fmt.Errorf("cannot source layer from %s: %w", ref, err)
 			}
-			layerSources = append(layerSources, src)
+			layerSources = append(layerSources, trusted(sl.Ref, src))
 		default:
 			return nil, fmt.Errorf("unknown static layer type: %s", sl.Type)
 		}
@@ -0,0 +1,261 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/registry-facade/api"
+	"github.com/opencontainers/go-digest"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+)
+
+// TrustConfig configures the verification of the static and IDE layers registry-facade injects into workspace images
+type TrustConfig struct {
+	// TrustRoot is the path to the trust root, a JSON file listing the layers we may inject (see TrustRoot).
+	TrustRoot string `json:"trustRoot"`
+	// PublicKey is the path to a PEM encoded (PKIX) ed25519 public key. If set, the trust root must be signed
+	// with the corresponding private key. The base64 encoded signature is expected next to the trust root, in <trustRoot>.sig.
+	PublicKey string `json:"publicKey,omitempty"`
+	// AuditOnly makes registry-facade serve layers which fail verification anyway. Failures are still logged and counted.
+	AuditOnly bool `json:"auditOnly,omitempty"`
+}
+
+// TrustedLayer is a layer listed in the trust root
+type TrustedLayer struct {
+	Name   string        `json:"name,omitempty"`
+	Digest digest.Digest `json:"digest"`
+	DiffID digest.Digest `json:"diffID"`
+}
+
+// TrustRoot lists the layers registry-facade may inject into workspace images
+type TrustRoot struct {
+	Layers []TrustedLayer `json:"layers"`
+
+	diffIDs map[digest.Digest]digest.Digest
+}
+
+var (
+	// ErrUntrustedLayer is returned when a layer is not listed in the trust root
+	ErrUntrustedLayer = xerrors.Errorf("layer is not listed in the trust root")
+	// ErrTamperedLayer is returned when the content of a layer does not match its digest
+	ErrTamperedLayer = xerrors.Errorf("layer content does not match its digest")
+)
+
+const (
+	verificationTrusted   = "trusted"
+	verificationUntrusted = "untrusted"
+	verificationTampered  = "tampered"
+)
+
+// NewTrustRoot produces a trust root listing the layers
+func NewTrustRoot(layers []TrustedLayer) (*TrustRoot, error) {
+	res := &TrustRoot{
+		Layers:  layers,
+		diffIDs: make(map[digest.Digest]digest.Digest, len(layers)),
+	}
+	for _, l := range layers {
+		if err := l.Digest.Validate(); err != nil {
+			return nil, xerrors.Errorf("invalid digest for layer %s: %w", l.Name, err)
+		}
+		if err := l.DiffID.Validate(); err != nil {
+			return nil, xerrors.Errorf("invalid diffID for layer %s: %w", l.Name, err)
+		}
+		res.diffIDs[l.Digest] = l.DiffID
+	}
+	return res, nil
+}
+
+// LoadTrustRoot reads the trust root and verifies its signature if the config names a public key
+func LoadTrustRoot(cfg TrustConfig) (*TrustRoot, error) {
+	fc, err := os.ReadFile(cfg.TrustRoot)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read trust root: %w", err)
+	}
+
+	if cfg.PublicKey != "" {
+		keyfc, err := os.ReadFile(cfg.PublicKey)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read trust root public key: %w", err)
+		}
+		block, _ := pem.Decode(keyfc)
+		if block == nil {
+			return nil, xerrors.Errorf("%s does not contain a PEM block", cfg.PublicKey)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse trust root public key: %w", err)
+		}
+		pk, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, xerrors.Errorf("trust root public key is not an ed25519 key")
+		}
+
+		sigfc, err := os.ReadFile(cfg.TrustRoot + ".sig")
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read trust root signature: %w", err)
+		}
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigfc)))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot decode trust root signature: %w", err)
+		}
+		if !ed25519.Verify(pk, fc, sig) {
+			return nil, xerrors.Errorf("trust root signature is invalid")
+		}
+	}
+
+	var root TrustRoot
+	err = json.Unmarshal(fc, &root)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal trust root: %w", err)
+	}
+	return NewTrustRoot(root.Layers)
+}
+
+// Verify checks if a layer is listed in the trust root
+func (t *TrustRoot) Verify(l AddonLayer) error {
+	diffID, ok := t.diffIDs[l.Descriptor.Digest]
+	if !ok || diffID != l.DiffID {
+		return xerrors.Errorf("%w: %s", ErrUntrustedLayer, l.Descriptor.Digest)
+	}
+	return nil
+}
+
+// contentVerifier is implemented by layer sources which can verify the content of their layers
+// cheaply enough to do so every time a manifest is composed, e.g. because the layers are on local disk.
+type contentVerifier interface {
+	VerifyContent(ctx context.Context, dgst digest.Digest) error
+}
+
+// NewTrustedLayerSource verifies the layers of src against the trust root
+func NewTrustedLayerSource(name string, src LayerSource, trust *TrustRoot, auditOnly bool, verifications *prometheus.CounterVec) *TrustedLayerSource {
+	return &TrustedLayerSource{
+		Name:          name,
+		Delegate:      src,
+		Trust:         trust,
+		AuditOnly:     auditOnly,
+		verifications: verifications,
+	}
+}
+
+// TrustedLayerSource only provides layers listed in the trust root. Every time a manifest is composed it checks
+// that the delegate's layers are listed, and re-hashes their content if the delegate can do so cheaply.
+// Blobs are verified against their digest while they're served.
+type TrustedLayerSource struct {
+	Name      string
+	Delegate  LayerSource
+	Trust     *TrustRoot
+	AuditOnly bool
+
+	verifications *prometheus.CounterVec
+}
+
+// Envs returns the list of env modifiers
+func (src *TrustedLayerSource) Envs(ctx context.Context, spec *api.ImageSpec) ([]EnvModifier, error) {
+	return src.Delegate.Envs(ctx, spec)
+}
+
+// GetLayer returns the list of all layers from the delegate if they are listed in the trust root
+func (src *TrustedLayerSource) GetLayer(ctx context.Context, spec *api.ImageSpec) ([]AddonLayer, error) {
+	layers, err := src.Delegate.GetLayer(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range layers {
+		err := src.Trust.Verify(l)
+		if err == nil {
+			if cv, ok := src.Delegate.(contentVerifier); ok {
+				err = cv.VerifyContent(ctx, l.Descriptor.Digest)
+			}
+		}
+		err = src.observe(l.Descriptor.Digest, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return layers, nil
+}
+
+// HasBlob checks if a digest can be served by this blob source
+func (src *TrustedLayerSource) HasBlob(ctx context.Context, spec *api.ImageSpec, dgst digest.Digest) bool {
+	return src.Delegate.HasBlob(ctx, spec, dgst)
+}
+
+// GetBlob provides access to a blob. If a ReadCloser is returned the receiver is expected to
+// call close on it eventually. Reading the blob fails at the end if its content does not match its digest.
+func (src *TrustedLayerSource) GetBlob(ctx context.Context, spec *api.ImageSpec, dgst digest.Digest) (mediaType string, url string, data io.ReadCloser, err error) {
+	mediaType, url, data, err = src.Delegate.GetBlob(ctx, spec, dgst)
+	if err != nil || data == nil {
+		return
+	}
+	data = &verifyingReader{
+		ReadCloser: data,
+		Verifier:   dgst.Verifier(),
+		Done: func(verified bool) error {
+			if verified {
+				return nil
+			}
+			return src.observe(dgst, xerrors.Errorf("%w: %s", ErrTamperedLayer, dgst))
+		},
+	}
+	return
+}
+
+// observe counts and logs the result of a verification. It returns the error if we must not serve the layer.
+func (src *TrustedLayerSource) observe(dgst digest.Digest, err error) error {
+	result := verificationTrusted
+	if xerrors.Is(err, ErrTamperedLayer) {
+		result = verificationTampered
+	} else if err != nil {
+		result = verificationUntrusted
+	}
+	if src.verifications != nil {
+		src.verifications.WithLabelValues(src.Name, result).Inc()
+	}
+	if err == nil {
+		return nil
+	}
+
+	log.WithError(err).WithField("source", src.Name).WithField("digest", dgst).WithField("auditOnly", src.AuditOnly).Error("layer failed verification")
+	if src.AuditOnly {
+		return nil
+	}
+	return err
+}
+
+// verifyingReader calls Done once the underlying reader is exhausted. If Done returns an error,
+// the reader fails with that error instead of io.EOF.
+type verifyingReader struct {
+	io.ReadCloser
+	Verifier digest.Verifier
+	Done     func(verified bool) error
+
+	done bool
+}
+
+func (r *verifyingReader) Read(b []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(b)
+	if n > 0 {
+		_, _ = r.Verifier.Write(b[:n])
+	}
+	if err == io.EOF && !r.done {
+		r.done = true
+		if verr := r.Done(r.Verifier.Verified()); verr != nil {
+			return n, verr
+		}
+	}
+	return
+}
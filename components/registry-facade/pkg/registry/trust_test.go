@@ -0,0 +1,174 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package registry
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/xerrors"
+)
+
+func writeTestLayer(t *testing.T, fn string, content string) {
+	t.Helper()
+
+	f, err := os.Create(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := gzip.NewWriter(f)
+	_, err = w.Write([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestTrustedFileLayerSource(t *testing.T, auditOnly bool) (fn string, src *TrustedLayerSource, verifications *prometheus.CounterVec) {
+	fn = filepath.Join(t.TempDir(), "supervisor.tar.gz")
+	writeTestLayer(t, fn, "supervisor")
+	files, err := NewFileLayerSource(context.Background(), fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewTrustRoot([]TrustedLayer{{Name: "supervisor", Digest: files[0].Descriptor.Digest, DiffID: files[0].DiffID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifications = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "verifications"}, []string{"source", "result"})
+	return fn, NewTrustedLayerSource("supervisor", files, root, auditOnly, verifications), verifications
+}
+
+func TestTrustedLayerSource(t *testing.T) {
+	ctx := context.Background()
+	fn, src, verifications := newTestTrustedFileLayerSource(t, false)
+
+	layers, err := src.GetLayer(ctx, nil)
+	if err != nil {
+		t.Fatalf("trusted layer failed verification: %v", err)
+	}
+	_, _, rc, err := src.GetBlob(ctx, nil, layers[0].Descriptor.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.Copy(io.Discard, rc)
+	rc.Close()
+	if err != nil {
+		t.Errorf("serving a trusted layer failed: %v", err)
+	}
+
+	// the node's copy of the layer is modified after we started
+	writeTestLayer(t, fn, "evil supervisor")
+	_, err = src.GetLayer(ctx, nil)
+	if !xerrors.Is(err, ErrTamperedLayer) {
+		t.Errorf("expected tampered layer to fail verification, got %v", err)
+	}
+	_, _, rc, err = src.GetBlob(ctx, nil, layers[0].Descriptor.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.Copy(io.Discard, rc)
+	rc.Close()
+	if !xerrors.Is(err, ErrTamperedLayer) {
+		t.Errorf("expected serving a tampered layer to fail, got %v", err)
+	}
+
+	if c := testutil.ToFloat64(verifications.WithLabelValues("supervisor", verificationTrusted)); c != 1 {
+		t.Errorf("expected one trusted verification, got %v", c)
+	}
+	if c := testutil.ToFloat64(verifications.WithLabelValues("supervisor", verificationTampered)); c != 2 {
+		t.Errorf("expected two tampered verifications, got %v", c)
+	}
+}
+
+func TestTrustedLayerSourceUntrusted(t *testing.T) {
+	_, src, verifications := newTestTrustedFileLayerSource(t, false)
+	src.Trust, _ = NewTrustRoot(nil)
+
+	_, err := src.GetLayer(context.Background(), nil)
+	if !xerrors.Is(err, ErrUntrustedLayer) {
+		t.Errorf("expected layer missing from the trust root to fail verification, got %v", err)
+	}
+	if c := testutil.ToFloat64(verifications.WithLabelValues("supervisor", verificationUntrusted)); c != 1 {
+		t.Errorf("expected one untrusted verification, got %v", c)
+	}
+}
+
+func TestTrustedLayerSourceAuditOnly(t *testing.T) {
+	fn, src, verifications := newTestTrustedFileLayerSource(t, true)
+
+	writeTestLayer(t, fn, "evil supervisor")
+	_, err := src.GetLayer(context.Background(), nil)
+	if err != nil {
+		t.Errorf("expected tampered layer to be served in audit mode, got %v", err)
+	}
+	if c := testutil.ToFloat64(verifications.WithLabelValues("supervisor", verificationTampered)); c != 1 {
+		t.Errorf("expected the tampered layer to be counted in audit mode, got %v", c)
+	}
+}
+
+func TestLoadTrustRoot(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := t.TempDir()
+	cfg := TrustConfig{
+		TrustRoot: filepath.Join(loc, "trust-root.json"),
+		PublicKey: filepath.Join(loc, "key.pem"),
+	}
+	root, err := json.Marshal(TrustRoot{Layers: []TrustedLayer{{
+		Name:   "supervisor",
+		Digest: "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		DiffID: "sha256:fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9",
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(fn string, content []byte) {
+		t.Helper()
+		if err := os.WriteFile(fn, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(cfg.PublicKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	writeFile(cfg.TrustRoot, root)
+	writeFile(cfg.TrustRoot+".sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, root))+"\n"))
+
+	trust, err := LoadTrustRoot(cfg)
+	if err != nil {
+		t.Fatalf("cannot load signed trust root: %v", err)
+	}
+	if len(trust.Layers) != 1 {
+		t.Errorf("expected one trusted layer, got %v", trust.Layers)
+	}
+
+	writeFile(cfg.TrustRoot, append(root, ' '))
+	_, err = LoadTrustRoot(cfg)
+	if err == nil {
+		t.Errorf("loaded a trust root which does not match its signature")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package client is the Go client of the supervisor API. IDE integrations and tools which run in a workspace
+// should use it rather than dialing supervisor and creating the service clients themselves.
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// DefaultAddr is the address supervisor serves its API on in a workspace
+	DefaultAddr = "localhost:22999"
+	// AddrEnvVar is the environment variable which overrides the address of supervisor
+	AddrEnvVar = "SUPERVISOR_ADDR"
+
+	// waitRetryInterval is the time we wait before asking again whether the IDE or content is ready
+	waitRetryInterval = time.Second
+)
+
+// Addr returns the address of supervisor, i.e. $SUPERVISOR_ADDR or DefaultAddr
+func Addr() string {
+	if addr := os.Getenv(AddrEnvVar); addr != "" {
+		return addr
+	}
+	return DefaultAddr
+}
+
+// Client talks to supervisor. All service clients share one connection.
+type Client struct {
+	conn *grpc.ClientConn
+
+	Status    api.StatusServiceClient
+	Info      api.InfoServiceClient
+	Terminal  api.TerminalServiceClient
+	Token     api.TokenServiceClient
+	Control   api.ControlServiceClient
+	Frontend  api.FrontendServiceClient
+	Recording api.RecordingServiceClient
+}
+
+type options struct {
+	Addr        string
+	DialOptions []grpc.DialOption
+}
+
+// Option configures a client
+type Option func(*options)
+
+// WithAddr connects to supervisor at addr instead of Addr()
+func WithAddr(addr string) Option {
+	return func(o *options) {
+		o.Addr = addr
+	}
+}
+
+// WithDialOptions adds gRPC dial options, e.g. interceptors
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.DialOptions = append(o.DialOptions, opts...)
+	}
+}
+
+// New connects to supervisor. It blocks until the connection is up or ctx is done.
+func New(ctx context.Context, opts ...Option) (*Client, error) {
+	cfg := options{Addr: Addr()}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	dialOpts := append([]grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock()}, cfg.DialOptions...)
+	conn, err := grpc.DialContext(ctx, cfg.Addr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to supervisor at %s: %w", cfg.Addr, err)
+	}
+	return NewFromConn(conn), nil
+}
+
+// NewFromConn creates a client which uses an existing connection. Closing the client closes the connection.
+func NewFromConn(conn *grpc.ClientConn) *Client {
+	return &Client{
+		conn:      conn,
+		Status:    api.NewStatusServiceClient(conn),
+		Info:      api.NewInfoServiceClient(conn),
+		Terminal:  api.NewTerminalServiceClient(conn),
+		Token:     api.NewTokenServiceClient(conn),
+		Control:   api.NewControlServiceClient(conn),
+		Frontend:  api.NewFrontendServiceClient(conn),
+		Recording: api.NewRecordingServiceClient(conn),
+	}
+}
+
+// Close closes the connection to supervisor
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// WaitForIDE returns once the IDE of the workspace is ready, or with an error once ctx is done
+func (c *Client) WaitForIDE(ctx context.Context) error {
+	return waitFor(ctx, func(ctx context.Context) (bool, error) {
+		resp, err := c.Status.IDEStatus(ctx, &api.IDEStatusRequest{Wait: true})
+		if err != nil {
+			return false, err
+		}
+		return resp.Ok, nil
+	})
+}
+
+// WaitForContent returns once the workspace content is available, or with an error once ctx is done
+func (c *Client) WaitForContent(ctx context.Context) (*api.ContentStatusResponse, error) {
+	var res *api.ContentStatusResponse
+	err := waitFor(ctx, func(ctx context.Context) (bool, error) {
+		resp, err := c.Status.ContentStatus(ctx, &api.ContentStatusRequest{Wait: true})
+		if err != nil {
+			return false, err
+		}
+		res = resp
+		return resp.Available, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// waitFor asks until ready returns true. Supervisor answers waiting requests once it times out,
+// so we ask again until ctx is done. Errors end the wait.
+func waitFor(ctx context.Context, ready func(ctx context.Context) (bool, error)) error {
+	for {
+		ok, err := ready(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitRetryInterval):
+		}
+	}
+}
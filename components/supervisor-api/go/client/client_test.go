@@ -0,0 +1,89 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package client
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+type fakeStatusService struct {
+	api.UnimplementedStatusServiceServer
+
+	// ReadyAfter is the number of IDEStatus calls after which the IDE is ready
+	ReadyAfter int32
+	calls      int32
+}
+
+func (s *fakeStatusService) IDEStatus(ctx context.Context, req *api.IDEStatusRequest) (*api.IDEStatusResponse, error) {
+	if !req.Wait {
+		return nil, status.Error(codes.InvalidArgument, "expected a waiting request")
+	}
+	return &api.IDEStatusResponse{Ok: atomic.AddInt32(&s.calls, 1) >= s.ReadyAfter}, nil
+}
+
+func newTestClient(t *testing.T, srv api.StatusServiceServer) *Client {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	api.RegisterStatusServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := New(ctx, WithAddr("bufnet"), WithDialOptions(grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestAddr(t *testing.T) {
+	defer os.Setenv(AddrEnvVar, os.Getenv(AddrEnvVar))
+
+	os.Unsetenv(AddrEnvVar)
+	if act := Addr(); act != DefaultAddr {
+		t.Errorf("Addr() = %s, expected %s", act, DefaultAddr)
+	}
+	os.Setenv(AddrEnvVar, "localhost:1234")
+	if act := Addr(); act != "localhost:1234" {
+		t.Errorf("Addr() = %s, expected the address from the environment", act)
+	}
+}
+
+func TestWaitForIDE(t *testing.T) {
+	srv := &fakeStatusService{ReadyAfter: 2}
+	c := newTestClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := c.WaitForIDE(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := atomic.LoadInt32(&srv.calls); calls != 2 {
+		t.Errorf("expected two calls, got %d", calls)
+	}
+
+	// errors other than "not ready yet" end the wait
+	_, err = c.WaitForContent(ctx)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented, got %v", err)
+	}
+}
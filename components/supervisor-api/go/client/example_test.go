@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/api/client"
+)
+
+func Example() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := client.New(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	info, err := c.Info.WorkspaceInfo(ctx, &api.WorkspaceInfoRequest{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(info.WorkspaceId)
+}
+
+func ExampleClient_WaitForIDE() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	c, err := client.New(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	err = c.WaitForIDE(ctx)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("IDE is ready")
+}
+
+func ExampleClient_terminals() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := client.New(ctx, client.WithAddr("localhost:22999"))
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	resp, err := c.Terminal.List(ctx, &api.ListTerminalsRequest{})
+	if err != nil {
+		panic(err)
+	}
+	for _, term := range resp.Terminals {
+		fmt.Println(term.Alias, term.Command)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package api
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const compatGoldenFile = "testdata/api.golden"
+
+var updateCompat = flag.Bool("update", false, "update the API compatibility golden file")
+
+// TestCompatibility makes sure the supervisor API (v1) only ever changes in a backwards compatible way.
+// Every service, method, message, field and enum value of the golden file must still exist as it was,
+// because IDE integrations built against an older version of the API call it. Additions are fine but
+// need the golden file updated with: go test -run TestCompatibility -update
+func TestCompatibility(t *testing.T) {
+	act := describeAPI()
+	if *updateCompat {
+		err := ioutil.WriteFile(compatGoldenFile, []byte(strings.Join(act, "\n")+"\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	fc, err := ioutil.ReadFile(compatGoldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := strings.Split(strings.TrimSpace(string(fc)), "\n")
+
+	present := make(map[string]struct{}, len(act))
+	for _, l := range act {
+		present[l] = struct{}{}
+	}
+	known := make(map[string]struct{}, len(exp))
+	for _, l := range exp {
+		known[l] = struct{}{}
+		if _, ok := present[l]; !ok {
+			t.Errorf("breaking change: %s was removed or changed", l)
+		}
+	}
+	for _, l := range act {
+		if _, ok := known[l]; !ok {
+			t.Errorf("%s is new - run go test -run TestCompatibility -update to add it to %s", l, compatGoldenFile)
+		}
+	}
+}
+
+// describeAPI lists every part of the API clients depend on, one per line
+func describeAPI() []string {
+	var res []string
+	protoregistry.GlobalFiles.RangeFilesByPackage("supervisor", func(f protoreflect.FileDescriptor) bool {
+		for i := 0; i < f.Services().Len(); i++ {
+			svc := f.Services().Get(i)
+			res = append(res, fmt.Sprintf("service %s", svc.FullName()))
+			for j := 0; j < svc.Methods().Len(); j++ {
+				m := svc.Methods().Get(j)
+				res = append(res, fmt.Sprintf("rpc %s(%s%s) returns (%s%s)", m.FullName(), streaming(m.IsStreamingClient()), m.Input().FullName(), streaming(m.IsStreamingServer()), m.Output().FullName()))
+			}
+		}
+		res = append(res, describeMessages(f.Messages())...)
+		res = append(res, describeEnums(f.Enums())...)
+		return true
+	})
+	sort.Strings(res)
+	return res
+}
+
+func describeMessages(msgs protoreflect.MessageDescriptors) []string {
+	var res []string
+	for i := 0; i < msgs.Len(); i++ {
+		msg := msgs.Get(i)
+		if msg.IsMapEntry() {
+			continue
+		}
+		res = append(res, fmt.Sprintf("message %s", msg.FullName()))
+		for j := 0; j < msg.Fields().Len(); j++ {
+			res = append(res, describeField(msg.Fields().Get(j)))
+		}
+		res = append(res, describeMessages(msg.Messages())...)
+		res = append(res, describeEnums(msg.Enums())...)
+	}
+	return res
+}
+
+func describeField(f protoreflect.FieldDescriptor) string {
+	var tpe string
+	switch {
+	case f.IsMap():
+		tpe = fmt.Sprintf("map<%s, %s>", fieldType(f.MapKey()), fieldType(f.MapValue()))
+	case f.Cardinality() == protoreflect.Repeated:
+		tpe = "repeated " + fieldType(f)
+	default:
+		tpe = fieldType(f)
+	}
+	res := fmt.Sprintf("field %s = %d %s", f.FullName(), f.Number(), tpe)
+	if oneof := f.ContainingOneof(); oneof != nil {
+		res += " oneof " + string(oneof.Name())
+	}
+	return res
+}
+
+func fieldType(f protoreflect.FieldDescriptor) string {
+	switch f.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return string(f.Message().FullName())
+	case protoreflect.EnumKind:
+		return string(f.Enum().FullName())
+	default:
+		return f.Kind().String()
+	}
+}
+
+func describeEnums(enums protoreflect.EnumDescriptors) []string {
+	var res []string
+	for i := 0; i < enums.Len(); i++ {
+		enum := enums.Get(i)
+		res = append(res, fmt.Sprintf("enum %s", enum.FullName()))
+		for j := 0; j < enum.Values().Len(); j++ {
+			v := enum.Values().Get(j)
+			res = append(res, fmt.Sprintf("value %s = %d", v.FullName(), v.Number()))
+		}
+	}
+	return res
+}
+
+func streaming(s bool) string {
+	if s {
+		return "stream "
+	}
+	return ""
+}
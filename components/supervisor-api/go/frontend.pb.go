@@ -0,0 +1,1009 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        v3.7.1
+// source: frontend.proto
+
+package api
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type RegisterFrontendRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name identifies the frontend, e.g. neovide
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// version is the version of the frontend
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// passive frontends do not become the active frontend, e.g. companion tools which only observe the workspace
+	Passive bool `protobuf:"varint,3,opt,name=passive,proto3" json:"passive,omitempty"`
+}
+
+func (x *RegisterFrontendRequest) Reset() {
+	*x = RegisterFrontendRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterFrontendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterFrontendRequest) ProtoMessage() {}
+
+func (x *RegisterFrontendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterFrontendRequest.ProtoReflect.Descriptor instead.
+func (*RegisterFrontendRequest) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterFrontendRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterFrontendRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RegisterFrontendRequest) GetPassive() bool {
+	if x != nil {
+		return x.Passive
+	}
+	return false
+}
+
+type FrontendUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Update:
+	//	*FrontendUpdate_Context
+	//	*FrontendUpdate_Ports
+	//	*FrontendUpdate_Tasks
+	//	*FrontendUpdate_Activation
+	//	*FrontendUpdate_ScheduledStop
+	Update isFrontendUpdate_Update `protobuf_oneof:"update"`
+}
+
+func (x *FrontendUpdate) Reset() {
+	*x = FrontendUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FrontendUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrontendUpdate) ProtoMessage() {}
+
+func (x *FrontendUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FrontendUpdate.ProtoReflect.Descriptor instead.
+func (*FrontendUpdate) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *FrontendUpdate) GetUpdate() isFrontendUpdate_Update {
+	if m != nil {
+		return m.Update
+	}
+	return nil
+}
+
+func (x *FrontendUpdate) GetContext() *FrontendContext {
+	if x, ok := x.GetUpdate().(*FrontendUpdate_Context); ok {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *FrontendUpdate) GetPorts() *PortsStatusResponse {
+	if x, ok := x.GetUpdate().(*FrontendUpdate_Ports); ok {
+		return x.Ports
+	}
+	return nil
+}
+
+func (x *FrontendUpdate) GetTasks() *TasksStatusResponse {
+	if x, ok := x.GetUpdate().(*FrontendUpdate_Tasks); ok {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *FrontendUpdate) GetActivation() *FrontendActivation {
+	if x, ok := x.GetUpdate().(*FrontendUpdate_Activation); ok {
+		return x.Activation
+	}
+	return nil
+}
+
+func (x *FrontendUpdate) GetScheduledStop() *ScheduledStop {
+	if x, ok := x.GetUpdate().(*FrontendUpdate_ScheduledStop); ok {
+		return x.ScheduledStop
+	}
+	return nil
+}
+
+type isFrontendUpdate_Update interface {
+	isFrontendUpdate_Update()
+}
+
+type FrontendUpdate_Context struct {
+	// context is the first update a frontend receives
+	Context *FrontendContext `protobuf:"bytes,1,opt,name=context,proto3,oneof"`
+}
+
+type FrontendUpdate_Ports struct {
+	// ports is the current state of all ports
+	Ports *PortsStatusResponse `protobuf:"bytes,2,opt,name=ports,proto3,oneof"`
+}
+
+type FrontendUpdate_Tasks struct {
+	// tasks is the current state of all tasks
+	Tasks *TasksStatusResponse `protobuf:"bytes,3,opt,name=tasks,proto3,oneof"`
+}
+
+type FrontendUpdate_Activation struct {
+	// activation is sent whenever the active frontend changes
+	Activation *FrontendActivation `protobuf:"bytes,4,opt,name=activation,proto3,oneof"`
+}
+
+type FrontendUpdate_ScheduledStop struct {
+	// scheduled_stop is sent when ws-manager schedules the workspace to stop, when the scheduled stop
+	// changes, and when the stop comes close
+	ScheduledStop *ScheduledStop `protobuf:"bytes,5,opt,name=scheduled_stop,json=scheduledStop,proto3,oneof"`
+}
+
+func (*FrontendUpdate_Context) isFrontendUpdate_Update() {}
+
+func (*FrontendUpdate_Ports) isFrontendUpdate_Update() {}
+
+func (*FrontendUpdate_Tasks) isFrontendUpdate_Update() {}
+
+func (*FrontendUpdate_Activation) isFrontendUpdate_Update() {}
+
+func (*FrontendUpdate_ScheduledStop) isFrontendUpdate_Update() {}
+
+type FrontendContext struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id identifies this registration
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// workspace describes the workspace, including how to reach the Gitpod API. Frontends ask the TokenService for
+	// a token to access it.
+	Workspace *WorkspaceInfoResponse `protobuf:"bytes,2,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	// supervisor_addr is the address of the supervisor API within the workspace
+	SupervisorAddr string `protobuf:"bytes,3,opt,name=supervisor_addr,json=supervisorAddr,proto3" json:"supervisor_addr,omitempty"`
+}
+
+func (x *FrontendContext) Reset() {
+	*x = FrontendContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FrontendContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrontendContext) ProtoMessage() {}
+
+func (x *FrontendContext) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FrontendContext.ProtoReflect.Descriptor instead.
+func (*FrontendContext) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FrontendContext) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FrontendContext) GetWorkspace() *WorkspaceInfoResponse {
+	if x != nil {
+		return x.Workspace
+	}
+	return nil
+}
+
+func (x *FrontendContext) GetSupervisorAddr() string {
+	if x != nil {
+		return x.SupervisorAddr
+	}
+	return ""
+}
+
+type FrontendActivation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// active is true if the receiving frontend is the active one
+	Active bool `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	// active_frontend is the active frontend, if there is one
+	ActiveFrontend *FrontendInfo `protobuf:"bytes,2,opt,name=active_frontend,json=activeFrontend,proto3" json:"active_frontend,omitempty"`
+}
+
+func (x *FrontendActivation) Reset() {
+	*x = FrontendActivation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FrontendActivation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrontendActivation) ProtoMessage() {}
+
+func (x *FrontendActivation) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FrontendActivation.ProtoReflect.Descriptor instead.
+func (*FrontendActivation) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FrontendActivation) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *FrontendActivation) GetActiveFrontend() *FrontendInfo {
+	if x != nil {
+		return x.ActiveFrontend
+	}
+	return nil
+}
+
+type ScheduledStop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// deadline is the time the workspace stops at in RFC 3339 format. Empty if there is no scheduled stop anymore.
+	Deadline string `protobuf:"bytes,1,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// remaining_seconds is the time left until the workspace stops
+	RemainingSeconds uint32 `protobuf:"varint,2,opt,name=remaining_seconds,json=remainingSeconds,proto3" json:"remaining_seconds,omitempty"`
+	// cancelled is true if the user cancelled the scheduled stop
+	Cancelled bool `protobuf:"varint,3,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+}
+
+func (x *ScheduledStop) Reset() {
+	*x = ScheduledStop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScheduledStop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledStop) ProtoMessage() {}
+
+func (x *ScheduledStop) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledStop.ProtoReflect.Descriptor instead.
+func (*ScheduledStop) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ScheduledStop) GetDeadline() string {
+	if x != nil {
+		return x.Deadline
+	}
+	return ""
+}
+
+func (x *ScheduledStop) GetRemainingSeconds() uint32 {
+	if x != nil {
+		return x.RemainingSeconds
+	}
+	return 0
+}
+
+func (x *ScheduledStop) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+type FrontendInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Passive bool   `protobuf:"varint,4,opt,name=passive,proto3" json:"passive,omitempty"`
+	Active  bool   `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`
+	// connected_since is the time the frontend registered at in RFC 3339 format
+	ConnectedSince string `protobuf:"bytes,6,opt,name=connected_since,json=connectedSince,proto3" json:"connected_since,omitempty"`
+}
+
+func (x *FrontendInfo) Reset() {
+	*x = FrontendInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FrontendInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrontendInfo) ProtoMessage() {}
+
+func (x *FrontendInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FrontendInfo.ProtoReflect.Descriptor instead.
+func (*FrontendInfo) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FrontendInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FrontendInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FrontendInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *FrontendInfo) GetPassive() bool {
+	if x != nil {
+		return x.Passive
+	}
+	return false
+}
+
+func (x *FrontendInfo) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *FrontendInfo) GetConnectedSince() string {
+	if x != nil {
+		return x.ConnectedSince
+	}
+	return ""
+}
+
+type ListFrontendsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListFrontendsRequest) Reset() {
+	*x = ListFrontendsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFrontendsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFrontendsRequest) ProtoMessage() {}
+
+func (x *ListFrontendsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFrontendsRequest.ProtoReflect.Descriptor instead.
+func (*ListFrontendsRequest) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{6}
+}
+
+type ListFrontendsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Frontends []*FrontendInfo `protobuf:"bytes,1,rep,name=frontends,proto3" json:"frontends,omitempty"`
+}
+
+func (x *ListFrontendsResponse) Reset() {
+	*x = ListFrontendsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_frontend_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFrontendsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFrontendsResponse) ProtoMessage() {}
+
+func (x *ListFrontendsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_frontend_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFrontendsResponse.ProtoReflect.Descriptor instead.
+func (*ListFrontendsResponse) Descriptor() ([]byte, []int) {
+	return file_frontend_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListFrontendsResponse) GetFrontends() []*FrontendInfo {
+	if x != nil {
+		return x.Frontends
+	}
+	return nil
+}
+
+var File_frontend_proto protoreflect.FileDescriptor
+
+var file_frontend_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x66, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0a, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x1a, 0x0a, 0x69, 0x6e,
+	0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x61, 0x0a, 0x17, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x22, 0xcb, 0x02, 0x0a, 0x0e, 0x46, 0x72,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x46, 0x72, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x37, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f,
+	0x72, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x37,
+	0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x73,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00,
+	0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x40, 0x0a, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x73, 0x75,
+	0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x64, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x0e, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x6f, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x53,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x53, 0x74, 0x6f, 0x70, 0x48, 0x00, 0x52, 0x0d,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x53, 0x74, 0x6f, 0x70, 0x42, 0x08, 0x0a,
+	0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x22, 0x8b, 0x01, 0x0a, 0x0f, 0x46, 0x72, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3f, 0x0a, 0x09, 0x77,
+	0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x27, 0x0a, 0x0f,
+	0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f,
+	0x72, 0x41, 0x64, 0x64, 0x72, 0x22, 0x6f, 0x0a, 0x12, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x64, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x12, 0x41, 0x0a, 0x0f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x72,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73,
+	0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0e, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x46, 0x72,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x22, 0x76, 0x0a, 0x0d, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x64, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67,
+	0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10,
+	0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x22, 0xa7,
+	0x01, 0x0a, 0x0c, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12,
+	0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x6e,
+	0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74,
+	0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x4f, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x09, 0x66, 0x72, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73,
+	0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x09, 0x66, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64,
+	0x73, 0x32, 0xc2, 0x01, 0x0a, 0x0f, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x57, 0x0a, 0x10, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x12, 0x23, 0x2e, 0x73, 0x75, 0x70, 0x65,
+	0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x46,
+	0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x46, 0x72, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x56,
+	0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x73, 0x12,
+	0x20, 0x2e, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x21, 0x2e, 0x73, 0x75, 0x70, 0x65, 0x72, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x46, 0x72, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x07, 0x5a, 0x05, 0x2e, 0x3b, 0x61, 0x70, 0x69, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_frontend_proto_rawDescOnce sync.Once
+	file_frontend_proto_rawDescData = file_frontend_proto_rawDesc
+)
+
+func file_frontend_proto_rawDescGZIP() []byte {
+	file_frontend_proto_rawDescOnce.Do(func() {
+		file_frontend_proto_rawDescData = protoimpl.X.CompressGZIP(file_frontend_proto_rawDescData)
+	})
+	return file_frontend_proto_rawDescData
+}
+
+var file_frontend_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_frontend_proto_goTypes = []interface{}{
+	(*RegisterFrontendRequest)(nil), // 0: supervisor.RegisterFrontendRequest
+	(*FrontendUpdate)(nil),          // 1: supervisor.FrontendUpdate
+	(*FrontendContext)(nil),         // 2: supervisor.FrontendContext
+	(*FrontendActivation)(nil),      // 3: supervisor.FrontendActivation
+	(*ScheduledStop)(nil),           // 4: supervisor.ScheduledStop
+	(*FrontendInfo)(nil),            // 5: supervisor.FrontendInfo
+	(*ListFrontendsRequest)(nil),    // 6: supervisor.ListFrontendsRequest
+	(*ListFrontendsResponse)(nil),   // 7: supervisor.ListFrontendsResponse
+	(*PortsStatusResponse)(nil),     // 8: supervisor.PortsStatusResponse
+	(*TasksStatusResponse)(nil),     // 9: supervisor.TasksStatusResponse
+	(*WorkspaceInfoResponse)(nil),   // 10: supervisor.WorkspaceInfoResponse
+}
+var file_frontend_proto_depIdxs = []int32{
+	2,  // 0: supervisor.FrontendUpdate.context:type_name -> supervisor.FrontendContext
+	8,  // 1: supervisor.FrontendUpdate.ports:type_name -> supervisor.PortsStatusResponse
+	9,  // 2: supervisor.FrontendUpdate.tasks:type_name -> supervisor.TasksStatusResponse
+	3,  // 3: supervisor.FrontendUpdate.activation:type_name -> supervisor.FrontendActivation
+	4,  // 4: supervisor.FrontendUpdate.scheduled_stop:type_name -> supervisor.ScheduledStop
+	10, // 5: supervisor.FrontendContext.workspace:type_name -> supervisor.WorkspaceInfoResponse
+	5,  // 6: supervisor.FrontendActivation.active_frontend:type_name -> supervisor.FrontendInfo
+	5,  // 7: supervisor.ListFrontendsResponse.frontends:type_name -> supervisor.FrontendInfo
+	0,  // 8: supervisor.FrontendService.RegisterFrontend:input_type -> supervisor.RegisterFrontendRequest
+	6,  // 9: supervisor.FrontendService.ListFrontends:input_type -> supervisor.ListFrontendsRequest
+	1,  // 10: supervisor.FrontendService.RegisterFrontend:output_type -> supervisor.FrontendUpdate
+	7,  // 11: supervisor.FrontendService.ListFrontends:output_type -> supervisor.ListFrontendsResponse
+	10, // [10:12] is the sub-list for method output_type
+	8,  // [8:10] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_frontend_proto_init() }
+func file_frontend_proto_init() {
+	if File_frontend_proto != nil {
+		return
+	}
+	file_info_proto_init()
+	file_status_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_frontend_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterFrontendRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frontend_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FrontendUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frontend_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FrontendContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frontend_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FrontendActivation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frontend_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScheduledStop); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frontend_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FrontendInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frontend_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFrontendsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_frontend_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFrontendsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_frontend_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*FrontendUpdate_Context)(nil),
+		(*FrontendUpdate_Ports)(nil),
+		(*FrontendUpdate_Tasks)(nil),
+		(*FrontendUpdate_Activation)(nil),
+		(*FrontendUpdate_ScheduledStop)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_frontend_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_frontend_proto_goTypes,
+		DependencyIndexes: file_frontend_proto_depIdxs,
+		MessageInfos:      file_frontend_proto_msgTypes,
+	}.Build()
+	File_frontend_proto = out.File
+	file_frontend_proto_rawDesc = nil
+	file_frontend_proto_goTypes = nil
+	file_frontend_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// FrontendServiceClient is the client API for FrontendService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FrontendServiceClient interface {
+	// RegisterFrontend registers a frontend and streams updates to it until the frontend disconnects.
+	// The first update is the frontend context. The most recently registered frontend is the active one.
+	RegisterFrontend(ctx context.Context, in *RegisterFrontendRequest, opts ...grpc.CallOption) (FrontendService_RegisterFrontendClient, error)
+	// ListFrontends lists the frontends which are connected.
+	ListFrontends(ctx context.Context, in *ListFrontendsRequest, opts ...grpc.CallOption) (*ListFrontendsResponse, error)
+}
+
+type frontendServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFrontendServiceClient(cc grpc.ClientConnInterface) FrontendServiceClient {
+	return &frontendServiceClient{cc}
+}
+
+func (c *frontendServiceClient) RegisterFrontend(ctx context.Context, in *RegisterFrontendRequest, opts ...grpc.CallOption) (FrontendService_RegisterFrontendClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FrontendService_serviceDesc.Streams[0], "/supervisor.FrontendService/RegisterFrontend", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &frontendServiceRegisterFrontendClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FrontendService_RegisterFrontendClient interface {
+	Recv() (*FrontendUpdate, error)
+	grpc.ClientStream
+}
+
+type frontendServiceRegisterFrontendClient struct {
+	grpc.ClientStream
+}
+
+func (x *frontendServiceRegisterFrontendClient) Recv() (*FrontendUpdate, error) {
+	m := new(FrontendUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *frontendServiceClient) ListFrontends(ctx context.Context, in *ListFrontendsRequest, opts ...grpc.CallOption) (*ListFrontendsResponse, error) {
+	out := new(ListFrontendsResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.FrontendService/ListFrontends", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FrontendServiceServer is the server API for FrontendService service.
+type FrontendServiceServer interface {
+	// RegisterFrontend registers a frontend and streams updates to it until the frontend disconnects.
+	// The first update is the frontend context. The most recently registered frontend is the active one.
+	RegisterFrontend(*RegisterFrontendRequest, FrontendService_RegisterFrontendServer) error
+	// ListFrontends lists the frontends which are connected.
+	ListFrontends(context.Context, *ListFrontendsRequest) (*ListFrontendsResponse, error)
+}
+
+// UnimplementedFrontendServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedFrontendServiceServer struct {
+}
+
+func (*UnimplementedFrontendServiceServer) RegisterFrontend(*RegisterFrontendRequest, FrontendService_RegisterFrontendServer) error {
+	return status.Errorf(codes.Unimplemented, "method RegisterFrontend not implemented")
+}
+func (*UnimplementedFrontendServiceServer) ListFrontends(context.Context, *ListFrontendsRequest) (*ListFrontendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFrontends not implemented")
+}
+
+func RegisterFrontendServiceServer(s *grpc.Server, srv FrontendServiceServer) {
+	s.RegisterService(&_FrontendService_serviceDesc, srv)
+}
+
+func _FrontendService_RegisterFrontend_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RegisterFrontendRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FrontendServiceServer).RegisterFrontend(m, &frontendServiceRegisterFrontendServer{stream})
+}
+
+type FrontendService_RegisterFrontendServer interface {
+	Send(*FrontendUpdate) error
+	grpc.ServerStream
+}
+
+type frontendServiceRegisterFrontendServer struct {
+	grpc.ServerStream
+}
+
+func (x *frontendServiceRegisterFrontendServer) Send(m *FrontendUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FrontendService_ListFrontends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFrontendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).ListFrontends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.FrontendService/ListFrontends",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).ListFrontends(ctx, req.(*ListFrontendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _FrontendService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "supervisor.FrontendService",
+	HandlerType: (*FrontendServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListFrontends",
+			Handler:    _FrontendService_ListFrontends_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RegisterFrontend",
+			Handler:       _FrontendService_RegisterFrontend_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "frontend.proto",
+}
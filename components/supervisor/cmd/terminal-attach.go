@@ -33,7 +33,7 @@ var terminalAttachCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		var (
 			alias  string
-			client = api.NewTerminalServiceClient(dialSupervisor())
+			client = dialSupervisor().Terminal
 		)
 		if len(args) == 0 {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -19,7 +19,7 @@ var terminalCloseCmd = &cobra.Command{
 	Short: "closes a terminal",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		client := api.NewTerminalServiceClient(dialSupervisor())
+		client := dialSupervisor().Terminal
 
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
@@ -23,7 +23,7 @@ var terminalListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "lists all of supervisor's terminals",
 	Run: func(cmd *cobra.Command, args []string) {
-		client := api.NewTerminalServiceClient(dialSupervisor())
+		client := dialSupervisor().Terminal
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -23,7 +23,7 @@ var terminalNewCmd = &cobra.Command{
 	Use:   "new",
 	Short: "opens a new terminal",
 	Run: func(cmd *cobra.Command, args []string) {
-		client := api.NewTerminalServiceClient(dialSupervisor())
+		client := dialSupervisor().Terminal
 
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
@@ -10,9 +10,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api/client"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/supervisor"
 )
 
@@ -26,7 +26,7 @@ func init() {
 	rootCmd.AddCommand(terminalCmd)
 }
 
-func dialSupervisor() *grpc.ClientConn {
+func dialSupervisor() *client.Client {
 	cfg, err := supervisor.GetConfig()
 	if err != nil {
 		log.WithError(err).Fatal("cannot get config")
@@ -35,12 +35,12 @@ func dialSupervisor() *grpc.ClientConn {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	url := fmt.Sprintf("localhost:%d", cfg.APIEndpointPort)
-	conn, err := grpc.DialContext(ctx, url, grpc.WithInsecure(), grpc.WithBlock())
+	c, err := client.New(ctx, client.WithAddr(url))
 	if err != nil {
 		log.WithError(err).Fatal("cannot connect to supervisor")
 	}
 
 	// TODO(cw): devise some means to properly close the connection
 
-	return conn
+	return c
 }
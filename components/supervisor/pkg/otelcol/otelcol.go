@@ -0,0 +1,293 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package otelcol is a minimal OpenTelemetry collector. It receives OTLP/HTTP exports from the applications
+// in a workspace and forwards them unchanged to a backend. It never decodes the payloads, which keeps it small,
+// but also means it cannot process or batch them. Users who need that can run a full collector instead.
+package otelcol
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// SignalTraces are the traces applications export to /v1/traces
+	SignalTraces = "traces"
+	// SignalLogs are the logs applications export to /v1/logs
+	SignalLogs = "logs"
+
+	// DefaultPort is the port OTLP/HTTP exporters send to by default
+	DefaultPort = 4318
+	// DefaultQueueSize is the number of exports we hold while the backend is slow or unavailable
+	DefaultQueueSize = 256
+
+	// maxExportSize limits the size of a single export we accept
+	maxExportSize = 8 << 20
+	// maxAttempts is how often we try to forward an export before we give up on it
+	maxAttempts = 5
+	// defaultBackoff is how long we wait before we retry an export for the first time
+	defaultBackoff = 500 * time.Millisecond
+)
+
+// Config configures the backend a collector forwards to
+type Config struct {
+	// Endpoint is the OTLP/HTTP endpoint of the backend. Signals are sent to /v1/<signal> below it.
+	Endpoint string
+	// Headers are sent to the backend with every export, e.g. to authenticate
+	Headers map[string]string
+	// Signals are the signals we forward. Defaults to all of them.
+	Signals []string
+}
+
+// Validate validates the config
+func (c *Config) Validate() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return xerrors.Errorf("invalid endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return xerrors.Errorf("endpoint must be an http or https URL")
+	}
+	for _, s := range c.Signals {
+		if s != SignalTraces && s != SignalLogs {
+			return xerrors.Errorf("unknown signal %q", s)
+		}
+	}
+	return nil
+}
+
+func (c *Config) forwards(signal string) bool {
+	if len(c.Signals) == 0 {
+		return true
+	}
+	for _, s := range c.Signals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats counts the exports a collector handled
+type Stats struct {
+	// Received is the number of exports applications sent us
+	Received uint64 `json:"received"`
+	// Forwarded is the number of exports the backend accepted
+	Forwarded uint64 `json:"forwarded"`
+	// Dropped is the number of exports we turned away because the queue was full
+	Dropped uint64 `json:"dropped"`
+	// Failed is the number of exports the backend rejected or we could not deliver
+	Failed uint64 `json:"failed"`
+}
+
+type export struct {
+	Signal          string
+	ContentType     string
+	ContentEncoding string
+	Body            []byte
+}
+
+// Collector receives OTLP/HTTP exports and forwards them to a backend
+type Collector struct {
+	// stats comes first so that its counters are 64 bit aligned for atomic access on 32 bit platforms
+	stats Stats
+
+	// Client sends the exports to the backend
+	Client *http.Client
+	// Backoff is how long we wait before we retry an export for the first time. It doubles with every attempt.
+	Backoff time.Duration
+
+	mu    sync.RWMutex
+	cfg   *Config
+	queue chan *export
+}
+
+// New creates a new collector which holds up to queueSize exports while the backend is slow or unavailable.
+// It doesn't forward anything until it's configured.
+func New(queueSize int) *Collector {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	return &Collector{
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		Backoff: defaultBackoff,
+		queue:   make(chan *export, queueSize),
+	}
+}
+
+// Configure changes the backend we forward to. Passing nil stops the collector from accepting exports.
+func (c *Collector) Configure(cfg *Config) {
+	c.mu.Lock()
+	c.cfg = cfg
+	c.mu.Unlock()
+}
+
+func (c *Collector) config() *Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cfg
+}
+
+// Stats returns the number of exports the collector handled so far
+func (c *Collector) Stats() Stats {
+	return Stats{
+		Received:  atomic.LoadUint64(&c.stats.Received),
+		Forwarded: atomic.LoadUint64(&c.stats.Forwarded),
+		Dropped:   atomic.LoadUint64(&c.stats.Dropped),
+		Failed:    atomic.LoadUint64(&c.stats.Failed),
+	}
+}
+
+// ServeHTTP implements the OTLP/HTTP receiver for traces and logs
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var signal string
+	switch r.URL.Path {
+	case "/v1/" + SignalTraces:
+		signal = SignalTraces
+	case "/v1/" + SignalLogs:
+		signal = SignalLogs
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg := c.config()
+	if cfg == nil {
+		http.Error(w, "no OpenTelemetry backend configured in .gitpod.yml", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxExportSize))
+	if err != nil {
+		http.Error(w, "export too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	atomic.AddUint64(&c.stats.Received, 1)
+
+	if cfg.forwards(signal) {
+		select {
+		case c.queue <- &export{
+			Signal:          signal,
+			ContentType:     r.Header.Get("Content-Type"),
+			ContentEncoding: r.Header.Get("Content-Encoding"),
+			Body:            body,
+		}:
+		default:
+			atomic.AddUint64(&c.stats.Dropped, 1)
+			// exporters retry on 503 and respect Retry-After
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "export queue is full", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	// an empty response message is an empty protobuf message, but not valid JSON
+	contentType := r.Header.Get("Content-Type")
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if strings.HasPrefix(contentType, "application/json") {
+		_, _ = w.Write([]byte("{}"))
+	}
+}
+
+// Run forwards the exports we receive to the backend until ctx is cancelled
+func (c *Collector) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-c.queue:
+			err := c.forward(ctx, e)
+			if err != nil {
+				atomic.AddUint64(&c.stats.Failed, 1)
+				log.WithError(err).WithField("signal", e.Signal).Debug("cannot forward OpenTelemetry export")
+				continue
+			}
+			atomic.AddUint64(&c.stats.Forwarded, 1)
+		}
+	}
+}
+
+func (c *Collector) forward(ctx context.Context, e *export) error {
+	backoff := c.Backoff
+	for attempt := 1; ; attempt++ {
+		// the backend may have changed since we received the export
+		cfg := c.config()
+		if cfg == nil {
+			return xerrors.Errorf("no backend configured")
+		}
+
+		retryAfter, err := c.send(ctx, cfg, e)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || attempt >= maxAttempts {
+			return err
+		}
+		if retryAfter < backoff {
+			retryAfter = backoff
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryAfter):
+		}
+		backoff *= 2
+	}
+}
+
+// send sends an export to the backend. If it fails, retryAfter is negative if retrying is pointless,
+// and how long the backend asked us to wait otherwise.
+func (c *Collector) send(ctx context.Context, cfg *Config, e *export) (retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/"+e.Signal, bytes.NewReader(e.Body))
+	if err != nil {
+		return -1, err
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if e.ContentType != "" {
+		req.Header.Set("Content-Type", e.ContentType)
+	}
+	if e.ContentEncoding != "" {
+		req.Header.Set("Content-Encoding", e.ContentEncoding)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		return 0, nil
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(s) * time.Second
+		}
+		return retryAfter, xerrors.Errorf("backend is unavailable: %s", resp.Status)
+	default:
+		return -1, xerrors.Errorf("backend rejected export: %s", resp.Status)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package otelcol
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type receivedExport struct {
+	Path        string
+	ContentType string
+	Auth        string
+	Body        string
+}
+
+type testBackend struct {
+	mu       sync.Mutex
+	received []receivedExport
+	// failures is the number of requests the backend answers with 503 before it accepts exports
+	failures int
+}
+
+func (b *testBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures > 0 {
+		b.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	b.received = append(b.received, receivedExport{
+		Path:        r.URL.Path,
+		ContentType: r.Header.Get("Content-Type"),
+		Auth:        r.Header.Get("X-Api-Key"),
+		Body:        string(body),
+	})
+}
+
+func (b *testBackend) Received() []receivedExport {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]receivedExport(nil), b.received...)
+}
+
+func sendExport(c *Collector, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	return rec
+}
+
+func waitForStats(t *testing.T, c *Collector, cond func(Stats) bool) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if cond(c.Stats()) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("collector did not reach the expected state: %+v", c.Stats())
+}
+
+func TestCollector(t *testing.T) {
+	backend := &testBackend{failures: 2}
+	srv := httptest.NewServer(backend)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New(10)
+	c.Backoff = time.Millisecond
+	c.Configure(&Config{
+		Endpoint: srv.URL + "/",
+		Headers:  map[string]string{"X-Api-Key": "secret"},
+		Signals:  []string{SignalTraces},
+	})
+	go c.Run(ctx)
+
+	rec := sendExport(c, "/v1/traces", "application/x-protobuf", "spans")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("unexpected response to protobuf export: %d %q", rec.Code, rec.Body.String())
+	}
+	rec = sendExport(c, "/v1/traces", "application/json", `{"resourceSpans":[]}`)
+	if rec.Code != http.StatusOK || rec.Body.String() != "{}" {
+		t.Errorf("unexpected response to JSON export: %d %q", rec.Code, rec.Body.String())
+	}
+	// logs aren't forwarded, but the application doesn't need to know
+	rec = sendExport(c, "/v1/logs", "application/x-protobuf", "logs")
+	if rec.Code != http.StatusOK {
+		t.Errorf("unexpected response to export of signal we don't forward: %d", rec.Code)
+	}
+	rec = sendExport(c, "/v1/metrics", "application/x-protobuf", "metrics")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unexpected response to export of unsupported signal: %d", rec.Code)
+	}
+
+	waitForStats(t, c, func(s Stats) bool { return s.Forwarded == 2 })
+	expected := []receivedExport{
+		{Path: "/v1/traces", ContentType: "application/x-protobuf", Auth: "secret", Body: "spans"},
+		{Path: "/v1/traces", ContentType: "application/json", Auth: "secret", Body: `{"resourceSpans":[]}`},
+	}
+	if diff := cmp.Diff(expected, backend.Received()); diff != "" {
+		t.Errorf("unexpected exports (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(Stats{Received: 3, Forwarded: 2}, c.Stats()); diff != "" {
+		t.Errorf("unexpected stats (-want +got):\n%s", diff)
+	}
+}
+
+func TestCollectorBackendRejects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New(10)
+	c.Configure(&Config{Endpoint: srv.URL})
+	go c.Run(ctx)
+
+	sendExport(c, "/v1/logs", "application/x-protobuf", "logs")
+	// we must not retry exports the backend rejected
+	waitForStats(t, c, func(s Stats) bool { return s.Failed == 1 })
+}
+
+func TestCollectorQueueFull(t *testing.T) {
+	c := New(1)
+	c.Configure(&Config{Endpoint: "http://localhost:1"})
+
+	// nothing forwards the exports, hence the second one doesn't fit into the queue
+	if rec := sendExport(c, "/v1/traces", "application/x-protobuf", "1"); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected response to first export: %d", rec.Code)
+	}
+	rec := sendExport(c, "/v1/traces", "application/x-protobuf", "2")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("expected exporter to be asked to retry later, got %d", rec.Code)
+	}
+	if s := c.Stats(); s.Dropped != 1 {
+		t.Errorf("expected one dropped export, got %+v", s)
+	}
+}
+
+func TestCollectorUnconfigured(t *testing.T) {
+	c := New(1)
+	if rec := sendExport(c, "/v1/traces", "application/x-protobuf", "spans"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected unconfigured collector to turn exports away, got %d", rec.Code)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Cfg   Config
+		Valid bool
+	}{
+		{Name: "valid", Cfg: Config{Endpoint: "https://api.honeycomb.io", Signals: []string{SignalTraces, SignalLogs}}, Valid: true},
+		{Name: "no scheme", Cfg: Config{Endpoint: "api.honeycomb.io"}},
+		{Name: "grpc", Cfg: Config{Endpoint: "grpc://localhost:4317"}},
+		{Name: "unknown signal", Cfg: Config{Endpoint: "https://api.honeycomb.io", Signals: []string{"metrics"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Cfg.Validate()
+			if (err == nil) != test.Valid {
+				t.Errorf("unexpected validation result: %v", err)
+			}
+		})
+	}
+}
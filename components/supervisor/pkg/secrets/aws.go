@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// awsSecretsManagerProvider reads secrets using the AWS Secrets Manager API. Names are secret names or ARNs.
+type awsSecretsManagerProvider struct {
+	Config ProviderConfig
+	Client *http.Client
+
+	now func() time.Time
+}
+
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Fetch reads a secret
+func (p *awsSecretsManagerProvider) Fetch(ctx context.Context, name string) (*Secret, error) {
+	region := p.Config.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		return nil, xerrors.Errorf("no AWS region: configure a region or set AWS_REGION")
+	}
+	creds := awsCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, xerrors.Errorf("no AWS credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	endpoint := p.Config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": name})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWSRequestV4(req, body, creds, region, "secretsmanager", p.now())
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read %s from AWS Secrets Manager: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary *string `json:"SecretBinary"`
+		Type         string  `json:"__type"`
+		Message      string  `json:"message"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&res)
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("cannot read %s from AWS Secrets Manager: %d %s %s", name, resp.StatusCode, res.Type, res.Message)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode AWS Secrets Manager response: %w", err)
+	}
+
+	switch {
+	case res.SecretString != nil:
+		return &Secret{Value: []byte(*res.SecretString)}, nil
+	case res.SecretBinary != nil:
+		value, err := base64.StdEncoding.DecodeString(*res.SecretBinary)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot decode secret %s: %w", name, err)
+		}
+		return &Secret{Value: value}, nil
+	}
+	return nil, xerrors.Errorf("secret %s has no value", name)
+}
+
+// Renew is not supported - AWS Secrets Manager has no leases
+func (p *awsSecretsManagerProvider) Renew(ctx context.Context, secret *Secret) (*Secret, error) {
+	return nil, ErrNotRenewable
+}
+
+// signAWSRequestV4 signs a request using AWS Signature Version 4
+func signAWSRequestV4(req *http.Request, body []byte, creds awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{amzDate[:8], region, service, "aws4_request"}, "/")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, part := range []string{amzDate[:8], region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
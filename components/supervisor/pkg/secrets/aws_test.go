@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAWSRequestV4(t *testing.T) {
+	// the example from the AWS Signature Version 4 documentation
+	req, err := http.NewRequest("GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	now, _ := time.Parse("20060102T150405Z", "20150830T123600Z")
+
+	signAWSRequestV4(req, nil, awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "iam", now)
+
+	expectation := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if act := req.Header.Get("Authorization"); act != expectation {
+		t.Errorf("unexpected signature:\nexpected %s\ngot      %s", expectation, act)
+	}
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"__type":"AccessDeniedException","message":"denied"}`))
+			return
+		}
+		var body strings.Builder
+		buf := make([]byte, 512)
+		n, _ := r.Body.Read(buf)
+		body.Write(buf[:n])
+		switch body.String() {
+		case `{"SecretId":"prod/db"}`:
+			_, _ = w.Write([]byte(`{"Name":"prod/db","SecretString":"{\"password\":\"pw\"}"}`))
+		case `{"SecretId":"prod/cert"}`:
+			_, _ = w.Write([]byte(`{"Name":"prod/cert","SecretBinary":"aGVsbG8="}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	setenv(t, "AWS_ACCESS_KEY_ID", "AKID")
+	setenv(t, "AWS_SECRET_ACCESS_KEY", "secret")
+	setenv(t, "AWS_SESSION_TOKEN", "session")
+	p, err := NewProvider(ProviderConfig{Type: ProviderAWSSecretsManager, Region: "eu-west-1", Endpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for name, expectation := range map[string]string{
+		"prod/db":   `{"password":"pw"}`,
+		"prod/cert": "hello",
+	} {
+		s, err := p.Fetch(ctx, name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if string(s.Value) != expectation {
+			t.Errorf("%s: expected %q, got %q", name, expectation, string(s.Value))
+		}
+		if s.Renewable {
+			t.Errorf("%s: AWS secrets have no lease", name)
+		}
+	}
+
+	_, err = p.Fetch(ctx, "prod/missing")
+	if err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// DefaultTTL is how long we cache secrets which have no lease, e.g. KV secrets
+	DefaultTTL = 5 * time.Minute
+
+	renewInterval = 10 * time.Second
+)
+
+type cachedSecret struct {
+	Provider string
+	Name     string
+	Secret   *Secret
+	Expires  time.Time
+}
+
+// Manager fetches secrets from the configured providers, caches them and keeps their leases alive
+type Manager struct {
+	Providers map[string]Provider
+	// TTL is how long we cache secrets which have no lease
+	TTL time.Duration
+
+	now   func() time.Time
+	mu    sync.Mutex
+	cache map[string]*cachedSecret
+}
+
+// NewManager creates a new secrets manager
+func NewManager(providers map[string]Provider) *Manager {
+	return &Manager{
+		Providers: providers,
+		TTL:       DefaultTTL,
+		now:       time.Now,
+		cache:     make(map[string]*cachedSecret),
+	}
+}
+
+// Get returns the value of a secret. If the provider is empty and there is only one provider, we use that one.
+// If the name ends in #key, the secret must be a JSON object and we return the value of key.
+func (m *Manager) Get(ctx context.Context, provider, name string) (string, error) {
+	provider, err := m.providerName(provider)
+	if err != nil {
+		return "", err
+	}
+	name, key := splitKey(name)
+
+	secret, err := m.fetch(ctx, provider, name)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return string(secret.Value), nil
+	}
+
+	var fields map[string]interface{}
+	err = json.Unmarshal(secret.Value, &fields)
+	if err != nil {
+		return "", xerrors.Errorf("secret %s is not a JSON object", name)
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", xerrors.Errorf("secret %s has no key %s", name, key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (m *Manager) providerName(provider string) (string, error) {
+	if provider != "" {
+		if _, ok := m.Providers[provider]; !ok {
+			return "", xerrors.Errorf("unknown secrets provider %s", provider)
+		}
+		return provider, nil
+	}
+	if len(m.Providers) != 1 {
+		return "", xerrors.Errorf("secret does not name its provider, but there are %d providers", len(m.Providers))
+	}
+	for name := range m.Providers {
+		provider = name
+	}
+	return provider, nil
+}
+
+func splitKey(name string) (string, string) {
+	if i := strings.LastIndex(name, "#"); i > 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+func (m *Manager) fetch(ctx context.Context, provider, name string) (*Secret, error) {
+	id := provider + "/" + name
+
+	m.mu.Lock()
+	if c, ok := m.cache[id]; ok && m.now().Before(c.Expires) {
+		m.mu.Unlock()
+		return c.Secret, nil
+	}
+	m.mu.Unlock()
+
+	// we don't hold the lock while fetching - if two tasks fetch the same secret at once, the last one wins
+	secret, err := m.Providers[provider].Fetch(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.cache[id] = &cachedSecret{
+		Provider: provider,
+		Name:     name,
+		Secret:   secret,
+		Expires:  m.expiry(secret),
+	}
+	m.mu.Unlock()
+	return secret, nil
+}
+
+func (m *Manager) expiry(secret *Secret) time.Time {
+	if secret.LeaseDuration > 0 {
+		return m.now().Add(secret.LeaseDuration)
+	}
+	return m.now().Add(m.TTL)
+}
+
+// Run renews the leases of the secrets we fetched until the context is canceled
+func (m *Manager) Run(ctx context.Context) {
+	t := time.NewTicker(renewInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			m.renew(ctx)
+		}
+	}
+}
+
+// renew extends the leases which expire within a third of their duration and forgets expired secrets
+func (m *Manager) renew(ctx context.Context) {
+	m.mu.Lock()
+	var due []*cachedSecret
+	for id, c := range m.cache {
+		remaining := c.Expires.Sub(m.now())
+		if remaining <= 0 {
+			delete(m.cache, id)
+			continue
+		}
+		if c.Secret.Renewable && remaining < c.Secret.LeaseDuration/3 {
+			due = append(due, c)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, c := range due {
+		renewed, err := m.Providers[c.Provider].Renew(ctx, c.Secret)
+		if err != nil {
+			log.WithError(err).WithField("secret", fmt.Sprintf("%s/%s", c.Provider, c.Name)).Warn("cannot renew secret lease")
+			continue
+		}
+
+		m.mu.Lock()
+		c.Secret = renewed
+		c.Expires = m.expiry(renewed)
+		m.mu.Unlock()
+	}
+}
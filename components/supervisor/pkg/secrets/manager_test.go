@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package secrets
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+type fakeProvider struct {
+	Secrets map[string]*Secret
+	Fetches int
+	Renews  int
+}
+
+func (p *fakeProvider) Fetch(ctx context.Context, name string) (*Secret, error) {
+	p.Fetches++
+	s, ok := p.Secrets[name]
+	if !ok {
+		return nil, xerrors.Errorf("secret %s does not exist", name)
+	}
+	return s, nil
+}
+
+func (p *fakeProvider) Renew(ctx context.Context, secret *Secret) (*Secret, error) {
+	if !secret.Renewable {
+		return nil, ErrNotRenewable
+	}
+	p.Renews++
+	return &Secret{Value: secret.Value, LeaseID: secret.LeaseID, LeaseDuration: secret.LeaseDuration, Renewable: true}, nil
+}
+
+func TestManagerGet(t *testing.T) {
+	provider := &fakeProvider{Secrets: map[string]*Secret{
+		"plain": {Value: []byte("hello")},
+		"json":  {Value: []byte(`{"user":"admin","port":5432}`)},
+	}}
+	mgr := NewManager(map[string]Provider{"vault": provider})
+
+	tests := []struct {
+		Provider    string
+		Name        string
+		Expectation string
+		Error       bool
+	}{
+		{Provider: "vault", Name: "plain", Expectation: "hello"},
+		{Name: "plain", Expectation: "hello"},
+		{Name: "json#user", Expectation: "admin"},
+		{Name: "json#port", Expectation: "5432"},
+		{Name: "json#password", Error: true},
+		{Name: "plain#user", Error: true},
+		{Name: "missing", Error: true},
+		{Provider: "aws", Name: "plain", Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Provider+"/"+test.Name, func(t *testing.T) {
+			act, err := mgr.Get(context.Background(), test.Provider, test.Name)
+			if test.Error {
+				if err == nil {
+					t.Errorf("expected an error, got %q", act)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if act != test.Expectation {
+				t.Errorf("unexpected value: expected %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestManagerRequiresProviderName(t *testing.T) {
+	mgr := NewManager(map[string]Provider{"a": &fakeProvider{}, "b": &fakeProvider{}})
+	_, err := mgr.Get(context.Background(), "", "plain")
+	if err == nil {
+		t.Error("expected an error when the provider is ambiguous")
+	}
+}
+
+func TestManagerCache(t *testing.T) {
+	var (
+		now      = time.Now()
+		provider = &fakeProvider{Secrets: map[string]*Secret{
+			"kv":      {Value: []byte(`{"field":"static"}`)},
+			"dynamic": {Value: []byte("creds"), LeaseID: "lease", LeaseDuration: time.Hour, Renewable: true},
+		}}
+		mgr = NewManager(map[string]Provider{"vault": provider})
+	)
+	mgr.now = func() time.Time { return now }
+
+	get := func(name string) {
+		_, err := mgr.Get(context.Background(), "", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get("kv")
+	get("kv#field")
+	if provider.Fetches != 1 {
+		t.Errorf("expected one fetch, got %d", provider.Fetches)
+	}
+	now = now.Add(DefaultTTL + time.Second)
+	get("kv")
+	if provider.Fetches != 2 {
+		t.Errorf("expected the secret to be fetched again after the TTL, got %d fetches", provider.Fetches)
+	}
+
+	get("dynamic")
+	now = now.Add(30 * time.Minute)
+	mgr.renew(context.Background())
+	if provider.Renews != 0 {
+		t.Errorf("expected no renewal before the last third of the lease")
+	}
+	now = now.Add(15 * time.Minute)
+	mgr.renew(context.Background())
+	if provider.Renews != 1 {
+		t.Errorf("expected the lease to be renewed, got %d renewals", provider.Renews)
+	}
+	now = now.Add(50 * time.Minute)
+	get("dynamic")
+	if provider.Fetches != 3 {
+		t.Errorf("expected the renewed secret to be cached, got %d fetches", provider.Fetches)
+	}
+
+	// expired secrets are forgotten
+	now = now.Add(2 * time.Hour)
+	mgr.renew(context.Background())
+	mgr.mu.Lock()
+	n := len(mgr.cache)
+	mgr.mu.Unlock()
+	if n != 0 {
+		t.Errorf("expected expired secrets to be removed from the cache, got %d", n)
+	}
+}
+
+// setenv sets an environment variable for the duration of a test
+func setenv(t *testing.T, key, value string) {
+	prev, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
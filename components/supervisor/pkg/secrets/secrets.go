@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package secrets fetches secrets from secrets managers, e.g. HashiCorp Vault or AWS Secrets Manager,
+// when a task starts. Secrets only ever live in memory and in the environment and files of the tasks
+// which declare them, so that they never end up in Gitpod's environment variable store or in a backup.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ProviderVault fetches secrets from HashiCorp Vault
+	ProviderVault = "vault"
+	// ProviderAWSSecretsManager fetches secrets from AWS Secrets Manager
+	ProviderAWSSecretsManager = "aws-secrets-manager"
+
+	defaultRequestTimeout = 10 * time.Second
+)
+
+// ErrNotRenewable is returned when renewing a secret which has no renewable lease
+var ErrNotRenewable = xerrors.Errorf("secret is not renewable")
+
+// Secret is a secret fetched from a secrets manager
+type Secret struct {
+	// Value is the secret itself. Secrets with multiple fields are JSON objects.
+	Value []byte
+	// LeaseID identifies the lease of dynamic secrets, e.g. database credentials Vault created for us
+	LeaseID string
+	// LeaseDuration is how long the secret is valid. Zero means the secret does not expire.
+	LeaseDuration time.Duration
+	// Renewable is true if the lease can be extended
+	Renewable bool
+}
+
+// Provider fetches secrets from a secrets manager
+type Provider interface {
+	// Fetch fetches a secret by its name
+	Fetch(ctx context.Context, name string) (*Secret, error)
+	// Renew extends the lease of a secret. Providers which have no leases return ErrNotRenewable.
+	Renew(ctx context.Context, secret *Secret) (*Secret, error)
+}
+
+// ProviderConfig configures a secrets provider
+type ProviderConfig struct {
+	// Type is either vault or aws-secrets-manager
+	Type string `json:"type"`
+
+	// Address is the URL of the Vault server, e.g. https://vault.example.com:8200
+	Address string `json:"address,omitempty"`
+	// Namespace is the Vault Enterprise namespace
+	Namespace string `json:"namespace,omitempty"`
+	// TokenFile contains the Vault token, e.g. after vault login. Defaults to ~/.vault-token.
+	// The VAULT_TOKEN environment variable takes precedence.
+	TokenFile string `json:"tokenFile,omitempty"`
+
+	// Region is the AWS region of the secrets. Defaults to the AWS_REGION environment variable.
+	// The credentials come from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+	Region string `json:"region,omitempty"`
+	// Endpoint overrides the AWS Secrets Manager endpoint, e.g. for VPC endpoints
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// Validate validates the configuration
+func (c ProviderConfig) Validate() error {
+	switch c.Type {
+	case ProviderVault:
+		if c.Address == "" {
+			return fmt.Errorf("vault requires an address")
+		}
+	case ProviderAWSSecretsManager:
+	default:
+		return fmt.Errorf("unknown secrets provider type %q", c.Type)
+	}
+	return nil
+}
+
+// NewProvider creates the provider a configuration describes
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: defaultRequestTimeout}
+	switch cfg.Type {
+	case ProviderVault:
+		return &vaultProvider{Config: cfg, Client: client}, nil
+	case ProviderAWSSecretsManager:
+		return &awsSecretsManagerProvider{Config: cfg, Client: client, now: time.Now}, nil
+	}
+	return nil, fmt.Errorf("unknown secrets provider type %q", cfg.Type)
+}
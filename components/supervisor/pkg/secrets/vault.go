@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// vaultProvider reads secrets using the Vault HTTP API. Names are API paths, e.g. secret/data/myapp for
+// the KV version 2 secret myapp, or database/creds/readonly for dynamic database credentials.
+type vaultProvider struct {
+	Config ProviderConfig
+	Client *http.Client
+}
+
+type vaultResponse struct {
+	LeaseID       string          `json:"lease_id"`
+	LeaseDuration int64           `json:"lease_duration"`
+	Renewable     bool            `json:"renewable"`
+	Data          json.RawMessage `json:"data"`
+	Errors        []string        `json:"errors"`
+}
+
+// Fetch reads a secret
+func (p *vaultProvider) Fetch(ctx context.Context, name string) (*Secret, error) {
+	var resp vaultResponse
+	err := p.call(ctx, "GET", strings.TrimPrefix(name, "/"), nil, &resp)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read %s from vault: %w", name, err)
+	}
+
+	value := resp.Data
+	// KV version 2 wraps the secret in data and adds metadata
+	var kv2 struct {
+		Data     json.RawMessage `json:"data"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if json.Unmarshal(resp.Data, &kv2) == nil && kv2.Data != nil && kv2.Metadata != nil {
+		value = kv2.Data
+	}
+	if len(value) == 0 || string(value) == "null" {
+		return nil, xerrors.Errorf("secret %s does not exist in vault", name)
+	}
+
+	return &Secret{
+		Value:         value,
+		LeaseID:       resp.LeaseID,
+		LeaseDuration: time.Duration(resp.LeaseDuration) * time.Second,
+		Renewable:     resp.Renewable && resp.LeaseID != "",
+	}, nil
+}
+
+// Renew extends the lease of a dynamic secret
+func (p *vaultProvider) Renew(ctx context.Context, secret *Secret) (*Secret, error) {
+	if !secret.Renewable {
+		return nil, ErrNotRenewable
+	}
+
+	var resp vaultResponse
+	err := p.call(ctx, "PUT", "sys/leases/renew", map[string]interface{}{
+		"lease_id":  secret.LeaseID,
+		"increment": int64(secret.LeaseDuration.Seconds()),
+	}, &resp)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot renew lease %s: %w", secret.LeaseID, err)
+	}
+	return &Secret{
+		Value:         secret.Value,
+		LeaseID:       secret.LeaseID,
+		LeaseDuration: time.Duration(resp.LeaseDuration) * time.Second,
+		Renewable:     resp.Renewable,
+	}, nil
+}
+
+func (p *vaultProvider) call(ctx context.Context, method, path string, body interface{}, res *vaultResponse) error {
+	token, err := p.token()
+	if err != nil {
+		return err
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(p.Config.Address, "/")+"/v1/"+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	req.Header.Set("X-Vault-Request", "true")
+	if p.Config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Config.Namespace)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(res)
+	if resp.StatusCode != http.StatusOK {
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.Join(res.Errors, ", "))
+		}
+		return fmt.Errorf("vault returned %d", resp.StatusCode)
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot decode vault response: %w", err)
+	}
+	return nil
+}
+
+// token reads the Vault token every time we need it, so that a vault login in a terminal takes effect right away
+func (p *vaultProvider) token() (string, error) {
+	if tkn := os.Getenv("VAULT_TOKEN"); tkn != "" {
+		return tkn, nil
+	}
+
+	fn := p.Config.TokenFile
+	if fn == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		fn = filepath.Join(home, ".vault-token")
+	}
+	tkn, err := os.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return "", xerrors.Errorf("no vault token: set VAULT_TOKEN or run vault login")
+	}
+	if err != nil {
+		return "", xerrors.Errorf("cannot read vault token: %w", err)
+	}
+	return strings.TrimSpace(string(tkn)), nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVaultProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/secret/data/myapp":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"hunter2"},"metadata":{"version":3}}}`))
+		case "GET /v1/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abc","lease_duration":3600,"renewable":true,"data":{"username":"v-ro","password":"pw"}}`))
+		case "PUT /v1/sys/leases/renew":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["lease_id"] != "database/creds/readonly/abc" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":["invalid lease"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abc","lease_duration":1800,"renewable":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	setenv(t, "VAULT_TOKEN", "s.token")
+	p, err := NewProvider(ProviderConfig{Type: ProviderVault, Address: srv.URL, Namespace: "team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	kv, err := p.Fetch(ctx, "secret/data/myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&Secret{Value: []byte(`{"password":"hunter2"}`)}, kv); diff != "" {
+		t.Errorf("unexpected KV secret (-want +got):\n%s", diff)
+	}
+
+	creds, err := p.Fetch(ctx, "/database/creds/readonly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.LeaseID != "database/creds/readonly/abc" || creds.LeaseDuration != time.Hour || !creds.Renewable {
+		t.Errorf("unexpected lease: %+v", creds)
+	}
+	renewed, err := p.Renew(ctx, creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.LeaseDuration != 30*time.Minute || string(renewed.Value) != string(creds.Value) {
+		t.Errorf("unexpected renewed secret: %+v", renewed)
+	}
+
+	_, err = p.Renew(ctx, kv)
+	if err != ErrNotRenewable {
+		t.Errorf("expected ErrNotRenewable, got %v", err)
+	}
+	_, err = p.Fetch(ctx, "secret/data/missing")
+	if err == nil {
+		t.Error("expected an error for a missing secret")
+	}
+
+	setenv(t, "VAULT_TOKEN", "wrong")
+	_, err = p.Fetch(ctx, "secret/data/myapp")
+	if err == nil {
+		t.Error("expected an error for a wrong token")
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package sharedcache coordinates the workspaces of a node which share a dependency cache.
+//
+// The cache is a directory all workspaces of a node can write to. Committed entries are immutable:
+// a workspace populates an entry in a staging directory while it holds a claim on the entry, and
+// commits the entry by renaming the staging directory. If two workspaces populate the same entry,
+// the first one to commit wins and the other one discards its copy. Claims are leases, so that the
+// claims of workspaces which stopped while populating an entry expire.
+package sharedcache
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	entriesDir = "entries"
+	claimsDir  = "claims"
+	stagingDir = "staging"
+	statsFile  = "stats.json"
+
+	// DefaultLeaseTTL is how long a claim stays valid without being renewed
+	DefaultLeaseTTL = 10 * time.Minute
+)
+
+var (
+	// ErrExists is returned when claiming or committing an entry which was committed already
+	ErrExists = xerrors.Errorf("cache entry exists already")
+	// ErrBusy is returned when claiming an entry which another workspace is populating
+	ErrBusy = xerrors.Errorf("cache entry is claimed by another workspace")
+
+	validKey = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*$`)
+)
+
+// Cache is a dependency cache shared between the workspaces of a node
+type Cache struct {
+	// Root is the directory of the cache
+	Root string
+	// Owner identifies this workspace in claims, e.g. the workspace instance ID
+	Owner string
+	// LeaseTTL is how long a claim stays valid without being renewed
+	LeaseTTL time.Duration
+}
+
+// New creates a new cache client
+func New(root, owner string) *Cache {
+	return &Cache{
+		Root:     root,
+		Owner:    owner,
+		LeaseTTL: DefaultLeaseTTL,
+	}
+}
+
+// Lookup returns the location of a committed entry
+func (c *Cache) Lookup(key string) (location string, ok bool) {
+	if !validKey.MatchString(key) {
+		return "", false
+	}
+	location = filepath.Join(c.Root, entriesDir, key)
+	if stat, err := os.Stat(location); err != nil || !stat.IsDir() {
+		return "", false
+	}
+	return location, true
+}
+
+// Claim claims an entry so that we can populate it. Returns ErrExists if the entry is committed
+// already, and ErrBusy if another workspace holds a valid claim on the entry.
+func (c *Cache) Claim(key string) (*Claim, error) {
+	if !validKey.MatchString(key) {
+		return nil, xerrors.Errorf("invalid cache key %q", key)
+	}
+	if _, ok := c.Lookup(key); ok {
+		return nil, ErrExists
+	}
+
+	for _, d := range []string{claimsDir, stagingDir, entriesDir} {
+		err := os.MkdirAll(filepath.Join(c.Root, d), 0755)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot prepare shared cache: %w", err)
+		}
+	}
+
+	lock := filepath.Join(c.Root, claimsDir, key)
+	err := c.createClaim(lock)
+	if os.IsExist(err) {
+		stat, serr := os.Stat(lock)
+		if serr == nil && time.Since(stat.ModTime()) < c.LeaseTTL {
+			return nil, ErrBusy
+		}
+
+		// The claim belongs to a workspace which stopped while populating the entry. The rename is atomic,
+		// hence only one of the workspaces which try to take over the claim succeeds.
+		stale := lock + ".stale." + c.Owner
+		if rerr := os.Rename(lock, stale); rerr != nil {
+			return nil, ErrBusy
+		}
+		_ = os.Remove(stale)
+		err = c.createClaim(lock)
+		if os.IsExist(err) {
+			return nil, ErrBusy
+		}
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot claim cache entry %s: %w", key, err)
+	}
+
+	staging := filepath.Join(c.Root, stagingDir, key+"."+c.Owner)
+	err = os.RemoveAll(staging)
+	if err == nil {
+		err = os.MkdirAll(staging, 0755)
+	}
+	if err != nil {
+		_ = os.Remove(lock)
+		return nil, xerrors.Errorf("cannot prepare staging of cache entry %s: %w", key, err)
+	}
+
+	return &Claim{
+		Key:   key,
+		Dir:   staging,
+		cache: c,
+		lock:  lock,
+	}, nil
+}
+
+func (c *Cache) createClaim(lock string) error {
+	f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(c.Owner)
+	if err != nil {
+		f.Close()
+		_ = os.Remove(lock)
+		return err
+	}
+	return f.Close()
+}
+
+// Claim is our claim on a cache entry
+type Claim struct {
+	// Key is the key of the entry
+	Key string
+	// Dir is the staging directory we populate the entry in
+	Dir string
+
+	cache *Cache
+	lock  string
+}
+
+// Renew extends the lease of the claim. Populating an entry which takes longer than the lease TTL must renew the claim.
+func (cl *Claim) Renew() error {
+	now := time.Now()
+	return os.Chtimes(cl.lock, now, now)
+}
+
+// Commit makes the entry available to all workspaces. Returns ErrExists if another workspace committed the entry first,
+// in which case we discard our copy.
+func (cl *Claim) Commit() error {
+	defer cl.release()
+
+	dst := filepath.Join(cl.cache.Root, entriesDir, cl.Key)
+	if _, ok := cl.cache.Lookup(cl.Key); ok {
+		_ = os.RemoveAll(cl.Dir)
+		return ErrExists
+	}
+	err := os.Rename(cl.Dir, dst)
+	if err != nil {
+		_ = os.RemoveAll(cl.Dir)
+		if _, ok := cl.cache.Lookup(cl.Key); ok {
+			return ErrExists
+		}
+		return xerrors.Errorf("cannot commit cache entry %s: %w", cl.Key, err)
+	}
+	return nil
+}
+
+// Abandon gives up the claim without committing the entry
+func (cl *Claim) Abandon() error {
+	defer cl.release()
+	return os.RemoveAll(cl.Dir)
+}
+
+// release removes the claim unless another workspace took it over because our lease expired
+func (cl *Claim) release() {
+	owner, err := os.ReadFile(cl.lock)
+	if err != nil || strings.TrimSpace(string(owner)) != cl.cache.Owner {
+		return
+	}
+	_ = os.Remove(cl.lock)
+}
+
+// Stats counts how the workspaces of a node use the cache
+type Stats struct {
+	// Hits is the number of lookups which found an entry
+	Hits uint64 `json:"hits"`
+	// Misses is the number of lookups which found no entry
+	Misses uint64 `json:"misses"`
+	// Commits is the number of entries workspaces committed
+	Commits uint64 `json:"commits"`
+	// Conflicts is the number of entries workspaces populated but could not commit, because another workspace was first
+	Conflicts uint64 `json:"conflicts"`
+}
+
+// HitRate is the share of lookups which found an entry
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// RecordStats adds our stats to those of the node and returns the node-wide totals
+func (c *Cache) RecordStats(delta Stats) (total Stats, err error) {
+	err = os.MkdirAll(c.Root, 0755)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(c.Root, statsFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	// other workspaces record their stats concurrently
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		return
+	}
+	//nolint:errcheck
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return
+	}
+	if len(content) > 0 {
+		err = json.Unmarshal(content, &total)
+		if err != nil {
+			// we'd rather start over than never record stats again
+			total = Stats{}
+		}
+	}
+	total.Hits += delta.Hits
+	total.Misses += delta.Misses
+	total.Commits += delta.Commits
+	total.Conflicts += delta.Conflicts
+
+	content, err = json.Marshal(total)
+	if err != nil {
+		return
+	}
+	err = f.Truncate(0)
+	if err != nil {
+		return
+	}
+	_, err = f.WriteAt(content, 0)
+	return
+}
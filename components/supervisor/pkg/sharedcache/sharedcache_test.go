@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package sharedcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClaimAndCommit(t *testing.T) {
+	root := t.TempDir()
+
+	ws1, ws2 := New(root, "ws1"), New(root, "ws2")
+	if _, ok := ws1.Lookup("go-abc"); ok {
+		t.Fatal("found entry in empty cache")
+	}
+
+	claim, err := ws1.Claim("go-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ws2.Claim("go-abc"); err != ErrBusy {
+		t.Errorf("expected ErrBusy when claiming a claimed entry, got %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(claim.Dir, "mod"), []byte("content"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = claim.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	location, ok := ws2.Lookup("go-abc")
+	if !ok {
+		t.Fatal("committed entry not found")
+	}
+	content, err := os.ReadFile(filepath.Join(location, "mod"))
+	if err != nil || string(content) != "content" {
+		t.Errorf("unexpected entry content: %q, %v", content, err)
+	}
+	if _, err := ws2.Claim("go-abc"); err != ErrExists {
+		t.Errorf("expected ErrExists when claiming a committed entry, got %v", err)
+	}
+}
+
+func TestCommitConflict(t *testing.T) {
+	root := t.TempDir()
+
+	ws1, ws2 := New(root, "ws1"), New(root, "ws2")
+	// ws2 takes over the claim of ws1 after its lease expired
+	ws2.LeaseTTL = 0
+
+	claim1, err := ws1.Claim("maven-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	claim2, err := ws2.Claim("maven-abc")
+	if err != nil {
+		t.Fatalf("cannot take over stale claim: %v", err)
+	}
+
+	if err := claim2.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := claim1.Commit(); err != ErrExists {
+		t.Errorf("expected ErrExists when committing second, got %v", err)
+	}
+	if _, err := os.Stat(claim1.Dir); !os.IsNotExist(err) {
+		t.Errorf("staging directory of the losing claim was not removed")
+	}
+}
+
+func TestRecordStats(t *testing.T) {
+	root := t.TempDir()
+
+	c := New(root, "ws1")
+	_, err := c.RecordStats(Stats{Hits: 1, Misses: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, err := c.RecordStats(Stats{Hits: 2, Conflicts: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(Stats{Hits: 3, Misses: 1, Conflicts: 1}, total); diff != "" {
+		t.Errorf("unexpected stats (-want +got):\n%s", diff)
+	}
+	if rate := total.HitRate(); rate != 0.75 {
+		t.Errorf("unexpected hit rate: %f", rate)
+	}
+}
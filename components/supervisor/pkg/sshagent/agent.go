@@ -0,0 +1,249 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package sshagent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// KeyUsage records how often a key held by the agent was used
+type KeyUsage struct {
+	Comment     string
+	Fingerprint string
+	SignCount   int
+	LastUsed    time.Time
+}
+
+// Agent is an in-memory SSH agent which audits every use of the keys it holds.
+// Keys never touch the filesystem.
+type Agent struct {
+	keyring agent.Agent
+
+	mu    sync.Mutex
+	usage map[string]*KeyUsage
+}
+
+var _ agent.Agent = &Agent{}
+
+// New produces a new, empty agent
+func New() *Agent {
+	return &Agent{
+		keyring: agent.NewKeyring(),
+		usage:   make(map[string]*KeyUsage),
+	}
+}
+
+// AddPEMKey parses a PEM encoded private key and adds it to the agent
+func (a *Agent) AddPEMKey(pemBytes []byte, comment string) error {
+	key, err := ssh.ParseRawPrivateKey(pemBytes)
+	if err != nil {
+		return xerrors.Errorf("cannot parse private key %s: %w", comment, err)
+	}
+	return a.Add(agent.AddedKey{PrivateKey: key, Comment: comment})
+}
+
+// List returns the identities known to the agent
+func (a *Agent) List() ([]*agent.Key, error) {
+	return a.keyring.List()
+}
+
+// Sign has the agent sign the data using a protocol 2 key as defined
+// in [PROTOCOL.agent] section 2.6.2. Every signature is recorded in the audit log.
+func (a *Agent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	sig, err := a.keyring.Sign(key, data)
+	a.audit(key, err)
+	return sig, err
+}
+
+// Add adds a private key to the agent
+func (a *Agent) Add(key agent.AddedKey) error {
+	err := a.keyring.Add(key)
+	if err != nil {
+		return err
+	}
+
+	signer, err := ssh.NewSignerFromKey(key.PrivateKey)
+	if err != nil {
+		return err
+	}
+	fp := ssh.FingerprintSHA256(signer.PublicKey())
+
+	a.mu.Lock()
+	a.usage[fp] = &KeyUsage{Comment: key.Comment, Fingerprint: fp}
+	a.mu.Unlock()
+
+	log.WithField("fingerprint", fp).WithField("comment", key.Comment).Info("added key to SSH agent")
+	return nil
+}
+
+// Remove removes all identities with the given public key
+func (a *Agent) Remove(key ssh.PublicKey) error {
+	err := a.keyring.Remove(key)
+	if err != nil {
+		return err
+	}
+
+	fp := ssh.FingerprintSHA256(key)
+	a.mu.Lock()
+	delete(a.usage, fp)
+	a.mu.Unlock()
+
+	log.WithField("fingerprint", fp).Info("removed key from SSH agent")
+	return nil
+}
+
+// RemoveAll removes all identities
+func (a *Agent) RemoveAll() error {
+	err := a.keyring.RemoveAll()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.usage = make(map[string]*KeyUsage)
+	a.mu.Unlock()
+
+	log.Info("removed all keys from SSH agent")
+	return nil
+}
+
+// Lock locks the agent. Sign and Remove will fail, and List will return an empty list.
+func (a *Agent) Lock(passphrase []byte) error {
+	return a.keyring.Lock(passphrase)
+}
+
+// Unlock undoes the effect of Lock
+func (a *Agent) Unlock(passphrase []byte) error {
+	return a.keyring.Unlock(passphrase)
+}
+
+// Signers returns signers for all the known keys
+func (a *Agent) Signers() ([]ssh.Signer, error) {
+	return a.keyring.Signers()
+}
+
+// Usage returns the usage of all keys held by the agent, sorted by fingerprint
+func (a *Agent) Usage() []KeyUsage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	res := make([]KeyUsage, 0, len(a.usage))
+	for _, u := range a.usage {
+		res = append(res, *u)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Fingerprint < res[j].Fingerprint })
+	return res
+}
+
+func (a *Agent) audit(key ssh.PublicKey, err error) {
+	fp := ssh.FingerprintSHA256(key)
+	if err != nil {
+		log.WithField("fingerprint", fp).WithError(err).Warn("SSH agent signature request failed")
+		return
+	}
+
+	a.mu.Lock()
+	u, ok := a.usage[fp]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	u.SignCount++
+	u.LastUsed = time.Now()
+	usage := *u
+	a.mu.Unlock()
+
+	log.WithField("fingerprint", fp).WithField("comment", usage.Comment).WithField("signCount", usage.SignCount).Info("SSH agent key used")
+}
+
+// Serve serves the agent on a unix socket until the context is canceled.
+// The socket is only accessible to the current user: its directory is created with mode 0700
+// before we listen, so that nobody else can connect while the socket still has the umask permissions.
+func (a *Agent) Serve(ctx context.Context, socket string) error {
+	err := privateDir(filepath.Dir(socket))
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(socket)
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("cannot remove stale SSH agent socket: %w", err)
+	}
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return xerrors.Errorf("cannot listen on SSH agent socket: %w", err)
+	}
+	err = os.Chmod(socket, 0600)
+	if err != nil {
+		l.Close()
+		return xerrors.Errorf("cannot restrict SSH agent socket permissions: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+			err := agent.ServeAgent(a, conn)
+			if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.WithError(err).Debug("SSH agent connection failed")
+			}
+		}()
+	}
+}
+
+// privateDir makes sure dir exists, is a directory owned by the current user and only accessible to them.
+// The directory might live in a world-writable location like /tmp, hence we refuse symlinks and
+// directories someone else created.
+func privateDir(dir string) error {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return xerrors.Errorf("cannot create SSH agent socket directory: %w", err)
+	}
+
+	stat, err := os.Lstat(dir)
+	if err != nil {
+		return xerrors.Errorf("cannot stat SSH agent socket directory: %w", err)
+	}
+	if !stat.IsDir() {
+		return xerrors.Errorf("SSH agent socket directory %s is not a directory", dir)
+	}
+	if sys, ok := stat.Sys().(*syscall.Stat_t); ok && int(sys.Uid) != os.Getuid() {
+		return xerrors.Errorf("SSH agent socket directory %s is owned by uid %d", dir, sys.Uid)
+	}
+
+	err = os.Chmod(dir, 0700)
+	if err != nil {
+		return xerrors.Errorf("cannot restrict SSH agent socket directory permissions: %w", err)
+	}
+	return nil
+}
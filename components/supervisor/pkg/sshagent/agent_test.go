@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package sshagent_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/gitpod-io/gitpod/supervisor/pkg/sshagent"
+)
+
+func TestAgentAuditsSignatures(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ag := sshagent.New()
+	err = ag.Add(agent.AddedKey{PrivateKey: priv, Comment: "test-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	socket := filepath.Join(t.TempDir(), "agent", "agent.sock")
+	go ag.Serve(ctx, socket)
+
+	var conn net.Conn
+	for i := 0; i < 50; i++ {
+		conn, err = net.Dial("unix", socket)
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("cannot connect to agent: %v", err)
+	}
+	defer conn.Close()
+
+	stat, err := os.Stat(filepath.Dir(socket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := stat.Mode().Perm(); perm != 0700 {
+		t.Errorf("expected socket directory to have mode 0700, got %o", perm)
+	}
+
+	client := agent.NewClient(conn)
+	keys, err := client.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0].Comment != "test-key" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = client.Sign(keys[0], []byte("hello world"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage := ag.Usage()
+	if len(usage) != 1 {
+		t.Fatalf("expected usage for one key, got %d", len(usage))
+	}
+	if usage[0].SignCount != 2 {
+		t.Errorf("expected two signatures, got %d", usage[0].SignCount)
+	}
+	if usage[0].Fingerprint != ssh.FingerprintSHA256(keys[0]) {
+		t.Errorf("unexpected fingerprint %s", usage[0].Fingerprint)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIAccessPolicy(t *testing.T) {
+	policy := &APIAccessPolicy{Rules: []APIAccessRule{
+		{Methods: []string{"/supervisor.TerminalService/*"}, Allow: []APIPeer{APIPeerIDE}},
+		{Methods: []string{"/supervisor.TokenService/GetToken"}, Allow: []APIPeer{APIPeerIDE, APIPeerTerminal}},
+		{Methods: []string{"/supervisor.ControlService/ExposePort"}},
+	}}
+	if err := policy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Method      string
+		Peer        APIPeer
+		Expectation bool
+	}{
+		{"/supervisor.TerminalService/Open", APIPeerIDE, true},
+		{"/supervisor.TerminalService/Open", APIPeerTerminal, false},
+		{"/supervisor.TerminalService/Open", APIPeerTask, false},
+		{"/supervisor.TerminalServiceFoo/Open", APIPeerTask, true},
+		{"/supervisor.TokenService/GetToken", APIPeerTerminal, true},
+		{"/supervisor.TokenService/GetToken", APIPeerTask, false},
+		{"/supervisor.TokenService/SetToken", APIPeerTask, true},
+		{"/supervisor.ControlService/ExposePort", APIPeerIDE, false},
+		{"/supervisor.ControlService/ExposePort", apiPeerSupervisor, true},
+		{"/supervisor.ControlService/ExposePort", apiPeerRemote, true},
+		{"/supervisor.StatusService/SupervisorStatus", APIPeerOther, true},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %s", test.Peer, test.Method), func(t *testing.T) {
+			if act := policy.Allows(test.Method, test.Peer); act != test.Expectation {
+				t.Errorf("unexpected decision: want %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+
+	if !(*APIAccessPolicy)(nil).Allows("/supervisor.TerminalService/Open", APIPeerOther) {
+		t.Error("expected everything to be allowed without policy")
+	}
+
+	for _, invalid := range []*APIAccessPolicy{
+		{Rules: []APIAccessRule{{Allow: []APIPeer{APIPeerIDE}}}},
+		{Rules: []APIAccessRule{{Methods: []string{"supervisor.TerminalService/Open"}}}},
+		{Rules: []APIAccessRule{{Methods: []string{"/supervisor.TerminalService"}}}},
+		{Rules: []APIAccessRule{{Methods: []string{"/supervisor.TerminalService/*"}, Allow: []APIPeer{apiPeerRemote}}}},
+		{RemoteNetworks: []string{"10.0.0.1"}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected policy to be invalid: %v", invalid)
+		}
+	}
+}
+
+func TestParseProcNetAddr(t *testing.T) {
+	tests := []struct {
+		Addr        string
+		Expectation string
+	}{
+		{"0100007F:1F90", "127.0.0.1:8080"},
+		{"00000000000000000000000001000000:0016", "[::1]:22"},
+		{"0000000000000000FFFF00000100007F:1F90", "127.0.0.1:8080"},
+	}
+	for _, test := range tests {
+		addr, err := parseProcNetAddr(test.Addr)
+		if err != nil {
+			t.Errorf("%s: %v", test.Addr, err)
+			continue
+		}
+		if act := addr.String(); act != test.Expectation {
+			t.Errorf("%s: want %s, got %s", test.Addr, test.Expectation, act)
+		}
+	}
+	if _, err := parseProcNetAddr("0100007F"); err == nil {
+		t.Error("expected an error for an address without port")
+	}
+}
+
+func TestAPIPeerResolverPeer(t *testing.T) {
+	// supervisor (1) starts the IDE (10) and terminals (20, 30)
+	procfs := t.TempDir()
+	for pid, ppid := range map[int]int{
+		10: 1, 11: 10, 12: 11,
+		20: 1, 21: 20,
+		30: 1, 31: 30,
+		40: 1,
+		50: 0, 51: 50,
+	} {
+		dir := filepath.Join(procfs, fmt.Sprint(pid))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		status := fmt.Sprintf("Name:\tbash\nPid:\t%d\nPPid:\t%d\n", pid, ppid)
+		if err := os.WriteFile(filepath.Join(dir, "status"), []byte(status), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &apiPeerResolver{
+		Self:   1,
+		Procfs: procfs,
+		IDE:    func() int { return 10 },
+		Terminal: func(pid int) (isTask bool, ok bool) {
+			switch pid {
+			case 20:
+				return true, true
+			case 30:
+				return false, true
+			}
+			return false, false
+		},
+	}
+	for pid, expectation := range map[int]APIPeer{
+		1:  apiPeerSupervisor,
+		12: APIPeerIDE,
+		21: APIPeerTask,
+		31: APIPeerTerminal,
+		40: APIPeerOther,
+		51: APIPeerOther,
+		99: APIPeerOther,
+	} {
+		if act := r.peer(pid); act != expectation {
+			t.Errorf("process %d: want %s, got %s", pid, expectation, act)
+		}
+	}
+}
+
+func TestAPIPeerResolverResolve(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	r := &apiPeerResolver{Self: os.Getpid(), Procfs: "/proc"}
+	info := r.Resolve(conn.LocalAddr(), conn.RemoteAddr())
+	if info.Peer != apiPeerSupervisor || info.PID != os.Getpid() {
+		t.Errorf("expected the connection to be ours, got %+v", info)
+	}
+
+	info = r.Resolve(conn.LocalAddr(), &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4242})
+	if info.Peer != APIPeerOther {
+		t.Errorf("expected a connection we cannot attribute to be other without remote networks, got %+v", info)
+	}
+
+	_, remote, _ := net.ParseCIDR("10.0.0.0/16")
+	r.Remote = []*net.IPNet{remote}
+	info = r.Resolve(conn.LocalAddr(), &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4242})
+	if info.Peer != apiPeerRemote {
+		t.Errorf("expected a connection from a remote network to be remote, got %+v", info)
+	}
+	info = r.Resolve(conn.LocalAddr(), &net.TCPAddr{IP: net.ParseIP("172.17.0.2"), Port: 4242})
+	if info.Peer != APIPeerOther {
+		t.Errorf("expected a connection from outside the remote networks to be other, got %+v", info)
+	}
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	r.Remote = append(r.Remote, loopback)
+	info = r.Resolve(conn.LocalAddr(), &net.TCPAddr{IP: net.ParseIP("127.0.0.2"), Port: 4242})
+	if info.Peer != APIPeerOther {
+		t.Errorf("expected a loopback connection to never be remote, got %+v", info)
+	}
+}
+
+func TestAPIAccessControlAuthorize(t *testing.T) {
+	a := &apiAccessControl{Policy: &APIAccessPolicy{Rules: []APIAccessRule{
+		{Methods: []string{"/supervisor.TerminalService/*"}, Allow: []APIPeer{APIPeerIDE}},
+	}}}
+	const method = "/supervisor.TerminalService/Open"
+
+	tests := []struct {
+		Name     string
+		Peer     APIPeer
+		Metadata metadata.MD
+		Code     codes.Code
+	}{
+		{Name: "allowed", Peer: APIPeerIDE, Code: codes.OK},
+		{Name: "denied", Peer: APIPeerTask, Code: codes.PermissionDenied},
+		{Name: "supervisor", Peer: apiPeerSupervisor, Code: codes.OK},
+		{Name: "REST request of a task", Peer: apiPeerSupervisor, Metadata: metadata.Pairs(apiPeerMetadataKey, string(APIPeerTask)), Code: codes.PermissionDenied},
+		{Name: "REST request of the IDE", Peer: apiPeerSupervisor, Metadata: metadata.Pairs(apiPeerMetadataKey, string(APIPeerIDE)), Code: codes.OK},
+		{Name: "task pretends to be the IDE", Peer: APIPeerTask, Metadata: metadata.Pairs(apiPeerMetadataKey, string(APIPeerIDE)), Code: codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: apiPeerInfo{Peer: test.Peer}})
+			if test.Metadata != nil {
+				ctx = metadata.NewIncomingContext(ctx, test.Metadata)
+			}
+			err := a.authorize(ctx, method)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("unexpected code: want %v, got %v", test.Code, code)
+			}
+		})
+	}
+}
@@ -13,12 +13,17 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+	// the timezone of a workspace is validated independently of the zoneinfo available in the workspace image
+	_ "time/tzdata"
 
 	env "github.com/Netflix/go-env"
 	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/secrets"
 )
 
 const supervisorConfigFile = "supervisor-config.json"
@@ -71,6 +76,10 @@ type StaticConfig struct {
 
 	// APIEndpointPort is the port where to serve the API endpoint on
 	APIEndpointPort int `json:"apiEndpointPort"`
+
+	// FakeTimeLibrary is a path in the filesystem where to find libfaketime. Workspaces can only
+	// use a fake time offset if this is set and their image has glibc.
+	FakeTimeLibrary string `json:"fakeTimeLibrary,omitempty"`
 }
 
 // Validate validates this configuration
@@ -175,12 +184,24 @@ type WorkspaceConfig struct {
 	// Tokens is a JSON encoded list of WorkspaceGitpodToken
 	Tokens string `env:"THEIA_SUPERVISOR_TOKENS"`
 
+	// SSHKeys is a JSON encoded list of WorkspaceSSHKey which supervisor loads into its SSH agent
+	SSHKeys string `env:"THEIA_SUPERVISOR_SSH_KEYS"`
+
 	// WorkspaceID is the ID of the workspace
 	WorkspaceID string `env:"GITPOD_WORKSPACE_ID"`
 
 	// WorkspaceInstanceID is the instance ID of the workspace
 	WorkspaceInstanceID string `env:"GITPOD_INSTANCE_ID"`
 
+	// OwnerID is the ID of the user who owns the workspace
+	OwnerID string `env:"GITPOD_OWNER_ID"`
+
+	// WorkspaceClass is the class of the workspace, e.g. regular or prebuild
+	WorkspaceClass string `env:"GITPOD_WORKSPACE_CLASS"`
+
+	// FeatureFlags is a comma separated list of the feature flags the workspace was started with, e.g. "full_workspace_backup"
+	FeatureFlags string `env:"GITPOD_FEATURE_FLAGS"`
+
 	// GitpodHost points to the Gitpod API server we're to talk to
 	GitpodHost string `env:"GITPOD_HOST"`
 
@@ -189,6 +210,52 @@ type WorkspaceConfig struct {
 
 	// GitpodHeadless controls whether the workspace is running headless
 	GitpodHeadless string `env:"GITPOD_HEADLESS"`
+
+	// Timezone is the IANA timezone (e.g. Europe/Berlin) the IDE and terminals run in
+	Timezone string `env:"GITPOD_TIMEZONE"`
+
+	// FakeTimeOffset shifts the clock the IDE and terminals see, e.g. "+2d" or "-90m".
+	// This is meant for testing time-dependent behaviour and requires libfaketime.
+	FakeTimeOffset string `env:"GITPOD_FAKETIME_OFFSET"`
+
+	// DisablePersistedHome stops supervisor from keeping shell history and other home directory files across workspace restarts
+	DisablePersistedHome bool `env:"GITPOD_DISABLE_PERSISTED_HOME"`
+
+	// PersistedHomeFiles is a colon separated list of additional files, relative to the home directory,
+	// which supervisor keeps across workspace restarts, e.g. ".config/gh/hosts.yml:.psql_history"
+	PersistedHomeFiles string `env:"GITPOD_PERSISTED_HOME_FILES"`
+
+	// SharedCacheDir is the dependency cache which all workspaces of a node share. If set, supervisor fills
+	// the Go and Maven caches from there and publishes the dependencies the workspace downloaded when it stops.
+	SharedCacheDir string `env:"GITPOD_SHARED_CACHE_DIR"`
+
+	// SecretProviders is a JSON encoded map of secrets providers, e.g. {"vault": {"type": "vault", "address": "https://vault.example.com"}},
+	// which tasks fetch the secrets they declare from when they start
+	SecretProviders string `env:"GITPOD_SECRET_PROVIDERS"`
+
+	// APIAccessPolicy is a JSON encoded APIAccessPolicy which restricts the supervisor API methods workspace processes may call.
+	// If empty, every process may call every method.
+	APIAccessPolicy string `env:"GITPOD_API_ACCESS_POLICY"`
+
+	// TerminalRecording is the user's opt-in to recording task terminals. Recordings are kept in the workspace
+	// content and only leave the workspace if the user shares them.
+	TerminalRecording bool `env:"GITPOD_TERMINAL_RECORDING"`
+
+	// LogMasking is a JSON encoded LogMaskingConfig. Secrets are masked in prebuild logs and terminal recordings
+	// unless it disables masking.
+	LogMasking string `env:"GITPOD_LOG_MASKING"`
+
+	// WorkspacePaused is true if ws-manager created the workspace ahead of demand. We initialize the content,
+	// but hold the IDE and the tasks until ws-manager activates the workspace.
+	WorkspacePaused bool `env:"GITPOD_WORKSPACE_PAUSED"`
+
+	// EgressPolicy is the JSON encoded EgressPolicy of the installation. If it or ProjectEgressPolicy is set,
+	// supervisor only lets the workspace connect to the destinations they allow.
+	EgressPolicy string `env:"GITPOD_EGRESS_POLICY"`
+
+	// ProjectEgressPolicy is the JSON encoded EgressPolicy of the project the workspace belongs to. It narrows
+	// the installation policy: the workspace may only connect to destinations both allow. It cannot allow exceptions.
+	ProjectEgressPolicy string `env:"GITPOD_PROJECT_EGRESS_POLICY"`
 }
 
 // WorkspaceGitpodToken is a list of tokens that should be added to supervisor's token service
@@ -197,16 +264,42 @@ type WorkspaceGitpodToken struct {
 	TokenOTS string `json:"tokenOTS"`
 }
 
+// WorkspaceSSHKey is a user-registered SSH key that should be added to supervisor's SSH agent
+type WorkspaceSSHKey struct {
+	// Name is used as comment of the key in the agent
+	Name string `json:"name"`
+	// KeyOTS is a one-time-secret URL from which the PEM encoded private key is downloaded
+	KeyOTS string `json:"keyOTS"`
+
+	// Key is the PEM encoded private key, populated when downloading the OTS
+	Key []byte `json:"-"`
+}
+
 // TaskConfig defines gitpod task shape
 type TaskConfig struct {
-	Name     *string            `json:"name,omitempty"`
-	Before   *string            `json:"before,omitempty"`
-	Init     *string            `json:"init,omitempty"`
-	Prebuild *string            `json:"prebuild,omitempty"`
-	Command  *string            `json:"command,omitempty"`
-	Env      *map[string]string `json:"env,omitempty"`
-	OpenIn   *string            `json:"openIn,omitempty"`
-	OpenMode *string            `json:"openMode,omitempty"`
+	Name     *string             `json:"name,omitempty"`
+	Before   *string             `json:"before,omitempty"`
+	Init     *string             `json:"init,omitempty"`
+	Inputs   *[]string           `json:"inputs,omitempty"`
+	Prebuild *string             `json:"prebuild,omitempty"`
+	Command  *string             `json:"command,omitempty"`
+	Env      *map[string]string  `json:"env,omitempty"`
+	OpenIn   *string             `json:"openIn,omitempty"`
+	OpenMode *string             `json:"openMode,omitempty"`
+	Secrets  *[]TaskSecretConfig `json:"secrets,omitempty"`
+}
+
+// TaskSecretConfig declares a secret a task fetches from a secrets provider when it starts
+type TaskSecretConfig struct {
+	// Provider names the provider in GITPOD_SECRET_PROVIDERS. It may be empty if there is only one provider.
+	Provider string `json:"provider,omitempty"`
+	// Name is the name of the secret the provider knows it by, e.g. secret/data/myapp#password for Vault.
+	// Names ending in #key select a field of secrets which are JSON objects.
+	Name string `json:"name"`
+	// Env is the environment variable the task finds the secret in
+	Env string `json:"env,omitempty"`
+	// File is the name of a file in $GITPOD_SECRETS_DIR the task finds the secret in
+	File string `json:"file,omitempty"`
 }
 
 // Validate validates this configuration
@@ -227,13 +320,191 @@ func (c WorkspaceConfig) Validate() error {
 		return err
 	}
 
+	if _, err := c.GetSSHKeys(false); err != nil {
+		return err
+	}
+
 	if _, _, err := c.GitpodAPIEndpoint(); err != nil {
 		return err
 	}
 
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("GITPOD_TIMEZONE is invalid: %w", err)
+		}
+	}
+
+	if _, err := c.GetFakeTimeOffset(); err != nil {
+		return err
+	}
+
+	if _, err := c.GetPersistedHomeFiles(); err != nil {
+		return err
+	}
+
+	if _, err := c.GetSecretProviders(); err != nil {
+		return err
+	}
+
+	if _, err := c.GetAPIAccessPolicy(); err != nil {
+		return err
+	}
+
+	if _, err := c.GetLogMasking(); err != nil {
+		return err
+	}
+
+	if _, err := c.GetEgressPolicy(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// GetEgressPolicy parses GITPOD_EGRESS_POLICY and narrows it by GITPOD_PROJECT_EGRESS_POLICY. Returns nil if neither is set.
+func (c WorkspaceConfig) GetEgressPolicy() (*EgressPolicy, error) {
+	if c.EgressPolicy == "" && c.ProjectEgressPolicy == "" {
+		return nil, nil
+	}
+
+	var installation, project *EgressPolicy
+	if c.EgressPolicy != "" {
+		err := json.Unmarshal([]byte(c.EgressPolicy), &installation)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse GITPOD_EGRESS_POLICY: %w", err)
+		}
+		err = installation.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress policy: %w", err)
+		}
+	}
+	if c.ProjectEgressPolicy != "" {
+		err := json.Unmarshal([]byte(c.ProjectEgressPolicy), &project)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse GITPOD_PROJECT_EGRESS_POLICY: %w", err)
+		}
+		err = project.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid project egress policy: %w", err)
+		}
+	}
+
+	switch {
+	case installation == nil:
+		// projects cannot allow exceptions
+		return &EgressPolicy{AllowedDomains: project.AllowedDomains, AllowedCIDRs: project.AllowedCIDRs}, nil
+	case project == nil:
+		return installation, nil
+	default:
+		return installation.narrow(project), nil
+	}
+}
+
+// GetLogMasking parses GITPOD_LOG_MASKING. Returns the default configuration if it is empty.
+func (c WorkspaceConfig) GetLogMasking() (*LogMaskingConfig, error) {
+	var res LogMaskingConfig
+	if c.LogMasking == "" {
+		return &res, nil
+	}
+
+	err := json.Unmarshal([]byte(c.LogMasking), &res)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse GITPOD_LOG_MASKING: %w", err)
+	}
+	if err := res.Validate(); err != nil {
+		return nil, fmt.Errorf("GITPOD_LOG_MASKING is invalid: %w", err)
+	}
+	return &res, nil
+}
+
+// GetAPIAccessPolicy parses GITPOD_API_ACCESS_POLICY. Returns nil if there is no policy.
+func (c WorkspaceConfig) GetAPIAccessPolicy() (*APIAccessPolicy, error) {
+	if c.APIAccessPolicy == "" {
+		return nil, nil
+	}
+
+	var res APIAccessPolicy
+	err := json.Unmarshal([]byte(c.APIAccessPolicy), &res)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse GITPOD_API_ACCESS_POLICY: %w", err)
+	}
+	if err := res.Validate(); err != nil {
+		return nil, fmt.Errorf("GITPOD_API_ACCESS_POLICY is invalid: %w", err)
+	}
+	return &res, nil
+}
+
+// GetSecretProviders parses GITPOD_SECRET_PROVIDERS
+func (c WorkspaceConfig) GetSecretProviders() (map[string]secrets.ProviderConfig, error) {
+	if c.SecretProviders == "" {
+		return nil, nil
+	}
+
+	var res map[string]secrets.ProviderConfig
+	err := json.Unmarshal([]byte(c.SecretProviders), &res)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse GITPOD_SECRET_PROVIDERS: %w", err)
+	}
+	for name, p := range res {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("GITPOD_SECRET_PROVIDERS: %s: %w", name, err)
+		}
+	}
+	return res, nil
+}
+
+// GetPersistedHomeFiles parses GITPOD_PERSISTED_HOME_FILES
+func (c WorkspaceConfig) GetPersistedHomeFiles() ([]string, error) {
+	if c.PersistedHomeFiles == "" {
+		return nil, nil
+	}
+
+	var res []string
+	for _, fn := range strings.Split(c.PersistedHomeFiles, ":") {
+		if fn == "" {
+			continue
+		}
+		fn = filepath.Clean(fn)
+		if filepath.IsAbs(fn) || fn == "." || fn == ".." || strings.HasPrefix(fn, "../") {
+			return nil, fmt.Errorf("GITPOD_PERSISTED_HOME_FILES must only contain paths within the home directory: %s", fn)
+		}
+		res = append(res, fn)
+	}
+	return res, nil
+}
+
+// GetFakeTimeOffset parses GITPOD_FAKETIME_OFFSET. Offsets are either Go durations (e.g. -1h30m)
+// or libfaketime style relative offsets (e.g. +2d). Returns zero if no offset is configured.
+func (c WorkspaceConfig) GetFakeTimeOffset() (time.Duration, error) {
+	if c.FakeTimeOffset == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(c.FakeTimeOffset); err == nil {
+		return d, nil
+	}
+
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'y': 365 * 24 * time.Hour,
+	}
+	var (
+		val  = c.FakeTimeOffset
+		unit = time.Second
+	)
+	if u, ok := units[val[len(val)-1]]; ok {
+		unit = u
+		val = val[:len(val)-1]
+	}
+	n, err := strconv.ParseInt(strings.TrimPrefix(val, "+"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("GITPOD_FAKETIME_OFFSET is invalid: %s", c.FakeTimeOffset)
+	}
+	return time.Duration(n) * unit, nil
+}
+
 // GetTokens parses tokens from GITPOD_TOKENS and possibly downloads OTS.
 func (c WorkspaceConfig) GetTokens(downloadOTS bool) ([]WorkspaceGitpodToken, error) {
 	if c.Tokens == "" {
@@ -256,12 +527,7 @@ func (c WorkspaceConfig) GetTokens(downloadOTS bool) ([]WorkspaceGitpodToken, er
 				continue
 			}
 
-			resp, err := client.Get(tks[i].TokenOTS)
-			if err != nil {
-				return nil, fmt.Errorf("cannot download token OTS: %w", err)
-			}
-			tkn, err := io.ReadAll(resp.Body)
-			resp.Body.Close()
+			tkn, err := fetchOTS(&client, tks[i].TokenOTS)
 			if err != nil {
 				return nil, fmt.Errorf("cannot download token OTS: %w", err)
 			}
@@ -272,6 +538,49 @@ func (c WorkspaceConfig) GetTokens(downloadOTS bool) ([]WorkspaceGitpodToken, er
 	return tks, nil
 }
 
+// GetSSHKeys parses SSH keys from THEIA_SUPERVISOR_SSH_KEYS and possibly downloads their OTS.
+func (c WorkspaceConfig) GetSSHKeys(downloadKeys bool) ([]WorkspaceSSHKey, error) {
+	if c.SSHKeys == "" {
+		return nil, nil
+	}
+
+	var keys []WorkspaceSSHKey
+	err := json.Unmarshal([]byte(c.SSHKeys), &keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse SSH keys: %w", err)
+	}
+	for _, k := range keys {
+		if k.KeyOTS == "" {
+			return nil, fmt.Errorf("SSH key %s has no keyOTS", k.Name)
+		}
+	}
+
+	if downloadKeys {
+		client := http.Client{
+			Timeout: 5 * time.Second,
+		}
+
+		for i := range keys {
+			keys[i].Key, err = fetchOTS(&client, keys[i].KeyOTS)
+			if err != nil {
+				return nil, fmt.Errorf("cannot download SSH key OTS: %w", err)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+func fetchOTS(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
 // GitpodAPIEndpoint produces the data required to connect to the Gitpod API
 func (c WorkspaceConfig) GitpodAPIEndpoint() (endpoint, host string, err error) {
 	gphost, err := url.Parse(c.GitpodHost)
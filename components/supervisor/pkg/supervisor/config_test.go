@@ -0,0 +1,113 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetFakeTimeOffset(t *testing.T) {
+	tests := []struct {
+		Offset      string
+		Expectation time.Duration
+		Error       bool
+	}{
+		{Offset: "", Expectation: 0},
+		{Offset: "-1h30m", Expectation: -90 * time.Minute},
+		{Offset: "+2d", Expectation: 48 * time.Hour},
+		{Offset: "-1y", Expectation: -365 * 24 * time.Hour},
+		{Offset: "+120", Expectation: 2 * time.Minute},
+		{Offset: "tomorrow", Error: true},
+		{Offset: "d", Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Offset, func(t *testing.T) {
+			act, err := WorkspaceConfig{FakeTimeOffset: test.Offset}.GetFakeTimeOffset()
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if act != test.Expectation {
+				t.Errorf("unexpected offset: expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestBuildClockEnv(t *testing.T) {
+	tmp := t.TempDir()
+	faketime := filepath.Join(tmp, "libfaketime.so.1")
+	err := os.WriteFile(faketime, nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name        string
+		Config      Config
+		Glibc       bool
+		Expectation []string
+	}{
+		{Name: "empty"},
+		{
+			Name:        "timezone",
+			Config:      Config{WorkspaceConfig: WorkspaceConfig{Timezone: "Europe/Berlin"}},
+			Expectation: []string{"TZ=Europe/Berlin"},
+		},
+		{
+			Name:   "invalid timezone",
+			Config: Config{WorkspaceConfig: WorkspaceConfig{Timezone: "Middle/Earth"}},
+		},
+		{
+			Name:   "offset without libfaketime",
+			Config: Config{WorkspaceConfig: WorkspaceConfig{FakeTimeOffset: "+1d"}},
+		},
+		{
+			Name: "offset",
+			Config: Config{
+				StaticConfig:    StaticConfig{FakeTimeLibrary: faketime},
+				WorkspaceConfig: WorkspaceConfig{FakeTimeOffset: "-1h"},
+			},
+			Glibc:       true,
+			Expectation: []string{"LD_PRELOAD=" + faketime, "FAKETIME=-3600", "FAKETIME_DONT_FAKE_MONOTONIC=1"},
+		},
+		{
+			Name: "offset without glibc",
+			Config: Config{
+				StaticConfig:    StaticConfig{FakeTimeLibrary: faketime},
+				WorkspaceConfig: WorkspaceConfig{FakeTimeOffset: "-1h"},
+			},
+		},
+		{
+			Name: "offset with missing libfaketime",
+			Config: Config{
+				StaticConfig:    StaticConfig{FakeTimeLibrary: filepath.Join(tmp, "missing.so")},
+				WorkspaceConfig: WorkspaceConfig{FakeTimeOffset: "-1h"},
+			},
+			Glibc: true,
+		},
+	}
+	if p, ok := os.LookupEnv("LD_PRELOAD"); ok {
+		os.Unsetenv("LD_PRELOAD")
+		defer os.Setenv("LD_PRELOAD", p)
+	}
+	defer func(loaders []string) { glibcLoaders = loaders }(glibcLoaders)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			glibcLoaders = []string{filepath.Join(tmp, "no-loader")}
+			if test.Glibc {
+				glibcLoaders = append(glibcLoaders, faketime)
+			}
+			act := buildClockEnv(&test.Config)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected environment (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// coreDumpLocation is where the kernel writes the core dumps of workspace processes if ws-daemon captures them
+	coreDumpLocation = "/workspace/.gitpod/cores"
+	// coreDumpCheckInterval is how often we look for new core dumps
+	coreDumpCheckInterval = 10 * time.Second
+)
+
+// CoreDump is a core dump of a workspace process
+type CoreDump struct {
+	Name string    `json:"name"`
+	Path string    `json:"path"`
+	Size int64     `json:"size"`
+	Time time.Time `json:"time"`
+}
+
+// coreDumps surfaces the core dumps ws-daemon captured for this workspace
+type coreDumps struct {
+	Location string
+	Interval time.Duration
+}
+
+func newCoreDumps() *coreDumps {
+	return &coreDumps{
+		Location: coreDumpLocation,
+		Interval: coreDumpCheckInterval,
+	}
+}
+
+// List returns the captured core dumps, newest first. Without captured core dumps the list is empty.
+func (c *coreDumps) List() ([]CoreDump, error) {
+	entries, err := os.ReadDir(c.Location)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res []CoreDump
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !strings.HasPrefix(e.Name(), "core.") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// ws-daemon removed the dump to stay within the limits
+			continue
+		}
+		res = append(res, CoreDump{
+			Name: e.Name(),
+			Path: filepath.Join(c.Location, e.Name()),
+			Size: info.Size(),
+			Time: info.ModTime(),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Time.After(res[j].Time) })
+	return res, nil
+}
+
+// Run logs new core dumps until ctx is done
+func (c *coreDumps) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	seen := make(map[string]struct{})
+	if dumps, _ := c.List(); len(dumps) > 0 {
+		// dumps from before a restart are not news
+		for _, d := range dumps {
+			seen[d.Name] = struct{}{}
+		}
+	}
+
+	t := time.NewTicker(c.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		dumps, err := c.List()
+		if err != nil {
+			log.WithError(err).Warn("cannot list core dumps")
+			continue
+		}
+		for _, d := range dumps {
+			if _, ok := seen[d.Name]; ok {
+				continue
+			}
+			seen[d.Name] = struct{}{}
+			log.WithField("path", d.Path).WithField("size", d.Size).Warn("a workspace process crashed and dumped core")
+		}
+	}
+}
+
+// ServeHTTP lists the core dumps as JSON
+func (c *coreDumps) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	dumps, err := c.List()
+	if err != nil {
+		log.WithError(err).Warn("cannot list core dumps")
+		http.Error(w, "cannot list core dumps", http.StatusInternalServerError)
+		return
+	}
+	if dumps == nil {
+		dumps = []CoreDump{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(dumps)
+}
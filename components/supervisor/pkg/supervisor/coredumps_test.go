@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCoreDumps(t *testing.T) {
+	loc := filepath.Join(t.TempDir(), "cores")
+	c := &coreDumps{Location: loc, Interval: time.Second}
+
+	list := func() []string {
+		rec := httptest.NewRecorder()
+		c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/_supervisor/v1/coredumps", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+		}
+		var dumps []CoreDump
+		err := json.Unmarshal(rec.Body.Bytes(), &dumps)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := []string{}
+		for _, d := range dumps {
+			res = append(res, d.Name)
+		}
+		return res
+	}
+
+	if diff := cmp.Diff([]string{}, list()); diff != "" {
+		t.Errorf("expected no core dumps without a location (-want +got):\n%s", diff)
+	}
+
+	err := os.MkdirAll(loc, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, name := range []string{"core.node.12.1", "core.java.34.2", "notes.txt"} {
+		fn := filepath.Join(loc, name)
+		err = os.WriteFile(fn, []byte("dump"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(time.Duration(i) * time.Minute)
+		err = os.Chtimes(fn, mtime, mtime)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff([]string{"core.java.34.2", "core.node.12.1"}, list()); diff != "" {
+		t.Errorf("unexpected core dumps (-want +got):\n%s", diff)
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/_supervisor/v1/coredumps", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected deletes to be rejected, got status %d", rec.Code)
+	}
+}
@@ -0,0 +1,553 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// egressPath serves the egress policy and the active exceptions to processes in the workspace
+	egressPath = "/_supervisor/egress/v1/"
+	// egressExceptionsPath is where processes in the workspace POST to request a temporary egress exception
+	egressExceptionsPath = egressPath + "exceptions"
+
+	// egressTable is the nftables table we enforce the egress policy with
+	egressTable = "gitpod_egress"
+	// egressRefreshInterval is how often we resolve the allowed domains again
+	egressRefreshInterval = time.Minute
+	// egressResolveTimeout is the time we allow for resolving all allowed domains
+	egressResolveTimeout = 10 * time.Second
+
+	defaultEgressExceptionTTL    = 15 * time.Minute
+	defaultMaxEgressExceptionTTL = time.Hour
+)
+
+// EgressPolicy restricts the destinations processes in the workspace can connect to
+type EgressPolicy struct {
+	// AllowedDomains are host names the workspace may connect to, e.g. github.com. We resolve them periodically
+	// and allow the addresses they resolve to. Wildcards are not supported.
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+	// AllowedCIDRs are networks the workspace may connect to, e.g. 10.0.0.0/8
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+	// Exceptions, if set, lets processes in the workspace request temporary exceptions from the policy
+	Exceptions *EgressExceptionPolicy `json:"exceptions,omitempty"`
+}
+
+// EgressExceptionPolicy configures the temporary exceptions processes in the workspace can request
+type EgressExceptionPolicy struct {
+	// MaxTTL is the longest an exception may last, e.g. 30m. Defaults to one hour.
+	MaxTTL string `json:"maxTTL,omitempty"`
+}
+
+// Validate validates the policy
+func (p *EgressPolicy) Validate() error {
+	for _, d := range p.AllowedDomains {
+		if !isEgressDomain(d) {
+			return fmt.Errorf("allowedDomains: %q is not a host name", d)
+		}
+	}
+	for _, c := range p.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return fmt.Errorf("allowedCIDRs: %w", err)
+		}
+	}
+	if _, err := p.maxExceptionTTL(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// narrow returns a policy which only allows the destinations both p and other allow. We keep the domains
+// both list, and of the networks those which lie within a network of the other policy. The exceptions are those of p.
+// Both policies must be valid.
+func (p *EgressPolicy) narrow(other *EgressPolicy) *EgressPolicy {
+	res := &EgressPolicy{Exceptions: p.Exceptions}
+	for _, d := range p.AllowedDomains {
+		for _, o := range other.AllowedDomains {
+			if strings.EqualFold(d, o) {
+				res.AllowedDomains = append(res.AllowedDomains, d)
+				break
+			}
+		}
+	}
+
+	seen := make(map[string]struct{})
+	for _, c := range p.AllowedCIDRs {
+		_, cn, _ := net.ParseCIDR(c)
+		for _, o := range other.AllowedCIDRs {
+			_, on, _ := net.ParseCIDR(o)
+			// two networks either do not overlap, or one lies within the other
+			var within string
+			switch {
+			case cidrWithin(on, cn):
+				within = o
+			case cidrWithin(cn, on):
+				within = c
+			default:
+				continue
+			}
+			if _, ok := seen[within]; ok {
+				continue
+			}
+			seen[within] = struct{}{}
+			res.AllowedCIDRs = append(res.AllowedCIDRs, within)
+		}
+	}
+	return res
+}
+
+// cidrWithin returns true if inner lies within outer
+func cidrWithin(inner, outer *net.IPNet) bool {
+	innerOnes, innerBits := inner.Mask.Size()
+	outerOnes, outerBits := outer.Mask.Size()
+	return innerBits == outerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+// maxExceptionTTL returns the longest an exception may last, or zero if the policy allows no exceptions
+func (p *EgressPolicy) maxExceptionTTL() (time.Duration, error) {
+	if p.Exceptions == nil {
+		return 0, nil
+	}
+	if p.Exceptions.MaxTTL == "" {
+		return defaultMaxEgressExceptionTTL, nil
+	}
+	ttl, err := time.ParseDuration(p.Exceptions.MaxTTL)
+	if err != nil {
+		return 0, fmt.Errorf("exceptions.maxTTL: %w", err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("exceptions.maxTTL must be positive")
+	}
+	return ttl, nil
+}
+
+// isEgressDomain returns true if d is a plain host name
+func isEgressDomain(d string) bool {
+	if d == "" || len(d) > 253 || net.ParseIP(d) != nil {
+		return false
+	}
+	for _, l := range strings.Split(strings.TrimSuffix(d, "."), ".") {
+		if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		for _, c := range l {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// EgressException temporarily allows connections to a destination the policy does not allow
+type EgressException struct {
+	// Destination is a host name or an IP address
+	Destination string    `json:"destination"`
+	Reason      string    `json:"reason"`
+	Expires     time.Time `json:"expires"`
+}
+
+// egressExceptionRequest is the body of a request for an exception
+type egressExceptionRequest struct {
+	Destination string `json:"destination"`
+	// TTL is how long the exception lasts, e.g. 10m. Defaults to 15 minutes, or the maximum the policy allows if that's shorter.
+	TTL    string `json:"ttl,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// egressStatus is what we serve on egressPath
+type egressStatus struct {
+	Policy     *EgressPolicy      `json:"policy"`
+	Exceptions []*EgressException `json:"exceptions"`
+}
+
+// egressFilter enforces an egress policy with nftables in the network namespace of the workspace. Connections
+// to destinations the policy does not allow are rejected. Because nftables knows nothing about host names
+// we resolve the allowed domains periodically and allow their addresses.
+//
+// The workspace must have its own network namespace in which supervisor may manage nftables, and the nft
+// binary must be available.
+type egressFilter struct {
+	Policy *EgressPolicy
+	// AlwaysAllowed are destinations supervisor needs itself, e.g. the Gitpod host and the DNS servers
+	AlwaysAllowed []string
+
+	// nft runs nft with the script on stdin
+	nft    func(ctx context.Context, script string) error
+	lookup func(ctx context.Context, host string) ([]net.IP, error)
+	now    func() time.Time
+
+	mu         sync.Mutex
+	resolved   map[string][]net.IP
+	exceptions []*EgressException
+}
+
+// newEgressFilter creates a filter for the egress policy of the workspace, or returns nil if there is no policy
+func newEgressFilter(cfg *Config) (*egressFilter, error) {
+	policy, err := cfg.GetEgressPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, nil
+	}
+
+	var always []string
+	if u, err := url.Parse(cfg.GitpodHost); err == nil && u.Hostname() != "" {
+		always = append(always, u.Hostname())
+	}
+	always = append(always, readNameservers("/etc/resolv.conf")...)
+
+	return &egressFilter{
+		Policy:        policy,
+		AlwaysAllowed: always,
+		nft: func(ctx context.Context, script string) error {
+			cmd := exec.CommandContext(ctx, "nft", "-f", "-")
+			cmd.Stdin = strings.NewReader(script)
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("nft failed: %w: %s", err, string(out))
+			}
+			return nil
+		},
+		lookup: func(ctx context.Context, host string) ([]net.IP, error) {
+			addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			res := make([]net.IP, len(addrs))
+			for i, a := range addrs {
+				res[i] = a.IP
+			}
+			return res, nil
+		},
+		now:      time.Now,
+		resolved: make(map[string][]net.IP),
+	}, nil
+}
+
+// readNameservers returns the DNS servers the workspace uses
+func readNameservers(fn string) []string {
+	f, err := os.Open(fn)
+	if err != nil {
+		log.WithError(err).Warn("cannot read DNS servers - egress policy might prevent name resolution")
+		return nil
+	}
+	defer f.Close()
+
+	var res []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" && net.ParseIP(fields[1]) != nil {
+			res = append(res, fields[1])
+		}
+	}
+	return res
+}
+
+// Apply installs the nftables rules which enforce the policy. Workspace processes must not start before the
+// rules are in place.
+func (f *egressFilter) Apply(ctx context.Context) error {
+	if f == nil {
+		return nil
+	}
+
+	f.resolve(ctx)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.nft(ctx, f.ruleset()+f.allowedElements())
+}
+
+// Run keeps the addresses of the allowed domains current until the context is canceled
+func (f *egressFilter) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if f == nil {
+		return
+	}
+
+	t := time.NewTicker(egressRefreshInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		err := f.refresh(ctx)
+		if err != nil {
+			// the rules we applied before still hold - we just don't pick up new addresses
+			log.WithError(err).Error("cannot update egress policy addresses")
+		}
+	}
+}
+
+// refresh resolves the allowed domains and replaces the allowed addresses
+func (f *egressFilter) refresh(ctx context.Context) error {
+	f.resolve(ctx)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.nft(ctx, f.allowedElements())
+}
+
+// resolve looks up all domains we allow, including those of exceptions. If a lookup fails we keep the addresses
+// the domain resolved to before.
+func (f *egressFilter) resolve(ctx context.Context) {
+	f.mu.Lock()
+	domains := append([]string{}, f.Policy.AllowedDomains...)
+	domains = append(domains, f.AlwaysAllowed...)
+	for _, e := range f.activeExceptions() {
+		domains = append(domains, e.Destination)
+	}
+	f.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, egressResolveTimeout)
+	defer cancel()
+
+	res := make(map[string][]net.IP, len(domains))
+	for _, d := range domains {
+		if ip := net.ParseIP(d); ip != nil {
+			res[d] = []net.IP{ip}
+			continue
+		}
+		ips, err := f.lookup(ctx, d)
+		if err != nil {
+			log.WithError(err).WithField("domain", d).Warn("cannot resolve domain of egress policy")
+			f.mu.Lock()
+			ips = f.resolved[d]
+			f.mu.Unlock()
+		}
+		res[d] = ips
+	}
+
+	f.mu.Lock()
+	f.resolved = res
+	f.mu.Unlock()
+}
+
+// ruleset produces the nftables script which (re-)creates our table. Callers must hold mu.
+func (f *egressFilter) ruleset() string {
+	var v4, v6 []string
+	for _, c := range f.Policy.AllowedCIDRs {
+		ip, n, err := net.ParseCIDR(c)
+		if err != nil {
+			continue
+		}
+		if ip.To4() != nil {
+			v4 = append(v4, n.String())
+		} else {
+			v6 = append(v6, n.String())
+		}
+	}
+
+	var b strings.Builder
+	// creating the table before deleting it makes sure the delete succeeds if the table does not exist yet
+	fmt.Fprintf(&b, "table inet %s\ndelete table inet %s\n", egressTable, egressTable)
+	fmt.Fprintf(&b, "table inet %s {\n", egressTable)
+	fmt.Fprintf(&b, "\tset cidrs_v4 { type ipv4_addr; flags interval;%s }\n", nftElements(v4))
+	fmt.Fprintf(&b, "\tset cidrs_v6 { type ipv6_addr; flags interval;%s }\n", nftElements(v6))
+	b.WriteString("\tset allowed_v4 { type ipv4_addr; flags timeout; }\n")
+	b.WriteString("\tset allowed_v6 { type ipv6_addr; flags timeout; }\n")
+	b.WriteString("\tchain output {\n")
+	b.WriteString("\t\ttype filter hook output priority 0; policy accept;\n")
+	b.WriteString("\t\toifname \"lo\" accept\n")
+	b.WriteString("\t\tct state established,related accept\n")
+	b.WriteString("\t\tip daddr @cidrs_v4 accept\n")
+	b.WriteString("\t\tip6 daddr @cidrs_v6 accept\n")
+	b.WriteString("\t\tip daddr @allowed_v4 accept\n")
+	b.WriteString("\t\tip6 daddr @allowed_v6 accept\n")
+	b.WriteString("\t\tlimit rate 10/minute log prefix \"gitpod-egress-denied: \"\n")
+	b.WriteString("\t\tcounter reject with icmpx type admin-prohibited\n")
+	b.WriteString("\t}\n}\n")
+	return b.String()
+}
+
+// allowedElements produces the nftables script which replaces the allowed addresses. Callers must hold mu.
+func (f *egressFilter) allowedElements() string {
+	now := f.now()
+	var (
+		v4, v6   = make(map[string]string), make(map[string]string)
+		timeouts = make(map[string]time.Duration)
+	)
+	add := func(ip net.IP, timeout time.Duration) {
+		var key string
+		if ip4 := ip.To4(); ip4 != nil {
+			key = ip4.String()
+			v4[key] = key
+		} else {
+			key = ip.String()
+			v6[key] = key
+		}
+		// addresses allowed by the policy never time out, those of exceptions only when all their exceptions do
+		if t, ok := timeouts[key]; ok && (t == 0 || t > timeout) {
+			return
+		}
+		timeouts[key] = timeout
+	}
+	for _, d := range append(append([]string{}, f.Policy.AllowedDomains...), f.AlwaysAllowed...) {
+		for _, ip := range f.resolved[d] {
+			add(ip, 0)
+		}
+	}
+	for _, e := range f.exceptions {
+		ttl := e.Expires.Sub(now).Truncate(time.Second)
+		if ttl < time.Second {
+			continue
+		}
+		for _, ip := range f.resolved[e.Destination] {
+			add(ip, ttl)
+		}
+	}
+
+	elements := func(addrs map[string]string) []string {
+		res := make([]string, 0, len(addrs))
+		for a := range addrs {
+			if t := timeouts[a]; t > 0 {
+				res = append(res, fmt.Sprintf("%s timeout %ds", a, int(t.Seconds())))
+			} else {
+				res = append(res, a)
+			}
+		}
+		sort.Strings(res)
+		return res
+	}
+
+	var b bytes.Buffer
+	for _, set := range []struct {
+		Name  string
+		Addrs map[string]string
+	}{{"allowed_v4", v4}, {"allowed_v6", v6}} {
+		fmt.Fprintf(&b, "flush set inet %s %s\n", egressTable, set.Name)
+		if len(set.Addrs) > 0 {
+			fmt.Fprintf(&b, "add element inet %s %s { %s }\n", egressTable, set.Name, strings.Join(elements(set.Addrs), ", "))
+		}
+	}
+	return b.String()
+}
+
+func nftElements(elements []string) string {
+	if len(elements) == 0 {
+		return ""
+	}
+	return " elements = { " + strings.Join(elements, ", ") + " };"
+}
+
+// ServeHTTP serves the policy and grants exceptions to processes in the workspace
+func (f *egressFilter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f == nil {
+		http.Error(w, "this workspace has no egress policy", http.StatusNotFound)
+		return
+	}
+	if !isLocalRequest(r) {
+		http.Error(w, "the egress policy is available from within the workspace only", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.URL.Path == egressExceptionsPath && r.Method == http.MethodPost:
+		f.serveException(w, r)
+	case strings.TrimSuffix(r.URL.Path, "/")+"/" == egressPath && r.Method == http.MethodGet:
+		f.mu.Lock()
+		res := egressStatus{Policy: f.Policy, Exceptions: f.activeExceptions()}
+		f.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// activeExceptions drops expired exceptions and returns the others. Callers must hold mu.
+func (f *egressFilter) activeExceptions() []*EgressException {
+	now := f.now()
+	res := make([]*EgressException, 0, len(f.exceptions))
+	for _, e := range f.exceptions {
+		if e.Expires.After(now) {
+			res = append(res, e)
+		}
+	}
+	f.exceptions = res
+	return res
+}
+
+func (f *egressFilter) serveException(w http.ResponseWriter, r *http.Request) {
+	maxTTL, _ := f.Policy.maxExceptionTTL()
+	if maxTTL == 0 {
+		http.Error(w, "the egress policy does not allow exceptions", http.StatusForbidden)
+		return
+	}
+
+	var req egressExceptionRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot parse request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if net.ParseIP(req.Destination) == nil && !isEgressDomain(req.Destination) {
+		http.Error(w, "destination must be a host name or an IP address", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		http.Error(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+	ttl := defaultEgressExceptionTTL
+	if ttl > maxTTL {
+		ttl = maxTTL
+	}
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 || ttl > maxTTL {
+			http.Error(w, fmt.Sprintf("ttl must be a duration of at most %s", maxTTL), http.StatusBadRequest)
+			return
+		}
+	}
+
+	exception := &EgressException{
+		Destination: req.Destination,
+		Reason:      req.Reason,
+		Expires:     f.now().Add(ttl),
+	}
+	f.mu.Lock()
+	f.exceptions = append(f.activeExceptions(), exception)
+	f.mu.Unlock()
+
+	err = f.refresh(r.Context())
+	if err != nil {
+		f.mu.Lock()
+		for i, e := range f.exceptions {
+			if e == exception {
+				f.exceptions = append(f.exceptions[:i], f.exceptions[i+1:]...)
+				break
+			}
+		}
+		f.mu.Unlock()
+		log.WithError(err).WithField("destination", req.Destination).Error("cannot grant egress exception")
+		http.Error(w, "cannot grant exception", http.StatusInternalServerError)
+		return
+	}
+	log.WithField("destination", exception.Destination).WithField("reason", exception.Reason).WithField("expires", exception.Expires).WithField("remoteAddr", r.RemoteAddr).Warn("granted egress exception")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(exception)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetEgressPolicy(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Installation string
+		Project      string
+		Expectation  *EgressPolicy
+		Error        bool
+	}{
+		{Name: "no policy"},
+		{
+			Name:         "narrowed",
+			Installation: `{"allowedDomains":["github.com","registry.npmjs.org"],"allowedCIDRs":["10.0.0.0/8","fd00::/8"],"exceptions":{"maxTTL":"30m"}}`,
+			Project:      `{"allowedDomains":["Registry.npmjs.org","evil.example.com"],"allowedCIDRs":["10.1.0.0/16","192.168.0.0/16","fd00::/16"],"exceptions":{"maxTTL":"24h"}}`,
+			Expectation: &EgressPolicy{
+				AllowedDomains: []string{"registry.npmjs.org"},
+				AllowedCIDRs:   []string{"10.1.0.0/16", "fd00::/16"},
+				Exceptions:     &EgressExceptionPolicy{MaxTTL: "30m"},
+			},
+		},
+		{
+			Name:         "project allows everything",
+			Installation: `{"allowedDomains":["github.com"],"allowedCIDRs":["10.0.0.0/8","172.16.0.0/12"]}`,
+			Project:      `{"allowedDomains":["github.com","gitlab.com"],"allowedCIDRs":["0.0.0.0/0","::/0"]}`,
+			Expectation: &EgressPolicy{
+				AllowedDomains: []string{"github.com"},
+				AllowedCIDRs:   []string{"10.0.0.0/8", "172.16.0.0/12"},
+			},
+		},
+		{
+			Name:         "project allows nothing the installation allows",
+			Installation: `{"allowedDomains":["github.com"],"allowedCIDRs":["10.0.0.0/8"]}`,
+			Project:      `{"allowedDomains":["gitlab.com"],"allowedCIDRs":["192.168.0.0/16"]}`,
+			Expectation:  &EgressPolicy{},
+		},
+		{Name: "installation only", Installation: `{"allowedDomains":["github.com"]}`, Expectation: &EgressPolicy{AllowedDomains: []string{"github.com"}}},
+		{Name: "project only", Project: `{"allowedCIDRs":["192.168.0.0/16"],"exceptions":{}}`, Expectation: &EgressPolicy{AllowedCIDRs: []string{"192.168.0.0/16"}}},
+		{Name: "wildcard domain", Installation: `{"allowedDomains":["*.github.com"]}`, Error: true},
+		{Name: "invalid CIDR", Project: `{"allowedCIDRs":["10.0.0.0"]}`, Error: true},
+		{Name: "invalid TTL", Installation: `{"exceptions":{"maxTTL":"forever"}}`, Error: true},
+		{Name: "invalid JSON", Installation: `allow all`, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := WorkspaceConfig{EgressPolicy: test.Installation, ProjectEgressPolicy: test.Project}.GetEgressPolicy()
+			if (err != nil) != test.Error {
+				t.Fatalf("error = %v, expected error: %v", err, test.Error)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected policy (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type fakeEgressNFT struct {
+	Scripts []string
+	Err     error
+}
+
+func (f *fakeEgressNFT) Run(ctx context.Context, script string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Scripts = append(f.Scripts, script)
+	return nil
+}
+
+func newTestEgressFilter(policy *EgressPolicy, nft *fakeEgressNFT, now *time.Time) *egressFilter {
+	addrs := map[string][]net.IP{
+		"github.com":       {net.ParseIP("140.82.121.3")},
+		"gitpod.io":        {net.ParseIP("34.111.0.1"), net.ParseIP("2600:1901::1")},
+		"pypi.org":         {net.ParseIP("151.101.0.223")},
+		"files.example.io": {net.ParseIP("140.82.121.3")},
+	}
+	return &egressFilter{
+		Policy:        policy,
+		AlwaysAllowed: []string{"gitpod.io", "10.0.0.10"},
+		nft:           nft.Run,
+		lookup: func(ctx context.Context, host string) ([]net.IP, error) {
+			ips, ok := addrs[host]
+			if !ok {
+				return nil, fmt.Errorf("no such host: %s", host)
+			}
+			return ips, nil
+		},
+		now:      func() time.Time { return *now },
+		resolved: make(map[string][]net.IP),
+	}
+}
+
+func TestEgressFilterApply(t *testing.T) {
+	var (
+		nft = &fakeEgressNFT{}
+		now = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	)
+	f := newTestEgressFilter(&EgressPolicy{AllowedDomains: []string{"github.com", "unknown.example.com"}, AllowedCIDRs: []string{"10.1.2.3/8", "fd00::/8"}}, nft, &now)
+
+	err := f.Apply(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nft.Scripts) != 1 {
+		t.Fatalf("expected a single nft transaction, got %d", len(nft.Scripts))
+	}
+	script := nft.Scripts[0]
+	for _, expected := range []string{
+		"delete table inet gitpod_egress\n",
+		"set cidrs_v4 { type ipv4_addr; flags interval; elements = { 10.0.0.0/8 }; }",
+		"set cidrs_v6 { type ipv6_addr; flags interval; elements = { fd00::/8 }; }",
+		"type filter hook output priority 0; policy accept;",
+		"counter reject with icmpx type admin-prohibited",
+		"flush set inet gitpod_egress allowed_v4\nadd element inet gitpod_egress allowed_v4 { 10.0.0.10, 140.82.121.3, 34.111.0.1 }\n",
+		"flush set inet gitpod_egress allowed_v6\nadd element inet gitpod_egress allowed_v6 { 2600:1901::1 }\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("nft script does not contain %q:\n%s", expected, script)
+		}
+	}
+}
+
+func TestEgressFilterExceptions(t *testing.T) {
+	var (
+		nft = &fakeEgressNFT{}
+		now = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	)
+	f := newTestEgressFilter(&EgressPolicy{AllowedDomains: []string{"github.com"}, Exceptions: &EgressExceptionPolicy{MaxTTL: "30m"}}, nft, &now)
+
+	do := func(method, path, body, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		Name       string
+		Body       string
+		RemoteAddr string
+		Code       int
+	}{
+		{Name: "remote request", Body: `{"destination":"pypi.org","reason":"pip install"}`, RemoteAddr: "10.0.0.1:1234", Code: http.StatusForbidden},
+		{Name: "no reason", Body: `{"destination":"pypi.org"}`, Code: http.StatusBadRequest},
+		{Name: "wildcard", Body: `{"destination":"*.pypi.org","reason":"pip install"}`, Code: http.StatusBadRequest},
+		{Name: "ttl too long", Body: `{"destination":"pypi.org","ttl":"2h","reason":"pip install"}`, Code: http.StatusBadRequest},
+		{Name: "domain", Body: `{"destination":"pypi.org","ttl":"10m","reason":"pip install"}`, Code: http.StatusCreated},
+		{Name: "address", Body: `{"destination":"198.51.100.7","reason":"license server"}`, Code: http.StatusCreated},
+		{Name: "address allowed by the policy", Body: `{"destination":"files.example.io","ttl":"5m","reason":"download"}`, Code: http.StatusCreated},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			remoteAddr := test.RemoteAddr
+			if remoteAddr == "" {
+				remoteAddr = "127.0.0.1:1234"
+			}
+			rec := do(http.MethodPost, egressExceptionsPath, test.Body, remoteAddr)
+			if rec.Code != test.Code {
+				t.Errorf("unexpected status %d, expected %d: %s", rec.Code, test.Code, rec.Body.String())
+			}
+		})
+	}
+
+	script := nft.Scripts[len(nft.Scripts)-1]
+	expected := "add element inet gitpod_egress allowed_v4 { 10.0.0.10, 140.82.121.3, 151.101.0.223 timeout 600s, 198.51.100.7 timeout 900s, 34.111.0.1 }\n"
+	if !strings.Contains(script, expected) {
+		t.Errorf("nft script does not contain %q:\n%s", expected, script)
+	}
+
+	// expired exceptions are gone from the status and the next refresh
+	now = now.Add(11 * time.Minute)
+	rec := do(http.MethodGet, egressPath, "", "127.0.0.1:1234")
+	var status egressStatus
+	err := json.Unmarshal(rec.Body.Bytes(), &status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var destinations []string
+	for _, e := range status.Exceptions {
+		destinations = append(destinations, e.Destination)
+	}
+	if diff := cmp.Diff([]string{"198.51.100.7"}, destinations); diff != "" {
+		t.Errorf("unexpected exceptions (-want +got):\n%s", diff)
+	}
+	err = f.refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script := nft.Scripts[len(nft.Scripts)-1]; strings.Contains(script, "151.101.0.223") || !strings.Contains(script, "198.51.100.7 timeout 240s") {
+		t.Errorf("unexpected addresses after the exception expired:\n%s", script)
+	}
+
+	// exceptions which cannot be enforced are not granted
+	nft.Err = fmt.Errorf("nft is gone")
+	rec = do(http.MethodPost, egressExceptionsPath, `{"destination":"pypi.org","reason":"pip install"}`, "127.0.0.1:1234")
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(f.exceptions) != 1 {
+		t.Errorf("failed exception was kept: %v", f.exceptions)
+	}
+}
+
+func TestEgressFilterNoExceptions(t *testing.T) {
+	now := time.Now()
+	f := newTestEgressFilter(&EgressPolicy{AllowedDomains: []string{"github.com"}}, &fakeEgressNFT{}, &now)
+
+	req := httptest.NewRequest(http.MethodPost, egressExceptionsPath, strings.NewReader(`{"destination":"pypi.org","reason":"pip install"}`))
+	req.RemoteAddr = "127.0.0.1:1234"
+	rec := httptest.NewRecorder()
+	f.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+)
+
+const (
+	// frontendWebsocketPath is where browser-based frontends connect to, relative to the supervisor API
+	frontendWebsocketPath = "/_supervisor/v1/frontend/connect"
+	// frontendPingInterval is how often we ping frontends connected through a WebSocket to notice when they're gone
+	frontendPingInterval = 30 * time.Second
+)
+
+// frontendService lets editor frontends other than the IDE we start register with supervisor.
+// While a frontend is active, it counts as the IDE of the workspace.
+type frontendService struct {
+	Info  *InfoService
+	Ports *ports.Manager
+	Tasks *tasksManager
+	// ScheduledStop notifies frontends before the workspace stops as scheduled
+	ScheduledStop *scheduledStop
+	// SupervisorAddr is the address of the supervisor API frontends should use
+	SupervisorAddr string
+	// Handshakes tells how often ws-proxy needs us to ping frontends to keep their connection open
+	Handshakes *proxyHandshakes
+
+	mu        sync.Mutex
+	seq       uint64
+	frontends map[string]*registeredFrontend
+	// active is ready while there is an active frontend
+	active *ideReadyState
+}
+
+type registeredFrontend struct {
+	Info *api.FrontendInfo
+	// seq orders frontends by the time they registered. The most recent non-passive frontend is the active one.
+	seq uint64
+	// changed receives a notification when the active frontend changes
+	changed chan struct{}
+}
+
+// info returns a copy of the frontend info which stays valid when the active frontend changes. Callers must hold mu.
+func (f *registeredFrontend) info() *api.FrontendInfo {
+	return proto.Clone(f.Info).(*api.FrontendInfo)
+}
+
+func newFrontendService(info *InfoService, portMgmt *ports.Manager, tasks *tasksManager, scheduledStop *scheduledStop, supervisorAddr string) *frontendService {
+	return &frontendService{
+		Info:           info,
+		Ports:          portMgmt,
+		Tasks:          tasks,
+		ScheduledStop:  scheduledStop,
+		SupervisorAddr: supervisorAddr,
+		frontends:      make(map[string]*registeredFrontend),
+		active:         &ideReadyState{cond: sync.NewCond(&sync.Mutex{})},
+	}
+}
+
+// RegisterGRPC registers the gRPC frontend service
+func (s *frontendService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterFrontendServiceServer(srv, s)
+}
+
+// RegisterFrontend registers a frontend and streams updates to it until it disconnects
+func (s *frontendService) RegisterFrontend(req *api.RegisterFrontendRequest, srv api.FrontendService_RegisterFrontendServer) error {
+	return s.serve(srv.Context(), req, srv.Send)
+}
+
+// ListFrontends lists the connected frontends
+func (s *frontendService) ListFrontends(ctx context.Context, req *api.ListFrontendsRequest) (*api.ListFrontendsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &api.ListFrontendsResponse{}
+	for _, f := range s.sortedFrontends() {
+		res.Frontends = append(res.Frontends, f.info())
+	}
+	return res, nil
+}
+
+// serve registers a frontend and sends it updates until ctx is canceled or sending fails
+func (s *frontendService) serve(ctx context.Context, req *api.RegisterFrontendRequest, send func(*api.FrontendUpdate) error) error {
+	if req.Name == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	info, err := s.Info.WorkspaceInfo(ctx, &api.WorkspaceInfoRequest{})
+	if err != nil {
+		return err
+	}
+
+	var portUpdates <-chan []*api.PortsStatus
+	if s.Ports != nil {
+		sub, err := s.Ports.Subscribe()
+		if err == ports.ErrTooManySubscriptions {
+			return status.Error(codes.ResourceExhausted, "too many subscriptions")
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		defer sub.Close()
+		portUpdates = sub.Updates()
+	}
+
+	f := s.register(req)
+	defer s.unregister(f)
+	log := log.WithField("frontend", f.Info.Name).WithField("id", f.Info.Id)
+	log.Info("frontend registered")
+	defer log.Info("frontend disconnected")
+
+	err = send(&api.FrontendUpdate{Update: &api.FrontendUpdate_Context{Context: &api.FrontendContext{
+		Id:             f.Info.Id,
+		Workspace:      info,
+		SupervisorAddr: s.SupervisorAddr,
+	}}})
+	if err != nil {
+		return err
+	}
+
+	var (
+		tasksReady     <-chan struct{}
+		taskUpdates    <-chan []*api.TaskStatus
+		scheduledStops <-chan *api.ScheduledStop
+	)
+	if s.Tasks != nil {
+		tasksReady = s.Tasks.ready
+	}
+	if s.ScheduledStop != nil {
+		var unsubscribe func()
+		scheduledStops, unsubscribe = s.ScheduledStop.Subscribe()
+		defer unsubscribe()
+	}
+	for {
+		var update *api.FrontendUpdate
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tasksReady:
+			tasksReady = nil
+			sub := s.Tasks.Subscribe()
+			if sub == nil {
+				return status.Error(codes.ResourceExhausted, "too many subscriptions")
+			}
+			defer sub.Close()
+			taskUpdates = sub.Updates()
+			continue
+		case <-f.changed:
+			update = &api.FrontendUpdate{Update: &api.FrontendUpdate_Activation{Activation: s.activation(f)}}
+		case p, ok := <-portUpdates:
+			if !ok {
+				portUpdates = nil
+				continue
+			}
+			update = &api.FrontendUpdate{Update: &api.FrontendUpdate_Ports{Ports: &api.PortsStatusResponse{Ports: p}}}
+		case t, ok := <-taskUpdates:
+			if !ok {
+				taskUpdates = nil
+				continue
+			}
+			update = &api.FrontendUpdate{Update: &api.FrontendUpdate_Tasks{Tasks: &api.TasksStatusResponse{Tasks: t}}}
+		case st := <-scheduledStops:
+			update = &api.FrontendUpdate{Update: &api.FrontendUpdate_ScheduledStop{ScheduledStop: st}}
+		}
+
+		err := send(update)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *frontendService) register(req *api.RegisterFrontendRequest) *registeredFrontend {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	f := &registeredFrontend{
+		Info: &api.FrontendInfo{
+			Id:             uuid.New().String(),
+			Name:           req.Name,
+			Version:        req.Version,
+			Passive:        req.Passive,
+			ConnectedSince: time.Now().Format(time.RFC3339),
+		},
+		seq:     s.seq,
+		changed: make(chan struct{}, 1),
+	}
+	s.frontends[f.Info.Id] = f
+	s.updateActive()
+	return f
+}
+
+func (s *frontendService) unregister(f *registeredFrontend) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.frontends, f.Info.Id)
+	s.updateActive()
+}
+
+// updateActive marks the most recently registered non-passive frontend as active and notifies all frontends.
+// Callers must hold mu.
+func (s *frontendService) updateActive() {
+	var active *registeredFrontend
+	for _, f := range s.frontends {
+		if f.Info.Passive {
+			continue
+		}
+		if active == nil || f.seq > active.seq {
+			active = f
+		}
+	}
+	for _, f := range s.frontends {
+		f.Info.Active = f == active
+		select {
+		case f.changed <- struct{}{}:
+		default:
+			// the frontend has a notification pending already
+		}
+	}
+	s.active.Set(active != nil)
+}
+
+// activation describes the active frontend as seen by f
+func (s *frontendService) activation(f *registeredFrontend) *api.FrontendActivation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &api.FrontendActivation{Active: f.Info.Active}
+	for _, o := range s.frontends {
+		if o.Info.Active {
+			res.ActiveFrontend = o.info()
+			break
+		}
+	}
+	return res
+}
+
+// sortedFrontends returns the frontends in the order they registered. Callers must hold mu.
+func (s *frontendService) sortedFrontends() []*registeredFrontend {
+	res := make([]*registeredFrontend, 0, len(s.frontends))
+	for _, f := range s.frontends {
+		res = append(res, f)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].seq < res[j].seq })
+	return res
+}
+
+var frontendUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// ServeHTTP serves the frontend contract over a WebSocket for frontends which cannot speak gRPC.
+// The first message a frontend sends is its RegisterFrontendRequest, all following messages are
+// FrontendUpdates we send.
+func (s *frontendService) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	conn, err := frontendUpgrader.Upgrade(resp, req, nil)
+	if err != nil {
+		// the upgrader has replied with an error already
+		log.WithError(err).Debug("cannot upgrade frontend connection")
+		return
+	}
+	defer conn.Close()
+
+	var reg api.RegisterFrontendRequest
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		return
+	}
+	err = (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader(msg), &reg)
+	if err != nil {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, fmt.Sprintf("invalid registration: %v", err)))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		// we only read to notice when the frontend closes the connection
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	var (
+		writeMu sync.Mutex
+		marshal = jsonpb.Marshaler{}
+	)
+	go func() {
+		interval := s.Handshakes.keepaliveWithin(frontendPingInterval)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				writeMu.Lock()
+				err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(interval))
+				writeMu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err = s.serve(ctx, &reg, func(update *api.FrontendUpdate) error {
+		msg, err := marshal.MarshalToString(update)
+		if err != nil {
+			return err
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(websocket.TextMessage, []byte(msg))
+	})
+	if err != nil {
+		writeMu.Lock()
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, status.Convert(err).Message()))
+		writeMu.Unlock()
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+type testFrontend struct {
+	Updates chan *api.FrontendUpdate
+	cancel  context.CancelFunc
+	done    chan error
+}
+
+func connectTestFrontend(s *frontendService, req *api.RegisterFrontendRequest) *testFrontend {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &testFrontend{
+		Updates: make(chan *api.FrontendUpdate, 10),
+		cancel:  cancel,
+		done:    make(chan error, 1),
+	}
+	go func() {
+		f.done <- s.serve(ctx, req, func(u *api.FrontendUpdate) error {
+			f.Updates <- u
+			return nil
+		})
+	}()
+	return f
+}
+
+func (f *testFrontend) Disconnect() error {
+	f.cancel()
+	return <-f.done
+}
+
+// WaitForActivation waits until the frontend is told that activeFrontend is the active one.
+// Activation updates sent in the meantime can be outdated by the time they are sent, hence we skip them.
+func (f *testFrontend) WaitForActivation(t *testing.T, active bool, activeFrontend string) {
+	var last *api.FrontendActivation
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case u := <-f.Updates:
+			a := u.GetActivation()
+			if a == nil {
+				continue
+			}
+			if a.Active == active && a.ActiveFrontend.GetName() == activeFrontend {
+				return
+			}
+			last = a
+		case <-timeout:
+			t.Fatalf("timeout waiting for activation of %q, last activation: %v", activeFrontend, last)
+		}
+	}
+}
+
+func newTestFrontendService(t *testing.T) *frontendService {
+	cfg := &Config{WorkspaceConfig: WorkspaceConfig{
+		WorkspaceRoot: t.TempDir(),
+		GitpodHost:    "https://gitpod.io",
+		WorkspaceID:   "foobar",
+	}}
+	return newFrontendService(&InfoService{cfg: cfg}, nil, nil, nil, "localhost:22999")
+}
+
+func TestFrontendServiceActivation(t *testing.T) {
+	s := newTestFrontendService(t)
+	if s.active.Get() {
+		t.Fatal("frontend is active without any frontend being registered")
+	}
+
+	first := connectTestFrontend(s, &api.RegisterFrontendRequest{Name: "first"})
+	u := <-first.Updates
+	ctx := u.GetContext()
+	if ctx == nil {
+		t.Fatalf("first update is not the frontend context: %v", u)
+	}
+	if ctx.Workspace.WorkspaceId != "foobar" || ctx.SupervisorAddr != "localhost:22999" || ctx.Id == "" {
+		t.Errorf("unexpected frontend context: %v", ctx)
+	}
+	first.WaitForActivation(t, true, "first")
+	if !s.active.Get() {
+		t.Error("frontend service does not report an active frontend")
+	}
+
+	passive := connectTestFrontend(s, &api.RegisterFrontendRequest{Name: "passive", Passive: true})
+	passive.WaitForActivation(t, false, "first")
+
+	second := connectTestFrontend(s, &api.RegisterFrontendRequest{Name: "second"})
+	second.WaitForActivation(t, true, "second")
+	first.WaitForActivation(t, false, "second")
+
+	resp, err := s.ListFrontends(context.Background(), &api.ListFrontendsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range resp.Frontends {
+		names = append(names, f.Name)
+	}
+	if len(names) != 3 || names[0] != "first" || names[1] != "passive" || names[2] != "second" {
+		t.Errorf("unexpected frontends: %v", names)
+	}
+
+	if err := second.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	first.WaitForActivation(t, true, "first")
+
+	for _, f := range []*testFrontend{first, passive} {
+		if err := f.Disconnect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.active.Get() {
+		t.Error("frontend service reports an active frontend after all frontends disconnected")
+	}
+}
+
+func TestFrontendServiceRequiresName(t *testing.T) {
+	s := newTestFrontendService(t)
+	err := s.serve(context.Background(), &api.RegisterFrontendRequest{}, func(*api.FrontendUpdate) error { return nil })
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bufio"
+	"compress/gzip"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/handshake"
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// supervisorCapabilities are the protocol capabilities supervisor announces to ws-proxy.
+// Supervisor does not close idle connections, hence it asks for no keepalive itself.
+var supervisorCapabilities = handshake.Capabilities{
+	Version:     handshake.Version,
+	Compression: []string{"gzip"},
+}
+
+// proxyHandshakes serves the handshake ws-proxy performs whenever it connects to this supervisor, and remembers
+// what the ws-proxy replicas agreed on.
+type proxyHandshakes struct {
+	mu sync.Mutex
+	// keepalive is the shortest keepalive interval a ws-proxy replica agreed on
+	keepalive time.Duration
+}
+
+func (h *proxyHandshakes) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	handshake.Handler(supervisorCapabilities, h.agree).ServeHTTP(w, req)
+}
+
+func (h *proxyHandshakes) agree(agreement handshake.Agreement) {
+	log.WithField("agreement", agreement).Debug("handshake with ws-proxy")
+
+	k := time.Duration(agreement.KeepaliveInterval)
+	if k == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.keepalive == 0 || k < h.keepalive {
+		h.keepalive = k
+	}
+}
+
+// keepaliveWithin returns how often we need to send keepalives on connections which pass through ws-proxy,
+// but at most max. Connections on which we send them less often might be closed by ws-proxy as idle.
+func (h *proxyHandshakes) keepaliveWithin(max time.Duration) time.Duration {
+	if h == nil {
+		return max
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.keepalive == 0 || h.keepalive > max {
+		return max
+	}
+	return h.keepalive
+}
+
+// compressAPIResponses gzips responses for clients which accept it. Unlike ws-proxy, which cannot compress the
+// supervisor API without buffering its streams, we flush the compressed stream whenever the API flushes.
+// ws-proxy asks for compressed responses only once it agreed on them in the handshake.
+func compressAPIResponses(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !acceptsGzip(req.Header.Get("Accept-Encoding")) || req.Header.Get("Upgrade") != "" {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer func() {
+			err := gw.Close()
+			if err != nil {
+				log.WithError(err).Debug("cannot finish compressed API response")
+			}
+		}()
+		h.ServeHTTP(gw, req)
+	})
+}
+
+func acceptsGzip(acceptEncoding string) bool {
+	for _, enc := range strings.Split(acceptEncoding, ",") {
+		enc = strings.TrimSpace(enc)
+		if idx := strings.Index(enc, ";"); idx >= 0 {
+			if strings.TrimSpace(enc[idx+1:]) == "q=0" {
+				continue
+			}
+			enc = strings.TrimSpace(enc[:idx])
+		}
+		if enc == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter compresses what's written to it unless the handler sets its own content encoding
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	hdr := w.ResponseWriter.Header()
+	if hdr.Get("Content-Encoding") != "" {
+		return
+	}
+	hdr.Set("Content-Encoding", "gzip")
+	hdr.Del("Content-Length")
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if status != http.StatusNoContent && status != http.StatusNotModified {
+		w.decide()
+	} else {
+		w.decided = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	w.decide()
+	if w.gz == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.gz.Write(b)
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("response writer cannot be hijacked")
+	}
+	return h.Hijack()
+}
+
+func (w *gzipResponseWriter) Close() error {
+	if w.gz == nil {
+		return nil
+	}
+	return w.gz.Close()
+}
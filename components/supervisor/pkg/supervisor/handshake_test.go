@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/handshake"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestProxyHandshakesKeepalive(t *testing.T) {
+	var nilHandshakes *proxyHandshakes
+	if act := nilHandshakes.keepaliveWithin(frontendPingInterval); act != frontendPingInterval {
+		t.Errorf("unexpected keepalive without handshakes: %s", act)
+	}
+
+	h := &proxyHandshakes{}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for _, k := range []time.Duration{0, 20 * time.Second, 10 * time.Second, 25 * time.Second} {
+		proxy := handshake.Capabilities{Version: handshake.Version, KeepaliveInterval: util.Duration(k)}
+		_, err := handshake.Do(context.Background(), srv.Client(), srv.URL, proxy)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if act := h.keepaliveWithin(frontendPingInterval); act != 10*time.Second {
+		t.Errorf("expected the shortest agreed keepalive, got %s", act)
+	}
+	if act := h.keepaliveWithin(5 * time.Second); act != 5*time.Second {
+		t.Errorf("expected keepalive to be capped, got %s", act)
+	}
+}
+
+func TestCompressAPIResponses(t *testing.T) {
+	flushed := make(chan struct{})
+	handler := compressAPIResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{\"result\":1}\n"))
+		w.(http.Flusher).Flush()
+		<-flushed
+		_, _ = w.Write([]byte("{\"result\":2}\n"))
+	}))
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	t.Run("streams compressed", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", srv.URL, nil)
+		// setting the header ourselves keeps the transport from decompressing the response
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if enc := resp.Header.Get("Content-Encoding"); enc != "gzip" {
+			t.Fatalf("unexpected content encoding %q", enc)
+		}
+
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := bufio.NewReader(gz)
+		// the first message must arrive before the handler finishes, i.e. the stream is not buffered
+		line, err := lines.ReadString('\n')
+		if err != nil || line != "{\"result\":1}\n" {
+			t.Fatalf("unexpected first message %q: %v", line, err)
+		}
+		close(flushed)
+		rest, err := io.ReadAll(lines)
+		if err != nil || string(rest) != "{\"result\":2}\n" {
+			t.Fatalf("unexpected second message %q: %v", rest, err)
+		}
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", srv.URL, nil)
+		req.Header.Set("Accept-Encoding", "gzip;q=0, identity")
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if enc := resp.Header.Get("Content-Encoding"); enc != "" {
+			t.Errorf("unexpected content encoding %q", enc)
+		}
+		if string(body) != "{\"result\":1}\n{\"result\":2}\n" {
+			t.Errorf("unexpected body %q", body)
+		}
+	})
+}
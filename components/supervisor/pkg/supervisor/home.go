@@ -0,0 +1,189 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// persistedHomeLocation is where we keep the persisted home files. Only /workspace is backed up.
+	persistedHomeLocation = "/workspace/.gitpod/home"
+
+	// persistedHomeSyncInterval is how often we copy the persisted home files to the workspace content.
+	// Anything that changed since the last sync is lost if the workspace is killed abruptly.
+	persistedHomeSyncInterval = 10 * time.Second
+)
+
+// defaultPersistedHomeFiles are the shell and REPL histories we keep across workspace restarts
+var defaultPersistedHomeFiles = []string{
+	".bash_history",
+	".zsh_history",
+	".local/share/fish/fish_history",
+	".python_history",
+	".node_repl_history",
+}
+
+// persistedHome keeps selected files of the home directory, e.g. the shell history, across workspace restarts.
+// The files are copied to the workspace content periodically and restored once the content is ready.
+type persistedHome struct {
+	Home  string
+	Store string
+	Files []string
+
+	mu       sync.Mutex
+	restored bool
+	synced   map[string]time.Time
+}
+
+// newPersistedHome returns nil if the persisted home is disabled
+func newPersistedHome(cfg *Config) *persistedHome {
+	if cfg.DisablePersistedHome {
+		return nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.WithError(err).Warn("cannot determine home directory - not persisting shell history")
+		return nil
+	}
+	files, err := cfg.GetPersistedHomeFiles()
+	if err != nil {
+		log.WithError(err).Warn("invalid persisted home files - only persisting shell history")
+	}
+
+	return &persistedHome{
+		Home:   home,
+		Store:  persistedHomeLocation,
+		Files:  append(append([]string{}, defaultPersistedHomeFiles...), files...),
+		synced: make(map[string]time.Time),
+	}
+}
+
+// Run restores the persisted files once the content is ready and syncs them until the context is canceled
+func (p *persistedHome) Run(ctx context.Context, wg *sync.WaitGroup, contentReady <-chan struct{}) {
+	defer wg.Done()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-contentReady:
+	}
+	p.Restore()
+
+	t := time.NewTicker(persistedHomeSyncInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			p.Sync()
+		}
+	}
+}
+
+// Restore copies the persisted files into the home directory, replacing whatever the workspace image put there
+func (p *persistedHome) Restore() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, fn := range p.Files {
+		var (
+			src = filepath.Join(p.Store, fn)
+			dst = filepath.Join(p.Home, fn)
+		)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		err := copyFileAtomically(src, dst)
+		if err != nil {
+			log.WithError(err).WithField("file", fn).Warn("cannot restore persisted home file")
+			continue
+		}
+		if stat, err := os.Stat(dst); err == nil {
+			p.synced[fn] = stat.ModTime()
+		}
+	}
+	p.restored = true
+}
+
+// Sync copies all files which changed since the last sync to the workspace content.
+// Before the files were restored, Sync does nothing so that we never replace the persisted
+// files with those of the workspace image.
+func (p *persistedHome) Sync() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.restored {
+		return
+	}
+
+	for _, fn := range p.Files {
+		src := filepath.Join(p.Home, fn)
+		stat, err := os.Stat(src)
+		if err != nil || stat.IsDir() {
+			continue
+		}
+		if stat.ModTime().Equal(p.synced[fn]) {
+			continue
+		}
+
+		err = copyFileAtomically(src, filepath.Join(p.Store, fn))
+		if err != nil {
+			log.WithError(err).WithField("file", fn).Warn("cannot persist home file")
+			continue
+		}
+		p.synced[fn] = stat.ModTime()
+	}
+}
+
+// copyFileAtomically copies src to dst such that dst is either the old or the new file, even if we're killed while copying
+func copyFileAtomically(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	err = os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, in)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Sync()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
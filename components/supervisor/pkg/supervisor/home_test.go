@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPersistedHome(t *testing.T) {
+	var (
+		home  = t.TempDir()
+		store = t.TempDir()
+	)
+	write := func(fn, content string) {
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fn, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(fn string) string {
+		c, err := os.ReadFile(fn)
+		if err != nil {
+			return ""
+		}
+		return string(c)
+	}
+
+	write(filepath.Join(home, ".bash_history"), "from image")
+	write(filepath.Join(home, ".zsh_history"), "zsh from image")
+	write(filepath.Join(store, ".bash_history"), "ls\ncd foo\n")
+	write(filepath.Join(store, ".local/share/fish/fish_history"), "- cmd: ls\n")
+
+	p := &persistedHome{
+		Home:   home,
+		Store:  store,
+		Files:  []string{".bash_history", ".zsh_history", ".local/share/fish/fish_history"},
+		synced: make(map[string]time.Time),
+	}
+
+	// syncing before restoring must not overwrite the persisted files
+	p.Sync()
+	if c := read(filepath.Join(store, ".bash_history")); c != "ls\ncd foo\n" {
+		t.Fatalf("sync before restore replaced persisted file: %q", c)
+	}
+
+	p.Restore()
+	if diff := cmp.Diff([]string{"ls\ncd foo\n", "zsh from image", "- cmd: ls\n"}, []string{
+		read(filepath.Join(home, ".bash_history")),
+		read(filepath.Join(home, ".zsh_history")),
+		read(filepath.Join(home, ".local/share/fish/fish_history")),
+	}); diff != "" {
+		t.Errorf("unexpected home content after restore (-want +got):\n%s", diff)
+	}
+
+	// make sure the modification time changes even on file systems with coarse timestamps
+	later := time.Now().Add(time.Minute)
+	write(filepath.Join(home, ".bash_history"), "ls\ncd foo\nmake\n")
+	_ = os.Chtimes(filepath.Join(home, ".bash_history"), later, later)
+	p.Sync()
+	if diff := cmp.Diff([]string{"ls\ncd foo\nmake\n", "zsh from image"}, []string{
+		read(filepath.Join(store, ".bash_history")),
+		read(filepath.Join(store, ".zsh_history")),
+	}); diff != "" {
+		t.Errorf("unexpected persisted content after sync (-want +got):\n%s", diff)
+	}
+
+	var disabled *persistedHome
+	disabled.Restore()
+	disabled.Sync()
+}
+
+func TestGetPersistedHomeFiles(t *testing.T) {
+	tests := []struct {
+		Files       string
+		Expectation []string
+		Error       bool
+	}{
+		{Files: ""},
+		{Files: ".psql_history:.config/gh/hosts.yml", Expectation: []string{".psql_history", ".config/gh/hosts.yml"}},
+		{Files: ".psql_history::./.irb_history", Expectation: []string{".psql_history", ".irb_history"}},
+		{Files: "/etc/passwd", Error: true},
+		{Files: "../.bash_history", Error: true},
+		{Files: "foo/../../.bash_history", Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Files, func(t *testing.T) {
+			act, err := WorkspaceConfig{PersistedHomeFiles: test.Files}.GetPersistedHomeFiles()
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected files (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
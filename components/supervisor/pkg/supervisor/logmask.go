@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gitpod-io/gitpod/supervisor/pkg/logmask"
+)
+
+// secretEnvVarMarkers are parts of environment variable names which suggest that the variable holds a secret
+var secretEnvVarMarkers = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "API_KEY", "APIKEY", "PRIVATE_KEY", "ACCESS_KEY", "CREDENTIAL"}
+
+// LogMaskingConfig configures how we redact secrets from task output before it is written to prebuild logs
+// or terminal recordings. Interactive terminals are never masked.
+type LogMaskingConfig struct {
+	// Disabled turns masking off
+	Disabled bool `json:"disabled,omitempty"`
+	// EnvVars are the names of additional environment variables whose values we mask, e.g. "MY_DB_*".
+	// Variables whose name suggests a secret, e.g. NPM_TOKEN, and the secrets of a task are always masked.
+	EnvVars []string `json:"envVars,omitempty"`
+	// Patterns are additional regular expressions whose matches we mask. If a pattern has a capture group,
+	// only the first group is masked.
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// Validate validates the configuration
+func (c *LogMaskingConfig) Validate() error {
+	for _, n := range c.EnvVars {
+		if _, err := path.Match(n, ""); err != nil {
+			return fmt.Errorf("envVars: %s is not a valid pattern: %w", n, err)
+		}
+	}
+	for _, p := range c.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("patterns: %w", err)
+		}
+	}
+	return nil
+}
+
+// isSecret returns true if the value of the environment variable name must be masked
+func (c *LogMaskingConfig) isSecret(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, m := range secretEnvVarMarkers {
+		if strings.Contains(upper, m) {
+			return true
+		}
+	}
+	for _, n := range c.EnvVars {
+		if ok, _ := path.Match(n, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// newMasker creates the masker for the output of a task. env is the environment of the task on top of
+// that of supervisor, and secrets are the names of the variables the task's secrets went to.
+// Returns nil if masking is disabled.
+func (c *LogMaskingConfig) newMasker(environ []string, env map[string]string, secrets map[string]struct{}) *logmask.Masker {
+	if c == nil || c.Disabled {
+		return nil
+	}
+
+	var values []string
+	for _, e := range environ {
+		segs := strings.SplitN(e, "=", 2)
+		if len(segs) == 2 && c.isSecret(segs[0]) {
+			values = append(values, segs[1])
+		}
+	}
+	for name, value := range env {
+		if _, ok := secrets[name]; ok || c.isSecret(name) {
+			values = append(values, value)
+		}
+	}
+
+	patterns := append([]*regexp.Regexp(nil), logmask.DefaultPatterns...)
+	for _, p := range c.Patterns {
+		// Validate made sure the patterns compile
+		patterns = append(patterns, regexp.MustCompile(p))
+	}
+	return logmask.New(values, patterns)
+}
+
+// taskMasker creates the masker for the output of a task which runs with env
+func (tm *tasksManager) taskMasker(t *task, env map[string]string) *logmask.Masker {
+	secrets := make(map[string]struct{})
+	if t.config.Secrets != nil {
+		for _, s := range *t.config.Secrets {
+			if s.Env != "" {
+				secrets[s.Env] = struct{}{}
+			}
+		}
+	}
+	return tm.logMasking.newMasker(os.Environ(), env, secrets)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetLogMasking(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Config   string
+		Expected *LogMaskingConfig
+		Invalid  bool
+	}{
+		{Name: "default", Expected: &LogMaskingConfig{}},
+		{Name: "disabled", Config: `{"disabled":true}`, Expected: &LogMaskingConfig{Disabled: true}},
+		{Name: "custom", Config: `{"envVars":["DB_*"],"patterns":["sk_live_[a-z0-9]+"]}`, Expected: &LogMaskingConfig{EnvVars: []string{"DB_*"}, Patterns: []string{"sk_live_[a-z0-9]+"}}},
+		{Name: "invalid JSON", Config: `{`, Invalid: true},
+		{Name: "invalid env var pattern", Config: `{"envVars":["DB_["]}`, Invalid: true},
+		{Name: "invalid pattern", Config: `{"patterns":["("]}`, Invalid: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := WorkspaceConfig{LogMasking: test.Config}.GetLogMasking()
+			if test.Invalid {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLogMaskingNewMasker(t *testing.T) {
+	cfg := &LogMaskingConfig{
+		EnvVars:  []string{"DB_*"},
+		Patterns: []string{`sk_live_[a-z0-9]+`},
+	}
+	environ := []string{
+		"NPM_TOKEN=npm-token-value",
+		"GIT_AUTHOR_NAME=Jane Doe",
+		"DB_URL=postgres://db.internal/app",
+	}
+	env := map[string]string{
+		"aws_secret_access_key": "aws-secret-value",
+		"VAULT_FETCHED":         "fetched-secret",
+		"NODE_ENV":              "production",
+	}
+	m := cfg.newMasker(environ, env, map[string]struct{}{"VAULT_FETCHED": {}})
+
+	input := "npm-token-value Jane Doe postgres://db.internal/app aws-secret-value fetched-secret production sk_live_abc123"
+	expected := "[masked] Jane Doe [masked] [masked] [masked] production [masked]"
+	if diff := cmp.Diff(expected, string(m.Mask([]byte(input)))); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	if m := (&LogMaskingConfig{Disabled: true}).newMasker(environ, env, nil); m != nil {
+		t.Error("expected no masker if masking is disabled")
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// metadataTokenPath is where processes in the workspace PUT to obtain a metadata token, relative to the supervisor API
+	metadataTokenPath = "/_supervisor/metadata/token"
+	// metadataPath serves the workspace metadata document at its root and single fields below it, e.g. /_supervisor/metadata/v1/branch
+	metadataPath = "/_supervisor/metadata/v1/"
+
+	// metadataScopesHeader lists the scopes a token is requested for, e.g. "workspace,repo"
+	metadataScopesHeader = "X-Gitpod-Metadata-Scopes"
+	// metadataTTLHeader is the lifetime of a requested token in seconds
+	metadataTTLHeader = "X-Gitpod-Metadata-Token-TTL-Seconds"
+	// metadataTokenHeader carries the token of a metadata request
+	metadataTokenHeader = "X-Gitpod-Metadata-Token"
+
+	defaultMetadataTokenTTL = 6 * time.Hour
+	maxMetadataTokenTTL     = 24 * time.Hour
+
+	// gitMetadataTimeout is the time we allow Git to tell us about the repository
+	gitMetadataTimeout = 5 * time.Second
+)
+
+// MetadataScope grants access to a group of metadata fields
+type MetadataScope string
+
+const (
+	// MetadataScopeWorkspace grants access to the workspace and instance ID and the workspace class
+	MetadataScopeWorkspace MetadataScope = "workspace"
+	// MetadataScopeOwner grants access to the ID of the workspace owner
+	MetadataScopeOwner MetadataScope = "owner"
+	// MetadataScopeRepo grants access to the repository and branch the workspace works on
+	MetadataScopeRepo MetadataScope = "repo"
+	// MetadataScopePorts grants access to the ports of the workspace
+	MetadataScopePorts MetadataScope = "ports"
+	// MetadataScopeFeatures grants access to the feature flags the workspace was started with
+	MetadataScopeFeatures MetadataScope = "features"
+)
+
+var metadataScopes = map[MetadataScope]struct{}{
+	MetadataScopeWorkspace: {},
+	MetadataScopeOwner:     {},
+	MetadataScopeRepo:      {},
+	MetadataScopePorts:     {},
+	MetadataScopeFeatures:  {},
+}
+
+// WorkspaceMetadata describes the workspace to tools which run in it, irrespective of the environment
+// they were started in. Fields the token of a request has no scope for are empty.
+type WorkspaceMetadata struct {
+	WorkspaceID  string          `json:"workspaceId,omitempty"`
+	InstanceID   string          `json:"instanceId,omitempty"`
+	Class        string          `json:"class,omitempty"`
+	OwnerID      string          `json:"ownerId,omitempty"`
+	Repo         string          `json:"repo,omitempty"`
+	Branch       string          `json:"branch,omitempty"`
+	Ports        []MetadataPort  `json:"ports,omitempty"`
+	FeatureFlags []string        `json:"featureFlags,omitempty"`
+	Scopes       []MetadataScope `json:"scopes"`
+}
+
+// MetadataPort is a port of the workspace
+type MetadataPort struct {
+	Port       uint32 `json:"port"`
+	Served     bool   `json:"served"`
+	URL        string `json:"url,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+type metadataToken struct {
+	Scopes  map[MetadataScope]struct{}
+	Expires time.Time
+}
+
+// metadataService serves workspace metadata to processes in the workspace, much like cloud instance metadata.
+// Processes first PUT to obtain a token for the scopes they need, and present it when they GET the metadata.
+// Requests which do not originate from within the workspace are rejected.
+type metadataService struct {
+	Config *Config
+	Ports  interface{ Status() []*api.PortsStatus }
+
+	// git runs Git in the repository and returns its trimmed output
+	git func(ctx context.Context, args ...string) (string, error)
+	now func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]*metadataToken
+}
+
+func newMetadataService(cfg *Config, ports interface{ Status() []*api.PortsStatus }) *metadataService {
+	return &metadataService{
+		Config: cfg,
+		Ports:  ports,
+		git: func(ctx context.Context, args ...string) (string, error) {
+			out, err := exec.CommandContext(ctx, "git", append([]string{"-C", cfg.RepoRoot}, args...)...).Output()
+			return strings.TrimSpace(string(out)), err
+		},
+		now:    time.Now,
+		tokens: make(map[string]*metadataToken),
+	}
+}
+
+// ServeHTTP issues metadata tokens and serves the metadata
+func (s *metadataService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isLocalRequest(r) {
+		http.Error(w, "metadata is available from within the workspace only", http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Path == metadataTokenPath {
+		if r.Method != http.MethodPut {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		s.serveToken(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	tkn, ok := s.lookupToken(r.Header.Get(metadataTokenHeader))
+	if !ok {
+		http.Error(w, "missing or invalid metadata token", http.StatusUnauthorized)
+		return
+	}
+	md := s.metadata(r.Context(), tkn.Scopes)
+
+	field := strings.Trim(strings.TrimPrefix(r.URL.Path, metadataPath), "/")
+	if field == "" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(md)
+		return
+	}
+	s.serveField(w, md, field)
+}
+
+// isLocalRequest returns true if the request originates from within the workspace, and not e.g. from ws-proxy
+func isLocalRequest(r *http.Request) bool {
+	if r.Header.Get("X-Forwarded-For") != "" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *metadataService) serveToken(w http.ResponseWriter, r *http.Request) {
+	scopes := make(map[MetadataScope]struct{})
+	for _, sc := range strings.Split(r.Header.Get(metadataScopesHeader), ",") {
+		sc = strings.TrimSpace(sc)
+		if sc == "" {
+			continue
+		}
+		if _, ok := metadataScopes[MetadataScope(sc)]; !ok {
+			http.Error(w, fmt.Sprintf("unknown scope %q", sc), http.StatusBadRequest)
+			return
+		}
+		scopes[MetadataScope(sc)] = struct{}{}
+	}
+	if len(scopes) == 0 {
+		http.Error(w, fmt.Sprintf("%s header is required", metadataScopesHeader), http.StatusBadRequest)
+		return
+	}
+
+	ttl := defaultMetadataTokenTTL
+	if v := r.Header.Get(metadataTTLHeader); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs <= 0 || time.Duration(secs)*time.Second > maxMetadataTokenTTL {
+			http.Error(w, fmt.Sprintf("%s must be between 1 and %d", metadataTTLHeader, int(maxMetadataTokenTTL.Seconds())), http.StatusBadRequest)
+			return
+		}
+		ttl = time.Duration(secs) * time.Second
+	}
+
+	var b [32]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		log.WithError(err).Error("cannot generate metadata token")
+		http.Error(w, "cannot generate token", http.StatusInternalServerError)
+		return
+	}
+	tkn := hex.EncodeToString(b[:])
+
+	s.mu.Lock()
+	now := s.now()
+	for k, t := range s.tokens {
+		if now.After(t.Expires) {
+			delete(s.tokens, k)
+		}
+	}
+	s.tokens[tkn] = &metadataToken{Scopes: scopes, Expires: now.Add(ttl)}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set(metadataTTLHeader, strconv.Itoa(int(ttl.Seconds())))
+	_, _ = w.Write([]byte(tkn))
+}
+
+func (s *metadataService) lookupToken(tkn string) (*metadataToken, bool) {
+	if tkn == "" {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[tkn]
+	if !ok || s.now().After(t.Expires) {
+		return nil, false
+	}
+	return t, true
+}
+
+// metadata produces the metadata the scopes grant access to
+func (s *metadataService) metadata(ctx context.Context, scopes map[MetadataScope]struct{}) *WorkspaceMetadata {
+	res := &WorkspaceMetadata{}
+	for sc := range scopes {
+		res.Scopes = append(res.Scopes, sc)
+	}
+	sort.Slice(res.Scopes, func(i, j int) bool { return res.Scopes[i] < res.Scopes[j] })
+
+	if _, ok := scopes[MetadataScopeWorkspace]; ok {
+		res.WorkspaceID = s.Config.WorkspaceID
+		res.InstanceID = s.Config.WorkspaceInstanceID
+		res.Class = s.Config.WorkspaceClass
+	}
+	if _, ok := scopes[MetadataScopeOwner]; ok {
+		res.OwnerID = s.Config.OwnerID
+	}
+	if _, ok := scopes[MetadataScopeRepo]; ok && s.Config.RepoRoot != "" {
+		ctx, cancel := context.WithTimeout(ctx, gitMetadataTimeout)
+		defer cancel()
+		if branch, err := s.git(ctx, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+			res.Branch = branch
+		}
+		if repo, err := s.git(ctx, "remote", "get-url", "origin"); err == nil {
+			res.Repo = stripURLCredentials(repo)
+		}
+	}
+	if _, ok := scopes[MetadataScopePorts]; ok && s.Ports != nil {
+		for _, p := range s.Ports.Status() {
+			mp := MetadataPort{Port: p.LocalPort, Served: p.Served}
+			if p.Exposed != nil {
+				mp.URL = p.Exposed.Url
+				mp.Visibility = strings.ToLower(p.Exposed.Visibility.String())
+			}
+			res.Ports = append(res.Ports, mp)
+		}
+	}
+	if _, ok := scopes[MetadataScopeFeatures]; ok && s.Config.FeatureFlags != "" {
+		res.FeatureFlags = strings.Split(s.Config.FeatureFlags, ",")
+	}
+	return res
+}
+
+// stripURLCredentials removes the user info from Git remote URLs, which may contain tokens
+func stripURLCredentials(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.Scheme == "" {
+		// e.g. scp-like SSH remotes such as git@github.com:gitpod-io/gitpod.git
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
+// serveField serves a single field of the metadata: strings as plain text, everything else as JSON
+func (s *metadataService) serveField(w http.ResponseWriter, md *WorkspaceMetadata, field string) {
+	var fields map[string]json.RawMessage
+	raw, err := json.Marshal(md)
+	if err == nil {
+		err = json.Unmarshal(raw, &fields)
+	}
+	if err != nil {
+		http.Error(w, "cannot serialize metadata", http.StatusInternalServerError)
+		return
+	}
+	v, ok := fields[field]
+	if !ok {
+		http.Error(w, fmt.Sprintf("no field %q or the token has no scope for it", field), http.StatusNotFound)
+		return
+	}
+
+	var str string
+	if json.Unmarshal(v, &str) == nil {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(str))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(v)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/otelcol"
+)
+
+// otelCollector runs the workspace's OpenTelemetry collector while .gitpod.yml configures a backend.
+// It listens on the port OTLP/HTTP exporters send to by default, so that applications need no configuration.
+type otelCollector struct {
+	Addr      string
+	Collector *otelcol.Collector
+}
+
+func newOTelCollector() *otelCollector {
+	return &otelCollector{
+		Addr:      fmt.Sprintf("localhost:%d", otelcol.DefaultPort),
+		Collector: otelcol.New(otelcol.DefaultQueueSize),
+	}
+}
+
+// Run configures the collector whenever .gitpod.yml changes. We only start listening once a backend
+// is configured, so that workspaces which don't use the collector keep the port free.
+func (c *otelCollector) Run(ctx context.Context, wg *sync.WaitGroup, configService gitpod.ConfigInterface) {
+	defer wg.Done()
+
+	var srv *http.Server
+	defer func() {
+		if srv == nil {
+			return
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+		log.WithField("stats", c.Collector.Stats()).Info("OpenTelemetry collector stopped")
+	}()
+
+	configs, errs := configService.Observe(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-errs:
+			if err != nil {
+				log.WithError(err).Debug("cannot read OpenTelemetry collector config")
+			}
+		case config := <-configs:
+			cfg := otelConfigFromGitpodConfig(config, os.Getenv)
+			if cfg != nil {
+				if err := cfg.Validate(); err != nil {
+					log.WithError(err).Warn("invalid otel config in .gitpod.yml - not forwarding traces and logs")
+					cfg = nil
+				}
+			}
+			c.Collector.Configure(cfg)
+			if cfg == nil || srv != nil {
+				continue
+			}
+
+			l, err := net.Listen("tcp", c.Addr)
+			if err != nil {
+				// e.g. because the user runs their own collector
+				log.WithError(err).WithField("addr", c.Addr).Warn("cannot start OpenTelemetry collector")
+				continue
+			}
+			srv = &http.Server{Handler: c.Collector}
+			go func() {
+				err := srv.Serve(l)
+				if err != nil && err != http.ErrServerClosed {
+					log.WithError(err).Error("OpenTelemetry collector failed")
+				}
+			}()
+			go c.Collector.Run(ctx)
+			log.WithField("addr", c.Addr).WithField("endpoint", cfg.Endpoint).Info("started OpenTelemetry collector")
+		}
+	}
+}
+
+// otelConfigFromGitpodConfig produces the collector config from .gitpod.yml, expanding environment variables
+// so that users can keep API keys in their Gitpod environment variables. It returns nil if there's no backend configured.
+func otelConfigFromGitpodConfig(config *gitpod.GitpodConfig, getenv func(string) string) *otelcol.Config {
+	if config == nil || config.Otel == nil {
+		return nil
+	}
+
+	cfg := &otelcol.Config{
+		Endpoint: os.Expand(config.Otel.Endpoint, getenv),
+		Signals:  config.Otel.Signals,
+	}
+	if len(config.Otel.Headers) > 0 {
+		cfg.Headers = make(map[string]string, len(config.Otel.Headers))
+		for k, v := range config.Otel.Headers {
+			cfg.Headers[k] = os.Expand(v, getenv)
+		}
+	}
+	return cfg
+}
+
+// buildOTelEnv points the OpenTelemetry SDKs of the applications in the workspace at the collector, unless the user
+// configured them differently. The SDKs default to this endpoint anyway, but not all of them to OTLP/HTTP.
+func buildOTelEnv(cfg *Config) []string {
+	var env []string
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		env = append(env, fmt.Sprintf("OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:%d", otelcol.DefaultPort))
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL") == "" {
+		env = append(env, "OTEL_EXPORTER_OTLP_PROTOCOL=http/protobuf")
+	}
+	if cfg.WorkspaceID != "" {
+		attrs := "gitpod.workspace.id=" + cfg.WorkspaceID
+		if a := os.Getenv("OTEL_RESOURCE_ATTRIBUTES"); a != "" {
+			attrs = a + "," + attrs
+		}
+		env = append(env, "OTEL_RESOURCE_ATTRIBUTES="+attrs)
+	}
+	return env
+}
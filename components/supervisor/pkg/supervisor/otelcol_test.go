@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/otelcol"
+)
+
+func TestOTelConfigFromGitpodConfig(t *testing.T) {
+	env := map[string]string{
+		"OTEL_BACKEND":      "https://api.honeycomb.io",
+		"HONEYCOMB_API_KEY": "secret",
+	}
+	tests := []struct {
+		Name        string
+		Config      *gitpod.GitpodConfig
+		Expectation *otelcol.Config
+	}{
+		{Name: "no config"},
+		{Name: "no otel config", Config: &gitpod.GitpodConfig{}},
+		{
+			Name: "env vars",
+			Config: &gitpod.GitpodConfig{Otel: &gitpod.Otel{
+				Endpoint: "${OTEL_BACKEND}",
+				Headers:  map[string]string{"x-honeycomb-team": "$HONEYCOMB_API_KEY", "x-honeycomb-dataset": "my-app"},
+				Signals:  []string{otelcol.SignalTraces},
+			}},
+			Expectation: &otelcol.Config{
+				Endpoint: "https://api.honeycomb.io",
+				Headers:  map[string]string{"x-honeycomb-team": "secret", "x-honeycomb-dataset": "my-app"},
+				Signals:  []string{otelcol.SignalTraces},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := otelConfigFromGitpodConfig(test.Config, func(k string) string { return env[k] })
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type testConfigService struct {
+	configs chan *gitpod.GitpodConfig
+}
+
+func (s *testConfigService) Observe(ctx context.Context) (<-chan *gitpod.GitpodConfig, <-chan error) {
+	return s.configs, make(chan error)
+}
+
+func TestOTelCollectorRun(t *testing.T) {
+	received := make(chan string, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.Path
+	}))
+	defer backend.Close()
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	configService := &testConfigService{configs: make(chan *gitpod.GitpodConfig)}
+	col := &otelCollector{Addr: addr, Collector: otelcol.New(1)}
+	go col.Run(ctx, &wg, configService)
+
+	// without a backend we don't listen
+	configService.configs <- &gitpod.GitpodConfig{}
+	if _, err := http.Post("http://"+addr+"/v1/traces", "application/x-protobuf", nil); err == nil {
+		t.Errorf("collector listens without a backend")
+	}
+
+	configService.configs <- &gitpod.GitpodConfig{Otel: &gitpod.Otel{Endpoint: backend.URL}}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = http.Post("http://"+addr+"/v1/traces", "application/x-protobuf", strings.NewReader("spans"))
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("collector does not listen: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	select {
+	case path := <-received:
+		if path != "/v1/traces" {
+			t.Errorf("export was forwarded to %s", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("export was not forwarded")
+	}
+
+	cancel()
+	wg.Wait()
+}
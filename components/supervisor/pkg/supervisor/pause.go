@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// resumePath is where ws-manager activates a paused workspace, relative to the supervisor API
+	resumePath = "/_supervisor/v1/resume"
+)
+
+// PauseState is the pause state as ws-manager sees it
+type PauseState struct {
+	// Paused is true while we hold the IDE and the tasks
+	Paused bool `json:"paused"`
+}
+
+// pauseGate holds the IDE and the tasks of a workspace ws-manager created ahead of demand until ws-manager activates it.
+// The workspace content is initialized in the meantime, so that the workspace is ready within moments of its activation.
+type pauseGate struct {
+	once    sync.Once
+	resumed chan struct{}
+}
+
+func newPauseGate(paused bool) *pauseGate {
+	res := &pauseGate{resumed: make(chan struct{})}
+	if !paused {
+		close(res.resumed)
+	}
+	return res
+}
+
+// Resumed is closed once the workspace was activated, or right away if it never was paused
+func (g *pauseGate) Resumed() <-chan struct{} {
+	return g.resumed
+}
+
+// Paused returns true while the workspace waits to be activated
+func (g *pauseGate) Paused() bool {
+	select {
+	case <-g.resumed:
+		return false
+	default:
+		return true
+	}
+}
+
+// Resume activates the workspace. Returns false if the workspace was not paused.
+func (g *pauseGate) Resume() (resumed bool) {
+	g.once.Do(func() {
+		if g.Paused() {
+			close(g.resumed)
+			resumed = true
+		}
+	})
+	return
+}
+
+// ServeHTTP serves the pause state: ws-manager POSTs to activate the workspace and GETs the state.
+// Activating a workspace which is not paused (anymore) succeeds, so that ws-manager can retry safely.
+func (g *pauseGate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if g.Resume() {
+			log.Info("workspace activated - starting IDE and tasks")
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(PauseState{Paused: g.Paused()})
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPauseGate(t *testing.T) {
+	request := func(g *pauseGate, method string) (int, PauseState) {
+		rec := httptest.NewRecorder()
+		g.ServeHTTP(rec, httptest.NewRequest(method, resumePath, nil))
+		var st PauseState
+		if rec.Code == http.StatusOK {
+			err := json.NewDecoder(rec.Body).Decode(&st)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rec.Code, st
+	}
+
+	g := newPauseGate(false)
+	if g.Paused() || g.Resume() {
+		t.Error("workspace which was never paused is paused")
+	}
+
+	g = newPauseGate(true)
+	if code, st := request(g, http.MethodGet); code != http.StatusOK || !st.Paused {
+		t.Errorf("expected paused workspace, got %d %v", code, st)
+	}
+	select {
+	case <-g.Resumed():
+		t.Fatal("paused workspace is resumed")
+	default:
+	}
+	if code, _ := request(g, http.MethodDelete); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected DELETE not to be allowed, got %d", code)
+	}
+
+	// ws-manager retries the activation until it knows that we received it
+	for i := 0; i < 2; i++ {
+		if code, st := request(g, http.MethodPost); code != http.StatusOK || st.Paused {
+			t.Errorf("expected activated workspace, got %d %v", code, st)
+		}
+	}
+	select {
+	case <-g.Resumed():
+	default:
+		t.Fatal("activated workspace is not resumed")
+	}
+}
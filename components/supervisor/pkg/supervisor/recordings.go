@@ -0,0 +1,304 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+const (
+	// recordingLocation is where we keep the recordings of task terminals. It is part of the workspace content,
+	// hence content-service backs the recordings up with the workspace.
+	recordingLocation = "/workspace/.gitpod/recordings"
+	// recordingSizeLimit is the size after which we stop recording a terminal
+	recordingSizeLimit = 64 << 20
+	// recordingChunkSize is the size of the chunks GetRecording streams
+	recordingChunkSize = 32 << 10
+)
+
+// recordingName matches the names of recordings, e.g. task-0-20211017T101500Z.cast
+var recordingName = regexp.MustCompile(`^task-([0-9]+)-([0-9]{8}T[0-9]{6}Z)\.cast$`)
+
+// recordingService records task terminals if the user opted in and provides the recordings
+type recordingService struct {
+	Location string
+	// Enabled is true if the user opted into recording task terminals
+	Enabled bool
+	// WorkspaceID namespaces the blobs shared recordings are uploaded as
+	WorkspaceID string
+	// Gitpod is the API we share recordings through. If nil, recordings cannot be shared.
+	Gitpod gitpod.APIInterface
+
+	mu     sync.Mutex
+	active map[string]struct{}
+	now    func() time.Time
+}
+
+func newRecordingService(cfg *Config, gitpodService *gitpod.APIoverJSONRPC) *recordingService {
+	res := &recordingService{
+		Location:    recordingLocation,
+		Enabled:     cfg.TerminalRecording,
+		WorkspaceID: cfg.WorkspaceID,
+		active:      make(map[string]struct{}),
+		now:         time.Now,
+	}
+	if gitpodService != nil {
+		res.Gitpod = gitpodService
+	}
+	return res
+}
+
+// RegisterGRPC registers the gRPC recording service
+func (s *recordingService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterRecordingServiceServer(srv, s)
+}
+
+// Record starts a recording of the terminal of a task. It returns nil if the user did not opt into recordings.
+func (s *recordingService) Record(taskID, title string) (*terminal.CastRecorder, error) {
+	if !s.Enabled {
+		return nil, nil
+	}
+
+	err := os.MkdirAll(s.Location, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create recording location: %w", err)
+	}
+	start := s.now().UTC()
+	name := fmt.Sprintf("task-%s-%s.cast", taskID, start.Format("20060102T150405Z"))
+	f, err := os.OpenFile(filepath.Join(s.Location, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create recording: %w", err)
+	}
+
+	s.mu.Lock()
+	s.active[name] = struct{}{}
+	s.mu.Unlock()
+
+	rec, err := terminal.NewCastRecorder(&recordingFile{File: f, done: func() {
+		s.mu.Lock()
+		delete(s.active, name)
+		s.mu.Unlock()
+	}}, terminal.CastHeader{
+		Timestamp: start.Unix(),
+		Title:     title,
+	})
+	if err != nil {
+		f.Close()
+		return nil, xerrors.Errorf("cannot start recording: %w", err)
+	}
+	rec.Limit = recordingSizeLimit
+	log.WithField("recording", name).Info("recording task terminal")
+	return rec, nil
+}
+
+// recordingFile marks a recording as inactive once it is closed
+type recordingFile struct {
+	*os.File
+	done func()
+}
+
+func (f *recordingFile) Close() error {
+	defer f.done()
+	return f.File.Close()
+}
+
+// ListRecordings lists the recordings of this workspace, newest first
+func (s *recordingService) ListRecordings(ctx context.Context, req *api.ListRecordingsRequest) (*api.ListRecordingsResponse, error) {
+	entries, err := os.ReadDir(s.Location)
+	if os.IsNotExist(err) {
+		return &api.ListRecordingsResponse{}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list recordings: %v", err)
+	}
+
+	var res []*api.Recording
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !recordingName.MatchString(e.Name()) {
+			continue
+		}
+		rec, err := s.describe(e.Name())
+		if err != nil {
+			log.WithError(err).WithField("recording", e.Name()).Debug("cannot describe recording")
+			continue
+		}
+		res = append(res, rec)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		ti, tj := res[i].StartedAt.AsTime(), res[j].StartedAt.AsTime()
+		if ti.Equal(tj) {
+			return res[i].Name < res[j].Name
+		}
+		return ti.After(tj)
+	})
+	return &api.ListRecordingsResponse{Recordings: res}, nil
+}
+
+func (s *recordingService) describe(name string) (*api.Recording, error) {
+	m := recordingName.FindStringSubmatch(name)
+	if m == nil {
+		return nil, xerrors.Errorf("not a recording: %s", name)
+	}
+	fn := filepath.Join(s.Location, name)
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var hdr terminal.CastHeader
+	line, err := bufio.NewReader(io.LimitReader(f, 64<<10)).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	err = json.Unmarshal(line, &hdr)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid recording header: %w", err)
+	}
+	startedAt, err := ptypes.TimestampProto(time.Unix(hdr.Timestamp, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	_, active := s.active[name]
+	s.mu.Unlock()
+
+	blob, _ := os.ReadFile(fn + ".blob")
+	return &api.Recording{
+		Name:      name,
+		TaskId:    m[1],
+		Title:     hdr.Title,
+		Size:      info.Size(),
+		StartedAt: startedAt,
+		Active:    active,
+		Blob:      strings.TrimSpace(string(blob)),
+	}, nil
+}
+
+// open opens the recording of a request, making sure it does not escape the recording location
+func (s *recordingService) open(name string) (*os.File, error) {
+	if !recordingName.MatchString(name) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid recording name %q", name)
+	}
+	f, err := os.Open(filepath.Join(s.Location, name))
+	if os.IsNotExist(err) {
+		return nil, status.Errorf(codes.NotFound, "recording %s does not exist", name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot open recording: %v", err)
+	}
+	return f, nil
+}
+
+// GetRecording streams a recording in the asciicast v2 format
+func (s *recordingService) GetRecording(req *api.GetRecordingRequest, srv api.RecordingService_GetRecordingServer) error {
+	f, err := s.open(req.Name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, recordingChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			serr := srv.Send(&api.GetRecordingResponse{Data: buf[:n]})
+			if serr != nil {
+				return serr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "cannot read recording: %v", err)
+		}
+	}
+}
+
+// ShareRecording uploads a recording to content-service
+func (s *recordingService) ShareRecording(ctx context.Context, req *api.ShareRecordingRequest) (*api.ShareRecordingResponse, error) {
+	f, err := s.open(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if s.Gitpod == nil {
+		return nil, status.Error(codes.Unavailable, "cannot share recordings without a connection to Gitpod")
+	}
+	s.mu.Lock()
+	_, active := s.active[req.Name]
+	s.mu.Unlock()
+	if active {
+		return nil, status.Errorf(codes.FailedPrecondition, "recording %s is still being recorded", req.Name)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot read recording: %v", err)
+	}
+
+	blob := fmt.Sprintf("terminal-recordings/%s/%s", s.WorkspaceID, req.Name)
+	uploadURL, err := s.Gitpod.GetContentBlobUploadURL(ctx, blob)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot get upload URL: %v", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot upload recording: %v", err)
+	}
+	httpReq.ContentLength = info.Size()
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot upload recording: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, status.Errorf(codes.Unavailable, "cannot upload recording: %s", resp.Status)
+	}
+
+	err = os.WriteFile(filepath.Join(s.Location, req.Name+".blob"), []byte(blob), 0644)
+	if err != nil {
+		log.WithError(err).WithField("recording", req.Name).Warn("cannot remember that the recording was shared")
+	}
+	log.WithField("recording", req.Name).WithField("blob", blob).Info("shared terminal recording")
+
+	downloadURL, err := s.Gitpod.GetContentBlobDownloadURL(ctx, blob)
+	if err != nil {
+		// the recording is shared nonetheless - it can be downloaded using the blob name
+		log.WithError(err).WithField("blob", blob).Warn("cannot get download URL of shared recording")
+	}
+	return &api.ShareRecordingResponse{
+		Blob:        blob,
+		DownloadUrl: downloadURL,
+	}, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// blobAPI hands out URLs of a fake storage for content blobs
+type blobAPI struct {
+	gitpod.APIInterface
+	URL string
+}
+
+func (b *blobAPI) GetContentBlobUploadURL(ctx context.Context, name string) (string, error) {
+	return b.URL + "/upload/" + name, nil
+}
+
+func (b *blobAPI) GetContentBlobDownloadURL(ctx context.Context, name string) (string, error) {
+	return b.URL + "/download/" + name, nil
+}
+
+type recordingStream struct {
+	grpc.ServerStream
+	bytes.Buffer
+}
+
+func (s *recordingStream) Send(resp *api.GetRecordingResponse) error {
+	_, err := s.Write(resp.Data)
+	return err
+}
+
+func TestRecordingService(t *testing.T) {
+	var (
+		ctx = context.Background()
+		now = time.Date(2021, 10, 17, 10, 15, 0, 0, time.UTC)
+	)
+	srv := &recordingService{
+		Location:    filepath.Join(t.TempDir(), "recordings"),
+		WorkspaceID: "amaranth-smelt-9ba20cc1",
+		active:      make(map[string]struct{}),
+		now:         func() time.Time { return now },
+	}
+
+	// without the user's opt-in we record nothing
+	rec, err := srv.Record("0", "build")
+	if err != nil || rec != nil {
+		t.Fatalf("expected no recording without opt-in, got %v, %v", rec, err)
+	}
+	list, err := srv.ListRecordings(ctx, &api.ListRecordingsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Recordings) != 0 {
+		t.Fatalf("expected no recordings, got %v", list.Recordings)
+	}
+
+	srv.Enabled = true
+	rec, err = srv.Record("0", "build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Output([]byte("make\r\n"))
+
+	list, err = srv.ListRecordings(ctx, &api.ListRecordingsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const name = "task-0-20211017T101500Z.cast"
+	if len(list.Recordings) != 1 {
+		t.Fatalf("expected one recording, got %v", list.Recordings)
+	}
+	if r := list.Recordings[0]; r.Name != name || r.TaskId != "0" || r.Title != "build" || !r.Active || !r.StartedAt.AsTime().Equal(now) {
+		t.Errorf("unexpected recording %v", r)
+	}
+
+	_, err = srv.ShareRecording(ctx, &api.ShareRecordingRequest{Name: name})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.Unavailable {
+		t.Errorf("expected sharing without Gitpod API to be unavailable, got %v", err)
+	}
+
+	err = rec.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stream recordingStream
+	err = srv.GetRecording(&api.GetRecordingRequest{Name: name}, &stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(stream.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[1], `"make\r\n"`) {
+		t.Errorf("unexpected recording content %q", stream.String())
+	}
+
+	for _, n := range []string{"../config.json", "task-0.cast", ""} {
+		err = srv.GetRecording(&api.GetRecordingRequest{Name: n}, &stream)
+		if st, ok := status.FromError(err); !ok || st.Code() != codes.InvalidArgument {
+			t.Errorf("expected recording name %q to be invalid, got %v", n, err)
+		}
+	}
+
+	var uploaded []byte
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+			return
+		}
+		uploaded, _ = io.ReadAll(r.Body)
+	}))
+	defer storage.Close()
+
+	const blob = "terminal-recordings/amaranth-smelt-9ba20cc1/" + name
+	srv.Gitpod = &blobAPI{URL: storage.URL}
+
+	shared, err := srv.ShareRecording(ctx, &api.ShareRecordingRequest{Name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared.Blob != blob || shared.DownloadUrl != storage.URL+"/download/"+blob {
+		t.Errorf("unexpected share response %v", shared)
+	}
+	expected, _ := os.ReadFile(filepath.Join(srv.Location, name))
+	if !bytes.Equal(uploaded, expected) {
+		t.Errorf("expected the recording to be uploaded, got %q", uploaded)
+	}
+
+	list, err = srv.ListRecordings(ctx, &api.ListRecordingsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Recordings) != 1 || list.Recordings[0].Blob != blob || list.Recordings[0].Active {
+		t.Errorf("expected the recording to be shared and inactive, got %v", list.Recordings)
+	}
+}
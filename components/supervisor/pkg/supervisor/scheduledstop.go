@@ -0,0 +1,236 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// scheduledStopPath is where ws-manager tells us when the workspace stops, relative to the supervisor API
+	scheduledStopPath = "/_supervisor/v1/scheduled-stop"
+)
+
+// scheduledStopLeadTimes are the times before a scheduled stop at which we warn the user
+var scheduledStopLeadTimes = []time.Duration{15 * time.Minute, 5 * time.Minute, 1 * time.Minute}
+
+// ScheduledStopState is the scheduled stop as ws-manager and the user see it
+type ScheduledStopState struct {
+	// Deadline is the time the workspace stops at in RFC 3339 format. Empty if there is no scheduled stop.
+	Deadline string `json:"deadline,omitempty"`
+	// Cancelled is true if the user cancelled the scheduled stop
+	Cancelled bool `json:"cancelled,omitempty"`
+}
+
+// scheduledStop counts down to the stop ws-manager scheduled for this workspace and warns the user before.
+// ws-manager stops the workspace at the deadline unless the user cancelled it here.
+type scheduledStop struct {
+	// LeadTimes are the times before the deadline we warn the user at, longest first
+	LeadTimes []time.Duration
+
+	mu        sync.Mutex
+	deadline  time.Time
+	cancelled bool
+	changed   chan struct{}
+	subs      map[chan *api.ScheduledStop]struct{}
+}
+
+func newScheduledStop() *scheduledStop {
+	return &scheduledStop{
+		LeadTimes: scheduledStopLeadTimes,
+		changed:   make(chan struct{}, 1),
+		subs:      make(map[chan *api.ScheduledStop]struct{}),
+	}
+}
+
+// State returns the current scheduled stop
+func (s *scheduledStop) State() ScheduledStopState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state()
+}
+
+// state returns the current scheduled stop. Callers must hold mu.
+func (s *scheduledStop) state() ScheduledStopState {
+	if s.deadline.IsZero() {
+		return ScheduledStopState{}
+	}
+	return ScheduledStopState{
+		Deadline:  s.deadline.Format(time.RFC3339),
+		Cancelled: s.cancelled,
+	}
+}
+
+// Set schedules the stop at deadline. A zero deadline removes the scheduled stop.
+func (s *scheduledStop) Set(deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deadline = deadline
+	s.cancelled = false
+	s.publish(time.Now())
+}
+
+// Cancel cancels the scheduled stop on behalf of the user. Returns false if there is no stop to cancel.
+func (s *scheduledStop) Cancel() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.deadline.IsZero() {
+		return false
+	}
+	s.cancelled = true
+	s.publish(time.Now())
+	return true
+}
+
+// Subscribe returns a channel which receives the current scheduled stop, if there is one, and all changes to it.
+// Receivers that fall behind only get the most recent update. Call the returned function to unsubscribe.
+func (s *scheduledStop) Subscribe() (<-chan *api.ScheduledStop, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := make(chan *api.ScheduledStop, 1)
+	s.subs[sub] = struct{}{}
+	if !s.deadline.IsZero() {
+		sub <- s.update(time.Now())
+	}
+	return sub, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subs, sub)
+	}
+}
+
+// update describes the scheduled stop at now. Callers must hold mu.
+func (s *scheduledStop) update(now time.Time) *api.ScheduledStop {
+	st := s.state()
+	res := &api.ScheduledStop{Deadline: st.Deadline, Cancelled: st.Cancelled}
+	if remaining := s.deadline.Sub(now); !s.deadline.IsZero() && remaining > 0 {
+		res.RemainingSeconds = uint32(remaining.Round(time.Second) / time.Second)
+	}
+	return res
+}
+
+// publish sends the scheduled stop to all subscribers and wakes the countdown. Callers must hold mu.
+func (s *scheduledStop) publish(now time.Time) {
+	update := s.update(now)
+	for sub := range s.subs {
+		select {
+		case <-sub:
+			// drop the update the subscriber did not receive yet
+		default:
+		}
+		sub <- update
+	}
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Run warns the user at each lead time before the scheduled stop until ctx is done
+func (s *scheduledStop) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	var (
+		deadline time.Time
+		// warned is the number of lead times we warned at for deadline
+		warned int
+	)
+	for {
+		s.mu.Lock()
+		if !s.deadline.Equal(deadline) {
+			deadline = s.deadline
+			warned = 0
+		}
+		active := !deadline.IsZero() && !s.cancelled
+		now := time.Now()
+		remaining := deadline.Sub(now)
+		if active && remaining > 0 {
+			// warn once, even if we passed several lead times since the stop was scheduled
+			var due bool
+			for warned < len(s.LeadTimes) && remaining <= s.LeadTimes[warned] {
+				warned++
+				due = true
+			}
+			if due {
+				log.WithField("deadline", deadline).WithField("remaining", remaining.Round(time.Second).String()).Warn("workspace stops soon as scheduled")
+				s.publish(now)
+				// publish notified the countdown of a change which is none
+				select {
+				case <-s.changed:
+				default:
+				}
+			}
+		}
+		s.mu.Unlock()
+
+		var (
+			timer *time.Timer
+			next  <-chan time.Time
+		)
+		if active && remaining > 0 && warned < len(s.LeadTimes) {
+			timer = time.NewTimer(remaining - s.LeadTimes[warned])
+			next = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.changed:
+		case <-next:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// ServeHTTP serves the scheduled stop: ws-manager PUTs the deadline, users DELETE to cancel the stop
+// and ws-manager GETs the state to find out if the user cancelled.
+func (s *scheduledStop) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req ScheduledStopState
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, "invalid scheduled stop", http.StatusBadRequest)
+			return
+		}
+		var deadline time.Time
+		if req.Deadline != "" {
+			deadline, err = time.Parse(time.RFC3339, req.Deadline)
+			if err != nil {
+				http.Error(w, "invalid deadline", http.StatusBadRequest)
+				return
+			}
+		}
+		s.Set(deadline)
+		log.WithField("deadline", req.Deadline).Info("workspace stop scheduled")
+	case http.MethodDelete:
+		if !s.Cancel() {
+			http.Error(w, "no scheduled stop", http.StatusNotFound)
+			return
+		}
+		log.Info("user cancelled the scheduled workspace stop")
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.State())
+}
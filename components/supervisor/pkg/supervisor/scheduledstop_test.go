@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestScheduledStopHTTP(t *testing.T) {
+	s := newScheduledStop()
+	do := func(method, body string) (int, ScheduledStopState) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(method, scheduledStopPath, strings.NewReader(body)))
+		var res ScheduledStopState
+		if rec.Code == http.StatusOK {
+			err := json.Unmarshal(rec.Body.Bytes(), &res)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rec.Code, res
+	}
+
+	tests := []struct {
+		Name        string
+		Method      string
+		Body        string
+		Code        int
+		Expectation ScheduledStopState
+	}{
+		{Name: "no scheduled stop", Method: http.MethodGet, Code: http.StatusOK},
+		{Name: "cancel without scheduled stop", Method: http.MethodDelete, Code: http.StatusNotFound},
+		{Name: "invalid deadline", Method: http.MethodPut, Body: `{"deadline":"tomorrow"}`, Code: http.StatusBadRequest},
+		{Name: "schedule", Method: http.MethodPut, Body: `{"deadline":"2021-05-04T10:00:00Z"}`, Code: http.StatusOK, Expectation: ScheduledStopState{Deadline: "2021-05-04T10:00:00Z"}},
+		{Name: "cancel", Method: http.MethodDelete, Code: http.StatusOK, Expectation: ScheduledStopState{Deadline: "2021-05-04T10:00:00Z", Cancelled: true}},
+		{Name: "cancelled", Method: http.MethodGet, Code: http.StatusOK, Expectation: ScheduledStopState{Deadline: "2021-05-04T10:00:00Z", Cancelled: true}},
+		{Name: "reschedule", Method: http.MethodPut, Body: `{"deadline":"2021-05-04T11:00:00Z"}`, Code: http.StatusOK, Expectation: ScheduledStopState{Deadline: "2021-05-04T11:00:00Z"}},
+		{Name: "clear", Method: http.MethodPut, Body: `{}`, Code: http.StatusOK},
+		{Name: "invalid method", Method: http.MethodPost, Code: http.StatusMethodNotAllowed},
+	}
+	// the tests build on each other
+	for _, test := range tests {
+		code, act := do(test.Method, test.Body)
+		if code != test.Code {
+			t.Fatalf("%s: unexpected status code %d", test.Name, code)
+		}
+		if diff := cmp.Diff(test.Expectation, act); diff != "" {
+			t.Errorf("%s: unexpected scheduled stop (-want +got):\n%s", test.Name, diff)
+		}
+	}
+}
+
+func TestScheduledStopCountdown(t *testing.T) {
+	s := newScheduledStop()
+	s.LeadTimes = []time.Duration{time.Hour, 200 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go s.Run(ctx, &wg)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	updates, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+	next := func() *api.ScheduledStop {
+		select {
+		case u := <-updates:
+			return u
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for scheduled stop update")
+			return nil
+		}
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond).Truncate(time.Second).Add(time.Second)
+	s.Set(deadline)
+	// we may receive the update of Set or the first warning right away, depending on the countdown
+	u := next()
+	if u.Deadline != deadline.Format(time.RFC3339) || u.Cancelled {
+		t.Fatalf("unexpected update: %v", u)
+	}
+
+	// the last warning comes 200ms before the deadline
+	for {
+		u = next()
+		if time.Until(deadline) <= 200*time.Millisecond {
+			break
+		}
+	}
+	if u.Deadline != deadline.Format(time.RFC3339) || u.RemainingSeconds > 1 {
+		t.Errorf("unexpected warning: %v", u)
+	}
+
+	s.Cancel()
+	u = next()
+	if !u.Cancelled {
+		t.Errorf("expected cancelled update, got %v", u)
+	}
+	select {
+	case u := <-updates:
+		t.Errorf("unexpected update after cancellation: %v", u)
+	case <-time.After(time.Until(deadline) + 100*time.Millisecond):
+	}
+}
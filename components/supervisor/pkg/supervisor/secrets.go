@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/secrets"
+)
+
+// secretsDirEnv points tasks to the directory which holds their secret files
+const secretsDirEnv = "GITPOD_SECRETS_DIR"
+
+// newSecretsManager creates the secrets manager for the providers in GITPOD_SECRET_PROVIDERS.
+// Returns nil if there are no providers.
+func newSecretsManager(cfg *Config) (*secrets.Manager, error) {
+	providerConfigs, err := cfg.GetSecretProviders()
+	if err != nil {
+		return nil, err
+	}
+	if len(providerConfigs) == 0 {
+		return nil, nil
+	}
+
+	providers := make(map[string]secrets.Provider, len(providerConfigs))
+	for name, c := range providerConfigs {
+		providers[name], err = secrets.NewProvider(c)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create secrets provider %s: %w", name, err)
+		}
+	}
+	return secrets.NewManager(providers), nil
+}
+
+// fetchSecrets fetches the secrets a task declares and adds them to its environment. Secret files go to
+// secretsLocation which is outside of /workspace, so that they never end up in a backup.
+func (tm *tasksManager) fetchSecrets(ctx context.Context, t *task, env map[string]string) error {
+	if t.config.Secrets == nil || len(*t.config.Secrets) == 0 {
+		return nil
+	}
+	if tm.config.isHeadless() {
+		// prebuilds are shared with everyone who can open the repository
+		log.WithField("task", t.Id).Warn("secrets are not available in prebuilds - not fetching any")
+		return nil
+	}
+	if tm.secrets == nil {
+		return xerrors.Errorf("task declares secrets, but there are no secrets providers")
+	}
+
+	dir := filepath.Join(tm.secretsLocation, "task-"+t.Id)
+	for _, s := range *t.config.Secrets {
+		if s.Env == "" && s.File == "" {
+			return xerrors.Errorf("secret %s has neither env nor file", s.Name)
+		}
+
+		value, err := tm.secrets.Get(ctx, s.Provider, s.Name)
+		if err != nil {
+			return xerrors.Errorf("cannot fetch secret %s: %w", s.Name, err)
+		}
+		if s.Env != "" {
+			env[s.Env] = value
+		}
+		if s.File == "" {
+			continue
+		}
+
+		fn := filepath.Clean(s.File)
+		if filepath.IsAbs(fn) || fn == "." || fn == ".." || strings.HasPrefix(fn, "../") {
+			return xerrors.Errorf("secret file %s must be relative to %s", s.File, secretsDirEnv)
+		}
+		fn = filepath.Join(dir, fn)
+		err = os.MkdirAll(filepath.Dir(fn), 0700)
+		if err != nil {
+			return xerrors.Errorf("cannot create secrets directory: %w", err)
+		}
+		err = os.WriteFile(fn, []byte(value), 0600)
+		if err != nil {
+			return xerrors.Errorf("cannot write secret file %s: %w", s.File, err)
+		}
+		env[secretsDirEnv] = dir
+	}
+	return nil
+}
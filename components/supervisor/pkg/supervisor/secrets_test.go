@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/secrets"
+)
+
+type staticSecretsProvider map[string]string
+
+func (p staticSecretsProvider) Fetch(ctx context.Context, name string) (*secrets.Secret, error) {
+	v, ok := p[name]
+	if !ok {
+		return nil, xerrors.Errorf("secret %s does not exist", name)
+	}
+	return &secrets.Secret{Value: []byte(v)}, nil
+}
+
+func (p staticSecretsProvider) Renew(ctx context.Context, secret *secrets.Secret) (*secrets.Secret, error) {
+	return nil, secrets.ErrNotRenewable
+}
+
+func TestFetchSecrets(t *testing.T) {
+	provider := staticSecretsProvider{
+		"secret/data/myapp": `{"password":"hunter2"}`,
+		"tls/cert":          "certificate",
+	}
+	tests := []struct {
+		Desc        string
+		Headless    bool
+		NoProviders bool
+		Secrets     []TaskSecretConfig
+
+		Error bool
+		Env   map[string]string
+		Files map[string]string
+	}{
+		{
+			Desc: "no secrets",
+			Env:  map[string]string{},
+		},
+		{
+			Desc: "env and file",
+			Secrets: []TaskSecretConfig{
+				{Name: "secret/data/myapp#password", Env: "DB_PASSWORD"},
+				{Name: "tls/cert", File: "tls/cert.pem"},
+			},
+			Env:   map[string]string{"DB_PASSWORD": "hunter2", "GITPOD_SECRETS_DIR": "task-0"},
+			Files: map[string]string{"task-0/tls/cert.pem": "certificate"},
+		},
+		{
+			Desc:     "prebuilds get no secrets",
+			Headless: true,
+			Secrets:  []TaskSecretConfig{{Name: "tls/cert", Env: "CERT"}},
+			Env:      map[string]string{},
+		},
+		{
+			Desc:    "missing secret",
+			Secrets: []TaskSecretConfig{{Name: "secret/data/other", Env: "OTHER"}},
+			Error:   true,
+		},
+		{
+			Desc:        "no providers",
+			NoProviders: true,
+			Secrets:     []TaskSecretConfig{{Name: "tls/cert", Env: "CERT"}},
+			Error:       true,
+		},
+		{
+			Desc:    "file outside of the secrets dir",
+			Secrets: []TaskSecretConfig{{Name: "tls/cert", File: "../../etc/cert.pem"}},
+			Error:   true,
+		},
+		{
+			Desc:    "neither env nor file",
+			Secrets: []TaskSecretConfig{{Name: "tls/cert"}},
+			Error:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			dir := t.TempDir()
+			tm := &tasksManager{
+				config:          &Config{},
+				secretsLocation: dir,
+			}
+			if test.Headless {
+				tm.config.GitpodHeadless = "true"
+			}
+			if !test.NoProviders {
+				tm.secrets = secrets.NewManager(map[string]secrets.Provider{"vault": provider})
+			}
+			tsk := &task{TaskStatus: api.TaskStatus{Id: "0"}}
+			if test.Secrets != nil {
+				tsk.config.Secrets = &test.Secrets
+			}
+
+			env := make(map[string]string)
+			err := tm.fetchSecrets(context.Background(), tsk, env)
+			if test.Error {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if d, ok := env["GITPOD_SECRETS_DIR"]; ok {
+				env["GITPOD_SECRETS_DIR"], _ = filepath.Rel(dir, d)
+			}
+			if diff := cmp.Diff(test.Env, env); diff != "" {
+				t.Errorf("unexpected env (-want +got):\n%s", diff)
+			}
+			for fn, expectation := range test.Files {
+				content, err := os.ReadFile(filepath.Join(dir, fn))
+				if err != nil {
+					t.Errorf("cannot read %s: %v", fn, err)
+					continue
+				}
+				if string(content) != expectation {
+					t.Errorf("unexpected content of %s: %q", fn, string(content))
+				}
+			}
+		})
+	}
+}
@@ -79,7 +79,9 @@ type statusService struct {
 	ContentState ContentState
 	Ports        *ports.Manager
 	Tasks        *tasksManager
-	ideReady     *ideReadyState
+	// Frontends counts as the IDE while one of them is active
+	Frontends *frontendService
+	ideReady  *ideReadyState
 }
 
 func (s *statusService) RegisterGRPC(srv *grpc.Server) {
@@ -95,16 +97,27 @@ func (s *statusService) SupervisorStatus(context.Context, *api.SupervisorStatusR
 }
 
 func (s *statusService) IDEStatus(ctx context.Context, req *api.IDEStatusRequest) (*api.IDEStatusResponse, error) {
+	var frontendActive *ideReadyState
+	if s.Frontends != nil {
+		frontendActive = s.Frontends.active
+	}
+
 	if req.Wait {
+		var frontendReady <-chan struct{}
+		if frontendActive != nil {
+			frontendReady = frontendActive.Wait()
+		}
 		select {
 		case <-s.ideReady.Wait():
 			return &api.IDEStatusResponse{Ok: true}, nil
+		case <-frontendReady:
+			return &api.IDEStatusResponse{Ok: true}, nil
 		case <-ctx.Done():
 			return nil, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
 		}
 	}
 
-	ok := s.ideReady.Get()
+	ok := s.ideReady.Get() || (frontendActive != nil && frontendActive.Get())
 	return &api.IDEStatusResponse{Ok: ok}, nil
 }
 
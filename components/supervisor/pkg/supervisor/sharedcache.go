@@ -0,0 +1,305 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/sharedcache"
+)
+
+// sharedCachePublishTimeout limits how long we delay the shutdown to publish dependencies to the shared cache
+const sharedCachePublishTimeout = 20 * time.Second
+
+// dependencyToolchain is a toolchain whose dependency cache we share between the workspaces of a node
+type dependencyToolchain struct {
+	Name string
+	// KeyFiles are the files in the repository which determine the dependencies, e.g. go.sum
+	KeyFiles []string
+	// CacheDir returns the local dependency cache of the toolchain
+	CacheDir func() (string, error)
+}
+
+var defaultDependencyToolchains = []dependencyToolchain{
+	{
+		Name:     "go",
+		KeyFiles: []string{"go.sum"},
+		CacheDir: func() (string, error) {
+			if dir := os.Getenv("GOMODCACHE"); dir != "" {
+				return dir, nil
+			}
+			if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+				return filepath.Join(gopath[0], "pkg", "mod"), nil
+			}
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, "go", "pkg", "mod"), nil
+		},
+	},
+	{
+		Name:     "maven",
+		KeyFiles: []string{"pom.xml"},
+		CacheDir: func() (string, error) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, ".m2", "repository"), nil
+		},
+	},
+}
+
+// sharedDependencyCache fills the local dependency caches of Go and Maven from a cache which all workspaces
+// of a node share, and publishes the dependencies this workspace downloaded to that cache when it stops.
+type sharedDependencyCache struct {
+	Cache      *sharedcache.Cache
+	RepoRoot   string
+	Toolchains []dependencyToolchain
+
+	restored chan struct{}
+
+	mu     sync.Mutex
+	misses []sharedCacheMiss
+	stats  sharedcache.Stats
+}
+
+type sharedCacheMiss struct {
+	Key      string
+	CacheDir string
+}
+
+// newSharedDependencyCache returns nil if there's no shared cache
+func newSharedDependencyCache(cfg *Config) *sharedDependencyCache {
+	if cfg.SharedCacheDir == "" {
+		return nil
+	}
+
+	return &sharedDependencyCache{
+		Cache:      sharedcache.New(cfg.SharedCacheDir, cfg.WorkspaceInstanceID),
+		RepoRoot:   cfg.RepoRoot,
+		Toolchains: defaultDependencyToolchains,
+		restored:   make(chan struct{}),
+	}
+}
+
+// Run restores the dependencies from the shared cache once the content is ready
+func (c *sharedDependencyCache) Run(ctx context.Context, wg *sync.WaitGroup, contentReady <-chan struct{}) {
+	defer wg.Done()
+	defer close(c.restored)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-contentReady:
+	}
+	c.Restore()
+}
+
+// ContentState delays the content readiness of cstate until the dependencies are restored. Tasks must not
+// start before that: a partially restored module could look complete to the toolchain.
+func (c *sharedDependencyCache) ContentState(cstate ContentState) ContentState {
+	if c == nil {
+		return cstate
+	}
+	return &cacheRestoredContentState{ContentState: cstate, restored: c.restored}
+}
+
+type cacheRestoredContentState struct {
+	ContentState
+	restored <-chan struct{}
+}
+
+func (s *cacheRestoredContentState) ContentReady() <-chan struct{} {
+	return s.restored
+}
+
+// Restore copies the cached dependencies of all toolchains the repository uses into their local caches
+func (c *sharedDependencyCache) Restore() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tc := range c.Toolchains {
+		key, ok := c.key(tc)
+		if !ok {
+			continue
+		}
+		cacheDir, err := tc.CacheDir()
+		if err != nil {
+			log.WithError(err).WithField("toolchain", tc.Name).Warn("cannot determine local dependency cache")
+			continue
+		}
+
+		entry, hit := c.Cache.Lookup(key)
+		if !hit {
+			c.stats.Misses++
+			c.misses = append(c.misses, sharedCacheMiss{Key: key, CacheDir: cacheDir})
+			continue
+		}
+		c.stats.Hits++
+
+		t0 := time.Now()
+		err = copyDependencyTree(entry, cacheDir, time.Time{})
+		if err != nil {
+			log.WithError(err).WithField("toolchain", tc.Name).Warn("cannot restore dependencies from shared cache")
+			continue
+		}
+		log.WithField("toolchain", tc.Name).WithField("key", key).WithField("duration", time.Since(t0).String()).Info("restored dependencies from shared cache")
+	}
+}
+
+// Publish adds the dependencies of all toolchains which missed the shared cache to the cache
+// and records our hits and misses.
+func (c *sharedDependencyCache) Publish() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deadline := time.Now().Add(sharedCachePublishTimeout)
+	for _, miss := range c.misses {
+		if stat, err := os.Stat(miss.CacheDir); err != nil || !stat.IsDir() {
+			continue
+		}
+
+		claim, err := c.Cache.Claim(miss.Key)
+		if err == sharedcache.ErrExists || err == sharedcache.ErrBusy {
+			// another workspace published these dependencies while we were running
+			continue
+		}
+		if err != nil {
+			log.WithError(err).WithField("key", miss.Key).Warn("cannot claim shared cache entry")
+			continue
+		}
+
+		err = copyDependencyTree(miss.CacheDir, claim.Dir, deadline)
+		if err != nil {
+			log.WithError(err).WithField("key", miss.Key).Warn("cannot publish dependencies to shared cache")
+			_ = claim.Abandon()
+			continue
+		}
+		err = claim.Commit()
+		if err == sharedcache.ErrExists {
+			c.stats.Conflicts++
+			continue
+		}
+		if err != nil {
+			log.WithError(err).WithField("key", miss.Key).Warn("cannot publish dependencies to shared cache")
+			continue
+		}
+		c.stats.Commits++
+	}
+	c.misses = nil
+
+	total, err := c.Cache.RecordStats(c.stats)
+	if err != nil {
+		log.WithError(err).Warn("cannot record shared cache stats")
+	}
+	log.WithField("workspace", c.stats).
+		WithField("workspaceHitRate", c.stats.HitRate()).
+		WithField("node", total).
+		WithField("nodeHitRate", total.HitRate()).
+		Info("shared dependency cache usage")
+}
+
+// key identifies the dependencies of a toolchain by the content of its key files.
+// Returns false if the repository does not use the toolchain.
+func (c *sharedDependencyCache) key(tc dependencyToolchain) (key string, ok bool) {
+	hash := sha256.New()
+	for _, fn := range tc.KeyFiles {
+		f, err := os.Open(filepath.Join(c.RepoRoot, fn))
+		if err != nil {
+			return "", false
+		}
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return "", false
+		}
+	}
+	return tc.Name + "-" + hex.EncodeToString(hash.Sum(nil))[:32], true
+}
+
+// copyDependencyTree copies the files of src to dst, keeping all files which exist in dst already.
+// If deadline is not zero, copying stops with an error once the deadline has passed.
+func copyDependencyTree(src, dst string, deadline time.Time) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return xerrors.Errorf("timed out copying %s", src)
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			// module caches are read-only - we need to be able to write to the directories we create
+			return os.MkdirAll(target, 0755)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			err = os.Symlink(link, target)
+			if os.IsExist(err) {
+				return nil
+			}
+			return err
+		case info.Mode().IsRegular():
+			return copyDependencyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+func copyDependencyFile(src, dst string, mode os.FileMode) error {
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if os.IsExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	defer in.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/pkg/sharedcache"
+)
+
+func TestSharedDependencyCache(t *testing.T) {
+	var (
+		shared = t.TempDir()
+		repo   = t.TempDir()
+	)
+	write := func(fn, content string) {
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fn, []byte(content), 0444)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(fn string) string {
+		c, err := os.ReadFile(fn)
+		if err != nil {
+			return ""
+		}
+		return string(c)
+	}
+	workspace := func(owner string) (*sharedDependencyCache, string) {
+		modCache := t.TempDir()
+		return &sharedDependencyCache{
+			Cache:    sharedcache.New(shared, owner),
+			RepoRoot: repo,
+			Toolchains: []dependencyToolchain{
+				{Name: "go", KeyFiles: []string{"go.sum"}, CacheDir: func() (string, error) { return modCache, nil }},
+				{Name: "maven", KeyFiles: []string{"pom.xml"}, CacheDir: func() (string, error) { return t.TempDir(), nil }},
+			},
+		}, modCache
+	}
+
+	write(filepath.Join(repo, "go.sum"), "example.com/mod v1.0.0 h1:abc=")
+
+	// the first workspace misses the cache and publishes the module it downloaded
+	ws1, modCache1 := workspace("ws1")
+	ws1.Restore()
+	write(filepath.Join(modCache1, "example.com/mod@v1.0.0/go.mod"), "module example.com/mod")
+	ws1.Publish()
+
+	// the second workspace finds it
+	ws2, modCache2 := workspace("ws2")
+	write(filepath.Join(modCache2, "example.com/local@v1.0.0/go.mod"), "module example.com/local")
+	ws2.Restore()
+	if c := read(filepath.Join(modCache2, "example.com/mod@v1.0.0/go.mod")); c != "module example.com/mod" {
+		t.Errorf("module was not restored from shared cache: %q", c)
+	}
+	if c := read(filepath.Join(modCache2, "example.com/local@v1.0.0/go.mod")); c != "module example.com/local" {
+		t.Errorf("restore modified local module: %q", c)
+	}
+	ws2.Publish()
+
+	total, err := ws2.Cache.RecordStats(sharedcache.Stats{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(sharedcache.Stats{Hits: 1, Misses: 1, Commits: 1}, total); diff != "" {
+		t.Errorf("unexpected stats (-want +got):\n%s", diff)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
 
+	"github.com/gitpod-io/gitpod/common-go/handshake"
 	"github.com/gitpod-io/gitpod/common-go/log"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/executor"
@@ -35,6 +36,7 @@ import (
 	"github.com/gitpod-io/gitpod/supervisor/api"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/dropwriter"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/sshagent"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
 	daemon "github.com/gitpod-io/gitpod/ws-daemon/api"
 )
@@ -57,6 +59,11 @@ func AddAPIEndpointOpts(opts ...grpc.ServerOption) {
 
 const (
 	maxIDEPause = 20 * time.Second
+
+	// sshAgentSocket is where supervisor serves its SSH agent. Terminals, tasks and the IDE
+	// find it through SSH_AUTH_SOCK. The socket lives in its own directory which only the
+	// workspace user can access.
+	sshAgentSocket = "/tmp/gitpod-ssh-agent/agent.sock"
 )
 
 type runOptions struct {
@@ -131,10 +138,23 @@ func Run(options ...RunOption) {
 		}
 	}
 
+	sshAgent := sshagent.New()
+	sshKeys, err := cfg.GetSSHKeys(true)
+	if err != nil {
+		log.WithError(err).Warn("cannot prepare SSH keys")
+	}
+	for _, k := range sshKeys {
+		err = sshAgent.AddPEMKey(k.Key, k.Name)
+		if err != nil {
+			log.WithError(err).WithField("name", k.Name).Warn("cannot add SSH key to agent")
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	var (
 		shutdown            = make(chan struct{})
 		ideReady            = &ideReadyState{cond: sync.NewCond(&sync.Mutex{})}
+		ideProcess          = &ideProcessState{}
 		cstate              = NewInMemoryContentState(cfg.RepoRoot)
 		gitpodService       = createGitpodService(cfg, tokenService)
 		gitpodConfigService = gitpod.NewConfigService(cfg.RepoRoot+"/.gitpod.yml", cstate.ContentReady())
@@ -149,27 +169,82 @@ func Run(options ...RunOption) {
 		)
 		termMux     = terminal.NewMux()
 		termMuxSrv  = terminal.NewMuxTerminalService(termMux)
-		taskManager = newTasksManager(cfg, termMuxSrv, cstate, &loggingHeadlessTaskProgressReporter{})
+		depCache    = newSharedDependencyCache(cfg)
+		taskManager = newTasksManager(cfg, termMuxSrv, depCache.ContentState(cstate), &loggingHeadlessTaskProgressReporter{})
+		home        = newPersistedHome(cfg)
+		coreDumps   = newCoreDumps()
+		stopSched   = newScheduledStop()
+		pause       = newPauseGate(cfg.WorkspacePaused)
+		otel        = newOTelCollector()
 	)
 	tokenService.provider[KindGit] = []tokenProvider{NewGitTokenProvider(gitpodService)}
 
+	secretsManager, err := newSecretsManager(cfg)
+	if err != nil {
+		log.WithError(err).Error("cannot create secrets providers")
+	}
+	taskManager.secrets = secretsManager
+
+	recordings := newRecordingService(cfg, gitpodService)
+	taskManager.recordings = recordings
+	taskManager.resumed = pause.Resumed()
+	taskManager.logMasking, err = cfg.GetLogMasking()
+	if err != nil {
+		log.WithError(err).Fatal("invalid log masking config")
+	}
+
 	termMuxSrv.DefaultWorkdir = cfg.RepoRoot
 	termMuxSrv.Env = buildIDEEnv(cfg)
 
+	infoService := &InfoService{cfg: cfg}
+	metadata := newMetadataService(cfg, portMgmt)
+	frontends := newFrontendService(infoService, portMgmt, taskManager, stopSched, fmt.Sprintf("localhost:%d", cfg.APIEndpointPort))
+	handshakes := &proxyHandshakes{}
+	frontends.Handshakes = handshakes
+
 	apiServices := []RegisterableService{
 		&statusService{
 			ContentState: cstate,
 			Ports:        portMgmt,
 			Tasks:        taskManager,
+			Frontends:    frontends,
 			ideReady:     ideReady,
 		},
 		termMuxSrv,
 		RegistrableTokenService{tokenService},
-		&InfoService{cfg: cfg},
+		infoService,
 		&ControlService{portsManager: portMgmt},
+		frontends,
+		recordings,
 	}
 	apiServices = append(apiServices, additionalServices...)
 
+	apiAccessPolicy, err := cfg.GetAPIAccessPolicy()
+	if err != nil {
+		log.WithError(err).Fatal("invalid API access policy")
+	}
+	var apiAccess *apiAccessControl
+	if apiAccessPolicy != nil {
+		// the policy is valid, hence so are its remote networks
+		remoteNetworks, _ := apiAccessPolicy.remoteNetworks()
+		apiAccess = &apiAccessControl{
+			Policy: apiAccessPolicy,
+			Resolver: &apiPeerResolver{
+				Self:   os.Getpid(),
+				Procfs: "/proc",
+				Remote: remoteNetworks,
+				IDE:    ideProcess.Get,
+				Terminal: func(pid int) (isTask bool, ok bool) {
+					alias, ok := termMux.FindByPID(pid)
+					if !ok {
+						return false, false
+					}
+					return taskManager.isTaskTerminal(alias), true
+				},
+			},
+		}
+	}
+
 	// The reaper can be turned into a terminating reaper by writing true to this channel.
 	// When in terminating mode, the reaper will send SIGTERM to each child that gets reparented
 	// to us and is still running. We use this mechanism to send SIGTERM to a shell child processes
@@ -180,20 +255,55 @@ func Run(options ...RunOption) {
 	//   - we want to do as much work as possible (SIGTERM'ing reparented processes during shutdown).
 	go reaper(terminatingReaper)
 
+	egress, err := newEgressFilter(cfg)
+	if err != nil {
+		log.WithError(err).Fatal("invalid egress policy")
+	}
+	// the workspace must not run unrestricted if we cannot enforce its egress policy
+	err = egress.Apply(ctx)
+	if err != nil {
+		log.WithError(err).Fatal("cannot enforce egress policy")
+	}
+
 	var ideWG sync.WaitGroup
 	ideWG.Add(1)
-	go startAndWatchIDE(ctx, cfg, &ideWG, ideReady)
+	go startAndWatchIDE(ctx, cfg, &ideWG, ideReady, ideProcess, pause)
 
 	var wg sync.WaitGroup
 	wg.Add(4)
 	go startContentInit(ctx, cfg, &wg, cstate)
-	go startAPIEndpoint(ctx, cfg, &wg, apiServices, apiEndpointOpts...)
+	go startAPIEndpoint(ctx, cfg, &wg, apiServices, apiAccess, coreDumps, stopSched, pause, frontends, metadata, handshakes, egress, apiEndpointOpts...)
 	go taskManager.Run(ctx, &wg)
+	if secretsManager != nil {
+		go secretsManager.Run(ctx)
+	}
+	go func() {
+		err := sshAgent.Serve(ctx, sshAgentSocket)
+		if err != nil {
+			log.WithError(err).Error("SSH agent failed")
+		}
+	}()
 
 	if !cfg.isHeadless() {
 		wg.Add(1)
 		go portMgmt.Run(ctx, &wg)
 	}
+	if home != nil && !cfg.isHeadless() {
+		wg.Add(1)
+		go home.Run(ctx, &wg, cstate.ContentReady())
+	}
+	if depCache != nil {
+		wg.Add(1)
+		go depCache.Run(ctx, &wg, cstate.ContentReady())
+	}
+	wg.Add(1)
+	go coreDumps.Run(ctx, &wg)
+	wg.Add(1)
+	go stopSched.Run(ctx, &wg)
+	wg.Add(1)
+	go otel.Run(ctx, &wg, gitpodConfigService)
+	wg.Add(1)
+	go egress.Run(ctx, &wg)
 
 	if cfg.PreventMetadataAccess {
 		go func() {
@@ -221,10 +331,20 @@ func Run(options ...RunOption) {
 		log.WithError(err).Error("terminal closure failed")
 	}
 
+	for _, u := range sshAgent.Usage() {
+		log.WithField("fingerprint", u.Fingerprint).WithField("comment", u.Comment).WithField("signCount", u.SignCount).Info("SSH agent key usage")
+	}
+
 	// terminate all child processes once the IDE is gone
 	ideWG.Wait()
 	terminateChildProcesses()
 
+	// shells write their history when they terminate - persist it before the workspace content is backed up
+	home.Sync()
+
+	// publish the dependencies the workspace downloaded while nothing modifies the local caches anymore
+	depCache.Publish()
+
 	if !opts.InNamespace {
 		callDaemonTeardown()
 	}
@@ -245,6 +365,8 @@ func createGitpodService(cfg *Config, tknsrv api.TokenServiceServer) *gitpod.API
 			"function:getToken",
 			"function:openPort",
 			"function:getOpenPorts",
+			"function:getContentBlobUploadUrl",
+			"function:getContentBlobDownloadUrl",
 		},
 	})
 	if err != nil {
@@ -361,7 +483,7 @@ func reaper(terminatingReaper <-chan bool) {
 	}
 }
 
-func startAndWatchIDE(ctx context.Context, cfg *Config, wg *sync.WaitGroup, ideReady *ideReadyState) {
+func startAndWatchIDE(ctx context.Context, cfg *Config, wg *sync.WaitGroup, ideReady *ideReadyState, ideProcess *ideProcessState, pause *pauseGate) {
 	defer wg.Done()
 	defer log.Debug("startAndWatchIDE shutdown")
 
@@ -370,6 +492,15 @@ func startAndWatchIDE(ctx context.Context, cfg *Config, wg *sync.WaitGroup, ideR
 		return
 	}
 
+	if pause.Paused() {
+		log.Info("workspace is paused - waiting for activation before starting the IDE")
+	}
+	select {
+	case <-ctx.Done():
+		return
+	case <-pause.Resumed():
+	}
+
 	type status int
 	const (
 		statusNeverRan status = iota
@@ -410,6 +541,7 @@ supervisorLoop:
 				return
 			}
 			s = statusShouldRun
+			ideProcess.Set(cmd.Process.Pid)
 
 			go func() {
 				runIDEReadinessProbe(cfg)
@@ -428,6 +560,7 @@ supervisorLoop:
 			}
 
 			ideReady.Set(false)
+			ideProcess.Set(0)
 			close(ideStopped)
 		}()
 
@@ -507,18 +640,105 @@ func buildIDEEnv(cfg *Config) []string {
 
 	ce := map[string]string{
 		"SUPERVISOR_ADDR": fmt.Sprintf("localhost:%d", cfg.APIEndpointPort),
+		"SSH_AUTH_SOCK":   sshAgentSocket,
 	}
 	for nme, val := range ce {
 		log.WithField("envvar", nme).Debug("passing environment variable to IDE")
 		env = append(env, fmt.Sprintf("%s=%s", nme, val))
 		envn = append(envn, nme)
 	}
+	for _, e := range buildClockEnv(cfg) {
+		env = append(env, e)
+		envn = append(envn, strings.SplitN(e, "=", 2)[0])
+	}
+	for _, e := range buildHistoryEnv(cfg) {
+		env = append(env, e)
+		envn = append(envn, strings.SplitN(e, "=", 2)[0])
+	}
+	for _, e := range buildOTelEnv(cfg) {
+		env = append(env, e)
+		envn = append(envn, strings.SplitN(e, "=", 2)[0])
+	}
 
 	log.WithField("envvar", envn).Debug("passing environment variables to IDE")
 
 	return env
 }
 
+// buildClockEnv produces the environment variables which set the timezone and fake time offset
+// of the workspace. Environment variables later in the list take precedence.
+func buildClockEnv(cfg *Config) []string {
+	var env []string
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			log.WithError(err).WithField("timezone", cfg.Timezone).Warn("invalid timezone - ignoring")
+		} else {
+			env = append(env, "TZ="+cfg.Timezone)
+		}
+	}
+
+	offset, err := cfg.GetFakeTimeOffset()
+	if err != nil {
+		log.WithError(err).Warn("invalid fake time offset - ignoring")
+		return env
+	}
+	if offset == 0 {
+		return env
+	}
+	if cfg.FakeTimeLibrary == "" {
+		log.WithField("offset", cfg.FakeTimeOffset).Warn("fake time offset requested, but no libfaketime available - ignoring")
+		return env
+	}
+	if _, err := os.Stat(cfg.FakeTimeLibrary); err != nil {
+		log.WithError(err).WithField("offset", cfg.FakeTimeOffset).Warn("fake time offset requested, but cannot find libfaketime - ignoring")
+		return env
+	}
+	if !hasGlibc() {
+		log.WithField("offset", cfg.FakeTimeOffset).Warn("fake time offset requested, but the workspace image has no glibc - ignoring")
+		return env
+	}
+
+	preload := cfg.FakeTimeLibrary
+	if p := os.Getenv("LD_PRELOAD"); p != "" {
+		preload += ":" + p
+	}
+	env = append(env,
+		"LD_PRELOAD="+preload,
+		fmt.Sprintf("FAKETIME=%+d", int64(offset/time.Second)),
+		// timers and timeouts would behave erratically if we faked the monotonic clock, too
+		"FAKETIME_DONT_FAKE_MONOTONIC=1",
+	)
+	return env
+}
+
+// glibcLoaders are the dynamic loaders of glibc. The libfaketime we ship is built against glibc: preloading it
+// into the processes of images with another libc, e.g. musl on Alpine, would keep them from starting.
+var glibcLoaders = []string{"/lib64/ld-linux-x86-64.so.2", "/lib/ld-linux-aarch64.so.1"}
+
+// hasGlibc returns true if the workspace image has glibc
+func hasGlibc() bool {
+	for _, fn := range glibcLoaders {
+		if _, err := os.Stat(fn); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// buildHistoryEnv makes bash write each command to its history file right away instead of when the shell exits,
+// so that the history survives terminals which are killed abruptly.
+func buildHistoryEnv(cfg *Config) []string {
+	if cfg.DisablePersistedHome {
+		return nil
+	}
+
+	cmd := "history -a"
+	if p := os.Getenv("PROMPT_COMMAND"); p != "" {
+		cmd += "; " + p
+	}
+	return []string{"PROMPT_COMMAND=" + cmd}
+}
+
 func runIDEReadinessProbe(cfg *Config) {
 	defer log.Info("IDE is ready")
 
@@ -568,7 +788,7 @@ func isBlacklistedEnvvar(name string) bool {
 	return false
 }
 
-func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, services []RegisterableService, opts ...grpc.ServerOption) {
+func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, services []RegisterableService, access *apiAccessControl, coreDumps, scheduledStop, pause, frontends, metadata, handshakes, egress http.Handler, opts ...grpc.ServerOption) {
 	defer wg.Done()
 	defer log.Debug("startAPIEndpoint shutdown")
 
@@ -578,9 +798,9 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 	}
 
 	m := cmux.New(l)
-	restMux := grpcruntime.NewServeMux()
+	restMux := grpcruntime.NewServeMux(access.RESTMuxOptions()...)
 	grpcMux := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
-	grpcServer := grpc.NewServer(opts...)
+	grpcServer := grpc.NewServer(append(opts, access.GRPCServerOptions()...)...)
 	grpcEndpoint := fmt.Sprintf("localhost:%d", cfg.APIEndpointPort)
 	for _, reg := range services {
 		if reg, ok := reg.(RegisterableGRPCService); ok {
@@ -597,9 +817,18 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 
 	httpMux := m.Match(cmux.HTTP1Fast())
 	routes := http.NewServeMux()
-	routes.Handle("/_supervisor/v1/", http.StripPrefix("/_supervisor", restMux))
+	routes.Handle("/_supervisor/v1/", compressAPIResponses(http.StripPrefix("/_supervisor", restMux)))
+	routes.Handle("/_supervisor/v1/coredumps", coreDumps)
+	routes.Handle(scheduledStopPath, scheduledStop)
+	routes.Handle(resumePath, pause)
+	routes.Handle(frontendWebsocketPath, frontends)
+	routes.Handle(metadataTokenPath, metadata)
+	routes.Handle(metadataPath, metadata)
+	routes.Handle(egressPath, egress)
+	routes.Handle(handshake.Path, handshakes)
 	routes.Handle("/_supervisor/frontend", http.FileServer(http.Dir(cfg.FrontendLocation)))
-	go http.Serve(httpMux, routes)
+	httpServer := &http.Server{Handler: routes, ConnContext: access.ConnContext}
+	go httpServer.Serve(httpMux)
 
 	go m.Serve()
 
@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// taskResultCache remembers which init commands succeeded on which inputs. The cache file lives in the
+// workspace content, hence it is part of prebuilds and incremental prebuilds or workspaces started from
+// them can skip init commands whose inputs did not change.
+type taskResultCache struct {
+	Filename string
+
+	mu sync.Mutex
+}
+
+type taskResult struct {
+	Key  string    `json:"key"`
+	Time time.Time `json:"time"`
+}
+
+// Hit returns true if the init command of the task last succeeded with the same key
+func (c *taskResultCache) Hit(taskID, key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results, err := c.load()
+	if err != nil {
+		return false
+	}
+	res, ok := results[taskID]
+	return ok && res.Key == key
+}
+
+// Store records that the init command of the task succeeded with key
+func (c *taskResultCache) Store(taskID, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results, err := c.load()
+	if err != nil {
+		// we'd rather start over than never cache again
+		results = make(map[string]taskResult)
+	}
+	results[taskID] = taskResult{Key: key, Time: time.Now()}
+
+	fc, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := c.Filename + ".tmp"
+	err = os.WriteFile(tmp, fc, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot write task results: %w", err)
+	}
+	return os.Rename(tmp, c.Filename)
+}
+
+func (c *taskResultCache) load() (map[string]taskResult, error) {
+	fc, err := os.ReadFile(c.Filename)
+	if os.IsNotExist(err) {
+		return make(map[string]taskResult), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]taskResult
+	err = json.Unmarshal(fc, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal task results: %w", err)
+	}
+	if res == nil {
+		res = make(map[string]taskResult)
+	}
+	return res, nil
+}
+
+// taskCacheKey hashes an init command together with the content of its inputs. Inputs are glob patterns
+// relative to root as understood by filepath.Match; directories they match are hashed recursively.
+func taskCacheKey(root, command string, inputs []string) (string, error) {
+	hash := sha256.New()
+	_, _ = io.WriteString(hash, command)
+	for _, pattern := range inputs {
+		// patterns without matches still change the key
+		_, _ = io.WriteString(hash, "\x00"+pattern+"\x00")
+
+		matches, err := filepath.Glob(filepath.Join(root, pattern))
+		if err != nil {
+			return "", xerrors.Errorf("invalid input pattern %s: %w", pattern, err)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			err = filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if !info.Mode().IsRegular() {
+					return nil
+				}
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				content := sha256.New()
+				_, err = io.Copy(content, f)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(hash, "%s\x00%x\x00", rel, content.Sum(nil))
+				return nil
+			})
+			if err != nil {
+				return "", xerrors.Errorf("cannot hash input %s: %w", match, err)
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
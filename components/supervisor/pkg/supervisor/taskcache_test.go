@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+func TestTaskCacheKey(t *testing.T) {
+	root := t.TempDir()
+	write := func(fn, content string) {
+		fn = filepath.Join(root, fn)
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key := func(command string, inputs ...string) string {
+		k, err := taskCacheKey(root, command, inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	write("package.json", `{"name":"foo"}`)
+	write("packages/a/package.json", `{"name":"a"}`)
+	base := key("yarn install", "package.json", "packages")
+
+	if k := key("yarn install", "package.json", "packages"); k != base {
+		t.Error("expected the key to be stable")
+	}
+	if k := key("npm install", "package.json", "packages"); k == base {
+		t.Error("expected the key to change with the command")
+	}
+	if k := key("yarn install", "package.json", "packages", "yarn.lock"); k == base {
+		t.Error("expected the key to change with the inputs, even if they do not exist")
+	}
+
+	write("packages/a/package.json", `{"name":"a","version":"1.0.0"}`)
+	if k := key("yarn install", "package.json", "packages"); k == base {
+		t.Error("expected the key to change with the content of directories")
+	}
+
+	globbed := key("yarn install", "*.json")
+	write("tsconfig.json", `{}`)
+	if k := key("yarn install", "*.json"); k == globbed {
+		t.Error("expected the key to change with the files a glob matches")
+	}
+
+	if _, err := taskCacheKey(root, "yarn install", []string{"["}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestTaskResultCache(t *testing.T) {
+	root := t.TempDir()
+	storeLocation := t.TempDir()
+	err := os.WriteFile(filepath.Join(root, "go.sum"), []byte("golang.org/x/xerrors v0.0.0"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		init   = "go mod download"
+		inputs = []string{"go.sum"}
+	)
+	newTask := func(tm *tasksManager) *task {
+		task := &task{
+			TaskStatus: api.TaskStatus{Id: "0"},
+			config:     TaskConfig{Init: &init, Inputs: &inputs},
+		}
+		tm.checkResultCache(task)
+		return task
+	}
+	newTasksManager := func(headless bool, source csapi.WorkspaceInitSource) *tasksManager {
+		terminalService := terminal.NewMuxTerminalService(terminal.NewMux())
+		terminalService.DefaultWorkdir = root
+		return &tasksManager{
+			config:          &Config{WorkspaceConfig: WorkspaceConfig{GitpodHeadless: strconv.FormatBool(headless)}},
+			terminalService: terminalService,
+			contentSource:   source,
+			storeLocation:   storeLocation,
+			resultCache:     &taskResultCache{Filename: filepath.Join(storeLocation, "task-results.json")},
+		}
+	}
+
+	prebuild := newTasksManager(true, csapi.WorkspaceInitFromOther)
+	task := newTask(prebuild)
+	if task.Cache == nil || task.Cache.Hit {
+		t.Fatalf("expected a cache miss, got %v", task.Cache)
+	}
+	if cmds := prebuild.getCommands(task); cmds[1] != &init {
+		t.Error("expected the init command to run on a cache miss")
+	}
+	prebuild.storeResult(task)
+
+	incremental := newTasksManager(true, csapi.WorkspaceInitFromPrebuild)
+	task = newTask(incremental)
+	if task.Cache == nil || !task.Cache.Hit {
+		t.Fatalf("expected a cache hit, got %v", task.Cache)
+	}
+	if cmds := incremental.getCommands(task); cmds[1] != nil {
+		t.Error("expected the init command to be skipped on a cache hit")
+	}
+
+	err = os.WriteFile(filepath.Join(root, "go.sum"), []byte("golang.org/x/xerrors v0.0.1"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task = newTask(incremental)
+	if task.Cache == nil || task.Cache.Hit {
+		t.Fatalf("expected a cache miss after the inputs changed, got %v", task.Cache)
+	}
+
+	// prebuilt workspaces don't run the init command anyways
+	task = newTask(newTasksManager(false, csapi.WorkspaceInitFromPrebuild))
+	if task.Cache != nil {
+		t.Errorf("expected no cache status, got %v", task.Cache)
+	}
+}
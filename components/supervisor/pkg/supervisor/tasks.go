@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +19,8 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/log"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/logmask"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/secrets"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
 )
 
@@ -83,6 +86,15 @@ type tasksManager struct {
 	terminalService *terminal.MuxTerminalService
 	contentState    ContentState
 	reporter        headlessTaskProgressReporter
+	resultCache     *taskResultCache
+	secrets         *secrets.Manager
+	secretsLocation string
+	// recordings records the task terminals if the user opted in
+	recordings *recordingService
+	// logMasking redacts secrets from prebuild logs and recordings. If nil, we mask nothing.
+	logMasking *LogMaskingConfig
+	// resumed, if set, holds the tasks until it is closed, i.e. until a paused workspace is activated
+	resumed <-chan struct{}
 }
 
 func newTasksManager(config *Config, terminalService *terminal.MuxTerminalService, contentState ContentState, reporter headlessTaskProgressReporter) *tasksManager {
@@ -94,6 +106,7 @@ func newTasksManager(config *Config, terminalService *terminal.MuxTerminalServic
 		subscriptions:   make(map[*tasksSubscription]struct{}),
 		ready:           make(chan struct{}),
 		storeLocation:   "/workspace/.gitpod",
+		secretsLocation: filepath.Join(os.TempDir(), "gitpod-secrets"),
 	}
 }
 
@@ -114,6 +127,19 @@ func (tm *tasksManager) getStatus() []*api.TaskStatus {
 	return status
 }
 
+// isTaskTerminal returns true if a terminal runs a task
+func (tm *tasksManager) isTaskTerminal(alias string) bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	for _, t := range tm.tasks {
+		if t.Terminal == alias {
+			return true
+		}
+	}
+	return false
+}
+
 func (tm *tasksManager) updateState(doUpdate func() (changed bool)) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
@@ -165,9 +191,17 @@ func (tm *tasksManager) init(ctx context.Context) {
 		return
 	case <-tm.contentState.ContentReady():
 	}
+	if tm.resumed != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tm.resumed:
+		}
+	}
 
 	contentSource, _ := tm.contentState.ContentSource()
 	tm.contentSource = contentSource
+	tm.resultCache = &taskResultCache{Filename: filepath.Join(tm.storeLocation, "task-results.json")}
 
 	for i, config := range *tasks {
 		id := strconv.Itoa(i)
@@ -195,6 +229,7 @@ func (tm *tasksManager) init(ctx context.Context) {
 			successChan: make(chan bool, 1),
 			title:       title,
 		}
+		tm.checkResultCache(task)
 		task.command = tm.getCommand(task)
 		if tm.config.isHeadless() && task.command == "exit" {
 			task.State = api.TaskState_closed
@@ -216,19 +251,43 @@ func (tm *tasksManager) Run(ctx context.Context, wg *sync.WaitGroup) {
 		}
 		taskLog := log.WithField("command", t.command)
 		taskLog.Info("starting a task terminal...")
-		openRequest := &api.OpenTerminalRequest{}
+		openRequest := &api.OpenTerminalRequest{Env: make(map[string]string)}
 		if t.config.Env != nil {
-			openRequest.Env = *t.config.Env
+			for k, v := range *t.config.Env {
+				openRequest.Env[k] = v
+			}
+		}
+		err := tm.fetchSecrets(ctx, t, openRequest.Env)
+		if err != nil {
+			taskLog.WithError(err).Error("cannot fetch task secrets")
+			t.successChan <- false
+			tm.setTaskState(t, api.TaskState_closed)
+			continue
 		}
 		var readTimeout time.Duration
 		if !tm.config.isHeadless() {
 			readTimeout = 5 * time.Second
 		}
+		masker := tm.taskMasker(t, openRequest.Env)
+		var recorder *terminal.CastRecorder
+		if tm.recordings != nil {
+			recorder, err = tm.recordings.Record(t.Id, t.title)
+			if err != nil {
+				taskLog.WithError(err).Warn("cannot record task terminal")
+			}
+			if recorder != nil && masker != nil {
+				recorder.Mask(masker)
+			}
+		}
 		resp, err := tm.terminalService.OpenWithOptions(ctx, openRequest, terminal.TermOptions{
 			ReadTimeout: readTimeout,
 			Title:       t.title,
+			Recorder:    recorder,
 		})
 		if err != nil {
+			if recorder != nil {
+				recorder.Close()
+			}
 			taskLog.WithError(err).Error("cannot open new task terminal")
 			t.successChan <- false
 			tm.setTaskState(t, api.TaskState_closed)
@@ -255,6 +314,9 @@ func (tm *tasksManager) Run(ctx context.Context, wg *sync.WaitGroup) {
 		go func(t *task, term *terminal.Term) {
 			state, _ := term.Wait()
 			if state != nil {
+				if state.Success() && tm.config.isHeadless() {
+					tm.storeResult(t)
+				}
 				t.successChan <- state.Success()
 			} else {
 				t.successChan <- false
@@ -263,7 +325,7 @@ func (tm *tasksManager) Run(ctx context.Context, wg *sync.WaitGroup) {
 			tm.setTaskState(t, api.TaskState_closed)
 		}(t, term)
 
-		tm.watch(t, term)
+		tm.watch(t, term, masker)
 
 		if t.command != "" {
 			term.PTY.Write([]byte(t.command + "\n"))
@@ -286,6 +348,47 @@ func (tm *tasksManager) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// checkResultCache computes the cache key of tasks which declare the inputs of their init command and
+// skips the init command if it last succeeded with the same key.
+func (tm *tasksManager) checkResultCache(task *task) {
+	if task.config.Inputs == nil || len(*task.config.Inputs) == 0 || task.config.Init == nil || strings.TrimSpace(*task.config.Init) == "" {
+		return
+	}
+	if !tm.config.isHeadless() && (tm.contentSource == csapi.WorkspaceInitFromPrebuild || tm.contentSource == csapi.WorkspaceInitFromBackup) {
+		// we don't run the init command anyways
+		return
+	}
+
+	key, err := taskCacheKey(tm.terminalService.DefaultWorkdir, *task.config.Init, *task.config.Inputs)
+	if err != nil {
+		log.WithError(err).WithField("task", task.Id).Warn("cannot compute task cache key - running init command")
+		return
+	}
+	task.Cache = &api.TaskCacheStatus{
+		Key: key,
+		Hit: tm.resultCache.Hit(task.Id, key),
+	}
+	if task.Cache.Hit {
+		log.WithField("task", task.Id).WithField("key", key).Info("inputs did not change - skipping init command")
+	}
+}
+
+// storeResult records that the init command of a task succeeded on its current inputs
+func (tm *tasksManager) storeResult(task *task) {
+	if task.Cache == nil {
+		return
+	}
+
+	// the init command may have changed its inputs, e.g. updated a lock file
+	key, err := taskCacheKey(tm.terminalService.DefaultWorkdir, *task.config.Init, *task.config.Inputs)
+	if err == nil {
+		err = tm.resultCache.Store(task.Id, key)
+	}
+	if err != nil {
+		log.WithError(err).WithField("task", task.Id).Warn("cannot store task result")
+	}
+}
+
 func (tm *tasksManager) getCommand(task *task) string {
 	commands := tm.getCommands(task)
 	command := composeCommand(composeCommandOptions{
@@ -341,7 +444,7 @@ func (tm *tasksManager) getHistfileCommand(task *task, commands []*string) strin
 func (tm *tasksManager) getCommands(task *task) []*string {
 	if tm.config.isHeadless() {
 		// prebuild
-		return []*string{task.config.Before, task.config.Init, task.config.Prebuild}
+		return []*string{task.config.Before, tm.getInitCommand(task), task.config.Prebuild}
 	}
 	if tm.contentSource == csapi.WorkspaceInitFromPrebuild {
 		// prebuilt
@@ -355,15 +458,24 @@ func (tm *tasksManager) getCommands(task *task) []*string {
 		return []*string{task.config.Before, task.config.Command}
 	}
 	// init
-	return []*string{task.config.Before, task.config.Init, task.config.Command}
+	return []*string{task.config.Before, tm.getInitCommand(task), task.config.Command}
 
 }
 
+// getInitCommand returns the init command of a task unless its result is cached
+func (tm *tasksManager) getInitCommand(task *task) *string {
+	if task.Cache != nil && task.Cache.Hit {
+		return nil
+	}
+	return task.config.Init
+}
+
 func (tm *tasksManager) prebuildLogFileName(task *task) string {
 	return tm.storeLocation + "/prebuild-log-" + task.Id
 }
 
-func (tm *tasksManager) watch(task *task, terminal *terminal.Term) {
+// watch writes the output of a headless task to its prebuild log and reports it, with secrets masked
+func (tm *tasksManager) watch(task *task, terminal *terminal.Term, masker *logmask.Masker) {
 	if !tm.config.isHeadless() {
 		return
 	}
@@ -389,6 +501,11 @@ func (tm *tasksManager) watch(task *task, terminal *terminal.Term) {
 			defer fileWriter.Flush()
 		}
 
+		out := logmask.NewWriter(taskOutputFunc(func(p []byte) {
+			fileWriter.Write(p)
+			tm.reporter.write(string(p), task, terminal)
+		}), masker)
+
 		buf := make([]byte, 4096)
 		for {
 			n, err := stdout.Read(buf)
@@ -403,25 +520,31 @@ func (tm *tasksManager) watch(task *task, terminal *terminal.Term) {
 					}
 					duration += " of watching your code build.\n"
 				}
-				data := string(buf[:n])
-				fileWriter.Write(buf[:n])
-				tm.reporter.write(data, task, terminal)
+				out.Write(buf[:n])
+				out.Flush()
 
 				endMessage := "\n🍌 This task ran as part of a workspace prebuild.\n" + duration + "\n"
 				fileWriter.WriteString(endMessage)
 				break
 			}
 			if err != nil {
+				out.Flush()
 				terminalLog.WithError(err).Error("cannot read from a task terminal")
 				return
 			}
-			data := string(buf[:n])
-			fileWriter.Write(buf[:n])
-			tm.reporter.write(data, task, terminal)
+			out.Write(buf[:n])
 		}
 	}()
 }
 
+// taskOutputFunc passes on task output which has been masked
+type taskOutputFunc func(p []byte)
+
+func (f taskOutputFunc) Write(p []byte) (int, error) {
+	f(p)
+	return len(p), nil
+}
+
 type composeCommandOptions struct {
 	commands []*string
 	format   string
@@ -443,7 +566,7 @@ type loggingHeadlessTaskProgressReporter struct {
 
 func (r *loggingHeadlessTaskProgressReporter) write(data string, task *task, terminal *terminal.Term) {
 	log.WithField("component", "workspace").WithField("pid", terminal.Command.Process.Pid).
-		WithField("taskLogMsg", taskLogMessage{Type: "workspaceTaskOutput", Data: data}).Info()
+		WithField("taskLogMsg", taskLogMessage{Type: "workspaceTaskOutput", Data: data, TaskID: task.Id}).Info()
 }
 
 func (r *loggingHeadlessTaskProgressReporter) done(success bool) {
@@ -460,4 +583,6 @@ func (r *loggingHeadlessTaskProgressReporter) done(success bool) {
 type taskLogMessage struct {
 	Type string `json:"type"`
 	Data string `json:"data"`
+	// TaskID attributes task output to the task which produced it
+	TaskID string `json:"taskId,omitempty"`
 }
@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/logmask"
+)
+
+// CastHeader is the first line of an asciicast v2 recording
+type CastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// CastRecorder records the output of a terminal in the asciicast v2 format,
+// see https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md.
+type CastRecorder struct {
+	// Limit is the size in bytes after which we stop recording. Use 0 for no limit.
+	Limit int64
+
+	mu      sync.Mutex
+	out     io.WriteCloser
+	start   time.Time
+	written int64
+	// pending are the bytes of an incomplete UTF-8 sequence at the end of the last output
+	pending []byte
+	stopped bool
+	// masked, if set, masks secrets before the output is recorded
+	masked *logmask.Writer
+
+	now func() time.Time
+}
+
+// NewCastRecorder writes the header of a recording to out and returns a recorder which appends to it
+func NewCastRecorder(out io.WriteCloser, header CastHeader) (*CastRecorder, error) {
+	r := &CastRecorder{out: out, now: time.Now}
+	r.start = r.now()
+
+	header.Version = 2
+	if header.Width == 0 || header.Height == 0 {
+		header.Width, header.Height = 80, 24
+	}
+	if header.Timestamp == 0 {
+		header.Timestamp = r.start.Unix()
+	}
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	err = r.write(append(line, '\n'))
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Mask redacts secrets from the recorded output. The output is recorded line by line then, so that secrets
+// which are split across writes are masked, too. Call Mask before the terminal produces any output.
+func (r *CastRecorder) Mask(m *logmask.Masker) {
+	r.masked = logmask.NewWriter(castOutput{r}, m)
+}
+
+// castOutput records what a masking writer passes on
+type castOutput struct {
+	r *CastRecorder
+}
+
+func (o castOutput) Write(p []byte) (int, error) {
+	o.r.output(p)
+	return len(p), nil
+}
+
+// Output records output of the terminal
+func (r *CastRecorder) Output(p []byte) {
+	if r.masked != nil {
+		_, _ = r.masked.Write(p)
+		return
+	}
+	r.output(p)
+}
+
+func (r *CastRecorder) output(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) > 0 {
+		p = append(r.pending, p...)
+		r.pending = nil
+	}
+	// JSON strings cannot hold partial UTF-8 sequences - we keep them until the remainder arrives
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(p[i]) {
+			continue
+		}
+		if !utf8.FullRune(p[i:]) {
+			r.pending = append([]byte(nil), p[i:]...)
+			p = p[:i]
+		}
+		break
+	}
+	if len(p) == 0 {
+		return
+	}
+	r.event("o", string(p))
+}
+
+// Resize records that the terminal changed its size
+func (r *CastRecorder) Resize(cols, rows uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// Close stops recording and closes the recording
+func (r *CastRecorder) Close() error {
+	if r.masked != nil {
+		_ = r.masked.Flush()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) > 0 {
+		r.event("o", string(r.pending))
+		r.pending = nil
+	}
+	r.stopped = true
+	return r.out.Close()
+}
+
+// event appends an event to the recording. Callers must hold mu.
+func (r *CastRecorder) event(code, data string) {
+	if r.stopped {
+		return
+	}
+
+	line, err := json.Marshal([]interface{}{
+		float64(r.now().Sub(r.start).Microseconds()) / 1e6,
+		code,
+		data,
+	})
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+	if r.Limit > 0 && r.written+int64(len(line)) > r.Limit {
+		log.WithField("limit", r.Limit).Warn("terminal recording reached its size limit - no longer recording")
+		r.stopped = true
+		return
+	}
+	err = r.write(line)
+	if err != nil {
+		log.WithError(err).Warn("cannot write terminal recording - no longer recording")
+		r.stopped = true
+	}
+}
+
+func (r *CastRecorder) write(p []byte) error {
+	n, err := r.out.Write(p)
+	r.written += int64(n)
+	return err
+}
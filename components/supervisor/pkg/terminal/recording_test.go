@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/pkg/logmask"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestCastRecorder(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Limit       int64
+		Secrets     []string
+		Interaction func(r *CastRecorder, tick func())
+		Expectation string
+	}{
+		{
+			Name: "output and resize",
+			Interaction: func(r *CastRecorder, tick func()) {
+				r.Output([]byte("hello\r\n"))
+				tick()
+				r.Resize(120, 40)
+				tick()
+				r.Output([]byte("\x1b[1mworld\x1b[0m"))
+			},
+			Expectation: `{"version":2,"width":80,"height":24,"timestamp":1634465700,"title":"build"}
+[0,"o","hello\r\n"]
+[1.5,"r","120x40"]
+[3,"o","\u001b[1mworld\u001b[0m"]
+`,
+		},
+		{
+			Name: "split UTF-8 sequence",
+			Interaction: func(r *CastRecorder, tick func()) {
+				euro := []byte("€")
+				r.Output(append([]byte("5"), euro[:1]...))
+				tick()
+				r.Output(euro[1:])
+			},
+			Expectation: `{"version":2,"width":80,"height":24,"timestamp":1634465700,"title":"build"}
+[0,"o","5"]
+[1.5,"o","€"]
+`,
+		},
+		{
+			Name:  "limit",
+			Limit: 100,
+			Interaction: func(r *CastRecorder, tick func()) {
+				r.Output([]byte("short"))
+				r.Output([]byte(strings.Repeat("x", 100)))
+				r.Output([]byte("dropped"))
+			},
+			Expectation: `{"version":2,"width":80,"height":24,"timestamp":1634465700,"title":"build"}
+[0,"o","short"]
+`,
+		},
+		{
+			Name:    "masked",
+			Secrets: []string{"secret-value"},
+			Interaction: func(r *CastRecorder, tick func()) {
+				r.Output([]byte("echo $TOKEN\r\nsecret-"))
+				tick()
+				r.Output([]byte("value\r\n$ "))
+			},
+			Expectation: `{"version":2,"width":80,"height":24,"timestamp":1634465700,"title":"build"}
+[0,"o","echo $TOKEN\r\n"]
+[1.5,"o","[masked]\r\n"]
+[1.5,"o","$ "]
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				buf bytes.Buffer
+				now = time.Unix(1634465700, 0)
+			)
+			r, err := NewCastRecorder(nopWriteCloser{&buf}, CastHeader{Title: "build", Timestamp: now.Unix()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.start = now
+			r.now = func() time.Time { return now }
+			r.Limit = test.Limit
+			if test.Secrets != nil {
+				r.Mask(logmask.New(test.Secrets, nil))
+			}
+
+			test.Interaction(r, func() { now = now.Add(1500 * time.Millisecond) })
+			err = r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(test.Expectation, buf.String()); diff != "" {
+				t.Errorf("unexpected recording (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -326,6 +326,9 @@ func (srv *MuxTerminalService) SetSize(ctx context.Context, req *api.SetTerminal
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	if term.Stdout.cast != nil {
+		term.Stdout.cast.Resize(uint16(req.Size.Cols), uint16(req.Size.Rows))
+	}
 
 	return &api.SetTerminalSizeResponse{}, nil
 }
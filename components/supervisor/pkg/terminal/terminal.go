@@ -45,6 +45,18 @@ func (m *Mux) Get(alias string) (*Term, bool) {
 	return term, ok
 }
 
+// FindByPID returns the alias of the terminal whose command has the given process ID
+func (m *Mux) FindByPID(pid int) (alias string, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for alias, term := range m.terms {
+		if term.Command != nil && term.Command.Process != nil && term.Command.Process.Pid == pid {
+			return alias, true
+		}
+	}
+	return "", false
+}
+
 // Start starts a new command in its own pseudo-terminal and returns an alias
 // for that pseudo terminal.
 func (m *Mux) Start(cmd *exec.Cmd, options TermOptions) (alias string, err error) {
@@ -132,6 +144,12 @@ func (m *Mux) doClose(alias string, gracePeriod time.Duration) error {
 	if err != nil {
 		log.WithError(err).Warn("cannot close pseudo-terminal")
 	}
+	if term.Stdout.cast != nil {
+		err = term.Stdout.cast.Close()
+		if err != nil {
+			log.WithError(err).Warn("cannot close terminal recording")
+		}
+	}
 	i := 0
 	for i < len(m.aliases) && m.aliases[i] != alias {
 		i++
@@ -210,6 +228,7 @@ func newTerm(pty *os.File, cmd *exec.Cmd, options TermOptions) (*Term, error) {
 			timeout:  timeout,
 			listener: make(map[*multiWriterListener]struct{}),
 			recorder: recorder,
+			cast:     options.Recorder,
 		},
 		Annotations: options.Annotations,
 		title:       options.Title,
@@ -235,6 +254,9 @@ type TermOptions struct {
 
 	// Title describes the terminal title.
 	Title string
+
+	// Recorder records the terminal output until the terminal closes. Use nil to not record the terminal.
+	Recorder *CastRecorder
 }
 
 // Term is a pseudo-terminal
@@ -298,6 +320,8 @@ type multiWriter struct {
 	// ring buffer to record last 256kb of pty output
 	// new listener is initialized with the latest recodring first
 	recorder *RingBuffer
+	// cast records all of the pty output if the terminal is recorded
+	cast *CastRecorder
 }
 
 var (
@@ -410,6 +434,9 @@ func (mw *multiWriter) Write(p []byte) (n int, err error) {
 	defer mw.mu.Unlock()
 
 	mw.recorder.Write(p)
+	if mw.cast != nil {
+		mw.cast.Output(p)
+	}
 
 	for lstr := range mw.listener {
 		if lstr.closed {
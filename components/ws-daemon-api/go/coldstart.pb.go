@@ -0,0 +1,297 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: coldstart.proto
+
+package api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetColdStartRequest struct {
+	// id is the instance ID of the workspace
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetColdStartRequest) Reset()         { *m = GetColdStartRequest{} }
+func (m *GetColdStartRequest) String() string { return proto.CompactTextString(m) }
+func (*GetColdStartRequest) ProtoMessage()    {}
+func (*GetColdStartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73f021196a9b74cc, []int{0}
+}
+
+func (m *GetColdStartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetColdStartRequest.Unmarshal(m, b)
+}
+func (m *GetColdStartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetColdStartRequest.Marshal(b, m, deterministic)
+}
+func (m *GetColdStartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetColdStartRequest.Merge(m, src)
+}
+func (m *GetColdStartRequest) XXX_Size() int {
+	return xxx_messageInfo_GetColdStartRequest.Size(m)
+}
+func (m *GetColdStartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetColdStartRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetColdStartRequest proto.InternalMessageInfo
+
+func (m *GetColdStartRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetColdStartResponse struct {
+	// workspace_class is the class of the workspace, i.e. its type. Empty until the workspace pod appeared on the node.
+	WorkspaceClass string `protobuf:"bytes,1,opt,name=workspace_class,json=workspaceClass,proto3" json:"workspace_class,omitempty"`
+	// phases are the phases of the start which completed, in the order they completed
+	Phases []*ColdStartPhase `protobuf:"bytes,2,rep,name=phases,proto3" json:"phases,omitempty"`
+	// complete is true once the IDE of the workspace listens and no phase is missing anymore
+	Complete             bool     `protobuf:"varint,3,opt,name=complete,proto3" json:"complete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetColdStartResponse) Reset()         { *m = GetColdStartResponse{} }
+func (m *GetColdStartResponse) String() string { return proto.CompactTextString(m) }
+func (*GetColdStartResponse) ProtoMessage()    {}
+func (*GetColdStartResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73f021196a9b74cc, []int{1}
+}
+
+func (m *GetColdStartResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetColdStartResponse.Unmarshal(m, b)
+}
+func (m *GetColdStartResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetColdStartResponse.Marshal(b, m, deterministic)
+}
+func (m *GetColdStartResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetColdStartResponse.Merge(m, src)
+}
+func (m *GetColdStartResponse) XXX_Size() int {
+	return xxx_messageInfo_GetColdStartResponse.Size(m)
+}
+func (m *GetColdStartResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetColdStartResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetColdStartResponse proto.InternalMessageInfo
+
+func (m *GetColdStartResponse) GetWorkspaceClass() string {
+	if m != nil {
+		return m.WorkspaceClass
+	}
+	return ""
+}
+
+func (m *GetColdStartResponse) GetPhases() []*ColdStartPhase {
+	if m != nil {
+		return m.Phases
+	}
+	return nil
+}
+
+func (m *GetColdStartResponse) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+// ColdStartPhase is a node-side phase of a workspace start
+type ColdStartPhase struct {
+	// name is one of mount, content, uidshift, network or ide:
+	//   mount:    creating and mounting the workspace sandbox, the shiftfs mark and proc
+	//   content:  restoring or initializing the workspace content, including the time waiting for a content job slot
+	//   uidshift: writing the UID and GID mappings of user-namespaced workspaces
+	//   network:  from scheduling the pod until its sandbox, incl. the network namespace, has an IP
+	//   ide:      from the start of the workspace container until the IDE listens on its port
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// duration_ms is how long the phase took in milliseconds. Phases which happen in several steps, e.g. mount,
+	// report the sum of their steps.
+	DurationMs           int64    `protobuf:"varint,2,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ColdStartPhase) Reset()         { *m = ColdStartPhase{} }
+func (m *ColdStartPhase) String() string { return proto.CompactTextString(m) }
+func (*ColdStartPhase) ProtoMessage()    {}
+func (*ColdStartPhase) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73f021196a9b74cc, []int{2}
+}
+
+func (m *ColdStartPhase) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ColdStartPhase.Unmarshal(m, b)
+}
+func (m *ColdStartPhase) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ColdStartPhase.Marshal(b, m, deterministic)
+}
+func (m *ColdStartPhase) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ColdStartPhase.Merge(m, src)
+}
+func (m *ColdStartPhase) XXX_Size() int {
+	return xxx_messageInfo_ColdStartPhase.Size(m)
+}
+func (m *ColdStartPhase) XXX_DiscardUnknown() {
+	xxx_messageInfo_ColdStartPhase.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ColdStartPhase proto.InternalMessageInfo
+
+func (m *ColdStartPhase) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ColdStartPhase) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*GetColdStartRequest)(nil), "wsdaemon.GetColdStartRequest")
+	proto.RegisterType((*GetColdStartResponse)(nil), "wsdaemon.GetColdStartResponse")
+	proto.RegisterType((*ColdStartPhase)(nil), "wsdaemon.ColdStartPhase")
+}
+
+func init() {
+	proto.RegisterFile("coldstart.proto", fileDescriptor_73f021196a9b74cc)
+}
+
+var fileDescriptor_73f021196a9b74cc = []byte{
+	// 282 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0xc1, 0x4a, 0xc3, 0x40,
+	0x10, 0x35, 0xad, 0x94, 0x3a, 0x95, 0x56, 0x56, 0x0f, 0xa1, 0xa0, 0x96, 0x80, 0x18, 0x91, 0xa6,
+	0x52, 0xff, 0xc0, 0x22, 0x9e, 0x44, 0x49, 0x6f, 0x5e, 0xca, 0x76, 0x77, 0x68, 0x17, 0x93, 0xcc,
+	0x9a, 0xd9, 0x98, 0xaf, 0xf0, 0x9f, 0x25, 0x6d, 0x1a, 0x2d, 0xe8, 0x6d, 0xf6, 0xcd, 0x7b, 0x33,
+	0x6f, 0xdf, 0xc0, 0x40, 0x51, 0xa2, 0xd9, 0xc9, 0xdc, 0x45, 0x36, 0x27, 0x47, 0xa2, 0x5b, 0xb2,
+	0x96, 0x98, 0x52, 0x16, 0x5c, 0xc1, 0xe9, 0x13, 0xba, 0x19, 0x25, 0x7a, 0x5e, 0xf5, 0x63, 0xfc,
+	0x28, 0x90, 0x9d, 0xe8, 0x43, 0xcb, 0x68, 0xdf, 0x1b, 0x79, 0xe1, 0x51, 0xdc, 0x32, 0x3a, 0xf8,
+	0xf2, 0xe0, 0x6c, 0x9f, 0xc7, 0x96, 0x32, 0x46, 0x71, 0x0d, 0x83, 0x92, 0xf2, 0x77, 0xb6, 0x52,
+	0xe1, 0x42, 0x25, 0x92, 0xb9, 0x56, 0xf5, 0x1b, 0x78, 0x56, 0xa1, 0xe2, 0x0e, 0x3a, 0x76, 0x2d,
+	0x19, 0xd9, 0x6f, 0x8d, 0xda, 0x61, 0x6f, 0xea, 0x47, 0x3b, 0x0f, 0x51, 0x33, 0xf5, 0xb5, 0x22,
+	0xc4, 0x35, 0x4f, 0x0c, 0xa1, 0xab, 0x28, 0xb5, 0x09, 0x3a, 0xf4, 0xdb, 0x23, 0x2f, 0xec, 0xc6,
+	0xcd, 0x3b, 0x78, 0x84, 0xfe, 0xbe, 0x4a, 0x08, 0x38, 0xcc, 0x64, 0x8a, 0xf5, 0xf6, 0x4d, 0x2d,
+	0x2e, 0xa1, 0xa7, 0x8b, 0x5c, 0x3a, 0x43, 0xd9, 0x22, 0xad, 0x16, 0x7b, 0x61, 0x3b, 0x86, 0x1d,
+	0xf4, 0xcc, 0x53, 0x05, 0x27, 0xcd, 0x98, 0x39, 0xe6, 0x9f, 0x46, 0xa1, 0x78, 0x81, 0xe3, 0xdf,
+	0x3f, 0x15, 0xe7, 0x3f, 0x46, 0xff, 0x48, 0x6a, 0x78, 0xf1, 0x5f, 0x7b, 0x1b, 0x50, 0x70, 0xf0,
+	0x70, 0xfb, 0x76, 0xb3, 0x32, 0x6e, 0x5d, 0x2c, 0x23, 0x45, 0xe9, 0x64, 0x65, 0x9c, 0x25, 0x3d,
+	0x36, 0x54, 0x57, 0x93, 0x92, 0xc7, 0x5b, 0xfd, 0x44, 0x5a, 0xb3, 0xec, 0x6c, 0x0e, 0x74, 0xff,
+	0x3d, 0x00, 0xb8, 0xde, 0xf7, 0xe9, 0xb3, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ColdStartServiceClient is the client API for ColdStartService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ColdStartServiceClient interface {
+	// GetColdStart returns how long each node-side phase of a workspace start took so far
+	GetColdStart(ctx context.Context, in *GetColdStartRequest, opts ...grpc.CallOption) (*GetColdStartResponse, error)
+}
+
+type coldStartServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewColdStartServiceClient(cc grpc.ClientConnInterface) ColdStartServiceClient {
+	return &coldStartServiceClient{cc}
+}
+
+func (c *coldStartServiceClient) GetColdStart(ctx context.Context, in *GetColdStartRequest, opts ...grpc.CallOption) (*GetColdStartResponse, error) {
+	out := new(GetColdStartResponse)
+	err := c.cc.Invoke(ctx, "/wsdaemon.ColdStartService/GetColdStart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ColdStartServiceServer is the server API for ColdStartService service.
+type ColdStartServiceServer interface {
+	// GetColdStart returns how long each node-side phase of a workspace start took so far
+	GetColdStart(context.Context, *GetColdStartRequest) (*GetColdStartResponse, error)
+}
+
+// UnimplementedColdStartServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedColdStartServiceServer struct {
+}
+
+func (*UnimplementedColdStartServiceServer) GetColdStart(ctx context.Context, req *GetColdStartRequest) (*GetColdStartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetColdStart not implemented")
+}
+
+func RegisterColdStartServiceServer(s *grpc.Server, srv ColdStartServiceServer) {
+	s.RegisterService(&_ColdStartService_serviceDesc, srv)
+}
+
+func _ColdStartService_GetColdStart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetColdStartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ColdStartServiceServer).GetColdStart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsdaemon.ColdStartService/GetColdStart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ColdStartServiceServer).GetColdStart(ctx, req.(*GetColdStartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ColdStartService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "wsdaemon.ColdStartService",
+	HandlerType: (*ColdStartServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetColdStart",
+			Handler:    _ColdStartService_GetColdStart_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "coldstart.proto",
+}
@@ -480,6 +480,113 @@ func (m *DisposeWorkspaceResponse) GetGitStatus() *api.GitStatus {
 	return nil
 }
 
+type StageSnapshotRequest struct {
+	// snapshot is the fully qualified name of the snapshot, as used by snapshot and prebuild initializers
+	Snapshot string `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// url is the signed URL we download the snapshot archive from
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// size is the size of the snapshot archive in bytes. If set, we verify the download against it.
+	Size                 int64    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StageSnapshotRequest) Reset()         { *m = StageSnapshotRequest{} }
+func (m *StageSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*StageSnapshotRequest) ProtoMessage()    {}
+func (*StageSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3ec90cbc4aa12fc6, []int{9}
+}
+
+func (m *StageSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StageSnapshotRequest.Unmarshal(m, b)
+}
+func (m *StageSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StageSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *StageSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StageSnapshotRequest.Merge(m, src)
+}
+func (m *StageSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_StageSnapshotRequest.Size(m)
+}
+func (m *StageSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StageSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StageSnapshotRequest proto.InternalMessageInfo
+
+func (m *StageSnapshotRequest) GetSnapshot() string {
+	if m != nil {
+		return m.Snapshot
+	}
+	return ""
+}
+
+func (m *StageSnapshotRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *StageSnapshotRequest) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type StageSnapshotResponse struct {
+	// already_staged is true if the node's content cache had the snapshot already
+	AlreadyStaged bool `protobuf:"varint,1,opt,name=already_staged,json=alreadyStaged,proto3" json:"alreadyStaged,omitempty"`
+	// size is the size of the staged snapshot archive in bytes
+	Size                 int64    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StageSnapshotResponse) Reset()         { *m = StageSnapshotResponse{} }
+func (m *StageSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*StageSnapshotResponse) ProtoMessage()    {}
+func (*StageSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3ec90cbc4aa12fc6, []int{10}
+}
+
+func (m *StageSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StageSnapshotResponse.Unmarshal(m, b)
+}
+func (m *StageSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StageSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *StageSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StageSnapshotResponse.Merge(m, src)
+}
+func (m *StageSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_StageSnapshotResponse.Size(m)
+}
+func (m *StageSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StageSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StageSnapshotResponse proto.InternalMessageInfo
+
+func (m *StageSnapshotResponse) GetAlreadyStaged() bool {
+	if m != nil {
+		return m.AlreadyStaged
+	}
+	return false
+}
+
+func (m *StageSnapshotResponse) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("wsdaemon.WorkspaceContentState", WorkspaceContentState_name, WorkspaceContentState_value)
 	proto.RegisterType((*InitWorkspaceRequest)(nil), "wsdaemon.InitWorkspaceRequest")
@@ -491,6 +598,8 @@ func init() {
 	proto.RegisterType((*TakeSnapshotResponse)(nil), "wsdaemon.TakeSnapshotResponse")
 	proto.RegisterType((*DisposeWorkspaceRequest)(nil), "wsdaemon.DisposeWorkspaceRequest")
 	proto.RegisterType((*DisposeWorkspaceResponse)(nil), "wsdaemon.DisposeWorkspaceResponse")
+	proto.RegisterType((*StageSnapshotRequest)(nil), "wsdaemon.StageSnapshotRequest")
+	proto.RegisterType((*StageSnapshotResponse)(nil), "wsdaemon.StageSnapshotResponse")
 }
 
 func init() {
@@ -498,44 +607,49 @@ func init() {
 }
 
 var fileDescriptor_3ec90cbc4aa12fc6 = []byte{
-	// 582 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x5f, 0x73, 0xd2, 0x40,
-	0x10, 0x6f, 0xd2, 0x16, 0x61, 0xe9, 0x9f, 0x78, 0x2d, 0x12, 0x51, 0x2b, 0x66, 0xda, 0x91, 0xea,
-	0x00, 0x33, 0xf8, 0xa0, 0xaf, 0xa0, 0x6d, 0x87, 0x99, 0x96, 0x62, 0x40, 0x99, 0xd1, 0x87, 0xcc,
-	0x41, 0xae, 0xf4, 0x06, 0xc8, 0xc5, 0xdc, 0x45, 0x66, 0xfc, 0x52, 0x7e, 0x2e, 0xbf, 0x85, 0x93,
-	0xe4, 0x08, 0x01, 0x1a, 0x7d, 0xbb, 0xdd, 0xdf, 0x9f, 0xbd, 0xec, 0xee, 0x05, 0xf6, 0x6c, 0x4c,
-	0x66, 0xcc, 0xa9, 0xb9, 0x1e, 0x13, 0x0c, 0x65, 0xe7, 0x3c, 0x8a, 0x4b, 0x67, 0x23, 0xe6, 0x08,
-	0xe2, 0x88, 0x2a, 0x27, 0xde, 0x4f, 0x3a, 0x22, 0x55, 0xec, 0xd2, 0x3a, 0x75, 0xa8, 0xa0, 0x78,
-	0x4a, 0x7f, 0x11, 0x2f, 0x12, 0x18, 0xbf, 0x55, 0x38, 0x6e, 0x3b, 0x54, 0x0c, 0x98, 0x37, 0xe1,
-	0x2e, 0x1e, 0x11, 0x93, 0xfc, 0xf0, 0x09, 0x17, 0xe8, 0x00, 0x54, 0x6a, 0xeb, 0x4a, 0x59, 0xa9,
-	0xe4, 0x4c, 0x95, 0xda, 0xe8, 0x3d, 0x64, 0x67, 0x44, 0x60, 0x1b, 0x0b, 0xac, 0xab, 0x65, 0xa5,
-	0x92, 0x6f, 0x3c, 0xab, 0x2d, 0x8a, 0xd5, 0x62, 0xf5, 0x8d, 0xa4, 0x98, 0x31, 0x19, 0x5d, 0x42,
-	0x3e, 0x51, 0x56, 0xdf, 0x0e, 0xb5, 0xa7, 0x35, 0x79, 0x3d, 0x79, 0xbb, 0xa5, 0x43, 0x7b, 0xc9,
-	0x35, 0x93, 0x42, 0xd4, 0x80, 0xc2, 0x9d, 0x3f, 0x9d, 0x5a, 0xf3, 0x05, 0xd3, 0x1a, 0xe2, 0xd1,
-	0xc4, 0x77, 0xf5, 0x9d, 0xb2, 0x52, 0xc9, 0x9a, 0x47, 0x01, 0x18, 0xbb, 0xb4, 0x42, 0x08, 0x9d,
-	0x83, 0x26, 0xeb, 0x58, 0x33, 0xec, 0xd0, 0x3b, 0xc2, 0x85, 0xbe, 0x5b, 0x56, 0x2a, 0x7b, 0xe6,
-	0xa1, 0xcc, 0xdf, 0xc8, 0x34, 0x7a, 0x0d, 0x87, 0x3e, 0x27, 0x9e, 0xe5, 0xe0, 0x19, 0x09, 0x2d,
-	0x6c, 0x3d, 0x13, 0x1a, 0x1f, 0x04, 0xe9, 0x4e, 0x9c, 0x35, 0x5a, 0xf0, 0x78, 0xe3, 0x73, 0xd1,
-	0x31, 0xec, 0xb2, 0xb9, 0x43, 0x3c, 0xd9, 0xb0, 0x28, 0x40, 0x45, 0x78, 0x14, 0xb4, 0xc1, 0xa2,
-	0x76, 0xd8, 0xb2, 0x9c, 0x99, 0x09, 0xc2, 0xb6, 0x6d, 0x14, 0xa1, 0xb0, 0xd6, 0x74, 0xee, 0x32,
-	0x87, 0x13, 0xe3, 0x14, 0xd0, 0x00, 0x53, 0x71, 0xc9, 0xbc, 0x00, 0x4f, 0x99, 0x85, 0x51, 0x80,
-	0xa3, 0x15, 0x96, 0x14, 0x9f, 0xc1, 0x51, 0x1f, 0x4f, 0x48, 0xcf, 0xc1, 0x2e, 0xbf, 0x67, 0xa9,
-	0xea, 0x0a, 0x1c, 0xaf, 0xd2, 0x22, 0x39, 0xd2, 0x60, 0xdb, 0xf7, 0xa6, 0x92, 0x18, 0x1c, 0x8d,
-	0x26, 0x14, 0x3f, 0x51, 0xee, 0x32, 0x4e, 0xfe, 0xbb, 0x1e, 0x4f, 0x20, 0x23, 0xc7, 0xa1, 0x86,
-	0x5d, 0x93, 0x91, 0xd1, 0x07, 0x7d, 0xd3, 0x42, 0x16, 0xfc, 0x00, 0x30, 0xa6, 0xc2, 0xe2, 0x02,
-	0x0b, 0x9f, 0x87, 0x5e, 0xf9, 0xc6, 0xd3, 0xf5, 0xc5, 0xb8, 0xa2, 0xa2, 0x17, 0x12, 0xcc, 0xdc,
-	0x78, 0x71, 0x7c, 0xf3, 0x19, 0x0a, 0xb1, 0xdd, 0xc7, 0x88, 0x1f, 0x20, 0x04, 0x65, 0x61, 0xa7,
-	0x73, 0xdb, 0xb9, 0xd0, 0xb6, 0xd0, 0x01, 0x40, 0xef, 0xa2, 0xdf, 0x6f, 0x77, 0xae, 0xac, 0x2f,
-	0x5d, 0x4d, 0x41, 0xfb, 0x90, 0x6b, 0x7e, 0x6d, 0xb6, 0xaf, 0x9b, 0xad, 0xeb, 0x0b, 0x4d, 0x45,
-	0x87, 0x90, 0x1f, 0x98, 0xcd, 0x6e, 0x57, 0xe2, 0xdb, 0x8d, 0x3f, 0x2a, 0x14, 0x37, 0x3c, 0xa3,
-	0x3b, 0x20, 0x13, 0xf6, 0x57, 0xc6, 0x85, 0x4e, 0x96, 0xab, 0xff, 0xd0, 0xe3, 0x29, 0xbd, 0x4c,
-	0xc5, 0xe5, 0xa8, 0xb6, 0xd0, 0x35, 0xe4, 0x13, 0x33, 0x44, 0xcf, 0x13, 0x8f, 0x69, 0x63, 0x01,
-	0x4a, 0x2f, 0x52, 0xd0, 0xd8, 0xed, 0x16, 0xf6, 0x92, 0x33, 0x45, 0x09, 0xc1, 0x03, 0x2b, 0x51,
-	0x3a, 0x49, 0x83, 0x63, 0xc3, 0xef, 0xa0, 0xad, 0xcf, 0x0d, 0xbd, 0x5a, 0xaa, 0x52, 0xd6, 0xa2,
-	0x64, 0xfc, 0x8b, 0xb2, 0x30, 0x6f, 0xbd, 0xfd, 0x76, 0x3e, 0xa6, 0xe2, 0xde, 0x1f, 0xd6, 0x46,
-	0x6c, 0x56, 0x1f, 0x53, 0xe1, 0x32, 0xbb, 0x4a, 0x99, 0x3c, 0xd5, 0xe7, 0xbc, 0x1a, 0x79, 0xd4,
-	0xb1, 0x4b, 0x87, 0x99, 0xf0, 0x47, 0xf5, 0xee, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa1, 0xbf,
-	0x58, 0xdb, 0xe9, 0x04, 0x00, 0x00,
+	// 659 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x51, 0x73, 0xd2, 0x40,
+	0x10, 0x2e, 0xa1, 0x45, 0x58, 0x0a, 0xc5, 0x2b, 0x48, 0x8c, 0xda, 0x62, 0xa6, 0x1d, 0xa9, 0x0e,
+	0x30, 0x83, 0x0f, 0xfa, 0x0a, 0xda, 0x76, 0x98, 0x69, 0x69, 0x3d, 0xd0, 0x3a, 0xfa, 0x90, 0xb9,
+	0x92, 0x2b, 0xbd, 0x29, 0x24, 0x31, 0x77, 0x91, 0xb1, 0x7f, 0xca, 0x5f, 0xe0, 0x7f, 0x73, 0x92,
+	0x1c, 0x21, 0x40, 0xa3, 0x6f, 0xb7, 0xbb, 0xdf, 0xf7, 0xdd, 0xee, 0xe6, 0xbb, 0xc0, 0xb6, 0x49,
+	0xe8, 0xd4, 0xb6, 0x9a, 0x8e, 0x6b, 0x0b, 0x1b, 0x65, 0x67, 0x3c, 0x8c, 0xb5, 0xc3, 0x91, 0x6d,
+	0x09, 0x6a, 0x89, 0x06, 0xa7, 0xee, 0x4f, 0x36, 0xa2, 0x0d, 0xe2, 0xb0, 0x16, 0xb3, 0x98, 0x60,
+	0x64, 0xc2, 0xee, 0xa9, 0x1b, 0x12, 0xf4, 0xdf, 0x0a, 0x94, 0x7b, 0x16, 0x13, 0x57, 0xb6, 0x7b,
+	0xc7, 0x1d, 0x32, 0xa2, 0x98, 0xfe, 0xf0, 0x28, 0x17, 0xa8, 0x08, 0x0a, 0x33, 0xd5, 0x54, 0x2d,
+	0x55, 0xcf, 0x61, 0x85, 0x99, 0xe8, 0x1d, 0x64, 0xa7, 0x54, 0x10, 0x93, 0x08, 0xa2, 0x2a, 0xb5,
+	0x54, 0x3d, 0xdf, 0x7e, 0xd6, 0x9c, 0x5f, 0xd6, 0x8c, 0xd8, 0xe7, 0x12, 0x82, 0x23, 0x30, 0x3a,
+	0x81, 0x7c, 0xec, 0x5a, 0x35, 0x1d, 0x70, 0x0f, 0x9a, 0xb2, 0x3d, 0xd9, 0xdd, 0x42, 0xa1, 0xb7,
+	0xc0, 0xe2, 0x38, 0x11, 0xb5, 0xa1, 0x72, 0xe3, 0x4d, 0x26, 0xc6, 0x6c, 0x8e, 0x34, 0xae, 0xc9,
+	0xe8, 0xce, 0x73, 0xd4, 0xcd, 0x5a, 0xaa, 0x9e, 0xc5, 0xbb, 0x7e, 0x31, 0x52, 0xe9, 0x06, 0x25,
+	0x74, 0x04, 0x25, 0x79, 0x8f, 0x31, 0x25, 0x16, 0xbb, 0xa1, 0x5c, 0xa8, 0x5b, 0xb5, 0x54, 0x7d,
+	0x1b, 0xef, 0xc8, 0xfc, 0xb9, 0x4c, 0xa3, 0x57, 0xb0, 0xe3, 0x71, 0xea, 0x1a, 0x16, 0x99, 0xd2,
+	0x40, 0xc2, 0x54, 0x33, 0x81, 0x70, 0xd1, 0x4f, 0xf7, 0xa3, 0xac, 0xde, 0x85, 0xc7, 0x6b, 0xe3,
+	0xa2, 0x32, 0x6c, 0xd9, 0x33, 0x8b, 0xba, 0x72, 0x61, 0x61, 0x80, 0xaa, 0xf0, 0xc8, 0x5f, 0x83,
+	0xc1, 0xcc, 0x60, 0x65, 0x39, 0x9c, 0xf1, 0xc3, 0x9e, 0xa9, 0x57, 0xa1, 0xb2, 0xb2, 0x74, 0xee,
+	0xd8, 0x16, 0xa7, 0xfa, 0x01, 0xa0, 0x2b, 0xc2, 0xc4, 0x89, 0xed, 0xfa, 0xf5, 0x84, 0x6f, 0xa1,
+	0x57, 0x60, 0x77, 0x09, 0x25, 0xc9, 0x87, 0xb0, 0x3b, 0x24, 0x77, 0x74, 0x60, 0x11, 0x87, 0xdf,
+	0xda, 0x89, 0xec, 0x3a, 0x94, 0x97, 0x61, 0x21, 0x1d, 0x95, 0x20, 0xed, 0xb9, 0x13, 0x09, 0xf4,
+	0x8f, 0x7a, 0x07, 0xaa, 0x1f, 0x19, 0x77, 0x6c, 0x4e, 0xff, 0x6b, 0x8f, 0x27, 0x90, 0x91, 0x9f,
+	0x43, 0x09, 0xb6, 0x26, 0x23, 0x7d, 0x08, 0xea, 0xba, 0x84, 0xbc, 0xf0, 0x3d, 0xc0, 0x98, 0x09,
+	0x83, 0x0b, 0x22, 0x3c, 0x1e, 0x68, 0xe5, 0xdb, 0x4f, 0x57, 0x8d, 0x71, 0xca, 0xc4, 0x20, 0x00,
+	0xe0, 0xdc, 0x78, 0x7e, 0xd4, 0xbf, 0x42, 0x79, 0x20, 0xc8, 0x78, 0x6d, 0x54, 0x0d, 0xb2, 0x5c,
+	0xa6, 0x64, 0x6f, 0x51, 0x3c, 0x1f, 0x4f, 0x89, 0xc6, 0x43, 0x08, 0x36, 0x39, 0xbb, 0xa7, 0x81,
+	0x25, 0xd3, 0x38, 0x38, 0xeb, 0x18, 0x2a, 0x2b, 0xca, 0xb2, 0xd9, 0x43, 0x28, 0x92, 0x89, 0x4b,
+	0x89, 0xf9, 0xcb, 0x6f, 0x78, 0x4c, 0xc3, 0xe1, 0xb3, 0xb8, 0x20, 0xb3, 0x01, 0xcb, 0x8c, 0x34,
+	0x95, 0x85, 0xe6, 0xeb, 0x4f, 0x50, 0x89, 0x86, 0xff, 0x10, 0x4e, 0xe7, 0xcf, 0x41, 0x51, 0x16,
+	0x36, 0xfb, 0x17, 0xfd, 0xe3, 0xd2, 0x06, 0x2a, 0x02, 0x0c, 0x8e, 0x87, 0xc3, 0x5e, 0xff, 0xd4,
+	0xf8, 0x7c, 0x59, 0x4a, 0xa1, 0x02, 0xe4, 0x3a, 0x5f, 0x3a, 0xbd, 0xb3, 0x4e, 0xf7, 0xec, 0xb8,
+	0xa4, 0xa0, 0x1d, 0xc8, 0x5f, 0xe1, 0xce, 0xe5, 0xa5, 0xac, 0xa7, 0xdb, 0x7f, 0xd2, 0x50, 0x5d,
+	0xd3, 0x0c, 0x37, 0x86, 0x30, 0x14, 0x96, 0xcc, 0x85, 0xf6, 0x16, 0x0f, 0xf5, 0xa1, 0xa7, 0xae,
+	0xed, 0x27, 0xd6, 0xa5, 0xb1, 0x36, 0xd0, 0x19, 0xe4, 0x63, 0x8e, 0x43, 0xcf, 0x63, 0x4f, 0x7f,
+	0xcd, 0xae, 0xda, 0x8b, 0x84, 0x6a, 0xa4, 0x76, 0x01, 0xdb, 0x71, 0x07, 0xa2, 0x18, 0xe1, 0x01,
+	0x03, 0x6b, 0x7b, 0x49, 0xe5, 0x48, 0xf0, 0x3b, 0x94, 0x56, 0x5d, 0x86, 0x5e, 0x2e, 0x58, 0x09,
+	0x26, 0xd6, 0xf4, 0x7f, 0x41, 0x22, 0x71, 0x0c, 0x85, 0x25, 0x4b, 0xc4, 0xf7, 0xf9, 0x90, 0x0b,
+	0xb5, 0xfd, 0xc4, 0xfa, 0x5c, 0xb3, 0xfb, 0xe6, 0xdb, 0xd1, 0x98, 0x89, 0x5b, 0xef, 0xba, 0x39,
+	0xb2, 0xa7, 0xad, 0x31, 0x13, 0x8e, 0x6d, 0x36, 0x98, 0x2d, 0x4f, 0xad, 0x19, 0x6f, 0x84, 0x02,
+	0x2d, 0xe2, 0xb0, 0xeb, 0x4c, 0xf0, 0xab, 0x7e, 0xfb, 0x77, 0x00, 0xd8, 0x24, 0xa3, 0x0a, 0xeb,
+	0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -560,10 +674,13 @@ type WorkspaceContentServiceClient interface {
 	TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (*TakeSnapshotResponse, error)
 	// disposeWorkspace cleans up a workspace, possibly after taking a final backup
 	DisposeWorkspace(ctx context.Context, in *DisposeWorkspaceRequest, opts ...grpc.CallOption) (*DisposeWorkspaceResponse, error)
+	// StageSnapshot downloads a snapshot, e.g. a prebuild, into the node's content cache so that workspaces
+	// which start from it later on need not download it.
+	StageSnapshot(ctx context.Context, in *StageSnapshotRequest, opts ...grpc.CallOption) (*StageSnapshotResponse, error)
 }
 
 type workspaceContentServiceClient struct {
-	cc grpc.ClientConnInterface `json:"cc,omitempty"`
+	cc grpc.ClientConnInterface
 }
 
 func NewWorkspaceContentServiceClient(cc grpc.ClientConnInterface) WorkspaceContentServiceClient {
@@ -606,6 +723,15 @@ func (c *workspaceContentServiceClient) DisposeWorkspace(ctx context.Context, in
 	return out, nil
 }
 
+func (c *workspaceContentServiceClient) StageSnapshot(ctx context.Context, in *StageSnapshotRequest, opts ...grpc.CallOption) (*StageSnapshotResponse, error) {
+	out := new(StageSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/wsdaemon.WorkspaceContentService/StageSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkspaceContentServiceServer is the server API for WorkspaceContentService service.
 type WorkspaceContentServiceServer interface {
 	// initWorkspace intialises a new workspace folder in the working area
@@ -618,6 +744,9 @@ type WorkspaceContentServiceServer interface {
 	TakeSnapshot(context.Context, *TakeSnapshotRequest) (*TakeSnapshotResponse, error)
 	// disposeWorkspace cleans up a workspace, possibly after taking a final backup
 	DisposeWorkspace(context.Context, *DisposeWorkspaceRequest) (*DisposeWorkspaceResponse, error)
+	// StageSnapshot downloads a snapshot, e.g. a prebuild, into the node's content cache so that workspaces
+	// which start from it later on need not download it.
+	StageSnapshot(context.Context, *StageSnapshotRequest) (*StageSnapshotResponse, error)
 }
 
 // UnimplementedWorkspaceContentServiceServer can be embedded to have forward compatible implementations.
@@ -636,6 +765,9 @@ func (*UnimplementedWorkspaceContentServiceServer) TakeSnapshot(ctx context.Cont
 func (*UnimplementedWorkspaceContentServiceServer) DisposeWorkspace(ctx context.Context, req *DisposeWorkspaceRequest) (*DisposeWorkspaceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DisposeWorkspace not implemented")
 }
+func (*UnimplementedWorkspaceContentServiceServer) StageSnapshot(ctx context.Context, req *StageSnapshotRequest) (*StageSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StageSnapshot not implemented")
+}
 
 func RegisterWorkspaceContentServiceServer(s *grpc.Server, srv WorkspaceContentServiceServer) {
 	s.RegisterService(&_WorkspaceContentService_serviceDesc, srv)
@@ -713,6 +845,24 @@ func _WorkspaceContentService_DisposeWorkspace_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkspaceContentService_StageSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StageSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceContentServiceServer).StageSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsdaemon.WorkspaceContentService/StageSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceContentServiceServer).StageSnapshot(ctx, req.(*StageSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WorkspaceContentService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "wsdaemon.WorkspaceContentService",
 	HandlerType: (*WorkspaceContentServiceServer)(nil),
@@ -733,6 +883,10 @@ var _WorkspaceContentService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DisposeWorkspace",
 			Handler:    _WorkspaceContentService_DisposeWorkspace_Handler,
 		},
+		{
+			MethodName: "StageSnapshot",
+			Handler:    _WorkspaceContentService_StageSnapshot_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "daemon.proto",
@@ -0,0 +1,293 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: forensics.proto
+
+package api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// CaptureFilesystemSnapshotRequest captures a forensic snapshot of a workspace's filesystem
+type CaptureFilesystemSnapshotRequest struct {
+	// id is the instance ID of the workspace to snapshot
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// requester identifies the person on whose behalf we snapshot, e.g. their email address. It is audit logged.
+	Requester string `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	// reason explains why we snapshot, e.g. an abuse case. It is audit logged.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// hash_patterns select the files whose content we hash. Patterns are matched against the base name of files
+	// (e.g. "*.sh") or, if they contain a slash, against their path relative to the snapshot root (e.g. "/.gitpod.yml").
+	HashPatterns         []string `protobuf:"bytes,4,rep,name=hash_patterns,json=hashPatterns,proto3" json:"hash_patterns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CaptureFilesystemSnapshotRequest) Reset()         { *m = CaptureFilesystemSnapshotRequest{} }
+func (m *CaptureFilesystemSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*CaptureFilesystemSnapshotRequest) ProtoMessage()    {}
+func (*CaptureFilesystemSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e3ffd86e832000b, []int{0}
+}
+
+func (m *CaptureFilesystemSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CaptureFilesystemSnapshotRequest.Unmarshal(m, b)
+}
+func (m *CaptureFilesystemSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CaptureFilesystemSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *CaptureFilesystemSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CaptureFilesystemSnapshotRequest.Merge(m, src)
+}
+func (m *CaptureFilesystemSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_CaptureFilesystemSnapshotRequest.Size(m)
+}
+func (m *CaptureFilesystemSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CaptureFilesystemSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CaptureFilesystemSnapshotRequest proto.InternalMessageInfo
+
+func (m *CaptureFilesystemSnapshotRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CaptureFilesystemSnapshotRequest) GetRequester() string {
+	if m != nil {
+		return m.Requester
+	}
+	return ""
+}
+
+func (m *CaptureFilesystemSnapshotRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *CaptureFilesystemSnapshotRequest) GetHashPatterns() []string {
+	if m != nil {
+		return m.HashPatterns
+	}
+	return nil
+}
+
+type CaptureFilesystemSnapshotResponse struct {
+	// url is the fully qualified name of the uploaded snapshot in the remote storage
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// key_id identifies the key the snapshot is encrypted with
+	KeyId string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// files is the number of files in the snapshot
+	Files int64 `protobuf:"varint,3,opt,name=files,proto3" json:"files,omitempty"`
+	// hashed_files is the number of files whose content we hashed
+	HashedFiles int64 `protobuf:"varint,4,opt,name=hashed_files,json=hashedFiles,proto3" json:"hashed_files,omitempty"`
+	// truncated is true if the snapshot misses files because the workspace has more than the configured maximum
+	Truncated            bool     `protobuf:"varint,5,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CaptureFilesystemSnapshotResponse) Reset()         { *m = CaptureFilesystemSnapshotResponse{} }
+func (m *CaptureFilesystemSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*CaptureFilesystemSnapshotResponse) ProtoMessage()    {}
+func (*CaptureFilesystemSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0e3ffd86e832000b, []int{1}
+}
+
+func (m *CaptureFilesystemSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CaptureFilesystemSnapshotResponse.Unmarshal(m, b)
+}
+func (m *CaptureFilesystemSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CaptureFilesystemSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *CaptureFilesystemSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CaptureFilesystemSnapshotResponse.Merge(m, src)
+}
+func (m *CaptureFilesystemSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_CaptureFilesystemSnapshotResponse.Size(m)
+}
+func (m *CaptureFilesystemSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CaptureFilesystemSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CaptureFilesystemSnapshotResponse proto.InternalMessageInfo
+
+func (m *CaptureFilesystemSnapshotResponse) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *CaptureFilesystemSnapshotResponse) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *CaptureFilesystemSnapshotResponse) GetFiles() int64 {
+	if m != nil {
+		return m.Files
+	}
+	return 0
+}
+
+func (m *CaptureFilesystemSnapshotResponse) GetHashedFiles() int64 {
+	if m != nil {
+		return m.HashedFiles
+	}
+	return 0
+}
+
+func (m *CaptureFilesystemSnapshotResponse) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*CaptureFilesystemSnapshotRequest)(nil), "wsdaemon.CaptureFilesystemSnapshotRequest")
+	proto.RegisterType((*CaptureFilesystemSnapshotResponse)(nil), "wsdaemon.CaptureFilesystemSnapshotResponse")
+}
+
+func init() {
+	proto.RegisterFile("forensics.proto", fileDescriptor_0e3ffd86e832000b)
+}
+
+var fileDescriptor_0e3ffd86e832000b = []byte{
+	// 320 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x4d, 0x4f, 0xfa, 0x40,
+	0x10, 0xc6, 0xff, 0xa5, 0x40, 0x60, 0xfe, 0xbe, 0x90, 0x8d, 0x9a, 0x6a, 0x3c, 0x14, 0xbc, 0xa0,
+	0x84, 0x92, 0xe8, 0x37, 0xd0, 0x84, 0xc4, 0x9b, 0x29, 0x37, 0x2f, 0x64, 0xe9, 0x0e, 0x74, 0x03,
+	0x74, 0xd7, 0x9d, 0x2d, 0x84, 0xaf, 0x60, 0xe2, 0xa7, 0xf0, 0x8b, 0x9a, 0x6e, 0x4b, 0x38, 0xf9,
+	0x72, 0x9b, 0xf9, 0x3d, 0x9b, 0x99, 0x67, 0x9f, 0x0c, 0x9c, 0xce, 0x95, 0xc1, 0x8c, 0x64, 0x42,
+	0x91, 0x36, 0xca, 0x2a, 0xd6, 0xda, 0x92, 0xe0, 0xb8, 0x56, 0x59, 0xef, 0xc3, 0x83, 0xf0, 0x89,
+	0x6b, 0x9b, 0x1b, 0x1c, 0xcb, 0x15, 0xd2, 0x8e, 0x2c, 0xae, 0x27, 0x19, 0xd7, 0x94, 0x2a, 0x1b,
+	0xe3, 0x5b, 0x8e, 0x64, 0xd9, 0x09, 0xd4, 0xa4, 0x08, 0xbc, 0xd0, 0xeb, 0xb7, 0xe3, 0x9a, 0x14,
+	0xec, 0x1a, 0xda, 0xa6, 0x94, 0xd0, 0x04, 0x35, 0x87, 0x0f, 0x80, 0x5d, 0x40, 0xd3, 0x20, 0x27,
+	0x95, 0x05, 0xbe, 0x93, 0xaa, 0x8e, 0xdd, 0xc0, 0x71, 0xca, 0x29, 0x9d, 0x6a, 0x6e, 0x2d, 0x9a,
+	0x8c, 0x82, 0x7a, 0xe8, 0xf7, 0xdb, 0xf1, 0x51, 0x01, 0x5f, 0x2a, 0xd6, 0xfb, 0xf4, 0xa0, 0xfb,
+	0x83, 0x1f, 0xd2, 0x2a, 0x23, 0x64, 0x1d, 0xf0, 0x73, 0xb3, 0xaa, 0x1c, 0x15, 0x25, 0x3b, 0x87,
+	0xe6, 0x12, 0x77, 0x53, 0x29, 0x2a, 0x3f, 0x8d, 0x25, 0xee, 0x9e, 0x05, 0x3b, 0x83, 0xc6, 0xbc,
+	0x18, 0xe3, 0xac, 0xf8, 0x71, 0xd9, 0xb0, 0x2e, 0xb8, 0xa5, 0x28, 0xa6, 0xa5, 0x58, 0x77, 0xe2,
+	0xff, 0x92, 0xb9, 0xb5, 0xc5, 0x17, 0xad, 0xc9, 0xb3, 0x84, 0x5b, 0x14, 0x41, 0x23, 0xf4, 0xfa,
+	0xad, 0xf8, 0x00, 0xee, 0xdf, 0x3d, 0xe8, 0x8c, 0xf7, 0x99, 0x4e, 0xd0, 0x6c, 0x64, 0x82, 0x6c,
+	0x03, 0x97, 0xdf, 0x3a, 0x67, 0x77, 0xd1, 0x3e, 0xf2, 0xe8, 0xb7, 0xb8, 0xaf, 0x06, 0x7f, 0x7a,
+	0x5b, 0x46, 0xd1, 0xfb, 0xf7, 0x38, 0x78, 0xbd, 0x5d, 0x48, 0x9b, 0xe6, 0xb3, 0x28, 0x51, 0xeb,
+	0xd1, 0x42, 0x5a, 0xad, 0xc4, 0x50, 0xaa, 0xaa, 0x1a, 0x6d, 0x69, 0x58, 0x0e, 0x1b, 0x71, 0x2d,
+	0x67, 0x4d, 0x77, 0x00, 0x0f, 0x5f, 0x03, 0x00, 0x42, 0x40, 0x23, 0x10, 0x13, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ForensicsServiceClient is the client API for ForensicsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ForensicsServiceClient interface {
+	// CaptureFilesystemSnapshot records the metadata of all files of a workspace and the content hashes of selected
+	// files. It only reads from the workspace and does not involve anything running in it, hence the user does not
+	// notice. The snapshot is encrypted and uploaded to the remote storage. The call returns once it is uploaded.
+	CaptureFilesystemSnapshot(ctx context.Context, in *CaptureFilesystemSnapshotRequest, opts ...grpc.CallOption) (*CaptureFilesystemSnapshotResponse, error)
+}
+
+type forensicsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewForensicsServiceClient(cc grpc.ClientConnInterface) ForensicsServiceClient {
+	return &forensicsServiceClient{cc}
+}
+
+func (c *forensicsServiceClient) CaptureFilesystemSnapshot(ctx context.Context, in *CaptureFilesystemSnapshotRequest, opts ...grpc.CallOption) (*CaptureFilesystemSnapshotResponse, error) {
+	out := new(CaptureFilesystemSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/wsdaemon.ForensicsService/CaptureFilesystemSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ForensicsServiceServer is the server API for ForensicsService service.
+type ForensicsServiceServer interface {
+	// CaptureFilesystemSnapshot records the metadata of all files of a workspace and the content hashes of selected
+	// files. It only reads from the workspace and does not involve anything running in it, hence the user does not
+	// notice. The snapshot is encrypted and uploaded to the remote storage. The call returns once it is uploaded.
+	CaptureFilesystemSnapshot(context.Context, *CaptureFilesystemSnapshotRequest) (*CaptureFilesystemSnapshotResponse, error)
+}
+
+// UnimplementedForensicsServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedForensicsServiceServer struct {
+}
+
+func (*UnimplementedForensicsServiceServer) CaptureFilesystemSnapshot(ctx context.Context, req *CaptureFilesystemSnapshotRequest) (*CaptureFilesystemSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CaptureFilesystemSnapshot not implemented")
+}
+
+func RegisterForensicsServiceServer(s *grpc.Server, srv ForensicsServiceServer) {
+	s.RegisterService(&_ForensicsService_serviceDesc, srv)
+}
+
+func _ForensicsService_CaptureFilesystemSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptureFilesystemSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ForensicsServiceServer).CaptureFilesystemSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsdaemon.ForensicsService/CaptureFilesystemSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ForensicsServiceServer).CaptureFilesystemSnapshot(ctx, req.(*CaptureFilesystemSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ForensicsService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "wsdaemon.ForensicsService",
+	HandlerType: (*ForensicsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CaptureFilesystemSnapshot",
+			Handler:    _ForensicsService_CaptureFilesystemSnapshot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "forensics.proto",
+}
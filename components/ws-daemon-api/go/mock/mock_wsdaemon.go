@@ -119,6 +119,26 @@ func (mr *MockWorkspaceContentServiceClientMockRecorder) DisposeWorkspace(ctx, i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisposeWorkspace", reflect.TypeOf((*MockWorkspaceContentServiceClient)(nil).DisposeWorkspace), varargs...)
 }
 
+// StageSnapshot mocks base method
+func (m *MockWorkspaceContentServiceClient) StageSnapshot(ctx context.Context, in *api.StageSnapshotRequest, opts ...grpc.CallOption) (*api.StageSnapshotResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StageSnapshot", varargs...)
+	ret0, _ := ret[0].(*api.StageSnapshotResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StageSnapshot indicates an expected call of StageSnapshot
+func (mr *MockWorkspaceContentServiceClientMockRecorder) StageSnapshot(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StageSnapshot", reflect.TypeOf((*MockWorkspaceContentServiceClient)(nil).StageSnapshot), varargs...)
+}
+
 // MockWorkspaceContentServiceServer is a mock of WorkspaceContentServiceServer interface
 type MockWorkspaceContentServiceServer struct {
 	ctrl     *gomock.Controller
@@ -201,3 +221,18 @@ func (mr *MockWorkspaceContentServiceServerMockRecorder) DisposeWorkspace(arg0,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisposeWorkspace", reflect.TypeOf((*MockWorkspaceContentServiceServer)(nil).DisposeWorkspace), arg0, arg1)
 }
+
+// StageSnapshot mocks base method
+func (m *MockWorkspaceContentServiceServer) StageSnapshot(arg0 context.Context, arg1 *api.StageSnapshotRequest) (*api.StageSnapshotResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StageSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(*api.StageSnapshotResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StageSnapshot indicates an expected call of StageSnapshot
+func (mr *MockWorkspaceContentServiceServerMockRecorder) StageSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StageSnapshot", reflect.TypeOf((*MockWorkspaceContentServiceServer)(nil).StageSnapshot), arg0, arg1)
+}
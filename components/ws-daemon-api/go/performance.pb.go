@@ -0,0 +1,290 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: performance.proto
+
+package api
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetPerformanceStatusRequest struct {
+	// id is the instance ID of the workspace
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPerformanceStatusRequest) Reset()         { *m = GetPerformanceStatusRequest{} }
+func (m *GetPerformanceStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPerformanceStatusRequest) ProtoMessage()    {}
+func (*GetPerformanceStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6db4cf59bf07e440, []int{0}
+}
+
+func (m *GetPerformanceStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPerformanceStatusRequest.Unmarshal(m, b)
+}
+func (m *GetPerformanceStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPerformanceStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPerformanceStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPerformanceStatusRequest.Merge(m, src)
+}
+func (m *GetPerformanceStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPerformanceStatusRequest.Size(m)
+}
+func (m *GetPerformanceStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPerformanceStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPerformanceStatusRequest proto.InternalMessageInfo
+
+func (m *GetPerformanceStatusRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetPerformanceStatusResponse struct {
+	// workspace_class is the class of the workspace, i.e. its type
+	WorkspaceClass string `protobuf:"bytes,1,opt,name=workspace_class,json=workspaceClass,proto3" json:"workspace_class,omitempty"`
+	// numa_node is the NUMA node the workspace is pinned to, or -1 if it is not pinned
+	NumaNode int32 `protobuf:"varint,2,opt,name=numa_node,json=numaNode,proto3" json:"numa_node,omitempty"`
+	// cpus are the CPUs the workspace has for itself in cpuset list format, e.g. 8-15. Empty if it is not pinned.
+	Cpus string `protobuf:"bytes,3,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	// memory_bound is true if the workspace allocates its memory on its NUMA node only
+	MemoryBound bool `protobuf:"varint,4,opt,name=memory_bound,json=memoryBound,proto3" json:"memory_bound,omitempty"`
+	// huge_page_size is the size of the workspace's huge pages in bytes, e.g. 2097152
+	HugePageSize int64 `protobuf:"varint,5,opt,name=huge_page_size,json=hugePageSize,proto3" json:"huge_page_size,omitempty"`
+	// huge_pages_limit is how much memory in bytes the workspace may back with huge pages
+	HugePagesLimit int64 `protobuf:"varint,6,opt,name=huge_pages_limit,json=hugePagesLimit,proto3" json:"huge_pages_limit,omitempty"`
+	// huge_pages_used is how much memory in bytes the workspace backs with huge pages at the moment
+	HugePagesUsed int64 `protobuf:"varint,7,opt,name=huge_pages_used,json=hugePagesUsed,proto3" json:"huge_pages_used,omitempty"`
+	// unavailable_reason explains why the workspace runs without the resources its class asks for. Empty if it has them.
+	UnavailableReason    string   `protobuf:"bytes,8,opt,name=unavailable_reason,json=unavailableReason,proto3" json:"unavailable_reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPerformanceStatusResponse) Reset()         { *m = GetPerformanceStatusResponse{} }
+func (m *GetPerformanceStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPerformanceStatusResponse) ProtoMessage()    {}
+func (*GetPerformanceStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6db4cf59bf07e440, []int{1}
+}
+
+func (m *GetPerformanceStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPerformanceStatusResponse.Unmarshal(m, b)
+}
+func (m *GetPerformanceStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPerformanceStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPerformanceStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPerformanceStatusResponse.Merge(m, src)
+}
+func (m *GetPerformanceStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPerformanceStatusResponse.Size(m)
+}
+func (m *GetPerformanceStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPerformanceStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPerformanceStatusResponse proto.InternalMessageInfo
+
+func (m *GetPerformanceStatusResponse) GetWorkspaceClass() string {
+	if m != nil {
+		return m.WorkspaceClass
+	}
+	return ""
+}
+
+func (m *GetPerformanceStatusResponse) GetNumaNode() int32 {
+	if m != nil {
+		return m.NumaNode
+	}
+	return 0
+}
+
+func (m *GetPerformanceStatusResponse) GetCpus() string {
+	if m != nil {
+		return m.Cpus
+	}
+	return ""
+}
+
+func (m *GetPerformanceStatusResponse) GetMemoryBound() bool {
+	if m != nil {
+		return m.MemoryBound
+	}
+	return false
+}
+
+func (m *GetPerformanceStatusResponse) GetHugePageSize() int64 {
+	if m != nil {
+		return m.HugePageSize
+	}
+	return 0
+}
+
+func (m *GetPerformanceStatusResponse) GetHugePagesLimit() int64 {
+	if m != nil {
+		return m.HugePagesLimit
+	}
+	return 0
+}
+
+func (m *GetPerformanceStatusResponse) GetHugePagesUsed() int64 {
+	if m != nil {
+		return m.HugePagesUsed
+	}
+	return 0
+}
+
+func (m *GetPerformanceStatusResponse) GetUnavailableReason() string {
+	if m != nil {
+		return m.UnavailableReason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*GetPerformanceStatusRequest)(nil), "wsdaemon.GetPerformanceStatusRequest")
+	proto.RegisterType((*GetPerformanceStatusResponse)(nil), "wsdaemon.GetPerformanceStatusResponse")
+}
+
+func init() {
+	proto.RegisterFile("performance.proto", fileDescriptor_6db4cf59bf07e440)
+}
+
+var fileDescriptor_6db4cf59bf07e440 = []byte{
+	// 358 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xcd, 0xae, 0xd3, 0x30,
+	0x10, 0x46, 0x49, 0xee, 0x6d, 0x49, 0x4d, 0x49, 0xa9, 0xc5, 0xc2, 0xa2, 0x2c, 0x42, 0x05, 0x25,
+	0x08, 0x25, 0x95, 0xe0, 0x0d, 0xca, 0x82, 0x0d, 0x42, 0x55, 0x2a, 0x36, 0x6c, 0x22, 0x27, 0x1e,
+	0x52, 0x8b, 0xc4, 0x36, 0x19, 0xbb, 0x15, 0x95, 0x78, 0x39, 0x9e, 0x0c, 0x25, 0xfd, 0xa1, 0x8b,
+	0x0a, 0x76, 0xa3, 0x33, 0x67, 0x2c, 0x6b, 0xbe, 0x21, 0x53, 0x03, 0xed, 0x37, 0xdd, 0x36, 0x5c,
+	0x95, 0x90, 0x9a, 0x56, 0x5b, 0x4d, 0x83, 0x3d, 0x0a, 0x0e, 0x8d, 0x56, 0xf3, 0x84, 0xcc, 0x3e,
+	0x82, 0x5d, 0xff, 0x35, 0x36, 0x96, 0x5b, 0x87, 0x19, 0xfc, 0x70, 0x80, 0x96, 0x86, 0xc4, 0x97,
+	0x82, 0x79, 0x91, 0x17, 0x8f, 0x32, 0x5f, 0x8a, 0xf9, 0x6f, 0x9f, 0x3c, 0xbf, 0xed, 0xa3, 0xd1,
+	0x0a, 0x81, 0xbe, 0x26, 0x93, 0xbd, 0x6e, 0xbf, 0xa3, 0xe1, 0x25, 0xe4, 0x65, 0xcd, 0x11, 0x4f,
+	0xd3, 0xe1, 0x05, 0x7f, 0xe8, 0x28, 0x9d, 0x91, 0x91, 0x72, 0x0d, 0xcf, 0x95, 0x16, 0xc0, 0xfc,
+	0xc8, 0x8b, 0x07, 0x59, 0xd0, 0x81, 0xcf, 0x5a, 0x00, 0xa5, 0xe4, 0xbe, 0x34, 0x0e, 0xd9, 0x5d,
+	0x3f, 0xda, 0xd7, 0xf4, 0x05, 0x19, 0x37, 0xd0, 0xe8, 0xf6, 0x67, 0x5e, 0x68, 0xa7, 0x04, 0xbb,
+	0x8f, 0xbc, 0x38, 0xc8, 0x1e, 0x1d, 0xd9, 0xaa, 0x43, 0xf4, 0x25, 0x09, 0xb7, 0xae, 0x82, 0xdc,
+	0xf0, 0x0a, 0x72, 0x94, 0x07, 0x60, 0x83, 0xc8, 0x8b, 0xef, 0xb2, 0x71, 0x47, 0xd7, 0xbc, 0x82,
+	0x8d, 0x3c, 0x00, 0x8d, 0xc9, 0x93, 0x8b, 0x85, 0x79, 0x2d, 0x1b, 0x69, 0xd9, 0xb0, 0xf7, 0xc2,
+	0xb3, 0x87, 0x9f, 0x3a, 0x4a, 0x17, 0x64, 0x72, 0x65, 0x3a, 0x04, 0xc1, 0x1e, 0xf6, 0xe2, 0xe3,
+	0x8b, 0xf8, 0x05, 0x41, 0xd0, 0x84, 0x50, 0xa7, 0xf8, 0x8e, 0xcb, 0x9a, 0x17, 0x35, 0xe4, 0x2d,
+	0x70, 0xd4, 0x8a, 0x05, 0xfd, 0xe7, 0xa7, 0x57, 0x9d, 0xac, 0x6f, 0xbc, 0xfb, 0x45, 0xe8, 0xf5,
+	0x02, 0xa1, 0xdd, 0xc9, 0x12, 0x68, 0x45, 0x9e, 0xde, 0xda, 0x2c, 0x7d, 0x95, 0x9e, 0xc3, 0x4a,
+	0xff, 0x91, 0xd4, 0xb3, 0xc5, 0xff, 0xb4, 0x63, 0x40, 0xf3, 0x07, 0xab, 0xb7, 0x5f, 0xdf, 0x54,
+	0xd2, 0x6e, 0x5d, 0x91, 0x96, 0xba, 0x59, 0x56, 0xd2, 0x1a, 0x2d, 0x12, 0xa9, 0x4f, 0xd5, 0x72,
+	0x8f, 0xc9, 0xf1, 0x9d, 0x25, 0x37, 0xb2, 0x18, 0xf6, 0x07, 0xf3, 0xfe, 0xcf, 0x00, 0x19, 0x96,
+	0x20, 0x30, 0x45, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PerformanceServiceClient is the client API for PerformanceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PerformanceServiceClient interface {
+	// GetPerformanceStatus returns what the node dedicated to a workspace, or why it runs without
+	GetPerformanceStatus(ctx context.Context, in *GetPerformanceStatusRequest, opts ...grpc.CallOption) (*GetPerformanceStatusResponse, error)
+}
+
+type performanceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPerformanceServiceClient(cc grpc.ClientConnInterface) PerformanceServiceClient {
+	return &performanceServiceClient{cc}
+}
+
+func (c *performanceServiceClient) GetPerformanceStatus(ctx context.Context, in *GetPerformanceStatusRequest, opts ...grpc.CallOption) (*GetPerformanceStatusResponse, error) {
+	out := new(GetPerformanceStatusResponse)
+	err := c.cc.Invoke(ctx, "/wsdaemon.PerformanceService/GetPerformanceStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PerformanceServiceServer is the server API for PerformanceService service.
+type PerformanceServiceServer interface {
+	// GetPerformanceStatus returns what the node dedicated to a workspace, or why it runs without
+	GetPerformanceStatus(context.Context, *GetPerformanceStatusRequest) (*GetPerformanceStatusResponse, error)
+}
+
+// UnimplementedPerformanceServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPerformanceServiceServer struct {
+}
+
+func (*UnimplementedPerformanceServiceServer) GetPerformanceStatus(ctx context.Context, req *GetPerformanceStatusRequest) (*GetPerformanceStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPerformanceStatus not implemented")
+}
+
+func RegisterPerformanceServiceServer(s *grpc.Server, srv PerformanceServiceServer) {
+	s.RegisterService(&_PerformanceService_serviceDesc, srv)
+}
+
+func _PerformanceService_GetPerformanceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPerformanceStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PerformanceServiceServer).GetPerformanceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsdaemon.PerformanceService/GetPerformanceStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PerformanceServiceServer).GetPerformanceStatus(ctx, req.(*GetPerformanceStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PerformanceService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "wsdaemon.PerformanceService",
+	HandlerType: (*PerformanceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPerformanceStatus",
+			Handler:    _PerformanceService_GetPerformanceStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "performance.proto",
+}
@@ -55,6 +55,28 @@ function deserialize_wsdaemon_InitWorkspaceResponse(buffer_arg) {
   return daemon_pb.InitWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsdaemon_StageSnapshotRequest(arg) {
+  if (!(arg instanceof daemon_pb.StageSnapshotRequest)) {
+    throw new Error('Expected argument of type wsdaemon.StageSnapshotRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsdaemon_StageSnapshotRequest(buffer_arg) {
+  return daemon_pb.StageSnapshotRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsdaemon_StageSnapshotResponse(arg) {
+  if (!(arg instanceof daemon_pb.StageSnapshotResponse)) {
+    throw new Error('Expected argument of type wsdaemon.StageSnapshotResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsdaemon_StageSnapshotResponse(buffer_arg) {
+  return daemon_pb.StageSnapshotResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsdaemon_TakeSnapshotRequest(arg) {
   if (!(arg instanceof daemon_pb.TakeSnapshotRequest)) {
     throw new Error('Expected argument of type wsdaemon.TakeSnapshotRequest');
@@ -151,6 +173,19 @@ disposeWorkspace: {
     responseSerialize: serialize_wsdaemon_DisposeWorkspaceResponse,
     responseDeserialize: deserialize_wsdaemon_DisposeWorkspaceResponse,
   },
+  // StageSnapshot downloads a snapshot, e.g. a prebuild, into the node's content cache so that workspaces
+// which start from it later on need not download it.
+stageSnapshot: {
+    path: '/wsdaemon.WorkspaceContentService/StageSnapshot',
+    requestStream: false,
+    responseStream: false,
+    requestType: daemon_pb.StageSnapshotRequest,
+    responseType: daemon_pb.StageSnapshotResponse,
+    requestSerialize: serialize_wsdaemon_StageSnapshotRequest,
+    requestDeserialize: deserialize_wsdaemon_StageSnapshotRequest,
+    responseSerialize: serialize_wsdaemon_StageSnapshotResponse,
+    responseDeserialize: deserialize_wsdaemon_StageSnapshotResponse,
+  },
 };
 
 exports.WorkspaceContentServiceClient = grpc.makeGenericClientConstructor(WorkspaceContentServiceService);
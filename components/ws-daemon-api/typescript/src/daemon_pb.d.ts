@@ -218,6 +218,58 @@ export namespace DisposeWorkspaceResponse {
   }
 }
 
+export class StageSnapshotRequest extends jspb.Message {
+  getSnapshot(): string;
+  setSnapshot(value: string): void;
+
+  getUrl(): string;
+  setUrl(value: string): void;
+
+  getSize(): number;
+  setSize(value: number): void;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): StageSnapshotRequest.AsObject;
+  static toObject(includeInstance: boolean, msg: StageSnapshotRequest): StageSnapshotRequest.AsObject;
+  static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+  static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+  static serializeBinaryToWriter(message: StageSnapshotRequest, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): StageSnapshotRequest;
+  static deserializeBinaryFromReader(message: StageSnapshotRequest, reader: jspb.BinaryReader): StageSnapshotRequest;
+}
+
+export namespace StageSnapshotRequest {
+  export type AsObject = {
+    snapshot: string,
+    url: string,
+    size: number,
+  }
+}
+
+export class StageSnapshotResponse extends jspb.Message {
+  getAlreadyStaged(): boolean;
+  setAlreadyStaged(value: boolean): void;
+
+  getSize(): number;
+  setSize(value: number): void;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): StageSnapshotResponse.AsObject;
+  static toObject(includeInstance: boolean, msg: StageSnapshotResponse): StageSnapshotResponse.AsObject;
+  static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+  static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+  static serializeBinaryToWriter(message: StageSnapshotResponse, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): StageSnapshotResponse;
+  static deserializeBinaryFromReader(message: StageSnapshotResponse, reader: jspb.BinaryReader): StageSnapshotResponse;
+}
+
+export namespace StageSnapshotResponse {
+  export type AsObject = {
+    alreadyStaged: boolean,
+    size: number,
+  }
+}
+
 export interface WorkspaceContentStateMap {
   NONE: 0;
   SETTING_UP: 1;
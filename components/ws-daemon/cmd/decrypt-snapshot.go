@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/forensics"
+)
+
+var decryptSnapshotOpts struct {
+	KeyFile string
+}
+
+// decryptSnapshotCmd prints a forensic filesystem snapshot as JSON
+var decryptSnapshotCmd = &cobra.Command{
+	Use:   "decrypt-snapshot <file>",
+	Short: "decrypts a forensic filesystem snapshot and prints it as JSON",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, err := forensics.LoadKey(decryptSnapshotOpts.KeyFile)
+		if err != nil {
+			log.WithError(err).Fatal("cannot load key")
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.WithError(err).Fatal("cannot open snapshot")
+		}
+		defer f.Close()
+
+		snapshot, keyID, err := forensics.ReadSnapshot(f, key)
+		if err != nil {
+			log.WithError(err).WithField("keyID", keyID).Fatal("cannot read snapshot")
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(snapshot)
+		if err != nil {
+			log.WithError(err).Fatal("cannot print snapshot")
+		}
+	},
+}
+
+func init() {
+	decryptSnapshotCmd.Flags().StringVar(&decryptSnapshotOpts.KeyFile, "key-file", "", "file containing the hex-encoded key the snapshot is encrypted with")
+	_ = decryptSnapshotCmd.MarkFlagRequired("key-file")
+	rootCmd.AddCommand(decryptSnapshotCmd)
+}
@@ -0,0 +1,364 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package cleanup reconciles the workspaces we expect on a node with the mounts, cgroups and network namespaces
+// that actually exist there, and removes the ones that workspaces left behind.
+package cleanup
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultInterval          = 5 * time.Minute
+	defaultGracePeriod       = 15 * time.Minute
+	defaultMaxCleanupsPerRun = 10
+)
+
+// Config configures the leak reconciler
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Interval is the time between two reconciliation runs. Defaults to 5 minutes.
+	Interval util.Duration `json:"interval,omitempty"`
+	// GracePeriod is how long a resource must have been leaked before we remove it. Defaults to 15 minutes.
+	GracePeriod util.Duration `json:"gracePeriod,omitempty"`
+	// MaxCleanupsPerRun limits the number of resources we remove in a single run. Defaults to 10.
+	MaxCleanupsPerRun int `json:"maxCleanupsPerRun,omitempty"`
+	// DryRun records and reports leaks, but never removes them
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// CGroupBasePath is where ws-daemon sees the node's cgroup filesystem. Empty disables cgroup reconciliation.
+	CGroupBasePath string `json:"cgroupBasePath,omitempty"`
+	// NetNSPath is the directory of named network namespaces on the node, e.g. /run/netns.
+	// Empty disables network namespace reconciliation.
+	NetNSPath string `json:"netnsPath,omitempty"`
+}
+
+// ResourceKind names a kind of node resource workspaces can leak
+type ResourceKind string
+
+const (
+	// ResourceMount is a mount in the workspace working area
+	ResourceMount ResourceKind = "mount"
+	// ResourceCGroup is the cgroup of a pod
+	ResourceCGroup ResourceKind = "cgroup"
+	// ResourceNetNS is a named network namespace
+	ResourceNetNS ResourceKind = "netns"
+	// ResourceSwapFile is the swap file of a workspace
+	ResourceSwapFile ResourceKind = "swapfile"
+	// ResourceReservation is the CPU and huge page reservation of a performance-class workspace
+	ResourceReservation ResourceKind = "reservation"
+)
+
+// Resource is a node resource a workspace may leak
+type Resource struct {
+	Kind ResourceKind
+	Path string
+	// InstanceID is the workspace instance the resource belongs to, if known
+	InstanceID string
+	// InUse is true if the resource is evidently still in use, e.g. a cgroup with processes in it
+	InUse bool
+}
+
+// Source discovers and removes a kind of node resource
+type Source interface {
+	Kind() ResourceKind
+	// List returns all resources of this kind which belong to workspaces
+	List() ([]Resource, error)
+	// Remove cleans up a leaked resource
+	Remove(res Resource) error
+}
+
+// LedgerEntry records a leaked resource from its discovery until its removal
+type LedgerEntry struct {
+	Kind       ResourceKind `json:"kind"`
+	Path       string       `json:"path"`
+	InstanceID string       `json:"instanceId,omitempty"`
+	FirstSeen  time.Time    `json:"firstSeen"`
+	LastSeen   time.Time    `json:"lastSeen"`
+	Attempts   int          `json:"attempts"`
+	LastError  string       `json:"lastError,omitempty"`
+}
+
+// Reconciler regularly compares the workspaces we expect on this node with the resources that exist on it.
+// Resources which are not in use and belong to no expected workspace are recorded in the ledger.
+// Once they've been leaked for longer than the grace period, we remove them.
+type Reconciler struct {
+	Config Config
+	// IsExpected returns true if the workspace instance is supposed to exist on this node
+	IsExpected func(instanceID string) bool
+	Sources    []Source
+
+	mu     sync.Mutex
+	ledger map[string]*LedgerEntry
+	stop   chan struct{}
+	once   sync.Once
+
+	now     func() time.Time
+	metrics *metrics
+}
+
+// NewReconciler creates a new reconciler. Call Start to begin reconciling.
+func NewReconciler(cfg Config, isExpected func(instanceID string) bool, sources ...Source) *Reconciler {
+	if cfg.Interval == 0 {
+		cfg.Interval = util.Duration(defaultInterval)
+	}
+	if cfg.GracePeriod == 0 {
+		cfg.GracePeriod = util.Duration(defaultGracePeriod)
+	}
+	if cfg.MaxCleanupsPerRun == 0 {
+		cfg.MaxCleanupsPerRun = defaultMaxCleanupsPerRun
+	}
+
+	return &Reconciler{
+		Config:     cfg,
+		IsExpected: isExpected,
+		Sources:    sources,
+		ledger:     make(map[string]*LedgerEntry),
+		stop:       make(chan struct{}),
+		now:        time.Now,
+		metrics:    newMetrics(),
+	}
+}
+
+// Start runs the reconciliation loop until Close is called
+func (r *Reconciler) Start() {
+	t := time.NewTicker(time.Duration(r.Config.Interval))
+	defer t.Stop()
+	for {
+		err := r.Reconcile()
+		if err != nil {
+			log.WithError(err).Warn("leak reconciliation incomplete")
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Close stops the reconciliation loop
+func (r *Reconciler) Close() error {
+	r.once.Do(func() { close(r.stop) })
+	return nil
+}
+
+// Reconcile runs a single reconciliation: it updates the ledger and removes resources beyond their grace period
+func (r *Reconciler) Reconcile() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		now     = r.now()
+		seen    = make(map[string]struct{})
+		leaked  = make(map[ResourceKind]int)
+		removed int
+		errs    []error
+	)
+	for _, src := range r.Sources {
+		// make sure we report zero drift for all kinds we reconcile
+		if _, ok := leaked[src.Kind()]; !ok {
+			leaked[src.Kind()] = 0
+		}
+
+		resources, err := src.List()
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("cannot list %s resources: %w", src.Kind(), err))
+			// we don't know what exists - keep the ledger entries of this kind as they are
+			for k, e := range r.ledger {
+				if e.Kind == src.Kind() {
+					seen[k] = struct{}{}
+					leaked[e.Kind]++
+				}
+			}
+			continue
+		}
+
+		for _, res := range resources {
+			if !r.isLeaked(res) {
+				continue
+			}
+
+			key := string(res.Kind) + ":" + res.Path
+			seen[key] = struct{}{}
+			entry, exists := r.ledger[key]
+			if !exists {
+				entry = &LedgerEntry{Kind: res.Kind, Path: res.Path, InstanceID: res.InstanceID, FirstSeen: now}
+				r.ledger[key] = entry
+				log.WithField("kind", res.Kind).WithField("path", res.Path).WithField("instanceId", res.InstanceID).Info("found leaked resource")
+			}
+			entry.LastSeen = now
+			leaked[res.Kind]++
+
+			if r.Config.DryRun || now.Sub(entry.FirstSeen) < time.Duration(r.Config.GracePeriod) {
+				continue
+			}
+			if removed >= r.Config.MaxCleanupsPerRun {
+				continue
+			}
+			// the workspace might have come back while we were busy - check again right before we remove anything
+			if !r.isLeaked(res) {
+				continue
+			}
+
+			removed++
+			entry.Attempts++
+			err := src.Remove(res)
+			if err != nil {
+				entry.LastError = err.Error()
+				r.metrics.Failures.WithLabelValues(string(res.Kind)).Inc()
+				errs = append(errs, xerrors.Errorf("cannot remove %s %s: %w", res.Kind, res.Path, err))
+				continue
+			}
+
+			log.WithField("kind", res.Kind).WithField("path", res.Path).WithField("instanceId", res.InstanceID).WithField("leakedSince", entry.FirstSeen).Info("removed leaked resource")
+			r.metrics.Removed.WithLabelValues(string(res.Kind)).Inc()
+			delete(r.ledger, key)
+			delete(seen, key)
+			leaked[res.Kind]--
+		}
+	}
+
+	// resources which disappeared on their own aren't leaked anymore
+	for k := range r.ledger {
+		if _, ok := seen[k]; !ok {
+			delete(r.ledger, k)
+		}
+	}
+	for kind, n := range leaked {
+		r.metrics.Leaked.WithLabelValues(string(kind)).Set(float64(n))
+	}
+	r.metrics.Runs.Inc()
+
+	if len(errs) > 0 {
+		return xerrors.Errorf("%d errors, first: %w", len(errs), errs[0])
+	}
+	return nil
+}
+
+// RemoveInstance removes the resources of a workspace instance right away instead of waiting for the grace period.
+// We call it once a workspace is disposed. Resources which are still in use are left to the reconciliation loop.
+func (r *Reconciler) RemoveInstance(ctx context.Context, instanceID string) error {
+	if r.Config.DryRun || instanceID == "" {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for _, src := range r.Sources {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		resources, err := src.List()
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("cannot list %s resources: %w", src.Kind(), err))
+			continue
+		}
+		for _, res := range resources {
+			if res.InstanceID != instanceID || res.InUse {
+				continue
+			}
+
+			err := src.Remove(res)
+			if err != nil {
+				r.metrics.Failures.WithLabelValues(string(res.Kind)).Inc()
+				errs = append(errs, xerrors.Errorf("cannot remove %s %s: %w", res.Kind, res.Path, err))
+				continue
+			}
+			log.WithField("kind", res.Kind).WithField("path", res.Path).WithField("instanceId", res.InstanceID).Debug("removed resource of disposed workspace")
+			r.metrics.Removed.WithLabelValues(string(res.Kind)).Inc()
+			delete(r.ledger, string(res.Kind)+":"+res.Path)
+		}
+	}
+
+	if len(errs) > 0 {
+		return xerrors.Errorf("%d errors, first: %w", len(errs), errs[0])
+	}
+	return nil
+}
+
+func (r *Reconciler) isLeaked(res Resource) bool {
+	if res.InUse {
+		return false
+	}
+	if res.InstanceID != "" && r.IsExpected != nil && r.IsExpected(res.InstanceID) {
+		return false
+	}
+	return true
+}
+
+// Ledger returns the resources which are currently leaked, oldest first
+func (r *Reconciler) Ledger() []LedgerEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]LedgerEntry, 0, len(r.ledger))
+	for _, e := range r.ledger {
+		res = append(res, *e)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].FirstSeen.Equal(res[j].FirstSeen) {
+			return res[i].Path < res[j].Path
+		}
+		return res[i].FirstSeen.Before(res[j].FirstSeen)
+	})
+	return res
+}
+
+// RegisterMetrics registers the drift and cleanup metrics
+func (r *Reconciler) RegisterMetrics(reg prometheus.Registerer) error {
+	return r.metrics.Register(reg)
+}
+
+type metrics struct {
+	Leaked   *prometheus.GaugeVec
+	Removed  *prometheus.CounterVec
+	Failures *prometheus.CounterVec
+	Runs     prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		Leaked: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cleanup_leaked_resources",
+			Help: "Number of resources on the node which belong to no expected workspace",
+		}, []string{"kind"}),
+		Removed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cleanup_removed_resources_total",
+			Help: "Number of leaked resources removed from the node",
+		}, []string{"kind"}),
+		Failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cleanup_failures_total",
+			Help: "Number of failed attempts to remove a leaked resource",
+		}, []string{"kind"}),
+		Runs: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cleanup_reconciliations_total",
+			Help: "Number of leak reconciliation runs",
+		}),
+	}
+}
+
+func (m *metrics) Register(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.Leaked, m.Removed, m.Failures, m.Runs} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
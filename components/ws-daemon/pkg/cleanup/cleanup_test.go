@@ -0,0 +1,299 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cleanup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	instanceA = "a6ad8fb2-37f3-4f4c-9e1b-9be0f6b9a5a1"
+	instanceB = "b3c0e7f1-54a2-4b7e-8f4d-0c1e2d3f4a5b"
+)
+
+type fakeSource struct {
+	Resources []Resource
+	Removed   []string
+}
+
+func (s *fakeSource) Kind() ResourceKind { return ResourceMount }
+
+func (s *fakeSource) List() ([]Resource, error) { return s.Resources, nil }
+
+func (s *fakeSource) Remove(res Resource) error {
+	s.Removed = append(s.Removed, res.Path)
+	var rest []Resource
+	for _, r := range s.Resources {
+		if r.Path != res.Path {
+			rest = append(rest, r)
+		}
+	}
+	s.Resources = rest
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Config          Config
+		Expected        map[string]bool
+		Resources       []Resource
+		ExpectedRemoved []string
+		ExpectedLedger  []string
+	}{
+		{
+			Name:     "removes leaks beyond the grace period",
+			Expected: map[string]bool{instanceA: true},
+			Resources: []Resource{
+				{Kind: ResourceMount, Path: "/area/" + instanceA, InstanceID: instanceA},
+				{Kind: ResourceMount, Path: "/area/" + instanceB, InstanceID: instanceB},
+			},
+			ExpectedRemoved: []string{"/area/" + instanceB},
+		},
+		{
+			Name:            "keeps resources in use",
+			Resources:       []Resource{{Kind: ResourceMount, Path: "/sys/fs/cgroup/pod", InUse: true}},
+			ExpectedRemoved: nil,
+		},
+		{
+			Name:   "dry run",
+			Config: Config{DryRun: true},
+			Resources: []Resource{
+				{Kind: ResourceMount, Path: "/area/" + instanceB, InstanceID: instanceB},
+			},
+			ExpectedLedger: []string{"/area/" + instanceB},
+		},
+		{
+			Name:   "limits cleanups per run",
+			Config: Config{MaxCleanupsPerRun: 1},
+			Resources: []Resource{
+				{Kind: ResourceMount, Path: "/area/" + instanceA, InstanceID: instanceA},
+				{Kind: ResourceMount, Path: "/area/" + instanceB, InstanceID: instanceB},
+			},
+			ExpectedRemoved: []string{"/area/" + instanceA},
+			ExpectedLedger:  []string{"/area/" + instanceB},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			src := &fakeSource{Resources: test.Resources}
+			r := NewReconciler(test.Config, func(instanceID string) bool { return test.Expected[instanceID] }, src)
+			now := time.Now()
+			r.now = func() time.Time { return now }
+
+			// the first run only records the leaks
+			err := r.Reconcile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(src.Removed) != 0 {
+				t.Fatalf("removed resources within their grace period: %v", src.Removed)
+			}
+
+			now = now.Add(time.Duration(r.Config.GracePeriod))
+			err = r.Reconcile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.ExpectedRemoved, src.Removed); diff != "" {
+				t.Errorf("unexpected removals (-want +got):\n%s", diff)
+			}
+
+			var ledger []string
+			for _, e := range r.Ledger() {
+				ledger = append(ledger, e.Path)
+			}
+			if diff := cmp.Diff(test.ExpectedLedger, ledger); diff != "" {
+				t.Errorf("unexpected ledger (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileWorkspaceReturns(t *testing.T) {
+	src := &fakeSource{Resources: []Resource{{Kind: ResourceMount, Path: "/area/" + instanceA, InstanceID: instanceA}}}
+	var expected bool
+	r := NewReconciler(Config{GracePeriod: util.Duration(time.Minute)}, func(string) bool { return expected }, src)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	_ = r.Reconcile()
+	if len(r.Ledger()) != 1 {
+		t.Fatalf("expected leak to be recorded")
+	}
+
+	// the workspace shows up (e.g. dispatch was late) - the resource is no longer leaked
+	expected = true
+	now = now.Add(2 * time.Minute)
+	_ = r.Reconcile()
+	if len(src.Removed) != 0 || len(r.Ledger()) != 0 {
+		t.Errorf("expected resource to be kept and forgotten, removed: %v, ledger: %v", src.Removed, r.Ledger())
+	}
+}
+
+func TestRemoveInstance(t *testing.T) {
+	src := &fakeSource{Resources: []Resource{
+		{Kind: ResourceMount, Path: "/area/" + instanceA, InstanceID: instanceA},
+		{Kind: ResourceMount, Path: "/area/" + instanceA + "-busy", InstanceID: instanceA, InUse: true},
+		{Kind: ResourceMount, Path: "/area/" + instanceB, InstanceID: instanceB},
+	}}
+	// the disposed workspace is still expected, e.g. because its pod is still terminating
+	r := NewReconciler(Config{}, func(string) bool { return true }, src)
+
+	err := r.RemoveInstance(context.Background(), instanceA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"/area/" + instanceA}, src.Removed); diff != "" {
+		t.Errorf("unexpected removals (-want +got):\n%s", diff)
+	}
+}
+
+func TestMountSourceList(t *testing.T) {
+	mounts := `/dev/sda1 /mnt/workingarea ext4 rw,relatime 0 0
+/dev/loop0 /mnt/workingarea/` + instanceA + ` ext4 rw,relatime,discard 0 0
+shiftfs /mnt/workingarea/` + instanceB + `-daemon/mark shiftfs rw,relatime,mark 0 0
+/dev/loop1 /mnt/workingarea/not-a-workspace ext4 rw,relatime 0 0
+/dev/sda1 /mnt/workingarea-other/` + instanceA + ` ext4 rw,relatime 0 0
+proc /proc proc rw 0 0`
+	fn := filepath.Join(t.TempDir(), "mounts")
+	err := os.WriteFile(fn, []byte(mounts), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &MountSource{MountsFile: fn, WorkingArea: "/mnt/workingarea"}
+	act, err := src.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := []Resource{
+		{Kind: ResourceMount, Path: "/mnt/workingarea/" + instanceB + "-daemon/mark", InstanceID: instanceB},
+		{Kind: ResourceMount, Path: "/mnt/workingarea/" + instanceA, InstanceID: instanceA},
+	}
+	if diff := cmp.Diff(expectation, act); diff != "" {
+		t.Errorf("unexpected mounts (-want +got):\n%s", diff)
+	}
+}
+
+func TestCGroupSourceList(t *testing.T) {
+	base := t.TempDir()
+	cgroups := map[string]string{
+		"cpu/kubepods/burstable/pod0f2b3c4d-1e2f-4a5b-8c7d-9e0f1a2b3c4d/cgroup.procs":         "",
+		"cpu/kubepods/burstable/pod0f2b3c4d-1e2f-4a5b-8c7d-9e0f1a2b3c4d/abcdef/cgroup.procs":  "",
+		"cpu/kubepods/besteffort/pod1f2b3c4d-1e2f-4a5b-8c7d-9e0f1a2b3c4d/cgroup.procs":        "",
+		"cpu/kubepods/besteffort/pod1f2b3c4d-1e2f-4a5b-8c7d-9e0f1a2b3c4d/abcdef/cgroup.procs": "1234\n",
+		"cpu/system.slice/cgroup.procs": "1\n",
+	}
+	for fn, content := range cgroups {
+		fn = filepath.Join(base, fn)
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fn, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src := &CGroupSource{BasePath: base}
+	act, err := src.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := []Resource{
+		{Kind: ResourceCGroup, Path: filepath.Join(base, "cpu/kubepods/besteffort/pod1f2b3c4d-1e2f-4a5b-8c7d-9e0f1a2b3c4d"), InUse: true},
+		{Kind: ResourceCGroup, Path: filepath.Join(base, "cpu/kubepods/burstable/pod0f2b3c4d-1e2f-4a5b-8c7d-9e0f1a2b3c4d")},
+	}
+	if diff := cmp.Diff(expectation, act); diff != "" {
+		t.Errorf("unexpected cgroups (-want +got):\n%s", diff)
+	}
+}
+
+func TestSwapFileSource(t *testing.T) {
+	base := t.TempDir()
+	for _, name := range []string{instanceA + ".swap", instanceB + ".tmp", "not-a-workspace.swap"} {
+		err := os.WriteFile(filepath.Join(base, name), nil, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var swappedOff []string
+	src := &SwapFileSource{Path: base, Suffix: ".swap", SwapOff: func(path string) error {
+		swappedOff = append(swappedOff, path)
+		return nil
+	}}
+	act, err := src.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := []Resource{
+		{Kind: ResourceSwapFile, Path: filepath.Join(base, instanceA+".swap"), InstanceID: instanceA},
+	}
+	if diff := cmp.Diff(expectation, act); diff != "" {
+		t.Fatalf("unexpected swap files (-want +got):\n%s", diff)
+	}
+
+	err = src.Remove(act[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(act[0].Path); !os.IsNotExist(err) || len(swappedOff) != 1 {
+		t.Errorf("expected swap file to be swapped off and removed, got %v and %v", swappedOff, err)
+	}
+	err = src.Remove(Resource{Kind: ResourceSwapFile, Path: filepath.Join(base, "..", "passwd")})
+	if err == nil {
+		t.Error("expected files outside the swap file location not to be removed")
+	}
+}
+
+func TestReservationSource(t *testing.T) {
+	base := t.TempDir()
+	for _, name := range []string{instanceA + ".json", instanceB + ".json.tmp", "not-a-workspace.json"} {
+		err := os.WriteFile(filepath.Join(base, name), nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var released []string
+	src := &ReservationSource{Path: base, Suffix: ".json", Release: func(instanceID string) error {
+		released = append(released, instanceID)
+		return nil
+	}}
+	act, err := src.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := []Resource{
+		{Kind: ResourceReservation, Path: filepath.Join(base, instanceA+".json"), InstanceID: instanceA},
+	}
+	if diff := cmp.Diff(expectation, act); diff != "" {
+		t.Fatalf("unexpected reservations (-want +got):\n%s", diff)
+	}
+
+	err = src.Remove(act[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{instanceA}, released); diff != "" {
+		t.Errorf("unexpected releases (-want +got):\n%s", diff)
+	}
+	err = src.Remove(Resource{Kind: ResourceReservation, Path: filepath.Join(base, "not-a-workspace.json"), InstanceID: "not-a-workspace"})
+	if err == nil {
+		t.Error("expected files not named after an instance not to be released")
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cleanup
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+
+	"golang.org/x/xerrors"
+)
+
+// instanceIDPattern matches workspace instance IDs. We never touch anything in the working area that isn't named after one.
+var instanceIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// MountSource finds mounts in the workspace working area, e.g. content sandboxes or shiftfs marks
+type MountSource struct {
+	// MountsFile lists the mounts of the mount namespace to reconcile, e.g. /proc/self/mounts
+	MountsFile string
+	// WorkingArea is the workspace working area as seen from that mount namespace
+	WorkingArea string
+	// Unmount unmounts a path in that mount namespace
+	Unmount func(path string) error
+}
+
+// Kind returns ResourceMount
+func (s *MountSource) Kind() ResourceKind {
+	return ResourceMount
+}
+
+// List returns all mounts in the working area, the deepest mounts first so that we unmount them in the right order
+func (s *MountSource) List() ([]Resource, error) {
+	f, err := os.Open(s.MountsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	area := filepath.Clean(s.WorkingArea) + "/"
+	var res []Resource
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		mp := fields[1]
+		if !strings.HasPrefix(mp, area) {
+			continue
+		}
+
+		// workspaces mount <area>/<instanceID> and <area>/<instanceID>-daemon/...
+		segs := strings.SplitN(strings.TrimPrefix(mp, area), "/", 2)
+		instanceID := strings.TrimSuffix(segs[0], "-daemon")
+		if !instanceIDPattern.MatchString(instanceID) {
+			continue
+		}
+		res = append(res, Resource{Kind: ResourceMount, Path: mp, InstanceID: instanceID})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return strings.Count(res[i].Path, "/") > strings.Count(res[j].Path, "/") })
+	return res, nil
+}
+
+// Remove unmounts the leaked mount
+func (s *MountSource) Remove(res Resource) error {
+	return s.Unmount(res.Path)
+}
+
+// podCGroupPattern matches the cgroups kubelet creates for pods with the cgroupfs and systemd drivers
+var podCGroupPattern = regexp.MustCompile(`^(pod[0-9a-f-]{36}|kubepods(-[a-z]+)?-pod[0-9a-f_]{36}\.slice)$`)
+
+// CGroupSource finds pod cgroups without processes. kubelet removes the cgroups of pods it knows about,
+// hence a pod cgroup which stays empty beyond the grace period has been leaked.
+type CGroupSource struct {
+	// BasePath is where the cgroup filesystem is mounted, e.g. /sys/fs/cgroup
+	BasePath string
+}
+
+// Kind returns ResourceCGroup
+func (s *CGroupSource) Kind() ResourceKind {
+	return ResourceCGroup
+}
+
+// List returns all pod cgroups
+func (s *CGroupSource) List() ([]Resource, error) {
+	var res []Resource
+	err := filepath.Walk(s.BasePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				// cgroups come and go while we walk the tree
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if !podCGroupPattern.MatchString(info.Name()) {
+			return nil
+		}
+
+		inUse, err := cgroupHasProcesses(path)
+		if err != nil {
+			return err
+		}
+		res = append(res, Resource{Kind: ResourceCGroup, Path: path, InUse: inUse})
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Remove removes the pod cgroup and all its children. The kernel refuses to remove cgroups with processes in them.
+func (s *CGroupSource) Remove(res Resource) error {
+	if !strings.HasPrefix(res.Path, filepath.Clean(s.BasePath)+"/") {
+		return xerrors.Errorf("%s is not a cgroup", res.Path)
+	}
+
+	var dirs []string
+	err := filepath.Walk(res.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// children before their parents
+	for i := len(dirs) - 1; i >= 0; i-- {
+		err := syscall.Rmdir(dirs[i])
+		if err != nil && !os.IsNotExist(err) {
+			return xerrors.Errorf("cannot remove cgroup %s: %w", dirs[i], err)
+		}
+	}
+	return nil
+}
+
+// cgroupHasProcesses returns true if the cgroup or any of its children contains a process
+func cgroupHasProcesses(path string) (bool, error) {
+	var found bool
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if found || info.IsDir() || info.Name() != "cgroup.procs" {
+			return nil
+		}
+
+		fc, err := os.ReadFile(p)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		found = len(strings.TrimSpace(string(fc))) > 0
+		return nil
+	})
+	return found, err
+}
+
+// NetNSSource finds named network namespaces which no process is in anymore, e.g. the ones of deleted pods
+// whose CNI teardown failed.
+type NetNSSource struct {
+	// Path is the directory of named network namespaces as seen from ws-daemon
+	Path string
+	// ProcPath is the node's proc filesystem, used to find the network namespaces in use
+	ProcPath string
+	// Unmount unmounts the network namespace file
+	Unmount func(path string) error
+}
+
+// Kind returns ResourceNetNS
+func (s *NetNSSource) Kind() ResourceKind {
+	return ResourceNetNS
+}
+
+// List returns all named network namespaces
+func (s *NetNSSource) List() ([]Resource, error) {
+	entries, err := os.ReadDir(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inUse, err := s.netnsInUse()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Resource
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(s.Path, e.Name())
+		var stat syscall.Stat_t
+		err := syscall.Stat(path, &stat)
+		if err != nil {
+			continue
+		}
+		_, used := inUse[stat.Ino]
+		res = append(res, Resource{Kind: ResourceNetNS, Path: path, InUse: used})
+	}
+	return res, nil
+}
+
+// netnsInUse returns the inodes of all network namespaces some process is in
+func (s *NetNSSource) netnsInUse() (map[uint64]struct{}, error) {
+	procs, err := os.ReadDir(s.ProcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[uint64]struct{})
+	for _, p := range procs {
+		if !p.IsDir() || strings.Trim(p.Name(), "0123456789") != "" {
+			continue
+		}
+		var stat syscall.Stat_t
+		err := syscall.Stat(filepath.Join(s.ProcPath, p.Name(), "ns", "net"), &stat)
+		if err != nil {
+			// processes come and go while we look at them
+			continue
+		}
+		res[stat.Ino] = struct{}{}
+	}
+	if len(res) == 0 {
+		// there's always some process in a network namespace - something's off with the proc filesystem
+		return nil, xerrors.Errorf("found no network namespaces in %s", s.ProcPath)
+	}
+	return res, nil
+}
+
+// Remove unmounts and deletes the network namespace file
+func (s *NetNSSource) Remove(res Resource) error {
+	if s.Unmount != nil {
+		err := s.Unmount(res.Path)
+		if err != nil {
+			return err
+		}
+	}
+	err := os.Remove(res.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// SwapFileSource finds the swap files of workspaces, e.g. the ones of workspaces which stopped while ws-daemon was down
+type SwapFileSource struct {
+	// Path is the directory ws-daemon creates the swap files in
+	Path string
+	// Suffix is the suffix of the swap files, whose names are instance IDs otherwise
+	Suffix string
+	// SwapOff makes the kernel stop swapping to a swap file
+	SwapOff func(path string) error
+}
+
+// Kind returns ResourceSwapFile
+func (s *SwapFileSource) Kind() ResourceKind {
+	return ResourceSwapFile
+}
+
+// List returns all swap files named after a workspace instance
+func (s *SwapFileSource) List() ([]Resource, error) {
+	return listInstanceFiles(ResourceSwapFile, s.Path, s.Suffix)
+}
+
+// Remove stops swapping to the swap file and deletes it
+func (s *SwapFileSource) Remove(res Resource) error {
+	if filepath.Dir(res.Path) != filepath.Clean(s.Path) {
+		return xerrors.Errorf("%s is not a swap file", res.Path)
+	}
+	if s.SwapOff != nil {
+		err := s.SwapOff(res.Path)
+		if err != nil {
+			return err
+		}
+	}
+	err := os.Remove(res.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ReservationSource finds the CPU and huge page reservations of workspaces, e.g. the ones of workspaces which
+// stopped while ws-daemon was down
+type ReservationSource struct {
+	// Path is the directory ws-daemon keeps the reservations in
+	Path string
+	// Suffix is the suffix of the reservation files, whose names are instance IDs otherwise
+	Suffix string
+	// Release frees the CPUs and huge pages of a workspace instance and removes its reservation file
+	Release func(instanceID string) error
+}
+
+// Kind returns ResourceReservation
+func (s *ReservationSource) Kind() ResourceKind {
+	return ResourceReservation
+}
+
+// List returns all reservation files named after a workspace instance
+func (s *ReservationSource) List() ([]Resource, error) {
+	return listInstanceFiles(ResourceReservation, s.Path, s.Suffix)
+}
+
+// Remove releases the reservation
+func (s *ReservationSource) Remove(res Resource) error {
+	if !instanceIDPattern.MatchString(res.InstanceID) {
+		return xerrors.Errorf("%s is not a reservation", res.Path)
+	}
+	return s.Release(res.InstanceID)
+}
+
+// listInstanceFiles returns the files in path which are named after a workspace instance and end in suffix
+func listInstanceFiles(kind ResourceKind, path, suffix string) ([]Resource, error) {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Resource
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), suffix) {
+			continue
+		}
+		instanceID := strings.TrimSuffix(e.Name(), suffix)
+		if !instanceIDPattern.MatchString(instanceID) {
+			continue
+		}
+		res = append(res, Resource{Kind: kind, Path: filepath.Join(path, e.Name()), InstanceID: instanceID})
+	}
+	return res, nil
+}
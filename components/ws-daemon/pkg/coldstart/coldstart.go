@@ -0,0 +1,271 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package coldstart breaks down how long the node took to start a workspace. The parts of ws-daemon which take
+// part in a workspace start record how long their phase took. Once the IDE of the workspace listens, the start
+// is complete and we observe its phases, keyed by workspace class.
+package coldstart
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+const (
+	// PhaseMount is creating and mounting the workspace sandbox, the shiftfs mark and proc
+	PhaseMount = "mount"
+	// PhaseContent is restoring or initializing the workspace content, including the wait for a content job slot
+	PhaseContent = "content"
+	// PhaseUIDShift is writing the UID and GID mappings of user-namespaced workspaces
+	PhaseUIDShift = "uidshift"
+	// PhaseNetwork is from scheduling the workspace pod until its sandbox, including the network namespace, has an IP
+	PhaseNetwork = "network"
+	// PhaseIDE is from the start of the workspace container until the IDE listens on its port
+	PhaseIDE = "ide"
+
+	defaultIDEPollInterval = 100 * time.Millisecond
+	defaultIDETimeout      = 10 * time.Minute
+	defaultRetention       = time.Hour
+)
+
+// Config configures the cold-start breakdown
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// IDEPollInterval is how often we check whether the IDE of a starting workspace listens. Defaults to 100ms.
+	IDEPollInterval util.Duration `json:"idePollInterval,omitempty"`
+	// IDETimeout is how long we wait for the IDE to listen before we give up on a start. Defaults to 10 minutes.
+	IDETimeout util.Duration `json:"ideTimeout,omitempty"`
+	// Retention is how long we keep the breakdown of a start after its last phase. Defaults to one hour.
+	Retention util.Duration `json:"retention,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.IDEPollInterval < 0 {
+		return xerrors.Errorf("idePollInterval must not be negative")
+	}
+	if c.IDETimeout < 0 {
+		return xerrors.Errorf("ideTimeout must not be negative")
+	}
+	if c.Retention < 0 {
+		return xerrors.Errorf("retention must not be negative")
+	}
+	return nil
+}
+
+// Recorder records the phases of workspace starts on this node. All methods of a nil recorder do nothing,
+// so that callers need not care whether the breakdown is enabled.
+type Recorder struct {
+	Config Config
+
+	procPath string
+
+	mu     sync.Mutex
+	starts map[string]*start
+
+	metrics *metrics
+	stop    chan struct{}
+}
+
+type start struct {
+	Class    string
+	Phases   []phase
+	Network  bool
+	Complete bool
+	Updated  time.Time
+}
+
+type phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// NewRecorder creates a new recorder. procPath is where ws-daemon sees the node's proc filesystem.
+func NewRecorder(cfg Config, procPath string) *Recorder {
+	if cfg.IDEPollInterval == 0 {
+		cfg.IDEPollInterval = util.Duration(defaultIDEPollInterval)
+	}
+	if cfg.IDETimeout == 0 {
+		cfg.IDETimeout = util.Duration(defaultIDETimeout)
+	}
+	if cfg.Retention == 0 {
+		cfg.Retention = util.Duration(defaultRetention)
+	}
+	return &Recorder{
+		Config:   cfg,
+		procPath: procPath,
+		starts:   make(map[string]*start),
+		metrics:  newMetrics(),
+		stop:     make(chan struct{}),
+	}
+}
+
+// RegisterMetrics registers the cold-start metrics with a registry
+func (r *Recorder) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(r.metrics.Phases)
+}
+
+// Start forgets the breakdown of starts whose last phase is older than the retention, until Close is called
+func (r *Recorder) Start() {
+	t := time.NewTicker(time.Duration(r.Config.Retention) / 4)
+	defer t.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case now := <-t.C:
+			r.expire(now)
+		}
+	}
+}
+
+// Close stops the recorder
+func (r *Recorder) Close() error {
+	close(r.stop)
+	return nil
+}
+
+func (r *Recorder) expire(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, s := range r.starts {
+		if now.Sub(s.Updated) > time.Duration(r.Config.Retention) {
+			delete(r.starts, id)
+		}
+	}
+}
+
+// get returns the start of a workspace instance. Callers must hold the lock.
+func (r *Recorder) get(instanceID string) *start {
+	s, ok := r.starts[instanceID]
+	if !ok {
+		s = &start{}
+		r.starts[instanceID] = s
+	}
+	return s
+}
+
+// Record adds d to a phase of a workspace start. Phases which happen in several steps are recorded
+// once per step. Once the start is complete, Record ignores further steps, e.g. proc mounts of nested containers.
+func (r *Recorder) Record(instanceID, name string, d time.Duration) {
+	if r == nil || instanceID == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(instanceID)
+	if s.Complete {
+		return
+	}
+	s.Updated = time.Now()
+	for i := range s.Phases {
+		if s.Phases[i].Name == name {
+			s.Phases[i].Duration += d
+			return
+		}
+	}
+	s.Phases = append(s.Phases, phase{Name: name, Duration: d})
+}
+
+// Measure records the time until the returned function is called, e.g. defer r.Measure(id, PhaseMount)()
+func (r *Recorder) Measure(instanceID, name string) func() {
+	if r == nil {
+		return func() {}
+	}
+	t0 := time.Now()
+	return func() {
+		r.Record(instanceID, name, time.Since(t0))
+	}
+}
+
+// setClass sets the class of a workspace start
+func (r *Recorder) setClass(instanceID, class string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(instanceID).Class = class
+}
+
+// recordNetwork records the network phase unless we recorded it already
+func (r *Recorder) recordNetwork(instanceID string, d time.Duration) {
+	r.mu.Lock()
+	s := r.get(instanceID)
+	if s.Network {
+		r.mu.Unlock()
+		return
+	}
+	s.Network = true
+	r.mu.Unlock()
+
+	r.Record(instanceID, PhaseNetwork, d)
+}
+
+// complete records the final phase of a start and observes all of its phases
+func (r *Recorder) complete(instanceID string, ide time.Duration) {
+	r.Record(instanceID, PhaseIDE, ide)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(instanceID)
+	if s.Complete {
+		return
+	}
+	s.Complete = true
+	for _, p := range s.Phases {
+		r.metrics.Phases.WithLabelValues(p.Name, s.Class).Observe(p.Duration.Seconds())
+	}
+}
+
+// GetColdStart returns how long each node-side phase of a workspace start took so far
+func (r *Recorder) GetColdStart(ctx context.Context, req *api.GetColdStartRequest) (*api.GetColdStartResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "ID is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.starts[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no cold start known for this workspace")
+	}
+
+	res := &api.GetColdStartResponse{
+		WorkspaceClass: s.Class,
+		Complete:       s.Complete,
+		Phases:         make([]*api.ColdStartPhase, 0, len(s.Phases)),
+	}
+	for _, p := range s.Phases {
+		res.Phases = append(res.Phases, &api.ColdStartPhase{
+			Name:       p.Name,
+			DurationMs: p.Duration.Milliseconds(),
+		})
+	}
+	return res, nil
+}
+
+type metrics struct {
+	Phases *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		Phases: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "workspace_coldstart_phase_seconds",
+			Help:    "Duration of the node-side phases of complete workspace starts",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		}, []string{"phase", "class"}),
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package coldstart
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(Config{Enabled: true}, "/proc")
+	listener := &DispatchListener{Recorder: r}
+
+	r.Record("foobar", PhaseMount, 100*time.Millisecond)
+	r.Record("foobar", PhaseContent, 2*time.Second)
+	r.Record("foobar", PhaseMount, 50*time.Millisecond)
+	listener.PodUpdated(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{wsk8s.WorkspaceIDLabel: "foobar"}},
+		Status: corev1.PodStatus{
+			PodIP: "10.0.0.1",
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(time.Now().Add(-3 * time.Second))},
+			},
+		},
+	})
+	r.setClass("foobar", "regular")
+
+	resp, err := r.GetColdStart(context.Background(), &api.GetColdStartRequest{Id: "foobar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Complete {
+		t.Error("start must not be complete before the IDE listens")
+	}
+	if n := countSeries(t, r); n != 0 {
+		t.Errorf("incomplete starts must not be observed, got %d series", n)
+	}
+
+	r.complete("foobar", time.Second)
+	// proc mounts of nested containers happen after the start
+	r.Record("foobar", PhaseMount, time.Minute)
+
+	resp, err = r.GetColdStart(context.Background(), &api.GetColdStartRequest{Id: "foobar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the network phase is measured against a timestamp with second resolution
+	for _, p := range resp.Phases {
+		if p.Name == PhaseNetwork && p.DurationMs >= 2000 && p.DurationMs < 5000 {
+			p.DurationMs = 3000
+		}
+	}
+	expected := &api.GetColdStartResponse{
+		WorkspaceClass: "regular",
+		Complete:       true,
+		Phases: []*api.ColdStartPhase{
+			{Name: PhaseMount, DurationMs: 150},
+			{Name: PhaseContent, DurationMs: 2000},
+			{Name: PhaseNetwork, DurationMs: 3000},
+			{Name: PhaseIDE, DurationMs: 1000},
+		},
+	}
+	if diff := cmp.Diff(expected, resp, cmp.Comparer(func(a, b *api.ColdStartPhase) bool { return a.Name == b.Name && a.DurationMs == b.DurationMs })); diff != "" {
+		t.Errorf("unexpected cold start (-want +got):\n%s", diff)
+	}
+	if n := countSeries(t, r); n != 4 {
+		t.Errorf("expected one series per phase, got %d", n)
+	}
+}
+
+func countSeries(t *testing.T, r *Recorder) (n int) {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	err := r.RegisterMetrics(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		n += len(mf.Metric)
+	}
+	return n
+}
+
+func TestRecorderNetworkOnce(t *testing.T) {
+	r := NewRecorder(Config{Enabled: true}, "/proc")
+	r.recordNetwork("foobar", time.Second)
+	r.recordNetwork("foobar", time.Minute)
+
+	resp, err := r.GetColdStart(context.Background(), &api.GetColdStartRequest{Id: "foobar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Phases) != 1 || resp.Phases[0].DurationMs != 1000 {
+		t.Errorf("expected the first network phase only, got %v", resp.Phases)
+	}
+}
+
+func TestRecorderExpire(t *testing.T) {
+	r := NewRecorder(Config{Enabled: true, Retention: 0}, "/proc")
+	r.Record("foobar", PhaseMount, time.Second)
+	r.expire(time.Now().Add(2 * time.Hour))
+
+	_, err := r.GetColdStart(context.Background(), &api.GetColdStartRequest{Id: "foobar"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected the start to be forgotten, got %v", err)
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Record("foobar", PhaseMount, time.Second)
+	r.Measure("foobar", PhaseContent)()
+}
+
+func TestIsListening(t *testing.T) {
+	const (
+		tcp = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:59D8 00000000:0000 0A 00000000:00000000 00:00000000 00000000 33333        0 1234 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:5DC0 0100007F:A2C4 01 00000000:00000000 00:00000000 00000000 33333        0 1235 1 0000000000000000 20 4 30 10 -1
+`
+		tcp6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:5DC0 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000 33333        0 1236 1 0000000000000000 100 0 0 10 0
+`
+	)
+	dir := t.TempDir()
+	for fn, content := range map[string]string{"tcp": tcp, "tcp6": tcp6} {
+		err := os.WriteFile(filepath.Join(dir, fn), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		Name     string
+		File     string
+		Port     uint16
+		Expected bool
+	}{
+		{Name: "listening", File: "tcp", Port: 23000, Expected: true},
+		{Name: "established only", File: "tcp", Port: 24000},
+		{Name: "listening on IPv6", File: "tcp6", Port: 24000, Expected: true},
+		{Name: "not listening", File: "tcp6", Port: 23000},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := isListening(filepath.Join(dir, test.File), test.Port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act != test.Expected {
+				t.Errorf("expected %v, got %v", test.Expected, act)
+			}
+		})
+	}
+
+	err := waitForListener(context.Background(), dir, 24000, 10*time.Millisecond, time.Second)
+	if err != nil {
+		t.Errorf("expected to find the IPv6 listener: %v", err)
+	}
+	err = waitForListener(context.Background(), dir, 25000, 10*time.Millisecond, 50*time.Millisecond)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected to give up on a port nobody listens on, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package coldstart
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+const (
+	workspaceContainerName = "workspace"
+	// tcpListen is the state of listening sockets in /proc/net/tcp
+	tcpListen = "0A"
+)
+
+// DispatchListener records the phases of a workspace start which we learn about from its pod and container
+type DispatchListener struct {
+	Recorder *Recorder
+}
+
+// PodUpdated records the network phase once the pod of a workspace has an IP. Kubernetes records the time a pod
+// was scheduled with second resolution only, hence so is the network phase.
+func (d *DispatchListener) PodUpdated(pod *corev1.Pod) {
+	instanceID := pod.Labels[wsk8s.WorkspaceIDLabel]
+	if instanceID == "" || pod.Status.PodIP == "" {
+		return
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type != corev1.PodScheduled || c.Status != corev1.ConditionTrue {
+			continue
+		}
+		d.Recorder.recordNetwork(instanceID, time.Since(c.LastTransitionTime.Time))
+		return
+	}
+}
+
+// WorkspaceAdded waits for the IDE of a workspace to listen, which completes the start
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	t0 := time.Now()
+	d.Recorder.setClass(ws.InstanceID, ws.Pod.Labels[wsk8s.TypeLabel])
+
+	port, ok := idePort(ws.Pod)
+	if !ok {
+		return xerrors.Errorf("workspace container has no IDE port")
+	}
+	pid, err := dispatch.WorkspacePID(ctx, ws)
+	if err != nil {
+		return err
+	}
+
+	// the container's network namespace is the one of its root process
+	procNet := filepath.Join(d.Recorder.procPath, fmt.Sprint(pid), "net")
+	go func() {
+		err := waitForListener(ctx, procNet, port, time.Duration(d.Recorder.Config.IDEPollInterval), time.Duration(d.Recorder.Config.IDETimeout))
+		if err != nil {
+			log.WithError(err).WithFields(ws.OWI()).Debug("IDE did not listen - cold start is incomplete")
+			return
+		}
+		d.Recorder.complete(ws.InstanceID, time.Since(t0))
+	}()
+	return nil
+}
+
+// idePort returns the port the IDE listens on, which ws-manager exposes as the only port of the workspace container
+func idePort(pod *corev1.Pod) (port uint16, ok bool) {
+	for _, c := range pod.Spec.Containers {
+		if c.Name != workspaceContainerName || len(c.Ports) == 0 {
+			continue
+		}
+		return uint16(c.Ports[0].ContainerPort), true
+	}
+	return 0, false
+}
+
+// waitForListener polls the sockets of a network namespace until one listens on port
+func waitForListener(ctx context.Context, procNet string, port uint16, interval, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		for _, fn := range []string{"tcp", "tcp6"} {
+			ok, err := isListening(filepath.Join(procNet, fn), port)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if ok {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// isListening returns true if a socket in a /proc/net/tcp(6) file listens on port
+func isListening(fn string, port uint16) (bool, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpListen {
+			continue
+		}
+		idx := strings.LastIndex(fields[1], ":")
+		if idx < 0 {
+			continue
+		}
+		p, err := strconv.ParseUint(fields[1][idx+1:], 16, 16)
+		if err != nil {
+			continue
+		}
+		if uint16(p) == port {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
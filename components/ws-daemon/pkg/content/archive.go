@@ -5,11 +5,15 @@
 package content
 
 import (
+	"archive/tar"
 	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
@@ -37,30 +41,7 @@ func BuildTarbal(ctx context.Context, src string, dst string, opts ...carchive.T
 		return fmt.Errorf("Unable to tar files: %v", err.Error())
 	}
 
-	uidMaps := make([]idtools.IDMap, len(cfg.UIDMaps))
-	for i, m := range cfg.UIDMaps {
-		uidMaps[i] = idtools.IDMap{
-			ContainerID: m.ContainerID,
-			HostID:      m.HostID,
-			Size:        m.Size,
-		}
-	}
-	gidMaps := make([]idtools.IDMap, len(cfg.GIDMaps))
-	for i, m := range cfg.GIDMaps {
-		gidMaps[i] = idtools.IDMap{
-			ContainerID: m.ContainerID,
-			HostID:      m.HostID,
-			Size:        m.Size,
-		}
-	}
-
-	tarout, err := archive.TarWithOptions(src, &archive.TarOptions{
-		Compression:    archive.Uncompressed,
-		WhiteoutFormat: archive.OverlayWhiteoutFormat,
-		InUserNS:       true,
-		UIDMaps:        uidMaps,
-		GIDMaps:        gidMaps,
-	})
+	tarout, err := archive.TarWithOptions(src, newTarOptions(cfg))
 	if err != nil {
 		return xerrors.Errorf("cannot create tar: %w", err)
 	}
@@ -91,6 +72,171 @@ func BuildTarbal(ctx context.Context, src string, dst string, opts ...carchive.T
 	return nil
 }
 
+// BuildIncrementalTarbal creates the same tar file as BuildTarbal would, but reads only the changed paths from src.
+// All other entries are copied from base, which must have been created by BuildTarbal with the same options.
+// changes are paths relative to src, which may have been modified, created or deleted since base was created.
+func BuildIncrementalTarbal(ctx context.Context, base string, src string, changes []string, dst string, opts ...carchive.TarOption) (err error) {
+	var cfg carchive.TarConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	//nolint:staticcheck,ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "buildIncrementalTarbal")
+	span.LogKV("base", base, "src", src, "dst", dst, "changes", len(changes))
+	defer tracing.FinishSpan(span, &err)
+
+	changed := make(map[string]struct{}, len(changes))
+	for _, c := range changes {
+		changed[filepath.Clean(c)] = struct{}{}
+	}
+	isChanged := func(name string) bool {
+		name = filepath.Clean(strings.TrimSuffix(name, "/"))
+		if _, ok := changed[name]; ok {
+			return true
+		}
+		return hasChangedParent(changed, name)
+	}
+
+	fin, err := os.Open(base)
+	if err != nil {
+		return xerrors.Errorf("cannot open base archive: %w", err)
+	}
+	defer fin.Close()
+
+	fout, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0744)
+	if err != nil {
+		return xerrors.Errorf("cannot open archive for writing: %w", err)
+	}
+	defer fout.Close()
+	fbout := bufio.NewWriter(fout)
+	defer fbout.Flush()
+
+	targetOut := newLimitWriter(fbout, cfg.MaxSizeBytes)
+	defer func(e *error) {
+		if targetOut.DidMaxOut() {
+			*e = ErrMaxSizeExceeded
+		}
+	}(&err)
+	tw := tar.NewWriter(targetOut)
+
+	// copy everything that did not change from the base archive
+	var (
+		tr      = tar.NewReader(bufio.NewReader(fin))
+		relinks []string
+	)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return xerrors.Errorf("cannot read base archive: %w", err)
+		}
+		if isChanged(hdr.Name) {
+			continue
+		}
+		if hdr.Typeflag == tar.TypeLink && isChanged(hdr.Linkname) {
+			// the file this entry links to is read from src again, hence we must read the link from there too
+			relinks = append(relinks, filepath.Clean(hdr.Name))
+			continue
+		}
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return xerrors.Errorf("cannot write tar file: %w", err)
+		}
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return xerrors.Errorf("cannot write tar file: %w", err)
+		}
+	}
+
+	// add the changed paths which still exist
+	var include []string
+	for p := range changed {
+		if hasChangedParent(changed, p) {
+			continue
+		}
+		include = append(include, p)
+	}
+	include = append(include, relinks...)
+	var existing []string
+	for _, p := range include {
+		if _, err := os.Lstat(filepath.Join(src, p)); err != nil {
+			continue
+		}
+		existing = append(existing, p)
+	}
+	sort.Strings(existing)
+	span.LogKV("existing", len(existing))
+
+	if len(existing) > 0 {
+		tarOpts := newTarOptions(cfg)
+		tarOpts.IncludeFiles = existing
+		tarout, err := archive.TarWithOptions(src, tarOpts)
+		if err != nil {
+			return xerrors.Errorf("cannot create tar: %w", err)
+		}
+		defer tarout.Close()
+
+		cr := tar.NewReader(tarout)
+		for {
+			hdr, err := cr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return xerrors.Errorf("cannot read changes: %w", err)
+			}
+			err = tw.WriteHeader(hdr)
+			if err != nil {
+				return xerrors.Errorf("cannot write tar file: %w", err)
+			}
+			_, err = io.Copy(tw, cr)
+			if err != nil {
+				return xerrors.Errorf("cannot write tar file: %w", err)
+			}
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return xerrors.Errorf("cannot write tar file: %w", err)
+	}
+	if err = fbout.Flush(); err != nil {
+		return xerrors.Errorf("cannot flush tar out stream: %w", err)
+	}
+
+	return nil
+}
+
+// newTarOptions translates our tar configuration into the options of the Docker archive package
+func newTarOptions(cfg carchive.TarConfig) *archive.TarOptions {
+	uidMaps := make([]idtools.IDMap, len(cfg.UIDMaps))
+	for i, m := range cfg.UIDMaps {
+		uidMaps[i] = idtools.IDMap{
+			ContainerID: m.ContainerID,
+			HostID:      m.HostID,
+			Size:        m.Size,
+		}
+	}
+	gidMaps := make([]idtools.IDMap, len(cfg.GIDMaps))
+	for i, m := range cfg.GIDMaps {
+		gidMaps[i] = idtools.IDMap{
+			ContainerID: m.ContainerID,
+			HostID:      m.HostID,
+			Size:        m.Size,
+		}
+	}
+
+	return &archive.TarOptions{
+		Compression:    archive.Uncompressed,
+		WhiteoutFormat: archive.OverlayWhiteoutFormat,
+		InUserNS:       true,
+		UIDMaps:        uidMaps,
+		GIDMaps:        gidMaps,
+	}
+}
+
 // ErrMaxSizeExceeded is emitted by LimitWriter when a write tries to write beyond the max number of bytes allowed
 var ErrMaxSizeExceeded = fmt.Errorf("maximum size exceeded")
 
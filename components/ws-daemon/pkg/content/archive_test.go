@@ -5,12 +5,15 @@
 package content
 
 import (
+	"archive/tar"
 	"context"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	carchive "github.com/gitpod-io/gitpod/content-service/pkg/archive"
 )
 
@@ -96,3 +99,55 @@ func TestBuildTarbalMaxSize(t *testing.T) {
 		os.RemoveAll(c)
 	}
 }
+
+func TestBuildIncrementalTarbal(t *testing.T) {
+	var (
+		src  = t.TempDir()
+		tmp  = t.TempDir()
+		base = filepath.Join(tmp, "base.tar")
+		incr = filepath.Join(tmp, "incremental.tar")
+		full = filepath.Join(tmp, "full.tar")
+	)
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(os.MkdirAll(filepath.Join(src, "src", "pkg"), 0755))
+	must(os.WriteFile(filepath.Join(src, "src", "pkg", "unchanged.go"), []byte("package pkg"), 0644))
+	must(os.WriteFile(filepath.Join(src, "src", "pkg", "modified.go"), []byte("package pkg"), 0644))
+	must(os.WriteFile(filepath.Join(src, "deleted.txt"), []byte("bye"), 0644))
+	must(BuildTarbal(context.Background(), src, base))
+
+	must(os.WriteFile(filepath.Join(src, "src", "pkg", "modified.go"), []byte("package pkg // changed"), 0644))
+	must(os.Remove(filepath.Join(src, "deleted.txt")))
+	must(os.MkdirAll(filepath.Join(src, "node_modules", "dep"), 0755))
+	must(os.WriteFile(filepath.Join(src, "node_modules", "dep", "index.js"), nil, 0644))
+
+	must(BuildIncrementalTarbal(context.Background(), base, src, []string{"src/pkg/modified.go", "deleted.txt", "node_modules"}, incr))
+	must(BuildTarbal(context.Background(), src, full))
+
+	readEntries := func(fn string) map[string]string {
+		t.Helper()
+		f, err := os.Open(fn)
+		must(err)
+		defer f.Close()
+
+		res := make(map[string]string)
+		tr := tar.NewReader(f)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return res
+			}
+			must(err)
+			content, err := io.ReadAll(tr)
+			must(err)
+			res[hdr.Name] = string(content)
+		}
+	}
+	if diff := cmp.Diff(readEntries(full), readEntries(incr)); diff != "" {
+		t.Errorf("incremental archive differs from full archive (-full +incremental):\n%s", diff)
+	}
+}
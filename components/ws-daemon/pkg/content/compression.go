@@ -0,0 +1,208 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/content-service/pkg/compression"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+const (
+	// defaultDictionaryOwner is the storage owner whose bucket keeps the dictionaries unless configured otherwise
+	defaultDictionaryOwner = "compression-dictionaries"
+	// defaultCompressionMinGain is how much a dictionary must beat gzip by unless configured otherwise
+	defaultCompressionMinGain = 0.05
+	// dictionarySampleBudget is how much of a workspace's content we read to train a dictionary
+	dictionarySampleBudget = 8 * 1024 * 1024
+	// maxCachedDictionaries limits the number of dictionaries we keep in memory
+	maxCachedDictionaries = 256
+)
+
+// backupCompressor compresses backups with the dictionary of their repository, which it trains from the
+// content of the first workspace of a repository it backs up. Backups fall back to gzip whenever there's
+// no dictionary to use.
+type backupCompressor struct {
+	Store   *storage.DictionaryStore
+	MinGain float64
+
+	mu    sync.Mutex
+	dicts map[string][]byte
+
+	metrics *compressionMetrics
+}
+
+type compressionMetrics struct {
+	backups   *prometheus.CounterVec
+	ratio     *prometheus.HistogramVec
+	trainings *prometheus.CounterVec
+}
+
+func newBackupCompressor(ps storage.PresignedAccess, owner string, minGain float64) *backupCompressor {
+	if owner == "" {
+		owner = defaultDictionaryOwner
+	}
+	if minGain == 0 {
+		minGain = defaultCompressionMinGain
+	}
+	return &backupCompressor{
+		Store:   &storage.DictionaryStore{Storage: ps, Owner: owner},
+		MinGain: minGain,
+		dicts:   make(map[string][]byte),
+	}
+}
+
+// RegisterMetrics registers the backup compressor's Prometheus metrics
+func (c *backupCompressor) RegisterMetrics(reg prometheus.Registerer) error {
+	m := &compressionMetrics{
+		backups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "backup_compression_total",
+			Help: "Number of compressed backups by compression method",
+		}, []string{"method"}),
+		ratio: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "backup_compression_ratio",
+			Help:    "Size of compressed backups relative to their uncompressed size",
+			Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+		}, []string{"method"}),
+		trainings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "backup_compression_dictionary_trainings_total",
+			Help: "Number of dictionaries trained, by whether they paid off",
+		}, []string{"outcome"}),
+	}
+	for _, col := range []prometheus.Collector{m.backups, m.ratio, m.trainings} {
+		err := reg.Register(col)
+		if err != nil {
+			return err
+		}
+	}
+	c.metrics = m
+	return nil
+}
+
+// Compress compresses the archive src to dst. The dictionary is trained from the content in location if the
+// repository has none yet. Without a repository or a dictionary that pays off, we fall back to gzip.
+func (c *backupCompressor) Compress(ctx context.Context, repository, location string, src io.Reader, dst io.Writer) (method compression.Method, err error) {
+	var dict []byte
+	if repository != "" {
+		dict = c.dictionary(ctx, repository, location)
+	}
+
+	var w io.WriteCloser
+	if len(dict) > 0 {
+		w, err = compression.NewWriter(dst, dict)
+		if err != nil {
+			return "", err
+		}
+		method = compression.MethodDictionary
+	} else {
+		w = gzip.NewWriter(dst)
+		method = compression.MethodGzip
+	}
+
+	_, err = io.Copy(w, src)
+	if err != nil {
+		return "", xerrors.Errorf("cannot compress backup: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return "", xerrors.Errorf("cannot compress backup: %w", err)
+	}
+	return method, nil
+}
+
+// observe records the outcome of compressing a backup of size bytes to compressedSize bytes
+func (c *backupCompressor) observe(method compression.Method, size, compressedSize int64) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.backups.WithLabelValues(string(method)).Inc()
+	if size > 0 {
+		c.metrics.ratio.WithLabelValues(string(method)).Observe(float64(compressedSize) / float64(size))
+	}
+}
+
+// dictionary returns the dictionary of a repository, training and storing one if the repository has none yet.
+// An empty dictionary means gzip does just as well.
+func (c *backupCompressor) dictionary(ctx context.Context, repository, location string) []byte {
+	c.mu.Lock()
+	dict, ok := c.dicts[repository]
+	c.mu.Unlock()
+	if ok {
+		return dict
+	}
+
+	log := log.WithField("repository", repository)
+	dict, found, err := c.Store.Get(ctx, repository)
+	if err != nil {
+		// we'll try again with the next backup
+		log.WithError(err).Warn("cannot download compression dictionary - falling back to gzip")
+		return nil
+	}
+	if !found {
+		dict, err = c.train(repository, location)
+		if err != nil {
+			log.WithError(err).Warn("cannot train compression dictionary - falling back to gzip")
+			c.observeTraining("failed")
+			return nil
+		}
+		err = c.Store.Put(ctx, repository, dict)
+		if err != nil {
+			// the dictionary is still good for this node
+			log.WithError(err).Warn("cannot upload compression dictionary")
+		}
+	}
+
+	c.mu.Lock()
+	if len(c.dicts) >= maxCachedDictionaries {
+		for k := range c.dicts {
+			delete(c.dicts, k)
+			break
+		}
+	}
+	c.dicts[repository] = dict
+	c.mu.Unlock()
+
+	return dict
+}
+
+// train trains a dictionary from the content in location, and returns an empty dictionary if it does not pay off
+func (c *backupCompressor) train(repository, location string) ([]byte, error) {
+	if _, err := os.Stat(location); err != nil {
+		return nil, err
+	}
+	samples, err := compression.Sample(location, dictionarySampleBudget)
+	if err != nil {
+		return nil, err
+	}
+	dict := compression.Train(samples, compression.MaxDictionarySize)
+	gain, err := compression.Evaluate(samples, dict)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("repository", repository).WithField("size", len(dict)).WithField("gain", gain).Info("trained compression dictionary")
+	if gain < c.MinGain {
+		c.observeTraining("rejected")
+		return []byte{}, nil
+	}
+	c.observeTraining("used")
+	return dict, nil
+}
+
+func (c *backupCompressor) observeTraining(outcome string) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.trainings.WithLabelValues(outcome).Inc()
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitpod-io/gitpod/content-service/pkg/compression"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+func TestBackupCompressor(t *testing.T) {
+	location := t.TempDir()
+	for i := 0; i < 50; i++ {
+		content := fmt.Sprintf("// Copyright (c) 2021 Gitpod GmbH. All rights reserved.\n// Licensed under the GNU Affero General Public License (AGPL).\n\npackage pkg%d\n\nimport (\n\t\"context\"\n\n\t\"golang.org/x/xerrors\"\n)\n", i)
+		err := os.WriteFile(filepath.Join(location, fmt.Sprintf("file%d.go", i)), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	backup := bytes.Repeat([]byte("// Licensed under the GNU Affero General Public License (AGPL).\n"), 100)
+
+	tests := []struct {
+		Name       string
+		Repository string
+		MinGain    float64
+		Method     compression.Method
+	}{
+		{Name: "no repository", Method: compression.MethodGzip},
+		{Name: "trained dictionary", Repository: "https://github.com/gitpod-io/gitpod.git", MinGain: -1, Method: compression.MethodDictionary},
+		{Name: "dictionary does not pay off", Repository: "https://github.com/gitpod-io/gitpod.git", MinGain: 1, Method: compression.MethodGzip},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			// the noop storage has no dictionaries and cannot store any, hence we always train
+			c := newBackupCompressor(&storage.PresignedNoopStorage{}, "", test.MinGain)
+
+			var compressed bytes.Buffer
+			method, err := c.Compress(context.Background(), test.Repository, location, bytes.NewReader(backup), &compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method != test.Method {
+				t.Errorf("expected %s compression, got %s", test.Method, method)
+			}
+
+			r, method, err := compression.Decompress(&compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method != test.Method {
+				t.Errorf("detected %s compression instead of %s", method, test.Method)
+			}
+			act, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(act, backup) {
+				t.Error("backup differs after compression")
+			}
+		})
+	}
+}
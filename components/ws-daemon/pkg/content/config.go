@@ -6,6 +6,7 @@ package content
 
 import (
 	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/pkg/qos"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
 )
@@ -30,6 +31,9 @@ type Config struct {
 	// Storage is some form of permanent file store to which we back up workspaces
 	Storage storage.Config `json:"storage"`
 
+	// QoS configures the concurrency and priority of content up- and downloads
+	QoS *qos.Config `json:"qos,omitempty"`
+
 	// Backup configures the behaviour of ws-daemon during backup
 	Backup struct {
 		// Timeout configures the maximum time the remote storage upload can take
@@ -44,6 +48,33 @@ type Config struct {
 		Period util.Duration `json:"period"`
 	} `json:"backup,omitempty"`
 
+	// ChangeJournal records which files change while a workspace runs, so that the backup on stop only
+	// needs to read the changed paths. We fall back to reading the whole workspace whenever the journal
+	// is unreliable.
+	ChangeJournal struct {
+		Enabled bool `json:"enabled"`
+
+		// MaxWatches is the number of directories we watch per workspace. Workspaces with more
+		// directories are backed up in full. Defaults to 100000.
+		MaxWatches int `json:"maxWatches,omitempty"`
+	} `json:"changeJournal,omitempty"`
+
+	// Compression compresses regular backups with a dictionary trained per repository, which does a lot better
+	// than generic compression on many small, similar files. Workspaces without a repository, and repositories
+	// whose dictionary does not pay off, are gzip compressed instead. All nodes must be able to restore
+	// compressed backups before this is enabled.
+	Compression struct {
+		Enabled bool `json:"enabled"`
+
+		// DictionaryOwner is the storage owner in whose bucket we keep the dictionaries.
+		// Defaults to "compression-dictionaries".
+		DictionaryOwner string `json:"dictionaryOwner,omitempty"`
+
+		// MinGain is how much smaller than with gzip a dictionary must make the content of a repository
+		// for us to use it, e.g. 0.1 for 10%. Defaults to 0.05.
+		MinGain float64 `json:"minGain,omitempty"`
+	} `json:"compression,omitempty"`
+
 	// FullWorkspaceBackup configures the FWB behaviour
 	FullWorkspaceBackup struct {
 		Enabled bool `json:"enabled"`
@@ -60,4 +91,46 @@ type Config struct {
 		// Args are additional arguments to pass to the CI runtime
 		Args []string `json:"args"`
 	} `json:"initializer"`
+
+	// Staging configures the node-local cache of snapshots which were pre-staged ahead of workspace starts
+	Staging struct {
+		Enabled bool `json:"enabled"`
+
+		// MaxSize is the disk space the staged snapshots may use. Zero means no limit.
+		MaxSize quota.Size `json:"maxSize"`
+
+		// TTL is the time after which a staged snapshot nobody used is evicted. Defaults to 2 hours.
+		TTL util.Duration `json:"ttl,omitempty"`
+	} `json:"staging,omitempty"`
+
+	// Layers unpacks content snapshots once per node into containerd snapshots which workspaces mount as the
+	// lower layer of their content, rather than each workspace extracting the snapshot on its own. Only
+	// workspaces which start from a single snapshot and have no backup are layered. Requires containerd and
+	// cannot be combined with WorkspaceSizeLimit.
+	Layers struct {
+		Enabled bool `json:"enabled"`
+
+		// Snapshotter is the containerd snapshotter we unpack content with. Defaults to "overlayfs".
+		Snapshotter string `json:"snapshotter,omitempty"`
+
+		// NodeLabel enables layers only on nodes which carry this label with the value "true",
+		// so that they can be rolled out per node pool. Empty means all nodes.
+		NodeLabel string `json:"nodeLabel,omitempty"`
+
+		// TTL is the time after which a layer no workspace uses is removed. Defaults to 2 hours.
+		TTL util.Duration `json:"ttl,omitempty"`
+	} `json:"layers,omitempty"`
+
+	// Teardown configures the steps we take when disposing a workspace, keyed by step name: backup, git-status,
+	// sandbox, content-layer, remove and node-resources. Steps which do not depend on each other run in parallel.
+	Teardown map[string]TeardownStepConfig `json:"teardown,omitempty"`
+}
+
+// TeardownStepConfig configures a step of the workspace teardown
+type TeardownStepConfig struct {
+	// Timeout is the time a single attempt of the step may take. Zero means the step's default.
+	Timeout util.Duration `json:"timeout,omitempty"`
+
+	// Attempts is how often we try the step before the teardown fails. Zero means the step's default.
+	Attempts int `json:"attempts,omitempty"`
 }
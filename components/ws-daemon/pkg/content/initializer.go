@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
@@ -26,6 +27,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/compression"
 	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 )
@@ -43,6 +45,14 @@ type RunInitializerOpts struct {
 	GID uint32
 
 	OWI map[string]interface{}
+
+	// StagedContent maps remote content names to archives staged on this node,
+	// which the initializer reads instead of downloading them.
+	StagedContent map[string]string
+
+	// LayeredContent is the remote content which is mounted as the lower layer of the destination already.
+	// The initializer neither extracts it nor cleans the destination.
+	LayeredContent string
 }
 
 // errors to be tested with errors.Is
@@ -63,17 +73,27 @@ func collectRemoteContent(ctx context.Context, rs storage.DirectAccess, ps stora
 		rc[storage.DefaultBackup] = *backup
 	}
 
+	err = collectSnapshots(ctx, ps, initializer, rc)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
+
+// collectSnapshots signs the downloads of the snapshots an initializer restores, including those of composite layers
+func collectSnapshots(ctx context.Context, ps storage.PresignedAccess, initializer *csapi.WorkspaceInitializer, rc map[string]storage.DownloadInfo) error {
 	if si := initializer.GetSnapshot(); si != nil {
 		bkt, obj, err := storage.ParseSnapshotName(si.Snapshot)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		info, err := ps.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
 		if err == storage.ErrNotFound {
-			return nil, errCannotFindSnapshot
+			return errCannotFindSnapshot
 		}
 		if err != nil {
-			return nil, xerrors.Errorf("cannot find snapshot: %w", err)
+			return xerrors.Errorf("cannot find snapshot: %w", err)
 		}
 
 		rc[si.Snapshot] = *info
@@ -81,19 +101,35 @@ func collectRemoteContent(ctx context.Context, rs storage.DirectAccess, ps stora
 	if si := initializer.GetPrebuild(); si != nil && si.Prebuild != nil && si.Prebuild.Snapshot != "" {
 		bkt, obj, err := storage.ParseSnapshotName(si.Prebuild.Snapshot)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		info, err := ps.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
 		if err == storage.ErrNotFound {
 			// no prebuild found - that's fine
 		} else if err != nil {
-			return nil, xerrors.Errorf("cannot find prebuild: %w", err)
+			return xerrors.Errorf("cannot find prebuild: %w", err)
 		} else {
 			rc[si.Prebuild.Snapshot] = *info
 		}
 	}
 
-	return rc, nil
+	if ci := initializer.GetComposite(); ci != nil {
+		for _, l := range ci.Layers {
+			if l.Initializer == nil {
+				continue
+			}
+			err := collectSnapshots(ctx, ps, l.Initializer, rc)
+			if errors.Is(err, errCannotFindSnapshot) && l.Optional {
+				// the layer will fail and be skipped
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 // RunInitializer runs a content initializer in a user, PID and mount namespace to isolate it from ws-daemon
@@ -134,6 +170,34 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 		return err
 	}
 
+	if len(opts.StagedContent) > 0 || opts.LayeredContent != "" {
+		rc := make(map[string]storage.DownloadInfo, len(remoteContent))
+		for k, v := range remoteContent {
+			rc[k] = v
+		}
+		remoteContent = rc
+	}
+	var stagedMounts []specs.Mount
+	for name, path := range opts.StagedContent {
+		info, ok := remoteContent[name]
+		if !ok {
+			continue
+		}
+		dst := fmt.Sprintf("/staged/%d%s", len(stagedMounts), stagedFileSuffix)
+		stagedMounts = append(stagedMounts, specs.Mount{
+			Destination: dst,
+			Source:      path,
+			Type:        "bind",
+			Options:     []string{"bind", "ro", "rprivate"},
+		})
+		info.URL = "file://" + dst
+		remoteContent[name] = info
+	}
+	if info, ok := remoteContent[opts.LayeredContent]; ok {
+		info.URL = layerURL
+		remoteContent[opts.LayeredContent] = info
+	}
+
 	msg := msgInitContent{
 		Destination:   "/dst",
 		Initializer:   init,
@@ -143,6 +207,7 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 		GID:           int(opts.GID),
 		UID:           int(opts.UID),
 		OWI:           opts.OWI,
+		Layered:       opts.LayeredContent != "",
 	}
 	fc, err := json.MarshalIndent(msg, "", "  ")
 	if err != nil {
@@ -186,6 +251,7 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 		Type:        "bind",
 		Options:     []string{"bind", "rprivate"},
 	})
+	spec.Mounts = append(spec.Mounts, stagedMounts...)
 
 	spec.Hostname = "content-init"
 	spec.Process.Terminal = false
@@ -283,12 +349,16 @@ func RunInitializerChild() (err error) {
 		return err
 	}
 
-	initSource, err := wsinit.InitializeWorkspace(ctx, "/dst", rs,
+	opts := []wsinit.InitializeOpt{
 		wsinit.WithInitializer(initializer),
-		wsinit.WithCleanSlate,
 		wsinit.WithMappings(initmsg.IDMappings),
 		wsinit.WithChown(initmsg.UID, initmsg.GID),
-	)
+	}
+	if !initmsg.Layered {
+		// a clean slate would remove the content of the lower layer again
+		opts = append(opts, wsinit.WithCleanSlate)
+	}
+	initSource, err := wsinit.InitializeWorkspace(ctx, "/dst", rs, opts...)
 	if err != nil {
 		return err
 	}
@@ -322,14 +392,33 @@ func (rs *remoteContentStorage) Download(ctx context.Context, destination string
 	if !exists {
 		return false, nil
 	}
+	if info.URL == layerURL {
+		// the content is the lower layer of the destination already
+		return true, nil
+	}
 
-	resp, err := http.Get(info.URL)
-	if err != nil {
-		return true, err
+	var body io.ReadCloser
+	if fn := strings.TrimPrefix(info.URL, "file://"); fn != info.URL {
+		// the content was staged on the node
+		body, err = os.Open(fn)
+		if err != nil {
+			return true, err
+		}
+	} else {
+		resp, err := http.Get(info.URL)
+		if err != nil {
+			return true, err
+		}
+		body = resp.Body
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 
-	err = archive.ExtractTarbal(ctx, resp.Body, destination, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
+	// backups may be compressed, see backupCompressor
+	content, _, err := compression.Decompress(body)
+	if err != nil {
+		return true, xerrors.Errorf("cannot decompress %s: %w", name, err)
+	}
+	err = archive.ExtractTarbal(ctx, content, destination, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
 	if err != nil {
 		return true, xerrors.Errorf("tar %s: %s", destination, err.Error())
 	}
@@ -376,4 +465,7 @@ type msgInitContent struct {
 
 	TraceInfo string
 	OWI       map[string]interface{}
+
+	// Layered is true if the destination is an overlay whose lower layer holds some of the remote content
+	Layered bool
 }
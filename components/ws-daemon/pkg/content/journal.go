@@ -0,0 +1,316 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// defaultChangeJournalMaxWatches is the number of directories we watch per workspace unless configured otherwise
+	defaultChangeJournalMaxWatches = 100000
+
+	changeJournalPollTimeout = 500 // milliseconds
+
+	changeJournalDirMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_ATTRIB |
+		unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF |
+		unix.IN_ONLYDIR | unix.IN_DONT_FOLLOW | unix.IN_EXCL_UNLINK
+)
+
+// changeJournal records which paths of a workspace change using inotify. Once the journal becomes unreliable,
+// e.g. because the kernel dropped events or we cannot watch all directories, it stays unreliable and
+// we must scan the whole workspace instead.
+type changeJournal struct {
+	root       string
+	maxWatches int
+
+	fd      int
+	stop    chan struct{}
+	stopped chan struct{}
+
+	mu         sync.Mutex
+	watches    map[int]string
+	changed    map[string]struct{}
+	unreliable string
+}
+
+// startChangeJournal watches all directories below root and records the changes made to them until Stop is called
+func startChangeJournal(root string, maxWatches int) (*changeJournal, error) {
+	if maxWatches <= 0 {
+		maxWatches = defaultChangeJournalMaxWatches
+	}
+
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create inotify instance: %w", err)
+	}
+	j := &changeJournal{
+		root:       root,
+		maxWatches: maxWatches,
+		fd:         fd,
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+		watches:    make(map[int]string),
+		changed:    make(map[string]struct{}),
+	}
+
+	// we watch before we read the events so that the kernel queues them up while we walk the tree
+	j.mu.Lock()
+	j.watchTree("", false)
+	j.mu.Unlock()
+
+	go j.run()
+	return j, nil
+}
+
+// watchTree adds watches for dir and all directories below it. If markChanged is true, all paths
+// we come across are recorded as changed, e.g. because dir was just created or moved here. Callers must hold mu.
+func (j *changeJournal) watchTree(dir string, markChanged bool) {
+	if j.unreliable != "" {
+		return
+	}
+
+	err := filepath.WalkDir(filepath.Join(j.root, dir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the path vanished while we walked the tree - we'll see the event for that
+			return nil
+		}
+		rel, err := filepath.Rel(j.root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = ""
+		}
+		if markChanged && rel != "" {
+			j.changed[rel] = struct{}{}
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if len(j.watches) >= j.maxWatches {
+			return xerrors.Errorf("workspace has more than %d directories", j.maxWatches)
+		}
+		wd, err := unix.InotifyAddWatch(j.fd, path, changeJournalDirMask)
+		if err == unix.ENOENT {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("cannot watch %s: %w", rel, err)
+		}
+		j.watches[wd] = rel
+		return nil
+	})
+	if err != nil {
+		j.markUnreliable(err.Error())
+	}
+}
+
+// markUnreliable gives up on the journal. Callers must hold mu.
+func (j *changeJournal) markUnreliable(reason string) {
+	if j.unreliable != "" {
+		return
+	}
+	j.unreliable = reason
+	j.changed = nil
+	log.WithField("root", j.root).WithField("reason", reason).Debug("change journal became unreliable")
+}
+
+func (j *changeJournal) run() {
+	defer close(j.stopped)
+	defer unix.Close(j.fd)
+
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		var stopping bool
+		select {
+		case <-j.stop:
+			stopping = true
+		default:
+		}
+
+		fds := []unix.PollFd{{Fd: int32(j.fd), Events: unix.POLLIN}}
+		timeout := changeJournalPollTimeout
+		if stopping {
+			timeout = 0
+		}
+		_, err := unix.Poll(fds, timeout)
+		if err != nil && err != unix.EINTR {
+			j.mu.Lock()
+			j.markUnreliable(err.Error())
+			j.mu.Unlock()
+			return
+		}
+
+		// read until the queue is empty, so that we've seen all events which happened before Stop was called
+		for {
+			n, err := unix.Read(j.fd, buf)
+			if err == unix.EAGAIN || err == unix.EINTR {
+				break
+			}
+			if err != nil {
+				j.mu.Lock()
+				j.markUnreliable(err.Error())
+				j.mu.Unlock()
+				return
+			}
+			j.handleEvents(buf[:n])
+		}
+
+		if stopping {
+			return
+		}
+	}
+}
+
+func (j *changeJournal) handleEvents(buf []byte) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		evt := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		nameBytes := buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(evt.Len)]
+		offset += unix.SizeofInotifyEvent + int(evt.Len)
+
+		if evt.Mask&unix.IN_Q_OVERFLOW != 0 {
+			j.markUnreliable("inotify queue overflow")
+			return
+		}
+		if j.unreliable != "" {
+			return
+		}
+
+		dir, ok := j.watches[int(evt.Wd)]
+		if evt.Mask&unix.IN_IGNORED != 0 {
+			delete(j.watches, int(evt.Wd))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if evt.Mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF) != 0 {
+			// the parent directory reports this path as deleted or moved
+			continue
+		}
+
+		name := string(bytes.TrimRight(nameBytes, "\x00"))
+		if name == "" {
+			// the watched directory itself changed
+			if dir != "" {
+				j.changed[dir] = struct{}{}
+			}
+			continue
+		}
+		path := filepath.Join(dir, name)
+		j.changed[path] = struct{}{}
+
+		if evt.Mask&unix.IN_ISDIR != 0 && evt.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+			// files may have been created in the new directory before we started watching it
+			j.watchTree(path, true)
+		}
+	}
+}
+
+// Stop stops recording changes. It returns the paths which changed relative to the root,
+// or false if the journal is unreliable and the whole tree must be considered changed.
+func (j *changeJournal) Stop() (changes []string, ok bool) {
+	select {
+	case <-j.stop:
+	default:
+		close(j.stop)
+	}
+	<-j.stopped
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.unreliable != "" {
+		return nil, false
+	}
+	return collapseChanges(j.changed), true
+}
+
+// Unreliable returns the reason the journal became unreliable, or an empty string if it's still reliable
+func (j *changeJournal) Unreliable() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.unreliable
+}
+
+// collapseChanges removes all paths from changes whose parent directory changed as well
+func collapseChanges(changes map[string]struct{}) []string {
+	res := make([]string, 0, len(changes))
+	for p := range changes {
+		if hasChangedParent(changes, p) {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+// hasChangedParent returns true if one of the parent directories of path is in changes
+func hasChangedParent(changes map[string]struct{}, path string) bool {
+	for {
+		idx := strings.LastIndex(path, string(os.PathSeparator))
+		if idx < 0 {
+			return false
+		}
+		path = path[:idx]
+		if _, ok := changes[path]; ok {
+			return true
+		}
+	}
+}
+
+// workspaceChangeJournal is what we keep per workspace to build its backup on stop incrementally:
+// a journal of the changes made since we archived the workspace content in base.
+type workspaceChangeJournal struct {
+	journal *changeJournal
+	base    string
+
+	ready chan struct{}
+	err   error
+}
+
+// Finish stops recording changes and returns what changed since base was created. If the journal is
+// unusable, Finish returns an error and the backup must be built from the whole workspace.
+func (w *workspaceChangeJournal) Finish() (base string, changes []string, err error) {
+	select {
+	case <-w.ready:
+	default:
+		// building the base archive amounts to a full backup - there's no point in waiting for it
+		w.journal.Stop()
+		return "", nil, xerrors.Errorf("base archive is not ready yet")
+	}
+
+	changes, ok := w.journal.Stop()
+	if w.err != nil {
+		return "", nil, xerrors.Errorf("cannot create base archive: %w", w.err)
+	}
+	if !ok {
+		return "", nil, xerrors.Errorf("change journal is unreliable: %s", w.journal.Unreliable())
+	}
+	return w.base, changes, nil
+}
+
+// Close stops recording changes and removes the base archive once it's no longer being built
+func (w *workspaceChangeJournal) Close() {
+	w.journal.Stop()
+	go func() {
+		<-w.ready
+		_ = os.Remove(w.base)
+	}()
+}
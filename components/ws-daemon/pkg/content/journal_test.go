@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChangeJournal(t *testing.T) {
+	root := t.TempDir()
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(os.MkdirAll(filepath.Join(root, "src", "pkg"), 0755))
+	must(os.WriteFile(filepath.Join(root, "src", "pkg", "unchanged.go"), []byte("package pkg"), 0644))
+	must(os.WriteFile(filepath.Join(root, "src", "pkg", "modified.go"), []byte("package pkg"), 0644))
+	must(os.WriteFile(filepath.Join(root, "deleted.txt"), []byte("bye"), 0644))
+
+	j, err := startChangeJournal(root, 0)
+	must(err)
+
+	must(os.WriteFile(filepath.Join(root, "src", "pkg", "modified.go"), []byte("package pkg // changed"), 0644))
+	must(os.Remove(filepath.Join(root, "deleted.txt")))
+	must(os.MkdirAll(filepath.Join(root, "node_modules", "dep"), 0755))
+	must(os.WriteFile(filepath.Join(root, "node_modules", "dep", "index.js"), nil, 0644))
+
+	changes, ok := j.Stop()
+	if !ok {
+		t.Fatalf("expected journal to be reliable: %s", j.Unreliable())
+	}
+	sort.Strings(changes)
+	expectation := []string{"deleted.txt", "node_modules", "src/pkg/modified.go"}
+	if diff := cmp.Diff(expectation, changes); diff != "" {
+		t.Errorf("unexpected changes (-want +got):\n%s", diff)
+	}
+}
+
+func TestChangeJournalMaxWatches(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"a", "b", "c"} {
+		err := os.Mkdir(filepath.Join(root, d), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	j, err := startChangeJournal(root, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok := j.Stop()
+	if ok {
+		t.Error("expected journal which cannot watch all directories to be unreliable")
+	}
+}
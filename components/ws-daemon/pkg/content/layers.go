@@ -0,0 +1,531 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/snapshots"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/compression"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
+)
+
+const (
+	// layerURL is the URL of remote content which is mounted as the lower layer of the workspace already.
+	// The content initializer must neither extract it nor clean the workspace location.
+	layerURL = "layer://"
+
+	layerKeyPrefix  = "gitpod/content/"
+	layerViewPrefix = "gitpod/content-view/"
+
+	// labelLayer marks the snapshots we unpacked content into and holds the name of the content
+	labelLayer = "gitpod.io/content-layer"
+	// labelLayerView marks the views workspaces mount and holds the workspace instance ID
+	labelLayerView = "gitpod.io/content-view"
+	// labelGCRoot keeps containerd's garbage collector from removing our snapshots - we remove them ourselves
+	labelGCRoot = "containerd.io/gc.root"
+
+	defaultLayerTTL = 2 * time.Hour
+)
+
+// layerStore unpacks content snapshots once per node into containerd snapshots. Workspaces starting from
+// such a snapshot mount it as the read-only lower layer of an overlay on their location rather than
+// extracting the archive themselves, and only what they change takes up space of their own.
+type layerStore struct {
+	Snapshotter snapshots.Snapshotter
+	// Mapping translates the node paths of snapshot mounts to paths in our mount namespace
+	Mapping container.PathMapping
+	// WorkingArea is where the workspaces whose locations we mount the overlays on live
+	WorkingArea string
+	// Location holds the upper and work directories of the workspaces' overlays
+	Location string
+	// TTL is the time after which we remove a layer no workspace uses
+	TTL time.Duration
+
+	mu       sync.Mutex
+	unpacks  map[string]chan struct{}
+	lastUsed map[string]time.Time
+
+	now     func() time.Time
+	metrics *layerMetrics
+}
+
+type layerMetrics struct {
+	unpacks *prometheus.CounterVec
+	mounts  *prometheus.CounterVec
+}
+
+func newLayerStore(snapshotter snapshots.Snapshotter, mapping container.PathMapping, workingArea string, ttl time.Duration) (*layerStore, error) {
+	if ttl == 0 {
+		ttl = defaultLayerTTL
+	}
+	location := filepath.Join(workingArea, ".layers")
+	err := os.MkdirAll(location, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create layer location: %w", err)
+	}
+	return &layerStore{
+		Snapshotter: snapshotter,
+		Mapping:     mapping,
+		WorkingArea: workingArea,
+		Location:    location,
+		TTL:         ttl,
+		unpacks:     make(map[string]chan struct{}),
+		lastUsed:    make(map[string]time.Time),
+		now:         time.Now,
+	}, nil
+}
+
+// RegisterMetrics registers the layer store's Prometheus metrics
+func (s *layerStore) RegisterMetrics(reg prometheus.Registerer) error {
+	m := &layerMetrics{
+		unpacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "content_layer_unpacks_total",
+			Help: "Number of content snapshots unpacked into node-local layers",
+		}, []string{"outcome"}),
+		mounts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "content_layer_mounts_total",
+			Help: "Number of workspaces which mounted their content as a layer, or fell back to extracting it",
+		}, []string{"outcome"}),
+	}
+	for _, col := range []prometheus.Collector{m.unpacks, m.mounts} {
+		err := reg.Register(col)
+		if err != nil {
+			return err
+		}
+	}
+	s.metrics = m
+	return nil
+}
+
+func (s *layerStore) observeMount(outcome string) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.mounts.WithLabelValues(outcome).Inc()
+}
+
+// layerKey is the key of the snapshot holding content unpacked with mappings. Workspaces with different
+// ID mappings see different file owners and cannot share a layer.
+func layerKey(name string, mappings []archive.IDMapping) string {
+	m, _ := json.Marshal(mappings)
+	return layerKeyPrefix + fmt.Sprintf("%x", sha256.Sum256(append([]byte(name+"\x00"), m...)))[:32]
+}
+
+// layeredSnapshot returns the snapshot an initializer restores into the workspace root, which we can
+// mount as layer. Composite initializers restore several snapshots and are not layered.
+func layeredSnapshot(initializer *csapi.WorkspaceInitializer) (name string, ok bool) {
+	if si := initializer.GetSnapshot(); si != nil {
+		return si.Snapshot, si.Snapshot != ""
+	}
+	if pi := initializer.GetPrebuild(); pi != nil && pi.Prebuild != nil {
+		return pi.Prebuild.Snapshot, pi.Prebuild.Snapshot != ""
+	}
+	return "", false
+}
+
+// Mount makes the content of snapshot name the lower layer of an overlay on target. staged is the path of
+// the snapshot archive if it is staged on this node, otherwise we download it from info. The upper layer
+// belongs to uid and gid.
+func (s *layerStore) Mount(ctx context.Context, instanceID, target, name string, info storage.DownloadInfo, staged string, mappings []archive.IDMapping, uid, gid int) (err error) {
+	key := layerKey(name, mappings)
+	err = s.unpack(ctx, key, name, info, staged, mappings)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.lastUsed[key] = s.now()
+	s.mu.Unlock()
+
+	viewKey := layerViewPrefix + instanceID
+	mounts, err := s.Snapshotter.View(ctx, viewKey, key, snapshots.WithLabels(map[string]string{
+		labelLayerView: instanceID,
+		labelGCRoot:    s.now().UTC().Format(time.RFC3339),
+	}))
+	if errdefs.IsAlreadyExists(err) {
+		mounts, err = s.Snapshotter.Mounts(ctx, viewKey)
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot create view of layer: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = s.Release(context.Background(), instanceID, target)
+		}
+	}()
+
+	lower, err := lowerDirs(mounts, s.Mapping)
+	if err != nil {
+		return err
+	}
+	var (
+		dir   = filepath.Join(s.Location, instanceID)
+		upper = filepath.Join(dir, "upper")
+		work  = filepath.Join(dir, "work")
+	)
+	for _, d := range []string{upper, work} {
+		err = os.MkdirAll(d, 0755)
+		if err != nil {
+			return xerrors.Errorf("cannot create overlay directories: %w", err)
+		}
+	}
+	// the root of the overlay takes its owner from the upper directory
+	err = os.Chown(upper, uid, gid)
+	if err != nil {
+		return xerrors.Errorf("cannot create overlay directories: %w", err)
+	}
+
+	opts := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", strings.Join(lower, ":"), upper, work)
+	err = unix.Mount("overlay", target, "overlay", 0, opts)
+	if err != nil {
+		return xerrors.Errorf("cannot mount overlay: %w", err)
+	}
+	return nil
+}
+
+// Release unmounts the overlay of a workspace and removes its upper layer. Releasing a workspace
+// which has no layer does nothing.
+func (s *layerStore) Release(ctx context.Context, instanceID, target string) error {
+	if target != "" {
+		err := unix.Unmount(target, 0)
+		if err != nil && err != unix.EINVAL && err != unix.ENOENT {
+			return xerrors.Errorf("cannot unmount overlay: %w", err)
+		}
+	}
+	err := os.RemoveAll(filepath.Join(s.Location, instanceID))
+	if err != nil {
+		return xerrors.Errorf("cannot remove upper layer: %w", err)
+	}
+
+	viewKey := layerViewPrefix + instanceID
+	info, err := s.Snapshotter.Stat(ctx, viewKey)
+	if errdefs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot find view of layer: %w", err)
+	}
+	s.mu.Lock()
+	s.lastUsed[info.Parent] = s.now()
+	s.mu.Unlock()
+
+	err = s.Snapshotter.Remove(ctx, viewKey)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return xerrors.Errorf("cannot remove view of layer: %w", err)
+	}
+	return nil
+}
+
+// unpack extracts a snapshot archive into the layer key unless it exists already
+func (s *layerStore) unpack(ctx context.Context, key, name string, info storage.DownloadInfo, staged string, mappings []archive.IDMapping) (err error) {
+	for {
+		_, err = s.Snapshotter.Stat(ctx, key)
+		if err == nil {
+			return nil
+		}
+		if !errdefs.IsNotFound(err) {
+			return xerrors.Errorf("cannot find layer: %w", err)
+		}
+
+		s.mu.Lock()
+		wait, inflight := s.unpacks[key]
+		if !inflight {
+			s.unpacks[key] = make(chan struct{})
+		}
+		s.mu.Unlock()
+		if !inflight {
+			break
+		}
+
+		// another workspace is unpacking the same content - wait for it and try again
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer func() {
+		s.mu.Lock()
+		close(s.unpacks[key])
+		delete(s.unpacks, key)
+		s.mu.Unlock()
+
+		outcome := "unpacked"
+		if err != nil {
+			outcome = "failed"
+		}
+		if s.metrics != nil {
+			s.metrics.unpacks.WithLabelValues(outcome).Inc()
+		}
+	}()
+
+	var body io.ReadCloser
+	if staged != "" {
+		body, err = os.Open(staged)
+	} else {
+		body, err = download(ctx, info.URL)
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot read %s: %w", name, err)
+	}
+	defer body.Close()
+
+	active := fmt.Sprintf("%s-unpack-%d", key, s.now().UnixNano())
+	mounts, err := s.Snapshotter.Prepare(ctx, active, "")
+	if err != nil {
+		return xerrors.Errorf("cannot prepare layer: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = s.Snapshotter.Remove(context.Background(), active)
+		}
+	}()
+	mounts, err = translateMounts(mounts, s.Mapping)
+	if err != nil {
+		return err
+	}
+
+	err = mount.WithTempMount(ctx, mounts, func(root string) error {
+		content, _, err := compression.Decompress(body)
+		if err != nil {
+			return xerrors.Errorf("cannot decompress %s: %w", name, err)
+		}
+		return archive.ExtractTarbal(ctx, content, root, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot unpack %s: %w", name, err)
+	}
+
+	err = s.Snapshotter.Commit(ctx, key, active, snapshots.WithLabels(map[string]string{
+		labelLayer:  name,
+		labelGCRoot: s.now().UTC().Format(time.RFC3339),
+	}))
+	if err != nil {
+		return xerrors.Errorf("cannot commit layer: %w", err)
+	}
+	log.WithField("snapshot", name).WithField("layer", key).Info("unpacked content into layer")
+	return nil
+}
+
+func download(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, xerrors.Errorf("download failed: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// Run removes the layers no workspace used within the TTL, and the views of workspaces which are gone, until ctx is canceled
+func (s *layerStore) Run(ctx context.Context, exists func(instanceID string) bool) {
+	t := time.NewTicker(10 * time.Minute)
+	defer t.Stop()
+	for {
+		err := s.collectGarbage(ctx, exists)
+		if err != nil {
+			log.WithError(err).Warn("cannot remove unused content layers")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *layerStore) collectGarbage(ctx context.Context, exists func(instanceID string) bool) error {
+	var (
+		layers = make(map[string]snapshots.Info)
+		inUse  = make(map[string]struct{})
+		leaked []string
+	)
+	err := s.Snapshotter.Walk(ctx, func(ctx context.Context, info snapshots.Info) error {
+		if _, ok := info.Labels[labelLayer]; ok {
+			layers[info.Name] = info
+		}
+		if id, ok := info.Labels[labelLayerView]; ok {
+			if exists(id) {
+				inUse[info.Parent] = struct{}{}
+			} else {
+				leaked = append(leaked, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range leaked {
+		err := s.Release(ctx, id, filepath.Join(s.WorkingArea, id))
+		if err != nil {
+			log.WithError(err).WithField("instanceId", id).Warn("cannot remove leaked content layer view")
+			continue
+		}
+		log.WithField("instanceId", id).Info("removed leaked content layer view")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, info := range layers {
+		if _, ok := inUse[key]; ok {
+			continue
+		}
+		if _, ok := s.unpacks[key]; ok {
+			continue
+		}
+		lastUsed, ok := s.lastUsed[key]
+		if !ok {
+			// we restarted since the layer was last used
+			lastUsed = info.Updated
+		}
+		if s.now().Sub(lastUsed) < s.TTL {
+			continue
+		}
+		err := s.Snapshotter.Remove(ctx, key)
+		if err != nil && !errdefs.IsNotFound(err) && !errdefs.IsFailedPrecondition(err) {
+			log.WithError(err).WithField("layer", key).Warn("cannot remove unused content layer")
+			continue
+		}
+		delete(s.lastUsed, key)
+		log.WithField("layer", key).WithField("snapshot", info.Labels[labelLayer]).Info("removed unused content layer")
+	}
+	return nil
+}
+
+// lowerDirs returns the directories of a read-only view in our mount namespace
+func lowerDirs(mounts []mount.Mount, mapping container.PathMapping) ([]string, error) {
+	if len(mounts) != 1 {
+		return nil, xerrors.Errorf("unexpected number of view mounts: %d", len(mounts))
+	}
+	m := mounts[0]
+
+	var dirs []string
+	switch m.Type {
+	case "bind":
+		dirs = []string{m.Source}
+	case "overlay":
+		for _, o := range m.Options {
+			if strings.HasPrefix(o, "lowerdir=") {
+				dirs = strings.Split(strings.TrimPrefix(o, "lowerdir="), ":")
+			}
+		}
+	default:
+		return nil, xerrors.Errorf("unsupported view mount type %s", m.Type)
+	}
+	if len(dirs) == 0 {
+		return nil, xerrors.Errorf("view mount has no lower directories")
+	}
+	for i, d := range dirs {
+		var err error
+		dirs[i], err = mapping.Translate(d)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot translate layer path: %w", err)
+		}
+	}
+	return dirs, nil
+}
+
+// translateMounts translates the node paths of mounts to paths in our mount namespace
+func translateMounts(mounts []mount.Mount, mapping container.PathMapping) ([]mount.Mount, error) {
+	res := make([]mount.Mount, 0, len(mounts))
+	for _, m := range mounts {
+		var err error
+		switch m.Type {
+		case "bind":
+			m.Source, err = mapping.Translate(m.Source)
+		case "overlay":
+			opts := make([]string, 0, len(m.Options))
+			for _, o := range m.Options {
+				segs := strings.SplitN(o, "=", 2)
+				if len(segs) == 2 && (segs[0] == "lowerdir" || segs[0] == "upperdir" || segs[0] == "workdir") {
+					dirs := strings.Split(segs[1], ":")
+					for i := range dirs {
+						dirs[i], err = mapping.Translate(dirs[i])
+						if err != nil {
+							break
+						}
+					}
+					o = segs[0] + "=" + strings.Join(dirs, ":")
+				}
+				opts = append(opts, o)
+			}
+			m.Options = opts
+		default:
+			err = xerrors.Errorf("unsupported mount type %s", m.Type)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("cannot translate layer mount: %w", err)
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// mountContentLayer mounts the snapshot a workspace starts from as the lower layer of its location if it can,
+// and tells the initializer not to extract it again. If mounting fails the initializer extracts the snapshot as usual.
+func (s *WorkspaceService) mountContentLayer(ctx context.Context, instanceID, location string, initializer *csapi.WorkspaceInitializer, remoteContent map[string]storage.DownloadInfo, opts *RunInitializerOpts) {
+	if s.layers == nil {
+		return
+	}
+	if _, ok := remoteContent[storage.DefaultBackup]; ok {
+		// the backup replaces the snapshot
+		return
+	}
+	name, ok := layeredSnapshot(initializer)
+	if !ok {
+		return
+	}
+	info, ok := remoteContent[name]
+	if !ok {
+		return
+	}
+
+	err := s.layers.Mount(ctx, instanceID, location, name, info, opts.StagedContent[name], opts.IdMappings, int(opts.UID), int(opts.GID))
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", "", instanceID)).WithField("snapshot", name).Warn("cannot mount content layer - extracting the snapshot instead")
+		s.layers.observeMount("fallback")
+		return
+	}
+	opts.LayeredContent = name
+	s.layers.observeMount("layered")
+}
+
+// releaseContentLayer unmounts the content layer of a workspace if it has one
+func (s *WorkspaceService) releaseContentLayer(ctx context.Context, instanceID, location string) error {
+	if s.layers == nil {
+		return nil
+	}
+	return s.layers.Release(ctx, instanceID, location)
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/snapshots"
+	"github.com/google/go-cmp/cmp"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
+)
+
+func TestLayeredSnapshot(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Initializer *csapi.WorkspaceInitializer
+		Expected    string
+	}{
+		{
+			Name:        "snapshot",
+			Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Snapshot{Snapshot: &csapi.SnapshotInitializer{Snapshot: "bucket@snap.tar"}}},
+			Expected:    "bucket@snap.tar",
+		},
+		{
+			Name:        "prebuild",
+			Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Prebuild{Prebuild: &csapi.PrebuildInitializer{Prebuild: &csapi.SnapshotInitializer{Snapshot: "bucket@prebuild.tar"}}}},
+			Expected:    "bucket@prebuild.tar",
+		},
+		{
+			Name:        "prebuild without snapshot",
+			Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Prebuild{Prebuild: &csapi.PrebuildInitializer{}}},
+		},
+		{
+			Name:        "git",
+			Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Git{Git: &csapi.GitInitializer{}}},
+		},
+		{
+			Name: "composite",
+			Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Composite{Composite: &csapi.CompositeInitializer{
+				Layers: []*csapi.CompositeInitializerLayer{
+					{Initializer: &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Snapshot{Snapshot: &csapi.SnapshotInitializer{Snapshot: "bucket@snap.tar"}}}},
+				},
+			}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, ok := layeredSnapshot(test.Initializer)
+			if ok != (test.Expected != "") {
+				t.Errorf("unexpected result: %v", ok)
+			}
+			if act != test.Expected {
+				t.Errorf("unexpected snapshot: %q, expected %q", act, test.Expected)
+			}
+		})
+	}
+}
+
+func TestLayerKey(t *testing.T) {
+	userns := []archive.IDMapping{{ContainerID: 0, HostID: 33333, Size: 1}}
+	if layerKey("snap", nil) != layerKey("snap", nil) {
+		t.Error("layer key is not stable")
+	}
+	if layerKey("snap", nil) == layerKey("snap", userns) {
+		t.Error("content with different mappings must not share a layer")
+	}
+	if layerKey("snap", nil) == layerKey("other", nil) {
+		t.Error("different content must not share a layer")
+	}
+}
+
+func TestLowerDirs(t *testing.T) {
+	// Translate only maps paths which exist
+	root := t.TempDir()
+	for _, d := range []string{"snapshots/1/fs", "snapshots/2/fs"} {
+		err := os.MkdirAll(filepath.Join(root, d), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mapping := container.PathMapping{"/var/lib/containerd": root}
+	tests := []struct {
+		Name     string
+		Mounts   []mount.Mount
+		Expected []string
+		Invalid  bool
+	}{
+		{
+			Name:     "bind",
+			Mounts:   []mount.Mount{{Type: "bind", Source: "/var/lib/containerd/snapshots/1/fs", Options: []string{"ro", "rbind"}}},
+			Expected: []string{filepath.Join(root, "snapshots/1/fs")},
+		},
+		{
+			Name:     "overlay",
+			Mounts:   []mount.Mount{{Type: "overlay", Source: "overlay", Options: []string{"lowerdir=/var/lib/containerd/snapshots/2/fs:/var/lib/containerd/snapshots/1/fs"}}},
+			Expected: []string{filepath.Join(root, "snapshots/2/fs"), filepath.Join(root, "snapshots/1/fs")},
+		},
+		{Name: "unmapped path", Mounts: []mount.Mount{{Type: "bind", Source: "/elsewhere/fs"}}, Invalid: true},
+		{Name: "unsupported type", Mounts: []mount.Mount{{Type: "zfs", Source: "pool/fs"}}, Invalid: true},
+		{Name: "no mounts", Invalid: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := lowerDirs(test.Mounts, mapping)
+			if test.Invalid {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected lower dirs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoteContentStorageLayered(t *testing.T) {
+	rs := &remoteContentStorage{RemoteContent: map[string]storage.DownloadInfo{
+		"snap": {URL: layerURL},
+	}}
+	// a layered download must not touch the destination
+	exists, err := rs.Download(context.Background(), "/does-not-exist", "snap", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Error("layered content must exist")
+	}
+}
+
+func TestLayerStoreCollectGarbage(t *testing.T) {
+	now := time.Now()
+	sn := &fakeSnapshotter{infos: map[string]snapshots.Info{
+		"gitpod/content/unused":  {Name: "gitpod/content/unused", Labels: map[string]string{labelLayer: "a"}, Updated: now.Add(-3 * time.Hour)},
+		"gitpod/content/recent":  {Name: "gitpod/content/recent", Labels: map[string]string{labelLayer: "b"}, Updated: now.Add(-3 * time.Hour)},
+		"gitpod/content/in-use":  {Name: "gitpod/content/in-use", Labels: map[string]string{labelLayer: "c"}, Updated: now.Add(-3 * time.Hour)},
+		"gitpod/content-view/ws": {Name: "gitpod/content-view/ws", Parent: "gitpod/content/in-use", Labels: map[string]string{labelLayerView: "ws"}},
+		"k8s.io/some-image":      {Name: "k8s.io/some-image", Updated: now.Add(-3 * time.Hour)},
+	}}
+	s, err := newLayerStore(sn, nil, t.TempDir(), 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return now }
+	s.lastUsed["gitpod/content/recent"] = now.Add(-time.Hour)
+
+	err = s.collectGarbage(context.Background(), func(id string) bool { return id == "ws" })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var remaining []string
+	for k := range sn.infos {
+		remaining = append(remaining, k)
+	}
+	sort.Strings(remaining)
+	expected := []string{"gitpod/content-view/ws", "gitpod/content/in-use", "gitpod/content/recent", "k8s.io/some-image"}
+	if diff := cmp.Diff(expected, remaining); diff != "" {
+		t.Errorf("unexpected snapshots (-want +got):\n%s", diff)
+	}
+}
+
+type fakeSnapshotter struct {
+	snapshots.Snapshotter
+	infos map[string]snapshots.Info
+}
+
+func (f *fakeSnapshotter) Stat(ctx context.Context, key string) (snapshots.Info, error) {
+	info, ok := f.infos[key]
+	if !ok {
+		return snapshots.Info{}, errdefs.ErrNotFound
+	}
+	return info, nil
+}
+
+func (f *fakeSnapshotter) Walk(ctx context.Context, fn snapshots.WalkFunc, filters ...string) error {
+	for _, info := range f.infos {
+		err := fn(ctx, info)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeSnapshotter) Remove(ctx context.Context, key string) error {
+	if _, ok := f.infos[key]; !ok {
+		return errdefs.ErrNotFound
+	}
+	delete(f.infos, key)
+	return nil
+}
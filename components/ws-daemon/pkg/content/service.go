@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -29,8 +30,10 @@ import (
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
 	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
+	"github.com/gitpod-io/gitpod/content-service/pkg/qos"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/coldstart"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/iws"
@@ -46,13 +49,24 @@ type WorkspaceService struct {
 	stopService context.CancelFunc
 	sandboxes   quota.SandboxProvider
 	runtime     container.Runtime
+	queue       *qos.Queue
+	staging     *stagingCache
+	layers      *layerStore
+	compressor  *backupCompressor
+	timings     *coldstart.Recorder
+
+	teardownDuration *prometheus.HistogramVec
+
+	// ReleaseNodeResources removes what a workspace left on the node outside its working area, e.g. cgroups and network namespaces.
+	// Runs as the last step of the teardown if set.
+	ReleaseNodeResources func(ctx context.Context, instanceID string) error
 }
 
 // WorkspaceExistenceCheck is a check that can determine if a workspace container currently exists on this node.
 type WorkspaceExistenceCheck func(instanceID string) bool
 
 // NewWorkspaceService creates a new workspce initialization service, starts housekeeping and the Prometheus integration
-func NewWorkspaceService(ctx context.Context, cfg Config, kubernetesNamespace string, runtime container.Runtime, wec WorkspaceExistenceCheck, uidmapper *iws.Uidmapper, reg prometheus.Registerer) (res *WorkspaceService, err error) {
+func NewWorkspaceService(ctx context.Context, cfg Config, kubernetesNamespace string, runtime container.Runtime, wec WorkspaceExistenceCheck, uidmapper *iws.Uidmapper, timings *coldstart.Recorder, reg prometheus.Registerer) (res *WorkspaceService, err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "NewWorkspaceService")
 	defer tracing.FinishSpan(span, &err)
 
@@ -62,11 +76,82 @@ func NewWorkspaceService(ctx context.Context, cfg Config, kubernetesNamespace st
 		return nil, xerrors.Errorf("cannot create working area: %w", err)
 	}
 
+	// change journals do not survive restarts, neither do the base archives we built for them
+	err = os.RemoveAll(changeJournalLocation(cfg))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot clean up change journals: %w", err)
+	}
+	if cfg.ChangeJournal.Enabled {
+		err = os.MkdirAll(changeJournalLocation(cfg), 0755)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create change journal location: %w", err)
+		}
+	}
+
 	// read all session json files
-	store, err := session.NewStore(ctx, cfg.WorkingArea, workspaceLifecycleHooks(cfg, kubernetesNamespace, wec, uidmapper))
+	store, err := session.NewStore(ctx, cfg.WorkingArea, workspaceLifecycleHooks(cfg, kubernetesNamespace, wec, uidmapper, timings))
 	if err != nil {
 		return nil, xerrors.Errorf("cannot create session store: %w", err)
 	}
+
+	queue, err := qos.NewQueue(cfg.QoS)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create content job queue: %w", err)
+	}
+	err = queue.RegisterMetrics(reg)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot register content job queue metrics: %w", err)
+	}
+	var staging *stagingCache
+	if cfg.Staging.Enabled {
+		staging, err = newStagingCache(filepath.Join(cfg.WorkingArea, ".staging"), int64(cfg.Staging.MaxSize), time.Duration(cfg.Staging.TTL))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create staging cache: %w", err)
+		}
+		err = staging.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register staging cache metrics: %w", err)
+		}
+	}
+	var layers *layerStore
+	if cfg.Layers.Enabled {
+		if cfg.WorkspaceSizeLimit > 0 {
+			return nil, xerrors.Errorf("content layers cannot be combined with a workspace size limit")
+		}
+		ctrd, ok := runtime.(*container.Containerd)
+		if !ok {
+			return nil, xerrors.Errorf("content layers require containerd")
+		}
+		snapshotter := cfg.Layers.Snapshotter
+		if snapshotter == "" {
+			snapshotter = "overlayfs"
+		}
+		layers, err = newLayerStore(ctrd.Client.SnapshotService(snapshotter), ctrd.Mapping, cfg.WorkingArea, time.Duration(cfg.Layers.TTL))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create content layer store: %w", err)
+		}
+		err = layers.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register content layer metrics: %w", err)
+		}
+	}
+	var compressor *backupCompressor
+	if cfg.Compression.Enabled {
+		ps, err := storage.NewPresignedAccess(&cfg.Storage)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create presigned storage for compression dictionaries: %w", err)
+		}
+		compressor = newBackupCompressor(ps, cfg.Compression.DictionaryOwner, cfg.Compression.MinGain)
+		err = compressor.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register backup compression metrics: %w", err)
+		}
+	}
+	teardownDuration := newTeardownDurationHistogram()
+	err = reg.Register(teardownDuration)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot register teardown metrics: %w", err)
+	}
 	ctx, stopService := context.WithCancel(ctx)
 
 	if err := registerWorkingAreaDiskspaceGauge(cfg.WorkingArea, reg); err != nil {
@@ -79,6 +164,13 @@ func NewWorkspaceService(ctx context.Context, cfg Config, kubernetesNamespace st
 		ctx:         ctx,
 		stopService: stopService,
 		runtime:     runtime,
+		queue:       queue,
+		staging:     staging,
+		layers:      layers,
+		compressor:  compressor,
+		timings:     timings,
+
+		teardownDuration: teardownDuration,
 	}, nil
 }
 
@@ -106,6 +198,9 @@ func registerWorkingAreaDiskspaceGauge(workingArea string, reg prometheus.Regist
 // Start starts this workspace service and returns when the service gets stopped.
 // This function is intended to run as Go routine.
 func (s *WorkspaceService) Start() {
+	if s.layers != nil {
+		go s.layers.Run(s.ctx, s.WorkspaceExists)
+	}
 	s.store.StartHousekeeping(s.ctx, 5*time.Minute)
 }
 
@@ -122,6 +217,9 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 
 		if err != nil && status.Code(err) != codes.AlreadyExists {
 			// the session failed - clean it up
+			if lerr := s.releaseContentLayer(ctx, req.Id, filepath.Join(s.store.Location, req.Id)); lerr != nil {
+				log.WithError(lerr).WithFields(log.OWI("", "", req.Id)).Warn("cannot release content layer")
+			}
 			derr := s.store.Delete(ctx, req.Id)
 			if err == nil && derr != nil {
 				err = derr
@@ -229,7 +327,29 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 			opts.UID = wsinit.GitpodUID + 100000 - 1
 			opts.GID = wsinit.GitpodGID + 100000 - 1
 		}
-		err = RunInitializer(ctx, workspace.Location, req.Initializer, remoteContent, opts)
+		if s.staging != nil {
+			for name := range remoteContent {
+				if name == storage.DefaultBackup {
+					continue
+				}
+				path, release, ok := s.staging.Acquire(name)
+				if !ok {
+					continue
+				}
+				defer release()
+				if opts.StagedContent == nil {
+					opts.StagedContent = make(map[string]string)
+				}
+				opts.StagedContent[name] = path
+			}
+		}
+		// Restoring the content is what the user waits for - it must not queue up behind prebuild snapshot uploads
+		restored := s.timings.Measure(req.Id, coldstart.PhaseContent)
+		err = s.queue.Do(ctx, qos.ClassInteractive, func(ctx context.Context) error {
+			s.mountContentLayer(ctx, req.Id, workspace.Location, req.Initializer, remoteContent, &opts)
+			return RunInitializer(ctx, workspace.Location, req.Initializer, remoteContent, opts)
+		})
+		restored()
 		if err != nil {
 			log.WithError(err).WithField("workspaceId", req.Id).Error("cannot initialize workspace")
 			return nil, status.Error(codes.Internal, fmt.Sprintf("cannot initialize workspace: %s", err.Error()))
@@ -257,6 +377,7 @@ func (s *WorkspaceService) creator(req *api.InitWorkspaceRequest, upperdir strin
 			Location:            location,
 			UpperdirLocation:    upperdir,
 			CheckoutLocation:    getCheckoutLocation(req),
+			Repository:          initializerRepository(req.Initializer),
 			CreatedAt:           time.Now(),
 			Owner:               req.Metadata.Owner,
 			WorkspaceID:         req.Metadata.MetaId,
@@ -278,6 +399,7 @@ func (s *WorkspaceService) createSandbox(ctx context.Context, req *api.InitWorks
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "createSandbox")
 	defer tracing.FinishSpan(span, &err)
+	defer s.timings.Measure(req.Id, coldstart.PhaseMount)()
 
 	owi := log.OWI(req.Metadata.Owner, req.Metadata.MetaId, req.Id)
 	if req.FullWorkspaceBackup {
@@ -315,7 +437,12 @@ func (s *WorkspaceService) createSandbox(ctx context.Context, req *api.InitWorks
 
 // getCheckoutLocation returns the first checkout location found of any Git initializer configured by this request
 func getCheckoutLocation(req *api.InitWorkspaceRequest) string {
-	spec := req.Initializer.Spec
+	return initializerCheckoutLocation(req.Initializer)
+}
+
+// initializerCheckoutLocation returns the first checkout location found of any Git initializer within initializer
+func initializerCheckoutLocation(initializer *csapi.WorkspaceInitializer) string {
+	spec := initializer.Spec
 	if ir, ok := spec.(*csapi.WorkspaceInitializer_Git); ok {
 		if ir.Git != nil {
 			return ir.Git.CheckoutLocation
@@ -326,6 +453,42 @@ func getCheckoutLocation(req *api.InitWorkspaceRequest) string {
 			return ir.Prebuild.Git.CheckoutLocation
 		}
 	}
+	if ir, ok := spec.(*csapi.WorkspaceInitializer_Composite); ok && ir.Composite != nil {
+		for _, l := range ir.Composite.Layers {
+			if l.Initializer == nil {
+				continue
+			}
+			if loc := initializerCheckoutLocation(l.Initializer); loc != "" {
+				return filepath.Join(l.TargetLocation, loc)
+			}
+		}
+	}
+	return ""
+}
+
+// initializerRepository returns the remote URI of the first Git initializer within initializer
+func initializerRepository(initializer *csapi.WorkspaceInitializer) string {
+	spec := initializer.Spec
+	if ir, ok := spec.(*csapi.WorkspaceInitializer_Git); ok {
+		if ir.Git != nil {
+			return ir.Git.RemoteUri
+		}
+	}
+	if ir, ok := spec.(*csapi.WorkspaceInitializer_Prebuild); ok {
+		if ir.Prebuild != nil && ir.Prebuild.Git != nil {
+			return ir.Prebuild.Git.RemoteUri
+		}
+	}
+	if ir, ok := spec.(*csapi.WorkspaceInitializer_Composite); ok && ir.Composite != nil {
+		for _, l := range ir.Composite.Layers {
+			if l.Initializer == nil {
+				continue
+			}
+			if repo := initializerRepository(l.Initializer); repo != "" {
+				return repo
+			}
+		}
+	}
 	return ""
 }
 
@@ -367,55 +530,109 @@ func (s *WorkspaceService) DisposeWorkspace(ctx context.Context, req *api.Dispos
 	}
 
 	// Ok, we have to do all the work
-	if req.Backup {
-		var (
-			backupName = storage.DefaultBackup
-			mfName     = storage.DefaultBackupManifest
-		)
-		if sess.FullWorkspaceBackup {
-			backupName = fmt.Sprintf(storage.FmtFullWorkspaceBackup, time.Now().UnixNano())
-		}
+	var changes *workspaceChanges
+	if wj, ok := sess.NonPersistentAttrs[session.AttrChangeJournal].(*workspaceChangeJournal); ok {
+		defer wj.Close()
 
-		err = s.uploadWorkspaceContent(ctx, sess, backupName, mfName)
+		base, paths, err := wj.Finish()
 		if err != nil {
-			log.WithError(err).WithFields(sess.OWI()).Error("final backup failed")
-			return nil, status.Error(codes.DataLoss, "final backup failed")
+			log.WithError(err).WithFields(sess.OWI()).Info("cannot use change journal - backing up the whole workspace")
+		} else {
+			changes = &workspaceChanges{Base: base, Paths: paths}
 		}
 	}
 
-	// Update the git status prior to deleting the workspace
-	repo, err = sess.UpdateGitStatus(ctx)
-	if err != nil {
-		log.WithError(err).WithField("workspaceId", req.Id).Error("cannot get git status")
-		span.LogKV("error", err)
-		return nil, status.Error(codes.Internal, "cannot get git status")
-	}
-	if repo != nil {
-		resp.GitStatus = repo
+	// The steps which don't depend on each other run in parallel. Everything but the removal of the workspace
+	// content from the store can be repeated, hence the removal comes last: should any step fail, the next
+	// call to DisposeWorkspace resumes where this one stopped.
+	var steps []teardownStep
+	if req.Backup {
+		steps = append(steps, teardownStep{
+			Name: teardownBackup,
+			Run: func(ctx context.Context) error {
+				var (
+					backupName = storage.DefaultBackup
+					mfName     = storage.DefaultBackupManifest
+				)
+				if sess.FullWorkspaceBackup {
+					backupName = fmt.Sprintf(storage.FmtFullWorkspaceBackup, time.Now().UnixNano())
+				}
+
+				// the workspace cannot be restarted before its final backup is done, hence it's interactive
+				return s.uploadWorkspaceContent(ctx, sess, backupName, mfName, changes, qos.ClassInteractive)
+			},
+		})
 	}
-
+	steps = append(steps, teardownStep{
+		Name: teardownGitStatus,
+		Run: func(ctx context.Context) error {
+			_, err := sess.UpdateGitStatus(ctx)
+			return err
+		},
+	})
 	if s.config.WorkspaceSizeLimit > 0 && !sess.FullWorkspaceBackup {
-		// We can delete the sandbox here (rather than in the store) because WaitOrMarkForDisposal
-		// ensures we're doing this exclusively for this workspace.
-		err = s.sandboxes.Dispose(ctx, sess.Location)
-		if err != nil {
-			log.WithError(err).WithField("workspaceId", req.Id).Error("cannot dispose sandbox")
-			span.LogKV("error", err)
-			return nil, status.Error(codes.Internal, "cannot dispose sandbox")
-		}
+		steps = append(steps, teardownStep{
+			Name:  teardownSandbox,
+			After: []string{teardownBackup, teardownGitStatus},
+			Run: func(ctx context.Context) error {
+				// We can delete the sandbox here (rather than in the store) because WaitOrMarkForDisposal
+				// ensures we're doing this exclusively for this workspace.
+				return s.sandboxes.Dispose(ctx, sess.Location)
+			},
+		})
+	}
+	if !sess.FullWorkspaceBackup {
+		steps = append(steps, teardownStep{
+			Name:  teardownContentLayer,
+			After: []string{teardownBackup, teardownGitStatus},
+			Run: func(ctx context.Context) error {
+				return s.releaseContentLayer(ctx, req.Id, sess.Location)
+			},
+		})
 	}
+	if s.ReleaseNodeResources != nil {
+		steps = append(steps, teardownStep{
+			Name:  teardownNodeResources,
+			After: []string{teardownSandbox, teardownContentLayer},
+			Run: func(ctx context.Context) error {
+				return s.ReleaseNodeResources(ctx, req.Id)
+			},
+		})
+	}
+	steps = append(steps, teardownStep{
+		Name:  teardownRemove,
+		After: []string{teardownBackup, teardownGitStatus, teardownSandbox, teardownContentLayer, teardownNodeResources},
+		Run: func(ctx context.Context) error {
+			return s.store.Delete(ctx, req.Id)
+		},
+	})
 
-	err = s.store.Delete(ctx, req.Id)
+	err = s.runTeardown(ctx, sess.OWI(), sess, steps)
 	if err != nil {
-		log.WithError(err).WithField("workspaceId", req.Id).Error("cannot delete workspace from store")
+		log.WithError(err).WithFields(sess.OWI()).Error("cannot dispose workspace")
 		span.LogKV("error", err)
-		return nil, status.Error(codes.Internal, "cannot delete workspace from store")
+		sess.AbortDisposal()
+
+		if terr, ok := err.(*teardownError); ok && terr.Step(teardownBackup) != nil {
+			return nil, status.Error(codes.DataLoss, "final backup failed")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if sess.LastGitStatus != nil {
+		resp.GitStatus = sess.LastGitStatus
+	}
 	return resp, nil
 }
 
-func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *session.Workspace, backupName, mfName string) (err error) {
+// workspaceChanges are the paths which changed since the workspace content was archived in Base
+type workspaceChanges struct {
+	Base  string
+	Paths []string
+}
+
+// uploadWorkspaceContent backs up the workspace content. If changes is not nil, the backup only reads the changed paths from disk.
+func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *session.Workspace, backupName, mfName string, changes *workspaceChanges, cls qos.Class) (err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "uploadWorkspaceContent")
 	defer tracing.FinishSpan(span, &err)
 
@@ -474,19 +691,21 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 			}
 		}()
 
-		var opts []archive.TarOption
-		if sess.UserNamespaced && !sess.FullWorkspaceBackup {
-			mappings := []archive.IDMapping{
-				{ContainerID: 0, HostID: wsinit.GitpodUID, Size: 1},
-				{ContainerID: 1, HostID: 100000, Size: 65534},
+		opts := workspaceTarOptions(sess)
+		if changes != nil {
+			// we removed the ready file after the change journal stopped
+			paths := append(changes.Paths, wsinit.WorkspaceReadyFile)
+			err = BuildIncrementalTarbal(ctx, changes.Base, loc, paths, tmpf.Name(), opts...)
+			if err == nil {
+				log.WithField("changes", len(paths)).WithFields(sess.OWI()).Debug("built workspace backup from change journal")
+			} else if err != ErrMaxSizeExceeded {
+				log.WithError(err).WithFields(sess.OWI()).Warn("cannot build workspace backup from change journal - backing up the whole workspace")
+				changes = nil
 			}
-			opts = append(opts,
-				archive.WithUIDMapping(mappings),
-				archive.WithGIDMapping(mappings),
-			)
 		}
-
-		err = BuildTarbal(ctx, loc, tmpf.Name(), opts...)
+		if changes == nil {
+			err = BuildTarbal(ctx, loc, tmpf.Name(), opts...)
+		}
 		if err != nil {
 			return
 		}
@@ -517,6 +736,19 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 		}
 	}()
 
+	layerf := tmpf.Name()
+	if s.compressor != nil && !sess.FullWorkspaceBackup {
+		// FWB layers are consumed as uncompressed OCI layers and must stay that way
+		fn, annotations, err := s.compressBackup(ctx, sess, loc, tmpf.Name(), tmpfDigest, tmpfSize)
+		if err != nil {
+			log.WithError(err).WithFields(sess.OWI()).Warn("cannot compress workspace backup - uploading it uncompressed")
+		} else {
+			defer os.Remove(fn)
+			layerf = fn
+			opts = append(opts, storage.WithAnnotations(annotations))
+		}
+	}
+
 	var (
 		layerBucket string
 		layerObject string
@@ -534,12 +766,10 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 			}
 		}
 
-		layerBucket, layerObject, err = rs.Upload(ctx, tmpf.Name(), backupName, layerUploadOpts...)
-		if err != nil {
+		return s.queue.Do(ctx, cls, func(ctx context.Context) (err error) {
+			layerBucket, layerObject, err = rs.Upload(ctx, layerf, backupName, layerUploadOpts...)
 			return
-		}
-
-		return
+		})
 	})
 	if err != nil {
 		return xerrors.Errorf("cannot upload workspace content: %w", err)
@@ -588,11 +818,10 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 		// Upload new manifest without opts as don't want to overwrite the layer trail with the manifest.
 		// We have to make sure we use the right content type s.t. we can identify this as manifest later on,
 		// e.g. when distinguishing between legacy snapshots and new manifests.
-		_, _, err = rs.Upload(ctx, tmpmf.Name(), mfName, storage.WithContentType(csapi.ContentTypeManifest))
-		if err != nil {
+		return s.queue.Do(ctx, cls, func(ctx context.Context) error {
+			_, _, err := rs.Upload(ctx, tmpmf.Name(), mfName, storage.WithContentType(csapi.ContentTypeManifest))
 			return err
-		}
-		return nil
+		})
 	})
 	if err != nil {
 		return xerrors.Errorf("cannot upload workspace content manifest: %w", err)
@@ -601,6 +830,51 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 	return nil
 }
 
+// compressBackup compresses the backup archive src into a new temp file and returns the annotations which describe the result.
+// Restores detect the compression from the content itself, the annotations merely keep compressed objects recognisable.
+func (s *WorkspaceService) compressBackup(ctx context.Context, sess *session.Workspace, loc, src string, uncompressedDigest digest.Digest, uncompressedSize int64) (fn string, annotations map[string]string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "compressBackup")
+	defer tracing.FinishSpan(span, &err)
+
+	in, err := os.Open(src)
+	if err != nil {
+		return "", nil, err
+	}
+	defer in.Close()
+	out, err := os.CreateTemp(s.config.TmpDir, fmt.Sprintf("wsbkp-%s-*.tar.z", sess.InstanceID))
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		out.Close()
+		if err != nil {
+			os.Remove(out.Name())
+		}
+	}()
+
+	dgst := digest.Canonical.Digester()
+	method, err := s.compressor.Compress(ctx, sess.Repository, loc, in, io.MultiWriter(out, dgst.Hash()))
+	if err != nil {
+		return "", nil, err
+	}
+	err = out.Sync()
+	if err != nil {
+		return "", nil, err
+	}
+	stat, err := out.Stat()
+	if err != nil {
+		return "", nil, err
+	}
+	s.compressor.observe(method, uncompressedSize, stat.Size())
+	span.LogKV("method", method, "size", stat.Size(), "uncompressedSize", uncompressedSize)
+
+	return out.Name(), map[string]string{
+		storage.ObjectAnnotationDigest:             dgst.Digest().String(),
+		storage.ObjectAnnotationUncompressedDigest: uncompressedDigest.String(),
+		storage.ObjectAnnotationCompression:        string(method),
+	}, nil
+}
+
 func retryIfErr(ctx context.Context, attempts int, log *logrus.Entry, op func(ctx context.Context) error) (err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "retryIfErr")
 	defer tracing.FinishSpan(span, &err)
@@ -710,7 +984,8 @@ func (s *WorkspaceService) TakeSnapshot(ctx context.Context, req *api.TakeSnapsh
 		snapshotName = rs.Qualify(backupName)
 	}
 
-	err = s.uploadWorkspaceContent(ctx, sess, backupName, mfName)
+	// Snapshots are mostly taken of prebuilds, which nobody is waiting for
+	err = s.uploadWorkspaceContent(ctx, sess, backupName, mfName, nil, qos.ClassBackground)
 	if err != nil {
 		log.WithError(err).WithField("workspaceId", req.Id).Error("snapshot upload failed")
 		return nil, status.Error(codes.Internal, "cannot upload snapshot")
@@ -721,6 +996,59 @@ func (s *WorkspaceService) TakeSnapshot(ctx context.Context, req *api.TakeSnapsh
 	}, nil
 }
 
+// StageSnapshot downloads a snapshot into the node's staging cache
+func (s *WorkspaceService) StageSnapshot(ctx context.Context, req *api.StageSnapshotRequest) (res *api.StageSnapshotResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "StageSnapshot")
+	span.SetTag("snapshot", req.Snapshot)
+	defer tracing.FinishSpan(span, &err)
+
+	if s.staging == nil {
+		return nil, status.Error(codes.FailedPrecondition, "snapshot staging is disabled on this node")
+	}
+	if _, _, err := storage.ParseSnapshotName(req.Snapshot); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid snapshot name")
+	}
+	if req.Url == "" {
+		return nil, status.Error(codes.InvalidArgument, "url is required")
+	}
+	if req.Size < 0 {
+		return nil, status.Error(codes.InvalidArgument, "size must not be negative")
+	}
+
+	var (
+		alreadyStaged bool
+		size          int64
+	)
+	// Staging is speculative - it must not hold up content restores of workspaces that are starting
+	err = s.queue.Do(ctx, qos.ClassBackground, func(ctx context.Context) (err error) {
+		alreadyStaged, size, err = s.staging.Stage(ctx, req.Snapshot, req.Url, req.Size)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).WithField("snapshot", req.Snapshot).Error("cannot stage snapshot")
+		return nil, status.Error(codes.Internal, fmt.Sprintf("cannot stage snapshot: %v", err))
+	}
+
+	return &api.StageSnapshotResponse{
+		AlreadyStaged: alreadyStaged,
+		Size:          size,
+	}, nil
+}
+
+// WorkspaceExists returns true if this service still manages the content of the workspace instance
+func (s *WorkspaceService) WorkspaceExists(instanceID string) bool {
+	return s.store.Get(instanceID) != nil
+}
+
+// WorkspaceLocation returns the location of the content of a workspace instance this service manages
+func (s *WorkspaceService) WorkspaceLocation(instanceID string) (loc string, ok bool) {
+	sess := s.store.Get(instanceID)
+	if sess == nil {
+		return "", false
+	}
+	return sess.Location, true
+}
+
 // Close ends this service and its housekeeping
 func (s *WorkspaceService) Close() error {
 	s.stopService()
@@ -760,7 +1088,7 @@ func (c *cannotCancelContext) Value(key interface{}) interface{} {
 	return c.Delegate.Value(key)
 }
 
-func workspaceLifecycleHooks(cfg Config, kubernetesNamespace string, workspaceExistenceCheck WorkspaceExistenceCheck, uidmapper *iws.Uidmapper) map[session.WorkspaceState][]session.WorkspaceLivecycleHook {
+func workspaceLifecycleHooks(cfg Config, kubernetesNamespace string, workspaceExistenceCheck WorkspaceExistenceCheck, uidmapper *iws.Uidmapper, timings *coldstart.Recorder) map[session.WorkspaceState][]session.WorkspaceLivecycleHook {
 	var setupWorkspace session.WorkspaceLivecycleHook = func(ctx context.Context, ws *session.Workspace) error {
 		if _, ok := ws.NonPersistentAttrs[session.AttrRemoteStorage]; !ok {
 			remoteStorage, err := storage.NewDirectAccess(&cfg.Storage)
@@ -791,6 +1119,38 @@ func workspaceLifecycleHooks(cfg Config, kubernetesNamespace string, workspaceEx
 
 		return nil
 	}
+	var startChangeJournal session.WorkspaceLivecycleHook = func(ctx context.Context, ws *session.Workspace) (err error) {
+		if !cfg.ChangeJournal.Enabled || ws.FullWorkspaceBackup {
+			return
+		}
+		if _, ok := ws.NonPersistentAttrs[session.AttrChangeJournal]; ok {
+			return
+		}
+
+		j, err := startChangeJournal(ws.Location, cfg.ChangeJournal.MaxWatches)
+		if err != nil {
+			// without a journal we back up the whole workspace on stop
+			log.WithError(err).WithFields(ws.OWI()).Warn("cannot start change journal")
+			return nil
+		}
+		wj := &workspaceChangeJournal{
+			journal: j,
+			base:    filepath.Join(changeJournalLocation(cfg), ws.InstanceID+".tar"),
+			ready:   make(chan struct{}),
+		}
+		go func() {
+			defer close(wj.ready)
+
+			// the journal is already running, hence everything that changes while we build the base archive is read again on stop
+			wj.err = BuildTarbal(context.Background(), ws.Location, wj.base, workspaceTarOptions(ws)...)
+			if wj.err != nil {
+				log.WithError(wj.err).WithFields(ws.OWI()).Warn("cannot build base archive for change journal")
+			}
+		}()
+		ws.NonPersistentAttrs[session.AttrChangeJournal] = wj
+
+		return nil
+	}
 	var startLiveBackup session.WorkspaceLivecycleHook = func(ctx context.Context, ws *session.Workspace) (err error) {
 		if !ws.FullWorkspaceBackup {
 			return
@@ -816,8 +1176,28 @@ func workspaceLifecycleHooks(cfg Config, kubernetesNamespace string, workspaceEx
 	}
 
 	return map[session.WorkspaceState][]session.WorkspaceLivecycleHook{
-		session.WorkspaceInitializing: {setupWorkspace, iws.ServeWorkspace(uidmapper)},
-		session.WorkspaceReady:        {setupWorkspace, startLiveBackup},
+		session.WorkspaceInitializing: {setupWorkspace, iws.ServeWorkspace(uidmapper, timings)},
+		session.WorkspaceReady:        {setupWorkspace, startLiveBackup, startChangeJournal},
 		session.WorkspaceDisposing:    {iws.StopServingWorkspace},
 	}
 }
+
+// workspaceTarOptions returns the options for archiving the content of a workspace
+func workspaceTarOptions(sess *session.Workspace) []archive.TarOption {
+	if !sess.UserNamespaced || sess.FullWorkspaceBackup {
+		return nil
+	}
+	mappings := []archive.IDMapping{
+		{ContainerID: 0, HostID: wsinit.GitpodUID, Size: 1},
+		{ContainerID: 1, HostID: 100000, Size: 65534},
+	}
+	return []archive.TarOption{
+		archive.WithUIDMapping(mappings),
+		archive.WithGIDMapping(mappings),
+	}
+}
+
+// changeJournalLocation is where we keep the base archives of the change journals
+func changeJournalLocation(cfg Config) string {
+	return filepath.Join(cfg.WorkingArea, ".journal")
+}
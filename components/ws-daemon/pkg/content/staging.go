@@ -0,0 +1,359 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	stagedFileSuffix = ".tar"
+	stagingTmpPrefix = "download-"
+	stagingUsePrefix = "use-"
+
+	// defaultStagingTTL is the time after which a staged snapshot nobody used is evicted
+	defaultStagingTTL = 2 * time.Hour
+)
+
+// stagingCache keeps snapshot archives on the node so that workspaces starting from them
+// need not download them again. Archives are addressed by the hash of their snapshot name.
+type stagingCache struct {
+	Location string
+	MaxSize  int64
+	TTL      time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]*stagedSnapshot
+	inflight map[string]*stagingDownload
+	now      func() time.Time
+
+	metrics *stagingMetrics
+}
+
+type stagedSnapshot struct {
+	Path     string
+	Size     int64
+	LastUsed time.Time
+}
+
+type stagingDownload struct {
+	done chan struct{}
+	err  error
+}
+
+type stagingMetrics struct {
+	requests *prometheus.CounterVec
+	bytes    prometheus.GaugeFunc
+}
+
+// newStagingCache creates a staging cache in location and picks up the archives staged before ws-daemon restarted
+func newStagingCache(location string, maxSize int64, ttl time.Duration) (*stagingCache, error) {
+	if ttl == 0 {
+		ttl = defaultStagingTTL
+	}
+	err := os.MkdirAll(location, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create staging area: %w", err)
+	}
+
+	c := &stagingCache{
+		Location: location,
+		MaxSize:  maxSize,
+		TTL:      ttl,
+		entries:  make(map[string]*stagedSnapshot),
+		inflight: make(map[string]*stagingDownload),
+		now:      time.Now,
+	}
+
+	files, err := os.ReadDir(location)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read staging area: %w", err)
+	}
+	for _, f := range files {
+		fn := filepath.Join(location, f.Name())
+		if f.IsDir() || !strings.HasSuffix(f.Name(), stagedFileSuffix) || strings.HasPrefix(f.Name(), stagingTmpPrefix) || strings.HasPrefix(f.Name(), stagingUsePrefix) {
+			// leftovers of downloads or workspace starts which were interrupted
+			_ = os.RemoveAll(fn)
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		c.entries[strings.TrimSuffix(f.Name(), stagedFileSuffix)] = &stagedSnapshot{
+			Path:     fn,
+			Size:     info.Size(),
+			LastUsed: info.ModTime(),
+		}
+	}
+
+	return c, nil
+}
+
+// RegisterMetrics registers the staging cache's Prometheus metrics
+func (c *stagingCache) RegisterMetrics(reg prometheus.Registerer) error {
+	m := &stagingMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "staged_snapshot_requests_total",
+			Help: "Number of staged snapshot lookups and stage requests",
+		}, []string{"op", "outcome"}),
+		bytes: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "staged_snapshot_bytes",
+			Help: "Size of all snapshots staged on this node",
+		}, func() float64 {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return float64(c.size())
+		}),
+	}
+	for _, col := range []prometheus.Collector{m.requests, m.bytes} {
+		err := reg.Register(col)
+		if err != nil {
+			return err
+		}
+	}
+	c.metrics = m
+	return nil
+}
+
+func (c *stagingCache) observe(op, outcome string) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.requests.WithLabelValues(op, outcome).Inc()
+}
+
+func stagingKey(name string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+}
+
+// size returns the total size of all staged snapshots. Callers must hold mu.
+func (c *stagingCache) size() (res int64) {
+	for _, e := range c.entries {
+		res += e.Size
+	}
+	return
+}
+
+// Stage downloads the snapshot from url unless it's staged already.
+// If size is known (i.e. > 0) the download must match it.
+func (c *stagingCache) Stage(ctx context.Context, name, url string, size int64) (alreadyStaged bool, stagedSize int64, err error) {
+	key := stagingKey(name)
+	for {
+		c.mu.Lock()
+		if e, ok := c.entries[key]; ok {
+			e.LastUsed = c.now()
+			c.mu.Unlock()
+			c.observe("stage", "hit")
+			return true, e.Size, nil
+		}
+		dl, ok := c.inflight[key]
+		if !ok {
+			dl = &stagingDownload{done: make(chan struct{})}
+			c.inflight[key] = dl
+			c.mu.Unlock()
+			break
+		}
+		c.mu.Unlock()
+
+		// someone else is staging this snapshot already - wait for them and try again
+		select {
+		case <-dl.done:
+		case <-ctx.Done():
+			return false, 0, ctx.Err()
+		}
+		if dl.err != nil {
+			return false, 0, dl.err
+		}
+	}
+
+	e, err := c.download(ctx, key, url, size)
+
+	c.mu.Lock()
+	dl := c.inflight[key]
+	delete(c.inflight, key)
+	if err == nil {
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+	dl.err = err
+	close(dl.done)
+
+	if err != nil {
+		c.observe("stage", "failed")
+		return false, 0, err
+	}
+	c.observe("stage", "downloaded")
+	return false, e.Size, nil
+}
+
+func (c *stagingCache) download(ctx context.Context, key, url string, size int64) (*stagedSnapshot, error) {
+	if c.MaxSize > 0 && size > c.MaxSize {
+		return nil, xerrors.Errorf("snapshot size %d exceeds staging area size %d", size, c.MaxSize)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download snapshot: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("cannot download snapshot: %s", resp.Status)
+	}
+	if size <= 0 {
+		size = resp.ContentLength
+	}
+	if size > 0 {
+		err = c.makeRoom(size)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tmp, err := os.CreateTemp(c.Location, stagingTmpPrefix+"*"+stagedFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	src := io.Reader(resp.Body)
+	if c.MaxSize > 0 {
+		src = io.LimitReader(resp.Body, c.MaxSize+1)
+	}
+	n, err := io.Copy(tmp, src)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download snapshot: %w", err)
+	}
+	if size > 0 && n != size {
+		return nil, xerrors.Errorf("downloaded %d bytes but expected %d", n, size)
+	}
+	if c.MaxSize > 0 && n > c.MaxSize {
+		return nil, xerrors.Errorf("snapshot exceeds staging area size %d", c.MaxSize)
+	}
+	if size <= 0 {
+		err = c.makeRoom(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fn := filepath.Join(c.Location, key+stagedFileSuffix)
+	err = os.Rename(tmp.Name(), fn)
+	if err != nil {
+		return nil, err
+	}
+	return &stagedSnapshot{Path: fn, Size: n, LastUsed: c.now()}, nil
+}
+
+// makeRoom evicts expired snapshots and, if that's not enough, the least recently used ones
+// until another size bytes fit into the staging area.
+func (c *stagingCache) makeRoom(size int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpired()
+	if c.MaxSize <= 0 {
+		return nil
+	}
+
+	var (
+		total = c.size()
+		lru   = make([]string, 0, len(c.entries))
+	)
+	for k := range c.entries {
+		lru = append(lru, k)
+	}
+	sort.Slice(lru, func(i, j int) bool { return c.entries[lru[i]].LastUsed.Before(c.entries[lru[j]].LastUsed) })
+	for _, k := range lru {
+		if total+size <= c.MaxSize {
+			break
+		}
+		total -= c.entries[k].Size
+		c.evict(k)
+	}
+	return nil
+}
+
+// evictExpired removes all snapshots nobody used within the TTL. Callers must hold mu.
+func (c *stagingCache) evictExpired() {
+	now := c.now()
+	for k, e := range c.entries {
+		if now.Sub(e.LastUsed) > c.TTL {
+			c.evict(k)
+		}
+	}
+}
+
+// evict removes a staged snapshot. Workspaces which are initializing from it keep their own link. Callers must hold mu.
+func (c *stagingCache) evict(key string) {
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	delete(c.entries, key)
+	err := os.Remove(e.Path)
+	if err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("path", e.Path).Warn("cannot remove staged snapshot")
+	}
+}
+
+// Acquire returns a path to the staged snapshot which stays valid until release is called,
+// even if the snapshot is evicted in the meantime. If the snapshot isn't staged, ok is false.
+func (c *stagingCache) Acquire(name string) (path string, release func(), ok bool) {
+	key := stagingKey(name)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpired()
+	e, ok := c.entries[key]
+	if !ok {
+		c.observe("acquire", "miss")
+		return "", nil, false
+	}
+
+	f, err := os.CreateTemp(c.Location, stagingUsePrefix+"*"+stagedFileSuffix)
+	if err != nil {
+		log.WithError(err).Warn("cannot use staged snapshot")
+		c.observe("acquire", "failed")
+		return "", nil, false
+	}
+	path = f.Name()
+	f.Close()
+	_ = os.Remove(path)
+
+	err = os.Link(e.Path, path)
+	if err != nil {
+		log.WithError(err).Warn("cannot use staged snapshot")
+		c.observe("acquire", "failed")
+		return "", nil, false
+	}
+	e.LastUsed = c.now()
+	c.observe("acquire", "hit")
+
+	return path, func() { _ = os.Remove(path) }, true
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStagingCache(t *testing.T) {
+	var downloads int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&downloads, 1)
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer srv.Close()
+
+	c, err := newStagingCache(t.TempDir(), 250, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, size, err := c.Stage(ctx, "snapshot-a.tar@bucket", srv.URL, 100)
+			if err != nil {
+				t.Error(err)
+			}
+			if size != 100 {
+				t.Errorf("unexpected size: %d", size)
+			}
+		}()
+	}
+	wg.Wait()
+	if downloads != 1 {
+		t.Errorf("expected concurrent stage requests to share a download, got %d downloads", downloads)
+	}
+
+	already, _, err := c.Stage(ctx, "snapshot-a.tar@bucket", srv.URL, 100)
+	if err != nil || !already {
+		t.Errorf("expected snapshot to be staged already: %v", err)
+	}
+	_, _, err = c.Stage(ctx, "snapshot-b.tar@bucket", srv.URL, 50)
+	if err == nil {
+		t.Error("expected size mismatch to fail")
+	}
+
+	now = now.Add(time.Minute)
+	_, _, err = c.Stage(ctx, "snapshot-b.tar@bucket", srv.URL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a is used more recently than b, hence staging c evicts b
+	now = now.Add(time.Minute)
+	path, release, ok := c.Acquire("snapshot-a.tar@bucket")
+	if !ok {
+		t.Fatal("expected staged snapshot a")
+	}
+	now = now.Add(time.Minute)
+	_, _, err = c.Stage(ctx, "snapshot-c.tar@bucket", srv.URL, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := c.Acquire("snapshot-b.tar@bucket"); ok {
+		t.Error("expected snapshot b to be evicted")
+	}
+
+	// an acquired snapshot stays readable even after it was evicted
+	now = now.Add(2 * time.Hour)
+	if _, _, ok := c.Acquire("snapshot-a.tar@bucket"); ok {
+		t.Error("expected snapshot a to be expired")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("acquired snapshot is gone: %v", err)
+	}
+	release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected release to remove acquired snapshot, got %v", err)
+	}
+}
+
+func TestStagingCacheRestart(t *testing.T) {
+	loc := t.TempDir()
+	for _, fn := range []string{stagingKey("a.tar@bucket") + stagedFileSuffix, stagingTmpPrefix + "123" + stagedFileSuffix, stagingUsePrefix + "456" + stagedFileSuffix} {
+		err := os.WriteFile(filepath.Join(loc, fn), []byte("content"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := newStagingCache(loc, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.entries) != 1 {
+		t.Errorf("expected one staged snapshot, got %d", len(c.entries))
+	}
+	files, _ := os.ReadDir(loc)
+	if len(files) != 1 {
+		t.Errorf("expected leftovers to be removed, got %d files", len(files))
+	}
+	_, release, ok := c.Acquire("a.tar@bucket")
+	if !ok {
+		t.Fatal("expected staged snapshot to survive restart")
+	}
+	release()
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// the steps of the workspace teardown
+const (
+	// teardownBackup uploads the final backup
+	teardownBackup = "backup"
+	// teardownGitStatus records the Git status of the workspace content
+	teardownGitStatus = "git-status"
+	// teardownSandbox unmounts and removes the sandbox of workspaces with a size limit
+	teardownSandbox = "sandbox"
+	// teardownContentLayer unmounts the content layer of the workspace
+	teardownContentLayer = "content-layer"
+	// teardownRemove removes the workspace content from the working area
+	teardownRemove = "remove"
+	// teardownNodeResources removes the mounts, cgroups and network namespaces the workspace left on the node
+	teardownNodeResources = "node-resources"
+)
+
+// defaultTeardownSteps configures the steps unless the config says otherwise. The backup retries on its own.
+var defaultTeardownSteps = map[string]TeardownStepConfig{
+	teardownBackup:        {Attempts: 1},
+	teardownGitStatus:     {Timeout: util.Duration(time.Minute), Attempts: 2},
+	teardownSandbox:       {Timeout: util.Duration(time.Minute), Attempts: 3},
+	teardownContentLayer:  {Timeout: util.Duration(time.Minute), Attempts: 3},
+	teardownRemove:        {Timeout: util.Duration(2 * time.Minute), Attempts: 3},
+	teardownNodeResources: {Timeout: util.Duration(30 * time.Second), Attempts: 2},
+}
+
+// teardownStep is a step of the workspace teardown
+type teardownStep struct {
+	Name string
+	// After are the steps which must succeed before this one runs. Steps which are not part of the teardown are ignored.
+	After []string
+	Run   func(ctx context.Context) error
+}
+
+// teardownProgress remembers the completed steps, so that a retried teardown continues where a failed one stopped
+type teardownProgress interface {
+	TeardownStepDone(name string) bool
+	MarkTeardownStepDone(name string) error
+}
+
+// teardownError lists the steps of a teardown which failed, or did not run because a step they depend on failed
+type teardownError struct {
+	Failed map[string]error
+}
+
+func (e *teardownError) Error() string {
+	steps := make([]string, 0, len(e.Failed))
+	for name, err := range e.Failed {
+		steps = append(steps, fmt.Sprintf("%s: %v", name, err))
+	}
+	sort.Strings(steps)
+	return "teardown failed: " + strings.Join(steps, "; ")
+}
+
+// Step returns the error of a step, or nil if it succeeded
+func (e *teardownError) Step(name string) error {
+	return e.Failed[name]
+}
+
+// errTeardownDependency is the error of steps which did not run because a step they depend on failed
+type errTeardownDependency string
+
+func (e errTeardownDependency) Error() string {
+	return fmt.Sprintf("skipped because %s failed", string(e))
+}
+
+// runTeardown runs the steps of a teardown. Each step starts as soon as the steps it depends on succeeded,
+// so independent steps run in parallel. Returns a *teardownError if any step failed.
+func (s *WorkspaceService) runTeardown(ctx context.Context, owi logrus.Fields, progress teardownProgress, steps []teardownStep) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "runTeardown")
+	defer tracing.FinishSpan(span, &err)
+
+	done := make(map[string]chan struct{}, len(steps))
+	for _, step := range steps {
+		done[step.Name] = make(chan struct{})
+	}
+
+	var (
+		mu     sync.Mutex
+		failed = make(map[string]error)
+		wg     sync.WaitGroup
+	)
+	fail := func(name string, err error) {
+		mu.Lock()
+		failed[name] = err
+		mu.Unlock()
+	}
+	for _, step := range steps {
+		wg.Add(1)
+		go func(step teardownStep) {
+			defer wg.Done()
+			defer close(done[step.Name])
+
+			for _, dep := range step.After {
+				c, ok := done[dep]
+				if !ok {
+					continue
+				}
+				<-c
+
+				mu.Lock()
+				_, depFailed := failed[dep]
+				mu.Unlock()
+				if depFailed {
+					fail(step.Name, errTeardownDependency(dep))
+					return
+				}
+			}
+
+			if progress.TeardownStepDone(step.Name) {
+				log.WithFields(owi).WithField("step", step.Name).Debug("teardown step completed before - skipping")
+				return
+			}
+			err := s.runTeardownStep(ctx, owi, step)
+			if err != nil {
+				fail(step.Name, err)
+				return
+			}
+			err = progress.MarkTeardownStepDone(step.Name)
+			if err != nil {
+				// a retried teardown will run the step again, which is fine
+				log.WithError(err).WithFields(owi).WithField("step", step.Name).Warn("cannot record teardown progress")
+			}
+		}(step)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return &teardownError{Failed: failed}
+	}
+	return nil
+}
+
+// runTeardownStep runs a single step with its timeout and retries
+func (s *WorkspaceService) runTeardownStep(ctx context.Context, owi logrus.Fields, step teardownStep) (err error) {
+	cfg := s.teardownStepConfig(step.Name)
+	start := time.Now()
+	defer func() {
+		if s.teardownDuration == nil {
+			return
+		}
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+		}
+		s.teardownDuration.WithLabelValues(step.Name, outcome).Observe(time.Since(start).Seconds())
+	}()
+
+	return retryIfErr(ctx, cfg.Attempts, log.WithFields(owi).WithField("op", "teardown "+step.Name), func(ctx context.Context) error {
+		if cfg.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.Timeout))
+			defer cancel()
+		}
+		return step.Run(ctx)
+	})
+}
+
+// teardownStepConfig returns the configuration of a step, using its defaults where the config has none
+func (s *WorkspaceService) teardownStepConfig(name string) TeardownStepConfig {
+	res := defaultTeardownSteps[name]
+	if c, ok := s.config.Teardown[name]; ok {
+		if c.Timeout > 0 {
+			res.Timeout = c.Timeout
+		}
+		if c.Attempts > 0 {
+			res.Attempts = c.Attempts
+		}
+	}
+	if res.Attempts == 0 {
+		res.Attempts = 1
+	}
+	return res
+}
+
+func newTeardownDurationHistogram() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "workspace_teardown_step_seconds",
+		Help:    "Time the steps of the workspace teardown take, including retries",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 14),
+	}, []string{"step", "outcome"})
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+type fakeTeardownProgress struct {
+	mu   sync.Mutex
+	Done []string
+}
+
+func (p *fakeTeardownProgress) TeardownStepDone(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, n := range p.Done {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *fakeTeardownProgress) MarkTeardownStepDone(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Done = append(p.Done, name)
+	return nil
+}
+
+func TestRunTeardown(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	record := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			ran = append(ran, name)
+			mu.Unlock()
+			return err
+		}
+	}
+
+	tests := []struct {
+		Name     string
+		Steps    []teardownStep
+		Done     []string
+		Ran      []string
+		Failed   []string
+		Progress []string
+		// Unordered is set if the steps which ran don't depend on each other
+		Unordered bool
+	}{
+		{
+			Name: "ordering",
+			Steps: []teardownStep{
+				{Name: "remove", After: []string{"sandbox"}, Run: record("remove", nil)},
+				{Name: "sandbox", After: []string{"backup", "not-part-of-the-teardown"}, Run: record("sandbox", nil)},
+				{Name: "backup", Run: record("backup", nil)},
+			},
+			Ran:      []string{"backup", "sandbox", "remove"},
+			Progress: []string{"backup", "sandbox", "remove"},
+		},
+		{
+			Name: "failed dependency",
+			Steps: []teardownStep{
+				{Name: "backup", Run: record("backup", fmt.Errorf("upload failed"))},
+				{Name: "git-status", Run: record("git-status", nil)},
+				{Name: "sandbox", After: []string{"backup", "git-status"}, Run: record("sandbox", nil)},
+				{Name: "remove", After: []string{"sandbox"}, Run: record("remove", nil)},
+			},
+			Ran:       []string{"backup", "git-status"},
+			Failed:    []string{"backup", "remove", "sandbox"},
+			Progress:  []string{"git-status"},
+			Unordered: true,
+		},
+		{
+			Name: "resume",
+			Steps: []teardownStep{
+				{Name: "backup", Run: record("backup", nil)},
+				{Name: "remove", After: []string{"backup"}, Run: record("remove", nil)},
+			},
+			Done:     []string{"backup"},
+			Ran:      []string{"remove"},
+			Progress: []string{"backup", "remove"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ran = nil
+			progress := &fakeTeardownProgress{Done: append([]string{}, test.Done...)}
+			s := &WorkspaceService{}
+
+			err := s.runTeardown(context.Background(), logrus.Fields{}, progress, test.Steps)
+
+			var failed []string
+			if terr, ok := err.(*teardownError); ok {
+				for _, step := range test.Steps {
+					if terr.Step(step.Name) != nil {
+						failed = append(failed, step.Name)
+					}
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sort.Strings(failed)
+			if diff := cmp.Diff(test.Failed, failed); diff != "" {
+				t.Errorf("unexpected failed steps (-want +got):\n%s", diff)
+			}
+
+			act := append([]string{}, ran...)
+			if test.Unordered {
+				sort.Strings(act)
+			}
+			if diff := cmp.Diff(test.Ran, act); diff != "" {
+				t.Errorf("unexpected steps (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.Progress, progress.Done); diff != "" {
+				t.Errorf("unexpected progress (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunTeardownParallel(t *testing.T) {
+	var (
+		running int32
+		started = make(chan struct{}, 2)
+		release = make(chan struct{})
+	)
+	parallel := func(ctx context.Context) error {
+		atomic.AddInt32(&running, 1)
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+	steps := []teardownStep{
+		{Name: "backup", Run: parallel},
+		{Name: "git-status", Run: parallel},
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- (&WorkspaceService{}).runTeardown(context.Background(), logrus.Fields{}, &fakeTeardownProgress{}, steps)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("independent steps did not run in parallel: %d running", atomic.LoadInt32(&running))
+		}
+	}
+	close(release)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunTeardownRetries(t *testing.T) {
+	var attempts int32
+	s := &WorkspaceService{
+		config: Config{Teardown: map[string]TeardownStepConfig{
+			teardownRemove: {Attempts: 2},
+		}},
+	}
+	steps := []teardownStep{
+		{Name: teardownRemove, Run: func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("step runs without a timeout")
+			}
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				return fmt.Errorf("device or resource busy")
+			}
+			return nil
+		}},
+	}
+
+	err := s.runTeardown(context.Background(), logrus.Fields{}, &fakeTeardownProgress{}, steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected two attempts, got %d", attempts)
+	}
+}
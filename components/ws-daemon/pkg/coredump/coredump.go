@@ -0,0 +1,247 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package coredump decides what happens with the core dumps of workspace processes. Workspaces either keep their dumps
+// in the workspace directory, within size limits, or have them discarded.
+//
+// The kernel's core_pattern is node-wide, but a pattern which is an absolute path resolves within the mount namespace
+// of the crashing process. Hence we point core_pattern at a directory in /workspace, and limit the dump size of each
+// workspace using RLIMIT_CORE.
+package coredump
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+const (
+	// Location is where workspaces find their core dumps. Supervisor expects them there, too.
+	Location = "/workspace/.gitpod/cores"
+	// Pattern is the core_pattern we set on the node
+	Pattern = Location + "/core.%e.%p.%t"
+
+	// ModeCapture keeps core dumps in the workspace
+	ModeCapture = "capture"
+	// ModeDiscard prevents core dumps
+	ModeDiscard = "discard"
+
+	defaultMaxDumps      = 5
+	defaultCheckInterval = 30 * time.Second
+)
+
+// Config configures the core dump handling of workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Default is the policy of workspaces whose class has no policy. If not set, we leave such workspaces alone.
+	Default *Policy `json:"default,omitempty"`
+	// Classes maps workspace classes to their policy. The class of a workspace is its type, e.g. regular or prebuild.
+	Classes map[string]Policy `json:"classes,omitempty"`
+	// CheckInterval is how often we enforce the size limits of captured dumps. Defaults to 30 seconds.
+	CheckInterval util.Duration `json:"checkInterval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Default != nil {
+		if err := c.Default.Validate(); err != nil {
+			return xerrors.Errorf("default: %w", err)
+		}
+	}
+	for class, p := range c.Classes {
+		if err := p.Validate(); err != nil {
+			return xerrors.Errorf("classes.%s: %w", class, err)
+		}
+	}
+	if c.CheckInterval < 0 {
+		return xerrors.Errorf("checkInterval must not be negative")
+	}
+	return nil
+}
+
+// Policy is what happens with the core dumps of a class of workspaces
+type Policy struct {
+	// Mode is either capture or discard
+	Mode string `json:"mode"`
+	// MaxDumpSize is the size beyond which the kernel truncates a core dump. Required in capture mode.
+	MaxDumpSize quota.Size `json:"maxDumpSize,omitempty"`
+	// MaxTotalSize limits the size of all dumps of a workspace. We remove the oldest dumps beyond it.
+	// Defaults to MaxDumpSize times MaxDumps.
+	MaxTotalSize quota.Size `json:"maxTotalSize,omitempty"`
+	// MaxDumps is the number of dumps we keep. We remove the oldest dumps beyond it. Defaults to 5.
+	MaxDumps int `json:"maxDumps,omitempty"`
+}
+
+// Validate validates the policy
+func (p *Policy) Validate() error {
+	switch p.Mode {
+	case ModeCapture:
+		if p.MaxDumpSize <= 0 {
+			return xerrors.Errorf("maxDumpSize is required in capture mode")
+		}
+	case ModeDiscard:
+	default:
+		return xerrors.Errorf("mode must be %s or %s, not %q", ModeCapture, ModeDiscard, p.Mode)
+	}
+	if p.MaxTotalSize < 0 {
+		return xerrors.Errorf("maxTotalSize must not be negative")
+	}
+	if p.MaxDumps < 0 {
+		return xerrors.Errorf("maxDumps must not be negative")
+	}
+	return nil
+}
+
+// limits returns the number and total size of dumps we keep
+func (p *Policy) limits() (maxDumps int, maxTotalSize int64) {
+	maxDumps = p.MaxDumps
+	if maxDumps == 0 {
+		maxDumps = defaultMaxDumps
+	}
+	maxTotalSize = int64(p.MaxTotalSize)
+	if maxTotalSize == 0 {
+		maxTotalSize = int64(p.MaxDumpSize) * int64(maxDumps)
+	}
+	return
+}
+
+// PolicyFor returns the policy of a workspace class, or nil if we leave the workspace alone
+func (c *Config) PolicyFor(class string) *Policy {
+	if p, ok := c.Classes[class]; ok {
+		return &p
+	}
+	return c.Default
+}
+
+// Manager sets the node's core_pattern and applies the core dump policies to workspaces
+type Manager struct {
+	Config Config
+
+	procPath        string
+	originalPattern []byte
+	metrics         *metrics
+}
+
+// NewManager creates a new core dump manager. procPath is where ws-daemon sees the node's proc filesystem.
+func NewManager(cfg Config, procPath string) *Manager {
+	return &Manager{
+		Config:   cfg,
+		procPath: procPath,
+		metrics:  newMetrics(),
+	}
+}
+
+func (m *Manager) corePatternFile() string {
+	return filepath.Join(m.procPath, "sys", "kernel", "core_pattern")
+}
+
+// Start points the node's core_pattern to the workspace core dump location
+func (m *Manager) Start() error {
+	fn := m.corePatternFile()
+	original, err := os.ReadFile(fn)
+	if err != nil {
+		return xerrors.Errorf("cannot read core_pattern: %w", err)
+	}
+	err = os.WriteFile(fn, []byte(Pattern), 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot set core_pattern: %w", err)
+	}
+	if strings.TrimSpace(string(original)) != Pattern {
+		m.originalPattern = original
+	}
+	log.WithField("pattern", Pattern).Info("set core_pattern for workspaces")
+	return nil
+}
+
+// Close restores the core_pattern the node had before we started
+func (m *Manager) Close() error {
+	if m.originalPattern == nil {
+		return nil
+	}
+	err := os.WriteFile(m.corePatternFile(), m.originalPattern, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot restore core_pattern: %w", err)
+	}
+	return nil
+}
+
+// RegisterMetrics registers the core dump metrics
+func (m *Manager) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(m.metrics.dumps)
+}
+
+const (
+	outcomeCaptured = "captured"
+	outcomeEvicted  = "evicted"
+)
+
+type metrics struct {
+	dumps *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		dumps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "coredumps_total",
+			Help: "Core dumps of workspace processes by outcome: captured, or evicted to stay within the limits",
+		}, []string{"outcome"}),
+	}
+}
+
+type dumpFile struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// enforceLimits removes the oldest dumps in dir until the remaining ones are within the policy's limits.
+// It returns the dumps which remain.
+func enforceLimits(dir string, p *Policy) (remaining []dumpFile, evicted int, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	var dumps []dumpFile
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !strings.HasPrefix(e.Name(), "core.") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// the dump is gone already
+			continue
+		}
+		dumps = append(dumps, dumpFile{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	// newest first
+	sort.Slice(dumps, func(i, j int) bool { return dumps[i].ModTime.After(dumps[j].ModTime) })
+
+	maxDumps, maxTotalSize := p.limits()
+	var total int64
+	for i, d := range dumps {
+		total += d.Size
+		if i < maxDumps && total <= maxTotalSize {
+			remaining = append(remaining, d)
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, d.Name))
+		if err != nil && !os.IsNotExist(err) {
+			return remaining, evicted, xerrors.Errorf("cannot remove %s: %w", d.Name, err)
+		}
+		evicted++
+	}
+	return remaining, evicted, nil
+}
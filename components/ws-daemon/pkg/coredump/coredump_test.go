@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package coredump
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Policy Policy
+		Valid  bool
+	}{
+		{Name: "capture", Policy: Policy{Mode: ModeCapture, MaxDumpSize: 1024}, Valid: true},
+		{Name: "capture without size", Policy: Policy{Mode: ModeCapture}},
+		{Name: "discard", Policy: Policy{Mode: ModeDiscard}, Valid: true},
+		{Name: "unknown mode", Policy: Policy{Mode: "keep"}},
+		{Name: "no mode"},
+		{Name: "negative max dumps", Policy: Policy{Mode: ModeDiscard, MaxDumps: -1}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Policy.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	cfg := Config{
+		Enabled: true,
+		Default: &Policy{Mode: ModeDiscard},
+		Classes: map[string]Policy{
+			"regular": {Mode: ModeCapture, MaxDumpSize: 1024},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if p := cfg.PolicyFor("regular"); p == nil || p.Mode != ModeCapture {
+		t.Errorf("expected the class policy, got %v", p)
+	}
+	if p := cfg.PolicyFor("prebuild"); p == nil || p.Mode != ModeDiscard {
+		t.Errorf("expected the default policy, got %v", p)
+	}
+
+	cfg.Default = nil
+	if p := cfg.PolicyFor("prebuild"); p != nil {
+		t.Errorf("expected no policy without a default, got %v", p)
+	}
+}
+
+func TestEnforceLimits(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		Name      string
+		Dumps     map[string]int
+		Policy    Policy
+		Remaining []string
+	}{
+		{
+			Name:      "within limits",
+			Dumps:     map[string]int{"core.a.1.1": 10, "core.b.2.2": 10},
+			Policy:    Policy{Mode: ModeCapture, MaxDumpSize: 10},
+			Remaining: []string{"core.b.2.2", "core.a.1.1"},
+		},
+		{
+			Name:      "too many dumps",
+			Dumps:     map[string]int{"core.a.1.1": 10, "core.b.2.2": 10, "core.c.3.3": 10},
+			Policy:    Policy{Mode: ModeCapture, MaxDumpSize: 10, MaxDumps: 2},
+			Remaining: []string{"core.c.3.3", "core.b.2.2"},
+		},
+		{
+			Name:      "too large",
+			Dumps:     map[string]int{"core.a.1.1": 10, "core.b.2.2": 10, "core.c.3.3": 10},
+			Policy:    Policy{Mode: ModeCapture, MaxDumpSize: 10, MaxTotalSize: 25},
+			Remaining: []string{"core.c.3.3", "core.b.2.2"},
+		},
+		{
+			Name:      "ignores other files",
+			Dumps:     map[string]int{"core.a.1.1": 10, "notes.txt": 100},
+			Policy:    Policy{Mode: ModeCapture, MaxDumpSize: 10, MaxDumps: 1},
+			Remaining: []string{"core.a.1.1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			// the file names sort by age, oldest first
+			var i int
+			for _, name := range sortedKeys(test.Dumps) {
+				fn := filepath.Join(dir, name)
+				err := os.WriteFile(fn, make([]byte, test.Dumps[name]), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				mtime := now.Add(time.Duration(i) * time.Minute)
+				err = os.Chtimes(fn, mtime, mtime)
+				if err != nil {
+					t.Fatal(err)
+				}
+				i++
+			}
+
+			remaining, evicted, err := enforceLimits(dir, &test.Policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, d := range remaining {
+				names = append(names, d.Name)
+			}
+			if diff := cmp.Diff(test.Remaining, names); diff != "" {
+				t.Errorf("unexpected remaining dumps (-want +got):\n%s", diff)
+			}
+
+			var expectedEvictions int
+			for name := range test.Dumps {
+				if filepath.Ext(name) != ".txt" {
+					expectedEvictions++
+				}
+			}
+			expectedEvictions -= len(test.Remaining)
+			if evicted != expectedEvictions {
+				t.Errorf("expected %d evictions, got %d", expectedEvictions, evicted)
+			}
+			for _, name := range names {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("remaining dump %s is gone: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
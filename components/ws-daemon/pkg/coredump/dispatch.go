@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package coredump
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/arch"
+)
+
+// DispatchListener applies the core dump policy of a workspace once its container is running
+type DispatchListener struct {
+	Manager *Manager
+}
+
+// WorkspaceAdded limits the core dumps of a workspace, prepares the directory they go to and keeps them within limits
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	class := ws.Pod.Labels[wsk8s.TypeLabel]
+	policy := d.Manager.Config.PolicyFor(class)
+	if policy == nil {
+		return nil
+	}
+
+	pid, err := dispatch.WorkspacePID(ctx, ws)
+	if err != nil {
+		return err
+	}
+
+	log := log.WithFields(ws.OWI()).WithField("class", class).WithField("mode", policy.Mode)
+	err = d.Manager.apply(pid, policy)
+	if err != nil {
+		return err
+	}
+	log.Info("applied core dump policy")
+
+	if policy.Mode != ModeCapture {
+		return nil
+	}
+	dir := d.Manager.location(pid)
+	go d.Manager.keepWithinLimits(ctx, dir, policy, log)
+	return nil
+}
+
+// location is where we see the core dump location of the workspace whose container has pid
+func (m *Manager) location(pid int) string {
+	return filepath.Join(m.procPath, fmt.Sprint(pid), "root", Location)
+}
+
+// apply sets the core dump size limit of the workspace's root process, which all processes started later on inherit.
+// In capture mode it also creates the directory the kernel writes the dumps to, owned by the owner of /workspace.
+func (m *Manager) apply(pid int, p *Policy) error {
+	var limit uint64
+	if p.Mode == ModeCapture {
+		limit = uint64(p.MaxDumpSize)
+	}
+	// setting the hard limit too prevents workspaces from raising the limit
+	err := arch.Prlimit(pid, unix.RLIMIT_CORE, &unix.Rlimit{Cur: limit, Max: limit})
+	if err != nil {
+		return xerrors.Errorf("cannot limit core dump size: %w", err)
+	}
+	if p.Mode != ModeCapture {
+		return nil
+	}
+
+	root := filepath.Join(m.procPath, fmt.Sprint(pid), "root", "workspace")
+	stat, err := os.Stat(root)
+	if err != nil {
+		return xerrors.Errorf("cannot find workspace directory: %w", err)
+	}
+	dir := m.location(pid)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return xerrors.Errorf("cannot create core dump location: %w", err)
+	}
+	if st, ok := stat.Sys().(*syscall.Stat_t); ok {
+		for _, d := range []string{filepath.Dir(dir), dir} {
+			err = os.Chown(d, int(st.Uid), int(st.Gid))
+			if err != nil {
+				return xerrors.Errorf("cannot chown core dump location: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// keepWithinLimits enforces the limits of a policy on a workspace's dumps until ctx is done
+func (m *Manager) keepWithinLimits(ctx context.Context, dir string, p *Policy, log *logrus.Entry) {
+	interval := time.Duration(m.Config.CheckInterval)
+	if interval == 0 {
+		interval = defaultCheckInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	seen := make(map[string]struct{})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		remaining, evicted, err := enforceLimits(dir, p)
+		if os.IsNotExist(err) {
+			// the workspace is going away
+			return
+		}
+		if err != nil {
+			log.WithError(err).Warn("cannot enforce core dump limits")
+		}
+		if evicted > 0 {
+			m.metrics.dumps.WithLabelValues(outcomeEvicted).Add(float64(evicted))
+			log.WithField("evicted", evicted).Info("removed core dumps beyond the limits")
+		}
+		current := make(map[string]struct{}, len(remaining))
+		for _, d := range remaining {
+			current[d.Name] = struct{}{}
+			if _, ok := seen[d.Name]; ok {
+				continue
+			}
+			m.metrics.dumps.WithLabelValues(outcomeCaptured).Inc()
+			log.WithField("dump", d.Name).WithField("size", d.Size).Info("workspace process dumped core")
+		}
+		seen = current
+	}
+}
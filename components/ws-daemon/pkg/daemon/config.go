@@ -5,12 +5,21 @@
 package daemon
 
 import (
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cleanup"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/coldstart"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/content"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/coredump"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/diskguard"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/forensics"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/gpu"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/hosts"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/iws"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/memcompress"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/netcapture"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/numa"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/resources"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/swapfile"
 )
 
 // Config configures the workspace node daemon
@@ -27,9 +36,27 @@ type Config struct {
 		Path    string `json:"path"`
 	} `json:"readiness"`
 
-	Content        content.Config      `json:"content"`
-	Uidmapper      iws.UidmapperConfig `json:"uidmapper"`
-	Resources      resources.Config    `json:"resources"`
-	Hosts          hosts.Config        `json:"hosts"`
-	DiskSpaceGuard diskguard.Config    `json:"disk"`
+	Content          content.Config      `json:"content"`
+	Uidmapper        iws.UidmapperConfig `json:"uidmapper"`
+	Resources        resources.Config    `json:"resources"`
+	Hosts            hosts.Config        `json:"hosts"`
+	DiskSpaceGuard   diskguard.Config    `json:"disk"`
+	Cleanup          cleanup.Config      `json:"cleanup"`
+	GPU              gpu.Config          `json:"gpu"`
+	PacketCapture    netcapture.Config   `json:"packetCapture"`
+	Forensics        forensics.Config    `json:"forensics"`
+	CoreDumps        coredump.Config     `json:"coreDumps"`
+	CompressedMemory memcompress.Config  `json:"compressedMemory"`
+	SwapFiles        swapfile.Config     `json:"swapFiles"`
+	ColdStart        coldstart.Config    `json:"coldStart"`
+	Performance      numa.Config         `json:"performance"`
+}
+
+// procLocation is where ws-daemon sees the node's proc filesystem. All parts of the daemon
+// share the location configured for the uidmapper. Defaults to /proc.
+func (c Config) procLocation() string {
+	if c.Uidmapper.ProcLocation == "" {
+		return "/proc"
+	}
+	return c.Uidmapper.ProcLocation
 }
@@ -8,19 +8,32 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/cleanup"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/coldstart"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/content"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/coredump"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/diskguard"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/forensics"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/gpu"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/hosts"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/arch"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/iws"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/memcompress"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/netcapture"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/numa"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/resources"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/swapfile"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sys/unix"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -49,14 +62,108 @@ func NewDaemon(config Config, reg prometheus.Registerer) (*Daemon, error) {
 	if nodename == "" {
 		return nil, xerrors.Errorf("NODENAME env var isn't set")
 	}
-	dsptch, err := dispatch.NewDispatch(containerRuntime, clientset, config.Runtime.KubernetesNamespace, nodename,
+	listener := []dispatch.Listener{
 		resources.NewDispatchListener(&config.Resources, reg),
 		&Containerd4214Workaround{},
-	)
+	}
+	var gpus *gpu.Manager
+	if config.GPU.Enabled {
+		gpus, err = newGPUManager(config)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create GPU manager: %w", err)
+		}
+		err = gpus.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register GPU metrics: %w", err)
+		}
+		listener = append(listener, &gpu.DispatchListener{Manager: gpus})
+	}
+	var coreDumps *coredump.Manager
+	if config.CoreDumps.Enabled {
+		err = config.CoreDumps.Validate()
+		if err != nil {
+			return nil, xerrors.Errorf("invalid core dump configuration: %w", err)
+		}
+		coreDumps = coredump.NewManager(config.CoreDumps, config.procLocation())
+		err = coreDumps.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register core dump metrics: %w", err)
+		}
+		listener = append(listener, &coredump.DispatchListener{Manager: coreDumps})
+	}
+	var compressedMemory *memcompress.Manager
+	if config.CompressedMemory.Enabled {
+		err = config.CompressedMemory.Validate()
+		if err != nil {
+			return nil, xerrors.Errorf("invalid compressed memory configuration: %w", err)
+		}
+		compressedMemory = memcompress.NewManager(config.CompressedMemory, config.procLocation())
+		err = compressedMemory.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register compressed memory metrics: %w", err)
+		}
+		listener = append(listener, &memcompress.DispatchListener{Manager: compressedMemory})
+	}
+	var swapFiles *swapfile.Manager
+	if config.SwapFiles.Enabled {
+		if config.CompressedMemory.Enabled {
+			// both limit how much workspaces may swap
+			return nil, xerrors.Errorf("swap files and compressed memory cannot be enabled together")
+		}
+		err = config.SwapFiles.Validate()
+		if err != nil {
+			return nil, xerrors.Errorf("invalid swap file configuration: %w", err)
+		}
+		swapFiles = swapfile.NewManager(config.SwapFiles)
+		err = swapFiles.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register swap file metrics: %w", err)
+		}
+		listener = append(listener, &swapfile.DispatchListener{Manager: swapFiles})
+	}
+	var timings *coldstart.Recorder
+	if config.ColdStart.Enabled {
+		err = config.ColdStart.Validate()
+		if err != nil {
+			return nil, xerrors.Errorf("invalid cold start configuration: %w", err)
+		}
+		timings = coldstart.NewRecorder(config.ColdStart, config.procLocation())
+		err = timings.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register cold start metrics: %w", err)
+		}
+		listener = append(listener, &coldstart.DispatchListener{Recorder: timings})
+	}
+	var performance *numa.Manager
+	if config.Performance.Enabled {
+		err = config.Performance.Validate()
+		if err != nil {
+			return nil, xerrors.Errorf("invalid performance class configuration: %w", err)
+		}
+		performance = numa.NewManager(config.Performance)
+		err = performance.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register performance class metrics: %w", err)
+		}
+		listener = append(listener, &numa.DispatchListener{Manager: performance})
+	}
+	dsptch, err := dispatch.NewDispatch(containerRuntime, clientset, config.Runtime.KubernetesNamespace, nodename, listener...)
 	if err != nil {
 		return nil, err
 	}
+	if gpus != nil {
+		gpus.IsExpected = dsptch.WorkspaceExistsOnNode
+	}
 
+	if config.Content.Layers.Enabled && config.Content.Layers.NodeLabel != "" {
+		config.Content.Layers.Enabled, err = nodeHasLabel(clientset, nodename, config.Content.Layers.NodeLabel)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot check if content layers are enabled on this node: %w", err)
+		}
+		if !config.Content.Layers.Enabled {
+			log.WithField("label", config.Content.Layers.NodeLabel).Info("content layers are not enabled for this node")
+		}
+	}
 	contentService, err := content.NewWorkspaceService(
 		context.Background(),
 		config.Content,
@@ -64,6 +171,7 @@ func NewDaemon(config Config, reg prometheus.Registerer) (*Daemon, error) {
 		containerRuntime,
 		dsptch.WorkspaceExistsOnNode,
 		&iws.Uidmapper{Config: config.Uidmapper, Runtime: containerRuntime},
+		timings,
 		reg,
 	)
 	if err != nil {
@@ -77,16 +185,127 @@ func NewDaemon(config Config, reg prometheus.Registerer) (*Daemon, error) {
 		return nil, err
 	}
 
+	var leaks *cleanup.Reconciler
+	if config.Cleanup.Enabled {
+		leaks = newLeakReconciler(config, dsptch, contentService, performance)
+		err = leaks.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register cleanup metrics: %w", err)
+		}
+		contentService.ReleaseNodeResources = leaks.RemoveInstance
+	}
+
+	var capture *netcapture.Service
+	if config.PacketCapture.Enabled {
+		if config.PacketCapture.StorageOwner == "" {
+			return nil, xerrors.Errorf("packet capture requires a storage owner")
+		}
+		capture = netcapture.NewService(config.PacketCapture, containerRuntime, config.Content.Storage, config.procLocation())
+		err = capture.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register packet capture metrics: %w", err)
+		}
+	}
+
+	var snapshots *forensics.Service
+	if config.Forensics.Enabled {
+		if config.Forensics.StorageOwner == "" {
+			return nil, xerrors.Errorf("forensic snapshots require a storage owner")
+		}
+		if len(config.Forensics.AllowedClients) == 0 {
+			return nil, xerrors.Errorf("forensic snapshots require allowed clients")
+		}
+		if config.Forensics.KeyID == "" {
+			return nil, xerrors.Errorf("forensic snapshots require a key ID")
+		}
+		snapshots, err = forensics.NewService(config.Forensics, containerRuntime, contentService, config.Content.Storage)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create forensics service: %w", err)
+		}
+		err = snapshots.RegisterMetrics(reg)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot register forensics metrics: %w", err)
+		}
+	}
+
 	return &Daemon{
 		Config: config,
 
-		dispatch:   dsptch,
-		content:    contentService,
-		diskGuards: dsk,
-		hosts:      hsts,
+		dispatch:    dsptch,
+		content:     contentService,
+		diskGuards:  dsk,
+		hosts:       hsts,
+		leaks:       leaks,
+		gpus:        gpus,
+		capture:     capture,
+		snapshots:   snapshots,
+		coreDumps:   coreDumps,
+		memory:      compressedMemory,
+		swapFiles:   swapFiles,
+		timings:     timings,
+		performance: performance,
 	}, nil
 }
 
+// newGPUManager manages the GPUs of the node using nvidia-smi
+func newGPUManager(config Config) (*gpu.Manager, error) {
+	return gpu.NewManager(config.GPU, gpu.NewNvidiaSMI(config.GPU, config.procLocation()))
+}
+
+// newLeakReconciler reconciles the mounts in the working area (both in our and the node's mount namespace),
+// pod cgroups, named network namespaces, swap files and performance class reservations with the workspaces we know about
+func newLeakReconciler(config Config, dsptch *dispatch.Dispatch, contentService *content.WorkspaceService, performance *numa.Manager) *cleanup.Reconciler {
+	cfg := config.Cleanup
+	procPath := config.procLocation()
+
+	sources := []cleanup.Source{
+		&cleanup.MountSource{
+			MountsFile:  filepath.Join(procPath, "self", "mounts"),
+			WorkingArea: config.Content.WorkingArea,
+			Unmount: func(path string) error {
+				return unix.Unmount(path, unix.MNT_DETACH)
+			},
+		},
+		&cleanup.MountSource{
+			MountsFile:  filepath.Join(procPath, "1", "mounts"),
+			WorkingArea: config.Content.WorkingAreaNode,
+			Unmount:     iws.UnmountOnNode,
+		},
+	}
+	if cfg.CGroupBasePath != "" {
+		sources = append(sources, &cleanup.CGroupSource{BasePath: cfg.CGroupBasePath})
+	}
+	if cfg.NetNSPath != "" {
+		sources = append(sources, &cleanup.NetNSSource{
+			// we see the node's network namespaces through the root of its init process
+			Path:     filepath.Join(procPath, "1", "root", cfg.NetNSPath),
+			ProcPath: procPath,
+			Unmount: func(path string) error {
+				return iws.UnmountOnNode(filepath.Join(cfg.NetNSPath, filepath.Base(path)))
+			},
+		})
+	}
+	if config.SwapFiles.Enabled {
+		sources = append(sources, &cleanup.SwapFileSource{
+			Path:    config.SwapFiles.Location,
+			Suffix:  swapfile.Suffix,
+			SwapOff: swapfile.SwapOff,
+		})
+	}
+	if performance != nil {
+		sources = append(sources, &cleanup.ReservationSource{
+			Path:    config.Performance.StatePath,
+			Suffix:  numa.Suffix,
+			Release: performance.Release,
+		})
+	}
+
+	isExpected := func(instanceID string) bool {
+		return dsptch.WorkspaceExistsOnNode(instanceID) || contentService.WorkspaceExists(instanceID)
+	}
+	return cleanup.NewReconciler(cfg, isExpected, sources...)
+}
+
 func newClientSet(kubeconfig string) (res *kubernetes.Clientset, err error) {
 	defer func() {
 		if err != nil {
@@ -113,14 +332,50 @@ func newClientSet(kubeconfig string) (res *kubernetes.Clientset, err error) {
 type Daemon struct {
 	Config Config
 
-	dispatch   *dispatch.Dispatch
-	content    *content.WorkspaceService
-	diskGuards []*diskguard.Guard
-	hosts      hosts.Controller
+	dispatch    *dispatch.Dispatch
+	content     *content.WorkspaceService
+	diskGuards  []*diskguard.Guard
+	hosts       hosts.Controller
+	leaks       *cleanup.Reconciler
+	gpus        *gpu.Manager
+	capture     *netcapture.Service
+	snapshots   *forensics.Service
+	coreDumps   *coredump.Manager
+	memory      *memcompress.Manager
+	swapFiles   *swapfile.Manager
+	timings     *coldstart.Recorder
+	performance *numa.Manager
 }
 
 // Start runs all parts of the daemon until stop is called
 func (d *Daemon) Start() error {
+	if a := arch.Current(); a.Support != arch.SupportFull {
+		log.WithField("arch", a.GOARCH).WithField("support", a.Support.String()).Warn("ws-daemon is not fully supported on this architecture")
+	}
+
+	if d.coreDumps != nil {
+		// the pattern must be in place before the first workspace captures a dump
+		err := d.coreDumps.Start()
+		if err != nil {
+			return xerrors.Errorf("cannot start core dump handling: %w", err)
+		}
+	}
+
+	if d.memory != nil {
+		// workspaces find out which backends the node supports when they start
+		d.memory.Start()
+	}
+	if d.swapFiles != nil {
+		d.swapFiles.Start()
+	}
+	if d.performance != nil {
+		// workspaces which are running already must keep their CPUs when the dispatch adds them again
+		err := d.performance.Start()
+		if err != nil {
+			return xerrors.Errorf("cannot start performance classes: %w", err)
+		}
+	}
+
 	err := d.dispatch.Start()
 	if err != nil {
 		return xerrors.Errorf("cannot start dispatch: %w", err)
@@ -133,6 +388,15 @@ func (d *Daemon) Start() error {
 	if d.hosts != nil {
 		go d.hosts.Start()
 	}
+	if d.leaks != nil {
+		go d.leaks.Start()
+	}
+	if d.gpus != nil {
+		go d.gpus.Start()
+	}
+	if d.timings != nil {
+		go d.timings.Start()
+	}
 
 	if d.Config.ReadinessSignal.Enabled {
 		go d.startReadinessSignal()
@@ -144,6 +408,18 @@ func (d *Daemon) Start() error {
 // Register registers all gRPC services provided by this daemon
 func (d *Daemon) Register(srv *grpc.Server) {
 	api.RegisterWorkspaceContentServiceServer(srv, d.content)
+	if d.capture != nil {
+		api.RegisterDebugServiceServer(srv, d.capture)
+	}
+	if d.snapshots != nil {
+		api.RegisterForensicsServiceServer(srv, d.snapshots)
+	}
+	if d.timings != nil {
+		api.RegisterColdStartServiceServer(srv, d.timings)
+	}
+	if d.performance != nil {
+		api.RegisterPerformanceServiceServer(srv, d.performance)
+	}
 }
 
 func (d *Daemon) startReadinessSignal() {
@@ -172,11 +448,34 @@ func (d *Daemon) startReadinessSignal() {
 // cannot be started again.
 func (d *Daemon) Stop() error {
 	var errs []error
+	if d.swapFiles != nil {
+		// closing the dispatch stops all workspace listeners - workspaces must keep their swap files nonetheless
+		errs = append(errs, d.swapFiles.Close())
+	}
+	if d.performance != nil {
+		// workspaces keep their CPUs and huge pages, too
+		errs = append(errs, d.performance.Close())
+	}
 	errs = append(errs, d.dispatch.Close())
 	errs = append(errs, d.content.Close())
 	if d.hosts != nil {
 		errs = append(errs, d.hosts.Close())
 	}
+	if d.leaks != nil {
+		errs = append(errs, d.leaks.Close())
+	}
+	if d.gpus != nil {
+		errs = append(errs, d.gpus.Close())
+	}
+	if d.coreDumps != nil {
+		errs = append(errs, d.coreDumps.Close())
+	}
+	if d.memory != nil {
+		errs = append(errs, d.memory.Close())
+	}
+	if d.timings != nil {
+		errs = append(errs, d.timings.Close())
+	}
 
 	for _, err := range errs {
 		if err != nil {
@@ -186,3 +485,15 @@ func (d *Daemon) Stop() error {
 
 	return nil
 }
+
+// nodeHasLabel returns true if the node carries label with the value "true"
+func nodeHasLabel(clientset *kubernetes.Clientset, nodename, label string) (bool, error) {
+	if clientset == nil {
+		return false, xerrors.Errorf("no Kubernetes client available")
+	}
+	node, err := clientset.CoreV1().Nodes().Get(context.Background(), nodename, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return node.Labels[label] == "true", nil
+}
@@ -6,6 +6,7 @@ package dispatch
 
 import (
 	"context"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -49,6 +50,12 @@ type UpdateListener interface {
 	WorkspaceUpdated(ctx context.Context, ws *Workspace) error
 }
 
+// PodListener gets called whenever the pod of a workspace on this node is updated, even before its container exists.
+// PodUpdated is called synchronously and must not block.
+type PodListener interface {
+	PodUpdated(pod *corev1.Pod)
+}
+
 // NewDispatch starts a new workspace dispatch
 func NewDispatch(runtime container.Runtime, kubernetes kubernetes.Interface, k8sNamespace, nodename string, listener ...Listener) (*Dispatch, error) {
 	d := &Dispatch{
@@ -98,6 +105,34 @@ func GetFromContext(ctx context.Context) *Dispatch {
 	return ctx.Value(contextDispatch).(*Dispatch)
 }
 
+// WorkspacePID returns the PID of the namespace root process of the workspace's container.
+// Listeners use it from within WorkspaceAdded to find the workspace in the node's proc filesystem.
+func WorkspacePID(ctx context.Context, ws *Workspace) (int, error) {
+	disp := GetFromContext(ctx)
+	if disp == nil {
+		return 0, xerrors.Errorf("no dispatch available")
+	}
+	pid, err := disp.Runtime.ContainerPID(context.Background(), ws.ContainerID)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot find workspace container PID: %w", err)
+	}
+	return int(pid), nil
+}
+
+// WorkspaceCGroup returns the cgroup of the workspace's container below the cgroup base path.
+// Listeners use it from within WorkspaceAdded to control the workspace's resources.
+func WorkspaceCGroup(ctx context.Context, ws *Workspace, basePath string) (string, error) {
+	disp := GetFromContext(ctx)
+	if disp == nil {
+		return "", xerrors.Errorf("no dispatch available")
+	}
+	cgroupPath, err := disp.Runtime.ContainerCGroupPath(context.Background(), ws.ContainerID)
+	if err != nil {
+		return "", xerrors.Errorf("cannot find workspace cgroup: %w", err)
+	}
+	return filepath.Join(basePath, cgroupPath), nil
+}
+
 // Start starts the dispatch
 func (d *Dispatch) Start() error {
 	ifac := informers.NewSharedInformerFactoryWithOptions(d.Kubernetes, podInformerResyncInterval, informers.WithNamespace(d.KubernetesNamespace))
@@ -179,6 +214,12 @@ func (d *Dispatch) handlePodUpdate(oldPod, newPod *corev1.Pod) {
 		return
 	}
 
+	for _, l := range d.Listener {
+		if pl, ok := l.(PodListener); ok {
+			pl.PodUpdated(newPod)
+		}
+	}
+
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
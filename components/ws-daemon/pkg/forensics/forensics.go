@@ -0,0 +1,354 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package forensics captures read-only snapshots of workspace filesystems for abuse investigations.
+package forensics
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
+)
+
+const (
+	defaultMaxFiles        = 500000
+	defaultMaxHashFileSize = 64 * 1024 * 1024
+	defaultMaxHashBytes    = 1024 * 1024 * 1024
+
+	// resolveTimeout limits how long we look for the workspace container
+	resolveTimeout = 5 * time.Second
+	// uploadTimeout limits how long we try to upload a snapshot once it's captured
+	uploadTimeout = 2 * time.Minute
+
+	contentTypeSnapshot = "application/vnd.gitpod.forensic-snapshot"
+)
+
+// Config configures forensic filesystem snapshots
+type Config struct {
+	Enabled bool `json:"enabled"`
+
+	// StorageOwner is the owner whose remote storage bucket snapshots go to. Snapshots never go to the bucket
+	// of the workspace owner, who must not learn about the investigation.
+	StorageOwner string `json:"storageOwner"`
+	// AllowedClients are the common names of the TLS client certificates which may take snapshots. Unlike
+	// other admin RPCs, snapshots require this list: clients not on it are always denied.
+	AllowedClients []string `json:"allowedClients"`
+
+	// KeyFile contains the hex-encoded 256 bit AES key we encrypt snapshots with, e.g. created using `openssl rand -hex 32`.
+	KeyFile string `json:"keyFile"`
+	// KeyID identifies the key in KeyFile, so that investigators know which key decrypts a snapshot after a key rotation.
+	KeyID string `json:"keyID"`
+
+	// MaxFiles is the largest number of files a snapshot lists. Defaults to 500000.
+	MaxFiles int `json:"maxFiles,omitempty"`
+	// MaxHashFileSize is the largest file whose content we hash. Defaults to 64 MiB.
+	MaxHashFileSize int64 `json:"maxHashFileSize,omitempty"`
+	// MaxHashBytes is the total number of bytes we read to hash file content per snapshot. Defaults to 1 GiB.
+	MaxHashBytes int64 `json:"maxHashBytes,omitempty"`
+}
+
+// Root is a directory of a workspace a snapshot covers
+type Root struct {
+	// Name is how the snapshot refers to the root, e.g. workspace
+	Name string
+	// Path is where ws-daemon finds the root
+	Path string
+}
+
+// WorkspaceLocator finds the content directory of workspace instances
+type WorkspaceLocator interface {
+	WorkspaceLocation(instanceID string) (loc string, ok bool)
+}
+
+// Uploader uploads a snapshot of a workspace instance and returns its fully qualified name
+type Uploader interface {
+	Upload(ctx context.Context, instanceID, source, name string, annotations map[string]string) (url string, err error)
+}
+
+// Service captures forensic filesystem snapshots of workspaces on request
+type Service struct {
+	Config Config
+
+	// ResolveRoots returns the directories of a workspace instance a snapshot covers
+	ResolveRoots func(ctx context.Context, instanceID string) ([]Root, error)
+	Uploader     Uploader
+
+	key []byte
+
+	mu     sync.Mutex
+	active map[string]struct{}
+
+	metrics *metrics
+}
+
+// NewService creates a new snapshot service which covers the content and the container upperdir of workspaces,
+// and uploads snapshots to the remote storage
+func NewService(cfg Config, rt container.Runtime, workspaces WorkspaceLocator, storageCfg storage.Config) (*Service, error) {
+	key, err := LoadKey(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Config: cfg,
+		ResolveRoots: func(ctx context.Context, instanceID string) ([]Root, error) {
+			loc, ok := workspaces.WorkspaceLocation(instanceID)
+			if !ok {
+				return nil, xerrors.Errorf("workspace %s does not exist on this node", instanceID)
+			}
+			roots := []Root{{Name: "workspace", Path: loc}}
+
+			ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+			defer cancel()
+			id, err := rt.WaitForContainer(ctx, instanceID)
+			if err != nil {
+				log.WithFields(log.OWI("", "", instanceID)).WithError(err).Warn("cannot find workspace container - snapshot misses the changes to the root filesystem")
+				return roots, nil
+			}
+			upperdir, err := rt.ContainerUpperdir(ctx, id)
+			if err != nil {
+				log.WithFields(log.OWI("", "", instanceID)).WithError(err).Warn("cannot find workspace container upperdir - snapshot misses the changes to the root filesystem")
+				return roots, nil
+			}
+			return append(roots, Root{Name: "rootfs", Path: upperdir}), nil
+		},
+		Uploader: &storageUploader{Config: storageCfg, Owner: cfg.StorageOwner},
+		key:      key,
+		active:   make(map[string]struct{}),
+		metrics:  newMetrics(),
+	}, nil
+}
+
+// LoadKey reads the hex-encoded AES-256 key snapshots are encrypted with
+func LoadKey(fn string) ([]byte, error) {
+	if fn == "" {
+		return nil, xerrors.Errorf("forensic snapshots require a key file")
+	}
+	raw, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read key file: %w", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, xerrors.Errorf("key file must contain a hex-encoded key: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, xerrors.Errorf("key must be %d bits long, not %d", keySize*8, len(key)*8)
+	}
+	return key, nil
+}
+
+// RegisterMetrics registers the snapshot metrics with a registry
+func (s *Service) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(s.metrics.Snapshots)
+}
+
+// CaptureFilesystemSnapshot captures a forensic snapshot of a workspace's filesystem and uploads it
+func (s *Service) CaptureFilesystemSnapshot(ctx context.Context, req *api.CaptureFilesystemSnapshotRequest) (resp *api.CaptureFilesystemSnapshotResponse, err error) {
+	client, err := s.authorize(ctx)
+	if err != nil {
+		s.audit(req, client).WithError(err).Warn("filesystem snapshot denied")
+		s.metrics.Snapshots.WithLabelValues("denied").Inc()
+		return nil, err
+	}
+
+	err = validateRequest(req)
+	if err != nil {
+		s.audit(req, client).WithError(err).Warn("filesystem snapshot rejected")
+		s.metrics.Snapshots.WithLabelValues("invalid").Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.mu.Lock()
+	if _, exists := s.active[req.Id]; exists {
+		s.mu.Unlock()
+		return nil, status.Error(codes.AlreadyExists, "there is a filesystem snapshot of this workspace under way already")
+	}
+	s.active[req.Id] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, req.Id)
+		s.mu.Unlock()
+	}()
+
+	audit := s.audit(req, client).WithField("hashPatterns", req.HashPatterns)
+	audit.Info("filesystem snapshot started")
+	defer func() {
+		if err != nil {
+			audit.WithError(err).Warn("filesystem snapshot failed")
+			s.metrics.Snapshots.WithLabelValues("failed").Inc()
+			return
+		}
+		audit.WithField("url", resp.Url).WithField("files", resp.Files).WithField("hashedFiles", resp.HashedFiles).Info("filesystem snapshot finished")
+		s.metrics.Snapshots.WithLabelValues("success").Inc()
+	}()
+
+	roots, err := s.ResolveRoots(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	snapshot := &Snapshot{
+		InstanceID:   req.Id,
+		CapturedAt:   time.Now().UTC(),
+		Requester:    req.Requester,
+		Reason:       req.Reason,
+		Client:       client,
+		HashPatterns: req.HashPatterns,
+	}
+	err = capture(ctx, snapshot, roots, s.limits())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot capture filesystem snapshot: %q", err)
+	}
+
+	f, err := os.CreateTemp("", "forensic-snapshot-*")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create snapshot file: %q", err)
+	}
+	defer os.Remove(f.Name())
+	err = writeSnapshot(f, snapshot, s.Config.KeyID, s.key)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot write snapshot: %q", err)
+	}
+
+	uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+	name := fmt.Sprintf("forensics/%s.snapshot", snapshot.CapturedAt.Format("20060102-150405"))
+	url, err := s.Uploader.Upload(uploadCtx, req.Id, f.Name(), name, map[string]string{
+		"requester": req.Requester,
+		"reason":    req.Reason,
+		"client":    client,
+		"keyID":     s.Config.KeyID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot upload snapshot: %q", err)
+	}
+
+	files, hashed := snapshot.count()
+	return &api.CaptureFilesystemSnapshotResponse{
+		Url:         url,
+		KeyId:       s.Config.KeyID,
+		Files:       files,
+		HashedFiles: hashed,
+		Truncated:   snapshot.Truncated,
+	}, nil
+}
+
+// authorize returns the common name of the client's certificate, and fails if the client may not take snapshots
+func (s *Service) authorize(ctx context.Context) (client string, err error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(ti.State.VerifiedChains) > 0 && len(ti.State.VerifiedChains[0]) > 0 {
+			client = ti.State.VerifiedChains[0][0].Subject.CommonName
+		}
+	}
+	for _, c := range s.Config.AllowedClients {
+		if client != "" && c == client {
+			return client, nil
+		}
+	}
+	return client, status.Error(codes.PermissionDenied, "client must not take filesystem snapshots")
+}
+
+func validateRequest(req *api.CaptureFilesystemSnapshotRequest) error {
+	if req.Id == "" {
+		return xerrors.Errorf("id is required")
+	}
+	if req.Requester == "" || req.Reason == "" {
+		return xerrors.Errorf("requester and reason are required")
+	}
+	for _, p := range req.HashPatterns {
+		if _, err := matchHashPattern(p, "/"); err != nil {
+			return xerrors.Errorf("invalid hash pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) limits() limits {
+	res := limits{
+		MaxFiles:        s.Config.MaxFiles,
+		MaxHashFileSize: s.Config.MaxHashFileSize,
+		MaxHashBytes:    s.Config.MaxHashBytes,
+	}
+	if res.MaxFiles == 0 {
+		res.MaxFiles = defaultMaxFiles
+	}
+	if res.MaxHashFileSize == 0 {
+		res.MaxHashFileSize = defaultMaxHashFileSize
+	}
+	if res.MaxHashBytes == 0 {
+		res.MaxHashBytes = defaultMaxHashBytes
+	}
+	return res
+}
+
+// audit returns a log entry which identifies a snapshot and who asked for it. Snapshots are part of abuse
+// investigations, hence we log all of them irrespective of their outcome.
+func (s *Service) audit(req *api.CaptureFilesystemSnapshotRequest, client string) *logrus.Entry {
+	return log.WithFields(log.OWI("", "", req.Id)).WithFields(logrus.Fields{
+		"audit":     "filesystem-snapshot",
+		"requester": req.Requester,
+		"reason":    req.Reason,
+		"client":    client,
+	})
+}
+
+// storageUploader uploads snapshots to a bucket of the storage owner, where they're sorted by workspace instance
+type storageUploader struct {
+	Config storage.Config
+	Owner  string
+}
+
+func (u *storageUploader) Upload(ctx context.Context, instanceID, source, name string, annotations map[string]string) (url string, err error) {
+	rs, err := storage.NewDirectAccess(&u.Config)
+	if err != nil {
+		return "", err
+	}
+	err = rs.Init(ctx, u.Owner, instanceID)
+	if err != nil {
+		return "", err
+	}
+	err = rs.EnsureExists(ctx)
+	if err != nil {
+		return "", err
+	}
+	_, _, err = rs.Upload(ctx, source, name, storage.WithContentType(contentTypeSnapshot), storage.WithAnnotations(annotations))
+	if err != nil {
+		return "", err
+	}
+	return rs.Qualify(name), nil
+}
+
+type metrics struct {
+	Snapshots *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		Snapshots: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "filesystem_snapshots_total",
+			Help: "Number of forensic filesystem snapshot requests by outcome",
+		}, []string{"outcome"}),
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package forensics
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+const instanceID = "a6ad8fb2-37f3-4f4c-9e1b-9be0f6b9a5a1"
+
+var testKey = bytes.Repeat([]byte{0x42}, keySize)
+
+type fakeUploader struct {
+	Content     []byte
+	Annotations map[string]string
+}
+
+func (u *fakeUploader) Upload(ctx context.Context, instanceID, source, name string, annotations map[string]string) (string, error) {
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return "", err
+	}
+	u.Content = content
+	u.Annotations = annotations
+	return instanceID + "/" + name, nil
+}
+
+func newTestService(cfg Config, roots []Root, up *fakeUploader) *Service {
+	if cfg.AllowedClients == nil {
+		cfg.AllowedClients = []string{"admin-cli"}
+	}
+	cfg.KeyID = "test-key"
+	return &Service{
+		Config: cfg,
+		ResolveRoots: func(ctx context.Context, id string) ([]Root, error) {
+			return roots, nil
+		},
+		Uploader: up,
+		key:      testKey,
+		active:   make(map[string]struct{}),
+		metrics:  newMetrics(),
+	}
+}
+
+func withClient(ctx context.Context, commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+// newTestWorkspace creates a workspace whose content users could have tampered with
+func newTestWorkspace(t *testing.T) (root string, outside string) {
+	dir := t.TempDir()
+	root = filepath.Join(dir, "workspace")
+	outside = filepath.Join(dir, "secret")
+	for fn, content := range map[string]string{
+		"gitpod/.gitpod.yml":     "tasks: []",
+		"gitpod/miner.sh":        "#!/bin/sh\n./xmrig",
+		"gitpod/README.md":       "# Gitpod",
+		"gitpod/node_modules/.a": "",
+	} {
+		fn = filepath.Join(root, fn)
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(outside, []byte("node secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// a symlink that points out of the workspace, to a file whose name matches a hash pattern
+	if err := os.Symlink(outside, filepath.Join(root, "gitpod", "escape.sh")); err != nil {
+		t.Fatal(err)
+	}
+	return root, outside
+}
+
+func sha256sum(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestCaptureFilesystemSnapshot(t *testing.T) {
+	root, outside := newTestWorkspace(t)
+	var up fakeUploader
+	svc := newTestService(Config{}, []Root{{Name: "workspace", Path: root}, {Name: "rootfs", Path: filepath.Join(root, "does-not-exist")}}, &up)
+
+	resp, err := svc.CaptureFilesystemSnapshot(withClient(context.Background(), "admin-cli"), &api.CaptureFilesystemSnapshotRequest{
+		Id:           instanceID,
+		Requester:    "abuse@gitpod.io",
+		Reason:       "case-123",
+		HashPatterns: []string{"*.sh", "/gitpod/.gitpod.yml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&api.CaptureFilesystemSnapshotResponse{
+		Url:         instanceID + "/forensics/" + strings.TrimPrefix(resp.Url, instanceID+"/forensics/"),
+		KeyId:       "test-key",
+		Files:       9,
+		HashedFiles: 2,
+	}, resp); diff != "" {
+		t.Errorf("unexpected response (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"client": "admin-cli", "reason": "case-123", "requester": "abuse@gitpod.io", "keyID": "test-key"}, up.Annotations); diff != "" {
+		t.Errorf("unexpected annotations (-want +got):\n%s", diff)
+	}
+	if bytes.Contains(up.Content, []byte("miner.sh")) {
+		t.Error("snapshot is not encrypted")
+	}
+
+	snapshot, keyID, err := ReadSnapshot(bytes.NewReader(up.Content), testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "test-key" {
+		t.Errorf("unexpected key ID: %s", keyID)
+	}
+	if snapshot.Requester != "abuse@gitpod.io" || snapshot.Reason != "case-123" || snapshot.Client != "admin-cli" || snapshot.InstanceID != instanceID {
+		t.Errorf("snapshot does not record who took it and why: %+v", snapshot)
+	}
+
+	files := make(map[string]File)
+	for _, f := range snapshot.Roots[0].Files {
+		files[f.Path] = f
+	}
+	if f := files["/gitpod/miner.sh"]; f.SHA256 != sha256sum("#!/bin/sh\n./xmrig") || f.Size != 17 || f.Mode != "-rw-r--r--" {
+		t.Errorf("unexpected entry of miner.sh: %+v", f)
+	}
+	if f := files["/gitpod/.gitpod.yml"]; f.SHA256 != sha256sum("tasks: []") {
+		t.Errorf("unexpected entry of .gitpod.yml: %+v", f)
+	}
+	if f := files["/gitpod/README.md"]; f.SHA256 != "" || f.Size != 8 {
+		t.Errorf("unexpected entry of README.md: %+v", f)
+	}
+	if f := files["/gitpod/escape.sh"]; f.Link != outside || f.SHA256 != "" {
+		t.Errorf("snapshot must not follow symlinks: %+v", f)
+	}
+	if _, ok := files["/"]; !ok {
+		t.Error("snapshot misses the root")
+	}
+	if f := snapshot.Roots[1].Files; len(f) != 1 || f[0].Error == "" {
+		t.Errorf("expected an error for the missing root, got %+v", f)
+	}
+
+	_, keyID, err = ReadSnapshot(bytes.NewReader(up.Content), bytes.Repeat([]byte{0x23}, keySize))
+	if err == nil || keyID != "test-key" {
+		t.Errorf("expected decryption with the wrong key to fail and name the right key, got %q, %v", keyID, err)
+	}
+}
+
+func TestCaptureFilesystemSnapshotLimits(t *testing.T) {
+	root, _ := newTestWorkspace(t)
+
+	t.Run("max files", func(t *testing.T) {
+		var up fakeUploader
+		svc := newTestService(Config{MaxFiles: 3}, []Root{{Name: "workspace", Path: root}}, &up)
+		resp, err := svc.CaptureFilesystemSnapshot(withClient(context.Background(), "admin-cli"), &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io", Reason: "case-123"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Files != 3 || !resp.Truncated {
+			t.Errorf("expected a truncated snapshot of 3 files, got %+v", resp)
+		}
+	})
+
+	t.Run("max hash file size", func(t *testing.T) {
+		var up fakeUploader
+		svc := newTestService(Config{MaxHashFileSize: 10}, []Root{{Name: "workspace", Path: root}}, &up)
+		resp, err := svc.CaptureFilesystemSnapshot(withClient(context.Background(), "admin-cli"), &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io", Reason: "case-123", HashPatterns: []string{"*"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshot, _, err := ReadSnapshot(bytes.NewReader(up.Content), testKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range snapshot.Roots[0].Files {
+			if f.Path == "/gitpod/miner.sh" && (f.SHA256 != "" || f.HashError == "") {
+				t.Errorf("expected large file not to be hashed: %+v", f)
+			}
+		}
+		// .gitpod.yml (9 bytes), README.md (8 bytes) and .a (empty)
+		if resp.HashedFiles != 3 {
+			t.Errorf("expected 3 hashed files, got %d", resp.HashedFiles)
+		}
+	})
+}
+
+func TestCaptureFilesystemSnapshotAccess(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Config  Config
+		Client  string
+		Request *api.CaptureFilesystemSnapshotRequest
+		Code    codes.Code
+	}{
+		{Name: "client not allowed", Client: "ws-manager", Request: &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io", Reason: "case-123"}, Code: codes.PermissionDenied},
+		{Name: "no client certificate", Request: &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io", Reason: "case-123"}, Code: codes.PermissionDenied},
+		{Name: "no allowed clients", Config: Config{AllowedClients: []string{}}, Request: &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io", Reason: "case-123"}, Code: codes.PermissionDenied},
+		{Name: "missing reason", Client: "admin-cli", Request: &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io"}, Code: codes.InvalidArgument},
+		{Name: "invalid hash pattern", Client: "admin-cli", Request: &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io", Reason: "case-123", HashPatterns: []string{"[a-"}}, Code: codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var up fakeUploader
+			svc := newTestService(test.Config, nil, &up)
+			ctx := context.Background()
+			if test.Client != "" {
+				ctx = withClient(ctx, test.Client)
+			}
+			_, err := svc.CaptureFilesystemSnapshot(ctx, test.Request)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("expected %v, got %v", test.Code, err)
+			}
+			if up.Content != nil {
+				t.Error("uploaded a snapshot")
+			}
+		})
+	}
+
+	svc := newTestService(Config{}, nil, &fakeUploader{})
+	svc.active[instanceID] = struct{}{}
+	_, err := svc.CaptureFilesystemSnapshot(withClient(context.Background(), "admin-cli"), &api.CaptureFilesystemSnapshotRequest{Id: instanceID, Requester: "abuse@gitpod.io", Reason: "case-123"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists, got %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package forensics
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// snapshotMagic starts every snapshot file and versions its format
+	snapshotMagic = "GPFSNAP1"
+	keySize       = 32
+)
+
+var (
+	errTruncated   = errors.New("snapshot has too many files")
+	errFileChanged = errors.New("file changed during snapshot")
+)
+
+// Snapshot is the content of a forensic filesystem snapshot
+type Snapshot struct {
+	InstanceID   string         `json:"instanceId"`
+	CapturedAt   time.Time      `json:"capturedAt"`
+	Requester    string         `json:"requester"`
+	Reason       string         `json:"reason"`
+	Client       string         `json:"client"`
+	HashPatterns []string       `json:"hashPatterns,omitempty"`
+	Roots        []RootSnapshot `json:"roots"`
+	// Truncated is true if the snapshot misses files because the workspace has too many
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RootSnapshot lists the files of a snapshot root
+type RootSnapshot struct {
+	Name  string `json:"name"`
+	Files []File `json:"files"`
+}
+
+// File describes a file as we found it. Its content is never part of a snapshot.
+type File struct {
+	// Path is the path of the file relative to its root, starting with a slash
+	Path    string    `json:"path"`
+	Mode    string    `json:"mode,omitempty"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"modTime,omitempty"`
+	UID     uint32    `json:"uid,omitempty"`
+	GID     uint32    `json:"gid,omitempty"`
+	Inode   uint64    `json:"inode,omitempty"`
+	// Link is the target of symlinks
+	Link string `json:"link,omitempty"`
+	// SHA256 is the hash of the content of files that match a hash pattern
+	SHA256 string `json:"sha256,omitempty"`
+	// HashError explains why we did not hash a file that matches a hash pattern
+	HashError string `json:"hashError,omitempty"`
+	// Error explains why we could not describe the file or list the content of the directory
+	Error string `json:"error,omitempty"`
+}
+
+func (s *Snapshot) count() (files, hashed int64) {
+	for _, r := range s.Roots {
+		for _, f := range r.Files {
+			files++
+			if f.SHA256 != "" {
+				hashed++
+			}
+		}
+	}
+	return
+}
+
+type limits struct {
+	MaxFiles        int
+	MaxHashFileSize int64
+	MaxHashBytes    int64
+}
+
+// capture lists the files of all roots in the snapshot. It never writes to the roots, never follows symlinks,
+// and leaves the access time of the files it hashes alone.
+func capture(ctx context.Context, snapshot *Snapshot, roots []Root, lim limits) error {
+	var (
+		files  int
+		budget = lim.MaxHashBytes
+	)
+	for _, root := range roots {
+		res := RootSnapshot{Name: root.Name}
+		err := filepath.WalkDir(root.Path, func(path string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			rel, rerr := filepath.Rel(root.Path, path)
+			if rerr != nil {
+				return rerr
+			}
+			if rel == "." {
+				rel = ""
+			}
+			rel = "/" + filepath.ToSlash(rel)
+
+			if err != nil {
+				// we cannot list the content of this directory or stat the root, but carry on with the rest
+				res.Files = append(res.Files, File{Path: rel, Error: err.Error()})
+				return nil
+			}
+			if files >= lim.MaxFiles {
+				snapshot.Truncated = true
+				return errTruncated
+			}
+			files++
+
+			res.Files = append(res.Files, describe(path, rel, d, snapshot.HashPatterns, lim.MaxHashFileSize, &budget))
+			return nil
+		})
+		snapshot.Roots = append(snapshot.Roots, res)
+		if errors.Is(err, errTruncated) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describe produces the snapshot entry of a file and hashes its content if it matches a pattern and the budget allows
+func describe(path, rel string, d fs.DirEntry, patterns []string, maxSize int64, budget *int64) File {
+	res := File{Path: rel}
+	info, err := d.Info()
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Mode = info.Mode().String()
+	res.Size = info.Size()
+	res.ModTime = info.ModTime().UTC()
+
+	var dev, ino uint64
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		res.UID = st.Uid
+		res.GID = st.Gid
+		res.Inode = st.Ino
+		dev, ino = uint64(st.Dev), st.Ino
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		res.Link, err = os.Readlink(path)
+		if err != nil {
+			res.Error = err.Error()
+		}
+		return res
+	}
+	if !info.Mode().IsRegular() || !matchesAny(patterns, rel) {
+		return res
+	}
+
+	switch {
+	case info.Size() > maxSize:
+		res.HashError = "file is too large"
+	case info.Size() > *budget:
+		res.HashError = "snapshot hashed too much content already"
+	default:
+		sum, n, err := hashFile(path, dev, ino, maxSize)
+		*budget -= n
+		if err != nil {
+			res.HashError = err.Error()
+		} else {
+			res.SHA256 = sum
+		}
+	}
+	return res
+}
+
+// hashFile hashes the content of a regular file unless it's not the file we walked past. Users control the
+// workspace filesystem and could replace the file with a symlink or FIFO in the meantime.
+func hashFile(path string, dev, ino uint64, maxSize int64) (sum string, n int64, err error) {
+	const flags = syscall.O_RDONLY | syscall.O_NOFOLLOW | syscall.O_NONBLOCK | syscall.O_CLOEXEC
+	fd, err := syscall.Open(path, flags|syscall.O_NOATIME, 0)
+	if err == syscall.EPERM {
+		// O_NOATIME requires us to own the file or CAP_FOWNER
+		fd, err = syscall.Open(path, flags, 0)
+	}
+	if err != nil {
+		return "", 0, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	f := os.NewFile(uintptr(fd), path)
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || !info.Mode().IsRegular() || uint64(st.Dev) != dev || st.Ino != ino {
+		return "", 0, errFileChanged
+	}
+
+	h := sha256.New()
+	n, err = io.Copy(h, io.LimitReader(f, maxSize))
+	if err != nil {
+		return "", n, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func matchesAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if ok, _ := matchHashPattern(p, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHashPattern matches patterns with a slash against the path relative to the root, and all others against the base name
+func matchHashPattern(pattern, rel string) (bool, error) {
+	if strings.Contains(pattern, "/") {
+		return filepath.Match(pattern, rel)
+	}
+	return filepath.Match(pattern, filepath.Base(rel))
+}
+
+// writeSnapshot writes the snapshot as gzipped JSON encrypted with AES-256-GCM. The file starts with a
+// header that names the key, which is authenticated but not encrypted, followed by the nonce and the ciphertext.
+func writeSnapshot(out io.Writer, snapshot *Snapshot, keyID string, key []byte) error {
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	err := json.NewEncoder(zw).Encode(snapshot)
+	if err != nil {
+		return err
+	}
+	err = zw.Close()
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	header := snapshotHeader(keyID)
+	_, err = out.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(nonce)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(aead.Seal(nil, nonce, plain.Bytes(), header))
+	return err
+}
+
+// ReadSnapshot decrypts a snapshot. It returns the ID of the key the snapshot was encrypted with even if
+// the key does not match, so that investigators know which key to use.
+func ReadSnapshot(in io.Reader, key []byte) (snapshot *Snapshot, keyID string, err error) {
+	raw, err := io.ReadAll(in)
+	if err != nil {
+		return nil, "", err
+	}
+	if !bytes.HasPrefix(raw, []byte(snapshotMagic)) || len(raw) < len(snapshotMagic)+2 {
+		return nil, "", xerrors.Errorf("not a filesystem snapshot")
+	}
+	idLen := int(binary.BigEndian.Uint16(raw[len(snapshotMagic):]))
+	headerLen := len(snapshotMagic) + 2 + idLen
+	if len(raw) < headerLen {
+		return nil, "", xerrors.Errorf("snapshot is truncated")
+	}
+	header := raw[:headerLen]
+	keyID = string(header[len(snapshotMagic)+2:])
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, keyID, err
+	}
+	if len(raw) < headerLen+aead.NonceSize() {
+		return nil, keyID, xerrors.Errorf("snapshot is truncated")
+	}
+	nonce := raw[headerLen : headerLen+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, raw[headerLen+aead.NonceSize():], header)
+	if err != nil {
+		return nil, keyID, xerrors.Errorf("cannot decrypt snapshot - is it encrypted with key %s?: %w", keyID, err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, keyID, err
+	}
+	defer zr.Close()
+	var res Snapshot
+	err = json.NewDecoder(zr).Decode(&res)
+	if err != nil {
+		return nil, keyID, xerrors.Errorf("cannot unmarshal snapshot: %w", err)
+	}
+	return &res, keyID, nil
+}
+
+func snapshotHeader(keyID string) []byte {
+	header := make([]byte, len(snapshotMagic)+2, len(snapshotMagic)+2+len(keyID))
+	copy(header, snapshotMagic)
+	binary.BigEndian.PutUint16(header[len(snapshotMagic):], uint16(len(keyID)))
+	return append(header, keyID...)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, xerrors.Errorf("key must be %d bits long", keySize*8)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package gpu
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// DispatchListener assigns GPUs to workspaces which request them, and releases them once the workspace is gone
+type DispatchListener struct {
+	Manager *Manager
+}
+
+// WorkspaceAdded assigns GPUs to a workspace
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	req, err := ParseRequest(ws.Pod.Annotations[wsk8s.GPUAnnotation])
+	if err != nil {
+		return err
+	}
+	if req == nil {
+		return nil
+	}
+
+	log := log.WithFields(ws.OWI())
+	a, err := d.Manager.Acquire(ws.InstanceID, *req)
+	if err != nil {
+		return xerrors.Errorf("cannot assign GPUs: %w", err)
+	}
+	log.WithField("gpus", a.GPUs).WithField("mig", a.MIG).Info("assigned GPUs to workspace")
+
+	if d.Manager.Config.CGroupBasePath != "" {
+		cgroup, err := dispatch.WorkspaceCGroup(ctx, ws, filepath.Join(d.Manager.Config.CGroupBasePath, "devices"))
+		if err != nil {
+			return xerrors.Errorf("cannot allow GPU access: %w", err)
+		}
+		rules, err := d.Manager.Driver.DeviceRules(a)
+		if err != nil {
+			return xerrors.Errorf("cannot allow GPU access: %w", err)
+		}
+		err = allowDevices(cgroup, rules)
+		if err != nil {
+			return xerrors.Errorf("cannot allow GPU access: %w", err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		err := d.Manager.Release(ws.InstanceID)
+		if err != nil {
+			log.WithError(err).Warn("cannot release GPUs - will retry")
+			return
+		}
+		log.Info("released GPUs of workspace")
+	}()
+	return nil
+}
+
+// allowDevices adds rules to the devices cgroup of a container
+func allowDevices(cgroup string, rules []string) error {
+	fn := filepath.Join(cgroup, "devices.allow")
+	for _, r := range rules {
+		// the kernel parses one rule per write
+		err := os.WriteFile(fn, []byte(r), 0644)
+		if err != nil {
+			return xerrors.Errorf("cannot allow device %s: %w", r, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package gpu assigns the GPUs of a node to workspaces. Workspaces get either whole GPUs or MIG slices,
+// never share a device with another workspace, and leave the devices reset for the next one.
+package gpu
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultSamplingInterval = 15 * time.Second
+	defaultNvidiaSMI        = "nvidia-smi"
+)
+
+// Config configures the GPU device management
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// StatePath is the file we keep the device assignments in, so that they survive ws-daemon restarts
+	StatePath string `json:"statePath"`
+	// ExclusiveMode puts whole GPUs into EXCLUSIVE_PROCESS compute mode while they are assigned to a workspace
+	ExclusiveMode bool `json:"exclusiveMode,omitempty"`
+	// NvidiaSMI is the path to the nvidia-smi binary. Defaults to nvidia-smi in the PATH.
+	NvidiaSMI string `json:"nvidiaSMI,omitempty"`
+	// CGroupBasePath is where ws-daemon sees the node's cgroup filesystem. If set, we allow workspaces access
+	// to their devices using the devices cgroup. Workspace pods must have the device nodes available.
+	CGroupBasePath string `json:"cgroupBasePath,omitempty"`
+	// SamplingInterval is the time between two utilization samples. Defaults to 15 seconds.
+	SamplingInterval util.Duration `json:"samplingInterval,omitempty"`
+}
+
+// Request is what a workspace asks for: either a number of whole GPUs or a single MIG slice
+type Request struct {
+	GPUs       int
+	MIGProfile string
+}
+
+// ParseRequest parses the value of the GPU annotation, e.g. "1" or "mig:1g.5gb"
+func ParseRequest(s string) (*Request, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "mig:") {
+		profile := strings.TrimPrefix(s, "mig:")
+		if profile == "" {
+			return nil, xerrors.Errorf("invalid GPU request %q: missing MIG profile", s)
+		}
+		return &Request{MIGProfile: profile}, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return nil, xerrors.Errorf("invalid GPU request %q: must be a number of GPUs or mig:<profile>", s)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	return &Request{GPUs: n}, nil
+}
+
+// Device is a GPU on the node
+type Device struct {
+	Index int
+	UUID  string
+	Name  string
+	// MIGEnabled is true if the GPU is partitioned into MIG slices. Such GPUs are never assigned as a whole.
+	MIGEnabled bool
+}
+
+// MIGSlice is a GPU and compute instance on a MIG enabled GPU
+type MIGSlice struct {
+	GPU             int    `json:"gpu"`
+	Profile         string `json:"profile"`
+	GPUInstance     int    `json:"gpuInstance"`
+	ComputeInstance int    `json:"computeInstance"`
+}
+
+// Assignment are the devices a workspace uses
+type Assignment struct {
+	InstanceID string    `json:"instanceId"`
+	GPUs       []int     `json:"gpus,omitempty"`
+	MIG        *MIGSlice `json:"mig,omitempty"`
+	// Releasing is true once the workspace stopped, but we could not reset its devices yet.
+	// We do not hand out devices of such assignments until they are reset.
+	Releasing bool `json:"releasing,omitempty"`
+}
+
+// Utilization is a sample of the use of a GPU
+type Utilization struct {
+	// GPU is the utilization of the GPU in percent
+	GPU float64
+	// MemoryUsedBytes is the memory in use on the GPU
+	MemoryUsedBytes float64
+}
+
+// Driver manages the GPUs of a node
+type Driver interface {
+	// Devices lists the GPUs of the node
+	Devices() ([]Device, error)
+	// CreateMIGSlice creates a GPU and compute instance with the profile on a MIG enabled GPU
+	CreateMIGSlice(gpu int, profile string) (*MIGSlice, error)
+	// DestroyMIGSlice destroys a MIG slice
+	DestroyMIGSlice(slice MIGSlice) error
+	// SetExclusive switches a GPU between the EXCLUSIVE_PROCESS and DEFAULT compute mode
+	SetExclusive(gpu int, exclusive bool) error
+	// Reset resets a GPU. This fails while processes still use the GPU.
+	Reset(gpu int) error
+	// Utilization samples the use of all GPUs by index
+	Utilization() (map[int]Utilization, error)
+	// DeviceRules lists the devices cgroup rules which give access to the devices of an assignment
+	DeviceRules(a *Assignment) ([]string, error)
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package gpu
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+)
+
+const (
+	instanceA = "a6ad8fb2-37f3-4f4c-9e1b-9be0f6b9a5a1"
+	instanceB = "b3c0e7f1-54a2-4b7e-8f4d-0c1e2d3f4a5b"
+)
+
+type fakeDriver struct {
+	devices   []Device
+	migFull   map[int]bool
+	nextGI    int
+	exclusive map[int]bool
+	resets    []int
+	destroyed []MIGSlice
+	resetErr  error
+}
+
+func (d *fakeDriver) Devices() ([]Device, error) { return d.devices, nil }
+
+func (d *fakeDriver) CreateMIGSlice(gpu int, profile string) (*MIGSlice, error) {
+	if d.migFull[gpu] {
+		return nil, xerrors.Errorf("insufficient resources")
+	}
+	d.nextGI++
+	return &MIGSlice{GPU: gpu, Profile: profile, GPUInstance: d.nextGI}, nil
+}
+
+func (d *fakeDriver) DestroyMIGSlice(slice MIGSlice) error {
+	if d.resetErr != nil {
+		return d.resetErr
+	}
+	d.destroyed = append(d.destroyed, slice)
+	return nil
+}
+
+func (d *fakeDriver) SetExclusive(gpu int, exclusive bool) error {
+	if d.exclusive == nil {
+		d.exclusive = make(map[int]bool)
+	}
+	d.exclusive[gpu] = exclusive
+	return nil
+}
+
+func (d *fakeDriver) Reset(gpu int) error {
+	if d.resetErr != nil {
+		return d.resetErr
+	}
+	d.resets = append(d.resets, gpu)
+	return nil
+}
+
+func (d *fakeDriver) Utilization() (map[int]Utilization, error) { return nil, nil }
+
+func (d *fakeDriver) DeviceRules(a *Assignment) ([]string, error) { return nil, nil }
+
+func newTestManager(t *testing.T, driver *fakeDriver, exclusive bool) *Manager {
+	m, err := NewManager(Config{Enabled: true, ExclusiveMode: exclusive, StatePath: filepath.Join(t.TempDir(), "gpu.json")}, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected *Request
+		Error    bool
+	}{
+		{Input: ""},
+		{Input: "0"},
+		{Input: "2", Expected: &Request{GPUs: 2}},
+		{Input: "mig:1g.5gb", Expected: &Request{MIGProfile: "1g.5gb"}},
+		{Input: "mig:", Error: true},
+		{Input: "-1", Error: true},
+		{Input: "all", Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			act, err := ParseRequest(test.Input)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected request (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAcquireWholeGPUs(t *testing.T) {
+	driver := &fakeDriver{devices: []Device{{Index: 0}, {Index: 1, MIGEnabled: true}, {Index: 2}}}
+	m := newTestManager(t, driver, true)
+
+	a, err := m.Acquire(instanceA, Request{GPUs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{0}, a.GPUs); diff != "" {
+		t.Errorf("unexpected GPUs (-want +got):\n%s", diff)
+	}
+	if !driver.exclusive[0] {
+		t.Errorf("GPU 0 is not in exclusive mode")
+	}
+
+	again, err := m.Acquire(instanceA, Request{GPUs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(a, again); diff != "" {
+		t.Errorf("acquiring twice changed the assignment (-want +got):\n%s", diff)
+	}
+
+	// the MIG enabled GPU is never assigned as a whole
+	_, err = m.Acquire(instanceB, Request{GPUs: 2})
+	if !xerrors.Is(err, ErrNoCapacity) {
+		t.Fatalf("expected ErrNoCapacity, got %v", err)
+	}
+
+	err = m.Release(instanceA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if driver.exclusive[0] {
+		t.Errorf("GPU 0 is still in exclusive mode")
+	}
+	if diff := cmp.Diff([]int{0}, driver.resets); diff != "" {
+		t.Errorf("unexpected resets (-want +got):\n%s", diff)
+	}
+
+	b, err := m.Acquire(instanceB, Request{GPUs: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{0, 2}, b.GPUs); diff != "" {
+		t.Errorf("unexpected GPUs (-want +got):\n%s", diff)
+	}
+}
+
+func TestAcquireMIGSlice(t *testing.T) {
+	driver := &fakeDriver{
+		devices: []Device{{Index: 0}, {Index: 1, MIGEnabled: true}, {Index: 2, MIGEnabled: true}},
+		migFull: map[int]bool{1: true},
+	}
+	m := newTestManager(t, driver, false)
+
+	a, err := m.Acquire(instanceA, Request{MIGProfile: "1g.5gb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.MIG == nil || a.MIG.GPU != 2 {
+		t.Fatalf("expected a slice on GPU 2, got %+v", a.MIG)
+	}
+
+	err = m.Release(instanceA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]MIGSlice{*a.MIG}, driver.destroyed); diff != "" {
+		t.Errorf("unexpected destroyed slices (-want +got):\n%s", diff)
+	}
+
+	driver.migFull[2] = true
+	_, err = m.Acquire(instanceB, Request{MIGProfile: "1g.5gb"})
+	if !xerrors.Is(err, ErrNoCapacity) {
+		t.Fatalf("expected ErrNoCapacity, got %v", err)
+	}
+}
+
+func TestReleaseWithFailingReset(t *testing.T) {
+	driver := &fakeDriver{devices: []Device{{Index: 0}}}
+	m := newTestManager(t, driver, false)
+
+	_, err := m.Acquire(instanceA, Request{GPUs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	driver.resetErr = xerrors.Errorf("GPU is in use")
+	err = m.Release(instanceA)
+	if err == nil {
+		t.Fatal("expected release to fail")
+	}
+
+	// the GPU must not be handed out before it was reset
+	_, err = m.Acquire(instanceB, Request{GPUs: 1})
+	if !xerrors.Is(err, ErrNoCapacity) {
+		t.Fatalf("expected ErrNoCapacity, got %v", err)
+	}
+
+	driver.resetErr = nil
+	m.releaseStale()
+	if a := m.Assignment(instanceA); a != nil {
+		t.Fatalf("assignment was not released: %+v", a)
+	}
+	_, err = m.Acquire(instanceB, Request{GPUs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatePersistence(t *testing.T) {
+	var (
+		driver = &fakeDriver{devices: []Device{{Index: 0}, {Index: 1}}}
+		cfg    = Config{Enabled: true, StatePath: filepath.Join(t.TempDir(), "gpu.json")}
+	)
+	m, err := NewManager(cfg, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Acquire(instanceA, Request{GPUs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewManager(cfg, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.Assignment(instanceA), restarted.Assignment(instanceA)); diff != "" {
+		t.Errorf("assignment did not survive restart (-want +got):\n%s", diff)
+	}
+	b, err := restarted.Acquire(instanceB, Request{GPUs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{1}, b.GPUs); diff != "" {
+		t.Errorf("unexpected GPUs (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseNvidiaSMI(t *testing.T) {
+	devices, err := parseDevices([]byte("0, GPU-8a6f, NVIDIA A100-SXM4-40GB, Enabled\n1, GPU-9b7e, Tesla T4, [N/A]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedDevices := []Device{
+		{Index: 0, UUID: "GPU-8a6f", Name: "NVIDIA A100-SXM4-40GB", MIGEnabled: true},
+		{Index: 1, UUID: "GPU-9b7e", Name: "Tesla T4"},
+	}
+	if diff := cmp.Diff(expectedDevices, devices); diff != "" {
+		t.Errorf("unexpected devices (-want +got):\n%s", diff)
+	}
+
+	slice, err := parseCreatedMIGSlice([]byte(`Successfully created GPU instance ID  9 on GPU  0 using profile MIG 1g.5gb (ID 19)
+Successfully created compute instance ID  0 on GPU  0 GPU instance ID  9 using profile MIG 1g.5gb (ID  0)`), 0, "1g.5gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&MIGSlice{GPU: 0, Profile: "1g.5gb", GPUInstance: 9}, slice); diff != "" {
+		t.Errorf("unexpected slice (-want +got):\n%s", diff)
+	}
+
+	util, err := parseUtilization([]byte("0, [N/A], 1024\n1, 35, 2048\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedUtil := map[int]Utilization{
+		0: {MemoryUsedBytes: 1024 * 1024 * 1024},
+		1: {GPU: 35, MemoryUsedBytes: 2048 * 1024 * 1024},
+	}
+	if diff := cmp.Diff(expectedUtil, util); diff != "" {
+		t.Errorf("unexpected utilization (-want +got):\n%s", diff)
+	}
+
+	minors, err := migMinors([]byte("config 1\nmonitor 2\ngpu0/gi9/access 93\ngpu0/gi9/ci0/access 94\ngpu0/gi10/access 102\n"), MIGSlice{GPU: 0, GPUInstance: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]int{93, 94}, minors); diff != "" {
+		t.Errorf("unexpected minors (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,472 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package gpu
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// staleGracePeriod is how long an assignment must be without workspace before we release it. Right after a restart
+// we do not know all workspaces on the node yet, and must not reset devices which are still in use.
+const staleGracePeriod = 2 * time.Minute
+
+// ErrNoCapacity is returned if the node has not enough free devices for a request
+var ErrNoCapacity = xerrors.Errorf("not enough free GPUs on this node")
+
+// Manager assigns the GPUs of a node to workspaces. It is the single place on a node which hands out devices,
+// and keeps its assignments in a state file so that they survive restarts.
+type Manager struct {
+	Config Config
+	Driver Driver
+
+	// IsExpected returns true if a workspace instance still exists on the node. Assignments of other
+	// instances are released once they have been unexpected for the stale grace period.
+	IsExpected func(instanceID string) bool
+
+	mu          sync.Mutex
+	assignments map[string]*Assignment
+	staleSince  map[string]time.Time
+
+	stop chan struct{}
+
+	metrics struct {
+		assignedGPUs    prometheus.GaugeFunc
+		migSlices       prometheus.GaugeFunc
+		releasing       prometheus.GaugeFunc
+		utilization     *prometheus.GaugeVec
+		memoryUsed      *prometheus.GaugeVec
+		assignmentTotal *prometheus.CounterVec
+	}
+}
+
+// NewManager creates a new GPU manager and restores the assignments from the state file
+func NewManager(cfg Config, driver Driver) (*Manager, error) {
+	if cfg.StatePath == "" {
+		return nil, xerrors.Errorf("GPU state path is required")
+	}
+
+	m := &Manager{
+		Config:      cfg,
+		Driver:      driver,
+		assignments: make(map[string]*Assignment),
+		staleSince:  make(map[string]time.Time),
+		stop:        make(chan struct{}),
+	}
+	err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	m.metrics.assignedGPUs = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gpu_assigned_devices",
+		Help: "Whole GPUs currently assigned to workspaces",
+	}, func() float64 {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		var n int
+		for _, a := range m.assignments {
+			n += len(a.GPUs)
+		}
+		return float64(n)
+	})
+	m.metrics.migSlices = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gpu_assigned_mig_slices",
+		Help: "MIG slices currently assigned to workspaces",
+	}, func() float64 {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		var n int
+		for _, a := range m.assignments {
+			if a.MIG != nil {
+				n++
+			}
+		}
+		return float64(n)
+	})
+	m.metrics.releasing = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gpu_releasing_assignments",
+		Help: "Assignments of stopped workspaces whose devices we could not reset yet",
+	}, func() float64 {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		var n int
+		for _, a := range m.assignments {
+			if a.Releasing {
+				n++
+			}
+		}
+		return float64(n)
+	})
+	m.metrics.utilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gpu_workspace_utilization_percent",
+		Help: "GPU utilization of workspaces with whole GPUs",
+	}, []string{"instanceId", "gpu"})
+	m.metrics.memoryUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gpu_workspace_memory_used_bytes",
+		Help: "GPU memory used by workspaces with whole GPUs",
+	}, []string{"instanceId", "gpu"})
+	m.metrics.assignmentTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gpu_assignments_total",
+		Help: "GPU assignment attempts by kind and outcome",
+	}, []string{"kind", "outcome"})
+
+	return m, nil
+}
+
+// RegisterMetrics registers the GPU metrics
+func (m *Manager) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{
+		m.metrics.assignedGPUs,
+		m.metrics.migSlices,
+		m.metrics.releasing,
+		m.metrics.utilization,
+		m.metrics.memoryUsed,
+		m.metrics.assignmentTotal,
+	} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Acquire assigns devices to a workspace instance. Acquiring devices for an instance which has an assignment
+// already returns that assignment.
+func (m *Manager) Acquire(instanceID string, req Request) (res *Assignment, err error) {
+	kind := "gpu"
+	if req.MIGProfile != "" {
+		kind = "mig"
+	}
+	defer func() {
+		outcome := "success"
+		if xerrors.Is(err, ErrNoCapacity) {
+			outcome = "no-capacity"
+		} else if err != nil {
+			outcome = "error"
+		}
+		m.metrics.assignmentTotal.WithLabelValues(kind, outcome).Inc()
+	}()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if a, ok := m.assignments[instanceID]; ok {
+		if a.Releasing {
+			return nil, xerrors.Errorf("GPUs of %s are still being reset", instanceID)
+		}
+		return a, nil
+	}
+
+	devices, err := m.Driver.Devices()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list GPUs: %w", err)
+	}
+
+	a := &Assignment{InstanceID: instanceID}
+	if req.MIGProfile != "" {
+		a.MIG, err = m.createMIGSlice(devices, req.MIGProfile)
+	} else {
+		a.GPUs, err = m.selectGPUs(devices, req.GPUs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Config.ExclusiveMode {
+		for _, gpu := range a.GPUs {
+			err = m.Driver.SetExclusive(gpu, true)
+			if err != nil {
+				m.rollback(a)
+				return nil, xerrors.Errorf("cannot put GPU %d into exclusive mode: %w", gpu, err)
+			}
+		}
+	}
+
+	m.assignments[instanceID] = a
+	err = m.persistState()
+	if err != nil {
+		delete(m.assignments, instanceID)
+		m.rollback(a)
+		return nil, err
+	}
+	return a, nil
+}
+
+// selectGPUs picks free whole GPUs. Must be called with mu held.
+func (m *Manager) selectGPUs(devices []Device, n int) ([]int, error) {
+	used := m.usedGPUs()
+	var res []int
+	for _, d := range devices {
+		if d.MIGEnabled || used[d.Index] {
+			continue
+		}
+		res = append(res, d.Index)
+		if len(res) == n {
+			return res, nil
+		}
+	}
+	return nil, xerrors.Errorf("%w: requested %d, %d available", ErrNoCapacity, n, len(res))
+}
+
+// createMIGSlice creates a slice on the first MIG enabled GPU with room for it. Must be called with mu held.
+func (m *Manager) createMIGSlice(devices []Device, profile string) (*MIGSlice, error) {
+	var lastErr error
+	for _, d := range devices {
+		if !d.MIGEnabled {
+			continue
+		}
+		slice, err := m.Driver.CreateMIGSlice(d.Index, profile)
+		if err != nil {
+			// the GPU is likely full - nvidia-smi does not tell us upfront
+			lastErr = err
+			continue
+		}
+		return slice, nil
+	}
+	if lastErr != nil {
+		return nil, xerrors.Errorf("%w: cannot create MIG slice %s: %v", ErrNoCapacity, profile, lastErr)
+	}
+	return nil, xerrors.Errorf("%w: no MIG enabled GPU", ErrNoCapacity)
+}
+
+// usedGPUs returns the whole GPUs which are assigned or still need a reset. Must be called with mu held.
+func (m *Manager) usedGPUs() map[int]bool {
+	res := make(map[int]bool)
+	for _, a := range m.assignments {
+		for _, gpu := range a.GPUs {
+			res[gpu] = true
+		}
+	}
+	return res
+}
+
+// rollback undoes a partial assignment on a best-effort basis. Must be called with mu held.
+func (m *Manager) rollback(a *Assignment) {
+	err := m.reset(a)
+	if err != nil {
+		log.WithError(err).WithField("instanceId", a.InstanceID).Warn("cannot roll back GPU assignment")
+	}
+}
+
+// Release resets the devices of a workspace instance and makes them available to other workspaces.
+// If the devices cannot be reset, e.g. because processes still use them, we keep them from other
+// workspaces and retry on the next sampling interval.
+func (m *Manager) Release(instanceID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.assignments[instanceID]
+	if !ok {
+		return nil
+	}
+	a.Releasing = true
+
+	err := m.reset(a)
+	if err != nil {
+		perr := m.persistState()
+		if perr != nil {
+			log.WithError(perr).Warn("cannot persist GPU state")
+		}
+		return xerrors.Errorf("cannot reset GPUs of %s: %w", instanceID, err)
+	}
+
+	delete(m.assignments, instanceID)
+	delete(m.staleSince, instanceID)
+	for _, gpu := range a.GPUs {
+		m.metrics.utilization.DeleteLabelValues(instanceID, strconv.Itoa(gpu))
+		m.metrics.memoryUsed.DeleteLabelValues(instanceID, strconv.Itoa(gpu))
+	}
+	return m.persistState()
+}
+
+// reset returns the devices of an assignment to their pristine state. Must be called with mu held.
+func (m *Manager) reset(a *Assignment) error {
+	if a.MIG != nil {
+		err := m.Driver.DestroyMIGSlice(*a.MIG)
+		if err != nil {
+			return err
+		}
+	}
+	for _, gpu := range a.GPUs {
+		if m.Config.ExclusiveMode {
+			err := m.Driver.SetExclusive(gpu, false)
+			if err != nil {
+				return err
+			}
+		}
+		err := m.Driver.Reset(gpu)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Assignment returns the assignment of a workspace instance, or nil if it has none
+func (m *Manager) Assignment(instanceID string) *Assignment {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.assignments[instanceID]
+}
+
+// Start samples the utilization of assigned GPUs and releases the devices of workspaces which are gone until Close is called
+func (m *Manager) Start() {
+	interval := time.Duration(m.Config.SamplingInterval)
+	if interval == 0 {
+		interval = defaultSamplingInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.releaseStale()
+		m.sample()
+
+		select {
+		case <-ticker.C:
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Close stops the manager
+func (m *Manager) Close() error {
+	close(m.stop)
+	return nil
+}
+
+// releaseStale releases the assignments of workspaces which are gone, and retries the reset of those which are releasing
+func (m *Manager) releaseStale() {
+	m.mu.Lock()
+	var (
+		stale []string
+		now   = time.Now()
+	)
+	for id, a := range m.assignments {
+		if a.Releasing {
+			stale = append(stale, id)
+			continue
+		}
+		if m.IsExpected == nil || m.IsExpected(id) {
+			delete(m.staleSince, id)
+			continue
+		}
+		since, ok := m.staleSince[id]
+		if !ok {
+			m.staleSince[id] = now
+			continue
+		}
+		if now.Sub(since) >= staleGracePeriod {
+			stale = append(stale, id)
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Strings(stale)
+	for _, id := range stale {
+		err := m.Release(id)
+		if err != nil {
+			log.WithError(err).WithField("instanceId", id).Warn("cannot release GPUs")
+			continue
+		}
+		log.WithField("instanceId", id).Info("released GPUs of stopped workspace")
+	}
+}
+
+func (m *Manager) sample() {
+	m.mu.Lock()
+	owner := make(map[int]string)
+	for id, a := range m.assignments {
+		if a.Releasing {
+			continue
+		}
+		for _, gpu := range a.GPUs {
+			owner[gpu] = id
+		}
+	}
+	m.mu.Unlock()
+	if len(owner) == 0 {
+		return
+	}
+
+	util, err := m.Driver.Utilization()
+	if err != nil {
+		log.WithError(err).Warn("cannot sample GPU utilization")
+		return
+	}
+	for gpu, id := range owner {
+		u, ok := util[gpu]
+		if !ok {
+			continue
+		}
+		m.metrics.utilization.WithLabelValues(id, strconv.Itoa(gpu)).Set(u.GPU)
+		m.metrics.memoryUsed.WithLabelValues(id, strconv.Itoa(gpu)).Set(u.MemoryUsedBytes)
+	}
+}
+
+type state struct {
+	Assignments []*Assignment `json:"assignments"`
+}
+
+func (m *Manager) loadState() error {
+	fc, err := os.ReadFile(m.Config.StatePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot read GPU state: %w", err)
+	}
+
+	var s state
+	err = json.Unmarshal(fc, &s)
+	if err != nil {
+		return xerrors.Errorf("cannot parse GPU state: %w", err)
+	}
+	for _, a := range s.Assignments {
+		m.assignments[a.InstanceID] = a
+	}
+	return nil
+}
+
+// persistState writes the assignments to the state file. Must be called with mu held.
+func (m *Manager) persistState() error {
+	s := state{Assignments: make([]*Assignment, 0, len(m.assignments))}
+	for _, a := range m.assignments {
+		s.Assignments = append(s.Assignments, a)
+	}
+	sort.Slice(s.Assignments, func(i, j int) bool { return s.Assignments[i].InstanceID < s.Assignments[j].InstanceID })
+
+	fc, err := json.Marshal(s)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal GPU state: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(m.Config.StatePath), 0755)
+	if err != nil {
+		return xerrors.Errorf("cannot persist GPU state: %w", err)
+	}
+	tmp := m.Config.StatePath + ".tmp"
+	err = os.WriteFile(tmp, fc, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot persist GPU state: %w", err)
+	}
+	err = os.Rename(tmp, m.Config.StatePath)
+	if err != nil {
+		return xerrors.Errorf("cannot persist GPU state: %w", err)
+	}
+	return nil
+}
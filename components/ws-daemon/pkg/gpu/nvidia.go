@@ -0,0 +1,278 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package gpu
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// nvidiaMajor is the device major of /dev/nvidia* - the driver always registers it with this number
+	nvidiaMajor = 195
+	// nvidiaCtlMinor is the minor of /dev/nvidiactl
+	nvidiaCtlMinor = 255
+)
+
+// NvidiaSMI manages NVIDIA GPUs using nvidia-smi
+type NvidiaSMI struct {
+	// Path is the nvidia-smi binary
+	Path string
+	// ProcPath is where we see the node's proc filesystem
+	ProcPath string
+}
+
+// NewNvidiaSMI creates a driver which uses the nvidia-smi binary of the configuration.
+// procPath is where ws-daemon sees the node's proc filesystem.
+func NewNvidiaSMI(cfg Config, procPath string) *NvidiaSMI {
+	res := &NvidiaSMI{Path: cfg.NvidiaSMI, ProcPath: procPath}
+	if res.Path == "" {
+		res.Path = defaultNvidiaSMI
+	}
+	return res
+}
+
+func (n *NvidiaSMI) run(args ...string) ([]byte, error) {
+	out, err := exec.Command(n.Path, args...).CombinedOutput()
+	if err != nil {
+		return nil, xerrors.Errorf("nvidia-smi %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// Devices lists the GPUs of the node
+func (n *NvidiaSMI) Devices() ([]Device, error) {
+	out, err := n.run("--query-gpu=index,uuid,name,mig.mode.current", "--format=csv,noheader")
+	if err != nil {
+		return nil, err
+	}
+	return parseDevices(out)
+}
+
+func parseDevices(out []byte) ([]Device, error) {
+	var res []Device
+	for _, row := range parseCSV(out) {
+		if len(row) < 4 {
+			return nil, xerrors.Errorf("unexpected nvidia-smi output: %v", row)
+		}
+		idx, err := strconv.Atoi(row[0])
+		if err != nil {
+			return nil, xerrors.Errorf("unexpected GPU index %q", row[0])
+		}
+		res = append(res, Device{
+			Index:      idx,
+			UUID:       row[1],
+			Name:       row[2],
+			MIGEnabled: row[3] == "Enabled",
+		})
+	}
+	return res, nil
+}
+
+var (
+	createdGPUInstance     = regexp.MustCompile(`created GPU instance ID\s+(\d+)`)
+	createdComputeInstance = regexp.MustCompile(`created compute instance ID\s+(\d+)`)
+)
+
+// CreateMIGSlice creates a GPU and compute instance with the profile on a MIG enabled GPU
+func (n *NvidiaSMI) CreateMIGSlice(gpu int, profile string) (*MIGSlice, error) {
+	out, err := n.run("mig", "-i", strconv.Itoa(gpu), "-cgi", profile, "-C")
+	if err != nil {
+		return nil, err
+	}
+	return parseCreatedMIGSlice(out, gpu, profile)
+}
+
+func parseCreatedMIGSlice(out []byte, gpu int, profile string) (*MIGSlice, error) {
+	gi := createdGPUInstance.FindSubmatch(out)
+	ci := createdComputeInstance.FindSubmatch(out)
+	if gi == nil || ci == nil {
+		return nil, xerrors.Errorf("unexpected nvidia-smi output: %s", strings.TrimSpace(string(out)))
+	}
+	giID, _ := strconv.Atoi(string(gi[1]))
+	ciID, _ := strconv.Atoi(string(ci[1]))
+	return &MIGSlice{GPU: gpu, Profile: profile, GPUInstance: giID, ComputeInstance: ciID}, nil
+}
+
+// DestroyMIGSlice destroys a MIG slice
+func (n *NvidiaSMI) DestroyMIGSlice(slice MIGSlice) error {
+	var (
+		gpu = strconv.Itoa(slice.GPU)
+		gi  = strconv.Itoa(slice.GPUInstance)
+		ci  = strconv.Itoa(slice.ComputeInstance)
+	)
+	_, err := n.run("mig", "-i", gpu, "-gi", gi, "-ci", ci, "-dci")
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	_, err = n.run("mig", "-i", gpu, "-gi", gi, "-dgi")
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	return strings.Contains(err.Error(), "Not Found") || strings.Contains(err.Error(), "not found")
+}
+
+// SetExclusive switches a GPU between the EXCLUSIVE_PROCESS and DEFAULT compute mode
+func (n *NvidiaSMI) SetExclusive(gpu int, exclusive bool) error {
+	mode := "DEFAULT"
+	if exclusive {
+		mode = "EXCLUSIVE_PROCESS"
+	}
+	_, err := n.run("-i", strconv.Itoa(gpu), "-c", mode)
+	return err
+}
+
+// Reset resets a GPU
+func (n *NvidiaSMI) Reset(gpu int) error {
+	_, err := n.run("-i", strconv.Itoa(gpu), "-r")
+	return err
+}
+
+// Utilization samples the use of all GPUs by index
+func (n *NvidiaSMI) Utilization() (map[int]Utilization, error) {
+	out, err := n.run("--query-gpu=index,utilization.gpu,memory.used", "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, err
+	}
+	return parseUtilization(out)
+}
+
+func parseUtilization(out []byte) (map[int]Utilization, error) {
+	res := make(map[int]Utilization)
+	for _, row := range parseCSV(out) {
+		if len(row) < 3 {
+			return nil, xerrors.Errorf("unexpected nvidia-smi output: %v", row)
+		}
+		idx, err := strconv.Atoi(row[0])
+		if err != nil {
+			return nil, xerrors.Errorf("unexpected GPU index %q", row[0])
+		}
+		// MIG enabled GPUs report [N/A] - we leave them at zero
+		util, _ := strconv.ParseFloat(row[1], 64)
+		memMiB, _ := strconv.ParseFloat(row[2], 64)
+		res[idx] = Utilization{GPU: util, MemoryUsedBytes: memMiB * 1024 * 1024}
+	}
+	return res, nil
+}
+
+// DeviceRules lists the devices cgroup rules which give access to the devices of an assignment
+func (n *NvidiaSMI) DeviceRules(a *Assignment) ([]string, error) {
+	majors, err := readDeviceMajors(filepath.Join(n.ProcPath, "devices"))
+	if err != nil {
+		return nil, err
+	}
+
+	// every workspace with a GPU needs the control device and unified memory
+	rules := []string{fmt.Sprintf("c %d:%d rwm", nvidiaMajor, nvidiaCtlMinor)}
+	if uvm, ok := majors["nvidia-uvm"]; ok {
+		rules = append(rules, fmt.Sprintf("c %d:0 rwm", uvm), fmt.Sprintf("c %d:1 rwm", uvm))
+	}
+	for _, gpu := range a.GPUs {
+		rules = append(rules, fmt.Sprintf("c %d:%d rwm", nvidiaMajor, gpu))
+	}
+	if a.MIG != nil {
+		rules = append(rules, fmt.Sprintf("c %d:%d rwm", nvidiaMajor, a.MIG.GPU))
+
+		caps, ok := majors["nvidia-caps"]
+		if !ok {
+			return nil, xerrors.Errorf("nvidia-caps device is not registered - is MIG supported by the driver?")
+		}
+		fc, err := os.ReadFile(filepath.Join(n.ProcPath, "driver", "nvidia-caps", "mig-minors"))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read MIG minors: %w", err)
+		}
+		minors, err := migMinors(fc, *a.MIG)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range minors {
+			rules = append(rules, fmt.Sprintf("c %d:%d r", caps, m))
+		}
+	}
+	return rules, nil
+}
+
+// migMinors finds the device minors of the access capabilities of a MIG slice in /proc/driver/nvidia-caps/mig-minors,
+// which has lines like "gpu0/gi9/access 93" and "gpu0/gi9/ci0/access 94"
+func migMinors(fc []byte, slice MIGSlice) ([]int, error) {
+	var (
+		gi  = fmt.Sprintf("gpu%d/gi%d/access", slice.GPU, slice.GPUInstance)
+		ci  = fmt.Sprintf("gpu%d/gi%d/ci%d/access", slice.GPU, slice.GPUInstance, slice.ComputeInstance)
+		res []int
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(fc))
+	for scanner.Scan() {
+		segs := strings.Fields(scanner.Text())
+		if len(segs) != 2 || (segs[0] != gi && segs[0] != ci) {
+			continue
+		}
+		minor, err := strconv.Atoi(segs[1])
+		if err != nil {
+			return nil, xerrors.Errorf("invalid MIG minor %q", segs[1])
+		}
+		res = append(res, minor)
+	}
+	if len(res) != 2 {
+		return nil, xerrors.Errorf("cannot find the device minors of MIG slice %s", ci)
+	}
+	return res, nil
+}
+
+// readDeviceMajors reads the character device majors from /proc/devices
+func readDeviceMajors(fn string) (map[string]int, error) {
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read device majors: %w", err)
+	}
+
+	res := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(fc))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Block devices") {
+			break
+		}
+		segs := strings.Fields(line)
+		if len(segs) != 2 {
+			continue
+		}
+		major, err := strconv.Atoi(segs[0])
+		if err != nil {
+			continue
+		}
+		res[segs[1]] = major
+	}
+	return res, nil
+}
+
+func parseCSV(out []byte) [][]string {
+	var res [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		segs := strings.Split(line, ",")
+		for i := range segs {
+			segs[i] = strings.TrimSpace(segs[i])
+		}
+		res = append(res, segs)
+	}
+	return res
+}
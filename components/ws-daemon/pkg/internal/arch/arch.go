@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package arch contains the architecture-specific bits of ws-daemon's low-level code, i.e. the syscalls
+// x/sys doesn't wrap for us and anything that depends on the byte order of the machine.
+// Everything in here must build for every architecture Go supports on Linux. What differs between
+// architectures lives in files with the respective build constraints.
+package arch
+
+import (
+	"runtime"
+)
+
+// SupportLevel describes how well ws-daemon is supported on an architecture
+type SupportLevel int
+
+const (
+	// SupportGeneric means ws-daemon builds for the architecture, but we have never run it there
+	SupportGeneric SupportLevel = iota
+	// SupportExperimental means ws-daemon's tests pass on the architecture, but we do not run it in production there
+	SupportExperimental
+	// SupportFull means we run ws-daemon on the architecture in production
+	SupportFull
+)
+
+func (s SupportLevel) String() string {
+	switch s {
+	case SupportFull:
+		return "full"
+	case SupportExperimental:
+		return "experimental"
+	default:
+		return "generic"
+	}
+}
+
+// Info describes the architecture ws-daemon was built for
+type Info struct {
+	GOARCH    string
+	Support   SupportLevel
+	BigEndian bool
+}
+
+// Current returns the architecture ws-daemon was built for
+func Current() Info {
+	return Info{
+		GOARCH:    runtime.GOARCH,
+		Support:   support,
+		BigEndian: bigEndian,
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package arch
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestHtons(t *testing.T) {
+	for _, v := range []uint16{0, 1, unix.ETH_P_ALL, unix.ETH_P_IP, 0xff00} {
+		n := Htons(v)
+		// whatever the byte order of the machine, the bytes in memory must be in network order
+		mem := (*[2]byte)(unsafe.Pointer(&n))
+		if act := binary.BigEndian.Uint16(mem[:]); act != v {
+			t.Errorf("Htons(%#04x) is %#04x in network order", v, act)
+		}
+	}
+}
+
+func TestBigEndian(t *testing.T) {
+	v := uint16(1)
+	littleEndian := (*[2]byte)(unsafe.Pointer(&v))[0] == 1
+	if bigEndian == littleEndian {
+		t.Errorf("the build constraints get the byte order of %s wrong: bigEndian is %v", Current().GOARCH, bigEndian)
+	}
+}
+
+func TestPrlimit(t *testing.T) {
+	var lim unix.Rlimit
+	err := unix.Getrlimit(unix.RLIMIT_CORE, &lim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lim.Cur == 0 {
+		t.Skip("core dumps are disabled, we cannot lower the limit any further")
+	}
+
+	// we only lower the soft limit so that the remaining tests aren't affected by this one
+	defer func() {
+		err := unix.Setrlimit(unix.RLIMIT_CORE, &lim)
+		if err != nil {
+			t.Errorf("cannot restore the core dump limit: %v", err)
+		}
+	}()
+	err = Prlimit(os.Getpid(), unix.RLIMIT_CORE, &unix.Rlimit{Cur: 0, Max: lim.Max})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var act unix.Rlimit
+	err = unix.Getrlimit(unix.RLIMIT_CORE, &act)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act.Cur != 0 || act.Max != lim.Max {
+		t.Errorf("unexpected limit after prlimit: %+v", act)
+	}
+}
+
+func TestSupportLevel(t *testing.T) {
+	expectations := map[string]SupportLevel{
+		"amd64":   SupportFull,
+		"arm64":   SupportFull,
+		"s390x":   SupportExperimental,
+		"ppc64le": SupportExperimental,
+	}
+	a := Current()
+	exp, ok := expectations[a.GOARCH]
+	if !ok {
+		exp = SupportGeneric
+	}
+	if a.Support != exp {
+		t.Errorf("expected %s support on %s, got %s", exp, a.GOARCH, a.Support)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package arch
+
+import "encoding/binary"
+
+// Htons converts a short from host to network byte order
+func Htons(v uint16) uint16 {
+	if bigEndian {
+		return v
+	}
+	return v<<8 | v>>8
+}
+
+// ByteOrder returns the byte order of the node, e.g. of on-disk formats the kernel reads natively
+func ByteOrder() binary.ByteOrder {
+	if bigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
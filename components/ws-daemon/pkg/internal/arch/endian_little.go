@@ -0,0 +1,9 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// +build !armbe,!arm64be,!mips,!mips64,!mips64p32,!ppc,!ppc64,!s390,!s390x,!sparc,!sparc64
+
+package arch
+
+const bigEndian = false
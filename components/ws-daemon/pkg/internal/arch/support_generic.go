@@ -0,0 +1,9 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// +build !amd64,!arm64,!s390x,!ppc64le
+
+package arch
+
+const support = SupportGeneric
@@ -2,7 +2,7 @@
 // Licensed under the GNU Affero General Public License (AGPL).
 // See License-AGPL.txt in the project root for license information.
 
-package iws
+package arch
 
 import (
 	"unsafe"
@@ -10,7 +10,11 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-func syscallMoveMount(fromDirFD int, fromPath string, toDirFD int, toPath string, flags uintptr) error {
+// The mount API syscalls were added after the syscall tables of all architectures were unified,
+// hence they have the same number everywhere and x/sys knows them on all architectures.
+
+// MoveMount moves a mount, e.g. one produced by OpenTree, to toPath relative to toDirFD
+func MoveMount(fromDirFD int, fromPath string, toDirFD int, toPath string, flags uintptr) error {
 	fromPathP, err := unix.BytePtrFromString(fromPath)
 	if err != nil {
 		return err
@@ -30,10 +34,11 @@ func syscallMoveMount(fromDirFD int, fromPath string, toDirFD int, toPath string
 
 const (
 	// FlagMoveMountFEmptyPath: empty from path permitted: https://elixir.bootlin.com/linux/latest/source/include/uapi/linux/mount.h#L70
-	flagMoveMountFEmptyPath = 0x00000004
+	FlagMoveMountFEmptyPath = 0x00000004
 )
 
-func syscallOpenTree(dfd int, path string, flags uintptr) (fd uintptr, err error) {
+// OpenTree returns a file descriptor for the mount at path relative to dfd
+func OpenTree(dfd int, path string, flags uintptr) (fd uintptr, err error) {
 	p1, err := unix.BytePtrFromString(path)
 	if err != nil {
 		return 0, err
@@ -48,7 +53,17 @@ func syscallOpenTree(dfd int, path string, flags uintptr) (fd uintptr, err error
 
 const (
 	// FlagOpenTreeClone: https://elixir.bootlin.com/linux/latest/source/include/uapi/linux/mount.h#L62
-	flagOpenTreeClone = 1
+	FlagOpenTreeClone = 1
 	// FlagAtRecursive: Apply to the entire subtree: https://elixir.bootlin.com/linux/latest/source/include/uapi/linux/fcntl.h#L112
-	flagAtRecursive = 0x8000
+	FlagAtRecursive = 0x8000
 )
+
+// Prlimit sets a resource limit of another process. Our version of x/sys does not have unix.Prlimit yet.
+// prlimit64 takes a struct rlimit64 on all architectures, which unix.Rlimit matches on all of them.
+func Prlimit(pid int, resource int, limit *unix.Rlimit) error {
+	_, _, errno := unix.RawSyscall6(unix.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(limit)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -31,6 +31,10 @@ const (
 	// Expect this to be an instance of *safetynet.LiveBackup
 	AttrLiveBackup = "live-backup"
 
+	// AttrChangeJournal is the name of the change journal associated with a workspace.
+	// Expect this to be an instance of *content.workspaceChangeJournal
+	AttrChangeJournal = "change-journal"
+
 	// AttrWorkspaceServer is the name of the workspace server cancel func.
 	// Expect this to be an instance of context.CancelFunc
 	AttrWorkspaceServer = "workspace-server"
@@ -57,6 +61,9 @@ type Workspace struct {
 	// CheckoutLocation is the path relative to location where the main Git working copy of this
 	// workspace resides. If this workspace has no Git working copy, this field is an empty string.
 	CheckoutLocation string `json:"checkoutLocation"`
+	// Repository is the remote URI of the main Git working copy of this workspace. If this workspace
+	// has no Git working copy, this field is an empty string.
+	Repository string `json:"repository,omitempty"`
 
 	// UpperdirLocation is the absolute ws-daemon container-relative path to the workspace container's upperdir.
 	UpperdirLocation string `json:"upperdir"`
@@ -74,12 +81,18 @@ type Workspace struct {
 	ServiceLocDaemon string `json:"serviceLocDaemon"`
 	UserNamespaced   bool   `json:"userNamespaced"`
 
+	// TeardownDone lists the teardown steps which completed already, so that a disposal which failed part-way
+	// continues where it stopped
+	TeardownDone []string `json:"teardownDone,omitempty"`
+
 	NonPersistentAttrs map[string]interface{} `json:"-"`
 
 	store              *Store
 	state              WorkspaceState
 	stateLock          sync.RWMutex
 	operatingCondition *sync.Cond
+	// disposing is true while someone disposes the workspace
+	disposing bool
 }
 
 // OWI produces the owner, workspace, instance log metadata from the information
@@ -159,7 +172,8 @@ func (s *Workspace) MarkInitDone(ctx context.Context) (err error) {
 	return nil
 }
 
-// WaitOrMarkForDisposal marks the workspace as disposing, or if it's already in that state waits until it's actually disposed
+// WaitOrMarkForDisposal marks the workspace as disposing, or if it's already in that state waits until it's actually disposed.
+// If an earlier disposal failed, or ws-daemon restarted while disposing the workspace, the caller takes over the disposal.
 func (s *Workspace) WaitOrMarkForDisposal(ctx context.Context) (done bool, repo *csapi.GitStatus, err error) {
 	//nolint:ineffassign,staticcheck
 	span, ctx := opentracing.StartSpanFromContext(ctx, "workspace.WaitOrMarkForDisposal")
@@ -169,8 +183,13 @@ func (s *Workspace) WaitOrMarkForDisposal(ctx context.Context) (done bool, repo
 	if s.state == WorkspaceDisposed {
 		s.stateLock.Unlock()
 		return true, nil, nil
+	} else if s.state == WorkspaceDisposing && !s.disposing {
+		s.disposing = true
+		s.stateLock.Unlock()
+		return false, nil, nil
 	} else if s.state != WorkspaceDisposing {
 		s.state = WorkspaceDisposing
+		s.disposing = true
 		s.stateLock.Unlock()
 
 		err = s.persist()
@@ -189,10 +208,51 @@ func (s *Workspace) WaitOrMarkForDisposal(ctx context.Context) (done bool, repo
 
 	s.operatingCondition.L.Lock()
 	s.operatingCondition.Wait()
-	done = true
-	repo = s.LastGitStatus
 	s.operatingCondition.L.Unlock()
-	return
+
+	s.stateLock.RLock()
+	disposed := s.state == WorkspaceDisposed
+	repo = s.LastGitStatus
+	s.stateLock.RUnlock()
+	if !disposed {
+		return false, nil, xerrors.Errorf("workspace disposal failed")
+	}
+	return true, repo, nil
+}
+
+// AbortDisposal gives up on disposing the workspace after a failure. Those waiting for the disposal fail,
+// and the next call to WaitOrMarkForDisposal takes over.
+func (s *Workspace) AbortDisposal() {
+	s.stateLock.Lock()
+	s.disposing = false
+	s.operatingCondition.Broadcast()
+	s.stateLock.Unlock()
+}
+
+// TeardownStepDone returns true if the teardown step completed in an earlier attempt to dispose the workspace
+func (s *Workspace) TeardownStepDone(name string) bool {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+
+	for _, n := range s.TeardownDone {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkTeardownStepDone records a completed teardown step. Once the workspace is disposed we no longer persist it.
+func (s *Workspace) MarkTeardownStepDone(name string) error {
+	s.stateLock.Lock()
+	s.TeardownDone = append(s.TeardownDone, name)
+	disposed := s.state == WorkspaceDisposed
+	s.stateLock.Unlock()
+
+	if disposed {
+		return nil
+	}
+	return s.persist()
 }
 
 // Dispose marks the workspace as disposed and clears it from disk
@@ -204,20 +264,11 @@ func (s *Workspace) Dispose(ctx context.Context) (err error) {
 	// we remove the workspace file first, so that should something go wrong while deleting the
 	// old workspace content we can garbage collect that content later.
 	err = os.Remove(s.persistentStateLocation())
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return xerrors.Errorf("cannot remove workspace: %w", err)
 	}
 
-	s.stateLock.Lock()
-	s.state = WorkspaceDisposed
-	s.operatingCondition.Broadcast()
-	s.stateLock.Unlock()
-
-	err = s.store.runLifecycleHooks(ctx, s)
-	if err != nil {
-		return err
-	}
-
+	// we only mark the workspace disposed once its content is gone, so that a failed removal can be retried
 	if !s.FullWorkspaceBackup {
 		err = os.RemoveAll(s.Location)
 	}
@@ -225,7 +276,13 @@ func (s *Workspace) Dispose(ctx context.Context) (err error) {
 		return xerrors.Errorf("cannot remove workspace: %w", err)
 	}
 
-	return nil
+	s.stateLock.Lock()
+	s.state = WorkspaceDisposed
+	s.disposing = false
+	s.operatingCondition.Broadcast()
+	s.stateLock.Unlock()
+
+	return s.store.runLifecycleHooks(ctx, s)
 }
 
 // IsReady returns true if the workspace is in the ready state
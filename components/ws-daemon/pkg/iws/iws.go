@@ -27,7 +27,9 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/coldstart"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/arch"
 	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
 )
 
@@ -71,7 +73,7 @@ var (
 )
 
 // ServeWorkspace establishes the IWS server for a workspace
-func ServeWorkspace(uidmapper *Uidmapper) func(ctx context.Context, ws *session.Workspace) error {
+func ServeWorkspace(uidmapper *Uidmapper, timings *coldstart.Recorder) func(ctx context.Context, ws *session.Workspace) error {
 	return func(ctx context.Context, ws *session.Workspace) (err error) {
 		if !ws.FullWorkspaceBackup && !ws.UserNamespaced {
 			return nil
@@ -84,6 +86,7 @@ func ServeWorkspace(uidmapper *Uidmapper) func(ctx context.Context, ws *session.
 		helper := &InWorkspaceServiceServer{
 			Uidmapper: uidmapper,
 			Session:   ws,
+			Timings:   timings,
 		}
 		err = helper.Start()
 		if err != nil {
@@ -118,6 +121,8 @@ func StopServingWorkspace(ctx context.Context, ws *session.Workspace) error {
 type InWorkspaceServiceServer struct {
 	Uidmapper *Uidmapper
 	Session   *session.Workspace
+	// Timings records how long the phases of the workspace start take
+	Timings *coldstart.Recorder
 
 	srv  *grpc.Server
 	sckt io.Closer
@@ -180,6 +185,7 @@ func (wbs *InWorkspaceServiceServer) PrepareForUserNS(ctx context.Context, req *
 	if !wbs.Session.UserNamespaced {
 		return nil, status.Error(codes.FailedPrecondition, "not supported for this workspace")
 	}
+	defer wbs.Timings.Measure(wbs.Session.InstanceID, coldstart.PhaseMount)()
 
 	rt := wbs.Uidmapper.Runtime
 	if rt == nil {
@@ -229,6 +235,7 @@ func (wbs *InWorkspaceServiceServer) MountProc(ctx context.Context, req *api.Mou
 	if !wbs.Session.UserNamespaced {
 		return nil, status.Error(codes.FailedPrecondition, "not supported for this workspace")
 	}
+	defer wbs.Timings.Measure(wbs.Session.InstanceID, coldstart.PhaseMount)()
 
 	var (
 		reqPID  = req.Pid
@@ -453,7 +460,7 @@ func (wbs *InWorkspaceServiceServer) UmountProc(ctx context.Context, req *api.Um
 }
 
 func moveMount(targetPid int, source, target string) error {
-	mntfd, err := syscallOpenTree(unix.AT_FDCWD, source, flagOpenTreeClone|flagAtRecursive)
+	mntfd, err := arch.OpenTree(unix.AT_FDCWD, source, arch.FlagOpenTreeClone|arch.FlagAtRecursive)
 	if err != nil {
 		return xerrors.Errorf("cannot open tree: %w", err)
 	}
@@ -577,6 +584,7 @@ func (wbs *InWorkspaceServiceServer) WriteIDMapping(ctx context.Context, req *ap
 	if !wbs.Session.UserNamespaced {
 		return nil, status.Error(codes.FailedPrecondition, "not supported for this workspace")
 	}
+	defer wbs.Timings.Measure(wbs.Session.InstanceID, coldstart.PhaseUIDShift)()
 
 	cid, err := wbs.Uidmapper.Runtime.WaitForContainer(ctx, wbs.Session.InstanceID)
 	if err != nil {
@@ -649,6 +657,13 @@ func (wbs *InWorkspaceServiceServer) unPrepareForUserNS() error {
 	return nil
 }
 
+// UnmountOnNode unmounts a path in the node's mount namespace
+func UnmountOnNode(target string) error {
+	return nsinsider("", 1, func(c *exec.Cmd) {
+		c.Args = append(c.Args, "unmount", "--target", target)
+	})
+}
+
 type ratelimitingInterceptor map[string]ratelimit
 
 type ratelimit struct {
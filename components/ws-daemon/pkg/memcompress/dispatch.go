@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package memcompress
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// DispatchListener applies the compressed memory policy of a workspace once its container is running
+type DispatchListener struct {
+	Manager *Manager
+}
+
+// WorkspaceAdded allows the workspace to compress its memory and samples the effect until the workspace is gone.
+// If the node cannot give the workspace compressed memory, the workspace runs without.
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	class := ws.Pod.Labels[wsk8s.TypeLabel]
+	policy := d.Manager.Config.PolicyFor(class)
+	if policy == nil {
+		return nil
+	}
+
+	cgroup, err := dispatch.WorkspaceCGroup(ctx, ws, d.Manager.Config.CGroupBasePath)
+	if err != nil {
+		return err
+	}
+
+	log := log.WithFields(ws.OWI()).WithField("class", class).WithField("backend", policy.Backend)
+	err = d.Manager.apply(cgroup, policy)
+	if _, unsupported := err.(*errUnsupported); unsupported {
+		d.Manager.metrics.workspaces.WithLabelValues(policy.Backend, outcomeUnsupported).Inc()
+		log.WithError(err).Warn("workspace runs without compressed memory")
+		return nil
+	}
+	if err != nil {
+		d.Manager.metrics.workspaces.WithLabelValues(policy.Backend, outcomeFailed).Inc()
+		return xerrors.Errorf("cannot apply compressed memory policy: %w", err)
+	}
+	d.Manager.metrics.workspaces.WithLabelValues(policy.Backend, outcomeApplied).Inc()
+	log.WithField("maxSize", int64(policy.MaxSize)).Info("applied compressed memory policy")
+
+	go d.Manager.sample(ctx, cgroup, policy, log)
+	return nil
+}
+
+// sample samples compression ratio, swap-ins and memory stalls of a workspace until ctx is done
+func (m *Manager) sample(ctx context.Context, cgroup string, p *Policy, log *logrus.Entry) {
+	t := time.NewTicker(m.sampleInterval())
+	defer t.Stop()
+
+	var (
+		swapinKey = "pswpin"
+		swapins   uint64
+		stall     time.Duration
+	)
+	if p.Backend == BackendZswap {
+		swapinKey = "zswpin"
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		stat, err := readMemoryStat(cgroup)
+		if os.IsNotExist(err) {
+			// the workspace is going away
+			return
+		}
+		if err != nil {
+			log.WithError(err).Debug("cannot sample compressed memory")
+			continue
+		}
+		if p.Backend == BackendZswap && stat["zswap"] > 0 {
+			m.metrics.ratio.WithLabelValues(p.Backend).Observe(float64(stat["zswapped"]) / float64(stat["zswap"]))
+		}
+		if v, ok := stat[swapinKey]; ok {
+			if v > swapins {
+				m.metrics.swapins.WithLabelValues(p.Backend).Add(float64(v - swapins))
+			}
+			swapins = v
+		}
+
+		s, err := readMemoryStall(cgroup)
+		if err != nil {
+			// not all kernels account memory pressure
+			continue
+		}
+		if s > stall {
+			m.metrics.stall.WithLabelValues(p.Backend).Add((s - stall).Seconds())
+		}
+		stall = s
+	}
+}
@@ -0,0 +1,435 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package memcompress extends the memory of workspaces with compressed memory. Instead of OOM-killing a workspace
+// which hits its memory limit, the kernel compresses the workspace's cold pages, either in the zswap pool or on a
+// zram swap device.
+//
+// Both backends are configured on the node - ws-daemon only decides how much of its memory each workspace may
+// compress, using the cgroup v2 memory controller. If the node or its kernel does not support a backend, workspaces
+// run without compressed memory as before.
+package memcompress
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+const (
+	// BackendZswap compresses pages in the node's zswap pool in front of its swap device
+	BackendZswap = "zswap"
+	// BackendZram swaps pages to a compressed zram device, which may write incompressible or idle pages back to disk
+	BackendZram = "zram"
+
+	defaultSysPath        = "/sys"
+	defaultSampleInterval = 30 * time.Second
+)
+
+// Config configures the compressed memory of workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Default is the policy of workspaces whose class has no policy. If not set, such workspaces get no compressed memory.
+	Default *Policy `json:"default,omitempty"`
+	// Classes maps workspace classes to their policy. The class of a workspace is its type, e.g. regular or prebuild.
+	Classes map[string]Policy `json:"classes,omitempty"`
+	// CGroupBasePath is where ws-daemon sees the node's cgroup v2 filesystem
+	CGroupBasePath string `json:"cgroupBasePath"`
+	// SysPath is where ws-daemon sees the node's sys filesystem. Defaults to /sys.
+	SysPath string `json:"sysPath,omitempty"`
+	// SampleInterval is how often we sample compression ratios and memory pressure. Defaults to 30 seconds.
+	SampleInterval util.Duration `json:"sampleInterval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.CGroupBasePath == "" {
+		return xerrors.Errorf("cgroupBasePath is required")
+	}
+	if c.Default != nil {
+		if err := c.Default.Validate(); err != nil {
+			return xerrors.Errorf("default: %w", err)
+		}
+	}
+	for class, p := range c.Classes {
+		if err := p.Validate(); err != nil {
+			return xerrors.Errorf("classes.%s: %w", class, err)
+		}
+	}
+	if c.SampleInterval < 0 {
+		return xerrors.Errorf("sampleInterval must not be negative")
+	}
+	return nil
+}
+
+// PolicyFor returns the policy of a workspace class, or nil if the workspace gets no compressed memory
+func (c *Config) PolicyFor(class string) *Policy {
+	if p, ok := c.Classes[class]; ok {
+		return &p
+	}
+	return c.Default
+}
+
+// Policy is how much compressed memory a class of workspaces gets
+type Policy struct {
+	// Backend is either zswap or zram
+	Backend string `json:"backend"`
+	// MaxSize is the amount of a workspace's memory the kernel may compress, measured before compression
+	MaxSize quota.Size `json:"maxSize"`
+	// Writeback allows zswap to write pages to the swap device on disk once its pool is full.
+	// Without writeback pages stay in memory, compressed or not. zram writeback is configured on the node.
+	Writeback bool `json:"writeback,omitempty"`
+}
+
+// Validate validates the policy
+func (p *Policy) Validate() error {
+	switch p.Backend {
+	case BackendZswap, BackendZram:
+	default:
+		return xerrors.Errorf("backend must be %s or %s, not %q", BackendZswap, BackendZram, p.Backend)
+	}
+	if p.MaxSize <= 0 {
+		return xerrors.Errorf("maxSize is required")
+	}
+	if p.Writeback && p.Backend != BackendZswap {
+		return xerrors.Errorf("writeback is configured on the node for %s", p.Backend)
+	}
+	return nil
+}
+
+// errUnsupported is returned when a workspace cannot get compressed memory on this node
+type errUnsupported struct {
+	Reason string
+}
+
+func (e *errUnsupported) Error() string {
+	return "compressed memory is not supported: " + e.Reason
+}
+
+// Manager applies the compressed memory policies to workspaces and samples their effect
+type Manager struct {
+	Config Config
+
+	procPath string
+	sysPath  string
+	support  map[string]error
+	metrics  *metrics
+	stop     context.CancelFunc
+}
+
+// NewManager creates a new compressed memory manager. procPath is where ws-daemon sees the node's proc filesystem.
+func NewManager(cfg Config, procPath string) *Manager {
+	sysPath := cfg.SysPath
+	if sysPath == "" {
+		sysPath = defaultSysPath
+	}
+	return &Manager{
+		Config:   cfg,
+		procPath: procPath,
+		sysPath:  sysPath,
+		support:  make(map[string]error),
+		metrics:  newMetrics(),
+	}
+}
+
+// RegisterMetrics registers the compressed memory metrics
+func (m *Manager) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range m.metrics.collectors() {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start finds out which backends the node supports. Workspaces whose backend the node does not support
+// run without compressed memory.
+func (m *Manager) Start() {
+	m.support[BackendZswap] = m.checkZswap()
+	zram, err := m.zramDevices()
+	if err == nil && len(zram) == 0 {
+		err = &errUnsupported{Reason: "no zram swap device"}
+	}
+	m.support[BackendZram] = err
+	if !fileExists(filepath.Join(m.Config.CGroupBasePath, "cgroup.controllers")) {
+		err = &errUnsupported{Reason: "node does not use cgroup v2"}
+		m.support[BackendZswap] = err
+		m.support[BackendZram] = err
+	}
+	for backend, err := range m.support {
+		if err != nil {
+			log.WithError(err).WithField("backend", backend).Warn("workspaces cannot use compressed memory backend")
+		}
+	}
+
+	if m.support[BackendZram] == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		m.stop = cancel
+		go m.sampleZram(ctx, zram)
+	}
+}
+
+// Close stops sampling
+func (m *Manager) Close() error {
+	if m.stop != nil {
+		m.stop()
+	}
+	return nil
+}
+
+func (m *Manager) checkZswap() error {
+	enabled, err := os.ReadFile(filepath.Join(m.sysPath, "module", "zswap", "parameters", "enabled"))
+	if os.IsNotExist(err) {
+		return &errUnsupported{Reason: "kernel has no zswap"}
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot check if zswap is enabled: %w", err)
+	}
+	if strings.TrimSpace(string(enabled)) != "Y" {
+		return &errUnsupported{Reason: "zswap is disabled on the node"}
+	}
+	return nil
+}
+
+// zramDevices returns the names of the zram devices the node swaps to, e.g. zram0
+func (m *Manager) zramDevices() ([]string, error) {
+	swaps, err := os.ReadFile(filepath.Join(m.procPath, "swaps"))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read swap devices: %w", err)
+	}
+	var res []string
+	scanner := bufio.NewScanner(bytes.NewReader(swaps))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "/dev/zram") {
+			continue
+		}
+		res = append(res, filepath.Base(fields[0]))
+	}
+	return res, scanner.Err()
+}
+
+// apply allows the workspace in cgroup to compress up to the policy's size of its memory
+func (m *Manager) apply(cgroup string, p *Policy) error {
+	if err := m.support[p.Backend]; err != nil {
+		return err
+	}
+	swapMax := filepath.Join(cgroup, "memory.swap.max")
+	if _, err := os.Stat(swapMax); err != nil {
+		return &errUnsupported{Reason: "no swap accounting for workspace cgroup"}
+	}
+	zswapMax := filepath.Join(cgroup, "memory.zswap.max")
+	_, err := os.Stat(zswapMax)
+	hasZswap := err == nil
+
+	size := strconv.FormatInt(int64(p.MaxSize), 10)
+	var files []cgroupFile
+	switch p.Backend {
+	case BackendZswap:
+		if !hasZswap {
+			return &errUnsupported{Reason: "kernel has no per-cgroup zswap limit"}
+		}
+		files = append(files, cgroupFile{zswapMax, size})
+		if fn := filepath.Join(cgroup, "memory.zswap.writeback"); fileExists(fn) {
+			files = append(files, cgroupFile{fn, boolValue(p.Writeback)})
+		} else if !p.Writeback {
+			log.WithField("cgroup", cgroup).Debug("kernel cannot disable zswap writeback - pages may reach the swap device")
+		}
+	case BackendZram:
+		if hasZswap {
+			// pages must not take a detour through zswap on their way to the zram device
+			files = append(files, cgroupFile{zswapMax, "0"})
+		}
+	}
+	// zswap only compresses pages the workspace may swap, and zram is a swap device
+	files = append(files, cgroupFile{swapMax, size})
+
+	for _, f := range files {
+		err := os.WriteFile(f.Name, []byte(f.Value), 0644)
+		if err != nil {
+			return xerrors.Errorf("cannot write %s: %w", filepath.Base(f.Name), err)
+		}
+	}
+	return nil
+}
+
+type cgroupFile struct {
+	Name  string
+	Value string
+}
+
+func fileExists(fn string) bool {
+	_, err := os.Stat(fn)
+	return err == nil
+}
+
+func boolValue(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// sampleZram samples the node-wide compression ratio of the zram devices until ctx is done.
+// zram does not account compression per cgroup.
+func (m *Manager) sampleZram(ctx context.Context, devices []string) {
+	t := time.NewTicker(m.sampleInterval())
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		var orig, compr uint64
+		for _, dev := range devices {
+			o, c, err := readMMStat(filepath.Join(m.sysPath, "block", dev, "mm_stat"))
+			if err != nil {
+				log.WithError(err).WithField("device", dev).Debug("cannot sample zram device")
+				continue
+			}
+			orig += o
+			compr += c
+		}
+		if compr > 0 {
+			m.metrics.ratio.WithLabelValues(BackendZram).Observe(float64(orig) / float64(compr))
+		}
+	}
+}
+
+func (m *Manager) sampleInterval() time.Duration {
+	if m.Config.SampleInterval > 0 {
+		return time.Duration(m.Config.SampleInterval)
+	}
+	return defaultSampleInterval
+}
+
+// readMMStat returns the size of the data stored on a zram device before and after compression
+func readMMStat(fn string) (orig, compr uint64, err error) {
+	c, err := os.ReadFile(fn)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(c))
+	if len(fields) < 2 {
+		return 0, 0, xerrors.Errorf("invalid mm_stat: %q", string(c))
+	}
+	orig, err = strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("invalid mm_stat: %w", err)
+	}
+	compr, err = strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("invalid mm_stat: %w", err)
+	}
+	return orig, compr, nil
+}
+
+// readMemoryStat returns the counters of a cgroup's memory.stat
+func readMemoryStat(cgroup string) (map[string]uint64, error) {
+	c, err := os.ReadFile(filepath.Join(cgroup, "memory.stat"))
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(c))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		res[fields[0]] = v
+	}
+	return res, scanner.Err()
+}
+
+// readMemoryStall returns for how long some tasks of a cgroup stalled on memory, e.g. while the kernel
+// decompressed their pages
+func readMemoryStall(cgroup string) (time.Duration, error) {
+	c, err := os.ReadFile(filepath.Join(cgroup, "memory.pressure"))
+	if err != nil {
+		return 0, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(c))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "total=") {
+				continue
+			}
+			us, err := strconv.ParseUint(strings.TrimPrefix(f, "total="), 10, 64)
+			if err != nil {
+				return 0, xerrors.Errorf("invalid memory pressure: %w", err)
+			}
+			return time.Duration(us) * time.Microsecond, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, xerrors.Errorf("memory pressure has no total stall time")
+}
+
+const (
+	outcomeApplied     = "applied"
+	outcomeUnsupported = "unsupported"
+	outcomeFailed      = "failed"
+)
+
+type metrics struct {
+	workspaces *prometheus.CounterVec
+	ratio      *prometheus.HistogramVec
+	swapins    *prometheus.CounterVec
+	stall      *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		workspaces: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "compressed_memory_workspaces_total",
+			Help: "Workspaces with a compressed memory policy by backend and outcome: applied, unsupported or failed",
+		}, []string{"backend", "outcome"}),
+		ratio: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "compressed_memory_compression_ratio",
+			Help:    "Ratio of the size of compressed workspace memory before and after compression. zram is sampled per node, zswap per workspace.",
+			Buckets: []float64{1, 1.5, 2, 2.5, 3, 4, 5, 7, 10},
+		}, []string{"backend"}),
+		swapins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "compressed_memory_swapins_total",
+			Help: "Pages workspaces faulted back in from compressed memory",
+		}, []string{"backend"}),
+		stall: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "compressed_memory_stall_seconds_total",
+			Help: "Time tasks of workspaces with compressed memory stalled on memory, e.g. waiting for decompression",
+		}, []string{"backend"}),
+	}
+}
+
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.workspaces, m.ratio, m.swapins, m.stall}
+}
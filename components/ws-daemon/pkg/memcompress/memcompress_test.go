@@ -0,0 +1,259 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package memcompress
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Policy Policy
+		Valid  bool
+	}{
+		{Name: "zswap", Policy: Policy{Backend: BackendZswap, MaxSize: quota.Gigabyte}, Valid: true},
+		{Name: "zswap with writeback", Policy: Policy{Backend: BackendZswap, MaxSize: quota.Gigabyte, Writeback: true}, Valid: true},
+		{Name: "zram", Policy: Policy{Backend: BackendZram, MaxSize: quota.Gigabyte}, Valid: true},
+		{Name: "zram with writeback", Policy: Policy{Backend: BackendZram, MaxSize: quota.Gigabyte, Writeback: true}},
+		{Name: "no size", Policy: Policy{Backend: BackendZswap}},
+		{Name: "unknown backend", Policy: Policy{Backend: "swap", MaxSize: quota.Gigabyte}},
+		{Name: "no backend"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Policy.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	cfg := Config{
+		Enabled: true,
+		Default: &Policy{Backend: BackendZram, MaxSize: quota.Gigabyte},
+		Classes: map[string]Policy{
+			"regular": {Backend: BackendZswap, MaxSize: 2 * quota.Gigabyte},
+		},
+	}
+	if p := cfg.PolicyFor("regular"); p == nil || p.Backend != BackendZswap {
+		t.Errorf("unexpected policy for regular workspaces: %+v", p)
+	}
+	if p := cfg.PolicyFor("prebuild"); p == nil || p.Backend != BackendZram {
+		t.Errorf("unexpected policy for prebuilds: %+v", p)
+	}
+	cfg.Default = nil
+	if p := cfg.PolicyFor("prebuild"); p != nil {
+		t.Errorf("expected no policy for prebuilds, got %+v", p)
+	}
+}
+
+func writeFiles(t *testing.T, base string, files map[string]string) {
+	for name, content := range files {
+		fn := filepath.Join(base, name)
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fn, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFiles(t *testing.T, base string, names ...string) map[string]string {
+	res := make(map[string]string, len(names))
+	for _, name := range names {
+		c, err := os.ReadFile(filepath.Join(base, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res[name] = string(c)
+	}
+	return res
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Policy      Policy
+		Node        map[string]string
+		CGroup      map[string]string
+		Unsupported bool
+		Expectation map[string]string
+	}{
+		{
+			Name:   "zswap",
+			Policy: Policy{Backend: BackendZswap, MaxSize: quota.Gigabyte},
+			Node:   map[string]string{"sys/module/zswap/parameters/enabled": "Y\n"},
+			CGroup: map[string]string{"memory.swap.max": "0", "memory.zswap.max": "max", "memory.zswap.writeback": "1"},
+			Expectation: map[string]string{
+				"memory.swap.max":        "1073741824",
+				"memory.zswap.max":       "1073741824",
+				"memory.zswap.writeback": "0",
+			},
+		},
+		{
+			Name:        "zswap without writeback control",
+			Policy:      Policy{Backend: BackendZswap, MaxSize: quota.Gigabyte, Writeback: true},
+			Node:        map[string]string{"sys/module/zswap/parameters/enabled": "Y\n"},
+			CGroup:      map[string]string{"memory.swap.max": "0", "memory.zswap.max": "max"},
+			Expectation: map[string]string{"memory.swap.max": "1073741824", "memory.zswap.max": "1073741824"},
+		},
+		{
+			Name:        "zswap disabled on node",
+			Policy:      Policy{Backend: BackendZswap, MaxSize: quota.Gigabyte},
+			Node:        map[string]string{"sys/module/zswap/parameters/enabled": "N\n"},
+			CGroup:      map[string]string{"memory.swap.max": "0", "memory.zswap.max": "max"},
+			Unsupported: true,
+			Expectation: map[string]string{"memory.swap.max": "0", "memory.zswap.max": "max"},
+		},
+		{
+			Name:        "zswap without cgroup limit",
+			Policy:      Policy{Backend: BackendZswap, MaxSize: quota.Gigabyte},
+			Node:        map[string]string{"sys/module/zswap/parameters/enabled": "Y\n"},
+			CGroup:      map[string]string{"memory.swap.max": "0"},
+			Unsupported: true,
+			Expectation: map[string]string{"memory.swap.max": "0"},
+		},
+		{
+			Name:   "zram",
+			Policy: Policy{Backend: BackendZram, MaxSize: 512 * quota.Megabyte},
+			Node: map[string]string{
+				"proc/swaps": "Filename\tType\tSize\tUsed\tPriority\n/dev/zram0\tpartition\t8388604\t0\t100\n",
+			},
+			CGroup:      map[string]string{"memory.swap.max": "0", "memory.zswap.max": "max"},
+			Expectation: map[string]string{"memory.swap.max": "536870912", "memory.zswap.max": "0"},
+		},
+		{
+			Name:   "zram without device",
+			Policy: Policy{Backend: BackendZram, MaxSize: 512 * quota.Megabyte},
+			Node: map[string]string{
+				"proc/swaps": "Filename\tType\tSize\tUsed\tPriority\n/dev/sda2\tpartition\t8388604\t0\t-2\n",
+			},
+			CGroup:      map[string]string{"memory.swap.max": "0"},
+			Unsupported: true,
+			Expectation: map[string]string{"memory.swap.max": "0"},
+		},
+		{
+			Name:   "no swap accounting",
+			Policy: Policy{Backend: BackendZram, MaxSize: 512 * quota.Megabyte},
+			Node: map[string]string{
+				"proc/swaps": "Filename\tType\tSize\tUsed\tPriority\n/dev/zram0\tpartition\t8388604\t0\t100\n",
+			},
+			CGroup:      map[string]string{"memory.max": "max"},
+			Unsupported: true,
+			Expectation: map[string]string{"memory.max": "max"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			base := t.TempDir()
+			node := map[string]string{
+				"cgroup/cgroup.controllers": "cpu memory",
+				"proc/swaps":                "Filename\tType\tSize\tUsed\tPriority\n",
+			}
+			for k, v := range test.Node {
+				node[k] = v
+			}
+			writeFiles(t, base, node)
+			cgroup := filepath.Join(base, "cgroup", "kubepods", "pod1234", "workspace")
+			writeFiles(t, cgroup, test.CGroup)
+
+			m := NewManager(Config{
+				Enabled:        true,
+				CGroupBasePath: filepath.Join(base, "cgroup"),
+				SysPath:        filepath.Join(base, "sys"),
+			}, filepath.Join(base, "proc"))
+			m.Start()
+			defer m.Close()
+
+			err := m.apply(cgroup, &test.Policy)
+			if _, unsupported := err.(*errUnsupported); unsupported != test.Unsupported {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !test.Unsupported && err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for name := range test.Expectation {
+				names = append(names, name)
+			}
+			if diff := cmp.Diff(test.Expectation, readFiles(t, cgroup, names...)); diff != "" {
+				t.Errorf("unexpected cgroup (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyWithoutCGroupV2(t *testing.T) {
+	base := t.TempDir()
+	writeFiles(t, base, map[string]string{
+		"sys/module/zswap/parameters/enabled":           "Y",
+		"proc/swaps":                                    "Filename\tType\tSize\tUsed\tPriority\n",
+		"cgroup/memory/workspace/memory.limit_in_bytes": "2147483648",
+	})
+	m := NewManager(Config{
+		Enabled:        true,
+		CGroupBasePath: filepath.Join(base, "cgroup"),
+		SysPath:        filepath.Join(base, "sys"),
+	}, filepath.Join(base, "proc"))
+	m.Start()
+	defer m.Close()
+
+	err := m.apply(filepath.Join(base, "cgroup", "memory", "workspace"), &Policy{Backend: BackendZswap, MaxSize: quota.Gigabyte})
+	if _, unsupported := err.(*errUnsupported); !unsupported {
+		t.Errorf("expected unsupported error, got %v", err)
+	}
+}
+
+func TestReadStats(t *testing.T) {
+	base := t.TempDir()
+	writeFiles(t, base, map[string]string{
+		"memory.stat":     "anon 1048576\nfile 0\nzswap 262144\nzswapped 1048576\nzswpin 12\nzswpout 256\npswpin 3\n",
+		"memory.pressure": "some avg10=0.00 avg60=0.00 avg300=0.00 total=1500000\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=500000\n",
+		"mm_stat":         "  4194304  1048576  1310720        0  1310720        0        0        0        0\n",
+	})
+
+	stat, err := readMemoryStat(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]uint64{
+		"anon": 1048576, "file": 0, "zswap": 262144, "zswapped": 1048576, "zswpin": 12, "zswpout": 256, "pswpin": 3,
+	}, stat); diff != "" {
+		t.Errorf("unexpected memory.stat (-want +got):\n%s", diff)
+	}
+
+	stall, err := readMemoryStall(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stall != 1500*time.Millisecond {
+		t.Errorf("unexpected stall time: %v", stall)
+	}
+
+	orig, compr, err := readMMStat(filepath.Join(base, "mm_stat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orig != 4194304 || compr != 1048576 {
+		t.Errorf("unexpected mm_stat: orig=%d compr=%d", orig, compr)
+	}
+}
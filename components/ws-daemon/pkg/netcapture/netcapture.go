@@ -0,0 +1,355 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package netcapture captures the network traffic of workspaces on demand, so that we can debug connectivity
+// issues without access to the node.
+package netcapture
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/container"
+)
+
+const (
+	defaultDuration    = 30 * time.Second
+	defaultMaxDuration = 5 * time.Minute
+	defaultMaxBytes    = 100 * 1024 * 1024
+	// maxSnapLen is the largest packet we capture, which covers the loopback MTU
+	maxSnapLen = 65535
+
+	// resolveTimeout limits how long we look for the workspace container
+	resolveTimeout = 5 * time.Second
+	// uploadTimeout limits how long we try to upload a capture once we're done capturing
+	uploadTimeout = 2 * time.Minute
+
+	contentTypePcap = "application/vnd.tcpdump.pcap"
+)
+
+// Config configures on-demand packet captures
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// DefaultDuration is how long we capture if the request does not say. Defaults to 30 seconds.
+	DefaultDuration util.Duration `json:"defaultDuration,omitempty"`
+	// MaxDuration is the longest capture we take. Defaults to 5 minutes.
+	MaxDuration util.Duration `json:"maxDuration,omitempty"`
+	// MaxBytes is the largest capture we take. Defaults to 100 MiB.
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+
+	// StorageOwner is the owner whose remote storage bucket captures go to. Captures contain the traffic
+	// of the workspace owner and never go to their bucket.
+	StorageOwner string `json:"storageOwner"`
+	// AllowedClients are the common names of the TLS client certificates which may capture.
+	// Empty allows all clients of the ws-daemon API.
+	AllowedClients []string `json:"allowedClients,omitempty"`
+
+	// TempDir is where we keep captures until they're uploaded. Defaults to the system's temp directory.
+	TempDir string `json:"tempDir,omitempty"`
+}
+
+// Uploader uploads a capture of a workspace instance and returns its fully qualified name
+type Uploader interface {
+	Upload(ctx context.Context, instanceID, source, name string, annotations map[string]string) (url string, err error)
+}
+
+// Service captures the network traffic of workspaces on request
+type Service struct {
+	Config Config
+
+	// ResolveNetNS returns the path of the network namespace of a workspace instance
+	ResolveNetNS func(ctx context.Context, instanceID string) (string, error)
+	// Open opens a packet source in a network namespace
+	Open     func(netns string) (PacketSource, error)
+	Uploader Uploader
+
+	mu     sync.Mutex
+	active map[string]struct{}
+
+	metrics *metrics
+}
+
+// NewService creates a new capture service which finds workspaces using the container runtime
+// and uploads captures to the remote storage. procPath is where ws-daemon sees the node's proc filesystem.
+func NewService(cfg Config, rt container.Runtime, storageCfg storage.Config, procPath string) *Service {
+	return &Service{
+		Config: cfg,
+		ResolveNetNS: func(ctx context.Context, instanceID string) (string, error) {
+			ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+			defer cancel()
+
+			id, err := rt.WaitForContainer(ctx, instanceID)
+			if err != nil {
+				return "", xerrors.Errorf("cannot find workspace container: %w", err)
+			}
+			pid, err := rt.ContainerPID(ctx, id)
+			if err != nil {
+				return "", xerrors.Errorf("cannot find workspace container PID: %w", err)
+			}
+			return filepath.Join(procPath, fmt.Sprint(pid), "ns", "net"), nil
+		},
+		Open:     OpenNetNS,
+		Uploader: &storageUploader{Config: storageCfg, Owner: cfg.StorageOwner},
+		active:   make(map[string]struct{}),
+		metrics:  newMetrics(),
+	}
+}
+
+// RegisterMetrics registers the capture metrics with a registry
+func (s *Service) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(s.metrics.Captures)
+}
+
+// CapturePackets captures the network traffic of a workspace and uploads the capture
+func (s *Service) CapturePackets(ctx context.Context, req *api.CapturePacketsRequest) (resp *api.CapturePacketsResponse, err error) {
+	client, err := s.authorize(ctx)
+	if err != nil {
+		s.audit(req, client).WithError(err).Warn("packet capture denied")
+		s.metrics.Captures.WithLabelValues("denied").Inc()
+		return nil, err
+	}
+
+	duration, maxBytes, snapLen, err := s.limits(req)
+	if err != nil {
+		s.audit(req, client).WithError(err).Warn("packet capture rejected")
+		s.metrics.Captures.WithLabelValues("invalid").Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.mu.Lock()
+	if _, exists := s.active[req.Id]; exists {
+		s.mu.Unlock()
+		return nil, status.Error(codes.AlreadyExists, "there is a packet capture for this workspace already")
+	}
+	s.active[req.Id] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, req.Id)
+		s.mu.Unlock()
+	}()
+
+	audit := s.audit(req, client).WithField("duration", duration.String()).WithField("maxBytes", maxBytes)
+	audit.Info("packet capture started")
+	defer func() {
+		if err != nil {
+			audit.WithError(err).Warn("packet capture failed")
+			s.metrics.Captures.WithLabelValues("failed").Inc()
+			return
+		}
+		audit.WithField("url", resp.Url).WithField("packets", resp.Packets).WithField("bytes", resp.Bytes).Info("packet capture finished")
+		s.metrics.Captures.WithLabelValues("success").Inc()
+	}()
+
+	netns, err := s.ResolveNetNS(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	src, err := s.Open(netns)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	f, err := os.CreateTemp(s.Config.TempDir, "capture-*.pcap")
+	if err != nil {
+		src.Close()
+		return nil, status.Errorf(codes.Internal, "cannot create capture file: %q", err)
+	}
+	defer os.Remove(f.Name())
+
+	captureCtx, cancel := context.WithTimeout(ctx, duration)
+	packets, size, truncated, err := capture(captureCtx, src, f, maxBytes, snapLen)
+	cancel()
+	src.Close()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot capture packets: %q", err)
+	}
+	if ctx.Err() != nil {
+		return nil, status.Error(codes.Canceled, "packet capture was cancelled")
+	}
+
+	uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+	name := fmt.Sprintf("captures/%s.pcap", time.Now().UTC().Format("20060102-150405"))
+	url, err := s.Uploader.Upload(uploadCtx, req.Id, f.Name(), name, map[string]string{
+		"requester": req.Requester,
+		"reason":    req.Reason,
+		"client":    client,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot upload capture: %q", err)
+	}
+
+	return &api.CapturePacketsResponse{
+		Url:       url,
+		Packets:   packets,
+		Bytes:     size,
+		Truncated: truncated,
+	}, nil
+}
+
+// authorize returns the common name of the client's certificate, and fails if the client may not capture
+func (s *Service) authorize(ctx context.Context) (client string, err error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(ti.State.VerifiedChains) > 0 && len(ti.State.VerifiedChains[0]) > 0 {
+			client = ti.State.VerifiedChains[0][0].Subject.CommonName
+		}
+	}
+	if len(s.Config.AllowedClients) == 0 {
+		return client, nil
+	}
+	for _, c := range s.Config.AllowedClients {
+		if client != "" && c == client {
+			return client, nil
+		}
+	}
+	return client, status.Error(codes.PermissionDenied, "client must not capture packets")
+}
+
+// limits validates the request against our configuration
+func (s *Service) limits(req *api.CapturePacketsRequest) (duration time.Duration, maxBytes int64, snapLen int, err error) {
+	if req.Id == "" {
+		return 0, 0, 0, xerrors.Errorf("id is required")
+	}
+	if req.Requester == "" || req.Reason == "" {
+		return 0, 0, 0, xerrors.Errorf("requester and reason are required")
+	}
+
+	duration = time.Duration(s.Config.DefaultDuration)
+	if duration == 0 {
+		duration = defaultDuration
+	}
+	if req.Duration != "" {
+		duration, err = time.ParseDuration(req.Duration)
+		if err != nil {
+			return 0, 0, 0, xerrors.Errorf("invalid duration: %w", err)
+		}
+	}
+	maxDuration := time.Duration(s.Config.MaxDuration)
+	if maxDuration == 0 {
+		maxDuration = defaultMaxDuration
+	}
+	if duration <= 0 || duration > maxDuration {
+		return 0, 0, 0, xerrors.Errorf("duration must be between 0 and %s", maxDuration)
+	}
+
+	maxBytes = s.Config.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = defaultMaxBytes
+	}
+	if req.MaxBytes < 0 || req.MaxBytes > maxBytes {
+		return 0, 0, 0, xerrors.Errorf("max bytes must be between 0 and %d", maxBytes)
+	}
+	if req.MaxBytes > 0 {
+		maxBytes = req.MaxBytes
+	}
+
+	snapLen = maxSnapLen
+	if req.SnapLen < 0 || req.SnapLen > maxSnapLen {
+		return 0, 0, 0, xerrors.Errorf("snap len must be between 0 and %d", maxSnapLen)
+	}
+	if req.SnapLen > 0 {
+		snapLen = int(req.SnapLen)
+	}
+	return duration, maxBytes, snapLen, nil
+}
+
+// audit returns a log entry which identifies a capture and who asked for it. Captures contain user traffic,
+// hence we log all of them irrespective of their outcome.
+func (s *Service) audit(req *api.CapturePacketsRequest, client string) *logrus.Entry {
+	return log.WithFields(log.OWI("", "", req.Id)).WithFields(logrus.Fields{
+		"audit":     "packet-capture",
+		"requester": req.Requester,
+		"reason":    req.Reason,
+		"client":    client,
+	})
+}
+
+// capture writes packets from src to out until ctx is done or the capture reached maxBytes
+func capture(ctx context.Context, src PacketSource, out io.Writer, maxBytes int64, snapLen int) (packets, size int64, truncated bool, err error) {
+	w, err := newPcapWriter(out, snapLen)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	buf := make([]byte, snapLen)
+	for ctx.Err() == nil {
+		captured, length, err := src.ReadPacket(buf)
+		if err != nil {
+			return packets, w.Size(), false, err
+		}
+		if captured == 0 {
+			continue
+		}
+		if w.Size()+w.RecordSize(captured) > maxBytes {
+			return packets, w.Size(), true, nil
+		}
+
+		err = w.WritePacket(time.Now(), buf[:captured], length)
+		if err != nil {
+			return packets, w.Size(), false, err
+		}
+		packets++
+	}
+	return packets, w.Size(), false, nil
+}
+
+// storageUploader uploads captures to a bucket of the storage owner, where they're sorted by workspace instance
+type storageUploader struct {
+	Config storage.Config
+	Owner  string
+}
+
+func (u *storageUploader) Upload(ctx context.Context, instanceID, source, name string, annotations map[string]string) (url string, err error) {
+	rs, err := storage.NewDirectAccess(&u.Config)
+	if err != nil {
+		return "", err
+	}
+	err = rs.Init(ctx, u.Owner, instanceID)
+	if err != nil {
+		return "", err
+	}
+	err = rs.EnsureExists(ctx)
+	if err != nil {
+		return "", err
+	}
+	_, _, err = rs.Upload(ctx, source, name, storage.WithContentType(contentTypePcap), storage.WithAnnotations(annotations))
+	if err != nil {
+		return "", err
+	}
+	return rs.Qualify(name), nil
+}
+
+type metrics struct {
+	Captures *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		Captures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "packet_captures_total",
+			Help: "Number of packet capture requests by outcome",
+		}, []string{"outcome"}),
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package netcapture
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+const instanceID = "a6ad8fb2-37f3-4f4c-9e1b-9be0f6b9a5a1"
+
+// fakeSource produces a fixed number of packets of the same size
+type fakeSource struct {
+	Packets int
+	Size    int
+	closed  bool
+}
+
+func (s *fakeSource) ReadPacket(buf []byte) (captured, length int, err error) {
+	if s.Packets == 0 {
+		return 0, 0, nil
+	}
+	s.Packets--
+	captured = s.Size
+	if captured > len(buf) {
+		captured = len(buf)
+	}
+	return captured, s.Size, nil
+}
+
+func (s *fakeSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+type fakeUploader struct {
+	Content     []byte
+	Annotations map[string]string
+}
+
+func (u *fakeUploader) Upload(ctx context.Context, instanceID, source, name string, annotations map[string]string) (string, error) {
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return "", err
+	}
+	u.Content = content
+	u.Annotations = annotations
+	return instanceID + "/" + name, nil
+}
+
+func newTestService(cfg Config, src *fakeSource, up *fakeUploader) *Service {
+	if cfg.DefaultDuration == 0 {
+		cfg.DefaultDuration = util.Duration(100 * time.Millisecond)
+	}
+	return &Service{
+		Config: cfg,
+		ResolveNetNS: func(ctx context.Context, id string) (string, error) {
+			return "/proc/42/ns/net", nil
+		},
+		Open:     func(netns string) (PacketSource, error) { return src, nil },
+		Uploader: up,
+		active:   make(map[string]struct{}),
+		metrics:  newMetrics(),
+	}
+}
+
+func withClient(ctx context.Context, commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+func TestCapturePackets(t *testing.T) {
+	type Expectation struct {
+		Code        codes.Code
+		Packets     int64
+		Bytes       int64
+		Truncated   bool
+		Annotations map[string]string
+	}
+	tests := []struct {
+		Name        string
+		Config      Config
+		Client      string
+		Request     *api.CapturePacketsRequest
+		Source      fakeSource
+		Expectation Expectation
+	}{
+		{
+			Name:    "capture",
+			Client:  "ws-manager",
+			Request: &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123"},
+			Source:  fakeSource{Packets: 3, Size: 100},
+			Expectation: Expectation{
+				Packets:     3,
+				Bytes:       pcapHeaderSize + 3*(pcapRecordSize+100),
+				Annotations: map[string]string{"client": "ws-manager", "reason": "ticket-123", "requester": "admin@gitpod.io"},
+			},
+		},
+		{
+			Name:    "snap len",
+			Request: &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123", SnapLen: 64},
+			Source:  fakeSource{Packets: 2, Size: 1500},
+			Expectation: Expectation{
+				Packets:     2,
+				Bytes:       pcapHeaderSize + 2*(pcapRecordSize+64),
+				Annotations: map[string]string{"client": "", "reason": "ticket-123", "requester": "admin@gitpod.io"},
+			},
+		},
+		{
+			Name:    "size cap",
+			Request: &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123", MaxBytes: pcapHeaderSize + 2*(pcapRecordSize+100) + 10},
+			Source:  fakeSource{Packets: 10, Size: 100},
+			Expectation: Expectation{
+				Packets:     2,
+				Bytes:       pcapHeaderSize + 2*(pcapRecordSize+100),
+				Truncated:   true,
+				Annotations: map[string]string{"client": "", "reason": "ticket-123", "requester": "admin@gitpod.io"},
+			},
+		},
+		{
+			Name:        "client not allowed",
+			Config:      Config{AllowedClients: []string{"admin-cli"}},
+			Client:      "ws-manager",
+			Request:     &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123"},
+			Expectation: Expectation{Code: codes.PermissionDenied},
+		},
+		{
+			Name:        "no client certificate",
+			Config:      Config{AllowedClients: []string{"admin-cli"}},
+			Request:     &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123"},
+			Expectation: Expectation{Code: codes.PermissionDenied},
+		},
+		{
+			Name:    "client allowed",
+			Config:  Config{AllowedClients: []string{"admin-cli"}},
+			Client:  "admin-cli",
+			Request: &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123"},
+			Expectation: Expectation{
+				Bytes:       pcapHeaderSize,
+				Annotations: map[string]string{"client": "admin-cli", "reason": "ticket-123", "requester": "admin@gitpod.io"},
+			},
+		},
+		{
+			Name:        "missing reason",
+			Request:     &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io"},
+			Expectation: Expectation{Code: codes.InvalidArgument},
+		},
+		{
+			Name:        "duration too long",
+			Request:     &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123", Duration: "1h"},
+			Expectation: Expectation{Code: codes.InvalidArgument},
+		},
+		{
+			Name:        "too many bytes",
+			Config:      Config{MaxBytes: 1024},
+			Request:     &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123", MaxBytes: 2048},
+			Expectation: Expectation{Code: codes.InvalidArgument},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				src = test.Source
+				up  fakeUploader
+				svc = newTestService(test.Config, &src, &up)
+				ctx = context.Background()
+			)
+			if test.Client != "" {
+				ctx = withClient(ctx, test.Client)
+			}
+
+			resp, err := svc.CapturePackets(ctx, test.Request)
+			act := Expectation{Code: status.Code(err)}
+			if resp != nil {
+				act.Packets = resp.Packets
+				act.Bytes = resp.Bytes
+				act.Truncated = resp.Truncated
+				act.Annotations = up.Annotations
+				if int64(len(up.Content)) != resp.Bytes {
+					t.Errorf("uploaded %d bytes, but reported %d", len(up.Content), resp.Bytes)
+				}
+				if !src.closed {
+					t.Error("packet source was not closed")
+				}
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+			if len(svc.active) != 0 {
+				t.Error("capture is still marked active")
+			}
+		})
+	}
+}
+
+func TestCapturePacketsOnePerWorkspace(t *testing.T) {
+	svc := newTestService(Config{}, &fakeSource{}, &fakeUploader{})
+	svc.active[instanceID] = struct{}{}
+
+	_, err := svc.CapturePackets(context.Background(), &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists, got %v", err)
+	}
+}
+
+func TestPcapHeader(t *testing.T) {
+	var up fakeUploader
+	svc := newTestService(Config{}, &fakeSource{Packets: 1, Size: 60}, &up)
+	_, err := svc.CapturePackets(context.Background(), &api.CapturePacketsRequest{Id: instanceID, Requester: "admin@gitpod.io", Reason: "ticket-123", SnapLen: 128})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hdr := up.Content[:pcapHeaderSize]
+	if magic := binary.LittleEndian.Uint32(hdr[0:4]); magic != pcapMagic {
+		t.Errorf("unexpected magic: %x", magic)
+	}
+	if snapLen := binary.LittleEndian.Uint32(hdr[16:20]); snapLen != 128 {
+		t.Errorf("unexpected snap len: %d", snapLen)
+	}
+	rec := up.Content[pcapHeaderSize : pcapHeaderSize+pcapRecordSize]
+	if captured, length := binary.LittleEndian.Uint32(rec[8:12]), binary.LittleEndian.Uint32(rec[12:16]); captured != 60 || length != 60 {
+		t.Errorf("unexpected record lengths: captured %d, length %d", captured, length)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package netcapture
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+const (
+	pcapMagic            = 0xa1b2c3d4
+	pcapVersionMajor     = 2
+	pcapVersionMinor     = 4
+	pcapLinkTypeEthernet = 1
+
+	pcapHeaderSize = 24
+	pcapRecordSize = 16
+)
+
+// pcapWriter writes packets in the libpcap file format which tcpdump and Wireshark read
+type pcapWriter struct {
+	w       io.Writer
+	snapLen int
+	size    int64
+}
+
+func newPcapWriter(w io.Writer, snapLen int) (*pcapWriter, error) {
+	var hdr [pcapHeaderSize]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], pcapMagic)
+	binary.LittleEndian.PutUint16(hdr[4:6], pcapVersionMajor)
+	binary.LittleEndian.PutUint16(hdr[6:8], pcapVersionMinor)
+	// hdr[8:16] are the timezone offset and timestamp accuracy, which are always zero
+	binary.LittleEndian.PutUint32(hdr[16:20], uint32(snapLen))
+	binary.LittleEndian.PutUint32(hdr[20:24], pcapLinkTypeEthernet)
+
+	_, err := w.Write(hdr[:])
+	if err != nil {
+		return nil, err
+	}
+	return &pcapWriter{w: w, snapLen: snapLen, size: pcapHeaderSize}, nil
+}
+
+// RecordSize returns the number of bytes a packet of the captured length adds to the capture
+func (p *pcapWriter) RecordSize(captured int) int64 {
+	if captured > p.snapLen {
+		captured = p.snapLen
+	}
+	return int64(pcapRecordSize + captured)
+}
+
+// WritePacket writes a packet of which we captured data. length is the size of the packet on the wire.
+func (p *pcapWriter) WritePacket(ts time.Time, data []byte, length int) error {
+	if len(data) > p.snapLen {
+		data = data[:p.snapLen]
+	}
+
+	var hdr [pcapRecordSize]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(hdr[4:8], uint32(ts.Nanosecond()/int(time.Microsecond)))
+	binary.LittleEndian.PutUint32(hdr[8:12], uint32(len(data)))
+	binary.LittleEndian.PutUint32(hdr[12:16], uint32(length))
+
+	_, err := p.w.Write(hdr[:])
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write(data)
+	if err != nil {
+		return err
+	}
+	p.size += int64(len(hdr) + len(data))
+	return nil
+}
+
+// Size returns the number of bytes written so far
+func (p *pcapWriter) Size() int64 {
+	return p.size
+}
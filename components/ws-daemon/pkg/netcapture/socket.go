@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package netcapture
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/arch"
+)
+
+// readTimeout is how long a read waits for a packet before it gives the capture loop the chance to stop
+const readTimeout = 200 * time.Millisecond
+
+// PacketSource produces the packets of a network namespace
+type PacketSource interface {
+	// ReadPacket reads the next packet into buf. It returns the number of bytes it read and the size of
+	// the packet on the wire, which is larger if buf was too small. It returns zero if no packet arrived
+	// within a short time.
+	ReadPacket(buf []byte) (captured, length int, err error)
+
+	Close() error
+}
+
+// OpenNetNS opens a packet socket for all interfaces of the network namespace at netns. The socket stays
+// in the namespace it was created in, so that we need to enter the namespace only to create it.
+func OpenNetNS(netns string) (PacketSource, error) {
+	target, err := os.Open(netns)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot open network namespace: %w", err)
+	}
+	defer target.Close()
+
+	// setns affects the calling thread only
+	runtime.LockOSThread()
+	orig, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, xerrors.Errorf("cannot open our network namespace: %w", err)
+	}
+	defer orig.Close()
+
+	err = unix.Setns(int(target.Fd()), unix.CLONE_NEWNET)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, xerrors.Errorf("cannot enter network namespace: %w", err)
+	}
+	fd, sockErr := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW|unix.SOCK_CLOEXEC, int(arch.Htons(unix.ETH_P_ALL)))
+	err = unix.Setns(int(orig.Fd()), unix.CLONE_NEWNET)
+	if err != nil {
+		// we leave the thread locked so that the Go runtime discards it once this goroutine ends,
+		// instead of running other goroutines in the workspace's network namespace
+		if sockErr == nil {
+			unix.Close(fd)
+		}
+		return nil, xerrors.Errorf("cannot leave network namespace: %w", err)
+	}
+	runtime.UnlockOSThread()
+	if sockErr != nil {
+		return nil, xerrors.Errorf("cannot create packet socket: %w", sockErr)
+	}
+
+	tv := unix.NsecToTimeval(readTimeout.Nanoseconds())
+	err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv)
+	if err != nil {
+		unix.Close(fd)
+		return nil, xerrors.Errorf("cannot set read timeout: %w", err)
+	}
+	return &packetSocket{fd: fd}, nil
+}
+
+type packetSocket struct {
+	fd int
+}
+
+func (s *packetSocket) ReadPacket(buf []byte) (captured, length int, err error) {
+	// with MSG_TRUNC packet sockets return the size of the packet rather than what fit into buf
+	n, _, err := unix.Recvfrom(s.fd, buf, unix.MSG_TRUNC)
+	if err == unix.EAGAIN || err == unix.EWOULDBLOCK || err == unix.EINTR {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	captured = n
+	if captured > len(buf) {
+		captured = len(buf)
+	}
+	return captured, n, nil
+}
+
+func (s *packetSocket) Close() error {
+	return unix.Close(s.fd)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package numa
+
+import (
+	"context"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// DispatchListener pins performance-class workspaces and gives them huge pages once their container is running,
+// and releases both once the workspace is gone
+type DispatchListener struct {
+	Manager *Manager
+}
+
+// WorkspaceAdded applies the policy of the workspace's class. If the node cannot give the workspace what its
+// class asks for, the workspace runs without and reports why in its performance status.
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	class := ws.Pod.Labels[wsk8s.TypeLabel]
+	policy := d.Manager.Config.PolicyFor(class)
+	if policy == nil {
+		return nil
+	}
+
+	cgroup, err := dispatch.WorkspaceCGroup(ctx, ws, d.Manager.Config.CGroupBasePath)
+	if err != nil {
+		return err
+	}
+
+	log := log.WithFields(ws.OWI()).WithField("class", class)
+	err = d.Manager.add(ws.InstanceID, class, cgroup, policy)
+	switch err.(type) {
+	case nil:
+		d.Manager.metrics.workspaces.WithLabelValues(outcomeApplied).Inc()
+		log.WithField("cpus", policy.CPUs).WithField("hugePages", int64(policy.HugePages)).Info("applied performance class")
+	case *errUnsupported:
+		d.Manager.metrics.workspaces.WithLabelValues(outcomeUnsupported).Inc()
+		log.WithError(err).Warn("workspace runs without the resources of its performance class")
+	case *errExhausted:
+		d.Manager.metrics.workspaces.WithLabelValues(outcomeExhausted).Inc()
+		log.WithError(err).Warn("workspace runs without the resources of its performance class")
+	default:
+		d.Manager.metrics.workspaces.WithLabelValues(outcomeFailed).Inc()
+		d.Manager.forget(ws.InstanceID)
+		return xerrors.Errorf("cannot apply performance class: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		if d.Manager.isClosing() {
+			// ws-daemon is shutting down, not the workspace
+			return
+		}
+		err := d.Manager.Release(ws.InstanceID)
+		if err != nil {
+			log.WithError(err).Error("cannot release CPUs and huge pages - the leak reconciler will try again")
+			return
+		}
+		log.Debug("released CPUs and huge pages")
+	}()
+	return nil
+}
+
+// GetPerformanceStatus returns what the node dedicated to a workspace, or why it runs without
+func (m *Manager) GetPerformanceStatus(ctx context.Context, req *api.GetPerformanceStatusRequest) (*api.GetPerformanceStatusResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws, ok := m.workspaces[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "workspace %s is not in a performance class on this node", req.Id)
+	}
+	res := &api.GetPerformanceStatusResponse{
+		WorkspaceClass:    ws.Class,
+		NumaNode:          -1,
+		UnavailableReason: ws.Reason,
+	}
+	r, ok := m.reservations[req.Id]
+	if !ok || ws.Reason != "" {
+		return res, nil
+	}
+
+	res.NumaNode = int32(r.Node)
+	res.Cpus = FormatCPUList(r.CPUs)
+	res.MemoryBound = r.MemoryBound
+	if r.HugePages > 0 {
+		res.HugePageSize = int64(r.HugePageSize)
+		res.HugePagesLimit = r.HugePages * int64(r.HugePageSize)
+		used, err := readInt(filepath.Join(ws.CGroup, hugetlbFile(r.HugePageSize, "current")))
+		if err == nil {
+			res.HugePagesUsed = used
+		}
+	}
+	return res, nil
+}
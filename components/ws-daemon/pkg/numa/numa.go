@@ -0,0 +1,702 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package numa gives workspaces in performance classes, e.g. for database benchmarks or large compiles, CPUs of
+// their own on a single NUMA node and memory backed by huge pages. Such workloads suffer when they share CPUs with
+// noisy neighbours or when their memory lives on another NUMA node than their CPUs.
+//
+// ws-daemon pins a workspace using the cgroup v2 cpuset controller: no two pinned workspaces share a CPU, and
+// workspaces may additionally be bound to the memory of their NUMA node. Workspaces without a performance class
+// are not pinned and may still run on any CPU, so nodes for performance classes should run little else.
+// For huge pages, ws-daemon grows the node's huge page pool by what the workspace may use, limits the workspace to
+// that amount using the hugetlb controller, and shrinks the pool again once the workspace stops.
+//
+// Every reservation is kept in a file named after the workspace instance, so that it survives ws-daemon restarts.
+// Reservations of workspaces which stopped while ws-daemon was down are released by the leak reconciler.
+package numa
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+const (
+	// Suffix is the suffix of the reservation files in the state path, which are named after workspace instances
+	Suffix = ".json"
+
+	defaultSysPath      = "/sys"
+	defaultHugePageSize = 2 * quota.Megabyte
+)
+
+// Config configures the CPU pinning and huge pages of performance-class workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Classes maps workspace classes to their policy. The class of a workspace is its type, e.g. regular or prebuild.
+	// Workspaces of other classes are neither pinned nor get huge pages.
+	Classes map[string]Policy `json:"classes,omitempty"`
+	// StatePath is the directory we keep the reservations of workspaces in, so that they survive ws-daemon restarts
+	StatePath string `json:"statePath"`
+	// ReservedCPUs are CPUs we never pin workspaces to in cpuset list format, e.g. 0-1 to leave them to the kubelet
+	// and system daemons
+	ReservedCPUs string `json:"reservedCPUs,omitempty"`
+	// CGroupBasePath is where ws-daemon sees the node's cgroup v2 filesystem
+	CGroupBasePath string `json:"cgroupBasePath"`
+	// SysPath is where ws-daemon sees the node's sys filesystem. Defaults to /sys.
+	SysPath string `json:"sysPath,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.StatePath == "" {
+		return xerrors.Errorf("statePath is required")
+	}
+	if c.CGroupBasePath == "" {
+		return xerrors.Errorf("cgroupBasePath is required")
+	}
+	if _, err := ParseCPUList(c.ReservedCPUs); err != nil {
+		return xerrors.Errorf("reservedCPUs: %w", err)
+	}
+	for class, p := range c.Classes {
+		if err := p.Validate(); err != nil {
+			return xerrors.Errorf("classes.%s: %w", class, err)
+		}
+	}
+	return nil
+}
+
+// PolicyFor returns the policy of a workspace class, or nil if the workspace is not in a performance class
+func (c *Config) PolicyFor(class string) *Policy {
+	if p, ok := c.Classes[class]; ok {
+		return &p
+	}
+	return nil
+}
+
+// Policy is what a performance class of workspaces gets
+type Policy struct {
+	// CPUs is the number of CPUs a workspace gets for itself, all on the same NUMA node. Zero means the workspace
+	// is not pinned. Keep it in line with the CPU limit of the class.
+	CPUs int `json:"cpus,omitempty"`
+	// BindMemory makes workspaces allocate their memory on the NUMA node of their CPUs only. Requires cpus.
+	BindMemory bool `json:"bindMemory,omitempty"`
+	// HugePages is how much of its memory a workspace may back with huge pages
+	HugePages quota.Size `json:"hugePages,omitempty"`
+	// HugePageSize is the size of the huge pages, either 2m or 1g. Defaults to 2m.
+	HugePageSize quota.Size `json:"hugePageSize,omitempty"`
+}
+
+// Validate validates the policy
+func (p *Policy) Validate() error {
+	if p.CPUs < 0 {
+		return xerrors.Errorf("cpus must not be negative")
+	}
+	if p.BindMemory && p.CPUs == 0 {
+		return xerrors.Errorf("bindMemory requires cpus")
+	}
+	if p.HugePages < 0 {
+		return xerrors.Errorf("hugePages must not be negative")
+	}
+	if p.CPUs == 0 && p.HugePages == 0 {
+		return xerrors.Errorf("either cpus or hugePages is required")
+	}
+	size := p.hugePageSize()
+	if size != 2*quota.Megabyte && size != quota.Gigabyte {
+		return xerrors.Errorf("hugePageSize must be 2m or 1g")
+	}
+	if p.HugePages%size != 0 {
+		return xerrors.Errorf("hugePages must be a multiple of hugePageSize")
+	}
+	return nil
+}
+
+func (p *Policy) hugePageSize() quota.Size {
+	if p.HugePageSize == 0 {
+		return defaultHugePageSize
+	}
+	return p.HugePageSize
+}
+
+// errUnsupported is returned when a workspace cannot get what its class asks for on this node
+type errUnsupported struct {
+	Reason string
+}
+
+func (e *errUnsupported) Error() string {
+	return "CPU pinning and huge pages are not supported: " + e.Reason
+}
+
+// errExhausted is returned when the node has not enough free CPUs or huge pages for a workspace
+type errExhausted struct {
+	Reason string
+}
+
+func (e *errExhausted) Error() string {
+	return "not enough resources on this node: " + e.Reason
+}
+
+// Reservation is what the node dedicates to a workspace instance
+type Reservation struct {
+	InstanceID string `json:"instanceId"`
+	// Node is the NUMA node the workspace is pinned to, or -1 if it is not pinned
+	Node        int   `json:"node"`
+	CPUs        []int `json:"cpus,omitempty"`
+	MemoryBound bool  `json:"memoryBound,omitempty"`
+	// HugePages is the number of huge pages we added to the node's pool for the workspace
+	HugePages    int64      `json:"hugePages,omitempty"`
+	HugePageSize quota.Size `json:"hugePageSize,omitempty"`
+}
+
+// workspace is a performance-class workspace we know about
+type workspace struct {
+	Class  string
+	CGroup string
+	// Reason explains why the workspace runs without reservation
+	Reason string
+}
+
+// Manager pins the workspaces of performance classes to CPUs and gives them huge pages
+type Manager struct {
+	Config Config
+
+	mu           sync.Mutex
+	topology     map[int][]int
+	reservations map[string]*Reservation
+	workspaces   map[string]*workspace
+	closing      bool
+
+	sysPath string
+	support error
+	metrics *metrics
+}
+
+// NewManager creates a new manager
+func NewManager(cfg Config) *Manager {
+	sysPath := cfg.SysPath
+	if sysPath == "" {
+		sysPath = defaultSysPath
+	}
+	return &Manager{
+		Config:       cfg,
+		reservations: make(map[string]*Reservation),
+		workspaces:   make(map[string]*workspace),
+		sysPath:      sysPath,
+		metrics:      newMetrics(),
+	}
+}
+
+// RegisterMetrics registers the metrics of the manager
+func (m *Manager) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range m.metrics.collectors() {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start discovers the NUMA nodes of the node and restores the reservations of the workspaces which are running.
+// It must be called before the first workspace is added. If the node does not support pinning, workspaces run without.
+func (m *Manager) Start() error {
+	if _, err := os.Stat(filepath.Join(m.Config.CGroupBasePath, "cgroup.controllers")); err != nil {
+		m.support = &errUnsupported{Reason: "node does not use cgroup v2"}
+		log.WithError(m.support).Warn("workspaces cannot be pinned or get huge pages")
+		return nil
+	}
+
+	topology, err := readTopology(m.sysPath)
+	if err != nil {
+		return xerrors.Errorf("cannot read NUMA topology: %w", err)
+	}
+	reserved, _ := ParseCPUList(m.Config.ReservedCPUs)
+	for node, cpus := range topology {
+		topology[node] = without(cpus, reserved)
+	}
+
+	err = os.MkdirAll(m.Config.StatePath, 0755)
+	if err != nil {
+		return xerrors.Errorf("cannot create state path: %w", err)
+	}
+	entries, err := os.ReadDir(m.Config.StatePath)
+	if err != nil {
+		return xerrors.Errorf("cannot restore reservations: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.topology = topology
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), Suffix) {
+			continue
+		}
+		fc, err := os.ReadFile(filepath.Join(m.Config.StatePath, e.Name()))
+		if err != nil {
+			return xerrors.Errorf("cannot restore reservations: %w", err)
+		}
+		var r Reservation
+		err = json.Unmarshal(fc, &r)
+		if err != nil {
+			log.WithError(err).WithField("file", e.Name()).Warn("ignoring invalid reservation")
+			continue
+		}
+		m.reservations[r.InstanceID] = &r
+	}
+	m.updateMetrics()
+
+	nodes := make([]string, 0, len(topology))
+	for node, cpus := range topology {
+		nodes = append(nodes, fmt.Sprintf("node%d=%s", node, FormatCPUList(cpus)))
+	}
+	sort.Strings(nodes)
+	log.WithField("nodes", nodes).WithField("reservations", len(m.reservations)).Info("discovered NUMA topology")
+	return nil
+}
+
+// Close stops releasing the reservations of workspaces which stop. Workspaces keep their reservation while
+// ws-daemon is down, and get it back once ws-daemon is up again.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	m.closing = true
+	m.mu.Unlock()
+	return nil
+}
+
+// add pins the workspace in cgroup and gives it huge pages as its policy says. If the workspace has a reservation
+// already, e.g. because ws-daemon restarted, we apply that one.
+func (m *Manager) add(instanceID, class, cgroup string, p *Policy) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := &workspace{Class: class, CGroup: cgroup}
+	m.workspaces[instanceID] = ws
+	defer func() {
+		if err != nil {
+			ws.Reason = err.Error()
+		}
+	}()
+
+	if m.support != nil {
+		return m.support
+	}
+	if p.CPUs > 0 && !fileExists(filepath.Join(cgroup, "cpuset.cpus")) {
+		return &errUnsupported{Reason: "cpuset controller is not enabled for the workspace cgroup"}
+	}
+	if p.HugePages > 0 && !fileExists(filepath.Join(cgroup, hugetlbFile(p.hugePageSize(), "max"))) {
+		return &errUnsupported{Reason: "hugetlb controller is not enabled for the workspace cgroup"}
+	}
+
+	r, exists := m.reservations[instanceID]
+	if !exists {
+		r, err = m.reserve(instanceID, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = applyReservation(cgroup, r)
+	if err != nil {
+		if rerr := m.release(r); rerr != nil {
+			log.WithError(rerr).WithField("instanceId", instanceID).Warn("cannot release reservation - the leak reconciler will try again")
+		}
+		return xerrors.Errorf("cannot apply reservation: %w", err)
+	}
+	return nil
+}
+
+// reserve picks CPUs, grows the huge page pool and persists the reservation. Must be called with mu held.
+func (m *Manager) reserve(instanceID string, p *Policy) (*Reservation, error) {
+	r := &Reservation{
+		InstanceID:  instanceID,
+		Node:        -1,
+		MemoryBound: p.BindMemory,
+	}
+	if p.CPUs > 0 {
+		node, cpus, err := m.selectCPUs(p.CPUs)
+		if err != nil {
+			return nil, err
+		}
+		r.Node, r.CPUs = node, cpus
+	}
+	if p.HugePages > 0 {
+		r.HugePageSize = p.hugePageSize()
+		pages := int64(p.HugePages / r.HugePageSize)
+		err := m.resizePool(r.Node, r.HugePageSize, pages)
+		if err != nil {
+			return nil, err
+		}
+		r.HugePages = pages
+	}
+
+	fc, err := json.Marshal(r)
+	if err == nil {
+		err = os.WriteFile(m.reservationPath(instanceID), fc, 0644)
+	}
+	if err != nil {
+		if r.HugePages > 0 {
+			_ = m.resizePool(r.Node, r.HugePageSize, -r.HugePages)
+		}
+		return nil, xerrors.Errorf("cannot persist reservation: %w", err)
+	}
+
+	m.reservations[instanceID] = r
+	m.updateMetrics()
+	return r, nil
+}
+
+// selectCPUs picks n free CPUs on the NUMA node which has the fewest free CPUs that fit, so that larger
+// workspaces still find room on the other nodes. Must be called with mu held.
+func (m *Manager) selectCPUs(n int) (node int, cpus []int, err error) {
+	used := make(map[int]bool)
+	for _, r := range m.reservations {
+		for _, cpu := range r.CPUs {
+			used[cpu] = true
+		}
+	}
+
+	node = -1
+	var best []int
+	for nd, all := range m.topology {
+		var free []int
+		for _, cpu := range all {
+			if !used[cpu] {
+				free = append(free, cpu)
+			}
+		}
+		if len(free) < n {
+			continue
+		}
+		if node == -1 || len(free) < len(best) || (len(free) == len(best) && nd < node) {
+			node, best = nd, free
+		}
+	}
+	if node == -1 {
+		return -1, nil, &errExhausted{Reason: fmt.Sprintf("no NUMA node has %d free CPUs", n)}
+	}
+	return node, best[:n], nil
+}
+
+// resizePool grows or shrinks the huge page pool of a NUMA node, or of the whole node if numaNode is -1,
+// by pages. The kernel may not find enough contiguous memory to grow the pool, in which case we leave it as it was.
+// Must be called with mu held.
+func (m *Manager) resizePool(numaNode int, size quota.Size, pages int64) error {
+	fn := m.poolPath(numaNode, size)
+	cur, err := readInt(fn)
+	if os.IsNotExist(err) {
+		return &errUnsupported{Reason: fmt.Sprintf("kernel has no %s huge pages", hugetlbSize(size))}
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot read huge page pool: %w", err)
+	}
+
+	want := cur + pages
+	if want < 0 {
+		want = 0
+	}
+	err = os.WriteFile(fn, []byte(strconv.FormatInt(want, 10)), 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot resize huge page pool: %w", err)
+	}
+	if pages <= 0 {
+		return nil
+	}
+
+	got, err := readInt(fn)
+	if err != nil {
+		return xerrors.Errorf("cannot read huge page pool: %w", err)
+	}
+	if got < want {
+		_ = os.WriteFile(fn, []byte(strconv.FormatInt(cur, 10)), 0644)
+		return &errExhausted{Reason: fmt.Sprintf("kernel found memory for %d of %d huge pages", got-cur, pages)}
+	}
+	return nil
+}
+
+func (m *Manager) poolPath(numaNode int, size quota.Size) string {
+	dir := fmt.Sprintf("hugepages-%dkB", size/quota.Kilobyte)
+	if numaNode < 0 {
+		return filepath.Join(m.sysPath, "kernel", "mm", "hugepages", dir, "nr_hugepages")
+	}
+	return filepath.Join(m.sysPath, "devices", "system", "node", fmt.Sprintf("node%d", numaNode), "hugepages", dir, "nr_hugepages")
+}
+
+func (m *Manager) reservationPath(instanceID string) string {
+	return filepath.Join(m.Config.StatePath, instanceID+Suffix)
+}
+
+// Release shrinks the huge page pool by the pages of a workspace instance, frees its CPUs and removes its reservation
+func (m *Manager) Release(instanceID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.workspaces, instanceID)
+	r, ok := m.reservations[instanceID]
+	if !ok {
+		// there's nothing to free, but there may be a file we could not restore
+		err := os.Remove(m.reservationPath(instanceID))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return m.release(r)
+}
+
+// release undoes a reservation. Must be called with mu held.
+func (m *Manager) release(r *Reservation) error {
+	if r.HugePages > 0 {
+		// pages the workspace still uses become surplus pages, which the kernel frees once they're unused
+		err := m.resizePool(r.Node, r.HugePageSize, -r.HugePages)
+		if err != nil {
+			return err
+		}
+		r.HugePages = 0
+	}
+	err := os.Remove(m.reservationPath(r.InstanceID))
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("cannot remove reservation: %w", err)
+	}
+	delete(m.reservations, r.InstanceID)
+	m.updateMetrics()
+	return nil
+}
+
+// forget drops a workspace which did not get a reservation
+func (m *Manager) forget(instanceID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.workspaces, instanceID)
+}
+
+// isClosing returns true once ws-daemon is shutting down
+func (m *Manager) isClosing() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closing
+}
+
+// updateMetrics updates the gauges from the reservations. Must be called with mu held.
+func (m *Manager) updateMetrics() {
+	var cpus int
+	hugePages := make(map[quota.Size]int64)
+	for _, r := range m.reservations {
+		cpus += len(r.CPUs)
+		if r.HugePages > 0 {
+			hugePages[r.HugePageSize] += r.HugePages * int64(r.HugePageSize)
+		}
+	}
+	m.metrics.pinnedCPUs.Set(float64(cpus))
+	for _, size := range []quota.Size{2 * quota.Megabyte, quota.Gigabyte} {
+		m.metrics.hugePages.WithLabelValues(hugetlbSize(size)).Set(float64(hugePages[size]))
+	}
+}
+
+// applyReservation writes a reservation to the cgroup of its workspace
+func applyReservation(cgroup string, r *Reservation) error {
+	var files []cgroupFile
+	if len(r.CPUs) > 0 {
+		files = append(files, cgroupFile{"cpuset.cpus", FormatCPUList(r.CPUs)})
+		if r.MemoryBound {
+			files = append(files, cgroupFile{"cpuset.mems", strconv.Itoa(r.Node)})
+		}
+	}
+	if r.HugePages > 0 {
+		files = append(files, cgroupFile{hugetlbFile(r.HugePageSize, "max"), strconv.FormatInt(r.HugePages*int64(r.HugePageSize), 10)})
+	}
+
+	for _, f := range files {
+		err := os.WriteFile(filepath.Join(cgroup, f.Name), []byte(f.Value), 0644)
+		if err != nil {
+			return xerrors.Errorf("cannot write %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+type cgroupFile struct {
+	Name  string
+	Value string
+}
+
+// hugetlbSize returns the name the kernel uses for a huge page size in the hugetlb controller, e.g. 2MB
+func hugetlbSize(size quota.Size) string {
+	if size >= quota.Gigabyte {
+		return fmt.Sprintf("%dGB", size/quota.Gigabyte)
+	}
+	return fmt.Sprintf("%dMB", size/quota.Megabyte)
+}
+
+// hugetlbFile returns the name of a hugetlb controller file, e.g. hugetlb.2MB.max
+func hugetlbFile(size quota.Size, name string) string {
+	return "hugetlb." + hugetlbSize(size) + "." + name
+}
+
+// readTopology returns the online CPUs of every NUMA node. Nodes without NUMA support are a single node 0.
+func readTopology(sysPath string) (map[int][]int, error) {
+	base := filepath.Join(sysPath, "devices", "system", "node")
+	entries, err := os.ReadDir(base)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	res := make(map[int][]int)
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "node") {
+			continue
+		}
+		node, err := strconv.Atoi(strings.TrimPrefix(e.Name(), "node"))
+		if err != nil {
+			continue
+		}
+		fc, err := os.ReadFile(filepath.Join(base, e.Name(), "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		cpus, err := ParseCPUList(string(fc))
+		if err != nil {
+			return nil, xerrors.Errorf("node %d: %w", node, err)
+		}
+		if len(cpus) > 0 {
+			res[node] = cpus
+		}
+	}
+	if len(res) > 0 {
+		return res, nil
+	}
+
+	fc, err := os.ReadFile(filepath.Join(sysPath, "devices", "system", "cpu", "online"))
+	if err != nil {
+		return nil, err
+	}
+	cpus, err := ParseCPUList(string(fc))
+	if err != nil {
+		return nil, err
+	}
+	return map[int][]int{0: cpus}, nil
+}
+
+// ParseCPUList parses a list of CPUs in cpuset list format, e.g. 0-3,8,10-11
+func ParseCPUList(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	var res []int
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, xerrors.Errorf("invalid CPU list %q", s)
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+			if err != nil || last < first {
+				return nil, xerrors.Errorf("invalid CPU list %q", s)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			res = append(res, cpu)
+		}
+	}
+	sort.Ints(res)
+	return res, nil
+}
+
+// FormatCPUList formats CPUs in cpuset list format, e.g. 0-3,8
+func FormatCPUList(cpus []int) string {
+	sorted := append([]int(nil), cpus...)
+	sort.Ints(sorted)
+
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+func without(cpus, exclude []int) []int {
+	ex := make(map[int]bool, len(exclude))
+	for _, cpu := range exclude {
+		ex[cpu] = true
+	}
+	var res []int
+	for _, cpu := range cpus {
+		if !ex[cpu] {
+			res = append(res, cpu)
+		}
+	}
+	return res
+}
+
+func readInt(fn string) (int64, error) {
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(fc)), 10, 64)
+}
+
+func fileExists(fn string) bool {
+	_, err := os.Stat(fn)
+	return err == nil
+}
+
+const (
+	outcomeApplied     = "applied"
+	outcomeUnsupported = "unsupported"
+	outcomeExhausted   = "exhausted"
+	outcomeFailed      = "failed"
+)
+
+type metrics struct {
+	workspaces *prometheus.CounterVec
+	pinnedCPUs prometheus.Gauge
+	hugePages  *prometheus.GaugeVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		workspaces: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "performance_class_workspaces_total",
+			Help: "Workspaces of performance classes by outcome: applied, unsupported, exhausted or failed",
+		}, []string{"outcome"}),
+		pinnedCPUs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "performance_class_pinned_cpus",
+			Help: "CPUs pinned to workspaces",
+		}),
+		hugePages: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "performance_class_huge_pages_bytes",
+			Help: "Memory in huge pages we added to the node's pool for workspaces, by page size",
+		}, []string{"size"}),
+	}
+}
+
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.workspaces, m.pinnedCPUs, m.hugePages}
+}
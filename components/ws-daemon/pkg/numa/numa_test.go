@@ -0,0 +1,318 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package numa
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/ws-daemon/api"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+const (
+	instanceA = "a6ad8fb2-37f3-4f4c-9e1b-9be0f6b9a5a1"
+	instanceB = "b3c0e7f1-54a2-4b7e-8f4d-0c1e2d3f4a5b"
+	instanceC = "c9d8e7f6-1a2b-4c3d-8e9f-0a1b2c3d4e5f"
+)
+
+func TestConfigValidate(t *testing.T) {
+	valid := func() Config {
+		return Config{
+			Enabled:        true,
+			StatePath:      "/mnt/workingarea/.performance",
+			CGroupBasePath: "/mnt/node-cgroups",
+			ReservedCPUs:   "0-1",
+			Classes:        map[string]Policy{"benchmark": {CPUs: 8, BindMemory: true, HugePages: 4 * quota.Gigabyte}},
+		}
+	}
+	tests := []struct {
+		Name   string
+		Modify func(*Config)
+		Valid  bool
+	}{
+		{Name: "valid", Modify: func(c *Config) {}, Valid: true},
+		{Name: "disabled", Modify: func(c *Config) { *c = Config{} }, Valid: true},
+		{Name: "1g pages", Modify: func(c *Config) {
+			c.Classes["benchmark"] = Policy{HugePages: 2 * quota.Gigabyte, HugePageSize: quota.Gigabyte}
+		}, Valid: true},
+		{Name: "no state path", Modify: func(c *Config) { c.StatePath = "" }},
+		{Name: "no cgroup base path", Modify: func(c *Config) { c.CGroupBasePath = "" }},
+		{Name: "invalid reserved CPUs", Modify: func(c *Config) { c.ReservedCPUs = "1-0" }},
+		{Name: "empty policy", Modify: func(c *Config) { c.Classes["benchmark"] = Policy{} }},
+		{Name: "memory binding without CPUs", Modify: func(c *Config) { c.Classes["benchmark"] = Policy{BindMemory: true, HugePages: quota.Gigabyte} }},
+		{Name: "unknown page size", Modify: func(c *Config) {
+			c.Classes["benchmark"] = Policy{HugePages: quota.Gigabyte, HugePageSize: 4 * quota.Megabyte}
+		}},
+		{Name: "partial page", Modify: func(c *Config) { c.Classes["benchmark"] = Policy{HugePages: 3 * quota.Megabyte} }},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := valid()
+			test.Modify(&cfg)
+			err := cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCPUList(t *testing.T) {
+	tests := []struct {
+		Input     string
+		Expected  []int
+		Formatted string
+	}{
+		{Input: "", Formatted: ""},
+		{Input: "3", Expected: []int{3}, Formatted: "3"},
+		{Input: "0-3,8,10-11\n", Expected: []int{0, 1, 2, 3, 8, 10, 11}, Formatted: "0-3,8,10-11"},
+		{Input: "8,0-1", Expected: []int{0, 1, 8}, Formatted: "0-1,8"},
+	}
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			act, err := ParseCPUList(test.Input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected CPUs (-want +got):\n%s", diff)
+			}
+			if f := FormatCPUList(act); f != test.Formatted {
+				t.Errorf("FormatCPUList() = %q, expected %q", f, test.Formatted)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"a", "3-1", "1,,2"} {
+		if _, err := ParseCPUList(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+type fakeNode struct {
+	Sys    string
+	CGroup string
+	State  string
+}
+
+// newFakeNode creates the sys and cgroup files of a node with two NUMA nodes of four CPUs each
+func newFakeNode(t *testing.T) *fakeNode {
+	base := t.TempDir()
+	n := &fakeNode{
+		Sys:    filepath.Join(base, "sys"),
+		CGroup: filepath.Join(base, "cgroup"),
+		State:  filepath.Join(base, "state"),
+	}
+	files := map[string]string{
+		filepath.Join(n.CGroup, "cgroup.controllers"):                                             "cpuset cpu io memory hugetlb pids",
+		filepath.Join(n.Sys, "devices/system/node/node0/cpulist"):                                 "0-3\n",
+		filepath.Join(n.Sys, "devices/system/node/node1/cpulist"):                                 "4-7\n",
+		filepath.Join(n.Sys, "devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages"): "0\n",
+		filepath.Join(n.Sys, "devices/system/node/node1/hugepages/hugepages-2048kB/nr_hugepages"): "16\n",
+		filepath.Join(n.Sys, "kernel/mm/hugepages/hugepages-2048kB/nr_hugepages"):                 "16\n",
+	}
+	for fn, content := range files {
+		err := os.MkdirAll(filepath.Dir(fn), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fn, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return n
+}
+
+// Workspace creates the cgroup of a workspace container
+func (n *fakeNode) Workspace(t *testing.T, instanceID string) string {
+	cgroup := filepath.Join(n.CGroup, instanceID)
+	err := os.MkdirAll(cgroup, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range []string{"cpuset.cpus", "cpuset.mems", "hugetlb.2MB.max", "hugetlb.2MB.current"} {
+		err = os.WriteFile(filepath.Join(cgroup, fn), nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cgroup
+}
+
+func (n *fakeNode) Manager(t *testing.T) *Manager {
+	m := NewManager(Config{
+		Enabled:        true,
+		StatePath:      n.State,
+		CGroupBasePath: n.CGroup,
+		SysPath:        n.Sys,
+		ReservedCPUs:   "0",
+	})
+	err := m.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func readFile(t *testing.T, fn string) string {
+	t.Helper()
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(fc))
+}
+
+func TestAddAndRelease(t *testing.T) {
+	node := newFakeNode(t)
+	m := node.Manager(t)
+	policy := &Policy{CPUs: 3, BindMemory: true, HugePages: 8 * quota.Megabyte}
+	pool0 := filepath.Join(node.Sys, "devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages")
+
+	// node 0 has three CPUs left after reserving CPU 0, which is the best fit
+	cgroupA := node.Workspace(t, instanceA)
+	err := m.add(instanceA, "benchmark", cgroupA, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for fn, expected := range map[string]string{
+		filepath.Join(cgroupA, "cpuset.cpus"):     "1-3",
+		filepath.Join(cgroupA, "cpuset.mems"):     "0",
+		filepath.Join(cgroupA, "hugetlb.2MB.max"): "8388608",
+		pool0: "4",
+	} {
+		if act := readFile(t, fn); act != expected {
+			t.Errorf("%s = %q, expected %q", filepath.Base(fn), act, expected)
+		}
+	}
+
+	cgroupB := node.Workspace(t, instanceB)
+	err = m.add(instanceB, "benchmark", cgroupB, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := readFile(t, filepath.Join(cgroupB, "cpuset.cpus")); act != "4-6" {
+		t.Errorf("second workspace got CPUs %q, expected 4-6", act)
+	}
+
+	// no NUMA node has three free CPUs anymore
+	cgroupC := node.Workspace(t, instanceC)
+	err = m.add(instanceC, "benchmark", cgroupC, policy)
+	if _, ok := err.(*errExhausted); !ok {
+		t.Fatalf("expected the node to be exhausted, got %v", err)
+	}
+	sts, err := m.GetPerformanceStatus(context.Background(), &api.GetPerformanceStatusRequest{Id: instanceC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sts.NumaNode != -1 || sts.UnavailableReason == "" {
+		t.Errorf("expected the status to report why the workspace is not pinned, got %v", sts)
+	}
+
+	err = os.WriteFile(filepath.Join(cgroupA, "hugetlb.2MB.current"), []byte("2097152\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sts, err = m.GetPerformanceStatus(context.Background(), &api.GetPerformanceStatusRequest{Id: instanceA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &api.GetPerformanceStatusResponse{
+		WorkspaceClass: "benchmark",
+		NumaNode:       0,
+		Cpus:           "1-3",
+		MemoryBound:    true,
+		HugePageSize:   2097152,
+		HugePagesLimit: 8388608,
+		HugePagesUsed:  2097152,
+	}
+	if diff := cmp.Diff(expected.String(), sts.String()); diff != "" {
+		t.Errorf("unexpected status (-want +got):\n%s", diff)
+	}
+
+	// once the first workspace is gone, its CPUs and huge pages are free again
+	err = m.Release(instanceA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := readFile(t, pool0); act != "0" {
+		t.Errorf("huge page pool of node 0 has %s pages, expected 0", act)
+	}
+	if _, err := os.Stat(filepath.Join(node.State, instanceA+Suffix)); !os.IsNotExist(err) {
+		t.Errorf("expected the reservation file to be removed, got %v", err)
+	}
+	if _, err := m.GetPerformanceStatus(context.Background(), &api.GetPerformanceStatusRequest{Id: instanceA}); err == nil {
+		t.Error("expected no status for a released workspace")
+	}
+	err = m.add(instanceC, "benchmark", cgroupC, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := readFile(t, filepath.Join(cgroupC, "cpuset.cpus")); act != "1-3" {
+		t.Errorf("workspace got CPUs %q, expected the ones of the released workspace", act)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	node := newFakeNode(t)
+	policy := &Policy{CPUs: 2, HugePages: 4 * quota.Megabyte}
+	pool0 := filepath.Join(node.Sys, "devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages")
+
+	m := node.Manager(t)
+	cgroupA := node.Workspace(t, instanceA)
+	err := m.add(instanceA, "benchmark", cgroupA, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = m.Close()
+
+	// after the restart, workspaces which start before the running ones are added again must not get their CPUs
+	m = node.Manager(t)
+	cgroupB := node.Workspace(t, instanceB)
+	err = m.add(instanceB, "benchmark", cgroupB, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := readFile(t, filepath.Join(cgroupB, "cpuset.cpus")); act != "4-5" {
+		t.Errorf("new workspace got CPUs %q, expected 4-5", act)
+	}
+
+	// the running workspace keeps its reservation and we do not grow the pool twice
+	err = m.add(instanceA, "benchmark", cgroupA, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := readFile(t, filepath.Join(cgroupA, "cpuset.cpus")); act != "1-2" {
+		t.Errorf("running workspace has CPUs %q, expected 1-2", act)
+	}
+	if act := readFile(t, pool0); act != "2" {
+		t.Errorf("huge page pool of node 0 has %s pages, expected 2", act)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	node := newFakeNode(t)
+	err := os.Remove(filepath.Join(node.CGroup, "cgroup.controllers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := node.Manager(t)
+	err = m.add(instanceA, "benchmark", node.Workspace(t, instanceA), &Policy{CPUs: 2})
+	if _, ok := err.(*errUnsupported); !ok {
+		t.Errorf("expected pinning to be unsupported without cgroup v2, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package swapfile
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/dispatch"
+)
+
+// DispatchListener gives a workspace its swap file once its container is running, and removes it once the
+// workspace is gone
+type DispatchListener struct {
+	Manager *Manager
+}
+
+// WorkspaceAdded creates the swap file of the workspace and samples its usage until the workspace is gone.
+// If the node cannot give the workspace a swap file, the workspace runs without.
+func (d *DispatchListener) WorkspaceAdded(ctx context.Context, ws *dispatch.Workspace) error {
+	class := ws.Pod.Labels[wsk8s.TypeLabel]
+	policy := d.Manager.Config.PolicyFor(class)
+	if policy == nil {
+		return nil
+	}
+
+	cgroup, err := dispatch.WorkspaceCGroup(ctx, ws, d.Manager.Config.CGroupBasePath)
+	if err != nil {
+		return err
+	}
+
+	log := log.WithFields(ws.OWI()).WithField("class", class)
+	err = d.Manager.add(ws.InstanceID, cgroup, policy)
+	switch err.(type) {
+	case nil:
+	case *errUnsupported:
+		d.Manager.metrics.workspaces.WithLabelValues(outcomeUnsupported).Inc()
+		log.WithError(err).Warn("workspace runs without swap file")
+		return nil
+	case *errExhausted:
+		d.Manager.metrics.workspaces.WithLabelValues(outcomeExhausted).Inc()
+		log.WithError(err).Warn("workspace runs without swap file")
+		return nil
+	default:
+		d.Manager.metrics.workspaces.WithLabelValues(outcomeFailed).Inc()
+		return xerrors.Errorf("cannot give workspace a swap file: %w", err)
+	}
+	d.Manager.metrics.workspaces.WithLabelValues(outcomeApplied).Inc()
+	log.WithField("size", int64(policy.Size)).Info("workspace has a swap file")
+
+	go d.Manager.watch(ctx, ws.InstanceID, cgroup, policy, log)
+	return nil
+}
+
+// watch samples the swap usage of a workspace until ctx is done, and removes its swap file then
+func (m *Manager) watch(ctx context.Context, instanceID, cgroup string, p *Policy, log *logrus.Entry) {
+	m.sample(ctx, cgroup, p, log)
+
+	if m.isClosing() {
+		// ws-daemon is shutting down, not the workspace
+		return
+	}
+	err := m.remove(instanceID)
+	if err != nil {
+		m.metrics.removals.WithLabelValues("failed").Inc()
+		log.WithError(err).Error("cannot remove swap file - the leak reconciler will try again")
+		return
+	}
+	m.metrics.removals.WithLabelValues("removed").Inc()
+	log.Debug("removed swap file")
+}
+
+// sample samples how much of its swap file a workspace uses, and how many pages it swaps out, until ctx is done
+func (m *Manager) sample(ctx context.Context, cgroup string, p *Policy, log *logrus.Entry) {
+	t := time.NewTicker(m.sampleInterval())
+	defer t.Stop()
+
+	var swapouts uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		used, err := readSwapCurrent(cgroup)
+		if os.IsNotExist(err) {
+			// the workspace is going away - we remove the swap file once ctx is done
+			continue
+		}
+		if err != nil {
+			log.WithError(err).Debug("cannot sample swap usage")
+			continue
+		}
+		m.metrics.usage.Observe(float64(used) / float64(p.Size))
+
+		if v, ok := readSwapOuts(cgroup); ok {
+			if v > swapouts {
+				m.metrics.swapouts.Add(float64(v - swapouts))
+			}
+			swapouts = v
+		}
+	}
+}
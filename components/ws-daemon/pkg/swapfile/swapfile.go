@@ -0,0 +1,466 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package swapfile gives workspaces a bounded amount of swap on the node's local disk. Instead of raising their
+// memory limit, workspaces whose class has a policy can swap out up to the policy's size of their memory when
+// they hit their limit, smoothing short memory spikes.
+//
+// For every such workspace ws-daemon creates a swap file of that size, e.g. on the node's local NVMe, and limits
+// the workspace's swap usage to the same size using the cgroup v2 memory controller. The kernel does not bind swap
+// files to cgroups, so the files form a node-wide pool which grows and shrinks with the workspaces that use it,
+// while the cgroup limits make sure no workspace swaps more than its share. The file is removed once the workspace
+// stops. Files which outlive their workspace, e.g. because the workspace stopped while ws-daemon was down, are
+// removed by the leak reconciler.
+package swapfile
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/arch"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+const (
+	// Suffix is the suffix of the swap files in the configured location, which are named after workspace instances
+	Suffix = ".swap"
+
+	// MinSize is the smallest swap file we create. The kernel needs at least ten pages, but a swap file
+	// which does not hold a meaningful part of a workspace's memory isn't worth the disk I/O.
+	MinSize = 16 * quota.Megabyte
+
+	defaultSampleInterval = 30 * time.Second
+)
+
+// Config configures the swap files of workspaces
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// Default is the policy of workspaces whose class has no policy. If not set, such workspaces get no swap file.
+	Default *Policy `json:"default,omitempty"`
+	// Classes maps workspace classes to their policy. The class of a workspace is its type, e.g. regular or prebuild.
+	Classes map[string]Policy `json:"classes,omitempty"`
+	// Location is the directory ws-daemon creates the swap files in. It should be on fast local disk, e.g. NVMe,
+	// and must be on a filesystem the kernel can swap to, e.g. ext4 or xfs.
+	Location string `json:"location"`
+	// MaxTotal is the combined size of all swap files on the node. Workspaces which would exceed it run without
+	// swap file. Zero means the swap files are limited by the free space in Location only.
+	MaxTotal quota.Size `json:"maxTotal,omitempty"`
+	// CGroupBasePath is where ws-daemon sees the node's cgroup v2 filesystem
+	CGroupBasePath string `json:"cgroupBasePath"`
+	// SampleInterval is how often we sample the swap usage of workspaces. Defaults to 30 seconds.
+	SampleInterval util.Duration `json:"sampleInterval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Location == "" {
+		return xerrors.Errorf("location is required")
+	}
+	if c.CGroupBasePath == "" {
+		return xerrors.Errorf("cgroupBasePath is required")
+	}
+	if c.MaxTotal < 0 {
+		return xerrors.Errorf("maxTotal must not be negative")
+	}
+	if c.Default != nil {
+		if err := c.Default.Validate(); err != nil {
+			return xerrors.Errorf("default: %w", err)
+		}
+	}
+	for class, p := range c.Classes {
+		if err := p.Validate(); err != nil {
+			return xerrors.Errorf("classes.%s: %w", class, err)
+		}
+		if c.MaxTotal > 0 && p.Size > c.MaxTotal {
+			return xerrors.Errorf("classes.%s: size exceeds maxTotal", class)
+		}
+	}
+	if c.SampleInterval < 0 {
+		return xerrors.Errorf("sampleInterval must not be negative")
+	}
+	return nil
+}
+
+// PolicyFor returns the policy of a workspace class, or nil if the workspace gets no swap file
+func (c *Config) PolicyFor(class string) *Policy {
+	if p, ok := c.Classes[class]; ok {
+		return &p
+	}
+	return c.Default
+}
+
+// Policy is how much swap a class of workspaces gets
+type Policy struct {
+	// Size is the size of the swap file, and how much of its memory a workspace may swap out
+	Size quota.Size `json:"size"`
+}
+
+// Validate validates the policy
+func (p *Policy) Validate() error {
+	if p.Size < MinSize {
+		return xerrors.Errorf("size must be at least %s", MinSize)
+	}
+	return nil
+}
+
+// errUnsupported is returned when a workspace cannot get a swap file on this node
+type errUnsupported struct {
+	Reason string
+}
+
+func (e *errUnsupported) Error() string {
+	return "swap files are not supported: " + e.Reason
+}
+
+// errExhausted is returned when there's no room for another swap file on this node
+type errExhausted struct {
+	Reason string
+}
+
+func (e *errExhausted) Error() string {
+	return "no room for swap file: " + e.Reason
+}
+
+// Manager creates, accounts and removes the swap files of workspaces
+type Manager struct {
+	Config Config
+
+	mu        sync.Mutex
+	files     map[string]int64
+	allocated int64
+	closing   bool
+
+	support error
+	metrics *metrics
+	swapOn  func(path string) error
+	swapOff func(path string) error
+}
+
+// NewManager creates a new swap file manager
+func NewManager(cfg Config) *Manager {
+	return &Manager{
+		Config:  cfg,
+		files:   make(map[string]int64),
+		metrics: newMetrics(),
+		swapOn:  swapOn,
+		swapOff: SwapOff,
+	}
+}
+
+// RegisterMetrics registers the swap file metrics
+func (m *Manager) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range m.metrics.collectors() {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start finds out if the node supports swap files for workspaces. If it does not, workspaces run without.
+func (m *Manager) Start() {
+	m.support = m.checkSupport()
+	if m.support != nil {
+		log.WithError(m.support).Warn("workspaces cannot use swap files")
+	}
+}
+
+// Close stops removing the swap files of workspaces which stop. Workspaces keep their swap file while ws-daemon
+// is down, and get it back once ws-daemon is up again.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	m.closing = true
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Manager) checkSupport() error {
+	if _, err := os.Stat(filepath.Join(m.Config.CGroupBasePath, "cgroup.controllers")); err != nil {
+		return &errUnsupported{Reason: "node does not use cgroup v2"}
+	}
+	err := os.MkdirAll(m.Config.Location, 0700)
+	if err != nil {
+		return xerrors.Errorf("cannot create swap file location: %w", err)
+	}
+	return nil
+}
+
+// Path returns the path of a workspace's swap file
+func (m *Manager) Path(instanceID string) string {
+	return filepath.Join(m.Config.Location, instanceID+Suffix)
+}
+
+// add gives the workspace in cgroup a swap file, and allows it to swap up to the policy's size of its memory.
+// If the workspace has a swap file already, e.g. because ws-daemon restarted, we use that one.
+func (m *Manager) add(instanceID, cgroup string, p *Policy) (err error) {
+	if m.support != nil {
+		return m.support
+	}
+	swapMax := filepath.Join(cgroup, "memory.swap.max")
+	if _, err := os.Stat(swapMax); err != nil {
+		return &errUnsupported{Reason: "no swap accounting for workspace cgroup"}
+	}
+
+	size := int64(p.Size)
+	err = m.reserve(instanceID, size)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			m.release(instanceID)
+		}
+	}()
+
+	fn := m.Path(instanceID)
+	err = m.swapOn(fn)
+	if err == unix.EBUSY {
+		// the kernel swaps to this file already
+		err = nil
+	} else if err != nil {
+		// there's no usable swap file yet - if there's a file at all, it's from a failed attempt
+		_ = os.Remove(fn)
+		err = createSwapFile(fn, size)
+		if err == unix.ENOSPC {
+			return &errExhausted{Reason: "not enough space in " + m.Config.Location}
+		}
+		if err != nil {
+			_ = os.Remove(fn)
+			return xerrors.Errorf("cannot create swap file: %w", err)
+		}
+		err = m.swapOn(fn)
+		if err != nil {
+			_ = os.Remove(fn)
+			return xerrors.Errorf("cannot swap to swap file: %w", err)
+		}
+	}
+
+	err = os.WriteFile(swapMax, []byte(strconv.FormatInt(size, 10)), 0644)
+	if err != nil {
+		_ = m.remove(instanceID)
+		return xerrors.Errorf("cannot write %s: %w", filepath.Base(swapMax), err)
+	}
+	return nil
+}
+
+// remove stops swapping to a workspace's swap file and removes it
+func (m *Manager) remove(instanceID string) error {
+	defer m.release(instanceID)
+
+	fn := m.Path(instanceID)
+	err := m.swapOff(fn)
+	if err != nil {
+		return xerrors.Errorf("cannot stop swapping to swap file: %w", err)
+	}
+	err = os.Remove(fn)
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("cannot remove swap file: %w", err)
+	}
+	return nil
+}
+
+// reserve accounts the swap file of a workspace against the node's budget
+func (m *Manager) reserve(instanceID string, size int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.files[instanceID]; exists {
+		return xerrors.Errorf("workspace has a swap file already")
+	}
+	if m.Config.MaxTotal > 0 && m.allocated+size > int64(m.Config.MaxTotal) {
+		return &errExhausted{Reason: "node swap file budget is used up"}
+	}
+	m.files[instanceID] = size
+	m.allocated += size
+	m.metrics.allocated.Set(float64(m.allocated))
+	return nil
+}
+
+// release returns the swap file of a workspace to the node's budget
+func (m *Manager) release(instanceID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	size, exists := m.files[instanceID]
+	if !exists {
+		return
+	}
+	delete(m.files, instanceID)
+	m.allocated -= size
+	m.metrics.allocated.Set(float64(m.allocated))
+}
+
+// isClosing returns true once ws-daemon is shutting down
+func (m *Manager) isClosing() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closing
+}
+
+func (m *Manager) sampleInterval() time.Duration {
+	if m.Config.SampleInterval > 0 {
+		return time.Duration(m.Config.SampleInterval)
+	}
+	return defaultSampleInterval
+}
+
+// createSwapFile creates a swap file of the given size. The file must not have holes, hence we allocate
+// its blocks rather than truncating it.
+func createSwapFile(fn string, size int64) error {
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = unix.Fallocate(int(f.Fd()), 0, 0, size)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteAt(swapHeader(size, os.Getpagesize()), 0)
+	if err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// swapHeader returns the first page of a swap area of the given size, as mkswap writes it in the node's byte order
+func swapHeader(size int64, pageSize int) []byte {
+	const (
+		versionOffset  = 1024
+		lastPageOffset = versionOffset + 4
+		uuidOffset     = lastPageOffset + 4 + 4 // nr_badpages is zero
+		labelOffset    = uuidOffset + 16
+	)
+	var (
+		hdr   = make([]byte, pageSize)
+		order = arch.ByteOrder()
+	)
+	order.PutUint32(hdr[versionOffset:], 1)
+	order.PutUint32(hdr[lastPageOffset:], uint32(size/int64(pageSize)-1))
+	uuid := hdr[uuidOffset : uuidOffset+16]
+	_, _ = rand.Read(uuid)
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	copy(hdr[labelOffset:labelOffset+16], "gitpod-workspace")
+	copy(hdr[pageSize-10:], "SWAPSPACE2")
+	return hdr
+}
+
+// swapOn makes the kernel swap to a swap file
+func swapOn(fn string) error {
+	p, err := unix.BytePtrFromString(fn)
+	if err != nil {
+		return err
+	}
+	_, _, errno := unix.Syscall(unix.SYS_SWAPON, uintptr(unsafe.Pointer(p)), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// SwapOff makes the kernel stop swapping to a swap file. The kernel swaps pages in use back in first.
+// Swap files the kernel does not swap to are fine.
+func SwapOff(fn string) error {
+	p, err := unix.BytePtrFromString(fn)
+	if err != nil {
+		return err
+	}
+	_, _, errno := unix.Syscall(unix.SYS_SWAPOFF, uintptr(unsafe.Pointer(p)), 0, 0)
+	if errno == unix.EINVAL || errno == unix.ENOENT {
+		return nil
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// readSwapCurrent returns how much of its memory a workspace has swapped out
+func readSwapCurrent(cgroup string) (int64, error) {
+	c, err := os.ReadFile(filepath.Join(cgroup, "memory.swap.current"))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(c)), 10, 64)
+}
+
+// readSwapOuts returns how many pages a workspace has swapped out, if the kernel accounts that per cgroup
+func readSwapOuts(cgroup string) (uint64, bool) {
+	c, err := os.ReadFile(filepath.Join(cgroup, "memory.stat"))
+	if err != nil {
+		return 0, false
+	}
+	for _, line := range strings.Split(string(c), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "pswpout" {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		return v, err == nil
+	}
+	return 0, false
+}
+
+const (
+	outcomeApplied     = "applied"
+	outcomeUnsupported = "unsupported"
+	outcomeExhausted   = "exhausted"
+	outcomeFailed      = "failed"
+)
+
+type metrics struct {
+	workspaces *prometheus.CounterVec
+	allocated  prometheus.Gauge
+	usage      prometheus.Histogram
+	swapouts   prometheus.Counter
+	removals   *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		workspaces: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "swapfile_workspaces_total",
+			Help: "Workspaces with a swap file policy by outcome: applied, unsupported, exhausted or failed",
+		}, []string{"outcome"}),
+		allocated: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "swapfile_allocated_bytes",
+			Help: "Combined size of the swap files of the workspaces on the node",
+		}),
+		usage: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "swapfile_usage_ratio",
+			Help:    "Share of their swap file workspaces use, sampled per workspace",
+			Buckets: []float64{0, 0.05, 0.1, 0.25, 0.5, 0.75, 0.9, 1},
+		}),
+		swapouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "swapfile_swapouts_total",
+			Help: "Pages workspaces with a swap file swapped out",
+		}),
+		removals: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "swapfile_removals_total",
+			Help: "Swap files removed once their workspace stopped by outcome: removed or failed",
+		}, []string{"outcome"}),
+	}
+}
+
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.workspaces, m.allocated, m.usage, m.swapouts, m.removals}
+}
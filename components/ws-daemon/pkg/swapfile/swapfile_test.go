@@ -0,0 +1,175 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package swapfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/arch"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/quota"
+)
+
+func TestConfigValidate(t *testing.T) {
+	valid := func() Config {
+		return Config{
+			Enabled:        true,
+			Location:       "/mnt/swapfiles",
+			CGroupBasePath: "/mnt/node-cgroups",
+			MaxTotal:       4 * quota.Gigabyte,
+			Classes:        map[string]Policy{"regular": {Size: quota.Gigabyte}},
+		}
+	}
+	tests := []struct {
+		Name   string
+		Modify func(*Config)
+		Valid  bool
+	}{
+		{Name: "valid", Modify: func(c *Config) {}, Valid: true},
+		{Name: "disabled", Modify: func(c *Config) { *c = Config{} }, Valid: true},
+		{Name: "no budget", Modify: func(c *Config) { c.MaxTotal = 0 }, Valid: true},
+		{Name: "no location", Modify: func(c *Config) { c.Location = "" }},
+		{Name: "no cgroup base path", Modify: func(c *Config) { c.CGroupBasePath = "" }},
+		{Name: "policy too small", Modify: func(c *Config) { c.Default = &Policy{Size: quota.Megabyte} }},
+		{Name: "policy exceeds budget", Modify: func(c *Config) { c.Classes["regular"] = Policy{Size: 8 * quota.Gigabyte} }},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := valid()
+			test.Modify(&cfg)
+			err := cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestSwapHeader(t *testing.T) {
+	const pageSize = 4096
+	hdr := swapHeader(int64(64*quota.Megabyte), pageSize)
+	if len(hdr) != pageSize {
+		t.Fatalf("expected header of one page, got %d bytes", len(hdr))
+	}
+	if sig := string(hdr[pageSize-10:]); sig != "SWAPSPACE2" {
+		t.Errorf("unexpected signature %q", sig)
+	}
+	if v := arch.ByteOrder().Uint32(hdr[1024:]); v != 1 {
+		t.Errorf("unexpected version %d", v)
+	}
+	if lastPage := arch.ByteOrder().Uint32(hdr[1028:]); lastPage != 64*256-1 {
+		t.Errorf("unexpected last page %d", lastPage)
+	}
+	if badPages := arch.ByteOrder().Uint32(hdr[1032:]); badPages != 0 {
+		t.Errorf("unexpected bad pages %d", badPages)
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	base := t.TempDir()
+	cgroups := filepath.Join(base, "cgroups")
+	newCGroup := func(name string) string {
+		cg := filepath.Join(cgroups, name)
+		err := os.MkdirAll(cg, 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(cg, "memory.swap.max"), []byte("max"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cg
+	}
+	if err := os.MkdirAll(cgroups, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cgroups, "cgroup.controllers"), []byte("memory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	active := make(map[string]bool)
+	m := NewManager(Config{
+		Enabled:        true,
+		Location:       filepath.Join(base, "swap"),
+		CGroupBasePath: cgroups,
+		MaxTotal:       48 * quota.Megabyte,
+	})
+	m.swapOn = func(path string) error {
+		if _, err := os.Stat(path); err != nil {
+			return unix.ENOENT
+		}
+		if active[path] {
+			return unix.EBUSY
+		}
+		active[path] = true
+		return nil
+	}
+	m.swapOff = func(path string) error {
+		delete(active, path)
+		return nil
+	}
+	m.Start()
+	if m.support != nil {
+		t.Fatal(m.support)
+	}
+
+	policy := &Policy{Size: 32 * quota.Megabyte}
+	cg := newCGroup("ws-a")
+	err := m.add("a", cg, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !active[m.Path("a")] {
+		t.Error("expected the kernel to swap to the swap file")
+	}
+	if swapMax, _ := os.ReadFile(filepath.Join(cg, "memory.swap.max")); string(swapMax) != "33554432" {
+		t.Errorf("unexpected memory.swap.max %q", swapMax)
+	}
+	if stat, err := os.Stat(m.Path("a")); err != nil || stat.Size() != int64(policy.Size) || stat.Mode().Perm() != 0600 {
+		t.Errorf("unexpected swap file: %v, %v", stat, err)
+	}
+
+	err = m.add("b", newCGroup("ws-b"), policy)
+	if _, ok := err.(*errExhausted); !ok {
+		t.Errorf("expected the node's budget to be used up, got %v", err)
+	}
+	if _, err := os.Stat(m.Path("b")); !os.IsNotExist(err) {
+		t.Errorf("expected no swap file for b, got %v", err)
+	}
+
+	// ws-daemon restarted
+	m.release("a")
+	err = m.add("a", cg, policy)
+	if err != nil {
+		t.Fatalf("cannot reuse swap file: %v", err)
+	}
+
+	err = m.remove("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(m.Path("a")); !os.IsNotExist(err) || active[m.Path("a")] {
+		t.Errorf("expected swap file to be gone, got %v", err)
+	}
+	if m.allocated != 0 {
+		t.Errorf("expected no allocated swap, got %d", m.allocated)
+	}
+
+	err = m.add("b", newCGroup("ws-b"), policy)
+	if err != nil {
+		t.Errorf("expected room for b once a is gone: %v", err)
+	}
+
+	err = m.add("c", filepath.Join(base, "cgroup-v1"), &Policy{Size: MinSize})
+	if _, ok := err.(*errUnsupported); !ok {
+		t.Errorf("expected cgroup without swap accounting to be unsupported, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package api
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// AuditActorMetadataKey is the request metadata which tells ws-manager who asked for a control action
+	AuditActorMetadataKey = "x-gitpod-audit-actor"
+	// AuditSourceMetadataKey is the request metadata which tells ws-manager which component asked for a control action
+	AuditSourceMetadataKey = "x-gitpod-audit-source"
+)
+
+// WithAuditActor attributes the ws-manager calls made with the returned context to an actor (e.g. a user ID)
+// and the component acting on their behalf. ws-manager records both in its audit log.
+func WithAuditActor(ctx context.Context, actor, source string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, AuditActorMetadataKey, actor, AuditSourceMetadataKey, source)
+}
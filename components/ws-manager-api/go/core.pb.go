@@ -26,6 +26,58 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// StartWorkspaceErrorDomain classifies why a workspace could not be started
+type StartWorkspaceErrorDomain int32
+
+const (
+	// START_ERROR_UNKNOWN are failures we cannot attribute to any other domain
+	StartWorkspaceErrorDomain_START_ERROR_UNKNOWN StartWorkspaceErrorDomain = 0
+	// START_ERROR_INVALID_REQUEST means the request itself is invalid, e.g. because it lacks required fields
+	StartWorkspaceErrorDomain_START_ERROR_INVALID_REQUEST StartWorkspaceErrorDomain = 1
+	// START_ERROR_CONFLICT means a workspace with the same ID exists already
+	StartWorkspaceErrorDomain_START_ERROR_CONFLICT StartWorkspaceErrorDomain = 2
+	// START_ERROR_QUOTA means the resource quota of the workspace namespace is exhausted
+	StartWorkspaceErrorDomain_START_ERROR_QUOTA StartWorkspaceErrorDomain = 3
+	// START_ERROR_CAPACITY means the cluster cannot take on more workspaces right now, e.g. because the Kubernetes API is overloaded
+	StartWorkspaceErrorDomain_START_ERROR_CAPACITY StartWorkspaceErrorDomain = 4
+	// START_ERROR_IMAGE means the workspace image cannot be used, e.g. because it is built for another platform
+	StartWorkspaceErrorDomain_START_ERROR_IMAGE StartWorkspaceErrorDomain = 5
+	// START_ERROR_ARCHIVED means the backup of the workspace is in cold storage and the workspace must be unarchived first
+	StartWorkspaceErrorDomain_START_ERROR_ARCHIVED StartWorkspaceErrorDomain = 6
+	// START_ERROR_DELETED means the workspace has been soft-deleted and must be restored first
+	StartWorkspaceErrorDomain_START_ERROR_DELETED StartWorkspaceErrorDomain = 7
+)
+
+var StartWorkspaceErrorDomain_name = map[int32]string{
+	0: "START_ERROR_UNKNOWN",
+	1: "START_ERROR_INVALID_REQUEST",
+	2: "START_ERROR_CONFLICT",
+	3: "START_ERROR_QUOTA",
+	4: "START_ERROR_CAPACITY",
+	5: "START_ERROR_IMAGE",
+	6: "START_ERROR_ARCHIVED",
+	7: "START_ERROR_DELETED",
+}
+
+var StartWorkspaceErrorDomain_value = map[string]int32{
+	"START_ERROR_UNKNOWN":         0,
+	"START_ERROR_INVALID_REQUEST": 1,
+	"START_ERROR_CONFLICT":        2,
+	"START_ERROR_QUOTA":           3,
+	"START_ERROR_CAPACITY":        4,
+	"START_ERROR_IMAGE":           5,
+	"START_ERROR_ARCHIVED":        6,
+	"START_ERROR_DELETED":         7,
+}
+
+func (x StartWorkspaceErrorDomain) String() string {
+	return proto.EnumName(StartWorkspaceErrorDomain_name, int32(x))
+}
+
+func (StartWorkspaceErrorDomain) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{0}
+}
+
 type StopWorkspacePolicy int32
 
 const (
@@ -48,7 +100,62 @@ func (x StopWorkspacePolicy) String() string {
 }
 
 func (StopWorkspacePolicy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{0}
+	return fileDescriptor_f7e43720d1edc0fe, []int{1}
+}
+
+// AccountingEvent is what happened to a workspace instance
+type AccountingEvent int32
+
+const (
+	// INSTANCE_STARTED means the instance is running
+	AccountingEvent_INSTANCE_STARTED AccountingEvent = 0
+	// INSTANCE_STOPPED means the instance stopped running
+	AccountingEvent_INSTANCE_STOPPED AccountingEvent = 1
+)
+
+var AccountingEvent_name = map[int32]string{
+	0: "INSTANCE_STARTED",
+	1: "INSTANCE_STOPPED",
+}
+
+var AccountingEvent_value = map[string]int32{
+	"INSTANCE_STARTED": 0,
+	"INSTANCE_STOPPED": 1,
+}
+
+func (x AccountingEvent) String() string {
+	return proto.EnumName(AccountingEvent_name, int32(x))
+}
+
+func (AccountingEvent) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{2}
+}
+
+type PendingOperationKind int32
+
+const (
+	// CONTENT_INITIALIZATION means ws-daemon is initializing the workspace content
+	PendingOperationKind_CONTENT_INITIALIZATION PendingOperationKind = 0
+	// CONTENT_FINALIZATION means ws-daemon is backing up and disposing of the workspace content
+	PendingOperationKind_CONTENT_FINALIZATION PendingOperationKind = 1
+)
+
+var PendingOperationKind_name = map[int32]string{
+	0: "CONTENT_INITIALIZATION",
+	1: "CONTENT_FINALIZATION",
+}
+
+var PendingOperationKind_value = map[string]int32{
+	"CONTENT_INITIALIZATION": 0,
+	"CONTENT_FINALIZATION":   1,
+}
+
+func (x PendingOperationKind) String() string {
+	return proto.EnumName(PendingOperationKind_name, int32(x))
+}
+
+func (PendingOperationKind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{3}
 }
 
 type AdmissionLevel int32
@@ -75,7 +182,75 @@ func (x AdmissionLevel) String() string {
 }
 
 func (AdmissionLevel) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{1}
+	return fileDescriptor_f7e43720d1edc0fe, []int{4}
+}
+
+// InitContainerState is where an init container is in its lifecycle
+type InitContainerState int32
+
+const (
+	// INIT_CONTAINER_WAITING means the init container has not started yet, e.g. because its image is being pulled
+	InitContainerState_INIT_CONTAINER_WAITING InitContainerState = 0
+	// INIT_CONTAINER_RUNNING means the init container is running
+	InitContainerState_INIT_CONTAINER_RUNNING InitContainerState = 1
+	// INIT_CONTAINER_SUCCEEDED means the init container finished successfully
+	InitContainerState_INIT_CONTAINER_SUCCEEDED InitContainerState = 2
+	// INIT_CONTAINER_FAILED means the init container failed, which fails the workspace
+	InitContainerState_INIT_CONTAINER_FAILED InitContainerState = 3
+)
+
+var InitContainerState_name = map[int32]string{
+	0: "INIT_CONTAINER_WAITING",
+	1: "INIT_CONTAINER_RUNNING",
+	2: "INIT_CONTAINER_SUCCEEDED",
+	3: "INIT_CONTAINER_FAILED",
+}
+
+var InitContainerState_value = map[string]int32{
+	"INIT_CONTAINER_WAITING":   0,
+	"INIT_CONTAINER_RUNNING":   1,
+	"INIT_CONTAINER_SUCCEEDED": 2,
+	"INIT_CONTAINER_FAILED":    3,
+}
+
+func (x InitContainerState) String() string {
+	return proto.EnumName(InitContainerState_name, int32(x))
+}
+
+func (InitContainerState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{5}
+}
+
+// BulkOperationState is where a bulk operation is in its lifecycle
+type BulkOperationState int32
+
+const (
+	// BULK_OPERATION_RUNNING means the operation has not reached all workspaces yet
+	BulkOperationState_BULK_OPERATION_RUNNING BulkOperationState = 0
+	// BULK_OPERATION_COMPLETED means the operation has worked on all workspaces. Some of them may have failed.
+	BulkOperationState_BULK_OPERATION_COMPLETED BulkOperationState = 1
+	// BULK_OPERATION_CANCELLED means the operation was cancelled before it reached all workspaces
+	BulkOperationState_BULK_OPERATION_CANCELLED BulkOperationState = 2
+)
+
+var BulkOperationState_name = map[int32]string{
+	0: "BULK_OPERATION_RUNNING",
+	1: "BULK_OPERATION_COMPLETED",
+	2: "BULK_OPERATION_CANCELLED",
+}
+
+var BulkOperationState_value = map[string]int32{
+	"BULK_OPERATION_RUNNING":   0,
+	"BULK_OPERATION_COMPLETED": 1,
+	"BULK_OPERATION_CANCELLED": 2,
+}
+
+func (x BulkOperationState) String() string {
+	return proto.EnumName(BulkOperationState_name, int32(x))
+}
+
+func (BulkOperationState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{6}
 }
 
 // PortVisibility defines who may access a workspace port which is guarded by an authentication in the proxy
@@ -104,7 +279,7 @@ func (x PortVisibility) String() string {
 }
 
 func (PortVisibility) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{2}
+	return fileDescriptor_f7e43720d1edc0fe, []int{7}
 }
 
 // WorkspaceConditionBool is a trinary bool: true/false/empty
@@ -133,7 +308,7 @@ func (x WorkspaceConditionBool) String() string {
 }
 
 func (WorkspaceConditionBool) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{3}
+	return fileDescriptor_f7e43720d1edc0fe, []int{8}
 }
 
 // WorkspacePhase is a simple, high-level summary of where the workspace is in its lifecycle.
@@ -167,6 +342,9 @@ const (
 	WorkspacePhase_STOPPING WorkspacePhase = 5
 	// Stopped means the workspace ended regularly because it was shut down.
 	WorkspacePhase_STOPPED WorkspacePhase = 6
+	// Archived means the workspace has been stopped for a long time and its backup was moved to cold storage.
+	// It must be unarchived before it can start again, which can take a while.
+	WorkspacePhase_ARCHIVED WorkspacePhase = 8
 )
 
 var WorkspacePhase_name = map[int32]string{
@@ -178,6 +356,7 @@ var WorkspacePhase_name = map[int32]string{
 	7: "INTERRUPTED",
 	5: "STOPPING",
 	6: "STOPPED",
+	8: "ARCHIVED",
 }
 
 var WorkspacePhase_value = map[string]int32{
@@ -189,6 +368,7 @@ var WorkspacePhase_value = map[string]int32{
 	"INTERRUPTED":  7,
 	"STOPPING":     5,
 	"STOPPED":      6,
+	"ARCHIVED":     8,
 }
 
 func (x WorkspacePhase) String() string {
@@ -196,7 +376,7 @@ func (x WorkspacePhase) String() string {
 }
 
 func (WorkspacePhase) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{4}
+	return fileDescriptor_f7e43720d1edc0fe, []int{9}
 }
 
 // WorkspaceFeatureFlag enable non-standard behaviour in workspaces
@@ -238,7 +418,7 @@ func (x WorkspaceFeatureFlag) String() string {
 }
 
 func (WorkspaceFeatureFlag) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{5}
+	return fileDescriptor_f7e43720d1edc0fe, []int{10}
 }
 
 // WorkspaceType specifies the purpose/use of a workspace. Different workspace types are handled differently by all parts of the system.
@@ -276,7 +456,7 @@ func (x WorkspaceType) String() string {
 }
 
 func (WorkspaceType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{6}
+	return fileDescriptor_f7e43720d1edc0fe, []int{11}
 }
 
 // GetWorkspacesRequest requests a list of running workspaces
@@ -365,10 +545,16 @@ type StartWorkspaceRequest struct {
 	// Spec is the configuration of the workspace that's required for the ws-manager to start the workspace
 	Spec *StartWorkspaceSpec `protobuf:"bytes,4,opt,name=spec,proto3" json:"spec,omitempty"`
 	// Type denots the kind of workspace we ought to start
-	Type                 WorkspaceType `protobuf:"varint,6,opt,name=type,proto3,enum=wsman.WorkspaceType" json:"type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Type WorkspaceType `protobuf:"varint,6,opt,name=type,proto3,enum=wsman.WorkspaceType" json:"type,omitempty"`
+	// paused pre-creates a regular workspace ahead of predicted demand: the workspace pod is created, its image pulled
+	// and its content initialized, but supervisor holds the IDE and the tasks until a StartWorkspace request for
+	// the same workspace and owner activates it. Paused workspaces which are not activated in time are stopped.
+	Paused bool `protobuf:"varint,7,opt,name=paused,proto3" json:"paused,omitempty"`
+	// template references a workspace template whose settings apply wherever the spec leaves them empty
+	Template             *WorkspaceTemplateRef `protobuf:"bytes,8,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *StartWorkspaceRequest) Reset()         { *m = StartWorkspaceRequest{} }
@@ -431,9 +617,25 @@ func (m *StartWorkspaceRequest) GetType() WorkspaceType {
 	return WorkspaceType_REGULAR
 }
 
+func (m *StartWorkspaceRequest) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *StartWorkspaceRequest) GetTemplate() *WorkspaceTemplateRef {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
 type StartWorkspaceResponse struct {
 	// URL is the external URL of the workspace
-	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// activated is true if the request activated a paused workspace rather than starting a new one
+	Activated            bool     `protobuf:"varint,2,opt,name=activated,proto3" json:"activated,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -471,22 +673,101 @@ func (m *StartWorkspaceResponse) GetUrl() string {
 	return ""
 }
 
+func (m *StartWorkspaceResponse) GetActivated() bool {
+	if m != nil {
+		return m.Activated
+	}
+	return false
+}
+
+// StartWorkspaceErrorDetails are attached to the gRPC status of a failed StartWorkspace call
+type StartWorkspaceErrorDetails struct {
+	// domain classifies the failure
+	Domain StartWorkspaceErrorDomain `protobuf:"varint,1,opt,name=domain,proto3,enum=wsman.StartWorkspaceErrorDomain" json:"domain,omitempty"`
+	// retryable is true if sending the same request again later may succeed
+	Retryable bool `protobuf:"varint,2,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	// retry_after is how long callers should wait before they retry. Must be a valid Go duration (see https://golang.org/pkg/time/#ParseDuration).
+	// Empty if the failure is not retryable.
+	RetryAfter string `protobuf:"bytes,3,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	// user_message explains the failure to the user of the workspace
+	UserMessage          string   `protobuf:"bytes,4,opt,name=user_message,json=userMessage,proto3" json:"user_message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartWorkspaceErrorDetails) Reset()         { *m = StartWorkspaceErrorDetails{} }
+func (m *StartWorkspaceErrorDetails) String() string { return proto.CompactTextString(m) }
+func (*StartWorkspaceErrorDetails) ProtoMessage()    {}
+func (*StartWorkspaceErrorDetails) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{4}
+}
+
+func (m *StartWorkspaceErrorDetails) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartWorkspaceErrorDetails.Unmarshal(m, b)
+}
+func (m *StartWorkspaceErrorDetails) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartWorkspaceErrorDetails.Marshal(b, m, deterministic)
+}
+func (m *StartWorkspaceErrorDetails) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartWorkspaceErrorDetails.Merge(m, src)
+}
+func (m *StartWorkspaceErrorDetails) XXX_Size() int {
+	return xxx_messageInfo_StartWorkspaceErrorDetails.Size(m)
+}
+func (m *StartWorkspaceErrorDetails) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartWorkspaceErrorDetails.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartWorkspaceErrorDetails proto.InternalMessageInfo
+
+func (m *StartWorkspaceErrorDetails) GetDomain() StartWorkspaceErrorDomain {
+	if m != nil {
+		return m.Domain
+	}
+	return StartWorkspaceErrorDomain_START_ERROR_UNKNOWN
+}
+
+func (m *StartWorkspaceErrorDetails) GetRetryable() bool {
+	if m != nil {
+		return m.Retryable
+	}
+	return false
+}
+
+func (m *StartWorkspaceErrorDetails) GetRetryAfter() string {
+	if m != nil {
+		return m.RetryAfter
+	}
+	return ""
+}
+
+func (m *StartWorkspaceErrorDetails) GetUserMessage() string {
+	if m != nil {
+		return m.UserMessage
+	}
+	return ""
+}
+
 // StopWorkspaceRequest requests that the workspace manager stops a workspace
 type StopWorkspaceRequest struct {
 	// ID is the unique identifier of the workspace to stop
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	// Policy determines how quickly a workspace will be stopped
-	Policy               StopWorkspacePolicy `protobuf:"varint,2,opt,name=policy,proto3,enum=wsman.StopWorkspacePolicy" json:"policy,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Policy StopWorkspacePolicy `protobuf:"varint,2,opt,name=policy,proto3,enum=wsman.StopWorkspacePolicy" json:"policy,omitempty"`
+	// expected_generation, if non-zero, makes this request conditional on the workspace's status generation.
+	// If the workspace has moved on since, the request fails with ABORTED and nothing is changed.
+	ExpectedGeneration   uint64   `protobuf:"varint,3,opt,name=expected_generation,json=expectedGeneration,proto3" json:"expected_generation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StopWorkspaceRequest) Reset()         { *m = StopWorkspaceRequest{} }
 func (m *StopWorkspaceRequest) String() string { return proto.CompactTextString(m) }
 func (*StopWorkspaceRequest) ProtoMessage()    {}
 func (*StopWorkspaceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{4}
+	return fileDescriptor_f7e43720d1edc0fe, []int{5}
 }
 
 func (m *StopWorkspaceRequest) XXX_Unmarshal(b []byte) error {
@@ -521,6 +802,13 @@ func (m *StopWorkspaceRequest) GetPolicy() StopWorkspacePolicy {
 	return StopWorkspacePolicy_NORMALLY
 }
 
+func (m *StopWorkspaceRequest) GetExpectedGeneration() uint64 {
+	if m != nil {
+		return m.ExpectedGeneration
+	}
+	return 0
+}
+
 // StopWorkspaceResponse is the answer to a stop workspace request
 type StopWorkspaceResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -532,7 +820,7 @@ func (m *StopWorkspaceResponse) Reset()         { *m = StopWorkspaceResponse{} }
 func (m *StopWorkspaceResponse) String() string { return proto.CompactTextString(m) }
 func (*StopWorkspaceResponse) ProtoMessage()    {}
 func (*StopWorkspaceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{5}
+	return fileDescriptor_f7e43720d1edc0fe, []int{6}
 }
 
 func (m *StopWorkspaceResponse) XXX_Unmarshal(b []byte) error {
@@ -566,7 +854,7 @@ func (m *DescribeWorkspaceRequest) Reset()         { *m = DescribeWorkspaceReque
 func (m *DescribeWorkspaceRequest) String() string { return proto.CompactTextString(m) }
 func (*DescribeWorkspaceRequest) ProtoMessage()    {}
 func (*DescribeWorkspaceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{6}
+	return fileDescriptor_f7e43720d1edc0fe, []int{7}
 }
 
 func (m *DescribeWorkspaceRequest) XXX_Unmarshal(b []byte) error {
@@ -608,7 +896,7 @@ func (m *DescribeWorkspaceResponse) Reset()         { *m = DescribeWorkspaceResp
 func (m *DescribeWorkspaceResponse) String() string { return proto.CompactTextString(m) }
 func (*DescribeWorkspaceResponse) ProtoMessage()    {}
 func (*DescribeWorkspaceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{7}
+	return fileDescriptor_f7e43720d1edc0fe, []int{8}
 }
 
 func (m *DescribeWorkspaceResponse) XXX_Unmarshal(b []byte) error {
@@ -654,7 +942,7 @@ func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
 func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
 func (*SubscribeRequest) ProtoMessage()    {}
 func (*SubscribeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{8}
+	return fileDescriptor_f7e43720d1edc0fe, []int{9}
 }
 
 func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
@@ -680,6 +968,7 @@ type SubscribeResponse struct {
 	// Types that are valid to be assigned to Payload:
 	//	*SubscribeResponse_Status
 	//	*SubscribeResponse_Log
+	//	*SubscribeResponse_Maintenance
 	Payload              isSubscribeResponse_Payload `protobuf_oneof:"payload"`
 	Header               map[string]string           `protobuf:"bytes,3,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
@@ -691,7 +980,7 @@ func (m *SubscribeResponse) Reset()         { *m = SubscribeResponse{} }
 func (m *SubscribeResponse) String() string { return proto.CompactTextString(m) }
 func (*SubscribeResponse) ProtoMessage()    {}
 func (*SubscribeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{9}
+	return fileDescriptor_f7e43720d1edc0fe, []int{10}
 }
 
 func (m *SubscribeResponse) XXX_Unmarshal(b []byte) error {
@@ -724,10 +1013,16 @@ type SubscribeResponse_Log struct {
 	Log *WorkspaceLogMessage `protobuf:"bytes,2,opt,name=log,proto3,oneof"`
 }
 
+type SubscribeResponse_Maintenance struct {
+	Maintenance *MaintenanceStatus `protobuf:"bytes,4,opt,name=maintenance,proto3,oneof"`
+}
+
 func (*SubscribeResponse_Status) isSubscribeResponse_Payload() {}
 
 func (*SubscribeResponse_Log) isSubscribeResponse_Payload() {}
 
+func (*SubscribeResponse_Maintenance) isSubscribeResponse_Payload() {}
+
 func (m *SubscribeResponse) GetPayload() isSubscribeResponse_Payload {
 	if m != nil {
 		return m.Payload
@@ -749,6 +1044,13 @@ func (m *SubscribeResponse) GetLog() *WorkspaceLogMessage {
 	return nil
 }
 
+func (m *SubscribeResponse) GetMaintenance() *MaintenanceStatus {
+	if x, ok := m.GetPayload().(*SubscribeResponse_Maintenance); ok {
+		return x.Maintenance
+	}
+	return nil
+}
+
 func (m *SubscribeResponse) GetHeader() map[string]string {
 	if m != nil {
 		return m.Header
@@ -761,6 +1063,7 @@ func (*SubscribeResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*SubscribeResponse_Status)(nil),
 		(*SubscribeResponse_Log)(nil),
+		(*SubscribeResponse_Maintenance)(nil),
 	}
 }
 
@@ -779,7 +1082,7 @@ func (m *MarkActiveRequest) Reset()         { *m = MarkActiveRequest{} }
 func (m *MarkActiveRequest) String() string { return proto.CompactTextString(m) }
 func (*MarkActiveRequest) ProtoMessage()    {}
 func (*MarkActiveRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{10}
+	return fileDescriptor_f7e43720d1edc0fe, []int{11}
 }
 
 func (m *MarkActiveRequest) XXX_Unmarshal(b []byte) error {
@@ -825,7 +1128,7 @@ func (m *MarkActiveResponse) Reset()         { *m = MarkActiveResponse{} }
 func (m *MarkActiveResponse) String() string { return proto.CompactTextString(m) }
 func (*MarkActiveResponse) ProtoMessage()    {}
 func (*MarkActiveResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{11}
+	return fileDescriptor_f7e43720d1edc0fe, []int{12}
 }
 
 func (m *MarkActiveResponse) XXX_Unmarshal(b []byte) error {
@@ -851,7 +1154,10 @@ type SetTimeoutRequest struct {
 	// id is the ID of the workspace
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	// duration is the new timeout duration. Must be a valid Go duration (see https://golang.org/pkg/time/#ParseDuration)
-	Duration             string   `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	Duration string `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	// expected_generation, if non-zero, makes this request conditional on the workspace's status generation.
+	// If the workspace has moved on since, the request fails with ABORTED and nothing is changed.
+	ExpectedGeneration   uint64   `protobuf:"varint,3,opt,name=expected_generation,json=expectedGeneration,proto3" json:"expected_generation,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -861,7 +1167,7 @@ func (m *SetTimeoutRequest) Reset()         { *m = SetTimeoutRequest{} }
 func (m *SetTimeoutRequest) String() string { return proto.CompactTextString(m) }
 func (*SetTimeoutRequest) ProtoMessage()    {}
 func (*SetTimeoutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{12}
+	return fileDescriptor_f7e43720d1edc0fe, []int{13}
 }
 
 func (m *SetTimeoutRequest) XXX_Unmarshal(b []byte) error {
@@ -896,6 +1202,13 @@ func (m *SetTimeoutRequest) GetDuration() string {
 	return ""
 }
 
+func (m *SetTimeoutRequest) GetExpectedGeneration() uint64 {
+	if m != nil {
+		return m.ExpectedGeneration
+	}
+	return 0
+}
+
 // SetTimeoutResponse is the answer to a set timeout request
 type SetTimeoutResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -907,7 +1220,7 @@ func (m *SetTimeoutResponse) Reset()         { *m = SetTimeoutResponse{} }
 func (m *SetTimeoutResponse) String() string { return proto.CompactTextString(m) }
 func (*SetTimeoutResponse) ProtoMessage()    {}
 func (*SetTimeoutResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{13}
+	return fileDescriptor_f7e43720d1edc0fe, []int{14}
 }
 
 func (m *SetTimeoutResponse) XXX_Unmarshal(b []byte) error {
@@ -936,17 +1249,20 @@ type ControlPortRequest struct {
 	// If true, the port will become publicly available, if false it will become inaccessible from outside the workspace.
 	Expose bool `protobuf:"varint,2,opt,name=expose,proto3" json:"expose,omitempty"`
 	// spec defines the port under control
-	Spec                 *PortSpec `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	Spec *PortSpec `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
+	// expected_generation, if non-zero, makes this request conditional on the workspace's status generation.
+	// If the workspace has moved on since, the request fails with ABORTED and nothing is changed.
+	ExpectedGeneration   uint64   `protobuf:"varint,4,opt,name=expected_generation,json=expectedGeneration,proto3" json:"expected_generation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ControlPortRequest) Reset()         { *m = ControlPortRequest{} }
 func (m *ControlPortRequest) String() string { return proto.CompactTextString(m) }
 func (*ControlPortRequest) ProtoMessage()    {}
 func (*ControlPortRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{14}
+	return fileDescriptor_f7e43720d1edc0fe, []int{15}
 }
 
 func (m *ControlPortRequest) XXX_Unmarshal(b []byte) error {
@@ -988,6 +1304,13 @@ func (m *ControlPortRequest) GetSpec() *PortSpec {
 	return nil
 }
 
+func (m *ControlPortRequest) GetExpectedGeneration() uint64 {
+	if m != nil {
+		return m.ExpectedGeneration
+	}
+	return 0
+}
+
 // ControlPortResponse is the answer to a workspace port control request
 type ControlPortResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -999,7 +1322,7 @@ func (m *ControlPortResponse) Reset()         { *m = ControlPortResponse{} }
 func (m *ControlPortResponse) String() string { return proto.CompactTextString(m) }
 func (*ControlPortResponse) ProtoMessage()    {}
 func (*ControlPortResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{15}
+	return fileDescriptor_f7e43720d1edc0fe, []int{16}
 }
 
 func (m *ControlPortResponse) XXX_Unmarshal(b []byte) error {
@@ -1033,7 +1356,7 @@ func (m *TakeSnapshotRequest) Reset()         { *m = TakeSnapshotRequest{} }
 func (m *TakeSnapshotRequest) String() string { return proto.CompactTextString(m) }
 func (*TakeSnapshotRequest) ProtoMessage()    {}
 func (*TakeSnapshotRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{16}
+	return fileDescriptor_f7e43720d1edc0fe, []int{17}
 }
 
 func (m *TakeSnapshotRequest) XXX_Unmarshal(b []byte) error {
@@ -1074,7 +1397,7 @@ func (m *TakeSnapshotResponse) Reset()         { *m = TakeSnapshotResponse{} }
 func (m *TakeSnapshotResponse) String() string { return proto.CompactTextString(m) }
 func (*TakeSnapshotResponse) ProtoMessage()    {}
 func (*TakeSnapshotResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{17}
+	return fileDescriptor_f7e43720d1edc0fe, []int{18}
 }
 
 func (m *TakeSnapshotResponse) XXX_Unmarshal(b []byte) error {
@@ -1107,17 +1430,20 @@ type ControlAdmissionRequest struct {
 	// ID is the unique identifier of the workspace whoose admission to control
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	// level is the new workspace admission level
-	Level                AdmissionLevel `protobuf:"varint,2,opt,name=level,proto3,enum=wsman.AdmissionLevel" json:"level,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Level AdmissionLevel `protobuf:"varint,2,opt,name=level,proto3,enum=wsman.AdmissionLevel" json:"level,omitempty"`
+	// expected_generation, if non-zero, makes this request conditional on the workspace's status generation.
+	// If the workspace has moved on since, the request fails with ABORTED and nothing is changed.
+	ExpectedGeneration   uint64   `protobuf:"varint,3,opt,name=expected_generation,json=expectedGeneration,proto3" json:"expected_generation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ControlAdmissionRequest) Reset()         { *m = ControlAdmissionRequest{} }
 func (m *ControlAdmissionRequest) String() string { return proto.CompactTextString(m) }
 func (*ControlAdmissionRequest) ProtoMessage()    {}
 func (*ControlAdmissionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{18}
+	return fileDescriptor_f7e43720d1edc0fe, []int{19}
 }
 
 func (m *ControlAdmissionRequest) XXX_Unmarshal(b []byte) error {
@@ -1152,6 +1478,13 @@ func (m *ControlAdmissionRequest) GetLevel() AdmissionLevel {
 	return AdmissionLevel_ADMIT_OWNER_ONLY
 }
 
+func (m *ControlAdmissionRequest) GetExpectedGeneration() uint64 {
+	if m != nil {
+		return m.ExpectedGeneration
+	}
+	return 0
+}
+
 type ControlAdmissionResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -1162,7 +1495,7 @@ func (m *ControlAdmissionResponse) Reset()         { *m = ControlAdmissionRespon
 func (m *ControlAdmissionResponse) String() string { return proto.CompactTextString(m) }
 func (*ControlAdmissionResponse) ProtoMessage()    {}
 func (*ControlAdmissionResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{19}
+	return fileDescriptor_f7e43720d1edc0fe, []int{20}
 }
 
 func (m *ControlAdmissionResponse) XXX_Unmarshal(b []byte) error {
@@ -1183,1138 +1516,5820 @@ func (m *ControlAdmissionResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ControlAdmissionResponse proto.InternalMessageInfo
 
-// WorkspaceStatus describes a workspace status
-type WorkspaceStatus struct {
-	// ID is the unique identifier of the workspace
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// Metadata is data associated with this workspace that's required for other parts of Gitpod to function
-	Metadata *WorkspaceMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	// Spec is the workspace spec during runtime
-	Spec *WorkspaceSpec `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
-	// the phase of a workspace is a simple, high-level summary of where the workspace is in its lifecycle
-	Phase WorkspacePhase `protobuf:"varint,4,opt,name=phase,proto3,enum=wsman.WorkspacePhase" json:"phase,omitempty"`
-	// conditions detail the current state of the workspace
-	Conditions *WorkspaceConditions `protobuf:"bytes,5,opt,name=conditions,proto3" json:"conditions,omitempty"`
-	// message is an optional human-readable message detailing the current phase
-	Message string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
-	// repo details the Git working copy status of the workspace.
-	// Note: this is a best-effort field and more often than not will not be present. Its absence does not
-	// indicate the absence of a working copy.
-	Repo *api.GitStatus `protobuf:"bytes,7,opt,name=repo,proto3" json:"repo,omitempty"`
-	// runtime contains information about the workspace's runtime environment
-	Runtime *WorkspaceRuntimeInfo `protobuf:"bytes,8,opt,name=runtime,proto3" json:"runtime,omitempty"`
-	// auth provides authentication information about the workspace. This info is primarily used by ws-proxy.
-	Auth                 *WorkspaceAuthentication `protobuf:"bytes,9,opt,name=auth,proto3" json:"auth,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
-	XXX_unrecognized     []byte                   `json:"-"`
-	XXX_sizecache        int32                    `json:"-"`
+// SetMaintenanceRequest announces or ends a cluster maintenance
+type SetMaintenanceRequest struct {
+	Status               *MaintenanceStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
-func (m *WorkspaceStatus) Reset()         { *m = WorkspaceStatus{} }
-func (m *WorkspaceStatus) String() string { return proto.CompactTextString(m) }
-func (*WorkspaceStatus) ProtoMessage()    {}
-func (*WorkspaceStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{20}
+func (m *SetMaintenanceRequest) Reset()         { *m = SetMaintenanceRequest{} }
+func (m *SetMaintenanceRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMaintenanceRequest) ProtoMessage()    {}
+func (*SetMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{21}
 }
 
-func (m *WorkspaceStatus) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WorkspaceStatus.Unmarshal(m, b)
+func (m *SetMaintenanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMaintenanceRequest.Unmarshal(m, b)
 }
-func (m *WorkspaceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WorkspaceStatus.Marshal(b, m, deterministic)
+func (m *SetMaintenanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMaintenanceRequest.Marshal(b, m, deterministic)
 }
-func (m *WorkspaceStatus) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkspaceStatus.Merge(m, src)
+func (m *SetMaintenanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMaintenanceRequest.Merge(m, src)
 }
-func (m *WorkspaceStatus) XXX_Size() int {
-	return xxx_messageInfo_WorkspaceStatus.Size(m)
+func (m *SetMaintenanceRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMaintenanceRequest.Size(m)
 }
-func (m *WorkspaceStatus) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkspaceStatus.DiscardUnknown(m)
+func (m *SetMaintenanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMaintenanceRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkspaceStatus proto.InternalMessageInfo
+var xxx_messageInfo_SetMaintenanceRequest proto.InternalMessageInfo
 
-func (m *WorkspaceStatus) GetId() string {
+func (m *SetMaintenanceRequest) GetStatus() *MaintenanceStatus {
 	if m != nil {
-		return m.Id
+		return m.Status
 	}
-	return ""
+	return nil
 }
 
-func (m *WorkspaceStatus) GetMetadata() *WorkspaceMetadata {
-	if m != nil {
-		return m.Metadata
-	}
-	return nil
+type SetMaintenanceResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceStatus) GetSpec() *WorkspaceSpec {
-	if m != nil {
-		return m.Spec
-	}
-	return nil
+func (m *SetMaintenanceResponse) Reset()         { *m = SetMaintenanceResponse{} }
+func (m *SetMaintenanceResponse) String() string { return proto.CompactTextString(m) }
+func (*SetMaintenanceResponse) ProtoMessage()    {}
+func (*SetMaintenanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{22}
 }
 
-func (m *WorkspaceStatus) GetPhase() WorkspacePhase {
-	if m != nil {
-		return m.Phase
-	}
-	return WorkspacePhase_UNKNOWN
+func (m *SetMaintenanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMaintenanceResponse.Unmarshal(m, b)
+}
+func (m *SetMaintenanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMaintenanceResponse.Marshal(b, m, deterministic)
+}
+func (m *SetMaintenanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMaintenanceResponse.Merge(m, src)
+}
+func (m *SetMaintenanceResponse) XXX_Size() int {
+	return xxx_messageInfo_SetMaintenanceResponse.Size(m)
+}
+func (m *SetMaintenanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMaintenanceResponse.DiscardUnknown(m)
 }
 
-func (m *WorkspaceStatus) GetConditions() *WorkspaceConditions {
-	if m != nil {
-		return m.Conditions
-	}
-	return nil
+var xxx_messageInfo_SetMaintenanceResponse proto.InternalMessageInfo
+
+// StartWorkspaceGroupRequest requests that the workspace manager starts a group of workspaces.
+// The workspaces of a group share their lifecycle: if one of them stops, all of them stop.
+type StartWorkspaceGroupRequest struct {
+	// id uniquely identifies the group
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// members are the workspaces of the group. A member starts once all members it depends on are running.
+	Members []*WorkspaceGroupMember `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	// timeout optionally sets a custom timeout for all members. The group times out once all of its members would.
+	Timeout              string   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceStatus) GetMessage() string {
-	if m != nil {
-		return m.Message
-	}
-	return ""
+func (m *StartWorkspaceGroupRequest) Reset()         { *m = StartWorkspaceGroupRequest{} }
+func (m *StartWorkspaceGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*StartWorkspaceGroupRequest) ProtoMessage()    {}
+func (*StartWorkspaceGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{23}
 }
 
-func (m *WorkspaceStatus) GetRepo() *api.GitStatus {
+func (m *StartWorkspaceGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartWorkspaceGroupRequest.Unmarshal(m, b)
+}
+func (m *StartWorkspaceGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartWorkspaceGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *StartWorkspaceGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartWorkspaceGroupRequest.Merge(m, src)
+}
+func (m *StartWorkspaceGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_StartWorkspaceGroupRequest.Size(m)
+}
+func (m *StartWorkspaceGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartWorkspaceGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartWorkspaceGroupRequest proto.InternalMessageInfo
+
+func (m *StartWorkspaceGroupRequest) GetId() string {
 	if m != nil {
-		return m.Repo
+		return m.Id
 	}
-	return nil
+	return ""
 }
 
-func (m *WorkspaceStatus) GetRuntime() *WorkspaceRuntimeInfo {
+func (m *StartWorkspaceGroupRequest) GetMembers() []*WorkspaceGroupMember {
 	if m != nil {
-		return m.Runtime
+		return m.Members
 	}
 	return nil
 }
 
-func (m *WorkspaceStatus) GetAuth() *WorkspaceAuthentication {
+func (m *StartWorkspaceGroupRequest) GetTimeout() string {
 	if m != nil {
-		return m.Auth
+		return m.Timeout
 	}
-	return nil
+	return ""
 }
 
-// WorkspaceSpec is the specification of a workspace at runtime
-type WorkspaceSpec struct {
-	// workspace_image is the name of the Docker image this workspace runs
-	WorkspaceImage string `protobuf:"bytes,1,opt,name=workspace_image,json=workspaceImage,proto3" json:"workspace_image,omitempty"`
-	// ide_image is the name of the Docker image used as IDE
-	IdeImage string `protobuf:"bytes,2,opt,name=ide_image,json=ideImage,proto3" json:"ide_image,omitempty"`
-	// headless marks this workspace a headless one - headless workspaces are not intended for users but for automation
-	Headless bool `protobuf:"varint,3,opt,name=headless,proto3" json:"headless,omitempty"`
-	// URL is the external URL of the workspace
-	Url string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
-	// exposed_ports lists all ports which this workspace has exposed to the outside world
-	ExposedPorts []*PortSpec `protobuf:"bytes,5,rep,name=exposed_ports,json=exposedPorts,proto3" json:"exposed_ports,omitempty"`
-	// workspace type denotes what kind of workspace this is, e.g. if it's user-facing, prebuilding content or probing the service
-	Type WorkspaceType `protobuf:"varint,6,opt,name=type,proto3,enum=wsman.WorkspaceType" json:"type,omitempty"`
-	// The intervals in which a heartbeat must be received for the workspace not to time out
-	Timeout              string   `protobuf:"bytes,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+// WorkspaceGroupMember is a workspace of a group
+type WorkspaceGroupMember struct {
+	// name identifies the member within the group, e.g. backend. Every member finds the URLs of the other
+	// members in its GITPOD_GROUP_<NAME>_URL environment variables.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// workspace is the start request of the member's workspace
+	Workspace *StartWorkspaceRequest `protobuf:"bytes,2,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	// depends_on are the names of the members which must be running before this member starts
+	DependsOn            []string `protobuf:"bytes,3,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceSpec) Reset()         { *m = WorkspaceSpec{} }
-func (m *WorkspaceSpec) String() string { return proto.CompactTextString(m) }
-func (*WorkspaceSpec) ProtoMessage()    {}
-func (*WorkspaceSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{21}
+func (m *WorkspaceGroupMember) Reset()         { *m = WorkspaceGroupMember{} }
+func (m *WorkspaceGroupMember) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceGroupMember) ProtoMessage()    {}
+func (*WorkspaceGroupMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{24}
 }
 
-func (m *WorkspaceSpec) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WorkspaceSpec.Unmarshal(m, b)
+func (m *WorkspaceGroupMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceGroupMember.Unmarshal(m, b)
 }
-func (m *WorkspaceSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WorkspaceSpec.Marshal(b, m, deterministic)
+func (m *WorkspaceGroupMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceGroupMember.Marshal(b, m, deterministic)
 }
-func (m *WorkspaceSpec) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkspaceSpec.Merge(m, src)
+func (m *WorkspaceGroupMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceGroupMember.Merge(m, src)
 }
-func (m *WorkspaceSpec) XXX_Size() int {
-	return xxx_messageInfo_WorkspaceSpec.Size(m)
+func (m *WorkspaceGroupMember) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceGroupMember.Size(m)
 }
-func (m *WorkspaceSpec) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkspaceSpec.DiscardUnknown(m)
+func (m *WorkspaceGroupMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceGroupMember.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkspaceSpec proto.InternalMessageInfo
+var xxx_messageInfo_WorkspaceGroupMember proto.InternalMessageInfo
 
-func (m *WorkspaceSpec) GetWorkspaceImage() string {
+func (m *WorkspaceGroupMember) GetName() string {
 	if m != nil {
-		return m.WorkspaceImage
+		return m.Name
 	}
 	return ""
 }
 
-func (m *WorkspaceSpec) GetIdeImage() string {
+func (m *WorkspaceGroupMember) GetWorkspace() *StartWorkspaceRequest {
 	if m != nil {
-		return m.IdeImage
+		return m.Workspace
 	}
-	return ""
+	return nil
 }
 
-func (m *WorkspaceSpec) GetHeadless() bool {
+func (m *WorkspaceGroupMember) GetDependsOn() []string {
 	if m != nil {
-		return m.Headless
+		return m.DependsOn
 	}
-	return false
+	return nil
 }
 
-func (m *WorkspaceSpec) GetUrl() string {
-	if m != nil {
-		return m.Url
-	}
-	return ""
+// StartWorkspaceGroupResponse is the answer to a start workspace group request
+type StartWorkspaceGroupResponse struct {
+	// urls maps the member names to the external URLs of their workspaces
+	Urls                 map[string]string `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func (m *WorkspaceSpec) GetExposedPorts() []*PortSpec {
+func (m *StartWorkspaceGroupResponse) Reset()         { *m = StartWorkspaceGroupResponse{} }
+func (m *StartWorkspaceGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*StartWorkspaceGroupResponse) ProtoMessage()    {}
+func (*StartWorkspaceGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{25}
+}
+
+func (m *StartWorkspaceGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartWorkspaceGroupResponse.Unmarshal(m, b)
+}
+func (m *StartWorkspaceGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartWorkspaceGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *StartWorkspaceGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartWorkspaceGroupResponse.Merge(m, src)
+}
+func (m *StartWorkspaceGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_StartWorkspaceGroupResponse.Size(m)
+}
+func (m *StartWorkspaceGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartWorkspaceGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartWorkspaceGroupResponse proto.InternalMessageInfo
+
+func (m *StartWorkspaceGroupResponse) GetUrls() map[string]string {
 	if m != nil {
-		return m.ExposedPorts
+		return m.Urls
 	}
 	return nil
 }
 
-func (m *WorkspaceSpec) GetType() WorkspaceType {
+// StopWorkspaceGroupRequest requests that the workspace manager stops all workspaces of a group
+type StopWorkspaceGroupRequest struct {
+	// id is the unique identifier of the group to stop
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// policy determines how quickly the workspaces will be stopped
+	Policy               StopWorkspacePolicy `protobuf:"varint,2,opt,name=policy,proto3,enum=wsman.StopWorkspacePolicy" json:"policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *StopWorkspaceGroupRequest) Reset()         { *m = StopWorkspaceGroupRequest{} }
+func (m *StopWorkspaceGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*StopWorkspaceGroupRequest) ProtoMessage()    {}
+func (*StopWorkspaceGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{26}
+}
+
+func (m *StopWorkspaceGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopWorkspaceGroupRequest.Unmarshal(m, b)
+}
+func (m *StopWorkspaceGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StopWorkspaceGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *StopWorkspaceGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopWorkspaceGroupRequest.Merge(m, src)
+}
+func (m *StopWorkspaceGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_StopWorkspaceGroupRequest.Size(m)
+}
+func (m *StopWorkspaceGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopWorkspaceGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StopWorkspaceGroupRequest proto.InternalMessageInfo
+
+func (m *StopWorkspaceGroupRequest) GetId() string {
 	if m != nil {
-		return m.Type
+		return m.Id
 	}
-	return WorkspaceType_REGULAR
+	return ""
 }
 
-func (m *WorkspaceSpec) GetTimeout() string {
+func (m *StopWorkspaceGroupRequest) GetPolicy() StopWorkspacePolicy {
 	if m != nil {
-		return m.Timeout
+		return m.Policy
 	}
-	return ""
+	return StopWorkspacePolicy_NORMALLY
 }
 
-// PortSpec describes a networking port exposed on a workspace
-type PortSpec struct {
-	// port is the outward-facing port
-	Port uint32 `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
-	// target is the inward-facing target port
-	Target uint32 `protobuf:"varint,2,opt,name=target,proto3" json:"target,omitempty"`
-	// visibility defines the visibility of the port
-	Visibility PortVisibility `protobuf:"varint,3,opt,name=visibility,proto3,enum=wsman.PortVisibility" json:"visibility,omitempty"`
-	// url is the public-facing URL this port is available at
-	Url                  string   `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+// StopWorkspaceGroupResponse is the answer to a stop workspace group request
+type StopWorkspaceGroupResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PortSpec) Reset()         { *m = PortSpec{} }
-func (m *PortSpec) String() string { return proto.CompactTextString(m) }
-func (*PortSpec) ProtoMessage()    {}
-func (*PortSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{22}
+func (m *StopWorkspaceGroupResponse) Reset()         { *m = StopWorkspaceGroupResponse{} }
+func (m *StopWorkspaceGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*StopWorkspaceGroupResponse) ProtoMessage()    {}
+func (*StopWorkspaceGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{27}
 }
 
-func (m *PortSpec) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PortSpec.Unmarshal(m, b)
+func (m *StopWorkspaceGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopWorkspaceGroupResponse.Unmarshal(m, b)
 }
-func (m *PortSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PortSpec.Marshal(b, m, deterministic)
+func (m *StopWorkspaceGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StopWorkspaceGroupResponse.Marshal(b, m, deterministic)
 }
-func (m *PortSpec) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PortSpec.Merge(m, src)
+func (m *StopWorkspaceGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopWorkspaceGroupResponse.Merge(m, src)
 }
-func (m *PortSpec) XXX_Size() int {
-	return xxx_messageInfo_PortSpec.Size(m)
+func (m *StopWorkspaceGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_StopWorkspaceGroupResponse.Size(m)
 }
-func (m *PortSpec) XXX_DiscardUnknown() {
-	xxx_messageInfo_PortSpec.DiscardUnknown(m)
+func (m *StopWorkspaceGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopWorkspaceGroupResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PortSpec proto.InternalMessageInfo
+var xxx_messageInfo_StopWorkspaceGroupResponse proto.InternalMessageInfo
 
-func (m *PortSpec) GetPort() uint32 {
-	if m != nil {
-		return m.Port
-	}
-	return 0
+// DescribeWorkspaceGroupRequest requests the status of all workspaces of a group
+type DescribeWorkspaceGroupRequest struct {
+	// id is the unique identifier of the group to describe
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PortSpec) GetTarget() uint32 {
-	if m != nil {
-		return m.Target
-	}
-	return 0
+func (m *DescribeWorkspaceGroupRequest) Reset()         { *m = DescribeWorkspaceGroupRequest{} }
+func (m *DescribeWorkspaceGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*DescribeWorkspaceGroupRequest) ProtoMessage()    {}
+func (*DescribeWorkspaceGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{28}
 }
 
-func (m *PortSpec) GetVisibility() PortVisibility {
-	if m != nil {
-		return m.Visibility
-	}
-	return PortVisibility_PORT_VISIBILITY_PRIVATE
+func (m *DescribeWorkspaceGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeWorkspaceGroupRequest.Unmarshal(m, b)
+}
+func (m *DescribeWorkspaceGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeWorkspaceGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *DescribeWorkspaceGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeWorkspaceGroupRequest.Merge(m, src)
+}
+func (m *DescribeWorkspaceGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_DescribeWorkspaceGroupRequest.Size(m)
+}
+func (m *DescribeWorkspaceGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeWorkspaceGroupRequest.DiscardUnknown(m)
 }
 
-func (m *PortSpec) GetUrl() string {
+var xxx_messageInfo_DescribeWorkspaceGroupRequest proto.InternalMessageInfo
+
+func (m *DescribeWorkspaceGroupRequest) GetId() string {
 	if m != nil {
-		return m.Url
+		return m.Id
 	}
 	return ""
 }
 
-// WorkspaceCondition gives more detailed information as to the state of the workspace. Which condition actually
-// has a value depends on the phase the workspace is in.
-type WorkspaceConditions struct {
-	// failed contains the reason the workspace failed to operate. If this field is empty, the workspace has not failed.
-	Failed string `protobuf:"bytes,1,opt,name=failed,proto3" json:"failed,omitempty"`
-	// timeout contains the reason the workspace has timed out. If this field is empty, the workspace has not timed out.
-	Timeout string `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	// pulling_images marks if the workspace is currently pulling its images. This condition can only be set during PhaseCreating
-	PullingImages WorkspaceConditionBool `protobuf:"varint,3,opt,name=pulling_images,json=pullingImages,proto3,enum=wsman.WorkspaceConditionBool" json:"pulling_images,omitempty"`
-	// service_exists denotes if the workspace theia-/ports- services exist. This condition will be true if either of the two services exist.
-	ServiceExists WorkspaceConditionBool `protobuf:"varint,4,opt,name=service_exists,json=serviceExists,proto3,enum=wsman.WorkspaceConditionBool" json:"service_exists,omitempty"`
-	// snapshot contains a snapshot URL if a snapshot was produced prior to shutting the workspace down. This condition is only used for headless workspaces.
-	Snapshot string `protobuf:"bytes,5,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
-	// final_backup_complete determines if the last state of the workspace has been backed up to remote storage.
-	// Once this is true, a new workspace with the same ID will be able to use this backup.
-	FinalBackupComplete WorkspaceConditionBool `protobuf:"varint,6,opt,name=final_backup_complete,json=finalBackupComplete,proto3,enum=wsman.WorkspaceConditionBool" json:"final_backup_complete,omitempty"`
-	// deployed indicates if a workspace container is currently deployed. If this condition is false, there is no means for the user to alter the workspace content.
-	Deployed WorkspaceConditionBool `protobuf:"varint,7,opt,name=deployed,proto3,enum=wsman.WorkspaceConditionBool" json:"deployed,omitempty"`
-	// network_not_ready indicates if a workspace container is currently experiencing a network problem.
-	NetworkNotReady WorkspaceConditionBool `protobuf:"varint,8,opt,name=network_not_ready,json=networkNotReady,proto3,enum=wsman.WorkspaceConditionBool" json:"network_not_ready,omitempty"`
-	// first_user_activity is the time when MarkActive was first called on the workspace
-	FirstUserActivity    *timestamp.Timestamp `protobuf:"bytes,9,opt,name=first_user_activity,json=firstUserActivity,proto3" json:"first_user_activity,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+// DescribeWorkspaceGroupResponse is the answer to a workspace group description request
+type DescribeWorkspaceGroupResponse struct {
+	// status are the status of all workspaces of the group
+	Status               []*WorkspaceStatus `protobuf:"bytes,1,rep,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
-func (m *WorkspaceConditions) Reset()         { *m = WorkspaceConditions{} }
-func (m *WorkspaceConditions) String() string { return proto.CompactTextString(m) }
-func (*WorkspaceConditions) ProtoMessage()    {}
-func (*WorkspaceConditions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{23}
+func (m *DescribeWorkspaceGroupResponse) Reset()         { *m = DescribeWorkspaceGroupResponse{} }
+func (m *DescribeWorkspaceGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*DescribeWorkspaceGroupResponse) ProtoMessage()    {}
+func (*DescribeWorkspaceGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{29}
 }
 
-func (m *WorkspaceConditions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WorkspaceConditions.Unmarshal(m, b)
+func (m *DescribeWorkspaceGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeWorkspaceGroupResponse.Unmarshal(m, b)
 }
-func (m *WorkspaceConditions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WorkspaceConditions.Marshal(b, m, deterministic)
+func (m *DescribeWorkspaceGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeWorkspaceGroupResponse.Marshal(b, m, deterministic)
 }
-func (m *WorkspaceConditions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkspaceConditions.Merge(m, src)
+func (m *DescribeWorkspaceGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeWorkspaceGroupResponse.Merge(m, src)
 }
-func (m *WorkspaceConditions) XXX_Size() int {
-	return xxx_messageInfo_WorkspaceConditions.Size(m)
+func (m *DescribeWorkspaceGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_DescribeWorkspaceGroupResponse.Size(m)
 }
-func (m *WorkspaceConditions) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkspaceConditions.DiscardUnknown(m)
+func (m *DescribeWorkspaceGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeWorkspaceGroupResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkspaceConditions proto.InternalMessageInfo
+var xxx_messageInfo_DescribeWorkspaceGroupResponse proto.InternalMessageInfo
 
-func (m *WorkspaceConditions) GetFailed() string {
+func (m *DescribeWorkspaceGroupResponse) GetStatus() []*WorkspaceStatus {
 	if m != nil {
-		return m.Failed
+		return m.Status
 	}
-	return ""
+	return nil
 }
 
-func (m *WorkspaceConditions) GetTimeout() string {
-	if m != nil {
-		return m.Timeout
-	}
-	return ""
+// ValidatePodTemplateRequest requests a dry-run check of a pod template
+type ValidatePodTemplateRequest struct {
+	// template is the pod template as YAML or JSON
+	Template             string   `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceConditions) GetPullingImages() WorkspaceConditionBool {
-	if m != nil {
-		return m.PullingImages
-	}
-	return WorkspaceConditionBool_FALSE
+func (m *ValidatePodTemplateRequest) Reset()         { *m = ValidatePodTemplateRequest{} }
+func (m *ValidatePodTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidatePodTemplateRequest) ProtoMessage()    {}
+func (*ValidatePodTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{30}
 }
 
-func (m *WorkspaceConditions) GetServiceExists() WorkspaceConditionBool {
-	if m != nil {
-		return m.ServiceExists
-	}
-	return WorkspaceConditionBool_FALSE
+func (m *ValidatePodTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidatePodTemplateRequest.Unmarshal(m, b)
+}
+func (m *ValidatePodTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidatePodTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidatePodTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidatePodTemplateRequest.Merge(m, src)
+}
+func (m *ValidatePodTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidatePodTemplateRequest.Size(m)
+}
+func (m *ValidatePodTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidatePodTemplateRequest.DiscardUnknown(m)
 }
 
-func (m *WorkspaceConditions) GetSnapshot() string {
+var xxx_messageInfo_ValidatePodTemplateRequest proto.InternalMessageInfo
+
+func (m *ValidatePodTemplateRequest) GetTemplate() string {
 	if m != nil {
-		return m.Snapshot
+		return m.Template
 	}
 	return ""
 }
 
-func (m *WorkspaceConditions) GetFinalBackupComplete() WorkspaceConditionBool {
-	if m != nil {
-		return m.FinalBackupComplete
-	}
-	return WorkspaceConditionBool_FALSE
+// ValidatePodTemplateResponse lists the violations of the pod template policy
+type ValidatePodTemplateResponse struct {
+	// violations is empty if the template complies with the policy
+	Violations           []*PodTemplateViolation `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
-func (m *WorkspaceConditions) GetDeployed() WorkspaceConditionBool {
-	if m != nil {
-		return m.Deployed
-	}
-	return WorkspaceConditionBool_FALSE
+func (m *ValidatePodTemplateResponse) Reset()         { *m = ValidatePodTemplateResponse{} }
+func (m *ValidatePodTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*ValidatePodTemplateResponse) ProtoMessage()    {}
+func (*ValidatePodTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{31}
 }
 
-func (m *WorkspaceConditions) GetNetworkNotReady() WorkspaceConditionBool {
-	if m != nil {
-		return m.NetworkNotReady
-	}
-	return WorkspaceConditionBool_FALSE
+func (m *ValidatePodTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidatePodTemplateResponse.Unmarshal(m, b)
+}
+func (m *ValidatePodTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidatePodTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *ValidatePodTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidatePodTemplateResponse.Merge(m, src)
+}
+func (m *ValidatePodTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_ValidatePodTemplateResponse.Size(m)
+}
+func (m *ValidatePodTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidatePodTemplateResponse.DiscardUnknown(m)
 }
 
-func (m *WorkspaceConditions) GetFirstUserActivity() *timestamp.Timestamp {
+var xxx_messageInfo_ValidatePodTemplateResponse proto.InternalMessageInfo
+
+func (m *ValidatePodTemplateResponse) GetViolations() []*PodTemplateViolation {
 	if m != nil {
-		return m.FirstUserActivity
+		return m.Violations
 	}
 	return nil
 }
 
-// WorkspaceMetadata is data associated with a workspace that's required for other parts of the system to function
-type WorkspaceMetadata struct {
-	// owner is the ID of the Gitpod user to whom we'll bill this workspace and who we consider responsible for its content
-	Owner string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
-	// meta_id is the workspace ID of this currently running workspace instance on the "meta pool" side
-	MetaId string `protobuf:"bytes,2,opt,name=meta_id,json=metaId,proto3" json:"meta_id,omitempty"`
-	// started_at is the time when this workspace was started. Consider this field read-only, i.e. setting in a request will have no effect.
-	StartedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+// PodTemplateViolation is a part of a pod template which violates the pod template policy
+type PodTemplateViolation struct {
+	// field is the path of the offending field, e.g. spec.containers[0].securityContext.privileged
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// message explains the violation
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceMetadata) Reset()         { *m = WorkspaceMetadata{} }
-func (m *WorkspaceMetadata) String() string { return proto.CompactTextString(m) }
-func (*WorkspaceMetadata) ProtoMessage()    {}
-func (*WorkspaceMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{24}
+func (m *PodTemplateViolation) Reset()         { *m = PodTemplateViolation{} }
+func (m *PodTemplateViolation) String() string { return proto.CompactTextString(m) }
+func (*PodTemplateViolation) ProtoMessage()    {}
+func (*PodTemplateViolation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{32}
 }
 
-func (m *WorkspaceMetadata) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WorkspaceMetadata.Unmarshal(m, b)
+func (m *PodTemplateViolation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodTemplateViolation.Unmarshal(m, b)
 }
-func (m *WorkspaceMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WorkspaceMetadata.Marshal(b, m, deterministic)
+func (m *PodTemplateViolation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodTemplateViolation.Marshal(b, m, deterministic)
 }
-func (m *WorkspaceMetadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkspaceMetadata.Merge(m, src)
+func (m *PodTemplateViolation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodTemplateViolation.Merge(m, src)
 }
-func (m *WorkspaceMetadata) XXX_Size() int {
-	return xxx_messageInfo_WorkspaceMetadata.Size(m)
+func (m *PodTemplateViolation) XXX_Size() int {
+	return xxx_messageInfo_PodTemplateViolation.Size(m)
 }
-func (m *WorkspaceMetadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkspaceMetadata.DiscardUnknown(m)
+func (m *PodTemplateViolation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodTemplateViolation.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkspaceMetadata proto.InternalMessageInfo
+var xxx_messageInfo_PodTemplateViolation proto.InternalMessageInfo
 
-func (m *WorkspaceMetadata) GetOwner() string {
+func (m *PodTemplateViolation) GetField() string {
 	if m != nil {
-		return m.Owner
+		return m.Field
 	}
 	return ""
 }
 
-func (m *WorkspaceMetadata) GetMetaId() string {
+func (m *PodTemplateViolation) GetMessage() string {
 	if m != nil {
-		return m.MetaId
+		return m.Message
 	}
 	return ""
 }
 
-func (m *WorkspaceMetadata) GetStartedAt() *timestamp.Timestamp {
+// ReportProxyActivityRequest reports the open connections of a proxy to workspaces.
+// Proxies may split their workspaces across several requests.
+type ReportProxyActivityRequest struct {
+	// proxy identifies the reporting proxy, e.g. its pod name
+	Proxy string `protobuf:"bytes,1,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	// workspaces lists the workspaces the proxy has connections to. Workspaces whose connections were closed
+	// since the last report are reported once with zero connections.
+	Workspaces           []*WorkspaceProxyActivity `protobuf:"bytes,2,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *ReportProxyActivityRequest) Reset()         { *m = ReportProxyActivityRequest{} }
+func (m *ReportProxyActivityRequest) String() string { return proto.CompactTextString(m) }
+func (*ReportProxyActivityRequest) ProtoMessage()    {}
+func (*ReportProxyActivityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{33}
+}
+
+func (m *ReportProxyActivityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportProxyActivityRequest.Unmarshal(m, b)
+}
+func (m *ReportProxyActivityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportProxyActivityRequest.Marshal(b, m, deterministic)
+}
+func (m *ReportProxyActivityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportProxyActivityRequest.Merge(m, src)
+}
+func (m *ReportProxyActivityRequest) XXX_Size() int {
+	return xxx_messageInfo_ReportProxyActivityRequest.Size(m)
+}
+func (m *ReportProxyActivityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportProxyActivityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportProxyActivityRequest proto.InternalMessageInfo
+
+func (m *ReportProxyActivityRequest) GetProxy() string {
 	if m != nil {
-		return m.StartedAt
+		return m.Proxy
+	}
+	return ""
+}
+
+func (m *ReportProxyActivityRequest) GetWorkspaces() []*WorkspaceProxyActivity {
+	if m != nil {
+		return m.Workspaces
 	}
 	return nil
 }
 
-// WorkspaceRuntimeInfo details the workspace's runtime, e.g. executing system, node other information
-// about the environment the workspace runs in. This information serves a diangostic purpose only and
-// should not be directly acted upon.
-type WorkspaceRuntimeInfo struct {
-	// node_name is the name of the node the workspace runs on
-	NodeName string `protobuf:"bytes,1,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
-	// pod_name is the name of the pod the workspace runs in
-	PodName string `protobuf:"bytes,2,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
-	// node_ip is the IP of the node the workspace runs on
-	NodeIp               string   `protobuf:"bytes,3,opt,name=node_ip,json=nodeIp,proto3" json:"node_ip,omitempty"`
+// WorkspaceProxyActivity are the connections a proxy has open to a workspace
+type WorkspaceProxyActivity struct {
+	// id is the ID of the workspace
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// connections is the number of requests to the workspace which are in flight
+	Connections uint32 `protobuf:"varint,2,opt,name=connections,proto3" json:"connections,omitempty"`
+	// sessions is the number of long-lived streams among them, e.g. websockets or event streams
+	Sessions             uint32   `protobuf:"varint,3,opt,name=sessions,proto3" json:"sessions,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceRuntimeInfo) Reset()         { *m = WorkspaceRuntimeInfo{} }
-func (m *WorkspaceRuntimeInfo) String() string { return proto.CompactTextString(m) }
-func (*WorkspaceRuntimeInfo) ProtoMessage()    {}
-func (*WorkspaceRuntimeInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{25}
+func (m *WorkspaceProxyActivity) Reset()         { *m = WorkspaceProxyActivity{} }
+func (m *WorkspaceProxyActivity) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceProxyActivity) ProtoMessage()    {}
+func (*WorkspaceProxyActivity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{34}
 }
 
-func (m *WorkspaceRuntimeInfo) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WorkspaceRuntimeInfo.Unmarshal(m, b)
+func (m *WorkspaceProxyActivity) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceProxyActivity.Unmarshal(m, b)
 }
-func (m *WorkspaceRuntimeInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WorkspaceRuntimeInfo.Marshal(b, m, deterministic)
+func (m *WorkspaceProxyActivity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceProxyActivity.Marshal(b, m, deterministic)
 }
-func (m *WorkspaceRuntimeInfo) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkspaceRuntimeInfo.Merge(m, src)
+func (m *WorkspaceProxyActivity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceProxyActivity.Merge(m, src)
 }
-func (m *WorkspaceRuntimeInfo) XXX_Size() int {
-	return xxx_messageInfo_WorkspaceRuntimeInfo.Size(m)
+func (m *WorkspaceProxyActivity) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceProxyActivity.Size(m)
 }
-func (m *WorkspaceRuntimeInfo) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkspaceRuntimeInfo.DiscardUnknown(m)
+func (m *WorkspaceProxyActivity) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceProxyActivity.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkspaceRuntimeInfo proto.InternalMessageInfo
+var xxx_messageInfo_WorkspaceProxyActivity proto.InternalMessageInfo
 
-func (m *WorkspaceRuntimeInfo) GetNodeName() string {
+func (m *WorkspaceProxyActivity) GetId() string {
 	if m != nil {
-		return m.NodeName
+		return m.Id
 	}
 	return ""
 }
 
-func (m *WorkspaceRuntimeInfo) GetPodName() string {
+func (m *WorkspaceProxyActivity) GetConnections() uint32 {
 	if m != nil {
-		return m.PodName
+		return m.Connections
 	}
-	return ""
+	return 0
 }
 
-func (m *WorkspaceRuntimeInfo) GetNodeIp() string {
+func (m *WorkspaceProxyActivity) GetSessions() uint32 {
 	if m != nil {
-		return m.NodeIp
+		return m.Sessions
 	}
-	return ""
+	return 0
 }
 
-// WorkspaceAuthentication contains authentication information used by ws-proxy to allow/deny access to
-// workspaces and their ports.
-type WorkspaceAuthentication struct {
-	// Admission describes who can access the workspace and its ports.
-	Admission AdmissionLevel `protobuf:"varint,1,opt,name=admission,proto3,enum=wsman.AdmissionLevel" json:"admission,omitempty"`
-	// Owner token is the token one needs to access the workspace. Its presence is checked by ws-proxy.
-	OwnerToken           string   `protobuf:"bytes,2,opt,name=owner_token,json=ownerToken,proto3" json:"owner_token,omitempty"`
+// ReportProxyActivityResponse is the answer to a proxy activity report
+type ReportProxyActivityResponse struct {
+	// min_interval is how long the proxy should wait before it reports again. Must be a valid Go duration (see https://golang.org/pkg/time/#ParseDuration)
+	MinInterval          string   `protobuf:"bytes,1,opt,name=min_interval,json=minInterval,proto3" json:"min_interval,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceAuthentication) Reset()         { *m = WorkspaceAuthentication{} }
-func (m *WorkspaceAuthentication) String() string { return proto.CompactTextString(m) }
-func (*WorkspaceAuthentication) ProtoMessage()    {}
-func (*WorkspaceAuthentication) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{26}
+func (m *ReportProxyActivityResponse) Reset()         { *m = ReportProxyActivityResponse{} }
+func (m *ReportProxyActivityResponse) String() string { return proto.CompactTextString(m) }
+func (*ReportProxyActivityResponse) ProtoMessage()    {}
+func (*ReportProxyActivityResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{35}
 }
 
-func (m *WorkspaceAuthentication) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WorkspaceAuthentication.Unmarshal(m, b)
+func (m *ReportProxyActivityResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportProxyActivityResponse.Unmarshal(m, b)
 }
-func (m *WorkspaceAuthentication) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WorkspaceAuthentication.Marshal(b, m, deterministic)
+func (m *ReportProxyActivityResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportProxyActivityResponse.Marshal(b, m, deterministic)
 }
-func (m *WorkspaceAuthentication) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkspaceAuthentication.Merge(m, src)
+func (m *ReportProxyActivityResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportProxyActivityResponse.Merge(m, src)
 }
-func (m *WorkspaceAuthentication) XXX_Size() int {
-	return xxx_messageInfo_WorkspaceAuthentication.Size(m)
+func (m *ReportProxyActivityResponse) XXX_Size() int {
+	return xxx_messageInfo_ReportProxyActivityResponse.Size(m)
 }
-func (m *WorkspaceAuthentication) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkspaceAuthentication.DiscardUnknown(m)
+func (m *ReportProxyActivityResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportProxyActivityResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkspaceAuthentication proto.InternalMessageInfo
-
-func (m *WorkspaceAuthentication) GetAdmission() AdmissionLevel {
-	if m != nil {
-		return m.Admission
-	}
-	return AdmissionLevel_ADMIT_OWNER_ONLY
-}
+var xxx_messageInfo_ReportProxyActivityResponse proto.InternalMessageInfo
 
-func (m *WorkspaceAuthentication) GetOwnerToken() string {
+func (m *ReportProxyActivityResponse) GetMinInterval() string {
 	if m != nil {
-		return m.OwnerToken
+		return m.MinInterval
 	}
 	return ""
 }
 
-// StartWorkspaceSpec specifies the configuration of a workspace for a workspace start
-type StartWorkspaceSpec struct {
-	// workspace_image is the Docker image name of the workspace container
-	WorkspaceImage string `protobuf:"bytes,1,opt,name=workspace_image,json=workspaceImage,proto3" json:"workspace_image,omitempty"`
-	// ide_image is the Docker image name of the IDE image
-	IdeImage string `protobuf:"bytes,2,opt,name=ide_image,json=ideImage,proto3" json:"ide_image,omitempty"`
-	// feature_flags provide a means for starting variants of workspaces (e.g. a privileged one)
-	FeatureFlags []WorkspaceFeatureFlag `protobuf:"varint,3,rep,packed,name=feature_flags,json=featureFlags,proto3,enum=wsman.WorkspaceFeatureFlag" json:"feature_flags,omitempty"`
-	// initializer configures how the workspace is to be initialized
-	Initializer *api.WorkspaceInitializer `protobuf:"bytes,4,opt,name=initializer,proto3" json:"initializer,omitempty"`
-	// ports is the set of ports which ought to be exposed to the internet
-	Ports []*PortSpec `protobuf:"bytes,5,rep,name=ports,proto3" json:"ports,omitempty"`
-	// envvars are user-defined environment variables which ought to be available in the workspace
-	Envvars []*EnvironmentVariable `protobuf:"bytes,6,rep,name=envvars,proto3" json:"envvars,omitempty"`
-	// checkout_location describes where the code has been checked out to
-	CheckoutLocation string `protobuf:"bytes,7,opt,name=checkout_location,json=checkoutLocation,proto3" json:"checkout_location,omitempty"`
-	// workspace_location describes where the workspace root of Theia will be
-	WorkspaceLocation string `protobuf:"bytes,8,opt,name=workspace_location,json=workspaceLocation,proto3" json:"workspace_location,omitempty"`
-	// Git configures the Git user in the workspace
-	Git *GitSpec `protobuf:"bytes,9,opt,name=git,proto3" json:"git,omitempty"`
-	// timeout optionally sets a custom workspace timeout
-	Timeout string `protobuf:"bytes,10,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	// admission controlls who can access the workspace and its ports.
-	Admission            AdmissionLevel `protobuf:"varint,11,opt,name=admission,proto3,enum=wsman.AdmissionLevel" json:"admission,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+// SubscribeAccountingRequest starts a stream of accounting records.
+// To process every record exactly once, consumers store the sequence of the last record they processed together
+// with the outcome of processing it, resume from that sequence after a restart and acknowledge it periodically.
+type SubscribeAccountingRequest struct {
+	// after_sequence is the sequence of the last record the consumer processed. The stream starts with the next record.
+	// Zero starts with the oldest record ws-manager retains.
+	AfterSequence        uint64   `protobuf:"varint,1,opt,name=after_sequence,json=afterSequence,proto3" json:"after_sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StartWorkspaceSpec) Reset()         { *m = StartWorkspaceSpec{} }
-func (m *StartWorkspaceSpec) String() string { return proto.CompactTextString(m) }
-func (*StartWorkspaceSpec) ProtoMessage()    {}
-func (*StartWorkspaceSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{27}
+func (m *SubscribeAccountingRequest) Reset()         { *m = SubscribeAccountingRequest{} }
+func (m *SubscribeAccountingRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeAccountingRequest) ProtoMessage()    {}
+func (*SubscribeAccountingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{36}
 }
 
-func (m *StartWorkspaceSpec) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StartWorkspaceSpec.Unmarshal(m, b)
+func (m *SubscribeAccountingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeAccountingRequest.Unmarshal(m, b)
 }
-func (m *StartWorkspaceSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StartWorkspaceSpec.Marshal(b, m, deterministic)
+func (m *SubscribeAccountingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeAccountingRequest.Marshal(b, m, deterministic)
 }
-func (m *StartWorkspaceSpec) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StartWorkspaceSpec.Merge(m, src)
+func (m *SubscribeAccountingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeAccountingRequest.Merge(m, src)
 }
-func (m *StartWorkspaceSpec) XXX_Size() int {
-	return xxx_messageInfo_StartWorkspaceSpec.Size(m)
+func (m *SubscribeAccountingRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeAccountingRequest.Size(m)
 }
-func (m *StartWorkspaceSpec) XXX_DiscardUnknown() {
-	xxx_messageInfo_StartWorkspaceSpec.DiscardUnknown(m)
+func (m *SubscribeAccountingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeAccountingRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_StartWorkspaceSpec proto.InternalMessageInfo
+var xxx_messageInfo_SubscribeAccountingRequest proto.InternalMessageInfo
 
-func (m *StartWorkspaceSpec) GetWorkspaceImage() string {
+func (m *SubscribeAccountingRequest) GetAfterSequence() uint64 {
 	if m != nil {
-		return m.WorkspaceImage
+		return m.AfterSequence
 	}
-	return ""
+	return 0
 }
 
-func (m *StartWorkspaceSpec) GetIdeImage() string {
-	if m != nil {
-		return m.IdeImage
-	}
-	return ""
+// AccountingRecord is the authoritative record of a workspace instance starting or stopping
+type AccountingRecord struct {
+	// sequence identifies the record. Sequences increase by one from record to record without gaps.
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// event is what happened to the instance
+	Event AccountingEvent `protobuf:"varint,2,opt,name=event,proto3,enum=wsman.AccountingEvent" json:"event,omitempty"`
+	// id is the ID of the workspace instance
+	Id string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// metadata is the metadata of the workspace instance, e.g. its owner
+	Metadata *WorkspaceMetadata `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// type is the class of the workspace instance
+	Type WorkspaceType `protobuf:"varint,5,opt,name=type,proto3,enum=wsman.WorkspaceType" json:"type,omitempty"`
+	// node_name is the name of the node the instance runs on
+	NodeName string `protobuf:"bytes,6,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	// time is when the event happened
+	Time *timestamp.Timestamp `protobuf:"bytes,7,opt,name=time,proto3" json:"time,omitempty"`
+	// runtime_seconds is the time the instance ran for. Only set for INSTANCE_STOPPED.
+	RuntimeSeconds uint64 `protobuf:"varint,8,opt,name=runtime_seconds,json=runtimeSeconds,proto3" json:"runtime_seconds,omitempty"`
+	// estimated is true if ws-manager did not see the event happen, e.g. because it was not running when the
+	// instance stopped. Time is when ws-manager noticed the event then.
+	Estimated            bool     `protobuf:"varint,9,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StartWorkspaceSpec) GetFeatureFlags() []WorkspaceFeatureFlag {
+func (m *AccountingRecord) Reset()         { *m = AccountingRecord{} }
+func (m *AccountingRecord) String() string { return proto.CompactTextString(m) }
+func (*AccountingRecord) ProtoMessage()    {}
+func (*AccountingRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{37}
+}
+
+func (m *AccountingRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountingRecord.Unmarshal(m, b)
+}
+func (m *AccountingRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountingRecord.Marshal(b, m, deterministic)
+}
+func (m *AccountingRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountingRecord.Merge(m, src)
+}
+func (m *AccountingRecord) XXX_Size() int {
+	return xxx_messageInfo_AccountingRecord.Size(m)
+}
+func (m *AccountingRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountingRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountingRecord proto.InternalMessageInfo
+
+func (m *AccountingRecord) GetSequence() uint64 {
 	if m != nil {
-		return m.FeatureFlags
+		return m.Sequence
 	}
-	return nil
+	return 0
 }
 
-func (m *StartWorkspaceSpec) GetInitializer() *api.WorkspaceInitializer {
+func (m *AccountingRecord) GetEvent() AccountingEvent {
 	if m != nil {
-		return m.Initializer
+		return m.Event
 	}
-	return nil
+	return AccountingEvent_INSTANCE_STARTED
 }
 
-func (m *StartWorkspaceSpec) GetPorts() []*PortSpec {
+func (m *AccountingRecord) GetId() string {
 	if m != nil {
-		return m.Ports
+		return m.Id
 	}
-	return nil
+	return ""
 }
 
-func (m *StartWorkspaceSpec) GetEnvvars() []*EnvironmentVariable {
+func (m *AccountingRecord) GetMetadata() *WorkspaceMetadata {
 	if m != nil {
-		return m.Envvars
+		return m.Metadata
 	}
 	return nil
 }
 
-func (m *StartWorkspaceSpec) GetCheckoutLocation() string {
+func (m *AccountingRecord) GetType() WorkspaceType {
 	if m != nil {
-		return m.CheckoutLocation
+		return m.Type
 	}
-	return ""
+	return WorkspaceType_REGULAR
 }
 
-func (m *StartWorkspaceSpec) GetWorkspaceLocation() string {
+func (m *AccountingRecord) GetNodeName() string {
 	if m != nil {
-		return m.WorkspaceLocation
+		return m.NodeName
 	}
 	return ""
 }
 
-func (m *StartWorkspaceSpec) GetGit() *GitSpec {
+func (m *AccountingRecord) GetTime() *timestamp.Timestamp {
 	if m != nil {
-		return m.Git
+		return m.Time
 	}
 	return nil
 }
 
-func (m *StartWorkspaceSpec) GetTimeout() string {
+func (m *AccountingRecord) GetRuntimeSeconds() uint64 {
 	if m != nil {
-		return m.Timeout
+		return m.RuntimeSeconds
 	}
-	return ""
+	return 0
 }
 
-func (m *StartWorkspaceSpec) GetAdmission() AdmissionLevel {
+func (m *AccountingRecord) GetEstimated() bool {
 	if m != nil {
-		return m.Admission
+		return m.Estimated
 	}
-	return AdmissionLevel_ADMIT_OWNER_ONLY
+	return false
 }
 
-// GitSpec configures the Git available within the workspace
-type GitSpec struct {
-	// The Git username
-	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	// The Git email address
-	Email                string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+// AckAccountingRequest acknowledges accounting records
+type AckAccountingRequest struct {
+	// sequence acknowledges all records up to and including this one
+	Sequence             uint64   `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GitSpec) Reset()         { *m = GitSpec{} }
-func (m *GitSpec) String() string { return proto.CompactTextString(m) }
-func (*GitSpec) ProtoMessage()    {}
-func (*GitSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{28}
+func (m *AckAccountingRequest) Reset()         { *m = AckAccountingRequest{} }
+func (m *AckAccountingRequest) String() string { return proto.CompactTextString(m) }
+func (*AckAccountingRequest) ProtoMessage()    {}
+func (*AckAccountingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{38}
 }
 
-func (m *GitSpec) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GitSpec.Unmarshal(m, b)
+func (m *AckAccountingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AckAccountingRequest.Unmarshal(m, b)
 }
-func (m *GitSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GitSpec.Marshal(b, m, deterministic)
+func (m *AckAccountingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AckAccountingRequest.Marshal(b, m, deterministic)
 }
-func (m *GitSpec) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GitSpec.Merge(m, src)
+func (m *AckAccountingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AckAccountingRequest.Merge(m, src)
 }
-func (m *GitSpec) XXX_Size() int {
-	return xxx_messageInfo_GitSpec.Size(m)
+func (m *AckAccountingRequest) XXX_Size() int {
+	return xxx_messageInfo_AckAccountingRequest.Size(m)
 }
-func (m *GitSpec) XXX_DiscardUnknown() {
-	xxx_messageInfo_GitSpec.DiscardUnknown(m)
+func (m *AckAccountingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AckAccountingRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GitSpec proto.InternalMessageInfo
-
-func (m *GitSpec) GetUsername() string {
-	if m != nil {
-		return m.Username
-	}
-	return ""
-}
+var xxx_messageInfo_AckAccountingRequest proto.InternalMessageInfo
 
-func (m *GitSpec) GetEmail() string {
+func (m *AckAccountingRequest) GetSequence() uint64 {
 	if m != nil {
-		return m.Email
+		return m.Sequence
 	}
-	return ""
+	return 0
 }
 
-// EnvironmentVariable describes an env var as key/value pair
-type EnvironmentVariable struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+// AckAccountingResponse is the answer to an acknowledgement
+type AckAccountingResponse struct {
+	// acknowledged is the sequence of the last acknowledged record, which can be greater than the acknowledged one
+	// if another consumer acknowledged more records already
+	Acknowledged         uint64   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *EnvironmentVariable) Reset()         { *m = EnvironmentVariable{} }
-func (m *EnvironmentVariable) String() string { return proto.CompactTextString(m) }
-func (*EnvironmentVariable) ProtoMessage()    {}
-func (*EnvironmentVariable) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{29}
+func (m *AckAccountingResponse) Reset()         { *m = AckAccountingResponse{} }
+func (m *AckAccountingResponse) String() string { return proto.CompactTextString(m) }
+func (*AckAccountingResponse) ProtoMessage()    {}
+func (*AckAccountingResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{39}
 }
 
-func (m *EnvironmentVariable) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_EnvironmentVariable.Unmarshal(m, b)
+func (m *AckAccountingResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AckAccountingResponse.Unmarshal(m, b)
 }
-func (m *EnvironmentVariable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_EnvironmentVariable.Marshal(b, m, deterministic)
+func (m *AckAccountingResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AckAccountingResponse.Marshal(b, m, deterministic)
 }
-func (m *EnvironmentVariable) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_EnvironmentVariable.Merge(m, src)
+func (m *AckAccountingResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AckAccountingResponse.Merge(m, src)
 }
-func (m *EnvironmentVariable) XXX_Size() int {
-	return xxx_messageInfo_EnvironmentVariable.Size(m)
+func (m *AckAccountingResponse) XXX_Size() int {
+	return xxx_messageInfo_AckAccountingResponse.Size(m)
 }
-func (m *EnvironmentVariable) XXX_DiscardUnknown() {
-	xxx_messageInfo_EnvironmentVariable.DiscardUnknown(m)
+func (m *AckAccountingResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AckAccountingResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_EnvironmentVariable proto.InternalMessageInfo
-
-func (m *EnvironmentVariable) GetName() string {
-	if m != nil {
-		return m.Name
-	}
-	return ""
-}
+var xxx_messageInfo_AckAccountingResponse proto.InternalMessageInfo
 
-func (m *EnvironmentVariable) GetValue() string {
+func (m *AckAccountingResponse) GetAcknowledged() uint64 {
 	if m != nil {
-		return m.Value
+		return m.Acknowledged
 	}
-	return ""
+	return 0
 }
 
-// HeadlessWorkspaceEvent is a log statement issued by a headless workspace
-type WorkspaceLogMessage struct {
-	// ID is the ID of the workspace this event eminated from
+// ScheduleStopRequest schedules, re-schedules or cancels the stop of a running workspace
+type ScheduleStopRequest struct {
+	// id is the ID of the workspace
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// metadata is the workspace metadata
-	Metadata *WorkspaceMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	// Message is the payload associated with this event
-	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// at is when the workspace stops. Either at or duration must be set unless the request cancels the scheduled stop.
+	At *timestamp.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	// duration is the time from now until the workspace stops. Must be a valid Go duration (see https://golang.org/pkg/time/#ParseDuration)
+	Duration string `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// cancel cancels the scheduled stop of the workspace
+	Cancel bool `protobuf:"varint,4,opt,name=cancel,proto3" json:"cancel,omitempty"`
+	// expected_generation, if non-zero, makes this request conditional on the workspace's status generation.
+	// If the workspace has moved on since, the request fails with ABORTED and nothing is changed.
+	ExpectedGeneration   uint64   `protobuf:"varint,5,opt,name=expected_generation,json=expectedGeneration,proto3" json:"expected_generation,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *WorkspaceLogMessage) Reset()         { *m = WorkspaceLogMessage{} }
-func (m *WorkspaceLogMessage) String() string { return proto.CompactTextString(m) }
-func (*WorkspaceLogMessage) ProtoMessage()    {}
-func (*WorkspaceLogMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f7e43720d1edc0fe, []int{30}
+func (m *ScheduleStopRequest) Reset()         { *m = ScheduleStopRequest{} }
+func (m *ScheduleStopRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleStopRequest) ProtoMessage()    {}
+func (*ScheduleStopRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{40}
 }
 
-func (m *WorkspaceLogMessage) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_WorkspaceLogMessage.Unmarshal(m, b)
+func (m *ScheduleStopRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleStopRequest.Unmarshal(m, b)
 }
-func (m *WorkspaceLogMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_WorkspaceLogMessage.Marshal(b, m, deterministic)
+func (m *ScheduleStopRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleStopRequest.Marshal(b, m, deterministic)
 }
-func (m *WorkspaceLogMessage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkspaceLogMessage.Merge(m, src)
+func (m *ScheduleStopRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleStopRequest.Merge(m, src)
 }
-func (m *WorkspaceLogMessage) XXX_Size() int {
-	return xxx_messageInfo_WorkspaceLogMessage.Size(m)
+func (m *ScheduleStopRequest) XXX_Size() int {
+	return xxx_messageInfo_ScheduleStopRequest.Size(m)
 }
-func (m *WorkspaceLogMessage) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkspaceLogMessage.DiscardUnknown(m)
+func (m *ScheduleStopRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleStopRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkspaceLogMessage proto.InternalMessageInfo
+var xxx_messageInfo_ScheduleStopRequest proto.InternalMessageInfo
 
-func (m *WorkspaceLogMessage) GetId() string {
+func (m *ScheduleStopRequest) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-func (m *WorkspaceLogMessage) GetMetadata() *WorkspaceMetadata {
+func (m *ScheduleStopRequest) GetAt() *timestamp.Timestamp {
 	if m != nil {
-		return m.Metadata
+		return m.At
 	}
 	return nil
 }
 
-func (m *WorkspaceLogMessage) GetMessage() string {
+func (m *ScheduleStopRequest) GetDuration() string {
 	if m != nil {
-		return m.Message
+		return m.Duration
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterEnum("wsman.StopWorkspacePolicy", StopWorkspacePolicy_name, StopWorkspacePolicy_value)
-	proto.RegisterEnum("wsman.AdmissionLevel", AdmissionLevel_name, AdmissionLevel_value)
-	proto.RegisterEnum("wsman.PortVisibility", PortVisibility_name, PortVisibility_value)
-	proto.RegisterEnum("wsman.WorkspaceConditionBool", WorkspaceConditionBool_name, WorkspaceConditionBool_value)
-	proto.RegisterEnum("wsman.WorkspacePhase", WorkspacePhase_name, WorkspacePhase_value)
-	proto.RegisterEnum("wsman.WorkspaceFeatureFlag", WorkspaceFeatureFlag_name, WorkspaceFeatureFlag_value)
-	proto.RegisterEnum("wsman.WorkspaceType", WorkspaceType_name, WorkspaceType_value)
-	proto.RegisterType((*GetWorkspacesRequest)(nil), "wsman.GetWorkspacesRequest")
-	proto.RegisterType((*GetWorkspacesResponse)(nil), "wsman.GetWorkspacesResponse")
-	proto.RegisterType((*StartWorkspaceRequest)(nil), "wsman.StartWorkspaceRequest")
-	proto.RegisterType((*StartWorkspaceResponse)(nil), "wsman.StartWorkspaceResponse")
-	proto.RegisterType((*StopWorkspaceRequest)(nil), "wsman.StopWorkspaceRequest")
-	proto.RegisterType((*StopWorkspaceResponse)(nil), "wsman.StopWorkspaceResponse")
-	proto.RegisterType((*DescribeWorkspaceRequest)(nil), "wsman.DescribeWorkspaceRequest")
-	proto.RegisterType((*DescribeWorkspaceResponse)(nil), "wsman.DescribeWorkspaceResponse")
-	proto.RegisterType((*SubscribeRequest)(nil), "wsman.SubscribeRequest")
-	proto.RegisterType((*SubscribeResponse)(nil), "wsman.SubscribeResponse")
-	proto.RegisterMapType((map[string]string)(nil), "wsman.SubscribeResponse.HeaderEntry")
-	proto.RegisterType((*MarkActiveRequest)(nil), "wsman.MarkActiveRequest")
-	proto.RegisterType((*MarkActiveResponse)(nil), "wsman.MarkActiveResponse")
-	proto.RegisterType((*SetTimeoutRequest)(nil), "wsman.SetTimeoutRequest")
-	proto.RegisterType((*SetTimeoutResponse)(nil), "wsman.SetTimeoutResponse")
-	proto.RegisterType((*ControlPortRequest)(nil), "wsman.ControlPortRequest")
-	proto.RegisterType((*ControlPortResponse)(nil), "wsman.ControlPortResponse")
-	proto.RegisterType((*TakeSnapshotRequest)(nil), "wsman.TakeSnapshotRequest")
-	proto.RegisterType((*TakeSnapshotResponse)(nil), "wsman.TakeSnapshotResponse")
-	proto.RegisterType((*ControlAdmissionRequest)(nil), "wsman.ControlAdmissionRequest")
-	proto.RegisterType((*ControlAdmissionResponse)(nil), "wsman.ControlAdmissionResponse")
-	proto.RegisterType((*WorkspaceStatus)(nil), "wsman.WorkspaceStatus")
-	proto.RegisterType((*WorkspaceSpec)(nil), "wsman.WorkspaceSpec")
-	proto.RegisterType((*PortSpec)(nil), "wsman.PortSpec")
-	proto.RegisterType((*WorkspaceConditions)(nil), "wsman.WorkspaceConditions")
-	proto.RegisterType((*WorkspaceMetadata)(nil), "wsman.WorkspaceMetadata")
-	proto.RegisterType((*WorkspaceRuntimeInfo)(nil), "wsman.WorkspaceRuntimeInfo")
-	proto.RegisterType((*WorkspaceAuthentication)(nil), "wsman.WorkspaceAuthentication")
-	proto.RegisterType((*StartWorkspaceSpec)(nil), "wsman.StartWorkspaceSpec")
-	proto.RegisterType((*GitSpec)(nil), "wsman.GitSpec")
-	proto.RegisterType((*EnvironmentVariable)(nil), "wsman.EnvironmentVariable")
-	proto.RegisterType((*WorkspaceLogMessage)(nil), "wsman.WorkspaceLogMessage")
+func (m *ScheduleStopRequest) GetCancel() bool {
+	if m != nil {
+		return m.Cancel
+	}
+	return false
 }
 
-func init() {
-	proto.RegisterFile("core.proto", fileDescriptor_f7e43720d1edc0fe)
+func (m *ScheduleStopRequest) GetExpectedGeneration() uint64 {
+	if m != nil {
+		return m.ExpectedGeneration
+	}
+	return 0
 }
 
-var fileDescriptor_f7e43720d1edc0fe = []byte{
-	// 2079 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xdd, 0x6f, 0xdb, 0xc8,
-	0x11, 0xb7, 0x2c, 0x59, 0x96, 0xc6, 0xb6, 0x4c, 0xaf, 0xbf, 0x14, 0xe5, 0xee, 0x62, 0xb0, 0x17,
-	0xd4, 0xf0, 0xd5, 0xf6, 0xc1, 0x97, 0x03, 0x2e, 0xb9, 0x02, 0x77, 0xb2, 0x4d, 0x3b, 0xbc, 0xc8,
-	0x92, 0xba, 0x92, 0x9c, 0x4b, 0x5e, 0x88, 0xb5, 0xb8, 0x96, 0x09, 0x53, 0x5c, 0x96, 0x5c, 0x39,
-	0x51, 0x81, 0xa2, 0x0f, 0x7d, 0x2f, 0x50, 0xa0, 0xcf, 0xfd, 0xe7, 0xfa, 0x57, 0xf4, 0xa1, 0x40,
-	0xb1, 0xcb, 0x25, 0x45, 0x7d, 0xc5, 0x79, 0xb8, 0x37, 0xce, 0xce, 0x6f, 0x3e, 0x76, 0x76, 0x66,
-	0x76, 0x96, 0x00, 0x5d, 0x16, 0xd0, 0x23, 0x3f, 0x60, 0x9c, 0xa1, 0xa5, 0x0f, 0x61, 0x9f, 0x78,
-	0x95, 0xe7, 0x5d, 0xe6, 0x71, 0xea, 0xf1, 0xc3, 0x90, 0x06, 0x0f, 0x4e, 0x97, 0x1e, 0x12, 0xdf,
-	0x39, 0x76, 0x3c, 0x87, 0x3b, 0xc4, 0x75, 0xfe, 0x42, 0x83, 0x08, 0x5d, 0x79, 0xd6, 0x63, 0xac,
-	0xe7, 0xd2, 0x63, 0x49, 0xdd, 0x0c, 0x6e, 0x8f, 0xb9, 0xd3, 0xa7, 0x21, 0x27, 0x7d, 0x3f, 0x02,
-	0xe8, 0x3b, 0xb0, 0x75, 0x49, 0xf9, 0x5b, 0x16, 0xdc, 0x87, 0x3e, 0xe9, 0xd2, 0x10, 0xd3, 0x3f,
-	0x0f, 0x68, 0xc8, 0xf5, 0x4b, 0xd8, 0x9e, 0x58, 0x0f, 0x7d, 0xe6, 0x85, 0x14, 0x1d, 0x41, 0x3e,
-	0xe4, 0x84, 0x0f, 0xc2, 0x72, 0x66, 0x2f, 0xbb, 0xbf, 0x72, 0xb2, 0x73, 0x24, 0x1d, 0x3a, 0x4a,
-	0xa0, 0x2d, 0xc9, 0xc5, 0x0a, 0xa5, 0xff, 0x27, 0x03, 0xdb, 0x2d, 0x4e, 0x82, 0x91, 0x2e, 0x65,
-	0x02, 0x95, 0x60, 0xd1, 0xb1, 0xcb, 0x99, 0xbd, 0xcc, 0x7e, 0x11, 0x2f, 0x3a, 0x36, 0x7a, 0x0e,
-	0x25, 0xb5, 0x19, 0xcb, 0x0f, 0xe8, 0xad, 0xf3, 0xb1, 0xbc, 0x28, 0x79, 0x6b, 0x6a, 0xb5, 0x29,
-	0x17, 0xd1, 0x0b, 0x28, 0xf4, 0x29, 0x27, 0x36, 0xe1, 0xa4, 0x9c, 0xdd, 0xcb, 0xec, 0xaf, 0x9c,
-	0x94, 0x27, 0x5d, 0xb8, 0x52, 0x7c, 0x9c, 0x20, 0xd1, 0x21, 0xe4, 0x42, 0x9f, 0x76, 0xcb, 0x39,
-	0x29, 0xf1, 0x44, 0x49, 0x8c, 0x3b, 0xd6, 0xf2, 0x69, 0x17, 0x4b, 0x18, 0xda, 0x87, 0x1c, 0x1f,
-	0xfa, 0xb4, 0x9c, 0xdf, 0xcb, 0xec, 0x97, 0x4e, 0xb6, 0x26, 0x0d, 0xb4, 0x87, 0x3e, 0xc5, 0x12,
-	0xf1, 0x4b, 0xae, 0xb0, 0xa4, 0xe5, 0xf5, 0x03, 0xd8, 0x99, 0xdc, 0xa4, 0x8a, 0x97, 0x06, 0xd9,
-	0x41, 0xe0, 0xaa, 0x6d, 0x8a, 0x4f, 0xfd, 0x3d, 0x6c, 0xb5, 0x38, 0xf3, 0x1f, 0x8d, 0xc7, 0x09,
-	0xe4, 0x7d, 0xe6, 0x3a, 0xdd, 0xa1, 0x8c, 0x43, 0xe9, 0xa4, 0x92, 0x38, 0x9d, 0x12, 0x6e, 0x4a,
-	0x04, 0x56, 0x48, 0x7d, 0x57, 0x04, 0x7b, 0x4c, 0x77, 0xe4, 0x86, 0x7e, 0x00, 0xe5, 0x73, 0x1a,
-	0x76, 0x03, 0xe7, 0x86, 0x3e, 0x66, 0x58, 0x67, 0xf0, 0x64, 0x06, 0x76, 0xc6, 0xf9, 0x67, 0x1e,
-	0x3f, 0x7f, 0xa4, 0xc3, 0xaa, 0x4b, 0x42, 0x5e, 0xed, 0x72, 0xe7, 0xc1, 0xe1, 0x43, 0x75, 0xa6,
-	0x63, 0x6b, 0x3a, 0x02, 0xad, 0x35, 0xb8, 0x89, 0x2c, 0xc6, 0x09, 0xf8, 0xdf, 0x0c, 0x6c, 0xa4,
-	0x16, 0x95, 0xf5, 0x6f, 0x3f, 0xcf, 0xfa, 0xeb, 0x85, 0xc4, 0xfe, 0x11, 0x64, 0x5d, 0xd6, 0x93,
-	0x66, 0x57, 0x92, 0x10, 0x26, 0xf0, 0x1a, 0xeb, 0x5d, 0xd1, 0x30, 0x24, 0x3d, 0xfa, 0x7a, 0x01,
-	0x0b, 0x20, 0xfa, 0x23, 0xe4, 0xef, 0x28, 0xb1, 0x69, 0x50, 0xce, 0xca, 0xfc, 0xfe, 0x3a, 0x8e,
-	0xfa, 0xa4, 0x2f, 0x47, 0xaf, 0x25, 0xcc, 0xf0, 0x78, 0x30, 0xc4, 0x4a, 0xa6, 0xf2, 0x12, 0x56,
-	0x52, 0xcb, 0xe2, 0xf0, 0xef, 0xe9, 0x30, 0x3e, 0xfc, 0x7b, 0x3a, 0x44, 0x5b, 0xb0, 0xf4, 0x40,
-	0xdc, 0x01, 0x55, 0x71, 0x88, 0x88, 0x57, 0x8b, 0x3f, 0x64, 0x4e, 0x8b, 0xb0, 0xec, 0x93, 0xa1,
-	0xcb, 0x88, 0xad, 0xff, 0x08, 0x1b, 0x57, 0x24, 0xb8, 0x97, 0xf1, 0x99, 0x9b, 0x1e, 0x3b, 0x90,
-	0xef, 0xba, 0x2c, 0xa4, 0xb6, 0x54, 0x55, 0xc0, 0x8a, 0xd2, 0xb7, 0x00, 0xa5, 0x85, 0xd5, 0xf9,
-	0xff, 0x04, 0x1b, 0x2d, 0xca, 0xdb, 0x4e, 0x9f, 0xb2, 0x01, 0x9f, 0xa7, 0xb2, 0x02, 0x05, 0x7b,
-	0x10, 0x10, 0xee, 0x30, 0x4f, 0xf9, 0x97, 0xd0, 0x42, 0x6d, 0x5a, 0x81, 0x52, 0x4b, 0x00, 0x9d,
-	0x31, 0x8f, 0x07, 0xcc, 0x6d, 0xb2, 0x80, 0x7f, 0xc2, 0x55, 0xfa, 0xd1, 0x67, 0x21, 0x8d, 0x5d,
-	0x8d, 0x28, 0xf4, 0x3b, 0x55, 0x94, 0x51, 0x19, 0xaf, 0xab, 0x48, 0x0b, 0x4d, 0xa3, 0x52, 0xd4,
-	0xb7, 0x61, 0x73, 0xcc, 0x84, 0xb2, 0xfc, 0x1c, 0x36, 0xdb, 0xe4, 0x9e, 0xb6, 0x3c, 0xe2, 0x87,
-	0x77, 0x6c, 0x9e, 0x69, 0x7d, 0x1f, 0xb6, 0xc6, 0x61, 0x73, 0xcb, 0xf2, 0x1a, 0x76, 0x95, 0x9d,
-	0xaa, 0xdd, 0x77, 0xc2, 0xd0, 0x61, 0xde, 0xbc, 0xfd, 0x7c, 0x03, 0x4b, 0x2e, 0x7d, 0xa0, 0xae,
-	0x2a, 0xcc, 0x6d, 0xe5, 0x78, 0x22, 0x57, 0x13, 0x4c, 0x1c, 0x61, 0xf4, 0x0a, 0x94, 0xa7, 0xf5,
-	0xaa, 0x4d, 0xfc, 0x3b, 0x0b, 0xeb, 0x13, 0xa9, 0x3b, 0x65, 0x2c, 0xdd, 0xef, 0x16, 0x3f, 0xbb,
-	0xdf, 0xed, 0x8f, 0x85, 0x76, 0xaa, 0x81, 0xa5, 0x5a, 0xdd, 0x37, 0xb0, 0xe4, 0xdf, 0x91, 0x90,
-	0xca, 0xd6, 0x38, 0xda, 0xcc, 0xa8, 0xc3, 0x08, 0x26, 0x8e, 0x30, 0xe8, 0x95, 0xb8, 0x8b, 0x3c,
-	0xdb, 0x11, 0x29, 0x11, 0x96, 0x97, 0x66, 0x17, 0xd5, 0x59, 0x82, 0xc0, 0x29, 0x34, 0x2a, 0xc3,
-	0x72, 0x3f, 0xaa, 0x35, 0xd9, 0x56, 0x8b, 0x38, 0x26, 0x45, 0x73, 0x0e, 0xa8, 0xcf, 0xca, 0xcb,
-	0xaa, 0x39, 0xab, 0xbb, 0x4d, 0xf5, 0xfd, 0xa3, 0x4b, 0x87, 0xab, 0xa6, 0x22, 0x61, 0xe8, 0x7b,
-	0x58, 0x0e, 0x06, 0x9e, 0xb8, 0xc9, 0xca, 0x05, 0x29, 0xf1, 0x74, 0xd2, 0x03, 0x1c, 0xb1, 0x4d,
-	0xef, 0x96, 0xe1, 0x18, 0x8b, 0x4e, 0x20, 0x47, 0x06, 0xfc, 0xae, 0x5c, 0x94, 0x32, 0x5f, 0x4d,
-	0xca, 0x54, 0x07, 0xfc, 0x8e, 0x7a, 0xdc, 0xe9, 0xca, 0x7c, 0xc7, 0x12, 0xab, 0xff, 0x2f, 0x03,
-	0x6b, 0x63, 0x41, 0x43, 0xbf, 0x87, 0xf5, 0x0f, 0xf1, 0x82, 0xe5, 0xf4, 0xc5, 0x6e, 0xa2, 0xb3,
-	0x2a, 0x25, 0xcb, 0xa6, 0x58, 0x45, 0x4f, 0xa1, 0xe8, 0xd8, 0x31, 0x44, 0x55, 0x93, 0x63, 0x2b,
-	0x66, 0x05, 0x0a, 0xa2, 0x63, 0xb8, 0x34, 0x0c, 0xe5, 0x11, 0x15, 0x70, 0x42, 0xc7, 0xa9, 0x99,
-	0x4b, 0x52, 0x13, 0xbd, 0x80, 0xb5, 0xa8, 0x62, 0x6c, 0xcb, 0x67, 0x01, 0x17, 0x81, 0xcf, 0xce,
-	0x2a, 0x98, 0x55, 0x85, 0x12, 0x0b, 0xe1, 0xe7, 0xdf, 0x61, 0xe2, 0x64, 0x78, 0x54, 0xd8, 0xf2,
-	0x08, 0x8a, 0x38, 0x26, 0xf5, 0xbf, 0x41, 0x21, 0xd6, 0x8e, 0x10, 0xe4, 0x84, 0x75, 0xb9, 0xdd,
-	0x35, 0x2c, 0xbf, 0x45, 0x65, 0x73, 0x12, 0xf4, 0x28, 0x97, 0x3b, 0x5c, 0xc3, 0x8a, 0x42, 0xdf,
-	0x03, 0x3c, 0x38, 0xa1, 0x73, 0xe3, 0xb8, 0xa2, 0xe7, 0x67, 0xc7, 0x32, 0x4b, 0x28, 0xbc, 0x4e,
-	0x98, 0x38, 0x05, 0x9c, 0xde, 0xba, 0xfe, 0xaf, 0x1c, 0x6c, 0xce, 0x48, 0x2c, 0x61, 0xf8, 0x96,
-	0x38, 0x2e, 0x8d, 0x2b, 0x45, 0x51, 0xe9, 0xad, 0x2c, 0x8e, 0x6d, 0x05, 0x9d, 0x43, 0xc9, 0x1f,
-	0xb8, 0xae, 0xe3, 0xf5, 0xa2, 0x33, 0x09, 0x95, 0x5b, 0x5f, 0xce, 0x4d, 0xdf, 0x53, 0xc6, 0x5c,
-	0xbc, 0xa6, 0x84, 0xe4, 0xb9, 0x85, 0x42, 0x4b, 0x3c, 0xa4, 0xd0, 0x8f, 0x4e, 0xc8, 0x43, 0x55,
-	0x36, 0x8f, 0x69, 0x51, 0x42, 0x86, 0x94, 0x11, 0xc7, 0x1f, 0xaa, 0x8e, 0x24, 0x8b, 0xa8, 0x88,
-	0x13, 0x1a, 0xfd, 0x09, 0xb6, 0x6f, 0x1d, 0x8f, 0xb8, 0xd6, 0x0d, 0xe9, 0xde, 0x0f, 0x7c, 0xab,
-	0xcb, 0xfa, 0xbe, 0x4b, 0x79, 0x7c, 0x8e, 0x8f, 0x18, 0xda, 0x94, 0xb2, 0xa7, 0x52, 0xf4, 0x4c,
-	0x49, 0xa2, 0x97, 0x50, 0xb0, 0xa9, 0xef, 0xb2, 0x21, 0xb5, 0xe5, 0x01, 0x3f, 0xaa, 0x25, 0x81,
-	0x23, 0x13, 0x36, 0x3c, 0xca, 0x45, 0x6a, 0x5b, 0x1e, 0xe3, 0x56, 0x40, 0x89, 0x3d, 0x94, 0x55,
-	0xf7, 0xa8, 0x8e, 0x75, 0x25, 0x57, 0x17, 0x5d, 0x97, 0xd8, 0x43, 0xf4, 0x0b, 0x6c, 0xde, 0x3a,
-	0x41, 0xc8, 0xad, 0x41, 0x48, 0x03, 0x8b, 0xc4, 0x03, 0x41, 0x51, 0x35, 0x91, 0x68, 0x52, 0x3d,
-	0x8a, 0x27, 0xd5, 0xa3, 0x76, 0x3c, 0xa9, 0xe2, 0x0d, 0x29, 0xd6, 0x09, 0x69, 0x90, 0x4c, 0x0c,
-	0x7f, 0x85, 0x8d, 0xa9, 0xee, 0x27, 0xee, 0x56, 0xf6, 0xc1, 0xa3, 0x81, 0x4a, 0x89, 0x88, 0x40,
-	0xbb, 0xa2, 0xed, 0x70, 0x62, 0x39, 0xb6, 0xca, 0x88, 0xbc, 0x20, 0x4d, 0x1b, 0xbd, 0x04, 0x08,
-	0xc5, 0xcc, 0x46, 0x6d, 0x8b, 0x70, 0xd5, 0x28, 0x3f, 0xe5, 0x46, 0x51, 0xa1, 0xab, 0x5c, 0xef,
-	0xc1, 0xd6, 0xac, 0x5e, 0x23, 0x6a, 0xde, 0x63, 0x36, 0xb5, 0x3c, 0xd2, 0x8f, 0xdb, 0x42, 0x41,
-	0x2c, 0xd4, 0x49, 0x9f, 0xa2, 0x27, 0x50, 0xf0, 0x99, 0x1d, 0xf1, 0x54, 0x6e, 0xfa, 0xcc, 0x96,
-	0xac, 0x5d, 0x58, 0x96, 0x72, 0x8e, 0x2f, 0xfd, 0x28, 0xe2, 0xbc, 0x20, 0x4d, 0x5f, 0x67, 0xb0,
-	0x3b, 0xa7, 0x41, 0xa1, 0xef, 0xa0, 0x48, 0xe2, 0x0b, 0x45, 0xda, 0x9a, 0x7b, 0x11, 0x8d, 0x70,
-	0xe8, 0x19, 0xac, 0xc8, 0xa8, 0x58, 0x9c, 0xdd, 0xd3, 0xf8, 0x92, 0x07, 0xb9, 0xd4, 0x16, 0x2b,
-	0xfa, 0x3f, 0x72, 0x80, 0xa6, 0xa7, 0xe2, 0xdf, 0xa8, 0xeb, 0xfd, 0x0c, 0x6b, 0xb7, 0x94, 0xf0,
-	0x41, 0x40, 0xad, 0x5b, 0x97, 0xf4, 0x42, 0x39, 0x62, 0x95, 0xa6, 0xdb, 0xf7, 0x45, 0x04, 0xba,
-	0x70, 0x49, 0x0f, 0xaf, 0xde, 0x8e, 0x88, 0x10, 0x5d, 0xc0, 0x4a, 0xea, 0x91, 0xa3, 0xa6, 0xf9,
-	0xaf, 0x27, 0x2f, 0x8c, 0x44, 0x91, 0x39, 0xc2, 0xe2, 0xb4, 0x20, 0x7a, 0x0e, 0x4b, 0x9f, 0xec,
-	0xa4, 0x11, 0x17, 0xbd, 0x80, 0x65, 0xea, 0x3d, 0x3c, 0x90, 0x20, 0x2c, 0xe7, 0x25, 0x30, 0xbe,
-	0xeb, 0x0c, 0xef, 0xc1, 0x09, 0x98, 0xd7, 0xa7, 0x1e, 0xbf, 0x26, 0x81, 0x43, 0x6e, 0x5c, 0x8a,
-	0x63, 0x28, 0xfa, 0x06, 0x36, 0xba, 0x77, 0xb4, 0x7b, 0xcf, 0x06, 0xdc, 0x72, 0x59, 0x74, 0x5c,
-	0xaa, 0xb1, 0x6a, 0x31, 0xa3, 0xa6, 0xd6, 0xd1, 0x21, 0xa0, 0x51, 0x64, 0x13, 0x74, 0x41, 0xa2,
-	0x37, 0x3e, 0x8c, 0xe6, 0x54, 0x05, 0xdf, 0x83, 0x6c, 0xcf, 0xe1, 0xaa, 0x68, 0x4a, 0xca, 0x1b,
-	0x71, 0x41, 0x0a, 0xaf, 0x05, 0x2b, 0xdd, 0x01, 0x61, 0xbc, 0x03, 0x8e, 0x65, 0xcc, 0xca, 0xe7,
-	0x65, 0x8c, 0xfe, 0x23, 0x2c, 0x2b, 0xf5, 0xa2, 0x6b, 0x89, 0xd2, 0x4d, 0x27, 0x77, 0x4c, 0x8b,
-	0xda, 0xa3, 0x7d, 0xe2, 0xb8, 0xf1, 0x5c, 0x2b, 0x09, 0xfd, 0x27, 0xd8, 0x9c, 0x11, 0x29, 0x71,
-	0x93, 0xa4, 0x94, 0xe4, 0x62, 0x05, 0xd3, 0x83, 0xb1, 0x3e, 0x48, 0x75, 0xff, 0xd1, 0xac, 0xfe,
-	0x1b, 0xcd, 0x48, 0xa9, 0x81, 0x24, 0x37, 0x36, 0x90, 0x1c, 0xbc, 0x80, 0xcd, 0x19, 0xaf, 0x2c,
-	0xb4, 0x0a, 0x85, 0x7a, 0x03, 0x5f, 0x55, 0x6b, 0xb5, 0x77, 0xda, 0x02, 0x5a, 0x87, 0x15, 0xf3,
-	0xea, 0xca, 0x38, 0x37, 0xab, 0x6d, 0xa3, 0xf6, 0x4e, 0xcb, 0x1c, 0xbc, 0x82, 0xd2, 0x78, 0x1c,
-	0xd1, 0x16, 0x68, 0xd5, 0xf3, 0x2b, 0xb3, 0x6d, 0x35, 0xde, 0xd6, 0x0d, 0x6c, 0x35, 0xea, 0x52,
-	0x10, 0x41, 0x29, 0x5a, 0x35, 0xae, 0x0d, 0xfc, 0xae, 0x51, 0x37, 0xb4, 0xcc, 0x81, 0x09, 0xa5,
-	0xf1, 0x7b, 0x11, 0x3d, 0x85, 0xdd, 0x66, 0x03, 0xb7, 0xad, 0x6b, 0xb3, 0x65, 0x9e, 0x9a, 0x35,
-	0xb3, 0xfd, 0xce, 0x6a, 0x62, 0xf3, 0xba, 0xda, 0x36, 0xb4, 0x05, 0x54, 0x81, 0x9d, 0x29, 0x66,
-	0xe7, 0xb4, 0x66, 0x9e, 0x69, 0x99, 0x83, 0x1f, 0x60, 0x67, 0x76, 0x4b, 0x46, 0x45, 0x58, 0xba,
-	0xa8, 0xd6, 0x5a, 0x42, 0x41, 0x01, 0x72, 0x6d, 0xdc, 0x31, 0xb4, 0x8c, 0x58, 0x34, 0xae, 0x9a,
-	0xed, 0x77, 0xda, 0xe2, 0xc1, 0xdf, 0x33, 0x50, 0x1a, 0x9f, 0xfb, 0xd0, 0x0a, 0x2c, 0x77, 0xea,
-	0x6f, 0xea, 0x8d, 0xb7, 0x75, 0x6d, 0x41, 0x10, 0x4d, 0xa3, 0x7e, 0x6e, 0xd6, 0x2f, 0xb5, 0x8c,
-	0x08, 0xc6, 0x19, 0x36, 0xaa, 0x6d, 0x41, 0x2d, 0x22, 0x0d, 0x56, 0xcd, 0xba, 0xd9, 0x36, 0xab,
-	0x35, 0xf3, 0xbd, 0x58, 0xc9, 0x0a, 0x30, 0xee, 0xd4, 0xeb, 0x82, 0xc8, 0xc9, 0x58, 0xd5, 0xdb,
-	0x06, 0xc6, 0x9d, 0x66, 0xdb, 0x38, 0xd7, 0x96, 0x85, 0x74, 0xab, 0xdd, 0x68, 0x36, 0x05, 0x7b,
-	0x49, 0x60, 0x25, 0x65, 0x9c, 0x6b, 0xf9, 0x83, 0x87, 0x54, 0x73, 0x4d, 0x75, 0x02, 0xe1, 0x72,
-	0xbd, 0xd1, 0x68, 0x6a, 0x0b, 0xe8, 0x09, 0x6c, 0x5f, 0x74, 0x6a, 0x35, 0xeb, 0x6d, 0x03, 0xbf,
-	0x69, 0x35, 0xab, 0x67, 0x86, 0x75, 0x5a, 0x3d, 0x7b, 0xd3, 0x69, 0x6a, 0x39, 0xb4, 0x09, 0xeb,
-	0x17, 0xe6, 0xaf, 0xc6, 0xb9, 0x85, 0x8d, 0x56, 0xa3, 0x83, 0xcf, 0x8c, 0x96, 0xb6, 0x24, 0x02,
-	0xde, 0x69, 0x19, 0xd8, 0xaa, 0x57, 0xaf, 0x0c, 0x89, 0xd7, 0xf2, 0x7a, 0xae, 0x90, 0xd1, 0x32,
-	0x7a, 0xae, 0xb0, 0xa8, 0x2d, 0xea, 0xb9, 0x42, 0x56, 0xcb, 0x1e, 0xfc, 0x9c, 0x1a, 0xf5, 0xc4,
-	0x70, 0x24, 0x77, 0x60, 0x5c, 0x76, 0x6a, 0x55, 0xac, 0x2d, 0x08, 0x87, 0x9b, 0xd8, 0x38, 0xed,
-	0x98, 0xb5, 0xf3, 0x28, 0x68, 0x4d, 0xdc, 0x38, 0x35, 0xb4, 0x45, 0xf1, 0x79, 0xf9, 0xba, 0xd1,
-	0x6a, 0x6b, 0xd9, 0x93, 0x7f, 0xe6, 0x41, 0x1b, 0x25, 0x1c, 0xf1, 0x48, 0x8f, 0x06, 0xa8, 0x06,
-	0x6b, 0x63, 0x7f, 0x52, 0x50, 0xdc, 0xee, 0x66, 0xfd, 0x77, 0xa9, 0x7c, 0x31, 0x9b, 0xa9, 0xde,
-	0x0b, 0x0b, 0xa8, 0x01, 0xa5, 0xf1, 0xf6, 0x8c, 0xbe, 0x98, 0xf9, 0x2f, 0x23, 0xd6, 0xf7, 0xe5,
-	0x1c, 0x6e, 0xa2, 0xb0, 0x06, 0x6b, 0x63, 0xa9, 0x9e, 0xb8, 0x37, 0xeb, 0x1f, 0x45, 0xe5, 0x8b,
-	0xd9, 0xcc, 0x44, 0xdb, 0xaf, 0xb0, 0x31, 0xf5, 0xeb, 0x00, 0x3d, 0x53, 0x42, 0xf3, 0x7e, 0x40,
-	0x54, 0xf6, 0xe6, 0x03, 0x12, 0xcd, 0xa7, 0x50, 0x4c, 0x9e, 0xe0, 0x68, 0x77, 0xfa, 0x51, 0x1e,
-	0x69, 0x2a, 0xcf, 0x7b, 0xad, 0xeb, 0x0b, 0xdf, 0x66, 0xd0, 0x19, 0xc0, 0xe8, 0x69, 0x8c, 0x62,
-	0xec, 0xd4, 0x53, 0xbb, 0xf2, 0x64, 0x06, 0x27, 0x71, 0xe4, 0x0c, 0x60, 0xf4, 0x10, 0x4e, 0x94,
-	0x4c, 0x3d, 0xae, 0x13, 0x25, 0x33, 0x5e, 0xcd, 0x0b, 0xe2, 0x1e, 0x4b, 0x3d, 0x6a, 0x51, 0x8c,
-	0x9d, 0x7e, 0x4b, 0x57, 0x2a, 0xb3, 0x58, 0x89, 0x1e, 0x13, 0x56, 0xd3, 0xcf, 0x5b, 0x14, 0xa3,
-	0x67, 0x3c, 0x8d, 0x2b, 0x4f, 0x67, 0xf2, 0x12, 0x55, 0x1d, 0xd0, 0x26, 0xdf, 0xa9, 0xe8, 0xab,
-	0x71, 0xe3, 0x93, 0x0f, 0xe3, 0xca, 0xb3, 0xb9, 0xfc, 0x58, 0xed, 0xe9, 0x1f, 0xde, 0x1f, 0xf4,
-	0x1c, 0x7e, 0x37, 0xb8, 0x39, 0xea, 0xb2, 0xfe, 0x71, 0xcf, 0xe1, 0x3e, 0xb3, 0x0f, 0x1d, 0xa6,
-	0xbe, 0x8e, 0x3f, 0x84, 0x87, 0xfd, 0xa8, 0x50, 0x8e, 0x89, 0xef, 0xdc, 0xe4, 0xe5, 0xdc, 0xf5,
-	0xdd, 0xff, 0x03, 0x00, 0x00, 0xff, 0xff, 0x8b, 0x81, 0x92, 0xa3, 0xf2, 0x14, 0x00, 0x00,
+// ScheduleStopResponse is the answer to a schedule stop request
+type ScheduleStopResponse struct {
+	// deadline is when the workspace stops. Not set if the request cancelled the scheduled stop.
+	Deadline             *timestamp.Timestamp `protobuf:"bytes,1,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+func (m *ScheduleStopResponse) Reset()         { *m = ScheduleStopResponse{} }
+func (m *ScheduleStopResponse) String() string { return proto.CompactTextString(m) }
+func (*ScheduleStopResponse) ProtoMessage()    {}
+func (*ScheduleStopResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{41}
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func (m *ScheduleStopResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleStopResponse.Unmarshal(m, b)
+}
+func (m *ScheduleStopResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleStopResponse.Marshal(b, m, deterministic)
+}
+func (m *ScheduleStopResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleStopResponse.Merge(m, src)
+}
+func (m *ScheduleStopResponse) XXX_Size() int {
+	return xxx_messageInfo_ScheduleStopResponse.Size(m)
+}
+func (m *ScheduleStopResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleStopResponse.DiscardUnknown(m)
+}
 
-// WorkspaceManagerClient is the client API for WorkspaceManager service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type WorkspaceManagerClient interface {
-	// getWorkspaces produces a list of running workspaces and their status
-	GetWorkspaces(ctx context.Context, in *GetWorkspacesRequest, opts ...grpc.CallOption) (*GetWorkspacesResponse, error)
-	// startWorkspace creates a new running workspace within the manager's cluster
-	StartWorkspace(ctx context.Context, in *StartWorkspaceRequest, opts ...grpc.CallOption) (*StartWorkspaceResponse, error)
-	// stopWorkspace stops a running workspace
-	StopWorkspace(ctx context.Context, in *StopWorkspaceRequest, opts ...grpc.CallOption) (*StopWorkspaceResponse, error)
-	// describeWorkspace investigates a workspace and returns its status, and configuration
-	DescribeWorkspace(ctx context.Context, in *DescribeWorkspaceRequest, opts ...grpc.CallOption) (*DescribeWorkspaceResponse, error)
-	// subscribe streams all status updates to a client
-	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeClient, error)
-	// markActive records a workspace as being active which prevents it from timing out
-	MarkActive(ctx context.Context, in *MarkActiveRequest, opts ...grpc.CallOption) (*MarkActiveResponse, error)
-	// setTimeout changes the default timeout for a running workspace
-	SetTimeout(ctx context.Context, in *SetTimeoutRequest, opts ...grpc.CallOption) (*SetTimeoutResponse, error)
-	// controlPort publicly exposes or un-exposes a network port for a workspace
-	ControlPort(ctx context.Context, in *ControlPortRequest, opts ...grpc.CallOption) (*ControlPortResponse, error)
-	// takeSnapshot creates a copy of the workspace content which can initialize a new workspace.
-	TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (*TakeSnapshotResponse, error)
-	// controlAdmission makes a workspace accessible for everyone or for the owner only
-	ControlAdmission(ctx context.Context, in *ControlAdmissionRequest, opts ...grpc.CallOption) (*ControlAdmissionResponse, error)
+var xxx_messageInfo_ScheduleStopResponse proto.InternalMessageInfo
+
+func (m *ScheduleStopResponse) GetDeadline() *timestamp.Timestamp {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
 }
 
-type workspaceManagerClient struct {
-	cc grpc.ClientConnInterface
+// GenerateWorkspaceIDRequest requests a new workspace ID
+type GenerateWorkspaceIDRequest struct {
+	// project is the project the workspace belongs to (e.g. gitpod-io/gitpod), which can have its own ID prefix. May be empty.
+	Project              string   `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewWorkspaceManagerClient(cc grpc.ClientConnInterface) WorkspaceManagerClient {
-	return &workspaceManagerClient{cc}
+func (m *GenerateWorkspaceIDRequest) Reset()         { *m = GenerateWorkspaceIDRequest{} }
+func (m *GenerateWorkspaceIDRequest) String() string { return proto.CompactTextString(m) }
+func (*GenerateWorkspaceIDRequest) ProtoMessage()    {}
+func (*GenerateWorkspaceIDRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{42}
 }
 
-func (c *workspaceManagerClient) GetWorkspaces(ctx context.Context, in *GetWorkspacesRequest, opts ...grpc.CallOption) (*GetWorkspacesResponse, error) {
-	out := new(GetWorkspacesResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/GetWorkspaces", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *GenerateWorkspaceIDRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GenerateWorkspaceIDRequest.Unmarshal(m, b)
+}
+func (m *GenerateWorkspaceIDRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GenerateWorkspaceIDRequest.Marshal(b, m, deterministic)
+}
+func (m *GenerateWorkspaceIDRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GenerateWorkspaceIDRequest.Merge(m, src)
+}
+func (m *GenerateWorkspaceIDRequest) XXX_Size() int {
+	return xxx_messageInfo_GenerateWorkspaceIDRequest.Size(m)
+}
+func (m *GenerateWorkspaceIDRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GenerateWorkspaceIDRequest.DiscardUnknown(m)
 }
 
-func (c *workspaceManagerClient) StartWorkspace(ctx context.Context, in *StartWorkspaceRequest, opts ...grpc.CallOption) (*StartWorkspaceResponse, error) {
-	out := new(StartWorkspaceResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/StartWorkspace", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_GenerateWorkspaceIDRequest proto.InternalMessageInfo
+
+func (m *GenerateWorkspaceIDRequest) GetProject() string {
+	if m != nil {
+		return m.Project
 	}
-	return out, nil
+	return ""
 }
 
-func (c *workspaceManagerClient) StopWorkspace(ctx context.Context, in *StopWorkspaceRequest, opts ...grpc.CallOption) (*StopWorkspaceResponse, error) {
-	out := new(StopWorkspaceResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/StopWorkspace", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+// GenerateWorkspaceIDResponse is the answer to a generate workspace ID request
+type GenerateWorkspaceIDResponse struct {
+	// id is the new workspace ID. No workspace with this ID exists in the cluster.
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *workspaceManagerClient) DescribeWorkspace(ctx context.Context, in *DescribeWorkspaceRequest, opts ...grpc.CallOption) (*DescribeWorkspaceResponse, error) {
-	out := new(DescribeWorkspaceResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DescribeWorkspace", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *GenerateWorkspaceIDResponse) Reset()         { *m = GenerateWorkspaceIDResponse{} }
+func (m *GenerateWorkspaceIDResponse) String() string { return proto.CompactTextString(m) }
+func (*GenerateWorkspaceIDResponse) ProtoMessage()    {}
+func (*GenerateWorkspaceIDResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{43}
 }
 
-func (c *workspaceManagerClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_WorkspaceManager_serviceDesc.Streams[0], "/wsman.WorkspaceManager/Subscribe", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &workspaceManagerSubscribeClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *GenerateWorkspaceIDResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GenerateWorkspaceIDResponse.Unmarshal(m, b)
+}
+func (m *GenerateWorkspaceIDResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GenerateWorkspaceIDResponse.Marshal(b, m, deterministic)
+}
+func (m *GenerateWorkspaceIDResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GenerateWorkspaceIDResponse.Merge(m, src)
+}
+func (m *GenerateWorkspaceIDResponse) XXX_Size() int {
+	return xxx_messageInfo_GenerateWorkspaceIDResponse.Size(m)
+}
+func (m *GenerateWorkspaceIDResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GenerateWorkspaceIDResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GenerateWorkspaceIDResponse proto.InternalMessageInfo
+
+func (m *GenerateWorkspaceIDResponse) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return x, nil
+	return ""
 }
 
-type WorkspaceManager_SubscribeClient interface {
-	Recv() (*SubscribeResponse, error)
-	grpc.ClientStream
+// ExportStateRequest requests a snapshot of ws-manager's in-memory state
+type ExportStateRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type workspaceManagerSubscribeClient struct {
-	grpc.ClientStream
+func (m *ExportStateRequest) Reset()         { *m = ExportStateRequest{} }
+func (m *ExportStateRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportStateRequest) ProtoMessage()    {}
+func (*ExportStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{44}
+}
+
+func (m *ExportStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportStateRequest.Unmarshal(m, b)
+}
+func (m *ExportStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportStateRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportStateRequest.Merge(m, src)
+}
+func (m *ExportStateRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportStateRequest.Size(m)
+}
+func (m *ExportStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportStateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportStateRequest proto.InternalMessageInfo
+
+// ExportStateResponse is the answer to an export state request
+type ExportStateResponse struct {
+	// snapshot is the opaque state snapshot which importState accepts
+	Snapshot []byte `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// version is the format version of the snapshot
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// created_at is when the snapshot was taken
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ExportStateResponse) Reset()         { *m = ExportStateResponse{} }
+func (m *ExportStateResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportStateResponse) ProtoMessage()    {}
+func (*ExportStateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{45}
+}
+
+func (m *ExportStateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportStateResponse.Unmarshal(m, b)
+}
+func (m *ExportStateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportStateResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportStateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportStateResponse.Merge(m, src)
+}
+func (m *ExportStateResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportStateResponse.Size(m)
+}
+func (m *ExportStateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportStateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportStateResponse proto.InternalMessageInfo
+
+func (m *ExportStateResponse) GetSnapshot() []byte {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+func (m *ExportStateResponse) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ExportStateResponse) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+// ImportStateRequest restores a state snapshot
+type ImportStateRequest struct {
+	// snapshot is a snapshot produced by exportState
+	Snapshot             []byte   `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportStateRequest) Reset()         { *m = ImportStateRequest{} }
+func (m *ImportStateRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportStateRequest) ProtoMessage()    {}
+func (*ImportStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{46}
+}
+
+func (m *ImportStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportStateRequest.Unmarshal(m, b)
+}
+func (m *ImportStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportStateRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportStateRequest.Merge(m, src)
+}
+func (m *ImportStateRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportStateRequest.Size(m)
+}
+func (m *ImportStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportStateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportStateRequest proto.InternalMessageInfo
+
+func (m *ImportStateRequest) GetSnapshot() []byte {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+// ImportStateResponse reports how a state snapshot was reconciled against the cluster
+type ImportStateResponse struct {
+	// restored_instances are the workspace instances whose state we restored
+	RestoredInstances []string `protobuf:"bytes,1,rep,name=restored_instances,json=restoredInstances,proto3" json:"restored_instances,omitempty"`
+	// dropped_instances are the workspace instances of the snapshot which no longer exist
+	DroppedInstances []string `protobuf:"bytes,2,rep,name=dropped_instances,json=droppedInstances,proto3" json:"dropped_instances,omitempty"`
+	// resumed_operations are the pending operations of the snapshot which ws-manager picks up again
+	ResumedOperations []*PendingOperation `protobuf:"bytes,3,rep,name=resumed_operations,json=resumedOperations,proto3" json:"resumed_operations,omitempty"`
+	// lost_operations are the pending operations of the snapshot whose workspace is gone. Their outcome is unknown,
+	// e.g. the final backup of a workspace might be missing.
+	LostOperations []*PendingOperation `protobuf:"bytes,4,rep,name=lost_operations,json=lostOperations,proto3" json:"lost_operations,omitempty"`
+	// maintenance_restored is true if we restored the maintenance status of the snapshot
+	MaintenanceRestored  bool     `protobuf:"varint,5,opt,name=maintenance_restored,json=maintenanceRestored,proto3" json:"maintenance_restored,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportStateResponse) Reset()         { *m = ImportStateResponse{} }
+func (m *ImportStateResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportStateResponse) ProtoMessage()    {}
+func (*ImportStateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{47}
+}
+
+func (m *ImportStateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportStateResponse.Unmarshal(m, b)
+}
+func (m *ImportStateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportStateResponse.Marshal(b, m, deterministic)
+}
+func (m *ImportStateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportStateResponse.Merge(m, src)
+}
+func (m *ImportStateResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportStateResponse.Size(m)
+}
+func (m *ImportStateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportStateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportStateResponse proto.InternalMessageInfo
+
+func (m *ImportStateResponse) GetRestoredInstances() []string {
+	if m != nil {
+		return m.RestoredInstances
+	}
+	return nil
+}
+
+func (m *ImportStateResponse) GetDroppedInstances() []string {
+	if m != nil {
+		return m.DroppedInstances
+	}
+	return nil
+}
+
+func (m *ImportStateResponse) GetResumedOperations() []*PendingOperation {
+	if m != nil {
+		return m.ResumedOperations
+	}
+	return nil
+}
+
+func (m *ImportStateResponse) GetLostOperations() []*PendingOperation {
+	if m != nil {
+		return m.LostOperations
+	}
+	return nil
+}
+
+func (m *ImportStateResponse) GetMaintenanceRestored() bool {
+	if m != nil {
+		return m.MaintenanceRestored
+	}
+	return false
+}
+
+// PendingOperation is an operation ws-manager performs on a workspace in the background
+type PendingOperation struct {
+	// instance_id is the ID of the workspace instance
+	InstanceId string `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// kind is what ws-manager does
+	Kind                 PendingOperationKind `protobuf:"varint,2,opt,name=kind,proto3,enum=wsman.PendingOperationKind" json:"kind,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *PendingOperation) Reset()         { *m = PendingOperation{} }
+func (m *PendingOperation) String() string { return proto.CompactTextString(m) }
+func (*PendingOperation) ProtoMessage()    {}
+func (*PendingOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{48}
+}
+
+func (m *PendingOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingOperation.Unmarshal(m, b)
+}
+func (m *PendingOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingOperation.Marshal(b, m, deterministic)
+}
+func (m *PendingOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingOperation.Merge(m, src)
+}
+func (m *PendingOperation) XXX_Size() int {
+	return xxx_messageInfo_PendingOperation.Size(m)
+}
+func (m *PendingOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingOperation proto.InternalMessageInfo
+
+func (m *PendingOperation) GetInstanceId() string {
+	if m != nil {
+		return m.InstanceId
+	}
+	return ""
+}
+
+func (m *PendingOperation) GetKind() PendingOperationKind {
+	if m != nil {
+		return m.Kind
+	}
+	return PendingOperationKind_CONTENT_INITIALIZATION
+}
+
+// DescribeArchivalRequest requests the archival status of stopped workspaces
+type DescribeArchivalRequest struct {
+	// workspace_id is the ID of the workspace (not of an instance). If empty, we describe all archived workspaces.
+	WorkspaceId          string   `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DescribeArchivalRequest) Reset()         { *m = DescribeArchivalRequest{} }
+func (m *DescribeArchivalRequest) String() string { return proto.CompactTextString(m) }
+func (*DescribeArchivalRequest) ProtoMessage()    {}
+func (*DescribeArchivalRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{49}
+}
+
+func (m *DescribeArchivalRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeArchivalRequest.Unmarshal(m, b)
+}
+func (m *DescribeArchivalRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeArchivalRequest.Marshal(b, m, deterministic)
+}
+func (m *DescribeArchivalRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeArchivalRequest.Merge(m, src)
+}
+func (m *DescribeArchivalRequest) XXX_Size() int {
+	return xxx_messageInfo_DescribeArchivalRequest.Size(m)
+}
+func (m *DescribeArchivalRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeArchivalRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeArchivalRequest proto.InternalMessageInfo
+
+func (m *DescribeArchivalRequest) GetWorkspaceId() string {
+	if m != nil {
+		return m.WorkspaceId
+	}
+	return ""
+}
+
+// DescribeArchivalResponse is the answer to a describe archival request
+type DescribeArchivalResponse struct {
+	Workspaces           []*ArchivalStatus `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DescribeArchivalResponse) Reset()         { *m = DescribeArchivalResponse{} }
+func (m *DescribeArchivalResponse) String() string { return proto.CompactTextString(m) }
+func (*DescribeArchivalResponse) ProtoMessage()    {}
+func (*DescribeArchivalResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{50}
+}
+
+func (m *DescribeArchivalResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeArchivalResponse.Unmarshal(m, b)
+}
+func (m *DescribeArchivalResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeArchivalResponse.Marshal(b, m, deterministic)
+}
+func (m *DescribeArchivalResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeArchivalResponse.Merge(m, src)
+}
+func (m *DescribeArchivalResponse) XXX_Size() int {
+	return xxx_messageInfo_DescribeArchivalResponse.Size(m)
+}
+func (m *DescribeArchivalResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeArchivalResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeArchivalResponse proto.InternalMessageInfo
+
+func (m *DescribeArchivalResponse) GetWorkspaces() []*ArchivalStatus {
+	if m != nil {
+		return m.Workspaces
+	}
+	return nil
+}
+
+// ArchivalStatus describes the backup of a stopped workspace
+type ArchivalStatus struct {
+	// workspace_id is the ID of the workspace
+	WorkspaceId string `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// owner is the user who owns the workspace and its backup
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// phase is STOPPED while the backup is in regular storage and ARCHIVED once it moved to cold storage
+	Phase WorkspacePhase `protobuf:"varint,3,opt,name=phase,proto3,enum=wsman.WorkspacePhase" json:"phase,omitempty"`
+	// stopped_at is when the last instance of the workspace stopped, or when the workspace was unarchived
+	StoppedAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=stopped_at,json=stoppedAt,proto3" json:"stopped_at,omitempty"`
+	// archive_at is when we archive the workspace unless it starts before. Unset if the workspace is archived.
+	ArchiveAt *timestamp.Timestamp `protobuf:"bytes,5,opt,name=archive_at,json=archiveAt,proto3" json:"archive_at,omitempty"`
+	// archived_at is when the backup moved to cold storage. Only set if the workspace is archived.
+	ArchivedAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	// backup_size is the size of the archived backup in bytes
+	BackupSize int64 `protobuf:"varint,7,opt,name=backup_size,json=backupSize,proto3" json:"backup_size,omitempty"`
+	// unarchiving is true while the backup moves out of cold storage
+	Unarchiving bool `protobuf:"varint,8,opt,name=unarchiving,proto3" json:"unarchiving,omitempty"`
+	// restore_latency is how long we expect unarchiving to take. It's a valid Go duration (see https://golang.org/pkg/time/#ParseDuration)
+	RestoreLatency       string   `protobuf:"bytes,9,opt,name=restore_latency,json=restoreLatency,proto3" json:"restore_latency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchivalStatus) Reset()         { *m = ArchivalStatus{} }
+func (m *ArchivalStatus) String() string { return proto.CompactTextString(m) }
+func (*ArchivalStatus) ProtoMessage()    {}
+func (*ArchivalStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{51}
+}
+
+func (m *ArchivalStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchivalStatus.Unmarshal(m, b)
+}
+func (m *ArchivalStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchivalStatus.Marshal(b, m, deterministic)
+}
+func (m *ArchivalStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchivalStatus.Merge(m, src)
+}
+func (m *ArchivalStatus) XXX_Size() int {
+	return xxx_messageInfo_ArchivalStatus.Size(m)
+}
+func (m *ArchivalStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchivalStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchivalStatus proto.InternalMessageInfo
+
+func (m *ArchivalStatus) GetWorkspaceId() string {
+	if m != nil {
+		return m.WorkspaceId
+	}
+	return ""
+}
+
+func (m *ArchivalStatus) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *ArchivalStatus) GetPhase() WorkspacePhase {
+	if m != nil {
+		return m.Phase
+	}
+	return WorkspacePhase_UNKNOWN
+}
+
+func (m *ArchivalStatus) GetStoppedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StoppedAt
+	}
+	return nil
+}
+
+func (m *ArchivalStatus) GetArchiveAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ArchiveAt
+	}
+	return nil
+}
+
+func (m *ArchivalStatus) GetArchivedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ArchivedAt
+	}
+	return nil
+}
+
+func (m *ArchivalStatus) GetBackupSize() int64 {
+	if m != nil {
+		return m.BackupSize
+	}
+	return 0
+}
+
+func (m *ArchivalStatus) GetUnarchiving() bool {
+	if m != nil {
+		return m.Unarchiving
+	}
+	return false
+}
+
+func (m *ArchivalStatus) GetRestoreLatency() string {
+	if m != nil {
+		return m.RestoreLatency
+	}
+	return ""
+}
+
+// UnarchiveWorkspaceRequest requests an archived workspace to be restored from cold storage
+type UnarchiveWorkspaceRequest struct {
+	// workspace_id is the ID of the workspace (not of an instance)
+	WorkspaceId          string   `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnarchiveWorkspaceRequest) Reset()         { *m = UnarchiveWorkspaceRequest{} }
+func (m *UnarchiveWorkspaceRequest) String() string { return proto.CompactTextString(m) }
+func (*UnarchiveWorkspaceRequest) ProtoMessage()    {}
+func (*UnarchiveWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{52}
+}
+
+func (m *UnarchiveWorkspaceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnarchiveWorkspaceRequest.Unmarshal(m, b)
+}
+func (m *UnarchiveWorkspaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnarchiveWorkspaceRequest.Marshal(b, m, deterministic)
+}
+func (m *UnarchiveWorkspaceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnarchiveWorkspaceRequest.Merge(m, src)
+}
+func (m *UnarchiveWorkspaceRequest) XXX_Size() int {
+	return xxx_messageInfo_UnarchiveWorkspaceRequest.Size(m)
+}
+func (m *UnarchiveWorkspaceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnarchiveWorkspaceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnarchiveWorkspaceRequest proto.InternalMessageInfo
+
+func (m *UnarchiveWorkspaceRequest) GetWorkspaceId() string {
+	if m != nil {
+		return m.WorkspaceId
+	}
+	return ""
+}
+
+// UnarchiveWorkspaceResponse is the answer to an unarchive workspace request
+type UnarchiveWorkspaceResponse struct {
+	// ready_at is when we expect the workspace to be able to start again
+	ReadyAt *timestamp.Timestamp `protobuf:"bytes,1,opt,name=ready_at,json=readyAt,proto3" json:"ready_at,omitempty"`
+	// restore_latency is how long we expect unarchiving to take. It's a valid Go duration (see https://golang.org/pkg/time/#ParseDuration)
+	RestoreLatency       string   `protobuf:"bytes,2,opt,name=restore_latency,json=restoreLatency,proto3" json:"restore_latency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnarchiveWorkspaceResponse) Reset()         { *m = UnarchiveWorkspaceResponse{} }
+func (m *UnarchiveWorkspaceResponse) String() string { return proto.CompactTextString(m) }
+func (*UnarchiveWorkspaceResponse) ProtoMessage()    {}
+func (*UnarchiveWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{53}
+}
+
+func (m *UnarchiveWorkspaceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnarchiveWorkspaceResponse.Unmarshal(m, b)
+}
+func (m *UnarchiveWorkspaceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnarchiveWorkspaceResponse.Marshal(b, m, deterministic)
+}
+func (m *UnarchiveWorkspaceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnarchiveWorkspaceResponse.Merge(m, src)
+}
+func (m *UnarchiveWorkspaceResponse) XXX_Size() int {
+	return xxx_messageInfo_UnarchiveWorkspaceResponse.Size(m)
+}
+func (m *UnarchiveWorkspaceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnarchiveWorkspaceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnarchiveWorkspaceResponse proto.InternalMessageInfo
+
+func (m *UnarchiveWorkspaceResponse) GetReadyAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReadyAt
+	}
+	return nil
+}
+
+func (m *UnarchiveWorkspaceResponse) GetRestoreLatency() string {
+	if m != nil {
+		return m.RestoreLatency
+	}
+	return ""
+}
+
+// DeleteWorkspaceRequest requests a stopped workspace to be soft-deleted
+type DeleteWorkspaceRequest struct {
+	// workspace_id is the ID of the workspace (not of an instance)
+	WorkspaceId string `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// owner is the user who owns the workspace and its backup
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// metadata is kept with the deleted workspace and handed back when it is restored, e.g. its description or context URL
+	Metadata             map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DeleteWorkspaceRequest) Reset()         { *m = DeleteWorkspaceRequest{} }
+func (m *DeleteWorkspaceRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWorkspaceRequest) ProtoMessage()    {}
+func (*DeleteWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{54}
+}
+
+func (m *DeleteWorkspaceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWorkspaceRequest.Unmarshal(m, b)
+}
+func (m *DeleteWorkspaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWorkspaceRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteWorkspaceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWorkspaceRequest.Merge(m, src)
+}
+func (m *DeleteWorkspaceRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWorkspaceRequest.Size(m)
+}
+func (m *DeleteWorkspaceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWorkspaceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWorkspaceRequest proto.InternalMessageInfo
+
+func (m *DeleteWorkspaceRequest) GetWorkspaceId() string {
+	if m != nil {
+		return m.WorkspaceId
+	}
+	return ""
+}
+
+func (m *DeleteWorkspaceRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *DeleteWorkspaceRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// DeleteWorkspaceResponse is the answer to a delete workspace request
+type DeleteWorkspaceResponse struct {
+	// purge_at is when the grace period ends and we delete the backup for good
+	PurgeAt              *timestamp.Timestamp `protobuf:"bytes,1,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DeleteWorkspaceResponse) Reset()         { *m = DeleteWorkspaceResponse{} }
+func (m *DeleteWorkspaceResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteWorkspaceResponse) ProtoMessage()    {}
+func (*DeleteWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{55}
+}
+
+func (m *DeleteWorkspaceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWorkspaceResponse.Unmarshal(m, b)
+}
+func (m *DeleteWorkspaceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWorkspaceResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteWorkspaceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWorkspaceResponse.Merge(m, src)
+}
+func (m *DeleteWorkspaceResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteWorkspaceResponse.Size(m)
+}
+func (m *DeleteWorkspaceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWorkspaceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWorkspaceResponse proto.InternalMessageInfo
+
+func (m *DeleteWorkspaceResponse) GetPurgeAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.PurgeAt
+	}
+	return nil
+}
+
+// RestoreDeletedWorkspaceRequest requests a soft-deleted workspace to be restored
+type RestoreDeletedWorkspaceRequest struct {
+	// workspace_id is the ID of the workspace (not of an instance)
+	WorkspaceId          string   `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreDeletedWorkspaceRequest) Reset()         { *m = RestoreDeletedWorkspaceRequest{} }
+func (m *RestoreDeletedWorkspaceRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreDeletedWorkspaceRequest) ProtoMessage()    {}
+func (*RestoreDeletedWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{56}
+}
+
+func (m *RestoreDeletedWorkspaceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreDeletedWorkspaceRequest.Unmarshal(m, b)
+}
+func (m *RestoreDeletedWorkspaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreDeletedWorkspaceRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreDeletedWorkspaceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreDeletedWorkspaceRequest.Merge(m, src)
+}
+func (m *RestoreDeletedWorkspaceRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreDeletedWorkspaceRequest.Size(m)
+}
+func (m *RestoreDeletedWorkspaceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreDeletedWorkspaceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreDeletedWorkspaceRequest proto.InternalMessageInfo
+
+func (m *RestoreDeletedWorkspaceRequest) GetWorkspaceId() string {
+	if m != nil {
+		return m.WorkspaceId
+	}
+	return ""
+}
+
+// RestoreDeletedWorkspaceResponse is the answer to a restore deleted workspace request
+type RestoreDeletedWorkspaceResponse struct {
+	// workspace is the workspace as it was deleted
+	Workspace            *DeletedWorkspace `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RestoreDeletedWorkspaceResponse) Reset()         { *m = RestoreDeletedWorkspaceResponse{} }
+func (m *RestoreDeletedWorkspaceResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreDeletedWorkspaceResponse) ProtoMessage()    {}
+func (*RestoreDeletedWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{57}
+}
+
+func (m *RestoreDeletedWorkspaceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreDeletedWorkspaceResponse.Unmarshal(m, b)
+}
+func (m *RestoreDeletedWorkspaceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreDeletedWorkspaceResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreDeletedWorkspaceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreDeletedWorkspaceResponse.Merge(m, src)
+}
+func (m *RestoreDeletedWorkspaceResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreDeletedWorkspaceResponse.Size(m)
+}
+func (m *RestoreDeletedWorkspaceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreDeletedWorkspaceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreDeletedWorkspaceResponse proto.InternalMessageInfo
+
+func (m *RestoreDeletedWorkspaceResponse) GetWorkspace() *DeletedWorkspace {
+	if m != nil {
+		return m.Workspace
+	}
+	return nil
+}
+
+// DescribeDeletedWorkspacesRequest requests the soft-deleted workspaces
+type DescribeDeletedWorkspacesRequest struct {
+	// owner restricts the list to the workspaces of a user. If empty, we describe all soft-deleted workspaces.
+	Owner                string   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DescribeDeletedWorkspacesRequest) Reset()         { *m = DescribeDeletedWorkspacesRequest{} }
+func (m *DescribeDeletedWorkspacesRequest) String() string { return proto.CompactTextString(m) }
+func (*DescribeDeletedWorkspacesRequest) ProtoMessage()    {}
+func (*DescribeDeletedWorkspacesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{58}
+}
+
+func (m *DescribeDeletedWorkspacesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeDeletedWorkspacesRequest.Unmarshal(m, b)
+}
+func (m *DescribeDeletedWorkspacesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeDeletedWorkspacesRequest.Marshal(b, m, deterministic)
+}
+func (m *DescribeDeletedWorkspacesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeDeletedWorkspacesRequest.Merge(m, src)
+}
+func (m *DescribeDeletedWorkspacesRequest) XXX_Size() int {
+	return xxx_messageInfo_DescribeDeletedWorkspacesRequest.Size(m)
+}
+func (m *DescribeDeletedWorkspacesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeDeletedWorkspacesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeDeletedWorkspacesRequest proto.InternalMessageInfo
+
+func (m *DescribeDeletedWorkspacesRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+// DescribeDeletedWorkspacesResponse is the answer to a describe deleted workspaces request
+type DescribeDeletedWorkspacesResponse struct {
+	Workspaces           []*DeletedWorkspace `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *DescribeDeletedWorkspacesResponse) Reset()         { *m = DescribeDeletedWorkspacesResponse{} }
+func (m *DescribeDeletedWorkspacesResponse) String() string { return proto.CompactTextString(m) }
+func (*DescribeDeletedWorkspacesResponse) ProtoMessage()    {}
+func (*DescribeDeletedWorkspacesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{59}
+}
+
+func (m *DescribeDeletedWorkspacesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeDeletedWorkspacesResponse.Unmarshal(m, b)
+}
+func (m *DescribeDeletedWorkspacesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeDeletedWorkspacesResponse.Marshal(b, m, deterministic)
+}
+func (m *DescribeDeletedWorkspacesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeDeletedWorkspacesResponse.Merge(m, src)
+}
+func (m *DescribeDeletedWorkspacesResponse) XXX_Size() int {
+	return xxx_messageInfo_DescribeDeletedWorkspacesResponse.Size(m)
+}
+func (m *DescribeDeletedWorkspacesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeDeletedWorkspacesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeDeletedWorkspacesResponse proto.InternalMessageInfo
+
+func (m *DescribeDeletedWorkspacesResponse) GetWorkspaces() []*DeletedWorkspace {
+	if m != nil {
+		return m.Workspaces
+	}
+	return nil
+}
+
+// DeletedWorkspace describes a soft-deleted workspace
+type DeletedWorkspace struct {
+	// workspace_id is the ID of the workspace
+	WorkspaceId string `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// owner is the user who owns the workspace and its backup
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// deleted_at is when the workspace was soft-deleted
+	DeletedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// purge_at is when the grace period ends and we delete the backup for good
+	PurgeAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"`
+	// metadata is what was passed along when the workspace was deleted
+	Metadata             map[string]string `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DeletedWorkspace) Reset()         { *m = DeletedWorkspace{} }
+func (m *DeletedWorkspace) String() string { return proto.CompactTextString(m) }
+func (*DeletedWorkspace) ProtoMessage()    {}
+func (*DeletedWorkspace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{60}
+}
+
+func (m *DeletedWorkspace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeletedWorkspace.Unmarshal(m, b)
+}
+func (m *DeletedWorkspace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeletedWorkspace.Marshal(b, m, deterministic)
+}
+func (m *DeletedWorkspace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeletedWorkspace.Merge(m, src)
+}
+func (m *DeletedWorkspace) XXX_Size() int {
+	return xxx_messageInfo_DeletedWorkspace.Size(m)
+}
+func (m *DeletedWorkspace) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeletedWorkspace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeletedWorkspace proto.InternalMessageInfo
+
+func (m *DeletedWorkspace) GetWorkspaceId() string {
+	if m != nil {
+		return m.WorkspaceId
+	}
+	return ""
+}
+
+func (m *DeletedWorkspace) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *DeletedWorkspace) GetDeletedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeletedAt
+	}
+	return nil
+}
+
+func (m *DeletedWorkspace) GetPurgeAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.PurgeAt
+	}
+	return nil
+}
+
+func (m *DeletedWorkspace) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// WorkspaceTemplateSpec are the settings a workspace template provides to the workspaces started from it
+type WorkspaceTemplateSpec struct {
+	// workspace_image is used if the start request has no workspace image
+	WorkspaceImage string `protobuf:"bytes,1,opt,name=workspace_image,json=workspaceImage,proto3" json:"workspace_image,omitempty"`
+	// ide_image is used if the start request has no IDE image
+	IdeImage string `protobuf:"bytes,2,opt,name=ide_image,json=ideImage,proto3" json:"ide_image,omitempty"`
+	// type is used if the start request asks for a regular workspace
+	Type WorkspaceType `protobuf:"varint,3,opt,name=type,proto3,enum=wsman.WorkspaceType" json:"type,omitempty"`
+	// envvars are added to the environment of the workspace. Variables of the start request with the same name take precedence.
+	Envvars []*EnvironmentVariable `protobuf:"bytes,4,rep,name=envvars,proto3" json:"envvars,omitempty"`
+	// tasks are the tasks (in the format of GITPOD_TASKS) the workspace runs unless the start request has tasks of its own
+	Tasks string `protobuf:"bytes,5,opt,name=tasks,proto3" json:"tasks,omitempty"`
+	// timeout is used if the start request has no timeout
+	Timeout              string   `protobuf:"bytes,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceTemplateSpec) Reset()         { *m = WorkspaceTemplateSpec{} }
+func (m *WorkspaceTemplateSpec) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceTemplateSpec) ProtoMessage()    {}
+func (*WorkspaceTemplateSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{61}
+}
+
+func (m *WorkspaceTemplateSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceTemplateSpec.Unmarshal(m, b)
+}
+func (m *WorkspaceTemplateSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceTemplateSpec.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceTemplateSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceTemplateSpec.Merge(m, src)
+}
+func (m *WorkspaceTemplateSpec) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceTemplateSpec.Size(m)
+}
+func (m *WorkspaceTemplateSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceTemplateSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceTemplateSpec proto.InternalMessageInfo
+
+func (m *WorkspaceTemplateSpec) GetWorkspaceImage() string {
+	if m != nil {
+		return m.WorkspaceImage
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplateSpec) GetIdeImage() string {
+	if m != nil {
+		return m.IdeImage
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplateSpec) GetType() WorkspaceType {
+	if m != nil {
+		return m.Type
+	}
+	return WorkspaceType_REGULAR
+}
+
+func (m *WorkspaceTemplateSpec) GetEnvvars() []*EnvironmentVariable {
+	if m != nil {
+		return m.Envvars
+	}
+	return nil
+}
+
+func (m *WorkspaceTemplateSpec) GetTasks() string {
+	if m != nil {
+		return m.Tasks
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplateSpec) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+// WorkspaceTemplate is a version of a named workspace template
+type WorkspaceTemplate struct {
+	// id identifies the template across all its versions
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name is unique among the templates of an owner
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// owner is the user who created the template and who alone can change it
+	Owner string `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	// team_id is the team the template is shared with. If empty, only its owner can use the template.
+	TeamId string `protobuf:"bytes,4,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	// version counts the updates of the template, starting at 1
+	Version int64 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	// latest_version is the version startWorkspace uses unless it asks for a specific one
+	LatestVersion int64 `protobuf:"varint,6,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	// spec are the settings of this version
+	Spec *WorkspaceTemplateSpec `protobuf:"bytes,7,opt,name=spec,proto3" json:"spec,omitempty"`
+	// created is when this version was created
+	Created              *timestamp.Timestamp `protobuf:"bytes,8,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *WorkspaceTemplate) Reset()         { *m = WorkspaceTemplate{} }
+func (m *WorkspaceTemplate) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceTemplate) ProtoMessage()    {}
+func (*WorkspaceTemplate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{62}
+}
+
+func (m *WorkspaceTemplate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceTemplate.Unmarshal(m, b)
+}
+func (m *WorkspaceTemplate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceTemplate.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceTemplate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceTemplate.Merge(m, src)
+}
+func (m *WorkspaceTemplate) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceTemplate.Size(m)
+}
+func (m *WorkspaceTemplate) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceTemplate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceTemplate proto.InternalMessageInfo
+
+func (m *WorkspaceTemplate) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplate) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplate) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplate) GetTeamId() string {
+	if m != nil {
+		return m.TeamId
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplate) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *WorkspaceTemplate) GetLatestVersion() int64 {
+	if m != nil {
+		return m.LatestVersion
+	}
+	return 0
+}
+
+func (m *WorkspaceTemplate) GetSpec() *WorkspaceTemplateSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (m *WorkspaceTemplate) GetCreated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+// WorkspaceTemplateRef references a workspace template in a start request
+type WorkspaceTemplateRef struct {
+	// id is the ID of the template
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// version is the version of the template to use. Zero uses the latest version.
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// team_id is the team of the user starting the workspace, which grants access to the templates shared with it
+	TeamId               string   `protobuf:"bytes,3,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceTemplateRef) Reset()         { *m = WorkspaceTemplateRef{} }
+func (m *WorkspaceTemplateRef) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceTemplateRef) ProtoMessage()    {}
+func (*WorkspaceTemplateRef) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{63}
+}
+
+func (m *WorkspaceTemplateRef) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceTemplateRef.Unmarshal(m, b)
+}
+func (m *WorkspaceTemplateRef) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceTemplateRef.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceTemplateRef) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceTemplateRef.Merge(m, src)
+}
+func (m *WorkspaceTemplateRef) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceTemplateRef.Size(m)
+}
+func (m *WorkspaceTemplateRef) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceTemplateRef.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceTemplateRef proto.InternalMessageInfo
+
+func (m *WorkspaceTemplateRef) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WorkspaceTemplateRef) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *WorkspaceTemplateRef) GetTeamId() string {
+	if m != nil {
+		return m.TeamId
+	}
+	return ""
+}
+
+// CreateWorkspaceTemplateRequest requests a new workspace template
+type CreateWorkspaceTemplateRequest struct {
+	// name is unique among the templates of an owner
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// owner is the user who creates the template
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// team_id shares the template with a team
+	TeamId string `protobuf:"bytes,3,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	// spec are the settings of the first version
+	Spec                 *WorkspaceTemplateSpec `protobuf:"bytes,4,opt,name=spec,proto3" json:"spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *CreateWorkspaceTemplateRequest) Reset()         { *m = CreateWorkspaceTemplateRequest{} }
+func (m *CreateWorkspaceTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateWorkspaceTemplateRequest) ProtoMessage()    {}
+func (*CreateWorkspaceTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{64}
+}
+
+func (m *CreateWorkspaceTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWorkspaceTemplateRequest.Unmarshal(m, b)
+}
+func (m *CreateWorkspaceTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWorkspaceTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateWorkspaceTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWorkspaceTemplateRequest.Merge(m, src)
+}
+func (m *CreateWorkspaceTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateWorkspaceTemplateRequest.Size(m)
+}
+func (m *CreateWorkspaceTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWorkspaceTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateWorkspaceTemplateRequest proto.InternalMessageInfo
+
+func (m *CreateWorkspaceTemplateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateWorkspaceTemplateRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *CreateWorkspaceTemplateRequest) GetTeamId() string {
+	if m != nil {
+		return m.TeamId
+	}
+	return ""
+}
+
+func (m *CreateWorkspaceTemplateRequest) GetSpec() *WorkspaceTemplateSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+// CreateWorkspaceTemplateResponse is the answer to a create workspace template request
+type CreateWorkspaceTemplateResponse struct {
+	Template             *WorkspaceTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *CreateWorkspaceTemplateResponse) Reset()         { *m = CreateWorkspaceTemplateResponse{} }
+func (m *CreateWorkspaceTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateWorkspaceTemplateResponse) ProtoMessage()    {}
+func (*CreateWorkspaceTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{65}
+}
+
+func (m *CreateWorkspaceTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWorkspaceTemplateResponse.Unmarshal(m, b)
+}
+func (m *CreateWorkspaceTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWorkspaceTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateWorkspaceTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWorkspaceTemplateResponse.Merge(m, src)
+}
+func (m *CreateWorkspaceTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateWorkspaceTemplateResponse.Size(m)
+}
+func (m *CreateWorkspaceTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWorkspaceTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateWorkspaceTemplateResponse proto.InternalMessageInfo
+
+func (m *CreateWorkspaceTemplateResponse) GetTemplate() *WorkspaceTemplate {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// UpdateWorkspaceTemplateRequest requests a new version of a workspace template
+type UpdateWorkspaceTemplateRequest struct {
+	// id is the ID of the template
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// owner must be the owner of the template
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// spec are the settings of the new version
+	Spec *WorkspaceTemplateSpec `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
+	// latest_version, if not zero, fails the update unless it is still the latest version of the template
+	LatestVersion        int64    `protobuf:"varint,4,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateWorkspaceTemplateRequest) Reset()         { *m = UpdateWorkspaceTemplateRequest{} }
+func (m *UpdateWorkspaceTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateWorkspaceTemplateRequest) ProtoMessage()    {}
+func (*UpdateWorkspaceTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{66}
+}
+
+func (m *UpdateWorkspaceTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateWorkspaceTemplateRequest.Unmarshal(m, b)
+}
+func (m *UpdateWorkspaceTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateWorkspaceTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateWorkspaceTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateWorkspaceTemplateRequest.Merge(m, src)
+}
+func (m *UpdateWorkspaceTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateWorkspaceTemplateRequest.Size(m)
+}
+func (m *UpdateWorkspaceTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateWorkspaceTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateWorkspaceTemplateRequest proto.InternalMessageInfo
+
+func (m *UpdateWorkspaceTemplateRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *UpdateWorkspaceTemplateRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *UpdateWorkspaceTemplateRequest) GetSpec() *WorkspaceTemplateSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (m *UpdateWorkspaceTemplateRequest) GetLatestVersion() int64 {
+	if m != nil {
+		return m.LatestVersion
+	}
+	return 0
+}
+
+// UpdateWorkspaceTemplateResponse is the answer to an update workspace template request
+type UpdateWorkspaceTemplateResponse struct {
+	Template             *WorkspaceTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *UpdateWorkspaceTemplateResponse) Reset()         { *m = UpdateWorkspaceTemplateResponse{} }
+func (m *UpdateWorkspaceTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateWorkspaceTemplateResponse) ProtoMessage()    {}
+func (*UpdateWorkspaceTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{67}
+}
+
+func (m *UpdateWorkspaceTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateWorkspaceTemplateResponse.Unmarshal(m, b)
+}
+func (m *UpdateWorkspaceTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateWorkspaceTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateWorkspaceTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateWorkspaceTemplateResponse.Merge(m, src)
+}
+func (m *UpdateWorkspaceTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateWorkspaceTemplateResponse.Size(m)
+}
+func (m *UpdateWorkspaceTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateWorkspaceTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateWorkspaceTemplateResponse proto.InternalMessageInfo
+
+func (m *UpdateWorkspaceTemplateResponse) GetTemplate() *WorkspaceTemplate {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// GetWorkspaceTemplateRequest requests a version of a workspace template
+type GetWorkspaceTemplateRequest struct {
+	// id is the ID of the template
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// version is the version to return. Zero returns the latest version.
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// user must be the owner of the template or a member of the team it is shared with
+	User string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	// team_id is the team of the user
+	TeamId               string   `protobuf:"bytes,4,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWorkspaceTemplateRequest) Reset()         { *m = GetWorkspaceTemplateRequest{} }
+func (m *GetWorkspaceTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWorkspaceTemplateRequest) ProtoMessage()    {}
+func (*GetWorkspaceTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{68}
+}
+
+func (m *GetWorkspaceTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWorkspaceTemplateRequest.Unmarshal(m, b)
+}
+func (m *GetWorkspaceTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWorkspaceTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWorkspaceTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWorkspaceTemplateRequest.Merge(m, src)
+}
+func (m *GetWorkspaceTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWorkspaceTemplateRequest.Size(m)
+}
+func (m *GetWorkspaceTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWorkspaceTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWorkspaceTemplateRequest proto.InternalMessageInfo
+
+func (m *GetWorkspaceTemplateRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GetWorkspaceTemplateRequest) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetWorkspaceTemplateRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *GetWorkspaceTemplateRequest) GetTeamId() string {
+	if m != nil {
+		return m.TeamId
+	}
+	return ""
+}
+
+// GetWorkspaceTemplateResponse is the answer to a get workspace template request
+type GetWorkspaceTemplateResponse struct {
+	Template             *WorkspaceTemplate `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *GetWorkspaceTemplateResponse) Reset()         { *m = GetWorkspaceTemplateResponse{} }
+func (m *GetWorkspaceTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWorkspaceTemplateResponse) ProtoMessage()    {}
+func (*GetWorkspaceTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{69}
+}
+
+func (m *GetWorkspaceTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWorkspaceTemplateResponse.Unmarshal(m, b)
+}
+func (m *GetWorkspaceTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWorkspaceTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *GetWorkspaceTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWorkspaceTemplateResponse.Merge(m, src)
+}
+func (m *GetWorkspaceTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_GetWorkspaceTemplateResponse.Size(m)
+}
+func (m *GetWorkspaceTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWorkspaceTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWorkspaceTemplateResponse proto.InternalMessageInfo
+
+func (m *GetWorkspaceTemplateResponse) GetTemplate() *WorkspaceTemplate {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// ListWorkspaceTemplatesRequest requests the workspace templates a user can use
+type ListWorkspaceTemplatesRequest struct {
+	// user lists the templates the user owns
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// team_id lists the templates shared with the team of the user
+	TeamId               string   `protobuf:"bytes,2,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWorkspaceTemplatesRequest) Reset()         { *m = ListWorkspaceTemplatesRequest{} }
+func (m *ListWorkspaceTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWorkspaceTemplatesRequest) ProtoMessage()    {}
+func (*ListWorkspaceTemplatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{70}
+}
+
+func (m *ListWorkspaceTemplatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWorkspaceTemplatesRequest.Unmarshal(m, b)
+}
+func (m *ListWorkspaceTemplatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWorkspaceTemplatesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWorkspaceTemplatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWorkspaceTemplatesRequest.Merge(m, src)
+}
+func (m *ListWorkspaceTemplatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWorkspaceTemplatesRequest.Size(m)
+}
+func (m *ListWorkspaceTemplatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWorkspaceTemplatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWorkspaceTemplatesRequest proto.InternalMessageInfo
+
+func (m *ListWorkspaceTemplatesRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ListWorkspaceTemplatesRequest) GetTeamId() string {
+	if m != nil {
+		return m.TeamId
+	}
+	return ""
+}
+
+// ListWorkspaceTemplatesResponse is the answer to a list workspace templates request
+type ListWorkspaceTemplatesResponse struct {
+	// templates are the latest versions of the templates, sorted by name
+	Templates            []*WorkspaceTemplate `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ListWorkspaceTemplatesResponse) Reset()         { *m = ListWorkspaceTemplatesResponse{} }
+func (m *ListWorkspaceTemplatesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWorkspaceTemplatesResponse) ProtoMessage()    {}
+func (*ListWorkspaceTemplatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{71}
+}
+
+func (m *ListWorkspaceTemplatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWorkspaceTemplatesResponse.Unmarshal(m, b)
+}
+func (m *ListWorkspaceTemplatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWorkspaceTemplatesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWorkspaceTemplatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWorkspaceTemplatesResponse.Merge(m, src)
+}
+func (m *ListWorkspaceTemplatesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWorkspaceTemplatesResponse.Size(m)
+}
+func (m *ListWorkspaceTemplatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWorkspaceTemplatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWorkspaceTemplatesResponse proto.InternalMessageInfo
+
+func (m *ListWorkspaceTemplatesResponse) GetTemplates() []*WorkspaceTemplate {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+// DeleteWorkspaceTemplateRequest requests a workspace template to be deleted
+type DeleteWorkspaceTemplateRequest struct {
+	// id is the ID of the template
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// owner must be the owner of the template
+	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWorkspaceTemplateRequest) Reset()         { *m = DeleteWorkspaceTemplateRequest{} }
+func (m *DeleteWorkspaceTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWorkspaceTemplateRequest) ProtoMessage()    {}
+func (*DeleteWorkspaceTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{72}
+}
+
+func (m *DeleteWorkspaceTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWorkspaceTemplateRequest.Unmarshal(m, b)
+}
+func (m *DeleteWorkspaceTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWorkspaceTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteWorkspaceTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWorkspaceTemplateRequest.Merge(m, src)
+}
+func (m *DeleteWorkspaceTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWorkspaceTemplateRequest.Size(m)
+}
+func (m *DeleteWorkspaceTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWorkspaceTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWorkspaceTemplateRequest proto.InternalMessageInfo
+
+func (m *DeleteWorkspaceTemplateRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeleteWorkspaceTemplateRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+// DeleteWorkspaceTemplateResponse is the answer to a delete workspace template request
+type DeleteWorkspaceTemplateResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWorkspaceTemplateResponse) Reset()         { *m = DeleteWorkspaceTemplateResponse{} }
+func (m *DeleteWorkspaceTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteWorkspaceTemplateResponse) ProtoMessage()    {}
+func (*DeleteWorkspaceTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{73}
+}
+
+func (m *DeleteWorkspaceTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWorkspaceTemplateResponse.Unmarshal(m, b)
+}
+func (m *DeleteWorkspaceTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWorkspaceTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteWorkspaceTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWorkspaceTemplateResponse.Merge(m, src)
+}
+func (m *DeleteWorkspaceTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteWorkspaceTemplateResponse.Size(m)
+}
+func (m *DeleteWorkspaceTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWorkspaceTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWorkspaceTemplateResponse proto.InternalMessageInfo
+
+// MaintenanceStatus describes a (scheduled) cluster maintenance
+type MaintenanceStatus struct {
+	// enabled is true if a maintenance is scheduled or under way
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// message explains the maintenance to users
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// starts_at is when the maintenance begins and workspaces will be stopped
+	StartsAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	// ends_at is when the maintenance is expected to be over
+	EndsAt               *timestamp.Timestamp `protobuf:"bytes,4,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *MaintenanceStatus) Reset()         { *m = MaintenanceStatus{} }
+func (m *MaintenanceStatus) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceStatus) ProtoMessage()    {}
+func (*MaintenanceStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{74}
+}
+
+func (m *MaintenanceStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceStatus.Unmarshal(m, b)
+}
+func (m *MaintenanceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceStatus.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceStatus.Merge(m, src)
+}
+func (m *MaintenanceStatus) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceStatus.Size(m)
+}
+func (m *MaintenanceStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceStatus proto.InternalMessageInfo
+
+func (m *MaintenanceStatus) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *MaintenanceStatus) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *MaintenanceStatus) GetStartsAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartsAt
+	}
+	return nil
+}
+
+func (m *MaintenanceStatus) GetEndsAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndsAt
+	}
+	return nil
+}
+
+// WorkspaceStatus describes a workspace status
+type WorkspaceStatus struct {
+	// ID is the unique identifier of the workspace
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Metadata is data associated with this workspace that's required for other parts of Gitpod to function
+	Metadata *WorkspaceMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Spec is the workspace spec during runtime
+	Spec *WorkspaceSpec `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
+	// the phase of a workspace is a simple, high-level summary of where the workspace is in its lifecycle
+	Phase WorkspacePhase `protobuf:"varint,4,opt,name=phase,proto3,enum=wsman.WorkspacePhase" json:"phase,omitempty"`
+	// conditions detail the current state of the workspace
+	Conditions *WorkspaceConditions `protobuf:"bytes,5,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	// message is an optional human-readable message detailing the current phase
+	Message string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	// repo details the Git working copy status of the workspace.
+	// Note: this is a best-effort field and more often than not will not be present. Its absence does not
+	// indicate the absence of a working copy.
+	Repo *api.GitStatus `protobuf:"bytes,7,opt,name=repo,proto3" json:"repo,omitempty"`
+	// runtime contains information about the workspace's runtime environment
+	Runtime *WorkspaceRuntimeInfo `protobuf:"bytes,8,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	// auth provides authentication information about the workspace. This info is primarily used by ws-proxy.
+	Auth *WorkspaceAuthentication `protobuf:"bytes,9,opt,name=auth,proto3" json:"auth,omitempty"`
+	// generation increases monotonically with every status update of a workspace, also across ws-manager restarts.
+	// Consumers can use it to discard status updates which arrive out of order, e.g. after re-connecting.
+	Generation uint64 `protobuf:"varint,10,opt,name=generation,proto3" json:"generation,omitempty"`
+	// init_containers reports the state of the init containers the workspace was started with
+	InitContainers       []*InitContainerStatus `protobuf:"bytes,11,rep,name=init_containers,json=initContainers,proto3" json:"init_containers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *WorkspaceStatus) Reset()         { *m = WorkspaceStatus{} }
+func (m *WorkspaceStatus) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceStatus) ProtoMessage()    {}
+func (*WorkspaceStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{75}
+}
+
+func (m *WorkspaceStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceStatus.Unmarshal(m, b)
+}
+func (m *WorkspaceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceStatus.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceStatus.Merge(m, src)
+}
+func (m *WorkspaceStatus) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceStatus.Size(m)
+}
+func (m *WorkspaceStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceStatus proto.InternalMessageInfo
+
+func (m *WorkspaceStatus) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WorkspaceStatus) GetMetadata() *WorkspaceMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *WorkspaceStatus) GetSpec() *WorkspaceSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (m *WorkspaceStatus) GetPhase() WorkspacePhase {
+	if m != nil {
+		return m.Phase
+	}
+	return WorkspacePhase_UNKNOWN
+}
+
+func (m *WorkspaceStatus) GetConditions() *WorkspaceConditions {
+	if m != nil {
+		return m.Conditions
+	}
+	return nil
+}
+
+func (m *WorkspaceStatus) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *WorkspaceStatus) GetRepo() *api.GitStatus {
+	if m != nil {
+		return m.Repo
+	}
+	return nil
+}
+
+func (m *WorkspaceStatus) GetRuntime() *WorkspaceRuntimeInfo {
+	if m != nil {
+		return m.Runtime
+	}
+	return nil
+}
+
+func (m *WorkspaceStatus) GetAuth() *WorkspaceAuthentication {
+	if m != nil {
+		return m.Auth
+	}
+	return nil
+}
+
+func (m *WorkspaceStatus) GetGeneration() uint64 {
+	if m != nil {
+		return m.Generation
+	}
+	return 0
+}
+
+func (m *WorkspaceStatus) GetInitContainers() []*InitContainerStatus {
+	if m != nil {
+		return m.InitContainers
+	}
+	return nil
+}
+
+// InitContainerStatus is the state of an init container which runs before the workspace starts
+type InitContainerStatus struct {
+	// name is the name of the init container in ws-manager's allowlist
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// state is where the init container is in its lifecycle
+	State InitContainerState `protobuf:"varint,2,opt,name=state,proto3,enum=wsman.InitContainerState" json:"state,omitempty"`
+	// exit_code is the exit code of the init container once it has terminated
+	ExitCode int32 `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	// message explains why an init container failed, if it did
+	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InitContainerStatus) Reset()         { *m = InitContainerStatus{} }
+func (m *InitContainerStatus) String() string { return proto.CompactTextString(m) }
+func (*InitContainerStatus) ProtoMessage()    {}
+func (*InitContainerStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{76}
+}
+
+func (m *InitContainerStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InitContainerStatus.Unmarshal(m, b)
+}
+func (m *InitContainerStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InitContainerStatus.Marshal(b, m, deterministic)
+}
+func (m *InitContainerStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InitContainerStatus.Merge(m, src)
+}
+func (m *InitContainerStatus) XXX_Size() int {
+	return xxx_messageInfo_InitContainerStatus.Size(m)
+}
+func (m *InitContainerStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_InitContainerStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InitContainerStatus proto.InternalMessageInfo
+
+func (m *InitContainerStatus) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InitContainerStatus) GetState() InitContainerState {
+	if m != nil {
+		return m.State
+	}
+	return InitContainerState_INIT_CONTAINER_WAITING
+}
+
+func (m *InitContainerStatus) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+func (m *InitContainerStatus) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// WorkspaceSpec is the specification of a workspace at runtime
+type WorkspaceSpec struct {
+	// workspace_image is the name of the Docker image this workspace runs
+	WorkspaceImage string `protobuf:"bytes,1,opt,name=workspace_image,json=workspaceImage,proto3" json:"workspace_image,omitempty"`
+	// ide_image is the name of the Docker image used as IDE
+	IdeImage string `protobuf:"bytes,2,opt,name=ide_image,json=ideImage,proto3" json:"ide_image,omitempty"`
+	// headless marks this workspace a headless one - headless workspaces are not intended for users but for automation
+	Headless bool `protobuf:"varint,3,opt,name=headless,proto3" json:"headless,omitempty"`
+	// URL is the external URL of the workspace
+	Url string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	// exposed_ports lists all ports which this workspace has exposed to the outside world
+	ExposedPorts []*PortSpec `protobuf:"bytes,5,rep,name=exposed_ports,json=exposedPorts,proto3" json:"exposed_ports,omitempty"`
+	// workspace type denotes what kind of workspace this is, e.g. if it's user-facing, prebuilding content or probing the service
+	Type WorkspaceType `protobuf:"varint,6,opt,name=type,proto3,enum=wsman.WorkspaceType" json:"type,omitempty"`
+	// The intervals in which a heartbeat must be received for the workspace not to time out
+	Timeout string `protobuf:"bytes,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// experiments maps experiment names to the variant this workspace is assigned to
+	Experiments map[string]string `protobuf:"bytes,8,rep,name=experiments,proto3" json:"experiments,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// scheduled_stop is when the workspace stops regardless of its activity, if a stop was scheduled using ScheduleStop
+	ScheduledStop *timestamp.Timestamp `protobuf:"bytes,9,opt,name=scheduled_stop,json=scheduledStop,proto3" json:"scheduled_stop,omitempty"`
+	// ide_pin is set if the workspace was started with a pinned IDE image
+	IdePin               *IDEPin  `protobuf:"bytes,10,opt,name=ide_pin,json=idePin,proto3" json:"ide_pin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceSpec) Reset()         { *m = WorkspaceSpec{} }
+func (m *WorkspaceSpec) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceSpec) ProtoMessage()    {}
+func (*WorkspaceSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{77}
+}
+
+func (m *WorkspaceSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceSpec.Unmarshal(m, b)
+}
+func (m *WorkspaceSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceSpec.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceSpec.Merge(m, src)
+}
+func (m *WorkspaceSpec) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceSpec.Size(m)
+}
+func (m *WorkspaceSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceSpec proto.InternalMessageInfo
+
+func (m *WorkspaceSpec) GetWorkspaceImage() string {
+	if m != nil {
+		return m.WorkspaceImage
+	}
+	return ""
+}
+
+func (m *WorkspaceSpec) GetIdeImage() string {
+	if m != nil {
+		return m.IdeImage
+	}
+	return ""
+}
+
+func (m *WorkspaceSpec) GetHeadless() bool {
+	if m != nil {
+		return m.Headless
+	}
+	return false
+}
+
+func (m *WorkspaceSpec) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *WorkspaceSpec) GetExposedPorts() []*PortSpec {
+	if m != nil {
+		return m.ExposedPorts
+	}
+	return nil
+}
+
+func (m *WorkspaceSpec) GetType() WorkspaceType {
+	if m != nil {
+		return m.Type
+	}
+	return WorkspaceType_REGULAR
+}
+
+func (m *WorkspaceSpec) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+func (m *WorkspaceSpec) GetExperiments() map[string]string {
+	if m != nil {
+		return m.Experiments
+	}
+	return nil
+}
+
+func (m *WorkspaceSpec) GetScheduledStop() *timestamp.Timestamp {
+	if m != nil {
+		return m.ScheduledStop
+	}
+	return nil
+}
+
+func (m *WorkspaceSpec) GetIdePin() *IDEPin {
+	if m != nil {
+		return m.IdePin
+	}
+	return nil
+}
+
+// IDEPin describes the IDE image a workspace was pinned to
+type IDEPin struct {
+	// pinned_image is the IDE image the workspace was pinned to. It's the ide_image of the workspace spec.
+	PinnedImage string `protobuf:"bytes,1,opt,name=pinned_image,json=pinnedImage,proto3" json:"pinned_image,omitempty"`
+	// default_image is the IDE image the workspace would have used without the pin
+	DefaultImage string `protobuf:"bytes,2,opt,name=default_image,json=defaultImage,proto3" json:"default_image,omitempty"`
+	// supervisor_version is the supervisor version the pinned image was checked against
+	SupervisorVersion    string   `protobuf:"bytes,3,opt,name=supervisor_version,json=supervisorVersion,proto3" json:"supervisor_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IDEPin) Reset()         { *m = IDEPin{} }
+func (m *IDEPin) String() string { return proto.CompactTextString(m) }
+func (*IDEPin) ProtoMessage()    {}
+func (*IDEPin) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{78}
+}
+
+func (m *IDEPin) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IDEPin.Unmarshal(m, b)
+}
+func (m *IDEPin) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IDEPin.Marshal(b, m, deterministic)
+}
+func (m *IDEPin) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IDEPin.Merge(m, src)
+}
+func (m *IDEPin) XXX_Size() int {
+	return xxx_messageInfo_IDEPin.Size(m)
+}
+func (m *IDEPin) XXX_DiscardUnknown() {
+	xxx_messageInfo_IDEPin.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IDEPin proto.InternalMessageInfo
+
+func (m *IDEPin) GetPinnedImage() string {
+	if m != nil {
+		return m.PinnedImage
+	}
+	return ""
+}
+
+func (m *IDEPin) GetDefaultImage() string {
+	if m != nil {
+		return m.DefaultImage
+	}
+	return ""
+}
+
+func (m *IDEPin) GetSupervisorVersion() string {
+	if m != nil {
+		return m.SupervisorVersion
+	}
+	return ""
+}
+
+// ResetIDEPinRequest removes the IDE pin of a workspace
+type ResetIDEPinRequest struct {
+	// id is the ID of the workspace
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetIDEPinRequest) Reset()         { *m = ResetIDEPinRequest{} }
+func (m *ResetIDEPinRequest) String() string { return proto.CompactTextString(m) }
+func (*ResetIDEPinRequest) ProtoMessage()    {}
+func (*ResetIDEPinRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{79}
+}
+
+func (m *ResetIDEPinRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetIDEPinRequest.Unmarshal(m, b)
+}
+func (m *ResetIDEPinRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetIDEPinRequest.Marshal(b, m, deterministic)
+}
+func (m *ResetIDEPinRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetIDEPinRequest.Merge(m, src)
+}
+func (m *ResetIDEPinRequest) XXX_Size() int {
+	return xxx_messageInfo_ResetIDEPinRequest.Size(m)
+}
+func (m *ResetIDEPinRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetIDEPinRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetIDEPinRequest proto.InternalMessageInfo
+
+func (m *ResetIDEPinRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// ResetIDEPinResponse is the answer to a ResetIDEPinRequest
+type ResetIDEPinResponse struct {
+	// ide_image is the IDE image the workspace uses from now on. The IDE which runs already keeps running until
+	// the workspace container is restarted.
+	IdeImage             string   `protobuf:"bytes,1,opt,name=ide_image,json=ideImage,proto3" json:"ide_image,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetIDEPinResponse) Reset()         { *m = ResetIDEPinResponse{} }
+func (m *ResetIDEPinResponse) String() string { return proto.CompactTextString(m) }
+func (*ResetIDEPinResponse) ProtoMessage()    {}
+func (*ResetIDEPinResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{80}
+}
+
+func (m *ResetIDEPinResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetIDEPinResponse.Unmarshal(m, b)
+}
+func (m *ResetIDEPinResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetIDEPinResponse.Marshal(b, m, deterministic)
+}
+func (m *ResetIDEPinResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetIDEPinResponse.Merge(m, src)
+}
+func (m *ResetIDEPinResponse) XXX_Size() int {
+	return xxx_messageInfo_ResetIDEPinResponse.Size(m)
+}
+func (m *ResetIDEPinResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetIDEPinResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetIDEPinResponse proto.InternalMessageInfo
+
+func (m *ResetIDEPinResponse) GetIdeImage() string {
+	if m != nil {
+		return m.IdeImage
+	}
+	return ""
+}
+
+// BulkOperationFilter selects the workspaces a bulk operation applies to. A workspace must match all criteria which are set.
+type BulkOperationFilter struct {
+	// owners matches the workspaces of any of these users
+	Owners []string `protobuf:"bytes,1,rep,name=owners,proto3" json:"owners,omitempty"`
+	// projects matches the workspaces of any of these projects (see WorkspaceMetadata.project)
+	Projects []string `protobuf:"bytes,2,rep,name=projects,proto3" json:"projects,omitempty"`
+	// types matches the workspaces of any of these types
+	Types []WorkspaceType `protobuf:"varint,3,rep,packed,name=types,proto3,enum=wsman.WorkspaceType" json:"types,omitempty"`
+	// started_before matches the workspaces which were started before this time
+	StartedBefore        *timestamp.Timestamp `protobuf:"bytes,4,opt,name=started_before,json=startedBefore,proto3" json:"started_before,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BulkOperationFilter) Reset()         { *m = BulkOperationFilter{} }
+func (m *BulkOperationFilter) String() string { return proto.CompactTextString(m) }
+func (*BulkOperationFilter) ProtoMessage()    {}
+func (*BulkOperationFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{81}
+}
+
+func (m *BulkOperationFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkOperationFilter.Unmarshal(m, b)
+}
+func (m *BulkOperationFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkOperationFilter.Marshal(b, m, deterministic)
+}
+func (m *BulkOperationFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkOperationFilter.Merge(m, src)
+}
+func (m *BulkOperationFilter) XXX_Size() int {
+	return xxx_messageInfo_BulkOperationFilter.Size(m)
+}
+func (m *BulkOperationFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkOperationFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkOperationFilter proto.InternalMessageInfo
+
+func (m *BulkOperationFilter) GetOwners() []string {
+	if m != nil {
+		return m.Owners
+	}
+	return nil
+}
+
+func (m *BulkOperationFilter) GetProjects() []string {
+	if m != nil {
+		return m.Projects
+	}
+	return nil
+}
+
+func (m *BulkOperationFilter) GetTypes() []WorkspaceType {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+func (m *BulkOperationFilter) GetStartedBefore() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartedBefore
+	}
+	return nil
+}
+
+// StartBulkOperationRequest applies an operation to all running workspaces which match a filter
+type StartBulkOperationRequest struct {
+	// filter selects the workspaces. The operation applies to the workspaces which match when the request arrives.
+	Filter *BulkOperationFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// operation is what we do to every workspace
+	//
+	// Types that are valid to be assigned to Operation:
+	//	*StartBulkOperationRequest_Stop
+	//	*StartBulkOperationRequest_SetTimeout
+	//	*StartBulkOperationRequest_Migrate
+	Operation isStartBulkOperationRequest_Operation `protobuf_oneof:"operation"`
+	// concurrency limits the workspaces the operation works on at the same time. Defaults to 10, at most 50.
+	Concurrency uint32 `protobuf:"varint,5,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
+	// dry_run only lists the workspaces the operation would apply to
+	DryRun               bool     `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartBulkOperationRequest) Reset()         { *m = StartBulkOperationRequest{} }
+func (m *StartBulkOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*StartBulkOperationRequest) ProtoMessage()    {}
+func (*StartBulkOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{82}
+}
+
+func (m *StartBulkOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartBulkOperationRequest.Unmarshal(m, b)
+}
+func (m *StartBulkOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartBulkOperationRequest.Marshal(b, m, deterministic)
+}
+func (m *StartBulkOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartBulkOperationRequest.Merge(m, src)
+}
+func (m *StartBulkOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_StartBulkOperationRequest.Size(m)
+}
+func (m *StartBulkOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartBulkOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartBulkOperationRequest proto.InternalMessageInfo
+
+func (m *StartBulkOperationRequest) GetFilter() *BulkOperationFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+type isStartBulkOperationRequest_Operation interface {
+	isStartBulkOperationRequest_Operation()
+}
+
+type StartBulkOperationRequest_Stop struct {
+	Stop *BulkStopOperation `protobuf:"bytes,2,opt,name=stop,proto3,oneof"`
+}
+
+type StartBulkOperationRequest_SetTimeout struct {
+	SetTimeout *BulkSetTimeoutOperation `protobuf:"bytes,3,opt,name=set_timeout,json=setTimeout,proto3,oneof"`
+}
+
+type StartBulkOperationRequest_Migrate struct {
+	Migrate *BulkMigrateOperation `protobuf:"bytes,4,opt,name=migrate,proto3,oneof"`
+}
+
+func (*StartBulkOperationRequest_Stop) isStartBulkOperationRequest_Operation() {}
+
+func (*StartBulkOperationRequest_SetTimeout) isStartBulkOperationRequest_Operation() {}
+
+func (*StartBulkOperationRequest_Migrate) isStartBulkOperationRequest_Operation() {}
+
+func (m *StartBulkOperationRequest) GetOperation() isStartBulkOperationRequest_Operation {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *StartBulkOperationRequest) GetStop() *BulkStopOperation {
+	if x, ok := m.GetOperation().(*StartBulkOperationRequest_Stop); ok {
+		return x.Stop
+	}
+	return nil
+}
+
+func (m *StartBulkOperationRequest) GetSetTimeout() *BulkSetTimeoutOperation {
+	if x, ok := m.GetOperation().(*StartBulkOperationRequest_SetTimeout); ok {
+		return x.SetTimeout
+	}
+	return nil
+}
+
+func (m *StartBulkOperationRequest) GetMigrate() *BulkMigrateOperation {
+	if x, ok := m.GetOperation().(*StartBulkOperationRequest_Migrate); ok {
+		return x.Migrate
+	}
+	return nil
+}
+
+func (m *StartBulkOperationRequest) GetConcurrency() uint32 {
+	if m != nil {
+		return m.Concurrency
+	}
+	return 0
+}
+
+func (m *StartBulkOperationRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*StartBulkOperationRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*StartBulkOperationRequest_Stop)(nil),
+		(*StartBulkOperationRequest_SetTimeout)(nil),
+		(*StartBulkOperationRequest_Migrate)(nil),
+	}
+}
+
+// BulkStopOperation stops workspaces
+type BulkStopOperation struct {
+	Policy               StopWorkspacePolicy `protobuf:"varint,1,opt,name=policy,proto3,enum=wsman.StopWorkspacePolicy" json:"policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *BulkStopOperation) Reset()         { *m = BulkStopOperation{} }
+func (m *BulkStopOperation) String() string { return proto.CompactTextString(m) }
+func (*BulkStopOperation) ProtoMessage()    {}
+func (*BulkStopOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{83}
+}
+
+func (m *BulkStopOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkStopOperation.Unmarshal(m, b)
+}
+func (m *BulkStopOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkStopOperation.Marshal(b, m, deterministic)
+}
+func (m *BulkStopOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkStopOperation.Merge(m, src)
+}
+func (m *BulkStopOperation) XXX_Size() int {
+	return xxx_messageInfo_BulkStopOperation.Size(m)
+}
+func (m *BulkStopOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkStopOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkStopOperation proto.InternalMessageInfo
+
+func (m *BulkStopOperation) GetPolicy() StopWorkspacePolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return StopWorkspacePolicy_NORMALLY
+}
+
+// BulkSetTimeoutOperation changes the timeout of workspaces, like setTimeout
+type BulkSetTimeoutOperation struct {
+	// duration is the new timeout duration. Must be a valid Go duration (see https://golang.org/pkg/time/#ParseDuration)
+	Duration             string   `protobuf:"bytes,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BulkSetTimeoutOperation) Reset()         { *m = BulkSetTimeoutOperation{} }
+func (m *BulkSetTimeoutOperation) String() string { return proto.CompactTextString(m) }
+func (*BulkSetTimeoutOperation) ProtoMessage()    {}
+func (*BulkSetTimeoutOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{84}
+}
+
+func (m *BulkSetTimeoutOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkSetTimeoutOperation.Unmarshal(m, b)
+}
+func (m *BulkSetTimeoutOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkSetTimeoutOperation.Marshal(b, m, deterministic)
+}
+func (m *BulkSetTimeoutOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkSetTimeoutOperation.Merge(m, src)
+}
+func (m *BulkSetTimeoutOperation) XXX_Size() int {
+	return xxx_messageInfo_BulkSetTimeoutOperation.Size(m)
+}
+func (m *BulkSetTimeoutOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkSetTimeoutOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkSetTimeoutOperation proto.InternalMessageInfo
+
+func (m *BulkSetTimeoutOperation) GetDuration() string {
+	if m != nil {
+		return m.Duration
+	}
+	return ""
+}
+
+// BulkMigrateOperation moves workspaces to another cluster. We stop them with a final backup and mark them with
+// the target cluster (see WorkspaceConditions.migrate_to), so that their next instance starts there.
+type BulkMigrateOperation struct {
+	TargetCluster        string   `protobuf:"bytes,1,opt,name=target_cluster,json=targetCluster,proto3" json:"target_cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BulkMigrateOperation) Reset()         { *m = BulkMigrateOperation{} }
+func (m *BulkMigrateOperation) String() string { return proto.CompactTextString(m) }
+func (*BulkMigrateOperation) ProtoMessage()    {}
+func (*BulkMigrateOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{85}
+}
+
+func (m *BulkMigrateOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkMigrateOperation.Unmarshal(m, b)
+}
+func (m *BulkMigrateOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkMigrateOperation.Marshal(b, m, deterministic)
+}
+func (m *BulkMigrateOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkMigrateOperation.Merge(m, src)
+}
+func (m *BulkMigrateOperation) XXX_Size() int {
+	return xxx_messageInfo_BulkMigrateOperation.Size(m)
+}
+func (m *BulkMigrateOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkMigrateOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkMigrateOperation proto.InternalMessageInfo
+
+func (m *BulkMigrateOperation) GetTargetCluster() string {
+	if m != nil {
+		return m.TargetCluster
+	}
+	return ""
+}
+
+type StartBulkOperationResponse struct {
+	// id identifies the bulk operation. Empty for dry runs.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// workspace_ids are the IDs of the workspaces the operation applies to
+	WorkspaceIds         []string `protobuf:"bytes,2,rep,name=workspace_ids,json=workspaceIds,proto3" json:"workspace_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartBulkOperationResponse) Reset()         { *m = StartBulkOperationResponse{} }
+func (m *StartBulkOperationResponse) String() string { return proto.CompactTextString(m) }
+func (*StartBulkOperationResponse) ProtoMessage()    {}
+func (*StartBulkOperationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{86}
+}
+
+func (m *StartBulkOperationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartBulkOperationResponse.Unmarshal(m, b)
+}
+func (m *StartBulkOperationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartBulkOperationResponse.Marshal(b, m, deterministic)
+}
+func (m *StartBulkOperationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartBulkOperationResponse.Merge(m, src)
+}
+func (m *StartBulkOperationResponse) XXX_Size() int {
+	return xxx_messageInfo_StartBulkOperationResponse.Size(m)
+}
+func (m *StartBulkOperationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartBulkOperationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartBulkOperationResponse proto.InternalMessageInfo
+
+func (m *StartBulkOperationResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *StartBulkOperationResponse) GetWorkspaceIds() []string {
+	if m != nil {
+		return m.WorkspaceIds
+	}
+	return nil
+}
+
+// BulkOperationStatus is the progress of a bulk operation
+type BulkOperationStatus struct {
+	Id    string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State BulkOperationState `protobuf:"varint,2,opt,name=state,proto3,enum=wsman.BulkOperationState" json:"state,omitempty"`
+	// total is the number of workspaces the operation applies to
+	Total uint32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	// succeeded is the number of workspaces the operation worked on successfully
+	Succeeded uint32 `protobuf:"varint,4,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	// failed is the number of workspaces the operation failed on, or which it did not reach because it was cancelled
+	Failed    uint32               `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// completed_at is the time the operation completed or was cancelled. Empty while the operation is running.
+	CompletedAt          *timestamp.Timestamp `protobuf:"bytes,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BulkOperationStatus) Reset()         { *m = BulkOperationStatus{} }
+func (m *BulkOperationStatus) String() string { return proto.CompactTextString(m) }
+func (*BulkOperationStatus) ProtoMessage()    {}
+func (*BulkOperationStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{87}
+}
+
+func (m *BulkOperationStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkOperationStatus.Unmarshal(m, b)
+}
+func (m *BulkOperationStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkOperationStatus.Marshal(b, m, deterministic)
+}
+func (m *BulkOperationStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkOperationStatus.Merge(m, src)
+}
+func (m *BulkOperationStatus) XXX_Size() int {
+	return xxx_messageInfo_BulkOperationStatus.Size(m)
+}
+func (m *BulkOperationStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkOperationStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkOperationStatus proto.InternalMessageInfo
+
+func (m *BulkOperationStatus) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *BulkOperationStatus) GetState() BulkOperationState {
+	if m != nil {
+		return m.State
+	}
+	return BulkOperationState_BULK_OPERATION_RUNNING
+}
+
+func (m *BulkOperationStatus) GetTotal() uint32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *BulkOperationStatus) GetSucceeded() uint32 {
+	if m != nil {
+		return m.Succeeded
+	}
+	return 0
+}
+
+func (m *BulkOperationStatus) GetFailed() uint32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *BulkOperationStatus) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *BulkOperationStatus) GetCompletedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CompletedAt
+	}
+	return nil
+}
+
+// BulkOperationResult is the outcome of a bulk operation for a single workspace
+type BulkOperationResult struct {
+	// id is the ID of the workspace
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// metadata is the metadata of the workspace
+	Metadata *WorkspaceMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// error is empty if the operation succeeded
+	Error                string               `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	CompletedAt          *timestamp.Timestamp `protobuf:"bytes,4,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BulkOperationResult) Reset()         { *m = BulkOperationResult{} }
+func (m *BulkOperationResult) String() string { return proto.CompactTextString(m) }
+func (*BulkOperationResult) ProtoMessage()    {}
+func (*BulkOperationResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{88}
+}
+
+func (m *BulkOperationResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkOperationResult.Unmarshal(m, b)
+}
+func (m *BulkOperationResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkOperationResult.Marshal(b, m, deterministic)
+}
+func (m *BulkOperationResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkOperationResult.Merge(m, src)
+}
+func (m *BulkOperationResult) XXX_Size() int {
+	return xxx_messageInfo_BulkOperationResult.Size(m)
+}
+func (m *BulkOperationResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkOperationResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkOperationResult proto.InternalMessageInfo
+
+func (m *BulkOperationResult) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *BulkOperationResult) GetMetadata() *WorkspaceMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *BulkOperationResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *BulkOperationResult) GetCompletedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CompletedAt
+	}
+	return nil
+}
+
+type DescribeBulkOperationRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DescribeBulkOperationRequest) Reset()         { *m = DescribeBulkOperationRequest{} }
+func (m *DescribeBulkOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*DescribeBulkOperationRequest) ProtoMessage()    {}
+func (*DescribeBulkOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{89}
+}
+
+func (m *DescribeBulkOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeBulkOperationRequest.Unmarshal(m, b)
+}
+func (m *DescribeBulkOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeBulkOperationRequest.Marshal(b, m, deterministic)
+}
+func (m *DescribeBulkOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeBulkOperationRequest.Merge(m, src)
+}
+func (m *DescribeBulkOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_DescribeBulkOperationRequest.Size(m)
+}
+func (m *DescribeBulkOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeBulkOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeBulkOperationRequest proto.InternalMessageInfo
+
+func (m *DescribeBulkOperationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type DescribeBulkOperationResponse struct {
+	Status               *BulkOperationStatus   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Results              []*BulkOperationResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *DescribeBulkOperationResponse) Reset()         { *m = DescribeBulkOperationResponse{} }
+func (m *DescribeBulkOperationResponse) String() string { return proto.CompactTextString(m) }
+func (*DescribeBulkOperationResponse) ProtoMessage()    {}
+func (*DescribeBulkOperationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{90}
+}
+
+func (m *DescribeBulkOperationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeBulkOperationResponse.Unmarshal(m, b)
+}
+func (m *DescribeBulkOperationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeBulkOperationResponse.Marshal(b, m, deterministic)
+}
+func (m *DescribeBulkOperationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeBulkOperationResponse.Merge(m, src)
+}
+func (m *DescribeBulkOperationResponse) XXX_Size() int {
+	return xxx_messageInfo_DescribeBulkOperationResponse.Size(m)
+}
+func (m *DescribeBulkOperationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeBulkOperationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeBulkOperationResponse proto.InternalMessageInfo
+
+func (m *DescribeBulkOperationResponse) GetStatus() *BulkOperationStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *DescribeBulkOperationResponse) GetResults() []*BulkOperationResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type WatchBulkOperationRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchBulkOperationRequest) Reset()         { *m = WatchBulkOperationRequest{} }
+func (m *WatchBulkOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchBulkOperationRequest) ProtoMessage()    {}
+func (*WatchBulkOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{91}
+}
+
+func (m *WatchBulkOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchBulkOperationRequest.Unmarshal(m, b)
+}
+func (m *WatchBulkOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchBulkOperationRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchBulkOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchBulkOperationRequest.Merge(m, src)
+}
+func (m *WatchBulkOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchBulkOperationRequest.Size(m)
+}
+func (m *WatchBulkOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchBulkOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchBulkOperationRequest proto.InternalMessageInfo
+
+func (m *WatchBulkOperationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// BulkOperationUpdate carries the result for a workspace, and the progress of the operation after that result.
+// The last update of a completed or cancelled operation carries no result.
+type BulkOperationUpdate struct {
+	Status               *BulkOperationStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Result               *BulkOperationResult `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BulkOperationUpdate) Reset()         { *m = BulkOperationUpdate{} }
+func (m *BulkOperationUpdate) String() string { return proto.CompactTextString(m) }
+func (*BulkOperationUpdate) ProtoMessage()    {}
+func (*BulkOperationUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{92}
+}
+
+func (m *BulkOperationUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkOperationUpdate.Unmarshal(m, b)
+}
+func (m *BulkOperationUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkOperationUpdate.Marshal(b, m, deterministic)
+}
+func (m *BulkOperationUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkOperationUpdate.Merge(m, src)
+}
+func (m *BulkOperationUpdate) XXX_Size() int {
+	return xxx_messageInfo_BulkOperationUpdate.Size(m)
+}
+func (m *BulkOperationUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkOperationUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkOperationUpdate proto.InternalMessageInfo
+
+func (m *BulkOperationUpdate) GetStatus() *BulkOperationStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *BulkOperationUpdate) GetResult() *BulkOperationResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type CancelBulkOperationRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelBulkOperationRequest) Reset()         { *m = CancelBulkOperationRequest{} }
+func (m *CancelBulkOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelBulkOperationRequest) ProtoMessage()    {}
+func (*CancelBulkOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{93}
+}
+
+func (m *CancelBulkOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelBulkOperationRequest.Unmarshal(m, b)
+}
+func (m *CancelBulkOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelBulkOperationRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelBulkOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelBulkOperationRequest.Merge(m, src)
+}
+func (m *CancelBulkOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelBulkOperationRequest.Size(m)
+}
+func (m *CancelBulkOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelBulkOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelBulkOperationRequest proto.InternalMessageInfo
+
+func (m *CancelBulkOperationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type CancelBulkOperationResponse struct {
+	Status               *BulkOperationStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CancelBulkOperationResponse) Reset()         { *m = CancelBulkOperationResponse{} }
+func (m *CancelBulkOperationResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelBulkOperationResponse) ProtoMessage()    {}
+func (*CancelBulkOperationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{94}
+}
+
+func (m *CancelBulkOperationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelBulkOperationResponse.Unmarshal(m, b)
+}
+func (m *CancelBulkOperationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelBulkOperationResponse.Marshal(b, m, deterministic)
+}
+func (m *CancelBulkOperationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelBulkOperationResponse.Merge(m, src)
+}
+func (m *CancelBulkOperationResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelBulkOperationResponse.Size(m)
+}
+func (m *CancelBulkOperationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelBulkOperationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelBulkOperationResponse proto.InternalMessageInfo
+
+func (m *CancelBulkOperationResponse) GetStatus() *BulkOperationStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// AuditRecord is a control action which affected a workspace, e.g. starting or stopping it
+type AuditRecord struct {
+	// sequence identifies the record. Sequences increase by one from record to record without gaps.
+	Sequence uint64               `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Time     *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// action is the name of the action, e.g. StopWorkspace or timeout for workspaces ws-manager stops on its own
+	Action string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	// id is the ID of the workspace instance. Empty for actions which affect many workspaces, e.g. StartBulkOperation.
+	Id string `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	// metadata is the metadata of the workspace, as far as ws-manager knew it when recording the action
+	Metadata *WorkspaceMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// actor is who asked for the action, as the caller claims in the x-gitpod-audit-actor request metadata (e.g. a user ID).
+	// Actions ws-manager takes on its own have the actor ws-manager.
+	Actor string `protobuf:"bytes,6,opt,name=actor,proto3" json:"actor,omitempty"`
+	// source is the component which asked for the action: the common name of its client certificate if it presented one,
+	// otherwise what it claims in the x-gitpod-audit-source request metadata
+	Source string `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	// peer is the network address the request came from
+	Peer string `protobuf:"bytes,8,opt,name=peer,proto3" json:"peer,omitempty"`
+	// parameters are the parameters of the action which matter to an audit, e.g. the new timeout
+	Parameters map[string]string `protobuf:"bytes,9,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// error is empty if the action succeeded
+	Error                string   `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditRecord) Reset()         { *m = AuditRecord{} }
+func (m *AuditRecord) String() string { return proto.CompactTextString(m) }
+func (*AuditRecord) ProtoMessage()    {}
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{95}
+}
+
+func (m *AuditRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRecord.Unmarshal(m, b)
+}
+func (m *AuditRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRecord.Marshal(b, m, deterministic)
+}
+func (m *AuditRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRecord.Merge(m, src)
+}
+func (m *AuditRecord) XXX_Size() int {
+	return xxx_messageInfo_AuditRecord.Size(m)
+}
+func (m *AuditRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRecord proto.InternalMessageInfo
+
+func (m *AuditRecord) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *AuditRecord) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetMetadata() *WorkspaceMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetParameters() map[string]string {
+	if m != nil {
+		return m.Parameters
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// QueryAuditLogRequest selects audit records. A record must match all criteria which are set.
+type QueryAuditLogRequest struct {
+	// id matches the records of this workspace instance
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// meta_id matches the records of all instances of this workspace
+	MetaId string `protobuf:"bytes,2,opt,name=meta_id,json=metaId,proto3" json:"meta_id,omitempty"`
+	// owner matches the records of the workspaces of this user
+	Owner string `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	// actions matches the records of any of these actions
+	Actions []string `protobuf:"bytes,4,rep,name=actions,proto3" json:"actions,omitempty"`
+	// since and until limit the time of the records
+	Since *timestamp.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until *timestamp.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	// after_sequence skips all records up to and including this sequence, e.g. to fetch the next page
+	AfterSequence uint64 `protobuf:"varint,7,opt,name=after_sequence,json=afterSequence,proto3" json:"after_sequence,omitempty"`
+	// limit is the maximum number of records to return. Defaults to 100, at most 1000.
+	Limit                uint32   `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryAuditLogRequest) Reset()         { *m = QueryAuditLogRequest{} }
+func (m *QueryAuditLogRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryAuditLogRequest) ProtoMessage()    {}
+func (*QueryAuditLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{96}
+}
+
+func (m *QueryAuditLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryAuditLogRequest.Unmarshal(m, b)
+}
+func (m *QueryAuditLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryAuditLogRequest.Marshal(b, m, deterministic)
+}
+func (m *QueryAuditLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryAuditLogRequest.Merge(m, src)
+}
+func (m *QueryAuditLogRequest) XXX_Size() int {
+	return xxx_messageInfo_QueryAuditLogRequest.Size(m)
+}
+func (m *QueryAuditLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryAuditLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryAuditLogRequest proto.InternalMessageInfo
+
+func (m *QueryAuditLogRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *QueryAuditLogRequest) GetMetaId() string {
+	if m != nil {
+		return m.MetaId
+	}
+	return ""
+}
+
+func (m *QueryAuditLogRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *QueryAuditLogRequest) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *QueryAuditLogRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *QueryAuditLogRequest) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *QueryAuditLogRequest) GetAfterSequence() uint64 {
+	if m != nil {
+		return m.AfterSequence
+	}
+	return 0
+}
+
+func (m *QueryAuditLogRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type QueryAuditLogResponse struct {
+	Records []*AuditRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	// more is true if there are more records which match
+	More                 bool     `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryAuditLogResponse) Reset()         { *m = QueryAuditLogResponse{} }
+func (m *QueryAuditLogResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryAuditLogResponse) ProtoMessage()    {}
+func (*QueryAuditLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{97}
+}
+
+func (m *QueryAuditLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryAuditLogResponse.Unmarshal(m, b)
+}
+func (m *QueryAuditLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryAuditLogResponse.Marshal(b, m, deterministic)
+}
+func (m *QueryAuditLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryAuditLogResponse.Merge(m, src)
+}
+func (m *QueryAuditLogResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryAuditLogResponse.Size(m)
+}
+func (m *QueryAuditLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryAuditLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryAuditLogResponse proto.InternalMessageInfo
+
+func (m *QueryAuditLogResponse) GetRecords() []*AuditRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+func (m *QueryAuditLogResponse) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+type SubscribeAuditLogRequest struct {
+	// after_sequence is the sequence of the last record the consumer processed. The stream starts with the next record.
+	// Zero starts with the oldest record ws-manager retains.
+	AfterSequence        uint64   `protobuf:"varint,1,opt,name=after_sequence,json=afterSequence,proto3" json:"after_sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeAuditLogRequest) Reset()         { *m = SubscribeAuditLogRequest{} }
+func (m *SubscribeAuditLogRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeAuditLogRequest) ProtoMessage()    {}
+func (*SubscribeAuditLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{98}
+}
+
+func (m *SubscribeAuditLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeAuditLogRequest.Unmarshal(m, b)
+}
+func (m *SubscribeAuditLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeAuditLogRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeAuditLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeAuditLogRequest.Merge(m, src)
+}
+func (m *SubscribeAuditLogRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeAuditLogRequest.Size(m)
+}
+func (m *SubscribeAuditLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeAuditLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeAuditLogRequest proto.InternalMessageInfo
+
+func (m *SubscribeAuditLogRequest) GetAfterSequence() uint64 {
+	if m != nil {
+		return m.AfterSequence
+	}
+	return 0
+}
+
+// PortSpec describes a networking port exposed on a workspace
+type PortSpec struct {
+	// port is the outward-facing port
+	Port uint32 `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	// target is the inward-facing target port
+	Target uint32 `protobuf:"varint,2,opt,name=target,proto3" json:"target,omitempty"`
+	// visibility defines the visibility of the port
+	Visibility PortVisibility `protobuf:"varint,3,opt,name=visibility,proto3,enum=wsman.PortVisibility" json:"visibility,omitempty"`
+	// url is the public-facing URL this port is available at
+	Url string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	// permissive_cors makes ws-proxy allow cross-origin requests to this port from any origin
+	PermissiveCors       bool     `protobuf:"varint,5,opt,name=permissive_cors,json=permissiveCors,proto3" json:"permissive_cors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PortSpec) Reset()         { *m = PortSpec{} }
+func (m *PortSpec) String() string { return proto.CompactTextString(m) }
+func (*PortSpec) ProtoMessage()    {}
+func (*PortSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{99}
+}
+
+func (m *PortSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PortSpec.Unmarshal(m, b)
+}
+func (m *PortSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PortSpec.Marshal(b, m, deterministic)
+}
+func (m *PortSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PortSpec.Merge(m, src)
+}
+func (m *PortSpec) XXX_Size() int {
+	return xxx_messageInfo_PortSpec.Size(m)
+}
+func (m *PortSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_PortSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PortSpec proto.InternalMessageInfo
+
+func (m *PortSpec) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *PortSpec) GetTarget() uint32 {
+	if m != nil {
+		return m.Target
+	}
+	return 0
+}
+
+func (m *PortSpec) GetVisibility() PortVisibility {
+	if m != nil {
+		return m.Visibility
+	}
+	return PortVisibility_PORT_VISIBILITY_PRIVATE
+}
+
+func (m *PortSpec) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *PortSpec) GetPermissiveCors() bool {
+	if m != nil {
+		return m.PermissiveCors
+	}
+	return false
+}
+
+// WorkspaceCondition gives more detailed information as to the state of the workspace. Which condition actually
+// has a value depends on the phase the workspace is in.
+type WorkspaceConditions struct {
+	// failed contains the reason the workspace failed to operate. If this field is empty, the workspace has not failed.
+	Failed string `protobuf:"bytes,1,opt,name=failed,proto3" json:"failed,omitempty"`
+	// timeout contains the reason the workspace has timed out. If this field is empty, the workspace has not timed out.
+	Timeout string `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// pulling_images marks if the workspace is currently pulling its images. This condition can only be set during PhaseCreating
+	PullingImages WorkspaceConditionBool `protobuf:"varint,3,opt,name=pulling_images,json=pullingImages,proto3,enum=wsman.WorkspaceConditionBool" json:"pulling_images,omitempty"`
+	// service_exists denotes if the workspace theia-/ports- services exist. This condition will be true if either of the two services exist.
+	ServiceExists WorkspaceConditionBool `protobuf:"varint,4,opt,name=service_exists,json=serviceExists,proto3,enum=wsman.WorkspaceConditionBool" json:"service_exists,omitempty"`
+	// snapshot contains a snapshot URL if a snapshot was produced prior to shutting the workspace down. This condition is only used for headless workspaces.
+	Snapshot string `protobuf:"bytes,5,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// final_backup_complete determines if the last state of the workspace has been backed up to remote storage.
+	// Once this is true, a new workspace with the same ID will be able to use this backup.
+	FinalBackupComplete WorkspaceConditionBool `protobuf:"varint,6,opt,name=final_backup_complete,json=finalBackupComplete,proto3,enum=wsman.WorkspaceConditionBool" json:"final_backup_complete,omitempty"`
+	// deployed indicates if a workspace container is currently deployed. If this condition is false, there is no means for the user to alter the workspace content.
+	Deployed WorkspaceConditionBool `protobuf:"varint,7,opt,name=deployed,proto3,enum=wsman.WorkspaceConditionBool" json:"deployed,omitempty"`
+	// network_not_ready indicates if a workspace container is currently experiencing a network problem.
+	NetworkNotReady WorkspaceConditionBool `protobuf:"varint,8,opt,name=network_not_ready,json=networkNotReady,proto3,enum=wsman.WorkspaceConditionBool" json:"network_not_ready,omitempty"`
+	// first_user_activity is the time when MarkActive was first called on the workspace
+	FirstUserActivity *timestamp.Timestamp `protobuf:"bytes,9,opt,name=first_user_activity,json=firstUserActivity,proto3" json:"first_user_activity,omitempty"`
+	// paused is true while the workspace was pre-created and waits to be activated
+	Paused WorkspaceConditionBool `protobuf:"varint,10,opt,name=paused,proto3,enum=wsman.WorkspaceConditionBool" json:"paused,omitempty"`
+	// migrate_to is the cluster a bulk migration moves the workspace to. The next instance of the workspace should start there.
+	MigrateTo            string   `protobuf:"bytes,11,opt,name=migrate_to,json=migrateTo,proto3" json:"migrate_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceConditions) Reset()         { *m = WorkspaceConditions{} }
+func (m *WorkspaceConditions) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceConditions) ProtoMessage()    {}
+func (*WorkspaceConditions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{100}
+}
+
+func (m *WorkspaceConditions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceConditions.Unmarshal(m, b)
+}
+func (m *WorkspaceConditions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceConditions.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceConditions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceConditions.Merge(m, src)
+}
+func (m *WorkspaceConditions) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceConditions.Size(m)
+}
+func (m *WorkspaceConditions) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceConditions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceConditions proto.InternalMessageInfo
+
+func (m *WorkspaceConditions) GetFailed() string {
+	if m != nil {
+		return m.Failed
+	}
+	return ""
+}
+
+func (m *WorkspaceConditions) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+func (m *WorkspaceConditions) GetPullingImages() WorkspaceConditionBool {
+	if m != nil {
+		return m.PullingImages
+	}
+	return WorkspaceConditionBool_FALSE
+}
+
+func (m *WorkspaceConditions) GetServiceExists() WorkspaceConditionBool {
+	if m != nil {
+		return m.ServiceExists
+	}
+	return WorkspaceConditionBool_FALSE
+}
+
+func (m *WorkspaceConditions) GetSnapshot() string {
+	if m != nil {
+		return m.Snapshot
+	}
+	return ""
+}
+
+func (m *WorkspaceConditions) GetFinalBackupComplete() WorkspaceConditionBool {
+	if m != nil {
+		return m.FinalBackupComplete
+	}
+	return WorkspaceConditionBool_FALSE
+}
+
+func (m *WorkspaceConditions) GetDeployed() WorkspaceConditionBool {
+	if m != nil {
+		return m.Deployed
+	}
+	return WorkspaceConditionBool_FALSE
+}
+
+func (m *WorkspaceConditions) GetNetworkNotReady() WorkspaceConditionBool {
+	if m != nil {
+		return m.NetworkNotReady
+	}
+	return WorkspaceConditionBool_FALSE
+}
+
+func (m *WorkspaceConditions) GetFirstUserActivity() *timestamp.Timestamp {
+	if m != nil {
+		return m.FirstUserActivity
+	}
+	return nil
+}
+
+func (m *WorkspaceConditions) GetPaused() WorkspaceConditionBool {
+	if m != nil {
+		return m.Paused
+	}
+	return WorkspaceConditionBool_FALSE
+}
+
+func (m *WorkspaceConditions) GetMigrateTo() string {
+	if m != nil {
+		return m.MigrateTo
+	}
+	return ""
+}
+
+// WorkspaceMetadata is data associated with a workspace that's required for other parts of the system to function
+type WorkspaceMetadata struct {
+	// owner is the ID of the Gitpod user to whom we'll bill this workspace and who we consider responsible for its content
+	Owner string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	// meta_id is the workspace ID of this currently running workspace instance on the "meta pool" side
+	MetaId string `protobuf:"bytes,2,opt,name=meta_id,json=metaId,proto3" json:"meta_id,omitempty"`
+	// started_at is the time when this workspace was started. Consider this field read-only, i.e. setting in a request will have no effect.
+	StartedAt *timestamp.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// group_id is the workspace group this workspace belongs to. Consider this field read-only - use StartWorkspaceGroup to start group members.
+	GroupId string `protobuf:"bytes,4,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// project is the project the workspace belongs to (e.g. gitpod-io/gitpod). May be empty.
+	Project              string   `protobuf:"bytes,5,opt,name=project,proto3" json:"project,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceMetadata) Reset()         { *m = WorkspaceMetadata{} }
+func (m *WorkspaceMetadata) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceMetadata) ProtoMessage()    {}
+func (*WorkspaceMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{101}
+}
+
+func (m *WorkspaceMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceMetadata.Unmarshal(m, b)
+}
+func (m *WorkspaceMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceMetadata.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceMetadata.Merge(m, src)
+}
+func (m *WorkspaceMetadata) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceMetadata.Size(m)
+}
+func (m *WorkspaceMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceMetadata proto.InternalMessageInfo
+
+func (m *WorkspaceMetadata) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *WorkspaceMetadata) GetMetaId() string {
+	if m != nil {
+		return m.MetaId
+	}
+	return ""
+}
+
+func (m *WorkspaceMetadata) GetStartedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartedAt
+	}
+	return nil
+}
+
+func (m *WorkspaceMetadata) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *WorkspaceMetadata) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
+}
+
+// WorkspaceRuntimeInfo details the workspace's runtime, e.g. executing system, node other information
+// about the environment the workspace runs in. This information serves a diangostic purpose only and
+// should not be directly acted upon.
+type WorkspaceRuntimeInfo struct {
+	// node_name is the name of the node the workspace runs on
+	NodeName string `protobuf:"bytes,1,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	// pod_name is the name of the pod the workspace runs in
+	PodName string `protobuf:"bytes,2,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
+	// node_ip is the IP of the node the workspace runs on
+	NodeIp string `protobuf:"bytes,3,opt,name=node_ip,json=nodeIp,proto3" json:"node_ip,omitempty"`
+	// pod_ip is the IP of the pod the workspace runs in
+	PodIp                string   `protobuf:"bytes,4,opt,name=pod_ip,json=podIp,proto3" json:"pod_ip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceRuntimeInfo) Reset()         { *m = WorkspaceRuntimeInfo{} }
+func (m *WorkspaceRuntimeInfo) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceRuntimeInfo) ProtoMessage()    {}
+func (*WorkspaceRuntimeInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{102}
+}
+
+func (m *WorkspaceRuntimeInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceRuntimeInfo.Unmarshal(m, b)
+}
+func (m *WorkspaceRuntimeInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceRuntimeInfo.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceRuntimeInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceRuntimeInfo.Merge(m, src)
+}
+func (m *WorkspaceRuntimeInfo) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceRuntimeInfo.Size(m)
+}
+func (m *WorkspaceRuntimeInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceRuntimeInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceRuntimeInfo proto.InternalMessageInfo
+
+func (m *WorkspaceRuntimeInfo) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *WorkspaceRuntimeInfo) GetPodName() string {
+	if m != nil {
+		return m.PodName
+	}
+	return ""
+}
+
+func (m *WorkspaceRuntimeInfo) GetNodeIp() string {
+	if m != nil {
+		return m.NodeIp
+	}
+	return ""
+}
+
+func (m *WorkspaceRuntimeInfo) GetPodIp() string {
+	if m != nil {
+		return m.PodIp
+	}
+	return ""
+}
+
+// WorkspaceAuthentication contains authentication information used by ws-proxy to allow/deny access to
+// workspaces and their ports.
+type WorkspaceAuthentication struct {
+	// Admission describes who can access the workspace and its ports.
+	Admission AdmissionLevel `protobuf:"varint,1,opt,name=admission,proto3,enum=wsman.AdmissionLevel" json:"admission,omitempty"`
+	// Owner token is the token one needs to access the workspace. Its presence is checked by ws-proxy.
+	OwnerToken           string   `protobuf:"bytes,2,opt,name=owner_token,json=ownerToken,proto3" json:"owner_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceAuthentication) Reset()         { *m = WorkspaceAuthentication{} }
+func (m *WorkspaceAuthentication) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceAuthentication) ProtoMessage()    {}
+func (*WorkspaceAuthentication) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{103}
+}
+
+func (m *WorkspaceAuthentication) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceAuthentication.Unmarshal(m, b)
+}
+func (m *WorkspaceAuthentication) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceAuthentication.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceAuthentication) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceAuthentication.Merge(m, src)
+}
+func (m *WorkspaceAuthentication) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceAuthentication.Size(m)
+}
+func (m *WorkspaceAuthentication) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceAuthentication.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceAuthentication proto.InternalMessageInfo
+
+func (m *WorkspaceAuthentication) GetAdmission() AdmissionLevel {
+	if m != nil {
+		return m.Admission
+	}
+	return AdmissionLevel_ADMIT_OWNER_ONLY
+}
+
+func (m *WorkspaceAuthentication) GetOwnerToken() string {
+	if m != nil {
+		return m.OwnerToken
+	}
+	return ""
+}
+
+// StartWorkspaceSpec specifies the configuration of a workspace for a workspace start
+type StartWorkspaceSpec struct {
+	// workspace_image is the Docker image name of the workspace container
+	WorkspaceImage string `protobuf:"bytes,1,opt,name=workspace_image,json=workspaceImage,proto3" json:"workspace_image,omitempty"`
+	// ide_image is the Docker image name of the IDE image
+	IdeImage string `protobuf:"bytes,2,opt,name=ide_image,json=ideImage,proto3" json:"ide_image,omitempty"`
+	// feature_flags provide a means for starting variants of workspaces (e.g. a privileged one)
+	FeatureFlags []WorkspaceFeatureFlag `protobuf:"varint,3,rep,packed,name=feature_flags,json=featureFlags,proto3,enum=wsman.WorkspaceFeatureFlag" json:"feature_flags,omitempty"`
+	// initializer configures how the workspace is to be initialized
+	Initializer *api.WorkspaceInitializer `protobuf:"bytes,4,opt,name=initializer,proto3" json:"initializer,omitempty"`
+	// ports is the set of ports which ought to be exposed to the internet
+	Ports []*PortSpec `protobuf:"bytes,5,rep,name=ports,proto3" json:"ports,omitempty"`
+	// envvars are user-defined environment variables which ought to be available in the workspace
+	Envvars []*EnvironmentVariable `protobuf:"bytes,6,rep,name=envvars,proto3" json:"envvars,omitempty"`
+	// checkout_location describes where the code has been checked out to
+	CheckoutLocation string `protobuf:"bytes,7,opt,name=checkout_location,json=checkoutLocation,proto3" json:"checkout_location,omitempty"`
+	// workspace_location describes where the workspace root of Theia will be
+	WorkspaceLocation string `protobuf:"bytes,8,opt,name=workspace_location,json=workspaceLocation,proto3" json:"workspace_location,omitempty"`
+	// Git configures the Git user in the workspace
+	Git *GitSpec `protobuf:"bytes,9,opt,name=git,proto3" json:"git,omitempty"`
+	// timeout optionally sets a custom workspace timeout
+	Timeout string `protobuf:"bytes,10,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// admission controlls who can access the workspace and its ports.
+	Admission AdmissionLevel `protobuf:"varint,11,opt,name=admission,proto3,enum=wsman.AdmissionLevel" json:"admission,omitempty"`
+	// experiments assigns the workspace to variants of experiments, e.g. to A/B test the delivery of the IDE.
+	// Keys are experiment names, values the variant names.
+	Experiments map[string]string `protobuf:"bytes,12,rep,name=experiments,proto3" json:"experiments,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// init_containers names the init containers from ws-manager's allowlist which run before the workspace starts,
+	// e.g. to install certificates or scan the workspace image. Unknown names fail the request.
+	InitContainers []string `protobuf:"bytes,13,rep,name=init_containers,json=initContainers,proto3" json:"init_containers,omitempty"`
+	// pinned_ide_image, if set, overrides ide_image, e.g. to reproduce a bug or to stay on an older IDE version.
+	// ws-manager only accepts images from its configured repositories which are compatible with the supervisor
+	// version of the installation.
+	PinnedIdeImage string `protobuf:"bytes,14,opt,name=pinned_ide_image,json=pinnedIdeImage,proto3" json:"pinned_ide_image,omitempty"`
+	// ssh_keys are the user's SSH keys which supervisor loads into the workspace's SSH agent
+	SshKeys              []*WorkspaceSSHKey `protobuf:"bytes,15,rep,name=ssh_keys,json=sshKeys,proto3" json:"ssh_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StartWorkspaceSpec) Reset()         { *m = StartWorkspaceSpec{} }
+func (m *StartWorkspaceSpec) String() string { return proto.CompactTextString(m) }
+func (*StartWorkspaceSpec) ProtoMessage()    {}
+func (*StartWorkspaceSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{104}
+}
+
+func (m *StartWorkspaceSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartWorkspaceSpec.Unmarshal(m, b)
+}
+func (m *StartWorkspaceSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartWorkspaceSpec.Marshal(b, m, deterministic)
+}
+func (m *StartWorkspaceSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartWorkspaceSpec.Merge(m, src)
+}
+func (m *StartWorkspaceSpec) XXX_Size() int {
+	return xxx_messageInfo_StartWorkspaceSpec.Size(m)
+}
+func (m *StartWorkspaceSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartWorkspaceSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartWorkspaceSpec proto.InternalMessageInfo
+
+func (m *StartWorkspaceSpec) GetWorkspaceImage() string {
+	if m != nil {
+		return m.WorkspaceImage
+	}
+	return ""
+}
+
+func (m *StartWorkspaceSpec) GetIdeImage() string {
+	if m != nil {
+		return m.IdeImage
+	}
+	return ""
+}
+
+func (m *StartWorkspaceSpec) GetFeatureFlags() []WorkspaceFeatureFlag {
+	if m != nil {
+		return m.FeatureFlags
+	}
+	return nil
+}
+
+func (m *StartWorkspaceSpec) GetInitializer() *api.WorkspaceInitializer {
+	if m != nil {
+		return m.Initializer
+	}
+	return nil
+}
+
+func (m *StartWorkspaceSpec) GetPorts() []*PortSpec {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+func (m *StartWorkspaceSpec) GetEnvvars() []*EnvironmentVariable {
+	if m != nil {
+		return m.Envvars
+	}
+	return nil
+}
+
+func (m *StartWorkspaceSpec) GetCheckoutLocation() string {
+	if m != nil {
+		return m.CheckoutLocation
+	}
+	return ""
+}
+
+func (m *StartWorkspaceSpec) GetWorkspaceLocation() string {
+	if m != nil {
+		return m.WorkspaceLocation
+	}
+	return ""
+}
+
+func (m *StartWorkspaceSpec) GetGit() *GitSpec {
+	if m != nil {
+		return m.Git
+	}
+	return nil
+}
+
+func (m *StartWorkspaceSpec) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+func (m *StartWorkspaceSpec) GetAdmission() AdmissionLevel {
+	if m != nil {
+		return m.Admission
+	}
+	return AdmissionLevel_ADMIT_OWNER_ONLY
+}
+
+func (m *StartWorkspaceSpec) GetExperiments() map[string]string {
+	if m != nil {
+		return m.Experiments
+	}
+	return nil
+}
+
+func (m *StartWorkspaceSpec) GetInitContainers() []string {
+	if m != nil {
+		return m.InitContainers
+	}
+	return nil
+}
+
+func (m *StartWorkspaceSpec) GetPinnedIdeImage() string {
+	if m != nil {
+		return m.PinnedIdeImage
+	}
+	return ""
+}
+
+func (m *StartWorkspaceSpec) GetSshKeys() []*WorkspaceSSHKey {
+	if m != nil {
+		return m.SshKeys
+	}
+	return nil
+}
+
+// WorkspaceSSHKey refers to an SSH key which supervisor downloads when the workspace starts
+type WorkspaceSSHKey struct {
+	// name identifies the key, e.g. in the SSH agent
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// key_ots is the URL of the one-time secret which holds the private key
+	KeyOts               string   `protobuf:"bytes,2,opt,name=key_ots,json=keyOts,proto3" json:"key_ots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceSSHKey) Reset()         { *m = WorkspaceSSHKey{} }
+func (m *WorkspaceSSHKey) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceSSHKey) ProtoMessage()    {}
+func (*WorkspaceSSHKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{105}
+}
+
+func (m *WorkspaceSSHKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceSSHKey.Unmarshal(m, b)
+}
+func (m *WorkspaceSSHKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceSSHKey.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceSSHKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceSSHKey.Merge(m, src)
+}
+func (m *WorkspaceSSHKey) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceSSHKey.Size(m)
+}
+func (m *WorkspaceSSHKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceSSHKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceSSHKey proto.InternalMessageInfo
+
+func (m *WorkspaceSSHKey) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WorkspaceSSHKey) GetKeyOts() string {
+	if m != nil {
+		return m.KeyOts
+	}
+	return ""
+}
+
+// GitSpec configures the Git available within the workspace
+type GitSpec struct {
+	// The Git username
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// The Git email address
+	Email                string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GitSpec) Reset()         { *m = GitSpec{} }
+func (m *GitSpec) String() string { return proto.CompactTextString(m) }
+func (*GitSpec) ProtoMessage()    {}
+func (*GitSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{106}
+}
+
+func (m *GitSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GitSpec.Unmarshal(m, b)
+}
+func (m *GitSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GitSpec.Marshal(b, m, deterministic)
+}
+func (m *GitSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GitSpec.Merge(m, src)
+}
+func (m *GitSpec) XXX_Size() int {
+	return xxx_messageInfo_GitSpec.Size(m)
+}
+func (m *GitSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_GitSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GitSpec proto.InternalMessageInfo
+
+func (m *GitSpec) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *GitSpec) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+// EnvironmentVariable describes an env var as key/value pair
+type EnvironmentVariable struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnvironmentVariable) Reset()         { *m = EnvironmentVariable{} }
+func (m *EnvironmentVariable) String() string { return proto.CompactTextString(m) }
+func (*EnvironmentVariable) ProtoMessage()    {}
+func (*EnvironmentVariable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{107}
+}
+
+func (m *EnvironmentVariable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnvironmentVariable.Unmarshal(m, b)
+}
+func (m *EnvironmentVariable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnvironmentVariable.Marshal(b, m, deterministic)
+}
+func (m *EnvironmentVariable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnvironmentVariable.Merge(m, src)
+}
+func (m *EnvironmentVariable) XXX_Size() int {
+	return xxx_messageInfo_EnvironmentVariable.Size(m)
+}
+func (m *EnvironmentVariable) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnvironmentVariable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnvironmentVariable proto.InternalMessageInfo
+
+func (m *EnvironmentVariable) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *EnvironmentVariable) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// HeadlessWorkspaceEvent is a log statement issued by a headless workspace
+type WorkspaceLogMessage struct {
+	// ID is the ID of the workspace this event eminated from
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// metadata is the workspace metadata
+	Metadata *WorkspaceMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Message is the payload associated with this event
+	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkspaceLogMessage) Reset()         { *m = WorkspaceLogMessage{} }
+func (m *WorkspaceLogMessage) String() string { return proto.CompactTextString(m) }
+func (*WorkspaceLogMessage) ProtoMessage()    {}
+func (*WorkspaceLogMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f7e43720d1edc0fe, []int{108}
+}
+
+func (m *WorkspaceLogMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkspaceLogMessage.Unmarshal(m, b)
+}
+func (m *WorkspaceLogMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkspaceLogMessage.Marshal(b, m, deterministic)
+}
+func (m *WorkspaceLogMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkspaceLogMessage.Merge(m, src)
+}
+func (m *WorkspaceLogMessage) XXX_Size() int {
+	return xxx_messageInfo_WorkspaceLogMessage.Size(m)
+}
+func (m *WorkspaceLogMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkspaceLogMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkspaceLogMessage proto.InternalMessageInfo
+
+func (m *WorkspaceLogMessage) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WorkspaceLogMessage) GetMetadata() *WorkspaceMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *WorkspaceLogMessage) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("wsman.StartWorkspaceErrorDomain", StartWorkspaceErrorDomain_name, StartWorkspaceErrorDomain_value)
+	proto.RegisterEnum("wsman.StopWorkspacePolicy", StopWorkspacePolicy_name, StopWorkspacePolicy_value)
+	proto.RegisterEnum("wsman.AccountingEvent", AccountingEvent_name, AccountingEvent_value)
+	proto.RegisterEnum("wsman.PendingOperationKind", PendingOperationKind_name, PendingOperationKind_value)
+	proto.RegisterEnum("wsman.AdmissionLevel", AdmissionLevel_name, AdmissionLevel_value)
+	proto.RegisterEnum("wsman.InitContainerState", InitContainerState_name, InitContainerState_value)
+	proto.RegisterEnum("wsman.BulkOperationState", BulkOperationState_name, BulkOperationState_value)
+	proto.RegisterEnum("wsman.PortVisibility", PortVisibility_name, PortVisibility_value)
+	proto.RegisterEnum("wsman.WorkspaceConditionBool", WorkspaceConditionBool_name, WorkspaceConditionBool_value)
+	proto.RegisterEnum("wsman.WorkspacePhase", WorkspacePhase_name, WorkspacePhase_value)
+	proto.RegisterEnum("wsman.WorkspaceFeatureFlag", WorkspaceFeatureFlag_name, WorkspaceFeatureFlag_value)
+	proto.RegisterEnum("wsman.WorkspaceType", WorkspaceType_name, WorkspaceType_value)
+	proto.RegisterType((*GetWorkspacesRequest)(nil), "wsman.GetWorkspacesRequest")
+	proto.RegisterType((*GetWorkspacesResponse)(nil), "wsman.GetWorkspacesResponse")
+	proto.RegisterType((*StartWorkspaceRequest)(nil), "wsman.StartWorkspaceRequest")
+	proto.RegisterType((*StartWorkspaceResponse)(nil), "wsman.StartWorkspaceResponse")
+	proto.RegisterType((*StartWorkspaceErrorDetails)(nil), "wsman.StartWorkspaceErrorDetails")
+	proto.RegisterType((*StopWorkspaceRequest)(nil), "wsman.StopWorkspaceRequest")
+	proto.RegisterType((*StopWorkspaceResponse)(nil), "wsman.StopWorkspaceResponse")
+	proto.RegisterType((*DescribeWorkspaceRequest)(nil), "wsman.DescribeWorkspaceRequest")
+	proto.RegisterType((*DescribeWorkspaceResponse)(nil), "wsman.DescribeWorkspaceResponse")
+	proto.RegisterType((*SubscribeRequest)(nil), "wsman.SubscribeRequest")
+	proto.RegisterType((*SubscribeResponse)(nil), "wsman.SubscribeResponse")
+	proto.RegisterMapType((map[string]string)(nil), "wsman.SubscribeResponse.HeaderEntry")
+	proto.RegisterType((*MarkActiveRequest)(nil), "wsman.MarkActiveRequest")
+	proto.RegisterType((*MarkActiveResponse)(nil), "wsman.MarkActiveResponse")
+	proto.RegisterType((*SetTimeoutRequest)(nil), "wsman.SetTimeoutRequest")
+	proto.RegisterType((*SetTimeoutResponse)(nil), "wsman.SetTimeoutResponse")
+	proto.RegisterType((*ControlPortRequest)(nil), "wsman.ControlPortRequest")
+	proto.RegisterType((*ControlPortResponse)(nil), "wsman.ControlPortResponse")
+	proto.RegisterType((*TakeSnapshotRequest)(nil), "wsman.TakeSnapshotRequest")
+	proto.RegisterType((*TakeSnapshotResponse)(nil), "wsman.TakeSnapshotResponse")
+	proto.RegisterType((*ControlAdmissionRequest)(nil), "wsman.ControlAdmissionRequest")
+	proto.RegisterType((*ControlAdmissionResponse)(nil), "wsman.ControlAdmissionResponse")
+	proto.RegisterType((*SetMaintenanceRequest)(nil), "wsman.SetMaintenanceRequest")
+	proto.RegisterType((*SetMaintenanceResponse)(nil), "wsman.SetMaintenanceResponse")
+	proto.RegisterType((*StartWorkspaceGroupRequest)(nil), "wsman.StartWorkspaceGroupRequest")
+	proto.RegisterType((*WorkspaceGroupMember)(nil), "wsman.WorkspaceGroupMember")
+	proto.RegisterType((*StartWorkspaceGroupResponse)(nil), "wsman.StartWorkspaceGroupResponse")
+	proto.RegisterMapType((map[string]string)(nil), "wsman.StartWorkspaceGroupResponse.UrlsEntry")
+	proto.RegisterType((*StopWorkspaceGroupRequest)(nil), "wsman.StopWorkspaceGroupRequest")
+	proto.RegisterType((*StopWorkspaceGroupResponse)(nil), "wsman.StopWorkspaceGroupResponse")
+	proto.RegisterType((*DescribeWorkspaceGroupRequest)(nil), "wsman.DescribeWorkspaceGroupRequest")
+	proto.RegisterType((*DescribeWorkspaceGroupResponse)(nil), "wsman.DescribeWorkspaceGroupResponse")
+	proto.RegisterType((*ValidatePodTemplateRequest)(nil), "wsman.ValidatePodTemplateRequest")
+	proto.RegisterType((*ValidatePodTemplateResponse)(nil), "wsman.ValidatePodTemplateResponse")
+	proto.RegisterType((*PodTemplateViolation)(nil), "wsman.PodTemplateViolation")
+	proto.RegisterType((*ReportProxyActivityRequest)(nil), "wsman.ReportProxyActivityRequest")
+	proto.RegisterType((*WorkspaceProxyActivity)(nil), "wsman.WorkspaceProxyActivity")
+	proto.RegisterType((*ReportProxyActivityResponse)(nil), "wsman.ReportProxyActivityResponse")
+	proto.RegisterType((*SubscribeAccountingRequest)(nil), "wsman.SubscribeAccountingRequest")
+	proto.RegisterType((*AccountingRecord)(nil), "wsman.AccountingRecord")
+	proto.RegisterType((*AckAccountingRequest)(nil), "wsman.AckAccountingRequest")
+	proto.RegisterType((*AckAccountingResponse)(nil), "wsman.AckAccountingResponse")
+	proto.RegisterType((*ScheduleStopRequest)(nil), "wsman.ScheduleStopRequest")
+	proto.RegisterType((*ScheduleStopResponse)(nil), "wsman.ScheduleStopResponse")
+	proto.RegisterType((*GenerateWorkspaceIDRequest)(nil), "wsman.GenerateWorkspaceIDRequest")
+	proto.RegisterType((*GenerateWorkspaceIDResponse)(nil), "wsman.GenerateWorkspaceIDResponse")
+	proto.RegisterType((*ExportStateRequest)(nil), "wsman.ExportStateRequest")
+	proto.RegisterType((*ExportStateResponse)(nil), "wsman.ExportStateResponse")
+	proto.RegisterType((*ImportStateRequest)(nil), "wsman.ImportStateRequest")
+	proto.RegisterType((*ImportStateResponse)(nil), "wsman.ImportStateResponse")
+	proto.RegisterType((*PendingOperation)(nil), "wsman.PendingOperation")
+	proto.RegisterType((*DescribeArchivalRequest)(nil), "wsman.DescribeArchivalRequest")
+	proto.RegisterType((*DescribeArchivalResponse)(nil), "wsman.DescribeArchivalResponse")
+	proto.RegisterType((*ArchivalStatus)(nil), "wsman.ArchivalStatus")
+	proto.RegisterType((*UnarchiveWorkspaceRequest)(nil), "wsman.UnarchiveWorkspaceRequest")
+	proto.RegisterType((*UnarchiveWorkspaceResponse)(nil), "wsman.UnarchiveWorkspaceResponse")
+	proto.RegisterType((*DeleteWorkspaceRequest)(nil), "wsman.DeleteWorkspaceRequest")
+	proto.RegisterMapType((map[string]string)(nil), "wsman.DeleteWorkspaceRequest.MetadataEntry")
+	proto.RegisterType((*DeleteWorkspaceResponse)(nil), "wsman.DeleteWorkspaceResponse")
+	proto.RegisterType((*RestoreDeletedWorkspaceRequest)(nil), "wsman.RestoreDeletedWorkspaceRequest")
+	proto.RegisterType((*RestoreDeletedWorkspaceResponse)(nil), "wsman.RestoreDeletedWorkspaceResponse")
+	proto.RegisterType((*DescribeDeletedWorkspacesRequest)(nil), "wsman.DescribeDeletedWorkspacesRequest")
+	proto.RegisterType((*DescribeDeletedWorkspacesResponse)(nil), "wsman.DescribeDeletedWorkspacesResponse")
+	proto.RegisterType((*DeletedWorkspace)(nil), "wsman.DeletedWorkspace")
+	proto.RegisterMapType((map[string]string)(nil), "wsman.DeletedWorkspace.MetadataEntry")
+	proto.RegisterType((*WorkspaceTemplateSpec)(nil), "wsman.WorkspaceTemplateSpec")
+	proto.RegisterType((*WorkspaceTemplate)(nil), "wsman.WorkspaceTemplate")
+	proto.RegisterType((*WorkspaceTemplateRef)(nil), "wsman.WorkspaceTemplateRef")
+	proto.RegisterType((*CreateWorkspaceTemplateRequest)(nil), "wsman.CreateWorkspaceTemplateRequest")
+	proto.RegisterType((*CreateWorkspaceTemplateResponse)(nil), "wsman.CreateWorkspaceTemplateResponse")
+	proto.RegisterType((*UpdateWorkspaceTemplateRequest)(nil), "wsman.UpdateWorkspaceTemplateRequest")
+	proto.RegisterType((*UpdateWorkspaceTemplateResponse)(nil), "wsman.UpdateWorkspaceTemplateResponse")
+	proto.RegisterType((*GetWorkspaceTemplateRequest)(nil), "wsman.GetWorkspaceTemplateRequest")
+	proto.RegisterType((*GetWorkspaceTemplateResponse)(nil), "wsman.GetWorkspaceTemplateResponse")
+	proto.RegisterType((*ListWorkspaceTemplatesRequest)(nil), "wsman.ListWorkspaceTemplatesRequest")
+	proto.RegisterType((*ListWorkspaceTemplatesResponse)(nil), "wsman.ListWorkspaceTemplatesResponse")
+	proto.RegisterType((*DeleteWorkspaceTemplateRequest)(nil), "wsman.DeleteWorkspaceTemplateRequest")
+	proto.RegisterType((*DeleteWorkspaceTemplateResponse)(nil), "wsman.DeleteWorkspaceTemplateResponse")
+	proto.RegisterType((*MaintenanceStatus)(nil), "wsman.MaintenanceStatus")
+	proto.RegisterType((*WorkspaceStatus)(nil), "wsman.WorkspaceStatus")
+	proto.RegisterType((*InitContainerStatus)(nil), "wsman.InitContainerStatus")
+	proto.RegisterType((*WorkspaceSpec)(nil), "wsman.WorkspaceSpec")
+	proto.RegisterMapType((map[string]string)(nil), "wsman.WorkspaceSpec.ExperimentsEntry")
+	proto.RegisterType((*IDEPin)(nil), "wsman.IDEPin")
+	proto.RegisterType((*ResetIDEPinRequest)(nil), "wsman.ResetIDEPinRequest")
+	proto.RegisterType((*ResetIDEPinResponse)(nil), "wsman.ResetIDEPinResponse")
+	proto.RegisterType((*BulkOperationFilter)(nil), "wsman.BulkOperationFilter")
+	proto.RegisterType((*StartBulkOperationRequest)(nil), "wsman.StartBulkOperationRequest")
+	proto.RegisterType((*BulkStopOperation)(nil), "wsman.BulkStopOperation")
+	proto.RegisterType((*BulkSetTimeoutOperation)(nil), "wsman.BulkSetTimeoutOperation")
+	proto.RegisterType((*BulkMigrateOperation)(nil), "wsman.BulkMigrateOperation")
+	proto.RegisterType((*StartBulkOperationResponse)(nil), "wsman.StartBulkOperationResponse")
+	proto.RegisterType((*BulkOperationStatus)(nil), "wsman.BulkOperationStatus")
+	proto.RegisterType((*BulkOperationResult)(nil), "wsman.BulkOperationResult")
+	proto.RegisterType((*DescribeBulkOperationRequest)(nil), "wsman.DescribeBulkOperationRequest")
+	proto.RegisterType((*DescribeBulkOperationResponse)(nil), "wsman.DescribeBulkOperationResponse")
+	proto.RegisterType((*WatchBulkOperationRequest)(nil), "wsman.WatchBulkOperationRequest")
+	proto.RegisterType((*BulkOperationUpdate)(nil), "wsman.BulkOperationUpdate")
+	proto.RegisterType((*CancelBulkOperationRequest)(nil), "wsman.CancelBulkOperationRequest")
+	proto.RegisterType((*CancelBulkOperationResponse)(nil), "wsman.CancelBulkOperationResponse")
+	proto.RegisterType((*AuditRecord)(nil), "wsman.AuditRecord")
+	proto.RegisterMapType((map[string]string)(nil), "wsman.AuditRecord.ParametersEntry")
+	proto.RegisterType((*QueryAuditLogRequest)(nil), "wsman.QueryAuditLogRequest")
+	proto.RegisterType((*QueryAuditLogResponse)(nil), "wsman.QueryAuditLogResponse")
+	proto.RegisterType((*SubscribeAuditLogRequest)(nil), "wsman.SubscribeAuditLogRequest")
+	proto.RegisterType((*PortSpec)(nil), "wsman.PortSpec")
+	proto.RegisterType((*WorkspaceConditions)(nil), "wsman.WorkspaceConditions")
+	proto.RegisterType((*WorkspaceMetadata)(nil), "wsman.WorkspaceMetadata")
+	proto.RegisterType((*WorkspaceRuntimeInfo)(nil), "wsman.WorkspaceRuntimeInfo")
+	proto.RegisterType((*WorkspaceAuthentication)(nil), "wsman.WorkspaceAuthentication")
+	proto.RegisterType((*StartWorkspaceSpec)(nil), "wsman.StartWorkspaceSpec")
+	proto.RegisterMapType((map[string]string)(nil), "wsman.StartWorkspaceSpec.ExperimentsEntry")
+	proto.RegisterType((*WorkspaceSSHKey)(nil), "wsman.WorkspaceSSHKey")
+	proto.RegisterType((*GitSpec)(nil), "wsman.GitSpec")
+	proto.RegisterType((*EnvironmentVariable)(nil), "wsman.EnvironmentVariable")
+	proto.RegisterType((*WorkspaceLogMessage)(nil), "wsman.WorkspaceLogMessage")
+}
+
+func init() {
+	proto.RegisterFile("core.proto", fileDescriptor_f7e43720d1edc0fe)
+}
+
+var fileDescriptor_f7e43720d1edc0fe = []byte{
+	// 5659 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x7c, 0x4b, 0x6f, 0x23, 0x49,
+	0x72, 0xb0, 0x48, 0xf1, 0x19, 0xd4, 0x83, 0x4a, 0x49, 0x2d, 0x76, 0xa9, 0x9f, 0x35, 0xd3, 0x33,
+	0x0d, 0xcd, 0xb4, 0x7a, 0x56, 0xdb, 0xb3, 0xf3, 0xda, 0xd9, 0x6f, 0x28, 0x8a, 0x52, 0x73, 0x9b,
+	0x7a, 0x74, 0x91, 0xea, 0x9e, 0xde, 0xcf, 0xd8, 0x42, 0x35, 0x2b, 0xa5, 0xae, 0x15, 0x59, 0x55,
+	0x5b, 0x95, 0xd4, 0xb4, 0x16, 0x5e, 0xfb, 0x60, 0xac, 0x0d, 0xc3, 0x07, 0x7b, 0x17, 0x58, 0xc0,
+	0x07, 0x03, 0x06, 0x7c, 0xf2, 0xc9, 0x30, 0x0c, 0xbf, 0x2e, 0x3e, 0xf9, 0x47, 0xf8, 0xea, 0xf3,
+	0x02, 0x3e, 0xf8, 0x07, 0x18, 0x30, 0xf2, 0x51, 0xc9, 0xaa, 0x62, 0x15, 0xc9, 0xee, 0x19, 0xdf,
+	0x98, 0x99, 0x11, 0x91, 0x91, 0x91, 0x51, 0x11, 0x91, 0x11, 0x99, 0x04, 0xe8, 0x39, 0x1e, 0xde,
+	0x76, 0x3d, 0x87, 0x38, 0x28, 0xff, 0x8d, 0x3f, 0x30, 0x6c, 0xe5, 0x5e, 0xcf, 0xb1, 0x09, 0xb6,
+	0xc9, 0x03, 0x1f, 0x7b, 0x97, 0x56, 0x0f, 0x3f, 0x30, 0x5c, 0xeb, 0xa1, 0x65, 0x5b, 0xc4, 0x32,
+	0xfa, 0xd6, 0x2f, 0xb0, 0xc7, 0xa1, 0x95, 0xdb, 0xe7, 0x8e, 0x73, 0xde, 0xc7, 0x0f, 0x59, 0xeb,
+	0xe5, 0xf0, 0xec, 0x21, 0xb1, 0x06, 0xd8, 0x27, 0xc6, 0xc0, 0xe5, 0x00, 0xea, 0x35, 0x58, 0x3b,
+	0xc0, 0xe4, 0xb9, 0xe3, 0x5d, 0xf8, 0xae, 0xd1, 0xc3, 0xbe, 0x86, 0x7f, 0x3e, 0xc4, 0x3e, 0x51,
+	0x0f, 0x60, 0x3d, 0xd6, 0xef, 0xbb, 0x8e, 0xed, 0x63, 0xb4, 0x0d, 0x05, 0x9f, 0x18, 0x64, 0xe8,
+	0xd7, 0x32, 0x77, 0xe6, 0xef, 0x57, 0x76, 0xae, 0x6d, 0x33, 0x86, 0xb6, 0x25, 0x68, 0x87, 0x8d,
+	0x6a, 0x02, 0x4a, 0xfd, 0xc7, 0x2c, 0xac, 0x77, 0x88, 0xe1, 0x8d, 0x68, 0x89, 0x29, 0xd0, 0x12,
+	0x64, 0x2d, 0xb3, 0x96, 0xb9, 0x93, 0xb9, 0x5f, 0xd6, 0xb2, 0x96, 0x89, 0xee, 0xc1, 0x92, 0x58,
+	0x8c, 0xee, 0x7a, 0xf8, 0xcc, 0x7a, 0x5d, 0xcb, 0xb2, 0xb1, 0x45, 0xd1, 0x7b, 0xc2, 0x3a, 0xd1,
+	0x23, 0x28, 0x0d, 0x30, 0x31, 0x4c, 0x83, 0x18, 0xb5, 0xf9, 0x3b, 0x99, 0xfb, 0x95, 0x9d, 0x5a,
+	0x9c, 0x85, 0x43, 0x31, 0xae, 0x49, 0x48, 0xf4, 0x00, 0x72, 0xbe, 0x8b, 0x7b, 0xb5, 0x1c, 0xc3,
+	0xb8, 0x2e, 0x30, 0xa2, 0x8c, 0x75, 0x5c, 0xdc, 0xd3, 0x18, 0x18, 0xba, 0x0f, 0x39, 0x72, 0xe5,
+	0xe2, 0x5a, 0xe1, 0x4e, 0xe6, 0xfe, 0xd2, 0xce, 0x5a, 0x7c, 0x82, 0xee, 0x95, 0x8b, 0x35, 0x06,
+	0x81, 0xae, 0x41, 0xc1, 0x35, 0x86, 0x3e, 0x36, 0x6b, 0xc5, 0x3b, 0x99, 0xfb, 0x25, 0x4d, 0xb4,
+	0xd0, 0x27, 0x50, 0x22, 0x78, 0xe0, 0xf6, 0x0d, 0x82, 0x6b, 0x25, 0x36, 0xe9, 0xe6, 0x18, 0x15,
+	0x31, 0xae, 0xe1, 0x33, 0x4d, 0x02, 0xff, 0x38, 0x57, 0xca, 0x57, 0x0b, 0xea, 0x63, 0xb8, 0x16,
+	0x97, 0x9a, 0xd8, 0x80, 0x2a, 0xcc, 0x0f, 0xbd, 0xbe, 0x90, 0x1b, 0xfd, 0x89, 0x6e, 0x40, 0xd9,
+	0xe8, 0x11, 0xeb, 0xd2, 0x20, 0xd8, 0x64, 0x32, 0x2b, 0x69, 0xa3, 0x0e, 0xf5, 0x5f, 0x33, 0xa0,
+	0x44, 0x49, 0x35, 0x3d, 0xcf, 0xf1, 0xf6, 0x30, 0x31, 0xac, 0xbe, 0x8f, 0x3e, 0x85, 0x82, 0xe9,
+	0x0c, 0x0c, 0xcb, 0x66, 0x14, 0x97, 0x76, 0xee, 0x24, 0x8a, 0x86, 0xa3, 0x30, 0x38, 0x4d, 0xc0,
+	0xd3, 0x69, 0x3d, 0x4c, 0xbc, 0x2b, 0xe3, 0x65, 0x1f, 0x07, 0xd3, 0xca, 0x0e, 0x74, 0x1b, 0x2a,
+	0xac, 0xa1, 0x1b, 0x67, 0x04, 0x7b, 0x6c, 0xa7, 0xca, 0x1a, 0xb0, 0xae, 0x3a, 0xed, 0x41, 0x77,
+	0x61, 0x61, 0xe8, 0x63, 0x4f, 0x1f, 0x60, 0xdf, 0x37, 0xce, 0x31, 0xdb, 0x99, 0xb2, 0x56, 0xa1,
+	0x7d, 0x87, 0xbc, 0x4b, 0xfd, 0xb3, 0x0c, 0xac, 0x75, 0x88, 0xe3, 0x4e, 0x55, 0x9d, 0x1d, 0x28,
+	0xb8, 0x4e, 0xdf, 0xea, 0x5d, 0x31, 0x3e, 0x96, 0x76, 0x14, 0xb9, 0x88, 0x10, 0xf2, 0x09, 0x83,
+	0xd0, 0x04, 0x24, 0x7a, 0x08, 0xab, 0xf8, 0xb5, 0x8b, 0x7b, 0x04, 0x9b, 0xfa, 0x39, 0xb6, 0xb1,
+	0x67, 0x10, 0xcb, 0xb1, 0x19, 0xa3, 0x39, 0x0d, 0x05, 0x43, 0x07, 0x72, 0x44, 0xdd, 0x80, 0xf5,
+	0x08, 0xbd, 0x60, 0x47, 0xd4, 0x2d, 0xa8, 0xed, 0x61, 0xbf, 0xe7, 0x59, 0x2f, 0xf1, 0x34, 0x4e,
+	0x55, 0x07, 0xae, 0x27, 0xc0, 0x26, 0x7c, 0x5b, 0x99, 0xe9, 0xdf, 0x16, 0x52, 0x61, 0xa1, 0x6f,
+	0xf8, 0xa4, 0x4e, 0xf7, 0xda, 0x22, 0x57, 0xe2, 0x7b, 0x89, 0xf4, 0xa9, 0x08, 0xaa, 0x9d, 0xe1,
+	0x4b, 0x3e, 0x63, 0xf0, 0x71, 0xff, 0x4b, 0x16, 0x56, 0x42, 0x9d, 0x62, 0xf6, 0x8f, 0x66, 0x9b,
+	0xfd, 0xf1, 0x9c, 0x9c, 0x7f, 0x1b, 0xe6, 0xfb, 0xce, 0x39, 0x9b, 0xb6, 0xb2, 0xa3, 0xc4, 0xc1,
+	0xdb, 0xce, 0xb9, 0xd8, 0xc8, 0xc7, 0x73, 0x1a, 0x05, 0x44, 0x3f, 0x84, 0x0a, 0xd5, 0x1c, 0x82,
+	0x6d, 0xc3, 0xee, 0xe1, 0x5a, 0x2e, 0xf2, 0xf5, 0x1e, 0x8e, 0x46, 0xe4, 0x44, 0x61, 0x70, 0xf4,
+	0x43, 0x28, 0xbc, 0xc2, 0x86, 0xc9, 0x94, 0x89, 0x5a, 0x9e, 0x77, 0x83, 0x4d, 0x8e, 0xaf, 0x64,
+	0xfb, 0x31, 0x03, 0x6b, 0xda, 0xc4, 0xbb, 0xd2, 0x04, 0x8e, 0xf2, 0x19, 0x54, 0x42, 0xdd, 0xf4,
+	0x2b, 0xba, 0xc0, 0x57, 0xc1, 0x57, 0x74, 0x81, 0xaf, 0xd0, 0x1a, 0xe4, 0x2f, 0x8d, 0xfe, 0x10,
+	0x0b, 0x29, 0xf2, 0xc6, 0xe7, 0xd9, 0x4f, 0x33, 0xbb, 0x65, 0x28, 0xba, 0xc6, 0x55, 0xdf, 0x31,
+	0x4c, 0xf5, 0x0b, 0x58, 0x39, 0x34, 0xbc, 0x0b, 0x26, 0xdd, 0x54, 0x6d, 0xbc, 0x06, 0x85, 0x5e,
+	0xdf, 0xf1, 0xe5, 0xc7, 0x28, 0x5a, 0xea, 0x1a, 0xa0, 0x30, 0xb2, 0xd0, 0x1e, 0x17, 0x56, 0x3a,
+	0x98, 0x74, 0xad, 0x01, 0x76, 0x86, 0x24, 0x8d, 0xa4, 0x02, 0x25, 0x73, 0x28, 0x34, 0x94, 0xf3,
+	0x27, 0xdb, 0x6f, 0xae, 0xc8, 0x6b, 0x80, 0xc2, 0x33, 0x0a, 0x3e, 0x7e, 0x93, 0x01, 0xd4, 0x70,
+	0x6c, 0xe2, 0x39, 0xfd, 0x13, 0xc7, 0x23, 0x13, 0x16, 0x87, 0x5f, 0xbb, 0x8e, 0x1f, 0x7c, 0xf2,
+	0xa2, 0x85, 0xde, 0x11, 0x06, 0x96, 0x9b, 0xe4, 0x65, 0xb1, 0x37, 0x94, 0x52, 0xc8, 0xac, 0xa6,
+	0xb0, 0x9a, 0x4b, 0x65, 0x75, 0x1d, 0x56, 0x23, 0x3c, 0x09, 0x5e, 0xef, 0xc1, 0x6a, 0xd7, 0xb8,
+	0xc0, 0x1d, 0xdb, 0x70, 0xfd, 0x57, 0x4e, 0x1a, 0xaf, 0xea, 0x7d, 0x58, 0x8b, 0x82, 0xa5, 0x99,
+	0x50, 0xf5, 0x4f, 0x32, 0xb0, 0x21, 0x26, 0xaa, 0x9b, 0x03, 0xcb, 0xf7, 0x2d, 0xc7, 0x4e, 0x93,
+	0xc0, 0x07, 0x90, 0xef, 0xe3, 0x4b, 0xdc, 0x17, 0xb6, 0x66, 0x5d, 0x2c, 0x55, 0xe2, 0xb5, 0xe9,
+	0xa0, 0xc6, 0x61, 0xde, 0x7c, 0x73, 0x14, 0xa8, 0x8d, 0x33, 0x22, 0x96, 0xdd, 0x82, 0xf5, 0x0e,
+	0x26, 0xa1, 0x0f, 0x25, 0x60, 0x31, 0xfe, 0xe9, 0xa6, 0x7e, 0x53, 0xd2, 0x2d, 0xd7, 0xe0, 0x5a,
+	0x9c, 0x94, 0x98, 0xe4, 0x97, 0x71, 0x77, 0x71, 0xe0, 0x39, 0x43, 0x37, 0x4d, 0x18, 0x1f, 0x43,
+	0x71, 0x80, 0x07, 0x2f, 0xb1, 0xe7, 0xd7, 0xb2, 0x77, 0xe6, 0x93, 0xbc, 0x1c, 0x43, 0x3f, 0x64,
+	0x30, 0x5a, 0x00, 0x8b, 0x6a, 0x50, 0x24, 0x5c, 0xff, 0x84, 0x67, 0x08, 0x9a, 0xea, 0xaf, 0x32,
+	0xb0, 0x96, 0x84, 0x8b, 0x10, 0xe4, 0x6c, 0x63, 0x80, 0xc5, 0xdc, 0xec, 0x37, 0xfa, 0x1c, 0xca,
+	0xdf, 0x04, 0xb0, 0xc2, 0x0c, 0xdd, 0x48, 0xf4, 0x5f, 0x82, 0x7d, 0x6d, 0x04, 0x8e, 0x6e, 0x02,
+	0x98, 0xd8, 0xc5, 0xb6, 0xe9, 0xeb, 0x6c, 0x43, 0xe6, 0xef, 0x97, 0xb5, 0xb2, 0xe8, 0x39, 0xb6,
+	0xd5, 0xbf, 0xcc, 0xc0, 0x66, 0xa2, 0x1c, 0x84, 0x0e, 0x7d, 0x05, 0xb9, 0xa1, 0xd7, 0x0f, 0xa2,
+	0xa0, 0x0f, 0x13, 0x67, 0x8d, 0x60, 0x6c, 0x9f, 0x7a, 0x7d, 0x9f, 0xdb, 0x24, 0x86, 0xa9, 0x7c,
+	0x02, 0x65, 0xd9, 0xf5, 0x26, 0xf6, 0x48, 0xd5, 0xe1, 0x7a, 0xc4, 0x11, 0x4d, 0xdc, 0xa0, 0xb7,
+	0x70, 0x8d, 0xea, 0x0d, 0x50, 0x22, 0xc3, 0x91, 0x75, 0xa8, 0x0f, 0xe1, 0xe6, 0x98, 0x0b, 0x9b,
+	0xc4, 0x82, 0x7a, 0x02, 0xb7, 0xd2, 0x10, 0xde, 0x32, 0xa8, 0xfc, 0x14, 0x94, 0x67, 0x46, 0xdf,
+	0x32, 0x0d, 0x82, 0x4f, 0x1c, 0x73, 0x14, 0x47, 0xf1, 0xf9, 0x95, 0x50, 0xe8, 0xc5, 0xb9, 0x90,
+	0x6d, 0xf5, 0x27, 0xb0, 0x99, 0x88, 0x29, 0x18, 0xf9, 0x02, 0xe0, 0xd2, 0x72, 0xfa, 0xec, 0x53,
+	0x0c, 0x98, 0xd9, 0x94, 0xb6, 0x4c, 0xc2, 0x3f, 0x0b, 0x60, 0xb4, 0x10, 0xb8, 0xba, 0x0f, 0x6b,
+	0x49, 0x30, 0x74, 0x27, 0xcf, 0x2c, 0xdc, 0x0f, 0x44, 0xc2, 0x1b, 0xf4, 0x13, 0x08, 0x42, 0x1f,
+	0xbe, 0xc3, 0x41, 0x53, 0xfd, 0x39, 0x28, 0x1a, 0x76, 0x1d, 0x8f, 0x9c, 0x78, 0xce, 0xeb, 0xab,
+	0xc0, 0x93, 0x07, 0xab, 0x5b, 0x83, 0xbc, 0x4b, 0xfb, 0x03, 0x6a, 0xac, 0x81, 0xbe, 0x04, 0x90,
+	0xaa, 0x1d, 0x7c, 0x8a, 0x37, 0xe3, 0x52, 0x8c, 0xd2, 0x0b, 0x21, 0xa8, 0x67, 0x70, 0x2d, 0x19,
+	0x6a, 0x4c, 0x9f, 0xee, 0x40, 0xa5, 0xe7, 0xd8, 0x36, 0xee, 0x71, 0x11, 0x51, 0xd6, 0x17, 0xb5,
+	0x70, 0x17, 0x15, 0xbf, 0x8f, 0x99, 0xe1, 0xf2, 0xd9, 0xc7, 0xbd, 0xa8, 0xc9, 0xb6, 0xfa, 0x15,
+	0x6c, 0x26, 0x2e, 0x4d, 0x88, 0xff, 0x2e, 0x2c, 0x0c, 0x2c, 0x5b, 0xa7, 0x46, 0xc9, 0xbb, 0x34,
+	0x02, 0x0b, 0x5d, 0x19, 0x58, 0x76, 0x4b, 0x74, 0xa9, 0x0d, 0x50, 0xa4, 0xc3, 0xaf, 0xf7, 0x7a,
+	0xce, 0xd0, 0x26, 0x96, 0x7d, 0x1e, 0x08, 0xe7, 0x1e, 0x2c, 0xb1, 0x78, 0x53, 0xf7, 0x69, 0x07,
+	0x0d, 0x32, 0x32, 0xcc, 0xd2, 0x2e, 0xb2, 0xde, 0x8e, 0xe8, 0x54, 0xff, 0x33, 0x0b, 0xd5, 0x30,
+	0x72, 0xcf, 0xf1, 0x4c, 0xce, 0x77, 0x04, 0x4b, 0xb6, 0xd1, 0x87, 0x90, 0xc7, 0x97, 0xd8, 0x26,
+	0xe2, 0x23, 0x0a, 0xf4, 0x73, 0x44, 0xa3, 0x49, 0x47, 0x35, 0x0e, 0x24, 0x64, 0x36, 0x2f, 0x65,
+	0x16, 0x3e, 0xb2, 0xe4, 0x66, 0x3e, 0xb2, 0x04, 0x67, 0x90, 0xfc, 0xd4, 0x33, 0xc8, 0x26, 0x94,
+	0x6d, 0xc7, 0xc4, 0x3a, 0xb3, 0x8f, 0x05, 0xae, 0xf1, 0xb4, 0xe3, 0x88, 0xda, 0xc8, 0x6d, 0xc8,
+	0x51, 0xdb, 0x5a, 0x2b, 0x8a, 0x28, 0x8d, 0x9f, 0x08, 0xb7, 0x83, 0x13, 0xe1, 0x76, 0x37, 0x38,
+	0x11, 0x6a, 0x0c, 0x0e, 0xbd, 0x0f, 0xcb, 0x1e, 0x5d, 0xd3, 0x00, 0xeb, 0x3e, 0xee, 0x39, 0xb6,
+	0xe9, 0xb3, 0xf3, 0x4b, 0x4e, 0x5b, 0x12, 0xdd, 0x1d, 0xde, 0x4b, 0xe3, 0x7f, 0xec, 0x13, 0x6b,
+	0xc0, 0x8e, 0x1d, 0x65, 0x1e, 0xff, 0xcb, 0x0e, 0x75, 0x07, 0xd6, 0xea, 0xbd, 0x8b, 0xf1, 0x1d,
+	0x9a, 0x20, 0x65, 0xf5, 0x0b, 0x58, 0x8f, 0xe1, 0x08, 0xbd, 0x50, 0x61, 0xc1, 0xe8, 0x5d, 0xd8,
+	0xce, 0x37, 0x7d, 0x6c, 0x9e, 0x63, 0x53, 0x20, 0x46, 0xfa, 0xd4, 0x7f, 0xce, 0xc0, 0x6a, 0xa7,
+	0xf7, 0x0a, 0x9b, 0xc3, 0x3e, 0xa6, 0xd6, 0x2b, 0xcd, 0x20, 0x6e, 0x41, 0xd6, 0x20, 0xb5, 0xec,
+	0x54, 0x69, 0x64, 0x0d, 0x12, 0x09, 0xbb, 0xe6, 0x63, 0x61, 0x17, 0x8d, 0xf2, 0xa8, 0xe3, 0xec,
+	0xd7, 0x72, 0x22, 0xca, 0x63, 0xad, 0x34, 0x8f, 0x9f, 0x4f, 0xf5, 0xf8, 0x47, 0xb0, 0x16, 0xe5,
+	0x5b, 0x2c, 0xfa, 0x07, 0x50, 0x32, 0xb1, 0x61, 0xf6, 0x2d, 0x1b, 0xd7, 0x32, 0x53, 0xd9, 0x95,
+	0xb0, 0xea, 0x0f, 0x40, 0x11, 0xd4, 0x47, 0xe6, 0xb6, 0xb5, 0x17, 0x88, 0xa3, 0x06, 0x45, 0xd7,
+	0x73, 0x7e, 0x86, 0x7b, 0x44, 0xc8, 0x24, 0x68, 0xaa, 0x0f, 0x60, 0x33, 0x11, 0x4f, 0xb0, 0x13,
+	0xb7, 0xea, 0x6b, 0x80, 0x9a, 0xaf, 0xe9, 0xa7, 0xdc, 0x21, 0x23, 0xdb, 0xab, 0xfe, 0x71, 0x06,
+	0x56, 0x23, 0xdd, 0x02, 0x9b, 0x6e, 0xbb, 0x08, 0xc3, 0x18, 0x8d, 0x05, 0x4d, 0xb6, 0x29, 0x4b,
+	0x97, 0xd8, 0xf3, 0x83, 0xd8, 0x76, 0x51, 0x0b, 0x9a, 0xe8, 0x33, 0x80, 0x9e, 0x87, 0xa9, 0x3e,
+	0xe9, 0x06, 0xa9, 0xcd, 0x4f, 0x15, 0x42, 0x59, 0x40, 0xd7, 0x89, 0xfa, 0x11, 0xa0, 0xd6, 0x20,
+	0xce, 0xde, 0x24, 0x36, 0xd4, 0xbf, 0xcb, 0xc2, 0x6a, 0x6b, 0x30, 0xce, 0xfa, 0x03, 0x40, 0x1e,
+	0xf6, 0x89, 0xe3, 0x61, 0x53, 0xb7, 0x6c, 0x9f, 0xd0, 0x6d, 0xe6, 0xbe, 0xa1, 0xac, 0xad, 0x04,
+	0x23, 0xad, 0x60, 0x00, 0x7d, 0x00, 0x2b, 0xa6, 0xe7, 0xb8, 0x6e, 0x04, 0x3a, 0xcb, 0xa0, 0xab,
+	0x62, 0x60, 0x04, 0xbc, 0xcf, 0x68, 0x0f, 0x07, 0xd8, 0xd4, 0x1d, 0x57, 0x28, 0x84, 0x2f, 0xce,
+	0x37, 0x1b, 0x81, 0xdf, 0xc1, 0xb6, 0x69, 0xd9, 0xe7, 0xc7, 0xc1, 0xb8, 0xb6, 0x22, 0x50, 0x64,
+	0x8f, 0x8f, 0xbe, 0x82, 0xe5, 0xbe, 0xe3, 0x93, 0x30, 0x91, 0xdc, 0x64, 0x22, 0x4b, 0x14, 0x3e,
+	0x44, 0xe1, 0x7b, 0xb0, 0x16, 0x3a, 0x6c, 0xe9, 0xc1, 0xba, 0x98, 0xde, 0x96, 0xb4, 0xd5, 0x41,
+	0x24, 0x52, 0x64, 0x43, 0xaa, 0x09, 0xd5, 0x38, 0x59, 0x7a, 0xec, 0x0f, 0x56, 0xad, 0x4b, 0x75,
+	0x81, 0xa0, 0xab, 0x65, 0xa2, 0x87, 0x90, 0xbb, 0xb0, 0x6c, 0x53, 0x18, 0xd2, 0xcd, 0x14, 0xf6,
+	0x9e, 0x58, 0xb6, 0xa9, 0x31, 0x40, 0xf5, 0x87, 0xb0, 0x11, 0x44, 0x0f, 0x75, 0xaf, 0xf7, 0xca,
+	0xba, 0x34, 0xfa, 0xc1, 0x6e, 0xde, 0x85, 0x05, 0xe9, 0xc3, 0x46, 0xb3, 0x55, 0x64, 0x5f, 0xcb,
+	0x54, 0x9f, 0x42, 0x6d, 0x1c, 0x5b, 0x6c, 0xec, 0xc7, 0x11, 0x9f, 0xc9, 0x9d, 0xbd, 0x8c, 0xe6,
+	0x05, 0xb0, 0x08, 0x3c, 0xc2, 0xbe, 0xf2, 0xb7, 0xf3, 0xb0, 0x14, 0x1d, 0x9e, 0x81, 0x11, 0xea,
+	0xb6, 0x9d, 0x6f, 0x6c, 0xec, 0x05, 0xe1, 0x1c, 0x6b, 0xd0, 0xb3, 0x84, 0xfb, 0xca, 0xf0, 0x71,
+	0x6d, 0x3e, 0x72, 0x96, 0x18, 0xf9, 0x62, 0x3a, 0xa8, 0x71, 0x18, 0xfa, 0x35, 0xf8, 0x84, 0x6b,
+	0x96, 0x41, 0x6a, 0xb9, 0xe9, 0x5f, 0x83, 0x80, 0xae, 0x13, 0x8a, 0x6a, 0x30, 0x96, 0x31, 0x45,
+	0xcd, 0x4f, 0x47, 0x15, 0xd0, 0x75, 0x82, 0xbe, 0x80, 0x8a, 0x68, 0xb0, 0x69, 0x0b, 0x53, 0x71,
+	0x83, 0x99, 0xe8, 0xbc, 0xb7, 0xa1, 0xf2, 0xd2, 0xe8, 0x5d, 0x0c, 0x5d, 0xdd, 0xb7, 0x7e, 0xc1,
+	0x7d, 0xd0, 0xbc, 0x06, 0xbc, 0xab, 0x63, 0xfd, 0x02, 0xd3, 0x70, 0x62, 0x68, 0x73, 0x04, 0xcb,
+	0x3e, 0x67, 0x9e, 0xa6, 0xa4, 0x85, 0xbb, 0x98, 0x3f, 0xe2, 0x1a, 0xa7, 0xf7, 0x0d, 0x82, 0xed,
+	0xde, 0x15, 0x73, 0x36, 0x65, 0x6d, 0x49, 0x74, 0xb7, 0x79, 0xaf, 0xfa, 0x23, 0xb8, 0x7e, 0x2a,
+	0xf0, 0xc6, 0xf3, 0x30, 0x33, 0xa8, 0xca, 0xef, 0x83, 0x92, 0x84, 0x2f, 0x95, 0xa5, 0xe4, 0x61,
+	0xc3, 0xbc, 0xa2, 0x32, 0x98, 0x6e, 0x8d, 0x8b, 0x0c, 0xb6, 0x4e, 0x92, 0xb8, 0xcf, 0x26, 0x72,
+	0xff, 0x1f, 0x19, 0xb8, 0xb6, 0x87, 0xfb, 0x98, 0xbc, 0x0d, 0xef, 0x29, 0xda, 0x75, 0x10, 0x49,
+	0x95, 0x52, 0xf5, 0xfe, 0x40, 0x28, 0x58, 0xf2, 0x4c, 0xdb, 0x41, 0x14, 0xc2, 0x8f, 0x29, 0x12,
+	0x59, 0xf9, 0x02, 0x16, 0x23, 0x43, 0x6f, 0x74, 0x5c, 0x39, 0x81, 0x8d, 0xb1, 0xe9, 0x46, 0x42,
+	0x75, 0x87, 0xde, 0x39, 0x9e, 0x51, 0xa8, 0x0c, 0xb6, 0x4e, 0xd4, 0x06, 0xdc, 0x12, 0x46, 0x88,
+	0x13, 0x36, 0xdf, 0x66, 0xbb, 0xbf, 0x86, 0xdb, 0xa9, 0x44, 0x24, 0x7b, 0xa1, 0xe3, 0x25, 0xe7,
+	0x6f, 0x23, 0x22, 0xc0, 0x10, 0xce, 0x08, 0x52, 0xfd, 0x14, 0xee, 0x04, 0x36, 0x27, 0x0e, 0xe6,
+	0x87, 0xa2, 0x78, 0xbe, 0x61, 0x99, 0xd0, 0x86, 0xa9, 0xbf, 0x07, 0x77, 0x27, 0x60, 0x0a, 0xae,
+	0x3e, 0x49, 0x30, 0x5b, 0xa9, 0x6c, 0x85, 0x0d, 0xd7, 0xbf, 0x65, 0xa1, 0x1a, 0x07, 0x78, 0x7b,
+	0xe5, 0xfa, 0x8c, 0x9e, 0x9f, 0x19, 0xb1, 0x19, 0x7d, 0xb3, 0x80, 0xae, 0x93, 0xc8, 0xb6, 0xe7,
+	0x66, 0xde, 0x76, 0x54, 0x0f, 0xa9, 0x73, 0x9e, 0x2d, 0xfb, 0x5e, 0xca, 0xb2, 0xff, 0x6f, 0x14,
+	0xf9, 0xbf, 0x32, 0xb0, 0x3e, 0x96, 0xbc, 0xa7, 0xd9, 0x2d, 0xfa, 0x95, 0x87, 0x84, 0x38, 0xa0,
+	0x67, 0x3a, 0x4e, 0x71, 0x69, 0x24, 0x47, 0xda, 0x4b, 0x23, 0x75, 0xcb, 0x0c, 0x40, 0x44, 0x22,
+	0xcf, 0x32, 0xc5, 0x60, 0x10, 0xf0, 0xcf, 0x4f, 0x0d, 0xf8, 0x1f, 0x41, 0x11, 0xdb, 0x97, 0x97,
+	0x86, 0x17, 0xb8, 0xf9, 0xe0, 0x54, 0xdf, 0xb4, 0x2f, 0x2d, 0xcf, 0xb1, 0x07, 0xd8, 0x26, 0xcf,
+	0x0c, 0xcf, 0xa2, 0x99, 0x78, 0x2d, 0x00, 0xa5, 0x2b, 0x23, 0x86, 0x7f, 0xe1, 0x33, 0xfb, 0x5f,
+	0xd6, 0x78, 0x23, 0x9c, 0x8a, 0x29, 0x44, 0x53, 0x31, 0x7f, 0x9e, 0x85, 0x95, 0xb1, 0xf5, 0x8e,
+	0xc5, 0xd3, 0x41, 0x5e, 0x26, 0x1b, 0xca, 0xcb, 0x48, 0x8d, 0x99, 0x0f, 0x6b, 0xcc, 0x06, 0x14,
+	0x09, 0x36, 0x06, 0x54, 0xcb, 0x78, 0xb2, 0xbf, 0x40, 0x9b, 0x2d, 0x33, 0x1c, 0x00, 0xe6, 0x99,
+	0x87, 0x08, 0x9a, 0xf4, 0x3c, 0x47, 0x27, 0xf5, 0x89, 0x1e, 0x00, 0x14, 0x18, 0xc0, 0x22, 0xef,
+	0x7d, 0x26, 0xc0, 0x3e, 0x12, 0xc9, 0xc7, 0x62, 0x24, 0x05, 0x94, 0xb8, 0x57, 0x22, 0x13, 0xf9,
+	0x08, 0x8a, 0x22, 0x56, 0xac, 0x95, 0xa6, 0x6b, 0xa0, 0x00, 0x55, 0x5f, 0xc0, 0xda, 0x18, 0x51,
+	0x0d, 0x9f, 0x8d, 0xc9, 0x24, 0x16, 0xd1, 0x86, 0x16, 0x14, 0x92, 0xc1, 0x7c, 0x58, 0x06, 0xea,
+	0x6f, 0x33, 0x70, 0xab, 0xc1, 0xa6, 0x49, 0x98, 0x81, 0xdb, 0x8c, 0xa4, 0x0c, 0x58, 0xf2, 0xb7,
+	0x99, 0x36, 0x8b, 0x14, 0x54, 0x6e, 0x56, 0x41, 0xa9, 0xcf, 0xe1, 0x76, 0x2a, 0x5b, 0xc2, 0x20,
+	0x3d, 0x8a, 0xe5, 0x5b, 0x12, 0x8e, 0xb7, 0x12, 0x47, 0x42, 0xaa, 0x7f, 0x9d, 0x81, 0x5b, 0xa7,
+	0xae, 0x39, 0x69, 0xc1, 0x71, 0xb1, 0x26, 0x2f, 0xf6, 0xa3, 0x48, 0xe6, 0x79, 0x96, 0xcd, 0x1f,
+	0xd7, 0xaa, 0x5c, 0x82, 0x56, 0xd1, 0xa5, 0xa7, 0x32, 0xf8, 0xad, 0x96, 0x4e, 0xe8, 0x49, 0x8b,
+	0xcc, 0xbc, 0xec, 0x74, 0x6d, 0x42, 0x90, 0x1b, 0xfa, 0xf2, 0x33, 0x63, 0xbf, 0x53, 0xbf, 0x32,
+	0xb5, 0x0b, 0x37, 0x92, 0x67, 0xfd, 0x56, 0x6b, 0x69, 0xc3, 0xcd, 0xb6, 0xe5, 0x8f, 0x93, 0xf5,
+	0x43, 0x5a, 0xcb, 0x78, 0xcc, 0x24, 0xf3, 0x98, 0x8d, 0xf0, 0xf8, 0x35, 0xdc, 0x4a, 0xa3, 0x26,
+	0x4f, 0xc5, 0xe5, 0x60, 0xee, 0xc0, 0xf9, 0xa5, 0xb3, 0x39, 0x02, 0x55, 0xf7, 0xe1, 0x16, 0xf7,
+	0x12, 0xdf, 0x4e, 0xdb, 0xd4, 0xbb, 0x70, 0x3b, 0x95, 0x8e, 0x48, 0x90, 0xfe, 0x53, 0x86, 0x56,
+	0x89, 0x62, 0x99, 0x77, 0xba, 0x8b, 0xd8, 0xa6, 0x06, 0x99, 0xcf, 0x51, 0xd2, 0x82, 0x66, 0x7a,
+	0x26, 0x10, 0x7d, 0x02, 0x65, 0x9f, 0x18, 0x1e, 0xf1, 0x67, 0x73, 0xb1, 0x25, 0x0e, 0x5c, 0x27,
+	0xe8, 0xfb, 0x74, 0x32, 0xd3, 0x9f, 0xcd, 0xc1, 0x16, 0x28, 0x68, 0x9d, 0xa8, 0x7f, 0x94, 0x83,
+	0xe5, 0x58, 0xc6, 0x75, 0x4c, 0x28, 0xe1, 0x54, 0x56, 0xf6, 0x4d, 0x52, 0x59, 0xa1, 0x4f, 0x74,
+	0xcc, 0xb3, 0x85, 0x3e, 0x4d, 0x79, 0x20, 0xca, 0xcd, 0x70, 0x20, 0xfa, 0x9c, 0xde, 0x8c, 0xb0,
+	0x4d, 0x8b, 0x1f, 0x78, 0xf3, 0xc9, 0x65, 0xc8, 0x86, 0x84, 0xd0, 0x42, 0xd0, 0x61, 0xa1, 0x17,
+	0xa2, 0x42, 0x7f, 0x00, 0x39, 0x0f, 0xbb, 0x8e, 0x70, 0x26, 0xd7, 0xb7, 0xc5, 0x4d, 0x0b, 0x71,
+	0x0b, 0x61, 0xfb, 0xc0, 0x22, 0xe2, 0x50, 0xc8, 0xc0, 0x68, 0x05, 0x44, 0x24, 0xc6, 0xd2, 0xea,
+	0xfc, 0x1a, 0x1f, 0x6e, 0xd9, 0x67, 0x8e, 0x16, 0xc0, 0xa2, 0x1d, 0xc8, 0x19, 0x43, 0xf2, 0x8a,
+	0x9d, 0x65, 0x2a, 0x3b, 0xb7, 0xe2, 0x38, 0xf5, 0x21, 0x79, 0x85, 0x6d, 0x62, 0xf5, 0xf8, 0x69,
+	0x9d, 0xc1, 0xa2, 0x5b, 0x00, 0xa1, 0x8c, 0x12, 0xb0, 0x8c, 0x52, 0xa8, 0x07, 0x35, 0x60, 0xd9,
+	0xb2, 0x2d, 0xa2, 0x53, 0x8e, 0x0d, 0xcb, 0xc6, 0x9e, 0x5f, 0xab, 0x44, 0xc2, 0x83, 0x96, 0x6d,
+	0x91, 0x46, 0x30, 0x28, 0x56, 0xb1, 0x64, 0x85, 0x3b, 0x7d, 0xf5, 0xd7, 0x19, 0x58, 0x4d, 0x80,
+	0x4b, 0xf4, 0x3e, 0x0f, 0x21, 0xef, 0x13, 0x6a, 0x2f, 0xf8, 0x69, 0xfe, 0x7a, 0xda, 0x34, 0x58,
+	0xe3, 0x70, 0x34, 0xfe, 0xc1, 0xaf, 0x19, 0x87, 0x26, 0x8f, 0x73, 0xf2, 0x5a, 0x89, 0x76, 0x34,
+	0x1c, 0x13, 0x87, 0xb7, 0x24, 0x17, 0xcd, 0x88, 0xff, 0xf7, 0x3c, 0x2c, 0x46, 0xb4, 0xe5, 0x3b,
+	0x8a, 0xb8, 0x14, 0x28, 0xd1, 0xf2, 0x70, 0x1f, 0xfb, 0x3c, 0x55, 0x5d, 0xd2, 0x64, 0x3b, 0x28,
+	0x12, 0xe6, 0x46, 0xf7, 0x2c, 0x1e, 0xc1, 0x22, 0x2f, 0x76, 0x9a, 0x3a, 0xcd, 0x12, 0xf9, 0x22,
+	0x08, 0x1d, 0xab, 0x75, 0x2e, 0x08, 0x28, 0xda, 0xe1, 0xbf, 0xc1, 0x55, 0x92, 0x50, 0x24, 0x56,
+	0x8c, 0x44, 0x62, 0xe8, 0x00, 0x2a, 0x34, 0x71, 0xe8, 0x59, 0x34, 0xb0, 0xa3, 0xf9, 0xd8, 0x70,
+	0xf0, 0x1b, 0x11, 0xcc, 0x76, 0x73, 0x04, 0xc7, 0x83, 0xdf, 0x30, 0x26, 0xaa, 0xc3, 0x92, 0x2f,
+	0x72, 0x8d, 0xa6, 0xee, 0x13, 0xc7, 0xad, 0x95, 0xa7, 0x9a, 0x87, 0x45, 0x89, 0x41, 0xd3, 0x93,
+	0xe8, 0x3d, 0x28, 0x52, 0x81, 0xba, 0x16, 0xd7, 0xc0, 0xca, 0xce, 0x62, 0xb0, 0xeb, 0x7b, 0xcd,
+	0x13, 0x7a, 0x3d, 0xc4, 0x32, 0xf1, 0x89, 0x65, 0x2b, 0x3f, 0x82, 0x6a, 0x9c, 0x97, 0x37, 0x8a,
+	0xb6, 0xff, 0x10, 0x0a, 0x9c, 0x22, 0x3d, 0xa2, 0xb8, 0x96, 0x6d, 0xd3, 0x84, 0x5a, 0x68, 0xa3,
+	0x2b, 0xbc, 0x8f, 0x6f, 0xe4, 0x3b, 0xb0, 0x68, 0xe2, 0x33, 0x63, 0xd8, 0x27, 0x91, 0x9d, 0x5e,
+	0x10, 0x9d, 0x1c, 0xe8, 0x01, 0x20, 0x7f, 0xe8, 0xd2, 0xcf, 0xd8, 0x77, 0x3c, 0xe9, 0xfa, 0xb9,
+	0xef, 0x5c, 0x19, 0x8d, 0x04, 0xee, 0xff, 0x5d, 0x40, 0x1a, 0xf6, 0x31, 0x11, 0xeb, 0x4a, 0x29,
+	0x6e, 0xed, 0xc0, 0x6a, 0x04, 0x4a, 0xb8, 0xa9, 0x88, 0xda, 0x65, 0xa2, 0x6a, 0x47, 0xaf, 0xe4,
+	0xac, 0xee, 0x0e, 0xfb, 0x17, 0x32, 0xdd, 0xb5, 0x6f, 0xf5, 0x09, 0xf6, 0x68, 0x4a, 0x99, 0x39,
+	0x99, 0x20, 0xbb, 0x28, 0x5a, 0x54, 0x4d, 0x45, 0x92, 0x36, 0xc8, 0x24, 0xca, 0x36, 0xda, 0x82,
+	0x3c, 0x55, 0x1e, 0x9e, 0x34, 0x4c, 0xd3, 0x2f, 0x0e, 0xc2, 0x76, 0x9f, 0x7a, 0x08, 0x6c, 0xea,
+	0x2f, 0xf1, 0x99, 0xe3, 0xe1, 0x19, 0x9c, 0xc3, 0xa2, 0xc0, 0xd8, 0x65, 0x08, 0xea, 0xbf, 0x67,
+	0x69, 0xf1, 0xd1, 0xf0, 0x48, 0x84, 0xff, 0x40, 0x38, 0x3b, 0x50, 0x38, 0x63, 0x4b, 0x91, 0xa7,
+	0x79, 0xce, 0x4d, 0xc2, 0x62, 0x35, 0x01, 0x49, 0xeb, 0x13, 0x4c, 0x11, 0xa3, 0xde, 0x84, 0x62,
+	0x50, 0x75, 0x93, 0x58, 0x8f, 0xe7, 0x34, 0x06, 0x87, 0xea, 0x50, 0xf1, 0x31, 0xd1, 0xc3, 0xe5,
+	0xe3, 0x91, 0xfd, 0x64, 0x68, 0xf2, 0x6e, 0x43, 0x18, 0x19, 0x7c, 0xd9, 0x8d, 0x3e, 0x81, 0xe2,
+	0xc0, 0x3a, 0xf7, 0x0c, 0x12, 0x08, 0x60, 0x33, 0x84, 0x7e, 0xc8, 0x47, 0xc2, 0xb8, 0x01, 0xb4,
+	0x28, 0x7e, 0xf5, 0x86, 0x9e, 0xc7, 0x32, 0x39, 0x79, 0x59, 0xfc, 0x0a, 0xba, 0x68, 0x64, 0x63,
+	0x7a, 0x57, 0xba, 0x37, 0xe4, 0x27, 0x95, 0x92, 0x56, 0x30, 0xbd, 0x2b, 0x6d, 0x68, 0xef, 0x56,
+	0xa0, 0x2c, 0x93, 0xb3, 0xea, 0x01, 0xac, 0x8c, 0x2d, 0x30, 0x54, 0xa9, 0xcd, 0xcc, 0x5c, 0xa9,
+	0xfd, 0x18, 0x36, 0x52, 0x96, 0x1c, 0xa9, 0x5d, 0x64, 0xa2, 0xb5, 0x0b, 0xf5, 0x4b, 0x58, 0x4b,
+	0x5a, 0x2a, 0x0d, 0x8c, 0x89, 0xe1, 0x9d, 0x63, 0xa2, 0xf7, 0xfa, 0x43, 0x9f, 0xc8, 0xa8, 0x6d,
+	0x91, 0xf7, 0x36, 0x78, 0xa7, 0xfa, 0x54, 0x5c, 0x11, 0x88, 0xe9, 0x40, 0x72, 0xa1, 0x80, 0x7e,
+	0x9b, 0xe1, 0x0c, 0x43, 0xa0, 0xc2, 0x0b, 0xa1, 0x14, 0x83, 0xaf, 0xfe, 0x55, 0x36, 0xf6, 0x49,
+	0xa4, 0xc4, 0x1f, 0x29, 0x1e, 0x67, 0x0c, 0x55, 0x7a, 0x1c, 0x7a, 0xe8, 0x75, 0x88, 0xd1, 0x17,
+	0xa5, 0x48, 0xde, 0xa0, 0xb5, 0x2b, 0x7f, 0xd8, 0xeb, 0x61, 0x6c, 0x62, 0x1e, 0x26, 0x2f, 0x6a,
+	0xa3, 0x0e, 0xfa, 0x1d, 0x9e, 0x19, 0x56, 0x5f, 0x64, 0xbf, 0x17, 0x35, 0xd1, 0x8a, 0x95, 0x23,
+	0x0a, 0x6f, 0x50, 0x8e, 0x40, 0x5f, 0xc2, 0x42, 0xcf, 0x19, 0xb8, 0x32, 0x5f, 0x32, 0xbd, 0x1a,
+	0x57, 0x91, 0xf0, 0x75, 0xa2, 0xfe, 0x7d, 0xdc, 0x62, 0x68, 0xd8, 0x1f, 0xf6, 0xc9, 0x77, 0x14,
+	0x9e, 0xad, 0x41, 0x1e, 0xd3, 0x0b, 0x7e, 0xc1, 0x71, 0x9d, 0x35, 0xc6, 0x58, 0xce, 0xbd, 0x19,
+	0xcb, 0xdb, 0x70, 0x23, 0xc8, 0x65, 0x25, 0xda, 0x8a, 0xb8, 0x21, 0xfd, 0xd3, 0x0c, 0xdc, 0x4c,
+	0x41, 0x10, 0x8a, 0xb5, 0x13, 0xbb, 0xe5, 0xa2, 0xa4, 0x6d, 0x7e, 0xe8, 0x8a, 0xdc, 0x23, 0x28,
+	0x7a, 0x4c, 0x54, 0x41, 0x51, 0x3c, 0x11, 0x89, 0x4b, 0x53, 0x0b, 0x40, 0xd5, 0x0f, 0xe0, 0xfa,
+	0x73, 0x83, 0xf4, 0x5e, 0xcd, 0xc4, 0xf8, 0x2f, 0x63, 0x5b, 0xc3, 0xcf, 0x8c, 0x6f, 0xc5, 0xed,
+	0x0e, 0x14, 0x38, 0x0b, 0xb5, 0x6c, 0x3a, 0x8e, 0x60, 0x56, 0x40, 0xaa, 0x1f, 0x82, 0xd2, 0x60,
+	0x95, 0xc7, 0x99, 0x98, 0x7d, 0x0a, 0x9b, 0x89, 0xd0, 0x6f, 0x2f, 0x62, 0xf5, 0x37, 0xf3, 0x50,
+	0xa9, 0x0f, 0x4d, 0x8b, 0xcc, 0x50, 0x47, 0x0f, 0x8a, 0xd1, 0xd9, 0x19, 0x8b, 0xd1, 0xd7, 0xa0,
+	0x60, 0xf4, 0x42, 0xe5, 0x57, 0xd1, 0x12, 0xcb, 0xca, 0x25, 0xea, 0x7d, 0xfe, 0x4d, 0xf4, 0xde,
+	0xe8, 0x11, 0xc7, 0x13, 0x27, 0x00, 0xde, 0xa0, 0x73, 0xfa, 0xce, 0xd0, 0xeb, 0x61, 0x11, 0x85,
+	0x89, 0x16, 0x0d, 0x80, 0x5d, 0x8c, 0x3d, 0x16, 0xe5, 0x97, 0x35, 0xf6, 0x1b, 0xed, 0x02, 0xb8,
+	0x86, 0x67, 0x0c, 0x30, 0xa1, 0x5e, 0xbb, 0xcc, 0x34, 0x4c, 0x0d, 0x4a, 0x48, 0x23, 0x99, 0x6c,
+	0x9f, 0x48, 0x20, 0x1e, 0x94, 0x85, 0xb0, 0x46, 0x5f, 0x1f, 0x84, 0xbe, 0x3e, 0xe5, 0x4b, 0x58,
+	0x8e, 0x21, 0xbd, 0x51, 0xf4, 0xf4, 0x17, 0x59, 0x58, 0x7b, 0x3a, 0xc4, 0xde, 0x15, 0xe3, 0xa2,
+	0xed, 0x9c, 0xa7, 0x9d, 0x72, 0x37, 0x68, 0xd0, 0x4d, 0x8c, 0xd0, 0x51, 0x9c, 0x36, 0xc3, 0x59,
+	0xdf, 0x48, 0x0e, 0xaf, 0x06, 0x45, 0xa3, 0x37, 0x2a, 0x30, 0x96, 0xb5, 0xa0, 0x89, 0x3e, 0x82,
+	0xbc, 0x6f, 0xd1, 0x3d, 0x9f, 0x5e, 0x5d, 0xe2, 0x80, 0x14, 0x83, 0x1e, 0x86, 0xfa, 0x33, 0x58,
+	0x52, 0x0e, 0x98, 0x70, 0xbd, 0xa3, 0x98, 0x70, 0xbd, 0x83, 0xb2, 0xde, 0xb7, 0x06, 0x16, 0x61,
+	0x5b, 0xb5, 0xa8, 0xf1, 0x86, 0xfa, 0x02, 0xd6, 0x63, 0x12, 0x11, 0x4a, 0xff, 0x21, 0xb5, 0x11,
+	0x74, 0x9b, 0x82, 0x84, 0x02, 0x1a, 0xdf, 0x41, 0x2d, 0x00, 0xa1, 0x6a, 0x30, 0xa0, 0xa1, 0x13,
+	0xbf, 0xfe, 0xc8, 0x7e, 0xab, 0x75, 0xa8, 0x8d, 0x2e, 0xa5, 0xc4, 0x04, 0x3e, 0xe3, 0x95, 0x94,
+	0xbf, 0xcd, 0x40, 0x29, 0x38, 0x41, 0x30, 0x55, 0x73, 0x3c, 0x5e, 0x13, 0x59, 0xd4, 0xd8, 0x6f,
+	0xaa, 0x96, 0xdc, 0x0b, 0x8b, 0x22, 0xb9, 0x68, 0xd1, 0x2a, 0xe6, 0xa5, 0xe5, 0x5b, 0x2f, 0xad,
+	0x3e, 0xbd, 0x02, 0x1c, 0xad, 0x23, 0x52, 0x82, 0xcf, 0xe4, 0xa0, 0x16, 0x02, 0x4c, 0x38, 0xde,
+	0xbc, 0x0f, 0xcb, 0x2e, 0xf6, 0xd8, 0x9d, 0xc3, 0x4b, 0xac, 0xf7, 0x1c, 0xcf, 0x17, 0xc5, 0xdf,
+	0xa5, 0x51, 0x77, 0xc3, 0xf1, 0x7c, 0xf5, 0x77, 0x39, 0x58, 0x4d, 0x38, 0x5e, 0x87, 0xdc, 0x26,
+	0x57, 0x2f, 0xd1, 0x0a, 0x9f, 0x6b, 0xb2, 0xd1, 0x73, 0xcd, 0x1e, 0x2c, 0xb9, 0xc3, 0x7e, 0xdf,
+	0xb2, 0xcf, 0x79, 0xa4, 0xec, 0x0b, 0xfe, 0x6f, 0xa6, 0x1e, 0xe2, 0x77, 0x1d, 0xa7, 0xaf, 0x2d,
+	0x0a, 0x24, 0x16, 0x4d, 0xfb, 0x94, 0x4a, 0xf0, 0x70, 0x00, 0xbf, 0xb6, 0x7c, 0xe2, 0xd7, 0x72,
+	0x33, 0x51, 0x11, 0x48, 0x4d, 0x86, 0x13, 0xb9, 0x1a, 0xc0, 0x13, 0xe4, 0xb2, 0x8d, 0x9e, 0xc2,
+	0xfa, 0x99, 0x65, 0x1b, 0x7d, 0x5d, 0x14, 0x33, 0x03, 0x3f, 0x57, 0x2b, 0xcc, 0x32, 0xd1, 0x2a,
+	0xc3, 0xdd, 0x65, 0xa8, 0x0d, 0x81, 0x89, 0x3e, 0xa3, 0xb7, 0x3b, 0xdc, 0xbe, 0x73, 0x25, 0x5e,
+	0x0e, 0x4c, 0xa5, 0x22, 0xc1, 0x51, 0x0b, 0x56, 0x6c, 0x4c, 0x68, 0xf8, 0xa4, 0xdb, 0x0e, 0xd1,
+	0x59, 0xa9, 0xb1, 0x56, 0x9a, 0x85, 0xc6, 0xb2, 0xc0, 0x3b, 0x72, 0x88, 0x46, 0xb1, 0xd0, 0x8f,
+	0x61, 0xf5, 0xcc, 0xf2, 0x7c, 0xa2, 0xb3, 0xab, 0xf8, 0x46, 0x70, 0x91, 0x7c, 0xfa, 0xa1, 0x70,
+	0x85, 0xa1, 0x9d, 0xfa, 0xd8, 0x93, 0x37, 0xc5, 0x3e, 0x96, 0x2f, 0x21, 0x60, 0x16, 0x5e, 0x04,
+	0x30, 0xbd, 0x87, 0x29, 0xc2, 0x6b, 0x9d, 0x38, 0xb5, 0x0a, 0x93, 0x7c, 0x59, 0xf4, 0x74, 0x1d,
+	0xf5, 0x1f, 0x32, 0xb0, 0x32, 0x66, 0xc3, 0x93, 0xcb, 0x67, 0xe9, 0xb6, 0x8c, 0x55, 0xce, 0xf9,
+	0xc1, 0x67, 0xb6, 0x5a, 0x95, 0x80, 0xae, 0x13, 0x74, 0x1d, 0x4a, 0xe7, 0xf4, 0xae, 0xe2, 0x28,
+	0x9f, 0x5a, 0x64, 0x6d, 0x5e, 0xb6, 0x08, 0xae, 0xd2, 0xe4, 0xa3, 0x57, 0x69, 0xfe, 0x00, 0xd6,
+	0x92, 0xb2, 0x3f, 0xd1, 0x8b, 0x5a, 0x99, 0xd8, 0x45, 0xad, 0xeb, 0x50, 0x72, 0x1d, 0x53, 0x0f,
+	0x15, 0x53, 0x8a, 0xae, 0x63, 0xb2, 0xa1, 0x0d, 0x28, 0x32, 0x3c, 0xcb, 0x0d, 0xfc, 0x20, 0x6d,
+	0xb6, 0x5c, 0xb4, 0x4e, 0xcf, 0x0c, 0x26, 0xed, 0xe7, 0xbc, 0xe5, 0x5d, 0xc7, 0x6c, 0xb9, 0xaa,
+	0x03, 0x1b, 0x29, 0x99, 0x24, 0xf4, 0x7d, 0x28, 0x1b, 0xc1, 0xc5, 0xe2, 0x5a, 0x26, 0x62, 0x2d,
+	0x62, 0x37, 0x98, 0x47, 0x70, 0xb4, 0x8c, 0xcf, 0x24, 0xac, 0x13, 0xe7, 0x02, 0x07, 0x37, 0xd0,
+	0x81, 0x75, 0x75, 0x69, 0x8f, 0xfa, 0x37, 0x05, 0x40, 0xe3, 0x8f, 0x69, 0xbe, 0xa3, 0x2c, 0xcd,
+	0x57, 0xb0, 0x78, 0x86, 0x0d, 0x32, 0xf4, 0xb0, 0x7e, 0xd6, 0x37, 0xce, 0x83, 0xa3, 0xee, 0x58,
+	0x9e, 0x6d, 0x9f, 0x03, 0xed, 0xf7, 0x8d, 0x73, 0x6d, 0xe1, 0x6c, 0xd4, 0xa0, 0xd7, 0x6c, 0x2a,
+	0xa1, 0xb7, 0x51, 0x22, 0x92, 0x7d, 0x37, 0x9e, 0xd9, 0x93, 0x84, 0x5a, 0x23, 0x58, 0x2d, 0x8c,
+	0x88, 0xee, 0x41, 0x7e, 0x62, 0xe6, 0x87, 0x8f, 0x86, 0xcb, 0x73, 0x85, 0xd9, 0xcb, 0x73, 0x1f,
+	0xc0, 0x4a, 0xef, 0x15, 0xee, 0x5d, 0x38, 0x43, 0xa2, 0xf7, 0x1d, 0xbe, 0x5d, 0x22, 0x04, 0xa9,
+	0x06, 0x03, 0x6d, 0xd1, 0x4f, 0x73, 0x19, 0x23, 0xc9, 0x4a, 0x68, 0x1e, 0x9a, 0xac, 0x7c, 0x33,
+	0x7a, 0x82, 0x21, 0xc0, 0xef, 0xc0, 0xfc, 0xb9, 0x45, 0xc4, 0x77, 0xbd, 0x24, 0xb8, 0x39, 0xb0,
+	0x38, 0xd7, 0x74, 0x28, 0x6c, 0xa4, 0x21, 0x6a, 0xa4, 0x23, 0x1a, 0x53, 0x99, 0x51, 0x63, 0xda,
+	0xd1, 0x8c, 0xd5, 0x02, 0x13, 0xc3, 0x56, 0xea, 0xb3, 0xab, 0x29, 0x69, 0xab, 0xf7, 0xc7, 0x13,
+	0x9b, 0x8b, 0x2c, 0xfa, 0x88, 0x25, 0x2f, 0xd1, 0x7d, 0xa8, 0x06, 0xa9, 0x22, 0xa9, 0x4e, 0x4b,
+	0x5c, 0xe3, 0x78, 0x7f, 0x2b, 0x50, 0xaa, 0xef, 0x41, 0xc9, 0xf7, 0x5f, 0xe9, 0x17, 0xf8, 0xca,
+	0xaf, 0x2d, 0xa7, 0x5c, 0x3a, 0xee, 0x3c, 0x7e, 0x82, 0xaf, 0xb4, 0xa2, 0xef, 0xbf, 0x7a, 0x82,
+	0xaf, 0xfc, 0x6f, 0x9d, 0xd1, 0xfa, 0x11, 0x2c, 0xc7, 0x68, 0x27, 0x26, 0x55, 0x37, 0xa0, 0x78,
+	0x81, 0xaf, 0x74, 0x87, 0xf8, 0x81, 0x15, 0xbb, 0xc0, 0x57, 0xc7, 0xc4, 0x57, 0xbf, 0x80, 0xa2,
+	0xd8, 0x32, 0xea, 0xac, 0xa8, 0xc5, 0x0e, 0xdb, 0x91, 0xa0, 0x4d, 0x19, 0xc0, 0x03, 0xc3, 0xea,
+	0x07, 0x0c, 0xb0, 0x86, 0xfa, 0xff, 0x60, 0x35, 0x41, 0xfb, 0xd2, 0x6a, 0x8a, 0xe3, 0x2b, 0x50,
+	0x87, 0xb0, 0x9a, 0xf0, 0xb4, 0xe7, 0x3b, 0x3a, 0x81, 0xa6, 0xa6, 0x7e, 0xb7, 0x7e, 0x97, 0x11,
+	0x09, 0xa7, 0xa4, 0xb7, 0x68, 0x68, 0x03, 0x56, 0x3b, 0xdd, 0xba, 0xd6, 0xd5, 0x9b, 0x9a, 0x76,
+	0xac, 0xe9, 0xa7, 0x47, 0x4f, 0x8e, 0x8e, 0x9f, 0x1f, 0x55, 0xe7, 0xd0, 0x6d, 0xd8, 0x0c, 0x0f,
+	0xb4, 0x8e, 0x9e, 0xd5, 0xdb, 0xad, 0x3d, 0x5d, 0x6b, 0x3e, 0x3d, 0x6d, 0x76, 0xba, 0xd5, 0x0c,
+	0xaa, 0xc1, 0x5a, 0x18, 0xa0, 0x71, 0x7c, 0xb4, 0xdf, 0x6e, 0x35, 0xba, 0xd5, 0x2c, 0x5a, 0x87,
+	0x95, 0xf0, 0xc8, 0xd3, 0xd3, 0xe3, 0x6e, 0xbd, 0x3a, 0x3f, 0x86, 0x50, 0x3f, 0xa9, 0x37, 0x5a,
+	0xdd, 0x17, 0xd5, 0x5c, 0x1c, 0xa1, 0x75, 0x58, 0x3f, 0x68, 0x56, 0xf3, 0x71, 0x84, 0xba, 0xd6,
+	0x78, 0xdc, 0x7a, 0xd6, 0xdc, 0xab, 0x16, 0xe2, 0x5c, 0xef, 0x35, 0xdb, 0xcd, 0x6e, 0x73, 0xaf,
+	0x5a, 0xdc, 0x7a, 0x04, 0xab, 0x09, 0xd9, 0x1e, 0xb4, 0x00, 0xa5, 0xa3, 0x63, 0xed, 0xb0, 0xde,
+	0x6e, 0xbf, 0xa8, 0xce, 0xa1, 0x65, 0xa8, 0xb4, 0x0e, 0x0f, 0x9b, 0x7b, 0xad, 0x7a, 0xb7, 0xd9,
+	0x7e, 0x51, 0xcd, 0x6c, 0x7d, 0x09, 0xcb, 0xb1, 0x8b, 0xc8, 0x68, 0x0d, 0xaa, 0xad, 0xa3, 0x4e,
+	0xb7, 0x7e, 0xd4, 0x68, 0xea, 0x6c, 0xaa, 0xe6, 0x5e, 0x75, 0x2e, 0xd6, 0x7b, 0x7c, 0x72, 0xd2,
+	0xdc, 0xab, 0x66, 0xb6, 0xda, 0xb0, 0x96, 0x74, 0xfd, 0x0e, 0x29, 0x70, 0xad, 0x71, 0x7c, 0xd4,
+	0x6d, 0x1e, 0x75, 0xf5, 0xd6, 0x51, 0xab, 0xdb, 0xaa, 0xb7, 0x5b, 0x3f, 0xa9, 0x77, 0x5b, 0xc7,
+	0x54, 0xbc, 0x35, 0x58, 0x0b, 0xc6, 0xf6, 0x5b, 0x47, 0xa3, 0x91, 0xcc, 0xd6, 0xe7, 0xb0, 0x14,
+	0xb5, 0x0a, 0x74, 0xd6, 0xfa, 0xde, 0x61, 0xab, 0xab, 0x1f, 0x3f, 0x3f, 0x6a, 0x6a, 0xfa, 0xf1,
+	0x11, 0x5b, 0x05, 0x82, 0x25, 0xde, 0xdb, 0x7c, 0xd6, 0xd4, 0x5e, 0x1c, 0x1f, 0x35, 0xab, 0x99,
+	0xad, 0x5f, 0x65, 0x00, 0x8d, 0xd7, 0x0e, 0x28, 0x23, 0x94, 0x01, 0xba, 0x47, 0xdd, 0x7a, 0x8b,
+	0xd2, 0x78, 0x5e, 0x6f, 0x75, 0x5b, 0x47, 0x07, 0xd5, 0xb9, 0x84, 0x31, 0xed, 0xf4, 0xe8, 0x88,
+	0x8e, 0x65, 0xd0, 0x0d, 0xa8, 0xc5, 0xc6, 0x3a, 0xa7, 0x8d, 0x46, 0xb3, 0xb9, 0xd7, 0xdc, 0xab,
+	0x66, 0xd1, 0x75, 0x58, 0x8f, 0x8d, 0xee, 0xd7, 0x5b, 0xed, 0xe6, 0x5e, 0x75, 0x7e, 0xab, 0x0f,
+	0x68, 0x3c, 0xa1, 0x44, 0xa7, 0xda, 0x3d, 0x6d, 0x3f, 0xd1, 0x8f, 0x4f, 0x9a, 0x1a, 0x5b, 0xad,
+	0x9c, 0x6a, 0x8e, 0x4e, 0x15, 0x1b, 0x6b, 0x1c, 0x1f, 0x9e, 0xf0, 0x6d, 0xcd, 0x24, 0x8d, 0xd2,
+	0x3d, 0x68, 0xd3, 0xd9, 0xb2, 0x5b, 0x2d, 0x58, 0x8a, 0xc6, 0xe9, 0x68, 0x13, 0x36, 0x4e, 0x8e,
+	0xb5, 0xae, 0xfe, 0xac, 0xd5, 0x69, 0xed, 0xb6, 0xda, 0xad, 0xee, 0x0b, 0xfd, 0x44, 0x6b, 0x3d,
+	0xab, 0x77, 0x9b, 0x7c, 0xc5, 0x63, 0x83, 0xa7, 0xbb, 0xed, 0x56, 0xa3, 0x9a, 0xd9, 0xfa, 0x14,
+	0xae, 0x25, 0x47, 0x5b, 0xa8, 0x0c, 0xf9, 0xfd, 0x7a, 0xbb, 0x43, 0x09, 0x94, 0x20, 0xd7, 0xd5,
+	0x4e, 0x9b, 0xd5, 0x0c, 0xed, 0x6c, 0x1e, 0x9e, 0x74, 0x5f, 0x54, 0xb3, 0x5b, 0xbf, 0xce, 0xc0,
+	0x52, 0xb4, 0xc8, 0x86, 0x2a, 0x50, 0x1c, 0x7d, 0x4f, 0x15, 0x28, 0x9e, 0x34, 0x8f, 0xf6, 0xb8,
+	0x60, 0x17, 0xa0, 0xd4, 0xd0, 0x9a, 0x75, 0xb6, 0x05, 0x59, 0x54, 0x85, 0x05, 0xa9, 0x1f, 0xb4,
+	0x67, 0x9e, 0x02, 0x07, 0xa2, 0xc9, 0x31, 0x75, 0x3d, 0xea, 0x36, 0x35, 0xed, 0xf4, 0x84, 0x29,
+	0x39, 0xc5, 0x66, 0xca, 0x47, 0x87, 0xf3, 0x14, 0x36, 0x50, 0xc5, 0x02, 0x1d, 0x92, 0x9f, 0x49,
+	0x69, 0xeb, 0x12, 0xd6, 0x92, 0x7c, 0x3b, 0x5d, 0xc0, 0xd1, 0xf1, 0xf1, 0x49, 0x75, 0x8e, 0xee,
+	0xe1, 0xfe, 0x69, 0xbb, 0xad, 0x3f, 0x3f, 0xd6, 0x9e, 0x74, 0x4e, 0xea, 0x8d, 0xa6, 0xbe, 0x5b,
+	0x6f, 0x3c, 0x39, 0x3d, 0xa9, 0xe6, 0xd0, 0x2a, 0x2c, 0xef, 0xb7, 0xbe, 0x6e, 0xd2, 0x4f, 0xbe,
+	0x73, 0x7c, 0xaa, 0x35, 0x9a, 0x9d, 0x6a, 0x9e, 0x2a, 0xdd, 0x69, 0xa7, 0xa9, 0xe9, 0x47, 0xf5,
+	0xc3, 0x26, 0x83, 0xaf, 0x16, 0xd4, 0x5c, 0x29, 0x53, 0xcd, 0xa8, 0xb9, 0x52, 0xb6, 0x9a, 0x55,
+	0x73, 0xa5, 0xf9, 0xea, 0xfc, 0xd6, 0x57, 0xb0, 0x18, 0x49, 0x9f, 0xb3, 0xf5, 0x34, 0x0f, 0x4e,
+	0xdb, 0x75, 0xad, 0x3a, 0x47, 0x79, 0x3c, 0xd1, 0x9a, 0xbb, 0xa7, 0xad, 0xf6, 0x1e, 0x17, 0xe1,
+	0x89, 0x76, 0xbc, 0xdb, 0xac, 0x66, 0xe9, 0xcf, 0x83, 0xc7, 0xc7, 0x9d, 0x6e, 0x75, 0x7e, 0xe7,
+	0x7f, 0x14, 0xa8, 0x8e, 0xcc, 0x9d, 0x61, 0x1b, 0xe7, 0xd8, 0x43, 0x6d, 0x58, 0x8c, 0x3c, 0xa9,
+	0x46, 0x41, 0x00, 0x93, 0xf4, 0x00, 0x5b, 0xb9, 0x91, 0x3c, 0x28, 0x4a, 0xcc, 0x73, 0xe8, 0x18,
+	0x96, 0xa2, 0x66, 0x11, 0x4d, 0x7c, 0xf9, 0xa4, 0xdc, 0x4c, 0x19, 0x95, 0x04, 0xdb, 0xb0, 0x18,
+	0xb1, 0x3d, 0x92, 0xbd, 0xa4, 0x17, 0xb8, 0xca, 0x8d, 0xe4, 0x41, 0x49, 0xed, 0x6b, 0x58, 0x19,
+	0x7b, 0xf3, 0x83, 0x6e, 0xcb, 0xeb, 0x5a, 0xc9, 0xaf, 0x65, 0x95, 0x3b, 0xe9, 0x00, 0x92, 0xf2,
+	0x2e, 0x94, 0xe5, 0x59, 0x1b, 0x6d, 0x8c, 0xbf, 0x01, 0xe5, 0x94, 0x6a, 0x69, 0x8f, 0x43, 0xd5,
+	0xb9, 0x8f, 0x32, 0xa8, 0x01, 0x30, 0x7a, 0x89, 0x89, 0x46, 0xaf, 0xe5, 0x62, 0x2f, 0x3b, 0x95,
+	0xeb, 0x09, 0x23, 0x92, 0x91, 0x06, 0xc0, 0x28, 0xef, 0x2e, 0x89, 0x8c, 0xbd, 0xe5, 0x54, 0xae,
+	0x27, 0x8c, 0x48, 0x22, 0xfb, 0x50, 0x09, 0x3d, 0x70, 0x44, 0x01, 0xec, 0xf8, 0x43, 0x4c, 0x45,
+	0x49, 0x1a, 0x92, 0x74, 0x5a, 0xb0, 0x10, 0x7e, 0xea, 0x88, 0x02, 0xe8, 0x84, 0x67, 0x92, 0xca,
+	0x66, 0xe2, 0x98, 0x24, 0x75, 0x0a, 0xd5, 0xf8, 0x0b, 0x44, 0x74, 0x2b, 0x3a, 0x79, 0xfc, 0x8d,
+	0xa4, 0x72, 0x3b, 0x75, 0x3c, 0xa2, 0xb0, 0x91, 0x17, 0x87, 0x23, 0x85, 0x4d, 0x7a, 0xd3, 0xa8,
+	0xdc, 0x4c, 0x19, 0x95, 0x04, 0x7f, 0x0a, 0xab, 0x51, 0x65, 0x66, 0x6f, 0xca, 0xd0, 0xdd, 0x49,
+	0x4f, 0xf1, 0x38, 0x69, 0x75, 0xfa, 0x6b, 0x3d, 0x75, 0x0e, 0xfd, 0x7f, 0x40, 0x11, 0xed, 0xe6,
+	0xe4, 0xef, 0x24, 0x29, 0x7e, 0x84, 0xfa, 0xdd, 0x09, 0x10, 0x92, 0xf8, 0x39, 0xbd, 0xed, 0x9b,
+	0xf4, 0x26, 0x0e, 0xbd, 0x9b, 0xf6, 0x0d, 0x44, 0x26, 0xb9, 0x37, 0x05, 0x2a, 0x2c, 0xa5, 0x84,
+	0x07, 0x6f, 0x52, 0x4a, 0xe9, 0xcf, 0xe8, 0x14, 0x75, 0x12, 0x48, 0x98, 0x7e, 0xc2, 0x8b, 0x2e,
+	0x49, 0x3f, 0xfd, 0x21, 0x9b, 0xa2, 0x4e, 0x02, 0x09, 0x69, 0xe3, 0x6a, 0xc2, 0x7b, 0xaf, 0xd1,
+	0x2e, 0xa7, 0xbe, 0x05, 0x53, 0x36, 0xc6, 0x1e, 0x69, 0xf1, 0x54, 0x1e, 0xb3, 0x00, 0x6d, 0x58,
+	0x8c, 0x3c, 0x35, 0x92, 0xd6, 0x2e, 0xe9, 0xd1, 0x92, 0x72, 0x23, 0x79, 0x30, 0xfc, 0xf5, 0x85,
+	0x9f, 0xf0, 0xc8, 0xaf, 0x2f, 0xe1, 0x3d, 0x92, 0xb2, 0x99, 0x38, 0x16, 0x96, 0x67, 0xc2, 0x2b,
+	0x1c, 0xb9, 0xde, 0xf4, 0x97, 0x3d, 0x8a, 0x3a, 0x09, 0x24, 0x6c, 0x70, 0x42, 0xef, 0x73, 0xa4,
+	0xc1, 0x19, 0x7f, 0xca, 0xa3, 0x28, 0x49, 0x43, 0x61, 0x3a, 0xad, 0xc1, 0x38, 0x9d, 0xd6, 0x20,
+	0x95, 0x4e, 0xc2, 0xdb, 0x1a, 0x6e, 0x6d, 0xe2, 0x0f, 0x34, 0xa4, 0xb5, 0x49, 0x79, 0xf7, 0xa1,
+	0xdc, 0x4e, 0x1d, 0x0f, 0x7f, 0xbc, 0xe3, 0x97, 0xf9, 0xe5, 0xc7, 0x9b, 0xfa, 0x4e, 0x40, 0xb9,
+	0x3b, 0x01, 0x42, 0x12, 0xd7, 0x60, 0x39, 0x76, 0x07, 0x0c, 0xdd, 0x9c, 0x78, 0xb1, 0x5e, 0xb9,
+	0x95, 0x36, 0x2c, 0x69, 0xfe, 0x0c, 0x36, 0x52, 0xae, 0xa3, 0xa3, 0x7b, 0xf2, 0x43, 0x99, 0x74,
+	0xe7, 0x5d, 0x79, 0x6f, 0x1a, 0x98, 0x9c, 0xcb, 0x1d, 0xfd, 0x09, 0x45, 0x1c, 0xca, 0x47, 0xef,
+	0xc7, 0x84, 0x9b, 0x76, 0x85, 0x5d, 0xb9, 0x3f, 0x1d, 0x30, 0xbc, 0xba, 0x94, 0x5b, 0xa4, 0x72,
+	0x75, 0x93, 0x2f, 0xbf, 0x2a, 0xef, 0x4d, 0x03, 0x0b, 0xcf, 0x95, 0x72, 0x6d, 0x53, 0xce, 0x35,
+	0xf9, 0xde, 0xa9, 0xf2, 0xde, 0x34, 0x30, 0x39, 0x97, 0x11, 0xfd, 0xfb, 0x1c, 0x39, 0x91, 0x9a,
+	0x10, 0xbd, 0xc5, 0x67, 0x79, 0x67, 0x22, 0x4c, 0xd8, 0x53, 0x24, 0x5f, 0x89, 0x94, 0x9e, 0x62,
+	0xe2, 0xfd, 0x4b, 0xe5, 0xde, 0x14, 0xa8, 0xb0, 0xdc, 0x52, 0x6e, 0x36, 0xa2, 0x7b, 0xc9, 0xea,
+	0x9b, 0x26, 0xb7, 0x69, 0x17, 0x24, 0x99, 0xf5, 0x08, 0xdd, 0x9a, 0x91, 0xd6, 0x63, 0xfc, 0xbe,
+	0x8d, 0xa2, 0x24, 0x0d, 0x45, 0x7d, 0x74, 0xfc, 0x26, 0x02, 0x8a, 0xfc, 0x87, 0x4d, 0x52, 0x59,
+	0x54, 0xb9, 0x3b, 0x01, 0x42, 0x12, 0x37, 0x61, 0x3d, 0xb1, 0x20, 0x8d, 0xde, 0x89, 0x69, 0x7e,
+	0xe2, 0x14, 0xef, 0x4e, 0x06, 0x92, 0xb3, 0x3c, 0x03, 0x34, 0x5e, 0x6b, 0x96, 0x4b, 0x48, 0x2d,
+	0x43, 0x2b, 0x89, 0xa5, 0x59, 0xae, 0xb1, 0xcc, 0xc3, 0xfd, 0x14, 0x56, 0x13, 0x2a, 0xbd, 0xd2,
+	0x91, 0xa4, 0xd7, 0x8c, 0x15, 0x75, 0x12, 0x48, 0xf8, 0xbc, 0x10, 0x29, 0xa7, 0x49, 0x0f, 0x9a,
+	0x54, 0x76, 0x54, 0x6e, 0x24, 0x0f, 0x86, 0xa8, 0xad, 0x8c, 0x55, 0xd0, 0xe4, 0x79, 0x21, 0xad,
+	0xb6, 0xa6, 0x24, 0x14, 0xea, 0xe8, 0xda, 0x77, 0x3f, 0xfc, 0xc9, 0xd6, 0xb9, 0x45, 0x5e, 0x0d,
+	0x5f, 0x6e, 0xf7, 0x9c, 0xc1, 0xc3, 0x73, 0x8b, 0xb8, 0x8e, 0xf9, 0xc0, 0x72, 0xc4, 0xaf, 0x87,
+	0xdf, 0xf8, 0x0f, 0x06, 0xfc, 0x50, 0xf6, 0xd0, 0x70, 0xad, 0x97, 0x05, 0x56, 0x01, 0xf8, 0xfe,
+	0xff, 0x0e, 0x00, 0x8b, 0x59, 0x45, 0x20, 0x67, 0x4b, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// WorkspaceManagerClient is the client API for WorkspaceManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WorkspaceManagerClient interface {
+	// getWorkspaces produces a list of running workspaces and their status
+	GetWorkspaces(ctx context.Context, in *GetWorkspacesRequest, opts ...grpc.CallOption) (*GetWorkspacesResponse, error)
+	// startWorkspace creates a new running workspace within the manager's cluster
+	StartWorkspace(ctx context.Context, in *StartWorkspaceRequest, opts ...grpc.CallOption) (*StartWorkspaceResponse, error)
+	// stopWorkspace stops a running workspace
+	StopWorkspace(ctx context.Context, in *StopWorkspaceRequest, opts ...grpc.CallOption) (*StopWorkspaceResponse, error)
+	// describeWorkspace investigates a workspace and returns its status, and configuration
+	DescribeWorkspace(ctx context.Context, in *DescribeWorkspaceRequest, opts ...grpc.CallOption) (*DescribeWorkspaceResponse, error)
+	// subscribe streams all status updates to a client
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeClient, error)
+	// markActive records a workspace as being active which prevents it from timing out
+	MarkActive(ctx context.Context, in *MarkActiveRequest, opts ...grpc.CallOption) (*MarkActiveResponse, error)
+	// setTimeout changes the default timeout for a running workspace
+	SetTimeout(ctx context.Context, in *SetTimeoutRequest, opts ...grpc.CallOption) (*SetTimeoutResponse, error)
+	// controlPort publicly exposes or un-exposes a network port for a workspace
+	ControlPort(ctx context.Context, in *ControlPortRequest, opts ...grpc.CallOption) (*ControlPortResponse, error)
+	// takeSnapshot creates a copy of the workspace content which can initialize a new workspace.
+	TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (*TakeSnapshotResponse, error)
+	// controlAdmission makes a workspace accessible for everyone or for the owner only
+	ControlAdmission(ctx context.Context, in *ControlAdmissionRequest, opts ...grpc.CallOption) (*ControlAdmissionResponse, error)
+	// setMaintenance announces or ends a cluster maintenance and notifies all subscribers
+	SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error)
+	// startWorkspaceGroup starts a group of related workspaces which share their lifecycle
+	StartWorkspaceGroup(ctx context.Context, in *StartWorkspaceGroupRequest, opts ...grpc.CallOption) (*StartWorkspaceGroupResponse, error)
+	// stopWorkspaceGroup stops all workspaces of a group
+	StopWorkspaceGroup(ctx context.Context, in *StopWorkspaceGroupRequest, opts ...grpc.CallOption) (*StopWorkspaceGroupResponse, error)
+	// describeWorkspaceGroup returns the status of all workspaces of a group
+	DescribeWorkspaceGroup(ctx context.Context, in *DescribeWorkspaceGroupRequest, opts ...grpc.CallOption) (*DescribeWorkspaceGroupResponse, error)
+	// validatePodTemplate checks a pod template against the pod template policy without applying it (dry-run)
+	ValidatePodTemplate(ctx context.Context, in *ValidatePodTemplateRequest, opts ...grpc.CallOption) (*ValidatePodTemplateResponse, error)
+	// reportProxyActivity records the connections a proxy has open to workspaces so that they count as activity
+	ReportProxyActivity(ctx context.Context, in *ReportProxyActivityRequest, opts ...grpc.CallOption) (*ReportProxyActivityResponse, error)
+	// subscribeAccounting streams the runtime accounting records of workspace instances, starting after a sequence ID
+	SubscribeAccounting(ctx context.Context, in *SubscribeAccountingRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeAccountingClient, error)
+	// ackAccounting acknowledges accounting records up to a sequence ID which allows ws-manager to discard them
+	AckAccounting(ctx context.Context, in *AckAccountingRequest, opts ...grpc.CallOption) (*AckAccountingResponse, error)
+	// scheduleStop stops a running workspace at a specific time, notifying its user before
+	ScheduleStop(ctx context.Context, in *ScheduleStopRequest, opts ...grpc.CallOption) (*ScheduleStopResponse, error)
+	// generateWorkspaceID produces a new workspace ID according to the installation's naming scheme
+	GenerateWorkspaceID(ctx context.Context, in *GenerateWorkspaceIDRequest, opts ...grpc.CallOption) (*GenerateWorkspaceIDResponse, error)
+	// exportState produces a snapshot of the workspace state ws-manager keeps in memory, e.g. for disaster recovery drills
+	ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (*ExportStateResponse, error)
+	// importState restores a snapshot produced by exportState, reconciling it against the workspaces which actually exist
+	ImportState(ctx context.Context, in *ImportStateRequest, opts ...grpc.CallOption) (*ImportStateResponse, error)
+	// describeArchival tells whether stopped workspaces have been archived to cold storage and how long restoring them takes
+	DescribeArchival(ctx context.Context, in *DescribeArchivalRequest, opts ...grpc.CallOption) (*DescribeArchivalResponse, error)
+	// unarchiveWorkspace moves the backup of an archived workspace out of cold storage so that the workspace can start again
+	UnarchiveWorkspace(ctx context.Context, in *UnarchiveWorkspaceRequest, opts ...grpc.CallOption) (*UnarchiveWorkspaceResponse, error)
+	// deleteWorkspace soft-deletes a stopped workspace. Its backup is kept for a grace period during which restoreDeletedWorkspace brings it back.
+	DeleteWorkspace(ctx context.Context, in *DeleteWorkspaceRequest, opts ...grpc.CallOption) (*DeleteWorkspaceResponse, error)
+	// restoreDeletedWorkspace brings back a soft-deleted workspace whose grace period has not ended yet
+	RestoreDeletedWorkspace(ctx context.Context, in *RestoreDeletedWorkspaceRequest, opts ...grpc.CallOption) (*RestoreDeletedWorkspaceResponse, error)
+	// describeDeletedWorkspaces lists the soft-deleted workspaces which can still be restored
+	DescribeDeletedWorkspaces(ctx context.Context, in *DescribeDeletedWorkspacesRequest, opts ...grpc.CallOption) (*DescribeDeletedWorkspacesResponse, error)
+	// createWorkspaceTemplate stores a named workspace template which startWorkspace can reference
+	CreateWorkspaceTemplate(ctx context.Context, in *CreateWorkspaceTemplateRequest, opts ...grpc.CallOption) (*CreateWorkspaceTemplateResponse, error)
+	// updateWorkspaceTemplate adds a new version to a workspace template. Earlier versions remain available.
+	UpdateWorkspaceTemplate(ctx context.Context, in *UpdateWorkspaceTemplateRequest, opts ...grpc.CallOption) (*UpdateWorkspaceTemplateResponse, error)
+	// getWorkspaceTemplate returns a version of a workspace template
+	GetWorkspaceTemplate(ctx context.Context, in *GetWorkspaceTemplateRequest, opts ...grpc.CallOption) (*GetWorkspaceTemplateResponse, error)
+	// listWorkspaceTemplates returns the latest version of all workspace templates a user can use
+	ListWorkspaceTemplates(ctx context.Context, in *ListWorkspaceTemplatesRequest, opts ...grpc.CallOption) (*ListWorkspaceTemplatesResponse, error)
+	// deleteWorkspaceTemplate deletes a workspace template and all its versions
+	DeleteWorkspaceTemplate(ctx context.Context, in *DeleteWorkspaceTemplateRequest, opts ...grpc.CallOption) (*DeleteWorkspaceTemplateResponse, error)
+	// resetIDEPin removes the IDE pin of a running workspace so that it uses the default IDE image again
+	ResetIDEPin(ctx context.Context, in *ResetIDEPinRequest, opts ...grpc.CallOption) (*ResetIDEPinResponse, error)
+	// startBulkOperation applies an operation to all workspaces which match a filter in the background and returns the ID of the job
+	StartBulkOperation(ctx context.Context, in *StartBulkOperationRequest, opts ...grpc.CallOption) (*StartBulkOperationResponse, error)
+	// describeBulkOperation returns the progress and the per-workspace results of a bulk operation
+	DescribeBulkOperation(ctx context.Context, in *DescribeBulkOperationRequest, opts ...grpc.CallOption) (*DescribeBulkOperationResponse, error)
+	// watchBulkOperation streams the per-workspace results of a bulk operation as they come in, starting with those we have already
+	WatchBulkOperation(ctx context.Context, in *WatchBulkOperationRequest, opts ...grpc.CallOption) (WorkspaceManager_WatchBulkOperationClient, error)
+	// cancelBulkOperation stops a bulk operation before it reaches the workspaces it has not worked on yet
+	CancelBulkOperation(ctx context.Context, in *CancelBulkOperationRequest, opts ...grpc.CallOption) (*CancelBulkOperationResponse, error)
+	// queryAuditLog returns the recorded control actions which match a filter, oldest first
+	QueryAuditLog(ctx context.Context, in *QueryAuditLogRequest, opts ...grpc.CallOption) (*QueryAuditLogResponse, error)
+	// subscribeAuditLog streams the audit log from a sequence on, including the records added while streaming, e.g. to export it to a SIEM
+	SubscribeAuditLog(ctx context.Context, in *SubscribeAuditLogRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeAuditLogClient, error)
+}
+
+type workspaceManagerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkspaceManagerClient(cc grpc.ClientConnInterface) WorkspaceManagerClient {
+	return &workspaceManagerClient{cc}
+}
+
+func (c *workspaceManagerClient) GetWorkspaces(ctx context.Context, in *GetWorkspacesRequest, opts ...grpc.CallOption) (*GetWorkspacesResponse, error) {
+	out := new(GetWorkspacesResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/GetWorkspaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) StartWorkspace(ctx context.Context, in *StartWorkspaceRequest, opts ...grpc.CallOption) (*StartWorkspaceResponse, error) {
+	out := new(StartWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/StartWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) StopWorkspace(ctx context.Context, in *StopWorkspaceRequest, opts ...grpc.CallOption) (*StopWorkspaceResponse, error) {
+	out := new(StopWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/StopWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) DescribeWorkspace(ctx context.Context, in *DescribeWorkspaceRequest, opts ...grpc.CallOption) (*DescribeWorkspaceResponse, error) {
+	out := new(DescribeWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DescribeWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkspaceManager_serviceDesc.Streams[0], "/wsman.WorkspaceManager/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workspaceManagerSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkspaceManager_SubscribeClient interface {
+	Recv() (*SubscribeResponse, error)
+	grpc.ClientStream
+}
+
+type workspaceManagerSubscribeClient struct {
+	grpc.ClientStream
 }
 
 func (x *workspaceManagerSubscribeClient) Recv() (*SubscribeResponse, error) {
@@ -2322,300 +7337,1306 @@ func (x *workspaceManagerSubscribeClient) Recv() (*SubscribeResponse, error) {
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return m, nil
+	return m, nil
+}
+
+func (c *workspaceManagerClient) MarkActive(ctx context.Context, in *MarkActiveRequest, opts ...grpc.CallOption) (*MarkActiveResponse, error) {
+	out := new(MarkActiveResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/MarkActive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) SetTimeout(ctx context.Context, in *SetTimeoutRequest, opts ...grpc.CallOption) (*SetTimeoutResponse, error) {
+	out := new(SetTimeoutResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/SetTimeout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ControlPort(ctx context.Context, in *ControlPortRequest, opts ...grpc.CallOption) (*ControlPortResponse, error) {
+	out := new(ControlPortResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ControlPort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (*TakeSnapshotResponse, error) {
+	out := new(TakeSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/TakeSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ControlAdmission(ctx context.Context, in *ControlAdmissionRequest, opts ...grpc.CallOption) (*ControlAdmissionResponse, error) {
+	out := new(ControlAdmissionResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ControlAdmission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*SetMaintenanceResponse, error) {
+	out := new(SetMaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/SetMaintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) StartWorkspaceGroup(ctx context.Context, in *StartWorkspaceGroupRequest, opts ...grpc.CallOption) (*StartWorkspaceGroupResponse, error) {
+	out := new(StartWorkspaceGroupResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/StartWorkspaceGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) StopWorkspaceGroup(ctx context.Context, in *StopWorkspaceGroupRequest, opts ...grpc.CallOption) (*StopWorkspaceGroupResponse, error) {
+	out := new(StopWorkspaceGroupResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/StopWorkspaceGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) DescribeWorkspaceGroup(ctx context.Context, in *DescribeWorkspaceGroupRequest, opts ...grpc.CallOption) (*DescribeWorkspaceGroupResponse, error) {
+	out := new(DescribeWorkspaceGroupResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DescribeWorkspaceGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ValidatePodTemplate(ctx context.Context, in *ValidatePodTemplateRequest, opts ...grpc.CallOption) (*ValidatePodTemplateResponse, error) {
+	out := new(ValidatePodTemplateResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ValidatePodTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ReportProxyActivity(ctx context.Context, in *ReportProxyActivityRequest, opts ...grpc.CallOption) (*ReportProxyActivityResponse, error) {
+	out := new(ReportProxyActivityResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ReportProxyActivity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) SubscribeAccounting(ctx context.Context, in *SubscribeAccountingRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeAccountingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkspaceManager_serviceDesc.Streams[1], "/wsman.WorkspaceManager/SubscribeAccounting", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workspaceManagerSubscribeAccountingClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkspaceManager_SubscribeAccountingClient interface {
+	Recv() (*AccountingRecord, error)
+	grpc.ClientStream
+}
+
+type workspaceManagerSubscribeAccountingClient struct {
+	grpc.ClientStream
+}
+
+func (x *workspaceManagerSubscribeAccountingClient) Recv() (*AccountingRecord, error) {
+	m := new(AccountingRecord)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workspaceManagerClient) AckAccounting(ctx context.Context, in *AckAccountingRequest, opts ...grpc.CallOption) (*AckAccountingResponse, error) {
+	out := new(AckAccountingResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/AckAccounting", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ScheduleStop(ctx context.Context, in *ScheduleStopRequest, opts ...grpc.CallOption) (*ScheduleStopResponse, error) {
+	out := new(ScheduleStopResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ScheduleStop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) GenerateWorkspaceID(ctx context.Context, in *GenerateWorkspaceIDRequest, opts ...grpc.CallOption) (*GenerateWorkspaceIDResponse, error) {
+	out := new(GenerateWorkspaceIDResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/GenerateWorkspaceID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (*ExportStateResponse, error) {
+	out := new(ExportStateResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ExportState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ImportState(ctx context.Context, in *ImportStateRequest, opts ...grpc.CallOption) (*ImportStateResponse, error) {
+	out := new(ImportStateResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ImportState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) DescribeArchival(ctx context.Context, in *DescribeArchivalRequest, opts ...grpc.CallOption) (*DescribeArchivalResponse, error) {
+	out := new(DescribeArchivalResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DescribeArchival", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) UnarchiveWorkspace(ctx context.Context, in *UnarchiveWorkspaceRequest, opts ...grpc.CallOption) (*UnarchiveWorkspaceResponse, error) {
+	out := new(UnarchiveWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/UnarchiveWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) DeleteWorkspace(ctx context.Context, in *DeleteWorkspaceRequest, opts ...grpc.CallOption) (*DeleteWorkspaceResponse, error) {
+	out := new(DeleteWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DeleteWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) RestoreDeletedWorkspace(ctx context.Context, in *RestoreDeletedWorkspaceRequest, opts ...grpc.CallOption) (*RestoreDeletedWorkspaceResponse, error) {
+	out := new(RestoreDeletedWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/RestoreDeletedWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) DescribeDeletedWorkspaces(ctx context.Context, in *DescribeDeletedWorkspacesRequest, opts ...grpc.CallOption) (*DescribeDeletedWorkspacesResponse, error) {
+	out := new(DescribeDeletedWorkspacesResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DescribeDeletedWorkspaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) CreateWorkspaceTemplate(ctx context.Context, in *CreateWorkspaceTemplateRequest, opts ...grpc.CallOption) (*CreateWorkspaceTemplateResponse, error) {
+	out := new(CreateWorkspaceTemplateResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/CreateWorkspaceTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) UpdateWorkspaceTemplate(ctx context.Context, in *UpdateWorkspaceTemplateRequest, opts ...grpc.CallOption) (*UpdateWorkspaceTemplateResponse, error) {
+	out := new(UpdateWorkspaceTemplateResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/UpdateWorkspaceTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) GetWorkspaceTemplate(ctx context.Context, in *GetWorkspaceTemplateRequest, opts ...grpc.CallOption) (*GetWorkspaceTemplateResponse, error) {
+	out := new(GetWorkspaceTemplateResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/GetWorkspaceTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ListWorkspaceTemplates(ctx context.Context, in *ListWorkspaceTemplatesRequest, opts ...grpc.CallOption) (*ListWorkspaceTemplatesResponse, error) {
+	out := new(ListWorkspaceTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ListWorkspaceTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) DeleteWorkspaceTemplate(ctx context.Context, in *DeleteWorkspaceTemplateRequest, opts ...grpc.CallOption) (*DeleteWorkspaceTemplateResponse, error) {
+	out := new(DeleteWorkspaceTemplateResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DeleteWorkspaceTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) ResetIDEPin(ctx context.Context, in *ResetIDEPinRequest, opts ...grpc.CallOption) (*ResetIDEPinResponse, error) {
+	out := new(ResetIDEPinResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ResetIDEPin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) StartBulkOperation(ctx context.Context, in *StartBulkOperationRequest, opts ...grpc.CallOption) (*StartBulkOperationResponse, error) {
+	out := new(StartBulkOperationResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/StartBulkOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) DescribeBulkOperation(ctx context.Context, in *DescribeBulkOperationRequest, opts ...grpc.CallOption) (*DescribeBulkOperationResponse, error) {
+	out := new(DescribeBulkOperationResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/DescribeBulkOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) WatchBulkOperation(ctx context.Context, in *WatchBulkOperationRequest, opts ...grpc.CallOption) (WorkspaceManager_WatchBulkOperationClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkspaceManager_serviceDesc.Streams[2], "/wsman.WorkspaceManager/WatchBulkOperation", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workspaceManagerWatchBulkOperationClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkspaceManager_WatchBulkOperationClient interface {
+	Recv() (*BulkOperationUpdate, error)
+	grpc.ClientStream
+}
+
+type workspaceManagerWatchBulkOperationClient struct {
+	grpc.ClientStream
+}
+
+func (x *workspaceManagerWatchBulkOperationClient) Recv() (*BulkOperationUpdate, error) {
+	m := new(BulkOperationUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workspaceManagerClient) CancelBulkOperation(ctx context.Context, in *CancelBulkOperationRequest, opts ...grpc.CallOption) (*CancelBulkOperationResponse, error) {
+	out := new(CancelBulkOperationResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/CancelBulkOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) QueryAuditLog(ctx context.Context, in *QueryAuditLogRequest, opts ...grpc.CallOption) (*QueryAuditLogResponse, error) {
+	out := new(QueryAuditLogResponse)
+	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/QueryAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workspaceManagerClient) SubscribeAuditLog(ctx context.Context, in *SubscribeAuditLogRequest, opts ...grpc.CallOption) (WorkspaceManager_SubscribeAuditLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WorkspaceManager_serviceDesc.Streams[3], "/wsman.WorkspaceManager/SubscribeAuditLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workspaceManagerSubscribeAuditLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkspaceManager_SubscribeAuditLogClient interface {
+	Recv() (*AuditRecord, error)
+	grpc.ClientStream
+}
+
+type workspaceManagerSubscribeAuditLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *workspaceManagerSubscribeAuditLogClient) Recv() (*AuditRecord, error) {
+	m := new(AuditRecord)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WorkspaceManagerServer is the server API for WorkspaceManager service.
+type WorkspaceManagerServer interface {
+	// getWorkspaces produces a list of running workspaces and their status
+	GetWorkspaces(context.Context, *GetWorkspacesRequest) (*GetWorkspacesResponse, error)
+	// startWorkspace creates a new running workspace within the manager's cluster
+	StartWorkspace(context.Context, *StartWorkspaceRequest) (*StartWorkspaceResponse, error)
+	// stopWorkspace stops a running workspace
+	StopWorkspace(context.Context, *StopWorkspaceRequest) (*StopWorkspaceResponse, error)
+	// describeWorkspace investigates a workspace and returns its status, and configuration
+	DescribeWorkspace(context.Context, *DescribeWorkspaceRequest) (*DescribeWorkspaceResponse, error)
+	// subscribe streams all status updates to a client
+	Subscribe(*SubscribeRequest, WorkspaceManager_SubscribeServer) error
+	// markActive records a workspace as being active which prevents it from timing out
+	MarkActive(context.Context, *MarkActiveRequest) (*MarkActiveResponse, error)
+	// setTimeout changes the default timeout for a running workspace
+	SetTimeout(context.Context, *SetTimeoutRequest) (*SetTimeoutResponse, error)
+	// controlPort publicly exposes or un-exposes a network port for a workspace
+	ControlPort(context.Context, *ControlPortRequest) (*ControlPortResponse, error)
+	// takeSnapshot creates a copy of the workspace content which can initialize a new workspace.
+	TakeSnapshot(context.Context, *TakeSnapshotRequest) (*TakeSnapshotResponse, error)
+	// controlAdmission makes a workspace accessible for everyone or for the owner only
+	ControlAdmission(context.Context, *ControlAdmissionRequest) (*ControlAdmissionResponse, error)
+	// setMaintenance announces or ends a cluster maintenance and notifies all subscribers
+	SetMaintenance(context.Context, *SetMaintenanceRequest) (*SetMaintenanceResponse, error)
+	// startWorkspaceGroup starts a group of related workspaces which share their lifecycle
+	StartWorkspaceGroup(context.Context, *StartWorkspaceGroupRequest) (*StartWorkspaceGroupResponse, error)
+	// stopWorkspaceGroup stops all workspaces of a group
+	StopWorkspaceGroup(context.Context, *StopWorkspaceGroupRequest) (*StopWorkspaceGroupResponse, error)
+	// describeWorkspaceGroup returns the status of all workspaces of a group
+	DescribeWorkspaceGroup(context.Context, *DescribeWorkspaceGroupRequest) (*DescribeWorkspaceGroupResponse, error)
+	// validatePodTemplate checks a pod template against the pod template policy without applying it (dry-run)
+	ValidatePodTemplate(context.Context, *ValidatePodTemplateRequest) (*ValidatePodTemplateResponse, error)
+	// reportProxyActivity records the connections a proxy has open to workspaces so that they count as activity
+	ReportProxyActivity(context.Context, *ReportProxyActivityRequest) (*ReportProxyActivityResponse, error)
+	// subscribeAccounting streams the runtime accounting records of workspace instances, starting after a sequence ID
+	SubscribeAccounting(*SubscribeAccountingRequest, WorkspaceManager_SubscribeAccountingServer) error
+	// ackAccounting acknowledges accounting records up to a sequence ID which allows ws-manager to discard them
+	AckAccounting(context.Context, *AckAccountingRequest) (*AckAccountingResponse, error)
+	// scheduleStop stops a running workspace at a specific time, notifying its user before
+	ScheduleStop(context.Context, *ScheduleStopRequest) (*ScheduleStopResponse, error)
+	// generateWorkspaceID produces a new workspace ID according to the installation's naming scheme
+	GenerateWorkspaceID(context.Context, *GenerateWorkspaceIDRequest) (*GenerateWorkspaceIDResponse, error)
+	// exportState produces a snapshot of the workspace state ws-manager keeps in memory, e.g. for disaster recovery drills
+	ExportState(context.Context, *ExportStateRequest) (*ExportStateResponse, error)
+	// importState restores a snapshot produced by exportState, reconciling it against the workspaces which actually exist
+	ImportState(context.Context, *ImportStateRequest) (*ImportStateResponse, error)
+	// describeArchival tells whether stopped workspaces have been archived to cold storage and how long restoring them takes
+	DescribeArchival(context.Context, *DescribeArchivalRequest) (*DescribeArchivalResponse, error)
+	// unarchiveWorkspace moves the backup of an archived workspace out of cold storage so that the workspace can start again
+	UnarchiveWorkspace(context.Context, *UnarchiveWorkspaceRequest) (*UnarchiveWorkspaceResponse, error)
+	// deleteWorkspace soft-deletes a stopped workspace. Its backup is kept for a grace period during which restoreDeletedWorkspace brings it back.
+	DeleteWorkspace(context.Context, *DeleteWorkspaceRequest) (*DeleteWorkspaceResponse, error)
+	// restoreDeletedWorkspace brings back a soft-deleted workspace whose grace period has not ended yet
+	RestoreDeletedWorkspace(context.Context, *RestoreDeletedWorkspaceRequest) (*RestoreDeletedWorkspaceResponse, error)
+	// describeDeletedWorkspaces lists the soft-deleted workspaces which can still be restored
+	DescribeDeletedWorkspaces(context.Context, *DescribeDeletedWorkspacesRequest) (*DescribeDeletedWorkspacesResponse, error)
+	// createWorkspaceTemplate stores a named workspace template which startWorkspace can reference
+	CreateWorkspaceTemplate(context.Context, *CreateWorkspaceTemplateRequest) (*CreateWorkspaceTemplateResponse, error)
+	// updateWorkspaceTemplate adds a new version to a workspace template. Earlier versions remain available.
+	UpdateWorkspaceTemplate(context.Context, *UpdateWorkspaceTemplateRequest) (*UpdateWorkspaceTemplateResponse, error)
+	// getWorkspaceTemplate returns a version of a workspace template
+	GetWorkspaceTemplate(context.Context, *GetWorkspaceTemplateRequest) (*GetWorkspaceTemplateResponse, error)
+	// listWorkspaceTemplates returns the latest version of all workspace templates a user can use
+	ListWorkspaceTemplates(context.Context, *ListWorkspaceTemplatesRequest) (*ListWorkspaceTemplatesResponse, error)
+	// deleteWorkspaceTemplate deletes a workspace template and all its versions
+	DeleteWorkspaceTemplate(context.Context, *DeleteWorkspaceTemplateRequest) (*DeleteWorkspaceTemplateResponse, error)
+	// resetIDEPin removes the IDE pin of a running workspace so that it uses the default IDE image again
+	ResetIDEPin(context.Context, *ResetIDEPinRequest) (*ResetIDEPinResponse, error)
+	// startBulkOperation applies an operation to all workspaces which match a filter in the background and returns the ID of the job
+	StartBulkOperation(context.Context, *StartBulkOperationRequest) (*StartBulkOperationResponse, error)
+	// describeBulkOperation returns the progress and the per-workspace results of a bulk operation
+	DescribeBulkOperation(context.Context, *DescribeBulkOperationRequest) (*DescribeBulkOperationResponse, error)
+	// watchBulkOperation streams the per-workspace results of a bulk operation as they come in, starting with those we have already
+	WatchBulkOperation(*WatchBulkOperationRequest, WorkspaceManager_WatchBulkOperationServer) error
+	// cancelBulkOperation stops a bulk operation before it reaches the workspaces it has not worked on yet
+	CancelBulkOperation(context.Context, *CancelBulkOperationRequest) (*CancelBulkOperationResponse, error)
+	// queryAuditLog returns the recorded control actions which match a filter, oldest first
+	QueryAuditLog(context.Context, *QueryAuditLogRequest) (*QueryAuditLogResponse, error)
+	// subscribeAuditLog streams the audit log from a sequence on, including the records added while streaming, e.g. to export it to a SIEM
+	SubscribeAuditLog(*SubscribeAuditLogRequest, WorkspaceManager_SubscribeAuditLogServer) error
+}
+
+// UnimplementedWorkspaceManagerServer can be embedded to have forward compatible implementations.
+type UnimplementedWorkspaceManagerServer struct {
+}
+
+func (*UnimplementedWorkspaceManagerServer) GetWorkspaces(ctx context.Context, req *GetWorkspacesRequest) (*GetWorkspacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaces not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) StartWorkspace(ctx context.Context, req *StartWorkspaceRequest) (*StartWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartWorkspace not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) StopWorkspace(ctx context.Context, req *StopWorkspaceRequest) (*StopWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopWorkspace not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) DescribeWorkspace(ctx context.Context, req *DescribeWorkspaceRequest) (*DescribeWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeWorkspace not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) Subscribe(req *SubscribeRequest, srv WorkspaceManager_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) MarkActive(ctx context.Context, req *MarkActiveRequest) (*MarkActiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkActive not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) SetTimeout(ctx context.Context, req *SetTimeoutRequest) (*SetTimeoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTimeout not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ControlPort(ctx context.Context, req *ControlPortRequest) (*ControlPortResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ControlPort not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) TakeSnapshot(ctx context.Context, req *TakeSnapshotRequest) (*TakeSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakeSnapshot not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ControlAdmission(ctx context.Context, req *ControlAdmissionRequest) (*ControlAdmissionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ControlAdmission not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) SetMaintenance(ctx context.Context, req *SetMaintenanceRequest) (*SetMaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenance not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) StartWorkspaceGroup(ctx context.Context, req *StartWorkspaceGroupRequest) (*StartWorkspaceGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartWorkspaceGroup not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) StopWorkspaceGroup(ctx context.Context, req *StopWorkspaceGroupRequest) (*StopWorkspaceGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopWorkspaceGroup not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) DescribeWorkspaceGroup(ctx context.Context, req *DescribeWorkspaceGroupRequest) (*DescribeWorkspaceGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeWorkspaceGroup not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ValidatePodTemplate(ctx context.Context, req *ValidatePodTemplateRequest) (*ValidatePodTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePodTemplate not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ReportProxyActivity(ctx context.Context, req *ReportProxyActivityRequest) (*ReportProxyActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportProxyActivity not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) SubscribeAccounting(req *SubscribeAccountingRequest, srv WorkspaceManager_SubscribeAccountingServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAccounting not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) AckAccounting(ctx context.Context, req *AckAccountingRequest) (*AckAccountingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckAccounting not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ScheduleStop(ctx context.Context, req *ScheduleStopRequest) (*ScheduleStopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleStop not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) GenerateWorkspaceID(ctx context.Context, req *GenerateWorkspaceIDRequest) (*GenerateWorkspaceIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateWorkspaceID not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ExportState(ctx context.Context, req *ExportStateRequest) (*ExportStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportState not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ImportState(ctx context.Context, req *ImportStateRequest) (*ImportStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportState not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) DescribeArchival(ctx context.Context, req *DescribeArchivalRequest) (*DescribeArchivalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeArchival not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) UnarchiveWorkspace(ctx context.Context, req *UnarchiveWorkspaceRequest) (*UnarchiveWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnarchiveWorkspace not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) DeleteWorkspace(ctx context.Context, req *DeleteWorkspaceRequest) (*DeleteWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkspace not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) RestoreDeletedWorkspace(ctx context.Context, req *RestoreDeletedWorkspaceRequest) (*RestoreDeletedWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreDeletedWorkspace not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) DescribeDeletedWorkspaces(ctx context.Context, req *DescribeDeletedWorkspacesRequest) (*DescribeDeletedWorkspacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeDeletedWorkspaces not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) CreateWorkspaceTemplate(ctx context.Context, req *CreateWorkspaceTemplateRequest) (*CreateWorkspaceTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkspaceTemplate not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) UpdateWorkspaceTemplate(ctx context.Context, req *UpdateWorkspaceTemplateRequest) (*UpdateWorkspaceTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWorkspaceTemplate not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) GetWorkspaceTemplate(ctx context.Context, req *GetWorkspaceTemplateRequest) (*GetWorkspaceTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceTemplate not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ListWorkspaceTemplates(ctx context.Context, req *ListWorkspaceTemplatesRequest) (*ListWorkspaceTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaceTemplates not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) DeleteWorkspaceTemplate(ctx context.Context, req *DeleteWorkspaceTemplateRequest) (*DeleteWorkspaceTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkspaceTemplate not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) ResetIDEPin(ctx context.Context, req *ResetIDEPinRequest) (*ResetIDEPinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetIDEPin not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) StartBulkOperation(ctx context.Context, req *StartBulkOperationRequest) (*StartBulkOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartBulkOperation not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) DescribeBulkOperation(ctx context.Context, req *DescribeBulkOperationRequest) (*DescribeBulkOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeBulkOperation not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) WatchBulkOperation(req *WatchBulkOperationRequest, srv WorkspaceManager_WatchBulkOperationServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBulkOperation not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) CancelBulkOperation(ctx context.Context, req *CancelBulkOperationRequest) (*CancelBulkOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelBulkOperation not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) QueryAuditLog(ctx context.Context, req *QueryAuditLogRequest) (*QueryAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditLog not implemented")
+}
+func (*UnimplementedWorkspaceManagerServer) SubscribeAuditLog(req *SubscribeAuditLogRequest, srv WorkspaceManager_SubscribeAuditLogServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAuditLog not implemented")
+}
+
+func RegisterWorkspaceManagerServer(s *grpc.Server, srv WorkspaceManagerServer) {
+	s.RegisterService(&_WorkspaceManager_serviceDesc, srv)
+}
+
+func _WorkspaceManager_GetWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkspacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).GetWorkspaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/GetWorkspaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).GetWorkspaces(ctx, req.(*GetWorkspacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_StartWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).StartWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/StartWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).StartWorkspace(ctx, req.(*StartWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_StopWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).StopWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/StopWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).StopWorkspace(ctx, req.(*StopWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_DescribeWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).DescribeWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/DescribeWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).DescribeWorkspace(ctx, req.(*DescribeWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkspaceManagerServer).Subscribe(m, &workspaceManagerSubscribeServer{stream})
+}
+
+type WorkspaceManager_SubscribeServer interface {
+	Send(*SubscribeResponse) error
+	grpc.ServerStream
+}
+
+type workspaceManagerSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *workspaceManagerSubscribeServer) Send(m *SubscribeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WorkspaceManager_MarkActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkActiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).MarkActive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/MarkActive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).MarkActive(ctx, req.(*MarkActiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workspaceManagerClient) MarkActive(ctx context.Context, in *MarkActiveRequest, opts ...grpc.CallOption) (*MarkActiveResponse, error) {
-	out := new(MarkActiveResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/MarkActive", in, out, opts...)
-	if err != nil {
+func _WorkspaceManager_SetTimeout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTimeoutRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).SetTimeout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/SetTimeout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).SetTimeout(ctx, req.(*SetTimeoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workspaceManagerClient) SetTimeout(ctx context.Context, in *SetTimeoutRequest, opts ...grpc.CallOption) (*SetTimeoutResponse, error) {
-	out := new(SetTimeoutResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/SetTimeout", in, out, opts...)
-	if err != nil {
+func _WorkspaceManager_ControlPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ControlPortRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ControlPort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ControlPort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ControlPort(ctx, req.(*ControlPortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workspaceManagerClient) ControlPort(ctx context.Context, in *ControlPortRequest, opts ...grpc.CallOption) (*ControlPortResponse, error) {
-	out := new(ControlPortResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ControlPort", in, out, opts...)
-	if err != nil {
+func _WorkspaceManager_TakeSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TakeSnapshotRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).TakeSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/TakeSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).TakeSnapshot(ctx, req.(*TakeSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workspaceManagerClient) TakeSnapshot(ctx context.Context, in *TakeSnapshotRequest, opts ...grpc.CallOption) (*TakeSnapshotResponse, error) {
-	out := new(TakeSnapshotResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/TakeSnapshot", in, out, opts...)
-	if err != nil {
+func _WorkspaceManager_ControlAdmission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ControlAdmissionRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ControlAdmission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ControlAdmission",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ControlAdmission(ctx, req.(*ControlAdmissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *workspaceManagerClient) ControlAdmission(ctx context.Context, in *ControlAdmissionRequest, opts ...grpc.CallOption) (*ControlAdmissionResponse, error) {
-	out := new(ControlAdmissionResponse)
-	err := c.cc.Invoke(ctx, "/wsman.WorkspaceManager/ControlAdmission", in, out, opts...)
-	if err != nil {
+func _WorkspaceManager_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).SetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/SetMaintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).SetMaintenance(ctx, req.(*SetMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// WorkspaceManagerServer is the server API for WorkspaceManager service.
-type WorkspaceManagerServer interface {
-	// getWorkspaces produces a list of running workspaces and their status
-	GetWorkspaces(context.Context, *GetWorkspacesRequest) (*GetWorkspacesResponse, error)
-	// startWorkspace creates a new running workspace within the manager's cluster
-	StartWorkspace(context.Context, *StartWorkspaceRequest) (*StartWorkspaceResponse, error)
-	// stopWorkspace stops a running workspace
-	StopWorkspace(context.Context, *StopWorkspaceRequest) (*StopWorkspaceResponse, error)
-	// describeWorkspace investigates a workspace and returns its status, and configuration
-	DescribeWorkspace(context.Context, *DescribeWorkspaceRequest) (*DescribeWorkspaceResponse, error)
-	// subscribe streams all status updates to a client
-	Subscribe(*SubscribeRequest, WorkspaceManager_SubscribeServer) error
-	// markActive records a workspace as being active which prevents it from timing out
-	MarkActive(context.Context, *MarkActiveRequest) (*MarkActiveResponse, error)
-	// setTimeout changes the default timeout for a running workspace
-	SetTimeout(context.Context, *SetTimeoutRequest) (*SetTimeoutResponse, error)
-	// controlPort publicly exposes or un-exposes a network port for a workspace
-	ControlPort(context.Context, *ControlPortRequest) (*ControlPortResponse, error)
-	// takeSnapshot creates a copy of the workspace content which can initialize a new workspace.
-	TakeSnapshot(context.Context, *TakeSnapshotRequest) (*TakeSnapshotResponse, error)
-	// controlAdmission makes a workspace accessible for everyone or for the owner only
-	ControlAdmission(context.Context, *ControlAdmissionRequest) (*ControlAdmissionResponse, error)
+func _WorkspaceManager_StartWorkspaceGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartWorkspaceGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).StartWorkspaceGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/StartWorkspaceGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).StartWorkspaceGroup(ctx, req.(*StartWorkspaceGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_StopWorkspaceGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopWorkspaceGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).StopWorkspaceGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/StopWorkspaceGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).StopWorkspaceGroup(ctx, req.(*StopWorkspaceGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_DescribeWorkspaceGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeWorkspaceGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).DescribeWorkspaceGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/DescribeWorkspaceGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).DescribeWorkspaceGroup(ctx, req.(*DescribeWorkspaceGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_ValidatePodTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidatePodTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ValidatePodTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ValidatePodTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ValidatePodTemplate(ctx, req.(*ValidatePodTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_ReportProxyActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportProxyActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ReportProxyActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ReportProxyActivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ReportProxyActivity(ctx, req.(*ReportProxyActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_SubscribeAccounting_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAccountingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkspaceManagerServer).SubscribeAccounting(m, &workspaceManagerSubscribeAccountingServer{stream})
+}
+
+type WorkspaceManager_SubscribeAccountingServer interface {
+	Send(*AccountingRecord) error
+	grpc.ServerStream
+}
+
+type workspaceManagerSubscribeAccountingServer struct {
+	grpc.ServerStream
+}
+
+func (x *workspaceManagerSubscribeAccountingServer) Send(m *AccountingRecord) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WorkspaceManager_AckAccounting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckAccountingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).AckAccounting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/AckAccounting",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).AckAccounting(ctx, req.(*AckAccountingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_ScheduleStop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleStopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ScheduleStop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ScheduleStop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ScheduleStop(ctx, req.(*ScheduleStopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_GenerateWorkspaceID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateWorkspaceIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).GenerateWorkspaceID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/GenerateWorkspaceID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).GenerateWorkspaceID(ctx, req.(*GenerateWorkspaceIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkspaceManager_ExportState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ExportState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ExportState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ExportState(ctx, req.(*ExportStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedWorkspaceManagerServer can be embedded to have forward compatible implementations.
-type UnimplementedWorkspaceManagerServer struct {
+func _WorkspaceManager_ImportState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ImportState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ImportState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ImportState(ctx, req.(*ImportStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (*UnimplementedWorkspaceManagerServer) GetWorkspaces(ctx context.Context, req *GetWorkspacesRequest) (*GetWorkspacesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaces not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) StartWorkspace(ctx context.Context, req *StartWorkspaceRequest) (*StartWorkspaceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StartWorkspace not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) StopWorkspace(ctx context.Context, req *StopWorkspaceRequest) (*StopWorkspaceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StopWorkspace not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) DescribeWorkspace(ctx context.Context, req *DescribeWorkspaceRequest) (*DescribeWorkspaceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DescribeWorkspace not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) Subscribe(req *SubscribeRequest, srv WorkspaceManager_SubscribeServer) error {
-	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) MarkActive(ctx context.Context, req *MarkActiveRequest) (*MarkActiveResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method MarkActive not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) SetTimeout(ctx context.Context, req *SetTimeoutRequest) (*SetTimeoutResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SetTimeout not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) ControlPort(ctx context.Context, req *ControlPortRequest) (*ControlPortResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ControlPort not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) TakeSnapshot(ctx context.Context, req *TakeSnapshotRequest) (*TakeSnapshotResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method TakeSnapshot not implemented")
-}
-func (*UnimplementedWorkspaceManagerServer) ControlAdmission(ctx context.Context, req *ControlAdmissionRequest) (*ControlAdmissionResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ControlAdmission not implemented")
+func _WorkspaceManager_DescribeArchival_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeArchivalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).DescribeArchival(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/DescribeArchival",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).DescribeArchival(ctx, req.(*DescribeArchivalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterWorkspaceManagerServer(s *grpc.Server, srv WorkspaceManagerServer) {
-	s.RegisterService(&_WorkspaceManager_serviceDesc, srv)
+func _WorkspaceManager_UnarchiveWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnarchiveWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).UnarchiveWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/UnarchiveWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).UnarchiveWorkspace(ctx, req.(*UnarchiveWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_GetWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetWorkspacesRequest)
+func _WorkspaceManager_DeleteWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWorkspaceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).GetWorkspaces(ctx, in)
+		return srv.(WorkspaceManagerServer).DeleteWorkspace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/GetWorkspaces",
+		FullMethod: "/wsman.WorkspaceManager/DeleteWorkspace",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).GetWorkspaces(ctx, req.(*GetWorkspacesRequest))
+		return srv.(WorkspaceManagerServer).DeleteWorkspace(ctx, req.(*DeleteWorkspaceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_StartWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartWorkspaceRequest)
+func _WorkspaceManager_RestoreDeletedWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreDeletedWorkspaceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).StartWorkspace(ctx, in)
+		return srv.(WorkspaceManagerServer).RestoreDeletedWorkspace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/StartWorkspace",
+		FullMethod: "/wsman.WorkspaceManager/RestoreDeletedWorkspace",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).StartWorkspace(ctx, req.(*StartWorkspaceRequest))
+		return srv.(WorkspaceManagerServer).RestoreDeletedWorkspace(ctx, req.(*RestoreDeletedWorkspaceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_StopWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopWorkspaceRequest)
+func _WorkspaceManager_DescribeDeletedWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeDeletedWorkspacesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).StopWorkspace(ctx, in)
+		return srv.(WorkspaceManagerServer).DescribeDeletedWorkspaces(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/StopWorkspace",
+		FullMethod: "/wsman.WorkspaceManager/DescribeDeletedWorkspaces",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).StopWorkspace(ctx, req.(*StopWorkspaceRequest))
+		return srv.(WorkspaceManagerServer).DescribeDeletedWorkspaces(ctx, req.(*DescribeDeletedWorkspacesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_DescribeWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DescribeWorkspaceRequest)
+func _WorkspaceManager_CreateWorkspaceTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkspaceTemplateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).DescribeWorkspace(ctx, in)
+		return srv.(WorkspaceManagerServer).CreateWorkspaceTemplate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/DescribeWorkspace",
+		FullMethod: "/wsman.WorkspaceManager/CreateWorkspaceTemplate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).DescribeWorkspace(ctx, req.(*DescribeWorkspaceRequest))
+		return srv.(WorkspaceManagerServer).CreateWorkspaceTemplate(ctx, req.(*CreateWorkspaceTemplateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SubscribeRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _WorkspaceManager_UpdateWorkspaceTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWorkspaceTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(WorkspaceManagerServer).Subscribe(m, &workspaceManagerSubscribeServer{stream})
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).UpdateWorkspaceTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/UpdateWorkspaceTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).UpdateWorkspaceTemplate(ctx, req.(*UpdateWorkspaceTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type WorkspaceManager_SubscribeServer interface {
-	Send(*SubscribeResponse) error
-	grpc.ServerStream
+func _WorkspaceManager_GetWorkspaceTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkspaceTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).GetWorkspaceTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/GetWorkspaceTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).GetWorkspaceTemplate(ctx, req.(*GetWorkspaceTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type workspaceManagerSubscribeServer struct {
-	grpc.ServerStream
+func _WorkspaceManager_ListWorkspaceTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkspaceTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).ListWorkspaceTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/ListWorkspaceTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).ListWorkspaceTemplates(ctx, req.(*ListWorkspaceTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *workspaceManagerSubscribeServer) Send(m *SubscribeResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _WorkspaceManager_DeleteWorkspaceTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWorkspaceTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkspaceManagerServer).DeleteWorkspaceTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wsman.WorkspaceManager/DeleteWorkspaceTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkspaceManagerServer).DeleteWorkspaceTemplate(ctx, req.(*DeleteWorkspaceTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_MarkActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MarkActiveRequest)
+func _WorkspaceManager_ResetIDEPin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetIDEPinRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).MarkActive(ctx, in)
+		return srv.(WorkspaceManagerServer).ResetIDEPin(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/MarkActive",
+		FullMethod: "/wsman.WorkspaceManager/ResetIDEPin",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).MarkActive(ctx, req.(*MarkActiveRequest))
+		return srv.(WorkspaceManagerServer).ResetIDEPin(ctx, req.(*ResetIDEPinRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_SetTimeout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetTimeoutRequest)
+func _WorkspaceManager_StartBulkOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartBulkOperationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).SetTimeout(ctx, in)
+		return srv.(WorkspaceManagerServer).StartBulkOperation(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/SetTimeout",
+		FullMethod: "/wsman.WorkspaceManager/StartBulkOperation",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).SetTimeout(ctx, req.(*SetTimeoutRequest))
+		return srv.(WorkspaceManagerServer).StartBulkOperation(ctx, req.(*StartBulkOperationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_ControlPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ControlPortRequest)
+func _WorkspaceManager_DescribeBulkOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeBulkOperationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).ControlPort(ctx, in)
+		return srv.(WorkspaceManagerServer).DescribeBulkOperation(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/ControlPort",
+		FullMethod: "/wsman.WorkspaceManager/DescribeBulkOperation",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).ControlPort(ctx, req.(*ControlPortRequest))
+		return srv.(WorkspaceManagerServer).DescribeBulkOperation(ctx, req.(*DescribeBulkOperationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_TakeSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TakeSnapshotRequest)
+func _WorkspaceManager_WatchBulkOperation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBulkOperationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkspaceManagerServer).WatchBulkOperation(m, &workspaceManagerWatchBulkOperationServer{stream})
+}
+
+type WorkspaceManager_WatchBulkOperationServer interface {
+	Send(*BulkOperationUpdate) error
+	grpc.ServerStream
+}
+
+type workspaceManagerWatchBulkOperationServer struct {
+	grpc.ServerStream
+}
+
+func (x *workspaceManagerWatchBulkOperationServer) Send(m *BulkOperationUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WorkspaceManager_CancelBulkOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelBulkOperationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).TakeSnapshot(ctx, in)
+		return srv.(WorkspaceManagerServer).CancelBulkOperation(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/TakeSnapshot",
+		FullMethod: "/wsman.WorkspaceManager/CancelBulkOperation",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).TakeSnapshot(ctx, req.(*TakeSnapshotRequest))
+		return srv.(WorkspaceManagerServer).CancelBulkOperation(ctx, req.(*CancelBulkOperationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WorkspaceManager_ControlAdmission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ControlAdmissionRequest)
+func _WorkspaceManager_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAuditLogRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WorkspaceManagerServer).ControlAdmission(ctx, in)
+		return srv.(WorkspaceManagerServer).QueryAuditLog(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/wsman.WorkspaceManager/ControlAdmission",
+		FullMethod: "/wsman.WorkspaceManager/QueryAuditLog",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkspaceManagerServer).ControlAdmission(ctx, req.(*ControlAdmissionRequest))
+		return srv.(WorkspaceManagerServer).QueryAuditLog(ctx, req.(*QueryAuditLogRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkspaceManager_SubscribeAuditLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAuditLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkspaceManagerServer).SubscribeAuditLog(m, &workspaceManagerSubscribeAuditLogServer{stream})
+}
+
+type WorkspaceManager_SubscribeAuditLogServer interface {
+	Send(*AuditRecord) error
+	grpc.ServerStream
+}
+
+type workspaceManagerSubscribeAuditLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *workspaceManagerSubscribeAuditLogServer) Send(m *AuditRecord) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _WorkspaceManager_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "wsman.WorkspaceManager",
 	HandlerType: (*WorkspaceManagerServer)(nil),
@@ -2656,6 +8677,110 @@ var _WorkspaceManager_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ControlAdmission",
 			Handler:    _WorkspaceManager_ControlAdmission_Handler,
 		},
+		{
+			MethodName: "SetMaintenance",
+			Handler:    _WorkspaceManager_SetMaintenance_Handler,
+		},
+		{
+			MethodName: "StartWorkspaceGroup",
+			Handler:    _WorkspaceManager_StartWorkspaceGroup_Handler,
+		},
+		{
+			MethodName: "StopWorkspaceGroup",
+			Handler:    _WorkspaceManager_StopWorkspaceGroup_Handler,
+		},
+		{
+			MethodName: "DescribeWorkspaceGroup",
+			Handler:    _WorkspaceManager_DescribeWorkspaceGroup_Handler,
+		},
+		{
+			MethodName: "ValidatePodTemplate",
+			Handler:    _WorkspaceManager_ValidatePodTemplate_Handler,
+		},
+		{
+			MethodName: "ReportProxyActivity",
+			Handler:    _WorkspaceManager_ReportProxyActivity_Handler,
+		},
+		{
+			MethodName: "AckAccounting",
+			Handler:    _WorkspaceManager_AckAccounting_Handler,
+		},
+		{
+			MethodName: "ScheduleStop",
+			Handler:    _WorkspaceManager_ScheduleStop_Handler,
+		},
+		{
+			MethodName: "GenerateWorkspaceID",
+			Handler:    _WorkspaceManager_GenerateWorkspaceID_Handler,
+		},
+		{
+			MethodName: "ExportState",
+			Handler:    _WorkspaceManager_ExportState_Handler,
+		},
+		{
+			MethodName: "ImportState",
+			Handler:    _WorkspaceManager_ImportState_Handler,
+		},
+		{
+			MethodName: "DescribeArchival",
+			Handler:    _WorkspaceManager_DescribeArchival_Handler,
+		},
+		{
+			MethodName: "UnarchiveWorkspace",
+			Handler:    _WorkspaceManager_UnarchiveWorkspace_Handler,
+		},
+		{
+			MethodName: "DeleteWorkspace",
+			Handler:    _WorkspaceManager_DeleteWorkspace_Handler,
+		},
+		{
+			MethodName: "RestoreDeletedWorkspace",
+			Handler:    _WorkspaceManager_RestoreDeletedWorkspace_Handler,
+		},
+		{
+			MethodName: "DescribeDeletedWorkspaces",
+			Handler:    _WorkspaceManager_DescribeDeletedWorkspaces_Handler,
+		},
+		{
+			MethodName: "CreateWorkspaceTemplate",
+			Handler:    _WorkspaceManager_CreateWorkspaceTemplate_Handler,
+		},
+		{
+			MethodName: "UpdateWorkspaceTemplate",
+			Handler:    _WorkspaceManager_UpdateWorkspaceTemplate_Handler,
+		},
+		{
+			MethodName: "GetWorkspaceTemplate",
+			Handler:    _WorkspaceManager_GetWorkspaceTemplate_Handler,
+		},
+		{
+			MethodName: "ListWorkspaceTemplates",
+			Handler:    _WorkspaceManager_ListWorkspaceTemplates_Handler,
+		},
+		{
+			MethodName: "DeleteWorkspaceTemplate",
+			Handler:    _WorkspaceManager_DeleteWorkspaceTemplate_Handler,
+		},
+		{
+			MethodName: "ResetIDEPin",
+			Handler:    _WorkspaceManager_ResetIDEPin_Handler,
+		},
+		{
+			MethodName: "StartBulkOperation",
+			Handler:    _WorkspaceManager_StartBulkOperation_Handler,
+		},
+		{
+			MethodName: "DescribeBulkOperation",
+			Handler:    _WorkspaceManager_DescribeBulkOperation_Handler,
+		},
+		{
+			MethodName: "CancelBulkOperation",
+			Handler:    _WorkspaceManager_CancelBulkOperation_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _WorkspaceManager_QueryAuditLog_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2663,6 +8788,21 @@ var _WorkspaceManager_serviceDesc = grpc.ServiceDesc{
 			Handler:       _WorkspaceManager_Subscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeAccounting",
+			Handler:       _WorkspaceManager_SubscribeAccounting_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchBulkOperation",
+			Handler:       _WorkspaceManager_WatchBulkOperation_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeAuditLog",
+			Handler:       _WorkspaceManager_SubscribeAuditLog_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "core.proto",
 }
@@ -0,0 +1,38 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package api
+
+import (
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// GetStartWorkspaceErrorDetails returns the details ws-manager attached to a failed StartWorkspace call,
+// or nil if there are none
+func GetStartWorkspaceErrorDetails(err error) *StartWorkspaceErrorDetails {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, d := range st.Details() {
+		if res, ok := d.(*StartWorkspaceErrorDetails); ok {
+			return res
+		}
+	}
+	return nil
+}
+
+// RetryAfterDuration returns how long callers should wait before they retry, or zero if ws-manager did not say
+func (d *StartWorkspaceErrorDetails) RetryAfterDuration() time.Duration {
+	if d == nil || d.RetryAfter == "" {
+		return 0
+	}
+	res, err := time.ParseDuration(d.RetryAfter)
+	if err != nil {
+		return 0
+	}
+	return res
+}
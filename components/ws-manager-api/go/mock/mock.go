@@ -96,6 +96,26 @@ func (mr *MockWorkspaceManagerClientMockRecorder) DescribeWorkspace(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DescribeWorkspace), varargs...)
 }
 
+// DescribeWorkspaceGroup mocks base method
+func (m *MockWorkspaceManagerClient) DescribeWorkspaceGroup(arg0 context.Context, arg1 *api.DescribeWorkspaceGroupRequest, arg2 ...grpc.CallOption) (*api.DescribeWorkspaceGroupResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeWorkspaceGroup", varargs...)
+	ret0, _ := ret[0].(*api.DescribeWorkspaceGroupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeWorkspaceGroup indicates an expected call of DescribeWorkspaceGroup
+func (mr *MockWorkspaceManagerClientMockRecorder) DescribeWorkspaceGroup(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkspaceGroup", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DescribeWorkspaceGroup), varargs...)
+}
+
 // GetWorkspaces mocks base method
 func (m *MockWorkspaceManagerClient) GetWorkspaces(arg0 context.Context, arg1 *api.GetWorkspacesRequest, arg2 ...grpc.CallOption) (*api.GetWorkspacesResponse, error) {
 	m.ctrl.T.Helper()
@@ -103,137 +123,697 @@ func (m *MockWorkspaceManagerClient) GetWorkspaces(arg0 context.Context, arg1 *a
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "GetWorkspaces", varargs...)
-	ret0, _ := ret[0].(*api.GetWorkspacesResponse)
+	ret := m.ctrl.Call(m, "GetWorkspaces", varargs...)
+	ret0, _ := ret[0].(*api.GetWorkspacesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaces indicates an expected call of GetWorkspaces
+func (mr *MockWorkspaceManagerClientMockRecorder) GetWorkspaces(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaces", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).GetWorkspaces), varargs...)
+}
+
+// MarkActive mocks base method
+func (m *MockWorkspaceManagerClient) MarkActive(arg0 context.Context, arg1 *api.MarkActiveRequest, arg2 ...grpc.CallOption) (*api.MarkActiveResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MarkActive", varargs...)
+	ret0, _ := ret[0].(*api.MarkActiveResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkActive indicates an expected call of MarkActive
+func (mr *MockWorkspaceManagerClientMockRecorder) MarkActive(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkActive", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).MarkActive), varargs...)
+}
+
+// SetMaintenance mocks base method
+func (m *MockWorkspaceManagerClient) SetMaintenance(arg0 context.Context, arg1 *api.SetMaintenanceRequest, arg2 ...grpc.CallOption) (*api.SetMaintenanceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetMaintenance", varargs...)
+	ret0, _ := ret[0].(*api.SetMaintenanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMaintenance indicates an expected call of SetMaintenance
+func (mr *MockWorkspaceManagerClientMockRecorder) SetMaintenance(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenance", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).SetMaintenance), varargs...)
+}
+
+// SetTimeout mocks base method
+func (m *MockWorkspaceManagerClient) SetTimeout(arg0 context.Context, arg1 *api.SetTimeoutRequest, arg2 ...grpc.CallOption) (*api.SetTimeoutResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetTimeout", varargs...)
+	ret0, _ := ret[0].(*api.SetTimeoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTimeout indicates an expected call of SetTimeout
+func (mr *MockWorkspaceManagerClientMockRecorder) SetTimeout(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimeout", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).SetTimeout), varargs...)
+}
+
+// StartWorkspace mocks base method
+func (m *MockWorkspaceManagerClient) StartWorkspace(arg0 context.Context, arg1 *api.StartWorkspaceRequest, arg2 ...grpc.CallOption) (*api.StartWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartWorkspace", varargs...)
+	ret0, _ := ret[0].(*api.StartWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartWorkspace indicates an expected call of StartWorkspace
+func (mr *MockWorkspaceManagerClientMockRecorder) StartWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).StartWorkspace), varargs...)
+}
+
+// StartWorkspaceGroup mocks base method
+func (m *MockWorkspaceManagerClient) StartWorkspaceGroup(arg0 context.Context, arg1 *api.StartWorkspaceGroupRequest, arg2 ...grpc.CallOption) (*api.StartWorkspaceGroupResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartWorkspaceGroup", varargs...)
+	ret0, _ := ret[0].(*api.StartWorkspaceGroupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartWorkspaceGroup indicates an expected call of StartWorkspaceGroup
+func (mr *MockWorkspaceManagerClientMockRecorder) StartWorkspaceGroup(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartWorkspaceGroup", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).StartWorkspaceGroup), varargs...)
+}
+
+// StopWorkspace mocks base method
+func (m *MockWorkspaceManagerClient) StopWorkspace(arg0 context.Context, arg1 *api.StopWorkspaceRequest, arg2 ...grpc.CallOption) (*api.StopWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StopWorkspace", varargs...)
+	ret0, _ := ret[0].(*api.StopWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopWorkspace indicates an expected call of StopWorkspace
+func (mr *MockWorkspaceManagerClientMockRecorder) StopWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).StopWorkspace), varargs...)
+}
+
+// StopWorkspaceGroup mocks base method
+func (m *MockWorkspaceManagerClient) StopWorkspaceGroup(arg0 context.Context, arg1 *api.StopWorkspaceGroupRequest, arg2 ...grpc.CallOption) (*api.StopWorkspaceGroupResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StopWorkspaceGroup", varargs...)
+	ret0, _ := ret[0].(*api.StopWorkspaceGroupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopWorkspaceGroup indicates an expected call of StopWorkspaceGroup
+func (mr *MockWorkspaceManagerClientMockRecorder) StopWorkspaceGroup(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopWorkspaceGroup", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).StopWorkspaceGroup), varargs...)
+}
+
+// Subscribe mocks base method
+func (m *MockWorkspaceManagerClient) Subscribe(arg0 context.Context, arg1 *api.SubscribeRequest, arg2 ...grpc.CallOption) (api.WorkspaceManager_SubscribeClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Subscribe", varargs...)
+	ret0, _ := ret[0].(api.WorkspaceManager_SubscribeClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe
+func (mr *MockWorkspaceManagerClientMockRecorder) Subscribe(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).Subscribe), varargs...)
+}
+
+// TakeSnapshot mocks base method
+func (m *MockWorkspaceManagerClient) TakeSnapshot(arg0 context.Context, arg1 *api.TakeSnapshotRequest, arg2 ...grpc.CallOption) (*api.TakeSnapshotResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TakeSnapshot", varargs...)
+	ret0, _ := ret[0].(*api.TakeSnapshotResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeSnapshot indicates an expected call of TakeSnapshot
+func (mr *MockWorkspaceManagerClientMockRecorder) TakeSnapshot(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshot", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).TakeSnapshot), varargs...)
+}
+
+// ValidatePodTemplate mocks base method
+func (m *MockWorkspaceManagerClient) ValidatePodTemplate(arg0 context.Context, arg1 *api.ValidatePodTemplateRequest, arg2 ...grpc.CallOption) (*api.ValidatePodTemplateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ValidatePodTemplate", varargs...)
+	ret0, _ := ret[0].(*api.ValidatePodTemplateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidatePodTemplate indicates an expected call of ValidatePodTemplate
+func (mr *MockWorkspaceManagerClientMockRecorder) ValidatePodTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePodTemplate", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ValidatePodTemplate), varargs...)
+}
+
+// ReportProxyActivity mocks base method
+func (m *MockWorkspaceManagerClient) ReportProxyActivity(arg0 context.Context, arg1 *api.ReportProxyActivityRequest, arg2 ...grpc.CallOption) (*api.ReportProxyActivityResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReportProxyActivity", varargs...)
+	ret0, _ := ret[0].(*api.ReportProxyActivityResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportProxyActivity indicates an expected call of ReportProxyActivity
+func (mr *MockWorkspaceManagerClientMockRecorder) ReportProxyActivity(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportProxyActivity", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ReportProxyActivity), varargs...)
+}
+
+// SubscribeAccounting mocks base method
+func (m *MockWorkspaceManagerClient) SubscribeAccounting(arg0 context.Context, arg1 *api.SubscribeAccountingRequest, arg2 ...grpc.CallOption) (api.WorkspaceManager_SubscribeAccountingClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SubscribeAccounting", varargs...)
+	ret0, _ := ret[0].(api.WorkspaceManager_SubscribeAccountingClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeAccounting indicates an expected call of SubscribeAccounting
+func (mr *MockWorkspaceManagerClientMockRecorder) SubscribeAccounting(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeAccounting", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).SubscribeAccounting), varargs...)
+}
+
+// AckAccounting mocks base method
+func (m *MockWorkspaceManagerClient) AckAccounting(arg0 context.Context, arg1 *api.AckAccountingRequest, arg2 ...grpc.CallOption) (*api.AckAccountingResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AckAccounting", varargs...)
+	ret0, _ := ret[0].(*api.AckAccountingResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AckAccounting indicates an expected call of AckAccounting
+func (mr *MockWorkspaceManagerClientMockRecorder) AckAccounting(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckAccounting", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).AckAccounting), varargs...)
+}
+
+// ScheduleStop mocks base method
+func (m *MockWorkspaceManagerClient) ScheduleStop(arg0 context.Context, arg1 *api.ScheduleStopRequest, arg2 ...grpc.CallOption) (*api.ScheduleStopResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScheduleStop", varargs...)
+	ret0, _ := ret[0].(*api.ScheduleStopResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleStop indicates an expected call of ScheduleStop
+func (mr *MockWorkspaceManagerClientMockRecorder) ScheduleStop(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleStop", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ScheduleStop), varargs...)
+}
+
+// GenerateWorkspaceID mocks base method
+func (m *MockWorkspaceManagerClient) GenerateWorkspaceID(arg0 context.Context, arg1 *api.GenerateWorkspaceIDRequest, arg2 ...grpc.CallOption) (*api.GenerateWorkspaceIDResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GenerateWorkspaceID", varargs...)
+	ret0, _ := ret[0].(*api.GenerateWorkspaceIDResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateWorkspaceID indicates an expected call of GenerateWorkspaceID
+func (mr *MockWorkspaceManagerClientMockRecorder) GenerateWorkspaceID(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateWorkspaceID", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).GenerateWorkspaceID), varargs...)
+}
+
+// ExportState mocks base method
+func (m *MockWorkspaceManagerClient) ExportState(arg0 context.Context, arg1 *api.ExportStateRequest, arg2 ...grpc.CallOption) (*api.ExportStateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportState", varargs...)
+	ret0, _ := ret[0].(*api.ExportStateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportState indicates an expected call of ExportState
+func (mr *MockWorkspaceManagerClientMockRecorder) ExportState(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportState", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ExportState), varargs...)
+}
+
+// ImportState mocks base method
+func (m *MockWorkspaceManagerClient) ImportState(arg0 context.Context, arg1 *api.ImportStateRequest, arg2 ...grpc.CallOption) (*api.ImportStateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ImportState", varargs...)
+	ret0, _ := ret[0].(*api.ImportStateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportState indicates an expected call of ImportState
+func (mr *MockWorkspaceManagerClientMockRecorder) ImportState(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportState", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ImportState), varargs...)
+}
+
+// DescribeArchival mocks base method
+func (m *MockWorkspaceManagerClient) DescribeArchival(arg0 context.Context, arg1 *api.DescribeArchivalRequest, arg2 ...grpc.CallOption) (*api.DescribeArchivalResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeArchival", varargs...)
+	ret0, _ := ret[0].(*api.DescribeArchivalResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeArchival indicates an expected call of DescribeArchival
+func (mr *MockWorkspaceManagerClientMockRecorder) DescribeArchival(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeArchival", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DescribeArchival), varargs...)
+}
+
+// UnarchiveWorkspace mocks base method
+func (m *MockWorkspaceManagerClient) UnarchiveWorkspace(arg0 context.Context, arg1 *api.UnarchiveWorkspaceRequest, arg2 ...grpc.CallOption) (*api.UnarchiveWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UnarchiveWorkspace", varargs...)
+	ret0, _ := ret[0].(*api.UnarchiveWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveWorkspace indicates an expected call of UnarchiveWorkspace
+func (mr *MockWorkspaceManagerClientMockRecorder) UnarchiveWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).UnarchiveWorkspace), varargs...)
+}
+
+// DeleteWorkspace mocks base method
+func (m *MockWorkspaceManagerClient) DeleteWorkspace(arg0 context.Context, arg1 *api.DeleteWorkspaceRequest, arg2 ...grpc.CallOption) (*api.DeleteWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteWorkspace", varargs...)
+	ret0, _ := ret[0].(*api.DeleteWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWorkspace indicates an expected call of DeleteWorkspace
+func (mr *MockWorkspaceManagerClientMockRecorder) DeleteWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DeleteWorkspace), varargs...)
+}
+
+// RestoreDeletedWorkspace mocks base method
+func (m *MockWorkspaceManagerClient) RestoreDeletedWorkspace(arg0 context.Context, arg1 *api.RestoreDeletedWorkspaceRequest, arg2 ...grpc.CallOption) (*api.RestoreDeletedWorkspaceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreDeletedWorkspace", varargs...)
+	ret0, _ := ret[0].(*api.RestoreDeletedWorkspaceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreDeletedWorkspace indicates an expected call of RestoreDeletedWorkspace
+func (mr *MockWorkspaceManagerClientMockRecorder) RestoreDeletedWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDeletedWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).RestoreDeletedWorkspace), varargs...)
+}
+
+// DescribeDeletedWorkspaces mocks base method
+func (m *MockWorkspaceManagerClient) DescribeDeletedWorkspaces(arg0 context.Context, arg1 *api.DescribeDeletedWorkspacesRequest, arg2 ...grpc.CallOption) (*api.DescribeDeletedWorkspacesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDeletedWorkspaces", varargs...)
+	ret0, _ := ret[0].(*api.DescribeDeletedWorkspacesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDeletedWorkspaces indicates an expected call of DescribeDeletedWorkspaces
+func (mr *MockWorkspaceManagerClientMockRecorder) DescribeDeletedWorkspaces(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeletedWorkspaces", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DescribeDeletedWorkspaces), varargs...)
+}
+
+// CreateWorkspaceTemplate mocks base method
+func (m *MockWorkspaceManagerClient) CreateWorkspaceTemplate(arg0 context.Context, arg1 *api.CreateWorkspaceTemplateRequest, arg2 ...grpc.CallOption) (*api.CreateWorkspaceTemplateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateWorkspaceTemplate", varargs...)
+	ret0, _ := ret[0].(*api.CreateWorkspaceTemplateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspaceTemplate indicates an expected call of CreateWorkspaceTemplate
+func (mr *MockWorkspaceManagerClientMockRecorder) CreateWorkspaceTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspaceTemplate", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).CreateWorkspaceTemplate), varargs...)
+}
+
+// UpdateWorkspaceTemplate mocks base method
+func (m *MockWorkspaceManagerClient) UpdateWorkspaceTemplate(arg0 context.Context, arg1 *api.UpdateWorkspaceTemplateRequest, arg2 ...grpc.CallOption) (*api.UpdateWorkspaceTemplateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateWorkspaceTemplate", varargs...)
+	ret0, _ := ret[0].(*api.UpdateWorkspaceTemplateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceTemplate indicates an expected call of UpdateWorkspaceTemplate
+func (mr *MockWorkspaceManagerClientMockRecorder) UpdateWorkspaceTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceTemplate", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).UpdateWorkspaceTemplate), varargs...)
+}
+
+// GetWorkspaceTemplate mocks base method
+func (m *MockWorkspaceManagerClient) GetWorkspaceTemplate(arg0 context.Context, arg1 *api.GetWorkspaceTemplateRequest, arg2 ...grpc.CallOption) (*api.GetWorkspaceTemplateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetWorkspaceTemplate", varargs...)
+	ret0, _ := ret[0].(*api.GetWorkspaceTemplateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceTemplate indicates an expected call of GetWorkspaceTemplate
+func (mr *MockWorkspaceManagerClientMockRecorder) GetWorkspaceTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceTemplate", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).GetWorkspaceTemplate), varargs...)
+}
+
+// ListWorkspaceTemplates mocks base method
+func (m *MockWorkspaceManagerClient) ListWorkspaceTemplates(arg0 context.Context, arg1 *api.ListWorkspaceTemplatesRequest, arg2 ...grpc.CallOption) (*api.ListWorkspaceTemplatesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListWorkspaceTemplates", varargs...)
+	ret0, _ := ret[0].(*api.ListWorkspaceTemplatesResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetWorkspaces indicates an expected call of GetWorkspaces
-func (mr *MockWorkspaceManagerClientMockRecorder) GetWorkspaces(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// ListWorkspaceTemplates indicates an expected call of ListWorkspaceTemplates
+func (mr *MockWorkspaceManagerClientMockRecorder) ListWorkspaceTemplates(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaces", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).GetWorkspaces), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceTemplates", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ListWorkspaceTemplates), varargs...)
 }
 
-// MarkActive mocks base method
-func (m *MockWorkspaceManagerClient) MarkActive(arg0 context.Context, arg1 *api.MarkActiveRequest, arg2 ...grpc.CallOption) (*api.MarkActiveResponse, error) {
+// DeleteWorkspaceTemplate mocks base method
+func (m *MockWorkspaceManagerClient) DeleteWorkspaceTemplate(arg0 context.Context, arg1 *api.DeleteWorkspaceTemplateRequest, arg2 ...grpc.CallOption) (*api.DeleteWorkspaceTemplateResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "MarkActive", varargs...)
-	ret0, _ := ret[0].(*api.MarkActiveResponse)
+	ret := m.ctrl.Call(m, "DeleteWorkspaceTemplate", varargs...)
+	ret0, _ := ret[0].(*api.DeleteWorkspaceTemplateResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MarkActive indicates an expected call of MarkActive
-func (mr *MockWorkspaceManagerClientMockRecorder) MarkActive(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// DeleteWorkspaceTemplate indicates an expected call of DeleteWorkspaceTemplate
+func (mr *MockWorkspaceManagerClientMockRecorder) DeleteWorkspaceTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkActive", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).MarkActive), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceTemplate", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DeleteWorkspaceTemplate), varargs...)
 }
 
-// SetTimeout mocks base method
-func (m *MockWorkspaceManagerClient) SetTimeout(arg0 context.Context, arg1 *api.SetTimeoutRequest, arg2 ...grpc.CallOption) (*api.SetTimeoutResponse, error) {
+// ResetIDEPin mocks base method
+func (m *MockWorkspaceManagerClient) ResetIDEPin(arg0 context.Context, arg1 *api.ResetIDEPinRequest, arg2 ...grpc.CallOption) (*api.ResetIDEPinResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "SetTimeout", varargs...)
-	ret0, _ := ret[0].(*api.SetTimeoutResponse)
+	ret := m.ctrl.Call(m, "ResetIDEPin", varargs...)
+	ret0, _ := ret[0].(*api.ResetIDEPinResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SetTimeout indicates an expected call of SetTimeout
-func (mr *MockWorkspaceManagerClientMockRecorder) SetTimeout(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// ResetIDEPin indicates an expected call of ResetIDEPin
+func (mr *MockWorkspaceManagerClientMockRecorder) ResetIDEPin(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimeout", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).SetTimeout), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetIDEPin", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).ResetIDEPin), varargs...)
 }
 
-// StartWorkspace mocks base method
-func (m *MockWorkspaceManagerClient) StartWorkspace(arg0 context.Context, arg1 *api.StartWorkspaceRequest, arg2 ...grpc.CallOption) (*api.StartWorkspaceResponse, error) {
+// StartBulkOperation mocks base method
+func (m *MockWorkspaceManagerClient) StartBulkOperation(arg0 context.Context, arg1 *api.StartBulkOperationRequest, arg2 ...grpc.CallOption) (*api.StartBulkOperationResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "StartWorkspace", varargs...)
-	ret0, _ := ret[0].(*api.StartWorkspaceResponse)
+	ret := m.ctrl.Call(m, "StartBulkOperation", varargs...)
+	ret0, _ := ret[0].(*api.StartBulkOperationResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// StartWorkspace indicates an expected call of StartWorkspace
-func (mr *MockWorkspaceManagerClientMockRecorder) StartWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// StartBulkOperation indicates an expected call of StartBulkOperation
+func (mr *MockWorkspaceManagerClientMockRecorder) StartBulkOperation(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).StartWorkspace), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartBulkOperation", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).StartBulkOperation), varargs...)
 }
 
-// StopWorkspace mocks base method
-func (m *MockWorkspaceManagerClient) StopWorkspace(arg0 context.Context, arg1 *api.StopWorkspaceRequest, arg2 ...grpc.CallOption) (*api.StopWorkspaceResponse, error) {
+// DescribeBulkOperation mocks base method
+func (m *MockWorkspaceManagerClient) DescribeBulkOperation(arg0 context.Context, arg1 *api.DescribeBulkOperationRequest, arg2 ...grpc.CallOption) (*api.DescribeBulkOperationResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "StopWorkspace", varargs...)
-	ret0, _ := ret[0].(*api.StopWorkspaceResponse)
+	ret := m.ctrl.Call(m, "DescribeBulkOperation", varargs...)
+	ret0, _ := ret[0].(*api.DescribeBulkOperationResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// StopWorkspace indicates an expected call of StopWorkspace
-func (mr *MockWorkspaceManagerClientMockRecorder) StopWorkspace(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// DescribeBulkOperation indicates an expected call of DescribeBulkOperation
+func (mr *MockWorkspaceManagerClientMockRecorder) DescribeBulkOperation(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopWorkspace", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).StopWorkspace), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeBulkOperation", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).DescribeBulkOperation), varargs...)
 }
 
-// Subscribe mocks base method
-func (m *MockWorkspaceManagerClient) Subscribe(arg0 context.Context, arg1 *api.SubscribeRequest, arg2 ...grpc.CallOption) (api.WorkspaceManager_SubscribeClient, error) {
+// WatchBulkOperation mocks base method
+func (m *MockWorkspaceManagerClient) WatchBulkOperation(arg0 context.Context, arg1 *api.WatchBulkOperationRequest, arg2 ...grpc.CallOption) (api.WorkspaceManager_WatchBulkOperationClient, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Subscribe", varargs...)
-	ret0, _ := ret[0].(api.WorkspaceManager_SubscribeClient)
+	ret := m.ctrl.Call(m, "WatchBulkOperation", varargs...)
+	ret0, _ := ret[0].(api.WorkspaceManager_WatchBulkOperationClient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Subscribe indicates an expected call of Subscribe
-func (mr *MockWorkspaceManagerClientMockRecorder) Subscribe(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// WatchBulkOperation indicates an expected call of WatchBulkOperation
+func (mr *MockWorkspaceManagerClientMockRecorder) WatchBulkOperation(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).Subscribe), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchBulkOperation", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).WatchBulkOperation), varargs...)
 }
 
-// TakeSnapshot mocks base method
-func (m *MockWorkspaceManagerClient) TakeSnapshot(arg0 context.Context, arg1 *api.TakeSnapshotRequest, arg2 ...grpc.CallOption) (*api.TakeSnapshotResponse, error) {
+// CancelBulkOperation mocks base method
+func (m *MockWorkspaceManagerClient) CancelBulkOperation(arg0 context.Context, arg1 *api.CancelBulkOperationRequest, arg2 ...grpc.CallOption) (*api.CancelBulkOperationResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "TakeSnapshot", varargs...)
-	ret0, _ := ret[0].(*api.TakeSnapshotResponse)
+	ret := m.ctrl.Call(m, "CancelBulkOperation", varargs...)
+	ret0, _ := ret[0].(*api.CancelBulkOperationResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TakeSnapshot indicates an expected call of TakeSnapshot
-func (mr *MockWorkspaceManagerClientMockRecorder) TakeSnapshot(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// CancelBulkOperation indicates an expected call of CancelBulkOperation
+func (mr *MockWorkspaceManagerClientMockRecorder) CancelBulkOperation(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshot", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).TakeSnapshot), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBulkOperation", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).CancelBulkOperation), varargs...)
+}
+
+// QueryAuditLog mocks base method
+func (m *MockWorkspaceManagerClient) QueryAuditLog(arg0 context.Context, arg1 *api.QueryAuditLogRequest, arg2 ...grpc.CallOption) (*api.QueryAuditLogResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryAuditLog", varargs...)
+	ret0, _ := ret[0].(*api.QueryAuditLogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryAuditLog indicates an expected call of QueryAuditLog
+func (mr *MockWorkspaceManagerClientMockRecorder) QueryAuditLog(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAuditLog", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).QueryAuditLog), varargs...)
+}
+
+// SubscribeAuditLog mocks base method
+func (m *MockWorkspaceManagerClient) SubscribeAuditLog(arg0 context.Context, arg1 *api.SubscribeAuditLogRequest, arg2 ...grpc.CallOption) (api.WorkspaceManager_SubscribeAuditLogClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SubscribeAuditLog", varargs...)
+	ret0, _ := ret[0].(api.WorkspaceManager_SubscribeAuditLogClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeAuditLog indicates an expected call of SubscribeAuditLog
+func (mr *MockWorkspaceManagerClientMockRecorder) SubscribeAuditLog(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeAuditLog", reflect.TypeOf((*MockWorkspaceManagerClient)(nil).SubscribeAuditLog), varargs...)
 }
 
 // MockWorkspaceManager_SubscribeClient is a mock of WorkspaceManager_SubscribeClient interface
@@ -358,3 +938,249 @@ func (mr *MockWorkspaceManager_SubscribeClientMockRecorder) Trailer() *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockWorkspaceManager_SubscribeClient)(nil).Trailer))
 }
+
+// MockWorkspaceManager_WatchBulkOperationClient is a mock of WorkspaceManager_WatchBulkOperationClient interface
+type MockWorkspaceManager_WatchBulkOperationClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkspaceManager_WatchBulkOperationClientMockRecorder
+}
+
+// MockWorkspaceManager_WatchBulkOperationClientMockRecorder is the mock recorder for MockWorkspaceManager_WatchBulkOperationClient
+type MockWorkspaceManager_WatchBulkOperationClientMockRecorder struct {
+	mock *MockWorkspaceManager_WatchBulkOperationClient
+}
+
+// NewMockWorkspaceManager_WatchBulkOperationClient creates a new mock instance
+func NewMockWorkspaceManager_WatchBulkOperationClient(ctrl *gomock.Controller) *MockWorkspaceManager_WatchBulkOperationClient {
+	mock := &MockWorkspaceManager_WatchBulkOperationClient{ctrl: ctrl}
+	mock.recorder = &MockWorkspaceManager_WatchBulkOperationClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWorkspaceManager_WatchBulkOperationClient) EXPECT() *MockWorkspaceManager_WatchBulkOperationClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method
+func (m *MockWorkspaceManager_WatchBulkOperationClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockWorkspaceManager_WatchBulkOperationClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockWorkspaceManager_WatchBulkOperationClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockWorkspaceManager_WatchBulkOperationClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockWorkspaceManager_WatchBulkOperationClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockWorkspaceManager_WatchBulkOperationClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockWorkspaceManager_WatchBulkOperationClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockWorkspaceManager_WatchBulkOperationClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockWorkspaceManager_WatchBulkOperationClient)(nil).Header))
+}
+
+// Recv mocks base method
+func (m *MockWorkspaceManager_WatchBulkOperationClient) Recv() (*api.BulkOperationUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*api.BulkOperationUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockWorkspaceManager_WatchBulkOperationClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockWorkspaceManager_WatchBulkOperationClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method
+func (m *MockWorkspaceManager_WatchBulkOperationClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockWorkspaceManager_WatchBulkOperationClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockWorkspaceManager_WatchBulkOperationClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockWorkspaceManager_WatchBulkOperationClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockWorkspaceManager_WatchBulkOperationClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockWorkspaceManager_WatchBulkOperationClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockWorkspaceManager_WatchBulkOperationClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockWorkspaceManager_WatchBulkOperationClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockWorkspaceManager_WatchBulkOperationClient)(nil).Trailer))
+}
+
+// MockWorkspaceManager_SubscribeAuditLogClient is a mock of WorkspaceManager_SubscribeAuditLogClient interface
+type MockWorkspaceManager_SubscribeAuditLogClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder
+}
+
+// MockWorkspaceManager_SubscribeAuditLogClientMockRecorder is the mock recorder for MockWorkspaceManager_SubscribeAuditLogClient
+type MockWorkspaceManager_SubscribeAuditLogClientMockRecorder struct {
+	mock *MockWorkspaceManager_SubscribeAuditLogClient
+}
+
+// NewMockWorkspaceManager_SubscribeAuditLogClient creates a new mock instance
+func NewMockWorkspaceManager_SubscribeAuditLogClient(ctrl *gomock.Controller) *MockWorkspaceManager_SubscribeAuditLogClient {
+	mock := &MockWorkspaceManager_SubscribeAuditLogClient{ctrl: ctrl}
+	mock.recorder = &MockWorkspaceManager_SubscribeAuditLogClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) EXPECT() *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockWorkspaceManager_SubscribeAuditLogClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockWorkspaceManager_SubscribeAuditLogClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockWorkspaceManager_SubscribeAuditLogClient)(nil).Header))
+}
+
+// Recv mocks base method
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) Recv() (*api.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*api.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockWorkspaceManager_SubscribeAuditLogClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockWorkspaceManager_SubscribeAuditLogClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockWorkspaceManager_SubscribeAuditLogClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method
+func (m *MockWorkspaceManager_SubscribeAuditLogClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockWorkspaceManager_SubscribeAuditLogClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockWorkspaceManager_SubscribeAuditLogClient)(nil).Trailer))
+}
@@ -20,6 +20,35 @@ interface IWorkspaceManagerService extends grpc.ServiceDefinition<grpc.UntypedSe
     controlPort: IWorkspaceManagerService_IControlPort;
     takeSnapshot: IWorkspaceManagerService_ITakeSnapshot;
     controlAdmission: IWorkspaceManagerService_IControlAdmission;
+    setMaintenance: IWorkspaceManagerService_ISetMaintenance;
+    startWorkspaceGroup: IWorkspaceManagerService_IStartWorkspaceGroup;
+    stopWorkspaceGroup: IWorkspaceManagerService_IStopWorkspaceGroup;
+    describeWorkspaceGroup: IWorkspaceManagerService_IDescribeWorkspaceGroup;
+    validatePodTemplate: IWorkspaceManagerService_IValidatePodTemplate;
+    reportProxyActivity: IWorkspaceManagerService_IReportProxyActivity;
+    subscribeAccounting: IWorkspaceManagerService_ISubscribeAccounting;
+    ackAccounting: IWorkspaceManagerService_IAckAccounting;
+    scheduleStop: IWorkspaceManagerService_IScheduleStop;
+    generateWorkspaceID: IWorkspaceManagerService_IGenerateWorkspaceID;
+    exportState: IWorkspaceManagerService_IExportState;
+    importState: IWorkspaceManagerService_IImportState;
+    describeArchival: IWorkspaceManagerService_IDescribeArchival;
+    unarchiveWorkspace: IWorkspaceManagerService_IUnarchiveWorkspace;
+    deleteWorkspace: IWorkspaceManagerService_IDeleteWorkspace;
+    restoreDeletedWorkspace: IWorkspaceManagerService_IRestoreDeletedWorkspace;
+    describeDeletedWorkspaces: IWorkspaceManagerService_IDescribeDeletedWorkspaces;
+    createWorkspaceTemplate: IWorkspaceManagerService_ICreateWorkspaceTemplate;
+    updateWorkspaceTemplate: IWorkspaceManagerService_IUpdateWorkspaceTemplate;
+    getWorkspaceTemplate: IWorkspaceManagerService_IGetWorkspaceTemplate;
+    listWorkspaceTemplates: IWorkspaceManagerService_IListWorkspaceTemplates;
+    deleteWorkspaceTemplate: IWorkspaceManagerService_IDeleteWorkspaceTemplate;
+    resetIDEPin: IWorkspaceManagerService_IResetIDEPin;
+    startBulkOperation: IWorkspaceManagerService_IStartBulkOperation;
+    describeBulkOperation: IWorkspaceManagerService_IDescribeBulkOperation;
+    watchBulkOperation: IWorkspaceManagerService_IWatchBulkOperation;
+    cancelBulkOperation: IWorkspaceManagerService_ICancelBulkOperation;
+    queryAuditLog: IWorkspaceManagerService_IQueryAuditLog;
+    subscribeAuditLog: IWorkspaceManagerService_ISubscribeAuditLog;
 }
 
 interface IWorkspaceManagerService_IGetWorkspaces extends grpc.MethodDefinition<core_pb.GetWorkspacesRequest, core_pb.GetWorkspacesResponse> {
@@ -112,6 +141,267 @@ interface IWorkspaceManagerService_IControlAdmission extends grpc.MethodDefiniti
     responseSerialize: grpc.serialize<core_pb.ControlAdmissionResponse>;
     responseDeserialize: grpc.deserialize<core_pb.ControlAdmissionResponse>;
 }
+interface IWorkspaceManagerService_ISetMaintenance extends grpc.MethodDefinition<core_pb.SetMaintenanceRequest, core_pb.SetMaintenanceResponse> {
+    path: "/wsman.WorkspaceManager/SetMaintenance";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.SetMaintenanceRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.SetMaintenanceRequest>;
+    responseSerialize: grpc.serialize<core_pb.SetMaintenanceResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.SetMaintenanceResponse>;
+}
+interface IWorkspaceManagerService_IStartWorkspaceGroup extends grpc.MethodDefinition<core_pb.StartWorkspaceGroupRequest, core_pb.StartWorkspaceGroupResponse> {
+    path: "/wsman.WorkspaceManager/StartWorkspaceGroup";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.StartWorkspaceGroupRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.StartWorkspaceGroupRequest>;
+    responseSerialize: grpc.serialize<core_pb.StartWorkspaceGroupResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.StartWorkspaceGroupResponse>;
+}
+interface IWorkspaceManagerService_IStopWorkspaceGroup extends grpc.MethodDefinition<core_pb.StopWorkspaceGroupRequest, core_pb.StopWorkspaceGroupResponse> {
+    path: "/wsman.WorkspaceManager/StopWorkspaceGroup";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.StopWorkspaceGroupRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.StopWorkspaceGroupRequest>;
+    responseSerialize: grpc.serialize<core_pb.StopWorkspaceGroupResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.StopWorkspaceGroupResponse>;
+}
+interface IWorkspaceManagerService_IDescribeWorkspaceGroup extends grpc.MethodDefinition<core_pb.DescribeWorkspaceGroupRequest, core_pb.DescribeWorkspaceGroupResponse> {
+    path: "/wsman.WorkspaceManager/DescribeWorkspaceGroup";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.DescribeWorkspaceGroupRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DescribeWorkspaceGroupRequest>;
+    responseSerialize: grpc.serialize<core_pb.DescribeWorkspaceGroupResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DescribeWorkspaceGroupResponse>;
+}
+interface IWorkspaceManagerService_IValidatePodTemplate extends grpc.MethodDefinition<core_pb.ValidatePodTemplateRequest, core_pb.ValidatePodTemplateResponse> {
+    path: "/wsman.WorkspaceManager/ValidatePodTemplate";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ValidatePodTemplateRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ValidatePodTemplateRequest>;
+    responseSerialize: grpc.serialize<core_pb.ValidatePodTemplateResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ValidatePodTemplateResponse>;
+}
+interface IWorkspaceManagerService_IReportProxyActivity extends grpc.MethodDefinition<core_pb.ReportProxyActivityRequest, core_pb.ReportProxyActivityResponse> {
+    path: "/wsman.WorkspaceManager/ReportProxyActivity";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ReportProxyActivityRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ReportProxyActivityRequest>;
+    responseSerialize: grpc.serialize<core_pb.ReportProxyActivityResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ReportProxyActivityResponse>;
+}
+interface IWorkspaceManagerService_ISubscribeAccounting extends grpc.MethodDefinition<core_pb.SubscribeAccountingRequest, core_pb.AccountingRecord> {
+    path: "/wsman.WorkspaceManager/SubscribeAccounting";
+    requestStream: false;
+    responseStream: true;
+    requestSerialize: grpc.serialize<core_pb.SubscribeAccountingRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.SubscribeAccountingRequest>;
+    responseSerialize: grpc.serialize<core_pb.AccountingRecord>;
+    responseDeserialize: grpc.deserialize<core_pb.AccountingRecord>;
+}
+interface IWorkspaceManagerService_IAckAccounting extends grpc.MethodDefinition<core_pb.AckAccountingRequest, core_pb.AckAccountingResponse> {
+    path: "/wsman.WorkspaceManager/AckAccounting";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.AckAccountingRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.AckAccountingRequest>;
+    responseSerialize: grpc.serialize<core_pb.AckAccountingResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.AckAccountingResponse>;
+}
+interface IWorkspaceManagerService_IScheduleStop extends grpc.MethodDefinition<core_pb.ScheduleStopRequest, core_pb.ScheduleStopResponse> {
+    path: "/wsman.WorkspaceManager/ScheduleStop";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ScheduleStopRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ScheduleStopRequest>;
+    responseSerialize: grpc.serialize<core_pb.ScheduleStopResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ScheduleStopResponse>;
+}
+interface IWorkspaceManagerService_IGenerateWorkspaceID extends grpc.MethodDefinition<core_pb.GenerateWorkspaceIDRequest, core_pb.GenerateWorkspaceIDResponse> {
+    path: "/wsman.WorkspaceManager/GenerateWorkspaceID";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.GenerateWorkspaceIDRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.GenerateWorkspaceIDRequest>;
+    responseSerialize: grpc.serialize<core_pb.GenerateWorkspaceIDResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.GenerateWorkspaceIDResponse>;
+}
+interface IWorkspaceManagerService_IExportState extends grpc.MethodDefinition<core_pb.ExportStateRequest, core_pb.ExportStateResponse> {
+    path: "/wsman.WorkspaceManager/ExportState";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ExportStateRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ExportStateRequest>;
+    responseSerialize: grpc.serialize<core_pb.ExportStateResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ExportStateResponse>;
+}
+interface IWorkspaceManagerService_IImportState extends grpc.MethodDefinition<core_pb.ImportStateRequest, core_pb.ImportStateResponse> {
+    path: "/wsman.WorkspaceManager/ImportState";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ImportStateRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ImportStateRequest>;
+    responseSerialize: grpc.serialize<core_pb.ImportStateResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ImportStateResponse>;
+}
+interface IWorkspaceManagerService_IDescribeArchival extends grpc.MethodDefinition<core_pb.DescribeArchivalRequest, core_pb.DescribeArchivalResponse> {
+    path: "/wsman.WorkspaceManager/DescribeArchival";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.DescribeArchivalRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DescribeArchivalRequest>;
+    responseSerialize: grpc.serialize<core_pb.DescribeArchivalResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DescribeArchivalResponse>;
+}
+interface IWorkspaceManagerService_IUnarchiveWorkspace extends grpc.MethodDefinition<core_pb.UnarchiveWorkspaceRequest, core_pb.UnarchiveWorkspaceResponse> {
+    path: "/wsman.WorkspaceManager/UnarchiveWorkspace";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.UnarchiveWorkspaceRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.UnarchiveWorkspaceRequest>;
+    responseSerialize: grpc.serialize<core_pb.UnarchiveWorkspaceResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.UnarchiveWorkspaceResponse>;
+}
+interface IWorkspaceManagerService_IDeleteWorkspace extends grpc.MethodDefinition<core_pb.DeleteWorkspaceRequest, core_pb.DeleteWorkspaceResponse> {
+    path: "/wsman.WorkspaceManager/DeleteWorkspace";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.DeleteWorkspaceRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DeleteWorkspaceRequest>;
+    responseSerialize: grpc.serialize<core_pb.DeleteWorkspaceResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DeleteWorkspaceResponse>;
+}
+interface IWorkspaceManagerService_IRestoreDeletedWorkspace extends grpc.MethodDefinition<core_pb.RestoreDeletedWorkspaceRequest, core_pb.RestoreDeletedWorkspaceResponse> {
+    path: "/wsman.WorkspaceManager/RestoreDeletedWorkspace";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.RestoreDeletedWorkspaceRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.RestoreDeletedWorkspaceRequest>;
+    responseSerialize: grpc.serialize<core_pb.RestoreDeletedWorkspaceResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.RestoreDeletedWorkspaceResponse>;
+}
+interface IWorkspaceManagerService_IDescribeDeletedWorkspaces extends grpc.MethodDefinition<core_pb.DescribeDeletedWorkspacesRequest, core_pb.DescribeDeletedWorkspacesResponse> {
+    path: "/wsman.WorkspaceManager/DescribeDeletedWorkspaces";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.DescribeDeletedWorkspacesRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DescribeDeletedWorkspacesRequest>;
+    responseSerialize: grpc.serialize<core_pb.DescribeDeletedWorkspacesResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DescribeDeletedWorkspacesResponse>;
+}
+interface IWorkspaceManagerService_ICreateWorkspaceTemplate extends grpc.MethodDefinition<core_pb.CreateWorkspaceTemplateRequest, core_pb.CreateWorkspaceTemplateResponse> {
+    path: "/wsman.WorkspaceManager/CreateWorkspaceTemplate";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.CreateWorkspaceTemplateRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.CreateWorkspaceTemplateRequest>;
+    responseSerialize: grpc.serialize<core_pb.CreateWorkspaceTemplateResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.CreateWorkspaceTemplateResponse>;
+}
+interface IWorkspaceManagerService_IUpdateWorkspaceTemplate extends grpc.MethodDefinition<core_pb.UpdateWorkspaceTemplateRequest, core_pb.UpdateWorkspaceTemplateResponse> {
+    path: "/wsman.WorkspaceManager/UpdateWorkspaceTemplate";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.UpdateWorkspaceTemplateRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.UpdateWorkspaceTemplateRequest>;
+    responseSerialize: grpc.serialize<core_pb.UpdateWorkspaceTemplateResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.UpdateWorkspaceTemplateResponse>;
+}
+interface IWorkspaceManagerService_IGetWorkspaceTemplate extends grpc.MethodDefinition<core_pb.GetWorkspaceTemplateRequest, core_pb.GetWorkspaceTemplateResponse> {
+    path: "/wsman.WorkspaceManager/GetWorkspaceTemplate";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.GetWorkspaceTemplateRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.GetWorkspaceTemplateRequest>;
+    responseSerialize: grpc.serialize<core_pb.GetWorkspaceTemplateResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.GetWorkspaceTemplateResponse>;
+}
+interface IWorkspaceManagerService_IListWorkspaceTemplates extends grpc.MethodDefinition<core_pb.ListWorkspaceTemplatesRequest, core_pb.ListWorkspaceTemplatesResponse> {
+    path: "/wsman.WorkspaceManager/ListWorkspaceTemplates";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ListWorkspaceTemplatesRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ListWorkspaceTemplatesRequest>;
+    responseSerialize: grpc.serialize<core_pb.ListWorkspaceTemplatesResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ListWorkspaceTemplatesResponse>;
+}
+interface IWorkspaceManagerService_IDeleteWorkspaceTemplate extends grpc.MethodDefinition<core_pb.DeleteWorkspaceTemplateRequest, core_pb.DeleteWorkspaceTemplateResponse> {
+    path: "/wsman.WorkspaceManager/DeleteWorkspaceTemplate";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.DeleteWorkspaceTemplateRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DeleteWorkspaceTemplateRequest>;
+    responseSerialize: grpc.serialize<core_pb.DeleteWorkspaceTemplateResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DeleteWorkspaceTemplateResponse>;
+}
+interface IWorkspaceManagerService_IResetIDEPin extends grpc.MethodDefinition<core_pb.ResetIDEPinRequest, core_pb.ResetIDEPinResponse> {
+    path: "/wsman.WorkspaceManager/ResetIDEPin";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.ResetIDEPinRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.ResetIDEPinRequest>;
+    responseSerialize: grpc.serialize<core_pb.ResetIDEPinResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.ResetIDEPinResponse>;
+}
+interface IWorkspaceManagerService_IStartBulkOperation extends grpc.MethodDefinition<core_pb.StartBulkOperationRequest, core_pb.StartBulkOperationResponse> {
+    path: "/wsman.WorkspaceManager/StartBulkOperation";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.StartBulkOperationRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.StartBulkOperationRequest>;
+    responseSerialize: grpc.serialize<core_pb.StartBulkOperationResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.StartBulkOperationResponse>;
+}
+interface IWorkspaceManagerService_IDescribeBulkOperation extends grpc.MethodDefinition<core_pb.DescribeBulkOperationRequest, core_pb.DescribeBulkOperationResponse> {
+    path: "/wsman.WorkspaceManager/DescribeBulkOperation";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.DescribeBulkOperationRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.DescribeBulkOperationRequest>;
+    responseSerialize: grpc.serialize<core_pb.DescribeBulkOperationResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.DescribeBulkOperationResponse>;
+}
+interface IWorkspaceManagerService_IWatchBulkOperation extends grpc.MethodDefinition<core_pb.WatchBulkOperationRequest, core_pb.BulkOperationUpdate> {
+    path: "/wsman.WorkspaceManager/WatchBulkOperation";
+    requestStream: false;
+    responseStream: true;
+    requestSerialize: grpc.serialize<core_pb.WatchBulkOperationRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.WatchBulkOperationRequest>;
+    responseSerialize: grpc.serialize<core_pb.BulkOperationUpdate>;
+    responseDeserialize: grpc.deserialize<core_pb.BulkOperationUpdate>;
+}
+interface IWorkspaceManagerService_ICancelBulkOperation extends grpc.MethodDefinition<core_pb.CancelBulkOperationRequest, core_pb.CancelBulkOperationResponse> {
+    path: "/wsman.WorkspaceManager/CancelBulkOperation";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.CancelBulkOperationRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.CancelBulkOperationRequest>;
+    responseSerialize: grpc.serialize<core_pb.CancelBulkOperationResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.CancelBulkOperationResponse>;
+}
+interface IWorkspaceManagerService_IQueryAuditLog extends grpc.MethodDefinition<core_pb.QueryAuditLogRequest, core_pb.QueryAuditLogResponse> {
+    path: "/wsman.WorkspaceManager/QueryAuditLog";
+    requestStream: false;
+    responseStream: false;
+    requestSerialize: grpc.serialize<core_pb.QueryAuditLogRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.QueryAuditLogRequest>;
+    responseSerialize: grpc.serialize<core_pb.QueryAuditLogResponse>;
+    responseDeserialize: grpc.deserialize<core_pb.QueryAuditLogResponse>;
+}
+interface IWorkspaceManagerService_ISubscribeAuditLog extends grpc.MethodDefinition<core_pb.SubscribeAuditLogRequest, core_pb.AuditRecord> {
+    path: "/wsman.WorkspaceManager/SubscribeAuditLog";
+    requestStream: false;
+    responseStream: true;
+    requestSerialize: grpc.serialize<core_pb.SubscribeAuditLogRequest>;
+    requestDeserialize: grpc.deserialize<core_pb.SubscribeAuditLogRequest>;
+    responseSerialize: grpc.serialize<core_pb.AuditRecord>;
+    responseDeserialize: grpc.deserialize<core_pb.AuditRecord>;
+}
 
 export const WorkspaceManagerService: IWorkspaceManagerService;
 
@@ -126,6 +416,35 @@ export interface IWorkspaceManagerServer {
     controlPort: grpc.handleUnaryCall<core_pb.ControlPortRequest, core_pb.ControlPortResponse>;
     takeSnapshot: grpc.handleUnaryCall<core_pb.TakeSnapshotRequest, core_pb.TakeSnapshotResponse>;
     controlAdmission: grpc.handleUnaryCall<core_pb.ControlAdmissionRequest, core_pb.ControlAdmissionResponse>;
+    setMaintenance: grpc.handleUnaryCall<core_pb.SetMaintenanceRequest, core_pb.SetMaintenanceResponse>;
+    startWorkspaceGroup: grpc.handleUnaryCall<core_pb.StartWorkspaceGroupRequest, core_pb.StartWorkspaceGroupResponse>;
+    stopWorkspaceGroup: grpc.handleUnaryCall<core_pb.StopWorkspaceGroupRequest, core_pb.StopWorkspaceGroupResponse>;
+    describeWorkspaceGroup: grpc.handleUnaryCall<core_pb.DescribeWorkspaceGroupRequest, core_pb.DescribeWorkspaceGroupResponse>;
+    validatePodTemplate: grpc.handleUnaryCall<core_pb.ValidatePodTemplateRequest, core_pb.ValidatePodTemplateResponse>;
+    reportProxyActivity: grpc.handleUnaryCall<core_pb.ReportProxyActivityRequest, core_pb.ReportProxyActivityResponse>;
+    subscribeAccounting: grpc.handleServerStreamingCall<core_pb.SubscribeAccountingRequest, core_pb.AccountingRecord>;
+    ackAccounting: grpc.handleUnaryCall<core_pb.AckAccountingRequest, core_pb.AckAccountingResponse>;
+    scheduleStop: grpc.handleUnaryCall<core_pb.ScheduleStopRequest, core_pb.ScheduleStopResponse>;
+    generateWorkspaceID: grpc.handleUnaryCall<core_pb.GenerateWorkspaceIDRequest, core_pb.GenerateWorkspaceIDResponse>;
+    exportState: grpc.handleUnaryCall<core_pb.ExportStateRequest, core_pb.ExportStateResponse>;
+    importState: grpc.handleUnaryCall<core_pb.ImportStateRequest, core_pb.ImportStateResponse>;
+    describeArchival: grpc.handleUnaryCall<core_pb.DescribeArchivalRequest, core_pb.DescribeArchivalResponse>;
+    unarchiveWorkspace: grpc.handleUnaryCall<core_pb.UnarchiveWorkspaceRequest, core_pb.UnarchiveWorkspaceResponse>;
+    deleteWorkspace: grpc.handleUnaryCall<core_pb.DeleteWorkspaceRequest, core_pb.DeleteWorkspaceResponse>;
+    restoreDeletedWorkspace: grpc.handleUnaryCall<core_pb.RestoreDeletedWorkspaceRequest, core_pb.RestoreDeletedWorkspaceResponse>;
+    describeDeletedWorkspaces: grpc.handleUnaryCall<core_pb.DescribeDeletedWorkspacesRequest, core_pb.DescribeDeletedWorkspacesResponse>;
+    createWorkspaceTemplate: grpc.handleUnaryCall<core_pb.CreateWorkspaceTemplateRequest, core_pb.CreateWorkspaceTemplateResponse>;
+    updateWorkspaceTemplate: grpc.handleUnaryCall<core_pb.UpdateWorkspaceTemplateRequest, core_pb.UpdateWorkspaceTemplateResponse>;
+    getWorkspaceTemplate: grpc.handleUnaryCall<core_pb.GetWorkspaceTemplateRequest, core_pb.GetWorkspaceTemplateResponse>;
+    listWorkspaceTemplates: grpc.handleUnaryCall<core_pb.ListWorkspaceTemplatesRequest, core_pb.ListWorkspaceTemplatesResponse>;
+    deleteWorkspaceTemplate: grpc.handleUnaryCall<core_pb.DeleteWorkspaceTemplateRequest, core_pb.DeleteWorkspaceTemplateResponse>;
+    resetIDEPin: grpc.handleUnaryCall<core_pb.ResetIDEPinRequest, core_pb.ResetIDEPinResponse>;
+    startBulkOperation: grpc.handleUnaryCall<core_pb.StartBulkOperationRequest, core_pb.StartBulkOperationResponse>;
+    describeBulkOperation: grpc.handleUnaryCall<core_pb.DescribeBulkOperationRequest, core_pb.DescribeBulkOperationResponse>;
+    watchBulkOperation: grpc.handleServerStreamingCall<core_pb.WatchBulkOperationRequest, core_pb.BulkOperationUpdate>;
+    cancelBulkOperation: grpc.handleUnaryCall<core_pb.CancelBulkOperationRequest, core_pb.CancelBulkOperationResponse>;
+    queryAuditLog: grpc.handleUnaryCall<core_pb.QueryAuditLogRequest, core_pb.QueryAuditLogResponse>;
+    subscribeAuditLog: grpc.handleServerStreamingCall<core_pb.SubscribeAuditLogRequest, core_pb.AuditRecord>;
 }
 
 export interface IWorkspaceManagerClient {
@@ -158,6 +477,90 @@ export interface IWorkspaceManagerClient {
     controlAdmission(request: core_pb.ControlAdmissionRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
+    setMaintenance(request: core_pb.SetMaintenanceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.SetMaintenanceResponse) => void): grpc.ClientUnaryCall;
+    setMaintenance(request: core_pb.SetMaintenanceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.SetMaintenanceResponse) => void): grpc.ClientUnaryCall;
+    setMaintenance(request: core_pb.SetMaintenanceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.SetMaintenanceResponse) => void): grpc.ClientUnaryCall;
+    startWorkspaceGroup(request: core_pb.StartWorkspaceGroupRequest, callback: (error: grpc.ServiceError | null, response: core_pb.StartWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    startWorkspaceGroup(request: core_pb.StartWorkspaceGroupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.StartWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    startWorkspaceGroup(request: core_pb.StartWorkspaceGroupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.StartWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    stopWorkspaceGroup(request: core_pb.StopWorkspaceGroupRequest, callback: (error: grpc.ServiceError | null, response: core_pb.StopWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    stopWorkspaceGroup(request: core_pb.StopWorkspaceGroupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.StopWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    stopWorkspaceGroup(request: core_pb.StopWorkspaceGroupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.StopWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    describeWorkspaceGroup(request: core_pb.DescribeWorkspaceGroupRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    describeWorkspaceGroup(request: core_pb.DescribeWorkspaceGroupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    describeWorkspaceGroup(request: core_pb.DescribeWorkspaceGroupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    validatePodTemplate(request: core_pb.ValidatePodTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ValidatePodTemplateResponse) => void): grpc.ClientUnaryCall;
+    validatePodTemplate(request: core_pb.ValidatePodTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ValidatePodTemplateResponse) => void): grpc.ClientUnaryCall;
+    validatePodTemplate(request: core_pb.ValidatePodTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ValidatePodTemplateResponse) => void): grpc.ClientUnaryCall;
+    reportProxyActivity(request: core_pb.ReportProxyActivityRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ReportProxyActivityResponse) => void): grpc.ClientUnaryCall;
+    reportProxyActivity(request: core_pb.ReportProxyActivityRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ReportProxyActivityResponse) => void): grpc.ClientUnaryCall;
+    reportProxyActivity(request: core_pb.ReportProxyActivityRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ReportProxyActivityResponse) => void): grpc.ClientUnaryCall;
+    subscribeAccounting(request: core_pb.SubscribeAccountingRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AccountingRecord>;
+    subscribeAccounting(request: core_pb.SubscribeAccountingRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AccountingRecord>;
+    ackAccounting(request: core_pb.AckAccountingRequest, callback: (error: grpc.ServiceError | null, response: core_pb.AckAccountingResponse) => void): grpc.ClientUnaryCall;
+    ackAccounting(request: core_pb.AckAccountingRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.AckAccountingResponse) => void): grpc.ClientUnaryCall;
+    ackAccounting(request: core_pb.AckAccountingRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.AckAccountingResponse) => void): grpc.ClientUnaryCall;
+    scheduleStop(request: core_pb.ScheduleStopRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ScheduleStopResponse) => void): grpc.ClientUnaryCall;
+    scheduleStop(request: core_pb.ScheduleStopRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ScheduleStopResponse) => void): grpc.ClientUnaryCall;
+    scheduleStop(request: core_pb.ScheduleStopRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ScheduleStopResponse) => void): grpc.ClientUnaryCall;
+    generateWorkspaceID(request: core_pb.GenerateWorkspaceIDRequest, callback: (error: grpc.ServiceError | null, response: core_pb.GenerateWorkspaceIDResponse) => void): grpc.ClientUnaryCall;
+    generateWorkspaceID(request: core_pb.GenerateWorkspaceIDRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.GenerateWorkspaceIDResponse) => void): grpc.ClientUnaryCall;
+    generateWorkspaceID(request: core_pb.GenerateWorkspaceIDRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.GenerateWorkspaceIDResponse) => void): grpc.ClientUnaryCall;
+    exportState(request: core_pb.ExportStateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ExportStateResponse) => void): grpc.ClientUnaryCall;
+    exportState(request: core_pb.ExportStateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ExportStateResponse) => void): grpc.ClientUnaryCall;
+    exportState(request: core_pb.ExportStateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ExportStateResponse) => void): grpc.ClientUnaryCall;
+    importState(request: core_pb.ImportStateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ImportStateResponse) => void): grpc.ClientUnaryCall;
+    importState(request: core_pb.ImportStateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ImportStateResponse) => void): grpc.ClientUnaryCall;
+    importState(request: core_pb.ImportStateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ImportStateResponse) => void): grpc.ClientUnaryCall;
+    describeArchival(request: core_pb.DescribeArchivalRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeArchivalResponse) => void): grpc.ClientUnaryCall;
+    describeArchival(request: core_pb.DescribeArchivalRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeArchivalResponse) => void): grpc.ClientUnaryCall;
+    describeArchival(request: core_pb.DescribeArchivalRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeArchivalResponse) => void): grpc.ClientUnaryCall;
+    unarchiveWorkspace(request: core_pb.UnarchiveWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.UnarchiveWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    unarchiveWorkspace(request: core_pb.UnarchiveWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.UnarchiveWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    unarchiveWorkspace(request: core_pb.UnarchiveWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.UnarchiveWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    deleteWorkspace(request: core_pb.DeleteWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    deleteWorkspace(request: core_pb.DeleteWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    deleteWorkspace(request: core_pb.DeleteWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    restoreDeletedWorkspace(request: core_pb.RestoreDeletedWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.RestoreDeletedWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    restoreDeletedWorkspace(request: core_pb.RestoreDeletedWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.RestoreDeletedWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    restoreDeletedWorkspace(request: core_pb.RestoreDeletedWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.RestoreDeletedWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    describeDeletedWorkspaces(request: core_pb.DescribeDeletedWorkspacesRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeDeletedWorkspacesResponse) => void): grpc.ClientUnaryCall;
+    describeDeletedWorkspaces(request: core_pb.DescribeDeletedWorkspacesRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeDeletedWorkspacesResponse) => void): grpc.ClientUnaryCall;
+    describeDeletedWorkspaces(request: core_pb.DescribeDeletedWorkspacesRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeDeletedWorkspacesResponse) => void): grpc.ClientUnaryCall;
+    createWorkspaceTemplate(request: core_pb.CreateWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.CreateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    createWorkspaceTemplate(request: core_pb.CreateWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.CreateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    createWorkspaceTemplate(request: core_pb.CreateWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.CreateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    updateWorkspaceTemplate(request: core_pb.UpdateWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.UpdateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    updateWorkspaceTemplate(request: core_pb.UpdateWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.UpdateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    updateWorkspaceTemplate(request: core_pb.UpdateWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.UpdateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    getWorkspaceTemplate(request: core_pb.GetWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.GetWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    getWorkspaceTemplate(request: core_pb.GetWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.GetWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    getWorkspaceTemplate(request: core_pb.GetWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.GetWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    listWorkspaceTemplates(request: core_pb.ListWorkspaceTemplatesRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ListWorkspaceTemplatesResponse) => void): grpc.ClientUnaryCall;
+    listWorkspaceTemplates(request: core_pb.ListWorkspaceTemplatesRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ListWorkspaceTemplatesResponse) => void): grpc.ClientUnaryCall;
+    listWorkspaceTemplates(request: core_pb.ListWorkspaceTemplatesRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ListWorkspaceTemplatesResponse) => void): grpc.ClientUnaryCall;
+    deleteWorkspaceTemplate(request: core_pb.DeleteWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    deleteWorkspaceTemplate(request: core_pb.DeleteWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    deleteWorkspaceTemplate(request: core_pb.DeleteWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    resetIDEPin(request: core_pb.ResetIDEPinRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ResetIDEPinResponse) => void): grpc.ClientUnaryCall;
+    resetIDEPin(request: core_pb.ResetIDEPinRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ResetIDEPinResponse) => void): grpc.ClientUnaryCall;
+    resetIDEPin(request: core_pb.ResetIDEPinRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ResetIDEPinResponse) => void): grpc.ClientUnaryCall;
+    startBulkOperation(request: core_pb.StartBulkOperationRequest, callback: (error: grpc.ServiceError | null, response: core_pb.StartBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    startBulkOperation(request: core_pb.StartBulkOperationRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.StartBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    startBulkOperation(request: core_pb.StartBulkOperationRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.StartBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    describeBulkOperation(request: core_pb.DescribeBulkOperationRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    describeBulkOperation(request: core_pb.DescribeBulkOperationRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    describeBulkOperation(request: core_pb.DescribeBulkOperationRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    watchBulkOperation(request: core_pb.WatchBulkOperationRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.BulkOperationUpdate>;
+    watchBulkOperation(request: core_pb.WatchBulkOperationRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.BulkOperationUpdate>;
+    cancelBulkOperation(request: core_pb.CancelBulkOperationRequest, callback: (error: grpc.ServiceError | null, response: core_pb.CancelBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    cancelBulkOperation(request: core_pb.CancelBulkOperationRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.CancelBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    cancelBulkOperation(request: core_pb.CancelBulkOperationRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.CancelBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    queryAuditLog(request: core_pb.QueryAuditLogRequest, callback: (error: grpc.ServiceError | null, response: core_pb.QueryAuditLogResponse) => void): grpc.ClientUnaryCall;
+    queryAuditLog(request: core_pb.QueryAuditLogRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.QueryAuditLogResponse) => void): grpc.ClientUnaryCall;
+    queryAuditLog(request: core_pb.QueryAuditLogRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.QueryAuditLogResponse) => void): grpc.ClientUnaryCall;
+    subscribeAuditLog(request: core_pb.SubscribeAuditLogRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AuditRecord>;
+    subscribeAuditLog(request: core_pb.SubscribeAuditLogRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AuditRecord>;
 }
 
 export class WorkspaceManagerClient extends grpc.Client implements IWorkspaceManagerClient {
@@ -191,4 +594,88 @@ export class WorkspaceManagerClient extends grpc.Client implements IWorkspaceMan
     public controlAdmission(request: core_pb.ControlAdmissionRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     public controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
     public controlAdmission(request: core_pb.ControlAdmissionRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ControlAdmissionResponse) => void): grpc.ClientUnaryCall;
+    public setMaintenance(request: core_pb.SetMaintenanceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.SetMaintenanceResponse) => void): grpc.ClientUnaryCall;
+    public setMaintenance(request: core_pb.SetMaintenanceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.SetMaintenanceResponse) => void): grpc.ClientUnaryCall;
+    public setMaintenance(request: core_pb.SetMaintenanceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.SetMaintenanceResponse) => void): grpc.ClientUnaryCall;
+    public startWorkspaceGroup(request: core_pb.StartWorkspaceGroupRequest, callback: (error: grpc.ServiceError | null, response: core_pb.StartWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public startWorkspaceGroup(request: core_pb.StartWorkspaceGroupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.StartWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public startWorkspaceGroup(request: core_pb.StartWorkspaceGroupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.StartWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public stopWorkspaceGroup(request: core_pb.StopWorkspaceGroupRequest, callback: (error: grpc.ServiceError | null, response: core_pb.StopWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public stopWorkspaceGroup(request: core_pb.StopWorkspaceGroupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.StopWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public stopWorkspaceGroup(request: core_pb.StopWorkspaceGroupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.StopWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public describeWorkspaceGroup(request: core_pb.DescribeWorkspaceGroupRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public describeWorkspaceGroup(request: core_pb.DescribeWorkspaceGroupRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public describeWorkspaceGroup(request: core_pb.DescribeWorkspaceGroupRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeWorkspaceGroupResponse) => void): grpc.ClientUnaryCall;
+    public validatePodTemplate(request: core_pb.ValidatePodTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ValidatePodTemplateResponse) => void): grpc.ClientUnaryCall;
+    public validatePodTemplate(request: core_pb.ValidatePodTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ValidatePodTemplateResponse) => void): grpc.ClientUnaryCall;
+    public validatePodTemplate(request: core_pb.ValidatePodTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ValidatePodTemplateResponse) => void): grpc.ClientUnaryCall;
+    public reportProxyActivity(request: core_pb.ReportProxyActivityRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ReportProxyActivityResponse) => void): grpc.ClientUnaryCall;
+    public reportProxyActivity(request: core_pb.ReportProxyActivityRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ReportProxyActivityResponse) => void): grpc.ClientUnaryCall;
+    public reportProxyActivity(request: core_pb.ReportProxyActivityRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ReportProxyActivityResponse) => void): grpc.ClientUnaryCall;
+    public subscribeAccounting(request: core_pb.SubscribeAccountingRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AccountingRecord>;
+    public subscribeAccounting(request: core_pb.SubscribeAccountingRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AccountingRecord>;
+    public ackAccounting(request: core_pb.AckAccountingRequest, callback: (error: grpc.ServiceError | null, response: core_pb.AckAccountingResponse) => void): grpc.ClientUnaryCall;
+    public ackAccounting(request: core_pb.AckAccountingRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.AckAccountingResponse) => void): grpc.ClientUnaryCall;
+    public ackAccounting(request: core_pb.AckAccountingRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.AckAccountingResponse) => void): grpc.ClientUnaryCall;
+    public scheduleStop(request: core_pb.ScheduleStopRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ScheduleStopResponse) => void): grpc.ClientUnaryCall;
+    public scheduleStop(request: core_pb.ScheduleStopRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ScheduleStopResponse) => void): grpc.ClientUnaryCall;
+    public scheduleStop(request: core_pb.ScheduleStopRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ScheduleStopResponse) => void): grpc.ClientUnaryCall;
+    public generateWorkspaceID(request: core_pb.GenerateWorkspaceIDRequest, callback: (error: grpc.ServiceError | null, response: core_pb.GenerateWorkspaceIDResponse) => void): grpc.ClientUnaryCall;
+    public generateWorkspaceID(request: core_pb.GenerateWorkspaceIDRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.GenerateWorkspaceIDResponse) => void): grpc.ClientUnaryCall;
+    public generateWorkspaceID(request: core_pb.GenerateWorkspaceIDRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.GenerateWorkspaceIDResponse) => void): grpc.ClientUnaryCall;
+    public exportState(request: core_pb.ExportStateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ExportStateResponse) => void): grpc.ClientUnaryCall;
+    public exportState(request: core_pb.ExportStateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ExportStateResponse) => void): grpc.ClientUnaryCall;
+    public exportState(request: core_pb.ExportStateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ExportStateResponse) => void): grpc.ClientUnaryCall;
+    public importState(request: core_pb.ImportStateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ImportStateResponse) => void): grpc.ClientUnaryCall;
+    public importState(request: core_pb.ImportStateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ImportStateResponse) => void): grpc.ClientUnaryCall;
+    public importState(request: core_pb.ImportStateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ImportStateResponse) => void): grpc.ClientUnaryCall;
+    public describeArchival(request: core_pb.DescribeArchivalRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeArchivalResponse) => void): grpc.ClientUnaryCall;
+    public describeArchival(request: core_pb.DescribeArchivalRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeArchivalResponse) => void): grpc.ClientUnaryCall;
+    public describeArchival(request: core_pb.DescribeArchivalRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeArchivalResponse) => void): grpc.ClientUnaryCall;
+    public unarchiveWorkspace(request: core_pb.UnarchiveWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.UnarchiveWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public unarchiveWorkspace(request: core_pb.UnarchiveWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.UnarchiveWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public unarchiveWorkspace(request: core_pb.UnarchiveWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.UnarchiveWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public deleteWorkspace(request: core_pb.DeleteWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public deleteWorkspace(request: core_pb.DeleteWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public deleteWorkspace(request: core_pb.DeleteWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public restoreDeletedWorkspace(request: core_pb.RestoreDeletedWorkspaceRequest, callback: (error: grpc.ServiceError | null, response: core_pb.RestoreDeletedWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public restoreDeletedWorkspace(request: core_pb.RestoreDeletedWorkspaceRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.RestoreDeletedWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public restoreDeletedWorkspace(request: core_pb.RestoreDeletedWorkspaceRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.RestoreDeletedWorkspaceResponse) => void): grpc.ClientUnaryCall;
+    public describeDeletedWorkspaces(request: core_pb.DescribeDeletedWorkspacesRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeDeletedWorkspacesResponse) => void): grpc.ClientUnaryCall;
+    public describeDeletedWorkspaces(request: core_pb.DescribeDeletedWorkspacesRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeDeletedWorkspacesResponse) => void): grpc.ClientUnaryCall;
+    public describeDeletedWorkspaces(request: core_pb.DescribeDeletedWorkspacesRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeDeletedWorkspacesResponse) => void): grpc.ClientUnaryCall;
+    public createWorkspaceTemplate(request: core_pb.CreateWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.CreateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public createWorkspaceTemplate(request: core_pb.CreateWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.CreateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public createWorkspaceTemplate(request: core_pb.CreateWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.CreateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public updateWorkspaceTemplate(request: core_pb.UpdateWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.UpdateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public updateWorkspaceTemplate(request: core_pb.UpdateWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.UpdateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public updateWorkspaceTemplate(request: core_pb.UpdateWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.UpdateWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public getWorkspaceTemplate(request: core_pb.GetWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.GetWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public getWorkspaceTemplate(request: core_pb.GetWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.GetWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public getWorkspaceTemplate(request: core_pb.GetWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.GetWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public listWorkspaceTemplates(request: core_pb.ListWorkspaceTemplatesRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ListWorkspaceTemplatesResponse) => void): grpc.ClientUnaryCall;
+    public listWorkspaceTemplates(request: core_pb.ListWorkspaceTemplatesRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ListWorkspaceTemplatesResponse) => void): grpc.ClientUnaryCall;
+    public listWorkspaceTemplates(request: core_pb.ListWorkspaceTemplatesRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ListWorkspaceTemplatesResponse) => void): grpc.ClientUnaryCall;
+    public deleteWorkspaceTemplate(request: core_pb.DeleteWorkspaceTemplateRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public deleteWorkspaceTemplate(request: core_pb.DeleteWorkspaceTemplateRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public deleteWorkspaceTemplate(request: core_pb.DeleteWorkspaceTemplateRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DeleteWorkspaceTemplateResponse) => void): grpc.ClientUnaryCall;
+    public resetIDEPin(request: core_pb.ResetIDEPinRequest, callback: (error: grpc.ServiceError | null, response: core_pb.ResetIDEPinResponse) => void): grpc.ClientUnaryCall;
+    public resetIDEPin(request: core_pb.ResetIDEPinRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.ResetIDEPinResponse) => void): grpc.ClientUnaryCall;
+    public resetIDEPin(request: core_pb.ResetIDEPinRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.ResetIDEPinResponse) => void): grpc.ClientUnaryCall;
+    public startBulkOperation(request: core_pb.StartBulkOperationRequest, callback: (error: grpc.ServiceError | null, response: core_pb.StartBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public startBulkOperation(request: core_pb.StartBulkOperationRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.StartBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public startBulkOperation(request: core_pb.StartBulkOperationRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.StartBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public describeBulkOperation(request: core_pb.DescribeBulkOperationRequest, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public describeBulkOperation(request: core_pb.DescribeBulkOperationRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public describeBulkOperation(request: core_pb.DescribeBulkOperationRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.DescribeBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public watchBulkOperation(request: core_pb.WatchBulkOperationRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.BulkOperationUpdate>;
+    public watchBulkOperation(request: core_pb.WatchBulkOperationRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.BulkOperationUpdate>;
+    public cancelBulkOperation(request: core_pb.CancelBulkOperationRequest, callback: (error: grpc.ServiceError | null, response: core_pb.CancelBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public cancelBulkOperation(request: core_pb.CancelBulkOperationRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.CancelBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public cancelBulkOperation(request: core_pb.CancelBulkOperationRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.CancelBulkOperationResponse) => void): grpc.ClientUnaryCall;
+    public queryAuditLog(request: core_pb.QueryAuditLogRequest, callback: (error: grpc.ServiceError | null, response: core_pb.QueryAuditLogResponse) => void): grpc.ClientUnaryCall;
+    public queryAuditLog(request: core_pb.QueryAuditLogRequest, metadata: grpc.Metadata, callback: (error: grpc.ServiceError | null, response: core_pb.QueryAuditLogResponse) => void): grpc.ClientUnaryCall;
+    public queryAuditLog(request: core_pb.QueryAuditLogRequest, metadata: grpc.Metadata, options: Partial<grpc.CallOptions>, callback: (error: grpc.ServiceError | null, response: core_pb.QueryAuditLogResponse) => void): grpc.ClientUnaryCall;
+    public subscribeAuditLog(request: core_pb.SubscribeAuditLogRequest, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AuditRecord>;
+    public subscribeAuditLog(request: core_pb.SubscribeAuditLogRequest, metadata?: grpc.Metadata, options?: Partial<grpc.CallOptions>): grpc.ClientReadableStream<core_pb.AuditRecord>;
 }
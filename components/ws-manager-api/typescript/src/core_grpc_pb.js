@@ -6,6 +6,83 @@ var core_pb = require('./core_pb.js');
 var content$service$api_initializer_pb = require('@gitpod/content-service/lib');
 var google_protobuf_timestamp_pb = require('google-protobuf/google/protobuf/timestamp_pb.js');
 
+function serialize_wsman_AccountingRecord(arg) {
+  if (!(arg instanceof core_pb.AccountingRecord)) {
+    throw new Error('Expected argument of type wsman.AccountingRecord');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_AccountingRecord(buffer_arg) {
+  return core_pb.AccountingRecord.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_AckAccountingRequest(arg) {
+  if (!(arg instanceof core_pb.AckAccountingRequest)) {
+    throw new Error('Expected argument of type wsman.AckAccountingRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_AckAccountingRequest(buffer_arg) {
+  return core_pb.AckAccountingRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_AckAccountingResponse(arg) {
+  if (!(arg instanceof core_pb.AckAccountingResponse)) {
+    throw new Error('Expected argument of type wsman.AckAccountingResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_AckAccountingResponse(buffer_arg) {
+  return core_pb.AckAccountingResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_AuditRecord(arg) {
+  if (!(arg instanceof core_pb.AuditRecord)) {
+    throw new Error('Expected argument of type wsman.AuditRecord');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_AuditRecord(buffer_arg) {
+  return core_pb.AuditRecord.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_BulkOperationUpdate(arg) {
+  if (!(arg instanceof core_pb.BulkOperationUpdate)) {
+    throw new Error('Expected argument of type wsman.BulkOperationUpdate');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_BulkOperationUpdate(buffer_arg) {
+  return core_pb.BulkOperationUpdate.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_CancelBulkOperationRequest(arg) {
+  if (!(arg instanceof core_pb.CancelBulkOperationRequest)) {
+    throw new Error('Expected argument of type wsman.CancelBulkOperationRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_CancelBulkOperationRequest(buffer_arg) {
+  return core_pb.CancelBulkOperationRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_CancelBulkOperationResponse(arg) {
+  if (!(arg instanceof core_pb.CancelBulkOperationResponse)) {
+    throw new Error('Expected argument of type wsman.CancelBulkOperationResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_CancelBulkOperationResponse(buffer_arg) {
+  return core_pb.CancelBulkOperationResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_ControlAdmissionRequest(arg) {
   if (!(arg instanceof core_pb.ControlAdmissionRequest)) {
     throw new Error('Expected argument of type wsman.ControlAdmissionRequest');
@@ -50,6 +127,160 @@ function deserialize_wsman_ControlPortResponse(buffer_arg) {
   return core_pb.ControlPortResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_CreateWorkspaceTemplateRequest(arg) {
+  if (!(arg instanceof core_pb.CreateWorkspaceTemplateRequest)) {
+    throw new Error('Expected argument of type wsman.CreateWorkspaceTemplateRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_CreateWorkspaceTemplateRequest(buffer_arg) {
+  return core_pb.CreateWorkspaceTemplateRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_CreateWorkspaceTemplateResponse(arg) {
+  if (!(arg instanceof core_pb.CreateWorkspaceTemplateResponse)) {
+    throw new Error('Expected argument of type wsman.CreateWorkspaceTemplateResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_CreateWorkspaceTemplateResponse(buffer_arg) {
+  return core_pb.CreateWorkspaceTemplateResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DeleteWorkspaceRequest(arg) {
+  if (!(arg instanceof core_pb.DeleteWorkspaceRequest)) {
+    throw new Error('Expected argument of type wsman.DeleteWorkspaceRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DeleteWorkspaceRequest(buffer_arg) {
+  return core_pb.DeleteWorkspaceRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DeleteWorkspaceResponse(arg) {
+  if (!(arg instanceof core_pb.DeleteWorkspaceResponse)) {
+    throw new Error('Expected argument of type wsman.DeleteWorkspaceResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DeleteWorkspaceResponse(buffer_arg) {
+  return core_pb.DeleteWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DeleteWorkspaceTemplateRequest(arg) {
+  if (!(arg instanceof core_pb.DeleteWorkspaceTemplateRequest)) {
+    throw new Error('Expected argument of type wsman.DeleteWorkspaceTemplateRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DeleteWorkspaceTemplateRequest(buffer_arg) {
+  return core_pb.DeleteWorkspaceTemplateRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DeleteWorkspaceTemplateResponse(arg) {
+  if (!(arg instanceof core_pb.DeleteWorkspaceTemplateResponse)) {
+    throw new Error('Expected argument of type wsman.DeleteWorkspaceTemplateResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DeleteWorkspaceTemplateResponse(buffer_arg) {
+  return core_pb.DeleteWorkspaceTemplateResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeArchivalRequest(arg) {
+  if (!(arg instanceof core_pb.DescribeArchivalRequest)) {
+    throw new Error('Expected argument of type wsman.DescribeArchivalRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeArchivalRequest(buffer_arg) {
+  return core_pb.DescribeArchivalRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeArchivalResponse(arg) {
+  if (!(arg instanceof core_pb.DescribeArchivalResponse)) {
+    throw new Error('Expected argument of type wsman.DescribeArchivalResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeArchivalResponse(buffer_arg) {
+  return core_pb.DescribeArchivalResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeBulkOperationRequest(arg) {
+  if (!(arg instanceof core_pb.DescribeBulkOperationRequest)) {
+    throw new Error('Expected argument of type wsman.DescribeBulkOperationRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeBulkOperationRequest(buffer_arg) {
+  return core_pb.DescribeBulkOperationRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeBulkOperationResponse(arg) {
+  if (!(arg instanceof core_pb.DescribeBulkOperationResponse)) {
+    throw new Error('Expected argument of type wsman.DescribeBulkOperationResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeBulkOperationResponse(buffer_arg) {
+  return core_pb.DescribeBulkOperationResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeDeletedWorkspacesRequest(arg) {
+  if (!(arg instanceof core_pb.DescribeDeletedWorkspacesRequest)) {
+    throw new Error('Expected argument of type wsman.DescribeDeletedWorkspacesRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeDeletedWorkspacesRequest(buffer_arg) {
+  return core_pb.DescribeDeletedWorkspacesRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeDeletedWorkspacesResponse(arg) {
+  if (!(arg instanceof core_pb.DescribeDeletedWorkspacesResponse)) {
+    throw new Error('Expected argument of type wsman.DescribeDeletedWorkspacesResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeDeletedWorkspacesResponse(buffer_arg) {
+  return core_pb.DescribeDeletedWorkspacesResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeWorkspaceGroupRequest(arg) {
+  if (!(arg instanceof core_pb.DescribeWorkspaceGroupRequest)) {
+    throw new Error('Expected argument of type wsman.DescribeWorkspaceGroupRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeWorkspaceGroupRequest(buffer_arg) {
+  return core_pb.DescribeWorkspaceGroupRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_DescribeWorkspaceGroupResponse(arg) {
+  if (!(arg instanceof core_pb.DescribeWorkspaceGroupResponse)) {
+    throw new Error('Expected argument of type wsman.DescribeWorkspaceGroupResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_DescribeWorkspaceGroupResponse(buffer_arg) {
+  return core_pb.DescribeWorkspaceGroupResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_DescribeWorkspaceRequest(arg) {
   if (!(arg instanceof core_pb.DescribeWorkspaceRequest)) {
     throw new Error('Expected argument of type wsman.DescribeWorkspaceRequest');
@@ -72,6 +303,72 @@ function deserialize_wsman_DescribeWorkspaceResponse(buffer_arg) {
   return core_pb.DescribeWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_ExportStateRequest(arg) {
+  if (!(arg instanceof core_pb.ExportStateRequest)) {
+    throw new Error('Expected argument of type wsman.ExportStateRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ExportStateRequest(buffer_arg) {
+  return core_pb.ExportStateRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ExportStateResponse(arg) {
+  if (!(arg instanceof core_pb.ExportStateResponse)) {
+    throw new Error('Expected argument of type wsman.ExportStateResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ExportStateResponse(buffer_arg) {
+  return core_pb.ExportStateResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_GenerateWorkspaceIDRequest(arg) {
+  if (!(arg instanceof core_pb.GenerateWorkspaceIDRequest)) {
+    throw new Error('Expected argument of type wsman.GenerateWorkspaceIDRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_GenerateWorkspaceIDRequest(buffer_arg) {
+  return core_pb.GenerateWorkspaceIDRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_GenerateWorkspaceIDResponse(arg) {
+  if (!(arg instanceof core_pb.GenerateWorkspaceIDResponse)) {
+    throw new Error('Expected argument of type wsman.GenerateWorkspaceIDResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_GenerateWorkspaceIDResponse(buffer_arg) {
+  return core_pb.GenerateWorkspaceIDResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_GetWorkspaceTemplateRequest(arg) {
+  if (!(arg instanceof core_pb.GetWorkspaceTemplateRequest)) {
+    throw new Error('Expected argument of type wsman.GetWorkspaceTemplateRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_GetWorkspaceTemplateRequest(buffer_arg) {
+  return core_pb.GetWorkspaceTemplateRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_GetWorkspaceTemplateResponse(arg) {
+  if (!(arg instanceof core_pb.GetWorkspaceTemplateResponse)) {
+    throw new Error('Expected argument of type wsman.GetWorkspaceTemplateResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_GetWorkspaceTemplateResponse(buffer_arg) {
+  return core_pb.GetWorkspaceTemplateResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_GetWorkspacesRequest(arg) {
   if (!(arg instanceof core_pb.GetWorkspacesRequest)) {
     throw new Error('Expected argument of type wsman.GetWorkspacesRequest');
@@ -94,6 +391,50 @@ function deserialize_wsman_GetWorkspacesResponse(buffer_arg) {
   return core_pb.GetWorkspacesResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_ImportStateRequest(arg) {
+  if (!(arg instanceof core_pb.ImportStateRequest)) {
+    throw new Error('Expected argument of type wsman.ImportStateRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ImportStateRequest(buffer_arg) {
+  return core_pb.ImportStateRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ImportStateResponse(arg) {
+  if (!(arg instanceof core_pb.ImportStateResponse)) {
+    throw new Error('Expected argument of type wsman.ImportStateResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ImportStateResponse(buffer_arg) {
+  return core_pb.ImportStateResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ListWorkspaceTemplatesRequest(arg) {
+  if (!(arg instanceof core_pb.ListWorkspaceTemplatesRequest)) {
+    throw new Error('Expected argument of type wsman.ListWorkspaceTemplatesRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ListWorkspaceTemplatesRequest(buffer_arg) {
+  return core_pb.ListWorkspaceTemplatesRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ListWorkspaceTemplatesResponse(arg) {
+  if (!(arg instanceof core_pb.ListWorkspaceTemplatesResponse)) {
+    throw new Error('Expected argument of type wsman.ListWorkspaceTemplatesResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ListWorkspaceTemplatesResponse(buffer_arg) {
+  return core_pb.ListWorkspaceTemplatesResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_MarkActiveRequest(arg) {
   if (!(arg instanceof core_pb.MarkActiveRequest)) {
     throw new Error('Expected argument of type wsman.MarkActiveRequest');
@@ -116,6 +457,138 @@ function deserialize_wsman_MarkActiveResponse(buffer_arg) {
   return core_pb.MarkActiveResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_QueryAuditLogRequest(arg) {
+  if (!(arg instanceof core_pb.QueryAuditLogRequest)) {
+    throw new Error('Expected argument of type wsman.QueryAuditLogRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_QueryAuditLogRequest(buffer_arg) {
+  return core_pb.QueryAuditLogRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_QueryAuditLogResponse(arg) {
+  if (!(arg instanceof core_pb.QueryAuditLogResponse)) {
+    throw new Error('Expected argument of type wsman.QueryAuditLogResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_QueryAuditLogResponse(buffer_arg) {
+  return core_pb.QueryAuditLogResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ReportProxyActivityRequest(arg) {
+  if (!(arg instanceof core_pb.ReportProxyActivityRequest)) {
+    throw new Error('Expected argument of type wsman.ReportProxyActivityRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ReportProxyActivityRequest(buffer_arg) {
+  return core_pb.ReportProxyActivityRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ReportProxyActivityResponse(arg) {
+  if (!(arg instanceof core_pb.ReportProxyActivityResponse)) {
+    throw new Error('Expected argument of type wsman.ReportProxyActivityResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ReportProxyActivityResponse(buffer_arg) {
+  return core_pb.ReportProxyActivityResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ResetIDEPinRequest(arg) {
+  if (!(arg instanceof core_pb.ResetIDEPinRequest)) {
+    throw new Error('Expected argument of type wsman.ResetIDEPinRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ResetIDEPinRequest(buffer_arg) {
+  return core_pb.ResetIDEPinRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ResetIDEPinResponse(arg) {
+  if (!(arg instanceof core_pb.ResetIDEPinResponse)) {
+    throw new Error('Expected argument of type wsman.ResetIDEPinResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ResetIDEPinResponse(buffer_arg) {
+  return core_pb.ResetIDEPinResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_RestoreDeletedWorkspaceRequest(arg) {
+  if (!(arg instanceof core_pb.RestoreDeletedWorkspaceRequest)) {
+    throw new Error('Expected argument of type wsman.RestoreDeletedWorkspaceRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_RestoreDeletedWorkspaceRequest(buffer_arg) {
+  return core_pb.RestoreDeletedWorkspaceRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_RestoreDeletedWorkspaceResponse(arg) {
+  if (!(arg instanceof core_pb.RestoreDeletedWorkspaceResponse)) {
+    throw new Error('Expected argument of type wsman.RestoreDeletedWorkspaceResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_RestoreDeletedWorkspaceResponse(buffer_arg) {
+  return core_pb.RestoreDeletedWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ScheduleStopRequest(arg) {
+  if (!(arg instanceof core_pb.ScheduleStopRequest)) {
+    throw new Error('Expected argument of type wsman.ScheduleStopRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ScheduleStopRequest(buffer_arg) {
+  return core_pb.ScheduleStopRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ScheduleStopResponse(arg) {
+  if (!(arg instanceof core_pb.ScheduleStopResponse)) {
+    throw new Error('Expected argument of type wsman.ScheduleStopResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ScheduleStopResponse(buffer_arg) {
+  return core_pb.ScheduleStopResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_SetMaintenanceRequest(arg) {
+  if (!(arg instanceof core_pb.SetMaintenanceRequest)) {
+    throw new Error('Expected argument of type wsman.SetMaintenanceRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_SetMaintenanceRequest(buffer_arg) {
+  return core_pb.SetMaintenanceRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_SetMaintenanceResponse(arg) {
+  if (!(arg instanceof core_pb.SetMaintenanceResponse)) {
+    throw new Error('Expected argument of type wsman.SetMaintenanceResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_SetMaintenanceResponse(buffer_arg) {
+  return core_pb.SetMaintenanceResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_SetTimeoutRequest(arg) {
   if (!(arg instanceof core_pb.SetTimeoutRequest)) {
     throw new Error('Expected argument of type wsman.SetTimeoutRequest');
@@ -138,6 +611,50 @@ function deserialize_wsman_SetTimeoutResponse(buffer_arg) {
   return core_pb.SetTimeoutResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_StartBulkOperationRequest(arg) {
+  if (!(arg instanceof core_pb.StartBulkOperationRequest)) {
+    throw new Error('Expected argument of type wsman.StartBulkOperationRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_StartBulkOperationRequest(buffer_arg) {
+  return core_pb.StartBulkOperationRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_StartBulkOperationResponse(arg) {
+  if (!(arg instanceof core_pb.StartBulkOperationResponse)) {
+    throw new Error('Expected argument of type wsman.StartBulkOperationResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_StartBulkOperationResponse(buffer_arg) {
+  return core_pb.StartBulkOperationResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_StartWorkspaceGroupRequest(arg) {
+  if (!(arg instanceof core_pb.StartWorkspaceGroupRequest)) {
+    throw new Error('Expected argument of type wsman.StartWorkspaceGroupRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_StartWorkspaceGroupRequest(buffer_arg) {
+  return core_pb.StartWorkspaceGroupRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_StartWorkspaceGroupResponse(arg) {
+  if (!(arg instanceof core_pb.StartWorkspaceGroupResponse)) {
+    throw new Error('Expected argument of type wsman.StartWorkspaceGroupResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_StartWorkspaceGroupResponse(buffer_arg) {
+  return core_pb.StartWorkspaceGroupResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_StartWorkspaceRequest(arg) {
   if (!(arg instanceof core_pb.StartWorkspaceRequest)) {
     throw new Error('Expected argument of type wsman.StartWorkspaceRequest');
@@ -160,6 +677,28 @@ function deserialize_wsman_StartWorkspaceResponse(buffer_arg) {
   return core_pb.StartWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_StopWorkspaceGroupRequest(arg) {
+  if (!(arg instanceof core_pb.StopWorkspaceGroupRequest)) {
+    throw new Error('Expected argument of type wsman.StopWorkspaceGroupRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_StopWorkspaceGroupRequest(buffer_arg) {
+  return core_pb.StopWorkspaceGroupRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_StopWorkspaceGroupResponse(arg) {
+  if (!(arg instanceof core_pb.StopWorkspaceGroupResponse)) {
+    throw new Error('Expected argument of type wsman.StopWorkspaceGroupResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_StopWorkspaceGroupResponse(buffer_arg) {
+  return core_pb.StopWorkspaceGroupResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_StopWorkspaceRequest(arg) {
   if (!(arg instanceof core_pb.StopWorkspaceRequest)) {
     throw new Error('Expected argument of type wsman.StopWorkspaceRequest');
@@ -182,6 +721,28 @@ function deserialize_wsman_StopWorkspaceResponse(buffer_arg) {
   return core_pb.StopWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_SubscribeAccountingRequest(arg) {
+  if (!(arg instanceof core_pb.SubscribeAccountingRequest)) {
+    throw new Error('Expected argument of type wsman.SubscribeAccountingRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_SubscribeAccountingRequest(buffer_arg) {
+  return core_pb.SubscribeAccountingRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_SubscribeAuditLogRequest(arg) {
+  if (!(arg instanceof core_pb.SubscribeAuditLogRequest)) {
+    throw new Error('Expected argument of type wsman.SubscribeAuditLogRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_SubscribeAuditLogRequest(buffer_arg) {
+  return core_pb.SubscribeAuditLogRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_wsman_SubscribeRequest(arg) {
   if (!(arg instanceof core_pb.SubscribeRequest)) {
     throw new Error('Expected argument of type wsman.SubscribeRequest');
@@ -226,6 +787,83 @@ function deserialize_wsman_TakeSnapshotResponse(buffer_arg) {
   return core_pb.TakeSnapshotResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_wsman_UnarchiveWorkspaceRequest(arg) {
+  if (!(arg instanceof core_pb.UnarchiveWorkspaceRequest)) {
+    throw new Error('Expected argument of type wsman.UnarchiveWorkspaceRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_UnarchiveWorkspaceRequest(buffer_arg) {
+  return core_pb.UnarchiveWorkspaceRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_UnarchiveWorkspaceResponse(arg) {
+  if (!(arg instanceof core_pb.UnarchiveWorkspaceResponse)) {
+    throw new Error('Expected argument of type wsman.UnarchiveWorkspaceResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_UnarchiveWorkspaceResponse(buffer_arg) {
+  return core_pb.UnarchiveWorkspaceResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_UpdateWorkspaceTemplateRequest(arg) {
+  if (!(arg instanceof core_pb.UpdateWorkspaceTemplateRequest)) {
+    throw new Error('Expected argument of type wsman.UpdateWorkspaceTemplateRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_UpdateWorkspaceTemplateRequest(buffer_arg) {
+  return core_pb.UpdateWorkspaceTemplateRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_UpdateWorkspaceTemplateResponse(arg) {
+  if (!(arg instanceof core_pb.UpdateWorkspaceTemplateResponse)) {
+    throw new Error('Expected argument of type wsman.UpdateWorkspaceTemplateResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_UpdateWorkspaceTemplateResponse(buffer_arg) {
+  return core_pb.UpdateWorkspaceTemplateResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ValidatePodTemplateRequest(arg) {
+  if (!(arg instanceof core_pb.ValidatePodTemplateRequest)) {
+    throw new Error('Expected argument of type wsman.ValidatePodTemplateRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ValidatePodTemplateRequest(buffer_arg) {
+  return core_pb.ValidatePodTemplateRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_ValidatePodTemplateResponse(arg) {
+  if (!(arg instanceof core_pb.ValidatePodTemplateResponse)) {
+    throw new Error('Expected argument of type wsman.ValidatePodTemplateResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_ValidatePodTemplateResponse(buffer_arg) {
+  return core_pb.ValidatePodTemplateResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_wsman_WatchBulkOperationRequest(arg) {
+  if (!(arg instanceof core_pb.WatchBulkOperationRequest)) {
+    throw new Error('Expected argument of type wsman.WatchBulkOperationRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_wsman_WatchBulkOperationRequest(buffer_arg) {
+  return core_pb.WatchBulkOperationRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 
 var WorkspaceManagerService = exports.WorkspaceManagerService = {
   // getWorkspaces produces a list of running workspaces and their status
@@ -348,6 +986,354 @@ controlAdmission: {
     responseSerialize: serialize_wsman_ControlAdmissionResponse,
     responseDeserialize: deserialize_wsman_ControlAdmissionResponse,
   },
+  // setMaintenance announces or ends a cluster maintenance and notifies all subscribers
+setMaintenance: {
+    path: '/wsman.WorkspaceManager/SetMaintenance',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.SetMaintenanceRequest,
+    responseType: core_pb.SetMaintenanceResponse,
+    requestSerialize: serialize_wsman_SetMaintenanceRequest,
+    requestDeserialize: deserialize_wsman_SetMaintenanceRequest,
+    responseSerialize: serialize_wsman_SetMaintenanceResponse,
+    responseDeserialize: deserialize_wsman_SetMaintenanceResponse,
+  },
+  // startWorkspaceGroup starts a group of related workspaces which share their lifecycle
+startWorkspaceGroup: {
+    path: '/wsman.WorkspaceManager/StartWorkspaceGroup',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.StartWorkspaceGroupRequest,
+    responseType: core_pb.StartWorkspaceGroupResponse,
+    requestSerialize: serialize_wsman_StartWorkspaceGroupRequest,
+    requestDeserialize: deserialize_wsman_StartWorkspaceGroupRequest,
+    responseSerialize: serialize_wsman_StartWorkspaceGroupResponse,
+    responseDeserialize: deserialize_wsman_StartWorkspaceGroupResponse,
+  },
+  // stopWorkspaceGroup stops all workspaces of a group
+stopWorkspaceGroup: {
+    path: '/wsman.WorkspaceManager/StopWorkspaceGroup',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.StopWorkspaceGroupRequest,
+    responseType: core_pb.StopWorkspaceGroupResponse,
+    requestSerialize: serialize_wsman_StopWorkspaceGroupRequest,
+    requestDeserialize: deserialize_wsman_StopWorkspaceGroupRequest,
+    responseSerialize: serialize_wsman_StopWorkspaceGroupResponse,
+    responseDeserialize: deserialize_wsman_StopWorkspaceGroupResponse,
+  },
+  // describeWorkspaceGroup returns the status of all workspaces of a group
+describeWorkspaceGroup: {
+    path: '/wsman.WorkspaceManager/DescribeWorkspaceGroup',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.DescribeWorkspaceGroupRequest,
+    responseType: core_pb.DescribeWorkspaceGroupResponse,
+    requestSerialize: serialize_wsman_DescribeWorkspaceGroupRequest,
+    requestDeserialize: deserialize_wsman_DescribeWorkspaceGroupRequest,
+    responseSerialize: serialize_wsman_DescribeWorkspaceGroupResponse,
+    responseDeserialize: deserialize_wsman_DescribeWorkspaceGroupResponse,
+  },
+  // validatePodTemplate checks a pod template against the pod template policy without applying it (dry-run)
+validatePodTemplate: {
+    path: '/wsman.WorkspaceManager/ValidatePodTemplate',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ValidatePodTemplateRequest,
+    responseType: core_pb.ValidatePodTemplateResponse,
+    requestSerialize: serialize_wsman_ValidatePodTemplateRequest,
+    requestDeserialize: deserialize_wsman_ValidatePodTemplateRequest,
+    responseSerialize: serialize_wsman_ValidatePodTemplateResponse,
+    responseDeserialize: deserialize_wsman_ValidatePodTemplateResponse,
+  },
+  // reportProxyActivity records the connections a proxy has open to workspaces so that they count as activity
+reportProxyActivity: {
+    path: '/wsman.WorkspaceManager/ReportProxyActivity',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ReportProxyActivityRequest,
+    responseType: core_pb.ReportProxyActivityResponse,
+    requestSerialize: serialize_wsman_ReportProxyActivityRequest,
+    requestDeserialize: deserialize_wsman_ReportProxyActivityRequest,
+    responseSerialize: serialize_wsman_ReportProxyActivityResponse,
+    responseDeserialize: deserialize_wsman_ReportProxyActivityResponse,
+  },
+  // subscribeAccounting streams the runtime accounting records of workspace instances, starting after a sequence ID
+subscribeAccounting: {
+    path: '/wsman.WorkspaceManager/SubscribeAccounting',
+    requestStream: false,
+    responseStream: true,
+    requestType: core_pb.SubscribeAccountingRequest,
+    responseType: core_pb.AccountingRecord,
+    requestSerialize: serialize_wsman_SubscribeAccountingRequest,
+    requestDeserialize: deserialize_wsman_SubscribeAccountingRequest,
+    responseSerialize: serialize_wsman_AccountingRecord,
+    responseDeserialize: deserialize_wsman_AccountingRecord,
+  },
+  // ackAccounting acknowledges accounting records up to a sequence ID which allows ws-manager to discard them
+ackAccounting: {
+    path: '/wsman.WorkspaceManager/AckAccounting',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.AckAccountingRequest,
+    responseType: core_pb.AckAccountingResponse,
+    requestSerialize: serialize_wsman_AckAccountingRequest,
+    requestDeserialize: deserialize_wsman_AckAccountingRequest,
+    responseSerialize: serialize_wsman_AckAccountingResponse,
+    responseDeserialize: deserialize_wsman_AckAccountingResponse,
+  },
+  // scheduleStop stops a running workspace at a specific time, notifying its user before
+scheduleStop: {
+    path: '/wsman.WorkspaceManager/ScheduleStop',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ScheduleStopRequest,
+    responseType: core_pb.ScheduleStopResponse,
+    requestSerialize: serialize_wsman_ScheduleStopRequest,
+    requestDeserialize: deserialize_wsman_ScheduleStopRequest,
+    responseSerialize: serialize_wsman_ScheduleStopResponse,
+    responseDeserialize: deserialize_wsman_ScheduleStopResponse,
+  },
+  // generateWorkspaceID produces a new workspace ID according to the installation's naming scheme
+generateWorkspaceID: {
+    path: '/wsman.WorkspaceManager/GenerateWorkspaceID',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.GenerateWorkspaceIDRequest,
+    responseType: core_pb.GenerateWorkspaceIDResponse,
+    requestSerialize: serialize_wsman_GenerateWorkspaceIDRequest,
+    requestDeserialize: deserialize_wsman_GenerateWorkspaceIDRequest,
+    responseSerialize: serialize_wsman_GenerateWorkspaceIDResponse,
+    responseDeserialize: deserialize_wsman_GenerateWorkspaceIDResponse,
+  },
+  // exportState produces a snapshot of the workspace state ws-manager keeps in memory, e.g. for disaster recovery drills
+exportState: {
+    path: '/wsman.WorkspaceManager/ExportState',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ExportStateRequest,
+    responseType: core_pb.ExportStateResponse,
+    requestSerialize: serialize_wsman_ExportStateRequest,
+    requestDeserialize: deserialize_wsman_ExportStateRequest,
+    responseSerialize: serialize_wsman_ExportStateResponse,
+    responseDeserialize: deserialize_wsman_ExportStateResponse,
+  },
+  // importState restores a snapshot produced by exportState, reconciling it against the workspaces which actually exist
+importState: {
+    path: '/wsman.WorkspaceManager/ImportState',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ImportStateRequest,
+    responseType: core_pb.ImportStateResponse,
+    requestSerialize: serialize_wsman_ImportStateRequest,
+    requestDeserialize: deserialize_wsman_ImportStateRequest,
+    responseSerialize: serialize_wsman_ImportStateResponse,
+    responseDeserialize: deserialize_wsman_ImportStateResponse,
+  },
+  // describeArchival tells whether stopped workspaces have been archived to cold storage and how long restoring them takes
+describeArchival: {
+    path: '/wsman.WorkspaceManager/DescribeArchival',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.DescribeArchivalRequest,
+    responseType: core_pb.DescribeArchivalResponse,
+    requestSerialize: serialize_wsman_DescribeArchivalRequest,
+    requestDeserialize: deserialize_wsman_DescribeArchivalRequest,
+    responseSerialize: serialize_wsman_DescribeArchivalResponse,
+    responseDeserialize: deserialize_wsman_DescribeArchivalResponse,
+  },
+  // unarchiveWorkspace moves the backup of an archived workspace out of cold storage so that the workspace can start again
+unarchiveWorkspace: {
+    path: '/wsman.WorkspaceManager/UnarchiveWorkspace',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.UnarchiveWorkspaceRequest,
+    responseType: core_pb.UnarchiveWorkspaceResponse,
+    requestSerialize: serialize_wsman_UnarchiveWorkspaceRequest,
+    requestDeserialize: deserialize_wsman_UnarchiveWorkspaceRequest,
+    responseSerialize: serialize_wsman_UnarchiveWorkspaceResponse,
+    responseDeserialize: deserialize_wsman_UnarchiveWorkspaceResponse,
+  },
+  // deleteWorkspace soft-deletes a stopped workspace. Its backup is kept for a grace period during which restoreDeletedWorkspace brings it back.
+deleteWorkspace: {
+    path: '/wsman.WorkspaceManager/DeleteWorkspace',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.DeleteWorkspaceRequest,
+    responseType: core_pb.DeleteWorkspaceResponse,
+    requestSerialize: serialize_wsman_DeleteWorkspaceRequest,
+    requestDeserialize: deserialize_wsman_DeleteWorkspaceRequest,
+    responseSerialize: serialize_wsman_DeleteWorkspaceResponse,
+    responseDeserialize: deserialize_wsman_DeleteWorkspaceResponse,
+  },
+  // restoreDeletedWorkspace brings back a soft-deleted workspace whose grace period has not ended yet
+restoreDeletedWorkspace: {
+    path: '/wsman.WorkspaceManager/RestoreDeletedWorkspace',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.RestoreDeletedWorkspaceRequest,
+    responseType: core_pb.RestoreDeletedWorkspaceResponse,
+    requestSerialize: serialize_wsman_RestoreDeletedWorkspaceRequest,
+    requestDeserialize: deserialize_wsman_RestoreDeletedWorkspaceRequest,
+    responseSerialize: serialize_wsman_RestoreDeletedWorkspaceResponse,
+    responseDeserialize: deserialize_wsman_RestoreDeletedWorkspaceResponse,
+  },
+  // describeDeletedWorkspaces lists the soft-deleted workspaces which can still be restored
+describeDeletedWorkspaces: {
+    path: '/wsman.WorkspaceManager/DescribeDeletedWorkspaces',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.DescribeDeletedWorkspacesRequest,
+    responseType: core_pb.DescribeDeletedWorkspacesResponse,
+    requestSerialize: serialize_wsman_DescribeDeletedWorkspacesRequest,
+    requestDeserialize: deserialize_wsman_DescribeDeletedWorkspacesRequest,
+    responseSerialize: serialize_wsman_DescribeDeletedWorkspacesResponse,
+    responseDeserialize: deserialize_wsman_DescribeDeletedWorkspacesResponse,
+  },
+  // createWorkspaceTemplate stores a named workspace template which startWorkspace can reference
+createWorkspaceTemplate: {
+    path: '/wsman.WorkspaceManager/CreateWorkspaceTemplate',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.CreateWorkspaceTemplateRequest,
+    responseType: core_pb.CreateWorkspaceTemplateResponse,
+    requestSerialize: serialize_wsman_CreateWorkspaceTemplateRequest,
+    requestDeserialize: deserialize_wsman_CreateWorkspaceTemplateRequest,
+    responseSerialize: serialize_wsman_CreateWorkspaceTemplateResponse,
+    responseDeserialize: deserialize_wsman_CreateWorkspaceTemplateResponse,
+  },
+  // updateWorkspaceTemplate adds a new version to a workspace template. Earlier versions remain available.
+updateWorkspaceTemplate: {
+    path: '/wsman.WorkspaceManager/UpdateWorkspaceTemplate',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.UpdateWorkspaceTemplateRequest,
+    responseType: core_pb.UpdateWorkspaceTemplateResponse,
+    requestSerialize: serialize_wsman_UpdateWorkspaceTemplateRequest,
+    requestDeserialize: deserialize_wsman_UpdateWorkspaceTemplateRequest,
+    responseSerialize: serialize_wsman_UpdateWorkspaceTemplateResponse,
+    responseDeserialize: deserialize_wsman_UpdateWorkspaceTemplateResponse,
+  },
+  // getWorkspaceTemplate returns a version of a workspace template
+getWorkspaceTemplate: {
+    path: '/wsman.WorkspaceManager/GetWorkspaceTemplate',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.GetWorkspaceTemplateRequest,
+    responseType: core_pb.GetWorkspaceTemplateResponse,
+    requestSerialize: serialize_wsman_GetWorkspaceTemplateRequest,
+    requestDeserialize: deserialize_wsman_GetWorkspaceTemplateRequest,
+    responseSerialize: serialize_wsman_GetWorkspaceTemplateResponse,
+    responseDeserialize: deserialize_wsman_GetWorkspaceTemplateResponse,
+  },
+  // listWorkspaceTemplates returns the latest version of all workspace templates a user can use
+listWorkspaceTemplates: {
+    path: '/wsman.WorkspaceManager/ListWorkspaceTemplates',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ListWorkspaceTemplatesRequest,
+    responseType: core_pb.ListWorkspaceTemplatesResponse,
+    requestSerialize: serialize_wsman_ListWorkspaceTemplatesRequest,
+    requestDeserialize: deserialize_wsman_ListWorkspaceTemplatesRequest,
+    responseSerialize: serialize_wsman_ListWorkspaceTemplatesResponse,
+    responseDeserialize: deserialize_wsman_ListWorkspaceTemplatesResponse,
+  },
+  // deleteWorkspaceTemplate deletes a workspace template and all its versions
+deleteWorkspaceTemplate: {
+    path: '/wsman.WorkspaceManager/DeleteWorkspaceTemplate',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.DeleteWorkspaceTemplateRequest,
+    responseType: core_pb.DeleteWorkspaceTemplateResponse,
+    requestSerialize: serialize_wsman_DeleteWorkspaceTemplateRequest,
+    requestDeserialize: deserialize_wsman_DeleteWorkspaceTemplateRequest,
+    responseSerialize: serialize_wsman_DeleteWorkspaceTemplateResponse,
+    responseDeserialize: deserialize_wsman_DeleteWorkspaceTemplateResponse,
+  },
+  // resetIDEPin removes the IDE pin of a running workspace so that it uses the default IDE image again
+resetIDEPin: {
+    path: '/wsman.WorkspaceManager/ResetIDEPin',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.ResetIDEPinRequest,
+    responseType: core_pb.ResetIDEPinResponse,
+    requestSerialize: serialize_wsman_ResetIDEPinRequest,
+    requestDeserialize: deserialize_wsman_ResetIDEPinRequest,
+    responseSerialize: serialize_wsman_ResetIDEPinResponse,
+    responseDeserialize: deserialize_wsman_ResetIDEPinResponse,
+  },
+  // startBulkOperation applies an operation to all workspaces which match a filter in the background and returns the ID of the job
+startBulkOperation: {
+    path: '/wsman.WorkspaceManager/StartBulkOperation',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.StartBulkOperationRequest,
+    responseType: core_pb.StartBulkOperationResponse,
+    requestSerialize: serialize_wsman_StartBulkOperationRequest,
+    requestDeserialize: deserialize_wsman_StartBulkOperationRequest,
+    responseSerialize: serialize_wsman_StartBulkOperationResponse,
+    responseDeserialize: deserialize_wsman_StartBulkOperationResponse,
+  },
+  // describeBulkOperation returns the progress and the per-workspace results of a bulk operation
+describeBulkOperation: {
+    path: '/wsman.WorkspaceManager/DescribeBulkOperation',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.DescribeBulkOperationRequest,
+    responseType: core_pb.DescribeBulkOperationResponse,
+    requestSerialize: serialize_wsman_DescribeBulkOperationRequest,
+    requestDeserialize: deserialize_wsman_DescribeBulkOperationRequest,
+    responseSerialize: serialize_wsman_DescribeBulkOperationResponse,
+    responseDeserialize: deserialize_wsman_DescribeBulkOperationResponse,
+  },
+  // watchBulkOperation streams the per-workspace results of a bulk operation as they come in, starting with those we have already
+watchBulkOperation: {
+    path: '/wsman.WorkspaceManager/WatchBulkOperation',
+    requestStream: false,
+    responseStream: true,
+    requestType: core_pb.WatchBulkOperationRequest,
+    responseType: core_pb.BulkOperationUpdate,
+    requestSerialize: serialize_wsman_WatchBulkOperationRequest,
+    requestDeserialize: deserialize_wsman_WatchBulkOperationRequest,
+    responseSerialize: serialize_wsman_BulkOperationUpdate,
+    responseDeserialize: deserialize_wsman_BulkOperationUpdate,
+  },
+  // cancelBulkOperation stops a bulk operation before it reaches the workspaces it has not worked on yet
+cancelBulkOperation: {
+    path: '/wsman.WorkspaceManager/CancelBulkOperation',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.CancelBulkOperationRequest,
+    responseType: core_pb.CancelBulkOperationResponse,
+    requestSerialize: serialize_wsman_CancelBulkOperationRequest,
+    requestDeserialize: deserialize_wsman_CancelBulkOperationRequest,
+    responseSerialize: serialize_wsman_CancelBulkOperationResponse,
+    responseDeserialize: deserialize_wsman_CancelBulkOperationResponse,
+  },
+  // queryAuditLog returns the recorded control actions which match a filter, oldest first
+queryAuditLog: {
+    path: '/wsman.WorkspaceManager/QueryAuditLog',
+    requestStream: false,
+    responseStream: false,
+    requestType: core_pb.QueryAuditLogRequest,
+    responseType: core_pb.QueryAuditLogResponse,
+    requestSerialize: serialize_wsman_QueryAuditLogRequest,
+    requestDeserialize: deserialize_wsman_QueryAuditLogRequest,
+    responseSerialize: serialize_wsman_QueryAuditLogResponse,
+    responseDeserialize: deserialize_wsman_QueryAuditLogResponse,
+  },
+  // subscribeAuditLog streams the audit log from a sequence on, including the records added while streaming, e.g. to export it to a SIEM
+subscribeAuditLog: {
+    path: '/wsman.WorkspaceManager/SubscribeAuditLog',
+    requestStream: false,
+    responseStream: true,
+    requestType: core_pb.SubscribeAuditLogRequest,
+    responseType: core_pb.AuditRecord,
+    requestSerialize: serialize_wsman_SubscribeAuditLogRequest,
+    requestDeserialize: deserialize_wsman_SubscribeAuditLogRequest,
+    responseSerialize: serialize_wsman_AuditRecord,
+    responseDeserialize: deserialize_wsman_AuditRecord,
+  },
 };
 
 exports.WorkspaceManagerClient = grpc.makeGenericClientConstructor(WorkspaceManagerService);
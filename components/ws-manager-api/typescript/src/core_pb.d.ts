@@ -70,6 +70,15 @@ export class StartWorkspaceRequest extends jspb.Message {
     getType(): WorkspaceType;
     setType(value: WorkspaceType): StartWorkspaceRequest;
 
+    getPaused(): boolean;
+    setPaused(value: boolean): StartWorkspaceRequest;
+
+
+    hasTemplate(): boolean;
+    clearTemplate(): void;
+    getTemplate(): WorkspaceTemplateRef | undefined;
+    setTemplate(value?: WorkspaceTemplateRef): StartWorkspaceRequest;
+
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StartWorkspaceRequest.AsObject;
@@ -88,6 +97,8 @@ export namespace StartWorkspaceRequest {
         metadata?: WorkspaceMetadata.AsObject,
         spec?: StartWorkspaceSpec.AsObject,
         type: WorkspaceType,
+        paused: boolean,
+        template?: WorkspaceTemplateRef.AsObject,
     }
 }
 
@@ -95,6 +106,9 @@ export class StartWorkspaceResponse extends jspb.Message {
     getUrl(): string;
     setUrl(value: string): StartWorkspaceResponse;
 
+    getActivated(): boolean;
+    setActivated(value: boolean): StartWorkspaceResponse;
+
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StartWorkspaceResponse.AsObject;
@@ -109,6 +123,40 @@ export class StartWorkspaceResponse extends jspb.Message {
 export namespace StartWorkspaceResponse {
     export type AsObject = {
         url: string,
+        activated: boolean,
+    }
+}
+
+export class StartWorkspaceErrorDetails extends jspb.Message { 
+    getDomain(): StartWorkspaceErrorDomain;
+    setDomain(value: StartWorkspaceErrorDomain): StartWorkspaceErrorDetails;
+
+    getRetryable(): boolean;
+    setRetryable(value: boolean): StartWorkspaceErrorDetails;
+
+    getRetryAfter(): string;
+    setRetryAfter(value: string): StartWorkspaceErrorDetails;
+
+    getUserMessage(): string;
+    setUserMessage(value: string): StartWorkspaceErrorDetails;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): StartWorkspaceErrorDetails.AsObject;
+    static toObject(includeInstance: boolean, msg: StartWorkspaceErrorDetails): StartWorkspaceErrorDetails.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: StartWorkspaceErrorDetails, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StartWorkspaceErrorDetails;
+    static deserializeBinaryFromReader(message: StartWorkspaceErrorDetails, reader: jspb.BinaryReader): StartWorkspaceErrorDetails;
+}
+
+export namespace StartWorkspaceErrorDetails {
+    export type AsObject = {
+        domain: StartWorkspaceErrorDomain,
+        retryable: boolean,
+        retryAfter: string,
+        userMessage: string,
     }
 }
 
@@ -119,6 +167,9 @@ export class StopWorkspaceRequest extends jspb.Message {
     getPolicy(): StopWorkspacePolicy;
     setPolicy(value: StopWorkspacePolicy): StopWorkspaceRequest;
 
+    getExpectedGeneration(): number;
+    setExpectedGeneration(value: number): StopWorkspaceRequest;
+
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StopWorkspaceRequest.AsObject;
@@ -134,6 +185,7 @@ export namespace StopWorkspaceRequest {
     export type AsObject = {
         id: string,
         policy: StopWorkspacePolicy,
+        expectedGeneration: number,
     }
 }
 
@@ -234,6 +286,12 @@ export class SubscribeResponse extends jspb.Message {
     setLog(value?: WorkspaceLogMessage): SubscribeResponse;
 
 
+    hasMaintenance(): boolean;
+    clearMaintenance(): void;
+    getMaintenance(): MaintenanceStatus | undefined;
+    setMaintenance(value?: MaintenanceStatus): SubscribeResponse;
+
+
     getHeaderMap(): jspb.Map<string, string>;
     clearHeaderMap(): void;
 
@@ -254,6 +312,7 @@ export namespace SubscribeResponse {
     export type AsObject = {
         status?: WorkspaceStatus.AsObject,
         log?: WorkspaceLogMessage.AsObject,
+        maintenance?: MaintenanceStatus.AsObject,
 
         headerMap: Array<[string, string]>,
     }
@@ -265,6 +324,8 @@ export namespace SubscribeResponse {
 
     LOG = 2,
 
+    MAINTENANCE = 4,
+
     }
 
 }
@@ -318,6 +379,9 @@ export class SetTimeoutRequest extends jspb.Message {
     getDuration(): string;
     setDuration(value: string): SetTimeoutRequest;
 
+    getExpectedGeneration(): number;
+    setExpectedGeneration(value: number): SetTimeoutRequest;
+
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): SetTimeoutRequest.AsObject;
@@ -333,6 +397,7 @@ export namespace SetTimeoutRequest {
     export type AsObject = {
         id: string,
         duration: string,
+        expectedGeneration: number,
     }
 }
 
@@ -366,6 +431,9 @@ export class ControlPortRequest extends jspb.Message {
     getSpec(): PortSpec | undefined;
     setSpec(value?: PortSpec): ControlPortRequest;
 
+    getExpectedGeneration(): number;
+    setExpectedGeneration(value: number): ControlPortRequest;
+
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): ControlPortRequest.AsObject;
@@ -382,6 +450,7 @@ export namespace ControlPortRequest {
         id: string,
         expose: boolean,
         spec?: PortSpec.AsObject,
+        expectedGeneration: number,
     }
 }
 
@@ -451,6 +520,9 @@ export class ControlAdmissionRequest extends jspb.Message {
     getLevel(): AdmissionLevel;
     setLevel(value: AdmissionLevel): ControlAdmissionRequest;
 
+    getExpectedGeneration(): number;
+    setExpectedGeneration(value: number): ControlAdmissionRequest;
+
 
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): ControlAdmissionRequest.AsObject;
@@ -466,6 +538,7 @@ export namespace ControlAdmissionRequest {
     export type AsObject = {
         id: string,
         level: AdmissionLevel,
+        expectedGeneration: number,
     }
 }
 
@@ -486,295 +559,2570 @@ export namespace ControlAdmissionResponse {
     }
 }
 
-export class WorkspaceStatus extends jspb.Message { 
-    getId(): string;
-    setId(value: string): WorkspaceStatus;
-
-
-    hasMetadata(): boolean;
-    clearMetadata(): void;
-    getMetadata(): WorkspaceMetadata | undefined;
-    setMetadata(value?: WorkspaceMetadata): WorkspaceStatus;
-
-
-    hasSpec(): boolean;
-    clearSpec(): void;
-    getSpec(): WorkspaceSpec | undefined;
-    setSpec(value?: WorkspaceSpec): WorkspaceStatus;
-
-    getPhase(): WorkspacePhase;
-    setPhase(value: WorkspacePhase): WorkspaceStatus;
-
-
-    hasConditions(): boolean;
-    clearConditions(): void;
-    getConditions(): WorkspaceConditions | undefined;
-    setConditions(value?: WorkspaceConditions): WorkspaceStatus;
-
-    getMessage(): string;
-    setMessage(value: string): WorkspaceStatus;
-
-
-    hasRepo(): boolean;
-    clearRepo(): void;
-    getRepo(): content_service_api_initializer_pb.GitStatus | undefined;
-    setRepo(value?: content_service_api_initializer_pb.GitStatus): WorkspaceStatus;
-
-
-    hasRuntime(): boolean;
-    clearRuntime(): void;
-    getRuntime(): WorkspaceRuntimeInfo | undefined;
-    setRuntime(value?: WorkspaceRuntimeInfo): WorkspaceStatus;
-
+export class SetMaintenanceRequest extends jspb.Message { 
 
-    hasAuth(): boolean;
-    clearAuth(): void;
-    getAuth(): WorkspaceAuthentication | undefined;
-    setAuth(value?: WorkspaceAuthentication): WorkspaceStatus;
+    hasStatus(): boolean;
+    clearStatus(): void;
+    getStatus(): MaintenanceStatus | undefined;
+    setStatus(value?: MaintenanceStatus): SetMaintenanceRequest;
 
 
     serializeBinary(): Uint8Array;
-    toObject(includeInstance?: boolean): WorkspaceStatus.AsObject;
-    static toObject(includeInstance: boolean, msg: WorkspaceStatus): WorkspaceStatus.AsObject;
+    toObject(includeInstance?: boolean): SetMaintenanceRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: SetMaintenanceRequest): SetMaintenanceRequest.AsObject;
     static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
     static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
-    static serializeBinaryToWriter(message: WorkspaceStatus, writer: jspb.BinaryWriter): void;
-    static deserializeBinary(bytes: Uint8Array): WorkspaceStatus;
-    static deserializeBinaryFromReader(message: WorkspaceStatus, reader: jspb.BinaryReader): WorkspaceStatus;
+    static serializeBinaryToWriter(message: SetMaintenanceRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): SetMaintenanceRequest;
+    static deserializeBinaryFromReader(message: SetMaintenanceRequest, reader: jspb.BinaryReader): SetMaintenanceRequest;
 }
 
-export namespace WorkspaceStatus {
+export namespace SetMaintenanceRequest {
     export type AsObject = {
-        id: string,
-        metadata?: WorkspaceMetadata.AsObject,
-        spec?: WorkspaceSpec.AsObject,
-        phase: WorkspacePhase,
-        conditions?: WorkspaceConditions.AsObject,
-        message: string,
-        repo?: content_service_api_initializer_pb.GitStatus.AsObject,
-        runtime?: WorkspaceRuntimeInfo.AsObject,
-        auth?: WorkspaceAuthentication.AsObject,
+        status?: MaintenanceStatus.AsObject,
     }
 }
 
-export class WorkspaceSpec extends jspb.Message { 
-    getWorkspaceImage(): string;
-    setWorkspaceImage(value: string): WorkspaceSpec;
-
-    getIdeImage(): string;
-    setIdeImage(value: string): WorkspaceSpec;
+export class SetMaintenanceResponse extends jspb.Message { 
 
-    getHeadless(): boolean;
-    setHeadless(value: boolean): WorkspaceSpec;
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): SetMaintenanceResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: SetMaintenanceResponse): SetMaintenanceResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: SetMaintenanceResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): SetMaintenanceResponse;
+    static deserializeBinaryFromReader(message: SetMaintenanceResponse, reader: jspb.BinaryReader): SetMaintenanceResponse;
+}
 
-    getUrl(): string;
-    setUrl(value: string): WorkspaceSpec;
+export namespace SetMaintenanceResponse {
+    export type AsObject = {
+    }
+}
 
-    clearExposedPortsList(): void;
-    getExposedPortsList(): Array<PortSpec>;
-    setExposedPortsList(value: Array<PortSpec>): WorkspaceSpec;
-    addExposedPorts(value?: PortSpec, index?: number): PortSpec;
+export class StartWorkspaceGroupRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): StartWorkspaceGroupRequest;
 
-    getType(): WorkspaceType;
-    setType(value: WorkspaceType): WorkspaceSpec;
+    clearMembersList(): void;
+    getMembersList(): Array<WorkspaceGroupMember>;
+    setMembersList(value: Array<WorkspaceGroupMember>): StartWorkspaceGroupRequest;
+    addMembers(value?: WorkspaceGroupMember, index?: number): WorkspaceGroupMember;
 
     getTimeout(): string;
-    setTimeout(value: string): WorkspaceSpec;
+    setTimeout(value: string): StartWorkspaceGroupRequest;
 
 
     serializeBinary(): Uint8Array;
-    toObject(includeInstance?: boolean): WorkspaceSpec.AsObject;
-    static toObject(includeInstance: boolean, msg: WorkspaceSpec): WorkspaceSpec.AsObject;
+    toObject(includeInstance?: boolean): StartWorkspaceGroupRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: StartWorkspaceGroupRequest): StartWorkspaceGroupRequest.AsObject;
     static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
     static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
-    static serializeBinaryToWriter(message: WorkspaceSpec, writer: jspb.BinaryWriter): void;
-    static deserializeBinary(bytes: Uint8Array): WorkspaceSpec;
-    static deserializeBinaryFromReader(message: WorkspaceSpec, reader: jspb.BinaryReader): WorkspaceSpec;
+    static serializeBinaryToWriter(message: StartWorkspaceGroupRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StartWorkspaceGroupRequest;
+    static deserializeBinaryFromReader(message: StartWorkspaceGroupRequest, reader: jspb.BinaryReader): StartWorkspaceGroupRequest;
 }
 
-export namespace WorkspaceSpec {
+export namespace StartWorkspaceGroupRequest {
     export type AsObject = {
-        workspaceImage: string,
-        ideImage: string,
-        headless: boolean,
-        url: string,
-        exposedPortsList: Array<PortSpec.AsObject>,
-        type: WorkspaceType,
+        id: string,
+        membersList: Array<WorkspaceGroupMember.AsObject>,
         timeout: string,
     }
 }
 
-export class PortSpec extends jspb.Message { 
-    getPort(): number;
-    setPort(value: number): PortSpec;
+export class WorkspaceGroupMember extends jspb.Message { 
+    getName(): string;
+    setName(value: string): WorkspaceGroupMember;
 
-    getTarget(): number;
-    setTarget(value: number): PortSpec;
 
-    getVisibility(): PortVisibility;
-    setVisibility(value: PortVisibility): PortSpec;
+    hasWorkspace(): boolean;
+    clearWorkspace(): void;
+    getWorkspace(): StartWorkspaceRequest | undefined;
+    setWorkspace(value?: StartWorkspaceRequest): WorkspaceGroupMember;
 
-    getUrl(): string;
-    setUrl(value: string): PortSpec;
+    clearDependsOnList(): void;
+    getDependsOnList(): Array<string>;
+    setDependsOnList(value: Array<string>): WorkspaceGroupMember;
+    addDependsOn(value: string, index?: number): string;
 
 
     serializeBinary(): Uint8Array;
-    toObject(includeInstance?: boolean): PortSpec.AsObject;
-    static toObject(includeInstance: boolean, msg: PortSpec): PortSpec.AsObject;
+    toObject(includeInstance?: boolean): WorkspaceGroupMember.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceGroupMember): WorkspaceGroupMember.AsObject;
     static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
     static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
-    static serializeBinaryToWriter(message: PortSpec, writer: jspb.BinaryWriter): void;
-    static deserializeBinary(bytes: Uint8Array): PortSpec;
-    static deserializeBinaryFromReader(message: PortSpec, reader: jspb.BinaryReader): PortSpec;
+    static serializeBinaryToWriter(message: WorkspaceGroupMember, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceGroupMember;
+    static deserializeBinaryFromReader(message: WorkspaceGroupMember, reader: jspb.BinaryReader): WorkspaceGroupMember;
 }
 
-export namespace PortSpec {
+export namespace WorkspaceGroupMember {
     export type AsObject = {
-        port: number,
-        target: number,
-        visibility: PortVisibility,
-        url: string,
+        name: string,
+        workspace?: StartWorkspaceRequest.AsObject,
+        dependsOnList: Array<string>,
     }
 }
 
-export class WorkspaceConditions extends jspb.Message { 
-    getFailed(): string;
-    setFailed(value: string): WorkspaceConditions;
-
-    getTimeout(): string;
-    setTimeout(value: string): WorkspaceConditions;
-
-    getPullingImages(): WorkspaceConditionBool;
-    setPullingImages(value: WorkspaceConditionBool): WorkspaceConditions;
+export class StartWorkspaceGroupResponse extends jspb.Message { 
 
-    getServiceExists(): WorkspaceConditionBool;
-    setServiceExists(value: WorkspaceConditionBool): WorkspaceConditions;
+    getUrlsMap(): jspb.Map<string, string>;
+    clearUrlsMap(): void;
 
-    getSnapshot(): string;
-    setSnapshot(value: string): WorkspaceConditions;
 
-    getFinalBackupComplete(): WorkspaceConditionBool;
-    setFinalBackupComplete(value: WorkspaceConditionBool): WorkspaceConditions;
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): StartWorkspaceGroupResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: StartWorkspaceGroupResponse): StartWorkspaceGroupResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: StartWorkspaceGroupResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StartWorkspaceGroupResponse;
+    static deserializeBinaryFromReader(message: StartWorkspaceGroupResponse, reader: jspb.BinaryReader): StartWorkspaceGroupResponse;
+}
 
-    getDeployed(): WorkspaceConditionBool;
-    setDeployed(value: WorkspaceConditionBool): WorkspaceConditions;
+export namespace StartWorkspaceGroupResponse {
+    export type AsObject = {
 
-    getNetworkNotReady(): WorkspaceConditionBool;
-    setNetworkNotReady(value: WorkspaceConditionBool): WorkspaceConditions;
+        urlsMap: Array<[string, string]>,
+    }
+}
 
+export class StopWorkspaceGroupRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): StopWorkspaceGroupRequest;
 
-    hasFirstUserActivity(): boolean;
-    clearFirstUserActivity(): void;
-    getFirstUserActivity(): google_protobuf_timestamp_pb.Timestamp | undefined;
-    setFirstUserActivity(value?: google_protobuf_timestamp_pb.Timestamp): WorkspaceConditions;
+    getPolicy(): StopWorkspacePolicy;
+    setPolicy(value: StopWorkspacePolicy): StopWorkspaceGroupRequest;
 
 
     serializeBinary(): Uint8Array;
-    toObject(includeInstance?: boolean): WorkspaceConditions.AsObject;
-    static toObject(includeInstance: boolean, msg: WorkspaceConditions): WorkspaceConditions.AsObject;
+    toObject(includeInstance?: boolean): StopWorkspaceGroupRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: StopWorkspaceGroupRequest): StopWorkspaceGroupRequest.AsObject;
     static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
     static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
-    static serializeBinaryToWriter(message: WorkspaceConditions, writer: jspb.BinaryWriter): void;
-    static deserializeBinary(bytes: Uint8Array): WorkspaceConditions;
-    static deserializeBinaryFromReader(message: WorkspaceConditions, reader: jspb.BinaryReader): WorkspaceConditions;
+    static serializeBinaryToWriter(message: StopWorkspaceGroupRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StopWorkspaceGroupRequest;
+    static deserializeBinaryFromReader(message: StopWorkspaceGroupRequest, reader: jspb.BinaryReader): StopWorkspaceGroupRequest;
 }
 
-export namespace WorkspaceConditions {
+export namespace StopWorkspaceGroupRequest {
     export type AsObject = {
-        failed: string,
-        timeout: string,
-        pullingImages: WorkspaceConditionBool,
-        serviceExists: WorkspaceConditionBool,
-        snapshot: string,
-        finalBackupComplete: WorkspaceConditionBool,
-        deployed: WorkspaceConditionBool,
-        networkNotReady: WorkspaceConditionBool,
-        firstUserActivity?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        id: string,
+        policy: StopWorkspacePolicy,
     }
 }
 
-export class WorkspaceMetadata extends jspb.Message { 
-    getOwner(): string;
-    setOwner(value: string): WorkspaceMetadata;
+export class StopWorkspaceGroupResponse extends jspb.Message { 
 
-    getMetaId(): string;
-    setMetaId(value: string): WorkspaceMetadata;
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): StopWorkspaceGroupResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: StopWorkspaceGroupResponse): StopWorkspaceGroupResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: StopWorkspaceGroupResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StopWorkspaceGroupResponse;
+    static deserializeBinaryFromReader(message: StopWorkspaceGroupResponse, reader: jspb.BinaryReader): StopWorkspaceGroupResponse;
+}
 
+export namespace StopWorkspaceGroupResponse {
+    export type AsObject = {
+    }
+}
 
-    hasStartedAt(): boolean;
-    clearStartedAt(): void;
-    getStartedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
-    setStartedAt(value?: google_protobuf_timestamp_pb.Timestamp): WorkspaceMetadata;
+export class DescribeWorkspaceGroupRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): DescribeWorkspaceGroupRequest;
 
 
     serializeBinary(): Uint8Array;
-    toObject(includeInstance?: boolean): WorkspaceMetadata.AsObject;
-    static toObject(includeInstance: boolean, msg: WorkspaceMetadata): WorkspaceMetadata.AsObject;
+    toObject(includeInstance?: boolean): DescribeWorkspaceGroupRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeWorkspaceGroupRequest): DescribeWorkspaceGroupRequest.AsObject;
     static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
     static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
-    static serializeBinaryToWriter(message: WorkspaceMetadata, writer: jspb.BinaryWriter): void;
-    static deserializeBinary(bytes: Uint8Array): WorkspaceMetadata;
-    static deserializeBinaryFromReader(message: WorkspaceMetadata, reader: jspb.BinaryReader): WorkspaceMetadata;
+    static serializeBinaryToWriter(message: DescribeWorkspaceGroupRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeWorkspaceGroupRequest;
+    static deserializeBinaryFromReader(message: DescribeWorkspaceGroupRequest, reader: jspb.BinaryReader): DescribeWorkspaceGroupRequest;
 }
 
-export namespace WorkspaceMetadata {
+export namespace DescribeWorkspaceGroupRequest {
     export type AsObject = {
-        owner: string,
-        metaId: string,
-        startedAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        id: string,
     }
 }
 
-export class WorkspaceRuntimeInfo extends jspb.Message { 
-    getNodeName(): string;
-    setNodeName(value: string): WorkspaceRuntimeInfo;
-
-    getPodName(): string;
-    setPodName(value: string): WorkspaceRuntimeInfo;
-
-    getNodeIp(): string;
-    setNodeIp(value: string): WorkspaceRuntimeInfo;
+export class DescribeWorkspaceGroupResponse extends jspb.Message { 
+    clearStatusList(): void;
+    getStatusList(): Array<WorkspaceStatus>;
+    setStatusList(value: Array<WorkspaceStatus>): DescribeWorkspaceGroupResponse;
+    addStatus(value?: WorkspaceStatus, index?: number): WorkspaceStatus;
 
 
     serializeBinary(): Uint8Array;
-    toObject(includeInstance?: boolean): WorkspaceRuntimeInfo.AsObject;
-    static toObject(includeInstance: boolean, msg: WorkspaceRuntimeInfo): WorkspaceRuntimeInfo.AsObject;
+    toObject(includeInstance?: boolean): DescribeWorkspaceGroupResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeWorkspaceGroupResponse): DescribeWorkspaceGroupResponse.AsObject;
     static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
     static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
-    static serializeBinaryToWriter(message: WorkspaceRuntimeInfo, writer: jspb.BinaryWriter): void;
-    static deserializeBinary(bytes: Uint8Array): WorkspaceRuntimeInfo;
-    static deserializeBinaryFromReader(message: WorkspaceRuntimeInfo, reader: jspb.BinaryReader): WorkspaceRuntimeInfo;
+    static serializeBinaryToWriter(message: DescribeWorkspaceGroupResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeWorkspaceGroupResponse;
+    static deserializeBinaryFromReader(message: DescribeWorkspaceGroupResponse, reader: jspb.BinaryReader): DescribeWorkspaceGroupResponse;
 }
 
-export namespace WorkspaceRuntimeInfo {
+export namespace DescribeWorkspaceGroupResponse {
     export type AsObject = {
-        nodeName: string,
-        podName: string,
-        nodeIp: string,
+        statusList: Array<WorkspaceStatus.AsObject>,
     }
 }
 
-export class WorkspaceAuthentication extends jspb.Message { 
-    getAdmission(): AdmissionLevel;
-    setAdmission(value: AdmissionLevel): WorkspaceAuthentication;
-
-    getOwnerToken(): string;
-    setOwnerToken(value: string): WorkspaceAuthentication;
+export class ValidatePodTemplateRequest extends jspb.Message { 
+    getTemplate(): string;
+    setTemplate(value: string): ValidatePodTemplateRequest;
 
 
     serializeBinary(): Uint8Array;
-    toObject(includeInstance?: boolean): WorkspaceAuthentication.AsObject;
-    static toObject(includeInstance: boolean, msg: WorkspaceAuthentication): WorkspaceAuthentication.AsObject;
+    toObject(includeInstance?: boolean): ValidatePodTemplateRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ValidatePodTemplateRequest): ValidatePodTemplateRequest.AsObject;
     static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
     static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
-    static serializeBinaryToWriter(message: WorkspaceAuthentication, writer: jspb.BinaryWriter): void;
-    static deserializeBinary(bytes: Uint8Array): WorkspaceAuthentication;
-    static deserializeBinaryFromReader(message: WorkspaceAuthentication, reader: jspb.BinaryReader): WorkspaceAuthentication;
+    static serializeBinaryToWriter(message: ValidatePodTemplateRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ValidatePodTemplateRequest;
+    static deserializeBinaryFromReader(message: ValidatePodTemplateRequest, reader: jspb.BinaryReader): ValidatePodTemplateRequest;
 }
 
-export namespace WorkspaceAuthentication {
+export namespace ValidatePodTemplateRequest {
     export type AsObject = {
-        admission: AdmissionLevel,
+        template: string,
+    }
+}
+
+export class ValidatePodTemplateResponse extends jspb.Message { 
+    clearViolationsList(): void;
+    getViolationsList(): Array<PodTemplateViolation>;
+    setViolationsList(value: Array<PodTemplateViolation>): ValidatePodTemplateResponse;
+    addViolations(value?: PodTemplateViolation, index?: number): PodTemplateViolation;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ValidatePodTemplateResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ValidatePodTemplateResponse): ValidatePodTemplateResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ValidatePodTemplateResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ValidatePodTemplateResponse;
+    static deserializeBinaryFromReader(message: ValidatePodTemplateResponse, reader: jspb.BinaryReader): ValidatePodTemplateResponse;
+}
+
+export namespace ValidatePodTemplateResponse {
+    export type AsObject = {
+        violationsList: Array<PodTemplateViolation.AsObject>,
+    }
+}
+
+export class PodTemplateViolation extends jspb.Message { 
+    getField(): string;
+    setField(value: string): PodTemplateViolation;
+
+    getMessage(): string;
+    setMessage(value: string): PodTemplateViolation;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): PodTemplateViolation.AsObject;
+    static toObject(includeInstance: boolean, msg: PodTemplateViolation): PodTemplateViolation.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: PodTemplateViolation, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): PodTemplateViolation;
+    static deserializeBinaryFromReader(message: PodTemplateViolation, reader: jspb.BinaryReader): PodTemplateViolation;
+}
+
+export namespace PodTemplateViolation {
+    export type AsObject = {
+        field: string,
+        message: string,
+    }
+}
+
+export class ReportProxyActivityRequest extends jspb.Message { 
+    getProxy(): string;
+    setProxy(value: string): ReportProxyActivityRequest;
+
+    clearWorkspacesList(): void;
+    getWorkspacesList(): Array<WorkspaceProxyActivity>;
+    setWorkspacesList(value: Array<WorkspaceProxyActivity>): ReportProxyActivityRequest;
+    addWorkspaces(value?: WorkspaceProxyActivity, index?: number): WorkspaceProxyActivity;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ReportProxyActivityRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ReportProxyActivityRequest): ReportProxyActivityRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ReportProxyActivityRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ReportProxyActivityRequest;
+    static deserializeBinaryFromReader(message: ReportProxyActivityRequest, reader: jspb.BinaryReader): ReportProxyActivityRequest;
+}
+
+export namespace ReportProxyActivityRequest {
+    export type AsObject = {
+        proxy: string,
+        workspacesList: Array<WorkspaceProxyActivity.AsObject>,
+    }
+}
+
+export class WorkspaceProxyActivity extends jspb.Message { 
+    getId(): string;
+    setId(value: string): WorkspaceProxyActivity;
+
+    getConnections(): number;
+    setConnections(value: number): WorkspaceProxyActivity;
+
+    getSessions(): number;
+    setSessions(value: number): WorkspaceProxyActivity;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceProxyActivity.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceProxyActivity): WorkspaceProxyActivity.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceProxyActivity, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceProxyActivity;
+    static deserializeBinaryFromReader(message: WorkspaceProxyActivity, reader: jspb.BinaryReader): WorkspaceProxyActivity;
+}
+
+export namespace WorkspaceProxyActivity {
+    export type AsObject = {
+        id: string,
+        connections: number,
+        sessions: number,
+    }
+}
+
+export class ReportProxyActivityResponse extends jspb.Message { 
+    getMinInterval(): string;
+    setMinInterval(value: string): ReportProxyActivityResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ReportProxyActivityResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ReportProxyActivityResponse): ReportProxyActivityResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ReportProxyActivityResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ReportProxyActivityResponse;
+    static deserializeBinaryFromReader(message: ReportProxyActivityResponse, reader: jspb.BinaryReader): ReportProxyActivityResponse;
+}
+
+export namespace ReportProxyActivityResponse {
+    export type AsObject = {
+        minInterval: string,
+    }
+}
+
+export class SubscribeAccountingRequest extends jspb.Message { 
+    getAfterSequence(): number;
+    setAfterSequence(value: number): SubscribeAccountingRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): SubscribeAccountingRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: SubscribeAccountingRequest): SubscribeAccountingRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: SubscribeAccountingRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): SubscribeAccountingRequest;
+    static deserializeBinaryFromReader(message: SubscribeAccountingRequest, reader: jspb.BinaryReader): SubscribeAccountingRequest;
+}
+
+export namespace SubscribeAccountingRequest {
+    export type AsObject = {
+        afterSequence: number,
+    }
+}
+
+export class AccountingRecord extends jspb.Message { 
+    getSequence(): number;
+    setSequence(value: number): AccountingRecord;
+
+    getEvent(): AccountingEvent;
+    setEvent(value: AccountingEvent): AccountingRecord;
+
+    getId(): string;
+    setId(value: string): AccountingRecord;
+
+
+    hasMetadata(): boolean;
+    clearMetadata(): void;
+    getMetadata(): WorkspaceMetadata | undefined;
+    setMetadata(value?: WorkspaceMetadata): AccountingRecord;
+
+    getType(): WorkspaceType;
+    setType(value: WorkspaceType): AccountingRecord;
+
+    getNodeName(): string;
+    setNodeName(value: string): AccountingRecord;
+
+
+    hasTime(): boolean;
+    clearTime(): void;
+    getTime(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setTime(value?: google_protobuf_timestamp_pb.Timestamp): AccountingRecord;
+
+    getRuntimeSeconds(): number;
+    setRuntimeSeconds(value: number): AccountingRecord;
+
+    getEstimated(): boolean;
+    setEstimated(value: boolean): AccountingRecord;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): AccountingRecord.AsObject;
+    static toObject(includeInstance: boolean, msg: AccountingRecord): AccountingRecord.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: AccountingRecord, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): AccountingRecord;
+    static deserializeBinaryFromReader(message: AccountingRecord, reader: jspb.BinaryReader): AccountingRecord;
+}
+
+export namespace AccountingRecord {
+    export type AsObject = {
+        sequence: number,
+        event: AccountingEvent,
+        id: string,
+        metadata?: WorkspaceMetadata.AsObject,
+        type: WorkspaceType,
+        nodeName: string,
+        time?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        runtimeSeconds: number,
+        estimated: boolean,
+    }
+}
+
+export class AckAccountingRequest extends jspb.Message { 
+    getSequence(): number;
+    setSequence(value: number): AckAccountingRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): AckAccountingRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: AckAccountingRequest): AckAccountingRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: AckAccountingRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): AckAccountingRequest;
+    static deserializeBinaryFromReader(message: AckAccountingRequest, reader: jspb.BinaryReader): AckAccountingRequest;
+}
+
+export namespace AckAccountingRequest {
+    export type AsObject = {
+        sequence: number,
+    }
+}
+
+export class AckAccountingResponse extends jspb.Message { 
+    getAcknowledged(): number;
+    setAcknowledged(value: number): AckAccountingResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): AckAccountingResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: AckAccountingResponse): AckAccountingResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: AckAccountingResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): AckAccountingResponse;
+    static deserializeBinaryFromReader(message: AckAccountingResponse, reader: jspb.BinaryReader): AckAccountingResponse;
+}
+
+export namespace AckAccountingResponse {
+    export type AsObject = {
+        acknowledged: number,
+    }
+}
+
+export class ScheduleStopRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): ScheduleStopRequest;
+
+
+    hasAt(): boolean;
+    clearAt(): void;
+    getAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setAt(value?: google_protobuf_timestamp_pb.Timestamp): ScheduleStopRequest;
+
+    getDuration(): string;
+    setDuration(value: string): ScheduleStopRequest;
+
+    getCancel(): boolean;
+    setCancel(value: boolean): ScheduleStopRequest;
+
+    getExpectedGeneration(): number;
+    setExpectedGeneration(value: number): ScheduleStopRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ScheduleStopRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ScheduleStopRequest): ScheduleStopRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ScheduleStopRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ScheduleStopRequest;
+    static deserializeBinaryFromReader(message: ScheduleStopRequest, reader: jspb.BinaryReader): ScheduleStopRequest;
+}
+
+export namespace ScheduleStopRequest {
+    export type AsObject = {
+        id: string,
+        at?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        duration: string,
+        cancel: boolean,
+        expectedGeneration: number,
+    }
+}
+
+export class ScheduleStopResponse extends jspb.Message { 
+
+    hasDeadline(): boolean;
+    clearDeadline(): void;
+    getDeadline(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setDeadline(value?: google_protobuf_timestamp_pb.Timestamp): ScheduleStopResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ScheduleStopResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ScheduleStopResponse): ScheduleStopResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ScheduleStopResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ScheduleStopResponse;
+    static deserializeBinaryFromReader(message: ScheduleStopResponse, reader: jspb.BinaryReader): ScheduleStopResponse;
+}
+
+export namespace ScheduleStopResponse {
+    export type AsObject = {
+        deadline?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class GenerateWorkspaceIDRequest extends jspb.Message { 
+    getProject(): string;
+    setProject(value: string): GenerateWorkspaceIDRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GenerateWorkspaceIDRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: GenerateWorkspaceIDRequest): GenerateWorkspaceIDRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: GenerateWorkspaceIDRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GenerateWorkspaceIDRequest;
+    static deserializeBinaryFromReader(message: GenerateWorkspaceIDRequest, reader: jspb.BinaryReader): GenerateWorkspaceIDRequest;
+}
+
+export namespace GenerateWorkspaceIDRequest {
+    export type AsObject = {
+        project: string,
+    }
+}
+
+export class GenerateWorkspaceIDResponse extends jspb.Message { 
+    getId(): string;
+    setId(value: string): GenerateWorkspaceIDResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GenerateWorkspaceIDResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: GenerateWorkspaceIDResponse): GenerateWorkspaceIDResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: GenerateWorkspaceIDResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GenerateWorkspaceIDResponse;
+    static deserializeBinaryFromReader(message: GenerateWorkspaceIDResponse, reader: jspb.BinaryReader): GenerateWorkspaceIDResponse;
+}
+
+export namespace GenerateWorkspaceIDResponse {
+    export type AsObject = {
+        id: string,
+    }
+}
+
+export class ExportStateRequest extends jspb.Message { 
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ExportStateRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ExportStateRequest): ExportStateRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ExportStateRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ExportStateRequest;
+    static deserializeBinaryFromReader(message: ExportStateRequest, reader: jspb.BinaryReader): ExportStateRequest;
+}
+
+export namespace ExportStateRequest {
+    export type AsObject = {
+    }
+}
+
+export class ExportStateResponse extends jspb.Message { 
+    getSnapshot(): Uint8Array | string;
+    getSnapshot_asU8(): Uint8Array;
+    getSnapshot_asB64(): string;
+    setSnapshot(value: Uint8Array | string): ExportStateResponse;
+
+    getVersion(): number;
+    setVersion(value: number): ExportStateResponse;
+
+
+    hasCreatedAt(): boolean;
+    clearCreatedAt(): void;
+    getCreatedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setCreatedAt(value?: google_protobuf_timestamp_pb.Timestamp): ExportStateResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ExportStateResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ExportStateResponse): ExportStateResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ExportStateResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ExportStateResponse;
+    static deserializeBinaryFromReader(message: ExportStateResponse, reader: jspb.BinaryReader): ExportStateResponse;
+}
+
+export namespace ExportStateResponse {
+    export type AsObject = {
+        snapshot: Uint8Array | string,
+        version: number,
+        createdAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class ImportStateRequest extends jspb.Message { 
+    getSnapshot(): Uint8Array | string;
+    getSnapshot_asU8(): Uint8Array;
+    getSnapshot_asB64(): string;
+    setSnapshot(value: Uint8Array | string): ImportStateRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ImportStateRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ImportStateRequest): ImportStateRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ImportStateRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ImportStateRequest;
+    static deserializeBinaryFromReader(message: ImportStateRequest, reader: jspb.BinaryReader): ImportStateRequest;
+}
+
+export namespace ImportStateRequest {
+    export type AsObject = {
+        snapshot: Uint8Array | string,
+    }
+}
+
+export class ImportStateResponse extends jspb.Message { 
+    clearRestoredInstancesList(): void;
+    getRestoredInstancesList(): Array<string>;
+    setRestoredInstancesList(value: Array<string>): ImportStateResponse;
+    addRestoredInstances(value: string, index?: number): string;
+
+    clearDroppedInstancesList(): void;
+    getDroppedInstancesList(): Array<string>;
+    setDroppedInstancesList(value: Array<string>): ImportStateResponse;
+    addDroppedInstances(value: string, index?: number): string;
+
+    clearResumedOperationsList(): void;
+    getResumedOperationsList(): Array<PendingOperation>;
+    setResumedOperationsList(value: Array<PendingOperation>): ImportStateResponse;
+    addResumedOperations(value?: PendingOperation, index?: number): PendingOperation;
+
+    clearLostOperationsList(): void;
+    getLostOperationsList(): Array<PendingOperation>;
+    setLostOperationsList(value: Array<PendingOperation>): ImportStateResponse;
+    addLostOperations(value?: PendingOperation, index?: number): PendingOperation;
+
+    getMaintenanceRestored(): boolean;
+    setMaintenanceRestored(value: boolean): ImportStateResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ImportStateResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ImportStateResponse): ImportStateResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ImportStateResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ImportStateResponse;
+    static deserializeBinaryFromReader(message: ImportStateResponse, reader: jspb.BinaryReader): ImportStateResponse;
+}
+
+export namespace ImportStateResponse {
+    export type AsObject = {
+        restoredInstancesList: Array<string>,
+        droppedInstancesList: Array<string>,
+        resumedOperationsList: Array<PendingOperation.AsObject>,
+        lostOperationsList: Array<PendingOperation.AsObject>,
+        maintenanceRestored: boolean,
+    }
+}
+
+export class PendingOperation extends jspb.Message { 
+    getInstanceId(): string;
+    setInstanceId(value: string): PendingOperation;
+
+    getKind(): PendingOperationKind;
+    setKind(value: PendingOperationKind): PendingOperation;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): PendingOperation.AsObject;
+    static toObject(includeInstance: boolean, msg: PendingOperation): PendingOperation.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: PendingOperation, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): PendingOperation;
+    static deserializeBinaryFromReader(message: PendingOperation, reader: jspb.BinaryReader): PendingOperation;
+}
+
+export namespace PendingOperation {
+    export type AsObject = {
+        instanceId: string,
+        kind: PendingOperationKind,
+    }
+}
+
+export class DescribeArchivalRequest extends jspb.Message { 
+    getWorkspaceId(): string;
+    setWorkspaceId(value: string): DescribeArchivalRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeArchivalRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeArchivalRequest): DescribeArchivalRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeArchivalRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeArchivalRequest;
+    static deserializeBinaryFromReader(message: DescribeArchivalRequest, reader: jspb.BinaryReader): DescribeArchivalRequest;
+}
+
+export namespace DescribeArchivalRequest {
+    export type AsObject = {
+        workspaceId: string,
+    }
+}
+
+export class DescribeArchivalResponse extends jspb.Message { 
+    clearWorkspacesList(): void;
+    getWorkspacesList(): Array<ArchivalStatus>;
+    setWorkspacesList(value: Array<ArchivalStatus>): DescribeArchivalResponse;
+    addWorkspaces(value?: ArchivalStatus, index?: number): ArchivalStatus;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeArchivalResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeArchivalResponse): DescribeArchivalResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeArchivalResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeArchivalResponse;
+    static deserializeBinaryFromReader(message: DescribeArchivalResponse, reader: jspb.BinaryReader): DescribeArchivalResponse;
+}
+
+export namespace DescribeArchivalResponse {
+    export type AsObject = {
+        workspacesList: Array<ArchivalStatus.AsObject>,
+    }
+}
+
+export class ArchivalStatus extends jspb.Message { 
+    getWorkspaceId(): string;
+    setWorkspaceId(value: string): ArchivalStatus;
+
+    getOwner(): string;
+    setOwner(value: string): ArchivalStatus;
+
+    getPhase(): WorkspacePhase;
+    setPhase(value: WorkspacePhase): ArchivalStatus;
+
+
+    hasStoppedAt(): boolean;
+    clearStoppedAt(): void;
+    getStoppedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setStoppedAt(value?: google_protobuf_timestamp_pb.Timestamp): ArchivalStatus;
+
+
+    hasArchiveAt(): boolean;
+    clearArchiveAt(): void;
+    getArchiveAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setArchiveAt(value?: google_protobuf_timestamp_pb.Timestamp): ArchivalStatus;
+
+
+    hasArchivedAt(): boolean;
+    clearArchivedAt(): void;
+    getArchivedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setArchivedAt(value?: google_protobuf_timestamp_pb.Timestamp): ArchivalStatus;
+
+    getBackupSize(): number;
+    setBackupSize(value: number): ArchivalStatus;
+
+    getUnarchiving(): boolean;
+    setUnarchiving(value: boolean): ArchivalStatus;
+
+    getRestoreLatency(): string;
+    setRestoreLatency(value: string): ArchivalStatus;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ArchivalStatus.AsObject;
+    static toObject(includeInstance: boolean, msg: ArchivalStatus): ArchivalStatus.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ArchivalStatus, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ArchivalStatus;
+    static deserializeBinaryFromReader(message: ArchivalStatus, reader: jspb.BinaryReader): ArchivalStatus;
+}
+
+export namespace ArchivalStatus {
+    export type AsObject = {
+        workspaceId: string,
+        owner: string,
+        phase: WorkspacePhase,
+        stoppedAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        archiveAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        archivedAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        backupSize: number,
+        unarchiving: boolean,
+        restoreLatency: string,
+    }
+}
+
+export class UnarchiveWorkspaceRequest extends jspb.Message { 
+    getWorkspaceId(): string;
+    setWorkspaceId(value: string): UnarchiveWorkspaceRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): UnarchiveWorkspaceRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: UnarchiveWorkspaceRequest): UnarchiveWorkspaceRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: UnarchiveWorkspaceRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): UnarchiveWorkspaceRequest;
+    static deserializeBinaryFromReader(message: UnarchiveWorkspaceRequest, reader: jspb.BinaryReader): UnarchiveWorkspaceRequest;
+}
+
+export namespace UnarchiveWorkspaceRequest {
+    export type AsObject = {
+        workspaceId: string,
+    }
+}
+
+export class UnarchiveWorkspaceResponse extends jspb.Message { 
+
+    hasReadyAt(): boolean;
+    clearReadyAt(): void;
+    getReadyAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setReadyAt(value?: google_protobuf_timestamp_pb.Timestamp): UnarchiveWorkspaceResponse;
+
+    getRestoreLatency(): string;
+    setRestoreLatency(value: string): UnarchiveWorkspaceResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): UnarchiveWorkspaceResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: UnarchiveWorkspaceResponse): UnarchiveWorkspaceResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: UnarchiveWorkspaceResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): UnarchiveWorkspaceResponse;
+    static deserializeBinaryFromReader(message: UnarchiveWorkspaceResponse, reader: jspb.BinaryReader): UnarchiveWorkspaceResponse;
+}
+
+export namespace UnarchiveWorkspaceResponse {
+    export type AsObject = {
+        readyAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        restoreLatency: string,
+    }
+}
+
+export class DeleteWorkspaceRequest extends jspb.Message { 
+    getWorkspaceId(): string;
+    setWorkspaceId(value: string): DeleteWorkspaceRequest;
+
+    getOwner(): string;
+    setOwner(value: string): DeleteWorkspaceRequest;
+
+
+    getMetadataMap(): jspb.Map<string, string>;
+    clearMetadataMap(): void;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DeleteWorkspaceRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DeleteWorkspaceRequest): DeleteWorkspaceRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DeleteWorkspaceRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DeleteWorkspaceRequest;
+    static deserializeBinaryFromReader(message: DeleteWorkspaceRequest, reader: jspb.BinaryReader): DeleteWorkspaceRequest;
+}
+
+export namespace DeleteWorkspaceRequest {
+    export type AsObject = {
+        workspaceId: string,
+        owner: string,
+
+        metadataMap: Array<[string, string]>,
+    }
+}
+
+export class DeleteWorkspaceResponse extends jspb.Message { 
+
+    hasPurgeAt(): boolean;
+    clearPurgeAt(): void;
+    getPurgeAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setPurgeAt(value?: google_protobuf_timestamp_pb.Timestamp): DeleteWorkspaceResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DeleteWorkspaceResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DeleteWorkspaceResponse): DeleteWorkspaceResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DeleteWorkspaceResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DeleteWorkspaceResponse;
+    static deserializeBinaryFromReader(message: DeleteWorkspaceResponse, reader: jspb.BinaryReader): DeleteWorkspaceResponse;
+}
+
+export namespace DeleteWorkspaceResponse {
+    export type AsObject = {
+        purgeAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class RestoreDeletedWorkspaceRequest extends jspb.Message { 
+    getWorkspaceId(): string;
+    setWorkspaceId(value: string): RestoreDeletedWorkspaceRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): RestoreDeletedWorkspaceRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: RestoreDeletedWorkspaceRequest): RestoreDeletedWorkspaceRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: RestoreDeletedWorkspaceRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): RestoreDeletedWorkspaceRequest;
+    static deserializeBinaryFromReader(message: RestoreDeletedWorkspaceRequest, reader: jspb.BinaryReader): RestoreDeletedWorkspaceRequest;
+}
+
+export namespace RestoreDeletedWorkspaceRequest {
+    export type AsObject = {
+        workspaceId: string,
+    }
+}
+
+export class RestoreDeletedWorkspaceResponse extends jspb.Message { 
+
+    hasWorkspace(): boolean;
+    clearWorkspace(): void;
+    getWorkspace(): DeletedWorkspace | undefined;
+    setWorkspace(value?: DeletedWorkspace): RestoreDeletedWorkspaceResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): RestoreDeletedWorkspaceResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: RestoreDeletedWorkspaceResponse): RestoreDeletedWorkspaceResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: RestoreDeletedWorkspaceResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): RestoreDeletedWorkspaceResponse;
+    static deserializeBinaryFromReader(message: RestoreDeletedWorkspaceResponse, reader: jspb.BinaryReader): RestoreDeletedWorkspaceResponse;
+}
+
+export namespace RestoreDeletedWorkspaceResponse {
+    export type AsObject = {
+        workspace?: DeletedWorkspace.AsObject,
+    }
+}
+
+export class DescribeDeletedWorkspacesRequest extends jspb.Message { 
+    getOwner(): string;
+    setOwner(value: string): DescribeDeletedWorkspacesRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeDeletedWorkspacesRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeDeletedWorkspacesRequest): DescribeDeletedWorkspacesRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeDeletedWorkspacesRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeDeletedWorkspacesRequest;
+    static deserializeBinaryFromReader(message: DescribeDeletedWorkspacesRequest, reader: jspb.BinaryReader): DescribeDeletedWorkspacesRequest;
+}
+
+export namespace DescribeDeletedWorkspacesRequest {
+    export type AsObject = {
+        owner: string,
+    }
+}
+
+export class DescribeDeletedWorkspacesResponse extends jspb.Message { 
+    clearWorkspacesList(): void;
+    getWorkspacesList(): Array<DeletedWorkspace>;
+    setWorkspacesList(value: Array<DeletedWorkspace>): DescribeDeletedWorkspacesResponse;
+    addWorkspaces(value?: DeletedWorkspace, index?: number): DeletedWorkspace;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeDeletedWorkspacesResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeDeletedWorkspacesResponse): DescribeDeletedWorkspacesResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeDeletedWorkspacesResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeDeletedWorkspacesResponse;
+    static deserializeBinaryFromReader(message: DescribeDeletedWorkspacesResponse, reader: jspb.BinaryReader): DescribeDeletedWorkspacesResponse;
+}
+
+export namespace DescribeDeletedWorkspacesResponse {
+    export type AsObject = {
+        workspacesList: Array<DeletedWorkspace.AsObject>,
+    }
+}
+
+export class DeletedWorkspace extends jspb.Message { 
+    getWorkspaceId(): string;
+    setWorkspaceId(value: string): DeletedWorkspace;
+
+    getOwner(): string;
+    setOwner(value: string): DeletedWorkspace;
+
+
+    hasDeletedAt(): boolean;
+    clearDeletedAt(): void;
+    getDeletedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setDeletedAt(value?: google_protobuf_timestamp_pb.Timestamp): DeletedWorkspace;
+
+
+    hasPurgeAt(): boolean;
+    clearPurgeAt(): void;
+    getPurgeAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setPurgeAt(value?: google_protobuf_timestamp_pb.Timestamp): DeletedWorkspace;
+
+
+    getMetadataMap(): jspb.Map<string, string>;
+    clearMetadataMap(): void;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DeletedWorkspace.AsObject;
+    static toObject(includeInstance: boolean, msg: DeletedWorkspace): DeletedWorkspace.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DeletedWorkspace, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DeletedWorkspace;
+    static deserializeBinaryFromReader(message: DeletedWorkspace, reader: jspb.BinaryReader): DeletedWorkspace;
+}
+
+export namespace DeletedWorkspace {
+    export type AsObject = {
+        workspaceId: string,
+        owner: string,
+        deletedAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        purgeAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+
+        metadataMap: Array<[string, string]>,
+    }
+}
+
+export class WorkspaceTemplateSpec extends jspb.Message { 
+    getWorkspaceImage(): string;
+    setWorkspaceImage(value: string): WorkspaceTemplateSpec;
+
+    getIdeImage(): string;
+    setIdeImage(value: string): WorkspaceTemplateSpec;
+
+    getType(): WorkspaceType;
+    setType(value: WorkspaceType): WorkspaceTemplateSpec;
+
+    clearEnvvarsList(): void;
+    getEnvvarsList(): Array<EnvironmentVariable>;
+    setEnvvarsList(value: Array<EnvironmentVariable>): WorkspaceTemplateSpec;
+    addEnvvars(value?: EnvironmentVariable, index?: number): EnvironmentVariable;
+
+    getTasks(): string;
+    setTasks(value: string): WorkspaceTemplateSpec;
+
+    getTimeout(): string;
+    setTimeout(value: string): WorkspaceTemplateSpec;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceTemplateSpec.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceTemplateSpec): WorkspaceTemplateSpec.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceTemplateSpec, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceTemplateSpec;
+    static deserializeBinaryFromReader(message: WorkspaceTemplateSpec, reader: jspb.BinaryReader): WorkspaceTemplateSpec;
+}
+
+export namespace WorkspaceTemplateSpec {
+    export type AsObject = {
+        workspaceImage: string,
+        ideImage: string,
+        type: WorkspaceType,
+        envvarsList: Array<EnvironmentVariable.AsObject>,
+        tasks: string,
+        timeout: string,
+    }
+}
+
+export class WorkspaceTemplate extends jspb.Message { 
+    getId(): string;
+    setId(value: string): WorkspaceTemplate;
+
+    getName(): string;
+    setName(value: string): WorkspaceTemplate;
+
+    getOwner(): string;
+    setOwner(value: string): WorkspaceTemplate;
+
+    getTeamId(): string;
+    setTeamId(value: string): WorkspaceTemplate;
+
+    getVersion(): number;
+    setVersion(value: number): WorkspaceTemplate;
+
+    getLatestVersion(): number;
+    setLatestVersion(value: number): WorkspaceTemplate;
+
+
+    hasSpec(): boolean;
+    clearSpec(): void;
+    getSpec(): WorkspaceTemplateSpec | undefined;
+    setSpec(value?: WorkspaceTemplateSpec): WorkspaceTemplate;
+
+
+    hasCreated(): boolean;
+    clearCreated(): void;
+    getCreated(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setCreated(value?: google_protobuf_timestamp_pb.Timestamp): WorkspaceTemplate;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceTemplate.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceTemplate): WorkspaceTemplate.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceTemplate, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceTemplate;
+    static deserializeBinaryFromReader(message: WorkspaceTemplate, reader: jspb.BinaryReader): WorkspaceTemplate;
+}
+
+export namespace WorkspaceTemplate {
+    export type AsObject = {
+        id: string,
+        name: string,
+        owner: string,
+        teamId: string,
+        version: number,
+        latestVersion: number,
+        spec?: WorkspaceTemplateSpec.AsObject,
+        created?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class WorkspaceTemplateRef extends jspb.Message { 
+    getId(): string;
+    setId(value: string): WorkspaceTemplateRef;
+
+    getVersion(): number;
+    setVersion(value: number): WorkspaceTemplateRef;
+
+    getTeamId(): string;
+    setTeamId(value: string): WorkspaceTemplateRef;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceTemplateRef.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceTemplateRef): WorkspaceTemplateRef.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceTemplateRef, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceTemplateRef;
+    static deserializeBinaryFromReader(message: WorkspaceTemplateRef, reader: jspb.BinaryReader): WorkspaceTemplateRef;
+}
+
+export namespace WorkspaceTemplateRef {
+    export type AsObject = {
+        id: string,
+        version: number,
+        teamId: string,
+    }
+}
+
+export class CreateWorkspaceTemplateRequest extends jspb.Message { 
+    getName(): string;
+    setName(value: string): CreateWorkspaceTemplateRequest;
+
+    getOwner(): string;
+    setOwner(value: string): CreateWorkspaceTemplateRequest;
+
+    getTeamId(): string;
+    setTeamId(value: string): CreateWorkspaceTemplateRequest;
+
+
+    hasSpec(): boolean;
+    clearSpec(): void;
+    getSpec(): WorkspaceTemplateSpec | undefined;
+    setSpec(value?: WorkspaceTemplateSpec): CreateWorkspaceTemplateRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CreateWorkspaceTemplateRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: CreateWorkspaceTemplateRequest): CreateWorkspaceTemplateRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CreateWorkspaceTemplateRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CreateWorkspaceTemplateRequest;
+    static deserializeBinaryFromReader(message: CreateWorkspaceTemplateRequest, reader: jspb.BinaryReader): CreateWorkspaceTemplateRequest;
+}
+
+export namespace CreateWorkspaceTemplateRequest {
+    export type AsObject = {
+        name: string,
+        owner: string,
+        teamId: string,
+        spec?: WorkspaceTemplateSpec.AsObject,
+    }
+}
+
+export class CreateWorkspaceTemplateResponse extends jspb.Message { 
+
+    hasTemplate(): boolean;
+    clearTemplate(): void;
+    getTemplate(): WorkspaceTemplate | undefined;
+    setTemplate(value?: WorkspaceTemplate): CreateWorkspaceTemplateResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CreateWorkspaceTemplateResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: CreateWorkspaceTemplateResponse): CreateWorkspaceTemplateResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CreateWorkspaceTemplateResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CreateWorkspaceTemplateResponse;
+    static deserializeBinaryFromReader(message: CreateWorkspaceTemplateResponse, reader: jspb.BinaryReader): CreateWorkspaceTemplateResponse;
+}
+
+export namespace CreateWorkspaceTemplateResponse {
+    export type AsObject = {
+        template?: WorkspaceTemplate.AsObject,
+    }
+}
+
+export class UpdateWorkspaceTemplateRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): UpdateWorkspaceTemplateRequest;
+
+    getOwner(): string;
+    setOwner(value: string): UpdateWorkspaceTemplateRequest;
+
+
+    hasSpec(): boolean;
+    clearSpec(): void;
+    getSpec(): WorkspaceTemplateSpec | undefined;
+    setSpec(value?: WorkspaceTemplateSpec): UpdateWorkspaceTemplateRequest;
+
+    getLatestVersion(): number;
+    setLatestVersion(value: number): UpdateWorkspaceTemplateRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): UpdateWorkspaceTemplateRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: UpdateWorkspaceTemplateRequest): UpdateWorkspaceTemplateRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: UpdateWorkspaceTemplateRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): UpdateWorkspaceTemplateRequest;
+    static deserializeBinaryFromReader(message: UpdateWorkspaceTemplateRequest, reader: jspb.BinaryReader): UpdateWorkspaceTemplateRequest;
+}
+
+export namespace UpdateWorkspaceTemplateRequest {
+    export type AsObject = {
+        id: string,
+        owner: string,
+        spec?: WorkspaceTemplateSpec.AsObject,
+        latestVersion: number,
+    }
+}
+
+export class UpdateWorkspaceTemplateResponse extends jspb.Message { 
+
+    hasTemplate(): boolean;
+    clearTemplate(): void;
+    getTemplate(): WorkspaceTemplate | undefined;
+    setTemplate(value?: WorkspaceTemplate): UpdateWorkspaceTemplateResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): UpdateWorkspaceTemplateResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: UpdateWorkspaceTemplateResponse): UpdateWorkspaceTemplateResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: UpdateWorkspaceTemplateResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): UpdateWorkspaceTemplateResponse;
+    static deserializeBinaryFromReader(message: UpdateWorkspaceTemplateResponse, reader: jspb.BinaryReader): UpdateWorkspaceTemplateResponse;
+}
+
+export namespace UpdateWorkspaceTemplateResponse {
+    export type AsObject = {
+        template?: WorkspaceTemplate.AsObject,
+    }
+}
+
+export class GetWorkspaceTemplateRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): GetWorkspaceTemplateRequest;
+
+    getVersion(): number;
+    setVersion(value: number): GetWorkspaceTemplateRequest;
+
+    getUser(): string;
+    setUser(value: string): GetWorkspaceTemplateRequest;
+
+    getTeamId(): string;
+    setTeamId(value: string): GetWorkspaceTemplateRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GetWorkspaceTemplateRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: GetWorkspaceTemplateRequest): GetWorkspaceTemplateRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: GetWorkspaceTemplateRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GetWorkspaceTemplateRequest;
+    static deserializeBinaryFromReader(message: GetWorkspaceTemplateRequest, reader: jspb.BinaryReader): GetWorkspaceTemplateRequest;
+}
+
+export namespace GetWorkspaceTemplateRequest {
+    export type AsObject = {
+        id: string,
+        version: number,
+        user: string,
+        teamId: string,
+    }
+}
+
+export class GetWorkspaceTemplateResponse extends jspb.Message { 
+
+    hasTemplate(): boolean;
+    clearTemplate(): void;
+    getTemplate(): WorkspaceTemplate | undefined;
+    setTemplate(value?: WorkspaceTemplate): GetWorkspaceTemplateResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GetWorkspaceTemplateResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: GetWorkspaceTemplateResponse): GetWorkspaceTemplateResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: GetWorkspaceTemplateResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GetWorkspaceTemplateResponse;
+    static deserializeBinaryFromReader(message: GetWorkspaceTemplateResponse, reader: jspb.BinaryReader): GetWorkspaceTemplateResponse;
+}
+
+export namespace GetWorkspaceTemplateResponse {
+    export type AsObject = {
+        template?: WorkspaceTemplate.AsObject,
+    }
+}
+
+export class ListWorkspaceTemplatesRequest extends jspb.Message { 
+    getUser(): string;
+    setUser(value: string): ListWorkspaceTemplatesRequest;
+
+    getTeamId(): string;
+    setTeamId(value: string): ListWorkspaceTemplatesRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ListWorkspaceTemplatesRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ListWorkspaceTemplatesRequest): ListWorkspaceTemplatesRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ListWorkspaceTemplatesRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ListWorkspaceTemplatesRequest;
+    static deserializeBinaryFromReader(message: ListWorkspaceTemplatesRequest, reader: jspb.BinaryReader): ListWorkspaceTemplatesRequest;
+}
+
+export namespace ListWorkspaceTemplatesRequest {
+    export type AsObject = {
+        user: string,
+        teamId: string,
+    }
+}
+
+export class ListWorkspaceTemplatesResponse extends jspb.Message { 
+    clearTemplatesList(): void;
+    getTemplatesList(): Array<WorkspaceTemplate>;
+    setTemplatesList(value: Array<WorkspaceTemplate>): ListWorkspaceTemplatesResponse;
+    addTemplates(value?: WorkspaceTemplate, index?: number): WorkspaceTemplate;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ListWorkspaceTemplatesResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ListWorkspaceTemplatesResponse): ListWorkspaceTemplatesResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ListWorkspaceTemplatesResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ListWorkspaceTemplatesResponse;
+    static deserializeBinaryFromReader(message: ListWorkspaceTemplatesResponse, reader: jspb.BinaryReader): ListWorkspaceTemplatesResponse;
+}
+
+export namespace ListWorkspaceTemplatesResponse {
+    export type AsObject = {
+        templatesList: Array<WorkspaceTemplate.AsObject>,
+    }
+}
+
+export class DeleteWorkspaceTemplateRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): DeleteWorkspaceTemplateRequest;
+
+    getOwner(): string;
+    setOwner(value: string): DeleteWorkspaceTemplateRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DeleteWorkspaceTemplateRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DeleteWorkspaceTemplateRequest): DeleteWorkspaceTemplateRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DeleteWorkspaceTemplateRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DeleteWorkspaceTemplateRequest;
+    static deserializeBinaryFromReader(message: DeleteWorkspaceTemplateRequest, reader: jspb.BinaryReader): DeleteWorkspaceTemplateRequest;
+}
+
+export namespace DeleteWorkspaceTemplateRequest {
+    export type AsObject = {
+        id: string,
+        owner: string,
+    }
+}
+
+export class DeleteWorkspaceTemplateResponse extends jspb.Message { 
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DeleteWorkspaceTemplateResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DeleteWorkspaceTemplateResponse): DeleteWorkspaceTemplateResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DeleteWorkspaceTemplateResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DeleteWorkspaceTemplateResponse;
+    static deserializeBinaryFromReader(message: DeleteWorkspaceTemplateResponse, reader: jspb.BinaryReader): DeleteWorkspaceTemplateResponse;
+}
+
+export namespace DeleteWorkspaceTemplateResponse {
+    export type AsObject = {
+    }
+}
+
+export class MaintenanceStatus extends jspb.Message { 
+    getEnabled(): boolean;
+    setEnabled(value: boolean): MaintenanceStatus;
+
+    getMessage(): string;
+    setMessage(value: string): MaintenanceStatus;
+
+
+    hasStartsAt(): boolean;
+    clearStartsAt(): void;
+    getStartsAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setStartsAt(value?: google_protobuf_timestamp_pb.Timestamp): MaintenanceStatus;
+
+
+    hasEndsAt(): boolean;
+    clearEndsAt(): void;
+    getEndsAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setEndsAt(value?: google_protobuf_timestamp_pb.Timestamp): MaintenanceStatus;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): MaintenanceStatus.AsObject;
+    static toObject(includeInstance: boolean, msg: MaintenanceStatus): MaintenanceStatus.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: MaintenanceStatus, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): MaintenanceStatus;
+    static deserializeBinaryFromReader(message: MaintenanceStatus, reader: jspb.BinaryReader): MaintenanceStatus;
+}
+
+export namespace MaintenanceStatus {
+    export type AsObject = {
+        enabled: boolean,
+        message: string,
+        startsAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        endsAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class WorkspaceStatus extends jspb.Message { 
+    getId(): string;
+    setId(value: string): WorkspaceStatus;
+
+
+    hasMetadata(): boolean;
+    clearMetadata(): void;
+    getMetadata(): WorkspaceMetadata | undefined;
+    setMetadata(value?: WorkspaceMetadata): WorkspaceStatus;
+
+
+    hasSpec(): boolean;
+    clearSpec(): void;
+    getSpec(): WorkspaceSpec | undefined;
+    setSpec(value?: WorkspaceSpec): WorkspaceStatus;
+
+    getPhase(): WorkspacePhase;
+    setPhase(value: WorkspacePhase): WorkspaceStatus;
+
+
+    hasConditions(): boolean;
+    clearConditions(): void;
+    getConditions(): WorkspaceConditions | undefined;
+    setConditions(value?: WorkspaceConditions): WorkspaceStatus;
+
+    getMessage(): string;
+    setMessage(value: string): WorkspaceStatus;
+
+
+    hasRepo(): boolean;
+    clearRepo(): void;
+    getRepo(): content_service_api_initializer_pb.GitStatus | undefined;
+    setRepo(value?: content_service_api_initializer_pb.GitStatus): WorkspaceStatus;
+
+
+    hasRuntime(): boolean;
+    clearRuntime(): void;
+    getRuntime(): WorkspaceRuntimeInfo | undefined;
+    setRuntime(value?: WorkspaceRuntimeInfo): WorkspaceStatus;
+
+
+    hasAuth(): boolean;
+    clearAuth(): void;
+    getAuth(): WorkspaceAuthentication | undefined;
+    setAuth(value?: WorkspaceAuthentication): WorkspaceStatus;
+
+    getGeneration(): number;
+    setGeneration(value: number): WorkspaceStatus;
+
+    clearInitContainersList(): void;
+    getInitContainersList(): Array<InitContainerStatus>;
+    setInitContainersList(value: Array<InitContainerStatus>): WorkspaceStatus;
+    addInitContainers(value?: InitContainerStatus, index?: number): InitContainerStatus;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceStatus.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceStatus): WorkspaceStatus.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceStatus, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceStatus;
+    static deserializeBinaryFromReader(message: WorkspaceStatus, reader: jspb.BinaryReader): WorkspaceStatus;
+}
+
+export namespace WorkspaceStatus {
+    export type AsObject = {
+        id: string,
+        metadata?: WorkspaceMetadata.AsObject,
+        spec?: WorkspaceSpec.AsObject,
+        phase: WorkspacePhase,
+        conditions?: WorkspaceConditions.AsObject,
+        message: string,
+        repo?: content_service_api_initializer_pb.GitStatus.AsObject,
+        runtime?: WorkspaceRuntimeInfo.AsObject,
+        auth?: WorkspaceAuthentication.AsObject,
+        generation: number,
+        initContainersList: Array<InitContainerStatus.AsObject>,
+    }
+}
+
+export class InitContainerStatus extends jspb.Message { 
+    getName(): string;
+    setName(value: string): InitContainerStatus;
+
+    getState(): InitContainerState;
+    setState(value: InitContainerState): InitContainerStatus;
+
+    getExitCode(): number;
+    setExitCode(value: number): InitContainerStatus;
+
+    getMessage(): string;
+    setMessage(value: string): InitContainerStatus;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): InitContainerStatus.AsObject;
+    static toObject(includeInstance: boolean, msg: InitContainerStatus): InitContainerStatus.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: InitContainerStatus, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): InitContainerStatus;
+    static deserializeBinaryFromReader(message: InitContainerStatus, reader: jspb.BinaryReader): InitContainerStatus;
+}
+
+export namespace InitContainerStatus {
+    export type AsObject = {
+        name: string,
+        state: InitContainerState,
+        exitCode: number,
+        message: string,
+    }
+}
+
+export class WorkspaceSpec extends jspb.Message { 
+    getWorkspaceImage(): string;
+    setWorkspaceImage(value: string): WorkspaceSpec;
+
+    getIdeImage(): string;
+    setIdeImage(value: string): WorkspaceSpec;
+
+    getHeadless(): boolean;
+    setHeadless(value: boolean): WorkspaceSpec;
+
+    getUrl(): string;
+    setUrl(value: string): WorkspaceSpec;
+
+    clearExposedPortsList(): void;
+    getExposedPortsList(): Array<PortSpec>;
+    setExposedPortsList(value: Array<PortSpec>): WorkspaceSpec;
+    addExposedPorts(value?: PortSpec, index?: number): PortSpec;
+
+    getType(): WorkspaceType;
+    setType(value: WorkspaceType): WorkspaceSpec;
+
+    getTimeout(): string;
+    setTimeout(value: string): WorkspaceSpec;
+
+
+    getExperimentsMap(): jspb.Map<string, string>;
+    clearExperimentsMap(): void;
+
+
+    hasScheduledStop(): boolean;
+    clearScheduledStop(): void;
+    getScheduledStop(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setScheduledStop(value?: google_protobuf_timestamp_pb.Timestamp): WorkspaceSpec;
+
+
+    hasIdePin(): boolean;
+    clearIdePin(): void;
+    getIdePin(): IDEPin | undefined;
+    setIdePin(value?: IDEPin): WorkspaceSpec;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceSpec.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceSpec): WorkspaceSpec.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceSpec, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceSpec;
+    static deserializeBinaryFromReader(message: WorkspaceSpec, reader: jspb.BinaryReader): WorkspaceSpec;
+}
+
+export namespace WorkspaceSpec {
+    export type AsObject = {
+        workspaceImage: string,
+        ideImage: string,
+        headless: boolean,
+        url: string,
+        exposedPortsList: Array<PortSpec.AsObject>,
+        type: WorkspaceType,
+        timeout: string,
+
+        experimentsMap: Array<[string, string]>,
+        scheduledStop?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        idePin?: IDEPin.AsObject,
+    }
+}
+
+export class IDEPin extends jspb.Message { 
+    getPinnedImage(): string;
+    setPinnedImage(value: string): IDEPin;
+
+    getDefaultImage(): string;
+    setDefaultImage(value: string): IDEPin;
+
+    getSupervisorVersion(): string;
+    setSupervisorVersion(value: string): IDEPin;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): IDEPin.AsObject;
+    static toObject(includeInstance: boolean, msg: IDEPin): IDEPin.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: IDEPin, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): IDEPin;
+    static deserializeBinaryFromReader(message: IDEPin, reader: jspb.BinaryReader): IDEPin;
+}
+
+export namespace IDEPin {
+    export type AsObject = {
+        pinnedImage: string,
+        defaultImage: string,
+        supervisorVersion: string,
+    }
+}
+
+export class ResetIDEPinRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): ResetIDEPinRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ResetIDEPinRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ResetIDEPinRequest): ResetIDEPinRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ResetIDEPinRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ResetIDEPinRequest;
+    static deserializeBinaryFromReader(message: ResetIDEPinRequest, reader: jspb.BinaryReader): ResetIDEPinRequest;
+}
+
+export namespace ResetIDEPinRequest {
+    export type AsObject = {
+        id: string,
+    }
+}
+
+export class ResetIDEPinResponse extends jspb.Message { 
+    getIdeImage(): string;
+    setIdeImage(value: string): ResetIDEPinResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ResetIDEPinResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ResetIDEPinResponse): ResetIDEPinResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ResetIDEPinResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ResetIDEPinResponse;
+    static deserializeBinaryFromReader(message: ResetIDEPinResponse, reader: jspb.BinaryReader): ResetIDEPinResponse;
+}
+
+export namespace ResetIDEPinResponse {
+    export type AsObject = {
+        ideImage: string,
+    }
+}
+
+export class BulkOperationFilter extends jspb.Message { 
+    clearOwnersList(): void;
+    getOwnersList(): Array<string>;
+    setOwnersList(value: Array<string>): BulkOperationFilter;
+    addOwners(value: string, index?: number): string;
+
+    clearProjectsList(): void;
+    getProjectsList(): Array<string>;
+    setProjectsList(value: Array<string>): BulkOperationFilter;
+    addProjects(value: string, index?: number): string;
+
+    clearTypesList(): void;
+    getTypesList(): Array<WorkspaceType>;
+    setTypesList(value: Array<WorkspaceType>): BulkOperationFilter;
+    addTypes(value: WorkspaceType, index?: number): WorkspaceType;
+
+
+    hasStartedBefore(): boolean;
+    clearStartedBefore(): void;
+    getStartedBefore(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setStartedBefore(value?: google_protobuf_timestamp_pb.Timestamp): BulkOperationFilter;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BulkOperationFilter.AsObject;
+    static toObject(includeInstance: boolean, msg: BulkOperationFilter): BulkOperationFilter.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BulkOperationFilter, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BulkOperationFilter;
+    static deserializeBinaryFromReader(message: BulkOperationFilter, reader: jspb.BinaryReader): BulkOperationFilter;
+}
+
+export namespace BulkOperationFilter {
+    export type AsObject = {
+        ownersList: Array<string>,
+        projectsList: Array<string>,
+        typesList: Array<WorkspaceType>,
+        startedBefore?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class StartBulkOperationRequest extends jspb.Message { 
+
+    hasFilter(): boolean;
+    clearFilter(): void;
+    getFilter(): BulkOperationFilter | undefined;
+    setFilter(value?: BulkOperationFilter): StartBulkOperationRequest;
+
+
+    hasStop(): boolean;
+    clearStop(): void;
+    getStop(): BulkStopOperation | undefined;
+    setStop(value?: BulkStopOperation): StartBulkOperationRequest;
+
+
+    hasSetTimeout(): boolean;
+    clearSetTimeout(): void;
+    getSetTimeout(): BulkSetTimeoutOperation | undefined;
+    setSetTimeout(value?: BulkSetTimeoutOperation): StartBulkOperationRequest;
+
+
+    hasMigrate(): boolean;
+    clearMigrate(): void;
+    getMigrate(): BulkMigrateOperation | undefined;
+    setMigrate(value?: BulkMigrateOperation): StartBulkOperationRequest;
+
+    getConcurrency(): number;
+    setConcurrency(value: number): StartBulkOperationRequest;
+
+    getDryRun(): boolean;
+    setDryRun(value: boolean): StartBulkOperationRequest;
+
+
+    getOperationCase(): StartBulkOperationRequest.OperationCase;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): StartBulkOperationRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: StartBulkOperationRequest): StartBulkOperationRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: StartBulkOperationRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StartBulkOperationRequest;
+    static deserializeBinaryFromReader(message: StartBulkOperationRequest, reader: jspb.BinaryReader): StartBulkOperationRequest;
+}
+
+export namespace StartBulkOperationRequest {
+    export type AsObject = {
+        filter?: BulkOperationFilter.AsObject,
+        stop?: BulkStopOperation.AsObject,
+        setTimeout?: BulkSetTimeoutOperation.AsObject,
+        migrate?: BulkMigrateOperation.AsObject,
+        concurrency: number,
+        dryRun: boolean,
+    }
+
+    export enum OperationCase {
+        OPERATION_NOT_SET = 0,
+    
+    STOP = 2,
+
+    SET_TIMEOUT = 3,
+
+    MIGRATE = 4,
+
+    }
+
+}
+
+export class BulkStopOperation extends jspb.Message { 
+    getPolicy(): StopWorkspacePolicy;
+    setPolicy(value: StopWorkspacePolicy): BulkStopOperation;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BulkStopOperation.AsObject;
+    static toObject(includeInstance: boolean, msg: BulkStopOperation): BulkStopOperation.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BulkStopOperation, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BulkStopOperation;
+    static deserializeBinaryFromReader(message: BulkStopOperation, reader: jspb.BinaryReader): BulkStopOperation;
+}
+
+export namespace BulkStopOperation {
+    export type AsObject = {
+        policy: StopWorkspacePolicy,
+    }
+}
+
+export class BulkSetTimeoutOperation extends jspb.Message { 
+    getDuration(): string;
+    setDuration(value: string): BulkSetTimeoutOperation;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BulkSetTimeoutOperation.AsObject;
+    static toObject(includeInstance: boolean, msg: BulkSetTimeoutOperation): BulkSetTimeoutOperation.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BulkSetTimeoutOperation, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BulkSetTimeoutOperation;
+    static deserializeBinaryFromReader(message: BulkSetTimeoutOperation, reader: jspb.BinaryReader): BulkSetTimeoutOperation;
+}
+
+export namespace BulkSetTimeoutOperation {
+    export type AsObject = {
+        duration: string,
+    }
+}
+
+export class BulkMigrateOperation extends jspb.Message { 
+    getTargetCluster(): string;
+    setTargetCluster(value: string): BulkMigrateOperation;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BulkMigrateOperation.AsObject;
+    static toObject(includeInstance: boolean, msg: BulkMigrateOperation): BulkMigrateOperation.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BulkMigrateOperation, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BulkMigrateOperation;
+    static deserializeBinaryFromReader(message: BulkMigrateOperation, reader: jspb.BinaryReader): BulkMigrateOperation;
+}
+
+export namespace BulkMigrateOperation {
+    export type AsObject = {
+        targetCluster: string,
+    }
+}
+
+export class StartBulkOperationResponse extends jspb.Message { 
+    getId(): string;
+    setId(value: string): StartBulkOperationResponse;
+
+    clearWorkspaceIdsList(): void;
+    getWorkspaceIdsList(): Array<string>;
+    setWorkspaceIdsList(value: Array<string>): StartBulkOperationResponse;
+    addWorkspaceIds(value: string, index?: number): string;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): StartBulkOperationResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: StartBulkOperationResponse): StartBulkOperationResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: StartBulkOperationResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): StartBulkOperationResponse;
+    static deserializeBinaryFromReader(message: StartBulkOperationResponse, reader: jspb.BinaryReader): StartBulkOperationResponse;
+}
+
+export namespace StartBulkOperationResponse {
+    export type AsObject = {
+        id: string,
+        workspaceIdsList: Array<string>,
+    }
+}
+
+export class BulkOperationStatus extends jspb.Message { 
+    getId(): string;
+    setId(value: string): BulkOperationStatus;
+
+    getState(): BulkOperationState;
+    setState(value: BulkOperationState): BulkOperationStatus;
+
+    getTotal(): number;
+    setTotal(value: number): BulkOperationStatus;
+
+    getSucceeded(): number;
+    setSucceeded(value: number): BulkOperationStatus;
+
+    getFailed(): number;
+    setFailed(value: number): BulkOperationStatus;
+
+
+    hasCreatedAt(): boolean;
+    clearCreatedAt(): void;
+    getCreatedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setCreatedAt(value?: google_protobuf_timestamp_pb.Timestamp): BulkOperationStatus;
+
+
+    hasCompletedAt(): boolean;
+    clearCompletedAt(): void;
+    getCompletedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setCompletedAt(value?: google_protobuf_timestamp_pb.Timestamp): BulkOperationStatus;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BulkOperationStatus.AsObject;
+    static toObject(includeInstance: boolean, msg: BulkOperationStatus): BulkOperationStatus.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BulkOperationStatus, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BulkOperationStatus;
+    static deserializeBinaryFromReader(message: BulkOperationStatus, reader: jspb.BinaryReader): BulkOperationStatus;
+}
+
+export namespace BulkOperationStatus {
+    export type AsObject = {
+        id: string,
+        state: BulkOperationState,
+        total: number,
+        succeeded: number,
+        failed: number,
+        createdAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        completedAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class BulkOperationResult extends jspb.Message { 
+    getId(): string;
+    setId(value: string): BulkOperationResult;
+
+
+    hasMetadata(): boolean;
+    clearMetadata(): void;
+    getMetadata(): WorkspaceMetadata | undefined;
+    setMetadata(value?: WorkspaceMetadata): BulkOperationResult;
+
+    getError(): string;
+    setError(value: string): BulkOperationResult;
+
+
+    hasCompletedAt(): boolean;
+    clearCompletedAt(): void;
+    getCompletedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setCompletedAt(value?: google_protobuf_timestamp_pb.Timestamp): BulkOperationResult;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BulkOperationResult.AsObject;
+    static toObject(includeInstance: boolean, msg: BulkOperationResult): BulkOperationResult.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BulkOperationResult, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BulkOperationResult;
+    static deserializeBinaryFromReader(message: BulkOperationResult, reader: jspb.BinaryReader): BulkOperationResult;
+}
+
+export namespace BulkOperationResult {
+    export type AsObject = {
+        id: string,
+        metadata?: WorkspaceMetadata.AsObject,
+        error: string,
+        completedAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    }
+}
+
+export class DescribeBulkOperationRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): DescribeBulkOperationRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeBulkOperationRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeBulkOperationRequest): DescribeBulkOperationRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeBulkOperationRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeBulkOperationRequest;
+    static deserializeBinaryFromReader(message: DescribeBulkOperationRequest, reader: jspb.BinaryReader): DescribeBulkOperationRequest;
+}
+
+export namespace DescribeBulkOperationRequest {
+    export type AsObject = {
+        id: string,
+    }
+}
+
+export class DescribeBulkOperationResponse extends jspb.Message { 
+
+    hasStatus(): boolean;
+    clearStatus(): void;
+    getStatus(): BulkOperationStatus | undefined;
+    setStatus(value?: BulkOperationStatus): DescribeBulkOperationResponse;
+
+    clearResultsList(): void;
+    getResultsList(): Array<BulkOperationResult>;
+    setResultsList(value: Array<BulkOperationResult>): DescribeBulkOperationResponse;
+    addResults(value?: BulkOperationResult, index?: number): BulkOperationResult;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): DescribeBulkOperationResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: DescribeBulkOperationResponse): DescribeBulkOperationResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: DescribeBulkOperationResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): DescribeBulkOperationResponse;
+    static deserializeBinaryFromReader(message: DescribeBulkOperationResponse, reader: jspb.BinaryReader): DescribeBulkOperationResponse;
+}
+
+export namespace DescribeBulkOperationResponse {
+    export type AsObject = {
+        status?: BulkOperationStatus.AsObject,
+        resultsList: Array<BulkOperationResult.AsObject>,
+    }
+}
+
+export class WatchBulkOperationRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): WatchBulkOperationRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WatchBulkOperationRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: WatchBulkOperationRequest): WatchBulkOperationRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WatchBulkOperationRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WatchBulkOperationRequest;
+    static deserializeBinaryFromReader(message: WatchBulkOperationRequest, reader: jspb.BinaryReader): WatchBulkOperationRequest;
+}
+
+export namespace WatchBulkOperationRequest {
+    export type AsObject = {
+        id: string,
+    }
+}
+
+export class BulkOperationUpdate extends jspb.Message { 
+
+    hasStatus(): boolean;
+    clearStatus(): void;
+    getStatus(): BulkOperationStatus | undefined;
+    setStatus(value?: BulkOperationStatus): BulkOperationUpdate;
+
+
+    hasResult(): boolean;
+    clearResult(): void;
+    getResult(): BulkOperationResult | undefined;
+    setResult(value?: BulkOperationResult): BulkOperationUpdate;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): BulkOperationUpdate.AsObject;
+    static toObject(includeInstance: boolean, msg: BulkOperationUpdate): BulkOperationUpdate.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: BulkOperationUpdate, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): BulkOperationUpdate;
+    static deserializeBinaryFromReader(message: BulkOperationUpdate, reader: jspb.BinaryReader): BulkOperationUpdate;
+}
+
+export namespace BulkOperationUpdate {
+    export type AsObject = {
+        status?: BulkOperationStatus.AsObject,
+        result?: BulkOperationResult.AsObject,
+    }
+}
+
+export class CancelBulkOperationRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): CancelBulkOperationRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CancelBulkOperationRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: CancelBulkOperationRequest): CancelBulkOperationRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CancelBulkOperationRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CancelBulkOperationRequest;
+    static deserializeBinaryFromReader(message: CancelBulkOperationRequest, reader: jspb.BinaryReader): CancelBulkOperationRequest;
+}
+
+export namespace CancelBulkOperationRequest {
+    export type AsObject = {
+        id: string,
+    }
+}
+
+export class CancelBulkOperationResponse extends jspb.Message { 
+
+    hasStatus(): boolean;
+    clearStatus(): void;
+    getStatus(): BulkOperationStatus | undefined;
+    setStatus(value?: BulkOperationStatus): CancelBulkOperationResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): CancelBulkOperationResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: CancelBulkOperationResponse): CancelBulkOperationResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: CancelBulkOperationResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): CancelBulkOperationResponse;
+    static deserializeBinaryFromReader(message: CancelBulkOperationResponse, reader: jspb.BinaryReader): CancelBulkOperationResponse;
+}
+
+export namespace CancelBulkOperationResponse {
+    export type AsObject = {
+        status?: BulkOperationStatus.AsObject,
+    }
+}
+
+export class AuditRecord extends jspb.Message { 
+    getSequence(): number;
+    setSequence(value: number): AuditRecord;
+
+
+    hasTime(): boolean;
+    clearTime(): void;
+    getTime(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setTime(value?: google_protobuf_timestamp_pb.Timestamp): AuditRecord;
+
+    getAction(): string;
+    setAction(value: string): AuditRecord;
+
+    getId(): string;
+    setId(value: string): AuditRecord;
+
+
+    hasMetadata(): boolean;
+    clearMetadata(): void;
+    getMetadata(): WorkspaceMetadata | undefined;
+    setMetadata(value?: WorkspaceMetadata): AuditRecord;
+
+    getActor(): string;
+    setActor(value: string): AuditRecord;
+
+    getSource(): string;
+    setSource(value: string): AuditRecord;
+
+    getPeer(): string;
+    setPeer(value: string): AuditRecord;
+
+
+    getParametersMap(): jspb.Map<string, string>;
+    clearParametersMap(): void;
+
+    getError(): string;
+    setError(value: string): AuditRecord;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): AuditRecord.AsObject;
+    static toObject(includeInstance: boolean, msg: AuditRecord): AuditRecord.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: AuditRecord, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): AuditRecord;
+    static deserializeBinaryFromReader(message: AuditRecord, reader: jspb.BinaryReader): AuditRecord;
+}
+
+export namespace AuditRecord {
+    export type AsObject = {
+        sequence: number,
+        time?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        action: string,
+        id: string,
+        metadata?: WorkspaceMetadata.AsObject,
+        actor: string,
+        source: string,
+        peer: string,
+
+        parametersMap: Array<[string, string]>,
+        error: string,
+    }
+}
+
+export class QueryAuditLogRequest extends jspb.Message { 
+    getId(): string;
+    setId(value: string): QueryAuditLogRequest;
+
+    getMetaId(): string;
+    setMetaId(value: string): QueryAuditLogRequest;
+
+    getOwner(): string;
+    setOwner(value: string): QueryAuditLogRequest;
+
+    clearActionsList(): void;
+    getActionsList(): Array<string>;
+    setActionsList(value: Array<string>): QueryAuditLogRequest;
+    addActions(value: string, index?: number): string;
+
+
+    hasSince(): boolean;
+    clearSince(): void;
+    getSince(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setSince(value?: google_protobuf_timestamp_pb.Timestamp): QueryAuditLogRequest;
+
+
+    hasUntil(): boolean;
+    clearUntil(): void;
+    getUntil(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setUntil(value?: google_protobuf_timestamp_pb.Timestamp): QueryAuditLogRequest;
+
+    getAfterSequence(): number;
+    setAfterSequence(value: number): QueryAuditLogRequest;
+
+    getLimit(): number;
+    setLimit(value: number): QueryAuditLogRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): QueryAuditLogRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: QueryAuditLogRequest): QueryAuditLogRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: QueryAuditLogRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): QueryAuditLogRequest;
+    static deserializeBinaryFromReader(message: QueryAuditLogRequest, reader: jspb.BinaryReader): QueryAuditLogRequest;
+}
+
+export namespace QueryAuditLogRequest {
+    export type AsObject = {
+        id: string,
+        metaId: string,
+        owner: string,
+        actionsList: Array<string>,
+        since?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        until?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        afterSequence: number,
+        limit: number,
+    }
+}
+
+export class QueryAuditLogResponse extends jspb.Message { 
+    clearRecordsList(): void;
+    getRecordsList(): Array<AuditRecord>;
+    setRecordsList(value: Array<AuditRecord>): QueryAuditLogResponse;
+    addRecords(value?: AuditRecord, index?: number): AuditRecord;
+
+    getMore(): boolean;
+    setMore(value: boolean): QueryAuditLogResponse;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): QueryAuditLogResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: QueryAuditLogResponse): QueryAuditLogResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: QueryAuditLogResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): QueryAuditLogResponse;
+    static deserializeBinaryFromReader(message: QueryAuditLogResponse, reader: jspb.BinaryReader): QueryAuditLogResponse;
+}
+
+export namespace QueryAuditLogResponse {
+    export type AsObject = {
+        recordsList: Array<AuditRecord.AsObject>,
+        more: boolean,
+    }
+}
+
+export class SubscribeAuditLogRequest extends jspb.Message { 
+    getAfterSequence(): number;
+    setAfterSequence(value: number): SubscribeAuditLogRequest;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): SubscribeAuditLogRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: SubscribeAuditLogRequest): SubscribeAuditLogRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: SubscribeAuditLogRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): SubscribeAuditLogRequest;
+    static deserializeBinaryFromReader(message: SubscribeAuditLogRequest, reader: jspb.BinaryReader): SubscribeAuditLogRequest;
+}
+
+export namespace SubscribeAuditLogRequest {
+    export type AsObject = {
+        afterSequence: number,
+    }
+}
+
+export class PortSpec extends jspb.Message { 
+    getPort(): number;
+    setPort(value: number): PortSpec;
+
+    getTarget(): number;
+    setTarget(value: number): PortSpec;
+
+    getVisibility(): PortVisibility;
+    setVisibility(value: PortVisibility): PortSpec;
+
+    getUrl(): string;
+    setUrl(value: string): PortSpec;
+
+    getPermissiveCors(): boolean;
+    setPermissiveCors(value: boolean): PortSpec;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): PortSpec.AsObject;
+    static toObject(includeInstance: boolean, msg: PortSpec): PortSpec.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: PortSpec, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): PortSpec;
+    static deserializeBinaryFromReader(message: PortSpec, reader: jspb.BinaryReader): PortSpec;
+}
+
+export namespace PortSpec {
+    export type AsObject = {
+        port: number,
+        target: number,
+        visibility: PortVisibility,
+        url: string,
+        permissiveCors: boolean,
+    }
+}
+
+export class WorkspaceConditions extends jspb.Message { 
+    getFailed(): string;
+    setFailed(value: string): WorkspaceConditions;
+
+    getTimeout(): string;
+    setTimeout(value: string): WorkspaceConditions;
+
+    getPullingImages(): WorkspaceConditionBool;
+    setPullingImages(value: WorkspaceConditionBool): WorkspaceConditions;
+
+    getServiceExists(): WorkspaceConditionBool;
+    setServiceExists(value: WorkspaceConditionBool): WorkspaceConditions;
+
+    getSnapshot(): string;
+    setSnapshot(value: string): WorkspaceConditions;
+
+    getFinalBackupComplete(): WorkspaceConditionBool;
+    setFinalBackupComplete(value: WorkspaceConditionBool): WorkspaceConditions;
+
+    getDeployed(): WorkspaceConditionBool;
+    setDeployed(value: WorkspaceConditionBool): WorkspaceConditions;
+
+    getNetworkNotReady(): WorkspaceConditionBool;
+    setNetworkNotReady(value: WorkspaceConditionBool): WorkspaceConditions;
+
+
+    hasFirstUserActivity(): boolean;
+    clearFirstUserActivity(): void;
+    getFirstUserActivity(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setFirstUserActivity(value?: google_protobuf_timestamp_pb.Timestamp): WorkspaceConditions;
+
+    getPaused(): WorkspaceConditionBool;
+    setPaused(value: WorkspaceConditionBool): WorkspaceConditions;
+
+    getMigrateTo(): string;
+    setMigrateTo(value: string): WorkspaceConditions;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceConditions.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceConditions): WorkspaceConditions.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceConditions, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceConditions;
+    static deserializeBinaryFromReader(message: WorkspaceConditions, reader: jspb.BinaryReader): WorkspaceConditions;
+}
+
+export namespace WorkspaceConditions {
+    export type AsObject = {
+        failed: string,
+        timeout: string,
+        pullingImages: WorkspaceConditionBool,
+        serviceExists: WorkspaceConditionBool,
+        snapshot: string,
+        finalBackupComplete: WorkspaceConditionBool,
+        deployed: WorkspaceConditionBool,
+        networkNotReady: WorkspaceConditionBool,
+        firstUserActivity?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        paused: WorkspaceConditionBool,
+        migrateTo: string,
+    }
+}
+
+export class WorkspaceMetadata extends jspb.Message { 
+    getOwner(): string;
+    setOwner(value: string): WorkspaceMetadata;
+
+    getMetaId(): string;
+    setMetaId(value: string): WorkspaceMetadata;
+
+
+    hasStartedAt(): boolean;
+    clearStartedAt(): void;
+    getStartedAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+    setStartedAt(value?: google_protobuf_timestamp_pb.Timestamp): WorkspaceMetadata;
+
+    getGroupId(): string;
+    setGroupId(value: string): WorkspaceMetadata;
+
+    getProject(): string;
+    setProject(value: string): WorkspaceMetadata;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceMetadata.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceMetadata): WorkspaceMetadata.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceMetadata, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceMetadata;
+    static deserializeBinaryFromReader(message: WorkspaceMetadata, reader: jspb.BinaryReader): WorkspaceMetadata;
+}
+
+export namespace WorkspaceMetadata {
+    export type AsObject = {
+        owner: string,
+        metaId: string,
+        startedAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+        groupId: string,
+        project: string,
+    }
+}
+
+export class WorkspaceRuntimeInfo extends jspb.Message { 
+    getNodeName(): string;
+    setNodeName(value: string): WorkspaceRuntimeInfo;
+
+    getPodName(): string;
+    setPodName(value: string): WorkspaceRuntimeInfo;
+
+    getNodeIp(): string;
+    setNodeIp(value: string): WorkspaceRuntimeInfo;
+
+    getPodIp(): string;
+    setPodIp(value: string): WorkspaceRuntimeInfo;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceRuntimeInfo.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceRuntimeInfo): WorkspaceRuntimeInfo.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceRuntimeInfo, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceRuntimeInfo;
+    static deserializeBinaryFromReader(message: WorkspaceRuntimeInfo, reader: jspb.BinaryReader): WorkspaceRuntimeInfo;
+}
+
+export namespace WorkspaceRuntimeInfo {
+    export type AsObject = {
+        nodeName: string,
+        podName: string,
+        nodeIp: string,
+        podIp: string,
+    }
+}
+
+export class WorkspaceAuthentication extends jspb.Message { 
+    getAdmission(): AdmissionLevel;
+    setAdmission(value: AdmissionLevel): WorkspaceAuthentication;
+
+    getOwnerToken(): string;
+    setOwnerToken(value: string): WorkspaceAuthentication;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceAuthentication.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceAuthentication): WorkspaceAuthentication.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceAuthentication, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceAuthentication;
+    static deserializeBinaryFromReader(message: WorkspaceAuthentication, reader: jspb.BinaryReader): WorkspaceAuthentication;
+}
+
+export namespace WorkspaceAuthentication {
+    export type AsObject = {
+        admission: AdmissionLevel,
         ownerToken: string,
     }
 }
@@ -826,6 +3174,23 @@ export class StartWorkspaceSpec extends jspb.Message {
     setAdmission(value: AdmissionLevel): StartWorkspaceSpec;
 
 
+    getExperimentsMap(): jspb.Map<string, string>;
+    clearExperimentsMap(): void;
+
+    clearInitContainersList(): void;
+    getInitContainersList(): Array<string>;
+    setInitContainersList(value: Array<string>): StartWorkspaceSpec;
+    addInitContainers(value: string, index?: number): string;
+
+    getPinnedIdeImage(): string;
+    setPinnedIdeImage(value: string): StartWorkspaceSpec;
+
+    clearSshKeysList(): void;
+    getSshKeysList(): Array<WorkspaceSSHKey>;
+    setSshKeysList(value: Array<WorkspaceSSHKey>): StartWorkspaceSpec;
+    addSshKeys(value?: WorkspaceSSHKey, index?: number): WorkspaceSSHKey;
+
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): StartWorkspaceSpec.AsObject;
     static toObject(includeInstance: boolean, msg: StartWorkspaceSpec): StartWorkspaceSpec.AsObject;
@@ -849,6 +3214,36 @@ export namespace StartWorkspaceSpec {
         git?: GitSpec.AsObject,
         timeout: string,
         admission: AdmissionLevel,
+
+        experimentsMap: Array<[string, string]>,
+        initContainersList: Array<string>,
+        pinnedIdeImage: string,
+        sshKeysList: Array<WorkspaceSSHKey.AsObject>,
+    }
+}
+
+export class WorkspaceSSHKey extends jspb.Message { 
+    getName(): string;
+    setName(value: string): WorkspaceSSHKey;
+
+    getKeyOts(): string;
+    setKeyOts(value: string): WorkspaceSSHKey;
+
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): WorkspaceSSHKey.AsObject;
+    static toObject(includeInstance: boolean, msg: WorkspaceSSHKey): WorkspaceSSHKey.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: WorkspaceSSHKey, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): WorkspaceSSHKey;
+    static deserializeBinaryFromReader(message: WorkspaceSSHKey, reader: jspb.BinaryReader): WorkspaceSSHKey;
+}
+
+export namespace WorkspaceSSHKey {
+    export type AsObject = {
+        name: string,
+        keyOts: string,
     }
 }
 
@@ -934,16 +3329,50 @@ export namespace WorkspaceLogMessage {
     }
 }
 
+export enum StartWorkspaceErrorDomain {
+    START_ERROR_UNKNOWN = 0,
+    START_ERROR_INVALID_REQUEST = 1,
+    START_ERROR_CONFLICT = 2,
+    START_ERROR_QUOTA = 3,
+    START_ERROR_CAPACITY = 4,
+    START_ERROR_IMAGE = 5,
+    START_ERROR_ARCHIVED = 6,
+    START_ERROR_DELETED = 7,
+}
+
 export enum StopWorkspacePolicy {
     NORMALLY = 0,
     IMMEDIATELY = 1,
 }
 
+export enum AccountingEvent {
+    INSTANCE_STARTED = 0,
+    INSTANCE_STOPPED = 1,
+}
+
+export enum PendingOperationKind {
+    CONTENT_INITIALIZATION = 0,
+    CONTENT_FINALIZATION = 1,
+}
+
 export enum AdmissionLevel {
     ADMIT_OWNER_ONLY = 0,
     ADMIT_EVERYONE = 1,
 }
 
+export enum InitContainerState {
+    INIT_CONTAINER_WAITING = 0,
+    INIT_CONTAINER_RUNNING = 1,
+    INIT_CONTAINER_SUCCEEDED = 2,
+    INIT_CONTAINER_FAILED = 3,
+}
+
+export enum BulkOperationState {
+    BULK_OPERATION_RUNNING = 0,
+    BULK_OPERATION_COMPLETED = 1,
+    BULK_OPERATION_CANCELLED = 2,
+}
+
 export enum PortVisibility {
     PORT_VISIBILITY_PRIVATE = 0,
     PORT_VISIBILITY_PUBLIC = 1,
@@ -964,6 +3393,7 @@ export enum WorkspacePhase {
     INTERRUPTED = 7,
     STOPPING = 5,
     STOPPED = 6,
+    ARCHIVED = 8,
 }
 
 export enum WorkspaceFeatureFlag {
@@ -15,43 +15,126 @@ var content$service$api_initializer_pb = require('@gitpod/content-service/lib');
 goog.object.extend(proto, content$service$api_initializer_pb);
 var google_protobuf_timestamp_pb = require('google-protobuf/google/protobuf/timestamp_pb.js');
 goog.object.extend(proto, google_protobuf_timestamp_pb);
+goog.exportSymbol('proto.wsman.AccountingEvent', null, global);
+goog.exportSymbol('proto.wsman.AccountingRecord', null, global);
+goog.exportSymbol('proto.wsman.AckAccountingRequest', null, global);
+goog.exportSymbol('proto.wsman.AckAccountingResponse', null, global);
 goog.exportSymbol('proto.wsman.AdmissionLevel', null, global);
+goog.exportSymbol('proto.wsman.ArchivalStatus', null, global);
+goog.exportSymbol('proto.wsman.AuditRecord', null, global);
+goog.exportSymbol('proto.wsman.BulkMigrateOperation', null, global);
+goog.exportSymbol('proto.wsman.BulkOperationFilter', null, global);
+goog.exportSymbol('proto.wsman.BulkOperationResult', null, global);
+goog.exportSymbol('proto.wsman.BulkOperationState', null, global);
+goog.exportSymbol('proto.wsman.BulkOperationStatus', null, global);
+goog.exportSymbol('proto.wsman.BulkOperationUpdate', null, global);
+goog.exportSymbol('proto.wsman.BulkSetTimeoutOperation', null, global);
+goog.exportSymbol('proto.wsman.BulkStopOperation', null, global);
+goog.exportSymbol('proto.wsman.CancelBulkOperationRequest', null, global);
+goog.exportSymbol('proto.wsman.CancelBulkOperationResponse', null, global);
 goog.exportSymbol('proto.wsman.ControlAdmissionRequest', null, global);
 goog.exportSymbol('proto.wsman.ControlAdmissionResponse', null, global);
 goog.exportSymbol('proto.wsman.ControlPortRequest', null, global);
 goog.exportSymbol('proto.wsman.ControlPortResponse', null, global);
+goog.exportSymbol('proto.wsman.CreateWorkspaceTemplateRequest', null, global);
+goog.exportSymbol('proto.wsman.CreateWorkspaceTemplateResponse', null, global);
+goog.exportSymbol('proto.wsman.DeleteWorkspaceRequest', null, global);
+goog.exportSymbol('proto.wsman.DeleteWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsman.DeleteWorkspaceTemplateRequest', null, global);
+goog.exportSymbol('proto.wsman.DeleteWorkspaceTemplateResponse', null, global);
+goog.exportSymbol('proto.wsman.DeletedWorkspace', null, global);
+goog.exportSymbol('proto.wsman.DescribeArchivalRequest', null, global);
+goog.exportSymbol('proto.wsman.DescribeArchivalResponse', null, global);
+goog.exportSymbol('proto.wsman.DescribeBulkOperationRequest', null, global);
+goog.exportSymbol('proto.wsman.DescribeBulkOperationResponse', null, global);
+goog.exportSymbol('proto.wsman.DescribeDeletedWorkspacesRequest', null, global);
+goog.exportSymbol('proto.wsman.DescribeDeletedWorkspacesResponse', null, global);
+goog.exportSymbol('proto.wsman.DescribeWorkspaceGroupRequest', null, global);
+goog.exportSymbol('proto.wsman.DescribeWorkspaceGroupResponse', null, global);
 goog.exportSymbol('proto.wsman.DescribeWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsman.DescribeWorkspaceResponse', null, global);
 goog.exportSymbol('proto.wsman.EnvironmentVariable', null, global);
+goog.exportSymbol('proto.wsman.ExportStateRequest', null, global);
+goog.exportSymbol('proto.wsman.ExportStateResponse', null, global);
+goog.exportSymbol('proto.wsman.GenerateWorkspaceIDRequest', null, global);
+goog.exportSymbol('proto.wsman.GenerateWorkspaceIDResponse', null, global);
+goog.exportSymbol('proto.wsman.GetWorkspaceTemplateRequest', null, global);
+goog.exportSymbol('proto.wsman.GetWorkspaceTemplateResponse', null, global);
 goog.exportSymbol('proto.wsman.GetWorkspacesRequest', null, global);
 goog.exportSymbol('proto.wsman.GetWorkspacesResponse', null, global);
 goog.exportSymbol('proto.wsman.GitSpec', null, global);
+goog.exportSymbol('proto.wsman.IDEPin', null, global);
+goog.exportSymbol('proto.wsman.ImportStateRequest', null, global);
+goog.exportSymbol('proto.wsman.ImportStateResponse', null, global);
+goog.exportSymbol('proto.wsman.InitContainerState', null, global);
+goog.exportSymbol('proto.wsman.InitContainerStatus', null, global);
+goog.exportSymbol('proto.wsman.ListWorkspaceTemplatesRequest', null, global);
+goog.exportSymbol('proto.wsman.ListWorkspaceTemplatesResponse', null, global);
+goog.exportSymbol('proto.wsman.MaintenanceStatus', null, global);
 goog.exportSymbol('proto.wsman.MarkActiveRequest', null, global);
 goog.exportSymbol('proto.wsman.MarkActiveResponse', null, global);
+goog.exportSymbol('proto.wsman.PendingOperation', null, global);
+goog.exportSymbol('proto.wsman.PendingOperationKind', null, global);
+goog.exportSymbol('proto.wsman.PodTemplateViolation', null, global);
 goog.exportSymbol('proto.wsman.PortSpec', null, global);
 goog.exportSymbol('proto.wsman.PortVisibility', null, global);
+goog.exportSymbol('proto.wsman.QueryAuditLogRequest', null, global);
+goog.exportSymbol('proto.wsman.QueryAuditLogResponse', null, global);
+goog.exportSymbol('proto.wsman.ReportProxyActivityRequest', null, global);
+goog.exportSymbol('proto.wsman.ReportProxyActivityResponse', null, global);
+goog.exportSymbol('proto.wsman.ResetIDEPinRequest', null, global);
+goog.exportSymbol('proto.wsman.ResetIDEPinResponse', null, global);
+goog.exportSymbol('proto.wsman.RestoreDeletedWorkspaceRequest', null, global);
+goog.exportSymbol('proto.wsman.RestoreDeletedWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsman.ScheduleStopRequest', null, global);
+goog.exportSymbol('proto.wsman.ScheduleStopResponse', null, global);
+goog.exportSymbol('proto.wsman.SetMaintenanceRequest', null, global);
+goog.exportSymbol('proto.wsman.SetMaintenanceResponse', null, global);
 goog.exportSymbol('proto.wsman.SetTimeoutRequest', null, global);
 goog.exportSymbol('proto.wsman.SetTimeoutResponse', null, global);
+goog.exportSymbol('proto.wsman.StartBulkOperationRequest', null, global);
+goog.exportSymbol('proto.wsman.StartBulkOperationResponse', null, global);
+goog.exportSymbol('proto.wsman.StartWorkspaceErrorDetails', null, global);
+goog.exportSymbol('proto.wsman.StartWorkspaceErrorDomain', null, global);
+goog.exportSymbol('proto.wsman.StartWorkspaceGroupRequest', null, global);
+goog.exportSymbol('proto.wsman.StartWorkspaceGroupResponse', null, global);
 goog.exportSymbol('proto.wsman.StartWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsman.StartWorkspaceResponse', null, global);
 goog.exportSymbol('proto.wsman.StartWorkspaceSpec', null, global);
+goog.exportSymbol('proto.wsman.StopWorkspaceGroupRequest', null, global);
+goog.exportSymbol('proto.wsman.StopWorkspaceGroupResponse', null, global);
 goog.exportSymbol('proto.wsman.StopWorkspacePolicy', null, global);
 goog.exportSymbol('proto.wsman.StopWorkspaceRequest', null, global);
 goog.exportSymbol('proto.wsman.StopWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsman.SubscribeAccountingRequest', null, global);
+goog.exportSymbol('proto.wsman.SubscribeAuditLogRequest', null, global);
 goog.exportSymbol('proto.wsman.SubscribeRequest', null, global);
 goog.exportSymbol('proto.wsman.SubscribeResponse', null, global);
 goog.exportSymbol('proto.wsman.TakeSnapshotRequest', null, global);
 goog.exportSymbol('proto.wsman.TakeSnapshotResponse', null, global);
+goog.exportSymbol('proto.wsman.UnarchiveWorkspaceRequest', null, global);
+goog.exportSymbol('proto.wsman.UnarchiveWorkspaceResponse', null, global);
+goog.exportSymbol('proto.wsman.UpdateWorkspaceTemplateRequest', null, global);
+goog.exportSymbol('proto.wsman.UpdateWorkspaceTemplateResponse', null, global);
+goog.exportSymbol('proto.wsman.ValidatePodTemplateRequest', null, global);
+goog.exportSymbol('proto.wsman.ValidatePodTemplateResponse', null, global);
+goog.exportSymbol('proto.wsman.WatchBulkOperationRequest', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceAuthentication', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceConditionBool', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceConditions', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceFeatureFlag', null, global);
+goog.exportSymbol('proto.wsman.WorkspaceGroupMember', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceLogMessage', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceMetadata', null, global);
 goog.exportSymbol('proto.wsman.WorkspacePhase', null, global);
+goog.exportSymbol('proto.wsman.WorkspaceProxyActivity', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceRuntimeInfo', null, global);
+goog.exportSymbol('proto.wsman.WorkspaceSSHKey', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceSpec', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceStatus', null, global);
+goog.exportSymbol('proto.wsman.WorkspaceTemplate', null, global);
+goog.exportSymbol('proto.wsman.WorkspaceTemplateRef', null, global);
+goog.exportSymbol('proto.wsman.WorkspaceTemplateSpec', null, global);
 goog.exportSymbol('proto.wsman.WorkspaceType', null, global);
 /**
  * Generated by JsPbCodeGenerator.
@@ -137,6 +220,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.wsman.StartWorkspaceResponse.displayName = 'proto.wsman.StartWorkspaceResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.StartWorkspaceErrorDetails = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.StartWorkspaceErrorDetails, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.StartWorkspaceErrorDetails.displayName = 'proto.wsman.StartWorkspaceErrorDetails';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -483,16 +587,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.WorkspaceStatus = function(opt_data) {
+proto.wsman.SetMaintenanceRequest = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.WorkspaceStatus, jspb.Message);
+goog.inherits(proto.wsman.SetMaintenanceRequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.WorkspaceStatus.displayName = 'proto.wsman.WorkspaceStatus';
+  proto.wsman.SetMaintenanceRequest.displayName = 'proto.wsman.SetMaintenanceRequest';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -504,16 +608,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.WorkspaceSpec = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.WorkspaceSpec.repeatedFields_, null);
+proto.wsman.SetMaintenanceResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.WorkspaceSpec, jspb.Message);
+goog.inherits(proto.wsman.SetMaintenanceResponse, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.WorkspaceSpec.displayName = 'proto.wsman.WorkspaceSpec';
+  proto.wsman.SetMaintenanceResponse.displayName = 'proto.wsman.SetMaintenanceResponse';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -525,16 +629,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.PortSpec = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+proto.wsman.StartWorkspaceGroupRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.StartWorkspaceGroupRequest.repeatedFields_, null);
 };
-goog.inherits(proto.wsman.PortSpec, jspb.Message);
+goog.inherits(proto.wsman.StartWorkspaceGroupRequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.PortSpec.displayName = 'proto.wsman.PortSpec';
+  proto.wsman.StartWorkspaceGroupRequest.displayName = 'proto.wsman.StartWorkspaceGroupRequest';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -546,16 +650,37 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.WorkspaceConditions = function(opt_data) {
+proto.wsman.WorkspaceGroupMember = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.WorkspaceGroupMember.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.WorkspaceGroupMember, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceGroupMember.displayName = 'proto.wsman.WorkspaceGroupMember';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.StartWorkspaceGroupResponse = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.WorkspaceConditions, jspb.Message);
+goog.inherits(proto.wsman.StartWorkspaceGroupResponse, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.WorkspaceConditions.displayName = 'proto.wsman.WorkspaceConditions';
+  proto.wsman.StartWorkspaceGroupResponse.displayName = 'proto.wsman.StartWorkspaceGroupResponse';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -567,16 +692,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.WorkspaceMetadata = function(opt_data) {
+proto.wsman.StopWorkspaceGroupRequest = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.WorkspaceMetadata, jspb.Message);
+goog.inherits(proto.wsman.StopWorkspaceGroupRequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.WorkspaceMetadata.displayName = 'proto.wsman.WorkspaceMetadata';
+  proto.wsman.StopWorkspaceGroupRequest.displayName = 'proto.wsman.StopWorkspaceGroupRequest';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -588,16 +713,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.WorkspaceRuntimeInfo = function(opt_data) {
+proto.wsman.StopWorkspaceGroupResponse = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.WorkspaceRuntimeInfo, jspb.Message);
+goog.inherits(proto.wsman.StopWorkspaceGroupResponse, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.WorkspaceRuntimeInfo.displayName = 'proto.wsman.WorkspaceRuntimeInfo';
+  proto.wsman.StopWorkspaceGroupResponse.displayName = 'proto.wsman.StopWorkspaceGroupResponse';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -609,16 +734,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.WorkspaceAuthentication = function(opt_data) {
+proto.wsman.DescribeWorkspaceGroupRequest = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.WorkspaceAuthentication, jspb.Message);
+goog.inherits(proto.wsman.DescribeWorkspaceGroupRequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.WorkspaceAuthentication.displayName = 'proto.wsman.WorkspaceAuthentication';
+  proto.wsman.DescribeWorkspaceGroupRequest.displayName = 'proto.wsman.DescribeWorkspaceGroupRequest';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -630,16 +755,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.StartWorkspaceSpec = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.StartWorkspaceSpec.repeatedFields_, null);
+proto.wsman.DescribeWorkspaceGroupResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.DescribeWorkspaceGroupResponse.repeatedFields_, null);
 };
-goog.inherits(proto.wsman.StartWorkspaceSpec, jspb.Message);
+goog.inherits(proto.wsman.DescribeWorkspaceGroupResponse, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.StartWorkspaceSpec.displayName = 'proto.wsman.StartWorkspaceSpec';
+  proto.wsman.DescribeWorkspaceGroupResponse.displayName = 'proto.wsman.DescribeWorkspaceGroupResponse';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -651,16 +776,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.GitSpec = function(opt_data) {
+proto.wsman.ValidatePodTemplateRequest = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.GitSpec, jspb.Message);
+goog.inherits(proto.wsman.ValidatePodTemplateRequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.GitSpec.displayName = 'proto.wsman.GitSpec';
+  proto.wsman.ValidatePodTemplateRequest.displayName = 'proto.wsman.ValidatePodTemplateRequest';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -672,16 +797,16 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.EnvironmentVariable = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+proto.wsman.ValidatePodTemplateResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.ValidatePodTemplateResponse.repeatedFields_, null);
 };
-goog.inherits(proto.wsman.EnvironmentVariable, jspb.Message);
+goog.inherits(proto.wsman.ValidatePodTemplateResponse, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.EnvironmentVariable.displayName = 'proto.wsman.EnvironmentVariable';
+  proto.wsman.ValidatePodTemplateResponse.displayName = 'proto.wsman.ValidatePodTemplateResponse';
 }
 /**
  * Generated by JsPbCodeGenerator.
@@ -693,125 +818,15767 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.wsman.WorkspaceLogMessage = function(opt_data) {
+proto.wsman.PodTemplateViolation = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-goog.inherits(proto.wsman.WorkspaceLogMessage, jspb.Message);
+goog.inherits(proto.wsman.PodTemplateViolation, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
    * @public
    * @override
    */
-  proto.wsman.WorkspaceLogMessage.displayName = 'proto.wsman.WorkspaceLogMessage';
+  proto.wsman.PodTemplateViolation.displayName = 'proto.wsman.PodTemplateViolation';
 }
-
-
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto suitable for use in Soy templates.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
- * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
- *     for transitional soy proto support: http://goto/soy-param-migration
- * @return {!Object}
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.wsman.GetWorkspacesRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.GetWorkspacesRequest.toObject(opt_includeInstance, this);
+proto.wsman.ReportProxyActivityRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.ReportProxyActivityRequest.repeatedFields_, null);
 };
-
-
+goog.inherits(proto.wsman.ReportProxyActivityRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ReportProxyActivityRequest.displayName = 'proto.wsman.ReportProxyActivityRequest';
+}
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Whether to include the JSPB
- *     instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.wsman.GetWorkspacesRequest} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.wsman.GetWorkspacesRequest.toObject = function(includeInstance, msg) {
-  var f, obj = {
-
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+proto.wsman.WorkspaceProxyActivity = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
+goog.inherits(proto.wsman.WorkspaceProxyActivity, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceProxyActivity.displayName = 'proto.wsman.WorkspaceProxyActivity';
 }
-
-
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.GetWorkspacesRequest}
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.wsman.GetWorkspacesRequest.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.GetWorkspacesRequest;
-  return proto.wsman.GetWorkspacesRequest.deserializeBinaryFromReader(msg, reader);
+proto.wsman.ReportProxyActivityResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-
-
+goog.inherits(proto.wsman.ReportProxyActivityResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ReportProxyActivityResponse.displayName = 'proto.wsman.ReportProxyActivityResponse';
+}
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.wsman.GetWorkspacesRequest} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.GetWorkspacesRequest}
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.wsman.GetWorkspacesRequest.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.wsman.SubscribeAccountingRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-
-
+goog.inherits(proto.wsman.SubscribeAccountingRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.SubscribeAccountingRequest.displayName = 'proto.wsman.SubscribeAccountingRequest';
+}
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.wsman.GetWorkspacesRequest.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.wsman.GetWorkspacesRequest.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.wsman.AccountingRecord = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-
-
+goog.inherits(proto.wsman.AccountingRecord, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.AccountingRecord.displayName = 'proto.wsman.AccountingRecord';
+}
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.GetWorkspacesRequest} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.wsman.GetWorkspacesRequest.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
+proto.wsman.AckAccountingRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
-
-
-
+goog.inherits(proto.wsman.AckAccountingRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.AckAccountingRequest.displayName = 'proto.wsman.AckAccountingRequest';
+}
 /**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
  */
-proto.wsman.GetWorkspacesResponse.repeatedFields_ = [1];
-
-
+proto.wsman.AckAccountingResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.AckAccountingResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.AckAccountingResponse.displayName = 'proto.wsman.AckAccountingResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ScheduleStopRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ScheduleStopRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ScheduleStopRequest.displayName = 'proto.wsman.ScheduleStopRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ScheduleStopResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ScheduleStopResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ScheduleStopResponse.displayName = 'proto.wsman.ScheduleStopResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.GenerateWorkspaceIDRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.GenerateWorkspaceIDRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.GenerateWorkspaceIDRequest.displayName = 'proto.wsman.GenerateWorkspaceIDRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.GenerateWorkspaceIDResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.GenerateWorkspaceIDResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.GenerateWorkspaceIDResponse.displayName = 'proto.wsman.GenerateWorkspaceIDResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ExportStateRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ExportStateRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ExportStateRequest.displayName = 'proto.wsman.ExportStateRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ExportStateResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ExportStateResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ExportStateResponse.displayName = 'proto.wsman.ExportStateResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ImportStateRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ImportStateRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ImportStateRequest.displayName = 'proto.wsman.ImportStateRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ImportStateResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.ImportStateResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.ImportStateResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ImportStateResponse.displayName = 'proto.wsman.ImportStateResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.PendingOperation = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.PendingOperation, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.PendingOperation.displayName = 'proto.wsman.PendingOperation';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeArchivalRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DescribeArchivalRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeArchivalRequest.displayName = 'proto.wsman.DescribeArchivalRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeArchivalResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.DescribeArchivalResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.DescribeArchivalResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeArchivalResponse.displayName = 'proto.wsman.DescribeArchivalResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ArchivalStatus = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ArchivalStatus, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ArchivalStatus.displayName = 'proto.wsman.ArchivalStatus';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.UnarchiveWorkspaceRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.UnarchiveWorkspaceRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.UnarchiveWorkspaceRequest.displayName = 'proto.wsman.UnarchiveWorkspaceRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.UnarchiveWorkspaceResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.UnarchiveWorkspaceResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.UnarchiveWorkspaceResponse.displayName = 'proto.wsman.UnarchiveWorkspaceResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DeleteWorkspaceRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DeleteWorkspaceRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DeleteWorkspaceRequest.displayName = 'proto.wsman.DeleteWorkspaceRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DeleteWorkspaceResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DeleteWorkspaceResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DeleteWorkspaceResponse.displayName = 'proto.wsman.DeleteWorkspaceResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.RestoreDeletedWorkspaceRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.RestoreDeletedWorkspaceRequest.displayName = 'proto.wsman.RestoreDeletedWorkspaceRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.RestoreDeletedWorkspaceResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.RestoreDeletedWorkspaceResponse.displayName = 'proto.wsman.RestoreDeletedWorkspaceResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DescribeDeletedWorkspacesRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeDeletedWorkspacesRequest.displayName = 'proto.wsman.DescribeDeletedWorkspacesRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.DescribeDeletedWorkspacesResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.DescribeDeletedWorkspacesResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeDeletedWorkspacesResponse.displayName = 'proto.wsman.DescribeDeletedWorkspacesResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DeletedWorkspace = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DeletedWorkspace, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DeletedWorkspace.displayName = 'proto.wsman.DeletedWorkspace';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceTemplateSpec = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.WorkspaceTemplateSpec.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.WorkspaceTemplateSpec, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceTemplateSpec.displayName = 'proto.wsman.WorkspaceTemplateSpec';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceTemplate = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceTemplate, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceTemplate.displayName = 'proto.wsman.WorkspaceTemplate';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceTemplateRef = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceTemplateRef, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceTemplateRef.displayName = 'proto.wsman.WorkspaceTemplateRef';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.CreateWorkspaceTemplateRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.CreateWorkspaceTemplateRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.CreateWorkspaceTemplateRequest.displayName = 'proto.wsman.CreateWorkspaceTemplateRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.CreateWorkspaceTemplateResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.CreateWorkspaceTemplateResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.CreateWorkspaceTemplateResponse.displayName = 'proto.wsman.CreateWorkspaceTemplateResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.UpdateWorkspaceTemplateRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.UpdateWorkspaceTemplateRequest.displayName = 'proto.wsman.UpdateWorkspaceTemplateRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.UpdateWorkspaceTemplateResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.UpdateWorkspaceTemplateResponse.displayName = 'proto.wsman.UpdateWorkspaceTemplateResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.GetWorkspaceTemplateRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.GetWorkspaceTemplateRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.GetWorkspaceTemplateRequest.displayName = 'proto.wsman.GetWorkspaceTemplateRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.GetWorkspaceTemplateResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.GetWorkspaceTemplateResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.GetWorkspaceTemplateResponse.displayName = 'proto.wsman.GetWorkspaceTemplateResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ListWorkspaceTemplatesRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ListWorkspaceTemplatesRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ListWorkspaceTemplatesRequest.displayName = 'proto.wsman.ListWorkspaceTemplatesRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ListWorkspaceTemplatesResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.ListWorkspaceTemplatesResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.ListWorkspaceTemplatesResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ListWorkspaceTemplatesResponse.displayName = 'proto.wsman.ListWorkspaceTemplatesResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DeleteWorkspaceTemplateRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DeleteWorkspaceTemplateRequest.displayName = 'proto.wsman.DeleteWorkspaceTemplateRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DeleteWorkspaceTemplateResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DeleteWorkspaceTemplateResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DeleteWorkspaceTemplateResponse.displayName = 'proto.wsman.DeleteWorkspaceTemplateResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.MaintenanceStatus = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.MaintenanceStatus, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.MaintenanceStatus.displayName = 'proto.wsman.MaintenanceStatus';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceStatus = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.WorkspaceStatus.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.WorkspaceStatus, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceStatus.displayName = 'proto.wsman.WorkspaceStatus';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.InitContainerStatus = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.InitContainerStatus, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.InitContainerStatus.displayName = 'proto.wsman.InitContainerStatus';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceSpec = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.WorkspaceSpec.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.WorkspaceSpec, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceSpec.displayName = 'proto.wsman.WorkspaceSpec';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.IDEPin = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.IDEPin, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.IDEPin.displayName = 'proto.wsman.IDEPin';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ResetIDEPinRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ResetIDEPinRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ResetIDEPinRequest.displayName = 'proto.wsman.ResetIDEPinRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.ResetIDEPinResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.ResetIDEPinResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.ResetIDEPinResponse.displayName = 'proto.wsman.ResetIDEPinResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.BulkOperationFilter = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.BulkOperationFilter.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.BulkOperationFilter, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.BulkOperationFilter.displayName = 'proto.wsman.BulkOperationFilter';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.StartBulkOperationRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, proto.wsman.StartBulkOperationRequest.oneofGroups_);
+};
+goog.inherits(proto.wsman.StartBulkOperationRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.StartBulkOperationRequest.displayName = 'proto.wsman.StartBulkOperationRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.BulkStopOperation = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.BulkStopOperation, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.BulkStopOperation.displayName = 'proto.wsman.BulkStopOperation';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.BulkSetTimeoutOperation = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.BulkSetTimeoutOperation, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.BulkSetTimeoutOperation.displayName = 'proto.wsman.BulkSetTimeoutOperation';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.BulkMigrateOperation = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.BulkMigrateOperation, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.BulkMigrateOperation.displayName = 'proto.wsman.BulkMigrateOperation';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.StartBulkOperationResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.StartBulkOperationResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.StartBulkOperationResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.StartBulkOperationResponse.displayName = 'proto.wsman.StartBulkOperationResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.BulkOperationStatus = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.BulkOperationStatus, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.BulkOperationStatus.displayName = 'proto.wsman.BulkOperationStatus';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.BulkOperationResult = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.BulkOperationResult, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.BulkOperationResult.displayName = 'proto.wsman.BulkOperationResult';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeBulkOperationRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.DescribeBulkOperationRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeBulkOperationRequest.displayName = 'proto.wsman.DescribeBulkOperationRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.DescribeBulkOperationResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.DescribeBulkOperationResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.DescribeBulkOperationResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.DescribeBulkOperationResponse.displayName = 'proto.wsman.DescribeBulkOperationResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WatchBulkOperationRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WatchBulkOperationRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WatchBulkOperationRequest.displayName = 'proto.wsman.WatchBulkOperationRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.BulkOperationUpdate = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.BulkOperationUpdate, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.BulkOperationUpdate.displayName = 'proto.wsman.BulkOperationUpdate';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.CancelBulkOperationRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.CancelBulkOperationRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.CancelBulkOperationRequest.displayName = 'proto.wsman.CancelBulkOperationRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.CancelBulkOperationResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.CancelBulkOperationResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.CancelBulkOperationResponse.displayName = 'proto.wsman.CancelBulkOperationResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.AuditRecord = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.AuditRecord, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.AuditRecord.displayName = 'proto.wsman.AuditRecord';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.QueryAuditLogRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.QueryAuditLogRequest.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.QueryAuditLogRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.QueryAuditLogRequest.displayName = 'proto.wsman.QueryAuditLogRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.QueryAuditLogResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.QueryAuditLogResponse.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.QueryAuditLogResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.QueryAuditLogResponse.displayName = 'proto.wsman.QueryAuditLogResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.SubscribeAuditLogRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.SubscribeAuditLogRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.SubscribeAuditLogRequest.displayName = 'proto.wsman.SubscribeAuditLogRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.PortSpec = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.PortSpec, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.PortSpec.displayName = 'proto.wsman.PortSpec';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceConditions = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceConditions, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceConditions.displayName = 'proto.wsman.WorkspaceConditions';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceMetadata = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceMetadata, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceMetadata.displayName = 'proto.wsman.WorkspaceMetadata';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceRuntimeInfo = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceRuntimeInfo, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceRuntimeInfo.displayName = 'proto.wsman.WorkspaceRuntimeInfo';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceAuthentication = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceAuthentication, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceAuthentication.displayName = 'proto.wsman.WorkspaceAuthentication';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.StartWorkspaceSpec = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.StartWorkspaceSpec.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.StartWorkspaceSpec, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.StartWorkspaceSpec.displayName = 'proto.wsman.StartWorkspaceSpec';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceSSHKey = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceSSHKey, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceSSHKey.displayName = 'proto.wsman.WorkspaceSSHKey';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.GitSpec = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.GitSpec, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.GitSpec.displayName = 'proto.wsman.GitSpec';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.EnvironmentVariable = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.EnvironmentVariable, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.EnvironmentVariable.displayName = 'proto.wsman.EnvironmentVariable';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.WorkspaceLogMessage = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.wsman.WorkspaceLogMessage, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.wsman.WorkspaceLogMessage.displayName = 'proto.wsman.WorkspaceLogMessage';
+}
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.GetWorkspacesRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GetWorkspacesRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GetWorkspacesRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspacesRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GetWorkspacesRequest}
+ */
+proto.wsman.GetWorkspacesRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GetWorkspacesRequest;
+  return proto.wsman.GetWorkspacesRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GetWorkspacesRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GetWorkspacesRequest}
+ */
+proto.wsman.GetWorkspacesRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.GetWorkspacesRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GetWorkspacesRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GetWorkspacesRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspacesRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.GetWorkspacesResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.GetWorkspacesResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GetWorkspacesResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GetWorkspacesResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspacesResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    statusList: jspb.Message.toObjectList(msg.getStatusList(),
+    proto.wsman.WorkspaceStatus.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GetWorkspacesResponse}
+ */
+proto.wsman.GetWorkspacesResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GetWorkspacesResponse;
+  return proto.wsman.GetWorkspacesResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GetWorkspacesResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GetWorkspacesResponse}
+ */
+proto.wsman.GetWorkspacesResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceStatus;
+      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
+      msg.addStatus(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.GetWorkspacesResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GetWorkspacesResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GetWorkspacesResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspacesResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getStatusList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated WorkspaceStatus status = 1;
+ * @return {!Array<!proto.wsman.WorkspaceStatus>}
+ */
+proto.wsman.GetWorkspacesResponse.prototype.getStatusList = function() {
+  return /** @type{!Array<!proto.wsman.WorkspaceStatus>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceStatus, 1));
+};
+
+
+/** @param {!Array<!proto.wsman.WorkspaceStatus>} value */
+proto.wsman.GetWorkspacesResponse.prototype.setStatusList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceStatus=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceStatus}
+ */
+proto.wsman.GetWorkspacesResponse.prototype.addStatus = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.WorkspaceStatus, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.GetWorkspacesResponse.prototype.clearStatusList = function() {
+  this.setStatusList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartWorkspaceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StartWorkspaceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    servicePrefix: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
+    spec: (f = msg.getSpec()) && proto.wsman.StartWorkspaceSpec.toObject(includeInstance, f),
+    type: jspb.Message.getFieldWithDefault(msg, 6, 0),
+    paused: jspb.Message.getFieldWithDefault(msg, 7, false),
+    template: (f = msg.getTemplate()) && proto.wsman.WorkspaceTemplateRef.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StartWorkspaceRequest}
+ */
+proto.wsman.StartWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StartWorkspaceRequest;
+  return proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StartWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StartWorkspaceRequest}
+ */
+proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setServicePrefix(value);
+      break;
+    case 3:
+      var value = new proto.wsman.WorkspaceMetadata;
+      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
+      msg.setMetadata(value);
+      break;
+    case 4:
+      var value = new proto.wsman.StartWorkspaceSpec;
+      reader.readMessage(value,proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
+      break;
+    case 6:
+      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
+      msg.setType(value);
+      break;
+    case 7:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setPaused(value);
+      break;
+    case 8:
+      var value = new proto.wsman.WorkspaceTemplateRef;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplateRef.deserializeBinaryFromReader);
+      msg.setTemplate(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StartWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StartWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getServicePrefix();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getMetadata();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
+    );
+  }
+  f = message.getSpec();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.wsman.StartWorkspaceSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getType();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      6,
+      f
+    );
+  }
+  f = message.getPaused();
+  if (f) {
+    writer.writeBool(
+      7,
+      f
+    );
+  }
+  f = message.getTemplate();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.wsman.WorkspaceTemplateRef.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string service_prefix = 2;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getServicePrefix = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceRequest.prototype.setServicePrefix = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional WorkspaceMetadata metadata = 3;
+ * @return {?proto.wsman.WorkspaceMetadata}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getMetadata = function() {
+  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 3));
+};
+
+
+/** @param {?proto.wsman.WorkspaceMetadata|undefined} value */
+proto.wsman.StartWorkspaceRequest.prototype.setMetadata = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.StartWorkspaceRequest.prototype.clearMetadata = function() {
+  this.setMetadata(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.hasMetadata = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional StartWorkspaceSpec spec = 4;
+ * @return {?proto.wsman.StartWorkspaceSpec}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.StartWorkspaceSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.StartWorkspaceSpec, 4));
+};
+
+
+/** @param {?proto.wsman.StartWorkspaceSpec|undefined} value */
+proto.wsman.StartWorkspaceRequest.prototype.setSpec = function(value) {
+  jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.StartWorkspaceRequest.prototype.clearSpec = function() {
+  this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * optional WorkspaceType type = 6;
+ * @return {!proto.wsman.WorkspaceType}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getType = function() {
+  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+};
+
+
+/** @param {!proto.wsman.WorkspaceType} value */
+proto.wsman.StartWorkspaceRequest.prototype.setType = function(value) {
+  jspb.Message.setProto3EnumField(this, 6, value);
+};
+
+
+/**
+ * optional bool paused = 7;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getPaused = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 7, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.StartWorkspaceRequest.prototype.setPaused = function(value) {
+  jspb.Message.setProto3BooleanField(this, 7, value);
+};
+
+
+/**
+ * optional WorkspaceTemplateRef template = 8;
+ * @return {?proto.wsman.WorkspaceTemplateRef}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.getTemplate = function() {
+  return /** @type{?proto.wsman.WorkspaceTemplateRef} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceTemplateRef, 8));
+};
+
+
+/** @param {?proto.wsman.WorkspaceTemplateRef|undefined} value */
+proto.wsman.StartWorkspaceRequest.prototype.setTemplate = function(value) {
+  jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.StartWorkspaceRequest.prototype.clearTemplate = function() {
+  this.setTemplate(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceRequest.prototype.hasTemplate = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StartWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StartWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    url: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    activated: jspb.Message.getFieldWithDefault(msg, 2, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StartWorkspaceResponse}
+ */
+proto.wsman.StartWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StartWorkspaceResponse;
+  return proto.wsman.StartWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StartWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StartWorkspaceResponse}
+ */
+proto.wsman.StartWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setActivated(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StartWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StartWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StartWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getActivated();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string url = 1;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceResponse.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceResponse.prototype.setUrl = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional bool activated = 2;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceResponse.prototype.getActivated = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.StartWorkspaceResponse.prototype.setActivated = function(value) {
+  jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StartWorkspaceErrorDetails.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartWorkspaceErrorDetails.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StartWorkspaceErrorDetails} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceErrorDetails.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    domain: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    retryable: jspb.Message.getFieldWithDefault(msg, 2, false),
+    retryAfter: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    userMessage: jspb.Message.getFieldWithDefault(msg, 4, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StartWorkspaceErrorDetails}
+ */
+proto.wsman.StartWorkspaceErrorDetails.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StartWorkspaceErrorDetails;
+  return proto.wsman.StartWorkspaceErrorDetails.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StartWorkspaceErrorDetails} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StartWorkspaceErrorDetails}
+ */
+proto.wsman.StartWorkspaceErrorDetails.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {!proto.wsman.StartWorkspaceErrorDomain} */ (reader.readEnum());
+      msg.setDomain(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setRetryable(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRetryAfter(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUserMessage(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StartWorkspaceErrorDetails.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StartWorkspaceErrorDetails.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StartWorkspaceErrorDetails} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceErrorDetails.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getDomain();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      1,
+      f
+    );
+  }
+  f = message.getRetryable();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+  f = message.getRetryAfter();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getUserMessage();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional StartWorkspaceErrorDomain domain = 1;
+ * @return {!proto.wsman.StartWorkspaceErrorDomain}
+ */
+proto.wsman.StartWorkspaceErrorDetails.prototype.getDomain = function() {
+  return /** @type {!proto.wsman.StartWorkspaceErrorDomain} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/** @param {!proto.wsman.StartWorkspaceErrorDomain} value */
+proto.wsman.StartWorkspaceErrorDetails.prototype.setDomain = function(value) {
+  jspb.Message.setProto3EnumField(this, 1, value);
+};
+
+
+/**
+ * optional bool retryable = 2;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.StartWorkspaceErrorDetails.prototype.getRetryable = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.StartWorkspaceErrorDetails.prototype.setRetryable = function(value) {
+  jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+/**
+ * optional string retry_after = 3;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceErrorDetails.prototype.getRetryAfter = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceErrorDetails.prototype.setRetryAfter = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string user_message = 4;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceErrorDetails.prototype.getUserMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceErrorDetails.prototype.setUserMessage = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StopWorkspaceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StopWorkspaceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    policy: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    expectedGeneration: jspb.Message.getFieldWithDefault(msg, 3, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StopWorkspaceRequest}
+ */
+proto.wsman.StopWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StopWorkspaceRequest;
+  return proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StopWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StopWorkspaceRequest}
+ */
+proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.StopWorkspacePolicy} */ (reader.readEnum());
+      msg.setPolicy(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setExpectedGeneration(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StopWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getPolicy();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+  f = message.getExpectedGeneration();
+  if (f !== 0) {
+    writer.writeUint64(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StopWorkspaceRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional StopWorkspacePolicy policy = 2;
+ * @return {!proto.wsman.StopWorkspacePolicy}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.getPolicy = function() {
+  return /** @type {!proto.wsman.StopWorkspacePolicy} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {!proto.wsman.StopWorkspacePolicy} value */
+proto.wsman.StopWorkspaceRequest.prototype.setPolicy = function(value) {
+  jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+/**
+ * optional uint64 expected_generation = 3;
+ * @return {number}
+ */
+proto.wsman.StopWorkspaceRequest.prototype.getExpectedGeneration = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.StopWorkspaceRequest.prototype.setExpectedGeneration = function(value) {
+  jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StopWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StopWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StopWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StopWorkspaceResponse}
+ */
+proto.wsman.StopWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StopWorkspaceResponse;
+  return proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StopWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StopWorkspaceResponse}
+ */
+proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StopWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StopWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeWorkspaceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeWorkspaceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeWorkspaceRequest}
+ */
+proto.wsman.DescribeWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeWorkspaceRequest;
+  return proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeWorkspaceRequest}
+ */
+proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.DescribeWorkspaceRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DescribeWorkspaceRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
+    lastactivity: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeWorkspaceResponse}
+ */
+proto.wsman.DescribeWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeWorkspaceResponse;
+  return proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeWorkspaceResponse}
+ */
+proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceStatus;
+      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setLastactivity(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+    );
+  }
+  f = message.getLastactivity();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional WorkspaceStatus status = 1;
+ * @return {?proto.wsman.WorkspaceStatus}
+ */
+proto.wsman.DescribeWorkspaceResponse.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.WorkspaceStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
+};
+
+
+/** @param {?proto.wsman.WorkspaceStatus|undefined} value */
+proto.wsman.DescribeWorkspaceResponse.prototype.setStatus = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.DescribeWorkspaceResponse.prototype.clearStatus = function() {
+  this.setStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.DescribeWorkspaceResponse.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional string lastActivity = 2;
+ * @return {string}
+ */
+proto.wsman.DescribeWorkspaceResponse.prototype.getLastactivity = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DescribeWorkspaceResponse.prototype.setLastactivity = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.SubscribeRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SubscribeRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SubscribeRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SubscribeRequest}
+ */
+proto.wsman.SubscribeRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SubscribeRequest;
+  return proto.wsman.SubscribeRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SubscribeRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SubscribeRequest}
+ */
+proto.wsman.SubscribeRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.SubscribeRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SubscribeRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SubscribeRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+/**
+ * Oneof group definitions for this message. Each group defines the field
+ * numbers belonging to that group. When of these fields' value is set, all
+ * other fields in the group are cleared. During deserialization, if multiple
+ * fields are encountered for a group, only the last value seen will be kept.
+ * @private {!Array<!Array<number>>}
+ * @const
+ */
+proto.wsman.SubscribeResponse.oneofGroups_ = [[1,2,4]];
+
+/**
+ * @enum {number}
+ */
+proto.wsman.SubscribeResponse.PayloadCase = {
+  PAYLOAD_NOT_SET: 0,
+  STATUS: 1,
+  LOG: 2,
+  MAINTENANCE: 4
+};
+
+/**
+ * @return {proto.wsman.SubscribeResponse.PayloadCase}
+ */
+proto.wsman.SubscribeResponse.prototype.getPayloadCase = function() {
+  return /** @type {proto.wsman.SubscribeResponse.PayloadCase} */(jspb.Message.computeOneofCase(this, proto.wsman.SubscribeResponse.oneofGroups_[0]));
+};
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.SubscribeResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SubscribeResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SubscribeResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
+    log: (f = msg.getLog()) && proto.wsman.WorkspaceLogMessage.toObject(includeInstance, f),
+    maintenance: (f = msg.getMaintenance()) && proto.wsman.MaintenanceStatus.toObject(includeInstance, f),
+    headerMap: (f = msg.getHeaderMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SubscribeResponse}
+ */
+proto.wsman.SubscribeResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SubscribeResponse;
+  return proto.wsman.SubscribeResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SubscribeResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SubscribeResponse}
+ */
+proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceStatus;
+      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
+      break;
+    case 2:
+      var value = new proto.wsman.WorkspaceLogMessage;
+      reader.readMessage(value,proto.wsman.WorkspaceLogMessage.deserializeBinaryFromReader);
+      msg.setLog(value);
+      break;
+    case 4:
+      var value = new proto.wsman.MaintenanceStatus;
+      reader.readMessage(value,proto.wsman.MaintenanceStatus.deserializeBinaryFromReader);
+      msg.setMaintenance(value);
+      break;
+    case 3:
+      var value = msg.getHeaderMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.SubscribeResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SubscribeResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SubscribeResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+    );
+  }
+  f = message.getLog();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.wsman.WorkspaceLogMessage.serializeBinaryToWriter
+    );
+  }
+  f = message.getMaintenance();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.wsman.MaintenanceStatus.serializeBinaryToWriter
+    );
+  }
+  f = message.getHeaderMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * optional WorkspaceStatus status = 1;
+ * @return {?proto.wsman.WorkspaceStatus}
+ */
+proto.wsman.SubscribeResponse.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.WorkspaceStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
+};
+
+
+/** @param {?proto.wsman.WorkspaceStatus|undefined} value */
+proto.wsman.SubscribeResponse.prototype.setStatus = function(value) {
+  jspb.Message.setOneofWrapperField(this, 1, proto.wsman.SubscribeResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.SubscribeResponse.prototype.clearStatus = function() {
+  this.setStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.SubscribeResponse.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional WorkspaceLogMessage log = 2;
+ * @return {?proto.wsman.WorkspaceLogMessage}
+ */
+proto.wsman.SubscribeResponse.prototype.getLog = function() {
+  return /** @type{?proto.wsman.WorkspaceLogMessage} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceLogMessage, 2));
+};
+
+
+/** @param {?proto.wsman.WorkspaceLogMessage|undefined} value */
+proto.wsman.SubscribeResponse.prototype.setLog = function(value) {
+  jspb.Message.setOneofWrapperField(this, 2, proto.wsman.SubscribeResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.SubscribeResponse.prototype.clearLog = function() {
+  this.setLog(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.SubscribeResponse.prototype.hasLog = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional MaintenanceStatus maintenance = 4;
+ * @return {?proto.wsman.MaintenanceStatus}
+ */
+proto.wsman.SubscribeResponse.prototype.getMaintenance = function() {
+  return /** @type{?proto.wsman.MaintenanceStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.MaintenanceStatus, 4));
+};
+
+
+/** @param {?proto.wsman.MaintenanceStatus|undefined} value */
+proto.wsman.SubscribeResponse.prototype.setMaintenance = function(value) {
+  jspb.Message.setOneofWrapperField(this, 4, proto.wsman.SubscribeResponse.oneofGroups_[0], value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.SubscribeResponse.prototype.clearMaintenance = function() {
+  this.setMaintenance(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.SubscribeResponse.prototype.hasMaintenance = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * map<string, string> header = 3;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.SubscribeResponse.prototype.getHeaderMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ */
+proto.wsman.SubscribeResponse.prototype.clearHeaderMap = function() {
+  this.getHeaderMap().clear();
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.MarkActiveRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.MarkActiveRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.MarkActiveRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.MarkActiveRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    closed: jspb.Message.getFieldWithDefault(msg, 2, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.MarkActiveRequest}
+ */
+proto.wsman.MarkActiveRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.MarkActiveRequest;
+  return proto.wsman.MarkActiveRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.MarkActiveRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.MarkActiveRequest}
+ */
+proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setClosed(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.MarkActiveRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.MarkActiveRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.MarkActiveRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.MarkActiveRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getClosed();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.MarkActiveRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.MarkActiveRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional bool closed = 2;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.MarkActiveRequest.prototype.getClosed = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.MarkActiveRequest.prototype.setClosed = function(value) {
+  jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.MarkActiveResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.MarkActiveResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.MarkActiveResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.MarkActiveResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.MarkActiveResponse}
+ */
+proto.wsman.MarkActiveResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.MarkActiveResponse;
+  return proto.wsman.MarkActiveResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.MarkActiveResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.MarkActiveResponse}
+ */
+proto.wsman.MarkActiveResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.MarkActiveResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.MarkActiveResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.MarkActiveResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.MarkActiveResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.SetTimeoutRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SetTimeoutRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SetTimeoutRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetTimeoutRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    duration: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    expectedGeneration: jspb.Message.getFieldWithDefault(msg, 3, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SetTimeoutRequest}
+ */
+proto.wsman.SetTimeoutRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SetTimeoutRequest;
+  return proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SetTimeoutRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SetTimeoutRequest}
+ */
+proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDuration(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setExpectedGeneration(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.SetTimeoutRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SetTimeoutRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SetTimeoutRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetTimeoutRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getDuration();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getExpectedGeneration();
+  if (f !== 0) {
+    writer.writeUint64(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.SetTimeoutRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.SetTimeoutRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string duration = 2;
+ * @return {string}
+ */
+proto.wsman.SetTimeoutRequest.prototype.getDuration = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.SetTimeoutRequest.prototype.setDuration = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional uint64 expected_generation = 3;
+ * @return {number}
+ */
+proto.wsman.SetTimeoutRequest.prototype.getExpectedGeneration = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.SetTimeoutRequest.prototype.setExpectedGeneration = function(value) {
+  jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.SetTimeoutResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SetTimeoutResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SetTimeoutResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetTimeoutResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SetTimeoutResponse}
+ */
+proto.wsman.SetTimeoutResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SetTimeoutResponse;
+  return proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SetTimeoutResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SetTimeoutResponse}
+ */
+proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.SetTimeoutResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SetTimeoutResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SetTimeoutResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetTimeoutResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ControlPortRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlPortRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ControlPortRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlPortRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    expose: jspb.Message.getFieldWithDefault(msg, 2, false),
+    spec: (f = msg.getSpec()) && proto.wsman.PortSpec.toObject(includeInstance, f),
+    expectedGeneration: jspb.Message.getFieldWithDefault(msg, 4, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ControlPortRequest}
+ */
+proto.wsman.ControlPortRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ControlPortRequest;
+  return proto.wsman.ControlPortRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ControlPortRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ControlPortRequest}
+ */
+proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setExpose(value);
+      break;
+    case 3:
+      var value = new proto.wsman.PortSpec;
+      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
+      break;
+    case 4:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setExpectedGeneration(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ControlPortRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ControlPortRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ControlPortRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlPortRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getExpose();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+  f = message.getSpec();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.wsman.PortSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getExpectedGeneration();
+  if (f !== 0) {
+    writer.writeUint64(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.ControlPortRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ControlPortRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional bool expose = 2;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.ControlPortRequest.prototype.getExpose = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.ControlPortRequest.prototype.setExpose = function(value) {
+  jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+/**
+ * optional PortSpec spec = 3;
+ * @return {?proto.wsman.PortSpec}
+ */
+proto.wsman.ControlPortRequest.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.PortSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.PortSpec, 3));
+};
+
+
+/** @param {?proto.wsman.PortSpec|undefined} value */
+proto.wsman.ControlPortRequest.prototype.setSpec = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.ControlPortRequest.prototype.clearSpec = function() {
+  this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ControlPortRequest.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional uint64 expected_generation = 4;
+ * @return {number}
+ */
+proto.wsman.ControlPortRequest.prototype.getExpectedGeneration = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.ControlPortRequest.prototype.setExpectedGeneration = function(value) {
+  jspb.Message.setProto3IntField(this, 4, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ControlPortResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlPortResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ControlPortResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlPortResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ControlPortResponse}
+ */
+proto.wsman.ControlPortResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ControlPortResponse;
+  return proto.wsman.ControlPortResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ControlPortResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ControlPortResponse}
+ */
+proto.wsman.ControlPortResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ControlPortResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ControlPortResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ControlPortResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlPortResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.TakeSnapshotRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.TakeSnapshotRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.TakeSnapshotRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.TakeSnapshotRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.TakeSnapshotRequest}
+ */
+proto.wsman.TakeSnapshotRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.TakeSnapshotRequest;
+  return proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.TakeSnapshotRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.TakeSnapshotRequest}
+ */
+proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.TakeSnapshotRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.TakeSnapshotRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.TakeSnapshotRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.TakeSnapshotRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.TakeSnapshotResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.TakeSnapshotResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.TakeSnapshotResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.TakeSnapshotResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.TakeSnapshotResponse}
+ */
+proto.wsman.TakeSnapshotResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.TakeSnapshotResponse;
+  return proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.TakeSnapshotResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.TakeSnapshotResponse}
+ */
+proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.TakeSnapshotResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.TakeSnapshotResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string url = 1;
+ * @return {string}
+ */
+proto.wsman.TakeSnapshotResponse.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.TakeSnapshotResponse.prototype.setUrl = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ControlAdmissionRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlAdmissionRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ControlAdmissionRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlAdmissionRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    level: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    expectedGeneration: jspb.Message.getFieldWithDefault(msg, 3, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ControlAdmissionRequest}
+ */
+proto.wsman.ControlAdmissionRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ControlAdmissionRequest;
+  return proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ControlAdmissionRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ControlAdmissionRequest}
+ */
+proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
+      msg.setLevel(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setExpectedGeneration(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ControlAdmissionRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ControlAdmissionRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getLevel();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+  f = message.getExpectedGeneration();
+  if (f !== 0) {
+    writer.writeUint64(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.ControlAdmissionRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ControlAdmissionRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional AdmissionLevel level = 2;
+ * @return {!proto.wsman.AdmissionLevel}
+ */
+proto.wsman.ControlAdmissionRequest.prototype.getLevel = function() {
+  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {!proto.wsman.AdmissionLevel} value */
+proto.wsman.ControlAdmissionRequest.prototype.setLevel = function(value) {
+  jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+/**
+ * optional uint64 expected_generation = 3;
+ * @return {number}
+ */
+proto.wsman.ControlAdmissionRequest.prototype.getExpectedGeneration = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.ControlAdmissionRequest.prototype.setExpectedGeneration = function(value) {
+  jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ControlAdmissionResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ControlAdmissionResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ControlAdmissionResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlAdmissionResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ControlAdmissionResponse}
+ */
+proto.wsman.ControlAdmissionResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ControlAdmissionResponse;
+  return proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ControlAdmissionResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ControlAdmissionResponse}
+ */
+proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ControlAdmissionResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ControlAdmissionResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.SetMaintenanceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SetMaintenanceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SetMaintenanceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetMaintenanceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    status: (f = msg.getStatus()) && proto.wsman.MaintenanceStatus.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SetMaintenanceRequest}
+ */
+proto.wsman.SetMaintenanceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SetMaintenanceRequest;
+  return proto.wsman.SetMaintenanceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SetMaintenanceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SetMaintenanceRequest}
+ */
+proto.wsman.SetMaintenanceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.MaintenanceStatus;
+      reader.readMessage(value,proto.wsman.MaintenanceStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.SetMaintenanceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SetMaintenanceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SetMaintenanceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetMaintenanceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.MaintenanceStatus.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional MaintenanceStatus status = 1;
+ * @return {?proto.wsman.MaintenanceStatus}
+ */
+proto.wsman.SetMaintenanceRequest.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.MaintenanceStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.MaintenanceStatus, 1));
+};
+
+
+/** @param {?proto.wsman.MaintenanceStatus|undefined} value */
+proto.wsman.SetMaintenanceRequest.prototype.setStatus = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.SetMaintenanceRequest.prototype.clearStatus = function() {
+  this.setStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.SetMaintenanceRequest.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.SetMaintenanceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SetMaintenanceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SetMaintenanceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetMaintenanceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SetMaintenanceResponse}
+ */
+proto.wsman.SetMaintenanceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SetMaintenanceResponse;
+  return proto.wsman.SetMaintenanceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SetMaintenanceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SetMaintenanceResponse}
+ */
+proto.wsman.SetMaintenanceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.SetMaintenanceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SetMaintenanceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SetMaintenanceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SetMaintenanceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.StartWorkspaceGroupRequest.repeatedFields_ = [2];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StartWorkspaceGroupRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartWorkspaceGroupRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StartWorkspaceGroupRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceGroupRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    membersList: jspb.Message.toObjectList(msg.getMembersList(),
+    proto.wsman.WorkspaceGroupMember.toObject, includeInstance),
+    timeout: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StartWorkspaceGroupRequest}
+ */
+proto.wsman.StartWorkspaceGroupRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StartWorkspaceGroupRequest;
+  return proto.wsman.StartWorkspaceGroupRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StartWorkspaceGroupRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StartWorkspaceGroupRequest}
+ */
+proto.wsman.StartWorkspaceGroupRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = new proto.wsman.WorkspaceGroupMember;
+      reader.readMessage(value,proto.wsman.WorkspaceGroupMember.deserializeBinaryFromReader);
+      msg.addMembers(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTimeout(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StartWorkspaceGroupRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StartWorkspaceGroupRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StartWorkspaceGroupRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceGroupRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getMembersList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      2,
+      f,
+      proto.wsman.WorkspaceGroupMember.serializeBinaryToWriter
+    );
+  }
+  f = message.getTimeout();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceGroupRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceGroupRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * repeated WorkspaceGroupMember members = 2;
+ * @return {!Array<!proto.wsman.WorkspaceGroupMember>}
+ */
+proto.wsman.StartWorkspaceGroupRequest.prototype.getMembersList = function() {
+  return /** @type{!Array<!proto.wsman.WorkspaceGroupMember>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceGroupMember, 2));
+};
+
+
+/** @param {!Array<!proto.wsman.WorkspaceGroupMember>} value */
+proto.wsman.StartWorkspaceGroupRequest.prototype.setMembersList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 2, value);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceGroupMember=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceGroupMember}
+ */
+proto.wsman.StartWorkspaceGroupRequest.prototype.addMembers = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 2, opt_value, proto.wsman.WorkspaceGroupMember, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.StartWorkspaceGroupRequest.prototype.clearMembersList = function() {
+  this.setMembersList([]);
+};
+
+
+/**
+ * optional string timeout = 3;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceGroupRequest.prototype.getTimeout = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceGroupRequest.prototype.setTimeout = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.WorkspaceGroupMember.repeatedFields_ = [3];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceGroupMember.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceGroupMember.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceGroupMember} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceGroupMember.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    name: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    workspace: (f = msg.getWorkspace()) && proto.wsman.StartWorkspaceRequest.toObject(includeInstance, f),
+    dependsOnList: jspb.Message.getRepeatedField(msg, 3)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceGroupMember}
+ */
+proto.wsman.WorkspaceGroupMember.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceGroupMember;
+  return proto.wsman.WorkspaceGroupMember.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceGroupMember} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceGroupMember}
+ */
+proto.wsman.WorkspaceGroupMember.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 2:
+      var value = new proto.wsman.StartWorkspaceRequest;
+      reader.readMessage(value,proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader);
+      msg.setWorkspace(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addDependsOn(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceGroupMember.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceGroupMember.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceGroupMember} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceGroupMember.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getWorkspace();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.wsman.StartWorkspaceRequest.serializeBinaryToWriter
+    );
+  }
+  f = message.getDependsOnList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string name = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceGroupMember.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceGroupMember.prototype.setName = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional StartWorkspaceRequest workspace = 2;
+ * @return {?proto.wsman.StartWorkspaceRequest}
+ */
+proto.wsman.WorkspaceGroupMember.prototype.getWorkspace = function() {
+  return /** @type{?proto.wsman.StartWorkspaceRequest} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.StartWorkspaceRequest, 2));
+};
+
+
+/** @param {?proto.wsman.StartWorkspaceRequest|undefined} value */
+proto.wsman.WorkspaceGroupMember.prototype.setWorkspace = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceGroupMember.prototype.clearWorkspace = function() {
+  this.setWorkspace(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceGroupMember.prototype.hasWorkspace = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * repeated string depends_on = 3;
+ * @return {!Array<string>}
+ */
+proto.wsman.WorkspaceGroupMember.prototype.getDependsOnList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 3));
+};
+
+
+/** @param {!Array<string>} value */
+proto.wsman.WorkspaceGroupMember.prototype.setDependsOnList = function(value) {
+  jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ */
+proto.wsman.WorkspaceGroupMember.prototype.addDependsOn = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.WorkspaceGroupMember.prototype.clearDependsOnList = function() {
+  this.setDependsOnList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StartWorkspaceGroupResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartWorkspaceGroupResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StartWorkspaceGroupResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceGroupResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    urlsMap: (f = msg.getUrlsMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StartWorkspaceGroupResponse}
+ */
+proto.wsman.StartWorkspaceGroupResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StartWorkspaceGroupResponse;
+  return proto.wsman.StartWorkspaceGroupResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StartWorkspaceGroupResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StartWorkspaceGroupResponse}
+ */
+proto.wsman.StartWorkspaceGroupResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = msg.getUrlsMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StartWorkspaceGroupResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StartWorkspaceGroupResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StartWorkspaceGroupResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StartWorkspaceGroupResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrlsMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(1, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * map<string, string> urls = 1;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.StartWorkspaceGroupResponse.prototype.getUrlsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 1, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ */
+proto.wsman.StartWorkspaceGroupResponse.prototype.clearUrlsMap = function() {
+  this.getUrlsMap().clear();
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StopWorkspaceGroupRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StopWorkspaceGroupRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StopWorkspaceGroupRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceGroupRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    policy: jspb.Message.getFieldWithDefault(msg, 2, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StopWorkspaceGroupRequest}
+ */
+proto.wsman.StopWorkspaceGroupRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StopWorkspaceGroupRequest;
+  return proto.wsman.StopWorkspaceGroupRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StopWorkspaceGroupRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StopWorkspaceGroupRequest}
+ */
+proto.wsman.StopWorkspaceGroupRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.StopWorkspacePolicy} */ (reader.readEnum());
+      msg.setPolicy(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StopWorkspaceGroupRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StopWorkspaceGroupRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StopWorkspaceGroupRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceGroupRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getPolicy();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.StopWorkspaceGroupRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StopWorkspaceGroupRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional StopWorkspacePolicy policy = 2;
+ * @return {!proto.wsman.StopWorkspacePolicy}
+ */
+proto.wsman.StopWorkspaceGroupRequest.prototype.getPolicy = function() {
+  return /** @type {!proto.wsman.StopWorkspacePolicy} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {!proto.wsman.StopWorkspacePolicy} value */
+proto.wsman.StopWorkspaceGroupRequest.prototype.setPolicy = function(value) {
+  jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.StopWorkspaceGroupResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StopWorkspaceGroupResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.StopWorkspaceGroupResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceGroupResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.StopWorkspaceGroupResponse}
+ */
+proto.wsman.StopWorkspaceGroupResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.StopWorkspaceGroupResponse;
+  return proto.wsman.StopWorkspaceGroupResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.StopWorkspaceGroupResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.StopWorkspaceGroupResponse}
+ */
+proto.wsman.StopWorkspaceGroupResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.StopWorkspaceGroupResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.StopWorkspaceGroupResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.StopWorkspaceGroupResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.StopWorkspaceGroupResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeWorkspaceGroupRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeWorkspaceGroupRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeWorkspaceGroupRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceGroupRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeWorkspaceGroupRequest}
+ */
+proto.wsman.DescribeWorkspaceGroupRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeWorkspaceGroupRequest;
+  return proto.wsman.DescribeWorkspaceGroupRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeWorkspaceGroupRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeWorkspaceGroupRequest}
+ */
+proto.wsman.DescribeWorkspaceGroupRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeWorkspaceGroupRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeWorkspaceGroupRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeWorkspaceGroupRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceGroupRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.DescribeWorkspaceGroupRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DescribeWorkspaceGroupRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeWorkspaceGroupResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeWorkspaceGroupResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    statusList: jspb.Message.toObjectList(msg.getStatusList(),
+    proto.wsman.WorkspaceStatus.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeWorkspaceGroupResponse}
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeWorkspaceGroupResponse;
+  return proto.wsman.DescribeWorkspaceGroupResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeWorkspaceGroupResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeWorkspaceGroupResponse}
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceStatus;
+      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
+      msg.addStatus(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeWorkspaceGroupResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeWorkspaceGroupResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getStatusList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated WorkspaceStatus status = 1;
+ * @return {!Array<!proto.wsman.WorkspaceStatus>}
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.prototype.getStatusList = function() {
+  return /** @type{!Array<!proto.wsman.WorkspaceStatus>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceStatus, 1));
+};
+
+
+/** @param {!Array<!proto.wsman.WorkspaceStatus>} value */
+proto.wsman.DescribeWorkspaceGroupResponse.prototype.setStatusList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceStatus=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceStatus}
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.prototype.addStatus = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.WorkspaceStatus, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.DescribeWorkspaceGroupResponse.prototype.clearStatusList = function() {
+  this.setStatusList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ValidatePodTemplateRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ValidatePodTemplateRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ValidatePodTemplateRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ValidatePodTemplateRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    template: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ValidatePodTemplateRequest}
+ */
+proto.wsman.ValidatePodTemplateRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ValidatePodTemplateRequest;
+  return proto.wsman.ValidatePodTemplateRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ValidatePodTemplateRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ValidatePodTemplateRequest}
+ */
+proto.wsman.ValidatePodTemplateRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTemplate(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ValidatePodTemplateRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ValidatePodTemplateRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ValidatePodTemplateRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ValidatePodTemplateRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getTemplate();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string template = 1;
+ * @return {string}
+ */
+proto.wsman.ValidatePodTemplateRequest.prototype.getTemplate = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ValidatePodTemplateRequest.prototype.setTemplate = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.ValidatePodTemplateResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ValidatePodTemplateResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ValidatePodTemplateResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ValidatePodTemplateResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ValidatePodTemplateResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    violationsList: jspb.Message.toObjectList(msg.getViolationsList(),
+    proto.wsman.PodTemplateViolation.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ValidatePodTemplateResponse}
+ */
+proto.wsman.ValidatePodTemplateResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ValidatePodTemplateResponse;
+  return proto.wsman.ValidatePodTemplateResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ValidatePodTemplateResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ValidatePodTemplateResponse}
+ */
+proto.wsman.ValidatePodTemplateResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.PodTemplateViolation;
+      reader.readMessage(value,proto.wsman.PodTemplateViolation.deserializeBinaryFromReader);
+      msg.addViolations(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ValidatePodTemplateResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ValidatePodTemplateResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ValidatePodTemplateResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ValidatePodTemplateResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getViolationsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.wsman.PodTemplateViolation.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated PodTemplateViolation violations = 1;
+ * @return {!Array<!proto.wsman.PodTemplateViolation>}
+ */
+proto.wsman.ValidatePodTemplateResponse.prototype.getViolationsList = function() {
+  return /** @type{!Array<!proto.wsman.PodTemplateViolation>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PodTemplateViolation, 1));
+};
+
+
+/** @param {!Array<!proto.wsman.PodTemplateViolation>} value */
+proto.wsman.ValidatePodTemplateResponse.prototype.setViolationsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.wsman.PodTemplateViolation=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PodTemplateViolation}
+ */
+proto.wsman.ValidatePodTemplateResponse.prototype.addViolations = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.PodTemplateViolation, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.ValidatePodTemplateResponse.prototype.clearViolationsList = function() {
+  this.setViolationsList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.PodTemplateViolation.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.PodTemplateViolation.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.PodTemplateViolation} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PodTemplateViolation.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    field: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    message: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.PodTemplateViolation}
+ */
+proto.wsman.PodTemplateViolation.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.PodTemplateViolation;
+  return proto.wsman.PodTemplateViolation.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.PodTemplateViolation} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.PodTemplateViolation}
+ */
+proto.wsman.PodTemplateViolation.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setField(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMessage(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.PodTemplateViolation.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.PodTemplateViolation.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.PodTemplateViolation} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PodTemplateViolation.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getField();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getMessage();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string field = 1;
+ * @return {string}
+ */
+proto.wsman.PodTemplateViolation.prototype.getField = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.PodTemplateViolation.prototype.setField = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string message = 2;
+ * @return {string}
+ */
+proto.wsman.PodTemplateViolation.prototype.getMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.PodTemplateViolation.prototype.setMessage = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.ReportProxyActivityRequest.repeatedFields_ = [2];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ReportProxyActivityRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ReportProxyActivityRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ReportProxyActivityRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ReportProxyActivityRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    proxy: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    workspacesList: jspb.Message.toObjectList(msg.getWorkspacesList(),
+    proto.wsman.WorkspaceProxyActivity.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ReportProxyActivityRequest}
+ */
+proto.wsman.ReportProxyActivityRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ReportProxyActivityRequest;
+  return proto.wsman.ReportProxyActivityRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ReportProxyActivityRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ReportProxyActivityRequest}
+ */
+proto.wsman.ReportProxyActivityRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProxy(value);
+      break;
+    case 2:
+      var value = new proto.wsman.WorkspaceProxyActivity;
+      reader.readMessage(value,proto.wsman.WorkspaceProxyActivity.deserializeBinaryFromReader);
+      msg.addWorkspaces(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ReportProxyActivityRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ReportProxyActivityRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ReportProxyActivityRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ReportProxyActivityRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getProxy();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getWorkspacesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      2,
+      f,
+      proto.wsman.WorkspaceProxyActivity.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string proxy = 1;
+ * @return {string}
+ */
+proto.wsman.ReportProxyActivityRequest.prototype.getProxy = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ReportProxyActivityRequest.prototype.setProxy = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * repeated WorkspaceProxyActivity workspaces = 2;
+ * @return {!Array<!proto.wsman.WorkspaceProxyActivity>}
+ */
+proto.wsman.ReportProxyActivityRequest.prototype.getWorkspacesList = function() {
+  return /** @type{!Array<!proto.wsman.WorkspaceProxyActivity>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceProxyActivity, 2));
+};
+
+
+/** @param {!Array<!proto.wsman.WorkspaceProxyActivity>} value */
+proto.wsman.ReportProxyActivityRequest.prototype.setWorkspacesList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 2, value);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceProxyActivity=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceProxyActivity}
+ */
+proto.wsman.ReportProxyActivityRequest.prototype.addWorkspaces = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 2, opt_value, proto.wsman.WorkspaceProxyActivity, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.ReportProxyActivityRequest.prototype.clearWorkspacesList = function() {
+  this.setWorkspacesList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceProxyActivity.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceProxyActivity.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceProxyActivity} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceProxyActivity.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    connections: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    sessions: jspb.Message.getFieldWithDefault(msg, 3, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceProxyActivity}
+ */
+proto.wsman.WorkspaceProxyActivity.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceProxyActivity;
+  return proto.wsman.WorkspaceProxyActivity.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceProxyActivity} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceProxyActivity}
+ */
+proto.wsman.WorkspaceProxyActivity.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setConnections(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setSessions(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceProxyActivity.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceProxyActivity.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceProxyActivity} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceProxyActivity.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getConnections();
+  if (f !== 0) {
+    writer.writeUint32(
+      2,
+      f
+    );
+  }
+  f = message.getSessions();
+  if (f !== 0) {
+    writer.writeUint32(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceProxyActivity.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceProxyActivity.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional uint32 connections = 2;
+ * @return {number}
+ */
+proto.wsman.WorkspaceProxyActivity.prototype.getConnections = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.WorkspaceProxyActivity.prototype.setConnections = function(value) {
+  jspb.Message.setProto3IntField(this, 2, value);
+};
+
+
+/**
+ * optional uint32 sessions = 3;
+ * @return {number}
+ */
+proto.wsman.WorkspaceProxyActivity.prototype.getSessions = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.WorkspaceProxyActivity.prototype.setSessions = function(value) {
+  jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ReportProxyActivityResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ReportProxyActivityResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ReportProxyActivityResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ReportProxyActivityResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    minInterval: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ReportProxyActivityResponse}
+ */
+proto.wsman.ReportProxyActivityResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ReportProxyActivityResponse;
+  return proto.wsman.ReportProxyActivityResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ReportProxyActivityResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ReportProxyActivityResponse}
+ */
+proto.wsman.ReportProxyActivityResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMinInterval(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ReportProxyActivityResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ReportProxyActivityResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ReportProxyActivityResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ReportProxyActivityResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getMinInterval();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string min_interval = 1;
+ * @return {string}
+ */
+proto.wsman.ReportProxyActivityResponse.prototype.getMinInterval = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ReportProxyActivityResponse.prototype.setMinInterval = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.SubscribeAccountingRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SubscribeAccountingRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SubscribeAccountingRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeAccountingRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    afterSequence: jspb.Message.getFieldWithDefault(msg, 1, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SubscribeAccountingRequest}
+ */
+proto.wsman.SubscribeAccountingRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SubscribeAccountingRequest;
+  return proto.wsman.SubscribeAccountingRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SubscribeAccountingRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SubscribeAccountingRequest}
+ */
+proto.wsman.SubscribeAccountingRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setAfterSequence(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.SubscribeAccountingRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SubscribeAccountingRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SubscribeAccountingRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeAccountingRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getAfterSequence();
+  if (f !== 0) {
+    writer.writeUint64(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional uint64 after_sequence = 1;
+ * @return {number}
+ */
+proto.wsman.SubscribeAccountingRequest.prototype.getAfterSequence = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.SubscribeAccountingRequest.prototype.setAfterSequence = function(value) {
+  jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.AccountingRecord.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.AccountingRecord.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.AccountingRecord} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.AccountingRecord.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    sequence: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    event: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    id: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
+    type: jspb.Message.getFieldWithDefault(msg, 5, 0),
+    nodeName: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    time: (f = msg.getTime()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    runtimeSeconds: jspb.Message.getFieldWithDefault(msg, 8, 0),
+    estimated: jspb.Message.getFieldWithDefault(msg, 9, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.AccountingRecord}
+ */
+proto.wsman.AccountingRecord.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.AccountingRecord;
+  return proto.wsman.AccountingRecord.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.AccountingRecord} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.AccountingRecord}
+ */
+proto.wsman.AccountingRecord.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setSequence(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.AccountingEvent} */ (reader.readEnum());
+      msg.setEvent(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 4:
+      var value = new proto.wsman.WorkspaceMetadata;
+      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
+      msg.setMetadata(value);
+      break;
+    case 5:
+      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
+      msg.setType(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setNodeName(value);
+      break;
+    case 7:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setTime(value);
+      break;
+    case 8:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setRuntimeSeconds(value);
+      break;
+    case 9:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setEstimated(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.AccountingRecord.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.AccountingRecord.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.AccountingRecord} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.AccountingRecord.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSequence();
+  if (f !== 0) {
+    writer.writeUint64(
+      1,
+      f
+    );
+  }
+  f = message.getEvent();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getMetadata();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
+    );
+  }
+  f = message.getType();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      5,
+      f
+    );
+  }
+  f = message.getNodeName();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+  f = message.getTime();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getRuntimeSeconds();
+  if (f !== 0) {
+    writer.writeUint64(
+      8,
+      f
+    );
+  }
+  f = message.getEstimated();
+  if (f) {
+    writer.writeBool(
+      9,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional uint64 sequence = 1;
+ * @return {number}
+ */
+proto.wsman.AccountingRecord.prototype.getSequence = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.AccountingRecord.prototype.setSequence = function(value) {
+  jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+/**
+ * optional AccountingEvent event = 2;
+ * @return {!proto.wsman.AccountingEvent}
+ */
+proto.wsman.AccountingRecord.prototype.getEvent = function() {
+  return /** @type {!proto.wsman.AccountingEvent} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {!proto.wsman.AccountingEvent} value */
+proto.wsman.AccountingRecord.prototype.setEvent = function(value) {
+  jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+/**
+ * optional string id = 3;
+ * @return {string}
+ */
+proto.wsman.AccountingRecord.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.AccountingRecord.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional WorkspaceMetadata metadata = 4;
+ * @return {?proto.wsman.WorkspaceMetadata}
+ */
+proto.wsman.AccountingRecord.prototype.getMetadata = function() {
+  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 4));
+};
+
+
+/** @param {?proto.wsman.WorkspaceMetadata|undefined} value */
+proto.wsman.AccountingRecord.prototype.setMetadata = function(value) {
+  jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.AccountingRecord.prototype.clearMetadata = function() {
+  this.setMetadata(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.AccountingRecord.prototype.hasMetadata = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * optional WorkspaceType type = 5;
+ * @return {!proto.wsman.WorkspaceType}
+ */
+proto.wsman.AccountingRecord.prototype.getType = function() {
+  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+};
+
+
+/** @param {!proto.wsman.WorkspaceType} value */
+proto.wsman.AccountingRecord.prototype.setType = function(value) {
+  jspb.Message.setProto3EnumField(this, 5, value);
+};
+
+
+/**
+ * optional string node_name = 6;
+ * @return {string}
+ */
+proto.wsman.AccountingRecord.prototype.getNodeName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.AccountingRecord.prototype.setNodeName = function(value) {
+  jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp time = 7;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.AccountingRecord.prototype.getTime = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 7));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.AccountingRecord.prototype.setTime = function(value) {
+  jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.AccountingRecord.prototype.clearTime = function() {
+  this.setTime(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.AccountingRecord.prototype.hasTime = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional uint64 runtime_seconds = 8;
+ * @return {number}
+ */
+proto.wsman.AccountingRecord.prototype.getRuntimeSeconds = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 8, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.AccountingRecord.prototype.setRuntimeSeconds = function(value) {
+  jspb.Message.setProto3IntField(this, 8, value);
+};
+
+
+/**
+ * optional bool estimated = 9;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.AccountingRecord.prototype.getEstimated = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 9, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.AccountingRecord.prototype.setEstimated = function(value) {
+  jspb.Message.setProto3BooleanField(this, 9, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.AckAccountingRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.AckAccountingRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.AckAccountingRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.AckAccountingRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    sequence: jspb.Message.getFieldWithDefault(msg, 1, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.AckAccountingRequest}
+ */
+proto.wsman.AckAccountingRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.AckAccountingRequest;
+  return proto.wsman.AckAccountingRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.AckAccountingRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.AckAccountingRequest}
+ */
+proto.wsman.AckAccountingRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setSequence(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.AckAccountingRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.AckAccountingRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.AckAccountingRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.AckAccountingRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSequence();
+  if (f !== 0) {
+    writer.writeUint64(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional uint64 sequence = 1;
+ * @return {number}
+ */
+proto.wsman.AckAccountingRequest.prototype.getSequence = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.AckAccountingRequest.prototype.setSequence = function(value) {
+  jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.AckAccountingResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.AckAccountingResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.AckAccountingResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.AckAccountingResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    acknowledged: jspb.Message.getFieldWithDefault(msg, 1, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.AckAccountingResponse}
+ */
+proto.wsman.AckAccountingResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.AckAccountingResponse;
+  return proto.wsman.AckAccountingResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.AckAccountingResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.AckAccountingResponse}
+ */
+proto.wsman.AckAccountingResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setAcknowledged(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.AckAccountingResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.AckAccountingResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.AckAccountingResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.AckAccountingResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getAcknowledged();
+  if (f !== 0) {
+    writer.writeUint64(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional uint64 acknowledged = 1;
+ * @return {number}
+ */
+proto.wsman.AckAccountingResponse.prototype.getAcknowledged = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.AckAccountingResponse.prototype.setAcknowledged = function(value) {
+  jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ScheduleStopRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ScheduleStopRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ScheduleStopRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ScheduleStopRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    at: (f = msg.getAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    duration: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    cancel: jspb.Message.getFieldWithDefault(msg, 4, false),
+    expectedGeneration: jspb.Message.getFieldWithDefault(msg, 5, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ScheduleStopRequest}
+ */
+proto.wsman.ScheduleStopRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ScheduleStopRequest;
+  return proto.wsman.ScheduleStopRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ScheduleStopRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ScheduleStopRequest}
+ */
+proto.wsman.ScheduleStopRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setAt(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDuration(value);
+      break;
+    case 4:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setCancel(value);
+      break;
+    case 5:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setExpectedGeneration(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ScheduleStopRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ScheduleStopRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ScheduleStopRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ScheduleStopRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getAt();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getDuration();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getCancel();
+  if (f) {
+    writer.writeBool(
+      4,
+      f
+    );
+  }
+  f = message.getExpectedGeneration();
+  if (f !== 0) {
+    writer.writeUint64(
+      5,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.ScheduleStopRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ScheduleStopRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp at = 2;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.ScheduleStopRequest.prototype.getAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 2));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.ScheduleStopRequest.prototype.setAt = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.ScheduleStopRequest.prototype.clearAt = function() {
+  this.setAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ScheduleStopRequest.prototype.hasAt = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional string duration = 3;
+ * @return {string}
+ */
+proto.wsman.ScheduleStopRequest.prototype.getDuration = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ScheduleStopRequest.prototype.setDuration = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional bool cancel = 4;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.ScheduleStopRequest.prototype.getCancel = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 4, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.ScheduleStopRequest.prototype.setCancel = function(value) {
+  jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+/**
+ * optional uint64 expected_generation = 5;
+ * @return {number}
+ */
+proto.wsman.ScheduleStopRequest.prototype.getExpectedGeneration = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.ScheduleStopRequest.prototype.setExpectedGeneration = function(value) {
+  jspb.Message.setProto3IntField(this, 5, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ScheduleStopResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ScheduleStopResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ScheduleStopResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ScheduleStopResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    deadline: (f = msg.getDeadline()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ScheduleStopResponse}
+ */
+proto.wsman.ScheduleStopResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ScheduleStopResponse;
+  return proto.wsman.ScheduleStopResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ScheduleStopResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ScheduleStopResponse}
+ */
+proto.wsman.ScheduleStopResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setDeadline(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ScheduleStopResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ScheduleStopResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ScheduleStopResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ScheduleStopResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getDeadline();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional google.protobuf.Timestamp deadline = 1;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.ScheduleStopResponse.prototype.getDeadline = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 1));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.ScheduleStopResponse.prototype.setDeadline = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.ScheduleStopResponse.prototype.clearDeadline = function() {
+  this.setDeadline(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ScheduleStopResponse.prototype.hasDeadline = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.GenerateWorkspaceIDRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GenerateWorkspaceIDRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GenerateWorkspaceIDRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GenerateWorkspaceIDRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    project: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GenerateWorkspaceIDRequest}
+ */
+proto.wsman.GenerateWorkspaceIDRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GenerateWorkspaceIDRequest;
+  return proto.wsman.GenerateWorkspaceIDRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GenerateWorkspaceIDRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GenerateWorkspaceIDRequest}
+ */
+proto.wsman.GenerateWorkspaceIDRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProject(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.GenerateWorkspaceIDRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GenerateWorkspaceIDRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GenerateWorkspaceIDRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GenerateWorkspaceIDRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getProject();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string project = 1;
+ * @return {string}
+ */
+proto.wsman.GenerateWorkspaceIDRequest.prototype.getProject = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.GenerateWorkspaceIDRequest.prototype.setProject = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.GenerateWorkspaceIDResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GenerateWorkspaceIDResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GenerateWorkspaceIDResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GenerateWorkspaceIDResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GenerateWorkspaceIDResponse}
+ */
+proto.wsman.GenerateWorkspaceIDResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GenerateWorkspaceIDResponse;
+  return proto.wsman.GenerateWorkspaceIDResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GenerateWorkspaceIDResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GenerateWorkspaceIDResponse}
+ */
+proto.wsman.GenerateWorkspaceIDResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.GenerateWorkspaceIDResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GenerateWorkspaceIDResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GenerateWorkspaceIDResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GenerateWorkspaceIDResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.GenerateWorkspaceIDResponse.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.GenerateWorkspaceIDResponse.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ExportStateRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ExportStateRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ExportStateRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ExportStateRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ExportStateRequest}
+ */
+proto.wsman.ExportStateRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ExportStateRequest;
+  return proto.wsman.ExportStateRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ExportStateRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ExportStateRequest}
+ */
+proto.wsman.ExportStateRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ExportStateRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ExportStateRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ExportStateRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ExportStateRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ExportStateResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ExportStateResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ExportStateResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ExportStateResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    snapshot: msg.getSnapshot_asB64(),
+    version: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    createdAt: (f = msg.getCreatedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ExportStateResponse}
+ */
+proto.wsman.ExportStateResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ExportStateResponse;
+  return proto.wsman.ExportStateResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ExportStateResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ExportStateResponse}
+ */
+proto.wsman.ExportStateResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {!Uint8Array} */ (reader.readBytes());
+      msg.setSnapshot(value);
+      break;
+    case 2:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setVersion(value);
+      break;
+    case 3:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setCreatedAt(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ExportStateResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ExportStateResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ExportStateResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ExportStateResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSnapshot_asU8();
+  if (f.length > 0) {
+    writer.writeBytes(
+      1,
+      f
+    );
+  }
+  f = message.getVersion();
+  if (f !== 0) {
+    writer.writeUint32(
+      2,
+      f
+    );
+  }
+  f = message.getCreatedAt();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional bytes snapshot = 1;
+ * @return {!(string|Uint8Array)}
+ */
+proto.wsman.ExportStateResponse.prototype.getSnapshot = function() {
+  return /** @type {!(string|Uint8Array)} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * optional bytes snapshot = 1;
+ * This is a type-conversion wrapper around `getSnapshot()`
+ * @return {string}
+ */
+proto.wsman.ExportStateResponse.prototype.getSnapshot_asB64 = function() {
+  return /** @type {string} */ (jspb.Message.bytesAsB64(
+      this.getSnapshot()));
+};
+
+
+/**
+ * optional bytes snapshot = 1;
+ * Note that Uint8Array is not supported on all browsers.
+ * @see http://caniuse.com/Uint8Array
+ * This is a type-conversion wrapper around `getSnapshot()`
+ * @return {!Uint8Array}
+ */
+proto.wsman.ExportStateResponse.prototype.getSnapshot_asU8 = function() {
+  return /** @type {!Uint8Array} */ (jspb.Message.bytesAsU8(
+      this.getSnapshot()));
+};
+
+
+/** @param {!(string|Uint8Array)} value */
+proto.wsman.ExportStateResponse.prototype.setSnapshot = function(value) {
+  jspb.Message.setProto3BytesField(this, 1, value);
+};
+
+
+/**
+ * optional uint32 version = 2;
+ * @return {number}
+ */
+proto.wsman.ExportStateResponse.prototype.getVersion = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.ExportStateResponse.prototype.setVersion = function(value) {
+  jspb.Message.setProto3IntField(this, 2, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp created_at = 3;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.ExportStateResponse.prototype.getCreatedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.ExportStateResponse.prototype.setCreatedAt = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.ExportStateResponse.prototype.clearCreatedAt = function() {
+  this.setCreatedAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ExportStateResponse.prototype.hasCreatedAt = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ImportStateRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ImportStateRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ImportStateRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ImportStateRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    snapshot: msg.getSnapshot_asB64()
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ImportStateRequest}
+ */
+proto.wsman.ImportStateRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ImportStateRequest;
+  return proto.wsman.ImportStateRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ImportStateRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ImportStateRequest}
+ */
+proto.wsman.ImportStateRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {!Uint8Array} */ (reader.readBytes());
+      msg.setSnapshot(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ImportStateRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ImportStateRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ImportStateRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ImportStateRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSnapshot_asU8();
+  if (f.length > 0) {
+    writer.writeBytes(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional bytes snapshot = 1;
+ * @return {!(string|Uint8Array)}
+ */
+proto.wsman.ImportStateRequest.prototype.getSnapshot = function() {
+  return /** @type {!(string|Uint8Array)} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * optional bytes snapshot = 1;
+ * This is a type-conversion wrapper around `getSnapshot()`
+ * @return {string}
+ */
+proto.wsman.ImportStateRequest.prototype.getSnapshot_asB64 = function() {
+  return /** @type {string} */ (jspb.Message.bytesAsB64(
+      this.getSnapshot()));
+};
+
+
+/**
+ * optional bytes snapshot = 1;
+ * Note that Uint8Array is not supported on all browsers.
+ * @see http://caniuse.com/Uint8Array
+ * This is a type-conversion wrapper around `getSnapshot()`
+ * @return {!Uint8Array}
+ */
+proto.wsman.ImportStateRequest.prototype.getSnapshot_asU8 = function() {
+  return /** @type {!Uint8Array} */ (jspb.Message.bytesAsU8(
+      this.getSnapshot()));
+};
+
+
+/** @param {!(string|Uint8Array)} value */
+proto.wsman.ImportStateRequest.prototype.setSnapshot = function(value) {
+  jspb.Message.setProto3BytesField(this, 1, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.ImportStateResponse.repeatedFields_ = [1,2,3,4];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ImportStateResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ImportStateResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ImportStateResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ImportStateResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    restoredInstancesList: jspb.Message.getRepeatedField(msg, 1),
+    droppedInstancesList: jspb.Message.getRepeatedField(msg, 2),
+    resumedOperationsList: jspb.Message.toObjectList(msg.getResumedOperationsList(),
+    proto.wsman.PendingOperation.toObject, includeInstance),
+    lostOperationsList: jspb.Message.toObjectList(msg.getLostOperationsList(),
+    proto.wsman.PendingOperation.toObject, includeInstance),
+    maintenanceRestored: jspb.Message.getFieldWithDefault(msg, 5, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ImportStateResponse}
+ */
+proto.wsman.ImportStateResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ImportStateResponse;
+  return proto.wsman.ImportStateResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ImportStateResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ImportStateResponse}
+ */
+proto.wsman.ImportStateResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addRestoredInstances(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addDroppedInstances(value);
+      break;
+    case 3:
+      var value = new proto.wsman.PendingOperation;
+      reader.readMessage(value,proto.wsman.PendingOperation.deserializeBinaryFromReader);
+      msg.addResumedOperations(value);
+      break;
+    case 4:
+      var value = new proto.wsman.PendingOperation;
+      reader.readMessage(value,proto.wsman.PendingOperation.deserializeBinaryFromReader);
+      msg.addLostOperations(value);
+      break;
+    case 5:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setMaintenanceRestored(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ImportStateResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ImportStateResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ImportStateResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ImportStateResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getRestoredInstancesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      1,
+      f
+    );
+  }
+  f = message.getDroppedInstancesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      2,
+      f
+    );
+  }
+  f = message.getResumedOperationsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      3,
+      f,
+      proto.wsman.PendingOperation.serializeBinaryToWriter
+    );
+  }
+  f = message.getLostOperationsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      4,
+      f,
+      proto.wsman.PendingOperation.serializeBinaryToWriter
+    );
+  }
+  f = message.getMaintenanceRestored();
+  if (f) {
+    writer.writeBool(
+      5,
+      f
+    );
+  }
+};
+
+
+/**
+ * repeated string restored_instances = 1;
+ * @return {!Array<string>}
+ */
+proto.wsman.ImportStateResponse.prototype.getRestoredInstancesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+};
+
+
+/** @param {!Array<string>} value */
+proto.wsman.ImportStateResponse.prototype.setRestoredInstancesList = function(value) {
+  jspb.Message.setField(this, 1, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ */
+proto.wsman.ImportStateResponse.prototype.addRestoredInstances = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.ImportStateResponse.prototype.clearRestoredInstancesList = function() {
+  this.setRestoredInstancesList([]);
+};
+
+
+/**
+ * repeated string dropped_instances = 2;
+ * @return {!Array<string>}
+ */
+proto.wsman.ImportStateResponse.prototype.getDroppedInstancesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 2));
+};
+
+
+/** @param {!Array<string>} value */
+proto.wsman.ImportStateResponse.prototype.setDroppedInstancesList = function(value) {
+  jspb.Message.setField(this, 2, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ */
+proto.wsman.ImportStateResponse.prototype.addDroppedInstances = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 2, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.ImportStateResponse.prototype.clearDroppedInstancesList = function() {
+  this.setDroppedInstancesList([]);
+};
+
+
+/**
+ * repeated PendingOperation resumed_operations = 3;
+ * @return {!Array<!proto.wsman.PendingOperation>}
+ */
+proto.wsman.ImportStateResponse.prototype.getResumedOperationsList = function() {
+  return /** @type{!Array<!proto.wsman.PendingOperation>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PendingOperation, 3));
+};
+
+
+/** @param {!Array<!proto.wsman.PendingOperation>} value */
+proto.wsman.ImportStateResponse.prototype.setResumedOperationsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 3, value);
+};
+
+
+/**
+ * @param {!proto.wsman.PendingOperation=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PendingOperation}
+ */
+proto.wsman.ImportStateResponse.prototype.addResumedOperations = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 3, opt_value, proto.wsman.PendingOperation, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.ImportStateResponse.prototype.clearResumedOperationsList = function() {
+  this.setResumedOperationsList([]);
+};
+
+
+/**
+ * repeated PendingOperation lost_operations = 4;
+ * @return {!Array<!proto.wsman.PendingOperation>}
+ */
+proto.wsman.ImportStateResponse.prototype.getLostOperationsList = function() {
+  return /** @type{!Array<!proto.wsman.PendingOperation>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PendingOperation, 4));
+};
+
+
+/** @param {!Array<!proto.wsman.PendingOperation>} value */
+proto.wsman.ImportStateResponse.prototype.setLostOperationsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 4, value);
+};
+
+
+/**
+ * @param {!proto.wsman.PendingOperation=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PendingOperation}
+ */
+proto.wsman.ImportStateResponse.prototype.addLostOperations = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 4, opt_value, proto.wsman.PendingOperation, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.ImportStateResponse.prototype.clearLostOperationsList = function() {
+  this.setLostOperationsList([]);
+};
+
+
+/**
+ * optional bool maintenance_restored = 5;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.ImportStateResponse.prototype.getMaintenanceRestored = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 5, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.ImportStateResponse.prototype.setMaintenanceRestored = function(value) {
+  jspb.Message.setProto3BooleanField(this, 5, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.PendingOperation.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.PendingOperation.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.PendingOperation} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PendingOperation.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    instanceId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    kind: jspb.Message.getFieldWithDefault(msg, 2, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.PendingOperation}
+ */
+proto.wsman.PendingOperation.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.PendingOperation;
+  return proto.wsman.PendingOperation.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.PendingOperation} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.PendingOperation}
+ */
+proto.wsman.PendingOperation.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setInstanceId(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.PendingOperationKind} */ (reader.readEnum());
+      msg.setKind(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.PendingOperation.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.PendingOperation.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.PendingOperation} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PendingOperation.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getInstanceId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getKind();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string instance_id = 1;
+ * @return {string}
+ */
+proto.wsman.PendingOperation.prototype.getInstanceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.PendingOperation.prototype.setInstanceId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional PendingOperationKind kind = 2;
+ * @return {!proto.wsman.PendingOperationKind}
+ */
+proto.wsman.PendingOperation.prototype.getKind = function() {
+  return /** @type {!proto.wsman.PendingOperationKind} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {!proto.wsman.PendingOperationKind} value */
+proto.wsman.PendingOperation.prototype.setKind = function(value) {
+  jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeArchivalRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeArchivalRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeArchivalRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeArchivalRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceId: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeArchivalRequest}
+ */
+proto.wsman.DescribeArchivalRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeArchivalRequest;
+  return proto.wsman.DescribeArchivalRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeArchivalRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeArchivalRequest}
+ */
+proto.wsman.DescribeArchivalRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeArchivalRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeArchivalRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeArchivalRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeArchivalRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_id = 1;
+ * @return {string}
+ */
+proto.wsman.DescribeArchivalRequest.prototype.getWorkspaceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DescribeArchivalRequest.prototype.setWorkspaceId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.DescribeArchivalResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeArchivalResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeArchivalResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeArchivalResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeArchivalResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspacesList: jspb.Message.toObjectList(msg.getWorkspacesList(),
+    proto.wsman.ArchivalStatus.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeArchivalResponse}
+ */
+proto.wsman.DescribeArchivalResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeArchivalResponse;
+  return proto.wsman.DescribeArchivalResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeArchivalResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeArchivalResponse}
+ */
+proto.wsman.DescribeArchivalResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.ArchivalStatus;
+      reader.readMessage(value,proto.wsman.ArchivalStatus.deserializeBinaryFromReader);
+      msg.addWorkspaces(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeArchivalResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeArchivalResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeArchivalResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeArchivalResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspacesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.wsman.ArchivalStatus.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated ArchivalStatus workspaces = 1;
+ * @return {!Array<!proto.wsman.ArchivalStatus>}
+ */
+proto.wsman.DescribeArchivalResponse.prototype.getWorkspacesList = function() {
+  return /** @type{!Array<!proto.wsman.ArchivalStatus>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.ArchivalStatus, 1));
+};
+
+
+/** @param {!Array<!proto.wsman.ArchivalStatus>} value */
+proto.wsman.DescribeArchivalResponse.prototype.setWorkspacesList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.wsman.ArchivalStatus=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.ArchivalStatus}
+ */
+proto.wsman.DescribeArchivalResponse.prototype.addWorkspaces = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.ArchivalStatus, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.DescribeArchivalResponse.prototype.clearWorkspacesList = function() {
+  this.setWorkspacesList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ArchivalStatus.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ArchivalStatus.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ArchivalStatus} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ArchivalStatus.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    phase: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    stoppedAt: (f = msg.getStoppedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    archiveAt: (f = msg.getArchiveAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    archivedAt: (f = msg.getArchivedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    backupSize: jspb.Message.getFieldWithDefault(msg, 7, 0),
+    unarchiving: jspb.Message.getFieldWithDefault(msg, 8, false),
+    restoreLatency: jspb.Message.getFieldWithDefault(msg, 9, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ArchivalStatus}
+ */
+proto.wsman.ArchivalStatus.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ArchivalStatus;
+  return proto.wsman.ArchivalStatus.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ArchivalStatus} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ArchivalStatus}
+ */
+proto.wsman.ArchivalStatus.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    case 3:
+      var value = /** @type {!proto.wsman.WorkspacePhase} */ (reader.readEnum());
+      msg.setPhase(value);
+      break;
+    case 4:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setStoppedAt(value);
+      break;
+    case 5:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setArchiveAt(value);
+      break;
+    case 6:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setArchivedAt(value);
+      break;
+    case 7:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setBackupSize(value);
+      break;
+    case 8:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setUnarchiving(value);
+      break;
+    case 9:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRestoreLatency(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ArchivalStatus.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ArchivalStatus.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ArchivalStatus} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ArchivalStatus.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getPhase();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
+  f = message.getStoppedAt();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getArchiveAt();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getArchivedAt();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getBackupSize();
+  if (f !== 0) {
+    writer.writeInt64(
+      7,
+      f
+    );
+  }
+  f = message.getUnarchiving();
+  if (f) {
+    writer.writeBool(
+      8,
+      f
+    );
+  }
+  f = message.getRestoreLatency();
+  if (f.length > 0) {
+    writer.writeString(
+      9,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_id = 1;
+ * @return {string}
+ */
+proto.wsman.ArchivalStatus.prototype.getWorkspaceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ArchivalStatus.prototype.setWorkspaceId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string owner = 2;
+ * @return {string}
+ */
+proto.wsman.ArchivalStatus.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ArchivalStatus.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional WorkspacePhase phase = 3;
+ * @return {!proto.wsman.WorkspacePhase}
+ */
+proto.wsman.ArchivalStatus.prototype.getPhase = function() {
+  return /** @type {!proto.wsman.WorkspacePhase} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {!proto.wsman.WorkspacePhase} value */
+proto.wsman.ArchivalStatus.prototype.setPhase = function(value) {
+  jspb.Message.setProto3EnumField(this, 3, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp stopped_at = 4;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.ArchivalStatus.prototype.getStoppedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 4));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.ArchivalStatus.prototype.setStoppedAt = function(value) {
+  jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.ArchivalStatus.prototype.clearStoppedAt = function() {
+  this.setStoppedAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ArchivalStatus.prototype.hasStoppedAt = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * optional google.protobuf.Timestamp archive_at = 5;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.ArchivalStatus.prototype.getArchiveAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 5));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.ArchivalStatus.prototype.setArchiveAt = function(value) {
+  jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.ArchivalStatus.prototype.clearArchiveAt = function() {
+  this.setArchiveAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ArchivalStatus.prototype.hasArchiveAt = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * optional google.protobuf.Timestamp archived_at = 6;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.ArchivalStatus.prototype.getArchivedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 6));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.ArchivalStatus.prototype.setArchivedAt = function(value) {
+  jspb.Message.setWrapperField(this, 6, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.ArchivalStatus.prototype.clearArchivedAt = function() {
+  this.setArchivedAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.ArchivalStatus.prototype.hasArchivedAt = function() {
+  return jspb.Message.getField(this, 6) != null;
+};
+
+
+/**
+ * optional int64 backup_size = 7;
+ * @return {number}
+ */
+proto.wsman.ArchivalStatus.prototype.getBackupSize = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.ArchivalStatus.prototype.setBackupSize = function(value) {
+  jspb.Message.setProto3IntField(this, 7, value);
+};
+
+
+/**
+ * optional bool unarchiving = 8;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.ArchivalStatus.prototype.getUnarchiving = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 8, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.ArchivalStatus.prototype.setUnarchiving = function(value) {
+  jspb.Message.setProto3BooleanField(this, 8, value);
+};
+
+
+/**
+ * optional string restore_latency = 9;
+ * @return {string}
+ */
+proto.wsman.ArchivalStatus.prototype.getRestoreLatency = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 9, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ArchivalStatus.prototype.setRestoreLatency = function(value) {
+  jspb.Message.setProto3StringField(this, 9, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.UnarchiveWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.UnarchiveWorkspaceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.UnarchiveWorkspaceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UnarchiveWorkspaceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceId: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.UnarchiveWorkspaceRequest}
+ */
+proto.wsman.UnarchiveWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.UnarchiveWorkspaceRequest;
+  return proto.wsman.UnarchiveWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.UnarchiveWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.UnarchiveWorkspaceRequest}
+ */
+proto.wsman.UnarchiveWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.UnarchiveWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.UnarchiveWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.UnarchiveWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UnarchiveWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_id = 1;
+ * @return {string}
+ */
+proto.wsman.UnarchiveWorkspaceRequest.prototype.getWorkspaceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.UnarchiveWorkspaceRequest.prototype.setWorkspaceId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.UnarchiveWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.UnarchiveWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UnarchiveWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    readyAt: (f = msg.getReadyAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    restoreLatency: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.UnarchiveWorkspaceResponse}
+ */
+proto.wsman.UnarchiveWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.UnarchiveWorkspaceResponse;
+  return proto.wsman.UnarchiveWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.UnarchiveWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.UnarchiveWorkspaceResponse}
+ */
+proto.wsman.UnarchiveWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setReadyAt(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRestoreLatency(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.UnarchiveWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.UnarchiveWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UnarchiveWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getReadyAt();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getRestoreLatency();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional google.protobuf.Timestamp ready_at = 1;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.getReadyAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 1));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.setReadyAt = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.clearReadyAt = function() {
+  this.setReadyAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.hasReadyAt = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional string restore_latency = 2;
+ * @return {string}
+ */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.getRestoreLatency = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.UnarchiveWorkspaceResponse.prototype.setRestoreLatency = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DeleteWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DeleteWorkspaceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DeleteWorkspaceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    metadataMap: (f = msg.getMetadataMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DeleteWorkspaceRequest}
+ */
+proto.wsman.DeleteWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DeleteWorkspaceRequest;
+  return proto.wsman.DeleteWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DeleteWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DeleteWorkspaceRequest}
+ */
+proto.wsman.DeleteWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    case 3:
+      var value = msg.getMetadataMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DeleteWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DeleteWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DeleteWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getMetadataMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * optional string workspace_id = 1;
+ * @return {string}
+ */
+proto.wsman.DeleteWorkspaceRequest.prototype.getWorkspaceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DeleteWorkspaceRequest.prototype.setWorkspaceId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string owner = 2;
+ * @return {string}
+ */
+proto.wsman.DeleteWorkspaceRequest.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DeleteWorkspaceRequest.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * map<string, string> metadata = 3;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.DeleteWorkspaceRequest.prototype.getMetadataMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ */
+proto.wsman.DeleteWorkspaceRequest.prototype.clearMetadataMap = function() {
+  this.getMetadataMap().clear();
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DeleteWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DeleteWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DeleteWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    purgeAt: (f = msg.getPurgeAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DeleteWorkspaceResponse}
+ */
+proto.wsman.DeleteWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DeleteWorkspaceResponse;
+  return proto.wsman.DeleteWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DeleteWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DeleteWorkspaceResponse}
+ */
+proto.wsman.DeleteWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setPurgeAt(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DeleteWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DeleteWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DeleteWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getPurgeAt();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional google.protobuf.Timestamp purge_at = 1;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.DeleteWorkspaceResponse.prototype.getPurgeAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 1));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.DeleteWorkspaceResponse.prototype.setPurgeAt = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.DeleteWorkspaceResponse.prototype.clearPurgeAt = function() {
+  this.setPurgeAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.DeleteWorkspaceResponse.prototype.hasPurgeAt = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.RestoreDeletedWorkspaceRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.RestoreDeletedWorkspaceRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceId: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.RestoreDeletedWorkspaceRequest}
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.RestoreDeletedWorkspaceRequest;
+  return proto.wsman.RestoreDeletedWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.RestoreDeletedWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.RestoreDeletedWorkspaceRequest}
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.RestoreDeletedWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.RestoreDeletedWorkspaceRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_id = 1;
+ * @return {string}
+ */
+proto.wsman.RestoreDeletedWorkspaceRequest.prototype.getWorkspaceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.RestoreDeletedWorkspaceRequest.prototype.setWorkspaceId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.RestoreDeletedWorkspaceResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.RestoreDeletedWorkspaceResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspace: (f = msg.getWorkspace()) && proto.wsman.DeletedWorkspace.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.RestoreDeletedWorkspaceResponse}
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.RestoreDeletedWorkspaceResponse;
+  return proto.wsman.RestoreDeletedWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.RestoreDeletedWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.RestoreDeletedWorkspaceResponse}
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.DeletedWorkspace;
+      reader.readMessage(value,proto.wsman.DeletedWorkspace.deserializeBinaryFromReader);
+      msg.setWorkspace(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.RestoreDeletedWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.RestoreDeletedWorkspaceResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspace();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.DeletedWorkspace.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional DeletedWorkspace workspace = 1;
+ * @return {?proto.wsman.DeletedWorkspace}
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.prototype.getWorkspace = function() {
+  return /** @type{?proto.wsman.DeletedWorkspace} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.DeletedWorkspace, 1));
+};
+
+
+/** @param {?proto.wsman.DeletedWorkspace|undefined} value */
+proto.wsman.RestoreDeletedWorkspaceResponse.prototype.setWorkspace = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.prototype.clearWorkspace = function() {
+  this.setWorkspace(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.RestoreDeletedWorkspaceResponse.prototype.hasWorkspace = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeDeletedWorkspacesRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeDeletedWorkspacesRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    owner: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeDeletedWorkspacesRequest}
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeDeletedWorkspacesRequest;
+  return proto.wsman.DescribeDeletedWorkspacesRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeDeletedWorkspacesRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeDeletedWorkspacesRequest}
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeDeletedWorkspacesRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeDeletedWorkspacesRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string owner = 1;
+ * @return {string}
+ */
+proto.wsman.DescribeDeletedWorkspacesRequest.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DescribeDeletedWorkspacesRequest.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeDeletedWorkspacesResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DescribeDeletedWorkspacesResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspacesList: jspb.Message.toObjectList(msg.getWorkspacesList(),
+    proto.wsman.DeletedWorkspace.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DescribeDeletedWorkspacesResponse}
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DescribeDeletedWorkspacesResponse;
+  return proto.wsman.DescribeDeletedWorkspacesResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DescribeDeletedWorkspacesResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DescribeDeletedWorkspacesResponse}
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.DeletedWorkspace;
+      reader.readMessage(value,proto.wsman.DeletedWorkspace.deserializeBinaryFromReader);
+      msg.addWorkspaces(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DescribeDeletedWorkspacesResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DescribeDeletedWorkspacesResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspacesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.wsman.DeletedWorkspace.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated DeletedWorkspace workspaces = 1;
+ * @return {!Array<!proto.wsman.DeletedWorkspace>}
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.prototype.getWorkspacesList = function() {
+  return /** @type{!Array<!proto.wsman.DeletedWorkspace>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.DeletedWorkspace, 1));
+};
+
+
+/** @param {!Array<!proto.wsman.DeletedWorkspace>} value */
+proto.wsman.DescribeDeletedWorkspacesResponse.prototype.setWorkspacesList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.wsman.DeletedWorkspace=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.DeletedWorkspace}
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.prototype.addWorkspaces = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.DeletedWorkspace, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.DescribeDeletedWorkspacesResponse.prototype.clearWorkspacesList = function() {
+  this.setWorkspacesList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DeletedWorkspace.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DeletedWorkspace.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DeletedWorkspace} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeletedWorkspace.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    deletedAt: (f = msg.getDeletedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    purgeAt: (f = msg.getPurgeAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    metadataMap: (f = msg.getMetadataMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DeletedWorkspace}
+ */
+proto.wsman.DeletedWorkspace.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DeletedWorkspace;
+  return proto.wsman.DeletedWorkspace.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DeletedWorkspace} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DeletedWorkspace}
+ */
+proto.wsman.DeletedWorkspace.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    case 3:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setDeletedAt(value);
+      break;
+    case 4:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setPurgeAt(value);
+      break;
+    case 5:
+      var value = msg.getMetadataMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DeletedWorkspace.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DeletedWorkspace.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DeletedWorkspace} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeletedWorkspace.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getDeletedAt();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getPurgeAt();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getMetadataMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(5, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+};
+
+
+/**
+ * optional string workspace_id = 1;
+ * @return {string}
+ */
+proto.wsman.DeletedWorkspace.prototype.getWorkspaceId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DeletedWorkspace.prototype.setWorkspaceId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string owner = 2;
+ * @return {string}
+ */
+proto.wsman.DeletedWorkspace.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DeletedWorkspace.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp deleted_at = 3;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.DeletedWorkspace.prototype.getDeletedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.DeletedWorkspace.prototype.setDeletedAt = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.DeletedWorkspace.prototype.clearDeletedAt = function() {
+  this.setDeletedAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.DeletedWorkspace.prototype.hasDeletedAt = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional google.protobuf.Timestamp purge_at = 4;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.DeletedWorkspace.prototype.getPurgeAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 4));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.DeletedWorkspace.prototype.setPurgeAt = function(value) {
+  jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.DeletedWorkspace.prototype.clearPurgeAt = function() {
+  this.setPurgeAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.DeletedWorkspace.prototype.hasPurgeAt = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * map<string, string> metadata = 5;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.DeletedWorkspace.prototype.getMetadataMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 5, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ */
+proto.wsman.DeletedWorkspace.prototype.clearMetadataMap = function() {
+  this.getMetadataMap().clear();
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.WorkspaceTemplateSpec.repeatedFields_ = [4];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceTemplateSpec.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceTemplateSpec} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceTemplateSpec.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    ideImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    type: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    envvarsList: jspb.Message.toObjectList(msg.getEnvvarsList(),
+    proto.wsman.EnvironmentVariable.toObject, includeInstance),
+    tasks: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    timeout: jspb.Message.getFieldWithDefault(msg, 6, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceTemplateSpec}
+ */
+proto.wsman.WorkspaceTemplateSpec.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceTemplateSpec;
+  return proto.wsman.WorkspaceTemplateSpec.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceTemplateSpec} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceTemplateSpec}
+ */
+proto.wsman.WorkspaceTemplateSpec.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceImage(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setIdeImage(value);
+      break;
+    case 3:
+      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
+      msg.setType(value);
+      break;
+    case 4:
+      var value = new proto.wsman.EnvironmentVariable;
+      reader.readMessage(value,proto.wsman.EnvironmentVariable.deserializeBinaryFromReader);
+      msg.addEnvvars(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTasks(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTimeout(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceTemplateSpec.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceTemplateSpec} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceTemplateSpec.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceImage();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getIdeImage();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getType();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
+  f = message.getEnvvarsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      4,
+      f,
+      proto.wsman.EnvironmentVariable.serializeBinaryToWriter
+    );
+  }
+  f = message.getTasks();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getTimeout();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_image = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.getWorkspaceImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplateSpec.prototype.setWorkspaceImage = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string ide_image = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.getIdeImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplateSpec.prototype.setIdeImage = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional WorkspaceType type = 3;
+ * @return {!proto.wsman.WorkspaceType}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.getType = function() {
+  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {!proto.wsman.WorkspaceType} value */
+proto.wsman.WorkspaceTemplateSpec.prototype.setType = function(value) {
+  jspb.Message.setProto3EnumField(this, 3, value);
+};
+
+
+/**
+ * repeated EnvironmentVariable envvars = 4;
+ * @return {!Array<!proto.wsman.EnvironmentVariable>}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.getEnvvarsList = function() {
+  return /** @type{!Array<!proto.wsman.EnvironmentVariable>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.EnvironmentVariable, 4));
+};
+
+
+/** @param {!Array<!proto.wsman.EnvironmentVariable>} value */
+proto.wsman.WorkspaceTemplateSpec.prototype.setEnvvarsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 4, value);
+};
+
+
+/**
+ * @param {!proto.wsman.EnvironmentVariable=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.EnvironmentVariable}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.addEnvvars = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 4, opt_value, proto.wsman.EnvironmentVariable, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.clearEnvvarsList = function() {
+  this.setEnvvarsList([]);
+};
+
+
+/**
+ * optional string tasks = 5;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.getTasks = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplateSpec.prototype.setTasks = function(value) {
+  jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * optional string timeout = 6;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplateSpec.prototype.getTimeout = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplateSpec.prototype.setTimeout = function(value) {
+  jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceTemplate.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceTemplate.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceTemplate} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceTemplate.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    name: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    teamId: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    version: jspb.Message.getFieldWithDefault(msg, 5, 0),
+    latestVersion: jspb.Message.getFieldWithDefault(msg, 6, 0),
+    spec: (f = msg.getSpec()) && proto.wsman.WorkspaceTemplateSpec.toObject(includeInstance, f),
+    created: (f = msg.getCreated()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceTemplate}
+ */
+proto.wsman.WorkspaceTemplate.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceTemplate;
+  return proto.wsman.WorkspaceTemplate.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceTemplate} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceTemplate}
+ */
+proto.wsman.WorkspaceTemplate.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTeamId(value);
+      break;
+    case 5:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setVersion(value);
+      break;
+    case 6:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setLatestVersion(value);
+      break;
+    case 7:
+      var value = new proto.wsman.WorkspaceTemplateSpec;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplateSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
+      break;
+    case 8:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setCreated(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceTemplate.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceTemplate.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceTemplate} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceTemplate.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getTeamId();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getVersion();
+  if (f !== 0) {
+    writer.writeInt64(
+      5,
+      f
+    );
+  }
+  f = message.getLatestVersion();
+  if (f !== 0) {
+    writer.writeInt64(
+      6,
+      f
+    );
+  }
+  f = message.getSpec();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      proto.wsman.WorkspaceTemplateSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getCreated();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplate.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string name = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplate.prototype.setName = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string owner = 3;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplate.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string team_id = 4;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getTeamId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplate.prototype.setTeamId = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional int64 version = 5;
+ * @return {number}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getVersion = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.WorkspaceTemplate.prototype.setVersion = function(value) {
+  jspb.Message.setProto3IntField(this, 5, value);
+};
+
+
+/**
+ * optional int64 latest_version = 6;
+ * @return {number}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getLatestVersion = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.WorkspaceTemplate.prototype.setLatestVersion = function(value) {
+  jspb.Message.setProto3IntField(this, 6, value);
+};
+
+
+/**
+ * optional WorkspaceTemplateSpec spec = 7;
+ * @return {?proto.wsman.WorkspaceTemplateSpec}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.WorkspaceTemplateSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceTemplateSpec, 7));
+};
+
+
+/** @param {?proto.wsman.WorkspaceTemplateSpec|undefined} value */
+proto.wsman.WorkspaceTemplate.prototype.setSpec = function(value) {
+  jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceTemplate.prototype.clearSpec = function() {
+  this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceTemplate.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional google.protobuf.Timestamp created = 8;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.WorkspaceTemplate.prototype.getCreated = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 8));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.WorkspaceTemplate.prototype.setCreated = function(value) {
+  jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceTemplate.prototype.clearCreated = function() {
+  this.setCreated(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceTemplate.prototype.hasCreated = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceTemplateRef.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceTemplateRef.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceTemplateRef} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceTemplateRef.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    version: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    teamId: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceTemplateRef}
+ */
+proto.wsman.WorkspaceTemplateRef.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceTemplateRef;
+  return proto.wsman.WorkspaceTemplateRef.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceTemplateRef} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceTemplateRef}
+ */
+proto.wsman.WorkspaceTemplateRef.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setVersion(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTeamId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceTemplateRef.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceTemplateRef.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceTemplateRef} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceTemplateRef.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getVersion();
+  if (f !== 0) {
+    writer.writeInt64(
+      2,
+      f
+    );
+  }
+  f = message.getTeamId();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplateRef.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplateRef.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional int64 version = 2;
+ * @return {number}
+ */
+proto.wsman.WorkspaceTemplateRef.prototype.getVersion = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.WorkspaceTemplateRef.prototype.setVersion = function(value) {
+  jspb.Message.setProto3IntField(this, 2, value);
+};
+
+
+/**
+ * optional string team_id = 3;
+ * @return {string}
+ */
+proto.wsman.WorkspaceTemplateRef.prototype.getTeamId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceTemplateRef.prototype.setTeamId = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.CreateWorkspaceTemplateRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.CreateWorkspaceTemplateRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    name: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    teamId: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    spec: (f = msg.getSpec()) && proto.wsman.WorkspaceTemplateSpec.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.CreateWorkspaceTemplateRequest}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.CreateWorkspaceTemplateRequest;
+  return proto.wsman.CreateWorkspaceTemplateRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.CreateWorkspaceTemplateRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.CreateWorkspaceTemplateRequest}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTeamId(value);
+      break;
+    case 4:
+      var value = new proto.wsman.WorkspaceTemplateSpec;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplateSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.CreateWorkspaceTemplateRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.CreateWorkspaceTemplateRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getTeamId();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getSpec();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.wsman.WorkspaceTemplateSpec.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string name = 1;
+ * @return {string}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.setName = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string owner = 2;
+ * @return {string}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string team_id = 3;
+ * @return {string}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.getTeamId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.setTeamId = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional WorkspaceTemplateSpec spec = 4;
+ * @return {?proto.wsman.WorkspaceTemplateSpec}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.WorkspaceTemplateSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceTemplateSpec, 4));
+};
+
+
+/** @param {?proto.wsman.WorkspaceTemplateSpec|undefined} value */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.setSpec = function(value) {
+  jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.clearSpec = function() {
+  this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.CreateWorkspaceTemplateRequest.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.CreateWorkspaceTemplateResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.CreateWorkspaceTemplateResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    template: (f = msg.getTemplate()) && proto.wsman.WorkspaceTemplate.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.CreateWorkspaceTemplateResponse}
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.CreateWorkspaceTemplateResponse;
+  return proto.wsman.CreateWorkspaceTemplateResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.CreateWorkspaceTemplateResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.CreateWorkspaceTemplateResponse}
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceTemplate;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplate.deserializeBinaryFromReader);
+      msg.setTemplate(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.CreateWorkspaceTemplateResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.CreateWorkspaceTemplateResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getTemplate();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceTemplate.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional WorkspaceTemplate template = 1;
+ * @return {?proto.wsman.WorkspaceTemplate}
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.prototype.getTemplate = function() {
+  return /** @type{?proto.wsman.WorkspaceTemplate} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceTemplate, 1));
+};
+
+
+/** @param {?proto.wsman.WorkspaceTemplate|undefined} value */
+proto.wsman.CreateWorkspaceTemplateResponse.prototype.setTemplate = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.prototype.clearTemplate = function() {
+  this.setTemplate(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.CreateWorkspaceTemplateResponse.prototype.hasTemplate = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.UpdateWorkspaceTemplateRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.UpdateWorkspaceTemplateRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    spec: (f = msg.getSpec()) && proto.wsman.WorkspaceTemplateSpec.toObject(includeInstance, f),
+    latestVersion: jspb.Message.getFieldWithDefault(msg, 4, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.UpdateWorkspaceTemplateRequest}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.UpdateWorkspaceTemplateRequest;
+  return proto.wsman.UpdateWorkspaceTemplateRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.UpdateWorkspaceTemplateRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.UpdateWorkspaceTemplateRequest}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    case 3:
+      var value = new proto.wsman.WorkspaceTemplateSpec;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplateSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
+      break;
+    case 4:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setLatestVersion(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.UpdateWorkspaceTemplateRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.UpdateWorkspaceTemplateRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getSpec();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.wsman.WorkspaceTemplateSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getLatestVersion();
+  if (f !== 0) {
+    writer.writeInt64(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string owner = 2;
+ * @return {string}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional WorkspaceTemplateSpec spec = 3;
+ * @return {?proto.wsman.WorkspaceTemplateSpec}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.WorkspaceTemplateSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceTemplateSpec, 3));
+};
+
+
+/** @param {?proto.wsman.WorkspaceTemplateSpec|undefined} value */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.setSpec = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.clearSpec = function() {
+  this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional int64 latest_version = 4;
+ * @return {number}
+ */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.getLatestVersion = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.UpdateWorkspaceTemplateRequest.prototype.setLatestVersion = function(value) {
+  jspb.Message.setProto3IntField(this, 4, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.UpdateWorkspaceTemplateResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.UpdateWorkspaceTemplateResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    template: (f = msg.getTemplate()) && proto.wsman.WorkspaceTemplate.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.UpdateWorkspaceTemplateResponse}
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.UpdateWorkspaceTemplateResponse;
+  return proto.wsman.UpdateWorkspaceTemplateResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.UpdateWorkspaceTemplateResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.UpdateWorkspaceTemplateResponse}
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceTemplate;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplate.deserializeBinaryFromReader);
+      msg.setTemplate(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.UpdateWorkspaceTemplateResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.UpdateWorkspaceTemplateResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getTemplate();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceTemplate.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional WorkspaceTemplate template = 1;
+ * @return {?proto.wsman.WorkspaceTemplate}
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.prototype.getTemplate = function() {
+  return /** @type{?proto.wsman.WorkspaceTemplate} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceTemplate, 1));
+};
+
+
+/** @param {?proto.wsman.WorkspaceTemplate|undefined} value */
+proto.wsman.UpdateWorkspaceTemplateResponse.prototype.setTemplate = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.prototype.clearTemplate = function() {
+  this.setTemplate(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.UpdateWorkspaceTemplateResponse.prototype.hasTemplate = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GetWorkspaceTemplateRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GetWorkspaceTemplateRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspaceTemplateRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    version: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    user: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    teamId: jspb.Message.getFieldWithDefault(msg, 4, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GetWorkspaceTemplateRequest}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GetWorkspaceTemplateRequest;
+  return proto.wsman.GetWorkspaceTemplateRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GetWorkspaceTemplateRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GetWorkspaceTemplateRequest}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setVersion(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUser(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTeamId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GetWorkspaceTemplateRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GetWorkspaceTemplateRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspaceTemplateRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getVersion();
+  if (f !== 0) {
+    writer.writeInt64(
+      2,
+      f
+    );
+  }
+  f = message.getUser();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getTeamId();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional int64 version = 2;
+ * @return {number}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.getVersion = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.setVersion = function(value) {
+  jspb.Message.setProto3IntField(this, 2, value);
+};
+
+
+/**
+ * optional string user = 3;
+ * @return {string}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.getUser = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.setUser = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string team_id = 4;
+ * @return {string}
+ */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.getTeamId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.GetWorkspaceTemplateRequest.prototype.setTeamId = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.GetWorkspaceTemplateResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.GetWorkspaceTemplateResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.GetWorkspaceTemplateResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspaceTemplateResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    template: (f = msg.getTemplate()) && proto.wsman.WorkspaceTemplate.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.GetWorkspaceTemplateResponse}
+ */
+proto.wsman.GetWorkspaceTemplateResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.GetWorkspaceTemplateResponse;
+  return proto.wsman.GetWorkspaceTemplateResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.GetWorkspaceTemplateResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.GetWorkspaceTemplateResponse}
+ */
+proto.wsman.GetWorkspaceTemplateResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceTemplate;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplate.deserializeBinaryFromReader);
+      msg.setTemplate(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.GetWorkspaceTemplateResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.GetWorkspaceTemplateResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.GetWorkspaceTemplateResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.GetWorkspaceTemplateResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getTemplate();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceTemplate.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional WorkspaceTemplate template = 1;
+ * @return {?proto.wsman.WorkspaceTemplate}
+ */
+proto.wsman.GetWorkspaceTemplateResponse.prototype.getTemplate = function() {
+  return /** @type{?proto.wsman.WorkspaceTemplate} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceTemplate, 1));
+};
+
+
+/** @param {?proto.wsman.WorkspaceTemplate|undefined} value */
+proto.wsman.GetWorkspaceTemplateResponse.prototype.setTemplate = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.GetWorkspaceTemplateResponse.prototype.clearTemplate = function() {
+  this.setTemplate(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.GetWorkspaceTemplateResponse.prototype.hasTemplate = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ListWorkspaceTemplatesRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ListWorkspaceTemplatesRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    user: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    teamId: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ListWorkspaceTemplatesRequest}
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ListWorkspaceTemplatesRequest;
+  return proto.wsman.ListWorkspaceTemplatesRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ListWorkspaceTemplatesRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ListWorkspaceTemplatesRequest}
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUser(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTeamId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ListWorkspaceTemplatesRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ListWorkspaceTemplatesRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUser();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getTeamId();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string user = 1;
+ * @return {string}
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.prototype.getUser = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ListWorkspaceTemplatesRequest.prototype.setUser = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string team_id = 2;
+ * @return {string}
+ */
+proto.wsman.ListWorkspaceTemplatesRequest.prototype.getTeamId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ListWorkspaceTemplatesRequest.prototype.setTeamId = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ListWorkspaceTemplatesResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ListWorkspaceTemplatesResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    templatesList: jspb.Message.toObjectList(msg.getTemplatesList(),
+    proto.wsman.WorkspaceTemplate.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ListWorkspaceTemplatesResponse}
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ListWorkspaceTemplatesResponse;
+  return proto.wsman.ListWorkspaceTemplatesResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ListWorkspaceTemplatesResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ListWorkspaceTemplatesResponse}
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new proto.wsman.WorkspaceTemplate;
+      reader.readMessage(value,proto.wsman.WorkspaceTemplate.deserializeBinaryFromReader);
+      msg.addTemplates(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ListWorkspaceTemplatesResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ListWorkspaceTemplatesResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getTemplatesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      proto.wsman.WorkspaceTemplate.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * repeated WorkspaceTemplate templates = 1;
+ * @return {!Array<!proto.wsman.WorkspaceTemplate>}
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.prototype.getTemplatesList = function() {
+  return /** @type{!Array<!proto.wsman.WorkspaceTemplate>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceTemplate, 1));
+};
+
+
+/** @param {!Array<!proto.wsman.WorkspaceTemplate>} value */
+proto.wsman.ListWorkspaceTemplatesResponse.prototype.setTemplatesList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceTemplate=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceTemplate}
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.prototype.addTemplates = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.WorkspaceTemplate, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.ListWorkspaceTemplatesResponse.prototype.clearTemplatesList = function() {
+  this.setTemplatesList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DeleteWorkspaceTemplateRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DeleteWorkspaceTemplateRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DeleteWorkspaceTemplateRequest}
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DeleteWorkspaceTemplateRequest;
+  return proto.wsman.DeleteWorkspaceTemplateRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DeleteWorkspaceTemplateRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DeleteWorkspaceTemplateRequest}
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DeleteWorkspaceTemplateRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DeleteWorkspaceTemplateRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DeleteWorkspaceTemplateRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string owner = 2;
+ * @return {string}
+ */
+proto.wsman.DeleteWorkspaceTemplateRequest.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.DeleteWorkspaceTemplateRequest.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.DeleteWorkspaceTemplateResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DeleteWorkspaceTemplateResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.DeleteWorkspaceTemplateResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceTemplateResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.DeleteWorkspaceTemplateResponse}
+ */
+proto.wsman.DeleteWorkspaceTemplateResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.DeleteWorkspaceTemplateResponse;
+  return proto.wsman.DeleteWorkspaceTemplateResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.DeleteWorkspaceTemplateResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.DeleteWorkspaceTemplateResponse}
+ */
+proto.wsman.DeleteWorkspaceTemplateResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.DeleteWorkspaceTemplateResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.DeleteWorkspaceTemplateResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.DeleteWorkspaceTemplateResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.DeleteWorkspaceTemplateResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.MaintenanceStatus.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.MaintenanceStatus.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.MaintenanceStatus} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.MaintenanceStatus.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    enabled: jspb.Message.getFieldWithDefault(msg, 1, false),
+    message: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    startsAt: (f = msg.getStartsAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    endsAt: (f = msg.getEndsAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.MaintenanceStatus}
+ */
+proto.wsman.MaintenanceStatus.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.MaintenanceStatus;
+  return proto.wsman.MaintenanceStatus.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.MaintenanceStatus} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.MaintenanceStatus}
+ */
+proto.wsman.MaintenanceStatus.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setEnabled(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMessage(value);
+      break;
+    case 3:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setStartsAt(value);
+      break;
+    case 4:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setEndsAt(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.MaintenanceStatus.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.MaintenanceStatus.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.MaintenanceStatus} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.MaintenanceStatus.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getEnabled();
+  if (f) {
+    writer.writeBool(
+      1,
+      f
+    );
+  }
+  f = message.getMessage();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getStartsAt();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getEndsAt();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional bool enabled = 1;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.MaintenanceStatus.prototype.getEnabled = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 1, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.MaintenanceStatus.prototype.setEnabled = function(value) {
+  jspb.Message.setProto3BooleanField(this, 1, value);
+};
+
+
+/**
+ * optional string message = 2;
+ * @return {string}
+ */
+proto.wsman.MaintenanceStatus.prototype.getMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.MaintenanceStatus.prototype.setMessage = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp starts_at = 3;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.MaintenanceStatus.prototype.getStartsAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.MaintenanceStatus.prototype.setStartsAt = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.MaintenanceStatus.prototype.clearStartsAt = function() {
+  this.setStartsAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.MaintenanceStatus.prototype.hasStartsAt = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional google.protobuf.Timestamp ends_at = 4;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.MaintenanceStatus.prototype.getEndsAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 4));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.MaintenanceStatus.prototype.setEndsAt = function(value) {
+  jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.MaintenanceStatus.prototype.clearEndsAt = function() {
+  this.setEndsAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.MaintenanceStatus.prototype.hasEndsAt = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.WorkspaceStatus.repeatedFields_ = [11];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceStatus.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceStatus.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceStatus} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceStatus.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
+    spec: (f = msg.getSpec()) && proto.wsman.WorkspaceSpec.toObject(includeInstance, f),
+    phase: jspb.Message.getFieldWithDefault(msg, 4, 0),
+    conditions: (f = msg.getConditions()) && proto.wsman.WorkspaceConditions.toObject(includeInstance, f),
+    message: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    repo: (f = msg.getRepo()) && content$service$api_initializer_pb.GitStatus.toObject(includeInstance, f),
+    runtime: (f = msg.getRuntime()) && proto.wsman.WorkspaceRuntimeInfo.toObject(includeInstance, f),
+    auth: (f = msg.getAuth()) && proto.wsman.WorkspaceAuthentication.toObject(includeInstance, f),
+    generation: jspb.Message.getFieldWithDefault(msg, 10, 0),
+    initContainersList: jspb.Message.toObjectList(msg.getInitContainersList(),
+    proto.wsman.InitContainerStatus.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceStatus}
+ */
+proto.wsman.WorkspaceStatus.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceStatus;
+  return proto.wsman.WorkspaceStatus.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceStatus} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceStatus}
+ */
+proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = new proto.wsman.WorkspaceMetadata;
+      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
+      msg.setMetadata(value);
+      break;
+    case 3:
+      var value = new proto.wsman.WorkspaceSpec;
+      reader.readMessage(value,proto.wsman.WorkspaceSpec.deserializeBinaryFromReader);
+      msg.setSpec(value);
+      break;
+    case 4:
+      var value = /** @type {!proto.wsman.WorkspacePhase} */ (reader.readEnum());
+      msg.setPhase(value);
+      break;
+    case 5:
+      var value = new proto.wsman.WorkspaceConditions;
+      reader.readMessage(value,proto.wsman.WorkspaceConditions.deserializeBinaryFromReader);
+      msg.setConditions(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMessage(value);
+      break;
+    case 7:
+      var value = new content$service$api_initializer_pb.GitStatus;
+      reader.readMessage(value,content$service$api_initializer_pb.GitStatus.deserializeBinaryFromReader);
+      msg.setRepo(value);
+      break;
+    case 8:
+      var value = new proto.wsman.WorkspaceRuntimeInfo;
+      reader.readMessage(value,proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader);
+      msg.setRuntime(value);
+      break;
+    case 9:
+      var value = new proto.wsman.WorkspaceAuthentication;
+      reader.readMessage(value,proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader);
+      msg.setAuth(value);
+      break;
+    case 10:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setGeneration(value);
+      break;
+    case 11:
+      var value = new proto.wsman.InitContainerStatus;
+      reader.readMessage(value,proto.wsman.InitContainerStatus.deserializeBinaryFromReader);
+      msg.addInitContainers(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceStatus.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceStatus.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceStatus} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getMetadata();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
+    );
+  }
+  f = message.getSpec();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.wsman.WorkspaceSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getPhase();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      4,
+      f
+    );
+  }
+  f = message.getConditions();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      proto.wsman.WorkspaceConditions.serializeBinaryToWriter
+    );
+  }
+  f = message.getMessage();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+  f = message.getRepo();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      content$service$api_initializer_pb.GitStatus.serializeBinaryToWriter
+    );
+  }
+  f = message.getRuntime();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter
+    );
+  }
+  f = message.getAuth();
+  if (f != null) {
+    writer.writeMessage(
+      9,
+      f,
+      proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter
+    );
+  }
+  f = message.getGeneration();
+  if (f !== 0) {
+    writer.writeUint64(
+      10,
+      f
+    );
+  }
+  f = message.getInitContainersList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      11,
+      f,
+      proto.wsman.InitContainerStatus.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceStatus.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceStatus.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional WorkspaceMetadata metadata = 2;
+ * @return {?proto.wsman.WorkspaceMetadata}
+ */
+proto.wsman.WorkspaceStatus.prototype.getMetadata = function() {
+  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 2));
+};
+
+
+/** @param {?proto.wsman.WorkspaceMetadata|undefined} value */
+proto.wsman.WorkspaceStatus.prototype.setMetadata = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceStatus.prototype.clearMetadata = function() {
+  this.setMetadata(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasMetadata = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional WorkspaceSpec spec = 3;
+ * @return {?proto.wsman.WorkspaceSpec}
+ */
+proto.wsman.WorkspaceStatus.prototype.getSpec = function() {
+  return /** @type{?proto.wsman.WorkspaceSpec} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceSpec, 3));
+};
+
+
+/** @param {?proto.wsman.WorkspaceSpec|undefined} value */
+proto.wsman.WorkspaceStatus.prototype.setSpec = function(value) {
+  jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceStatus.prototype.clearSpec = function() {
+  this.setSpec(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasSpec = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional WorkspacePhase phase = 4;
+ * @return {!proto.wsman.WorkspacePhase}
+ */
+proto.wsman.WorkspaceStatus.prototype.getPhase = function() {
+  return /** @type {!proto.wsman.WorkspacePhase} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/** @param {!proto.wsman.WorkspacePhase} value */
+proto.wsman.WorkspaceStatus.prototype.setPhase = function(value) {
+  jspb.Message.setProto3EnumField(this, 4, value);
+};
+
+
+/**
+ * optional WorkspaceConditions conditions = 5;
+ * @return {?proto.wsman.WorkspaceConditions}
+ */
+proto.wsman.WorkspaceStatus.prototype.getConditions = function() {
+  return /** @type{?proto.wsman.WorkspaceConditions} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceConditions, 5));
+};
+
+
+/** @param {?proto.wsman.WorkspaceConditions|undefined} value */
+proto.wsman.WorkspaceStatus.prototype.setConditions = function(value) {
+  jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceStatus.prototype.clearConditions = function() {
+  this.setConditions(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasConditions = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * optional string message = 6;
+ * @return {string}
+ */
+proto.wsman.WorkspaceStatus.prototype.getMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceStatus.prototype.setMessage = function(value) {
+  jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * optional contentservice.GitStatus repo = 7;
+ * @return {?proto.contentservice.GitStatus}
+ */
+proto.wsman.WorkspaceStatus.prototype.getRepo = function() {
+  return /** @type{?proto.contentservice.GitStatus} */ (
+    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.GitStatus, 7));
+};
+
+
+/** @param {?proto.contentservice.GitStatus|undefined} value */
+proto.wsman.WorkspaceStatus.prototype.setRepo = function(value) {
+  jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceStatus.prototype.clearRepo = function() {
+  this.setRepo(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasRepo = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional WorkspaceRuntimeInfo runtime = 8;
+ * @return {?proto.wsman.WorkspaceRuntimeInfo}
+ */
+proto.wsman.WorkspaceStatus.prototype.getRuntime = function() {
+  return /** @type{?proto.wsman.WorkspaceRuntimeInfo} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceRuntimeInfo, 8));
+};
+
+
+/** @param {?proto.wsman.WorkspaceRuntimeInfo|undefined} value */
+proto.wsman.WorkspaceStatus.prototype.setRuntime = function(value) {
+  jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceStatus.prototype.clearRuntime = function() {
+  this.setRuntime(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasRuntime = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * optional WorkspaceAuthentication auth = 9;
+ * @return {?proto.wsman.WorkspaceAuthentication}
+ */
+proto.wsman.WorkspaceStatus.prototype.getAuth = function() {
+  return /** @type{?proto.wsman.WorkspaceAuthentication} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceAuthentication, 9));
+};
+
+
+/** @param {?proto.wsman.WorkspaceAuthentication|undefined} value */
+proto.wsman.WorkspaceStatus.prototype.setAuth = function(value) {
+  jspb.Message.setWrapperField(this, 9, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceStatus.prototype.clearAuth = function() {
+  this.setAuth(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceStatus.prototype.hasAuth = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
+/**
+ * optional uint64 generation = 10;
+ * @return {number}
+ */
+proto.wsman.WorkspaceStatus.prototype.getGeneration = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 10, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.WorkspaceStatus.prototype.setGeneration = function(value) {
+  jspb.Message.setProto3IntField(this, 10, value);
+};
+
+
+/**
+ * repeated InitContainerStatus init_containers = 11;
+ * @return {!Array<!proto.wsman.InitContainerStatus>}
+ */
+proto.wsman.WorkspaceStatus.prototype.getInitContainersList = function() {
+  return /** @type{!Array<!proto.wsman.InitContainerStatus>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.InitContainerStatus, 11));
+};
+
+
+/** @param {!Array<!proto.wsman.InitContainerStatus>} value */
+proto.wsman.WorkspaceStatus.prototype.setInitContainersList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 11, value);
+};
+
+
+/**
+ * @param {!proto.wsman.InitContainerStatus=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.InitContainerStatus}
+ */
+proto.wsman.WorkspaceStatus.prototype.addInitContainers = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 11, opt_value, proto.wsman.InitContainerStatus, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.WorkspaceStatus.prototype.clearInitContainersList = function() {
+  this.setInitContainersList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.InitContainerStatus.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.InitContainerStatus.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.InitContainerStatus} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.InitContainerStatus.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    name: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    state: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    exitCode: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    message: jspb.Message.getFieldWithDefault(msg, 4, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.InitContainerStatus}
+ */
+proto.wsman.InitContainerStatus.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.InitContainerStatus;
+  return proto.wsman.InitContainerStatus.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.InitContainerStatus} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.InitContainerStatus}
+ */
+proto.wsman.InitContainerStatus.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 2:
+      var value = /** @type {!proto.wsman.InitContainerState} */ (reader.readEnum());
+      msg.setState(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setExitCode(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMessage(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.InitContainerStatus.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.InitContainerStatus.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.InitContainerStatus} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.InitContainerStatus.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getState();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+  f = message.getExitCode();
+  if (f !== 0) {
+    writer.writeInt32(
+      3,
+      f
+    );
+  }
+  f = message.getMessage();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string name = 1;
+ * @return {string}
+ */
+proto.wsman.InitContainerStatus.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.InitContainerStatus.prototype.setName = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional InitContainerState state = 2;
+ * @return {!proto.wsman.InitContainerState}
+ */
+proto.wsman.InitContainerStatus.prototype.getState = function() {
+  return /** @type {!proto.wsman.InitContainerState} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {!proto.wsman.InitContainerState} value */
+proto.wsman.InitContainerStatus.prototype.setState = function(value) {
+  jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+/**
+ * optional int32 exit_code = 3;
+ * @return {number}
+ */
+proto.wsman.InitContainerStatus.prototype.getExitCode = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.InitContainerStatus.prototype.setExitCode = function(value) {
+  jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+/**
+ * optional string message = 4;
+ * @return {string}
+ */
+proto.wsman.InitContainerStatus.prototype.getMessage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.InitContainerStatus.prototype.setMessage = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.WorkspaceSpec.repeatedFields_ = [5];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceSpec.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceSpec} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceSpec.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    workspaceImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    ideImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    headless: jspb.Message.getFieldWithDefault(msg, 3, false),
+    url: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    exposedPortsList: jspb.Message.toObjectList(msg.getExposedPortsList(),
+    proto.wsman.PortSpec.toObject, includeInstance),
+    type: jspb.Message.getFieldWithDefault(msg, 6, 0),
+    timeout: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    experimentsMap: (f = msg.getExperimentsMap()) ? f.toObject(includeInstance, undefined) : [],
+    scheduledStop: (f = msg.getScheduledStop()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    idePin: (f = msg.getIdePin()) && proto.wsman.IDEPin.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceSpec}
+ */
+proto.wsman.WorkspaceSpec.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceSpec;
+  return proto.wsman.WorkspaceSpec.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceSpec} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceSpec}
+ */
+proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setWorkspaceImage(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setIdeImage(value);
+      break;
+    case 3:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setHeadless(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    case 5:
+      var value = new proto.wsman.PortSpec;
+      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
+      msg.addExposedPorts(value);
+      break;
+    case 6:
+      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
+      msg.setType(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTimeout(value);
+      break;
+    case 8:
+      var value = msg.getExperimentsMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
+      break;
+    case 9:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setScheduledStop(value);
+      break;
+    case 10:
+      var value = new proto.wsman.IDEPin;
+      reader.readMessage(value,proto.wsman.IDEPin.deserializeBinaryFromReader);
+      msg.setIdePin(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceSpec.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceSpec.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceSpec} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceSpec.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getWorkspaceImage();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getIdeImage();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getHeadless();
+  if (f) {
+    writer.writeBool(
+      3,
+      f
+    );
+  }
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getExposedPortsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      5,
+      f,
+      proto.wsman.PortSpec.serializeBinaryToWriter
+    );
+  }
+  f = message.getType();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      6,
+      f
+    );
+  }
+  f = message.getTimeout();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+  f = message.getExperimentsMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(8, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+  f = message.getScheduledStop();
+  if (f != null) {
+    writer.writeMessage(
+      9,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getIdePin();
+  if (f != null) {
+    writer.writeMessage(
+      10,
+      f,
+      proto.wsman.IDEPin.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string workspace_image = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getWorkspaceImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceSpec.prototype.setWorkspaceImage = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string ide_image = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getIdeImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceSpec.prototype.setIdeImage = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional bool headless = 3;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceSpec.prototype.getHeadless = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 3, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.WorkspaceSpec.prototype.setHeadless = function(value) {
+  jspb.Message.setProto3BooleanField(this, 3, value);
+};
+
+
+/**
+ * optional string url = 4;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceSpec.prototype.setUrl = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * repeated PortSpec exposed_ports = 5;
+ * @return {!Array<!proto.wsman.PortSpec>}
+ */
+proto.wsman.WorkspaceSpec.prototype.getExposedPortsList = function() {
+  return /** @type{!Array<!proto.wsman.PortSpec>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PortSpec, 5));
+};
+
+
+/** @param {!Array<!proto.wsman.PortSpec>} value */
+proto.wsman.WorkspaceSpec.prototype.setExposedPortsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 5, value);
+};
+
+
+/**
+ * @param {!proto.wsman.PortSpec=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PortSpec}
+ */
+proto.wsman.WorkspaceSpec.prototype.addExposedPorts = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.wsman.PortSpec, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.WorkspaceSpec.prototype.clearExposedPortsList = function() {
+  this.setExposedPortsList([]);
+};
+
+
+/**
+ * optional WorkspaceType type = 6;
+ * @return {!proto.wsman.WorkspaceType}
+ */
+proto.wsman.WorkspaceSpec.prototype.getType = function() {
+  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+};
+
+
+/** @param {!proto.wsman.WorkspaceType} value */
+proto.wsman.WorkspaceSpec.prototype.setType = function(value) {
+  jspb.Message.setProto3EnumField(this, 6, value);
+};
+
+
+/**
+ * optional string timeout = 7;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSpec.prototype.getTimeout = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceSpec.prototype.setTimeout = function(value) {
+  jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+/**
+ * map<string, string> experiments = 8;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.WorkspaceSpec.prototype.getExperimentsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 8, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ */
+proto.wsman.WorkspaceSpec.prototype.clearExperimentsMap = function() {
+  this.getExperimentsMap().clear();
+};
+
+
+/**
+ * optional google.protobuf.Timestamp scheduled_stop = 9;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.WorkspaceSpec.prototype.getScheduledStop = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 9));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.WorkspaceSpec.prototype.setScheduledStop = function(value) {
+  jspb.Message.setWrapperField(this, 9, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceSpec.prototype.clearScheduledStop = function() {
+  this.setScheduledStop(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceSpec.prototype.hasScheduledStop = function() {
+  return jspb.Message.getField(this, 9) != null;
+};
+
+
+/**
+ * optional IDEPin ide_pin = 10;
+ * @return {?proto.wsman.IDEPin}
+ */
+proto.wsman.WorkspaceSpec.prototype.getIdePin = function() {
+  return /** @type{?proto.wsman.IDEPin} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.IDEPin, 10));
+};
+
+
+/** @param {?proto.wsman.IDEPin|undefined} value */
+proto.wsman.WorkspaceSpec.prototype.setIdePin = function(value) {
+  jspb.Message.setWrapperField(this, 10, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.WorkspaceSpec.prototype.clearIdePin = function() {
+  this.setIdePin(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.WorkspaceSpec.prototype.hasIdePin = function() {
+  return jspb.Message.getField(this, 10) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.IDEPin.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.IDEPin.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.IDEPin} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.IDEPin.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    pinnedImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    defaultImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    supervisorVersion: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.IDEPin}
+ */
+proto.wsman.IDEPin.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.IDEPin;
+  return proto.wsman.IDEPin.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.IDEPin} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.IDEPin}
+ */
+proto.wsman.IDEPin.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPinnedImage(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDefaultImage(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSupervisorVersion(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.IDEPin.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.IDEPin.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.IDEPin} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.IDEPin.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getPinnedImage();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getDefaultImage();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getSupervisorVersion();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string pinned_image = 1;
+ * @return {string}
+ */
+proto.wsman.IDEPin.prototype.getPinnedImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.IDEPin.prototype.setPinnedImage = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string default_image = 2;
+ * @return {string}
+ */
+proto.wsman.IDEPin.prototype.getDefaultImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.IDEPin.prototype.setDefaultImage = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string supervisor_version = 3;
+ * @return {string}
+ */
+proto.wsman.IDEPin.prototype.getSupervisorVersion = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.IDEPin.prototype.setSupervisorVersion = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
@@ -824,8 +16591,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.GetWorkspacesResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.GetWorkspacesResponse.toObject(opt_includeInstance, this);
+proto.wsman.ResetIDEPinRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ResetIDEPinRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -834,14 +16601,13 @@ proto.wsman.GetWorkspacesResponse.prototype.toObject = function(opt_includeInsta
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.GetWorkspacesResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.ResetIDEPinRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.GetWorkspacesResponse.toObject = function(includeInstance, msg) {
+proto.wsman.ResetIDEPinRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    statusList: jspb.Message.toObjectList(msg.getStatusList(),
-    proto.wsman.WorkspaceStatus.toObject, includeInstance)
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -855,23 +16621,23 @@ proto.wsman.GetWorkspacesResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.GetWorkspacesResponse}
+ * @return {!proto.wsman.ResetIDEPinRequest}
  */
-proto.wsman.GetWorkspacesResponse.deserializeBinary = function(bytes) {
+proto.wsman.ResetIDEPinRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.GetWorkspacesResponse;
-  return proto.wsman.GetWorkspacesResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.ResetIDEPinRequest;
+  return proto.wsman.ResetIDEPinRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.GetWorkspacesResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.ResetIDEPinRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.GetWorkspacesResponse}
+ * @return {!proto.wsman.ResetIDEPinRequest}
  */
-proto.wsman.GetWorkspacesResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.ResetIDEPinRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -879,9 +16645,8 @@ proto.wsman.GetWorkspacesResponse.deserializeBinaryFromReader = function(msg, re
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new proto.wsman.WorkspaceStatus;
-      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
-      msg.addStatus(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
       break;
     default:
       reader.skipField();
@@ -896,9 +16661,9 @@ proto.wsman.GetWorkspacesResponse.deserializeBinaryFromReader = function(msg, re
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.GetWorkspacesResponse.prototype.serializeBinary = function() {
+proto.wsman.ResetIDEPinRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.GetWorkspacesResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.ResetIDEPinRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -906,58 +16671,170 @@ proto.wsman.GetWorkspacesResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.GetWorkspacesResponse} message
+ * @param {!proto.wsman.ResetIDEPinRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.GetWorkspacesResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.ResetIDEPinRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getStatusList();
+  f = message.getId();
   if (f.length > 0) {
-    writer.writeRepeatedMessage(
+    writer.writeString(
       1,
-      f,
-      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+      f
     );
   }
 };
 
 
 /**
- * repeated WorkspaceStatus status = 1;
- * @return {!Array<!proto.wsman.WorkspaceStatus>}
+ * optional string id = 1;
+ * @return {string}
  */
-proto.wsman.GetWorkspacesResponse.prototype.getStatusList = function() {
-  return /** @type{!Array<!proto.wsman.WorkspaceStatus>} */ (
-    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceStatus, 1));
+proto.wsman.ResetIDEPinRequest.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
-/** @param {!Array<!proto.wsman.WorkspaceStatus>} value */
-proto.wsman.GetWorkspacesResponse.prototype.setStatusList = function(value) {
-  jspb.Message.setRepeatedWrapperField(this, 1, value);
+/** @param {string} value */
+proto.wsman.ResetIDEPinRequest.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {!proto.wsman.WorkspaceStatus=} opt_value
- * @param {number=} opt_index
- * @return {!proto.wsman.WorkspaceStatus}
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.GetWorkspacesResponse.prototype.addStatus = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.WorkspaceStatus, opt_index);
+proto.wsman.ResetIDEPinResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.ResetIDEPinResponse.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.ResetIDEPinResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.GetWorkspacesResponse.prototype.clearStatusList = function() {
-  this.setStatusList([]);
+proto.wsman.ResetIDEPinResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    ideImage: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.ResetIDEPinResponse}
+ */
+proto.wsman.ResetIDEPinResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.ResetIDEPinResponse;
+  return proto.wsman.ResetIDEPinResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.ResetIDEPinResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.ResetIDEPinResponse}
+ */
+proto.wsman.ResetIDEPinResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setIdeImage(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.ResetIDEPinResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.ResetIDEPinResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.ResetIDEPinResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.ResetIDEPinResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getIdeImage();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string ide_image = 1;
+ * @return {string}
+ */
+proto.wsman.ResetIDEPinResponse.prototype.getIdeImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.ResetIDEPinResponse.prototype.setIdeImage = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.BulkOperationFilter.repeatedFields_ = [1,2,3];
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -970,9 +16847,9 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
- */
-proto.wsman.StartWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.StartWorkspaceRequest.toObject(opt_includeInstance, this);
+ */
+proto.wsman.BulkOperationFilter.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BulkOperationFilter.toObject(opt_includeInstance, this);
 };
 
 
@@ -981,17 +16858,16 @@ proto.wsman.StartWorkspaceRequest.prototype.toObject = function(opt_includeInsta
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.StartWorkspaceRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.BulkOperationFilter} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceRequest.toObject = function(includeInstance, msg) {
+proto.wsman.BulkOperationFilter.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    servicePrefix: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
-    spec: (f = msg.getSpec()) && proto.wsman.StartWorkspaceSpec.toObject(includeInstance, f),
-    type: jspb.Message.getFieldWithDefault(msg, 6, 0)
+    ownersList: jspb.Message.getRepeatedField(msg, 1),
+    projectsList: jspb.Message.getRepeatedField(msg, 2),
+    typesList: jspb.Message.getRepeatedField(msg, 3),
+    startedBefore: (f = msg.getStartedBefore()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -1005,23 +16881,23 @@ proto.wsman.StartWorkspaceRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.StartWorkspaceRequest}
+ * @return {!proto.wsman.BulkOperationFilter}
  */
-proto.wsman.StartWorkspaceRequest.deserializeBinary = function(bytes) {
+proto.wsman.BulkOperationFilter.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.StartWorkspaceRequest;
-  return proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BulkOperationFilter;
+  return proto.wsman.BulkOperationFilter.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.StartWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.BulkOperationFilter} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.StartWorkspaceRequest}
+ * @return {!proto.wsman.BulkOperationFilter}
  */
-proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BulkOperationFilter.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1030,25 +16906,20 @@ proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader = function(msg, re
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
+      msg.addOwners(value);
       break;
     case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setServicePrefix(value);
+      msg.addProjects(value);
       break;
     case 3:
-      var value = new proto.wsman.WorkspaceMetadata;
-      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
-      msg.setMetadata(value);
+      var value = /** @type {!Array<!proto.wsman.WorkspaceType>} */ (reader.readPackedEnum());
+      msg.setTypesList(value);
       break;
     case 4:
-      var value = new proto.wsman.StartWorkspaceSpec;
-      reader.readMessage(value,proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader);
-      msg.setSpec(value);
-      break;
-    case 6:
-      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
-      msg.setType(value);
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setStartedBefore(value);
       break;
     default:
       reader.skipField();
@@ -1063,9 +16934,9 @@ proto.wsman.StartWorkspaceRequest.deserializeBinaryFromReader = function(msg, re
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.StartWorkspaceRequest.prototype.serializeBinary = function() {
+proto.wsman.BulkOperationFilter.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.StartWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.BulkOperationFilter.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1073,127 +16944,152 @@ proto.wsman.StartWorkspaceRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.StartWorkspaceRequest} message
+ * @param {!proto.wsman.BulkOperationFilter} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.BulkOperationFilter.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
+  f = message.getOwnersList();
   if (f.length > 0) {
-    writer.writeString(
+    writer.writeRepeatedString(
       1,
       f
     );
   }
-  f = message.getServicePrefix();
+  f = message.getProjectsList();
   if (f.length > 0) {
-    writer.writeString(
+    writer.writeRepeatedString(
       2,
       f
     );
   }
-  f = message.getMetadata();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getTypesList();
+  if (f.length > 0) {
+    writer.writePackedEnum(
       3,
-      f,
-      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
+      f
     );
   }
-  f = message.getSpec();
+  f = message.getStartedBefore();
   if (f != null) {
     writer.writeMessage(
       4,
       f,
-      proto.wsman.StartWorkspaceSpec.serializeBinaryToWriter
-    );
-  }
-  f = message.getType();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      6,
-      f
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
 };
 
 
 /**
- * optional string id = 1;
- * @return {string}
+ * repeated string owners = 1;
+ * @return {!Array<string>}
  */
-proto.wsman.StartWorkspaceRequest.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.BulkOperationFilter.prototype.getOwnersList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
 };
 
 
-/** @param {string} value */
-proto.wsman.StartWorkspaceRequest.prototype.setId = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+/** @param {!Array<string>} value */
+proto.wsman.BulkOperationFilter.prototype.setOwnersList = function(value) {
+  jspb.Message.setField(this, 1, value || []);
 };
 
 
 /**
- * optional string service_prefix = 2;
- * @return {string}
+ * @param {string} value
+ * @param {number=} opt_index
  */
-proto.wsman.StartWorkspaceRequest.prototype.getServicePrefix = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.BulkOperationFilter.prototype.addOwners = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 1, value, opt_index);
 };
 
 
-/** @param {string} value */
-proto.wsman.StartWorkspaceRequest.prototype.setServicePrefix = function(value) {
-  jspb.Message.setProto3StringField(this, 2, value);
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.BulkOperationFilter.prototype.clearOwnersList = function() {
+  this.setOwnersList([]);
 };
 
 
 /**
- * optional WorkspaceMetadata metadata = 3;
- * @return {?proto.wsman.WorkspaceMetadata}
+ * repeated string projects = 2;
+ * @return {!Array<string>}
  */
-proto.wsman.StartWorkspaceRequest.prototype.getMetadata = function() {
-  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 3));
+proto.wsman.BulkOperationFilter.prototype.getProjectsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 2));
 };
 
 
-/** @param {?proto.wsman.WorkspaceMetadata|undefined} value */
-proto.wsman.StartWorkspaceRequest.prototype.setMetadata = function(value) {
-  jspb.Message.setWrapperField(this, 3, value);
+/** @param {!Array<string>} value */
+proto.wsman.BulkOperationFilter.prototype.setProjectsList = function(value) {
+  jspb.Message.setField(this, 2, value || []);
 };
 
 
 /**
- * Clears the message field making it undefined.
+ * @param {string} value
+ * @param {number=} opt_index
  */
-proto.wsman.StartWorkspaceRequest.prototype.clearMetadata = function() {
-  this.setMetadata(undefined);
+proto.wsman.BulkOperationFilter.prototype.addProjects = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 2, value, opt_index);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Clears the list making it empty but non-null.
  */
-proto.wsman.StartWorkspaceRequest.prototype.hasMetadata = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.wsman.BulkOperationFilter.prototype.clearProjectsList = function() {
+  this.setProjectsList([]);
 };
 
 
 /**
- * optional StartWorkspaceSpec spec = 4;
- * @return {?proto.wsman.StartWorkspaceSpec}
+ * repeated WorkspaceType types = 3;
+ * @return {!Array<!proto.wsman.WorkspaceType>}
  */
-proto.wsman.StartWorkspaceRequest.prototype.getSpec = function() {
-  return /** @type{?proto.wsman.StartWorkspaceSpec} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.StartWorkspaceSpec, 4));
+proto.wsman.BulkOperationFilter.prototype.getTypesList = function() {
+  return /** @type {!Array<!proto.wsman.WorkspaceType>} */ (jspb.Message.getRepeatedField(this, 3));
 };
 
 
-/** @param {?proto.wsman.StartWorkspaceSpec|undefined} value */
-proto.wsman.StartWorkspaceRequest.prototype.setSpec = function(value) {
+/** @param {!Array<!proto.wsman.WorkspaceType>} value */
+proto.wsman.BulkOperationFilter.prototype.setTypesList = function(value) {
+  jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceType} value
+ * @param {number=} opt_index
+ */
+proto.wsman.BulkOperationFilter.prototype.addTypes = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.BulkOperationFilter.prototype.clearTypesList = function() {
+  this.setTypesList([]);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp started_before = 4;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.BulkOperationFilter.prototype.getStartedBefore = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 4));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.BulkOperationFilter.prototype.setStartedBefore = function(value) {
   jspb.Message.setWrapperField(this, 4, value);
 };
 
@@ -1201,8 +17097,8 @@ proto.wsman.StartWorkspaceRequest.prototype.setSpec = function(value) {
 /**
  * Clears the message field making it undefined.
  */
-proto.wsman.StartWorkspaceRequest.prototype.clearSpec = function() {
-  this.setSpec(undefined);
+proto.wsman.BulkOperationFilter.prototype.clearStartedBefore = function() {
+  this.setStartedBefore(undefined);
 };
 
 
@@ -1210,26 +17106,38 @@ proto.wsman.StartWorkspaceRequest.prototype.clearSpec = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.StartWorkspaceRequest.prototype.hasSpec = function() {
+proto.wsman.BulkOperationFilter.prototype.hasStartedBefore = function() {
   return jspb.Message.getField(this, 4) != null;
 };
 
 
+
 /**
- * optional WorkspaceType type = 6;
- * @return {!proto.wsman.WorkspaceType}
+ * Oneof group definitions for this message. Each group defines the field
+ * numbers belonging to that group. When of these fields' value is set, all
+ * other fields in the group are cleared. During deserialization, if multiple
+ * fields are encountered for a group, only the last value seen will be kept.
+ * @private {!Array<!Array<number>>}
+ * @const
  */
-proto.wsman.StartWorkspaceRequest.prototype.getType = function() {
-  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
-};
+proto.wsman.StartBulkOperationRequest.oneofGroups_ = [[2,3,4]];
 
-
-/** @param {!proto.wsman.WorkspaceType} value */
-proto.wsman.StartWorkspaceRequest.prototype.setType = function(value) {
-  jspb.Message.setProto3EnumField(this, 6, value);
+/**
+ * @enum {number}
+ */
+proto.wsman.StartBulkOperationRequest.OperationCase = {
+  OPERATION_NOT_SET: 0,
+  STOP: 2,
+  SET_TIMEOUT: 3,
+  MIGRATE: 4
 };
 
-
+/**
+ * @return {proto.wsman.StartBulkOperationRequest.OperationCase}
+ */
+proto.wsman.StartBulkOperationRequest.prototype.getOperationCase = function() {
+  return /** @type {proto.wsman.StartBulkOperationRequest.OperationCase} */(jspb.Message.computeOneofCase(this, proto.wsman.StartBulkOperationRequest.oneofGroups_[0]));
+};
 
 
 
@@ -1244,8 +17152,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.StartWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.StartWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsman.StartBulkOperationRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartBulkOperationRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -1254,13 +17162,18 @@ proto.wsman.StartWorkspaceResponse.prototype.toObject = function(opt_includeInst
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.StartWorkspaceResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.StartBulkOperationRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceResponse.toObject = function(includeInstance, msg) {
+proto.wsman.StartBulkOperationRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+    filter: (f = msg.getFilter()) && proto.wsman.BulkOperationFilter.toObject(includeInstance, f),
+    stop: (f = msg.getStop()) && proto.wsman.BulkStopOperation.toObject(includeInstance, f),
+    setTimeout: (f = msg.getSetTimeout()) && proto.wsman.BulkSetTimeoutOperation.toObject(includeInstance, f),
+    migrate: (f = msg.getMigrate()) && proto.wsman.BulkMigrateOperation.toObject(includeInstance, f),
+    concurrency: jspb.Message.getFieldWithDefault(msg, 5, 0),
+    dryRun: jspb.Message.getFieldWithDefault(msg, 6, false)
   };
 
   if (includeInstance) {
@@ -1274,23 +17187,23 @@ proto.wsman.StartWorkspaceResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.StartWorkspaceResponse}
+ * @return {!proto.wsman.StartBulkOperationRequest}
  */
-proto.wsman.StartWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsman.StartBulkOperationRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.StartWorkspaceResponse;
-  return proto.wsman.StartWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.StartBulkOperationRequest;
+  return proto.wsman.StartBulkOperationRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.StartWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.StartBulkOperationRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.StartWorkspaceResponse}
+ * @return {!proto.wsman.StartBulkOperationRequest}
  */
-proto.wsman.StartWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.StartBulkOperationRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1298,8 +17211,32 @@ proto.wsman.StartWorkspaceResponse.deserializeBinaryFromReader = function(msg, r
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
+      var value = new proto.wsman.BulkOperationFilter;
+      reader.readMessage(value,proto.wsman.BulkOperationFilter.deserializeBinaryFromReader);
+      msg.setFilter(value);
+      break;
+    case 2:
+      var value = new proto.wsman.BulkStopOperation;
+      reader.readMessage(value,proto.wsman.BulkStopOperation.deserializeBinaryFromReader);
+      msg.setStop(value);
+      break;
+    case 3:
+      var value = new proto.wsman.BulkSetTimeoutOperation;
+      reader.readMessage(value,proto.wsman.BulkSetTimeoutOperation.deserializeBinaryFromReader);
+      msg.setSetTimeout(value);
+      break;
+    case 4:
+      var value = new proto.wsman.BulkMigrateOperation;
+      reader.readMessage(value,proto.wsman.BulkMigrateOperation.deserializeBinaryFromReader);
+      msg.setMigrate(value);
+      break;
+    case 5:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setConcurrency(value);
+      break;
+    case 6:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setDryRun(value);
       break;
     default:
       reader.skipField();
@@ -1314,9 +17251,9 @@ proto.wsman.StartWorkspaceResponse.deserializeBinaryFromReader = function(msg, r
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.StartWorkspaceResponse.prototype.serializeBinary = function() {
+proto.wsman.StartBulkOperationRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.StartWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.StartBulkOperationRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1324,16 +17261,55 @@ proto.wsman.StartWorkspaceResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.StartWorkspaceResponse} message
+ * @param {!proto.wsman.StartBulkOperationRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StartWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.StartBulkOperationRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getUrl();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getFilter();
+  if (f != null) {
+    writer.writeMessage(
       1,
+      f,
+      proto.wsman.BulkOperationFilter.serializeBinaryToWriter
+    );
+  }
+  f = message.getStop();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.wsman.BulkStopOperation.serializeBinaryToWriter
+    );
+  }
+  f = message.getSetTimeout();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.wsman.BulkSetTimeoutOperation.serializeBinaryToWriter
+    );
+  }
+  f = message.getMigrate();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.wsman.BulkMigrateOperation.serializeBinaryToWriter
+    );
+  }
+  f = message.getConcurrency();
+  if (f !== 0) {
+    writer.writeUint32(
+      5,
+      f
+    );
+  }
+  f = message.getDryRun();
+  if (f) {
+    writer.writeBool(
+      6,
       f
     );
   }
@@ -1341,169 +17317,166 @@ proto.wsman.StartWorkspaceResponse.serializeBinaryToWriter = function(message, w
 
 
 /**
- * optional string url = 1;
- * @return {string}
+ * optional BulkOperationFilter filter = 1;
+ * @return {?proto.wsman.BulkOperationFilter}
  */
-proto.wsman.StartWorkspaceResponse.prototype.getUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.StartBulkOperationRequest.prototype.getFilter = function() {
+  return /** @type{?proto.wsman.BulkOperationFilter} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkOperationFilter, 1));
 };
 
 
-/** @param {string} value */
-proto.wsman.StartWorkspaceResponse.prototype.setUrl = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+/** @param {?proto.wsman.BulkOperationFilter|undefined} value */
+proto.wsman.StartBulkOperationRequest.prototype.setFilter = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.StartBulkOperationRequest.prototype.clearFilter = function() {
+  this.setFilter(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.StartBulkOperationRequest.prototype.hasFilter = function() {
+  return jspb.Message.getField(this, 1) != null;
 };
 
 
+/**
+ * optional BulkStopOperation stop = 2;
+ * @return {?proto.wsman.BulkStopOperation}
+ */
+proto.wsman.StartBulkOperationRequest.prototype.getStop = function() {
+  return /** @type{?proto.wsman.BulkStopOperation} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkStopOperation, 2));
+};
+
 
+/** @param {?proto.wsman.BulkStopOperation|undefined} value */
+proto.wsman.StartBulkOperationRequest.prototype.setStop = function(value) {
+  jspb.Message.setOneofWrapperField(this, 2, proto.wsman.StartBulkOperationRequest.oneofGroups_[0], value);
+};
 
 
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto suitable for use in Soy templates.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
- * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
- *     for transitional soy proto support: http://goto/soy-param-migration
- * @return {!Object}
+ * Clears the message field making it undefined.
+ */
+proto.wsman.StartBulkOperationRequest.prototype.clearStop = function() {
+  this.setStop(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.StopWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.StopWorkspaceRequest.toObject(opt_includeInstance, this);
+proto.wsman.StartBulkOperationRequest.prototype.hasStop = function() {
+  return jspb.Message.getField(this, 2) != null;
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Whether to include the JSPB
- *     instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.wsman.StopWorkspaceRequest} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * optional BulkSetTimeoutOperation set_timeout = 3;
+ * @return {?proto.wsman.BulkSetTimeoutOperation}
  */
-proto.wsman.StopWorkspaceRequest.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    policy: jspb.Message.getFieldWithDefault(msg, 2, 0)
-  };
+proto.wsman.StartBulkOperationRequest.prototype.getSetTimeout = function() {
+  return /** @type{?proto.wsman.BulkSetTimeoutOperation} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkSetTimeoutOperation, 3));
+};
 
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+
+/** @param {?proto.wsman.BulkSetTimeoutOperation|undefined} value */
+proto.wsman.StartBulkOperationRequest.prototype.setSetTimeout = function(value) {
+  jspb.Message.setOneofWrapperField(this, 3, proto.wsman.StartBulkOperationRequest.oneofGroups_[0], value);
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.StopWorkspaceRequest}
+ * Clears the message field making it undefined.
  */
-proto.wsman.StopWorkspaceRequest.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.StopWorkspaceRequest;
-  return proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+proto.wsman.StartBulkOperationRequest.prototype.clearSetTimeout = function() {
+  this.setSetTimeout(undefined);
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.wsman.StopWorkspaceRequest} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.StopWorkspaceRequest}
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.StopWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
-      break;
-    case 2:
-      var value = /** @type {!proto.wsman.StopWorkspacePolicy} */ (reader.readEnum());
-      msg.setPolicy(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.wsman.StartBulkOperationRequest.prototype.hasSetTimeout = function() {
+  return jspb.Message.getField(this, 3) != null;
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * optional BulkMigrateOperation migrate = 4;
+ * @return {?proto.wsman.BulkMigrateOperation}
  */
-proto.wsman.StopWorkspaceRequest.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.wsman.StartBulkOperationRequest.prototype.getMigrate = function() {
+  return /** @type{?proto.wsman.BulkMigrateOperation} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkMigrateOperation, 4));
+};
+
+
+/** @param {?proto.wsman.BulkMigrateOperation|undefined} value */
+proto.wsman.StartBulkOperationRequest.prototype.setMigrate = function(value) {
+  jspb.Message.setOneofWrapperField(this, 4, proto.wsman.StartBulkOperationRequest.oneofGroups_[0], value);
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.StopWorkspaceRequest} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Clears the message field making it undefined.
  */
-proto.wsman.StopWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getId();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-  f = message.getPolicy();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      2,
-      f
-    );
-  }
+proto.wsman.StartBulkOperationRequest.prototype.clearMigrate = function() {
+  this.setMigrate(undefined);
 };
 
 
 /**
- * optional string id = 1;
- * @return {string}
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.StopWorkspaceRequest.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.StartBulkOperationRequest.prototype.hasMigrate = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
-/** @param {string} value */
-proto.wsman.StopWorkspaceRequest.prototype.setId = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+/**
+ * optional uint32 concurrency = 5;
+ * @return {number}
+ */
+proto.wsman.StartBulkOperationRequest.prototype.getConcurrency = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.StartBulkOperationRequest.prototype.setConcurrency = function(value) {
+  jspb.Message.setProto3IntField(this, 5, value);
 };
 
 
 /**
- * optional StopWorkspacePolicy policy = 2;
- * @return {!proto.wsman.StopWorkspacePolicy}
+ * optional bool dry_run = 6;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
  */
-proto.wsman.StopWorkspaceRequest.prototype.getPolicy = function() {
-  return /** @type {!proto.wsman.StopWorkspacePolicy} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+proto.wsman.StartBulkOperationRequest.prototype.getDryRun = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 6, false));
 };
 
 
-/** @param {!proto.wsman.StopWorkspacePolicy} value */
-proto.wsman.StopWorkspaceRequest.prototype.setPolicy = function(value) {
-  jspb.Message.setProto3EnumField(this, 2, value);
+/** @param {boolean} value */
+proto.wsman.StartBulkOperationRequest.prototype.setDryRun = function(value) {
+  jspb.Message.setProto3BooleanField(this, 6, value);
 };
 
 
@@ -1521,8 +17494,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.StopWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.StopWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsman.BulkStopOperation.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BulkStopOperation.toObject(opt_includeInstance, this);
 };
 
 
@@ -1531,13 +17504,13 @@ proto.wsman.StopWorkspaceResponse.prototype.toObject = function(opt_includeInsta
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.StopWorkspaceResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.BulkStopOperation} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StopWorkspaceResponse.toObject = function(includeInstance, msg) {
+proto.wsman.BulkStopOperation.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    policy: jspb.Message.getFieldWithDefault(msg, 1, 0)
   };
 
   if (includeInstance) {
@@ -1551,29 +17524,33 @@ proto.wsman.StopWorkspaceResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.StopWorkspaceResponse}
+ * @return {!proto.wsman.BulkStopOperation}
  */
-proto.wsman.StopWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsman.BulkStopOperation.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.StopWorkspaceResponse;
-  return proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BulkStopOperation;
+  return proto.wsman.BulkStopOperation.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.StopWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.BulkStopOperation} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.StopWorkspaceResponse}
+ * @return {!proto.wsman.BulkStopOperation}
  */
-proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BulkStopOperation.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = /** @type {!proto.wsman.StopWorkspacePolicy} */ (reader.readEnum());
+      msg.setPolicy(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1587,9 +17564,9 @@ proto.wsman.StopWorkspaceResponse.deserializeBinaryFromReader = function(msg, re
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.StopWorkspaceResponse.prototype.serializeBinary = function() {
+proto.wsman.BulkStopOperation.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.BulkStopOperation.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1597,12 +17574,34 @@ proto.wsman.StopWorkspaceResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.StopWorkspaceResponse} message
+ * @param {!proto.wsman.BulkStopOperation} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.StopWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.BulkStopOperation.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getPolicy();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional StopWorkspacePolicy policy = 1;
+ * @return {!proto.wsman.StopWorkspacePolicy}
+ */
+proto.wsman.BulkStopOperation.prototype.getPolicy = function() {
+  return /** @type {!proto.wsman.StopWorkspacePolicy} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/** @param {!proto.wsman.StopWorkspacePolicy} value */
+proto.wsman.BulkStopOperation.prototype.setPolicy = function(value) {
+  jspb.Message.setProto3EnumField(this, 1, value);
 };
 
 
@@ -1620,8 +17619,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.DescribeWorkspaceRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.DescribeWorkspaceRequest.toObject(opt_includeInstance, this);
+proto.wsman.BulkSetTimeoutOperation.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BulkSetTimeoutOperation.toObject(opt_includeInstance, this);
 };
 
 
@@ -1630,13 +17629,13 @@ proto.wsman.DescribeWorkspaceRequest.prototype.toObject = function(opt_includeIn
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.DescribeWorkspaceRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.BulkSetTimeoutOperation} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceRequest.toObject = function(includeInstance, msg) {
+proto.wsman.BulkSetTimeoutOperation.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, "")
+    duration: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -1650,23 +17649,23 @@ proto.wsman.DescribeWorkspaceRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.DescribeWorkspaceRequest}
+ * @return {!proto.wsman.BulkSetTimeoutOperation}
  */
-proto.wsman.DescribeWorkspaceRequest.deserializeBinary = function(bytes) {
+proto.wsman.BulkSetTimeoutOperation.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.DescribeWorkspaceRequest;
-  return proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BulkSetTimeoutOperation;
+  return proto.wsman.BulkSetTimeoutOperation.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.DescribeWorkspaceRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.BulkSetTimeoutOperation} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.DescribeWorkspaceRequest}
+ * @return {!proto.wsman.BulkSetTimeoutOperation}
  */
-proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BulkSetTimeoutOperation.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1675,7 +17674,7 @@ proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader = function(msg,
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
+      msg.setDuration(value);
       break;
     default:
       reader.skipField();
@@ -1690,9 +17689,9 @@ proto.wsman.DescribeWorkspaceRequest.deserializeBinaryFromReader = function(msg,
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.DescribeWorkspaceRequest.prototype.serializeBinary = function() {
+proto.wsman.BulkSetTimeoutOperation.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.BulkSetTimeoutOperation.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1700,13 +17699,13 @@ proto.wsman.DescribeWorkspaceRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.DescribeWorkspaceRequest} message
+ * @param {!proto.wsman.BulkSetTimeoutOperation} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.BulkSetTimeoutOperation.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getId();
+  f = message.getDuration();
   if (f.length > 0) {
     writer.writeString(
       1,
@@ -1717,16 +17716,16 @@ proto.wsman.DescribeWorkspaceRequest.serializeBinaryToWriter = function(message,
 
 
 /**
- * optional string id = 1;
+ * optional string duration = 1;
  * @return {string}
  */
-proto.wsman.DescribeWorkspaceRequest.prototype.getId = function() {
+proto.wsman.BulkSetTimeoutOperation.prototype.getDuration = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.DescribeWorkspaceRequest.prototype.setId = function(value) {
+proto.wsman.BulkSetTimeoutOperation.prototype.setDuration = function(value) {
   jspb.Message.setProto3StringField(this, 1, value);
 };
 
@@ -1745,8 +17744,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.DescribeWorkspaceResponse.toObject(opt_includeInstance, this);
+proto.wsman.BulkMigrateOperation.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BulkMigrateOperation.toObject(opt_includeInstance, this);
 };
 
 
@@ -1755,14 +17754,13 @@ proto.wsman.DescribeWorkspaceResponse.prototype.toObject = function(opt_includeI
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.DescribeWorkspaceResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.BulkMigrateOperation} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceResponse.toObject = function(includeInstance, msg) {
+proto.wsman.BulkMigrateOperation.toObject = function(includeInstance, msg) {
   var f, obj = {
-    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
-    lastactivity: jspb.Message.getFieldWithDefault(msg, 2, "")
+    targetCluster: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -1776,23 +17774,23 @@ proto.wsman.DescribeWorkspaceResponse.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.DescribeWorkspaceResponse}
+ * @return {!proto.wsman.BulkMigrateOperation}
  */
-proto.wsman.DescribeWorkspaceResponse.deserializeBinary = function(bytes) {
+proto.wsman.BulkMigrateOperation.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.DescribeWorkspaceResponse;
-  return proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BulkMigrateOperation;
+  return proto.wsman.BulkMigrateOperation.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.DescribeWorkspaceResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.BulkMigrateOperation} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.DescribeWorkspaceResponse}
+ * @return {!proto.wsman.BulkMigrateOperation}
  */
-proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BulkMigrateOperation.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1800,110 +17798,71 @@ proto.wsman.DescribeWorkspaceResponse.deserializeBinaryFromReader = function(msg
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new proto.wsman.WorkspaceStatus;
-      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
-      msg.setStatus(value);
-      break;
-    case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setLastactivity(value);
+      msg.setTargetCluster(value);
       break;
     default:
       reader.skipField();
-      break;
-    }
-  }
-  return msg;
-};
-
-
-/**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
- */
-proto.wsman.DescribeWorkspaceResponse.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
-};
-
-
-/**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.DescribeWorkspaceResponse} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
- */
-proto.wsman.DescribeWorkspaceResponse.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getStatus();
-  if (f != null) {
-    writer.writeMessage(
-      1,
-      f,
-      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
-    );
-  }
-  f = message.getLastactivity();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
-      f
-    );
-  }
-};
-
-
-/**
- * optional WorkspaceStatus status = 1;
- * @return {?proto.wsman.WorkspaceStatus}
- */
-proto.wsman.DescribeWorkspaceResponse.prototype.getStatus = function() {
-  return /** @type{?proto.wsman.WorkspaceStatus} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
-};
-
-
-/** @param {?proto.wsman.WorkspaceStatus|undefined} value */
-proto.wsman.DescribeWorkspaceResponse.prototype.setStatus = function(value) {
-  jspb.Message.setWrapperField(this, 1, value);
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * Clears the message field making it undefined.
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.clearStatus = function() {
-  this.setStatus(undefined);
+proto.wsman.BulkMigrateOperation.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.BulkMigrateOperation.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.BulkMigrateOperation} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.hasStatus = function() {
-  return jspb.Message.getField(this, 1) != null;
+proto.wsman.BulkMigrateOperation.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getTargetCluster();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string lastActivity = 2;
+ * optional string target_cluster = 1;
  * @return {string}
  */
-proto.wsman.DescribeWorkspaceResponse.prototype.getLastactivity = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.BulkMigrateOperation.prototype.getTargetCluster = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.DescribeWorkspaceResponse.prototype.setLastactivity = function(value) {
-  jspb.Message.setProto3StringField(this, 2, value);
+proto.wsman.BulkMigrateOperation.prototype.setTargetCluster = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.StartBulkOperationResponse.repeatedFields_ = [2];
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -1917,8 +17876,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.SubscribeRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SubscribeRequest.toObject(opt_includeInstance, this);
+proto.wsman.StartBulkOperationResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.StartBulkOperationResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -1927,13 +17886,14 @@ proto.wsman.SubscribeRequest.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.SubscribeRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.StartBulkOperationResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeRequest.toObject = function(includeInstance, msg) {
+proto.wsman.StartBulkOperationResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    workspaceIdsList: jspb.Message.getRepeatedField(msg, 2)
   };
 
   if (includeInstance) {
@@ -1947,29 +17907,37 @@ proto.wsman.SubscribeRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SubscribeRequest}
+ * @return {!proto.wsman.StartBulkOperationResponse}
  */
-proto.wsman.SubscribeRequest.deserializeBinary = function(bytes) {
+proto.wsman.StartBulkOperationResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SubscribeRequest;
-  return proto.wsman.SubscribeRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.StartBulkOperationResponse;
+  return proto.wsman.StartBulkOperationResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.SubscribeRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.StartBulkOperationResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SubscribeRequest}
+ * @return {!proto.wsman.StartBulkOperationResponse}
  */
-proto.wsman.SubscribeRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.StartBulkOperationResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addWorkspaceIds(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1983,9 +17951,9 @@ proto.wsman.SubscribeRequest.deserializeBinaryFromReader = function(msg, reader)
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.SubscribeRequest.prototype.serializeBinary = function() {
+proto.wsman.StartBulkOperationResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.SubscribeRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.StartBulkOperationResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1993,44 +17961,79 @@ proto.wsman.SubscribeRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SubscribeRequest} message
+ * @param {!proto.wsman.StartBulkOperationResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.StartBulkOperationResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getWorkspaceIdsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.StartBulkOperationResponse.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
+/** @param {string} value */
+proto.wsman.StartBulkOperationResponse.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
 
 /**
- * Oneof group definitions for this message. Each group defines the field
- * numbers belonging to that group. When of these fields' value is set, all
- * other fields in the group are cleared. During deserialization, if multiple
- * fields are encountered for a group, only the last value seen will be kept.
- * @private {!Array<!Array<number>>}
- * @const
+ * repeated string workspace_ids = 2;
+ * @return {!Array<string>}
  */
-proto.wsman.SubscribeResponse.oneofGroups_ = [[1,2]];
+proto.wsman.StartBulkOperationResponse.prototype.getWorkspaceIdsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 2));
+};
+
+
+/** @param {!Array<string>} value */
+proto.wsman.StartBulkOperationResponse.prototype.setWorkspaceIdsList = function(value) {
+  jspb.Message.setField(this, 2, value || []);
+};
+
 
 /**
- * @enum {number}
+ * @param {string} value
+ * @param {number=} opt_index
  */
-proto.wsman.SubscribeResponse.PayloadCase = {
-  PAYLOAD_NOT_SET: 0,
-  STATUS: 1,
-  LOG: 2
+proto.wsman.StartBulkOperationResponse.prototype.addWorkspaceIds = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 2, value, opt_index);
 };
 
+
 /**
- * @return {proto.wsman.SubscribeResponse.PayloadCase}
+ * Clears the list making it empty but non-null.
  */
-proto.wsman.SubscribeResponse.prototype.getPayloadCase = function() {
-  return /** @type {proto.wsman.SubscribeResponse.PayloadCase} */(jspb.Message.computeOneofCase(this, proto.wsman.SubscribeResponse.oneofGroups_[0]));
+proto.wsman.StartBulkOperationResponse.prototype.clearWorkspaceIdsList = function() {
+  this.setWorkspaceIdsList([]);
 };
 
 
 
+
+
 if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
  * Creates an object representation of this proto suitable for use in Soy templates.
@@ -2042,8 +18045,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.SubscribeResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SubscribeResponse.toObject(opt_includeInstance, this);
+proto.wsman.BulkOperationStatus.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BulkOperationStatus.toObject(opt_includeInstance, this);
 };
 
 
@@ -2052,15 +18055,19 @@ proto.wsman.SubscribeResponse.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.SubscribeResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.BulkOperationStatus} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeResponse.toObject = function(includeInstance, msg) {
+proto.wsman.BulkOperationStatus.toObject = function(includeInstance, msg) {
   var f, obj = {
-    status: (f = msg.getStatus()) && proto.wsman.WorkspaceStatus.toObject(includeInstance, f),
-    log: (f = msg.getLog()) && proto.wsman.WorkspaceLogMessage.toObject(includeInstance, f),
-    headerMap: (f = msg.getHeaderMap()) ? f.toObject(includeInstance, undefined) : []
+    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    state: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    total: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    succeeded: jspb.Message.getFieldWithDefault(msg, 4, 0),
+    failed: jspb.Message.getFieldWithDefault(msg, 5, 0),
+    createdAt: (f = msg.getCreatedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    completedAt: (f = msg.getCompletedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -2074,23 +18081,23 @@ proto.wsman.SubscribeResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SubscribeResponse}
+ * @return {!proto.wsman.BulkOperationStatus}
  */
-proto.wsman.SubscribeResponse.deserializeBinary = function(bytes) {
+proto.wsman.BulkOperationStatus.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SubscribeResponse;
-  return proto.wsman.SubscribeResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BulkOperationStatus;
+  return proto.wsman.BulkOperationStatus.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.SubscribeResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.BulkOperationStatus} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SubscribeResponse}
+ * @return {!proto.wsman.BulkOperationStatus}
  */
-proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BulkOperationStatus.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2098,20 +18105,34 @@ proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new proto.wsman.WorkspaceStatus;
-      reader.readMessage(value,proto.wsman.WorkspaceStatus.deserializeBinaryFromReader);
-      msg.setStatus(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
       break;
     case 2:
-      var value = new proto.wsman.WorkspaceLogMessage;
-      reader.readMessage(value,proto.wsman.WorkspaceLogMessage.deserializeBinaryFromReader);
-      msg.setLog(value);
+      var value = /** @type {!proto.wsman.BulkOperationState} */ (reader.readEnum());
+      msg.setState(value);
       break;
     case 3:
-      var value = msg.getHeaderMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
-         });
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setTotal(value);
+      break;
+    case 4:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setSucceeded(value);
+      break;
+    case 5:
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setFailed(value);
+      break;
+    case 6:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setCreatedAt(value);
+      break;
+    case 7:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setCompletedAt(value);
       break;
     default:
       reader.skipField();
@@ -2126,9 +18147,9 @@ proto.wsman.SubscribeResponse.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.SubscribeResponse.prototype.serializeBinary = function() {
+proto.wsman.BulkOperationStatus.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.SubscribeResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.BulkOperationStatus.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2136,56 +18157,162 @@ proto.wsman.SubscribeResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SubscribeResponse} message
+ * @param {!proto.wsman.BulkOperationStatus} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SubscribeResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.BulkOperationStatus.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getStatus();
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getState();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      2,
+      f
+    );
+  }
+  f = message.getTotal();
+  if (f !== 0) {
+    writer.writeUint32(
+      3,
+      f
+    );
+  }
+  f = message.getSucceeded();
+  if (f !== 0) {
+    writer.writeUint32(
+      4,
+      f
+    );
+  }
+  f = message.getFailed();
+  if (f !== 0) {
+    writer.writeUint32(
+      5,
+      f
+    );
+  }
+  f = message.getCreatedAt();
   if (f != null) {
     writer.writeMessage(
-      1,
+      6,
       f,
-      proto.wsman.WorkspaceStatus.serializeBinaryToWriter
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
-  f = message.getLog();
+  f = message.getCompletedAt();
   if (f != null) {
     writer.writeMessage(
-      2,
+      7,
       f,
-      proto.wsman.WorkspaceLogMessage.serializeBinaryToWriter
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
-  f = message.getHeaderMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
-  }
 };
 
 
 /**
- * optional WorkspaceStatus status = 1;
- * @return {?proto.wsman.WorkspaceStatus}
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.wsman.BulkOperationStatus.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.BulkOperationStatus.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional BulkOperationState state = 2;
+ * @return {!proto.wsman.BulkOperationState}
+ */
+proto.wsman.BulkOperationStatus.prototype.getState = function() {
+  return /** @type {!proto.wsman.BulkOperationState} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/** @param {!proto.wsman.BulkOperationState} value */
+proto.wsman.BulkOperationStatus.prototype.setState = function(value) {
+  jspb.Message.setProto3EnumField(this, 2, value);
+};
+
+
+/**
+ * optional uint32 total = 3;
+ * @return {number}
+ */
+proto.wsman.BulkOperationStatus.prototype.getTotal = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.BulkOperationStatus.prototype.setTotal = function(value) {
+  jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+/**
+ * optional uint32 succeeded = 4;
+ * @return {number}
+ */
+proto.wsman.BulkOperationStatus.prototype.getSucceeded = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.BulkOperationStatus.prototype.setSucceeded = function(value) {
+  jspb.Message.setProto3IntField(this, 4, value);
+};
+
+
+/**
+ * optional uint32 failed = 5;
+ * @return {number}
+ */
+proto.wsman.BulkOperationStatus.prototype.getFailed = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.BulkOperationStatus.prototype.setFailed = function(value) {
+  jspb.Message.setProto3IntField(this, 5, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp created_at = 6;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.wsman.SubscribeResponse.prototype.getStatus = function() {
-  return /** @type{?proto.wsman.WorkspaceStatus} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceStatus, 1));
+proto.wsman.BulkOperationStatus.prototype.getCreatedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 6));
 };
 
 
-/** @param {?proto.wsman.WorkspaceStatus|undefined} value */
-proto.wsman.SubscribeResponse.prototype.setStatus = function(value) {
-  jspb.Message.setOneofWrapperField(this, 1, proto.wsman.SubscribeResponse.oneofGroups_[0], value);
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.BulkOperationStatus.prototype.setCreatedAt = function(value) {
+  jspb.Message.setWrapperField(this, 6, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
  */
-proto.wsman.SubscribeResponse.prototype.clearStatus = function() {
-  this.setStatus(undefined);
+proto.wsman.BulkOperationStatus.prototype.clearCreatedAt = function() {
+  this.setCreatedAt(undefined);
 };
 
 
@@ -2193,32 +18320,32 @@ proto.wsman.SubscribeResponse.prototype.clearStatus = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.SubscribeResponse.prototype.hasStatus = function() {
-  return jspb.Message.getField(this, 1) != null;
+proto.wsman.BulkOperationStatus.prototype.hasCreatedAt = function() {
+  return jspb.Message.getField(this, 6) != null;
 };
 
 
 /**
- * optional WorkspaceLogMessage log = 2;
- * @return {?proto.wsman.WorkspaceLogMessage}
+ * optional google.protobuf.Timestamp completed_at = 7;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.wsman.SubscribeResponse.prototype.getLog = function() {
-  return /** @type{?proto.wsman.WorkspaceLogMessage} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceLogMessage, 2));
+proto.wsman.BulkOperationStatus.prototype.getCompletedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 7));
 };
 
 
-/** @param {?proto.wsman.WorkspaceLogMessage|undefined} value */
-proto.wsman.SubscribeResponse.prototype.setLog = function(value) {
-  jspb.Message.setOneofWrapperField(this, 2, proto.wsman.SubscribeResponse.oneofGroups_[0], value);
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.BulkOperationStatus.prototype.setCompletedAt = function(value) {
+  jspb.Message.setWrapperField(this, 7, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
  */
-proto.wsman.SubscribeResponse.prototype.clearLog = function() {
-  this.setLog(undefined);
+proto.wsman.BulkOperationStatus.prototype.clearCompletedAt = function() {
+  this.setCompletedAt(undefined);
 };
 
 
@@ -2226,29 +18353,8 @@ proto.wsman.SubscribeResponse.prototype.clearLog = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.SubscribeResponse.prototype.hasLog = function() {
-  return jspb.Message.getField(this, 2) != null;
-};
-
-
-/**
- * map<string, string> header = 3;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,string>}
- */
-proto.wsman.SubscribeResponse.prototype.getHeaderMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,string>} */ (
-      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
-      null));
-};
-
-
-/**
- * Clears values from the map. The map will be non-null.
- */
-proto.wsman.SubscribeResponse.prototype.clearHeaderMap = function() {
-  this.getHeaderMap().clear();
+proto.wsman.BulkOperationStatus.prototype.hasCompletedAt = function() {
+  return jspb.Message.getField(this, 7) != null;
 };
 
 
@@ -2266,8 +18372,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.MarkActiveRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.MarkActiveRequest.toObject(opt_includeInstance, this);
+proto.wsman.BulkOperationResult.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BulkOperationResult.toObject(opt_includeInstance, this);
 };
 
 
@@ -2276,14 +18382,16 @@ proto.wsman.MarkActiveRequest.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.MarkActiveRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.BulkOperationResult} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.MarkActiveRequest.toObject = function(includeInstance, msg) {
+proto.wsman.BulkOperationResult.toObject = function(includeInstance, msg) {
   var f, obj = {
     id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    closed: jspb.Message.getFieldWithDefault(msg, 2, false)
+    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
+    error: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    completedAt: (f = msg.getCompletedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -2297,23 +18405,23 @@ proto.wsman.MarkActiveRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.MarkActiveRequest}
+ * @return {!proto.wsman.BulkOperationResult}
  */
-proto.wsman.MarkActiveRequest.deserializeBinary = function(bytes) {
+proto.wsman.BulkOperationResult.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.MarkActiveRequest;
-  return proto.wsman.MarkActiveRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BulkOperationResult;
+  return proto.wsman.BulkOperationResult.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.MarkActiveRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.BulkOperationResult} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.MarkActiveRequest}
+ * @return {!proto.wsman.BulkOperationResult}
  */
-proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BulkOperationResult.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2325,8 +18433,18 @@ proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader
       msg.setId(value);
       break;
     case 2:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setClosed(value);
+      var value = new proto.wsman.WorkspaceMetadata;
+      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
+      msg.setMetadata(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setError(value);
+      break;
+    case 4:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setCompletedAt(value);
       break;
     default:
       reader.skipField();
@@ -2341,9 +18459,9 @@ proto.wsman.MarkActiveRequest.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.MarkActiveRequest.prototype.serializeBinary = function() {
+proto.wsman.BulkOperationResult.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.MarkActiveRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.BulkOperationResult.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2351,11 +18469,11 @@ proto.wsman.MarkActiveRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.MarkActiveRequest} message
+ * @param {!proto.wsman.BulkOperationResult} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.MarkActiveRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.BulkOperationResult.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -2364,13 +18482,29 @@ proto.wsman.MarkActiveRequest.serializeBinaryToWriter = function(message, writer
       f
     );
   }
-  f = message.getClosed();
-  if (f) {
-    writer.writeBool(
+  f = message.getMetadata();
+  if (f != null) {
+    writer.writeMessage(
       2,
+      f,
+      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
+    );
+  }
+  f = message.getError();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
       f
     );
   }
+  f = message.getCompletedAt();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -2378,130 +18512,95 @@ proto.wsman.MarkActiveRequest.serializeBinaryToWriter = function(message, writer
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.MarkActiveRequest.prototype.getId = function() {
+proto.wsman.BulkOperationResult.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.MarkActiveRequest.prototype.setId = function(value) {
+proto.wsman.BulkOperationResult.prototype.setId = function(value) {
   jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional bool closed = 2;
- * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
- * You should avoid comparisons like {@code val === true/false} in those cases.
- * @return {boolean}
+ * optional WorkspaceMetadata metadata = 2;
+ * @return {?proto.wsman.WorkspaceMetadata}
  */
-proto.wsman.MarkActiveRequest.prototype.getClosed = function() {
-  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
+proto.wsman.BulkOperationResult.prototype.getMetadata = function() {
+  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 2));
 };
 
 
-/** @param {boolean} value */
-proto.wsman.MarkActiveRequest.prototype.setClosed = function(value) {
-  jspb.Message.setProto3BooleanField(this, 2, value);
+/** @param {?proto.wsman.WorkspaceMetadata|undefined} value */
+proto.wsman.BulkOperationResult.prototype.setMetadata = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
 };
 
 
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.BulkOperationResult.prototype.clearMetadata = function() {
+  this.setMetadata(undefined);
+};
 
 
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto suitable for use in Soy templates.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
- * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
- *     for transitional soy proto support: http://goto/soy-param-migration
- * @return {!Object}
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.MarkActiveResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.MarkActiveResponse.toObject(opt_includeInstance, this);
+proto.wsman.BulkOperationResult.prototype.hasMetadata = function() {
+  return jspb.Message.getField(this, 2) != null;
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Whether to include the JSPB
- *     instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.wsman.MarkActiveResponse} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * optional string error = 3;
+ * @return {string}
  */
-proto.wsman.MarkActiveResponse.toObject = function(includeInstance, msg) {
-  var f, obj = {
+proto.wsman.BulkOperationResult.prototype.getError = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
 
-  };
 
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+/** @param {string} value */
+proto.wsman.BulkOperationResult.prototype.setError = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.MarkActiveResponse}
+ * optional google.protobuf.Timestamp completed_at = 4;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.wsman.MarkActiveResponse.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.MarkActiveResponse;
-  return proto.wsman.MarkActiveResponse.deserializeBinaryFromReader(msg, reader);
+proto.wsman.BulkOperationResult.prototype.getCompletedAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 4));
 };
 
 
-/**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.wsman.MarkActiveResponse} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.MarkActiveResponse}
- */
-proto.wsman.MarkActiveResponse.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.BulkOperationResult.prototype.setCompletedAt = function(value) {
+  jspb.Message.setWrapperField(this, 4, value);
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * Clears the message field making it undefined.
  */
-proto.wsman.MarkActiveResponse.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.wsman.MarkActiveResponse.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.wsman.BulkOperationResult.prototype.clearCompletedAt = function() {
+  this.setCompletedAt(undefined);
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.MarkActiveResponse} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.MarkActiveResponse.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
+proto.wsman.BulkOperationResult.prototype.hasCompletedAt = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
@@ -2519,8 +18618,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.SetTimeoutRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SetTimeoutRequest.toObject(opt_includeInstance, this);
+proto.wsman.DescribeBulkOperationRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeBulkOperationRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -2529,14 +18628,13 @@ proto.wsman.SetTimeoutRequest.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.SetTimeoutRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.DescribeBulkOperationRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SetTimeoutRequest.toObject = function(includeInstance, msg) {
+proto.wsman.DescribeBulkOperationRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    duration: jspb.Message.getFieldWithDefault(msg, 2, "")
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -2550,23 +18648,23 @@ proto.wsman.SetTimeoutRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SetTimeoutRequest}
+ * @return {!proto.wsman.DescribeBulkOperationRequest}
  */
-proto.wsman.SetTimeoutRequest.deserializeBinary = function(bytes) {
+proto.wsman.DescribeBulkOperationRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SetTimeoutRequest;
-  return proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.DescribeBulkOperationRequest;
+  return proto.wsman.DescribeBulkOperationRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.SetTimeoutRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.DescribeBulkOperationRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SetTimeoutRequest}
+ * @return {!proto.wsman.DescribeBulkOperationRequest}
  */
-proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.DescribeBulkOperationRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2577,10 +18675,6 @@ proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader
       var value = /** @type {string} */ (reader.readString());
       msg.setId(value);
       break;
-    case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setDuration(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -2594,9 +18688,9 @@ proto.wsman.SetTimeoutRequest.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.SetTimeoutRequest.prototype.serializeBinary = function() {
+proto.wsman.DescribeBulkOperationRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.SetTimeoutRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.DescribeBulkOperationRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2604,11 +18698,11 @@ proto.wsman.SetTimeoutRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SetTimeoutRequest} message
+ * @param {!proto.wsman.DescribeBulkOperationRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SetTimeoutRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.DescribeBulkOperationRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -2617,13 +18711,6 @@ proto.wsman.SetTimeoutRequest.serializeBinaryToWriter = function(message, writer
       f
     );
   }
-  f = message.getDuration();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
-      f
-    );
-  }
 };
 
 
@@ -2631,32 +18718,24 @@ proto.wsman.SetTimeoutRequest.serializeBinaryToWriter = function(message, writer
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.SetTimeoutRequest.prototype.getId = function() {
+proto.wsman.DescribeBulkOperationRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.SetTimeoutRequest.prototype.setId = function(value) {
+proto.wsman.DescribeBulkOperationRequest.prototype.setId = function(value) {
   jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
+
 /**
- * optional string duration = 2;
- * @return {string}
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
  */
-proto.wsman.SetTimeoutRequest.prototype.getDuration = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
-};
-
-
-/** @param {string} value */
-proto.wsman.SetTimeoutRequest.prototype.setDuration = function(value) {
-  jspb.Message.setProto3StringField(this, 2, value);
-};
-
-
+proto.wsman.DescribeBulkOperationResponse.repeatedFields_ = [2];
 
 
 
@@ -2671,8 +18750,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.SetTimeoutResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.SetTimeoutResponse.toObject(opt_includeInstance, this);
+proto.wsman.DescribeBulkOperationResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.DescribeBulkOperationResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -2681,13 +18760,15 @@ proto.wsman.SetTimeoutResponse.prototype.toObject = function(opt_includeInstance
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.SetTimeoutResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.DescribeBulkOperationResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SetTimeoutResponse.toObject = function(includeInstance, msg) {
+proto.wsman.DescribeBulkOperationResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    status: (f = msg.getStatus()) && proto.wsman.BulkOperationStatus.toObject(includeInstance, f),
+    resultsList: jspb.Message.toObjectList(msg.getResultsList(),
+    proto.wsman.BulkOperationResult.toObject, includeInstance)
   };
 
   if (includeInstance) {
@@ -2701,29 +18782,39 @@ proto.wsman.SetTimeoutResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.SetTimeoutResponse}
+ * @return {!proto.wsman.DescribeBulkOperationResponse}
  */
-proto.wsman.SetTimeoutResponse.deserializeBinary = function(bytes) {
+proto.wsman.DescribeBulkOperationResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.SetTimeoutResponse;
-  return proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.DescribeBulkOperationResponse;
+  return proto.wsman.DescribeBulkOperationResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.SetTimeoutResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.DescribeBulkOperationResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.SetTimeoutResponse}
+ * @return {!proto.wsman.DescribeBulkOperationResponse}
  */
-proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.DescribeBulkOperationResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = new proto.wsman.BulkOperationStatus;
+      reader.readMessage(value,proto.wsman.BulkOperationStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
+      break;
+    case 2:
+      var value = new proto.wsman.BulkOperationResult;
+      reader.readMessage(value,proto.wsman.BulkOperationResult.deserializeBinaryFromReader);
+      msg.addResults(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -2737,9 +18828,9 @@ proto.wsman.SetTimeoutResponse.deserializeBinaryFromReader = function(msg, reade
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.SetTimeoutResponse.prototype.serializeBinary = function() {
+proto.wsman.DescribeBulkOperationResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.SetTimeoutResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.DescribeBulkOperationResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2747,12 +18838,95 @@ proto.wsman.SetTimeoutResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.SetTimeoutResponse} message
+ * @param {!proto.wsman.DescribeBulkOperationResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.SetTimeoutResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.DescribeBulkOperationResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.BulkOperationStatus.serializeBinaryToWriter
+    );
+  }
+  f = message.getResultsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      2,
+      f,
+      proto.wsman.BulkOperationResult.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional BulkOperationStatus status = 1;
+ * @return {?proto.wsman.BulkOperationStatus}
+ */
+proto.wsman.DescribeBulkOperationResponse.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.BulkOperationStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkOperationStatus, 1));
+};
+
+
+/** @param {?proto.wsman.BulkOperationStatus|undefined} value */
+proto.wsman.DescribeBulkOperationResponse.prototype.setStatus = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.DescribeBulkOperationResponse.prototype.clearStatus = function() {
+  this.setStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.DescribeBulkOperationResponse.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * repeated BulkOperationResult results = 2;
+ * @return {!Array<!proto.wsman.BulkOperationResult>}
+ */
+proto.wsman.DescribeBulkOperationResponse.prototype.getResultsList = function() {
+  return /** @type{!Array<!proto.wsman.BulkOperationResult>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.BulkOperationResult, 2));
+};
+
+
+/** @param {!Array<!proto.wsman.BulkOperationResult>} value */
+proto.wsman.DescribeBulkOperationResponse.prototype.setResultsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 2, value);
+};
+
+
+/**
+ * @param {!proto.wsman.BulkOperationResult=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.BulkOperationResult}
+ */
+proto.wsman.DescribeBulkOperationResponse.prototype.addResults = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 2, opt_value, proto.wsman.BulkOperationResult, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.DescribeBulkOperationResponse.prototype.clearResultsList = function() {
+  this.setResultsList([]);
 };
 
 
@@ -2770,8 +18944,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.ControlPortRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlPortRequest.toObject(opt_includeInstance, this);
+proto.wsman.WatchBulkOperationRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WatchBulkOperationRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -2780,15 +18954,13 @@ proto.wsman.ControlPortRequest.prototype.toObject = function(opt_includeInstance
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlPortRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.WatchBulkOperationRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortRequest.toObject = function(includeInstance, msg) {
+proto.wsman.WatchBulkOperationRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    expose: jspb.Message.getFieldWithDefault(msg, 2, false),
-    spec: (f = msg.getSpec()) && proto.wsman.PortSpec.toObject(includeInstance, f)
+    id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -2802,23 +18974,23 @@ proto.wsman.ControlPortRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlPortRequest}
+ * @return {!proto.wsman.WatchBulkOperationRequest}
  */
-proto.wsman.ControlPortRequest.deserializeBinary = function(bytes) {
+proto.wsman.WatchBulkOperationRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlPortRequest;
-  return proto.wsman.ControlPortRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.WatchBulkOperationRequest;
+  return proto.wsman.WatchBulkOperationRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.ControlPortRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.WatchBulkOperationRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlPortRequest}
+ * @return {!proto.wsman.WatchBulkOperationRequest}
  */
-proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.WatchBulkOperationRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -2829,15 +19001,6 @@ proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reade
       var value = /** @type {string} */ (reader.readString());
       msg.setId(value);
       break;
-    case 2:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setExpose(value);
-      break;
-    case 3:
-      var value = new proto.wsman.PortSpec;
-      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
-      msg.setSpec(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -2851,9 +19014,9 @@ proto.wsman.ControlPortRequest.deserializeBinaryFromReader = function(msg, reade
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.ControlPortRequest.prototype.serializeBinary = function() {
+proto.wsman.WatchBulkOperationRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlPortRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.WatchBulkOperationRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -2861,11 +19024,11 @@ proto.wsman.ControlPortRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlPortRequest} message
+ * @param {!proto.wsman.WatchBulkOperationRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.WatchBulkOperationRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -2874,21 +19037,6 @@ proto.wsman.ControlPortRequest.serializeBinaryToWriter = function(message, write
       f
     );
   }
-  f = message.getExpose();
-  if (f) {
-    writer.writeBool(
-      2,
-      f
-    );
-  }
-  f = message.getSpec();
-  if (f != null) {
-    writer.writeMessage(
-      3,
-      f,
-      proto.wsman.PortSpec.serializeBinaryToWriter
-    );
-  }
 };
 
 
@@ -2896,67 +19044,17 @@ proto.wsman.ControlPortRequest.serializeBinaryToWriter = function(message, write
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.ControlPortRequest.prototype.getId = function() {
+proto.wsman.WatchBulkOperationRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.ControlPortRequest.prototype.setId = function(value) {
+proto.wsman.WatchBulkOperationRequest.prototype.setId = function(value) {
   jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
-/**
- * optional bool expose = 2;
- * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
- * You should avoid comparisons like {@code val === true/false} in those cases.
- * @return {boolean}
- */
-proto.wsman.ControlPortRequest.prototype.getExpose = function() {
-  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
-};
-
-
-/** @param {boolean} value */
-proto.wsman.ControlPortRequest.prototype.setExpose = function(value) {
-  jspb.Message.setProto3BooleanField(this, 2, value);
-};
-
-
-/**
- * optional PortSpec spec = 3;
- * @return {?proto.wsman.PortSpec}
- */
-proto.wsman.ControlPortRequest.prototype.getSpec = function() {
-  return /** @type{?proto.wsman.PortSpec} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.PortSpec, 3));
-};
-
-
-/** @param {?proto.wsman.PortSpec|undefined} value */
-proto.wsman.ControlPortRequest.prototype.setSpec = function(value) {
-  jspb.Message.setWrapperField(this, 3, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- */
-proto.wsman.ControlPortRequest.prototype.clearSpec = function() {
-  this.setSpec(undefined);
-};
-
-
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.wsman.ControlPortRequest.prototype.hasSpec = function() {
-  return jspb.Message.getField(this, 3) != null;
-};
-
-
 
 
 
@@ -2971,8 +19069,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.ControlPortResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlPortResponse.toObject(opt_includeInstance, this);
+proto.wsman.BulkOperationUpdate.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.BulkOperationUpdate.toObject(opt_includeInstance, this);
 };
 
 
@@ -2981,13 +19079,14 @@ proto.wsman.ControlPortResponse.prototype.toObject = function(opt_includeInstanc
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlPortResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.BulkOperationUpdate} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortResponse.toObject = function(includeInstance, msg) {
+proto.wsman.BulkOperationUpdate.toObject = function(includeInstance, msg) {
   var f, obj = {
-
+    status: (f = msg.getStatus()) && proto.wsman.BulkOperationStatus.toObject(includeInstance, f),
+    result: (f = msg.getResult()) && proto.wsman.BulkOperationResult.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -3001,29 +19100,39 @@ proto.wsman.ControlPortResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlPortResponse}
+ * @return {!proto.wsman.BulkOperationUpdate}
  */
-proto.wsman.ControlPortResponse.deserializeBinary = function(bytes) {
+proto.wsman.BulkOperationUpdate.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlPortResponse;
-  return proto.wsman.ControlPortResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.BulkOperationUpdate;
+  return proto.wsman.BulkOperationUpdate.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.ControlPortResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.BulkOperationUpdate} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlPortResponse}
+ * @return {!proto.wsman.BulkOperationUpdate}
  */
-proto.wsman.ControlPortResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.BulkOperationUpdate.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
+    case 1:
+      var value = new proto.wsman.BulkOperationStatus;
+      reader.readMessage(value,proto.wsman.BulkOperationStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
+      break;
+    case 2:
+      var value = new proto.wsman.BulkOperationResult;
+      reader.readMessage(value,proto.wsman.BulkOperationResult.deserializeBinaryFromReader);
+      msg.setResult(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -3037,9 +19146,9 @@ proto.wsman.ControlPortResponse.deserializeBinaryFromReader = function(msg, read
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.ControlPortResponse.prototype.serializeBinary = function() {
+proto.wsman.BulkOperationUpdate.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlPortResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.BulkOperationUpdate.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3047,12 +19156,94 @@ proto.wsman.ControlPortResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlPortResponse} message
+ * @param {!proto.wsman.BulkOperationUpdate} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlPortResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.BulkOperationUpdate.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      proto.wsman.BulkOperationStatus.serializeBinaryToWriter
+    );
+  }
+  f = message.getResult();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.wsman.BulkOperationResult.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional BulkOperationStatus status = 1;
+ * @return {?proto.wsman.BulkOperationStatus}
+ */
+proto.wsman.BulkOperationUpdate.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.BulkOperationStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkOperationStatus, 1));
+};
+
+
+/** @param {?proto.wsman.BulkOperationStatus|undefined} value */
+proto.wsman.BulkOperationUpdate.prototype.setStatus = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.BulkOperationUpdate.prototype.clearStatus = function() {
+  this.setStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.BulkOperationUpdate.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional BulkOperationResult result = 2;
+ * @return {?proto.wsman.BulkOperationResult}
+ */
+proto.wsman.BulkOperationUpdate.prototype.getResult = function() {
+  return /** @type{?proto.wsman.BulkOperationResult} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkOperationResult, 2));
+};
+
+
+/** @param {?proto.wsman.BulkOperationResult|undefined} value */
+proto.wsman.BulkOperationUpdate.prototype.setResult = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.BulkOperationUpdate.prototype.clearResult = function() {
+  this.setResult(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.BulkOperationUpdate.prototype.hasResult = function() {
+  return jspb.Message.getField(this, 2) != null;
 };
 
 
@@ -3070,8 +19261,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.TakeSnapshotRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.TakeSnapshotRequest.toObject(opt_includeInstance, this);
+proto.wsman.CancelBulkOperationRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.CancelBulkOperationRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -3080,11 +19271,11 @@ proto.wsman.TakeSnapshotRequest.prototype.toObject = function(opt_includeInstanc
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.TakeSnapshotRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.CancelBulkOperationRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotRequest.toObject = function(includeInstance, msg) {
+proto.wsman.CancelBulkOperationRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     id: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
@@ -3100,23 +19291,23 @@ proto.wsman.TakeSnapshotRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.TakeSnapshotRequest}
+ * @return {!proto.wsman.CancelBulkOperationRequest}
  */
-proto.wsman.TakeSnapshotRequest.deserializeBinary = function(bytes) {
+proto.wsman.CancelBulkOperationRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.TakeSnapshotRequest;
-  return proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.CancelBulkOperationRequest;
+  return proto.wsman.CancelBulkOperationRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.TakeSnapshotRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.CancelBulkOperationRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.TakeSnapshotRequest}
+ * @return {!proto.wsman.CancelBulkOperationRequest}
  */
-proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.CancelBulkOperationRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3140,9 +19331,9 @@ proto.wsman.TakeSnapshotRequest.deserializeBinaryFromReader = function(msg, read
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.TakeSnapshotRequest.prototype.serializeBinary = function() {
+proto.wsman.CancelBulkOperationRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter(this, writer);
+  proto.wsman.CancelBulkOperationRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3150,11 +19341,11 @@ proto.wsman.TakeSnapshotRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.TakeSnapshotRequest} message
+ * @param {!proto.wsman.CancelBulkOperationRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.CancelBulkOperationRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -3170,13 +19361,13 @@ proto.wsman.TakeSnapshotRequest.serializeBinaryToWriter = function(message, writ
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.TakeSnapshotRequest.prototype.getId = function() {
+proto.wsman.CancelBulkOperationRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.TakeSnapshotRequest.prototype.setId = function(value) {
+proto.wsman.CancelBulkOperationRequest.prototype.setId = function(value) {
   jspb.Message.setProto3StringField(this, 1, value);
 };
 
@@ -3195,8 +19386,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.TakeSnapshotResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.TakeSnapshotResponse.toObject(opt_includeInstance, this);
+proto.wsman.CancelBulkOperationResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.CancelBulkOperationResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -3205,13 +19396,13 @@ proto.wsman.TakeSnapshotResponse.prototype.toObject = function(opt_includeInstan
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.TakeSnapshotResponse} msg The msg instance to transform.
+ * @param {!proto.wsman.CancelBulkOperationResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotResponse.toObject = function(includeInstance, msg) {
+proto.wsman.CancelBulkOperationResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    url: jspb.Message.getFieldWithDefault(msg, 1, "")
+    status: (f = msg.getStatus()) && proto.wsman.BulkOperationStatus.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -3225,23 +19416,23 @@ proto.wsman.TakeSnapshotResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.TakeSnapshotResponse}
+ * @return {!proto.wsman.CancelBulkOperationResponse}
  */
-proto.wsman.TakeSnapshotResponse.deserializeBinary = function(bytes) {
+proto.wsman.CancelBulkOperationResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.TakeSnapshotResponse;
-  return proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.CancelBulkOperationResponse;
+  return proto.wsman.CancelBulkOperationResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.TakeSnapshotResponse} msg The message object to deserialize into.
+ * @param {!proto.wsman.CancelBulkOperationResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.TakeSnapshotResponse}
+ * @return {!proto.wsman.CancelBulkOperationResponse}
  */
-proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.CancelBulkOperationResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3249,8 +19440,9 @@ proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader = function(msg, rea
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
+      var value = new proto.wsman.BulkOperationStatus;
+      reader.readMessage(value,proto.wsman.BulkOperationStatus.deserializeBinaryFromReader);
+      msg.setStatus(value);
       break;
     default:
       reader.skipField();
@@ -3265,9 +19457,9 @@ proto.wsman.TakeSnapshotResponse.deserializeBinaryFromReader = function(msg, rea
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.TakeSnapshotResponse.prototype.serializeBinary = function() {
+proto.wsman.CancelBulkOperationResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter(this, writer);
+  proto.wsman.CancelBulkOperationResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3275,34 +19467,53 @@ proto.wsman.TakeSnapshotResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.TakeSnapshotResponse} message
+ * @param {!proto.wsman.CancelBulkOperationResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.TakeSnapshotResponse.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.CancelBulkOperationResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getUrl();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getStatus();
+  if (f != null) {
+    writer.writeMessage(
       1,
-      f
+      f,
+      proto.wsman.BulkOperationStatus.serializeBinaryToWriter
     );
   }
 };
 
 
 /**
- * optional string url = 1;
- * @return {string}
+ * optional BulkOperationStatus status = 1;
+ * @return {?proto.wsman.BulkOperationStatus}
  */
-proto.wsman.TakeSnapshotResponse.prototype.getUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.CancelBulkOperationResponse.prototype.getStatus = function() {
+  return /** @type{?proto.wsman.BulkOperationStatus} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.BulkOperationStatus, 1));
 };
 
 
-/** @param {string} value */
-proto.wsman.TakeSnapshotResponse.prototype.setUrl = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+/** @param {?proto.wsman.BulkOperationStatus|undefined} value */
+proto.wsman.CancelBulkOperationResponse.prototype.setStatus = function(value) {
+  jspb.Message.setWrapperField(this, 1, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.CancelBulkOperationResponse.prototype.clearStatus = function() {
+  this.setStatus(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.CancelBulkOperationResponse.prototype.hasStatus = function() {
+  return jspb.Message.getField(this, 1) != null;
 };
 
 
@@ -3320,8 +19531,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.ControlAdmissionRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlAdmissionRequest.toObject(opt_includeInstance, this);
+proto.wsman.AuditRecord.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.AuditRecord.toObject(opt_includeInstance, this);
 };
 
 
@@ -3330,14 +19541,22 @@ proto.wsman.ControlAdmissionRequest.prototype.toObject = function(opt_includeIns
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlAdmissionRequest} msg The msg instance to transform.
+ * @param {!proto.wsman.AuditRecord} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.ControlAdmissionRequest.toObject = function(includeInstance, msg) {
+proto.wsman.AuditRecord.toObject = function(includeInstance, msg) {
   var f, obj = {
-    id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    level: jspb.Message.getFieldWithDefault(msg, 2, 0)
+    sequence: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    time: (f = msg.getTime()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    action: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    id: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
+    actor: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    source: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    peer: jspb.Message.getFieldWithDefault(msg, 8, ""),
+    parametersMap: (f = msg.getParametersMap()) ? f.toObject(includeInstance, undefined) : [],
+    error: jspb.Message.getFieldWithDefault(msg, 10, "")
   };
 
   if (includeInstance) {
@@ -3351,23 +19570,23 @@ proto.wsman.ControlAdmissionRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlAdmissionRequest}
+ * @return {!proto.wsman.AuditRecord}
  */
-proto.wsman.ControlAdmissionRequest.deserializeBinary = function(bytes) {
+proto.wsman.AuditRecord.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlAdmissionRequest;
-  return proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.AuditRecord;
+  return proto.wsman.AuditRecord.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.ControlAdmissionRequest} msg The message object to deserialize into.
+ * @param {!proto.wsman.AuditRecord} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlAdmissionRequest}
+ * @return {!proto.wsman.AuditRecord}
  */
-proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.AuditRecord.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3375,12 +19594,48 @@ proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg,
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setSequence(value);
+      break;
+    case 2:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setTime(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAction(value);
+      break;
+    case 4:
       var value = /** @type {string} */ (reader.readString());
       msg.setId(value);
       break;
-    case 2:
-      var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
-      msg.setLevel(value);
+    case 5:
+      var value = new proto.wsman.WorkspaceMetadata;
+      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
+      msg.setMetadata(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setActor(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSource(value);
+      break;
+    case 8:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPeer(value);
+      break;
+    case 9:
+      var value = msg.getParametersMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
+      break;
+    case 10:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setError(value);
       break;
     default:
       reader.skipField();
@@ -3392,172 +19647,297 @@ proto.wsman.ControlAdmissionRequest.deserializeBinaryFromReader = function(msg,
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.AuditRecord.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.AuditRecord.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.AuditRecord} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.AuditRecord.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSequence();
+  if (f !== 0) {
+    writer.writeUint64(
+      1,
+      f
+    );
+  }
+  f = message.getTime();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getAction();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getMetadata();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
+    );
+  }
+  f = message.getActor();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+  f = message.getSource();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+  f = message.getPeer();
+  if (f.length > 0) {
+    writer.writeString(
+      8,
+      f
+    );
+  }
+  f = message.getParametersMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(9, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+  f = message.getError();
+  if (f.length > 0) {
+    writer.writeString(
+      10,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional uint64 sequence = 1;
+ * @return {number}
+ */
+proto.wsman.AuditRecord.prototype.getSequence = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/** @param {number} value */
+proto.wsman.AuditRecord.prototype.setSequence = function(value) {
+  jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp time = 2;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.wsman.AuditRecord.prototype.getTime = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 2));
+};
+
+
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.AuditRecord.prototype.setTime = function(value) {
+  jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ */
+proto.wsman.AuditRecord.prototype.clearTime = function() {
+  this.setTime(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.AuditRecord.prototype.hasTime = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional string action = 3;
+ * @return {string}
+ */
+proto.wsman.AuditRecord.prototype.getAction = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.AuditRecord.prototype.setAction = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string id = 4;
+ * @return {string}
+ */
+proto.wsman.AuditRecord.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.AuditRecord.prototype.setId = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional WorkspaceMetadata metadata = 5;
+ * @return {?proto.wsman.WorkspaceMetadata}
+ */
+proto.wsman.AuditRecord.prototype.getMetadata = function() {
+  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
+    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 5));
+};
+
+
+/** @param {?proto.wsman.WorkspaceMetadata|undefined} value */
+proto.wsman.AuditRecord.prototype.setMetadata = function(value) {
+  jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
  */
-proto.wsman.ControlAdmissionRequest.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.wsman.AuditRecord.prototype.clearMetadata = function() {
+  this.setMetadata(undefined);
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlAdmissionRequest} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.wsman.ControlAdmissionRequest.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getId();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-  f = message.getLevel();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      2,
-      f
-    );
-  }
+proto.wsman.AuditRecord.prototype.hasMetadata = function() {
+  return jspb.Message.getField(this, 5) != null;
 };
 
 
 /**
- * optional string id = 1;
+ * optional string actor = 6;
  * @return {string}
  */
-proto.wsman.ControlAdmissionRequest.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.wsman.AuditRecord.prototype.getActor = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.ControlAdmissionRequest.prototype.setId = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+proto.wsman.AuditRecord.prototype.setActor = function(value) {
+  jspb.Message.setProto3StringField(this, 6, value);
 };
 
 
 /**
- * optional AdmissionLevel level = 2;
- * @return {!proto.wsman.AdmissionLevel}
+ * optional string source = 7;
+ * @return {string}
  */
-proto.wsman.ControlAdmissionRequest.prototype.getLevel = function() {
-  return /** @type {!proto.wsman.AdmissionLevel} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+proto.wsman.AuditRecord.prototype.getSource = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
 };
 
 
-/** @param {!proto.wsman.AdmissionLevel} value */
-proto.wsman.ControlAdmissionRequest.prototype.setLevel = function(value) {
-  jspb.Message.setProto3EnumField(this, 2, value);
+/** @param {string} value */
+proto.wsman.AuditRecord.prototype.setSource = function(value) {
+  jspb.Message.setProto3StringField(this, 7, value);
 };
 
 
-
-
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto suitable for use in Soy templates.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
- * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
- *     for transitional soy proto support: http://goto/soy-param-migration
- * @return {!Object}
+ * optional string peer = 8;
+ * @return {string}
  */
-proto.wsman.ControlAdmissionResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.ControlAdmissionResponse.toObject(opt_includeInstance, this);
+proto.wsman.AuditRecord.prototype.getPeer = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
 };
 
 
-/**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Whether to include the JSPB
- *     instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.wsman.ControlAdmissionResponse} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
- */
-proto.wsman.ControlAdmissionResponse.toObject = function(includeInstance, msg) {
-  var f, obj = {
-
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+/** @param {string} value */
+proto.wsman.AuditRecord.prototype.setPeer = function(value) {
+  jspb.Message.setProto3StringField(this, 8, value);
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.ControlAdmissionResponse}
+ * map<string, string> parameters = 9;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
  */
-proto.wsman.ControlAdmissionResponse.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.ControlAdmissionResponse;
-  return proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader(msg, reader);
+proto.wsman.AuditRecord.prototype.getParametersMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 9, opt_noLazyCreate,
+      null));
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.wsman.ControlAdmissionResponse} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.ControlAdmissionResponse}
+ * Clears values from the map. The map will be non-null.
  */
-proto.wsman.ControlAdmissionResponse.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.wsman.AuditRecord.prototype.clearParametersMap = function() {
+  this.getParametersMap().clear();
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * optional string error = 10;
+ * @return {string}
  */
-proto.wsman.ControlAdmissionResponse.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.wsman.AuditRecord.prototype.getError = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
 };
 
 
-/**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.ControlAdmissionResponse} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
- */
-proto.wsman.ControlAdmissionResponse.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
+/** @param {string} value */
+proto.wsman.AuditRecord.prototype.setError = function(value) {
+  jspb.Message.setProto3StringField(this, 10, value);
 };
 
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.QueryAuditLogRequest.repeatedFields_ = [4];
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -3571,8 +19951,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceStatus.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceStatus.toObject(opt_includeInstance, this);
+proto.wsman.QueryAuditLogRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.QueryAuditLogRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -3581,21 +19961,20 @@ proto.wsman.WorkspaceStatus.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceStatus} msg The msg instance to transform.
+ * @param {!proto.wsman.QueryAuditLogRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.toObject = function(includeInstance, msg) {
+proto.wsman.QueryAuditLogRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
     id: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    metadata: (f = msg.getMetadata()) && proto.wsman.WorkspaceMetadata.toObject(includeInstance, f),
-    spec: (f = msg.getSpec()) && proto.wsman.WorkspaceSpec.toObject(includeInstance, f),
-    phase: jspb.Message.getFieldWithDefault(msg, 4, 0),
-    conditions: (f = msg.getConditions()) && proto.wsman.WorkspaceConditions.toObject(includeInstance, f),
-    message: jspb.Message.getFieldWithDefault(msg, 6, ""),
-    repo: (f = msg.getRepo()) && content$service$api_initializer_pb.GitStatus.toObject(includeInstance, f),
-    runtime: (f = msg.getRuntime()) && proto.wsman.WorkspaceRuntimeInfo.toObject(includeInstance, f),
-    auth: (f = msg.getAuth()) && proto.wsman.WorkspaceAuthentication.toObject(includeInstance, f)
+    metaId: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    owner: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    actionsList: jspb.Message.getRepeatedField(msg, 4),
+    since: (f = msg.getSince()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    until: (f = msg.getUntil()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    afterSequence: jspb.Message.getFieldWithDefault(msg, 7, 0),
+    limit: jspb.Message.getFieldWithDefault(msg, 8, 0)
   };
 
   if (includeInstance) {
@@ -3609,23 +19988,23 @@ proto.wsman.WorkspaceStatus.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceStatus}
+ * @return {!proto.wsman.QueryAuditLogRequest}
  */
-proto.wsman.WorkspaceStatus.deserializeBinary = function(bytes) {
+proto.wsman.QueryAuditLogRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceStatus;
-  return proto.wsman.WorkspaceStatus.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.QueryAuditLogRequest;
+  return proto.wsman.QueryAuditLogRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceStatus} msg The message object to deserialize into.
+ * @param {!proto.wsman.QueryAuditLogRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceStatus}
+ * @return {!proto.wsman.QueryAuditLogRequest}
  */
-proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.QueryAuditLogRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3637,42 +20016,34 @@ proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader)
       msg.setId(value);
       break;
     case 2:
-      var value = new proto.wsman.WorkspaceMetadata;
-      reader.readMessage(value,proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader);
-      msg.setMetadata(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMetaId(value);
       break;
     case 3:
-      var value = new proto.wsman.WorkspaceSpec;
-      reader.readMessage(value,proto.wsman.WorkspaceSpec.deserializeBinaryFromReader);
-      msg.setSpec(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOwner(value);
       break;
     case 4:
-      var value = /** @type {!proto.wsman.WorkspacePhase} */ (reader.readEnum());
-      msg.setPhase(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.addActions(value);
       break;
     case 5:
-      var value = new proto.wsman.WorkspaceConditions;
-      reader.readMessage(value,proto.wsman.WorkspaceConditions.deserializeBinaryFromReader);
-      msg.setConditions(value);
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setSince(value);
       break;
     case 6:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setMessage(value);
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setUntil(value);
       break;
     case 7:
-      var value = new content$service$api_initializer_pb.GitStatus;
-      reader.readMessage(value,content$service$api_initializer_pb.GitStatus.deserializeBinaryFromReader);
-      msg.setRepo(value);
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setAfterSequence(value);
       break;
     case 8:
-      var value = new proto.wsman.WorkspaceRuntimeInfo;
-      reader.readMessage(value,proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader);
-      msg.setRuntime(value);
-      break;
-    case 9:
-      var value = new proto.wsman.WorkspaceAuthentication;
-      reader.readMessage(value,proto.wsman.WorkspaceAuthentication.deserializeBinaryFromReader);
-      msg.setAuth(value);
+      var value = /** @type {number} */ (reader.readUint32());
+      msg.setLimit(value);
       break;
     default:
       reader.skipField();
@@ -3687,9 +20058,9 @@ proto.wsman.WorkspaceStatus.deserializeBinaryFromReader = function(msg, reader)
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceStatus.prototype.serializeBinary = function() {
+proto.wsman.QueryAuditLogRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceStatus.serializeBinaryToWriter(this, writer);
+  proto.wsman.QueryAuditLogRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3697,11 +20068,11 @@ proto.wsman.WorkspaceStatus.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceStatus} message
+ * @param {!proto.wsman.QueryAuditLogRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.QueryAuditLogRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
   f = message.getId();
   if (f.length > 0) {
@@ -3710,66 +20081,55 @@ proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer)
       f
     );
   }
-  f = message.getMetadata();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getMetaId();
+  if (f.length > 0) {
+    writer.writeString(
       2,
-      f,
-      proto.wsman.WorkspaceMetadata.serializeBinaryToWriter
+      f
     );
   }
-  f = message.getSpec();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getOwner();
+  if (f.length > 0) {
+    writer.writeString(
       3,
-      f,
-      proto.wsman.WorkspaceSpec.serializeBinaryToWriter
+      f
     );
   }
-  f = message.getPhase();
-  if (f !== 0.0) {
-    writer.writeEnum(
+  f = message.getActionsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
       4,
       f
     );
   }
-  f = message.getConditions();
+  f = message.getSince();
   if (f != null) {
     writer.writeMessage(
       5,
       f,
-      proto.wsman.WorkspaceConditions.serializeBinaryToWriter
-    );
-  }
-  f = message.getMessage();
-  if (f.length > 0) {
-    writer.writeString(
-      6,
-      f
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
-  f = message.getRepo();
+  f = message.getUntil();
   if (f != null) {
     writer.writeMessage(
-      7,
+      6,
       f,
-      content$service$api_initializer_pb.GitStatus.serializeBinaryToWriter
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
-  f = message.getRuntime();
-  if (f != null) {
-    writer.writeMessage(
-      8,
-      f,
-      proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter
+  f = message.getAfterSequence();
+  if (f !== 0) {
+    writer.writeUint64(
+      7,
+      f
     );
   }
-  f = message.getAuth();
-  if (f != null) {
-    writer.writeMessage(
-      9,
-      f,
-      proto.wsman.WorkspaceAuthentication.serializeBinaryToWriter
+  f = message.getLimit();
+  if (f !== 0) {
+    writer.writeUint32(
+      8,
+      f
     );
   }
 };
@@ -3779,110 +20139,91 @@ proto.wsman.WorkspaceStatus.serializeBinaryToWriter = function(message, writer)
  * optional string id = 1;
  * @return {string}
  */
-proto.wsman.WorkspaceStatus.prototype.getId = function() {
+proto.wsman.QueryAuditLogRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.WorkspaceStatus.prototype.setId = function(value) {
+proto.wsman.QueryAuditLogRequest.prototype.setId = function(value) {
   jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional WorkspaceMetadata metadata = 2;
- * @return {?proto.wsman.WorkspaceMetadata}
+ * optional string meta_id = 2;
+ * @return {string}
  */
-proto.wsman.WorkspaceStatus.prototype.getMetadata = function() {
-  return /** @type{?proto.wsman.WorkspaceMetadata} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceMetadata, 2));
+proto.wsman.QueryAuditLogRequest.prototype.getMetaId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
-/** @param {?proto.wsman.WorkspaceMetadata|undefined} value */
-proto.wsman.WorkspaceStatus.prototype.setMetadata = function(value) {
-  jspb.Message.setWrapperField(this, 2, value);
+/** @param {string} value */
+proto.wsman.QueryAuditLogRequest.prototype.setMetaId = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
+ * optional string owner = 3;
+ * @return {string}
  */
-proto.wsman.WorkspaceStatus.prototype.clearMetadata = function() {
-  this.setMetadata(undefined);
+proto.wsman.QueryAuditLogRequest.prototype.getOwner = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.wsman.WorkspaceStatus.prototype.hasMetadata = function() {
-  return jspb.Message.getField(this, 2) != null;
+/** @param {string} value */
+proto.wsman.QueryAuditLogRequest.prototype.setOwner = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * optional WorkspaceSpec spec = 3;
- * @return {?proto.wsman.WorkspaceSpec}
+ * repeated string actions = 4;
+ * @return {!Array<string>}
  */
-proto.wsman.WorkspaceStatus.prototype.getSpec = function() {
-  return /** @type{?proto.wsman.WorkspaceSpec} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceSpec, 3));
-};
-
-
-/** @param {?proto.wsman.WorkspaceSpec|undefined} value */
-proto.wsman.WorkspaceStatus.prototype.setSpec = function(value) {
-  jspb.Message.setWrapperField(this, 3, value);
+proto.wsman.QueryAuditLogRequest.prototype.getActionsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 4));
 };
 
 
-/**
- * Clears the message field making it undefined.
- */
-proto.wsman.WorkspaceStatus.prototype.clearSpec = function() {
-  this.setSpec(undefined);
+/** @param {!Array<string>} value */
+proto.wsman.QueryAuditLogRequest.prototype.setActionsList = function(value) {
+  jspb.Message.setField(this, 4, value || []);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @param {number=} opt_index
  */
-proto.wsman.WorkspaceStatus.prototype.hasSpec = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.wsman.QueryAuditLogRequest.prototype.addActions = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 4, value, opt_index);
 };
 
 
 /**
- * optional WorkspacePhase phase = 4;
- * @return {!proto.wsman.WorkspacePhase}
+ * Clears the list making it empty but non-null.
  */
-proto.wsman.WorkspaceStatus.prototype.getPhase = function() {
-  return /** @type {!proto.wsman.WorkspacePhase} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
-};
-
-
-/** @param {!proto.wsman.WorkspacePhase} value */
-proto.wsman.WorkspaceStatus.prototype.setPhase = function(value) {
-  jspb.Message.setProto3EnumField(this, 4, value);
+proto.wsman.QueryAuditLogRequest.prototype.clearActionsList = function() {
+  this.setActionsList([]);
 };
 
 
 /**
- * optional WorkspaceConditions conditions = 5;
- * @return {?proto.wsman.WorkspaceConditions}
+ * optional google.protobuf.Timestamp since = 5;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.wsman.WorkspaceStatus.prototype.getConditions = function() {
-  return /** @type{?proto.wsman.WorkspaceConditions} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceConditions, 5));
+proto.wsman.QueryAuditLogRequest.prototype.getSince = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 5));
 };
 
 
-/** @param {?proto.wsman.WorkspaceConditions|undefined} value */
-proto.wsman.WorkspaceStatus.prototype.setConditions = function(value) {
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.QueryAuditLogRequest.prototype.setSince = function(value) {
   jspb.Message.setWrapperField(this, 5, value);
 };
 
@@ -3890,8 +20231,8 @@ proto.wsman.WorkspaceStatus.prototype.setConditions = function(value) {
 /**
  * Clears the message field making it undefined.
  */
-proto.wsman.WorkspaceStatus.prototype.clearConditions = function() {
-  this.setConditions(undefined);
+proto.wsman.QueryAuditLogRequest.prototype.clearSince = function() {
+  this.setSince(undefined);
 };
 
 
@@ -3899,80 +20240,32 @@ proto.wsman.WorkspaceStatus.prototype.clearConditions = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.WorkspaceStatus.prototype.hasConditions = function() {
+proto.wsman.QueryAuditLogRequest.prototype.hasSince = function() {
   return jspb.Message.getField(this, 5) != null;
 };
 
 
 /**
- * optional string message = 6;
- * @return {string}
- */
-proto.wsman.WorkspaceStatus.prototype.getMessage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
-};
-
-
-/** @param {string} value */
-proto.wsman.WorkspaceStatus.prototype.setMessage = function(value) {
-  jspb.Message.setProto3StringField(this, 6, value);
-};
-
-
-/**
- * optional contentservice.GitStatus repo = 7;
- * @return {?proto.contentservice.GitStatus}
- */
-proto.wsman.WorkspaceStatus.prototype.getRepo = function() {
-  return /** @type{?proto.contentservice.GitStatus} */ (
-    jspb.Message.getWrapperField(this, content$service$api_initializer_pb.GitStatus, 7));
-};
-
-
-/** @param {?proto.contentservice.GitStatus|undefined} value */
-proto.wsman.WorkspaceStatus.prototype.setRepo = function(value) {
-  jspb.Message.setWrapperField(this, 7, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- */
-proto.wsman.WorkspaceStatus.prototype.clearRepo = function() {
-  this.setRepo(undefined);
-};
-
-
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.wsman.WorkspaceStatus.prototype.hasRepo = function() {
-  return jspb.Message.getField(this, 7) != null;
-};
-
-
-/**
- * optional WorkspaceRuntimeInfo runtime = 8;
- * @return {?proto.wsman.WorkspaceRuntimeInfo}
+ * optional google.protobuf.Timestamp until = 6;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.wsman.WorkspaceStatus.prototype.getRuntime = function() {
-  return /** @type{?proto.wsman.WorkspaceRuntimeInfo} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceRuntimeInfo, 8));
+proto.wsman.QueryAuditLogRequest.prototype.getUntil = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 6));
 };
 
 
-/** @param {?proto.wsman.WorkspaceRuntimeInfo|undefined} value */
-proto.wsman.WorkspaceStatus.prototype.setRuntime = function(value) {
-  jspb.Message.setWrapperField(this, 8, value);
+/** @param {?proto.google.protobuf.Timestamp|undefined} value */
+proto.wsman.QueryAuditLogRequest.prototype.setUntil = function(value) {
+  jspb.Message.setWrapperField(this, 6, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
  */
-proto.wsman.WorkspaceStatus.prototype.clearRuntime = function() {
-  this.setRuntime(undefined);
+proto.wsman.QueryAuditLogRequest.prototype.clearUntil = function() {
+  this.setUntil(undefined);
 };
 
 
@@ -3980,41 +20273,38 @@ proto.wsman.WorkspaceStatus.prototype.clearRuntime = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.wsman.WorkspaceStatus.prototype.hasRuntime = function() {
-  return jspb.Message.getField(this, 8) != null;
+proto.wsman.QueryAuditLogRequest.prototype.hasUntil = function() {
+  return jspb.Message.getField(this, 6) != null;
 };
 
 
 /**
- * optional WorkspaceAuthentication auth = 9;
- * @return {?proto.wsman.WorkspaceAuthentication}
+ * optional uint64 after_sequence = 7;
+ * @return {number}
  */
-proto.wsman.WorkspaceStatus.prototype.getAuth = function() {
-  return /** @type{?proto.wsman.WorkspaceAuthentication} */ (
-    jspb.Message.getWrapperField(this, proto.wsman.WorkspaceAuthentication, 9));
+proto.wsman.QueryAuditLogRequest.prototype.getAfterSequence = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
 };
 
 
-/** @param {?proto.wsman.WorkspaceAuthentication|undefined} value */
-proto.wsman.WorkspaceStatus.prototype.setAuth = function(value) {
-  jspb.Message.setWrapperField(this, 9, value);
+/** @param {number} value */
+proto.wsman.QueryAuditLogRequest.prototype.setAfterSequence = function(value) {
+  jspb.Message.setProto3IntField(this, 7, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
+ * optional uint32 limit = 8;
+ * @return {number}
  */
-proto.wsman.WorkspaceStatus.prototype.clearAuth = function() {
-  this.setAuth(undefined);
+proto.wsman.QueryAuditLogRequest.prototype.getLimit = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 8, 0));
 };
 
 
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.wsman.WorkspaceStatus.prototype.hasAuth = function() {
-  return jspb.Message.getField(this, 9) != null;
+/** @param {number} value */
+proto.wsman.QueryAuditLogRequest.prototype.setLimit = function(value) {
+  jspb.Message.setProto3IntField(this, 8, value);
 };
 
 
@@ -4024,7 +20314,7 @@ proto.wsman.WorkspaceStatus.prototype.hasAuth = function() {
  * @private {!Array<number>}
  * @const
  */
-proto.wsman.WorkspaceSpec.repeatedFields_ = [5];
+proto.wsman.QueryAuditLogResponse.repeatedFields_ = [1];
 
 
 
@@ -4039,8 +20329,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     for transitional soy proto support: http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.wsman.WorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
-  return proto.wsman.WorkspaceSpec.toObject(opt_includeInstance, this);
+proto.wsman.QueryAuditLogResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.QueryAuditLogResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -4049,20 +20339,15 @@ proto.wsman.WorkspaceSpec.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Whether to include the JSPB
  *     instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.wsman.WorkspaceSpec} msg The msg instance to transform.
+ * @param {!proto.wsman.QueryAuditLogResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceSpec.toObject = function(includeInstance, msg) {
+proto.wsman.QueryAuditLogResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    workspaceImage: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    ideImage: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    headless: jspb.Message.getFieldWithDefault(msg, 3, false),
-    url: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    exposedPortsList: jspb.Message.toObjectList(msg.getExposedPortsList(),
-    proto.wsman.PortSpec.toObject, includeInstance),
-    type: jspb.Message.getFieldWithDefault(msg, 6, 0),
-    timeout: jspb.Message.getFieldWithDefault(msg, 7, "")
+    recordsList: jspb.Message.toObjectList(msg.getRecordsList(),
+    proto.wsman.AuditRecord.toObject, includeInstance),
+    more: jspb.Message.getFieldWithDefault(msg, 2, false)
   };
 
   if (includeInstance) {
@@ -4076,23 +20361,23 @@ proto.wsman.WorkspaceSpec.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.wsman.WorkspaceSpec}
+ * @return {!proto.wsman.QueryAuditLogResponse}
  */
-proto.wsman.WorkspaceSpec.deserializeBinary = function(bytes) {
+proto.wsman.QueryAuditLogResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.wsman.WorkspaceSpec;
-  return proto.wsman.WorkspaceSpec.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.wsman.QueryAuditLogResponse;
+  return proto.wsman.QueryAuditLogResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.wsman.WorkspaceSpec} msg The message object to deserialize into.
+ * @param {!proto.wsman.QueryAuditLogResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.wsman.WorkspaceSpec}
+ * @return {!proto.wsman.QueryAuditLogResponse}
  */
-proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
+proto.wsman.QueryAuditLogResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -4100,33 +20385,13 @@ proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setWorkspaceImage(value);
+      var value = new proto.wsman.AuditRecord;
+      reader.readMessage(value,proto.wsman.AuditRecord.deserializeBinaryFromReader);
+      msg.addRecords(value);
       break;
     case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setIdeImage(value);
-      break;
-    case 3:
       var value = /** @type {boolean} */ (reader.readBool());
-      msg.setHeadless(value);
-      break;
-    case 4:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrl(value);
-      break;
-    case 5:
-      var value = new proto.wsman.PortSpec;
-      reader.readMessage(value,proto.wsman.PortSpec.deserializeBinaryFromReader);
-      msg.addExposedPorts(value);
-      break;
-    case 6:
-      var value = /** @type {!proto.wsman.WorkspaceType} */ (reader.readEnum());
-      msg.setType(value);
-      break;
-    case 7:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setTimeout(value);
+      msg.setMore(value);
       break;
     default:
       reader.skipField();
@@ -4141,9 +20406,9 @@ proto.wsman.WorkspaceSpec.deserializeBinaryFromReader = function(msg, reader) {
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceSpec.prototype.serializeBinary = function() {
+proto.wsman.QueryAuditLogResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.wsman.WorkspaceSpec.serializeBinaryToWriter(this, writer);
+  proto.wsman.QueryAuditLogResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4151,59 +20416,24 @@ proto.wsman.WorkspaceSpec.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.wsman.WorkspaceSpec} message
+ * @param {!proto.wsman.QueryAuditLogResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.wsman.WorkspaceSpec.serializeBinaryToWriter = function(message, writer) {
+proto.wsman.QueryAuditLogResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getWorkspaceImage();
+  f = message.getRecordsList();
   if (f.length > 0) {
-    writer.writeString(
+    writer.writeRepeatedMessage(
       1,
-      f
-    );
-  }
-  f = message.getIdeImage();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
-      f
+      f,
+      proto.wsman.AuditRecord.serializeBinaryToWriter
     );
   }
-  f = message.getHeadless();
+  f = message.getMore();
   if (f) {
     writer.writeBool(
-      3,
-      f
-    );
-  }
-  f = message.getUrl();
-  if (f.length > 0) {
-    writer.writeString(
-      4,
-      f
-    );
-  }
-  f = message.getExposedPortsList();
-  if (f.length > 0) {
-    writer.writeRepeatedMessage(
-      5,
-      f,
-      proto.wsman.PortSpec.serializeBinaryToWriter
-    );
-  }
-  f = message.getType();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      6,
-      f
-    );
-  }
-  f = message.getTimeout();
-  if (f.length > 0) {
-    writer.writeString(
-      7,
+      2,
       f
     );
   }
@@ -4211,128 +20441,178 @@ proto.wsman.WorkspaceSpec.serializeBinaryToWriter = function(message, writer) {
 
 
 /**
- * optional string workspace_image = 1;
- * @return {string}
- */
-proto.wsman.WorkspaceSpec.prototype.getWorkspaceImage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+ * repeated AuditRecord records = 1;
+ * @return {!Array<!proto.wsman.AuditRecord>}
+ */
+proto.wsman.QueryAuditLogResponse.prototype.getRecordsList = function() {
+  return /** @type{!Array<!proto.wsman.AuditRecord>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.AuditRecord, 1));
 };
 
 
-/** @param {string} value */
-proto.wsman.WorkspaceSpec.prototype.setWorkspaceImage = function(value) {
-  jspb.Message.setProto3StringField(this, 1, value);
+/** @param {!Array<!proto.wsman.AuditRecord>} value */
+proto.wsman.QueryAuditLogResponse.prototype.setRecordsList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 1, value);
 };
 
 
 /**
- * optional string ide_image = 2;
- * @return {string}
+ * @param {!proto.wsman.AuditRecord=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.AuditRecord}
  */
-proto.wsman.WorkspaceSpec.prototype.getIdeImage = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.wsman.QueryAuditLogResponse.prototype.addRecords = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.wsman.AuditRecord, opt_index);
 };
 
 
-/** @param {string} value */
-proto.wsman.WorkspaceSpec.prototype.setIdeImage = function(value) {
-  jspb.Message.setProto3StringField(this, 2, value);
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.QueryAuditLogResponse.prototype.clearRecordsList = function() {
+  this.setRecordsList([]);
 };
 
 
 /**
- * optional bool headless = 3;
+ * optional bool more = 2;
  * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
  * You should avoid comparisons like {@code val === true/false} in those cases.
  * @return {boolean}
  */
-proto.wsman.WorkspaceSpec.prototype.getHeadless = function() {
-  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 3, false));
+proto.wsman.QueryAuditLogResponse.prototype.getMore = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 2, false));
 };
 
 
 /** @param {boolean} value */
-proto.wsman.WorkspaceSpec.prototype.setHeadless = function(value) {
-  jspb.Message.setProto3BooleanField(this, 3, value);
+proto.wsman.QueryAuditLogResponse.prototype.setMore = function(value) {
+  jspb.Message.setProto3BooleanField(this, 2, value);
 };
 
 
-/**
- * optional string url = 4;
- * @return {string}
- */
-proto.wsman.WorkspaceSpec.prototype.getUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
-};
-
 
-/** @param {string} value */
-proto.wsman.WorkspaceSpec.prototype.setUrl = function(value) {
-  jspb.Message.setProto3StringField(this, 4, value);
-};
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * repeated PortSpec exposed_ports = 5;
- * @return {!Array<!proto.wsman.PortSpec>}
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.wsman.WorkspaceSpec.prototype.getExposedPortsList = function() {
-  return /** @type{!Array<!proto.wsman.PortSpec>} */ (
-    jspb.Message.getRepeatedWrapperField(this, proto.wsman.PortSpec, 5));
+proto.wsman.SubscribeAuditLogRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.SubscribeAuditLogRequest.toObject(opt_includeInstance, this);
 };
 
 
-/** @param {!Array<!proto.wsman.PortSpec>} value */
-proto.wsman.WorkspaceSpec.prototype.setExposedPortsList = function(value) {
-  jspb.Message.setRepeatedWrapperField(this, 5, value);
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.SubscribeAuditLogRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeAuditLogRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    afterSequence: jspb.Message.getFieldWithDefault(msg, 1, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * @param {!proto.wsman.PortSpec=} opt_value
- * @param {number=} opt_index
- * @return {!proto.wsman.PortSpec}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.SubscribeAuditLogRequest}
  */
-proto.wsman.WorkspaceSpec.prototype.addExposedPorts = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.wsman.PortSpec, opt_index);
+proto.wsman.SubscribeAuditLogRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.SubscribeAuditLogRequest;
+  return proto.wsman.SubscribeAuditLogRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.SubscribeAuditLogRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.SubscribeAuditLogRequest}
  */
-proto.wsman.WorkspaceSpec.prototype.clearExposedPortsList = function() {
-  this.setExposedPortsList([]);
+proto.wsman.SubscribeAuditLogRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readUint64());
+      msg.setAfterSequence(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * optional WorkspaceType type = 6;
- * @return {!proto.wsman.WorkspaceType}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.wsman.WorkspaceSpec.prototype.getType = function() {
-  return /** @type {!proto.wsman.WorkspaceType} */ (jspb.Message.getFieldWithDefault(this, 6, 0));
+proto.wsman.SubscribeAuditLogRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.SubscribeAuditLogRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
-/** @param {!proto.wsman.WorkspaceType} value */
-proto.wsman.WorkspaceSpec.prototype.setType = function(value) {
-  jspb.Message.setProto3EnumField(this, 6, value);
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.SubscribeAuditLogRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.SubscribeAuditLogRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getAfterSequence();
+  if (f !== 0) {
+    writer.writeUint64(
+      1,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string timeout = 7;
- * @return {string}
+ * optional uint64 after_sequence = 1;
+ * @return {number}
  */
-proto.wsman.WorkspaceSpec.prototype.getTimeout = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+proto.wsman.SubscribeAuditLogRequest.prototype.getAfterSequence = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
-/** @param {string} value */
-proto.wsman.WorkspaceSpec.prototype.setTimeout = function(value) {
-  jspb.Message.setProto3StringField(this, 7, value);
+/** @param {number} value */
+proto.wsman.SubscribeAuditLogRequest.prototype.setAfterSequence = function(value) {
+  jspb.Message.setProto3IntField(this, 1, value);
 };
 
 
@@ -4369,7 +20649,8 @@ proto.wsman.PortSpec.toObject = function(includeInstance, msg) {
     port: jspb.Message.getFieldWithDefault(msg, 1, 0),
     target: jspb.Message.getFieldWithDefault(msg, 2, 0),
     visibility: jspb.Message.getFieldWithDefault(msg, 3, 0),
-    url: jspb.Message.getFieldWithDefault(msg, 4, "")
+    url: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    permissiveCors: jspb.Message.getFieldWithDefault(msg, 5, false)
   };
 
   if (includeInstance) {
@@ -4422,6 +20703,10 @@ proto.wsman.PortSpec.deserializeBinaryFromReader = function(msg, reader) {
       var value = /** @type {string} */ (reader.readString());
       msg.setUrl(value);
       break;
+    case 5:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setPermissiveCors(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -4479,6 +20764,13 @@ proto.wsman.PortSpec.serializeBinaryToWriter = function(message, writer) {
       f
     );
   }
+  f = message.getPermissiveCors();
+  if (f) {
+    writer.writeBool(
+      5,
+      f
+    );
+  }
 };
 
 
@@ -4542,6 +20834,23 @@ proto.wsman.PortSpec.prototype.setUrl = function(value) {
 };
 
 
+/**
+ * optional bool permissive_cors = 5;
+ * Note that Boolean fields may be set to 0/1 when serialized from a Java server.
+ * You should avoid comparisons like {@code val === true/false} in those cases.
+ * @return {boolean}
+ */
+proto.wsman.PortSpec.prototype.getPermissiveCors = function() {
+  return /** @type {boolean} */ (jspb.Message.getFieldWithDefault(this, 5, false));
+};
+
+
+/** @param {boolean} value */
+proto.wsman.PortSpec.prototype.setPermissiveCors = function(value) {
+  jspb.Message.setProto3BooleanField(this, 5, value);
+};
+
+
 
 
 
@@ -4580,7 +20889,9 @@ proto.wsman.WorkspaceConditions.toObject = function(includeInstance, msg) {
     finalBackupComplete: jspb.Message.getFieldWithDefault(msg, 6, 0),
     deployed: jspb.Message.getFieldWithDefault(msg, 7, 0),
     networkNotReady: jspb.Message.getFieldWithDefault(msg, 8, 0),
-    firstUserActivity: (f = msg.getFirstUserActivity()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+    firstUserActivity: (f = msg.getFirstUserActivity()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    paused: jspb.Message.getFieldWithDefault(msg, 10, 0),
+    migrateTo: jspb.Message.getFieldWithDefault(msg, 11, "")
   };
 
   if (includeInstance) {
@@ -4654,6 +20965,14 @@ proto.wsman.WorkspaceConditions.deserializeBinaryFromReader = function(msg, read
       reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
       msg.setFirstUserActivity(value);
       break;
+    case 10:
+      var value = /** @type {!proto.wsman.WorkspaceConditionBool} */ (reader.readEnum());
+      msg.setPaused(value);
+      break;
+    case 11:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMigrateTo(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -4747,6 +21066,20 @@ proto.wsman.WorkspaceConditions.serializeBinaryToWriter = function(message, writ
       google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
+  f = message.getPaused();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      10,
+      f
+    );
+  }
+  f = message.getMigrateTo();
+  if (f.length > 0) {
+    writer.writeString(
+      11,
+      f
+    );
+  }
 };
 
 
@@ -4903,6 +21236,36 @@ proto.wsman.WorkspaceConditions.prototype.hasFirstUserActivity = function() {
 };
 
 
+/**
+ * optional WorkspaceConditionBool paused = 10;
+ * @return {!proto.wsman.WorkspaceConditionBool}
+ */
+proto.wsman.WorkspaceConditions.prototype.getPaused = function() {
+  return /** @type {!proto.wsman.WorkspaceConditionBool} */ (jspb.Message.getFieldWithDefault(this, 10, 0));
+};
+
+
+/** @param {!proto.wsman.WorkspaceConditionBool} value */
+proto.wsman.WorkspaceConditions.prototype.setPaused = function(value) {
+  jspb.Message.setProto3EnumField(this, 10, value);
+};
+
+
+/**
+ * optional string migrate_to = 11;
+ * @return {string}
+ */
+proto.wsman.WorkspaceConditions.prototype.getMigrateTo = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 11, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceConditions.prototype.setMigrateTo = function(value) {
+  jspb.Message.setProto3StringField(this, 11, value);
+};
+
+
 
 
 
@@ -4935,7 +21298,9 @@ proto.wsman.WorkspaceMetadata.toObject = function(includeInstance, msg) {
   var f, obj = {
     owner: jspb.Message.getFieldWithDefault(msg, 1, ""),
     metaId: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    startedAt: (f = msg.getStartedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+    startedAt: (f = msg.getStartedAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+    groupId: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    project: jspb.Message.getFieldWithDefault(msg, 5, "")
   };
 
   if (includeInstance) {
@@ -4985,6 +21350,14 @@ proto.wsman.WorkspaceMetadata.deserializeBinaryFromReader = function(msg, reader
       reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
       msg.setStartedAt(value);
       break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupId(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProject(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -5036,6 +21409,20 @@ proto.wsman.WorkspaceMetadata.serializeBinaryToWriter = function(message, writer
       google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
+  f = message.getGroupId();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getProject();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
 };
 
 
@@ -5102,6 +21489,36 @@ proto.wsman.WorkspaceMetadata.prototype.hasStartedAt = function() {
 };
 
 
+/**
+ * optional string group_id = 4;
+ * @return {string}
+ */
+proto.wsman.WorkspaceMetadata.prototype.getGroupId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceMetadata.prototype.setGroupId = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional string project = 5;
+ * @return {string}
+ */
+proto.wsman.WorkspaceMetadata.prototype.getProject = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceMetadata.prototype.setProject = function(value) {
+  jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
 
 
 
@@ -5134,7 +21551,8 @@ proto.wsman.WorkspaceRuntimeInfo.toObject = function(includeInstance, msg) {
   var f, obj = {
     nodeName: jspb.Message.getFieldWithDefault(msg, 1, ""),
     podName: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    nodeIp: jspb.Message.getFieldWithDefault(msg, 3, "")
+    nodeIp: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    podIp: jspb.Message.getFieldWithDefault(msg, 4, "")
   };
 
   if (includeInstance) {
@@ -5183,6 +21601,10 @@ proto.wsman.WorkspaceRuntimeInfo.deserializeBinaryFromReader = function(msg, rea
       var value = /** @type {string} */ (reader.readString());
       msg.setNodeIp(value);
       break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPodIp(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -5233,6 +21655,13 @@ proto.wsman.WorkspaceRuntimeInfo.serializeBinaryToWriter = function(message, wri
       f
     );
   }
+  f = message.getPodIp();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
 };
 
 
@@ -5261,23 +21690,38 @@ proto.wsman.WorkspaceRuntimeInfo.prototype.getPodName = function() {
 
 
 /** @param {string} value */
-proto.wsman.WorkspaceRuntimeInfo.prototype.setPodName = function(value) {
-  jspb.Message.setProto3StringField(this, 2, value);
+proto.wsman.WorkspaceRuntimeInfo.prototype.setPodName = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string node_ip = 3;
+ * @return {string}
+ */
+proto.wsman.WorkspaceRuntimeInfo.prototype.getNodeIp = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceRuntimeInfo.prototype.setNodeIp = function(value) {
+  jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * optional string node_ip = 3;
+ * optional string pod_ip = 4;
  * @return {string}
  */
-proto.wsman.WorkspaceRuntimeInfo.prototype.getNodeIp = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+proto.wsman.WorkspaceRuntimeInfo.prototype.getPodIp = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
 };
 
 
 /** @param {string} value */
-proto.wsman.WorkspaceRuntimeInfo.prototype.setNodeIp = function(value) {
-  jspb.Message.setProto3StringField(this, 3, value);
+proto.wsman.WorkspaceRuntimeInfo.prototype.setPodIp = function(value) {
+  jspb.Message.setProto3StringField(this, 4, value);
 };
 
 
@@ -5439,7 +21883,7 @@ proto.wsman.WorkspaceAuthentication.prototype.setOwnerToken = function(value) {
  * @private {!Array<number>}
  * @const
  */
-proto.wsman.StartWorkspaceSpec.repeatedFields_ = [3,5,6];
+proto.wsman.StartWorkspaceSpec.repeatedFields_ = [3,5,6,13,15];
 
 
 
@@ -5482,7 +21926,12 @@ proto.wsman.StartWorkspaceSpec.toObject = function(includeInstance, msg) {
     workspaceLocation: jspb.Message.getFieldWithDefault(msg, 8, ""),
     git: (f = msg.getGit()) && proto.wsman.GitSpec.toObject(includeInstance, f),
     timeout: jspb.Message.getFieldWithDefault(msg, 10, ""),
-    admission: jspb.Message.getFieldWithDefault(msg, 11, 0)
+    admission: jspb.Message.getFieldWithDefault(msg, 11, 0),
+    experimentsMap: (f = msg.getExperimentsMap()) ? f.toObject(includeInstance, undefined) : [],
+    initContainersList: jspb.Message.getRepeatedField(msg, 13),
+    pinnedIdeImage: jspb.Message.getFieldWithDefault(msg, 14, ""),
+    sshKeysList: jspb.Message.toObjectList(msg.getSshKeysList(),
+    proto.wsman.WorkspaceSSHKey.toObject, includeInstance)
   };
 
   if (includeInstance) {
@@ -5567,6 +22016,25 @@ proto.wsman.StartWorkspaceSpec.deserializeBinaryFromReader = function(msg, reade
       var value = /** @type {!proto.wsman.AdmissionLevel} */ (reader.readEnum());
       msg.setAdmission(value);
       break;
+    case 12:
+      var value = msg.getExperimentsMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "");
+         });
+      break;
+    case 13:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addInitContainers(value);
+      break;
+    case 14:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPinnedIdeImage(value);
+      break;
+    case 15:
+      var value = new proto.wsman.WorkspaceSSHKey;
+      reader.readMessage(value,proto.wsman.WorkspaceSSHKey.deserializeBinaryFromReader);
+      msg.addSshKeys(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -5677,6 +22145,32 @@ proto.wsman.StartWorkspaceSpec.serializeBinaryToWriter = function(message, write
       f
     );
   }
+  f = message.getExperimentsMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(12, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+  f = message.getInitContainersList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      13,
+      f
+    );
+  }
+  f = message.getPinnedIdeImage();
+  if (f.length > 0) {
+    writer.writeString(
+      14,
+      f
+    );
+  }
+  f = message.getSshKeysList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      15,
+      f,
+      proto.wsman.WorkspaceSSHKey.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -5936,6 +22430,260 @@ proto.wsman.StartWorkspaceSpec.prototype.setAdmission = function(value) {
 };
 
 
+/**
+ * map<string, string> experiments = 12;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getExperimentsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 12, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearExperimentsMap = function() {
+  this.getExperimentsMap().clear();
+};
+
+
+/**
+ * repeated string init_containers = 13;
+ * @return {!Array<string>}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getInitContainersList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 13));
+};
+
+
+/** @param {!Array<string>} value */
+proto.wsman.StartWorkspaceSpec.prototype.setInitContainersList = function(value) {
+  jspb.Message.setField(this, 13, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ */
+proto.wsman.StartWorkspaceSpec.prototype.addInitContainers = function(value, opt_index) {
+  jspb.Message.addToRepeatedField(this, 13, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearInitContainersList = function() {
+  this.setInitContainersList([]);
+};
+
+
+/**
+ * optional string pinned_ide_image = 14;
+ * @return {string}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getPinnedIdeImage = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 14, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.StartWorkspaceSpec.prototype.setPinnedIdeImage = function(value) {
+  jspb.Message.setProto3StringField(this, 14, value);
+};
+
+
+/**
+ * repeated WorkspaceSSHKey ssh_keys = 15;
+ * @return {!Array<!proto.wsman.WorkspaceSSHKey>}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.getSshKeysList = function() {
+  return /** @type{!Array<!proto.wsman.WorkspaceSSHKey>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.wsman.WorkspaceSSHKey, 15));
+};
+
+
+/** @param {!Array<!proto.wsman.WorkspaceSSHKey>} value */
+proto.wsman.StartWorkspaceSpec.prototype.setSshKeysList = function(value) {
+  jspb.Message.setRepeatedWrapperField(this, 15, value);
+};
+
+
+/**
+ * @param {!proto.wsman.WorkspaceSSHKey=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.WorkspaceSSHKey}
+ */
+proto.wsman.StartWorkspaceSpec.prototype.addSshKeys = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 15, opt_value, proto.wsman.WorkspaceSSHKey, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ */
+proto.wsman.StartWorkspaceSpec.prototype.clearSshKeysList = function() {
+  this.setSshKeysList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto suitable for use in Soy templates.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     com.google.apps.jspb.JsClassTemplate.JS_RESERVED_WORDS.
+ * @param {boolean=} opt_includeInstance Whether to include the JSPB instance
+ *     for transitional soy proto support: http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.wsman.WorkspaceSSHKey.prototype.toObject = function(opt_includeInstance) {
+  return proto.wsman.WorkspaceSSHKey.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Whether to include the JSPB
+ *     instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.wsman.WorkspaceSSHKey} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceSSHKey.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    name: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    keyOts: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.WorkspaceSSHKey}
+ */
+proto.wsman.WorkspaceSSHKey.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.wsman.WorkspaceSSHKey;
+  return proto.wsman.WorkspaceSSHKey.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.WorkspaceSSHKey} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.WorkspaceSSHKey}
+ */
+proto.wsman.WorkspaceSSHKey.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setKeyOts(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.WorkspaceSSHKey.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.wsman.WorkspaceSSHKey.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.WorkspaceSSHKey} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.WorkspaceSSHKey.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getKeyOts();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string name = 1;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSSHKey.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceSSHKey.prototype.setName = function(value) {
+  jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string key_ots = 2;
+ * @return {string}
+ */
+proto.wsman.WorkspaceSSHKey.prototype.getKeyOts = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/** @param {string} value */
+proto.wsman.WorkspaceSSHKey.prototype.setKeyOts = function(value) {
+  jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
 
 
 
@@ -6439,6 +23187,20 @@ proto.wsman.WorkspaceLogMessage.prototype.setMessage = function(value) {
 };
 
 
+/**
+ * @enum {number}
+ */
+proto.wsman.StartWorkspaceErrorDomain = {
+  START_ERROR_UNKNOWN: 0,
+  START_ERROR_INVALID_REQUEST: 1,
+  START_ERROR_CONFLICT: 2,
+  START_ERROR_QUOTA: 3,
+  START_ERROR_CAPACITY: 4,
+  START_ERROR_IMAGE: 5,
+  START_ERROR_ARCHIVED: 6,
+  START_ERROR_DELETED: 7
+};
+
 /**
  * @enum {number}
  */
@@ -6447,6 +23209,22 @@ proto.wsman.StopWorkspacePolicy = {
   IMMEDIATELY: 1
 };
 
+/**
+ * @enum {number}
+ */
+proto.wsman.AccountingEvent = {
+  INSTANCE_STARTED: 0,
+  INSTANCE_STOPPED: 1
+};
+
+/**
+ * @enum {number}
+ */
+proto.wsman.PendingOperationKind = {
+  CONTENT_INITIALIZATION: 0,
+  CONTENT_FINALIZATION: 1
+};
+
 /**
  * @enum {number}
  */
@@ -6455,6 +23233,25 @@ proto.wsman.AdmissionLevel = {
   ADMIT_EVERYONE: 1
 };
 
+/**
+ * @enum {number}
+ */
+proto.wsman.InitContainerState = {
+  INIT_CONTAINER_WAITING: 0,
+  INIT_CONTAINER_RUNNING: 1,
+  INIT_CONTAINER_SUCCEEDED: 2,
+  INIT_CONTAINER_FAILED: 3
+};
+
+/**
+ * @enum {number}
+ */
+proto.wsman.BulkOperationState = {
+  BULK_OPERATION_RUNNING: 0,
+  BULK_OPERATION_COMPLETED: 1,
+  BULK_OPERATION_CANCELLED: 2
+};
+
 /**
  * @enum {number}
  */
@@ -6483,7 +23280,8 @@ proto.wsman.WorkspacePhase = {
   RUNNING: 4,
   INTERRUPTED: 7,
   STOPPING: 5,
-  STOPPED: 6
+  STOPPED: 6,
+  ARCHIVED: 8
 };
 
 /**
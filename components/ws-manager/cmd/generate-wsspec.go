@@ -27,10 +27,12 @@ var generateWsspecCmd = &cobra.Command{
 		schema.Ref = "#/definitions/StartWorkspaceSpec"
 
 		initializers := map[string]interface{}{
-			"WorkspaceInitializer_Empty":    &csapi.WorkspaceInitializer_Empty{},
-			"WorkspaceInitializer_Git":      &csapi.WorkspaceInitializer_Git{},
-			"WorkspaceInitializer_Snapshot": &csapi.WorkspaceInitializer_Snapshot{},
-			"WorkspaceInitializer_Prebuild": &csapi.WorkspaceInitializer_Prebuild{},
+			"WorkspaceInitializer_Empty":     &csapi.WorkspaceInitializer_Empty{},
+			"WorkspaceInitializer_Git":       &csapi.WorkspaceInitializer_Git{},
+			"WorkspaceInitializer_Snapshot":  &csapi.WorkspaceInitializer_Snapshot{},
+			"WorkspaceInitializer_Prebuild":  &csapi.WorkspaceInitializer_Prebuild{},
+			"WorkspaceInitializer_Composite": &csapi.WorkspaceInitializer_Composite{},
+			"WorkspaceInitializer_Archive":   &csapi.WorkspaceInitializer_Archive{},
 		}
 		initializerDefs := make([]*jsonschema.Type, 0)
 		for k, t := range initializers {
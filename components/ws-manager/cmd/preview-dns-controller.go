@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"github.com/bombsimon/logrusr"
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-manager/pkg/previewdns"
+)
+
+// previewDNSControllerCmd represents the preview-dns-controller command
+var previewDNSControllerCmd = &cobra.Command{
+	Use:   "preview-dns-controller",
+	Short: "Maintains external-dns records for workspaces on custom preview domains",
+
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := getConfig()
+		if cfg.PreviewDNS == nil {
+			log.Fatal("no previewDNS configuration")
+		}
+		err := cfg.PreviewDNS.Validate()
+		if err != nil {
+			log.WithError(err).Fatal("invalid configuration")
+		}
+
+		ctrl.SetLogger(logrusr.NewLogger(log.Log))
+
+		opts := ctrl.Options{
+			Scheme:                 scheme,
+			Namespace:              cfg.Manager.Namespace,
+			HealthProbeBindAddress: ":0",
+			LeaderElection:         true,
+			LeaderElectionID:       "preview-dns-controller-leader.gitpod.io",
+		}
+		if cfg.Prometheus.Addr != "" {
+			opts.MetricsBindAddress = cfg.Prometheus.Addr
+		}
+
+		mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), opts)
+		if err != nil {
+			log.WithError(err).Fatal("unable to start manager")
+		}
+
+		err = (&previewdns.Reconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("PreviewDNS"),
+			Config: *cfg.PreviewDNS,
+		}).SetupWithManager(mgr)
+		if err != nil {
+			log.WithError(err).Fatal("unable to create controller")
+		}
+
+		log.Info("preview DNS controller is up and running. Stop with SIGINT or CTRL+C")
+		if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+			log.WithError(err).Fatal("problem running preview DNS controller")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(previewDNSControllerCmd)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 	"github.com/gitpod-io/gitpod/ws-manager/pkg/manager"
+	"github.com/gitpod-io/gitpod/ws-manager/pkg/previewdns"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -110,7 +111,14 @@ type config struct {
 	} `json:"pprof"`
 	Prometheus struct {
 		Addr string `json:"addr"`
+
+		// OpenMetrics serves the metrics in the OpenMetrics format to scrapers which ask for it. Only that format
+		// carries the exemplars which link workspace phase latencies to their traces.
+		OpenMetrics bool `json:"openMetrics,omitempty"`
 	} `json:"prometheus"`
+
+	// PreviewDNS configures the preview-dns-controller command
+	PreviewDNS *previewdns.Config `json:"previewDNS,omitempty"`
 }
 
 func newClientSet() (*kubernetes.Clientset, error) {
@@ -7,6 +7,7 @@ package cmd
 import (
 	"context"
 	"net"
+	"net/http"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -19,6 +20,7 @@ import (
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -66,6 +68,10 @@ var runCmd = &cobra.Command{
 		if cfg.Prometheus.Addr != "" {
 			opts.MetricsBindAddress = cfg.Prometheus.Addr
 		}
+		if cfg.Prometheus.Addr != "" && cfg.Prometheus.OpenMetrics {
+			// the controller manager cannot serve OpenMetrics - we serve its registry ourselves
+			opts.MetricsBindAddress = "0"
+		}
 
 		mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), opts)
 		if err != nil {
@@ -199,6 +205,20 @@ var runCmd = &cobra.Command{
 		if cfg.PProf.Addr != "" {
 			go pprof.Serve(cfg.PProf.Addr)
 		}
+		if cfg.Prometheus.Addr != "" && cfg.Prometheus.OpenMetrics {
+			go func() {
+				handler := http.NewServeMux()
+				handler.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+					ErrorHandling:     promhttp.ContinueOnError,
+					EnableOpenMetrics: true,
+				}))
+
+				err := http.ListenAndServe(cfg.Prometheus.Addr, handler)
+				if err != nil {
+					log.WithError(err).Fatal("cannot serve Prometheus metrics")
+				}
+			}()
+		}
 
 		// run until we're told to stop
 		log.Info("🦸  wsman is up and running. Stop with SIGINT or CTRL+C")
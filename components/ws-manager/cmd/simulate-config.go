@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/spf13/cobra"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+	"github.com/gitpod-io/gitpod/ws-manager/pkg/manager"
+)
+
+var simulateConfigOpts struct {
+	Sample string
+	JSON   bool
+}
+
+// simulateConfigCmd reports how a configuration change would affect workspaces
+var simulateConfigCmd = &cobra.Command{
+	Use:   "simulate-config <proposed-config.json>",
+	Short: "Reports how a proposed configuration changes workspace pods, URLs and images compared to the current one (--config)",
+	Long: `Produces the workspaces of a sample of start requests with the current and the proposed configuration
+and reports the differences in pod specs, workspace and port URLs, pod templates and image references.
+The sample is a JSON array of StartWorkspaceRequests (see "ws-manager generate wsspec").
+Nothing is created in or read from the cluster.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		current := getConfig()
+		err := current.Manager.Validate()
+		if err != nil {
+			return fmt.Errorf("current configuration is invalid: %w", err)
+		}
+
+		ctnt, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("cannot read proposed configuration: %w", err)
+		}
+		var proposed config
+		err = json.Unmarshal(ctnt, &proposed)
+		if err != nil {
+			return fmt.Errorf("cannot read proposed configuration: %w", err)
+		}
+		err = proposed.Manager.Validate()
+		if err != nil {
+			return fmt.Errorf("proposed configuration is invalid: %w", err)
+		}
+
+		sample, err := readStartWorkspaceRequests(simulateConfigOpts.Sample)
+		if err != nil {
+			return err
+		}
+
+		report, err := manager.SimulateConfigChange(current.Manager, proposed.Manager, sample)
+		if err != nil {
+			return err
+		}
+
+		if simulateConfigOpts.JSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		}
+		for _, ws := range report.Workspaces {
+			if !ws.Changed() {
+				continue
+			}
+			fmt.Printf("workspace %s (%s, %s)\n", ws.ID, ws.MetaID, ws.Type)
+			if ws.CurrentError != ws.ProposedError {
+				fmt.Printf("  error: %q -> %q\n", ws.CurrentError, ws.ProposedError)
+			}
+			for _, v := range ws.Values {
+				fmt.Printf("  %s: %q -> %q\n", v.Name, v.Current, v.Proposed)
+			}
+			if ws.PodDiff != "" {
+				fmt.Printf("  pod (-current +proposed):\n%s\n", ws.PodDiff)
+			}
+		}
+		fmt.Printf("%d of %d workspaces change\n", report.Changed(), len(report.Workspaces))
+		return nil
+	},
+}
+
+// readStartWorkspaceRequests reads a JSON array of start requests
+func readStartWorkspaceRequests(fn string) ([]*api.StartWorkspaceRequest, error) {
+	ctnt, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read sample: %w", err)
+	}
+	var raw []json.RawMessage
+	err = json.Unmarshal(ctnt, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read sample: %w", err)
+	}
+
+	res := make([]*api.StartWorkspaceRequest, len(raw))
+	for i, r := range raw {
+		var req api.StartWorkspaceRequest
+		err = jsonpb.Unmarshal(bytes.NewReader(r), &req)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request %d of the sample: %w", i, err)
+		}
+		res[i] = &req
+	}
+	return res, nil
+}
+
+func init() {
+	simulateConfigCmd.Flags().StringVar(&simulateConfigOpts.Sample, "sample", "", "JSON file containing an array of StartWorkspaceRequests")
+	simulateConfigCmd.Flags().BoolVar(&simulateConfigOpts.JSON, "json", false, "print the report as JSON")
+	_ = simulateConfigCmd.MarkFlagRequired("sample")
+	rootCmd.AddCommand(simulateConfigCmd)
+}
@@ -0,0 +1,546 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// accountingCompactionThreshold is the number of obsolete journal entries after which we rewrite the journal
+const accountingCompactionThreshold = 1000
+
+// AccountingConfig enables the stream of runtime accounting records billing systems reconcile against
+type AccountingConfig struct {
+	// JournalPath is the file we keep accounting records in until they're acknowledged. It must be on a persistent
+	// volume, otherwise we lose unacknowledged records and the instances we consider running when ws-manager restarts.
+	JournalPath string `json:"journalPath"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *AccountingConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.JournalPath, validation.Required),
+	)
+}
+
+// accountingJournalEntry is a line of the accounting journal. Every entry is either a record or an acknowledgement.
+type accountingJournalEntry struct {
+	Record       json.RawMessage `json:"record,omitempty"`
+	Acknowledged uint64          `json:"acknowledged,omitempty"`
+}
+
+// accountingJournal records when workspace instances start and stop. Records are persisted before anyone
+// can see them and kept until they're acknowledged.
+type accountingJournal struct {
+	fn string
+
+	mu sync.Mutex
+	f  *os.File
+	// records are the unacknowledged records ordered by their sequence
+	records []*api.AccountingRecord
+	// running are the start records of the instances which have not stopped yet
+	running map[string]*api.AccountingRecord
+	// missing are the running instances which had no pod during the last housekeeping
+	missing      map[string]struct{}
+	last         uint64
+	acknowledged uint64
+	// entries counts the entries of the journal file
+	entries int
+	// changed is closed and replaced whenever we add a record
+	changed chan struct{}
+}
+
+// openAccountingJournal restores the accounting journal from fn, or starts a new one if fn does not exist
+func openAccountingJournal(fn string) (*accountingJournal, error) {
+	j := &accountingJournal{
+		fn:      fn,
+		running: make(map[string]*api.AccountingRecord),
+		missing: make(map[string]struct{}),
+		changed: make(chan struct{}),
+	}
+
+	err := j.restore()
+	if err != nil {
+		return nil, err
+	}
+
+	// we always start with a compacted journal so that we don't have to deal with entries we skipped while restoring
+	err = j.compact()
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *accountingJournal) restore() error {
+	fc, err := os.ReadFile(j.fn)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot read accounting journal: %w", err)
+	}
+
+	var records []*api.AccountingRecord
+	lines := bytes.Split(fc, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry accountingJournalEntry
+		err := json.Unmarshal(line, &entry)
+		if err == nil && len(entry.Record) > 0 {
+			var rec api.AccountingRecord
+			err = jsonpb.Unmarshal(bytes.NewReader(entry.Record), &rec)
+			if err == nil {
+				records = append(records, &rec)
+			}
+		}
+		if err != nil {
+			if i == len(lines)-1 {
+				// ws-manager stopped while writing the last entry. It was never acknowledged to anyone.
+				log.WithError(err).WithField("journal", j.fn).Warn("ignoring incomplete last entry of the accounting journal")
+				break
+			}
+			return xerrors.Errorf("cannot parse line %d of the accounting journal: %w", i+1, err)
+		}
+		if entry.Acknowledged > j.acknowledged {
+			j.acknowledged = entry.Acknowledged
+		}
+	}
+
+	for _, rec := range records {
+		if rec.Sequence > j.last {
+			j.last = rec.Sequence
+		}
+		switch rec.Event {
+		case api.AccountingEvent_INSTANCE_STARTED:
+			j.running[rec.Id] = rec
+		case api.AccountingEvent_INSTANCE_STOPPED:
+			delete(j.running, rec.Id)
+		}
+		if rec.Sequence > j.acknowledged {
+			j.records = append(j.records, rec)
+		}
+	}
+	if j.acknowledged > j.last {
+		j.last = j.acknowledged
+	}
+	return nil
+}
+
+// compact rewrites the journal with the entries we still need. Callers must hold mu or have exclusive access.
+func (j *accountingJournal) compact() error {
+	var buf bytes.Buffer
+	write := func(entry accountingJournalEntry) error {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		return nil
+	}
+
+	err := write(accountingJournalEntry{Acknowledged: j.acknowledged})
+	if err != nil {
+		return err
+	}
+	// start records which were acknowledged already tell us when the instance started once it stops
+	for _, rec := range j.running {
+		if rec.Sequence > j.acknowledged {
+			continue
+		}
+		entry, err := newAccountingJournalEntry(rec)
+		if err != nil {
+			return err
+		}
+		err = write(entry)
+		if err != nil {
+			return err
+		}
+	}
+	for _, rec := range j.records {
+		entry, err := newAccountingJournalEntry(rec)
+		if err != nil {
+			return err
+		}
+		err = write(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	tmp := j.fn + ".tmp"
+	err = writeFileSync(tmp, buf.Bytes())
+	if err != nil {
+		return xerrors.Errorf("cannot write accounting journal: %w", err)
+	}
+	if j.f != nil {
+		j.f.Close()
+		j.f = nil
+	}
+	err = os.Rename(tmp, j.fn)
+	if err != nil {
+		return xerrors.Errorf("cannot replace accounting journal: %w", err)
+	}
+	j.f, err = os.OpenFile(j.fn, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return xerrors.Errorf("cannot open accounting journal: %w", err)
+	}
+	j.entries = bytes.Count(buf.Bytes(), []byte("\n"))
+	return nil
+}
+
+func writeFileSync(fn string, content []byte) error {
+	f, err := os.OpenFile(fn, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func newAccountingJournalEntry(rec *api.AccountingRecord) (accountingJournalEntry, error) {
+	raw, err := (&jsonpb.Marshaler{}).MarshalToString(rec)
+	if err != nil {
+		return accountingJournalEntry{}, err
+	}
+	return accountingJournalEntry{Record: json.RawMessage(raw)}, nil
+}
+
+// append persists an entry. Callers must hold mu.
+func (j *accountingJournal) append(entry accountingJournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = j.f.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	j.entries++
+	// a record we have not persisted must not reach anyone
+	return j.f.Sync()
+}
+
+// add persists a new record and notifies everyone waiting for records. Callers must hold mu.
+func (j *accountingJournal) add(rec *api.AccountingRecord) error {
+	rec.Sequence = j.last + 1
+	entry, err := newAccountingJournalEntry(rec)
+	if err != nil {
+		return err
+	}
+	err = j.append(entry)
+	if err != nil {
+		return err
+	}
+
+	j.last = rec.Sequence
+	j.records = append(j.records, rec)
+	close(j.changed)
+	j.changed = make(chan struct{})
+	return nil
+}
+
+// Started records that an instance is running. We record every instance once only.
+func (j *accountingJournal) Started(status *api.WorkspaceStatus, now time.Time) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, exists := j.running[status.Id]; exists {
+		return nil
+	}
+
+	ts, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return err
+	}
+	rec := &api.AccountingRecord{
+		Event:    api.AccountingEvent_INSTANCE_STARTED,
+		Id:       status.Id,
+		Metadata: proto.Clone(status.Metadata).(*api.WorkspaceMetadata),
+		Type:     status.Spec.GetType(),
+		Time:     ts,
+	}
+	if status.Runtime != nil {
+		rec.NodeName = status.Runtime.NodeName
+	}
+	err = j.add(rec)
+	if err != nil {
+		return err
+	}
+	j.running[status.Id] = rec
+	return nil
+}
+
+// Stopped records that an instance stopped running. Instances we have not seen running are not recorded.
+func (j *accountingJournal) Stopped(instanceID string, now time.Time, estimated bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	start, running := j.running[instanceID]
+	if !running {
+		return nil
+	}
+	startTime, err := ptypes.Timestamp(start.Time)
+	if err != nil {
+		return err
+	}
+	ts, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return err
+	}
+	var runtime uint64
+	if now.After(startTime) {
+		runtime = uint64(now.Sub(startTime).Seconds())
+	}
+
+	err = j.add(&api.AccountingRecord{
+		Event:          api.AccountingEvent_INSTANCE_STOPPED,
+		Id:             instanceID,
+		Metadata:       start.Metadata,
+		Type:           start.Type,
+		NodeName:       start.NodeName,
+		Time:           ts,
+		RuntimeSeconds: runtime,
+		Estimated:      estimated,
+	})
+	if err != nil {
+		return err
+	}
+	delete(j.running, instanceID)
+	delete(j.missing, instanceID)
+	return nil
+}
+
+// Since returns the records after a sequence and a channel which is closed once there are more.
+// We cannot serve records which were acknowledged already, except when starting at zero.
+func (j *accountingJournal) Since(sequence uint64) ([]*api.AccountingRecord, <-chan struct{}, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if sequence == 0 {
+		sequence = j.acknowledged
+	}
+	if sequence < j.acknowledged {
+		return nil, nil, status.Errorf(codes.OutOfRange, "records up to %d were acknowledged and discarded already", j.acknowledged)
+	}
+	if sequence > j.last {
+		return nil, nil, status.Errorf(codes.OutOfRange, "there is no record %d yet, the last one is %d", sequence, j.last)
+	}
+
+	var res []*api.AccountingRecord
+	for _, rec := range j.records {
+		if rec.Sequence > sequence {
+			res = append(res, rec)
+		}
+	}
+	return res, j.changed, nil
+}
+
+// Acknowledge discards all records up to sequence and returns the sequence of the last acknowledged record
+func (j *accountingJournal) Acknowledge(sequence uint64) (uint64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if sequence > j.last {
+		return 0, status.Errorf(codes.InvalidArgument, "there is no record %d yet, the last one is %d", sequence, j.last)
+	}
+	if sequence <= j.acknowledged {
+		return j.acknowledged, nil
+	}
+
+	err := j.append(accountingJournalEntry{Acknowledged: sequence})
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "cannot persist acknowledgement: %v", err)
+	}
+	j.acknowledged = sequence
+
+	var idx int
+	for idx < len(j.records) && j.records[idx].Sequence <= sequence {
+		idx++
+	}
+	// the start records of running instances stay in the journal, we just don't serve them anymore
+	j.records = append([]*api.AccountingRecord(nil), j.records[idx:]...)
+
+	// a compacted journal has the acknowledgement, the start records of running instances and all unacknowledged records
+	if obsolete := j.entries - 1 - len(j.running) - len(j.records); obsolete >= accountingCompactionThreshold {
+		err = j.compact()
+		if err != nil {
+			// the acknowledgement is persisted already - compacting the journal can wait until next time
+			log.WithError(err).Warn("cannot compact accounting journal")
+		}
+	}
+	return j.acknowledged, nil
+}
+
+// MarkMissing remembers the running instances which have no pod. Instances which are missing twice in a row
+// stopped while we did not watch, which is why MarkMissing returns them. exists is called without holding the lock.
+func (j *accountingJournal) MarkMissing(exists func(instanceID string) (bool, error)) (stopped []string, err error) {
+	j.mu.Lock()
+	ids := make([]string, 0, len(j.running))
+	for id := range j.running {
+		ids = append(ids, id)
+	}
+	j.mu.Unlock()
+
+	missing := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		ok, err := exists(id)
+		if err != nil {
+			return nil, err
+		}
+		missing[id] = !ok
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for id, m := range missing {
+		if !m {
+			delete(j.missing, id)
+			continue
+		}
+		if _, wasMissing := j.missing[id]; wasMissing {
+			stopped = append(stopped, id)
+			continue
+		}
+		j.missing[id] = struct{}{}
+	}
+	return stopped, nil
+}
+
+// Close closes the journal file
+func (j *accountingJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
+
+// onAccountingChange records instances which start or stop running
+func (m *Manager) onAccountingChange(status *api.WorkspaceStatus) {
+	if m.accounting == nil {
+		return
+	}
+
+	var err error
+	switch status.Phase {
+	case api.WorkspacePhase_RUNNING:
+		err = m.accounting.Started(status, time.Now())
+	case api.WorkspacePhase_STOPPED:
+		err = m.accounting.Stopped(status.Id, time.Now(), false)
+	}
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI(status.Metadata.GetOwner(), status.Metadata.GetMetaId(), status.Id)).WithField("phase", status.Phase.String()).Error("cannot record workspace instance for accounting")
+	}
+}
+
+// SubscribeAccounting streams the runtime accounting records of workspace instances
+func (m *Manager) SubscribeAccounting(req *api.SubscribeAccountingRequest, srv api.WorkspaceManager_SubscribeAccountingServer) error {
+	if m.accounting == nil {
+		return status.Error(codes.Unimplemented, "accounting is disabled")
+	}
+
+	ctx := srv.Context()
+	cursor := req.AfterSequence
+	for {
+		records, changed, err := m.accounting.Since(cursor)
+		if err != nil {
+			return err
+		}
+		for _, rec := range records {
+			err = srv.Send(rec)
+			if err != nil {
+				return err
+			}
+			cursor = rec.Sequence
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// AckAccounting acknowledges accounting records so that we can discard them
+func (m *Manager) AckAccounting(ctx context.Context, req *api.AckAccountingRequest) (res *api.AckAccountingResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "AckAccounting")
+	span.SetTag("sequence", req.Sequence)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.accounting == nil {
+		return nil, status.Error(codes.Unimplemented, "accounting is disabled")
+	}
+
+	acknowledged, err := m.accounting.Acknowledge(req.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	return &api.AckAccountingResponse{Acknowledged: acknowledged}, nil
+}
+
+// recordMissingInstancesStopped records the instances which stopped while we did not watch, e.g. because
+// ws-manager was down when their pod was deleted
+func (m *Monitor) recordMissingInstancesStopped(ctx context.Context) error {
+	stopped, err := m.manager.accounting.MarkMissing(func(instanceID string) (bool, error) {
+		_, err := m.manager.findWorkspacePod(ctx, instanceID)
+		if isKubernetesObjNotFoundError(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return xerrors.Errorf("recordMissingInstancesStopped: %w", err)
+	}
+
+	for _, id := range stopped {
+		err := m.manager.accounting.Stopped(id, time.Now(), true)
+		if err != nil {
+			m.OnError(xerrors.Errorf("recordMissingInstancesStopped: %w", err))
+			continue
+		}
+		log.WithFields(log.OWI("", "", id)).Info("recorded stop of a workspace instance which stopped unobserved")
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func newAccountingTestStatus(id string) *api.WorkspaceStatus {
+	return &api.WorkspaceStatus{
+		Id:       id,
+		Phase:    api.WorkspacePhase_RUNNING,
+		Metadata: &api.WorkspaceMetadata{Owner: "owner", MetaId: "meta-" + id},
+		Spec:     &api.WorkspaceSpec{Type: api.WorkspaceType_PREBUILD},
+		Runtime:  &api.WorkspaceRuntimeInfo{NodeName: "node-1"},
+	}
+}
+
+type accountingTestRecord struct {
+	Sequence  uint64
+	Event     api.AccountingEvent
+	ID        string
+	Runtime   uint64
+	Estimated bool
+}
+
+func summarizeAccountingRecords(records []*api.AccountingRecord) []accountingTestRecord {
+	res := make([]accountingTestRecord, len(records))
+	for i, r := range records {
+		res[i] = accountingTestRecord{r.Sequence, r.Event, r.Id, r.RuntimeSeconds, r.Estimated}
+	}
+	return res
+}
+
+func expectAccountingRecords(t *testing.T, j *accountingJournal, after uint64, expected ...accountingTestRecord) {
+	t.Helper()
+
+	records, _, err := j.Since(after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act := summarizeAccountingRecords(records)
+	if len(act) != len(expected) {
+		t.Fatalf("expected %d records, got %v", len(expected), act)
+	}
+	for i := range act {
+		if act[i] != expected[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, expected[i], act[i])
+		}
+	}
+}
+
+func TestAccountingJournal(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := openAccountingJournal(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	t0 := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(j.Started(newAccountingTestStatus("a"), t0))
+	must(j.Started(newAccountingTestStatus("b"), t0))
+	// the monitor tells us about running workspaces over and over again
+	must(j.Started(newAccountingTestStatus("a"), t0.Add(time.Minute)))
+	must(j.Stopped("a", t0.Add(90*time.Second), false))
+	// instances which never ran are not recorded
+	must(j.Stopped("never-started", t0, false))
+
+	expectAccountingRecords(t, j, 0,
+		accountingTestRecord{1, api.AccountingEvent_INSTANCE_STARTED, "a", 0, false},
+		accountingTestRecord{2, api.AccountingEvent_INSTANCE_STARTED, "b", 0, false},
+		accountingTestRecord{3, api.AccountingEvent_INSTANCE_STOPPED, "a", 90, false},
+	)
+	records, _, _ := j.Since(2)
+	if stop := records[0]; stop.NodeName != "node-1" || stop.Type != api.WorkspaceType_PREBUILD || stop.Metadata.GetOwner() != "owner" {
+		t.Errorf("stop record does not describe the instance: %v", stop)
+	}
+
+	acked, err := j.Acknowledge(2)
+	if err != nil || acked != 2 {
+		t.Fatalf("unexpected acknowledgement: %d, %v", acked, err)
+	}
+	expectAccountingRecords(t, j, 0,
+		accountingTestRecord{3, api.AccountingEvent_INSTANCE_STOPPED, "a", 90, false},
+	)
+	if _, _, err := j.Since(1); status.Code(err) != codes.OutOfRange {
+		t.Errorf("expected OutOfRange for acknowledged records, got %v", err)
+	}
+	if _, _, err := j.Since(4); status.Code(err) != codes.OutOfRange {
+		t.Errorf("expected OutOfRange for future records, got %v", err)
+	}
+	if _, err := j.Acknowledge(4); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument when acknowledging future records, got %v", err)
+	}
+	if acked, _ := j.Acknowledge(1); acked != 2 {
+		t.Errorf("acknowledging older records moved the acknowledgement back to %d", acked)
+	}
+
+	// ws-manager restarts: b's start record was acknowledged, but we still need it when b stops
+	must(j.Close())
+	j, err = openAccountingJournal(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	expectAccountingRecords(t, j, 2,
+		accountingTestRecord{3, api.AccountingEvent_INSTANCE_STOPPED, "a", 90, false},
+	)
+	must(j.Started(newAccountingTestStatus("b"), t0.Add(time.Hour)))
+	must(j.Stopped("b", t0.Add(time.Hour), false))
+	expectAccountingRecords(t, j, 3,
+		accountingTestRecord{4, api.AccountingEvent_INSTANCE_STOPPED, "b", 3600, false},
+	)
+}
+
+func TestAccountingJournalIncompleteEntry(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := openAccountingJournal(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = j.Started(newAccountingTestStatus("a"), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	f, err := os.OpenFile(fn, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"record":{"sequence":"2","eve`)
+	f.Close()
+
+	j, err = openAccountingJournal(fn)
+	if err != nil {
+		t.Fatalf("incomplete last entry prevented restoring the journal: %v", err)
+	}
+	defer j.Close()
+	expectAccountingRecords(t, j, 0,
+		accountingTestRecord{1, api.AccountingEvent_INSTANCE_STARTED, "a", 0, false},
+	)
+	err = j.Stopped("a", time.Now(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectAccountingRecords(t, j, 1,
+		accountingTestRecord{2, api.AccountingEvent_INSTANCE_STOPPED, "a", 0, false},
+	)
+}
+
+func TestAccountingJournalCompaction(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := openAccountingJournal(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	now := time.Now()
+	err = j.Started(newAccountingTestStatus("running"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < accountingCompactionThreshold; i++ {
+		id := "ws-" + time.Duration(i).String()
+		if err := j.Started(newAccountingTestStatus(id), now); err != nil {
+			t.Fatal(err)
+		}
+		if err := j.Stopped(id, now, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = j.Acknowledge(j.last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.entries != 2 {
+		t.Errorf("expected the acknowledgement and the running instance in the compacted journal, got %d entries", j.entries)
+	}
+
+	j.Close()
+	j, err = openAccountingJournal(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if _, ok := j.running["running"]; !ok || len(j.running) != 1 {
+		t.Errorf("compaction lost track of running instances: %v", j.running)
+	}
+	if j.last != 1+2*accountingCompactionThreshold {
+		t.Errorf("compaction reset the sequence to %d", j.last)
+	}
+}
+
+func TestAccountingJournalMarkMissing(t *testing.T) {
+	j, err := openAccountingJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	for _, id := range []string{"gone", "flaky", "there"} {
+		if err := j.Started(newAccountingTestStatus(id), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	housekeeping := []map[string]bool{
+		{"gone": false, "flaky": false, "there": true},
+		{"gone": false, "flaky": true, "there": true},
+	}
+	var stopped []string
+	for _, pods := range housekeeping {
+		stopped, err = j.MarkMissing(func(id string) (bool, error) { return pods[id], nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(stopped) != 1 || stopped[0] != "gone" {
+		t.Errorf("expected only the instance missing twice in a row to have stopped, got %v", stopped)
+	}
+}
+
+func TestOnAccountingChange(t *testing.T) {
+	j, err := openAccountingJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	m := &Manager{accounting: j}
+
+	for _, phase := range []api.WorkspacePhase{api.WorkspacePhase_CREATING, api.WorkspacePhase_RUNNING, api.WorkspacePhase_RUNNING, api.WorkspacePhase_STOPPING, api.WorkspacePhase_STOPPED} {
+		sts := newAccountingTestStatus("a")
+		sts.Phase = phase
+		m.onAccountingChange(sts)
+	}
+	expectAccountingRecords(t, j, 0,
+		accountingTestRecord{1, api.AccountingEvent_INSTANCE_STARTED, "a", 0, false},
+		accountingTestRecord{2, api.AccountingEvent_INSTANCE_STOPPED, "a", 0, false},
+	)
+
+	// without accounting there's nothing to record
+	(&Manager{}).onAccountingChange(newAccountingTestStatus("b"))
+}
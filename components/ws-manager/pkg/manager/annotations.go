@@ -65,6 +65,9 @@ const (
 	// This is handy if you want to prevent a workspace from timing out during lunch break.
 	customTimeoutAnnotation = "gitpod/customTimeout"
 
+	// scheduledStopAnnotation contains the RFC 3339 time at which a workspace stops regardless of its activity
+	scheduledStopAnnotation = "gitpod/scheduledStop"
+
 	// firstUserActivityAnnotation marks a workspace woth the timestamp of first user activity in it
 	firstUserActivityAnnotation = "gitpod/firstUserActivity"
 
@@ -80,6 +83,28 @@ const (
 	// ingressPortsAnnotation holds the mapping workspace port -> allocated ingress port on kubernetes services
 	ingressPortsAnnotation = "gitpod/ingressPorts"
 
+	// workspaceExperimentsAnnotation contains the JSON encoded experiment variants the workspace is assigned to
+	workspaceExperimentsAnnotation = "gitpod/experiments"
+
+	// workspaceInitContainersAnnotation lists the init containers from the allowlist the workspace opted into, comma-separated
+	workspaceInitContainersAnnotation = "gitpod/initContainers"
+
+	// workspaceIDEPinAnnotation contains the JSON encoded IDE pin of workspaces which were started with a pinned IDE image
+	workspaceIDEPinAnnotation = "gitpod/idePin"
+
+	// workspacePausedAnnotation is set on workspaces which were created ahead of demand. Its value is pausedWaiting until
+	// a StartWorkspace request activates the workspace, and pausedActivating until supervisor confirmed the activation.
+	workspacePausedAnnotation = "gitpod/paused"
+
+	// workspaceActivatedAnnotation contains the RFC 3339 time at which a paused workspace was activated
+	workspaceActivatedAnnotation = "gitpod/activated"
+
+	// workspaceProjectAnnotation contains the project a workspace belongs to
+	workspaceProjectAnnotation = "gitpod/project"
+
+	// workspaceMigrateToAnnotation contains the cluster a bulk migration moves the workspace to
+	workspaceMigrateToAnnotation = "gitpod/migrateTo"
+
 	// withUsernamespaceAnnotation is set on workspaces which are wrapped in a user namespace (or have some form of user namespace support)
 	// Beware: this annotation is duplicated/copied in ws-daemon
 	withUsernamespaceAnnotation = "gitpod/withUsernamespace"
@@ -0,0 +1,544 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultArchivalInterval         = 1 * time.Hour
+	defaultArchivalWarmStorageClass = "STANDARD"
+)
+
+// ArchivalConfig moves the backups of workspaces which have been stopped for a long time to cold storage
+type ArchivalConfig struct {
+	// StatePath is the file we keep the stopped and archived workspaces in. It must be on a persistent volume,
+	// otherwise we forget which workspaces are archived when ws-manager restarts.
+	StatePath string `json:"statePath"`
+	// After is how long a regular workspace must have been stopped before we archive its backup
+	After util.Duration `json:"after"`
+	// ColdStorageClass is the storage class archived backups move to, e.g. ARCHIVE on GCS or GLACIER on S3
+	ColdStorageClass string `json:"coldStorageClass"`
+	// WarmStorageClass is the storage class backups move back to when a workspace is unarchived. Defaults to STANDARD.
+	WarmStorageClass string `json:"warmStorageClass,omitempty"`
+	// RestoreLatency is how long we expect unarchiving a workspace to take. We surface it to users.
+	RestoreLatency util.Duration `json:"restoreLatency"`
+	// Interval is how often we look for workspaces to archive. Defaults to one hour.
+	Interval util.Duration `json:"interval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ArchivalConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.StatePath, validation.Required),
+		validation.Field(&c.After, validation.Required, validation.Min(util.Duration(0))),
+		validation.Field(&c.ColdStorageClass, validation.Required),
+		validation.Field(&c.RestoreLatency, validation.Min(util.Duration(0))),
+		validation.Field(&c.Interval, validation.Min(util.Duration(0))),
+	)
+}
+
+type archivalState string
+
+const (
+	// archivalStopped means the backup is in regular storage
+	archivalStopped archivalState = "stopped"
+	// archivalArchiving means the backup is moving to cold storage
+	archivalArchiving archivalState = "archiving"
+	// archivalArchived means the backup is in cold storage
+	archivalArchived archivalState = "archived"
+	// archivalUnarchiving means the backup is moving out of cold storage
+	archivalUnarchiving archivalState = "unarchiving"
+)
+
+// archivalRecord is a regular workspace which stopped and whose backup we archive eventually
+type archivalRecord struct {
+	WorkspaceID string        `json:"workspaceId"`
+	Owner       string        `json:"owner"`
+	State       archivalState `json:"state"`
+	StoppedAt   time.Time     `json:"stoppedAt"`
+	ArchivedAt  time.Time     `json:"archivedAt,omitempty"`
+	BackupSize  int64         `json:"backupSize,omitempty"`
+	// UnarchivedAt is when the owner asked for the workspace to be unarchived
+	UnarchivedAt time.Time `json:"unarchivedAt,omitempty"`
+}
+
+// archiver keeps track of stopped workspaces and moves the backups of those which have been stopped for long to cold storage
+type archiver struct {
+	Config  ArchivalConfig
+	Storage storage.PresignedAccess
+
+	mu      sync.Mutex
+	records map[string]*archivalRecord
+
+	kick   chan struct{}
+	now    func() time.Time
+	cancel context.CancelFunc
+}
+
+// newArchiver restores the archival state from the state file, or starts with an empty one if the file does not exist
+func newArchiver(cfg ArchivalConfig, s storage.PresignedAccess) (*archiver, error) {
+	if cfg.WarmStorageClass == "" {
+		cfg.WarmStorageClass = defaultArchivalWarmStorageClass
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = util.Duration(defaultArchivalInterval)
+	}
+
+	a := &archiver{
+		Config:  cfg,
+		Storage: s,
+		records: make(map[string]*archivalRecord),
+		kick:    make(chan struct{}, 1),
+		now:     time.Now,
+	}
+
+	fc, err := os.ReadFile(cfg.StatePath)
+	if os.IsNotExist(err) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read archival state: %w", err)
+	}
+	var records []*archivalRecord
+	err = json.Unmarshal(fc, &records)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read archival state: %w", err)
+	}
+	for _, r := range records {
+		a.records[r.WorkspaceID] = r
+	}
+	return a, nil
+}
+
+// persist writes the archival state to the state file. Callers must hold mu.
+func (a *archiver) persist() error {
+	records := make([]*archivalRecord, 0, len(a.records))
+	for _, r := range a.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].WorkspaceID < records[j].WorkspaceID })
+	err := writeStateFile(a.Config.StatePath, records)
+	if err != nil {
+		return xerrors.Errorf("cannot write archival state: %w", err)
+	}
+	return nil
+}
+
+// writeStateFile writes state as JSON to fn. It writes to a temporary file first so that we never leave
+// a half-written file behind.
+func writeStateFile(fn string, state interface{}) error {
+	fc, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fn), "."+filepath.Base(fn)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(fc)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fn)
+}
+
+// Stopped records that a regular workspace stopped and its backup is complete
+func (a *archiver) Stopped(workspaceID, owner string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.records[workspaceID] = &archivalRecord{
+		WorkspaceID: workspaceID,
+		Owner:       owner,
+		State:       archivalStopped,
+		StoppedAt:   a.now(),
+	}
+	return a.persist()
+}
+
+// AdmitStart forgets a workspace which is about to start, so that we do not archive it while it runs.
+// Workspaces whose backup is (moving) in cold storage cannot start.
+func (a *archiver) AdmitStart(workspaceID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r, ok := a.records[workspaceID]
+	if !ok {
+		return nil
+	}
+	switch r.State {
+	case archivalStopped:
+		delete(a.records, workspaceID)
+		return a.persist()
+	case archivalUnarchiving:
+		retryAfter := time.Until(a.readyAt(r))
+		if retryAfter < time.Second {
+			retryAfter = time.Second
+		}
+		return newStartWorkspaceError(codes.FailedPrecondition, api.StartWorkspaceErrorDomain_START_ERROR_ARCHIVED, retryAfter,
+			"The workspace is being restored from the archive. Please try again later.",
+			xerrors.Errorf("workspace %s is being unarchived", workspaceID))
+	default:
+		return newStartWorkspaceError(codes.FailedPrecondition, api.StartWorkspaceErrorDomain_START_ERROR_ARCHIVED, 0,
+			"The workspace has been archived. Unarchive it to start it again.",
+			xerrors.Errorf("workspace %s is archived", workspaceID))
+	}
+}
+
+// Forget stops keeping track of a workspace, e.g. because it was deleted, and returns what we knew about it
+func (a *archiver) Forget(workspaceID string) (*archivalRecord, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r, ok := a.records[workspaceID]
+	if !ok {
+		return nil, nil
+	}
+	delete(a.records, workspaceID)
+	return r, a.persist()
+}
+
+// Remember keeps track of a workspace we forgot about again, e.g. because its deletion was undone
+func (a *archiver) Remember(r *archivalRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.records[r.WorkspaceID] = r
+	return a.persist()
+}
+
+// readyAt is when we expect an unarchiving workspace to be able to start again. Callers must hold mu.
+func (a *archiver) readyAt(r *archivalRecord) time.Time {
+	return r.UnarchivedAt.Add(time.Duration(a.Config.RestoreLatency))
+}
+
+// Unarchive marks an archived workspace to be moved out of cold storage and returns when we expect it to be ready
+func (a *archiver) Unarchive(workspaceID string) (readyAt time.Time, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r, ok := a.records[workspaceID]
+	if !ok {
+		return time.Time{}, status.Errorf(codes.NotFound, "workspace %s is not archived", workspaceID)
+	}
+	switch r.State {
+	case archivalStopped:
+		// the workspace is not archived (anymore) and can start right away
+		return a.now(), nil
+	case archivalUnarchiving:
+		return a.readyAt(r), nil
+	}
+
+	// workspaces which are archiving right now are unarchived once that's done
+	r.State = archivalUnarchiving
+	r.UnarchivedAt = a.now()
+	err = a.persist()
+	if err != nil {
+		return time.Time{}, err
+	}
+	select {
+	case a.kick <- struct{}{}:
+	default:
+	}
+	return a.readyAt(r), nil
+}
+
+// Describe returns the archival state of a workspace, or of all archived workspaces if workspaceID is empty
+func (a *archiver) Describe(workspaceID string) []*api.ArchivalStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var res []*api.ArchivalStatus
+	for _, r := range a.records {
+		if workspaceID != "" && r.WorkspaceID != workspaceID {
+			continue
+		}
+		if workspaceID == "" && r.State == archivalStopped {
+			continue
+		}
+
+		s := &api.ArchivalStatus{
+			WorkspaceId:    r.WorkspaceID,
+			Owner:          r.Owner,
+			Phase:          api.WorkspacePhase_ARCHIVED,
+			StoppedAt:      archivalTimestamp(r.StoppedAt),
+			ArchivedAt:     archivalTimestamp(r.ArchivedAt),
+			BackupSize:     r.BackupSize,
+			Unarchiving:    r.State == archivalUnarchiving,
+			RestoreLatency: time.Duration(a.Config.RestoreLatency).String(),
+		}
+		if r.State == archivalStopped {
+			s.Phase = api.WorkspacePhase_STOPPED
+			s.ArchiveAt = archivalTimestamp(r.StoppedAt.Add(time.Duration(a.Config.After)))
+		}
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].WorkspaceId < res[j].WorkspaceId })
+	return res
+}
+
+func archivalTimestamp(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	res, _ := ptypes.TimestampProto(t)
+	return res
+}
+
+// Start archives and unarchives workspaces in the background until Close is called
+func (a *archiver) Start() {
+	var ctx context.Context
+	ctx, a.cancel = context.WithCancel(context.Background())
+	go a.Run(ctx)
+}
+
+// Close stops archiving workspaces
+func (a *archiver) Close() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// Run archives and unarchives workspaces until ctx is canceled
+func (a *archiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(a.Config.Interval))
+	defer ticker.Stop()
+	for {
+		a.archive(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-a.kick:
+		}
+	}
+}
+
+// archive moves the backups of workspaces which have been stopped for long to cold storage, and those of workspaces
+// which are unarchiving back. Workspaces which were archiving when ws-manager stopped are archived again.
+func (a *archiver) archive(ctx context.Context) {
+	a.mu.Lock()
+	var (
+		due []archivalRecord
+		now = a.now()
+	)
+	for _, r := range a.records {
+		if r.State == archivalStopped && now.Sub(r.StoppedAt) >= time.Duration(a.Config.After) {
+			r.State = archivalArchiving
+		}
+		if r.State == archivalArchiving || r.State == archivalUnarchiving {
+			due = append(due, *r)
+		}
+	}
+	if len(due) > 0 {
+		err := a.persist()
+		if err != nil {
+			log.WithError(err).Error("cannot persist archival state")
+		}
+	}
+	a.mu.Unlock()
+
+	for _, r := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		class := a.Config.ColdStorageClass
+		if r.State == archivalUnarchiving {
+			class = a.Config.WarmStorageClass
+		}
+		size, err := a.Storage.SetStorageClass(ctx, a.Storage.Bucket(r.Owner), storage.WorkspacePrefix(r.WorkspaceID), class)
+		if err != nil {
+			// we try again during the next round
+			log.WithError(err).WithFields(log.OWI(r.Owner, r.WorkspaceID, "")).WithField("state", r.State).Warn("cannot move workspace backup between storage classes")
+			continue
+		}
+
+		a.mu.Lock()
+		cur, ok := a.records[r.WorkspaceID]
+		switch {
+		case !ok:
+		case cur.State == archivalArchiving:
+			cur.State = archivalArchived
+			cur.ArchivedAt = a.now()
+			cur.BackupSize = size
+		case cur.State == archivalUnarchiving && r.State == archivalUnarchiving:
+			cur.State = archivalStopped
+			cur.StoppedAt = a.now()
+			cur.ArchivedAt = time.Time{}
+			cur.UnarchivedAt = time.Time{}
+		}
+		err = a.persist()
+		a.mu.Unlock()
+		if err != nil {
+			log.WithError(err).Error("cannot persist archival state")
+		}
+		log.WithFields(log.OWI(r.Owner, r.WorkspaceID, "")).WithField("state", r.State).WithField("size", size).Info("moved workspace backup between storage classes")
+
+		if ok && cur.State == archivalUnarchiving && r.State == archivalArchiving {
+			// the owner asked for the workspace to be unarchived while we archived it
+			select {
+			case a.kick <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// newArchivedWorkspacesVec reports the workspaces we keep track of by their archival state
+func newArchivedWorkspacesVec(m *Manager) *archivedWorkspacesVec {
+	return &archivedWorkspacesVec{
+		manager: m,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, metricsWorkspaceSubsystem, "archival_state_total"),
+			"stopped regular workspaces by the state of their backup",
+			[]string{"state"},
+			prometheus.Labels(map[string]string{}),
+		),
+	}
+}
+
+type archivedWorkspacesVec struct {
+	manager *Manager
+	desc    *prometheus.Desc
+}
+
+// Describe implements Collector. It will send exactly one Desc to the provided channel.
+func (v *archivedWorkspacesVec) Describe(ch chan<- *prometheus.Desc) {
+	ch <- v.desc
+}
+
+// Collect implements Collector.
+func (v *archivedWorkspacesVec) Collect(ch chan<- prometheus.Metric) {
+	a := v.manager.archiver
+	if a == nil {
+		return
+	}
+
+	counts := map[archivalState]int{
+		archivalStopped:     0,
+		archivalArchiving:   0,
+		archivalArchived:    0,
+		archivalUnarchiving: 0,
+	}
+	a.mu.Lock()
+	for _, r := range a.records {
+		counts[r.State]++
+	}
+	a.mu.Unlock()
+
+	for state, count := range counts {
+		metric, err := prometheus.NewConstMetric(v.desc, prometheus.GaugeValue, float64(count), string(state))
+		if err != nil {
+			continue
+		}
+		ch <- metric
+	}
+}
+
+// onArchivalChange records regular workspaces whose last instance stopped with a complete backup
+func (m *Manager) onArchivalChange(status *api.WorkspaceStatus) {
+	if m.archiver == nil || status.Phase != api.WorkspacePhase_STOPPED {
+		return
+	}
+	if status.Spec.GetType() != api.WorkspaceType_REGULAR || status.Conditions.GetFinalBackupComplete() != api.WorkspaceConditionBool_TRUE {
+		return
+	}
+
+	err := m.archiver.Stopped(status.Metadata.GetMetaId(), status.Metadata.GetOwner())
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI(status.Metadata.GetOwner(), status.Metadata.GetMetaId(), status.Id)).Error("cannot record stopped workspace for archival")
+	}
+}
+
+// admitArchivedWorkspaceStart refuses to start workspaces whose backup is in cold storage
+func (m *Manager) admitArchivedWorkspaceStart(req *api.StartWorkspaceRequest) error {
+	if m.archiver == nil || req.Type != api.WorkspaceType_REGULAR {
+		return nil
+	}
+	return m.archiver.AdmitStart(req.Metadata.MetaId)
+}
+
+// DescribeArchival tells whether stopped workspaces have been archived to cold storage
+func (m *Manager) DescribeArchival(ctx context.Context, req *api.DescribeArchivalRequest) (res *api.DescribeArchivalResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "DescribeArchival")
+	span.SetTag("workspaceId", req.WorkspaceId)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.archiver == nil {
+		return nil, status.Error(codes.Unimplemented, "archival is disabled")
+	}
+
+	res = &api.DescribeArchivalResponse{Workspaces: m.archiver.Describe(req.WorkspaceId)}
+	if req.WorkspaceId != "" && len(res.Workspaces) == 0 {
+		return nil, status.Errorf(codes.NotFound, "workspace %s is not known to have stopped", req.WorkspaceId)
+	}
+	return res, nil
+}
+
+// UnarchiveWorkspace moves the backup of an archived workspace out of cold storage
+func (m *Manager) UnarchiveWorkspace(ctx context.Context, req *api.UnarchiveWorkspaceRequest) (res *api.UnarchiveWorkspaceResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "UnarchiveWorkspace")
+	span.SetTag("workspaceId", req.WorkspaceId)
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "UnarchiveWorkspace", Metadata: &api.WorkspaceMetadata{MetaId: req.WorkspaceId}}, err)
+	}()
+
+	if m.archiver == nil {
+		return nil, status.Error(codes.Unimplemented, "archival is disabled")
+	}
+	if req.WorkspaceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "workspace ID is required")
+	}
+
+	readyAt, err := m.archiver.Unarchive(req.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	log.WithFields(log.OWI("", req.WorkspaceId, "")).WithField("readyAt", readyAt).Info("unarchiving workspace")
+
+	latency := time.Until(readyAt)
+	if latency < 0 {
+		latency = 0
+	}
+	return &api.UnarchiveWorkspaceResponse{
+		ReadyAt:        archivalTimestamp(readyAt),
+		RestoreLatency: latency.Round(time.Second).String(),
+	}, nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+type archivalTestStorage struct {
+	storage.PresignedNoopStorage
+
+	mu      sync.Mutex
+	classes map[string]string
+	fail    bool
+}
+
+func (s *archivalTestStorage) SetStorageClass(ctx context.Context, bucket, prefix, class string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return 0, xerrors.Errorf("storage unavailable")
+	}
+	s.classes[bucket+"/"+prefix] = class
+	return 42, nil
+}
+
+func (s *archivalTestStorage) Class(owner, workspaceID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.classes[s.Bucket(owner)+"/"+storage.WorkspacePrefix(workspaceID)]
+}
+
+func newArchivalTestArchiver(t *testing.T, fn string, s storage.PresignedAccess, now *time.Time) *archiver {
+	a, err := newArchiver(ArchivalConfig{
+		StatePath:        fn,
+		After:            util.Duration(14 * 24 * time.Hour),
+		ColdStorageClass: "ARCHIVE",
+		RestoreLatency:   util.Duration(12 * time.Hour),
+	}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.now = func() time.Time { return *now }
+	return a
+}
+
+func TestArchiver(t *testing.T) {
+	var (
+		fn  = filepath.Join(t.TempDir(), "archival.json")
+		s   = &archivalTestStorage{classes: make(map[string]string)}
+		now = time.Now()
+		ctx = context.Background()
+	)
+	a := newArchivalTestArchiver(t, fn, s, &now)
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(a.Stopped("ws-a", "owner"))
+	must(a.Stopped("ws-b", "owner"))
+
+	// restarting a stopped workspace means we no longer archive it
+	must(a.AdmitStart("ws-b"))
+	if res := a.Describe("ws-b"); len(res) != 0 {
+		t.Errorf("expected started workspace to be forgotten, got %v", res)
+	}
+
+	a.archive(ctx)
+	if res := a.Describe("ws-a"); len(res) != 1 || res[0].Phase != api.WorkspacePhase_STOPPED || res[0].ArchiveAt == nil {
+		t.Fatalf("expected workspace to remain stopped, got %v", res)
+	}
+
+	// failing to move the backup leaves the workspace stopped until the next attempt
+	now = now.Add(15 * 24 * time.Hour)
+	s.fail = true
+	a.archive(ctx)
+	if res := a.Describe(""); len(res) != 1 || res[0].Phase != api.WorkspacePhase_ARCHIVED || res[0].ArchivedAt != nil {
+		t.Fatalf("expected workspace to be archiving, got %v", res)
+	}
+	s.fail = false
+	a.archive(ctx)
+	if res := a.Describe(""); len(res) != 1 || res[0].ArchivedAt == nil || res[0].BackupSize != 42 {
+		t.Fatalf("expected workspace to be archived, got %v", res)
+	}
+	if c := s.Class("owner", "ws-a"); c != "ARCHIVE" {
+		t.Errorf("expected backup to be in ARCHIVE, got %q", c)
+	}
+
+	err := a.AdmitStart("ws-a")
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.FailedPrecondition {
+		t.Fatalf("expected archived workspace start to fail with FailedPrecondition, got %v", err)
+	}
+
+	// the archival state survives restarts
+	a = newArchivalTestArchiver(t, fn, s, &now)
+	readyAt, err := a.Unarchive("ws-a")
+	must(err)
+	if exp := now.Add(12 * time.Hour); !readyAt.Equal(exp) {
+		t.Errorf("expected workspace to be ready at %v, got %v", exp, readyAt)
+	}
+	// unarchiving is idempotent
+	now = now.Add(time.Hour)
+	again, err := a.Unarchive("ws-a")
+	must(err)
+	if !again.Equal(readyAt) {
+		t.Errorf("expected repeated unarchive to keep ready time %v, got %v", readyAt, again)
+	}
+	if res := a.Describe("ws-a"); len(res) != 1 || !res[0].Unarchiving {
+		t.Fatalf("expected workspace to be unarchiving, got %v", res)
+	}
+	if err := a.AdmitStart("ws-a"); err == nil {
+		t.Fatal("expected unarchiving workspace start to fail")
+	}
+
+	a.archive(ctx)
+	if c := s.Class("owner", "ws-a"); c != defaultArchivalWarmStorageClass {
+		t.Errorf("expected backup to be in %s, got %q", defaultArchivalWarmStorageClass, c)
+	}
+	if res := a.Describe("ws-a"); len(res) != 1 || res[0].Phase != api.WorkspacePhase_STOPPED {
+		t.Fatalf("expected workspace to be stopped again, got %v", res)
+	}
+	must(a.AdmitStart("ws-a"))
+
+	_, err = a.Unarchive("ws-c")
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+		t.Errorf("expected unknown workspace to be NotFound, got %v", err)
+	}
+}
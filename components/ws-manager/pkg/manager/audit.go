@@ -0,0 +1,585 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultAuditMaxFileSize = 64 << 20
+	defaultAuditMaxFiles    = 5
+	defaultAuditQueryLimit  = 100
+	maxAuditQueryLimit      = 1000
+
+	// auditRecentRecords is the number of records we keep in memory to serve subscribers without reading the audit files
+	auditRecentRecords = 1000
+	// auditMaxLineSize is the size of the largest audit record we can read back
+	auditMaxLineSize = 1 << 20
+	// auditMetadataTimeout limits how long we look for the metadata of a workspace we record an action for
+	auditMetadataTimeout = 5 * time.Second
+
+	// auditSelf is the actor and source of the actions ws-manager takes on its own, e.g. stopping a workspace which timed out
+	auditSelf = "ws-manager"
+)
+
+// AuditLogConfig enables the audit log of the control actions which affect workspaces, e.g. starting, stopping
+// or changing the timeout of a workspace
+type AuditLogConfig struct {
+	// Path is the file we append audit records to. Older records are in Path.1 up to Path.<MaxFiles-1>.
+	// It must be on a persistent volume, otherwise we lose the audit log when ws-manager restarts.
+	Path string `json:"path"`
+	// MaxFileSize is the size in bytes at which we start a new file. Defaults to 64 MiB.
+	MaxFileSize int64 `json:"maxFileSize,omitempty"`
+	// MaxFiles is the number of files we keep, including the one we append to. Defaults to 5.
+	// Consume SubscribeAuditLog to keep records for longer, e.g. in a SIEM.
+	MaxFiles int `json:"maxFiles,omitempty"`
+	// Log writes every record to the log as well, for SIEM pipelines which consume logs
+	Log bool `json:"log,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *AuditLogConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Path, validation.Required),
+		validation.Field(&c.MaxFileSize, validation.Min(int64(0))),
+		validation.Field(&c.MaxFiles, validation.Min(0)),
+	)
+}
+
+// auditLog is an append-only log of audit records. Every record is persisted before anyone can see it.
+type auditLog struct {
+	Config AuditLogConfig
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	last uint64
+	// recent are the most recent records ordered by their sequence
+	recent []*api.AuditRecord
+	// changed is closed and replaced whenever we add a record
+	changed chan struct{}
+}
+
+// openAuditLog continues the audit log at cfg.Path, or starts a new one if there is none
+func openAuditLog(cfg AuditLogConfig) (*auditLog, error) {
+	if cfg.MaxFileSize == 0 {
+		cfg.MaxFileSize = defaultAuditMaxFileSize
+	}
+	if cfg.MaxFiles == 0 {
+		cfg.MaxFiles = defaultAuditMaxFiles
+	}
+	l := &auditLog{
+		Config:  cfg,
+		changed: make(chan struct{}),
+	}
+
+	err := l.restore()
+	if err != nil {
+		return nil, err
+	}
+	l.f, err = os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot open audit log: %w", err)
+	}
+	stat, err := l.f.Stat()
+	if err != nil {
+		l.f.Close()
+		return nil, xerrors.Errorf("cannot open audit log: %w", err)
+	}
+	l.size = stat.Size()
+	return l, nil
+}
+
+// files returns the names of the audit files, oldest first. The last one is the file we append to.
+func (l *auditLog) files() []string {
+	res := make([]string, 0, l.Config.MaxFiles)
+	for i := l.Config.MaxFiles - 1; i > 0; i-- {
+		res = append(res, fmt.Sprintf("%s.%d", l.Config.Path, i))
+	}
+	return append(res, l.Config.Path)
+}
+
+// restore finds the last sequence and the recent records. Callers must have exclusive access.
+func (l *auditLog) restore() error {
+	fc, err := os.ReadFile(l.Config.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("cannot read audit log: %w", err)
+	}
+	if idx := bytes.LastIndexByte(fc, '\n'); idx < len(fc)-1 {
+		// ws-manager stopped while writing the last record. Appending to it would corrupt the next record, too.
+		log.WithField("path", l.Config.Path).Warn("dropping incomplete last record of the audit log")
+		fc = fc[:idx+1]
+		err = os.Truncate(l.Config.Path, int64(len(fc)))
+		if err != nil {
+			return xerrors.Errorf("cannot truncate audit log: %w", err)
+		}
+	}
+	err = scanAuditRecords(bytes.NewReader(fc), func(rec *api.AuditRecord) bool {
+		l.addRecent(rec)
+		return true
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot read audit log: %w", err)
+	}
+	if len(l.recent) > 0 {
+		l.last = l.recent[len(l.recent)-1].Sequence
+		return nil
+	}
+
+	// we have just rotated the audit log - the last sequence is in the files before
+	files := l.files()
+	for i := len(files) - 2; i >= 0; i-- {
+		f, err := os.Open(files[i])
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("cannot read audit log: %w", err)
+		}
+		err = scanAuditRecords(f, func(rec *api.AuditRecord) bool {
+			l.last = rec.Sequence
+			return true
+		})
+		f.Close()
+		if err != nil {
+			return xerrors.Errorf("cannot read audit log: %w", err)
+		}
+		if l.last > 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// scanAuditRecords calls fn for every record in r until fn returns false. Lines which are no records
+// are skipped: the last line of the file we append to may be incomplete while we read it.
+func scanAuditRecords(r io.Reader, fn func(rec *api.AuditRecord) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), auditMaxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var rec api.AuditRecord
+		err := jsonpb.Unmarshal(bytes.NewReader(line), &rec)
+		if err != nil {
+			log.WithError(err).Debug("skipping invalid line of the audit log")
+			continue
+		}
+		if !fn(&rec) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// addRecent remembers a record for subscribers. Callers must hold mu or have exclusive access.
+func (l *auditLog) addRecent(rec *api.AuditRecord) {
+	l.recent = append(l.recent, rec)
+	if len(l.recent) > 2*auditRecentRecords {
+		l.recent = append([]*api.AuditRecord(nil), l.recent[len(l.recent)-auditRecentRecords:]...)
+	}
+}
+
+// Add persists a record and notifies everyone waiting for records
+func (l *auditLog) Add(rec *api.AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return xerrors.Errorf("audit log is closed")
+	}
+	rec.Sequence = l.last + 1
+	line, err := (&jsonpb.Marshaler{}).MarshalToString(rec)
+	if err != nil {
+		return err
+	}
+	if l.size > 0 && l.size+int64(len(line))+1 > l.Config.MaxFileSize {
+		err = l.rotate()
+		if err != nil {
+			return xerrors.Errorf("cannot rotate audit log: %w", err)
+		}
+	}
+	n, err := l.f.Write(append([]byte(line), '\n'))
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	// a record we have not persisted must not reach anyone
+	err = l.f.Sync()
+	if err != nil {
+		return err
+	}
+
+	l.last = rec.Sequence
+	l.addRecent(rec)
+	close(l.changed)
+	l.changed = make(chan struct{})
+	return nil
+}
+
+// rotate starts a new file. The oldest file is removed. Callers must hold mu.
+func (l *auditLog) rotate() error {
+	err := l.f.Close()
+	l.f = nil
+	if err != nil {
+		return err
+	}
+
+	files := l.files()
+	if len(files) == 1 {
+		err = os.Remove(files[0])
+	}
+	for i := 0; i < len(files)-1 && err == nil; i++ {
+		err = os.Rename(files[i+1], files[i])
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	l.f, err = os.OpenFile(l.Config.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	l.size = 0
+	return nil
+}
+
+// Query returns up to limit records after a sequence which match, oldest first, and whether there are more
+func (l *auditLog) Query(match func(rec *api.AuditRecord) bool, after uint64, limit int) (res []*api.AuditRecord, more bool, err error) {
+	// The files we opened stay intact when we rotate, hence we need not hold the lock while reading them.
+	// We miss the records added while we read, as if they had been added after the query.
+	l.mu.Lock()
+	var fs []*os.File
+	for _, fn := range l.files() {
+		f, err := os.Open(fn)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			l.mu.Unlock()
+			for _, f := range fs {
+				f.Close()
+			}
+			return nil, false, err
+		}
+		fs = append(fs, f)
+	}
+	l.mu.Unlock()
+	defer func() {
+		for _, f := range fs {
+			f.Close()
+		}
+	}()
+
+	for _, f := range fs {
+		err = scanAuditRecords(f, func(rec *api.AuditRecord) bool {
+			if rec.Sequence <= after || !match(rec) {
+				return true
+			}
+			if len(res) == limit {
+				more = true
+				return false
+			}
+			res = append(res, rec)
+			return true
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		if more {
+			break
+		}
+	}
+	return res, more, nil
+}
+
+// Since returns the records after a sequence and a channel which is closed once there are more.
+// If the records are not in memory anymore, Since returns them from the files in pages, with a closed channel.
+func (l *auditLog) Since(sequence uint64) ([]*api.AuditRecord, <-chan struct{}, error) {
+	l.mu.Lock()
+	if sequence > l.last {
+		l.mu.Unlock()
+		return nil, nil, status.Errorf(codes.OutOfRange, "there is no record %d yet, the last one is %d", sequence, l.last)
+	}
+	if sequence == l.last || (len(l.recent) > 0 && sequence+1 >= l.recent[0].Sequence) {
+		var res []*api.AuditRecord
+		for _, rec := range l.recent {
+			if rec.Sequence > sequence {
+				res = append(res, rec)
+			}
+		}
+		changed := l.changed
+		l.mu.Unlock()
+		return res, changed, nil
+	}
+	l.mu.Unlock()
+
+	res, _, err := l.Query(func(*api.AuditRecord) bool { return true }, sequence, maxAuditQueryLimit)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "cannot read audit log: %v", err)
+	}
+	if len(res) == 0 {
+		// the records between sequence and the ones we have in memory were rotated away already
+		return l.Since(l.oldestRecent() - 1)
+	}
+	next := make(chan struct{})
+	close(next)
+	return res, next, nil
+}
+
+func (l *auditLog) oldestRecent() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.recent) == 0 {
+		return l.last + 1
+	}
+	return l.recent[0].Sequence
+}
+
+// Close closes the audit file
+func (l *auditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// auditRecordMatcher returns a function which tells whether a record matches the criteria of a query
+func auditRecordMatcher(req *api.QueryAuditLogRequest) (func(rec *api.AuditRecord) bool, error) {
+	var since, until time.Time
+	if req.Since != nil {
+		t, err := ptypes.Timestamp(req.Since)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid since: %w", err)
+		}
+		since = t
+	}
+	if req.Until != nil {
+		t, err := ptypes.Timestamp(req.Until)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid until: %w", err)
+		}
+		until = t
+	}
+	actions := make(map[string]struct{}, len(req.Actions))
+	for _, a := range req.Actions {
+		actions[a] = struct{}{}
+	}
+
+	return func(rec *api.AuditRecord) bool {
+		if req.Id != "" && rec.Id != req.Id {
+			return false
+		}
+		if req.MetaId != "" && rec.Metadata.GetMetaId() != req.MetaId {
+			return false
+		}
+		if req.Owner != "" && rec.Metadata.GetOwner() != req.Owner {
+			return false
+		}
+		if _, ok := actions[rec.Action]; len(actions) > 0 && !ok {
+			return false
+		}
+		if since.IsZero() && until.IsZero() {
+			return true
+		}
+		t, err := ptypes.Timestamp(rec.Time)
+		if err != nil {
+			return false
+		}
+		return !t.Before(since) && (until.IsZero() || !t.After(until))
+	}, nil
+}
+
+// auditCaller is who asked for a control action
+type auditCaller struct {
+	Actor  string
+	Source string
+	Peer   string
+	// BulkOperation is the bulk operation the action is part of, if any
+	BulkOperation string
+}
+
+type auditCallerKey struct{}
+
+// withAuditCaller attributes the actions taken with the returned context to a caller, e.g. the caller
+// who started a bulk operation
+func withAuditCaller(ctx context.Context, caller auditCaller) context.Context {
+	return context.WithValue(ctx, auditCallerKey{}, caller)
+}
+
+// auditCallerFromContext determines the caller from the gRPC request of the context. Actions without
+// a request are the ones ws-manager takes on its own.
+func auditCallerFromContext(ctx context.Context) auditCaller {
+	if caller, ok := ctx.Value(auditCallerKey{}).(auditCaller); ok {
+		return caller
+	}
+
+	var res auditCaller
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(api.AuditActorMetadataKey); len(v) > 0 {
+			res.Actor = v[0]
+		}
+		if v := md.Get(api.AuditSourceMetadataKey); len(v) > 0 {
+			res.Source = v[0]
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		if res.Actor == "" {
+			res.Actor = auditSelf
+		}
+		if res.Source == "" {
+			res.Source = auditSelf
+		}
+		return res
+	}
+	if p.Addr != nil {
+		res.Peer = p.Addr.String()
+	}
+	// a verified client certificate is more trustworthy than what the client claims
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		chains := tlsInfo.State.VerifiedChains
+		if len(chains) > 0 && len(chains[0]) > 0 && chains[0][0].Subject.CommonName != "" {
+			res.Source = chains[0][0].Subject.CommonName
+		}
+	}
+	return res
+}
+
+// audit records a control action which affected a workspace. err is the outcome of the action.
+// If the record names an instance but has no metadata, we look the metadata up.
+func (m *Manager) audit(ctx context.Context, rec *api.AuditRecord, err error) {
+	if m.auditLog == nil {
+		return
+	}
+
+	caller := auditCallerFromContext(ctx)
+	rec.Actor, rec.Source, rec.Peer = caller.Actor, caller.Source, caller.Peer
+	rec.Time = ptypes.TimestampNow()
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if caller.BulkOperation != "" {
+		if rec.Parameters == nil {
+			rec.Parameters = make(map[string]string)
+		}
+		rec.Parameters["bulkOperation"] = caller.BulkOperation
+	}
+	if rec.Metadata == nil && rec.Id != "" {
+		// the context of the action may be done already
+		lctx, cancel := context.WithTimeout(context.Background(), auditMetadataTimeout)
+		pod, perr := m.findWorkspacePod(lctx, rec.Id)
+		cancel()
+		if perr == nil {
+			rec.Metadata = getWorkspaceMetadata(pod)
+		}
+	}
+
+	clog := log.WithFields(log.OWI(rec.Metadata.GetOwner(), rec.Metadata.GetMetaId(), rec.Id)).WithField("action", rec.Action)
+	werr := m.auditLog.Add(rec)
+	if werr != nil {
+		// the log is our last resort to keep the record
+		clog.WithError(werr).WithField("audit", rec).Error("cannot record control action in the audit log")
+		return
+	}
+	if m.auditLog.Config.Log {
+		clog.WithField("audit", rec).Info("audit")
+	}
+}
+
+// QueryAuditLog returns the recorded control actions which match a filter, oldest first
+func (m *Manager) QueryAuditLog(ctx context.Context, req *api.QueryAuditLogRequest) (res *api.QueryAuditLogResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "QueryAuditLog")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.auditLog == nil {
+		return nil, status.Error(codes.Unimplemented, "audit log is disabled")
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultAuditQueryLimit
+	}
+	if limit > maxAuditQueryLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be at most %d", maxAuditQueryLimit)
+	}
+	match, err := auditRecordMatcher(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	records, more, err := m.auditLog.Query(match, req.AfterSequence, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot read audit log: %v", err)
+	}
+	return &api.QueryAuditLogResponse{Records: records, More: more}, nil
+}
+
+// SubscribeAuditLog streams the audit log from a sequence on, including the records added while streaming
+func (m *Manager) SubscribeAuditLog(req *api.SubscribeAuditLogRequest, srv api.WorkspaceManager_SubscribeAuditLogServer) error {
+	if m.auditLog == nil {
+		return status.Error(codes.Unimplemented, "audit log is disabled")
+	}
+
+	ctx := srv.Context()
+	cursor := req.AfterSequence
+	for {
+		records, changed, err := m.auditLog.Since(cursor)
+		if err != nil {
+			return err
+		}
+		for _, rec := range records {
+			err = srv.Send(rec)
+			if err != nil {
+				return err
+			}
+			cursor = rec.Sequence
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
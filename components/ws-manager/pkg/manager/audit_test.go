@@ -0,0 +1,284 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func auditSequences(records []*api.AuditRecord) []uint64 {
+	res := make([]uint64, len(records))
+	for i, r := range records {
+		res[i] = r.Sequence
+	}
+	return res
+}
+
+func addAuditRecords(t *testing.T, l *auditLog, records ...*api.AuditRecord) {
+	t.Helper()
+	for _, rec := range records {
+		err := l.Add(rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := openAuditLog(AuditLogConfig{Path: fn})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t0 := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *api.AuditRecord {
+		ts, _ := ptypes.TimestampProto(t0.Add(d))
+		return &api.AuditRecord{Time: ts}
+	}
+	records := []*api.AuditRecord{
+		{Action: "StartWorkspace", Id: "a", Metadata: &api.WorkspaceMetadata{Owner: "alice", MetaId: "meta-a"}},
+		{Action: "StopWorkspace", Id: "a", Metadata: &api.WorkspaceMetadata{Owner: "alice", MetaId: "meta-a"}},
+		{Action: "StartWorkspace", Id: "b", Metadata: &api.WorkspaceMetadata{Owner: "bob", MetaId: "meta-b"}},
+		{Action: "SetMaintenance"},
+	}
+	for i, rec := range records {
+		rec.Time = at(time.Duration(i) * time.Minute).Time
+	}
+	addAuditRecords(t, l, records...)
+
+	tests := []struct {
+		Name   string
+		Req    *api.QueryAuditLogRequest
+		After  uint64
+		Limit  int
+		Expect []uint64
+		More   bool
+	}{
+		{Name: "all", Req: &api.QueryAuditLogRequest{}, Limit: 10, Expect: []uint64{1, 2, 3, 4}},
+		{Name: "instance", Req: &api.QueryAuditLogRequest{Id: "a"}, Limit: 10, Expect: []uint64{1, 2}},
+		{Name: "workspace", Req: &api.QueryAuditLogRequest{MetaId: "meta-b"}, Limit: 10, Expect: []uint64{3}},
+		{Name: "owner", Req: &api.QueryAuditLogRequest{Owner: "alice"}, Limit: 10, Expect: []uint64{1, 2}},
+		{Name: "actions", Req: &api.QueryAuditLogRequest{Actions: []string{"StartWorkspace", "SetMaintenance"}}, Limit: 10, Expect: []uint64{1, 3, 4}},
+		{Name: "time range", Req: &api.QueryAuditLogRequest{Since: at(time.Minute).Time, Until: at(2 * time.Minute).Time}, Limit: 10, Expect: []uint64{2, 3}},
+		{Name: "first page", Req: &api.QueryAuditLogRequest{}, Limit: 2, Expect: []uint64{1, 2}, More: true},
+		{Name: "last page", Req: &api.QueryAuditLogRequest{}, After: 2, Limit: 2, Expect: []uint64{3, 4}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			match, err := auditRecordMatcher(test.Req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, more, err := l.Query(match, test.After, test.Limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expect, auditSequences(res)); diff != "" {
+				t.Errorf("unexpected records (-want +got):\n%s", diff)
+			}
+			if more != test.More {
+				t.Errorf("more is %v, expected %v", more, test.More)
+			}
+		})
+	}
+
+	// a record ws-manager did not finish writing when it stopped is dropped, and the sequence continues
+	l.Close()
+	f, err := os.OpenFile(fn, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"sequence":"5","act`)
+	f.Close()
+
+	l, err = openAuditLog(AuditLogConfig{Path: fn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	rec := &api.AuditRecord{Action: "StopWorkspace", Id: "b"}
+	addAuditRecords(t, l, rec)
+	if rec.Sequence != 5 {
+		t.Errorf("sequence did not continue after restart: %d", rec.Sequence)
+	}
+	res, _, err := l.Query(func(*api.AuditRecord) bool { return true }, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]uint64{1, 2, 3, 4, 5}, auditSequences(res)); diff != "" {
+		t.Errorf("unexpected records after restart (-want +got):\n%s", diff)
+	}
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := AuditLogConfig{Path: fn, MaxFileSize: 200, MaxFiles: 3}
+	l, err := openAuditLog(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		addAuditRecords(t, l, &api.AuditRecord{Action: "StopWorkspace", Id: "some-workspace-instance", Actor: "someone"})
+	}
+	l.Close()
+
+	if _, err := os.Stat(fn + ".3"); !os.IsNotExist(err) {
+		t.Errorf("kept more files than configured: %v", err)
+	}
+	l, err = openAuditLog(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	res, more, err := l.Query(func(*api.AuditRecord) bool { return true }, 0, maxAuditQueryLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if more || len(res) == 0 || len(res) >= 20 {
+		t.Fatalf("expected some of the records to be rotated away, got %v", auditSequences(res))
+	}
+	for i, rec := range res {
+		if rec.Sequence != res[0].Sequence+uint64(i) {
+			t.Fatalf("retained records are not contiguous: %v", auditSequences(res))
+		}
+	}
+	if res[len(res)-1].Sequence != 20 {
+		t.Errorf("lost the most recent records: %v", auditSequences(res))
+	}
+
+	rec := &api.AuditRecord{Action: "StopWorkspace"}
+	addAuditRecords(t, l, rec)
+	if rec.Sequence != 21 {
+		t.Errorf("sequence did not continue after restart: %d", rec.Sequence)
+	}
+}
+
+func TestAuditLogSince(t *testing.T) {
+	l, err := openAuditLog(AuditLogConfig{Path: filepath.Join(t.TempDir(), "audit.jsonl")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	_, _, err = l.Since(1)
+	if status.Code(err) != codes.OutOfRange {
+		t.Errorf("expected OutOfRange for a sequence we do not have yet, got %v", err)
+	}
+
+	records, changed, err := l.Since(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %v", auditSequences(records))
+	}
+	addAuditRecords(t, l, &api.AuditRecord{Action: "StartWorkspace"}, &api.AuditRecord{Action: "StopWorkspace"})
+	select {
+	case <-changed:
+	default:
+		t.Fatal("adding a record did not notify subscribers")
+	}
+
+	records, _, err = l.Since(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]uint64{2}, auditSequences(records)); diff != "" {
+		t.Errorf("unexpected records (-want +got):\n%s", diff)
+	}
+}
+
+func TestAuditCallerFromContext(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}
+	verified := credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "server"}}}},
+	}}
+	withPeer := func(ctx context.Context, auth credentials.AuthInfo) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{Addr: addr, AuthInfo: auth})
+	}
+	claims := func(actor, source string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(api.AuditActorMetadataKey, actor, api.AuditSourceMetadataKey, source))
+	}
+
+	tests := []struct {
+		Name   string
+		Ctx    context.Context
+		Expect auditCaller
+	}{
+		{Name: "ws-manager itself", Ctx: context.Background(), Expect: auditCaller{Actor: auditSelf, Source: auditSelf}},
+		{Name: "anonymous caller", Ctx: withPeer(context.Background(), nil), Expect: auditCaller{Peer: addr.String()}},
+		{Name: "claimed caller", Ctx: withPeer(claims("user-1", "server"), nil), Expect: auditCaller{Actor: "user-1", Source: "server", Peer: addr.String()}},
+		{Name: "client certificate", Ctx: withPeer(claims("user-1", "ws-proxy"), verified), Expect: auditCaller{Actor: "user-1", Source: "server", Peer: addr.String()}},
+		{
+			Name:   "bulk operation",
+			Ctx:    withAuditCaller(context.Background(), auditCaller{Actor: "admin", Source: "server", BulkOperation: "op"}),
+			Expect: auditCaller{Actor: "admin", Source: "server", BulkOperation: "op"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := auditCallerFromContext(test.Ctx)
+			if diff := cmp.Diff(test.Expect, act); diff != "" {
+				t.Errorf("unexpected caller (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestManagerAudit(t *testing.T) {
+	var m Manager
+	// without an audit log there is nothing to record
+	m.audit(context.Background(), &api.AuditRecord{Action: "StopWorkspace"}, nil)
+
+	l, err := openAuditLog(AuditLogConfig{Path: filepath.Join(t.TempDir(), "audit.jsonl")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	m.auditLog = l
+
+	ctx := withAuditCaller(context.Background(), auditCaller{Actor: "admin", Source: "server", BulkOperation: "op"})
+	m.audit(ctx, &api.AuditRecord{
+		Action:   "StopWorkspace",
+		Id:       "a",
+		Metadata: &api.WorkspaceMetadata{Owner: "alice", MetaId: "meta-a"},
+	}, xerrors.Errorf("cannot stop workspace"))
+
+	records, _, err := l.Since(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.Time == nil {
+		t.Error("record has no time")
+	}
+	if rec.Actor != "admin" || rec.Source != "server" || rec.Error != "cannot stop workspace" || rec.Parameters["bulkOperation"] != "op" {
+		t.Errorf("unexpected record: %v", rec)
+	}
+}
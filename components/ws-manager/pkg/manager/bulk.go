@@ -0,0 +1,476 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultBulkConcurrency = 10
+	maxBulkConcurrency     = 50
+
+	// bulkOperationTimeout limits the operation on a single workspace
+	bulkOperationTimeout = 1 * time.Minute
+	// bulkOperationRetention is how long we keep completed bulk operations around for clients to look at
+	bulkOperationRetention = 1 * time.Hour
+)
+
+// bulkOperationFunc applies a bulk operation to a single workspace
+type bulkOperationFunc func(ctx context.Context, workspaceID string) error
+
+// bulkOperations keeps track of the bulk operations of this ws-manager instance. We keep them in memory only,
+// i.e. a restart of ws-manager forgets all bulk operations and stops those which are running.
+type bulkOperations struct {
+	mu  sync.Mutex
+	ops map[string]*bulkOperation
+
+	now func() time.Time
+}
+
+func newBulkOperations() *bulkOperations {
+	return &bulkOperations{
+		ops: make(map[string]*bulkOperation),
+		now: time.Now,
+	}
+}
+
+// bulkOperation applies an operation to a fixed set of workspaces
+type bulkOperation struct {
+	ID         string
+	Workspaces []*corev1.Pod
+	Created    time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	now    func() time.Time
+
+	mu        sync.Mutex
+	results   []*api.BulkOperationResult
+	state     api.BulkOperationState
+	completed time.Time
+	// changed is closed and replaced whenever a result comes in or the state changes
+	changed chan struct{}
+}
+
+// Start starts a bulk operation on the workspaces in the background, working on at most concurrency workspaces at a time
+func (b *bulkOperations) Start(workspaces []*corev1.Pod, concurrency int, fn bulkOperationFunc) *bulkOperation {
+	ctx, cancel := context.WithCancel(context.Background())
+	op := &bulkOperation{
+		ID:         uuid.New().String(),
+		Workspaces: workspaces,
+		Created:    b.now(),
+		ctx:        ctx,
+		cancel:     cancel,
+		now:        b.now,
+		state:      api.BulkOperationState_BULK_OPERATION_RUNNING,
+		changed:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	for id, o := range b.ops {
+		o.mu.Lock()
+		expired := o.state != api.BulkOperationState_BULK_OPERATION_RUNNING && b.now().Sub(o.completed) > bulkOperationRetention
+		o.mu.Unlock()
+		if expired {
+			delete(b.ops, id)
+		}
+	}
+	b.ops[op.ID] = op
+	b.mu.Unlock()
+
+	go op.run(concurrency, fn)
+	return op
+}
+
+// Get returns the bulk operation with the ID, or nil if there is none
+func (b *bulkOperations) Get(id string) *bulkOperation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.ops[id]
+}
+
+func (op *bulkOperation) run(concurrency int, fn bulkOperationFunc) {
+	var (
+		wg   sync.WaitGroup
+		sema = make(chan struct{}, concurrency)
+		next int
+	)
+dispatch:
+	for ; next < len(op.Workspaces); next++ {
+		select {
+		case sema <- struct{}{}:
+		case <-op.ctx.Done():
+			break dispatch
+		}
+		if op.ctx.Err() != nil {
+			<-sema
+			break
+		}
+
+		pod := op.Workspaces[next]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sema }()
+
+			// cancelling the bulk operation must not interrupt the workspaces we're working on already
+			ctx, cancel := context.WithTimeout(context.Background(), bulkOperationTimeout)
+			defer cancel()
+			err := fn(ctx, pod.Annotations[workspaceIDAnnotation])
+			if err != nil {
+				log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).WithField("bulkOperation", op.ID).Warn("bulk operation failed on workspace")
+			}
+			op.addResult(pod, err)
+		}()
+	}
+	wg.Wait()
+
+	for _, pod := range op.Workspaces[next:] {
+		op.addResult(pod, xerrors.Errorf("bulk operation was cancelled"))
+	}
+
+	state := api.BulkOperationState_BULK_OPERATION_COMPLETED
+	if op.ctx.Err() != nil {
+		state = api.BulkOperationState_BULK_OPERATION_CANCELLED
+	}
+	op.mu.Lock()
+	op.state = state
+	op.completed = op.now()
+	op.notify()
+	op.mu.Unlock()
+	op.cancel()
+
+	log.WithField("bulkOperation", op.ID).WithField("state", state.String()).Info("bulk operation done")
+}
+
+func (op *bulkOperation) addResult(pod *corev1.Pod, err error) {
+	res := &api.BulkOperationResult{
+		Id:       pod.Annotations[workspaceIDAnnotation],
+		Metadata: getWorkspaceMetadata(pod),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	res.CompletedAt, _ = ptypes.TimestampProto(op.now())
+	op.results = append(op.results, res)
+	op.notify()
+}
+
+// notify wakes everyone waiting for changes. Callers must hold mu.
+func (op *bulkOperation) notify() {
+	close(op.changed)
+	op.changed = make(chan struct{})
+}
+
+// Cancel stops the bulk operation from working on more workspaces
+func (op *bulkOperation) Cancel() {
+	op.cancel()
+}
+
+// Results returns the results from the n-th one on, the status of the operation after each of those results,
+// the current status of the operation and a channel which is closed once something changed.
+func (op *bulkOperation) Results(n int) (results []*api.BulkOperationResult, progress []*api.BulkOperationStatus, current *api.BulkOperationStatus, changed <-chan struct{}) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	var succeeded, failed uint32
+	count := func(r *api.BulkOperationResult) {
+		if r.Error == "" {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	if n > len(op.results) {
+		n = len(op.results)
+	}
+	for _, r := range op.results[:n] {
+		count(r)
+	}
+	results = op.results[n:]
+	for _, r := range results {
+		count(r)
+		progress = append(progress, op.status(api.BulkOperationState_BULK_OPERATION_RUNNING, succeeded, failed, time.Time{}))
+	}
+	current = op.status(op.state, succeeded, failed, op.completed)
+	return results, progress, current, op.changed
+}
+
+func (op *bulkOperation) status(state api.BulkOperationState, succeeded, failed uint32, completed time.Time) *api.BulkOperationStatus {
+	res := &api.BulkOperationStatus{
+		Id:        op.ID,
+		State:     state,
+		Total:     uint32(len(op.Workspaces)),
+		Succeeded: succeeded,
+		Failed:    failed,
+	}
+	res.CreatedAt, _ = ptypes.TimestampProto(op.Created)
+	if !completed.IsZero() {
+		res.CompletedAt, _ = ptypes.TimestampProto(completed)
+	}
+	return res
+}
+
+// selectBulkWorkspaces returns the workspace pods which match the filter, ordered by workspace ID.
+// We leave workspaces which are being deleted alone.
+func selectBulkWorkspaces(pods []corev1.Pod, filter *api.BulkOperationFilter) ([]*corev1.Pod, error) {
+	contains := func(vs []string, v string) bool {
+		if len(vs) == 0 {
+			return true
+		}
+		for _, s := range vs {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	var startedBefore time.Time
+	if filter.StartedBefore != nil {
+		t, err := ptypes.Timestamp(filter.StartedBefore)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid started_before: %w", err)
+		}
+		startedBefore = t
+	}
+
+	var res []*corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if _, ok := pod.Annotations[workspaceIDAnnotation]; !ok {
+			continue
+		}
+		if !contains(filter.Owners, pod.Labels[wsk8s.OwnerLabel]) || !contains(filter.Projects, pod.Annotations[workspaceProjectAnnotation]) {
+			continue
+		}
+		if !startedBefore.IsZero() && !pod.CreationTimestamp.Time.Before(startedBefore) {
+			continue
+		}
+		if len(filter.Types) > 0 {
+			tpe, err := (&workspaceObjects{Pod: pod}).WorkspaceType()
+			if err != nil {
+				continue
+			}
+			var matches bool
+			for _, t := range filter.Types {
+				if t == tpe {
+					matches = true
+					break
+				}
+			}
+			if !matches {
+				continue
+			}
+		}
+		res = append(res, pod)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Annotations[workspaceIDAnnotation] < res[j].Annotations[workspaceIDAnnotation]
+	})
+	return res, nil
+}
+
+// bulkOperationFuncFor returns the function which applies the operation of a request to a single workspace
+func (m *Manager) bulkOperationFuncFor(req *api.StartBulkOperationRequest) (bulkOperationFunc, error) {
+	switch op := req.Operation.(type) {
+	case *api.StartBulkOperationRequest_Stop:
+		gracePeriod := stopWorkspaceNormallyGracePeriod
+		if op.Stop.Policy == api.StopWorkspacePolicy_IMMEDIATELY {
+			gracePeriod = stopWorkspaceImmediatelyGracePeriod
+		}
+		return func(ctx context.Context, workspaceID string) (err error) {
+			defer func() {
+				m.audit(ctx, &api.AuditRecord{Action: "StopWorkspace", Id: workspaceID, Parameters: map[string]string{"policy": op.Stop.Policy.String()}}, err)
+			}()
+			return m.stopWorkspace(ctx, workspaceID, gracePeriod)
+		}, nil
+	case *api.StartBulkOperationRequest_SetTimeout:
+		_, err := time.ParseDuration(op.SetTimeout.Duration)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid duration \"%s\": %w", op.SetTimeout.Duration, err)
+		}
+		return func(ctx context.Context, workspaceID string) error {
+			_, err := m.SetTimeout(ctx, &api.SetTimeoutRequest{Id: workspaceID, Duration: op.SetTimeout.Duration})
+			return err
+		}, nil
+	case *api.StartBulkOperationRequest_Migrate:
+		if op.Migrate.TargetCluster == "" {
+			return nil, xerrors.Errorf("migration needs a target cluster")
+		}
+		return func(ctx context.Context, workspaceID string) (err error) {
+			defer func() {
+				m.audit(ctx, &api.AuditRecord{Action: "MigrateWorkspace", Id: workspaceID, Parameters: map[string]string{"targetCluster": op.Migrate.TargetCluster}}, err)
+			}()
+
+			// we mark the workspace before stopping it, so that whoever watches the workspace stop learns where to start it next
+			err = m.markWorkspace(ctx, workspaceID, addMark(workspaceMigrateToAnnotation, op.Migrate.TargetCluster))
+			if err != nil {
+				return err
+			}
+			return m.stopWorkspace(ctx, workspaceID, stopWorkspaceNormallyGracePeriod)
+		}, nil
+	default:
+		return nil, xerrors.Errorf("unknown operation")
+	}
+}
+
+// bulkOperationName names the operation of a request in the audit log
+func bulkOperationName(req *api.StartBulkOperationRequest) string {
+	switch req.Operation.(type) {
+	case *api.StartBulkOperationRequest_Stop:
+		return "stop"
+	case *api.StartBulkOperationRequest_SetTimeout:
+		return "setTimeout"
+	case *api.StartBulkOperationRequest_Migrate:
+		return "migrate"
+	default:
+		return "unknown"
+	}
+}
+
+// StartBulkOperation applies an operation to all workspaces which match a filter in the background
+func (m *Manager) StartBulkOperation(ctx context.Context, req *api.StartBulkOperationRequest) (res *api.StartBulkOperationResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "StartBulkOperation")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+
+	filter := req.Filter
+	if filter == nil || (len(filter.Owners) == 0 && len(filter.Projects) == 0 && len(filter.Types) == 0 && filter.StartedBefore == nil) {
+		// we'd rather not stop all workspaces of a cluster because someone forgot to set the filter
+		return nil, status.Error(codes.InvalidArgument, "filter must have at least one criterion")
+	}
+	concurrency := int(req.Concurrency)
+	if concurrency == 0 {
+		concurrency = defaultBulkConcurrency
+	}
+	if concurrency > maxBulkConcurrency {
+		return nil, status.Errorf(codes.InvalidArgument, "concurrency must be at most %d", maxBulkConcurrency)
+	}
+	fn, err := m.bulkOperationFuncFor(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid operation: %v", err)
+	}
+
+	var pods corev1.PodList
+	err = m.Clientset.List(ctx, &pods, workspaceObjectListOptions(m.Config.Namespace))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list workspaces: %v", err)
+	}
+	workspaces, err := selectBulkWorkspaces(pods.Items, filter)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
+	}
+
+	res = &api.StartBulkOperationResponse{}
+	for _, pod := range workspaces {
+		res.WorkspaceIds = append(res.WorkspaceIds, pod.Annotations[workspaceIDAnnotation])
+	}
+	if req.DryRun {
+		return res, nil
+	}
+
+	// the actions of the operation are the caller's, and we record them as part of the operation
+	var (
+		caller  = auditCallerFromContext(ctx)
+		started = make(chan struct{})
+	)
+	op := m.bulkOperations.Start(workspaces, concurrency, func(ctx context.Context, workspaceID string) error {
+		<-started
+		return fn(withAuditCaller(ctx, caller), workspaceID)
+	})
+	caller.BulkOperation = op.ID
+	close(started)
+	res.Id = op.ID
+	m.audit(ctx, &api.AuditRecord{
+		Action: "StartBulkOperation",
+		Parameters: map[string]string{
+			"bulkOperation": op.ID,
+			"operation":     bulkOperationName(req),
+			"workspaces":    fmt.Sprint(len(workspaces)),
+		},
+	}, nil)
+	log.WithField("bulkOperation", op.ID).WithField("workspaces", len(workspaces)).WithField("concurrency", concurrency).Info("started bulk operation")
+	return res, nil
+}
+
+// DescribeBulkOperation returns the progress and the per-workspace results of a bulk operation
+func (m *Manager) DescribeBulkOperation(ctx context.Context, req *api.DescribeBulkOperationRequest) (res *api.DescribeBulkOperationResponse, err error) {
+	op := m.bulkOperations.Get(req.Id)
+	if op == nil {
+		return nil, status.Errorf(codes.NotFound, "bulk operation %s does not exist", req.Id)
+	}
+
+	results, _, current, _ := op.Results(0)
+	return &api.DescribeBulkOperationResponse{Status: current, Results: results}, nil
+}
+
+// WatchBulkOperation streams the per-workspace results of a bulk operation, starting with those we have already
+func (m *Manager) WatchBulkOperation(req *api.WatchBulkOperationRequest, srv api.WorkspaceManager_WatchBulkOperationServer) error {
+	op := m.bulkOperations.Get(req.Id)
+	if op == nil {
+		return status.Errorf(codes.NotFound, "bulk operation %s does not exist", req.Id)
+	}
+
+	var sent int
+	for {
+		results, progress, current, changed := op.Results(sent)
+		for i, r := range results {
+			err := srv.Send(&api.BulkOperationUpdate{Status: progress[i], Result: r})
+			if err != nil {
+				return err
+			}
+		}
+		sent += len(results)
+		if current.State != api.BulkOperationState_BULK_OPERATION_RUNNING {
+			return srv.Send(&api.BulkOperationUpdate{Status: current})
+		}
+
+		select {
+		case <-changed:
+		case <-srv.Context().Done():
+			return nil
+		}
+	}
+}
+
+// CancelBulkOperation stops a bulk operation before it reaches the workspaces it has not worked on yet.
+// The workspaces it is working on at the moment are not interrupted.
+func (m *Manager) CancelBulkOperation(ctx context.Context, req *api.CancelBulkOperationRequest) (res *api.CancelBulkOperationResponse, err error) {
+	op := m.bulkOperations.Get(req.Id)
+	if op == nil {
+		return nil, status.Errorf(codes.NotFound, "bulk operation %s does not exist", req.Id)
+	}
+
+	op.Cancel()
+	log.WithField("bulkOperation", op.ID).Info("cancelled bulk operation")
+	m.audit(ctx, &api.AuditRecord{Action: "CancelBulkOperation", Parameters: map[string]string{"bulkOperation": op.ID}}, nil)
+
+	_, _, current, _ := op.Results(0)
+	return &api.CancelBulkOperationResponse{Status: current}, nil
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func newBulkTestPod(id, owner, project, tpe string, created time.Time) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "ws-" + id,
+			CreationTimestamp: metav1.Time{Time: created},
+			Labels: map[string]string{
+				wsk8s.OwnerLabel: owner,
+				wsk8s.TypeLabel:  tpe,
+			},
+			Annotations: map[string]string{
+				workspaceIDAnnotation: id,
+			},
+		},
+	}
+	if project != "" {
+		pod.Annotations[workspaceProjectAnnotation] = project
+	}
+	return pod
+}
+
+func TestSelectBulkWorkspaces(t *testing.T) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	deleting := newBulkTestPod("ws-deleting", "alice", "gitpod-io/gitpod", "regular", now.Add(-2*time.Hour))
+	deleting.DeletionTimestamp = &metav1.Time{Time: now}
+	pods := []corev1.Pod{
+		newBulkTestPod("ws-c", "alice", "gitpod-io/gitpod", "regular", now.Add(-2*time.Hour)),
+		newBulkTestPod("ws-a", "alice", "gitpod-io/website", "regular", now.Add(-2*time.Hour)),
+		newBulkTestPod("ws-b", "bob", "gitpod-io/gitpod", "prebuild", now.Add(-10*time.Minute)),
+		newBulkTestPod("ws-d", "carol", "", "regular", now.Add(-3*time.Hour)),
+		deleting,
+	}
+	before, _ := ptypes.TimestampProto(now.Add(-time.Hour))
+
+	tests := []struct {
+		Name     string
+		Filter   *api.BulkOperationFilter
+		Expected []string
+	}{
+		{Name: "owner", Filter: &api.BulkOperationFilter{Owners: []string{"alice"}}, Expected: []string{"ws-a", "ws-c"}},
+		{Name: "project", Filter: &api.BulkOperationFilter{Projects: []string{"gitpod-io/gitpod"}}, Expected: []string{"ws-b", "ws-c"}},
+		{Name: "type", Filter: &api.BulkOperationFilter{Types: []api.WorkspaceType{api.WorkspaceType_PREBUILD}}, Expected: []string{"ws-b"}},
+		{Name: "age", Filter: &api.BulkOperationFilter{StartedBefore: before}, Expected: []string{"ws-a", "ws-c", "ws-d"}},
+		{
+			Name:     "all criteria",
+			Filter:   &api.BulkOperationFilter{Owners: []string{"alice", "bob"}, Projects: []string{"gitpod-io/gitpod"}, StartedBefore: before},
+			Expected: []string{"ws-c"},
+		},
+		{Name: "no match", Filter: &api.BulkOperationFilter{Owners: []string{"dave"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			res, err := selectBulkWorkspaces(pods, test.Filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var act []string
+			for _, pod := range res {
+				act = append(act, pod.Annotations[workspaceIDAnnotation])
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected workspaces (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func newBulkTestWorkspaces(n int) []*corev1.Pod {
+	res := make([]*corev1.Pod, n)
+	for i := range res {
+		pod := newBulkTestPod(fmt.Sprintf("ws-%02d", i), "alice", "", "regular", time.Now())
+		res[i] = &pod
+	}
+	return res
+}
+
+func waitForBulkOperation(t *testing.T, op *bulkOperation) *api.BulkOperationStatus {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		_, _, current, changed := op.Results(0)
+		if current.State != api.BulkOperationState_BULK_OPERATION_RUNNING {
+			return current
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			t.Fatal("bulk operation did not complete in time")
+		}
+	}
+}
+
+func TestBulkOperation(t *testing.T) {
+	var (
+		running, maxRunning int32
+		mu                  sync.Mutex
+		seen                = make(map[string]int)
+	)
+	op := newBulkOperations().Start(newBulkTestWorkspaces(20), 3, func(ctx context.Context, workspaceID string) error {
+		cur := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if cur <= max || atomic.CompareAndSwapInt32(&maxRunning, max, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		seen[workspaceID]++
+		mu.Unlock()
+		if workspaceID == "ws-07" {
+			return fmt.Errorf("cannot stop workspace")
+		}
+		return nil
+	})
+
+	sts := waitForBulkOperation(t, op)
+	if sts.State != api.BulkOperationState_BULK_OPERATION_COMPLETED || sts.Total != 20 || sts.Succeeded != 19 || sts.Failed != 1 || sts.CompletedAt == nil {
+		t.Errorf("unexpected status: %v", sts)
+	}
+	if maxRunning > 3 {
+		t.Errorf("worked on %d workspaces at the same time, expected at most 3", maxRunning)
+	}
+	if len(seen) != 20 {
+		t.Errorf("worked on %d workspaces, expected 20", len(seen))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("worked on %s %d times", id, n)
+		}
+	}
+
+	// watchers which start late get the results they missed, each with the progress after it
+	results, progress, _, _ := op.Results(15)
+	if len(results) != 5 || len(progress) != 5 {
+		t.Fatalf("expected 5 results and progress updates, got %d and %d", len(results), len(progress))
+	}
+	last := progress[len(progress)-1]
+	if last.Succeeded+last.Failed != 20 || last.State != api.BulkOperationState_BULK_OPERATION_RUNNING {
+		t.Errorf("unexpected progress after the last result: %v", last)
+	}
+}
+
+func TestBulkOperationCancel(t *testing.T) {
+	var (
+		release = make(chan struct{})
+		started int32
+	)
+	op := newBulkOperations().Start(newBulkTestWorkspaces(10), 2, func(ctx context.Context, workspaceID string) error {
+		atomic.AddInt32(&started, 1)
+		<-release
+		// cancelling the bulk operation must not cancel the workspaces in flight
+		return ctx.Err()
+	})
+
+	for atomic.LoadInt32(&started) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	op.Cancel()
+	close(release)
+
+	sts := waitForBulkOperation(t, op)
+	if sts.State != api.BulkOperationState_BULK_OPERATION_CANCELLED {
+		t.Errorf("state = %v, expected cancelled", sts.State)
+	}
+	if sts.Succeeded != 2 || sts.Failed != 8 {
+		t.Errorf("succeeded = %d, failed = %d, expected 2 and 8", sts.Succeeded, sts.Failed)
+	}
+	if n := atomic.LoadInt32(&started); n != 2 {
+		t.Errorf("worked on %d workspaces after cancelling, expected 2", n)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsdaemon "github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+// ChaosFault is a failure we can inject into the lifecycle of a workspace
+type ChaosFault string
+
+const (
+	// ChaosBackupFailure reports the final backup of a workspace as failed after ws-daemon disposed it
+	ChaosBackupFailure ChaosFault = "backup-failure"
+	// ChaosPodDeletion deletes the pod of a running workspace without grace period, as if its node went away
+	ChaosPodDeletion ChaosFault = "pod-deletion"
+	// ChaosDaemonTimeout fails the first call of each kind to ws-daemon with a deadline exceeded error
+	ChaosDaemonTimeout ChaosFault = "wsdaemon-timeout"
+)
+
+// chaosRetention is the time we remember that we injected a fault into a workspace instance
+const chaosRetention = 24 * time.Hour
+
+// ChaosConfig configures the faults we inject into the lifecycle of test workspaces, so that we can continuously
+// validate how the state machine copes with failures in staging instead of learning about them from incidents.
+type ChaosConfig struct {
+	// Owners are the users whose workspaces are subject to chaos, e.g. the users of the integration tests.
+	// Chaos never affects the workspaces of anyone else.
+	Owners []string `json:"owners"`
+	// Faults are the faults we inject. Each fault is injected at most once per workspace instance.
+	Faults []ChaosFaultConfig `json:"faults"`
+}
+
+// ChaosFaultConfig configures a fault we inject
+type ChaosFaultConfig struct {
+	Fault ChaosFault `json:"fault"`
+	// Probability is the share of the owners' workspace instances we inject the fault into. Defaults to 1, i.e. all of them.
+	Probability float64 `json:"probability,omitempty"`
+	// Delay is the time a workspace runs before we delete its pod. Applies to pod-deletion only.
+	Delay util.Duration `json:"delay,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ChaosConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Owners, validation.Required),
+		validation.Field(&c.Faults, validation.Required),
+	)
+	if err != nil {
+		return err
+	}
+	for i, o := range c.Owners {
+		if o == "" {
+			return xerrors.Errorf("owners[%d] must not be empty", i)
+		}
+	}
+
+	seen := make(map[ChaosFault]struct{}, len(c.Faults))
+	for i := range c.Faults {
+		f := &c.Faults[i]
+		err := validation.ValidateStruct(f,
+			validation.Field(&f.Fault, validation.Required, validation.In(ChaosBackupFailure, ChaosPodDeletion, ChaosDaemonTimeout)),
+			validation.Field(&f.Probability, validation.Min(0.0), validation.Max(1.0)),
+			validation.Field(&f.Delay, validation.Min(util.Duration(0))),
+		)
+		if err != nil {
+			return xerrors.Errorf("faults[%d]: %w", i, err)
+		}
+		if _, exists := seen[f.Fault]; exists {
+			return xerrors.Errorf("faults[%d]: duplicate fault %s", i, f.Fault)
+		}
+		seen[f.Fault] = struct{}{}
+	}
+	return nil
+}
+
+// chaos decides which faults we inject into which workspace instance. A nil chaos injects nothing.
+type chaos struct {
+	owners map[string]struct{}
+	faults map[ChaosFault]ChaosFaultConfig
+
+	mu       sync.Mutex
+	injected map[string]time.Time
+	now      func() time.Time
+}
+
+func newChaos(cfg *ChaosConfig) *chaos {
+	if cfg == nil {
+		return nil
+	}
+	c := &chaos{
+		owners:   make(map[string]struct{}, len(cfg.Owners)),
+		faults:   make(map[ChaosFault]ChaosFaultConfig, len(cfg.Faults)),
+		injected: make(map[string]time.Time),
+		now:      time.Now,
+	}
+	for _, o := range cfg.Owners {
+		c.owners[o] = struct{}{}
+	}
+	for _, f := range cfg.Faults {
+		c.faults[f.Fault] = f
+	}
+	return c
+}
+
+// Applies returns true if we would inject the fault into the workspace instance of meta
+func (c *chaos) Applies(fault ChaosFault, meta *metav1.ObjectMeta) bool {
+	if c == nil || meta == nil {
+		return false
+	}
+	f, ok := c.faults[fault]
+	if !ok {
+		return false
+	}
+	if _, ok := c.owners[meta.Labels[wsk8s.OwnerLabel]]; !ok {
+		return false
+	}
+	instanceID := meta.Labels[wsk8s.WorkspaceIDLabel]
+	if instanceID == "" {
+		return false
+	}
+
+	probability := f.Probability
+	if probability == 0 {
+		probability = 1
+	}
+	// the choice must be stable for an instance, hence we derive it from the instance ID rather than rolling the dice
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(instanceID + "/" + string(fault)))
+	return float64(h.Sum64())/math.MaxUint64 < probability
+}
+
+// Inject returns true if the fault is to be injected at point into the workspace instance of meta now.
+// It returns true at most once per instance, fault and point.
+func (c *chaos) Inject(fault ChaosFault, point string, meta *metav1.ObjectMeta) bool {
+	if !c.Applies(fault, meta) {
+		return false
+	}
+
+	key := meta.Labels[wsk8s.WorkspaceIDLabel] + "/" + string(fault) + "/" + point
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, t := range c.injected {
+		if now.Sub(t) > chaosRetention {
+			delete(c.injected, k)
+		}
+	}
+	if _, done := c.injected[key]; done {
+		return false
+	}
+	c.injected[key] = now
+	return true
+}
+
+// Delay returns the configured delay of a fault
+func (c *chaos) Delay(fault ChaosFault) time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.faults[fault].Delay)
+}
+
+// injectChaos returns true if the fault is to be injected now, and records the injection
+func (m *Manager) injectChaos(fault ChaosFault, point string, meta *metav1.ObjectMeta) bool {
+	if !m.chaos.Inject(fault, point, meta) {
+		return false
+	}
+	log.WithFields(wsk8s.GetOWIFromObject(meta)).WithField("fault", fault).WithField("point", point).Warn("chaos: injecting fault")
+	m.metrics.OnChaosFaultInjected(fault)
+	return true
+}
+
+// chaosObjectMeta returns the metadata which identifies the workspace instance of wso
+func chaosObjectMeta(wso *workspaceObjects) *metav1.ObjectMeta {
+	if wso.Pod != nil {
+		return &wso.Pod.ObjectMeta
+	}
+	if wso.PLIS != nil {
+		return &wso.PLIS.ObjectMeta
+	}
+	return nil
+}
+
+// scheduleChaosPodDeletion deletes the pod of a running workspace after the configured delay, if chaos applies to it
+func (m *Manager) scheduleChaosPodDeletion(pod *corev1.Pod) {
+	if !m.injectChaos(ChaosPodDeletion, "", &pod.ObjectMeta) {
+		return
+	}
+
+	time.AfterFunc(m.chaos.Delay(ChaosPodDeletion), func() {
+		owi := wsk8s.GetOWIFromObject(&pod.ObjectMeta)
+		if m.Config.DryRun {
+			log.WithFields(owi).Info("chaos: should have deleted pod but this is a dry run")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), kubernetesOperationTimeout)
+		defer cancel()
+		err := m.Clientset.Delete(ctx, pod, client.GracePeriodSeconds(0))
+		if err != nil && !isKubernetesObjNotFoundError(err) {
+			log.WithError(err).WithFields(owi).Warn("chaos: cannot delete workspace pod")
+		}
+	})
+}
+
+// chaosDaemonClient fails the first call of each kind to ws-daemon for a workspace instance as if it timed out
+type chaosDaemonClient struct {
+	wsdaemon.WorkspaceContentServiceClient
+
+	manager *Manager
+	meta    *metav1.ObjectMeta
+}
+
+func (c *chaosDaemonClient) timeout(method string) error {
+	if !c.manager.injectChaos(ChaosDaemonTimeout, method, c.meta) {
+		return nil
+	}
+	return grpc_status.Error(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+}
+
+func (c *chaosDaemonClient) InitWorkspace(ctx context.Context, in *wsdaemon.InitWorkspaceRequest, opts ...grpc.CallOption) (*wsdaemon.InitWorkspaceResponse, error) {
+	if err := c.timeout("InitWorkspace"); err != nil {
+		return nil, err
+	}
+	return c.WorkspaceContentServiceClient.InitWorkspace(ctx, in, opts...)
+}
+
+func (c *chaosDaemonClient) TakeSnapshot(ctx context.Context, in *wsdaemon.TakeSnapshotRequest, opts ...grpc.CallOption) (*wsdaemon.TakeSnapshotResponse, error) {
+	if err := c.timeout("TakeSnapshot"); err != nil {
+		return nil, err
+	}
+	return c.WorkspaceContentServiceClient.TakeSnapshot(ctx, in, opts...)
+}
+
+func (c *chaosDaemonClient) DisposeWorkspace(ctx context.Context, in *wsdaemon.DisposeWorkspaceRequest, opts ...grpc.CallOption) (*wsdaemon.DisposeWorkspaceResponse, error) {
+	if err := c.timeout("DisposeWorkspace"); err != nil {
+		return nil, err
+	}
+	return c.WorkspaceContentServiceClient.DisposeWorkspace(ctx, in, opts...)
+}
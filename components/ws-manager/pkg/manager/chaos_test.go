@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsdaemon "github.com/gitpod-io/gitpod/ws-daemon/api"
+)
+
+func TestChaosConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Cfg   *ChaosConfig
+		Valid bool
+	}{
+		{Name: "nil", Valid: true},
+		{Name: "valid", Cfg: &ChaosConfig{Owners: []string{"test-user"}, Faults: []ChaosFaultConfig{{Fault: ChaosPodDeletion, Delay: util.Duration(time.Minute)}, {Fault: ChaosBackupFailure, Probability: 0.5}}}, Valid: true},
+		{Name: "no owners", Cfg: &ChaosConfig{Faults: []ChaosFaultConfig{{Fault: ChaosPodDeletion}}}},
+		{Name: "empty owner", Cfg: &ChaosConfig{Owners: []string{""}, Faults: []ChaosFaultConfig{{Fault: ChaosPodDeletion}}}},
+		{Name: "no faults", Cfg: &ChaosConfig{Owners: []string{"test-user"}}},
+		{Name: "unknown fault", Cfg: &ChaosConfig{Owners: []string{"test-user"}, Faults: []ChaosFaultConfig{{Fault: "node-fire"}}}},
+		{Name: "invalid probability", Cfg: &ChaosConfig{Owners: []string{"test-user"}, Faults: []ChaosFaultConfig{{Fault: ChaosBackupFailure, Probability: 1.5}}}},
+		{Name: "duplicate fault", Cfg: &ChaosConfig{Owners: []string{"test-user"}, Faults: []ChaosFaultConfig{{Fault: ChaosBackupFailure}, {Fault: ChaosBackupFailure}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func chaosTestMeta(owner, instanceID string) *metav1.ObjectMeta {
+	return &metav1.ObjectMeta{Labels: map[string]string{wsk8s.OwnerLabel: owner, wsk8s.WorkspaceIDLabel: instanceID}}
+}
+
+func TestChaosInject(t *testing.T) {
+	c := newChaos(&ChaosConfig{
+		Owners: []string{"test-user"},
+		Faults: []ChaosFaultConfig{
+			{Fault: ChaosBackupFailure},
+			{Fault: ChaosDaemonTimeout, Probability: 0.5},
+		},
+	})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	if newChaos(nil).Inject(ChaosBackupFailure, "", chaosTestMeta("test-user", "i1")) {
+		t.Error("chaos without config injected a fault")
+	}
+	if c.Inject(ChaosBackupFailure, "", chaosTestMeta("someone-else", "i1")) {
+		t.Error("injected a fault into a workspace of someone else")
+	}
+	if c.Inject(ChaosPodDeletion, "", chaosTestMeta("test-user", "i1")) {
+		t.Error("injected a fault which is not configured")
+	}
+	if !c.Inject(ChaosBackupFailure, "", chaosTestMeta("test-user", "i1")) {
+		t.Error("did not inject configured fault")
+	}
+	if c.Inject(ChaosBackupFailure, "", chaosTestMeta("test-user", "i1")) {
+		t.Error("injected fault twice into the same instance")
+	}
+	if !c.Inject(ChaosBackupFailure, "", chaosTestMeta("test-user", "i2")) {
+		t.Error("did not inject fault into another instance")
+	}
+
+	var applied int
+	for i := 0; i < 1000; i++ {
+		meta := chaosTestMeta("test-user", fmt.Sprintf("instance-%d", i))
+		if c.Applies(ChaosDaemonTimeout, meta) {
+			applied++
+		}
+		if c.Applies(ChaosDaemonTimeout, meta) != c.Applies(ChaosDaemonTimeout, meta) {
+			t.Fatal("choice is not stable for an instance")
+		}
+	}
+	if applied < 400 || applied > 600 {
+		t.Errorf("expected fault to apply to about half of the instances, got %d of 1000", applied)
+	}
+
+	now = now.Add(chaosRetention + time.Minute)
+	if !c.Inject(ChaosBackupFailure, "", chaosTestMeta("test-user", "i1")) {
+		t.Error("expected injections to be forgotten after the retention")
+	}
+}
+
+type fakeContentService struct {
+	wsdaemon.WorkspaceContentServiceClient
+	calls int
+}
+
+func (f *fakeContentService) DisposeWorkspace(ctx context.Context, in *wsdaemon.DisposeWorkspaceRequest, opts ...grpc.CallOption) (*wsdaemon.DisposeWorkspaceResponse, error) {
+	f.calls++
+	return &wsdaemon.DisposeWorkspaceResponse{}, nil
+}
+
+func TestChaosDaemonClient(t *testing.T) {
+	m := &Manager{chaos: newChaos(&ChaosConfig{Owners: []string{"test-user"}, Faults: []ChaosFaultConfig{{Fault: ChaosDaemonTimeout}}})}
+	m.metrics = newMetrics(m)
+
+	fake := &fakeContentService{}
+	snc := &chaosDaemonClient{WorkspaceContentServiceClient: fake, manager: m, meta: chaosTestMeta("test-user", "i1")}
+
+	_, err := snc.DisposeWorkspace(context.Background(), &wsdaemon.DisposeWorkspaceRequest{})
+	if grpc_status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected first call to time out, got %v", err)
+	}
+	_, err = snc.DisposeWorkspace(context.Background(), &wsdaemon.DisposeWorkspaceRequest{})
+	if err != nil {
+		t.Errorf("expected retry to succeed, got %v", err)
+	}
+	if fake.calls != 1 {
+		t.Errorf("expected one call to reach ws-daemon, got %d", fake.calls)
+	}
+}
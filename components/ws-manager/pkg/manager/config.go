@@ -5,21 +5,19 @@
 package manager
 
 import (
-	"bytes"
-	"html/template"
 	iofs "io/fs"
-	"net/url"
 	"os"
-	"path/filepath"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
 	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	"k8s.io/apimachinery/pkg/util/yaml"
 
+	"github.com/gitpod-io/gitpod/common-go/imageref"
+	"github.com/gitpod-io/gitpod/common-go/namegen"
 	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
 )
 
 type osFS struct{}
@@ -83,6 +81,64 @@ type Configuration struct {
 	RegistryFacadeHost string `json:"registryFacadeHost"`
 	// IngressPortAllocator contains all config for the IngressPortAllocator
 	IngressPortAllocator *IngressPortAllocatorConfig `json:"ingressPortAllocator"`
+	// ImageRewrite maps workspace and IDE image references to different locations, e.g. registry mirrors in air-gapped installations.
+	// registry-facade pulls the images from the rewritten location.
+	ImageRewrite []imageref.RewriteRule `json:"imageRewrite,omitempty"`
+	// PreviewDNSHostnameTemplate is a Go template which resolves to the hostname of a workspace on a custom preview domain.
+	// If set, regular workspaces are annotated with their hostname and marked once they're ready, so that the preview DNS
+	// controller can create their DNS records. Available fields are the same as for WorkspaceURLTemplate.
+	PreviewDNSHostnameTemplate string `json:"previewDnsHostnameTemplate,omitempty"`
+	// ImageCompatibility enables checking that workspace images can run on the node pools before we start a workspace.
+	// If not set, we start workspaces regardless of the platform their image is built for.
+	ImageCompatibility *ImageCompatibilityConfig `json:"imageCompatibility,omitempty"`
+	// IDEPinning lets workspace starts pin an IDE image other than the default one. If not set, we reject pinned IDE images.
+	IDEPinning *IDEPinningConfig `json:"idePinning,omitempty"`
+	// ProxyActivity enables proxies to report the connections they have open to workspaces, which then count as activity.
+	// If not set, we reject such reports and only MarkActive calls count as activity.
+	ProxyActivity *ProxyActivityConfig `json:"proxyActivity,omitempty"`
+	// SlowStart limits how many workspaces we start per minute right after ws-manager started or a maintenance ended.
+	// If not set, we start workspaces as fast as they're requested.
+	SlowStart *SlowStartConfig `json:"slowStart,omitempty"`
+	// InstanceDNS gives regular workspace instances a stable cluster-internal DNS name, and lets the members of
+	// a workspace group reach each other. If not set, workspaces can only be reached through ws-proxy.
+	InstanceDNS *InstanceDNSConfig `json:"instanceDNS,omitempty"`
+	// Accounting records when workspace instances start and stop for billing systems to reconcile against.
+	// If not set, SubscribeAccounting and AckAccounting are unavailable.
+	Accounting *AccountingConfig `json:"accounting,omitempty"`
+	// AuditLog records every control action which affects a workspace, who asked for it and its outcome.
+	// If not set, we record nothing and QueryAuditLog and SubscribeAuditLog are unavailable.
+	AuditLog *AuditLogConfig `json:"auditLog,omitempty"`
+	// Archival moves the backups of regular workspaces which have been stopped for long to cold storage.
+	// If not set, we never archive workspaces and DescribeArchival and UnarchiveWorkspace are unavailable.
+	Archival *ArchivalConfig `json:"archival,omitempty"`
+	// SoftDelete keeps the backups of deleted workspaces for a grace period during which they can be restored.
+	// If not set, DeleteWorkspace, RestoreDeletedWorkspace and DescribeDeletedWorkspaces are unavailable.
+	SoftDelete *SoftDeleteConfig `json:"softDelete,omitempty"`
+	// WorkspaceTemplates stores named, versioned workspace templates which StartWorkspace requests can reference.
+	// If not set, the workspace template RPCs are unavailable and we reject start requests which reference a template.
+	WorkspaceTemplates *WorkspaceTemplatesConfig `json:"workspaceTemplates,omitempty"`
+	// PausedWorkspaces lets StartWorkspace create workspaces ahead of predicted demand, which a later StartWorkspace activates.
+	// If not set, we reject requests to create paused workspaces.
+	PausedWorkspaces *PausedWorkspacesConfig `json:"pausedWorkspaces,omitempty"`
+	// Chaos injects failures into the lifecycle of test workspaces to validate how we cope with them.
+	// If not set, we inject nothing. Never configure this outside of test installations.
+	Chaos *ChaosConfig `json:"chaos,omitempty"`
+	// WorkspaceIDs is the naming scheme of GenerateWorkspaceID, e.g. to give workspaces recognizable prefixes per project.
+	// If set, we also refuse to start workspaces whose ID ws-proxy could not route to. If not set, GenerateWorkspaceID
+	// produces IDs like amaranth-smelt-9ba20cc1.
+	WorkspaceIDs *namegen.IDScheme `json:"workspaceIDs,omitempty"`
+	// ScaleHints publishes the workspaces which are about to need nodes per node shape, for cluster autoscalers to
+	// provision nodes before workspace pods become unschedulable. If not set, we publish no scale hints.
+	ScaleHints *ScaleHintsConfig `json:"scaleHints,omitempty"`
+	// InitContainers is the allowlist of init containers users can opt into when starting a workspace, keyed by container name.
+	// Init containers have to comply with the pod template policy. If not set, workspaces cannot request init containers.
+	InitContainers map[string]InitContainerConfig `json:"initContainers,omitempty"`
+	// SharedCache mounts a node directory into regular and prebuild workspaces which they share as dependency cache.
+	// If not set, workspaces download all their dependencies themselves.
+	SharedCache *SharedCacheConfig `json:"sharedCache,omitempty"`
+	// Supervisor configures supervisor in all workspaces, e.g. the secrets providers tasks use.
+	// If not set, supervisor runs with its defaults.
+	Supervisor *SupervisorConfig `json:"supervisor,omitempty"`
 }
 
 // AllContainerConfiguration contains the configuration for all container in a workspace pod
@@ -134,6 +190,10 @@ type WorkspacePodTemplateConfiguration struct {
 	ProbePath string `json:"probePath,omitempty"`
 	// GhostPath is a path to an additional workspace pod template YAML file for ghost workspaces
 	GhostPath string `json:"ghostPath,omitempty"`
+	// Policy restricts what the templates may change about workspace pods. Templates which violate the policy
+	// are rejected during startup. If a template changes at runtime, we keep using the last version which complied.
+	// If not set, templates must not use privileged containers, host namespaces, hostPath volumes or added capabilities.
+	Policy *PodTemplatePolicy `json:"policy,omitempty"`
 }
 
 // WorkspaceDaemonConfiguration configures our connection to the workspace sync daemons runnin on the nodes
@@ -179,17 +239,54 @@ func (c *Configuration) Validate() error {
 		validation.Field(&c.WorkspacePodTemplate.ProbePath, validPodTemplate),
 		validation.Field(&c.WorkspacePodTemplate.GhostPath, validPodTemplate),
 		validation.Field(&c.WorkspacePodTemplate.RegularPath, validPodTemplate),
+		validation.Field(&c.WorkspacePodTemplate.Policy),
 	)
 	if err != nil {
 		return xerrors.Errorf("workspacePodTemplate: %w", err)
 	}
+	for _, fn := range []string{
+		c.WorkspacePodTemplate.DefaultPath,
+		c.WorkspacePodTemplate.PrebuildPath,
+		c.WorkspacePodTemplate.ProbePath,
+		c.WorkspacePodTemplate.GhostPath,
+		c.WorkspacePodTemplate.RegularPath,
+	} {
+		// the templates parse - validPodTemplate made sure of that
+		tpl, _ := getWorkspacePodTemplate(fn)
+		if violations := c.WorkspacePodTemplate.Policy.Check(tpl); len(violations) > 0 {
+			return xerrors.Errorf("workspacePodTemplate: %s violates the pod template policy: %s", fn, violations[0])
+		}
+	}
 
 	err = validation.ValidateStruct(c,
 		validation.Field(&c.WorkspaceURLTemplate, validation.Required, validWorkspaceURLTemplate),
+		validation.Field(&c.WorkspacePortURLTemplate, validWorkspaceURLTemplate),
 		validation.Field(&c.WorkspaceHostPath, validation.Required),
 		validation.Field(&c.HeartbeatInterval, validation.Required),
 		validation.Field(&c.GitpodHostURL, validation.Required, is.URL),
 		validation.Field(&c.ReconnectionInterval, validation.Required),
+		validation.Field(&c.PreviewDNSHostnameTemplate, validPreviewDNSHostnameTemplate),
+		validation.Field(&c.ImageRewrite, validation.By(func(interface{}) error {
+			_, err := imageref.NewRewriter(c.ImageRewrite)
+			return err
+		})),
+		validation.Field(&c.ImageCompatibility),
+		validation.Field(&c.IDEPinning),
+		validation.Field(&c.ProxyActivity),
+		validation.Field(&c.SlowStart),
+		validation.Field(&c.InstanceDNS),
+		validation.Field(&c.Accounting),
+		validation.Field(&c.AuditLog),
+		validation.Field(&c.Archival),
+		validation.Field(&c.SoftDelete),
+		validation.Field(&c.WorkspaceTemplates),
+		validation.Field(&c.PausedWorkspaces),
+		validation.Field(&c.Chaos),
+		validation.Field(&c.WorkspaceIDs),
+		validation.Field(&c.ScaleHints),
+		validation.Field(&c.InitContainers, validInitContainers(c.WorkspacePodTemplate.Policy)),
+		validation.Field(&c.SharedCache),
+		validation.Field(&c.Supervisor),
 	)
 	return err
 }
@@ -209,17 +306,31 @@ var validWorkspaceURLTemplate = validation.By(func(o interface{}) error {
 	if !ok {
 		return xerrors.Errorf("field should be string")
 	}
+	if s == "" {
+		return nil
+	}
 
-	wsurl, err := renderWorkspaceURL(s, "foo", "bar", "gitpod.io")
+	err := wsurl.ValidateURLTemplate(s)
 	if err != nil {
-		return xerrors.Errorf("cannot render URL: %w", err)
+		return xerrors.Errorf("not a valid URL template: %w", err)
 	}
-	_, err = url.Parse(wsurl)
-	if err != nil {
-		return xerrors.Errorf("not a valid URL: %w", err)
+	return nil
+})
+
+var validPreviewDNSHostnameTemplate = validation.By(func(o interface{}) error {
+	s, ok := o.(string)
+	if !ok {
+		return xerrors.Errorf("field should be string")
+	}
+	if s == "" {
+		return nil
 	}
 
-	return err
+	err := wsurl.ValidateHostnameTemplate(s)
+	if err != nil {
+		return xerrors.Errorf("not a valid hostname template: %w", err)
+	}
+	return nil
 })
 
 // ContainerConfiguration configures properties of workspace pod container
@@ -297,76 +408,33 @@ func getWorkspacePodTemplate(filename string) (*corev1.Pod, error) {
 		return nil, nil
 	}
 
-	tpr := os.Getenv("TELEPRESENCE_ROOT")
-	if tpr != "" {
-		filename = filepath.Join(tpr, filename)
-	}
-
-	tpl, err := fs.Open(filename)
-	if err != nil {
-		return nil, xerrors.Errorf("cannot read pod template: %w", err)
-	}
-	defer tpl.Close()
-
-	var res corev1.Pod
-	decoder := yaml.NewYAMLOrJSONDecoder(tpl, 4096)
-	err = decoder.Decode(&res)
+	tpl, err := readPodTemplate(filename)
 	if err != nil {
-		return nil, xerrors.Errorf("cannot unmarshal pod template: %w", err)
+		return nil, err
 	}
-
-	return &res, nil
+	return parsePodTemplate(tpl)
 }
 
 // renderWorkspaceURL takes a workspace URL template and renders it
 func renderWorkspaceURL(urltpl, id, servicePrefix, host string) (string, error) {
-	tpl, err := template.New("url").Parse(urltpl)
-	if err != nil {
-		return "", xerrors.Errorf("cannot compute workspace URL: %w", err)
-	}
-
-	type data struct {
-		ID     string
-		Prefix string
-		Host   string
-	}
-	d := data{
+	res, err := wsurl.RenderTemplate(urltpl, wsurl.TemplateContext{
 		ID:     id,
 		Prefix: servicePrefix,
 		Host:   host,
-	}
-
-	var b bytes.Buffer
-	err = tpl.Execute(&b, d)
+	})
 	if err != nil {
 		return "", xerrors.Errorf("cannot compute workspace URL: %w", err)
 	}
-
-	return b.String(), nil
-}
-
-type portURLContext struct {
-	ID            string
-	Prefix        string
-	Host          string
-	WorkspacePort string
-	IngressPort   string
+	return res, nil
 }
 
 // renderWorkspacePortURL takes a workspace port URL template and renders it
-func renderWorkspacePortURL(urltpl string, ctx portURLContext) (string, error) {
-	tpl, err := template.New("url").Parse(urltpl)
-	if err != nil {
-		return "", xerrors.Errorf("cannot compute workspace URL: %w", err)
-	}
-
-	var b bytes.Buffer
-	err = tpl.Execute(&b, ctx)
+func renderWorkspacePortURL(urltpl string, ctx wsurl.TemplateContext) (string, error) {
+	res, err := wsurl.RenderTemplate(urltpl, ctx)
 	if err != nil {
 		return "", xerrors.Errorf("cannot compute workspace port URL: %w", err)
 	}
-
-	return b.String(), nil
+	return res, nil
 }
 
 // ResourceConfiguration configures resources of a pod/container
@@ -8,6 +8,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -26,6 +27,7 @@ import (
 
 	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
 	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
 	regapi "github.com/gitpod-io/gitpod/registry-facade/api"
 	"github.com/gitpod-io/gitpod/ws-manager/api"
 )
@@ -36,23 +38,38 @@ var (
 	boolTrue  = true
 )
 
+// allowedGitpodEnvvars are the GITPOD_ env vars a start workspace request may set. We drop all other
+// request env vars starting with GITPOD_ as we set those ourselves.
+var allowedGitpodEnvvars = map[string]struct{}{
+	"GITPOD_TASKS":                  {},
+	"GITPOD_RESOLVED_EXTENSIONS":    {},
+	"GITPOD_EXTERNAL_EXTENSIONS":    {},
+	"GITPOD_TIMEZONE":               {},
+	"GITPOD_FAKETIME_OFFSET":        {},
+	"GITPOD_DISABLE_PERSISTED_HOME": {},
+	"GITPOD_PERSISTED_HOME_FILES":   {},
+	"GITPOD_TERMINAL_RECORDING":     {},
+	"GITPOD_LOG_MASKING":            {},
+	"GITPOD_PROJECT_EGRESS_POLICY":  {},
+}
+
 // createWorkspacePod creates the actual workspace pod based on the definite workspace pod and appropriate
 // templates. The result of this function is not expected to be modified prior to being passed to Kubernetes.
 func (m *Manager) createWorkspacePod(startContext *startWorkspaceContext) (*corev1.Pod, error) {
-	podTemplate, err := getWorkspacePodTemplate(m.Config.WorkspacePodTemplate.DefaultPath)
+	podTemplate, err := m.podTemplates.Get(m.Config.WorkspacePodTemplate.DefaultPath, m.Config.WorkspacePodTemplate.Policy)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot read pod template - this is a configuration problem: %w", err)
 	}
 	var typeSpecificTpl *corev1.Pod
 	switch startContext.Request.Type {
 	case api.WorkspaceType_REGULAR:
-		typeSpecificTpl, err = getWorkspacePodTemplate(m.Config.WorkspacePodTemplate.RegularPath)
+		typeSpecificTpl, err = m.podTemplates.Get(m.Config.WorkspacePodTemplate.RegularPath, m.Config.WorkspacePodTemplate.Policy)
 	case api.WorkspaceType_PREBUILD:
-		typeSpecificTpl, err = getWorkspacePodTemplate(m.Config.WorkspacePodTemplate.PrebuildPath)
+		typeSpecificTpl, err = m.podTemplates.Get(m.Config.WorkspacePodTemplate.PrebuildPath, m.Config.WorkspacePodTemplate.Policy)
 	case api.WorkspaceType_PROBE:
-		typeSpecificTpl, err = getWorkspacePodTemplate(m.Config.WorkspacePodTemplate.ProbePath)
+		typeSpecificTpl, err = m.podTemplates.Get(m.Config.WorkspacePodTemplate.ProbePath, m.Config.WorkspacePodTemplate.Policy)
 	case api.WorkspaceType_GHOST:
-		typeSpecificTpl, err = getWorkspacePodTemplate(m.Config.WorkspacePodTemplate.GhostPath)
+		typeSpecificTpl, err = m.podTemplates.Get(m.Config.WorkspacePodTemplate.GhostPath, m.Config.WorkspacePodTemplate.Policy)
 	}
 	if err != nil {
 		return nil, xerrors.Errorf("cannot read type-specific pod template - this is a configuration problem: %w", err)
@@ -232,6 +249,9 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 		BaseRef: startContext.Request.Spec.WorkspaceImage,
 		IdeRef:  startContext.Request.Spec.IdeImage,
 	}
+	if pinned := startContext.Request.Spec.PinnedIdeImage; pinned != "" {
+		spec.IdeRef = pinned
+	}
 	imageSpec, err := spec.ToBase64()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot create remarshal image spec: %w", err)
@@ -279,6 +299,23 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 		//           Until then, the custom seccomp profile isn't suitable for workspaces.
 		"seccomp.security.alpha.kubernetes.io/pod": "runtime/default",
 	}
+	if req.Paused {
+		annotations[workspacePausedAnnotation] = pausedWaiting
+	}
+	if req.Metadata.Project != "" {
+		annotations[workspaceProjectAnnotation] = req.Metadata.Project
+	}
+	if req.Spec.PinnedIdeImage != "" && m.Config.IDEPinning != nil {
+		pin, err := json.Marshal(ideImagePin{
+			PinnedImage:       req.Spec.PinnedIdeImage,
+			DefaultImage:      req.Spec.IdeImage,
+			SupervisorVersion: m.Config.IDEPinning.SupervisorVersion,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("cannot serialize IDE pin: %w", err)
+		}
+		annotations[workspaceIDEPinAnnotation] = string(pin)
+	}
 	if req.Spec.Timeout != "" {
 		_, err := time.ParseDuration(req.Spec.Timeout)
 		if err != nil {
@@ -286,6 +323,16 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 		}
 		annotations[customTimeoutAnnotation] = req.Spec.Timeout
 	}
+	if startContext.PreviewDNSHostname != "" {
+		annotations[wsk8s.PreviewDNSHostnameAnnotation] = startContext.PreviewDNSHostname
+	}
+	if len(req.Spec.Experiments) > 0 {
+		experiments, err := json.Marshal(req.Spec.Experiments)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot serialize experiments: %w", err)
+		}
+		annotations[workspaceExperimentsAnnotation] = string(experiments)
+	}
 
 	// By default we embue our workspace pods with some tolerance towards pressure taints,
 	// see https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/#taint-based-evictions
@@ -294,6 +341,11 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 	// we'd rather wait things out or gracefully fail the workspace ourselves.
 	var perssureToleranceSeconds int64 = 30
 
+	initContainers, err := m.createInitContainers(startContext)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create init containers: %w", err)
+	}
+
 	pod := corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        fmt.Sprintf("%s-%s", prefix, req.Id),
@@ -336,6 +388,9 @@ func (m *Manager) createDefiniteWorkspacePod(startContext *startWorkspaceContext
 		},
 	}
 
+	addInitContainers(&pod, initContainers)
+	m.addSharedCache(&pod, startContext)
+
 	ffidx := make(map[api.WorkspaceFeatureFlag]struct{})
 	for _, feature := range startContext.Request.Spec.FeatureFlags {
 		if _, seen := ffidx[feature]; seen {
@@ -535,10 +590,18 @@ func (m *Manager) createWorkspaceEnvironment(startContext *startWorkspaceContext
 	result = append(result, corev1.EnvVar{Name: "GITPOD_CLI_APITOKEN", Value: startContext.CLIAPIKey})
 	result = append(result, corev1.EnvVar{Name: "GITPOD_WORKSPACE_ID", Value: startContext.Request.Metadata.MetaId})
 	result = append(result, corev1.EnvVar{Name: "GITPOD_INSTANCE_ID", Value: startContext.Request.Id})
+	result = append(result, corev1.EnvVar{Name: "GITPOD_OWNER_ID", Value: startContext.Request.Metadata.Owner})
+	result = append(result, corev1.EnvVar{Name: "GITPOD_WORKSPACE_CLASS", Value: strings.ToLower(startContext.Request.Type.String())})
+	if ff := featureFlagNames(spec.FeatureFlags); ff != "" {
+		result = append(result, corev1.EnvVar{Name: "GITPOD_FEATURE_FLAGS", Value: ff})
+	}
 	result = append(result, corev1.EnvVar{Name: "GITPOD_THEIA_PORT", Value: strconv.Itoa(int(startContext.IDEPort))})
 	result = append(result, corev1.EnvVar{Name: "THEIA_WORKSPACE_ROOT", Value: getWorkspaceRelativePath(spec.WorkspaceLocation)})
 	result = append(result, corev1.EnvVar{Name: "GITPOD_HOST", Value: m.Config.GitpodHostURL})
 	result = append(result, corev1.EnvVar{Name: "GITPOD_WORKSPACE_URL", Value: startContext.WorkspaceURL})
+	if host := m.instanceHostname(startContext.Request.Id); host != "" && startContext.Request.Type == api.WorkspaceType_REGULAR {
+		result = append(result, corev1.EnvVar{Name: "GITPOD_INSTANCE_HOST", Value: host})
+	}
 	result = append(result, corev1.EnvVar{Name: "THEIA_SUPERVISOR_TOKEN", Value: m.Config.TheiaSupervisorToken})
 	result = append(result, corev1.EnvVar{Name: "THEIA_SUPERVISOR_ENDPOINT", Value: fmt.Sprintf(":%d", startContext.SupervisorPort)})
 	result = append(result, corev1.EnvVar{Name: "THEIA_WEBVIEW_EXTERNAL_ENDPOINT", Value: "webview-{{hostname}}"})
@@ -549,15 +612,23 @@ func (m *Manager) createWorkspaceEnvironment(startContext *startWorkspaceContext
 		result = append(result, corev1.EnvVar{Name: "GITPOD_GIT_USER_NAME", Value: spec.Git.Username})
 		result = append(result, corev1.EnvVar{Name: "GITPOD_GIT_USER_EMAIL", Value: spec.Git.Email})
 	}
+	if len(spec.SshKeys) > 0 {
+		sshKeys, err := sshKeysEnv(spec.SshKeys)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create environment: %w", err)
+		}
+		result = append(result, corev1.EnvVar{Name: supervisorSSHKeysEnvvar, Value: sshKeys})
+	}
 
 	// User-defined env vars (i.e. those coming from the request)
 	if spec.Envvars != nil {
 		for _, e := range spec.Envvars {
-			if e.Name == "GITPOD_TASKS" || e.Name == "GITPOD_RESOLVED_EXTENSIONS" || e.Name == "GITPOD_EXTERNAL_EXTENSIONS" {
-				result = append(result, corev1.EnvVar{Name: e.Name, Value: e.Value})
+			if _, allowed := allowedGitpodEnvvars[e.Name]; !allowed && strings.HasPrefix(e.Name, "GITPOD_") {
+				// we don't allow env vars starting with GITPOD_ and those that we do allow we've listed in allowedGitpodEnvvars
 				continue
-			} else if strings.HasPrefix(e.Name, "GITPOD_") {
-				// we don't allow env vars starting with GITPOD_ and those that we do allow we've listed above
+			}
+			if e.Name == supervisorSSHKeysEnvvar {
+				// the SSH keys come from the spec
 				continue
 			}
 
@@ -578,6 +649,17 @@ func (m *Manager) createWorkspaceEnvironment(startContext *startWorkspaceContext
 	if startContext.Headless {
 		result = append(result, corev1.EnvVar{Name: "GITPOD_HEADLESS", Value: "true"})
 	}
+	if startContext.Request.Paused {
+		result = append(result, corev1.EnvVar{Name: "GITPOD_WORKSPACE_PAUSED", Value: "true"})
+	}
+	if m.hasSharedCache(startContext) {
+		result = append(result, corev1.EnvVar{Name: "GITPOD_SHARED_CACHE_DIR", Value: sharedCacheDir})
+	}
+	supervisorEnv, err := m.Config.Supervisor.env()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create environment: %w", err)
+	}
+	result = append(result, supervisorEnv...)
 
 	// remove empty env vars
 	cleanResult := make([]corev1.EnvVar, 0)
@@ -592,6 +674,42 @@ func (m *Manager) createWorkspaceEnvironment(startContext *startWorkspaceContext
 	return cleanResult, nil
 }
 
+// supervisorSSHKeysEnvvar is where supervisor expects the SSH keys it loads into the workspace's SSH agent
+const supervisorSSHKeysEnvvar = "THEIA_SUPERVISOR_SSH_KEYS"
+
+// sshKeysEnv marshals SSH keys in the form supervisor expects, e.g. [{"name":"id_rsa","keyOTS":"https://..."}]
+func sshKeysEnv(keys []*api.WorkspaceSSHKey) (string, error) {
+	type sshKey struct {
+		Name   string `json:"name"`
+		KeyOTS string `json:"keyOTS"`
+	}
+	res := make([]sshKey, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, sshKey{Name: k.Name, KeyOTS: k.KeyOts})
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// featureFlagNames lists the feature flags in the comma-separated form supervisor expects, e.g. "full_workspace_backup,user_namespace"
+func featureFlagNames(flags []api.WorkspaceFeatureFlag) string {
+	var (
+		names = make([]string, 0, len(flags))
+		seen  = make(map[api.WorkspaceFeatureFlag]struct{}, len(flags))
+	)
+	for _, f := range flags {
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		names = append(names, strings.ToLower(f.String()))
+	}
+	return strings.Join(names, ",")
+}
+
 func (m *Manager) createWorkspaceVolumes(startContext *startWorkspaceContext) (workspace corev1.Volume, err error) {
 	// silly protobuf structure design - this needs to be a reference to a string,
 	// so we have to assign it to a variable first to take the address
@@ -678,7 +796,7 @@ func (m *Manager) createPortsService(workspaceID string, metaID string, serviceP
 		}
 
 		ingressPort, _ := alloc.AllocatedPort(int(p.Port))
-		url, err := renderWorkspacePortURL(m.Config.WorkspacePortURLTemplate, portURLContext{
+		url, err := renderWorkspacePortURL(m.Config.WorkspacePortURLTemplate, wsurl.TemplateContext{
 			Host:          m.Config.GitpodHostURL,
 			ID:            metaID,
 			IngressPort:   fmt.Sprint(ingressPort),
@@ -689,6 +807,9 @@ func (m *Manager) createPortsService(workspaceID string, metaID string, serviceP
 			return nil, xerrors.Errorf("cannot render public URL for %d: %w", p.Port, err)
 		}
 		annotations[fmt.Sprintf("gitpod/port-url-%d", p.Port)] = url
+		if p.PermissiveCors {
+			annotations[portCORSAnnotation(p.Port)] = portCORSPermissive
+		}
 	}
 
 	return &corev1.Service{
@@ -729,6 +850,14 @@ func (m *Manager) newStartWorkspaceContext(ctx context.Context, req *api.StartWo
 		return nil, xerrors.Errorf("cannot get workspace URL: %w", err)
 	}
 
+	var previewDNSHostname string
+	if m.Config.PreviewDNSHostnameTemplate != "" && !headless {
+		previewDNSHostname, err = renderWorkspaceURL(m.Config.PreviewDNSHostnameTemplate, req.Id, req.ServicePrefix, m.Config.GitpodHostURL)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get preview DNS hostname: %w", err)
+		}
+	}
+
 	cliAPIKey, err := getRandomString(32)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot create CLI API key: %w", err)
@@ -742,17 +871,22 @@ func (m *Manager) newStartWorkspaceContext(ctx context.Context, req *api.StartWo
 	workspaceSpan := opentracing.StartSpan("workspace", opentracing.FollowsFrom(opentracing.SpanFromContext(ctx).Context()))
 	traceID := tracing.GetTraceID(workspaceSpan)
 
+	labels := map[string]string{
+		"app":                  "gitpod",
+		"component":            "workspace",
+		wsk8s.WorkspaceIDLabel: req.Id,
+		wsk8s.OwnerLabel:       req.Metadata.Owner,
+		wsk8s.MetaIDLabel:      req.Metadata.MetaId,
+		wsk8s.TypeLabel:        workspaceType,
+		headlessLabel:          fmt.Sprintf("%v", headless),
+		markerLabel:            "true",
+	}
+	if req.Metadata.GroupId != "" {
+		labels[workspaceGroupLabel] = req.Metadata.GroupId
+	}
+
 	return &startWorkspaceContext{
-		Labels: map[string]string{
-			"app":                  "gitpod",
-			"component":            "workspace",
-			wsk8s.WorkspaceIDLabel: req.Id,
-			wsk8s.OwnerLabel:       req.Metadata.Owner,
-			wsk8s.MetaIDLabel:      req.Metadata.MetaId,
-			wsk8s.TypeLabel:        workspaceType,
-			headlessLabel:          fmt.Sprintf("%v", headless),
-			markerLabel:            "true",
-		},
+		Labels:         labels,
 		CLIAPIKey:      cliAPIKey,
 		OwnerToken:     ownerToken,
 		Request:        req,
@@ -761,6 +895,8 @@ func (m *Manager) newStartWorkspaceContext(ctx context.Context, req *api.StartWo
 		WorkspaceURL:   workspaceURL,
 		TraceID:        traceID,
 		Headless:       headless,
+
+		PreviewDNSHostname: previewDNSHostname,
 	}, nil
 }
 
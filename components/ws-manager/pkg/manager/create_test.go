@@ -29,6 +29,8 @@ func TestCreateDefiniteWorkspacePod(t *testing.T) {
 		ProbeTemplate    *corev1.Pod            `json:"probeTemplate,omitempty"`
 		RegularTemplate  *corev1.Pod            `json:"regularTemplate,omitempty"`
 		ResourceRequests *ResourceConfiguration `json:"resourceRequests,omitempty"`
+		SharedCache      *SharedCacheConfig     `json:"sharedCache,omitempty"`
+		Supervisor       *SupervisorConfig      `json:"supervisor,omitempty"`
 	}
 	type gold struct {
 		Pod   corev1.Pod `json:"reason,omitempty"`
@@ -52,6 +54,8 @@ func TestCreateDefiniteWorkspacePod(t *testing.T) {
 				cfg.Container = cont
 				manager.Config = cfg
 			}
+			manager.Config.SharedCache = fixture.SharedCache
+			manager.Config.Supervisor = fixture.Supervisor
 
 			// create in-memory file system
 			mapFS := fstest.MapFS{}
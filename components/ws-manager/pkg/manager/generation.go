@@ -0,0 +1,169 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusGenerations hands out the generation numbers of workspace status updates.
+//
+// All generations are drawn from a single counter which is seeded with the time ws-manager started
+// (in microseconds). This way generations increase monotonically per workspace instance, even across
+// ws-manager restarts and without having to persist anything. Microseconds keep the numbers within
+// the range JavaScript can represent precisely.
+type statusGenerations struct {
+	mu      sync.Mutex
+	counter uint64
+	current map[string]uint64
+	control map[string]*controlLock
+}
+
+// controlLock serializes the control requests of a single workspace instance
+type controlLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newStatusGenerations() *statusGenerations {
+	return &statusGenerations{
+		counter: uint64(time.Now().UnixNano() / int64(time.Microsecond)),
+		current: make(map[string]uint64),
+		control: make(map[string]*controlLock),
+	}
+}
+
+// Next assigns a new generation to a workspace instance
+func (g *statusGenerations) Next(instanceID string) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.counter++
+	g.current[instanceID] = g.counter
+	return g.counter
+}
+
+// Current returns the generation last handed out for a workspace instance.
+// If the instance has no generation yet, it gets a new one.
+func (g *statusGenerations) Current(instanceID string) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if gen, ok := g.current[instanceID]; ok {
+		return gen
+	}
+	g.counter++
+	g.current[instanceID] = g.counter
+	return g.counter
+}
+
+// lookup returns the generation last handed out for a workspace instance, without assigning one
+func (g *statusGenerations) lookup(instanceID string) (gen uint64, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gen, ok = g.current[instanceID]
+	return
+}
+
+// advance moves the generation of a known workspace instance forward. Unknown instances remain unknown.
+func (g *statusGenerations) advance(instanceID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.current[instanceID]; !ok {
+		return
+	}
+	g.counter++
+	g.current[instanceID] = g.counter
+}
+
+// lockControl acquires the control lock of a workspace instance. The lock exists only while someone holds
+// or waits for it, so that we don't accumulate locks for every instance we have ever seen.
+func (g *statusGenerations) lockControl(instanceID string) (unlock func()) {
+	g.mu.Lock()
+	l, ok := g.control[instanceID]
+	if !ok {
+		l = &controlLock{}
+		g.control[instanceID] = l
+	}
+	l.refs++
+	g.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		g.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(g.control, instanceID)
+		}
+		g.mu.Unlock()
+	}
+}
+
+// Forget removes a workspace instance. Should the instance show up again it gets a new, higher generation.
+func (g *statusGenerations) Forget(instanceID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.current, instanceID)
+}
+
+// Restore restores the generation an instance had before ws-manager restarted. If the instance has a lower generation
+// in the meantime, it gets a new one so that its generations keep increasing no matter our clock.
+func (g *statusGenerations) Restore(instanceID string, generation uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.counter < generation {
+		g.counter = generation
+	}
+	current, ok := g.current[instanceID]
+	switch {
+	case !ok:
+		g.current[instanceID] = generation
+	case current < generation:
+		g.counter++
+		g.current[instanceID] = g.counter
+	}
+}
+
+// checkExpectedGeneration implements the compare-and-set semantics of control requests: if the
+// request expects a generation, that generation must be the workspace's current one.
+//
+// The check and the modification the request makes have to be atomic. Hence, control requests of a
+// workspace instance are serialized: checkExpectedGeneration holds the instance's control lock until the
+// request calls done with its outcome. A successful request advances the generation right away, so that
+// concurrent requests which expect the same generation fail, even before the resulting status update is out.
+func (m *Manager) checkExpectedGeneration(instanceID string, expected uint64) (done func(err error), err error) {
+	unlock := m.generations.lockControl(instanceID)
+	done = func(err error) {
+		if err == nil {
+			m.generations.advance(instanceID)
+		}
+		unlock()
+	}
+
+	if expected == 0 {
+		return done, nil
+	}
+
+	current, ok := m.generations.lookup(instanceID)
+	if !ok {
+		unlock()
+		return nil, status.Errorf(codes.Aborted, "workspace %s has no generation yet, not %d", instanceID, expected)
+	}
+	if current != expected {
+		unlock()
+		return nil, status.Errorf(codes.Aborted, "workspace %s is at generation %d, not %d", instanceID, current, expected)
+	}
+	return done, nil
+}
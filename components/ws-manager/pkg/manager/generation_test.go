@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusGenerations(t *testing.T) {
+	g := newStatusGenerations()
+
+	first := g.Current("a")
+	if cur := g.Current("a"); cur != first {
+		t.Errorf("Current changed the generation: %d != %d", cur, first)
+	}
+
+	other := g.Next("b")
+	if other <= first {
+		t.Errorf("generations are not monotonic across instances: %d <= %d", other, first)
+	}
+
+	next := g.Next("a")
+	if next <= other {
+		t.Errorf("generations are not monotonic: %d <= %d", next, other)
+	}
+
+	g.Forget("a")
+	if reassigned := g.Current("a"); reassigned <= next {
+		t.Errorf("forgotten instance did not get a higher generation: %d <= %d", reassigned, next)
+	}
+}
+
+func TestCheckExpectedGeneration(t *testing.T) {
+	m := &Manager{generations: newStatusGenerations()}
+	current := m.generations.Next("foo")
+
+	tests := []struct {
+		Desc     string
+		Expected uint64
+		Code     codes.Code
+	}{
+		{"unconditional", 0, codes.OK},
+		{"current generation", current, codes.OK},
+		{"outdated generation", current - 1, codes.Aborted},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			done, err := m.checkExpectedGeneration("foo", test.Expected)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("unexpected code: %v, expected %v", code, test.Code)
+			}
+			if done != nil {
+				// the request failed, hence the generation must not change
+				done(xerrors.Errorf("failed"))
+			}
+		})
+	}
+
+	t.Run("unknown instance", func(t *testing.T) {
+		_, err := m.checkExpectedGeneration("unknown", current)
+		if code := status.Code(err); code != codes.Aborted {
+			t.Errorf("unexpected code: %v, expected %v", code, codes.Aborted)
+		}
+		if _, ok := m.generations.lookup("unknown"); ok {
+			t.Errorf("checking the generation of an unknown instance assigned a generation")
+		}
+	})
+}
+
+func TestCheckExpectedGenerationIsAtomic(t *testing.T) {
+	m := &Manager{generations: newStatusGenerations()}
+	current := m.generations.Next("foo")
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			done, err := m.checkExpectedGeneration("foo", current)
+			if err != nil {
+				return
+			}
+			// simulate the modification the control request makes
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			succeeded++
+			mu.Unlock()
+			done(nil)
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("expected exactly one request to succeed, got %d", succeeded)
+	}
+	if gen, _ := m.generations.lookup("foo"); gen <= current {
+		t.Errorf("successful request did not advance the generation: %d <= %d", gen, current)
+	}
+	if len(m.generations.control) != 0 {
+		t.Errorf("control locks were not released: %v", m.generations.control)
+	}
+}
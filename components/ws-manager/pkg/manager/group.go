@@ -0,0 +1,352 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// groupMemberPollInterval is how often we check if the dependencies of a group member are running
+	groupMemberPollInterval = 2 * time.Second
+)
+
+var (
+	// groups IDs end up as label value
+	groupIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9_.]{0,61}[a-zA-Z0-9])?$`)
+	// member names end up in environment variable names
+	groupMemberNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+)
+
+// StartWorkspaceGroup starts a group of workspaces. Members start in dependency order, i.e. a member starts once
+// all members it depends on are running. If a member fails to start, we stop the members we've started already.
+func (m *Manager) StartWorkspaceGroup(ctx context.Context, req *api.StartWorkspaceGroupRequest) (res *api.StartWorkspaceGroupResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "StartWorkspaceGroup")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		ids := make([]string, 0, len(req.Members))
+		for _, member := range req.Members {
+			ids = append(ids, member.GetWorkspace().GetId())
+		}
+		m.audit(ctx, &api.AuditRecord{Action: "StartWorkspaceGroup", Parameters: map[string]string{"group": req.Id, "members": strings.Join(ids, ",")}}, err)
+	}()
+
+	members, err := planWorkspaceGroup(req, func(ws *api.StartWorkspaceRequest) (string, error) {
+		return renderWorkspaceURL(m.Config.WorkspaceURLTemplate, ws.Id, ws.ServicePrefix, m.Config.GitpodHostURL)
+	}, func(ws *api.StartWorkspaceRequest) string {
+		return m.instanceHostname(ws.Id)
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workspace group: %v", err)
+	}
+
+	existing, err := m.listWorkspaceGroupPods(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot start workspace group: %v", err)
+	}
+	if len(existing) > 0 {
+		return nil, status.Errorf(codes.AlreadyExists, "workspace group %s exists already", req.Id)
+	}
+
+	clog := log.WithField("group", req.Id)
+	res = &api.StartWorkspaceGroupResponse{Urls: make(map[string]string, len(members))}
+	ids := make(map[string]string, len(members))
+	for _, member := range members {
+		for _, dep := range member.DependsOn {
+			err = m.waitForGroupMember(ctx, ids[dep])
+			if err != nil {
+				err = xerrors.Errorf("member %s cannot start because %s is not running: %w", member.Name, dep, err)
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+
+		var sws *api.StartWorkspaceResponse
+		sws, err = m.startWorkspace(ctx, member.Workspace)
+		if err != nil {
+			err = xerrors.Errorf("cannot start member %s: %w", member.Name, err)
+			break
+		}
+		ids[member.Name] = member.Workspace.Id
+		res.Urls[member.Name] = sws.Url
+		clog.WithField("member", member.Name).WithField("instanceId", member.Workspace.Id).Info("started workspace group member")
+	}
+	if err != nil {
+		clog.WithError(err).Warn("cannot start workspace group - stopping the members we've started")
+		if serr := m.stopWorkspaceGroup(ctx, req.Id, "", stopWorkspaceImmediatelyGracePeriod); serr != nil {
+			clog.WithError(serr).Error("cannot stop workspace group")
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot start workspace group: %v", err)
+	}
+
+	return res, nil
+}
+
+// planWorkspaceGroup validates a group and returns its members in the order we must start them.
+// It also adds the group metadata and the environment variables for service discovery to the members' start requests.
+// instanceHost returns the cluster-internal hostname of a member, or an empty string if members have none.
+func planWorkspaceGroup(req *api.StartWorkspaceGroupRequest, workspaceURL func(*api.StartWorkspaceRequest) (string, error), instanceHost func(*api.StartWorkspaceRequest) string) ([]*api.WorkspaceGroupMember, error) {
+	if !groupIDRegexp.MatchString(req.Id) {
+		return nil, xerrors.Errorf("group ID %q must be a valid label value", req.Id)
+	}
+	if len(req.Members) == 0 {
+		return nil, xerrors.Errorf("group has no members")
+	}
+	if req.Timeout != "" {
+		if _, err := time.ParseDuration(req.Timeout); err != nil {
+			return nil, xerrors.Errorf("invalid timeout %q: %w", req.Timeout, err)
+		}
+	}
+
+	members := make(map[string]*api.WorkspaceGroupMember, len(req.Members))
+	envNames := make(map[string]string, len(req.Members))
+	for _, member := range req.Members {
+		if !groupMemberNameRegexp.MatchString(member.Name) {
+			return nil, xerrors.Errorf("member name %q must consist of lower case alphanumeric characters or '-'", member.Name)
+		}
+		if _, exists := members[member.Name]; exists {
+			return nil, xerrors.Errorf("duplicate member %s", member.Name)
+		}
+		envName := groupMemberEnvName(member.Name)
+		if other, exists := envNames[envName]; exists {
+			return nil, xerrors.Errorf("members %s and %s have the same environment variable name", other, member.Name)
+		}
+		ws := member.Workspace
+		if ws == nil || ws.Spec == nil || ws.Metadata == nil {
+			return nil, xerrors.Errorf("member %s has no workspace", member.Name)
+		}
+		if ws.Type != api.WorkspaceType_REGULAR {
+			return nil, xerrors.Errorf("member %s must be a regular workspace", member.Name)
+		}
+		if ws.Metadata.GroupId != "" && ws.Metadata.GroupId != req.Id {
+			return nil, xerrors.Errorf("member %s belongs to group %s", member.Name, ws.Metadata.GroupId)
+		}
+		if err := validateStartWorkspaceRequest(ws); err != nil {
+			return nil, xerrors.Errorf("member %s: %w", member.Name, err)
+		}
+		members[member.Name] = member
+		envNames[envName] = member.Name
+	}
+	for _, member := range req.Members {
+		for _, dep := range member.DependsOn {
+			if _, exists := members[dep]; !exists {
+				return nil, xerrors.Errorf("member %s depends on unknown member %s", member.Name, dep)
+			}
+		}
+	}
+
+	// we start members in topological order, keeping the request order where dependencies permit
+	var (
+		order   = make([]*api.WorkspaceGroupMember, 0, len(req.Members))
+		visited = make(map[string]bool, len(req.Members))
+		visit   func(member *api.WorkspaceGroupMember, path []string) error
+	)
+	visit = func(member *api.WorkspaceGroupMember, path []string) error {
+		done, seen := visited[member.Name]
+		if done {
+			return nil
+		}
+		if seen {
+			return xerrors.Errorf("dependency cycle: %s", strings.Join(append(path, member.Name), " -> "))
+		}
+		visited[member.Name] = false
+		for _, dep := range member.DependsOn {
+			if err := visit(members[dep], append(path, member.Name)); err != nil {
+				return err
+			}
+		}
+		visited[member.Name] = true
+		order = append(order, member)
+		return nil
+	}
+	for _, member := range req.Members {
+		if err := visit(member, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	env := []*api.EnvironmentVariable{{Name: "GITPOD_GROUP_ID", Value: req.Id}}
+	for _, member := range req.Members {
+		url, err := workspaceURL(member.Workspace)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot render URL of member %s: %w", member.Name, err)
+		}
+		env = append(env, &api.EnvironmentVariable{Name: fmt.Sprintf("GITPOD_GROUP_%s_URL", groupMemberEnvName(member.Name)), Value: url})
+		if host := instanceHost(member.Workspace); host != "" {
+			env = append(env, &api.EnvironmentVariable{Name: fmt.Sprintf("GITPOD_GROUP_%s_HOST", groupMemberEnvName(member.Name)), Value: host})
+		}
+	}
+	for _, member := range order {
+		ws := member.Workspace
+		ws.Metadata.GroupId = req.Id
+		ws.Spec.Envvars = append(ws.Spec.Envvars, &api.EnvironmentVariable{Name: "GITPOD_GROUP_MEMBER", Value: member.Name})
+		ws.Spec.Envvars = append(ws.Spec.Envvars, env...)
+		if req.Timeout != "" {
+			ws.Spec.Timeout = req.Timeout
+		}
+	}
+
+	return order, nil
+}
+
+// groupMemberEnvName turns a member name into the part of an environment variable name
+func groupMemberEnvName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// waitForGroupMember waits until a workspace is running. We give up if the workspace fails or stops,
+// or if it does not become running within the total startup timeout.
+func (m *Manager) waitForGroupMember(ctx context.Context, workspaceID string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(m.Config.Timeouts.TotalStartup))
+	defer cancel()
+
+	for {
+		pod, err := m.findWorkspacePod(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
+		sts, err := m.getWorkspaceStatus(workspaceObjects{Pod: pod})
+		if err != nil {
+			return err
+		}
+		if sts.Conditions.Failed != "" {
+			return xerrors.Errorf("workspace failed: %s", sts.Conditions.Failed)
+		}
+		switch sts.Phase {
+		case api.WorkspacePhase_RUNNING:
+			return nil
+		case api.WorkspacePhase_STOPPING, api.WorkspacePhase_STOPPED:
+			return xerrors.Errorf("workspace is %s", strings.ToLower(sts.Phase.String()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(groupMemberPollInterval):
+		}
+	}
+}
+
+// StopWorkspaceGroup stops all workspaces of a group
+func (m *Manager) StopWorkspaceGroup(ctx context.Context, req *api.StopWorkspaceGroupRequest) (res *api.StopWorkspaceGroupResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "StopWorkspaceGroup")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "StopWorkspaceGroup", Parameters: map[string]string{"group": req.Id, "policy": req.Policy.String()}}, err)
+	}()
+
+	pods, err := m.listWorkspaceGroupPods(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot stop workspace group: %v", err)
+	}
+	if len(pods) == 0 {
+		return nil, status.Errorf(codes.NotFound, "workspace group %s does not exist", req.Id)
+	}
+
+	gracePeriod := stopWorkspaceNormallyGracePeriod
+	if req.Policy == api.StopWorkspacePolicy_IMMEDIATELY {
+		gracePeriod = stopWorkspaceImmediatelyGracePeriod
+	}
+	err = m.stopWorkspaceGroup(ctx, req.Id, "", gracePeriod)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot stop workspace group: %v", err)
+	}
+
+	return &api.StopWorkspaceGroupResponse{}, nil
+}
+
+// stopWorkspaceGroup stops all workspaces of a group except the one with the workspace ID except.
+// Workspaces which are stopping already are left alone.
+func (m *Manager) stopWorkspaceGroup(ctx context.Context, groupID, except string, gracePeriod time.Duration) error {
+	pods, err := m.listWorkspaceGroupPods(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, pod := range pods {
+		workspaceID, ok := pod.Annotations[workspaceIDAnnotation]
+		if !ok || workspaceID == except || pod.DeletionTimestamp != nil {
+			continue
+		}
+		err := m.stopWorkspace(ctx, workspaceID, gracePeriod)
+		if err != nil && !isKubernetesObjNotFoundError(err) {
+			errs = append(errs, fmt.Sprintf("workspaceId=%s: %q", workspaceID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return xerrors.Errorf("cannot stop %d members: %s", len(errs), strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// DescribeWorkspaceGroup returns the status of all workspaces of a group
+func (m *Manager) DescribeWorkspaceGroup(ctx context.Context, req *api.DescribeWorkspaceGroupRequest) (res *api.DescribeWorkspaceGroupResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "DescribeWorkspaceGroup")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+
+	pods, err := m.listWorkspaceGroupPods(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get workspace group status: %v", err)
+	}
+	if len(pods) == 0 {
+		return nil, status.Errorf(codes.NotFound, "workspace group %s does not exist", req.Id)
+	}
+
+	res = &api.DescribeWorkspaceGroupResponse{}
+	for i := range pods {
+		pod := &pods[i]
+		// see DescribeWorkspace for why we determine the generation before computing the status
+		generation := m.generations.Current(pod.Annotations[workspaceIDAnnotation])
+
+		wso, err := m.getWorkspaceObjects(ctx, pod)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot get workspace group status: %v", err)
+		}
+		sts, err := m.getWorkspaceStatus(*wso)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot get workspace group status: %v", err)
+		}
+		sts.Generation = generation
+		res.Status = append(res.Status, sts)
+	}
+	return res, nil
+}
+
+// listWorkspaceGroupPods lists the pods of all workspaces of a group
+func (m *Manager) listWorkspaceGroupPods(ctx context.Context, groupID string) ([]corev1.Pod, error) {
+	var pods corev1.PodList
+	err := m.Clientset.List(ctx, &pods, &client.ListOptions{
+		Namespace: m.Config.Namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			markerLabel:         "true",
+			workspaceGroupLabel: groupID,
+		}),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list pods of workspace group %s: %w", groupID, err)
+	}
+	return pods.Items, nil
+}
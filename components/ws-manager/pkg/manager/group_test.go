@@ -0,0 +1,158 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestPlanWorkspaceGroup(t *testing.T) {
+	member := func(name string, dependsOn ...string) *api.WorkspaceGroupMember {
+		return &api.WorkspaceGroupMember{
+			Name:      name,
+			DependsOn: dependsOn,
+			Workspace: &api.StartWorkspaceRequest{
+				Id:            name + "-id",
+				ServicePrefix: name + "-prefix",
+				Metadata:      &api.WorkspaceMetadata{Owner: "owner", MetaId: "meta"},
+				Type:          api.WorkspaceType_REGULAR,
+				Spec: &api.StartWorkspaceSpec{
+					WorkspaceImage:    "image",
+					CheckoutLocation:  "repo",
+					WorkspaceLocation: "repo",
+					Initializer:       &csapi.WorkspaceInitializer{},
+				},
+			},
+		}
+	}
+	workspaceURL := func(req *api.StartWorkspaceRequest) (string, error) {
+		return "https://" + req.ServicePrefix + ".gitpod.io", nil
+	}
+
+	tests := []struct {
+		Name          string
+		Request       *api.StartWorkspaceGroupRequest
+		ExpectedOrder []string
+		ExpectedError string
+	}{
+		{
+			Name:          "dependency order",
+			Request:       &api.StartWorkspaceGroupRequest{Id: "grp", Members: []*api.WorkspaceGroupMember{member("frontend", "backend"), member("backend", "db"), member("db"), member("docs")}},
+			ExpectedOrder: []string{"db", "backend", "frontend", "docs"},
+		},
+		{
+			Name:          "cycle",
+			Request:       &api.StartWorkspaceGroupRequest{Id: "grp", Members: []*api.WorkspaceGroupMember{member("a", "b"), member("b", "c"), member("c", "a")}},
+			ExpectedError: "dependency cycle: a -> b -> c -> a",
+		},
+		{
+			Name:          "unknown dependency",
+			Request:       &api.StartWorkspaceGroupRequest{Id: "grp", Members: []*api.WorkspaceGroupMember{member("a", "b")}},
+			ExpectedError: "member a depends on unknown member b",
+		},
+		{
+			Name:          "duplicate member",
+			Request:       &api.StartWorkspaceGroupRequest{Id: "grp", Members: []*api.WorkspaceGroupMember{member("a"), member("a")}},
+			ExpectedError: "duplicate member a",
+		},
+		{
+			Name:          "invalid member name",
+			Request:       &api.StartWorkspaceGroupRequest{Id: "grp", Members: []*api.WorkspaceGroupMember{member("Backend")}},
+			ExpectedError: "member name \"Backend\"",
+		},
+		{
+			Name:          "invalid group ID",
+			Request:       &api.StartWorkspaceGroupRequest{Id: "grp/1", Members: []*api.WorkspaceGroupMember{member("a")}},
+			ExpectedError: "group ID \"grp/1\"",
+		},
+		{
+			Name:          "invalid timeout",
+			Request:       &api.StartWorkspaceGroupRequest{Id: "grp", Timeout: "forever", Members: []*api.WorkspaceGroupMember{member("a")}},
+			ExpectedError: "invalid timeout",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			members, err := planWorkspaceGroup(test.Request, workspaceURL, func(*api.StartWorkspaceRequest) string { return "" })
+			if test.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.ExpectedError) {
+					t.Fatalf("expected error containing %q, got %v", test.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var order []string
+			for _, m := range members {
+				order = append(order, m.Name)
+			}
+			if diff := cmp.Diff(test.ExpectedOrder, order); diff != "" {
+				t.Errorf("unexpected start order (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlanWorkspaceGroupEnvironment(t *testing.T) {
+	req := &api.StartWorkspaceGroupRequest{
+		Id:      "grp",
+		Timeout: "2h",
+		Members: []*api.WorkspaceGroupMember{
+			{
+				Name: "api-server",
+				Workspace: &api.StartWorkspaceRequest{
+					Id:            "ws1",
+					ServicePrefix: "ws1",
+					Metadata:      &api.WorkspaceMetadata{Owner: "owner", MetaId: "meta"},
+					Spec: &api.StartWorkspaceSpec{
+						WorkspaceImage:    "image",
+						CheckoutLocation:  "repo",
+						WorkspaceLocation: "repo",
+						Initializer:       &csapi.WorkspaceInitializer{},
+						Envvars:           []*api.EnvironmentVariable{{Name: "FOO", Value: "bar"}},
+					},
+				},
+			},
+		},
+	}
+	members, err := planWorkspaceGroup(req, func(req *api.StartWorkspaceRequest) (string, error) {
+		return "https://" + req.ServicePrefix + ".gitpod.io", nil
+	}, func(req *api.StartWorkspaceRequest) string {
+		return "ws-" + req.Id + ".default.svc.cluster.local"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws := members[0].Workspace
+	env := make(map[string]string)
+	for _, e := range ws.Spec.Envvars {
+		env[e.Name] = e.Value
+	}
+	expectedEnv := map[string]string{
+		"FOO":                          "bar",
+		"GITPOD_GROUP_ID":              "grp",
+		"GITPOD_GROUP_MEMBER":          "api-server",
+		"GITPOD_GROUP_API_SERVER_URL":  "https://ws1.gitpod.io",
+		"GITPOD_GROUP_API_SERVER_HOST": "ws-ws1.default.svc.cluster.local",
+	}
+	if diff := cmp.Diff(expectedEnv, env); diff != "" {
+		t.Errorf("unexpected environment (-want +got):\n%s", diff)
+	}
+	if ws.Metadata.GroupId != "grp" {
+		t.Errorf("unexpected group ID: %q", ws.Metadata.GroupId)
+	}
+	if ws.Spec.Timeout != "2h" {
+		t.Errorf("unexpected timeout: %q", ws.Spec.Timeout)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/mod/semver"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitpod-io/gitpod/common-go/imageref"
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	regapi "github.com/gitpod-io/gitpod/registry-facade/api"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// ideSupervisorMinVersionLabel is the image label with the oldest supervisor version an IDE image works with
+	ideSupervisorMinVersionLabel = "io.gitpod.ide.supervisor.min-version"
+	// ideSupervisorMaxVersionLabel is the image label with the newest supervisor version an IDE image works with. It's optional.
+	ideSupervisorMaxVersionLabel = "io.gitpod.ide.supervisor.max-version"
+
+	// defaultIDEPinTimeout is the time we allow for resolving the labels of a pinned IDE image
+	defaultIDEPinTimeout = 10 * time.Second
+)
+
+// IDEPinningConfig configures which IDE images workspaces can be pinned to
+type IDEPinningConfig struct {
+	// Repositories are the image repositories pinned IDE images can come from, e.g. eu.gcr.io/gitpod-core-dev/build/ide/code
+	Repositories []string `json:"repositories"`
+	// SupervisorVersion is the version of the supervisor this installation ships. Pinned IDE images must declare
+	// that they work with it.
+	SupervisorVersion string `json:"supervisorVersion"`
+	// RegistryAuthFile is a Docker config.json with the credentials of the registries IDE images come from
+	RegistryAuthFile string `json:"registryAuthFile,omitempty"`
+	// Timeout is the time we allow for resolving the labels of a pinned IDE image. Defaults to 10 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *IDEPinningConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Repositories, validation.Required, validation.By(func(o interface{}) error {
+			repos, ok := o.([]string)
+			if !ok {
+				return xerrors.Errorf("field should be a list of repositories")
+			}
+			for _, r := range repos {
+				if r == "" || strings.Contains(r, "@") || strings.LastIndexByte(r, ':') > strings.LastIndexByte(r, '/') {
+					return xerrors.Errorf("%q is not a repository", r)
+				}
+			}
+			return nil
+		})),
+		validation.Field(&c.SupervisorVersion, validation.Required, validation.By(func(o interface{}) error {
+			v, ok := o.(string)
+			if !ok || !semver.IsValid(canonicalVersion(v)) {
+				return xerrors.Errorf("must be a semantic version")
+			}
+			return nil
+		})),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+	)
+}
+
+// ideImagePin is the content of the IDE pin annotation
+type ideImagePin struct {
+	PinnedImage       string `json:"pinnedImage"`
+	DefaultImage      string `json:"defaultImage"`
+	SupervisorVersion string `json:"supervisorVersion"`
+}
+
+// IncompatibleIDEError is returned when a pinned IDE image does not work with the supervisor of this installation
+type IncompatibleIDEError struct {
+	Image             string
+	SupervisorVersion string
+	MinVersion        string
+	MaxVersion        string
+}
+
+func (e *IncompatibleIDEError) Error() string {
+	supported := "at least " + e.MinVersion
+	if e.MaxVersion != "" {
+		supported = fmt.Sprintf("%s to %s", e.MinVersion, e.MaxVersion)
+	}
+	return fmt.Sprintf("IDE image %s works with supervisor %s, but this installation runs supervisor %s", e.Image, supported, e.SupervisorVersion)
+}
+
+// imageLabelResolver resolves the labels of an image
+type imageLabelResolver interface {
+	ResolveLabels(ctx context.Context, ref string) (map[string]string, error)
+}
+
+// checkIDEPin makes sure a workspace can only be pinned to IDE images we allow and which work with our supervisor
+func (m *Manager) checkIDEPin(ctx context.Context, req *api.StartWorkspaceRequest) (err error) {
+	if req.Spec == nil || req.Spec.PinnedIdeImage == "" {
+		return nil
+	}
+	cfg := m.Config.IDEPinning
+	if cfg == nil || m.ideLabels == nil {
+		return errStartWorkspaceInvalid(xerrors.Errorf("this installation does not support pinned IDE images"))
+	}
+
+	span, ctx := tracing.FromContext(ctx, "checkIDEPin")
+	defer tracing.FinishSpan(span, &err)
+
+	pinned := req.Spec.PinnedIdeImage
+	err = checkIDEPinRef(pinned, cfg.Repositories)
+	if err != nil {
+		return errStartWorkspaceInvalid(err)
+	}
+
+	timeout := time.Duration(cfg.Timeout)
+	if timeout == 0 {
+		timeout = defaultIDEPinTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	labels, err := m.ideLabels.ResolveLabels(ctx, m.imageRewriter.Rewrite(pinned))
+	if err != nil {
+		// unlike the workspace image compatibility we do not start the workspace if we cannot tell:
+		// a pin which silently breaks the IDE is worse than a failed start.
+		return errStartWorkspaceImage("Cannot verify the pinned IDE image "+pinned, err)
+	}
+	err = checkIDECompatibility(pinned, labels, cfg.SupervisorVersion)
+	if err != nil {
+		return errStartWorkspaceImage("The pinned IDE image cannot be used: "+err.Error(), err)
+	}
+	log.WithFields(log.OWI(req.Metadata.Owner, req.Metadata.MetaId, req.Id)).WithField("ideImage", pinned).Info("workspace starts with pinned IDE image")
+	return nil
+}
+
+// checkIDEPinRef fails if the reference is not from one of the repositories, or is not pinned to a tag or digest
+func checkIDEPinRef(ref string, repositories []string) error {
+	host, repo, reference, err := parseImageRef(ref)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(ref, "@") && !strings.HasSuffix(ref, ":"+reference) {
+		return xerrors.Errorf("pinned IDE image %s has neither a tag nor a digest", ref)
+	}
+	if reference == "latest" {
+		return xerrors.Errorf("pinned IDE image %s must not use the latest tag", ref)
+	}
+
+	name := host + "/" + repo
+	for _, r := range repositories {
+		if strings.TrimSuffix(imageref.Normalize(r), ":latest") == name {
+			return nil
+		}
+	}
+	return xerrors.Errorf("pinned IDE image %s is not from an allowed repository", ref)
+}
+
+// checkIDECompatibility fails with an IncompatibleIDEError if the image labels do not include the supervisor version
+func checkIDECompatibility(ref string, labels map[string]string, supervisorVersion string) error {
+	minVersion, maxVersion := labels[ideSupervisorMinVersionLabel], labels[ideSupervisorMaxVersionLabel]
+	if minVersion == "" {
+		return xerrors.Errorf("IDE image %s does not declare the supervisor versions it works with (%s label)", ref, ideSupervisorMinVersionLabel)
+	}
+	if !semver.IsValid(canonicalVersion(minVersion)) {
+		return xerrors.Errorf("IDE image %s has an invalid %s label: %s", ref, ideSupervisorMinVersionLabel, minVersion)
+	}
+	if maxVersion != "" && !semver.IsValid(canonicalVersion(maxVersion)) {
+		return xerrors.Errorf("IDE image %s has an invalid %s label: %s", ref, ideSupervisorMaxVersionLabel, maxVersion)
+	}
+
+	v := canonicalVersion(supervisorVersion)
+	if semver.Compare(v, canonicalVersion(minVersion)) < 0 || (maxVersion != "" && semver.Compare(v, canonicalVersion(maxVersion)) > 0) {
+		return &IncompatibleIDEError{
+			Image:             ref,
+			SupervisorVersion: supervisorVersion,
+			MinVersion:        minVersion,
+			MaxVersion:        maxVersion,
+		}
+	}
+	return nil
+}
+
+// canonicalVersion adds the v prefix semver expects
+func canonicalVersion(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
+
+// getIDEPin returns the IDE pin of a workspace pod, or nil if it has none
+func getIDEPin(pod *corev1.Pod) (*api.IDEPin, error) {
+	v, ok := pod.Annotations[workspaceIDEPinAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var pin ideImagePin
+	err := json.Unmarshal([]byte(v), &pin)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid IDE pin: %w", err)
+	}
+	return &api.IDEPin{
+		PinnedImage:       pin.PinnedImage,
+		DefaultImage:      pin.DefaultImage,
+		SupervisorVersion: pin.SupervisorVersion,
+	}, nil
+}
+
+// ResetIDEPin removes the IDE pin of a running workspace. The workspace uses the default IDE image the next time
+// registry-facade serves its image, i.e. once the workspace container restarts.
+func (m *Manager) ResetIDEPin(ctx context.Context, req *api.ResetIDEPinRequest) (res *api.ResetIDEPinResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "ResetIDEPin")
+	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "ResetIDEPin", Id: req.Id}, err)
+	}()
+
+	pod, err := m.findWorkspacePod(ctx, req.Id)
+	if isKubernetesObjNotFoundError(err) {
+		return nil, status.Errorf(codes.NotFound, "workspace %s does not exist", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get workspace status: %q", err)
+	}
+	tracing.ApplyOWI(span, wsk8s.GetOWIFromObject(&pod.ObjectMeta))
+
+	pin, err := getIDEPin(pod)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot reset IDE pin: %q", err)
+	}
+	if pin == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "workspace %s has no pinned IDE image", req.Id)
+	}
+
+	spec, err := regapi.ImageSpecFromBase64(pod.Annotations[workspaceImageSpecAnnotation])
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid image spec: %q", err)
+	}
+	spec.IdeRef = pin.DefaultImage
+	imageSpec, err := spec.ToBase64()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot remarshal image spec: %q", err)
+	}
+	err = m.markWorkspace(ctx, req.Id, addMark(workspaceImageSpecAnnotation, imageSpec), deleteMark(workspaceIDEPinAnnotation))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot reset IDE pin: %q", err)
+	}
+	log.WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).WithField("pinnedImage", pin.PinnedImage).WithField("ideImage", pin.DefaultImage).Info("reset IDE pin")
+
+	return &api.ResetIDEPinResponse{IdeImage: pin.DefaultImage}, nil
+}
+
+// ResolveLabels resolves the labels of an image from its config. For multi-platform images we use the config of the first platform.
+func (r *registryPlatformResolver) ResolveLabels(ctx context.Context, ref string) (map[string]string, error) {
+	host, repo, reference, err := parseImageRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	accept := []string{ocispec.MediaTypeImageIndex, mediaTypeDockerManifestList, ocispec.MediaTypeImageManifest, mediaTypeDockerManifest}
+	mediaType, body, err := r.get(ctx, host, r.url(host, "/v2/"+repo+"/manifests/"+reference), accept)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType == ocispec.MediaTypeImageIndex || mediaType == mediaTypeDockerManifestList {
+		var index ocispec.Index
+		err = json.Unmarshal(body, &index)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse manifest list of %s: %w", ref, err)
+		}
+		var dgst string
+		for _, m := range index.Manifests {
+			if m.Platform != nil && m.Platform.OS == "unknown" {
+				continue
+			}
+			dgst = m.Digest.String()
+			break
+		}
+		if dgst == "" {
+			return nil, xerrors.Errorf("manifest list of %s has no image manifest", ref)
+		}
+		_, body, err = r.get(ctx, host, r.url(host, "/v2/"+repo+"/manifests/"+dgst), accept[2:])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := r.config(ctx, host, repo, ref, body)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Config.Labels, nil
+}
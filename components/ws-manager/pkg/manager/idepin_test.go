@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestCheckIDEPinRef(t *testing.T) {
+	repos := []string{"eu.gcr.io/gitpod-core-dev/build/ide/code", "gitpod/openvscode-server"}
+	tests := []struct {
+		Name  string
+		Ref   string
+		Error bool
+	}{
+		{Name: "tag", Ref: "eu.gcr.io/gitpod-core-dev/build/ide/code:commit-abc"},
+		{Name: "digest", Ref: "eu.gcr.io/gitpod-core-dev/build/ide/code@sha256:aaaa"},
+		{Name: "docker hub", Ref: "docker.io/gitpod/openvscode-server:1.58.0"},
+		{Name: "no tag", Ref: "eu.gcr.io/gitpod-core-dev/build/ide/code", Error: true},
+		{Name: "latest", Ref: "gitpod/openvscode-server:latest", Error: true},
+		{Name: "other repository", Ref: "eu.gcr.io/gitpod-core-dev/build/ide/theia:commit-abc", Error: true},
+		{Name: "repository prefix", Ref: "eu.gcr.io/gitpod-core-dev/build/ide/code-fork:commit-abc", Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := checkIDEPinRef(test.Ref, repos)
+			if act := err != nil; act != test.Error {
+				t.Errorf("error = %v, expected %v: %v", act, test.Error, err)
+			}
+		})
+	}
+}
+
+func TestCheckIDECompatibility(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Labels       map[string]string
+		Incompatible bool
+		Error        bool
+	}{
+		{Name: "min version", Labels: map[string]string{ideSupervisorMinVersionLabel: "1.2.0"}},
+		{Name: "range", Labels: map[string]string{ideSupervisorMinVersionLabel: "v1.0.0", ideSupervisorMaxVersionLabel: "1.2.3"}},
+		{Name: "too new", Labels: map[string]string{ideSupervisorMinVersionLabel: "1.3.0"}, Incompatible: true},
+		{Name: "too old", Labels: map[string]string{ideSupervisorMinVersionLabel: "1.0.0", ideSupervisorMaxVersionLabel: "1.2.2"}, Incompatible: true},
+		{Name: "no labels", Error: true},
+		{Name: "invalid label", Labels: map[string]string{ideSupervisorMinVersionLabel: "latest"}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := checkIDECompatibility("code:commit-abc", test.Labels, "1.2.3")
+
+			var incompatible *IncompatibleIDEError
+			if act := xerrors.As(err, &incompatible); act != test.Incompatible {
+				t.Errorf("incompatible = %v, expected %v: %v", act, test.Incompatible, err)
+			}
+			if act := err != nil && incompatible == nil; act != test.Error {
+				t.Errorf("error = %v, expected %v: %v", act, test.Error, err)
+			}
+		})
+	}
+}
+
+func TestRegistryLabelResolver(t *testing.T) {
+	srv := fakeRegistry(t, "")
+	host := strings.TrimPrefix(srv.URL, "http://")
+	r := &registryPlatformResolver{Client: srv.Client(), Scheme: "http"}
+
+	tests := []struct {
+		Image    string
+		Expected map[string]string
+	}{
+		{Image: "multi:latest", Expected: map[string]string{ideSupervisorMinVersionLabel: "1.2.0"}},
+		{Image: "single:latest", Expected: map[string]string{ideSupervisorMinVersionLabel: "1.0.0", ideSupervisorMaxVersionLabel: "1.1.0"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Image, func(t *testing.T) {
+			act, err := r.ResolveLabels(context.Background(), host+"/"+test.Image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected labels (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetIDEPin(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Annotations map[string]string
+		Expectation *api.IDEPin
+		Error       bool
+	}{
+		{Name: "no pin"},
+		{Name: "invalid pin", Annotations: map[string]string{workspaceIDEPinAnnotation: "code"}, Error: true},
+		{
+			Name:        "pin",
+			Annotations: map[string]string{workspaceIDEPinAnnotation: `{"pinnedImage":"code:old","defaultImage":"code:new","supervisorVersion":"1.2.3"}`},
+			Expectation: &api.IDEPin{PinnedImage: "code:old", DefaultImage: "code:new", SupervisorVersion: "1.2.3"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := getIDEPin(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: test.Annotations}})
+			if act := err != nil; act != test.Error {
+				t.Fatalf("error = %v, expected %v: %v", act, test.Error, err)
+			}
+			if diff := cmp.Diff(test.Expectation, act, cmp.Comparer(func(a, b *api.IDEPin) bool { return a.String() == b.String() })); diff != "" {
+				t.Errorf("unexpected pin (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,517 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/imageref"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+
+	// defaultImageCompatibilityTimeout is the time we allow for resolving the platforms of an image
+	defaultImageCompatibilityTimeout = 10 * time.Second
+)
+
+// ImageCompatibilityConfig configures the checks we run to make sure workspace images can run on the nodes
+type ImageCompatibilityConfig struct {
+	// NodePlatforms are the platforms of the node pools workspaces run on. An image must support at least one of them.
+	NodePlatforms []ImagePlatform `json:"nodePlatforms"`
+	// RegistryAuthFile is a Docker config.json with the credentials of the registries workspace images come from
+	RegistryAuthFile string `json:"registryAuthFile,omitempty"`
+	// Timeout is the time we allow for resolving the platforms of an image. If we cannot resolve them in time,
+	// we start the workspace anyways. Defaults to 10 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ImageCompatibilityConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.NodePlatforms, validation.Required, validation.By(func(o interface{}) error {
+			ps, ok := o.([]ImagePlatform)
+			if !ok {
+				return xerrors.Errorf("field should be a list of platforms")
+			}
+			for i, p := range ps {
+				if p.OS == "" || p.Architecture == "" {
+					return xerrors.Errorf("platform %d: os and architecture are required", i)
+				}
+			}
+			return nil
+		})),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+	)
+}
+
+// ImagePlatform is a platform images are built for and nodes provide
+type ImagePlatform struct {
+	OS           string   `json:"os"`
+	Architecture string   `json:"architecture"`
+	Variant      string   `json:"variant,omitempty"`
+	OSFeatures   []string `json:"osFeatures,omitempty"`
+}
+
+func (p ImagePlatform) String() string {
+	res := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		res += "/" + p.Variant
+	}
+	return res
+}
+
+// runsOn returns true if an image built for this platform runs on nodes of the other platform
+func (p ImagePlatform) runsOn(node ImagePlatform) bool {
+	if p.OS != node.OS || normalizeArchitecture(p.Architecture) != normalizeArchitecture(node.Architecture) {
+		return false
+	}
+	if p.Variant != "" && node.Variant != "" && p.Variant != node.Variant {
+		return false
+	}
+	for _, f := range p.OSFeatures {
+		var found bool
+		for _, nf := range node.OSFeatures {
+			if f == nf {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeArchitecture maps the architecture names of uname to those of Go, which images use
+func normalizeArchitecture(arch string) string {
+	switch arch {
+	case "x86_64", "x86-64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	default:
+		return arch
+	}
+}
+
+// IncompatibleImageError is returned when a workspace image cannot run on any of the node pools
+type IncompatibleImageError struct {
+	Image     string
+	Platforms []ImagePlatform
+	Nodes     []ImagePlatform
+}
+
+func (e *IncompatibleImageError) Error() string {
+	platforms := make([]string, len(e.Platforms))
+	for i, p := range e.Platforms {
+		platforms[i] = p.String()
+	}
+	nodes := make([]string, len(e.Nodes))
+	for i, p := range e.Nodes {
+		nodes[i] = p.String()
+	}
+	return fmt.Sprintf("image %s is built for %s, but workspaces run on %s", e.Image, strings.Join(platforms, ", "), strings.Join(nodes, ", "))
+}
+
+// checkWorkspaceImageCompatibility fails fast if the workspace image cannot run on any of the node pools.
+// If we cannot resolve the platforms of the image we start the workspace anyways.
+func (m *Manager) checkWorkspaceImageCompatibility(ctx context.Context, req *api.StartWorkspaceRequest) (err error) {
+	cfg := m.Config.ImageCompatibility
+	if cfg == nil || m.imagePlatforms == nil || req.Spec == nil || req.Spec.WorkspaceImage == "" {
+		return nil
+	}
+
+	span, ctx := tracing.FromContext(ctx, "checkWorkspaceImageCompatibility")
+	defer tracing.FinishSpan(span, &err)
+
+	timeout := time.Duration(cfg.Timeout)
+	if timeout == 0 {
+		timeout = defaultImageCompatibilityTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ref := m.imageRewriter.Rewrite(req.Spec.WorkspaceImage)
+	err = checkImageCompatibility(ctx, m.imagePlatforms, ref, cfg.NodePlatforms)
+	var incompatible *IncompatibleImageError
+	if xerrors.As(err, &incompatible) {
+		return errStartWorkspaceImage("The workspace image cannot run in this cluster: "+incompatible.Error(), incompatible)
+	}
+	if err != nil {
+		log.WithFields(log.OWI(req.Metadata.Owner, req.Metadata.MetaId, req.Id)).WithError(err).WithField("image", ref).Warn("cannot check workspace image compatibility - starting workspace anyways")
+		return nil
+	}
+	return nil
+}
+
+// checkImageCompatibility fails with an IncompatibleImageError if the image cannot run on any node platform.
+// Any other error means we could not tell.
+func checkImageCompatibility(ctx context.Context, resolver imagePlatformResolver, ref string, nodes []ImagePlatform) error {
+	platforms, err := resolver.ResolvePlatforms(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if len(platforms) == 0 {
+		// the image does not tell which platform it's built for - we'll have to try
+		return nil
+	}
+	for _, p := range platforms {
+		for _, n := range nodes {
+			if p.runsOn(n) {
+				return nil
+			}
+		}
+	}
+	return &IncompatibleImageError{Image: ref, Platforms: platforms, Nodes: nodes}
+}
+
+// imagePlatformResolver resolves the platforms an image is built for
+type imagePlatformResolver interface {
+	ResolvePlatforms(ctx context.Context, ref string) ([]ImagePlatform, error)
+}
+
+// registryPlatformResolver resolves the platforms of an image from its manifest in the registry
+type registryPlatformResolver struct {
+	Client *http.Client
+	// Auth maps registry hosts to their base64 encoded basic auth credentials
+	Auth map[string]string
+	// Scheme is the scheme we use to talk to registries. Defaults to https.
+	Scheme string
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// newRegistryPlatformResolver creates a resolver which uses the credentials of a Docker config.json file
+func newRegistryPlatformResolver(authFile string) (*registryPlatformResolver, error) {
+	res := &registryPlatformResolver{
+		Client: &http.Client{},
+		Auth:   make(map[string]string),
+		tokens: make(map[string]string),
+	}
+	if authFile == "" {
+		return res, nil
+	}
+
+	fc, err := os.ReadFile(authFile)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read registry auth file: %w", err)
+	}
+	var cfg struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	err = json.Unmarshal(fc, &cfg)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse registry auth file: %w", err)
+	}
+	for host, a := range cfg.Auths {
+		host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+		host = strings.SplitN(host, "/", 2)[0]
+		if host == "index.docker.io" {
+			host = "docker.io"
+		}
+		res.Auth[host] = a.Auth
+	}
+	return res, nil
+}
+
+// ResolvePlatforms resolves the platforms of an image from its manifest list, or the config of its manifest
+func (r *registryPlatformResolver) ResolvePlatforms(ctx context.Context, ref string) ([]ImagePlatform, error) {
+	host, repo, reference, err := parseImageRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		manifestURL = r.url(host, "/v2/"+repo+"/manifests/"+reference)
+		accept      = []string{ocispec.MediaTypeImageIndex, mediaTypeDockerManifestList, ocispec.MediaTypeImageManifest, mediaTypeDockerManifest}
+	)
+	mediaType, body, err := r.get(ctx, host, manifestURL, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mediaType {
+	case ocispec.MediaTypeImageIndex, mediaTypeDockerManifestList:
+		var index ocispec.Index
+		err = json.Unmarshal(body, &index)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse manifest list of %s: %w", ref, err)
+		}
+		var res []ImagePlatform
+		for _, m := range index.Manifests {
+			if m.Platform == nil || m.Platform.OS == "unknown" {
+				// e.g. attestation manifests
+				continue
+			}
+			res = append(res, ImagePlatform{
+				OS:           m.Platform.OS,
+				Architecture: m.Platform.Architecture,
+				Variant:      m.Platform.Variant,
+				OSFeatures:   m.Platform.OSFeatures,
+			})
+		}
+		return res, nil
+
+	default:
+		cfg, err := r.config(ctx, host, repo, ref, body)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.OS == "" || cfg.Architecture == "" {
+			return nil, nil
+		}
+		return []ImagePlatform{{OS: cfg.OS, Architecture: cfg.Architecture, Variant: cfg.Variant, OSFeatures: cfg.OSFeatures}}, nil
+	}
+}
+
+// imageConfig is the part of an image config we are interested in
+type imageConfig struct {
+	OS           string   `json:"os"`
+	Architecture string   `json:"architecture"`
+	Variant      string   `json:"variant"`
+	OSFeatures   []string `json:"os.features"`
+	Config       struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+// config fetches the config an image manifest points to
+func (r *registryPlatformResolver) config(ctx context.Context, host, repo, ref string, manifestBody []byte) (*imageConfig, error) {
+	var manifest ocispec.Manifest
+	err := json.Unmarshal(manifestBody, &manifest)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse manifest of %s: %w", ref, err)
+	}
+	if manifest.Config.Digest == "" {
+		return nil, xerrors.Errorf("manifest of %s has no config", ref)
+	}
+	_, body, err := r.get(ctx, host, r.url(host, "/v2/"+repo+"/blobs/"+manifest.Config.Digest.String()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var cfg imageConfig
+	err = json.Unmarshal(body, &cfg)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse config of %s: %w", ref, err)
+	}
+	return &cfg, nil
+}
+
+func (r *registryPlatformResolver) url(host, path string) string {
+	scheme := r.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	return scheme + "://" + host + path
+}
+
+// get fetches a registry resource, authenticating if the registry asks us to
+func (r *registryPlatformResolver) get(ctx context.Context, host, u string, accept []string) (mediaType string, body []byte, err error) {
+	do := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		r.mu.Lock()
+		tkn := r.tokens[host]
+		r.mu.Unlock()
+		if tkn != "" {
+			req.Header.Set("Authorization", tkn)
+		}
+		return r.Client.Do(req)
+	}
+
+	resp, err := do()
+	if err != nil {
+		return "", nil, xerrors.Errorf("cannot reach registry %s: %w", host, err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		err = r.authenticate(ctx, host, challenge)
+		if err != nil {
+			return "", nil, err
+		}
+		resp, err = do()
+		if err != nil {
+			return "", nil, xerrors.Errorf("cannot reach registry %s: %w", host, err)
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, xerrors.Errorf("registry %s responded with %s for %s", host, resp.Status, u)
+	}
+	body, err = io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", nil, xerrors.Errorf("cannot read registry response: %w", err)
+	}
+	mediaType = strings.TrimSpace(strings.SplitN(resp.Header.Get("Content-Type"), ";", 2)[0])
+	return mediaType, body, nil
+}
+
+// authenticate answers the authentication challenge of a registry
+func (r *registryPlatformResolver) authenticate(ctx context.Context, host, challenge string) error {
+	basic := r.Auth[host]
+	scheme, params := parseAuthChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if basic == "" {
+			return xerrors.Errorf("registry %s requires credentials", host)
+		}
+		r.setToken(host, "Basic "+basic)
+		return nil
+	case "bearer":
+	default:
+		return xerrors.Errorf("registry %s uses unsupported authentication %q", host, scheme)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return xerrors.Errorf("registry %s sent an invalid token realm", host)
+	}
+	q := tokenURL.Query()
+	for _, p := range []string{"service", "scope"} {
+		if v := params[p]; v != "" {
+			q.Set(p, v)
+		}
+	}
+	tokenURL.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	if basic != "" {
+		req.Header.Set("Authorization", "Basic "+basic)
+	}
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("cannot get token for registry %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("cannot get token for registry %s: %s", host, resp.Status)
+	}
+
+	var tkn struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&tkn)
+	if err != nil {
+		return xerrors.Errorf("cannot parse token of registry %s: %w", host, err)
+	}
+	if tkn.Token == "" {
+		tkn.Token = tkn.AccessToken
+	}
+	if tkn.Token == "" {
+		return xerrors.Errorf("registry %s sent no token", host)
+	}
+	r.setToken(host, "Bearer "+tkn.Token)
+	return nil
+}
+
+func (r *registryPlatformResolver) setToken(host, tkn string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens == nil {
+		r.tokens = make(map[string]string)
+	}
+	r.tokens[host] = tkn
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header, e.g. Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseAuthChallenge(challenge string) (scheme string, params map[string]string) {
+	params = make(map[string]string)
+	segs := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	scheme = segs[0]
+	if len(segs) < 2 {
+		return
+	}
+
+	rest := segs[1]
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = strings.TrimSpace(rest[eq+1:])
+
+		var val string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				val, rest = rest[1:], ""
+			} else {
+				val, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if comma := strings.IndexByte(rest, ','); comma >= 0 {
+			val, rest = rest[:comma], rest[comma:]
+		} else {
+			val, rest = rest, ""
+		}
+		params[key] = val
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return
+}
+
+// parseImageRef splits an image reference into the registry host, repository and tag or digest
+func parseImageRef(ref string) (host, repo, reference string, err error) {
+	normalized := imageref.Normalize(ref)
+
+	name := normalized
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name, reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndexByte(name, ':'); i > strings.LastIndexByte(name, '/') {
+		name, reference = name[:i], name[i+1:]
+	}
+	segs := strings.SplitN(name, "/", 2)
+	if len(segs) != 2 || reference == "" {
+		return "", "", "", xerrors.Errorf("invalid image reference %s", ref)
+	}
+	return segs[0], segs[1], reference, nil
+}
+
+// basicAuth encodes credentials the way Docker config files do
+func basicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+)
+
+func TestImagePlatformRunsOn(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Image    ImagePlatform
+		Node     ImagePlatform
+		Expected bool
+	}{
+		{"same platform", ImagePlatform{OS: "linux", Architecture: "amd64"}, ImagePlatform{OS: "linux", Architecture: "amd64"}, true},
+		{"architecture alias", ImagePlatform{OS: "linux", Architecture: "amd64"}, ImagePlatform{OS: "linux", Architecture: "x86_64"}, true},
+		{"other architecture", ImagePlatform{OS: "linux", Architecture: "arm64"}, ImagePlatform{OS: "linux", Architecture: "amd64"}, false},
+		{"other os", ImagePlatform{OS: "windows", Architecture: "amd64"}, ImagePlatform{OS: "linux", Architecture: "amd64"}, false},
+		{"variant mismatch", ImagePlatform{OS: "linux", Architecture: "arm", Variant: "v7"}, ImagePlatform{OS: "linux", Architecture: "arm", Variant: "v6"}, false},
+		{"node without variant", ImagePlatform{OS: "linux", Architecture: "arm", Variant: "v7"}, ImagePlatform{OS: "linux", Architecture: "arm"}, true},
+		{"missing os feature", ImagePlatform{OS: "linux", Architecture: "amd64", OSFeatures: []string{"sse4"}}, ImagePlatform{OS: "linux", Architecture: "amd64"}, false},
+		{"present os feature", ImagePlatform{OS: "linux", Architecture: "amd64", OSFeatures: []string{"sse4"}}, ImagePlatform{OS: "linux", Architecture: "amd64", OSFeatures: []string{"avx", "sse4"}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if act := test.Image.runsOn(test.Node); act != test.Expected {
+				t.Errorf("runsOn() = %v, expected %v", act, test.Expected)
+			}
+		})
+	}
+}
+
+func TestParseImageRef(t *testing.T) {
+	tests := []struct {
+		Ref       string
+		Host      string
+		Repo      string
+		Reference string
+	}{
+		{"alpine", "docker.io", "library/alpine", "latest"},
+		{"gitpod/workspace-full:latest", "docker.io", "gitpod/workspace-full", "latest"},
+		{"eu.gcr.io/gitpod/workspace:abc", "eu.gcr.io", "gitpod/workspace", "abc"},
+		{"localhost:5000/foo@sha256:0000", "localhost:5000", "foo", "sha256:0000"},
+	}
+	for _, test := range tests {
+		t.Run(test.Ref, func(t *testing.T) {
+			host, repo, reference, err := parseImageRef(test.Ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]string{test.Host, test.Repo, test.Reference}, []string{host, repo, reference}); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull"`)
+	if scheme != "Bearer" {
+		t.Errorf("unexpected scheme %q", scheme)
+	}
+	expected := map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/alpine:pull",
+	}
+	if diff := cmp.Diff(expected, params); diff != "" {
+		t.Errorf("unexpected params (-want +got):\n%s", diff)
+	}
+}
+
+// fakeRegistry serves an image index and a single-platform image with IDE labels, protected by a bearer token
+func fakeRegistry(t *testing.T, credentials string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if credentials != "" && r.Header.Get("Authorization") != "Basic "+credentials {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "tkn"})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tkn" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/multi/manifests/latest":
+			w.Header().Set("Content-Type", mediaTypeDockerManifestList)
+			fmt.Fprint(w, `{"schemaVersion":2,"manifests":[
+				{"digest":"sha256:aaaa","platform":{"os":"linux","architecture":"amd64"}},
+				{"digest":"sha256:bbbb","platform":{"os":"linux","architecture":"arm64","variant":"v8"}},
+				{"digest":"sha256:cccc","platform":{"os":"unknown","architecture":"unknown"}}
+			]}`)
+		case "/v2/single/manifests/latest":
+			w.Header().Set("Content-Type", mediaTypeDockerManifest)
+			fmt.Fprint(w, `{"schemaVersion":2,"config":{"digest":"sha256:dddd"}}`)
+		case "/v2/multi/manifests/sha256:aaaa":
+			w.Header().Set("Content-Type", mediaTypeDockerManifest)
+			fmt.Fprint(w, `{"schemaVersion":2,"config":{"digest":"sha256:eeee"}}`)
+		case "/v2/multi/blobs/sha256:eeee":
+			fmt.Fprint(w, `{"os":"linux","architecture":"amd64","config":{"Labels":{"io.gitpod.ide.supervisor.min-version":"1.2.0"}}}`)
+		case "/v2/single/blobs/sha256:dddd":
+			fmt.Fprint(w, `{"os":"linux","architecture":"arm64","config":{"Labels":{"io.gitpod.ide.supervisor.min-version":"1.0.0","io.gitpod.ide.supervisor.max-version":"1.1.0"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRegistryPlatformResolver(t *testing.T) {
+	credentials := basicAuth("user", "pass")
+	tests := []struct {
+		Name        string
+		Image       string
+		Credentials string
+		Expected    []ImagePlatform
+		Error       bool
+	}{
+		{
+			Name:  "manifest list",
+			Image: "multi:latest",
+			Expected: []ImagePlatform{
+				{OS: "linux", Architecture: "amd64"},
+				{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			},
+		},
+		{
+			Name:     "single manifest",
+			Image:    "single:latest",
+			Expected: []ImagePlatform{{OS: "linux", Architecture: "arm64"}},
+		},
+		{
+			Name:        "basic auth for token",
+			Image:       "single:latest",
+			Credentials: credentials,
+			Expected:    []ImagePlatform{{OS: "linux", Architecture: "arm64"}},
+		},
+		{
+			Name:  "unknown image",
+			Image: "missing:latest",
+			Error: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := fakeRegistry(t, test.Credentials)
+			host := strings.TrimPrefix(srv.URL, "http://")
+
+			r := &registryPlatformResolver{
+				Client: srv.Client(),
+				Auth:   map[string]string{host: test.Credentials},
+				Scheme: "http",
+			}
+			act, err := r.ResolvePlatforms(context.Background(), host+"/"+test.Image)
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected platforms (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type staticPlatformResolver struct {
+	Platforms []ImagePlatform
+	Err       error
+}
+
+func (r staticPlatformResolver) ResolvePlatforms(ctx context.Context, ref string) ([]ImagePlatform, error) {
+	return r.Platforms, r.Err
+}
+
+func TestCheckImageCompatibility(t *testing.T) {
+	nodes := []ImagePlatform{{OS: "linux", Architecture: "amd64"}}
+	tests := []struct {
+		Name         string
+		Resolver     staticPlatformResolver
+		Incompatible bool
+		Error        bool
+	}{
+		{Name: "compatible", Resolver: staticPlatformResolver{Platforms: []ImagePlatform{{OS: "linux", Architecture: "arm64"}, {OS: "linux", Architecture: "amd64"}}}},
+		{Name: "incompatible", Resolver: staticPlatformResolver{Platforms: []ImagePlatform{{OS: "linux", Architecture: "arm64"}}}, Incompatible: true},
+		{Name: "no platform", Resolver: staticPlatformResolver{}},
+		{Name: "resolution failure", Resolver: staticPlatformResolver{Err: xerrors.Errorf("registry down")}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := checkImageCompatibility(context.Background(), test.Resolver, "foo:latest", nodes)
+
+			var incompatible *IncompatibleImageError
+			if act := xerrors.As(err, &incompatible); act != test.Incompatible {
+				t.Errorf("incompatible = %v, expected %v: %v", act, test.Incompatible, err)
+			}
+			if act := err != nil && incompatible == nil; act != test.Error {
+				t.Errorf("error = %v, expected %v: %v", act, test.Error, err)
+			}
+		})
+	}
+}
@@ -53,6 +53,10 @@ func (m *Manager) GetImageSpec(ctx context.Context, req *regapi.GetImageSpecRequ
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	// The pod keeps the original image references which we report in the workspace status.
+	// registry-facade however must pull the images from where the rewrite rules point it to.
+	spec.BaseRef = m.imageRewriter.Rewrite(spec.BaseRef)
+	spec.IdeRef = m.imageRewriter.Rewrite(spec.IdeRef)
 
 	if _, ok := pod.Labels[fullWorkspaceBackupAnnotation]; ok {
 		owner := pod.Labels[wsk8s.OwnerLabel]
@@ -0,0 +1,313 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// initContainersVolumeName is the name of the volume init containers share with the workspace container
+	initContainersVolumeName = "init-containers"
+	// initContainersMountPath is where init containers and the workspace container find the shared volume
+	initContainersMountPath = "/.workspace-init"
+	// initContainersVolumeSize caps how much init containers can store in the shared volume
+	initContainersVolumeSize = "64Mi"
+)
+
+// InitContainerConfig is an init container users can opt into when starting a workspace,
+// e.g. to install certificates or run compliance checks before the workspace starts.
+// Init containers run as the gitpod user without privileges. They share a volume mounted at /.workspace-init
+// with the workspace container, which mounts it read-only. If an init container fails, the workspace fails.
+type InitContainerConfig struct {
+	// Image is the image reference of the init container
+	Image string `json:"image"`
+	// Command overrides the entrypoint of the image
+	Command []string `json:"command,omitempty"`
+	// Args are the arguments of the command
+	Args []string `json:"args,omitempty"`
+	// Env are additional environment variables. Variables starting with GITPOD_ are reserved.
+	Env map[string]string `json:"env,omitempty"`
+	// Requests are the resources the init container requests
+	Requests ResourceConfiguration `json:"requests,omitempty"`
+	// Limits cap the resources of the init container. CPU and memory limits are required.
+	Limits ResourceConfiguration `json:"limits"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *InitContainerConfig) Validate() error {
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Image, validation.Required),
+		validation.Field(&c.Env, validation.By(func(interface{}) error {
+			for name := range c.Env {
+				if strings.HasPrefix(name, "GITPOD_") {
+					return xerrors.Errorf("%s is reserved", name)
+				}
+			}
+			return nil
+		})),
+		validation.Field(&c.Requests, validResourceConfig),
+		validation.Field(&c.Limits, validResourceConfig),
+	)
+	if err != nil {
+		return err
+	}
+
+	limits, _ := c.Limits.ResourceList()
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if _, ok := limits[name]; !ok {
+			return xerrors.Errorf("limits: %s limit is required", name)
+		}
+	}
+	requests, _ := c.Requests.ResourceList()
+	for name, req := range requests {
+		if lim, ok := limits[name]; ok && req.Cmp(lim) > 0 {
+			return xerrors.Errorf("requests: %s request %s exceeds the limit %s", name, req.String(), lim.String())
+		}
+	}
+	return nil
+}
+
+// validInitContainers validates the init container allowlist. The init containers must also comply with
+// the pod template policy because they end up in the workspace pod just like the template does.
+func validInitContainers(policy *PodTemplatePolicy) validation.Rule {
+	return validation.By(func(o interface{}) error {
+		cfgs, ok := o.(map[string]InitContainerConfig)
+		if !ok {
+			return xerrors.Errorf("can only validate init container configs")
+		}
+
+		for _, name := range sortedInitContainerNames(cfgs) {
+			if errs := k8svalidation.IsDNS1123Label(name); len(errs) > 0 {
+				return xerrors.Errorf("%s: not a valid container name: %s", name, errs[0])
+			}
+			if name == "workspace" {
+				return xerrors.Errorf("%s: name is reserved", name)
+			}
+
+			cfg := cfgs[name]
+			err := cfg.Validate()
+			if err != nil {
+				return xerrors.Errorf("%s: %w", name, err)
+			}
+			c, _ := renderInitContainer(name, &cfg, nil)
+			if violations := policy.Check(&corev1.Pod{Spec: corev1.PodSpec{InitContainers: []corev1.Container{*c}}}); len(violations) > 0 {
+				return xerrors.Errorf("%s: violates the pod template policy: %s", name, violations[0])
+			}
+		}
+		return nil
+	})
+}
+
+func sortedInitContainerNames(cfgs map[string]InitContainerConfig) []string {
+	names := make([]string, 0, len(cfgs))
+	for name := range cfgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateInitContainerRequest makes sure a workspace opts only into init containers from the allowlist, each at most once
+func (m *Manager) validateInitContainerRequest(req *api.StartWorkspaceRequest) error {
+	seen := make(map[string]struct{}, len(req.Spec.InitContainers))
+	for _, name := range req.Spec.InitContainers {
+		if _, ok := m.Config.InitContainers[name]; !ok {
+			return xerrors.Errorf("init container %s is not available", name)
+		}
+		if _, ok := seen[name]; ok {
+			return xerrors.Errorf("init container %s is requested more than once", name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
+// createInitContainers renders the init containers a workspace opted into, in the order they were requested
+func (m *Manager) createInitContainers(startContext *startWorkspaceContext) ([]corev1.Container, error) {
+	req := startContext.Request
+	env := []corev1.EnvVar{
+		{Name: "GITPOD_WORKSPACE_ID", Value: req.Metadata.MetaId},
+		{Name: "GITPOD_INSTANCE_ID", Value: req.Id},
+		{Name: "GITPOD_OWNER_ID", Value: req.Metadata.Owner},
+		{Name: "GITPOD_WORKSPACE_IMAGE", Value: req.Spec.WorkspaceImage},
+	}
+
+	res := make([]corev1.Container, 0, len(req.Spec.InitContainers))
+	for _, name := range req.Spec.InitContainers {
+		cfg, ok := m.Config.InitContainers[name]
+		if !ok {
+			return nil, xerrors.Errorf("unknown init container: %s", name)
+		}
+		c, err := renderInitContainer(name, &cfg, env)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create init container %s: %w", name, err)
+		}
+		res = append(res, *c)
+	}
+	return res, nil
+}
+
+func renderInitContainer(name string, cfg *InitContainerConfig, env []corev1.EnvVar) (*corev1.Container, error) {
+	limits, err := cfg.Limits.ResourceList()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse limits: %w", err)
+	}
+	requests, err := cfg.Requests.ResourceList()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse requests: %w", err)
+	}
+
+	env = append([]corev1.EnvVar{}, env...)
+	names := make([]string, 0, len(cfg.Env))
+	for n := range cfg.Env {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		env = append(env, corev1.EnvVar{Name: n, Value: cfg.Env[n]})
+	}
+
+	gitpodUID := int64(33333)
+	return &corev1.Container{
+		Name:    name,
+		Image:   cfg.Image,
+		Command: cfg.Command,
+		Args:    cfg.Args,
+		Env:     env,
+		Resources: corev1.ResourceRequirements{
+			Limits:   limits,
+			Requests: requests,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: initContainersVolumeName, MountPath: initContainersMountPath},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &boolFalse,
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			Privileged:               &boolFalse,
+			RunAsGroup:               &gitpodUID,
+			RunAsNonRoot:             &boolTrue,
+			RunAsUser:                &gitpodUID,
+		},
+		ImagePullPolicy:          corev1.PullIfNotPresent,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}, nil
+}
+
+// addInitContainers adds the init containers and the volume they share with the workspace container to a pod
+func addInitContainers(pod *corev1.Pod, containers []corev1.Container) {
+	if len(containers) == 0 {
+		return
+	}
+
+	sizeLimit := resource.MustParse(initContainersVolumeSize)
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, containers...)
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: initContainersVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &sizeLimit},
+		},
+	})
+	for i, c := range pod.Spec.Containers {
+		if c.Name != "workspace" {
+			continue
+		}
+		pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      initContainersVolumeName,
+			MountPath: initContainersMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	names := make([]string, 0, len(containers))
+	for _, c := range containers {
+		names = append(names, c.Name)
+	}
+	pod.Annotations[workspaceInitContainersAnnotation] = strings.Join(names, ",")
+}
+
+// getInitContainerStatus reports the state of the init containers a workspace opted into
+func getInitContainerStatus(pod *corev1.Pod) []*api.InitContainerStatus {
+	names := pod.Annotations[workspaceInitContainersAnnotation]
+	if names == "" {
+		return nil
+	}
+
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+
+	var res []*api.InitContainerStatus
+	for _, name := range strings.Split(names, ",") {
+		s := &api.InitContainerStatus{Name: name, State: api.InitContainerState_INIT_CONTAINER_WAITING}
+		cs, ok := statuses[name]
+		switch {
+		case !ok:
+		case cs.State.Running != nil:
+			s.State = api.InitContainerState_INIT_CONTAINER_RUNNING
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+			s.State = api.InitContainerState_INIT_CONTAINER_SUCCEEDED
+		case cs.State.Terminated != nil:
+			s.State = api.InitContainerState_INIT_CONTAINER_FAILED
+			s.ExitCode = cs.State.Terminated.ExitCode
+			s.Message = initContainerFailureMessage(cs.State.Terminated)
+		case cs.State.Waiting != nil && isImagePullFailure(cs.State.Waiting.Reason):
+			s.State = api.InitContainerState_INIT_CONTAINER_FAILED
+			s.Message = fmt.Sprintf("cannot pull image: %s", cs.State.Waiting.Message)
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// extractInitContainerFailure returns the reason an init container failed for, or an empty string if none failed
+func extractInitContainerFailure(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.State.Waiting != nil && isImagePullFailure(cs.State.Waiting.Reason) {
+			return fmt.Sprintf("init container %s: cannot pull image: %s", cs.Name, cs.State.Waiting.Message)
+		}
+
+		// the pod's restart policy is Never, hence a failed init container is not restarted and fails the pod
+		terminated := cs.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+		return fmt.Sprintf("init container %s failed: %s", cs.Name, initContainerFailureMessage(terminated))
+	}
+	return ""
+}
+
+// getRunningInitContainer returns the name of the init container which currently runs, if any
+func getRunningInitContainer(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.State.Running != nil {
+			return cs.Name
+		}
+	}
+	return ""
+}
+
+func initContainerFailureMessage(terminated *corev1.ContainerStateTerminated) string {
+	if terminated.Message != "" {
+		return extractFailureFromLogs([]byte(terminated.Message))
+	}
+	return fmt.Sprintf("exit code %d", terminated.ExitCode)
+}
+
+func isImagePullFailure(reason string) bool {
+	return reason == "ImagePullBackOff" || reason == "ErrImagePull"
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+var testInitContainers = map[string]InitContainerConfig{
+	"certs": {
+		Image:   "eu.gcr.io/acme/certs:1.0",
+		Command: []string{"/install-certs"},
+		Env:     map[string]string{"TARGET": "/.workspace-init/certs"},
+		Limits:  ResourceConfiguration{CPU: "100m", Memory: "64Mi"},
+	},
+	"scanner": {
+		Image:    "eu.gcr.io/acme/scanner:2.1",
+		Requests: ResourceConfiguration{CPU: "200m", Memory: "128Mi"},
+		Limits:   ResourceConfiguration{CPU: "500m", Memory: "256Mi"},
+	},
+}
+
+func TestInitContainersValidate(t *testing.T) {
+	limits := ResourceConfiguration{CPU: "100m", Memory: "64Mi"}
+	tests := []struct {
+		Name   string
+		Cfg    map[string]InitContainerConfig
+		Policy *PodTemplatePolicy
+		Valid  bool
+	}{
+		{Name: "none", Valid: true},
+		{Name: "valid", Cfg: testInitContainers, Valid: true},
+		{Name: "invalid name", Cfg: map[string]InitContainerConfig{"Certs_1": {Image: "certs", Limits: limits}}},
+		{Name: "reserved name", Cfg: map[string]InitContainerConfig{"workspace": {Image: "certs", Limits: limits}}},
+		{Name: "no image", Cfg: map[string]InitContainerConfig{"certs": {Limits: limits}}},
+		{Name: "no limits", Cfg: map[string]InitContainerConfig{"certs": {Image: "certs"}}},
+		{Name: "no memory limit", Cfg: map[string]InitContainerConfig{"certs": {Image: "certs", Limits: ResourceConfiguration{CPU: "100m"}}}},
+		{
+			Name: "request exceeds limit",
+			Cfg:  map[string]InitContainerConfig{"certs": {Image: "certs", Requests: ResourceConfiguration{CPU: "1"}, Limits: limits}},
+		},
+		{Name: "reserved env var", Cfg: map[string]InitContainerConfig{"certs": {Image: "certs", Env: map[string]string{"GITPOD_HOST": "foo"}, Limits: limits}}},
+		{
+			Name:   "exceeds policy",
+			Cfg:    testInitContainers,
+			Policy: &PodTemplatePolicy{MaxResources: &ResourceConfiguration{Memory: "128Mi"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := validation.Validate(test.Cfg, validInitContainers(test.Policy))
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestValidateInitContainerRequest(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Requested []string
+		Valid     bool
+	}{
+		{Name: "none", Valid: true},
+		{Name: "allowlisted", Requested: []string{"scanner", "certs"}, Valid: true},
+		{Name: "unknown", Requested: []string{"certs", "miner"}},
+		{Name: "duplicate", Requested: []string{"certs", "certs"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			m := &Manager{Config: Configuration{InitContainers: testInitContainers}}
+			err := m.validateInitContainerRequest(&api.StartWorkspaceRequest{Spec: &api.StartWorkspaceSpec{InitContainers: test.Requested}})
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCreateInitContainers(t *testing.T) {
+	m := &Manager{Config: Configuration{InitContainers: testInitContainers}}
+	startContext := &startWorkspaceContext{
+		Request: &api.StartWorkspaceRequest{
+			Id:       "a2b1c3d4",
+			Metadata: &api.WorkspaceMetadata{Owner: "owner", MetaId: "amaranth-smelt-9ba20cc1"},
+			Spec: &api.StartWorkspaceSpec{
+				WorkspaceImage: "eu.gcr.io/gitpod/workspace-full",
+				InitContainers: []string{"scanner", "certs"},
+			},
+		},
+	}
+
+	containers, err := m.createInitContainers(startContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "workspace"}}},
+	}
+	addInitContainers(pod, containers)
+
+	var names []string
+	for _, c := range pod.Spec.InitContainers {
+		names = append(names, c.Name)
+		if sc := c.SecurityContext; sc == nil || sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot || *sc.AllowPrivilegeEscalation {
+			t.Errorf("init container %s is not restricted", c.Name)
+		}
+		if c.Resources.Limits.Cpu().IsZero() || c.Resources.Limits.Memory().IsZero() {
+			t.Errorf("init container %s has no resource caps", c.Name)
+		}
+	}
+	if diff := cmp.Diff([]string{"scanner", "certs"}, names); diff != "" {
+		t.Errorf("unexpected init containers (-want +got):\n%s", diff)
+	}
+	expectedEnv := []corev1.EnvVar{
+		{Name: "GITPOD_WORKSPACE_ID", Value: "amaranth-smelt-9ba20cc1"},
+		{Name: "GITPOD_INSTANCE_ID", Value: "a2b1c3d4"},
+		{Name: "GITPOD_OWNER_ID", Value: "owner"},
+		{Name: "GITPOD_WORKSPACE_IMAGE", Value: "eu.gcr.io/gitpod/workspace-full"},
+		{Name: "TARGET", Value: "/.workspace-init/certs"},
+	}
+	if diff := cmp.Diff(expectedEnv, pod.Spec.InitContainers[1].Env); diff != "" {
+		t.Errorf("unexpected env (-want +got):\n%s", diff)
+	}
+	expectedMount := []corev1.VolumeMount{{Name: initContainersVolumeName, MountPath: initContainersMountPath, ReadOnly: true}}
+	if diff := cmp.Diff(expectedMount, pod.Spec.Containers[0].VolumeMounts); diff != "" {
+		t.Errorf("unexpected workspace volume mounts (-want +got):\n%s", diff)
+	}
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].EmptyDir == nil {
+		t.Errorf("expected a shared emptyDir volume, got %v", pod.Spec.Volumes)
+	}
+	if act := pod.Annotations[workspaceInitContainersAnnotation]; act != "scanner,certs" {
+		t.Errorf("unexpected init containers annotation %q", act)
+	}
+}
+
+func TestInitContainerStatus(t *testing.T) {
+	terminated := func(exitCode int32, msg string) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: msg}}
+	}
+	tests := []struct {
+		Name     string
+		Statuses []corev1.ContainerStatus
+		Expected []*api.InitContainerStatus
+		Failure  string
+	}{
+		{
+			Name: "not started",
+			Expected: []*api.InitContainerStatus{
+				{Name: "scanner", State: api.InitContainerState_INIT_CONTAINER_WAITING},
+				{Name: "certs", State: api.InitContainerState_INIT_CONTAINER_WAITING},
+			},
+		},
+		{
+			Name: "running",
+			Statuses: []corev1.ContainerStatus{
+				{Name: "scanner", State: terminated(0, "")},
+				{Name: "certs", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+			Expected: []*api.InitContainerStatus{
+				{Name: "scanner", State: api.InitContainerState_INIT_CONTAINER_SUCCEEDED},
+				{Name: "certs", State: api.InitContainerState_INIT_CONTAINER_RUNNING},
+			},
+		},
+		{
+			Name: "failed",
+			Statuses: []corev1.ContainerStatus{
+				{Name: "scanner", State: terminated(3, "image contains CVE-2021-44228")},
+				{Name: "certs", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+			},
+			Expected: []*api.InitContainerStatus{
+				{Name: "scanner", State: api.InitContainerState_INIT_CONTAINER_FAILED, ExitCode: 3, Message: "image contains CVE-2021-44228"},
+				{Name: "certs", State: api.InitContainerState_INIT_CONTAINER_WAITING},
+			},
+			Failure: "init container scanner failed: image contains CVE-2021-44228",
+		},
+		{
+			Name: "failed without message",
+			Statuses: []corev1.ContainerStatus{
+				{Name: "scanner", State: terminated(1, "")},
+			},
+			Expected: []*api.InitContainerStatus{
+				{Name: "scanner", State: api.InitContainerState_INIT_CONTAINER_FAILED, ExitCode: 1, Message: "exit code 1"},
+				{Name: "certs", State: api.InitContainerState_INIT_CONTAINER_WAITING},
+			},
+			Failure: "init container scanner failed: exit code 1",
+		},
+		{
+			Name: "image pull failure",
+			Statuses: []corev1.ContainerStatus{
+				{Name: "scanner", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull", Message: "not found"}}},
+			},
+			Expected: []*api.InitContainerStatus{
+				{Name: "scanner", State: api.InitContainerState_INIT_CONTAINER_FAILED, Message: "cannot pull image: not found"},
+				{Name: "certs", State: api.InitContainerState_INIT_CONTAINER_WAITING},
+			},
+			Failure: "init container scanner: cannot pull image: not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{workspaceInitContainersAnnotation: "scanner,certs"}},
+				Status:     corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: test.Statuses},
+			}
+			if diff := cmp.Diff(test.Expected, getInitContainerStatus(pod), cmpopts.IgnoreUnexported(api.InitContainerStatus{})); diff != "" {
+				t.Errorf("unexpected status (-want +got):\n%s", diff)
+			}
+			if failure, _ := extractFailure(workspaceObjects{Pod: pod}); failure != test.Failure {
+				t.Errorf("unexpected failure: want %q, got %q", test.Failure, failure)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const defaultClusterDomain = "cluster.local"
+
+// InstanceDNSConfig gives every regular workspace instance a stable cluster-internal DNS name.
+// Pod IPs change whenever a workspace restarts, hence other workspaces, e.g. the members of a workspace group
+// or test tooling, cannot reliably reference them.
+type InstanceDNSConfig struct {
+	// ClusterDomain is the DNS domain of the cluster. Defaults to cluster.local.
+	ClusterDomain string `json:"clusterDomain,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *InstanceDNSConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.ClusterDomain, is.DNSName),
+	)
+}
+
+func (c *InstanceDNSConfig) clusterDomain() string {
+	if c.ClusterDomain == "" {
+		return defaultClusterDomain
+	}
+	return c.ClusterDomain
+}
+
+// getInstanceServiceName returns the name of the headless service which provides the DNS name of a workspace instance
+func getInstanceServiceName(instanceID string) string {
+	return fmt.Sprintf("ws-%s", strings.TrimSpace(strings.ToLower(instanceID)))
+}
+
+// getGroupNetworkPolicyName returns the name of the network policy which admits the other members of a workspace group
+func getGroupNetworkPolicyName(instanceID string) string {
+	return fmt.Sprintf("ws-%s-group", strings.TrimSpace(strings.ToLower(instanceID)))
+}
+
+// instanceHostname returns the stable DNS name of a workspace instance, or an empty string if instance DNS is disabled
+func (m *Manager) instanceHostname(instanceID string) string {
+	if m.Config.InstanceDNS == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", getInstanceServiceName(instanceID), m.Config.Namespace, m.Config.InstanceDNS.clusterDomain())
+}
+
+// newInstanceService produces the headless service which resolves to the pod of a workspace instance
+func (m *Manager) newInstanceService(startContext *startWorkspaceContext) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getInstanceServiceName(startContext.Request.Id),
+			Namespace: m.Config.Namespace,
+			Labels:    startContext.Labels,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  startContext.Labels,
+			// the name must resolve while the workspace is still starting, e.g. so that group members which depend
+			// on this one can connect to it as soon as it is running
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// newGroupNetworkPolicy produces the network policy which admits traffic from the other members of the workspace
+// group to a workspace instance. Without it the default workspace network policy only admits our own components.
+func (m *Manager) newGroupNetworkPolicy(startContext *startWorkspaceContext) *networkingv1.NetworkPolicy {
+	groupID := startContext.Request.Metadata.GroupId
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getGroupNetworkPolicyName(startContext.Request.Id),
+			Namespace: m.Config.Namespace,
+			Labels:    startContext.Labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					markerLabel:            "true",
+					wsk8s.WorkspaceIDLabel: startContext.Request.Id,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									markerLabel:         "true",
+									workspaceGroupLabel: groupID,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// deleteDanglingGroupNetworkPolicies removes group network policies for which there is no corresponding workspace pod anymore.
+// Unlike services, nothing else tells us that a network policy outlived its workspace.
+func (m *Monitor) deleteDanglingGroupNetworkPolicies(ctx context.Context) error {
+	var policies networkingv1.NetworkPolicyList
+	err := m.manager.Clientset.List(ctx, &policies, workspaceObjectListOptions(m.manager.Config.Namespace))
+	if err != nil {
+		return xerrors.Errorf("deleteDanglingGroupNetworkPolicies: %w", err)
+	}
+
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		workspaceID, ok := policy.Labels[wsk8s.WorkspaceIDLabel]
+		if !ok {
+			m.OnError(xerrors.Errorf("network policy %s does not have %s label", policy.Name, wsk8s.WorkspaceIDLabel))
+			continue
+		}
+		_, err := m.manager.findWorkspacePod(ctx, workspaceID)
+		if !isKubernetesObjNotFoundError(err) {
+			continue
+		}
+
+		if m.manager.Config.DryRun {
+			log.WithFields(log.OWI("", "", workspaceID)).WithField("name", policy.Name).Info("should have deleted dangling network policy but this is a dry run")
+			continue
+		}
+
+		err = m.manager.Clientset.Delete(ctx, policy)
+		if err != nil && !isKubernetesObjNotFoundError(err) {
+			m.OnError(xerrors.Errorf("deleteDanglingGroupNetworkPolicies: %w", err))
+			continue
+		}
+		log.WithFields(log.OWI("", "", workspaceID)).WithField("name", policy.Name).Info("deleted dangling network policy")
+	}
+
+	return nil
+}
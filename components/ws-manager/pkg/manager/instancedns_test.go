@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestInstanceHostname(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Config   *InstanceDNSConfig
+		Expected string
+	}{
+		{Name: "disabled"},
+		{Name: "default cluster domain", Config: &InstanceDNSConfig{}, Expected: "ws-a2b1c3d4.default.svc.cluster.local"},
+		{Name: "custom cluster domain", Config: &InstanceDNSConfig{ClusterDomain: "gitpod.internal"}, Expected: "ws-a2b1c3d4.default.svc.gitpod.internal"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			m := &Manager{Config: Configuration{Namespace: "default", InstanceDNS: test.Config}}
+			if act := m.instanceHostname("A2B1C3D4"); act != test.Expected {
+				t.Errorf("unexpected hostname: want %q, got %q", test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestInstanceDNSConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Cfg   *InstanceDNSConfig
+		Valid bool
+	}{
+		{Name: "nil", Valid: true},
+		{Name: "default", Cfg: &InstanceDNSConfig{}, Valid: true},
+		{Name: "cluster domain", Cfg: &InstanceDNSConfig{ClusterDomain: "cluster.local"}, Valid: true},
+		{Name: "invalid cluster domain", Cfg: &InstanceDNSConfig{ClusterDomain: "cluster local"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestInstanceDNSObjects(t *testing.T) {
+	m := &Manager{Config: Configuration{Namespace: "default", InstanceDNS: &InstanceDNSConfig{}}}
+	startContext := &startWorkspaceContext{
+		Request: &api.StartWorkspaceRequest{
+			Id:       "a2b1c3d4",
+			Metadata: &api.WorkspaceMetadata{GroupId: "grp"},
+		},
+		Labels: map[string]string{
+			markerLabel:         "true",
+			"workspaceID":       "a2b1c3d4",
+			workspaceGroupLabel: "grp",
+		},
+	}
+
+	svc := m.newInstanceService(startContext)
+	if svc.Name != "ws-a2b1c3d4" {
+		t.Errorf("unexpected service name %q", svc.Name)
+	}
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		t.Errorf("instance service is not headless: %q", svc.Spec.ClusterIP)
+	}
+	if diff := cmp.Diff(startContext.Labels, svc.Spec.Selector); diff != "" {
+		t.Errorf("unexpected selector (-want +got):\n%s", diff)
+	}
+
+	policy := m.newGroupNetworkPolicy(startContext)
+	if policy.Name != "ws-a2b1c3d4-group" {
+		t.Errorf("unexpected network policy name %q", policy.Name)
+	}
+	if diff := cmp.Diff(map[string]string{markerLabel: "true", "workspaceID": "a2b1c3d4"}, policy.Spec.PodSelector.MatchLabels); diff != "" {
+		t.Errorf("network policy selects unexpected pods (-want +got):\n%s", diff)
+	}
+	if len(policy.Spec.Ingress) != 1 || len(policy.Spec.Ingress[0].From) != 1 {
+		t.Fatalf("unexpected ingress rules: %v", policy.Spec.Ingress)
+	}
+	from := policy.Spec.Ingress[0].From[0].PodSelector.MatchLabels
+	if diff := cmp.Diff(map[string]string{markerLabel: "true", workspaceGroupLabel: "grp"}, from); diff != "" {
+		t.Errorf("network policy admits unexpected pods (-want +got):\n%s", diff)
+	}
+}
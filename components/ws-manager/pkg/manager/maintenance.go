@@ -0,0 +1,75 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// SetMaintenance announces or ends a cluster maintenance and notifies all subscribers.
+// The maintenance status lives in memory only, i.e. it has to be announced again if ws-manager restarts.
+func (m *Manager) SetMaintenance(ctx context.Context, req *api.SetMaintenanceRequest) (res *api.SetMaintenanceResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "SetMaintenance")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "SetMaintenance", Parameters: map[string]string{"enabled": fmt.Sprint(req.Status.GetEnabled()), "message": req.Status.GetMessage()}}, err)
+	}()
+
+	sts := req.Status
+	if sts == nil {
+		sts = &api.MaintenanceStatus{}
+	}
+	if sts.StartsAt != nil && sts.EndsAt != nil {
+		start, err := ptypes.Timestamp(sts.StartsAt)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid start: %v", err)
+		}
+		end, err := ptypes.Timestamp(sts.EndsAt)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid end: %v", err)
+		}
+		if end.Before(start) {
+			return nil, status.Errorf(codes.InvalidArgument, "maintenance cannot end before it starts")
+		}
+	}
+
+	m.maintenanceLock.Lock()
+	ended := m.maintenance != nil && m.maintenance.Enabled && !sts.Enabled
+	m.maintenance = sts
+	m.maintenanceLock.Unlock()
+	log.WithField("maintenance", sts).Info("maintenance status changed")
+
+	if ended {
+		// everyone who waited for the maintenance to end is about to start a workspace
+		m.slowStart.Restart()
+	}
+
+	m.publishToSubscribers(ctx, &api.SubscribeResponse{
+		Payload: &api.SubscribeResponse_Maintenance{Maintenance: sts},
+	})
+
+	return &api.SetMaintenanceResponse{}, nil
+}
+
+// currentMaintenance returns the maintenance status if a maintenance is announced
+func (m *Manager) currentMaintenance() *api.MaintenanceStatus {
+	m.maintenanceLock.Lock()
+	defer m.maintenanceLock.Unlock()
+
+	if m.maintenance == nil || !m.maintenance.Enabled {
+		return nil
+	}
+	return m.maintenance
+}
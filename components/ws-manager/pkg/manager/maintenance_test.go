@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+type chanSubscriber chan *api.SubscribeResponse
+
+func (c chanSubscriber) Send(resp *api.SubscribeResponse) error {
+	c <- resp
+	return nil
+}
+
+func TestSetMaintenance(t *testing.T) {
+	m := &Manager{subscribers: make(map[string]chan *api.SubscribeResponse)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Now()
+	start, _ := ptypes.TimestampProto(now)
+	end, _ := ptypes.TimestampProto(now.Add(-1 * time.Hour))
+	_, err := m.SetMaintenance(ctx, &api.SetMaintenanceRequest{Status: &api.MaintenanceStatus{Enabled: true, StartsAt: start, EndsAt: end}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for maintenance ending before it starts, got %v", err)
+	}
+
+	_, err = m.SetMaintenance(ctx, &api.SetMaintenanceRequest{Status: &api.MaintenanceStatus{Enabled: true, Message: "upgrade", StartsAt: start}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// new subscribers learn about the announced maintenance first
+	sub := make(chanSubscriber, 10)
+	go m.subscribe(ctx, sub)
+	select {
+	case resp := <-sub:
+		if resp.GetMaintenance().GetMessage() != "upgrade" {
+			t.Errorf("unexpected first message: %v", resp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber was not told about the maintenance")
+	}
+
+	_, err = m.SetMaintenance(ctx, &api.SetMaintenanceRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case resp := <-sub:
+		if resp.GetMaintenance() == nil || resp.GetMaintenance().Enabled {
+			t.Errorf("expected end of maintenance, got %v", resp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber was not told about the end of the maintenance")
+	}
+	if mt := m.currentMaintenance(); mt != nil {
+		t.Errorf("maintenance is still announced: %v", mt)
+	}
+}
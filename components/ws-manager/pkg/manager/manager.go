@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -36,9 +38,11 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/gitpod-io/gitpod/common-go/imageref"
 	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
 	"github.com/gitpod-io/gitpod/content-service/pkg/layer"
 	regapi "github.com/gitpod-io/gitpod/registry-facade/api"
 	wsdaemon "github.com/gitpod-io/gitpod/ws-daemon/api"
@@ -56,14 +60,52 @@ type Manager struct {
 
 	activity     map[string]time.Time
 	activityLock sync.Mutex
+	// assumedActivity is when we marked all workspaces active during startup, i.e. the activity we did not observe
+	assumedActivity time.Time
+
+	proxyActivity     map[string]map[string]proxyActivity
+	proxyActivityLock sync.Mutex
 
 	ingressPortAllocator IngressPortAllocator
+	imageRewriter        *imageref.Rewriter
+	imagePlatforms       imagePlatformResolver
+	ideLabels            imageLabelResolver
+	podTemplates         *podTemplateStore
 
 	wsdaemonPool *grpcpool.Pool
 
 	subscribers    map[string]chan *api.SubscribeResponse
 	subscriberLock sync.RWMutex
 
+	generations *statusGenerations
+
+	maintenance     *api.MaintenanceStatus
+	maintenanceLock sync.Mutex
+
+	slowStart *slowStart
+
+	scaleHints *scaleHints
+
+	accounting *accountingJournal
+
+	auditLog *auditLog
+
+	archiver *archiver
+
+	softDeleter *softDeleter
+
+	templates *templateStore
+
+	bulkOperations *bulkOperations
+
+	// activations are the IDs of the paused workspaces whose supervisor we are telling about their activation
+	activations sync.Map
+
+	// monitor is the monitor created for this manager, if any
+	monitor *Monitor
+
+	chaos *chaos
+
 	metrics *metrics
 }
 
@@ -77,6 +119,8 @@ type startWorkspaceContext struct {
 	WorkspaceURL   string                     `json:"workspaceURL"`
 	TraceID        string                     `json:"traceID"`
 	Headless       bool                       `json:"headless"`
+	// PreviewDNSHostname is the hostname of the workspace on the custom preview domain, if there is one
+	PreviewDNSHostname string `json:"previewDnsHostname,omitempty"`
 }
 
 const (
@@ -92,6 +136,8 @@ const (
 	markerLabel = "gpwsman"
 	// headlessLabel marks a workspace as headless
 	headlessLabel = "headless"
+	// workspaceGroupLabel is the ID of the workspace group a workspace belongs to
+	workspaceGroupLabel = "gitpod.io/workspaceGroup"
 
 	// theiaVersionLabelFmt is the format to produce the label a node has if Theia is available on it in a particular version
 	theiaVersionLabelFmt = "gitpod.io/theia.%s"
@@ -117,6 +163,74 @@ func New(config Configuration, client client.Client, rawClient kubernetes.Interf
 		return nil, xerrors.Errorf("error initializing IngressPortAllocator: %w", err)
 	}
 
+	imageRewriter, err := imageref.NewRewriter(config.ImageRewrite)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid image rewrite rules: %w", err)
+	}
+
+	var imagePlatforms imagePlatformResolver
+	if config.ImageCompatibility != nil {
+		imagePlatforms, err = newRegistryPlatformResolver(config.ImageCompatibility.RegistryAuthFile)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create image platform resolver: %w", err)
+		}
+	}
+
+	var ideLabels imageLabelResolver
+	if config.IDEPinning != nil {
+		ideLabels, err = newRegistryPlatformResolver(config.IDEPinning.RegistryAuthFile)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create IDE image label resolver: %w", err)
+		}
+	}
+
+	var accounting *accountingJournal
+	if config.Accounting != nil {
+		accounting, err = openAccountingJournal(config.Accounting.JournalPath)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot open accounting journal: %w", err)
+		}
+	}
+
+	var audit *auditLog
+	if config.AuditLog != nil {
+		audit, err = openAuditLog(*config.AuditLog)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot open audit log: %w", err)
+		}
+	}
+
+	var archiver *archiver
+	if config.Archival != nil {
+		if cp == nil {
+			return nil, xerrors.Errorf("archival requires content storage")
+		}
+		archiver, err = newArchiver(*config.Archival, cp.Storage)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot restore archival state: %w", err)
+		}
+		archiver.Start()
+	}
+
+	var softDeleter *softDeleter
+	if config.SoftDelete != nil {
+		if cp == nil {
+			return nil, xerrors.Errorf("soft-deletion requires content storage")
+		}
+		softDeleter, err = newSoftDeleter(*config.SoftDelete, cp.Storage)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot restore soft-delete state: %w", err)
+		}
+	}
+
+	var templates *templateStore
+	if config.WorkspaceTemplates != nil {
+		templates, err = newTemplateStore(*config.WorkspaceTemplates)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot restore workspace templates: %w", err)
+		}
+	}
+
 	wsdaemonConnfactory, _ := newWssyncConnectionFactory(config)
 	m := &Manager{
 		Config:               config,
@@ -124,12 +238,31 @@ func New(config Configuration, client client.Client, rawClient kubernetes.Interf
 		RawClient:            rawClient,
 		Content:              cp,
 		activity:             make(map[string]time.Time),
+		proxyActivity:        make(map[string]map[string]proxyActivity),
 		subscribers:          make(map[string]chan *api.SubscribeResponse),
+		generations:          newStatusGenerations(),
 		wsdaemonPool:         grpcpool.New(wsdaemonConnfactory),
 		ingressPortAllocator: ingressPortAllocator,
+		imageRewriter:        imageRewriter,
+		imagePlatforms:       imagePlatforms,
+		ideLabels:            ideLabels,
+		podTemplates:         newPodTemplateStore(),
+		slowStart:            newSlowStart(config.SlowStart),
+		scaleHints:           newScaleHints(config.ScaleHints),
+		accounting:           accounting,
+		auditLog:             audit,
+		archiver:             archiver,
+		softDeleter:          softDeleter,
+		templates:            templates,
+		bulkOperations:       newBulkOperations(),
+		chaos:                newChaos(config.Chaos),
 	}
 	m.metrics = newMetrics(m)
 	m.OnChange = m.onChange
+	if softDeleter != nil {
+		softDeleter.OnPurged = func() { m.metrics.OnWorkspaceSoftDeletion("purged") }
+		softDeleter.Start()
+	}
 	return m, nil
 }
 
@@ -144,10 +277,43 @@ func Register(grpcServer *grpc.Server, manager *Manager) {
 func (m *Manager) Close() {
 	m.wsdaemonPool.Close()
 	m.ingressPortAllocator.Stop()
+	if m.accounting != nil {
+		m.accounting.Close()
+	}
+	if m.auditLog != nil {
+		m.auditLog.Close()
+	}
+	if m.archiver != nil {
+		m.archiver.Close()
+	}
+	if m.softDeleter != nil {
+		m.softDeleter.Close()
+	}
 }
 
 // StartWorkspace creates a new running workspace within the manager's cluster
 func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceRequest) (res *api.StartWorkspaceResponse, err error) {
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{
+			Action:   "StartWorkspace",
+			Id:       req.Id,
+			Metadata: req.Metadata,
+			Parameters: map[string]string{
+				"type":           req.Type.String(),
+				"workspaceImage": req.Spec.GetWorkspaceImage(),
+				"ideImage":       req.Spec.GetIdeImage(),
+			},
+		}, err)
+	}()
+
+	if req.Metadata != nil && req.Metadata.GroupId != "" {
+		return nil, errStartWorkspaceInvalid(xerrors.Errorf("cannot start group member %s on its own - use StartWorkspaceGroup", req.Id))
+	}
+	return m.startWorkspace(ctx, req)
+}
+
+// startWorkspace creates the pod, PLIS and services of a workspace. Its errors carry StartWorkspaceErrorDetails.
+func (m *Manager) startWorkspace(ctx context.Context, req *api.StartWorkspaceRequest) (res *api.StartWorkspaceResponse, err error) {
 	owi := log.OWI(req.Metadata.Owner, req.Metadata.MetaId, req.Id)
 	clog := log.WithFields(owi)
 	span, ctx := tracing.FromContext(ctx, "StartWorkspace")
@@ -155,20 +321,72 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 	tracing.ApplyOWI(span, owi)
 	defer tracing.FinishSpan(span, &err)
 
+	var podCreated bool
+	defer func() {
+		err = classifyStartWorkspaceError(err, podCreated)
+	}()
+
+	// Starting a workspace which was created paused activates it
+	if !req.Paused {
+		res, activated, err := m.activatePausedWorkspace(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if activated {
+			return res, nil
+		}
+	}
+
 	// Make sure the objects we're about to create do not exist already
 	exists, err := m.workspaceExists(ctx, req.Id)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot start workspace: %w", err)
 	}
 	if exists {
-		return nil, xerrors.Errorf("workspace %s exists already", req.Id)
+		return nil, status.Errorf(codes.AlreadyExists, "workspace %s exists already", req.Id)
 	}
 	tracing.LogEvent(span, "workspace does not exist")
+	err = m.applyWorkspaceTemplate(req)
+	if err != nil {
+		return nil, err
+	}
 	err = validateStartWorkspaceRequest(req)
 	if err != nil {
-		return nil, xerrors.Errorf("cannot start workspace: %w", err)
+		return nil, errStartWorkspaceInvalid(err)
+	}
+	err = m.validateWorkspaceID(req)
+	if err != nil {
+		return nil, errStartWorkspaceInvalid(err)
+	}
+	err = m.validateInitContainerRequest(req)
+	if err != nil {
+		return nil, errStartWorkspaceInvalid(err)
+	}
+	err = m.validatePausedRequest(req)
+	if err != nil {
+		return nil, errStartWorkspaceInvalid(err)
 	}
 	tracing.LogEvent(span, "validated workspace start request")
+	err = m.admitWorkspaceStart(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	err = m.admitArchivedWorkspaceStart(req)
+	if err != nil {
+		return nil, err
+	}
+	err = m.admitDeletedWorkspaceStart(req)
+	if err != nil {
+		return nil, err
+	}
+	err = m.checkWorkspaceImageCompatibility(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	err = m.checkIDEPin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	// create the objects required to start the workspace pod/service
 	startContext, err := m.newStartWorkspaceContext(ctx, req)
 	if err != nil {
@@ -191,7 +409,9 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 		clog.WithError(err).WithField("req", req).WithField("pod", safePod).Error("was unable to start workspace")
 		return nil, err
 	}
+	podCreated = true
 	tracing.LogEvent(span, "pod created")
+	m.scaleHints.OnStart(pod)
 
 	// the pod lifecycle independent state is a config map which stores information about a workspace
 	// prior to/beyond a workspace pod's lifetime. These config maps can exist without a pod only for
@@ -225,7 +445,7 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 	theiaServiceName := getTheiaServiceName(servicePrefix)
 	alloc, err := m.ingressPortAllocator.UpdateAllocatedPorts(req.Metadata.MetaId, theiaServiceName, []int{int(startContext.IDEPort)})
 	if err != nil {
-		return nil, err
+		return nil, newStartWorkspaceError(codes.ResourceExhausted, api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY, 0, "The cluster cannot take on more workspaces at the moment.", err)
 	}
 	serializedPorts, err := alloc.Marshal()
 	if err != nil {
@@ -264,6 +484,24 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 	}
 	tracing.LogEvent(span, "theia service created")
 
+	if m.Config.InstanceDNS != nil {
+		err = m.Clientset.Create(ctx, m.newInstanceService(startContext))
+		if err != nil {
+			clog.WithError(err).WithField("req", req).Error("was unable to start workspace")
+			return nil, xerrors.Errorf("cannot create workspace's instance service: %w", err)
+		}
+		tracing.LogEvent(span, "instance service created")
+
+		if req.Metadata.GroupId != "" {
+			err = m.Clientset.Create(ctx, m.newGroupNetworkPolicy(startContext))
+			if err != nil {
+				clog.WithError(err).WithField("req", req).Error("was unable to start workspace")
+				return nil, xerrors.Errorf("cannot create workspace's group network policy: %w", err)
+			}
+			tracing.LogEvent(span, "group network policy created")
+		}
+	}
+
 	// if we have ports configured already, create the ports service
 	if len(req.Spec.Ports) > 0 {
 		portService, err := m.createPortsService(req.Id, servicePrefix, req.Metadata.MetaId, req.Spec.Ports)
@@ -281,6 +519,9 @@ func (m *Manager) StartWorkspace(ctx context.Context, req *api.StartWorkspaceReq
 	}
 
 	m.metrics.OnWorkspaceStarted(req.Type)
+	if req.Paused {
+		m.metrics.OnPausedWorkspace("created")
+	}
 
 	return okResponse, nil
 }
@@ -294,6 +535,8 @@ func validateStartWorkspaceRequest(req *api.StartWorkspaceRequest) error {
 		validation.Field(&req.Spec.Ports, validation.By(areValidPorts)),
 		validation.Field(&req.Spec.Initializer, validation.Required),
 		validation.Field(&req.Spec.FeatureFlags, validation.By(areValidFeatureFlags)),
+		validation.Field(&req.Spec.Experiments, validation.By(areValidExperiments)),
+		validation.Field(&req.Spec.SshKeys, validation.By(areValidSSHKeys)),
 	)
 	if err != nil {
 		return xerrors.Errorf("invalid request: %w", err)
@@ -363,11 +606,66 @@ func areValidFeatureFlags(value interface{}) error {
 	return nil
 }
 
+func areValidSSHKeys(value interface{}) error {
+	s, ok := value.([]*api.WorkspaceSSHKey)
+	if !ok {
+		return xerrors.Errorf("value is not an SSH key list")
+	}
+
+	idx := make(map[string]struct{}, len(s))
+	for _, k := range s {
+		if k == nil || k.Name == "" {
+			return xerrors.Errorf("SSH keys need a name")
+		}
+		if _, exists := idx[k.Name]; exists {
+			return xerrors.Errorf("SSH key %s is not unique", k.Name)
+		}
+		idx[k.Name] = struct{}{}
+
+		// supervisor downloads the key when the workspace starts - keys without a one-time secret fail the workspace
+		if k.KeyOts == "" {
+			return xerrors.Errorf("SSH key %s has no key OTS", k.Name)
+		}
+	}
+
+	return nil
+}
+
+// experimentNameRegexp matches experiment and variant names. They end up in metric labels and log fields, hence we keep them simple.
+var experimentNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]{0,61}[a-z0-9])?$`)
+
+func areValidExperiments(value interface{}) error {
+	s, ok := value.(map[string]string)
+	if !ok {
+		return xerrors.Errorf("value not an experiment map")
+	}
+
+	for experiment, variant := range s {
+		if !experimentNameRegexp.MatchString(experiment) {
+			return xerrors.Errorf("invalid experiment name %q", experiment)
+		}
+		if !experimentNameRegexp.MatchString(variant) {
+			return xerrors.Errorf("experiment %s: invalid variant name %q", experiment, variant)
+		}
+	}
+
+	return nil
+}
+
 // StopWorkspace stops a running workspace
 func (m *Manager) StopWorkspace(ctx context.Context, req *api.StopWorkspaceRequest) (res *api.StopWorkspaceResponse, err error) {
 	span, ctx := tracing.FromContext(ctx, "StopWorkspace")
 	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
 	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "StopWorkspace", Id: req.Id, Parameters: map[string]string{"policy": req.Policy.String()}}, err)
+	}()
+
+	generationDone, err := m.checkExpectedGeneration(req.Id, req.ExpectedGeneration)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { generationDone(err) }()
 
 	gracePeriod := stopWorkspaceNormallyGracePeriod
 	if req.Policy == api.StopWorkspacePolicy_IMMEDIATELY {
@@ -464,6 +762,32 @@ func (m *Manager) stopWorkspace(ctx context.Context, workspaceID string, gracePe
 	)
 	tracing.LogEvent(span, "ports service deleted")
 
+	var instanceServiceErr, groupNetworkPolicyErr error
+	if m.Config.InstanceDNS != nil {
+		instanceServiceErr = m.Clientset.Delete(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      getInstanceServiceName(workspaceID),
+				Namespace: m.Config.Namespace,
+			},
+		},
+			&client.DeleteOptions{
+				GracePeriodSeconds: &gracePeriodSeconds,
+				PropagationPolicy:  &propagationPolicy,
+			},
+		)
+		tracing.LogEvent(span, "instance service deleted")
+
+		if _, ok := pod.Labels[workspaceGroupLabel]; ok {
+			groupNetworkPolicyErr = m.Clientset.Delete(ctx, &networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      getGroupNetworkPolicyName(workspaceID),
+					Namespace: m.Config.Namespace,
+				},
+			})
+			tracing.LogEvent(span, "group network policy deleted")
+		}
+	}
+
 	podErr := m.Clientset.Delete(ctx,
 		&corev1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
@@ -487,6 +811,12 @@ func (m *Manager) stopWorkspace(ctx context.Context, workspaceID string, gracePe
 	if portsServiceErr != nil && !isKubernetesObjNotFoundError(portsServiceErr) {
 		return xerrors.Errorf("stopWorkspace: %w", portsServiceErr)
 	}
+	if instanceServiceErr != nil && !isKubernetesObjNotFoundError(instanceServiceErr) {
+		return xerrors.Errorf("stopWorkspace: %w", instanceServiceErr)
+	}
+	if groupNetworkPolicyErr != nil && !isKubernetesObjNotFoundError(groupNetworkPolicyErr) {
+		return xerrors.Errorf("stopWorkspace: %w", groupNetworkPolicyErr)
+	}
 	return nil
 }
 
@@ -517,6 +847,7 @@ func (m *Manager) findWorkspacePod(ctx context.Context, workspaceID string) (*co
 }
 
 // getPodID computes the pod ID from a workpace ID
+//
 //nolint:unused,deadcode
 func getPodID(workspaceType, workspaceID string) string {
 	return fmt.Sprintf("%s-%s", strings.TrimSpace(strings.ToLower(workspaceType)), strings.TrimSpace(workspaceID))
@@ -599,7 +930,9 @@ func (m *Manager) markAllWorkspacesActive() error {
 		return xerrors.Errorf("markAllWorkspacesActive: %w", err)
 	}
 
+	now := time.Now()
 	m.activityLock.Lock()
+	m.assumedActivity = now
 	for _, pod := range pods.Items {
 		wsid, ok := pod.Annotations[workspaceIDAnnotation]
 		if !ok {
@@ -607,7 +940,7 @@ func (m *Manager) markAllWorkspacesActive() error {
 			continue
 		}
 
-		m.activity[wsid] = time.Now()
+		m.activity[wsid] = now
 	}
 	m.activityLock.Unlock()
 	return nil
@@ -618,6 +951,23 @@ func (m *Manager) ControlPort(ctx context.Context, req *api.ControlPortRequest)
 	span, ctx := tracing.FromContext(ctx, "ControlPort")
 	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
 	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{
+			Action: "ControlPort",
+			Id:     req.Id,
+			Parameters: map[string]string{
+				"port":       fmt.Sprint(req.Spec.GetPort()),
+				"expose":     fmt.Sprint(req.Expose),
+				"visibility": req.Spec.GetVisibility().String(),
+			},
+		}, err)
+	}()
+
+	generationDone, err := m.checkExpectedGeneration(req.Id, req.ExpectedGeneration)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { generationDone(err) }()
 
 	pod, err := m.findWorkspacePod(ctx, req.Id)
 	if err != nil {
@@ -778,7 +1128,7 @@ func (m *Manager) ControlPort(ctx context.Context, req *api.ControlPortRequest)
 
 		for _, p := range service.Spec.Ports {
 			ingressPort, _ := alloc.AllocatedPort(int(p.Port))
-			url, err := renderWorkspacePortURL(m.Config.WorkspacePortURLTemplate, portURLContext{
+			url, err := renderWorkspacePortURL(m.Config.WorkspacePortURLTemplate, wsurl.TemplateContext{
 				Host:          m.Config.GitpodHostURL,
 				ID:            req.Id,
 				IngressPort:   fmt.Sprint(ingressPort),
@@ -790,6 +1140,11 @@ func (m *Manager) ControlPort(ctx context.Context, req *api.ControlPortRequest)
 			}
 			service.Annotations[fmt.Sprintf("gitpod/port-url-%d", p.Port)] = url
 		}
+		if req.Expose && req.Spec.PermissiveCors {
+			service.Annotations[portCORSAnnotation(req.Spec.Port)] = portCORSPermissive
+		} else {
+			delete(service.Annotations, portCORSAnnotation(req.Spec.Port))
+		}
 
 		err = m.Clientset.Update(ctx, &service)
 		if err != nil {
@@ -808,6 +1163,14 @@ func (m *Manager) ControlPort(ctx context.Context, req *api.ControlPortRequest)
 	return &api.ControlPortResponse{}, nil
 }
 
+// portCORSPermissive is the value of the port CORS annotation of ports with permissive CORS
+const portCORSPermissive = "permissive"
+
+// portCORSAnnotation is the annotation on the ports service which marks a port for permissive CORS
+func portCORSAnnotation(port uint32) string {
+	return fmt.Sprintf("gitpod/port-cors-%d", port)
+}
+
 // portSpecToName generates a port name from the given PortSpec
 func portSpecToName(spec *api.PortSpec) string {
 	api.PortVisibility_PORT_VISIBILITY_PUBLIC.EnumDescriptor()
@@ -853,6 +1216,10 @@ func (m *Manager) DescribeWorkspace(ctx context.Context, req *api.DescribeWorksp
 	tracing.ApplyOWI(span, wsk8s.GetOWIFromObject(&pod.ObjectMeta))
 	tracing.LogEvent(span, "get pod")
 
+	// We determine the generation before computing the status so that the status is at least as recent
+	// as any status update we published under that generation.
+	generation := m.generations.Current(req.Id)
+
 	wso, err := m.getWorkspaceObjects(ctx, pod)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot get workspace status: %q", err)
@@ -862,6 +1229,7 @@ func (m *Manager) DescribeWorkspace(ctx context.Context, req *api.DescribeWorksp
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot get workspace status: %q", err)
 	}
+	sts.Generation = generation
 
 	result := &api.DescribeWorkspaceResponse{
 		Status: sts,
@@ -898,6 +1266,11 @@ func (m *Manager) subscribe(ctx context.Context, recv subscriber) (err error) {
 		// we must generate they key within the lock, otherwise we might end up with duplicate keys
 		key = fmt.Sprintf("k%d@%d", len(m.subscribers), time.Now().UnixNano())
 	}
+	// new subscribers learn about an announced maintenance right away. We do this while holding the lock
+	// so that a concurrent SetMaintenance cannot be overtaken by the state we send here.
+	if maintenance := m.currentMaintenance(); maintenance != nil {
+		incoming <- &api.SubscribeResponse{Payload: &api.SubscribeResponse_Maintenance{Maintenance: maintenance}}
+	}
 	m.subscribers[key] = incoming
 	log.WithField("subscriberKey", key).WithField("subscriberCount", len(m.subscribers)).Info("new subscriber")
 	m.subscriberLock.Unlock()
@@ -980,6 +1353,11 @@ func (m *Manager) dropSubscriber(dropouts []string) {
 func (m *Manager) onChange(ctx context.Context, status *api.WorkspaceStatus) {
 	log := log.WithFields(log.OWI(status.Metadata.Owner, status.Metadata.MetaId, status.Id))
 
+	status.Generation = m.generations.Next(status.Id)
+	if status.Phase == api.WorkspacePhase_STOPPED {
+		m.generations.Forget(status.Id)
+	}
+
 	header := make(map[string]string)
 	span := opentracing.SpanFromContext(ctx)
 	if span != nil {
@@ -1006,7 +1384,9 @@ func (m *Manager) onChange(ctx context.Context, status *api.WorkspaceStatus) {
 		Header:  header,
 	})
 
-	m.metrics.OnChange(status)
+	m.metrics.OnChange(ctx, status)
+	m.onAccountingChange(status)
+	m.onArchivalChange(status)
 
 	// There are some conditions we'd like to get notified about, for example while running experiements or because
 	// they represent out-of-the-ordinary situations.
@@ -1040,11 +1420,18 @@ func (m *Manager) GetWorkspaces(ctx context.Context, req *api.GetWorkspacesReque
 
 	result := make([]*api.WorkspaceStatus, 0, len(wsos))
 	for _, wso := range wsos {
+		var generation uint64
+		if id, ok := wso.WorkspaceID(); ok {
+			// see DescribeWorkspace for why we determine the generation first
+			generation = m.generations.Current(id)
+		}
+
 		status, err := m.getWorkspaceStatus(wso)
 		if err != nil {
 			log.WithError(err).Error("cannot get complete workspace list")
 			continue
 		}
+		status.Generation = generation
 
 		result = append(result, status)
 	}
@@ -1182,7 +1569,11 @@ func (m *Manager) connectToWorkspaceDaemon(ctx context.Context, wso workspaceObj
 		return nil, err
 	}
 
-	return wsdaemon.NewWorkspaceContentServiceClient(conn), nil
+	snc := wsdaemon.NewWorkspaceContentServiceClient(conn)
+	if meta := chaosObjectMeta(&wso); m.chaos.Applies(ChaosDaemonTimeout, meta) {
+		snc = &chaosDaemonClient{WorkspaceContentServiceClient: snc, manager: m, meta: meta}
+	}
+	return snc, nil
 }
 
 // newWssyncConnectionFactory creates a new wsdaemon connection factory based on the wsmanager configuration
@@ -27,6 +27,9 @@ func (m *Manager) TakeSnapshot(ctx context.Context, req *api.TakeSnapshotRequest
 	span, ctx := tracing.FromContext(ctx, "TakeSnapshot")
 	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
 	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "TakeSnapshot", Id: req.Id}, err)
+	}()
 
 	pod, err := m.findWorkspacePod(ctx, req.Id)
 	if isKubernetesObjNotFoundError(err) {
@@ -73,6 +76,15 @@ func (m *Manager) ControlAdmission(ctx context.Context, req *api.ControlAdmissio
 	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
 	tracing.LogRequestSafe(span, req)
 	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "ControlAdmission", Id: req.Id, Parameters: map[string]string{"level": req.Level.String()}}, err)
+	}()
+
+	generationDone, err := m.checkExpectedGeneration(req.Id, req.ExpectedGeneration)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { generationDone(err) }()
 
 	pod, err := m.findWorkspacePod(ctx, req.Id)
 	if isKubernetesObjNotFoundError(err) {
@@ -119,12 +131,21 @@ func (m *Manager) SetTimeout(ctx context.Context, req *api.SetTimeoutRequest) (r
 	span, ctx := tracing.FromContext(ctx, "SetTimeout")
 	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
 	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "SetTimeout", Id: req.Id, Parameters: map[string]string{"duration": req.Duration}}, err)
+	}()
 
 	_, err = time.ParseDuration(req.Duration)
 	if err != nil {
 		return nil, xerrors.Errorf("invalid duration \"%s\": %w", req.Duration, err)
 	}
 
+	generationDone, err := m.checkExpectedGeneration(req.Id, req.ExpectedGeneration)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { generationDone(err) }()
+
 	err = m.markWorkspace(ctx, req.Id, addMark(customTimeoutAnnotation, req.Duration))
 	if err != nil {
 		return nil, xerrors.Errorf("cannot set workspace timeout: %w", err)
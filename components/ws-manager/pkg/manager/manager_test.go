@@ -197,6 +197,7 @@ func TestGetWorkspaces(t *testing.T) {
 					}
 
 					status.Metadata.StartedAt = nil
+					status.Generation = 0
 					newStatus = append(newStatus, status)
 				}
 
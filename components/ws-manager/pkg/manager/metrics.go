@@ -11,10 +11,12 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
 	"github.com/gitpod-io/gitpod/ws-manager/api"
 )
 
@@ -31,23 +33,42 @@ const (
 type metrics struct {
 	manager *Manager
 
-	startupTimeHistVec    *prometheus.HistogramVec
-	totalStartsCounterVec *prometheus.CounterVec
-	totalStopsCounterVec  *prometheus.CounterVec
+	startupTimeHistVec     *prometheus.HistogramVec
+	phaseTransitionHistVec *prometheus.HistogramVec
+	totalStartsCounterVec  *prometheus.CounterVec
+	totalStopsCounterVec   *prometheus.CounterVec
+
+	deferredStartsCounterVec *prometheus.CounterVec
+
+	chaosFaultsCounterVec *prometheus.CounterVec
+
+	softDeletionsCounterVec *prometheus.CounterVec
+	pausedCounterVec        *prometheus.CounterVec
+	templatesCounterVec     *prometheus.CounterVec
+
+	// traceID returns the ID of the trace a status update belongs to, which we link latencies to as exemplar
+	traceID func(ctx context.Context) string
 
 	mu         sync.Mutex
-	phaseState map[string]api.WorkspacePhase
+	phaseState map[string]phaseState
 }
 
-type wsstate struct {
+// phaseState is the phase a workspace instance is in and when it entered that phase
+type phaseState struct {
 	Phase api.WorkspacePhase
-	Type  api.WorkspaceType
+	Since time.Time
 }
 
+// exemplarTraceIDLabel is the exemplar label which carries the trace ID
+const exemplarTraceIDLabel = "trace_id"
+
 func newMetrics(m *Manager) *metrics {
 	return &metrics{
 		manager:    m,
-		phaseState: make(map[string]api.WorkspacePhase),
+		phaseState: make(map[string]phaseState),
+		traceID: func(ctx context.Context) string {
+			return tracing.TraceIDOf(opentracing.SpanFromContext(ctx))
+		},
 		startupTimeHistVec: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: metricsNamespace,
 			Subsystem: metricsWorkspaceSubsystem,
@@ -56,6 +77,13 @@ func newMetrics(m *Manager) *metrics {
 			// same as components/ws-manager-bridge/src/prometheus-metrics-exporter.ts#L15
 			Buckets: prometheus.ExponentialBuckets(2, 2, 10),
 		}, []string{"type"}),
+		phaseTransitionHistVec: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsWorkspaceSubsystem,
+			Name:      "phase_transition_seconds",
+			Help:      "time workspace instances spent in a phase before they transitioned to the next one",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+		}, []string{"from", "to", "type"}),
 		totalStartsCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: metricsNamespace,
 			Subsystem: metricsWorkspaceSubsystem,
@@ -68,6 +96,36 @@ func newMetrics(m *Manager) *metrics {
 			Name:      "stops_total",
 			Help:      "total number of workspaces stopped",
 		}, []string{"reason"}),
+		deferredStartsCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsWorkspaceSubsystem,
+			Name:      "starts_deferred_total",
+			Help:      "total number of workspace starts deferred while the cluster was warming up",
+		}, []string{"outcome"}),
+		chaosFaultsCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsWorkspaceSubsystem,
+			Name:      "chaos_faults_total",
+			Help:      "total number of faults injected into test workspaces",
+		}, []string{"fault"}),
+		softDeletionsCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsWorkspaceSubsystem,
+			Name:      "soft_deletions_total",
+			Help:      "total number of workspaces soft-deleted, restored and purged",
+		}, []string{"outcome"}),
+		templatesCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsWorkspaceSubsystem,
+			Name:      "template_operations_total",
+			Help:      "total number of workspace templates created, updated, deleted and used to start workspaces",
+		}, []string{"operation"}),
+		pausedCounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsWorkspaceSubsystem,
+			Name:      "paused_total",
+			Help:      "total number of workspaces created paused, activated and expired before their activation",
+		}, []string{"outcome"}),
 	}
 }
 
@@ -75,12 +133,21 @@ func newMetrics(m *Manager) *metrics {
 func (m *metrics) Register(reg prometheus.Registerer) error {
 	collectors := []prometheus.Collector{
 		m.startupTimeHistVec,
+		m.phaseTransitionHistVec,
 		newPhaseTotalVec(m.manager),
 		newWorkspaceActivityVec(m.manager),
 		newTimeoutSettingsVec(m.manager),
 		newSubscriberQueueLevelVec(m.manager),
 		m.totalStartsCounterVec,
 		m.totalStopsCounterVec,
+		m.deferredStartsCounterVec,
+		m.chaosFaultsCounterVec,
+		m.softDeletionsCounterVec,
+		m.pausedCounterVec,
+		m.templatesCounterVec,
+		newSlowStartRateGauge(m.manager),
+		newArchivedWorkspacesVec(m.manager),
+		newScaleHintsVec(m.manager),
 	}
 	for _, c := range collectors {
 		err := reg.Register(c)
@@ -103,24 +170,114 @@ func (m *metrics) OnWorkspaceStarted(tpe api.WorkspaceType) {
 	counter.Inc()
 }
 
-func (m *metrics) OnChange(status *api.WorkspaceStatus) {
-	var removeFromState bool
+func (m *metrics) OnChaosFaultInjected(fault ChaosFault) {
+	counter, err := m.chaosFaultsCounterVec.GetMetricWithLabelValues(string(fault))
+	if err != nil {
+		log.WithError(err).WithField("fault", fault).Warn("cannot get counter for chaos fault metric")
+		return
+	}
+
+	counter.Inc()
+}
+
+func (m *metrics) OnWorkspaceSoftDeletion(outcome string) {
+	counter, err := m.softDeletionsCounterVec.GetMetricWithLabelValues(outcome)
+	if err != nil {
+		log.WithError(err).WithField("outcome", outcome).Warn("cannot get counter for soft-deletion metric")
+		return
+	}
+
+	counter.Inc()
+}
+
+func (m *metrics) OnWorkspaceTemplate(operation string) {
+	counter, err := m.templatesCounterVec.GetMetricWithLabelValues(operation)
+	if err != nil {
+		log.WithError(err).WithField("operation", operation).Warn("cannot get counter for workspace template metric")
+		return
+	}
+
+	counter.Inc()
+}
+
+func (m *metrics) OnPausedWorkspace(outcome string) {
+	counter, err := m.pausedCounterVec.GetMetricWithLabelValues(outcome)
+	if err != nil {
+		log.WithError(err).WithField("outcome", outcome).Warn("cannot get counter for paused workspace metric")
+		return
+	}
+
+	counter.Inc()
+}
+
+func (m *metrics) OnWorkspaceStartDeferred(outcome string) {
+	counter, err := m.deferredStartsCounterVec.GetMetricWithLabelValues(outcome)
+	if err != nil {
+		log.WithError(err).WithField("outcome", outcome).Warn("cannot get counter for deferred workspace start metric")
+		return
+	}
+
+	counter.Inc()
+}
+
+// newSlowStartRateGauge reports the starts per minute we currently admit, or -1 if we do not limit starts
+func newSlowStartRateGauge(m *Manager) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsWorkspaceSubsystem,
+		Name:      "start_admission_rate",
+		Help:      "workspace starts per minute admitted while the cluster is warming up, -1 if starts are not limited",
+	}, func() float64 {
+		rate := m.slowStart.Rate()
+		if rate < 0 {
+			return -1
+		}
+		return rate
+	})
+}
+
+// observeWithTrace observes v and links it to the trace traceID as exemplar, unless traceID is empty
+func observeWithTrace(obs prometheus.Observer, v float64, traceID string) {
+	if eo, ok := obs.(prometheus.ExemplarObserver); ok && traceID != "" {
+		eo.ObserveWithExemplar(v, prometheus.Labels{exemplarTraceIDLabel: traceID})
+		return
+	}
+	obs.Observe(v)
+}
+
+func (m *metrics) OnChange(ctx context.Context, status *api.WorkspaceStatus) {
+	var (
+		removeFromState bool
+		now             = time.Now()
+		traceID         = m.traceID(ctx)
+		tpe             = api.WorkspaceType_name[int32(status.GetSpec().GetType())]
+	)
 	m.mu.Lock()
+	prev, known := m.phaseState[status.Id]
 	defer func() {
 		if removeFromState {
 			delete(m.phaseState, status.Id)
-		} else {
-			m.phaseState[status.Id] = status.Phase
+		} else if !known || prev.Phase != status.Phase {
+			m.phaseState[status.Id] = phaseState{Phase: status.Phase, Since: now}
 		}
 		m.mu.Unlock()
 	}()
 
+	if known && prev.Phase != status.Phase {
+		hist, err := m.phaseTransitionHistVec.GetMetricWithLabelValues(prev.Phase.String(), status.Phase.String(), tpe)
+		if err != nil {
+			log.WithError(err).WithField("type", tpe).Warn("cannot get phase transition histogram metric")
+		} else {
+			observeWithTrace(hist, now.Sub(prev.Since).Seconds(), traceID)
+		}
+	}
+
 	switch status.Phase {
 	case api.WorkspacePhase_RUNNING:
 		if status.Metadata.StartedAt == nil {
 			return
 		}
-		countedAlready := m.phaseState[status.Id] == api.WorkspacePhase_RUNNING
+		countedAlready := known && prev.Phase == api.WorkspacePhase_RUNNING
 		if countedAlready {
 			return
 		}
@@ -129,13 +286,12 @@ func (m *metrics) OnChange(status *api.WorkspaceStatus) {
 		if err != nil {
 			return
 		}
-		tpe := api.WorkspaceType_name[int32(status.Spec.Type)]
 		hist, err := m.startupTimeHistVec.GetMetricWithLabelValues(tpe)
 		if err != nil {
 			log.WithError(err).WithField("type", tpe).Warn("cannot get startup time histogram metric")
 			return
 		}
-		hist.Observe(time.Since(t).Seconds())
+		observeWithTrace(hist, now.Sub(t).Seconds(), traceID)
 
 	case api.WorkspacePhase_STOPPED:
 		var reason string
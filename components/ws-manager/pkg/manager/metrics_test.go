@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestPhaseTransitionExemplars(t *testing.T) {
+	m := newMetrics(nil)
+	m.traceID = func(ctx context.Context) string { return "0123456789abcdef" }
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m.phaseTransitionHistVec, m.startupTimeHistVec)
+
+	startedAt, _ := ptypes.TimestampProto(time.Now().Add(-10 * time.Second))
+	status := func(phase api.WorkspacePhase) *api.WorkspaceStatus {
+		return &api.WorkspaceStatus{
+			Id:       "instance",
+			Phase:    phase,
+			Metadata: &api.WorkspaceMetadata{StartedAt: startedAt},
+			Spec:     &api.WorkspaceSpec{Type: api.WorkspaceType_REGULAR},
+		}
+	}
+	for _, phase := range []api.WorkspacePhase{
+		api.WorkspacePhase_PENDING,
+		api.WorkspacePhase_CREATING,
+		// repeated updates within a phase are no transition
+		api.WorkspacePhase_CREATING,
+		api.WorkspacePhase_INITIALIZING,
+		api.WorkspacePhase_RUNNING,
+		api.WorkspacePhase_RUNNING,
+	} {
+		m.OnChange(context.Background(), status(phase))
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hists := make(map[string][]*dto.Metric)
+	for _, mf := range mfs {
+		hists[mf.GetName()] = mf.Metric
+	}
+
+	transitions := hists["gitpod_ws_manager_workspace_phase_transition_seconds"]
+	if len(transitions) != 3 {
+		t.Fatalf("expected three kinds of transitions, got %d", len(transitions))
+	}
+	for _, metric := range transitions {
+		if cnt := metric.GetHistogram().GetSampleCount(); cnt != 1 {
+			t.Errorf("expected one observation per transition, got %d", cnt)
+		}
+		assertTraceExemplar(t, metric, "0123456789abcdef")
+	}
+
+	startup := hists["gitpod_ws_manager_workspace_startup_seconds"]
+	if len(startup) != 1 || startup[0].GetHistogram().GetSampleCount() != 1 {
+		t.Fatalf("expected the startup to be observed once, got %v", startup)
+	}
+	assertTraceExemplar(t, startup[0], "0123456789abcdef")
+}
+
+func TestPhaseTransitionWithoutTrace(t *testing.T) {
+	m := newMetrics(nil)
+	m.traceID = func(ctx context.Context) string { return "" }
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m.phaseTransitionHistVec)
+
+	for _, phase := range []api.WorkspacePhase{api.WorkspacePhase_PENDING, api.WorkspacePhase_CREATING} {
+		m.OnChange(context.Background(), &api.WorkspaceStatus{Id: "instance", Phase: phase, Spec: &api.WorkspaceSpec{}})
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mfs) != 1 || len(mfs[0].Metric) != 1 {
+		t.Fatalf("expected one transition, got %v", mfs)
+	}
+	for _, b := range mfs[0].Metric[0].GetHistogram().GetBucket() {
+		if b.Exemplar != nil {
+			t.Errorf("expected no exemplar without trace, got %v", b.Exemplar)
+		}
+	}
+}
+
+// assertTraceExemplar fails the test if none of the histogram's buckets links to the trace
+func assertTraceExemplar(t *testing.T, metric *dto.Metric, traceID string) {
+	t.Helper()
+
+	for _, b := range metric.GetHistogram().GetBucket() {
+		for _, l := range b.GetExemplar().GetLabel() {
+			if l.GetName() == exemplarTraceIDLabel && l.GetValue() == traceID {
+				return
+			}
+		}
+	}
+	t.Errorf("histogram has no exemplar linking to trace %s", traceID)
+}
@@ -122,6 +122,7 @@ func (m *Manager) CreateMonitor() (*Monitor, error) {
 		}
 	}
 	res.eventpool = workpool.NewEventWorkerPool(res.handleEvent)
+	m.monitor = &res
 
 	return &res, nil
 }
@@ -319,6 +320,14 @@ func (m *Monitor) actOnPodEvent(ctx context.Context, status *api.WorkspaceStatus
 		// It is important that we do not fail here if the pod is already gone, i.e. when we lost the race. The
 		// stopping PLIS update MUST still happen in this case.
 		err := m.manager.stopWorkspace(ctx, workspaceID, stopWorkspaceNormallyGracePeriod)
+		if pod.DeletionTimestamp == nil {
+			// we record the first time only
+			reason := "failed"
+			if status.Conditions.Failed == "" {
+				reason = "timeout"
+			}
+			m.manager.audit(ctx, &api.AuditRecord{Action: "StopWorkspace", Id: workspaceID, Metadata: getWorkspaceMetadata(pod), Parameters: map[string]string{"reason": reason}}, err)
+		}
 		if err != nil && !isKubernetesObjNotFoundError(err) {
 			return xerrors.Errorf("cannot stop workspace: %w", err)
 		}
@@ -341,6 +350,16 @@ func (m *Monitor) actOnPodEvent(ctx context.Context, status *api.WorkspaceStatus
 		}()
 	}
 
+	if pod.Annotations[workspacePausedAnnotation] == pausedActivating && (status.Phase == api.WorkspacePhase_INITIALIZING || status.Phase == api.WorkspacePhase_RUNNING) {
+		// the workspace was activated before its supervisor was up - tell it now
+		go func() {
+			err := m.manager.completeActivation(ctx, pod)
+			if err != nil {
+				log.WithError(err).Debug("cannot tell supervisor about the activation yet")
+			}
+		}()
+	}
+
 	if status.Phase == api.WorkspacePhase_INITIALIZING {
 		if wso.IsWorkspaceHeadless() {
 			return
@@ -363,6 +382,8 @@ func (m *Monitor) actOnPodEvent(ctx context.Context, status *api.WorkspaceStatus
 	}
 
 	if status.Phase == api.WorkspacePhase_RUNNING {
+		m.manager.scheduleChaosPodDeletion(pod)
+
 		if wso.IsWorkspaceHeadless() {
 			// this is a headless workspace, which means that instead of probing for it becoming available, we'll listen to its log
 			// output, parse it and forward it. Listen() is idempotent.
@@ -376,7 +397,16 @@ func (m *Monitor) actOnPodEvent(ctx context.Context, status *api.WorkspaceStatus
 			tracing.LogEvent(span, "removeTraceAnnotation")
 			// once a regular workspace is up and running, we'll remove the traceID information so that the parent span
 			// ends once the workspace has started
-			err := m.manager.markWorkspace(ctx, workspaceID, deleteMark(wsk8s.TraceIDAnnotation))
+			annotations := []*annotation{deleteMark(wsk8s.TraceIDAnnotation)}
+
+			// the workspace is reachable now, hence the preview DNS controller can create its DNS record
+			_, hasPreviewDNS := pod.Annotations[wsk8s.PreviewDNSHostnameAnnotation]
+			_, previewDNSReady := pod.Annotations[wsk8s.PreviewDNSReadyAnnotation]
+			if hasPreviewDNS && !previewDNSReady {
+				annotations = append(annotations, addMark(wsk8s.PreviewDNSReadyAnnotation, "true"))
+			}
+
+			err := m.manager.markWorkspace(ctx, workspaceID, annotations...)
 			if err != nil {
 				log.WithError(err).Warn("was unable to remove traceID annotation from workspace")
 			}
@@ -421,6 +451,14 @@ func (m *Monitor) actOnPodEvent(ctx context.Context, status *api.WorkspaceStatus
 		if err != nil {
 			return xerrors.Errorf("cannot update pod lifecycle independent state: %w", err)
 		}
+
+		// workspace groups share their lifecycle - if one member stops, all of them stop
+		if groupID := pod.Labels[workspaceGroupLabel]; groupID != "" {
+			err = m.manager.stopWorkspaceGroup(ctx, groupID, workspaceID, stopWorkspaceNormallyGracePeriod)
+			if err != nil {
+				log.WithError(err).WithField("group", groupID).Warn("cannot stop the other members of the workspace group")
+			}
+		}
 	}
 
 	return nil
@@ -458,6 +496,7 @@ func (m *Monitor) actOnHeadlessDone(pod *corev1.Pod, failed bool) (err error) {
 		// log error and try to stop the workspace
 		log.WithError(err).Warn("cannot mark headless workspace as failed - stopping myself")
 		err = m.manager.stopWorkspace(context.Background(), id, stopWorkspaceNormallyGracePeriod)
+		m.manager.audit(context.Background(), &api.AuditRecord{Action: "StopWorkspace", Id: id, Metadata: getWorkspaceMetadata(pod), Parameters: map[string]string{"reason": "headless failed"}}, err)
 		if err == nil || isKubernetesObjNotFoundError(err) {
 			// workspace is gone - we're good
 			return nil
@@ -499,6 +538,7 @@ func (m *Monitor) actOnHeadlessDone(pod *corev1.Pod, failed bool) (err error) {
 
 	// healthy prebuilds don't fail the workspace, thus we have to stop them ourselves
 	err = m.manager.stopWorkspace(ctx, id, stopWorkspaceNormallyGracePeriod)
+	m.manager.audit(ctx, &api.AuditRecord{Action: "StopWorkspace", Id: id, Metadata: getWorkspaceMetadata(pod), Parameters: map[string]string{"reason": "headless finished"}}, err)
 	if err != nil {
 		log.WithError(err).Error("unable to stop finished headless workspace")
 	}
@@ -664,6 +704,27 @@ func (m *Monitor) doHousekeeping(ctx context.Context) {
 	if err != nil {
 		m.OnError(err)
 	}
+
+	if m.manager.Config.InstanceDNS != nil {
+		err = m.deleteDanglingGroupNetworkPolicies(ctx)
+		if err != nil {
+			m.OnError(err)
+		}
+	}
+
+	if m.manager.Config.PausedWorkspaces != nil {
+		err = m.completeActivations(ctx)
+		if err != nil {
+			m.OnError(err)
+		}
+	}
+
+	if m.manager.accounting != nil {
+		err = m.recordMissingInstancesStopped(ctx)
+		if err != nil {
+			m.OnError(err)
+		}
+	}
 }
 
 // writeEventTraceLog writes an event trace log if one is configured. This function is written in
@@ -1104,6 +1165,9 @@ func (m *Monitor) finalizeWorkspaceContent(ctx context.Context, wso *workspaceOb
 		if resp != nil {
 			gitStatus = resp.GitStatus
 		}
+		if err == nil && doBackup && m.manager.injectChaos(ChaosBackupFailure, "", chaosObjectMeta(wso)) {
+			err = grpc_status.Error(codes.DataLoss, "chaos: injected backup failure")
+		}
 
 		// we're done disposing - remove from the finalizerMap
 		m.finalizerMapLock.Lock()
@@ -1302,6 +1366,15 @@ func (m *Monitor) deleteDanglingPodLifecycleIndependentState(ctx context.Context
 	return nil
 }
 
+// isRunning returns true if the workspace is in the running phase
+func (m *Monitor) isRunning(wso workspaceObjects) bool {
+	sts, err := m.manager.getWorkspaceStatus(wso)
+	if err != nil {
+		return false
+	}
+	return sts.Phase == api.WorkspacePhase_RUNNING
+}
+
 // markTimedoutWorkspaces finds workspaces which haven't been active recently and marks them as timed out
 func (m *Monitor) markTimedoutWorkspaces(ctx context.Context) (err error) {
 	span, ctx := tracing.FromContext(ctx, "markTimedoutWorkspaces")
@@ -1313,8 +1386,19 @@ func (m *Monitor) markTimedoutWorkspaces(ctx context.Context) (err error) {
 		return xerrors.Errorf("stopTimedoutWorkspaces: %w", err)
 	}
 
-	errs := make([]string, 0)
-	idx := make(map[string]struct{})
+	type timedoutMember struct {
+		WorkspaceID string
+		Reason      string
+	}
+	var (
+		errs = make([]string, 0)
+		idx  = make(map[string]struct{})
+		// a workspace group times out only once all of its members have timed out
+		groupMembers = make(map[string][]timedoutMember)
+		activeGroups = make(map[string]struct{})
+
+		scheduledStops = m.scheduledStopReasons(ctx, pods.Items)
+	)
 	for _, pod := range pods.Items {
 		workspaceID, ok := pod.Annotations[workspaceIDAnnotation]
 		if !ok {
@@ -1329,20 +1413,53 @@ func (m *Monitor) markTimedoutWorkspaces(ctx context.Context) (err error) {
 			continue
 		}
 
+		if reason := scheduledStops[pod.Name]; reason != "" {
+			// a scheduled stop applies to the workspace alone, regardless of its group
+			err = m.manager.markWorkspace(ctx, workspaceID, addMark(workspaceTimedOutAnnotation, reason))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("workspaceId=%s: %q", workspaceID, err))
+			}
+			continue
+		}
+
 		timedout, err := m.manager.isWorkspaceTimedOut(workspaceObjects{Pod: &pod})
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("workspaceId=%s: %q", workspaceID, err))
 			continue
 		}
+		if groupID := pod.Labels[workspaceGroupLabel]; groupID != "" && m.isRunning(workspaceObjects{Pod: &pod}) {
+			// running members time out due to inactivity, which must not stop the group while other members are active.
+			// Members which take too long to start or stop time out as usual.
+			if timedout == "" {
+				activeGroups[groupID] = struct{}{}
+			} else {
+				groupMembers[groupID] = append(groupMembers[groupID], timedoutMember{WorkspaceID: workspaceID, Reason: timedout})
+			}
+			continue
+		}
 		if timedout == "" {
 			continue
 		}
+		if pod.Annotations[workspacePausedAnnotation] == pausedWaiting {
+			m.manager.metrics.OnPausedWorkspace("expired")
+		}
 		err = m.manager.markWorkspace(ctx, workspaceID, addMark(workspaceTimedOutAnnotation, timedout))
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("workspaceId=%s: %q", workspaceID, err))
 			// don't skip the next step - even if we did not mark the workspace as timed out, we still want to stop it
 		}
 	}
+	for groupID, members := range groupMembers {
+		if _, active := activeGroups[groupID]; active {
+			continue
+		}
+		for _, member := range members {
+			err = m.manager.markWorkspace(ctx, member.WorkspaceID, addMark(workspaceTimedOutAnnotation, member.Reason))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("workspaceId=%s: %q", member.WorkspaceID, err))
+			}
+		}
+	}
 
 	// timeout PLIS only workspaces
 	var allPlis corev1.ConfigMapList
@@ -0,0 +1,183 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// pausedWaiting marks a paused workspace which waits to be activated
+	pausedWaiting = "true"
+	// pausedActivating marks a paused workspace which was activated, but whose supervisor does not know yet
+	pausedActivating = "activating"
+
+	// supervisorResumePath is where we activate a paused workspace with supervisor, relative to the supervisor URL
+	supervisorResumePath = "/_supervisor/v1/resume"
+)
+
+// PausedWorkspacesConfig configures workspaces which are created ahead of predicted demand and activated by StartWorkspace
+type PausedWorkspacesConfig struct {
+	// Timeout is how long a paused workspace waits to be activated before we stop it
+	Timeout util.Duration `json:"timeout"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *PausedWorkspacesConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Timeout, validation.Required, validation.Min(util.Duration(0))),
+	)
+}
+
+// validatePausedRequest makes sure we can create the workspace of a request paused, if it asks for that
+func (m *Manager) validatePausedRequest(req *api.StartWorkspaceRequest) error {
+	if !req.Paused {
+		return nil
+	}
+	if m.Config.PausedWorkspaces == nil {
+		return xerrors.Errorf("paused workspaces are not enabled")
+	}
+	if req.Type != api.WorkspaceType_REGULAR {
+		return xerrors.Errorf("only regular workspaces can be paused")
+	}
+	if req.Metadata.GroupId != "" {
+		return xerrors.Errorf("members of workspace groups cannot be paused")
+	}
+	return nil
+}
+
+// activatePausedWorkspace activates the paused workspace a start request refers to. Returns false if there is no such workspace,
+// in which case the request starts a new workspace as usual.
+func (m *Manager) activatePausedWorkspace(ctx context.Context, req *api.StartWorkspaceRequest) (res *api.StartWorkspaceResponse, activated bool, err error) {
+	if m.Config.PausedWorkspaces == nil {
+		return nil, false, nil
+	}
+
+	pod, err := m.findWorkspacePod(ctx, req.Id)
+	if isKubernetesObjNotFoundError(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, xerrors.Errorf("cannot find paused workspace: %w", err)
+	}
+	paused, ok := pod.Annotations[workspacePausedAnnotation]
+	if !ok || pod.Labels[wsk8s.OwnerLabel] != req.Metadata.Owner {
+		// not ours to activate - the request fails because the workspace exists already
+		return nil, false, nil
+	}
+	if isPodBeingDeleted(pod) {
+		return nil, false, status.Errorf(codes.FailedPrecondition, "paused workspace %s is stopping", req.Id)
+	}
+
+	log := log.WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta))
+	if paused == pausedWaiting {
+		err = m.markWorkspace(ctx, req.Id,
+			addMark(workspacePausedAnnotation, pausedActivating),
+			addMark(workspaceActivatedAnnotation, time.Now().UTC().Format(time.RFC3339)),
+		)
+		if err != nil {
+			return nil, false, xerrors.Errorf("cannot activate paused workspace: %w", err)
+		}
+		m.metrics.OnPausedWorkspace("activated")
+		log.Info("activated paused workspace")
+	}
+
+	// If supervisor isn't up yet the monitor tells it once it is.
+	err = m.completeActivation(ctx, pod)
+	if err != nil {
+		log.WithError(err).Debug("cannot tell supervisor about the activation yet")
+	}
+
+	return &api.StartWorkspaceResponse{
+		Url:       pod.Annotations[workspaceURLAnnotation],
+		Activated: true,
+	}, true, nil
+}
+
+// completeActivation tells the supervisor of an activated workspace to start the IDE and the tasks.
+// It does nothing if we are telling supervisor already.
+func (m *Manager) completeActivation(ctx context.Context, pod *corev1.Pod) error {
+	workspaceID := pod.Annotations[workspaceIDAnnotation]
+	if _, busy := m.activations.LoadOrStore(workspaceID, struct{}{}); busy {
+		return nil
+	}
+	defer m.activations.Delete(workspaceID)
+
+	sctx, cancel := context.WithTimeout(ctx, supervisorRequestTimeout)
+	defer cancel()
+	err := resumeSupervisor(sctx, pod)
+	if err != nil {
+		return err
+	}
+	return m.markWorkspace(ctx, workspaceID, deleteMark(workspacePausedAnnotation))
+}
+
+// resumeSupervisor asks the supervisor of a paused workspace to start the IDE and the tasks
+func resumeSupervisor(ctx context.Context, pod *corev1.Pod) error {
+	req, err := newSupervisorRequest(ctx, pod, http.MethodPost, supervisorResumePath, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := supervisorClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("supervisor responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// getActivatedAt returns when a paused workspace was activated
+func getActivatedAt(pod *corev1.Pod) (time.Time, bool) {
+	v, ok := pod.Annotations[workspaceActivatedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).WithField("activated", v).Warn("pod has invalid activated annotation - ignoring it")
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// completeActivations tells the supervisors of activated workspaces about their activation, if they missed it
+func (m *Monitor) completeActivations(ctx context.Context) error {
+	var pods corev1.PodList
+	err := m.manager.Clientset.List(ctx, &pods, workspaceObjectListOptions(m.manager.Config.Namespace))
+	if err != nil {
+		return xerrors.Errorf("completeActivations: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Annotations[workspacePausedAnnotation] != pausedActivating || isPodBeingDeleted(pod) {
+			continue
+		}
+		err := m.manager.completeActivation(ctx, pod)
+		if err != nil {
+			log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).Warn("cannot tell supervisor about the activation - will try again")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestActivatePausedWorkspace(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		resumes int32
+		up      int32
+	)
+	supervisor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&up) == 0 {
+			http.Error(w, "supervisor is not up yet", http.StatusBadGateway)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != supervisorResumePath || r.Header.Get("X-Gitpod-Owner-Token") != "" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&resumes, 1)
+		_, _ = w.Write([]byte(`{"paused":false}`))
+	}))
+	defer supervisor.Close()
+
+	newPod := func(id string, annotations map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ws-" + id,
+				Namespace: "default",
+				Labels: map[string]string{
+					markerLabel:       "true",
+					wsk8s.MetaIDLabel: id,
+					wsk8s.OwnerLabel:  "owner",
+					"workspaceID":     id,
+					wsk8s.TypeLabel:   "regular",
+				},
+				Annotations: map[string]string{
+					workspaceIDAnnotation:  id,
+					workspaceURLAnnotation: supervisor.URL,
+					ownerTokenAnnotation:   "owner-token",
+				},
+			},
+		}
+		for k, v := range annotations {
+			pod.Annotations[k] = v
+		}
+		return withSupervisor(t, pod, supervisor)
+	}
+	m := &Manager{
+		Config: Configuration{
+			Namespace:        "default",
+			PausedWorkspaces: &PausedWorkspacesConfig{Timeout: util.Duration(10 * time.Minute)},
+		},
+		Clientset: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			newPod("paused", map[string]string{workspacePausedAnnotation: pausedWaiting}),
+			newPod("running", nil),
+		).Build(),
+	}
+	m.metrics = newMetrics(m)
+	startRequest := func(id, owner string) *api.StartWorkspaceRequest {
+		return &api.StartWorkspaceRequest{Id: id, Metadata: &api.WorkspaceMetadata{Owner: owner, MetaId: id}}
+	}
+
+	for _, req := range []*api.StartWorkspaceRequest{
+		startRequest("unknown", "owner"),
+		startRequest("running", "owner"),
+		startRequest("paused", "someone-else"),
+	} {
+		_, activated, err := m.activatePausedWorkspace(ctx, req)
+		if err != nil || activated {
+			t.Errorf("expected %s of %s not to be activated, got %v, %v", req.Id, req.Metadata.Owner, activated, err)
+		}
+	}
+
+	// supervisor isn't up yet - the activation completes later
+	res, activated, err := m.activatePausedWorkspace(ctx, startRequest("paused", "owner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !activated || !res.Activated || res.Url != supervisor.URL {
+		t.Fatalf("expected paused workspace to be activated, got %v, %v", res, activated)
+	}
+	pod, err := m.findWorkspacePod(ctx, "paused")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := pod.Annotations[workspacePausedAnnotation]; v != pausedActivating {
+		t.Errorf("expected activation to be pending, got %s annotation %q", workspacePausedAnnotation, v)
+	}
+	if _, ok := getActivatedAt(pod); !ok {
+		t.Errorf("expected activated workspace to have a valid %s annotation", workspaceActivatedAnnotation)
+	}
+
+	atomic.StoreInt32(&up, 1)
+	mon := &Monitor{manager: m}
+	err = mon.completeActivations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod, err = m.findWorkspacePod(ctx, "paused")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, paused := pod.Annotations[workspacePausedAnnotation]; paused || atomic.LoadInt32(&resumes) != 1 {
+		t.Errorf("expected supervisor to complete the activation, got %d resumes and annotations %v", resumes, pod.Annotations)
+	}
+
+	// activated workspaces are regular workspaces, which exist already
+	_, activated, err = m.activatePausedWorkspace(ctx, startRequest("paused", "owner"))
+	if err != nil || activated {
+		t.Errorf("expected activated workspace not to be activated again, got %v, %v", activated, err)
+	}
+}
+
+func TestValidatePausedRequest(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Config  *PausedWorkspacesConfig
+		Request *api.StartWorkspaceRequest
+		Valid   bool
+	}{
+		{
+			Name:    "not paused",
+			Request: &api.StartWorkspaceRequest{Type: api.WorkspaceType_PREBUILD, Metadata: &api.WorkspaceMetadata{}},
+			Valid:   true,
+		},
+		{
+			Name:    "paused",
+			Config:  &PausedWorkspacesConfig{Timeout: util.Duration(time.Minute)},
+			Request: &api.StartWorkspaceRequest{Paused: true, Metadata: &api.WorkspaceMetadata{}},
+			Valid:   true,
+		},
+		{
+			Name:    "not enabled",
+			Request: &api.StartWorkspaceRequest{Paused: true, Metadata: &api.WorkspaceMetadata{}},
+		},
+		{
+			Name:    "prebuild",
+			Config:  &PausedWorkspacesConfig{Timeout: util.Duration(time.Minute)},
+			Request: &api.StartWorkspaceRequest{Paused: true, Type: api.WorkspaceType_PREBUILD, Metadata: &api.WorkspaceMetadata{}},
+		},
+		{
+			Name:    "group member",
+			Config:  &PausedWorkspacesConfig{Timeout: util.Duration(time.Minute)},
+			Request: &api.StartWorkspaceRequest{Paused: true, Metadata: &api.WorkspaceMetadata{GroupId: "group"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			m := &Manager{Config: Configuration{PausedWorkspaces: test.Config}}
+			err := m.validatePausedRequest(test.Request)
+			if test.Valid && err != nil {
+				t.Errorf("expected request to be valid, got %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected request to be invalid")
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// PodTemplatePolicy restricts what operator-supplied pod templates may change about workspace pods.
+// The zero value forbids privileged containers, host namespaces, hostPath volumes and added capabilities.
+type PodTemplatePolicy struct {
+	// AllowPrivileged admits privileged containers and containers which allow privilege escalation
+	AllowPrivileged bool `json:"allowPrivileged,omitempty"`
+	// AllowHostNamespaces admits pods which use the host's network, PID or IPC namespace
+	AllowHostNamespaces bool `json:"allowHostNamespaces,omitempty"`
+	// AllowedHostPaths are the path prefixes hostPath volumes may mount. If empty, hostPath volumes are forbidden.
+	AllowedHostPaths []string `json:"allowedHostPaths,omitempty"`
+	// AllowedCapabilities are the Linux capabilities containers may add
+	AllowedCapabilities []string `json:"allowedCapabilities,omitempty"`
+	// MaxResources caps the resource requests and limits of containers
+	MaxResources *ResourceConfiguration `json:"maxResources,omitempty"`
+}
+
+// Validate validates the policy
+func (p *PodTemplatePolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	for _, hp := range p.AllowedHostPaths {
+		if !filepath.IsAbs(hp) {
+			return xerrors.Errorf("allowedHostPaths: %s is not an absolute path", hp)
+		}
+	}
+	if p.MaxResources != nil {
+		err := validation.Validate(*p.MaxResources, validResourceConfig)
+		if err != nil {
+			return xerrors.Errorf("maxResources: %w", err)
+		}
+	}
+	return nil
+}
+
+// PodTemplateViolation is a part of a pod template which violates the pod template policy
+type PodTemplateViolation struct {
+	// Field is the path of the offending field, e.g. spec.containers[0].securityContext.privileged
+	Field   string
+	Message string
+}
+
+func (v PodTemplateViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// Check returns all parts of the template which violate the policy. A nil policy is the zero policy.
+func (p *PodTemplatePolicy) Check(tpl *corev1.Pod) (violations []PodTemplateViolation) {
+	if tpl == nil {
+		return nil
+	}
+	if p == nil {
+		p = &PodTemplatePolicy{}
+	}
+	violate := func(field, format string, args ...interface{}) {
+		violations = append(violations, PodTemplateViolation{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	spec := tpl.Spec
+	if !p.AllowHostNamespaces {
+		for _, ns := range []struct {
+			Field   string
+			Enabled bool
+		}{
+			{"spec.hostNetwork", spec.HostNetwork},
+			{"spec.hostPID", spec.HostPID},
+			{"spec.hostIPC", spec.HostIPC},
+		} {
+			if ns.Enabled {
+				violate(ns.Field, "host namespaces are not allowed")
+			}
+		}
+	}
+
+	for i, vol := range spec.Volumes {
+		if vol.HostPath == nil {
+			continue
+		}
+		if !p.hostPathAllowed(vol.HostPath.Path) {
+			violate(fmt.Sprintf("spec.volumes[%d].hostPath.path", i), "host path %s is not allowed", vol.HostPath.Path)
+		}
+	}
+
+	var maxResources corev1.ResourceList
+	if p.MaxResources != nil {
+		// the policy is validated during startup, hence the quantities parse
+		maxResources, _ = p.MaxResources.ResourceList()
+	}
+	checkContainers := func(field string, containers []corev1.Container) {
+		for i, c := range containers {
+			field := fmt.Sprintf("%s[%d]", field, i)
+			if sc := c.SecurityContext; sc != nil {
+				if !p.AllowPrivileged && sc.Privileged != nil && *sc.Privileged {
+					violate(field+".securityContext.privileged", "privileged containers are not allowed")
+				}
+				if !p.AllowPrivileged && sc.AllowPrivilegeEscalation != nil && *sc.AllowPrivilegeEscalation {
+					violate(field+".securityContext.allowPrivilegeEscalation", "privilege escalation is not allowed")
+				}
+				if sc.Capabilities != nil {
+					for _, cap := range sc.Capabilities.Add {
+						if !p.capabilityAllowed(string(cap)) {
+							violate(field+".securityContext.capabilities.add", "capability %s is not allowed", cap)
+						}
+					}
+				}
+			}
+
+			for name, req := range c.Resources.Requests {
+				if lim, ok := c.Resources.Limits[name]; ok && req.Cmp(lim) > 0 {
+					violate(fmt.Sprintf("%s.resources.requests.%s", field, name), "request %s exceeds the limit %s", req.String(), lim.String())
+				}
+			}
+			for _, kind := range []struct {
+				Name string
+				List corev1.ResourceList
+			}{{"requests", c.Resources.Requests}, {"limits", c.Resources.Limits}} {
+				for name, q := range kind.List {
+					if q.Sign() < 0 {
+						violate(fmt.Sprintf("%s.resources.%s.%s", field, kind.Name, name), "quantity must not be negative")
+						continue
+					}
+					if max, ok := maxResources[name]; ok && q.Cmp(max) > 0 {
+						violate(fmt.Sprintf("%s.resources.%s.%s", field, kind.Name, name), "%s exceeds the maximum of %s", q.String(), max.String())
+					}
+				}
+			}
+		}
+	}
+	checkContainers("spec.initContainers", spec.InitContainers)
+	checkContainers("spec.containers", spec.Containers)
+
+	// resource lists are maps - we sort the violations for a stable result
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Field < violations[j].Field })
+	return violations
+}
+
+func (p *PodTemplatePolicy) hostPathAllowed(path string) bool {
+	path = filepath.Clean(path)
+	for _, allowed := range p.AllowedHostPaths {
+		allowed = filepath.Clean(allowed)
+		if path == allowed || strings.HasPrefix(path, strings.TrimSuffix(allowed, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PodTemplatePolicy) capabilityAllowed(cap string) bool {
+	cap = strings.TrimPrefix(strings.ToUpper(cap), "CAP_")
+	for _, allowed := range p.AllowedCapabilities {
+		if strings.TrimPrefix(strings.ToUpper(allowed), "CAP_") == cap {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePodTemplate parses a pod template from YAML or JSON
+func parsePodTemplate(tpl []byte) (*corev1.Pod, error) {
+	var res corev1.Pod
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(tpl), 4096)
+	err := decoder.Decode(&res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal pod template: %w", err)
+	}
+	return &res, nil
+}
+
+// podTemplateStore loads pod templates and accepts only those which comply with the pod template policy.
+// Operators change templates at runtime by updating their config map. If a changed template violates the policy,
+// we keep using the template we accepted last rather than failing every workspace start.
+type podTemplateStore struct {
+	mu       sync.Mutex
+	accepted map[string]*loadedPodTemplate
+	rejected map[string][]byte
+}
+
+type loadedPodTemplate struct {
+	Source []byte
+	Pod    *corev1.Pod
+}
+
+func newPodTemplateStore() *podTemplateStore {
+	return &podTemplateStore{
+		accepted: make(map[string]*loadedPodTemplate),
+		rejected: make(map[string][]byte),
+	}
+}
+
+// Get returns the template at filename, or the template we accepted last if the current one violates the policy.
+// Returns nil if filename is empty. Callers may modify the returned pod.
+func (s *podTemplateStore) Get(filename string, policy *PodTemplatePolicy) (*corev1.Pod, error) {
+	if filename == "" {
+		return nil, nil
+	}
+
+	src, err := readPodTemplate(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := s.accepted[filename]
+	if last != nil && bytes.Equal(last.Source, src) {
+		return last.Pod.DeepCopy(), nil
+	}
+	if rejected, ok := s.rejected[filename]; ok && bytes.Equal(rejected, src) {
+		// we've complained about this version already
+		return s.fallback(filename, last, xerrors.Errorf("pod template violates the pod template policy"))
+	}
+
+	pod, err := parsePodTemplate(src)
+	if err == nil {
+		if violations := policy.Check(pod); len(violations) > 0 {
+			msgs := make([]string, len(violations))
+			for i, v := range violations {
+				msgs[i] = v.String()
+			}
+			err = xerrors.Errorf("pod template violates the pod template policy: %s", strings.Join(msgs, "; "))
+		}
+	}
+	if err != nil {
+		s.rejected[filename] = src
+		log.WithError(err).WithField("template", filename).Error("rejected pod template")
+		return s.fallback(filename, last, err)
+	}
+
+	delete(s.rejected, filename)
+	s.accepted[filename] = &loadedPodTemplate{Source: src, Pod: pod}
+	return pod.DeepCopy(), nil
+}
+
+func (s *podTemplateStore) fallback(filename string, last *loadedPodTemplate, err error) (*corev1.Pod, error) {
+	if last == nil {
+		return nil, err
+	}
+	log.WithField("template", filename).Debug("using the last accepted version of a rejected pod template")
+	return last.Pod.DeepCopy(), nil
+}
+
+// readPodTemplate reads a pod template file
+func readPodTemplate(filename string) ([]byte, error) {
+	tpr := os.Getenv("TELEPRESENCE_ROOT")
+	if tpr != "" {
+		filename = filepath.Join(tpr, filename)
+	}
+
+	tpl, err := fs.Open(filename)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read pod template: %w", err)
+	}
+	defer tpl.Close()
+
+	res, err := ioutil.ReadAll(tpl)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read pod template: %w", err)
+	}
+	return res, nil
+}
+
+// ValidatePodTemplate checks a pod template against the pod template policy without applying it
+func (m *Manager) ValidatePodTemplate(ctx context.Context, req *api.ValidatePodTemplateRequest) (*api.ValidatePodTemplateResponse, error) {
+	pod, err := parsePodTemplate([]byte(req.Template))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	violations := m.Config.WorkspacePodTemplate.Policy.Check(pod)
+	res := &api.ValidatePodTemplateResponse{
+		Violations: make([]*api.PodTemplateViolation, 0, len(violations)),
+	}
+	for _, v := range violations {
+		res.Violations = append(res.Violations, &api.PodTemplateViolation{Field: v.Field, Message: v.Message})
+	}
+	return res, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestPodTemplatePolicyCheck(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Policy      *PodTemplatePolicy
+		Template    string
+		Expectation []string
+	}{
+		{
+			Desc:     "harmless template",
+			Template: `{"spec":{"dnsPolicy":"None","containers":[{"name":"workspace","resources":{"requests":{"cpu":"1"},"limits":{"cpu":"2"}}}]}}`,
+		},
+		{
+			Desc:     "privileged container",
+			Template: `{"spec":{"containers":[{"name":"workspace","securityContext":{"privileged":true,"allowPrivilegeEscalation":true}}]}}`,
+			Expectation: []string{
+				"spec.containers[0].securityContext.allowPrivilegeEscalation: privilege escalation is not allowed",
+				"spec.containers[0].securityContext.privileged: privileged containers are not allowed",
+			},
+		},
+		{
+			Desc:     "privileged container allowed",
+			Policy:   &PodTemplatePolicy{AllowPrivileged: true},
+			Template: `{"spec":{"containers":[{"name":"workspace","securityContext":{"privileged":true}}]}}`,
+		},
+		{
+			Desc:        "host network",
+			Template:    `{"spec":{"hostNetwork":true}}`,
+			Expectation: []string{"spec.hostNetwork: host namespaces are not allowed"},
+		},
+		{
+			Desc:     "host paths",
+			Policy:   &PodTemplatePolicy{AllowedHostPaths: []string{"/mnt/cache"}},
+			Template: `{"spec":{"volumes":[{"name":"a","hostPath":{"path":"/mnt/cache/npm"}},{"name":"b","hostPath":{"path":"/mnt/cache-other"}},{"name":"c","hostPath":{"path":"/mnt/cache/../../etc"}}]}}`,
+			Expectation: []string{
+				"spec.volumes[1].hostPath.path: host path /mnt/cache-other is not allowed",
+				"spec.volumes[2].hostPath.path: host path /mnt/cache/../../etc is not allowed",
+			},
+		},
+		{
+			Desc:        "capabilities",
+			Policy:      &PodTemplatePolicy{AllowedCapabilities: []string{"CAP_NET_BIND_SERVICE"}},
+			Template:    `{"spec":{"initContainers":[{"name":"init","securityContext":{"capabilities":{"add":["NET_BIND_SERVICE","SYS_ADMIN"]}}}]}}`,
+			Expectation: []string{"spec.initContainers[0].securityContext.capabilities.add: capability SYS_ADMIN is not allowed"},
+		},
+		{
+			Desc:     "resources",
+			Policy:   &PodTemplatePolicy{MaxResources: &ResourceConfiguration{Memory: "16Gi"}},
+			Template: `{"spec":{"containers":[{"name":"workspace","resources":{"requests":{"cpu":"4"},"limits":{"cpu":"2","memory":"32Gi"}}}]}}`,
+			Expectation: []string{
+				"spec.containers[0].resources.limits.memory: 32Gi exceeds the maximum of 16Gi",
+				"spec.containers[0].resources.requests.cpu: request 4 exceeds the limit 2",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			tpl, err := parsePodTemplate([]byte(test.Template))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var act []string
+			for _, v := range test.Policy.Check(tpl) {
+				act = append(act, v.String())
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected violations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPodTemplateStore(t *testing.T) {
+	const (
+		good    = `{"spec":{"dnsPolicy":"None"}}`
+		better  = `{"spec":{"dnsPolicy":"Default"}}`
+		violent = `{"spec":{"hostPID":true}}`
+	)
+	mapFS := fstest.MapFS{}
+	fs = mapFS
+
+	store := newPodTemplateStore()
+	get := func(ctnt string) (*corev1.Pod, error) {
+		mapFS["template.yaml"] = &fstest.MapFile{Data: []byte(ctnt)}
+		return store.Get("template.yaml", nil)
+	}
+
+	_, err := get(violent)
+	if err == nil {
+		t.Fatal("expected the violating template to be rejected without a fallback")
+	}
+
+	pod, err := get(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// callers may modify the template
+	pod.Spec.DNSPolicy = corev1.DNSClusterFirst
+
+	for i := 0; i < 2; i++ {
+		pod, err = get(violent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pod.Spec.HostPID || pod.Spec.DNSPolicy != corev1.DNSNone {
+			t.Errorf("expected the last accepted template, got %+v", pod.Spec)
+		}
+	}
+
+	pod, err = get(better)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pod.Spec.DNSPolicy != corev1.DNSDefault {
+		t.Errorf("expected the changed template, got %+v", pod.Spec)
+	}
+
+	pod, err = store.Get("", nil)
+	if pod != nil || err != nil {
+		t.Errorf("expected no template for an empty path, got %v, %v", pod, err)
+	}
+}
+
+func TestValidatePodTemplate(t *testing.T) {
+	m := &Manager{Config: Configuration{WorkspacePodTemplate: WorkspacePodTemplateConfiguration{
+		Policy: &PodTemplatePolicy{AllowedHostPaths: []string{"/var/gitpod"}},
+	}}}
+
+	resp, err := m.ValidatePodTemplate(context.Background(), &api.ValidatePodTemplateRequest{
+		Template: "spec:\n  volumes:\n  - name: root\n    hostPath:\n      path: /\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []*api.PodTemplateViolation{{Field: "spec.volumes[0].hostPath.path", Message: "host path / is not allowed"}}
+	if diff := cmp.Diff(exp, resp.Violations, cmp.Comparer(func(a, b *api.PodTemplateViolation) bool {
+		return a.Field == b.Field && a.Message == b.Message
+	})); diff != "" {
+		t.Errorf("unexpected violations (-want +got):\n%s", diff)
+	}
+
+	_, err = m.ValidatePodTemplate(context.Background(), &api.ValidatePodTemplateRequest{Template: "spec: ["})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a template which does not parse, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultProxyActivityMaxAge            = 2 * time.Minute
+	defaultProxyActivityMaxExtension      = 30 * time.Minute
+	defaultProxyActivityMinReportInterval = 30 * time.Second
+)
+
+// ProxyActivityConfig configures how the connections proxies have open to a workspace count towards its activity
+type ProxyActivityConfig struct {
+	// MaxAge is the time after which we forget the report of a proxy that stopped reporting. Defaults to 2 minutes.
+	MaxAge util.Duration `json:"maxAge,omitempty"`
+	// MaxExtension is the time open connections can keep a workspace alive past the last activity of its user,
+	// e.g. because a browser tab still streams logs. Defaults to 30 minutes.
+	MaxExtension util.Duration `json:"maxExtension,omitempty"`
+	// MinReportInterval is the time proxies have to wait between two reports. Defaults to 30 seconds.
+	MinReportInterval util.Duration `json:"minReportInterval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ProxyActivityConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.MaxAge, validation.Min(util.Duration(0))),
+		validation.Field(&c.MaxExtension, validation.Min(util.Duration(0))),
+		validation.Field(&c.MinReportInterval, validation.Min(util.Duration(0))),
+	)
+}
+
+func (c *ProxyActivityConfig) maxAge() time.Duration {
+	if c.MaxAge == 0 {
+		return defaultProxyActivityMaxAge
+	}
+	return time.Duration(c.MaxAge)
+}
+
+func (c *ProxyActivityConfig) maxExtension() time.Duration {
+	if c.MaxExtension == 0 {
+		return defaultProxyActivityMaxExtension
+	}
+	return time.Duration(c.MaxExtension)
+}
+
+func (c *ProxyActivityConfig) minReportInterval() time.Duration {
+	if c.MinReportInterval == 0 {
+		return defaultProxyActivityMinReportInterval
+	}
+	return time.Duration(c.MinReportInterval)
+}
+
+// proxyActivity is what a single proxy last reported for a workspace
+type proxyActivity struct {
+	Connections uint32
+	Sessions    uint32
+	Time        time.Time
+}
+
+// ReportProxyActivity records the connections a proxy has open to workspaces
+func (m *Manager) ReportProxyActivity(ctx context.Context, req *api.ReportProxyActivityRequest) (res *api.ReportProxyActivityResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "ReportProxyActivity")
+	span.SetTag("proxy", req.Proxy)
+	defer tracing.FinishSpan(span, &err)
+
+	cfg := m.Config.ProxyActivity
+	if cfg == nil {
+		return nil, status.Error(codes.Unimplemented, "proxy activity is disabled")
+	}
+	if req.Proxy == "" {
+		return nil, status.Error(codes.InvalidArgument, "proxy is required")
+	}
+
+	now := time.Now()
+	m.proxyActivityLock.Lock()
+	if m.proxyActivity == nil {
+		m.proxyActivity = make(map[string]map[string]proxyActivity)
+	}
+	for _, ws := range req.Workspaces {
+		if ws.Id == "" {
+			continue
+		}
+
+		proxies := m.proxyActivity[ws.Id]
+		if ws.Connections == 0 {
+			delete(proxies, req.Proxy)
+			if len(proxies) == 0 {
+				delete(m.proxyActivity, ws.Id)
+			}
+			continue
+		}
+		if proxies == nil {
+			proxies = make(map[string]proxyActivity)
+			m.proxyActivity[ws.Id] = proxies
+		}
+		proxies[req.Proxy] = proxyActivity{
+			Connections: ws.Connections,
+			Sessions:    ws.Sessions,
+			Time:        now,
+		}
+	}
+
+	// proxies which went away don't tell us - hence we forget what they reported at some point
+	for id, proxies := range m.proxyActivity {
+		for proxy, act := range proxies {
+			if now.Sub(act.Time) > cfg.maxAge() {
+				delete(proxies, proxy)
+			}
+		}
+		if len(proxies) == 0 {
+			delete(m.proxyActivity, id)
+		}
+	}
+	m.proxyActivityLock.Unlock()
+
+	log.WithField("proxy", req.Proxy).WithField("workspaces", len(req.Workspaces)).Debug("proxy reported activity")
+	return &api.ReportProxyActivityResponse{
+		MinInterval: cfg.minReportInterval().String(),
+	}, nil
+}
+
+// getProxyActivity sums up the current reports of all proxies for a workspace. The returned time
+// is the time of the most recent report, and zero if no proxy has connections to the workspace.
+func (m *Manager) getProxyActivity(workspaceID string) (connections, sessions uint32, lastReport time.Time) {
+	cfg := m.Config.ProxyActivity
+	if cfg == nil {
+		return
+	}
+
+	m.proxyActivityLock.Lock()
+	defer m.proxyActivityLock.Unlock()
+	for _, act := range m.proxyActivity[workspaceID] {
+		if time.Since(act.Time) > cfg.maxAge() {
+			continue
+		}
+		connections += act.Connections
+		sessions += act.Sessions
+		if act.Time.After(lastReport) {
+			lastReport = act.Time
+		}
+	}
+	return
+}
+
+// withProxyActivity extends the last activity of a workspace by the connections proxies have open to it.
+// Open connections extend the activity by at most MaxExtension, so that a forgotten browser tab cannot
+// keep a workspace alive forever.
+func (m *Manager) withProxyActivity(workspaceID string, lastActivity time.Time) time.Time {
+	connections, _, lastReport := m.getProxyActivity(workspaceID)
+	if connections == 0 || !lastReport.After(lastActivity) {
+		return lastActivity
+	}
+
+	limit := lastActivity.Add(m.Config.ProxyActivity.maxExtension())
+	if lastReport.After(limit) {
+		return limit
+	}
+	return lastReport
+}
+
+// hasProxySessions returns true if a proxy currently streams to the workspace, e.g. logs or a terminal
+func (m *Manager) hasProxySessions(workspaceID string) bool {
+	_, sessions, _ := m.getProxyActivity(workspaceID)
+	return sessions > 0
+}
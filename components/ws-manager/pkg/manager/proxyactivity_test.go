@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestReportProxyActivity(t *testing.T) {
+	m := &Manager{
+		Config: Configuration{
+			ProxyActivity: &ProxyActivityConfig{
+				MaxExtension:      util.Duration(30 * time.Minute),
+				MinReportInterval: util.Duration(time.Minute),
+			},
+		},
+	}
+	report := func(proxy string, workspaces ...*api.WorkspaceProxyActivity) {
+		resp, err := m.ReportProxyActivity(context.Background(), &api.ReportProxyActivityRequest{Proxy: proxy, Workspaces: workspaces})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.MinInterval != "1m0s" {
+			t.Errorf("unexpected min interval: %s", resp.MinInterval)
+		}
+	}
+
+	report("proxy-a", &api.WorkspaceProxyActivity{Id: "foo", Connections: 2, Sessions: 1})
+	report("proxy-b", &api.WorkspaceProxyActivity{Id: "foo", Connections: 1}, &api.WorkspaceProxyActivity{Id: "bar", Connections: 1})
+	if c, s, _ := m.getProxyActivity("foo"); c != 3 || s != 1 {
+		t.Errorf("expected the reports of all proxies to add up, got %d connections and %d sessions", c, s)
+	}
+	if !m.hasProxySessions("foo") || m.hasProxySessions("bar") {
+		t.Error("unexpected sessions")
+	}
+
+	report("proxy-a", &api.WorkspaceProxyActivity{Id: "foo"})
+	if c, s, _ := m.getProxyActivity("foo"); c != 1 || s != 0 {
+		t.Errorf("expected closed connections to be removed, got %d connections and %d sessions", c, s)
+	}
+
+	m.proxyActivity["bar"]["proxy-b"] = proxyActivity{Connections: 1, Time: time.Now().Add(-1 * time.Hour)}
+	if c, _, _ := m.getProxyActivity("bar"); c != 0 {
+		t.Errorf("expected stale reports to be ignored, got %d connections", c)
+	}
+	report("proxy-a")
+	if _, ok := m.proxyActivity["bar"]; ok {
+		t.Error("expected stale reports to be pruned")
+	}
+
+	_, err := m.ReportProxyActivity(context.Background(), &api.ReportProxyActivityRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a report without proxy, got %v", err)
+	}
+	_, err = (&Manager{}).ReportProxyActivity(context.Background(), &api.ReportProxyActivityRequest{Proxy: "proxy-a"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented if proxy activity is disabled, got %v", err)
+	}
+}
+
+func TestWithProxyActivity(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		Name         string
+		LastActivity time.Duration
+		Report       *proxyActivity
+		Expectation  time.Duration
+	}{
+		{Name: "no report", LastActivity: 10 * time.Minute, Expectation: 10 * time.Minute},
+		{Name: "open connections", LastActivity: 10 * time.Minute, Report: &proxyActivity{Connections: 1, Time: now.Add(-1 * time.Minute)}, Expectation: 1 * time.Minute},
+		{Name: "report before the last activity", LastActivity: 1 * time.Minute, Report: &proxyActivity{Connections: 1, Time: now.Add(-90 * time.Second)}, Expectation: 1 * time.Minute},
+		{Name: "extension is capped", LastActivity: 50 * time.Minute, Report: &proxyActivity{Connections: 1, Time: now.Add(-1 * time.Minute)}, Expectation: 20 * time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			m := &Manager{
+				Config:        Configuration{ProxyActivity: &ProxyActivityConfig{MaxExtension: util.Duration(30 * time.Minute)}},
+				proxyActivity: make(map[string]map[string]proxyActivity),
+			}
+			if test.Report != nil {
+				m.proxyActivity["foo"] = map[string]proxyActivity{"proxy-a": *test.Report}
+			}
+
+			act := m.withProxyActivity("foo", now.Add(-test.LastActivity))
+			if exp := now.Add(-test.Expectation); !act.Equal(exp) {
+				t.Errorf("unexpected activity: want %s ago, got %s ago", test.Expectation, now.Sub(act))
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultScaleHintWindow   = 10 * time.Minute
+	defaultScaleHintLeadTime = 3 * time.Minute
+
+	// scaleHintDefaultClass is the class of workspace pods which match none of the configured classes
+	scaleHintDefaultClass = "default"
+
+	scaleHintSourcePending  = "pending"
+	scaleHintSourceForecast = "forecast"
+)
+
+// ScaleHintsConfig configures the scale hints we publish for cluster autoscalers. Autoscalers only react once pods are
+// unschedulable, which adds the time it takes to provision a node to workspace starts. The hints tell them which node
+// shapes the workspaces we are about to start need, e.g. to size a low-priority placeholder deployment per node shape
+// through an external metrics adapter.
+type ScaleHintsConfig struct {
+	// Classes group workspace pods by the nodes they need. A pod belongs to the first class whose node selector its
+	// node selector or required node affinity demands. Pods which match no class count towards the "default" class.
+	Classes []ScaleHintClass `json:"classes,omitempty"`
+	// Window is the period over which we measure the start rate of a class. Defaults to 10 minutes.
+	Window util.Duration `json:"window,omitempty"`
+	// LeadTime is how long it takes to provision a node. We forecast the starts within the lead time. Defaults to 3 minutes.
+	LeadTime util.Duration `json:"leadTime,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ScaleHintsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Classes),
+		validation.Field(&c.Window, validation.Min(util.Duration(0))),
+		validation.Field(&c.LeadTime, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(c.Classes))
+	for _, cls := range c.Classes {
+		if cls.Name == scaleHintDefaultClass {
+			return xerrors.Errorf("class name %s is reserved", scaleHintDefaultClass)
+		}
+		if _, exists := names[cls.Name]; exists {
+			return xerrors.Errorf("class %s is configured twice", cls.Name)
+		}
+		names[cls.Name] = struct{}{}
+	}
+	return nil
+}
+
+// ScaleHintClass is a group of workspace pods which need the same kind of node
+type ScaleHintClass struct {
+	Name string `json:"name"`
+	// NodeSelector are the node labels the workspace pods of this class require
+	NodeSelector map[string]string `json:"nodeSelector"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c ScaleHintClass) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required),
+		validation.Field(&c.NodeSelector, validation.Required),
+	)
+}
+
+func (c *ScaleHintsConfig) window() time.Duration {
+	if c.Window == 0 {
+		return defaultScaleHintWindow
+	}
+	return time.Duration(c.Window)
+}
+
+func (c *ScaleHintsConfig) leadTime() time.Duration {
+	if c.LeadTime == 0 {
+		return defaultScaleHintLeadTime
+	}
+	return time.Duration(c.LeadTime)
+}
+
+// scaleHints remembers the recent workspace starts per class to forecast the capacity the next starts need
+type scaleHints struct {
+	Config ScaleHintsConfig
+
+	mu     sync.Mutex
+	starts map[string][]scaleHintStart
+
+	now func() time.Time
+}
+
+type scaleHintStart struct {
+	Time     time.Time
+	Requests corev1.ResourceList
+}
+
+// scaleHint is the capacity a class of workspaces is about to need
+type scaleHint struct {
+	Class    string
+	Pending  int
+	Forecast int
+	Requests corev1.ResourceList
+}
+
+func newScaleHints(cfg *ScaleHintsConfig) *scaleHints {
+	if cfg == nil {
+		return nil
+	}
+	return &scaleHints{
+		Config: *cfg,
+		starts: make(map[string][]scaleHintStart),
+		now:    time.Now,
+	}
+}
+
+// OnStart records the start of a workspace pod
+func (h *scaleHints) OnStart(pod *corev1.Pod) {
+	if h == nil {
+		return
+	}
+
+	var (
+		class = h.classOf(pod)
+		now   = h.now()
+	)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.starts[class] = append(h.prune(class, now), scaleHintStart{Time: now, Requests: podRequests(pod)})
+}
+
+// prune drops the starts of a class which are outside the window. Callers must hold mu.
+func (h *scaleHints) prune(class string, now time.Time) []scaleHintStart {
+	var (
+		starts = h.starts[class]
+		cutoff = now.Add(-h.Config.window())
+		i      int
+	)
+	for i < len(starts) && starts[i].Time.Before(cutoff) {
+		i++
+	}
+	starts = starts[i:]
+	if len(starts) == 0 {
+		delete(h.starts, class)
+	} else {
+		h.starts[class] = starts
+	}
+	return starts
+}
+
+// Hints computes the hint of every class from the unscheduled workspace pods and the recent starts.
+// Configured classes always have a hint, so that autoscalers scale down once the demand is gone.
+func (h *scaleHints) Hints(pods []corev1.Pod) []scaleHint {
+	hints := make(map[string]*scaleHint, len(h.Config.Classes)+1)
+	hint := func(class string) *scaleHint {
+		res, ok := hints[class]
+		if !ok {
+			res = &scaleHint{Class: class, Requests: corev1.ResourceList{}}
+			hints[class] = res
+		}
+		return res
+	}
+	for _, cls := range h.Config.Classes {
+		hint(cls.Name)
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if !isUnscheduled(pod) {
+			continue
+		}
+		res := hint(h.classOf(pod))
+		res.Pending++
+		addResources(res.Requests, podRequests(pod), 1)
+	}
+
+	var (
+		now      = h.now()
+		window   = h.Config.window()
+		leadTime = h.Config.leadTime()
+	)
+	h.mu.Lock()
+	for class := range h.starts {
+		starts := h.prune(class, now)
+		if len(starts) == 0 {
+			continue
+		}
+
+		forecast := int(math.Ceil(float64(len(starts)) * float64(leadTime) / float64(window)))
+		res := hint(class)
+		res.Forecast = forecast
+
+		// the forecast workspaces need what the recent starts of their class needed on average
+		total := corev1.ResourceList{}
+		for _, s := range starts {
+			addResources(total, s.Requests, 1)
+		}
+		for name, q := range total {
+			avg := resource.NewMilliQuantity(q.MilliValue()/int64(len(starts)), q.Format)
+			addResources(res.Requests, corev1.ResourceList{name: *avg}, forecast)
+		}
+	}
+	h.mu.Unlock()
+
+	res := make([]scaleHint, 0, len(hints))
+	for _, h := range hints {
+		res = append(res, *h)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Class < res[j].Class })
+	return res
+}
+
+// classOf returns the class of a workspace pod
+func (h *scaleHints) classOf(pod *corev1.Pod) string {
+	required := requiredNodeLabels(pod)
+	for _, cls := range h.Config.Classes {
+		matches := true
+		for k, v := range cls.NodeSelector {
+			if required[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return cls.Name
+		}
+	}
+	return scaleHintDefaultClass
+}
+
+// requiredNodeLabels returns the node labels a pod demands with a single value, either in its node selector
+// or in the first term of its required node affinity. We AND all affinities into the first term.
+func requiredNodeLabels(pod *corev1.Pod) map[string]string {
+	res := make(map[string]string, len(pod.Spec.NodeSelector))
+	for k, v := range pod.Spec.NodeSelector {
+		res[k] = v
+	}
+
+	aff := pod.Spec.Affinity
+	if aff == nil || aff.NodeAffinity == nil || aff.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return res
+	}
+	terms := aff.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return res
+	}
+	for _, expr := range terms[0].MatchExpressions {
+		if expr.Operator != corev1.NodeSelectorOpIn || len(expr.Values) != 1 {
+			continue
+		}
+		res[expr.Key] = expr.Values[0]
+	}
+	return res
+}
+
+// isUnscheduled returns true if a pod waits for a node
+func isUnscheduled(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// podRequests sums up the resource requests of the containers of a pod
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	res := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		addResources(res, c.Resources.Requests, 1)
+	}
+	return res
+}
+
+func addResources(dst, src corev1.ResourceList, times int) {
+	for name, q := range src {
+		sum := dst[name]
+		for i := 0; i < times; i++ {
+			sum.Add(q)
+		}
+		dst[name] = sum
+	}
+}
+
+// scaleHintsVec reports the scale hints of all classes
+type scaleHintsVec struct {
+	manager *Manager
+
+	workspaces *prometheus.Desc
+	cpu        *prometheus.Desc
+	memory     *prometheus.Desc
+}
+
+func newScaleHintsVec(m *Manager) *scaleHintsVec {
+	return &scaleHintsVec{
+		manager: m,
+		workspaces: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "scale_hint", "workspaces"),
+			"workspaces which wait for a node (pending) or which we expect to start within the node provisioning lead time (forecast), by class",
+			[]string{"class", "source"},
+			prometheus.Labels(map[string]string{}),
+		),
+		cpu: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "scale_hint", "cpu_cores"),
+			"CPU the pending and forecast workspaces of a class request",
+			[]string{"class"},
+			prometheus.Labels(map[string]string{}),
+		),
+		memory: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "scale_hint", "memory_bytes"),
+			"memory the pending and forecast workspaces of a class request",
+			[]string{"class"},
+			prometheus.Labels(map[string]string{}),
+		),
+	}
+}
+
+// Describe implements Collector
+func (v *scaleHintsVec) Describe(ch chan<- *prometheus.Desc) {
+	ch <- v.workspaces
+	ch <- v.cpu
+	ch <- v.memory
+}
+
+// Collect implements Collector
+func (v *scaleHintsVec) Collect(ch chan<- prometheus.Metric) {
+	h := v.manager.scaleHints
+	if h == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kubernetesOperationTimeout)
+	defer cancel()
+
+	var pods corev1.PodList
+	err := v.manager.Clientset.List(ctx, &pods, workspaceObjectListOptions(v.manager.Config.Namespace))
+	if err != nil {
+		log.WithError(err).Debug("cannot list workspaces for scale hints")
+		return
+	}
+
+	for _, hint := range h.Hints(pods.Items) {
+		cpu := hint.Requests[corev1.ResourceCPU]
+		mem := hint.Requests[corev1.ResourceMemory]
+		ch <- prometheus.MustNewConstMetric(v.workspaces, prometheus.GaugeValue, float64(hint.Pending), hint.Class, scaleHintSourcePending)
+		ch <- prometheus.MustNewConstMetric(v.workspaces, prometheus.GaugeValue, float64(hint.Forecast), hint.Class, scaleHintSourceForecast)
+		ch <- prometheus.MustNewConstMetric(v.cpu, prometheus.GaugeValue, float64(cpu.MilliValue())/1000, hint.Class)
+		ch <- prometheus.MustNewConstMetric(v.memory, prometheus.GaugeValue, float64(mem.Value()), hint.Class)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func newScaleHintTestPod(nodeSelector map[string]string, affinity map[string]string, cpu, memory string, scheduled bool) corev1.Pod {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeSelector: nodeSelector,
+			Containers: []corev1.Container{{
+				Name: "workspace",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	if len(affinity) > 0 {
+		var exprs []corev1.NodeSelectorRequirement
+		for k, v := range affinity {
+			exprs = append(exprs, corev1.NodeSelectorRequirement{Key: k, Operator: corev1.NodeSelectorOpIn, Values: []string{v}})
+		}
+		pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: exprs}},
+			},
+		}}
+	}
+	if scheduled {
+		pod.Spec.NodeName = "node-1"
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+func TestScaleHints(t *testing.T) {
+	type hint struct {
+		Class    string
+		Pending  int
+		Forecast int
+		CPU      string
+		Memory   string
+	}
+
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := newScaleHints(&ScaleHintsConfig{
+		Classes: []ScaleHintClass{
+			{Name: "large", NodeSelector: map[string]string{"gitpod.io/workload_workspace": "true", "node.kubernetes.io/instance-type": "n2-standard-16"}},
+			{Name: "regular", NodeSelector: map[string]string{"gitpod.io/workload_workspace": "true"}},
+		},
+		Window:   util.Duration(10 * time.Minute),
+		LeadTime: util.Duration(3 * time.Minute),
+	})
+	h.now = func() time.Time { return now }
+
+	regular := map[string]string{"gitpod.io/workload_workspace": "true"}
+	large := map[string]string{"node.kubernetes.io/instance-type": "n2-standard-16"}
+
+	// starts which left the window don't count towards the forecast
+	start := newScaleHintTestPod(regular, nil, "1", "2Gi", false)
+	h.OnStart(&start)
+	now = now.Add(11 * time.Minute)
+	for i := 0; i < 5; i++ {
+		pod := newScaleHintTestPod(regular, nil, "1", "2Gi", false)
+		h.OnStart(&pod)
+	}
+	start = newScaleHintTestPod(regular, large, "4", "16Gi", false)
+	h.OnStart(&start)
+
+	deleting := newScaleHintTestPod(regular, nil, "1", "2Gi", false)
+	deleting.DeletionTimestamp = &metav1.Time{Time: now}
+	pods := []corev1.Pod{
+		newScaleHintTestPod(regular, nil, "1", "2Gi", false),
+		newScaleHintTestPod(regular, nil, "1", "2Gi", true),
+		newScaleHintTestPod(regular, large, "4", "16Gi", false),
+		newScaleHintTestPod(nil, nil, "500m", "1Gi", false),
+		deleting,
+	}
+
+	var act []hint
+	for _, sh := range h.Hints(pods) {
+		cpu := sh.Requests[corev1.ResourceCPU]
+		mem := sh.Requests[corev1.ResourceMemory]
+		act = append(act, hint{Class: sh.Class, Pending: sh.Pending, Forecast: sh.Forecast, CPU: cpu.String(), Memory: mem.String()})
+	}
+	exp := []hint{
+		{Class: "default", Pending: 1, CPU: "500m", Memory: "1Gi"},
+		// one pending, one forecast: ceil(1 start * 3m / 10m)
+		{Class: "large", Pending: 1, Forecast: 1, CPU: "8", Memory: "32Gi"},
+		// one pending, two forecast: ceil(5 starts * 3m / 10m)
+		{Class: "regular", Pending: 1, Forecast: 2, CPU: "3", Memory: "6Gi"},
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("unexpected hints (-want +got):\n%s", diff)
+	}
+
+	// configured classes keep their hint once the demand is gone
+	now = now.Add(time.Hour)
+	act = nil
+	for _, sh := range h.Hints(nil) {
+		act = append(act, hint{Class: sh.Class, Pending: sh.Pending, Forecast: sh.Forecast})
+	}
+	if diff := cmp.Diff([]hint{{Class: "large"}, {Class: "regular"}}, act); diff != "" {
+		t.Errorf("unexpected hints without demand (-want +got):\n%s", diff)
+	}
+}
+
+func TestScaleHintsConfigValidate(t *testing.T) {
+	selector := map[string]string{"gitpod.io/workload_workspace": "true"}
+	tests := []struct {
+		Name   string
+		Config ScaleHintsConfig
+		Error  bool
+	}{
+		{Name: "valid", Config: ScaleHintsConfig{Classes: []ScaleHintClass{{Name: "regular", NodeSelector: selector}}}},
+		{Name: "no classes", Config: ScaleHintsConfig{}},
+		{Name: "unnamed class", Config: ScaleHintsConfig{Classes: []ScaleHintClass{{NodeSelector: selector}}}, Error: true},
+		{Name: "class without selector", Config: ScaleHintsConfig{Classes: []ScaleHintClass{{Name: "regular"}}}, Error: true},
+		{Name: "reserved class", Config: ScaleHintsConfig{Classes: []ScaleHintClass{{Name: "default", NodeSelector: selector}}}, Error: true},
+		{Name: "duplicate class", Config: ScaleHintsConfig{Classes: []ScaleHintClass{{Name: "regular", NodeSelector: selector}, {Name: "regular", NodeSelector: selector}}}, Error: true},
+		{Name: "negative window", Config: ScaleHintsConfig{Window: util.Duration(-time.Minute)}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("error = %v, expected error: %v", err, test.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// supervisorScheduledStopPath is where supervisor serves the scheduled stop it counts down to, relative to the supervisor URL
+	supervisorScheduledStopPath = "/_supervisor/v1/scheduled-stop"
+	// supervisorRequestTimeout is the time we allow supervisor to answer a request
+	supervisorRequestTimeout = 5 * time.Second
+	// maxConcurrentScheduledStopChecks limits the supervisors we ask about cancelled scheduled stops at once
+	maxConcurrentScheduledStopChecks = 16
+)
+
+// supervisorScheduledStop is the scheduled stop as supervisor knows it
+type supervisorScheduledStop struct {
+	// Deadline is the RFC 3339 time at which the workspace stops. Empty if there is no scheduled stop.
+	Deadline string `json:"deadline,omitempty"`
+	// Cancelled is true if the user cancelled the scheduled stop from within the workspace
+	Cancelled bool `json:"cancelled,omitempty"`
+}
+
+// ScheduleStop schedules, re-schedules or cancels the stop of a running workspace. Supervisor notifies the user before
+// the workspace stops. The scheduled stop is a regular stop, i.e. we back the workspace up before its pod goes away.
+func (m *Manager) ScheduleStop(ctx context.Context, req *api.ScheduleStopRequest) (res *api.ScheduleStopResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "ScheduleStop")
+	tracing.ApplyOWI(span, log.OWI("", "", req.Id))
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		params := map[string]string{"cancel": fmt.Sprint(req.Cancel)}
+		if req.At != nil {
+			params["at"] = ptypes.TimestampString(req.At)
+		}
+		if req.Duration != "" {
+			params["duration"] = req.Duration
+		}
+		m.audit(ctx, &api.AuditRecord{Action: "ScheduleStop", Id: req.Id, Parameters: params}, err)
+	}()
+
+	var deadline time.Time
+	if !req.Cancel {
+		deadline, err = scheduledStopDeadline(req, time.Now())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	generationDone, err := m.checkExpectedGeneration(req.Id, req.ExpectedGeneration)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { generationDone(err) }()
+
+	pod, err := m.findWorkspacePod(ctx, req.Id)
+	if isKubernetesObjNotFoundError(err) {
+		return nil, status.Errorf(codes.NotFound, "workspace %s does not exist", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get workspace status: %q", err)
+	}
+	tracing.ApplyOWI(span, wsk8s.GetOWIFromObject(&pod.ObjectMeta))
+
+	wso, err := m.getWorkspaceObjects(ctx, pod)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get workspace status: %q", err)
+	}
+	sts, err := m.getWorkspaceStatus(*wso)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get workspace status: %q", err)
+	}
+	if sts.Phase != api.WorkspacePhase_RUNNING {
+		return nil, status.Errorf(codes.FailedPrecondition, "can only schedule the stop of running workspaces")
+	}
+
+	res = &api.ScheduleStopResponse{}
+	mark := deleteMark(scheduledStopAnnotation)
+	if !req.Cancel {
+		mark = addMark(scheduledStopAnnotation, deadline.Format(time.RFC3339))
+		res.Deadline, err = ptypes.TimestampProto(deadline)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot convert deadline: %q", err)
+		}
+	}
+	err = m.markWorkspace(ctx, req.Id, mark)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot schedule workspace stop: %q", err)
+	}
+	log.WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).WithField("deadline", deadline).WithField("cancel", req.Cancel).Info("scheduled workspace stop")
+
+	// Supervisor counts down to the deadline. If it misses this update the workspace still stops in time,
+	// but the user isn't warned before.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), supervisorRequestTimeout)
+		defer cancel()
+		err := putSupervisorScheduledStop(ctx, pod, deadline)
+		if err != nil {
+			log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).Warn("cannot tell supervisor about scheduled stop - user will not be notified")
+		}
+	}()
+
+	return res, nil
+}
+
+// scheduledStopDeadline computes the deadline of a schedule stop request
+func scheduledStopDeadline(req *api.ScheduleStopRequest, now time.Time) (time.Time, error) {
+	var deadline time.Time
+	switch {
+	case req.At != nil && req.Duration != "":
+		return time.Time{}, xerrors.Errorf("at and duration are mutually exclusive")
+	case req.At != nil:
+		at, err := ptypes.Timestamp(req.At)
+		if err != nil {
+			return time.Time{}, xerrors.Errorf("invalid time: %w", err)
+		}
+		deadline = at
+	case req.Duration != "":
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return time.Time{}, xerrors.Errorf("invalid duration \"%s\": %w", req.Duration, err)
+		}
+		deadline = now.Add(d)
+	default:
+		return time.Time{}, xerrors.Errorf("either at or duration is required")
+	}
+	// the annotation has second precision
+	deadline = deadline.UTC().Truncate(time.Second)
+	if !deadline.After(now) {
+		return time.Time{}, xerrors.Errorf("scheduled stop must be in the future")
+	}
+	return deadline, nil
+}
+
+// getScheduledStop returns when a workspace pod stops as scheduled
+func getScheduledStop(pod *corev1.Pod) (deadline time.Time, ok bool) {
+	v, ok := pod.Annotations[scheduledStopAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	deadline, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		log.WithError(err).WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta)).WithField("scheduledStop", v).Warn("pod has invalid scheduled stop annotation - ignoring it")
+		return time.Time{}, false
+	}
+	return deadline, true
+}
+
+func getScheduledStopProto(pod *corev1.Pod) *timestamp.Timestamp {
+	deadline, ok := getScheduledStop(pod)
+	if !ok {
+		return nil
+	}
+	ts, err := ptypes.TimestampProto(deadline)
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
+// scheduledStopReasons finds the pods which stop as scheduled and returns why, keyed by pod name. We ask the
+// supervisors of those pods concurrently, so that supervisors which are slow to answer don't hold up the timeout checks.
+func (m *Monitor) scheduledStopReasons(ctx context.Context, pods []corev1.Pod) map[string]string {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentScheduledStopChecks)
+		res = make(map[string]string)
+		now = time.Now()
+	)
+	for i := range pods {
+		pod := &pods[i]
+		if _, timedout := pod.Annotations[workspaceTimedOutAnnotation]; timedout {
+			continue
+		}
+		deadline, ok := getScheduledStop(pod)
+		if !ok || now.Before(deadline) || isPodBeingDeleted(pod) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			reason := m.scheduledStopReason(ctx, pod, deadline)
+			if reason == "" {
+				return
+			}
+			mu.Lock()
+			res[pod.Name] = reason
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return res
+}
+
+// scheduledStopReason returns why a workspace whose scheduled stop is due stops, or an empty string if it must not stop.
+// If the user cancelled the scheduled stop from within the workspace, we remove it instead.
+func (m *Monitor) scheduledStopReason(ctx context.Context, pod *corev1.Pod, deadline time.Time) string {
+
+	log := log.WithFields(wsk8s.GetOWIFromObject(&pod.ObjectMeta))
+	sctx, cancel := context.WithTimeout(ctx, supervisorRequestTimeout)
+	defer cancel()
+	sup, err := getSupervisorScheduledStop(sctx, pod)
+	if err != nil {
+		// we guarantee that the workspace stops - if supervisor does not answer, nobody cancelled the stop
+		log.WithError(err).Debug("cannot ask supervisor if the user cancelled the scheduled stop")
+	} else if sup.Cancelled && sup.Deadline == deadline.Format(time.RFC3339) {
+		workspaceID := pod.Annotations[workspaceIDAnnotation]
+		err = m.manager.markWorkspace(ctx, workspaceID, deleteMark(scheduledStopAnnotation))
+		if err != nil {
+			log.WithError(err).Warn("cannot remove scheduled stop the user cancelled")
+		}
+		log.Info("user cancelled the scheduled stop")
+		return ""
+	}
+
+	return fmt.Sprintf("workspace stopped as scheduled at %s", deadline.Format(time.RFC3339))
+}
+
+// supervisorClient talks to supervisor within the cluster
+var supervisorClient = &http.Client{
+	Timeout: supervisorRequestTimeout,
+}
+
+// supervisorURL returns the URL we reach the supervisor of a workspace pod at. We talk to supervisor on the pod IP
+// and the port its readiness probe uses, rather than through ws-proxy, so that we need neither the owner token
+// nor the workspace URL, whose DNS we don't control.
+func supervisorURL(pod *corev1.Pod) (string, error) {
+	if pod.Status.PodIP == "" {
+		return "", xerrors.Errorf("pod %s has no IP yet", pod.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name != "workspace" || c.ReadinessProbe == nil || c.ReadinessProbe.HTTPGet == nil {
+			continue
+		}
+		port := c.ReadinessProbe.HTTPGet.Port.IntValue()
+		if port == 0 {
+			break
+		}
+		return "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)), nil
+	}
+	return "", xerrors.Errorf("pod %s has no supervisor port", pod.Name)
+}
+
+// newSupervisorRequest creates a request to the supervisor of a workspace pod. path is relative to the supervisor URL.
+func newSupervisorRequest(ctx context.Context, pod *corev1.Pod, method, path string, body io.Reader) (*http.Request, error) {
+	u, err := supervisorURL(pod)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, u+path, body)
+}
+
+// putSupervisorScheduledStop tells the supervisor of a workspace when the workspace stops. A zero deadline cancels the scheduled stop.
+func putSupervisorScheduledStop(ctx context.Context, pod *corev1.Pod, deadline time.Time) error {
+	var body supervisorScheduledStop
+	if !deadline.IsZero() {
+		body.Deadline = deadline.Format(time.RFC3339)
+	}
+	_, err := doSupervisorScheduledStopRequest(ctx, pod, http.MethodPut, &body)
+	return err
+}
+
+// getSupervisorScheduledStop asks the supervisor of a workspace about the scheduled stop
+func getSupervisorScheduledStop(ctx context.Context, pod *corev1.Pod) (*supervisorScheduledStop, error) {
+	return doSupervisorScheduledStopRequest(ctx, pod, http.MethodGet, nil)
+}
+
+func doSupervisorScheduledStopRequest(ctx context.Context, pod *corev1.Pod, method string, body *supervisorScheduledStop) (*supervisorScheduledStop, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := newSupervisorRequest(ctx, pod, method, supervisorScheduledStopPath, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := supervisorClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("supervisor responded with %s", resp.Status)
+	}
+
+	var res supervisorScheduledStop
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode supervisor response: %w", err)
+	}
+	return &res, nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestScheduledStopDeadline(t *testing.T) {
+	now := time.Date(2021, 5, 4, 10, 0, 0, 500, time.UTC)
+	at := func(t time.Time) *api.ScheduleStopRequest {
+		ts, _ := ptypes.TimestampProto(t)
+		return &api.ScheduleStopRequest{At: ts}
+	}
+
+	tests := []struct {
+		Name        string
+		Req         *api.ScheduleStopRequest
+		Expectation time.Time
+		Error       bool
+	}{
+		{Name: "at", Req: at(now.Add(time.Hour)), Expectation: time.Date(2021, 5, 4, 11, 0, 0, 0, time.UTC)},
+		{Name: "at in other zone", Req: at(time.Date(2021, 5, 4, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))), Expectation: time.Date(2021, 5, 4, 10, 30, 0, 0, time.UTC)},
+		{Name: "duration", Req: &api.ScheduleStopRequest{Duration: "90m"}, Expectation: time.Date(2021, 5, 4, 11, 30, 0, 0, time.UTC)},
+		{Name: "at and duration", Req: &api.ScheduleStopRequest{At: at(now.Add(time.Hour)).At, Duration: "5m"}, Error: true},
+		{Name: "neither at nor duration", Req: &api.ScheduleStopRequest{}, Error: true},
+		{Name: "invalid duration", Req: &api.ScheduleStopRequest{Duration: "soon"}, Error: true},
+		{Name: "past", Req: at(now.Add(-time.Minute)), Error: true},
+		{Name: "negative duration", Req: &api.ScheduleStopRequest{Duration: "-5m"}, Error: true},
+		{Name: "now", Req: &api.ScheduleStopRequest{Duration: "0s"}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := scheduledStopDeadline(test.Req, now)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !act.Equal(test.Expectation) {
+				t.Errorf("unexpected deadline: expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestGetScheduledStop(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Annotations map[string]string
+		Expectation *time.Time
+	}{
+		{Name: "no annotation"},
+		{Name: "invalid annotation", Annotations: map[string]string{scheduledStopAnnotation: "tomorrow"}},
+		{Name: "valid annotation", Annotations: map[string]string{scheduledStopAnnotation: "2021-05-04T10:00:00Z"}, Expectation: func() *time.Time {
+			t := time.Date(2021, 5, 4, 10, 0, 0, 0, time.UTC)
+			return &t
+		}()},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, ok := getScheduledStop(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: test.Annotations}})
+			if ok != (test.Expectation != nil) {
+				t.Fatalf("unexpected ok: %v", ok)
+			}
+			if ok && !act.Equal(*test.Expectation) {
+				t.Errorf("unexpected deadline: expected %v, got %v", *test.Expectation, act)
+			}
+		})
+	}
+}
+
+// withSupervisor makes the test server the supervisor of a pod, which we reach on the pod IP and the port of the readiness probe
+func withSupervisor(t *testing.T, pod *corev1.Pod, srv *httptest.Server) *corev1.Pod {
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	pod.Status.PodIP = host
+	pod.Spec.Containers = []corev1.Container{{
+		Name: "workspace",
+		ReadinessProbe: &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{
+			Path: "/_supervisor/v1/status/content/wait/true",
+			Port: intstr.FromInt(p),
+		}}},
+	}}
+	return pod
+}
+
+func TestSupervisorScheduledStop(t *testing.T) {
+	var state supervisorScheduledStop
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != supervisorScheduledStopPath {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Gitpod-Owner-Token") != "" {
+			http.Error(w, "we must not send the owner token", http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodPut {
+			state = supervisorScheduledStop{}
+			err := json.NewDecoder(r.Body).Decode(&state)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(state)
+	}))
+	defer srv.Close()
+
+	pod := withSupervisor(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ws-foo", Annotations: map[string]string{
+		workspaceURLAnnotation: "https://foo.ws.example.com",
+		ownerTokenAnnotation:   "owner-token",
+	}}}, srv)
+	ctx := context.Background()
+
+	deadline := time.Date(2021, 5, 4, 10, 0, 0, 0, time.UTC)
+	err := putSupervisorScheduledStop(ctx, pod, deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := getSupervisorScheduledStop(ctx, pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&supervisorScheduledStop{Deadline: "2021-05-04T10:00:00Z"}, act); diff != "" {
+		t.Errorf("unexpected scheduled stop (-want +got):\n%s", diff)
+	}
+
+	err = putSupervisorScheduledStop(ctx, pod, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err = getSupervisorScheduledStop(ctx, pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&supervisorScheduledStop{}, act); diff != "" {
+		t.Errorf("scheduled stop was not cancelled (-want +got):\n%s", diff)
+	}
+
+	pod.Status.PodIP = ""
+	_, err = getSupervisorScheduledStop(ctx, pod)
+	if err == nil {
+		t.Errorf("expected error without pod IP")
+	}
+}
+
+func TestScheduledStopReasons(t *testing.T) {
+	const (
+		due   = 4 * maxConcurrentScheduledStopChecks
+		delay = 100 * time.Millisecond
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a slow supervisor which knows nothing about a cancellation
+		time.Sleep(delay)
+		_ = json.NewEncoder(w).Encode(supervisorScheduledStop{})
+	}))
+	defer srv.Close()
+
+	past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	var pods []corev1.Pod
+	for i := 0; i < due; i++ {
+		pods = append(pods, *withSupervisor(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws-due-" + strconv.Itoa(i),
+			Annotations: map[string]string{scheduledStopAnnotation: past},
+		}}, srv))
+	}
+	pods = append(pods,
+		*withSupervisor(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ws-later", Annotations: map[string]string{scheduledStopAnnotation: future}}}, srv),
+		*withSupervisor(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ws-timedout", Annotations: map[string]string{scheduledStopAnnotation: past, workspaceTimedOutAnnotation: "timed out"}}}, srv),
+		*withSupervisor(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ws-none"}}, srv),
+	)
+
+	start := time.Now()
+	act := (&Monitor{}).scheduledStopReasons(context.Background(), pods)
+	if elapsed := time.Since(start); elapsed >= due*delay/2 {
+		t.Errorf("expected supervisors to be asked concurrently, took %s", elapsed)
+	}
+	if len(act) != due {
+		t.Errorf("expected %d scheduled stops, got %d: %v", due, len(act), act)
+	}
+	for _, name := range []string{"ws-later", "ws-timedout", "ws-none"} {
+		if reason, ok := act[name]; ok {
+			t.Errorf("%s must not stop as scheduled, got %q", name, reason)
+		}
+	}
+}
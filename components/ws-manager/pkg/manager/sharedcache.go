@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"path/filepath"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// sharedCacheVolumeName is the name of the shared cache volume
+	sharedCacheVolumeName = "vol-shared-cache"
+	// sharedCacheDir is the path within the workspace container where the shared cache is mounted to
+	sharedCacheDir = "/.gitpod-shared-cache"
+)
+
+// SharedCacheConfig mounts a directory of the node into regular and prebuild workspaces, which supervisor
+// uses as dependency cache that all workspaces on the node share.
+type SharedCacheConfig struct {
+	// HostPath is the directory on the node which holds the shared cache. The workspace user must be able to write to it.
+	HostPath string `json:"hostPath"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *SharedCacheConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.HostPath, validation.Required, validation.By(func(o interface{}) error {
+			if !filepath.IsAbs(o.(string)) {
+				return xerrors.Errorf("must be an absolute path")
+			}
+			return nil
+		})),
+	)
+}
+
+// hasSharedCache returns true if a workspace gets the shared cache
+func (m *Manager) hasSharedCache(startContext *startWorkspaceContext) bool {
+	if m.Config.SharedCache == nil {
+		return false
+	}
+	tpe := startContext.Request.Type
+	return tpe == api.WorkspaceType_REGULAR || tpe == api.WorkspaceType_PREBUILD
+}
+
+// addSharedCache mounts the shared cache into the workspace container of a pod
+func (m *Manager) addSharedCache(pod *corev1.Pod, startContext *startWorkspaceContext) {
+	if !m.hasSharedCache(startContext) {
+		return
+	}
+
+	hostPathOrCreate := corev1.HostPathDirectoryOrCreate
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: sharedCacheVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: m.Config.SharedCache.HostPath,
+				Type: &hostPathOrCreate,
+			},
+		},
+	})
+	for i, c := range pod.Spec.Containers {
+		if c.Name != "workspace" {
+			continue
+		}
+		pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      sharedCacheVolumeName,
+			MountPath: sharedCacheDir,
+		})
+		break
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gitpod-io/gitpod/common-go/imageref"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// ConfigChangeReport describes how a configuration change affects a sample of workspaces
+type ConfigChangeReport struct {
+	Workspaces []WorkspaceChange `json:"workspaces"`
+}
+
+// Changed returns the number of workspaces the configuration change affects
+func (r *ConfigChangeReport) Changed() int {
+	var n int
+	for _, ws := range r.Workspaces {
+		if ws.Changed() {
+			n++
+		}
+	}
+	return n
+}
+
+// WorkspaceChange describes how a configuration change affects a single workspace
+type WorkspaceChange struct {
+	ID     string `json:"id"`
+	MetaID string `json:"metaId"`
+	Type   string `json:"type"`
+
+	// Values are the URLs, images and pod templates that differ between the configurations
+	Values []ValueChange `json:"values,omitempty"`
+	// PodDiff is the difference between the workspace pods, empty if they're the same
+	PodDiff string `json:"podDiff,omitempty"`
+
+	// CurrentError is set if we cannot produce the workspace with the current configuration
+	CurrentError string `json:"currentError,omitempty"`
+	// ProposedError is set if we cannot produce the workspace with the proposed configuration
+	ProposedError string `json:"proposedError,omitempty"`
+}
+
+// Changed returns true if the configuration change affects the workspace
+func (c *WorkspaceChange) Changed() bool {
+	return len(c.Values) > 0 || c.PodDiff != "" || c.CurrentError != c.ProposedError
+}
+
+// ValueChange is a value that differs between the configurations
+type ValueChange struct {
+	Name     string `json:"name"`
+	Current  string `json:"current"`
+	Proposed string `json:"proposed"`
+}
+
+// SimulateConfigChange produces the workspaces in sample with the current and the proposed configuration and reports
+// the differences. It does not talk to Kubernetes: ingress ports are assumed to be the workspace ports, and the
+// tokens ws-manager generates for each workspace are the same for both configurations.
+func SimulateConfigChange(current, proposed Configuration, sample []*api.StartWorkspaceRequest) (*ConfigChangeReport, error) {
+	cm, err := newSimulationManager(current)
+	if err != nil {
+		return nil, xerrors.Errorf("current configuration: %w", err)
+	}
+	pm, err := newSimulationManager(proposed)
+	if err != nil {
+		return nil, xerrors.Errorf("proposed configuration: %w", err)
+	}
+
+	res := &ConfigChangeReport{Workspaces: make([]WorkspaceChange, 0, len(sample))}
+	for _, req := range sample {
+		change := WorkspaceChange{
+			ID:   req.Id,
+			Type: strings.ToLower(api.WorkspaceType_name[int32(req.Type)]),
+		}
+		if req.Metadata != nil {
+			change.MetaID = req.Metadata.MetaId
+		}
+
+		cur, curErr := cm.simulateWorkspace(req)
+		if curErr != nil {
+			change.CurrentError = curErr.Error()
+		}
+		prop, propErr := pm.simulateWorkspace(req)
+		if propErr != nil {
+			change.ProposedError = propErr.Error()
+		}
+		if curErr == nil && propErr == nil {
+			change.Values = diffSimulatedValues(cur.Values, prop.Values)
+			change.PodDiff, err = diffPods(cur.Pod, prop.Pod)
+			if err != nil {
+				return nil, xerrors.Errorf("cannot compare pods of %s: %w", req.Id, err)
+			}
+		}
+		res.Workspaces = append(res.Workspaces, change)
+	}
+	return res, nil
+}
+
+// newSimulationManager creates a manager which can produce workspace pods, but cannot talk to Kubernetes
+func newSimulationManager(config Configuration) (*Manager, error) {
+	imageRewriter, err := imageref.NewRewriter(config.ImageRewrite)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid image rewrite rules: %w", err)
+	}
+	return &Manager{
+		Config:        config,
+		imageRewriter: imageRewriter,
+		podTemplates:  newPodTemplateStore(),
+	}, nil
+}
+
+type simulatedWorkspace struct {
+	Pod    *corev1.Pod
+	Values []ValueChange
+}
+
+// simulateWorkspace produces everything StartWorkspace would derive from the configuration for a workspace
+func (m *Manager) simulateWorkspace(req *api.StartWorkspaceRequest) (*simulatedWorkspace, error) {
+	err := validateStartWorkspaceRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	startContext, err := m.newStartWorkspaceContext(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	// tie down values which would otherwise differ between the configurations
+	startContext.CLIAPIKey = "cli-api-key"
+	startContext.OwnerToken = "owner-token"
+	startContext.TraceID = ""
+
+	pod, err := m.createWorkspacePod(startContext)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []ValueChange{
+		{Name: "url", Current: startContext.WorkspaceURL},
+		{Name: "previewDnsHostname", Current: startContext.PreviewDNSHostname},
+		{Name: "podTemplates", Current: m.podTemplatePaths(req.Type)},
+		{Name: "workspaceImage", Current: m.imageRewriter.Rewrite(req.Spec.WorkspaceImage)},
+		{Name: "ideImage", Current: m.imageRewriter.Rewrite(req.Spec.IdeImage)},
+	}
+	for _, p := range req.Spec.Ports {
+		url, err := renderWorkspacePortURL(m.Config.WorkspacePortURLTemplate, wsurl.TemplateContext{
+			Host:          m.Config.GitpodHostURL,
+			ID:            req.Metadata.MetaId,
+			IngressPort:   fmt.Sprint(p.Port),
+			Prefix:        getServicePrefix(req),
+			WorkspacePort: fmt.Sprint(p.Port),
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("cannot render public URL for %d: %w", p.Port, err)
+		}
+		values = append(values, ValueChange{Name: fmt.Sprintf("portUrl/%d", p.Port), Current: url})
+	}
+
+	return &simulatedWorkspace{Pod: pod, Values: values}, nil
+}
+
+// podTemplatePaths lists the pod templates which apply to a workspace type
+func (m *Manager) podTemplatePaths(tpe api.WorkspaceType) string {
+	tpls := m.Config.WorkspacePodTemplate
+	paths := []string{tpls.DefaultPath}
+	switch tpe {
+	case api.WorkspaceType_REGULAR:
+		paths = append(paths, tpls.RegularPath)
+	case api.WorkspaceType_PREBUILD:
+		paths = append(paths, tpls.PrebuildPath)
+	case api.WorkspaceType_PROBE:
+		paths = append(paths, tpls.ProbePath)
+	case api.WorkspaceType_GHOST:
+		paths = append(paths, tpls.GhostPath)
+	}
+
+	var res []string
+	for _, p := range paths {
+		if p != "" {
+			res = append(res, p)
+		}
+	}
+	return strings.Join(res, ", ")
+}
+
+// diffSimulatedValues returns the values which differ. Both lists must have been produced from the same request.
+func diffSimulatedValues(current, proposed []ValueChange) []ValueChange {
+	var res []ValueChange
+	for i := range current {
+		if current[i].Current == proposed[i].Current {
+			continue
+		}
+		res = append(res, ValueChange{Name: current[i].Name, Current: current[i].Current, Proposed: proposed[i].Current})
+	}
+	return res
+}
+
+// diffPods compares the pods in their serialized form, which is what Kubernetes gets to see
+func diffPods(current, proposed *corev1.Pod) (string, error) {
+	toMap := func(pod *corev1.Pod) (res map[string]interface{}, err error) {
+		raw, err := json.Marshal(pod)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(raw, &res)
+		return res, err
+	}
+
+	cur, err := toMap(current)
+	if err != nil {
+		return "", err
+	}
+	prop, err := toMap(proposed)
+	if err != nil {
+		return "", err
+	}
+	return cmp.Diff(cur, prop), nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/common-go/imageref"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestSimulateConfigChange(t *testing.T) {
+	fs = fstest.MapFS{
+		"default-template.yaml": &fstest.MapFile{Data: []byte("spec:\n  dnsPolicy: None\n")},
+		"regular-template.yaml": &fstest.MapFile{Data: []byte("metadata:\n  labels:\n    pool: workspaces\n")},
+	}
+
+	current := Configuration{
+		Namespace:                "default",
+		SchedulerName:            "workspace-scheduler",
+		HeartbeatInterval:        util.Duration(30 * time.Second),
+		WorkspaceHostPath:        "/tmp/workspaces",
+		GitpodHostURL:            "gitpod.io",
+		WorkspaceURLTemplate:     "https://{{ .Prefix }}.ws.{{ .Host }}",
+		WorkspacePortURLTemplate: "https://{{ .WorkspacePort }}-{{ .Prefix }}.ws.{{ .Host }}",
+		RegistryFacadeHost:       "registry-facade:8080",
+		WorkspacePodTemplate:     WorkspacePodTemplateConfiguration{DefaultPath: "default-template.yaml"},
+		Container: AllContainerConfiguration{
+			Workspace: ContainerConfiguration{
+				Image:    "workspace-image",
+				Limits:   ResourceConfiguration{CPU: "900m", Memory: "1000M"},
+				Requests: ResourceConfiguration{CPU: "899m", Memory: "999M"},
+			},
+		},
+	}
+	proposed := current
+	proposed.WorkspacePortURLTemplate = "https://{{ .WorkspacePort }}-{{ .Prefix }}.ws-eu.{{ .Host }}"
+	proposed.WorkspacePodTemplate.RegularPath = "regular-template.yaml"
+	proposed.ImageRewrite = []imageref.RewriteRule{{Pattern: `docker\.io/(.*)`, Replacement: "mirror.example.com/${1}"}}
+
+	request := func(id string, tpe api.WorkspaceType) *api.StartWorkspaceRequest {
+		return &api.StartWorkspaceRequest{
+			Id:            id,
+			Type:          tpe,
+			ServicePrefix: id + "-prefix",
+			Metadata:      &api.WorkspaceMetadata{Owner: "tester", MetaId: id + "-meta"},
+			Spec: &api.StartWorkspaceSpec{
+				WorkspaceImage:    "eu.gcr.io/gitpod/workspace:latest",
+				IdeImage:          "docker.io/gitpod/ide:latest",
+				CheckoutLocation:  "gitpod",
+				WorkspaceLocation: "gitpod",
+				Initializer:       &csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Empty{Empty: &csapi.EmptyInitializer{}}},
+				Ports:             []*api.PortSpec{{Port: 8080}},
+			},
+		}
+	}
+	report, err := SimulateConfigChange(current, proposed, []*api.StartWorkspaceRequest{
+		request("regular", api.WorkspaceType_REGULAR),
+		request("prebuild", api.WorkspaceType_PREBUILD),
+		{Id: "invalid", Spec: &api.StartWorkspaceSpec{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Workspaces) != 3 {
+		t.Fatalf("expected a result for each workspace, got %d", len(report.Workspaces))
+	}
+
+	regular := report.Workspaces[0]
+	expectation := []ValueChange{
+		{Name: "podTemplates", Current: "default-template.yaml", Proposed: "default-template.yaml, regular-template.yaml"},
+		{Name: "ideImage", Current: "docker.io/gitpod/ide:latest", Proposed: "mirror.example.com/gitpod/ide:latest"},
+		{Name: "portUrl/8080", Current: "https://8080-regular-prefix.ws.gitpod.io", Proposed: "https://8080-regular-prefix.ws-eu.gitpod.io"},
+	}
+	if diff := cmp.Diff(expectation, regular.Values); diff != "" {
+		t.Errorf("unexpected value changes of the regular workspace (-want +got):\n%s", diff)
+	}
+	if !strings.Contains(regular.PodDiff, "workspaces") {
+		t.Errorf("expected the pod diff to show the label of the regular template:\n%s", regular.PodDiff)
+	}
+
+	// the prebuild is not affected by the regular template, but by the image rewrite
+	prebuild := report.Workspaces[1]
+	if prebuild.PodDiff != "" {
+		t.Errorf("expected the prebuild pod to stay the same:\n%s", prebuild.PodDiff)
+	}
+	if len(prebuild.Values) != 2 || prebuild.Values[0].Name != "ideImage" {
+		t.Errorf("unexpected value changes of the prebuild: %v", prebuild.Values)
+	}
+
+	invalid := report.Workspaces[2]
+	if invalid.CurrentError == "" || invalid.CurrentError != invalid.ProposedError || invalid.Changed() {
+		t.Errorf("expected an invalid request to fail the same way with both configurations: %+v", invalid)
+	}
+	if report.Changed() != 2 {
+		t.Errorf("expected two changed workspaces, got %d", report.Changed())
+	}
+}
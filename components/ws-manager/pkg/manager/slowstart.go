@@ -0,0 +1,220 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultSlowStartBurst   = 1
+	defaultSlowStartMaxWait = 10 * time.Second
+)
+
+const (
+	slowStartAdmitted  = "admitted"
+	slowStartRejected  = "rejected"
+	slowStartCancelled = "cancelled"
+)
+
+// SlowStartConfig configures how many workspaces we start per minute after ws-manager started or a maintenance ended.
+// Right after an outage everyone retries at once, and admitting all of them would pull images, hit ws-daemon and
+// the Kubernetes API hard enough to cause the next outage.
+type SlowStartConfig struct {
+	// InitialRate is the number of workspace starts per minute we admit right after ws-manager started
+	InitialRate float64 `json:"initialRate"`
+	// FinalRate is the number of workspace starts per minute we admit at the end of the ramp-up
+	FinalRate float64 `json:"finalRate"`
+	// RampUp is the time in which the rate grows linearly from InitialRate to FinalRate. Afterwards we no longer limit starts.
+	RampUp util.Duration `json:"rampUp"`
+	// Burst is the number of starts we admit at once. Defaults to 1.
+	Burst int `json:"burst,omitempty"`
+	// MaxWait is the time a start may wait for admission before we reject it. Defaults to 10 seconds.
+	MaxWait util.Duration `json:"maxWait,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *SlowStartConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.InitialRate, validation.Min(0.0)),
+		validation.Field(&c.FinalRate, validation.Required, validation.Min(0.0)),
+		validation.Field(&c.RampUp, validation.Required, validation.Min(util.Duration(0))),
+		validation.Field(&c.Burst, validation.Min(0)),
+		validation.Field(&c.MaxWait, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return err
+	}
+	if c.FinalRate < c.InitialRate {
+		return xerrors.Errorf("finalRate must not be less than initialRate")
+	}
+	return nil
+}
+
+func (c *SlowStartConfig) burst() float64 {
+	if c.Burst == 0 {
+		return defaultSlowStartBurst
+	}
+	return float64(c.Burst)
+}
+
+func (c *SlowStartConfig) maxWait() time.Duration {
+	if c.MaxWait == 0 {
+		return defaultSlowStartMaxWait
+	}
+	return time.Duration(c.MaxWait)
+}
+
+// slowStart is a token bucket whose rate grows over the ramp-up
+type slowStart struct {
+	Config SlowStartConfig
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+
+	mu     sync.Mutex
+	begin  time.Time
+	last   time.Time
+	tokens float64
+}
+
+func newSlowStart(cfg *SlowStartConfig) *slowStart {
+	if cfg == nil {
+		return nil
+	}
+	res := &slowStart{
+		Config: *cfg,
+		now:    time.Now,
+		after:  time.After,
+	}
+	res.Restart()
+	return res
+}
+
+// Restart begins a new ramp-up, e.g. because the cluster recovered from a maintenance
+func (s *slowStart) Restart() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.begin = s.now()
+	s.last = s.begin
+	s.tokens = s.Config.burst()
+}
+
+// Rate returns the number of starts per minute we currently admit. It returns a negative number once the ramp-up is over.
+func (s *slowStart) Rate() float64 {
+	if s == nil {
+		return -1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rate(s.now()) * 60
+}
+
+// rate returns the starts per second we admit at t, or a negative number once the ramp-up is over
+func (s *slowStart) rate(t time.Time) float64 {
+	elapsed := t.Sub(s.begin)
+	rampUp := time.Duration(s.Config.RampUp)
+	if elapsed >= rampUp {
+		return -1
+	}
+	progress := float64(elapsed) / float64(rampUp)
+	return (s.Config.InitialRate + (s.Config.FinalRate-s.Config.InitialRate)*progress) / 60
+}
+
+// reserve takes a token and returns how long its holder has to wait before it may start a workspace.
+// If that's longer than maxWait we don't take the token and ok is false.
+func (s *slowStart) reserve() (wait time.Duration, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	rate := s.rate(now)
+	if rate < 0 {
+		return 0, true
+	}
+	s.tokens = math.Min(s.Config.burst(), s.tokens+now.Sub(s.last).Seconds()*rate)
+	s.last = now
+
+	// the rate only grows during the ramp-up, hence the wait we compute here is an upper bound
+	tokens := s.tokens - 1
+	if tokens < 0 {
+		if rate == 0 {
+			return s.Config.maxWait() + time.Second, false
+		}
+		wait = time.Duration(-tokens / rate * float64(time.Second))
+	}
+	if wait > s.Config.maxWait() {
+		return wait, false
+	}
+	s.tokens = tokens
+	return wait, true
+}
+
+// cancel returns a token we reserved but did not use
+func (s *slowStart) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = math.Min(s.Config.burst(), s.tokens+1)
+}
+
+// Admit waits until we may start another workspace. It returns the outcome if the start had to wait,
+// and a capacity error if the start would have to wait longer than maxWait.
+func (s *slowStart) Admit(ctx context.Context) (outcome string, err error) {
+	if s == nil {
+		return "", nil
+	}
+
+	wait, ok := s.reserve()
+	if !ok {
+		retryAfter := wait.Round(time.Second)
+		if retryAfter < time.Second {
+			retryAfter = time.Second
+		}
+		return slowStartRejected, newStartWorkspaceError(codes.Unavailable, api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY, retryAfter,
+			"The cluster is starting up. Please try again in a few seconds.",
+			xerrors.Errorf("cluster is warming up: next start possible in %s", wait))
+	}
+	if wait == 0 {
+		return "", nil
+	}
+
+	select {
+	case <-s.after(wait):
+		return slowStartAdmitted, nil
+	case <-ctx.Done():
+		s.cancel()
+		return slowStartCancelled, ctx.Err()
+	}
+}
+
+// admitWorkspaceStart defers a workspace start while the cluster is warming up
+func (m *Manager) admitWorkspaceStart(ctx context.Context, req *api.StartWorkspaceRequest) error {
+	outcome, err := m.slowStart.Admit(ctx)
+	if outcome != "" {
+		m.metrics.OnWorkspaceStartDeferred(outcome)
+		log.WithFields(log.OWI(req.Metadata.Owner, req.Metadata.MetaId, req.Id)).WithField("outcome", outcome).Debug("deferred workspace start during slow start")
+	}
+	return err
+}
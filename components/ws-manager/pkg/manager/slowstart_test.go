@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func newTestSlowStart(cfg SlowStartConfig) (*slowStart, *time.Time) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := newSlowStart(&cfg)
+	s.now = func() time.Time { return now }
+	s.after = func(time.Duration) <-chan time.Time {
+		c := make(chan time.Time, 1)
+		c <- now
+		return c
+	}
+	s.Restart()
+	return s, &now
+}
+
+func TestSlowStartReserve(t *testing.T) {
+	s, now := newTestSlowStart(SlowStartConfig{
+		InitialRate: 60,
+		FinalRate:   540,
+		RampUp:      util.Duration(10 * time.Minute),
+		Burst:       2,
+		MaxWait:     util.Duration(3 * time.Second),
+	})
+
+	type reservation struct {
+		Wait time.Duration
+		OK   bool
+	}
+	reserve := func() reservation {
+		wait, ok := s.reserve()
+		return reservation{Wait: wait.Round(time.Millisecond), OK: ok}
+	}
+
+	// the burst is available right away, then we admit one start per second
+	for i, exp := range []reservation{
+		{OK: true},
+		{OK: true},
+		{Wait: 1 * time.Second, OK: true},
+		{Wait: 2 * time.Second, OK: true},
+		{Wait: 3 * time.Second, OK: true},
+		{Wait: 4 * time.Second, OK: false},
+		{Wait: 4 * time.Second, OK: false},
+	} {
+		if act := reserve(); act != exp {
+			t.Errorf("reservation %d: got %+v, expected %+v", i, act, exp)
+		}
+	}
+
+	// halfway through the ramp-up we admit five starts per second
+	*now = now.Add(5 * time.Minute)
+	s.tokens = 0
+	s.last = *now
+	if act, exp := reserve(), (reservation{Wait: 200 * time.Millisecond, OK: true}); act != exp {
+		t.Errorf("halfway: got %+v, expected %+v", act, exp)
+	}
+
+	// once the ramp-up is over we do not limit starts at all
+	*now = now.Add(5 * time.Minute)
+	for i := 0; i < 100; i++ {
+		if act, exp := reserve(), (reservation{OK: true}); act != exp {
+			t.Fatalf("after ramp-up: got %+v, expected %+v", act, exp)
+		}
+	}
+	if rate := s.Rate(); rate >= 0 {
+		t.Errorf("expected no rate after the ramp-up, got %f", rate)
+	}
+
+	// a restart begins a new ramp-up
+	s.Restart()
+	if rate := s.Rate(); rate != 60 {
+		t.Errorf("expected a rate of 60 after restart, got %f", rate)
+	}
+}
+
+func TestSlowStartAdmit(t *testing.T) {
+	s, _ := newTestSlowStart(SlowStartConfig{
+		InitialRate: 6,
+		FinalRate:   60,
+		RampUp:      util.Duration(10 * time.Minute),
+		MaxWait:     util.Duration(15 * time.Second),
+	})
+	ctx := context.Background()
+
+	outcomes := make([]string, 0, 3)
+	var err error
+	for i := 0; i < 3; i++ {
+		var outcome string
+		outcome, err = s.Admit(ctx)
+		outcomes = append(outcomes, outcome)
+	}
+	if exp := []string{"", slowStartAdmitted, slowStartRejected}; outcomes[0] != exp[0] || outcomes[1] != exp[1] || outcomes[2] != exp[2] {
+		t.Errorf("unexpected outcomes %v, expected %v", outcomes, exp)
+	}
+
+	var swe *startWorkspaceError
+	if !xerrors.As(err, &swe) {
+		t.Fatalf("expected a start workspace error, got %v", err)
+	}
+	if swe.Code != codes.Unavailable || swe.Details.Domain != api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY || swe.Details.RetryAfter != "20s" {
+		t.Errorf("unexpected error %v with details %v", swe, swe.Details)
+	}
+
+	// a start whose client gave up does not use up a token
+	s.after = func(time.Duration) <-chan time.Time { return nil }
+	s.tokens = 0
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	outcome, err := s.Admit(cctx)
+	if outcome != slowStartCancelled || err == nil {
+		t.Errorf("expected the start to be cancelled, got %q, %v", outcome, err)
+	}
+	if s.tokens != 0 {
+		t.Errorf("expected the token to be returned, have %f tokens", s.tokens)
+	}
+}
+
+func TestSlowStartDisabled(t *testing.T) {
+	var s *slowStart
+	outcome, err := s.Admit(context.Background())
+	if outcome != "" || err != nil {
+		t.Errorf("expected a disabled slow start to admit everything, got %q, %v", outcome, err)
+	}
+	s.Restart()
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const defaultSoftDeleteInterval = 1 * time.Hour
+
+// SoftDeleteConfig keeps the backups of deleted workspaces for a grace period during which they can be restored
+type SoftDeleteConfig struct {
+	// StatePath is the file we keep the deleted workspaces in. It must be on a persistent volume,
+	// otherwise we forget about deleted workspaces and never purge their backups when ws-manager restarts.
+	StatePath string `json:"statePath"`
+	// GracePeriod is how long we keep the backup of a deleted workspace before we delete it for good
+	GracePeriod util.Duration `json:"gracePeriod"`
+	// Interval is how often we look for workspaces to purge. Defaults to one hour.
+	Interval util.Duration `json:"interval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *SoftDeleteConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.StatePath, validation.Required),
+		validation.Field(&c.GracePeriod, validation.Required, validation.Min(util.Duration(0))),
+		validation.Field(&c.Interval, validation.Min(util.Duration(0))),
+	)
+}
+
+// deletionRecord is a soft-deleted workspace whose backup we purge once the grace period ended
+type deletionRecord struct {
+	WorkspaceID string            `json:"workspaceId"`
+	Owner       string            `json:"owner"`
+	DeletedAt   time.Time         `json:"deletedAt"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// Archival is what the archiver knew about the workspace before it was deleted
+	Archival *archivalRecord `json:"archival,omitempty"`
+	// Purging is true while we delete the backup, at which point the workspace can no longer be restored
+	Purging bool `json:"purging,omitempty"`
+}
+
+// softDeleter keeps track of deleted workspaces and purges their backups once the grace period ended
+type softDeleter struct {
+	Config  SoftDeleteConfig
+	Storage storage.PresignedAccess
+	// OnPurged is called for every workspace whose backup we deleted for good
+	OnPurged func()
+
+	mu      sync.Mutex
+	records map[string]*deletionRecord
+
+	now    func() time.Time
+	cancel context.CancelFunc
+}
+
+// newSoftDeleter restores the deleted workspaces from the state file, or starts with none if the file does not exist
+func newSoftDeleter(cfg SoftDeleteConfig, s storage.PresignedAccess) (*softDeleter, error) {
+	if cfg.Interval == 0 {
+		cfg.Interval = util.Duration(defaultSoftDeleteInterval)
+	}
+
+	d := &softDeleter{
+		Config:  cfg,
+		Storage: s,
+		records: make(map[string]*deletionRecord),
+		now:     time.Now,
+	}
+
+	fc, err := os.ReadFile(cfg.StatePath)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read soft-delete state: %w", err)
+	}
+	var records []*deletionRecord
+	err = json.Unmarshal(fc, &records)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read soft-delete state: %w", err)
+	}
+	for _, r := range records {
+		d.records[r.WorkspaceID] = r
+	}
+	return d, nil
+}
+
+// persist writes the deleted workspaces to the state file. Callers must hold mu.
+func (d *softDeleter) persist() error {
+	records := make([]*deletionRecord, 0, len(d.records))
+	for _, r := range d.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].WorkspaceID < records[j].WorkspaceID })
+	err := writeStateFile(d.Config.StatePath, records)
+	if err != nil {
+		return xerrors.Errorf("cannot write soft-delete state: %w", err)
+	}
+	return nil
+}
+
+// purgeAt is when we delete the backup of a deleted workspace for good. Callers must hold mu.
+func (d *softDeleter) purgeAt(r *deletionRecord) time.Time {
+	return r.DeletedAt.Add(time.Duration(d.Config.GracePeriod))
+}
+
+// Delete soft-deletes a workspace. Deleting a workspace twice keeps the original grace period.
+func (d *softDeleter) Delete(r *deletionRecord) (purgeAt time.Time, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if cur, ok := d.records[r.WorkspaceID]; ok {
+		return d.purgeAt(cur), nil
+	}
+
+	r.DeletedAt = d.now()
+	d.records[r.WorkspaceID] = r
+	err = d.persist()
+	if err != nil {
+		delete(d.records, r.WorkspaceID)
+		return time.Time{}, err
+	}
+	return d.purgeAt(r), nil
+}
+
+// Restore undoes the deletion of a workspace whose backup we have not purged yet
+func (d *softDeleter) Restore(workspaceID string) (*deletionRecord, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	r, ok := d.records[workspaceID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "workspace %s is not deleted or has been purged already", workspaceID)
+	}
+	if r.Purging {
+		return nil, status.Errorf(codes.FailedPrecondition, "the grace period of workspace %s has ended and it is being purged", workspaceID)
+	}
+	delete(d.records, workspaceID)
+	err := d.persist()
+	if err != nil {
+		d.records[workspaceID] = r
+		return nil, err
+	}
+	return r, nil
+}
+
+// AdmitStart refuses to start deleted workspaces
+func (d *softDeleter) AdmitStart(workspaceID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.records[workspaceID]; !ok {
+		return nil
+	}
+	return newStartWorkspaceError(codes.FailedPrecondition, api.StartWorkspaceErrorDomain_START_ERROR_DELETED, 0,
+		"The workspace has been deleted. Restore it to start it again.",
+		xerrors.Errorf("workspace %s is deleted", workspaceID))
+}
+
+// Describe returns the deleted workspaces of owner, or all deleted workspaces if owner is empty
+func (d *softDeleter) Describe(owner string) []*api.DeletedWorkspace {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var res []*api.DeletedWorkspace
+	for _, r := range d.records {
+		if owner != "" && r.Owner != owner {
+			continue
+		}
+		res = append(res, d.describe(r))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].WorkspaceId < res[j].WorkspaceId })
+	return res
+}
+
+// describe converts a record to its API representation. Callers must hold mu.
+func (d *softDeleter) describe(r *deletionRecord) *api.DeletedWorkspace {
+	return &api.DeletedWorkspace{
+		WorkspaceId: r.WorkspaceID,
+		Owner:       r.Owner,
+		DeletedAt:   archivalTimestamp(r.DeletedAt),
+		PurgeAt:     archivalTimestamp(d.purgeAt(r)),
+		Metadata:    r.Metadata,
+	}
+}
+
+// Start purges the backups of deleted workspaces in the background until Close is called
+func (d *softDeleter) Start() {
+	var ctx context.Context
+	ctx, d.cancel = context.WithCancel(context.Background())
+	go d.Run(ctx)
+}
+
+// Close stops purging backups
+func (d *softDeleter) Close() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+}
+
+// Run purges the backups of deleted workspaces until ctx is canceled
+func (d *softDeleter) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(d.Config.Interval))
+	defer ticker.Stop()
+	for {
+		d.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge deletes the backups of deleted workspaces whose grace period ended. Workspaces we were purging
+// when ws-manager stopped are purged again.
+func (d *softDeleter) purge(ctx context.Context) {
+	d.mu.Lock()
+	var (
+		due []deletionRecord
+		now = d.now()
+	)
+	for _, r := range d.records {
+		if !now.Before(d.purgeAt(r)) {
+			r.Purging = true
+			due = append(due, *r)
+		}
+	}
+	if len(due) > 0 {
+		err := d.persist()
+		if err != nil {
+			log.WithError(err).Error("cannot persist soft-delete state")
+		}
+	}
+	d.mu.Unlock()
+
+	for _, r := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		owi := log.OWI(r.Owner, r.WorkspaceID, "")
+		err := d.Storage.DeleteObject(ctx, d.Storage.Bucket(r.Owner), &storage.DeleteObjectQuery{Prefix: storage.WorkspacePrefix(r.WorkspaceID)})
+		if err != nil && !xerrors.Is(err, storage.ErrNotFound) {
+			// we try again during the next round
+			log.WithError(err).WithFields(owi).Warn("cannot purge backup of deleted workspace")
+			continue
+		}
+
+		d.mu.Lock()
+		delete(d.records, r.WorkspaceID)
+		err = d.persist()
+		d.mu.Unlock()
+		if err != nil {
+			log.WithError(err).Error("cannot persist soft-delete state")
+		}
+		log.WithFields(owi).Info("purged backup of deleted workspace")
+
+		if d.OnPurged != nil {
+			d.OnPurged()
+		}
+	}
+}
+
+// DeleteWorkspace soft-deletes a stopped workspace
+func (m *Manager) DeleteWorkspace(ctx context.Context, req *api.DeleteWorkspaceRequest) (res *api.DeleteWorkspaceResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "DeleteWorkspace")
+	tracing.ApplyOWI(span, log.OWI(req.Owner, req.WorkspaceId, ""))
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "DeleteWorkspace", Metadata: &api.WorkspaceMetadata{MetaId: req.WorkspaceId, Owner: req.Owner}}, err)
+	}()
+
+	if m.softDeleter == nil {
+		return nil, status.Error(codes.Unimplemented, "soft-deletion is disabled")
+	}
+	if req.WorkspaceId == "" || req.Owner == "" {
+		return nil, status.Error(codes.InvalidArgument, "workspace ID and owner are required")
+	}
+
+	running, err := m.workspaceIDExists(ctx, req.WorkspaceId)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot check if the workspace is running: %v", err)
+	}
+	if running {
+		return nil, status.Errorf(codes.FailedPrecondition, "workspace %s is still running - stop it first", req.WorkspaceId)
+	}
+
+	r := &deletionRecord{
+		WorkspaceID: req.WorkspaceId,
+		Owner:       req.Owner,
+		Metadata:    req.Metadata,
+	}
+	if m.archiver != nil {
+		// we must not archive a backup we're about to purge, but want to remember its storage class should the workspace be restored
+		r.Archival, err = m.archiver.Forget(req.WorkspaceId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot delete workspace: %v", err)
+		}
+	}
+	purgeAt, err := m.softDeleter.Delete(r)
+	if err != nil {
+		if r.Archival != nil {
+			_ = m.archiver.Remember(r.Archival)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot delete workspace: %v", err)
+	}
+	m.metrics.OnWorkspaceSoftDeletion("deleted")
+	log.WithFields(log.OWI(req.Owner, req.WorkspaceId, "")).WithField("purgeAt", purgeAt).Info("soft-deleted workspace")
+
+	return &api.DeleteWorkspaceResponse{PurgeAt: archivalTimestamp(purgeAt)}, nil
+}
+
+// RestoreDeletedWorkspace undoes the deletion of a workspace whose grace period has not ended yet
+func (m *Manager) RestoreDeletedWorkspace(ctx context.Context, req *api.RestoreDeletedWorkspaceRequest) (res *api.RestoreDeletedWorkspaceResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "RestoreDeletedWorkspace")
+	span.SetTag("workspaceId", req.WorkspaceId)
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "RestoreDeletedWorkspace", Metadata: &api.WorkspaceMetadata{MetaId: req.WorkspaceId}}, err)
+	}()
+
+	if m.softDeleter == nil {
+		return nil, status.Error(codes.Unimplemented, "soft-deletion is disabled")
+	}
+	if req.WorkspaceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "workspace ID is required")
+	}
+
+	r, err := m.softDeleter.Restore(req.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	owi := log.OWI(r.Owner, r.WorkspaceID, "")
+	if m.archiver != nil && r.Archival != nil {
+		err = m.archiver.Remember(r.Archival)
+		if err != nil {
+			log.WithError(err).WithFields(owi).Error("cannot restore archival state of deleted workspace")
+		}
+	}
+	m.metrics.OnWorkspaceSoftDeletion("restored")
+	log.WithFields(owi).Info("restored deleted workspace")
+
+	m.softDeleter.mu.Lock()
+	desc := m.softDeleter.describe(r)
+	m.softDeleter.mu.Unlock()
+	return &api.RestoreDeletedWorkspaceResponse{Workspace: desc}, nil
+}
+
+// DescribeDeletedWorkspaces lists the soft-deleted workspaces which can still be restored
+func (m *Manager) DescribeDeletedWorkspaces(ctx context.Context, req *api.DescribeDeletedWorkspacesRequest) (res *api.DescribeDeletedWorkspacesResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "DescribeDeletedWorkspaces")
+	span.SetTag("owner", req.Owner)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.softDeleter == nil {
+		return nil, status.Error(codes.Unimplemented, "soft-deletion is disabled")
+	}
+	return &api.DescribeDeletedWorkspacesResponse{Workspaces: m.softDeleter.Describe(req.Owner)}, nil
+}
+
+// admitDeletedWorkspaceStart refuses to start workspaces which have been soft-deleted
+func (m *Manager) admitDeletedWorkspaceStart(req *api.StartWorkspaceRequest) error {
+	if m.softDeleter == nil || req.Metadata == nil {
+		return nil
+	}
+	return m.softDeleter.AdmitStart(req.Metadata.MetaId)
+}
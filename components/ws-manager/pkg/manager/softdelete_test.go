@@ -0,0 +1,200 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+type softDeleteTestStorage struct {
+	storage.PresignedNoopStorage
+
+	mu      sync.Mutex
+	deleted []string
+	fail    bool
+}
+
+func (s *softDeleteTestStorage) DeleteObject(ctx context.Context, bucket string, query *storage.DeleteObjectQuery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return xerrors.Errorf("storage unavailable")
+	}
+	s.deleted = append(s.deleted, bucket+"/"+query.Prefix)
+	return nil
+}
+
+func (s *softDeleteTestStorage) Deleted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.deleted...)
+}
+
+func newSoftDeleteTestDeleter(t *testing.T, fn string, s storage.PresignedAccess, now *time.Time) *softDeleter {
+	d, err := newSoftDeleter(SoftDeleteConfig{
+		StatePath:   fn,
+		GracePeriod: util.Duration(7 * 24 * time.Hour),
+	}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.now = func() time.Time { return *now }
+	return d
+}
+
+func TestSoftDeleter(t *testing.T) {
+	var (
+		fn  = filepath.Join(t.TempDir(), "softdelete.json")
+		s   = &softDeleteTestStorage{}
+		now = time.Now()
+		ctx = context.Background()
+	)
+	d := newSoftDeleteTestDeleter(t, fn, s, &now)
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	purgeAt, err := d.Delete(&deletionRecord{WorkspaceID: "ws-a", Owner: "owner", Metadata: map[string]string{"description": "my workspace"}})
+	must(err)
+	if exp := now.Add(7 * 24 * time.Hour); !purgeAt.Equal(exp) {
+		t.Errorf("expected workspace to be purged at %v, got %v", exp, purgeAt)
+	}
+	_, err = d.Delete(&deletionRecord{WorkspaceID: "ws-b", Owner: "other"})
+	must(err)
+
+	// deleting twice keeps the original grace period
+	now = now.Add(time.Hour)
+	again, err := d.Delete(&deletionRecord{WorkspaceID: "ws-a", Owner: "owner"})
+	must(err)
+	if !again.Equal(purgeAt) {
+		t.Errorf("expected repeated delete to keep purge time %v, got %v", purgeAt, again)
+	}
+
+	err = d.AdmitStart("ws-a")
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.FailedPrecondition {
+		t.Fatalf("expected deleted workspace start to fail with FailedPrecondition, got %v", err)
+	}
+	must(d.AdmitStart("ws-c"))
+
+	if res := d.Describe("owner"); len(res) != 1 || res[0].WorkspaceId != "ws-a" || res[0].Metadata["description"] != "my workspace" {
+		t.Fatalf("expected the deleted workspace of owner, got %v", res)
+	}
+	if res := d.Describe(""); len(res) != 2 {
+		t.Fatalf("expected all deleted workspaces, got %v", res)
+	}
+
+	// the deleted workspaces survive restarts
+	d = newSoftDeleteTestDeleter(t, fn, s, &now)
+	r, err := d.Restore("ws-a")
+	must(err)
+	if r.Owner != "owner" || r.Metadata["description"] != "my workspace" {
+		t.Errorf("expected restore to hand back the workspace as it was deleted, got %+v", r)
+	}
+	must(d.AdmitStart("ws-a"))
+	_, err = d.Restore("ws-a")
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+		t.Errorf("expected restored workspace to be NotFound, got %v", err)
+	}
+
+	d.purge(ctx)
+	if del := s.Deleted(); len(del) != 0 {
+		t.Fatalf("expected nothing to be purged during the grace period, got %v", del)
+	}
+
+	// failing to purge the backup keeps the workspace until the next attempt, but it cannot be restored anymore
+	now = now.Add(7 * 24 * time.Hour)
+	s.fail = true
+	d.purge(ctx)
+	if res := d.Describe(""); len(res) != 1 {
+		t.Fatalf("expected workspace to remain deleted, got %v", res)
+	}
+	_, err = d.Restore("ws-b")
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.FailedPrecondition {
+		t.Errorf("expected purging workspace restore to fail with FailedPrecondition, got %v", err)
+	}
+
+	s.fail = false
+	d.purge(ctx)
+	if del := s.Deleted(); len(del) != 1 || del[0] != s.Bucket("other")+"/"+storage.WorkspacePrefix("ws-b") {
+		t.Errorf("expected the backup of ws-b to be purged, got %v", del)
+	}
+	if res := d.Describe(""); len(res) != 0 {
+		t.Errorf("expected purged workspace to be forgotten, got %v", res)
+	}
+}
+
+func TestDeleteWorkspace(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		s   = &softDeleteTestStorage{}
+		now = time.Now()
+		ctx = context.Background()
+	)
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws-running",
+			Namespace: "default",
+			Labels: map[string]string{
+				markerLabel:       "true",
+				wsk8s.MetaIDLabel: "ws-running",
+			},
+		},
+	}
+	m := &Manager{
+		Config:      Configuration{Namespace: "default"},
+		Clientset:   fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(running).Build(),
+		archiver:    newArchivalTestArchiver(t, filepath.Join(dir, "archival.json"), &archivalTestStorage{classes: make(map[string]string)}, &now),
+		softDeleter: newSoftDeleteTestDeleter(t, filepath.Join(dir, "softdelete.json"), s, &now),
+	}
+	m.metrics = newMetrics(m)
+
+	_, err := m.DeleteWorkspace(ctx, &api.DeleteWorkspaceRequest{WorkspaceId: "ws-running", Owner: "owner"})
+	if st, ok := status.FromError(err); !ok || st.Code() != codes.FailedPrecondition {
+		t.Fatalf("expected deleting a running workspace to fail with FailedPrecondition, got %v", err)
+	}
+
+	err = m.archiver.Stopped("ws-a", "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.DeleteWorkspace(ctx, &api.DeleteWorkspaceRequest{WorkspaceId: "ws-a", Owner: "owner"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := m.archiver.Describe("ws-a"); len(res) != 0 {
+		t.Errorf("expected deleted workspace not to be archived, got %v", res)
+	}
+
+	res, err := m.RestoreDeletedWorkspace(ctx, &api.RestoreDeletedWorkspaceRequest{WorkspaceId: "ws-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Workspace.Owner != "owner" {
+		t.Errorf("expected restored workspace of owner, got %v", res.Workspace)
+	}
+	if res := m.archiver.Describe("ws-a"); len(res) != 1 || res[0].Phase != api.WorkspacePhase_STOPPED {
+		t.Errorf("expected restored workspace to be archived again eventually, got %v", res)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// quotaRetryAfter is how long we suggest to wait when the namespace's resource quota is exhausted.
+	// Quota frees up only once other workspaces stop, which takes a while.
+	quotaRetryAfter = 1 * time.Minute
+	// capacityRetryAfter is how long we suggest to wait when Kubernetes is overloaded and did not tell us
+	capacityRetryAfter = 5 * time.Second
+)
+
+// startWorkspaceError is a StartWorkspace failure with details callers can act on
+type startWorkspaceError struct {
+	Code    codes.Code
+	Details *api.StartWorkspaceErrorDetails
+	Err     error
+}
+
+func (e *startWorkspaceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *startWorkspaceError) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus makes the gRPC server send the error with its details
+func (e *startWorkspaceError) GRPCStatus() *status.Status {
+	st := status.New(e.Code, e.Err.Error())
+	res, err := st.WithDetails(e.Details)
+	if err != nil {
+		log.WithError(err).Warn("cannot attach details to StartWorkspace error")
+		return st
+	}
+	return res
+}
+
+func newStartWorkspaceError(code codes.Code, domain api.StartWorkspaceErrorDomain, retryAfter time.Duration, userMessage string, err error) error {
+	details := &api.StartWorkspaceErrorDetails{
+		Domain:      domain,
+		Retryable:   retryAfter > 0,
+		UserMessage: userMessage,
+	}
+	if retryAfter > 0 {
+		details.RetryAfter = retryAfter.String()
+	}
+	return &startWorkspaceError{Code: code, Details: details, Err: err}
+}
+
+// classifyStartWorkspaceError attaches details to a StartWorkspace failure. Errors which carry details already
+// keep them. created is true if we have created the workspace pod already, in which case sending the same
+// request again cannot succeed.
+func classifyStartWorkspaceError(err error, created bool) error {
+	if err == nil {
+		return nil
+	}
+	var swe *startWorkspaceError
+	if xerrors.As(err, &swe) {
+		return err
+	}
+
+	var (
+		code        = codes.Internal
+		domain      = api.StartWorkspaceErrorDomain_START_ERROR_UNKNOWN
+		retryAfter  time.Duration
+		userMessage = "Cannot start the workspace."
+	)
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		code = st.Code()
+	}
+
+	var apiStatus k8serr.APIStatus
+	if xerrors.As(err, &apiStatus) {
+		statusErr := &k8serr.StatusError{ErrStatus: apiStatus.Status()}
+		switch {
+		case k8serr.IsForbidden(statusErr) && strings.Contains(statusErr.Error(), "exceeded quota"):
+			code = codes.ResourceExhausted
+			domain = api.StartWorkspaceErrorDomain_START_ERROR_QUOTA
+			retryAfter = quotaRetryAfter
+			userMessage = "There are too many workspaces running at the moment. Please try again in a minute."
+		case k8serr.IsAlreadyExists(statusErr):
+			code = codes.AlreadyExists
+			domain = api.StartWorkspaceErrorDomain_START_ERROR_CONFLICT
+			userMessage = "The workspace is starting already."
+		case k8serr.IsInvalid(statusErr) || k8serr.IsBadRequest(statusErr):
+			code = codes.InvalidArgument
+			domain = api.StartWorkspaceErrorDomain_START_ERROR_INVALID_REQUEST
+		case k8serr.IsTooManyRequests(statusErr) || k8serr.IsServerTimeout(statusErr) || k8serr.IsTimeout(statusErr) ||
+			k8serr.IsServiceUnavailable(statusErr) || k8serr.IsInternalError(statusErr):
+			code = codes.Unavailable
+			domain = api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY
+			retryAfter = capacityRetryAfter
+			if s, ok := k8serr.SuggestsClientDelay(statusErr); ok && s > 0 {
+				retryAfter = time.Duration(s) * time.Second
+			}
+			userMessage = "The cluster is busy at the moment. Please try again in a few seconds."
+		}
+	} else {
+		switch code {
+		case codes.InvalidArgument:
+			domain = api.StartWorkspaceErrorDomain_START_ERROR_INVALID_REQUEST
+		case codes.AlreadyExists:
+			domain = api.StartWorkspaceErrorDomain_START_ERROR_CONFLICT
+			userMessage = "The workspace is starting already."
+		case codes.ResourceExhausted:
+			domain = api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY
+			retryAfter = capacityRetryAfter
+			userMessage = "The cluster is busy at the moment. Please try again in a few seconds."
+		}
+	}
+	if created {
+		retryAfter = 0
+	}
+	return newStartWorkspaceError(code, domain, retryAfter, userMessage, err)
+}
+
+// errStartWorkspaceImage fails a workspace start because of its image
+func errStartWorkspaceImage(userMessage string, err error) error {
+	return newStartWorkspaceError(codes.FailedPrecondition, api.StartWorkspaceErrorDomain_START_ERROR_IMAGE, 0, userMessage, err)
+}
+
+// errStartWorkspaceInvalid fails a workspace start because of an invalid request
+func errStartWorkspaceInvalid(err error) error {
+	return newStartWorkspaceError(codes.InvalidArgument, api.StartWorkspaceErrorDomain_START_ERROR_INVALID_REQUEST, 0, fmt.Sprintf("Cannot start the workspace: %s", err), err)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestClassifyStartWorkspaceError(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		Name       string
+		Err        error
+		Created    bool
+		Code       codes.Code
+		Domain     api.StartWorkspaceErrorDomain
+		RetryAfter string
+	}{
+		{
+			Name:       "quota",
+			Err:        xerrors.Errorf("cannot create pod: %w", k8serr.NewForbidden(pods, "ws-foo", xerrors.Errorf("exceeded quota: workspaces, requested: pods=1, used: pods=100, limited: pods=100"))),
+			Code:       codes.ResourceExhausted,
+			Domain:     api.StartWorkspaceErrorDomain_START_ERROR_QUOTA,
+			RetryAfter: "1m0s",
+		},
+		{
+			Name:   "forbidden",
+			Err:    k8serr.NewForbidden(pods, "ws-foo", xerrors.Errorf("not allowed")),
+			Code:   codes.Internal,
+			Domain: api.StartWorkspaceErrorDomain_START_ERROR_UNKNOWN,
+		},
+		{
+			Name:       "too many requests",
+			Err:        k8serr.NewTooManyRequests("slow down", 3),
+			Code:       codes.Unavailable,
+			Domain:     api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY,
+			RetryAfter: "3s",
+		},
+		{
+			Name:       "server timeout",
+			Err:        k8serr.NewServerTimeout(pods, "create", 0),
+			Code:       codes.Unavailable,
+			Domain:     api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY,
+			RetryAfter: "5s",
+		},
+		{
+			Name:    "capacity after the pod was created",
+			Err:     k8serr.NewServerTimeout(pods, "create", 0),
+			Created: true,
+			Code:    codes.Unavailable,
+			Domain:  api.StartWorkspaceErrorDomain_START_ERROR_CAPACITY,
+		},
+		{
+			Name:   "exists",
+			Err:    k8serr.NewAlreadyExists(pods, "ws-foo"),
+			Code:   codes.AlreadyExists,
+			Domain: api.StartWorkspaceErrorDomain_START_ERROR_CONFLICT,
+		},
+		{
+			Name:   "gRPC status",
+			Err:    status.Error(codes.AlreadyExists, "workspace exists already"),
+			Code:   codes.AlreadyExists,
+			Domain: api.StartWorkspaceErrorDomain_START_ERROR_CONFLICT,
+		},
+		{
+			Name:   "invalid",
+			Err:    errStartWorkspaceInvalid(xerrors.Errorf("id is required")),
+			Code:   codes.InvalidArgument,
+			Domain: api.StartWorkspaceErrorDomain_START_ERROR_INVALID_REQUEST,
+		},
+		{
+			Name:   "image",
+			Err:    errStartWorkspaceImage("image is built for arm64", xerrors.Errorf("incompatible")),
+			Code:   codes.FailedPrecondition,
+			Domain: api.StartWorkspaceErrorDomain_START_ERROR_IMAGE,
+		},
+		{
+			Name:   "unknown",
+			Err:    xerrors.Errorf("something went wrong"),
+			Code:   codes.Internal,
+			Domain: api.StartWorkspaceErrorDomain_START_ERROR_UNKNOWN,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := classifyStartWorkspaceError(test.Err, test.Created)
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("unexpected code: want %s, got %s", test.Code, code)
+			}
+
+			details := api.GetStartWorkspaceErrorDetails(err)
+			if details == nil {
+				t.Fatal("expected error details")
+			}
+			type result struct {
+				Domain     api.StartWorkspaceErrorDomain
+				Retryable  bool
+				RetryAfter string
+			}
+			exp := result{Domain: test.Domain, Retryable: test.RetryAfter != "", RetryAfter: test.RetryAfter}
+			act := result{Domain: details.Domain, Retryable: details.Retryable, RetryAfter: details.RetryAfter}
+			if diff := cmp.Diff(exp, act); diff != "" {
+				t.Errorf("unexpected details (-want +got):\n%s", diff)
+			}
+			if details.UserMessage == "" {
+				t.Error("expected a user message")
+			}
+		})
+	}
+}
+
+func TestStartWorkspaceErrorRoundTrip(t *testing.T) {
+	err := classifyStartWorkspaceError(k8serr.NewTooManyRequests("slow down", 2), false)
+
+	// this is what the client sees after the status went over the wire
+	st, _ := status.FromError(err)
+	received := status.FromProto(st.Proto()).Err()
+
+	details := api.GetStartWorkspaceErrorDetails(received)
+	if details == nil {
+		t.Fatal("expected error details")
+	}
+	if d := details.RetryAfterDuration(); d != 2*time.Second {
+		t.Errorf("unexpected retry after: %s", d)
+	}
+	if api.GetStartWorkspaceErrorDetails(xerrors.Errorf("plain error")) != nil {
+		t.Error("expected no details for plain errors")
+	}
+}
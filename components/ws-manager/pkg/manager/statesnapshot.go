@@ -0,0 +1,371 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// stateSnapshotVersion is the format version of the state snapshots we produce. Bump it whenever the format changes
+// in a way older ws-manager cannot make sense of.
+const stateSnapshotVersion = 1
+
+// stateSnapshot is the workspace state ws-manager keeps in memory only, i.e. everything we lose when ws-manager
+// goes away and which Kubernetes does not know about.
+type stateSnapshot struct {
+	Version   uint32    `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	Instances         []snapshotInstance         `json:"instances,omitempty"`
+	PendingOperations []snapshotPendingOperation `json:"pendingOperations,omitempty"`
+	Maintenance       *snapshotMaintenanceStatus `json:"maintenance,omitempty"`
+}
+
+type snapshotInstance struct {
+	ID           string                   `json:"id"`
+	LastActivity *time.Time               `json:"lastActivity,omitempty"`
+	Generation   uint64                   `json:"generation,omitempty"`
+	Proxies      map[string]proxyActivity `json:"proxies,omitempty"`
+}
+
+type snapshotPendingOperation struct {
+	InstanceID string                   `json:"instanceId"`
+	Kind       api.PendingOperationKind `json:"kind"`
+}
+
+type snapshotMaintenanceStatus struct {
+	Message  string     `json:"message,omitempty"`
+	StartsAt *time.Time `json:"startsAt,omitempty"`
+	EndsAt   *time.Time `json:"endsAt,omitempty"`
+}
+
+// ExportState produces a snapshot of the workspace state we keep in memory
+func (m *Manager) ExportState(ctx context.Context, req *api.ExportStateRequest) (res *api.ExportStateResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "ExportState")
+	defer tracing.FinishSpan(span, &err)
+
+	snapshot, err := m.stateSnapshot(time.Now().UTC())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot produce state snapshot: %v", err)
+	}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot serialize state snapshot: %v", err)
+	}
+	createdAt, err := ptypes.TimestampProto(snapshot.CreatedAt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot serialize state snapshot: %v", err)
+	}
+	log.WithField("instances", len(snapshot.Instances)).WithField("pendingOperations", len(snapshot.PendingOperations)).Info("exported state snapshot")
+
+	return &api.ExportStateResponse{
+		Snapshot:  content,
+		Version:   snapshot.Version,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+func (m *Manager) stateSnapshot(now time.Time) (*stateSnapshot, error) {
+	instances := make(map[string]*snapshotInstance)
+	instance := func(id string) *snapshotInstance {
+		inst, ok := instances[id]
+		if !ok {
+			inst = &snapshotInstance{ID: id}
+			instances[id] = inst
+		}
+		return inst
+	}
+
+	m.activityLock.Lock()
+	for id, t := range m.activity {
+		t := t
+		instance(id).LastActivity = &t
+	}
+	m.activityLock.Unlock()
+
+	m.proxyActivityLock.Lock()
+	for id, proxies := range m.proxyActivity {
+		inst := instance(id)
+		inst.Proxies = make(map[string]proxyActivity, len(proxies))
+		for proxy, act := range proxies {
+			inst.Proxies[proxy] = act
+		}
+	}
+	m.proxyActivityLock.Unlock()
+
+	// we export the generations of instances we know about for other reasons only - instances which have nothing
+	// but a generation are long gone or get a new generation anyways.
+	m.generations.mu.Lock()
+	for id, gen := range m.generations.current {
+		instance(id).Generation = gen
+	}
+	m.generations.mu.Unlock()
+
+	res := &stateSnapshot{
+		Version:   stateSnapshotVersion,
+		CreatedAt: now,
+		Instances: make([]snapshotInstance, 0, len(instances)),
+	}
+	for _, inst := range instances {
+		res.Instances = append(res.Instances, *inst)
+	}
+	sort.Slice(res.Instances, func(i, j int) bool { return res.Instances[i].ID < res.Instances[j].ID })
+
+	if m.monitor != nil {
+		res.PendingOperations = m.monitor.pendingOperations()
+	}
+
+	if mnt := m.currentMaintenance(); mnt != nil {
+		var err error
+		res.Maintenance = &snapshotMaintenanceStatus{Message: mnt.Message}
+		res.Maintenance.StartsAt, err = snapshotTime(mnt.StartsAt)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid maintenance start: %w", err)
+		}
+		res.Maintenance.EndsAt, err = snapshotTime(mnt.EndsAt)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid maintenance end: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+// pendingOperations lists the content initializations and finalizations which are under way
+func (m *Monitor) pendingOperations() []snapshotPendingOperation {
+	var res []snapshotPendingOperation
+
+	m.initializerMapLock.Lock()
+	for podName := range m.initializerMap {
+		// the initializer map is indexed by pod name which is the workspace type followed by the instance ID
+		segs := strings.SplitN(podName, "-", 2)
+		if len(segs) != 2 {
+			continue
+		}
+		res = append(res, snapshotPendingOperation{InstanceID: segs[1], Kind: api.PendingOperationKind_CONTENT_INITIALIZATION})
+	}
+	m.initializerMapLock.Unlock()
+
+	m.finalizerMapLock.Lock()
+	for id := range m.finalizerMap {
+		res = append(res, snapshotPendingOperation{InstanceID: id, Kind: api.PendingOperationKind_CONTENT_FINALIZATION})
+	}
+	m.finalizerMapLock.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].InstanceID == res[j].InstanceID {
+			return res[i].Kind < res[j].Kind
+		}
+		return res[i].InstanceID < res[j].InstanceID
+	})
+	return res
+}
+
+// ImportState restores a state snapshot, e.g. on a freshly started ws-manager in a recovered cluster. We restore the state
+// of instances which still have a pod only, and never replace state this ws-manager has learned itself since it started.
+func (m *Manager) ImportState(ctx context.Context, req *api.ImportStateRequest) (res *api.ImportStateResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "ImportState")
+	defer tracing.FinishSpan(span, &err)
+	defer func() {
+		m.audit(ctx, &api.AuditRecord{Action: "ImportState"}, err)
+	}()
+
+	var snapshot stateSnapshot
+	err = json.Unmarshal(req.Snapshot, &snapshot)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot parse state snapshot: %v", err)
+	}
+	if snapshot.Version != stateSnapshotVersion {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported state snapshot version %d, expected %d", snapshot.Version, stateSnapshotVersion)
+	}
+
+	pods, plis, err := m.listWorkspaceInstances(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list workspaces: %v", err)
+	}
+
+	res = &api.ImportStateResponse{}
+	for _, inst := range snapshot.Instances {
+		if _, exists := pods[inst.ID]; !exists {
+			res.DroppedInstances = append(res.DroppedInstances, inst.ID)
+			continue
+		}
+
+		if inst.LastActivity != nil {
+			m.restoreActivity(inst.ID, *inst.LastActivity)
+		}
+		if len(inst.Proxies) > 0 {
+			m.restoreProxyActivity(inst.ID, inst.Proxies)
+		}
+		if inst.Generation > 0 {
+			m.generations.Restore(inst.ID, inst.Generation)
+		}
+		res.RestoredInstances = append(res.RestoredInstances, inst.ID)
+	}
+
+	for _, op := range snapshot.PendingOperations {
+		var resumed bool
+		switch op.Kind {
+		case api.PendingOperationKind_CONTENT_INITIALIZATION:
+			// the monitor initializes the content of workspaces ws-daemon knows nothing about when it waits for the initialization
+			_, resumed = pods[op.InstanceID]
+		case api.PendingOperationKind_CONTENT_FINALIZATION:
+			// the monitor finalizes the content of all stopping workspaces which still have their lifecycle independent state
+			_, resumed = plis[op.InstanceID]
+		}
+
+		pop := &api.PendingOperation{InstanceId: op.InstanceID, Kind: op.Kind}
+		if resumed {
+			res.ResumedOperations = append(res.ResumedOperations, pop)
+			continue
+		}
+		res.LostOperations = append(res.LostOperations, pop)
+		log.WithFields(log.OWI("", "", op.InstanceID)).WithField("operation", op.Kind.String()).Warn("pending operation of state snapshot is lost - its workspace no longer exists")
+	}
+
+	if snapshot.Maintenance != nil {
+		res.MaintenanceRestored, err = m.restoreMaintenance(ctx, snapshot.Maintenance)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid maintenance status: %v", err)
+		}
+	}
+
+	log.WithFields(map[string]interface{}{
+		"createdAt":         snapshot.CreatedAt,
+		"restored":          len(res.RestoredInstances),
+		"dropped":           len(res.DroppedInstances),
+		"resumedOperations": len(res.ResumedOperations),
+		"lostOperations":    len(res.LostOperations),
+		"maintenance":       res.MaintenanceRestored,
+	}).Info("imported state snapshot")
+	return res, nil
+}
+
+// listWorkspaceInstances returns the IDs of the workspace instances which have a pod or lifecycle independent state
+func (m *Manager) listWorkspaceInstances(ctx context.Context) (pods, plis map[string]struct{}, err error) {
+	var podList corev1.PodList
+	err = m.Clientset.List(ctx, &podList, workspaceObjectListOptions(m.Config.Namespace))
+	if err != nil {
+		return nil, nil, err
+	}
+	pods = make(map[string]struct{}, len(podList.Items))
+	for _, pod := range podList.Items {
+		if id, ok := pod.Labels[wsk8s.WorkspaceIDLabel]; ok {
+			pods[id] = struct{}{}
+		}
+	}
+
+	var cfgmapList corev1.ConfigMapList
+	err = m.Clientset.List(ctx, &cfgmapList, workspaceObjectListOptions(m.Config.Namespace))
+	if err != nil {
+		return nil, nil, err
+	}
+	plis = make(map[string]struct{}, len(cfgmapList.Items))
+	for _, cfgmap := range cfgmapList.Items {
+		if id, ok := cfgmap.Labels[wsk8s.WorkspaceIDLabel]; ok {
+			plis[id] = struct{}{}
+		}
+	}
+
+	return pods, plis, nil
+}
+
+// restoreActivity restores the last activity of an instance unless the instance has been active since
+func (m *Manager) restoreActivity(instanceID string, lastActivity time.Time) {
+	m.activityLock.Lock()
+	defer m.activityLock.Unlock()
+
+	current, ok := m.activity[instanceID]
+	if ok && !current.Equal(m.assumedActivity) && current.After(lastActivity) {
+		return
+	}
+	m.activity[instanceID] = lastActivity
+}
+
+// restoreProxyActivity restores what proxies reported for an instance unless they have reported since
+func (m *Manager) restoreProxyActivity(instanceID string, proxies map[string]proxyActivity) {
+	m.proxyActivityLock.Lock()
+	defer m.proxyActivityLock.Unlock()
+
+	if m.proxyActivity == nil {
+		m.proxyActivity = make(map[string]map[string]proxyActivity)
+	}
+	current := m.proxyActivity[instanceID]
+	if current == nil {
+		current = make(map[string]proxyActivity, len(proxies))
+		m.proxyActivity[instanceID] = current
+	}
+	for proxy, act := range proxies {
+		if _, reported := current[proxy]; reported {
+			continue
+		}
+		current[proxy] = act
+	}
+}
+
+// restoreMaintenance restores a maintenance unless one was announced to this ws-manager already
+func (m *Manager) restoreMaintenance(ctx context.Context, mnt *snapshotMaintenanceStatus) (restored bool, err error) {
+	sts := &api.MaintenanceStatus{
+		Enabled: true,
+		Message: mnt.Message,
+	}
+	sts.StartsAt, err = snapshotTimestamp(mnt.StartsAt)
+	if err != nil {
+		return false, err
+	}
+	sts.EndsAt, err = snapshotTimestamp(mnt.EndsAt)
+	if err != nil {
+		return false, err
+	}
+
+	m.maintenanceLock.Lock()
+	if m.maintenance != nil {
+		m.maintenanceLock.Unlock()
+		return false, nil
+	}
+	m.maintenance = sts
+	m.maintenanceLock.Unlock()
+	log.WithField("maintenance", sts).Info("restored maintenance status")
+
+	m.publishToSubscribers(ctx, &api.SubscribeResponse{
+		Payload: &api.SubscribeResponse_Maintenance{Maintenance: sts},
+	})
+	return true, nil
+}
+
+func snapshotTime(ts *timestamp.Timestamp) (*time.Time, error) {
+	if ts == nil {
+		return nil, nil
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func snapshotTimestamp(t *time.Time) (*timestamp.Timestamp, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return ptypes.TimestampProto(*t)
+}
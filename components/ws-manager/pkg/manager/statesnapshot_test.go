@@ -0,0 +1,168 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestStateSnapshot(t *testing.T) {
+	var (
+		now      = time.Now().UTC().Truncate(time.Second)
+		startsAt = now.Add(time.Hour)
+	)
+	startsAtProto, _ := ptypes.TimestampProto(startsAt)
+
+	old := &Manager{
+		activity: map[string]time.Time{
+			"running":  now.Add(-10 * time.Minute),
+			"stopping": now.Add(-20 * time.Minute),
+			"gone":     now.Add(-30 * time.Minute),
+		},
+		proxyActivity: map[string]map[string]proxyActivity{
+			"running": {"proxy-a": {Connections: 2, Sessions: 1, Time: now.Add(-time.Minute)}},
+		},
+		generations: newStatusGenerations(),
+		maintenance: &api.MaintenanceStatus{Enabled: true, Message: "upgrade", StartsAt: startsAtProto},
+	}
+	old.monitor = &Monitor{
+		manager:        old,
+		initializerMap: map[string]struct{}{"ws-running": {}, "prebuild-gone": {}},
+		finalizerMap:   map[string]context.CancelFunc{"stopping": nil},
+	}
+	runningGen := old.generations.Next("running")
+	// the old ws-manager handed out generations the recovered one has not reached yet, e.g. because its clock is behind
+	old.generations.counter += 1000000
+	stoppingGen := old.generations.Next("stopping")
+
+	exported, err := old.ExportState(context.Background(), &api.ExportStateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.Version != stateSnapshotVersion {
+		t.Errorf("unexpected snapshot version: %d", exported.Version)
+	}
+
+	workspace := func(id string) (*corev1.Pod, *corev1.ConfigMap) {
+		meta := metav1.ObjectMeta{
+			Name:      "ws-" + id,
+			Namespace: "default",
+			Labels: map[string]string{
+				markerLabel:            "true",
+				wsk8s.WorkspaceIDLabel: id,
+			},
+		}
+		return &corev1.Pod{ObjectMeta: meta}, &corev1.ConfigMap{ObjectMeta: meta}
+	}
+	runningPod, runningPLIS := workspace("running")
+	stoppingPod, stoppingPLIS := workspace("stopping")
+	recovered := &Manager{
+		Config:        Configuration{Namespace: "default", ProxyActivity: &ProxyActivityConfig{}},
+		Clientset:     fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(runningPod, runningPLIS, stoppingPod, stoppingPLIS).Build(),
+		activity:      make(map[string]time.Time),
+		proxyActivity: make(map[string]map[string]proxyActivity),
+		generations:   newStatusGenerations(),
+	}
+	// we marked all workspaces active during startup and the user has been active in the stopping one since
+	recovered.assumedActivity = now.Add(-5 * time.Minute)
+	recovered.activity["running"] = recovered.assumedActivity
+	recovered.activity["stopping"] = now
+	stoppingGenRecovered := recovered.generations.Next("stopping")
+
+	res, err := recovered.ImportState(context.Background(), &api.ImportStateRequest{Snapshot: exported.Snapshot})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectation := &api.ImportStateResponse{
+		RestoredInstances: []string{"running", "stopping"},
+		DroppedInstances:  []string{"gone"},
+		ResumedOperations: []*api.PendingOperation{
+			{InstanceId: "running", Kind: api.PendingOperationKind_CONTENT_INITIALIZATION},
+			{InstanceId: "stopping", Kind: api.PendingOperationKind_CONTENT_FINALIZATION},
+		},
+		LostOperations: []*api.PendingOperation{
+			{InstanceId: "gone", Kind: api.PendingOperationKind_CONTENT_INITIALIZATION},
+		},
+		MaintenanceRestored: true,
+	}
+	if diff := cmp.Diff(expectation, res, cmpopts.IgnoreUnexported(api.ImportStateResponse{}, api.PendingOperation{})); diff != "" {
+		t.Errorf("unexpected import result (-want +got):\n%s", diff)
+	}
+
+	if act := recovered.activity["running"]; !act.Equal(now.Add(-10 * time.Minute)) {
+		t.Errorf("expected the activity we assumed during startup to be replaced, got %s", act)
+	}
+	if act := recovered.activity["stopping"]; !act.Equal(now) {
+		t.Errorf("expected activity since startup to be kept, got %s", act)
+	}
+	if _, ok := recovered.activity["gone"]; ok {
+		t.Error("restored the activity of an instance which no longer exists")
+	}
+	if c, s, _ := recovered.getProxyActivity("running"); c != 2 || s != 1 {
+		t.Errorf("expected proxy activity to be restored, got %d connections and %d sessions", c, s)
+	}
+	if gen := recovered.generations.Current("running"); gen != runningGen {
+		t.Errorf("expected generation %d to be restored, got %d", runningGen, gen)
+	}
+	if gen := recovered.generations.Current("stopping"); gen <= stoppingGen || gen <= stoppingGenRecovered {
+		t.Errorf("expected generation to be higher than %d, got %d", stoppingGen, gen)
+	}
+	mnt := recovered.currentMaintenance()
+	if mnt == nil || mnt.Message != "upgrade" || !proto.Equal(mnt.StartsAt, startsAtProto) {
+		t.Errorf("unexpected maintenance status: %v", mnt)
+	}
+
+	// a maintenance announced to the recovered ws-manager takes precedence
+	res, err = recovered.ImportState(context.Background(), &api.ImportStateRequest{Snapshot: exported.Snapshot})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MaintenanceRestored {
+		t.Error("expected maintenance not to be restored twice")
+	}
+
+	_, err = recovered.ImportState(context.Background(), &api.ImportStateRequest{Snapshot: []byte(`{"version":42}`)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for unknown snapshot versions, got %v", err)
+	}
+}
+
+func TestStatusGenerationsRestore(t *testing.T) {
+	g := newStatusGenerations()
+	g.Restore("a", 42)
+	if gen := g.Current("a"); gen != 42 {
+		t.Errorf("expected generation 42 to be restored, got %d", gen)
+	}
+
+	current := g.Next("b")
+	g.Restore("b", current-1)
+	if gen := g.Current("b"); gen != current {
+		t.Errorf("expected newer generation %d to be kept, got %d", current, gen)
+	}
+
+	g.Restore("b", current+1000)
+	if gen := g.Current("b"); gen <= current+1000 {
+		t.Errorf("expected a generation higher than %d, got %d", current+1000, gen)
+	}
+	if gen := g.Next("c"); gen <= current+1000 {
+		t.Errorf("expected generations to stay above restored ones, got %d", gen)
+	}
+}
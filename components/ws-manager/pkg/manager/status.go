@@ -346,6 +346,19 @@ func (m *Manager) getWorkspaceStatus(wso workspaceObjects) (*api.WorkspaceStatus
 		if av, ok := api.AdmissionLevel_value[strings.ToUpper(wso.Pod.Annotations[workspaceAdmissionAnnotation])]; ok {
 			admission = api.AdmissionLevel(av)
 		}
+		idePin, err := getIDEPin(wso.Pod)
+		if err != nil {
+			log.WithError(err).WithFields(wso.GetOWI()).Warn("pod has invalid IDE pin annotation - ignoring it")
+			idePin = nil
+		}
+		var experiments map[string]string
+		if ex, ok := wso.Pod.Annotations[workspaceExperimentsAnnotation]; ok {
+			err := json.Unmarshal([]byte(ex), &experiments)
+			if err != nil {
+				log.WithError(err).WithFields(wso.GetOWI()).Warn("pod has invalid experiments annotation - ignoring it")
+				experiments = nil
+			}
+		}
 
 		status = &api.WorkspaceStatus{
 			Id:       id,
@@ -357,7 +370,11 @@ func (m *Manager) getWorkspaceStatus(wso workspaceObjects) (*api.WorkspaceStatus
 				Url:            wsurl,
 				Type:           tpe,
 				Timeout:        timeout,
+				Experiments:    experiments,
+				ScheduledStop:  getScheduledStopProto(wso.Pod),
+				IdePin:         idePin,
 			},
+			InitContainers: getInitContainerStatus(wso.Pod),
 			Conditions: &api.WorkspaceConditions{
 				Snapshot: wso.Pod.Annotations[workspaceSnapshotAnnotation],
 			},
@@ -365,6 +382,7 @@ func (m *Manager) getWorkspaceStatus(wso workspaceObjects) (*api.WorkspaceStatus
 				NodeName: wso.Pod.Spec.NodeName,
 				PodName:  wso.Pod.Name,
 				NodeIp:   wso.Pod.Status.HostIP,
+				PodIp:    wso.Pod.Status.PodIP,
 			},
 			Auth: &api.WorkspaceAuthentication{
 				Admission:  admission,
@@ -402,6 +420,7 @@ func (m *Manager) getWorkspaceStatus(wso workspaceObjects) (*api.WorkspaceStatus
 				Visibility: portNameToVisibility(p.Name),
 				Url:        service.Annotations[fmt.Sprintf("gitpod/port-url-%d", p.Port)],
 			}
+			port.PermissiveCors = service.Annotations[portCORSAnnotation(port.Port)] == portCORSPermissive
 
 			// enforce the cannonical form where target defaults to port
 			if port.Port == port.Target {
@@ -446,6 +465,8 @@ func getWorkspaceMetadata(pod *corev1.Pod) *api.WorkspaceMetadata {
 		Owner:     pod.ObjectMeta.Labels[wsk8s.OwnerLabel],
 		MetaId:    pod.ObjectMeta.Labels[wsk8s.MetaIDLabel],
 		StartedAt: started,
+		GroupId:   pod.ObjectMeta.Labels[workspaceGroupLabel],
+		Project:   pod.ObjectMeta.Annotations[workspaceProjectAnnotation],
 	}
 }
 
@@ -465,6 +486,10 @@ func (m *Manager) extractStatusFromPod(result *api.WorkspaceStatus, wso workspac
 		}
 		result.Conditions.Timeout = reason
 	}
+	if _, paused := pod.Annotations[workspacePausedAnnotation]; paused {
+		result.Conditions.Paused = api.WorkspaceConditionBool_TRUE
+	}
+	result.Conditions.MigrateTo = pod.Annotations[workspaceMigrateToAnnotation]
 
 	if isPodBeingDeleted(pod) {
 		result.Phase = api.WorkspacePhase_STOPPING
@@ -481,6 +506,13 @@ func (m *Manager) extractStatusFromPod(result *api.WorkspaceStatus, wso workspac
 
 	status := pod.Status
 	if status.Phase == corev1.PodPending {
+		if name := getRunningInitContainer(pod); name != "" {
+			result.Phase = api.WorkspacePhase_CREATING
+			result.Conditions.PullingImages = api.WorkspaceConditionBool_FALSE
+			result.Message = fmt.Sprintf("init container %s is running", name)
+			return nil
+		}
+
 		// check if any container is still pulling images
 		for _, cs := range status.ContainerStatuses {
 			if cs.State.Waiting != nil {
@@ -618,10 +650,13 @@ func extractFailure(wso workspaceObjects) (string, *api.WorkspacePhase) {
 		// the workspace, e.g. stopping.
 		return fmt.Sprintf("%s: %s", status.Reason, status.Message), nil
 	}
+	if failure := extractInitContainerFailure(pod); failure != "" {
+		return failure, nil
+	}
 
 	for _, cs := range status.ContainerStatuses {
 		if cs.State.Waiting != nil {
-			if cs.State.Waiting.Reason == "ImagePullBackOff" || cs.State.Waiting.Reason == "ErrImagePull" {
+			if isImagePullFailure(cs.State.Waiting.Reason) {
 				// If the image pull failed we were definitely in the api.WorkspacePhase_CREATING phase,
 				// unless of course this pod has been deleted already.
 				var res api.WorkspacePhase
@@ -740,6 +775,7 @@ const (
 	activityInterrupted        activity = "workspace interruption"
 	activityStopping           activity = "stopping"
 	activityBackup             activity = "backup"
+	activityPaused             activity = "waiting for activation"
 )
 
 // isWorkspaceTimedOut determines if a workspace is timed out based on the manager configuration and state the pod is in.
@@ -771,6 +807,14 @@ func (m *Manager) isWorkspaceTimedOut(wso workspaceObjects) (reason string, err
 		lastActivity := m.getWorkspaceActivity(workspaceID)
 		_, isClosed := wso.Pod.Annotations[workspaceClosedAnnotation]
 
+		if wso.Pod.Annotations[workspacePausedAnnotation] == pausedWaiting && m.Config.PausedWorkspaces != nil && phase != api.WorkspacePhase_STOPPING {
+			return decide(start, m.Config.PausedWorkspaces.Timeout, activityPaused)
+		}
+		if activatedAt, ok := getActivatedAt(wso.Pod); ok && activatedAt.After(start) {
+			// a paused workspace starts for the user when it is activated
+			start = activatedAt
+		}
+
 		switch phase {
 		case api.WorkspacePhase_PENDING:
 			return decide(start, m.Config.Timeouts.Initialization, activityInit)
@@ -791,8 +835,10 @@ func (m *Manager) isWorkspaceTimedOut(wso workspaceObjects) (reason string, err
 			} else if lastActivity == nil {
 				// the workspace is up and running, but the user has never produced any activity
 				return decide(start, m.Config.Timeouts.TotalStartup, activityNone)
-			} else if isClosed {
-				return decide(*lastActivity, m.Config.Timeouts.AfterClose, activityClosed)
+			}
+			lastUserActivity := m.withProxyActivity(workspaceID, *lastActivity)
+			if isClosed && !m.hasProxySessions(workspaceID) {
+				return decide(lastUserActivity, m.Config.Timeouts.AfterClose, activityClosed)
 			}
 			timeout := m.Config.Timeouts.RegularWorkspace
 			if ctv, ok := wso.Pod.Annotations[customTimeoutAnnotation]; ok {
@@ -803,7 +849,7 @@ func (m *Manager) isWorkspaceTimedOut(wso workspaceObjects) (reason string, err
 					timeout = util.Duration(ct)
 				}
 			}
-			return decide(*lastActivity, timeout, activityNone)
+			return decide(lastUserActivity, timeout, activityNone)
 
 		case api.WorkspacePhase_INTERRUPTED:
 			if lastActivity == nil {
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SupervisorConfig is the installation-wide configuration of supervisor in all workspaces. We pass it to supervisor
+// in GITPOD_ env vars, which start workspace requests cannot set, so that users cannot weaken it.
+type SupervisorConfig struct {
+	// SecretProviders are the secrets providers tasks fetch the secrets they declare from, keyed by name,
+	// e.g. {"vault": {"type": "vault", "address": "https://vault.example.com"}}. Supervisor validates them in detail.
+	SecretProviders map[string]json.RawMessage `json:"secretProviders,omitempty"`
+	// APIAccessPolicy restricts which workspace processes may call which methods of supervisor's API,
+	// e.g. {"rules": [{"methods": ["/supervisor.TokenService/*"], "allow": ["ide"]}], "remoteNetworks": ["10.20.0.0/16"]}.
+	// remoteNetworks must contain the networks ws-proxy connects from. Supervisor validates the policy in detail.
+	APIAccessPolicy json.RawMessage `json:"apiAccessPolicy,omitempty"`
+	// EgressPolicy restricts the destinations workspaces may connect to, e.g. {"allowedDomains": ["github.com"]}.
+	// The egress policies of projects, which start workspace requests carry, can only narrow it.
+	// Supervisor validates the policy in detail.
+	EgressPolicy json.RawMessage `json:"egressPolicy,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *SupervisorConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.SecretProviders, validation.By(func(o interface{}) error {
+			for name, p := range o.(map[string]json.RawMessage) {
+				var provider struct {
+					Type string `json:"type"`
+				}
+				err := json.Unmarshal(p, &provider)
+				if err != nil {
+					return xerrors.Errorf("%s: %w", name, err)
+				}
+				if provider.Type == "" {
+					return xerrors.Errorf("%s: type is required", name)
+				}
+			}
+			return nil
+		})),
+		validation.Field(&c.APIAccessPolicy, validation.By(validateJSONObject)),
+		validation.Field(&c.EgressPolicy, validation.By(validateJSONObject)),
+	)
+}
+
+// validateJSONObject validates that a json.RawMessage is a JSON object
+func validateJSONObject(o interface{}) error {
+	var obj map[string]json.RawMessage
+	err := json.Unmarshal(o.(json.RawMessage), &obj)
+	if err != nil {
+		return xerrors.Errorf("must be a JSON object: %w", err)
+	}
+	return nil
+}
+
+// env produces the env vars which configure supervisor
+func (c *SupervisorConfig) env() ([]corev1.EnvVar, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	var res []corev1.EnvVar
+	if len(c.SecretProviders) > 0 {
+		providers, err := json.Marshal(c.SecretProviders)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot marshal secret providers: %w", err)
+		}
+		res = append(res, corev1.EnvVar{Name: "GITPOD_SECRET_PROVIDERS", Value: string(providers)})
+	}
+	for _, policy := range []struct {
+		Name  string
+		Value json.RawMessage
+	}{
+		{"GITPOD_API_ACCESS_POLICY", c.APIAccessPolicy},
+		{"GITPOD_EGRESS_POLICY", c.EgressPolicy},
+	} {
+		if len(policy.Value) == 0 {
+			continue
+		}
+		var value bytes.Buffer
+		err := json.Compact(&value, policy.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot marshal %s: %w", policy.Name, err)
+		}
+		res = append(res, corev1.EnvVar{Name: policy.Name, Value: value.String()})
+	}
+	return res, nil
+}
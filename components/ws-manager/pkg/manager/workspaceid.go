@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/namegen"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// GenerateWorkspaceID produces a new workspace ID according to the configured naming scheme. The ID does not collide
+// with any workspace in this cluster. Workspaces that are stopped are unknown to us though, which is why callers that
+// keep such workspaces around must check for collisions themselves.
+func (m *Manager) GenerateWorkspaceID(ctx context.Context, req *api.GenerateWorkspaceIDRequest) (res *api.GenerateWorkspaceIDResponse, err error) {
+	span, ctx := tracing.FromContext(ctx, "GenerateWorkspaceID")
+	tracing.LogRequestSafe(span, req)
+	defer tracing.FinishSpan(span, &err)
+
+	id, err := m.Config.WorkspaceIDs.Generate(req.Project, func(id string) (bool, error) {
+		return m.workspaceIDExists(ctx, id)
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot generate workspace ID: %v", err)
+	}
+	tracing.ApplyOWI(span, log.OWI("", id, ""))
+
+	return &api.GenerateWorkspaceIDResponse{Id: id}, nil
+}
+
+// workspaceIDExists checks if any workspace instance with the workspace ID exists in this cluster
+func (m *Manager) workspaceIDExists(ctx context.Context, id string) (bool, error) {
+	var pods corev1.PodList
+	err := m.Clientset.List(ctx, &pods, &client.ListOptions{
+		Namespace: m.Config.Namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			markerLabel:       "true",
+			wsk8s.MetaIDLabel: id,
+		}),
+	})
+	if err != nil {
+		return false, xerrors.Errorf("cannot list pods of workspace %s: %w", id, err)
+	}
+	return len(pods.Items) > 0, nil
+}
+
+// validateWorkspaceID makes sure that ws-proxy can route to the workspace we're about to start. We only enforce this
+// if the installation has a naming scheme, because it is then that we know all workspace IDs come from us.
+func (m *Manager) validateWorkspaceID(req *api.StartWorkspaceRequest) error {
+	if m.Config.WorkspaceIDs == nil || req.Metadata == nil {
+		return nil
+	}
+	return namegen.ValidateWorkspaceID(req.Metadata.MetaId)
+}
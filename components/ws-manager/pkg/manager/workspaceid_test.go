@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+	"github.com/gitpod-io/gitpod/common-go/namegen"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestGenerateWorkspaceID(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws-foobar",
+			Namespace: "default",
+			Labels: map[string]string{
+				markerLabel:       "true",
+				wsk8s.MetaIDLabel: "acme-amaranth-smelt-9ba20cc1",
+			},
+		},
+	}
+	m := &Manager{
+		Config: Configuration{
+			Namespace:    "default",
+			WorkspaceIDs: &namegen.IDScheme{Prefix: "acme", ProjectPrefixes: map[string]string{"gitpod-io/gitpod": "gp"}},
+		},
+		Clientset: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(pod).Build(),
+	}
+
+	exists, err := m.workspaceIDExists(context.Background(), "acme-amaranth-smelt-9ba20cc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Error("expected workspace ID to exist")
+	}
+
+	for project, prefix := range map[string]string{"": "acme-", "gitpod-io/website": "acme-", "gitpod-io/gitpod": "gp-"} {
+		res, err := m.GenerateWorkspaceID(context.Background(), &api.GenerateWorkspaceIDRequest{Project: project})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(res.Id, prefix) {
+			t.Errorf("expected ID of project %q to start with %s, got %s", project, prefix, res.Id)
+		}
+		if err := namegen.ValidateWorkspaceID(res.Id); err != nil {
+			t.Errorf("generated invalid ID: %v", err)
+		}
+	}
+}
+
+func TestValidateWorkspaceID(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Scheme *namegen.IDScheme
+		ID     string
+		Valid  bool
+	}{
+		{Name: "no scheme", ID: "Foo_Bar", Valid: true},
+		{Name: "generated ID", Scheme: &namegen.IDScheme{Prefix: "acme"}, ID: "acme-amaranth-smelt-9ba20cc1", Valid: true},
+		{Name: "UUID", Scheme: &namegen.IDScheme{Prefix: "acme"}, ID: "a7dab5e2-8c53-4a2b-8a07-4a4c9a9bbc97", Valid: true},
+		{Name: "unroutable ID", Scheme: &namegen.IDScheme{Prefix: "acme"}, ID: "Foo_Bar"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			m := &Manager{Config: Configuration{WorkspaceIDs: test.Scheme}}
+			err := m.validateWorkspaceID(&api.StartWorkspaceRequest{Metadata: &api.WorkspaceMetadata{MetaId: test.ID}})
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
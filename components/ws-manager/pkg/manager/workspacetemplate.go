@@ -0,0 +1,518 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultTemplateMaxVersions = 20
+
+	// tasksEnvVar is the environment variable supervisor reads the tasks of a workspace from
+	tasksEnvVar = "GITPOD_TASKS"
+)
+
+// WorkspaceTemplatesConfig stores named workspace templates which StartWorkspace requests can reference
+type WorkspaceTemplatesConfig struct {
+	// StatePath is the file we keep the templates in. It must be on a persistent volume,
+	// otherwise all templates are gone when ws-manager restarts.
+	StatePath string `json:"statePath"`
+	// MaxVersions is how many versions of a template we keep. Older versions can no longer be used. Defaults to 20.
+	MaxVersions int `json:"maxVersions,omitempty"`
+	// MaxPerOwner limits how many templates a user can own. Zero means no limit.
+	MaxPerOwner int `json:"maxPerOwner,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *WorkspaceTemplatesConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.StatePath, validation.Required),
+		validation.Field(&c.MaxVersions, validation.Min(0)),
+		validation.Field(&c.MaxPerOwner, validation.Min(0)),
+	)
+}
+
+// templateRecord is a workspace template with its versions, oldest first
+type templateRecord struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Owner    string            `json:"owner"`
+	TeamID   string            `json:"teamId,omitempty"`
+	Versions []templateVersion `json:"versions"`
+}
+
+// latest returns the latest version of the template
+func (r *templateRecord) latest() *templateVersion {
+	return &r.Versions[len(r.Versions)-1]
+}
+
+// usableBy is true if user owns the template or belongs to the team it is shared with
+func (r *templateRecord) usableBy(user, teamID string) bool {
+	if user != "" && r.Owner == user {
+		return true
+	}
+	return teamID != "" && r.TeamID == teamID
+}
+
+type templateVersion struct {
+	Version int64     `json:"version"`
+	Created time.Time `json:"created"`
+	// Spec is the api.WorkspaceTemplateSpec of this version in its JSON form
+	Spec json.RawMessage `json:"spec"`
+}
+
+// templateStore keeps the workspace templates and persists them to the state file
+type templateStore struct {
+	Config WorkspaceTemplatesConfig
+
+	mu      sync.Mutex
+	records map[string]*templateRecord
+
+	now   func() time.Time
+	newID func() string
+}
+
+// newTemplateStore restores the templates from the state file, or starts with none if the file does not exist
+func newTemplateStore(cfg WorkspaceTemplatesConfig) (*templateStore, error) {
+	if cfg.MaxVersions == 0 {
+		cfg.MaxVersions = defaultTemplateMaxVersions
+	}
+
+	s := &templateStore{
+		Config:  cfg,
+		records: make(map[string]*templateRecord),
+		now:     time.Now,
+		newID:   func() string { return uuid.New().String() },
+	}
+
+	fc, err := os.ReadFile(cfg.StatePath)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read workspace templates: %w", err)
+	}
+	var records []*templateRecord
+	err = json.Unmarshal(fc, &records)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read workspace templates: %w", err)
+	}
+	for _, r := range records {
+		if len(r.Versions) == 0 {
+			continue
+		}
+		s.records[r.ID] = r
+	}
+	return s, nil
+}
+
+// persist writes the templates to the state file. Callers must hold mu.
+func (s *templateStore) persist() error {
+	records := make([]*templateRecord, 0, len(s.records))
+	for _, r := range s.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	err := writeStateFile(s.Config.StatePath, records)
+	if err != nil {
+		return xerrors.Errorf("cannot write workspace templates: %w", err)
+	}
+	return nil
+}
+
+// Create stores a new template whose name must be unique among the templates of its owner
+func (s *templateStore) Create(name, owner, teamID string, spec *api.WorkspaceTemplateSpec) (*api.WorkspaceTemplate, error) {
+	v, err := s.newVersion(1, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var owned int
+	for _, r := range s.records {
+		if r.Owner != owner {
+			continue
+		}
+		if r.Name == name {
+			return nil, status.Errorf(codes.AlreadyExists, "workspace template %s exists already", name)
+		}
+		owned++
+	}
+	if s.Config.MaxPerOwner > 0 && owned >= s.Config.MaxPerOwner {
+		return nil, status.Errorf(codes.ResourceExhausted, "cannot own more than %d workspace templates", s.Config.MaxPerOwner)
+	}
+
+	r := &templateRecord{
+		ID:       s.newID(),
+		Name:     name,
+		Owner:    owner,
+		TeamID:   teamID,
+		Versions: []templateVersion{*v},
+	}
+	s.records[r.ID] = r
+	err = s.persist()
+	if err != nil {
+		delete(s.records, r.ID)
+		return nil, status.Errorf(codes.Internal, "cannot create workspace template: %v", err)
+	}
+	return describeTemplate(r, r.latest())
+}
+
+// Update adds a new version to a template. If latestVersion is not zero, the update fails unless it is still the latest version.
+func (s *templateStore) Update(id, owner string, latestVersion int64, spec *api.WorkspaceTemplateSpec) (*api.WorkspaceTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok || r.Owner != owner {
+		return nil, status.Errorf(codes.NotFound, "workspace template %s does not exist", id)
+	}
+	cur := r.latest().Version
+	if latestVersion != 0 && latestVersion != cur {
+		return nil, status.Errorf(codes.Aborted, "workspace template %s was updated concurrently: latest version is %d", id, cur)
+	}
+	v, err := s.newVersion(cur+1, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	prev := r.Versions
+	versions := append(append([]templateVersion(nil), prev...), *v)
+	if len(versions) > s.Config.MaxVersions {
+		versions = versions[len(versions)-s.Config.MaxVersions:]
+	}
+	r.Versions = versions
+	err = s.persist()
+	if err != nil {
+		r.Versions = prev
+		return nil, status.Errorf(codes.Internal, "cannot update workspace template: %v", err)
+	}
+	return describeTemplate(r, r.latest())
+}
+
+// Get returns a version of a template, or its latest version if version is zero. Templates the user
+// cannot use do not exist as far as they are concerned.
+func (s *templateStore) Get(id string, version int64, user, teamID string) (*api.WorkspaceTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok || !r.usableBy(user, teamID) {
+		return nil, status.Errorf(codes.NotFound, "workspace template %s does not exist", id)
+	}
+	if version == 0 {
+		return describeTemplate(r, r.latest())
+	}
+	for i := range r.Versions {
+		if r.Versions[i].Version == version {
+			return describeTemplate(r, &r.Versions[i])
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "workspace template %s has no version %d", id, version)
+}
+
+// List returns the latest version of all templates the user can use, sorted by name
+func (s *templateStore) List(user, teamID string) ([]*api.WorkspaceTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*api.WorkspaceTemplate
+	for _, r := range s.records {
+		if !r.usableBy(user, teamID) {
+			continue
+		}
+		t, err := describeTemplate(r, r.latest())
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+// Delete deletes a template with all its versions
+func (s *templateStore) Delete(id, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok || r.Owner != owner {
+		return status.Errorf(codes.NotFound, "workspace template %s does not exist", id)
+	}
+	delete(s.records, id)
+	err := s.persist()
+	if err != nil {
+		s.records[id] = r
+		return status.Errorf(codes.Internal, "cannot delete workspace template: %v", err)
+	}
+	return nil
+}
+
+// newVersion validates spec and produces a version of it
+func (s *templateStore) newVersion(version int64, spec *api.WorkspaceTemplateSpec) (*templateVersion, error) {
+	err := validateTemplateSpec(spec)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workspace template: %v", err)
+	}
+	raw, err := (&jsonpb.Marshaler{}).MarshalToString(spec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot serialize workspace template: %v", err)
+	}
+	return &templateVersion{
+		Version: version,
+		Created: s.now(),
+		Spec:    json.RawMessage(raw),
+	}, nil
+}
+
+func validateTemplateSpec(spec *api.WorkspaceTemplateSpec) error {
+	if spec == nil {
+		return xerrors.Errorf("spec is required")
+	}
+	err := isValidWorkspaceType(spec.Type)
+	if err != nil {
+		return xerrors.Errorf("type: %w", err)
+	}
+	if spec.Timeout != "" {
+		_, err = time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return xerrors.Errorf("timeout: %w", err)
+		}
+	}
+	if spec.Tasks != "" && !json.Valid([]byte(spec.Tasks)) {
+		return xerrors.Errorf("tasks are not valid JSON")
+	}
+	for _, e := range spec.Envvars {
+		if e.Name == "" {
+			return xerrors.Errorf("envvars: name is required")
+		}
+	}
+	return nil
+}
+
+// describeTemplate converts a version of a template to its API representation
+func describeTemplate(r *templateRecord, v *templateVersion) (*api.WorkspaceTemplate, error) {
+	var spec api.WorkspaceTemplateSpec
+	err := jsonpb.Unmarshal(bytes.NewReader(v.Spec), &spec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot read workspace template %s: %v", r.ID, err)
+	}
+	return &api.WorkspaceTemplate{
+		Id:            r.ID,
+		Name:          r.Name,
+		Owner:         r.Owner,
+		TeamId:        r.TeamID,
+		Version:       v.Version,
+		LatestVersion: r.latest().Version,
+		Spec:          &spec,
+		Created:       archivalTimestamp(v.Created),
+	}, nil
+}
+
+// applyTemplate fills in what the spec of a start request leaves empty from a template.
+// Environment variables of the request take precedence over those of the template.
+func applyTemplate(req *api.StartWorkspaceRequest, tpl *api.WorkspaceTemplateSpec) {
+	if req.Spec == nil {
+		req.Spec = &api.StartWorkspaceSpec{}
+	}
+	spec := req.Spec
+	if spec.WorkspaceImage == "" {
+		spec.WorkspaceImage = tpl.WorkspaceImage
+	}
+	if spec.IdeImage == "" {
+		spec.IdeImage = tpl.IdeImage
+	}
+	if spec.Timeout == "" {
+		spec.Timeout = tpl.Timeout
+	}
+	if req.Type == api.WorkspaceType_REGULAR {
+		req.Type = tpl.Type
+	}
+
+	env := tpl.Envvars
+	if tpl.Tasks != "" {
+		env = append(append([]*api.EnvironmentVariable(nil), env...), &api.EnvironmentVariable{Name: tasksEnvVar, Value: tpl.Tasks})
+	}
+	set := make(map[string]struct{}, len(spec.Envvars))
+	for _, e := range spec.Envvars {
+		set[e.Name] = struct{}{}
+	}
+	var merged []*api.EnvironmentVariable
+	for _, e := range env {
+		if _, ok := set[e.Name]; ok {
+			continue
+		}
+		merged = append(merged, &api.EnvironmentVariable{Name: e.Name, Value: e.Value})
+		set[e.Name] = struct{}{}
+	}
+	spec.Envvars = append(merged, spec.Envvars...)
+}
+
+// applyWorkspaceTemplate fills in a start request from the workspace template it references
+func (m *Manager) applyWorkspaceTemplate(req *api.StartWorkspaceRequest) error {
+	if req.Template == nil || req.Template.Id == "" {
+		return nil
+	}
+	if m.templates == nil {
+		return errStartWorkspaceInvalid(xerrors.Errorf("workspace templates are disabled"))
+	}
+
+	var owner string
+	if req.Metadata != nil {
+		owner = req.Metadata.Owner
+	}
+	tpl, err := m.templates.Get(req.Template.Id, req.Template.Version, owner, req.Template.TeamId)
+	if err != nil {
+		return newStartWorkspaceError(status.Code(err), api.StartWorkspaceErrorDomain_START_ERROR_INVALID_REQUEST, 0,
+			"Cannot start the workspace: "+status.Convert(err).Message(), err)
+	}
+	applyTemplate(req, tpl.Spec)
+	m.metrics.OnWorkspaceTemplate("used")
+	log.WithFields(log.OWI(owner, req.Metadata.GetMetaId(), req.Id)).WithField("template", tpl.Id).WithField("version", tpl.Version).Debug("applied workspace template")
+	return nil
+}
+
+// CreateWorkspaceTemplate stores a named workspace template which StartWorkspace can reference
+func (m *Manager) CreateWorkspaceTemplate(ctx context.Context, req *api.CreateWorkspaceTemplateRequest) (res *api.CreateWorkspaceTemplateResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "CreateWorkspaceTemplate")
+	span.SetTag("owner", req.Owner)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.templates == nil {
+		return nil, status.Error(codes.Unimplemented, "workspace templates are disabled")
+	}
+	if req.Name == "" || req.Owner == "" {
+		return nil, status.Error(codes.InvalidArgument, "name and owner are required")
+	}
+
+	tpl, err := m.templates.Create(req.Name, req.Owner, req.TeamId, req.Spec)
+	if err != nil {
+		return nil, err
+	}
+	m.metrics.OnWorkspaceTemplate("created")
+	log.WithField("owner", req.Owner).WithField("template", tpl.Id).Info("created workspace template")
+
+	return &api.CreateWorkspaceTemplateResponse{Template: tpl}, nil
+}
+
+// UpdateWorkspaceTemplate adds a new version to a workspace template
+func (m *Manager) UpdateWorkspaceTemplate(ctx context.Context, req *api.UpdateWorkspaceTemplateRequest) (res *api.UpdateWorkspaceTemplateResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "UpdateWorkspaceTemplate")
+	span.SetTag("template", req.Id)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.templates == nil {
+		return nil, status.Error(codes.Unimplemented, "workspace templates are disabled")
+	}
+	if req.Id == "" || req.Owner == "" {
+		return nil, status.Error(codes.InvalidArgument, "template ID and owner are required")
+	}
+
+	tpl, err := m.templates.Update(req.Id, req.Owner, req.LatestVersion, req.Spec)
+	if err != nil {
+		return nil, err
+	}
+	m.metrics.OnWorkspaceTemplate("updated")
+	log.WithField("owner", req.Owner).WithField("template", tpl.Id).WithField("version", tpl.Version).Info("updated workspace template")
+
+	return &api.UpdateWorkspaceTemplateResponse{Template: tpl}, nil
+}
+
+// GetWorkspaceTemplate returns a version of a workspace template
+func (m *Manager) GetWorkspaceTemplate(ctx context.Context, req *api.GetWorkspaceTemplateRequest) (res *api.GetWorkspaceTemplateResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "GetWorkspaceTemplate")
+	span.SetTag("template", req.Id)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.templates == nil {
+		return nil, status.Error(codes.Unimplemented, "workspace templates are disabled")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "template ID is required")
+	}
+
+	tpl, err := m.templates.Get(req.Id, req.Version, req.User, req.TeamId)
+	if err != nil {
+		return nil, err
+	}
+	return &api.GetWorkspaceTemplateResponse{Template: tpl}, nil
+}
+
+// ListWorkspaceTemplates returns the latest version of all workspace templates a user can use
+func (m *Manager) ListWorkspaceTemplates(ctx context.Context, req *api.ListWorkspaceTemplatesRequest) (res *api.ListWorkspaceTemplatesResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "ListWorkspaceTemplates")
+	span.SetTag("user", req.User)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.templates == nil {
+		return nil, status.Error(codes.Unimplemented, "workspace templates are disabled")
+	}
+	if req.User == "" && req.TeamId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user or team ID is required")
+	}
+
+	tpls, err := m.templates.List(req.User, req.TeamId)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ListWorkspaceTemplatesResponse{Templates: tpls}, nil
+}
+
+// DeleteWorkspaceTemplate deletes a workspace template and all its versions. Workspaces started from it are not affected.
+func (m *Manager) DeleteWorkspaceTemplate(ctx context.Context, req *api.DeleteWorkspaceTemplateRequest) (res *api.DeleteWorkspaceTemplateResponse, err error) {
+	//nolint:ineffassign
+	span, ctx := tracing.FromContext(ctx, "DeleteWorkspaceTemplate")
+	span.SetTag("template", req.Id)
+	defer tracing.FinishSpan(span, &err)
+
+	if m.templates == nil {
+		return nil, status.Error(codes.Unimplemented, "workspace templates are disabled")
+	}
+	if req.Id == "" || req.Owner == "" {
+		return nil, status.Error(codes.InvalidArgument, "template ID and owner are required")
+	}
+
+	err = m.templates.Delete(req.Id, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	m.metrics.OnWorkspaceTemplate("deleted")
+	log.WithField("owner", req.Owner).WithField("template", req.Id).Info("deleted workspace template")
+
+	return &api.DeleteWorkspaceTemplateResponse{}, nil
+}
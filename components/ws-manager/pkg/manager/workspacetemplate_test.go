@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestTemplateStore(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "templates.json")
+	newStore := func() *templateStore {
+		s, err := newTemplateStore(WorkspaceTemplatesConfig{StatePath: fn, MaxVersions: 2, MaxPerOwner: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids int
+		s.newID = func() string { ids++; return fmt.Sprintf("tpl-%d", ids) }
+		return s
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectCode := func(err error, code codes.Code) {
+		t.Helper()
+		if status.Code(err) != code {
+			t.Errorf("expected %v, got %v", code, err)
+		}
+	}
+	spec := func(image string) *api.WorkspaceTemplateSpec {
+		return &api.WorkspaceTemplateSpec{WorkspaceImage: image, Envvars: []*api.EnvironmentVariable{{Name: "FOO", Value: "bar"}}}
+	}
+
+	s := newStore()
+	tpl, err := s.Create("node", "alice", "team-a", spec("node:1"))
+	must(err)
+	if tpl.Version != 1 || tpl.LatestVersion != 1 {
+		t.Errorf("unexpected version of new template: %d/%d", tpl.Version, tpl.LatestVersion)
+	}
+	_, err = s.Create("node", "alice", "", spec("node:2"))
+	expectCode(err, codes.AlreadyExists)
+	_, err = s.Create("node", "bob", "", spec("node:2"))
+	must(err)
+	_, err = s.Create("go", "alice", "", &api.WorkspaceTemplateSpec{Timeout: "forever"})
+	expectCode(err, codes.InvalidArgument)
+	_, err = s.Create("go", "alice", "", spec("go:1"))
+	must(err)
+	_, err = s.Create("rust", "alice", "", spec("rust:1"))
+	expectCode(err, codes.ResourceExhausted)
+
+	_, err = s.Update(tpl.Id, "bob", 0, spec("node:2"))
+	expectCode(err, codes.NotFound)
+	_, err = s.Update(tpl.Id, "alice", 1, spec("node:2"))
+	must(err)
+	_, err = s.Update(tpl.Id, "alice", 1, spec("node:3"))
+	expectCode(err, codes.Aborted)
+	_, err = s.Update(tpl.Id, "alice", 0, spec("node:3"))
+	must(err)
+
+	// the state survives a restart
+	s = newStore()
+	latest, err := s.Get(tpl.Id, 0, "carol", "team-a")
+	must(err)
+	if latest.Version != 3 || latest.Spec.WorkspaceImage != "node:3" {
+		t.Errorf("unexpected latest version %d with image %s", latest.Version, latest.Spec.WorkspaceImage)
+	}
+	v2, err := s.Get(tpl.Id, 2, "alice", "")
+	must(err)
+	if v2.Spec.WorkspaceImage != "node:2" || v2.LatestVersion != 3 {
+		t.Errorf("unexpected version 2: %v", v2)
+	}
+	_, err = s.Get(tpl.Id, 1, "alice", "")
+	expectCode(err, codes.NotFound)
+	_, err = s.Get(tpl.Id, 0, "carol", "team-b")
+	expectCode(err, codes.NotFound)
+
+	names := func(tpls []*api.WorkspaceTemplate) []string {
+		var res []string
+		for _, t := range tpls {
+			res = append(res, t.Owner+"/"+t.Name)
+		}
+		return res
+	}
+	tpls, err := s.List("alice", "")
+	must(err)
+	if diff := cmp.Diff([]string{"alice/go", "alice/node"}, names(tpls)); diff != "" {
+		t.Errorf("unexpected templates (-want +got):\n%s", diff)
+	}
+	tpls, err = s.List("bob", "team-a")
+	must(err)
+	if diff := cmp.Diff([]string{"alice/node", "bob/node"}, names(tpls)); diff != "" {
+		t.Errorf("unexpected templates (-want +got):\n%s", diff)
+	}
+
+	expectCode(s.Delete(tpl.Id, "bob"), codes.NotFound)
+	must(s.Delete(tpl.Id, "alice"))
+	_, err = newStore().Get(tpl.Id, 0, "alice", "")
+	expectCode(err, codes.NotFound)
+}
+
+func TestApplyTemplate(t *testing.T) {
+	tpl := &api.WorkspaceTemplateSpec{
+		WorkspaceImage: "template-image",
+		IdeImage:       "template-ide",
+		Type:           api.WorkspaceType_PREBUILD,
+		Envvars: []*api.EnvironmentVariable{
+			{Name: "FOO", Value: "template"},
+			{Name: "BAR", Value: "template"},
+		},
+		Tasks:   `[{"init":"npm install"}]`,
+		Timeout: "60m",
+	}
+	req := &api.StartWorkspaceRequest{
+		Spec: &api.StartWorkspaceSpec{
+			IdeImage: "request-ide",
+			Envvars:  []*api.EnvironmentVariable{{Name: "FOO", Value: "request"}},
+		},
+	}
+	applyTemplate(req, tpl)
+
+	expected := &api.StartWorkspaceRequest{
+		Type: api.WorkspaceType_PREBUILD,
+		Spec: &api.StartWorkspaceSpec{
+			WorkspaceImage: "template-image",
+			IdeImage:       "request-ide",
+			Timeout:        "60m",
+			Envvars: []*api.EnvironmentVariable{
+				{Name: "BAR", Value: "template"},
+				{Name: tasksEnvVar, Value: `[{"init":"npm install"}]`},
+				{Name: "FOO", Value: "request"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, req, cmpopts.IgnoreUnexported(api.StartWorkspaceRequest{}, api.StartWorkspaceSpec{}, api.EnvironmentVariable{})); diff != "" {
+		t.Errorf("unexpected request (-want +got):\n%s", diff)
+	}
+	if len(tpl.Envvars) != 2 {
+		t.Errorf("applying the template modified it: %v", tpl.Envvars)
+	}
+}
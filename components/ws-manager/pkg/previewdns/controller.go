@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package previewdns maintains the DNS records of workspaces on custom preview domains.
+// ws-manager annotates workspace pods with their preview hostname and marks them once they're ready.
+// For each ready workspace this controller creates a DNSEndpoint which external-dns turns into a DNS record.
+// The record is removed when the workspace stops.
+package previewdns
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+)
+
+// DNSEndpointGVK is the kind of the external-dns CRD we create per workspace
+var DNSEndpointGVK = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// Config configures the preview DNS records
+type Config struct {
+	// RecordType is the type of the DNS records, e.g. CNAME or A
+	RecordType string `json:"recordType"`
+	// Targets are what the records point to, e.g. the hostname or IPs of the ws-proxy load balancer
+	Targets []string `json:"targets"`
+	// TTL is the TTL of the records in seconds. Zero uses the DNS provider's default.
+	TTL int64 `json:"ttl,omitempty"`
+	// Wildcard additionally creates a record for *.<hostname> so that subdomains, e.g. workspace ports, resolve too
+	Wildcard bool `json:"wildcard,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.RecordType, validation.Required, validation.In("A", "AAAA", "CNAME")),
+		validation.Field(&c.Targets, validation.Required),
+		validation.Field(&c.TTL, validation.Min(0)),
+	)
+}
+
+// Reconciler creates and deletes the DNSEndpoints of workspace pods
+type Reconciler struct {
+	client.Client
+	Log    logr.Logger
+	Config Config
+}
+
+// Reconcile makes sure a workspace pod has a DNSEndpoint iff it's ready and not stopping
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("pod", req.NamespacedName)
+
+	var pod corev1.Pod
+	err := r.Client.Get(ctx, req.NamespacedName, &pod)
+	if errors.IsNotFound(err) {
+		// the DNSEndpoint is owned by the pod and garbage collected with it - we just speed things up
+		return ctrl.Result{}, r.deleteEndpoint(ctx, req.Namespace, req.Name)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hostname := pod.Annotations[wsk8s.PreviewDNSHostnameAnnotation]
+	_, ready := pod.Annotations[wsk8s.PreviewDNSReadyAnnotation]
+	if hostname == "" || !ready || pod.DeletionTimestamp != nil {
+		return ctrl.Result{}, r.deleteEndpoint(ctx, pod.Namespace, pod.Name)
+	}
+
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+	endpoint.SetNamespace(pod.Namespace)
+	endpoint.SetName(pod.Name)
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, endpoint, func() error {
+		endpoint.SetLabels(map[string]string{
+			wsk8s.WorkspaceIDLabel: pod.Labels[wsk8s.WorkspaceIDLabel],
+			wsk8s.MetaIDLabel:      pod.Labels[wsk8s.MetaIDLabel],
+		})
+		endpoint.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(&pod, corev1.SchemeGroupVersion.WithKind("Pod"))})
+		return unstructured.SetNestedSlice(endpoint.Object, r.endpoints(hostname), "spec", "endpoints")
+	})
+	if err != nil {
+		return ctrl.Result{}, xerrors.Errorf("cannot create DNS endpoint for %s: %w", hostname, err)
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("DNS endpoint "+string(op), "hostname", hostname)
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) endpoints(hostname string) []interface{} {
+	names := []string{hostname}
+	if r.Config.Wildcard {
+		names = append(names, "*."+hostname)
+	}
+
+	targets := make([]interface{}, len(r.Config.Targets))
+	for i, t := range r.Config.Targets {
+		targets[i] = t
+	}
+	res := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		ep := map[string]interface{}{
+			"dnsName":    name,
+			"recordType": r.Config.RecordType,
+			"targets":    targets,
+		}
+		if r.Config.TTL > 0 {
+			ep["recordTTL"] = r.Config.TTL
+		}
+		res = append(res, ep)
+	}
+	return res
+}
+
+func (r *Reconciler) deleteEndpoint(ctx context.Context, namespace, name string) error {
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(DNSEndpointGVK)
+	endpoint.SetNamespace(namespace)
+	endpoint.SetName(name)
+	err := r.Client.Delete(ctx, endpoint)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot delete DNS endpoint %s: %w", name, err)
+	}
+	r.Log.Info("DNS endpoint deleted", "pod", namespace+"/"+name)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	hasPreviewDNS := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[wsk8s.PreviewDNSHostnameAnnotation]
+		return ok
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("previewdns").
+		For(&corev1.Pod{}, builder.WithPredicates(hasPreviewDNS)).
+		Complete(r)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package previewdns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	wsk8s "github.com/gitpod-io/gitpod/common-go/kubernetes"
+)
+
+func TestReconcile(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws-foobar",
+			Namespace: "default",
+			Labels:    map[string]string{wsk8s.WorkspaceIDLabel: "foobar", wsk8s.MetaIDLabel: "meta"},
+			Annotations: map[string]string{
+				wsk8s.PreviewDNSHostnameAnnotation: "foobar.preview.example.com",
+			},
+		},
+	}
+	clnt := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(pod).Build()
+	r := &Reconciler{
+		Client: clnt,
+		Log:    logr.Discard(),
+		Config: Config{RecordType: "CNAME", Targets: []string{"proxy.example.com"}, TTL: 60, Wildcard: true},
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}}
+	getEndpoint := func() (*unstructured.Unstructured, error) {
+		ep := &unstructured.Unstructured{}
+		ep.SetGroupVersionKind(DNSEndpointGVK)
+		err := clnt.Get(ctx, req.NamespacedName, ep)
+		return ep, err
+	}
+
+	// not ready yet
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getEndpoint(); !errors.IsNotFound(err) {
+		t.Fatalf("expected no DNS endpoint before the workspace is ready, got %v", err)
+	}
+
+	// ready
+	pod.Annotations[wsk8s.PreviewDNSReadyAnnotation] = "true"
+	if err := clnt.Update(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	ep, err := getEndpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints, _, _ := unstructured.NestedSlice(ep.Object, "spec", "endpoints")
+	expectation := []interface{}{
+		map[string]interface{}{"dnsName": "foobar.preview.example.com", "recordType": "CNAME", "targets": []interface{}{"proxy.example.com"}, "recordTTL": int64(60)},
+		map[string]interface{}{"dnsName": "*.foobar.preview.example.com", "recordType": "CNAME", "targets": []interface{}{"proxy.example.com"}, "recordTTL": int64(60)},
+	}
+	if diff := cmp.Diff(expectation, endpoints); diff != "" {
+		t.Errorf("unexpected endpoints (-want +got):\n%s", diff)
+	}
+	if refs := ep.GetOwnerReferences(); len(refs) != 1 || refs[0].Name != pod.Name {
+		t.Errorf("expected the pod to own the DNS endpoint, got %v", refs)
+	}
+	if id := ep.GetLabels()[wsk8s.WorkspaceIDLabel]; id != "foobar" {
+		t.Errorf("unexpected workspace ID label: %s", id)
+	}
+
+	// stopped
+	if err := clnt.Delete(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getEndpoint(); !errors.IsNotFound(err) {
+		t.Fatalf("expected DNS endpoint to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/adminclient"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/bundle"
+)
+
+var debugBundleOpts struct {
+	Output   string
+	LogLines int
+	Timeout  time.Duration
+}
+
+// debugCmd groups the troubleshooting commands
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Troubleshooting tools for a running ws-proxy",
+}
+
+// debugBundleCmd collects the state of a running ws-proxy into a support bundle
+var debugBundleCmd = &cobra.Command{
+	Use:   "bundle <config.json>",
+	Short: "Collects config, workspace routing, access log, metrics and goroutines of a running ws-proxy into an archive",
+	Long: `Collects the state of the ws-proxy running next to this command (e.g. using kubectl exec) into a gzipped tar archive:
+the configuration with secrets redacted, the workspace info cache, route table and open connections (requires the admin interface),
+the tail of the access log (requires the audit log), a metrics snapshot and a goroutine dump.
+Items we cannot collect are listed in the archive's manifest.json.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		out := os.Stdout
+		if fn := debugBundleOpts.Output; fn != "" && fn != "-" {
+			f, err := os.OpenFile(fn, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create bundle")
+			}
+			defer f.Close()
+			out = f
+		}
+
+		manifest, err := writeDebugBundle(out, args[0])
+		if err != nil {
+			log.WithError(err).Fatal("cannot write bundle")
+		}
+		for _, item := range manifest.Items {
+			if item.Error != "" {
+				log.WithField("item", item.Name).WithField("reason", item.Error).Warn("could not collect bundle item")
+			}
+		}
+		if out != os.Stdout {
+			log.WithField("output", debugBundleOpts.Output).Info("wrote support bundle")
+		}
+	},
+}
+
+func init() {
+	debugBundleCmd.Flags().StringVarP(&debugBundleOpts.Output, "output", "o", "ws-proxy-bundle.tar.gz", "where to write the bundle to, - for stdout")
+	debugBundleCmd.Flags().IntVar(&debugBundleOpts.LogLines, "log-lines", 1000, "number of access log lines to include")
+	debugBundleCmd.Flags().DurationVar(&debugBundleOpts.Timeout, "timeout", 10*time.Second, "timeout of each request to the running ws-proxy")
+	debugCmd.AddCommand(debugBundleCmd)
+	rootCmd.AddCommand(debugCmd)
+}
+
+// writeDebugBundle collects a support bundle based on the configuration in fn and writes it to out
+func writeDebugBundle(out io.Writer, fn string) (bundle.Manifest, error) {
+	labels := map[string]string{"version": Version}
+	if hostname, err := os.Hostname(); err == nil {
+		labels["hostname"] = hostname
+	}
+	w := bundle.NewWriter(out, labels)
+
+	// we don't validate the config: a bundle is most useful when something is wrong
+	rawCfg, cfgErr := os.ReadFile(fn)
+	var cfg Config
+	if cfgErr == nil {
+		cfgErr = json.Unmarshal(rawCfg, &cfg)
+	}
+
+	client := &http.Client{
+		Timeout: debugBundleOpts.Timeout,
+		Transport: &http.Transport{
+			// we talk to the ws-proxy on this host whose certificate does not name localhost
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	var (
+		adminClient *adminclient.Client
+		adminErr    = cfgErr
+	)
+	if cfgErr == nil {
+		adminClient, adminErr = adminclient.NewFromConfig(cfg.Admin)
+		if adminErr == nil {
+			adminClient.HTTPClient = client
+		}
+	}
+	admin := func(path string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			if adminErr != nil {
+				return nil, adminErr
+			}
+			return adminClient.Get(context.Background(), path)
+		}
+	}
+
+	items := []struct {
+		Name    string
+		Collect func() ([]byte, error)
+	}{
+		{"config.json", func() ([]byte, error) {
+			if cfgErr != nil {
+				return nil, cfgErr
+			}
+			return bundle.RedactJSON(rawCfg)
+		}},
+		{"workspaces.json", admin("/debug/cache")},
+		{"connections.json", admin("/debug/connections")},
+		{"routes.json", admin("/debug/routes")},
+		{"access-log.jsonl", func() ([]byte, error) {
+			if cfgErr != nil {
+				return nil, cfgErr
+			}
+			if cfg.Proxy.Audit == nil {
+				return nil, xerrors.Errorf("the audit log is not configured")
+			}
+			return bundle.TailFile(cfg.Proxy.Audit.File, debugBundleOpts.LogLines)
+		}},
+		{"metrics.txt", func() ([]byte, error) {
+			if cfgErr != nil {
+				return nil, cfgErr
+			}
+			if cfg.PrometheusAddr == "" {
+				return nil, xerrors.Errorf("metrics are not served")
+			}
+			return fetchDebugURL(client, "http://"+adminclient.LocalAddr(cfg.PrometheusAddr)+"/metrics", "")
+		}},
+		{"goroutines.txt", func() ([]byte, error) {
+			if cfgErr != nil {
+				return nil, cfgErr
+			}
+			if cfg.PProfAddr == "" {
+				return nil, xerrors.Errorf("pprof is not served")
+			}
+			return fetchDebugURL(client, "http://"+adminclient.LocalAddr(cfg.PProfAddr)+"/debug/pprof/goroutine?debug=2", "")
+		}},
+	}
+	for _, item := range items {
+		err := w.Add(item.Name, item.Collect)
+		if err != nil {
+			return w.Manifest(), err
+		}
+	}
+
+	manifest := w.Manifest()
+	return manifest, w.Close()
+}
+
+// fetchDebugURL GETs a URL of the running ws-proxy
+func fetchDebugURL(client *http.Client, url, token string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %d: %s", url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
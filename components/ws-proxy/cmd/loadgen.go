@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/loadgen"
+)
+
+var loadgenOpts struct {
+	HostHeader    string
+	HostSuffix    string
+	Workspaces    []string
+	MixFile       string
+	Concurrency   int
+	Duration      time.Duration
+	Timeout       time.Duration
+	Insecure      bool
+	Output        string
+	Baseline      string
+	MaxRegression float64
+}
+
+// loadgenCmd sends a representative request mix to a ws-proxy
+var loadgenCmd = &cobra.Command{
+	Use:   "loadgen <target>",
+	Short: "Sends a representative mix of workspace requests to a ws-proxy and reports throughput and latencies",
+	Long: `Sends a representative mix of workspace requests to the ws-proxy at target (e.g. http://localhost:8080) and reports
+throughput, errors and latency percentiles per kind of request as JSON. The workspaces must exist and be accessible
+to the requests of the mix.
+
+With --baseline the result is compared against a previous one and the command fails if the throughput dropped by
+more than --max-regression. Run both on the same machine for the comparison to be meaningful.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mix := loadgen.DefaultMix(loadgenOpts.HostSuffix)
+		if fn := loadgenOpts.MixFile; fn != "" {
+			mix = nil
+			err := readJSONFile(fn, &mix)
+			if err != nil {
+				log.WithError(err).Fatal("cannot read request mix")
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			cancel()
+		}()
+
+		log.WithField("target", args[0]).WithField("duration", loadgenOpts.Duration).Info("generating load")
+		res, err := loadgen.Run(ctx, loadgen.Config{
+			Target:      args[0],
+			HostHeader:  loadgenOpts.HostHeader,
+			Workspaces:  loadgenOpts.Workspaces,
+			Mix:         mix,
+			Concurrency: loadgenOpts.Concurrency,
+			Duration:    loadgenOpts.Duration,
+			Timeout:     loadgenOpts.Timeout,
+			Insecure:    loadgenOpts.Insecure,
+		})
+		if err != nil {
+			log.WithError(err).Fatal("cannot generate load")
+		}
+
+		out := os.Stdout
+		if fn := loadgenOpts.Output; fn != "" && fn != "-" {
+			f, err := os.OpenFile(fn, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+			if err != nil {
+				log.WithError(err).Fatal("cannot write result")
+			}
+			defer f.Close()
+			out = f
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(res)
+		if err != nil {
+			log.WithError(err).Fatal("cannot write result")
+		}
+
+		if fn := loadgenOpts.Baseline; fn != "" {
+			var baseline loadgen.Result
+			err := readJSONFile(fn, &baseline)
+			if err != nil {
+				log.WithError(err).Fatal("cannot read baseline")
+			}
+			err = loadgen.Compare(&baseline, res, loadgenOpts.MaxRegression)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			log.Info("no regression compared to the baseline")
+		}
+	},
+}
+
+func readJSONFile(fn string, dst interface{}) error {
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(fc, dst)
+	if err != nil {
+		return xerrors.Errorf("cannot unmarshal %s: %w", fn, err)
+	}
+	return nil
+}
+
+func init() {
+	loadgenCmd.Flags().StringVar(&loadgenOpts.HostHeader, "host-header", loadgen.DefaultHostHeader, "header ws-proxy routes requests by")
+	loadgenCmd.Flags().StringVar(&loadgenOpts.HostSuffix, "host-suffix", ".ws.gitpod.io", "domain suffix of workspace hosts in the default request mix")
+	loadgenCmd.Flags().StringSliceVarP(&loadgenOpts.Workspaces, "workspace", "w", nil, "workspace IDs to send requests for")
+	loadgenCmd.Flags().StringVar(&loadgenOpts.MixFile, "mix", "", "JSON file with the request mix to send instead of the default one")
+	loadgenCmd.Flags().IntVarP(&loadgenOpts.Concurrency, "concurrency", "c", 16, "number of requests in flight")
+	loadgenCmd.Flags().DurationVarP(&loadgenOpts.Duration, "duration", "d", 30*time.Second, "how long to send requests for")
+	loadgenCmd.Flags().DurationVar(&loadgenOpts.Timeout, "timeout", 10*time.Second, "timeout of a single request")
+	loadgenCmd.Flags().BoolVar(&loadgenOpts.Insecure, "insecure", false, "skip verifying the certificate of the target")
+	loadgenCmd.Flags().StringVarP(&loadgenOpts.Output, "output", "o", "-", "where to write the result to, - for stdout")
+	loadgenCmd.Flags().StringVar(&loadgenOpts.Baseline, "baseline", "", "result of a previous run to compare against")
+	loadgenCmd.Flags().Float64Var(&loadgenOpts.MaxRegression, "max-regression", 0.1, "throughput drop compared to the baseline we tolerate, e.g. 0.1 for 10%")
+	_ = loadgenCmd.MarkFlagRequired("workspace")
+	rootCmd.AddCommand(loadgenCmd)
+}
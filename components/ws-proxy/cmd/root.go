@@ -7,6 +7,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/certs"
 	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
 )
 
@@ -38,10 +40,6 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	closer := tracing.Init("ws-proxy")
-	if closer != nil {
-		defer closer.Close()
-	}
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -60,6 +58,14 @@ type Config struct {
 	PProfAddr                   string                            `json:"pprofAddr"`
 	PrometheusAddr              string                            `json:"prometheusAddr"`
 	ReadinessProbeAddr          string                            `json:"readinessProbeAddr"`
+	Health                      proxy.HealthConfig                `json:"health"`
+	Tracing                     *TracingConfig                    `json:"tracing,omitempty"`
+	Admin                       *proxy.AdminConfig                `json:"admin,omitempty"`
+	RouteHooks                  *proxy.RouteHooksConfig           `json:"routeHooks,omitempty"`
+	// Certificates obtains the certificates of the TLS listener from an ACME CA, e.g. Let's Encrypt
+	Certificates *certs.Config `json:"certificates,omitempty"`
+	// Upgrade lets a new ws-proxy process take the listeners over from the running one without dropping connections
+	Upgrade *proxy.UpgradeConfig `json:"upgrade,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -73,10 +79,71 @@ func (c *Config) Validate() error {
 	if err := c.WorkspaceInfoProviderConfig.Validate(); err != nil {
 		return err
 	}
+	if err := c.Health.Validate(); err != nil {
+		return err
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+	if err := c.Admin.Validate(); err != nil {
+		return err
+	}
+	if err := c.RouteHooks.Validate(); err != nil {
+		return err
+	}
+	if err := c.Certificates.Validate(); err != nil {
+		return err
+	}
+	if err := c.Upgrade.Validate(); err != nil {
+		return err
+	}
+	if c.Certificates != nil && !c.Proxy.HTTPS.Enabled {
+		return xerrors.Errorf("certificates require HTTPS to be enabled")
+	}
 
 	return nil
 }
 
+// TracingConfig configures how ws-proxy reports traces. Settings made here take
+// precedence over the JAEGER_* environment variables.
+type TracingConfig struct {
+	// SamplerType is one of const, probabilistic, ratelimiting or remote
+	SamplerType  string  `json:"samplerType"`
+	SamplerParam float64 `json:"samplerParam"`
+	// CollectorEndpoint is the HTTP endpoint of the collector spans are sent to
+	CollectorEndpoint string `json:"collectorEndpoint,omitempty"`
+	// AgentHostPort is the UDP address of the agent spans are sent to
+	AgentHostPort string `json:"agentHostPort,omitempty"`
+}
+
+// Validate validates this config
+func (c *TracingConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	return validation.ValidateStruct(c,
+		validation.Field(&c.SamplerType, validation.In("const", "probabilistic", "ratelimiting", "remote")),
+		validation.Field(&c.SamplerParam, validation.Min(0.0)),
+	)
+}
+
+// Options produces the tracing options for this config
+func (c *TracingConfig) Options() []tracing.Option {
+	if c == nil {
+		return nil
+	}
+
+	var opts []tracing.Option
+	if c.SamplerType != "" {
+		opts = append(opts, tracing.WithSampler(c.SamplerType, c.SamplerParam))
+	}
+	if c.CollectorEndpoint != "" || c.AgentHostPort != "" {
+		opts = append(opts, tracing.WithCollectorEndpoint(c.CollectorEndpoint, c.AgentHostPort))
+	}
+	return opts
+}
+
 // IngressKind names a kind of ingress
 type IngressKind string
 
@@ -120,6 +187,9 @@ func (c *IngressConfig) Validate() (err error) {
 type HostBasedInressConfig struct {
 	Address string `json:"address"`
 	Header  string `json:"header"`
+
+	// AdditionalAddresses are further addresses the proxy listens on, e.g. "[::]:8080" next to "0.0.0.0:8080" for dual-stack
+	AdditionalAddresses []string `json:"additionalAddresses,omitempty"`
 }
 
 // Validate validates this config
@@ -128,8 +198,9 @@ func (c *HostBasedInressConfig) Validate() error {
 		return xerrors.Errorf("host based ingress config is mandatory")
 	}
 	return validation.ValidateStruct(c,
-		validation.Field(&c.Address, validation.Required),
+		validation.Field(&c.Address, validation.Required, validListenAddress),
 		validation.Field(&c.Header, validation.Required),
+		validation.Field(&c.AdditionalAddresses, validListenAddresses),
 	)
 }
 
@@ -138,6 +209,9 @@ type PathAndHostIngressConfig struct {
 	Address    string `json:"address"`
 	Header     string `json:"header"`
 	TrimPrefix string `json:"trimPrefix"`
+
+	// AdditionalAddresses are further addresses the proxy listens on, e.g. "[::]:8080" next to "0.0.0.0:8080" for dual-stack
+	AdditionalAddresses []string `json:"additionalAddresses,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -146,8 +220,9 @@ func (c *PathAndHostIngressConfig) Validate() error {
 		return xerrors.Errorf("pathAndHost based ingress config is mandatory")
 	}
 	err := validation.ValidateStruct(c,
-		validation.Field(&c.Address, validation.Required),
+		validation.Field(&c.Address, validation.Required, validListenAddress),
 		validation.Field(&c.Header, validation.Required),
+		validation.Field(&c.AdditionalAddresses, validListenAddresses),
 	)
 	if err != nil {
 		return err
@@ -161,6 +236,12 @@ type PathAndPortBasedIngressConfig struct {
 	TrimPrefix string `json:"trimPrefix"`
 	Start      uint16 `json:"start"`
 	End        uint16 `json:"end"`
+
+	// AdditionalAddresses are further addresses the proxy listens on, e.g. "[::]:8080" next to "0.0.0.0:8080" for dual-stack
+	AdditionalAddresses []string `json:"additionalAddresses,omitempty"`
+	// PortRangeHosts are the IPs we listen on for the port range, e.g. ["0.0.0.0", "::"] for dual-stack.
+	// Defaults to all address families the node supports.
+	PortRangeHosts []string `json:"portRangeHosts,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -169,9 +250,23 @@ func (c *PathAndPortBasedIngressConfig) Validate() error {
 		return xerrors.Errorf("pathAndPort based ingress config is mandatory")
 	}
 	err := validation.ValidateStruct(c,
-		validation.Field(&c.Address, validation.Required),
+		validation.Field(&c.Address, validation.Required, validListenAddress),
 		validation.Field(&c.Start, validation.Required),
 		validation.Field(&c.End, validation.Required),
+		validation.Field(&c.AdditionalAddresses, validListenAddresses),
+		validation.Field(&c.PortRangeHosts, validation.By(func(o interface{}) error {
+			hosts, ok := o.([]string)
+			if !ok {
+				return xerrors.Errorf("field should be a list of IPs")
+			}
+			for _, h := range hosts {
+				err := proxy.ValidateListenAddress(net.JoinHostPort(h, "0"))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})),
 	)
 	if err != nil {
 		return err
@@ -184,6 +279,28 @@ func (c *PathAndPortBasedIngressConfig) Validate() error {
 	return err
 }
 
+var validListenAddress = validation.By(func(o interface{}) error {
+	addr, ok := o.(string)
+	if !ok {
+		return xerrors.Errorf("field should be a string")
+	}
+	return proxy.ValidateListenAddress(addr)
+})
+
+var validListenAddresses = validation.By(func(o interface{}) error {
+	addrs, ok := o.([]string)
+	if !ok {
+		return xerrors.Errorf("field should be a list of addresses")
+	}
+	for _, addr := range addrs {
+		err := proxy.ValidateListenAddress(addr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+})
+
 // getConfig loads and validates the configuration
 func getConfig(fn string) (*Config, error) {
 	fc, err := os.ReadFile(fn)
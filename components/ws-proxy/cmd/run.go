@@ -5,10 +5,13 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,6 +21,9 @@ import (
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/pprof"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/certs"
 	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
 )
 
@@ -34,67 +40,488 @@ var runCmd = &cobra.Command{
 			log.WithError(err).WithField("filename", args[0]).Fatal("cannot load config")
 		}
 
+		var upgrades *proxy.UpgradeController
+		if cfg.Upgrade != nil {
+			upgrades = proxy.NewUpgradeController(*cfg.Upgrade)
+		}
+		// runs after all other deferred functions, so that everything is shut down before we wait for our successors
+		var actAsInit bool
+		defer func() {
+			if actAsInit {
+				log.Info("staying on as PID 1 of the container until our successors stop")
+				os.Exit(upgrades.ActAsInit())
+			}
+		}()
+
+		if closer := tracing.Init(ServiceName, cfg.Tracing.Options()...); closer != nil {
+			defer closer.Close()
+		}
+
 		const wsmanConnectionAttempts = 5
 		workspaceInfoProvider := proxy.NewRemoteWorkspaceInfoProvider(cfg.WorkspaceInfoProviderConfig)
-		for i := 0; i < wsmanConnectionAttempts; i++ {
-			err = workspaceInfoProvider.Run()
-			if err == nil {
-				break
-			}
-			if i == wsmanConnectionAttempts-1 {
-				continue
+
+		var routeHooks *proxy.RouteHooks
+		if cfg.RouteHooks != nil {
+			routeHooks, err = proxy.NewRouteHooks(*cfg.RouteHooks)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create route hooks")
 			}
+			routeHooks.Start()
+			defer routeHooks.Close()
+			workspaceInfoProvider.OnChange = routeHooks.OnChange
+		}
 
-			log.WithError(err).Error("cannot start workspace info provider - will retry in 10 seconds")
-			time.Sleep(10 * time.Second)
+		var waker *proxy.WorkspaceWaker
+		if cfg.Proxy.WakeOnRequest != nil {
+			waker, err = proxy.NewWorkspaceWaker(*cfg.Proxy.WakeOnRequest, &cfg.Proxy, workspaceInfoProvider)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create workspace waker")
+			}
+			workspaceInfoProvider.OnStatus = waker.Observe
+			waker.Start()
+			defer func() {
+				err := waker.Close()
+				if err != nil {
+					log.WithError(err).Warn("cannot persist recently seen workspaces")
+				}
+			}()
+		}
+		health := proxy.NewHealthChecker(cfg.Health, workspaceInfoProvider)
+		transportPool := proxy.NewTransportPool(cfg.Proxy.TransportConfig)
+		var prewarmer *proxy.UpstreamPrewarmer
+		if cfg.Proxy.Prewarm != nil {
+			prewarmer = proxy.NewUpstreamPrewarmer(*cfg.Proxy.Prewarm, cfg.Proxy.WorkspacePodConfig, transportPool)
+			onStatus := workspaceInfoProvider.OnStatus
+			workspaceInfoProvider.OnStatus = func(status *wsapi.WorkspaceStatus) {
+				if onStatus != nil {
+					onStatus(status)
+				}
+				prewarmer.Observe(status)
+			}
+			prewarmer.Start()
+			defer prewarmer.Close()
+		}
+		var handshakes *proxy.SupervisorHandshakes
+		if cfg.Proxy.Handshake != nil {
+			handshakes = proxy.NewSupervisorHandshakes(*cfg.Proxy.Handshake, cfg.Proxy.WorkspacePodConfig, transportPool)
+			onStatus := workspaceInfoProvider.OnStatus
+			workspaceInfoProvider.OnStatus = func(status *wsapi.WorkspaceStatus) {
+				if onStatus != nil {
+					onStatus(status)
+				}
+				handshakes.Observe(status)
+			}
+			handshakes.Start()
+			defer handshakes.Close()
+		}
+		var portGate *proxy.PortGate
+		if cfg.Proxy.PortGate != nil {
+			portGate = proxy.NewPortGate(*cfg.Proxy.PortGate, cfg.Proxy.WorkspacePodConfig, transportPool)
+		}
+		var upstreamDiscovery *proxy.UpstreamDiscovery
+		if cfg.Proxy.UpstreamDiscovery != nil {
+			upstreamDiscovery, err = proxy.NewUpstreamDiscovery(*cfg.Proxy.UpstreamDiscovery)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create upstream discovery")
+			}
+			upstreamDiscovery.Start()
+			defer upstreamDiscovery.Close()
+		}
+		var sloTracker *proxy.SLOTracker
+		if cfg.Proxy.SLO != nil {
+			sloTracker = proxy.NewSLOTracker(*cfg.Proxy.SLO)
+		}
+		var auditLog *proxy.AuditLog
+		if cfg.Proxy.Audit != nil {
+			auditLog, err = proxy.NewAuditLog(*cfg.Proxy.Audit)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create audit log")
+			}
+			defer func() {
+				err := auditLog.Close()
+				if err != nil {
+					log.WithError(err).Warn("cannot close audit log")
+				}
+			}()
+		}
+		var experimentTracker *proxy.ExperimentTracker
+		if len(cfg.Proxy.Experiments) > 0 {
+			experimentTracker = proxy.NewExperimentTracker()
+		}
+		var activityTracker *proxy.ActivityTracker
+		if cfg.Proxy.ActivityReport != nil {
+			name, err := os.Hostname()
+			if err != nil {
+				log.WithError(err).Fatal("cannot determine the proxy name for activity reports")
+			}
+			activityTracker = proxy.NewActivityTracker(*cfg.Proxy.ActivityReport, name, workspaceInfoProvider, workspaceInfoProvider)
+		}
+		var bandwidthTracker *proxy.BandwidthTracker
+		if cfg.Proxy.Bandwidth != nil {
+			bandwidthTracker = proxy.NewBandwidthTracker(*cfg.Proxy.Bandwidth, workspaceInfoProvider)
+		}
+		var websocketThrottler *proxy.WebsocketThrottler
+		if cfg.Proxy.Routes.ThrottlesWebsockets() {
+			websocketThrottler = proxy.NewWebsocketThrottler()
+		}
+		var abuseDetector *proxy.AbuseDetector
+		if cfg.Proxy.AbuseDetection != nil {
+			abuseDetector, err = proxy.NewAbuseDetector(*cfg.Proxy.AbuseDetection, cfg.Proxy.GitpodInstallation.HostName, workspaceInfoProvider, workspaceInfoProvider)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create abuse detector")
+			}
+		}
+		var anomalyDetector *proxy.AnomalyDetector
+		if cfg.Proxy.AnomalyDetection != nil {
+			anomalyDetector = proxy.NewAnomalyDetector(*cfg.Proxy.AnomalyDetection, workspaceInfoProvider)
+			anomalyDetector.Start()
+			defer anomalyDetector.Close()
+		}
+		var authThrottler *proxy.AuthThrottler
+		if cfg.Proxy.AuthThrottle != nil {
+			authThrottler, err = proxy.NewAuthThrottler(*cfg.Proxy.AuthThrottle)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create auth throttler")
+			}
 		}
+		var portTokens *proxy.PortTokens
+		if cfg.Proxy.PortTokens != nil {
+			portTokens, err = proxy.NewPortTokens(*cfg.Proxy.PortTokens)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create port tokens")
+			}
+		}
+		var turnServer *proxy.TURNServer
+		if cfg.Proxy.TURN != nil {
+			turnServer, err = proxy.NewTURNServer(*cfg.Proxy.TURN, workspaceInfoProvider)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create TURN server")
+			}
+			turnServer.Health = health
+		}
+		var schemeRedirector *proxy.SchemeRedirector
+		if cfg.Proxy.SchemeRedirect != nil {
+			var hostHeader string
+			switch cfg.Ingress.Kind {
+			case HostBasedIngress:
+				hostHeader = cfg.Ingress.HostBasedIngress.Header
+			case PathAndHostIngress:
+				hostHeader = cfg.Ingress.PathAndHostIngress.Header
+			}
+			schemeRedirector, err = proxy.NewSchemeRedirector(*cfg.Proxy.SchemeRedirect, cfg.Proxy.ClientIP, hostHeader)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create scheme redirector")
+			}
+		}
+		var certManager *certs.Manager
+		if cfg.Certificates != nil {
+			store, err := certs.NewStore(cfg.Certificates.Storage)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create certificate store")
+			}
+			certManager, err = certs.NewManager(*cfg.Certificates, store)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create certificate manager")
+			}
+			certManager.Start()
+			defer certManager.Close()
+		}
+		connections := proxy.NewConnectionTracker()
+		newWorkspaceProxy := func(addrs []string, router proxy.WorkspaceRouter) *proxy.WorkspaceProxy {
+			p := proxy.NewWorkspaceProxy(addrs[0], cfg.Proxy, router, workspaceInfoProvider)
+			p.AdditionalAddresses = addrs[1:]
+			p.WorkspaceWaker = waker
+			p.Health = health
+			p.TransportPool = transportPool
+			p.SLOTracker = sloTracker
+			p.AuditLog = auditLog
+			p.ExperimentTracker = experimentTracker
+			p.ActivityTracker = activityTracker
+			p.BandwidthTracker = bandwidthTracker
+			p.WebsocketThrottler = websocketThrottler
+			p.AbuseDetector = abuseDetector
+			p.AnomalyDetector = anomalyDetector
+			p.AuthThrottler = authThrottler
+			p.PortTokens = portTokens
+			p.TURN = turnServer
+			p.SupervisorHandshakes = handshakes
+			p.PortGate = portGate
+			p.SchemeRedirector = schemeRedirector
+			p.UpstreamDiscovery = upstreamDiscovery
+			p.Connections = connections
+			p.Upgrades = upgrades
+			if certManager != nil {
+				p.Certificates = certManager
+			}
+			for _, addr := range addrs {
+				health.ExpectListener(addr)
+			}
+			return p
+		}
+
+		restored, err := workspaceInfoProvider.LoadSnapshot()
 		if err != nil {
-			log.WithError(err).Fatal("cannot start workspace info provider")
+			log.WithError(err).Warn("cannot load workspace info snapshot - starting with an empty cache")
+		} else if restored > 0 {
+			log.WithField("workspaces", restored).Info("restored workspace info snapshot")
+		}
+		runInfoProvider := func() (err error) {
+			for i := 0; i < wsmanConnectionAttempts; i++ {
+				err = workspaceInfoProvider.Run()
+				if err == nil {
+					return nil
+				}
+				if i == wsmanConnectionAttempts-1 {
+					continue
+				}
+
+				log.WithError(err).Error("cannot start workspace info provider - will retry in 10 seconds")
+				time.Sleep(10 * time.Second)
+			}
+			return err
+		}
+		if restored > 0 {
+			// we serve workspaces from the snapshot until we reach ws-manager
+			go func() {
+				for {
+					err := runInfoProvider()
+					if err == nil {
+						break
+					}
+					log.WithError(err).Error("cannot start workspace info provider - serving workspaces from the snapshot")
+				}
+				log.Infof("workspace info provider started")
+			}()
+		} else {
+			err = runInfoProvider()
+			if err != nil {
+				log.WithError(err).Fatal("cannot start workspace info provider")
+			}
+			log.Infof("workspace info provider started")
+		}
+		workspaceInfoProvider.StartSnapshots()
+		if activityTracker != nil {
+			activityTracker.Start()
+			defer activityTracker.Close()
+		}
+
+		if upgrades != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(upgrades.Config.HandoverTimeout))
+			n, err := upgrades.Inherit(ctx)
+			cancel()
+			if err != nil {
+				log.WithError(err).Warn("cannot take listeners over from the running ws-proxy - listening on our own")
+			} else if n == 0 {
+				log.Info("no running ws-proxy to take listeners over from")
+			}
 		}
-		log.Infof("workspace info provider started")
 
 		switch cfg.Ingress.Kind {
 		case HostBasedIngress:
-			addr := cfg.Ingress.HostBasedIngress.Address
-			go proxy.NewWorkspaceProxy(addr, cfg.Proxy, proxy.HostBasedRouter(cfg.Ingress.HostBasedIngress.Header, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffix), workspaceInfoProvider).MustServe()
-			log.WithField("ingress", cfg.Ingress.Kind).Infof("started proxying on %s", addr)
+			addrs := append([]string{cfg.Ingress.HostBasedIngress.Address}, cfg.Ingress.HostBasedIngress.AdditionalAddresses...)
+			go newWorkspaceProxy(addrs, proxy.HostBasedRouter(cfg.Ingress.HostBasedIngress.Header, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffix, cfg.Proxy.ForeignContent)).MustServe()
+			log.WithField("ingress", cfg.Ingress.Kind).Infof("started proxying on %s", strings.Join(addrs, ", "))
 		case PathAndHostIngress:
-			addr := cfg.Ingress.PathAndHostIngress.Address
-			go proxy.NewWorkspaceProxy(addr, cfg.Proxy, proxy.PathAndHostRouter(cfg.Ingress.PathAndHostIngress.TrimPrefix, cfg.Ingress.PathAndHostIngress.Header, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffix), workspaceInfoProvider).MustServe()
-			log.WithField("ingress", cfg.Ingress.Kind).Infof("started proxying on %s", addr)
+			addrs := append([]string{cfg.Ingress.PathAndHostIngress.Address}, cfg.Ingress.PathAndHostIngress.AdditionalAddresses...)
+			go newWorkspaceProxy(addrs, proxy.PathAndHostRouter(cfg.Ingress.PathAndHostIngress.TrimPrefix, cfg.Ingress.PathAndHostIngress.Header, cfg.Proxy.GitpodInstallation.WorkspaceHostSuffix)).MustServe()
+			log.WithField("ingress", cfg.Ingress.Kind).Infof("started proxying on %s", strings.Join(addrs, ", "))
 		case PathAndPortIngress:
 			var (
-				addr   = cfg.Ingress.PathAndPortIngress.Address
+				addrs  = append([]string{cfg.Ingress.PathAndPortIngress.Address}, cfg.Ingress.PathAndPortIngress.AdditionalAddresses...)
 				router = proxy.PathAndPortRouter(cfg.Ingress.PathAndPortIngress.TrimPrefix)
+				hosts  = cfg.Ingress.PathAndPortIngress.PortRangeHosts
 			)
-			go proxy.NewWorkspaceProxy(addr, cfg.Proxy, router, workspaceInfoProvider).MustServe()
-			log.WithField("ingress", cfg.Ingress.Kind).Infof("started proxying on %s", addr)
+			go newWorkspaceProxy(addrs, router).MustServe()
+			log.WithField("ingress", cfg.Ingress.Kind).Infof("started proxying on %s", strings.Join(addrs, ", "))
 
+			if len(hosts) == 0 {
+				hosts = []string{""}
+			}
 			for port := cfg.Ingress.PathAndPortIngress.Start; port <= cfg.Ingress.PathAndPortIngress.End; port++ {
-				go proxy.
-					NewWorkspaceProxy(fmt.Sprintf(":%d", port), cfg.Proxy, router, workspaceInfoProvider).
-					MustServe()
+				portAddrs := make([]string, len(hosts))
+				for i, host := range hosts {
+					portAddrs[i] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+				}
+				go newWorkspaceProxy(portAddrs, router).MustServe()
 			}
-			log.WithField("ingress", cfg.Ingress.Kind).Infof("started proxying on port range :%d-:%d", cfg.Ingress.PathAndPortIngress.Start, cfg.Ingress.PathAndPortIngress.End)
+			log.WithField("ingress", cfg.Ingress.Kind).WithField("hosts", hosts).Infof("started proxying on port range :%d-:%d", cfg.Ingress.PathAndPortIngress.Start, cfg.Ingress.PathAndPortIngress.End)
 		default:
 			log.Fatalf("unknown ingress kind %s", cfg.Ingress.Kind)
 		}
 
+		var tcpProxy *proxy.TCPProxy
+		if cfg.Proxy.TCP != nil {
+			tcpProxy = proxy.NewTCPProxy(*cfg.Proxy.TCP, cfg.Proxy, workspaceInfoProvider)
+			tcpProxy.Health = health
+			tcpProxy.Bandwidth = bandwidthTracker
+			tcpProxy.Upgrades = upgrades
+			tcpProxy.AbuseDetector = abuseDetector
+			tcpProxy.PortGate = portGate
+			tcpProxy.ExpectListeners()
+			tcpProxy.MustServe()
+			log.WithField("start", cfg.Proxy.TCP.Start).WithField("end", cfg.Proxy.TCP.End).WithField("sni", cfg.Proxy.TCP.SNIAddress).WithField("proxyProtocol", cfg.Proxy.TCP.ProxyProtocolAddress).Info("started TCP proxy")
+		}
+
+		if turnServer != nil {
+			turnServer.MustServe()
+			log.WithField("addr", cfg.Proxy.TURN.ListenAddr).WithField("publicIP", cfg.Proxy.TURN.PublicIP).Info("started TURN server")
+		}
+
 		if cfg.PProfAddr != "" {
 			go pprof.Serve(cfg.PProfAddr)
 		}
+		if cfg.Admin != nil {
+			admin, err := proxy.NewAdminHandler(*cfg.Admin)
+			if err != nil {
+				log.WithError(err).Fatal("cannot create admin interface")
+			}
+			admin.Cache = workspaceInfoProvider
+			admin.Refresher = workspaceInfoProvider
+			admin.Connections = connections
+			admin.Bandwidth = bandwidthTracker
+			admin.Abuse = abuseDetector
+			admin.Anomalies = anomalyDetector
+			admin.Config = cfg
+			admin.Routes = cfg.Proxy.Routes
+			admin.Health = health
+			admin.Upgrades = upgrades
+			go func() {
+				err := proxy.ServeAdmin(*cfg.Admin, admin, upgrades)
+				if err != nil && !upgrades.IsReplaced() {
+					log.WithError(err).Fatal("admin interface failed")
+				}
+			}()
+			log.WithField("addr", cfg.Admin.Address).Info("started admin interface")
+		}
 		if cfg.PrometheusAddr != "" {
 			reg := prometheus.NewRegistry()
 			reg.MustRegister(
 				prometheus.NewGoCollector(),
 				prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
 			)
+			err = transportPool.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+			if err != nil {
+				log.WithError(err).Fatal("cannot register transport pool metrics")
+			}
+			err = workspaceInfoProvider.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+			if err != nil {
+				log.WithError(err).Fatal("cannot register workspace info provider metrics")
+			}
+			if auditLog != nil {
+				err = auditLog.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register audit log metrics")
+				}
+			}
+			if routeHooks != nil {
+				err = routeHooks.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register route hook metrics")
+				}
+			}
+			if tcpProxy != nil {
+				err = tcpProxy.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register TCP proxy metrics")
+				}
+			}
+			if turnServer != nil {
+				err = turnServer.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register TURN metrics")
+				}
+			}
+			if experimentTracker != nil {
+				err = experimentTracker.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register experiment metrics")
+				}
+			}
+			if activityTracker != nil {
+				err = activityTracker.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register activity report metrics")
+				}
+			}
+			if bandwidthTracker != nil {
+				err = bandwidthTracker.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register bandwidth metrics")
+				}
+			}
+			if websocketThrottler != nil {
+				err = websocketThrottler.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register websocket throttle metrics")
+				}
+			}
+			if abuseDetector != nil {
+				err = abuseDetector.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register abuse detection metrics")
+				}
+			}
+			if anomalyDetector != nil {
+				err = anomalyDetector.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register anomaly detection metrics")
+				}
+			}
+			if authThrottler != nil {
+				err = authThrottler.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register auth throttle metrics")
+				}
+			}
+			if upstreamDiscovery != nil {
+				err = upstreamDiscovery.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register upstream discovery metrics")
+				}
+			}
+			if prewarmer != nil {
+				err = prewarmer.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register prewarm metrics")
+				}
+			}
+			if handshakes != nil {
+				err = handshakes.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register handshake metrics")
+				}
+			}
+			if portGate != nil {
+				err = portGate.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register port gate metrics")
+				}
+			}
+			if schemeRedirector != nil {
+				err = schemeRedirector.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register scheme redirect metrics")
+				}
+			}
+			if certManager != nil {
+				err = certManager.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register certificate metrics")
+				}
+			}
 
 			handler := http.NewServeMux()
 			handler.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+			if sloTracker != nil {
+				err = sloTracker.RegisterMetrics(prometheus.WrapRegistererWithPrefix("gitpod_ws_proxy_", reg))
+				if err != nil {
+					log.WithError(err).Fatal("cannot register SLO metrics")
+				}
+				handler.Handle("/slo", sloTracker.StatusHandler())
+			}
 
 			go func() {
-				err := http.ListenAndServe(cfg.PrometheusAddr, handler)
+				err := listenAndServe(upgrades, cfg.PrometheusAddr, handler)
 				if err != nil {
 					log.WithError(err).Error("Prometheus metrics server failed")
 				}
@@ -103,25 +530,65 @@ var runCmd = &cobra.Command{
 		}
 		if cfg.ReadinessProbeAddr != "" {
 			go func() {
-				err = http.ListenAndServe(cfg.ReadinessProbeAddr, http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
-					if workspaceInfoProvider.Ready() {
-						resp.WriteHeader(http.StatusOK)
-					} else {
-						resp.WriteHeader(http.StatusServiceUnavailable)
-					}
-				}))
-
+				err := listenAndServe(upgrades, cfg.ReadinessProbeAddr, health.Handler())
 				if err != nil {
 					log.WithError(err).Fatal("readiness endpoint server failed")
 				}
 			}()
 		}
 
+		upgradeChan := make(chan os.Signal, 1)
+		if upgrades != nil {
+			go func() {
+				// the running ws-proxy drains once we tell it we're ready, hence we must listen on all addresses first
+				for {
+					if ok, _ := health.Live(); ok {
+						break
+					}
+					time.Sleep(100 * time.Millisecond)
+				}
+				err := upgrades.Ready()
+				if err != nil {
+					log.WithError(err).Error("cannot complete upgrade handover")
+				}
+			}()
+			signal.Notify(upgradeChan, syscall.SIGUSR2)
+		}
+
 		log.Info("🚪 ws-proxy is up and running")
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-		<-sigChan
+	waitForSignal:
+		for {
+			select {
+			case <-upgradeChan:
+				status, err := upgrades.Upgrade()
+				if err != nil {
+					log.WithError(err).Warn("cannot upgrade")
+					continue
+				}
+				log.WithField("successor", status.Successor).Info("received SIGUSR2, upgrading")
+			case <-upgrades.Drained():
+				// our successor serves from the workspace info snapshot it loaded at startup and persists it from now on
+				log.Info("a successor took over and all connections drained, ws-proxy stopped.")
+				// our container stops with PID 1, hence PID 1 must outlive its successors
+				actAsInit = os.Getpid() == 1
+				return
+			case sig := <-sigChan:
+				if upgrades.IsReplaced() && os.Getpid() == 1 {
+					// we're still draining, but our successor must stop, too
+					_ = syscall.Kill(-1, sig.(syscall.Signal))
+					actAsInit = true
+					return
+				}
+				break waitForSignal
+			}
+		}
 		log.Info("received SIGTERM, ws-proxy is stopping...")
+		err = workspaceInfoProvider.PersistSnapshot()
+		if err != nil {
+			log.WithError(err).Warn("cannot persist workspace info snapshot")
+		}
 
 		defer func() {
 			log.Info("ws-proxy stopped.")
@@ -132,3 +599,18 @@ var runCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(runCmd)
 }
+
+// listenAndServe serves handler on addr with a listener we hand over to a successor ws-proxy, if upgrades are enabled
+func listenAndServe(upgrades *proxy.UpgradeController, addr string, handler http.Handler) error {
+	srv := &http.Server{Addr: addr, Handler: handler}
+	upgrades.RegisterServer(srv)
+	lns, err := upgrades.Listen([]string{addr})
+	if err != nil {
+		return err
+	}
+	err = srv.Serve(lns[0])
+	if upgrades.IsReplaced() {
+		return nil
+	}
+	return err
+}
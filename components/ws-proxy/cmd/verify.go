@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/verify"
+)
+
+var verifyOpts struct {
+	HostSuffix string
+	Port       int
+	HTTPSPort  int
+	Address    string
+	CACert     string
+	Timeout    time.Duration
+	Output     string
+}
+
+// verifyCmd checks that an installation routes workspace traffic to ws-proxy
+var verifyCmd = &cobra.Command{
+	Use:   "verify <domain>",
+	Short: "Checks DNS, certificates and routing of workspace traffic of an installation",
+	Long: `Checks that the workspace hosts of the installation at domain (e.g. gitpod.example.com) resolve via wildcard DNS,
+serve a valid certificate, reach ws-proxy's workspace and port routes, and pass websocket upgrades on.
+
+The checks run against a synthetic workspace which ws-proxy serves itself, hence they need no running workspace.
+ws-proxy serves it only while its verifyEndpoint config is enabled. The report is written as JSON and the command fails if any check failed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := verify.Config{
+			Domain:     args[0],
+			HostSuffix: verifyOpts.HostSuffix,
+			Port:       verifyOpts.Port,
+			HTTPSPort:  verifyOpts.HTTPSPort,
+			Address:    verifyOpts.Address,
+			Timeout:    verifyOpts.Timeout,
+		}
+		if fn := verifyOpts.CACert; fn != "" {
+			pem, err := os.ReadFile(fn)
+			if err != nil {
+				log.WithError(err).Fatal("cannot read CA certificate")
+			}
+			cfg.RootCAs = x509.NewCertPool()
+			if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+				log.WithField("file", fn).Fatal("no certificates found in CA certificate file")
+			}
+		}
+
+		report, err := verify.Run(context.Background(), cfg)
+		if err != nil {
+			log.WithError(err).Fatal("cannot verify installation")
+		}
+
+		out := os.Stdout
+		if fn := verifyOpts.Output; fn != "" && fn != "-" {
+			f, err := os.OpenFile(fn, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+			if err != nil {
+				log.WithError(err).Fatal("cannot write report")
+			}
+			defer f.Close()
+			out = f
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+		if err != nil {
+			log.WithError(err).Fatal("cannot write report")
+		}
+
+		if !report.OK {
+			log.Fatal("installation failed verification")
+		}
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyOpts.HostSuffix, "host-suffix", "", "suffix of workspace hosts, defaults to .ws.<domain>")
+	verifyCmd.Flags().IntVar(&verifyOpts.Port, "port", verify.DefaultPort, "workspace port whose host we check port routing with")
+	verifyCmd.Flags().IntVar(&verifyOpts.HTTPSPort, "https-port", 443, "port the installation serves HTTPS on")
+	verifyCmd.Flags().StringVar(&verifyOpts.Address, "address", "", "host:port to connect to instead of the resolved addresses, e.g. a load balancer DNS does not point to yet")
+	verifyCmd.Flags().StringVar(&verifyOpts.CACert, "ca-cert", "", "PEM file of the CA which issued the installation's certificates, defaults to the system roots")
+	verifyCmd.Flags().DurationVar(&verifyOpts.Timeout, "timeout", 10*time.Second, "timeout of a single check")
+	verifyCmd.Flags().StringVarP(&verifyOpts.Output, "output", "o", "-", "where to write the report to, - for stdout")
+	rootCmd.AddCommand(verifyCmd)
+}
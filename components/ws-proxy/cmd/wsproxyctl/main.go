@@ -0,0 +1,247 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// wsproxyctl operates a running ws-proxy through its admin interface, e.g.
+//
+//	kubectl exec deploy/ws-proxy -- /app/wsproxyctl drain
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/adminclient"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+)
+
+var opts struct {
+	Config    string
+	URL       string
+	TokenFile string
+	Timeout   time.Duration
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "wsproxyctl",
+	Short: "Operates a running ws-proxy through its admin interface",
+	Long: `Operates a running ws-proxy through its admin interface. By default wsproxyctl finds the admin
+interface and its token in the ws-proxy configuration, which works when it runs in the ws-proxy container.
+Use --url and --token-file to talk to a ws-proxy elsewhere, e.g. through kubectl port-forward.`,
+	SilenceUsage: true,
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Prints the workspace info cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.Cache(ctx)
+		})
+	},
+}
+
+var cacheRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Re-fetches the workspace info cache from ws-manager and prints what changed",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.RefreshCache(ctx)
+		})
+	},
+}
+
+var connectionsCmd = &cobra.Command{
+	Use:   "connections",
+	Short: "Lists the open client connections",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.Connections(ctx)
+		})
+	},
+}
+
+var bandwidthCmd = &cobra.Command{
+	Use:   "bandwidth [workspaceID]",
+	Short: "Prints the bandwidth usage of all workspaces or a single one",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var wsid string
+		if len(args) > 0 {
+			wsid = args[0]
+		}
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.Bandwidth(ctx, wsid)
+		})
+	},
+}
+
+var abuseCmd = &cobra.Command{
+	Use:   "abuse",
+	Short: "Lists the ports abuse detection restricted",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.Abuse(ctx)
+		})
+	},
+}
+
+var anomaliesCmd = &cobra.Command{
+	Use:   "anomalies",
+	Short: "Lists the most recent anomalies and the self-healing actions taken in response",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.Anomalies(ctx)
+		})
+	},
+}
+
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "Prints the effective route table",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.Routes(ctx)
+		})
+	},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Prints the configuration the ws-proxy runs with",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			return c.Config(ctx)
+		})
+	},
+}
+
+var drainOpts struct {
+	Status bool
+	Cancel bool
+}
+
+var drainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Makes the ws-proxy report not ready so that the load balancer stops sending it new traffic",
+	Long: `Makes the ws-proxy report not ready so that the load balancer stops sending it new traffic.
+Open connections are not affected. Draining does not survive a restart of the ws-proxy.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if drainOpts.Status && drainOpts.Cancel {
+			return xerrors.Errorf("--status and --cancel are mutually exclusive")
+		}
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			switch {
+			case drainOpts.Status:
+				return c.DrainStatus(ctx)
+			case drainOpts.Cancel:
+				return c.Undrain(ctx)
+			default:
+				return c.Drain(ctx)
+			}
+		})
+	},
+}
+
+var upgradeOpts struct {
+	Status bool
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Starts a new ws-proxy process which takes the listeners over from the running one",
+	Long: `Starts a new ws-proxy process which takes the listeners over from the running one. The new process
+reads the ws-proxy binary and configuration from disk. Once it serves, the running ws-proxy stops accepting
+connections and stops when its open connections, e.g. WebSockets, have closed or the drain timeout passed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return run(func(ctx context.Context, c *adminclient.Client) (interface{}, error) {
+			if upgradeOpts.Status {
+				return c.UpgradeStatus(ctx)
+			}
+			return c.Upgrade(ctx)
+		})
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&opts.Config, "config", "c", "/config/config.json", "ws-proxy configuration to find the admin interface in")
+	rootCmd.PersistentFlags().StringVar(&opts.URL, "url", "", "URL of the admin interface, e.g. http://localhost:60061. Overrides the configuration.")
+	rootCmd.PersistentFlags().StringVar(&opts.TokenFile, "token-file", "", "file containing the admin token. Required with --url.")
+	rootCmd.PersistentFlags().DurationVar(&opts.Timeout, "timeout", 10*time.Second, "timeout of requests to the admin interface")
+
+	drainCmd.Flags().BoolVar(&drainOpts.Status, "status", false, "print whether the ws-proxy is draining without changing it")
+	drainCmd.Flags().BoolVar(&drainOpts.Cancel, "cancel", false, "stop draining")
+	upgradeCmd.Flags().BoolVar(&upgradeOpts.Status, "status", false, "print the state of the upgrade without starting one")
+
+	cacheCmd.AddCommand(cacheRefreshCmd)
+	rootCmd.AddCommand(cacheCmd, connectionsCmd, bandwidthCmd, abuseCmd, anomaliesCmd, routesCmd, configCmd, drainCmd, upgradeCmd)
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// run calls the admin interface and prints the result as JSON
+func run(call func(ctx context.Context, c *adminclient.Client) (interface{}, error)) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	c.HTTPClient.Timeout = opts.Timeout
+
+	res, err := call(context.Background(), c)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+func newClient() (*adminclient.Client, error) {
+	if opts.URL != "" {
+		if opts.TokenFile == "" {
+			return nil, xerrors.Errorf("--url requires --token-file")
+		}
+		tkn, err := os.ReadFile(opts.TokenFile)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read admin token: %w", err)
+		}
+		return adminclient.New(opts.URL, strings.TrimSpace(string(tkn))), nil
+	}
+
+	fc, err := os.ReadFile(opts.Config)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read ws-proxy configuration: %w", err)
+	}
+	// we only need the admin config - decoding only that keeps us working across versions of the configuration
+	var cfg struct {
+		Admin *proxy.AdminConfig `json:"admin"`
+	}
+	err = json.Unmarshal(fc, &cfg)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode ws-proxy configuration: %w", err)
+	}
+	c, err := adminclient.NewFromConfig(cfg.Admin)
+	if err != nil {
+		return nil, xerrors.Errorf("%s: %w", opts.Config, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,255 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package adminclient is a client of the ws-proxy admin interface
+package adminclient
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+)
+
+// StatusError is returned when the admin interface responds with anything but 200 OK
+type StatusError struct {
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %d: %s", e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if err is a StatusError of a feature the proxy has not enabled, e.g. abuse detection
+func IsNotFound(err error) bool {
+	var serr *StatusError
+	return xerrors.As(err, &serr) && serr.StatusCode == http.StatusNotFound
+}
+
+// Client talks to the admin interface of a ws-proxy
+type Client struct {
+	// BaseURL is the URL of the admin interface, e.g. https://localhost:60061
+	BaseURL string
+	// Token is the bearer token we authenticate with. Empty if the HTTP client presents a client certificate.
+	Token string
+	// HTTPClient sends the requests
+	HTTPClient *http.Client
+}
+
+// New creates a new client of the admin interface at baseURL
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewFromConfig creates a client of the admin interface configured in cfg, which is served on this host,
+// e.g. when we run in the ws-proxy container.
+func NewFromConfig(cfg *proxy.AdminConfig) (*Client, error) {
+	if cfg == nil {
+		return nil, xerrors.Errorf("the admin interface is not configured")
+	}
+	if cfg.TokenFile == "" {
+		return nil, xerrors.Errorf("the admin interface accepts client certificates only")
+	}
+	tkn, err := os.ReadFile(cfg.TokenFile)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read admin token: %w", err)
+	}
+
+	scheme := "http"
+	if cfg.Certificate != "" {
+		scheme = "https"
+	}
+	c := New(scheme+"://"+LocalAddr(cfg.Address), strings.TrimSpace(string(tkn)))
+	c.HTTPClient.Transport = &http.Transport{
+		// we talk to the ws-proxy on this host whose certificate does not name localhost
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	return c, nil
+}
+
+// LocalAddr turns a listen address, e.g. :60060, into an address we can connect to on this host
+func LocalAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// Cache returns the content of the workspace info cache
+func (c *Client) Cache(ctx context.Context) (*proxy.CacheDump, error) {
+	var res proxy.CacheDump
+	err := c.getJSON(ctx, "/debug/cache", &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RefreshCache makes the proxy re-fetch the workspace info cache from ws-manager
+func (c *Client) RefreshCache(ctx context.Context) (*proxy.CacheRefresh, error) {
+	var res proxy.CacheRefresh
+	err := c.doJSON(ctx, http.MethodPost, "/admin/cache/refresh", &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Connections lists the open client connections
+func (c *Client) Connections(ctx context.Context) ([]proxy.ConnectionInfo, error) {
+	var res []proxy.ConnectionInfo
+	err := c.getJSON(ctx, "/debug/connections", &res)
+	return res, err
+}
+
+// Bandwidth returns the bandwidth usage of all workspaces, or of a single one if workspaceID is not empty
+func (c *Client) Bandwidth(ctx context.Context, workspaceID string) ([]proxy.BandwidthUsage, error) {
+	path := "/debug/bandwidth"
+	if workspaceID != "" {
+		path += "?" + url.Values{"workspace": []string{workspaceID}}.Encode()
+	}
+	var res []proxy.BandwidthUsage
+	err := c.getJSON(ctx, path, &res)
+	return res, err
+}
+
+// Abuse lists the ports abuse detection restricted
+func (c *Client) Abuse(ctx context.Context) ([]proxy.AbusivePort, error) {
+	var res []proxy.AbusivePort
+	err := c.getJSON(ctx, "/debug/abuse", &res)
+	return res, err
+}
+
+// Anomalies lists the most recent anomalies and the self-healing actions taken in response
+func (c *Client) Anomalies(ctx context.Context) ([]proxy.AnomalyEvent, error) {
+	var res []proxy.AnomalyEvent
+	err := c.getJSON(ctx, "/debug/anomalies", &res)
+	return res, err
+}
+
+// Routes returns the route table with defaults for everything the configuration does not set
+func (c *Client) Routes(ctx context.Context) (proxy.RouteTable, error) {
+	var res proxy.RouteTable
+	err := c.getJSON(ctx, "/debug/routes", &res)
+	return res, err
+}
+
+// Config returns the configuration the proxy runs with. We don't decode it because the proxy
+// and this client need not agree on the version of the configuration.
+func (c *Client) Config(ctx context.Context) (json.RawMessage, error) {
+	return c.Get(ctx, "/debug/config")
+}
+
+// DrainStatus tells if the proxy is draining
+func (c *Client) DrainStatus(ctx context.Context) (*proxy.DrainStatus, error) {
+	return c.drain(ctx, http.MethodGet)
+}
+
+// Drain makes the proxy report not ready so that the load balancer stops sending it new traffic
+func (c *Client) Drain(ctx context.Context) (*proxy.DrainStatus, error) {
+	return c.drain(ctx, http.MethodPost)
+}
+
+// Undrain makes the proxy report its actual readiness again
+func (c *Client) Undrain(ctx context.Context) (*proxy.DrainStatus, error) {
+	return c.drain(ctx, http.MethodDelete)
+}
+
+func (c *Client) drain(ctx context.Context, method string) (*proxy.DrainStatus, error) {
+	var res proxy.DrainStatus
+	err := c.doJSON(ctx, method, "/admin/drain", &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpgradeStatus describes the state of an upgrade of the proxy
+func (c *Client) UpgradeStatus(ctx context.Context) (*proxy.UpgradeStatus, error) {
+	return c.upgrade(ctx, http.MethodGet)
+}
+
+// Upgrade makes the proxy start a successor which takes its listeners over, after which the proxy drains and stops
+func (c *Client) Upgrade(ctx context.Context) (*proxy.UpgradeStatus, error) {
+	return c.upgrade(ctx, http.MethodPost)
+}
+
+func (c *Client) upgrade(ctx context.Context, method string) (*proxy.UpgradeStatus, error) {
+	var res proxy.UpgradeStatus
+	err := c.doJSON(ctx, method, "/admin/upgrade", &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Get GETs a path of the admin interface, e.g. a pprof profile, and returns the response body
+func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, path)
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, res interface{}) error {
+	return c.doJSON(ctx, http.MethodGet, path, res)
+}
+
+func (c *Client) doJSON(ctx context.Context, method, path string, res interface{}) error {
+	body, err := c.do(ctx, method, path)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(body, res)
+	if err != nil {
+		return xerrors.Errorf("cannot decode response of %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Path: path, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return body, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package adminclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+)
+
+type fakeCache []*proxy.WorkspaceInfo
+
+func (c fakeCache) DumpCache() ([]*proxy.WorkspaceInfo, uint64) { return c, 42 }
+
+func (c fakeCache) RefreshCache(ctx context.Context) (*proxy.CacheRefresh, error) {
+	return &proxy.CacheRefresh{Updated: len(c), Version: 43}, nil
+}
+
+type fakeInfoProviderHealth struct{}
+
+func (fakeInfoProviderHealth) Health() proxy.InfoProviderHealth {
+	return proxy.InfoProviderHealth{Connected: true}
+}
+
+func newTestClient(t *testing.T) (*Client, *proxy.HealthChecker) {
+	cache := fakeCache{{WorkspaceID: "amaranth-smelt-9ba20cc1", IDEPublicPort: "443"}}
+	health := proxy.NewHealthChecker(proxy.HealthConfig{}, fakeInfoProviderHealth{})
+	srv := httptest.NewServer(&proxy.AdminHandler{
+		Token:       "s3cr3t",
+		Cache:       cache,
+		Refresher:   cache,
+		Connections: proxy.NewConnectionTracker(),
+		Config:      map[string]string{"hello": "world"},
+		Routes:      proxy.RouteTable{proxy.RouteSupervisor: {ResponseTimeout: util.Duration(1000)}},
+		Health:      health,
+	})
+	t.Cleanup(srv.Close)
+
+	return New(srv.URL+"/", "s3cr3t"), health
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	client, health := newTestClient(t)
+
+	dump, err := client.Cache(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump.Version != 42 || len(dump.Workspaces) != 1 || dump.Workspaces[0].WorkspaceID != "amaranth-smelt-9ba20cc1" {
+		t.Errorf("unexpected cache dump: %+v", dump)
+	}
+
+	refresh, err := client.RefreshCache(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&proxy.CacheRefresh{Updated: 1, Version: 43}, refresh); diff != "" {
+		t.Errorf("unexpected cache refresh (-want +got):\n%s", diff)
+	}
+
+	conns, err := client.Connections(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conns) != 0 {
+		t.Errorf("unexpected connections: %v", conns)
+	}
+
+	routes, err := client.Routes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := routes[proxy.RouteSupervisor]; rc == nil || rc.Upstream != proxy.UpstreamSupervisor || rc.ResponseTimeout != 1000 {
+		t.Errorf("unexpected supervisor route: %+v", rc)
+	}
+	if rc := routes[proxy.RoutePort]; rc == nil || rc.Upstream != proxy.UpstreamPort {
+		t.Errorf("route table lacks default port route: %+v", routes)
+	}
+
+	cfg, err := client.Config(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfgContent map[string]string
+	err = json.Unmarshal(cfg, &cfgContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"hello": "world"}, cfgContent); diff != "" {
+		t.Errorf("unexpected config (-want +got):\n%s", diff)
+	}
+
+	status, err := client.Drain(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Draining || !health.Draining() {
+		t.Errorf("proxy does not drain: %+v", status)
+	}
+	status, err = client.DrainStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Draining {
+		t.Errorf("drain status does not report draining")
+	}
+	status, err = client.Undrain(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Draining || health.Draining() {
+		t.Errorf("proxy still drains: %+v", status)
+	}
+
+	// abuse detection, anomaly detection and bandwidth tracking are not enabled
+	_, err = client.Abuse(ctx)
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	_, err = client.Anomalies(ctx)
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	_, err = client.Bandwidth(ctx, "amaranth-smelt-9ba20cc1")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestClientUnauthorized(t *testing.T) {
+	client, _ := newTestClient(t)
+	client.Token = "foobar"
+
+	_, err := client.Cache(context.Background())
+	var serr *StatusError
+	if !xerrors.As(err, &serr) || serr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+}
+
+func TestLocalAddr(t *testing.T) {
+	tests := map[string]string{
+		":60061":         "localhost:60061",
+		"0.0.0.0:60061":  "localhost:60061",
+		"[::]:60061":     "localhost:60061",
+		"10.0.0.1:60061": "10.0.0.1:60061",
+		"no-port":        "no-port",
+	}
+	for addr, expectation := range tests {
+		if act := LocalAddr(addr); act != expectation {
+			t.Errorf("LocalAddr(%q) = %q, expected %q", addr, act, expectation)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package bundle writes support bundles: a gzipped tar archive of diagnostic files and a manifest
+// which records what we could and could not collect.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// ManifestName is the name of the manifest in the archive
+const ManifestName = "manifest.json"
+
+// Redacted replaces secret values
+const Redacted = "[redacted]"
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	Created time.Time         `json:"created"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Items   []Item            `json:"items"`
+}
+
+// Item is an entry of the bundle
+type Item struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+	// Error explains why we could not collect the item. Items with an error are not in the archive.
+	Error string `json:"error,omitempty"`
+}
+
+// Writer writes a bundle
+type Writer struct {
+	gz       *gzip.Writer
+	tar      *tar.Writer
+	manifest Manifest
+}
+
+// NewWriter creates a bundle writer which writes the archive to out
+func NewWriter(out io.Writer, labels map[string]string) *Writer {
+	gz := gzip.NewWriter(out)
+	return &Writer{
+		gz:  gz,
+		tar: tar.NewWriter(gz),
+		manifest: Manifest{
+			Created: time.Now().UTC(),
+			Labels:  labels,
+		},
+	}
+}
+
+// Add collects an item and adds it to the bundle. If collect fails we record the error in the manifest and carry on,
+// hence Add only returns an error if it cannot write the archive.
+func (w *Writer) Add(name string, collect func() ([]byte, error)) error {
+	content, err := collect()
+	if err != nil {
+		w.manifest.Items = append(w.manifest.Items, Item{Name: name, Error: err.Error()})
+		return nil
+	}
+
+	w.manifest.Items = append(w.manifest.Items, Item{Name: name, Size: len(content)})
+	return w.write(name, content)
+}
+
+func (w *Writer) write(name string, content []byte) error {
+	err := w.tar.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: w.manifest.Created,
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot write %s: %w", name, err)
+	}
+	_, err = w.tar.Write(content)
+	if err != nil {
+		return xerrors.Errorf("cannot write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Manifest returns the manifest of what was added so far
+func (w *Writer) Manifest() Manifest {
+	return w.manifest
+}
+
+// Close writes the manifest and finishes the archive
+func (w *Writer) Close() error {
+	manifest, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = w.write(ManifestName, manifest)
+	if err != nil {
+		return err
+	}
+	err = w.tar.Close()
+	if err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// secretKey matches the names of configuration fields which hold secrets. Fields which name a file holding
+// the secret are fine, as are the key and crt fields which are paths throughout ws-proxy's configuration.
+var secretKey = regexp.MustCompile(`(?i)(token|secret|password|credential|apikey)`)
+
+// RedactJSON replaces the string values of fields which look like they hold secrets
+func RedactJSON(content []byte) ([]byte, error) {
+	var v interface{}
+	err := json.Unmarshal(content, &v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(redact(v), "", "  ")
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, isString := value.(string); isString && secretKey.MatchString(key) && !strings.HasSuffix(strings.ToLower(key), "file") {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redact(value)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// maxTailBytes limits how far from the end of a file we look for the lines TailFile returns
+const maxTailBytes = 16 << 20
+
+// TailFile returns the last n lines of a file
+func TailFile(fn string, n int) ([]byte, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := stat.Size() - maxTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		// the first line is likely incomplete
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+
+	content = bytes.TrimSuffix(content, []byte("\n"))
+	lines := bytes.Split(content, []byte("\n"))
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	res := bytes.Join(lines, []byte("\n"))
+	if len(res) > 0 {
+		res = append(res, '\n')
+	}
+	return res, nil
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, map[string]string{"hostname": "ws-proxy-1"})
+	err := w.Add("config.json", func() ([]byte, error) { return []byte("{}"), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Add("metrics.txt", func() ([]byte, error) { return nil, xerrors.Errorf("metrics are not served") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(content)
+	}
+
+	if files["config.json"] != "{}" {
+		t.Errorf("unexpected config.json: %q", files["config.json"])
+	}
+	if _, ok := files["metrics.txt"]; ok {
+		t.Errorf("items we could not collect must not be in the archive")
+	}
+	var manifest Manifest
+	err = json.Unmarshal([]byte(files[ManifestName]), &manifest)
+	if err != nil {
+		t.Fatalf("cannot read manifest: %v", err)
+	}
+	expectation := []Item{
+		{Name: "config.json", Size: 2},
+		{Name: "metrics.txt", Error: "metrics are not served"},
+	}
+	if diff := cmp.Diff(expectation, manifest.Items); diff != "" {
+		t.Errorf("unexpected manifest (-want +got):\n%s", diff)
+	}
+	if manifest.Labels["hostname"] != "ws-proxy-1" {
+		t.Errorf("unexpected labels: %v", manifest.Labels)
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	input := `{
+		"proxy": {"https": {"key": "/mnt/certs/tls.key", "crt": "/mnt/certs/tls.crt"}},
+		"admin": {"tokenFile": "/mnt/admin/token", "token": "s3cr3t"},
+		"routeHooks": {"hooks": [{"url": "https://example.com", "secret": "s3cr3t", "secretFile": "/mnt/hook"}]},
+		"certificates": {"storage": {"secret": {"namespace": "default"}}, "dns": {"cloudflare": {"apiToken": "s3cr3t"}}},
+		"password": 42
+	}`
+	act, err := RedactJSON([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res map[string]interface{}
+	err = json.Unmarshal(act, &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(act), "s3cr3t") {
+		t.Errorf("secrets were not redacted:\n%s", act)
+	}
+	for _, keep := range []string{"/mnt/certs/tls.key", "/mnt/admin/token", "/mnt/hook", `"namespace": "default"`, "42"} {
+		if !strings.Contains(string(act), keep) {
+			t.Errorf("expected %s to be kept:\n%s", keep, act)
+		}
+	}
+
+	_, err = RedactJSON([]byte("not json"))
+	if err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestTailFile(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "access.log")
+	var content strings.Builder
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&content, "line %d\n", i)
+	}
+	err := os.WriteFile(fn, []byte(content.String()), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Lines       int
+		Expectation string
+	}{
+		{Lines: 3, Expectation: "line 7\nline 8\nline 9\n"},
+		{Lines: 1, Expectation: "line 9\n"},
+		{Lines: 100, Expectation: content.String()},
+	}
+	for _, test := range tests {
+		act, err := TailFile(fn, test.Lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(act) != test.Expectation {
+			t.Errorf("tail %d: expected %q, got %q", test.Lines, test.Expectation, string(act))
+		}
+	}
+
+	_, err = TailFile(filepath.Join(t.TempDir(), "missing.log"), 10)
+	if err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
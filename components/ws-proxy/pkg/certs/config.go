@@ -0,0 +1,169 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package certs
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultRenewBefore      = 30 * 24 * time.Hour
+	defaultCheckInterval    = time.Hour
+	defaultPropagationDelay = 30 * time.Second
+)
+
+// Config configures the certificates ws-proxy obtains from an ACME CA, e.g. Let's Encrypt, and serves on its TLS listener
+type Config struct {
+	// DirectoryURL is the ACME directory of the CA. Defaults to Let's Encrypt.
+	DirectoryURL string `json:"directoryURL,omitempty"`
+	// Email is the contact of the ACME account, to which the CA sends expiry notices
+	Email string `json:"email"`
+
+	// Certificates are the certificates we obtain and renew
+	Certificates []CertificateConfig `json:"certificates"`
+	// DNS solves the DNS-01 challenges which prove we control the domains
+	DNS DNSConfig `json:"dns"`
+	// Storage is where we keep the certificates and the ACME account key
+	Storage StorageConfig `json:"storage"`
+
+	// RenewBefore is how long before a certificate expires we renew it. Defaults to 30 days.
+	RenewBefore util.Duration `json:"renewBefore,omitempty"`
+	// CheckInterval is how often we check whether certificates need renewal. Defaults to an hour.
+	CheckInterval util.Duration `json:"checkInterval,omitempty"`
+	// PropagationDelay is how long we wait for challenge records to propagate before we ask the CA to check them.
+	// Defaults to 30 seconds.
+	PropagationDelay util.Duration `json:"propagationDelay,omitempty"`
+}
+
+// CertificateConfig configures a certificate
+type CertificateConfig struct {
+	// Name identifies the certificate in the storage
+	Name string `json:"name"`
+	// Domains are the names the certificate is valid for, e.g. *.ws.gitpod.example.com
+	Domains []string `json:"domains"`
+}
+
+// DNSConfig configures how we solve DNS-01 challenges
+type DNSConfig struct {
+	// Provider is the name of a registered DNS provider, e.g. webhook or cloudflare
+	Provider string `json:"provider"`
+	// Options configure the provider
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// StorageConfig configures where we keep certificates. Exactly one of the locations must be set.
+type StorageConfig struct {
+	// Directory stores certificates in a local directory
+	Directory string `json:"directory,omitempty"`
+	// Secret stores certificates in Kubernetes secrets, which all replicas of ws-proxy share
+	Secret *SecretStorageConfig `json:"secret,omitempty"`
+}
+
+// SecretStorageConfig configures the Kubernetes secrets we store certificates in
+type SecretStorageConfig struct {
+	// Namespace is the namespace of the secrets
+	Namespace string `json:"namespace"`
+	// Prefix is the prefix of the secret names. Certificates are stored in <prefix>-<name>, the account key in <prefix>-account.
+	Prefix string `json:"prefix"`
+	// Kubeconfig is the kubeconfig we use to talk to Kubernetes. Defaults to the in-cluster configuration.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+}
+
+var validName = validation.Match(regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`))
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.DirectoryURL, is.URL),
+		validation.Field(&c.Email, validation.Required, is.Email),
+		validation.Field(&c.Certificates, validation.Required),
+		validation.Field(&c.DNS),
+		validation.Field(&c.Storage),
+		validation.Field(&c.RenewBefore, validation.Min(util.Duration(0))),
+		validation.Field(&c.CheckInterval, validation.Min(util.Duration(0))),
+		validation.Field(&c.PropagationDelay, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid certificates config: %w", err)
+	}
+
+	names := make(map[string]struct{}, len(c.Certificates))
+	for _, cert := range c.Certificates {
+		if _, exists := names[cert.Name]; exists {
+			return xerrors.Errorf("invalid certificates config: certificate %s is configured more than once", cert.Name)
+		}
+		names[cert.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c CertificateConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, validName),
+		validation.Field(&c.Domains, validation.Required, validation.Each(validation.By(validateDomain))),
+	)
+}
+
+func validateDomain(value interface{}) error {
+	domain, _ := value.(string)
+	if len(domain) > 2 && domain[:2] == "*." {
+		domain = domain[2:]
+	}
+	return is.DNSName.Validate(domain)
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c DNSConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Provider, validation.Required, validation.By(func(value interface{}) error {
+			if _, ok := dnsProviders[c.Provider]; !ok {
+				return xerrors.Errorf("unknown DNS provider %s", c.Provider)
+			}
+			return nil
+		})),
+	)
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c StorageConfig) Validate() error {
+	if (c.Directory == "") == (c.Secret == nil) {
+		return xerrors.Errorf("storage needs either a directory or a secret")
+	}
+	if c.Secret == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c.Secret,
+		validation.Field(&c.Secret.Namespace, validation.Required),
+		validation.Field(&c.Secret.Prefix, validation.Required, validName),
+	)
+}
+
+func (c *Config) directoryURL() string {
+	if c.DirectoryURL == "" {
+		return acme.LetsEncryptURL
+	}
+	return c.DirectoryURL
+}
+
+func durationOrDefault(d util.Duration, def time.Duration) time.Duration {
+	if d == 0 {
+		return def
+	}
+	return time.Duration(d)
+}
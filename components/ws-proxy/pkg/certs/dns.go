@@ -0,0 +1,279 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// DNSProvider publishes the TXT records of DNS-01 challenges
+type DNSProvider interface {
+	// Present publishes a TXT record with the value at the fully qualified name, e.g. _acme-challenge.example.com
+	Present(ctx context.Context, fqdn, value string) error
+	// CleanUp removes a TXT record Present published
+	CleanUp(ctx context.Context, fqdn, value string) error
+}
+
+// DNSProviderFactory creates a DNS provider from its options
+type DNSProviderFactory func(options json.RawMessage) (DNSProvider, error)
+
+var dnsProviders = map[string]DNSProviderFactory{
+	"webhook":    newWebhookDNSProvider,
+	"cloudflare": newCloudflareDNSProvider,
+}
+
+// RegisterDNSProvider makes a DNS provider available under a name. It must be called before the configuration is validated.
+func RegisterDNSProvider(name string, factory DNSProviderFactory) {
+	dnsProviders[name] = factory
+}
+
+// NewDNSProvider creates the DNS provider the configuration names
+func NewDNSProvider(cfg DNSConfig) (DNSProvider, error) {
+	factory, ok := dnsProviders[cfg.Provider]
+	if !ok {
+		return nil, xerrors.Errorf("unknown DNS provider %s", cfg.Provider)
+	}
+	p, err := factory(cfg.Options)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create DNS provider %s: %w", cfg.Provider, err)
+	}
+	return p, nil
+}
+
+// challengeRecordName returns the name of the TXT record which proves control over a domain
+func challengeRecordName(domain string) string {
+	return "_acme-challenge." + strings.TrimPrefix(domain, "*.")
+}
+
+func readToken(fn string) (string, error) {
+	if fn == "" {
+		return "", nil
+	}
+	tkn, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return "", xerrors.Errorf("cannot read token: %w", err)
+	}
+	return strings.TrimSpace(string(tkn)), nil
+}
+
+// region webhook
+
+// WebhookDNSOptions configure the webhook DNS provider, which delegates publishing challenge records to an
+// operator provided service. We POST {"action": "present"|"cleanup", "fqdn": "...", "value": "..."} to the URL.
+type WebhookDNSOptions struct {
+	URL string `json:"url"`
+	// TokenFile contains a token we send as bearer token
+	TokenFile string `json:"tokenFile,omitempty"`
+	// Timeout is the time we allow the service to respond. Defaults to a minute.
+	Timeout util.Duration `json:"timeout,omitempty"`
+}
+
+type webhookDNSProvider struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+func newWebhookDNSProvider(options json.RawMessage) (DNSProvider, error) {
+	var opts WebhookDNSOptions
+	err := json.Unmarshal(options, &opts)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid options: %w", err)
+	}
+	err = validation.ValidateStruct(&opts,
+		validation.Field(&opts.URL, validation.Required, is.URL),
+		validation.Field(&opts.Timeout, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	tkn, err := readToken(opts.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookDNSProvider{URL: opts.URL, Token: tkn, Client: &http.Client{Timeout: durationOrDefault(opts.Timeout, time.Minute)}}, nil
+}
+
+func (p *webhookDNSProvider) Present(ctx context.Context, fqdn, value string) error {
+	return p.call(ctx, "present", fqdn, value)
+}
+
+func (p *webhookDNSProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	return p.call(ctx, "cleanup", fqdn, value)
+}
+
+func (p *webhookDNSProvider) call(ctx context.Context, action, fqdn, value string) error {
+	body, err := json.Marshal(map[string]string{"action": action, "fqdn": fqdn, "value": value})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("cannot %s challenge record: %w", action, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return xerrors.Errorf("cannot %s challenge record: webhook responded with %s", action, resp.Status)
+	}
+	return nil
+}
+
+// endregion
+
+// region cloudflare
+
+const cloudflareAPI = "https://api.cloudflare.com/client/v4"
+
+// CloudflareDNSOptions configure the Cloudflare DNS provider
+type CloudflareDNSOptions struct {
+	// ZoneID is the zone the challenge records are published in
+	ZoneID string `json:"zoneID"`
+	// APITokenFile contains an API token with DNS edit permission on the zone
+	APITokenFile string `json:"apiTokenFile"`
+	// BaseURL is the Cloudflare API. Defaults to the public API.
+	BaseURL string `json:"baseURL,omitempty"`
+}
+
+type cloudflareDNSProvider struct {
+	ZoneID  string
+	Token   string
+	BaseURL string
+	Client  *http.Client
+
+	mu      sync.Mutex
+	records map[string]string
+}
+
+func newCloudflareDNSProvider(options json.RawMessage) (DNSProvider, error) {
+	var opts CloudflareDNSOptions
+	err := json.Unmarshal(options, &opts)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid options: %w", err)
+	}
+	err = validation.ValidateStruct(&opts,
+		validation.Field(&opts.ZoneID, validation.Required),
+		validation.Field(&opts.APITokenFile, validation.Required),
+		validation.Field(&opts.BaseURL, is.URL),
+	)
+	if err != nil {
+		return nil, err
+	}
+	tkn, err := readToken(opts.APITokenFile)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = cloudflareAPI
+	}
+	return &cloudflareDNSProvider{
+		ZoneID:  opts.ZoneID,
+		Token:   tkn,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		records: make(map[string]string),
+	}, nil
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result struct {
+		ID string `json:"id"`
+	} `json:"result"`
+}
+
+func (p *cloudflareDNSProvider) Present(ctx context.Context, fqdn, value string) error {
+	res, err := p.call(ctx, http.MethodPost, "/dns_records", map[string]interface{}{
+		"type":    "TXT",
+		"name":    fqdn,
+		"content": value,
+		"ttl":     120,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.records[fqdn+"/"+value] = res.Result.ID
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *cloudflareDNSProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	p.mu.Lock()
+	id, ok := p.records[fqdn+"/"+value]
+	delete(p.records, fqdn+"/"+value)
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	_, err := p.call(ctx, http.MethodDelete, "/dns_records/"+id, nil)
+	return err
+}
+
+func (p *cloudflareDNSProvider) call(ctx context.Context, method, path string, body interface{}) (*cloudflareResponse, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/zones/%s%s", p.BaseURL, p.ZoneID, path), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.Token)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot call Cloudflare API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var res cloudflareResponse
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode Cloudflare API response (%s): %w", resp.Status, err)
+	}
+	if !res.Success {
+		msgs := make([]string, 0, len(res.Errors))
+		for _, e := range res.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return nil, xerrors.Errorf("Cloudflare API failed (%s): %s", resp.Status, strings.Join(msgs, "; "))
+	}
+	return &res, nil
+}
+
+// endregion
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package certs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type recordedCall struct {
+	Method string
+	Path   string
+	Auth   string
+	Body   map[string]interface{}
+}
+
+func recordingServer(t *testing.T, respond func(w http.ResponseWriter, call recordedCall)) (*httptest.Server, *[]recordedCall) {
+	var calls []recordedCall
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := recordedCall{Method: r.Method, Path: r.URL.Path, Auth: r.Header.Get("Authorization")}
+		body, _ := ioutil.ReadAll(r.Body)
+		if len(body) > 0 {
+			_ = json.Unmarshal(body, &call.Body)
+		}
+		calls = append(calls, call)
+		respond(w, call)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func writeToken(t *testing.T) string {
+	fn := filepath.Join(t.TempDir(), "token")
+	err := ioutil.WriteFile(fn, []byte("secret-token\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fn
+}
+
+func TestWebhookDNSProvider(t *testing.T) {
+	srv, calls := recordingServer(t, func(w http.ResponseWriter, call recordedCall) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	p, err := NewDNSProvider(DNSConfig{
+		Provider: "webhook",
+		Options:  json.RawMessage(fmt.Sprintf(`{"url": %q, "tokenFile": %q}`, srv.URL+"/dns", writeToken(t))),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	err = p.Present(ctx, challengeRecordName("*.ws.example.com"), "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.CleanUp(ctx, challengeRecordName("*.ws.example.com"), "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []recordedCall{
+		{Method: "POST", Path: "/dns", Auth: "Bearer secret-token", Body: map[string]interface{}{"action": "present", "fqdn": "_acme-challenge.ws.example.com", "value": "challenge"}},
+		{Method: "POST", Path: "/dns", Auth: "Bearer secret-token", Body: map[string]interface{}{"action": "cleanup", "fqdn": "_acme-challenge.ws.example.com", "value": "challenge"}},
+	}
+	if diff := cmp.Diff(expected, *calls); diff != "" {
+		t.Errorf("unexpected webhook calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestCloudflareDNSProvider(t *testing.T) {
+	srv, calls := recordingServer(t, func(w http.ResponseWriter, call recordedCall) {
+		if call.Body != nil && call.Body["content"] == "rejected" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success": false, "errors": [{"message": "record rejected"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"success": true, "result": {"id": "record-1"}}`)
+	})
+
+	p, err := NewDNSProvider(DNSConfig{
+		Provider: "cloudflare",
+		Options:  json.RawMessage(fmt.Sprintf(`{"zoneID": "zone-1", "apiTokenFile": %q, "baseURL": %q}`, writeToken(t), srv.URL)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	err = p.Present(ctx, "_acme-challenge.example.com", "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.CleanUp(ctx, "_acme-challenge.example.com", "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// records we did not create are none of our business
+	err = p.CleanUp(ctx, "_acme-challenge.example.com", "someone-elses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Present(ctx, "_acme-challenge.example.com", "rejected")
+	if err == nil {
+		t.Error("expected an error if Cloudflare rejects the record")
+	}
+
+	expected := []recordedCall{
+		{Method: "POST", Path: "/zones/zone-1/dns_records", Auth: "Bearer secret-token", Body: map[string]interface{}{"type": "TXT", "name": "_acme-challenge.example.com", "content": "challenge", "ttl": float64(120)}},
+		{Method: "DELETE", Path: "/zones/zone-1/dns_records/record-1", Auth: "Bearer secret-token"},
+		{Method: "POST", Path: "/zones/zone-1/dns_records", Auth: "Bearer secret-token", Body: map[string]interface{}{"type": "TXT", "name": "_acme-challenge.example.com", "content": "rejected", "ttl": float64(120)}},
+	}
+	if diff := cmp.Diff(expected, *calls); diff != "" {
+		t.Errorf("unexpected Cloudflare API calls (-want +got):\n%s", diff)
+	}
+}
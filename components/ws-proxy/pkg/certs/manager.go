@@ -0,0 +1,394 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package certs
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// issuer obtains certificates for a set of domains
+type issuer interface {
+	Issue(ctx context.Context, domains []string, key crypto.Signer) (der [][]byte, err error)
+}
+
+// Manager obtains certificates from an ACME CA, renews them before they expire and serves them by SNI
+type Manager struct {
+	Config Config
+	Store  Store
+
+	issuer issuer
+
+	mu    sync.RWMutex
+	certs map[string]*tls.Certificate
+
+	expiry   *prometheus.GaugeVec
+	renewals *prometheus.CounterVec
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewManager creates a new certificate manager
+func NewManager(cfg Config, store Store) (*Manager, error) {
+	dns, err := NewDNSProvider(cfg.DNS)
+	if err != nil {
+		return nil, err
+	}
+	return newManager(cfg, store, &acmeIssuer{Config: cfg, Store: store, DNS: dns}), nil
+}
+
+func newManager(cfg Config, store Store, iss issuer) *Manager {
+	return &Manager{
+		Config: cfg,
+		Store:  store,
+		issuer: iss,
+		certs:  make(map[string]*tls.Certificate),
+		expiry: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "certificate_expiry_timestamp_seconds",
+			Help: "Time at which the certificates we serve expire",
+		}, []string{"certificate"}),
+		renewals: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "certificate_renewals_total",
+			Help: "Certificates we obtained from the ACME CA, by outcome",
+		}, []string{"certificate", "outcome"}),
+		stop: make(chan struct{}),
+	}
+}
+
+// RegisterMetrics registers the manager's metrics
+func (m *Manager) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.expiry, m.renewals} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start loads the stored certificates and keeps them renewed in the background
+func (m *Manager) Start() {
+	go func() {
+		interval := durationOrDefault(m.Config.CheckInterval, defaultCheckInterval)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			m.reconcile(ctx)
+			cancel()
+
+			select {
+			case <-t.C:
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops renewing certificates
+func (m *Manager) Close() {
+	m.once.Do(func() { close(m.stop) })
+}
+
+// GetCertificate returns the certificate for the server name of a TLS handshake. It returns nil if no certificate
+// we manage is valid for the name, so that the caller can fall back to another certificate.
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.TrimSuffix(strings.ToLower(hello.ServerName), ".")
+	if name == "" {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, c := range m.certs {
+		if c.Leaf.VerifyHostname(name) == nil {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// reconcile makes sure all certificates are valid for their domains and far from expiry
+func (m *Manager) reconcile(ctx context.Context) {
+	for _, cc := range m.Config.Certificates {
+		err := m.reconcileCertificate(ctx, cc)
+		if err != nil {
+			log.WithError(err).WithField("certificate", cc.Name).Error("cannot obtain certificate - will try again")
+		}
+	}
+}
+
+func (m *Manager) reconcileCertificate(ctx context.Context, cc CertificateConfig) error {
+	// another replica might have renewed the certificate, hence we always look at the store first
+	bundle, err := m.Store.LoadCertificate(ctx, cc.Name)
+	if err != nil {
+		return err
+	}
+	if bundle != nil {
+		crt, err := parseBundle(bundle)
+		if err != nil {
+			log.WithError(err).WithField("certificate", cc.Name).Warn("stored certificate is invalid - obtaining a new one")
+		} else {
+			m.install(cc.Name, crt)
+			if !m.needsRenewal(cc, crt.Leaf) {
+				return nil
+			}
+		}
+	}
+
+	log.WithField("certificate", cc.Name).WithField("domains", cc.Domains).Info("obtaining certificate")
+	bundle, err = m.obtain(ctx, cc)
+	if err != nil {
+		m.renewals.WithLabelValues(cc.Name, "failure").Inc()
+		return err
+	}
+	crt, err := parseBundle(bundle)
+	if err != nil {
+		m.renewals.WithLabelValues(cc.Name, "failure").Inc()
+		return xerrors.Errorf("CA issued an unusable certificate: %w", err)
+	}
+	m.renewals.WithLabelValues(cc.Name, "success").Inc()
+	m.install(cc.Name, crt)
+	log.WithField("certificate", cc.Name).WithField("notAfter", crt.Leaf.NotAfter).Info("obtained certificate")
+
+	err = m.Store.SaveCertificate(ctx, cc.Name, bundle)
+	if err != nil {
+		// we serve the certificate nonetheless and try storing it again once it's due for renewal
+		return xerrors.Errorf("cannot store certificate: %w", err)
+	}
+	return nil
+}
+
+// needsRenewal returns true if a certificate expires soon or does not cover all domains
+func (m *Manager) needsRenewal(cc CertificateConfig, leaf *x509.Certificate) bool {
+	if time.Until(leaf.NotAfter) < durationOrDefault(m.Config.RenewBefore, defaultRenewBefore) {
+		return true
+	}
+	for _, d := range cc.Domains {
+		if !containsName(leaf.DNSNames, d) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) obtain(ctx context.Context, cc CertificateConfig) (*Bundle, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot generate key: %w", err)
+	}
+	der, err := m.issuer.Issue(ctx, cc.Domains, key)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot encode key: %w", err)
+	}
+	var res Bundle
+	for _, c := range der {
+		res.Certificate = append(res.Certificate, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c})...)
+	}
+	res.Key = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return &res, nil
+}
+
+func (m *Manager) install(name string, crt *tls.Certificate) {
+	m.mu.Lock()
+	m.certs[name] = crt
+	m.mu.Unlock()
+	m.expiry.WithLabelValues(name).Set(float64(crt.Leaf.NotAfter.Unix()))
+}
+
+func parseBundle(bundle *Bundle) (*tls.Certificate, error) {
+	crt, err := tls.X509KeyPair(bundle.Certificate, bundle.Key)
+	if err != nil {
+		return nil, err
+	}
+	crt.Leaf, err = x509.ParseCertificate(crt.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	return &crt, nil
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// acmeIssuer obtains certificates from an ACME CA, proving control over the domains using DNS-01 challenges
+type acmeIssuer struct {
+	Config Config
+	Store  Store
+	DNS    DNSProvider
+
+	mu     sync.Mutex
+	client *acme.Client
+}
+
+func (i *acmeIssuer) Issue(ctx context.Context, domains []string, key crypto.Signer) ([][]byte, error) {
+	client, err := i.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create order: %w", err)
+	}
+	for _, u := range order.AuthzURLs {
+		err = i.authorize(ctx, client, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+	order, err = client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, xerrors.Errorf("order failed: %w", err)
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: domains}, key)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create certificate request: %w", err)
+	}
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot finalize order: %w", err)
+	}
+	return der, nil
+}
+
+// authorize solves the DNS-01 challenge of an authorization
+func (i *acmeIssuer) authorize(ctx context.Context, client *acme.Client, url string) error {
+	authz, err := client.GetAuthorization(ctx, url)
+	if err != nil {
+		return xerrors.Errorf("cannot get authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var chal *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return xerrors.Errorf("CA offers no dns-01 challenge for %s", authz.Identifier.Value)
+	}
+
+	value, err := client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return xerrors.Errorf("cannot compute challenge record: %w", err)
+	}
+	fqdn := challengeRecordName(authz.Identifier.Value)
+	err = i.DNS.Present(ctx, fqdn, value)
+	if err != nil {
+		return xerrors.Errorf("cannot publish challenge record for %s: %w", authz.Identifier.Value, err)
+	}
+	defer func() {
+		// the context might have expired by now, but we still want to clean up
+		cctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		err := i.DNS.CleanUp(cctx, fqdn, value)
+		if err != nil {
+			log.WithError(err).WithField("fqdn", fqdn).Warn("cannot remove challenge record")
+		}
+	}()
+
+	select {
+	case <-time.After(durationOrDefault(i.Config.PropagationDelay, defaultPropagationDelay)):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	_, err = client.Accept(ctx, chal)
+	if err != nil {
+		return xerrors.Errorf("cannot accept challenge for %s: %w", authz.Identifier.Value, err)
+	}
+	_, err = client.WaitAuthorization(ctx, authz.URI)
+	if err != nil {
+		return xerrors.Errorf("authorization for %s failed: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+// getClient returns an ACME client with a registered account, creating the account if there is none yet
+func (i *acmeIssuer) getClient(ctx context.Context) (*acme.Client, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.client != nil {
+		return i.client, nil
+	}
+
+	key, err := i.loadAccountKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := &acme.Client{Key: key, DirectoryURL: i.Config.directoryURL(), UserAgent: "gitpod-ws-proxy"}
+	_, err = client.Register(ctx, &acme.Account{Contact: []string{"mailto:" + i.Config.Email}}, acme.AcceptTOS)
+	if err != nil && err != acme.ErrAccountAlreadyExists {
+		return nil, xerrors.Errorf("cannot register ACME account: %w", err)
+	}
+	i.client = client
+	return client, nil
+}
+
+func (i *acmeIssuer) loadAccountKey(ctx context.Context) (crypto.Signer, error) {
+	stored, err := i.Store.LoadAccountKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		blk, _ := pem.Decode(stored)
+		if blk == nil {
+			return nil, xerrors.Errorf("stored account key is not PEM encoded")
+		}
+		key, err := x509.ParseECPrivateKey(blk.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse stored account key: %w", err)
+		}
+		return key, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot generate account key: %w", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot encode account key: %w", err)
+	}
+	err = i.Store.SaveAccountKey(ctx, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
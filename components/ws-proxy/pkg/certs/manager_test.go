@@ -0,0 +1,155 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package certs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeIssuer issues self-signed certificates which are valid for Validity
+type fakeIssuer struct {
+	Validity time.Duration
+	Issued   [][]string
+}
+
+func (i *fakeIssuer) Issue(ctx context.Context, domains []string, key crypto.Signer) ([][]byte, error) {
+	i.Issued = append(i.Issued, domains)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(len(i.Issued))),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(i.Validity),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{der}, nil
+}
+
+func TestManagerReconcile(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		store = &DirectoryStore{Location: t.TempDir()}
+		cfg   = Config{
+			Certificates: []CertificateConfig{
+				{Name: "workspaces", Domains: []string{"*.ws.example.com"}},
+				{Name: "installation", Domains: []string{"example.com", "www.example.com"}},
+			},
+		}
+		iss = &fakeIssuer{Validity: 90 * 24 * time.Hour}
+		mgr = newManager(cfg, store, iss)
+	)
+
+	mgr.reconcile(ctx)
+	if diff := cmp.Diff([][]string{{"*.ws.example.com"}, {"example.com", "www.example.com"}}, iss.Issued); diff != "" {
+		t.Fatalf("unexpected certificates issued (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		ServerName  string
+		Expectation string
+	}{
+		{ServerName: "foo.ws.example.com", Expectation: "*.ws.example.com"},
+		{ServerName: "FOO.ws.example.com.", Expectation: "*.ws.example.com"},
+		{ServerName: "www.example.com", Expectation: "example.com"},
+		{ServerName: "foo.bar.ws.example.com"},
+		{ServerName: "other.com"},
+		{ServerName: ""},
+	}
+	for _, test := range tests {
+		crt, err := mgr.GetCertificate(&tls.ClientHelloInfo{ServerName: test.ServerName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var act string
+		if crt != nil {
+			act = crt.Leaf.Subject.CommonName
+		}
+		if act != test.Expectation {
+			t.Errorf("unexpected certificate for %q: %q, expected %q", test.ServerName, act, test.Expectation)
+		}
+	}
+
+	// a new manager, e.g. another replica, uses the stored certificates
+	iss2 := &fakeIssuer{Validity: 90 * 24 * time.Hour}
+	newManager(cfg, store, iss2).reconcile(ctx)
+	if len(iss2.Issued) != 0 {
+		t.Errorf("stored certificates were issued again: %v", iss2.Issued)
+	}
+
+	// certificates are renewed once they no longer cover their domains
+	cfg.Certificates[1].Domains = append(cfg.Certificates[1].Domains, "api.example.com")
+	newManager(cfg, store, iss2).reconcile(ctx)
+	if diff := cmp.Diff([][]string{{"example.com", "www.example.com", "api.example.com"}}, iss2.Issued); diff != "" {
+		t.Errorf("unexpected certificates issued (-want +got):\n%s", diff)
+	}
+}
+
+func TestManagerRenewsExpiringCertificates(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		store = &DirectoryStore{Location: t.TempDir()}
+		cfg   = Config{Certificates: []CertificateConfig{{Name: "workspaces", Domains: []string{"*.ws.example.com"}}}}
+		iss   = &fakeIssuer{Validity: 10 * 24 * time.Hour}
+		mgr   = newManager(cfg, store, iss)
+	)
+
+	mgr.reconcile(ctx)
+	mgr.reconcile(ctx)
+	if len(iss.Issued) != 2 {
+		t.Errorf("expected the certificate to be renewed as it expires within 30 days, but issued %d", len(iss.Issued))
+	}
+
+	iss.Validity = 90 * 24 * time.Hour
+	mgr.reconcile(ctx)
+	mgr.reconcile(ctx)
+	if len(iss.Issued) != 3 {
+		t.Errorf("expected the certificate to be renewed once, but issued %d", len(iss.Issued))
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		Email:        "admin@example.com",
+		Certificates: []CertificateConfig{{Name: "workspaces", Domains: []string{"*.ws.example.com", "example.com"}}},
+		DNS:          DNSConfig{Provider: "webhook"},
+		Storage:      StorageConfig{Directory: "/var/lib/certs"},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := map[string]func(c *Config){
+		"no email":         func(c *Config) { c.Email = "" },
+		"no certificates":  func(c *Config) { c.Certificates = nil },
+		"invalid domain":   func(c *Config) { c.Certificates[0].Domains = []string{"*.*.example.com"} },
+		"duplicate name":   func(c *Config) { c.Certificates = append(c.Certificates, c.Certificates[0]) },
+		"unknown provider": func(c *Config) { c.DNS.Provider = "carrier-pigeon" },
+		"no storage":       func(c *Config) { c.Storage = StorageConfig{} },
+		"two storages": func(c *Config) {
+			c.Storage.Secret = &SecretStorageConfig{Namespace: "default", Prefix: "ws-proxy-certs"}
+		},
+	}
+	for name, mod := range invalid {
+		cfg := valid
+		cfg.Certificates = []CertificateConfig{{Name: "workspaces", Domains: []string{"*.ws.example.com"}}}
+		mod(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected config to be invalid", name)
+		}
+	}
+}
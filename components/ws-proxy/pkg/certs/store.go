@@ -0,0 +1,241 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package certs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	accountKeyName = "acme-account.key"
+)
+
+// Bundle is a PEM encoded certificate chain and its private key
+type Bundle struct {
+	Certificate []byte
+	Key         []byte
+}
+
+// Store keeps certificates and the ACME account key
+type Store interface {
+	// LoadCertificate loads a certificate. It returns nil if there is none.
+	LoadCertificate(ctx context.Context, name string) (*Bundle, error)
+	// SaveCertificate stores a certificate
+	SaveCertificate(ctx context.Context, name string, bundle *Bundle) error
+	// LoadAccountKey loads the PEM encoded ACME account key. It returns nil if there is none.
+	LoadAccountKey(ctx context.Context) ([]byte, error)
+	// SaveAccountKey stores the PEM encoded ACME account key
+	SaveAccountKey(ctx context.Context, key []byte) error
+}
+
+// NewStore creates the store the configuration describes
+func NewStore(cfg StorageConfig) (Store, error) {
+	if cfg.Directory != "" {
+		return &DirectoryStore{Location: cfg.Directory}, nil
+	}
+
+	clientset, err := newClientSet(cfg.Secret.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretStore{Client: clientset, Namespace: cfg.Secret.Namespace, Prefix: cfg.Secret.Prefix}, nil
+}
+
+func newClientSet(kubeconfig string) (res *kubernetes.Clientset, err error) {
+	defer func() {
+		if err != nil {
+			err = xerrors.Errorf("cannot create clientset: %w", err)
+		}
+	}()
+
+	if kubeconfig != "" {
+		res, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewForConfig(res)
+	}
+
+	k8s, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(k8s)
+}
+
+// DirectoryStore stores certificates in a directory. Each certificate is stored in
+// <location>/<name>/tls.crt and tls.key, the account key in <location>/acme-account.key.
+type DirectoryStore struct {
+	Location string
+}
+
+// LoadCertificate loads a certificate. It returns nil if there is none.
+func (s *DirectoryStore) LoadCertificate(ctx context.Context, name string) (*Bundle, error) {
+	crt, err := s.read(filepath.Join(name, corev1.TLSCertKey))
+	if crt == nil || err != nil {
+		return nil, err
+	}
+	key, err := s.read(filepath.Join(name, corev1.TLSPrivateKeyKey))
+	if key == nil || err != nil {
+		return nil, err
+	}
+	return &Bundle{Certificate: crt, Key: key}, nil
+}
+
+// SaveCertificate stores a certificate
+func (s *DirectoryStore) SaveCertificate(ctx context.Context, name string, bundle *Bundle) error {
+	err := os.MkdirAll(filepath.Join(s.Location, name), 0700)
+	if err != nil {
+		return xerrors.Errorf("cannot store certificate %s: %w", name, err)
+	}
+	// we write the key first so that a certificate never refers to a key we don't have
+	err = s.write(filepath.Join(name, corev1.TLSPrivateKeyKey), bundle.Key)
+	if err != nil {
+		return err
+	}
+	return s.write(filepath.Join(name, corev1.TLSCertKey), bundle.Certificate)
+}
+
+// LoadAccountKey loads the PEM encoded ACME account key. It returns nil if there is none.
+func (s *DirectoryStore) LoadAccountKey(ctx context.Context) ([]byte, error) {
+	return s.read(accountKeyName)
+}
+
+// SaveAccountKey stores the PEM encoded ACME account key
+func (s *DirectoryStore) SaveAccountKey(ctx context.Context, key []byte) error {
+	err := os.MkdirAll(s.Location, 0700)
+	if err != nil {
+		return xerrors.Errorf("cannot store account key: %w", err)
+	}
+	return s.write(accountKeyName, key)
+}
+
+func (s *DirectoryStore) read(name string) ([]byte, error) {
+	content, err := ioutil.ReadFile(filepath.Join(s.Location, name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read %s: %w", name, err)
+	}
+	return content, nil
+}
+
+// write replaces a file atomically so that we never serve half a certificate
+func (s *DirectoryStore) write(name string, content []byte) error {
+	fn := filepath.Join(s.Location, name)
+	tmp, err := ioutil.TempFile(filepath.Dir(fn), "."+filepath.Base(fn))
+	if err != nil {
+		return xerrors.Errorf("cannot write %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if err != nil {
+		tmp.Close()
+		return xerrors.Errorf("cannot write %s: %w", name, err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return xerrors.Errorf("cannot write %s: %w", name, err)
+	}
+	err = os.Rename(tmp.Name(), fn)
+	if err != nil {
+		return xerrors.Errorf("cannot write %s: %w", name, err)
+	}
+	return nil
+}
+
+// SecretStore stores certificates in Kubernetes secrets, so that all replicas of ws-proxy share them.
+// Each certificate is stored as TLS secret <prefix>-<name>, the account key in <prefix>-account.
+type SecretStore struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Prefix    string
+}
+
+// LoadCertificate loads a certificate. It returns nil if there is none.
+func (s *SecretStore) LoadCertificate(ctx context.Context, name string) (*Bundle, error) {
+	secret, err := s.get(ctx, s.Prefix+"-"+name)
+	if secret == nil || err != nil {
+		return nil, err
+	}
+	crt, key := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(crt) == 0 || len(key) == 0 {
+		return nil, nil
+	}
+	return &Bundle{Certificate: crt, Key: key}, nil
+}
+
+// SaveCertificate stores a certificate
+func (s *SecretStore) SaveCertificate(ctx context.Context, name string, bundle *Bundle) error {
+	return s.put(ctx, s.Prefix+"-"+name, corev1.SecretTypeTLS, map[string][]byte{
+		corev1.TLSCertKey:       bundle.Certificate,
+		corev1.TLSPrivateKeyKey: bundle.Key,
+	})
+}
+
+// LoadAccountKey loads the PEM encoded ACME account key. It returns nil if there is none.
+func (s *SecretStore) LoadAccountKey(ctx context.Context) ([]byte, error) {
+	secret, err := s.get(ctx, s.Prefix+"-account")
+	if secret == nil || err != nil {
+		return nil, err
+	}
+	return secret.Data[accountKeyName], nil
+}
+
+// SaveAccountKey stores the PEM encoded ACME account key
+func (s *SecretStore) SaveAccountKey(ctx context.Context, key []byte) error {
+	return s.put(ctx, s.Prefix+"-account", corev1.SecretTypeOpaque, map[string][]byte{accountKeyName: key})
+}
+
+func (s *SecretStore) get(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret, err := s.Client.CoreV1().Secrets(s.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if k8serr.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get secret %s: %w", name, err)
+	}
+	return secret, nil
+}
+
+func (s *SecretStore) put(ctx context.Context, name string, tpe corev1.SecretType, data map[string][]byte) error {
+	secrets := s.Client.CoreV1().Secrets(s.Namespace)
+	secret, err := s.get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.Namespace,
+				Labels:    map[string]string{"component": "ws-proxy"},
+			},
+			Type: tpe,
+			Data: data,
+		}, metav1.CreateOptions{})
+	} else {
+		// the update fails if another replica changed the secret since we read it, in which case we use theirs next time
+		secret.Data = data
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot store secret %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package certs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"directory": func(t *testing.T) Store { return &DirectoryStore{Location: t.TempDir()} },
+		"secret": func(t *testing.T) Store {
+			return &SecretStore{Client: fake.NewSimpleClientset(), Namespace: "default", Prefix: "ws-proxy-certs"}
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			var (
+				ctx   = context.Background()
+				store = newStore(t)
+			)
+
+			bundle, err := store.LoadCertificate(ctx, "workspaces")
+			if err != nil || bundle != nil {
+				t.Fatalf("expected no certificate, got %v, %v", bundle, err)
+			}
+			key, err := store.LoadAccountKey(ctx)
+			if err != nil || key != nil {
+				t.Fatalf("expected no account key, got %v, %v", key, err)
+			}
+
+			for _, b := range []*Bundle{
+				{Certificate: []byte("cert"), Key: []byte("key")},
+				{Certificate: []byte("renewed cert"), Key: []byte("renewed key")},
+			} {
+				err = store.SaveCertificate(ctx, "workspaces", b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				bundle, err = store.LoadCertificate(ctx, "workspaces")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(b, bundle); diff != "" {
+					t.Errorf("unexpected certificate (-want +got):\n%s", diff)
+				}
+			}
+
+			err = store.SaveAccountKey(ctx, []byte("account key"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err = store.LoadAccountKey(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(key) != "account key" {
+				t.Errorf("unexpected account key %q", key)
+			}
+		})
+	}
+}
+
+func TestSecretStoreSecretType(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		client = fake.NewSimpleClientset()
+		store  = &SecretStore{Client: client, Namespace: "default", Prefix: "ws-proxy-certs"}
+	)
+	err := store.SaveCertificate(ctx, "workspaces", &Bundle{Certificate: []byte("cert"), Key: []byte("key")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := client.CoreV1().Secrets("default").Get(ctx, "ws-proxy-certs-workspaces", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Type != corev1.SecretTypeTLS {
+		t.Errorf("unexpected secret type %s, expected %s", secret.Type, corev1.SecretTypeTLS)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package loadgen sends a representative mix of workspace requests to a ws-proxy and measures how it copes.
+// Results can be compared against a baseline to catch throughput regressions.
+package loadgen
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// DefaultHostHeader is the header ws-proxy routes workspace requests by
+const DefaultHostHeader = "x-wsproxy-host"
+
+// Request is a kind of request in a mix
+type Request struct {
+	// Name identifies the kind of request in the result
+	Name   string `json:"name"`
+	Method string `json:"method,omitempty"`
+	// Host is the workspace host the request is for. {{workspace}} is replaced by one of the workspaces.
+	Host   string            `json:"host"`
+	Path   string            `json:"path"`
+	Header map[string]string `json:"header,omitempty"`
+	// Weight is the share of this kind of request in the mix, relative to the other requests
+	Weight int `json:"weight"`
+}
+
+// Mix is a set of requests we send in proportion to their weight
+type Mix []Request
+
+// WorkspacePlaceholder is replaced by a workspace ID in the host of a request
+const WorkspacePlaceholder = "{{workspace}}"
+
+// DefaultMix is the request mix we see in production: mostly IDE traffic, supervisor status polls and exposed ports.
+// hostSuffix is the domain suffix of workspace hosts, e.g. .ws.gitpod.io.
+func DefaultMix(hostSuffix string) Mix {
+	return Mix{
+		{Name: "ide", Host: WorkspacePlaceholder + hostSuffix, Path: "/services", Weight: 60},
+		{Name: "supervisor", Host: WorkspacePlaceholder + hostSuffix, Path: "/_supervisor/v1/status/supervisor", Weight: 20},
+		{Name: "port", Host: "8080-" + WorkspacePlaceholder + hostSuffix, Path: "/", Weight: 20},
+	}
+}
+
+// Validate checks that requests can be sent using this mix
+func (m Mix) Validate() error {
+	if len(m) == 0 {
+		return xerrors.Errorf("mix has no requests")
+	}
+	names := make(map[string]struct{}, len(m))
+	for i, r := range m {
+		if r.Name == "" {
+			return xerrors.Errorf("request %d has no name", i)
+		}
+		if _, exists := names[r.Name]; exists {
+			return xerrors.Errorf("request name %s is not unique", r.Name)
+		}
+		names[r.Name] = struct{}{}
+		if r.Weight <= 0 {
+			return xerrors.Errorf("request %s: weight must be positive", r.Name)
+		}
+		if r.Host == "" {
+			return xerrors.Errorf("request %s has no host", r.Name)
+		}
+		if !strings.HasPrefix(r.Path, "/") {
+			return xerrors.Errorf("request %s: path must start with /", r.Name)
+		}
+	}
+	return nil
+}
+
+// schedule spreads the requests of the mix according to their weight. Walking it round-robin sends every kind of
+// request in proportion to its weight without a random number generator in the hot path.
+func (m Mix) schedule() []int {
+	var total int
+	for _, r := range m {
+		total += r.Weight
+	}
+	res := make([]int, 0, total)
+	sent := make([]int, len(m))
+	for len(res) < total {
+		// pick the request which is furthest behind its share
+		best := -1
+		var bestLag float64
+		for i, r := range m {
+			lag := float64(len(res)+1)*float64(r.Weight)/float64(total) - float64(sent[i])
+			if best < 0 || lag > bestLag {
+				best, bestLag = i, lag
+			}
+		}
+		sent[best]++
+		res = append(res, best)
+	}
+	return res
+}
+
+// Config configures a load generation run
+type Config struct {
+	// Target is the URL of the ws-proxy, e.g. https://localhost:8080
+	Target string
+	// HostHeader is the header we set the workspace host in. Defaults to DefaultHostHeader.
+	HostHeader string
+	// Workspaces are the IDs of the workspaces we send requests for
+	Workspaces []string
+	Mix        Mix
+	// Concurrency is the number of requests in flight
+	Concurrency int
+	// Duration is how long we send requests for
+	Duration time.Duration
+	// Timeout is the timeout of a single request
+	Timeout time.Duration
+	// Insecure skips TLS certificate verification
+	Insecure bool
+}
+
+// Result is the outcome of a load generation run
+type Result struct {
+	Duration time.Duration `json:"duration"`
+	Requests int64         `json:"requests"`
+	Errors   int64         `json:"errors"`
+	// Throughput is the number of successful requests per second
+	Throughput float64                `json:"throughput"`
+	Kinds      map[string]*KindResult `json:"kinds"`
+}
+
+// KindResult is the outcome for one kind of request in the mix
+type KindResult struct {
+	Requests    int64         `json:"requests"`
+	Errors      int64         `json:"errors"`
+	StatusCodes map[int]int64 `json:"statusCodes"`
+	Throughput  float64       `json:"throughput"`
+	P50         time.Duration `json:"p50"`
+	P90         time.Duration `json:"p90"`
+	P99         time.Duration `json:"p99"`
+
+	latencies []time.Duration
+}
+
+// Run sends requests to the target until the configured duration has passed or ctx is canceled
+func Run(ctx context.Context, cfg Config) (*Result, error) {
+	if cfg.Target == "" {
+		return nil, xerrors.Errorf("target is required")
+	}
+	if len(cfg.Workspaces) == 0 {
+		return nil, xerrors.Errorf("at least one workspace is required")
+	}
+	if err := cfg.Mix.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.HostHeader == "" {
+		cfg.HostHeader = DefaultHostHeader
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	target := strings.TrimSuffix(cfg.Target, "/")
+
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        cfg.Concurrency,
+			MaxIdleConnsPerHost: cfg.Concurrency,
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: cfg.Insecure},
+		},
+		// we measure the proxy, not the pages it redirects to
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		schedule = cfg.Mix.schedule()
+		next     uint64
+		mu       sync.Mutex
+		kinds    = make(map[string]*KindResult, len(cfg.Mix))
+		wg       sync.WaitGroup
+	)
+	for _, r := range cfg.Mix {
+		kinds[r.Name] = &KindResult{StatusCodes: make(map[int]int64)}
+	}
+
+	start := time.Now()
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				n := atomic.AddUint64(&next, 1) - 1
+				r := cfg.Mix[schedule[n%uint64(len(schedule))]]
+				ws := cfg.Workspaces[(n/uint64(len(schedule)))%uint64(len(cfg.Workspaces))]
+
+				t0 := time.Now()
+				code, err := send(ctx, client, target, cfg.HostHeader, ws, r)
+				latency := time.Since(t0)
+				if ctx.Err() != nil {
+					// requests we cancel at the end of the run would count as errors
+					return
+				}
+
+				mu.Lock()
+				k := kinds[r.Name]
+				k.Requests++
+				if err != nil || code >= 500 {
+					k.Errors++
+				}
+				if err == nil {
+					k.StatusCodes[code]++
+				}
+				k.latencies = append(k.latencies, latency)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	res := &Result{
+		Duration: time.Since(start),
+		Kinds:    kinds,
+	}
+	secs := res.Duration.Seconds()
+	for _, k := range kinds {
+		res.Requests += k.Requests
+		res.Errors += k.Errors
+		k.Throughput = float64(k.Requests-k.Errors) / secs
+		sort.Slice(k.latencies, func(i, j int) bool { return k.latencies[i] < k.latencies[j] })
+		k.P50, k.P90, k.P99 = percentile(k.latencies, 0.5), percentile(k.latencies, 0.9), percentile(k.latencies, 0.99)
+		k.latencies = nil
+	}
+	res.Throughput = float64(res.Requests-res.Errors) / secs
+	return res, nil
+}
+
+func send(ctx context.Context, client *http.Client, target, hostHeader, workspace string, r Request) (int, error) {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	host := strings.ReplaceAll(r.Host, WorkspacePlaceholder, workspace)
+	req, err := http.NewRequestWithContext(ctx, method, target+r.Path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Host = host
+	req.Header.Set(hostHeader, host)
+	for k, v := range r.Header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection is reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// percentile expects latencies to be sorted
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	idx := int(float64(len(latencies)-1) * p)
+	return latencies[idx]
+}
+
+// Compare checks current against baseline and returns an error naming everything whose throughput dropped
+// by more than maxRegression, e.g. 0.1 for 10%, or whose error rate went up.
+func Compare(baseline, current *Result, maxRegression float64) error {
+	var regressions []string
+	check := func(name string, base, cur float64) {
+		if base <= 0 {
+			return
+		}
+		if drop := (base - cur) / base; drop > maxRegression {
+			regressions = append(regressions, fmt.Sprintf("%s: throughput dropped by %.1f%% (%.1f/s -> %.1f/s)", name, drop*100, base, cur))
+		}
+	}
+	check("total", baseline.Throughput, current.Throughput)
+
+	names := make([]string, 0, len(baseline.Kinds))
+	for name := range baseline.Kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		base := baseline.Kinds[name]
+		cur, ok := current.Kinds[name]
+		if !ok {
+			regressions = append(regressions, fmt.Sprintf("%s: missing from the current result", name))
+			continue
+		}
+		check(name, base.Throughput, cur.Throughput)
+		if base.Errors == 0 && cur.Errors > 0 {
+			regressions = append(regressions, fmt.Sprintf("%s: %d errors", name, cur.Errors))
+		}
+	}
+
+	if len(regressions) > 0 {
+		return xerrors.Errorf("performance regressed:\n%s", strings.Join(regressions, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package loadgen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchedule(t *testing.T) {
+	mix := Mix{
+		{Name: "a", Weight: 3},
+		{Name: "b", Weight: 1},
+	}
+	act := mix.schedule()
+	// b is spread across the schedule instead of coming last
+	if diff := cmp.Diff([]int{0, 0, 1, 0}, act); diff != "" {
+		t.Errorf("unexpected schedule (-want +got):\n%s", diff)
+	}
+}
+
+func TestMixValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Mix   Mix
+		Valid bool
+	}{
+		{Name: "default", Mix: DefaultMix(".ws.gitpod.test"), Valid: true},
+		{Name: "empty"},
+		{Name: "no name", Mix: Mix{{Host: "a", Path: "/", Weight: 1}}},
+		{Name: "duplicate name", Mix: Mix{{Name: "a", Host: "a", Path: "/", Weight: 1}, {Name: "a", Host: "a", Path: "/", Weight: 1}}},
+		{Name: "zero weight", Mix: Mix{{Name: "a", Host: "a", Path: "/"}}},
+		{Name: "relative path", Mix: Mix{{Name: "a", Host: "a", Path: "foo", Weight: 1}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Mix.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		hosts = make(map[string]int)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts[r.Header.Get(DefaultHostHeader)]++
+		mu.Unlock()
+		if strings.HasPrefix(r.Header.Get(DefaultHostHeader), "8080-") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	res, err := Run(context.Background(), Config{
+		Target:      srv.URL,
+		Workspaces:  []string{"ws1", "ws2"},
+		Mix:         DefaultMix(".ws.gitpod.test"),
+		Concurrency: 4,
+		Duration:    200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Requests == 0 || res.Throughput <= 0 {
+		t.Fatalf("no requests succeeded: %+v", res)
+	}
+	ide, port := res.Kinds["ide"], res.Kinds["port"]
+	if ide.Errors != 0 || ide.StatusCodes[http.StatusOK] != ide.Requests {
+		t.Errorf("unexpected IDE result: %+v", ide)
+	}
+	if port.Errors != port.Requests || port.Throughput != 0 {
+		t.Errorf("failing port requests were not counted as errors: %+v", port)
+	}
+	if ide.Requests < 2*port.Requests {
+		t.Errorf("requests were not sent in proportion to the mix: %d IDE and %d port requests", ide.Requests, port.Requests)
+	}
+	if ide.P50 == 0 || ide.P99 < ide.P50 {
+		t.Errorf("unexpected latencies: %+v", ide)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, host := range []string{"ws1.ws.gitpod.test", "ws2.ws.gitpod.test", "8080-ws1.ws.gitpod.test"} {
+		if hosts[host] == 0 {
+			t.Errorf("no requests for %s", host)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Result{
+		Throughput: 1000,
+		Kinds: map[string]*KindResult{
+			"ide":  {Throughput: 600},
+			"port": {Throughput: 400},
+		},
+	}
+	tests := []struct {
+		Name        string
+		Current     *Result
+		Regressions []string
+	}{
+		{
+			Name: "within tolerance",
+			Current: &Result{Throughput: 950, Kinds: map[string]*KindResult{
+				"ide":  {Throughput: 560},
+				"port": {Throughput: 390},
+			}},
+		},
+		{
+			Name: "faster",
+			Current: &Result{Throughput: 2000, Kinds: map[string]*KindResult{
+				"ide":  {Throughput: 1200},
+				"port": {Throughput: 800},
+			}},
+		},
+		{
+			Name: "port regressed",
+			Current: &Result{Throughput: 920, Kinds: map[string]*KindResult{
+				"ide":  {Throughput: 600},
+				"port": {Throughput: 320, Errors: 3},
+			}},
+			Regressions: []string{"port: throughput dropped by 20.0%", "port: 3 errors"},
+		},
+		{
+			Name:        "kind missing",
+			Current:     &Result{Throughput: 1000, Kinds: map[string]*KindResult{"ide": {Throughput: 1000}}},
+			Regressions: []string{"port: missing"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := Compare(baseline, test.Current, 0.1)
+			if len(test.Regressions) == 0 {
+				if err != nil {
+					t.Errorf("unexpected regression: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected a regression")
+			}
+			for _, r := range test.Regressions {
+				if !strings.Contains(err.Error(), r) {
+					t.Errorf("expected %q in %q", r, err.Error())
+				}
+			}
+		})
+	}
+}
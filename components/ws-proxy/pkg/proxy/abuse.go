@@ -0,0 +1,475 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultAbuseWindow = 10 * time.Minute
+	// abuseRestrictionRetention is how long we restrict a port ourselves. By then ws-manager has made it private,
+	// or the workspace is long gone.
+	abuseRestrictionRetention = 24 * time.Hour
+	// abuseReportTimeout limits how long we wait for ws-manager to make a port private
+	abuseReportTimeout = 10 * time.Second
+)
+
+// AbuseDetectionConfig configures the detection of public ports which host abusive content, e.g. phishing kits or
+// open proxies. Rules score the requests to public ports. Once the score of a port within the window reaches the
+// threshold, we restrict the port to the workspace owner and ask ws-manager to make it private.
+type AbuseDetectionConfig struct {
+	Rules []AbuseRule `json:"rules"`
+	// Threshold is the score at which we restrict a port
+	Threshold int `json:"threshold"`
+	// MinClients is the number of distinct client IPs the matching requests must come from. Developers who test
+	// their own application rarely come from many addresses, the victims of a phishing page do.
+	MinClients int `json:"minClients,omitempty"`
+	// Window is the period over which we add up the score of a port. Defaults to 10 minutes.
+	Window util.Duration `json:"window,omitempty"`
+	// DryRun logs and counts the ports we would restrict, but does not restrict them
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *AbuseDetectionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Rules, validation.Required),
+		validation.Field(&c.Threshold, validation.Required, validation.Min(1)),
+		validation.Field(&c.MinClients, validation.Min(0)),
+		validation.Field(&c.Window, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid abuse detection config: %w", err)
+	}
+	names := make(map[string]struct{}, len(c.Rules))
+	for i, r := range c.Rules {
+		_, err := r.compile()
+		if err != nil {
+			return xerrors.Errorf("invalid abuse detection config: rule %d: %w", i, err)
+		}
+		if _, exists := names[r.Name]; exists {
+			return xerrors.Errorf("invalid abuse detection config: duplicate rule %s", r.Name)
+		}
+		names[r.Name] = struct{}{}
+	}
+	return nil
+}
+
+// AbuseRule scores requests which are characteristic of abuse. A request matches if it matches all conditions of the rule.
+type AbuseRule struct {
+	Name string `json:"name"`
+	// Methods are the request methods the rule applies to, all if empty
+	Methods []string `json:"methods,omitempty"`
+	// Path is a regular expression the request path must match, e.g. the paths of well-known phishing kits
+	Path string `json:"path,omitempty"`
+	// Query is a regular expression the raw query must match, e.g. url=https?:// of open redirects and web proxies
+	Query string `json:"query,omitempty"`
+	// UserAgent is a regular expression the user agent must match
+	UserAgent string `json:"userAgent,omitempty"`
+	// ProxyRequests matches CONNECT requests and requests with an absolute URL, which clients send to proxies only
+	ProxyRequests bool `json:"proxyRequests,omitempty"`
+	// Score is what a matching request adds to the score of its port
+	Score int `json:"score"`
+}
+
+type compiledAbuseRule struct {
+	AbuseRule
+	methods   map[string]struct{}
+	path      *regexp.Regexp
+	query     *regexp.Regexp
+	userAgent *regexp.Regexp
+}
+
+func (r AbuseRule) compile() (*compiledAbuseRule, error) {
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required),
+		validation.Field(&r.Score, validation.Required, validation.Min(1)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Methods) == 0 && r.Path == "" && r.Query == "" && r.UserAgent == "" && !r.ProxyRequests {
+		return nil, xerrors.Errorf("%s has no conditions and would match every request", r.Name)
+	}
+
+	res := &compiledAbuseRule{AbuseRule: r}
+	if len(r.Methods) > 0 {
+		res.methods = make(map[string]struct{}, len(r.Methods))
+		for _, m := range r.Methods {
+			res.methods[strings.ToUpper(m)] = struct{}{}
+		}
+	}
+	for _, e := range []struct {
+		Expr string
+		Dst  **regexp.Regexp
+	}{
+		{r.Path, &res.path},
+		{r.Query, &res.query},
+		{r.UserAgent, &res.userAgent},
+	} {
+		if e.Expr == "" {
+			continue
+		}
+		*e.Dst, err = regexp.Compile(e.Expr)
+		if err != nil {
+			return nil, xerrors.Errorf("%s: %w", r.Name, err)
+		}
+	}
+	return res, nil
+}
+
+func (r *compiledAbuseRule) Matches(req *http.Request) bool {
+	if r.methods != nil {
+		if _, ok := r.methods[req.Method]; !ok {
+			return false
+		}
+	}
+	if r.path != nil && !r.path.MatchString(req.URL.Path) {
+		return false
+	}
+	if r.query != nil && !r.query.MatchString(req.URL.RawQuery) {
+		return false
+	}
+	if r.userAgent != nil && !r.userAgent.MatchString(req.UserAgent()) {
+		return false
+	}
+	if r.ProxyRequests && req.Method != http.MethodConnect && !req.URL.IsAbs() {
+		return false
+	}
+	return true
+}
+
+// PortController changes the visibility of workspace ports
+type PortController interface {
+	ControlPort(ctx context.Context, req *wsapi.ControlPortRequest) (*wsapi.ControlPortResponse, error)
+}
+
+// AbusivePort is a port whose requests scored above the threshold
+type AbusivePort struct {
+	WorkspaceID string `json:"workspaceID"`
+	InstanceID  string `json:"instanceID"`
+	Port        uint32 `json:"port"`
+	// Rules counts the matching requests by rule
+	Rules        map[string]int `json:"rules"`
+	Clients      int            `json:"clients"`
+	RestrictedAt time.Time      `json:"restrictedAt"`
+}
+
+type abuseHit struct {
+	Time   time.Time
+	Score  int
+	Client string
+	Rule   string
+}
+
+type portAbuse struct {
+	hits       []abuseHit
+	restricted *AbusivePort
+}
+
+type abusePortKey struct {
+	InstanceID string
+	Port       uint32
+}
+
+// AbuseDetector scores the requests to public workspace ports and restricts the ports which score above the threshold
+// to the workspace owner. It never looks at requests of the owner or to private ports.
+type AbuseDetector struct {
+	Config       AbuseDetectionConfig
+	InfoProvider WorkspaceInfoProvider
+	// Ports makes restricted ports private in ws-manager, so that they stay restricted across all proxies and restarts.
+	// If nil, we restrict ports in this proxy only.
+	Ports PortController
+
+	rules        []*compiledAbuseRule
+	cookiePrefix string
+
+	mu    sync.Mutex
+	ports map[abusePortKey]*portAbuse
+
+	matches      *prometheus.CounterVec
+	restrictions *prometheus.CounterVec
+}
+
+// NewAbuseDetector creates a new abuse detector. Domain is the Gitpod installation whose owner cookies we accept.
+func NewAbuseDetector(cfg AbuseDetectionConfig, domain string, ip WorkspaceInfoProvider, ports PortController) (*AbuseDetector, error) {
+	rules := make([]*compiledAbuseRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		cr, err := r.compile()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, cr)
+	}
+	return &AbuseDetector{
+		Config:       cfg,
+		InfoProvider: ip,
+		Ports:        ports,
+		rules:        rules,
+		cookiePrefix: ownerCookiePrefix(domain),
+		ports:        make(map[abusePortKey]*portAbuse),
+		matches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "abuse_rule_matches_total",
+			Help: "Requests to public workspace ports which matched an abuse rule, by rule",
+		}, []string{"rule"}),
+		restrictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "abuse_port_restrictions_total",
+			Help: "Public workspace ports we restricted to their owner because of abuse, by whether we only pretended to (dry run)",
+		}, []string{"dry_run"}),
+	}, nil
+}
+
+// RegisterMetrics registers the abuse detection metrics
+func (d *AbuseDetector) RegisterMetrics(reg prometheus.Registerer) error {
+	err := reg.Register(d.matches)
+	if err != nil {
+		return err
+	}
+	return reg.Register(d.restrictions)
+}
+
+// Handler denies everyone but the owner access to restricted ports and scores the requests to all other public ports.
+// It must run after the workspace authentication. If the detector is nil, the handler does nothing.
+func (d *AbuseDetector) Handler(pages *ErrorPages) mux.MiddlewareFunc {
+	if d == nil {
+		return func(h http.Handler) http.Handler { return h }
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			coords := getWorkspaceCoords(req)
+			port, err := strconv.ParseUint(coords.Port, 10, 16)
+			if coords.ID == "" || err != nil {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			ws := d.InfoProvider.WorkspaceInfo(req.Context(), coords.ID)
+			if ws == nil {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			if _, _, _, err := checkOwnerCookie(req, d.cookiePrefix, ws); err == nil {
+				// the owner may always access their ports and is no victim of them
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			key := abusePortKey{InstanceID: ws.InstanceID, Port: uint32(port)}
+			if d.isRestricted(key, time.Now()) {
+				getLog(req.Context()).WithField("port", port).Debug("denied request to port restricted because of abuse")
+				serveErrorPage(pages, resp, req, ErrorPageUnauthorized, http.StatusForbidden)
+				return
+			}
+			if isPublicPort(ws, uint32(port)) {
+				d.score(req, ws, uint32(port), key)
+			}
+			h.ServeHTTP(resp, req)
+		})
+	}
+}
+
+// IsRestricted returns true if we restricted a workspace port to its owner. Raw TCP connections cannot prove that
+// they come from the owner, hence they must not reach restricted ports. If the detector is nil, no port is restricted.
+func (d *AbuseDetector) IsRestricted(ws *WorkspaceInfo, port uint32) bool {
+	if d == nil {
+		return false
+	}
+	return d.isRestricted(abusePortKey{InstanceID: ws.InstanceID, Port: port}, time.Now())
+}
+
+func isPublicPort(ws *WorkspaceInfo, port uint32) bool {
+	for _, p := range ws.Ports {
+		if p.Port == port {
+			return p.Visibility == wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC
+		}
+	}
+	return false
+}
+
+func (d *AbuseDetector) isRestricted(key abusePortKey, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pa, ok := d.ports[key]
+	if !ok || pa.restricted == nil || d.Config.DryRun {
+		return false
+	}
+	if now.Sub(pa.restricted.RestrictedAt) > abuseRestrictionRetention {
+		delete(d.ports, key)
+		return false
+	}
+	return true
+}
+
+// score adds the score of a request to its port and restricts the port once it reaches the threshold
+func (d *AbuseDetector) score(req *http.Request, ws *WorkspaceInfo, port uint32, key abusePortKey) {
+	var hits []abuseHit
+	now := time.Now()
+	client, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		client = req.RemoteAddr
+	}
+	for _, r := range d.rules {
+		if !r.Matches(req) {
+			continue
+		}
+		d.matches.WithLabelValues(r.Name).Inc()
+		hits = append(hits, abuseHit{Time: now, Score: r.Score, Client: client, Rule: r.Name})
+	}
+	if len(hits) == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	pa, ok := d.ports[key]
+	if !ok {
+		d.prune(now)
+		pa = &portAbuse{}
+		d.ports[key] = pa
+	}
+	if pa.restricted != nil {
+		// we're in dry run mode, otherwise the request would not have made it here
+		d.mu.Unlock()
+		return
+	}
+	pa.hits = append(expireAbuseHits(pa.hits, now, d.window()), hits...)
+
+	var (
+		total   int
+		clients = make(map[string]struct{})
+		rules   = make(map[string]int)
+	)
+	for _, h := range pa.hits {
+		total += h.Score
+		clients[h.Client] = struct{}{}
+		rules[h.Rule]++
+	}
+	if total < d.Config.Threshold || len(clients) < d.Config.MinClients {
+		d.mu.Unlock()
+		return
+	}
+	pa.hits = nil
+	pa.restricted = &AbusivePort{
+		WorkspaceID:  ws.WorkspaceID,
+		InstanceID:   ws.InstanceID,
+		Port:         port,
+		Rules:        rules,
+		Clients:      len(clients),
+		RestrictedAt: now,
+	}
+	restricted := *pa.restricted
+	d.mu.Unlock()
+
+	d.restrictions.WithLabelValues(strconv.FormatBool(d.Config.DryRun)).Inc()
+	log := log.WithFields(log.OWI("", ws.WorkspaceID, ws.InstanceID)).
+		WithField("port", port).
+		WithField("rules", rules).
+		WithField("clients", len(clients)).
+		WithField("score", total).
+		WithField("dryRun", d.Config.DryRun)
+	if d.Config.DryRun {
+		log.Warn("public port looks abusive - not restricting it in dry run mode")
+		return
+	}
+	// abuse tooling picks up this entry
+	log.Warn("public port looks abusive - restricting it to the workspace owner")
+	go d.makePrivate(ws, restricted)
+}
+
+// makePrivate asks ws-manager to make a port private
+func (d *AbuseDetector) makePrivate(ws *WorkspaceInfo, p AbusivePort) {
+	if d.Ports == nil {
+		return
+	}
+	var spec *wsapi.PortSpec
+	for _, wp := range ws.Ports {
+		if wp.Port == p.Port {
+			spec = proto.Clone(&wp.PortSpec).(*wsapi.PortSpec)
+			break
+		}
+	}
+	if spec == nil {
+		return
+	}
+	spec.Visibility = wsapi.PortVisibility_PORT_VISIBILITY_PRIVATE
+
+	ctx, cancel := context.WithTimeout(context.Background(), abuseReportTimeout)
+	defer cancel()
+	_, err := d.Ports.ControlPort(ctx, &wsapi.ControlPortRequest{
+		Id:     p.WorkspaceID,
+		Expose: true,
+		Spec:   spec,
+	})
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", p.WorkspaceID, p.InstanceID)).WithField("port", p.Port).Error("cannot make abusive port private - it stays restricted in this proxy only")
+	}
+}
+
+// Restricted lists the ports we restricted, or would have restricted in dry run mode
+func (d *AbuseDetector) Restricted() []AbusivePort {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var res []AbusivePort
+	for _, pa := range d.ports {
+		if pa.restricted != nil {
+			res = append(res, *pa.restricted)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].RestrictedAt.Before(res[j].RestrictedAt) })
+	return res
+}
+
+func (d *AbuseDetector) window() time.Duration {
+	if d.Config.Window == 0 {
+		return defaultAbuseWindow
+	}
+	return time.Duration(d.Config.Window)
+}
+
+// prune forgets the ports without recent hits and expired restrictions. Callers must hold the lock.
+func (d *AbuseDetector) prune(now time.Time) {
+	for key, pa := range d.ports {
+		if pa.restricted != nil {
+			if now.Sub(pa.restricted.RestrictedAt) > abuseRestrictionRetention {
+				delete(d.ports, key)
+			}
+			continue
+		}
+		pa.hits = expireAbuseHits(pa.hits, now, d.window())
+		if len(pa.hits) == 0 {
+			delete(d.ports, key)
+		}
+	}
+}
+
+func expireAbuseHits(hits []abuseHit, now time.Time, window time.Duration) []abuseHit {
+	i := 0
+	for i < len(hits) && now.Sub(hits[i].Time) > window {
+		i++
+	}
+	return hits[i:]
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+type fakePortController struct {
+	mu   sync.Mutex
+	reqs []*wsapi.ControlPortRequest
+	done chan struct{}
+}
+
+func (f *fakePortController) ControlPort(ctx context.Context, req *wsapi.ControlPortRequest) (*wsapi.ControlPortResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.reqs = append(f.reqs, req)
+	close(f.done)
+	return &wsapi.ControlPortResponse{}, nil
+}
+
+func TestAbuseDetectionConfigValidate(t *testing.T) {
+	rule := AbuseRule{Name: "phishing", Path: "^/wp-login", Score: 1}
+	tests := []struct {
+		Name  string
+		Cfg   *AbuseDetectionConfig
+		Valid bool
+	}{
+		{Name: "nil", Valid: true},
+		{Name: "valid", Cfg: &AbuseDetectionConfig{Rules: []AbuseRule{rule}, Threshold: 10, Window: util.Duration(time.Minute)}, Valid: true},
+		{Name: "no rules", Cfg: &AbuseDetectionConfig{Threshold: 10}},
+		{Name: "no threshold", Cfg: &AbuseDetectionConfig{Rules: []AbuseRule{rule}}},
+		{Name: "rule without conditions", Cfg: &AbuseDetectionConfig{Rules: []AbuseRule{{Name: "all", Score: 1}}, Threshold: 10}},
+		{Name: "rule without score", Cfg: &AbuseDetectionConfig{Rules: []AbuseRule{{Name: "phishing", Path: "^/wp-login"}}, Threshold: 10}},
+		{Name: "invalid expression", Cfg: &AbuseDetectionConfig{Rules: []AbuseRule{{Name: "phishing", Path: "(", Score: 1}}, Threshold: 10}},
+		{Name: "duplicate rule", Cfg: &AbuseDetectionConfig{Rules: []AbuseRule{rule, rule}, Threshold: 10}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestAbuseRuleMatches(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Rule     AbuseRule
+		Method   string
+		Target   string
+		Agent    string
+		Expected bool
+	}{
+		{Name: "path", Rule: AbuseRule{Path: "^/wp-login"}, Method: "GET", Target: "/wp-login.php", Expected: true},
+		{Name: "path mismatch", Rule: AbuseRule{Path: "^/wp-login"}, Method: "GET", Target: "/index.html"},
+		{Name: "query", Rule: AbuseRule{Query: "url=https?"}, Method: "GET", Target: "/browse?url=https://bank.example.com", Expected: true},
+		{Name: "method", Rule: AbuseRule{Methods: []string{"post"}, Path: "^/login"}, Method: "POST", Target: "/login", Expected: true},
+		{Name: "method mismatch", Rule: AbuseRule{Methods: []string{"post"}, Path: "^/login"}, Method: "GET", Target: "/login"},
+		{Name: "user agent", Rule: AbuseRule{UserAgent: "(?i)curl"}, Method: "GET", Target: "/", Agent: "curl/7.68.0", Expected: true},
+		{Name: "absolute URL", Rule: AbuseRule{ProxyRequests: true}, Method: "GET", Target: "http://example.com/", Expected: true},
+		{Name: "connect", Rule: AbuseRule{ProxyRequests: true}, Method: "CONNECT", Target: "example.com:443", Expected: true},
+		{Name: "origin form", Rule: AbuseRule{ProxyRequests: true}, Method: "GET", Target: "/"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Rule.Name, test.Rule.Score = test.Name, 1
+			r, err := test.Rule.compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(test.Method, test.Target, nil)
+			if test.Method != http.MethodConnect && test.Target[0] == '/' {
+				// httptest makes all targets absolute, but servers see the request target as it was sent
+				req.URL.Scheme, req.URL.Host = "", ""
+			}
+			req.Header.Set("User-Agent", test.Agent)
+			if act := r.Matches(req); act != test.Expected {
+				t.Errorf("unexpected match: want %v, got %v", test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestAbuseDetectorHandler(t *testing.T) {
+	const (
+		domain      = "test-domain.com"
+		workspaceID = "amaranth-smelt-9ba20cc1"
+		instanceID  = "instance-1"
+		ownerToken  = "owner-token"
+	)
+	newDetector := func(dryRun bool, ports PortController) *AbuseDetector {
+		d, err := NewAbuseDetector(AbuseDetectionConfig{
+			Rules:      []AbuseRule{{Name: "phishing", Path: "^/signin", Score: 1}},
+			Threshold:  3,
+			MinClients: 2,
+			DryRun:     dryRun,
+		}, domain, &fixedInfoProvider{Infos: map[string]*WorkspaceInfo{
+			workspaceID: {
+				WorkspaceID: workspaceID,
+				InstanceID:  instanceID,
+				Auth:        &wsapi.WorkspaceAuthentication{OwnerToken: ownerToken},
+				Ports: []PortInfo{
+					{PortSpec: wsapi.PortSpec{Port: 8080, Target: 38080, Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC}},
+					{PortSpec: wsapi.PortSpec{Port: 3000, Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PRIVATE}},
+				},
+			},
+		}}, ports)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	send := func(h http.Handler, port, path, client string, owner bool) int {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = client + ":1234"
+		req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: workspaceID, workspacePortIdentifier: port})
+		if owner {
+			req = setOwnerTokenCookie(req, instanceID, ownerToken)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	ok := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {})
+
+	t.Run("restricts public port", func(t *testing.T) {
+		ports := &fakePortController{done: make(chan struct{})}
+		d := newDetector(false, ports)
+		h := d.Handler(nil)(ok)
+
+		// the owner is never scored, and a single client is not enough
+		for i := 0; i < 5; i++ {
+			send(h, "8080", "/signin", "10.0.0.1", true)
+			send(h, "8080", "/signin", "10.0.0.2", false)
+		}
+		if rs := d.Restricted(); len(rs) > 0 {
+			t.Fatalf("unexpected restriction: %v", rs)
+		}
+		if code := send(h, "8080", "/signin", "10.0.0.3", false); code != http.StatusOK {
+			t.Errorf("unexpected status of the request which crossed the threshold: %d", code)
+		}
+
+		if code := send(h, "8080", "/", "10.0.0.4", false); code != http.StatusForbidden {
+			t.Errorf("restricted port admitted a visitor: %d", code)
+		}
+		if code := send(h, "8080", "/", "10.0.0.1", true); code != http.StatusOK {
+			t.Errorf("restricted port denied the owner: %d", code)
+		}
+		if code := send(h, "3000", "/", "10.0.0.4", false); code != http.StatusOK {
+			t.Errorf("restriction affected another port: %d", code)
+		}
+
+		select {
+		case <-ports.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("port was not made private")
+		}
+		expected := &wsapi.ControlPortRequest{
+			Id:     workspaceID,
+			Expose: true,
+			Spec:   &wsapi.PortSpec{Port: 8080, Target: 38080, Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PRIVATE},
+		}
+		if diff := cmp.Diff(expected, ports.reqs[0]); diff != "" {
+			t.Errorf("unexpected port control request (-want +got):\n%s", diff)
+		}
+
+		rs := d.Restricted()
+		if len(rs) != 1 || rs[0].Port != 8080 || rs[0].Clients != 2 || rs[0].Rules["phishing"] != 6 {
+			t.Errorf("unexpected restrictions: %+v", rs)
+		}
+	})
+
+	t.Run("ignores private ports", func(t *testing.T) {
+		d := newDetector(false, nil)
+		h := d.Handler(nil)(ok)
+		for i := 0; i < 5; i++ {
+			send(h, "3000", "/signin", "10.0.0.2", false)
+			send(h, "3000", "/signin", "10.0.0.3", false)
+		}
+		if rs := d.Restricted(); len(rs) > 0 {
+			t.Errorf("unexpected restriction: %v", rs)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		d := newDetector(true, nil)
+		h := d.Handler(nil)(ok)
+		for i := 0; i < 5; i++ {
+			send(h, "8080", "/signin", "10.0.0.2", false)
+			send(h, "8080", "/signin", "10.0.0.3", false)
+		}
+		if rs := d.Restricted(); len(rs) != 1 {
+			t.Errorf("expected the port to be reported: %v", rs)
+		}
+		if code := send(h, "8080", "/", "10.0.0.4", false); code != http.StatusOK {
+			t.Errorf("dry run restricted the port: %d", code)
+		}
+	})
+}
+
+func TestExpireAbuseHits(t *testing.T) {
+	now := time.Now()
+	hits := []abuseHit{
+		{Time: now.Add(-20 * time.Minute), Rule: "a"},
+		{Time: now.Add(-5 * time.Minute), Rule: "b"},
+		{Time: now, Rule: "c"},
+	}
+	act := expireAbuseHits(hits, now, 10*time.Minute)
+	if len(act) != 2 || act[0].Rule != "b" {
+		t.Errorf("unexpected hits: %+v", act)
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultActivityReportInterval  = 30 * time.Second
+	defaultActivityReportBatchSize = 500
+	defaultActivityReportTimeout   = 10 * time.Second
+
+	// maxActivityReportBackoff is the longest we wait between two reports when ws-manager keeps failing them
+	maxActivityReportBackoff = 5 * time.Minute
+)
+
+// ActivityReportConfig configures how we report the connections we have open to workspaces to ws-manager
+type ActivityReportConfig struct {
+	// Interval is the time between two reports. ws-manager may ask us to report less often. Defaults to 30 seconds.
+	Interval util.Duration `json:"interval,omitempty"`
+	// BatchSize is the maximum number of workspaces we report in a single request. Defaults to 500.
+	BatchSize int `json:"batchSize,omitempty"`
+	// Timeout is the time we allow for a single report request. Defaults to 10 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ActivityReportConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Interval, validation.Min(util.Duration(0))),
+		validation.Field(&c.BatchSize, validation.Min(0)),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+	)
+}
+
+// ActivityReporter sends activity reports to ws-manager
+type ActivityReporter interface {
+	ReportProxyActivity(ctx context.Context, req *wsapi.ReportProxyActivityRequest) (*wsapi.ReportProxyActivityResponse, error)
+}
+
+// workspaceConnections counts the requests in flight to a workspace
+type workspaceConnections struct {
+	Connections uint32
+	Sessions    uint32
+}
+
+// ActivityTracker counts the requests in flight to each workspace and periodically reports them to ws-manager,
+// so that e.g. a browser tab which still streams logs counts as activity. Reports never overlap: if ws-manager
+// is slow or fails, we back off rather than pile up requests.
+type ActivityTracker struct {
+	Config       ActivityReportConfig
+	Proxy        string
+	Reporter     ActivityReporter
+	InfoProvider WorkspaceInfoProvider
+
+	mu     sync.Mutex
+	active map[string]*workspaceConnections
+	// reported maps the workspaces we last reported open connections for to their instance ID
+	reported map[string]string
+
+	stop chan struct{}
+	done chan struct{}
+
+	reports *prometheus.CounterVec
+}
+
+// NewActivityTracker creates a new activity tracker. Call Start to begin reporting.
+func NewActivityTracker(cfg ActivityReportConfig, proxy string, reporter ActivityReporter, ip WorkspaceInfoProvider) *ActivityTracker {
+	return &ActivityTracker{
+		Config:       cfg,
+		Proxy:        proxy,
+		Reporter:     reporter,
+		InfoProvider: ip,
+		active:       make(map[string]*workspaceConnections),
+		reported:     make(map[string]string),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+		reports: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "activity_reports_total",
+			Help: "Activity reports sent to ws-manager",
+		}, []string{"outcome"}),
+	}
+}
+
+// RegisterMetrics registers the activity report metrics
+func (t *ActivityTracker) RegisterMetrics(reg prometheus.Registerer) error {
+	err := reg.Register(t.reports)
+	if err != nil {
+		return err
+	}
+	return reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "activity_workspaces",
+		Help: "Workspaces we currently have requests in flight to",
+	}, func() float64 {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return float64(len(t.active))
+	}))
+}
+
+// Handler counts the requests in flight to a workspace. If the tracker is nil, the handler does nothing.
+func (t *ActivityTracker) Handler(h http.Handler) http.Handler {
+	if t == nil {
+		return h
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		workspaceID := getWorkspaceCoords(req).ID
+		if workspaceID == "" {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		session := isSessionRequest(req)
+		t.open(workspaceID, session)
+		defer t.close(workspaceID, session)
+
+		h.ServeHTTP(resp, req)
+	})
+}
+
+// isSessionRequest returns true for requests which stream for a long time, e.g. websockets or event streams
+func isSessionRequest(req *http.Request) bool {
+	return isWebsocketRequest(req) || strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+}
+
+func (t *ActivityTracker) open(workspaceID string, session bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c, ok := t.active[workspaceID]
+	if !ok {
+		c = &workspaceConnections{}
+		t.active[workspaceID] = c
+	}
+	c.Connections++
+	if session {
+		c.Sessions++
+	}
+}
+
+func (t *ActivityTracker) close(workspaceID string, session bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c, ok := t.active[workspaceID]
+	if !ok {
+		return
+	}
+	c.Connections--
+	if session {
+		c.Sessions--
+	}
+	if c.Connections == 0 {
+		delete(t.active, workspaceID)
+	}
+}
+
+// Start starts reporting to ws-manager
+func (t *ActivityTracker) Start() {
+	go t.run()
+}
+
+// Close stops reporting to ws-manager
+func (t *ActivityTracker) Close() {
+	close(t.stop)
+	<-t.done
+}
+
+func (t *ActivityTracker) run() {
+	defer close(t.done)
+
+	interval := time.Duration(t.Config.Interval)
+	if interval == 0 {
+		interval = defaultActivityReportInterval
+	}
+
+	var (
+		wait    = interval
+		backoff time.Duration
+	)
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-t.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		minInterval, err := t.report()
+		if err != nil {
+			if backoff == 0 {
+				backoff = interval
+			}
+			backoff *= 2
+			if backoff > maxActivityReportBackoff {
+				backoff = maxActivityReportBackoff
+			}
+			wait = backoff
+			log.WithError(err).WithField("retryIn", wait).Warn("cannot report proxy activity to ws-manager")
+			continue
+		}
+
+		backoff = 0
+		wait = interval
+		if minInterval > wait {
+			wait = minInterval
+		}
+	}
+}
+
+// report sends the current connections to ws-manager in batches. Workspaces whose connections were closed since
+// the last report are reported once with zero connections. We stop at the first batch that fails and report the
+// remaining workspaces next time.
+func (t *ActivityTracker) report() (minInterval time.Duration, err error) {
+	t.mu.Lock()
+	var (
+		workspaces = make(map[string]workspaceConnections, len(t.active))
+		ids        = make([]string, 0, len(t.active)+len(t.reported))
+	)
+	for id, c := range t.active {
+		workspaces[id] = *c
+		ids = append(ids, id)
+	}
+	for id := range t.reported {
+		if _, ok := t.active[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	t.mu.Unlock()
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	sort.Strings(ids)
+
+	batchSize := t.Config.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultActivityReportBatchSize
+	}
+	timeout := time.Duration(t.Config.Timeout)
+	if timeout == 0 {
+		timeout = defaultActivityReportTimeout
+	}
+
+	// we only look up workspaces we already know about: the info of workspaces with requests in flight is cached anyways
+	known, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for len(ids) > 0 {
+		n := batchSize
+		if n > len(ids) {
+			n = len(ids)
+		}
+		batch := ids[:n]
+		ids = ids[n:]
+
+		var (
+			req       = &wsapi.ReportProxyActivityRequest{Proxy: t.Proxy}
+			instances = make(map[string]string, len(batch))
+		)
+		for _, id := range batch {
+			c, active := workspaces[id]
+			instanceID, reported := t.reportedInstance(id)
+			if active {
+				info := t.InfoProvider.WorkspaceInfo(known, id)
+				if info == nil || info.InstanceID == "" {
+					continue
+				}
+				instanceID = info.InstanceID
+			} else if !reported {
+				continue
+			}
+			instances[id] = instanceID
+			req.Workspaces = append(req.Workspaces, &wsapi.WorkspaceProxyActivity{
+				Id:          instanceID,
+				Connections: c.Connections,
+				Sessions:    c.Sessions,
+			})
+		}
+		if len(req.Workspaces) == 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		resp, err := t.Reporter.ReportProxyActivity(ctx, req)
+		cancel()
+		if err != nil {
+			t.reports.WithLabelValues("error").Inc()
+			return 0, xerrors.Errorf("cannot report activity of %d workspaces: %w", len(req.Workspaces), err)
+		}
+		t.reports.WithLabelValues("success").Inc()
+
+		t.mu.Lock()
+		for id, instanceID := range instances {
+			if _, active := workspaces[id]; active {
+				t.reported[id] = instanceID
+			} else {
+				delete(t.reported, id)
+			}
+		}
+		t.mu.Unlock()
+
+		if resp.MinInterval != "" {
+			d, err := time.ParseDuration(resp.MinInterval)
+			if err != nil {
+				log.WithError(err).WithField("minInterval", resp.MinInterval).Warn("ws-manager sent an invalid minimum report interval")
+			} else if d > minInterval {
+				minInterval = d
+			}
+		}
+	}
+	return minInterval, nil
+}
+
+func (t *ActivityTracker) reportedInstance(workspaceID string) (instanceID string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	instanceID, ok = t.reported[workspaceID]
+	return
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+type fakeActivityReporter struct {
+	Requests    []*wsapi.ReportProxyActivityRequest
+	Err         error
+	MinInterval string
+}
+
+func (r *fakeActivityReporter) ReportProxyActivity(ctx context.Context, req *wsapi.ReportProxyActivityRequest) (*wsapi.ReportProxyActivityResponse, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	r.Requests = append(r.Requests, req)
+	return &wsapi.ReportProxyActivityResponse{MinInterval: r.MinInterval}, nil
+}
+
+func TestActivityTrackerHandler(t *testing.T) {
+	tracker := NewActivityTracker(ActivityReportConfig{}, "ws-proxy-0", &fakeActivityReporter{}, &fixedInfoProvider{})
+
+	var (
+		inflight = make(chan struct{})
+		release  = make(chan struct{})
+	)
+	h := tracker.Handler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		inflight <- struct{}{}
+		<-release
+	}))
+	serve := func(header http.Header) {
+		req := httptest.NewRequest("GET", "http://example.com", nil)
+		req.Header = header
+		req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: "amaranth-smelt-9ba20cc1"})
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	go serve(http.Header{})
+	go serve(http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}})
+	go serve(http.Header{"Accept": {"text/event-stream"}})
+	for i := 0; i < 3; i++ {
+		<-inflight
+	}
+
+	tracker.mu.Lock()
+	act := *tracker.active["amaranth-smelt-9ba20cc1"]
+	tracker.mu.Unlock()
+	if diff := cmp.Diff(workspaceConnections{Connections: 3, Sessions: 2}, act); diff != "" {
+		t.Errorf("unexpected connections (-want +got):\n%s", diff)
+	}
+
+	for i := 0; i < 3; i++ {
+		release <- struct{}{}
+	}
+	for i := 0; i < 100; i++ {
+		tracker.mu.Lock()
+		n := len(tracker.active)
+		tracker.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("expected the workspace to be removed once all requests finished")
+}
+
+func TestActivityTrackerReport(t *testing.T) {
+	ip := &fixedInfoProvider{
+		Infos: map[string]*WorkspaceInfo{
+			"ws-a": {WorkspaceID: "ws-a", InstanceID: "inst-a"},
+			"ws-b": {WorkspaceID: "ws-b", InstanceID: "inst-b"},
+			"ws-c": {WorkspaceID: "ws-c", InstanceID: "inst-c"},
+		},
+	}
+	reporter := &fakeActivityReporter{MinInterval: "1m"}
+	tracker := NewActivityTracker(ActivityReportConfig{BatchSize: 2}, "ws-proxy-0", reporter, ip)
+	ignoreProto := cmpopts.IgnoreUnexported(wsapi.ReportProxyActivityRequest{}, wsapi.WorkspaceProxyActivity{})
+
+	tracker.open("ws-a", false)
+	tracker.open("ws-b", true)
+	tracker.open("ws-c", false)
+	tracker.open("ws-unknown", false)
+	minInterval, err := tracker.report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minInterval != time.Minute {
+		t.Errorf("unexpected min interval: %s", minInterval)
+	}
+	expected := []*wsapi.ReportProxyActivityRequest{
+		{Proxy: "ws-proxy-0", Workspaces: []*wsapi.WorkspaceProxyActivity{{Id: "inst-a", Connections: 1}, {Id: "inst-b", Connections: 1, Sessions: 1}}},
+		{Proxy: "ws-proxy-0", Workspaces: []*wsapi.WorkspaceProxyActivity{{Id: "inst-c", Connections: 1}}},
+	}
+	if diff := cmp.Diff(expected, reporter.Requests, ignoreProto); diff != "" {
+		t.Errorf("unexpected reports (-want +got):\n%s", diff)
+	}
+
+	// closed connections are reported once with zero connections
+	tracker.close("ws-a", false)
+	tracker.close("ws-b", true)
+	tracker.close("ws-c", false)
+	tracker.close("ws-unknown", false)
+	reporter.Requests = nil
+	reporter.Err = xerrors.Errorf("unavailable")
+	_, err = tracker.report()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	reporter.Err = nil
+	_, err = tracker.report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []*wsapi.ReportProxyActivityRequest{
+		{Proxy: "ws-proxy-0", Workspaces: []*wsapi.WorkspaceProxyActivity{{Id: "inst-a"}, {Id: "inst-b"}}},
+		{Proxy: "ws-proxy-0", Workspaces: []*wsapi.WorkspaceProxyActivity{{Id: "inst-c"}}},
+	}
+	if diff := cmp.Diff(expected, reporter.Requests, ignoreProto); diff != "" {
+		t.Errorf("unexpected reports (-want +got):\n%s", diff)
+	}
+
+	// nothing to report
+	reporter.Requests = nil
+	_, err = tracker.report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reporter.Requests) != 0 {
+		t.Errorf("expected no reports, got %d", len(reporter.Requests))
+	}
+}
+
+func TestActivityReportConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *ActivityReportConfig
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "defaults", Config: &ActivityReportConfig{}},
+		{Name: "negative batch size", Config: &ActivityReportConfig{BatchSize: -1}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
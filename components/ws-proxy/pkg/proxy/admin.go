@@ -0,0 +1,429 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// AdminConfig configures the admin interface which exposes pprof and the runtime state of the proxy for troubleshooting
+type AdminConfig struct {
+	// Address is where the admin interface listens. It must not be reachable from the internet.
+	Address string `json:"address"`
+	// TokenFile contains the bearer token admin requests must present
+	TokenFile string `json:"tokenFile,omitempty"`
+	// ClientCA is the PEM encoded certificate authority of client certificates we admit. Requires Certificate and Key.
+	ClientCA string `json:"clientCA,omitempty"`
+	// Certificate and Key are the TLS certificate of the admin interface. If not set, the admin interface serves plain HTTP.
+	Certificate string `json:"crt,omitempty"`
+	Key         string `json:"key,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *AdminConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.TokenFile == "" && c.ClientCA == "" {
+		return xerrors.Errorf("invalid admin config: tokenFile or clientCA is required")
+	}
+	if (c.Certificate == "") != (c.Key == "") {
+		return xerrors.Errorf("invalid admin config: crt and key must be set together")
+	}
+	if c.ClientCA != "" && c.Certificate == "" {
+		return xerrors.Errorf("invalid admin config: clientCA requires crt and key")
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Address, validation.Required),
+		validation.Field(&c.TokenFile, validation.By(validateOptionalFileExists)),
+		validation.Field(&c.ClientCA, validation.By(validateOptionalFileExists)),
+		validation.Field(&c.Certificate, validation.By(validateOptionalFileExists)),
+		validation.Field(&c.Key, validation.By(validateOptionalFileExists)),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid admin config: %w", err)
+	}
+	return nil
+}
+
+// CacheDumper provides the contents of the workspace info cache
+type CacheDumper interface {
+	DumpCache() (infos []*WorkspaceInfo, version uint64)
+}
+
+// DumpCache returns the contents of the workspace info cache with owner tokens replaced by their hash
+func (p *RemoteWorkspaceInfoProvider) DumpCache() ([]*WorkspaceInfo, uint64) {
+	infos, version := p.cache.Snapshot()
+	res := make([]*WorkspaceInfo, 0, len(infos))
+	for _, info := range infos {
+		res = append(res, redactWorkspaceInfo(info))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].WorkspaceID < res[j].WorkspaceID })
+	return res, version
+}
+
+// CacheDump is the content of the workspace info cache the admin interface serves
+type CacheDump struct {
+	Version    uint64           `json:"version"`
+	Workspaces []*WorkspaceInfo `json:"workspaces"`
+}
+
+// CacheRefresher re-fetches the workspace info cache from ws-manager
+type CacheRefresher interface {
+	RefreshCache(ctx context.Context) (*CacheRefresh, error)
+}
+
+// CacheRefresh describes the changes a refresh of the workspace info cache applied
+type CacheRefresh struct {
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+	Deleted  int    `json:"deleted"`
+	Version  uint64 `json:"version"`
+}
+
+// RefreshCache brings the workspace info cache up to date with ws-manager without waiting for a reconnect,
+// e.g. if we suspect the cache missed an update
+func (p *RemoteWorkspaceInfoProvider) RefreshCache(ctx context.Context) (*CacheRefresh, error) {
+	p.mu.Lock()
+	client := p.client
+	p.mu.Unlock()
+	if client == nil {
+		return nil, xerrors.Errorf("not connected to ws-manager")
+	}
+
+	infos, err := p.fetchInitialWorkspaceInfo(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	diff := p.cache.Reinit(infos)
+	p.markUpdated()
+	_, version := p.cache.Snapshot()
+	log.WithField("diff", diff).Info("refreshed workspace info cache on request")
+
+	return &CacheRefresh{
+		Inserted: diff.Inserted,
+		Updated:  diff.Updated,
+		Deleted:  diff.Deleted,
+		Version:  version,
+	}, nil
+}
+
+// DrainStatus tells if ws-proxy is draining
+type DrainStatus struct {
+	Draining bool `json:"draining"`
+}
+
+// ConnectionInfo describes an open client connection
+type ConnectionInfo struct {
+	Local  string    `json:"local"`
+	Remote string    `json:"remote"`
+	State  string    `json:"state"`
+	Since  time.Time `json:"since"`
+}
+
+// ConnectionTracker keeps track of the open client connections of the proxies
+type ConnectionTracker struct {
+	mu    sync.Mutex
+	conns map[net.Conn]*ConnectionInfo
+}
+
+// NewConnectionTracker creates a new connection tracker
+func NewConnectionTracker() *ConnectionTracker {
+	return &ConnectionTracker{
+		conns: make(map[net.Conn]*ConnectionInfo),
+	}
+}
+
+// ConnState records connection state changes. Use it as http.Server.ConnState.
+func (t *ConnectionTracker) ConnState(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		// hijacked connections are websockets - we lose track of them once the handler takes over
+		delete(t.conns, conn)
+		return
+	}
+
+	info, ok := t.conns[conn]
+	if !ok {
+		info = &ConnectionInfo{
+			Local:  conn.LocalAddr().String(),
+			Remote: conn.RemoteAddr().String(),
+		}
+		t.conns[conn] = info
+	}
+	info.State = state.String()
+	info.Since = time.Now()
+}
+
+// Connections lists the open connections
+func (t *ConnectionTracker) Connections() []ConnectionInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	res := make([]ConnectionInfo, 0, len(t.conns))
+	for _, info := range t.conns {
+		res = append(res, *info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Local != res[j].Local {
+			return res[i].Local < res[j].Local
+		}
+		return res[i].Remote < res[j].Remote
+	})
+	return res
+}
+
+// AdminHandler serves the admin interface
+type AdminHandler struct {
+	// Token is the bearer token admin requests must present. Empty disables token authentication.
+	Token string
+	// ClientCertAuth admits requests which present a verified client certificate
+	ClientCertAuth bool
+
+	Cache       CacheDumper
+	Refresher   CacheRefresher
+	Connections *ConnectionTracker
+	Bandwidth   *BandwidthTracker
+	Abuse       *AbuseDetector
+	Anomalies   *AnomalyDetector
+	// Config is the configuration the proxy runs with
+	Config interface{}
+	// Routes is the route table the proxy runs with
+	Routes RouteTable
+	// Health is drained on request
+	Health *HealthChecker
+	// Upgrades starts a successor ws-proxy on request
+	Upgrades *UpgradeController
+
+	once sync.Once
+	mux  *http.ServeMux
+}
+
+// NewAdminHandler creates the admin handler of the configuration
+func NewAdminHandler(cfg AdminConfig) (*AdminHandler, error) {
+	h := &AdminHandler{ClientCertAuth: cfg.ClientCA != ""}
+	if cfg.TokenFile != "" {
+		tkn, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read admin token: %w", err)
+		}
+		h.Token = strings.TrimSpace(string(tkn))
+		if h.Token == "" {
+			return nil, xerrors.Errorf("admin token file %s is empty", cfg.TokenFile)
+		}
+	}
+	return h, nil
+}
+
+// ServeHTTP serves an admin request
+func (h *AdminHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if !h.authenticated(req) {
+		log.WithField("remote", req.RemoteAddr).WithField("path", req.URL.Path).Warn("unauthenticated admin request")
+		resp.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(resp, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.once.Do(h.installRoutes)
+	h.mux.ServeHTTP(resp, req)
+}
+
+func (h *AdminHandler) installRoutes() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/cache", func(resp http.ResponseWriter, req *http.Request) {
+		if h.Cache == nil {
+			http.Error(resp, "no workspace info cache", http.StatusNotFound)
+			return
+		}
+		infos, version := h.Cache.DumpCache()
+		writeAdminJSON(resp, CacheDump{Version: version, Workspaces: infos})
+	})
+	mux.HandleFunc("/debug/connections", func(resp http.ResponseWriter, req *http.Request) {
+		if h.Connections == nil {
+			http.Error(resp, "connections are not tracked", http.StatusNotFound)
+			return
+		}
+		writeAdminJSON(resp, h.Connections.Connections())
+	})
+	mux.HandleFunc("/debug/bandwidth", func(resp http.ResponseWriter, req *http.Request) {
+		if h.Bandwidth == nil {
+			http.Error(resp, "bandwidth is not tracked", http.StatusNotFound)
+			return
+		}
+		usage := h.Bandwidth.Usage()
+		if wsid := req.URL.Query().Get("workspace"); wsid != "" {
+			filtered := make([]BandwidthUsage, 0, 1)
+			for _, u := range usage {
+				if u.WorkspaceID == wsid {
+					filtered = append(filtered, u)
+				}
+			}
+			usage = filtered
+		}
+		writeAdminJSON(resp, usage)
+	})
+	mux.HandleFunc("/debug/abuse", func(resp http.ResponseWriter, req *http.Request) {
+		if h.Abuse == nil {
+			http.Error(resp, "abuse detection is disabled", http.StatusNotFound)
+			return
+		}
+		writeAdminJSON(resp, h.Abuse.Restricted())
+	})
+	mux.HandleFunc("/debug/anomalies", func(resp http.ResponseWriter, req *http.Request) {
+		if h.Anomalies == nil {
+			http.Error(resp, "anomaly detection is disabled", http.StatusNotFound)
+			return
+		}
+		writeAdminJSON(resp, h.Anomalies.Events())
+	})
+	mux.HandleFunc("/debug/config", func(resp http.ResponseWriter, req *http.Request) {
+		writeAdminJSON(resp, h.Config)
+	})
+	mux.HandleFunc("/debug/routes", func(resp http.ResponseWriter, req *http.Request) {
+		writeAdminJSON(resp, h.Routes.Effective())
+	})
+	mux.HandleFunc("/admin/cache/refresh", func(resp http.ResponseWriter, req *http.Request) {
+		if !requireAdminMethod(resp, req, http.MethodPost) {
+			return
+		}
+		if h.Refresher == nil {
+			http.Error(resp, "the workspace info cache cannot be refreshed", http.StatusNotFound)
+			return
+		}
+		res, err := h.Refresher.RefreshCache(req.Context())
+		if err != nil {
+			log.WithError(err).Warn("cannot refresh workspace info cache")
+			http.Error(resp, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		writeAdminJSON(resp, res)
+	})
+	mux.HandleFunc("/admin/drain", func(resp http.ResponseWriter, req *http.Request) {
+		if !requireAdminMethod(resp, req, http.MethodGet, http.MethodPost, http.MethodDelete) {
+			return
+		}
+		if h.Health == nil {
+			http.Error(resp, "health checks are disabled", http.StatusNotFound)
+			return
+		}
+		switch req.Method {
+		case http.MethodPost:
+			h.Health.SetDraining(true)
+			log.WithField("remote", req.RemoteAddr).Info("draining on request")
+		case http.MethodDelete:
+			h.Health.SetDraining(false)
+			log.WithField("remote", req.RemoteAddr).Info("stopped draining on request")
+		}
+		writeAdminJSON(resp, DrainStatus{Draining: h.Health.Draining()})
+	})
+	mux.HandleFunc("/admin/upgrade", func(resp http.ResponseWriter, req *http.Request) {
+		if !requireAdminMethod(resp, req, http.MethodGet, http.MethodPost) {
+			return
+		}
+		if h.Upgrades == nil {
+			http.Error(resp, "upgrades are disabled", http.StatusNotFound)
+			return
+		}
+		if req.Method == http.MethodGet {
+			writeAdminJSON(resp, h.Upgrades.Status())
+			return
+		}
+		status, err := h.Upgrades.Upgrade()
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusConflict)
+			return
+		}
+		log.WithField("remote", req.RemoteAddr).WithField("successor", status.Successor).Info("upgrading on request")
+		writeAdminJSON(resp, status)
+	})
+	h.mux = mux
+}
+
+func (h *AdminHandler) authenticated(req *http.Request) bool {
+	if h.ClientCertAuth && req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+		return true
+	}
+	if h.Token == "" {
+		return false
+	}
+	tkn := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(tkn), []byte(h.Token)) == 1
+}
+
+// requireAdminMethod responds with 405 and returns false if the request does not use one of the methods
+func requireAdminMethod(resp http.ResponseWriter, req *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if req.Method == m {
+			return true
+		}
+	}
+	resp.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(resp, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+func writeAdminJSON(resp http.ResponseWriter, v interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(resp)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(v)
+	if err != nil {
+		log.WithError(err).Warn("cannot write admin response")
+	}
+}
+
+// ServeAdmin serves the admin interface until the listener fails. The listener is obtained from upgrades, which may be nil.
+func ServeAdmin(cfg AdminConfig, handler http.Handler, upgrades *UpgradeController) error {
+	srv := &http.Server{Addr: cfg.Address, Handler: handler}
+	upgrades.RegisterServer(srv)
+	lns, err := upgrades.Listen([]string{cfg.Address})
+	if err != nil {
+		return err
+	}
+	if cfg.Certificate == "" {
+		return srv.Serve(lns[0])
+	}
+
+	srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.ClientCA != "" {
+		ca, err := os.ReadFile(cfg.ClientCA)
+		if err != nil {
+			return xerrors.Errorf("cannot read admin client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return xerrors.Errorf("admin client CA %s contains no certificates", cfg.ClientCA)
+		}
+		srv.TLSConfig.ClientCAs = pool
+		// we admit requests with a bearer token, too, hence the client certificate is optional
+		srv.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return srv.ServeTLS(lns[0], cfg.Certificate, cfg.Key)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAdminHandler(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	err := os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prov := NewRemoteWorkspaceInfoProvider(WorkspaceInfoProviderConfig{WsManagerAddr: "target"})
+	prov.cache.Insert(mapWorkspaceStatusToInfo(testWorkspaceStatus))
+
+	admin, err := NewAdminHandler(AdminConfig{Address: ":0", TokenFile: tokenFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.Cache = prov
+	admin.Refresher = prov
+	admin.Connections = NewConnectionTracker()
+	admin.Bandwidth = NewBandwidthTracker(BandwidthConfig{}, prov)
+	_, release := admin.Bandwidth.TrackConn(context.Background(), testWorkspaceInfo.WorkspaceID, nil)
+	release()
+	admin.Config = map[string]string{"hello": "world"}
+
+	tests := []struct {
+		Name   string
+		Path   string
+		Token  string
+		Status int
+		Check  func(t *testing.T, body string)
+	}{
+		{Name: "no token", Path: "/debug/config", Status: http.StatusUnauthorized},
+		{Name: "wrong token", Path: "/debug/config", Token: "foobar", Status: http.StatusUnauthorized},
+		{
+			Name: "config", Path: "/debug/config", Token: "s3cr3t", Status: http.StatusOK,
+			Check: func(t *testing.T, body string) {
+				if !strings.Contains(body, `"hello": "world"`) {
+					t.Errorf("config is missing from %s", body)
+				}
+			},
+		},
+		{
+			Name: "cache", Path: "/debug/cache", Token: "s3cr3t", Status: http.StatusOK,
+			Check: func(t *testing.T, body string) {
+				if strings.Contains(body, testWorkspaceStatus.Auth.OwnerToken) {
+					t.Error("cache dump contains the owner token")
+				}
+				var dump struct {
+					Workspaces []*WorkspaceInfo `json:"workspaces"`
+				}
+				err := json.Unmarshal([]byte(body), &dump)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(dump.Workspaces) != 1 || dump.Workspaces[0].WorkspaceID != testWorkspaceInfo.WorkspaceID {
+					t.Errorf("unexpected cache dump: %s", body)
+				}
+			},
+		},
+		{Name: "connections", Path: "/debug/connections", Token: "s3cr3t", Status: http.StatusOK},
+		{
+			Name: "bandwidth", Path: "/debug/bandwidth?workspace=" + testWorkspaceInfo.WorkspaceID, Token: "s3cr3t", Status: http.StatusOK,
+			Check: func(t *testing.T, body string) {
+				var usage []BandwidthUsage
+				err := json.Unmarshal([]byte(body), &usage)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(usage) != 1 || usage[0].InstanceID != testWorkspaceInfo.InstanceID {
+					t.Errorf("unexpected bandwidth usage: %s", body)
+				}
+			},
+		},
+		{
+			Name: "bandwidth of another workspace", Path: "/debug/bandwidth?workspace=foobar", Token: "s3cr3t", Status: http.StatusOK,
+			Check: func(t *testing.T, body string) {
+				if strings.TrimSpace(body) != "[]" {
+					t.Errorf("unexpected bandwidth usage: %s", body)
+				}
+			},
+		},
+		{
+			Name: "routes", Path: "/debug/routes", Token: "s3cr3t", Status: http.StatusOK,
+			Check: func(t *testing.T, body string) {
+				var routes RouteTable
+				err := json.Unmarshal([]byte(body), &routes)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(defaultRouteTable, routes); diff != "" {
+					t.Errorf("unexpected route table (-want +got):\n%s", diff)
+				}
+			},
+		},
+		{Name: "cache refresh requires POST", Path: "/admin/cache/refresh", Token: "s3cr3t", Status: http.StatusMethodNotAllowed},
+		{Name: "drain without health checks", Path: "/admin/drain", Token: "s3cr3t", Status: http.StatusNotFound},
+		{Name: "upgrade without upgrades", Path: "/admin/upgrade", Token: "s3cr3t", Status: http.StatusNotFound},
+		{Name: "pprof", Path: "/debug/pprof/", Token: "s3cr3t", Status: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://admin"+test.Path, nil)
+			if test.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.Token)
+			}
+			rec := httptest.NewRecorder()
+			admin.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Fatalf("unexpected status %d, expected %d: %s", rec.Code, test.Status, rec.Body.String())
+			}
+			if test.Check != nil {
+				test.Check(t, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestConnectionTracker(t *testing.T) {
+	tracker := NewConnectionTracker()
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	tracker.ConnState(a, http.StateNew)
+	tracker.ConnState(a, http.StateActive)
+	if diff := cmp.Diff([]string{"active"}, connStates(tracker)); diff != "" {
+		t.Errorf("unexpected connections (-want +got):\n%s", diff)
+	}
+
+	tracker.ConnState(a, http.StateIdle)
+	tracker.ConnState(b, http.StateNew)
+	if diff := cmp.Diff([]string{"idle", "new"}, connStates(tracker)); diff != "" {
+		t.Errorf("unexpected connections (-want +got):\n%s", diff)
+	}
+
+	tracker.ConnState(a, http.StateClosed)
+	tracker.ConnState(b, http.StateHijacked)
+	if diff := cmp.Diff([]string{}, connStates(tracker)); diff != "" {
+		t.Errorf("unexpected connections (-want +got):\n%s", diff)
+	}
+}
+
+func connStates(tracker *ConnectionTracker) []string {
+	conns := tracker.Connections()
+	res := make([]string, 0, len(conns))
+	for _, c := range conns {
+		res = append(res, c.State)
+	}
+	// net.Pipe connections all have the same address - order by state for a stable result
+	if len(res) == 2 && res[0] > res[1] {
+		res[0], res[1] = res[1], res[0]
+	}
+	return res
+}
@@ -0,0 +1,589 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultAnomalyInterval      = 30 * time.Second
+	defaultAnomalyCooldown      = 5 * time.Minute
+	defaultDegradedModeDuration = 5 * time.Minute
+	defaultAnomalyMinRequests   = 20
+	defaultAnomalyMinCacheSize  = 10
+	// anomalyActionTimeout limits how long a self-healing action may take
+	anomalyActionTimeout = 30 * time.Second
+	// maxAnomalyEvents is the number of recent anomalies the admin interface serves
+	maxAnomalyEvents = 100
+)
+
+// AnomalyKind is a kind of anomaly the detector looks for
+type AnomalyKind string
+
+const (
+	// AnomalyErrorRate is a spike of failing requests to a single workspace
+	AnomalyErrorRate AnomalyKind = "errorRate"
+	// AnomalyCacheShrinkage is a sudden loss of workspaces from the workspace info cache
+	AnomalyCacheShrinkage AnomalyKind = "cacheShrinkage"
+)
+
+// AnomalyAction is a self-healing action the detector takes in response to an anomaly
+type AnomalyAction string
+
+const (
+	// AnomalyActionRefreshWorkspace fetches the status of the affected workspace from ws-manager
+	AnomalyActionRefreshWorkspace AnomalyAction = "refreshWorkspace"
+	// AnomalyActionRefreshCache fetches the status of all workspaces from ws-manager
+	AnomalyActionRefreshCache AnomalyAction = "refreshCache"
+	// AnomalyActionReconnect ends the subscription to ws-manager and subscribes again
+	AnomalyActionReconnect AnomalyAction = "reconnect"
+	// AnomalyActionDegradedMode freezes the cache for a while and restores the workspaces it lost, marked as stale
+	AnomalyActionDegradedMode AnomalyAction = "degradedMode"
+)
+
+// AnomalyDetectionConfig configures the detection of anomalies in how we route workspaces, and the self-healing
+// actions we take in response. Every anomaly is logged as an alert, whether we take actions or not.
+type AnomalyDetectionConfig struct {
+	// Interval is how often we look for anomalies. Defaults to 30 seconds.
+	Interval util.Duration `json:"interval,omitempty"`
+	// Cooldown is how long we wait before we take the same action for the same target again. Defaults to 5 minutes.
+	Cooldown util.Duration `json:"cooldown,omitempty"`
+	// DryRun logs and counts anomalies and the actions we would take, but does not take them
+	DryRun bool `json:"dryRun,omitempty"`
+
+	ErrorRate      *ErrorRateAnomalyConfig      `json:"errorRate,omitempty"`
+	CacheShrinkage *CacheShrinkageAnomalyConfig `json:"cacheShrinkage,omitempty"`
+}
+
+// ErrorRateAnomalyConfig detects workspaces whose requests fail far more often than usual, e.g. because we route
+// them using outdated workspace info
+type ErrorRateAnomalyConfig struct {
+	// Threshold is the share of requests within an interval which must fail with a 5xx status, between 0 and 1
+	Threshold float64 `json:"threshold"`
+	// MinRequests is the number of requests a workspace must receive within an interval. Defaults to 20.
+	MinRequests int `json:"minRequests,omitempty"`
+	// Actions are taken in the given order. Supported are refreshWorkspace and refreshCache.
+	Actions []AnomalyAction `json:"actions,omitempty"`
+}
+
+// CacheShrinkageAnomalyConfig detects the workspace info cache losing many workspaces at once, e.g. because
+// ws-manager sent us an incomplete list of workspaces
+type CacheShrinkageAnomalyConfig struct {
+	// Threshold is the share of workspaces the cache must lose within an interval, between 0 and 1
+	Threshold float64 `json:"threshold"`
+	// MinSize is the size the cache must have had for the detection to apply. Defaults to 10.
+	MinSize int `json:"minSize,omitempty"`
+	// DegradedModeDuration is how long the degraded mode lasts. Defaults to 5 minutes.
+	DegradedModeDuration util.Duration `json:"degradedModeDuration,omitempty"`
+	// Actions are taken in the given order. Supported are refreshCache, reconnect and degradedMode.
+	Actions []AnomalyAction `json:"actions,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *AnomalyDetectionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Interval, validation.Min(util.Duration(0))),
+		validation.Field(&c.Cooldown, validation.Min(util.Duration(0))),
+	)
+	if err == nil && c.ErrorRate == nil && c.CacheShrinkage == nil {
+		err = xerrors.Errorf("neither errorRate nor cacheShrinkage is configured")
+	}
+	if err == nil && c.ErrorRate != nil {
+		err = validation.ValidateStruct(c.ErrorRate,
+			validation.Field(&c.ErrorRate.Threshold, validation.Required, validation.Max(1.0)),
+			validation.Field(&c.ErrorRate.MinRequests, validation.Min(0)),
+			validation.Field(&c.ErrorRate.Actions, validation.Each(validation.In(AnomalyActionRefreshWorkspace, AnomalyActionRefreshCache))),
+		)
+		if err != nil {
+			err = xerrors.Errorf("errorRate: %w", err)
+		}
+	}
+	if err == nil && c.CacheShrinkage != nil {
+		err = validation.ValidateStruct(c.CacheShrinkage,
+			validation.Field(&c.CacheShrinkage.Threshold, validation.Required, validation.Max(1.0)),
+			validation.Field(&c.CacheShrinkage.MinSize, validation.Min(0)),
+			validation.Field(&c.CacheShrinkage.DegradedModeDuration, validation.Min(util.Duration(0))),
+			validation.Field(&c.CacheShrinkage.Actions, validation.Each(validation.In(AnomalyActionRefreshCache, AnomalyActionReconnect, AnomalyActionDegradedMode))),
+		)
+		if err != nil {
+			err = xerrors.Errorf("cacheShrinkage: %w", err)
+		}
+	}
+	if err != nil {
+		return xerrors.Errorf("invalid anomaly detection config: %w", err)
+	}
+	return nil
+}
+
+// SelfHealingInfoProvider is a workspace info provider whose cache the anomaly detector can heal
+type SelfHealingInfoProvider interface {
+	CacheRefresher
+
+	// CachedWorkspaces returns the workspaces in the cache
+	CachedWorkspaces() []*WorkspaceInfo
+	// RefreshWorkspace fetches the status of a single workspace from ws-manager
+	RefreshWorkspace(ctx context.Context, workspaceID string) error
+	// Reconnect ends the subscription to ws-manager and subscribes again
+	Reconnect() error
+	// EnterDegradedMode freezes the cache for the given duration and restores the lost workspaces as stale entries
+	EnterDegradedMode(duration time.Duration, lost []*WorkspaceInfo)
+}
+
+// CachedWorkspaces returns the workspaces in the cache
+func (p *RemoteWorkspaceInfoProvider) CachedWorkspaces() []*WorkspaceInfo {
+	infos, _ := p.cache.Snapshot()
+	return infos
+}
+
+// RefreshWorkspace fetches the status of a single workspace from ws-manager, e.g. if we suspect we missed an update
+func (p *RemoteWorkspaceInfoProvider) RefreshWorkspace(ctx context.Context, workspaceID string) error {
+	p.mu.Lock()
+	client := p.client
+	p.mu.Unlock()
+	if client == nil {
+		return xerrors.Errorf("not connected to ws-manager")
+	}
+
+	var instanceID string
+	for _, info := range p.CachedWorkspaces() {
+		if info.WorkspaceID == workspaceID {
+			instanceID = info.InstanceID
+			break
+		}
+	}
+	if instanceID == "" {
+		return xerrors.Errorf("workspace %s is not in the cache", workspaceID)
+	}
+
+	resp, err := client.DescribeWorkspace(ctx, &wsapi.DescribeWorkspaceRequest{Id: instanceID})
+	if status.Code(err) == codes.NotFound {
+		p.cache.Delete(workspaceID, 0)
+		log.WithFields(log.OWI("", workspaceID, instanceID)).Info("removed workspace ws-manager does not know anymore from the cache")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	st := resp.Status
+	if st == nil || isMalformedStatus(st) {
+		return xerrors.Errorf("ws-manager sent a malformed status for workspace %s", workspaceID)
+	}
+	if st.Phase == wsapi.WorkspacePhase_STOPPED {
+		p.cache.Delete(st.Metadata.MetaId, st.Generation)
+	} else {
+		p.cache.Insert(mapWorkspaceStatusToInfo(st))
+	}
+	return nil
+}
+
+// Reconnect ends the subscription to ws-manager. Run subscribes again after the reconnect interval, which brings
+// the cache up to date as well.
+func (p *RemoteWorkspaceInfoProvider) Reconnect() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancelListen == nil {
+		return xerrors.Errorf("not subscribed to ws-manager")
+	}
+	p.reconnecting = true
+	p.cancelListen()
+	return nil
+}
+
+// EnterDegradedMode freezes the cache for the given duration and restores the workspaces it lost as stale entries.
+// We keep routing those workspaces while we find out whether they are really gone. Entering the degraded mode
+// again extends it.
+func (p *RemoteWorkspaceInfoProvider) EnterDegradedMode(duration time.Duration, lost []*WorkspaceInfo) {
+	until := time.Now().Add(duration)
+	p.mu.Lock()
+	if until.After(p.degradedUntil) {
+		p.degradedUntil = until
+	}
+	p.mu.Unlock()
+
+	p.cache.SetFrozen(true)
+	stale := make([]*WorkspaceInfo, 0, len(lost))
+	for _, info := range lost {
+		s := *info
+		s.Stale = true
+		stale = append(stale, &s)
+	}
+	restored := p.cache.Restore(stale)
+	log.WithField("restored", restored).WithField("until", until).Warn("entering degraded mode - the workspace info cache is frozen")
+
+	time.AfterFunc(duration, p.leaveDegradedMode)
+}
+
+// leaveDegradedMode thaws the cache and removes the workspaces ws-manager does not know anymore,
+// unless the degraded mode was extended in the meantime
+func (p *RemoteWorkspaceInfoProvider) leaveDegradedMode() {
+	p.mu.Lock()
+	if p.degradedUntil.IsZero() || time.Now().Before(p.degradedUntil) {
+		p.mu.Unlock()
+		return
+	}
+	p.degradedUntil = time.Time{}
+	readOnly := p.readOnly
+	p.mu.Unlock()
+
+	if readOnly {
+		// ws-manager is unreachable - the cache stays frozen until recordSuccess thaws it
+		log.Info("left degraded mode - staying read-only")
+		return
+	}
+	p.cache.SetFrozen(false)
+	ctx, cancel := context.WithTimeout(context.Background(), anomalyActionTimeout)
+	defer cancel()
+	_, err := p.RefreshCache(ctx)
+	if err != nil {
+		log.WithError(err).Warn("cannot refresh the workspace info cache after degraded mode - stale workspaces remain until we reconnect")
+	}
+	log.Info("left degraded mode")
+}
+
+// isDegraded is true while we are in degraded mode. Callers are expected to hold mu.
+func (p *RemoteWorkspaceInfoProvider) isDegraded() bool {
+	return !p.degradedUntil.IsZero()
+}
+
+// AnomalyEvent describes an anomaly and the actions we took in response
+type AnomalyEvent struct {
+	Time        time.Time             `json:"time"`
+	Kind        AnomalyKind           `json:"kind"`
+	WorkspaceID string                `json:"workspaceID,omitempty"`
+	Description string                `json:"description"`
+	Actions     []AnomalyActionResult `json:"actions,omitempty"`
+}
+
+// AnomalyActionResult is the outcome of a self-healing action
+type AnomalyActionResult struct {
+	Action AnomalyAction `json:"action"`
+	// Outcome is one of success, error, cooldown or dryRun
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+const (
+	anomalyOutcomeSuccess  = "success"
+	anomalyOutcomeError    = "error"
+	anomalyOutcomeCooldown = "cooldown"
+	anomalyOutcomeDryRun   = "dryRun"
+)
+
+type anomalyRequestCount struct {
+	Total  int
+	Failed int
+}
+
+// AnomalyDetector watches the error rate of requests per workspace and the size of the workspace info cache,
+// and heals the cache when either behaves unusually
+type AnomalyDetector struct {
+	Config   AnomalyDetectionConfig
+	Provider SelfHealingInfoProvider
+	// OnAnomaly is called for every anomaly once we took the actions in response. It must not block.
+	OnAnomaly func(evt AnomalyEvent)
+
+	mu         sync.Mutex
+	requests   map[string]*anomalyRequestCount
+	lastAction map[string]time.Time
+	events     []AnomalyEvent
+
+	// lastCache is the cache content at the previous evaluation. Only evaluate accesses it.
+	lastCache map[string]*WorkspaceInfo
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	anomalies *prometheus.CounterVec
+	actions   *prometheus.CounterVec
+}
+
+// NewAnomalyDetector creates a new anomaly detector
+func NewAnomalyDetector(cfg AnomalyDetectionConfig, provider SelfHealingInfoProvider) *AnomalyDetector {
+	return &AnomalyDetector{
+		Config:     cfg,
+		Provider:   provider,
+		requests:   make(map[string]*anomalyRequestCount),
+		lastAction: make(map[string]time.Time),
+		stop:       make(chan struct{}),
+		anomalies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "anomalies_total",
+			Help: "Anomalies we detected, by kind",
+		}, []string{"kind"}),
+		actions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "anomaly_actions_total",
+			Help: "Self-healing actions we took in response to anomalies, by action and outcome",
+		}, []string{"action", "outcome"}),
+	}
+}
+
+// RegisterMetrics registers the anomaly detection metrics
+func (d *AnomalyDetector) RegisterMetrics(reg prometheus.Registerer) error {
+	err := reg.Register(d.anomalies)
+	if err != nil {
+		return err
+	}
+	return reg.Register(d.actions)
+}
+
+// Handler counts the requests to each workspace and how many of them fail. If the detector is nil, the handler does nothing.
+func (d *AnomalyDetector) Handler() mux.MiddlewareFunc {
+	if d == nil || d.Config.ErrorRate == nil {
+		return func(h http.Handler) http.Handler { return h }
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			id := getWorkspaceCoords(req).ID
+			if id == "" {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			rec := &statusRecordingResponseWriter{ResponseWriter: resp, status: http.StatusOK}
+			h.ServeHTTP(rec, req)
+			d.countRequest(id, rec.status >= http.StatusInternalServerError)
+		})
+	}
+}
+
+func (d *AnomalyDetector) countRequest(workspaceID string, failed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c, ok := d.requests[workspaceID]
+	if !ok {
+		c = &anomalyRequestCount{}
+		d.requests[workspaceID] = c
+	}
+	c.Total++
+	if failed {
+		c.Failed++
+	}
+}
+
+// Start looks for anomalies periodically until Close is called
+func (d *AnomalyDetector) Start() {
+	interval := time.Duration(d.Config.Interval)
+	if interval == 0 {
+		interval = defaultAnomalyInterval
+	}
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				d.evaluate(time.Now())
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops looking for anomalies
+func (d *AnomalyDetector) Close() {
+	d.stopOnce.Do(func() { close(d.stop) })
+}
+
+// Events returns the most recent anomalies, oldest first
+func (d *AnomalyDetector) Events() []AnomalyEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]AnomalyEvent(nil), d.events...)
+}
+
+// evaluate looks for anomalies since the previous evaluation and responds to them
+func (d *AnomalyDetector) evaluate(now time.Time) {
+	d.mu.Lock()
+	requests := d.requests
+	d.requests = make(map[string]*anomalyRequestCount)
+	for key, t := range d.lastAction {
+		if now.Sub(t) >= d.cooldown() {
+			delete(d.lastAction, key)
+		}
+	}
+	d.mu.Unlock()
+
+	if cfg := d.Config.ErrorRate; cfg != nil {
+		minRequests := cfg.MinRequests
+		if minRequests == 0 {
+			minRequests = defaultAnomalyMinRequests
+		}
+		ids := make([]string, 0, len(requests))
+		for id := range requests {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			c := requests[id]
+			if c.Total < minRequests || float64(c.Failed)/float64(c.Total) < cfg.Threshold {
+				continue
+			}
+			evt := AnomalyEvent{
+				Time:        now,
+				Kind:        AnomalyErrorRate,
+				WorkspaceID: id,
+				Description: fmt.Sprintf("%d of %d requests failed", c.Failed, c.Total),
+			}
+			evt.Actions = d.act(now, evt, cfg.Actions, nil)
+			d.record(evt)
+		}
+	}
+
+	if cfg := d.Config.CacheShrinkage; cfg != nil {
+		// Stale workspaces come from a snapshot or the degraded mode. They are expected to go away, hence we ignore them.
+		current := make(map[string]*WorkspaceInfo)
+		for _, info := range d.Provider.CachedWorkspaces() {
+			if info.Stale {
+				continue
+			}
+			current[info.WorkspaceID] = info
+		}
+		previous := d.lastCache
+		d.lastCache = current
+
+		minSize := cfg.MinSize
+		if minSize == 0 {
+			minSize = defaultAnomalyMinCacheSize
+		}
+		if len(previous) < minSize {
+			return
+		}
+		var lost []*WorkspaceInfo
+		for id, info := range previous {
+			if _, ok := current[id]; !ok {
+				lost = append(lost, info)
+			}
+		}
+		if float64(len(lost))/float64(len(previous)) < cfg.Threshold {
+			return
+		}
+		evt := AnomalyEvent{
+			Time:        now,
+			Kind:        AnomalyCacheShrinkage,
+			Description: fmt.Sprintf("the workspace info cache lost %d of %d workspaces", len(lost), len(previous)),
+		}
+		evt.Actions = d.act(now, evt, cfg.Actions, lost)
+		d.record(evt)
+	}
+}
+
+func (d *AnomalyDetector) cooldown() time.Duration {
+	if d.Config.Cooldown == 0 {
+		return defaultAnomalyCooldown
+	}
+	return time.Duration(d.Config.Cooldown)
+}
+
+// act takes the actions in response to an anomaly unless we took them for the same target recently
+func (d *AnomalyDetector) act(now time.Time, evt AnomalyEvent, actions []AnomalyAction, lost []*WorkspaceInfo) []AnomalyActionResult {
+	res := make([]AnomalyActionResult, 0, len(actions))
+	for _, a := range actions {
+		r := AnomalyActionResult{Action: a}
+		key := string(a) + "/" + evt.WorkspaceID
+
+		d.mu.Lock()
+		last, cooling := d.lastAction[key]
+		cooling = cooling && now.Sub(last) < d.cooldown()
+		if !cooling {
+			d.lastAction[key] = now
+		}
+		d.mu.Unlock()
+
+		switch {
+		case cooling:
+			r.Outcome = anomalyOutcomeCooldown
+		case d.Config.DryRun:
+			r.Outcome = anomalyOutcomeDryRun
+		default:
+			err := d.do(a, evt, lost)
+			if err != nil {
+				r.Outcome = anomalyOutcomeError
+				r.Error = err.Error()
+			} else {
+				r.Outcome = anomalyOutcomeSuccess
+			}
+		}
+		d.actions.WithLabelValues(string(a), r.Outcome).Inc()
+
+		entry := log.WithFields(log.OWI("", evt.WorkspaceID, "")).WithField("kind", evt.Kind).WithField("action", a).WithField("outcome", r.Outcome)
+		if r.Outcome == anomalyOutcomeError {
+			entry.WithField("error", r.Error).Warn("self-healing action failed")
+		} else {
+			entry.Info("self-healing action")
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+func (d *AnomalyDetector) do(a AnomalyAction, evt AnomalyEvent, lost []*WorkspaceInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), anomalyActionTimeout)
+	defer cancel()
+
+	switch a {
+	case AnomalyActionRefreshWorkspace:
+		return d.Provider.RefreshWorkspace(ctx, evt.WorkspaceID)
+	case AnomalyActionRefreshCache:
+		_, err := d.Provider.RefreshCache(ctx)
+		return err
+	case AnomalyActionReconnect:
+		return d.Provider.Reconnect()
+	case AnomalyActionDegradedMode:
+		duration := defaultDegradedModeDuration
+		if cfg := d.Config.CacheShrinkage; cfg != nil && cfg.DegradedModeDuration != 0 {
+			duration = time.Duration(cfg.DegradedModeDuration)
+		}
+		d.Provider.EnterDegradedMode(duration, lost)
+		return nil
+	default:
+		return xerrors.Errorf("unsupported action %s", a)
+	}
+}
+
+// record logs the anomaly as an alert and keeps it for the admin interface
+func (d *AnomalyDetector) record(evt AnomalyEvent) {
+	d.anomalies.WithLabelValues(string(evt.Kind)).Inc()
+
+	// alerting picks up this entry
+	log.WithFields(log.OWI("", evt.WorkspaceID, "")).
+		WithField("kind", evt.Kind).
+		WithField("description", evt.Description).
+		WithField("actions", evt.Actions).
+		WithField("dryRun", d.Config.DryRun).
+		Warn("anomaly detected")
+
+	d.mu.Lock()
+	d.events = append(d.events, evt)
+	if len(d.events) > maxAnomalyEvents {
+		d.events = d.events[len(d.events)-maxAnomalyEvents:]
+	}
+	d.mu.Unlock()
+
+	if d.OnAnomaly != nil {
+		d.OnAnomaly(evt)
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+type fakeSelfHealingProvider struct {
+	Infos []*WorkspaceInfo
+
+	Refreshed    []string
+	CacheRefresh int
+	Reconnects   int
+	Degraded     []string
+	ReconnectErr error
+}
+
+func (f *fakeSelfHealingProvider) CachedWorkspaces() []*WorkspaceInfo { return f.Infos }
+
+func (f *fakeSelfHealingProvider) RefreshWorkspace(ctx context.Context, workspaceID string) error {
+	f.Refreshed = append(f.Refreshed, workspaceID)
+	return nil
+}
+
+func (f *fakeSelfHealingProvider) RefreshCache(ctx context.Context) (*CacheRefresh, error) {
+	f.CacheRefresh++
+	return &CacheRefresh{}, nil
+}
+
+func (f *fakeSelfHealingProvider) Reconnect() error {
+	f.Reconnects++
+	return f.ReconnectErr
+}
+
+func (f *fakeSelfHealingProvider) EnterDegradedMode(duration time.Duration, lost []*WorkspaceInfo) {
+	for _, info := range lost {
+		f.Degraded = append(f.Degraded, info.WorkspaceID)
+	}
+	sort.Strings(f.Degraded)
+}
+
+func TestAnomalyErrorRate(t *testing.T) {
+	prov := &fakeSelfHealingProvider{}
+	d := NewAnomalyDetector(AnomalyDetectionConfig{
+		Cooldown: util.Duration(time.Minute),
+		ErrorRate: &ErrorRateAnomalyConfig{
+			Threshold:   0.5,
+			MinRequests: 4,
+			Actions:     []AnomalyAction{AnomalyActionRefreshWorkspace},
+		},
+	}, prov)
+	var alerts []AnomalyEvent
+	d.OnAnomaly = func(evt AnomalyEvent) { alerts = append(alerts, evt) }
+
+	handler := d.Handler()(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/fail" {
+			resp.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	serve := func(workspaceID, path string, n int) {
+		for i := 0; i < n; i++ {
+			req := mux.SetURLVars(httptest.NewRequest("GET", path, nil), map[string]string{workspaceIDIdentifier: workspaceID})
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+
+	// broken fails most requests, healthy only a few and quiet too few to tell
+	serve("broken", "/fail", 3)
+	serve("broken", "/", 1)
+	serve("healthy", "/fail", 1)
+	serve("healthy", "/", 9)
+	serve("quiet", "/fail", 3)
+	now := time.Now()
+	d.evaluate(now)
+	if diff := cmp.Diff([]string{"broken"}, prov.Refreshed); diff != "" {
+		t.Errorf("unexpected refreshed workspaces (-want +got):\n%s", diff)
+	}
+	if len(alerts) != 1 || alerts[0].Kind != AnomalyErrorRate || alerts[0].WorkspaceID != "broken" {
+		t.Fatalf("unexpected alerts: %+v", alerts)
+	}
+	if diff := cmp.Diff([]AnomalyActionResult{{Action: AnomalyActionRefreshWorkspace, Outcome: anomalyOutcomeSuccess}}, alerts[0].Actions); diff != "" {
+		t.Errorf("unexpected actions (-want +got):\n%s", diff)
+	}
+
+	// counts start over with every evaluation, and we do not refresh the same workspace again during the cooldown
+	serve("broken", "/fail", 4)
+	d.evaluate(now.Add(30 * time.Second))
+	if len(prov.Refreshed) != 1 {
+		t.Errorf("refreshed workspaces during the cooldown: %v", prov.Refreshed)
+	}
+	if evts := d.Events(); len(evts) != 2 || evts[1].Actions[0].Outcome != anomalyOutcomeCooldown {
+		t.Errorf("anomaly during the cooldown was not recorded: %+v", evts)
+	}
+	d.evaluate(now.Add(time.Minute))
+	if len(d.Events()) != 2 {
+		t.Errorf("anomaly without requests: %+v", d.Events())
+	}
+	serve("broken", "/fail", 4)
+	d.evaluate(now.Add(2 * time.Minute))
+	if len(prov.Refreshed) != 2 {
+		t.Errorf("did not refresh the workspace after the cooldown: %v", prov.Refreshed)
+	}
+}
+
+func TestAnomalyCacheShrinkage(t *testing.T) {
+	newInfos := func(n int) []*WorkspaceInfo {
+		res := make([]*WorkspaceInfo, 0, n)
+		for i := 0; i < n; i++ {
+			res = append(res, &WorkspaceInfo{WorkspaceID: fmt.Sprintf("ws-%d", i)})
+		}
+		return res
+	}
+
+	tests := []struct {
+		Name       string
+		DryRun     bool
+		Before     []*WorkspaceInfo
+		After      []*WorkspaceInfo
+		Degraded   []string
+		Reconnects int
+		Anomalies  int
+	}{
+		{
+			Name:       "shrinks",
+			Before:     newInfos(10),
+			After:      newInfos(6),
+			Degraded:   []string{"ws-6", "ws-7", "ws-8", "ws-9"},
+			Reconnects: 1,
+			Anomalies:  1,
+		},
+		{
+			Name:   "shrinks below the threshold",
+			Before: newInfos(10),
+			After:  newInfos(8),
+		},
+		{
+			Name:   "too small to tell",
+			Before: newInfos(5),
+			After:  newInfos(0),
+		},
+		{
+			Name:      "dry run",
+			DryRun:    true,
+			Before:    newInfos(10),
+			After:     newInfos(2),
+			Anomalies: 1,
+		},
+		{
+			// stale workspaces came from a snapshot or the degraded mode and are expected to go away
+			Name: "stale workspaces go away",
+			Before: append(newInfos(10), func() (res []*WorkspaceInfo) {
+				for i := 10; i < 20; i++ {
+					res = append(res, &WorkspaceInfo{WorkspaceID: fmt.Sprintf("ws-%d", i), Stale: true})
+				}
+				return
+			}()...),
+			After: newInfos(10),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			prov := &fakeSelfHealingProvider{ReconnectErr: xerrors.Errorf("not subscribed")}
+			d := NewAnomalyDetector(AnomalyDetectionConfig{
+				DryRun: test.DryRun,
+				CacheShrinkage: &CacheShrinkageAnomalyConfig{
+					Threshold: 0.3,
+					Actions:   []AnomalyAction{AnomalyActionDegradedMode, AnomalyActionReconnect},
+				},
+			}, prov)
+
+			now := time.Now()
+			prov.Infos = test.Before
+			d.evaluate(now)
+			prov.Infos = test.After
+			d.evaluate(now.Add(time.Second))
+
+			if diff := cmp.Diff(test.Degraded, prov.Degraded); diff != "" {
+				t.Errorf("unexpected workspaces in degraded mode (-want +got):\n%s", diff)
+			}
+			if prov.Reconnects != test.Reconnects {
+				t.Errorf("reconnected %d times, expected %d", prov.Reconnects, test.Reconnects)
+			}
+			evts := d.Events()
+			if len(evts) != test.Anomalies {
+				t.Fatalf("got %d anomalies, expected %d: %+v", len(evts), test.Anomalies, evts)
+			}
+			if test.Anomalies == 0 {
+				return
+			}
+			exp := []AnomalyActionResult{
+				{Action: AnomalyActionDegradedMode, Outcome: anomalyOutcomeSuccess},
+				{Action: AnomalyActionReconnect, Outcome: anomalyOutcomeError, Error: "not subscribed"},
+			}
+			if test.DryRun {
+				exp = []AnomalyActionResult{
+					{Action: AnomalyActionDegradedMode, Outcome: anomalyOutcomeDryRun},
+					{Action: AnomalyActionReconnect, Outcome: anomalyOutcomeDryRun},
+				}
+			}
+			if diff := cmp.Diff(exp, evts[0].Actions); diff != "" {
+				t.Errorf("unexpected actions (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDegradedMode(t *testing.T) {
+	prov := NewRemoteWorkspaceInfoProvider(WorkspaceInfoProviderConfig{WsManagerAddr: "target"})
+	other := *testWorkspaceInfo
+	other.WorkspaceID = "other"
+	other.IDEPublicPort = "8443"
+	other.Ports = nil
+	prov.cache.Insert(&other)
+
+	prov.EnterDegradedMode(time.Hour, []*WorkspaceInfo{testWorkspaceInfo, &other})
+	info := prov.WorkspaceInfo(context.Background(), testWorkspaceInfo.WorkspaceID)
+	if info == nil || !info.Stale {
+		t.Fatalf("lost workspace was not restored as a stale entry: %+v", info)
+	}
+	if testWorkspaceInfo.Stale {
+		t.Error("degraded mode modified the lost workspace")
+	}
+	if info := prov.WorkspaceInfo(context.Background(), other.WorkspaceID); info == nil || info.Stale {
+		t.Errorf("degraded mode replaced a workspace which was still in the cache: %+v", info)
+	}
+	if _, ok := prov.ReadOnly(); !ok {
+		t.Error("degraded mode is not read-only")
+	}
+	prov.cache.Delete(other.WorkspaceID, 0)
+	if prov.cache.Size() != 2 {
+		t.Error("cache is not frozen in degraded mode")
+	}
+
+	// ws-manager coming back does not end the degraded mode early
+	prov.recordSuccess(nil)
+	prov.cache.Delete(other.WorkspaceID, 0)
+	if prov.cache.Size() != 2 {
+		t.Error("recovering from read-only mode thawed the cache in degraded mode")
+	}
+
+	prov.mu.Lock()
+	prov.degradedUntil = time.Now().Add(-time.Second)
+	prov.mu.Unlock()
+	prov.leaveDegradedMode()
+	if _, ok := prov.ReadOnly(); ok {
+		t.Error("did not leave degraded mode")
+	}
+	prov.cache.Delete(other.WorkspaceID, 0)
+	if prov.cache.Size() != 1 {
+		t.Error("cache is still frozen after the degraded mode")
+	}
+}
+
+func TestAnomalyDetectionConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Cfg   *AnomalyDetectionConfig
+		Valid bool
+	}{
+		{Name: "disabled", Valid: true},
+		{Name: "nothing to detect", Cfg: &AnomalyDetectionConfig{}},
+		{
+			Name: "valid",
+			Cfg: &AnomalyDetectionConfig{
+				ErrorRate:      &ErrorRateAnomalyConfig{Threshold: 0.5, Actions: []AnomalyAction{AnomalyActionRefreshWorkspace}},
+				CacheShrinkage: &CacheShrinkageAnomalyConfig{Threshold: 0.3, Actions: []AnomalyAction{AnomalyActionDegradedMode, AnomalyActionRefreshCache}},
+			},
+			Valid: true,
+		},
+		{Name: "threshold above 1", Cfg: &AnomalyDetectionConfig{ErrorRate: &ErrorRateAnomalyConfig{Threshold: 2}}},
+		{Name: "missing threshold", Cfg: &AnomalyDetectionConfig{CacheShrinkage: &CacheShrinkageAnomalyConfig{}}},
+		{
+			Name: "reconnect per workspace",
+			Cfg:  &AnomalyDetectionConfig{ErrorRate: &ErrorRateAnomalyConfig{Threshold: 0.5, Actions: []AnomalyAction{AnomalyActionReconnect}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultAuditBufferSize    = 4096
+	defaultAuditFlushInterval = time.Second
+)
+
+// AuditConfig configures the audit log of authentication decisions
+type AuditConfig struct {
+	// File is where we append the audit events, one JSON object per line
+	File string `json:"file"`
+	// BufferSize is the number of events we hold while the file cannot keep up. Once the buffer is full we drop
+	// events rather than stall requests. Defaults to 4096.
+	BufferSize int `json:"bufferSize,omitempty"`
+	// FlushInterval is how often we write buffered events to the file. Defaults to one second.
+	FlushInterval util.Duration `json:"flushInterval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *AuditConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.File, validation.Required),
+		validation.Field(&c.BufferSize, validation.Min(0)),
+		validation.Field(&c.FlushInterval, validation.Min(util.Duration(0))),
+	)
+}
+
+// AuthReason explains an authentication decision
+type AuthReason string
+
+const (
+	// AuthReasonPublicWorkspace allows access because the workspace admits everyone
+	AuthReasonPublicWorkspace AuthReason = "public-workspace"
+	// AuthReasonPublicPort allows access because the port is public
+	AuthReasonPublicPort AuthReason = "public-port"
+	// AuthReasonOwnerToken allows access because the request carries the owner token
+	AuthReasonOwnerToken AuthReason = "owner-token"
+	// AuthReasonPortToken allows access because the request carries a valid port token
+	AuthReasonPortToken AuthReason = "port-token"
+	// AuthReasonNoWorkspaceID denies access because the request does not address a workspace
+	AuthReasonNoWorkspaceID AuthReason = "no-workspace-id"
+	// AuthReasonWorkspaceNotFound denies access because we don't know the workspace
+	AuthReasonWorkspaceNotFound AuthReason = "workspace-not-found"
+	// AuthReasonProxyNotReady denies access because we have not heard from ws-manager yet
+	AuthReasonProxyNotReady AuthReason = "proxy-not-ready"
+	// AuthReasonNoToken denies access because the request carries no owner token
+	AuthReasonNoToken AuthReason = "no-token"
+	// AuthReasonMalformedToken denies access because the owner token cannot be decoded
+	AuthReasonMalformedToken AuthReason = "malformed-token"
+	// AuthReasonTokenMismatch denies access because the token is not the owner token of the workspace
+	AuthReasonTokenMismatch AuthReason = "token-mismatch"
+	// AuthReasonThrottled denies access because the client failed too many owner token checks
+	AuthReasonThrottled AuthReason = "throttled"
+)
+
+// Sources of the token of an authentication decision
+const (
+	AuthTokenSourceCookie = "cookie"
+	AuthTokenSourceHeader = "header"
+)
+
+// AuthDecision is an audit event which records whether we let a request access a workspace
+type AuthDecision struct {
+	Time        time.Time `json:"time"`
+	WorkspaceID string    `json:"workspaceId,omitempty"`
+	InstanceID  string    `json:"instanceId,omitempty"`
+	Port        string    `json:"port,omitempty"`
+	// TokenHash is the SHA256 of the token the request presented. We never log the token itself.
+	TokenHash   string     `json:"tokenHash,omitempty"`
+	TokenSource string     `json:"tokenSource,omitempty"`
+	Allowed     bool       `json:"allowed"`
+	Reason      AuthReason `json:"reason"`
+	RemoteAddr  string     `json:"remoteAddr,omitempty"`
+	Method      string     `json:"method,omitempty"`
+	Host        string     `json:"host,omitempty"`
+	Path        string     `json:"path,omitempty"`
+}
+
+// newAuthDecision produces the audit event of a request
+func newAuthDecision(req *http.Request, wsID, port string) *AuthDecision {
+	return &AuthDecision{
+		Time:        time.Now(),
+		WorkspaceID: wsID,
+		Port:        port,
+		RemoteAddr:  req.RemoteAddr,
+		Method:      req.Method,
+		Host:        req.Host,
+		Path:        req.URL.Path,
+	}
+}
+
+// withToken records the token a request presented
+func (d *AuthDecision) withToken(source, token string) *AuthDecision {
+	d.TokenSource = source
+	if token != "" {
+		d.TokenHash = hashOwnerToken(token)
+	}
+	return d
+}
+
+func (d *AuthDecision) allow(reason AuthReason) *AuthDecision {
+	d.Allowed = true
+	d.Reason = reason
+	return d
+}
+
+func (d *AuthDecision) deny(reason AuthReason) *AuthDecision {
+	d.Allowed = false
+	d.Reason = reason
+	return d
+}
+
+// AuditLog writes authentication decisions to a file. Recording never blocks: if the file cannot keep up,
+// we drop events once the buffer is full and count them.
+type AuditLog struct {
+	out           io.WriteCloser
+	flushInterval time.Duration
+
+	mu     sync.RWMutex
+	events chan *AuthDecision
+	closed bool
+	done   chan struct{}
+
+	decisions *prometheus.CounterVec
+	dropped   prometheus.Counter
+}
+
+// NewAuditLog opens the audit log file and starts writing to it
+func NewAuditLog(cfg AuditConfig) (*AuditLog, error) {
+	f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot open audit log: %w", err)
+	}
+	return newAuditLog(f, cfg.BufferSize, time.Duration(cfg.FlushInterval)), nil
+}
+
+func newAuditLog(out io.WriteCloser, bufferSize int, flushInterval time.Duration) *AuditLog {
+	if bufferSize == 0 {
+		bufferSize = defaultAuditBufferSize
+	}
+	if flushInterval == 0 {
+		flushInterval = defaultAuditFlushInterval
+	}
+
+	a := &AuditLog{
+		out:           out,
+		flushInterval: flushInterval,
+		events:        make(chan *AuthDecision, bufferSize),
+		done:          make(chan struct{}),
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "audit_auth_decisions_total",
+			Help: "Authentication decisions on workspace routes",
+		}, []string{"allowed", "reason"}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "audit_events_dropped_total",
+			Help: "Audit events we dropped because the audit log could not keep up",
+		}),
+	}
+	go a.write()
+	return a
+}
+
+// Record adds an authentication decision to the audit log. Record is safe to call on a nil audit log.
+func (a *AuditLog) Record(d *AuthDecision) {
+	if a == nil {
+		return
+	}
+	a.decisions.WithLabelValues(strconv.FormatBool(d.Allowed), string(d.Reason)).Inc()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		a.dropped.Inc()
+		return
+	}
+	select {
+	case a.events <- d:
+	default:
+		a.dropped.Inc()
+	}
+}
+
+func (a *AuditLog) write() {
+	defer close(a.done)
+
+	var (
+		w      = bufio.NewWriter(a.out)
+		enc    = json.NewEncoder(w)
+		ticker = time.NewTicker(a.flushInterval)
+		failed bool
+	)
+	defer ticker.Stop()
+
+	report := func(err error) {
+		// once a write failed all subsequent ones fail too - there's no point in logging each of them
+		if err != nil && !failed {
+			log.WithError(err).Error("cannot write audit log")
+			failed = true
+		}
+	}
+	for {
+		select {
+		case d, ok := <-a.events:
+			if !ok {
+				report(w.Flush())
+				return
+			}
+			report(enc.Encode(d))
+		case <-ticker.C:
+			report(w.Flush())
+		}
+	}
+}
+
+// Close writes all buffered events and closes the audit log file
+func (a *AuditLog) Close() error {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.events)
+	a.mu.Unlock()
+
+	<-a.done
+	return a.out.Close()
+}
+
+// RegisterMetrics registers the audit log metrics
+func (a *AuditLog) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{a.decisions, a.dropped} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+type auditBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	// block, if not nil, blocks all writes until it's closed
+	block chan struct{}
+}
+
+func (b *auditBuffer) Write(p []byte) (int, error) {
+	if b.block != nil {
+		<-b.block
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *auditBuffer) Close() error { return nil }
+
+func TestAuditLogAuthDecisions(t *testing.T) {
+	const (
+		domain      = "test-domain.com"
+		workspaceID = "workspace-id"
+		instanceID  = "instance-id"
+		ownerToken  = "s3cr3t"
+	)
+	infos := map[string]*WorkspaceInfo{
+		workspaceID: {
+			WorkspaceID: workspaceID,
+			InstanceID:  instanceID,
+			Auth:        &api.WorkspaceAuthentication{Admission: api.AdmissionLevel_ADMIT_OWNER_ONLY, OwnerToken: ownerToken},
+			Ports:       []PortInfo{{PortSpec: api.PortSpec{Port: 8080, Visibility: api.PortVisibility_PORT_VISIBILITY_PUBLIC}}},
+		},
+	}
+
+	out := &auditBuffer{}
+	audit := newAuditLog(out, 10, time.Hour)
+	handler := WorkspaceAuthHandler(domain, &fixedInfoProvider{Infos: infos}, nil, audit, nil)(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+
+	requests := []struct {
+		WorkspaceID string
+		Port        string
+		Token       string
+	}{
+		{WorkspaceID: workspaceID, Token: ownerToken},
+		{WorkspaceID: workspaceID, Token: "guessed"},
+		{WorkspaceID: workspaceID, Port: "8080"},
+		{WorkspaceID: workspaceID, Port: "3000"},
+		{WorkspaceID: "unknown"},
+	}
+	for _, r := range requests {
+		req := httptest.NewRequest("GET", "http://"+domain+"/", nil)
+		if r.Token != "" {
+			setOwnerTokenCookie(req, instanceID, r.Token)
+		}
+		req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: r.WorkspaceID, workspacePortIdentifier: r.Port})
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	err := audit.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decisions []AuthDecision
+	for _, line := range strings.Split(strings.TrimSpace(out.buf.String()), "\n") {
+		var d AuthDecision
+		err := json.Unmarshal([]byte(line), &d)
+		if err != nil {
+			t.Fatalf("cannot parse audit event %q: %v", line, err)
+		}
+		decisions = append(decisions, d)
+	}
+
+	base := AuthDecision{WorkspaceID: workspaceID, InstanceID: instanceID, RemoteAddr: "192.0.2.1:1234", Method: "GET", Host: domain, Path: "/"}
+	decision := func(modify func(d *AuthDecision)) AuthDecision {
+		d := base
+		modify(&d)
+		return d
+	}
+	expectation := []AuthDecision{
+		decision(func(d *AuthDecision) {
+			d.Allowed, d.Reason, d.TokenSource, d.TokenHash = true, AuthReasonOwnerToken, AuthTokenSourceCookie, hashOwnerToken(ownerToken)
+		}),
+		decision(func(d *AuthDecision) {
+			d.Reason, d.TokenSource, d.TokenHash = AuthReasonTokenMismatch, AuthTokenSourceCookie, hashOwnerToken("guessed")
+		}),
+		decision(func(d *AuthDecision) { d.Allowed, d.Reason, d.Port = true, AuthReasonPublicPort, "8080" }),
+		decision(func(d *AuthDecision) {
+			d.Reason, d.Port, d.TokenSource = AuthReasonNoToken, "3000", AuthTokenSourceCookie
+		}),
+		decision(func(d *AuthDecision) {
+			d.Reason, d.WorkspaceID, d.InstanceID = AuthReasonWorkspaceNotFound, "unknown", ""
+		}),
+	}
+	if diff := cmp.Diff(expectation, decisions, cmpopts.IgnoreFields(AuthDecision{}, "Time")); diff != "" {
+		t.Errorf("unexpected audit events (-want +got):\n%s", diff)
+	}
+	if strings.Contains(out.buf.String(), ownerToken) {
+		t.Error("audit log contains the owner token")
+	}
+}
+
+func TestAuditLogBackpressure(t *testing.T) {
+	out := &auditBuffer{block: make(chan struct{})}
+	audit := newAuditLog(out, 2, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			audit.Record(&AuthDecision{Reason: AuthReasonNoToken})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("recording blocked on a stalled audit log")
+	}
+
+	if dropped := testutil.ToFloat64(audit.dropped); dropped < 90 {
+		t.Errorf("expected most events to be dropped, but only %v were", dropped)
+	}
+	close(out.block)
+	_ = audit.Close()
+}
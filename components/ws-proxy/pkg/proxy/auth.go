@@ -12,41 +12,56 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/ws-manager/api"
 )
 
-// WorkspaceAuthHandler rejects requests which are not authenticated or authorized to access a workspace
-func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.MiddlewareFunc {
+// WorkspaceAuthHandler rejects requests which are not authenticated or authorized to access a workspace.
+// If pages is nil, rejected requests get a status code only. If audit is not nil, we record every decision.
+// If throttle is not nil, clients which fail the owner token check too often are blocked for a while.
+func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider, pages *ErrorPages, audit *AuditLog, throttle *AuthThrottler) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
-		cookiePrefix := domain
-		for _, c := range []string{" ", "-", "."} {
-			cookiePrefix = strings.ReplaceAll(cookiePrefix, c, "_")
-		}
-		cookiePrefix = "_" + cookiePrefix + "_ws_"
+		cookiePrefix := ownerCookiePrefix(domain)
 
 		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 			var (
-				log  = getLog(req.Context())
-				vars = mux.Vars(req)
-				wsID = vars[workspaceIDIdentifier]
-				port = vars[workspacePortIdentifier]
+				log      = getLog(req.Context())
+				vars     = mux.Vars(req)
+				wsID     = vars[workspaceIDIdentifier]
+				port     = vars[workspacePortIdentifier]
+				decision = newAuthDecision(req, wsID, port)
 			)
 			if wsID == "" {
 				log.Warn("workspace request without workspace ID")
-				resp.WriteHeader(http.StatusForbidden)
+				audit.Record(decision.deny(AuthReasonNoWorkspaceID))
+				serveErrorPage(pages, resp, req, ErrorPageUnauthorized, http.StatusForbidden)
 				return
 			}
 
 			ws := info.WorkspaceInfo(req.Context(), wsID)
 			if ws == nil {
+				if serveNotReady(pages, info, resp, req) {
+					audit.Record(decision.deny(AuthReasonProxyNotReady))
+					return
+				}
 				log.Warn("did not find workspace info")
-				resp.WriteHeader(http.StatusNotFound)
+				audit.Record(decision.deny(AuthReasonWorkspaceNotFound))
+				serveErrorPage(pages, resp, req, ErrorPageWorkspaceNotFound, http.StatusNotFound)
 				return
 			}
+			decision.InstanceID = ws.InstanceID
 
 			if ws.Auth != nil && ws.Auth.Admission == api.AdmissionLevel_ADMIT_EVERYONE {
 				// workspace is free for all - no tokens or cookies matter
+				audit.Record(decision.allow(AuthReasonPublicWorkspace))
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			if port != "" && portTokenAuthorized(req) {
+				// the request carries a port token the owner issued for this very port
+				audit.Record(decision.allow(AuthReasonPortToken))
 				h.ServeHTTP(resp, req)
 				return
 			}
@@ -71,6 +86,7 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 
 				if isPublic {
 					// workspace port is free for all - no tokens or cookies matter
+					audit.Record(decision.allow(AuthReasonPublicPort))
 					h.ServeHTTP(resp, req)
 					return
 				}
@@ -78,27 +94,132 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 				// port seems to be private - subject it to the same access policy as the workspace itself
 			}
 
-			cn := fmt.Sprintf("%s%s_owner_", cookiePrefix, ws.InstanceID)
-			c, err := req.Cookie(cn)
-			if err != nil {
-				log.WithField("cookieName", cn).Warn("no owner cookie present")
-				resp.WriteHeader(http.StatusUnauthorized)
+			if retryAfter, allowed := throttle.Allow(req); !allowed {
+				log.Debug("client is blocked because of failed owner token checks")
+				audit.Record(decision.deny(AuthReasonThrottled))
+				serveThrottled(pages, resp, req, retryAfter)
 				return
 			}
 
-			tkn, err := url.QueryUnescape(c.Value)
+			tkn, reason, status, err := checkOwnerCookie(req, cookiePrefix, ws)
+			decision.withToken(AuthTokenSourceCookie, tkn)
 			if err != nil {
-				log.WithError(err).Warn("cannot decode owner token")
-				resp.WriteHeader(http.StatusBadRequest)
+				log.WithError(err).Warn("owner authentication failed")
+				if reason != AuthReasonNoToken {
+					throttle.Failed(req)
+				}
+				audit.Record(decision.deny(reason))
+				serveErrorPage(pages, resp, req, ErrorPageUnauthorized, status)
 				return
 			}
-			if tkn != ws.Auth.OwnerToken {
-				log.Warn("owner token mismatch")
-				resp.WriteHeader(http.StatusForbidden)
+
+			audit.Record(decision.allow(reason))
+			h.ServeHTTP(resp, req)
+		})
+	}
+}
+
+// WorkspaceOwnerAuthHandler rejects all requests which do not carry the workspace owner's token, irrespective of the
+// workspace's admission level. Clients which cannot use the owner cookie can pass the token in the X-Gitpod-Owner-Token header.
+// If throttle is not nil, clients which fail the owner token check too often are blocked for a while.
+func WorkspaceOwnerAuthHandler(domain string, info WorkspaceInfoProvider, pages *ErrorPages, audit *AuditLog, throttle *AuthThrottler) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		cookiePrefix := ownerCookiePrefix(domain)
+
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			var (
+				log      = getLog(req.Context())
+				wsID     = mux.Vars(req)[workspaceIDIdentifier]
+				decision = newAuthDecision(req, wsID, "")
+			)
+			if wsID == "" {
+				log.Warn("workspace request without workspace ID")
+				audit.Record(decision.deny(AuthReasonNoWorkspaceID))
+				serveErrorPage(pages, resp, req, ErrorPageUnauthorized, http.StatusForbidden)
 				return
 			}
 
+			ws := info.WorkspaceInfo(req.Context(), wsID)
+			if ws == nil {
+				if serveNotReady(pages, info, resp, req) {
+					audit.Record(decision.deny(AuthReasonProxyNotReady))
+					return
+				}
+				log.Warn("did not find workspace info")
+				audit.Record(decision.deny(AuthReasonWorkspaceNotFound))
+				serveErrorPage(pages, resp, req, ErrorPageWorkspaceNotFound, http.StatusNotFound)
+				return
+			}
+			decision.InstanceID = ws.InstanceID
+
+			if retryAfter, allowed := throttle.Allow(req); !allowed {
+				log.Debug("client is blocked because of failed owner token checks")
+				audit.Record(decision.deny(AuthReasonThrottled))
+				serveThrottled(pages, resp, req, retryAfter)
+				return
+			}
+
+			if tkn := req.Header.Get(workspaceOwnerTokenHeader); tkn != "" {
+				decision.withToken(AuthTokenSourceHeader, tkn)
+				if !ws.IsOwnerToken(tkn) {
+					log.Warn("owner token mismatch")
+					throttle.Failed(req)
+					audit.Record(decision.deny(AuthReasonTokenMismatch))
+					serveErrorPage(pages, resp, req, ErrorPageUnauthorized, http.StatusForbidden)
+					return
+				}
+				// the token is meant for ws-proxy only
+				req.Header.Del(workspaceOwnerTokenHeader)
+				audit.Record(decision.allow(AuthReasonOwnerToken))
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			tkn, reason, status, err := checkOwnerCookie(req, cookiePrefix, ws)
+			decision.withToken(AuthTokenSourceCookie, tkn)
+			if err != nil {
+				log.WithError(err).Warn("owner authentication failed")
+				if reason != AuthReasonNoToken {
+					throttle.Failed(req)
+				}
+				audit.Record(decision.deny(reason))
+				serveErrorPage(pages, resp, req, ErrorPageUnauthorized, status)
+				return
+			}
+
+			audit.Record(decision.allow(reason))
 			h.ServeHTTP(resp, req)
 		})
 	}
 }
+
+// workspaceOwnerTokenHeader carries the owner token of requests which cannot send the owner cookie
+const workspaceOwnerTokenHeader = "X-Gitpod-Owner-Token"
+
+// ownerCookiePrefix returns the prefix of the owner cookie names set by the Gitpod installation on domain
+func ownerCookiePrefix(domain string) string {
+	prefix := domain
+	for _, c := range []string{" ", "-", "."} {
+		prefix = strings.ReplaceAll(prefix, c, "_")
+	}
+	return "_" + prefix + "_ws_"
+}
+
+// checkOwnerCookie verifies that the request carries the owner cookie of the workspace. It returns the token the
+// cookie carries and the reason of the decision. If the request is not authorized, it returns the status code to respond with.
+func checkOwnerCookie(req *http.Request, cookiePrefix string, ws *WorkspaceInfo) (tkn string, reason AuthReason, status int, err error) {
+	cn := fmt.Sprintf("%s%s_owner_", cookiePrefix, ws.InstanceID)
+	c, err := req.Cookie(cn)
+	if err != nil {
+		return "", AuthReasonNoToken, http.StatusUnauthorized, xerrors.Errorf("no owner cookie %s present", cn)
+	}
+
+	tkn, err = url.QueryUnescape(c.Value)
+	if err != nil {
+		return "", AuthReasonMalformedToken, http.StatusBadRequest, xerrors.Errorf("cannot decode owner token: %w", err)
+	}
+	if !ws.IsOwnerToken(tkn) {
+		return tkn, AuthReasonTokenMismatch, http.StatusForbidden, xerrors.Errorf("owner token mismatch")
+	}
+	return tkn, AuthReasonOwnerToken, http.StatusOK, nil
+}
@@ -223,7 +223,7 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
 			var res testResult
-			handler := WorkspaceAuthHandler(domain, &fixedInfoProvider{Infos: test.Infos})(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			handler := WorkspaceAuthHandler(domain, &fixedInfoProvider{Infos: test.Infos}, nil, nil, nil)(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 				res.HandlerCalled = true
 				resp.WriteHeader(http.StatusOK)
 			}))
@@ -0,0 +1,374 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultAuthThrottleWindow = 10 * time.Minute
+	defaultAuthThrottleBlock  = 15 * time.Minute
+
+	authThrottleKeyFingerprint = "fingerprint"
+	authThrottleKeyIP          = "ip"
+)
+
+// AuthThrottleConfig configures the throttling of clients which repeatedly fail the owner token checks of workspaces,
+// e.g. because they try stolen or guessed tokens. We tell clients apart by their fingerprint: their IP address, user
+// agent and, if ws-proxy terminates TLS, their TLS ClientHello. Clients whose fingerprint fails the checks too often
+// within the window are blocked from all owner token checks for a while.
+type AuthThrottleConfig struct {
+	// MaxFailures is the number of failed owner token checks of a fingerprint within the window at which we block it
+	MaxFailures int `json:"maxFailures"`
+	// MaxFailuresPerIP is the number of failed checks of an IP address, irrespective of the fingerprint, at which
+	// we block the address. It catches clients which vary their user agent. Keep it well above MaxFailures, since many
+	// users may share an address, e.g. behind a corporate NAT. Zero disables blocking by address.
+	MaxFailuresPerIP int `json:"maxFailuresPerIP,omitempty"`
+	// Window is the period over which we count failed checks. Defaults to 10 minutes.
+	Window util.Duration `json:"window,omitempty"`
+	// Block is how long we block a client. Defaults to 15 minutes.
+	Block util.Duration `json:"block,omitempty"`
+	// Exempt lists the networks in CIDR notation we never throttle, e.g. our own monitoring
+	Exempt []string `json:"exempt,omitempty"`
+	// DryRun logs and counts the clients we would block, but does not block them
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *AuthThrottleConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.MaxFailures, validation.Required, validation.Min(1)),
+		validation.Field(&c.MaxFailuresPerIP, validation.Min(0)),
+		validation.Field(&c.Window, validation.Min(util.Duration(0))),
+		validation.Field(&c.Block, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid auth throttle config: %w", err)
+	}
+	_, err = parseTrustedNets(c.Exempt)
+	if err != nil {
+		return xerrors.Errorf("invalid auth throttle config: exempt: %w", err)
+	}
+	return nil
+}
+
+type authFailures struct {
+	failures     []time.Time
+	blockedUntil time.Time
+}
+
+// AuthThrottler blocks clients which repeatedly fail the owner token checks of workspaces. Requests of blocked
+// clients fail before we look at their token, so that they cannot keep guessing.
+type AuthThrottler struct {
+	Config AuthThrottleConfig
+
+	exempt trustedNets
+	now    func() time.Time
+
+	mu      sync.Mutex
+	clients map[string]*authFailures
+
+	// hellos maps the connections whose TLS handshake we've seen to the fingerprint of their ClientHello
+	hellos sync.Map
+
+	failures  *prometheus.CounterVec
+	blocks    *prometheus.CounterVec
+	throttled *prometheus.CounterVec
+}
+
+// NewAuthThrottler creates a new auth throttler
+func NewAuthThrottler(cfg AuthThrottleConfig) (*AuthThrottler, error) {
+	exempt, err := parseTrustedNets(cfg.Exempt)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthThrottler{
+		Config:  cfg,
+		exempt:  exempt,
+		now:     time.Now,
+		clients: make(map[string]*authFailures),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_throttle_failures_total",
+			Help: "Failed owner token checks we counted towards throttling, by whether the client had a TLS fingerprint",
+		}, []string{"tls_fingerprint"}),
+		blocks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_throttle_blocks_total",
+			Help: "Clients we blocked because of failed owner token checks, by key (fingerprint or ip) and whether we only pretended to (dry run)",
+		}, []string{"key", "dry_run"}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_throttle_rejections_total",
+			Help: "Requests of blocked clients, by whether we let them pass anyway (dry run)",
+		}, []string{"dry_run"}),
+	}, nil
+}
+
+// RegisterMetrics registers the auth throttling metrics
+func (t *AuthThrottler) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{t.failures, t.blocks, t.throttled} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Allow returns false and the time until the block ends if the client of the request is blocked.
+// If the throttler is nil, it allows all requests.
+func (t *AuthThrottler) Allow(req *http.Request) (retryAfter time.Duration, allowed bool) {
+	if t == nil {
+		return 0, true
+	}
+	ip := clientIP(req)
+	if t.isExempt(ip) {
+		return 0, true
+	}
+
+	now := t.now()
+	keys := t.keys(req, ip)
+	t.mu.Lock()
+	for _, key := range keys {
+		c, ok := t.clients[key]
+		if !ok || !c.blockedUntil.After(now) {
+			continue
+		}
+		if d := c.blockedUntil.Sub(now); d > retryAfter {
+			retryAfter = d
+		}
+	}
+	t.mu.Unlock()
+
+	if retryAfter == 0 {
+		return 0, true
+	}
+	t.throttled.WithLabelValues(strconv.FormatBool(t.Config.DryRun)).Inc()
+	if t.Config.DryRun {
+		return 0, true
+	}
+	return retryAfter, false
+}
+
+// Failed counts a failed owner token check of the client of the request, and blocks the client once it failed too often.
+// If the throttler is nil, it does nothing.
+func (t *AuthThrottler) Failed(req *http.Request) {
+	if t == nil {
+		return
+	}
+	ip := clientIP(req)
+	if t.isExempt(ip) {
+		return
+	}
+
+	tlsFingerprint := t.tlsFingerprint(req)
+	t.failures.WithLabelValues(strconv.FormatBool(tlsFingerprint != "")).Inc()
+
+	var (
+		now  = t.now()
+		keys = t.keys(req, ip)
+	)
+	limits := map[string]int{keys[0]: t.Config.MaxFailures}
+	if len(keys) > 1 {
+		limits[keys[1]] = t.Config.MaxFailuresPerIP
+	}
+
+	t.mu.Lock()
+	var blocked []string
+	for key, limit := range limits {
+		c, ok := t.clients[key]
+		if !ok {
+			t.prune(now)
+			c = &authFailures{}
+			t.clients[key] = c
+		}
+		if c.blockedUntil.After(now) {
+			// we're in dry run mode, otherwise the request would not have made it here
+			continue
+		}
+		c.failures = append(expireAuthFailures(c.failures, now, t.window()), now)
+		if len(c.failures) < limit {
+			continue
+		}
+		c.failures = nil
+		c.blockedUntil = now.Add(t.block())
+		blocked = append(blocked, key)
+	}
+	t.mu.Unlock()
+
+	for _, key := range blocked {
+		kind := authThrottleKeyFingerprint
+		if strings.HasPrefix(key, authThrottleKeyIP+":") {
+			kind = authThrottleKeyIP
+		}
+		t.blocks.WithLabelValues(kind, strconv.FormatBool(t.Config.DryRun)).Inc()
+		log := log.WithField("clientIP", ip).
+			WithField("key", kind).
+			WithField("fingerprint", keys[0]).
+			WithField("userAgent", req.UserAgent()).
+			WithField("tlsFingerprint", tlsFingerprint).
+			WithField("dryRun", t.Config.DryRun)
+		if t.Config.DryRun {
+			log.Warn("client failed too many owner token checks - not blocking it in dry run mode")
+			continue
+		}
+		log.WithField("until", now.Add(t.block())).Warn("client failed too many owner token checks - blocking it")
+	}
+}
+
+// keys returns the keys we count the failures of a request's client under: the fingerprint and, if we block by
+// address, the address
+func (t *AuthThrottler) keys(req *http.Request, ip string) []string {
+	res := []string{fingerprintClient(ip, req.UserAgent(), t.tlsFingerprint(req))}
+	if t.Config.MaxFailuresPerIP > 0 {
+		res = append(res, authThrottleKeyIP+":"+ip)
+	}
+	return res
+}
+
+func (t *AuthThrottler) isExempt(ip string) bool {
+	return t.exempt.Contains(net.ParseIP(ip))
+}
+
+func (t *AuthThrottler) window() time.Duration {
+	if t.Config.Window == 0 {
+		return defaultAuthThrottleWindow
+	}
+	return time.Duration(t.Config.Window)
+}
+
+func (t *AuthThrottler) block() time.Duration {
+	if t.Config.Block == 0 {
+		return defaultAuthThrottleBlock
+	}
+	return time.Duration(t.Config.Block)
+}
+
+// prune forgets the clients without recent failures whose block has ended. Callers must hold the lock.
+func (t *AuthThrottler) prune(now time.Time) {
+	for key, c := range t.clients {
+		if c.blockedUntil.After(now) {
+			continue
+		}
+		c.failures = expireAuthFailures(c.failures, now, t.window())
+		if len(c.failures) == 0 {
+			delete(t.clients, key)
+		}
+	}
+}
+
+func expireAuthFailures(failures []time.Time, now time.Time, window time.Duration) []time.Time {
+	i := 0
+	for i < len(failures) && now.Sub(failures[i]) > window {
+		i++
+	}
+	return failures[i:]
+}
+
+// clientIP returns the IP address of the client of a request. It must run after the client IP handler.
+func clientIP(req *http.Request) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return ip
+}
+
+// fingerprintClient combines what we know about a client into a fingerprint
+func fingerprintClient(ip, userAgent, tlsFingerprint string) string {
+	h := sha256.Sum256([]byte(ip + "\n" + userAgent + "\n" + tlsFingerprint))
+	return hex.EncodeToString(h[:8])
+}
+
+type tlsConnKey struct{}
+
+// connKey identifies a client connection by its local and remote address
+func connKey(c net.Conn) string {
+	return c.LocalAddr().String() + "|" + c.RemoteAddr().String()
+}
+
+// GetConfigForClient records the fingerprint of a TLS ClientHello. Use it as tls.Config.GetConfigForClient.
+func (t *AuthThrottler) GetConfigForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	if hello.Conn != nil {
+		t.hellos.Store(connKey(hello.Conn), clientHelloFingerprint(hello))
+	}
+	return nil, nil
+}
+
+// ConnContext lets the requests of a connection find the fingerprint of its TLS ClientHello. Use it as http.Server.ConnContext.
+func (t *AuthThrottler) ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, tlsConnKey{}, connKey(c))
+}
+
+// ConnState forgets the TLS fingerprints of connections we no longer serve requests on. Use it as http.Server.ConnState.
+func (t *AuthThrottler) ConnState(c net.Conn, state http.ConnState) {
+	if state == http.StateClosed || state == http.StateHijacked {
+		t.hellos.Delete(connKey(c))
+	}
+}
+
+// tlsFingerprint returns the fingerprint of the TLS ClientHello of the connection of a request, if we've seen one
+func (t *AuthThrottler) tlsFingerprint(req *http.Request) string {
+	key, ok := req.Context().Value(tlsConnKey{}).(string)
+	if !ok {
+		return ""
+	}
+	fp, ok := t.hellos.Load(key)
+	if !ok {
+		return ""
+	}
+	return fp.(string)
+}
+
+// clientHelloFingerprint fingerprints a TLS ClientHello in the spirit of JA3: the same client software produces
+// the same fingerprint. Go does not expose the extensions of a ClientHello, hence we use the versions, cipher suites,
+// curves, point formats, signature schemes and ALPN protocols the client offers.
+func clientHelloFingerprint(hello *tls.ClientHelloInfo) string {
+	var (
+		fields = make([]string, 0, 6)
+		field  = func(n int, v func(i int) uint64) {
+			vs := make([]string, n)
+			for i := range vs {
+				vs[i] = strconv.FormatUint(v(i), 10)
+			}
+			fields = append(fields, strings.Join(vs, "-"))
+		}
+	)
+	field(len(hello.SupportedVersions), func(i int) uint64 { return uint64(hello.SupportedVersions[i]) })
+	field(len(hello.CipherSuites), func(i int) uint64 { return uint64(hello.CipherSuites[i]) })
+	field(len(hello.SupportedCurves), func(i int) uint64 { return uint64(hello.SupportedCurves[i]) })
+	field(len(hello.SupportedPoints), func(i int) uint64 { return uint64(hello.SupportedPoints[i]) })
+	field(len(hello.SignatureSchemes), func(i int) uint64 { return uint64(hello.SignatureSchemes[i]) })
+	fields = append(fields, strings.Join(hello.SupportedProtos, "-"))
+
+	h := md5.Sum([]byte(strings.Join(fields, ",")))
+	return hex.EncodeToString(h[:])
+}
+
+// serveThrottled rejects the request of a blocked client
+func serveThrottled(pages *ErrorPages, resp http.ResponseWriter, req *http.Request, retryAfter time.Duration) {
+	resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	serveErrorPage(pages, resp, req, ErrorPageUnauthorized, http.StatusTooManyRequests)
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestAuthThrottleConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Cfg   *AuthThrottleConfig
+		Valid bool
+	}{
+		{Name: "nil", Valid: true},
+		{Name: "valid", Cfg: &AuthThrottleConfig{MaxFailures: 10, MaxFailuresPerIP: 100, Window: util.Duration(time.Minute), Exempt: []string{"10.0.0.0/8"}}, Valid: true},
+		{Name: "no max failures", Cfg: &AuthThrottleConfig{}},
+		{Name: "negative block", Cfg: &AuthThrottleConfig{MaxFailures: 10, Block: util.Duration(-time.Minute)}},
+		{Name: "invalid exemption", Cfg: &AuthThrottleConfig{MaxFailures: 10, Exempt: []string{"not-a-network"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestAuthThrottler(t *testing.T) {
+	newRequest := func(ip, userAgent string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "http://test-domain.com/", nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("User-Agent", userAgent)
+		return req
+	}
+	newThrottler := func(cfg AuthThrottleConfig) (*AuthThrottler, *time.Time) {
+		th, err := NewAuthThrottler(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+		th.now = func() time.Time { return now }
+		return th, &now
+	}
+	allowed := func(th *AuthThrottler, req *http.Request) bool {
+		_, ok := th.Allow(req)
+		return ok
+	}
+
+	t.Run("fingerprint", func(t *testing.T) {
+		th, now := newThrottler(AuthThrottleConfig{MaxFailures: 3, Window: util.Duration(time.Minute), Block: util.Duration(10 * time.Minute)})
+		attacker := newRequest("192.0.2.1", "curl/7.68.0")
+		for i := 0; i < 2; i++ {
+			th.Failed(attacker)
+		}
+		if !allowed(th, attacker) {
+			t.Fatal("client was blocked before reaching the maximum failures")
+		}
+
+		// failures outside the window do not count
+		*now = now.Add(2 * time.Minute)
+		th.Failed(attacker)
+		if !allowed(th, attacker) {
+			t.Fatal("expired failures counted towards the block")
+		}
+		th.Failed(attacker)
+		th.Failed(attacker)
+		retryAfter, ok := th.Allow(attacker)
+		if ok || retryAfter != 10*time.Minute {
+			t.Fatalf("expected client to be blocked for 10 minutes, got %v, %v", retryAfter, ok)
+		}
+		if !allowed(th, newRequest("192.0.2.1", "Mozilla/5.0")) {
+			t.Error("a different client behind the same address was blocked")
+		}
+
+		*now = now.Add(10*time.Minute + time.Second)
+		if !allowed(th, attacker) {
+			t.Error("client is still blocked after the block ended")
+		}
+	})
+
+	t.Run("ip", func(t *testing.T) {
+		th, _ := newThrottler(AuthThrottleConfig{MaxFailures: 3, MaxFailuresPerIP: 5})
+		for _, ua := range []string{"a", "b", "c", "d", "e"} {
+			th.Failed(newRequest("192.0.2.1", ua))
+		}
+		if allowed(th, newRequest("192.0.2.1", "f")) {
+			t.Error("expected address of client which varies its user agent to be blocked")
+		}
+		if !allowed(th, newRequest("192.0.2.2", "f")) {
+			t.Error("a different address was blocked")
+		}
+	})
+
+	t.Run("exempt", func(t *testing.T) {
+		th, _ := newThrottler(AuthThrottleConfig{MaxFailures: 1, Exempt: []string{"10.0.0.0/8"}})
+		monitoring := newRequest("10.1.2.3", "prober")
+		th.Failed(monitoring)
+		if !allowed(th, monitoring) {
+			t.Error("exempt client was blocked")
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		th, _ := newThrottler(AuthThrottleConfig{MaxFailures: 1, DryRun: true})
+		attacker := newRequest("192.0.2.1", "curl/7.68.0")
+		th.Failed(attacker)
+		if !allowed(th, attacker) {
+			t.Error("client was blocked in dry run mode")
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var th *AuthThrottler
+		th.Failed(newRequest("192.0.2.1", "curl/7.68.0"))
+		if !allowed(th, newRequest("192.0.2.1", "curl/7.68.0")) {
+			t.Error("nil throttler blocked a client")
+		}
+	})
+}
+
+func TestAuthThrottlerTLSFingerprint(t *testing.T) {
+	th, err := NewAuthThrottler(AuthThrottleConfig{MaxFailures: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hello := func(ciphers ...uint16) *tls.ClientHelloInfo {
+		return &tls.ClientHelloInfo{
+			CipherSuites:      ciphers,
+			SupportedVersions: []uint16{tls.VersionTLS13, tls.VersionTLS12},
+			SupportedCurves:   []tls.CurveID{tls.X25519, tls.CurveP256},
+			SupportedProtos:   []string{"h2", "http/1.1"},
+		}
+	}
+	if clientHelloFingerprint(hello(tls.TLS_AES_128_GCM_SHA256)) == clientHelloFingerprint(hello(tls.TLS_CHACHA20_POLY1305_SHA256)) {
+		t.Fatal("different ClientHellos have the same fingerprint")
+	}
+
+	// two connections from the same address and user agent, but with different TLS stacks
+	var (
+		scriptConn  = &fakeConn{local: "198.51.100.1:443", remote: "192.0.2.1:1000"}
+		browserConn = &fakeConn{local: "198.51.100.1:443", remote: "192.0.2.1:1001"}
+	)
+	scriptHello := hello(tls.TLS_AES_128_GCM_SHA256)
+	scriptHello.Conn = scriptConn
+	browserHello := hello(tls.TLS_CHACHA20_POLY1305_SHA256)
+	browserHello.Conn = browserConn
+	for _, h := range []*tls.ClientHelloInfo{scriptHello, browserHello} {
+		if _, err := th.GetConfigForClient(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newRequest := func(c *fakeConn) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "https://test-domain.com/", nil)
+		req = req.WithContext(th.ConnContext(context.Background(), c))
+		req.RemoteAddr = c.remote
+		req.Header.Set("User-Agent", "Mozilla/5.0")
+		return req
+	}
+
+	th.Failed(newRequest(scriptConn))
+	if _, ok := th.Allow(newRequest(scriptConn)); ok {
+		t.Error("expected client to be blocked")
+	}
+	if _, ok := th.Allow(newRequest(browserConn)); !ok {
+		t.Error("client with a different TLS fingerprint was blocked")
+	}
+
+	th.ConnState(scriptConn, http.StateClosed)
+	if fp := th.tlsFingerprint(newRequest(scriptConn)); fp != "" {
+		t.Errorf("expected TLS fingerprint of closed connection to be gone, got %s", fp)
+	}
+}
+
+func TestWorkspaceAuthHandlerThrottle(t *testing.T) {
+	const (
+		domain      = "test-domain.com"
+		workspaceID = "workspac-65f4-43c9-bf46-3541b89dca85"
+		instanceID  = "instance-fce1-4ff6-9364-cf6dff0c4ecf"
+		ownerToken  = "owner-token"
+	)
+	infos := &fixedInfoProvider{Infos: map[string]*WorkspaceInfo{
+		workspaceID: {
+			WorkspaceID: workspaceID,
+			InstanceID:  instanceID,
+			Auth: &api.WorkspaceAuthentication{
+				Admission:  api.AdmissionLevel_ADMIT_OWNER_ONLY,
+				OwnerToken: ownerToken,
+			},
+		},
+	}}
+	th, err := NewAuthThrottler(AuthThrottleConfig{MaxFailures: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := WorkspaceAuthHandler(domain, infos, nil, nil, th)(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+	serve := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://"+domain+"/", nil)
+		if token != "" {
+			setOwnerTokenCookie(req, instanceID, token)
+		}
+		req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: workspaceID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// requests without token, e.g. of users who are not logged in, are no guesses
+	for i := 0; i < 3; i++ {
+		if rr := serve(""); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected request without token to be unauthorized, got %d", rr.Code)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if rr := serve("guess"); rr.Code != http.StatusForbidden {
+			t.Fatalf("expected token mismatch, got %d", rr.Code)
+		}
+	}
+	rr := serve(ownerToken)
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") != "900" {
+		t.Errorf("expected blocked client not to get its token checked, got %d with Retry-After %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+	local, remote string
+}
+
+type fakeAddr string
+
+func (a fakeAddr) Network() string { return "tcp" }
+func (a fakeAddr) String() string  { return string(a) }
+
+func (c *fakeConn) LocalAddr() net.Addr  { return fakeAddr(c.local) }
+func (c *fakeConn) RemoteAddr() net.Addr { return fakeAddr(c.remote) }
@@ -0,0 +1,415 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultBandwidthRetention = 1 * time.Hour
+
+	// egressChunkSize is the most we write at once when egress is throttled, so that throttled streams flow evenly
+	egressChunkSize = 16 * 1024
+)
+
+// BandwidthConfig configures the accounting of the traffic of workspace instances and, optionally, limits their egress
+type BandwidthConfig struct {
+	// EgressBytesPerSecond limits how fast each workspace instance sends data to its clients, across all routes and connections.
+	// Zero means no limit.
+	EgressBytesPerSecond int64 `json:"egressBytesPerSecond,omitempty"`
+	// EgressBurstBytes is how much an instance may send at full speed before the limit applies. Defaults to EgressBytesPerSecond.
+	EgressBurstBytes int64 `json:"egressBurstBytes,omitempty"`
+	// Retention is how long we keep the counters of an instance after its last connection closed. Defaults to one hour.
+	Retention util.Duration `json:"retention,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *BandwidthConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.EgressBytesPerSecond, validation.Min(int64(0))),
+		validation.Field(&c.EgressBurstBytes, validation.Min(int64(0))),
+		validation.Field(&c.Retention, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid bandwidth config: %w", err)
+	}
+	if c.EgressBurstBytes > 0 && c.EgressBytesPerSecond == 0 {
+		return xerrors.Errorf("invalid bandwidth config: egressBurstBytes requires egressBytesPerSecond")
+	}
+	return nil
+}
+
+// BandwidthUsage is the traffic of a workspace instance
+type BandwidthUsage struct {
+	WorkspaceID string `json:"workspaceID"`
+	InstanceID  string `json:"instanceID"`
+	// BytesIn is what clients sent to the instance
+	BytesIn int64 `json:"bytesIn"`
+	// BytesOut is what the instance sent to its clients
+	BytesOut int64 `json:"bytesOut"`
+	// ThrottledSeconds is the time the instance's egress waited for the limit
+	ThrottledSeconds float64 `json:"throttledSeconds"`
+	// Connections is the number of requests and connections currently open
+	Connections int       `json:"connections"`
+	LastSeen    time.Time `json:"lastSeen"`
+}
+
+// egressLimiter is a token bucket of bytes. Writes may overdraw it and then wait until the debt is paid.
+type egressLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newEgressLimiter(cfg BandwidthConfig) *egressLimiter {
+	if cfg.EgressBytesPerSecond == 0 {
+		return nil
+	}
+	burst := cfg.EgressBurstBytes
+	if burst == 0 {
+		burst = cfg.EgressBytesPerSecond
+	}
+	return &egressLimiter{
+		rate:   float64(cfg.EgressBytesPerSecond),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// take takes n bytes out of the bucket and returns how long to wait before sending them
+func (l *egressLimiter) take(n int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.After(l.last) {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+	}
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// allow takes n tokens out of the bucket if it holds that many. Unlike take it never overdraws the bucket.
+func (l *egressLimiter) allow(n int, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.After(l.last) {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+	}
+	if l.tokens < float64(n) {
+		return false
+	}
+	l.tokens -= float64(n)
+	return true
+}
+
+// instanceBandwidth counts the traffic of a workspace instance
+type instanceBandwidth struct {
+	WorkspaceID string
+	InstanceID  string
+
+	in        int64
+	out       int64
+	throttled int64
+
+	limiter *egressLimiter
+
+	// connections and lastSeen are guarded by the tracker's lock
+	connections int
+	lastSeen    time.Time
+}
+
+// waitEgress waits until the instance may send n bytes
+func (b *instanceBandwidth) waitEgress(ctx context.Context, n int) error {
+	if b.limiter == nil {
+		return nil
+	}
+	wait := b.limiter.take(n, time.Now())
+	if wait <= 0 {
+		return nil
+	}
+	atomic.AddInt64(&b.throttled, int64(wait))
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// write sends p to a client of the instance, subject to its egress limit
+func (b *instanceBandwidth) write(ctx context.Context, w io.Writer, p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if b.limiter != nil && len(chunk) > egressChunkSize {
+			chunk = chunk[:egressChunkSize]
+		}
+		err = b.waitEgress(ctx, len(chunk))
+		if err != nil {
+			return n, err
+		}
+		var m int
+		m, err = w.Write(chunk)
+		n += m
+		atomic.AddInt64(&b.out, int64(m))
+		if err != nil {
+			return n, err
+		}
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// BandwidthTracker counts the bytes clients exchange with each workspace instance across all routes,
+// and limits the egress of instances if configured.
+type BandwidthTracker struct {
+	Config       BandwidthConfig
+	InfoProvider WorkspaceInfoProvider
+
+	mu        sync.Mutex
+	instances map[string]*instanceBandwidth
+
+	bytesDesc     *prometheus.Desc
+	throttledDesc *prometheus.Desc
+}
+
+// NewBandwidthTracker creates a new bandwidth tracker
+func NewBandwidthTracker(cfg BandwidthConfig, ip WorkspaceInfoProvider) *BandwidthTracker {
+	return &BandwidthTracker{
+		Config:       cfg,
+		InfoProvider: ip,
+		instances:    make(map[string]*instanceBandwidth),
+		bytesDesc: prometheus.NewDesc("workspace_bandwidth_bytes_total",
+			"Bytes exchanged with workspace instances we have seen traffic for recently, by direction",
+			[]string{"workspace", "instance", "direction"}, nil),
+		throttledDesc: prometheus.NewDesc("workspace_egress_throttled_seconds_total",
+			"Time the egress of workspace instances waited for the egress limit",
+			[]string{"workspace", "instance"}, nil),
+	}
+}
+
+// RegisterMetrics registers the bandwidth metrics. Only instances we have seen traffic for within the retention
+// period have metrics, which keeps their cardinality in check.
+func (t *BandwidthTracker) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(t)
+}
+
+// Describe implements prometheus.Collector
+func (t *BandwidthTracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.bytesDesc
+	ch <- t.throttledDesc
+}
+
+// Collect implements prometheus.Collector
+func (t *BandwidthTracker) Collect(ch chan<- prometheus.Metric) {
+	for _, u := range t.Usage() {
+		ch <- prometheus.MustNewConstMetric(t.bytesDesc, prometheus.CounterValue, float64(u.BytesIn), u.WorkspaceID, u.InstanceID, "ingress")
+		ch <- prometheus.MustNewConstMetric(t.bytesDesc, prometheus.CounterValue, float64(u.BytesOut), u.WorkspaceID, u.InstanceID, "egress")
+		ch <- prometheus.MustNewConstMetric(t.throttledDesc, prometheus.CounterValue, u.ThrottledSeconds, u.WorkspaceID, u.InstanceID)
+	}
+}
+
+// Usage lists the traffic of all instances we have seen traffic for within the retention period
+func (t *BandwidthTracker) Usage() []BandwidthUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(time.Now())
+	res := make([]BandwidthUsage, 0, len(t.instances))
+	for _, b := range t.instances {
+		res = append(res, BandwidthUsage{
+			WorkspaceID:      b.WorkspaceID,
+			InstanceID:       b.InstanceID,
+			BytesIn:          atomic.LoadInt64(&b.in),
+			BytesOut:         atomic.LoadInt64(&b.out),
+			ThrottledSeconds: time.Duration(atomic.LoadInt64(&b.throttled)).Seconds(),
+			Connections:      b.connections,
+			LastSeen:         b.lastSeen,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].InstanceID < res[j].InstanceID })
+	return res
+}
+
+// prune forgets the instances we have not seen traffic for within the retention period. Callers must hold the lock.
+func (t *BandwidthTracker) prune(now time.Time) {
+	retention := time.Duration(t.Config.Retention)
+	if retention == 0 {
+		retention = defaultBandwidthRetention
+	}
+	for id, b := range t.instances {
+		if b.connections == 0 && now.Sub(b.lastSeen) > retention {
+			delete(t.instances, id)
+		}
+	}
+}
+
+// open returns the counters of the instance of a workspace and counts a connection, or returns nil if we don't know the workspace
+func (t *BandwidthTracker) open(ctx context.Context, workspaceID string) *instanceBandwidth {
+	info := t.InfoProvider.WorkspaceInfo(ctx, workspaceID)
+	if info == nil || info.InstanceID == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.instances[info.InstanceID]
+	if !ok {
+		t.prune(time.Now())
+		b = &instanceBandwidth{
+			WorkspaceID: info.WorkspaceID,
+			InstanceID:  info.InstanceID,
+			limiter:     newEgressLimiter(t.Config),
+		}
+		t.instances[info.InstanceID] = b
+	}
+	b.connections++
+	b.lastSeen = time.Now()
+	return b
+}
+
+func (t *BandwidthTracker) close(b *instanceBandwidth) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b.connections--
+	b.lastSeen = time.Now()
+}
+
+// Handler counts the bytes of requests to workspaces and their responses, including websockets. If the tracker is nil, the handler does nothing.
+func (t *BandwidthTracker) Handler(h http.Handler) http.Handler {
+	if t == nil {
+		return h
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		workspaceID := getWorkspaceCoords(req).ID
+		if workspaceID == "" {
+			h.ServeHTTP(resp, req)
+			return
+		}
+		b := t.open(req.Context(), workspaceID)
+		if b == nil {
+			h.ServeHTTP(resp, req)
+			return
+		}
+		defer t.close(b)
+
+		if req.Body != nil && req.Body != http.NoBody {
+			req.Body = &countingBody{ReadCloser: req.Body, n: &b.in}
+		}
+		h.ServeHTTP(&bandwidthResponseWriter{ResponseWriter: resp, ctx: req.Context(), bw: b}, req)
+	})
+}
+
+// TrackConn counts the bytes exchanged over a client connection to a workspace, e.g. of the TCP proxy.
+// Call the returned function once the connection is closed. If the tracker is nil, the connection is returned as is.
+func (t *BandwidthTracker) TrackConn(ctx context.Context, workspaceID string, conn net.Conn) (net.Conn, func()) {
+	if t == nil {
+		return conn, func() {}
+	}
+	b := t.open(ctx, workspaceID)
+	if b == nil {
+		return conn, func() {}
+	}
+	return &bandwidthConn{Conn: conn, bw: b}, func() { t.close(b) }
+}
+
+// countingBody counts the bytes read from a request body
+type countingBody struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(b.n, int64(n))
+	return n, err
+}
+
+// bandwidthResponseWriter counts and throttles the bytes written to a response. It supports
+// hijacking and flushing so that websockets and streaming responses keep working.
+type bandwidthResponseWriter struct {
+	http.ResponseWriter
+	ctx context.Context
+	bw  *instanceBandwidth
+}
+
+func (w *bandwidthResponseWriter) Write(p []byte) (int, error) {
+	return w.bw.write(w.ctx, w.ResponseWriter, p)
+}
+
+func (w *bandwidthResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *bandwidthResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("response writer does not support hijacking")
+	}
+	conn, brw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &bandwidthConn{Conn: conn, bw: w.bw}, brw, nil
+}
+
+// bandwidthConn counts the bytes exchanged over a client connection and throttles what we write to it
+type bandwidthConn struct {
+	net.Conn
+	bw *instanceBandwidth
+}
+
+func (c *bandwidthConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(&c.bw.in, int64(n))
+	return n, err
+}
+
+func (c *bandwidthConn) Write(p []byte) (int, error) {
+	return c.bw.write(context.Background(), c.Conn, p)
+}
+
+// CloseWrite passes half-closed connections on
+func (c *bandwidthConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/mux"
+)
+
+func newTestBandwidthTracker(cfg BandwidthConfig) *BandwidthTracker {
+	return NewBandwidthTracker(cfg, &fixedInfoProvider{
+		Infos: map[string]*WorkspaceInfo{
+			"amaranth-smelt-9ba20cc1": {WorkspaceID: "amaranth-smelt-9ba20cc1", InstanceID: "instance-1"},
+		},
+	})
+}
+
+func TestBandwidthTrackerHandler(t *testing.T) {
+	tracker := newTestBandwidthTracker(BandwidthConfig{})
+	h := tracker.Handler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(ioutil.Discard, req.Body)
+		_, _ = resp.Write([]byte("hello world"))
+	}))
+
+	for _, wsid := range []string{"amaranth-smelt-9ba20cc1", "amaranth-smelt-9ba20cc1", "unknown", ""} {
+		req := httptest.NewRequest("POST", "http://example.com", strings.NewReader("0123456789"))
+		req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: wsid})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Body.String() != "hello world" {
+			t.Errorf("unexpected response %q", rec.Body.String())
+		}
+	}
+
+	expected := []BandwidthUsage{
+		{WorkspaceID: "amaranth-smelt-9ba20cc1", InstanceID: "instance-1", BytesIn: 20, BytesOut: 22},
+	}
+	if diff := cmp.Diff(expected, tracker.Usage(), cmpopts.IgnoreFields(BandwidthUsage{}, "LastSeen")); diff != "" {
+		t.Errorf("unexpected usage (-want +got):\n%s", diff)
+	}
+}
+
+func TestBandwidthTrackerConn(t *testing.T) {
+	tracker := newTestBandwidthTracker(BandwidthConfig{})
+	client, server := net.Pipe()
+	defer client.Close()
+
+	conn, release := tracker.TrackConn(context.Background(), "amaranth-smelt-9ba20cc1", server)
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+		_, _ = io.ReadFull(client, make([]byte, 5))
+	}()
+	_, err := io.ReadFull(conn, make([]byte, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Write([]byte("pong!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usage := tracker.Usage()
+	if len(usage) != 1 || usage[0].BytesIn != 4 || usage[0].BytesOut != 5 || usage[0].Connections != 1 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	release()
+	if usage := tracker.Usage(); usage[0].Connections != 0 {
+		t.Errorf("expected the connection to be released, got %+v", usage)
+	}
+}
+
+func TestBandwidthTrackerPrune(t *testing.T) {
+	tracker := newTestBandwidthTracker(BandwidthConfig{})
+	_, release := tracker.TrackConn(context.Background(), "amaranth-smelt-9ba20cc1", nil)
+
+	tracker.mu.Lock()
+	tracker.prune(time.Now().Add(2 * defaultBandwidthRetention))
+	n := len(tracker.instances)
+	tracker.mu.Unlock()
+	if n != 1 {
+		t.Errorf("instances with open connections must not be pruned")
+	}
+
+	release()
+	tracker.mu.Lock()
+	tracker.prune(time.Now().Add(2 * defaultBandwidthRetention))
+	n = len(tracker.instances)
+	tracker.mu.Unlock()
+	if n != 0 {
+		t.Errorf("expected idle instances to be pruned")
+	}
+}
+
+func TestEgressLimiter(t *testing.T) {
+	var (
+		now = time.Now()
+		l   = newEgressLimiter(BandwidthConfig{EgressBytesPerSecond: 1000, EgressBurstBytes: 500})
+	)
+	l.last = now
+
+	for i, test := range []struct {
+		After time.Duration
+		Bytes int
+		Wait  time.Duration
+	}{
+		{Bytes: 500},
+		{Bytes: 100, Wait: 100 * time.Millisecond},
+		{Bytes: 100, Wait: 200 * time.Millisecond},
+		// time pays the debt
+		{After: 200 * time.Millisecond, Bytes: 100, Wait: 100 * time.Millisecond},
+		// but the bucket never holds more than the burst
+		{After: 10 * time.Second, Bytes: 600, Wait: 100 * time.Millisecond},
+	} {
+		now = now.Add(test.After)
+		if wait := l.take(test.Bytes, now).Round(time.Millisecond); wait != test.Wait {
+			t.Errorf("take %d: waited %s, expected %s", i, wait, test.Wait)
+		}
+	}
+
+	if newEgressLimiter(BandwidthConfig{}) != nil {
+		t.Errorf("expected no limiter without egress limit")
+	}
+}
+
+func TestBandwidthEgressThrottling(t *testing.T) {
+	tracker := newTestBandwidthTracker(BandwidthConfig{EgressBytesPerSecond: 100 * 1024, EgressBurstBytes: 10 * 1024})
+	h := tracker.Handler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		_, _ = resp.Write(make([]byte, 30*1024))
+	}))
+
+	req := httptest.NewRequest("GET", "http://example.com", nil)
+	req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: "amaranth-smelt-9ba20cc1"})
+	start := time.Now()
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	// 20 KiB beyond the burst take 200ms at 100 KiB/s
+	if d := time.Since(start); d < 150*time.Millisecond {
+		t.Errorf("expected the response to be throttled, took %s", d)
+	}
+	usage := tracker.Usage()
+	if len(usage) != 1 || usage[0].BytesOut != 30*1024 || usage[0].ThrottledSeconds == 0 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
+
+func TestBandwidthConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *BandwidthConfig
+		Valid  bool
+	}{
+		{Name: "nil", Valid: true},
+		{Name: "accounting only", Config: &BandwidthConfig{}, Valid: true},
+		{Name: "egress limit", Config: &BandwidthConfig{EgressBytesPerSecond: 1 << 20, EgressBurstBytes: 4 << 20}, Valid: true},
+		{Name: "negative limit", Config: &BandwidthConfig{EgressBytesPerSecond: -1}},
+		{Name: "burst without limit", Config: &BandwidthConfig{EgressBurstBytes: 1 << 20}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
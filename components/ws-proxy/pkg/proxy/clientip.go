@@ -0,0 +1,387 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultProxyProtocolTimeout = 5 * time.Second
+
+	// a PROXY protocol v1 header is at most 107 bytes long
+	maxProxyProtocolHeaderLen = 107
+)
+
+var proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// ClientIPConfig configures how we learn the IP of clients when ws-proxy sits behind a load balancer.
+// Audit logs, client identity propagation and workspaces see the client IP instead of the load balancer's.
+type ClientIPConfig struct {
+	// TrustedProxies are the IPs or CIDRs of load balancers whose PROXY protocol and X-Forwarded-For/X-Real-IP headers we honor.
+	// Clients outside of these networks cannot claim another IP.
+	TrustedProxies []string `json:"trustedProxies"`
+	// ProxyProtocol accepts PROXY protocol v1 and v2 headers from trusted proxies on all inbound listeners
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+	// ProxyProtocolTimeout limits how long we wait for the PROXY protocol header. Defaults to 5 seconds.
+	ProxyProtocolTimeout util.Duration `json:"proxyProtocolTimeout,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ClientIPConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.TrustedProxies, validation.Required),
+		validation.Field(&c.ProxyProtocolTimeout, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid client IP config: %w", err)
+	}
+	_, err = parseTrustedNets(c.TrustedProxies)
+	if err != nil {
+		return xerrors.Errorf("invalid client IP config: %w", err)
+	}
+	return nil
+}
+
+// trustedNets are the networks of trusted proxies
+type trustedNets []*net.IPNet
+
+func parseTrustedNets(cidrs []string) (trustedNets, error) {
+	res := make(trustedNets, 0, len(cidrs))
+	for _, c := range cidrs {
+		if !strings.Contains(c, "/") {
+			ip := net.ParseIP(c)
+			if ip == nil {
+				return nil, xerrors.Errorf("%s is neither an IP nor a CIDR", c)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			res = append(res, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, xerrors.Errorf("%s is neither an IP nor a CIDR", c)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// Contains returns true if ip belongs to a trusted proxy
+func (t trustedNets) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range t {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIPHandler sets the remote address of requests which arrive through trusted proxies to the address of the client.
+// The client is the rightmost untrusted entry of X-Forwarded-For, or X-Real-IP. Client ports are not forwarded, hence
+// such remote addresses have port 0. Requests from untrusted peers must not forward headers in the name of someone else.
+func clientIPHandler(cfg *ClientIPConfig) (func(http.Handler) http.Handler, error) {
+	if cfg == nil {
+		return func(h http.Handler) http.Handler { return h }, nil
+	}
+	trusted, err := parseTrustedNets(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			peer, _, err := net.SplitHostPort(req.RemoteAddr)
+			if err != nil || !trusted.Contains(net.ParseIP(peer)) {
+				req.Header.Del("X-Forwarded-For")
+				req.Header.Del("X-Real-IP")
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			var forwarded []string
+			for _, hdr := range req.Header.Values("X-Forwarded-For") {
+				for _, ip := range strings.Split(hdr, ",") {
+					forwarded = append(forwarded, strings.TrimSpace(ip))
+				}
+			}
+			client := ""
+			for i := len(forwarded) - 1; i >= 0; i-- {
+				ip := net.ParseIP(forwarded[i])
+				if ip == nil {
+					// we cannot trust anything beyond an entry which is not an IP
+					break
+				}
+				client = ip.String()
+				// the reverse proxy appends the client again, hence we keep only the hops before it
+				forwarded = forwarded[:i]
+				if !trusted.Contains(ip) {
+					break
+				}
+			}
+			if client == "" {
+				if ip := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); ip != nil {
+					client = ip.String()
+				}
+			}
+			if client != "" {
+				req.RemoteAddr = net.JoinHostPort(client, "0")
+				req.Header.Set("X-Real-IP", client)
+				if len(forwarded) > 0 {
+					req.Header.Set("X-Forwarded-For", strings.Join(forwarded, ", "))
+				} else {
+					req.Header.Del("X-Forwarded-For")
+				}
+			}
+			h.ServeHTTP(resp, req)
+		})
+	}, nil
+}
+
+// proxyProtocolListener reads the PROXY protocol header of connections from trusted proxies and reports
+// the source address of the header as remote address. Connections without header are served as they are.
+type proxyProtocolListener struct {
+	net.Listener
+
+	trusted trustedNets
+	timeout time.Duration
+
+	conns     chan net.Conn
+	errs      chan error
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// newProxyProtocolListener wraps ln. If cfg does not enable the PROXY protocol, ln is returned as is.
+func newProxyProtocolListener(ln net.Listener, cfg *ClientIPConfig) (net.Listener, error) {
+	if cfg == nil || !cfg.ProxyProtocol {
+		return ln, nil
+	}
+	trusted, err := parseTrustedNets(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(cfg.ProxyProtocolTimeout)
+	if timeout == 0 {
+		timeout = defaultProxyProtocolTimeout
+	}
+
+	res := &proxyProtocolListener{
+		Listener: ln,
+		trusted:  trusted,
+		timeout:  timeout,
+		conns:    make(chan net.Conn),
+		errs:     make(chan error),
+		closed:   make(chan struct{}),
+	}
+	go res.acceptLoop()
+	return res, nil
+}
+
+// acceptLoop reads headers in the background, so that slow clients cannot block accepting other connections
+func (l *proxyProtocolListener) acceptLoop() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			select {
+			case l.errs <- err:
+			case <-l.closed:
+				return
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		go l.handshake(conn)
+	}
+}
+
+func (l *proxyProtocolListener) handshake(conn net.Conn) {
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok && l.trusted.Contains(addr.IP) {
+		_ = conn.SetReadDeadline(time.Now().Add(l.timeout))
+		br := bufio.NewReader(conn)
+		src, _, err := readProxyHeader(br)
+		if err != nil {
+			log.WithError(err).WithField("remoteAddr", conn.RemoteAddr().String()).Debug("invalid PROXY protocol header")
+			conn.Close()
+			return
+		}
+		_ = conn.SetReadDeadline(time.Time{})
+		pc := &prefixConn{Conn: conn, r: br}
+		if src != nil {
+			pc.remote = src
+		}
+		conn = pc
+	}
+
+	select {
+	case l.conns <- conn:
+	case <-l.closed:
+		conn.Close()
+	}
+}
+
+// Accept returns the next connection whose PROXY protocol header we've read
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.closed:
+		return nil, xerrors.Errorf("listener closed")
+	}
+}
+
+// Close closes the listener
+func (l *proxyProtocolListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return l.Listener.Close()
+}
+
+// readProxyHeader reads a PROXY protocol v1 or v2 header if the connection starts with one. Returns nil addresses if
+// there is no header, or the header does not carry addresses, e.g. for health checks of the load balancer.
+func readProxyHeader(r *bufio.Reader) (src, dst *net.TCPAddr, err error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cannot read PROXY protocol header: %w", err)
+	}
+	switch first[0] {
+	case 'P':
+		sig, err := r.Peek(6)
+		if err != nil || string(sig) != "PROXY " {
+			return nil, nil, nil
+		}
+		return readProxyHeaderV1(r)
+	case proxyProtocolV2Signature[0]:
+		sig, err := r.Peek(len(proxyProtocolV2Signature))
+		if err != nil || !bytes.Equal(sig, proxyProtocolV2Signature) {
+			return nil, nil, nil
+		}
+		return readProxyHeaderV2(r)
+	default:
+		return nil, nil, nil
+	}
+}
+
+func readProxyHeaderV1(r *bufio.Reader) (src, dst *net.TCPAddr, err error) {
+	var line []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, xerrors.Errorf("cannot read PROXY protocol header: %w", err)
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= maxProxyProtocolHeaderLen {
+			return nil, nil, xerrors.Errorf("PROXY protocol header is too long")
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, xerrors.Errorf("PROXY protocol header does not end with CRLF")
+	}
+
+	fields := strings.Split(string(bytes.TrimSuffix(line, []byte("\r\n"))), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, nil, xerrors.Errorf("unsupported PROXY protocol header %q", line)
+	}
+	srcIP, dstIP := net.ParseIP(fields[2]), net.ParseIP(fields[3])
+	srcPort, srcErr := strconv.ParseUint(fields[4], 10, 16)
+	dstPort, dstErr := strconv.ParseUint(fields[5], 10, 16)
+	if srcIP == nil || dstIP == nil || srcErr != nil || dstErr != nil {
+		return nil, nil, xerrors.Errorf("invalid addresses in PROXY protocol header %q", line)
+	}
+	return &net.TCPAddr{IP: srcIP, Port: int(srcPort)}, &net.TCPAddr{IP: dstIP, Port: int(dstPort)}, nil
+}
+
+func readProxyHeaderV2(r *bufio.Reader) (src, dst *net.TCPAddr, err error) {
+	hdr := make([]byte, 16)
+	_, err = io.ReadFull(r, hdr)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cannot read PROXY protocol header: %w", err)
+	}
+	var (
+		version = hdr[12] >> 4
+		command = hdr[12] & 0x0f
+		family  = hdr[13]
+		length  = binary.BigEndian.Uint16(hdr[14:16])
+	)
+	if version != 2 {
+		return nil, nil, xerrors.Errorf("unsupported PROXY protocol version %d", version)
+	}
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cannot read PROXY protocol header: %w", err)
+	}
+
+	const (
+		commandLocal = 0x0
+		commandProxy = 0x1
+		familyTCP4   = 0x11
+		familyTCP6   = 0x21
+	)
+	switch command {
+	case commandLocal:
+		return nil, nil, nil
+	case commandProxy:
+	default:
+		return nil, nil, xerrors.Errorf("unsupported PROXY protocol command %d", command)
+	}
+
+	var ipLen int
+	switch family {
+	case familyTCP4:
+		ipLen = net.IPv4len
+	case familyTCP6:
+		ipLen = net.IPv6len
+	default:
+		// e.g. UDP or unix sockets - the header carries no TCP addresses
+		return nil, nil, nil
+	}
+	if len(body) < 2*ipLen+4 {
+		return nil, nil, xerrors.Errorf("PROXY protocol header is too short")
+	}
+	src = &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), body[:ipLen]...)),
+		Port: int(binary.BigEndian.Uint16(body[2*ipLen:])),
+	}
+	dst = &net.TCPAddr{
+		IP:   net.IP(append([]byte(nil), body[ipLen:2*ipLen]...)),
+		Port: int(binary.BigEndian.Uint16(body[2*ipLen+2:])),
+	}
+	return src, dst, nil
+}
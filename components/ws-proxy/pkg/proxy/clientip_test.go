@@ -0,0 +1,222 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadProxyHeader(t *testing.T) {
+	v2 := func(command, family byte, addrs []byte) string {
+		hdr := append([]byte(nil), proxyProtocolV2Signature...)
+		hdr = append(hdr, 0x20|command, family, 0, 0)
+		binary.BigEndian.PutUint16(hdr[14:], uint16(len(addrs)))
+		return string(append(hdr, addrs...))
+	}
+	tcp4 := []byte{192, 0, 2, 1, 10, 0, 0, 1, 0xdc, 0x04, 0x75, 0x31}
+
+	tests := []struct {
+		Name   string
+		Header string
+		Src    string
+		Dst    string
+		Rest   string
+		Error  bool
+	}{
+		{Name: "v1 TCP4", Header: "PROXY TCP4 192.0.2.1 10.0.0.1 56324 30001\r\nGET /", Src: "192.0.2.1:56324", Dst: "10.0.0.1:30001", Rest: "GET /"},
+		{Name: "v1 TCP6", Header: "PROXY TCP6 2001:db8::1 fd00::1 56324 30001\r\n", Src: "[2001:db8::1]:56324", Dst: "[fd00::1]:30001"},
+		{Name: "v1 unknown", Header: "PROXY UNKNOWN\r\nGET /", Rest: "GET /"},
+		{Name: "v1 without CR", Header: "PROXY TCP4 192.0.2.1 10.0.0.1 56324 30001\n", Error: true},
+		{Name: "v1 invalid port", Header: "PROXY TCP4 192.0.2.1 10.0.0.1 56324 65536\r\n", Error: true},
+		{Name: "v1 too long", Header: "PROXY TCP4 " + strings.Repeat("1", 120) + "\r\n", Error: true},
+		{Name: "v2 TCP4", Header: v2(0x1, 0x11, tcp4) + "GET /", Src: "192.0.2.1:56324", Dst: "10.0.0.1:30001", Rest: "GET /"},
+		{Name: "v2 TLVs", Header: v2(0x1, 0x11, append(tcp4, 0x04, 0x00, 0x01, 0x00)) + "GET /", Src: "192.0.2.1:56324", Dst: "10.0.0.1:30001", Rest: "GET /"},
+		{Name: "v2 local", Header: v2(0x0, 0x00, nil) + "GET /", Rest: "GET /"},
+		{Name: "v2 too short", Header: v2(0x1, 0x11, tcp4[:8]), Error: true},
+		{Name: "no header", Header: "GET / HTTP/1.1\r\n", Rest: "GET / HTTP/1.1\r\n"},
+		{Name: "no header starting with P", Header: "POST / HTTP/1.1\r\n", Rest: "POST / HTTP/1.1\r\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(test.Header))
+			src, dst, err := readProxyHeader(r)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.Error {
+				return
+			}
+
+			str := func(a *net.TCPAddr) string {
+				if a == nil {
+					return ""
+				}
+				return a.String()
+			}
+			rest, _ := io.ReadAll(r)
+			if diff := cmp.Diff([]string{test.Src, test.Dst, test.Rest}, []string{str(src), str(dst), string(rest)}); diff != "" {
+				t.Errorf("unexpected result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientIPHandler(t *testing.T) {
+	tests := []struct {
+		Name          string
+		RemoteAddr    string
+		Header        http.Header
+		RemoteAddrExp string
+		HeaderExp     http.Header
+	}{
+		{
+			Name:          "untrusted peer",
+			RemoteAddr:    "192.0.2.1:56324",
+			Header:        http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-Ip": {"198.51.100.1"}},
+			RemoteAddrExp: "192.0.2.1:56324",
+			HeaderExp:     http.Header{},
+		},
+		{
+			Name:          "trusted peer",
+			RemoteAddr:    "10.0.0.1:56324",
+			Header:        http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			RemoteAddrExp: "198.51.100.1:0",
+			HeaderExp:     http.Header{"X-Real-Ip": {"198.51.100.1"}},
+		},
+		{
+			Name:          "spoofed entries are kept for the workspace",
+			RemoteAddr:    "10.0.0.1:56324",
+			Header:        http.Header{"X-Forwarded-For": {"203.0.113.7, 198.51.100.1", "10.0.0.2"}},
+			RemoteAddrExp: "198.51.100.1:0",
+			HeaderExp:     http.Header{"X-Forwarded-For": {"203.0.113.7"}, "X-Real-Ip": {"198.51.100.1"}},
+		},
+		{
+			Name:          "invalid entry",
+			RemoteAddr:    "10.0.0.1:56324",
+			Header:        http.Header{"X-Forwarded-For": {"198.51.100.1, unknown"}},
+			RemoteAddrExp: "10.0.0.1:56324",
+			HeaderExp:     http.Header{"X-Forwarded-For": {"198.51.100.1, unknown"}},
+		},
+		{
+			Name:          "X-Real-IP",
+			RemoteAddr:    "[fd00::1]:56324",
+			Header:        http.Header{"X-Real-Ip": {"2001:db8::1"}},
+			RemoteAddrExp: "[2001:db8::1]:0",
+			HeaderExp:     http.Header{"X-Real-Ip": {"2001:db8::1"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			mw, err := clientIPHandler(&ClientIPConfig{TrustedProxies: []string{"10.0.0.0/8", "fd00::1"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				remoteAddr string
+				header     http.Header
+			)
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				remoteAddr = req.RemoteAddr
+				header = req.Header
+			}))
+			req := httptest.NewRequest("GET", "http://example.com", nil)
+			req.RemoteAddr = test.RemoteAddr
+			req.Header = test.Header
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if remoteAddr != test.RemoteAddrExp {
+				t.Errorf("unexpected remote address: want %s, got %s", test.RemoteAddrExp, remoteAddr)
+			}
+			if diff := cmp.Diff(test.HeaderExp, header); diff != "" {
+				t.Errorf("unexpected header (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := newProxyProtocolListener(inner, &ClientIPConfig{
+		TrustedProxies: []string{"127.0.0.1"},
+		ProxyProtocol:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, req.RemoteAddr)
+	})}
+	go srv.Serve(ln)
+
+	// a slow client must not block others
+	slow, err := net.Dial("tcp", inner.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slow.Close()
+
+	for _, test := range []struct {
+		Prefix      string
+		Expectation string
+	}{
+		{Prefix: "PROXY TCP4 192.0.2.1 10.0.0.1 56324 443\r\n", Expectation: "192.0.2.1:56324"},
+		{Prefix: "", Expectation: "127.0.0.1:"},
+	} {
+		conn, err := net.Dial("tcp", inner.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		fmt.Fprintf(conn, "%sGET / HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n", test.Prefix)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		conn.Close()
+		if !strings.HasPrefix(string(body), test.Expectation) {
+			t.Errorf("unexpected remote address: want %s, got %s", test.Expectation, body)
+		}
+	}
+}
+
+func TestClientIPConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *ClientIPConfig
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "valid", Config: &ClientIPConfig{TrustedProxies: []string{"10.0.0.0/8", "fd00::/8", "192.0.2.1"}, ProxyProtocol: true}},
+		{Name: "no trusted proxies", Config: &ClientIPConfig{ProxyProtocol: true}, Error: true},
+		{Name: "invalid CIDR", Config: &ClientIPConfig{TrustedProxies: []string{"10.0.0.0/33"}}, Error: true},
+		{Name: "invalid IP", Config: &ClientIPConfig{TrustedProxies: []string{"load-balancer"}}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+)
+
+const (
+	encodingGzip   = "gzip"
+	encodingBrotli = "br"
+
+	defaultCompressionMinSize = 1024
+	// defaultBrotliLevel trades compression ratio for speed, as we compress on the fly
+	defaultBrotliLevel = 4
+)
+
+var (
+	defaultCompressionEncodings    = []string{encodingGzip}
+	defaultCompressionContentTypes = []string{
+		"text/*",
+		"application/javascript",
+		"application/x-javascript",
+		"application/json",
+		"application/manifest+json",
+		"application/xml",
+		"application/wasm",
+		"image/svg+xml",
+	}
+)
+
+// CompressionConfig configures the compression of proxied responses
+type CompressionConfig struct {
+	// Encodings are the content encodings we compress with, in order of preference. Supported are "br" and "gzip".
+	// Defaults to gzip.
+	Encodings []string `json:"encodings,omitempty"`
+	// ContentTypes are the media types we compress. Entries ending in "/*" match all subtypes of a type.
+	// Defaults to text, JavaScript, JSON, XML, SVG and WebAssembly.
+	ContentTypes []string `json:"contentTypes,omitempty"`
+	// MinSize is the size in bytes below which we do not compress responses. Defaults to 1024.
+	MinSize int `json:"minSize,omitempty"`
+	// Ports enables compression on exposed ports. IDE routes are always compressed.
+	Ports bool `json:"ports,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *CompressionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Encodings, validation.Each(validation.In(encodingBrotli, encodingGzip))),
+		validation.Field(&c.ContentTypes, validation.Each(validation.By(func(o interface{}) error {
+			ct, ok := o.(string)
+			if !ok {
+				return xerrors.Errorf("field should be a string")
+			}
+			if _, _, err := mime.ParseMediaType(ct); err != nil {
+				return xerrors.Errorf("invalid content type %s: %w", ct, err)
+			}
+			return nil
+		}))),
+		validation.Field(&c.MinSize, validation.Min(0)),
+	)
+}
+
+// compressor compresses responses according to a compression config
+type compressor struct {
+	Encodings    []string
+	ContentTypes []string
+	MinSize      int
+}
+
+func newCompressor(cfg *CompressionConfig) *compressor {
+	res := &compressor{
+		Encodings:    defaultCompressionEncodings,
+		ContentTypes: defaultCompressionContentTypes,
+		MinSize:      defaultCompressionMinSize,
+	}
+	if cfg == nil {
+		return res
+	}
+	if len(cfg.Encodings) > 0 {
+		res.Encodings = cfg.Encodings
+	}
+	if len(cfg.ContentTypes) > 0 {
+		res.ContentTypes = cfg.ContentTypes
+	}
+	if cfg.MinSize > 0 {
+		res.MinSize = cfg.MinSize
+	}
+	return res
+}
+
+// compressHandler compresses responses except for websockets and the supervisor API, whose streams the
+// compression would buffer
+func compressHandler(cfg *CompressionConfig) mux.MiddlewareFunc {
+	c := newCompressor(cfg)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if isWebsocketRequest(req) || strings.HasPrefix(req.URL.Path, "/_supervisor/v1/") {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			c.ServeHTTP(h, resp, req)
+		})
+	}
+}
+
+// portCompressHandler compresses responses of exposed ports if the config asks for it
+func portCompressHandler(cfg *CompressionConfig) mux.MiddlewareFunc {
+	if cfg == nil || !cfg.Ports {
+		return func(h http.Handler) http.Handler { return h }
+	}
+	c := newCompressor(cfg)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if isWebsocketRequest(req) {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			c.ServeHTTP(h, resp, req)
+		})
+	}
+}
+
+// ServeHTTP serves the request using h and compresses the response if the client accepts one of our encodings
+func (c *compressor) ServeHTTP(h http.Handler, resp http.ResponseWriter, req *http.Request) {
+	encoding := c.negotiate(req.Header.Get("Accept-Encoding"))
+	if encoding == "" || req.Method == http.MethodHead {
+		h.ServeHTTP(resp, req)
+		return
+	}
+
+	w := &compressResponseWriter{
+		ResponseWriter: resp,
+		compressor:     c,
+		encoding:       encoding,
+	}
+	defer w.Close()
+
+	var rw http.ResponseWriter = w
+	if _, ok := resp.(http.Hijacker); ok {
+		rw = hijackableCompressResponseWriter{w}
+	}
+	h.ServeHTTP(rw, req)
+}
+
+// negotiate picks the first of our encodings the client accepts
+func (c *compressor) negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	accepted := make(map[string]bool)
+	for _, e := range strings.Split(acceptEncoding, ",") {
+		var (
+			segs = strings.Split(e, ";")
+			name = strings.ToLower(strings.TrimSpace(segs[0]))
+			ok   = true
+		)
+		for _, p := range segs[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64)
+			ok = err == nil && q > 0
+		}
+		accepted[name] = ok
+	}
+	for _, e := range c.Encodings {
+		ok, present := accepted[e]
+		if !present {
+			ok = accepted["*"]
+		}
+		if ok {
+			return e
+		}
+	}
+	return ""
+}
+
+// compressible returns true if we compress responses of the content type
+func (c *compressor) compressible(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, ct := range c.ContentTypes {
+		if ct == mt {
+			return true
+		}
+		if strings.HasSuffix(ct, "/*") && strings.HasPrefix(mt, strings.TrimSuffix(ct, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressResponseWriter decides whether to compress a response once the handler writes the header. Responses without
+// Content-Length are buffered until they reach the minimum size. Responses the upstream compressed already pass through.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	compressor *compressor
+	encoding   string
+
+	status      int
+	wroteHeader bool
+	// pending is true while we buffer a response of unknown size
+	pending bool
+	buf     []byte
+	enc     io.WriteCloser
+}
+
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+
+	hdr := w.Header()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent ||
+		hdr.Get("Content-Encoding") != "" || hdr.Get("Content-Range") != "" ||
+		!w.compressor.compressible(hdr.Get("Content-Type")) {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	hdr.Add("Vary", "Accept-Encoding")
+	if cl := hdr.Get("Content-Length"); cl != "" {
+		size, err := strconv.Atoi(cl)
+		if err != nil || size < w.compressor.MinSize {
+			w.ResponseWriter.WriteHeader(status)
+			return
+		}
+		w.startCompression()
+		return
+	}
+	w.pending = true
+}
+
+func (w *compressResponseWriter) startCompression() {
+	hdr := w.Header()
+	hdr.Del("Content-Length")
+	hdr.Set("Content-Encoding", w.encoding)
+	// the compressed representation is no longer byte-for-byte identical
+	if etag := hdr.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		hdr.Set("ETag", "W/"+etag)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	switch w.encoding {
+	case encodingBrotli:
+		w.enc = brotli.NewWriterLevel(w.ResponseWriter, defaultBrotliLevel)
+	default:
+		w.enc = gzip.NewWriter(w.ResponseWriter)
+	}
+}
+
+// passThrough sends a pending response uncompressed
+func (w *compressResponseWriter) passThrough() error {
+	w.pending = false
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			// net/http would sniff the content type once we write - we need it before that to decide
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	if !w.pending {
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.compressor.MinSize {
+		return len(b), nil
+	}
+	w.pending = false
+	w.startCompression()
+	buf := w.buf
+	w.buf = nil
+	_, err := w.enc.Write(buf)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush sends pending responses uncompressed, as someone wants them delivered now
+func (w *compressResponseWriter) Flush() {
+	if w.pending {
+		_ = w.passThrough()
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the response once the handler returned
+func (w *compressResponseWriter) Close() error {
+	if w.pending {
+		return w.passThrough()
+	}
+	if w.enc != nil {
+		return w.enc.Close()
+	}
+	return nil
+}
+
+// hijackableCompressResponseWriter lets handlers take over connections we did not write to yet
+type hijackableCompressResponseWriter struct {
+	*compressResponseWriter
+}
+
+func (w hijackableCompressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.wroteHeader {
+		return nil, nil, xerrors.Errorf("cannot hijack a connection after writing the response header")
+	}
+	// we must not write anything once the handler owns the connection
+	w.wroteHeader = true
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
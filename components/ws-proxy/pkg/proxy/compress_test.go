@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestCompressorNegotiate(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Encodings      []string
+		AcceptEncoding string
+		Expectation    string
+	}{
+		{Name: "none", AcceptEncoding: ""},
+		{Name: "gzip", AcceptEncoding: "gzip, deflate", Expectation: "gzip"},
+		{Name: "preference", Encodings: []string{"br", "gzip"}, AcceptEncoding: "gzip, br", Expectation: "br"},
+		{Name: "fallback", Encodings: []string{"br", "gzip"}, AcceptEncoding: "gzip", Expectation: "gzip"},
+		{Name: "refused", Encodings: []string{"br", "gzip"}, AcceptEncoding: "br;q=0, gzip;q=0.5", Expectation: "gzip"},
+		{Name: "wildcard", AcceptEncoding: "*", Expectation: "gzip"},
+		{Name: "wildcard refused", AcceptEncoding: "*;q=0"},
+		{Name: "unsupported", AcceptEncoding: "deflate"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			c := newCompressor(&CompressionConfig{Encodings: test.Encodings})
+			if act := c.negotiate(test.AcceptEncoding); act != test.Expectation {
+				t.Errorf("unexpected encoding: want %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestCompressHandler(t *testing.T) {
+	large := strings.Repeat("console.log('hello world');\n", 100)
+	tests := []struct {
+		Name           string
+		Config         *CompressionConfig
+		AcceptEncoding string
+		Header         http.Header
+		Body           string
+		Flush          bool
+		Encoding       string
+	}{
+		{Name: "gzip", AcceptEncoding: "gzip", Header: http.Header{"Content-Type": {"application/javascript"}}, Body: large, Encoding: "gzip"},
+		{Name: "brotli", Config: &CompressionConfig{Encodings: []string{"br", "gzip"}}, AcceptEncoding: "gzip, br", Header: http.Header{"Content-Type": {"application/javascript"}}, Body: large, Encoding: "br"},
+		{Name: "content length", AcceptEncoding: "gzip", Header: http.Header{"Content-Type": {"text/css"}, "Content-Length": {strconv.Itoa(len(large))}}, Body: large, Encoding: "gzip"},
+		{Name: "sniffed content type", AcceptEncoding: "gzip", Body: "<html>" + large, Encoding: "gzip"},
+		{Name: "client does not accept", Header: http.Header{"Content-Type": {"application/javascript"}}, Body: large},
+		{Name: "too small", AcceptEncoding: "gzip", Header: http.Header{"Content-Type": {"application/json"}}, Body: `{"foo":"bar"}`},
+		{Name: "too small with content length", AcceptEncoding: "gzip", Header: http.Header{"Content-Type": {"application/json"}, "Content-Length": {"13"}}, Body: `{"foo":"bar"}`},
+		{Name: "content type not allowed", AcceptEncoding: "gzip", Header: http.Header{"Content-Type": {"image/png"}}, Body: large},
+		{Name: "already compressed", AcceptEncoding: "gzip", Header: http.Header{"Content-Type": {"application/javascript"}, "Content-Encoding": {"br"}}, Body: large},
+		{Name: "flushed before min size", AcceptEncoding: "gzip", Header: http.Header{"Content-Type": {"text/plain"}}, Body: "streaming", Flush: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			h := compressHandler(test.Config)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				for k, v := range test.Header {
+					w.Header()[k] = v
+				}
+				if test.Flush {
+					_, _ = io.WriteString(w, test.Body)
+					w.(http.Flusher).Flush()
+					return
+				}
+				_, _ = io.WriteString(w, test.Body[:len(test.Body)/2])
+				_, _ = io.WriteString(w, test.Body[len(test.Body)/2:])
+			}))
+
+			req := httptest.NewRequest("GET", "http://example.com/foo.js", nil)
+			if test.AcceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", test.AcceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			resp := rec.Result()
+			if act := resp.Header.Get("Content-Encoding"); test.Header.Get("Content-Encoding") == "" && act != test.Encoding {
+				t.Fatalf("unexpected content encoding: want %q, got %q", test.Encoding, act)
+			}
+			var body io.Reader = resp.Body
+			switch test.Encoding {
+			case "gzip":
+				gz, err := gzip.NewReader(resp.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gz
+				if resp.Header.Get("Content-Length") != "" {
+					t.Error("compressed responses must not have the content length of the uncompressed body")
+				}
+			case "br":
+				body = brotli.NewReader(resp.Body)
+			}
+			act, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exp := test.Body; string(act) != exp {
+				t.Errorf("unexpected body: want %d bytes, got %d bytes", len(exp), len(act))
+			}
+		})
+	}
+}
+
+func TestCompressHandlerETag(t *testing.T) {
+	h := compressHandler(nil)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = io.WriteString(w, strings.Repeat("<p>hello</p>", 200))
+	}))
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if etag := rec.Header().Get("ETag"); etag != `W/"abc"` {
+		t.Errorf("expected a weak ETag, got %s", etag)
+	}
+	if vary := rec.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("expected Vary: Accept-Encoding, got %s", vary)
+	}
+}
+
+func TestCompressionConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *CompressionConfig
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "valid", Config: &CompressionConfig{Encodings: []string{"br", "gzip"}, ContentTypes: []string{"text/*", "application/json"}, MinSize: 512, Ports: true}},
+		{Name: "unsupported encoding", Config: &CompressionConfig{Encodings: []string{"deflate"}}, Error: true},
+		{Name: "invalid content type", Config: &CompressionConfig{ContentTypes: []string{"text/"}}, Error: true},
+		{Name: "negative min size", Config: &CompressionConfig{MinSize: -1}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
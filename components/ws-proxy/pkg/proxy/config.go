@@ -29,6 +29,83 @@ type Config struct {
 	WorkspacePodConfig *WorkspacePodConfig `json:"workspacePodConfig"`
 
 	BuiltinPages BuiltinPagesConfig `json:"builtinPages"`
+
+	WakeOnRequest *WakeOnRequestConfig `json:"wakeOnRequest,omitempty"`
+
+	DynamicPorts *DynamicPortsConfig `json:"dynamicPorts,omitempty"`
+
+	SLO *SLOConfig `json:"slo,omitempty"`
+
+	Headers *HeaderPolicyConfig `json:"headers,omitempty"`
+
+	PortCORS *PortCORSConfig `json:"portCors,omitempty"`
+
+	// Routes tunes the upstream, timeouts, retries and websockets of individual routes
+	Routes RouteTable `json:"routes,omitempty"`
+
+	// UpstreamRetry retries requests to workspaces which do not accept connections yet
+	UpstreamRetry *UpstreamRetryConfig `json:"upstreamRetry,omitempty"`
+
+	// Audit records all authentication decisions on workspace routes
+	Audit *AuditConfig `json:"audit,omitempty"`
+
+	// Experiments configures the IDE variants of the experiments ws-manager assigns workspaces to
+	Experiments Experiments `json:"experiments,omitempty"`
+
+	// TCP exposes public workspace ports to non-HTTP clients
+	TCP *TCPProxyConfig `json:"tcp,omitempty"`
+
+	// ClientIP determines the client IP of requests which arrive through a load balancer
+	ClientIP *ClientIPConfig `json:"clientIP,omitempty"`
+
+	// ActivityReport reports the connections we have open to workspaces to ws-manager, where they count as activity
+	ActivityReport *ActivityReportConfig `json:"activityReport,omitempty"`
+
+	// Compression configures the compression of IDE responses and, optionally, exposed ports
+	Compression *CompressionConfig `json:"compression,omitempty"`
+
+	// ForeignContent serves webviews and the mini-browser from their own hosts
+	ForeignContent *ForeignContentConfig `json:"foreignContent,omitempty"`
+
+	// PortCredentials configures which Gitpod cookies and internal headers reach applications on exposed ports
+	PortCredentials *PortCredentialsConfig `json:"portCredentials,omitempty"`
+
+	// Bandwidth accounts the traffic of workspace instances and optionally limits their egress
+	Bandwidth *BandwidthConfig `json:"bandwidth,omitempty"`
+
+	// AbuseDetection restricts public ports which look like they host abusive content to the workspace owner
+	AbuseDetection *AbuseDetectionConfig `json:"abuseDetection,omitempty"`
+
+	// AnomalyDetection alerts on error-rate spikes and sudden cache shrinkage and heals the workspace info cache
+	AnomalyDetection *AnomalyDetectionConfig `json:"anomalyDetection,omitempty"`
+
+	// AuthThrottle blocks clients which fail the owner token checks of workspaces too often, e.g. credential stuffing
+	AuthThrottle *AuthThrottleConfig `json:"authThrottle,omitempty"`
+
+	// Prewarm connects to the IDE of workspaces as soon as they are running
+	Prewarm *PrewarmConfig `json:"prewarm,omitempty"`
+
+	// PortTokens enables signed URLs which expose a workspace port under /t/{token}, e.g. for webhooks
+	PortTokens *PortTokensConfig `json:"portTokens,omitempty"`
+
+	// SchemeRedirect redirects plain HTTP requests to HTTPS and sends HSTS headers, per domain
+	SchemeRedirect *SchemeRedirectConfig `json:"schemeRedirect,omitempty"`
+
+	// TURN relays UDP traffic of WebRTC applications in workspaces, e.g. data channels with external peers
+	TURN *TURNConfig `json:"turn,omitempty"`
+
+	// Handshake negotiates the protocol capabilities with the supervisor of workspaces, e.g. compression of its API
+	Handshake *HandshakeConfig `json:"handshake,omitempty"`
+
+	// UpstreamDiscovery balances requests to internal upstreams, e.g. blobserve, across the ready endpoints of their service
+	UpstreamDiscovery *UpstreamDiscoveryConfig `json:"upstreamDiscovery,omitempty"`
+
+	// PortGate confirms with supervisor that a workspace port is served before we route to it
+	PortGate *PortGateConfig `json:"portGate,omitempty"`
+
+	// VerifyEndpoint serves the synthetic workspace `ws-proxy verify` checks an installation against. Off by default:
+	// enable it while you verify an installation only, as anyone can reach the workspace.
+	VerifyEndpoint bool `json:"verifyEndpoint,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -41,12 +118,45 @@ func (c *Config) Validate() error {
 		c.BlobServer,
 		c.GitpodInstallation,
 		c.WorkspacePodConfig,
+		c.WakeOnRequest,
+		c.DynamicPorts,
+		c.SLO,
+		c.Headers,
+		c.PortCORS,
+		c.Routes,
+		c.UpstreamRetry,
+		c.Audit,
+		c.Experiments,
+		c.TCP,
+		c.ClientIP,
+		c.ActivityReport,
+		c.Compression,
+		c.ForeignContent,
+		c.PortCredentials,
+		c.Bandwidth,
+		c.AbuseDetection,
+		c.AnomalyDetection,
+		c.AuthThrottle,
+		c.Prewarm,
+		c.PortTokens,
+		c.SchemeRedirect,
+		c.TURN,
+		c.Handshake,
+		c.UpstreamDiscovery,
+		c.PortGate,
 	} {
 		err := v.Validate()
 		if err != nil {
 			return err
 		}
 	}
+	if c.SchemeRedirect != nil && c.Headers != nil && c.Headers.StrictTransportSecurity != "" {
+		for _, d := range c.SchemeRedirect.Domains {
+			if d.HSTS != nil {
+				return xerrors.Errorf("configure HSTS either in headers or in schemeRedirect, not both")
+			}
+		}
+	}
 
 	return nil
 }
@@ -99,6 +209,9 @@ func (c *GitpodInstallation) Validate() error {
 type BlobServerConfig struct {
 	Scheme string `json:"scheme"`
 	Host   string `json:"host"`
+
+	// IDEStatic serves static IDE resources from blobserve. If nil, only the IDE root is served from blobserve.
+	IDEStatic *IDEStaticConfig `json:"ideStatic,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -110,6 +223,7 @@ func (c *BlobServerConfig) Validate() error {
 	err := validation.ValidateStruct(c,
 		validation.Field(&c.Scheme, validation.Required, validation.In("http", "https")),
 		validation.Field(&c.Host, validation.Required),
+		validation.Field(&c.IDEStatic),
 	)
 	if err != nil {
 		return fmt.Errorf("invalid blobserver config: %w", err)
@@ -123,6 +237,19 @@ type TransportConfig struct {
 	IdleConnTimeout          util.Duration `json:"idleConnTimeout"`
 	WebsocketIdleConnTimeout util.Duration `json:"websocketIdleConnTimeout"`
 	MaxIdleConns             int           `json:"maxIdleConns"`
+
+	// MaxIdleConnsPerUpstream is the number of idle connections we keep per workspace upstream. Defaults to 16.
+	MaxIdleConnsPerUpstream int `json:"maxIdleConnsPerUpstream,omitempty"`
+	// PoolTTL is how long we keep the connection pool of an upstream we have not talked to. Defaults to 10 minutes.
+	PoolTTL util.Duration `json:"poolTTL,omitempty"`
+	// HTTP2 enables HTTP/2 with prior knowledge (h2c) towards workspace upstreams. Websockets always use HTTP/1.1,
+	// gRPC requests always use HTTP/2.
+	HTTP2 bool `json:"http2,omitempty"`
+
+	// Dial configures how we connect to upstreams
+	Dial *UpstreamDialConfig `json:"dial,omitempty"`
+	// ClientIdentity propagates the client IP to workspace ports. If nil, workspace ports see ws-proxy as client.
+	ClientIdentity *ClientIdentityConfig `json:"clientIdentity,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -136,12 +263,48 @@ func (c *TransportConfig) Validate() error {
 		validation.Field(&c.IdleConnTimeout, validation.Required),
 		validation.Field(&c.WebsocketIdleConnTimeout, validation.Required),
 		validation.Field(&c.MaxIdleConns, validation.Required, validation.Min(1)),
+		validation.Field(&c.MaxIdleConnsPerUpstream, validation.Min(0)),
+		validation.Field(&c.PoolTTL, validation.Min(util.Duration(0))),
+		validation.Field(&c.Dial),
+		validation.Field(&c.ClientIdentity),
+	)
+}
+
+// DynamicPortsConfig enables routing to workspace ports which were never exposed.
+// Requests to such ports go straight to the workspace pod and are subject to the workspace's access policy.
+type DynamicPortsConfig struct {
+	// Start is the first port of the range we route dynamically
+	Start uint16 `json:"start"`
+	// End is the last port of the range we route dynamically
+	End uint16 `json:"end"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *DynamicPortsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Start, validation.Required),
+		validation.Field(&c.End, validation.Required, validation.Min(c.Start)),
 	)
+	if err != nil {
+		return xerrors.Errorf("invalid dynamic ports config: %w", err)
+	}
+	return nil
+}
+
+// Contains returns true if the port is in the dynamic port range
+func (c *DynamicPortsConfig) Contains(port uint16) bool {
+	return c != nil && c.Start <= port && port <= c.End
 }
 
 // BuiltinPagesConfig configures pages served directly by ws-proxy
 type BuiltinPagesConfig struct {
 	Location string `json:"location"`
+	// TemplateLocation is an optional directory of error page templates which take precedence over the builtin ones
+	TemplateLocation string `json:"templateLocation,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -154,8 +317,14 @@ func (c *BuiltinPagesConfig) Validate() error {
 		validation.Field(&c.Location,
 			validation.Required,
 			validation.By(validateFileExists("")),
-			validation.By(validateFileExists(builtinPagePortNotFound)),
+			validation.By(validateFileExists(builtinPageError)),
 		),
+		validation.Field(&c.TemplateLocation, validation.By(func(value interface{}) error {
+			if c.TemplateLocation == "" {
+				return nil
+			}
+			return validateFileExists("")(value)
+		})),
 	)
 }
 
@@ -0,0 +1,221 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	// portCORSOptInHeader lets dev servers opt into permissive CORS by responding with "X-Gitpod-CORS: permissive"
+	portCORSOptInHeader = "X-Gitpod-CORS"
+	portCORSPermissive  = "permissive"
+)
+
+var defaultPortCORSMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+// PortCORSConfig configures how we answer cross-origin requests to exposed workspace ports.
+// Requests from origins we don't allow pass through unchanged, so that dev servers can still handle CORS themselves.
+type PortCORSConfig struct {
+	// AllowedOrigins are regular expressions matching the full origin, e.g. https://.*\.example\.com
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// AllowCredentials lets browsers send cookies along with cross-origin requests from allowed origins
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+	// AllowedMethods defaults to all common methods
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// AllowedHeaders are the request headers browsers may send. Empty allows the headers the browser asks for.
+	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
+	// MaxAge is how long browsers may cache the answer to a preflight request
+	MaxAge util.Duration `json:"maxAge,omitempty"`
+
+	// AllowPermissive lets workspace users opt ports into permissive CORS, i.e. any origin with credentials.
+	// Users opt in when exposing the port, or by having the dev server respond with the X-Gitpod-CORS: permissive header.
+	AllowPermissive bool `json:"allowPermissive,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *PortCORSConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.AllowedOrigins, validation.Each(validation.By(isValidRegexp))),
+		validation.Field(&c.AllowedMethods, validation.Each(validation.Required)),
+		validation.Field(&c.MaxAge, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid port CORS config: %w", err)
+	}
+	return nil
+}
+
+func isValidRegexp(value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return xerrors.Errorf("value is not a string")
+	}
+	_, err := regexp.Compile(s)
+	return err
+}
+
+// portCORS answers cross-origin requests to exposed ports
+type portCORS struct {
+	Config *PortCORSConfig
+	Info   WorkspaceInfoProvider
+
+	origins []*regexp.Regexp
+}
+
+// newPortCORS produces the CORS handling for exposed ports. If cfg is nil, we leave CORS to the dev servers.
+func newPortCORS(cfg *PortCORSConfig, info WorkspaceInfoProvider) (*portCORS, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	res := &portCORS{Config: cfg, Info: info}
+	for _, o := range cfg.AllowedOrigins {
+		r, err := regexp.Compile("^(?:" + o + ")$")
+		if err != nil {
+			return nil, xerrors.Errorf("invalid allowed origin %s: %w", o, err)
+		}
+		res.origins = append(res.origins, r)
+	}
+	return res, nil
+}
+
+// policy determines how we answer a cross-origin request
+func (c *portCORS) policy(req *http.Request) (allowed, permissive bool) {
+	if c.Config.AllowPermissive && c.isPermissivePort(req) {
+		return true, true
+	}
+
+	origin := req.Header.Get("Origin")
+	for _, r := range c.origins {
+		if r.MatchString(origin) {
+			return true, false
+		}
+	}
+	return false, false
+}
+
+func (c *portCORS) isPermissivePort(req *http.Request) bool {
+	if c.Info == nil {
+		return false
+	}
+	coords := getWorkspaceCoords(req)
+	info := c.Info.WorkspaceInfo(req.Context(), coords.ID)
+	if info == nil {
+		return false
+	}
+	for _, p := range info.Ports {
+		if strconv.Itoa(int(p.Port)) == coords.Port {
+			return p.PermissiveCors
+		}
+	}
+	return false
+}
+
+// Handler answers preflight requests from allowed origins. It must run before authentication
+// because browsers never send credentials along with preflight requests.
+func (c *portCORS) Handler(h http.Handler) http.Handler {
+	if c == nil {
+		return h
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !isPreflightRequest(req) {
+			h.ServeHTTP(resp, req)
+			return
+		}
+		allowed, permissive := c.policy(req)
+		if !allowed {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		c.setHeaders(resp.Header(), req, permissive)
+		c.setPreflightHeaders(resp.Header(), req, permissive)
+		resp.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// withPortCORS sets the CORS headers on responses to cross-origin requests from allowed origins,
+// and on responses whose dev server opted into permissive CORS.
+func withPortCORS(c *portCORS) proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		if c == nil {
+			return
+		}
+		cfg.appendResponseHandler(func(resp *http.Response, req *http.Request) error {
+			optIn := strings.EqualFold(resp.Header.Get(portCORSOptInHeader), portCORSPermissive)
+			resp.Header.Del(portCORSOptInHeader)
+			if req.Header.Get("Origin") == "" {
+				return nil
+			}
+
+			allowed, permissive := c.policy(req)
+			if !allowed && optIn && c.Config.AllowPermissive {
+				allowed, permissive = true, true
+			}
+			if !allowed {
+				return nil
+			}
+
+			c.setHeaders(resp.Header, req, permissive)
+			if isPreflightRequest(req) {
+				// the dev server answered the preflight request itself, but opted into permissive CORS
+				c.setPreflightHeaders(resp.Header, req, permissive)
+			}
+			return nil
+		})
+	}
+}
+
+func (c *portCORS) setHeaders(h http.Header, req *http.Request, permissive bool) {
+	// we own the CORS headers of allowed origins - drop whatever the dev server sent
+	h.Del("Access-Control-Allow-Credentials")
+	h.Set("Access-Control-Allow-Origin", req.Header.Get("Origin"))
+	h.Add("Vary", "Origin")
+	if permissive || c.Config.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (c *portCORS) setPreflightHeaders(h http.Header, req *http.Request, permissive bool) {
+	methods := c.Config.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultPortCORSMethods
+	}
+	if permissive {
+		methods = []string{req.Header.Get("Access-Control-Request-Method")}
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	headers := req.Header.Get("Access-Control-Request-Headers")
+	if !permissive && len(c.Config.AllowedHeaders) > 0 {
+		headers = strings.Join(c.Config.AllowedHeaders, ", ")
+	}
+	if headers != "" {
+		h.Set("Access-Control-Allow-Headers", headers)
+	}
+
+	if c.Config.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", fmt.Sprint(int(time.Duration(c.Config.MaxAge).Seconds())))
+	}
+}
+
+func isPreflightRequest(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Origin") != "" && req.Header.Get("Access-Control-Request-Method") != ""
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestPortCORSPreflight(t *testing.T) {
+	permissiveWorkspace := workspaces[0]
+	permissiveWorkspace.WorkspaceID = "permissive-ws"
+	permissiveWorkspace.Ports = []PortInfo{{PortSpec: workspaces[0].Ports[0].PortSpec}}
+	permissiveWorkspace.Ports[0].PermissiveCors = true
+	infos := &fixedInfoProvider{Infos: map[string]*WorkspaceInfo{
+		workspaces[0].WorkspaceID: &workspaces[0],
+		"permissive-ws":           &permissiveWorkspace,
+	}}
+	cfg := &PortCORSConfig{
+		AllowedOrigins:  []string{`https://.*\.example\.com`},
+		AllowedHeaders:  []string{"Content-Type"},
+		MaxAge:          util.Duration(10 * time.Minute),
+		AllowPermissive: true,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name           string
+		WorkspaceID    string
+		Origin         string
+		ExpectedStatus int
+		ExpectedHeader http.Header
+	}{
+		{
+			Name:           "allowed origin",
+			WorkspaceID:    workspaces[0].WorkspaceID,
+			Origin:         "https://app.example.com",
+			ExpectedStatus: http.StatusNoContent,
+			ExpectedHeader: http.Header{
+				"Access-Control-Allow-Headers": {"Content-Type"},
+				"Access-Control-Allow-Methods": {"GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS"},
+				"Access-Control-Allow-Origin":  {"https://app.example.com"},
+				"Access-Control-Max-Age":       {"600"},
+				"Vary":                         {"Origin"},
+			},
+		},
+		{
+			Name:           "origin must match fully",
+			WorkspaceID:    workspaces[0].WorkspaceID,
+			Origin:         "https://app.example.com.evil.com",
+			ExpectedStatus: http.StatusTeapot,
+			ExpectedHeader: http.Header{},
+		},
+		{
+			Name:           "permissive port",
+			WorkspaceID:    "permissive-ws",
+			Origin:         "https://evil.com",
+			ExpectedStatus: http.StatusNoContent,
+			ExpectedHeader: http.Header{
+				"Access-Control-Allow-Credentials": {"true"},
+				"Access-Control-Allow-Headers":     {"X-Custom"},
+				"Access-Control-Allow-Methods":     {"PUT"},
+				"Access-Control-Allow-Origin":      {"https://evil.com"},
+				"Access-Control-Max-Age":           {"600"},
+				"Vary":                             {"Origin"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cors, err := newPortCORS(cfg, infos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler := cors.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			req := httptest.NewRequest("OPTIONS", "http://localhost/", nil)
+			req = mux.SetURLVars(req, map[string]string{
+				workspaceIDIdentifier:   test.WorkspaceID,
+				workspacePortIdentifier: "28080",
+			})
+			req.Header.Set("Origin", test.Origin)
+			req.Header.Set("Access-Control-Request-Method", "PUT")
+			req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.ExpectedStatus {
+				t.Errorf("unexpected status: %d, expected %d", rec.Code, test.ExpectedStatus)
+			}
+			if diff := cmp.Diff(test.ExpectedHeader, rec.Header()); diff != "" {
+				t.Errorf("unexpected response headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPortCORSResponse(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Config         *PortCORSConfig
+		Origin         string
+		Upstream       http.Header
+		ExpectedHeader http.Header
+	}{
+		{
+			Name:           "no CORS config",
+			Origin:         "https://app.example.com",
+			Upstream:       http.Header{"X-Gitpod-Cors": {"permissive"}},
+			ExpectedHeader: http.Header{"X-Gitpod-Cors": {"permissive"}},
+		},
+		{
+			Name:   "allowed origin overrides upstream",
+			Config: &PortCORSConfig{AllowedOrigins: []string{`https://app\.example\.com`}, AllowCredentials: true},
+			Origin: "https://app.example.com",
+			Upstream: http.Header{
+				"Access-Control-Allow-Origin":      {"*"},
+				"Access-Control-Allow-Credentials": {"false"},
+			},
+			ExpectedHeader: http.Header{
+				"Access-Control-Allow-Credentials": {"true"},
+				"Access-Control-Allow-Origin":      {"https://app.example.com"},
+				"Vary":                             {"Origin"},
+			},
+		},
+		{
+			Name:           "other origins are left to the upstream",
+			Config:         &PortCORSConfig{AllowedOrigins: []string{`https://app\.example\.com`}},
+			Origin:         "https://other.com",
+			Upstream:       http.Header{"Access-Control-Allow-Origin": {"*"}},
+			ExpectedHeader: http.Header{"Access-Control-Allow-Origin": {"*"}},
+		},
+		{
+			Name:     "upstream opts in",
+			Config:   &PortCORSConfig{AllowPermissive: true},
+			Origin:   "https://other.com",
+			Upstream: http.Header{"X-Gitpod-Cors": {"permissive"}},
+			ExpectedHeader: http.Header{
+				"Access-Control-Allow-Credentials": {"true"},
+				"Access-Control-Allow-Origin":      {"https://other.com"},
+				"Vary":                             {"Origin"},
+			},
+		},
+		{
+			Name:           "upstream opt-in is disabled",
+			Config:         &PortCORSConfig{},
+			Origin:         "https://other.com",
+			Upstream:       http.Header{"X-Gitpod-Cors": {"permissive"}},
+			ExpectedHeader: http.Header{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cors, err := newPortCORS(test.Config, &fixedInfoProvider{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cfg proxyPassConfig
+			withPortCORS(cors)(&cfg)
+
+			req := httptest.NewRequest("GET", "http://localhost/", nil)
+			req.Header.Set("Origin", test.Origin)
+			resp := &http.Response{Header: test.Upstream}
+			for _, h := range cfg.ResponseHandler {
+				err := h(resp, req)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if diff := cmp.Diff(test.ExpectedHeader, resp.Header); diff != "" {
+				t.Errorf("unexpected response headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
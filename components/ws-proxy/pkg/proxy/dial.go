@@ -0,0 +1,313 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// ClientIdentityConfig modes
+const (
+	// ClientIdentityProxyProtocol sends a PROXY protocol v1 header on every connection to a workspace port
+	ClientIdentityProxyProtocol = "proxy-protocol"
+	// ClientIdentitySignedHeader adds the client IP and a signature to every request to a workspace port
+	ClientIdentitySignedHeader = "signed-header"
+)
+
+const (
+	// clientIPHeader carries the client IP in signed-header mode
+	clientIPHeader = "X-Gitpod-Client-IP"
+	// clientIPSignatureHeader carries t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<client IP>">
+	clientIPSignatureHeader = "X-Gitpod-Client-IP-Signature"
+)
+
+// UpstreamDialConfig configures how we connect to upstreams
+type UpstreamDialConfig struct {
+	// PreferIPv6 tries the IPv6 addresses of dual-stack upstreams first
+	PreferIPv6 bool `json:"preferIPv6,omitempty"`
+	// SourceAddress is the local IP we connect to upstreams from. Defaults to whatever the kernel picks.
+	SourceAddress string `json:"sourceAddress,omitempty"`
+	// FallbackDelay is how long we wait for the preferred address family before we race the other one (happy eyeballs).
+	// Defaults to 300ms, a negative value disables the fallback race.
+	FallbackDelay util.Duration `json:"fallbackDelay,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *UpstreamDialConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.SourceAddress, validation.By(func(value interface{}) error {
+			if s := value.(string); s != "" && net.ParseIP(s) == nil {
+				return xerrors.Errorf("must be an IP address")
+			}
+			return nil
+		})),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid upstream dial config: %w", err)
+	}
+	return nil
+}
+
+// ClientIdentityConfig configures how workspace ports learn the IP of the client which sent a request
+type ClientIdentityConfig struct {
+	// Mode is either proxy-protocol or signed-header
+	Mode string `json:"mode"`
+	// SigningKeyFile contains the key we sign the client IP header with in signed-header mode
+	SigningKeyFile string `json:"signingKeyFile,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ClientIdentityConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Mode, validation.Required, validation.In(ClientIdentityProxyProtocol, ClientIdentitySignedHeader)),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid client identity config: %w", err)
+	}
+	if c.Mode == ClientIdentitySignedHeader && c.SigningKeyFile == "" {
+		return xerrors.Errorf("invalid client identity config: signed-header mode requires a signingKeyFile")
+	}
+	return nil
+}
+
+// newUpstreamDialer produces the dial function of an upstream transport. If client is not nil,
+// every connection starts with a PROXY protocol header naming client as source.
+func newUpstreamDialer(config *TransportConfig, client *net.TCPAddr) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		KeepAlive: 30 * time.Second,
+	}
+
+	var preferIPv6 bool
+	if dc := config.Dial; dc != nil {
+		if dc.SourceAddress != "" {
+			dialer.LocalAddr = &net.TCPAddr{IP: net.ParseIP(dc.SourceAddress)}
+		}
+		dialer.FallbackDelay = time.Duration(dc.FallbackDelay)
+		preferIPv6 = dc.PreferIPv6
+	}
+
+	base := dialer.DialContext
+	if preferIPv6 {
+		base = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialPreferIPv6(ctx, dialer, network, addr)
+		}
+	}
+	// routes can override the connect timeout, hence we apply it per dial rather than on the dialer
+	connectTimeout := time.Duration(config.ConnectTimeout) // default: 30s
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		timeout := connectTimeout
+		if d, ok := getConnectTimeout(ctx); ok {
+			timeout = d
+		}
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return base(ctx, network, addr)
+	}
+	if client == nil {
+		return dial
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = conn.Write(proxyProtocolHeader(client, conn.RemoteAddr()))
+		if err != nil {
+			conn.Close()
+			return nil, xerrors.Errorf("cannot send PROXY protocol header: %w", err)
+		}
+		return conn, nil
+	}
+}
+
+// dialPreferIPv6 connects to the IPv6 addresses of addr first and races the IPv4 addresses once the
+// fallback delay has passed. The Go dialer always prefers the family of the first address the resolver returns.
+func dialPreferIPv6(ctx context.Context, dialer *net.Dialer, network, addr string) (net.Conn, error) {
+	if network != "tcp" {
+		return dialer.DialContext(ctx, network, addr)
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil && net.ParseIP(host) != nil {
+		// there's nothing to prefer if we connect to an IP address
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	fallbackDelay := dialer.FallbackDelay
+	if fallbackDelay == 0 {
+		fallbackDelay = 300 * time.Millisecond
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type dialResult struct {
+		Conn net.Conn
+		Err  error
+	}
+	results := make(chan dialResult)
+	race := func(network string) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		select {
+		case results <- dialResult{Conn: conn, Err: err}:
+		case <-ctx.Done():
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}
+	go race("tcp6")
+
+	var (
+		pending         = 1
+		fallbackPending = true
+		fallbackTimer   = time.NewTimer(fallbackDelay)
+		fallback        = fallbackTimer.C
+		firstErr        error
+	)
+	defer fallbackTimer.Stop()
+	if fallbackDelay < 0 {
+		// no race, but we still fall back to IPv4 if IPv6 fails, e.g. for IPv4-only upstreams
+		fallback = nil
+	}
+	startFallback := func() {
+		fallbackPending = false
+		fallback = nil
+		pending++
+		go race("tcp4")
+	}
+	for {
+		select {
+		case <-fallback:
+			startFallback()
+		case res := <-results:
+			pending--
+			if res.Err == nil {
+				return res.Conn, nil
+			}
+			if firstErr == nil {
+				firstErr = res.Err
+			}
+			if fallbackPending {
+				startFallback()
+			}
+			if pending == 0 {
+				return nil, firstErr
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// proxyProtocolHeader produces a PROXY protocol v1 header
+func proxyProtocolHeader(src *net.TCPAddr, dst net.Addr) []byte {
+	dstTCP, ok := dst.(*net.TCPAddr)
+	if !ok {
+		return []byte("PROXY UNKNOWN\r\n")
+	}
+
+	proto, srcIP, dstIP := "TCP4", src.IP.String(), dstTCP.IP.String()
+	if src.IP.To4() == nil || dstTCP.IP.To4() == nil {
+		// both addresses must be of the same family - we map IPv4 to IPv6 if only one of them is IPv6
+		proto, srcIP, dstIP = "TCP6", formatIPv6(src.IP), formatIPv6(dstTCP.IP)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "PROXY %s %s %s %d %d\r\n", proto, srcIP, dstIP, src.Port, dstTCP.Port)
+	return buf.Bytes()
+}
+
+// formatIPv6 formats ip as IPv6 address - net.IP prints IPv4-mapped addresses in dotted form
+func formatIPv6(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return "::ffff:" + ip4.String()
+	}
+	return ip.String()
+}
+
+type clientAddrContextKey struct{}
+
+func getClientAddr(ctx context.Context) *net.TCPAddr {
+	addr, _ := ctx.Value(clientAddrContextKey{}).(*net.TCPAddr)
+	return addr
+}
+
+// clientIdentityHandler propagates the client IP of requests to workspace ports. If cfg is nil,
+// workspace ports see ws-proxy as client.
+func clientIdentityHandler(cfg *ClientIdentityConfig) (func(http.Handler) http.Handler, error) {
+	if cfg == nil {
+		return func(h http.Handler) http.Handler { return h }, nil
+	}
+
+	var key []byte
+	if cfg.Mode == ClientIdentitySignedHeader {
+		var err error
+		key, err = ioutil.ReadFile(cfg.SigningKeyFile)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read client identity signing key: %w", err)
+		}
+		key = bytes.TrimSpace(key)
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			// clients must never be able to claim an identity themselves
+			req.Header.Del(clientIPHeader)
+			req.Header.Del(clientIPSignatureHeader)
+
+			client, err := net.ResolveTCPAddr("tcp", req.RemoteAddr)
+			if err != nil {
+				getLog(req.Context()).WithError(err).WithField("remoteAddr", req.RemoteAddr).Debug("cannot determine client address")
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			switch cfg.Mode {
+			case ClientIdentityProxyProtocol:
+				req = req.WithContext(context.WithValue(req.Context(), clientAddrContextKey{}, client))
+			case ClientIdentitySignedHeader:
+				ip := client.IP.String()
+				req.Header.Set(clientIPHeader, ip)
+				req.Header.Set(clientIPSignatureHeader, signClientIP(key, ip, time.Now()))
+			}
+			h.ServeHTTP(resp, req)
+		})
+	}, nil
+}
+
+// signClientIP produces the value of the client IP signature header
+func signClientIP(key []byte, ip string, now time.Time) string {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(ts + "." + ip))
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
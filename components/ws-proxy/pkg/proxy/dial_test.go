@@ -0,0 +1,234 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestProxyProtocolHeader(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Src         *net.TCPAddr
+		Dst         net.Addr
+		Expectation string
+	}{
+		{
+			Name:        "IPv4",
+			Src:         &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 56324},
+			Dst:         &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+			Expectation: "PROXY TCP4 203.0.113.7 10.0.0.1 56324 8080\r\n",
+		},
+		{
+			Name:        "IPv6",
+			Src:         &net.TCPAddr{IP: net.ParseIP("2001:db8::7"), Port: 56324},
+			Dst:         &net.TCPAddr{IP: net.ParseIP("fd00::1"), Port: 8080},
+			Expectation: "PROXY TCP6 2001:db8::7 fd00::1 56324 8080\r\n",
+		},
+		{
+			Name:        "mixed families",
+			Src:         &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 56324},
+			Dst:         &net.TCPAddr{IP: net.ParseIP("fd00::1"), Port: 8080},
+			Expectation: "PROXY TCP6 ::ffff:203.0.113.7 fd00::1 56324 8080\r\n",
+		},
+		{
+			Name:        "unknown destination",
+			Src:         &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 56324},
+			Dst:         &net.UnixAddr{Name: "/tmp/sock"},
+			Expectation: "PROXY UNKNOWN\r\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := string(proxyProtocolHeader(test.Src, test.Dst))
+			if act != test.Expectation {
+				t.Errorf("unexpected header: %q, expected %q", act, test.Expectation)
+			}
+		})
+	}
+}
+
+func TestTransportPoolProxyProtocol(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	headers := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				line, _ := r.ReadString('\n')
+				headers <- line
+				for {
+					req, err := http.ReadRequest(r)
+					if err != nil {
+						return
+					}
+					req.Body.Close()
+					_, _ = conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"))
+				}
+			}()
+		}
+	}()
+
+	pool := NewTransportPool(&TransportConfig{ConnectTimeout: util.Duration(time.Second), IdleConnTimeout: util.Duration(time.Minute), MaxIdleConns: 10})
+	for _, client := range []string{"203.0.113.7:1234", "203.0.113.7:1235", "203.0.113.8:1234"} {
+		addr, _ := net.ResolveTCPAddr("tcp", client)
+		req, _ := http.NewRequestWithContext(context.WithValue(context.Background(), clientAddrContextKey{}, addr), "GET", "http://"+l.Addr().String(), nil)
+		resp, err := pool.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	// the second request reuses the connection of the first one - they come from the same client IP
+	expectations := []string{
+		"PROXY TCP4 203.0.113.7 127.0.0.1 1234 " + portOf(l.Addr()) + "\r\n",
+		"PROXY TCP4 203.0.113.8 127.0.0.1 1234 " + portOf(l.Addr()) + "\r\n",
+	}
+	for _, exp := range expectations {
+		select {
+		case act := <-headers:
+			if act != exp {
+				t.Errorf("unexpected PROXY protocol header: %q, expected %q", act, exp)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("upstream did not receive header %q", exp)
+		}
+	}
+	select {
+	case act := <-headers:
+		t.Errorf("unexpected connection: %q", act)
+	default:
+	}
+}
+
+func portOf(addr net.Addr) string {
+	_, port, _ := net.SplitHostPort(addr.String())
+	return port
+}
+
+func TestDialPreferIPv6(t *testing.T) {
+	// the upstream only listens on IPv4 - we must fall back irrespective of the fallback delay
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	for _, delay := range []time.Duration{0, -1} {
+		dial := newUpstreamDialer(&TransportConfig{
+			ConnectTimeout: util.Duration(time.Second),
+			Dial:           &UpstreamDialConfig{PreferIPv6: true, FallbackDelay: util.Duration(delay)},
+		}, nil)
+		conn, err := dial(context.Background(), "tcp", "localhost:"+portOf(l.Addr()))
+		if err != nil {
+			t.Errorf("fallback delay %v: %v", delay, err)
+			continue
+		}
+		conn.Close()
+	}
+}
+
+func TestClientIdentityHandler(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	err := ioutil.WriteFile(keyFile, []byte("secret\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("signed header", func(t *testing.T) {
+		mw, err := clientIdentityHandler(&ClientIdentityConfig{Mode: ClientIdentitySignedHeader, SigningKeyFile: keyFile})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fwd http.Header
+		mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			fwd = req.Header
+		})).ServeHTTP(httptest.NewRecorder(), spoofedClientRequest())
+
+		if ip := fwd.Get(clientIPHeader); ip != "203.0.113.7" {
+			t.Errorf("unexpected client IP: %q", ip)
+		}
+		if sig := fwd.Get(clientIPSignatureHeader); sig == "spoofed" || sig == "" {
+			t.Errorf("unexpected signature: %q", sig)
+		}
+	})
+
+	t.Run("proxy protocol", func(t *testing.T) {
+		mw, err := clientIdentityHandler(&ClientIdentityConfig{Mode: ClientIdentityProxyProtocol})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var (
+			fwd    http.Header
+			client *net.TCPAddr
+		)
+		mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			fwd = req.Header
+			client = getClientAddr(req.Context())
+		})).ServeHTTP(httptest.NewRecorder(), spoofedClientRequest())
+
+		if client == nil || client.String() != "203.0.113.7:1234" {
+			t.Errorf("unexpected client address: %v", client)
+		}
+		if len(fwd) != 0 {
+			t.Errorf("client identity headers were not removed: %v", fwd)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := clientIdentityHandler(&ClientIdentityConfig{Mode: ClientIdentitySignedHeader, SigningKeyFile: filepath.Join(os.TempDir(), "does-not-exist")})
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func spoofedClientRequest() *http.Request {
+	req := httptest.NewRequest("GET", "http://localhost/", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set(clientIPHeader, "10.0.0.1")
+	req.Header.Set(clientIPSignatureHeader, "spoofed")
+	return req
+}
+
+func TestSignClientIP(t *testing.T) {
+	act := signClientIP([]byte("secret"), "203.0.113.7", time.Unix(1600000000, 0))
+	// echo -n "1600000000.203.0.113.7" | openssl dgst -sha256 -hmac secret
+	exp := "t=1600000000,v1=88a82a944047170a9bb29be3aac45d5d96dde49db7ac41f048a533f65ac55f5e"
+	if act != exp {
+		t.Errorf("unexpected signature: %q, expected %q", act, exp)
+	}
+}
@@ -0,0 +1,673 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	discovery "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultDiscoveryHealthInterval   = 5 * time.Second
+	defaultDiscoveryHealthTimeout    = 2 * time.Second
+	defaultDiscoveryUnhealthyAfter   = 2
+	defaultDiscoveryEjectionTime     = 10 * time.Second
+	defaultDiscoveryFailoverAttempts = 3
+
+	// discoveryResync is how often the informer replays all EndpointSlices, in case we missed an update
+	discoveryResync = 10 * time.Minute
+)
+
+// UpstreamDiscoveryConfig configures the discovery of internal upstreams, e.g. blobserve, using their EndpointSlices.
+// Requests to a discovered host are balanced across the ready endpoints of its service, rather than sent to the service IP.
+type UpstreamDiscoveryConfig struct {
+	// Namespace is the namespace of the services
+	Namespace string `json:"namespace"`
+	// Kubeconfig is the kubeconfig we use to talk to Kubernetes. Defaults to the in-cluster configuration.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Services are the upstreams we discover
+	Services []DiscoveredServiceConfig `json:"services"`
+	// HealthCheck probes the endpoints we discovered, so that we stop using those which fail before Kubernetes notices
+	HealthCheck *EndpointHealthCheckConfig `json:"healthCheck,omitempty"`
+	// EjectionTime is how long we skip an endpoint which refused a connection. Defaults to 10 seconds.
+	EjectionTime util.Duration `json:"ejectionTime,omitempty"`
+	// FailoverAttempts limits the endpoints we try for an idempotent request whose connection fails. Defaults to 3.
+	FailoverAttempts int `json:"failoverAttempts,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *UpstreamDiscoveryConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Namespace, validation.Required),
+		validation.Field(&c.Services, validation.Required),
+		validation.Field(&c.HealthCheck),
+		validation.Field(&c.EjectionTime, validation.Min(util.Duration(0))),
+		validation.Field(&c.FailoverAttempts, validation.Min(0)),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid upstream discovery config: %w", err)
+	}
+
+	hosts := make(map[string]struct{}, len(c.Services))
+	for _, s := range c.Services {
+		if _, exists := hosts[s.Host]; exists {
+			return xerrors.Errorf("invalid upstream discovery config: host %s is discovered twice", s.Host)
+		}
+		hosts[s.Host] = struct{}{}
+	}
+	return nil
+}
+
+// DiscoveredServiceConfig maps the host of an upstream to the service whose endpoints serve it
+type DiscoveredServiceConfig struct {
+	// Host is the host and port of the upstream as it appears in upstream URLs, e.g. blobserve.default.svc.cluster.local:4000
+	Host string `json:"host"`
+	// Service is the name of the Kubernetes service
+	Service string `json:"service"`
+	// Port is the name of the service port. Defaults to the port the EndpointSlices list if there's only one.
+	Port string `json:"port,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c DiscoveredServiceConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Host, validation.Required, validation.By(func(value interface{}) error {
+			_, _, err := net.SplitHostPort(value.(string))
+			if err != nil {
+				return xerrors.Errorf("must be host:port")
+			}
+			return nil
+		})),
+		validation.Field(&c.Service, validation.Required),
+	)
+}
+
+// EndpointHealthCheckConfig configures the active health checks of discovered endpoints
+type EndpointHealthCheckConfig struct {
+	// Path is requested using HTTP GET and must answer with a 2xx status. If empty, we only open a connection.
+	Path string `json:"path,omitempty"`
+	// Interval is the time between two probes of an endpoint. Defaults to 5 seconds.
+	Interval util.Duration `json:"interval,omitempty"`
+	// Timeout limits a single probe. Defaults to 2 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+	// UnhealthyThreshold is the number of consecutive failed probes after which we stop using an endpoint. Defaults to 2.
+	UnhealthyThreshold int `json:"unhealthyThreshold,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *EndpointHealthCheckConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Path, validation.By(func(value interface{}) error {
+			if s := value.(string); s != "" && !strings.HasPrefix(s, "/") {
+				return xerrors.Errorf("must start with /")
+			}
+			return nil
+		})),
+		validation.Field(&c.Interval, validation.Min(util.Duration(0))),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+		validation.Field(&c.UnhealthyThreshold, validation.Min(0)),
+	)
+}
+
+// UpstreamDiscovery watches the EndpointSlices of internal upstreams and balances requests across their ready endpoints.
+// Unlike the service IP, we stop using an endpoint as soon as it's no longer ready, e.g. while its node drains, and
+// try another endpoint if an idempotent request cannot connect.
+type UpstreamDiscovery struct {
+	Config UpstreamDiscoveryConfig
+
+	client   kubernetes.Interface
+	services map[string]*discoveredService
+
+	probe func(ctx context.Context, addr string) error
+	now   func() time.Time
+
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	metrics *upstreamDiscoveryMetrics
+}
+
+// NewUpstreamDiscovery creates a new upstream discovery. Call Start to begin watching the services.
+func NewUpstreamDiscovery(config UpstreamDiscoveryConfig) (*UpstreamDiscovery, error) {
+	client, err := newKubernetesClient(config.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return newUpstreamDiscovery(config, client), nil
+}
+
+func newUpstreamDiscovery(config UpstreamDiscoveryConfig, client kubernetes.Interface) *UpstreamDiscovery {
+	d := &UpstreamDiscovery{
+		Config:   config,
+		client:   client,
+		services: make(map[string]*discoveredService, len(config.Services)),
+		now:      time.Now,
+		stop:     make(chan struct{}),
+		metrics:  newUpstreamDiscoveryMetrics(),
+	}
+	d.probe = d.defaultProbe
+	for _, s := range config.Services {
+		d.services[s.Host] = &discoveredService{
+			Config:    s,
+			slices:    make(map[string][]string),
+			endpoints: make(map[string]*upstreamEndpoint),
+		}
+	}
+	return d
+}
+
+func newKubernetesClient(kubeconfig string) (res kubernetes.Interface, err error) {
+	defer func() {
+		if err != nil {
+			err = xerrors.Errorf("cannot create clientset: %w", err)
+		}
+	}()
+
+	var cfg *rest.Config
+	if kubeconfig != "" {
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		cfg, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
+
+// Start watches the EndpointSlices of the services and starts the health checks.
+// Until we know the endpoints of a service, its requests go to the service as configured.
+func (d *UpstreamDiscovery) Start() {
+	names := make([]string, 0, len(d.services))
+	for _, s := range d.services {
+		names = append(names, s.Config.Service)
+	}
+	sort.Strings(names)
+
+	factory := informers.NewSharedInformerFactoryWithOptions(d.client, discoveryResync,
+		informers.WithNamespace(d.Config.Namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = fmt.Sprintf("%s in (%s)", discovery.LabelServiceName, strings.Join(names, ","))
+		}),
+	)
+	informer := factory.Discovery().V1beta1().EndpointSlices().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if slice, ok := obj.(*discovery.EndpointSlice); ok {
+				d.updateSlice(slice)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if slice, ok := newObj.(*discovery.EndpointSlice); ok {
+				d.updateSlice(slice)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if slice, ok := obj.(*discovery.EndpointSlice); ok {
+				d.deleteSlice(slice)
+			}
+		},
+	})
+	factory.Start(d.stop)
+
+	if d.Config.HealthCheck != nil {
+		d.wg.Add(1)
+		go d.runHealthChecks()
+	}
+}
+
+// Close stops watching the services and the health checks
+func (d *UpstreamDiscovery) Close() {
+	close(d.stop)
+	d.wg.Wait()
+}
+
+// Transport produces a round tripper which sends requests to discovered hosts to one of their endpoints
+// using next, and all other requests to next unchanged.
+func (d *UpstreamDiscovery) Transport(next http.RoundTripper) http.RoundTripper {
+	return &discoveryTransport{discovery: d, next: next}
+}
+
+func (d *UpstreamDiscovery) service(slice *discovery.EndpointSlice) *discoveredService {
+	name := slice.Labels[discovery.LabelServiceName]
+	for _, s := range d.services {
+		if s.Config.Service == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (d *UpstreamDiscovery) updateSlice(slice *discovery.EndpointSlice) {
+	svc := d.service(slice)
+	if svc == nil {
+		return
+	}
+
+	port, ok := slicePort(slice, svc.Config.Port)
+	if !ok {
+		svc.setSlice(slice.Name, nil)
+		d.metrics.OnEndpointsChange(svc)
+		return
+	}
+	var addrs []string
+	for _, ep := range slice.Endpoints {
+		// endpoints without conditions are ready
+		if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+			continue
+		}
+		for _, addr := range ep.Addresses {
+			addrs = append(addrs, net.JoinHostPort(addr, strconv.Itoa(int(port))))
+		}
+	}
+	svc.setSlice(slice.Name, addrs)
+	d.metrics.OnEndpointsChange(svc)
+}
+
+func (d *UpstreamDiscovery) deleteSlice(slice *discovery.EndpointSlice) {
+	svc := d.service(slice)
+	if svc == nil {
+		return
+	}
+	svc.setSlice(slice.Name, nil)
+	d.metrics.OnEndpointsChange(svc)
+}
+
+// slicePort finds the port of an EndpointSlice which serves the named service port
+func slicePort(slice *discovery.EndpointSlice, name string) (int32, bool) {
+	for _, p := range slice.Ports {
+		if p.Port == nil {
+			continue
+		}
+		if name == "" && len(slice.Ports) == 1 {
+			return *p.Port, true
+		}
+		if p.Name != nil && *p.Name == name {
+			return *p.Port, true
+		}
+	}
+	return 0, false
+}
+
+func (d *UpstreamDiscovery) runHealthChecks() {
+	defer d.wg.Done()
+
+	interval := time.Duration(d.Config.HealthCheck.Interval)
+	if interval == 0 {
+		interval = defaultDiscoveryHealthInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			d.checkHealth()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// checkHealth probes all endpoints once
+func (d *UpstreamDiscovery) checkHealth() {
+	timeout := time.Duration(d.Config.HealthCheck.Timeout)
+	if timeout == 0 {
+		timeout = defaultDiscoveryHealthTimeout
+	}
+	threshold := d.Config.HealthCheck.UnhealthyThreshold
+	if threshold == 0 {
+		threshold = defaultDiscoveryUnhealthyAfter
+	}
+
+	var wg sync.WaitGroup
+	for _, svc := range d.services {
+		for _, ep := range svc.Endpoints() {
+			wg.Add(1)
+			go func(svc *discoveredService, ep *upstreamEndpoint) {
+				defer wg.Done()
+
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				err := d.probe(ctx, ep.Addr)
+				if ep.observeProbe(err == nil, threshold) {
+					log.WithError(err).WithField("service", svc.Config.Service).WithField("endpoint", ep.Addr).WithField("healthy", err == nil).Info("upstream endpoint health changed")
+				}
+			}(svc, ep)
+		}
+	}
+	wg.Wait()
+
+	for _, svc := range d.services {
+		d.metrics.OnEndpointsChange(svc)
+	}
+}
+
+func (d *UpstreamDiscovery) defaultProbe(ctx context.Context, addr string) error {
+	if d.Config.HealthCheck.Path == "" {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+d.Config.HealthCheck.Path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return xerrors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *UpstreamDiscovery) ejectionTime() time.Duration {
+	if d.Config.EjectionTime > 0 {
+		return time.Duration(d.Config.EjectionTime)
+	}
+	return defaultDiscoveryEjectionTime
+}
+
+func (d *UpstreamDiscovery) failoverAttempts() int {
+	if d.Config.FailoverAttempts > 0 {
+		return d.Config.FailoverAttempts
+	}
+	return defaultDiscoveryFailoverAttempts
+}
+
+// discoveredService is the set of ready endpoints of a service
+type discoveredService struct {
+	Config DiscoveredServiceConfig
+
+	mu sync.Mutex
+	// slices are the endpoint addresses per EndpointSlice
+	slices map[string][]string
+	// endpoints keeps the health of an endpoint while it moves between slices
+	endpoints map[string]*upstreamEndpoint
+	ordered   []*upstreamEndpoint
+	next      int
+}
+
+func (s *discoveredService) setSlice(name string, addrs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(addrs) == 0 {
+		delete(s.slices, name)
+	} else {
+		s.slices[name] = addrs
+	}
+
+	endpoints := make(map[string]*upstreamEndpoint, len(s.endpoints))
+	for _, addrs := range s.slices {
+		for _, addr := range addrs {
+			ep, ok := s.endpoints[addr]
+			if !ok {
+				ep = &upstreamEndpoint{Addr: addr}
+			}
+			endpoints[addr] = ep
+		}
+	}
+	ordered := make([]*upstreamEndpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		ordered = append(ordered, ep)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Addr < ordered[j].Addr })
+
+	s.endpoints = endpoints
+	s.ordered = ordered
+}
+
+// Endpoints returns all ready endpoints, irrespective of their health
+func (s *discoveredService) Endpoints() []*upstreamEndpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*upstreamEndpoint(nil), s.ordered...)
+}
+
+// Pick chooses the next endpoint round-robin, skipping those in exclude and those which are unhealthy or ejected.
+// If all endpoints are unhealthy or ejected we'd rather try one of them than fail the request. Returns nil
+// if there's no endpoint left to try.
+func (s *discoveredService) Pick(now time.Time, exclude map[*upstreamEndpoint]struct{}) *upstreamEndpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var fallback *upstreamEndpoint
+	for i := 0; i < len(s.ordered); i++ {
+		ep := s.ordered[(s.next+i)%len(s.ordered)]
+		if _, skip := exclude[ep]; skip {
+			continue
+		}
+		if !ep.Usable(now) {
+			if fallback == nil {
+				fallback = ep
+			}
+			continue
+		}
+		s.next = (s.next + i + 1) % len(s.ordered)
+		return ep
+	}
+	return fallback
+}
+
+// Known returns true once we know at least one endpoint of the service
+func (s *discoveredService) Known() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.ordered) > 0
+}
+
+// upstreamEndpoint is a single ready endpoint of a discovered service
+type upstreamEndpoint struct {
+	Addr string
+
+	mu           sync.Mutex
+	unhealthy    bool
+	probeFails   int
+	ejectedUntil time.Time
+}
+
+// Usable returns false while the endpoint fails its health checks or is ejected after a failed connection
+func (e *upstreamEndpoint) Usable(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !e.unhealthy && !now.Before(e.ejectedUntil)
+}
+
+// Eject skips the endpoint until the given time
+func (e *upstreamEndpoint) Eject(until time.Time) {
+	e.mu.Lock()
+	e.ejectedUntil = until
+	e.mu.Unlock()
+}
+
+// observeProbe records a health check result and returns true if the health of the endpoint changed
+func (e *upstreamEndpoint) observeProbe(ok bool, threshold int) (changed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if ok {
+		e.probeFails = 0
+		changed = e.unhealthy
+		e.unhealthy = false
+		return changed
+	}
+	e.probeFails++
+	if e.probeFails >= threshold && !e.unhealthy {
+		e.unhealthy = true
+		return true
+	}
+	return false
+}
+
+// discoveryTransport sends requests to discovered hosts to one of their endpoints
+type discoveryTransport struct {
+	discovery *UpstreamDiscovery
+	next      http.RoundTripper
+}
+
+func (t *discoveryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	svc, ok := t.discovery.services[req.URL.Host]
+	if !ok || !svc.Known() {
+		return t.next.RoundTrip(req)
+	}
+
+	var (
+		d        = t.discovery
+		failover = !isWebsocketRequest(req) && isIdempotentRequest(req)
+		tried    = make(map[*upstreamEndpoint]struct{})
+		lastErr  error
+	)
+	for attempt := 0; attempt < d.failoverAttempts(); attempt++ {
+		ep := svc.Pick(d.now(), tried)
+		if ep == nil {
+			break
+		}
+		tried[ep] = struct{}{}
+
+		outreq := req.Clone(req.Context())
+		if outreq.Host == "" {
+			// the upstream still sees the host it's addressed by
+			outreq.Host = req.URL.Host
+		}
+		outreq.URL.Host = ep.Addr
+
+		resp, err := t.next.RoundTrip(outreq)
+		if err == nil {
+			return resp, nil
+		}
+		if !isEndpointFailure(err) {
+			return nil, err
+		}
+
+		ep.Eject(d.now().Add(d.ejectionTime()))
+		d.metrics.OnEjection(svc)
+		getLog(req.Context()).WithError(err).WithField("endpoint", ep.Addr).WithField("service", svc.Config.Service).Debug("upstream endpoint failed - ejecting it")
+		lastErr = err
+		if !failover {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// isEndpointFailure returns true if we could not connect to an endpoint, or it reset the connection
+func isEndpointFailure(err error) bool {
+	var opErr *net.OpError
+	if xerrors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return xerrors.Is(err, syscall.ECONNREFUSED) || xerrors.Is(err, syscall.ECONNRESET)
+}
+
+// RegisterMetrics registers the endpoint and ejection metrics
+func (d *UpstreamDiscovery) RegisterMetrics(reg prometheus.Registerer) error {
+	return d.metrics.Register(reg)
+}
+
+type upstreamDiscoveryMetrics struct {
+	endpoints *prometheus.GaugeVec
+	ejections *prometheus.CounterVec
+}
+
+func newUpstreamDiscoveryMetrics() *upstreamDiscoveryMetrics {
+	return &upstreamDiscoveryMetrics{
+		endpoints: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upstream_discovery_endpoints",
+			Help: "number of ready endpoints of discovered upstream services, by whether they pass their health checks",
+		}, []string{"service", "healthy"}),
+		ejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "upstream_discovery_ejections_total",
+			Help: "number of times we skipped an endpoint of a discovered service because we could not connect to it",
+		}, []string{"service"}),
+	}
+}
+
+// Register registers all upstream discovery metrics
+func (m *upstreamDiscoveryMetrics) Register(reg prometheus.Registerer) error {
+	if m == nil {
+		return nil
+	}
+
+	collectors := []prometheus.Collector{
+		m.endpoints,
+		m.ejections,
+	}
+	for _, c := range collectors {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *upstreamDiscoveryMetrics) OnEndpointsChange(svc *discoveredService) {
+	if m == nil {
+		return
+	}
+
+	var healthy, unhealthy int
+	for _, ep := range svc.Endpoints() {
+		ep.mu.Lock()
+		if ep.unhealthy {
+			unhealthy++
+		} else {
+			healthy++
+		}
+		ep.mu.Unlock()
+	}
+	m.endpoints.WithLabelValues(svc.Config.Service, "true").Set(float64(healthy))
+	m.endpoints.WithLabelValues(svc.Config.Service, "false").Set(float64(unhealthy))
+}
+
+func (m *upstreamDiscoveryMetrics) OnEjection(svc *discoveredService) {
+	if m == nil {
+		return
+	}
+	m.ejections.WithLabelValues(svc.Config.Service).Inc()
+}
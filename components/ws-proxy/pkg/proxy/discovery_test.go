@@ -0,0 +1,275 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	discovery "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testBlobserveHost = "blobserve.default.svc.cluster.local:4000"
+
+func newTestEndpointSlice(name, service string, port int32, ready map[string]bool) *discovery.EndpointSlice {
+	portName := "http"
+	slice := &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{discovery.LabelServiceName: service},
+		},
+		AddressType: discovery.AddressTypeIPv4,
+		Ports:       []discovery.EndpointPort{{Name: &portName, Port: &port}},
+	}
+	for addr, r := range ready {
+		r := r
+		slice.Endpoints = append(slice.Endpoints, discovery.Endpoint{
+			Addresses:  []string{addr},
+			Conditions: discovery.EndpointConditions{Ready: &r},
+		})
+	}
+	return slice
+}
+
+func newTestUpstreamDiscovery() *UpstreamDiscovery {
+	return newUpstreamDiscovery(UpstreamDiscoveryConfig{
+		Namespace: "default",
+		Services:  []DiscoveredServiceConfig{{Host: testBlobserveHost, Service: "blobserve", Port: "http"}},
+	}, fake.NewSimpleClientset())
+}
+
+func endpointAddrs(svc *discoveredService) []string {
+	var res []string
+	for _, ep := range svc.Endpoints() {
+		res = append(res, ep.Addr)
+	}
+	return res
+}
+
+func TestUpstreamDiscoverySlices(t *testing.T) {
+	d := newTestUpstreamDiscovery()
+	svc := d.services[testBlobserveHost]
+
+	d.updateSlice(newTestEndpointSlice("blobserve-a", "blobserve", 4000, map[string]bool{"10.0.0.1": true, "10.0.0.2": false}))
+	d.updateSlice(newTestEndpointSlice("blobserve-b", "blobserve", 4000, map[string]bool{"10.0.1.1": true}))
+	d.updateSlice(newTestEndpointSlice("server-a", "server", 3000, map[string]bool{"10.0.2.1": true}))
+	if diff := cmp.Diff([]string{"10.0.0.1:4000", "10.0.1.1:4000"}, endpointAddrs(svc)); diff != "" {
+		t.Errorf("unexpected endpoints (-want +got):\n%s", diff)
+	}
+
+	// the health of an endpoint survives slice updates
+	svc.Endpoints()[1].Eject(time.Now().Add(time.Hour))
+	d.updateSlice(newTestEndpointSlice("blobserve-a", "blobserve", 4000, map[string]bool{"10.0.0.1": false, "10.0.0.2": true}))
+	if diff := cmp.Diff([]string{"10.0.0.2:4000", "10.0.1.1:4000"}, endpointAddrs(svc)); diff != "" {
+		t.Errorf("unexpected endpoints after the update (-want +got):\n%s", diff)
+	}
+	if svc.Endpoints()[1].Usable(time.Now()) {
+		t.Errorf("endpoint lost its ejection across a slice update")
+	}
+
+	other := "metrics"
+	slice := newTestEndpointSlice("blobserve-b", "blobserve", 9500, map[string]bool{"10.0.1.1": true})
+	slice.Ports[0].Name = &other
+	d.updateSlice(slice)
+	if diff := cmp.Diff([]string{"10.0.0.2:4000"}, endpointAddrs(svc)); diff != "" {
+		t.Errorf("endpoints of a slice without the service port remain (-want +got):\n%s", diff)
+	}
+
+	d.deleteSlice(newTestEndpointSlice("blobserve-a", "blobserve", 4000, nil))
+	if svc.Known() {
+		t.Errorf("service still has endpoints: %v", endpointAddrs(svc))
+	}
+}
+
+func TestDiscoveryTransport(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		hits = make(map[string]int)
+	)
+	newEndpoint := func(name string) (*httptest.Server, string, int32) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits[name]++
+			mu.Unlock()
+			fmt.Fprintf(w, "%s %s", name, r.Host)
+		}))
+		host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+		p, _ := strconv.Atoi(port)
+		return srv, host, int32(p)
+	}
+	srvA, hostA, portA := newEndpoint("a")
+	defer srvA.Close()
+	srvB, hostB, portB := newEndpoint("b")
+	defer srvB.Close()
+	// an endpoint which is gone, e.g. because its node drained before the EndpointSlice was updated
+	srvGone, hostGone, portGone := newEndpoint("gone")
+	srvGone.Close()
+
+	now := time.Now()
+	d := newTestUpstreamDiscovery()
+	d.now = func() time.Time { return now }
+	d.updateSlice(newTestEndpointSlice("a", "blobserve", portA, map[string]bool{hostA: true}))
+	d.updateSlice(newTestEndpointSlice("b", "blobserve", portB, map[string]bool{hostB: true}))
+	d.updateSlice(newTestEndpointSlice("gone", "blobserve", portGone, map[string]bool{hostGone: true}))
+
+	client := &http.Client{Transport: d.Transport(http.DefaultTransport)}
+	get := func(method string) (string, error) {
+		req, _ := http.NewRequest(method, "http://"+testBlobserveHost+"/image/index.html", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	for i := 0; i < 10; i++ {
+		body, err := get(http.MethodGet)
+		if err != nil {
+			t.Fatalf("request %d failed although there are healthy endpoints: %v", i, err)
+		}
+		if i == 0 && body[2:] != testBlobserveHost {
+			t.Errorf("upstream saw host %q, expected %q", body[2:], testBlobserveHost)
+		}
+	}
+	mu.Lock()
+	if hits["a"] == 0 || hits["b"] == 0 {
+		t.Errorf("requests were not balanced across the endpoints: %v", hits)
+	}
+	mu.Unlock()
+	var ejected []string
+	for _, ep := range d.services[testBlobserveHost].Endpoints() {
+		if !ep.Usable(now) {
+			ejected = append(ejected, ep.Addr)
+		}
+	}
+	if diff := cmp.Diff([]string{net.JoinHostPort(hostGone, strconv.Itoa(int(portGone)))}, ejected); diff != "" {
+		t.Errorf("unexpected ejected endpoints (-want +got):\n%s", diff)
+	}
+
+	// once all endpoints are gone, there's nothing to fail over to
+	srvA.Close()
+	srvB.Close()
+	_, err := get(http.MethodPost)
+	if err == nil {
+		t.Errorf("expected request to fail")
+	}
+
+	// requests to other hosts and to services without endpoints are sent as they are
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer other.Close()
+	resp, err := client.Get(other.URL)
+	if err != nil {
+		t.Fatalf("request to an undiscovered host failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestUpstreamDiscoveryHealthCheck(t *testing.T) {
+	d := newTestUpstreamDiscovery()
+	d.Config.HealthCheck = &EndpointHealthCheckConfig{UnhealthyThreshold: 2}
+	failing := map[string]bool{"10.0.0.2:4000": true}
+	d.probe = func(ctx context.Context, addr string) error {
+		if failing[addr] {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	}
+	d.updateSlice(newTestEndpointSlice("blobserve-a", "blobserve", 4000, map[string]bool{"10.0.0.1": true, "10.0.0.2": true}))
+	svc := d.services[testBlobserveHost]
+
+	pick := func() []string {
+		var res []string
+		for i := 0; i < 4; i++ {
+			res = append(res, svc.Pick(time.Now(), nil).Addr)
+		}
+		return res
+	}
+
+	d.checkHealth()
+	if diff := cmp.Diff([]string{"10.0.0.1:4000", "10.0.0.2:4000", "10.0.0.1:4000", "10.0.0.2:4000"}, pick()); diff != "" {
+		t.Errorf("a single failed probe made an endpoint unhealthy (-want +got):\n%s", diff)
+	}
+	d.checkHealth()
+	if diff := cmp.Diff([]string{"10.0.0.1:4000", "10.0.0.1:4000", "10.0.0.1:4000", "10.0.0.1:4000"}, pick()); diff != "" {
+		t.Errorf("unhealthy endpoint is still used (-want +got):\n%s", diff)
+	}
+
+	// we'd rather try an unhealthy endpoint than none
+	failing["10.0.0.1:4000"] = true
+	d.checkHealth()
+	d.checkHealth()
+	if ep := svc.Pick(time.Now(), nil); ep == nil {
+		t.Errorf("expected an unhealthy endpoint as last resort")
+	}
+
+	delete(failing, "10.0.0.2:4000")
+	d.checkHealth()
+	if diff := cmp.Diff([]string{"10.0.0.2:4000", "10.0.0.2:4000", "10.0.0.2:4000", "10.0.0.2:4000"}, pick()); diff != "" {
+		t.Errorf("recovered endpoint is not used (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpstreamDiscoveryInformer(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	d := newUpstreamDiscovery(UpstreamDiscoveryConfig{
+		Namespace: "default",
+		Services:  []DiscoveredServiceConfig{{Host: testBlobserveHost, Service: "blobserve"}},
+	}, client)
+	d.Start()
+	defer d.Close()
+
+	_, err := client.DiscoveryV1beta1().EndpointSlices("default").Create(context.Background(), newTestEndpointSlice("blobserve-a", "blobserve", 4000, map[string]bool{"10.0.0.1": true}), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := d.services[testBlobserveHost]
+	deadline := time.Now().Add(5 * time.Second)
+	for !svc.Known() {
+		if time.Now().After(deadline) {
+			t.Fatal("discovery did not learn about the EndpointSlice")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if diff := cmp.Diff([]string{"10.0.0.1:4000"}, endpointAddrs(svc)); diff != "" {
+		t.Errorf("unexpected endpoints (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpstreamDiscoveryConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config UpstreamDiscoveryConfig
+		Error  bool
+	}{
+		{Name: "valid", Config: UpstreamDiscoveryConfig{Namespace: "default", Services: []DiscoveredServiceConfig{{Host: testBlobserveHost, Service: "blobserve"}}}},
+		{Name: "no namespace", Config: UpstreamDiscoveryConfig{Services: []DiscoveredServiceConfig{{Host: testBlobserveHost, Service: "blobserve"}}}, Error: true},
+		{Name: "host without port", Config: UpstreamDiscoveryConfig{Namespace: "default", Services: []DiscoveredServiceConfig{{Host: "blobserve", Service: "blobserve"}}}, Error: true},
+		{Name: "duplicate host", Config: UpstreamDiscoveryConfig{Namespace: "default", Services: []DiscoveredServiceConfig{{Host: testBlobserveHost, Service: "a"}, {Host: testBlobserveHost, Service: "b"}}}, Error: true},
+		{Name: "relative health path", Config: UpstreamDiscoveryConfig{Namespace: "default", Services: []DiscoveredServiceConfig{{Host: testBlobserveHost, Service: "blobserve"}}, HealthCheck: &EndpointHealthCheckConfig{Path: "ready"}}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("error = %v, expected error: %v", err, test.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// ErrorPage identifies a class of errors ws-proxy renders a page for
+type ErrorPage string
+
+const (
+	// ErrorPageWorkspaceNotFound is served if we don't know the workspace a request is for
+	ErrorPageWorkspaceNotFound ErrorPage = "workspace-not-found"
+	// ErrorPageWorkspaceStarting is served while a workspace is starting
+	ErrorPageWorkspaceStarting ErrorPage = "workspace-starting"
+	// ErrorPagePortNotFound is served if a workspace port is not exposed or does not respond
+	ErrorPagePortNotFound ErrorPage = "port-not-found"
+	// ErrorPageUnauthorized is served if a request is not allowed to access a workspace
+	ErrorPageUnauthorized ErrorPage = "unauthorized"
+	// ErrorPageUpstreamTimeout is served if the workspace did not respond in time
+	ErrorPageUpstreamTimeout ErrorPage = "upstream-timeout"
+	// ErrorPageProxyNotReady is served if we cannot tell whether a workspace exists because we have not heard from ws-manager
+	ErrorPageProxyNotReady ErrorPage = "proxy-not-ready"
+	// ErrorPageWorkspaceRestarted is served if a request expects a workspace instance which is no longer running
+	ErrorPageWorkspaceRestarted ErrorPage = "workspace-restarted"
+
+	// builtinPageError is the template used for all error pages which have no template of their own
+	builtinPageError = "error.html"
+)
+
+var errorPages = []ErrorPage{
+	ErrorPageWorkspaceNotFound,
+	ErrorPageWorkspaceStarting,
+	ErrorPagePortNotFound,
+	ErrorPageUnauthorized,
+	ErrorPageUpstreamTimeout,
+	ErrorPageProxyNotReady,
+	ErrorPageWorkspaceRestarted,
+}
+
+var errorPageMessages = map[ErrorPage]string{
+	ErrorPageWorkspaceNotFound:  "workspace not found",
+	ErrorPageWorkspaceStarting:  "workspace is starting",
+	ErrorPagePortNotFound:       "port not found",
+	ErrorPageUnauthorized:       "not authorized to access this workspace",
+	ErrorPageUpstreamTimeout:    "workspace did not respond in time",
+	ErrorPageProxyNotReady:      "ws-proxy is not ready yet",
+	ErrorPageWorkspaceRestarted: "this workspace was restarted",
+}
+
+// ErrorPageData is available to error page templates and makes up the JSON variant of an error page
+type ErrorPageData struct {
+	Error       ErrorPage `json:"error"`
+	Status      int       `json:"status"`
+	Message     string    `json:"message"`
+	WorkspaceID string    `json:"workspaceID,omitempty"`
+	Port        string    `json:"port,omitempty"`
+	// EventsURL is where progress of a starting workspace is streamed to
+	EventsURL string `json:"eventsURL,omitempty"`
+	// GitpodURL is the URL of the Gitpod installation, e.g. https://gitpod.io
+	GitpodURL string `json:"-"`
+}
+
+// ErrorPages renders error pages from templates. Operators can provide their own templates
+// which take precedence over the builtin ones.
+type ErrorPages struct {
+	gitpodURL string
+	pages     map[ErrorPage]*template.Template
+	fallback  *template.Template
+}
+
+// NewErrorPages loads the error page templates
+func NewErrorPages(config *Config) (*ErrorPages, error) {
+	load := newPageTemplateLoader(config)
+
+	fallback, err := load(builtinPageError)
+	if err != nil {
+		return nil, err
+	}
+	if fallback == nil {
+		return nil, xerrors.Errorf("error page %s not found", builtinPageError)
+	}
+
+	pages := make(map[ErrorPage]*template.Template, len(errorPages))
+	for _, p := range errorPages {
+		tpl, err := load(string(p) + ".html")
+		if err != nil {
+			return nil, err
+		}
+		if tpl == nil {
+			continue
+		}
+		pages[p] = tpl
+	}
+
+	return &ErrorPages{
+		gitpodURL: gitpodInstallationURL(config),
+		pages:     pages,
+		fallback:  fallback,
+	}, nil
+}
+
+// newPageTemplateLoader produces a function which loads page templates from the operator provided template location
+// first, and the builtin pages second. The loader returns nil if neither location has the template.
+func newPageTemplateLoader(config *Config) func(name string) (*template.Template, error) {
+	gitpodURL := gitpodInstallationURL(config)
+	tpRoot := os.Getenv("TELEPRESENCE_ROOT")
+
+	var locations []string
+	if config.BuiltinPages.TemplateLocation != "" {
+		locations = append(locations, filepath.Join(tpRoot, config.BuiltinPages.TemplateLocation))
+	}
+	locations = append(locations, filepath.Join(tpRoot, config.BuiltinPages.Location))
+
+	return func(name string) (*template.Template, error) {
+		for _, loc := range locations {
+			fc, err := os.ReadFile(filepath.Join(loc, name))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, xerrors.Errorf("cannot read page %s: %w", name, err)
+			}
+
+			// builtin pages refer to gitpod.io, which we replace with the actual installation
+			fc = bytes.ReplaceAll(fc, []byte("https://gitpod.io"), []byte(gitpodURL))
+			tpl, err := template.New(name).Parse(string(fc))
+			if err != nil {
+				return nil, xerrors.Errorf("cannot parse page %s: %w", name, err)
+			}
+			return tpl, nil
+		}
+		return nil, nil
+	}
+}
+
+func gitpodInstallationURL(config *Config) string {
+	return fmt.Sprintf("%s://%s", config.GitpodInstallation.Scheme, config.GitpodInstallation.HostName)
+}
+
+// Serve renders an error page. Clients which prefer JSON over HTML get the JSON variant.
+func (p *ErrorPages) Serve(resp http.ResponseWriter, req *http.Request, page ErrorPage, status int, data ErrorPageData) {
+	data.Error = page
+	data.Status = status
+	if data.Message == "" {
+		data.Message = errorPageMessages[page]
+	}
+	data.GitpodURL = p.gitpodURL
+
+	if prefersJSON(req) {
+		resp.Header().Set("Content-Type", "application/json")
+		resp.WriteHeader(status)
+		err := json.NewEncoder(resp).Encode(data)
+		if err != nil {
+			log.WithError(err).WithField("page", page).Warn("cannot render error page")
+		}
+		return
+	}
+
+	tpl, ok := p.pages[page]
+	if !ok {
+		tpl = p.fallback
+	}
+	// render first so that a broken template does not leave us with half a page
+	var buf bytes.Buffer
+	err := tpl.Execute(&buf, data)
+	if err != nil {
+		log.WithError(err).WithField("page", page).Warn("cannot render error page")
+		resp.WriteHeader(status)
+		return
+	}
+
+	resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	resp.WriteHeader(status)
+	resp.Write(buf.Bytes())
+}
+
+// Handler produces an HTTP handler which serves an error page for the workspace the request is for
+func (p *ErrorPages) Handler(page ErrorPage, status int) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		coords := getWorkspaceCoords(req)
+		p.Serve(resp, req, page, status, ErrorPageData{
+			WorkspaceID: coords.ID,
+			Port:        coords.Port,
+		})
+	})
+}
+
+// serveErrorPage serves an error page to clients which explicitly accept HTML or JSON.
+// All other clients, e.g. API calls made by the IDE, get just the status code as they always did.
+func serveErrorPage(pages *ErrorPages, resp http.ResponseWriter, req *http.Request, page ErrorPage, status int) {
+	accept := req.Header.Get("Accept")
+	if pages == nil || !(strings.Contains(accept, "text/html") || strings.Contains(accept, "application/json")) {
+		resp.WriteHeader(status)
+		return
+	}
+	pages.Handler(page, status).ServeHTTP(resp, req)
+}
+
+// prefersJSON returns true if the request accepts JSON, but not HTML
+func prefersJSON(req *http.Request) bool {
+	accept := req.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+)
+
+func TestErrorPages(t *testing.T) {
+	builtin, operator := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(builtin, builtinPageError):            "builtin {{.Status}} {{.Message}} https://gitpod.io",
+		filepath.Join(builtin, "port-not-found.html"):       "builtin port {{.Port}}",
+		filepath.Join(operator, "port-not-found.html"):      "operator port {{.Port}} of {{.WorkspaceID}}",
+		filepath.Join(operator, "workspace-not-found.html"): "operator {{.WorkspaceID}}",
+	}
+	for fn, content := range files {
+		err := os.WriteFile(fn, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		Name             string
+		TemplateLocation string
+		Page             ErrorPage
+		Status           int
+		Accept           string
+		WorkspaceID      string
+		Expectation      string
+		ContentType      string
+	}{
+		{
+			Name:        "builtin",
+			Page:        ErrorPagePortNotFound,
+			Status:      http.StatusNotFound,
+			WorkspaceID: "amaranth-smelt-9ba20cc1",
+			Expectation: "builtin port 8080",
+			ContentType: "text/html; charset=utf-8",
+		},
+		{
+			Name:        "fallback",
+			Page:        ErrorPageUpstreamTimeout,
+			Status:      http.StatusGatewayTimeout,
+			Expectation: "builtin 504 workspace did not respond in time https://test-domain.com",
+			ContentType: "text/html; charset=utf-8",
+		},
+		{
+			Name:             "operator template",
+			TemplateLocation: operator,
+			Page:             ErrorPagePortNotFound,
+			Status:           http.StatusNotFound,
+			WorkspaceID:      "amaranth-smelt-9ba20cc1",
+			Expectation:      "operator port 8080 of amaranth-smelt-9ba20cc1",
+			ContentType:      "text/html; charset=utf-8",
+		},
+		{
+			Name:             "operator template escapes",
+			TemplateLocation: operator,
+			Page:             ErrorPageWorkspaceNotFound,
+			Status:           http.StatusNotFound,
+			WorkspaceID:      "<script>",
+			Expectation:      "operator &lt;script&gt;",
+			ContentType:      "text/html; charset=utf-8",
+		},
+		{
+			Name:        "JSON",
+			Page:        ErrorPagePortNotFound,
+			Status:      http.StatusNotFound,
+			Accept:      "application/json",
+			WorkspaceID: "amaranth-smelt-9ba20cc1",
+			Expectation: `{"error":"port-not-found","status":404,"message":"port not found","workspaceID":"amaranth-smelt-9ba20cc1","port":"8080"}` + "\n",
+			ContentType: "application/json",
+		},
+		{
+			Name:        "HTML preferred over JSON",
+			Page:        ErrorPagePortNotFound,
+			Status:      http.StatusNotFound,
+			Accept:      "text/html,application/json",
+			Expectation: "builtin port 8080",
+			ContentType: "text/html; charset=utf-8",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := config
+			cfg.BuiltinPages = BuiltinPagesConfig{Location: builtin, TemplateLocation: test.TemplateLocation}
+			pages, err := NewErrorPages(&cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			if test.Accept != "" {
+				req.Header.Set("Accept", test.Accept)
+			}
+			req = mux.SetURLVars(req, map[string]string{
+				workspaceIDIdentifier:   test.WorkspaceID,
+				workspacePortIdentifier: "8080",
+			})
+			rec := httptest.NewRecorder()
+			pages.Handler(test.Page, test.Status).ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("unexpected status code: expected %d, got %d", test.Status, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != test.ContentType {
+				t.Errorf("unexpected content type: %s", ct)
+			}
+			if diff := cmp.Diff(test.Expectation, rec.Body.String()); diff != "" {
+				t.Errorf("unexpected body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// experimentNameRegexp matches experiment and variant names. ws-manager admits the same names.
+var experimentNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]{0,61}[a-z0-9])?$`)
+
+// Experiments configures how we serve the IDE to workspaces which take part in an experiment.
+// ws-manager assigns workspaces to variants when they start; workspaces which are not assigned a variant
+// we know are served as usual.
+type Experiments map[string]ExperimentConfig
+
+// ExperimentConfig configures the variants of an experiment
+type ExperimentConfig struct {
+	Variants map[string]ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariant configures how we serve the IDE to workspaces assigned to the variant
+type ExperimentVariant struct {
+	// IDEImage replaces the IDE image of the workspace when serving IDE assets from blobserve
+	IDEImage string `json:"ideImage,omitempty"`
+	// Headers are applied to IDE requests and responses after the header policy
+	Headers []HeaderRule `json:"headers,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (e Experiments) Validate() error {
+	for name, exp := range e {
+		if !experimentNameRegexp.MatchString(name) {
+			return xerrors.Errorf("invalid experiments config: invalid experiment name %q", name)
+		}
+		if len(exp.Variants) == 0 {
+			return xerrors.Errorf("invalid experiments config: experiment %s has no variants", name)
+		}
+		for vname, v := range exp.Variants {
+			if !experimentNameRegexp.MatchString(vname) {
+				return xerrors.Errorf("invalid experiments config: experiment %s: invalid variant name %q", name, vname)
+			}
+			err := validation.ValidateStruct(&v,
+				validation.Field(&v.Headers),
+			)
+			if err != nil {
+				return xerrors.Errorf("invalid experiments config: experiment %s, variant %s: %w", name, vname, err)
+			}
+		}
+	}
+	return nil
+}
+
+// experimentAssignment is the variant a workspace is assigned to in an experiment we know
+type experimentAssignment struct {
+	Experiment string
+	Variant    string
+	Config     ExperimentVariant
+}
+
+// experimentAssignments are the assignments of a workspace, ordered by experiment name
+type experimentAssignments []experimentAssignment
+
+// resolveExperiments returns the assignments of the workspace to experiments we know.
+// Assignments to experiments or variants we don't know are ignored.
+func resolveExperiments(cfg Experiments, info *WorkspaceInfo) experimentAssignments {
+	if len(cfg) == 0 || info == nil || len(info.Experiments) == 0 {
+		return nil
+	}
+
+	var res experimentAssignments
+	for name, variant := range info.Experiments {
+		exp, ok := cfg[name]
+		if !ok {
+			continue
+		}
+		v, ok := exp.Variants[variant]
+		if !ok {
+			continue
+		}
+		res = append(res, experimentAssignment{Experiment: name, Variant: variant, Config: v})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Experiment < res[j].Experiment })
+	return res
+}
+
+// IDEImage returns the IDE image of the first assignment which replaces it, or an empty string if none does
+func (a experimentAssignments) IDEImage() string {
+	for _, e := range a {
+		if e.Config.IDEImage != "" {
+			return e.Config.IDEImage
+		}
+	}
+	return ""
+}
+
+// ApplyRequest applies the request header rules of all assignments
+func (a experimentAssignments) ApplyRequest(req *http.Request) {
+	for _, e := range a {
+		for _, r := range e.Config.Headers {
+			if r.Direction == HeaderRuleRequest && r.appliesTo(SLORouteClassIDE) {
+				r.apply(req.Header)
+			}
+		}
+	}
+}
+
+// ApplyResponse applies the response header rules of all assignments
+func (a experimentAssignments) ApplyResponse(resp *http.Response) {
+	for _, e := range a {
+		for _, r := range e.Config.Headers {
+			if r.Direction == HeaderRuleResponse && r.appliesTo(SLORouteClassIDE) {
+				r.apply(resp.Header)
+			}
+		}
+	}
+}
+
+type experimentsContextKey struct{}
+
+func getExperimentAssignments(ctx context.Context) experimentAssignments {
+	a, _ := ctx.Value(experimentsContextKey{}).(experimentAssignments)
+	return a
+}
+
+// ExperimentTracker counts how often we served the IDE of an experiment variant
+type ExperimentTracker struct {
+	exposures *prometheus.CounterVec
+}
+
+// NewExperimentTracker creates a new experiment tracker
+func NewExperimentTracker() *ExperimentTracker {
+	return &ExperimentTracker{
+		exposures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "experiment_exposures_total",
+			Help: "IDE page loads of workspaces assigned to an experiment variant",
+		}, []string{"experiment", "variant"}),
+	}
+}
+
+// RegisterMetrics registers the exposure metrics
+func (t *ExperimentTracker) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(t.exposures)
+}
+
+func (t *ExperimentTracker) expose(info *WorkspaceInfo, a experimentAssignments) {
+	for _, e := range a {
+		if t != nil {
+			t.exposures.WithLabelValues(e.Experiment, e.Variant).Inc()
+		}
+		log.WithFields(log.OWI("", info.WorkspaceID, info.InstanceID)).
+			WithField("experiment", e.Experiment).
+			WithField("variant", e.Variant).
+			Debug("serving IDE experiment variant")
+	}
+}
+
+// experimentHandler serves workspaces which take part in an experiment according to their variant.
+// It must run after the workspaceMustExistHandler, because it needs the workspace info in the request context.
+// If a variant replaces the IDE image, subsequent handlers see a workspace info with that image.
+func experimentHandler(cfg Experiments, tracker *ExperimentTracker) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if len(cfg) == 0 {
+			return h
+		}
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			info := getWorkspaceInfoFromContext(req.Context())
+			assignments := resolveExperiments(cfg, info)
+			if len(assignments) == 0 {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			// the IDE page load is the one request per session we count as exposure
+			if req.URL.Path == "/" {
+				tracker.expose(info, assignments)
+			}
+
+			ctx := context.WithValue(req.Context(), experimentsContextKey{}, assignments)
+			if img := assignments.IDEImage(); img != "" {
+				// the info is shared through the cache - we must not modify it
+				variant := *info
+				variant.IDEImage = img
+				ctx = context.WithValue(ctx, infoContextValueKey, &variant)
+			}
+			h.ServeHTTP(resp, req.WithContext(ctx))
+		})
+	}
+}
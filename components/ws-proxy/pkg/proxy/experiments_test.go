@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var testExperiments = Experiments{
+	"ide-assets": {Variants: map[string]ExperimentVariant{
+		"control": {},
+		"next":    {IDEImage: "eu.gcr.io/gitpod-core-dev/build/ide/code:next"},
+	}},
+	"coop": {Variants: map[string]ExperimentVariant{
+		"strict": {Headers: []HeaderRule{
+			{Direction: HeaderRuleResponse, Action: HeaderRuleAdd, Header: "Cross-Origin-Opener-Policy", Value: "same-origin"},
+		}},
+	}},
+}
+
+func TestExperimentsValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config Experiments
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "valid", Config: testExperiments},
+		{Name: "invalid experiment name", Config: Experiments{"IDE Assets": {Variants: map[string]ExperimentVariant{"a": {}}}}, Error: true},
+		{Name: "invalid variant name", Config: Experiments{"ide": {Variants: map[string]ExperimentVariant{"-a": {}}}}, Error: true},
+		{Name: "no variants", Config: Experiments{"ide": {}}, Error: true},
+		{Name: "invalid header rule", Config: Experiments{"ide": {Variants: map[string]ExperimentVariant{"a": {Headers: []HeaderRule{{Direction: "sideways"}}}}}}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveExperiments(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Experiments map[string]string
+		Expectation []string
+		IDEImage    string
+	}{
+		{Name: "no experiments"},
+		{Name: "unknown experiment", Experiments: map[string]string{"foo": "bar"}},
+		{Name: "unknown variant", Experiments: map[string]string{"ide-assets": "previous"}},
+		{
+			Name:        "control",
+			Experiments: map[string]string{"ide-assets": "control"},
+			Expectation: []string{"ide-assets/control"},
+		},
+		{
+			Name:        "sorted",
+			Experiments: map[string]string{"ide-assets": "next", "coop": "strict"},
+			Expectation: []string{"coop/strict", "ide-assets/next"},
+			IDEImage:    "eu.gcr.io/gitpod-core-dev/build/ide/code:next",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := resolveExperiments(testExperiments, &WorkspaceInfo{Experiments: test.Experiments})
+
+			var names []string
+			for _, a := range act {
+				names = append(names, a.Experiment+"/"+a.Variant)
+			}
+			if diff := cmp.Diff(test.Expectation, names); diff != "" {
+				t.Errorf("unexpected assignments (-want +got):\n%s", diff)
+			}
+			if img := act.IDEImage(); img != test.IDEImage {
+				t.Errorf("unexpected IDE image %q, expected %q", img, test.IDEImage)
+			}
+		})
+	}
+}
+
+func TestExperimentHandler(t *testing.T) {
+	info := &WorkspaceInfo{
+		WorkspaceID: "amaranth-smelt-9ba20cc1",
+		IDEImage:    "eu.gcr.io/gitpod-core-dev/build/ide/code:latest",
+		Experiments: map[string]string{"ide-assets": "next", "coop": "strict"},
+	}
+	tracker := NewExperimentTracker()
+
+	var (
+		served *WorkspaceInfo
+		resp   = &http.Response{Header: make(http.Header)}
+	)
+	handler := experimentHandler(testExperiments, tracker)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served = getWorkspaceInfoFromContext(req.Context())
+		getExperimentAssignments(req.Context()).ApplyResponse(resp)
+	}))
+
+	for _, path := range []string{"/", "/out/vs/workbench/workbench.web.api.js"} {
+		req := httptest.NewRequest("GET", "http://workspace"+path, nil)
+		req = req.WithContext(context.WithValue(req.Context(), infoContextValueKey, info))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if served == nil || served.IDEImage != "eu.gcr.io/gitpod-core-dev/build/ide/code:next" {
+		t.Errorf("IDE image was not replaced: %+v", served)
+	}
+	if info.IDEImage != "eu.gcr.io/gitpod-core-dev/build/ide/code:latest" {
+		t.Errorf("cached workspace info was modified: %s", info.IDEImage)
+	}
+	if diff := cmp.Diff([]string{"same-origin", "same-origin"}, resp.Header.Values("Cross-Origin-Opener-Policy")); diff != "" {
+		t.Errorf("unexpected response headers (-want +got):\n%s", diff)
+	}
+	// only the IDE page load counts as exposure
+	for _, exp := range []struct{ Experiment, Variant string }{{"ide-assets", "next"}, {"coop", "strict"}} {
+		if n := testutil.ToFloat64(tracker.exposures.WithLabelValues(exp.Experiment, exp.Variant)); n != 1 {
+			t.Errorf("expected one exposure of %s/%s, got %v", exp.Experiment, exp.Variant, n)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+)
+
+var foreignContentPrefixRegex = regexp.MustCompile("^[a-z][a-z0-9]*$")
+
+// ForeignContentConfig configures the origin we serve foreign content, e.g. webviews and the mini-browser, from.
+// Foreign hosts look like <prefix>-[<port>-]<workspaceID><hostSuffix>.
+type ForeignContentConfig struct {
+	// HostSuffix is the host suffix of foreign content, e.g. ".ws-foreign.gitpod.io". Workspace hosts no longer serve
+	// foreign content if it differs from the workspace host suffix. Defaults to the workspace host suffix.
+	HostSuffix string `json:"hostSuffix,omitempty"`
+	// Prefixes are the host prefixes of foreign content. Defaults to webview, browser and extensions.
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ForeignContentConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.HostSuffix, validation.By(func(o interface{}) error {
+			suffix, ok := o.(string)
+			if !ok {
+				return xerrors.Errorf("field should be a string")
+			}
+			if suffix == "" {
+				return nil
+			}
+			if !strings.HasPrefix(suffix, ".") {
+				return xerrors.Errorf("must start with a dot")
+			}
+			return is.Host.Validate(strings.TrimPrefix(suffix, "."))
+		})),
+		validation.Field(&c.Prefixes, validation.Each(validation.Match(foreignContentPrefixRegex))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid foreign content config: %w", err)
+	}
+	return nil
+}
+
+// matchFirst matches if any of the matchers does. Matchers must not modify the route match unless they match.
+func matchFirst(matchers ...mux.MatcherFunc) mux.MatcherFunc {
+	return func(req *http.Request, m *mux.RouteMatch) bool {
+		for _, f := range matchers {
+			if f(req, m) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// stripCredentialsHandler removes the credentials of the workspace owner from requests for foreign content once they
+// are authenticated, so that the sandboxed content cannot read them
+func stripCredentialsHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		req.Header.Del("Cookie")
+		req.Header.Del("Authorization")
+		req.Header.Del(workspaceOwnerTokenHeader)
+		h.ServeHTTP(resp, req)
+	})
+}
+
+// withSetCookieFilter stops foreign content from setting cookies, which the browser would send to the workspace otherwise
+func withSetCookieFilter() proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		cfg.appendResponseHandler(func(resp *http.Response, req *http.Request) error {
+			resp.Header.Del("Set-Cookie")
+			return nil
+		})
+	}
+}
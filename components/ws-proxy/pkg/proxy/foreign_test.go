@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+)
+
+func TestForeignHostMatch(t *testing.T) {
+	const wsHostSuffix = ".ws.gitpod.io"
+	type matchResult struct {
+		MatchesWorkspace bool
+		MatchesPort      bool
+		Vars             map[string]string
+	}
+	tests := []struct {
+		Name       string
+		Config     *ForeignContentConfig
+		HostHeader string
+		Expected   matchResult
+	}{
+		{
+			Name:       "webview",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io"},
+			HostHeader: "webview-amaranth-smelt-9ba20cc1.ws-foreign.gitpod.io",
+			Expected: matchResult{
+				MatchesWorkspace: true,
+				Vars: map[string]string{
+					foreignOriginPrefix:   "webview-",
+					workspaceIDIdentifier: "amaranth-smelt-9ba20cc1",
+				},
+			},
+		},
+		{
+			Name:       "mini browser port",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io"},
+			HostHeader: "browser-3000-amaranth-smelt-9ba20cc1.ws-foreign.gitpod.io:443",
+			Expected: matchResult{
+				MatchesPort: true,
+				Vars: map[string]string{
+					foreignOriginPrefix:     "browser-",
+					workspaceIDIdentifier:   "amaranth-smelt-9ba20cc1",
+					workspacePortIdentifier: "3000",
+				},
+			},
+		},
+		{
+			Name:       "workspace host",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io"},
+			HostHeader: "amaranth-smelt-9ba20cc1.ws.gitpod.io",
+			Expected: matchResult{
+				MatchesWorkspace: true,
+				Vars: map[string]string{
+					foreignOriginPrefix:   "",
+					workspaceIDIdentifier: "amaranth-smelt-9ba20cc1",
+				},
+			},
+		},
+		{
+			Name:       "foreign prefix on workspace host",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io"},
+			HostHeader: "webview-amaranth-smelt-9ba20cc1.ws.gitpod.io",
+		},
+		{
+			Name:       "foreign host without prefix",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io"},
+			HostHeader: "amaranth-smelt-9ba20cc1.ws-foreign.gitpod.io",
+		},
+		{
+			Name:       "unknown prefix",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io", Prefixes: []string{"webview"}},
+			HostHeader: "browser-amaranth-smelt-9ba20cc1.ws-foreign.gitpod.io",
+		},
+		{
+			Name:       "suffix is not a pattern",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io"},
+			HostHeader: "webview-amaranth-smelt-9ba20cc1.ws-foreignXgitpod.io",
+		},
+		{
+			Name:       "trailing host",
+			Config:     &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io"},
+			HostHeader: "webview-amaranth-smelt-9ba20cc1.ws-foreign.gitpod.io.evil.com",
+		},
+		{
+			Name:       "default suffix",
+			Config:     &ForeignContentConfig{},
+			HostHeader: "extensions-amaranth-smelt-9ba20cc1.ws.gitpod.io",
+			Expected: matchResult{
+				MatchesWorkspace: true,
+				Vars: map[string]string{
+					foreignOriginPrefix:   "extensions-",
+					workspaceIDIdentifier: "amaranth-smelt-9ba20cc1",
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r := mux.NewRouter()
+			theiaRouter, portRouter, _ := HostBasedRouter(forwardedHostnameHeader, wsHostSuffix, test.Config)(r, nil)
+			theiaRouter.NewRoute().HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+			portRouter.NewRoute().HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			req, _ := http.NewRequest(http.MethodGet, "https://"+test.HostHeader, nil)
+			req.Header.Set(forwardedHostnameHeader, test.HostHeader)
+
+			var (
+				res       matchResult
+				wsMatch   mux.RouteMatch
+				portMatch mux.RouteMatch
+			)
+			if portRouter.Match(req, &portMatch) {
+				res.MatchesPort = true
+				res.Vars = portMatch.Vars
+			} else if theiaRouter.Match(req, &wsMatch) {
+				res.MatchesWorkspace = true
+				res.Vars = wsMatch.Vars
+			}
+
+			if diff := cmp.Diff(test.Expected, res); diff != "" {
+				t.Errorf("unexpected match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestForeignContentConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *ForeignContentConfig
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "valid", Config: &ForeignContentConfig{HostSuffix: ".ws-foreign.gitpod.io", Prefixes: []string{"webview", "browser"}}},
+		{Name: "suffix without dot", Config: &ForeignContentConfig{HostSuffix: "ws-foreign.gitpod.io"}, Error: true},
+		{Name: "invalid suffix", Config: &ForeignContentConfig{HostSuffix: ".ws foreign"}, Error: true},
+		{Name: "invalid prefix", Config: &ForeignContentConfig{Prefixes: []string{"web-view"}}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
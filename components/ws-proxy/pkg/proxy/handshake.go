@@ -0,0 +1,327 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/handshake"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultHandshakeTimeout = 5 * time.Second
+	defaultHandshakeRefresh = 10 * time.Minute
+	// handshakeConcurrency limits the handshakes we perform at the same time
+	handshakeConcurrency = 10
+	// handshakeQueueSize limits the workspaces waiting for a handshake, e.g. when we learn about all running workspaces at startup
+	handshakeQueueSize = 1000
+
+	handshakeOutcomeAgreed   = "agreed"
+	handshakeOutcomeBaseline = "baseline"
+	handshakeOutcomeFailure  = "failure"
+	handshakeOutcomeDropped  = "dropped"
+)
+
+// HandshakeConfig configures the handshake in which we negotiate the protocol capabilities with the supervisor of
+// workspaces. Without the handshake we treat all supervisors as if they predated it.
+type HandshakeConfig struct {
+	// Compression are the content encodings we let supervisor compress its API responses with, in order of preference.
+	// Defaults to gzip.
+	Compression []string `json:"compression,omitempty"`
+	// KeepaliveInterval is the longest time connections to workspaces may stay idle before something between
+	// the user and supervisor, e.g. a load balancer, closes them. Supervisor then keeps its websockets busy more often.
+	KeepaliveInterval util.Duration `json:"keepaliveInterval,omitempty"`
+	// Timeout limits a single handshake. Defaults to 5 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+	// Refresh is the age after which we repeat a handshake, e.g. to notice a supervisor which restarted. Defaults to 10 minutes.
+	Refresh util.Duration `json:"refresh,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *HandshakeConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Compression, validation.Each(validation.In(encodingGzip))),
+		validation.Field(&c.KeepaliveInterval, validation.Min(util.Duration(0))),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+		validation.Field(&c.Refresh, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid handshake config: %w", err)
+	}
+	return nil
+}
+
+// SupervisorHandshakes negotiates the protocol capabilities with the supervisor of every running workspace, and
+// again whenever a workspace runs in a new instance or pod. Until a handshake completes, we assume the baseline.
+// Workspace status updates must be passed to Observe.
+type SupervisorHandshakes struct {
+	Config             HandshakeConfig
+	WorkspacePodConfig *WorkspacePodConfig
+	Client             *http.Client
+
+	capabilities handshake.Capabilities
+	queue        chan handshakeTarget
+	stop         chan struct{}
+	wg           sync.WaitGroup
+	metrics      *handshakeMetrics
+
+	mu sync.RWMutex
+	// agreements are keyed by workspace ID
+	agreements map[string]*supervisorAgreement
+}
+
+type handshakeTarget struct {
+	WorkspaceID string
+	InstanceID  string
+	PodIP       string
+}
+
+type supervisorAgreement struct {
+	handshakeTarget
+	Agreement handshake.Agreement
+	// Done is the time the handshake completed. Zero while the handshake is pending.
+	Done time.Time
+}
+
+// NewSupervisorHandshakes creates a new handshaker which connects to supervisor using the transport
+func NewSupervisorHandshakes(cfg HandshakeConfig, podCfg *WorkspacePodConfig, transport http.RoundTripper) *SupervisorHandshakes {
+	if len(cfg.Compression) == 0 {
+		cfg.Compression = []string{encodingGzip}
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = util.Duration(defaultHandshakeTimeout)
+	}
+	if cfg.Refresh == 0 {
+		cfg.Refresh = util.Duration(defaultHandshakeRefresh)
+	}
+
+	return &SupervisorHandshakes{
+		Config:             cfg,
+		WorkspacePodConfig: podCfg,
+		Client:             &http.Client{Transport: transport, Timeout: time.Duration(cfg.Timeout)},
+		capabilities: handshake.Capabilities{
+			Version:           handshake.Version,
+			Compression:       cfg.Compression,
+			KeepaliveInterval: cfg.KeepaliveInterval,
+		},
+		queue:      make(chan handshakeTarget, handshakeQueueSize),
+		stop:       make(chan struct{}),
+		metrics:    newHandshakeMetrics(),
+		agreements: make(map[string]*supervisorAgreement),
+	}
+}
+
+// RegisterMetrics registers the handshake metrics
+func (s *SupervisorHandshakes) RegisterMetrics(reg prometheus.Registerer) error {
+	return s.metrics.Register(reg)
+}
+
+// Start starts the handshake workers
+func (s *SupervisorHandshakes) Start() {
+	for i := 0; i < handshakeConcurrency; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+}
+
+// Close stops the handshake workers and waits for the handshakes in progress
+func (s *SupervisorHandshakes) Close() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// Observe queues a handshake when a workspace is running in an instance or pod we have not shaken hands with.
+// Observe never blocks: if too many handshakes are waiting already, we retry once a request needs the agreement.
+func (s *SupervisorHandshakes) Observe(status *wsapi.WorkspaceStatus) {
+	if status.Metadata == nil {
+		return
+	}
+	workspaceID := status.Metadata.MetaId
+
+	if status.Phase == wsapi.WorkspacePhase_STOPPING || status.Phase == wsapi.WorkspacePhase_STOPPED {
+		s.mu.Lock()
+		if a, ok := s.agreements[workspaceID]; ok && a.InstanceID == status.Id {
+			delete(s.agreements, workspaceID)
+		}
+		s.mu.Unlock()
+		return
+	}
+	if status.Phase != wsapi.WorkspacePhase_RUNNING {
+		return
+	}
+
+	var podIP string
+	if status.Runtime != nil {
+		podIP = status.Runtime.PodIp
+	}
+	s.handshake(handshakeTarget{WorkspaceID: workspaceID, InstanceID: status.Id, PodIP: podIP}, false)
+}
+
+// Agreement returns what we agreed on with the supervisor of a workspace. If we have not shaken hands yet,
+// or the agreement is due for a refresh, Agreement queues a handshake and returns the current agreement
+// or the baseline.
+func (s *SupervisorHandshakes) Agreement(workspaceID string) handshake.Agreement {
+	s.mu.RLock()
+	a, ok := s.agreements[workspaceID]
+	var (
+		res    handshake.Agreement
+		target handshakeTarget
+		stale  bool
+	)
+	if ok {
+		res = a.Agreement
+		target = a.handshakeTarget
+		stale = !a.Done.IsZero() && time.Since(a.Done) > time.Duration(s.Config.Refresh)
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		// we have not seen the workspace running yet, e.g. because ws-proxy just started
+		s.handshake(handshakeTarget{WorkspaceID: workspaceID}, false)
+	} else if stale {
+		s.handshake(target, true)
+	}
+	return res
+}
+
+// handshake queues a handshake unless one is pending or done for the target already
+func (s *SupervisorHandshakes) handshake(target handshakeTarget, refresh bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.agreements[target.WorkspaceID]
+	if ok && a.Done.IsZero() {
+		// a handshake is pending already
+		return
+	}
+	if ok && !refresh && (target.InstanceID == "" || a.handshakeTarget == target) {
+		return
+	}
+
+	pending := &supervisorAgreement{handshakeTarget: target}
+	if ok && a.handshakeTarget == target {
+		// until the refresh completes, the previous agreement stands
+		pending.Agreement = a.Agreement
+	}
+	select {
+	case s.queue <- target:
+		s.agreements[target.WorkspaceID] = pending
+	default:
+		s.metrics.OnHandshake(handshakeOutcomeDropped, 0)
+	}
+}
+
+func (s *SupervisorHandshakes) work() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case target := <-s.queue:
+			start := time.Now()
+			agreement, err := s.do(target.WorkspaceID)
+			s.complete(target, agreement, err)
+
+			outcome := handshakeOutcomeAgreed
+			if err != nil {
+				outcome = handshakeOutcomeFailure
+				log.WithError(err).WithFields(log.OWI("", target.WorkspaceID, target.InstanceID)).Debug("cannot shake hands with supervisor")
+			} else if agreement.Version == handshake.Baseline.Version {
+				outcome = handshakeOutcomeBaseline
+			}
+			s.metrics.OnHandshake(outcome, time.Since(start))
+		}
+	}
+}
+
+func (s *SupervisorHandshakes) do(workspaceID string) (handshake.Agreement, error) {
+	upstream, err := buildWorkspacePodURL(s.WorkspacePodConfig.ServiceTemplate, workspaceID, fmt.Sprint(s.WorkspacePodConfig.SupervisorPort))
+	if err != nil {
+		return handshake.Baseline, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.Config.Timeout))
+	defer cancel()
+	return handshake.Do(ctx, s.Client, strings.TrimSuffix(upstream.String(), "/"), s.capabilities)
+}
+
+// complete stores the outcome of a handshake. Failed handshakes leave the baseline in place
+// until the refresh interval passed.
+func (s *SupervisorHandshakes) complete(target handshakeTarget, agreement handshake.Agreement, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.agreements[target.WorkspaceID]
+	if !ok || a.handshakeTarget != target {
+		// the workspace stopped or moved on while we were shaking hands
+		return
+	}
+	if err != nil {
+		agreement = handshake.Baseline
+	}
+	a.Agreement = agreement
+	a.Done = time.Now()
+}
+
+// Handler asks supervisor for compressed API responses if we agreed on compression and the client accepts it.
+// Otherwise supervisor must not compress, hence we strip the Accept-Encoding header.
+func (s *SupervisorHandshakes) Handler(h http.HandlerFunc) http.HandlerFunc {
+	if s == nil {
+		return h
+	}
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if !isWebsocketRequest(req) {
+			agreement := s.Agreement(getWorkspaceCoords(req).ID)
+			enc := agreement.Compression
+			if enc != "" {
+				enc = (&compressor{Encodings: []string{enc}}).negotiate(req.Header.Get("Accept-Encoding"))
+			}
+			if enc != "" {
+				req.Header.Set("Accept-Encoding", enc)
+			} else {
+				req.Header.Del("Accept-Encoding")
+			}
+		}
+		h(resp, req)
+	}
+}
+
+type handshakeMetrics struct {
+	handshakes *prometheus.HistogramVec
+}
+
+func newHandshakeMetrics() *handshakeMetrics {
+	return &handshakeMetrics{
+		handshakes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "supervisor_handshake_seconds",
+			Help:    "Duration of handshakes with the supervisor of workspaces by outcome",
+			Buckets: []float64{.01, .05, .1, .5, 1, 5},
+		}, []string{"outcome"}),
+	}
+}
+
+func (m *handshakeMetrics) Register(reg prometheus.Registerer) error {
+	return reg.Register(m.handshakes)
+}
+
+func (m *handshakeMetrics) OnHandshake(outcome string, duration time.Duration) {
+	m.handshakes.WithLabelValues(outcome).Observe(duration.Seconds())
+}
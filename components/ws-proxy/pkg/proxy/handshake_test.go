@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/handshake"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func newHandshakeTestSupervisor(t *testing.T, supervisor *handshake.Capabilities) (handshakes *SupervisorHandshakes, count *int32) {
+	count = new(int32)
+	mux := http.NewServeMux()
+	if supervisor != nil {
+		h := handshake.Handler(*supervisor, nil)
+		mux.Handle(handshake.Path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(count, 1)
+			h.ServeHTTP(w, r)
+		}))
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	handshakes = NewSupervisorHandshakes(HandshakeConfig{KeepaliveInterval: util.Duration(20 * time.Second)}, &WorkspacePodConfig{
+		ServiceTemplate: "http://" + u.Host,
+		SupervisorPort:  22999,
+	}, http.DefaultTransport)
+	handshakes.Start()
+	t.Cleanup(handshakes.Close)
+	return handshakes, count
+}
+
+func runningStatus(instanceID, podIP string) *wsapi.WorkspaceStatus {
+	return &wsapi.WorkspaceStatus{
+		Id:       instanceID,
+		Phase:    wsapi.WorkspacePhase_RUNNING,
+		Metadata: &wsapi.WorkspaceMetadata{MetaId: "amaranth-smelt-9ba20cc1"},
+		Runtime:  &wsapi.WorkspaceRuntimeInfo{PodIp: podIP},
+	}
+}
+
+func waitForAgreement(t *testing.T, handshakes *SupervisorHandshakes, expected handshake.Agreement) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		handshakes.mu.RLock()
+		a, ok := handshakes.agreements["amaranth-smelt-9ba20cc1"]
+		done := ok && !a.Done.IsZero()
+		var act handshake.Agreement
+		if done {
+			act = a.Agreement
+		}
+		handshakes.mu.RUnlock()
+		if done && act == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected agreement %+v, got %+v (done: %v)", expected, act, done)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSupervisorHandshakes(t *testing.T) {
+	supervisor := handshake.Capabilities{Version: handshake.Version, Compression: []string{"gzip"}}
+	handshakes, count := newHandshakeTestSupervisor(t, &supervisor)
+	expected := handshake.Agreement{Version: handshake.Version, Compression: "gzip", KeepaliveInterval: util.Duration(20 * time.Second)}
+
+	handshakes.Observe(runningStatus("instance-1", "10.0.0.1"))
+	waitForAgreement(t, handshakes, expected)
+	if act := handshakes.Agreement("amaranth-smelt-9ba20cc1"); act != expected {
+		t.Errorf("unexpected agreement %+v", act)
+	}
+
+	// the same instance does not shake hands again
+	handshakes.Observe(runningStatus("instance-1", "10.0.0.1"))
+	handshakes.Agreement("amaranth-smelt-9ba20cc1")
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(count); n != 1 {
+		t.Errorf("expected one handshake, got %d", n)
+	}
+
+	// a new pod does
+	handshakes.Observe(runningStatus("instance-1", "10.0.0.2"))
+	waitForAgreement(t, handshakes, expected)
+	if n := atomic.LoadInt32(count); n != 2 {
+		t.Errorf("expected a handshake with the new pod, got %d handshakes", n)
+	}
+
+	// stopping forgets the agreement
+	stopping := runningStatus("instance-1", "10.0.0.2")
+	stopping.Phase = wsapi.WorkspacePhase_STOPPING
+	handshakes.Observe(stopping)
+	handshakes.mu.RLock()
+	_, ok := handshakes.agreements["amaranth-smelt-9ba20cc1"]
+	handshakes.mu.RUnlock()
+	if ok {
+		t.Error("expected the agreement to be forgotten once the workspace stops")
+	}
+}
+
+func TestSupervisorHandshakesBaseline(t *testing.T) {
+	// supervisor predates the handshake
+	handshakes, _ := newHandshakeTestSupervisor(t, nil)
+
+	if act := handshakes.Agreement("amaranth-smelt-9ba20cc1"); act != handshake.Baseline {
+		t.Errorf("expected the baseline before the handshake, got %+v", act)
+	}
+	waitForAgreement(t, handshakes, handshake.Baseline)
+}
+
+func TestSupervisorHandshakesHandler(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Agreement      handshake.Agreement
+		AcceptEncoding string
+		Expected       string
+	}{
+		{Name: "baseline", Agreement: handshake.Baseline, AcceptEncoding: "gzip, br", Expected: ""},
+		{Name: "agreed compression", Agreement: handshake.Agreement{Version: 1, Compression: "gzip"}, AcceptEncoding: "br, gzip", Expected: "gzip"},
+		{Name: "client rejects compression", Agreement: handshake.Agreement{Version: 1, Compression: "gzip"}, AcceptEncoding: "br", Expected: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			handshakes := NewSupervisorHandshakes(HandshakeConfig{}, &WorkspacePodConfig{}, http.DefaultTransport)
+			handshakes.agreements["amaranth-smelt-9ba20cc1"] = &supervisorAgreement{Agreement: test.Agreement, Done: time.Now()}
+
+			var act string
+			h := handshakes.Handler(func(w http.ResponseWriter, r *http.Request) {
+				act = r.Header.Get("Accept-Encoding")
+			})
+			req := httptest.NewRequest("GET", "/_supervisor/v1/status/ports", nil)
+			req.Header.Set("Accept-Encoding", test.AcceptEncoding)
+			req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: "amaranth-smelt-9ba20cc1"})
+			h(httptest.NewRecorder(), req)
+
+			if act != test.Expected {
+				t.Errorf("unexpected upstream Accept-Encoding: want %q, got %q", test.Expected, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// workspaceIDHeader tells the upstream which workspace a request is for
+	workspaceIDHeader = "X-Gitpod-WorkspaceId"
+	// instanceIDHeader tells the upstream which workspace instance a request is for
+	instanceIDHeader = "X-Gitpod-InstanceId"
+)
+
+// HeaderRule directions
+const (
+	HeaderRuleRequest  = "request"
+	HeaderRuleResponse = "response"
+)
+
+// HeaderRule actions
+const (
+	HeaderRuleAdd     = "add"
+	HeaderRuleRemove  = "remove"
+	HeaderRuleReplace = "replace"
+)
+
+// HeaderPolicyConfig configures how we rewrite the headers of requests towards workspaces and of their responses.
+// Hop-by-hop headers are never forwarded, irrespective of this policy.
+type HeaderPolicyConfig struct {
+	// StripRequestHeaders are removed from requests before they reach a workspace.
+	// Clients can never set X-Gitpod-WorkspaceId and X-Gitpod-InstanceId themselves.
+	StripRequestHeaders []string `json:"stripRequestHeaders,omitempty"`
+
+	// ContentSecurityPolicy is set on IDE responses
+	ContentSecurityPolicy string `json:"contentSecurityPolicy,omitempty"`
+	// StrictTransportSecurity is set on IDE responses
+	StrictTransportSecurity string `json:"strictTransportSecurity,omitempty"`
+	// FrameOptions is set as X-Frame-Options on IDE responses, e.g. SAMEORIGIN
+	FrameOptions string `json:"frameOptions,omitempty"`
+
+	// Rules are additional rewrites, applied in order after everything else
+	Rules []HeaderRule `json:"rules,omitempty"`
+}
+
+// HeaderRule adds, removes or replaces a header
+type HeaderRule struct {
+	// Routes limits the rule to route classes (ide, port, blobserve). Empty applies the rule to all routes.
+	Routes []string `json:"routes,omitempty"`
+	// Direction is either request (towards the workspace) or response (towards the client)
+	Direction string `json:"direction"`
+	// Action is one of add, remove or replace. Replace sets the header only if it's present already.
+	Action string `json:"action"`
+	Header string `json:"header"`
+	Value  string `json:"value,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *HeaderPolicyConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.FrameOptions, validation.In("DENY", "SAMEORIGIN")),
+		validation.Field(&c.Rules),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid header policy: %w", err)
+	}
+	return nil
+}
+
+// Validate validates the rule
+func (r HeaderRule) Validate() error {
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.Routes, validation.Each(validation.In(SLORouteClassIDE, SLORouteClassPort, SLORouteClassBlobserve))),
+		validation.Field(&r.Direction, validation.Required, validation.In(HeaderRuleRequest, HeaderRuleResponse)),
+		validation.Field(&r.Action, validation.Required, validation.In(HeaderRuleAdd, HeaderRuleRemove, HeaderRuleReplace)),
+		validation.Field(&r.Header, validation.Required),
+	)
+	if err != nil {
+		return err
+	}
+	if r.Action != HeaderRuleRemove && r.Value == "" {
+		return xerrors.Errorf("%s rule for %s needs a value", r.Action, r.Header)
+	}
+	return nil
+}
+
+func (r HeaderRule) appliesTo(class string) bool {
+	if len(r.Routes) == 0 {
+		return true
+	}
+	for _, c := range r.Routes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func (r HeaderRule) apply(h http.Header) {
+	switch r.Action {
+	case HeaderRuleAdd:
+		h.Add(r.Header, r.Value)
+	case HeaderRuleRemove:
+		h.Del(r.Header)
+	case HeaderRuleReplace:
+		if _, ok := h[http.CanonicalHeaderKey(r.Header)]; ok {
+			h.Set(r.Header, r.Value)
+		}
+	}
+}
+
+// headerPolicy is the header policy of a route class
+type headerPolicy struct {
+	Config *HeaderPolicyConfig
+	Class  string
+	Info   WorkspaceInfoProvider
+}
+
+type headerPolicyContextKey struct{}
+
+// headerPolicyHandler makes proxyPass apply the header policy to requests of this route class and their responses.
+// If the policy is nil, we leave all headers as they are.
+func headerPolicyHandler(cfg *HeaderPolicyConfig, class string, info WorkspaceInfoProvider) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if cfg == nil {
+			return h
+		}
+		policy := &headerPolicy{Config: cfg, Class: class, Info: info}
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			h.ServeHTTP(resp, req.WithContext(context.WithValue(req.Context(), headerPolicyContextKey{}, policy)))
+		})
+	}
+}
+
+func getHeaderPolicy(ctx context.Context) *headerPolicy {
+	p, _ := ctx.Value(headerPolicyContextKey{}).(*headerPolicy)
+	return p
+}
+
+// ApplyRequest rewrites the headers of a request before we forward it
+func (p *headerPolicy) ApplyRequest(req *http.Request) {
+	if p == nil {
+		return
+	}
+
+	req.Header.Del(workspaceIDHeader)
+	req.Header.Del(instanceIDHeader)
+	for _, h := range p.Config.StripRequestHeaders {
+		req.Header.Del(h)
+	}
+
+	if coords := getWorkspaceCoords(req); coords.ID != "" {
+		req.Header.Set(workspaceIDHeader, coords.ID)
+
+		info := getWorkspaceInfoFromContext(req.Context())
+		if info == nil && p.Info != nil {
+			info = p.Info.WorkspaceInfo(req.Context(), coords.ID)
+		}
+		if info != nil && info.InstanceID != "" {
+			req.Header.Set(instanceIDHeader, info.InstanceID)
+		}
+	}
+
+	for _, r := range p.Config.Rules {
+		if r.Direction == HeaderRuleRequest && r.appliesTo(p.Class) {
+			r.apply(req.Header)
+		}
+	}
+}
+
+// ApplyResponse rewrites the headers of a response before we send it to the client
+func (p *headerPolicy) ApplyResponse(resp *http.Response) {
+	if p == nil {
+		return
+	}
+
+	if p.Class == SLORouteClassIDE {
+		for h, v := range map[string]string{
+			"Content-Security-Policy":   p.Config.ContentSecurityPolicy,
+			"Strict-Transport-Security": p.Config.StrictTransportSecurity,
+			"X-Frame-Options":           p.Config.FrameOptions,
+		} {
+			if v != "" {
+				resp.Header.Set(h, v)
+			}
+		}
+	}
+
+	for _, r := range p.Config.Rules {
+		if r.Direction == HeaderRuleResponse && r.appliesTo(p.Class) {
+			r.apply(resp.Header)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+)
+
+func TestHeaderPolicy(t *testing.T) {
+	cfg := &HeaderPolicyConfig{
+		StripRequestHeaders:     []string{"X-Internal-Secret"},
+		ContentSecurityPolicy:   "frame-ancestors 'self'",
+		StrictTransportSecurity: "max-age=31536000",
+		FrameOptions:            "SAMEORIGIN",
+		Rules: []HeaderRule{
+			{Direction: HeaderRuleRequest, Action: HeaderRuleAdd, Header: "X-Env", Value: "prod"},
+			{Routes: []string{SLORouteClassPort}, Direction: HeaderRuleRequest, Action: HeaderRuleRemove, Header: "Cookie"},
+			{Direction: HeaderRuleResponse, Action: HeaderRuleReplace, Header: "Server", Value: "gitpod"},
+			{Routes: []string{SLORouteClassPort}, Direction: HeaderRuleResponse, Action: HeaderRuleRemove, Header: "X-Powered-By"},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	infos := &fixedInfoProvider{Infos: map[string]*WorkspaceInfo{
+		workspaces[0].WorkspaceID: &workspaces[0],
+	}}
+
+	tests := []struct {
+		Class            string
+		ExpectedRequest  http.Header
+		ExpectedResponse http.Header
+	}{
+		{
+			Class: SLORouteClassIDE,
+			ExpectedRequest: http.Header{
+				"Cookie":               {"foo=bar"},
+				"X-Env":                {"prod"},
+				"X-Gitpod-Instanceid":  {workspaces[0].InstanceID},
+				"X-Gitpod-Workspaceid": {workspaces[0].WorkspaceID},
+			},
+			ExpectedResponse: http.Header{
+				"Content-Security-Policy":   {"frame-ancestors 'self'"},
+				"Server":                    {"gitpod"},
+				"Strict-Transport-Security": {"max-age=31536000"},
+				"X-Frame-Options":           {"SAMEORIGIN"},
+				"X-Powered-By":              {"php"},
+			},
+		},
+		{
+			Class: SLORouteClassPort,
+			ExpectedRequest: http.Header{
+				"X-Env":                {"prod"},
+				"X-Gitpod-Instanceid":  {workspaces[0].InstanceID},
+				"X-Gitpod-Workspaceid": {workspaces[0].WorkspaceID},
+			},
+			ExpectedResponse: http.Header{
+				"Server": {"gitpod"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Class, func(t *testing.T) {
+			var policy *headerPolicy
+			handler := headerPolicyHandler(cfg, test.Class, infos)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				policy = getHeaderPolicy(req.Context())
+			}))
+			req := httptest.NewRequest("GET", "http://localhost/", nil)
+			req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: workspaces[0].WorkspaceID})
+			req.Header.Set("Cookie", "foo=bar")
+			req.Header.Set("X-Internal-Secret", "secret")
+			req.Header.Set(instanceIDHeader, "spoofed")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			policy.ApplyRequest(req)
+			if diff := cmp.Diff(test.ExpectedRequest, req.Header); diff != "" {
+				t.Errorf("unexpected request headers (-want +got):\n%s", diff)
+			}
+
+			resp := &http.Response{Header: http.Header{
+				"Server":       {"nginx"},
+				"X-Powered-By": {"php"},
+			}}
+			policy.ApplyResponse(resp)
+			if diff := cmp.Diff(test.ExpectedResponse, resp.Header); diff != "" {
+				t.Errorf("unexpected response headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeaderPolicyValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *HeaderPolicyConfig
+		Error  bool
+	}{
+		{Name: "disabled"},
+		{Name: "empty", Config: &HeaderPolicyConfig{}},
+		{Name: "invalid frame options", Config: &HeaderPolicyConfig{FrameOptions: "ALLOW-FROM foo"}, Error: true},
+		{Name: "unknown route", Config: &HeaderPolicyConfig{Rules: []HeaderRule{
+			{Routes: []string{"foo"}, Direction: HeaderRuleRequest, Action: HeaderRuleRemove, Header: "Cookie"},
+		}}, Error: true},
+		{Name: "unknown action", Config: &HeaderPolicyConfig{Rules: []HeaderRule{
+			{Direction: HeaderRuleRequest, Action: "rename", Header: "Cookie"},
+		}}, Error: true},
+		{Name: "add without value", Config: &HeaderPolicyConfig{Rules: []HeaderRule{
+			{Direction: HeaderRuleResponse, Action: HeaderRuleAdd, Header: "X-Foo"},
+		}}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// DegradedMode determines how ws-proxy reports readiness while ws-manager is unreachable
+type DegradedMode string
+
+const (
+	// DegradedModeUnready reports ws-proxy as not ready while ws-manager is unreachable
+	DegradedModeUnready DegradedMode = "unready"
+	// DegradedModeServeCached keeps ws-proxy ready while ws-manager is unreachable, serving workspaces from the info cache
+	DegradedModeServeCached DegradedMode = "serveCached"
+)
+
+// HealthConfig configures the liveness and readiness endpoints
+type HealthConfig struct {
+	// DegradedMode determines readiness while ws-manager is unreachable. Defaults to DegradedModeUnready.
+	DegradedMode DegradedMode `json:"degradedMode,omitempty"`
+	// MaxStaleness is how long we serve cached workspace info in DegradedModeServeCached.
+	// Zero means there's no limit.
+	MaxStaleness util.Duration `json:"maxStaleness,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *HealthConfig) Validate() error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.DegradedMode, validation.In(DegradedModeUnready, DegradedModeServeCached)),
+		validation.Field(&c.MaxStaleness, validation.Min(util.Duration(0))),
+	)
+}
+
+// InfoProviderHealth describes the state of a workspace info provider
+type InfoProviderHealth struct {
+	// Connected is true if the info provider receives updates from ws-manager
+	Connected bool `json:"connected"`
+	// ReadOnly is true while the info provider serves workspaces from a frozen cache because ws-manager is unreachable
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// LastUpdate is when we last heard from ws-manager
+	LastUpdate *time.Time `json:"lastUpdate,omitempty"`
+	// CacheSize is the number of workspaces the info provider knows about
+	CacheSize int `json:"cacheSize"`
+	// StaleEntries is the number of workspaces we restored from a snapshot which ws-manager has not confirmed yet
+	StaleEntries int `json:"staleEntries,omitempty"`
+}
+
+// HealthStatus summarises the health of ws-proxy
+type HealthStatus string
+
+const (
+	// HealthStatusOK means ws-proxy is fully operational
+	HealthStatusOK HealthStatus = "ok"
+	// HealthStatusDegraded means ws-proxy serves requests, but cannot reach ws-manager
+	HealthStatusDegraded HealthStatus = "degraded"
+	// HealthStatusUnavailable means ws-proxy cannot serve requests
+	HealthStatusUnavailable HealthStatus = "unavailable"
+	// HealthStatusDraining means an operator asked ws-proxy to stop receiving new traffic
+	HealthStatusDraining HealthStatus = "draining"
+)
+
+// HealthReport is the JSON body of the liveness and readiness endpoints
+type HealthReport struct {
+	Status       HealthStatus        `json:"status"`
+	InfoProvider *InfoProviderHealth `json:"infoProvider,omitempty"`
+	Listeners    ListenerHealth      `json:"listeners"`
+}
+
+// ListenerHealth describes the state of the proxy's listener sockets
+type ListenerHealth struct {
+	Total     int `json:"total"`
+	Listening int `json:"listening"`
+	// Pending lists the addresses we do not listen on yet
+	Pending []string `json:"pending,omitempty"`
+}
+
+// HealthChecker serves the liveness and readiness endpoints of ws-proxy
+type HealthChecker struct {
+	Config       HealthConfig
+	InfoProvider interface{ Health() InfoProviderHealth }
+
+	mu        sync.RWMutex
+	listeners map[string]bool
+	draining  bool
+}
+
+// NewHealthChecker creates a new health checker
+func NewHealthChecker(cfg HealthConfig, infoProvider interface{ Health() InfoProviderHealth }) *HealthChecker {
+	if cfg.DegradedMode == "" {
+		cfg.DegradedMode = DegradedModeUnready
+	}
+	return &HealthChecker{
+		Config:       cfg,
+		InfoProvider: infoProvider,
+		listeners:    make(map[string]bool),
+	}
+}
+
+// ExpectListener registers a listener address which must be listening for ws-proxy to be alive
+func (h *HealthChecker) ExpectListener(addr string) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.listeners[addr]; !exists {
+		h.listeners[addr] = false
+	}
+}
+
+// ListenerUp marks a listener as listening
+func (h *HealthChecker) ListenerUp(addr string) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners[addr] = true
+}
+
+// SetDraining marks ws-proxy as draining. While draining ws-proxy reports not ready so that the load balancer
+// stops sending new traffic, but keeps serving the connections it has.
+func (h *HealthChecker) SetDraining(draining bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.draining = draining
+}
+
+// Draining returns true if ws-proxy is draining
+func (h *HealthChecker) Draining() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.draining
+}
+
+func (h *HealthChecker) listenerHealth() ListenerHealth {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	res := ListenerHealth{Total: len(h.listeners)}
+	for addr, up := range h.listeners {
+		if up {
+			res.Listening++
+		} else {
+			res.Pending = append(res.Pending, addr)
+		}
+	}
+	sort.Strings(res.Pending)
+	return res
+}
+
+// Live reports whether ws-proxy is alive, i.e. listens on all its sockets.
+// Liveness does not depend on ws-manager so that Kubernetes won't restart ws-proxy if ws-manager is down.
+func (h *HealthChecker) Live() (ok bool, report HealthReport) {
+	report.Listeners = h.listenerHealth()
+	if report.Listeners.Listening < report.Listeners.Total {
+		report.Status = HealthStatusUnavailable
+		return false, report
+	}
+
+	report.Status = HealthStatusOK
+	return true, report
+}
+
+// Ready reports whether ws-proxy can serve workspace requests
+func (h *HealthChecker) Ready() (ok bool, report HealthReport) {
+	ok, report = h.Live()
+	ip := h.InfoProvider.Health()
+	report.InfoProvider = &ip
+	if !ok {
+		return false, report
+	}
+	if h.Draining() {
+		report.Status = HealthStatusDraining
+		return false, report
+	}
+	if ip.Connected {
+		return true, report
+	}
+
+	report.Status = HealthStatusDegraded
+	if ip.ReadOnly {
+		// the frozen cache does not age - we serve the workspaces we know until ws-manager is back
+		return true, report
+	}
+	if h.Config.DegradedMode != DegradedModeServeCached || ip.LastUpdate == nil {
+		return false, report
+	}
+	if h.Config.MaxStaleness > 0 && time.Since(*ip.LastUpdate) > time.Duration(h.Config.MaxStaleness) {
+		report.Status = HealthStatusUnavailable
+		return false, report
+	}
+	return true, report
+}
+
+// proxyStatusHeader tells clients which cannot parse error pages, e.g. load balancers, why we rejected a request
+const proxyStatusHeader = "X-Gitpod-Proxy-Status"
+
+// notReadyRetryAfter is the Retry-After, in seconds, we advise while the info provider is not ready
+const notReadyRetryAfter = "5"
+
+// serveNotReady responds with a 503 and returns true if the info provider is not ready. Until we hear from ws-manager
+// we cannot tell a workspace which does not exist from one we don't know about yet.
+func serveNotReady(pages *ErrorPages, ip WorkspaceInfoProvider, resp http.ResponseWriter, req *http.Request) bool {
+	if infoProviderReady(ip) {
+		return false
+	}
+
+	getLog(req.Context()).Debug("workspace info provider is not ready - rejecting request")
+	resp.Header().Set("Retry-After", notReadyRetryAfter)
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.Header().Set(proxyStatusHeader, string(ErrorPageProxyNotReady))
+	serveErrorPage(pages, resp, req, ErrorPageProxyNotReady, http.StatusServiceUnavailable)
+	return true
+}
+
+// infoProviderReady returns false if the info provider can tell that it has not heard from ws-manager
+func infoProviderReady(ip WorkspaceInfoProvider) bool {
+	rp, ok := ip.(interface{ Ready() bool })
+	return !ok || rp.Ready()
+}
+
+// Handler serves /live and /ready. For backwards compatibility all other paths serve readiness.
+func (h *HealthChecker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/live", func(resp http.ResponseWriter, req *http.Request) {
+		serveHealthReport(resp, h.Live)
+	})
+	ready := func(resp http.ResponseWriter, req *http.Request) {
+		serveHealthReport(resp, h.Ready)
+	}
+	mux.HandleFunc("/ready", ready)
+	mux.HandleFunc("/", ready)
+	return mux
+}
+
+func serveHealthReport(resp http.ResponseWriter, check func() (bool, HealthReport)) {
+	ok, report := check()
+
+	resp.Header().Set("Content-Type", "application/json")
+	if ok {
+		resp.WriteHeader(http.StatusOK)
+	} else {
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}
+	err := json.NewEncoder(resp).Encode(report)
+	if err != nil {
+		log.WithError(err).Debug("cannot write health report")
+	}
+}
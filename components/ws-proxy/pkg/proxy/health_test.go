@@ -0,0 +1,227 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+type fakeInfoProviderHealth InfoProviderHealth
+
+func (f fakeInfoProviderHealth) Health() InfoProviderHealth { return InfoProviderHealth(f) }
+
+func TestHealthChecker(t *testing.T) {
+	recently := time.Now().Add(-1 * time.Minute)
+	longAgo := time.Now().Add(-1 * time.Hour)
+
+	tests := []struct {
+		Name         string
+		Config       HealthConfig
+		InfoProvider fakeInfoProviderHealth
+		Listening    bool
+		Draining     bool
+		Path         string
+		Status       int
+		Health       HealthStatus
+	}{
+		{
+			Name:         "live",
+			InfoProvider: fakeInfoProviderHealth{Connected: false},
+			Listening:    true,
+			Path:         "/live",
+			Status:       http.StatusOK,
+			Health:       HealthStatusOK,
+		},
+		{
+			Name:         "not listening",
+			InfoProvider: fakeInfoProviderHealth{Connected: true},
+			Path:         "/live",
+			Status:       http.StatusServiceUnavailable,
+			Health:       HealthStatusUnavailable,
+		},
+		{
+			Name:         "ready",
+			InfoProvider: fakeInfoProviderHealth{Connected: true, LastUpdate: &recently},
+			Listening:    true,
+			Path:         "/ready",
+			Status:       http.StatusOK,
+			Health:       HealthStatusOK,
+		},
+		{
+			Name:         "legacy readiness path",
+			InfoProvider: fakeInfoProviderHealth{Connected: true, LastUpdate: &recently},
+			Listening:    true,
+			Path:         "/",
+			Status:       http.StatusOK,
+			Health:       HealthStatusOK,
+		},
+		{
+			Name:         "ws-manager unreachable",
+			InfoProvider: fakeInfoProviderHealth{Connected: false, LastUpdate: &recently},
+			Listening:    true,
+			Path:         "/ready",
+			Status:       http.StatusServiceUnavailable,
+			Health:       HealthStatusDegraded,
+		},
+		{
+			Name:         "serve cached",
+			Config:       HealthConfig{DegradedMode: DegradedModeServeCached, MaxStaleness: util.Duration(10 * time.Minute)},
+			InfoProvider: fakeInfoProviderHealth{Connected: false, LastUpdate: &recently},
+			Listening:    true,
+			Path:         "/ready",
+			Status:       http.StatusOK,
+			Health:       HealthStatusDegraded,
+		},
+		{
+			Name:         "serve cached too stale",
+			Config:       HealthConfig{DegradedMode: DegradedModeServeCached, MaxStaleness: util.Duration(10 * time.Minute)},
+			InfoProvider: fakeInfoProviderHealth{Connected: false, LastUpdate: &longAgo},
+			Listening:    true,
+			Path:         "/ready",
+			Status:       http.StatusServiceUnavailable,
+			Health:       HealthStatusUnavailable,
+		},
+		{
+			Name:         "read-only",
+			Config:       HealthConfig{DegradedMode: DegradedModeUnready, MaxStaleness: util.Duration(10 * time.Minute)},
+			InfoProvider: fakeInfoProviderHealth{Connected: false, ReadOnly: true, LastUpdate: &longAgo},
+			Listening:    true,
+			Path:         "/ready",
+			Status:       http.StatusOK,
+			Health:       HealthStatusDegraded,
+		},
+		{
+			Name:         "serve cached never connected",
+			Config:       HealthConfig{DegradedMode: DegradedModeServeCached},
+			InfoProvider: fakeInfoProviderHealth{Connected: false},
+			Listening:    true,
+			Path:         "/ready",
+			Status:       http.StatusServiceUnavailable,
+			Health:       HealthStatusDegraded,
+		},
+		{
+			Name:         "draining",
+			InfoProvider: fakeInfoProviderHealth{Connected: true, LastUpdate: &recently},
+			Listening:    true,
+			Draining:     true,
+			Path:         "/ready",
+			Status:       http.StatusServiceUnavailable,
+			Health:       HealthStatusDraining,
+		},
+		{
+			Name:         "draining live",
+			InfoProvider: fakeInfoProviderHealth{Connected: true, LastUpdate: &recently},
+			Listening:    true,
+			Draining:     true,
+			Path:         "/live",
+			Status:       http.StatusOK,
+			Health:       HealthStatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			h := NewHealthChecker(test.Config, test.InfoProvider)
+			h.ExpectListener(":8080")
+			if test.Listening {
+				h.ListenerUp(":8080")
+			}
+			h.SetDraining(test.Draining)
+
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, httptest.NewRequest("GET", test.Path, nil))
+			if rec.Code != test.Status {
+				t.Errorf("unexpected status code: expected %d, got %d", test.Status, rec.Code)
+			}
+
+			var report HealthReport
+			err := json.Unmarshal(rec.Body.Bytes(), &report)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Status != test.Health {
+				t.Errorf("unexpected health status: expected %s, got %s", test.Health, report.Status)
+			}
+			expectedListeners := ListenerHealth{Total: 1, Listening: 1}
+			if !test.Listening {
+				expectedListeners = ListenerHealth{Total: 1, Pending: []string{":8080"}}
+			}
+			if diff := cmp.Diff(expectedListeners, report.Listeners); diff != "" {
+				t.Errorf("unexpected listener health (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type readinessInfoProvider struct {
+	fixedInfoProvider
+	ready bool
+}
+
+func (p *readinessInfoProvider) Ready() bool { return p.ready }
+
+func TestServeNotReady(t *testing.T) {
+	known := &WorkspaceInfo{WorkspaceID: "known", Auth: &api.WorkspaceAuthentication{Admission: api.AdmissionLevel_ADMIT_EVERYONE}}
+
+	tests := []struct {
+		Name        string
+		Ready       bool
+		WorkspaceID string
+		Status      int
+		Header      http.Header
+	}{
+		{Name: "not ready unknown workspace", WorkspaceID: "unknown", Status: http.StatusServiceUnavailable, Header: http.Header{
+			"Cache-Control":         {"no-store"},
+			"Retry-After":           {"5"},
+			"X-Gitpod-Proxy-Status": {"proxy-not-ready"},
+		}},
+		{Name: "not ready known workspace", WorkspaceID: "known", Status: http.StatusOK, Header: http.Header{}},
+		{Name: "ready unknown workspace", Ready: true, WorkspaceID: "unknown", Status: http.StatusNotFound, Header: http.Header{}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ip := &readinessInfoProvider{fixedInfoProvider: fixedInfoProvider{Infos: map[string]*WorkspaceInfo{"known": known}}, ready: test.Ready}
+			handler := WorkspaceAuthHandler("test-domain.com", ip, nil, nil, nil)(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				resp.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest("GET", "http://"+test.WorkspaceID+".test-domain.com/", nil)
+			req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: test.WorkspaceID})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("unexpected status: %d, expected %d", rec.Code, test.Status)
+			}
+			if diff := cmp.Diff(test.Header, rec.Header()); diff != "" {
+				t.Errorf("unexpected header (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInfoProviderNotReadyDuration(t *testing.T) {
+	p := NewRemoteWorkspaceInfoProvider(WorkspaceInfoProviderConfig{})
+	time.Sleep(10 * time.Millisecond)
+	if d := p.NotReadyDuration(); d < 10*time.Millisecond {
+		t.Errorf("unexpected not-ready duration of a cold info provider: %s", d)
+	}
+
+	p.mu.Lock()
+	p.ready = true
+	p.mu.Unlock()
+	if d := p.NotReadyDuration(); d != 0 {
+		t.Errorf("unexpected not-ready duration of a ready info provider: %s", d)
+	}
+}
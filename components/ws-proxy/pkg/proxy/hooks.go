@@ -0,0 +1,444 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// RouteEventType describes what happened to a workspace route
+type RouteEventType string
+
+const (
+	// RouteAdded means ws-proxy started serving a workspace
+	RouteAdded RouteEventType = "added"
+	// RouteUpdated means the public URL or exposed ports of a workspace changed
+	RouteUpdated RouteEventType = "updated"
+	// RouteRemoved means ws-proxy stopped serving a workspace
+	RouteRemoved RouteEventType = "removed"
+)
+
+const (
+	// routeHookSignatureHeader carries the HMAC-SHA256 of the webhook payload if the webhook has a secret
+	routeHookSignatureHeader = "X-Gitpod-Signature"
+	// routeHookEventHeader carries the event type of the webhook payload
+	routeHookEventHeader = "X-Gitpod-Event"
+
+	defaultRouteHookQueueSize = 1000
+	defaultRouteHookTimeout   = 10 * time.Second
+	defaultRouteHookRetries   = 3
+)
+
+// RouteHooksConfig configures the webhooks ws-proxy calls when workspace routes change, e.g. to provision DNS records
+// or firewall rules for workspaces in self-hosted installations.
+type RouteHooksConfig struct {
+	Webhooks []WebhookConfig `json:"webhooks"`
+	// QueueSize is the number of events we buffer per hook. If a hook cannot keep up, we drop events rather
+	// than hold up the workspace info cache. Defaults to 1000.
+	QueueSize int `json:"queueSize,omitempty"`
+}
+
+// WebhookConfig configures a webhook
+type WebhookConfig struct {
+	// Name identifies the webhook in logs and metrics
+	Name string `json:"name"`
+	// URL receives the events as JSON POST requests
+	URL string `json:"url"`
+	// Events limits the webhook to some event types (added, updated, removed). Empty sends all events.
+	Events []RouteEventType `json:"events,omitempty"`
+	// SecretFile contains the key we sign payloads with. The signature is sent as X-Gitpod-Signature: t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<payload>">.
+	SecretFile string `json:"secretFile,omitempty"`
+	// Timeout limits each delivery attempt. Defaults to 10 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+	// Retries is the number of times we retry failed deliveries with exponential backoff. Defaults to 3.
+	Retries *int `json:"retries,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *RouteHooksConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Webhooks, validation.Required),
+		validation.Field(&c.QueueSize, validation.Min(0)),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid route hooks config: %w", err)
+	}
+
+	names := make(map[string]struct{}, len(c.Webhooks))
+	for _, wh := range c.Webhooks {
+		if _, exists := names[wh.Name]; exists {
+			return xerrors.Errorf("invalid route hooks config: webhook %s exists more than once", wh.Name)
+		}
+		names[wh.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Validate validates the webhook
+func (c WebhookConfig) Validate() error {
+	err := validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required),
+		validation.Field(&c.URL, validation.Required, is.URL),
+		validation.Field(&c.Events, validation.Each(validation.In(RouteAdded, RouteUpdated, RouteRemoved))),
+		validation.Field(&c.SecretFile, validation.By(validateOptionalFileExists)),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return err
+	}
+	if c.Retries != nil && *c.Retries < 0 {
+		return xerrors.Errorf("retries must not be negative")
+	}
+	return nil
+}
+
+// RouteEvent is sent to route hooks when a workspace route changes
+type RouteEvent struct {
+	Type        RouteEventType `json:"type"`
+	Time        time.Time      `json:"time"`
+	WorkspaceID string         `json:"workspaceId"`
+	InstanceID  string         `json:"instanceId"`
+	// URL is the public URL of the workspace's IDE
+	URL   string           `json:"url"`
+	Ports []RouteEventPort `json:"ports,omitempty"`
+}
+
+// RouteEventPort is an exposed port of a workspace route
+type RouteEventPort struct {
+	Port       uint32 `json:"port"`
+	URL        string `json:"url"`
+	Visibility string `json:"visibility"`
+}
+
+// RouteHook is called for every route event the hook subscribed to. Hooks are called one event at a time
+// in the order the events happened. Embedders can register their own hooks in addition to webhooks.
+type RouteHook interface {
+	OnRouteEvent(ctx context.Context, evt RouteEvent) error
+}
+
+// RouteHookFunc is a RouteHook implemented by a function
+type RouteHookFunc func(ctx context.Context, evt RouteEvent) error
+
+// OnRouteEvent calls f
+func (f RouteHookFunc) OnRouteEvent(ctx context.Context, evt RouteEvent) error {
+	return f(ctx, evt)
+}
+
+// RouteHooks calls hooks when workspace routes change
+type RouteHooks struct {
+	queueSize int
+
+	mu      sync.RWMutex
+	hooks   []*routeHook
+	stopped bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	metrics struct {
+		events  *prometheus.CounterVec
+		dropped *prometheus.CounterVec
+	}
+}
+
+type routeHook struct {
+	Name    string
+	Hook    RouteHook
+	Events  map[RouteEventType]struct{}
+	Timeout time.Duration
+	Retries int
+
+	queue chan RouteEvent
+}
+
+// NewRouteHooks creates the webhooks of the configuration. Call Start to start delivering events.
+func NewRouteHooks(cfg RouteHooksConfig) (*RouteHooks, error) {
+	res := &RouteHooks{queueSize: cfg.QueueSize}
+	if res.queueSize == 0 {
+		res.queueSize = defaultRouteHookQueueSize
+	}
+	res.ctx, res.cancel = context.WithCancel(context.Background())
+	res.metrics.events = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "route_hook_events_total",
+		Help: "Route events delivered to hooks by hook, event type and outcome",
+	}, []string{"hook", "type", "outcome"})
+	res.metrics.dropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "route_hook_events_dropped_total",
+		Help: "Route events dropped because the hook could not keep up",
+	}, []string{"hook"})
+
+	for _, wh := range cfg.Webhooks {
+		hook, err := newWebhook(wh)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create webhook %s: %w", wh.Name, err)
+		}
+		retries := defaultRouteHookRetries
+		if wh.Retries != nil {
+			retries = *wh.Retries
+		}
+		timeout := time.Duration(wh.Timeout)
+		if timeout == 0 {
+			timeout = defaultRouteHookTimeout
+		}
+		res.add(&routeHook{Name: wh.Name, Hook: hook, Timeout: timeout, Retries: retries}, wh.Events)
+	}
+	return res, nil
+}
+
+// Register adds a hook which is called for the given event types, or all events if none are given.
+// Hooks must be registered before Start.
+func (h *RouteHooks) Register(name string, hook RouteHook, events ...RouteEventType) {
+	h.add(&routeHook{Name: name, Hook: hook, Timeout: defaultRouteHookTimeout}, events)
+}
+
+func (h *RouteHooks) add(hook *routeHook, events []RouteEventType) {
+	if len(events) > 0 {
+		hook.Events = make(map[RouteEventType]struct{}, len(events))
+		for _, e := range events {
+			hook.Events[e] = struct{}{}
+		}
+	}
+	hook.queue = make(chan RouteEvent, h.queueSize)
+
+	h.mu.Lock()
+	h.hooks = append(h.hooks, hook)
+	h.mu.Unlock()
+}
+
+// RegisterMetrics registers the delivery metrics
+func (h *RouteHooks) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{h.metrics.events, h.metrics.dropped} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start starts delivering events to the hooks
+func (h *RouteHooks) Start() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, hook := range h.hooks {
+		h.wg.Add(1)
+		go h.deliver(hook)
+	}
+}
+
+// Close stops delivering events. Events which are still queued are dropped.
+func (h *RouteHooks) Close() {
+	h.mu.Lock()
+	if h.stopped {
+		h.mu.Unlock()
+		return
+	}
+	h.stopped = true
+	h.cancel()
+	for _, hook := range h.hooks {
+		close(hook.queue)
+	}
+	h.mu.Unlock()
+
+	h.wg.Wait()
+}
+
+// OnChange turns a change of the workspace info cache into a route event. old is nil for new workspaces,
+// new is nil for removed ones. Changes which affect neither the public URL nor the exposed ports are ignored.
+// OnChange never blocks, so that it can be called while the cache is locked.
+func (h *RouteHooks) OnChange(old, new *WorkspaceInfo) {
+	var evt RouteEvent
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		evt = newRouteEvent(RouteAdded, new)
+	case new == nil:
+		evt = newRouteEvent(RouteRemoved, old)
+	case old.InstanceID != new.InstanceID:
+		// a new instance of the workspace replaced the old one without us seeing it stop
+		h.publish(newRouteEvent(RouteRemoved, old))
+		evt = newRouteEvent(RouteAdded, new)
+	case routeEqual(old, new):
+		return
+	default:
+		evt = newRouteEvent(RouteUpdated, new)
+	}
+	h.publish(evt)
+}
+
+func (h *RouteHooks) publish(evt RouteEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.stopped {
+		return
+	}
+
+	for _, hook := range h.hooks {
+		if hook.Events != nil {
+			if _, ok := hook.Events[evt.Type]; !ok {
+				continue
+			}
+		}
+		select {
+		case hook.queue <- evt:
+		default:
+			h.metrics.dropped.WithLabelValues(hook.Name).Inc()
+			log.WithFields(log.OWI("", evt.WorkspaceID, evt.InstanceID)).WithField("hook", hook.Name).WithField("type", evt.Type).Warn("route hook cannot keep up - dropping event")
+		}
+	}
+}
+
+func (h *RouteHooks) deliver(hook *routeHook) {
+	defer h.wg.Done()
+
+	for evt := range hook.queue {
+		err := h.call(hook, evt)
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+			if h.ctx.Err() == nil {
+				log.WithFields(log.OWI("", evt.WorkspaceID, evt.InstanceID)).WithField("hook", hook.Name).WithField("type", evt.Type).WithError(err).Warn("route hook failed")
+			}
+		}
+		h.metrics.events.WithLabelValues(hook.Name, string(evt.Type), outcome).Inc()
+	}
+}
+
+// call calls the hook and retries with exponential backoff until the hook succeeds or we run out of retries
+func (h *RouteHooks) call(hook *routeHook, evt RouteEvent) (err error) {
+	backoff := 1 * time.Second
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(h.ctx, hook.Timeout)
+		err = hook.Hook.OnRouteEvent(ctx, evt)
+		cancel()
+		if err == nil || attempt >= hook.Retries {
+			return err
+		}
+
+		select {
+		case <-h.ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func newRouteEvent(tpe RouteEventType, info *WorkspaceInfo) RouteEvent {
+	evt := RouteEvent{
+		Type:        tpe,
+		Time:        time.Now(),
+		WorkspaceID: info.WorkspaceID,
+		InstanceID:  info.InstanceID,
+		URL:         info.URL,
+	}
+	for _, p := range info.Ports {
+		evt.Ports = append(evt.Ports, RouteEventPort{
+			Port:       p.Port,
+			URL:        p.Url,
+			Visibility: strings.ToLower(strings.TrimPrefix(p.Visibility.String(), "PORT_VISIBILITY_")),
+		})
+	}
+	return evt
+}
+
+// routeEqual returns true if a and b are served on the same public URLs
+func routeEqual(a, b *WorkspaceInfo) bool {
+	if a.URL != b.URL || len(a.Ports) != len(b.Ports) {
+		return false
+	}
+	for i := range a.Ports {
+		pa, pb := a.Ports[i], b.Ports[i]
+		if pa.Port != pb.Port || pa.Url != pb.Url || pa.Visibility != pb.Visibility {
+			return false
+		}
+	}
+	return true
+}
+
+// webhook posts route events to a URL
+type webhook struct {
+	URL    string
+	Secret []byte
+	Client *http.Client
+}
+
+func newWebhook(cfg WebhookConfig) (*webhook, error) {
+	res := &webhook{URL: cfg.URL, Client: &http.Client{}}
+	if cfg.SecretFile != "" {
+		secret, err := ioutil.ReadFile(cfg.SecretFile)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read secret: %w", err)
+		}
+		res.Secret = bytes.TrimSpace(secret)
+	}
+	return res, nil
+}
+
+// OnRouteEvent posts the event to the webhook URL
+func (wh *webhook) OnRouteEvent(ctx context.Context, evt RouteEvent) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(routeHookEventHeader, string(evt.Type))
+	if len(wh.Secret) > 0 {
+		req.Header.Set(routeHookSignatureHeader, signRouteEvent(wh.Secret, body, time.Now()))
+	}
+
+	resp, err := wh.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return xerrors.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// signRouteEvent produces the value of the webhook signature header
+func signRouteEvent(key []byte, body []byte, now time.Time) string {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/xerrors"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestRouteHooksOnChange(t *testing.T) {
+	base := &WorkspaceInfo{
+		WorkspaceID: "amaranth-smelt-9ba20cc1",
+		InstanceID:  "e63cb5ff-f4e4-4065-8554-b431a32c0000",
+		URL:         "https://amaranth-smelt-9ba20cc1.ws-eu01.gitpod.io",
+		Generation:  1,
+	}
+	withPort := *base
+	withPort.Generation = 2
+	withPort.Ports = []PortInfo{{
+		PortSpec:   wsapi.PortSpec{Port: 8080, Url: "https://8080-amaranth-smelt-9ba20cc1.ws-eu01.gitpod.io", Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC},
+		PublicPort: "10001",
+	}}
+	nextGeneration := withPort
+	nextGeneration.Generation = 3
+	nextGeneration.IPAddress = "10.0.0.1"
+	newInstance := nextGeneration
+	newInstance.InstanceID = "e63cb5ff-f4e4-4065-8554-b431a32c0001"
+
+	var events []RouteEvent
+	hooks, err := NewRouteHooks(RouteHooksConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	hooks.Register("test", RouteHookFunc(func(ctx context.Context, evt RouteEvent) error {
+		events = append(events, evt)
+		if len(events) == 5 {
+			close(done)
+		}
+		return nil
+	}))
+	hooks.Start()
+
+	hooks.OnChange(nil, base)
+	hooks.OnChange(base, &withPort)
+	// neither the URL nor the ports changed - no event
+	hooks.OnChange(&withPort, &nextGeneration)
+	hooks.OnChange(&nextGeneration, &newInstance)
+	hooks.OnChange(&newInstance, nil)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for events, got %d", len(events))
+	}
+	hooks.Close()
+
+	port := []RouteEventPort{{Port: 8080, URL: "https://8080-amaranth-smelt-9ba20cc1.ws-eu01.gitpod.io", Visibility: "public"}}
+	expectation := []RouteEvent{
+		{Type: RouteAdded, WorkspaceID: base.WorkspaceID, InstanceID: base.InstanceID, URL: base.URL},
+		{Type: RouteUpdated, WorkspaceID: base.WorkspaceID, InstanceID: base.InstanceID, URL: base.URL, Ports: port},
+		{Type: RouteRemoved, WorkspaceID: base.WorkspaceID, InstanceID: base.InstanceID, URL: base.URL, Ports: port},
+		{Type: RouteAdded, WorkspaceID: base.WorkspaceID, InstanceID: newInstance.InstanceID, URL: base.URL, Ports: port},
+		{Type: RouteRemoved, WorkspaceID: base.WorkspaceID, InstanceID: newInstance.InstanceID, URL: base.URL, Ports: port},
+	}
+	if diff := cmp.Diff(expectation, events, cmpopts.IgnoreFields(RouteEvent{}, "Time")); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+}
+
+func TestRouteHooksCache(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	prov := NewRemoteWorkspaceInfoProvider(WorkspaceInfoProviderConfig{WsManagerAddr: "target"})
+	prov.OnChange = func(old, new *WorkspaceInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case old == nil:
+			events = append(events, "added "+new.WorkspaceID)
+		case new == nil:
+			events = append(events, "removed "+old.WorkspaceID)
+		default:
+			events = append(events, "updated "+new.WorkspaceID)
+		}
+	}
+
+	prov.cache.Restore([]*WorkspaceInfo{{WorkspaceID: "restored", Stale: true}})
+	prov.cache.Insert(&WorkspaceInfo{WorkspaceID: "foo", Generation: 1})
+	prov.cache.Reinit([]*WorkspaceInfo{
+		{WorkspaceID: "foo", Generation: 1},
+		{WorkspaceID: "bar", Generation: 1},
+	})
+	prov.cache.Delete("bar", 1)
+
+	expectation := []string{"added foo", "added bar", "removed restored", "removed bar"}
+	if diff := cmp.Diff(expectation, events); diff != "" {
+		t.Errorf("unexpected changes (-want +got):\n%s", diff)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secretFile, []byte("s3cr3t\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type delivery struct {
+		Event     string
+		Signature string
+		Body      RouteEvent
+	}
+	var (
+		mu         sync.Mutex
+		deliveries []delivery
+		attempts   int
+		received   = make(chan struct{})
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			// the first attempt fails - we expect a retry
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var d delivery
+		d.Event = r.Header.Get(routeHookEventHeader)
+		d.Signature = r.Header.Get(routeHookSignatureHeader)
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &d.Body)
+		deliveries = append(deliveries, d)
+		close(received)
+	}))
+	defer srv.Close()
+
+	retries := 1
+	hooks, err := NewRouteHooks(RouteHooksConfig{Webhooks: []WebhookConfig{
+		{Name: "dns", URL: srv.URL, Events: []RouteEventType{RouteRemoved}, SecretFile: secretFile, Retries: &retries},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks.Start()
+	defer hooks.Close()
+
+	info := &WorkspaceInfo{WorkspaceID: "amaranth-smelt-9ba20cc1", URL: "https://amaranth-smelt-9ba20cc1.ws-eu01.gitpod.io"}
+	// the webhook is not interested in added routes
+	hooks.OnChange(nil, info)
+	hooks.OnChange(info, nil)
+
+	select {
+	case <-received:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the webhook")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(deliveries))
+	}
+	d := deliveries[0]
+	if d.Event != string(RouteRemoved) || d.Body.Type != RouteRemoved || d.Body.WorkspaceID != info.WorkspaceID {
+		t.Errorf("unexpected delivery: %+v", d)
+	}
+	if !strings.HasPrefix(d.Signature, "t=") || !strings.Contains(d.Signature, ",v1=") {
+		t.Errorf("unexpected signature: %s", d.Signature)
+	}
+}
+
+func TestRouteHooksDropEvents(t *testing.T) {
+	hooks, err := NewRouteHooks(RouteHooksConfig{QueueSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks.Register("slow", RouteHookFunc(func(ctx context.Context, evt RouteEvent) error {
+		return xerrors.Errorf("never called")
+	}))
+	// we don't start the hooks, hence the queue fills up
+
+	info := &WorkspaceInfo{WorkspaceID: "amaranth-smelt-9ba20cc1"}
+	hooks.OnChange(nil, info)
+	hooks.OnChange(info, nil)
+
+	if n := testutil.ToFloat64(hooks.metrics.dropped.WithLabelValues("slow")); n != 1 {
+		t.Errorf("expected one dropped event, got %v", n)
+	}
+}
+
+func TestRouteHooksConfigValidate(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		Name   string
+		Config *RouteHooksConfig
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "valid", Config: &RouteHooksConfig{Webhooks: []WebhookConfig{{Name: "dns", URL: "http://dns-operator:8080/hooks", Events: []RouteEventType{RouteAdded}}}}},
+		{Name: "no webhooks", Config: &RouteHooksConfig{}, Error: true},
+		{Name: "no name", Config: &RouteHooksConfig{Webhooks: []WebhookConfig{{URL: "http://dns-operator"}}}, Error: true},
+		{Name: "invalid URL", Config: &RouteHooksConfig{Webhooks: []WebhookConfig{{Name: "dns", URL: "not a url"}}}, Error: true},
+		{Name: "unknown event", Config: &RouteHooksConfig{Webhooks: []WebhookConfig{{Name: "dns", URL: "http://dns-operator", Events: []RouteEventType{"renamed"}}}}, Error: true},
+		{Name: "negative retries", Config: &RouteHooksConfig{Webhooks: []WebhookConfig{{Name: "dns", URL: "http://dns-operator", Retries: &negative}}}, Error: true},
+		{Name: "duplicate name", Config: &RouteHooksConfig{Webhooks: []WebhookConfig{{Name: "dns", URL: "http://a"}, {Name: "dns", URL: "http://b"}}}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
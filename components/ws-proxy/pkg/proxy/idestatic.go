@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+)
+
+// IDEStaticConfig serves static IDE resources straight from the IDE image using blobserve,
+// rather than from the workspace. If blobserve does not have a resource, we serve it from the workspace.
+type IDEStaticConfig struct {
+	// Host is the blobserve host static IDE resources are served from. Defaults to the blobserver host.
+	Host string `json:"host,omitempty"`
+	// Paths maps request paths to paths within the IDE image
+	Paths []IDEStaticPath `json:"paths"`
+}
+
+// IDEStaticPath maps a request path prefix to a path within the IDE image
+type IDEStaticPath struct {
+	// Prefix is the request path prefix, e.g. /static
+	Prefix string `json:"prefix"`
+	// ImagePath replaces the prefix to form the path within the IDE image, e.g. /out/static. Defaults to the prefix.
+	ImagePath string `json:"imagePath,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *IDEStaticConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Paths, validation.Required),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid IDE static config: %w", err)
+	}
+	return nil
+}
+
+// Validate validates the path mapping
+func (p IDEStaticPath) Validate() error {
+	isAbsPath := validation.By(func(value interface{}) error {
+		if s, _ := value.(string); s != "" && !strings.HasPrefix(s, "/") {
+			return xerrors.Errorf("must start with /")
+		}
+		return nil
+	})
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Prefix, validation.Required, isAbsPath),
+		validation.Field(&p.ImagePath, isAbsPath),
+	)
+}
+
+// HandleIDEStaticRoute serves static IDE resources from blobserve, and falls back to the workspace if blobserve misses.
+// Like the root route, blobserve content needs no authentication - the workspace fallback does.
+func (ir *ideRoutes) HandleIDEStaticRoute(route *mux.Route, mapping IDEStaticPath) {
+	r := route.Subrouter()
+	r.Use(logRouteHandlerHandler("HandleIDEStaticRoute"))
+	r.Use(ir.Config.CorsHandler)
+	r.Use(ir.workspaceMustExistHandler)
+	r.Use(ideImageCacheHandler)
+	r.Use(ideStaticPathHandler(mapping))
+
+	workspaceIDEPass := ir.Config.WorkspaceAuthHandler(
+		routePass(ir.Config, ir.InfoProvider, RouteIDE),
+	)
+	rc := ir.Config.Config.Routes.Get(RouteIDE)
+	r.NewRoute().HandlerFunc(routeHandler(rc, proxyPass(ir.Config, ideStaticResolver, withRoute(rc), func(h *proxyPassConfig) {
+		h.Transport = &blobserveTransport{
+			transport: h.Transport,
+			Config:    ir.Config.Config,
+			// we serve static resources from the workspace origin - redirects to the blobserve origin would break relative imports
+			resolveImage: func(req *http.Request) string { return "" },
+		}
+	}, withHTTPErrorHandler(restoreIDEStaticPath(workspaceIDEPass)), withIDEImageCaching())))
+}
+
+type ideStaticPathContextKey struct{}
+
+// ideStaticPathHandler maps the request path to the path within the IDE image
+func ideStaticPathHandler(mapping IDEStaticPath) mux.MiddlewareFunc {
+	imagePath := mapping.ImagePath
+	if imagePath == "" {
+		imagePath = mapping.Prefix
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			original := req.URL.Path
+			req.URL.Path = imagePath + strings.TrimPrefix(original, mapping.Prefix)
+			req.URL.RawPath = ""
+			h.ServeHTTP(resp, req.WithContext(context.WithValue(req.Context(), ideStaticPathContextKey{}, original)))
+		})
+	}
+}
+
+// restoreIDEStaticPath restores the request path before we fall back to the workspace
+func restoreIDEStaticPath(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if original, ok := req.Context().Value(ideStaticPathContextKey{}).(string); ok {
+			req.URL.Path = original
+			req.URL.RawPath = ""
+		}
+		h.ServeHTTP(resp, req)
+	})
+}
+
+func ideStaticResolver(config *Config, req *http.Request) (*url.URL, error) {
+	info := getWorkspaceInfoFromContext(req.Context())
+	if info == nil || info.IDEImage == "" {
+		return nil, xerrors.Errorf("no workspace information available - cannot resolve IDE image")
+	}
+
+	host := config.BlobServer.Host
+	if static := config.BlobServer.IDEStatic; static != nil && static.Host != "" {
+		host = static.Host
+	}
+	return &url.URL{
+		Scheme: config.BlobServer.Scheme,
+		Host:   host,
+		Path:   "/" + info.IDEImage,
+	}, nil
+}
+
+// ideImageETag identifies the content of an IDE image. Images which are not pinned to a digest
+// can change their content without changing their name, hence we cannot identify their content.
+func ideImageETag(image string) string {
+	idx := strings.LastIndex(image, "@")
+	if idx < 0 {
+		return ""
+	}
+	return `"` + image[idx+1:] + `"`
+}
+
+// ideImageCacheHandler answers revalidation requests for static resources of an IDE image which has not changed
+// since the browser cached them, without asking blobserve.
+func ideImageCacheHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		etag := ideImageETag(getWorkspaceInfoFromContext(req.Context()).IDEImage)
+		if etag == "" || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		for _, m := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+			if strings.TrimSpace(m) == etag {
+				resp.Header().Set("ETag", etag)
+				resp.Header().Set("Cache-Control", "no-cache")
+				resp.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		h.ServeHTTP(resp, req)
+	})
+}
+
+// withIDEImageCaching makes browsers revalidate static IDE resources on every use. Resources of IDE images pinned to
+// a digest carry the digest as ETag, so that a new IDE image busts the cache while revalidation remains cheap otherwise.
+func withIDEImageCaching() proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		cfg.appendResponseHandler(func(resp *http.Response, req *http.Request) error {
+			resp.Header.Set("Cache-Control", "no-cache")
+
+			info := getWorkspaceInfoFromContext(req.Context())
+			if info == nil {
+				return nil
+			}
+			if etag := ideImageETag(info.IDEImage); etag != "" {
+				resp.Header.Set("ETag", etag)
+				resp.Header.Del("Last-Modified")
+			}
+			return nil
+		})
+	}
+}
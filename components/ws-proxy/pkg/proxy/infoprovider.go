@@ -6,17 +6,23 @@ package proxy
 
 import (
 	"context"
+	"crypto/subtle"
 	"io"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
 	"github.com/gitpod-io/gitpod/common-go/util"
 	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
 )
@@ -41,8 +47,27 @@ type WorkspaceInfoProvider interface {
 
 // WorkspaceInfoProviderConfig configures a WorkspaceInfoProvider
 type WorkspaceInfoProviderConfig struct {
-	WsManagerAddr     string        `json:"wsManagerAddr"`
-	ReconnectInterval util.Duration `json:"reconnectInterval"`
+	WsManagerAddr     string              `json:"wsManagerAddr"`
+	ReconnectInterval util.Duration       `json:"reconnectInterval"`
+	TLS               *WsManagerTLSConfig `json:"tls,omitempty"`
+
+	// MaxWaiters limits the number of requests that concurrently wait for workspace info to arrive.
+	// Defaults to 10000.
+	MaxWaiters int `json:"maxWaiters,omitempty"`
+	// MaxWaitersPerWorkspace limits the number of requests that concurrently wait for the info of a
+	// single workspace to arrive. Defaults to 500.
+	MaxWaitersPerWorkspace int `json:"maxWaitersPerWorkspace,omitempty"`
+
+	// SnapshotFile, if set, is where we periodically persist the workspace info cache. We load the snapshot
+	// at startup so that we can serve workspaces before we have reached ws-manager.
+	SnapshotFile string `json:"snapshotFile,omitempty"`
+	// SnapshotInterval is how often we persist the workspace info cache. Defaults to 30 seconds.
+	SnapshotInterval util.Duration `json:"snapshotInterval,omitempty"`
+	// SnapshotMaxAge is the age beyond which we ignore a snapshot at startup. Defaults to one hour.
+	SnapshotMaxAge util.Duration `json:"snapshotMaxAge,omitempty"`
+
+	// ReadOnly, if set, freezes the workspace info cache once ws-manager has been unreachable for a while
+	ReadOnly *ReadOnlyConfig `json:"readOnly,omitempty"`
 }
 
 // Validate validates the configuration to catch issues during startup and not at runtime
@@ -53,8 +78,19 @@ func (c *WorkspaceInfoProviderConfig) Validate() error {
 
 	err := validation.ValidateStruct(c,
 		validation.Field(&c.WsManagerAddr, validation.Required),
+		validation.Field(&c.MaxWaiters, validation.Min(0)),
+		validation.Field(&c.MaxWaitersPerWorkspace, validation.Min(0)),
+		validation.Field(&c.SnapshotInterval, validation.Min(util.Duration(0))),
+		validation.Field(&c.SnapshotMaxAge, validation.Min(util.Duration(0))),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	err = c.ReadOnly.Validate()
+	if err != nil {
+		return err
+	}
+	return c.TLS.Validate()
 }
 
 // WorkspaceInfo is all the infos ws-proxy needs to know about a workspace
@@ -65,11 +101,33 @@ type WorkspaceInfo struct {
 
 	IDEImage string
 
+	// Experiments maps the experiments the workspace takes part in to the variant it was assigned
+	Experiments map[string]string `json:",omitempty"`
+
 	// (parsed from URL)
 	IDEPublicPort string
 
 	Ports []PortInfo
 	Auth  *wsapi.WorkspaceAuthentication
+
+	// IPAddress is the IP of the workspace pod. Empty if unknown.
+	IPAddress string
+
+	// Generation is the generation of the status this info was derived from. Zero means unknown.
+	Generation uint64
+
+	// OwnerTokenHash is the SHA256 of the owner token. It's set instead of Auth.OwnerToken if the info comes from a snapshot.
+	OwnerTokenHash string `json:",omitempty"`
+	// Stale is true if the info comes from a snapshot and ws-manager has not confirmed it yet
+	Stale bool `json:"-"`
+}
+
+// IsOwnerToken returns true if tkn is the owner token of the workspace instance
+func (info *WorkspaceInfo) IsOwnerToken(tkn string) bool {
+	if info.OwnerTokenHash != "" {
+		return subtle.ConstantTimeCompare([]byte(hashOwnerToken(tkn)), []byte(info.OwnerTokenHash)) == 1
+	}
+	return tkn == info.Auth.OwnerToken
 }
 
 // PortInfo contains all information ws-proxy needs to know about a workspace port
@@ -84,11 +142,38 @@ type PortInfo struct {
 type RemoteWorkspaceInfoProvider struct {
 	Config WorkspaceInfoProviderConfig
 	Dialer WSManagerDialer
-
-	stop  chan struct{}
-	ready bool
-	mu    sync.Mutex
-	cache *workspaceInfoCache
+	// OnStatus, if set, is called for every workspace status we receive from ws-manager
+	OnStatus func(status *wsapi.WorkspaceStatus)
+	// OnChange, if set, is called for every workspace ws-manager adds to, updates in or removes from the cache.
+	// old is nil for new workspaces, new is nil for removed ones. Workspaces restored from a snapshot are not reported.
+	// OnChange is called while the cache is locked and must not block.
+	OnChange func(old, new *WorkspaceInfo)
+
+	stop        chan struct{}
+	ready       bool
+	client      wsapi.WorkspaceManagerClient
+	lastUpdate  time.Time
+	maintenance *MaintenanceInfo
+	mu          sync.Mutex
+	cache       *workspaceInfoCache
+
+	// notReadySince is when we lost the connection to ws-manager, or when we were created if we never connected
+	notReadySince time.Time
+
+	// snapshotVersion is the cache version we last persisted
+	snapshotVersion uint64
+
+	// failures counts the consecutive failed attempts to reach ws-manager
+	failures int
+	// readOnly is true while the cache is frozen because we failed to reach ws-manager too often
+	readOnly bool
+	// degradedUntil is when the degraded mode the anomaly detector asked for ends. The cache is frozen until then.
+	degradedUntil time.Time
+
+	// cancelListen ends the current subscription to ws-manager
+	cancelListen context.CancelFunc
+	// reconnecting is true if the subscription ended because we were asked to reconnect
+	reconnecting bool
 }
 
 // WSManagerDialer dials out to a ws-manager instance
@@ -96,12 +181,23 @@ type WSManagerDialer func(target string) (io.Closer, wsapi.WorkspaceManagerClien
 
 // NewRemoteWorkspaceInfoProvider creates a fresh WorkspaceInfoProvider
 func NewRemoteWorkspaceInfoProvider(config WorkspaceInfoProviderConfig) *RemoteWorkspaceInfoProvider {
-	return &RemoteWorkspaceInfoProvider{
+	p := &RemoteWorkspaceInfoProvider{
 		Config: config,
 		Dialer: defaultWsmanagerDialer,
-		cache:  newWorkspaceInfoCache(),
+		cache:  newWorkspaceInfoCache(config.MaxWaiters, config.MaxWaitersPerWorkspace),
 		stop:   make(chan struct{}),
+
+		notReadySince: time.Now(),
+	}
+	p.cache.onChange = func(old, new *WorkspaceInfo) {
+		if p.OnChange != nil {
+			p.OnChange(old, new)
+		}
 	}
+	if config.TLS != nil {
+		p.Dialer = newTLSWsmanagerDialer(*config.TLS, p.stop)
+	}
+	return p
 }
 
 // Close prevents the info provider from connecting
@@ -129,6 +225,7 @@ func (p *RemoteWorkspaceInfoProvider) Run() (err error) {
 	target := p.Config.WsManagerAddr
 	conn, client, err := p.Dialer(target)
 	if err != nil {
+		p.recordFailure()
 		return xerrors.Errorf("error while connecting to ws-manager: %w", err)
 	}
 
@@ -137,27 +234,43 @@ func (p *RemoteWorkspaceInfoProvider) Run() (err error) {
 	defer cancel()
 	infos, err := p.fetchInitialWorkspaceInfo(ctx, client)
 	if err != nil {
+		p.recordFailure()
 		return err
 	}
-	p.cache.Reinit(infos)
+	diff := p.cache.Reinit(infos)
+	log.WithField("diff", diff).Debug("initialized workspace info cache")
+	p.markUpdated()
 
 	// maintain connection and stream workspace statuus
 	go func(conn io.Closer, client wsapi.WorkspaceManagerClient) {
 		for {
 			p.mu.Lock()
 			p.ready = true
+			p.client = client
+			p.notReadySince = time.Time{}
 			p.mu.Unlock()
 
 			err := p.listen(client)
-			if xerrors.Is(err, io.EOF) {
+			p.mu.Lock()
+			reconnecting := p.reconnecting
+			p.reconnecting = false
+			p.mu.Unlock()
+			if reconnecting {
+				log.Info("reconnecting to ws-manager on request")
+			} else if xerrors.Is(err, io.EOF) {
 				log.Warn("ws-manager closed the connection, reconnecting after timeout...")
 			} else if err != nil {
 				log.WithError(err).Warnf("error while listening for workspace status updates, reconnecting after timeout")
 			}
 
 			conn.Close()
+			if !reconnecting {
+				p.recordFailure()
+			}
 			p.mu.Lock()
 			p.ready = false
+			p.client = nil
+			p.notReadySince = time.Now()
 			p.mu.Unlock()
 
 			var stop bool
@@ -175,6 +288,7 @@ func (p *RemoteWorkspaceInfoProvider) Run() (err error) {
 
 				conn, client, err = p.Dialer(target)
 				if err != nil {
+					p.recordFailure()
 					log.WithError(err).Warnf("error while connecting to ws-manager, reconnecting after timeout...")
 					continue
 				}
@@ -194,6 +308,113 @@ func (p *RemoteWorkspaceInfoProvider) Ready() bool {
 	return p.ready
 }
 
+// NotReadyDuration returns how long the info provider has not been ready, or zero if it is ready
+func (p *RemoteWorkspaceInfoProvider) NotReadyDuration() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ready {
+		return 0
+	}
+	return time.Since(p.notReadySince)
+}
+
+// ReportProxyActivity reports the connections we have open to workspaces to the ws-manager we're connected to
+func (p *RemoteWorkspaceInfoProvider) ReportProxyActivity(ctx context.Context, req *wsapi.ReportProxyActivityRequest) (*wsapi.ReportProxyActivityResponse, error) {
+	p.mu.Lock()
+	client := p.client
+	p.mu.Unlock()
+
+	if client == nil {
+		return nil, xerrors.Errorf("not connected to ws-manager")
+	}
+	return client.ReportProxyActivity(ctx, req)
+}
+
+// ControlPort changes a workspace port through the ws-manager we're connected to
+func (p *RemoteWorkspaceInfoProvider) ControlPort(ctx context.Context, req *wsapi.ControlPortRequest) (*wsapi.ControlPortResponse, error) {
+	p.mu.Lock()
+	client := p.client
+	p.mu.Unlock()
+
+	if client == nil {
+		return nil, xerrors.Errorf("not connected to ws-manager")
+	}
+	return client.ControlPort(ctx, req)
+}
+
+// RegisterMetrics registers the readiness metrics of the info provider
+func (p *RemoteWorkspaceInfoProvider) RegisterMetrics(reg prometheus.Registerer) error {
+	err := reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "info_provider_not_ready_seconds",
+		Help: "Time the workspace info provider has not been connected to ws-manager, zero while it is connected",
+	}, func() float64 {
+		return p.NotReadyDuration().Seconds()
+	}))
+	if err != nil {
+		return err
+	}
+	return reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "info_provider_read_only",
+		Help: "One while the workspace info provider serves workspaces read-only from a frozen cache, zero otherwise",
+	}, func() float64 {
+		if _, ok := p.ReadOnly(); ok {
+			return 1
+		}
+		return 0
+	}))
+}
+
+// Health describes the connection to ws-manager and the state of the workspace info cache
+func (p *RemoteWorkspaceInfoProvider) Health() InfoProviderHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	res := InfoProviderHealth{
+		Connected:    p.ready,
+		ReadOnly:     p.readOnly || p.isDegraded(),
+		CacheSize:    p.cache.Size(),
+		StaleEntries: p.cache.StaleCount(),
+	}
+	if !p.lastUpdate.IsZero() {
+		lastUpdate := p.lastUpdate
+		res.LastUpdate = &lastUpdate
+	}
+	return res
+}
+
+// Maintenance returns the cluster maintenance ws-manager announced, or nil if there's none
+func (p *RemoteWorkspaceInfoProvider) Maintenance() *MaintenanceInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.maintenance
+}
+
+func (p *RemoteWorkspaceInfoProvider) setMaintenance(status *wsapi.MaintenanceStatus) {
+	var m *MaintenanceInfo
+	if status != nil && status.Enabled {
+		m = &MaintenanceInfo{Message: status.Message}
+		if status.StartsAt != nil {
+			m.StartsAt, _ = ptypes.Timestamp(status.StartsAt)
+		}
+		if status.EndsAt != nil {
+			m.EndsAt, _ = ptypes.Timestamp(status.EndsAt)
+		}
+	}
+
+	p.mu.Lock()
+	p.maintenance = m
+	p.mu.Unlock()
+}
+
+// markUpdated records that we have just heard from ws-manager
+func (p *RemoteWorkspaceInfoProvider) markUpdated() {
+	p.mu.Lock()
+	p.lastUpdate = time.Now()
+	p.mu.Unlock()
+}
+
 // listen starts listening to WorkspaceStatus updates from ws-manager
 func (p *RemoteWorkspaceInfoProvider) listen(client wsapi.WorkspaceManagerClient) (err error) {
 	defer func() {
@@ -202,33 +423,64 @@ func (p *RemoteWorkspaceInfoProvider) listen(client wsapi.WorkspaceManagerClient
 		}
 	}()
 
-	// rebuild entire cache on (re-)connect
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.mu.Lock()
+	p.cancelListen = cancel
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.cancelListen = nil
+		p.mu.Unlock()
+	}()
+
+	// bring the cache up to date on (re-)connect
 	infos, err := p.fetchInitialWorkspaceInfo(ctx, client)
 	if err != nil {
 		return err
 	}
-	p.cache.Reinit(infos)
+	diff := p.cache.Reinit(infos)
+	log.WithField("diff", diff).Debug("refreshed workspace info cache")
+	p.markUpdated()
+
+	// ws-manager tells new subscribers about an announced maintenance, hence we forget what we knew
+	p.setMaintenance(nil)
 
 	// start streaming status updates
 	stream, err := client.Subscribe(ctx, &wsapi.SubscribeRequest{})
 	if err != nil {
 		return err
 	}
+	p.recordSuccess(infos)
 	for {
 		resp, err := stream.Recv()
 		if err != nil {
 			return err
 		}
 
+		p.markUpdated()
+
+		if maintenance := resp.GetMaintenance(); maintenance != nil {
+			log.WithField("enabled", maintenance.Enabled).WithField("message", maintenance.Message).Info("ws-manager changed the maintenance status")
+			p.setMaintenance(maintenance)
+			continue
+		}
+
 		status := resp.GetStatus()
 		if status == nil {
 			// some subscription responses contain log output rather than status updates.
 			continue
 		}
+		if isMalformedStatus(status) {
+			log.WithField("instanceId", status.Id).Warn("ignoring malformed workspace status update from ws-manager")
+			continue
+		}
+		if p.OnStatus != nil {
+			p.OnStatus(status)
+		}
 
 		if status.Phase == wsapi.WorkspacePhase_STOPPED {
-			p.cache.Delete(status.Metadata.MetaId)
+			p.cache.Delete(status.Metadata.MetaId, status.Generation)
 		} else {
 			info := mapWorkspaceStatusToInfo(status)
 			p.cache.Insert(info)
@@ -237,7 +489,10 @@ func (p *RemoteWorkspaceInfoProvider) listen(client wsapi.WorkspaceManagerClient
 }
 
 // fetchInitialWorkspaceInfo retrieves initial WorkspaceStatus' from ws-manager and maps them into WorkspaceInfos
-func (p *RemoteWorkspaceInfoProvider) fetchInitialWorkspaceInfo(ctx context.Context, client wsapi.WorkspaceManagerClient) ([]*WorkspaceInfo, error) {
+func (p *RemoteWorkspaceInfoProvider) fetchInitialWorkspaceInfo(ctx context.Context, client wsapi.WorkspaceManagerClient) (_ []*WorkspaceInfo, err error) {
+	span, ctx := tracing.FromContext(ctx, "fetchInitialWorkspaceInfo")
+	defer tracing.FinishSpan(span, &err)
+
 	initialResp, err := client.GetWorkspaces(ctx, &wsapi.GetWorkspacesRequest{})
 	if err != nil {
 		return nil, xerrors.Errorf("error while retrieving initial state from ws-manager: %w", err)
@@ -245,11 +500,23 @@ func (p *RemoteWorkspaceInfoProvider) fetchInitialWorkspaceInfo(ctx context.Cont
 
 	var infos []*WorkspaceInfo
 	for _, status := range initialResp.GetStatus() {
+		if isMalformedStatus(status) {
+			log.WithField("instanceId", status.Id).Warn("ignoring malformed workspace status from ws-manager")
+			continue
+		}
+		if p.OnStatus != nil {
+			p.OnStatus(status)
+		}
 		infos = append(infos, mapWorkspaceStatusToInfo(status))
 	}
 	return infos, nil
 }
 
+// isMalformedStatus returns true if a status lacks the parts we need to route to its workspace
+func isMalformedStatus(status *wsapi.WorkspaceStatus) bool {
+	return status.Metadata == nil || status.Spec == nil
+}
+
 func mapWorkspaceStatusToInfo(status *wsapi.WorkspaceStatus) *WorkspaceInfo {
 	var portInfos []PortInfo
 	for _, spec := range status.Spec.ExposedPorts {
@@ -269,9 +536,12 @@ func mapWorkspaceStatusToInfo(status *wsapi.WorkspaceStatus) *WorkspaceInfo {
 		InstanceID:    status.Id,
 		URL:           status.Spec.Url,
 		IDEImage:      status.Spec.IdeImage,
+		Experiments:   status.Spec.Experiments,
 		IDEPublicPort: getPortStr(status.Spec.Url),
 		Ports:         portInfos,
 		Auth:          status.Auth,
+		IPAddress:     status.GetRuntime().GetPodIp(),
+		Generation:    status.Generation,
 	}
 }
 
@@ -284,8 +554,12 @@ func (p *RemoteWorkspaceInfoProvider) WorkspaceInfo(ctx context.Context, workspa
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	info, present := p.cache.WaitFor(ctx, workspaceID)
-	if !present {
+	info, err := p.cache.WaitFor(ctx, workspaceID)
+	if xerrors.Is(err, ErrTooManyWaiters) {
+		log.WithFields(log.OWI("", workspaceID, "")).Warn("too many callers waiting for workspace info - rejecting request")
+		return nil
+	}
+	if err != nil {
 		return nil
 	}
 	return info
@@ -300,8 +574,13 @@ func (p *RemoteWorkspaceInfoProvider) WorkspaceCoords(publicPort string) *Worksp
 	return coords
 }
 
-// getPortStr extracts the port part from a given URL string. Returns "" if parsing fails or port is not specified
+// getPortStr extracts the port part from a given URL string. Returns "" if parsing fails or port is not specified.
+// IPv6 hosts must be bracketed as usual, e.g. https://[fd00::1]:8443.
 func getPortStr(urlStr string) string {
+	if !strings.Contains(urlStr, "://") {
+		// without scheme url.Parse mistakes the host for a path, e.g. for [fd00::1]:8080
+		urlStr = "//" + urlStr
+	}
 	portURL, err := url.Parse(urlStr)
 	if err != nil {
 		log.WithField("url", urlStr).WithError(err).Error("error parsing URL while getting URL port")
@@ -318,124 +597,407 @@ func getPortStr(urlStr string) string {
 	return portURL.Port()
 }
 
+// ErrTooManyWaiters is returned when a caller wants to wait for workspace info
+// but the number of concurrent waiters has reached its limit.
+var ErrTooManyWaiters = xerrors.Errorf("too many concurrent waiters for workspace info")
+
+const (
+	defaultMaxWaiters             = 10000
+	defaultMaxWaitersPerWorkspace = 500
+)
+
 // workspaceInfoCache stores WorkspaceInfo in a manner which is easy to query for WorkspaceInfoProvider
 type workspaceInfoCache struct {
 	// WorkspaceInfos indexed by workspaceID
 	infos map[string]*WorkspaceInfo
-	// WorkspaceCoords indexed by public (proxy) port (string)
-	coordsByPublicPort map[string]*WorkspaceCoords
+	// WorkspaceCoords indexed by public (proxy) port. Lookups do not need mu, but writes must hold it.
+	coordsByPublicPort *portIndex
+
+	// waiters are the channels of WaitFor callers indexed by the workspaceID they wait for
+	waiters map[string]map[chan *WorkspaceInfo]struct{}
+	// waiterCount is the total number of channels in waiters
+	waiterCount int
+
+	maxWaiters             int
+	maxWaitersPerWorkspace int
+
+	// version changes whenever the cache content changes
+	version uint64
 
-	// cond signals the arrival of new workspace info
-	cond *sync.Cond
-	// mu is cond's Locker
-	mu *sync.RWMutex
+	// onChange, if set, is called with mu held whenever a workspace is added, updated or removed
+	onChange func(old, new *WorkspaceInfo)
+
+	// frozen suppresses the removal of workspaces, e.g. while we cannot reach ws-manager
+	frozen bool
+
+	mu sync.RWMutex
 }
 
-func newWorkspaceInfoCache() *workspaceInfoCache {
-	var mu sync.RWMutex
+func newWorkspaceInfoCache(maxWaiters, maxWaitersPerWorkspace int) *workspaceInfoCache {
+	if maxWaiters <= 0 {
+		maxWaiters = defaultMaxWaiters
+	}
+	if maxWaitersPerWorkspace <= 0 {
+		maxWaitersPerWorkspace = defaultMaxWaitersPerWorkspace
+	}
 	return &workspaceInfoCache{
-		infos:              make(map[string]*WorkspaceInfo),
-		coordsByPublicPort: make(map[string]*WorkspaceCoords),
-		mu:                 &mu,
-		cond:               sync.NewCond(&mu),
+		infos:                  make(map[string]*WorkspaceInfo),
+		coordsByPublicPort:     newPortIndex(),
+		waiters:                make(map[string]map[chan *WorkspaceInfo]struct{}),
+		maxWaiters:             maxWaiters,
+		maxWaitersPerWorkspace: maxWaitersPerWorkspace,
 	}
 }
 
-func (c *workspaceInfoCache) Reinit(infos []*WorkspaceInfo) {
-	c.cond.L.Lock()
-	defer c.cond.L.Unlock()
+// cacheDiff counts the changes Reinit applied to the cache
+type cacheDiff struct {
+	Inserted int
+	Updated  int
+	Deleted  int
+	// Retained counts the workspaces we did not delete because the cache is frozen
+	Retained int
+}
 
-	c.infos = make(map[string]*WorkspaceInfo, len(infos))
-	c.coordsByPublicPort = make(map[string]*WorkspaceCoords, len(c.coordsByPublicPort))
+// Reinit brings the cache to the state of infos, which is the complete list of workspaces ws-manager knows about.
+// Rather than rebuilding the cache, Reinit applies only the difference to the current content so that lookups
+// never miss workspaces which are present before and after the refresh.
+func (c *workspaceInfoCache) Reinit(infos []*WorkspaceInfo) (diff cacheDiff) {
+	// Comparing thousands of infos takes a while - we do that under the read lock only
+	// and re-check every change when we apply it.
+	var (
+		present = make(map[string]struct{}, len(infos))
+		changed = make([]*WorkspaceInfo, 0)
+		removed = make([]*WorkspaceInfo, 0)
+	)
+	c.mu.RLock()
+	for _, info := range infos {
+		present[info.WorkspaceID] = struct{}{}
+		if existing, ok := c.infos[info.WorkspaceID]; ok && workspaceInfoEqual(existing, info) {
+			continue
+		}
+		changed = append(changed, info)
+	}
+	for id, info := range c.infos {
+		if _, ok := present[id]; !ok {
+			removed = append(removed, info)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, info := range changed {
+		existing, ok := c.infos[info.WorkspaceID]
+		if ok && c.isOutdated(info.WorkspaceID, info.Generation) {
+			// an update arrived while we were comparing
+			continue
+		}
+		if ok {
+			c.removeCoords(existing)
+			diff.Updated++
+		} else {
+			diff.Inserted++
+		}
+		c.doInsert(info)
+		c.notifyWaiters(info)
+		c.changed(existing, info)
+	}
+	for _, info := range removed {
+		if c.infos[info.WorkspaceID] != info {
+			// the workspace was re-inserted while we were comparing
+			continue
+		}
+		if c.frozen {
+			diff.Retained++
+			continue
+		}
+		c.removeCoords(info)
+		delete(c.infos, info.WorkspaceID)
+		c.changed(info, nil)
+		diff.Deleted++
+	}
+	if diff.Inserted > 0 || diff.Updated > 0 || diff.Deleted > 0 {
+		c.version++
+	}
+	return
+}
+
+// workspaceInfoEqual returns true if a and b describe the same state of a workspace
+func workspaceInfoEqual(a, b *WorkspaceInfo) bool {
+	if a.WorkspaceID != b.WorkspaceID ||
+		a.InstanceID != b.InstanceID ||
+		a.URL != b.URL ||
+		a.IDEImage != b.IDEImage ||
+		a.IDEPublicPort != b.IDEPublicPort ||
+		a.IPAddress != b.IPAddress ||
+		a.Generation != b.Generation ||
+		a.OwnerTokenHash != b.OwnerTokenHash ||
+		a.Stale != b.Stale ||
+		len(a.Ports) != len(b.Ports) ||
+		len(a.Experiments) != len(b.Experiments) {
+		return false
+	}
+	for name, variant := range a.Experiments {
+		if v, ok := b.Experiments[name]; !ok || v != variant {
+			return false
+		}
+	}
+	if !proto.Equal(a.Auth, b.Auth) {
+		return false
+	}
+	for i := range a.Ports {
+		if a.Ports[i].PublicPort != b.Ports[i].PublicPort || !proto.Equal(&a.Ports[i].PortSpec, &b.Ports[i].PortSpec) {
+			return false
+		}
+	}
+	return true
+}
+
+// Restore adds stale infos, e.g. from a snapshot, unless we know about the workspace already.
+// Returns the number of infos added.
+func (c *workspaceInfoCache) Restore(infos []*WorkspaceInfo) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
+	var n int
 	for _, info := range infos {
+		if _, exists := c.infos[info.WorkspaceID]; exists {
+			continue
+		}
 		c.doInsert(info)
+		c.notifyWaiters(info)
+		n++
+	}
+	c.version++
+	return n
+}
+
+// Snapshot returns all infos in the cache and the cache version they represent
+func (c *workspaceInfoCache) Snapshot() ([]*WorkspaceInfo, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	res := make([]*WorkspaceInfo, 0, len(c.infos))
+	for _, info := range c.infos {
+		res = append(res, info)
 	}
-	c.cond.Broadcast()
+	return res, c.version
 }
 
 func (c *workspaceInfoCache) Insert(info *WorkspaceInfo) {
-	c.cond.L.Lock()
-	defer c.cond.L.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isOutdated(info.WorkspaceID, info.Generation) {
+		log.WithFields(log.OWI("", info.WorkspaceID, info.InstanceID)).WithField("generation", info.Generation).Debug("discarding out-of-order workspace info")
+		return
+	}
 
+	existing, ok := c.infos[info.WorkspaceID]
+	if ok {
+		c.removeCoords(existing)
+	}
 	c.doInsert(info)
-	c.cond.Broadcast()
+	c.notifyWaiters(info)
+	c.changed(existing, info)
+	c.version++
 }
 
 func (c *workspaceInfoCache) doInsert(info *WorkspaceInfo) {
 	c.infos[info.WorkspaceID] = info
-	c.coordsByPublicPort[info.IDEPublicPort] = &WorkspaceCoords{
+	c.coordsByPublicPort.Set(info.IDEPublicPort, &WorkspaceCoords{
 		ID: info.WorkspaceID,
-	}
+	})
 
 	for _, p := range info.Ports {
-		c.coordsByPublicPort[p.PublicPort] = &WorkspaceCoords{
+		c.coordsByPublicPort.Set(p.PublicPort, &WorkspaceCoords{
 			ID:   info.WorkspaceID,
 			Port: strconv.Itoa(int(p.Port)),
+		})
+	}
+}
+
+// removeCoords removes the public ports of info from the port index unless they belong to another workspace by now.
+// Callers are expected to hold mu.
+func (c *workspaceInfoCache) removeCoords(info *WorkspaceInfo) {
+	ports := make([]string, 0, len(info.Ports)+1)
+	ports = append(ports, info.IDEPublicPort)
+	for _, p := range info.Ports {
+		ports = append(ports, p.PublicPort)
+	}
+	for _, p := range ports {
+		if coords, ok := c.coordsByPublicPort.Get(p); ok && coords.ID == info.WorkspaceID {
+			c.coordsByPublicPort.Delete(p)
 		}
 	}
 }
 
-func (c *workspaceInfoCache) Delete(workspaceID string) {
-	c.cond.L.Lock()
-	defer c.cond.L.Unlock()
+// notifyWaiters hands the info to everyone waiting for it and removes them from the registry.
+// Callers are expected to hold mu.
+func (c *workspaceInfoCache) notifyWaiters(info *WorkspaceInfo) {
+	ws, ok := c.waiters[info.WorkspaceID]
+	if !ok {
+		return
+	}
+	for ch := range ws {
+		// ch is buffered and each waiter is notified at most once, hence this never blocks
+		ch <- info
+	}
+	c.waiterCount -= len(ws)
+	delete(c.waiters, info.WorkspaceID)
+}
+
+// isOutdated returns true if we already know a more recent status of the workspace than the given generation.
+// Callers are expected to hold mu.
+func (c *workspaceInfoCache) isOutdated(workspaceID string, generation uint64) bool {
+	if generation == 0 {
+		// ws-manager does not tell us about generations - we have to take the update as it comes
+		return false
+	}
+	existing, ok := c.infos[workspaceID]
+	return ok && existing.Generation > generation
+}
+
+// Delete removes the workspace from the cache unless we know a more recent status than the given generation,
+// e.g. because a new instance of the workspace has started already.
+func (c *workspaceInfoCache) Delete(workspaceID string, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	info, present := c.infos[workspaceID]
 	if !present || info == nil {
 		return
 	}
-	delete(c.coordsByPublicPort, info.IDEPublicPort)
+	if c.isOutdated(workspaceID, generation) {
+		return
+	}
+	if c.frozen {
+		log.WithFields(log.OWI("", workspaceID, info.InstanceID)).Debug("cache is frozen - not removing workspace")
+		return
+	}
+	c.removeCoords(info)
 	delete(c.infos, workspaceID)
+	c.changed(info, nil)
+	c.version++
+}
+
+// SetFrozen freezes or thaws the cache. A frozen cache keeps adding and updating workspaces, but never removes any.
+func (c *workspaceInfoCache) SetFrozen(frozen bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.frozen = frozen
+}
+
+// changed reports a change to onChange. Callers are expected to hold mu.
+func (c *workspaceInfoCache) changed(old, new *WorkspaceInfo) {
+	if c.onChange == nil {
+		return
+	}
+	c.onChange(old, new)
 }
 
 // WaitFor waits for workspace info until that info is available or the context is canceled.
-func (c *workspaceInfoCache) WaitFor(ctx context.Context, workspaceID string) (w *WorkspaceInfo, ok bool) {
+// If too many callers are waiting already, WaitFor returns ErrTooManyWaiters right away.
+func (c *workspaceInfoCache) WaitFor(ctx context.Context, workspaceID string) (_ *WorkspaceInfo, err error) {
 	c.mu.RLock()
-	w, ok = c.infos[workspaceID]
+	w, ok := c.infos[workspaceID]
 	c.mu.RUnlock()
 	if ok {
-		return
+		return w, nil
 	}
 
-	inc := make(chan *WorkspaceInfo)
-	go func() {
-		defer close(inc)
+	// this is a cache miss which makes us wait for ws-manager
+	span, ctx := tracing.FromContext(ctx, "workspaceInfoCache.WaitFor")
+	span.SetTag(log.WorkspaceField, workspaceID)
+	defer tracing.FinishSpan(span, &err)
 
-		c.cond.L.Lock()
-		defer c.cond.L.Unlock()
-		for {
-			c.cond.Wait()
-			if ctx.Err() != nil {
-				return
-			}
+	c.mu.Lock()
+	// the info might have arrived while we did not hold the lock
+	if w, ok := c.infos[workspaceID]; ok {
+		c.mu.Unlock()
+		return w, nil
+	}
+	ws := c.waiters[workspaceID]
+	if c.waiterCount >= c.maxWaiters || len(ws) >= c.maxWaitersPerWorkspace {
+		c.mu.Unlock()
+		return nil, ErrTooManyWaiters
+	}
+	if ws == nil {
+		ws = make(map[chan *WorkspaceInfo]struct{})
+		c.waiters[workspaceID] = ws
+	}
+	inc := make(chan *WorkspaceInfo, 1)
+	ws[inc] = struct{}{}
+	c.waiterCount++
+	c.mu.Unlock()
 
-			info, ok := c.infos[workspaceID]
-			if !ok {
-				continue
-			}
+	select {
+	case w = <-inc:
+		return w, nil
+	case <-ctx.Done():
+	}
 
-			inc <- info
-			return
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ws, ok := c.waiters[workspaceID]; ok {
+		if _, registered := ws[inc]; registered {
+			delete(ws, inc)
+			c.waiterCount--
+			if len(ws) == 0 {
+				delete(c.waiters, workspaceID)
+			}
 		}
-	}()
+	}
 
+	// we might have been notified between the context being canceled and us acquiring the lock
 	select {
 	case w = <-inc:
-		if w == nil {
-			return nil, false
-		}
-		return w, true
-	case <-ctx.Done():
-		return nil, false
+		return w, nil
+	default:
+		return nil, ctx.Err()
 	}
 }
 
-func (c *workspaceInfoCache) GetCoordsByPublicPort(wsProxyPort string) (*WorkspaceCoords, bool) {
+// WaiterCount returns the number of callers currently waiting in WaitFor
+func (c *workspaceInfoCache) WaiterCount() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	coords, ok := c.coordsByPublicPort[wsProxyPort]
-	return coords, ok
+	return c.waiterCount
+}
+
+// Size returns the number of workspaces in the cache
+func (c *workspaceInfoCache) Size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.infos)
+}
+
+// StaleCount returns the number of stale workspaces in the cache
+func (c *workspaceInfoCache) StaleCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var n int
+	for _, info := range c.infos {
+		if info.Stale {
+			n++
+		}
+	}
+	return n
+}
+
+// GetCoordsByPublicPort returns the coordinates of a public (proxy) port. It is on the hot path of port-based
+// routing, hence does not take mu.
+func (c *workspaceInfoCache) GetCoordsByPublicPort(wsProxyPort string) (*WorkspaceCoords, bool) {
+	return c.coordsByPublicPort.Get(wsProxyPort)
 }
 
 type fixedInfoProvider struct {
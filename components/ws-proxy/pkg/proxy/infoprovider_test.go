@@ -8,12 +8,17 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/gitpod-io/gitpod/common-go/util"
 	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
 	wsmock "github.com/gitpod-io/gitpod/ws-manager/api/mock"
 )
@@ -175,6 +180,308 @@ func TestRemoteInfoProvider(t *testing.T) {
 
 }
 
+func TestWorkspaceInfoCacheWaitFor(t *testing.T) {
+	t.Run("notifies all waiters", func(t *testing.T) {
+		cache := newWorkspaceInfoCache(0, 0)
+
+		const waiters = 10
+		res := make(chan *WorkspaceInfo, waiters)
+		for i := 0; i < waiters; i++ {
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+				defer cancel()
+				nfo, _ := cache.WaitFor(ctx, testWorkspaceInfo.WorkspaceID)
+				res <- nfo
+			}()
+		}
+		for cache.WaiterCount() < waiters {
+			time.Sleep(1 * time.Millisecond)
+		}
+
+		cache.Insert(testWorkspaceInfo)
+		for i := 0; i < waiters; i++ {
+			if nfo := <-res; nfo != testWorkspaceInfo {
+				t.Errorf("waiter %d got unexpected info: %v", i, nfo)
+			}
+		}
+		if c := cache.WaiterCount(); c != 0 {
+			t.Errorf("expected no waiters left, got %d", c)
+		}
+	})
+
+	t.Run("cleans up on context cancellation", func(t *testing.T) {
+		cache := newWorkspaceInfoCache(0, 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		nfo, err := cache.WaitFor(ctx, "foobar")
+		if nfo != nil {
+			t.Errorf("expected no info, got %v", nfo)
+		}
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+		if c := cache.WaiterCount(); c != 0 {
+			t.Errorf("expected no waiters left, got %d", c)
+		}
+	})
+
+	t.Run("rejects excess waiters", func(t *testing.T) {
+		cache := newWorkspaceInfoCache(3, 2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		for _, id := range []string{"a", "a", "b"} {
+			go cache.WaitFor(ctx, id)
+		}
+		for cache.WaiterCount() < 3 {
+			time.Sleep(1 * time.Millisecond)
+		}
+
+		for _, id := range []string{"a", "c"} {
+			_, err := cache.WaitFor(ctx, id)
+			if err != ErrTooManyWaiters {
+				t.Errorf("expected ErrTooManyWaiters for %s, got %v", id, err)
+			}
+		}
+
+		cancel()
+		for cache.WaiterCount() > 0 {
+			time.Sleep(1 * time.Millisecond)
+		}
+	})
+}
+
+func TestWorkspaceInfoCacheGenerations(t *testing.T) {
+	var (
+		id      = "foobar"
+		older   = &WorkspaceInfo{WorkspaceID: id, InstanceID: "old", Generation: 10}
+		newer   = &WorkspaceInfo{WorkspaceID: id, InstanceID: "new", Generation: 20}
+		unknown = &WorkspaceInfo{WorkspaceID: id, InstanceID: "unknown"}
+	)
+	get := func(cache *workspaceInfoCache) *WorkspaceInfo {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		nfo, _ := cache.WaitFor(ctx, id)
+		return nfo
+	}
+
+	cache := newWorkspaceInfoCache(0, 0)
+	cache.Insert(newer)
+	cache.Insert(older)
+	if nfo := get(cache); nfo != newer {
+		t.Errorf("out-of-order insert replaced newer info: %v", nfo)
+	}
+
+	cache.Delete(id, older.Generation)
+	if nfo := get(cache); nfo != newer {
+		t.Errorf("out-of-order delete removed newer info: %v", nfo)
+	}
+
+	cache.Insert(unknown)
+	if nfo := get(cache); nfo != unknown {
+		t.Errorf("info without generation was discarded: %v", nfo)
+	}
+
+	cache.Delete(id, 0)
+	if nfo := get(cache); nfo != nil {
+		t.Errorf("delete without generation did not remove info: %v", nfo)
+	}
+}
+
+func TestWorkspaceInfoCacheReinit(t *testing.T) {
+	var (
+		kept    = &WorkspaceInfo{WorkspaceID: "kept", IDEPublicPort: "1000", Generation: 1}
+		changed = &WorkspaceInfo{WorkspaceID: "changed", IDEPublicPort: "2000", Generation: 1, Ports: []PortInfo{{PublicPort: "2001"}}}
+		gone    = &WorkspaceInfo{WorkspaceID: "gone", IDEPublicPort: "3000", Generation: 1, Ports: []PortInfo{{PublicPort: "3001"}}}
+	)
+	cache := newWorkspaceInfoCache(0, 0)
+	diff := cache.Reinit([]*WorkspaceInfo{kept, changed, gone})
+	if diff != (cacheDiff{Inserted: 3}) {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	_, version := cache.Snapshot()
+
+	diff = cache.Reinit([]*WorkspaceInfo{
+		{WorkspaceID: "kept", IDEPublicPort: "1000", Generation: 1},
+		{WorkspaceID: "changed", IDEPublicPort: "2000", Generation: 2, Ports: []PortInfo{{PublicPort: "2002"}}},
+		{WorkspaceID: "new", IDEPublicPort: "4000", Generation: 1},
+	})
+	if diff != (cacheDiff{Inserted: 1, Updated: 1, Deleted: 1}) {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if nfo := cache.infos["kept"]; nfo != kept {
+		t.Error("unchanged info was replaced")
+	}
+	for port, expected := range map[string]bool{"1000": true, "2000": true, "2001": false, "2002": true, "3000": false, "3001": false, "4000": true} {
+		if _, ok := cache.GetCoordsByPublicPort(port); ok != expected {
+			t.Errorf("port %s: expected presence %v, got %v", port, expected, ok)
+		}
+	}
+	_, newVersion := cache.Snapshot()
+	if newVersion == version {
+		t.Error("cache version did not change")
+	}
+
+	diff = cache.Reinit([]*WorkspaceInfo{
+		{WorkspaceID: "kept", IDEPublicPort: "1000", Generation: 1},
+		{WorkspaceID: "changed", IDEPublicPort: "2000", Generation: 2, Ports: []PortInfo{{PublicPort: "2002"}}},
+		{WorkspaceID: "new", IDEPublicPort: "4000", Generation: 1},
+	})
+	if diff != (cacheDiff{}) {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if _, v := cache.Snapshot(); v != newVersion {
+		t.Error("cache version changed without a change")
+	}
+}
+
+func BenchmarkWorkspaceInfoCacheLookup(b *testing.B) {
+	const workspaces = 10000
+	infos := make([]*WorkspaceInfo, workspaces)
+	for i := range infos {
+		infos[i] = &WorkspaceInfo{
+			WorkspaceID:   fmt.Sprintf("ws-%d", i),
+			IDEPublicPort: strconv.Itoa(10000 + i),
+			Generation:    1,
+			Ports:         []PortInfo{{PublicPort: strconv.Itoa(30000 + i)}},
+		}
+	}
+	cache := newWorkspaceInfoCache(0, 0)
+	cache.Reinit(infos)
+	ports := make([]string, workspaces)
+	for i := range ports {
+		ports[i] = infos[i].IDEPublicPort
+	}
+
+	// refresh the cache continuously, as a flapping connection to ws-manager would,
+	// with a few workspaces changing on every refresh
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for gen := uint64(2); ; gen++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			refresh := make([]*WorkspaceInfo, len(infos))
+			copy(refresh, infos)
+			for i := 0; i < 10; i++ {
+				idx := int(gen*7+uint64(i)) % workspaces
+				nfo := *refresh[idx]
+				nfo.Generation = gen
+				refresh[idx] = &nfo
+			}
+			cache.Reinit(refresh)
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			i++
+			if _, ok := cache.GetCoordsByPublicPort(ports[i%workspaces]); !ok {
+				b.Fatal("lookup missed a workspace")
+			}
+		}
+	})
+}
+
+// TestWorkspaceInfoCacheAllocations guards the cache hits every proxied request makes against allocation regressions
+func TestWorkspaceInfoCacheAllocations(t *testing.T) {
+	cache := newWorkspaceInfoCache(0, 0)
+	cache.Reinit([]*WorkspaceInfo{
+		{WorkspaceID: "ws", IDEPublicPort: "10000", Generation: 1},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		Name   string
+		Budget float64
+		Lookup func()
+	}{
+		{"WaitFor", 0, func() {
+			if _, err := cache.WaitFor(ctx, "ws"); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"GetCoordsByPublicPort", 0, func() {
+			if _, ok := cache.GetCoordsByPublicPort("10000"); !ok {
+				t.Fatal("lookup missed the workspace")
+			}
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, test.Lookup); allocs > test.Budget {
+				t.Errorf("%s allocates %.0f times, the budget is %.0f", test.Name, allocs, test.Budget)
+			}
+		})
+	}
+}
+
+func TestWorkspaceInfoSnapshot(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "snapshot.json")
+	cfg := WorkspaceInfoProviderConfig{WsManagerAddr: "target", SnapshotFile: fn}
+
+	prov := NewRemoteWorkspaceInfoProvider(cfg)
+	prov.cache.Insert(mapWorkspaceStatusToInfo(testWorkspaceStatus))
+	err := prov.PersistSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(fc), testWorkspaceStatus.Auth.OwnerToken) {
+		t.Fatal("snapshot contains the owner token")
+	}
+
+	restored := NewRemoteWorkspaceInfoProvider(cfg)
+	n, err := restored.LoadSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected one restored workspace, got %d", n)
+	}
+	if h := restored.Health(); h.StaleEntries != 1 || h.LastUpdate == nil {
+		t.Errorf("restored workspace is not marked stale: %+v", h)
+	}
+	info := restored.WorkspaceInfo(context.Background(), testWorkspaceInfo.WorkspaceID)
+	if info == nil {
+		t.Fatal("restored workspace is unknown")
+	}
+	if !info.IsOwnerToken(testWorkspaceStatus.Auth.OwnerToken) || info.IsOwnerToken("") || info.IsOwnerToken("foobar") {
+		t.Error("restored workspace does not check the owner token")
+	}
+	if coords := restored.WorkspaceCoords("443"); coords == nil || coords.ID != testWorkspaceInfo.WorkspaceID {
+		t.Errorf("restored workspace has no coords: %v", coords)
+	}
+
+	// ws-manager confirms the workspace
+	restored.cache.Reinit([]*WorkspaceInfo{mapWorkspaceStatusToInfo(testWorkspaceStatus)})
+	if h := restored.Health(); h.StaleEntries != 0 {
+		t.Errorf("confirmed workspace is still stale: %+v", h)
+	}
+
+	// outdated snapshots are ignored
+	cfg.SnapshotMaxAge = util.Duration(time.Nanosecond)
+	n, err = NewRemoteWorkspaceInfoProvider(cfg).LoadSnapshot()
+	if err != nil || n != 0 {
+		t.Errorf("outdated snapshot was restored: %d, %v", n, err)
+	}
+
+	cfg.SnapshotFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = NewRemoteWorkspaceInfoProvider(cfg).LoadSnapshot()
+	if err != nil {
+		t.Errorf("missing snapshot failed to load: %v", err)
+	}
+}
+
 var (
 	testWorkspaceStatus = &wsapi.WorkspaceStatus{
 		Id: "e63cb5ff-f4e4-4065-8554-b431a32c0000",
@@ -216,3 +523,25 @@ var (
 		WorkspaceID: testWorkspaceStatus.Metadata.MetaId,
 	}
 )
+
+func TestGetPortStr(t *testing.T) {
+	tests := []struct {
+		URL      string
+		Expected string
+	}{
+		{"https://amaranth-smelt-9ba20cc1.ws.gitpod.io", "443"},
+		{"http://amaranth-smelt-9ba20cc1.ws.gitpod.io", "80"},
+		{"https://10.0.0.1:10000", "10000"},
+		{"https://[fd00::1]:10000", "10000"},
+		{"https://[fd00::1]", "443"},
+		{"[fd00::1]:10000", "10000"},
+		{"10.0.0.1:10000", "10000"},
+	}
+	for _, test := range tests {
+		t.Run(test.URL, func(t *testing.T) {
+			if act := getPortStr(test.URL); act != test.Expected {
+				t.Errorf("getPortStr() = %q, expected %q", act, test.Expected)
+			}
+		})
+	}
+}
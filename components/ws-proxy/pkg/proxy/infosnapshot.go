@@ -0,0 +1,176 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// defaultSnapshotInterval is how often we write the workspace info snapshot if not configured otherwise
+	defaultSnapshotInterval = 30 * time.Second
+	// defaultSnapshotMaxAge is the age beyond which we ignore a snapshot if not configured otherwise
+	defaultSnapshotMaxAge = time.Hour
+)
+
+// workspaceInfoSnapshot is the on-disk format of the workspace info cache
+type workspaceInfoSnapshot struct {
+	Created    time.Time        `json:"created"`
+	Workspaces []*WorkspaceInfo `json:"workspaces"`
+}
+
+// LoadSnapshot restores the workspace info cache from the snapshot file. All restored workspaces are stale
+// until ws-manager confirms them. A missing or outdated snapshot is not an error. Returns the number of restored workspaces.
+func (p *RemoteWorkspaceInfoProvider) LoadSnapshot() (int, error) {
+	if p.Config.SnapshotFile == "" {
+		return 0, nil
+	}
+
+	fc, err := os.ReadFile(p.Config.SnapshotFile)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, xerrors.Errorf("cannot read workspace info snapshot: %w", err)
+	}
+	var snapshot workspaceInfoSnapshot
+	err = json.Unmarshal(fc, &snapshot)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot unmarshal workspace info snapshot: %w", err)
+	}
+
+	maxAge := time.Duration(p.Config.SnapshotMaxAge)
+	if maxAge == 0 {
+		maxAge = defaultSnapshotMaxAge
+	}
+	if age := time.Since(snapshot.Created); age > maxAge {
+		log.WithField("age", age.String()).Info("workspace info snapshot is too old - ignoring it")
+		return 0, nil
+	}
+
+	infos := make([]*WorkspaceInfo, 0, len(snapshot.Workspaces))
+	for _, info := range snapshot.Workspaces {
+		if info == nil || info.WorkspaceID == "" || info.Auth == nil || info.OwnerTokenHash == "" {
+			continue
+		}
+		info.Stale = true
+		infos = append(infos, info)
+	}
+	n := p.cache.Restore(infos)
+
+	// the snapshot is as good as the last update we got from ws-manager before it was written
+	p.mu.Lock()
+	if p.lastUpdate.IsZero() {
+		p.lastUpdate = snapshot.Created
+	}
+	p.mu.Unlock()
+	return n, nil
+}
+
+// PersistSnapshot writes the workspace info cache to the snapshot file if it has changed since the last write.
+// The snapshot never contains owner tokens, only their hashes.
+func (p *RemoteWorkspaceInfoProvider) PersistSnapshot() error {
+	if p.Config.SnapshotFile == "" {
+		return nil
+	}
+
+	infos, version := p.cache.Snapshot()
+	p.mu.Lock()
+	if version == p.snapshotVersion {
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	snapshot := workspaceInfoSnapshot{
+		Created:    time.Now(),
+		Workspaces: make([]*WorkspaceInfo, 0, len(infos)),
+	}
+	for _, info := range infos {
+		if info.Auth == nil {
+			continue
+		}
+		entry := redactWorkspaceInfo(info)
+		entry.Stale = false
+		snapshot.Workspaces = append(snapshot.Workspaces, entry)
+	}
+	sort.Slice(snapshot.Workspaces, func(i, j int) bool { return snapshot.Workspaces[i].WorkspaceID < snapshot.Workspaces[j].WorkspaceID })
+	fc, err := json.Marshal(snapshot)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal workspace info snapshot: %w", err)
+	}
+
+	// write to a temporary file first so that we never leave a half-written snapshot behind
+	tmp, err := os.CreateTemp(filepath.Dir(p.Config.SnapshotFile), ".workspace-info-*")
+	if err != nil {
+		return xerrors.Errorf("cannot write workspace info snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(fc)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot write workspace info snapshot: %w", err)
+	}
+	err = os.Rename(tmp.Name(), p.Config.SnapshotFile)
+	if err != nil {
+		return xerrors.Errorf("cannot write workspace info snapshot: %w", err)
+	}
+
+	p.mu.Lock()
+	p.snapshotVersion = version
+	p.mu.Unlock()
+	return nil
+}
+
+// StartSnapshots periodically persists the workspace info cache until Close is called
+func (p *RemoteWorkspaceInfoProvider) StartSnapshots() {
+	if p.Config.SnapshotFile == "" {
+		return
+	}
+
+	interval := time.Duration(p.Config.SnapshotInterval)
+	if interval == 0 {
+		interval = defaultSnapshotInterval
+	}
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-t.C:
+			}
+
+			err := p.PersistSnapshot()
+			if err != nil {
+				log.WithError(err).Warn("cannot persist workspace info snapshot")
+			}
+		}
+	}()
+}
+
+// redactWorkspaceInfo returns a copy of the info which carries the hash of the owner token instead of the token itself
+func redactWorkspaceInfo(info *WorkspaceInfo) *WorkspaceInfo {
+	entry := *info
+	if info.Auth != nil {
+		entry.Auth = &wsapi.WorkspaceAuthentication{Admission: info.Auth.Admission}
+		if entry.OwnerTokenHash == "" {
+			entry.OwnerTokenHash = hashOwnerToken(info.Auth.OwnerToken)
+		}
+	}
+	return &entry
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// workspaceInstanceHeader carries the workspace instance a client expects to talk to
+const workspaceInstanceHeader = "X-Gitpod-Instance-Id"
+
+// instanceCookieName returns the name of the cookie in which the dashboard stores the instance of a workspace it opened
+func instanceCookieName(domain, workspaceID string) string {
+	return ownerCookiePrefix(domain) + workspaceID + "_instance_"
+}
+
+// instanceHint returns the workspace instance a request expects, or an empty string if it does not say.
+// The header takes precedence over the cookie.
+func instanceHint(req *http.Request, domain, workspaceID string) string {
+	if hint := req.Header.Get(workspaceInstanceHeader); hint != "" {
+		return hint
+	}
+	c, err := req.Cookie(instanceCookieName(domain, workspaceID))
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// instanceMustMatchHandler rejects requests which expect another instance of the workspace than the one that
+// is running, e.g. from an IDE which was opened before the workspace restarted. Such an IDE would otherwise
+// talk to the new instance with the state of the old one. It relies on the workspaceMustExistHandler to
+// provide the workspace info.
+func instanceMustMatchHandler(domain string, pages *ErrorPages) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			info := getWorkspaceInfoFromContext(req.Context())
+			if info == nil {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			hint := instanceHint(req, domain, info.WorkspaceID)
+			if hint == "" || hint == info.InstanceID {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			log.WithFields(log.OWI("", info.WorkspaceID, info.InstanceID)).WithField("expectedInstanceID", hint).Debug("request for a stale workspace instance")
+			serveErrorPage(pages, resp, req, ErrorPageWorkspaceRestarted, http.StatusGone)
+		})
+	}
+}
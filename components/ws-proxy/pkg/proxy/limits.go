@@ -0,0 +1,170 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+)
+
+var (
+	// errRequestBodyTooLarge is returned when reading a request body which exceeds the limit of its route
+	errRequestBodyTooLarge = xerrors.New("request body too large")
+	// errResponseTooLarge is returned when an upstream response exceeds the limits of its route
+	errResponseTooLarge = xerrors.New("upstream response too large")
+)
+
+// RouteLimitsConfig limits the size of requests and responses of a route. All limits are in bytes and zero means no limit.
+// Independent of the route, the server rejects request headers larger than 1 MiB.
+type RouteLimitsConfig struct {
+	// MaxRequestHeaderBytes limits the size of the request line and headers
+	MaxRequestHeaderBytes int64 `json:"maxRequestHeaderBytes,omitempty"`
+	// MaxRequestBodyBytes limits the size of request bodies. We enforce the limit while we stream the body upstream.
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty"`
+	// BufferRequestBodyBytes is the size up to which we read request bodies before we connect to the upstream,
+	// so that slow uploads do not hold on to workspace connections. Larger bodies are streamed.
+	BufferRequestBodyBytes int64 `json:"bufferRequestBodyBytes,omitempty"`
+	// MaxResponseHeaderBytes limits the size of the upstream's response headers
+	MaxResponseHeaderBytes int64 `json:"maxResponseHeaderBytes,omitempty"`
+	// MaxResponseBodyBytes limits the size of upstream response bodies. Responses which exceed the limit
+	// while we stream them are cut off.
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *RouteLimitsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	bufferLimit := c.MaxRequestBodyBytes
+	if bufferLimit == 0 {
+		bufferLimit = c.BufferRequestBodyBytes
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.MaxRequestHeaderBytes, validation.Min(int64(0))),
+		validation.Field(&c.MaxRequestBodyBytes, validation.Min(int64(0))),
+		validation.Field(&c.BufferRequestBodyBytes, validation.Min(int64(0)), validation.Max(bufferLimit)),
+		validation.Field(&c.MaxResponseHeaderBytes, validation.Min(int64(0))),
+		validation.Field(&c.MaxResponseBodyBytes, validation.Min(int64(0))),
+	)
+}
+
+// limitRequestHandler enforces the request limits of a route
+func limitRequestHandler(limits *RouteLimitsConfig, h http.HandlerFunc) http.HandlerFunc {
+	if limits == nil {
+		return h
+	}
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if limits.MaxRequestHeaderBytes > 0 && requestHeaderSize(req) > limits.MaxRequestHeaderBytes {
+			getLog(req.Context()).WithField("limit", limits.MaxRequestHeaderBytes).Debug("request headers too large")
+			http.Error(resp, "request headers too large", http.StatusRequestHeaderFieldsTooLarge)
+			return
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if limits.MaxRequestBodyBytes > 0 {
+				if req.ContentLength > limits.MaxRequestBodyBytes {
+					getLog(req.Context()).WithField("limit", limits.MaxRequestBodyBytes).Debug("request body too large")
+					http.Error(resp, "request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				req.Body = &limitedBody{ReadCloser: req.Body, remaining: limits.MaxRequestBodyBytes, err: errRequestBodyTooLarge}
+			}
+			if req.ContentLength > 0 && req.ContentLength <= limits.BufferRequestBodyBytes {
+				err := bufferRequestBody(req)
+				if xerrors.Is(err, errRequestBodyTooLarge) {
+					http.Error(resp, "request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				if err != nil {
+					getLog(req.Context()).WithError(err).Debug("cannot read request body")
+					http.Error(resp, "cannot read request body", http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		h(resp, req)
+	}
+}
+
+// requestHeaderSize approximates the size of the request line and headers as they appear on the wire
+func requestHeaderSize(req *http.Request) int64 {
+	size := int64(len(req.Method) + len(req.RequestURI) + len(req.Proto) + 4)
+	for k, vs := range req.Header {
+		for _, v := range vs {
+			size += int64(len(k) + len(v) + 4)
+		}
+	}
+	return size
+}
+
+// bufferRequestBody reads a request body which is at most as large as its content length into memory
+func bufferRequestBody(req *http.Request) error {
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, req.ContentLength))
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
+
+// limitResponse enforces the response limits of a route on the response of an upstream
+func limitResponse(limits *RouteLimitsConfig, resp *http.Response) error {
+	if limits == nil {
+		return nil
+	}
+	if limits.MaxResponseHeaderBytes > 0 {
+		var size int64
+		for k, vs := range resp.Header {
+			for _, v := range vs {
+				size += int64(len(k) + len(v) + 4)
+			}
+		}
+		if size > limits.MaxResponseHeaderBytes {
+			return xerrors.Errorf("%d bytes of headers: %w", size, errResponseTooLarge)
+		}
+	}
+	if limits.MaxResponseBodyBytes > 0 && resp.StatusCode != http.StatusSwitchingProtocols {
+		if resp.ContentLength > limits.MaxResponseBodyBytes {
+			return xerrors.Errorf("%d bytes of body: %w", resp.ContentLength, errResponseTooLarge)
+		}
+		resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: limits.MaxResponseBodyBytes, err: errResponseTooLarge}
+	}
+	return nil
+}
+
+// limitedBody fails reads once more than the remaining number of bytes were read. Unlike a
+// LimitReader it tells an exhausted limit apart from the end of the body.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	err       error
+}
+
+func (b *limitedBody) Read(p []byte) (n int, err error) {
+	if b.remaining < 0 {
+		return 0, b.err
+	}
+	// read one byte more than we allow to learn whether the body exceeds the limit
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err = b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), b.err
+	}
+	return n, err
+}
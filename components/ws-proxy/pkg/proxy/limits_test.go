@@ -0,0 +1,188 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestRouteLimits(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if size := req.URL.Query().Get("respond"); size != "" {
+			n, _ := strconv.Atoi(size)
+			if req.URL.Query().Get("chunked") == "" {
+				w.Header().Set("Content-Length", size)
+			}
+			if padding, _ := strconv.Atoi(req.URL.Query().Get("padding")); padding > 0 {
+				w.Header().Set("X-Padding", strings.Repeat("x", padding))
+			}
+			w.Write(bytes.Repeat([]byte("x"), n))
+			return
+		}
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "read %d bytes", len(body))
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	limits := &RouteLimitsConfig{
+		MaxRequestHeaderBytes:  512,
+		MaxRequestBodyBytes:    1024,
+		BufferRequestBodyBytes: 256,
+		MaxResponseHeaderBytes: 512,
+		MaxResponseBodyBytes:   1024,
+	}
+	// unsized hides the length of a body so that it is sent chunked
+	type unsized struct{ io.Reader }
+
+	tests := []struct {
+		Name           string
+		Request        *http.Request
+		ExpectedStatus int
+		ExpectedBody   string
+	}{
+		{
+			Name:           "small body",
+			Request:        httptest.NewRequest("POST", "http://example.com/", strings.NewReader(strings.Repeat("x", 100))),
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   "read 100 bytes",
+		},
+		{
+			Name:           "streamed body",
+			Request:        httptest.NewRequest("POST", "http://example.com/", strings.NewReader(strings.Repeat("x", 1024))),
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   "read 1024 bytes",
+		},
+		{
+			Name:           "body too large",
+			Request:        httptest.NewRequest("POST", "http://example.com/", strings.NewReader(strings.Repeat("x", 1025))),
+			ExpectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			Name:           "chunked body too large",
+			Request:        httptest.NewRequest("POST", "http://example.com/", unsized{strings.NewReader(strings.Repeat("x", 64*1024))}),
+			ExpectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			Name: "headers too large",
+			Request: func() *http.Request {
+				req := httptest.NewRequest("GET", "http://example.com/", nil)
+				req.Header.Set("X-Padding", strings.Repeat("x", 512))
+				return req
+			}(),
+			ExpectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			Name:           "response",
+			Request:        httptest.NewRequest("GET", "http://example.com/?respond=1024", nil),
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   strings.Repeat("x", 1024),
+		},
+		{
+			Name:           "response too large",
+			Request:        httptest.NewRequest("GET", "http://example.com/?respond=1025", nil),
+			ExpectedStatus: http.StatusBadGateway,
+		},
+		{
+			Name:           "response headers too large",
+			Request:        httptest.NewRequest("GET", "http://example.com/?respond=1&padding=512", nil),
+			ExpectedStatus: http.StatusBadGateway,
+		},
+		{
+			Name:           "chunked response is cut off",
+			Request:        httptest.NewRequest("GET", "http://example.com/?respond=64000&chunked=true", nil),
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   strings.Repeat("x", 1024),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := config
+			rhc, err := NewRouteHandlerConfig(&cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rc := RouteConfig{Limits: limits}
+			resolver := func(*Config, *http.Request) (*url.URL, error) { return upstreamURL, nil }
+			handler := routeHandler(rc, proxyPass(rhc, resolver, withRoute(rc)))
+
+			rec := httptest.NewRecorder()
+			func() {
+				// the reverse proxy aborts responses it cannot copy completely
+				defer func() {
+					if r := recover(); r != nil && r != http.ErrAbortHandler {
+						panic(r)
+					}
+				}()
+				handler.ServeHTTP(rec, test.Request)
+			}()
+
+			if rec.Code != test.ExpectedStatus {
+				t.Errorf("unexpected status %d, expected %d", rec.Code, test.ExpectedStatus)
+			}
+			if test.ExpectedBody != "" && rec.Body.String() != test.ExpectedBody {
+				t.Errorf("unexpected body of %d bytes", rec.Body.Len())
+			}
+		})
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	errLimit := xerrors.New("limit")
+	tests := []struct {
+		Name          string
+		Content       string
+		Limit         int64
+		ExpectedRead  string
+		ExpectedError error
+	}{
+		{Name: "below limit", Content: "hello", Limit: 10, ExpectedRead: "hello"},
+		{Name: "at limit", Content: "hello", Limit: 5, ExpectedRead: "hello"},
+		{Name: "above limit", Content: "hello world", Limit: 5, ExpectedRead: "hello", ExpectedError: errLimit},
+		{Name: "zero limit", Content: "hello", Limit: 0, ExpectedError: errLimit},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			body := &limitedBody{ReadCloser: ioutil.NopCloser(strings.NewReader(test.Content)), remaining: test.Limit, err: errLimit}
+			read, err := ioutil.ReadAll(body)
+			if string(read) != test.ExpectedRead {
+				t.Errorf("unexpected content %q, expected %q", read, test.ExpectedRead)
+			}
+			if err != test.ExpectedError {
+				t.Errorf("unexpected error %v, expected %v", err, test.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestRouteLimitsValidate(t *testing.T) {
+	invalid := []*RouteLimitsConfig{
+		{MaxRequestBodyBytes: -1},
+		{MaxRequestBodyBytes: 1024, BufferRequestBodyBytes: 2048},
+	}
+	for _, limits := range invalid {
+		if err := limits.Validate(); err == nil {
+			t.Errorf("expected limits %+v to be invalid", limits)
+		}
+	}
+	if err := (&RouteLimitsConfig{BufferRequestBodyBytes: 2048}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net"
+	"strconv"
+
+	"golang.org/x/xerrors"
+)
+
+// ValidateListenAddress makes sure an address is something we can listen on, e.g. ":8080", "0.0.0.0:8080" or "[::]:8080".
+// IPv6 addresses must be bracketed.
+func ValidateListenAddress(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return xerrors.Errorf("invalid listen address %s: %w", addr, err)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return xerrors.Errorf("invalid port in listen address %s", addr)
+	}
+	return nil
+}
+
+// listenNetwork returns the network we listen on for an address. Explicit IPv4 and IPv6 addresses only listen on
+// their own address family, so that e.g. "0.0.0.0:8080" and "[::]:8080" can be combined for dual-stack listening.
+// Addresses without host listen on all address families the node supports.
+func listenNetwork(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return "tcp"
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return "tcp"
+	case ip.To4() != nil:
+		return "tcp4"
+	default:
+		return "tcp6"
+	}
+}
+
+// listen opens a listener on each address. If one of them fails, all others are closed again.
+func listen(addrs []string) ([]net.Listener, error) {
+	res := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		ln, err := net.Listen(listenNetwork(addr), addr)
+		if err != nil {
+			for _, l := range res {
+				l.Close()
+			}
+			return nil, xerrors.Errorf("cannot listen on %s: %w", addr, err)
+		}
+		res = append(res, ln)
+	}
+	return res, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net"
+	"testing"
+)
+
+func TestValidateListenAddress(t *testing.T) {
+	tests := []struct {
+		Addr  string
+		Error bool
+	}{
+		{Addr: ":8080"},
+		{Addr: "0.0.0.0:8080"},
+		{Addr: "[::]:8080"},
+		{Addr: "[fd00::1]:8080"},
+		{Addr: "localhost:8080"},
+		{Addr: "8080", Error: true},
+		{Addr: ":::8080", Error: true},
+		{Addr: ":70000", Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Addr, func(t *testing.T) {
+			err := ValidateListenAddress(test.Addr)
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestListenNetwork(t *testing.T) {
+	tests := []struct {
+		Addr     string
+		Expected string
+	}{
+		{":8080", "tcp"},
+		{"0.0.0.0:8080", "tcp4"},
+		{"[::]:8080", "tcp6"},
+		{"[fd00::1]:8080", "tcp6"},
+		{"localhost:8080", "tcp"},
+	}
+	for _, test := range tests {
+		t.Run(test.Addr, func(t *testing.T) {
+			if act := listenNetwork(test.Addr); act != test.Expected {
+				t.Errorf("listenNetwork() = %q, expected %q", act, test.Expected)
+			}
+		})
+	}
+}
+
+func TestListenDualStack(t *testing.T) {
+	probe, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 is not available: %v", err)
+	}
+	probe.Close()
+
+	// find a port that's free on IPv4 - with IPv6 only sockets the same port is free on IPv6, too
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+
+	lns, err := listen([]string{net.JoinHostPort("0.0.0.0", port), net.JoinHostPort("::", port)})
+	if err != nil {
+		t.Fatalf("cannot listen on IPv4 and IPv6 separately: %v", err)
+	}
+	defer func() {
+		for _, l := range lns {
+			l.Close()
+		}
+	}()
+
+	for _, addr := range []string{net.JoinHostPort("127.0.0.1", port), net.JoinHostPort("::1", port)} {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Errorf("cannot connect to %s: %v", addr, err)
+			continue
+		}
+		conn.Close()
+	}
+}
+
+func TestListenClosesOnFailure(t *testing.T) {
+	taken, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	free, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	freeAddr := free.Addr().String()
+	free.Close()
+
+	_, err = listen([]string{freeAddr, taken.Addr().String()})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// the first listener must have been closed again
+	ln, err := net.Listen("tcp4", freeAddr)
+	if err != nil {
+		t.Fatalf("listener on %s was not closed: %v", freeAddr, err)
+	}
+	ln.Close()
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// maintenanceHeader tells API clients about an announced cluster maintenance
+	maintenanceHeader = "X-Gitpod-Maintenance"
+
+	// builtinPageMaintenanceBanner is the template of the banner we inject into IDE pages during a maintenance
+	builtinPageMaintenanceBanner = "maintenance-banner.html"
+
+	// maxBannerInjectionSize is the largest HTML page we inject the maintenance banner into
+	maxBannerInjectionSize = 2 << 20
+)
+
+// MaintenanceInfo describes an announced cluster maintenance
+type MaintenanceInfo struct {
+	Message string
+	// StartsAt is when the maintenance begins. Zero if unknown.
+	StartsAt time.Time
+	// EndsAt is when the maintenance is expected to be over. Zero if unknown.
+	EndsAt time.Time
+}
+
+// HeaderValue produces the value of the X-Gitpod-Maintenance header, e.g.
+// "enabled; start=2021-04-01T10:00:00Z; end=2021-04-01T12:00:00Z"
+func (m *MaintenanceInfo) HeaderValue() string {
+	segs := []string{"enabled"}
+	if !m.StartsAt.IsZero() {
+		segs = append(segs, "start="+m.StartsAt.UTC().Format(time.RFC3339))
+	}
+	if !m.EndsAt.IsZero() {
+		segs = append(segs, "end="+m.EndsAt.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(segs, "; ")
+}
+
+// MaintenanceProvider knows if a cluster maintenance is announced
+type MaintenanceProvider interface {
+	// Maintenance returns the announced maintenance, or nil if there's none
+	Maintenance() *MaintenanceInfo
+}
+
+// WithMaintenanceNotice informs users about announced cluster maintenance
+func WithMaintenanceNotice(provider MaintenanceProvider) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.Maintenance = provider
+	}
+}
+
+// maintenanceHeaderHandler sets the X-Gitpod-Maintenance header on all responses while a maintenance is announced
+func maintenanceHeaderHandler(config *RouteHandlerConfig) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if config.Maintenance == nil {
+			return h
+		}
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if m := config.Maintenance.Maintenance(); m != nil {
+				resp.Header().Set(maintenanceHeader, m.HeaderValue())
+				if isDocumentRequest(req) {
+					// We need the uncompressed page to inject the maintenance banner. Without this header the
+					// transport asks for and decompresses gzip transparently. We compress the response ourselves.
+					req.Header.Del("Accept-Encoding")
+				}
+			}
+			h.ServeHTTP(resp, req)
+		})
+	}
+}
+
+// withMaintenanceBanner injects the maintenance banner into IDE pages while a maintenance is announced
+func withMaintenanceBanner(config *RouteHandlerConfig) proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		if config.Maintenance == nil || config.MaintenanceBanner == nil {
+			return
+		}
+		cfg.appendResponseHandler(func(resp *http.Response, req *http.Request) error {
+			m := config.Maintenance.Maintenance()
+			if m == nil || !isDocumentRequest(req) || !isInjectableHTML(resp) {
+				return nil
+			}
+			return injectMaintenanceBanner(config.MaintenanceBanner, m, resp)
+		})
+	}
+}
+
+func injectMaintenanceBanner(tpl *template.Template, m *MaintenanceInfo, resp *http.Response) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBannerInjectionSize+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxBannerInjectionSize {
+		// too large to buffer - pass the page through untouched
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var banner bytes.Buffer
+	err = tpl.Execute(&banner, m)
+	if err != nil {
+		log.WithError(err).Warn("cannot render maintenance banner")
+		return nil
+	}
+
+	pos := bodyContentOffset(body)
+	if pos < 0 {
+		return nil
+	}
+	modified := make([]byte, 0, len(body)+banner.Len())
+	modified = append(modified, body[:pos]...)
+	modified = append(modified, banner.Bytes()...)
+	modified = append(modified, body[pos:]...)
+
+	resp.Body = io.NopCloser(bytes.NewReader(modified))
+	resp.ContentLength = int64(len(modified))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(modified)))
+	// the banner must disappear once the maintenance is over
+	resp.Header.Set("Cache-Control", "no-cache")
+	resp.Header.Del("ETag")
+	resp.Header.Del("Last-Modified")
+	return nil
+}
+
+// bodyContentOffset returns the position right after the opening body tag, or -1 if there is none
+func bodyContentOffset(page []byte) int {
+	start := bytes.Index(bytes.ToLower(page), []byte("<body"))
+	if start < 0 {
+		return -1
+	}
+	end := bytes.IndexByte(page[start:], '>')
+	if end < 0 {
+		return -1
+	}
+	return start + end + 1
+}
+
+// isDocumentRequest returns true if the browser navigates to a page, as opposed to loading an iframe or resource
+func isDocumentRequest(req *http.Request) bool {
+	if !isNavigationRequest(req) {
+		return false
+	}
+	dest := req.Header.Get("Sec-Fetch-Dest")
+	return dest == "" || dest == "document"
+}
+
+func isInjectableHTML(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	if enc := resp.Header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		return false
+	}
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html")
+}
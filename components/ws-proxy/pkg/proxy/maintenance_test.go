@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeMaintenanceProvider struct {
+	m *MaintenanceInfo
+}
+
+func (f *fakeMaintenanceProvider) Maintenance() *MaintenanceInfo { return f.m }
+
+func TestMaintenanceNotice(t *testing.T) {
+	const page = `<html><head></head><BODY class="ide"><p>IDE</p></BODY></html>`
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("ETag", `"abc"`)
+		io.WriteString(w, page)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	maintenance := &MaintenanceInfo{
+		Message:  "Cluster upgrade",
+		StartsAt: time.Date(2021, 4, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		Name        string
+		Maintenance *MaintenanceInfo
+		Path        string
+		FetchMode   string
+		FetchDest   string
+		Header      string
+		Banner      bool
+	}{
+		{
+			Name:      "no maintenance",
+			Path:      "/",
+			FetchMode: "navigate",
+			FetchDest: "document",
+		},
+		{
+			Name:        "IDE page",
+			Maintenance: maintenance,
+			Path:        "/",
+			FetchMode:   "navigate",
+			FetchDest:   "document",
+			Header:      "enabled; start=2021-04-01T10:00:00Z",
+			Banner:      true,
+		},
+		{
+			Name:        "iframe",
+			Maintenance: maintenance,
+			Path:        "/",
+			FetchMode:   "navigate",
+			FetchDest:   "iframe",
+			Header:      "enabled; start=2021-04-01T10:00:00Z",
+		},
+		{
+			Name:        "API call",
+			Maintenance: maintenance,
+			Path:        "/api",
+			FetchMode:   "cors",
+			FetchDest:   "empty",
+			Header:      "enabled; start=2021-04-01T10:00:00Z",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := config
+			rhc, err := NewRouteHandlerConfig(&cfg, WithMaintenanceNotice(&fakeMaintenanceProvider{test.Maintenance}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolver := func(*Config, *http.Request) (*url.URL, error) { return upstreamURL, nil }
+			handler := maintenanceHeaderHandler(rhc)(proxyPass(rhc, resolver, withMaintenanceBanner(rhc)))
+
+			req := httptest.NewRequest("GET", "http://example.com"+test.Path, nil)
+			req.Header.Set("Sec-Fetch-Mode", test.FetchMode)
+			req.Header.Set("Sec-Fetch-Dest", test.FetchDest)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if h := rec.Header().Get(maintenanceHeader); h != test.Header {
+				t.Errorf("unexpected %s header: %q", maintenanceHeader, h)
+			}
+
+			body := rec.Body.String()
+			hasBanner := strings.Contains(body, "gitpod-maintenance-banner")
+			if hasBanner != test.Banner {
+				t.Fatalf("unexpected banner presence %v: %s", hasBanner, body)
+			}
+			if !test.Banner {
+				return
+			}
+			if !strings.HasPrefix(body, `<html><head></head><BODY class="ide">`) || !strings.HasSuffix(body, `<p>IDE</p></BODY></html>`) {
+				t.Errorf("banner was not injected at the start of the body: %s", body)
+			}
+			if !strings.Contains(body, "Cluster upgrade") || !strings.Contains(body, "Apr 1, 10:00 UTC") {
+				t.Errorf("banner does not describe the maintenance: %s", body)
+			}
+			if rec.Header().Get("ETag") != "" || rec.Header().Get("Cache-Control") != "no-cache" {
+				t.Errorf("page with banner is cacheable: %v", rec.Header())
+			}
+		})
+	}
+}
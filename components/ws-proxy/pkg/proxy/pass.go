@@ -5,14 +5,15 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"syscall"
-	"time"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
@@ -26,6 +27,8 @@ type proxyPassConfig struct {
 	ResponseHandler []responseHandler
 	ErrorHandler    errorHandler
 	Transport       http.RoundTripper
+	// Streaming disables response buffering, e.g. for server-sent streams
+	Streaming bool
 }
 
 func (ppc *proxyPassConfig) appendResponseHandler(handler responseHandler) {
@@ -72,11 +75,19 @@ func proxyPass(config *RouteHandlerConfig, resolver targetResolver, opts ...prox
 			return
 		}
 
-		var originalURL = *req.URL
+		var (
+			originalURL = *req.URL
+			policy      = getHeaderPolicy(req.Context())
+			experiments = getExperimentAssignments(req.Context())
+		)
 
 		// TODO(cw): we should cache the proxy for some time for each target URL
 		proxy := httputil.NewSingleHostReverseProxy(targetURL)
 		proxy.Transport = h.Transport
+		if h.Streaming || isGRPCRequest(req) {
+			// gRPC streams messages in both directions - we must not buffer them
+			proxy.FlushInterval = -1
+		}
 		proxy.ModifyResponse = func(resp *http.Response) error {
 			url := resp.Request.URL
 			if url == nil {
@@ -95,28 +106,85 @@ func proxyPass(config *RouteHandlerConfig, resolver targetResolver, opts ...prox
 					return err
 				}
 			}
+			policy.ApplyResponse(resp)
+			experiments.ApplyResponse(resp)
 
 			return nil
 		}
 
 		proxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
+			if xerrors.Is(err, errRequestBodyTooLarge) {
+				getLog(req.Context()).Debug("request body exceeds the route's limit")
+				http.Error(rw, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if xerrors.Is(err, errResponseTooLarge) {
+				log.WithField("url", originalURL.String()).WithError(err).Warn("upstream response exceeds the route's limit")
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			if isGRPCRequest(req) {
+				// gRPC clients can make sense of neither error pages nor redirects
+				log.WithField("url", originalURL.String()).WithError(err).Debug("proxied gRPC request failed")
+				serveGRPCError(rw, grpcStatusUnavailable, connectErrorToCause(err))
+				return
+			}
 			if h.ErrorHandler != nil {
 				req.URL = &originalURL
 				h.ErrorHandler(w, req, err)
 				return
 			}
 
+			if xerrors.Is(err, errUpstreamStarting) {
+				// the workspace does not accept connections yet - users are better off with the starting page than a raw 502
+				req.URL = &originalURL
+				rw.Header().Set("Retry-After", "1")
+				serveErrorPage(config.ErrorPages, rw, req, ErrorPageWorkspaceStarting, http.StatusServiceUnavailable)
+				return
+			}
+
 			log.WithField("url", originalURL.String()).WithError(err).Error("proxied request failed")
+			if isTimeoutError(err) {
+				req.URL = &originalURL
+				serveErrorPage(config.ErrorPages, rw, req, ErrorPageUpstreamTimeout, http.StatusGatewayTimeout)
+				return
+			}
 			rw.WriteHeader(http.StatusBadGateway)
 		}
 
 		getLog(req.Context()).WithField("targetURL", targetURL.String()).Debug("proxy-passing request")
+		policy.ApplyRequest(req)
+		experiments.ApplyRequest(req)
+		injectTraceHeaders(req)
 		proxy.ServeHTTP(w, req)
 	}
 }
 
 func isWebsocketRequest(req *http.Request) bool {
-	return strings.ToLower(req.Header.Get("Connection")) == "upgrade" && strings.ToLower(req.Header.Get("Upgrade")) == "websocket"
+	return strings.Contains(strings.ToLower(req.Header.Get("Connection")), "upgrade") && strings.ToLower(req.Header.Get("Upgrade")) == "websocket"
+}
+
+func isGRPCRequest(req *http.Request) bool {
+	return strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
+}
+
+// grpcStatusUnavailable is the gRPC status code for UNAVAILABLE
+const grpcStatusUnavailable = 14
+
+// serveGRPCError responds with a trailers-only gRPC response carrying the status code and message
+func serveGRPCError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	w.Header().Set("Grpc-Message", url.PathEscape(msg))
+	w.WriteHeader(http.StatusOK)
+}
+
+func isTimeoutError(err error) bool {
+	if xerrors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netError net.Error
+	return xerrors.As(err, &netError) && netError.Timeout()
 }
 
 func connectErrorToCause(err error) string {
@@ -171,24 +239,6 @@ func withErrorHandler(h errorHandler) proxyPassOpt {
 	}
 }
 
-func createDefaultTransport(config *TransportConfig) *http.Transport {
-	// TODO equivalent of client_max_body_size 2048m; necessary ???
-	// this is based on http.DefaultTransport, with some values exposed to config
-	return &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   time.Duration(config.ConnectTimeout), // default: 30s
-			KeepAlive: 30 * time.Second,
-			DualStack: true,
-		}).DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          config.MaxIdleConns,                   // default: 100
-		IdleConnTimeout:       time.Duration(config.IdleConnTimeout), // default: 90s
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-}
-
 // tell the browser to cache for 1 year and don't ask the server during this period
 func withLongTermCaching() proxyPassOpt {
 	return func(cfg *proxyPassConfig) {
@@ -199,6 +249,13 @@ func withLongTermCaching() proxyPassOpt {
 	}
 }
 
+// withStreaming flushes responses to the client as soon as we receive them
+func withStreaming() proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		cfg.Streaming = true
+	}
+}
+
 func withXFrameOptionsFilter() proxyPassOpt {
 	return func(cfg *proxyPassConfig) {
 		cfg.appendResponseHandler(func(resp *http.Response, req *http.Request) error {
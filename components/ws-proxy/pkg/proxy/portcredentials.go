@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+)
+
+// internalHeaderPrefix is the prefix of the headers Gitpod uses internally, e.g. X-Gitpod-Owner-Token
+const internalHeaderPrefix = "X-Gitpod-"
+
+// PortCredentialsConfig configures which Gitpod cookies and internal headers of a request reach the applications on
+// exposed ports. By default we forward neither, so that applications cannot harvest the credentials of their visitors.
+type PortCredentialsConfig struct {
+	// AllowCookies are the names of Gitpod cookies we forward nonetheless. The session, owner and port auth cookies are never forwarded.
+	AllowCookies []string `json:"allowCookies,omitempty"`
+	// AllowHeaders are the X-Gitpod-* headers clients send which we forward nonetheless. The owner token is never forwarded.
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *PortCredentialsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.AllowCookies, validation.Each(validation.Required)),
+		validation.Field(&c.AllowHeaders, validation.Each(validation.Required, validation.By(func(value interface{}) error {
+			if strings.EqualFold(value.(string), workspaceOwnerTokenHeader) {
+				return xerrors.Errorf("%s must not be forwarded", workspaceOwnerTokenHeader)
+			}
+			return nil
+		}))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid port credentials config: %w", err)
+	}
+	return nil
+}
+
+// removeInternalHeaders removes all X-Gitpod-* headers clients sent, except for the allowed ones.
+// The headers we set for the upstream ourselves are added later on.
+func removeInternalHeaders(h http.Header, cfg *PortCredentialsConfig) {
+	for name := range h {
+		if !strings.HasPrefix(http.CanonicalHeaderKey(name), internalHeaderPrefix) {
+			continue
+		}
+		if cfg != nil && containsFold(cfg.AllowHeaders, name) && !strings.EqualFold(name, workspaceOwnerTokenHeader) {
+			continue
+		}
+		h.Del(name)
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
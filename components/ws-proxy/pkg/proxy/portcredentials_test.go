@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRemoveInternalHeaders(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Config   *PortCredentialsConfig
+		Expected http.Header
+	}{
+		{
+			Name:     "no config",
+			Expected: http.Header{"Accept": {"text/html"}},
+		},
+		{
+			Name:     "allowed header",
+			Config:   &PortCredentialsConfig{AllowHeaders: []string{"x-gitpod-instance-id"}},
+			Expected: http.Header{"Accept": {"text/html"}, "X-Gitpod-Instance-Id": {"some-instance"}},
+		},
+		{
+			Name:     "owner token is never forwarded",
+			Config:   &PortCredentialsConfig{AllowHeaders: []string{"X-Gitpod-Owner-Token"}},
+			Expected: http.Header{"Accept": {"text/html"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			h := http.Header{
+				"Accept":               {"text/html"},
+				"X-Gitpod-Owner-Token": {"some-token"},
+				"X-Gitpod-Instance-Id": {"some-instance"},
+			}
+			removeInternalHeaders(h, test.Config)
+			if diff := cmp.Diff(test.Expected, h); diff != "" {
+				t.Errorf("unexpected headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	err := (&PortCredentialsConfig{AllowHeaders: []string{"X-Gitpod-Owner-Token"}}).Validate()
+	if err == nil {
+		t.Error("expected allowing the owner token to be invalid")
+	}
+}
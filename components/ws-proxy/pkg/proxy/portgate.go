@@ -0,0 +1,333 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	defaultPortGateTTL          = 5 * time.Second
+	defaultPortGateNotServedTTL = 1 * time.Second
+	defaultPortGateTimeout      = 2 * time.Second
+
+	// portGateMaxChecks is the number of cached checks above which we drop the expired ones
+	portGateMaxChecks = 1024
+	// portGateRetryAfter tells clients when to try a port which is not served again, e.g. while the application restarts
+	portGateRetryAfter = "1"
+	// portGateStatusPath is supervisor's REST endpoint which lists the ports in the workspace
+	portGateStatusPath = "/_supervisor/v1/status/ports"
+
+	portGateOutcomeServed        = "served"
+	portGateOutcomeNotServed     = "not-served"
+	portGateOutcomeWrongProtocol = "wrong-protocol"
+	portGateOutcomeError         = "error"
+)
+
+// PortGateConfig configures how we confirm with supervisor that a workspace port is served before we route to it.
+// Without the confirmation, users who open a port while its application restarts see a connection refused error.
+type PortGateConfig struct {
+	// TTL is how long we remember that a port is served. Defaults to 5 seconds.
+	TTL util.Duration `json:"ttl,omitempty"`
+	// NotServedTTL is how long we remember that a port is not served, or that we could not tell. Defaults to 1 second.
+	NotServedTTL util.Duration `json:"notServedTTL,omitempty"`
+	// Timeout limits a single check. Defaults to 2 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+	// CheckProtocol makes us also probe served ports with an HTTP request, so that ports which speak something
+	// else, e.g. TLS, get an error page instead of a broken response.
+	CheckProtocol bool `json:"checkProtocol,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *PortGateConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.TTL, validation.Min(util.Duration(0))),
+		validation.Field(&c.NotServedTTL, validation.Min(util.Duration(0))),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid port gate config: %w", err)
+	}
+	return nil
+}
+
+// PortGate confirms with the supervisor of a workspace that a port is served before we route requests to it,
+// and caches the outcome briefly. If we cannot reach supervisor we route anyway.
+type PortGate struct {
+	Config             PortGateConfig
+	WorkspacePodConfig *WorkspacePodConfig
+	Client             *http.Client
+
+	checksTotal *prometheus.CounterVec
+	now         func() time.Time
+
+	mu     sync.Mutex
+	checks map[portGateKey]*portGateCheck
+}
+
+type portGateKey struct {
+	WorkspaceID string
+	Port        string
+}
+
+type portGateCheck struct {
+	// done is closed once the check completed
+	done    chan struct{}
+	Outcome string
+	Expires time.Time
+}
+
+// NewPortGate creates a new port gate which connects to supervisor and workspace ports using the transport
+func NewPortGate(cfg PortGateConfig, podCfg *WorkspacePodConfig, transport http.RoundTripper) *PortGate {
+	if cfg.TTL == 0 {
+		cfg.TTL = util.Duration(defaultPortGateTTL)
+	}
+	if cfg.NotServedTTL == 0 {
+		cfg.NotServedTTL = util.Duration(defaultPortGateNotServedTTL)
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = util.Duration(defaultPortGateTimeout)
+	}
+
+	return &PortGate{
+		Config:             cfg,
+		WorkspacePodConfig: podCfg,
+		Client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(cfg.Timeout),
+			// the application's redirects are none of our business
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		checksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "port_gate_checks_total",
+			Help: "Checks whether a workspace port is served, by outcome",
+		}, []string{"outcome"}),
+		now:    time.Now,
+		checks: make(map[portGateKey]*portGateCheck),
+	}
+}
+
+// RegisterMetrics registers the port gate metrics
+func (g *PortGate) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(g.checksTotal)
+}
+
+// Handler serves an error page for requests to ports which are not served. resolve produces the URL we'd route
+// a request to, which we probe if the config asks us to check the protocol.
+func (g *PortGate) Handler(pages *ErrorPages, resolve func(req *http.Request) (*url.URL, error)) mux.MiddlewareFunc {
+	if g == nil {
+		return func(h http.Handler) http.Handler { return h }
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			coords := getWorkspaceCoords(req)
+			if coords.ID == "" || coords.Port == "" {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			outcome := g.Check(req.Context(), portGateKey{WorkspaceID: coords.ID, Port: coords.Port}, func() (*url.URL, error) {
+				return resolve(req)
+			})
+			if outcome == portGateOutcomeServed || outcome == portGateOutcomeError {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			getLog(req.Context()).WithField("port", coords.Port).WithField("outcome", outcome).Debug("workspace port is not ready - rejecting request")
+			resp.Header().Set("Cache-Control", "no-store")
+			resp.Header().Set(proxyStatusHeader, string(ErrorPagePortNotFound))
+			status := http.StatusBadGateway
+			if outcome == portGateOutcomeNotServed {
+				resp.Header().Set("Retry-After", portGateRetryAfter)
+				status = http.StatusServiceUnavailable
+			}
+			serveErrorPage(pages, resp, req, ErrorPagePortNotFound, status)
+		})
+	}
+}
+
+// AdmitsConn returns false if supervisor does not see a workspace port served, e.g. while its application restarts.
+// Raw TCP connections may speak any protocol, hence ports which fail the protocol check pass.
+// If the gate is nil, it admits all connections.
+func (g *PortGate) AdmitsConn(ctx context.Context, workspaceID, port string, probeTarget func() (*url.URL, error)) bool {
+	if g == nil {
+		return true
+	}
+	return g.Check(ctx, portGateKey{WorkspaceID: workspaceID, Port: port}, probeTarget) != portGateOutcomeNotServed
+}
+
+// Check returns whether a workspace port is served. Concurrent checks of the same port share one request to supervisor.
+func (g *PortGate) Check(ctx context.Context, key portGateKey, probeTarget func() (*url.URL, error)) string {
+	g.mu.Lock()
+	c, ok := g.checks[key]
+	if ok {
+		select {
+		case <-c.done:
+			if g.now().Before(c.Expires) {
+				g.mu.Unlock()
+				return c.Outcome
+			}
+		default:
+			g.mu.Unlock()
+			select {
+			case <-c.done:
+				return c.Outcome
+			case <-ctx.Done():
+				return portGateOutcomeError
+			}
+		}
+	}
+	if len(g.checks) >= portGateMaxChecks {
+		now := g.now()
+		for k, c := range g.checks {
+			select {
+			case <-c.done:
+				if !now.Before(c.Expires) {
+					delete(g.checks, k)
+				}
+			default:
+			}
+		}
+	}
+	c = &portGateCheck{done: make(chan struct{})}
+	g.checks[key] = c
+	g.mu.Unlock()
+
+	// the check must not fail because the request which started it was cancelled, others wait for it too
+	checkCtx, cancel := context.WithTimeout(context.Background(), time.Duration(g.Config.Timeout))
+	defer cancel()
+	outcome := g.check(checkCtx, key, probeTarget)
+	g.checksTotal.WithLabelValues(outcome).Inc()
+
+	ttl := g.Config.NotServedTTL
+	if outcome == portGateOutcomeServed {
+		ttl = g.Config.TTL
+	}
+	g.mu.Lock()
+	c.Outcome = outcome
+	c.Expires = g.now().Add(time.Duration(ttl))
+	g.mu.Unlock()
+	close(c.done)
+
+	return outcome
+}
+
+func (g *PortGate) check(ctx context.Context, key portGateKey, probeTarget func() (*url.URL, error)) string {
+	port, err := strconv.ParseUint(key.Port, 10, 32)
+	if err != nil {
+		return portGateOutcomeError
+	}
+
+	ports, err := g.portsStatus(ctx, key.WorkspaceID)
+	if err != nil {
+		getLog(ctx).WithError(err).WithField("workspaceId", key.WorkspaceID).Debug("cannot get the ports status from supervisor")
+		return portGateOutcomeError
+	}
+	var served bool
+	for _, p := range ports {
+		if p.Served && (p.LocalPort == uint32(port) || p.GlobalPort == uint32(port)) {
+			served = true
+			break
+		}
+	}
+	if !served {
+		return portGateOutcomeNotServed
+	}
+	if !g.Config.CheckProtocol {
+		return portGateOutcomeServed
+	}
+
+	target, err := probeTarget()
+	if err != nil {
+		return portGateOutcomeError
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.String(), nil)
+	if err != nil {
+		return portGateOutcomeError
+	}
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "malformed HTTP"):
+			return portGateOutcomeWrongProtocol
+		case xerrors.Is(err, syscall.ECONNREFUSED) || xerrors.Is(err, syscall.ECONNRESET):
+			// the application stopped serving the port since supervisor last looked
+			return portGateOutcomeNotServed
+		default:
+			return portGateOutcomeError
+		}
+	}
+	resp.Body.Close()
+	return portGateOutcomeServed
+}
+
+// portStatus is the part of supervisor's PortsStatus we care about
+type portStatus struct {
+	LocalPort  uint32 `json:"localPort"`
+	GlobalPort uint32 `json:"globalPort"`
+	Served     bool   `json:"served"`
+}
+
+// portsStatus asks the supervisor of a workspace for the ports in the workspace
+func (g *PortGate) portsStatus(ctx context.Context, workspaceID string) ([]portStatus, error) {
+	upstream, err := buildWorkspacePodURL(g.WorkspacePodConfig.ServiceTemplate, workspaceID, fmt.Sprint(g.WorkspacePodConfig.SupervisorPort))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(upstream.String(), "/")+portGateStatusPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("supervisor responded with %d", resp.StatusCode)
+	}
+
+	// PortsStatus streams, hence the REST gateway wraps every message in a result or error
+	var msg struct {
+		Result *struct {
+			Ports []portStatus `json:"ports"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&msg)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode ports status: %w", err)
+	}
+	if msg.Error != nil {
+		return nil, xerrors.Errorf("supervisor failed: %s", msg.Error.Message)
+	}
+	if msg.Result == nil {
+		return nil, xerrors.Errorf("supervisor sent no ports status")
+	}
+	return msg.Result.Ports, nil
+}
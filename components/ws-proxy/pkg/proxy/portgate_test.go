@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type fakePortsSupervisor struct {
+	// Ports is the JSON of the ports supervisor reports
+	Ports  atomic.Value
+	Status int32
+	Calls  int32
+}
+
+func newPortGateTestSupervisor(t *testing.T) (*PortGate, *fakePortsSupervisor) {
+	supervisor := &fakePortsSupervisor{Status: http.StatusOK}
+	supervisor.Ports.Store(`[]`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&supervisor.Calls, 1)
+		if r.URL.Path != portGateStatusPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(int(atomic.LoadInt32(&supervisor.Status)))
+		fmt.Fprintf(w, `{"result":{"ports":%s}}`+"\n", supervisor.Ports.Load())
+	}))
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	gate := NewPortGate(PortGateConfig{}, &WorkspacePodConfig{
+		ServiceTemplate: "http://" + u.Host,
+		SupervisorPort:  22999,
+	}, http.DefaultTransport)
+	return gate, supervisor
+}
+
+func TestPortGateCheck(t *testing.T) {
+	gate, supervisor := newPortGateTestSupervisor(t)
+	now := time.Now()
+	gate.now = func() time.Time { return now }
+	key := portGateKey{WorkspaceID: "amaranth-smelt-9ba20cc1", Port: "3000"}
+	noProbe := func() (*url.URL, error) {
+		t.Error("probed the port although the config does not ask us to")
+		return nil, fmt.Errorf("no probe")
+	}
+	check := func(expected string) {
+		t.Helper()
+		if act := gate.Check(context.Background(), key, noProbe); act != expected {
+			t.Errorf("Check() = %s, expected %s", act, expected)
+		}
+	}
+
+	supervisor.Ports.Store(`[{"localPort":3000,"globalPort":3000,"served":false}]`)
+	check(portGateOutcomeNotServed)
+
+	// the application came back, but we remember that the port was not served for a moment
+	supervisor.Ports.Store(`[{"localPort":3000,"globalPort":3000,"served":true}]`)
+	check(portGateOutcomeNotServed)
+	now = now.Add(time.Duration(gate.Config.NotServedTTL))
+	check(portGateOutcomeServed)
+	if calls := atomic.LoadInt32(&supervisor.Calls); calls != 2 {
+		t.Errorf("expected two calls to supervisor, got %d", calls)
+	}
+
+	// ports which bind to localhost are served through supervisor's proxy on their global port
+	key.Port = "60000"
+	supervisor.Ports.Store(`[{"localPort":3001,"globalPort":60000,"served":true}]`)
+	check(portGateOutcomeServed)
+
+	key.Port = "8080"
+	atomic.StoreInt32(&supervisor.Status, http.StatusInternalServerError)
+	check(portGateOutcomeError)
+}
+
+func TestPortGateCheckProtocol(t *testing.T) {
+	gate, supervisor := newPortGateTestSupervisor(t)
+	gate.Config.CheckProtocol = true
+	supervisor.Ports.Store(`[{"localPort":3000,"globalPort":3000,"served":true}]`)
+
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer app.Close()
+
+	// a port which speaks TLS answers a plain HTTP request with a TLS alert
+	tlsApp, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tlsApp.Close()
+	go func() {
+		for {
+			conn, err := tlsApp.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("\x15\x03\x01\x00\x02\x02\x46"))
+			conn.Close()
+		}
+	}()
+
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+
+	tests := []struct {
+		Name     string
+		Target   string
+		Expected string
+	}{
+		{Name: "http", Target: app.URL, Expected: portGateOutcomeServed},
+		{Name: "tls", Target: "http://" + tlsApp.Addr().String(), Expected: portGateOutcomeWrongProtocol},
+		{Name: "stopped since", Target: gone.URL, Expected: portGateOutcomeNotServed},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			key := portGateKey{WorkspaceID: "amaranth-smelt-9ba20cc1-" + test.Name, Port: "3000"}
+			act := gate.Check(context.Background(), key, func() (*url.URL, error) {
+				return url.Parse(test.Target)
+			})
+			if act != test.Expected {
+				t.Errorf("Check() = %s, expected %s", act, test.Expected)
+			}
+		})
+	}
+}
+
+func TestPortGateHandler(t *testing.T) {
+	gate, supervisor := newPortGateTestSupervisor(t)
+	supervisor.Ports.Store(`[{"localPort":3000,"globalPort":3000,"served":true}]`)
+
+	handler := gate.Handler(nil, func(req *http.Request) (*url.URL, error) {
+		return nil, fmt.Errorf("no probe")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		Name       string
+		Port       string
+		Status     int
+		RetryAfter string
+	}{
+		{Name: "served", Port: "3000", Status: http.StatusTeapot},
+		{Name: "not served", Port: "8080", Status: http.StatusServiceUnavailable, RetryAfter: portGateRetryAfter},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://"+test.Port+"-amaranth-smelt-9ba20cc1.ws.gitpod.io/", nil)
+			req = mux.SetURLVars(req, map[string]string{
+				workspaceIDIdentifier:   "amaranth-smelt-9ba20cc1",
+				workspacePortIdentifier: test.Port,
+			})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("status = %d, expected %d", rec.Code, test.Status)
+			}
+			if act := rec.Header().Get("Retry-After"); act != test.RetryAfter {
+				t.Errorf("Retry-After = %q, expected %q", act, test.RetryAfter)
+			}
+		})
+	}
+}
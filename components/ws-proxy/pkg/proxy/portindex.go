@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"sync/atomic"
+)
+
+// portIndex maps public (proxy) ports to workspace coordinates. Lookups are lock-free and do not allocate:
+// canonical port numbers index an array, and only ports which are no canonical decimal number
+// (which ws-manager never hands out) fall back to a map.
+//
+// Writers must be serialised by the caller, readers may run concurrently with writers.
+type portIndex struct {
+	// byNumber holds a *WorkspaceCoords per port number - nil if the port is not in use
+	byNumber []atomic.Value
+	// other holds the coordinates of non-canonical ports. It is replaced on every write
+	// so that readers never see a map which is being modified.
+	other atomic.Value
+}
+
+func newPortIndex() *portIndex {
+	res := &portIndex{
+		byNumber: make([]atomic.Value, 1<<16),
+	}
+	res.other.Store(map[string]*WorkspaceCoords{})
+	return res
+}
+
+// Get returns the coordinates of a public port
+func (idx *portIndex) Get(port string) (*WorkspaceCoords, bool) {
+	if n, ok := parsePort(port); ok {
+		coords, _ := idx.byNumber[n].Load().(*WorkspaceCoords)
+		return coords, coords != nil
+	}
+	coords, ok := idx.other.Load().(map[string]*WorkspaceCoords)[port]
+	return coords, ok
+}
+
+// Set makes port point to coords
+func (idx *portIndex) Set(port string, coords *WorkspaceCoords) {
+	if n, ok := parsePort(port); ok {
+		idx.byNumber[n].Store(coords)
+		return
+	}
+	idx.updateOther(func(m map[string]*WorkspaceCoords) { m[port] = coords })
+}
+
+// Delete removes a port
+func (idx *portIndex) Delete(port string) {
+	if n, ok := parsePort(port); ok {
+		// atomic.Value cannot hold nil - we store a typed nil instead
+		if idx.byNumber[n].Load() != nil {
+			idx.byNumber[n].Store((*WorkspaceCoords)(nil))
+		}
+		return
+	}
+	if _, ok := idx.other.Load().(map[string]*WorkspaceCoords)[port]; !ok {
+		return
+	}
+	idx.updateOther(func(m map[string]*WorkspaceCoords) { delete(m, port) })
+}
+
+func (idx *portIndex) updateOther(mod func(m map[string]*WorkspaceCoords)) {
+	old := idx.other.Load().(map[string]*WorkspaceCoords)
+	m := make(map[string]*WorkspaceCoords, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	mod(m)
+	idx.other.Store(m)
+}
+
+// parsePort parses the canonical decimal representation of a port number, i.e. without sign or leading zeros.
+// Unlike strconv.Atoi it never allocates, not even for invalid input.
+func parsePort(s string) (uint16, bool) {
+	if len(s) == 0 || len(s) > 5 || (s[0] == '0' && len(s) > 1) {
+		return 0, false
+	}
+	var n uint32
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + uint32(c-'0')
+	}
+	if n > 1<<16-1 {
+		return 0, false
+	}
+	return uint16(n), true
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"testing"
+)
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Port     uint16
+		Expected bool
+	}{
+		{"0", 0, true},
+		{"8080", 8080, true},
+		{"65535", 65535, true},
+		{"65536", 0, false},
+		{"99999", 0, false},
+		{"100000", 0, false},
+		{"08080", 0, false},
+		{"+80", 0, false},
+		{"-1", 0, false},
+		{"80a", 0, false},
+		{"", 0, false},
+	}
+	for _, test := range tests {
+		port, ok := parsePort(test.Input)
+		if ok != test.Expected || port != test.Port {
+			t.Errorf("parsePort(%q) = %d, %v; expected %d, %v", test.Input, port, ok, test.Port, test.Expected)
+		}
+	}
+}
+
+func TestPortIndex(t *testing.T) {
+	idx := newPortIndex()
+	var (
+		ide  = &WorkspaceCoords{ID: "ws"}
+		port = &WorkspaceCoords{ID: "ws", Port: "8080"}
+	)
+	for _, p := range []string{"10000", "010000"} {
+		if _, ok := idx.Get(p); ok {
+			t.Errorf("empty index has port %s", p)
+		}
+	}
+
+	idx.Set("10000", ide)
+	idx.Set("010000", port)
+	if coords, ok := idx.Get("10000"); !ok || coords != ide {
+		t.Errorf("unexpected coords for canonical port: %v", coords)
+	}
+	if coords, ok := idx.Get("010000"); !ok || coords != port {
+		t.Errorf("unexpected coords for non-canonical port: %v", coords)
+	}
+
+	idx.Delete("10000")
+	idx.Delete("010000")
+	idx.Delete("20000")
+	for _, p := range []string{"10000", "010000", "20000"} {
+		if coords, ok := idx.Get(p); ok {
+			t.Errorf("deleted port %s still has coords %v", p, coords)
+		}
+	}
+}
@@ -0,0 +1,660 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	// portTokenPathPrefix is the path on the workspace host under which port tokens address a workspace port
+	portTokenPathPrefix = "/t/"
+	// portTokenAPIPath is where the workspace owner issues and revokes port tokens
+	portTokenAPIPath = "/_wsproxy/port-tokens"
+
+	// Used as key for storing the port token in the requests mux.Vars() map
+	portTokenIdentifier = "portToken"
+
+	// portTokenRevocationReload is how often we pick up revocations other proxies wrote to the revocation file
+	portTokenRevocationReload = 10 * time.Second
+
+	// defaultPortTokenMaxAge is how long port tokens are valid unless configured otherwise
+	defaultPortTokenMaxAge = 30 * 24 * time.Hour
+	// maxPortTokenRevocationsPerWorkspace limits the revocations of unexpired tokens we keep per workspace
+	maxPortTokenRevocationsPerWorkspace = 1000
+	// maxPortTokenRevocations limits the revocations of unexpired tokens we keep in total
+	maxPortTokenRevocations = 100000
+
+	// a port token ID consists of a nonce, the token's expiry and a MAC which proves we issued it for the workspace
+	portTokenNonceLen  = 9
+	portTokenExpiryLen = 8
+	portTokenIDMACLen  = 12
+)
+
+var (
+	// errUnknownPortToken is returned when revoking a token we did not issue for the workspace
+	errUnknownPortToken = xerrors.New("unknown port token")
+	// errTooManyPortTokenRevocations is returned when a revocation would exceed the revocation limits
+	errTooManyPortTokenRevocations = xerrors.New("too many revoked port tokens")
+)
+
+// PortTokensConfig configures signed URLs which expose a single workspace port under /t/{token} on the workspace host,
+// e.g. for registering webhook receivers with third-party services. Anyone who has the URL can access the port,
+// irrespective of its visibility, until the workspace owner revokes the token.
+type PortTokensConfig struct {
+	// SecretFile contains the key we sign port tokens with. All proxies of an installation must share the key.
+	SecretFile string `json:"secretFile"`
+	// RevocationFile is where we persist revoked tokens. Proxies which share the file share revocations:
+	// they append to it under a lock held on RevocationFile.lock. If empty, revocations are lost when the proxy restarts.
+	RevocationFile string `json:"revocationFile,omitempty"`
+	// MaxAge is how long port tokens are valid after we issued them. Defaults to 30 days.
+	// We forget revocations once their tokens expired.
+	MaxAge util.Duration `json:"maxAge,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *PortTokensConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.SecretFile, validation.Required, validation.By(validateFileExists(""))),
+		validation.Field(&c.MaxAge, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid port tokens config: %w", err)
+	}
+	return nil
+}
+
+// portTokenClaims is what a port token grants access to
+type portTokenClaims struct {
+	ID          string `json:"i"`
+	WorkspaceID string `json:"w"`
+	Port        uint16 `json:"p"`
+}
+
+// PortToken is a token the workspace owner issued
+type PortToken struct {
+	ID        string    `json:"id"`
+	Port      uint16    `json:"port"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// portTokenRevocation is an entry of the revocation file, which has one JSON encoded revocation per line
+type portTokenRevocation struct {
+	Key       string    `json:"k"`
+	ExpiresAt time.Time `json:"e"`
+}
+
+// PortTokens issues, verifies and revokes port tokens
+type PortTokens struct {
+	Config PortTokensConfig
+
+	key []byte
+
+	mu sync.Mutex
+	// revoked maps the revocation keys of revoked, unexpired tokens to their expiry
+	revoked  map[string]time.Time
+	loadedAt time.Time
+	// modTime and size identify the revocation file content we've loaded, fileEntries is its number of lines
+	modTime     time.Time
+	size        int64
+	fileEntries int
+}
+
+// NewPortTokens creates port tokens signed with the configured secret and loads the revoked tokens
+func NewPortTokens(cfg PortTokensConfig) (*PortTokens, error) {
+	key, err := os.ReadFile(cfg.SecretFile)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read port token secret: %w", err)
+	}
+	key = bytes.TrimSpace(key)
+	if len(key) < 32 {
+		return nil, xerrors.Errorf("port token secret must be at least 32 bytes long")
+	}
+
+	t := &PortTokens{
+		Config:  cfg,
+		key:     key,
+		revoked: make(map[string]time.Time),
+	}
+	err = t.loadRevocations()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func portTokenRevocationKey(workspaceID, id string) string {
+	return workspaceID + "/" + id
+}
+
+func (t *PortTokens) maxAge() time.Duration {
+	if t.Config.MaxAge == 0 {
+		return defaultPortTokenMaxAge
+	}
+	return time.Duration(t.Config.MaxAge)
+}
+
+// Issue produces a new token for a workspace port. The host is the workspace host the token is valid on.
+func (t *PortTokens) Issue(workspaceID string, port uint16, scheme, host string) (*PortToken, error) {
+	expiry := time.Now().Add(t.maxAge()).Truncate(time.Second).UTC()
+	id, err := t.newPortTokenID(workspaceID, expiry)
+	if err != nil {
+		return nil, err
+	}
+	claims := portTokenClaims{
+		ID:          id,
+		WorkspaceID: workspaceID,
+		Port:        port,
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	tkn := base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(t.sign(payload))
+
+	return &PortToken{
+		ID:        claims.ID,
+		Port:      port,
+		URL:       fmt.Sprintf("%s://%s%s%s/", scheme, host, portTokenPathPrefix, tkn),
+		ExpiresAt: expiry,
+	}, nil
+}
+
+func (t *PortTokens) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	_, _ = mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// newPortTokenID produces a token ID which names the token's expiry and proves that we issued it for the workspace.
+// This way we can validate the IDs workspace owners revoke without keeping track of the tokens we issued.
+func (t *PortTokens) newPortTokenID(workspaceID string, expiry time.Time) (string, error) {
+	id := make([]byte, portTokenNonceLen+portTokenExpiryLen, portTokenNonceLen+portTokenExpiryLen+portTokenIDMACLen)
+	_, err := rand.Read(id[:portTokenNonceLen])
+	if err != nil {
+		return "", err
+	}
+	binary.BigEndian.PutUint64(id[portTokenNonceLen:], uint64(expiry.Unix()))
+	id = append(id, t.signID(workspaceID, id)...)
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+func (t *PortTokens) signID(workspaceID string, id []byte) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	_, _ = mac.Write([]byte("port-token-id\x00" + workspaceID + "\x00"))
+	_, _ = mac.Write(id)
+	return mac.Sum(nil)[:portTokenIDMACLen]
+}
+
+// parsePortTokenID returns the expiry of a token ID if we issued it for the workspace
+func (t *PortTokens) parsePortTokenID(workspaceID, id string) (expiry time.Time, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil || len(raw) != portTokenNonceLen+portTokenExpiryLen+portTokenIDMACLen {
+		return time.Time{}, errUnknownPortToken
+	}
+	signed := raw[:portTokenNonceLen+portTokenExpiryLen]
+	if !hmac.Equal(raw[len(signed):], t.signID(workspaceID, signed)) {
+		return time.Time{}, errUnknownPortToken
+	}
+	return time.Unix(int64(binary.BigEndian.Uint64(signed[portTokenNonceLen:])), 0).UTC(), nil
+}
+
+// parsePortToken decodes the claims of a token without verifying its signature
+func parsePortToken(tkn string) (claims *portTokenClaims, payload, signature []byte, err error) {
+	segs := strings.Split(tkn, ".")
+	if len(segs) != 2 {
+		return nil, nil, nil, xerrors.Errorf("malformed port token")
+	}
+	payload, err = base64.RawURLEncoding.DecodeString(segs[0])
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("malformed port token: %w", err)
+	}
+	signature, err = base64.RawURLEncoding.DecodeString(segs[1])
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("malformed port token: %w", err)
+	}
+	claims = &portTokenClaims{}
+	err = json.Unmarshal(payload, claims)
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf("malformed port token: %w", err)
+	}
+	return claims, payload, signature, nil
+}
+
+// Verify returns the claims of a token if we signed it, and it has neither expired nor been revoked
+func (t *PortTokens) Verify(tkn string) (*portTokenClaims, error) {
+	claims, payload, signature, err := parsePortToken(tkn)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(signature, t.sign(payload)) {
+		return nil, xerrors.Errorf("invalid port token signature")
+	}
+	expiry, err := t.parsePortTokenID(claims.WorkspaceID, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(expiry) {
+		return nil, xerrors.Errorf("port token has expired")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if time.Since(t.loadedAt) > portTokenRevocationReload {
+		err := t.reloadRevocationsLocked()
+		if err != nil {
+			log.WithError(err).Warn("cannot reload revoked port tokens")
+		}
+	}
+	if _, revoked := t.revoked[portTokenRevocationKey(claims.WorkspaceID, claims.ID)]; revoked {
+		return nil, xerrors.Errorf("port token has been revoked")
+	}
+	return claims, nil
+}
+
+// Revoke revokes a token of a workspace. Returns errUnknownPortToken if we did not issue the token for the workspace,
+// and errTooManyPortTokenRevocations if the workspace or all workspaces revoked too many unexpired tokens.
+func (t *PortTokens) Revoke(workspaceID, id string) error {
+	expiry, err := t.parsePortTokenID(workspaceID, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if now.After(expiry) {
+		// the token is not valid anymore anyways
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// other proxies must not append to the revocation file until we have: we must respect the limits across all of them
+	unlock, err := t.lockRevocationFile(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = t.reloadRevocations()
+	if err != nil {
+		return err
+	}
+	key := portTokenRevocationKey(workspaceID, id)
+	if _, revoked := t.revoked[key]; revoked {
+		return nil
+	}
+
+	t.prune(now)
+	if len(t.revoked) >= maxPortTokenRevocations {
+		return errTooManyPortTokenRevocations
+	}
+	var perWorkspace int
+	for k := range t.revoked {
+		if strings.HasPrefix(k, workspaceID+"/") {
+			perWorkspace++
+		}
+	}
+	if perWorkspace >= maxPortTokenRevocationsPerWorkspace {
+		return errTooManyPortTokenRevocations
+	}
+
+	err = t.appendRevocation(portTokenRevocation{Key: key, ExpiresAt: expiry})
+	if err != nil {
+		return err
+	}
+	t.revoked[key] = expiry
+
+	if t.fileEntries > 2*len(t.revoked)+maxPortTokenRevocationsPerWorkspace {
+		// most entries of the file belong to expired tokens
+		err = t.compactRevocations()
+		if err != nil {
+			log.WithError(err).Warn("cannot compact revoked port tokens")
+		}
+	}
+	return nil
+}
+
+// prune forgets the revocations of expired tokens. Callers must hold mu.
+func (t *PortTokens) prune(now time.Time) {
+	for k, expiry := range t.revoked {
+		if now.After(expiry) {
+			delete(t.revoked, k)
+		}
+	}
+}
+
+func (t *PortTokens) loadRevocations() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reloadRevocationsLocked()
+}
+
+// lockRevocationFile locks the revocation file against other proxies, exclusively if we're about to modify it.
+// We lock a separate file because compactions replace the revocation file.
+func (t *PortTokens) lockRevocationFile(exclusive bool) (unlock func(), err error) {
+	fn := t.Config.RevocationFile
+	if fn == "" {
+		return func() {}, nil
+	}
+
+	f, err := os.OpenFile(fn+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot lock revoked port tokens: %w", err)
+	}
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	err = syscall.Flock(int(f.Fd()), how)
+	if err != nil {
+		f.Close()
+		return nil, xerrors.Errorf("cannot lock revoked port tokens: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// reloadRevocationsLocked reloads the revocation file under a shared lock. Callers must hold mu.
+func (t *PortTokens) reloadRevocationsLocked() error {
+	unlock, err := t.lockRevocationFile(false)
+	if err != nil {
+		t.loadedAt = time.Now()
+		return err
+	}
+	defer unlock()
+	return t.reloadRevocations()
+}
+
+// reloadRevocations reads the revocation file if it changed. Callers must hold mu and lock the revocation file.
+func (t *PortTokens) reloadRevocations() error {
+	t.loadedAt = time.Now()
+	fn := t.Config.RevocationFile
+	if fn == "" {
+		return nil
+	}
+
+	stat, err := os.Stat(fn)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot read revoked port tokens: %w", err)
+	}
+	if stat.ModTime().Equal(t.modTime) && stat.Size() == t.size {
+		return nil
+	}
+	f, err := os.Open(fn)
+	if err != nil {
+		return xerrors.Errorf("cannot read revoked port tokens: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		now     = time.Now()
+		revoked = make(map[string]time.Time)
+		entries int
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entries++
+		var r portTokenRevocation
+		err := json.Unmarshal(scanner.Bytes(), &r)
+		if err != nil || r.Key == "" {
+			log.WithError(err).Warn("skipping malformed entry of the revoked port tokens")
+			continue
+		}
+		if now.After(r.ExpiresAt) {
+			continue
+		}
+		revoked[r.Key] = r.ExpiresAt
+	}
+	if err := scanner.Err(); err != nil {
+		return xerrors.Errorf("cannot read revoked port tokens: %w", err)
+	}
+
+	// the file is the source of truth once there is one: it has the revocations of all proxies
+	t.revoked = revoked
+	t.fileEntries = entries
+	t.modTime = stat.ModTime()
+	t.size = stat.Size()
+	return nil
+}
+
+// appendRevocation appends a revocation to the revocation file. Callers must hold mu and lock the revocation file exclusively.
+func (t *PortTokens) appendRevocation(r portTokenRevocation) error {
+	fn := t.Config.RevocationFile
+	if fn == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal revoked port token: %w", err)
+	}
+	f, err := os.OpenFile(fn, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot write revoked port token: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot write revoked port token: %w", err)
+	}
+	t.fileEntries++
+	t.updateFileStat()
+	return nil
+}
+
+// compactRevocations rewrites the revocation file with the revocations of unexpired tokens only.
+// Callers must hold mu and lock the revocation file exclusively.
+func (t *PortTokens) compactRevocations() error {
+	fn := t.Config.RevocationFile
+	if fn == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for k, expiry := range t.revoked {
+		line, err := json.Marshal(portTokenRevocation{Key: k, ExpiresAt: expiry})
+		if err != nil {
+			return xerrors.Errorf("cannot marshal revoked port token: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	// write to a temporary file first so that we never leave a half-written file behind
+	tmp, err := os.CreateTemp(filepath.Dir(fn), ".port-tokens-*")
+	if err != nil {
+		return xerrors.Errorf("cannot write revoked port tokens: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(buf.Bytes())
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot write revoked port tokens: %w", err)
+	}
+	err = os.Rename(tmp.Name(), fn)
+	if err != nil {
+		return xerrors.Errorf("cannot write revoked port tokens: %w", err)
+	}
+	t.fileEntries = len(t.revoked)
+	t.updateFileStat()
+	return nil
+}
+
+// updateFileStat records that we've seen the revocation file as it is now. Callers must hold mu.
+func (t *PortTokens) updateFileStat() {
+	if stat, err := os.Stat(t.Config.RevocationFile); err == nil {
+		t.modTime = stat.ModTime()
+		t.size = stat.Size()
+	}
+}
+
+type portTokenContextKey struct{}
+
+// portTokenAuthorized returns true if the request carried a valid port token for the workspace port it addresses
+func portTokenAuthorized(req *http.Request) bool {
+	ok, _ := req.Context().Value(portTokenContextKey{}).(bool)
+	return ok
+}
+
+// Handler verifies the port tokens of requests which the workspace router matched by their /t/{token} path and
+// strips the token from the path, so that the token reaches neither logs nor the workspace.
+// Requests without a port token pass untouched. If t is nil, requests with port tokens are not found.
+func (t *PortTokens) Handler(pages *ErrorPages) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			vars := mux.Vars(req)
+			tkn := vars[portTokenIdentifier]
+			if tkn == "" {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			req.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, portTokenPathPrefix+tkn), "/")
+			req.URL.RawPath = ""
+			req.RequestURI = req.URL.RequestURI()
+			delete(vars, portTokenIdentifier)
+
+			if t == nil {
+				serveErrorPage(pages, resp, req, ErrorPagePortNotFound, http.StatusNotFound)
+				return
+			}
+			claims, err := t.Verify(tkn)
+			if err != nil || claims.WorkspaceID != vars[workspaceIDIdentifier] || strconv.Itoa(int(claims.Port)) != vars[workspacePortIdentifier] {
+				log.WithError(err).WithField("workspaceId", vars[workspaceIDIdentifier]).Debug("rejected port token")
+				serveErrorPage(pages, resp, req, ErrorPagePortNotFound, http.StatusNotFound)
+				return
+			}
+
+			h.ServeHTTP(resp, req.WithContext(context.WithValue(req.Context(), portTokenContextKey{}, true)))
+		})
+	}
+}
+
+// ServeHTTP lets the workspace owner issue (POST {"port": 3000}) and revoke (DELETE <path>/{id}) port tokens.
+// It must run behind the workspace owner authentication.
+func (t *PortTokens) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	coords := getWorkspaceCoords(req)
+	if coords.ID == "" {
+		http.Error(resp, "no workspace", http.StatusNotFound)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodPost:
+		var body struct {
+			Port uint16 `json:"port"`
+		}
+		err := json.NewDecoder(http.MaxBytesReader(resp, req.Body, 1024)).Decode(&body)
+		if err != nil || body.Port == 0 {
+			http.Error(resp, "port is required", http.StatusBadRequest)
+			return
+		}
+		scheme := "https"
+		if req.TLS == nil && req.Header.Get("X-Forwarded-Proto") == "http" {
+			scheme = "http"
+		}
+		host := req.Header.Get(forwardedHostnameHeader)
+		if host == "" {
+			host = req.Host
+		}
+		tkn, err := t.Issue(coords.ID, body.Port, scheme, host)
+		if err != nil {
+			log.WithError(err).Error("cannot issue port token")
+			http.Error(resp, "cannot issue port token", http.StatusInternalServerError)
+			return
+		}
+		log.WithFields(log.OWI("", coords.ID, "")).WithField("port", body.Port).WithField("tokenId", tkn.ID).Info("issued port token")
+		resp.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(resp).Encode(tkn)
+	case http.MethodDelete:
+		id := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, portTokenAPIPath), "/")
+		if id == "" || strings.Contains(id, "/") {
+			http.Error(resp, "token ID is required", http.StatusBadRequest)
+			return
+		}
+		err := t.Revoke(coords.ID, id)
+		if xerrors.Is(err, errUnknownPortToken) {
+			http.Error(resp, "unknown port token", http.StatusNotFound)
+			return
+		}
+		if xerrors.Is(err, errTooManyPortTokenRevocations) {
+			http.Error(resp, "too many revoked port tokens - stop the workspace to invalidate its tokens", http.StatusTooManyRequests)
+			return
+		}
+		if err != nil {
+			log.WithError(err).Error("cannot revoke port token")
+			http.Error(resp, "cannot revoke port token", http.StatusInternalServerError)
+			return
+		}
+		log.WithFields(log.OWI("", coords.ID, "")).WithField("tokenId", id).Info("revoked port token")
+		resp.WriteHeader(http.StatusNoContent)
+	default:
+		resp.Header().Set("Allow", "POST, DELETE")
+		http.Error(resp, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// matchPortTokenHost matches requests to /t/{token} on workspace hosts and routes them to the port the token names.
+// The token is verified later on by PortTokens.Handler.
+func matchPortTokenHost(wsHostSuffix string, headerProvider hostHeaderProvider) mux.MatcherFunc {
+	matchHost := matchWorkspaceHostHeader(wsHostSuffix, headerProvider)
+	return func(req *http.Request, m *mux.RouteMatch) bool {
+		if !strings.HasPrefix(req.URL.Path, portTokenPathPrefix) {
+			return false
+		}
+		tkn := strings.SplitN(strings.TrimPrefix(req.URL.Path, portTokenPathPrefix), "/", 2)[0]
+		claims, _, _, err := parsePortToken(tkn)
+		if err != nil || claims.Port == 0 {
+			return false
+		}
+
+		var probe mux.RouteMatch
+		if !matchHost(req, &probe) || probe.Vars[foreignOriginPrefix] != "" {
+			return false
+		}
+		if m.Vars == nil {
+			m.Vars = make(map[string]string)
+		}
+		m.Vars[workspaceIDIdentifier] = probe.Vars[workspaceIDIdentifier]
+		m.Vars[workspacePortIdentifier] = strconv.Itoa(int(claims.Port))
+		m.Vars[portTokenIdentifier] = tkn
+		return true
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func newTestPortTokens(t *testing.T, revocationFile string) *PortTokens {
+	secret := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secret, []byte(strings.Repeat("s", 32)+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := NewPortTokens(PortTokensConfig{SecretFile: secret, RevocationFile: revocationFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func tokenFromURL(u string) string {
+	return strings.TrimSuffix(strings.SplitN(u, portTokenPathPrefix, 2)[1], "/")
+}
+
+func TestPortTokens(t *testing.T) {
+	revocations := filepath.Join(t.TempDir(), "revoked.jsonl")
+	tokens := newTestPortTokens(t, revocations)
+
+	tkn, err := tokens.Issue("amaranth-smelt-9ba20cc1", 8080, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(tkn.URL, "https://amaranth-smelt-9ba20cc1.test-domain.com/t/") {
+		t.Errorf("unexpected URL: %s", tkn.URL)
+	}
+	raw := tokenFromURL(tkn.URL)
+
+	claims, err := tokens.Verify(raw)
+	if err != nil {
+		t.Fatalf("cannot verify issued token: %v", err)
+	}
+	if claims.WorkspaceID != "amaranth-smelt-9ba20cc1" || claims.Port != 8080 || claims.ID != tkn.ID {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	other, _ := tokens.Issue("amaranth-smelt-9ba20cc1", 9090, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	segs := strings.Split(raw, ".")
+	_, err = tokens.Verify(strings.Split(tokenFromURL(other.URL), ".")[0] + "." + segs[1])
+	if err == nil {
+		t.Error("expected token with foreign signature to be rejected")
+	}
+
+	err = tokens.Revoke("amaranth-smelt-9ba20cc1", tkn.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.Verify(raw); err == nil {
+		t.Error("expected revoked token to be rejected")
+	}
+	if _, err := tokens.Verify(tokenFromURL(other.URL)); err != nil {
+		t.Errorf("revocation affected another token: %v", err)
+	}
+
+	// revocations survive restarts
+	restarted, err := NewPortTokens(tokens.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restarted.Verify(raw); err == nil {
+		t.Error("expected revoked token to be rejected after restart")
+	}
+}
+
+func TestPortTokenRevocationValidation(t *testing.T) {
+	tokens := newTestPortTokens(t, filepath.Join(t.TempDir(), "revoked.jsonl"))
+	tkn, err := tokens.Issue("amaranth-smelt-9ba20cc1", 8080, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", "not-a-token-id", tkn.ID + "x"} {
+		if err := tokens.Revoke("amaranth-smelt-9ba20cc1", id); err != errUnknownPortToken {
+			t.Errorf("expected unknown token ID %q to be rejected, got %v", id, err)
+		}
+	}
+	if err := tokens.Revoke("blue-gull-d41d8cd9", tkn.ID); err != errUnknownPortToken {
+		t.Errorf("expected token ID of another workspace to be rejected, got %v", err)
+	}
+	if len(tokens.revoked) != 0 {
+		t.Errorf("rejected revocations were recorded: %v", tokens.revoked)
+	}
+	if _, err := tokens.Verify(tokenFromURL(tkn.URL)); err != nil {
+		t.Errorf("rejected revocation affected the token: %v", err)
+	}
+}
+
+func TestPortTokenExpiry(t *testing.T) {
+	tokens := newTestPortTokens(t, filepath.Join(t.TempDir(), "revoked.jsonl"))
+	tokens.Config.MaxAge = -util.Duration(time.Minute)
+
+	tkn, err := tokens.Issue("amaranth-smelt-9ba20cc1", 8080, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.Verify(tokenFromURL(tkn.URL)); err == nil {
+		t.Error("expected expired token to be rejected")
+	}
+	if err := tokens.Revoke("amaranth-smelt-9ba20cc1", tkn.ID); err != nil {
+		t.Errorf("cannot revoke expired token: %v", err)
+	}
+	if len(tokens.revoked) != 0 {
+		t.Errorf("revocation of expired token was recorded: %v", tokens.revoked)
+	}
+
+	// revocations of tokens which expired since are pruned
+	tokens.revoked["amaranth-smelt-9ba20cc1/expired"] = time.Now().Add(-time.Minute)
+	tokens.Config.MaxAge = 0
+	valid, _ := tokens.Issue("amaranth-smelt-9ba20cc1", 8080, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	if err := tokens.Revoke("amaranth-smelt-9ba20cc1", valid.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tokens.revoked["amaranth-smelt-9ba20cc1/expired"]; ok {
+		t.Error("expected revocation of expired token to be pruned")
+	}
+}
+
+func TestPortTokenRevocationLimit(t *testing.T) {
+	tokens := newTestPortTokens(t, filepath.Join(t.TempDir(), "revoked.jsonl"))
+	expiry := time.Now().Add(time.Hour)
+	for i := 0; i < maxPortTokenRevocationsPerWorkspace; i++ {
+		tokens.revoked[portTokenRevocationKey("amaranth-smelt-9ba20cc1", strconv.Itoa(i))] = expiry
+	}
+
+	tkn, _ := tokens.Issue("amaranth-smelt-9ba20cc1", 8080, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	if err := tokens.Revoke("amaranth-smelt-9ba20cc1", tkn.ID); err != errTooManyPortTokenRevocations {
+		t.Errorf("expected revocation beyond the limit to be rejected, got %v", err)
+	}
+	other, _ := tokens.Issue("blue-gull-d41d8cd9", 8080, "https", "blue-gull-d41d8cd9.test-domain.com")
+	if err := tokens.Revoke("blue-gull-d41d8cd9", other.ID); err != nil {
+		t.Errorf("limit of one workspace affected another: %v", err)
+	}
+}
+
+func TestPortTokensShareRevocations(t *testing.T) {
+	revocations := filepath.Join(t.TempDir(), "revoked.jsonl")
+	a := newTestPortTokens(t, revocations)
+	b, err := NewPortTokens(a.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tknA, _ := a.Issue("amaranth-smelt-9ba20cc1", 8080, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	tknB, _ := b.Issue("amaranth-smelt-9ba20cc1", 9090, "https", "amaranth-smelt-9ba20cc1.test-domain.com")
+	if _, err := b.Verify(tokenFromURL(tknA.URL)); err != nil {
+		t.Fatal(err)
+	}
+
+	// both proxies revoke a token without having seen the other's revocation
+	if err := a.Revoke("amaranth-smelt-9ba20cc1", tknA.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Revoke("amaranth-smelt-9ba20cc1", tknB.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewPortTokens(a.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tkn := range []*PortToken{tknA, tknB} {
+		if _, err := restarted.Verify(tokenFromURL(tkn.URL)); err == nil {
+			t.Errorf("expected revoked token %s to be rejected", tkn.ID)
+		}
+	}
+}
+
+func TestPortTokenRoutes(t *testing.T) {
+	ws := workspaces[0]
+	ws.Ports = []PortInfo{{PortSpec: api.PortSpec{Port: 28080, Target: 38080, Visibility: api.PortVisibility_PORT_VISIBILITY_PRIVATE}}}
+
+	target := startTestTarget(t, portServeHost, "port")
+	defer target.Close()
+
+	tokens := newTestPortTokens(t, "")
+	p := NewWorkspaceProxy(":8080", config, HostBasedRouter(hostBasedHeader, wsHostSuffix, nil), &fakeWsInfoProvider{infos: []WorkspaceInfo{ws}})
+	p.PortTokens = tokens
+	handler, err := p.Handler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve := func(req *http.Request) (int, string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		body, _ := io.ReadAll(rec.Result().Body)
+		return rec.Code, string(body)
+	}
+
+	// the workspace owner issues a token
+	code, body := serve(modifyRequest(httptest.NewRequest("POST", ws.URL+"_wsproxy/port-tokens", strings.NewReader(`{"port":28080}`)), addHostHeader))
+	if code != http.StatusUnauthorized {
+		t.Errorf("expected others to be unable to issue tokens, got %d", code)
+	}
+	code, body = serve(modifyRequest(httptest.NewRequest("POST", ws.URL+"_wsproxy/port-tokens", strings.NewReader(`{"port":28080}`)), addHostHeader, addOwnerToken(ws.InstanceID, ws.Auth.OwnerToken)))
+	if code != http.StatusOK {
+		t.Fatalf("cannot issue token: %d %s", code, body)
+	}
+	var tkn PortToken
+	err = json.Unmarshal([]byte(body), &tkn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, body = serve(modifyRequest(httptest.NewRequest("GET", tkn.URL+"hooks/push?x=1", nil), addHostHeader))
+	if code != http.StatusOK || body != "port hit: /hooks/push?x=1\n" {
+		t.Errorf("unexpected response to token request: %d %q", code, body)
+	}
+
+	code, _ = serve(modifyRequest(httptest.NewRequest("GET", strings.Replace(tkn.URL, "https://", "https://28080-", 1)+"hooks/push", nil), addHostHeader))
+	if code != http.StatusUnauthorized {
+		t.Errorf("expected private port without token to stay private, got %d", code)
+	}
+
+	forged := strings.Replace(tkn.URL, tokenFromURL(tkn.URL), tokenFromURL(tkn.URL)+"x", 1)
+	code, _ = serve(modifyRequest(httptest.NewRequest("GET", forged, nil), addHostHeader))
+	if code != http.StatusNotFound {
+		t.Errorf("expected forged token to be rejected, got %d", code)
+	}
+
+	code, _ = serve(modifyRequest(httptest.NewRequest("DELETE", ws.URL+"_wsproxy/port-tokens/unknown", nil), addHostHeader, addOwnerToken(ws.InstanceID, ws.Auth.OwnerToken)))
+	if code != http.StatusNotFound {
+		t.Errorf("expected revocation of unknown token to be rejected, got %d", code)
+	}
+	code, _ = serve(modifyRequest(httptest.NewRequest("DELETE", ws.URL+"_wsproxy/port-tokens/"+tkn.ID, nil), addHostHeader, addOwnerToken(ws.InstanceID, ws.Auth.OwnerToken)))
+	if code != http.StatusNoContent {
+		t.Errorf("cannot revoke token: %d", code)
+	}
+	code, _ = serve(modifyRequest(httptest.NewRequest("GET", tkn.URL, nil), addHostHeader))
+	if code != http.StatusNotFound {
+		t.Errorf("expected revoked token to be rejected, got %d", code)
+	}
+}
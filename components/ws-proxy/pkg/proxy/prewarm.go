@@ -0,0 +1,357 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	defaultPrewarmConcurrency = 10
+	defaultPrewarmTimeout     = 10 * time.Second
+	// prewarmQueueSize limits the workspaces waiting for a warm-up, e.g. when we learn about all running workspaces at startup
+	prewarmQueueSize = 1000
+
+	// defaultWarmConnTTL is how long we keep an unused warm connection if the transport has no idle connection timeout
+	defaultWarmConnTTL = 90 * time.Second
+
+	prewarmOutcomeSuccess = "success"
+	prewarmOutcomeFailure = "failure"
+	prewarmOutcomeDropped = "dropped"
+)
+
+// PrewarmConfig configures warming up the upstream of workspaces as soon as they are running, so that the
+// first request of the user does not wait for the connection to the workspace or for the IDE to start serving.
+type PrewarmConfig struct {
+	// Concurrency limits the number of workspaces we warm up at the same time. Defaults to 10.
+	Concurrency int `json:"concurrency,omitempty"`
+	// Timeout limits a single warm-up. Defaults to 10 seconds.
+	Timeout util.Duration `json:"timeout,omitempty"`
+	// ProbeIDE sends a HEAD request to the IDE root instead of only opening a connection. The response
+	// makes the IDE do its first-request work, and the connection stays in the pool for the user's requests.
+	ProbeIDE bool `json:"probeIDE,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *PrewarmConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Concurrency, validation.Min(0)),
+		validation.Field(&c.Timeout, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid prewarm config: %w", err)
+	}
+	return nil
+}
+
+// UpstreamPrewarmer warms up the IDE upstream of workspaces once they are running.
+// Workspace status updates must be passed to Observe.
+type UpstreamPrewarmer struct {
+	Config             PrewarmConfig
+	WorkspacePodConfig *WorkspacePodConfig
+	Pool               *TransportPool
+
+	queue   chan *wsapi.WorkspaceStatus
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	metrics *prewarmMetrics
+
+	mu sync.Mutex
+	// warmed are the instances we have warmed up already
+	warmed map[string]struct{}
+}
+
+// NewUpstreamPrewarmer creates a new prewarmer which warms up connections in pool
+func NewUpstreamPrewarmer(cfg PrewarmConfig, podCfg *WorkspacePodConfig, pool *TransportPool) *UpstreamPrewarmer {
+	if cfg.Concurrency == 0 {
+		cfg.Concurrency = defaultPrewarmConcurrency
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = util.Duration(defaultPrewarmTimeout)
+	}
+
+	return &UpstreamPrewarmer{
+		Config:             cfg,
+		WorkspacePodConfig: podCfg,
+		Pool:               pool,
+		queue:              make(chan *wsapi.WorkspaceStatus, prewarmQueueSize),
+		stop:               make(chan struct{}),
+		metrics:            newPrewarmMetrics(),
+		warmed:             make(map[string]struct{}),
+	}
+}
+
+// RegisterMetrics registers the prewarm metrics
+func (p *UpstreamPrewarmer) RegisterMetrics(reg prometheus.Registerer) error {
+	return p.metrics.Register(reg)
+}
+
+// Start starts the warm-up workers
+func (p *UpstreamPrewarmer) Start() {
+	for i := 0; i < p.Config.Concurrency; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+// Close stops the warm-up workers and waits for the warm-ups in progress
+func (p *UpstreamPrewarmer) Close() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// Observe queues a warm-up when an instance reaches RUNNING. Observe never blocks: if too many warm-ups
+// are waiting already, we drop the instance.
+func (p *UpstreamPrewarmer) Observe(status *wsapi.WorkspaceStatus) {
+	if status.Metadata == nil || status.Spec == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if status.Phase == wsapi.WorkspacePhase_STOPPING || status.Phase == wsapi.WorkspacePhase_STOPPED {
+		delete(p.warmed, status.Id)
+		return
+	}
+	if status.Phase != wsapi.WorkspacePhase_RUNNING || status.Spec.Type != wsapi.WorkspaceType_REGULAR {
+		// nobody opens prebuilds or probe workspaces in a browser
+		return
+	}
+	if _, ok := p.warmed[status.Id]; ok {
+		return
+	}
+	p.warmed[status.Id] = struct{}{}
+
+	select {
+	case p.queue <- status:
+	default:
+		p.metrics.OnPrewarm(prewarmOutcomeDropped, 0)
+	}
+}
+
+func (p *UpstreamPrewarmer) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case status := <-p.queue:
+			start := time.Now()
+			err := p.warm(status.Metadata.MetaId)
+			if err != nil {
+				log.WithError(err).WithFields(log.OWI("", status.Metadata.MetaId, status.Id)).Debug("cannot warm up workspace upstream")
+				p.metrics.OnPrewarm(prewarmOutcomeFailure, time.Since(start))
+				continue
+			}
+			p.metrics.OnPrewarm(prewarmOutcomeSuccess, time.Since(start))
+		}
+	}
+}
+
+// warm opens a connection to the IDE of a workspace and, if configured, requests the IDE root
+func (p *UpstreamPrewarmer) warm(workspaceID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.Config.Timeout))
+	defer cancel()
+
+	upstream, err := buildWorkspacePodURL(p.WorkspacePodConfig.ServiceTemplate, workspaceID, fmt.Sprint(p.WorkspacePodConfig.TheiaPort))
+	if err != nil {
+		return err
+	}
+	if !p.Config.ProbeIDE {
+		return p.Pool.Prewarm(ctx, upstream)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", upstream.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.Pool.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	// reading the body to the end returns the connection to the pool
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return xerrors.Errorf("IDE responded with %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// warmConns holds connections we opened ahead of time until a request needs one.
+// Connections no request picks up are closed after the TTL.
+type warmConns struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	conns map[string]net.Conn
+}
+
+func newWarmConns(dial func(ctx context.Context, network, addr string) (net.Conn, error), ttl time.Duration) *warmConns {
+	if ttl <= 0 {
+		ttl = defaultWarmConnTTL
+	}
+	return &warmConns{
+		dial:  dial,
+		ttl:   ttl,
+		conns: make(map[string]net.Conn),
+	}
+}
+
+// Open dials addr and keeps the connection for the next DialContext to addr. We keep at most one connection per address.
+func (w *warmConns) Open(ctx context.Context, addr string) error {
+	w.mu.Lock()
+	_, exists := w.conns[addr]
+	w.mu.Unlock()
+	if exists {
+		return nil
+	}
+
+	conn, err := w.dial(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if _, exists := w.conns[addr]; exists {
+		// someone else warmed up the upstream while we were dialing
+		w.mu.Unlock()
+		conn.Close()
+		return nil
+	}
+	w.conns[addr] = conn
+	w.mu.Unlock()
+
+	time.AfterFunc(w.ttl, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		// a request may have taken the connection in the meantime
+		if w.conns[addr] == conn {
+			delete(w.conns, addr)
+			conn.Close()
+		}
+	})
+	return nil
+}
+
+// DialContext hands out the warm connection to addr if we have one and the upstream has not closed it, and dials otherwise
+func (w *warmConns) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	w.mu.Lock()
+	conn, ok := w.conns[addr]
+	delete(w.conns, addr)
+	w.mu.Unlock()
+
+	if ok {
+		if connAlive(conn) {
+			return conn, nil
+		}
+		conn.Close()
+	}
+	return w.dial(ctx, network, addr)
+}
+
+// Close closes all warm connections
+func (w *warmConns) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for addr, conn := range w.conns {
+		conn.Close()
+		delete(w.conns, addr)
+	}
+}
+
+// connAlive returns true if the peer has not closed the connection yet. Nobody must read from conn concurrently.
+func connAlive(conn net.Conn) bool {
+	err := conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = conn.SetReadDeadline(time.Time{})
+	}()
+
+	// an idle upstream must not send anything - if it does, we cannot hand out the connection either
+	_, err = conn.Read(make([]byte, 1))
+	if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// canonicalAddr returns the host:port the HTTP transport dials for u
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+type prewarmMetrics struct {
+	prewarms *prometheus.CounterVec
+	duration prometheus.Histogram
+}
+
+func newPrewarmMetrics() *prewarmMetrics {
+	return &prewarmMetrics{
+		prewarms: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "upstream_prewarm_total",
+			Help: "number of workspace upstream warm-ups, by outcome (success, failure or dropped)",
+		}, []string{"outcome"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "upstream_prewarm_duration_seconds",
+			Help:    "time a successful workspace upstream warm-up took",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		}),
+	}
+}
+
+// Register registers all prewarm metrics
+func (m *prewarmMetrics) Register(reg prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		m.prewarms,
+		m.duration,
+	}
+	for _, c := range collectors {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *prewarmMetrics) OnPrewarm(outcome string, duration time.Duration) {
+	m.prewarms.WithLabelValues(outcome).Inc()
+	if outcome == prewarmOutcomeSuccess {
+		m.duration.Observe(duration.Seconds())
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func newPrewarmTestUpstream(t *testing.T) (srv *httptest.Server, conns, heads *int32) {
+	conns, heads = new(int32), new(int32)
+	srv = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			atomic.AddInt32(heads, 1)
+		}
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(conns, 1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return
+}
+
+func TestTransportPoolPrewarm(t *testing.T) {
+	srv, conns, _ := newPrewarmTestUpstream(t)
+	pool := NewTransportPool(&TransportConfig{ConnectTimeout: util.Duration(time.Second), IdleConnTimeout: util.Duration(time.Minute), MaxIdleConns: 10})
+	u, _ := url.Parse(srv.URL)
+
+	err := pool.Prewarm(context.Background(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a second warm-up must not open another connection
+	err = pool.Prewarm(context.Background(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := pool.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Errorf("expected the request to use the warm connection, but the upstream saw %d connections", n)
+	}
+}
+
+func TestConnAlive(t *testing.T) {
+	a, b := net.Pipe()
+	if !connAlive(a) {
+		t.Error("expected an open connection to be alive")
+	}
+	b.Close()
+	if connAlive(a) {
+		t.Error("expected a closed connection not to be alive")
+	}
+}
+
+func TestWarmConnsRedialsClosedConnections(t *testing.T) {
+	var dials int
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		a, b := net.Pipe()
+		if dials == 1 {
+			// the upstream closes the first connection right away
+			b.Close()
+		}
+		return a, nil
+	}
+	w := newWarmConns(dial, time.Minute)
+	defer w.Close()
+
+	err := w.Open(context.Background(), "upstream:80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := w.DialContext(context.Background(), "tcp", "upstream:80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if dials != 2 || !connAlive(conn) {
+		t.Errorf("expected a fresh connection, dialed %d times", dials)
+	}
+}
+
+func TestUpstreamPrewarmer(t *testing.T) {
+	srv, conns, heads := newPrewarmTestUpstream(t)
+	u, _ := url.Parse(srv.URL)
+
+	pool := NewTransportPool(&TransportConfig{ConnectTimeout: util.Duration(time.Second), IdleConnTimeout: util.Duration(time.Minute), MaxIdleConns: 10})
+	p := NewUpstreamPrewarmer(PrewarmConfig{ProbeIDE: true}, &WorkspacePodConfig{
+		ServiceTemplate: "http://" + u.Hostname() + ":{{ .port }}",
+		TheiaPort:       uint16(mustAtoi(t, u.Port())),
+	}, pool)
+
+	status := func(instanceID string, phase wsapi.WorkspacePhase, tpe wsapi.WorkspaceType) *wsapi.WorkspaceStatus {
+		return &wsapi.WorkspaceStatus{
+			Id:       instanceID,
+			Phase:    phase,
+			Metadata: &wsapi.WorkspaceMetadata{MetaId: "amaranth-smelt-9ba20cc1"},
+			Spec:     &wsapi.WorkspaceSpec{Type: tpe},
+		}
+	}
+	for _, s := range []*wsapi.WorkspaceStatus{
+		status("instance-1", wsapi.WorkspacePhase_CREATING, wsapi.WorkspaceType_REGULAR),
+		status("instance-1", wsapi.WorkspacePhase_RUNNING, wsapi.WorkspaceType_REGULAR),
+		// we warm up every instance once
+		status("instance-1", wsapi.WorkspacePhase_RUNNING, wsapi.WorkspaceType_REGULAR),
+		status("instance-2", wsapi.WorkspacePhase_RUNNING, wsapi.WorkspaceType_PREBUILD),
+	} {
+		p.Observe(s)
+	}
+	p.Start()
+	for i := 0; i < 100 && testutil.ToFloat64(p.metrics.prewarms.WithLabelValues(prewarmOutcomeSuccess)) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	p.Close()
+
+	if n := testutil.ToFloat64(p.metrics.prewarms.WithLabelValues(prewarmOutcomeSuccess)); n != 1 {
+		t.Errorf("expected one successful warm-up, got %v", n)
+	}
+	if n := atomic.LoadInt32(heads); n != 1 {
+		t.Errorf("expected one HEAD request, got %d", n)
+	}
+
+	// the user's first request reuses the connection of the warm-up
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := pool.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Errorf("expected one upstream connection, got %d", n)
+	}
+
+	// a new instance after a restart is warmed up again
+	if _, ok := p.warmed["instance-1"]; !ok {
+		t.Fatal("expected instance-1 to be marked as warmed")
+	}
+	p.Observe(status("instance-1", wsapi.WorkspacePhase_STOPPED, wsapi.WorkspaceType_REGULAR))
+	if _, ok := p.warmed["instance-1"]; ok {
+		t.Error("expected stopped instances to be forgotten")
+	}
+}
+
+func TestPrewarmerDropsWhenQueueIsFull(t *testing.T) {
+	p := NewUpstreamPrewarmer(PrewarmConfig{}, &WorkspacePodConfig{}, nil)
+	for i := 0; i < prewarmQueueSize+5; i++ {
+		p.Observe(&wsapi.WorkspaceStatus{
+			Id:       "instance-" + strconv.Itoa(i),
+			Phase:    wsapi.WorkspacePhase_RUNNING,
+			Metadata: &wsapi.WorkspaceMetadata{MetaId: "ws-" + strconv.Itoa(i)},
+			Spec:     &wsapi.WorkspaceSpec{Type: wsapi.WorkspaceType_REGULAR},
+		})
+	}
+	if n := testutil.ToFloat64(p.metrics.prewarms.WithLabelValues(prewarmOutcomeDropped)); n != 5 {
+		t.Errorf("expected five dropped warm-ups, got %v", n)
+	}
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
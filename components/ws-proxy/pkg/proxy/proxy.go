@@ -5,11 +5,16 @@
 package proxy
 
 import (
+	"crypto/tls"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/gorilla/mux"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 )
@@ -20,6 +25,56 @@ type WorkspaceProxy struct {
 	Config                Config
 	WorkspaceRouter       WorkspaceRouter
 	WorkspaceInfoProvider WorkspaceInfoProvider
+	// WorkspaceWaker, if set, starts stopped workspaces when their owner tries to access them
+	WorkspaceWaker *WorkspaceWaker
+	// Health, if set, is told once the proxy listens on its address
+	Health *HealthChecker
+	// TransportPool, if set, is used to connect to workspaces instead of a pool of this proxy's own
+	TransportPool *TransportPool
+	// SLOTracker, if set, records all requests towards the service level objectives
+	SLOTracker *SLOTracker
+	// AuditLog, if set, records all authentication decisions
+	AuditLog *AuditLog
+	// ExperimentTracker, if set, counts the exposures of experiment variants
+	ExperimentTracker *ExperimentTracker
+	// ActivityTracker, if set, counts the requests in flight to workspaces and reports them to ws-manager
+	ActivityTracker *ActivityTracker
+	// BandwidthTracker, if set, counts the bytes exchanged with workspaces and limits their egress
+	BandwidthTracker *BandwidthTracker
+	// WebsocketThrottler, if set, throttles the websockets of the routes which configure it
+	WebsocketThrottler *WebsocketThrottler
+	// AbuseDetector, if set, restricts public ports which look abusive to the workspace owner
+	AbuseDetector *AbuseDetector
+	// AnomalyDetector, if set, counts the failing requests per workspace
+	AnomalyDetector *AnomalyDetector
+	// AuthThrottler, if set, blocks clients which fail the owner token checks too often
+	AuthThrottler *AuthThrottler
+	// PortTokens, if set, admits requests to workspace ports which carry a port token
+	PortTokens *PortTokens
+	// TURN, if set, hands out credentials for the TURN relay to workspace owners
+	TURN *TURNServer
+	// SupervisorHandshakes, if set, negotiates the protocol capabilities with the supervisor of workspaces
+	SupervisorHandshakes *SupervisorHandshakes
+	// PortGate, if set, rejects requests to workspace ports which supervisor does not see served
+	PortGate *PortGate
+	// AdditionalAddresses are further addresses the proxy listens on, e.g. to listen on IPv4 and IPv6 separately
+	AdditionalAddresses []string
+	// Connections, if set, tracks the open client connections
+	Connections *ConnectionTracker
+	// Certificates, if set, provides the certificates of the TLS listener by SNI. The configured certificate
+	// serves all names it does not have a certificate for.
+	Certificates CertificateProvider
+	// Upgrades, if set, provides the listeners and hands them over to a successor ws-proxy
+	Upgrades *UpgradeController
+	// SchemeRedirector, if set, redirects plain HTTP requests to HTTPS and sends HSTS headers before we route requests
+	SchemeRedirector *SchemeRedirector
+	// UpstreamDiscovery, if set, balances requests to internal upstreams across the ready endpoints of their service
+	UpstreamDiscovery *UpstreamDiscovery
+}
+
+// CertificateProvider provides the certificate for a TLS handshake. It returns nil if it has none for the handshake's server name.
+type CertificateProvider interface {
+	GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
 }
 
 // NewWorkspaceProxy creates a new workspace proxy
@@ -40,7 +95,28 @@ func (p *WorkspaceProxy) MustServe() {
 		return
 	}
 	srv := &http.Server{Addr: p.Address, Handler: handler}
+	if p.Connections != nil {
+		srv.ConnState = p.Connections.ConnState
+	}
+	p.Upgrades.RegisterServer(srv)
+	addrs := append([]string{p.Address}, p.AdditionalAddresses...)
+	lns, err := p.Upgrades.Listen(addrs)
+	if err != nil {
+		log.WithError(err).Fatal("cannot start proxy")
+		return
+	}
+	for i, ln := range lns {
+		lns[i], err = newProxyProtocolListener(ln, p.Config.ClientIP)
+		if err != nil {
+			log.WithError(err).Fatal("cannot start proxy")
+			return
+		}
+	}
+	for _, addr := range addrs {
+		p.Health.ListenerUp(addr)
+	}
 
+	var serve func(ln net.Listener) error
 	if p.Config.HTTPS.Enabled {
 		var (
 			crt = p.Config.HTTPS.Certificate
@@ -50,32 +126,148 @@ func (p *WorkspaceProxy) MustServe() {
 			crt = filepath.Join(tproot, crt)
 			key = filepath.Join(tproot, key)
 		}
-		err = srv.ListenAndServeTLS(crt, key)
+		if p.Certificates != nil {
+			srv.TLSConfig, err = certificateProviderTLSConfig(p.Certificates, crt, key)
+			if err != nil {
+				log.WithError(err).Fatal("cannot start proxy")
+				return
+			}
+			crt, key = "", ""
+		}
+		if p.AuthThrottler != nil {
+			// the throttler tells clients apart by their TLS ClientHello, too
+			if srv.TLSConfig == nil {
+				srv.TLSConfig = &tls.Config{}
+			}
+			srv.TLSConfig.GetConfigForClient = p.AuthThrottler.GetConfigForClient
+			srv.ConnContext = p.AuthThrottler.ConnContext
+			connState := srv.ConnState
+			srv.ConnState = func(c net.Conn, state http.ConnState) {
+				if connState != nil {
+					connState(c, state)
+				}
+				p.AuthThrottler.ConnState(c, state)
+			}
+		}
+		serve = func(ln net.Listener) error { return srv.ServeTLS(ln, crt, key) }
 	} else {
-		err = srv.ListenAndServe()
+		// without TLS there's no ALPN, hence we accept HTTP/2 with prior knowledge (h2c), e.g. for gRPC
+		srv.Handler = h2c.NewHandler(handler, &http2.Server{})
+		serve = srv.Serve
 	}
 
-	if err != nil {
+	errs := make(chan error, len(lns))
+	for _, ln := range lns {
+		go func(ln net.Listener) {
+			errs <- serve(ln)
+		}(ln)
+	}
+	err = <-errs
+	if err != nil && !p.Upgrades.IsReplaced() {
 		log.WithError(err).Fatal("cannot start proxy")
 		return
 	}
 }
 
+// certificateProviderTLSConfig serves the certificates of the provider, falling back to the configured certificate if there is one
+func certificateProviderTLSConfig(certs CertificateProvider, crt, key string) (*tls.Config, error) {
+	var fallback *tls.Certificate
+	if crt != "" && key != "" {
+		c, err := tls.LoadX509KeyPair(crt, key)
+		if err != nil {
+			return nil, err
+		}
+		fallback = &c
+	}
+	return &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			c, err := certs.GetCertificate(hello)
+			if c != nil || err != nil {
+				return c, err
+			}
+			if fallback == nil {
+				return nil, xerrors.Errorf("no certificate for %s", hello.ServerName)
+			}
+			return fallback, nil
+		},
+	}, nil
+}
+
 // Handler returns the HTTP handler that serves the proxy routes
 func (p *WorkspaceProxy) Handler() (http.Handler, error) {
 	r := mux.NewRouter()
 
 	// install routes
-	handlerConfig, err := NewRouteHandlerConfig(&p.Config, WithDefaultAuth(p.WorkspaceInfoProvider))
+	opts := []RouteHandlerConfigOpt{WithDefaultAuth(p.WorkspaceInfoProvider)}
+	if p.WorkspaceWaker != nil {
+		opts = append(opts, WithWorkspaceWaker(p.WorkspaceWaker))
+	}
+	if p.TransportPool != nil {
+		opts = append(opts, WithTransportPool(p.TransportPool))
+	}
+	if p.UpstreamDiscovery != nil {
+		opts = append(opts, WithUpstreamDiscovery(p.UpstreamDiscovery))
+	}
+	if p.SLOTracker != nil {
+		opts = append(opts, WithSLOTracker(p.SLOTracker))
+	}
+	if p.AuditLog != nil {
+		opts = append(opts, WithAuditLog(p.AuditLog))
+	}
+	if p.ExperimentTracker != nil {
+		opts = append(opts, WithExperimentTracker(p.ExperimentTracker))
+	}
+	if p.ActivityTracker != nil {
+		opts = append(opts, WithActivityTracker(p.ActivityTracker))
+	}
+	if p.BandwidthTracker != nil {
+		opts = append(opts, WithBandwidthTracker(p.BandwidthTracker))
+	}
+	if p.WebsocketThrottler != nil {
+		opts = append(opts, WithWebsocketThrottler(p.WebsocketThrottler))
+	}
+	if p.AbuseDetector != nil {
+		opts = append(opts, WithAbuseDetector(p.AbuseDetector))
+	}
+	if p.AnomalyDetector != nil {
+		opts = append(opts, WithAnomalyDetector(p.AnomalyDetector))
+	}
+	if p.AuthThrottler != nil {
+		opts = append(opts, WithAuthThrottler(p.AuthThrottler))
+	}
+	if p.PortTokens != nil {
+		opts = append(opts, WithPortTokens(p.PortTokens))
+	}
+	if p.TURN != nil {
+		opts = append(opts, WithTURN(p.TURN))
+	}
+	if p.SupervisorHandshakes != nil {
+		opts = append(opts, WithSupervisorHandshakes(p.SupervisorHandshakes))
+	}
+	if p.PortGate != nil {
+		opts = append(opts, WithPortGate(p.PortGate))
+	}
+	if mp, ok := p.WorkspaceInfoProvider.(MaintenanceProvider); ok {
+		opts = append(opts, WithMaintenanceNotice(mp))
+	}
+	if rp, ok := p.WorkspaceInfoProvider.(ReadOnlyProvider); ok {
+		opts = append(opts, WithReadOnlyNotice(rp))
+	}
+	handlerConfig, err := NewRouteHandlerConfig(&p.Config, opts...)
 	if err != nil {
 		return nil, err
 	}
 	theiaRouter, portRouter, blobserveRouter := p.WorkspaceRouter(r, p.WorkspaceInfoProvider)
 	installWorkspaceRoutes(theiaRouter, handlerConfig, p.WorkspaceInfoProvider)
-	err = installWorkspacePortRoutes(portRouter, handlerConfig)
+	err = installWorkspacePortRoutes(portRouter, handlerConfig, p.WorkspaceInfoProvider)
 	if err != nil {
 		return nil, err
 	}
 	installBlobserveRoutes(blobserveRouter, handlerConfig)
-	return r, nil
+
+	clientIP, err := clientIPHandler(p.Config.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+	return p.SchemeRedirector.Handler(clientIP(r)), nil
 }
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// staleRoutingHeader tells clients that we route with a frozen copy of what ws-manager last told us, e.g.
+// "age=120; last-update=2021-04-01T10:00:00Z"
+const staleRoutingHeader = "X-Gitpod-Stale-Routing"
+
+// ReadOnlyConfig configures the emergency read-only mode. Once ws-manager has been unreachable for a while we freeze
+// the workspace info cache: we keep routing to the workspaces we know, and no longer remove any of them until
+// ws-manager is back.
+type ReadOnlyConfig struct {
+	// FailureThreshold is the number of consecutive failed attempts to connect to, refresh from or subscribe to
+	// ws-manager after which we enter read-only mode
+	FailureThreshold int `json:"failureThreshold"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *ReadOnlyConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.FailureThreshold, validation.Required, validation.Min(1)),
+	)
+}
+
+// ReadOnlyProvider knows if workspaces are routed from a frozen cache
+type ReadOnlyProvider interface {
+	// ReadOnly returns true if the cache is frozen, and when we last heard from ws-manager
+	ReadOnly() (lastUpdate time.Time, readOnly bool)
+}
+
+// WithReadOnlyNotice marks responses served from a frozen workspace info cache
+func WithReadOnlyNotice(provider ReadOnlyProvider) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.ReadOnly = provider
+	}
+}
+
+// readOnlyHeaderHandler sets the X-Gitpod-Stale-Routing header on all responses while we are in read-only mode
+func readOnlyHeaderHandler(config *RouteHandlerConfig) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if config.ReadOnly == nil {
+			return h
+		}
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if lastUpdate, ok := config.ReadOnly.ReadOnly(); ok {
+				resp.Header().Set(staleRoutingHeader, staleRoutingHeaderValue(lastUpdate, time.Now()))
+			}
+			h.ServeHTTP(resp, req)
+		})
+	}
+}
+
+func staleRoutingHeaderValue(lastUpdate, now time.Time) string {
+	if lastUpdate.IsZero() {
+		return "age=unknown"
+	}
+	return fmt.Sprintf("age=%d; last-update=%s", int64(now.Sub(lastUpdate).Seconds()), lastUpdate.UTC().Format(time.RFC3339))
+}
+
+// ReadOnly returns true if the cache is frozen, and when we last heard from ws-manager
+func (p *RemoteWorkspaceInfoProvider) ReadOnly() (lastUpdate time.Time, readOnly bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.lastUpdate, p.readOnly || p.isDegraded()
+}
+
+// recordFailure counts a failed attempt to reach ws-manager and enters read-only mode once we have failed too often
+func (p *RemoteWorkspaceInfoProvider) recordFailure() {
+	if p.Config.ReadOnly == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failures++
+	if p.readOnly || p.failures < p.Config.ReadOnly.FailureThreshold {
+		return
+	}
+	p.readOnly = true
+	p.cache.SetFrozen(true)
+	log.WithField("failures", p.failures).WithField("lastUpdate", p.lastUpdate).Warn("ws-manager is unreachable - serving workspaces read-only from the cache")
+}
+
+// recordSuccess resets the failure count once we receive updates from ws-manager again. If we were in read-only mode
+// we apply the removals we held back while the cache was frozen, based on infos which ws-manager just gave us.
+func (p *RemoteWorkspaceInfoProvider) recordSuccess(infos []*WorkspaceInfo) {
+	p.mu.Lock()
+	p.failures = 0
+	wasReadOnly := p.readOnly
+	p.readOnly = false
+	degraded := p.isDegraded()
+	p.mu.Unlock()
+
+	if !wasReadOnly || degraded {
+		// leaving the degraded mode thaws the cache
+		return
+	}
+	p.cache.SetFrozen(false)
+	diff := p.cache.Reinit(infos)
+	log.WithField("diff", diff).Info("ws-manager is reachable again - leaving read-only mode")
+}
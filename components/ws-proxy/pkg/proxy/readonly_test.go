@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWorkspaceInfoCacheFrozen(t *testing.T) {
+	cache := newWorkspaceInfoCache(0, 0)
+	other := *testWorkspaceInfo
+	other.WorkspaceID = "other"
+	other.IDEPublicPort = "8443"
+	other.Ports = nil
+	cache.Insert(testWorkspaceInfo)
+	cache.Insert(&other)
+
+	cache.SetFrozen(true)
+	cache.Delete(testWorkspaceInfo.WorkspaceID, 0)
+	if diff := cache.Reinit([]*WorkspaceInfo{&other}); diff != (cacheDiff{Retained: 1}) {
+		t.Errorf("unexpected diff while frozen: %+v", diff)
+	}
+	if cache.Size() != 2 {
+		t.Fatalf("frozen cache removed workspaces: %d left", cache.Size())
+	}
+	if _, ok := cache.GetCoordsByPublicPort(testWorkspaceInfo.IDEPublicPort); !ok {
+		t.Error("frozen cache lost the coordinates of a workspace")
+	}
+
+	cache.SetFrozen(false)
+	if diff := cache.Reinit([]*WorkspaceInfo{&other}); diff != (cacheDiff{Deleted: 1}) {
+		t.Errorf("unexpected diff after thawing: %+v", diff)
+	}
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	prov := NewRemoteWorkspaceInfoProvider(WorkspaceInfoProviderConfig{
+		WsManagerAddr: "target",
+		ReadOnly:      &ReadOnlyConfig{FailureThreshold: 2},
+	})
+	prov.cache.Insert(testWorkspaceInfo)
+	prov.markUpdated()
+
+	prov.recordFailure()
+	if _, ok := prov.ReadOnly(); ok {
+		t.Fatal("entered read-only mode before reaching the failure threshold")
+	}
+	prov.recordFailure()
+	lastUpdate, ok := prov.ReadOnly()
+	if !ok {
+		t.Fatal("did not enter read-only mode")
+	}
+	if lastUpdate.IsZero() {
+		t.Error("read-only mode does not know when we last heard from ws-manager")
+	}
+	if !prov.Health().ReadOnly {
+		t.Error("health does not report read-only mode")
+	}
+
+	// a stopped workspace which arrives in the meantime must not take the workspace away
+	prov.cache.Delete(testWorkspaceInfo.WorkspaceID, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if info := prov.WorkspaceInfo(ctx, testWorkspaceInfo.WorkspaceID); info == nil {
+		t.Fatal("read-only mode does not serve the cached workspace")
+	}
+
+	// once ws-manager is back it is authoritative again
+	prov.recordSuccess(nil)
+	if _, ok := prov.ReadOnly(); ok {
+		t.Error("did not leave read-only mode")
+	}
+	if prov.cache.Size() != 0 {
+		t.Error("leaving read-only mode did not apply the removals we held back")
+	}
+
+	// failures are counted consecutively
+	prov.recordFailure()
+	if _, ok := prov.ReadOnly(); ok {
+		t.Error("failures before the last success count towards the threshold")
+	}
+}
+
+func TestReadOnlyModeDisabled(t *testing.T) {
+	prov := NewRemoteWorkspaceInfoProvider(WorkspaceInfoProviderConfig{WsManagerAddr: "target"})
+	for i := 0; i < 100; i++ {
+		prov.recordFailure()
+	}
+	if _, ok := prov.ReadOnly(); ok {
+		t.Error("entered read-only mode without being configured to")
+	}
+}
+
+type fakeReadOnlyProvider struct {
+	LastUpdate time.Time
+	Enabled    bool
+}
+
+func (f fakeReadOnlyProvider) ReadOnly() (time.Time, bool) { return f.LastUpdate, f.Enabled }
+
+func TestReadOnlyHeaderHandler(t *testing.T) {
+	lastUpdate := time.Now().Add(-2 * time.Minute)
+	tests := []struct {
+		Name     string
+		Provider ReadOnlyProvider
+		Expected string
+	}{
+		{Name: "no provider"},
+		{Name: "not read-only", Provider: fakeReadOnlyProvider{LastUpdate: lastUpdate}},
+		{
+			Name:     "read-only",
+			Provider: fakeReadOnlyProvider{LastUpdate: lastUpdate, Enabled: true},
+			Expected: "age=120; last-update=" + lastUpdate.UTC().Format(time.RFC3339),
+		},
+		{Name: "never heard from ws-manager", Provider: fakeReadOnlyProvider{Enabled: true}, Expected: "age=unknown"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			handler := readOnlyHeaderHandler(&RouteHandlerConfig{ReadOnly: test.Provider})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", "http://localhost/", nil))
+
+			if diff := cmp.Diff(test.Expected, rec.Header().Get(staleRoutingHeader)); diff != "" {
+				t.Errorf("unexpected header (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
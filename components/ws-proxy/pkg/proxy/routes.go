@@ -8,13 +8,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	htmltemplate "html/template"
 	"io"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
-	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -25,6 +25,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
 )
 
 // RouteHandlerConfig configures a RouteHandler
@@ -33,6 +34,44 @@ type RouteHandlerConfig struct {
 	DefaultTransport     http.RoundTripper
 	CorsHandler          mux.MiddlewareFunc
 	WorkspaceAuthHandler mux.MiddlewareFunc
+	WorkspaceWaker       *WorkspaceWaker
+	ErrorPages           *ErrorPages
+	Maintenance          MaintenanceProvider
+	MaintenanceBanner    *htmltemplate.Template
+	SLOTracker           *SLOTracker
+	// UpstreamHealth is nil unless upstream retries are configured
+	UpstreamHealth *UpstreamHealth
+	// UpstreamDiscovery, if set, sends requests to discovered upstreams to one of their endpoints
+	UpstreamDiscovery *UpstreamDiscovery
+	// AuditLog, if set, records all authentication decisions
+	AuditLog *AuditLog
+	// AuthThrottler, if set, blocks clients which fail the owner token checks too often
+	AuthThrottler *AuthThrottler
+	// ExperimentTracker, if set, counts the exposures of experiment variants
+	ExperimentTracker *ExperimentTracker
+	// ActivityTracker, if set, counts the requests in flight to workspaces
+	ActivityTracker *ActivityTracker
+	// BandwidthTracker, if set, counts the bytes exchanged with workspaces and limits their egress
+	BandwidthTracker *BandwidthTracker
+	// WebsocketThrottler, if set, throttles the websockets of the routes which configure it
+	WebsocketThrottler *WebsocketThrottler
+	// AbuseDetector, if set, restricts public ports which look abusive to the workspace owner
+	AbuseDetector *AbuseDetector
+	// AnomalyDetector, if set, counts the failing requests per workspace
+	AnomalyDetector *AnomalyDetector
+	// PortTokens, if set, admits requests to workspace ports which carry a port token
+	PortTokens *PortTokens
+	// TURN, if set, hands out credentials for the TURN relay to workspace owners
+	TURN *TURNServer
+	// SupervisorHandshakes, if set, negotiates the protocol capabilities with supervisor
+	SupervisorHandshakes *SupervisorHandshakes
+	// PortGate, if set, rejects requests to workspace ports which supervisor does not see served
+	PortGate *PortGate
+	// ReadOnly, if set, tells us when we route from a frozen workspace info cache
+	ReadOnly ReadOnlyProvider
+
+	// SupervisorAuthHandler guards the supervisor API which only the workspace owner may use
+	SupervisorAuthHandler mux.MiddlewareFunc
 }
 
 // RouteHandlerConfigOpt modifies the router handler config
@@ -41,7 +80,125 @@ type RouteHandlerConfigOpt func(*Config, *RouteHandlerConfig)
 // WithDefaultAuth enables workspace access authentication
 func WithDefaultAuth(infoprov WorkspaceInfoProvider) RouteHandlerConfigOpt {
 	return func(config *Config, c *RouteHandlerConfig) {
-		c.WorkspaceAuthHandler = WorkspaceAuthHandler(config.GitpodInstallation.HostName, infoprov)
+		// the handlers are built once the routes are installed so that they pick up the audit log and auth throttler irrespective of the option order
+		c.WorkspaceAuthHandler = func(h http.Handler) http.Handler {
+			return WorkspaceAuthHandler(config.GitpodInstallation.HostName, infoprov, c.ErrorPages, c.AuditLog, c.AuthThrottler)(h)
+		}
+		c.SupervisorAuthHandler = func(h http.Handler) http.Handler {
+			return WorkspaceOwnerAuthHandler(config.GitpodInstallation.HostName, infoprov, c.ErrorPages, c.AuditLog, c.AuthThrottler)(h)
+		}
+	}
+}
+
+// WithAuditLog records all authentication decisions in the audit log
+func WithAuditLog(audit *AuditLog) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.AuditLog = audit
+	}
+}
+
+// WithAuthThrottler blocks clients which fail the owner token checks too often
+func WithAuthThrottler(throttle *AuthThrottler) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.AuthThrottler = throttle
+	}
+}
+
+// WithWorkspaceWaker starts stopped workspaces when their owner tries to access them
+func WithWorkspaceWaker(waker *WorkspaceWaker) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.WorkspaceWaker = waker
+	}
+}
+
+// WithTransportPool shares a transport pool between route handlers
+func WithTransportPool(pool *TransportPool) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.DefaultTransport = pool
+	}
+}
+
+// WithUpstreamDiscovery balances requests to discovered upstreams across their endpoints
+func WithUpstreamDiscovery(discovery *UpstreamDiscovery) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.UpstreamDiscovery = discovery
+	}
+}
+
+// WithSLOTracker records all requests towards the service level objectives
+func WithSLOTracker(tracker *SLOTracker) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.SLOTracker = tracker
+	}
+}
+
+// WithExperimentTracker counts the exposures of experiment variants
+func WithExperimentTracker(tracker *ExperimentTracker) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.ExperimentTracker = tracker
+	}
+}
+
+// WithActivityTracker counts the requests in flight to workspaces
+func WithActivityTracker(tracker *ActivityTracker) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.ActivityTracker = tracker
+	}
+}
+
+// WithBandwidthTracker counts the bytes exchanged with workspaces and limits their egress
+func WithBandwidthTracker(tracker *BandwidthTracker) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.BandwidthTracker = tracker
+	}
+}
+
+// WithWebsocketThrottler throttles the websockets of the routes which configure it
+func WithWebsocketThrottler(throttler *WebsocketThrottler) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.WebsocketThrottler = throttler
+	}
+}
+
+// WithAbuseDetector restricts public ports which look abusive to the workspace owner
+func WithAbuseDetector(detector *AbuseDetector) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.AbuseDetector = detector
+	}
+}
+
+// WithAnomalyDetector counts the failing requests per workspace to detect error-rate spikes
+func WithAnomalyDetector(detector *AnomalyDetector) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.AnomalyDetector = detector
+	}
+}
+
+// WithPortTokens admits requests to workspace ports which carry a port token and lets workspace owners issue them
+func WithPortTokens(tokens *PortTokens) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.PortTokens = tokens
+	}
+}
+
+// WithTURN lets workspace owners obtain credentials for the TURN relay
+func WithTURN(turn *TURNServer) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.TURN = turn
+	}
+}
+
+// WithSupervisorHandshakes negotiates the protocol capabilities with the supervisor of workspaces
+func WithSupervisorHandshakes(handshakes *SupervisorHandshakes) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.SupervisorHandshakes = handshakes
+	}
+}
+
+// WithPortGate rejects requests to workspace ports which supervisor does not see served
+func WithPortGate(gate *PortGate) RouteHandlerConfigOpt {
+	return func(config *Config, c *RouteHandlerConfig) {
+		c.PortGate = gate
 	}
 }
 
@@ -51,16 +208,34 @@ func NewRouteHandlerConfig(config *Config, opts ...RouteHandlerConfigOpt) (*Rout
 	if err != nil {
 		return nil, err
 	}
+	errorPages, err := NewErrorPages(config)
+	if err != nil {
+		return nil, err
+	}
+	maintenanceBanner, err := newPageTemplateLoader(config)(builtinPageMaintenanceBanner)
+	if err != nil {
+		return nil, err
+	}
 
 	cfg := &RouteHandlerConfig{
-		Config:               config,
-		DefaultTransport:     createDefaultTransport(config.TransportConfig),
-		CorsHandler:          corsHandler,
-		WorkspaceAuthHandler: func(h http.Handler) http.Handler { return h },
+		Config:                config,
+		DefaultTransport:      NewTransportPool(config.TransportConfig),
+		CorsHandler:           corsHandler,
+		WorkspaceAuthHandler:  func(h http.Handler) http.Handler { return h },
+		SupervisorAuthHandler: func(h http.Handler) http.Handler { return h },
+		ErrorPages:            errorPages,
+		MaintenanceBanner:     maintenanceBanner,
+	}
+	if config.UpstreamRetry != nil {
+		cfg.UpstreamHealth = NewUpstreamHealth()
 	}
 	for _, o := range opts {
 		o(config, cfg)
 	}
+	if cfg.UpstreamDiscovery != nil {
+		// irrespective of the option order, discovery must wrap the transport pool all routes use
+		cfg.DefaultTransport = cfg.UpstreamDiscovery.Transport(cfg.DefaultTransport)
+	}
 	return cfg, nil
 }
 
@@ -69,13 +244,28 @@ type RouteHandler = func(r *mux.Router, config *RouteHandlerConfig)
 
 // installWorkspaceRoutes configures routing of workspace and IDE requests
 func installWorkspaceRoutes(r *mux.Router, config *RouteHandlerConfig, ip WorkspaceInfoProvider) {
+	// the synthetic workspace of `ws-proxy verify` exists without ws-manager knowing about it
+	r.Use(verifyHandler(config.Config.VerifyEndpoint, SLORouteClassIDE))
 	r.Use(logHandler)
-	r.Use(handlers.CompressHandler)
+	r.Use(tracingHandler)
+	r.Use(config.SLOTracker.Handler(SLORouteClassIDE))
+	r.Use(config.AnomalyDetector.Handler())
+	r.Use(headerPolicyHandler(config.Config.Headers, SLORouteClassIDE, ip))
+	// we count what goes over the wire, i.e. after compression
+	r.Use(config.BandwidthTracker.Handler)
+	r.Use(compressHandler(config.Config.Compression))
+	r.Use(maintenanceHeaderHandler(config))
+	r.Use(readOnlyHeaderHandler(config))
+	r.Use(config.ActivityTracker.Handler)
 
 	// Note: the order of routes defines their priority.
 	//       Routes registered first have priority over those that come afterwards.
 	routes := newIDERoutes(config, ip)
 
+	if config.WorkspaceWaker != nil {
+		r.Path(wakeEventsPath).HandlerFunc(config.WorkspaceWaker.ServeEvents)
+	}
+
 	// The favicon warants special handling, because we pull that from the supervisor frontend
 	// rather than the IDE.
 	faviconRouter := r.Path("/favicon.ico").Subrouter()
@@ -96,24 +286,43 @@ func installWorkspaceRoutes(r *mux.Router, config *RouteHandlerConfig, ip Worksp
 		routes.HandleDirectIDERoute(r.PathPrefix(pp))
 	}
 
+	if config.PortTokens != nil {
+		routes.HandlePortTokensRoute(r.PathPrefix(portTokenAPIPath))
+	}
+	if config.TURN != nil {
+		routes.HandleTURNCredentialsRoute(r.Path(turnCredentialsAPIPath))
+	}
+
 	routes.HandleSupervisorFrontendRoute(r.PathPrefix("/_supervisor/frontend"))
 	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_supervisor/v1/status/supervisor"), false)
 	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_supervisor/v1/status/ide"), false)
-	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_supervisor/v1"), true)
+	routes.HandleSupervisorAPIRoute(r.PathPrefix("/_supervisor/v1"))
 	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_supervisor"), true)
 
-	routes.HandleDirectIDERoute(r.MatcherFunc(func(req *http.Request, m *mux.RouteMatch) bool {
+	routes.HandleForeignContentRoute(r.MatcherFunc(func(req *http.Request, m *mux.RouteMatch) bool {
 		return m.Vars != nil && m.Vars[foreignOriginPrefix] != ""
 	}))
 
+	if bs := config.Config.BlobServer; bs != nil && bs.IDEStatic != nil {
+		for _, p := range bs.IDEStatic.Paths {
+			routes.HandleIDEStaticRoute(r.PathPrefix(p.Prefix), p)
+		}
+	}
+
 	routes.HandleRoot(r.NewRoute())
 }
 
 func newIDERoutes(config *RouteHandlerConfig, ip WorkspaceInfoProvider) *ideRoutes {
+	var (
+		mustExist     = workspaceMustExistHandler(config.Config, ip, config.WorkspaceWaker, config.ErrorPages)
+		instanceMatch = instanceMustMatchHandler(config.Config.GitpodInstallation.HostName, config.ErrorPages)
+		experiments   = experimentHandler(config.Config.Experiments, config.ExperimentTracker)
+	)
 	return &ideRoutes{
-		Config:                    config,
-		InfoProvider:              ip,
-		workspaceMustExistHandler: workspaceMustExistHandler(config.Config, ip),
+		Config:       config,
+		InfoProvider: ip,
+		// all IDE routes which know the workspace serve the instance the client expects according to its experiment variants
+		workspaceMustExistHandler: func(h http.Handler) http.Handler { return mustExist(instanceMatch(experiments(h))) },
 	}
 }
 
@@ -125,13 +334,30 @@ type ideRoutes struct {
 }
 
 func (ir *ideRoutes) HandleDirectIDERoute(route *mux.Route) {
+	ir.handleDirectIDERoute(route, "HandleDirectIDERoute", RouteIDEDirect)
+}
+
+// HandleForeignContentRoute serves foreign content, e.g. webviews, which is served from its own origin.
+// Foreign content is sandboxed: it never sees the credentials of the workspace owner and cannot set cookies.
+func (ir *ideRoutes) HandleForeignContentRoute(route *mux.Route) {
 	r := route.Subrouter()
-	r.Use(logRouteHandlerHandler("HandleDirectIDERoute"))
+	r.Use(logRouteHandlerHandler("HandleForeignContentRoute"))
 	r.Use(ir.Config.CorsHandler)
 	r.Use(ir.Config.WorkspaceAuthHandler)
 	r.Use(ir.workspaceMustExistHandler)
+	r.Use(stripCredentialsHandler)
 
-	r.NewRoute().HandlerFunc(proxyPass(ir.Config, workspacePodResolver))
+	r.NewRoute().HandlerFunc(routePass(ir.Config, ir.InfoProvider, RouteForeign, withSetCookieFilter()))
+}
+
+func (ir *ideRoutes) handleDirectIDERoute(route *mux.Route, handlerName, routeName string) {
+	r := route.Subrouter()
+	r.Use(logRouteHandlerHandler(handlerName))
+	r.Use(ir.Config.CorsHandler)
+	r.Use(ir.Config.WorkspaceAuthHandler)
+	r.Use(ir.workspaceMustExistHandler)
+
+	r.NewRoute().HandlerFunc(routePass(ir.Config, ir.InfoProvider, routeName))
 }
 
 func (ir *ideRoutes) HandleDirectSupervisorRoute(route *mux.Route, authenticated bool) {
@@ -143,7 +369,41 @@ func (ir *ideRoutes) HandleDirectSupervisorRoute(route *mux.Route, authenticated
 		r.Use(ir.Config.WorkspaceAuthHandler)
 	}
 
-	r.NewRoute().HandlerFunc(proxyPass(ir.Config, workspacePodSupervisorResolver))
+	r.NewRoute().HandlerFunc(routePass(ir.Config, ir.InfoProvider, RouteSupervisor))
+}
+
+// HandleSupervisorAPIRoute proxies the supervisor API. Unlike the IDE, the API is available to the workspace owner only,
+// even if the workspace is shared. Streams (e.g. terminal output) and websockets are passed through unbuffered.
+func (ir *ideRoutes) HandleSupervisorAPIRoute(route *mux.Route) {
+	r := route.Subrouter()
+	r.Use(logRouteHandlerHandler("HandleSupervisorAPIRoute"))
+	r.Use(ir.Config.CorsHandler)
+	r.Use(ir.workspaceMustExistHandler)
+	r.Use(ir.Config.SupervisorAuthHandler)
+
+	r.NewRoute().HandlerFunc(ir.Config.SupervisorHandshakes.Handler(routePass(ir.Config, ir.InfoProvider, RouteSupervisorAPI, withStreaming())))
+}
+
+// HandlePortTokensRoute lets the workspace owner issue and revoke port tokens
+func (ir *ideRoutes) HandlePortTokensRoute(route *mux.Route) {
+	r := route.Subrouter()
+	r.Use(logRouteHandlerHandler("HandlePortTokensRoute"))
+	r.Use(ir.Config.CorsHandler)
+	r.Use(ir.workspaceMustExistHandler)
+	r.Use(ir.Config.SupervisorAuthHandler)
+
+	r.NewRoute().Handler(ir.Config.PortTokens)
+}
+
+// HandleTURNCredentialsRoute lets the workspace owner obtain credentials for the TURN relay
+func (ir *ideRoutes) HandleTURNCredentialsRoute(route *mux.Route) {
+	r := route.Subrouter()
+	r.Use(logRouteHandlerHandler("HandleTURNCredentialsRoute"))
+	r.Use(ir.Config.CorsHandler)
+	r.Use(ir.workspaceMustExistHandler)
+	r.Use(ir.Config.SupervisorAuthHandler)
+
+	r.NewRoute().Handler(ir.Config.TURN)
 }
 
 func (ir *ideRoutes) HandleSupervisorFrontendRoute(route *mux.Route) {
@@ -163,13 +423,14 @@ func (ir *ideRoutes) HandleSupervisorFrontendRoute(route *mux.Route) {
 		})
 	})
 	// always hit the blobserver to ensure that blob is downloaded
+	rc := ir.Config.Config.Routes.Get(RouteSupervisor)
 	r.NewRoute().HandlerFunc(proxyPass(ir.Config, func(cfg *Config, req *http.Request) (tgt *url.URL, err error) {
 		var dst url.URL
 		dst.Scheme = cfg.BlobServer.Scheme
 		dst.Host = cfg.BlobServer.Host
 		dst.Path = "/" + cfg.WorkspacePodConfig.SupervisorImage
 		return &dst, nil
-	}, func(h *proxyPassConfig) {
+	}, withRoute(rc), func(h *proxyPassConfig) {
 		h.Transport = &blobserveTransport{
 			transport: h.Transport,
 			Config:    ir.Config.Config,
@@ -195,10 +456,11 @@ func (ir *ideRoutes) HandleRoot(route *mux.Route) {
 	r.Use(ir.workspaceMustExistHandler)
 
 	workspaceIDEPass := ir.Config.WorkspaceAuthHandler(
-		proxyPass(ir.Config, workspacePodResolver),
+		routePass(ir.Config, ir.InfoProvider, RouteIDE, withMaintenanceBanner(ir.Config)),
 	)
 	// always hit the blobserver to ensure that blob is downloaded
-	r.NewRoute().HandlerFunc(proxyPass(ir.Config, dynamicIDEResolver, func(h *proxyPassConfig) {
+	rc := ir.Config.Config.Routes.Get(RouteIDE)
+	r.NewRoute().HandlerFunc(routeHandler(rc, proxyPass(ir.Config, dynamicIDEResolver, withRoute(rc), func(h *proxyPassConfig) {
 		h.Transport = &blobserveTransport{
 			transport: h.Transport,
 			Config:    ir.Config.Config,
@@ -211,7 +473,7 @@ func (ir *ideRoutes) HandleRoot(route *mux.Route) {
 				return info.IDEImage
 			},
 		}
-	}, withHTTPErrorHandler(workspaceIDEPass)))
+	}, withHTTPErrorHandler(workspaceIDEPass), withMaintenanceBanner(ir.Config))))
 }
 
 const imagePathSeparator = "/__files__"
@@ -219,6 +481,9 @@ const imagePathSeparator = "/__files__"
 // installBlobserveRoutes  implements long-lived caching with versioned URLs, see https://web.dev/http-cache/#versioned-urls
 func installBlobserveRoutes(r *mux.Router, config *RouteHandlerConfig) {
 	r.Use(logHandler)
+	r.Use(tracingHandler)
+	r.Use(config.SLOTracker.Handler(SLORouteClassBlobserve))
+	r.Use(headerPolicyHandler(config.Config.Headers, SLORouteClassBlobserve, nil))
 	r.Use(handlers.CompressHandler)
 	r.Use(logRouteHandlerHandler("BlobserveRootHandler"))
 	r.Use(handlers.CORS(
@@ -244,24 +509,49 @@ func installBlobserveRoutes(r *mux.Router, config *RouteHandlerConfig) {
 }
 
 // installWorkspacePortRoutes configures routing for exposed ports
-func installWorkspacePortRoutes(r *mux.Router, config *RouteHandlerConfig) error {
-	showPortNotFoundPage, err := servePortNotFoundPage(config.Config)
+func installWorkspacePortRoutes(r *mux.Router, config *RouteHandlerConfig, ip WorkspaceInfoProvider) error {
+	cors, err := newPortCORS(config.Config.PortCORS, ip)
+	if err != nil {
+		return err
+	}
+	clientIdentity, err := clientIdentityHandler(config.Config.TransportConfig.ClientIdentity)
 	if err != nil {
 		return err
 	}
 
+	r.Use(verifyHandler(config.Config.VerifyEndpoint, SLORouteClassPort))
+	// port tokens must not show up in logs, hence we verify and strip them first
+	r.Use(config.PortTokens.Handler(config.ErrorPages))
 	r.Use(logHandler)
+	r.Use(tracingHandler)
+	r.Use(config.SLOTracker.Handler(SLORouteClassPort))
+	r.Use(config.AnomalyDetector.Handler())
+	r.Use(headerPolicyHandler(config.Config.Headers, SLORouteClassPort, ip))
+	r.Use(readOnlyHeaderHandler(config))
+	// preflight requests never carry credentials, hence we must answer them before authentication
+	r.Use(cors.Handler)
 	r.Use(config.WorkspaceAuthHandler)
-	// filter all session cookies
-	r.Use(sensitiveCookieHandler(config.Config.GitpodInstallation.HostName))
+	r.Use(config.AbuseDetector.Handler(config.ErrorPages))
+	// only authenticated requests may make us ask supervisor about the ports
+	r.Use(config.PortGate.Handler(config.ErrorPages, func(req *http.Request) (*url.URL, error) {
+		return dynamicWorkspacePortResolver(ip)(config.Config, req)
+	}))
+	r.Use(config.ActivityTracker.Handler)
+	r.Use(config.BandwidthTracker.Handler)
+	// filter all Gitpod cookies and internal headers so that applications cannot harvest their visitors' credentials
+	r.Use(sensitiveCookieHandler(config.Config.GitpodInstallation.HostName, config.Config.PortCredentials))
+	r.Use(clientIdentity)
+	r.Use(portCompressHandler(config.Config.Compression))
 
 	// forward request to workspace port
 	r.NewRoute().HandlerFunc(
-		proxyPass(
+		routePass(
 			config,
-			workspacePodPortResolver,
-			withHTTPErrorHandler(showPortNotFoundPage),
+			ip,
+			RoutePort,
+			withHTTPErrorHandler(config.ErrorPages.Handler(ErrorPagePortNotFound, http.StatusNotFound)),
 			withXFrameOptionsFilter(),
+			withPortCORS(cors),
 		),
 	)
 
@@ -280,6 +570,33 @@ func workspacePodPortResolver(config *Config, req *http.Request) (url *url.URL,
 	return buildWorkspacePodURL(config.WorkspacePodConfig.PortServiceTemplate, coords.ID, coords.Port)
 }
 
+// dynamicWorkspacePortResolver resolves ports within the dynamic port range which were never exposed to the workspace pod's IP.
+// All other ports resolve like workspacePodPortResolver.
+func dynamicWorkspacePortResolver(ip WorkspaceInfoProvider) targetResolver {
+	return func(config *Config, req *http.Request) (*url.URL, error) {
+		coords := getWorkspaceCoords(req)
+		port, err := strconv.ParseUint(coords.Port, 10, 16)
+		if err != nil || !config.DynamicPorts.Contains(uint16(port)) {
+			return workspacePodPortResolver(config, req)
+		}
+
+		info := ip.WorkspaceInfo(req.Context(), coords.ID)
+		if info == nil || info.IPAddress == "" {
+			return workspacePodPortResolver(config, req)
+		}
+		for _, p := range info.Ports {
+			if p.Port == uint32(port) {
+				return workspacePodPortResolver(config, req)
+			}
+		}
+
+		return &url.URL{
+			Scheme: "http",
+			Host:   net.JoinHostPort(info.IPAddress, coords.Port),
+		}, nil
+	}
+}
+
 // workspacePodSupervisorResolver resolves to the workspace pods Supervisor url from the given request
 func workspacePodSupervisorResolver(config *Config, req *http.Request) (url *url.URL, err error) {
 	coords := getWorkspaceCoords(req)
@@ -324,17 +641,10 @@ func buildWorkspacePodURL(tmpl string, workspaceID string, port string) (*url.UR
 func corsHandler(scheme, hostname string) (mux.MiddlewareFunc, error) {
 	origin := fmt.Sprintf("%s://%s", scheme, hostname)
 
-	domainRegex := strings.ReplaceAll(hostname, ".", "\\.")
-	originRegex, err := regexp.Compile(".*" + domainRegex)
-	if err != nil {
-		return nil, err
-	}
-
 	return handlers.CORS(
 		handlers.AllowedOriginValidator(func(origin string) bool {
 			// Is the origin a subdomain of the installations hostname?
-			matches := originRegex.Match([]byte(origin))
-			return matches
+			return wsurl.IsSubdomainOrigin(origin, hostname)
 		}),
 		// TODO(gpl) For domain-based workspace access with authentication (for accessing the IDE) we need to respond with the precise Origin header that was sent
 		handlers.AllowedOrigins([]string{origin}),
@@ -406,10 +716,17 @@ func getLog(ctx context.Context) *logrus.Entry {
 	return rl
 }
 
-func sensitiveCookieHandler(domain string) func(h http.Handler) http.Handler {
+// sensitiveCookieHandler removes the Gitpod cookies and internal headers from requests, except for those cfg allows
+func sensitiveCookieHandler(domain string, cfg *PortCredentialsConfig) func(h http.Handler) http.Handler {
+	var allowCookies []string
+	if cfg != nil {
+		allowCookies = cfg.AllowCookies
+	}
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
-			cookies := removeSensitiveCookies(readCookies(req.Header, ""), domain)
+			removeInternalHeaders(req.Header, cfg)
+
+			cookies := removeSensitiveCookies(readCookies(req.Header, ""), domain, allowCookies...)
 			header := make([]string, 0, len(cookies))
 			for _, c := range cookies {
 				if c == nil {
@@ -450,11 +767,18 @@ func sensitiveCookieHandler(domain string) func(h http.Handler) http.Handler {
 }
 
 // workspaceMustExistHandler redirects if we don't know about a workspace yet.
-func workspaceMustExistHandler(config *Config, infoProvider WorkspaceInfoProvider) mux.MiddlewareFunc {
+func workspaceMustExistHandler(config *Config, infoProvider WorkspaceInfoProvider, waker *WorkspaceWaker, pages *ErrorPages) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 			coords := getWorkspaceCoords(req)
 			info := infoProvider.WorkspaceInfo(req.Context(), coords.ID)
+			if info == nil && serveNotReady(pages, infoProvider, resp, req) {
+				// neither waking nor restarting a workspace we merely haven't heard of yet
+				return
+			}
+			if waker != nil && waker.Handle(resp, req, coords.ID, info) {
+				return
+			}
 			if info == nil {
 				log.WithFields(log.OWI("", coords.ID, "")).Info("no workspace info found - redirecting to start")
 				redirectURL := fmt.Sprintf("%s://%s/start/#%s", config.GitpodInstallation.Scheme, config.GitpodInstallation.HostName, coords.ID)
@@ -477,9 +801,9 @@ func getWorkspaceInfoFromContext(ctx context.Context) *WorkspaceInfo {
 	return rl
 }
 
-// removeSensitiveCookies all sensitive cookies from the list.
+// removeSensitiveCookies all sensitive cookies from the list, i.e. all cookies Gitpod sets, except for the allowed ones.
 // This function modifies the slice in-place.
-func removeSensitiveCookies(cookies []*http.Cookie, domain string) []*http.Cookie {
+func removeSensitiveCookies(cookies []*http.Cookie, domain string, allow ...string) []*http.Cookie {
 	hostnamePrefix := domain
 	for _, c := range []string{" ", "-", "."} {
 		hostnamePrefix = strings.ReplaceAll(hostnamePrefix, c, "_")
@@ -500,6 +824,10 @@ func removeSensitiveCookies(cookies []*http.Cookie, domain string) []*http.Cooki
 			// skip owner token
 			continue
 		}
+		if strings.HasPrefix(c.Name, hostnamePrefix) && !containsFold(allow, c.Name) {
+			// skip all other Gitpod cookies, e.g. the workspace instance hint
+			continue
+		}
 		log.WithField("hostnamePrefix", hostnamePrefix).WithField("name", c.Name).Debug("keeping cookie")
 		cookies[n] = c
 		n++
@@ -583,10 +911,10 @@ func (t *blobserveTransport) redirect(image string, req *http.Request) (*http.Re
 
 	var location string
 	if t.Config.GitpodInstallation.WorkspaceHostSuffix != "" {
-		location = fmt.Sprintf("%s://%s%s/%s%s%s",
+		scheme := wsurl.Scheme{HostSuffix: t.Config.GitpodInstallation.WorkspaceHostSuffix}
+		location = fmt.Sprintf("%s://%s/%s%s%s",
 			t.Config.GitpodInstallation.Scheme,
-			"blobserve",
-			t.Config.GitpodInstallation.WorkspaceHostSuffix,
+			scheme.BlobserveHost(),
 			image,
 			imagePathSeparator,
 			path,
@@ -623,24 +951,3 @@ func (t *blobserveTransport) redirect(image string, req *http.Request) (*http.Re
 }
 
 // endregion
-
-const (
-	builtinPagePortNotFound = "port-not-found.html"
-)
-
-func servePortNotFoundPage(config *Config) (http.Handler, error) {
-	fn := filepath.Join(config.BuiltinPages.Location, builtinPagePortNotFound)
-	if tp := os.Getenv("TELEPRESENCE_ROOT"); tp != "" {
-		fn = filepath.Join(tp, fn)
-	}
-	page, err := os.ReadFile(fn)
-	if err != nil {
-		return nil, err
-	}
-	page = bytes.ReplaceAll(page, []byte("https://gitpod.io"), []byte(fmt.Sprintf("%s://%s", config.GitpodInstallation.Scheme, config.GitpodInstallation.HostName)))
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write(page)
-	}), nil
-}
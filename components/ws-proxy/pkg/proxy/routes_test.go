@@ -18,6 +18,13 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/util"
@@ -44,9 +51,22 @@ var (
 			},
 			URL:         "https://amaranth-smelt-9ba20cc1.test-domain.com/",
 			WorkspaceID: "amaranth-smelt-9ba20cc1",
+			IPAddress:   "localhost",
 		},
 	}
 
+	pinnedIDEWorkspace = func() WorkspaceInfo {
+		ws := workspaces[0]
+		ws.IDEImage = "gitpod-io/ide@sha256:0a1b2c"
+		return ws
+	}()
+
+	sharedWorkspace = func() WorkspaceInfo {
+		ws := workspaces[0]
+		ws.Auth = &api.WorkspaceAuthentication{Admission: api.AdmissionLevel_ADMIT_EVERYONE, OwnerToken: "owner-token"}
+		return ws
+	}()
+
 	ideServerHost  = "localhost:20000"
 	workspacePort  = uint16(20001)
 	supervisorPort = uint16(20002)
@@ -81,6 +101,43 @@ var (
 			Location: "../../public",
 		},
 	}
+
+	ideStaticConfig = func() Config {
+		cfg := config
+		cfg.BlobServer = &BlobServerConfig{
+			Host:   blobServeHost,
+			Scheme: "http",
+			IDEStatic: &IDEStaticConfig{
+				Paths: []IDEStaticPath{{Prefix: "/static", ImagePath: "/out/static"}},
+			},
+		}
+		return cfg
+	}()
+
+	routeTableConfig = func() Config {
+		cfg := config
+		cfg.Routes = RouteTable{
+			RouteIDEDirect: {Upstream: UpstreamSupervisor},
+			RouteForeign:   {Websocket: &RouteWebsocketConfig{Disabled: true}},
+		}
+		return cfg
+	}()
+
+	foreignContentConfig = func() Config {
+		cfg := config
+		cfg.ForeignContent = &ForeignContentConfig{HostSuffix: ".foreign-domain.com"}
+		return cfg
+	}()
+
+	upstreamRetryConfig = func() Config {
+		cfg := config
+		cfg.UpstreamRetry = &UpstreamRetryConfig{
+			Budget:          util.Duration(50 * time.Millisecond),
+			InitialBackoff:  util.Duration(10 * time.Millisecond),
+			UnhealthyPeriod: util.Duration(time.Minute),
+		}
+		return cfg
+	}()
 )
 
 type Target struct {
@@ -308,6 +365,87 @@ func TestRoutes(t *testing.T) {
 				Body: "workspace hit: /not-from-failed-blobserve\n",
 			},
 		},
+		{
+			Desc:   "IDE static from blobserve",
+			Config: &ideStaticConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"static/main.js", nil),
+				addHostHeader,
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}, Blobserve: &Target{Status: http.StatusOK}},
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Cache-Control":  {"no-cache"},
+					"Content-Length": {"56"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+				},
+				Body: "blobserve hit: /gitpod-io/ide:latest/out/static/main.js\n",
+			},
+		},
+		{
+			Desc:   "IDE static blobserve miss",
+			Config: &ideStaticConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"static/main.js", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}, Blobserve: &Target{Status: http.StatusNotFound}},
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Content-Length": {"31"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+				},
+				Body: "workspace hit: /static/main.js\n",
+			},
+		},
+		{
+			Desc:   "IDE static blobserve miss unauthorized",
+			Config: &ideStaticConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"static/main.js", nil),
+				addHostHeader,
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}, Blobserve: &Target{Status: http.StatusNotFound}},
+			Expectation: Expectation{
+				Status: http.StatusUnauthorized,
+			},
+		},
+		{
+			Desc:       "IDE static pinned image",
+			Config:     &ideStaticConfig,
+			Workspaces: []WorkspaceInfo{pinnedIDEWorkspace},
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"static/main.js", nil),
+				addHostHeader,
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}, Blobserve: &Target{Status: http.StatusOK}},
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Cache-Control":  {"no-cache"},
+					"Content-Length": {"63"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+					"Etag":           {`"sha256:0a1b2c"`},
+				},
+				Body: "blobserve hit: /gitpod-io/ide@sha256:0a1b2c/out/static/main.js\n",
+			},
+		},
+		{
+			Desc:       "IDE static pinned image not modified",
+			Config:     &ideStaticConfig,
+			Workspaces: []WorkspaceInfo{pinnedIDEWorkspace},
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"static/main.js", nil),
+				addHostHeader,
+				addHeader("If-None-Match", `"sha256:0a1b2c"`),
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}, Blobserve: &Target{Status: http.StatusInternalServerError}},
+			Expectation: Expectation{
+				Status: http.StatusNotModified,
+				Header: http.Header{
+					"Cache-Control": {"no-cache"},
+					"Etag":          {`"sha256:0a1b2c"`},
+				},
+			},
+		},
 		{
 			Desc:   "CORS preflight",
 			Config: &config,
@@ -330,6 +468,58 @@ func TestRoutes(t *testing.T) {
 				Body: "workspace hit: /somewhere/in/the/ide\n",
 			},
 		},
+		{
+			Desc:   "route table upstream",
+			Config: &routeTableConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"services", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+			),
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Content-Length": {"26"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+				},
+				Body: "supervisor hit: /services\n",
+			},
+		},
+		{
+			Desc:   "upstream retry workspace starting",
+			Config: &upstreamRetryConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"services", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+				addHeader("Accept", "application/json"),
+			),
+			Targets: &Targets{},
+			Expectation: Expectation{
+				Status: http.StatusServiceUnavailable,
+				Header: http.Header{
+					"Content-Type": {"application/json"},
+					"Retry-After":  {"1"},
+				},
+				Body: "{\"error\":\"workspace-starting\",\"status\":503,\"message\":\"workspace is starting\",\"workspaceID\":\"amaranth-smelt-9ba20cc1\"}\n",
+			},
+		},
+		{
+			Desc:   "route table websocket disabled",
+			Config: &routeTableConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", "https://webview-"+workspaces[0].WorkspaceID+wsHostSuffix+"/index.html", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+				addHeader("Connection", "Upgrade"),
+				addHeader("Upgrade", "websocket"),
+			),
+			Expectation: Expectation{
+				Status: http.StatusBadRequest,
+				Header: http.Header{
+					"Content-Type":           {"text/plain; charset=utf-8"},
+					"X-Content-Type-Options": {"nosniff"},
+				},
+				Body: "websockets are disabled on this route\n",
+			},
+		},
 		{
 			Desc: "unauthenticated supervisor API (supervisor status)",
 			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"_supervisor/v1/status/supervisor", nil),
@@ -382,6 +572,105 @@ func TestRoutes(t *testing.T) {
 				Body: "supervisor hit: /_supervisor/v1/status/content\n",
 			},
 		},
+		{
+			Desc: "supervisor API with owner token header",
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"_supervisor/v1/status/content", nil),
+				addHostHeader,
+				addHeader("X-Gitpod-Owner-Token", workspaces[0].Auth.OwnerToken),
+			),
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Content-Length": {"47"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+				},
+				Body: "supervisor hit: /_supervisor/v1/status/content\n",
+			},
+		},
+		{
+			Desc: "supervisor API with wrong owner token header",
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"_supervisor/v1/status/content", nil),
+				addHostHeader,
+				addHeader("X-Gitpod-Owner-Token", "foobar"),
+			),
+			Expectation: Expectation{
+				Status: http.StatusForbidden,
+			},
+		},
+		{
+			Desc:       "shared workspace unauthenticated supervisor API",
+			Workspaces: []WorkspaceInfo{sharedWorkspace},
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"_supervisor/v1/terminal/list", nil),
+				addHostHeader,
+			),
+			Expectation: Expectation{
+				Status: http.StatusUnauthorized,
+			},
+		},
+		{
+			Desc:       "shared workspace unauthenticated IDE",
+			Workspaces: []WorkspaceInfo{sharedWorkspace},
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"services", nil),
+				addHostHeader,
+			),
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Content-Length": {"25"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+				},
+				Body: "workspace hit: /services\n",
+			},
+		},
+		{
+			Desc:   "stale instance cookie",
+			Config: &config,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL, nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+				addCookie(http.Cookie{Name: "_test_domain_com_ws_" + workspaces[0].WorkspaceID + "_instance_", Value: "a-previous-instance"}),
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}},
+			Expectation: Expectation{
+				Status: http.StatusGone,
+			},
+		},
+		{
+			Desc:   "stale instance header",
+			Config: &config,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL, nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+				addHeader("X-Gitpod-Instance-Id", "a-previous-instance"),
+				addHeader("Accept", "application/json"),
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}},
+			Expectation: Expectation{
+				Status: http.StatusGone,
+				Header: http.Header{
+					"Content-Type": {"application/json"},
+				},
+				Body: "{\"error\":\"workspace-restarted\",\"status\":410,\"message\":\"this workspace was restarted\",\"workspaceID\":\"amaranth-smelt-9ba20cc1\"}\n",
+			},
+		},
+		{
+			Desc:   "current instance header",
+			Config: &config,
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL, nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+				addHeader("X-Gitpod-Instance-Id", workspaces[0].InstanceID),
+			),
+			Targets: &Targets{Workspace: &Target{Status: http.StatusOK}},
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Content-Length": {"17"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+				},
+				Body: "workspace hit: /\n",
+			},
+		},
 		{
 			Desc: "non-existent authorized GET /",
 			Request: modifyRequest(httptest.NewRequest("GET", strings.ReplaceAll(workspaces[0].URL, "amaranth", "blabla"), nil),
@@ -487,12 +776,11 @@ func TestRoutes(t *testing.T) {
 			Targets: &Targets{},
 			Expectation: Expectation{
 				Status: http.StatusNotFound,
-				Body: "<!doctype html>\n<!--\n Copyright (c) 2020 Gitpod GmbH. All rights reserved.\n " +
-					"Licensed under the GNU Affero General Public License (AGPL).\n See License-AGPL." +
-					"txt in the project root for license information.\n-->\n\n<html lang=\"en\">\n  <" +
+				Header: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+				Body: "<!doctype html>\n\n\n<html lang=\"en\">\n  <" +
 					"head>\n    <meta charset=\"utf-8\">\n    <meta name=\"viewport\" content=\"user-" +
 					"scalable=0, initial-scale=1, minimum-scale=1, width=device-width, height=device-" +
-					"height\">\n    <!-- PWA primary color -->\n    <meta name=\"theme-color\" conten" +
+					"height\">\n    \n    <meta name=\"theme-color\" conten" +
 					"t=\"#000000\">\n    <link rel=\"manifest\" href=\"https://test-domain.com/manife" +
 					"st.webmanifest\">\n    <link rel=\"apple-touch-icon\" type=\"image/png\" href=\"https:/" +
 					"/test-domain.com/images/apple-touch-icon.png\" sizes=\"180x180\"/>\n    <link re" +
@@ -533,6 +821,27 @@ func TestRoutes(t *testing.T) {
 					"    window.location.reload(true);\n      });\n    </script>\n  </body>\n</html>\n",
 			},
 		},
+		{
+			Desc: "dynamic port GET",
+			Config: func() *Config {
+				cfg := config
+				cfg.DynamicPorts = &DynamicPortsConfig{Start: workspacePort, End: supervisorPort}
+				// dynamic ports must not depend on the port service
+				podCfg := *cfg.WorkspacePodConfig
+				podCfg.PortServiceTemplate = "http://port-service.invalid:{{ .port }}"
+				cfg.WorkspacePodConfig = &podCfg
+				return &cfg
+			}(),
+			Request: modifyRequest(httptest.NewRequest("GET", fmt.Sprintf("https://%d-%s.test-domain.com/dev-server", workspacePort, workspaces[0].WorkspaceID), nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+			),
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{"Content-Length": {"27"}, "Content-Type": {"text/plain; charset=utf-8"}},
+				Body:   "workspace hit: /dev-server\n",
+			},
+		},
 		{
 			Desc: "port cookies",
 			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].Ports[0].Url+"this-does-not-exist", nil),
@@ -554,6 +863,83 @@ func TestRoutes(t *testing.T) {
 				Body:   "[\"foobar=baz;another=cookie\"]\n",
 			},
 		},
+		{
+			Desc: "port internal headers",
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].Ports[0].Url+"this-does-not-exist", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+				addHeader("X-Gitpod-Owner-Token", workspaces[0].Auth.OwnerToken),
+				addHeader("X-Gitpod-Instance-Id", workspaces[0].InstanceID),
+				addHeader("X-Custom", "kept"),
+			),
+			Targets: &Targets{
+				Port: &Target{
+					Handler: func(w http.ResponseWriter, r *http.Request, requestCount uint8) {
+						fmt.Fprintf(w, "%+q %+q %+q\n", r.Header["X-Gitpod-Owner-Token"], r.Header["X-Gitpod-Instance-Id"], r.Header["X-Custom"])
+					},
+				},
+			},
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{"Content-Length": {"15"}, "Content-Type": {"text/plain; charset=utf-8"}},
+				Body:   "[] [] [\"kept\"]\n",
+			},
+		},
+		{
+			Desc: "foreign content without credentials",
+			Request: modifyRequest(httptest.NewRequest("GET", "https://webview-"+workspaces[0].WorkspaceID+wsHostSuffix+"/index.html", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+				addCookie(http.Cookie{Name: "foobar", Value: "baz"}),
+				addHeader("Authorization", "Bearer foobar"),
+			),
+			Targets: &Targets{
+				Workspace: &Target{
+					Handler: func(w http.ResponseWriter, r *http.Request, requestCount uint8) {
+						http.SetCookie(w, &http.Cookie{Name: "foreign", Value: "content"})
+						fmt.Fprintf(w, "%+q %+q\n", r.Header["Cookie"], r.Header["Authorization"])
+					},
+				},
+			},
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{"Content-Length": {"6"}, "Content-Type": {"text/plain; charset=utf-8"}},
+				Body:   "[] []\n",
+			},
+		},
+		{
+			Desc: "foreign content requires authentication",
+			Request: modifyRequest(httptest.NewRequest("GET", "https://webview-"+workspaces[0].WorkspaceID+wsHostSuffix+"/index.html", nil),
+				addHostHeader,
+			),
+			Expectation: Expectation{
+				Status: http.StatusUnauthorized,
+			},
+		},
+		{
+			Desc:   "foreign content on its own hosts",
+			Config: &foreignContentConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", "https://webview-"+workspaces[0].WorkspaceID+".foreign-domain.com/index.html", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+			),
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{"Content-Length": {"27"}, "Content-Type": {"text/plain; charset=utf-8"}},
+				Body:   "workspace hit: /index.html\n",
+			},
+		},
+		{
+			Desc:   "no foreign content on workspace hosts",
+			Config: &foreignContentConfig,
+			Request: modifyRequest(httptest.NewRequest("GET", "https://webview-"+workspaces[0].WorkspaceID+wsHostSuffix+"/index.html", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+			),
+			Expectation: Expectation{
+				Status: http.StatusNotFound,
+			},
+		},
 		{
 			Desc: "port GET 200 w/o X-Frame-Options header",
 			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].Ports[0].Url+"returns-200-with-frame-options-header", nil),
@@ -641,12 +1027,16 @@ func TestRoutes(t *testing.T) {
 			if test.Config != nil {
 				cfg = *test.Config
 			}
-			router := HostBasedRouter(hostBasedHeader, wsHostSuffix)
+			router := HostBasedRouter(hostBasedHeader, wsHostSuffix, cfg.ForeignContent)
 			if test.Router != nil {
 				router = test.Router(&cfg)
 			}
 
-			proxy := NewWorkspaceProxy(":8080", cfg, router, &fakeWsInfoProvider{infos: workspaces})
+			infos := workspaces
+			if test.Workspaces != nil {
+				infos = test.Workspaces
+			}
+			proxy := NewWorkspaceProxy(":8080", cfg, router, &fakeWsInfoProvider{infos: infos})
 			handler, err := proxy.Handler()
 			if err != nil {
 				t.Fatalf("cannot create proxy handler: %q", err)
@@ -721,6 +1111,7 @@ func TestRemoveSensitiveCookies(t *testing.T) {
 		sessionCookie     = &http.Cookie{Domain: domain, Name: "_test_domain_com_", Value: "fobar"}
 		portAuthCookie    = &http.Cookie{Domain: domain, Name: "_test_domain_com_ws_77f6b236_3456_4b88_8284_81ca543a9d65_port_auth_", Value: "some-token"}
 		ownerCookie       = &http.Cookie{Domain: domain, Name: "_test_domain_com_ws_77f6b236_3456_4b88_8284_81ca543a9d65_owner_", Value: "some-other-token"}
+		instanceCookie    = &http.Cookie{Domain: domain, Name: "_test_domain_com_ws_amaranth-smelt-9ba20cc1_instance_", Value: "some-instance"}
 		miscCookie        = &http.Cookie{Domain: domain, Name: "some-other-cookie", Value: "I like cookies"}
 		invalidCookieName = &http.Cookie{Domain: domain, Name: "foobar[0]", Value: "violates RFC6266"}
 	)
@@ -728,18 +1119,22 @@ func TestRemoveSensitiveCookies(t *testing.T) {
 	tests := []struct {
 		Name     string
 		Input    []*http.Cookie
+		Allow    []string
 		Expected []*http.Cookie
 	}{
-		{"no cookies", []*http.Cookie{}, []*http.Cookie{}},
-		{"session cookie", []*http.Cookie{sessionCookie, miscCookie}, []*http.Cookie{miscCookie}},
-		{"portAuth cookie", []*http.Cookie{portAuthCookie, miscCookie}, []*http.Cookie{miscCookie}},
-		{"owner cookie", []*http.Cookie{ownerCookie, miscCookie}, []*http.Cookie{miscCookie}},
-		{"misc cookie", []*http.Cookie{miscCookie}, []*http.Cookie{miscCookie}},
-		{"invalid cookie name", []*http.Cookie{invalidCookieName}, []*http.Cookie{invalidCookieName}},
+		{"no cookies", []*http.Cookie{}, nil, []*http.Cookie{}},
+		{"session cookie", []*http.Cookie{sessionCookie, miscCookie}, nil, []*http.Cookie{miscCookie}},
+		{"portAuth cookie", []*http.Cookie{portAuthCookie, miscCookie}, nil, []*http.Cookie{miscCookie}},
+		{"owner cookie", []*http.Cookie{ownerCookie, miscCookie}, nil, []*http.Cookie{miscCookie}},
+		{"instance cookie", []*http.Cookie{instanceCookie, miscCookie}, nil, []*http.Cookie{miscCookie}},
+		{"allowed instance cookie", []*http.Cookie{instanceCookie, miscCookie}, []string{instanceCookie.Name}, []*http.Cookie{instanceCookie, miscCookie}},
+		{"owner cookie cannot be allowed", []*http.Cookie{ownerCookie}, []string{ownerCookie.Name}, []*http.Cookie{}},
+		{"misc cookie", []*http.Cookie{miscCookie}, nil, []*http.Cookie{miscCookie}},
+		{"invalid cookie name", []*http.Cookie{invalidCookieName}, nil, []*http.Cookie{invalidCookieName}},
 	}
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			res := removeSensitiveCookies(test.Input, domain)
+			res := removeSensitiveCookies(test.Input, domain, test.Allow...)
 			if diff := cmp.Diff(test.Expected, res); diff != "" {
 				t.Errorf("unexpected result (-want +got):\n%s", diff)
 			}
@@ -771,7 +1166,7 @@ func TestSensitiveCookieHandler(t *testing.T) {
 			rec := httptest.NewRecorder()
 
 			var act string
-			sensitiveCookieHandler(domain)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			sensitiveCookieHandler(domain, nil)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 				act = r.Header.Get("cookie")
 				rw.WriteHeader(http.StatusOK)
 			})).ServeHTTP(rec, req)
@@ -782,3 +1177,96 @@ func TestSensitiveCookieHandler(t *testing.T) {
 		})
 	}
 }
+
+type echoTestService struct {
+	grpc_testing.UnimplementedTestServiceServer
+}
+
+func (echoTestService) FullDuplexCall(srv grpc_testing.TestService_FullDuplexCallServer) error {
+	srv.SetTrailer(metadata.Pairs("echo-trailer", "done"))
+	for {
+		req, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = srv.Send(&grpc_testing.StreamingOutputCallResponse{Payload: req.Payload})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func TestGRPCPassthrough(t *testing.T) {
+	log.Init("ws-proxy-test", "", false, true)
+	log.Log.Logger.SetLevel(logrus.ErrorLevel)
+
+	proxy := NewWorkspaceProxy(":8080", config, HostBasedRouter(hostBasedHeader, wsHostSuffix, nil), &fakeWsInfoProvider{infos: workspaces})
+	handler, err := proxy.Handler()
+	if err != nil {
+		t.Fatalf("cannot create proxy handler: %q", err)
+	}
+	// the upstream speaks h2c only, as does our client
+	srv := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer srv.Close()
+
+	conn, err := grpc.Dial(srv.Listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpc_testing.NewTestServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, hostBasedHeader, strings.TrimSuffix(strings.TrimPrefix(workspaces[0].Ports[0].Url, "https://"), "/"))
+
+	t.Run("upstream unavailable", func(t *testing.T) {
+		_, err := client.EmptyCall(ctx, &grpc_testing.Empty{})
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("expected code %v, got %v", codes.Unavailable, err)
+		}
+	})
+
+	t.Run("bidirectional stream", func(t *testing.T) {
+		l, err := net.Listen("tcp", portServeHost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		upstream := grpc.NewServer()
+		grpc_testing.RegisterTestServiceServer(upstream, echoTestService{})
+		go upstream.Serve(l)
+		defer upstream.Stop()
+
+		stream, err := client.FullDuplexCall(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// every message must make it through before the next one is sent, i.e. nothing is buffered
+		for _, msg := range []string{"hello", "world"} {
+			err = stream.Send(&grpc_testing.StreamingOutputCallRequest{Payload: &grpc_testing.Payload{Body: []byte(msg)}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := stream.Recv()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act := string(resp.Payload.Body); act != msg {
+				t.Errorf("expected %s, got %s", msg, act)
+			}
+		}
+		err = stream.CloseSend()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Fatalf("expected end of stream, got %v", err)
+		}
+		if act := stream.Trailer().Get("echo-trailer"); len(act) != 1 || act[0] != "done" {
+			t.Errorf("upstream trailers did not pass through: %v", stream.Trailer())
+		}
+	})
+}
@@ -0,0 +1,402 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// Names of the routes in the route table
+const (
+	// RouteIDE is the IDE root which we serve from blobserve, falling back to the workspace
+	RouteIDE = "ide"
+	// RouteIDEDirect are the IDE routes we always serve from the workspace, e.g. /services
+	RouteIDEDirect = "ide-direct"
+	// RouteForeign is foreign content, e.g. webviews, served from the foreign origin
+	RouteForeign = "foreign"
+	// RouteSupervisor is the supervisor frontend and status
+	RouteSupervisor = "supervisor"
+	// RouteSupervisorAPI is the supervisor API available to the workspace owner only
+	RouteSupervisorAPI = "supervisor-api"
+	// RoutePort are the exposed workspace ports
+	RoutePort = "port"
+)
+
+// Upstream resolution strategies of routes
+const (
+	// UpstreamIDE resolves to the IDE port of the workspace pod
+	UpstreamIDE = "ide"
+	// UpstreamSupervisor resolves to the supervisor port of the workspace pod
+	UpstreamSupervisor = "supervisor"
+	// UpstreamPort resolves to the workspace port the request addresses
+	UpstreamPort = "port"
+)
+
+// defaultRouteTable is the route table we use for all routes operators do not configure
+var defaultRouteTable = RouteTable{
+	RouteIDE:           {Upstream: UpstreamIDE},
+	RouteIDEDirect:     {Upstream: UpstreamIDE},
+	RouteForeign:       {Upstream: UpstreamIDE},
+	RouteSupervisor:    {Upstream: UpstreamSupervisor},
+	RouteSupervisorAPI: {Upstream: UpstreamSupervisor},
+	RoutePort:          {Upstream: UpstreamPort},
+}
+
+// RouteTable configures the routes ws-proxy serves by their name, e.g. to tune the timeouts of the IDE route.
+// Routes which are not in the table behave as they always did.
+type RouteTable map[string]*RouteConfig
+
+// RouteConfig configures how we proxy the requests of a route
+type RouteConfig struct {
+	// Upstream is the resolution strategy of the route's upstream: ide, supervisor or port.
+	// For the IDE route, the upstream is what we fall back to if blobserve does not have a resource.
+	Upstream string `json:"upstream,omitempty"`
+	// ConnectTimeout is the time we allow for connecting to the upstream. Defaults to the transport's connect timeout.
+	ConnectTimeout util.Duration `json:"connectTimeout,omitempty"`
+	// ResponseTimeout is the time we allow the upstream to respond with headers, e.g. to bound long-polls. Defaults to no limit.
+	ResponseTimeout util.Duration `json:"responseTimeout,omitempty"`
+	// Retry retries idempotent requests which failed to reach the upstream
+	Retry *RouteRetryConfig `json:"retry,omitempty"`
+	// Websocket configures websocket connections of the route
+	Websocket *RouteWebsocketConfig `json:"websocket,omitempty"`
+	// Limits limits the size of requests and responses of the route
+	Limits *RouteLimitsConfig `json:"limits,omitempty"`
+}
+
+// RouteRetryConfig configures retries of requests which failed to reach the upstream
+type RouteRetryConfig struct {
+	// Attempts is the number of retries after the first attempt
+	Attempts int `json:"attempts"`
+	// Backoff is the time we wait between attempts
+	Backoff util.Duration `json:"backoff,omitempty"`
+}
+
+// RouteWebsocketConfig configures websocket connections of a route
+type RouteWebsocketConfig struct {
+	// Disabled rejects websocket upgrades
+	Disabled bool `json:"disabled,omitempty"`
+	// IdleTimeout closes websocket connections without traffic in either direction. Defaults to no limit.
+	IdleTimeout util.Duration `json:"idleTimeout,omitempty"`
+	// Throttle limits the message rate of websockets per workspace and bounds what we buffer for slow clients
+	Throttle *WebsocketThrottleConfig `json:"throttle,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (t RouteTable) Validate() error {
+	for name, rc := range t {
+		if _, known := defaultRouteTable[name]; !known {
+			return xerrors.Errorf("invalid route table: unknown route %s", name)
+		}
+		if rc == nil {
+			continue
+		}
+		err := validation.ValidateStruct(rc,
+			validation.Field(&rc.Upstream, validation.In(UpstreamIDE, UpstreamSupervisor, UpstreamPort)),
+			validation.Field(&rc.ConnectTimeout, validation.Min(util.Duration(0))),
+			validation.Field(&rc.ResponseTimeout, validation.Min(util.Duration(0))),
+			validation.Field(&rc.Retry),
+			validation.Field(&rc.Websocket),
+			validation.Field(&rc.Limits),
+		)
+		if err != nil {
+			return xerrors.Errorf("invalid route table: route %s: %w", name, err)
+		}
+		if rc.Websocket != nil && rc.Websocket.Throttle != nil && rc.Websocket.Throttle.Drop && t.Get(name).Upstream != UpstreamPort {
+			return xerrors.Errorf("invalid route table: route %s: only routes to workspace ports may drop websocket messages", name)
+		}
+	}
+	return nil
+}
+
+// ThrottlesWebsockets returns true if any route throttles its websockets
+func (t RouteTable) ThrottlesWebsockets() bool {
+	for _, rc := range t {
+		if rc != nil && rc.Websocket != nil && rc.Websocket.Throttle != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *RouteRetryConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Attempts, validation.Min(0)),
+		validation.Field(&c.Backoff, validation.Min(util.Duration(0))),
+	)
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *RouteWebsocketConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.IdleTimeout, validation.Min(util.Duration(0))),
+		validation.Field(&c.Throttle),
+	)
+}
+
+// Get returns the configuration of a route, with defaults for everything the table does not configure
+func (t RouteTable) Get(name string) RouteConfig {
+	var res RouteConfig
+	if rc := defaultRouteTable[name]; rc != nil {
+		res = *rc
+	}
+	if rc := t[name]; rc != nil {
+		upstream := res.Upstream
+		res = *rc
+		if res.Upstream == "" {
+			res.Upstream = upstream
+		}
+	}
+	return res
+}
+
+// Effective returns the configuration of all routes, with defaults for everything the table does not configure
+func (t RouteTable) Effective() RouteTable {
+	res := make(RouteTable, len(defaultRouteTable))
+	for name := range defaultRouteTable {
+		rc := t.Get(name)
+		res[name] = &rc
+	}
+	return res
+}
+
+// upstreamResolver produces the target resolver of an upstream resolution strategy
+func upstreamResolver(upstream string, ip WorkspaceInfoProvider) targetResolver {
+	switch upstream {
+	case UpstreamSupervisor:
+		return workspacePodSupervisorResolver
+	case UpstreamPort:
+		return dynamicWorkspacePortResolver(ip)
+	default:
+		return workspacePodResolver
+	}
+}
+
+// routePass proxies the requests of a route to the upstream the route table configures
+func routePass(config *RouteHandlerConfig, ip WorkspaceInfoProvider, route string, opts ...proxyPassOpt) http.HandlerFunc {
+	rc := config.Config.Routes.Get(route)
+	return config.WebsocketThrottler.Handler(route, rc, routeHandler(rc, proxyPass(config, upstreamResolver(rc.Upstream, ip), append([]proxyPassOpt{withRoute(rc), withUpstreamRetry(config)}, opts...)...)))
+}
+
+// routeHandler rejects websocket upgrades if the route does not permit them, and requests which exceed the route's limits
+func routeHandler(rc RouteConfig, h http.HandlerFunc) http.HandlerFunc {
+	h = limitRequestHandler(rc.Limits, h)
+	if rc.Websocket == nil || !rc.Websocket.Disabled {
+		return h
+	}
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if isWebsocketRequest(req) {
+			http.Error(resp, "websockets are disabled on this route", http.StatusBadRequest)
+			return
+		}
+		h(resp, req)
+	}
+}
+
+// withRoute applies the timeouts, retries, websocket settings and response limits of a route. It must be the first option
+// so that the transports other options install (e.g. blobserve) build on it.
+func withRoute(rc RouteConfig) proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		if rc.ConnectTimeout == 0 && rc.ResponseTimeout == 0 && rc.Retry == nil && rc.Websocket == nil && rc.Limits == nil {
+			return
+		}
+		cfg.Transport = &routeTransport{transport: cfg.Transport, Config: rc}
+	}
+}
+
+type connectTimeoutContextKey struct{}
+
+// getConnectTimeout returns the connect timeout of the route a request belongs to
+func getConnectTimeout(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(connectTimeoutContextKey{}).(time.Duration)
+	return d, ok
+}
+
+// routeTransport applies the configuration of a route to the requests we send upstream
+type routeTransport struct {
+	transport http.RoundTripper
+	Config    RouteConfig
+}
+
+func (t *routeTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	ws := isWebsocketRequest(req)
+	if t.Config.ConnectTimeout > 0 {
+		// the transport pool shares connections between routes, hence the dialer learns about the timeout through the context
+		req = req.WithContext(context.WithValue(req.Context(), connectTimeoutContextKey{}, time.Duration(t.Config.ConnectTimeout)))
+	}
+
+	var retries int
+	if t.Config.Retry != nil && !ws && isIdempotentRequest(req) {
+		retries = t.Config.Retry.Attempts
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err = t.roundTrip(req)
+		if err == nil || attempt >= retries || req.Context().Err() != nil {
+			break
+		}
+
+		getLog(req.Context()).WithError(err).WithField("attempt", attempt+1).Debug("upstream request failed - retrying")
+		select {
+		case <-time.After(time.Duration(t.Config.Retry.Backoff)):
+		case <-req.Context().Done():
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = limitResponse(t.Config.Limits, resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusSwitchingProtocols && t.Config.Websocket != nil && t.Config.Websocket.IdleTimeout > 0 {
+		if conn, ok := resp.Body.(io.ReadWriteCloser); ok {
+			resp.Body = newIdleTimeoutConn(conn, time.Duration(t.Config.Websocket.IdleTimeout))
+		}
+	}
+	return resp, nil
+}
+
+// roundTrip forwards a request and fails if the upstream does not respond within the response timeout.
+// Unlike a context deadline the response timeout does not limit how long we stream the response body.
+func (t *routeTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	timeout := time.Duration(t.Config.ResponseTimeout)
+	if timeout == 0 {
+		return t.transport.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(timeout, cancel)
+	resp, err := t.transport.RoundTrip(req.WithContext(ctx))
+	if !timer.Stop() {
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, xerrors.Errorf("upstream did not respond within %s: %w", timeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if conn, ok := resp.Body.(io.ReadWriteCloser); ok {
+		resp.Body = &cancelOnCloseConn{ReadWriteCloser: conn, cancel: cancel}
+	} else {
+		resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	}
+	return resp, nil
+}
+
+// isIdempotentRequest returns true if we can safely send a request again
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// cancelOnCloseConn retains the writer of upgraded connections, which the reverse proxy requires
+type cancelOnCloseConn struct {
+	io.ReadWriteCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnCloseConn) Close() error {
+	err := c.ReadWriteCloser.Close()
+	c.cancel()
+	return err
+}
+
+// idleTimeoutConn closes an upgraded connection once there was no traffic in either direction for the idle timeout
+type idleTimeoutConn struct {
+	io.ReadWriteCloser
+
+	timeout      time.Duration
+	lastActivity int64
+
+	mu     sync.Mutex
+	timer  *time.Timer
+	closed bool
+}
+
+func newIdleTimeoutConn(conn io.ReadWriteCloser, timeout time.Duration) *idleTimeoutConn {
+	c := &idleTimeoutConn{
+		ReadWriteCloser: conn,
+		timeout:         timeout,
+		lastActivity:    time.Now().UnixNano(),
+	}
+	c.mu.Lock()
+	c.timer = time.AfterFunc(timeout, c.checkIdle)
+	c.mu.Unlock()
+	return c
+}
+
+func (c *idleTimeoutConn) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	atomic.StoreInt64(&c.lastActivity, time.Now().UnixNano())
+	return n, err
+}
+
+func (c *idleTimeoutConn) Write(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Write(p)
+	atomic.StoreInt64(&c.lastActivity, time.Now().UnixNano())
+	return n, err
+}
+
+func (c *idleTimeoutConn) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.timer.Stop()
+	c.mu.Unlock()
+	return c.ReadWriteCloser.Close()
+}
+
+func (c *idleTimeoutConn) checkIdle() {
+	idle := time.Since(time.Unix(0, atomic.LoadInt64(&c.lastActivity)))
+	if idle >= c.timeout {
+		c.Close()
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.timer = time.AfterFunc(c.timeout-idle, c.checkIdle)
+}
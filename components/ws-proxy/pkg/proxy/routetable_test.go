@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestRouteTable(t *testing.T) {
+	table := RouteTable{
+		RouteIDE:  {ResponseTimeout: util.Duration(time.Minute)},
+		RoutePort: {Upstream: UpstreamIDE},
+	}
+	if err := table.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Route       string
+		Expectation RouteConfig
+	}{
+		{Route: RouteIDE, Expectation: RouteConfig{Upstream: UpstreamIDE, ResponseTimeout: util.Duration(time.Minute)}},
+		{Route: RoutePort, Expectation: RouteConfig{Upstream: UpstreamIDE}},
+		{Route: RouteSupervisorAPI, Expectation: RouteConfig{Upstream: UpstreamSupervisor}},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(test.Expectation, table.Get(test.Route)); diff != "" {
+			t.Errorf("unexpected config of route %s (-want +got):\n%s", test.Route, diff)
+		}
+	}
+
+	invalid := []RouteTable{
+		{"unknown": {}},
+		{RouteIDE: {Upstream: "somewhere"}},
+		{RouteIDE: {Retry: &RouteRetryConfig{Attempts: -1}}},
+	}
+	for _, table := range invalid {
+		if err := table.Validate(); err == nil {
+			t.Errorf("expected route table %v to be invalid", table)
+		}
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRouteTransportRetry(t *testing.T) {
+	tests := []struct {
+		Name             string
+		Method           string
+		ExpectedAttempts int
+		ExpectedError    bool
+	}{
+		{Name: "idempotent request", Method: "GET", ExpectedAttempts: 3},
+		{Name: "non-idempotent request", Method: "POST", ExpectedAttempts: 1, ExpectedError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var attempts int
+			rt := &routeTransport{
+				transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					attempts++
+					if attempts < 3 {
+						return nil, &net.OpError{Op: "dial", Err: xerrors.Errorf("connection refused")}
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+				}),
+				Config: RouteConfig{Retry: &RouteRetryConfig{Attempts: 2, Backoff: util.Duration(time.Millisecond)}},
+			}
+
+			_, err := rt.RoundTrip(httptest.NewRequest(test.Method, "http://localhost/", nil))
+			if (err != nil) != test.ExpectedError {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if attempts != test.ExpectedAttempts {
+				t.Errorf("unexpected number of attempts: %d, expected %d", attempts, test.ExpectedAttempts)
+			}
+		})
+	}
+}
+
+func TestRouteTransportResponseTimeout(t *testing.T) {
+	rt := &routeTransport{
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/slow" {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("fast"))}, nil
+		}),
+		Config: RouteConfig{ResponseTimeout: util.Duration(50 * time.Millisecond)},
+	}
+
+	_, err := rt.RoundTrip(httptest.NewRequest("GET", "http://localhost/slow", nil))
+	if !isTimeoutError(err) {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+
+	resp, err := rt.RoundTrip(httptest.NewRequest("GET", "http://localhost/fast", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the response timeout must not limit how long we read the body
+	time.Sleep(100 * time.Millisecond)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "fast" {
+		t.Errorf("unexpected body: %q, %v", body, err)
+	}
+}
+
+func TestIdleTimeoutConn(t *testing.T) {
+	client, upstream := net.Pipe()
+	defer client.Close()
+
+	conn := newIdleTimeoutConn(upstream, 100*time.Millisecond)
+	go func() {
+		// traffic keeps the connection alive
+		for i := 0; i < 3; i++ {
+			time.Sleep(50 * time.Millisecond)
+			_, _ = client.Write([]byte("ping"))
+		}
+	}()
+
+	buf := make([]byte, 4)
+	start := time.Now()
+	for {
+		_, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("connection was closed despite traffic after %s", elapsed)
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// hstsPreloadMinMaxAge is the shortest max-age the HSTS preload list accepts
+const hstsPreloadMinMaxAge = 365 * 24 * time.Hour
+
+// portHostRegexp extracts the workspace port from the first label of a port host, e.g. 3000-amaranth-smelt-9ba20cc1.ws.gitpod.io
+var portHostRegexp = regexp.MustCompile("^(?:webview-|browser-|extensions-)?([0-9]+)-")
+
+// SchemeRedirectConfig redirects plain HTTP requests to HTTPS and tells browsers to stick with HTTPS (HSTS).
+// Users who mix schemes otherwise end up with cookies the IDE cannot see and fail to authenticate.
+type SchemeRedirectConfig struct {
+	// Domains configure the behaviour per domain. A domain applies to its host and all hosts below it;
+	// if several domains apply, the longest wins. Requests to other hosts are left alone.
+	Domains []SchemeRedirectDomain `json:"domains"`
+	// ExcludedPorts are workspace ports which must stay reachable via plain HTTP, e.g. for local tooling.
+	// We neither redirect requests to them nor send them HSTS headers.
+	ExcludedPorts []uint16 `json:"excludedPorts,omitempty"`
+	// TrustForwardedProto determines the scheme from the X-Forwarded-Proto header of requests which arrive
+	// without TLS. If clientIP is configured, we honor the header from its trusted proxies only.
+	TrustForwardedProto bool `json:"trustForwardedProto,omitempty"`
+}
+
+// SchemeRedirectDomain configures the scheme redirect of a domain
+type SchemeRedirectDomain struct {
+	Domain string `json:"domain"`
+	// Redirect redirects plain HTTP requests to HTTPS
+	Redirect bool `json:"redirect,omitempty"`
+	// HSTS, if set, adds a Strict-Transport-Security header to HTTPS responses
+	HSTS *HSTSConfig `json:"hsts,omitempty"`
+}
+
+// HSTSConfig configures the Strict-Transport-Security header
+type HSTSConfig struct {
+	MaxAge            util.Duration `json:"maxAge"`
+	IncludeSubDomains bool          `json:"includeSubDomains,omitempty"`
+	// Preload asks browsers to put the domain on their HSTS preload list, which requires a max age of at least
+	// a year, includeSubDomains and redirects to HTTPS. Getting off the list again takes months.
+	Preload bool `json:"preload,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *SchemeRedirectConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if len(c.Domains) == 0 {
+		return xerrors.Errorf("invalid scheme redirect config: domains are required")
+	}
+	seen := make(map[string]struct{}, len(c.Domains))
+	for _, d := range c.Domains {
+		err := d.validate()
+		if err != nil {
+			return xerrors.Errorf("invalid scheme redirect config: %w", err)
+		}
+		domain := normalizeDomain(d.Domain)
+		if _, exists := seen[domain]; exists {
+			return xerrors.Errorf("invalid scheme redirect config: domain %s is configured twice", d.Domain)
+		}
+		seen[domain] = struct{}{}
+	}
+	for _, p := range c.ExcludedPorts {
+		if p == 0 {
+			return xerrors.Errorf("invalid scheme redirect config: excluded ports must not be 0")
+		}
+	}
+	return nil
+}
+
+func (d *SchemeRedirectDomain) validate() error {
+	if normalizeDomain(d.Domain) == "" {
+		return xerrors.Errorf("domain is required")
+	}
+	if !d.Redirect && d.HSTS == nil {
+		return xerrors.Errorf("domain %s neither redirects nor sends HSTS headers", d.Domain)
+	}
+	if d.HSTS == nil {
+		return nil
+	}
+	if d.HSTS.MaxAge <= 0 {
+		return xerrors.Errorf("domain %s: HSTS max age must be positive", d.Domain)
+	}
+	if d.HSTS.Preload {
+		if time.Duration(d.HSTS.MaxAge) < hstsPreloadMinMaxAge {
+			return xerrors.Errorf("domain %s: HSTS preload requires a max age of at least %s", d.Domain, hstsPreloadMinMaxAge)
+		}
+		if !d.HSTS.IncludeSubDomains {
+			return xerrors.Errorf("domain %s: HSTS preload requires includeSubDomains", d.Domain)
+		}
+		if !d.Redirect {
+			return xerrors.Errorf("domain %s: HSTS preload requires redirects to HTTPS", d.Domain)
+		}
+	}
+	return nil
+}
+
+// headerValue produces the value of the Strict-Transport-Security header
+func (c *HSTSConfig) headerValue() string {
+	res := fmt.Sprintf("max-age=%d", int64(time.Duration(c.MaxAge).Seconds()))
+	if c.IncludeSubDomains {
+		res += "; includeSubDomains"
+	}
+	if c.Preload {
+		res += "; preload"
+	}
+	return res
+}
+
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.Trim(domain, "."))
+}
+
+// SchemeRedirector redirects plain HTTP requests to HTTPS and adds HSTS headers to HTTPS responses, before we route them
+type SchemeRedirector struct {
+	Config SchemeRedirectConfig
+
+	// hostHeader is the header which carries the host of requests that arrive through an ingress. If empty, we use the Host header.
+	hostHeader string
+	trusted    trustedNets
+	// trustAll honors X-Forwarded-Proto from everyone because clientIP does not tell us whom to trust
+	trustAll bool
+	excluded map[uint16]struct{}
+
+	requests *prometheus.CounterVec
+}
+
+// NewSchemeRedirector creates a new scheme redirector
+func NewSchemeRedirector(cfg SchemeRedirectConfig, clientIP *ClientIPConfig, hostHeader string) (*SchemeRedirector, error) {
+	res := &SchemeRedirector{
+		Config:     cfg,
+		hostHeader: hostHeader,
+		excluded:   make(map[uint16]struct{}, len(cfg.ExcludedPorts)),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "scheme_redirect_requests_total",
+			Help: "Requests the scheme redirect handled, by domain, scheme and what we did: redirect, hsts, excluded (port) or none",
+		}, []string{"domain", "scheme", "action"}),
+	}
+	if cfg.TrustForwardedProto {
+		if clientIP == nil {
+			res.trustAll = true
+		} else {
+			trusted, err := parseTrustedNets(clientIP.TrustedProxies)
+			if err != nil {
+				return nil, err
+			}
+			res.trusted = trusted
+		}
+	}
+	for _, p := range cfg.ExcludedPorts {
+		res.excluded[p] = struct{}{}
+	}
+	return res, nil
+}
+
+// RegisterMetrics registers the scheme redirect metrics
+func (s *SchemeRedirector) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(s.requests)
+}
+
+// Handler redirects and adds HSTS headers. If the redirector is nil, the handler does nothing.
+func (s *SchemeRedirector) Handler(h http.Handler) http.Handler {
+	if s == nil {
+		return h
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		host := req.Host
+		if s.hostHeader != "" {
+			if hdr := req.Header.Get(s.hostHeader); hdr != "" {
+				host = hdr
+			}
+		}
+		hostname, port := splitHost(host)
+		scheme := s.scheme(req)
+
+		domain := s.domain(hostname)
+		if domain == nil {
+			h.ServeHTTP(resp, req)
+			return
+		}
+		label := normalizeDomain(domain.Domain)
+		if s.isExcluded(hostname, port) {
+			s.requests.WithLabelValues(label, scheme, "excluded").Inc()
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		switch {
+		case scheme == "http" && domain.Redirect:
+			s.requests.WithLabelValues(label, scheme, "redirect").Inc()
+			target := "https://" + hostname + req.URL.RequestURI()
+			http.Redirect(resp, req, target, http.StatusPermanentRedirect)
+			return
+		case scheme == "https" && domain.HSTS != nil:
+			s.requests.WithLabelValues(label, scheme, "hsts").Inc()
+			resp.Header().Set("Strict-Transport-Security", domain.HSTS.headerValue())
+		default:
+			s.requests.WithLabelValues(label, scheme, "none").Inc()
+		}
+		h.ServeHTTP(resp, req)
+	})
+}
+
+// scheme determines the scheme the client used to reach us or the load balancer in front of us
+func (s *SchemeRedirector) scheme(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	if s.trustAll || s.isTrustedPeer(req) {
+		switch strings.ToLower(strings.TrimSpace(strings.Split(req.Header.Get("X-Forwarded-Proto"), ",")[0])) {
+		case "https":
+			return "https"
+		case "http":
+			return "http"
+		}
+		// a trusted proxy which does not tell us the scheme has not terminated TLS for us either
+	}
+	return "http"
+}
+
+func (s *SchemeRedirector) isTrustedPeer(req *http.Request) bool {
+	if len(s.trusted) == 0 {
+		return false
+	}
+	peer, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	return s.trusted.Contains(net.ParseIP(peer))
+}
+
+// domain finds the most specific domain the host belongs to
+func (s *SchemeRedirector) domain(hostname string) *SchemeRedirectDomain {
+	var (
+		res    *SchemeRedirectDomain
+		resLen int
+	)
+	for i, d := range s.Config.Domains {
+		domain := normalizeDomain(d.Domain)
+		if hostname != domain && !strings.HasSuffix(hostname, "."+domain) {
+			continue
+		}
+		if len(domain) > resLen {
+			res, resLen = &s.Config.Domains[i], len(domain)
+		}
+	}
+	return res
+}
+
+// isExcluded returns true if the request goes to an excluded workspace port, either by port host or by an explicit port in the host
+func (s *SchemeRedirector) isExcluded(hostname string, port int) bool {
+	if len(s.excluded) == 0 {
+		return false
+	}
+	if port > 0 && port <= 65535 {
+		if _, ok := s.excluded[uint16(port)]; ok {
+			return true
+		}
+	}
+	m := portHostRegexp.FindStringSubmatch(hostname)
+	if m == nil {
+		return false
+	}
+	p, err := strconv.ParseUint(m[1], 10, 16)
+	if err != nil {
+		return false
+	}
+	_, ok := s.excluded[uint16(p)]
+	return ok
+}
+
+// splitHost splits a host into its lower-case hostname and port. Port is 0 if the host has none.
+func splitHost(host string) (hostname string, port int) {
+	hostname = host
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+		port, _ = strconv.Atoi(p)
+	}
+	return strings.ToLower(strings.TrimSuffix(hostname, ".")), port
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestSchemeRedirector(t *testing.T) {
+	cfg := SchemeRedirectConfig{
+		Domains: []SchemeRedirectDomain{
+			{
+				Domain:   "gitpod.io",
+				Redirect: true,
+				HSTS:     &HSTSConfig{MaxAge: util.Duration(365 * 24 * time.Hour), IncludeSubDomains: true, Preload: true},
+			},
+			{
+				// the workspace domain redirects, but does not pin browsers to HTTPS
+				Domain:   "ws.gitpod.io",
+				Redirect: true,
+			},
+		},
+		ExcludedPorts:       []uint16{3000},
+		TrustForwardedProto: true,
+	}
+
+	tests := []struct {
+		Name       string
+		URL        string
+		RemoteAddr string
+		TLS        bool
+		Header     http.Header
+		ClientIP   *ClientIPConfig
+
+		Status   int
+		Location string
+		HSTS     string
+		Action   string
+	}{
+		{
+			Name:     "plain HTTP is redirected",
+			URL:      "http://gitpod.io/workspaces?a=b",
+			Status:   http.StatusPermanentRedirect,
+			Location: "https://gitpod.io/workspaces?a=b",
+			Action:   "redirect",
+		},
+		{
+			Name:   "HTTPS gets HSTS",
+			URL:    "https://gitpod.io/",
+			TLS:    true,
+			Status: http.StatusOK,
+			HSTS:   "max-age=31536000; includeSubDomains; preload",
+			Action: "hsts",
+		},
+		{
+			Name:   "most specific domain wins",
+			URL:    "https://amaranth-smelt-9ba20cc1.ws.gitpod.io/",
+			TLS:    true,
+			Status: http.StatusOK,
+			Action: "none",
+		},
+		{
+			Name:     "redirect drops the port",
+			URL:      "http://amaranth-smelt-9ba20cc1.ws.gitpod.io:80/",
+			Status:   http.StatusPermanentRedirect,
+			Location: "https://amaranth-smelt-9ba20cc1.ws.gitpod.io/",
+			Action:   "redirect",
+		},
+		{
+			Name:   "excluded port host",
+			URL:    "http://3000-amaranth-smelt-9ba20cc1.ws.gitpod.io/",
+			Status: http.StatusOK,
+			Action: "excluded",
+		},
+		{
+			Name:   "excluded webview port host",
+			URL:    "http://webview-3000-amaranth-smelt-9ba20cc1.ws.gitpod.io/",
+			Status: http.StatusOK,
+			Action: "excluded",
+		},
+		{
+			Name:     "other port host",
+			URL:      "http://8080-amaranth-smelt-9ba20cc1.ws.gitpod.io/",
+			Status:   http.StatusPermanentRedirect,
+			Location: "https://8080-amaranth-smelt-9ba20cc1.ws.gitpod.io/",
+			Action:   "redirect",
+		},
+		{
+			Name:   "excluded explicit port",
+			URL:    "http://gitpod.io:3000/",
+			Status: http.StatusOK,
+			Action: "excluded",
+		},
+		{
+			Name:   "other domain",
+			URL:    "http://notgitpod.io/",
+			Status: http.StatusOK,
+		},
+		{
+			Name:     "host header of the ingress",
+			URL:      "http://ws-proxy:8080/",
+			Header:   http.Header{"X-Wsproxy-Host": {"Gitpod.io"}},
+			Status:   http.StatusPermanentRedirect,
+			Location: "https://gitpod.io/",
+			Action:   "redirect",
+		},
+		{
+			Name:   "TLS terminated by load balancer",
+			URL:    "http://gitpod.io/",
+			Header: http.Header{"X-Forwarded-Proto": {"https"}},
+			Status: http.StatusOK,
+			HSTS:   "max-age=31536000; includeSubDomains; preload",
+			Action: "hsts",
+		},
+		{
+			Name:       "X-Forwarded-Proto from trusted proxy",
+			URL:        "http://gitpod.io/",
+			RemoteAddr: "10.0.0.1:4242",
+			Header:     http.Header{"X-Forwarded-Proto": {"https"}},
+			ClientIP:   &ClientIPConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+			Status:     http.StatusOK,
+			HSTS:       "max-age=31536000; includeSubDomains; preload",
+			Action:     "hsts",
+		},
+		{
+			Name:       "X-Forwarded-Proto from untrusted peer",
+			URL:        "http://gitpod.io/",
+			RemoteAddr: "203.0.113.7:4242",
+			Header:     http.Header{"X-Forwarded-Proto": {"https"}},
+			ClientIP:   &ClientIPConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+			Status:     http.StatusPermanentRedirect,
+			Location:   "https://gitpod.io/",
+			Action:     "redirect",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			s, err := NewSchemeRedirector(cfg, test.ClientIP, forwardedHostnameHeader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var served bool
+			h := s.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				served = true
+			}))
+
+			req := httptest.NewRequest("GET", test.URL, nil)
+			for k, v := range test.Header {
+				req.Header[k] = v
+			}
+			if test.RemoteAddr != "" {
+				req.RemoteAddr = test.RemoteAddr
+			}
+			if test.TLS {
+				req.TLS = &tls.ConnectionState{}
+			} else {
+				req.TLS = nil
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("unexpected status: want %d, got %d", test.Status, rec.Code)
+			}
+			if served == (test.Status == http.StatusPermanentRedirect) {
+				t.Errorf("request served: %v", served)
+			}
+			if loc := rec.Header().Get("Location"); loc != test.Location {
+				t.Errorf("unexpected location: want %q, got %q", test.Location, loc)
+			}
+			if hsts := rec.Header().Get("Strict-Transport-Security"); hsts != test.HSTS {
+				t.Errorf("unexpected HSTS header: want %q, got %q", test.HSTS, hsts)
+			}
+			var counted string
+			for _, domain := range []string{"gitpod.io", "ws.gitpod.io"} {
+				for _, scheme := range []string{"http", "https"} {
+					for _, action := range []string{"redirect", "hsts", "excluded", "none"} {
+						if testutil.ToFloat64(s.requests.WithLabelValues(domain, scheme, action)) > 0 {
+							counted += action
+						}
+					}
+				}
+			}
+			if counted != test.Action {
+				t.Errorf("unexpected metrics: want %q, got %q", test.Action, counted)
+			}
+		})
+	}
+}
+
+func TestSchemeRedirectConfigValidate(t *testing.T) {
+	year := util.Duration(365 * 24 * time.Hour)
+	tests := []struct {
+		Name   string
+		Config SchemeRedirectConfig
+		Valid  bool
+	}{
+		{Name: "valid", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io", Redirect: true}}}, Valid: true},
+		{Name: "no domains", Config: SchemeRedirectConfig{}},
+		{Name: "domain does nothing", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io"}}}},
+		{Name: "duplicate domain", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io", Redirect: true}, {Domain: "Gitpod.io.", Redirect: true}}}},
+		{Name: "preload with short max age", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io", Redirect: true, HSTS: &HSTSConfig{MaxAge: util.Duration(time.Hour), IncludeSubDomains: true, Preload: true}}}}},
+		{Name: "preload without subdomains", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io", Redirect: true, HSTS: &HSTSConfig{MaxAge: year, Preload: true}}}}},
+		{Name: "preload without redirect", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io", HSTS: &HSTSConfig{MaxAge: year, IncludeSubDomains: true, Preload: true}}}}},
+		{Name: "HSTS without max age", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io", HSTS: &HSTSConfig{}}}}},
+		{Name: "excluded port 0", Config: SchemeRedirectConfig{Domains: []SchemeRedirectDomain{{Domain: "gitpod.io", Redirect: true}}, ExcludedPorts: []uint16{0}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err == nil) != test.Valid {
+				t.Errorf("unexpected validation result: %v", err)
+			}
+		})
+	}
+}
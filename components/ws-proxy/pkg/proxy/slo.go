@@ -0,0 +1,392 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	// SLORouteClassIDE are requests to the IDE and supervisor of a workspace
+	SLORouteClassIDE = "ide"
+	// SLORouteClassPort are requests to exposed workspace ports
+	SLORouteClassPort = "port"
+	// SLORouteClassBlobserve are requests for IDE assets served by blobserve
+	SLORouteClassBlobserve = "blobserve"
+
+	sloAvailability = "availability"
+	sloLatency      = "latency"
+
+	// sloBucketWidth is the resolution at which we track requests. Windows are rounded down to this resolution.
+	sloBucketWidth = 10 * time.Second
+)
+
+// defaultSLOAlerts are the multi-window burn-rate alerts recommended by the Google SRE workbook:
+// page if we'd burn 2% of a 30 day error budget within an hour, open a ticket if we'd burn 5% within six hours.
+var defaultSLOAlerts = []SLOAlert{
+	{Name: "page", LongWindow: util.Duration(time.Hour), ShortWindow: util.Duration(5 * time.Minute), BurnRate: 14.4},
+	{Name: "ticket", LongWindow: util.Duration(6 * time.Hour), ShortWindow: util.Duration(30 * time.Minute), BurnRate: 6},
+}
+
+// SLOConfig configures the service level objectives ws-proxy tracks
+type SLOConfig struct {
+	// Objectives are keyed by route class, i.e. ide, port or blobserve
+	Objectives map[string]SLOObjective `json:"objectives"`
+	// Alerts are multi-window burn-rate alerts. Defaults to a "page" (1h/5m, 14.4) and "ticket" (6h/30m, 6) alert.
+	Alerts []SLOAlert `json:"alerts,omitempty"`
+}
+
+// SLOObjective is the objective of a route class
+type SLOObjective struct {
+	// Availability is the fraction of requests which must not fail with a 5xx status, e.g. 0.999
+	Availability float64 `json:"availability,omitempty"`
+	// Latency is the time to the response header within which a request counts as fast
+	Latency util.Duration `json:"latency,omitempty"`
+	// LatencyTarget is the fraction of requests which must be fast, e.g. 0.99
+	LatencyTarget float64 `json:"latencyTarget,omitempty"`
+}
+
+// SLOAlert fires if the error budget burn rate exceeds the threshold in both windows. The short window
+// makes sure the alert stops firing soon after the problem went away.
+type SLOAlert struct {
+	Name        string        `json:"name"`
+	LongWindow  util.Duration `json:"longWindow"`
+	ShortWindow util.Duration `json:"shortWindow"`
+	BurnRate    float64       `json:"burnRate"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *SLOConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if len(c.Objectives) == 0 {
+		return xerrors.Errorf("invalid SLO config: objectives are required")
+	}
+	for class, o := range c.Objectives {
+		err := validation.Validate(class, validation.In(SLORouteClassIDE, SLORouteClassPort, SLORouteClassBlobserve))
+		if err != nil {
+			return xerrors.Errorf("invalid SLO config: route class %s: %w", class, err)
+		}
+		err = validation.ValidateStruct(&o,
+			validation.Field(&o.Availability, validation.Min(0.0), validation.Max(1.0)),
+			validation.Field(&o.Latency, validation.Min(util.Duration(0))),
+			validation.Field(&o.LatencyTarget, validation.Min(0.0), validation.Max(1.0)),
+		)
+		if err != nil {
+			return xerrors.Errorf("invalid SLO config: route class %s: %w", class, err)
+		}
+		if o.Availability == 0 && o.LatencyTarget == 0 {
+			return xerrors.Errorf("invalid SLO config: route class %s: either availability or latencyTarget is required", class)
+		}
+		if (o.Latency == 0) != (o.LatencyTarget == 0) {
+			return xerrors.Errorf("invalid SLO config: route class %s: latency and latencyTarget go together", class)
+		}
+		if o.Availability == 1 || o.LatencyTarget == 1 {
+			return xerrors.Errorf("invalid SLO config: route class %s: an objective of 100%% leaves no error budget", class)
+		}
+	}
+
+	names := make(map[string]struct{}, len(c.Alerts))
+	for _, a := range c.Alerts {
+		err := validation.ValidateStruct(&a,
+			validation.Field(&a.Name, validation.Required),
+			validation.Field(&a.ShortWindow, validation.Required, validation.Min(util.Duration(sloBucketWidth))),
+			validation.Field(&a.LongWindow, validation.Required, validation.Min(a.ShortWindow)),
+			validation.Field(&a.BurnRate, validation.Required, validation.Min(0.0)),
+		)
+		if err != nil {
+			return xerrors.Errorf("invalid SLO config: alert %s: %w", a.Name, err)
+		}
+		if _, exists := names[a.Name]; exists {
+			return xerrors.Errorf("invalid SLO config: alert %s exists more than once", a.Name)
+		}
+		names[a.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (c *SLOConfig) alerts() []SLOAlert {
+	if len(c.Alerts) == 0 {
+		return defaultSLOAlerts
+	}
+	return c.Alerts
+}
+
+// SLOTracker computes the error budget burn rate of the configured objectives from the requests ws-proxy serves.
+// Doing this in ws-proxy rather than from histograms in Prometheus gets us exact latency thresholds.
+type SLOTracker struct {
+	Config SLOConfig
+
+	mu      sync.Mutex
+	slos    []*sloCounter
+	windows []time.Duration
+	now     func() time.Time
+
+	burnRateDesc *prometheus.Desc
+	alertingDesc *prometheus.Desc
+}
+
+// NewSLOTracker creates a new SLO tracker
+func NewSLOTracker(config SLOConfig) *SLOTracker {
+	windows := make(map[time.Duration]struct{})
+	for _, a := range config.alerts() {
+		windows[time.Duration(a.LongWindow)] = struct{}{}
+		windows[time.Duration(a.ShortWindow)] = struct{}{}
+	}
+	res := &SLOTracker{
+		Config: config,
+		now:    time.Now,
+		burnRateDesc: prometheus.NewDesc("slo_burn_rate",
+			"rate at which the error budget of an objective is burnt - 1 means the budget lasts exactly the SLO period",
+			[]string{"class", "slo", "window"}, nil),
+		alertingDesc: prometheus.NewDesc("slo_alerting",
+			"1 if the burn rate of an objective exceeds the alert threshold in both alert windows, 0 otherwise",
+			[]string{"class", "slo", "alert"}, nil),
+	}
+	for w := range windows {
+		res.windows = append(res.windows, w)
+	}
+	sort.Slice(res.windows, func(i, j int) bool { return res.windows[i] < res.windows[j] })
+
+	var (
+		longest = res.windows[len(res.windows)-1]
+		classes = make([]string, 0, len(config.Objectives))
+	)
+	for class := range config.Objectives {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		o := config.Objectives[class]
+		if o.Availability > 0 {
+			res.slos = append(res.slos, newSLOCounter(class, sloAvailability, o.Availability, longest))
+		}
+		if o.LatencyTarget > 0 {
+			res.slos = append(res.slos, newSLOCounter(class, sloLatency, o.LatencyTarget, longest))
+		}
+	}
+	return res
+}
+
+// Handler records the requests of a route class. If the tracker is nil, the handler does nothing.
+func (t *SLOTracker) Handler(class string) mux.MiddlewareFunc {
+	if t == nil {
+		return func(h http.Handler) http.Handler { return h }
+	}
+	objective, ok := t.Config.Objectives[class]
+	if !ok {
+		return func(h http.Handler) http.Handler { return h }
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			rec := &sloResponseWriter{
+				statusRecordingResponseWriter: statusRecordingResponseWriter{ResponseWriter: resp, status: http.StatusOK},
+				start:                         time.Now(),
+			}
+			h.ServeHTTP(rec, req)
+
+			now := t.now()
+			if objective.Availability > 0 {
+				t.record(class, sloAvailability, now, rec.status < http.StatusInternalServerError)
+			}
+			// there's no meaningful latency of connections we've handed over, e.g. websockets
+			if objective.LatencyTarget > 0 && rec.status != http.StatusSwitchingProtocols {
+				if rec.firstByte.IsZero() {
+					rec.firstByte = time.Now()
+				}
+				t.record(class, sloLatency, now, rec.firstByte.Sub(rec.start) <= time.Duration(objective.Latency))
+			}
+		})
+	}
+}
+
+func (t *SLOTracker) record(class, slo string, now time.Time, good bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, c := range t.slos {
+		if c.Class == class && c.SLO == slo {
+			c.Record(now, good)
+			return
+		}
+	}
+}
+
+// SLOStatus is the state of a single objective
+type SLOStatus struct {
+	Class     string  `json:"class"`
+	SLO       string  `json:"slo"`
+	Objective float64 `json:"objective"`
+	// BurnRates are keyed by window, e.g. 5m0s
+	BurnRates map[string]float64 `json:"burnRates"`
+	// Alerting lists the alerts which currently fire
+	Alerting []string `json:"alerting"`
+}
+
+// Status computes the burn rates and alerts of all objectives
+func (t *SLOTracker) Status() []SLOStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	res := make([]SLOStatus, 0, len(t.slos))
+	for _, c := range t.slos {
+		status := SLOStatus{
+			Class:     c.Class,
+			SLO:       c.SLO,
+			Objective: c.Objective,
+			BurnRates: make(map[string]float64, len(t.windows)),
+			Alerting:  []string{},
+		}
+		for _, w := range t.windows {
+			status.BurnRates[w.String()] = c.BurnRate(now, w)
+		}
+		for _, a := range t.Config.alerts() {
+			if status.BurnRates[time.Duration(a.LongWindow).String()] > a.BurnRate && status.BurnRates[time.Duration(a.ShortWindow).String()] > a.BurnRate {
+				status.Alerting = append(status.Alerting, a.Name)
+			}
+		}
+		res = append(res, status)
+	}
+	return res
+}
+
+// StatusHandler serves the SLO status as JSON
+func (t *SLOTracker) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(t.Status())
+		if err != nil {
+			log.WithError(err).Warn("cannot serve SLO status")
+		}
+	})
+}
+
+// RegisterMetrics registers the burn rate metrics
+func (t *SLOTracker) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(t)
+}
+
+// Describe implements prometheus.Collector
+func (t *SLOTracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.burnRateDesc
+	ch <- t.alertingDesc
+}
+
+// Collect implements prometheus.Collector
+func (t *SLOTracker) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range t.Status() {
+		for window, rate := range s.BurnRates {
+			ch <- prometheus.MustNewConstMetric(t.burnRateDesc, prometheus.GaugeValue, rate, s.Class, s.SLO, window)
+		}
+
+		alerting := make(map[string]struct{}, len(s.Alerting))
+		for _, a := range s.Alerting {
+			alerting[a] = struct{}{}
+		}
+		for _, a := range t.Config.alerts() {
+			var v float64
+			if _, ok := alerting[a.Name]; ok {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(t.alertingDesc, prometheus.GaugeValue, v, s.Class, s.SLO, a.Name)
+		}
+	}
+}
+
+// sloCounter counts good and total requests of an objective in a ring of time buckets
+type sloCounter struct {
+	Class     string
+	SLO       string
+	Objective float64
+
+	buckets []sloBucket
+}
+
+type sloBucket struct {
+	epoch int64
+	good  uint64
+	total uint64
+}
+
+func newSLOCounter(class, slo string, objective float64, longestWindow time.Duration) *sloCounter {
+	return &sloCounter{
+		Class:     class,
+		SLO:       slo,
+		Objective: objective,
+		buckets:   make([]sloBucket, longestWindow/sloBucketWidth+1),
+	}
+}
+
+func (c *sloCounter) Record(now time.Time, good bool) {
+	epoch := now.UnixNano() / int64(sloBucketWidth)
+	b := &c.buckets[epoch%int64(len(c.buckets))]
+	if b.epoch != epoch {
+		*b = sloBucket{epoch: epoch}
+	}
+	b.total++
+	if good {
+		b.good++
+	}
+}
+
+// BurnRate is the ratio of bad requests in the window relative to the error budget
+func (c *sloCounter) BurnRate(now time.Time, window time.Duration) float64 {
+	var (
+		epoch  = now.UnixNano() / int64(sloBucketWidth)
+		oldest = epoch - int64(window/sloBucketWidth)
+		good   uint64
+		total  uint64
+	)
+	for _, b := range c.buckets {
+		if b.epoch <= oldest || b.epoch > epoch {
+			continue
+		}
+		good += b.good
+		total += b.total
+	}
+	if total == 0 {
+		return 0
+	}
+
+	errorRatio := float64(total-good) / float64(total)
+	return errorRatio / (1 - c.Objective)
+}
+
+// sloResponseWriter records when the response header was written
+type sloResponseWriter struct {
+	statusRecordingResponseWriter
+	start     time.Time
+	firstByte time.Time
+}
+
+func (w *sloResponseWriter) WriteHeader(code int) {
+	if w.firstByte.IsZero() {
+		w.firstByte = time.Now()
+	}
+	w.statusRecordingResponseWriter.WriteHeader(code)
+}
+
+func (w *sloResponseWriter) Write(b []byte) (int, error) {
+	if w.firstByte.IsZero() {
+		w.firstByte = time.Now()
+	}
+	return w.statusRecordingResponseWriter.Write(b)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestSLOTracker(t *testing.T) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewSLOTracker(SLOConfig{
+		Objectives: map[string]SLOObjective{
+			SLORouteClassPort: {Availability: 0.9, Latency: util.Duration(50 * time.Millisecond), LatencyTarget: 0.5},
+		},
+		Alerts: []SLOAlert{
+			{Name: "fast", LongWindow: util.Duration(time.Hour), ShortWindow: util.Duration(5 * time.Minute), BurnRate: 1.5},
+		},
+	})
+	tracker.now = func() time.Time { return now }
+
+	serve := func(class string, status int, delay time.Duration) {
+		handler := tracker.Handler(class)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.WriteHeader(status)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost/", nil))
+	}
+
+	// 50 minutes ago: 10% errors, i.e. exactly the error budget
+	now = now.Add(-50 * time.Minute)
+	for i := 0; i < 9; i++ {
+		serve(SLORouteClassPort, http.StatusOK, 0)
+	}
+	serve(SLORouteClassPort, http.StatusBadGateway, 0)
+	// requests of other classes are not tracked
+	serve(SLORouteClassIDE, http.StatusBadGateway, 0)
+
+	// now: 50% errors and slow responses
+	now = now.Add(50 * time.Minute)
+	serve(SLORouteClassPort, http.StatusOK, 100*time.Millisecond)
+	serve(SLORouteClassPort, http.StatusServiceUnavailable, 0)
+
+	expectation := []SLOStatus{
+		{
+			Class:     SLORouteClassPort,
+			SLO:       sloAvailability,
+			Objective: 0.9,
+			BurnRates: map[string]float64{"5m0s": 5, "1h0m0s": 2 / 12. / 0.1},
+			Alerting:  []string{"fast"},
+		},
+		{
+			Class:     SLORouteClassPort,
+			SLO:       sloLatency,
+			Objective: 0.5,
+			BurnRates: map[string]float64{"5m0s": 1, "1h0m0s": 1 / 12. / 0.5},
+			Alerting:  []string{},
+		},
+	}
+	if diff := cmp.Diff(expectation, tracker.Status(), cmp.Comparer(func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 })); diff != "" {
+		t.Errorf("unexpected status (-want +got):\n%s", diff)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	err := tracker.RegisterMetrics(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP slo_alerting 1 if the burn rate of an objective exceeds the alert threshold in both alert windows, 0 otherwise
+# TYPE slo_alerting gauge
+slo_alerting{alert="fast",class="port",slo="availability"} 1
+slo_alerting{alert="fast",class="port",slo="latency"} 0
+`), "slo_alerting")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// once the requests leave the windows the alert stops firing
+	now = now.Add(2 * time.Hour)
+	if act := tracker.Status()[0]; len(act.Alerting) != 0 || act.BurnRates["1h0m0s"] != 0 {
+		t.Errorf("expected burn rate to drop to zero, got %v", act)
+	}
+
+	var disabled *SLOTracker
+	disabled.Handler(SLORouteClassPort)(http.NotFoundHandler())
+}
+
+func TestSLOConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *SLOConfig
+		Error  bool
+	}{
+		{Name: "disabled"},
+		{Name: "no objectives", Config: &SLOConfig{}, Error: true},
+		{Name: "valid", Config: &SLOConfig{Objectives: map[string]SLOObjective{
+			SLORouteClassIDE:  {Availability: 0.999, Latency: util.Duration(time.Second), LatencyTarget: 0.99},
+			SLORouteClassPort: {Availability: 0.99},
+		}}},
+		{Name: "unknown class", Config: &SLOConfig{Objectives: map[string]SLOObjective{"foo": {Availability: 0.99}}}, Error: true},
+		{Name: "no budget", Config: &SLOConfig{Objectives: map[string]SLOObjective{SLORouteClassIDE: {Availability: 1}}}, Error: true},
+		{Name: "latency without target", Config: &SLOConfig{Objectives: map[string]SLOObjective{SLORouteClassIDE: {Latency: util.Duration(time.Second)}}}, Error: true},
+		{Name: "short window too long", Config: &SLOConfig{
+			Objectives: map[string]SLOObjective{SLORouteClassIDE: {Availability: 0.99}},
+			Alerts:     []SLOAlert{{Name: "page", LongWindow: util.Duration(time.Minute), ShortWindow: util.Duration(time.Hour), BurnRate: 1}},
+		}, Error: true},
+		{Name: "duplicate alert", Config: &SLOConfig{
+			Objectives: map[string]SLOObjective{SLORouteClassIDE: {Availability: 0.99}},
+			Alerts: []SLOAlert{
+				{Name: "page", LongWindow: util.Duration(time.Hour), ShortWindow: util.Duration(time.Minute), BurnRate: 1},
+				{Name: "page", LongWindow: util.Duration(time.Hour), ShortWindow: util.Duration(time.Minute), BurnRate: 2},
+			},
+		}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"hash/crc32"
+	"net"
+
+	"golang.org/x/xerrors"
+)
+
+// This file implements the subset of STUN (RFC 5389) the TURN relay needs.
+
+const (
+	stunHeaderSize  = 20
+	stunMagicCookie = 0x2112A442
+	// stunFingerprintXOR is XORed with the CRC32 of a message to produce the FINGERPRINT attribute
+	stunFingerprintXOR = 0x5354554e
+)
+
+type stunMethod uint16
+
+const (
+	stunMethodBinding          stunMethod = 0x001
+	stunMethodAllocate         stunMethod = 0x003
+	stunMethodRefresh          stunMethod = 0x004
+	stunMethodSend             stunMethod = 0x006
+	stunMethodData             stunMethod = 0x007
+	stunMethodCreatePermission stunMethod = 0x008
+	stunMethodChannelBind      stunMethod = 0x009
+)
+
+func (m stunMethod) String() string {
+	switch m {
+	case stunMethodBinding:
+		return "binding"
+	case stunMethodAllocate:
+		return "allocate"
+	case stunMethodRefresh:
+		return "refresh"
+	case stunMethodSend:
+		return "send"
+	case stunMethodData:
+		return "data"
+	case stunMethodCreatePermission:
+		return "createPermission"
+	case stunMethodChannelBind:
+		return "channelBind"
+	default:
+		return "unknown"
+	}
+}
+
+type stunClass uint16
+
+const (
+	stunClassRequest    stunClass = 0
+	stunClassIndication stunClass = 1
+	stunClassSuccess    stunClass = 2
+	stunClassError      stunClass = 3
+)
+
+type stunAttrType uint16
+
+const (
+	stunAttrUsername           stunAttrType = 0x0006
+	stunAttrMessageIntegrity   stunAttrType = 0x0008
+	stunAttrErrorCode          stunAttrType = 0x0009
+	stunAttrUnknownAttributes  stunAttrType = 0x000A
+	stunAttrChannelNumber      stunAttrType = 0x000C
+	stunAttrLifetime           stunAttrType = 0x000D
+	stunAttrXORPeerAddress     stunAttrType = 0x0012
+	stunAttrData               stunAttrType = 0x0013
+	stunAttrRealm              stunAttrType = 0x0014
+	stunAttrNonce              stunAttrType = 0x0015
+	stunAttrXORRelayedAddress  stunAttrType = 0x0016
+	stunAttrRequestedTransport stunAttrType = 0x0019
+	stunAttrDontFragment       stunAttrType = 0x001A
+	stunAttrXORMappedAddress   stunAttrType = 0x0020
+	stunAttrSoftware           stunAttrType = 0x8022
+	stunAttrFingerprint        stunAttrType = 0x8028
+)
+
+// stunAttrComprehensionRequired returns true if we must reject requests which carry an attribute we do not understand
+func stunAttrComprehensionRequired(t stunAttrType) bool {
+	return t < 0x8000
+}
+
+type stunAttr struct {
+	Type  stunAttrType
+	Value []byte
+}
+
+// stunMessage is a decoded STUN message
+type stunMessage struct {
+	Method        stunMethod
+	Class         stunClass
+	TransactionID [12]byte
+	Attrs         []stunAttr
+
+	// raw is the message as it was received, used to verify MESSAGE-INTEGRITY
+	raw []byte
+	// integrityOffset is the offset of the MESSAGE-INTEGRITY attribute in raw, or -1
+	integrityOffset int
+}
+
+// isSTUN returns true if a datagram looks like a STUN message rather than TURN ChannelData
+func isSTUN(b []byte) bool {
+	return len(b) >= stunHeaderSize && b[0]&0xC0 == 0 && binary.BigEndian.Uint32(b[4:8]) == stunMagicCookie
+}
+
+func stunMessageType(m stunMethod, c stunClass) uint16 {
+	mt := uint16(m)
+	ct := uint16(c)
+	return (mt & 0x000F) | ((mt & 0x0070) << 1) | ((mt & 0x0F80) << 2) | ((ct & 1) << 4) | ((ct & 2) << 7)
+}
+
+// parseSTUN decodes a STUN message
+func parseSTUN(b []byte) (*stunMessage, error) {
+	if !isSTUN(b) {
+		return nil, xerrors.Errorf("not a STUN message")
+	}
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if length%4 != 0 || stunHeaderSize+length > len(b) {
+		return nil, xerrors.Errorf("invalid STUN message length %d", length)
+	}
+	b = b[:stunHeaderSize+length]
+
+	tpe := binary.BigEndian.Uint16(b[0:2])
+	msg := &stunMessage{
+		Method:          stunMethod((tpe & 0x000F) | ((tpe & 0x00E0) >> 1) | ((tpe & 0x3E00) >> 2)),
+		Class:           stunClass(((tpe & 0x0010) >> 4) | ((tpe & 0x0100) >> 7)),
+		raw:             b,
+		integrityOffset: -1,
+	}
+	copy(msg.TransactionID[:], b[8:20])
+
+	for offset := stunHeaderSize; offset < len(b); {
+		if offset+4 > len(b) {
+			return nil, xerrors.Errorf("truncated STUN attribute")
+		}
+		at := stunAttrType(binary.BigEndian.Uint16(b[offset : offset+2]))
+		al := int(binary.BigEndian.Uint16(b[offset+2 : offset+4]))
+		if offset+4+al > len(b) {
+			return nil, xerrors.Errorf("truncated STUN attribute %#04x", uint16(at))
+		}
+		if msg.integrityOffset >= 0 && at != stunAttrFingerprint {
+			// everything after MESSAGE-INTEGRITY but FINGERPRINT must be ignored
+			break
+		}
+		if at == stunAttrMessageIntegrity {
+			msg.integrityOffset = offset
+		}
+		msg.Attrs = append(msg.Attrs, stunAttr{Type: at, Value: b[offset+4 : offset+4+al]})
+		offset += 4 + (al+3)&^3
+	}
+	return msg, nil
+}
+
+// Get returns the value of the first attribute of a type
+func (m *stunMessage) Get(t stunAttrType) ([]byte, bool) {
+	for _, a := range m.Attrs {
+		if a.Type == t {
+			return a.Value, true
+		}
+	}
+	return nil, false
+}
+
+// VerifyIntegrity checks the MESSAGE-INTEGRITY attribute of a received message against a key
+func (m *stunMessage) VerifyIntegrity(key []byte) bool {
+	if m.integrityOffset < 0 {
+		return false
+	}
+	mi, _ := m.Get(stunAttrMessageIntegrity)
+	if len(mi) != sha1.Size {
+		return false
+	}
+	// the length in the header covers the message up to and including MESSAGE-INTEGRITY
+	buf := make([]byte, m.integrityOffset)
+	copy(buf, m.raw[:m.integrityOffset])
+	binary.BigEndian.PutUint16(buf[2:4], uint16(m.integrityOffset-stunHeaderSize+4+sha1.Size))
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(buf)
+	return hmac.Equal(mac.Sum(nil), mi)
+}
+
+// stunBuilder encodes a STUN message
+type stunBuilder struct {
+	buf []byte
+}
+
+func newSTUNBuilder(m stunMethod, c stunClass, transactionID [12]byte) *stunBuilder {
+	buf := make([]byte, stunHeaderSize, 128)
+	binary.BigEndian.PutUint16(buf[0:2], stunMessageType(m, c))
+	binary.BigEndian.PutUint32(buf[4:8], stunMagicCookie)
+	copy(buf[8:20], transactionID[:])
+	return &stunBuilder{buf: buf}
+}
+
+func (b *stunBuilder) setLength(n int) {
+	binary.BigEndian.PutUint16(b.buf[2:4], uint16(n))
+}
+
+// Add appends an attribute
+func (b *stunBuilder) Add(t stunAttrType, value []byte) *stunBuilder {
+	var hdr [4]byte
+	binary.BigEndian.PutUint16(hdr[0:2], uint16(t))
+	binary.BigEndian.PutUint16(hdr[2:4], uint16(len(value)))
+	b.buf = append(b.buf, hdr[:]...)
+	b.buf = append(b.buf, value...)
+	for len(b.buf)%4 != 0 {
+		b.buf = append(b.buf, 0)
+	}
+	b.setLength(len(b.buf) - stunHeaderSize)
+	return b
+}
+
+// AddUint32 appends an attribute with a 32-bit value, e.g. LIFETIME
+func (b *stunBuilder) AddUint32(t stunAttrType, v uint32) *stunBuilder {
+	var val [4]byte
+	binary.BigEndian.PutUint32(val[:], v)
+	return b.Add(t, val[:])
+}
+
+// AddAddress appends an XOR-*-ADDRESS attribute
+func (b *stunBuilder) AddAddress(t stunAttrType, addr *net.UDPAddr) *stunBuilder {
+	return b.Add(t, encodeSTUNXORAddress(addr, b.buf[8:20]))
+}
+
+// AddError appends an ERROR-CODE attribute
+func (b *stunBuilder) AddError(code int, reason string) *stunBuilder {
+	val := make([]byte, 4, 4+len(reason))
+	val[2] = byte(code / 100)
+	val[3] = byte(code % 100)
+	return b.Add(stunAttrErrorCode, append(val, reason...))
+}
+
+// Bytes returns the message. If key is not nil, the message ends with MESSAGE-INTEGRITY. All messages end with FINGERPRINT.
+func (b *stunBuilder) Bytes(key []byte) []byte {
+	if key != nil {
+		b.setLength(len(b.buf) - stunHeaderSize + 4 + sha1.Size)
+		mac := hmac.New(sha1.New, key)
+		_, _ = mac.Write(b.buf)
+		b.Add(stunAttrMessageIntegrity, mac.Sum(nil))
+	}
+	b.setLength(len(b.buf) - stunHeaderSize + 8)
+	return b.AddUint32(stunAttrFingerprint, crc32.ChecksumIEEE(b.buf)^stunFingerprintXOR).buf
+}
+
+func encodeSTUNXORAddress(addr *net.UDPAddr, transactionID []byte) []byte {
+	var (
+		family byte = 0x01
+		ip          = addr.IP.To4()
+	)
+	if ip == nil {
+		family = 0x02
+		ip = addr.IP.To16()
+	}
+	res := make([]byte, 4+len(ip))
+	res[1] = family
+	binary.BigEndian.PutUint16(res[2:4], uint16(addr.Port)^(stunMagicCookie>>16))
+	mask := stunXORMask(transactionID)
+	for i := range ip {
+		res[4+i] = ip[i] ^ mask[i]
+	}
+	return res
+}
+
+func decodeSTUNXORAddress(val []byte, transactionID []byte) (*net.UDPAddr, error) {
+	if len(val) < 4 {
+		return nil, xerrors.Errorf("invalid address attribute")
+	}
+	var size int
+	switch val[1] {
+	case 0x01:
+		size = net.IPv4len
+	case 0x02:
+		size = net.IPv6len
+	default:
+		return nil, xerrors.Errorf("unknown address family %d", val[1])
+	}
+	if len(val) != 4+size {
+		return nil, xerrors.Errorf("invalid address attribute")
+	}
+	mask := stunXORMask(transactionID)
+	ip := make(net.IP, size)
+	for i := range ip {
+		ip[i] = val[4+i] ^ mask[i]
+	}
+	return &net.UDPAddr{
+		IP:   ip,
+		Port: int(binary.BigEndian.Uint16(val[2:4]) ^ (stunMagicCookie >> 16)),
+	}, nil
+}
+
+// stunXORMask is what XOR-*-ADDRESS attributes XOR the address with: the magic cookie followed by the transaction ID
+func stunXORMask(transactionID []byte) []byte {
+	mask := make([]byte, 16)
+	binary.BigEndian.PutUint32(mask[0:4], stunMagicCookie)
+	copy(mask[4:], transactionID)
+	return mask
+}
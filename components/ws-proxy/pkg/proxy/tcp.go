@@ -0,0 +1,472 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
+)
+
+const (
+	defaultTCPConnectTimeout = 10 * time.Second
+	defaultTCPIdleTimeout    = 1 * time.Hour
+)
+
+// TCPProxyConfig configures the raw TCP proxy for non-HTTP workspace services, e.g. databases, game servers or debuggers.
+// TCP connections carry no credentials, hence only ports their owner made public are reachable.
+type TCPProxyConfig struct {
+	// Start and End are the TCP port range ws-proxy listens on. Each port serves the workspace port
+	// whose public (HTTP) port is the TCP port minus PublicPortOffset.
+	Start uint16 `json:"start,omitempty"`
+	End   uint16 `json:"end,omitempty"`
+	// PublicPortOffset is the distance between the TCP port range and the public ports ws-manager assigns to workspace ports
+	PublicPortOffset int `json:"publicPortOffset,omitempty"`
+	// Hosts are the hosts the port range listens on. If empty, we listen on all interfaces.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// SNIAddress accepts TLS connections and selects the workspace port using the server name, e.g.
+	// 5432-amaranth-smelt-9ba20cc1.ws-eu01.gitpod.io. The TLS session is passed through to the workspace.
+	SNIAddress string `json:"sniAddress,omitempty"`
+	// ProxyProtocolAddress accepts connections which start with a PROXY protocol v1 or v2 header, e.g. from a load balancer
+	// in front of the port range. The destination port of the header selects the workspace port like a TCP port of the range.
+	ProxyProtocolAddress string `json:"proxyProtocolAddress,omitempty"`
+
+	// SendProxyProtocol sends a PROXY protocol v1 header with the client's address to the workspace
+	SendProxyProtocol bool `json:"sendProxyProtocol,omitempty"`
+	// ConnectTimeout limits how long we try to connect to the workspace. Defaults to 10 seconds.
+	ConnectTimeout util.Duration `json:"connectTimeout,omitempty"`
+	// IdleTimeout closes connections which did not transfer any data for this long. Defaults to one hour.
+	IdleTimeout util.Duration `json:"idleTimeout,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *TCPProxyConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Start == 0 && c.End == 0 && c.SNIAddress == "" && c.ProxyProtocolAddress == "" {
+		return xerrors.Errorf("tcp: neither a port range, an SNI address nor a PROXY protocol address is configured")
+	}
+	if c.Start != 0 || c.End != 0 {
+		if c.Start == 0 || c.End < c.Start {
+			return xerrors.Errorf("tcp: invalid port range %d-%d", c.Start, c.End)
+		}
+		if first, last := int(c.Start)-c.PublicPortOffset, int(c.End)-c.PublicPortOffset; first < 1 || last > 65535 {
+			return xerrors.Errorf("tcp: publicPortOffset %d maps the port range outside of the valid ports", c.PublicPortOffset)
+		}
+	}
+	for _, addr := range []string{c.SNIAddress, c.ProxyProtocolAddress} {
+		if addr == "" {
+			continue
+		}
+		if err := ValidateListenAddress(addr); err != nil {
+			return xerrors.Errorf("tcp: %w", err)
+		}
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.ConnectTimeout, validation.Min(util.Duration(0))),
+		validation.Field(&c.IdleTimeout, validation.Min(util.Duration(0))),
+	)
+}
+
+// TCPProxy forwards raw TCP connections to workspace ports
+type TCPProxy struct {
+	Config       TCPProxyConfig
+	PodConfig    *WorkspacePodConfig
+	InfoProvider WorkspaceInfoProvider
+	Health       *HealthChecker
+	// Bandwidth, if set, counts the bytes exchanged with workspaces and limits their egress
+	Bandwidth *BandwidthTracker
+	// Upgrades, if set, provides the listeners and hands them over to a successor ws-proxy
+	Upgrades *UpgradeController
+	// AbuseDetector, if set, rejects connections to ports it restricted to the workspace owner
+	AbuseDetector *AbuseDetector
+	// PortGate, if set, rejects connections to workspace ports which supervisor does not see served
+	PortGate *PortGate
+
+	scheme wsurl.Scheme
+
+	metrics struct {
+		connections *prometheus.CounterVec
+		active      prometheus.Gauge
+		bytes       *prometheus.CounterVec
+	}
+}
+
+// NewTCPProxy creates a new TCP proxy. Call MustServe to start listening.
+func NewTCPProxy(cfg TCPProxyConfig, proxyCfg Config, infoProvider WorkspaceInfoProvider) *TCPProxy {
+	res := &TCPProxy{
+		Config:       cfg,
+		PodConfig:    proxyCfg.WorkspacePodConfig,
+		InfoProvider: infoProvider,
+	}
+	if proxyCfg.GitpodInstallation != nil {
+		res.scheme.HostSuffix = proxyCfg.GitpodInstallation.WorkspaceHostSuffix
+	}
+
+	res.metrics.connections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tcp_connections_total",
+		Help: "TCP connections by the way the workspace port was selected and outcome",
+	}, []string{"mode", "outcome"})
+	res.metrics.active = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tcp_connections_active",
+		Help: "Currently open TCP connections to workspace ports",
+	})
+	res.metrics.bytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tcp_bytes_total",
+		Help: "Bytes forwarded by the TCP proxy by direction",
+	}, []string{"direction"})
+	return res
+}
+
+// RegisterMetrics registers the TCP proxy metrics
+func (p *TCPProxy) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{p.metrics.connections, p.metrics.active, p.metrics.bytes} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tcpListener is a TCP proxy listen address and the way its connections select the workspace port
+type tcpListener struct {
+	Addr   string
+	Mode   string
+	Select func(conn net.Conn) (coords *WorkspaceCoords, client net.Conn, err error)
+}
+
+func (p *TCPProxy) listeners() []tcpListener {
+	var res []tcpListener
+	if p.Config.Start != 0 {
+		hosts := p.Config.Hosts
+		if len(hosts) == 0 {
+			hosts = []string{""}
+		}
+		for port := int(p.Config.Start); port <= int(p.Config.End); port++ {
+			publicPort := strconv.Itoa(port - p.Config.PublicPortOffset)
+			for _, host := range hosts {
+				res = append(res, tcpListener{
+					Addr: net.JoinHostPort(host, strconv.Itoa(port)),
+					Mode: "port",
+					Select: func(conn net.Conn) (*WorkspaceCoords, net.Conn, error) {
+						return p.InfoProvider.WorkspaceCoords(publicPort), conn, nil
+					},
+				})
+			}
+		}
+	}
+	if p.Config.SNIAddress != "" {
+		res = append(res, tcpListener{Addr: p.Config.SNIAddress, Mode: "sni", Select: p.selectBySNI})
+	}
+	if p.Config.ProxyProtocolAddress != "" {
+		res = append(res, tcpListener{Addr: p.Config.ProxyProtocolAddress, Mode: "proxyProtocol", Select: p.selectByProxyProtocol})
+	}
+	return res
+}
+
+// ExpectListeners registers all listen addresses with the health checker
+func (p *TCPProxy) ExpectListeners() {
+	for _, l := range p.listeners() {
+		p.Health.ExpectListener(l.Addr)
+	}
+}
+
+// MustServe starts listening and forwarding connections. Fails fatally if we cannot listen.
+func (p *TCPProxy) MustServe() {
+	ls := p.listeners()
+	addrs := make([]string, len(ls))
+	for i, l := range ls {
+		addrs[i] = l.Addr
+	}
+	lns, err := p.Upgrades.Listen(addrs)
+	if err != nil {
+		log.WithError(err).Fatal("cannot start TCP proxy")
+		return
+	}
+	for i, ln := range lns {
+		p.Health.ListenerUp(addrs[i])
+		go p.serve(ln, ls[i])
+	}
+}
+
+func (p *TCPProxy) serve(ln net.Listener, l tcpListener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				time.Sleep(50 * time.Millisecond)
+				continue
+			}
+			if p.Upgrades.IsReplaced() {
+				return
+			}
+			log.WithError(err).WithField("addr", l.Addr).Error("TCP proxy listener failed")
+			return
+		}
+		go p.handle(conn, l)
+	}
+}
+
+func (p *TCPProxy) handle(conn net.Conn, l tcpListener) {
+	defer conn.Close()
+
+	connectTimeout := time.Duration(p.Config.ConnectTimeout)
+	if connectTimeout == 0 {
+		connectTimeout = defaultTCPConnectTimeout
+	}
+	// clients must select the workspace port in time, e.g. send their ClientHello
+	_ = conn.SetReadDeadline(time.Now().Add(connectTimeout))
+	coords, client, err := l.Select(conn)
+	if err != nil {
+		log.WithError(err).WithField("addr", l.Addr).Debug("cannot select workspace port for TCP connection")
+		p.metrics.connections.WithLabelValues(l.Mode, "invalid").Inc()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	target, err := p.resolve(ctx, coords)
+	if err != nil {
+		cancel()
+		log.WithError(err).WithField("addr", l.Addr).Debug("cannot resolve workspace port for TCP connection")
+		p.metrics.connections.WithLabelValues(l.Mode, "not_found").Inc()
+		return
+	}
+	var dialer net.Dialer
+	upstream, err := dialer.DialContext(ctx, "tcp", target)
+	cancel()
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", coords.ID, "")).WithField("port", coords.Port).Debug("cannot connect to workspace port")
+		p.metrics.connections.WithLabelValues(l.Mode, "unavailable").Inc()
+		return
+	}
+	defer upstream.Close()
+
+	client, release := p.Bandwidth.TrackConn(context.Background(), coords.ID, client)
+	defer release()
+
+	if p.Config.SendProxyProtocol {
+		src, _ := client.RemoteAddr().(*net.TCPAddr)
+		if src == nil {
+			src = &net.TCPAddr{IP: net.IPv4zero}
+		}
+		_, err = upstream.Write(proxyProtocolHeader(src, upstream.RemoteAddr()))
+		if err != nil {
+			p.metrics.connections.WithLabelValues(l.Mode, "unavailable").Inc()
+			return
+		}
+	}
+
+	p.metrics.connections.WithLabelValues(l.Mode, "forwarded").Inc()
+	p.metrics.active.Inc()
+	defer p.metrics.active.Dec()
+	p.pipe(client, upstream)
+}
+
+// resolve returns the address of a workspace port if the port is publicly reachable, and neither restricted
+// because of abuse nor known not to be served
+func (p *TCPProxy) resolve(ctx context.Context, coords *WorkspaceCoords) (string, error) {
+	if coords == nil || coords.ID == "" {
+		return "", xerrors.Errorf("unknown workspace port")
+	}
+	if coords.Port == "" {
+		return "", xerrors.Errorf("the IDE is not reachable via TCP")
+	}
+
+	info := p.InfoProvider.WorkspaceInfo(ctx, coords.ID)
+	if info == nil {
+		return "", xerrors.Errorf("unknown workspace %s", coords.ID)
+	}
+	port, err := strconv.ParseUint(coords.Port, 10, 16)
+	if err != nil || !isPublicPort(info, uint32(port)) {
+		return "", xerrors.Errorf("port %s of workspace %s is not public", coords.Port, coords.ID)
+	}
+	if p.AbuseDetector.IsRestricted(info, uint32(port)) {
+		return "", xerrors.Errorf("port %s of workspace %s is restricted because of abuse", coords.Port, coords.ID)
+	}
+
+	u, err := buildWorkspacePodURL(p.PodConfig.PortServiceTemplate, coords.ID, coords.Port)
+	if err != nil {
+		return "", err
+	}
+	if !p.PortGate.AdmitsConn(ctx, coords.ID, coords.Port, func() (*url.URL, error) { return u, nil }) {
+		return "", xerrors.Errorf("port %s of workspace %s is not served", coords.Port, coords.ID)
+	}
+	return u.Host, nil
+}
+
+// pipe copies data in both directions until either side closes its connection or the connection is idle for too long
+func (p *TCPProxy) pipe(client, upstream net.Conn) {
+	idleTimeout := time.Duration(p.Config.IdleTimeout)
+	if idleTimeout == 0 {
+		idleTimeout = defaultTCPIdleTimeout
+	}
+
+	var (
+		mu       sync.Mutex
+		lastSeen = time.Now()
+	)
+	touch := func() {
+		mu.Lock()
+		lastSeen = time.Now()
+		mu.Unlock()
+	}
+	copyConn := func(dst, src net.Conn, direction string) {
+		buf := make([]byte, 32*1024)
+		for {
+			_ = src.SetReadDeadline(time.Now().Add(idleTimeout))
+			n, err := src.Read(buf)
+			if n > 0 {
+				touch()
+				if _, werr := dst.Write(buf[:n]); werr != nil {
+					return
+				}
+				p.metrics.bytes.WithLabelValues(direction).Add(float64(n))
+			}
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				mu.Lock()
+				idle := time.Since(lastSeen)
+				mu.Unlock()
+				if idle < idleTimeout {
+					// the other direction is still active
+					continue
+				}
+				return
+			}
+			if err != nil {
+				// we pass half-closed connections on, e.g. for clients which shut down writing after sending a request
+				if cw, ok := dst.(interface{ CloseWrite() error }); ok && err == io.EOF {
+					_ = cw.CloseWrite()
+				}
+				return
+			}
+		}
+	}
+
+	done := make(chan struct{}, 2)
+	go func() {
+		copyConn(upstream, client, "upstream")
+		done <- struct{}{}
+	}()
+	go func() {
+		copyConn(client, upstream, "downstream")
+		done <- struct{}{}
+	}()
+	<-done
+	// one direction is done - we close both connections if the other one does not finish soon after
+	select {
+	case <-done:
+	case <-time.After(idleTimeout):
+	}
+	client.Close()
+	upstream.Close()
+}
+
+// selectBySNI reads the TLS ClientHello and selects the workspace port using its server name.
+// The returned connection replays the ClientHello so that the workspace can complete the handshake.
+func (p *TCPProxy) selectBySNI(conn net.Conn) (*WorkspaceCoords, net.Conn, error) {
+	serverName, hello, err := peekServerName(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+	host, err := p.scheme.ParseHost(serverName)
+	if err != nil || host.Port == 0 || host.ForeignPrefix != "" {
+		return nil, nil, xerrors.Errorf("server name %q does not name a workspace port", serverName)
+	}
+	return &WorkspaceCoords{ID: host.WorkspaceID, Port: strconv.Itoa(host.Port)}, &prefixConn{Conn: conn, r: io.MultiReader(bytes.NewReader(hello), conn)}, nil
+}
+
+// selectByProxyProtocol reads a PROXY protocol header and selects the workspace port using its destination port.
+// The returned connection reports the source address of the header as remote address.
+func (p *TCPProxy) selectByProxyProtocol(conn net.Conn) (*WorkspaceCoords, net.Conn, error) {
+	br := bufio.NewReader(conn)
+	src, dst, err := readProxyHeader(br)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dst == nil {
+		return nil, nil, xerrors.Errorf("connection does not start with a PROXY protocol header naming the destination")
+	}
+	publicPort := strconv.Itoa(dst.Port - p.Config.PublicPortOffset)
+	return p.InfoProvider.WorkspaceCoords(publicPort), &prefixConn{Conn: conn, r: br, remote: src}, nil
+}
+
+var errClientHelloRead = xerrors.Errorf("client hello read")
+
+// peekServerName reads the TLS ClientHello from conn and returns its server name and the bytes read
+func peekServerName(conn net.Conn) (serverName string, hello []byte, err error) {
+	var buf bytes.Buffer
+	var hi *tls.ClientHelloInfo
+	// crypto/tls parses the ClientHello for us - we abort the handshake right after
+	err = tls.Server(readOnlyConn{r: io.TeeReader(conn, &buf)}, &tls.Config{
+		GetConfigForClient: func(h *tls.ClientHelloInfo) (*tls.Config, error) {
+			hi = h
+			return nil, errClientHelloRead
+		},
+	}).Handshake()
+	if hi == nil {
+		return "", nil, xerrors.Errorf("cannot read TLS ClientHello: %w", err)
+	}
+	if hi.ServerName == "" {
+		return "", nil, xerrors.Errorf("TLS ClientHello has no server name")
+	}
+	return hi.ServerName, buf.Bytes(), nil
+}
+
+// readOnlyConn is a net.Conn which can only be read from
+type readOnlyConn struct {
+	r io.Reader
+}
+
+func (c readOnlyConn) Read(p []byte) (int, error)         { return c.r.Read(p) }
+func (c readOnlyConn) Write(p []byte) (int, error)        { return 0, io.ErrClosedPipe }
+func (c readOnlyConn) Close() error                       { return nil }
+func (c readOnlyConn) LocalAddr() net.Addr                { return nil }
+func (c readOnlyConn) RemoteAddr() net.Addr               { return nil }
+func (c readOnlyConn) SetDeadline(t time.Time) error      { return nil }
+func (c readOnlyConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c readOnlyConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// prefixConn is a net.Conn which reads from r before reading from the connection itself
+type prefixConn struct {
+	net.Conn
+	r      io.Reader
+	remote net.Addr
+}
+
+func (c *prefixConn) Read(p []byte) (int, error) { return c.r.Read(p) }
+
+func (c *prefixConn) RemoteAddr() net.Addr {
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *prefixConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const tcpTestWorkspaceID = "amaranth-smelt-9ba20cc1"
+
+func newTCPTestProxy(cfg TCPProxyConfig, port string, visibility wsapi.PortVisibility) *TCPProxy {
+	portNum, _ := strconv.Atoi(port)
+	return NewTCPProxy(cfg, Config{
+		GitpodInstallation: &GitpodInstallation{WorkspaceHostSuffix: ".ws.gitpod.example.com"},
+		WorkspacePodConfig: &WorkspacePodConfig{PortServiceTemplate: "http://127.0.0.1:{{ .port }}"},
+	}, &fixedInfoProvider{
+		Infos: map[string]*WorkspaceInfo{
+			tcpTestWorkspaceID: {
+				WorkspaceID: tcpTestWorkspaceID,
+				Ports:       []PortInfo{{PortSpec: wsapi.PortSpec{Port: uint32(portNum), Visibility: visibility}, PublicPort: "20001"}},
+			},
+		},
+		Coords: map[string]*WorkspaceCoords{
+			"20000": {ID: tcpTestWorkspaceID},
+			"20001": {ID: tcpTestWorkspaceID, Port: port},
+		},
+	})
+}
+
+func TestTCPProxyResolve(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Visibility  wsapi.PortVisibility
+		Coords      *WorkspaceCoords
+		Expectation string
+	}{
+		{Desc: "public port", Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC, Coords: &WorkspaceCoords{ID: tcpTestWorkspaceID, Port: "5432"}, Expectation: "127.0.0.1:5432"},
+		{Desc: "private port", Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PRIVATE, Coords: &WorkspaceCoords{ID: tcpTestWorkspaceID, Port: "5432"}},
+		{Desc: "unexposed port", Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC, Coords: &WorkspaceCoords{ID: tcpTestWorkspaceID, Port: "3306"}},
+		{Desc: "IDE", Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC, Coords: &WorkspaceCoords{ID: tcpTestWorkspaceID}},
+		{Desc: "unknown workspace", Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC, Coords: &WorkspaceCoords{ID: "blue-whale-12345678", Port: "5432"}},
+		{Desc: "unknown public port", Visibility: wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			p := newTCPTestProxy(TCPProxyConfig{}, "5432", test.Visibility)
+			act, err := p.resolve(context.Background(), test.Coords)
+			if (err != nil) != (test.Expectation == "") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if act != test.Expectation {
+				t.Errorf("unexpected target: want %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestTCPProxyResolveRestrictedPort(t *testing.T) {
+	coords := &WorkspaceCoords{ID: tcpTestWorkspaceID, Port: "5432"}
+
+	t.Run("restricted because of abuse", func(t *testing.T) {
+		p := newTCPTestProxy(TCPProxyConfig{}, "5432", wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC)
+		d, err := NewAbuseDetector(AbuseDetectionConfig{Threshold: 1, MinClients: 1}, "test-domain.com", p.InfoProvider, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.AbuseDetector = d
+		if _, err := p.resolve(context.Background(), coords); err != nil {
+			t.Fatalf("unrestricted port is not reachable: %v", err)
+		}
+
+		d.ports[abusePortKey{Port: 5432}] = &portAbuse{restricted: &AbusivePort{WorkspaceID: tcpTestWorkspaceID, Port: 5432, RestrictedAt: time.Now()}}
+		if target, err := p.resolve(context.Background(), coords); err == nil {
+			t.Errorf("restricted port is reachable via %s", target)
+		}
+	})
+
+	t.Run("not served", func(t *testing.T) {
+		// raw TCP services do not speak HTTP - that must not keep them from being reachable
+		service, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer service.Close()
+		go func() {
+			for {
+				conn, err := service.Accept()
+				if err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte("not HTTP\n"))
+				conn.Close()
+			}
+		}()
+		_, port, _ := net.SplitHostPort(service.Addr().String())
+		coords := &WorkspaceCoords{ID: tcpTestWorkspaceID, Port: port}
+
+		p := newTCPTestProxy(TCPProxyConfig{}, port, wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC)
+		gate, supervisor := newPortGateTestSupervisor(t)
+		gate.Config.CheckProtocol = true
+		p.PortGate = gate
+
+		supervisor.Ports.Store(fmt.Sprintf(`[{"localPort":%s,"globalPort":%s,"served":false}]`, port, port))
+		if target, err := p.resolve(context.Background(), coords); err == nil {
+			t.Errorf("port which is not served is reachable via %s", target)
+		}
+
+		now := time.Now().Add(time.Duration(gate.Config.NotServedTTL))
+		gate.now = func() time.Time { return now }
+		supervisor.Ports.Store(fmt.Sprintf(`[{"localPort":%s,"globalPort":%s,"served":true}]`, port, port))
+		if _, err := p.resolve(context.Background(), coords); err != nil {
+			t.Errorf("served port is not reachable: %v", err)
+		}
+	})
+}
+
+func TestTCPProxySNI(t *testing.T) {
+	p := newTCPTestProxy(TCPProxyConfig{}, "5432", wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		_ = tls.Client(client, &tls.Config{ServerName: "5432-" + tcpTestWorkspaceID + ".ws.gitpod.example.com"}).Handshake()
+	}()
+
+	coords, conn, err := p.selectBySNI(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&WorkspaceCoords{ID: tcpTestWorkspaceID, Port: "5432"}, coords); diff != "" {
+		t.Errorf("unexpected coords (-want +got):\n%s", diff)
+	}
+
+	// the ClientHello is passed on to the workspace
+	buf := make([]byte, 1)
+	_, err = io.ReadFull(conn, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf[0] != 0x16 {
+		t.Errorf("expected the replayed ClientHello to start with a handshake record, got %x", buf[0])
+	}
+}
+
+func TestTCPProxyForward(t *testing.T) {
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer upstream.Close()
+	headers := make(chan string, 1)
+	go func() {
+		conn, err := upstream.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		hdr, _ := r.ReadString('\n')
+		headers <- hdr
+		_, _ = io.Copy(conn, r)
+	}()
+	_, upstreamPort, _ := net.SplitHostPort(upstream.Addr().String())
+
+	p := newTCPTestProxy(TCPProxyConfig{
+		PublicPortOffset:     10000,
+		ProxyProtocolAddress: ":0",
+		SendProxyProtocol:    true,
+		IdleTimeout:          util.Duration(5 * time.Second),
+	}, upstreamPort, wsapi.PortVisibility_PORT_VISIBILITY_PUBLIC)
+	var lst tcpListener
+	for _, l := range p.listeners() {
+		if l.Mode == "proxyProtocol" {
+			lst = l
+		}
+	}
+
+	client, server := net.Pipe()
+	defer client.Close()
+	go p.handle(server, lst)
+
+	_, err = fmt.Fprintf(client, "PROXY TCP4 192.0.2.1 10.0.0.1 56324 30001\r\nhello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(client, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "hello" {
+		t.Errorf("unexpected echo: %q", buf)
+	}
+
+	expectedHeader := fmt.Sprintf("PROXY TCP4 192.0.2.1 127.0.0.1 56324 %s\r\n", upstreamPort)
+	if hdr := <-headers; hdr != expectedHeader {
+		t.Errorf("unexpected PROXY protocol header: want %q, got %q", expectedHeader, hdr)
+	}
+}
+
+func TestTCPProxyConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *TCPProxyConfig
+		Error  bool
+	}{
+		{Name: "nil"},
+		{Name: "port range", Config: &TCPProxyConfig{Start: 30001, End: 30100, PublicPortOffset: 10000}},
+		{Name: "SNI", Config: &TCPProxyConfig{SNIAddress: ":8443"}},
+		{Name: "nothing to listen on", Config: &TCPProxyConfig{}, Error: true},
+		{Name: "inverted range", Config: &TCPProxyConfig{Start: 30100, End: 30001}, Error: true},
+		{Name: "offset out of range", Config: &TCPProxyConfig{Start: 30001, End: 30100, PublicPortOffset: 40000}, Error: true},
+		{Name: "invalid address", Config: &TCPProxyConfig{ProxyProtocolAddress: "8443"}, Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
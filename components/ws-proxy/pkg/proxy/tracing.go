@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// tracingHandler starts a span for every request. If the request carries trace headers,
+// the span continues the caller's trace.
+func tracingHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		var opts []opentracing.StartSpanOption
+		sctx, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+		if err == nil {
+			opts = append(opts, ext.RPCServerOption(sctx))
+		}
+		span := opentracing.StartSpan("ws-proxy.request", opts...)
+		defer span.Finish()
+
+		var (
+			vars = mux.Vars(req)
+			wsID = vars[workspaceIDIdentifier]
+			port = vars[workspacePortIdentifier]
+		)
+		ext.HTTPMethod.Set(span, req.Method)
+		ext.HTTPUrl.Set(span, req.URL.String())
+		if wsID != "" {
+			span.SetTag(log.WorkspaceField, wsID)
+		}
+		if port != "" {
+			span.SetTag("workspacePort", port)
+		}
+
+		rec := &statusRecordingResponseWriter{ResponseWriter: resp, status: http.StatusOK}
+		h.ServeHTTP(rec, req.WithContext(opentracing.ContextWithSpan(req.Context(), span)))
+
+		ext.HTTPStatusCode.Set(span, uint16(rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			ext.Error.Set(span, true)
+		}
+	})
+}
+
+// injectTraceHeaders propagates the span found in the request's context to the upstream
+func injectTraceHeaders(req *http.Request) {
+	span := opentracing.SpanFromContext(req.Context())
+	if span == nil {
+		return
+	}
+
+	err := opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+	if err != nil {
+		log.WithError(err).Debug("cannot inject trace headers")
+	}
+}
+
+// statusRecordingResponseWriter records the status code written to it. It supports
+// hijacking and flushing so that websockets and streaming responses keep working.
+type statusRecordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecordingResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecordingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusRecordingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("response writer does not support hijacking")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestTracingHandler(t *testing.T) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	parent := tracer.StartSpan("caller")
+	req := httptest.NewRequest("GET", "http://example.com/foo", nil)
+	err := tracer.Inject(parent.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var upstreamHeader http.Header
+	handler := tracingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outgoing := r.Clone(r.Context())
+		outgoing.Header = make(http.Header)
+		injectTraceHeaders(outgoing)
+		upstreamHeader = outgoing.Header
+
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := tracer.FinishedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one finished span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.ParentID != parent.Context().(mocktracer.MockSpanContext).SpanID {
+		t.Errorf("span does not continue the caller's trace")
+	}
+	if code := span.Tag("http.status_code"); code != uint16(http.StatusBadGateway) {
+		t.Errorf("unexpected status code tag: %v", code)
+	}
+	if span.Tag("error") != true {
+		t.Errorf("expected span to be marked as error")
+	}
+
+	upstream, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(upstreamHeader))
+	if err != nil {
+		t.Fatalf("upstream did not receive trace headers: %v", err)
+	}
+	if upstream.(mocktracer.MockSpanContext).SpanID != span.SpanContext.SpanID {
+		t.Errorf("upstream received the wrong span")
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// defaultMaxIdleConnsPerUpstream is the number of idle connections we keep per upstream if not configured otherwise
+	defaultMaxIdleConnsPerUpstream = 16
+	// defaultTransportPoolTTL is how long we keep the transport of an unused upstream if not configured otherwise
+	defaultTransportPoolTTL = 10 * time.Minute
+)
+
+// TransportPool is a http.RoundTripper which maintains a separate transport, and hence
+// separate connection pool, per upstream. This way a busy workspace cannot exhaust the idle
+// connections of all other workspaces, and connections to stopped workspaces are dropped
+// once their upstream has not been used for the pool TTL.
+type TransportPool struct {
+	Config *TransportConfig
+
+	mu         sync.Mutex
+	transports map[string]*upstreamTransport
+	lastSweep  time.Time
+
+	metrics *transportPoolMetrics
+}
+
+type upstreamTransport struct {
+	http1    *http.Transport
+	h2c      *http2.Transport
+	warm     *warmConns
+	lastUsed time.Time
+}
+
+// NewTransportPool creates a new transport pool
+func NewTransportPool(config *TransportConfig) *TransportPool {
+	return &TransportPool{
+		Config:     config,
+		transports: make(map[string]*upstreamTransport),
+		lastSweep:  time.Now(),
+	}
+}
+
+// RegisterMetrics registers the pool utilization metrics
+func (p *TransportPool) RegisterMetrics(reg prometheus.Registerer) error {
+	metrics := newTransportPoolMetrics()
+	err := metrics.Register(reg)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.metrics = metrics
+	p.metrics.OnPoolSizeChange(len(p.transports))
+	p.mu.Unlock()
+	return nil
+}
+
+// RoundTrip forwards the request using the transport of its upstream. Requests which must announce their client
+// using the PROXY protocol get a transport per upstream and client IP, so that clients never share a connection.
+func (p *TransportPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	t, metrics := p.get(req.URL.Scheme+"://"+req.URL.Host, getClientAddr(req.Context()))
+
+	if metrics != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				metrics.OnConnection(info.Reused)
+			},
+		}))
+	}
+
+	if p.useH2C(req) {
+		return t.h2c.RoundTrip(req)
+	}
+	return t.http1.RoundTrip(req)
+}
+
+// useH2C decides if we talk HTTP/2 cleartext to the upstream. gRPC requires HTTP/2, hence we always use it for
+// gRPC requests to plain HTTP upstreams. HTTP/2 has no connection upgrades, hence websockets always use HTTP/1.1.
+func (p *TransportPool) useH2C(req *http.Request) bool {
+	if req.URL.Scheme != "http" || req.Header.Get("Upgrade") != "" {
+		return false
+	}
+	return p.Config.HTTP2 || isGRPCRequest(req)
+}
+
+// Prewarm opens a connection to an upstream which the next request to that upstream uses instead of dialing,
+// so that this request does not wait for the connection to be established.
+func (p *TransportPool) Prewarm(ctx context.Context, upstream *url.URL) error {
+	t, _ := p.get(upstream.Scheme+"://"+upstream.Host, nil)
+	return t.warm.Open(ctx, canonicalAddr(upstream))
+}
+
+// CloseIdleConnections closes the idle connections of all upstreams
+func (p *TransportPool) CloseIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, t := range p.transports {
+		t.closeIdleConnections()
+	}
+}
+
+func (p *TransportPool) get(upstream string, client *net.TCPAddr) (*upstreamTransport, *transportPoolMetrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	ttl := p.ttl()
+	if now.Sub(p.lastSweep) > ttl/2 {
+		p.evictUnused(now, ttl)
+		p.lastSweep = now
+	}
+
+	key := upstream
+	if client != nil {
+		key += "#" + client.IP.String()
+	}
+	t, ok := p.transports[key]
+	if !ok {
+		t = newUpstreamTransport(p.Config, client)
+		p.transports[key] = t
+		p.metrics.OnPoolSizeChange(len(p.transports))
+	}
+	t.lastUsed = now
+	return t, p.metrics
+}
+
+// evictUnused removes the transports of upstreams we have not used for the TTL. Requests
+// which are still in flight on an evicted transport are not affected.
+func (p *TransportPool) evictUnused(now time.Time, ttl time.Duration) {
+	var evicted int
+	for upstream, t := range p.transports {
+		if now.Sub(t.lastUsed) <= ttl {
+			continue
+		}
+		t.closeIdleConnections()
+		delete(p.transports, upstream)
+		evicted++
+	}
+	if evicted > 0 {
+		p.metrics.OnEviction(evicted)
+		p.metrics.OnPoolSizeChange(len(p.transports))
+	}
+}
+
+func (p *TransportPool) ttl() time.Duration {
+	if p.Config.PoolTTL > 0 {
+		return time.Duration(p.Config.PoolTTL)
+	}
+	return defaultTransportPoolTTL
+}
+
+// newUpstreamTransport creates the transport of an upstream. If client is not nil, every connection
+// announces client using the PROXY protocol.
+func newUpstreamTransport(config *TransportConfig, client *net.TCPAddr) *upstreamTransport {
+	maxIdleConnsPerHost := config.MaxIdleConnsPerUpstream
+	if maxIdleConnsPerHost == 0 {
+		maxIdleConnsPerHost = defaultMaxIdleConnsPerUpstream
+	}
+
+	warm := newWarmConns(newUpstreamDialer(config, client), time.Duration(config.IdleConnTimeout))
+	dial := warm.DialContext
+	// this is based on http.DefaultTransport, with some values exposed to config
+	res := &upstreamTransport{
+		http1: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dial,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          config.MaxIdleConns, // default: 100
+			MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+			IdleConnTimeout:       time.Duration(config.IdleConnTimeout), // default: 90s
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		// h2c: HTTP/2 with prior knowledge over a plain TCP connection. The transport does not
+		// dial until it's used, i.e. if HTTP2 is disabled it only ever carries gRPC requests.
+		h2c: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return dial(context.Background(), network, addr)
+			},
+			ReadIdleTimeout: time.Duration(config.IdleConnTimeout),
+		},
+		warm: warm,
+	}
+	return res
+}
+
+func (t *upstreamTransport) closeIdleConnections() {
+	t.http1.CloseIdleConnections()
+	t.h2c.CloseIdleConnections()
+	t.warm.Close()
+}
+
+type transportPoolMetrics struct {
+	poolSize    prometheus.Gauge
+	evictions   prometheus.Counter
+	connections *prometheus.CounterVec
+}
+
+func newTransportPoolMetrics() *transportPoolMetrics {
+	return &transportPoolMetrics{
+		poolSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "transport_pool_upstreams",
+			Help: "number of upstreams with a pooled transport",
+		}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "transport_pool_evictions_total",
+			Help: "number of upstream transports evicted from the pool because they were unused",
+		}),
+		connections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "transport_pool_connections_total",
+			Help: "number of connections used for upstream requests, by whether they were reused from the pool",
+		}, []string{"reused"}),
+	}
+}
+
+// Register registers all transport pool metrics
+func (m *transportPoolMetrics) Register(reg prometheus.Registerer) error {
+	if m == nil {
+		return nil
+	}
+
+	collectors := []prometheus.Collector{
+		m.poolSize,
+		m.evictions,
+		m.connections,
+	}
+	for _, c := range collectors {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *transportPoolMetrics) OnPoolSizeChange(v int) {
+	if m == nil {
+		return
+	}
+	m.poolSize.Set(float64(v))
+}
+
+func (m *transportPoolMetrics) OnEviction(n int) {
+	if m == nil {
+		return
+	}
+	m.evictions.Add(float64(n))
+}
+
+func (m *transportPoolMetrics) OnConnection(reused bool) {
+	if m == nil {
+		return
+	}
+	m.connections.WithLabelValues(strconv.FormatBool(reused)).Inc()
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestTransportPool(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	})
+	upstreamA := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer upstreamA.Close()
+	upstreamB := httptest.NewServer(handler)
+	defer upstreamB.Close()
+
+	get := func(t *testing.T, pool *TransportPool, url string, header http.Header) string {
+		req, _ := http.NewRequest("GET", url, nil)
+		if header != nil {
+			req.Header = header
+		}
+		resp, err := pool.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	t.Run("per upstream", func(t *testing.T) {
+		pool := NewTransportPool(&TransportConfig{ConnectTimeout: util.Duration(time.Second), IdleConnTimeout: util.Duration(time.Minute), MaxIdleConns: 10})
+		reg := prometheus.NewRegistry()
+		err := pool.RegisterMetrics(reg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 3; i++ {
+			get(t, pool, upstreamA.URL, nil)
+			get(t, pool, upstreamB.URL, nil)
+		}
+		if n := testutil.ToFloat64(pool.metrics.poolSize); n != 2 {
+			t.Errorf("expected two pooled upstreams, got %v", n)
+		}
+		if n := testutil.ToFloat64(pool.metrics.connections.WithLabelValues("false")); n != 2 {
+			t.Errorf("expected one new connection per upstream, got %v", n)
+		}
+		if n := testutil.ToFloat64(pool.metrics.connections.WithLabelValues("true")); n != 4 {
+			t.Errorf("expected four reused connections, got %v", n)
+		}
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		pool := NewTransportPool(&TransportConfig{PoolTTL: util.Duration(50 * time.Millisecond), MaxIdleConns: 10})
+		get(t, pool, upstreamA.URL, nil)
+		time.Sleep(100 * time.Millisecond)
+		get(t, pool, upstreamB.URL, nil)
+
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		if _, ok := pool.transports["http://"+upstreamA.Listener.Addr().String()]; ok {
+			t.Error("unused upstream was not evicted")
+		}
+		if len(pool.transports) != 1 {
+			t.Errorf("expected one pooled upstream, got %d", len(pool.transports))
+		}
+	})
+
+	t.Run("h2c", func(t *testing.T) {
+		pool := NewTransportPool(&TransportConfig{HTTP2: true, MaxIdleConns: 10})
+		if proto := get(t, pool, upstreamA.URL, nil); proto != "HTTP/2.0" {
+			t.Errorf("expected HTTP/2.0, got %s", proto)
+		}
+		if proto := get(t, pool, upstreamA.URL, http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}}); proto != "HTTP/1.1" {
+			t.Errorf("expected upgrade request to use HTTP/1.1, got %s", proto)
+		}
+	})
+}
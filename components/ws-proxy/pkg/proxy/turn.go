@@ -0,0 +1,920 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	// turnCredentialsAPIPath is where the workspace owner obtains TURN credentials for the workspace
+	turnCredentialsAPIPath = "/_wsproxy/turn-credentials"
+
+	defaultTURNRealm                      = "gitpod"
+	defaultTURNCredentialTTL              = 12 * time.Hour
+	defaultTURNMaxAllocationsPerWorkspace = 10
+
+	turnDefaultAllocationLifetime = 10 * time.Minute
+	turnMaxAllocationLifetime     = 1 * time.Hour
+	turnPermissionLifetime        = 5 * time.Minute
+	turnChannelLifetime           = 10 * time.Minute
+	turnNonceLifetime             = 1 * time.Hour
+	turnJanitorInterval           = 30 * time.Second
+
+	// turnTransportUDP is the protocol number of UDP in REQUESTED-TRANSPORT, the only transport we relay
+	turnTransportUDP = 17
+	turnMaxDatagram  = 65535
+)
+
+// defaultTURNDeniedPeerNetworks keep peers from using the relay to reach into the cluster network
+var defaultTURNDeniedPeerNetworks = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"224.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+}
+
+// TURNConfig configures the TURN relay (RFC 5766) which lets WebRTC applications in workspaces exchange media and data
+// channels with peers outside of the cluster. Workspace pods cannot receive UDP traffic from the internet, but they can
+// allocate a relayed address on ws-proxy which the external peer sends its traffic to.
+//
+// Credentials follow the TURN REST API convention: the username is "<expiry>:<workspaceID>", the password the
+// base64-encoded HMAC-SHA1 of the username. The workspace owner obtains them from /_wsproxy/turn-credentials.
+type TURNConfig struct {
+	// ListenAddr is the UDP address TURN clients talk to, e.g. ":3478"
+	ListenAddr string `json:"listenAddr"`
+	// PublicIP is the IP under which clients and peers reach ws-proxy. We hand it out as relayed address.
+	PublicIP string `json:"publicIP"`
+	// URLs are the TURN URIs we hand out with the credentials. Defaults to turn:<publicIP>:<port>?transport=udp.
+	URLs []string `json:"urls,omitempty"`
+	// RelayPortStart and RelayPortEnd are the UDP ports relayed addresses are allocated from. If both are zero,
+	// the operating system picks the ports.
+	RelayPortStart uint16 `json:"relayPortStart,omitempty"`
+	RelayPortEnd   uint16 `json:"relayPortEnd,omitempty"`
+	// Realm is the realm of the long-term credentials. Defaults to "gitpod".
+	Realm string `json:"realm,omitempty"`
+	// SecretFile contains the key we derive credentials from. All proxies of an installation must share the key.
+	SecretFile string `json:"secretFile"`
+	// CredentialTTL is how long credentials are valid. Defaults to 12 hours.
+	CredentialTTL util.Duration `json:"credentialTTL,omitempty"`
+	// MaxAllocationsPerWorkspace limits the relayed addresses a workspace can hold at a time. Defaults to 10.
+	MaxAllocationsPerWorkspace int `json:"maxAllocationsPerWorkspace,omitempty"`
+	// DeniedPeerNetworks are the networks we never relay to or from. If empty, loopback, private, link-local,
+	// shared and multicast networks are denied so that the relay does not expose the cluster network.
+	DeniedPeerNetworks []string `json:"deniedPeerNetworks,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *TURNConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if err := ValidateListenAddress(c.ListenAddr); err != nil {
+		return xerrors.Errorf("turn: %w", err)
+	}
+	if net.ParseIP(c.PublicIP) == nil {
+		return xerrors.Errorf("turn: publicIP %q is not an IP address", c.PublicIP)
+	}
+	if c.RelayPortStart != 0 || c.RelayPortEnd != 0 {
+		if c.RelayPortStart == 0 || c.RelayPortEnd < c.RelayPortStart {
+			return xerrors.Errorf("turn: invalid relay port range %d-%d", c.RelayPortStart, c.RelayPortEnd)
+		}
+	}
+	for _, n := range c.DeniedPeerNetworks {
+		if _, _, err := net.ParseCIDR(n); err != nil {
+			return xerrors.Errorf("turn: invalid denied peer network %s: %w", n, err)
+		}
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.SecretFile, validation.Required, validation.By(validateFileExists(""))),
+		validation.Field(&c.CredentialTTL, validation.Min(util.Duration(0))),
+		validation.Field(&c.MaxAllocationsPerWorkspace, validation.Min(0)),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid TURN config: %w", err)
+	}
+	return nil
+}
+
+// TURNCredentials are the credentials of a workspace in the shape of a WebRTC RTCIceServer
+type TURNCredentials struct {
+	URLs       []string `json:"urls"`
+	Username   string   `json:"username"`
+	Credential string   `json:"credential"`
+	// TTL is the number of seconds the credentials are valid for
+	TTL int64 `json:"ttl"`
+}
+
+// turnAllocation is a relayed address a client holds
+type turnAllocation struct {
+	Client      *net.UDPAddr
+	WorkspaceID string
+	Username    string
+	// TransactionID is the transaction that created the allocation, so that we can answer retransmissions
+	TransactionID [12]byte
+	Relay         *net.UDPConn
+	RelayAddr     *net.UDPAddr
+	Expires       time.Time
+	key           []byte
+
+	// permissions maps peer IPs to the time their permission expires
+	permissions map[string]time.Time
+	channels    map[uint16]*turnChannel
+	peers       map[string]uint16
+}
+
+type turnChannel struct {
+	Peer    *net.UDPAddr
+	Expires time.Time
+}
+
+// TURNServer relays UDP traffic between TURN clients and their peers
+type TURNServer struct {
+	Config       TURNConfig
+	InfoProvider WorkspaceInfoProvider
+	Health       *HealthChecker
+
+	secret   []byte
+	publicIP net.IP
+	denied   []*net.IPNet
+	conn     net.PacketConn
+
+	mu           sync.Mutex
+	allocations  map[string]*turnAllocation
+	relayPorts   map[int]struct{}
+	perWorkspace map[string]int
+
+	now func() time.Time
+
+	metrics struct {
+		allocations *prometheus.CounterVec
+		active      prometheus.Gauge
+		bytes       *prometheus.CounterVec
+	}
+}
+
+// NewTURNServer creates a new TURN relay. Call MustServe to start listening.
+func NewTURNServer(cfg TURNConfig, infoProvider WorkspaceInfoProvider) (*TURNServer, error) {
+	secret, err := os.ReadFile(cfg.SecretFile)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read TURN secret: %w", err)
+	}
+	secret = bytes.TrimSpace(secret)
+	if len(secret) < 32 {
+		return nil, xerrors.Errorf("TURN secret must be at least 32 bytes long")
+	}
+
+	if cfg.Realm == "" {
+		cfg.Realm = defaultTURNRealm
+	}
+	if cfg.CredentialTTL == 0 {
+		cfg.CredentialTTL = util.Duration(defaultTURNCredentialTTL)
+	}
+	if cfg.MaxAllocationsPerWorkspace == 0 {
+		cfg.MaxAllocationsPerWorkspace = defaultTURNMaxAllocationsPerWorkspace
+	}
+	deniedNetworks := cfg.DeniedPeerNetworks
+	if len(deniedNetworks) == 0 {
+		deniedNetworks = defaultTURNDeniedPeerNetworks
+	}
+	var denied []*net.IPNet
+	for _, n := range deniedNetworks {
+		_, ipnet, err := net.ParseCIDR(n)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid denied peer network %s: %w", n, err)
+		}
+		denied = append(denied, ipnet)
+	}
+	publicIP := net.ParseIP(cfg.PublicIP)
+	if publicIP == nil {
+		return nil, xerrors.Errorf("publicIP %q is not an IP address", cfg.PublicIP)
+	}
+	if ip4 := publicIP.To4(); ip4 != nil {
+		publicIP = ip4
+	}
+
+	s := &TURNServer{
+		Config:       cfg,
+		InfoProvider: infoProvider,
+		secret:       secret,
+		publicIP:     publicIP,
+		denied:       denied,
+		allocations:  make(map[string]*turnAllocation),
+		relayPorts:   make(map[int]struct{}),
+		perWorkspace: make(map[string]int),
+		now:          time.Now,
+	}
+	s.metrics.allocations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "turn_allocations_total",
+		Help: "TURN allocation requests by outcome",
+	}, []string{"outcome"})
+	s.metrics.active = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "turn_allocations_active",
+		Help: "Relayed addresses TURN clients currently hold",
+	})
+	s.metrics.bytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "turn_bytes_total",
+		Help: "Bytes relayed by the TURN server by direction",
+	}, []string{"direction"})
+	return s, nil
+}
+
+// RegisterMetrics registers the TURN server metrics
+func (s *TURNServer) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{s.metrics.allocations, s.metrics.active, s.metrics.bytes} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MustServe starts listening for TURN clients. Fails fatally if we cannot listen.
+// Allocations do not survive a restart of ws-proxy; clients re-allocate when their ICE connection fails.
+func (s *TURNServer) MustServe() {
+	s.Health.ExpectListener(s.Config.ListenAddr)
+	conn, err := net.ListenPacket("udp", s.Config.ListenAddr)
+	if err != nil {
+		log.WithError(err).Fatal("cannot start TURN server")
+		return
+	}
+	s.Health.ListenerUp(s.Config.ListenAddr)
+	go s.serve(conn)
+}
+
+func (s *TURNServer) serve(conn net.PacketConn) {
+	s.conn = conn
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.janitor(stop)
+
+	buf := make([]byte, turnMaxDatagram)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			log.WithError(err).Error("TURN server stopped")
+			return
+		}
+		client, ok := addr.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		s.handlePacket(buf[:n], client)
+	}
+}
+
+func (s *TURNServer) handlePacket(b []byte, client *net.UDPAddr) {
+	if !isSTUN(b) {
+		s.handleChannelData(b, client)
+		return
+	}
+	msg, err := parseSTUN(b)
+	if err != nil {
+		log.WithError(err).WithField("client", client.String()).Debug("dropping malformed STUN message")
+		return
+	}
+
+	switch {
+	case msg.Class == stunClassIndication && msg.Method == stunMethodSend:
+		s.handleSend(msg, client)
+	case msg.Class != stunClassRequest:
+		// we never send requests, hence expect no responses
+	case msg.Method == stunMethodBinding:
+		s.reply(client, newSTUNBuilder(stunMethodBinding, stunClassSuccess, msg.TransactionID).AddAddress(stunAttrXORMappedAddress, client), nil)
+	case msg.Method == stunMethodAllocate, msg.Method == stunMethodRefresh, msg.Method == stunMethodCreatePermission, msg.Method == stunMethodChannelBind:
+		s.handleRequest(msg, client)
+	default:
+		s.replyError(client, msg, 400, "Bad Request", nil)
+	}
+}
+
+func (s *TURNServer) reply(client *net.UDPAddr, b *stunBuilder, key []byte) {
+	_, err := s.conn.WriteTo(b.Bytes(key), client)
+	if err != nil {
+		log.WithError(err).WithField("client", client.String()).Debug("cannot reply to TURN client")
+	}
+}
+
+func (s *TURNServer) replyError(client *net.UDPAddr, msg *stunMessage, code int, reason string, key []byte) {
+	s.reply(client, newSTUNBuilder(msg.Method, stunClassError, msg.TransactionID).AddError(code, reason), key)
+}
+
+// handleRequest authenticates a request with the long-term credential mechanism and dispatches it
+func (s *TURNServer) handleRequest(msg *stunMessage, client *net.UDPAddr) {
+	var unknown []byte
+	for _, a := range msg.Attrs {
+		switch a.Type {
+		case stunAttrUsername, stunAttrMessageIntegrity, stunAttrRealm, stunAttrNonce, stunAttrLifetime, stunAttrRequestedTransport,
+			stunAttrDontFragment, stunAttrXORPeerAddress, stunAttrChannelNumber, stunAttrData:
+		default:
+			if stunAttrComprehensionRequired(a.Type) {
+				unknown = append(unknown, byte(a.Type>>8), byte(a.Type))
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		s.reply(client, newSTUNBuilder(msg.Method, stunClassError, msg.TransactionID).AddError(420, "Unknown Attribute").Add(stunAttrUnknownAttributes, unknown), nil)
+		return
+	}
+
+	username, workspaceID, key, code := s.authenticate(msg)
+	if code != 0 {
+		if msg.Method == stunMethodAllocate {
+			s.metrics.allocations.WithLabelValues("unauthorized").Inc()
+		}
+		reason := "Unauthorized"
+		if code == 438 {
+			reason = "Stale Nonce"
+		}
+		b := newSTUNBuilder(msg.Method, stunClassError, msg.TransactionID).AddError(code, reason).
+			Add(stunAttrRealm, []byte(s.Config.Realm)).
+			Add(stunAttrNonce, []byte(s.nonce()))
+		s.reply(client, b, nil)
+		return
+	}
+
+	if msg.Method == stunMethodAllocate {
+		s.handleAllocate(msg, client, username, workspaceID, key)
+		return
+	}
+
+	s.mu.Lock()
+	alloc, ok := s.allocations[client.String()]
+	s.mu.Unlock()
+	if !ok {
+		s.replyError(client, msg, 437, "Allocation Mismatch", key)
+		return
+	}
+	if alloc.Username != username {
+		s.replyError(client, msg, 441, "Wrong Credentials", key)
+		return
+	}
+
+	switch msg.Method {
+	case stunMethodRefresh:
+		s.handleRefresh(msg, alloc, key)
+	case stunMethodCreatePermission:
+		s.handleCreatePermission(msg, alloc, key)
+	case stunMethodChannelBind:
+		s.handleChannelBind(msg, alloc, key)
+	}
+}
+
+// authenticate verifies the credentials of a request. It returns a STUN error code if the request is not authenticated.
+func (s *TURNServer) authenticate(msg *stunMessage) (username, workspaceID string, key []byte, code int) {
+	usr, hasUsername := msg.Get(stunAttrUsername)
+	realm, hasRealm := msg.Get(stunAttrRealm)
+	nonce, hasNonce := msg.Get(stunAttrNonce)
+	if msg.integrityOffset < 0 || !hasUsername || !hasRealm || !hasNonce {
+		return "", "", nil, 401
+	}
+	if string(realm) != s.Config.Realm {
+		return "", "", nil, 401
+	}
+	if !s.validNonce(string(nonce)) {
+		return "", "", nil, 438
+	}
+
+	username = string(usr)
+	segs := strings.SplitN(username, ":", 2)
+	if len(segs) != 2 || segs[1] == "" {
+		return "", "", nil, 401
+	}
+	expiry, err := strconv.ParseInt(segs[0], 10, 64)
+	if err != nil || s.now().Unix() > expiry {
+		return "", "", nil, 401
+	}
+	key = turnKey(username, s.Config.Realm, s.password(username))
+	if !msg.VerifyIntegrity(key) {
+		return "", "", nil, 401
+	}
+	return username, segs[1], key, 0
+}
+
+// password returns the password of a TURN REST API username
+func (s *TURNServer) password(username string) string {
+	mac := hmac.New(sha1.New, s.secret)
+	_, _ = mac.Write([]byte(username))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// turnKey is the long-term credential key MESSAGE-INTEGRITY is computed with
+func turnKey(username, realm, password string) []byte {
+	sum := md5.Sum([]byte(username + ":" + realm + ":" + password))
+	return sum[:]
+}
+
+// nonce produces a nonce which is valid for turnNonceLifetime. Nonces are signed rather than stored,
+// so that they remain valid across requests without any state on our side.
+func (s *TURNServer) nonce() string {
+	expiry := strconv.FormatInt(s.now().Add(turnNonceLifetime).Unix(), 16)
+	return expiry + "-" + s.nonceSignature(expiry)
+}
+
+func (s *TURNServer) nonceSignature(expiry string) string {
+	mac := hmac.New(sha1.New, s.secret)
+	_, _ = mac.Write([]byte("nonce:" + expiry))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+func (s *TURNServer) validNonce(nonce string) bool {
+	segs := strings.SplitN(nonce, "-", 2)
+	if len(segs) != 2 || !hmac.Equal([]byte(segs[1]), []byte(s.nonceSignature(segs[0]))) {
+		return false
+	}
+	expiry, err := strconv.ParseInt(segs[0], 16, 64)
+	return err == nil && s.now().Unix() <= expiry
+}
+
+func (s *TURNServer) handleAllocate(msg *stunMessage, client *net.UDPAddr, username, workspaceID string, key []byte) {
+	s.mu.Lock()
+	existing, ok := s.allocations[client.String()]
+	s.mu.Unlock()
+	if ok {
+		if existing.TransactionID == msg.TransactionID {
+			// a retransmission of the request which created the allocation
+			s.replyAllocated(msg, existing, key)
+			return
+		}
+		s.replyError(client, msg, 437, "Allocation Mismatch", key)
+		return
+	}
+
+	transport, ok := msg.Get(stunAttrRequestedTransport)
+	if !ok || len(transport) != 4 {
+		s.replyError(client, msg, 400, "Bad Request", key)
+		return
+	}
+	if transport[0] != turnTransportUDP {
+		s.replyError(client, msg, 442, "Unsupported Transport Protocol", key)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	info := s.InfoProvider.WorkspaceInfo(ctx, workspaceID)
+	cancel()
+	if info == nil {
+		s.metrics.allocations.WithLabelValues("forbidden").Inc()
+		s.replyError(client, msg, 403, "Forbidden", key)
+		return
+	}
+
+	s.mu.Lock()
+	if s.perWorkspace[workspaceID] >= s.Config.MaxAllocationsPerWorkspace {
+		s.mu.Unlock()
+		s.metrics.allocations.WithLabelValues("quota").Inc()
+		s.replyError(client, msg, 486, "Allocation Quota Reached", key)
+		return
+	}
+	relay, err := s.listenRelay()
+	if err != nil {
+		s.mu.Unlock()
+		log.WithError(err).WithFields(log.OWI("", workspaceID, "")).Warn("cannot allocate TURN relay address")
+		s.metrics.allocations.WithLabelValues("failed").Inc()
+		s.replyError(client, msg, 508, "Insufficient Capacity", key)
+		return
+	}
+	port := relay.LocalAddr().(*net.UDPAddr).Port
+	alloc := &turnAllocation{
+		Client:        client,
+		WorkspaceID:   workspaceID,
+		Username:      username,
+		TransactionID: msg.TransactionID,
+		Relay:         relay,
+		RelayAddr:     &net.UDPAddr{IP: s.publicIP, Port: port},
+		Expires:       s.now().Add(turnAllocationLifetime(msg)),
+		key:           key,
+		permissions:   make(map[string]time.Time),
+		channels:      make(map[uint16]*turnChannel),
+		peers:         make(map[string]uint16),
+	}
+	s.allocations[client.String()] = alloc
+	s.relayPorts[port] = struct{}{}
+	s.perWorkspace[workspaceID]++
+	s.mu.Unlock()
+
+	s.metrics.allocations.WithLabelValues("created").Inc()
+	s.metrics.active.Inc()
+	log.WithFields(log.OWI("", workspaceID, "")).WithField("relay", alloc.RelayAddr.String()).Debug("created TURN allocation")
+
+	go s.relayFromPeers(alloc)
+	s.replyAllocated(msg, alloc, key)
+}
+
+func (s *TURNServer) replyAllocated(msg *stunMessage, alloc *turnAllocation, key []byte) {
+	s.mu.Lock()
+	lifetime := alloc.Expires.Sub(s.now())
+	s.mu.Unlock()
+	b := newSTUNBuilder(stunMethodAllocate, stunClassSuccess, msg.TransactionID).
+		AddAddress(stunAttrXORRelayedAddress, alloc.RelayAddr).
+		AddAddress(stunAttrXORMappedAddress, alloc.Client).
+		AddUint32(stunAttrLifetime, uint32(lifetime/time.Second))
+	s.reply(alloc.Client, b, key)
+}
+
+// turnAllocationLifetime returns the lifetime a client requested, within the bounds we permit
+func turnAllocationLifetime(msg *stunMessage) time.Duration {
+	val, ok := msg.Get(stunAttrLifetime)
+	if !ok || len(val) != 4 {
+		return turnDefaultAllocationLifetime
+	}
+	lifetime := time.Duration(binary.BigEndian.Uint32(val)) * time.Second
+	if lifetime > turnMaxAllocationLifetime {
+		return turnMaxAllocationLifetime
+	}
+	if lifetime < turnDefaultAllocationLifetime {
+		return turnDefaultAllocationLifetime
+	}
+	return lifetime
+}
+
+// listenRelay opens the socket of a relayed address. Callers must hold mu.
+func (s *TURNServer) listenRelay() (*net.UDPConn, error) {
+	network := "udp6"
+	if s.publicIP.To4() != nil {
+		network = "udp4"
+	}
+	if s.Config.RelayPortStart == 0 {
+		return net.ListenUDP(network, &net.UDPAddr{})
+	}
+
+	var (
+		size  = int(s.Config.RelayPortEnd) - int(s.Config.RelayPortStart) + 1
+		first = rand.Intn(size)
+	)
+	for i := 0; i < size; i++ {
+		port := int(s.Config.RelayPortStart) + (first+i)%size
+		if _, used := s.relayPorts[port]; used {
+			continue
+		}
+		conn, err := net.ListenUDP(network, &net.UDPAddr{Port: port})
+		if err != nil {
+			continue
+		}
+		return conn, nil
+	}
+	return nil, xerrors.Errorf("no relay port available")
+}
+
+func (s *TURNServer) handleRefresh(msg *stunMessage, alloc *turnAllocation, key []byte) {
+	lifetime := turnAllocationLifetime(msg)
+	if val, ok := msg.Get(stunAttrLifetime); ok && len(val) == 4 && binary.BigEndian.Uint32(val) == 0 {
+		lifetime = 0
+	}
+
+	if lifetime == 0 {
+		s.mu.Lock()
+		s.deleteAllocation(alloc)
+		s.mu.Unlock()
+	} else {
+		s.mu.Lock()
+		alloc.Expires = s.now().Add(lifetime)
+		s.mu.Unlock()
+	}
+	s.reply(alloc.Client, newSTUNBuilder(stunMethodRefresh, stunClassSuccess, msg.TransactionID).AddUint32(stunAttrLifetime, uint32(lifetime/time.Second)), key)
+}
+
+// peerAddresses decodes the XOR-PEER-ADDRESS attributes of a request and returns a STUN error code if we must not relay to them
+func (s *TURNServer) peerAddresses(msg *stunMessage) (peers []*net.UDPAddr, code int, reason string) {
+	for _, a := range msg.Attrs {
+		if a.Type != stunAttrXORPeerAddress {
+			continue
+		}
+		peer, err := decodeSTUNXORAddress(a.Value, msg.TransactionID[:])
+		if err != nil {
+			return nil, 400, "Bad Request"
+		}
+		if (peer.IP.To4() != nil) != (s.publicIP.To4() != nil) {
+			return nil, 443, "Peer Address Family Mismatch"
+		}
+		if !s.peerAllowed(peer.IP) {
+			return nil, 403, "Forbidden"
+		}
+		peers = append(peers, peer)
+	}
+	if len(peers) == 0 {
+		return nil, 400, "Bad Request"
+	}
+	return peers, 0, ""
+}
+
+// peerAllowed returns true if we may relay traffic to and from an IP
+func (s *TURNServer) peerAllowed(ip net.IP) bool {
+	for _, n := range s.denied {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *TURNServer) handleCreatePermission(msg *stunMessage, alloc *turnAllocation, key []byte) {
+	peers, code, reason := s.peerAddresses(msg)
+	if code != 0 {
+		s.replyError(alloc.Client, msg, code, reason, key)
+		return
+	}
+
+	s.mu.Lock()
+	for _, peer := range peers {
+		alloc.permissions[peer.IP.String()] = s.now().Add(turnPermissionLifetime)
+	}
+	s.mu.Unlock()
+	s.reply(alloc.Client, newSTUNBuilder(stunMethodCreatePermission, stunClassSuccess, msg.TransactionID), key)
+}
+
+func (s *TURNServer) handleChannelBind(msg *stunMessage, alloc *turnAllocation, key []byte) {
+	val, ok := msg.Get(stunAttrChannelNumber)
+	if !ok || len(val) != 4 {
+		s.replyError(alloc.Client, msg, 400, "Bad Request", key)
+		return
+	}
+	channel := binary.BigEndian.Uint16(val)
+	if channel < 0x4000 || channel > 0x7FFF {
+		s.replyError(alloc.Client, msg, 400, "Bad Request", key)
+		return
+	}
+	peers, code, reason := s.peerAddresses(msg)
+	if code != 0 {
+		s.replyError(alloc.Client, msg, code, reason, key)
+		return
+	}
+	peer := peers[0]
+
+	s.mu.Lock()
+	if bound, exists := alloc.channels[channel]; exists && bound.Peer.String() != peer.String() {
+		s.mu.Unlock()
+		s.replyError(alloc.Client, msg, 400, "Bad Request", key)
+		return
+	}
+	if bound, exists := alloc.peers[peer.String()]; exists && bound != channel {
+		s.mu.Unlock()
+		s.replyError(alloc.Client, msg, 400, "Bad Request", key)
+		return
+	}
+	alloc.channels[channel] = &turnChannel{Peer: peer, Expires: s.now().Add(turnChannelLifetime)}
+	alloc.peers[peer.String()] = channel
+	alloc.permissions[peer.IP.String()] = s.now().Add(turnPermissionLifetime)
+	s.mu.Unlock()
+
+	s.reply(alloc.Client, newSTUNBuilder(stunMethodChannelBind, stunClassSuccess, msg.TransactionID), key)
+}
+
+// handleSend relays the data of a Send indication to a peer the client has a permission for
+func (s *TURNServer) handleSend(msg *stunMessage, client *net.UDPAddr) {
+	val, ok := msg.Get(stunAttrXORPeerAddress)
+	if !ok {
+		return
+	}
+	peer, err := decodeSTUNXORAddress(val, msg.TransactionID[:])
+	if err != nil {
+		return
+	}
+	data, ok := msg.Get(stunAttrData)
+	if !ok {
+		return
+	}
+	s.relayToPeer(client, peer, data)
+}
+
+// handleChannelData relays a ChannelData message to the peer bound to its channel
+func (s *TURNServer) handleChannelData(b []byte, client *net.UDPAddr) {
+	if len(b) < 4 {
+		return
+	}
+	channel := binary.BigEndian.Uint16(b[0:2])
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if channel < 0x4000 || channel > 0x7FFF || 4+length > len(b) {
+		return
+	}
+
+	s.mu.Lock()
+	var peer *net.UDPAddr
+	if alloc, ok := s.allocations[client.String()]; ok {
+		if c, ok := alloc.channels[channel]; ok {
+			peer = c.Peer
+		}
+	}
+	s.mu.Unlock()
+	if peer == nil {
+		return
+	}
+	s.relayToPeer(client, peer, b[4:4+length])
+}
+
+func (s *TURNServer) relayToPeer(client, peer *net.UDPAddr, data []byte) {
+	s.mu.Lock()
+	alloc, ok := s.allocations[client.String()]
+	var permitted bool
+	if ok {
+		expires, exists := alloc.permissions[peer.IP.String()]
+		permitted = exists && s.now().Before(expires)
+	}
+	s.mu.Unlock()
+	if !permitted {
+		return
+	}
+
+	n, err := alloc.Relay.WriteToUDP(data, peer)
+	if err != nil {
+		log.WithError(err).WithFields(log.OWI("", alloc.WorkspaceID, "")).Debug("cannot relay data to TURN peer")
+		return
+	}
+	s.metrics.bytes.WithLabelValues("to_peer").Add(float64(n))
+}
+
+// relayFromPeers forwards the traffic peers send to a relayed address to the client, until the allocation is deleted
+func (s *TURNServer) relayFromPeers(alloc *turnAllocation) {
+	buf := make([]byte, turnMaxDatagram)
+	for {
+		n, peer, err := alloc.Relay.ReadFromUDP(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+
+		s.mu.Lock()
+		expires, permitted := alloc.permissions[peer.IP.String()]
+		permitted = permitted && s.now().Before(expires)
+		channel, bound := alloc.peers[peer.String()]
+		if bound && s.now().After(alloc.channels[channel].Expires) {
+			bound = false
+		}
+		s.mu.Unlock()
+		if !permitted {
+			continue
+		}
+
+		var msg []byte
+		if bound {
+			msg = make([]byte, 4+n)
+			binary.BigEndian.PutUint16(msg[0:2], channel)
+			binary.BigEndian.PutUint16(msg[2:4], uint16(n))
+			copy(msg[4:], buf[:n])
+		} else {
+			var tid [12]byte
+			_, _ = rand.Read(tid[:])
+			msg = newSTUNBuilder(stunMethodData, stunClassIndication, tid).
+				AddAddress(stunAttrXORPeerAddress, peer).
+				Add(stunAttrData, buf[:n]).
+				Bytes(nil)
+		}
+		_, err = s.conn.WriteTo(msg, alloc.Client)
+		if err != nil {
+			log.WithError(err).WithFields(log.OWI("", alloc.WorkspaceID, "")).Debug("cannot relay data to TURN client")
+			continue
+		}
+		s.metrics.bytes.WithLabelValues("from_peer").Add(float64(n))
+	}
+}
+
+// deleteAllocation releases a relayed address. Callers must hold mu.
+func (s *TURNServer) deleteAllocation(alloc *turnAllocation) {
+	if s.allocations[alloc.Client.String()] != alloc {
+		return
+	}
+	delete(s.allocations, alloc.Client.String())
+	delete(s.relayPorts, alloc.RelayAddr.Port)
+	s.perWorkspace[alloc.WorkspaceID]--
+	if s.perWorkspace[alloc.WorkspaceID] <= 0 {
+		delete(s.perWorkspace, alloc.WorkspaceID)
+	}
+	_ = alloc.Relay.Close()
+	s.metrics.active.Dec()
+}
+
+// janitor expires allocations, permissions and channels, and releases the allocations of workspaces which are gone
+func (s *TURNServer) janitor(stop <-chan struct{}) {
+	ticker := time.NewTicker(turnJanitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		s.expire()
+	}
+}
+
+func (s *TURNServer) expire() {
+	s.mu.Lock()
+	now := s.now()
+	workspaces := make(map[string]struct{}, len(s.perWorkspace))
+	for _, alloc := range s.allocations {
+		if now.After(alloc.Expires) {
+			s.deleteAllocation(alloc)
+			continue
+		}
+		workspaces[alloc.WorkspaceID] = struct{}{}
+		for ip, expires := range alloc.permissions {
+			if now.After(expires) {
+				delete(alloc.permissions, ip)
+			}
+		}
+		for channel, c := range alloc.channels {
+			if now.After(c.Expires) {
+				delete(alloc.channels, channel)
+				delete(alloc.peers, c.Peer.String())
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for id := range workspaces {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		info := s.InfoProvider.WorkspaceInfo(ctx, id)
+		cancel()
+		if info != nil {
+			continue
+		}
+		s.mu.Lock()
+		for _, alloc := range s.allocations {
+			if alloc.WorkspaceID == id {
+				s.deleteAllocation(alloc)
+			}
+		}
+		s.mu.Unlock()
+		log.WithFields(log.OWI("", id, "")).Debug("released TURN allocations of stopped workspace")
+	}
+}
+
+// Credentials produces TURN credentials for a workspace
+func (s *TURNServer) Credentials(workspaceID string) *TURNCredentials {
+	ttl := time.Duration(s.Config.CredentialTTL)
+	username := fmt.Sprintf("%d:%s", s.now().Add(ttl).Unix(), workspaceID)
+
+	urls := s.Config.URLs
+	if len(urls) == 0 {
+		_, port, _ := net.SplitHostPort(s.Config.ListenAddr)
+		urls = []string{fmt.Sprintf("turn:%s?transport=udp", net.JoinHostPort(s.publicIP.String(), port))}
+	}
+	return &TURNCredentials{
+		URLs:       urls,
+		Username:   username,
+		Credential: s.password(username),
+		TTL:        int64(ttl / time.Second),
+	}
+}
+
+// ServeHTTP hands out TURN credentials for the workspace (POST). It must run behind the workspace owner authentication.
+func (s *TURNServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	coords := getWorkspaceCoords(req)
+	if coords.ID == "" {
+		http.Error(resp, "no workspace", http.StatusNotFound)
+		return
+	}
+	if req.Method != http.MethodPost {
+		resp.Header().Set("Allow", "POST")
+		http.Error(resp, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	creds := s.Credentials(coords.ID)
+	log.WithFields(log.OWI("", coords.ID, "")).Debug("issued TURN credentials")
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(resp).Encode(creds)
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTURNServer(t *testing.T, infoProvider WorkspaceInfoProvider) (*TURNServer, *net.UDPAddr) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secret, []byte(strings.Repeat("s", 32)+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewTURNServer(TURNConfig{
+		ListenAddr:                 "127.0.0.1:3478",
+		PublicIP:                   "127.0.0.1",
+		SecretFile:                 secret,
+		MaxAllocationsPerWorkspace: 1,
+		// the peers of the test live on the loopback interface
+		DeniedPeerNetworks: []string{"10.0.0.0/8"},
+	}, infoProvider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go s.serve(conn)
+	return s, conn.LocalAddr().(*net.UDPAddr)
+}
+
+// turnTestClient is a TURN client which speaks just enough TURN for the tests
+type turnTestClient struct {
+	t      *testing.T
+	conn   *net.UDPConn
+	server *net.UDPAddr
+	tid    byte
+
+	username, realm, nonce string
+	key                    []byte
+}
+
+func newTURNTestClient(t *testing.T, server *net.UDPAddr) *turnTestClient {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &turnTestClient{t: t, conn: conn, server: server}
+}
+
+func (c *turnTestClient) builder(m stunMethod, class stunClass) *stunBuilder {
+	c.tid++
+	var tid [12]byte
+	tid[11] = c.tid
+	b := newSTUNBuilder(m, class, tid)
+	if c.key != nil && class == stunClassRequest {
+		b.Add(stunAttrUsername, []byte(c.username)).Add(stunAttrRealm, []byte(c.realm)).Add(stunAttrNonce, []byte(c.nonce))
+	}
+	return b
+}
+
+func (c *turnTestClient) send(b []byte) {
+	_, err := c.conn.WriteToUDP(b, c.server)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *turnTestClient) read() []byte {
+	_ = c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, turnMaxDatagram)
+	n, _, err := c.conn.ReadFromUDP(buf)
+	if err != nil {
+		c.t.Fatalf("no message from TURN server: %v", err)
+	}
+	return buf[:n]
+}
+
+func (c *turnTestClient) request(b *stunBuilder) *stunMessage {
+	c.send(b.Bytes(c.key))
+	msg, err := parseSTUN(c.read())
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if msg.Class == stunClassSuccess && c.key != nil && !msg.VerifyIntegrity(c.key) {
+		c.t.Errorf("response to %s has invalid message integrity", msg.Method)
+	}
+	return msg
+}
+
+// login answers the 401 challenge of the server with the credentials
+func (c *turnTestClient) login(creds *TURNCredentials) {
+	msg := c.request(c.builder(stunMethodAllocate, stunClassRequest))
+	if code := stunErrorCode(msg); code != 401 {
+		c.t.Fatalf("expected unauthenticated request to be challenged, got %d", code)
+	}
+	realm, _ := msg.Get(stunAttrRealm)
+	nonce, _ := msg.Get(stunAttrNonce)
+	c.username, c.realm, c.nonce = creds.Username, string(realm), string(nonce)
+	c.key = turnKey(creds.Username, c.realm, creds.Credential)
+}
+
+func stunErrorCode(msg *stunMessage) int {
+	if msg.Class != stunClassError {
+		return 0
+	}
+	val, ok := msg.Get(stunAttrErrorCode)
+	if !ok || len(val) < 4 {
+		return -1
+	}
+	return int(val[2])*100 + int(val[3])
+}
+
+func requestedTransportUDP() []byte {
+	return []byte{turnTransportUDP, 0, 0, 0}
+}
+
+func TestTURNRelay(t *testing.T) {
+	ws := workspaces[0]
+	s, addr := newTestTURNServer(t, &fakeWsInfoProvider{infos: []WorkspaceInfo{ws}})
+	client := newTURNTestClient(t, addr)
+	client.login(s.Credentials(ws.WorkspaceID))
+
+	msg := client.request(client.builder(stunMethodAllocate, stunClassRequest).Add(stunAttrRequestedTransport, requestedTransportUDP()))
+	if msg.Class != stunClassSuccess {
+		t.Fatalf("cannot allocate: %d", stunErrorCode(msg))
+	}
+	val, _ := msg.Get(stunAttrXORRelayedAddress)
+	relay, err := decodeSTUNXORAddress(val, msg.TransactionID[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, _ = msg.Get(stunAttrXORMappedAddress)
+	mapped, err := decodeSTUNXORAddress(val, msg.TransactionID[:])
+	if err != nil || mapped.String() != client.conn.LocalAddr().String() {
+		t.Errorf("unexpected mapped address %v: %v", mapped, err)
+	}
+
+	peer, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	peerAddr := peer.LocalAddr().(*net.UDPAddr)
+	readPeer := func() []byte {
+		_ = peer.SetReadDeadline(time.Now().Add(2 * time.Second))
+		buf := make([]byte, 1500)
+		n, from, err := peer.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatalf("peer did not receive data: %v", err)
+		}
+		if from.Port != relay.Port {
+			t.Errorf("expected data from the relayed address %s, got %s", relay, from)
+		}
+		return buf[:n]
+	}
+
+	msg = client.request(client.builder(stunMethodCreatePermission, stunClassRequest).AddAddress(stunAttrXORPeerAddress, &net.UDPAddr{IP: net.IPv4(10, 1, 2, 3), Port: 4242}))
+	if code := stunErrorCode(msg); code != 403 {
+		t.Errorf("expected peers in denied networks to be forbidden, got %d", code)
+	}
+	msg = client.request(client.builder(stunMethodCreatePermission, stunClassRequest).AddAddress(stunAttrXORPeerAddress, peerAddr))
+	if msg.Class != stunClassSuccess {
+		t.Fatalf("cannot create permission: %d", stunErrorCode(msg))
+	}
+
+	// client -> peer using a Send indication
+	client.send(client.builder(stunMethodSend, stunClassIndication).AddAddress(stunAttrXORPeerAddress, peerAddr).Add(stunAttrData, []byte("hello peer")).Bytes(nil))
+	if data := readPeer(); string(data) != "hello peer" {
+		t.Errorf("unexpected data at peer: %q", data)
+	}
+
+	// peer -> client using a Data indication
+	_, err = peer.WriteToUDP([]byte("hello client"), &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: relay.Port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err = parseSTUN(client.read())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := msg.Get(stunAttrData)
+	if msg.Method != stunMethodData || string(data) != "hello client" {
+		t.Errorf("unexpected data indication: %s %q", msg.Method, data)
+	}
+
+	msg = client.request(client.builder(stunMethodChannelBind, stunClassRequest).AddUint32(stunAttrChannelNumber, 0x40000000).AddAddress(stunAttrXORPeerAddress, peerAddr))
+	if msg.Class != stunClassSuccess {
+		t.Fatalf("cannot bind channel: %d", stunErrorCode(msg))
+	}
+
+	// client -> peer using ChannelData
+	channelData := make([]byte, 4, 4+len("via channel"))
+	binary.BigEndian.PutUint16(channelData[0:2], 0x4000)
+	binary.BigEndian.PutUint16(channelData[2:4], uint16(len("via channel")))
+	client.send(append(channelData, "via channel"...))
+	if data := readPeer(); string(data) != "via channel" {
+		t.Errorf("unexpected channel data at peer: %q", data)
+	}
+
+	// peer -> client using ChannelData
+	_, err = peer.WriteToUDP([]byte("back via channel"), &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: relay.Port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := client.read(); binary.BigEndian.Uint16(data[0:2]) != 0x4000 || !bytes.Equal(data[4:], []byte("back via channel")) {
+		t.Errorf("unexpected channel data at client: %q", data)
+	}
+
+	// the workspace may hold a single allocation only
+	other := newTURNTestClient(t, addr)
+	other.login(s.Credentials(ws.WorkspaceID))
+	msg = other.request(other.builder(stunMethodAllocate, stunClassRequest).Add(stunAttrRequestedTransport, requestedTransportUDP()))
+	if code := stunErrorCode(msg); code != 486 {
+		t.Errorf("expected allocation quota to be enforced, got %d", code)
+	}
+
+	msg = client.request(client.builder(stunMethodRefresh, stunClassRequest).AddUint32(stunAttrLifetime, 0))
+	if msg.Class != stunClassSuccess {
+		t.Fatalf("cannot delete allocation: %d", stunErrorCode(msg))
+	}
+	s.mu.Lock()
+	remaining := len(s.allocations)
+	s.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("expected allocation to be deleted, %d remain", remaining)
+	}
+	msg = other.request(other.builder(stunMethodAllocate, stunClassRequest).Add(stunAttrRequestedTransport, requestedTransportUDP()))
+	if msg.Class != stunClassSuccess {
+		t.Errorf("expected the released quota to be available again, got %d", stunErrorCode(msg))
+	}
+}
+
+func TestTURNAuthentication(t *testing.T) {
+	ws := workspaces[0]
+	s, addr := newTestTURNServer(t, &fakeWsInfoProvider{infos: []WorkspaceInfo{ws}})
+
+	tests := []struct {
+		Name        string
+		Credentials func() *TURNCredentials
+		Nonce       func(string) string
+		Code        int
+	}{
+		{
+			Name:        "unknown workspace",
+			Credentials: func() *TURNCredentials { return s.Credentials("foobar") },
+			Code:        403,
+		},
+		{
+			Name: "expired credentials",
+			Credentials: func() *TURNCredentials {
+				creds := s.Credentials(ws.WorkspaceID)
+				creds.Username = fmt.Sprintf("%d:%s", time.Now().Add(-time.Minute).Unix(), ws.WorkspaceID)
+				creds.Credential = s.password(creds.Username)
+				return creds
+			},
+			Code: 401,
+		},
+		{
+			Name: "credentials of another workspace",
+			Credentials: func() *TURNCredentials {
+				creds := s.Credentials(ws.WorkspaceID)
+				creds.Username = strings.Replace(creds.Username, ws.WorkspaceID, "foobar", 1)
+				return creds
+			},
+			Code: 401,
+		},
+		{
+			Name:        "forged nonce",
+			Credentials: func() *TURNCredentials { return s.Credentials(ws.WorkspaceID) },
+			Nonce:       func(n string) string { return "ffffffffff" + n[strings.Index(n, "-"):] },
+			Code:        438,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client := newTURNTestClient(t, addr)
+			client.login(test.Credentials())
+			if test.Nonce != nil {
+				client.nonce = test.Nonce(client.nonce)
+			}
+			msg := client.request(client.builder(stunMethodAllocate, stunClassRequest).Add(stunAttrRequestedTransport, requestedTransportUDP()))
+			if code := stunErrorCode(msg); code != test.Code {
+				t.Errorf("unexpected error code: want %d, got %d", test.Code, code)
+			}
+		})
+	}
+}
+
+func TestTURNCredentialsRoute(t *testing.T) {
+	ws := workspaces[0]
+	infoProvider := &fakeWsInfoProvider{infos: []WorkspaceInfo{ws}}
+	s, _ := newTestTURNServer(t, infoProvider)
+
+	p := NewWorkspaceProxy(":8080", config, HostBasedRouter(hostBasedHeader, wsHostSuffix, nil), infoProvider)
+	p.TURN = s
+	handler, err := p.Handler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve := func(req *http.Request) (int, string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		body, _ := io.ReadAll(rec.Result().Body)
+		return rec.Code, string(body)
+	}
+
+	code, _ := serve(modifyRequest(httptest.NewRequest("POST", ws.URL+"_wsproxy/turn-credentials", nil), addHostHeader))
+	if code != http.StatusUnauthorized {
+		t.Errorf("expected others to be unable to obtain credentials, got %d", code)
+	}
+	code, body := serve(modifyRequest(httptest.NewRequest("POST", ws.URL+"_wsproxy/turn-credentials", nil), addHostHeader, addOwnerToken(ws.InstanceID, ws.Auth.OwnerToken)))
+	if code != http.StatusOK {
+		t.Fatalf("cannot obtain credentials: %d %s", code, body)
+	}
+	var creds TURNCredentials
+	err = json.Unmarshal([]byte(body), &creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(creds.Username, ":"+ws.WorkspaceID) || creds.Credential != s.password(creds.Username) {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+	if len(creds.URLs) != 1 || creds.URLs[0] != "turn:127.0.0.1:3478?transport=udp" {
+		t.Errorf("unexpected TURN URLs: %v", creds.URLs)
+	}
+}
@@ -0,0 +1,671 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+const (
+	upgradeSocketPrefix = "ws-proxy-"
+	upgradeSocketSuffix = ".sock"
+
+	defaultHandoverTimeout = 30 * time.Second
+	// maxHandoverFDs is the number of listeners we send per message. The kernel limits SCM_RIGHTS to 253 file descriptors.
+	maxHandoverFDs = 200
+)
+
+// UpgradeConfig configures zero-downtime upgrades of ws-proxy. A new ws-proxy process - be it a new binary or the same
+// binary with a new configuration - takes the listener sockets over from the running one, which then stops accepting
+// connections and drains the ones it has, e.g. WebSockets, instead of breaking them.
+type UpgradeConfig struct {
+	// SocketDir is where ws-proxy offers its listeners to its successor. Both processes must see the same directory,
+	// e.g. a hostPath volume if the successor runs in another pod on the same node.
+	SocketDir string `json:"socketDir"`
+	// DrainTimeout is the time we give the connections of a replaced ws-proxy to finish before it stops
+	DrainTimeout util.Duration `json:"drainTimeout"`
+	// HandoverTimeout is the time a successor has to take the listeners over and serve them. Defaults to 30 seconds.
+	HandoverTimeout util.Duration `json:"handoverTimeout,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *UpgradeConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.SocketDir, validation.Required),
+		validation.Field(&c.DrainTimeout, validation.Required, validation.Min(util.Duration(time.Second))),
+		validation.Field(&c.HandoverTimeout, validation.Min(util.Duration(0))),
+	)
+}
+
+// UpgradeStatus describes the state of an upgrade
+type UpgradeStatus struct {
+	// Successor is the process ID of the ws-proxy we started to replace us, if any
+	Successor int `json:"successor,omitempty"`
+	// Replaced is true once a successor took our listeners over and we drain
+	Replaced bool `json:"replaced"`
+	// Connections is the number of open client connections
+	Connections int64 `json:"connections"`
+}
+
+// upgradeHandover is the message which accompanies the listeners we hand over
+type upgradeHandover struct {
+	Addrs []string `json:"addrs"`
+	More  bool     `json:"more"`
+}
+
+// upgradeReady is the message a successor sends once it serves the listeners
+type upgradeReady struct {
+	Ready bool `json:"ready"`
+}
+
+// UpgradeController coordinates upgrades between ws-proxy processes. All listeners it creates can be bound by more
+// than one process (SO_REUSEPORT), and are handed over to the successor through a Unix socket so that no connection
+// in the accept queue gets lost. A nil controller listens on its own and never hands over.
+type UpgradeController struct {
+	Config UpgradeConfig
+
+	mu          sync.Mutex
+	inherited   map[string]*net.TCPListener
+	listeners   map[string]*net.TCPListener
+	addrs       []string
+	servers     []*http.Server
+	predecessor *net.UnixConn
+	offer       net.Listener
+	successor   *os.Process
+
+	active   int64
+	replaced chan struct{}
+	drained  chan struct{}
+	once     sync.Once
+}
+
+// NewUpgradeController creates a new upgrade controller
+func NewUpgradeController(cfg UpgradeConfig) *UpgradeController {
+	if cfg.HandoverTimeout == 0 {
+		cfg.HandoverTimeout = util.Duration(defaultHandoverTimeout)
+	}
+	return &UpgradeController{
+		Config:    cfg,
+		inherited: make(map[string]*net.TCPListener),
+		listeners: make(map[string]*net.TCPListener),
+		replaced:  make(chan struct{}),
+		drained:   make(chan struct{}),
+	}
+}
+
+// Inherit takes the listeners over from the ws-proxy which offers them most recently in the socket directory.
+// It returns the number of listeners it received, which is zero if there is no ws-proxy to take over from.
+// Call Ready once we serve the listeners so that the predecessor starts draining.
+func (u *UpgradeController) Inherit(ctx context.Context) (int, error) {
+	fn, err := latestUpgradeSocket(u.Config.SocketDir)
+	if err != nil || fn == "" {
+		return 0, err
+	}
+
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "unixpacket", fn)
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		// nobody listens on the socket anymore, e.g. because its ws-proxy crashed
+		log.WithField("socket", fn).Warn("removing stale upgrade socket")
+		_ = os.Remove(fn)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, xerrors.Errorf("cannot connect to upgrade socket %s: %w", fn, err)
+	}
+	conn := c.(*net.UnixConn)
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetReadDeadline(dl)
+	}
+
+	lns, err := receiveListeners(conn)
+	if err != nil {
+		conn.Close()
+		return 0, err
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for addr, ln := range lns {
+		u.inherited[addr] = ln
+	}
+	u.predecessor = conn
+	log.WithField("socket", fn).WithField("listeners", len(lns)).Info("took listeners over from the running ws-proxy")
+	return len(lns), nil
+}
+
+// Listen opens a listener on each address, or uses the one we inherited for it. If one of them fails, all others are closed again.
+func (u *UpgradeController) Listen(addrs []string) ([]net.Listener, error) {
+	if u == nil {
+		return listen(addrs)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	res := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		ln, ok := u.inherited[addr]
+		if ok {
+			delete(u.inherited, addr)
+		} else {
+			var err error
+			ln, err = listenReusePort(addr)
+			if err != nil {
+				for _, l := range res {
+					l.Close()
+				}
+				return nil, xerrors.Errorf("cannot listen on %s: %w", addr, err)
+			}
+		}
+		if _, exists := u.listeners[addr]; !exists {
+			u.addrs = append(u.addrs, addr)
+		}
+		u.listeners[addr] = ln
+		res = append(res, &upgradeListener{Listener: ln, u: u})
+	}
+	return res, nil
+}
+
+// RegisterServer makes the controller shut the server down gracefully once we are replaced
+func (u *UpgradeController) RegisterServer(srv *http.Server) {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.servers = append(u.servers, srv)
+}
+
+// Ready tells the predecessor that we serve its listeners, closes the inherited listeners we do not need,
+// e.g. because the new configuration dropped their address, and offers our listeners to our own successor.
+func (u *UpgradeController) Ready() error {
+	u.mu.Lock()
+	for addr, ln := range u.inherited {
+		log.WithField("addr", addr).Info("closing inherited listener which the configuration no longer uses")
+		ln.Close()
+		delete(u.inherited, addr)
+	}
+	predecessor := u.predecessor
+	u.predecessor = nil
+	u.mu.Unlock()
+
+	if predecessor != nil {
+		msg, _ := json.Marshal(upgradeReady{Ready: true})
+		_, err := predecessor.Write(msg)
+		predecessor.Close()
+		if err != nil {
+			return xerrors.Errorf("cannot tell the previous ws-proxy that we are ready: %w", err)
+		}
+	}
+
+	err := os.MkdirAll(u.Config.SocketDir, 0700)
+	if err != nil {
+		return xerrors.Errorf("cannot create upgrade socket directory: %w", err)
+	}
+	fn := filepath.Join(u.Config.SocketDir, fmt.Sprintf("%s%d-%d%s", upgradeSocketPrefix, time.Now().UnixNano(), os.Getpid(), upgradeSocketSuffix))
+	offer, err := net.Listen("unixpacket", fn)
+	if err != nil {
+		return xerrors.Errorf("cannot create upgrade socket: %w", err)
+	}
+	u.mu.Lock()
+	u.offer = offer
+	u.mu.Unlock()
+	go u.serveHandovers(offer)
+	log.WithField("socket", fn).Info("offering listeners to a successor")
+	return nil
+}
+
+// Upgrade starts a new ws-proxy process with the same arguments. The new process reads the binary and the configuration
+// from disk, takes our listeners over and makes us drain once it serves them. Its output goes to our output.
+func (u *UpgradeController) Upgrade() (*UpgradeStatus, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.isReplaced() {
+		return nil, xerrors.Errorf("this ws-proxy was replaced already")
+	}
+	if u.successor != nil {
+		return nil, xerrors.Errorf("upgrade in progress: successor %d is starting", u.successor.Pid)
+	}
+	if u.offer == nil {
+		return nil, xerrors.Errorf("this ws-proxy does not offer its listeners yet")
+	}
+
+	// we look the binary up again rather than using /proc/self/exe, which points to the binary we were started from
+	bin, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return nil, xerrors.Errorf("cannot find ws-proxy binary: %w", err)
+	}
+	cmd := exec.Command(bin, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	err = cmd.Start()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot start successor: %w", err)
+	}
+	u.successor = cmd.Process
+	log.WithField("pid", cmd.Process.Pid).WithField("binary", bin).Info("started successor")
+
+	go func() {
+		err := cmd.Wait()
+		if u.isReplaced() {
+			return
+		}
+		log.WithError(err).WithField("pid", cmd.Process.Pid).Error("successor exited before it took over - continuing to serve")
+		u.mu.Lock()
+		u.successor = nil
+		u.mu.Unlock()
+	}()
+	return u.status(), nil
+}
+
+// ActAsInit keeps a replaced ws-proxy running as PID 1 of its container, which would stop with it otherwise, until
+// all successors have stopped. It forwards signals to the successors, reaps them and returns the exit code of the last one.
+func (u *UpgradeController) ActAsInit() int {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT, syscall.SIGUSR2)
+	defer signal.Stop(sigs)
+	go func() {
+		for sig := range sigs {
+			// -1 signals all processes of the container but PID 1, i.e. the successors and their successors
+			_ = syscall.Kill(-1, sig.(syscall.Signal))
+		}
+	}()
+
+	var code int
+	for {
+		var ws syscall.WaitStatus
+		_, err := syscall.Wait4(-1, &ws, 0, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			// ECHILD - there is no successor left
+			return code
+		}
+		code = ws.ExitStatus()
+	}
+}
+
+// Status describes the state of the upgrade
+func (u *UpgradeController) Status() *UpgradeStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.status()
+}
+
+func (u *UpgradeController) status() *UpgradeStatus {
+	res := &UpgradeStatus{
+		Replaced:    u.isReplaced(),
+		Connections: atomic.LoadInt64(&u.active),
+	}
+	if u.successor != nil {
+		res.Successor = u.successor.Pid
+	}
+	return res
+}
+
+// Replaced is closed once a successor took our listeners over
+func (u *UpgradeController) Replaced() <-chan struct{} {
+	if u == nil {
+		return nil
+	}
+	return u.replaced
+}
+
+// Drained is closed once all connections of a replaced ws-proxy are closed, or the drain timeout passed
+func (u *UpgradeController) Drained() <-chan struct{} {
+	if u == nil {
+		return nil
+	}
+	return u.drained
+}
+
+// IsReplaced returns true if a successor took our listeners over. Listeners fail once we are replaced - that is expected.
+func (u *UpgradeController) IsReplaced() bool {
+	if u == nil {
+		return false
+	}
+	return u.isReplaced()
+}
+
+func (u *UpgradeController) isReplaced() bool {
+	select {
+	case <-u.replaced:
+		return true
+	default:
+		return false
+	}
+}
+
+func (u *UpgradeController) serveHandovers(offer net.Listener) {
+	for {
+		c, err := offer.Accept()
+		if err != nil {
+			if !u.isReplaced() {
+				log.WithError(err).Error("upgrade socket failed - we cannot be upgraded anymore")
+			}
+			return
+		}
+
+		conn := c.(*net.UnixConn)
+		err = u.handover(conn)
+		conn.Close()
+		if err != nil {
+			log.WithError(err).Warn("listener handover failed - continuing to serve")
+			continue
+		}
+
+		u.drain()
+		return
+	}
+}
+
+// handover sends our listeners to a successor and waits until it serves them
+func (u *UpgradeController) handover(conn *net.UnixConn) error {
+	_ = conn.SetDeadline(time.Now().Add(time.Duration(u.Config.HandoverTimeout)))
+
+	u.mu.Lock()
+	addrs := make([]string, len(u.addrs))
+	copy(addrs, u.addrs)
+	lns := make([]*net.TCPListener, len(addrs))
+	for i, addr := range addrs {
+		lns[i] = u.listeners[addr]
+	}
+	u.mu.Unlock()
+
+	err := sendListeners(conn, addrs, lns)
+	if err != nil {
+		return err
+	}
+	log.WithField("listeners", len(addrs)).Info("handed listeners over - waiting for the successor to serve them")
+
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return xerrors.Errorf("successor did not become ready: %w", err)
+	}
+	var ready upgradeReady
+	err = json.Unmarshal(buf[:n], &ready)
+	if err != nil || !ready.Ready {
+		return xerrors.Errorf("successor did not become ready: %s", string(buf[:n]))
+	}
+	return nil
+}
+
+// drain stops accepting connections and waits for the open ones to close or the drain timeout to pass
+func (u *UpgradeController) drain() {
+	u.once.Do(func() {
+		close(u.replaced)
+		log.WithField("connections", atomic.LoadInt64(&u.active)).Info("successor took over - draining")
+
+		u.mu.Lock()
+		if u.offer != nil {
+			u.offer.Close()
+		}
+		servers := u.servers
+		lns := make([]*net.TCPListener, 0, len(u.listeners))
+		for _, ln := range u.listeners {
+			lns = append(lns, ln)
+		}
+		u.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(u.Config.DrainTimeout))
+		// Shutdown stops the HTTP servers from accepting and closes their idle connections
+		for _, srv := range servers {
+			go func(srv *http.Server) {
+				_ = srv.Shutdown(ctx)
+			}(srv)
+		}
+		// the successor holds copies of the listeners, hence closing ours does not drop any connection
+		for _, ln := range lns {
+			ln.Close()
+		}
+
+		go func() {
+			defer cancel()
+			defer close(u.drained)
+
+			// Shutdown does not wait for hijacked connections like WebSockets, hence we count them ourselves
+			t := time.NewTicker(time.Second)
+			defer t.Stop()
+			for atomic.LoadInt64(&u.active) > 0 {
+				select {
+				case <-ctx.Done():
+					log.WithField("connections", atomic.LoadInt64(&u.active)).Warn("drain timeout passed - closing remaining connections")
+					return
+				case <-t.C:
+				}
+			}
+			log.Info("all connections drained")
+		}()
+	})
+}
+
+// latestUpgradeSocket returns the most recent upgrade socket in dir, or an empty string if there is none
+func latestUpgradeSocket(dir string) (string, error) {
+	fs, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", xerrors.Errorf("cannot read upgrade socket directory: %w", err)
+	}
+	var fn string
+	for _, f := range fs {
+		if f.Type()&os.ModeSocket == 0 || !strings.HasPrefix(f.Name(), upgradeSocketPrefix) || !strings.HasSuffix(f.Name(), upgradeSocketSuffix) {
+			continue
+		}
+		if f.Name() > fn {
+			fn = f.Name()
+		}
+	}
+	if fn == "" {
+		return "", nil
+	}
+	return filepath.Join(dir, fn), nil
+}
+
+// sendListeners sends copies of the listeners' file descriptors in batches, each accompanied by the addresses of its listeners
+func sendListeners(conn *net.UnixConn, addrs []string, lns []*net.TCPListener) error {
+	for start := 0; start == 0 || start < len(lns); start += maxHandoverFDs {
+		end := start + maxHandoverFDs
+		if end > len(lns) {
+			end = len(lns)
+		}
+
+		var (
+			files = make([]*os.File, 0, end-start)
+			fds   = make([]int, 0, end-start)
+		)
+		closeFiles := func() {
+			for _, f := range files {
+				f.Close()
+			}
+		}
+		for _, ln := range lns[start:end] {
+			f, err := ln.File()
+			if err != nil {
+				closeFiles()
+				return xerrors.Errorf("cannot get listener file descriptor: %w", err)
+			}
+			files = append(files, f)
+			fds = append(fds, int(f.Fd()))
+		}
+
+		msg, err := json.Marshal(upgradeHandover{Addrs: addrs[start:end], More: end < len(lns)})
+		if err != nil {
+			closeFiles()
+			return err
+		}
+		var oob []byte
+		if len(fds) > 0 {
+			oob = syscall.UnixRights(fds...)
+		}
+		_, _, err = conn.WriteMsgUnix(msg, oob, nil)
+		closeFiles()
+		if err != nil {
+			return xerrors.Errorf("cannot send listeners: %w", err)
+		}
+		if end == len(lns) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// receiveListeners receives the listeners sendListeners sends
+func receiveListeners(conn *net.UnixConn) (map[string]*net.TCPListener, error) {
+	res := make(map[string]*net.TCPListener)
+	closeAll := func() {
+		for _, ln := range res {
+			ln.Close()
+		}
+	}
+
+	buf := make([]byte, 64*1024)
+	oob := make([]byte, syscall.CmsgSpace(4*maxHandoverFDs))
+	for {
+		n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			closeAll()
+			return nil, xerrors.Errorf("cannot receive listeners: %w", err)
+		}
+
+		var fds []int
+		if oobn > 0 {
+			msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+			if err != nil {
+				closeAll()
+				return nil, xerrors.Errorf("cannot parse listeners: %w", err)
+			}
+			for i := range msgs {
+				f, err := syscall.ParseUnixRights(&msgs[i])
+				if err != nil {
+					closeAll()
+					return nil, xerrors.Errorf("cannot parse listeners: %w", err)
+				}
+				fds = append(fds, f...)
+			}
+		}
+
+		var msg upgradeHandover
+		err = json.Unmarshal(buf[:n], &msg)
+		if err == nil && len(msg.Addrs) != len(fds) {
+			err = xerrors.Errorf("received %d listeners for %d addresses", len(fds), len(msg.Addrs))
+		}
+		if err != nil {
+			for _, fd := range fds {
+				syscall.Close(fd)
+			}
+			closeAll()
+			return nil, xerrors.Errorf("invalid handover message: %w", err)
+		}
+
+		for i, fd := range fds {
+			f := os.NewFile(uintptr(fd), msg.Addrs[i])
+			ln, err := net.FileListener(f)
+			// FileListener duplicates the file descriptor
+			f.Close()
+			if err != nil {
+				closeAll()
+				return nil, xerrors.Errorf("cannot use listener for %s: %w", msg.Addrs[i], err)
+			}
+			tcpLn, ok := ln.(*net.TCPListener)
+			if !ok {
+				ln.Close()
+				closeAll()
+				return nil, xerrors.Errorf("listener for %s is no TCP listener", msg.Addrs[i])
+			}
+			res[msg.Addrs[i]] = tcpLn
+		}
+		if !msg.More {
+			return res, nil
+		}
+	}
+}
+
+// listenReusePort listens on addr such that other processes can listen on it, too. The kernel distributes new
+// connections between all of them, which lets a successor listen on addresses we do not hand over.
+func listenReusePort(addr string) (*net.TCPListener, error) {
+	lc := net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			var serr error
+			err := c.Control(func(fd uintptr) {
+				serr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			})
+			if err != nil {
+				return err
+			}
+			return serr
+		},
+	}
+	ln, err := lc.Listen(context.Background(), listenNetwork(addr), addr)
+	if err != nil {
+		return nil, err
+	}
+	return ln.(*net.TCPListener), nil
+}
+
+// upgradeListener counts the connections it accepts until they are closed
+type upgradeListener struct {
+	net.Listener
+	u *UpgradeController
+}
+
+func (l *upgradeListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt64(&l.u.active, 1)
+	return &upgradeConn{Conn: conn, u: l.u}, nil
+}
+
+type upgradeConn struct {
+	net.Conn
+	u    *UpgradeController
+	once sync.Once
+}
+
+func (c *upgradeConn) Close() error {
+	c.once.Do(func() {
+		atomic.AddInt64(&c.u.active, -1)
+	})
+	return c.Conn.Close()
+}
+
+// CloseWrite passes half-closed connections on
+func (c *upgradeConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestUpgradeConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Cfg   *UpgradeConfig
+		Valid bool
+	}{
+		{Name: "nil", Valid: true},
+		{Name: "valid", Cfg: &UpgradeConfig{SocketDir: "/var/run/ws-proxy", DrainTimeout: util.Duration(time.Hour)}, Valid: true},
+		{Name: "no socket dir", Cfg: &UpgradeConfig{DrainTimeout: util.Duration(time.Hour)}},
+		{Name: "no drain timeout", Cfg: &UpgradeConfig{SocketDir: "/var/run/ws-proxy"}},
+		{Name: "negative handover timeout", Cfg: &UpgradeConfig{SocketDir: "/var/run/ws-proxy", DrainTimeout: util.Duration(time.Hour), HandoverTimeout: util.Duration(-time.Second)}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Cfg.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// startUpgradeTestProxy serves name on all paths but /ws, which holds the connection open like a WebSocket until the client closes it
+func startUpgradeTestProxy(t *testing.T, u *UpgradeController, addr, name string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, name)
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("cannot hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\n\r\n"))
+		_, _ = io.Copy(io.Discard, conn)
+	})
+
+	srv := &http.Server{Handler: mux}
+	u.RegisterServer(srv)
+	lns, err := u.Listen([]string{addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		err := srv.Serve(lns[0])
+		if err != nil && err != http.ErrServerClosed && !u.IsReplaced() {
+			t.Errorf("%s failed: %v", name, err)
+		}
+	}()
+	t.Cleanup(func() { srv.Close() })
+}
+
+func getUpgradeTestProxy(t *testing.T, addr string) string {
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}, Timeout: 5 * time.Second}
+	resp, err := client.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestUpgradeHandover(t *testing.T) {
+	cfg := UpgradeConfig{SocketDir: t.TempDir(), DrainTimeout: util.Duration(10 * time.Second), HandoverTimeout: util.Duration(5 * time.Second)}
+	const addr = "127.0.0.1:0"
+
+	predecessor := NewUpgradeController(cfg)
+	startUpgradeTestProxy(t, predecessor, addr, "predecessor")
+	err := predecessor.Ready()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var port string
+	for a, ln := range predecessor.listeners {
+		if a == addr {
+			port = ln.Addr().String()
+		}
+	}
+	if act := getUpgradeTestProxy(t, port); act != "predecessor" {
+		t.Fatalf("unexpected response before the upgrade: %q", act)
+	}
+
+	// a long-lived connection, e.g. a WebSocket, must survive the upgrade
+	ws, err := net.Dial("tcp", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	_, err = ws.Write([]byte("GET /ws HTTP/1.1\r\nHost: test\r\n\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ws.Read(make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	successor := NewUpgradeController(cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	n, err := successor.Inherit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected to inherit one listener, got %d", n)
+	}
+	startUpgradeTestProxy(t, successor, addr, "successor")
+	err = successor.Ready()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-predecessor.Replaced():
+	case <-time.After(5 * time.Second):
+		t.Fatal("predecessor was not replaced")
+	}
+	if act := getUpgradeTestProxy(t, port); act != "successor" {
+		t.Errorf("expected the successor to serve new connections on the same port, got %q", act)
+	}
+
+	select {
+	case <-predecessor.Drained():
+		t.Fatal("predecessor drained while a connection was open")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if status := predecessor.Status(); !status.Replaced || status.Connections != 1 {
+		t.Errorf("unexpected predecessor status: %+v", status)
+	}
+	_, err = ws.Write([]byte("still there"))
+	if err != nil {
+		t.Errorf("long-lived connection broke during the upgrade: %v", err)
+	}
+
+	ws.Close()
+	select {
+	case <-predecessor.Drained():
+	case <-time.After(5 * time.Second):
+		t.Fatal("predecessor did not drain after the last connection closed")
+	}
+
+	// the successor offers the listeners to its own successor
+	sock, err := latestUpgradeSocket(cfg.SocketDir)
+	if err != nil || sock == "" {
+		t.Errorf("successor does not offer its listeners: %q, %v", sock, err)
+	}
+}
+
+func TestUpgradeInheritWithoutPredecessor(t *testing.T) {
+	dir := t.TempDir()
+	u := NewUpgradeController(UpgradeConfig{SocketDir: filepath.Join(dir, "does-not-exist"), DrainTimeout: util.Duration(time.Second)})
+	n, err := u.Inherit(context.Background())
+	if err != nil || n != 0 {
+		t.Errorf("expected no listeners and no error without socket directory, got %d, %v", n, err)
+	}
+
+	// a socket nobody listens on anymore
+	stale := filepath.Join(dir, upgradeSocketPrefix+"1-1"+upgradeSocketSuffix)
+	ln, err := net.Listen("unixpacket", stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+
+	u = NewUpgradeController(UpgradeConfig{SocketDir: dir, DrainTimeout: util.Duration(time.Second)})
+	n, err = u.Inherit(context.Background())
+	if err != nil || n != 0 {
+		t.Errorf("expected no listeners and no error with a stale socket, got %d, %v", n, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected stale socket to be removed: %v", err)
+	}
+}
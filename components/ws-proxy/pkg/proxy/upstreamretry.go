@@ -0,0 +1,164 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+// UpstreamRetryConfig configures how we handle workspaces which do not accept connections yet, e.g. right after they started
+type UpstreamRetryConfig struct {
+	// Budget is the total time we spend retrying a request which was refused or reset by the workspace
+	Budget util.Duration `json:"budget"`
+	// InitialBackoff is the time we wait before the first retry. Every subsequent retry doubles the backoff.
+	InitialBackoff util.Duration `json:"initialBackoff"`
+	// MaxBackoff limits the backoff between retries. Defaults to the budget.
+	MaxBackoff util.Duration `json:"maxBackoff,omitempty"`
+	// UnhealthyPeriod is how long we consider an upstream unhealthy once it exhausted the budget of a request.
+	// While an upstream is unhealthy we fail its requests right away and serve the "workspace starting" page.
+	UnhealthyPeriod util.Duration `json:"unhealthyPeriod"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *UpstreamRetryConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Budget, validation.Required, validation.Min(util.Duration(0))),
+		validation.Field(&c.InitialBackoff, validation.Required, validation.Min(util.Duration(0)), validation.Max(c.Budget)),
+		validation.Field(&c.MaxBackoff, validation.Min(util.Duration(0))),
+		validation.Field(&c.UnhealthyPeriod, validation.Min(util.Duration(0))),
+	)
+}
+
+// errUpstreamStarting is returned for requests to upstreams which do not accept connections yet
+var errUpstreamStarting = xerrors.Errorf("workspace is starting")
+
+// UpstreamHealth remembers which upstreams recently refused our connections
+type UpstreamHealth struct {
+	mu        sync.Mutex
+	unhealthy map[string]time.Time
+}
+
+// NewUpstreamHealth creates a new upstream health tracker
+func NewUpstreamHealth() *UpstreamHealth {
+	return &UpstreamHealth{
+		unhealthy: make(map[string]time.Time),
+	}
+}
+
+// MarkUnhealthy marks an upstream unhealthy for a period of time
+func (h *UpstreamHealth) MarkUnhealthy(upstream string, period time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unhealthy[upstream] = time.Now().Add(period)
+}
+
+// MarkHealthy marks an upstream healthy
+func (h *UpstreamHealth) MarkHealthy(upstream string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.unhealthy, upstream)
+}
+
+// IsHealthy returns false if the upstream is marked unhealthy
+func (h *UpstreamHealth) IsHealthy(upstream string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	until, ok := h.unhealthy[upstream]
+	if !ok {
+		return true
+	}
+	if time.Now().After(until) {
+		delete(h.unhealthy, upstream)
+		return true
+	}
+	return false
+}
+
+// withUpstreamRetry retries requests to workspaces which do not accept connections yet
+func withUpstreamRetry(config *RouteHandlerConfig) proxyPassOpt {
+	return func(cfg *proxyPassConfig) {
+		if config.Config.UpstreamRetry == nil || config.UpstreamHealth == nil {
+			return
+		}
+		cfg.Transport = &upstreamRetryTransport{
+			transport: cfg.Transport,
+			Config:    *config.Config.UpstreamRetry,
+			Health:    config.UpstreamHealth,
+		}
+	}
+}
+
+// upstreamRetryTransport retries idempotent requests which the upstream refused or reset with exponential backoff,
+// and short-circuits requests to upstreams which recently exhausted the retry budget.
+type upstreamRetryTransport struct {
+	transport http.RoundTripper
+	Config    UpstreamRetryConfig
+	Health    *UpstreamHealth
+}
+
+func (t *upstreamRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	upstream := req.URL.Host
+	if !t.Health.IsHealthy(upstream) {
+		return nil, errUpstreamStarting
+	}
+
+	var (
+		retry    = !isWebsocketRequest(req) && isIdempotentRequest(req)
+		deadline = time.Now().Add(time.Duration(t.Config.Budget))
+		backoff  = time.Duration(t.Config.InitialBackoff)
+		maxWait  = time.Duration(t.Config.MaxBackoff)
+	)
+	if maxWait == 0 {
+		maxWait = time.Duration(t.Config.Budget)
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err == nil {
+			t.Health.MarkHealthy(upstream)
+			return resp, nil
+		}
+		if !isUpstreamStartingError(err) {
+			return nil, err
+		}
+
+		remaining := time.Until(deadline)
+		if !retry || remaining <= 0 {
+			t.Health.MarkUnhealthy(upstream, time.Duration(t.Config.UnhealthyPeriod))
+			getLog(req.Context()).WithError(err).WithField("attempts", attempt).Debug("upstream does not accept connections - marking it unhealthy")
+			return nil, xerrors.Errorf("%w: %v", errUpstreamStarting, err)
+		}
+
+		wait := backoff
+		if wait > remaining {
+			wait = remaining
+		}
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, err
+		}
+		backoff *= 2
+		if backoff > maxWait {
+			backoff = maxWait
+		}
+	}
+}
+
+// isUpstreamStartingError returns true if the upstream refused or reset our connection, as workspaces do while they start
+func isUpstreamStartingError(err error) bool {
+	return xerrors.Is(err, errUpstreamStarting) || xerrors.Is(err, syscall.ECONNREFUSED) || xerrors.Is(err, syscall.ECONNRESET)
+}
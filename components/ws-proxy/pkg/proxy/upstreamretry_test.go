@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+)
+
+func TestUpstreamRetryTransport(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	tests := []struct {
+		Name             string
+		Method           string
+		Failures         int
+		Err              error
+		ExpectedAttempts int
+		ExpectedStarting bool
+		ExpectedHealthy  bool
+	}{
+		{Name: "recovers within budget", Method: "GET", Failures: 2, Err: refused, ExpectedAttempts: 3, ExpectedHealthy: true},
+		{Name: "exhausts budget", Method: "GET", Failures: 1000, Err: refused, ExpectedStarting: true},
+		{Name: "non-idempotent request", Method: "POST", Failures: 1, Err: refused, ExpectedAttempts: 1, ExpectedStarting: true},
+		{Name: "other errors", Method: "GET", Failures: 1, Err: xerrors.Errorf("tls: bad certificate"), ExpectedAttempts: 1, ExpectedHealthy: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var attempts int
+			rt := &upstreamRetryTransport{
+				transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					attempts++
+					if attempts <= test.Failures {
+						return nil, test.Err
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+				}),
+				Config: UpstreamRetryConfig{
+					Budget:          util.Duration(50 * time.Millisecond),
+					InitialBackoff:  util.Duration(5 * time.Millisecond),
+					UnhealthyPeriod: util.Duration(time.Minute),
+				},
+				Health: NewUpstreamHealth(),
+			}
+
+			_, err := rt.RoundTrip(httptest.NewRequest(test.Method, "http://10.0.0.1:23000/", nil))
+			if starting := xerrors.Is(err, errUpstreamStarting); starting != test.ExpectedStarting {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.ExpectedAttempts > 0 && attempts != test.ExpectedAttempts {
+				t.Errorf("unexpected number of attempts: %d, expected %d", attempts, test.ExpectedAttempts)
+			}
+			if healthy := rt.Health.IsHealthy("10.0.0.1:23000"); healthy != test.ExpectedHealthy {
+				t.Errorf("unexpected upstream health: %v, expected %v", healthy, test.ExpectedHealthy)
+			}
+
+			// unhealthy upstreams are short-circuited
+			attempts = 0
+			_, err = rt.RoundTrip(httptest.NewRequest("GET", "http://10.0.0.1:23000/", nil))
+			if !test.ExpectedHealthy && (attempts != 0 || !xerrors.Is(err, errUpstreamStarting)) {
+				t.Errorf("request to unhealthy upstream was not short-circuited: %d attempts, %v", attempts, err)
+			}
+		})
+	}
+}
+
+func TestUpstreamHealth(t *testing.T) {
+	h := NewUpstreamHealth()
+	h.MarkUnhealthy("a", 20*time.Millisecond)
+	if h.IsHealthy("a") {
+		t.Error("upstream is healthy right after it was marked unhealthy")
+	}
+	if !h.IsHealthy("b") {
+		t.Error("unknown upstream is unhealthy")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !h.IsHealthy("a") {
+		t.Error("upstream is still unhealthy after the unhealthy period")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// VerifyWorkspaceID is the synthetic workspace `ws-proxy verify` checks an installation against. ws-proxy
+	// answers the requests for it itself, so that an installation can be verified without starting a workspace.
+	VerifyWorkspaceID = "wsproxy-verify-00000000"
+	// VerifyPath is the path ws-proxy answers on the hosts of the synthetic workspace
+	VerifyPath = "/_wsproxy/verify"
+
+	// websocketAcceptGUID is what RFC 6455 appends to the key of a websocket handshake
+	websocketAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// VerifyResponse describes how ws-proxy routed a request for the synthetic workspace
+type VerifyResponse struct {
+	// Route is the class of route which served the request, i.e. SLORouteClassIDE or SLORouteClassPort
+	Route       string `json:"route"`
+	WorkspaceID string `json:"workspaceID"`
+	Port        string `json:"port,omitempty"`
+	// Host is the host the request was for as ws-proxy saw it
+	Host string `json:"host"`
+}
+
+// WebsocketAccept computes the Sec-WebSocket-Accept value of a websocket handshake with key
+func WebsocketAccept(key string) string {
+	h := sha1.Sum([]byte(key + websocketAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// verifyHandler answers the requests for VerifyPath of the synthetic workspace and passes all others on.
+// Websocket upgrades are accepted and the connection closed right after the handshake.
+// Unless enabled, there is no synthetic workspace and all requests are passed on.
+func verifyHandler(enabled bool, route string) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		if !enabled {
+			return h
+		}
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != VerifyPath {
+				h.ServeHTTP(resp, req)
+				return
+			}
+			coords := getWorkspaceCoords(req)
+			if coords.ID != VerifyWorkspaceID {
+				h.ServeHTTP(resp, req)
+				return
+			}
+
+			if isWebsocketRequest(req) {
+				serveVerifyUpgrade(resp, req)
+				return
+			}
+
+			resp.Header().Set("Content-Type", "application/json")
+			resp.Header().Set("Cache-Control", "no-store")
+			err := json.NewEncoder(resp).Encode(VerifyResponse{
+				Route:       route,
+				WorkspaceID: coords.ID,
+				Port:        coords.Port,
+				Host:        req.Host,
+			})
+			if err != nil {
+				log.WithError(err).Debug("cannot write verify response")
+			}
+		})
+	}
+}
+
+func serveVerifyUpgrade(resp http.ResponseWriter, req *http.Request) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(resp, "Sec-WebSocket-Key is missing", http.StatusBadRequest)
+		return
+	}
+	hj, ok := resp.(http.Hijacker)
+	if !ok {
+		http.Error(resp, "connection cannot be upgraded", http.StatusInternalServerError)
+		return
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		log.WithError(err).Debug("cannot hijack verify connection")
+		return
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", WebsocketAccept(key))
+	err = brw.Flush()
+	if err != nil {
+		log.WithError(err).Debug("cannot write verify upgrade")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+)
+
+func TestVerifyHandler(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Disabled    bool
+		WorkspaceID string
+		Path        string
+		Expected    *VerifyResponse
+	}{
+		{Name: "synthetic workspace", WorkspaceID: VerifyWorkspaceID, Path: VerifyPath, Expected: &VerifyResponse{Route: SLORouteClassPort, WorkspaceID: VerifyWorkspaceID, Port: "8080", Host: "example.com"}},
+		{Name: "other path", WorkspaceID: VerifyWorkspaceID, Path: "/"},
+		{Name: "other workspace", WorkspaceID: "amaranth-smelt-9ba20cc1", Path: VerifyPath},
+		{Name: "disabled", Disabled: true, WorkspaceID: VerifyWorkspaceID, Path: VerifyPath},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var passed bool
+			handler := verifyHandler(!test.Disabled, SLORouteClassPort)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				passed = true
+			}))
+
+			req := httptest.NewRequest("GET", "http://example.com"+test.Path, nil)
+			req = mux.SetURLVars(req, map[string]string{workspaceIDIdentifier: test.WorkspaceID, workspacePortIdentifier: "8080"})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if test.Expected == nil {
+				if !passed {
+					t.Error("expected the request to be passed on")
+				}
+				return
+			}
+			if passed {
+				t.Error("expected the request to be answered")
+			}
+			var act VerifyResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &act)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expected, &act); diff != "" {
+				t.Errorf("unexpected response (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWebsocketAccept(t *testing.T) {
+	// the example of RFC 6455, section 1.3
+	if act := WebsocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); act != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("unexpected accept %q", act)
+	}
+}
@@ -0,0 +1,425 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	// WakeStarterServer starts workspaces using the Gitpod server API
+	WakeStarterServer = "server"
+	// WakeStarterWsManager starts workspaces using ws-manager directly
+	WakeStarterWsManager = "ws-manager"
+
+	defaultWakeMaxEntries = 10000
+	defaultWakeMaxAge     = 14 * 24 * time.Hour
+
+	// wakeTimeout is the time we give a workspace to start before we consider waking it failed
+	wakeTimeout = 10 * time.Minute
+	// wakePersistInterval is the interval in which we write the recently seen workspaces to disk
+	wakePersistInterval = 30 * time.Second
+	// wakeEventsPollInterval is the interval in which we check for progress when streaming events
+	wakeEventsPollInterval = 1 * time.Second
+
+	wakeEventsPath = "/_wake/events"
+)
+
+// WakeOnRequestConfig configures starting stopped workspaces when their owner tries to access them
+type WakeOnRequestConfig struct {
+	// Starter determines how workspaces are started. Either "server" or "ws-manager".
+	Starter string `json:"starter"`
+	// StateFile is where we remember the workspaces we've seen recently
+	StateFile string `json:"stateFile"`
+	// MaxEntries limits the number of workspaces we remember. Defaults to 10000.
+	MaxEntries int `json:"maxEntries,omitempty"`
+	// MaxAge is how long we remember a workspace after it was last running. Defaults to 14 days.
+	MaxAge util.Duration `json:"maxAge,omitempty"`
+	// ServerEndpoint is the Gitpod server API endpoint, e.g. wss://gitpod.io/api/gitpod. Required for the "server" starter.
+	ServerEndpoint string `json:"serverEndpoint,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *WakeOnRequestConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Starter == WakeStarterServer && c.ServerEndpoint == "" {
+		return xerrors.Errorf("invalid wake on request config: the server starter requires a serverEndpoint")
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.Starter, validation.Required, validation.In(WakeStarterServer, WakeStarterWsManager)),
+		validation.Field(&c.StateFile, validation.Required),
+		validation.Field(&c.MaxEntries, validation.Min(0)),
+		validation.Field(&c.MaxAge, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid wake on request config: %w", err)
+	}
+	return nil
+}
+
+// wakeState is the progress of a workspace we're waking
+type wakeState struct {
+	Started time.Time
+	Phase   wsapi.WorkspacePhase
+	Failure string
+	// InstanceID, OwnerToken are the new instance's coordinates once it's running
+	InstanceID string
+	OwnerToken string
+	// Auth are the params of the instance whose owner woke the workspace
+	Auth *wakeParams
+}
+
+func (s *wakeState) Ready() bool {
+	return s.Phase == wsapi.WorkspacePhase_RUNNING && s.Failure == ""
+}
+
+// WorkspaceWaker starts stopped workspaces when their owner tries to access them,
+// and shows a progress page until they're running.
+type WorkspaceWaker struct {
+	Config       WakeOnRequestConfig
+	Installation GitpodInstallation
+
+	store   *wakeStore
+	starter workspaceStarter
+	pages   *ErrorPages
+	stop    chan struct{}
+
+	mu     sync.Mutex
+	waking map[string]*wakeState
+}
+
+// NewWorkspaceWaker creates a new waker. Workspace status updates must be passed to Observe.
+func NewWorkspaceWaker(cfg WakeOnRequestConfig, proxyCfg *Config, wsman *RemoteWorkspaceInfoProvider) (*WorkspaceWaker, error) {
+	if cfg.MaxEntries == 0 {
+		cfg.MaxEntries = defaultWakeMaxEntries
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = util.Duration(defaultWakeMaxAge)
+	}
+
+	var starter workspaceStarter
+	switch cfg.Starter {
+	case WakeStarterServer:
+		starter = &serverStarter{
+			Endpoint:      cfg.ServerEndpoint,
+			SessionCookie: sessionCookieName(proxyCfg.GitpodInstallation.HostName),
+		}
+	case WakeStarterWsManager:
+		starter = &wsManagerStarter{
+			Addr:   wsman.Config.WsManagerAddr,
+			Dialer: wsman.Dialer,
+		}
+	default:
+		return nil, xerrors.Errorf("unknown starter: %s", cfg.Starter)
+	}
+
+	pages, err := NewErrorPages(proxyCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	store := newWakeStore(cfg.StateFile, cfg.MaxEntries, time.Duration(cfg.MaxAge))
+	err = store.Load()
+	if err != nil {
+		log.WithError(err).Warn("cannot load recently seen workspaces - starting afresh")
+	}
+
+	return &WorkspaceWaker{
+		Config:       cfg,
+		Installation: *proxyCfg.GitpodInstallation,
+		store:        store,
+		starter:      starter,
+		pages:        pages,
+		stop:         make(chan struct{}),
+		waking:       make(map[string]*wakeState),
+	}, nil
+}
+
+// Start periodically persists the workspaces we've seen until Close is called
+func (w *WorkspaceWaker) Start() {
+	go func() {
+		t := time.NewTicker(wakePersistInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-t.C:
+			}
+
+			err := w.store.Persist()
+			if err != nil {
+				log.WithError(err).Warn("cannot persist recently seen workspaces")
+			}
+		}
+	}()
+}
+
+// Close stops the waker and persists the workspaces we've seen
+func (w *WorkspaceWaker) Close() error {
+	close(w.stop)
+	return w.store.Persist()
+}
+
+// Observe records workspace status updates
+func (w *WorkspaceWaker) Observe(status *wsapi.WorkspaceStatus) {
+	if status.Metadata == nil || status.Spec == nil {
+		return
+	}
+	if status.Phase == wsapi.WorkspacePhase_RUNNING && status.Spec.Type == wsapi.WorkspaceType_REGULAR {
+		if p := wakeParamsFromStatus(status, time.Now()); p != nil {
+			w.store.Remember(p)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	st, ok := w.waking[status.Metadata.MetaId]
+	if !ok || st.Failure != "" {
+		return
+	}
+	st.Phase = status.Phase
+	if status.Conditions != nil && status.Conditions.Failed != "" {
+		st.Failure = status.Conditions.Failed
+	} else if status.Phase == wsapi.WorkspacePhase_STOPPING || status.Phase == wsapi.WorkspacePhase_STOPPED {
+		st.Failure = "workspace stopped while starting"
+	}
+	if status.Phase == wsapi.WorkspacePhase_RUNNING && status.Auth != nil {
+		st.InstanceID = status.Id
+		st.OwnerToken = status.Auth.OwnerToken
+	}
+}
+
+// state returns a copy of the wake state of a workspace, or nil if we're not waking it
+func (w *WorkspaceWaker) state(workspaceID string) *wakeState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	st, ok := w.waking[workspaceID]
+	if !ok {
+		return nil
+	}
+	if time.Since(st.Started) > wakeTimeout && !st.Ready() {
+		if st.Failure == "" {
+			st.Failure = "workspace did not start in time"
+		}
+	}
+	res := *st
+	return &res
+}
+
+// Handle deals with navigation requests to workspaces which are stopped or which we are waking.
+// It returns false if the request should be handled as if there was no waker.
+func (w *WorkspaceWaker) Handle(resp http.ResponseWriter, req *http.Request, workspaceID string, info *WorkspaceInfo) bool {
+	if !isNavigationRequest(req) {
+		return false
+	}
+
+	st := w.state(workspaceID)
+	if st != nil {
+		if !w.isOwner(req, st.Auth) {
+			return false
+		}
+		if st.Ready() && info != nil && info.InstanceID == st.InstanceID {
+			w.handOver(resp, req, workspaceID, st)
+			return true
+		}
+		if st.Failure != "" {
+			// reloading the page after a failure gives the owner a chance to try again
+			w.mu.Lock()
+			delete(w.waking, workspaceID)
+			w.mu.Unlock()
+		} else {
+			w.servePage(resp, req, workspaceID)
+			return true
+		}
+	}
+	if info != nil {
+		return false
+	}
+
+	params := w.store.Get(workspaceID)
+	if params == nil || !w.isOwner(req, params) {
+		return false
+	}
+
+	w.mu.Lock()
+	if _, waking := w.waking[workspaceID]; waking {
+		w.mu.Unlock()
+		w.servePage(resp, req, workspaceID)
+		return true
+	}
+	w.waking[workspaceID] = &wakeState{
+		Started: time.Now(),
+		Auth:    params,
+	}
+	w.mu.Unlock()
+
+	log.WithFields(log.OWI(params.Owner, workspaceID, "")).WithField("starter", w.Config.Starter).Info("waking workspace")
+	// The request's context ends once we've served the page. Starting the workspace must outlive the request.
+	startReq := req.Clone(context.Background())
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), wakeTimeout)
+		defer cancel()
+
+		err := w.starter.StartWorkspace(ctx, params, startReq)
+		if err == nil {
+			return
+		}
+
+		log.WithError(err).WithFields(log.OWI(params.Owner, workspaceID, "")).Warn("cannot wake workspace")
+		w.mu.Lock()
+		if st, ok := w.waking[workspaceID]; ok {
+			st.Failure = err.Error()
+		}
+		w.mu.Unlock()
+	}()
+
+	w.servePage(resp, req, workspaceID)
+	return true
+}
+
+// isOwner checks if the request carries the owner cookie of the workspace instance described by params
+func (w *WorkspaceWaker) isOwner(req *http.Request, params *wakeParams) bool {
+	c, err := req.Cookie(ownerCookieName(w.Installation.HostName, params.InstanceID))
+	if err != nil {
+		return false
+	}
+	tkn, err := url.QueryUnescape(c.Value)
+	if err != nil {
+		return false
+	}
+	return params.IsOwnerToken(tkn)
+}
+
+// handOver gives the owner access to the new workspace instance. The owner proved their identity
+// using the previous instance's owner cookie, hence we can hand them the new one.
+func (w *WorkspaceWaker) handOver(resp http.ResponseWriter, req *http.Request, workspaceID string, st *wakeState) {
+	w.mu.Lock()
+	delete(w.waking, workspaceID)
+	w.mu.Unlock()
+
+	http.SetCookie(resp, &http.Cookie{
+		Name:     ownerCookieName(w.Installation.HostName, st.InstanceID),
+		Value:    url.QueryEscape(st.OwnerToken),
+		Path:     "/",
+		Domain:   strings.TrimPrefix(w.Installation.WorkspaceHostSuffix, "."),
+		Secure:   w.Installation.Scheme == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(resp, req, req.RequestURI, http.StatusSeeOther)
+}
+
+func (w *WorkspaceWaker) servePage(resp http.ResponseWriter, req *http.Request, workspaceID string) {
+	// Path based routing strips a prefix from the URL path. The events endpoint lives behind the same prefix.
+	var prefix string
+	if u, err := url.ParseRequestURI(req.RequestURI); err == nil {
+		prefix = strings.TrimSuffix(u.Path, req.URL.Path)
+	}
+
+	resp.Header().Set("Cache-Control", "no-store")
+	w.pages.Serve(resp, req, ErrorPageWorkspaceStarting, http.StatusAccepted, ErrorPageData{
+		WorkspaceID: workspaceID,
+		EventsURL:   prefix + wakeEventsPath,
+	})
+}
+
+type wakeEvent struct {
+	Phase   string `json:"phase,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ServeEvents streams the progress of a workspace we're waking as server-sent events
+func (w *WorkspaceWaker) ServeEvents(resp http.ResponseWriter, req *http.Request) {
+	workspaceID := mux.Vars(req)[workspaceIDIdentifier]
+	st := w.state(workspaceID)
+	if st == nil || !w.isOwner(req, st.Auth) {
+		resp.WriteHeader(http.StatusNotFound)
+		return
+	}
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp.Header().Set("Content-Type", "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(http.StatusOK)
+
+	send := func(event string, data wakeEvent) {
+		fc, _ := json.Marshal(data)
+		fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", event, fc)
+		flusher.Flush()
+	}
+
+	var lastPhase wsapi.WorkspacePhase = -1
+	t := time.NewTicker(wakeEventsPollInterval)
+	defer t.Stop()
+	for {
+		st = w.state(workspaceID)
+		switch {
+		case st == nil:
+			// somebody else completed the hand-over already
+			send("ready", wakeEvent{})
+			return
+		case st.Failure != "":
+			send("failed", wakeEvent{Message: st.Failure})
+			return
+		case st.Ready():
+			send("ready", wakeEvent{})
+			return
+		case st.Phase != lastPhase:
+			send("phase", wakeEvent{Phase: st.Phase.String()})
+			lastPhase = st.Phase
+		}
+
+		select {
+		case <-req.Context().Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// isNavigationRequest returns true if the request was made by a browser navigating to a page
+func isNavigationRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	if mode := req.Header.Get("Sec-Fetch-Mode"); mode != "" {
+		return mode == "navigate"
+	}
+	return strings.Contains(req.Header.Get("Accept"), "text/html")
+}
+
+// ownerCookieName produces the name of the cookie which carries the owner token of a workspace instance
+func ownerCookieName(hostName, instanceID string) string {
+	prefix := hostName
+	for _, c := range []string{" ", "-", "."} {
+		prefix = strings.ReplaceAll(prefix, c, "_")
+	}
+	return fmt.Sprintf("_%s_ws_%s_owner_", prefix, instanceID)
+}
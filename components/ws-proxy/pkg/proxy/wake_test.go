@@ -0,0 +1,198 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+func TestWakeStore(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	fn := filepath.Join(t.TempDir(), "wake.json")
+
+	store := newWakeStore(fn, 2, time.Hour)
+	store.Remember(&wakeParams{WorkspaceID: "old", LastSeen: now.Add(-2 * time.Hour)})
+	store.Remember(&wakeParams{WorkspaceID: "a", LastSeen: now.Add(-2 * time.Minute)})
+	store.Remember(&wakeParams{WorkspaceID: "b", LastSeen: now.Add(-1 * time.Minute)})
+	store.Remember(&wakeParams{WorkspaceID: "c", LastSeen: now})
+	err := store.Persist()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := newWakeStore(fn, 2, time.Hour)
+	err = loaded.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var act []string
+	for _, id := range []string{"old", "a", "b", "c"} {
+		if loaded.Get(id) != nil {
+			act = append(act, id)
+		}
+	}
+	if diff := cmp.Diff([]string{"b", "c"}, act); diff != "" {
+		t.Errorf("unexpected workspaces (-want +got):\n%s", diff)
+	}
+
+	err = newWakeStore(filepath.Join(t.TempDir(), "missing.json"), 2, time.Hour).Load()
+	if err != nil {
+		t.Errorf("missing state file should not be an error: %v", err)
+	}
+}
+
+type fakeStarter struct {
+	mu      sync.Mutex
+	started []string
+	err     error
+}
+
+func (s *fakeStarter) StartWorkspace(ctx context.Context, params *wakeParams, req *http.Request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = append(s.started, params.WorkspaceID)
+	return s.err
+}
+
+func (s *fakeStarter) Started() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.started...)
+}
+
+func TestWorkspaceWaker(t *testing.T) {
+	const (
+		workspaceID = "amaranth-smelt-9ba20cc1"
+		oldInstance = "old-instance"
+		oldToken    = "old-token"
+		newInstance = "new-instance"
+		newToken    = "new-token"
+	)
+	installation := GitpodInstallation{
+		Scheme:              "https",
+		HostName:            "gitpod.io",
+		WorkspaceHostSuffix: ".ws.gitpod.io",
+	}
+
+	newWaker := func() (*WorkspaceWaker, *fakeStarter) {
+		starter := &fakeStarter{}
+		waker := &WorkspaceWaker{
+			Config:       WakeOnRequestConfig{Starter: WakeStarterWsManager},
+			Installation: installation,
+			store:        newWakeStore(filepath.Join(t.TempDir(), "wake.json"), 10, time.Hour),
+			starter:      starter,
+			pages: &ErrorPages{
+				pages:    map[ErrorPage]*template.Template{ErrorPageWorkspaceStarting: template.Must(template.New("").Parse("{{.EventsURL}}"))},
+				fallback: template.Must(template.New("").Parse("{{.Message}}")),
+			},
+			stop:   make(chan struct{}),
+			waking: make(map[string]*wakeState),
+		}
+		waker.Observe(&wsapi.WorkspaceStatus{
+			Id:       oldInstance,
+			Phase:    wsapi.WorkspacePhase_RUNNING,
+			Metadata: &wsapi.WorkspaceMetadata{MetaId: workspaceID, Owner: "owner"},
+			Spec:     &wsapi.WorkspaceSpec{Type: wsapi.WorkspaceType_REGULAR},
+			Auth:     &wsapi.WorkspaceAuthentication{OwnerToken: oldToken},
+		})
+		return waker, starter
+	}
+	newRequest := func(token string, navigate bool) *http.Request {
+		req := httptest.NewRequest("GET", "https://"+workspaceID+".ws.gitpod.io/", nil)
+		if navigate {
+			req.Header.Set("Sec-Fetch-Mode", "navigate")
+		} else {
+			req.Header.Set("Sec-Fetch-Mode", "cors")
+		}
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: ownerCookieName(installation.HostName, oldInstance), Value: url.QueryEscape(token)})
+		}
+		return req
+	}
+
+	t.Run("only the owner wakes", func(t *testing.T) {
+		waker, starter := newWaker()
+
+		for _, req := range []*http.Request{
+			newRequest("", true),
+			newRequest("wrong-token", true),
+			newRequest(oldToken, false),
+		} {
+			if waker.Handle(httptest.NewRecorder(), req, workspaceID, nil) {
+				t.Errorf("unexpected wake for %v", req.Header)
+			}
+		}
+		if waker.Handle(httptest.NewRecorder(), newRequest(oldToken, true), "unknown", nil) {
+			t.Errorf("unexpected wake of unknown workspace")
+		}
+		if s := starter.Started(); len(s) != 0 {
+			t.Errorf("unexpected starts: %v", s)
+		}
+	})
+
+	t.Run("wake and hand over", func(t *testing.T) {
+		waker, starter := newWaker()
+
+		rec := httptest.NewRecorder()
+		if !waker.Handle(rec, newRequest(oldToken, true), workspaceID, nil) {
+			t.Fatal("owner did not wake the workspace")
+		}
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("unexpected status code %d", rec.Code)
+		}
+		if body := rec.Body.String(); body != wakeEventsPath {
+			t.Errorf("unexpected events URL %q", body)
+		}
+
+		// a second request must not start the workspace again
+		waker.Handle(httptest.NewRecorder(), newRequest(oldToken, true), workspaceID, nil)
+		time.Sleep(50 * time.Millisecond)
+		if diff := cmp.Diff([]string{workspaceID}, starter.Started()); diff != "" {
+			t.Errorf("unexpected starts (-want +got):\n%s", diff)
+		}
+
+		waker.Observe(&wsapi.WorkspaceStatus{
+			Id:       newInstance,
+			Phase:    wsapi.WorkspacePhase_RUNNING,
+			Metadata: &wsapi.WorkspaceMetadata{MetaId: workspaceID, Owner: "owner"},
+			Spec:     &wsapi.WorkspaceSpec{Type: wsapi.WorkspaceType_REGULAR},
+			Auth:     &wsapi.WorkspaceAuthentication{OwnerToken: newToken},
+		})
+
+		rec = httptest.NewRecorder()
+		info := &WorkspaceInfo{WorkspaceID: workspaceID, InstanceID: newInstance}
+		if !waker.Handle(rec, newRequest(oldToken, true), workspaceID, info) {
+			t.Fatal("owner cookie was not handed over")
+		}
+		if rec.Code != http.StatusSeeOther {
+			t.Errorf("unexpected status code %d", rec.Code)
+		}
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("expected one cookie, got %d", len(cookies))
+		}
+		if c := cookies[0]; c.Name != ownerCookieName(installation.HostName, newInstance) || c.Value != newToken || c.Domain != "ws.gitpod.io" {
+			t.Errorf("unexpected cookie %v", c)
+		}
+
+		// once handed over, the new instance is served as usual
+		if waker.Handle(httptest.NewRecorder(), newRequest(oldToken, true), workspaceID, info) {
+			t.Errorf("waker handled request after hand-over")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// workspaceStarter starts a new instance of a workspace somebody tried to access
+type workspaceStarter interface {
+	// StartWorkspace starts the workspace. req is the request that made us start the workspace.
+	StartWorkspace(ctx context.Context, params *wakeParams, req *http.Request) error
+}
+
+// serverStarter starts workspaces using the Gitpod server API on behalf of the user
+type serverStarter struct {
+	Endpoint string
+	// SessionCookie is the name of the Gitpod session cookie
+	SessionCookie string
+}
+
+func (s *serverStarter) StartWorkspace(ctx context.Context, params *wakeParams, req *http.Request) error {
+	// We authenticate as the user who tried to access the workspace. Their browser sends the Gitpod
+	// session cookie along with all workspace requests.
+	c, err := req.Cookie(s.SessionCookie)
+	if err != nil {
+		return xerrors.Errorf("no Gitpod session present")
+	}
+
+	srv, err := gitpod.ConnectToServer(s.Endpoint, gitpod.ConnectToServerOpts{
+		Context: ctx,
+		Cookie:  c.String(),
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot connect to server: %w", err)
+	}
+	defer srv.Close()
+
+	_, err = srv.StartWorkspace(ctx, params.WorkspaceID, &gitpod.StartWorkspaceOptions{})
+	if err != nil {
+		return xerrors.Errorf("cannot start workspace: %w", err)
+	}
+	return nil
+}
+
+// sessionCookieName produces the name of the Gitpod session cookie for a Gitpod installation
+func sessionCookieName(hostName string) string {
+	name := hostName
+	for _, c := range []string{" ", "-", "."} {
+		name = strings.ReplaceAll(name, c, "_")
+	}
+	return "_" + name + "_"
+}
+
+// wsManagerStarter starts workspaces by talking to ws-manager directly.
+//
+// The workspace status does not contain all parameters a workspace was started with. Workspaces
+// started this way restore their last backup, but do not get the user's environment variables
+// and open the workspace root rather than the original checkout location.
+type wsManagerStarter struct {
+	Addr   string
+	Dialer WSManagerDialer
+}
+
+func (s *wsManagerStarter) StartWorkspace(ctx context.Context, params *wakeParams, req *http.Request) error {
+	conn, client, err := s.Dialer(s.Addr)
+	if err != nil {
+		return xerrors.Errorf("cannot connect to ws-manager: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = client.StartWorkspace(ctx, &wsapi.StartWorkspaceRequest{
+		Id:            uuid.New().String(),
+		ServicePrefix: params.WorkspaceID,
+		Metadata: &wsapi.WorkspaceMetadata{
+			Owner:  params.Owner,
+			MetaId: params.WorkspaceID,
+		},
+		Type: wsapi.WorkspaceType_REGULAR,
+		Spec: &wsapi.StartWorkspaceSpec{
+			WorkspaceImage: params.WorkspaceImage,
+			IdeImage:       params.IDEImage,
+			Timeout:        params.Timeout,
+			Admission:      params.Admission,
+			// content initialization prefers the workspace's last backup over the initializer
+			Initializer: &csapi.WorkspaceInitializer{
+				Spec: &csapi.WorkspaceInitializer_Empty{Empty: &csapi.EmptyInitializer{}},
+			},
+			CheckoutLocation:  ".",
+			WorkspaceLocation: ".",
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot start workspace: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// wakeParams is what we remember about a workspace so that we can start it again
+type wakeParams struct {
+	WorkspaceID    string               `json:"workspaceID"`
+	InstanceID     string               `json:"instanceID"`
+	Owner          string               `json:"owner"`
+	WorkspaceImage string               `json:"workspaceImage"`
+	IDEImage       string               `json:"ideImage"`
+	Timeout        string               `json:"timeout,omitempty"`
+	Admission      wsapi.AdmissionLevel `json:"admission,omitempty"`
+	// OwnerTokenHash is the SHA256 of the instance's owner token. We never store the token itself.
+	OwnerTokenHash string    `json:"ownerTokenHash"`
+	LastSeen       time.Time `json:"lastSeen"`
+}
+
+func wakeParamsFromStatus(status *wsapi.WorkspaceStatus, now time.Time) *wakeParams {
+	if status.Metadata == nil || status.Spec == nil || status.Auth == nil {
+		return nil
+	}
+
+	return &wakeParams{
+		WorkspaceID:    status.Metadata.MetaId,
+		InstanceID:     status.Id,
+		Owner:          status.Metadata.Owner,
+		WorkspaceImage: status.Spec.WorkspaceImage,
+		IDEImage:       status.Spec.IdeImage,
+		Timeout:        status.Spec.Timeout,
+		Admission:      status.Auth.Admission,
+		OwnerTokenHash: hashOwnerToken(status.Auth.OwnerToken),
+		LastSeen:       now,
+	}
+}
+
+func hashOwnerToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// IsOwnerToken returns true if token is the owner token of the instance these params were taken from
+func (p *wakeParams) IsOwnerToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashOwnerToken(token)), []byte(p.OwnerTokenHash)) == 1
+}
+
+// wakeStore remembers recently seen workspaces and persists them to a file
+type wakeStore struct {
+	Filename   string
+	MaxEntries int
+	MaxAge     time.Duration
+
+	mu     sync.Mutex
+	params map[string]*wakeParams
+	dirty  bool
+}
+
+type wakeStoreFile struct {
+	Workspaces []*wakeParams `json:"workspaces"`
+}
+
+func newWakeStore(filename string, maxEntries int, maxAge time.Duration) *wakeStore {
+	return &wakeStore{
+		Filename:   filename,
+		MaxEntries: maxEntries,
+		MaxAge:     maxAge,
+		params:     make(map[string]*wakeParams),
+	}
+}
+
+// Load reads the store's content from disk. A missing file is not an error.
+func (s *wakeStore) Load() error {
+	fc, err := os.ReadFile(s.Filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot read wake state: %w", err)
+	}
+
+	var f wakeStoreFile
+	err = json.Unmarshal(fc, &f)
+	if err != nil {
+		return xerrors.Errorf("cannot unmarshal wake state: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range f.Workspaces {
+		if p == nil || p.WorkspaceID == "" {
+			continue
+		}
+		s.params[p.WorkspaceID] = p
+	}
+	s.prune(time.Now())
+	return nil
+}
+
+// Remember records the start parameters of a workspace
+func (s *wakeStore) Remember(p *wakeParams) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.params[p.WorkspaceID] = p
+	s.dirty = true
+	if len(s.params) > s.MaxEntries {
+		s.prune(p.LastSeen)
+	}
+}
+
+// Get returns the start parameters of a workspace or nil if we don't know it
+func (s *wakeStore) Get(workspaceID string) *wakeParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.params[workspaceID]
+	if !ok || time.Since(p.LastSeen) > s.MaxAge {
+		return nil
+	}
+	return p
+}
+
+// prune removes all entries older than MaxAge and then the oldest entries until we're within MaxEntries.
+// Callers are expected to hold mu.
+func (s *wakeStore) prune(now time.Time) {
+	for id, p := range s.params {
+		if now.Sub(p.LastSeen) > s.MaxAge {
+			delete(s.params, id)
+			s.dirty = true
+		}
+	}
+	if len(s.params) <= s.MaxEntries {
+		return
+	}
+
+	ps := make([]*wakeParams, 0, len(s.params))
+	for _, p := range s.params {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].LastSeen.Before(ps[j].LastSeen) })
+	for _, p := range ps[:len(ps)-s.MaxEntries] {
+		delete(s.params, p.WorkspaceID)
+	}
+	s.dirty = true
+}
+
+// Persist writes the store's content to disk if it has changed since the last write
+func (s *wakeStore) Persist() (err error) {
+	defer func() {
+		if err != nil {
+			// try again next time
+			s.mu.Lock()
+			s.dirty = true
+			s.mu.Unlock()
+		}
+	}()
+
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	s.prune(time.Now())
+	f := wakeStoreFile{Workspaces: make([]*wakeParams, 0, len(s.params))}
+	for _, p := range s.params {
+		f.Workspaces = append(f.Workspaces, p)
+	}
+	s.dirty = false
+	s.mu.Unlock()
+
+	sort.Slice(f.Workspaces, func(i, j int) bool { return f.Workspaces[i].WorkspaceID < f.Workspaces[j].WorkspaceID })
+	fc, err := json.Marshal(f)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal wake state: %w", err)
+	}
+
+	// write to a temporary file first so that we never leave a half-written state behind
+	tmp, err := os.CreateTemp(filepath.Dir(s.Filename), ".wake-state-*")
+	if err != nil {
+		return xerrors.Errorf("cannot write wake state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(fc)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot write wake state: %w", err)
+	}
+	err = os.Rename(tmp.Name(), s.Filename)
+	if err != nil {
+		return xerrors.Errorf("cannot write wake state: %w", err)
+	}
+	return nil
+}
@@ -5,15 +5,19 @@
 package proxy
 
 import (
+	"net"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gorilla/mux"
 	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/namegen"
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
 )
 
 const (
@@ -32,9 +36,9 @@ const (
 	// Used to communicate router error happening in the matcher with the error handler which set the code to the HTTP response
 	routerErrorCode = "routerErrorCode"
 
-	// This pattern matches v4 UUIDs as well as the new generated workspace ids (e.g. pink-panda-ns35kd21)
-	workspaceIDRegex   = "(?P<" + workspaceIDIdentifier + ">[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|[0-9a-z]{2,16}-[0-9a-z]{2,16}-[0-9a-z]{8})"
-	workspacePortRegex = "(?P<" + workspacePortIdentifier + ">[0-9]+)-"
+	// This pattern matches v4 UUIDs as well as the generated workspace ids (e.g. pink-panda-ns35kd21 or acme-pink-panda-ns35kd21).
+	// ws-manager validates workspace IDs against the same expression so that every workspace it starts can be routed.
+	workspaceIDRegex = "(?P<" + workspaceIDIdentifier + ">" + namegen.WorkspaceIDExpr + ")"
 )
 
 // WorkspaceRouter is a function that configures subrouters (one for theia, one for the exposed ports) on the given router
@@ -42,14 +46,28 @@ const (
 // with the keys workspacePortIdentifier and workspaceIDIdentifier
 type WorkspaceRouter func(r *mux.Router, wsInfoProvider WorkspaceInfoProvider) (theiaRouter *mux.Router, portRouter *mux.Router, blobserveRouter *mux.Router)
 
-// HostBasedRouter is a WorkspaceRouter that routes simply based on the "Host" header
-func HostBasedRouter(header, wsHostSuffix string) WorkspaceRouter {
+// HostBasedRouter is a WorkspaceRouter that routes simply based on the "Host" header. If foreign is not nil,
+// foreign content is served from the hosts it configures only.
+func HostBasedRouter(header, wsHostSuffix string, foreign *ForeignContentConfig) WorkspaceRouter {
 	return func(r *mux.Router, wsInfoProvider WorkspaceInfoProvider) (*mux.Router, *mux.Router, *mux.Router) {
+		scheme := wsurl.Scheme{HostSuffix: wsHostSuffix}
+		if foreign != nil {
+			scheme.ForeignHostSuffix = foreign.HostSuffix
+			scheme.ForeignPrefixes = foreign.Prefixes
+		}
+
+		var (
+			getHostHeader  = func(req *http.Request) string { return req.Header.Get(header) }
+			matchWorkspace = matchWorkspaceHost(scheme, false, getHostHeader)
+			matchPort      = matchWorkspaceHost(scheme, true, getHostHeader)
+		)
+		// port tokens address a workspace port by path on the workspace host
+		matchPort = matchFirst(matchPortTokenHost(wsHostSuffix, getHostHeader), matchPort)
+
 		var (
-			getHostHeader   = func(req *http.Request) string { return req.Header.Get(header) }
 			blobserveRouter = r.MatcherFunc(matchBlobserveHostHeader(wsHostSuffix, getHostHeader)).Subrouter()
-			portRouter      = r.MatcherFunc(matchWorkspacePortHostHeader(wsHostSuffix, getHostHeader)).Subrouter()
-			theiaRouter     = r.MatcherFunc(matchWorkspaceHostHeader(wsHostSuffix, getHostHeader)).Subrouter()
+			portRouter      = r.MatcherFunc(matchPort).Subrouter()
+			theiaRouter     = r.MatcherFunc(matchWorkspace).Subrouter()
 		)
 
 		r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -64,83 +82,136 @@ func HostBasedRouter(header, wsHostSuffix string) WorkspaceRouter {
 type hostHeaderProvider func(req *http.Request) string
 
 func matchWorkspaceHostHeader(wsHostSuffix string, headerProvider hostHeaderProvider) mux.MatcherFunc {
-	r := regexp.MustCompile("^(webview-|browser-|extensions-)?" + workspaceIDRegex + wsHostSuffix)
+	return matchWorkspaceHost(wsurl.Scheme{HostSuffix: wsHostSuffix}, false, headerProvider)
+}
+
+func matchWorkspacePortHostHeader(wsHostSuffix string, headerProvider hostHeaderProvider) mux.MatcherFunc {
+	return matchWorkspaceHost(wsurl.Scheme{HostSuffix: wsHostSuffix}, true, headerProvider)
+}
+
+// matchWorkspaceHost matches the hosts of the scheme which serve a workspace port if port is true, or the workspace itself otherwise
+func matchWorkspaceHost(scheme wsurl.Scheme, port bool, headerProvider hostHeaderProvider) mux.MatcherFunc {
+	routes := newHostRoutes(scheme, maxHostRoutes)
 	return func(req *http.Request, m *mux.RouteMatch) bool {
 		hostname := headerProvider(req)
 		if hostname == "" {
 			return false
 		}
 
-		matches := r.FindStringSubmatch(hostname)
-		if len(matches) < 3 {
+		route := routes.Get(hostname)
+		if route == nil {
 			return false
 		}
-
-		workspaceID := matches[2]
-		if workspaceID == "" {
+		if port != (route.Port != "") {
 			return false
 		}
 
 		if m.Vars == nil {
 			m.Vars = make(map[string]string)
 		}
-		m.Vars[workspaceIDIdentifier] = workspaceID
-		if len(matches) == 3 {
-			m.Vars[foreignOriginPrefix] = matches[1]
+		m.Vars[workspaceIDIdentifier] = route.WorkspaceID
+		if port {
+			m.Vars[workspacePortIdentifier] = route.Port
 		}
+		m.Vars[foreignOriginPrefix] = route.ForeignOriginPrefix
 		return true
 	}
 }
 
-func matchWorkspacePortHostHeader(wsHostSuffix string, headerProvider hostHeaderProvider) mux.MatcherFunc {
-	r := regexp.MustCompile("^(webview-|browser-|extensions-)?" + workspacePortRegex + workspaceIDRegex + wsHostSuffix)
-	return func(req *http.Request, m *mux.RouteMatch) bool {
-		hostname := headerProvider(req)
-		if hostname == "" {
-			return false
-		}
+// maxHostRoutes limits the number of hosts a hostRoutes remembers
+const maxHostRoutes = 16384
 
-		matches := r.FindStringSubmatch(hostname)
-		if len(matches) < 4 {
-			return false
-		}
+// hostRoute is a workspace host in the form the router needs it
+type hostRoute struct {
+	WorkspaceID         string
+	Port                string
+	ForeignOriginPrefix string
+}
 
-		workspaceID := matches[3]
-		if workspaceID == "" {
-			return false
-		}
+// hostRoutes remembers the routes of the workspace hosts we have seen, so that routing a request neither parses
+// nor allocates once we have seen its host. Parsing a host yields the same route every time, hence the routes
+// never go stale. Lookups of remembered hosts only take the read lock.
+//
+// To bound the memory we keep two generations of at most limit/2 routes each. Once the recent generation is full,
+// it becomes the old one and we forget the previous old one. Looking up a host of the old generation moves it to
+// the recent one, hence hosts which keep receiving requests survive, while hosts nobody uses anymore are forgotten.
+// Unlike copy-on-write, remembering a host never copies the routes: anyone can make up hosts which parse.
+type hostRoutes struct {
+	scheme wsurl.Scheme
+	limit  int
+
+	mu     sync.RWMutex
+	recent map[string]*hostRoute
+	old    map[string]*hostRoute
+}
 
-		workspacePort := matches[2]
-		if workspacePort == "" {
-			return false
-		}
+func newHostRoutes(scheme wsurl.Scheme, limit int) *hostRoutes {
+	if limit < 2 {
+		limit = 2
+	}
+	return &hostRoutes{
+		scheme: scheme,
+		limit:  limit,
+		recent: make(map[string]*hostRoute),
+	}
+}
 
-		if m.Vars == nil {
-			m.Vars = make(map[string]string)
-		}
-		m.Vars[workspaceIDIdentifier] = workspaceID
-		m.Vars[workspacePortIdentifier] = workspacePort
-		if len(matches) == 4 {
-			m.Vars[foreignOriginPrefix] = matches[1]
-		}
-		return true
+// Get returns the route of a host, or nil if the host is no workspace host
+func (r *hostRoutes) Get(hostname string) *hostRoute {
+	r.mu.RLock()
+	route, recent := r.recent[hostname]
+	if !recent {
+		route = r.old[hostname]
+	}
+	r.mu.RUnlock()
+	if recent {
+		return route
 	}
+	if route != nil {
+		r.remember(hostname, route)
+		return route
+	}
+
+	host, err := r.scheme.ParseHost(hostname)
+	if err != nil {
+		// we do not remember hosts which do not parse - anyone can make those up
+		return nil
+	}
+	route = &hostRoute{WorkspaceID: host.WorkspaceID}
+	if host.Port != 0 {
+		route.Port = strconv.Itoa(host.Port)
+	}
+	if host.ForeignPrefix != "" {
+		route.ForeignOriginPrefix = host.ForeignPrefix + "-"
+	}
+	r.remember(hostname, route)
+	return route
+}
+
+// remember adds a route to the recent generation, and starts a new generation if the recent one is full
+func (r *hostRoutes) remember(hostname string, route *hostRoute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.recent[hostname]; ok {
+		return
+	}
+	if len(r.recent) >= r.limit/2 {
+		r.old = r.recent
+		r.recent = make(map[string]*hostRoute, r.limit/2)
+	}
+	r.recent[hostname] = route
+	delete(r.old, hostname)
 }
 
 func matchBlobserveHostHeader(wsHostSuffix string, headerProvider hostHeaderProvider) mux.MatcherFunc {
-	r := regexp.MustCompile("^blobserve" + wsHostSuffix)
+	scheme := wsurl.Scheme{HostSuffix: wsHostSuffix}
 	return func(req *http.Request, m *mux.RouteMatch) bool {
 		hostname := headerProvider(req)
 		if hostname == "" {
 			return false
 		}
-
-		matches := r.FindStringSubmatch(hostname)
-		if len(matches) < 1 {
-			return false
-		}
-
-		return true
+		return scheme.IsBlobserveHost(hostname)
 	}
 }
 
@@ -160,6 +231,10 @@ func portBasedRouter(r *mux.Router, wsInfoProvider WorkspaceInfoProvider, routeP
 
 		coords := wsInfoProvider.WorkspaceCoords(publicPort)
 		if coords == nil {
+			if !infoProviderReady(wsInfoProvider) {
+				m.Vars[routerErrorCode] = "503"
+				return false
+			}
 			log.Debugf("no match for port request to: '%s' (host), '%s' (url)", req.Host, req.URL.String())
 			m.Vars[routerErrorCode] = "404"
 			return false
@@ -196,6 +271,10 @@ func portBasedRouter(r *mux.Router, wsInfoProvider WorkspaceInfoProvider, routeP
 			w.WriteHeader(502)
 			return
 		}
+		if code == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", notReadyRetryAfter)
+			w.Header().Set(proxyStatusHeader, string(ErrorPageProxyNotReady))
+		}
 		w.WriteHeader(code)
 	})
 
@@ -204,17 +283,19 @@ func portBasedRouter(r *mux.Router, wsInfoProvider WorkspaceInfoProvider, routeP
 
 // getPublicPortFromPortReq extracts the public port from requests to "exposed ports"
 func getPublicPortFromPortReq(req *http.Request) (string, error) {
-	parts := strings.SplitN(req.Host, ":", 2)
-	if len(parts) == 0 {
+	// SplitHostPort copes with IPv6 literals, e.g. [fd00::1]:10000
+	_, port, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		var addrErr *net.AddrError
+		if xerrors.As(err, &addrErr) && addrErr.Err == "missing port in address" {
+			return "", xerrors.Errorf("request without explicit port: %s", req.Host)
+		}
 		return "", xerrors.Errorf("request without proper host: %s", req.Host)
-	} else if len(parts) == 1 {
-		// only hostname, no port specified:
+	}
+	if port == "" {
 		return "", xerrors.Errorf("request without explicit port: %s", req.Host)
-	} else if len(parts) == 2 {
-		// "regular" <host>:<port>
-		return parts[1], nil
 	}
-	return "", xerrors.Errorf("request without proper host: %s", req.Host)
+	return port, nil
 }
 
 func getWorkspaceCoords(req *http.Request) WorkspaceCoords {
@@ -316,7 +397,7 @@ func PathAndHostRouter(trimPrefix, header, wsHostSuffix string) WorkspaceRouter
 	return func(r *mux.Router, wsInfoProvider WorkspaceInfoProvider) (theiaRouter *mux.Router, portRouter *mux.Router, blobserveRouter *mux.Router) {
 		theiaRouter = pathBasedTheiaRouter(r, wsInfoProvider, trimPrefix)
 		blobserveRouter = pathBasedBlobserveRouter(r)
-		_, portRouter, _ = HostBasedRouter(header, wsHostSuffix)(r, wsInfoProvider)
+		_, portRouter, _ = HostBasedRouter(header, wsHostSuffix, nil)(r, wsInfoProvider)
 		return
 	}
 }
@@ -5,13 +5,16 @@
 package proxy
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/gorilla/mux"
 
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
 	"github.com/gitpod-io/gitpod/ws-manager/api"
 )
 
@@ -39,7 +42,7 @@ func TestWorkspaceRouter(t *testing.T) {
 			Headers: map[string]string{
 				forwardedHostnameHeader: "amaranth-smelt-9ba20cc1.ws.gitpod.dev",
 			},
-			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix),
+			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix, nil),
 			WSHostSuffix: wsHostSuffix,
 			Expected: Expectation{
 				WorkspaceID: "amaranth-smelt-9ba20cc1",
@@ -53,7 +56,7 @@ func TestWorkspaceRouter(t *testing.T) {
 			Headers: map[string]string{
 				forwardedHostnameHeader: "1234-amaranth-smelt-9ba20cc1.ws.gitpod.dev",
 			},
-			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix),
+			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix, nil),
 			WSHostSuffix: wsHostSuffix,
 			Expected: Expectation{
 				WorkspaceID:   "amaranth-smelt-9ba20cc1",
@@ -62,13 +65,42 @@ func TestWorkspaceRouter(t *testing.T) {
 				URL:           "http://1234-amaranth-smelt-9ba20cc1.ws.gitpod.dev/",
 			},
 		},
+		{
+			Name: "host-based workspace access with custom ID scheme",
+			URL:  "http://acme-amaranth-smelt-9ba20cc1.ws.gitpod.dev/",
+			Headers: map[string]string{
+				forwardedHostnameHeader: "acme-amaranth-smelt-9ba20cc1.ws.gitpod.dev",
+			},
+			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix, nil),
+			WSHostSuffix: wsHostSuffix,
+			Expected: Expectation{
+				WorkspaceID: "acme-amaranth-smelt-9ba20cc1",
+				Status:      http.StatusOK,
+				URL:         "http://acme-amaranth-smelt-9ba20cc1.ws.gitpod.dev/",
+			},
+		},
+		{
+			Name: "host-based port access with custom ID scheme",
+			URL:  "http://1234-acme-x8c2fd4k.ws.gitpod.dev/",
+			Headers: map[string]string{
+				forwardedHostnameHeader: "1234-acme-x8c2fd4k.ws.gitpod.dev",
+			},
+			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix, nil),
+			WSHostSuffix: wsHostSuffix,
+			Expected: Expectation{
+				WorkspaceID:   "acme-x8c2fd4k",
+				WorkspacePort: "1234",
+				Status:        http.StatusOK,
+				URL:           "http://1234-acme-x8c2fd4k.ws.gitpod.dev/",
+			},
+		},
 		{
 			Name: "host-based blobserve access",
 			URL:  "http://blobserve.ws.gitpod.dev/image:version:/foo/main.js",
 			Headers: map[string]string{
 				forwardedHostnameHeader: "blobserve.ws.gitpod.dev",
 			},
-			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix),
+			Router:       HostBasedRouter(forwardedHostnameHeader, wsHostSuffix, nil),
 			WSHostSuffix: wsHostSuffix,
 			Expected: Expectation{
 				Status: http.StatusOK,
@@ -451,3 +483,141 @@ func TestMatchWorkspaceHostHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestHostRoutes(t *testing.T) {
+	scheme := wsurl.Scheme{HostSuffix: ".gitpod.io"}
+	routes := newHostRoutes(scheme, 2)
+
+	hosts := []string{
+		"amaranth-smelt-9ba20cc1.gitpod.io",
+		"8080-amaranth-smelt-9ba20cc1.gitpod.io",
+		"browser-8080-amaranth-smelt-9ba20cc1.gitpod.io",
+		"amaranth-smelt-9ba20cc1.example.com",
+	}
+	// every host twice: once parsed and once remembered - with a limit of two we also forget routes along the way
+	for i := 0; i < 2; i++ {
+		for _, host := range hosts {
+			var expected *hostRoute
+			if h, err := scheme.ParseHost(host); err == nil {
+				expected = &hostRoute{WorkspaceID: h.WorkspaceID}
+				if h.Port != 0 {
+					expected.Port = strconv.Itoa(h.Port)
+				}
+				if h.ForeignPrefix != "" {
+					expected.ForeignOriginPrefix = h.ForeignPrefix + "-"
+				}
+			}
+			if diff := cmp.Diff(expected, routes.Get(host)); diff != "" {
+				t.Errorf("unexpected route for %s (-want +got):\n%s", host, diff)
+			}
+		}
+	}
+	if n := len(routes.recent) + len(routes.old); n > 2 {
+		t.Errorf("host routes exceeded their limit: %d routes", n)
+	}
+}
+
+func TestHostRoutesEviction(t *testing.T) {
+	scheme := wsurl.Scheme{HostSuffix: ".gitpod.io"}
+	routes := newHostRoutes(scheme, 4)
+
+	const hot = "amaranth-smelt-9ba20cc1.gitpod.io"
+	routes.Get(hot)
+	for i := 0; i < 10; i++ {
+		// a host nobody asks for twice, and the hot host which receives requests all the time
+		routes.Get(fmt.Sprintf("%d-amaranth-smelt-9ba20cc1.gitpod.io", 3000+i))
+		routes.Get(hot)
+
+		routes.mu.RLock()
+		_, recent := routes.recent[hot]
+		_, old := routes.old[hot]
+		n := len(routes.recent) + len(routes.old)
+		routes.mu.RUnlock()
+		if !recent && !old {
+			t.Fatalf("forgot the hot host after %d other hosts", i+1)
+		}
+		if n > 4 {
+			t.Fatalf("host routes exceeded their limit: %d routes", n)
+		}
+	}
+
+	routes.mu.RLock()
+	_, ok := routes.recent["3000-amaranth-smelt-9ba20cc1.gitpod.io"]
+	if !ok {
+		_, ok = routes.old["3000-amaranth-smelt-9ba20cc1.gitpod.io"]
+	}
+	routes.mu.RUnlock()
+	if ok {
+		t.Error("expected the host nobody used anymore to be forgotten")
+	}
+}
+
+// TestMatchWorkspaceHostAllocations guards host-based routing against allocation regressions
+func TestMatchWorkspaceHostAllocations(t *testing.T) {
+	const host = "8080-amaranth-smelt-9ba20cc1.gitpod.io"
+	var (
+		match = matchWorkspaceHost(wsurl.Scheme{HostSuffix: ".gitpod.io"}, true, func(req *http.Request) string { return host })
+		req   = &http.Request{Host: host}
+		m     = mux.RouteMatch{Vars: make(map[string]string)}
+	)
+	allocs := testing.AllocsPerRun(100, func() {
+		if !match(req, &m) {
+			t.Fatal("host did not match")
+		}
+	})
+	if allocs > 0 {
+		t.Errorf("matching a workspace host allocates %.0f times, the budget is 0", allocs)
+	}
+}
+
+func BenchmarkMatchWorkspaceHost(b *testing.B) {
+	const workspaces = 10000
+	hosts := make([]string, workspaces)
+	for i := range hosts {
+		hosts[i] = fmt.Sprintf("%d-amaranth-smelt-%08x.gitpod.io", 3000+i%1000, i)
+	}
+	match := matchWorkspaceHost(wsurl.Scheme{HostSuffix: ".gitpod.io"}, true, func(req *http.Request) string { return req.Host })
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var (
+			i int
+			m = mux.RouteMatch{Vars: make(map[string]string)}
+		)
+		for pb.Next() {
+			i++
+			if !match(&http.Request{Host: hosts[i%workspaces]}, &m) {
+				b.Fatal("host did not match")
+			}
+		}
+	})
+}
+
+func TestGetPublicPortFromPortReq(t *testing.T) {
+	tests := []struct {
+		Host  string
+		Port  string
+		Error bool
+	}{
+		{Host: "10.0.0.1:10000", Port: "10000"},
+		{Host: "gitpod.example.com:10000", Port: "10000"},
+		{Host: "[fd00::1]:10000", Port: "10000"},
+		{Host: "[fd00::1]", Error: true},
+		{Host: "gitpod.example.com", Error: true},
+		{Host: "fd00::1:10000", Error: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Host, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			req.Host = test.Host
+
+			port, err := getPublicPortFromPortReq(req)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if port != test.Port {
+				t.Errorf("port = %q, expected %q", port, test.Port)
+			}
+		})
+	}
+}
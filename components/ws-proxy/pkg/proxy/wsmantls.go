@@ -0,0 +1,302 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const defaultTLSReloadInterval = 30 * time.Second
+
+// WsManagerTLSConfig configures TLS for the connection to ws-manager
+type WsManagerTLSConfig struct {
+	// CA is the path to the PEM encoded certificate authority used to verify ws-manager
+	CA string `json:"ca"`
+	// Certificate and PrivateKey are the client certificate presented to ws-manager.
+	// If both are empty, the connection is encrypted but not mutually authenticated.
+	Certificate string `json:"crt"`
+	PrivateKey  string `json:"key"`
+	// ServerName overrides the name used to verify ws-manager's certificate
+	ServerName string `json:"serverName,omitempty"`
+	// SPIFFEID, if set, requires ws-manager's certificate to carry this SPIFFE ID as URI SAN
+	SPIFFEID string `json:"spiffeID,omitempty"`
+	// ReloadInterval is the interval in which the certificate files are checked for changes
+	ReloadInterval util.Duration `json:"reloadInterval,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *WsManagerTLSConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if (c.Certificate == "") != (c.PrivateKey == "") {
+		return xerrors.Errorf("invalid ws-manager TLS config: crt and key must be set together")
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.CA, validation.Required, validation.By(validateFileExists(""))),
+		validation.Field(&c.Certificate, validation.By(validateOptionalFileExists)),
+		validation.Field(&c.PrivateKey, validation.By(validateOptionalFileExists)),
+		validation.Field(&c.ReloadInterval, validation.Min(util.Duration(0))),
+	)
+	if err != nil {
+		return xerrors.Errorf("invalid ws-manager TLS config: %w", err)
+	}
+	return nil
+}
+
+func validateOptionalFileExists(value interface{}) error {
+	if pth, ok := value.(string); ok && pth == "" {
+		return nil
+	}
+	return validateFileExists("")(value)
+}
+
+// certWatcher keeps the TLS material for the ws-manager connection up to date
+// by watching the certificate files for changes.
+type certWatcher struct {
+	Config WsManagerTLSConfig
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	roots    *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+func newCertWatcher(cfg WsManagerTLSConfig) (*certWatcher, error) {
+	w := &certWatcher{
+		Config:   cfg,
+		modTimes: make(map[string]time.Time),
+	}
+	_, err := w.reload()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *certWatcher) files() []string {
+	fs := []string{w.Config.CA}
+	if w.Config.Certificate != "" {
+		fs = append(fs, w.Config.Certificate, w.Config.PrivateKey)
+	}
+
+	if tproot := os.Getenv("TELEPRESENCE_ROOT"); tproot != "" {
+		for i := range fs {
+			fs[i] = filepath.Join(tproot, fs[i])
+		}
+	}
+	return fs
+}
+
+// reload loads the certificates anew if any of the files changed since the last load
+func (w *certWatcher) reload() (changed bool, err error) {
+	fs := w.files()
+
+	modTimes := make(map[string]time.Time, len(fs))
+	for _, fn := range fs {
+		stat, err := os.Stat(fn)
+		if err != nil {
+			return false, xerrors.Errorf("cannot stat %s: %w", fn, err)
+		}
+		modTimes[fn] = stat.ModTime()
+	}
+
+	w.mu.RLock()
+	for fn, mt := range modTimes {
+		if !w.modTimes[fn].Equal(mt) {
+			changed = true
+			break
+		}
+	}
+	w.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	ca, err := os.ReadFile(fs[0])
+	if err != nil {
+		return false, xerrors.Errorf("cannot read ca certificate: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if ok := roots.AppendCertsFromPEM(ca); !ok {
+		return false, xerrors.Errorf("failed to append ca certs")
+	}
+
+	var cert *tls.Certificate
+	if len(fs) == 3 {
+		c, err := tls.LoadX509KeyPair(fs[1], fs[2])
+		if err != nil {
+			return false, xerrors.Errorf("cannot load client certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	w.mu.Lock()
+	w.roots = roots
+	w.cert = cert
+	w.modTimes = modTimes
+	w.mu.Unlock()
+
+	return true, nil
+}
+
+// Watch checks the certificate files for changes until stop is closed
+func (w *certWatcher) Watch(stop <-chan struct{}) {
+	interval := time.Duration(w.Config.ReloadInterval)
+	if interval == 0 {
+		interval = defaultTLSReloadInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+
+		changed, err := w.reload()
+		if err != nil {
+			log.WithError(err).Warn("cannot reload ws-manager TLS certificates - keeping the old ones")
+			continue
+		}
+		if changed {
+			log.Info("reloaded ws-manager TLS certificates")
+		}
+	}
+}
+
+// TLSConfig produces a TLS config which always uses the most recently loaded certificates.
+// New certificates take effect on the next connection to ws-manager.
+func (w *certWatcher) TLSConfig(serverName string) *tls.Config {
+	if w.Config.ServerName != "" {
+		serverName = w.Config.ServerName
+	}
+
+	return &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			w.mu.RLock()
+			defer w.mu.RUnlock()
+
+			if w.cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return w.cert, nil
+		},
+		// We verify the server certificate ourselves in VerifyPeerCertificate so that
+		// a rotated CA takes effect without having to re-create the config.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return w.verifyServerCertificate(rawCerts, serverName)
+		},
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+func (w *certWatcher) verifyServerCertificate(rawCerts [][]byte, serverName string) error {
+	if len(rawCerts) == 0 {
+		return xerrors.Errorf("ws-manager presented no certificate")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		c, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return xerrors.Errorf("cannot parse ws-manager certificate: %w", err)
+		}
+		certs = append(certs, c)
+	}
+
+	w.mu.RLock()
+	roots := w.roots
+	w.mu.RUnlock()
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if w.Config.SPIFFEID == "" {
+		// SPIFFE certificates identify workloads by their URI SAN, not by DNS name
+		opts.DNSName = serverName
+	}
+	_, err := certs[0].Verify(opts)
+	if err != nil {
+		return xerrors.Errorf("cannot verify ws-manager certificate: %w", err)
+	}
+
+	if w.Config.SPIFFEID != "" {
+		for _, u := range certs[0].URIs {
+			if u.String() == w.Config.SPIFFEID {
+				return nil
+			}
+		}
+		return xerrors.Errorf("ws-manager certificate does not carry SPIFFE ID %s", w.Config.SPIFFEID)
+	}
+
+	return nil
+}
+
+// newTLSWsmanagerDialer produces a dialer which connects to ws-manager using TLS.
+// The certificates are loaded on first dial and watched for changes until stop is closed.
+func newTLSWsmanagerDialer(cfg WsManagerTLSConfig, stop <-chan struct{}) WSManagerDialer {
+	var (
+		once    sync.Once
+		watcher *certWatcher
+		initErr error
+	)
+	return func(target string) (io.Closer, wsapi.WorkspaceManagerClient, error) {
+		once.Do(func() {
+			watcher, initErr = newCertWatcher(cfg)
+			if initErr != nil {
+				return
+			}
+			go watcher.Watch(stop)
+		})
+		if initErr != nil {
+			return nil, nil, initErr
+		}
+
+		host := target
+		if h, _, err := net.SplitHostPort(target); err == nil {
+			host = h
+		}
+		creds := credentials.NewTLS(watcher.TLSConfig(host))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, target, grpc.WithTransportCredentials(creds), grpc.WithBlock())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		client := wsapi.NewWorkspaceManagerClient(conn)
+		return conn, client, nil
+	}
+}
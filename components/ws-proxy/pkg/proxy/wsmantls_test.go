@@ -0,0 +1,185 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+	PEM  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{
+		Cert: cert,
+		Key:  key,
+		PEM:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (ca *testCA) Issue(t *testing.T, dnsName, spiffeID string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if spiffeID != "" {
+		u, err := url.Parse(spiffeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.Cert, &key.PublicKey, ca.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func serveTLS(t *testing.T, cert tls.Certificate) string {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				_ = c.(*tls.Conn).Handshake()
+			}(conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestCertWatcher(t *testing.T) {
+	var (
+		caA = newTestCA(t, "ca-a")
+		caB = newTestCA(t, "ca-b")
+		dir = t.TempDir()
+		fn  = filepath.Join(dir, "ca.crt")
+	)
+	err := os.WriteFile(fn, caA.PEM, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const spiffeID = "spiffe://gitpod.io/ws-manager"
+	var (
+		addrA      = serveTLS(t, caA.Issue(t, "ws-manager", spiffeID))
+		addrB      = serveTLS(t, caB.Issue(t, "ws-manager", ""))
+		addrWrongN = serveTLS(t, caA.Issue(t, "somebody-else", ""))
+	)
+
+	w, err := newCertWatcher(WsManagerTLSConfig{CA: fn, ServerName: "ws-manager"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handshake := func(w *certWatcher, addr string) error {
+		conn, err := tls.Dial("tcp", addr, w.TLSConfig("ignored"))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	if err := handshake(w, addrA); err != nil {
+		t.Errorf("expected handshake with CA A to succeed: %v", err)
+	}
+	if err := handshake(w, addrB); err == nil {
+		t.Errorf("expected handshake with CA B to fail before rotation")
+	}
+	if err := handshake(w, addrWrongN); err == nil {
+		t.Errorf("expected handshake with wrong server name to fail")
+	}
+
+	// rotate the CA
+	err = os.WriteFile(fn, caB.PEM, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	err = os.Chtimes(fn, future, future)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := w.reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatal("expected reload to pick up the rotated CA")
+	}
+	if err := handshake(w, addrB); err != nil {
+		t.Errorf("expected handshake with CA B to succeed after rotation: %v", err)
+	}
+
+	// SPIFFE ID verification
+	err = os.WriteFile(fn, caA.PEM, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sw, err := newCertWatcher(WsManagerTLSConfig{CA: fn, SPIFFEID: spiffeID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(sw, addrA); err != nil {
+		t.Errorf("expected handshake with matching SPIFFE ID to succeed: %v", err)
+	}
+	if err := handshake(sw, addrWrongN); err == nil {
+		t.Errorf("expected handshake without SPIFFE ID to fail")
+	}
+}
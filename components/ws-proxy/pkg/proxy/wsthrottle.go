@@ -0,0 +1,550 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// defaultWebsocketMaxBufferedBytes is how much we buffer for a slow websocket client unless configured otherwise
+	defaultWebsocketMaxBufferedBytes = 1024 * 1024
+
+	// websocketBudgetRetention is how long we keep the budget of a workspace route after its last websocket closed,
+	// so that reconnecting does not refill the budget
+	websocketBudgetRetention = 1 * time.Minute
+
+	// websocketFlushTimeout is how long we try to deliver what we buffered for a client once the connection closes,
+	// e.g. the close frame of the workspace
+	websocketFlushTimeout = 1 * time.Second
+
+	// websocketMaxFrameHeaderLength is the length of a frame header with a 64 bit payload length and a masking key
+	websocketMaxFrameHeaderLength = 14
+)
+
+// Directions of websocket messages
+const (
+	websocketDirectionIngress = "ingress"
+	websocketDirectionEgress  = "egress"
+)
+
+// WebsocketThrottleConfig throttles the messages exchanged over the websockets of a route
+type WebsocketThrottleConfig struct {
+	// MessagesPerSecond limits the messages each workspace exchanges over the websockets of the route,
+	// per direction and across all its connections. Zero means no limit.
+	MessagesPerSecond int `json:"messagesPerSecond,omitempty"`
+	// Burst is how many messages a workspace may exchange at once before the limit applies. Defaults to MessagesPerSecond.
+	Burst int `json:"burst,omitempty"`
+	// MaxBufferedBytes is how much we buffer per connection for clients which read slower than the workspace sends.
+	// Once the buffer is full we hold back the workspace, or drop messages if Drop is set. Defaults to 1 MiB.
+	MaxBufferedBytes int64 `json:"maxBufferedBytes,omitempty"`
+	// Drop drops messages which exceed the limit or do not fit the buffer instead of holding them back.
+	// Dropping messages breaks the IDE and supervisor protocols, hence only routes to workspace ports may drop.
+	Drop bool `json:"drop,omitempty"`
+}
+
+// Validate validates the configuration to catch issues during startup and not at runtime
+func (c *WebsocketThrottleConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	err := validation.ValidateStruct(c,
+		validation.Field(&c.MessagesPerSecond, validation.Min(0)),
+		validation.Field(&c.Burst, validation.Min(0)),
+		validation.Field(&c.MaxBufferedBytes, validation.Min(int64(0))),
+	)
+	if err != nil {
+		return err
+	}
+	if c.Burst > 0 && c.MessagesPerSecond == 0 {
+		return xerrors.Errorf("burst requires messagesPerSecond")
+	}
+	return nil
+}
+
+func (c WebsocketThrottleConfig) maxBufferedBytes() int {
+	if c.MaxBufferedBytes == 0 {
+		return defaultWebsocketMaxBufferedBytes
+	}
+	return int(c.MaxBufferedBytes)
+}
+
+func (c WebsocketThrottleConfig) newLimiter() *egressLimiter {
+	if c.MessagesPerSecond == 0 {
+		return nil
+	}
+	burst := c.Burst
+	if burst == 0 {
+		burst = c.MessagesPerSecond
+	}
+	return &egressLimiter{
+		rate:   float64(c.MessagesPerSecond),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+type websocketBudgetKey struct {
+	Route       string
+	WorkspaceID string
+}
+
+// websocketBudget is the message budget of a workspace on a route, shared by all its websockets
+type websocketBudget struct {
+	ingress *egressLimiter
+	egress  *egressLimiter
+
+	// connections and lastSeen are guarded by the throttler's lock
+	connections int
+	lastSeen    time.Time
+}
+
+// WebsocketThrottler throttles the messages of websockets on routes which configure it, so that a single workspace
+// flooding its websockets cannot degrade the proxy for everyone else.
+type WebsocketThrottler struct {
+	mu      sync.Mutex
+	budgets map[websocketBudgetKey]*websocketBudget
+
+	metrics struct {
+		throttled *prometheus.CounterVec
+		waited    *prometheus.CounterVec
+		buffered  *prometheus.GaugeVec
+	}
+}
+
+// NewWebsocketThrottler creates a new websocket throttler
+func NewWebsocketThrottler() *WebsocketThrottler {
+	res := &WebsocketThrottler{
+		budgets: make(map[websocketBudgetKey]*websocketBudget),
+	}
+	res.metrics.throttled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "websocket_throttled_messages_total",
+		Help: "Websocket messages we held back or dropped, by route, direction, reason (rate or buffer) and action",
+	}, []string{"route", "direction", "reason", "action"})
+	res.metrics.waited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "websocket_throttled_seconds_total",
+		Help: "Time websocket messages were held back for the message rate limit, by route and direction",
+	}, []string{"route", "direction"})
+	res.metrics.buffered = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "websocket_buffered_bytes",
+		Help: "Bytes buffered for websocket clients which read slower than their workspace sends, by route",
+	}, []string{"route"})
+	return res
+}
+
+// RegisterMetrics registers the websocket throttle metrics
+func (t *WebsocketThrottler) RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{t.metrics.throttled, t.metrics.waited, t.metrics.buffered} {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler throttles the websockets of a route if the route configures it. If the throttler is nil, the handler does nothing.
+func (t *WebsocketThrottler) Handler(route string, rc RouteConfig, h http.HandlerFunc) http.HandlerFunc {
+	if t == nil || rc.Websocket == nil || rc.Websocket.Throttle == nil {
+		return h
+	}
+	cfg := *rc.Websocket.Throttle
+	return func(resp http.ResponseWriter, req *http.Request) {
+		workspaceID := getWorkspaceCoords(req).ID
+		if workspaceID == "" || !isWebsocketRequest(req) {
+			h(resp, req)
+			return
+		}
+		h(&throttledWebsocketResponseWriter{
+			ResponseWriter: resp,
+			throttler:      t,
+			key:            websocketBudgetKey{Route: route, WorkspaceID: workspaceID},
+			cfg:            cfg,
+		}, req)
+	}
+}
+
+// open returns the budget of a workspace route and counts a connection
+func (t *WebsocketThrottler) open(key websocketBudgetKey, cfg WebsocketThrottleConfig) *websocketBudget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.budgets[key]
+	if !ok {
+		t.prune(time.Now())
+		b = &websocketBudget{
+			ingress: cfg.newLimiter(),
+			egress:  cfg.newLimiter(),
+		}
+		t.budgets[key] = b
+	}
+	b.connections++
+	b.lastSeen = time.Now()
+	return b
+}
+
+func (t *WebsocketThrottler) close(b *websocketBudget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b.connections--
+	b.lastSeen = time.Now()
+}
+
+// prune forgets the budgets of workspace routes without websockets. Callers must hold the lock.
+func (t *WebsocketThrottler) prune(now time.Time) {
+	for key, b := range t.budgets {
+		if b.connections == 0 && now.Sub(b.lastSeen) > websocketBudgetRetention {
+			delete(t.budgets, key)
+		}
+	}
+}
+
+// throttledWebsocketResponseWriter throttles the connection the reverse proxy hijacks for the websocket
+type throttledWebsocketResponseWriter struct {
+	http.ResponseWriter
+	throttler *WebsocketThrottler
+	key       websocketBudgetKey
+	cfg       WebsocketThrottleConfig
+}
+
+func (w *throttledWebsocketResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *throttledWebsocketResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("response writer does not support hijacking")
+	}
+	conn, brw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return newThrottledWebsocketConn(conn, w.throttler, w.key, w.cfg), brw, nil
+}
+
+// throttledWebsocketConn throttles the messages exchanged over a websocket client connection. Reads are the messages
+// the client sends to the workspace, writes the messages the workspace sends to the client. The latter are buffered
+// so that a slow client holds back the workspace only once the buffer is full.
+type throttledWebsocketConn struct {
+	net.Conn
+
+	throttler *WebsocketThrottler
+	key       websocketBudgetKey
+	cfg       WebsocketThrottleConfig
+	budget    *websocketBudget
+	buffered  prometheus.Gauge
+
+	ingress *websocketMessageFilter
+	readBuf []byte
+	pending bytes.Buffer
+
+	egress *websocketMessageFilter
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  [][]byte
+	size   int
+	err    error
+	closed bool
+	done   chan struct{}
+	// drained is closed once the write loop has delivered or given up on the queue
+	drained chan struct{}
+}
+
+func newThrottledWebsocketConn(conn net.Conn, t *WebsocketThrottler, key websocketBudgetKey, cfg WebsocketThrottleConfig) *throttledWebsocketConn {
+	c := &throttledWebsocketConn{
+		Conn:      conn,
+		throttler: t,
+		key:       key,
+		cfg:       cfg,
+		budget:    t.open(key, cfg),
+		buffered:  t.metrics.buffered.WithLabelValues(key.Route),
+		readBuf:   make([]byte, 32*1024),
+		done:      make(chan struct{}),
+		drained:   make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	c.ingress = &websocketMessageFilter{admit: func() bool { return c.admitRate(c.budget.ingress, websocketDirectionIngress) }}
+	c.egress = &websocketMessageFilter{admit: c.admitEgress}
+	go c.writeLoop()
+	return c
+}
+
+// admitRate decides whether to forward a message subject to the message rate limit. Unless we drop messages,
+// it holds back the message until the limit permits it.
+func (c *throttledWebsocketConn) admitRate(l *egressLimiter, direction string) bool {
+	if l == nil {
+		return true
+	}
+	now := time.Now()
+	if c.cfg.Drop {
+		if l.allow(1, now) {
+			return true
+		}
+		c.throttler.metrics.throttled.WithLabelValues(c.key.Route, direction, "rate", "dropped").Inc()
+		return false
+	}
+
+	wait := l.take(1, now)
+	if wait <= 0 {
+		return true
+	}
+	c.throttler.metrics.throttled.WithLabelValues(c.key.Route, direction, "rate", "delayed").Inc()
+	c.throttler.metrics.waited.WithLabelValues(c.key.Route, direction).Add(wait.Seconds())
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// admitEgress decides whether to forward a message to the client. If we drop messages, we also drop those
+// which arrive while the buffer of the client is full.
+func (c *throttledWebsocketConn) admitEgress() bool {
+	if !c.admitRate(c.budget.egress, websocketDirectionEgress) {
+		return false
+	}
+	if !c.cfg.Drop {
+		return true
+	}
+
+	c.mu.Lock()
+	full := c.size >= c.cfg.maxBufferedBytes()
+	c.mu.Unlock()
+	if full {
+		c.throttler.metrics.throttled.WithLabelValues(c.key.Route, websocketDirectionEgress, "buffer", "dropped").Inc()
+		return false
+	}
+	return true
+}
+
+func (c *throttledWebsocketConn) Read(p []byte) (int, error) {
+	for c.pending.Len() == 0 {
+		n, err := c.Conn.Read(c.readBuf)
+		if n > 0 {
+			_ = c.ingress.filter(c.readBuf[:n], func(b []byte) error {
+				_, _ = c.pending.Write(b)
+				return nil
+			})
+		}
+		if err != nil {
+			if c.pending.Len() > 0 {
+				break
+			}
+			return 0, err
+		}
+	}
+	return c.pending.Read(p)
+}
+
+func (c *throttledWebsocketConn) Write(p []byte) (int, error) {
+	err := c.egress.filter(p, c.enqueue)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// enqueue buffers p for the client, and waits while the buffer is full
+func (c *throttledWebsocketConn) enqueue(p []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size >= c.cfg.maxBufferedBytes() && c.err == nil && !c.closed {
+		c.throttler.metrics.throttled.WithLabelValues(c.key.Route, websocketDirectionEgress, "buffer", "delayed").Inc()
+		for c.size >= c.cfg.maxBufferedBytes() && c.err == nil && !c.closed {
+			c.cond.Wait()
+		}
+	}
+	if c.err != nil {
+		return c.err
+	}
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	c.queue = append(c.queue, append([]byte(nil), p...))
+	c.size += len(p)
+	c.buffered.Add(float64(len(p)))
+	c.cond.Broadcast()
+	return nil
+}
+
+// writeLoop delivers the buffered messages to the client until the connection closes
+func (c *throttledWebsocketConn) writeLoop() {
+	defer close(c.drained)
+
+	for {
+		c.mu.Lock()
+		for len(c.queue) == 0 && !c.closed {
+			c.cond.Wait()
+		}
+		if len(c.queue) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		p := c.queue[0]
+		c.queue[0] = nil
+		c.queue = c.queue[1:]
+		c.mu.Unlock()
+
+		_, err := c.Conn.Write(p)
+
+		c.mu.Lock()
+		c.size -= len(p)
+		c.buffered.Sub(float64(len(p)))
+		if err != nil {
+			c.err = err
+			for _, q := range c.queue {
+				c.size -= len(q)
+				c.buffered.Sub(float64(len(q)))
+			}
+			c.queue = nil
+		}
+		c.cond.Broadcast()
+		c.mu.Unlock()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Close delivers what we buffered for the client, unless the client does not accept it within the flush timeout,
+// and closes the connection
+func (c *throttledWebsocketConn) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return c.Conn.Close()
+	}
+	c.closed = true
+	close(c.done)
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	_ = c.Conn.SetWriteDeadline(time.Now().Add(websocketFlushTimeout))
+	<-c.drained
+	c.throttler.close(c.budget)
+	return c.Conn.Close()
+}
+
+// websocketMessageFilter forwards or drops whole messages of a websocket byte stream. It decides once the first
+// frame of a message starts, and always forwards control frames, which may arrive between the frames of a message.
+type websocketMessageFilter struct {
+	// admit decides whether to forward a message. It may block to hold back the message.
+	admit func() bool
+
+	hdr     [websocketMaxFrameHeaderLength]byte
+	hdrLen  int
+	payload uint64
+	forward bool
+	// drop is true while we drop the frames of a message
+	drop bool
+}
+
+// filter passes the bytes of p which we forward on to emit
+func (f *websocketMessageFilter) filter(p []byte, emit func([]byte) error) error {
+	for len(p) > 0 {
+		if f.payload > 0 {
+			n := uint64(len(p))
+			if n > f.payload {
+				n = f.payload
+			}
+			if f.forward {
+				err := emit(p[:n])
+				if err != nil {
+					return err
+				}
+			}
+			f.payload -= n
+			p = p[n:]
+			continue
+		}
+
+		for len(p) > 0 && f.hdrLen < websocketFrameHeaderLength(f.hdr[:f.hdrLen]) {
+			f.hdr[f.hdrLen] = p[0]
+			f.hdrLen++
+			p = p[1:]
+		}
+		if f.hdrLen < websocketFrameHeaderLength(f.hdr[:f.hdrLen]) {
+			return nil
+		}
+
+		hdr := f.hdr[:f.hdrLen]
+		f.hdrLen = 0
+		switch opcode := hdr[0] & 0x0f; {
+		case opcode&0x08 != 0:
+			// control frame
+			f.forward = true
+		case opcode != 0:
+			// first frame of a message
+			f.drop = !f.admit()
+			f.forward = !f.drop
+		default:
+			// continuation frame
+			f.forward = !f.drop
+		}
+		f.payload = websocketPayloadLength(hdr)
+		if f.forward {
+			err := emit(hdr)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// websocketFrameHeaderLength returns the length of a frame header given its first bytes
+func websocketFrameHeaderLength(hdr []byte) int {
+	if len(hdr) < 2 {
+		return 2
+	}
+	l := 2
+	switch hdr[1] & 0x7f {
+	case 126:
+		l += 2
+	case 127:
+		l += 8
+	}
+	if hdr[1]&0x80 != 0 {
+		// masking key
+		l += 4
+	}
+	return l
+}
+
+// websocketPayloadLength returns the payload length of a complete frame header
+func websocketPayloadLength(hdr []byte) uint64 {
+	switch l := hdr[1] & 0x7f; l {
+	case 126:
+		return uint64(binary.BigEndian.Uint16(hdr[2:4]))
+	case 127:
+		return binary.BigEndian.Uint64(hdr[2:10]) & math.MaxInt64
+	default:
+		return uint64(l)
+	}
+}
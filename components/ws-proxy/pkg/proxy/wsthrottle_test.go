@@ -0,0 +1,257 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// websocketTestFrame produces a websocket frame. Masked frames use a zero masking key, which leaves the payload as is.
+func websocketTestFrame(fin bool, opcode byte, masked bool, payload []byte) []byte {
+	var hdr bytes.Buffer
+	b0 := opcode
+	if fin {
+		b0 |= 0x80
+	}
+	hdr.WriteByte(b0)
+
+	var mask byte
+	if masked {
+		mask = 0x80
+	}
+	switch {
+	case len(payload) < 126:
+		hdr.WriteByte(mask | byte(len(payload)))
+	case len(payload) <= 0xffff:
+		hdr.WriteByte(mask | 126)
+		_ = binary.Write(&hdr, binary.BigEndian, uint16(len(payload)))
+	default:
+		hdr.WriteByte(mask | 127)
+		_ = binary.Write(&hdr, binary.BigEndian, uint64(len(payload)))
+	}
+	if masked {
+		hdr.Write([]byte{0, 0, 0, 0})
+	}
+	hdr.Write(payload)
+	return hdr.Bytes()
+}
+
+func TestWebsocketMessageFilter(t *testing.T) {
+	var (
+		first   = websocketTestFrame(true, 0x1, true, []byte("first"))
+		second1 = websocketTestFrame(false, 0x2, true, bytes.Repeat([]byte("a"), 300))
+		ping    = websocketTestFrame(true, 0x9, true, []byte("ping"))
+		second2 = websocketTestFrame(true, 0x0, true, bytes.Repeat([]byte("b"), 70000))
+		third   = websocketTestFrame(true, 0x1, false, nil)
+		closing = websocketTestFrame(true, 0x8, false, []byte{0x03, 0xe8})
+		stream  = bytes.Join([][]byte{first, second1, ping, second2, third, closing}, nil)
+	)
+
+	tests := []struct {
+		Name        string
+		Admit       []bool
+		Expectation []byte
+	}{
+		{Name: "forward all", Admit: []bool{true, true, true}, Expectation: stream},
+		{
+			Name:  "drop fragmented message",
+			Admit: []bool{true, false, true},
+			// the ping arrives between the fragments of the dropped message, but is no part of it
+			Expectation: bytes.Join([][]byte{first, ping, third, closing}, nil),
+		},
+		{Name: "drop all", Admit: []bool{false, false, false}, Expectation: bytes.Join([][]byte{ping, closing}, nil)},
+	}
+	for _, test := range tests {
+		for _, chunkSize := range []int{1, 3, 1000, len(stream)} {
+			t.Run(test.Name, func(t *testing.T) {
+				var (
+					admitted int
+					out      bytes.Buffer
+				)
+				f := &websocketMessageFilter{admit: func() bool {
+					res := test.Admit[admitted]
+					admitted++
+					return res
+				}}
+				for p := stream; len(p) > 0; {
+					n := chunkSize
+					if n > len(p) {
+						n = len(p)
+					}
+					err := f.filter(p[:n], func(b []byte) error {
+						out.Write(b)
+						return nil
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					p = p[n:]
+				}
+				if admitted != len(test.Admit) {
+					t.Errorf("expected %d messages, got %d", len(test.Admit), admitted)
+				}
+				if !bytes.Equal(out.Bytes(), test.Expectation) {
+					t.Errorf("chunk size %d: unexpected output of %d bytes, expected %d bytes", chunkSize, out.Len(), len(test.Expectation))
+				}
+			})
+		}
+	}
+}
+
+func newTestThrottledWebsocketConn(t *testing.T, cfg WebsocketThrottleConfig) (client net.Conn, conn *throttledWebsocketConn) {
+	client, server := net.Pipe()
+	conn = newThrottledWebsocketConn(server, NewWebsocketThrottler(), websocketBudgetKey{Route: RoutePort, WorkspaceID: "amaranth-smelt-9ba20cc1"}, cfg)
+	t.Cleanup(func() {
+		client.Close()
+		conn.Close()
+	})
+	return client, conn
+}
+
+func TestThrottledWebsocketConnDropsMessages(t *testing.T) {
+	client, conn := newTestThrottledWebsocketConn(t, WebsocketThrottleConfig{MessagesPerSecond: 1, Burst: 2, Drop: true})
+
+	msg := websocketTestFrame(true, 0x1, false, []byte("hello"))
+	go func() {
+		for i := 0; i < 5; i++ {
+			_, _ = conn.Write(msg)
+		}
+		conn.Close()
+	}()
+
+	res, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := bytes.Repeat(msg, 2); !bytes.Equal(res, exp) {
+		t.Errorf("expected the burst of two messages to arrive, got %q", res)
+	}
+}
+
+func TestThrottledWebsocketConnDelaysMessages(t *testing.T) {
+	client, conn := newTestThrottledWebsocketConn(t, WebsocketThrottleConfig{MessagesPerSecond: 20, Burst: 1})
+
+	msg := websocketTestFrame(true, 0x1, true, []byte("hello"))
+	go func() {
+		for i := 0; i < 3; i++ {
+			_, _ = client.Write(msg)
+		}
+		client.Close()
+	}()
+
+	start := time.Now()
+	res, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := bytes.Repeat(msg, 3); !bytes.Equal(res, exp) {
+		t.Errorf("expected all messages to arrive, got %q", res)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected messages beyond the burst to be held back, took %s", elapsed)
+	}
+}
+
+func TestThrottledWebsocketConnSlowClient(t *testing.T) {
+	msg := websocketTestFrame(true, 0x2, false, []byte(strings.Repeat("x", 100)))
+
+	t.Run("drop", func(t *testing.T) {
+		client, conn := newTestThrottledWebsocketConn(t, WebsocketThrottleConfig{MaxBufferedBytes: 250, Drop: true})
+
+		// the client does not read - writes must not block nonetheless
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 10; i++ {
+				_, _ = conn.Write(msg)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("writes blocked on a slow client")
+		}
+		go conn.Close()
+
+		res, err := io.ReadAll(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) == 0 || len(res)%len(msg) != 0 || len(res) > 10*len(msg) {
+			t.Errorf("expected whole messages to arrive, got %d bytes", len(res))
+		}
+		if len(res) == 10*len(msg) {
+			t.Error("expected messages to be dropped")
+		}
+	})
+
+	t.Run("hold back", func(t *testing.T) {
+		client, conn := newTestThrottledWebsocketConn(t, WebsocketThrottleConfig{MaxBufferedBytes: 250})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 10; i++ {
+				_, _ = conn.Write(msg)
+			}
+		}()
+		select {
+		case <-done:
+			t.Fatal("writes did not block on a slow client")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		var res bytes.Buffer
+		go func() {
+			<-done
+			conn.Close()
+		}()
+		_, err := io.Copy(&res, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := bytes.Repeat(msg, 10); !bytes.Equal(res.Bytes(), exp) {
+			t.Errorf("expected all messages to arrive, got %d bytes", res.Len())
+		}
+	})
+}
+
+func TestWebsocketThrottleRouteTable(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Route string
+		Cfg   WebsocketThrottleConfig
+		Valid bool
+	}{
+		{Name: "delay on IDE", Route: RouteIDEDirect, Cfg: WebsocketThrottleConfig{MessagesPerSecond: 100}, Valid: true},
+		{Name: "drop on ports", Route: RoutePort, Cfg: WebsocketThrottleConfig{MessagesPerSecond: 100, Drop: true}, Valid: true},
+		{Name: "drop on IDE", Route: RouteIDEDirect, Cfg: WebsocketThrottleConfig{MessagesPerSecond: 100, Drop: true}},
+		{Name: "drop on supervisor", Route: RouteSupervisorAPI, Cfg: WebsocketThrottleConfig{Drop: true}},
+		{Name: "burst without rate", Route: RoutePort, Cfg: WebsocketThrottleConfig{Burst: 10}},
+		{Name: "negative buffer", Route: RoutePort, Cfg: WebsocketThrottleConfig{MaxBufferedBytes: -1}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := test.Cfg
+			rt := RouteTable{test.Route: {Websocket: &RouteWebsocketConfig{Throttle: &cfg}}}
+			err := rt.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+			if !rt.ThrottlesWebsockets() {
+				t.Error("expected route table to throttle websockets")
+			}
+		})
+	}
+}
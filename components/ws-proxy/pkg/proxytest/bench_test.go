@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxytest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/loadgen"
+)
+
+// benchmarkWorkspaces are the workspaces the proxy knows in benchmarks
+var benchmarkWorkspaces = func() []string {
+	res := make([]string, 100)
+	for i := range res {
+		res[i] = fmt.Sprintf("bench-workspace-%08x", i)
+	}
+	return res
+}()
+
+// startBenchmarkProxy starts a proxy which knows all benchmarkWorkspaces and waits until it knows all of them
+func startBenchmarkProxy(tb testing.TB) *Proxy {
+	// we'd measure the logger otherwise
+	log.Log.Logger.SetLevel(logrus.ErrorLevel)
+
+	wsman := NewWorkspaceManager()
+	for _, id := range benchmarkWorkspaces {
+		wsman.SetWorkspace(Workspace(id, id+"-instance"))
+	}
+	p := StartProxy(tb, wsman)
+	WaitFor(tb, "the proxy to know all workspaces", func() bool {
+		return currentInstance(p, benchmarkWorkspaces[len(benchmarkWorkspaces)-1]) != ""
+	})
+	return p
+}
+
+// newMixRequest produces the request r of the mix sends for a workspace
+func newMixRequest(r loadgen.Request, workspaceID string) *http.Request {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	host := strings.ReplaceAll(r.Host, loadgen.WorkspacePlaceholder, workspaceID)
+	req := httptest.NewRequest(method, r.Path, nil)
+	req.Host = host
+	req.Header.Set(HostHeader, host)
+	for k, v := range r.Header {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+// serveMixRequest serves a request of the mix through the handler chain of the proxy and fails if the
+// upstream did not answer it
+func serveMixRequest(tb testing.TB, p *Proxy, r loadgen.Request, workspaceID string) {
+	rec := httptest.NewRecorder()
+	p.Handler.ServeHTTP(rec, newMixRequest(r, workspaceID))
+	if rec.Code != http.StatusOK {
+		tb.Fatalf("%s request for %s failed with %d: %s", r.Name, workspaceID, rec.Code, rec.Body.String())
+	}
+}
+
+// BenchmarkProxy measures the handler chain of the proxy for each kind of request in the default mix
+func BenchmarkProxy(b *testing.B) {
+	p := startBenchmarkProxy(b)
+	for _, r := range loadgen.DefaultMix(HostSuffix) {
+		r := r
+		b.Run(r.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				serveMixRequest(b, p, r, benchmarkWorkspaces[i%len(benchmarkWorkspaces)])
+			}
+		})
+		b.Run(r.Name+"-parallel", func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				var i int
+				for pb.Next() {
+					i++
+					serveMixRequest(b, p, r, benchmarkWorkspaces[i%len(benchmarkWorkspaces)])
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxytest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// BrowserEnvVar names the Chrome or Chromium binary browser tests run. Without it we look for one on the PATH.
+	BrowserEnvVar = "PROXYTEST_BROWSER"
+
+	// browserTimeout is how long we wait for the browser to start, answer a command or load a page
+	browserTimeout = 15 * time.Second
+)
+
+// browserBinaries are the names under which we look for a browser on the PATH
+var browserBinaries = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell"}
+
+// BrowserResponse is a response the browser received while loading a page
+type BrowserResponse struct {
+	URL    string
+	Status int
+	// Type is the resource type of the response, e.g. Document, Script or Fetch
+	Type string
+}
+
+// Browser is a headless Chrome or Chromium which loads pages from a Proxy the way users do: it resolves all hosts
+// of the installation to the proxy, speaks TLS, keeps cookies, follows redirects and upgrades WebSockets.
+// It drives the browser through the DevTools protocol.
+type Browser struct {
+	// Front is the TLS server the browser connects to. Like the ingress in front of ws-proxy, it sets the
+	// HostHeader the proxy routes by.
+	Front *httptest.Server
+	// Dashboard serves the Gitpod installation itself, e.g. the start page the proxy redirects to
+	Dashboard *Upstream
+
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu        sync.Mutex
+	nextID    int64
+	pending   map[int64]chan cdpMessage
+	responses []BrowserResponse
+	loaded    chan struct{}
+}
+
+type cdpMessage struct {
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// StartBrowser starts a headless browser which loads all hosts of the installation from p. The browser is stopped
+// when the test ends. Tests which need a browser are skipped if there is none, or if they run with -short.
+func StartBrowser(t testing.TB, p *Proxy) *Browser {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("browser tests take too long for -short")
+	}
+	bin := os.Getenv(BrowserEnvVar)
+	if bin == "" {
+		for _, n := range browserBinaries {
+			if fn, err := exec.LookPath(n); err == nil {
+				bin = fn
+				break
+			}
+		}
+	}
+	if bin == "" {
+		t.Skipf("no headless browser found - set %s to run browser tests", BrowserEnvVar)
+	}
+
+	b := &Browser{
+		Dashboard: StartUpstream(t, "dashboard"),
+		pending:   make(map[int64]chan cdpMessage),
+		loaded:    make(chan struct{}, 1),
+	}
+	b.Front = startFront(t, p, b.Dashboard)
+	frontAddr := b.Front.Listener.Addr().String()
+
+	cmd := exec.Command(bin,
+		"--headless",
+		"--disable-gpu",
+		"--no-sandbox",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--disable-extensions",
+		"--disable-background-networking",
+		"--user-data-dir="+t.TempDir(),
+		"--remote-debugging-port=0",
+		// the front has a self-signed certificate
+		"--ignore-certificate-errors",
+		fmt.Sprintf("--host-resolver-rules=MAP *%s %s, MAP %s %s", HostSuffix, frontAddr, HostName, frontAddr),
+		"about:blank",
+	)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Start()
+	if err != nil {
+		t.Fatalf("cannot start browser %s: %v", bin, err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	debuggerURL, err := waitForDevTools(stderr)
+	if err != nil {
+		t.Fatalf("browser did not start: %v", err)
+	}
+	pageURL, err := findPage(debuggerURL)
+	if err != nil {
+		t.Fatalf("cannot find the browser's page: %v", err)
+	}
+	b.conn, _, err = websocket.DefaultDialer.Dial(pageURL, nil)
+	if err != nil {
+		t.Fatalf("cannot connect to the browser: %v", err)
+	}
+	t.Cleanup(func() { b.conn.Close() })
+	go b.read()
+
+	for _, domain := range []string{"Page.enable", "Network.enable", "Runtime.enable"} {
+		err = b.call(domain, nil, nil)
+		if err != nil {
+			t.Fatalf("cannot set up the browser: %v", err)
+		}
+	}
+	return b
+}
+
+// startFront starts the TLS server the browser connects to. It serves the installation from dashboard and
+// forwards everything else to the proxy.
+func startFront(t testing.TB, p *Proxy, dashboard *Upstream) *httptest.Server {
+	proxyURL, err := url.Parse(p.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboardURL, err := url.Parse(dashboard.Server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toProxy := httputil.NewSingleHostReverseProxy(proxyURL)
+	toDashboard := httputil.NewSingleHostReverseProxy(dashboardURL)
+
+	front := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if host == HostName {
+			toDashboard.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Set(HostHeader, host)
+		toProxy.ServeHTTP(w, r)
+	}))
+	front.StartTLS()
+	t.Cleanup(front.Close)
+	return front
+}
+
+// waitForDevTools waits for the browser to announce where its DevTools listen
+func waitForDevTools(stderr io.Reader) (string, error) {
+	res := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if i := strings.Index(line, "DevTools listening on "); i >= 0 {
+				res <- strings.TrimSpace(line[i+len("DevTools listening on "):])
+				break
+			}
+		}
+		// the browser blocks if nobody reads its output
+		_, _ = io.Copy(io.Discard, stderr)
+	}()
+
+	select {
+	case u := <-res:
+		return u, nil
+	case <-time.After(browserTimeout):
+		return "", xerrors.Errorf("browser did not announce its DevTools within %v", browserTimeout)
+	}
+}
+
+// findPage returns the DevTools URL of the page the browser opened on start
+func findPage(debuggerURL string) (string, error) {
+	u, err := url.Parse(debuggerURL)
+	if err != nil {
+		return "", err
+	}
+	list := fmt.Sprintf("http://%s/json/list", u.Host)
+
+	deadline := time.Now().Add(browserTimeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(list)
+		if err != nil {
+			return "", err
+		}
+		var targets []struct {
+			Type                 string `json:"type"`
+			WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&targets)
+		resp.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		for _, tgt := range targets {
+			if tgt.Type == "page" && tgt.WebSocketDebuggerURL != "" {
+				return tgt.WebSocketDebuggerURL, nil
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return "", xerrors.Errorf("browser opened no page")
+}
+
+// read dispatches what the browser sends until the connection closes
+func (b *Browser) read() {
+	for {
+		var msg cdpMessage
+		err := b.conn.ReadJSON(&msg)
+		if err != nil {
+			b.mu.Lock()
+			for id, c := range b.pending {
+				close(c)
+				delete(b.pending, id)
+			}
+			b.mu.Unlock()
+			return
+		}
+
+		if msg.ID != 0 {
+			b.mu.Lock()
+			c, ok := b.pending[msg.ID]
+			delete(b.pending, msg.ID)
+			b.mu.Unlock()
+			if ok {
+				c <- msg
+			}
+			continue
+		}
+		b.handleEvent(msg)
+	}
+}
+
+func (b *Browser) handleEvent(msg cdpMessage) {
+	switch msg.Method {
+	case "Page.loadEventFired":
+		select {
+		case b.loaded <- struct{}{}:
+		default:
+		}
+	case "Network.responseReceived":
+		var evt struct {
+			Type     string `json:"type"`
+			Response struct {
+				URL    string `json:"url"`
+				Status int    `json:"status"`
+			} `json:"response"`
+		}
+		if json.Unmarshal(msg.Params, &evt) != nil {
+			return
+		}
+		b.recordResponse(BrowserResponse{URL: evt.Response.URL, Status: evt.Response.Status, Type: evt.Type})
+	case "Network.requestWillBeSent":
+		// redirects show up as the response to the request which the redirect replaces
+		var evt struct {
+			Type             string `json:"type"`
+			RedirectResponse *struct {
+				URL    string `json:"url"`
+				Status int    `json:"status"`
+			} `json:"redirectResponse"`
+		}
+		if json.Unmarshal(msg.Params, &evt) != nil || evt.RedirectResponse == nil {
+			return
+		}
+		b.recordResponse(BrowserResponse{URL: evt.RedirectResponse.URL, Status: evt.RedirectResponse.Status, Type: evt.Type})
+	}
+}
+
+func (b *Browser) recordResponse(r BrowserResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.responses = append(b.responses, r)
+}
+
+// call sends a DevTools command and unmarshals its result into result, unless that's nil
+func (b *Browser) call(method string, params interface{}, result interface{}) error {
+	var rawParams json.RawMessage
+	if params != nil {
+		var err error
+		rawParams, err = json.Marshal(params)
+		if err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	c := make(chan cdpMessage, 1)
+	b.pending[id] = c
+	b.mu.Unlock()
+
+	b.writeMu.Lock()
+	err := b.conn.WriteJSON(cdpMessage{ID: id, Method: method, Params: rawParams})
+	b.writeMu.Unlock()
+	if err != nil {
+		return xerrors.Errorf("%s: %w", method, err)
+	}
+
+	select {
+	case msg, ok := <-c:
+		if !ok {
+			return xerrors.Errorf("%s: browser connection closed", method)
+		}
+		if msg.Error != nil {
+			return xerrors.Errorf("%s: %s", method, msg.Error.Message)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	case <-time.After(browserTimeout):
+		b.mu.Lock()
+		delete(b.pending, id)
+		b.mu.Unlock()
+		return xerrors.Errorf("%s: browser did not answer within %v", method, browserTimeout)
+	}
+}
+
+// Navigate loads u and waits until the page has loaded. Navigate does not fail if the server responds with an error,
+// check Response for that.
+func (b *Browser) Navigate(u string) error {
+	select {
+	case <-b.loaded:
+	default:
+	}
+
+	var res struct {
+		ErrorText string `json:"errorText"`
+	}
+	err := b.call("Page.navigate", map[string]interface{}{"url": u}, &res)
+	if err != nil {
+		return err
+	}
+	if res.ErrorText != "" {
+		return xerrors.Errorf("cannot load %s: %s", u, res.ErrorText)
+	}
+
+	select {
+	case <-b.loaded:
+		return nil
+	case <-time.After(browserTimeout):
+		return xerrors.Errorf("%s did not load within %v", u, browserTimeout)
+	}
+}
+
+// Eval evaluates a JavaScript expression in the current page and unmarshals its value into result, unless that's nil.
+// If the expression produces a promise, Eval waits for it to settle.
+func (b *Browser) Eval(expr string, result interface{}) error {
+	var res struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text      string `json:"text"`
+			Exception *struct {
+				Description string `json:"description"`
+			} `json:"exception"`
+		} `json:"exceptionDetails"`
+	}
+	err := b.call("Runtime.evaluate", map[string]interface{}{
+		"expression":    expr,
+		"awaitPromise":  true,
+		"returnByValue": true,
+	}, &res)
+	if err != nil {
+		return err
+	}
+	if e := res.ExceptionDetails; e != nil {
+		msg := e.Text
+		if e.Exception != nil && e.Exception.Description != "" {
+			msg = e.Exception.Description
+		}
+		return xerrors.Errorf("%s: %s", expr, msg)
+	}
+	if result == nil || len(res.Result.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Result.Value, result)
+}
+
+// SetCookie stores a cookie in the browser as if a server had set it. The cookie must have a domain.
+func (b *Browser) SetCookie(c *http.Cookie) error {
+	path := c.Path
+	if path == "" {
+		path = "/"
+	}
+	var res struct {
+		Success *bool `json:"success"`
+	}
+	err := b.call("Network.setCookie", map[string]interface{}{
+		"name":     c.Name,
+		"value":    c.Value,
+		"domain":   c.Domain,
+		"path":     path,
+		"secure":   c.Secure,
+		"httpOnly": c.HttpOnly,
+	}, &res)
+	if err != nil {
+		return err
+	}
+	// newer browsers always succeed and leave out the field
+	if res.Success != nil && !*res.Success {
+		return xerrors.Errorf("browser rejected cookie %s", c.Name)
+	}
+	return nil
+}
+
+// Cookies returns the cookies the browser would send to u
+func (b *Browser) Cookies(u string) ([]*http.Cookie, error) {
+	var res struct {
+		Cookies []struct {
+			Name     string `json:"name"`
+			Value    string `json:"value"`
+			Domain   string `json:"domain"`
+			Path     string `json:"path"`
+			Secure   bool   `json:"secure"`
+			HTTPOnly bool   `json:"httpOnly"`
+		} `json:"cookies"`
+	}
+	err := b.call("Network.getCookies", map[string]interface{}{"urls": []string{u}}, &res)
+	if err != nil {
+		return nil, err
+	}
+	cookies := make([]*http.Cookie, 0, len(res.Cookies))
+	for _, c := range res.Cookies {
+		cookies = append(cookies, &http.Cookie{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path, Secure: c.Secure, HttpOnly: c.HTTPOnly})
+	}
+	return cookies, nil
+}
+
+// Responses returns the responses the browser received so far, in the order it received them
+func (b *Browser) Responses() []BrowserResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]BrowserResponse(nil), b.responses...)
+}
+
+// Response returns the last response the browser received for u
+func (b *Browser) Response(u string) (BrowserResponse, bool) {
+	resps := b.Responses()
+	for i := len(resps) - 1; i >= 0; i-- {
+		if resps[i].URL == u {
+			return resps[i], true
+		}
+	}
+	return BrowserResponse{}, false
+}
+
+// WorkspaceURL is the URL of path in a workspace as the browser sees it
+func WorkspaceURL(workspaceID, path string) string {
+	return "https://" + WorkspaceHost(workspaceID) + path
+}
+
+// PortURL is the URL of path on a workspace port as the browser sees it
+func PortURL(port uint32, workspaceID, path string) string {
+	return "https://" + PortHost(port, workspaceID) + path
+}
+
+// OwnerCookie is the owner cookie of a workspace instance, as the Gitpod installation sets it
+func OwnerCookie(instanceID, token string) *http.Cookie {
+	prefix := "_" + strings.NewReplacer(" ", "_", "-", "_", ".", "_").Replace(HostName) + "_ws_"
+	return &http.Cookie{
+		Name:     prefix + instanceID + "_owner_",
+		Value:    url.QueryEscape(token),
+		Domain:   strings.TrimPrefix(HostSuffix, "."),
+		Path:     "/",
+		Secure:   true,
+		HttpOnly: true,
+	}
+}
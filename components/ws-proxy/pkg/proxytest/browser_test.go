@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxytest
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const fakeIDEPage = `<!DOCTYPE html>
+<html>
+<head>
+	<link rel="stylesheet" href="/style.css">
+	<script src="/app.js"></script>
+</head>
+<body></body>
+</html>`
+
+// fakeIDEScript does what an IDE does on load: it connects its WebSocket and asks supervisor for its status
+const fakeIDEScript = `window.ideReady = new Promise((resolve, reject) => {
+	const ws = new WebSocket("wss://" + location.host + "/services");
+	ws.onopen = () => ws.send("ping");
+	ws.onmessage = (evt) => resolve(evt.data);
+	ws.onerror = () => reject(new Error("WebSocket failed"));
+}).then(async (echo) => {
+	const resp = await fetch("/_supervisor/v1/status/supervisor");
+	return { echo, supervisor: await resp.text() };
+});`
+
+// ideSessionCookie is the cookie the fake IDE sets when its page loads
+const ideSessionCookie = "ide-session"
+
+// fakeIDE serves an IDE page which loads assets and echoes what it receives on its WebSocket
+func fakeIDE() http.Handler {
+	upgrader := websocket.Upgrader{
+		// the proxy's CORS policy decides which origins may connect
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			tpe, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			err = conn.WriteMessage(tpe, msg)
+			if err != nil {
+				return
+			}
+		}
+	})
+	mux.HandleFunc("/app.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/javascript")
+		_, _ = io.WriteString(w, fakeIDEScript)
+	})
+	mux.HandleFunc("/style.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		_, _ = io.WriteString(w, "body { margin: 0; }")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: ideSessionCookie, Value: "session", Path: "/", Secure: true})
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = io.WriteString(w, fakeIDEPage)
+	})
+	return mux
+}
+
+// ownerOnly produces the status of a running workspace only its owner can access
+func ownerOnly(workspaceID, instanceID string) *wsapi.WorkspaceStatus {
+	ws := Workspace(workspaceID, instanceID)
+	ws.Auth.Admission = wsapi.AdmissionLevel_ADMIT_OWNER_ONLY
+	return ws
+}
+
+type ideState struct {
+	Echo       string `json:"echo"`
+	Supervisor string `json:"supervisor"`
+}
+
+// loadIDE loads the IDE of a workspace and waits until it has connected
+func loadIDE(t *testing.T, b *Browser, workspaceID string) {
+	t.Helper()
+
+	err := b.Navigate(WorkspaceURL(workspaceID, "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state ideState
+	err = b.Eval("window.ideReady", &state)
+	if err != nil {
+		t.Fatalf("IDE did not load: %v", err)
+	}
+	if diff := cmp.Diff(ideState{Echo: "ping", Supervisor: "supervisor"}, state); diff != "" {
+		t.Errorf("unexpected IDE state (-want +got):\n%s", diff)
+	}
+}
+
+func TestBrowserIDELoad(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	wsman.SetWorkspace(Workspace(workspaceA, "instance-a"))
+	p := StartProxy(t, wsman)
+	p.IDE.Serve(fakeIDE())
+	b := StartBrowser(t, p)
+	WaitFor(t, "the proxy to know workspace A", func() bool { return currentInstance(p, workspaceA) == "instance-a" })
+
+	loadIDE(t, b, workspaceA)
+
+	for _, path := range []string{"/", "/app.js", "/style.css"} {
+		resp, ok := b.Response(WorkspaceURL(workspaceA, path))
+		if !ok {
+			t.Errorf("browser did not load %s", path)
+			continue
+		}
+		if resp.Status != http.StatusOK {
+			t.Errorf("expected %s to load with 200, got %d", path, resp.Status)
+		}
+	}
+
+	// assets are loaded with the cookies the IDE set on the page
+	var found bool
+	for _, req := range p.IDE.Requests() {
+		if req.URL.Path != "/app.js" {
+			continue
+		}
+		found = true
+		if _, err := req.Cookie(ideSessionCookie); err != nil {
+			t.Errorf("the IDE's own cookie did not reach it: %v", err)
+		}
+	}
+	if !found {
+		t.Error("the IDE upstream did not serve /app.js")
+	}
+}
+
+func TestBrowserOwnerCookie(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	wsman.SetWorkspace(ownerOnly(workspaceA, "instance-a"))
+	p := StartProxy(t, wsman)
+	p.IDE.Serve(fakeIDE())
+	b := StartBrowser(t, p)
+	WaitFor(t, "the proxy to know workspace A", func() bool { return currentInstance(p, workspaceA) == "instance-a" })
+
+	err := b.Navigate(WorkspaceURL(workspaceA, "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, ok := b.Response(WorkspaceURL(workspaceA, "/"))
+	if !ok {
+		t.Fatal("browser did not load the workspace")
+	}
+	if resp.Status != http.StatusUnauthorized {
+		t.Errorf("expected the workspace to be denied without owner cookie, got %d", resp.Status)
+	}
+
+	err = b.SetCookie(OwnerCookie("instance-a", "not-the-owner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Navigate(WorkspaceURL(workspaceA, "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, _ = b.Response(WorkspaceURL(workspaceA, "/"))
+	if resp.Status != http.StatusForbidden {
+		t.Errorf("expected the workspace to be denied with the wrong owner token, got %d", resp.Status)
+	}
+
+	// the owner cookie must reach the proxy with the page, its assets and the WebSocket upgrade
+	err = b.SetCookie(OwnerCookie("instance-a", OwnerToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadIDE(t, b, workspaceA)
+}
+
+func TestBrowserPortCookies(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	wsman.SetWorkspace(ownerOnly(workspaceA, "instance-a"))
+	p := StartProxy(t, wsman)
+	b := StartBrowser(t, p)
+	WaitFor(t, "the proxy to know workspace A", func() bool { return currentInstance(p, workspaceA) == "instance-a" })
+
+	owner := OwnerCookie("instance-a", OwnerToken)
+	for _, c := range []*http.Cookie{owner, {Name: "app", Value: "1", Domain: PortHost(8080, workspaceA), Secure: true}} {
+		err := b.SetCookie(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := b.Navigate(PortURL(8080, workspaceA, "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := b.Response(PortURL(8080, workspaceA, "/")); resp.Status != http.StatusOK {
+		t.Fatalf("expected the owner to load the private port, got %d", resp.Status)
+	}
+
+	reqs := p.Port.Requests()
+	if len(reqs) == 0 {
+		t.Fatal("the port upstream received no request")
+	}
+	req := reqs[len(reqs)-1]
+	if _, err := req.Cookie("app"); err != nil {
+		t.Errorf("the application's cookie did not reach the port: %v", err)
+	}
+	if _, err := req.Cookie(owner.Name); err == nil {
+		t.Error("the owner cookie must not reach the application on the port")
+	}
+}
+
+func TestBrowserRedirectToStart(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	p := StartProxy(t, wsman)
+	b := StartBrowser(t, p)
+	WaitFor(t, "the proxy to subscribe", func() bool { return wsman.Subscribers() == 1 && p.InfoProvider.Ready() })
+
+	// the proxy does not know the workspace and sends the user to the dashboard to start it
+	err := b.Navigate(WorkspaceURL(workspaceB, "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := b.Response(WorkspaceURL(workspaceB, "/")); resp.Status != http.StatusFound {
+		t.Errorf("expected a redirect, got %d", resp.Status)
+	}
+	var location string
+	err = b.Eval("location.href", &location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "https://" + HostName + "/start/#" + workspaceB; location != expected {
+		t.Errorf("expected the browser to end up at %s, got %s", expected, location)
+	}
+	reqs := b.Dashboard.Requests()
+	if len(reqs) == 0 || reqs[len(reqs)-1].URL.Path != "/start/" {
+		t.Error("the dashboard did not serve the start page")
+	}
+}
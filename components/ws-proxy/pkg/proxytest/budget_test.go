@@ -0,0 +1,92 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxytest
+
+import (
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/loadgen"
+)
+
+// allocationBudget is the number of allocations the handler chain may make per request of each kind in the default mix.
+// Allocations are deterministic, hence the budgets leave about 5% of headroom only. If a change legitimately needs
+// more allocations, raise the budget in the same change and say why.
+var allocationBudget = map[string]float64{
+	"ide":        265,
+	"supervisor": 282,
+	"port":       226,
+}
+
+// maxOverhead is how much slower than a plain reverse proxy to the same upstream the handler chain may be.
+// Timings are noisy on shared CI machines, hence this only catches gross regressions. Use BenchmarkProxy or
+// ws-proxy loadgen with a baseline to spot smaller ones.
+const maxOverhead = 3.0
+
+func TestAllocationBudget(t *testing.T) {
+	p := startBenchmarkProxy(t)
+	for _, r := range loadgen.DefaultMix(HostSuffix) {
+		r := r
+		t.Run(r.Name, func(t *testing.T) {
+			budget, ok := allocationBudget[r.Name]
+			if !ok {
+				t.Fatalf("no allocation budget for %s requests", r.Name)
+			}
+			allocs := testing.AllocsPerRun(200, func() {
+				serveMixRequest(t, p, r, benchmarkWorkspaces[0])
+			})
+			if allocs > budget {
+				t.Errorf("%s requests allocate %.0f times, the budget is %.0f", r.Name, allocs, budget)
+			}
+		})
+	}
+}
+
+func TestThroughputBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("measuring throughput takes too long for -short")
+	}
+
+	p := startBenchmarkProxy(t)
+	for _, r := range loadgen.DefaultMix(HostSuffix) {
+		r := r
+		t.Run(r.Name, func(t *testing.T) {
+			upstream, err := url.Parse(p.IDE.Server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain := httputil.NewSingleHostReverseProxy(upstream)
+
+			var overhead float64
+			// we take the best of a few attempts so that a busy machine doesn't fail the test
+			for attempt := 0; attempt < 3; attempt++ {
+				proxied := testing.Benchmark(func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						serveMixRequest(t, p, r, benchmarkWorkspaces[i%len(benchmarkWorkspaces)])
+					}
+				})
+				baseline := testing.Benchmark(func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						plain.ServeHTTP(httptest.NewRecorder(), newMixRequest(r, benchmarkWorkspaces[i%len(benchmarkWorkspaces)]))
+					}
+				})
+				o := float64(proxied.NsPerOp()) / float64(baseline.NsPerOp())
+				t.Logf("%s requests take %v, %.2f times as long as through a plain reverse proxy", r.Name, time.Duration(proxied.NsPerOp()), o)
+				if attempt == 0 || o < overhead {
+					overhead = o
+				}
+				if overhead <= maxOverhead {
+					break
+				}
+			}
+			if overhead > maxOverhead {
+				t.Errorf("%s requests take %.2f times as long as through a plain reverse proxy, the budget is %.2f", r.Name, overhead, maxOverhead)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package proxytest runs a complete ws-proxy against an in-memory ws-manager and stub workspace upstreams,
+// so that tests can cover reconnects, cache staleness and routing end to end without a cluster.
+// Browser tests load the proxy in a headless Chrome or Chromium, see StartBrowser.
+package proxytest
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/util"
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+)
+
+const (
+	// HostHeader is the header the proxy routes by
+	HostHeader = "x-wsproxy-host"
+	// HostName is the Gitpod installation the proxy serves
+	HostName = "gitpod.test"
+	// HostSuffix is the suffix of all workspace hosts
+	HostSuffix = ".ws.gitpod.test"
+	// UpstreamHeader names the upstream that served a response
+	UpstreamHeader = "X-Proxytest-Upstream"
+	// OwnerToken is the owner token of the workspaces Workspace creates
+	OwnerToken = "owner-token"
+)
+
+// Proxy is a ws-proxy serving on an ephemeral port
+type Proxy struct {
+	URL string
+	// Handler is the handler chain of the proxy. Serving requests with it directly leaves out the
+	// client side of the connection, e.g. in benchmarks.
+	Handler      http.Handler
+	Config       proxy.Config
+	InfoProvider *proxy.RemoteWorkspaceInfoProvider
+
+	// IDE, Supervisor and Port are the upstreams of all workspaces
+	IDE        *Upstream
+	Supervisor *Upstream
+	Port       *Upstream
+}
+
+// StartProxy starts a ws-proxy which gets its workspaces from wsman. The proxy and its upstreams are stopped
+// when the test ends. configure can change the proxy configuration before the proxy starts.
+func StartProxy(t testing.TB, wsman *WorkspaceManager, configure ...func(cfg *proxy.Config)) *Proxy {
+	t.Helper()
+
+	p := &Proxy{
+		IDE:        StartUpstream(t, "ide"),
+		Supervisor: StartUpstream(t, "supervisor"),
+		Port:       StartUpstream(t, "port"),
+	}
+	blobserve := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(blobserve.Close)
+	blobserveURL, _ := url.Parse(blobserve.URL)
+
+	p.Config = proxy.Config{
+		TransportConfig: &proxy.TransportConfig{
+			ConnectTimeout:           util.Duration(5 * time.Second),
+			IdleConnTimeout:          util.Duration(time.Minute),
+			WebsocketIdleConnTimeout: util.Duration(time.Minute),
+			MaxIdleConns:             100,
+		},
+		GitpodInstallation: &proxy.GitpodInstallation{
+			HostName: HostName,
+			Scheme:   "https",
+		},
+		BlobServer: &proxy.BlobServerConfig{
+			Host:   blobserveURL.Host,
+			Scheme: "http",
+		},
+		WorkspacePodConfig: &proxy.WorkspacePodConfig{
+			ServiceTemplate:     "http://127.0.0.1:{{ .port }}",
+			PortServiceTemplate: "http://" + p.Port.Host(),
+			TheiaPort:           p.IDE.Port(),
+			SupervisorPort:      p.Supervisor.Port(),
+			SupervisorImage:     "gitpod-io/supervisor:latest",
+		},
+		BuiltinPages: proxy.BuiltinPagesConfig{
+			Location: publicDir(),
+		},
+		// the header policy tells upstreams which workspace a request is for
+		Headers: &proxy.HeaderPolicyConfig{},
+	}
+	for _, c := range configure {
+		c(&p.Config)
+	}
+	err := p.Config.Validate()
+	if err != nil {
+		t.Fatalf("invalid proxy configuration: %v", err)
+	}
+
+	p.InfoProvider = proxy.NewRemoteWorkspaceInfoProvider(proxy.WorkspaceInfoProviderConfig{
+		WsManagerAddr:     "wsman.test:8080",
+		ReconnectInterval: util.Duration(10 * time.Millisecond),
+	})
+	p.InfoProvider.Dialer = wsman.Dial
+	err = p.InfoProvider.Run()
+	if err != nil {
+		t.Fatalf("cannot start workspace info provider: %v", err)
+	}
+	t.Cleanup(func() {
+		p.InfoProvider.Close()
+		wsman.Disconnect(nil)
+	})
+
+	wsp := proxy.NewWorkspaceProxy("", p.Config, proxy.HostBasedRouter(HostHeader, HostSuffix, nil), p.InfoProvider)
+	handler, err := wsp.Handler()
+	if err != nil {
+		t.Fatalf("cannot create proxy handler: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	p.URL = srv.URL
+	p.Handler = handler
+
+	return p
+}
+
+// publicDir is the location of ws-proxy's builtin pages
+func publicDir() string {
+	_, fn, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(fn), "..", "..", "public")
+}
+
+// Response is a response the proxy served
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+// Get requests path from host through the proxy without following redirects
+func (p *Proxy) Get(host, path string) (*Response, error) {
+	req, err := http.NewRequest("GET", p.URL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(HostHeader, host)
+	return p.Do(req)
+}
+
+// Do sends a request through the proxy without following redirects. Callers must set the HostHeader.
+func (p *Proxy) Do(req *http.Request) (*Response, error) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: string(body)}, nil
+}
+
+// WorkspaceHost is the host under which the proxy serves a workspace
+func WorkspaceHost(workspaceID string) string {
+	return workspaceID + HostSuffix
+}
+
+// PortHost is the host under which the proxy serves a workspace port
+func PortHost(port uint32, workspaceID string) string {
+	return fmt.Sprintf("%d-%s%s", port, workspaceID, HostSuffix)
+}
+
+// Workspace produces the status of a running workspace everyone can access
+func Workspace(workspaceID, instanceID string) *wsapi.WorkspaceStatus {
+	return &wsapi.WorkspaceStatus{
+		Id:    instanceID,
+		Phase: wsapi.WorkspacePhase_RUNNING,
+		Metadata: &wsapi.WorkspaceMetadata{
+			Owner:  "owner",
+			MetaId: workspaceID,
+		},
+		Spec: &wsapi.WorkspaceSpec{
+			Url:      "https://" + WorkspaceHost(workspaceID) + "/",
+			IdeImage: "gitpod-io/ide:latest",
+			Type:     wsapi.WorkspaceType_REGULAR,
+		},
+		Auth: &wsapi.WorkspaceAuthentication{
+			Admission:  wsapi.AdmissionLevel_ADMIT_EVERYONE,
+			OwnerToken: OwnerToken,
+		},
+	}
+}
+
+// Upstream is a stub workspace service. It answers every request with its name, unless it was told to Serve
+// a handler, and records the request.
+type Upstream struct {
+	Name   string
+	Server *httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+	handler  http.Handler
+}
+
+// StartUpstream starts an upstream which is stopped when the test ends
+func StartUpstream(t testing.TB, name string) *Upstream {
+	u := &Upstream{Name: name}
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.mu.Lock()
+		u.requests = append(u.requests, r.Clone(r.Context()))
+		h := u.handler
+		u.mu.Unlock()
+
+		w.Header().Set(UpstreamHeader, u.Name)
+		if h != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, u.Name)
+	}))
+	t.Cleanup(u.Server.Close)
+	return u
+}
+
+// Serve makes the upstream answer requests with h rather than its name
+func (u *Upstream) Serve(h http.Handler) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.handler = h
+}
+
+// Host is the host:port the upstream listens on
+func (u *Upstream) Host() string {
+	return u.Server.Listener.Addr().String()
+}
+
+// Port is the port the upstream listens on
+func (u *Upstream) Port() uint16 {
+	return uint16(u.Server.Listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Requests returns the requests the upstream received
+func (u *Upstream) Requests() []*http.Request {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return append([]*http.Request(nil), u.requests...)
+}
+
+// WaitFor polls cond until it's true and fails the test if that doesn't happen within five seconds
+func WaitFor(t testing.TB, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxytest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+const (
+	workspaceA = "amaranth-smelt-9ba20cc1"
+	workspaceB = "blue-gorilla-3b0f2e19"
+)
+
+// currentInstance returns the instance the proxy knows for a workspace, or "" if it knows none
+func currentInstance(p *Proxy, workspaceID string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	info := p.InfoProvider.WorkspaceInfo(ctx, workspaceID)
+	if info == nil {
+		return ""
+	}
+	return info.InstanceID
+}
+
+func TestRouting(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	wsman.SetWorkspace(Workspace(workspaceA, "instance-a"))
+	wsman.SetWorkspace(Workspace(workspaceB, "instance-b"))
+	p := StartProxy(t, wsman)
+
+	tests := []struct {
+		Name        string
+		Host        string
+		Path        string
+		Upstream    *Upstream
+		WorkspaceID string
+		InstanceID  string
+	}{
+		{"IDE of A", WorkspaceHost(workspaceA), "/services", p.IDE, workspaceA, "instance-a"},
+		{"IDE of B", WorkspaceHost(workspaceB), "/services", p.IDE, workspaceB, "instance-b"},
+		{"supervisor of B", WorkspaceHost(workspaceB), "/_supervisor/v1/status/supervisor", p.Supervisor, workspaceB, "instance-b"},
+		{"port of A", PortHost(8080, workspaceA), "/", p.Port, workspaceA, "instance-a"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			before := len(test.Upstream.Requests())
+			resp, err := p.Get(test.Host, test.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusOK || resp.Header.Get(UpstreamHeader) != test.Upstream.Name {
+				t.Fatalf("expected %s to serve the request, got %d from %q", test.Upstream.Name, resp.StatusCode, resp.Header.Get(UpstreamHeader))
+			}
+
+			reqs := test.Upstream.Requests()
+			if len(reqs) != before+1 {
+				t.Fatalf("expected one more upstream request, got %d", len(reqs)-before)
+			}
+			req := reqs[len(reqs)-1]
+			if id := req.Header.Get("X-Gitpod-WorkspaceId"); id != test.WorkspaceID {
+				t.Errorf("expected the request for workspace %s, got %s", test.WorkspaceID, id)
+			}
+			if id := req.Header.Get("X-Gitpod-InstanceId"); id != test.InstanceID {
+				t.Errorf("expected the request for instance %s, got %s", test.InstanceID, id)
+			}
+		})
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	wsman.SetWorkspace(Workspace(workspaceA, "instance-a"))
+	p := StartProxy(t, wsman)
+	WaitFor(t, "the proxy to subscribe", func() bool { return wsman.Subscribers() == 1 })
+
+	wsman.FailDial(xerrors.Errorf("connection refused"))
+	wsman.Disconnect(nil)
+	WaitFor(t, "the proxy to retry", func() bool { return wsman.Dials() >= 3 })
+	if p.InfoProvider.Ready() {
+		t.Error("expected the proxy not to be ready while ws-manager is unreachable")
+	}
+
+	// we keep serving what we know while we're disconnected
+	if inst := currentInstance(p, workspaceA); inst != "instance-a" {
+		t.Errorf("expected the proxy to keep instance-a while disconnected, got %q", inst)
+	}
+
+	// changes we missed while disconnected arrive with the reconnect
+	wsman.RemoveWorkspace("instance-a")
+	wsman.SetWorkspace(Workspace(workspaceB, "instance-b"))
+	wsman.FailDial(nil)
+	WaitFor(t, "the proxy to reconnect", func() bool { return wsman.Subscribers() == 1 && p.InfoProvider.Ready() })
+
+	if inst := currentInstance(p, workspaceA); inst != "" {
+		t.Errorf("expected workspace A to be gone, got %q", inst)
+	}
+	resp, err := p.Get(WorkspaceHost(workspaceB), "/services")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected workspace B to be served after the reconnect, got %d", resp.StatusCode)
+	}
+}
+
+func TestCacheUpdates(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	wsman.SetWorkspace(Workspace(workspaceA, "instance-1"))
+	p := StartProxy(t, wsman)
+	WaitFor(t, "the proxy to subscribe", func() bool { return wsman.Subscribers() == 1 })
+
+	// until a delayed update arrives the proxy routes to the instance it knows
+	wsman.DelayUpdates(200 * time.Millisecond)
+	wsman.SetWorkspace(Workspace(workspaceA, "instance-2"))
+	if inst := currentInstance(p, workspaceA); inst != "instance-1" {
+		t.Errorf("expected the stale instance-1 before the update arrives, got %q", inst)
+	}
+	WaitFor(t, "instance-2", func() bool { return currentInstance(p, workspaceA) == "instance-2" })
+	wsman.DelayUpdates(0)
+
+	// out-of-order updates do not replace newer information
+	outdated := Workspace(workspaceA, "instance-1")
+	outdated.Generation = 1
+	wsman.Send(&wsapi.SubscribeResponse{Payload: &wsapi.SubscribeResponse_Status{Status: outdated}})
+	wsman.RemoveWorkspace("instance-2")
+	WaitFor(t, "workspace A to be removed", func() bool { return currentInstance(p, workspaceA) == "" })
+}
+
+func TestMalformedStatus(t *testing.T) {
+	wsman := NewWorkspaceManager()
+	wsman.SetWorkspace(&wsapi.WorkspaceStatus{Id: "no-metadata", Phase: wsapi.WorkspacePhase_RUNNING})
+	p := StartProxy(t, wsman)
+	WaitFor(t, "the proxy to subscribe", func() bool { return wsman.Subscribers() == 1 })
+
+	for _, status := range []*wsapi.WorkspaceStatus{
+		{Id: "no-metadata", Phase: wsapi.WorkspacePhase_STOPPED},
+		{Id: "no-spec", Phase: wsapi.WorkspacePhase_RUNNING, Metadata: &wsapi.WorkspaceMetadata{MetaId: workspaceB}},
+	} {
+		wsman.Send(&wsapi.SubscribeResponse{Payload: &wsapi.SubscribeResponse_Status{Status: status}})
+	}
+	wsman.Send(&wsapi.SubscribeResponse{})
+	wsman.SetWorkspace(Workspace(workspaceA, "instance-a"))
+
+	// the proxy carries on with the updates after the malformed ones
+	WaitFor(t, "workspace A", func() bool { return currentInstance(p, workspaceA) == "instance-a" })
+	if inst := currentInstance(p, workspaceB); inst != "" {
+		t.Errorf("expected the status without spec to be ignored, got %q", inst)
+	}
+	if wsman.Dials() != 1 {
+		t.Errorf("expected the proxy to stay connected, but it dialed %d times", wsman.Dials())
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package proxytest
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+
+	wsapi "github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// WorkspaceManager is a scriptable in-memory ws-manager client. Tests change the workspaces it knows about and
+// control its status streams: they can disconnect subscribers, delay updates and send malformed statuses.
+type WorkspaceManager struct {
+	// WorkspaceManagerClient makes the fake satisfy the interface. Calling a method the fake does not implement panics.
+	wsapi.WorkspaceManagerClient
+
+	mu               sync.Mutex
+	workspaces       map[string]*wsapi.WorkspaceStatus
+	generation       uint64
+	subscriptions    map[*subscription]struct{}
+	subscribed       chan struct{}
+	delay            time.Duration
+	dialErr          error
+	getWorkspacesErr error
+	dials            int
+	activity         []*wsapi.ReportProxyActivityRequest
+	portControls     []*wsapi.ControlPortRequest
+}
+
+// NewWorkspaceManager creates a fake ws-manager which knows no workspaces
+func NewWorkspaceManager() *WorkspaceManager {
+	return &WorkspaceManager{
+		workspaces:    make(map[string]*wsapi.WorkspaceStatus),
+		subscriptions: make(map[*subscription]struct{}),
+		subscribed:    make(chan struct{}),
+	}
+}
+
+// Dial connects to the fake. Use it as the dialer of the workspace info provider.
+func (m *WorkspaceManager) Dial(target string) (io.Closer, wsapi.WorkspaceManagerClient, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dials++
+	if m.dialErr != nil {
+		return nil, nil, m.dialErr
+	}
+	return io.NopCloser(nil), m, nil
+}
+
+// Dials returns how often clients connected to the fake
+func (m *WorkspaceManager) Dials() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.dials
+}
+
+// FailDial makes connecting to the fake fail with err until it's called with nil
+func (m *WorkspaceManager) FailDial(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dialErr = err
+}
+
+// FailGetWorkspaces makes GetWorkspaces fail with err until it's called with nil
+func (m *WorkspaceManager) FailGetWorkspaces(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.getWorkspacesErr = err
+}
+
+// DelayUpdates delays every update subscribers receive from now on by d
+func (m *WorkspaceManager) DelayUpdates(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.delay = d
+}
+
+// SetWorkspace adds or updates a workspace instance and sends its status to all subscribers.
+// Statuses without a generation get the next one.
+func (m *WorkspaceManager) SetWorkspace(status *wsapi.WorkspaceStatus) {
+	status = proto.Clone(status).(*wsapi.WorkspaceStatus)
+
+	m.mu.Lock()
+	if status.Generation == 0 {
+		m.generation++
+		status.Generation = m.generation
+	} else if status.Generation > m.generation {
+		m.generation = status.Generation
+	}
+	m.workspaces[status.Id] = status
+	m.mu.Unlock()
+
+	m.Send(&wsapi.SubscribeResponse{Payload: &wsapi.SubscribeResponse_Status{Status: status}})
+}
+
+// RemoveWorkspace removes a workspace instance and sends its STOPPED status to all subscribers
+func (m *WorkspaceManager) RemoveWorkspace(instanceID string) {
+	m.mu.Lock()
+	status, ok := m.workspaces[instanceID]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.workspaces, instanceID)
+	m.generation++
+	status = proto.Clone(status).(*wsapi.WorkspaceStatus)
+	status.Phase = wsapi.WorkspacePhase_STOPPED
+	status.Generation = m.generation
+	m.mu.Unlock()
+
+	m.Send(&wsapi.SubscribeResponse{Payload: &wsapi.SubscribeResponse_Status{Status: status}})
+}
+
+// Send sends a response to all subscribers as it is, e.g. a malformed status or a maintenance announcement.
+// Unlike SetWorkspace, Send does not change the workspaces GetWorkspaces returns.
+func (m *WorkspaceManager) Send(resp *wsapi.SubscribeResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for s := range m.subscriptions {
+		s.push(resp, nil)
+	}
+}
+
+// Disconnect ends all subscriptions with err, io.EOF if err is nil, as if the connection to ws-manager broke
+func (m *WorkspaceManager) Disconnect(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for s := range m.subscriptions {
+		s.push(nil, err)
+		delete(m.subscriptions, s)
+	}
+}
+
+// Subscribers returns the number of active subscriptions
+func (m *WorkspaceManager) Subscribers() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.subscriptions)
+}
+
+// WaitForSubscribers waits until there are at least n active subscriptions or the context is done
+func (m *WorkspaceManager) WaitForSubscribers(ctx context.Context, n int) error {
+	for {
+		m.mu.Lock()
+		count, subscribed := len(m.subscriptions), m.subscribed
+		m.mu.Unlock()
+		if count >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-subscribed:
+		}
+	}
+}
+
+// ProxyActivity returns the proxy activity reports the fake received
+func (m *WorkspaceManager) ProxyActivity() []*wsapi.ReportProxyActivityRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*wsapi.ReportProxyActivityRequest(nil), m.activity...)
+}
+
+// PortControls returns the port control requests the fake received
+func (m *WorkspaceManager) PortControls() []*wsapi.ControlPortRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*wsapi.ControlPortRequest(nil), m.portControls...)
+}
+
+// GetWorkspaces returns the workspaces the fake knows about
+func (m *WorkspaceManager) GetWorkspaces(ctx context.Context, in *wsapi.GetWorkspacesRequest, opts ...grpc.CallOption) (*wsapi.GetWorkspacesResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.getWorkspacesErr != nil {
+		return nil, m.getWorkspacesErr
+	}
+	res := &wsapi.GetWorkspacesResponse{}
+	for _, status := range m.workspaces {
+		res.Status = append(res.Status, proto.Clone(status).(*wsapi.WorkspaceStatus))
+	}
+	return res, nil
+}
+
+// Subscribe streams the updates the test sends from now on
+func (m *WorkspaceManager) Subscribe(ctx context.Context, in *wsapi.SubscribeRequest, opts ...grpc.CallOption) (wsapi.WorkspaceManager_SubscribeClient, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := &subscription{ctx: ctx, manager: m, notify: make(chan struct{}, 1)}
+	m.subscriptions[s] = struct{}{}
+	close(m.subscribed)
+	m.subscribed = make(chan struct{})
+	return s, nil
+}
+
+// ReportProxyActivity records the report
+func (m *WorkspaceManager) ReportProxyActivity(ctx context.Context, in *wsapi.ReportProxyActivityRequest, opts ...grpc.CallOption) (*wsapi.ReportProxyActivityResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.activity = append(m.activity, proto.Clone(in).(*wsapi.ReportProxyActivityRequest))
+	return &wsapi.ReportProxyActivityResponse{}, nil
+}
+
+// ControlPort records the request. It does not change the workspace, tests send the resulting status themselves.
+func (m *WorkspaceManager) ControlPort(ctx context.Context, in *wsapi.ControlPortRequest, opts ...grpc.CallOption) (*wsapi.ControlPortResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.portControls = append(m.portControls, proto.Clone(in).(*wsapi.ControlPortRequest))
+	return &wsapi.ControlPortResponse{}, nil
+}
+
+func (m *WorkspaceManager) updateDelay() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.delay
+}
+
+type subscriptionItem struct {
+	resp *wsapi.SubscribeResponse
+	err  error
+}
+
+// subscription is a status stream. It queues updates without limit, so that tests never block on slow subscribers.
+type subscription struct {
+	// ClientStream makes the subscription satisfy the interface. Only Recv is implemented.
+	grpc.ClientStream
+
+	ctx     context.Context
+	manager *WorkspaceManager
+
+	mu     sync.Mutex
+	queue  []subscriptionItem
+	notify chan struct{}
+}
+
+func (s *subscription) push(resp *wsapi.SubscribeResponse, err error) {
+	s.mu.Lock()
+	s.queue = append(s.queue, subscriptionItem{resp: resp, err: err})
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Recv returns the next update
+func (s *subscription) Recv() (*wsapi.SubscribeResponse, error) {
+	for {
+		s.mu.Lock()
+		if len(s.queue) > 0 {
+			item := s.queue[0]
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+
+			if d := s.manager.updateDelay(); d > 0 && item.err == nil {
+				time.Sleep(d)
+			}
+			return item.resp, item.err
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-s.ctx.Done():
+			s.manager.mu.Lock()
+			delete(s.manager.subscriptions, s)
+			s.manager.mu.Unlock()
+			return nil, s.ctx.Err()
+		case <-s.notify:
+		}
+	}
+}
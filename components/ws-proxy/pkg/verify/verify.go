@@ -0,0 +1,364 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+// Package verify checks that an installation's DNS, certificates and load balancers route workspace traffic to
+// ws-proxy. The checks run against the synthetic workspace ws-proxy serves itself, hence they need no workspace.
+package verify
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/wsurl"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+)
+
+const (
+	// DefaultPort is the workspace port whose host we check port routing with
+	DefaultPort = 8080
+
+	// minCertValidity is the time a certificate must remain valid for. Certificates which expire sooner
+	// suggest that their renewal is broken.
+	minCertValidity = 7 * 24 * time.Hour
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	// StatusOK means the check passed
+	StatusOK Status = "ok"
+	// StatusFailed means the check failed
+	StatusFailed Status = "failed"
+	// StatusSkipped means the check did not run, e.g. because a check it depends on failed
+	StatusSkipped Status = "skipped"
+)
+
+// Config configures the checks
+type Config struct {
+	// Domain is the domain of the installation, e.g. gitpod.example.com
+	Domain string
+	// HostSuffix is the suffix of workspace hosts. Defaults to .ws.<Domain>.
+	HostSuffix string
+	// Port is the workspace port we check port routing with. Defaults to DefaultPort.
+	Port int
+	// HTTPSPort is the port the installation serves HTTPS on. Defaults to 443.
+	HTTPSPort int
+	// Address, if set, is where we connect to instead of the resolved addresses, e.g. a load balancer
+	// DNS does not point to yet. It must include the port.
+	Address string
+	// Timeout limits each check. Defaults to 10 seconds.
+	Timeout time.Duration
+	// RootCAs verify the certificates. Defaults to the system roots.
+	RootCAs *x509.CertPool
+	// Resolver resolves the workspace hosts. Defaults to net.DefaultResolver.
+	Resolver interface {
+		LookupHost(ctx context.Context, host string) ([]string, error)
+	}
+}
+
+// Check is the outcome of a single check
+type Check struct {
+	Name       string `json:"name"`
+	Host       string `json:"host"`
+	Status     Status `json:"status"`
+	Message    string `json:"message,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Domain      string  `json:"domain"`
+	WorkspaceID string  `json:"workspaceID"`
+	OK          bool    `json:"ok"`
+	Checks      []Check `json:"checks"`
+}
+
+// Run checks DNS resolution, TLS certificates, routing and websocket upgrades of the workspace host and a port host
+// of the synthetic workspace. Failed checks are part of the report - the error is reserved for invalid configuration.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Domain == "" {
+		return nil, xerrors.Errorf("domain is required")
+	}
+	if cfg.HostSuffix == "" {
+		cfg.HostSuffix = ".ws." + cfg.Domain
+	}
+	if cfg.Port == 0 {
+		cfg.Port = DefaultPort
+	}
+	if cfg.HTTPSPort == 0 {
+		cfg.HTTPSPort = 443
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = net.DefaultResolver
+	}
+
+	scheme := wsurl.Scheme{HostSuffix: cfg.HostSuffix}
+	if err := scheme.Validate(); err != nil {
+		return nil, xerrors.Errorf("invalid workspace host suffix: %w", err)
+	}
+	workspaceHost, err := scheme.WorkspaceHost(proxy.VerifyWorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	portHost, err := scheme.PortHost(proxy.VerifyWorkspaceID, cfg.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{Config: cfg}
+	report := &Report{
+		Domain:      cfg.Domain,
+		WorkspaceID: proxy.VerifyWorkspaceID,
+	}
+	for _, h := range []struct {
+		Host  string
+		Route string
+		Port  string
+	}{
+		{Host: workspaceHost, Route: proxy.SLORouteClassIDE},
+		{Host: portHost, Route: proxy.SLORouteClassPort, Port: strconv.Itoa(cfg.Port)},
+	} {
+		var (
+			host     = h.Host
+			expected = proxy.VerifyResponse{Route: h.Route, WorkspaceID: proxy.VerifyWorkspaceID, Port: h.Port}
+		)
+		steps := []step{
+			{Name: "dns", Run: func(ctx context.Context) (string, error) { return c.checkDNS(ctx, host) }},
+			{Name: "tls", Run: func(ctx context.Context) (string, error) { return c.checkTLS(ctx, host) }},
+			{Name: "route", Run: func(ctx context.Context) (string, error) { return c.checkRoute(ctx, host, expected) }},
+		}
+		if h.Route == proxy.SLORouteClassIDE {
+			steps = append(steps, step{Name: "websocket", Run: func(ctx context.Context) (string, error) { return c.checkWebsocket(ctx, host) }})
+		}
+
+		// every check relies on the ones before it, e.g. there's no point in checking routes without a certificate
+		failed := false
+		for _, s := range steps {
+			chk := Check{Name: s.Name, Host: host}
+			if failed {
+				chk.Status = StatusSkipped
+				report.Checks = append(report.Checks, chk)
+				continue
+			}
+			if s.Name == "dns" && cfg.Address != "" {
+				chk.Status = StatusSkipped
+				chk.Message = "connecting to " + cfg.Address + " instead"
+				report.Checks = append(report.Checks, chk)
+				continue
+			}
+
+			start := time.Now()
+			cctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+			msg, err := s.Run(cctx)
+			cancel()
+			chk.DurationMs = time.Since(start).Milliseconds()
+			if err != nil {
+				chk.Status = StatusFailed
+				chk.Message = err.Error()
+				failed = true
+			} else {
+				chk.Status = StatusOK
+				chk.Message = msg
+			}
+			report.Checks = append(report.Checks, chk)
+		}
+	}
+
+	report.OK = true
+	for _, chk := range report.Checks {
+		if chk.Status == StatusFailed {
+			report.OK = false
+			break
+		}
+	}
+	return report, nil
+}
+
+type step struct {
+	Name string
+	Run  func(ctx context.Context) (msg string, err error)
+}
+
+type checker struct {
+	Config Config
+}
+
+func (c *checker) checkDNS(ctx context.Context, host string) (string, error) {
+	addrs, err := c.Config.Resolver.LookupHost(ctx, host)
+	if err != nil {
+		return "", xerrors.Errorf("cannot resolve %s - is there a wildcard DNS record for *%s? %w", host, c.Config.HostSuffix, err)
+	}
+	if len(addrs) == 0 {
+		return "", xerrors.Errorf("%s resolves to no address", host)
+	}
+	return "resolves to " + strings.Join(addrs, ", "), nil
+}
+
+// dial connects to the configured address or the address the host of addr resolves to
+func (c *checker) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	var d net.Dialer
+	if c.Config.Address != "" {
+		return d.DialContext(ctx, network, c.Config.Address)
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := c.Config.Resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, xerrors.Errorf("%s resolves to no address", host)
+	}
+	return d.DialContext(ctx, network, net.JoinHostPort(addrs[0], port))
+}
+
+// url produces the URL of the verify path on host
+func (c *checker) url(host string) string {
+	if c.Config.HTTPSPort != 443 {
+		host = net.JoinHostPort(host, strconv.Itoa(c.Config.HTTPSPort))
+	}
+	return "https://" + host + proxy.VerifyPath
+}
+
+func (c *checker) dialTLS(ctx context.Context, host string) (*tls.Conn, error) {
+	conn, err := c.dial(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(c.Config.HTTPSPort)))
+	if err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName: host,
+		RootCAs:    c.Config.RootCAs,
+		NextProtos: []string{"http/1.1"},
+	})
+	if dl, ok := ctx.Deadline(); ok {
+		_ = tlsConn.SetDeadline(dl)
+	}
+	err = tlsConn.Handshake()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+func (c *checker) checkTLS(ctx context.Context, host string) (string, error) {
+	conn, err := c.dialTLS(ctx, host)
+	if err != nil {
+		return "", xerrors.Errorf("TLS handshake with %s failed - does the certificate cover *%s and include its intermediates? %w", host, c.Config.HostSuffix, err)
+	}
+	defer conn.Close()
+
+	crt := conn.ConnectionState().PeerCertificates[0]
+	if remaining := time.Until(crt.NotAfter); remaining < minCertValidity {
+		return "", xerrors.Errorf("certificate of %s expires at %s - is its renewal working?", host, crt.NotAfter.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("certificate issued by %q, valid until %s", crt.Issuer.CommonName, crt.NotAfter.Format(time.RFC3339)), nil
+}
+
+func (c *checker) client() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:     c.dial,
+			TLSClientConfig: &tls.Config{RootCAs: c.Config.RootCAs},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (c *checker) checkRoute(ctx context.Context, host string, expected proxy.VerifyResponse) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url(host), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	client := c.client()
+	defer client.CloseIdleConnections()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("cannot request %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		hint := "the request did not reach ws-proxy's workspace routes"
+		if resp.StatusCode == http.StatusFound {
+			hint = "ws-proxy routed the request, but does not know the synthetic workspace - is ws-proxy up to date?"
+		}
+		return "", xerrors.Errorf("%s responded with %s: %s", host, resp.Status, hint)
+	}
+	var act proxy.VerifyResponse
+	err = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&act)
+	if err != nil {
+		return "", xerrors.Errorf("%s did not respond like ws-proxy: %w", host, err)
+	}
+	if act.Route != expected.Route || act.WorkspaceID != expected.WorkspaceID || act.Port != expected.Port {
+		return "", xerrors.Errorf("%s was routed as %s of workspace %s (port %q) instead of %s of workspace %s (port %q)",
+			host, act.Route, act.WorkspaceID, act.Port, expected.Route, expected.WorkspaceID, expected.Port)
+	}
+	return fmt.Sprintf("routed to the %s route of ws-proxy", act.Route), nil
+}
+
+func (c *checker) checkWebsocket(ctx context.Context, host string) (string, error) {
+	conn, err := c.dialTLS(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	rawKey := make([]byte, 16)
+	_, err = rand.Read(rawKey)
+	if err != nil {
+		return "", err
+	}
+	key := base64.StdEncoding.EncodeToString(rawKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url(host), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	err = req.Write(conn)
+	if err != nil {
+		return "", xerrors.Errorf("cannot send websocket upgrade: %w", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return "", xerrors.Errorf("cannot read websocket upgrade response: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return "", xerrors.Errorf("websocket upgrade was answered with %s - does the load balancer pass upgrades on?", resp.Status)
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != proxy.WebsocketAccept(key) {
+		return "", xerrors.Errorf("websocket upgrade was answered with the wrong Sec-WebSocket-Accept %q", accept)
+	}
+	return "websocket upgrade succeeded", nil
+}
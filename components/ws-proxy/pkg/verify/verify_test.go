@@ -0,0 +1,245 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package verify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxy"
+	"github.com/gitpod-io/gitpod/ws-proxy/pkg/proxytest"
+)
+
+// selfSigned produces a self-signed certificate for domains, valid for validity
+func selfSigned(t *testing.T, validity time.Duration, domains ...string) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "verify test CA"},
+		DNSNames:              domains,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crt, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(crt)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// startInstallation serves handler via TLS like the load balancer of an installation would,
+// which passes the Host on in the header ws-proxy routes by
+func startInstallation(t *testing.T, handler http.Handler, crt tls.Certificate) string {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set(proxytest.HostHeader, r.Host)
+		handler.ServeHTTP(w, r)
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{crt}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv.Listener.Addr().String()
+}
+
+func enableVerifyEndpoint(cfg *proxy.Config) {
+	cfg.VerifyEndpoint = true
+}
+
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestRun(t *testing.T) {
+	const (
+		domain        = "gitpod.test"
+		workspaceHost = "wsproxy-verify-00000000.ws.gitpod.test"
+		portHost      = "8080-wsproxy-verify-00000000.ws.gitpod.test"
+	)
+	var (
+		wsproxy   = proxytest.StartProxy(t, proxytest.NewWorkspaceManager(), enableVerifyEndpoint).Handler
+		wildcard  = staticResolver{workspaceHost: {"127.0.0.1"}, portHost: {"127.0.0.1"}}
+		crt, pool = selfSigned(t, 90*24*time.Hour, "*.ws.gitpod.test")
+	)
+
+	type check struct {
+		Name   string
+		Host   string
+		Status Status
+	}
+	allOK := []check{
+		{"dns", workspaceHost, StatusOK},
+		{"tls", workspaceHost, StatusOK},
+		{"route", workspaceHost, StatusOK},
+		{"websocket", workspaceHost, StatusOK},
+		{"dns", portHost, StatusOK},
+		{"tls", portHost, StatusOK},
+		{"route", portHost, StatusOK},
+	}
+
+	tests := []struct {
+		Name     string
+		Handler  http.Handler
+		Cert     func() (tls.Certificate, *x509.CertPool)
+		Resolver staticResolver
+		Expected []check
+	}{
+		{
+			Name:     "all good",
+			Resolver: wildcard,
+			Expected: allOK,
+		},
+		{
+			Name:     "no wildcard DNS",
+			Resolver: staticResolver{workspaceHost: {"127.0.0.1"}},
+			Expected: []check{
+				{"dns", workspaceHost, StatusOK},
+				{"tls", workspaceHost, StatusOK},
+				{"route", workspaceHost, StatusOK},
+				{"websocket", workspaceHost, StatusOK},
+				{"dns", portHost, StatusFailed},
+				{"tls", portHost, StatusSkipped},
+				{"route", portHost, StatusSkipped},
+			},
+		},
+		{
+			Name:     "certificate for the wrong hosts",
+			Resolver: wildcard,
+			Cert:     func() (tls.Certificate, *x509.CertPool) { return selfSigned(t, 90*24*time.Hour, "gitpod.test") },
+			Expected: []check{
+				{"dns", workspaceHost, StatusOK},
+				{"tls", workspaceHost, StatusFailed},
+				{"route", workspaceHost, StatusSkipped},
+				{"websocket", workspaceHost, StatusSkipped},
+				{"dns", portHost, StatusOK},
+				{"tls", portHost, StatusFailed},
+				{"route", portHost, StatusSkipped},
+			},
+		},
+		{
+			Name:     "certificate about to expire",
+			Resolver: wildcard,
+			Cert:     func() (tls.Certificate, *x509.CertPool) { return selfSigned(t, 24*time.Hour, "*.ws.gitpod.test") },
+			Expected: []check{
+				{"dns", workspaceHost, StatusOK},
+				{"tls", workspaceHost, StatusFailed},
+				{"route", workspaceHost, StatusSkipped},
+				{"websocket", workspaceHost, StatusSkipped},
+				{"dns", portHost, StatusOK},
+				{"tls", portHost, StatusFailed},
+				{"route", portHost, StatusSkipped},
+			},
+		},
+		{
+			Name:     "not routed to ws-proxy",
+			Resolver: wildcard,
+			Handler:  http.NotFoundHandler(),
+			Expected: []check{
+				{"dns", workspaceHost, StatusOK},
+				{"tls", workspaceHost, StatusOK},
+				{"route", workspaceHost, StatusFailed},
+				{"websocket", workspaceHost, StatusSkipped},
+				{"dns", portHost, StatusOK},
+				{"tls", portHost, StatusOK},
+				{"route", portHost, StatusFailed},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				handler  = wsproxy
+				srvCrt   = crt
+				clientCA = pool
+			)
+			if test.Handler != nil {
+				handler = test.Handler
+			}
+			if test.Cert != nil {
+				srvCrt, clientCA = test.Cert()
+			}
+			_, port, _ := net.SplitHostPort(startInstallation(t, handler, srvCrt))
+			httpsPort, _ := strconv.Atoi(port)
+
+			report, err := Run(context.Background(), Config{
+				Domain:    domain,
+				HTTPSPort: httpsPort,
+				RootCAs:   clientCA,
+				Resolver:  test.Resolver,
+				Timeout:   5 * time.Second,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var act []check
+			for _, c := range report.Checks {
+				act = append(act, check{c.Name, c.Host, c.Status})
+				if c.Status == StatusFailed && c.Message == "" {
+					t.Errorf("failed check %s of %s has no message", c.Name, c.Host)
+				}
+			}
+			if diff := cmp.Diff(test.Expected, act); diff != "" {
+				t.Errorf("unexpected checks (-want +got):\n%s", diff)
+			}
+			expectOK := cmp.Equal(test.Expected, allOK)
+			if report.OK != expectOK {
+				t.Errorf("expected report.OK to be %v", expectOK)
+			}
+		})
+	}
+}
+
+func TestRunAddressSkipsDNS(t *testing.T) {
+	crt, pool := selfSigned(t, 90*24*time.Hour, "*.ws.gitpod.test")
+	addr := startInstallation(t, proxytest.StartProxy(t, proxytest.NewWorkspaceManager(), enableVerifyEndpoint).Handler, crt)
+
+	report, err := Run(context.Background(), Config{Domain: "gitpod.test", Address: addr, RootCAs: pool, Resolver: staticResolver{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK {
+		t.Errorf("expected the checks to pass when connecting to the address, got %+v", report.Checks)
+	}
+	for _, c := range report.Checks {
+		if c.Name == "dns" && (c.Status != StatusSkipped || !strings.Contains(c.Message, addr)) {
+			t.Errorf("expected DNS to be skipped, got %+v", c)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License-AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/spf13/cobra"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/ws-manager/api"
+)
+
+// workspacesMaintenanceCmd announces or ends a cluster maintenance
+var workspacesMaintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "announces a cluster maintenance to users, or ends it using --off",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		off, _ := cmd.Flags().GetBool("off")
+		message, _ := cmd.Flags().GetString("message")
+		parseTime := func(flag string) *timestamp.Timestamp {
+			val, _ := cmd.Flags().GetString(flag)
+			if val == "" {
+				return nil
+			}
+			t, err := time.Parse(time.RFC3339, val)
+			if err != nil {
+				log.WithError(err).Fatalf("invalid --%s", flag)
+			}
+			ts, err := ptypes.TimestampProto(t)
+			if err != nil {
+				log.WithError(err).Fatalf("invalid --%s", flag)
+			}
+			return ts
+		}
+
+		status := &api.MaintenanceStatus{
+			Enabled: !off,
+		}
+		if !off {
+			status.Message = message
+			status.StartsAt = parseTime("start")
+			status.EndsAt = parseTime("end")
+		}
+
+		conn, client, err := getWorkspacesClient(ctx)
+		if err != nil {
+			log.WithError(err).Fatal("cannot connect")
+		}
+		defer conn.Close()
+
+		resp, err := client.SetMaintenance(ctx, &api.SetMaintenanceRequest{Status: status})
+		if err != nil {
+			log.WithError(err).Fatal("error during RPC call")
+		}
+
+		err = getOutputFormat("maintenance status changed\n", "").Print(resp)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	workspacesCmd.AddCommand(workspacesMaintenanceCmd)
+	workspacesMaintenanceCmd.Flags().Bool("off", false, "ends the maintenance")
+	workspacesMaintenanceCmd.Flags().String("message", "", "message shown to users")
+	workspacesMaintenanceCmd.Flags().String("start", "", "start of the maintenance window (RFC3339)")
+	workspacesMaintenanceCmd.Flags().String("end", "", "end of the maintenance window (RFC3339)")
+}